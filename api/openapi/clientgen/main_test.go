@@ -0,0 +1,75 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/api/openapi"
+)
+
+// TestGeneratedClientsUpToDate fails when the committed clients drift from
+// the specification. Run "make openapi-clients" to regenerate them.
+func TestGeneratedClientsUpToDate(t *testing.T) {
+	files, err := generate(openapi.YAML())
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	for name, want := range files {
+		got, err := os.ReadFile(filepath.Join("..", "clients", name))
+		if err != nil {
+			t.Fatalf("read %s: %v", name, err)
+		}
+		if string(got) != want {
+			t.Errorf("%s is stale; run make openapi-clients", name)
+		}
+	}
+}
+
+func TestGenerateCoversEveryOperation(t *testing.T) {
+	ops, err := openapi.Operations()
+	if err != nil {
+		t.Fatalf("Operations: %v", err)
+	}
+	files, err := generate(openapi.YAML())
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	for _, op := range ops {
+		if !strings.Contains(files[typescriptFile], "  async "+op.ID+"(") {
+			t.Errorf("typescript client missing %s", op.ID)
+		}
+		if !strings.Contains(files[pythonFile], "    def "+camelToSnake(op.ID)+"(") {
+			t.Errorf("python client missing %s", camelToSnake(op.ID))
+		}
+	}
+}
+
+func TestCamelToSnake(t *testing.T) {
+	tests := map[string]string{
+		"listObjects":        "list_objects",
+		"getObjectMetadata":  "get_object_metadata",
+		"healthCheck":        "health_check",
+		"metrics":            "metrics",
+		"triggerReplication": "trigger_replication",
+	}
+	for in, want := range tests {
+		if got := camelToSnake(in); got != want {
+			t.Errorf("camelToSnake(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,311 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+const pyRuntime = `class ApiError(Exception):
+    """Raised when the server answers with a non-2xx status."""
+
+    def __init__(self, status: int, body: Any) -> None:
+        super().__init__(f"HTTP {status}: {body}")
+        self.status = status
+        self.body = body
+
+
+def _encode_path(value: str) -> str:
+    return urllib.parse.quote(value, safe="/")
+
+
+def _query_value(value: Any) -> str:
+    if isinstance(value, bool):
+        return "true" if value else "false"
+    return str(value)
+
+
+def _encode_form(fields: Dict[str, str], file_field: str, file: bytes, filename: str) -> Tuple[bytes, str]:
+    boundary = uuid.uuid4().hex
+    parts = []
+    for name, value in fields.items():
+        parts.append(f'--{boundary}\r\nContent-Disposition: form-data; name="{name}"\r\n\r\n{value}\r\n'.encode())
+    parts.append(
+        f'--{boundary}\r\nContent-Disposition: form-data; name="{file_field}"; filename="{filename}"\r\n'
+        "Content-Type: application/octet-stream\r\n\r\n".encode()
+    )
+    parts.append(file)
+    parts.append(f"\r\n--{boundary}--\r\n".encode())
+    return b"".join(parts), "multipart/form-data; boundary=" + boundary
+
+
+class ObjstoreClient:
+    """Client for the REST API.
+
+    base_url is the server root, e.g. http://localhost:8080.
+    """
+
+    def __init__(
+        self,
+        base_url: str,
+        token: Optional[str] = None,
+        headers: Optional[Dict[str, str]] = None,
+        timeout: float = 30.0,
+    ) -> None:
+        self.base_url = base_url.rstrip("/")
+        self.token = token
+        self.headers = dict(headers or {})
+        self.timeout = timeout
+
+    def _request(
+        self,
+        method: str,
+        path: str,
+        query: Optional[Dict[str, Any]] = None,
+        body: Optional[bytes] = None,
+        content_type: Optional[str] = None,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> Tuple[Dict[str, str], bytes]:
+        url = self.base_url + path
+        if query:
+            params = {k: _query_value(v) for k, v in query.items() if v is not None}
+            if params:
+                url += "?" + urllib.parse.urlencode(params)
+        all_headers = dict(self.headers)
+        all_headers.update(headers or {})
+        if self.token:
+            all_headers["Authorization"] = "Bearer " + self.token
+        if content_type and not any(k.lower() == "content-type" for k in all_headers):
+            all_headers["Content-Type"] = content_type
+        req = urllib.request.Request(url, data=body, method=method, headers=all_headers)
+        try:
+            with urllib.request.urlopen(req, timeout=self.timeout) as resp:  # nosec B310 - base_url is caller configuration
+                return dict(resp.headers.items()), resp.read()
+        except urllib.error.HTTPError as err:
+            raw = err.read()
+            try:
+                parsed: Any = json.loads(raw)
+            except ValueError:
+                parsed = raw.decode("utf-8", "replace")
+            raise ApiError(err.code, parsed) from None
+`
+
+// python renders a standard-library-only Python client.
+func python(doc *document, endpoints []endpoint) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Code generated by go run ./api/openapi/clientgen. DO NOT EDIT.\n")
+	fmt.Fprintf(&b, "# Source: api/openapi/objstore.yaml (%s %s)\n", doc.Info.Title, doc.Info.Version)
+	fmt.Fprintf(&b, "\"\"\"Python client for the %s.\"\"\"\n\n", doc.Info.Title)
+	b.WriteString(`from __future__ import annotations
+
+import json
+import urllib.error
+import urllib.parse
+import urllib.request
+import uuid
+from typing import Any, Dict, List, Optional, Tuple, TypedDict
+
+`)
+
+	for _, name := range doc.Components.Schemas.keys {
+		s := doc.Components.Schemas.values[name]
+		if len(s.Properties.keys) == 0 {
+			fmt.Fprintf(&b, "\n%s = %s\n", name, pyType(s))
+			continue
+		}
+		fmt.Fprintf(&b, "\nclass %s(TypedDict, total=False):\n", name)
+		lines := docLines(s.Description, "")
+		var required []string
+		for _, prop := range s.Properties.keys {
+			if s.isRequired(prop) {
+				required = append(required, prop)
+			}
+		}
+		if len(required) > 0 {
+			if len(lines) > 0 {
+				lines = append(lines, "")
+			}
+			lines = append(lines, "Required keys: "+strings.Join(required, ", ")+".")
+		}
+		pyDocstring(&b, "    ", lines)
+		for _, prop := range s.Properties.keys {
+			ps := s.Properties.values[prop]
+			fmt.Fprintf(&b, "    %s: %s\n", prop, pyType(ps))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(pyRuntime)
+	for _, ep := range endpoints {
+		b.WriteString("\n")
+		pyMethod(&b, ep)
+	}
+	return b.String()
+}
+
+func pyMethod(b *strings.Builder, ep endpoint) {
+	args := []string{"self"}
+	var params []string
+	for _, p := range ep.PathParams {
+		args = append(args, p.Name+": str")
+		params = append(params, fmt.Sprintf("%s: %s", p.Name, p.Description))
+	}
+	for _, p := range ep.RequiredQuery {
+		args = append(args, p.Name+": "+pyType(p.Schema))
+		params = append(params, fmt.Sprintf("%s: %s", p.Name, p.Description))
+	}
+	switch ep.BodyKind {
+	case bodyBinary:
+		args = append(args, "body: bytes")
+	case bodyForm:
+		args = append(args, "fields: Dict[str, str]", "file: bytes", `filename: str = "file"`)
+		params = append(params, "fields: form fields, sent before the file",
+			"file: file content", "filename: file name sent with the file")
+	case bodyJSON:
+		if ep.BodyRequired {
+			args = append(args, "body: "+pyType(ep.BodySchema))
+		} else {
+			args = append(args, "body: Optional["+pyType(ep.BodySchema)+"] = None")
+		}
+	}
+	args = append(args, "*")
+	for _, p := range ep.OptionalQuery {
+		args = append(args, fmt.Sprintf("%s: Optional[%s] = None", p.Name, pyType(p.Schema)))
+		params = append(params, fmt.Sprintf("%s: %s", p.Name, p.Description))
+	}
+	args = append(args, "headers: Optional[Dict[str, str]] = None")
+
+	var result string
+	switch ep.ResultKind {
+	case bodyJSON:
+		result = pyType(ep.ResultSchema)
+	case bodyBinary:
+		result = "bytes"
+	case bodyText:
+		result = "str"
+	default:
+		result = "None"
+		if ep.ResultHeaders {
+			result = "Dict[str, str]"
+		}
+	}
+
+	fmt.Fprintf(b, "    def %s(\n", camelToSnake(ep.ID))
+	for _, arg := range args {
+		fmt.Fprintf(b, "        %s,\n", arg)
+	}
+	fmt.Fprintf(b, "    ) -> %s:\n", result)
+
+	lines := docLines(ep.Summary, ep.Description)
+	if len(params) > 0 {
+		lines = append(lines, "", "Args:")
+		for _, p := range params {
+			lines = append(lines, "    "+p)
+		}
+	}
+	pyDocstring(b, "        ", lines)
+
+	path := ep.Path
+	for _, p := range ep.PathParams {
+		path = strings.ReplaceAll(path, "{"+p.Name+"}", "{_encode_path("+p.Name+")}")
+	}
+	pathExpr := fmt.Sprintf("%q", path)
+	if len(ep.PathParams) > 0 {
+		pathExpr = "f" + pathExpr
+	}
+	query := "None"
+	if len(ep.RequiredQuery)+len(ep.OptionalQuery) > 0 {
+		var fields []string
+		for _, p := range append(append([]parameter(nil), ep.RequiredQuery...), ep.OptionalQuery...) {
+			fields = append(fields, fmt.Sprintf("%q: %s", p.Name, p.Name))
+		}
+		query = "{" + strings.Join(fields, ", ") + "}"
+	}
+	body, contentType := "None", "None"
+	switch ep.BodyKind {
+	case bodyBinary:
+		body, contentType = "body", `"application/octet-stream"`
+	case bodyForm:
+		fmt.Fprintf(b, "        body, content_type = _encode_form(fields, %q, file, filename)\n", ep.FormFile)
+		body, contentType = "body", "content_type"
+	case bodyJSON:
+		body, contentType = "None if body is None else json.dumps(body).encode()", `"application/json"`
+	}
+
+	call := fmt.Sprintf("self._request(%q, %s, %s, %s, %s, headers)", ep.Method, pathExpr, query, body, contentType)
+	switch result {
+	case "None":
+		fmt.Fprintf(b, "        %s\n", call)
+	case "Dict[str, str]":
+		fmt.Fprintf(b, "        resp_headers, _ = %s\n        return resp_headers\n", call)
+	case "bytes":
+		fmt.Fprintf(b, "        _, data = %s\n        return data\n", call)
+	case "str":
+		fmt.Fprintf(b, "        _, data = %s\n        return data.decode(\"utf-8\")\n", call)
+	default:
+		fmt.Fprintf(b, "        _, data = %s\n        result: %s = json.loads(data)\n        return result\n", call, result)
+	}
+}
+
+func pyDocstring(b *strings.Builder, indent string, lines []string) {
+	if len(lines) == 0 {
+		return
+	}
+	if len(lines) == 1 {
+		fmt.Fprintf(b, "%s\"\"\"%s\"\"\"\n", indent, lines[0])
+		return
+	}
+	fmt.Fprintf(b, "%s\"\"\"%s\n", indent, lines[0])
+	for _, line := range lines[1:] {
+		if line == "" {
+			b.WriteString("\n")
+			continue
+		}
+		fmt.Fprintf(b, "%s%s\n", indent, line)
+	}
+	fmt.Fprintf(b, "%s\"\"\"\n", indent)
+}
+
+// pyType maps a schema to a Python type annotation.
+func pyType(s *schema) string {
+	if s == nil {
+		return "Any"
+	}
+	if s.Ref != "" {
+		return s.refName()
+	}
+	switch s.Type {
+	case "string":
+		if s.Format == "binary" {
+			return "bytes"
+		}
+		return "str"
+	case "integer":
+		return "int"
+	case "number":
+		return "float"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "List[" + pyType(s.Items) + "]"
+	case "object":
+		if s.AdditionalProperties != nil && s.AdditionalProperties.schema != nil {
+			return "Dict[str, " + pyType(s.AdditionalProperties.schema) + "]"
+		}
+		return "Dict[str, Any]"
+	}
+	return "Any"
+}
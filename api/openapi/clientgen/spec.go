@@ -0,0 +1,366 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// orderedMap decodes a YAML mapping while keeping its key order, so the
+// generated clients follow the order of the specification.
+type orderedMap[V any] struct {
+	keys   []string
+	values map[string]V
+}
+
+func (m *orderedMap[V]) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: expected a mapping", node.Line)
+	}
+	m.values = make(map[string]V, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		var v V
+		if err := node.Content[i+1].Decode(&v); err != nil {
+			return err
+		}
+		key := node.Content[i].Value
+		m.keys = append(m.keys, key)
+		m.values[key] = v
+	}
+	return nil
+}
+
+type document struct {
+	Info struct {
+		Title   string `yaml:"title"`
+		Version string `yaml:"version"`
+	} `yaml:"info"`
+	Servers    []server             `yaml:"servers"`
+	Paths      orderedMap[pathItem] `yaml:"paths"`
+	Components struct {
+		Schemas orderedMap[*schema] `yaml:"schemas"`
+	} `yaml:"components"`
+}
+
+type server struct {
+	URL string `yaml:"url"`
+}
+
+type pathItem struct {
+	Servers []server   `yaml:"servers"`
+	Get     *operation `yaml:"get"`
+	Put     *operation `yaml:"put"`
+	Post    *operation `yaml:"post"`
+	Delete  *operation `yaml:"delete"`
+	Head    *operation `yaml:"head"`
+	Patch   *operation `yaml:"patch"`
+}
+
+type operation struct {
+	OperationID string               `yaml:"operationId"`
+	Summary     string               `yaml:"summary"`
+	Description string               `yaml:"description"`
+	Parameters  []parameter          `yaml:"parameters"`
+	RequestBody *requestBody         `yaml:"requestBody"`
+	Responses   orderedMap[response] `yaml:"responses"`
+}
+
+type parameter struct {
+	Name        string  `yaml:"name"`
+	In          string  `yaml:"in"`
+	Description string  `yaml:"description"`
+	Required    bool    `yaml:"required"`
+	Schema      *schema `yaml:"schema"`
+}
+
+type requestBody struct {
+	Required bool                  `yaml:"required"`
+	Content  orderedMap[mediaType] `yaml:"content"`
+}
+
+type mediaType struct {
+	Schema *schema `yaml:"schema"`
+}
+
+type response struct {
+	Description string                `yaml:"description"`
+	Headers     map[string]any        `yaml:"headers"`
+	Content     orderedMap[mediaType] `yaml:"content"`
+}
+
+type schema struct {
+	Ref                  string              `yaml:"$ref"`
+	Type                 string              `yaml:"type"`
+	Format               string              `yaml:"format"`
+	Description          string              `yaml:"description"`
+	Properties           orderedMap[*schema] `yaml:"properties"`
+	Required             []string            `yaml:"required"`
+	Items                *schema             `yaml:"items"`
+	AdditionalProperties *additional         `yaml:"additionalProperties"`
+	Enum                 []string            `yaml:"enum"`
+}
+
+// additional is an additionalProperties value, which is either a boolean
+// or a schema.
+type additional struct {
+	schema *schema
+}
+
+func (a *additional) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return nil
+	}
+	a.schema = new(schema)
+	return node.Decode(a.schema)
+}
+
+// refName returns the component name a $ref points at.
+func (s *schema) refName() string {
+	return strings.TrimPrefix(s.Ref, "#/components/schemas/")
+}
+
+func (s *schema) isRequired(name string) bool {
+	for _, r := range s.Required {
+		if r == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Kinds of request and response bodies.
+const (
+	bodyNone   = ""
+	bodyJSON   = "json"
+	bodyBinary = "binary"
+	bodyText   = "text"
+	bodyForm   = "form"
+)
+
+// endpoint is an operation reduced to what a client method needs.
+type endpoint struct {
+	ID          string
+	Method      string
+	Path        string // full path including the server base path
+	Summary     string
+	Description string
+
+	PathParams    []parameter
+	RequiredQuery []parameter
+	OptionalQuery []parameter
+
+	BodyKind     string
+	BodySchema   *schema
+	BodyRequired bool
+	FormFile     string // field of the file in a form body
+
+	ResultKind    string
+	ResultSchema  *schema
+	ResultHeaders bool
+}
+
+// parse decodes a specification and flattens its operations.
+func parse(data []byte) (*document, []endpoint, error) {
+	var doc document
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse spec: %w", err)
+	}
+
+	basePath, err := serverPath(doc.Servers)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var endpoints []endpoint
+	for _, path := range doc.Paths.keys {
+		item := doc.Paths.values[path]
+		prefix := basePath
+		if len(item.Servers) > 0 {
+			if prefix, err = serverPath(item.Servers); err != nil {
+				return nil, nil, err
+			}
+		}
+		for _, m := range []struct {
+			method string
+			op     *operation
+		}{
+			{"GET", item.Get}, {"PUT", item.Put}, {"POST", item.Post},
+			{"DELETE", item.Delete}, {"HEAD", item.Head}, {"PATCH", item.Patch},
+		} {
+			if m.op == nil {
+				continue
+			}
+			ep, err := newEndpoint(m.method, prefix+path, m.op)
+			if err != nil {
+				return nil, nil, err
+			}
+			endpoints = append(endpoints, ep)
+		}
+	}
+	return &doc, endpoints, nil
+}
+
+// serverPath returns the path component of the first server URL.
+func serverPath(servers []server) (string, error) {
+	if len(servers) == 0 {
+		return "", nil
+	}
+	u, err := url.Parse(servers[0].URL)
+	if err != nil {
+		return "", fmt.Errorf("invalid server URL %q: %w", servers[0].URL, err)
+	}
+	return strings.TrimSuffix(u.Path, "/"), nil
+}
+
+func newEndpoint(method, path string, op *operation) (endpoint, error) {
+	if op.OperationID == "" {
+		return endpoint{}, fmt.Errorf("%s %s has no operationId", method, path)
+	}
+	ep := endpoint{
+		ID:          op.OperationID,
+		Method:      method,
+		Path:        path,
+		Summary:     op.Summary,
+		Description: strings.TrimSpace(op.Description),
+	}
+
+	for _, p := range op.Parameters {
+		switch {
+		case p.In == "path":
+			ep.PathParams = append(ep.PathParams, p)
+		case p.In == "query" && p.Required:
+			ep.RequiredQuery = append(ep.RequiredQuery, p)
+		case p.In == "query":
+			ep.OptionalQuery = append(ep.OptionalQuery, p)
+		}
+	}
+
+	if rb := op.RequestBody; rb != nil {
+		ep.BodyRequired = rb.Required
+		// Prefer a raw upload over multipart forms: it streams and needs no
+		// form encoding on the client.
+		if mt, ok := rb.Content.values["application/octet-stream"]; ok {
+			ep.BodyKind, ep.BodySchema, ep.BodyRequired = bodyBinary, mt.Schema, true
+		} else if mt, ok := rb.Content.values["application/json"]; ok {
+			ep.BodyKind, ep.BodySchema = bodyJSON, mt.Schema
+		} else if mt, ok := rb.Content.values["multipart/form-data"]; ok && mt.Schema != nil {
+			// A form-only body is fields followed by one file, such as a
+			// browser upload; the file is the binary property.
+			for _, prop := range mt.Schema.Properties.keys {
+				if mt.Schema.Properties.values[prop].Format == "binary" {
+					ep.FormFile = prop
+				}
+			}
+			if ep.FormFile == "" {
+				return endpoint{}, fmt.Errorf("%s: multipart request body has no binary property", op.OperationID)
+			}
+			ep.BodyKind, ep.BodySchema, ep.BodyRequired = bodyForm, mt.Schema, true
+		} else {
+			return endpoint{}, fmt.Errorf("%s: unsupported request body %v", op.OperationID, rb.Content.keys)
+		}
+	}
+
+	// An operation that may succeed with 204 No Content has no result body
+	// the clients can rely on, even if another success status carries one.
+	codes := append([]string(nil), op.Responses.keys...)
+	sort.Strings(codes)
+	for _, code := range codes {
+		if !strings.HasPrefix(code, "2") {
+			continue
+		}
+		if _, ok := op.Responses.values["204"]; ok {
+			code = "204"
+		}
+		resp := op.Responses.values[code]
+		ep.ResultHeaders = len(resp.Headers) > 0
+		if mt, ok := resp.Content.values["application/json"]; ok {
+			ep.ResultKind, ep.ResultSchema = bodyJSON, mt.Schema
+		} else if _, ok := resp.Content.values["application/octet-stream"]; ok {
+			ep.ResultKind = bodyBinary
+		} else if _, ok := resp.Content.values["application/x-tar"]; ok {
+			ep.ResultKind = bodyBinary
+		} else if _, ok := resp.Content.values["text/plain"]; ok {
+			ep.ResultKind = bodyText
+		}
+		break
+	}
+	return ep, nil
+}
+
+// camelToSnake converts an identifier such as putObject to put_object.
+func camelToSnake(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if r >= 'A' && r <= 'Z' {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// snakeToCamel converts an identifier such as destination_type to
+// destinationType.
+func snakeToCamel(s string) string {
+	parts := strings.Split(s, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// docLines folds a summary and description into comment lines.
+func docLines(summary, description string) []string {
+	var lines []string
+	if summary != "" {
+		lines = append(lines, strings.TrimSuffix(summary, ".")+".")
+	}
+	if description != "" && description != summary {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, wrap(strings.Join(strings.Fields(description), " "), 74)...)
+	}
+	return lines
+}
+
+// wrap splits text into lines of at most width characters.
+func wrap(text string, width int) []string {
+	var lines []string
+	var line strings.Builder
+	for _, word := range strings.Fields(text) {
+		if line.Len() > 0 && line.Len()+1+len(word) > width {
+			lines = append(lines, line.String())
+			line.Reset()
+		}
+		if line.Len() > 0 {
+			line.WriteByte(' ')
+		}
+		line.WriteString(word)
+	}
+	if line.Len() > 0 {
+		lines = append(lines, line.String())
+	}
+	return lines
+}
@@ -0,0 +1,285 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+const tsRuntime = `export class ApiError extends Error {
+  constructor(
+    readonly status: number,
+    readonly body: unknown,
+  ) {
+    super(` + "`HTTP ${status}: ${typeof body === 'string' ? body : JSON.stringify(body)}`" + `);
+    this.name = 'ApiError';
+  }
+}
+
+export interface ClientOptions {
+  /** Bearer token sent in the Authorization header. */
+  token?: string;
+  /** Headers sent with every request. */
+  headers?: Record<string, string>;
+  /** fetch implementation; defaults to globalThis.fetch. */
+  fetch?: typeof fetch;
+}
+
+export interface RequestOptions {
+  /** Headers sent with this request, e.g. Content-Type for uploads. */
+  headers?: Record<string, string>;
+  signal?: AbortSignal;
+}
+
+function encodePath(value: string): string {
+  return value.split('/').map(encodeURIComponent).join('/');
+}
+
+export class ObjstoreClient {
+  private readonly baseUrl: string;
+
+  /** @param baseUrl server root, e.g. http://localhost:8080 */
+  constructor(
+    baseUrl: string,
+    private readonly options: ClientOptions = {},
+  ) {
+    this.baseUrl = baseUrl.replace(/\/+$/, '');
+  }
+
+  private async request(
+    method: string,
+    path: string,
+    query: Record<string, unknown> | undefined,
+    body: BodyInit | undefined,
+    contentType: string | undefined,
+    opts: RequestOptions | undefined,
+  ): Promise<Response> {
+    let url = this.baseUrl + path;
+    if (query) {
+      const params = new URLSearchParams();
+      for (const [name, value] of Object.entries(query)) {
+        if (value !== undefined && value !== null) params.set(name, String(value));
+      }
+      const qs = params.toString();
+      if (qs) url += '?' + qs;
+    }
+    const headers: Record<string, string> = { ...this.options.headers, ...opts?.headers };
+    if (this.options.token) headers['Authorization'] = ` + "`Bearer ${this.options.token}`" + `;
+    if (contentType && !Object.keys(headers).some((h) => h.toLowerCase() === 'content-type')) {
+      headers['Content-Type'] = contentType;
+    }
+    const doFetch = this.options.fetch ?? globalThis.fetch;
+    const resp = await doFetch(url, { method, headers, body, signal: opts?.signal });
+    if (!resp.ok) {
+      const text = await resp.text();
+      let parsed: unknown = text;
+      try {
+        parsed = JSON.parse(text);
+      } catch {
+        // not JSON; keep the raw text
+      }
+      throw new ApiError(resp.status, parsed);
+    }
+    return resp;
+  }
+`
+
+// typescript renders a fetch-based TypeScript client.
+func typescript(doc *document, endpoints []endpoint) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// Code generated by go run ./api/openapi/clientgen. DO NOT EDIT.\n")
+	fmt.Fprintf(&b, "// Source: api/openapi/objstore.yaml (%s %s)\n\n", doc.Info.Title, doc.Info.Version)
+
+	for _, name := range doc.Components.Schemas.keys {
+		s := doc.Components.Schemas.values[name]
+		tsComment(&b, "", docLines(s.Description, ""))
+		if len(s.Properties.keys) == 0 {
+			fmt.Fprintf(&b, "export type %s = %s;\n\n", name, tsType(s))
+			continue
+		}
+		fmt.Fprintf(&b, "export interface %s {\n", name)
+		for _, prop := range s.Properties.keys {
+			ps := s.Properties.values[prop]
+			tsComment(&b, "  ", docLines(ps.Description, ""))
+			optional := "?"
+			if s.isRequired(prop) {
+				optional = ""
+			}
+			fmt.Fprintf(&b, "  %s%s: %s;\n", prop, optional, tsType(ps))
+		}
+		b.WriteString("}\n\n")
+	}
+
+	b.WriteString(tsRuntime)
+	for _, ep := range endpoints {
+		b.WriteString("\n")
+		tsMethod(&b, ep)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func tsMethod(b *strings.Builder, ep endpoint) {
+	var args, params []string
+	for _, p := range ep.PathParams {
+		args = append(args, snakeToCamel(p.Name)+": string")
+		params = append(params, fmt.Sprintf("@param %s %s", snakeToCamel(p.Name), p.Description))
+	}
+	for _, p := range ep.RequiredQuery {
+		args = append(args, snakeToCamel(p.Name)+": "+tsType(p.Schema))
+		params = append(params, fmt.Sprintf("@param %s %s", snakeToCamel(p.Name), p.Description))
+	}
+	switch ep.BodyKind {
+	case bodyBinary:
+		args = append(args, "body: BodyInit")
+	case bodyForm:
+		args = append(args, "body: FormData")
+		params = append(params, fmt.Sprintf("@param body form fields, with the file last as %q", ep.FormFile))
+	case bodyJSON:
+		optional := "?"
+		if ep.BodyRequired {
+			optional = ""
+		}
+		args = append(args, "body"+optional+": "+tsType(ep.BodySchema))
+	}
+	if len(ep.OptionalQuery) > 0 {
+		var fields []string
+		for _, p := range ep.OptionalQuery {
+			fields = append(fields, fmt.Sprintf("%s?: %s", p.Name, tsType(p.Schema)))
+			params = append(params, fmt.Sprintf("@param query.%s %s", p.Name, p.Description))
+		}
+		args = append(args, "query: { "+strings.Join(fields, "; ")+" } = {}")
+	}
+	args = append(args, "opts?: RequestOptions")
+
+	var result string
+	switch ep.ResultKind {
+	case bodyJSON:
+		result = tsType(ep.ResultSchema)
+	case bodyBinary:
+		result = "Response"
+	case bodyText:
+		result = "string"
+	default:
+		result = "void"
+		if ep.ResultHeaders {
+			result = "Headers"
+		}
+	}
+
+	lines := docLines(ep.Summary, ep.Description)
+	if len(params) > 0 {
+		lines = append(lines, "")
+		lines = append(lines, params...)
+	}
+	tsComment(b, "  ", lines)
+	fmt.Fprintf(b, "  async %s(%s): Promise<%s> {\n", ep.ID, strings.Join(args, ", "), result)
+
+	path := ep.Path
+	for _, p := range ep.PathParams {
+		path = strings.ReplaceAll(path, "{"+p.Name+"}", "${encodePath("+snakeToCamel(p.Name)+")}")
+	}
+	query := "undefined"
+	if len(ep.RequiredQuery)+len(ep.OptionalQuery) > 0 {
+		var fields []string
+		for _, p := range ep.RequiredQuery {
+			fields = append(fields, fmt.Sprintf("%s: %s", p.Name, snakeToCamel(p.Name)))
+		}
+		if len(ep.OptionalQuery) > 0 {
+			fields = append(fields, "...query")
+		}
+		query = "{ " + strings.Join(fields, ", ") + " }"
+	}
+	body, contentType := "undefined", "undefined"
+	switch ep.BodyKind {
+	case bodyBinary:
+		body, contentType = "body", "'application/octet-stream'"
+	case bodyForm:
+		// fetch sets the multipart boundary itself.
+		body = "body"
+	case bodyJSON:
+		body, contentType = "body === undefined ? undefined : JSON.stringify(body)", "'application/json'"
+	}
+
+	call := fmt.Sprintf("this.request('%s', `%s`, %s, %s, %s, opts)", ep.Method, path, query, body, contentType)
+	switch result {
+	case "void":
+		fmt.Fprintf(b, "    await %s;\n", call)
+	case "Headers":
+		fmt.Fprintf(b, "    return (await %s).headers;\n", call)
+	case "Response":
+		fmt.Fprintf(b, "    return %s;\n", call)
+	case "string":
+		fmt.Fprintf(b, "    return (await %s).text();\n", call)
+	default:
+		fmt.Fprintf(b, "    return (await (await %s).json()) as %s;\n", call, result)
+	}
+	b.WriteString("  }\n")
+}
+
+func tsComment(b *strings.Builder, indent string, lines []string) {
+	if len(lines) == 0 {
+		return
+	}
+	if len(lines) == 1 {
+		fmt.Fprintf(b, "%s/** %s */\n", indent, lines[0])
+		return
+	}
+	fmt.Fprintf(b, "%s/**\n", indent)
+	for _, line := range lines {
+		fmt.Fprintf(b, "%s%s\n", indent, strings.TrimRight(" * "+line, " "))
+	}
+	fmt.Fprintf(b, "%s */\n", indent)
+}
+
+// tsType maps a schema to a TypeScript type.
+func tsType(s *schema) string {
+	if s == nil {
+		return "unknown"
+	}
+	if s.Ref != "" {
+		return s.refName()
+	}
+	if len(s.Enum) > 0 {
+		values := make([]string, len(s.Enum))
+		for i, v := range s.Enum {
+			values[i] = "'" + v + "'"
+		}
+		return strings.Join(values, " | ")
+	}
+	switch s.Type {
+	case "string":
+		if s.Format == "binary" {
+			return "Blob"
+		}
+		return "string"
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "array":
+		item := tsType(s.Items)
+		if strings.Contains(item, " ") {
+			item = "(" + item + ")"
+		}
+		return item + "[]"
+	case "object":
+		if s.AdditionalProperties != nil && s.AdditionalProperties.schema != nil {
+			return "Record<string, " + tsType(s.AdditionalProperties.schema) + ">"
+		}
+		return "Record<string, unknown>"
+	}
+	return "unknown"
+}
@@ -0,0 +1,2233 @@
+# Code generated by go run ./api/openapi/clientgen. DO NOT EDIT.
+# Source: api/openapi/objstore.yaml (Go ObjectStore REST API 0.1.0-beta)
+"""Python client for the Go ObjectStore REST API."""
+
+from __future__ import annotations
+
+import json
+import urllib.error
+import urllib.parse
+import urllib.request
+import uuid
+from typing import Any, Dict, List, Optional, Tuple, TypedDict
+
+
+class ErrorResponse(TypedDict, total=False):
+    """Body of every error response, shared by the REST and QUIC servers. Branch on code rather than on message, whose wording may change.
+
+    Required keys: error, code, status, message, retryable.
+    """
+    error: str
+    code: str
+    status: int
+    message: str
+    request_id: str
+    retryable: bool
+    details: Dict[str, Any]
+
+
+class SuccessResponse(TypedDict, total=False):
+    """Required keys: message."""
+    message: str
+    data: Dict[str, Any]
+
+
+class PutObjectResponse(TypedDict, total=False):
+    """Required keys: message, data."""
+    message: str
+    data: PutResult
+
+
+class PutResult(TypedDict, total=False):
+    """The object written by an upload.
+
+    Required keys: key, etag, size.
+    """
+    key: str
+    etag: str
+    version: str
+    size: int
+    last_modified: str
+
+
+class RenameResult(TypedDict, total=False):
+    """Required keys: moved, skipped, failed."""
+    moved: int
+    skipped: int
+    failed: int
+    dry_run: bool
+
+
+class HealthResponse(TypedDict, total=False):
+    """Required keys: status."""
+    status: str
+    version: str
+
+
+class Metadata(TypedDict, total=False):
+    content_type: str
+    content_encoding: str
+    size: int
+    last_modified: str
+    etag: str
+    custom: Dict[str, str]
+
+
+class MetadataPatch(TypedDict, total=False):
+    """Changes to apply to existing metadata; empty fields are left unchanged."""
+    content_type: str
+    content_encoding: str
+    storage_class: str
+    set: Dict[str, str]
+    remove: List[str]
+
+
+class ObjectResponse(TypedDict, total=False):
+    """Required keys: key, size."""
+    key: str
+    size: int
+    modified: str
+    etag: str
+    content_type: str
+    metadata: Dict[str, str]
+
+
+class ListObjectsResponse(TypedDict, total=False):
+    """Required keys: objects, truncated."""
+    objects: List[ObjectResponse]
+    common_prefixes: List[str]
+    next_token: str
+    truncated: bool
+
+
+class SearchResponse(TypedDict, total=False):
+    """Required keys: query, objects, count."""
+    query: str
+    objects: List[ObjectResponse]
+    count: int
+
+
+class Folder(TypedDict, total=False):
+    """Required keys: prefix, objects, bytes."""
+    prefix: str
+    objects: int
+    bytes: int
+
+
+class BrowseResponse(TypedDict, total=False):
+    """Required keys: prefix, folders, objects, total_objects, total_bytes."""
+    prefix: str
+    folders: List[Folder]
+    objects: List[ObjectResponse]
+    total_objects: int
+    total_bytes: int
+
+
+class Change(TypedDict, total=False):
+    """Required keys: sequence, op, key, timestamp."""
+    sequence: int
+    op: str
+    key: str
+    etag: str
+    size: int
+    timestamp: str
+
+
+class ChangeList(TypedDict, total=False):
+    """Required keys: changes, next, latest, more."""
+    changes: List[Change]
+    next: int
+    latest: int
+    more: bool
+
+
+class StorageSnapshot(TypedDict, total=False):
+    time: str
+    objects: int
+    bytes: int
+    top_prefixes: List[Dict[str, Any]]
+
+
+class StorageStats(TypedDict, total=False):
+    backends: List[Dict[str, Any]]
+
+
+class AgeBucket(TypedDict, total=False):
+    label: str
+    objects: int
+    bytes: int
+
+
+class AgeDistribution(TypedDict, total=False):
+    prefix: str
+    objects: int
+    bytes: int
+    age: List[AgeBucket]
+    access: List[AgeBucket]
+
+
+class AccessReport(TypedDict, total=False):
+    generated_at: str
+    tracking_since: str
+    total: AgeDistribution
+    prefixes: List[AgeDistribution]
+
+
+class Cost(TypedDict, total=False):
+    storage: float
+    requests: float
+    egress: float
+    total: float
+
+
+class CostLine(TypedDict, total=False):
+    backend: str
+    group: str
+    requests: Dict[str, int]
+    egress_bytes: int
+    stored_bytes: int
+    gb_months: float
+    cost: Cost
+    projected: Cost
+
+
+class DiffChange(TypedDict, total=False):
+    op: str
+    path: str
+    old: Any
+    new: Any
+
+
+class DiffResult(TypedDict, total=False):
+    mode: str
+    identical: bool
+    size_a: int
+    size_b: int
+    unified: str
+    changes: List[DiffChange]
+    first_difference: int
+    truncated: bool
+
+
+class CostReport(TypedDict, total=False):
+    month: str
+    currency: str
+    generated_at: str
+    lines: List[CostLine]
+    groups: List[Dict[str, Any]]
+    total: Cost
+    projected: Cost
+
+
+class JobRun(TypedDict, total=False):
+    job: str
+    node: str
+    started_at: str
+    finished_at: str
+    outcome: str
+    message: str
+    error: str
+
+
+class JobStatus(TypedDict, total=False):
+    name: str
+    interval_seconds: int
+    running: bool
+    last_run: JobRun
+    next_run: str
+    history: List[JobRun]
+
+
+class JobList(TypedDict, total=False):
+    jobs: List[JobStatus]
+
+
+class Task(TypedDict, total=False):
+    id: str
+    type: str
+    payload: Dict[str, Any]
+    state: str
+    attempts: int
+    max_attempts: int
+    next_attempt: str
+    last_error: str
+    node: str
+    created_at: str
+    updated_at: str
+
+
+class TaskList(TypedDict, total=False):
+    tasks: List[Task]
+
+
+class ArchiveRequest(TypedDict, total=False):
+    """Required keys: key, destination_type."""
+    key: str
+    destination_type: str
+    destination_settings: Dict[str, str]
+    async: bool
+
+
+class PolicyRequest(TypedDict, total=False):
+    """Required keys: id, retention_seconds, action."""
+    id: str
+    prefix: str
+    retention_seconds: int
+    action: str
+    destination_type: str
+    destination_settings: Dict[str, str]
+
+
+class PolicyResponse(TypedDict, total=False):
+    id: str
+    prefix: str
+    retention_seconds: int
+    action: str
+
+
+class PolicyListResponse(TypedDict, total=False):
+    policies: List[PolicyResponse]
+    count: int
+
+
+class ApplyPoliciesResponse(TypedDict, total=False):
+    message: str
+    policies_count: int
+    objects_processed: int
+
+
+class ReplicationPolicyRequest(TypedDict, total=False):
+    """Required keys: id, destination_backend."""
+    id: str
+    source_backend: str
+    destination_backend: str
+    destination_settings: Dict[str, str]
+    enabled: bool
+    write_once: bool
+
+
+class ReplicationPolicyResponse(TypedDict, total=False):
+    id: str
+    source_backend: str
+    destination_backend: str
+    enabled: bool
+    write_once: bool
+
+
+class ReplicationPolicyListResponse(TypedDict, total=False):
+    policies: List[ReplicationPolicyResponse]
+    count: int
+
+
+class TriggerReplicationRequest(TypedDict, total=False):
+    """Required keys: policy_id."""
+    policy_id: str
+    async: bool
+
+
+class ReplicationStatusResponse(TypedDict, total=False):
+    policy_id: str
+    status: str
+    objects_replicated: int
+    last_run: str
+    error: str
+
+
+class TokenRequest(TypedDict, total=False):
+    """Required keys: actions."""
+    prefix: str
+    actions: List[str]
+    ttl_seconds: int
+
+
+class TokenResponse(TypedDict, total=False):
+    """Required keys: token, expires_at, actions."""
+    token: str
+    expires_at: str
+    prefix: str
+    actions: List[str]
+
+
+class DeletionRequest(TypedDict, total=False):
+    """Required keys: id, key, status, requested_at."""
+    id: str
+    key: str
+    status: str
+    requested_by: str
+    requested_at: str
+    decided_by: str
+    decided_at: str
+    reason: str
+
+
+class DeletionRequestList(TypedDict, total=False):
+    """Required keys: requests, count."""
+    requests: List[DeletionRequest]
+    count: int
+
+
+class RejectDeletionRequest(TypedDict, total=False):
+    reason: str
+
+
+class LegalHoldRequest(TypedDict, total=False):
+    reason: str
+
+
+class LegalHold(TypedDict, total=False):
+    """Required keys: key, placed_at."""
+    key: str
+    placed_by: str
+    placed_at: str
+    reason: str
+
+
+class LegalHoldList(TypedDict, total=False):
+    """Required keys: holds, count."""
+    holds: List[LegalHold]
+    count: int
+
+
+class AcquireLockRequest(TypedDict, total=False):
+    ttl_seconds: int
+    wait_seconds: int
+    owner: str
+
+
+class Lock(TypedDict, total=False):
+    """Required keys: key, acquired_at, expires_at."""
+    key: str
+    token: str
+    owner: str
+    acquired_at: str
+    expires_at: str
+
+
+class LockList(TypedDict, total=False):
+    """Required keys: locks, count."""
+    locks: List[Lock]
+    count: int
+
+
+class SetQuotaRequest(TypedDict, total=False):
+    """Zero or missing limits are unlimited."""
+    max_bytes: int
+    max_objects: int
+
+
+class Quota(TypedDict, total=False):
+    """Required keys: prefix."""
+    prefix: str
+    max_bytes: int
+    max_objects: int
+
+
+class QuotaList(TypedDict, total=False):
+    """Required keys: quotas, count."""
+    quotas: List[Quota]
+    count: int
+
+
+class WebhookSink(TypedDict, total=False):
+    """Required keys: type."""
+    type: str
+    settings: Dict[str, str]
+
+
+class SetWebhookRequest(TypedDict, total=False):
+    """Required keys: sink."""
+    events: List[str]
+    prefix: str
+    suffix: str
+    sink: WebhookSink
+
+
+class Webhook(TypedDict, total=False):
+    """Required keys: id, sink."""
+    id: str
+    events: List[str]
+    prefix: str
+    suffix: str
+    sink: WebhookSink
+
+
+class WebhookList(TypedDict, total=False):
+    """Required keys: webhooks, count."""
+    webhooks: List[Webhook]
+    count: int
+
+
+class CreateAccessKeyRequest(TypedDict, total=False):
+    """Required keys: principal_id."""
+    principal_id: str
+    roles: List[str]
+    description: str
+
+
+class AccessKey(TypedDict, total=False):
+    """Required keys: access_key_id, principal_id, created_at."""
+    access_key_id: str
+    secret_access_key: str
+    principal_id: str
+    roles: List[str]
+    description: str
+    created_at: str
+
+
+class AccessKeyList(TypedDict, total=False):
+    """Required keys: access_keys, count."""
+    access_keys: List[AccessKey]
+    count: int
+
+
+class SetAliasRequest(TypedDict, total=False):
+    """Required keys: target."""
+    target: str
+
+
+class Alias(TypedDict, total=False):
+    """Required keys: name, target, updated_at."""
+    name: str
+    target: str
+    updated_at: str
+    updated_by: str
+
+
+class AliasList(TypedDict, total=False):
+    """Required keys: aliases, count."""
+    aliases: List[Alias]
+    count: int
+
+
+class CreateShareRequest(TypedDict, total=False):
+    ttl_seconds: int
+    max_downloads: int
+    password: str
+    bytes_per_second: int
+
+
+class Share(TypedDict, total=False):
+    """Required keys: id, key, status, created_at, expires_at, password_protected, downloads, bytes_served."""
+    id: str
+    key: str
+    token: str
+    url: str
+    status: str
+    created_by: str
+    created_at: str
+    expires_at: str
+    max_downloads: int
+    bytes_per_second: int
+    password_protected: bool
+    downloads: int
+    bytes_served: int
+    last_download: str
+
+
+class ShareList(TypedDict, total=False):
+    """Required keys: shares, count."""
+    shares: List[Share]
+    count: int
+
+
+class CreateUploadRequest(TypedDict, total=False):
+    ttl_seconds: int
+    size: int
+    content_type: str
+    content_encoding: str
+    metadata: Dict[str, str]
+
+
+class CompleteUploadRequest(TypedDict, total=False):
+    sha256: str
+
+
+class UploadChunk(TypedDict, total=False):
+    """Required keys: number, size, sha256."""
+    number: int
+    size: int
+    sha256: str
+
+
+class UploadSession(TypedDict, total=False):
+    """Required keys: id, key, created_at, expires_at, chunks, received."""
+    id: str
+    key: str
+    created_by: str
+    created_at: str
+    expires_at: str
+    size: int
+    content_type: str
+    content_encoding: str
+    metadata: Dict[str, str]
+    chunks: List[UploadChunk]
+    received: int
+
+
+class CreateManifestRequest(TypedDict, total=False):
+    """Required keys: name."""
+    name: str
+    keys: List[str]
+
+
+class UpdateManifestMembersRequest(TypedDict, total=False):
+    add: List[str]
+    remove: List[str]
+
+
+class ManifestMember(TypedDict, total=False):
+    """Required keys: key, size."""
+    key: str
+    etag: str
+    size: int
+
+
+class Manifest(TypedDict, total=False):
+    """Required keys: name, version, members, size, updated_at."""
+    name: str
+    version: int
+    members: List[ManifestMember]
+    size: int
+    updated_at: str
+    published_by: str
+
+
+class ManifestList(TypedDict, total=False):
+    """Required keys: manifests, count."""
+    manifests: List[str]
+    count: int
+
+
+class ManifestVersionList(TypedDict, total=False):
+    """Required keys: name, versions, count."""
+    name: str
+    versions: List[int]
+    count: int
+
+
+class SelectObjectContentRequest(TypedDict, total=False):
+    """Required keys: expression, input."""
+    expression: str
+    input: SelectInput
+    output: SelectOutput
+
+
+class SelectInput(TypedDict, total=False):
+    """Required keys: format."""
+    format: str
+    compression: str
+    csv: SelectCSVInput
+
+
+class SelectCSVInput(TypedDict, total=False):
+    file_header_info: str
+    field_delimiter: str
+    comments: str
+
+
+class SelectOutput(TypedDict, total=False):
+    format: str
+    field_delimiter: str
+
+
+class ApiError(Exception):
+    """Raised when the server answers with a non-2xx status."""
+
+    def __init__(self, status: int, body: Any) -> None:
+        super().__init__(f"HTTP {status}: {body}")
+        self.status = status
+        self.body = body
+
+
+def _encode_path(value: str) -> str:
+    return urllib.parse.quote(value, safe="/")
+
+
+def _query_value(value: Any) -> str:
+    if isinstance(value, bool):
+        return "true" if value else "false"
+    return str(value)
+
+
+def _encode_form(fields: Dict[str, str], file_field: str, file: bytes, filename: str) -> Tuple[bytes, str]:
+    boundary = uuid.uuid4().hex
+    parts = []
+    for name, value in fields.items():
+        parts.append(f'--{boundary}\r\nContent-Disposition: form-data; name="{name}"\r\n\r\n{value}\r\n'.encode())
+    parts.append(
+        f'--{boundary}\r\nContent-Disposition: form-data; name="{file_field}"; filename="{filename}"\r\n'
+        "Content-Type: application/octet-stream\r\n\r\n".encode()
+    )
+    parts.append(file)
+    parts.append(f"\r\n--{boundary}--\r\n".encode())
+    return b"".join(parts), "multipart/form-data; boundary=" + boundary
+
+
+class ObjstoreClient:
+    """Client for the REST API.
+
+    base_url is the server root, e.g. http://localhost:8080.
+    """
+
+    def __init__(
+        self,
+        base_url: str,
+        token: Optional[str] = None,
+        headers: Optional[Dict[str, str]] = None,
+        timeout: float = 30.0,
+    ) -> None:
+        self.base_url = base_url.rstrip("/")
+        self.token = token
+        self.headers = dict(headers or {})
+        self.timeout = timeout
+
+    def _request(
+        self,
+        method: str,
+        path: str,
+        query: Optional[Dict[str, Any]] = None,
+        body: Optional[bytes] = None,
+        content_type: Optional[str] = None,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> Tuple[Dict[str, str], bytes]:
+        url = self.base_url + path
+        if query:
+            params = {k: _query_value(v) for k, v in query.items() if v is not None}
+            if params:
+                url += "?" + urllib.parse.urlencode(params)
+        all_headers = dict(self.headers)
+        all_headers.update(headers or {})
+        if self.token:
+            all_headers["Authorization"] = "Bearer " + self.token
+        if content_type and not any(k.lower() == "content-type" for k in all_headers):
+            all_headers["Content-Type"] = content_type
+        req = urllib.request.Request(url, data=body, method=method, headers=all_headers)
+        try:
+            with urllib.request.urlopen(req, timeout=self.timeout) as resp:  # nosec B310 - base_url is caller configuration
+                return dict(resp.headers.items()), resp.read()
+        except urllib.error.HTTPError as err:
+            raw = err.read()
+            try:
+                parsed: Any = json.loads(raw)
+            except ValueError:
+                parsed = raw.decode("utf-8", "replace")
+            raise ApiError(err.code, parsed) from None
+
+    def health_check(
+        self,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> HealthResponse:
+        """Health check.
+
+        Check if the server is healthy and responsive
+        """
+        _, data = self._request("GET", "/health", None, None, None, headers)
+        result: HealthResponse = json.loads(data)
+        return result
+
+    def metrics(
+        self,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> str:
+        """Prometheus metrics.
+
+        Prometheus exposition endpoint. Served at the server root (not under
+        /api/v1). Requires authentication and authorization by default; set the
+        server's MetricsPublic configuration flag (--metrics-public) to expose it
+        anonymously for Prometheus scrapers.
+        """
+        _, data = self._request("GET", "/metrics", None, None, None, headers)
+        return data.decode("utf-8")
+
+    def download_share(
+        self,
+        token: str,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> bytes:
+        """Download through a share link.
+
+        Download the object of a share link. No credentials are needed beyond the
+        link token; a password-protected link also needs its password in the
+        X-Share-Password header or as the password of HTTP basic authentication.
+        Each download counts against the link's download limit when it starts, and
+        is streamed no faster than the link's bandwidth cap.
+
+        Args:
+            token: Link token
+        """
+        _, data = self._request("GET", f"/share/{_encode_path(token)}", None, None, None, headers)
+        return data
+
+    def upload_with_post_policy(
+        self,
+        fields: Dict[str, str],
+        file: bytes,
+        filename: str = "file",
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> Dict[str, str]:
+        """Upload with a browser form (POST policy).
+
+        Upload an object from an HTML form signed with an S3-style POST policy, so
+        browsers can upload directly without holding credentials. No other
+        authentication is needed: the policy, signed with a SigV4 key, is the
+        credential. Every form field must meet a condition of the policy, the file
+        size must be within its content-length-range, and the signer must be
+        allowed to write the key. Field names are case-insensitive and the file
+        comes last. Only served when the server is configured with a POST policy
+        authenticator.
+
+        Args:
+            fields: form fields, sent before the file
+            file: file content
+            filename: file name sent with the file
+        """
+        body, content_type = _encode_form(fields, "file", file, filename)
+        resp_headers, _ = self._request("POST", "/upload", None, body, content_type, headers)
+        return resp_headers
+
+    def list_objects(
+        self,
+        *,
+        prefix: Optional[str] = None,
+        limit: Optional[int] = None,
+        token: Optional[str] = None,
+        delimiter: Optional[str] = None,
+        sort: Optional[str] = None,
+        order: Optional[str] = None,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> ListObjectsResponse:
+        """List objects.
+
+        List objects with optional prefix filtering and pagination support
+
+        Args:
+            prefix: Filter objects by prefix
+            limit: Maximum number of results to return
+            token: Continuation token for pagination
+            delimiter: Delimiter for hierarchical listing (e.g., "/" for directory-like structure)
+            sort: Order the objects of the page by name, size or modification time. Pages are still taken in key order.
+
+            order: Sort direction
+        """
+        _, data = self._request("GET", "/api/v1/objects", {"prefix": prefix, "limit": limit, "token": token, "delimiter": delimiter, "sort": sort, "order": order}, None, None, headers)
+        result: ListObjectsResponse = json.loads(data)
+        return result
+
+    def update_metadata_batch(
+        self,
+        body: MetadataPatch,
+        *,
+        prefix: Optional[str] = None,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> SuccessResponse:
+        """Update metadata under a prefix.
+
+        Apply a metadata patch to every object whose key starts with prefix
+        without rewriting any data. Objects that already match the patch are
+        skipped. A failure on one object does not stop the others; the first error
+        is returned once every object has been visited.
+
+        Args:
+            prefix: Key prefix of the objects to update
+        """
+        _, data = self._request("PATCH", "/api/v1/objects", {"prefix": prefix}, None if body is None else json.dumps(body).encode(), "application/json", headers)
+        result: SuccessResponse = json.loads(data)
+        return result
+
+    def get_object(
+        self,
+        key: str,
+        *,
+        as_of: Optional[str] = None,
+        preset: Optional[str] = None,
+        decompress: Optional[str] = None,
+        range-lines: Optional[str] = None,
+        jq: Optional[str] = None,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> bytes:
+        """Download object.
+
+        Retrieve an object from the storage backend. With `preset`, the object's
+        derived object for that transform preset (such as a thumbnail) is returned
+        instead, generated and cached on first request. With `as_of`, the version
+        of the object that was current at that time is returned from a versioned
+        backend.
+
+        Args:
+            key: Object key/path
+            as_of: Return the version of the object that was current at this RFC 3339 time. Requires a versioned backend (S3, MinIO, GCS) or blob snapshots (Azure).
+
+            preset: Transform preset whose derived object to return
+            decompress: Decompress the object on the server before returning it
+            range-lines: Return only the given 1-based, inclusive line range (FIRST-LAST, FIRST- or -LAST), applied after decompression
+
+            jq: jq expression evaluated against each JSON value in the object; results are returned as newline-delimited JSON
+
+        """
+        _, data = self._request("GET", f"/api/v1/objects/{_encode_path(key)}", {"as_of": as_of, "preset": preset, "decompress": decompress, "range-lines": range-lines, "jq": jq}, None, None, headers)
+        return data
+
+    def put_object(
+        self,
+        key: str,
+        body: bytes,
+        *,
+        atomic: Optional[bool] = None,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> PutObjectResponse:
+        """Upload object.
+
+        Upload an object to the storage backend with optional metadata
+
+        Args:
+            key: Object key/path
+            atomic: Stage the upload under a hidden key, verify it against the data sent and any declared size or checksum-sha256 custom metadata, and only then promote it to the key, so readers never observe a partially written object. A failed atomic upload leaves any existing object untouched.
+        """
+        _, data = self._request("PUT", f"/api/v1/objects/{_encode_path(key)}", {"atomic": atomic}, body, "application/octet-stream", headers)
+        result: PutObjectResponse = json.loads(data)
+        return result
+
+    def delete_object(
+        self,
+        key: str,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> None:
+        """Delete object.
+
+        Remove an object from the storage backend
+
+        Args:
+            key: Object key/path
+        """
+        self._request("DELETE", f"/api/v1/objects/{_encode_path(key)}", None, None, None, headers)
+
+    def head_object(
+        self,
+        key: str,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> Dict[str, str]:
+        """Get object metadata via HEAD.
+
+        Check if an object exists and retrieve its metadata headers without
+        downloading its content. Returns the same metadata headers as GET.
+
+        Args:
+            key: Object key/path
+        """
+        resp_headers, _ = self._request("HEAD", f"/api/v1/objects/{_encode_path(key)}", None, None, None, headers)
+        return resp_headers
+
+    def search_objects(
+        self,
+        q: str,
+        *,
+        limit: Optional[int] = None,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> SearchResponse:
+        """Search objects.
+
+        Find objects whose key, content type or custom metadata match a query.
+        Terms are space-separated and must all match. "field:value" matches a
+        custom metadata field, "content-type:" the content type, "prefix:" the key
+        prefix, and a trailing "*" matches by term prefix. Requires a server
+        started with search enabled.
+
+        Args:
+            q: Search query
+            limit: Maximum number of results to return
+        """
+        _, data = self._request("GET", "/api/v1/search", {"q": q, "limit": limit}, None, None, headers)
+        result: SearchResponse = json.loads(data)
+        return result
+
+    def browse(
+        self,
+        *,
+        prefix: Optional[str] = None,
+        delimiter: Optional[str] = None,
+        totals: Optional[bool] = None,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> BrowseResponse:
+        """Browse a virtual directory.
+
+        Return the folders and objects directly under a prefix. Folders are the
+        common prefixes one delimiter deeper. With totals, each folder carries the
+        number and total size of the objects under it, which lists every object
+        under the prefix.
+
+        Args:
+            prefix: Prefix to browse; usually empty or ending in the delimiter
+            delimiter: Separator of virtual directory levels
+            totals: Count the objects and bytes under each folder
+        """
+        _, data = self._request("GET", "/api/v1/browse", {"prefix": prefix, "delimiter": delimiter, "totals": totals}, None, None, headers)
+        result: BrowseResponse = json.loads(data)
+        return result
+
+    def get_changes(
+        self,
+        *,
+        since: Optional[int] = None,
+        limit: Optional[int] = None,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> ChangeList:
+        """Read the change feed.
+
+        Return the puts, metadata updates and deletes made after a sequence
+        number, oldest first. Consumers store the returned next sequence and pass
+        it as since on their next call. 410 means the changes after since are no
+        longer retained and the consumer must resynchronize from a full listing.
+        Requires a server started with the change feed enabled.
+
+        Args:
+            since: Return changes with a greater sequence (0 starts at the oldest retained change)
+            limit: Maximum number of changes to return
+        """
+        _, data = self._request("GET", "/api/v1/changes", {"since": since, "limit": limit}, None, None, headers)
+        result: ChangeList = json.loads(data)
+        return result
+
+    def get_stats(
+        self,
+        *,
+        backend: Optional[str] = None,
+        since: Optional[str] = None,
+        format: Optional[str] = None,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> StorageStats:
+        """Storage statistics over time.
+
+        Return periodic snapshots of the object count, total size and largest
+        prefixes of each backend, with the average daily growth over the returned
+        window. With format=prometheus the latest snapshot of each backend is
+        rendered as Prometheus gauges instead. Requires a server started with
+        storage statistics enabled.
+
+        Args:
+            backend: Only return this backend
+            since: Start of the window, as an RFC 3339 time or a duration back from now such as 168h (default the whole retained history)
+            format: Response format
+        """
+        _, data = self._request("GET", "/api/v1/stats", {"backend": backend, "since": since, "format": format}, None, None, headers)
+        result: StorageStats = json.loads(data)
+        return result
+
+    def analyze_prefix(
+        self,
+        *,
+        prefix: Optional[str] = None,
+        depth: Optional[int] = None,
+        buckets: Optional[str] = None,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> AccessReport:
+        """Age and access distribution of a prefix.
+
+        Count the objects under a prefix in buckets by the time since they were
+        last modified and, on a server started with access tracking enabled, by
+        the time since they were last read, in total and grouped by key segments
+        below the prefix. Objects not read since tracking started are counted in a
+        final "never" bucket. Every object under the prefix is listed.
+
+        Args:
+            prefix: Only count keys starting with this prefix
+            depth: Key segments below the prefix objects are grouped by (default 1, negative for totals only)
+            buckets: Comma-separated ascending bucket bounds, as days such as 30d or durations such as 12h (default 1d,7d,30d,90d,180d,365d)
+        """
+        _, data = self._request("GET", "/api/v1/analyze", {"prefix": prefix, "depth": depth, "buckets": buckets}, None, None, headers)
+        result: AccessReport = json.loads(data)
+        return result
+
+    def get_cost_report(
+        self,
+        *,
+        month: Optional[str] = None,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> CostReport:
+        """Estimate monthly cost.
+
+        Return the storage, request and egress cost of a month by backend and
+        group, priced with the server's pricing tables. For the current month the
+        report also projects the cost of the whole month from the bytes stored now
+        and the request and egress rates so far. Requires a server started with
+        cost metering enabled.
+
+        Args:
+            month: Month to report, formatted as YYYY-MM (default the current month, UTC)
+        """
+        _, data = self._request("GET", "/api/v1/cost", {"month": month}, None, None, headers)
+        result: CostReport = json.loads(data)
+        return result
+
+    def list_jobs(
+        self,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> JobList:
+        """List background jobs.
+
+        Return the status of every background job the server schedules, such as
+        lifecycle, replication and inventory: whether it is running, its last run
+        and when it is next due. Requires a server started with at least one
+        background job.
+        """
+        _, data = self._request("GET", "/api/v1/jobs", None, None, None, headers)
+        result: JobList = json.loads(data)
+        return result
+
+    def get_job(
+        self,
+        name: str,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> JobStatus:
+        """Get a background job.
+
+        Return the status of a background job with its run history, newest first.
+
+        Args:
+            name: Job name
+        """
+        _, data = self._request("GET", f"/api/v1/jobs/{_encode_path(name)}", None, None, None, headers)
+        result: JobStatus = json.loads(data)
+        return result
+
+    def list_tasks(
+        self,
+        *,
+        state: Optional[str] = None,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> TaskList:
+        """List tasks.
+
+        Return queued tasks, oldest first: asynchronous archives, replication
+        syncs and notification redeliveries with their attempts and last error.
+        Requires a server started with --tasks.
+
+        Args:
+            state: Only return tasks in this state
+        """
+        _, data = self._request("GET", "/api/v1/tasks", {"state": state}, None, None, headers)
+        result: TaskList = json.loads(data)
+        return result
+
+    def get_task(
+        self,
+        id: str,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> Task:
+        """Get a task.
+
+        Return a queued task.
+
+        Args:
+            id: Task ID
+        """
+        _, data = self._request("GET", f"/api/v1/tasks/{_encode_path(id)}", None, None, None, headers)
+        result: Task = json.loads(data)
+        return result
+
+    def retry_task(
+        self,
+        id: str,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> Task:
+        """Retry a dead-lettered task.
+
+        Move a dead-lettered task back to pending with a fresh set of attempts.
+
+        Args:
+            id: Task ID
+        """
+        _, data = self._request("POST", f"/api/v1/tasks/{_encode_path(id)}/retry", None, None, None, headers)
+        result: Task = json.loads(data)
+        return result
+
+    def select_object_content(
+        self,
+        key: str,
+        body: SelectObjectContentRequest,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> str:
+        """Query object content with SQL.
+
+        Run an S3 Select-style SQL query over a CSV, JSON or Parquet object and
+        stream the matching records. A failure after the response started
+        truncates it and is reported in the X-Select-Error trailer. The query's
+        statistics are sent in the X-Select-Bytes-Scanned,
+        X-Select-Bytes-Processed and X-Select-Bytes-Returned trailers.
+
+        Args:
+            key: Object key/path
+        """
+        _, data = self._request("POST", f"/api/v1/select/{_encode_path(key)}", None, None if body is None else json.dumps(body).encode(), "application/json", headers)
+        return data.decode("utf-8")
+
+    def diff_objects(
+        self,
+        a: str,
+        b: str,
+        *,
+        mode: Optional[str] = None,
+        context: Optional[int] = None,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> DiffResult:
+        """Compare two objects.
+
+        Compare two objects on the server. Text is compared line by line and
+        returned as a unified diff, JSON documents are compared structurally (each
+        added, removed or replaced value by its JSON Pointer path), and anything
+        else byte by byte. Text and JSON comparison is limited to 16 MiB per
+        object and 1000 changes; without a mode, larger objects are compared as
+        binary.
+
+        Args:
+            a: First object key, optionally followed by @ and an RFC 3339 time to compare the version that was current then
+
+            b: Second object key, in the same form as a
+            mode: Comparison mode (default detected from the content)
+            context: Unchanged lines shown around each change in a text diff (default 3)
+        """
+        _, data = self._request("GET", "/api/v1/diff", {"a": a, "b": b, "mode": mode, "context": context}, None, None, headers)
+        result: DiffResult = json.loads(data)
+        return result
+
+    def rename_prefix(
+        self,
+        prefix: str,
+        new_prefix: str,
+        *,
+        dry_run: Optional[bool] = None,
+        overwrite: Optional[bool] = None,
+        concurrency: Optional[int] = None,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> RenameResult:
+        """Rename a prefix.
+
+        Move every object whose key starts with prefix to the same key under
+        new_prefix. Objects are copied in parallel, server-side where the backend
+        supports it, keeping their metadata, and each is deleted from its old key
+        only once its copy succeeded. Objects that already exist at a new key with
+        different content are skipped unless overwrite is set. Running the same
+        rename again resumes it: objects already copied are recognized and only
+        removed from their old key. A failure on one object does not stop the
+        others; the first error is returned once every object has been visited.
+        Requires delete permission on prefix and write permission on new_prefix.
+
+        Args:
+            prefix: Key prefix of the objects to move
+            new_prefix: Key prefix to move them to; neither prefix may contain the other
+            dry_run: Report what would be moved and skipped without changing anything
+            overwrite: Replace objects that already exist at a new key with different content
+            concurrency: Objects moved in parallel (default 8)
+        """
+        _, data = self._request("POST", "/api/v1/rename", {"prefix": prefix, "new_prefix": new_prefix, "dry_run": dry_run, "overwrite": overwrite, "concurrency": concurrency}, None, None, headers)
+        result: RenameResult = json.loads(data)
+        return result
+
+    def exists_object(
+        self,
+        key: str,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> None:
+        """Check object existence.
+
+        Lightweight existence check for an object. Returns 200 if the object
+        exists, 404 otherwise. No body is returned.
+
+        Args:
+            key: Object key/path
+        """
+        self._request("HEAD", f"/api/v1/exists/{_encode_path(key)}", None, None, None, headers)
+
+    def get_object_metadata(
+        self,
+        key: str,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> ObjectResponse:
+        """Get object metadata.
+
+        Retrieve metadata for an object as a JSON body without downloading the
+        object content. This route is parity with the QUIC GET /metadata/<key>
+        route and the gRPC GetMetadata RPC.
+
+        Args:
+            key: Object key/path
+        """
+        _, data = self._request("GET", f"/api/v1/metadata/{_encode_path(key)}", None, None, None, headers)
+        result: ObjectResponse = json.loads(data)
+        return result
+
+    def update_object_metadata(
+        self,
+        key: str,
+        body: Metadata,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> SuccessResponse:
+        """Update object metadata.
+
+        Update metadata for an existing object
+
+        Args:
+            key: Object key/path
+        """
+        _, data = self._request("PUT", f"/api/v1/metadata/{_encode_path(key)}", None, None if body is None else json.dumps(body).encode(), "application/json", headers)
+        result: SuccessResponse = json.loads(data)
+        return result
+
+    def archive_object(
+        self,
+        body: ArchiveRequest,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> SuccessResponse:
+        """Archive object.
+
+        Archive an object to a cold-storage destination (e.g., Glacier). The
+        object is read from the active backend and written to the specified
+        destination.
+        """
+        _, data = self._request("POST", "/api/v1/archive", None, None if body is None else json.dumps(body).encode(), "application/json", headers)
+        result: SuccessResponse = json.loads(data)
+        return result
+
+    def get_policies(
+        self,
+        *,
+        prefix: Optional[str] = None,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> PolicyListResponse:
+        """List lifecycle policies.
+
+        Retrieve all configured lifecycle policies, optionally filtered by prefix
+
+        Args:
+            prefix: Filter policies by object-key prefix
+        """
+        _, data = self._request("GET", "/api/v1/policies", {"prefix": prefix}, None, None, headers)
+        result: PolicyListResponse = json.loads(data)
+        return result
+
+    def add_policy(
+        self,
+        body: PolicyRequest,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> SuccessResponse:
+        """Add lifecycle policy.
+
+        Create a new lifecycle policy
+        """
+        _, data = self._request("POST", "/api/v1/policies", None, None if body is None else json.dumps(body).encode(), "application/json", headers)
+        result: SuccessResponse = json.loads(data)
+        return result
+
+    def remove_policy(
+        self,
+        id: str,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> SuccessResponse:
+        """Remove lifecycle policy.
+
+        Delete a lifecycle policy by its ID
+
+        Args:
+            id: Policy ID
+        """
+        _, data = self._request("DELETE", f"/api/v1/policies/{_encode_path(id)}", None, None, None, headers)
+        result: SuccessResponse = json.loads(data)
+        return result
+
+    def apply_policies(
+        self,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> ApplyPoliciesResponse:
+        """Apply lifecycle policies.
+
+        Evaluate all lifecycle policies against the current object set and execute
+        the configured actions (delete or archive) for matching objects that have
+        exceeded their retention period.
+        """
+        _, data = self._request("POST", "/api/v1/policies/apply", None, None, None, headers)
+        result: ApplyPoliciesResponse = json.loads(data)
+        return result
+
+    def get_replication_policies(
+        self,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> ReplicationPolicyListResponse:
+        """List replication policies.
+
+        Retrieve all configured replication policies
+        """
+        _, data = self._request("GET", "/api/v1/replication/policies", None, None, None, headers)
+        result: ReplicationPolicyListResponse = json.loads(data)
+        return result
+
+    def add_replication_policy(
+        self,
+        body: ReplicationPolicyRequest,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> SuccessResponse:
+        """Add replication policy.
+
+        Create a new replication policy
+        """
+        _, data = self._request("POST", "/api/v1/replication/policies", None, None if body is None else json.dumps(body).encode(), "application/json", headers)
+        result: SuccessResponse = json.loads(data)
+        return result
+
+    def get_replication_policy(
+        self,
+        id: str,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> ReplicationPolicyResponse:
+        """Get replication policy.
+
+        Retrieve a specific replication policy by ID
+
+        Args:
+            id: Replication policy ID
+        """
+        _, data = self._request("GET", f"/api/v1/replication/policies/{_encode_path(id)}", None, None, None, headers)
+        result: ReplicationPolicyResponse = json.loads(data)
+        return result
+
+    def remove_replication_policy(
+        self,
+        id: str,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> SuccessResponse:
+        """Remove replication policy.
+
+        Delete a replication policy by ID
+
+        Args:
+            id: Replication policy ID
+        """
+        _, data = self._request("DELETE", f"/api/v1/replication/policies/{_encode_path(id)}", None, None, None, headers)
+        result: SuccessResponse = json.loads(data)
+        return result
+
+    def trigger_replication(
+        self,
+        body: TriggerReplicationRequest,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> SuccessResponse:
+        """Trigger replication.
+
+        Manually trigger replication for a specific policy
+        """
+        _, data = self._request("POST", "/api/v1/replication/trigger", None, None if body is None else json.dumps(body).encode(), "application/json", headers)
+        result: SuccessResponse = json.loads(data)
+        return result
+
+    def get_replication_status(
+        self,
+        id: str,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> ReplicationStatusResponse:
+        """Get replication status.
+
+        Retrieve the current replication status for a policy
+
+        Args:
+            id: Replication policy ID
+        """
+        _, data = self._request("GET", f"/api/v1/replication/status/{_encode_path(id)}", None, None, None, headers)
+        result: ReplicationStatusResponse = json.loads(data)
+        return result
+
+    def list_deletion_requests(
+        self,
+        *,
+        status: Optional[str] = None,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> DeletionRequestList:
+        """List deletion requests.
+
+        List deletions of objects under protected prefixes, oldest first. Requires
+        the admin action on the retention resource.
+
+        Args:
+            status: Only return requests with this status
+        """
+        _, data = self._request("GET", "/api/v1/deletions", {"status": status}, None, None, headers)
+        result: DeletionRequestList = json.loads(data)
+        return result
+
+    def get_deletion_request(
+        self,
+        id: str,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> DeletionRequest:
+        """Get deletion request.
+
+        Args:
+            id: Deletion request ID
+        """
+        _, data = self._request("GET", f"/api/v1/deletions/{_encode_path(id)}", None, None, None, headers)
+        result: DeletionRequest = json.loads(data)
+        return result
+
+    def approve_deletion_request(
+        self,
+        id: str,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> DeletionRequest:
+        """Approve deletion request.
+
+        Approve a pending deletion and delete the object. The approver must be a
+        different principal from the requester (two-person rule).
+
+        Args:
+            id: Deletion request ID
+        """
+        _, data = self._request("POST", f"/api/v1/deletions/{_encode_path(id)}/approve", None, None, None, headers)
+        result: DeletionRequest = json.loads(data)
+        return result
+
+    def reject_deletion_request(
+        self,
+        id: str,
+        body: Optional[RejectDeletionRequest] = None,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> DeletionRequest:
+        """Reject deletion request.
+
+        Reject a pending deletion; the object is kept.
+
+        Args:
+            id: Deletion request ID
+        """
+        _, data = self._request("POST", f"/api/v1/deletions/{_encode_path(id)}/reject", None, None if body is None else json.dumps(body).encode(), "application/json", headers)
+        result: DeletionRequest = json.loads(data)
+        return result
+
+    def list_legal_holds(
+        self,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> LegalHoldList:
+        """List legal holds."""
+        _, data = self._request("GET", "/api/v1/holds", None, None, None, headers)
+        result: LegalHoldList = json.loads(data)
+        return result
+
+    def place_legal_hold(
+        self,
+        key: str,
+        body: Optional[LegalHoldRequest] = None,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> LegalHold:
+        """Place legal hold.
+
+        Place a legal hold on an object. Held objects cannot be deleted or
+        overwritten until the hold is released.
+
+        Args:
+            key: Object key/path
+        """
+        _, data = self._request("PUT", f"/api/v1/holds/{_encode_path(key)}", None, None if body is None else json.dumps(body).encode(), "application/json", headers)
+        result: LegalHold = json.loads(data)
+        return result
+
+    def release_legal_hold(
+        self,
+        key: str,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> None:
+        """Release legal hold.
+
+        Args:
+            key: Object key/path
+        """
+        self._request("DELETE", f"/api/v1/holds/{_encode_path(key)}", None, None, None, headers)
+
+    def list_locks(
+        self,
+        *,
+        prefix: Optional[str] = None,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> LockList:
+        """List locks.
+
+        List the held advisory locks. Lock tokens are not returned.
+
+        Args:
+            prefix: Only list locks on keys starting with this prefix
+        """
+        _, data = self._request("GET", "/api/v1/locks", {"prefix": prefix}, None, None, headers)
+        result: LockList = json.loads(data)
+        return result
+
+    def get_lock(
+        self,
+        key: str,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> Lock:
+        """Get lock.
+
+        Get the advisory lock on a key. The lock token is not returned.
+
+        Args:
+            key: Locked key
+        """
+        _, data = self._request("GET", f"/api/v1/locks/{_encode_path(key)}", None, None, None, headers)
+        result: Lock = json.loads(data)
+        return result
+
+    def acquire_lock(
+        self,
+        key: str,
+        body: Optional[AcquireLockRequest] = None,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> Lock:
+        """Acquire or refresh lock.
+
+        Acquire the advisory lock on a key, waiting up to wait_seconds for it to
+        be released, and return it with the token needed to refresh or release it.
+        An expired lock is taken over. With an X-Lock-Token header the held lock
+        is refreshed to expire ttl_seconds from now instead. Locks are advisory
+        and do not block writes to the key.
+
+        Args:
+            key: Key to lock
+        """
+        _, data = self._request("PUT", f"/api/v1/locks/{_encode_path(key)}", None, None if body is None else json.dumps(body).encode(), "application/json", headers)
+        result: Lock = json.loads(data)
+        return result
+
+    def release_lock(
+        self,
+        key: str,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> None:
+        """Release lock.
+
+        Args:
+            key: Locked key
+        """
+        self._request("DELETE", f"/api/v1/locks/{_encode_path(key)}", None, None, None, headers)
+
+    def list_aliases(
+        self,
+        *,
+        prefix: Optional[str] = None,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> AliasList:
+        """List aliases.
+
+        Args:
+            prefix: Only list aliases whose names start with this prefix
+        """
+        _, data = self._request("GET", "/api/v1/aliases", {"prefix": prefix}, None, None, headers)
+        result: AliasList = json.loads(data)
+        return result
+
+    def get_alias(
+        self,
+        key: str,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> Alias:
+        """Resolve alias.
+
+        Get an alias with the key of the object it points at. Reading the alias
+        name as an object reads its target directly.
+
+        Args:
+            key: Alias name
+        """
+        _, data = self._request("GET", f"/api/v1/aliases/{_encode_path(key)}", None, None, None, headers)
+        result: Alias = json.loads(data)
+        return result
+
+    def set_alias(
+        self,
+        key: str,
+        body: SetAliasRequest,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> Alias:
+        """Set alias.
+
+        Point an alias at an existing object, creating the alias or moving it.
+        Reads of the alias name that find no object read the target instead, so a
+        well-known name can follow new releases without copying them. The target
+        must be an object, not another alias, and the caller must be allowed to
+        read it.
+
+        Args:
+            key: Alias name
+        """
+        _, data = self._request("PUT", f"/api/v1/aliases/{_encode_path(key)}", None, None if body is None else json.dumps(body).encode(), "application/json", headers)
+        result: Alias = json.loads(data)
+        return result
+
+    def delete_alias(
+        self,
+        key: str,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> None:
+        """Delete alias.
+
+        Remove an alias. Its target is not changed.
+
+        Args:
+            key: Alias name
+        """
+        self._request("DELETE", f"/api/v1/aliases/{_encode_path(key)}", None, None, None, headers)
+
+    def list_quotas(
+        self,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> QuotaList:
+        """List quotas.
+
+        List the prefixes with a quota on the default backend.
+        """
+        _, data = self._request("GET", "/api/v1/quotas", None, None, None, headers)
+        result: QuotaList = json.loads(data)
+        return result
+
+    def get_quota(
+        self,
+        prefix: str,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> Quota:
+        """Get quota.
+
+        Args:
+            prefix: Key prefix; empty for every key
+        """
+        _, data = self._request("GET", f"/api/v1/quotas/{_encode_path(prefix)}", None, None, None, headers)
+        result: Quota = json.loads(data)
+        return result
+
+    def set_quota(
+        self,
+        prefix: str,
+        body: SetQuotaRequest,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> Quota:
+        """Set quota.
+
+        Limit the bytes and objects stored under a prefix. Writes that would
+        exceed the quota fail with 429. A prefix without an overlay of its own
+        gets one with the settings of the overlay that applied to it, so its
+        objects are still compressed and encrypted the same way. Quotas set
+        through the API last until the server restarts and reads its --overlays
+        file again.
+
+        Args:
+            prefix: Key prefix; empty for every key
+        """
+        _, data = self._request("PUT", f"/api/v1/quotas/{_encode_path(prefix)}", None, None if body is None else json.dumps(body).encode(), "application/json", headers)
+        result: Quota = json.loads(data)
+        return result
+
+    def remove_quota(
+        self,
+        prefix: str,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> None:
+        """Remove quota.
+
+        Remove the quota of a prefix. Its overlay is kept.
+
+        Args:
+            prefix: Key prefix; empty for every key
+        """
+        self._request("DELETE", f"/api/v1/quotas/{_encode_path(prefix)}", None, None, None, headers)
+
+    def list_webhooks(
+        self,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> WebhookList:
+        """List webhooks.
+
+        List the event notification rules of the default backend with their sink
+        settings, which may carry credentials.
+        """
+        _, data = self._request("GET", "/api/v1/webhooks", None, None, None, headers)
+        result: WebhookList = json.loads(data)
+        return result
+
+    def get_webhook(
+        self,
+        id: str,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> Webhook:
+        """Get webhook.
+
+        Args:
+            id: Webhook ID
+        """
+        _, data = self._request("GET", f"/api/v1/webhooks/{_encode_path(id)}", None, None, None, headers)
+        result: Webhook = json.loads(data)
+        return result
+
+    def set_webhook(
+        self,
+        id: str,
+        body: SetWebhookRequest,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> Webhook:
+        """Set webhook.
+
+        Create or replace an event notification rule, which sends the events it
+        matches to a sink: an incoming webhook (slack, teams) or a message service
+        (sns, sqs, eventbridge, pubsub, kafka, smtp). Notifications already queued
+        go to the sink they were queued for. Rules set through the API last until
+        the server restarts and reads its --notifications file again.
+
+        Args:
+            id: Webhook ID
+        """
+        _, data = self._request("PUT", f"/api/v1/webhooks/{_encode_path(id)}", None, None if body is None else json.dumps(body).encode(), "application/json", headers)
+        result: Webhook = json.loads(data)
+        return result
+
+    def delete_webhook(
+        self,
+        id: str,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> None:
+        """Delete webhook.
+
+        Remove an event notification rule. Notifications already queued for it are
+        still delivered.
+
+        Args:
+            id: Webhook ID
+        """
+        self._request("DELETE", f"/api/v1/webhooks/{_encode_path(id)}", None, None, None, headers)
+
+    def list_access_keys(
+        self,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> AccessKeyList:
+        """List access keys.
+
+        List the SigV4 access keys issued by the server. Secrets are not returned.
+        """
+        _, data = self._request("GET", "/api/v1/access-keys", None, None, None, headers)
+        result: AccessKeyList = json.loads(data)
+        return result
+
+    def create_access_key(
+        self,
+        body: CreateAccessKeyRequest,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> AccessKey:
+        """Create access key.
+
+        Issue a SigV4 access key that authenticates as the given principal and
+        roles. The secret is only returned in this response.
+        """
+        _, data = self._request("POST", "/api/v1/access-keys", None, None if body is None else json.dumps(body).encode(), "application/json", headers)
+        result: AccessKey = json.loads(data)
+        return result
+
+    def get_access_key(
+        self,
+        id: str,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> AccessKey:
+        """Get access key.
+
+        Get an issued access key. The secret is not returned.
+
+        Args:
+            id: Access key ID
+        """
+        _, data = self._request("GET", f"/api/v1/access-keys/{_encode_path(id)}", None, None, None, headers)
+        result: AccessKey = json.loads(data)
+        return result
+
+    def delete_access_key(
+        self,
+        id: str,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> None:
+        """Delete access key.
+
+        Revoke an access key; requests signed with it no longer authenticate.
+
+        Args:
+            id: Access key ID
+        """
+        self._request("DELETE", f"/api/v1/access-keys/{_encode_path(id)}", None, None, None, headers)
+
+    def list_shares(
+        self,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> ShareList:
+        """List share links.
+
+        List every share link with its download accounting, including expired and
+        exhausted links. Link tokens are not returned.
+        """
+        _, data = self._request("GET", "/api/v1/shares", None, None, None, headers)
+        result: ShareList = json.loads(data)
+        return result
+
+    def create_share(
+        self,
+        key: str,
+        body: Optional[CreateShareRequest] = None,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> Share:
+        """Create share link.
+
+        Create a public download link to an object and return it with its token
+        and URL, which are only returned here. Creating a link needs read access
+        to the key. Objects encrypted with a customer-provided key cannot be
+        shared.
+
+        Args:
+            key: Key of the object to share
+        """
+        _, data = self._request("POST", f"/api/v1/shares/objects/{_encode_path(key)}", None, None if body is None else json.dumps(body).encode(), "application/json", headers)
+        result: Share = json.loads(data)
+        return result
+
+    def get_share(
+        self,
+        id: str,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> Share:
+        """Get share link.
+
+        Get a share link with its download accounting. The link token is not
+        returned.
+
+        Args:
+            id: Link ID
+        """
+        _, data = self._request("GET", f"/api/v1/shares/{_encode_path(id)}", None, None, None, headers)
+        result: Share = json.loads(data)
+        return result
+
+    def revoke_share(
+        self,
+        id: str,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> None:
+        """Revoke share link.
+
+        Revoke a share link. Downloads already in progress are not interrupted.
+
+        Args:
+            id: Link ID
+        """
+        self._request("DELETE", f"/api/v1/shares/{_encode_path(id)}", None, None, None, headers)
+
+    def create_upload(
+        self,
+        key: str,
+        body: Optional[CreateUploadRequest] = None,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> UploadSession:
+        """Create upload session.
+
+        Start an upload session for an object. Numbered chunks are then uploaded
+        to the session, in any order and in parallel, and the session is completed
+        to store the object. Sessions are kept on the backend, so they survive
+        server restarts, and expire after their TTL. Creating a session needs
+        write access to the key; so do the operations on it.
+
+        Args:
+            key: Key of the object to upload
+        """
+        _, data = self._request("POST", f"/api/v1/uploads/objects/{_encode_path(key)}", None, None if body is None else json.dumps(body).encode(), "application/json", headers)
+        result: UploadSession = json.loads(data)
+        return result
+
+    def get_upload(
+        self,
+        id: str,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> UploadSession:
+        """Get upload session.
+
+        Get an upload session with the chunks received so far, so an interrupted
+        upload can resume with the missing ones.
+
+        Args:
+            id: Upload session ID
+        """
+        _, data = self._request("GET", f"/api/v1/uploads/{_encode_path(id)}", None, None, None, headers)
+        result: UploadSession = json.loads(data)
+        return result
+
+    def abort_upload(
+        self,
+        id: str,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> None:
+        """Abort upload session.
+
+        Abort an upload session and delete its chunks.
+
+        Args:
+            id: Upload session ID
+        """
+        self._request("DELETE", f"/api/v1/uploads/{_encode_path(id)}", None, None, None, headers)
+
+    def upload_chunk(
+        self,
+        id: str,
+        number: str,
+        body: bytes,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> UploadChunk:
+        """Upload chunk.
+
+        Upload a numbered chunk of an upload session, replacing any earlier upload
+        of it. The chunk is only kept if it matches the SHA-256 in the
+        X-Chunk-SHA256 header. Chunks are at most 256 MiB, and at most the
+        server's maximum request size.
+
+        Args:
+            id: Upload session ID
+            number: Chunk number, from 1 to 10000
+        """
+        _, data = self._request("PUT", f"/api/v1/uploads/{_encode_path(id)}/chunks/{_encode_path(number)}", None, body, "application/octet-stream", headers)
+        result: UploadChunk = json.loads(data)
+        return result
+
+    def complete_upload(
+        self,
+        id: str,
+        body: Optional[CompleteUploadRequest] = None,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> SuccessResponse:
+        """Complete upload session.
+
+        Assemble the chunks of an upload session, numbered from 1 without gaps,
+        into its object and end the session. Each chunk is checked against its
+        SHA-256 again as it is read, and the whole object against the optional
+        sha256 of the body.
+
+        Args:
+            id: Upload session ID
+        """
+        _, data = self._request("POST", f"/api/v1/uploads/{_encode_path(id)}/complete", None, None if body is None else json.dumps(body).encode(), "application/json", headers)
+        result: SuccessResponse = json.loads(data)
+        return result
+
+    def list_manifests(
+        self,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> ManifestList:
+        """List manifests."""
+        _, data = self._request("GET", "/api/v1/manifests", None, None, None, headers)
+        result: ManifestList = json.loads(data)
+        return result
+
+    def create_manifest(
+        self,
+        body: CreateManifestRequest,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> Manifest:
+        """Create manifest.
+
+        Create the draft of a new manifest. Each key is pinned to the ETag and
+        size it has now; publishing or archiving fails if a member changes
+        afterwards.
+        """
+        _, data = self._request("POST", "/api/v1/manifests", None, None if body is None else json.dumps(body).encode(), "application/json", headers)
+        result: Manifest = json.loads(data)
+        return result
+
+    def get_manifest(
+        self,
+        name: str,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> Manifest:
+        """Get current manifest version.
+
+        Args:
+            name: Manifest name
+        """
+        _, data = self._request("GET", f"/api/v1/manifests/{_encode_path(name)}", None, None, None, headers)
+        result: Manifest = json.loads(data)
+        return result
+
+    def get_manifest_draft(
+        self,
+        name: str,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> Manifest:
+        """Get manifest draft.
+
+        Args:
+            name: Manifest name
+        """
+        _, data = self._request("GET", f"/api/v1/manifests/{_encode_path(name)}/draft", None, None, None, headers)
+        result: Manifest = json.loads(data)
+        return result
+
+    def update_manifest_members(
+        self,
+        name: str,
+        body: UpdateManifestMembersRequest,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> Manifest:
+        """Update manifest members.
+
+        Add keys to and then remove keys from a manifest's draft. Added keys are
+        pinned to their current ETag and size; keys already in the draft are
+        re-pinned.
+
+        Args:
+            name: Manifest name
+        """
+        _, data = self._request("POST", f"/api/v1/manifests/{_encode_path(name)}/members", None, None if body is None else json.dumps(body).encode(), "application/json", headers)
+        result: Manifest = json.loads(data)
+        return result
+
+    def publish_manifest(
+        self,
+        name: str,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> Manifest:
+        """Publish manifest.
+
+        Publish the draft as the manifest's next version and make it current in a
+        single write, so readers see the old dataset or the new one and never a
+        mix. Fails if any member changed since it was added, or if another publish
+        won the race.
+
+        Args:
+            name: Manifest name
+        """
+        _, data = self._request("POST", f"/api/v1/manifests/{_encode_path(name)}/publish", None, None, None, headers)
+        result: Manifest = json.loads(data)
+        return result
+
+    def list_manifest_versions(
+        self,
+        name: str,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> ManifestVersionList:
+        """List manifest versions.
+
+        Args:
+            name: Manifest name
+        """
+        _, data = self._request("GET", f"/api/v1/manifests/{_encode_path(name)}/versions", None, None, None, headers)
+        result: ManifestVersionList = json.loads(data)
+        return result
+
+    def get_manifest_version(
+        self,
+        name: str,
+        version: str,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> Manifest:
+        """Get manifest version.
+
+        Args:
+            name: Manifest name
+            version: Published version number
+        """
+        _, data = self._request("GET", f"/api/v1/manifests/{_encode_path(name)}/versions/{_encode_path(version)}", None, None, None, headers)
+        result: Manifest = json.loads(data)
+        return result
+
+    def get_manifest_tar(
+        self,
+        name: str,
+        *,
+        version: Optional[str] = None,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> bytes:
+        """Download manifest as tar.
+
+        Stream the members of a manifest version as a tar archive, in key order.
+        Every member is checked against its pinned ETag before the archive starts;
+        a member changed while streaming truncates it.
+
+        Args:
+            name: Manifest name
+            version: Published version number, or "draft"; defaults to the current version
+        """
+        _, data = self._request("GET", f"/api/v1/manifests/{_encode_path(name)}/tar", {"version": version}, None, None, headers)
+        return data
+
+    def create_token(
+        self,
+        body: TokenRequest,
+        *,
+        headers: Optional[Dict[str, str]] = None,
+    ) -> TokenResponse:
+        """Mint scoped token.
+
+        Exchange the caller's credentials for a short-lived token limited to a key
+        prefix and a set of actions (read, write, delete, list), to hand to an
+        untrusted worker or browser. The caller must hold every requested action.
+        Available when the server is configured with a token service.
+        """
+        _, data = self._request("POST", "/api/v1/tokens", None, None if body is None else json.dumps(body).encode(), "application/json", headers)
+        result: TokenResponse = json.loads(data)
+        return result
@@ -0,0 +1,1803 @@
+// Code generated by go run ./api/openapi/clientgen. DO NOT EDIT.
+// Source: api/openapi/objstore.yaml (Go ObjectStore REST API 0.1.0-beta)
+
+/** Body of every error response, shared by the REST and QUIC servers. Branch on code rather than on message, whose wording may change. */
+export interface ErrorResponse {
+  /** HTTP status text. */
+  error: string;
+  /** Machine-readable error code. */
+  code: 'INTERNAL' | 'NOT_FOUND' | 'ALREADY_EXISTS' | 'INVALID_ARGUMENT' | 'PERMISSION_DENIED' | 'UNAUTHENTICATED' | 'RESOURCE_EXHAUSTED' | 'UNAVAILABLE' | 'CANCELED' | 'DEADLINE_EXCEEDED' | 'PRECONDITION_FAILED';
+  /** HTTP status code. */
+  status: number;
+  /** Detailed error message. */
+  message: string;
+  /** X-Request-ID of the failed request. */
+  request_id?: string;
+  /** Whether retrying the request unchanged may succeed. */
+  retryable: boolean;
+  /** Additional error-specific fields. */
+  details?: Record<string, unknown>;
+}
+
+export interface SuccessResponse {
+  /** Success message. */
+  message: string;
+  /** Optional response data. */
+  data?: Record<string, unknown>;
+}
+
+export interface PutObjectResponse {
+  message: string;
+  data: PutResult;
+}
+
+/** The object written by an upload. */
+export interface PutResult {
+  key: string;
+  /** ETag of the object; empty when the backend reports none. */
+  etag: string;
+  /** Version or generation of the object, on backends that keep them (an S3 or Azure version ID, a GCS generation). */
+  version?: string;
+  /** Size of the object in bytes. */
+  size: number;
+  /** Modification time of the object, when known. */
+  last_modified?: string;
+}
+
+export interface RenameResult {
+  /** Objects moved, or that would be moved in a dry run. */
+  moved: number;
+  /** Objects left in place because a different object exists at their new key. */
+  skipped: number;
+  /** Objects whose copy or delete failed. */
+  failed: number;
+  /** True when nothing was changed. */
+  dry_run?: boolean;
+}
+
+export interface HealthResponse {
+  /** Health status. */
+  status: string;
+  /** API version. */
+  version?: string;
+}
+
+export interface Metadata {
+  /** MIME type of the object. */
+  content_type?: string;
+  /** Content encoding. */
+  content_encoding?: string;
+  /** Size in bytes. */
+  size?: number;
+  /** Last modification timestamp. */
+  last_modified?: string;
+  /** Entity tag for versioning/caching. */
+  etag?: string;
+  /** Custom metadata key-value pairs. */
+  custom?: Record<string, string>;
+}
+
+/** Changes to apply to existing metadata; empty fields are left unchanged. */
+export interface MetadataPatch {
+  content_type?: string;
+  content_encoding?: string;
+  storage_class?: string;
+  /** Custom metadata fields to add or replace. */
+  set?: Record<string, string>;
+  /** Custom metadata fields to delete. */
+  remove?: string[];
+}
+
+export interface ObjectResponse {
+  /** Object key/path. */
+  key: string;
+  /** Size in bytes. */
+  size: number;
+  /** Last modification timestamp. */
+  modified?: string;
+  /** Entity tag. */
+  etag?: string;
+  /** MIME type of the object. */
+  content_type?: string;
+  /** Custom metadata key-value pairs. */
+  metadata?: Record<string, string>;
+}
+
+export interface ListObjectsResponse {
+  /** List of objects. */
+  objects: ObjectResponse[];
+  /** Common prefixes (when using delimiter). */
+  common_prefixes?: string[];
+  /** Token for fetching the next page. */
+  next_token?: string;
+  /** Whether more results are available. */
+  truncated: boolean;
+}
+
+export interface SearchResponse {
+  /** The query that was run. */
+  query: string;
+  /** Matching objects. */
+  objects: ObjectResponse[];
+  /** Number of objects returned. */
+  count: number;
+}
+
+export interface Folder {
+  /** Full prefix of the folder, ending in the delimiter. */
+  prefix: string;
+  /** Objects under the folder at any depth (0 without totals). */
+  objects: number;
+  /** Total size of the objects under the folder (0 without totals). */
+  bytes: number;
+}
+
+export interface BrowseResponse {
+  /** The browsed prefix. */
+  prefix: string;
+  folders: Folder[];
+  /** Objects directly under the prefix. */
+  objects: ObjectResponse[];
+  /** Objects directly under the prefix, plus those in folders with totals. */
+  total_objects: number;
+  /** Total size of the objects counted in total_objects. */
+  total_bytes: number;
+}
+
+export interface Change {
+  /** Position of the change in the feed. */
+  sequence: number;
+  op: 'put' | 'update_metadata' | 'delete';
+  key: string;
+  /** ETag of the object after the change (omitted for deletes). */
+  etag?: string;
+  /** Size of the object after the change. */
+  size?: number;
+  timestamp: string;
+}
+
+export interface ChangeList {
+  changes: Change[];
+  /** Sequence to pass as since for the next page. */
+  next: number;
+  /** Sequence of the most recent change. */
+  latest: number;
+  /** Whether more changes after next are already available. */
+  more: boolean;
+}
+
+export interface StorageSnapshot {
+  time?: string;
+  objects?: number;
+  bytes?: number;
+  top_prefixes?: (Record<string, unknown>)[];
+}
+
+export interface StorageStats {
+  backends?: (Record<string, unknown>)[];
+}
+
+export interface AgeBucket {
+  label?: string;
+  objects?: number;
+  bytes?: number;
+}
+
+export interface AgeDistribution {
+  prefix?: string;
+  objects?: number;
+  bytes?: number;
+  /** Objects by time since they were last modified. */
+  age?: AgeBucket[];
+  /** Objects by time since they were last read, ending with a "never" bucket (only when access tracking is enabled). */
+  access?: AgeBucket[];
+}
+
+export interface AccessReport {
+  generated_at?: string;
+  /** When read tracking started (only when access tracking is enabled). */
+  tracking_since?: string;
+  total?: AgeDistribution;
+  prefixes?: AgeDistribution[];
+}
+
+export interface Cost {
+  storage?: number;
+  requests?: number;
+  egress?: number;
+  total?: number;
+}
+
+export interface CostLine {
+  backend?: string;
+  /** Configured group name, or the top-level prefix of keys in no group. */
+  group?: string;
+  /** Request counts by class (write, read, list, delete). */
+  requests?: Record<string, number>;
+  egress_bytes?: number;
+  /** Bytes stored now (current month only). */
+  stored_bytes?: number;
+  /** Storage used so far in the month, in GiB-months. */
+  gb_months?: number;
+  cost?: Cost;
+  projected?: Cost;
+}
+
+export interface DiffChange {
+  op?: 'add' | 'remove' | 'replace';
+  /** JSON Pointer of the value; empty for the whole document. */
+  path?: string;
+  /** Value in the first document. */
+  old?: unknown;
+  /** Value in the second document. */
+  new?: unknown;
+}
+
+export interface DiffResult {
+  mode?: 'text' | 'json' | 'binary';
+  identical?: boolean;
+  size_a?: number;
+  size_b?: number;
+  /** Unified diff of a text comparison. */
+  unified?: string;
+  /** Differences found by a JSON comparison. */
+  changes?: DiffChange[];
+  /** Offset of the first differing byte of a binary comparison. */
+  first_difference?: number;
+  /** More changes were found than are reported. */
+  truncated?: boolean;
+}
+
+export interface CostReport {
+  month?: string;
+  currency?: string;
+  generated_at?: string;
+  lines?: CostLine[];
+  groups?: (Record<string, unknown>)[];
+  total?: Cost;
+  projected?: Cost;
+}
+
+export interface JobRun {
+  job?: string;
+  /** Node that ran the job. */
+  node?: string;
+  started_at?: string;
+  finished_at?: string;
+  outcome?: 'succeeded' | 'failed' | 'skipped';
+  message?: string;
+  error?: string;
+}
+
+export interface JobStatus {
+  name?: string;
+  interval_seconds?: number;
+  running?: boolean;
+  last_run?: JobRun;
+  next_run?: string;
+  /** Recent runs, newest first (only returned for a single job). */
+  history?: JobRun[];
+}
+
+export interface JobList {
+  jobs?: JobStatus[];
+}
+
+export interface Task {
+  id?: string;
+  /** Operation the task runs. */
+  type?: string;
+  /** Operation-specific arguments. */
+  payload?: Record<string, unknown>;
+  state?: 'pending' | 'running' | 'succeeded' | 'dead';
+  attempts?: number;
+  max_attempts?: number;
+  /** When a pending task is next due. */
+  next_attempt?: string;
+  last_error?: string;
+  /** Node that last attempted the task. */
+  node?: string;
+  created_at?: string;
+  updated_at?: string;
+}
+
+export interface TaskList {
+  tasks?: Task[];
+}
+
+export interface ArchiveRequest {
+  /** Object key to archive. */
+  key: string;
+  /** Archive destination type (e.g., "glacier", "s3"). */
+  destination_type: string;
+  /** Destination-specific settings. */
+  destination_settings?: Record<string, string>;
+  /** Queue the archive as a task and return 202 with it instead of waiting; the server must be started with --tasks
+. */
+  async?: boolean;
+}
+
+export interface PolicyRequest {
+  /** Unique policy identifier. */
+  id: string;
+  /** Object key prefix the policy applies to (empty = all objects). */
+  prefix?: string;
+  /** Retention period in seconds. */
+  retention_seconds: number;
+  /** Action to perform when retention expires. */
+  action: 'delete' | 'archive';
+  /** Archive destination type (required when action=archive). */
+  destination_type?: string;
+  /** Destination-specific settings (used when action=archive). */
+  destination_settings?: Record<string, string>;
+}
+
+export interface PolicyResponse {
+  /** Policy identifier. */
+  id?: string;
+  /** Object key prefix. */
+  prefix?: string;
+  /** Retention period in seconds. */
+  retention_seconds?: number;
+  /** Action performed when retention expires. */
+  action?: string;
+}
+
+export interface PolicyListResponse {
+  policies?: PolicyResponse[];
+  /** Total number of policies. */
+  count?: number;
+}
+
+export interface ApplyPoliciesResponse {
+  message?: string;
+  /** Number of policies evaluated. */
+  policies_count?: number;
+  /** Number of objects affected. */
+  objects_processed?: number;
+}
+
+export interface ReplicationPolicyRequest {
+  /** Unique replication policy identifier. */
+  id: string;
+  /** Source backend name (empty = default backend). */
+  source_backend?: string;
+  /** Destination backend name. */
+  destination_backend: string;
+  /** Destination backend settings. */
+  destination_settings?: Record<string, string>;
+  /** Whether the policy is active. */
+  enabled?: boolean;
+  /** Write every source version to its own versioned key on the destination and never propagate deletes. */
+  write_once?: boolean;
+}
+
+export interface ReplicationPolicyResponse {
+  /** Policy identifier. */
+  id?: string;
+  source_backend?: string;
+  destination_backend?: string;
+  enabled?: boolean;
+  write_once?: boolean;
+}
+
+export interface ReplicationPolicyListResponse {
+  policies?: ReplicationPolicyResponse[];
+  count?: number;
+}
+
+export interface TriggerReplicationRequest {
+  /** Replication policy ID to trigger. */
+  policy_id: string;
+  /** Queue the sync as a task and return 202 with it instead of waiting; the server must be started with --tasks
+. */
+  async?: boolean;
+}
+
+export interface ReplicationStatusResponse {
+  policy_id?: string;
+  /** Current replication status. */
+  status?: string;
+  /** Number of objects replicated in the last run. */
+  objects_replicated?: number;
+  /** Timestamp of the last replication run. */
+  last_run?: string;
+  /** Error message from the last run, if any. */
+  error?: string;
+}
+
+export interface TokenRequest {
+  /** Key prefix the token is limited to; empty allows every key. */
+  prefix?: string;
+  /** Permitted actions. */
+  actions: ('read' | 'write' | 'delete' | 'list')[];
+  /** Token lifetime in seconds (default 900, maximum set by the server). */
+  ttl_seconds?: number;
+}
+
+export interface TokenResponse {
+  /** Bearer token to send in the Authorization header. */
+  token: string;
+  expires_at: string;
+  prefix?: string;
+  actions: string[];
+}
+
+export interface DeletionRequest {
+  id: string;
+  key: string;
+  status: 'pending' | 'approved' | 'rejected';
+  /** Principal that attempted the delete. */
+  requested_by?: string;
+  requested_at: string;
+  /** Principal that approved or rejected the request. */
+  decided_by?: string;
+  decided_at?: string;
+  /** Rejection reason. */
+  reason?: string;
+}
+
+export interface DeletionRequestList {
+  requests: DeletionRequest[];
+  count: number;
+}
+
+export interface RejectDeletionRequest {
+  reason?: string;
+}
+
+export interface LegalHoldRequest {
+  reason?: string;
+}
+
+export interface LegalHold {
+  key: string;
+  placed_by?: string;
+  placed_at: string;
+  reason?: string;
+}
+
+export interface LegalHoldList {
+  holds: LegalHold[];
+  count: number;
+}
+
+export interface AcquireLockRequest {
+  ttl_seconds?: number;
+  wait_seconds?: number;
+  /** Recorded owner when the request is not authenticated. */
+  owner?: string;
+}
+
+export interface Lock {
+  key: string;
+  /** Only returned to the holder. */
+  token?: string;
+  owner?: string;
+  acquired_at: string;
+  expires_at: string;
+}
+
+export interface LockList {
+  locks: Lock[];
+  count: number;
+}
+
+/** Zero or missing limits are unlimited. */
+export interface SetQuotaRequest {
+  max_bytes?: number;
+  max_objects?: number;
+}
+
+export interface Quota {
+  prefix: string;
+  max_bytes?: number;
+  max_objects?: number;
+}
+
+export interface QuotaList {
+  quotas: Quota[];
+  count: number;
+}
+
+export interface WebhookSink {
+  type: string;
+  settings?: Record<string, string>;
+}
+
+export interface SetWebhookRequest {
+  /** Event name patterns; empty matches every event. */
+  events?: string[];
+  prefix?: string;
+  suffix?: string;
+  sink: WebhookSink;
+}
+
+export interface Webhook {
+  id: string;
+  events?: string[];
+  prefix?: string;
+  suffix?: string;
+  sink: WebhookSink;
+}
+
+export interface WebhookList {
+  webhooks: Webhook[];
+  count: number;
+}
+
+export interface CreateAccessKeyRequest {
+  /** Principal the key authenticates as. */
+  principal_id: string;
+  roles?: string[];
+  description?: string;
+}
+
+export interface AccessKey {
+  access_key_id: string;
+  /** Only returned when the key is created. */
+  secret_access_key?: string;
+  principal_id: string;
+  roles?: string[];
+  description?: string;
+  created_at: string;
+}
+
+export interface AccessKeyList {
+  access_keys: AccessKey[];
+  count: number;
+}
+
+export interface SetAliasRequest {
+  target: string;
+}
+
+export interface Alias {
+  name: string;
+  target: string;
+  updated_at: string;
+  updated_by?: string;
+}
+
+export interface AliasList {
+  aliases: Alias[];
+  count: number;
+}
+
+export interface CreateShareRequest {
+  ttl_seconds?: number;
+  /** Downloads allowed through the link; 0 for no limit. */
+  max_downloads?: number;
+  /** Password downloads must present. */
+  password?: string;
+  /** Bandwidth cap for downloads through the link; 0 for none. */
+  bytes_per_second?: number;
+}
+
+export interface Share {
+  id: string;
+  key: string;
+  /** Only returned when the link is created. */
+  token?: string;
+  /** Only returned when the link is created. */
+  url?: string;
+  status: 'active' | 'expired' | 'exhausted';
+  created_by?: string;
+  created_at: string;
+  expires_at: string;
+  max_downloads?: number;
+  bytes_per_second?: number;
+  password_protected: boolean;
+  downloads: number;
+  bytes_served: number;
+  last_download?: string;
+}
+
+export interface ShareList {
+  shares: Share[];
+  count: number;
+}
+
+export interface CreateUploadRequest {
+  /** How long the session can be continued. */
+  ttl_seconds?: number;
+  /** Size the completed object must have; 0 if unknown. */
+  size?: number;
+  content_type?: string;
+  content_encoding?: string;
+  /** Custom metadata stored with the object. */
+  metadata?: Record<string, string>;
+}
+
+export interface CompleteUploadRequest {
+  /** Hex SHA-256 digest the whole object must have. */
+  sha256?: string;
+}
+
+export interface UploadChunk {
+  number: number;
+  size: number;
+  sha256: string;
+}
+
+export interface UploadSession {
+  id: string;
+  key: string;
+  created_by?: string;
+  created_at: string;
+  expires_at: string;
+  size?: number;
+  content_type?: string;
+  content_encoding?: string;
+  metadata?: Record<string, string>;
+  /** Chunks received so far, in order. */
+  chunks: UploadChunk[];
+  /** Total size of the chunks received. */
+  received: number;
+}
+
+export interface CreateManifestRequest {
+  /** 1 to 128 letters, digits, '.', '_' or '-'. */
+  name: string;
+  keys?: string[];
+}
+
+export interface UpdateManifestMembersRequest {
+  add?: string[];
+  remove?: string[];
+}
+
+export interface ManifestMember {
+  key: string;
+  /** ETag the object had when added. */
+  etag?: string;
+  size: number;
+}
+
+export interface Manifest {
+  name: string;
+  /** Published version number; 0 for the draft. */
+  version: number;
+  members: ManifestMember[];
+  /** Total size of the members in bytes. */
+  size: number;
+  updated_at: string;
+  published_by?: string;
+}
+
+export interface ManifestList {
+  manifests: string[];
+  count: number;
+}
+
+export interface ManifestVersionList {
+  name: string;
+  versions: number[];
+  count: number;
+}
+
+export interface SelectObjectContentRequest {
+  /** SELECT statement over S3Object. */
+  expression: string;
+  input: SelectInput;
+  output?: SelectOutput;
+}
+
+export interface SelectInput {
+  format: 'csv' | 'json' | 'parquet';
+  /** Compression of CSV and JSON objects. */
+  compression?: 'gzip' | 'zlib' | 'deflate' | 'bzip2' | 'zstd';
+  csv?: SelectCSVInput;
+}
+
+export interface SelectCSVInput {
+  /** Whether the first line names the columns (use), is skipped (ignore) or is a record (none). */
+  file_header_info?: 'none' | 'ignore' | 'use';
+  field_delimiter?: string;
+  /** Skip lines starting with this character. */
+  comments?: string;
+}
+
+export interface SelectOutput {
+  /** Defaults to csv for CSV input and json otherwise. */
+  format?: 'csv' | 'json';
+  field_delimiter?: string;
+}
+
+export class ApiError extends Error {
+  constructor(
+    readonly status: number,
+    readonly body: unknown,
+  ) {
+    super(`HTTP ${status}: ${typeof body === 'string' ? body : JSON.stringify(body)}`);
+    this.name = 'ApiError';
+  }
+}
+
+export interface ClientOptions {
+  /** Bearer token sent in the Authorization header. */
+  token?: string;
+  /** Headers sent with every request. */
+  headers?: Record<string, string>;
+  /** fetch implementation; defaults to globalThis.fetch. */
+  fetch?: typeof fetch;
+}
+
+export interface RequestOptions {
+  /** Headers sent with this request, e.g. Content-Type for uploads. */
+  headers?: Record<string, string>;
+  signal?: AbortSignal;
+}
+
+function encodePath(value: string): string {
+  return value.split('/').map(encodeURIComponent).join('/');
+}
+
+export class ObjstoreClient {
+  private readonly baseUrl: string;
+
+  /** @param baseUrl server root, e.g. http://localhost:8080 */
+  constructor(
+    baseUrl: string,
+    private readonly options: ClientOptions = {},
+  ) {
+    this.baseUrl = baseUrl.replace(/\/+$/, '');
+  }
+
+  private async request(
+    method: string,
+    path: string,
+    query: Record<string, unknown> | undefined,
+    body: BodyInit | undefined,
+    contentType: string | undefined,
+    opts: RequestOptions | undefined,
+  ): Promise<Response> {
+    let url = this.baseUrl + path;
+    if (query) {
+      const params = new URLSearchParams();
+      for (const [name, value] of Object.entries(query)) {
+        if (value !== undefined && value !== null) params.set(name, String(value));
+      }
+      const qs = params.toString();
+      if (qs) url += '?' + qs;
+    }
+    const headers: Record<string, string> = { ...this.options.headers, ...opts?.headers };
+    if (this.options.token) headers['Authorization'] = `Bearer ${this.options.token}`;
+    if (contentType && !Object.keys(headers).some((h) => h.toLowerCase() === 'content-type')) {
+      headers['Content-Type'] = contentType;
+    }
+    const doFetch = this.options.fetch ?? globalThis.fetch;
+    const resp = await doFetch(url, { method, headers, body, signal: opts?.signal });
+    if (!resp.ok) {
+      const text = await resp.text();
+      let parsed: unknown = text;
+      try {
+        parsed = JSON.parse(text);
+      } catch {
+        // not JSON; keep the raw text
+      }
+      throw new ApiError(resp.status, parsed);
+    }
+    return resp;
+  }
+
+  /**
+   * Health check.
+   *
+   * Check if the server is healthy and responsive
+   */
+  async healthCheck(opts?: RequestOptions): Promise<HealthResponse> {
+    return (await (await this.request('GET', `/health`, undefined, undefined, undefined, opts)).json()) as HealthResponse;
+  }
+
+  /**
+   * Prometheus metrics.
+   *
+   * Prometheus exposition endpoint. Served at the server root (not under
+   * /api/v1). Requires authentication and authorization by default; set the
+   * server's MetricsPublic configuration flag (--metrics-public) to expose it
+   * anonymously for Prometheus scrapers.
+   */
+  async metrics(opts?: RequestOptions): Promise<string> {
+    return (await this.request('GET', `/metrics`, undefined, undefined, undefined, opts)).text();
+  }
+
+  /**
+   * Download through a share link.
+   *
+   * Download the object of a share link. No credentials are needed beyond the
+   * link token; a password-protected link also needs its password in the
+   * X-Share-Password header or as the password of HTTP basic authentication.
+   * Each download counts against the link's download limit when it starts, and
+   * is streamed no faster than the link's bandwidth cap.
+   *
+   * @param token Link token
+   */
+  async downloadShare(token: string, opts?: RequestOptions): Promise<Response> {
+    return this.request('GET', `/share/${encodePath(token)}`, undefined, undefined, undefined, opts);
+  }
+
+  /**
+   * Upload with a browser form (POST policy).
+   *
+   * Upload an object from an HTML form signed with an S3-style POST policy, so
+   * browsers can upload directly without holding credentials. No other
+   * authentication is needed: the policy, signed with a SigV4 key, is the
+   * credential. Every form field must meet a condition of the policy, the file
+   * size must be within its content-length-range, and the signer must be
+   * allowed to write the key. Field names are case-insensitive and the file
+   * comes last. Only served when the server is configured with a POST policy
+   * authenticator.
+   *
+   * @param body form fields, with the file last as "file"
+   */
+  async uploadWithPostPolicy(body: FormData, opts?: RequestOptions): Promise<Headers> {
+    return (await this.request('POST', `/upload`, undefined, body, undefined, opts)).headers;
+  }
+
+  /**
+   * List objects.
+   *
+   * List objects with optional prefix filtering and pagination support
+   *
+   * @param query.prefix Filter objects by prefix
+   * @param query.limit Maximum number of results to return
+   * @param query.token Continuation token for pagination
+   * @param query.delimiter Delimiter for hierarchical listing (e.g., "/" for directory-like structure)
+   * @param query.sort Order the objects of the page by name, size or modification time. Pages are still taken in key order.
+
+   * @param query.order Sort direction
+   */
+  async listObjects(query: { prefix?: string; limit?: number; token?: string; delimiter?: string; sort?: 'name' | 'size' | 'mtime'; order?: 'asc' | 'desc' } = {}, opts?: RequestOptions): Promise<ListObjectsResponse> {
+    return (await (await this.request('GET', `/api/v1/objects`, { ...query }, undefined, undefined, opts)).json()) as ListObjectsResponse;
+  }
+
+  /**
+   * Update metadata under a prefix.
+   *
+   * Apply a metadata patch to every object whose key starts with prefix
+   * without rewriting any data. Objects that already match the patch are
+   * skipped. A failure on one object does not stop the others; the first error
+   * is returned once every object has been visited.
+   *
+   * @param query.prefix Key prefix of the objects to update
+   */
+  async updateMetadataBatch(body: MetadataPatch, query: { prefix?: string } = {}, opts?: RequestOptions): Promise<SuccessResponse> {
+    return (await (await this.request('PATCH', `/api/v1/objects`, { ...query }, body === undefined ? undefined : JSON.stringify(body), 'application/json', opts)).json()) as SuccessResponse;
+  }
+
+  /**
+   * Download object.
+   *
+   * Retrieve an object from the storage backend. With `preset`, the object's
+   * derived object for that transform preset (such as a thumbnail) is returned
+   * instead, generated and cached on first request. With `as_of`, the version
+   * of the object that was current at that time is returned from a versioned
+   * backend.
+   *
+   * @param key Object key/path
+   * @param query.as_of Return the version of the object that was current at this RFC 3339 time. Requires a versioned backend (S3, MinIO, GCS) or blob snapshots (Azure).
+
+   * @param query.preset Transform preset whose derived object to return
+   * @param query.decompress Decompress the object on the server before returning it
+   * @param query.range-lines Return only the given 1-based, inclusive line range (FIRST-LAST, FIRST- or -LAST), applied after decompression
+
+   * @param query.jq jq expression evaluated against each JSON value in the object; results are returned as newline-delimited JSON
+
+   */
+  async getObject(key: string, query: { as_of?: string; preset?: string; decompress?: 'gzip' | 'zlib' | 'deflate' | 'bzip2' | 'zstd'; range-lines?: string; jq?: string } = {}, opts?: RequestOptions): Promise<Response> {
+    return this.request('GET', `/api/v1/objects/${encodePath(key)}`, { ...query }, undefined, undefined, opts);
+  }
+
+  /**
+   * Upload object.
+   *
+   * Upload an object to the storage backend with optional metadata
+   *
+   * @param key Object key/path
+   * @param query.atomic Stage the upload under a hidden key, verify it against the data sent and any declared size or checksum-sha256 custom metadata, and only then promote it to the key, so readers never observe a partially written object. A failed atomic upload leaves any existing object untouched.
+   */
+  async putObject(key: string, body: BodyInit, query: { atomic?: boolean } = {}, opts?: RequestOptions): Promise<PutObjectResponse> {
+    return (await (await this.request('PUT', `/api/v1/objects/${encodePath(key)}`, { ...query }, body, 'application/octet-stream', opts)).json()) as PutObjectResponse;
+  }
+
+  /**
+   * Delete object.
+   *
+   * Remove an object from the storage backend
+   *
+   * @param key Object key/path
+   */
+  async deleteObject(key: string, opts?: RequestOptions): Promise<void> {
+    await this.request('DELETE', `/api/v1/objects/${encodePath(key)}`, undefined, undefined, undefined, opts);
+  }
+
+  /**
+   * Get object metadata via HEAD.
+   *
+   * Check if an object exists and retrieve its metadata headers without
+   * downloading its content. Returns the same metadata headers as GET.
+   *
+   * @param key Object key/path
+   */
+  async headObject(key: string, opts?: RequestOptions): Promise<Headers> {
+    return (await this.request('HEAD', `/api/v1/objects/${encodePath(key)}`, undefined, undefined, undefined, opts)).headers;
+  }
+
+  /**
+   * Search objects.
+   *
+   * Find objects whose key, content type or custom metadata match a query.
+   * Terms are space-separated and must all match. "field:value" matches a
+   * custom metadata field, "content-type:" the content type, "prefix:" the key
+   * prefix, and a trailing "*" matches by term prefix. Requires a server
+   * started with search enabled.
+   *
+   * @param q Search query
+   * @param query.limit Maximum number of results to return
+   */
+  async searchObjects(q: string, query: { limit?: number } = {}, opts?: RequestOptions): Promise<SearchResponse> {
+    return (await (await this.request('GET', `/api/v1/search`, { q: q, ...query }, undefined, undefined, opts)).json()) as SearchResponse;
+  }
+
+  /**
+   * Browse a virtual directory.
+   *
+   * Return the folders and objects directly under a prefix. Folders are the
+   * common prefixes one delimiter deeper. With totals, each folder carries the
+   * number and total size of the objects under it, which lists every object
+   * under the prefix.
+   *
+   * @param query.prefix Prefix to browse; usually empty or ending in the delimiter
+   * @param query.delimiter Separator of virtual directory levels
+   * @param query.totals Count the objects and bytes under each folder
+   */
+  async browse(query: { prefix?: string; delimiter?: string; totals?: boolean } = {}, opts?: RequestOptions): Promise<BrowseResponse> {
+    return (await (await this.request('GET', `/api/v1/browse`, { ...query }, undefined, undefined, opts)).json()) as BrowseResponse;
+  }
+
+  /**
+   * Read the change feed.
+   *
+   * Return the puts, metadata updates and deletes made after a sequence
+   * number, oldest first. Consumers store the returned next sequence and pass
+   * it as since on their next call. 410 means the changes after since are no
+   * longer retained and the consumer must resynchronize from a full listing.
+   * Requires a server started with the change feed enabled.
+   *
+   * @param query.since Return changes with a greater sequence (0 starts at the oldest retained change)
+   * @param query.limit Maximum number of changes to return
+   */
+  async getChanges(query: { since?: number; limit?: number } = {}, opts?: RequestOptions): Promise<ChangeList> {
+    return (await (await this.request('GET', `/api/v1/changes`, { ...query }, undefined, undefined, opts)).json()) as ChangeList;
+  }
+
+  /**
+   * Storage statistics over time.
+   *
+   * Return periodic snapshots of the object count, total size and largest
+   * prefixes of each backend, with the average daily growth over the returned
+   * window. With format=prometheus the latest snapshot of each backend is
+   * rendered as Prometheus gauges instead. Requires a server started with
+   * storage statistics enabled.
+   *
+   * @param query.backend Only return this backend
+   * @param query.since Start of the window, as an RFC 3339 time or a duration back from now such as 168h (default the whole retained history)
+   * @param query.format Response format
+   */
+  async getStats(query: { backend?: string; since?: string; format?: 'json' | 'prometheus' } = {}, opts?: RequestOptions): Promise<StorageStats> {
+    return (await (await this.request('GET', `/api/v1/stats`, { ...query }, undefined, undefined, opts)).json()) as StorageStats;
+  }
+
+  /**
+   * Age and access distribution of a prefix.
+   *
+   * Count the objects under a prefix in buckets by the time since they were
+   * last modified and, on a server started with access tracking enabled, by
+   * the time since they were last read, in total and grouped by key segments
+   * below the prefix. Objects not read since tracking started are counted in a
+   * final "never" bucket. Every object under the prefix is listed.
+   *
+   * @param query.prefix Only count keys starting with this prefix
+   * @param query.depth Key segments below the prefix objects are grouped by (default 1, negative for totals only)
+   * @param query.buckets Comma-separated ascending bucket bounds, as days such as 30d or durations such as 12h (default 1d,7d,30d,90d,180d,365d)
+   */
+  async analyzePrefix(query: { prefix?: string; depth?: number; buckets?: string } = {}, opts?: RequestOptions): Promise<AccessReport> {
+    return (await (await this.request('GET', `/api/v1/analyze`, { ...query }, undefined, undefined, opts)).json()) as AccessReport;
+  }
+
+  /**
+   * Estimate monthly cost.
+   *
+   * Return the storage, request and egress cost of a month by backend and
+   * group, priced with the server's pricing tables. For the current month the
+   * report also projects the cost of the whole month from the bytes stored now
+   * and the request and egress rates so far. Requires a server started with
+   * cost metering enabled.
+   *
+   * @param query.month Month to report, formatted as YYYY-MM (default the current month, UTC)
+   */
+  async getCostReport(query: { month?: string } = {}, opts?: RequestOptions): Promise<CostReport> {
+    return (await (await this.request('GET', `/api/v1/cost`, { ...query }, undefined, undefined, opts)).json()) as CostReport;
+  }
+
+  /**
+   * List background jobs.
+   *
+   * Return the status of every background job the server schedules, such as
+   * lifecycle, replication and inventory: whether it is running, its last run
+   * and when it is next due. Requires a server started with at least one
+   * background job.
+   */
+  async listJobs(opts?: RequestOptions): Promise<JobList> {
+    return (await (await this.request('GET', `/api/v1/jobs`, undefined, undefined, undefined, opts)).json()) as JobList;
+  }
+
+  /**
+   * Get a background job.
+   *
+   * Return the status of a background job with its run history, newest first.
+   *
+   * @param name Job name
+   */
+  async getJob(name: string, opts?: RequestOptions): Promise<JobStatus> {
+    return (await (await this.request('GET', `/api/v1/jobs/${encodePath(name)}`, undefined, undefined, undefined, opts)).json()) as JobStatus;
+  }
+
+  /**
+   * List tasks.
+   *
+   * Return queued tasks, oldest first: asynchronous archives, replication
+   * syncs and notification redeliveries with their attempts and last error.
+   * Requires a server started with --tasks.
+   *
+   * @param query.state Only return tasks in this state
+   */
+  async listTasks(query: { state?: 'pending' | 'running' | 'succeeded' | 'dead' } = {}, opts?: RequestOptions): Promise<TaskList> {
+    return (await (await this.request('GET', `/api/v1/tasks`, { ...query }, undefined, undefined, opts)).json()) as TaskList;
+  }
+
+  /**
+   * Get a task.
+   *
+   * Return a queued task.
+   *
+   * @param id Task ID
+   */
+  async getTask(id: string, opts?: RequestOptions): Promise<Task> {
+    return (await (await this.request('GET', `/api/v1/tasks/${encodePath(id)}`, undefined, undefined, undefined, opts)).json()) as Task;
+  }
+
+  /**
+   * Retry a dead-lettered task.
+   *
+   * Move a dead-lettered task back to pending with a fresh set of attempts.
+   *
+   * @param id Task ID
+   */
+  async retryTask(id: string, opts?: RequestOptions): Promise<Task> {
+    return (await (await this.request('POST', `/api/v1/tasks/${encodePath(id)}/retry`, undefined, undefined, undefined, opts)).json()) as Task;
+  }
+
+  /**
+   * Query object content with SQL.
+   *
+   * Run an S3 Select-style SQL query over a CSV, JSON or Parquet object and
+   * stream the matching records. A failure after the response started
+   * truncates it and is reported in the X-Select-Error trailer. The query's
+   * statistics are sent in the X-Select-Bytes-Scanned,
+   * X-Select-Bytes-Processed and X-Select-Bytes-Returned trailers.
+   *
+   * @param key Object key/path
+   */
+  async selectObjectContent(key: string, body: SelectObjectContentRequest, opts?: RequestOptions): Promise<string> {
+    return (await this.request('POST', `/api/v1/select/${encodePath(key)}`, undefined, body === undefined ? undefined : JSON.stringify(body), 'application/json', opts)).text();
+  }
+
+  /**
+   * Compare two objects.
+   *
+   * Compare two objects on the server. Text is compared line by line and
+   * returned as a unified diff, JSON documents are compared structurally (each
+   * added, removed or replaced value by its JSON Pointer path), and anything
+   * else byte by byte. Text and JSON comparison is limited to 16 MiB per
+   * object and 1000 changes; without a mode, larger objects are compared as
+   * binary.
+   *
+   * @param a First object key, optionally followed by @ and an RFC 3339 time to compare the version that was current then
+
+   * @param b Second object key, in the same form as a
+   * @param query.mode Comparison mode (default detected from the content)
+   * @param query.context Unchanged lines shown around each change in a text diff (default 3)
+   */
+  async diffObjects(a: string, b: string, query: { mode?: 'text' | 'json' | 'binary'; context?: number } = {}, opts?: RequestOptions): Promise<DiffResult> {
+    return (await (await this.request('GET', `/api/v1/diff`, { a: a, b: b, ...query }, undefined, undefined, opts)).json()) as DiffResult;
+  }
+
+  /**
+   * Rename a prefix.
+   *
+   * Move every object whose key starts with prefix to the same key under
+   * new_prefix. Objects are copied in parallel, server-side where the backend
+   * supports it, keeping their metadata, and each is deleted from its old key
+   * only once its copy succeeded. Objects that already exist at a new key with
+   * different content are skipped unless overwrite is set. Running the same
+   * rename again resumes it: objects already copied are recognized and only
+   * removed from their old key. A failure on one object does not stop the
+   * others; the first error is returned once every object has been visited.
+   * Requires delete permission on prefix and write permission on new_prefix.
+   *
+   * @param prefix Key prefix of the objects to move
+   * @param newPrefix Key prefix to move them to; neither prefix may contain the other
+   * @param query.dry_run Report what would be moved and skipped without changing anything
+   * @param query.overwrite Replace objects that already exist at a new key with different content
+   * @param query.concurrency Objects moved in parallel (default 8)
+   */
+  async renamePrefix(prefix: string, newPrefix: string, query: { dry_run?: boolean; overwrite?: boolean; concurrency?: number } = {}, opts?: RequestOptions): Promise<RenameResult> {
+    return (await (await this.request('POST', `/api/v1/rename`, { prefix: prefix, new_prefix: newPrefix, ...query }, undefined, undefined, opts)).json()) as RenameResult;
+  }
+
+  /**
+   * Check object existence.
+   *
+   * Lightweight existence check for an object. Returns 200 if the object
+   * exists, 404 otherwise. No body is returned.
+   *
+   * @param key Object key/path
+   */
+  async existsObject(key: string, opts?: RequestOptions): Promise<void> {
+    await this.request('HEAD', `/api/v1/exists/${encodePath(key)}`, undefined, undefined, undefined, opts);
+  }
+
+  /**
+   * Get object metadata.
+   *
+   * Retrieve metadata for an object as a JSON body without downloading the
+   * object content. This route is parity with the QUIC GET /metadata/<key>
+   * route and the gRPC GetMetadata RPC.
+   *
+   * @param key Object key/path
+   */
+  async getObjectMetadata(key: string, opts?: RequestOptions): Promise<ObjectResponse> {
+    return (await (await this.request('GET', `/api/v1/metadata/${encodePath(key)}`, undefined, undefined, undefined, opts)).json()) as ObjectResponse;
+  }
+
+  /**
+   * Update object metadata.
+   *
+   * Update metadata for an existing object
+   *
+   * @param key Object key/path
+   */
+  async updateObjectMetadata(key: string, body: Metadata, opts?: RequestOptions): Promise<SuccessResponse> {
+    return (await (await this.request('PUT', `/api/v1/metadata/${encodePath(key)}`, undefined, body === undefined ? undefined : JSON.stringify(body), 'application/json', opts)).json()) as SuccessResponse;
+  }
+
+  /**
+   * Archive object.
+   *
+   * Archive an object to a cold-storage destination (e.g., Glacier). The
+   * object is read from the active backend and written to the specified
+   * destination.
+   */
+  async archiveObject(body: ArchiveRequest, opts?: RequestOptions): Promise<SuccessResponse> {
+    return (await (await this.request('POST', `/api/v1/archive`, undefined, body === undefined ? undefined : JSON.stringify(body), 'application/json', opts)).json()) as SuccessResponse;
+  }
+
+  /**
+   * List lifecycle policies.
+   *
+   * Retrieve all configured lifecycle policies, optionally filtered by prefix
+   *
+   * @param query.prefix Filter policies by object-key prefix
+   */
+  async getPolicies(query: { prefix?: string } = {}, opts?: RequestOptions): Promise<PolicyListResponse> {
+    return (await (await this.request('GET', `/api/v1/policies`, { ...query }, undefined, undefined, opts)).json()) as PolicyListResponse;
+  }
+
+  /**
+   * Add lifecycle policy.
+   *
+   * Create a new lifecycle policy
+   */
+  async addPolicy(body: PolicyRequest, opts?: RequestOptions): Promise<SuccessResponse> {
+    return (await (await this.request('POST', `/api/v1/policies`, undefined, body === undefined ? undefined : JSON.stringify(body), 'application/json', opts)).json()) as SuccessResponse;
+  }
+
+  /**
+   * Remove lifecycle policy.
+   *
+   * Delete a lifecycle policy by its ID
+   *
+   * @param id Policy ID
+   */
+  async removePolicy(id: string, opts?: RequestOptions): Promise<SuccessResponse> {
+    return (await (await this.request('DELETE', `/api/v1/policies/${encodePath(id)}`, undefined, undefined, undefined, opts)).json()) as SuccessResponse;
+  }
+
+  /**
+   * Apply lifecycle policies.
+   *
+   * Evaluate all lifecycle policies against the current object set and execute
+   * the configured actions (delete or archive) for matching objects that have
+   * exceeded their retention period.
+   */
+  async applyPolicies(opts?: RequestOptions): Promise<ApplyPoliciesResponse> {
+    return (await (await this.request('POST', `/api/v1/policies/apply`, undefined, undefined, undefined, opts)).json()) as ApplyPoliciesResponse;
+  }
+
+  /**
+   * List replication policies.
+   *
+   * Retrieve all configured replication policies
+   */
+  async getReplicationPolicies(opts?: RequestOptions): Promise<ReplicationPolicyListResponse> {
+    return (await (await this.request('GET', `/api/v1/replication/policies`, undefined, undefined, undefined, opts)).json()) as ReplicationPolicyListResponse;
+  }
+
+  /**
+   * Add replication policy.
+   *
+   * Create a new replication policy
+   */
+  async addReplicationPolicy(body: ReplicationPolicyRequest, opts?: RequestOptions): Promise<SuccessResponse> {
+    return (await (await this.request('POST', `/api/v1/replication/policies`, undefined, body === undefined ? undefined : JSON.stringify(body), 'application/json', opts)).json()) as SuccessResponse;
+  }
+
+  /**
+   * Get replication policy.
+   *
+   * Retrieve a specific replication policy by ID
+   *
+   * @param id Replication policy ID
+   */
+  async getReplicationPolicy(id: string, opts?: RequestOptions): Promise<ReplicationPolicyResponse> {
+    return (await (await this.request('GET', `/api/v1/replication/policies/${encodePath(id)}`, undefined, undefined, undefined, opts)).json()) as ReplicationPolicyResponse;
+  }
+
+  /**
+   * Remove replication policy.
+   *
+   * Delete a replication policy by ID
+   *
+   * @param id Replication policy ID
+   */
+  async removeReplicationPolicy(id: string, opts?: RequestOptions): Promise<SuccessResponse> {
+    return (await (await this.request('DELETE', `/api/v1/replication/policies/${encodePath(id)}`, undefined, undefined, undefined, opts)).json()) as SuccessResponse;
+  }
+
+  /**
+   * Trigger replication.
+   *
+   * Manually trigger replication for a specific policy
+   */
+  async triggerReplication(body: TriggerReplicationRequest, opts?: RequestOptions): Promise<SuccessResponse> {
+    return (await (await this.request('POST', `/api/v1/replication/trigger`, undefined, body === undefined ? undefined : JSON.stringify(body), 'application/json', opts)).json()) as SuccessResponse;
+  }
+
+  /**
+   * Get replication status.
+   *
+   * Retrieve the current replication status for a policy
+   *
+   * @param id Replication policy ID
+   */
+  async getReplicationStatus(id: string, opts?: RequestOptions): Promise<ReplicationStatusResponse> {
+    return (await (await this.request('GET', `/api/v1/replication/status/${encodePath(id)}`, undefined, undefined, undefined, opts)).json()) as ReplicationStatusResponse;
+  }
+
+  /**
+   * List deletion requests.
+   *
+   * List deletions of objects under protected prefixes, oldest first. Requires
+   * the admin action on the retention resource.
+   *
+   * @param query.status Only return requests with this status
+   */
+  async listDeletionRequests(query: { status?: 'pending' | 'approved' | 'rejected' } = {}, opts?: RequestOptions): Promise<DeletionRequestList> {
+    return (await (await this.request('GET', `/api/v1/deletions`, { ...query }, undefined, undefined, opts)).json()) as DeletionRequestList;
+  }
+
+  /**
+   * Get deletion request.
+   *
+   * @param id Deletion request ID
+   */
+  async getDeletionRequest(id: string, opts?: RequestOptions): Promise<DeletionRequest> {
+    return (await (await this.request('GET', `/api/v1/deletions/${encodePath(id)}`, undefined, undefined, undefined, opts)).json()) as DeletionRequest;
+  }
+
+  /**
+   * Approve deletion request.
+   *
+   * Approve a pending deletion and delete the object. The approver must be a
+   * different principal from the requester (two-person rule).
+   *
+   * @param id Deletion request ID
+   */
+  async approveDeletionRequest(id: string, opts?: RequestOptions): Promise<DeletionRequest> {
+    return (await (await this.request('POST', `/api/v1/deletions/${encodePath(id)}/approve`, undefined, undefined, undefined, opts)).json()) as DeletionRequest;
+  }
+
+  /**
+   * Reject deletion request.
+   *
+   * Reject a pending deletion; the object is kept.
+   *
+   * @param id Deletion request ID
+   */
+  async rejectDeletionRequest(id: string, body?: RejectDeletionRequest, opts?: RequestOptions): Promise<DeletionRequest> {
+    return (await (await this.request('POST', `/api/v1/deletions/${encodePath(id)}/reject`, undefined, body === undefined ? undefined : JSON.stringify(body), 'application/json', opts)).json()) as DeletionRequest;
+  }
+
+  /** List legal holds. */
+  async listLegalHolds(opts?: RequestOptions): Promise<LegalHoldList> {
+    return (await (await this.request('GET', `/api/v1/holds`, undefined, undefined, undefined, opts)).json()) as LegalHoldList;
+  }
+
+  /**
+   * Place legal hold.
+   *
+   * Place a legal hold on an object. Held objects cannot be deleted or
+   * overwritten until the hold is released.
+   *
+   * @param key Object key/path
+   */
+  async placeLegalHold(key: string, body?: LegalHoldRequest, opts?: RequestOptions): Promise<LegalHold> {
+    return (await (await this.request('PUT', `/api/v1/holds/${encodePath(key)}`, undefined, body === undefined ? undefined : JSON.stringify(body), 'application/json', opts)).json()) as LegalHold;
+  }
+
+  /**
+   * Release legal hold.
+   *
+   * @param key Object key/path
+   */
+  async releaseLegalHold(key: string, opts?: RequestOptions): Promise<void> {
+    await this.request('DELETE', `/api/v1/holds/${encodePath(key)}`, undefined, undefined, undefined, opts);
+  }
+
+  /**
+   * List locks.
+   *
+   * List the held advisory locks. Lock tokens are not returned.
+   *
+   * @param query.prefix Only list locks on keys starting with this prefix
+   */
+  async listLocks(query: { prefix?: string } = {}, opts?: RequestOptions): Promise<LockList> {
+    return (await (await this.request('GET', `/api/v1/locks`, { ...query }, undefined, undefined, opts)).json()) as LockList;
+  }
+
+  /**
+   * Get lock.
+   *
+   * Get the advisory lock on a key. The lock token is not returned.
+   *
+   * @param key Locked key
+   */
+  async getLock(key: string, opts?: RequestOptions): Promise<Lock> {
+    return (await (await this.request('GET', `/api/v1/locks/${encodePath(key)}`, undefined, undefined, undefined, opts)).json()) as Lock;
+  }
+
+  /**
+   * Acquire or refresh lock.
+   *
+   * Acquire the advisory lock on a key, waiting up to wait_seconds for it to
+   * be released, and return it with the token needed to refresh or release it.
+   * An expired lock is taken over. With an X-Lock-Token header the held lock
+   * is refreshed to expire ttl_seconds from now instead. Locks are advisory
+   * and do not block writes to the key.
+   *
+   * @param key Key to lock
+   */
+  async acquireLock(key: string, body?: AcquireLockRequest, opts?: RequestOptions): Promise<Lock> {
+    return (await (await this.request('PUT', `/api/v1/locks/${encodePath(key)}`, undefined, body === undefined ? undefined : JSON.stringify(body), 'application/json', opts)).json()) as Lock;
+  }
+
+  /**
+   * Release lock.
+   *
+   * @param key Locked key
+   */
+  async releaseLock(key: string, opts?: RequestOptions): Promise<void> {
+    await this.request('DELETE', `/api/v1/locks/${encodePath(key)}`, undefined, undefined, undefined, opts);
+  }
+
+  /**
+   * List aliases.
+   *
+   * @param query.prefix Only list aliases whose names start with this prefix
+   */
+  async listAliases(query: { prefix?: string } = {}, opts?: RequestOptions): Promise<AliasList> {
+    return (await (await this.request('GET', `/api/v1/aliases`, { ...query }, undefined, undefined, opts)).json()) as AliasList;
+  }
+
+  /**
+   * Resolve alias.
+   *
+   * Get an alias with the key of the object it points at. Reading the alias
+   * name as an object reads its target directly.
+   *
+   * @param key Alias name
+   */
+  async getAlias(key: string, opts?: RequestOptions): Promise<Alias> {
+    return (await (await this.request('GET', `/api/v1/aliases/${encodePath(key)}`, undefined, undefined, undefined, opts)).json()) as Alias;
+  }
+
+  /**
+   * Set alias.
+   *
+   * Point an alias at an existing object, creating the alias or moving it.
+   * Reads of the alias name that find no object read the target instead, so a
+   * well-known name can follow new releases without copying them. The target
+   * must be an object, not another alias, and the caller must be allowed to
+   * read it.
+   *
+   * @param key Alias name
+   */
+  async setAlias(key: string, body: SetAliasRequest, opts?: RequestOptions): Promise<Alias> {
+    return (await (await this.request('PUT', `/api/v1/aliases/${encodePath(key)}`, undefined, body === undefined ? undefined : JSON.stringify(body), 'application/json', opts)).json()) as Alias;
+  }
+
+  /**
+   * Delete alias.
+   *
+   * Remove an alias. Its target is not changed.
+   *
+   * @param key Alias name
+   */
+  async deleteAlias(key: string, opts?: RequestOptions): Promise<void> {
+    await this.request('DELETE', `/api/v1/aliases/${encodePath(key)}`, undefined, undefined, undefined, opts);
+  }
+
+  /**
+   * List quotas.
+   *
+   * List the prefixes with a quota on the default backend.
+   */
+  async listQuotas(opts?: RequestOptions): Promise<QuotaList> {
+    return (await (await this.request('GET', `/api/v1/quotas`, undefined, undefined, undefined, opts)).json()) as QuotaList;
+  }
+
+  /**
+   * Get quota.
+   *
+   * @param prefix Key prefix; empty for every key
+   */
+  async getQuota(prefix: string, opts?: RequestOptions): Promise<Quota> {
+    return (await (await this.request('GET', `/api/v1/quotas/${encodePath(prefix)}`, undefined, undefined, undefined, opts)).json()) as Quota;
+  }
+
+  /**
+   * Set quota.
+   *
+   * Limit the bytes and objects stored under a prefix. Writes that would
+   * exceed the quota fail with 429. A prefix without an overlay of its own
+   * gets one with the settings of the overlay that applied to it, so its
+   * objects are still compressed and encrypted the same way. Quotas set
+   * through the API last until the server restarts and reads its --overlays
+   * file again.
+   *
+   * @param prefix Key prefix; empty for every key
+   */
+  async setQuota(prefix: string, body: SetQuotaRequest, opts?: RequestOptions): Promise<Quota> {
+    return (await (await this.request('PUT', `/api/v1/quotas/${encodePath(prefix)}`, undefined, body === undefined ? undefined : JSON.stringify(body), 'application/json', opts)).json()) as Quota;
+  }
+
+  /**
+   * Remove quota.
+   *
+   * Remove the quota of a prefix. Its overlay is kept.
+   *
+   * @param prefix Key prefix; empty for every key
+   */
+  async removeQuota(prefix: string, opts?: RequestOptions): Promise<void> {
+    await this.request('DELETE', `/api/v1/quotas/${encodePath(prefix)}`, undefined, undefined, undefined, opts);
+  }
+
+  /**
+   * List webhooks.
+   *
+   * List the event notification rules of the default backend with their sink
+   * settings, which may carry credentials.
+   */
+  async listWebhooks(opts?: RequestOptions): Promise<WebhookList> {
+    return (await (await this.request('GET', `/api/v1/webhooks`, undefined, undefined, undefined, opts)).json()) as WebhookList;
+  }
+
+  /**
+   * Get webhook.
+   *
+   * @param id Webhook ID
+   */
+  async getWebhook(id: string, opts?: RequestOptions): Promise<Webhook> {
+    return (await (await this.request('GET', `/api/v1/webhooks/${encodePath(id)}`, undefined, undefined, undefined, opts)).json()) as Webhook;
+  }
+
+  /**
+   * Set webhook.
+   *
+   * Create or replace an event notification rule, which sends the events it
+   * matches to a sink: an incoming webhook (slack, teams) or a message service
+   * (sns, sqs, eventbridge, pubsub, kafka, smtp). Notifications already queued
+   * go to the sink they were queued for. Rules set through the API last until
+   * the server restarts and reads its --notifications file again.
+   *
+   * @param id Webhook ID
+   */
+  async setWebhook(id: string, body: SetWebhookRequest, opts?: RequestOptions): Promise<Webhook> {
+    return (await (await this.request('PUT', `/api/v1/webhooks/${encodePath(id)}`, undefined, body === undefined ? undefined : JSON.stringify(body), 'application/json', opts)).json()) as Webhook;
+  }
+
+  /**
+   * Delete webhook.
+   *
+   * Remove an event notification rule. Notifications already queued for it are
+   * still delivered.
+   *
+   * @param id Webhook ID
+   */
+  async deleteWebhook(id: string, opts?: RequestOptions): Promise<void> {
+    await this.request('DELETE', `/api/v1/webhooks/${encodePath(id)}`, undefined, undefined, undefined, opts);
+  }
+
+  /**
+   * List access keys.
+   *
+   * List the SigV4 access keys issued by the server. Secrets are not returned.
+   */
+  async listAccessKeys(opts?: RequestOptions): Promise<AccessKeyList> {
+    return (await (await this.request('GET', `/api/v1/access-keys`, undefined, undefined, undefined, opts)).json()) as AccessKeyList;
+  }
+
+  /**
+   * Create access key.
+   *
+   * Issue a SigV4 access key that authenticates as the given principal and
+   * roles. The secret is only returned in this response.
+   */
+  async createAccessKey(body: CreateAccessKeyRequest, opts?: RequestOptions): Promise<AccessKey> {
+    return (await (await this.request('POST', `/api/v1/access-keys`, undefined, body === undefined ? undefined : JSON.stringify(body), 'application/json', opts)).json()) as AccessKey;
+  }
+
+  /**
+   * Get access key.
+   *
+   * Get an issued access key. The secret is not returned.
+   *
+   * @param id Access key ID
+   */
+  async getAccessKey(id: string, opts?: RequestOptions): Promise<AccessKey> {
+    return (await (await this.request('GET', `/api/v1/access-keys/${encodePath(id)}`, undefined, undefined, undefined, opts)).json()) as AccessKey;
+  }
+
+  /**
+   * Delete access key.
+   *
+   * Revoke an access key; requests signed with it no longer authenticate.
+   *
+   * @param id Access key ID
+   */
+  async deleteAccessKey(id: string, opts?: RequestOptions): Promise<void> {
+    await this.request('DELETE', `/api/v1/access-keys/${encodePath(id)}`, undefined, undefined, undefined, opts);
+  }
+
+  /**
+   * List share links.
+   *
+   * List every share link with its download accounting, including expired and
+   * exhausted links. Link tokens are not returned.
+   */
+  async listShares(opts?: RequestOptions): Promise<ShareList> {
+    return (await (await this.request('GET', `/api/v1/shares`, undefined, undefined, undefined, opts)).json()) as ShareList;
+  }
+
+  /**
+   * Create share link.
+   *
+   * Create a public download link to an object and return it with its token
+   * and URL, which are only returned here. Creating a link needs read access
+   * to the key. Objects encrypted with a customer-provided key cannot be
+   * shared.
+   *
+   * @param key Key of the object to share
+   */
+  async createShare(key: string, body?: CreateShareRequest, opts?: RequestOptions): Promise<Share> {
+    return (await (await this.request('POST', `/api/v1/shares/objects/${encodePath(key)}`, undefined, body === undefined ? undefined : JSON.stringify(body), 'application/json', opts)).json()) as Share;
+  }
+
+  /**
+   * Get share link.
+   *
+   * Get a share link with its download accounting. The link token is not
+   * returned.
+   *
+   * @param id Link ID
+   */
+  async getShare(id: string, opts?: RequestOptions): Promise<Share> {
+    return (await (await this.request('GET', `/api/v1/shares/${encodePath(id)}`, undefined, undefined, undefined, opts)).json()) as Share;
+  }
+
+  /**
+   * Revoke share link.
+   *
+   * Revoke a share link. Downloads already in progress are not interrupted.
+   *
+   * @param id Link ID
+   */
+  async revokeShare(id: string, opts?: RequestOptions): Promise<void> {
+    await this.request('DELETE', `/api/v1/shares/${encodePath(id)}`, undefined, undefined, undefined, opts);
+  }
+
+  /**
+   * Create upload session.
+   *
+   * Start an upload session for an object. Numbered chunks are then uploaded
+   * to the session, in any order and in parallel, and the session is completed
+   * to store the object. Sessions are kept on the backend, so they survive
+   * server restarts, and expire after their TTL. Creating a session needs
+   * write access to the key; so do the operations on it.
+   *
+   * @param key Key of the object to upload
+   */
+  async createUpload(key: string, body?: CreateUploadRequest, opts?: RequestOptions): Promise<UploadSession> {
+    return (await (await this.request('POST', `/api/v1/uploads/objects/${encodePath(key)}`, undefined, body === undefined ? undefined : JSON.stringify(body), 'application/json', opts)).json()) as UploadSession;
+  }
+
+  /**
+   * Get upload session.
+   *
+   * Get an upload session with the chunks received so far, so an interrupted
+   * upload can resume with the missing ones.
+   *
+   * @param id Upload session ID
+   */
+  async getUpload(id: string, opts?: RequestOptions): Promise<UploadSession> {
+    return (await (await this.request('GET', `/api/v1/uploads/${encodePath(id)}`, undefined, undefined, undefined, opts)).json()) as UploadSession;
+  }
+
+  /**
+   * Abort upload session.
+   *
+   * Abort an upload session and delete its chunks.
+   *
+   * @param id Upload session ID
+   */
+  async abortUpload(id: string, opts?: RequestOptions): Promise<void> {
+    await this.request('DELETE', `/api/v1/uploads/${encodePath(id)}`, undefined, undefined, undefined, opts);
+  }
+
+  /**
+   * Upload chunk.
+   *
+   * Upload a numbered chunk of an upload session, replacing any earlier upload
+   * of it. The chunk is only kept if it matches the SHA-256 in the
+   * X-Chunk-SHA256 header. Chunks are at most 256 MiB, and at most the
+   * server's maximum request size.
+   *
+   * @param id Upload session ID
+   * @param number Chunk number, from 1 to 10000
+   */
+  async uploadChunk(id: string, number: string, body: BodyInit, opts?: RequestOptions): Promise<UploadChunk> {
+    return (await (await this.request('PUT', `/api/v1/uploads/${encodePath(id)}/chunks/${encodePath(number)}`, undefined, body, 'application/octet-stream', opts)).json()) as UploadChunk;
+  }
+
+  /**
+   * Complete upload session.
+   *
+   * Assemble the chunks of an upload session, numbered from 1 without gaps,
+   * into its object and end the session. Each chunk is checked against its
+   * SHA-256 again as it is read, and the whole object against the optional
+   * sha256 of the body.
+   *
+   * @param id Upload session ID
+   */
+  async completeUpload(id: string, body?: CompleteUploadRequest, opts?: RequestOptions): Promise<SuccessResponse> {
+    return (await (await this.request('POST', `/api/v1/uploads/${encodePath(id)}/complete`, undefined, body === undefined ? undefined : JSON.stringify(body), 'application/json', opts)).json()) as SuccessResponse;
+  }
+
+  /** List manifests. */
+  async listManifests(opts?: RequestOptions): Promise<ManifestList> {
+    return (await (await this.request('GET', `/api/v1/manifests`, undefined, undefined, undefined, opts)).json()) as ManifestList;
+  }
+
+  /**
+   * Create manifest.
+   *
+   * Create the draft of a new manifest. Each key is pinned to the ETag and
+   * size it has now; publishing or archiving fails if a member changes
+   * afterwards.
+   */
+  async createManifest(body: CreateManifestRequest, opts?: RequestOptions): Promise<Manifest> {
+    return (await (await this.request('POST', `/api/v1/manifests`, undefined, body === undefined ? undefined : JSON.stringify(body), 'application/json', opts)).json()) as Manifest;
+  }
+
+  /**
+   * Get current manifest version.
+   *
+   * @param name Manifest name
+   */
+  async getManifest(name: string, opts?: RequestOptions): Promise<Manifest> {
+    return (await (await this.request('GET', `/api/v1/manifests/${encodePath(name)}`, undefined, undefined, undefined, opts)).json()) as Manifest;
+  }
+
+  /**
+   * Get manifest draft.
+   *
+   * @param name Manifest name
+   */
+  async getManifestDraft(name: string, opts?: RequestOptions): Promise<Manifest> {
+    return (await (await this.request('GET', `/api/v1/manifests/${encodePath(name)}/draft`, undefined, undefined, undefined, opts)).json()) as Manifest;
+  }
+
+  /**
+   * Update manifest members.
+   *
+   * Add keys to and then remove keys from a manifest's draft. Added keys are
+   * pinned to their current ETag and size; keys already in the draft are
+   * re-pinned.
+   *
+   * @param name Manifest name
+   */
+  async updateManifestMembers(name: string, body: UpdateManifestMembersRequest, opts?: RequestOptions): Promise<Manifest> {
+    return (await (await this.request('POST', `/api/v1/manifests/${encodePath(name)}/members`, undefined, body === undefined ? undefined : JSON.stringify(body), 'application/json', opts)).json()) as Manifest;
+  }
+
+  /**
+   * Publish manifest.
+   *
+   * Publish the draft as the manifest's next version and make it current in a
+   * single write, so readers see the old dataset or the new one and never a
+   * mix. Fails if any member changed since it was added, or if another publish
+   * won the race.
+   *
+   * @param name Manifest name
+   */
+  async publishManifest(name: string, opts?: RequestOptions): Promise<Manifest> {
+    return (await (await this.request('POST', `/api/v1/manifests/${encodePath(name)}/publish`, undefined, undefined, undefined, opts)).json()) as Manifest;
+  }
+
+  /**
+   * List manifest versions.
+   *
+   * @param name Manifest name
+   */
+  async listManifestVersions(name: string, opts?: RequestOptions): Promise<ManifestVersionList> {
+    return (await (await this.request('GET', `/api/v1/manifests/${encodePath(name)}/versions`, undefined, undefined, undefined, opts)).json()) as ManifestVersionList;
+  }
+
+  /**
+   * Get manifest version.
+   *
+   * @param name Manifest name
+   * @param version Published version number
+   */
+  async getManifestVersion(name: string, version: string, opts?: RequestOptions): Promise<Manifest> {
+    return (await (await this.request('GET', `/api/v1/manifests/${encodePath(name)}/versions/${encodePath(version)}`, undefined, undefined, undefined, opts)).json()) as Manifest;
+  }
+
+  /**
+   * Download manifest as tar.
+   *
+   * Stream the members of a manifest version as a tar archive, in key order.
+   * Every member is checked against its pinned ETag before the archive starts;
+   * a member changed while streaming truncates it.
+   *
+   * @param name Manifest name
+   * @param query.version Published version number, or "draft"; defaults to the current version
+   */
+  async getManifestTar(name: string, query: { version?: string } = {}, opts?: RequestOptions): Promise<Response> {
+    return this.request('GET', `/api/v1/manifests/${encodePath(name)}/tar`, { ...query }, undefined, undefined, opts);
+  }
+
+  /**
+   * Mint scoped token.
+   *
+   * Exchange the caller's credentials for a short-lived token limited to a key
+   * prefix and a set of actions (read, write, delete, list), to hand to an
+   * untrusted worker or browser. The caller must hold every requested action.
+   * Available when the server is configured with a token service.
+   */
+  async createToken(body: TokenRequest, opts?: RequestOptions): Promise<TokenResponse> {
+    return (await (await this.request('POST', `/api/v1/tokens`, undefined, body === undefined ? undefined : JSON.stringify(body), 'application/json', opts)).json()) as TokenResponse;
+  }
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package openapi embeds the OpenAPI 3 specification of the REST server.
+//
+// objstore.yaml is the source of truth. The REST server serves it as
+// /openapi.json and /openapi.yaml, the route tests check that it documents
+// every registered handler, and the TypeScript and Python clients under
+// clients/ are generated from it with "make openapi-clients".
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed objstore.yaml
+var specYAML []byte
+
+var (
+	jsonOnce sync.Once
+	specJSON []byte
+	jsonErr  error
+)
+
+// methods lists the HTTP methods an OpenAPI path item may define, in the
+// order operations are reported.
+var methods = []string{"get", "put", "post", "delete", "head", "patch", "options"}
+
+// Operation is one method on one path of the specification.
+type Operation struct {
+	Method string // upper-case HTTP method
+	Path   string // OpenAPI path template, e.g. /objects/{key}
+	ID     string // operationId
+}
+
+// YAML returns the specification as written.
+func YAML() []byte {
+	return specYAML
+}
+
+// JSON returns the specification encoded as JSON.
+func JSON() ([]byte, error) {
+	jsonOnce.Do(func() {
+		var doc map[string]any
+		if jsonErr = yaml.Unmarshal(specYAML, &doc); jsonErr != nil {
+			jsonErr = fmt.Errorf("failed to parse OpenAPI spec: %w", jsonErr)
+			return
+		}
+		specJSON, jsonErr = json.Marshal(doc)
+	})
+	return specJSON, jsonErr
+}
+
+// Operations returns every operation in the specification, sorted by path
+// and then by method.
+func Operations() ([]Operation, error) {
+	var doc struct {
+		Paths map[string]map[string]yaml.Node `yaml:"paths"`
+	}
+	if err := yaml.Unmarshal(specYAML, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI spec: %w", err)
+	}
+
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var ops []Operation
+	for _, path := range paths {
+		for _, method := range methods {
+			node, ok := doc.Paths[path][method]
+			if !ok {
+				continue
+			}
+			var op struct {
+				OperationID string `yaml:"operationId"`
+			}
+			if err := node.Decode(&op); err != nil {
+				return nil, fmt.Errorf("failed to parse %s %s: %w", method, path, err)
+			}
+			ops = append(ops, Operation{Method: strings.ToUpper(method), Path: path, ID: op.OperationID})
+		}
+	}
+	return ops, nil
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestJSON(t *testing.T) {
+	data, err := JSON()
+	if err != nil {
+		t.Fatalf("JSON() error = %v", err)
+	}
+
+	var doc struct {
+		OpenAPI string                    `json:"openapi"`
+		Paths   map[string]map[string]any `json:"paths"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("JSON() is not valid JSON: %v", err)
+	}
+	if doc.OpenAPI != "3.0.3" {
+		t.Errorf("openapi = %q, want 3.0.3", doc.OpenAPI)
+	}
+	if _, ok := doc.Paths["/objects/{key}"]["put"]; !ok {
+		t.Error("spec is missing PUT /objects/{key}")
+	}
+}
+
+func TestOperations(t *testing.T) {
+	ops, err := Operations()
+	if err != nil {
+		t.Fatalf("Operations() error = %v", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, op := range ops {
+		if op.ID == "" {
+			t.Errorf("%s %s has no operationId", op.Method, op.Path)
+		}
+		if seen[op.ID] {
+			t.Errorf("duplicate operationId %q", op.ID)
+		}
+		seen[op.ID] = true
+	}
+	for _, id := range []string{"putObject", "searchObjects", "archiveObject", "addPolicy", "triggerReplication", "healthCheck"} {
+		if !seen[id] {
+			t.Errorf("operation %q not found", id)
+		}
+	}
+}
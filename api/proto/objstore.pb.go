@@ -17,6 +17,13 @@
 // 	protoc        v3.21.12
 // source: objstore.proto
 
+// Versioning: objstore.v1 only evolves in backwards-compatible ways. New
+// RPCs, messages and fields may be added, but existing field numbers, names
+// and types never change and removed fields are reserved. A breaking change
+// ships as a new package (objstore.v2) served alongside v1. Clients can read
+// the revision of the v1 surface a server implements from
+// HealthResponse.api_version.
+
 package objstorepb
 
 import (
@@ -352,7 +359,13 @@ type PutResponse struct {
 	// Optional message (e.g., error details)
 	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
 	// ETag of the stored object
-	Etag          string `protobuf:"bytes,3,opt,name=etag,proto3" json:"etag,omitempty"`
+	Etag string `protobuf:"bytes,3,opt,name=etag,proto3" json:"etag,omitempty"`
+	// Version or generation of the stored object on backends that keep them
+	Version string `protobuf:"bytes,4,opt,name=version,proto3" json:"version,omitempty"`
+	// Size of the stored object in bytes
+	Size int64 `protobuf:"varint,5,opt,name=size,proto3" json:"size,omitempty"`
+	// Modification time of the stored object
+	LastModified  *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=last_modified,json=lastModified,proto3" json:"last_modified,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -408,6 +421,27 @@ func (x *PutResponse) GetEtag() string {
 	return ""
 }
 
+func (x *PutResponse) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *PutResponse) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *PutResponse) GetLastModified() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastModified
+	}
+	return nil
+}
+
 // GetRequest represents a request to retrieve an object.
 type GetRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -1128,7 +1162,12 @@ type HealthResponse struct {
 	state  protoimpl.MessageState `protogen:"open.v1"`
 	Status HealthResponse_Status  `protobuf:"varint,1,opt,name=status,proto3,enum=objstore.v1.HealthResponse_Status" json:"status,omitempty"`
 	// Optional message
-	Message       string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// Revision of the objstore.v1 API implemented by the server. Incremented
+	// whenever RPCs or fields are added.
+	ApiVersion int32 `protobuf:"varint,3,opt,name=api_version,json=apiVersion,proto3" json:"api_version,omitempty"`
+	// Server build version
+	ServerVersion string `protobuf:"bytes,4,opt,name=server_version,json=serverVersion,proto3" json:"server_version,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -1177,6 +1216,20 @@ func (x *HealthResponse) GetMessage() string {
 	return ""
 }
 
+func (x *HealthResponse) GetApiVersion() int32 {
+	if x != nil {
+		return x.ApiVersion
+	}
+	return 0
+}
+
+func (x *HealthResponse) GetServerVersion() string {
+	if x != nil {
+		return x.ServerVersion
+	}
+	return ""
+}
+
 // ArchiveRequest represents a request to archive an object.
 type ArchiveRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -1296,6 +1349,465 @@ func (x *ArchiveResponse) GetMessage() string {
 	return ""
 }
 
+// RestoreFromArchiveRequest represents a request to copy an object back from
+// an archival backend.
+type RestoreFromArchiveRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Storage key to restore the object to
+	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// Source backend type. Archive-only backends (glacier, azurearchive) cannot
+	// be read back and are rejected with FAILED_PRECONDITION.
+	SourceType string `protobuf:"bytes,2,opt,name=source_type,json=sourceType,proto3" json:"source_type,omitempty"`
+	// Source backend settings
+	SourceSettings map[string]string `protobuf:"bytes,3,rep,name=source_settings,json=sourceSettings,proto3" json:"source_settings,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Key of the object in the source backend (defaults to key)
+	SourceKey     string `protobuf:"bytes,4,opt,name=source_key,json=sourceKey,proto3" json:"source_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreFromArchiveRequest) Reset() {
+	*x = RestoreFromArchiveRequest{}
+	mi := &file_objstore_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreFromArchiveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreFromArchiveRequest) ProtoMessage() {}
+
+func (x *RestoreFromArchiveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_objstore_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreFromArchiveRequest.ProtoReflect.Descriptor instead.
+func (*RestoreFromArchiveRequest) Descriptor() ([]byte, []int) {
+	return file_objstore_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *RestoreFromArchiveRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *RestoreFromArchiveRequest) GetSourceType() string {
+	if x != nil {
+		return x.SourceType
+	}
+	return ""
+}
+
+func (x *RestoreFromArchiveRequest) GetSourceSettings() map[string]string {
+	if x != nil {
+		return x.SourceSettings
+	}
+	return nil
+}
+
+func (x *RestoreFromArchiveRequest) GetSourceKey() string {
+	if x != nil {
+		return x.SourceKey
+	}
+	return ""
+}
+
+// RestoreFromArchiveResponse represents the response from a RestoreFromArchive operation.
+type RestoreFromArchiveResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether the operation was successful
+	Success bool `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	// Optional message (e.g., error details)
+	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// Size in bytes of the restored object
+	Size          int64 `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreFromArchiveResponse) Reset() {
+	*x = RestoreFromArchiveResponse{}
+	mi := &file_objstore_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreFromArchiveResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreFromArchiveResponse) ProtoMessage() {}
+
+func (x *RestoreFromArchiveResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_objstore_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreFromArchiveResponse.ProtoReflect.Descriptor instead.
+func (*RestoreFromArchiveResponse) Descriptor() ([]byte, []int) {
+	return file_objstore_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *RestoreFromArchiveResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *RestoreFromArchiveResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *RestoreFromArchiveResponse) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+// SearchRequest represents a search query against the metadata index.
+type SearchRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Space-separated terms that must all match (e.g. "report author:alice")
+	Query string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// Maximum number of results (0 = server default)
+	Limit         int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchRequest) Reset() {
+	*x = SearchRequest{}
+	mi := &file_objstore_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchRequest) ProtoMessage() {}
+
+func (x *SearchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_objstore_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchRequest.ProtoReflect.Descriptor instead.
+func (*SearchRequest) Descriptor() ([]byte, []int) {
+	return file_objstore_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *SearchRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// SearchResponse represents the results of a Search operation.
+type SearchResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Matching objects, sorted by key
+	Objects       []*ObjectInfo `protobuf:"bytes,1,rep,name=objects,proto3" json:"objects,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchResponse) Reset() {
+	*x = SearchResponse{}
+	mi := &file_objstore_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchResponse) ProtoMessage() {}
+
+func (x *SearchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_objstore_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchResponse.ProtoReflect.Descriptor instead.
+func (*SearchResponse) Descriptor() ([]byte, []int) {
+	return file_objstore_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *SearchResponse) GetObjects() []*ObjectInfo {
+	if x != nil {
+		return x.Objects
+	}
+	return nil
+}
+
+// GetChangesRequest represents a request to read the change feed.
+type GetChangesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Return changes with a greater sequence (0 = from the oldest retained change)
+	Since uint64 `protobuf:"varint,1,opt,name=since,proto3" json:"since,omitempty"`
+	// Maximum number of changes (0 = server default)
+	Limit         int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetChangesRequest) Reset() {
+	*x = GetChangesRequest{}
+	mi := &file_objstore_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetChangesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetChangesRequest) ProtoMessage() {}
+
+func (x *GetChangesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_objstore_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetChangesRequest.ProtoReflect.Descriptor instead.
+func (*GetChangesRequest) Descriptor() ([]byte, []int) {
+	return file_objstore_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GetChangesRequest) GetSince() uint64 {
+	if x != nil {
+		return x.Since
+	}
+	return 0
+}
+
+func (x *GetChangesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// Change represents one entry of the change feed.
+type Change struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Position of the change in the feed
+	Sequence uint64 `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	// Operation: "put", "update_metadata" or "delete"
+	Op string `protobuf:"bytes,2,opt,name=op,proto3" json:"op,omitempty"`
+	// Object key
+	Key string `protobuf:"bytes,3,opt,name=key,proto3" json:"key,omitempty"`
+	// ETag of the object after the change (empty for deletes)
+	Etag string `protobuf:"bytes,4,opt,name=etag,proto3" json:"etag,omitempty"`
+	// Size of the object after the change
+	Size int64 `protobuf:"varint,5,opt,name=size,proto3" json:"size,omitempty"`
+	// Time the change was recorded
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Change) Reset() {
+	*x = Change{}
+	mi := &file_objstore_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Change) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Change) ProtoMessage() {}
+
+func (x *Change) ProtoReflect() protoreflect.Message {
+	mi := &file_objstore_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Change.ProtoReflect.Descriptor instead.
+func (*Change) Descriptor() ([]byte, []int) {
+	return file_objstore_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *Change) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *Change) GetOp() string {
+	if x != nil {
+		return x.Op
+	}
+	return ""
+}
+
+func (x *Change) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *Change) GetEtag() string {
+	if x != nil {
+		return x.Etag
+	}
+	return ""
+}
+
+func (x *Change) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *Change) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+// GetChangesResponse represents a page of the change feed.
+type GetChangesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Changes after the requested sequence, oldest first
+	Changes []*Change `protobuf:"bytes,1,rep,name=changes,proto3" json:"changes,omitempty"`
+	// Sequence to request the next page with
+	Next uint64 `protobuf:"varint,2,opt,name=next,proto3" json:"next,omitempty"`
+	// Sequence of the most recent change
+	Latest uint64 `protobuf:"varint,3,opt,name=latest,proto3" json:"latest,omitempty"`
+	// Whether more changes after next are already available
+	More          bool `protobuf:"varint,4,opt,name=more,proto3" json:"more,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetChangesResponse) Reset() {
+	*x = GetChangesResponse{}
+	mi := &file_objstore_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetChangesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetChangesResponse) ProtoMessage() {}
+
+func (x *GetChangesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_objstore_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetChangesResponse.ProtoReflect.Descriptor instead.
+func (*GetChangesResponse) Descriptor() ([]byte, []int) {
+	return file_objstore_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *GetChangesResponse) GetChanges() []*Change {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+func (x *GetChangesResponse) GetNext() uint64 {
+	if x != nil {
+		return x.Next
+	}
+	return 0
+}
+
+func (x *GetChangesResponse) GetLatest() uint64 {
+	if x != nil {
+		return x.Latest
+	}
+	return 0
+}
+
+func (x *GetChangesResponse) GetMore() bool {
+	if x != nil {
+		return x.More
+	}
+	return false
+}
+
 // LifecyclePolicy represents a lifecycle policy for objects.
 type LifecyclePolicy struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -1317,7 +1829,7 @@ type LifecyclePolicy struct {
 
 func (x *LifecyclePolicy) Reset() {
 	*x = LifecyclePolicy{}
-	mi := &file_objstore_proto_msgTypes[20]
+	mi := &file_objstore_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1329,7 +1841,7 @@ func (x *LifecyclePolicy) String() string {
 func (*LifecyclePolicy) ProtoMessage() {}
 
 func (x *LifecyclePolicy) ProtoReflect() protoreflect.Message {
-	mi := &file_objstore_proto_msgTypes[20]
+	mi := &file_objstore_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1342,7 +1854,7 @@ func (x *LifecyclePolicy) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LifecyclePolicy.ProtoReflect.Descriptor instead.
 func (*LifecyclePolicy) Descriptor() ([]byte, []int) {
-	return file_objstore_proto_rawDescGZIP(), []int{20}
+	return file_objstore_proto_rawDescGZIP(), []int{27}
 }
 
 func (x *LifecyclePolicy) GetId() string {
@@ -1398,7 +1910,7 @@ type AddPolicyRequest struct {
 
 func (x *AddPolicyRequest) Reset() {
 	*x = AddPolicyRequest{}
-	mi := &file_objstore_proto_msgTypes[21]
+	mi := &file_objstore_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1410,7 +1922,7 @@ func (x *AddPolicyRequest) String() string {
 func (*AddPolicyRequest) ProtoMessage() {}
 
 func (x *AddPolicyRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_objstore_proto_msgTypes[21]
+	mi := &file_objstore_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1423,7 +1935,7 @@ func (x *AddPolicyRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AddPolicyRequest.ProtoReflect.Descriptor instead.
 func (*AddPolicyRequest) Descriptor() ([]byte, []int) {
-	return file_objstore_proto_rawDescGZIP(), []int{21}
+	return file_objstore_proto_rawDescGZIP(), []int{28}
 }
 
 func (x *AddPolicyRequest) GetPolicy() *LifecyclePolicy {
@@ -1446,7 +1958,7 @@ type AddPolicyResponse struct {
 
 func (x *AddPolicyResponse) Reset() {
 	*x = AddPolicyResponse{}
-	mi := &file_objstore_proto_msgTypes[22]
+	mi := &file_objstore_proto_msgTypes[29]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1458,7 +1970,7 @@ func (x *AddPolicyResponse) String() string {
 func (*AddPolicyResponse) ProtoMessage() {}
 
 func (x *AddPolicyResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_objstore_proto_msgTypes[22]
+	mi := &file_objstore_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1471,7 +1983,7 @@ func (x *AddPolicyResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AddPolicyResponse.ProtoReflect.Descriptor instead.
 func (*AddPolicyResponse) Descriptor() ([]byte, []int) {
-	return file_objstore_proto_rawDescGZIP(), []int{22}
+	return file_objstore_proto_rawDescGZIP(), []int{29}
 }
 
 func (x *AddPolicyResponse) GetSuccess() bool {
@@ -1499,7 +2011,7 @@ type RemovePolicyRequest struct {
 
 func (x *RemovePolicyRequest) Reset() {
 	*x = RemovePolicyRequest{}
-	mi := &file_objstore_proto_msgTypes[23]
+	mi := &file_objstore_proto_msgTypes[30]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1511,7 +2023,7 @@ func (x *RemovePolicyRequest) String() string {
 func (*RemovePolicyRequest) ProtoMessage() {}
 
 func (x *RemovePolicyRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_objstore_proto_msgTypes[23]
+	mi := &file_objstore_proto_msgTypes[30]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1524,7 +2036,7 @@ func (x *RemovePolicyRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RemovePolicyRequest.ProtoReflect.Descriptor instead.
 func (*RemovePolicyRequest) Descriptor() ([]byte, []int) {
-	return file_objstore_proto_rawDescGZIP(), []int{23}
+	return file_objstore_proto_rawDescGZIP(), []int{30}
 }
 
 func (x *RemovePolicyRequest) GetId() string {
@@ -1547,7 +2059,7 @@ type RemovePolicyResponse struct {
 
 func (x *RemovePolicyResponse) Reset() {
 	*x = RemovePolicyResponse{}
-	mi := &file_objstore_proto_msgTypes[24]
+	mi := &file_objstore_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1559,7 +2071,7 @@ func (x *RemovePolicyResponse) String() string {
 func (*RemovePolicyResponse) ProtoMessage() {}
 
 func (x *RemovePolicyResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_objstore_proto_msgTypes[24]
+	mi := &file_objstore_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1572,7 +2084,7 @@ func (x *RemovePolicyResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RemovePolicyResponse.ProtoReflect.Descriptor instead.
 func (*RemovePolicyResponse) Descriptor() ([]byte, []int) {
-	return file_objstore_proto_rawDescGZIP(), []int{24}
+	return file_objstore_proto_rawDescGZIP(), []int{31}
 }
 
 func (x *RemovePolicyResponse) GetSuccess() bool {
@@ -1600,7 +2112,7 @@ type GetPoliciesRequest struct {
 
 func (x *GetPoliciesRequest) Reset() {
 	*x = GetPoliciesRequest{}
-	mi := &file_objstore_proto_msgTypes[25]
+	mi := &file_objstore_proto_msgTypes[32]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1612,7 +2124,7 @@ func (x *GetPoliciesRequest) String() string {
 func (*GetPoliciesRequest) ProtoMessage() {}
 
 func (x *GetPoliciesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_objstore_proto_msgTypes[25]
+	mi := &file_objstore_proto_msgTypes[32]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1625,7 +2137,7 @@ func (x *GetPoliciesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetPoliciesRequest.ProtoReflect.Descriptor instead.
 func (*GetPoliciesRequest) Descriptor() ([]byte, []int) {
-	return file_objstore_proto_rawDescGZIP(), []int{25}
+	return file_objstore_proto_rawDescGZIP(), []int{32}
 }
 
 func (x *GetPoliciesRequest) GetPrefix() string {
@@ -1650,7 +2162,7 @@ type GetPoliciesResponse struct {
 
 func (x *GetPoliciesResponse) Reset() {
 	*x = GetPoliciesResponse{}
-	mi := &file_objstore_proto_msgTypes[26]
+	mi := &file_objstore_proto_msgTypes[33]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1662,7 +2174,7 @@ func (x *GetPoliciesResponse) String() string {
 func (*GetPoliciesResponse) ProtoMessage() {}
 
 func (x *GetPoliciesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_objstore_proto_msgTypes[26]
+	mi := &file_objstore_proto_msgTypes[33]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1675,7 +2187,7 @@ func (x *GetPoliciesResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetPoliciesResponse.ProtoReflect.Descriptor instead.
 func (*GetPoliciesResponse) Descriptor() ([]byte, []int) {
-	return file_objstore_proto_rawDescGZIP(), []int{26}
+	return file_objstore_proto_rawDescGZIP(), []int{33}
 }
 
 func (x *GetPoliciesResponse) GetPolicies() []*LifecyclePolicy {
@@ -1708,7 +2220,7 @@ type ApplyPoliciesRequest struct {
 
 func (x *ApplyPoliciesRequest) Reset() {
 	*x = ApplyPoliciesRequest{}
-	mi := &file_objstore_proto_msgTypes[27]
+	mi := &file_objstore_proto_msgTypes[34]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1720,7 +2232,7 @@ func (x *ApplyPoliciesRequest) String() string {
 func (*ApplyPoliciesRequest) ProtoMessage() {}
 
 func (x *ApplyPoliciesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_objstore_proto_msgTypes[27]
+	mi := &file_objstore_proto_msgTypes[34]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1733,7 +2245,7 @@ func (x *ApplyPoliciesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ApplyPoliciesRequest.ProtoReflect.Descriptor instead.
 func (*ApplyPoliciesRequest) Descriptor() ([]byte, []int) {
-	return file_objstore_proto_rawDescGZIP(), []int{27}
+	return file_objstore_proto_rawDescGZIP(), []int{34}
 }
 
 // ApplyPoliciesResponse represents the response from an ApplyPolicies operation.
@@ -1753,7 +2265,7 @@ type ApplyPoliciesResponse struct {
 
 func (x *ApplyPoliciesResponse) Reset() {
 	*x = ApplyPoliciesResponse{}
-	mi := &file_objstore_proto_msgTypes[28]
+	mi := &file_objstore_proto_msgTypes[35]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1765,7 +2277,7 @@ func (x *ApplyPoliciesResponse) String() string {
 func (*ApplyPoliciesResponse) ProtoMessage() {}
 
 func (x *ApplyPoliciesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_objstore_proto_msgTypes[28]
+	mi := &file_objstore_proto_msgTypes[35]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1778,7 +2290,7 @@ func (x *ApplyPoliciesResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ApplyPoliciesResponse.ProtoReflect.Descriptor instead.
 func (*ApplyPoliciesResponse) Descriptor() ([]byte, []int) {
-	return file_objstore_proto_rawDescGZIP(), []int{28}
+	return file_objstore_proto_rawDescGZIP(), []int{35}
 }
 
 func (x *ApplyPoliciesResponse) GetSuccess() bool {
@@ -1824,7 +2336,7 @@ type EncryptionConfig struct {
 
 func (x *EncryptionConfig) Reset() {
 	*x = EncryptionConfig{}
-	mi := &file_objstore_proto_msgTypes[29]
+	mi := &file_objstore_proto_msgTypes[36]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1836,7 +2348,7 @@ func (x *EncryptionConfig) String() string {
 func (*EncryptionConfig) ProtoMessage() {}
 
 func (x *EncryptionConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_objstore_proto_msgTypes[29]
+	mi := &file_objstore_proto_msgTypes[36]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1849,7 +2361,7 @@ func (x *EncryptionConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EncryptionConfig.ProtoReflect.Descriptor instead.
 func (*EncryptionConfig) Descriptor() ([]byte, []int) {
-	return file_objstore_proto_rawDescGZIP(), []int{29}
+	return file_objstore_proto_rawDescGZIP(), []int{36}
 }
 
 func (x *EncryptionConfig) GetEnabled() bool {
@@ -1888,7 +2400,7 @@ type EncryptionPolicy struct {
 
 func (x *EncryptionPolicy) Reset() {
 	*x = EncryptionPolicy{}
-	mi := &file_objstore_proto_msgTypes[30]
+	mi := &file_objstore_proto_msgTypes[37]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1900,7 +2412,7 @@ func (x *EncryptionPolicy) String() string {
 func (*EncryptionPolicy) ProtoMessage() {}
 
 func (x *EncryptionPolicy) ProtoReflect() protoreflect.Message {
-	mi := &file_objstore_proto_msgTypes[30]
+	mi := &file_objstore_proto_msgTypes[37]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1913,7 +2425,7 @@ func (x *EncryptionPolicy) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EncryptionPolicy.ProtoReflect.Descriptor instead.
 func (*EncryptionPolicy) Descriptor() ([]byte, []int) {
-	return file_objstore_proto_rawDescGZIP(), []int{30}
+	return file_objstore_proto_rawDescGZIP(), []int{37}
 }
 
 func (x *EncryptionPolicy) GetBackend() *EncryptionConfig {
@@ -1968,7 +2480,7 @@ type ReplicationPolicy struct {
 
 func (x *ReplicationPolicy) Reset() {
 	*x = ReplicationPolicy{}
-	mi := &file_objstore_proto_msgTypes[31]
+	mi := &file_objstore_proto_msgTypes[38]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1980,7 +2492,7 @@ func (x *ReplicationPolicy) String() string {
 func (*ReplicationPolicy) ProtoMessage() {}
 
 func (x *ReplicationPolicy) ProtoReflect() protoreflect.Message {
-	mi := &file_objstore_proto_msgTypes[31]
+	mi := &file_objstore_proto_msgTypes[38]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1993,7 +2505,7 @@ func (x *ReplicationPolicy) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReplicationPolicy.ProtoReflect.Descriptor instead.
 func (*ReplicationPolicy) Descriptor() ([]byte, []int) {
-	return file_objstore_proto_rawDescGZIP(), []int{31}
+	return file_objstore_proto_rawDescGZIP(), []int{38}
 }
 
 func (x *ReplicationPolicy) GetId() string {
@@ -2084,7 +2596,7 @@ type AddReplicationPolicyRequest struct {
 
 func (x *AddReplicationPolicyRequest) Reset() {
 	*x = AddReplicationPolicyRequest{}
-	mi := &file_objstore_proto_msgTypes[32]
+	mi := &file_objstore_proto_msgTypes[39]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2096,7 +2608,7 @@ func (x *AddReplicationPolicyRequest) String() string {
 func (*AddReplicationPolicyRequest) ProtoMessage() {}
 
 func (x *AddReplicationPolicyRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_objstore_proto_msgTypes[32]
+	mi := &file_objstore_proto_msgTypes[39]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2109,7 +2621,7 @@ func (x *AddReplicationPolicyRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AddReplicationPolicyRequest.ProtoReflect.Descriptor instead.
 func (*AddReplicationPolicyRequest) Descriptor() ([]byte, []int) {
-	return file_objstore_proto_rawDescGZIP(), []int{32}
+	return file_objstore_proto_rawDescGZIP(), []int{39}
 }
 
 func (x *AddReplicationPolicyRequest) GetPolicy() *ReplicationPolicy {
@@ -2132,7 +2644,7 @@ type AddReplicationPolicyResponse struct {
 
 func (x *AddReplicationPolicyResponse) Reset() {
 	*x = AddReplicationPolicyResponse{}
-	mi := &file_objstore_proto_msgTypes[33]
+	mi := &file_objstore_proto_msgTypes[40]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2144,7 +2656,7 @@ func (x *AddReplicationPolicyResponse) String() string {
 func (*AddReplicationPolicyResponse) ProtoMessage() {}
 
 func (x *AddReplicationPolicyResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_objstore_proto_msgTypes[33]
+	mi := &file_objstore_proto_msgTypes[40]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2157,7 +2669,7 @@ func (x *AddReplicationPolicyResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AddReplicationPolicyResponse.ProtoReflect.Descriptor instead.
 func (*AddReplicationPolicyResponse) Descriptor() ([]byte, []int) {
-	return file_objstore_proto_rawDescGZIP(), []int{33}
+	return file_objstore_proto_rawDescGZIP(), []int{40}
 }
 
 func (x *AddReplicationPolicyResponse) GetSuccess() bool {
@@ -2185,7 +2697,7 @@ type RemoveReplicationPolicyRequest struct {
 
 func (x *RemoveReplicationPolicyRequest) Reset() {
 	*x = RemoveReplicationPolicyRequest{}
-	mi := &file_objstore_proto_msgTypes[34]
+	mi := &file_objstore_proto_msgTypes[41]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2197,7 +2709,7 @@ func (x *RemoveReplicationPolicyRequest) String() string {
 func (*RemoveReplicationPolicyRequest) ProtoMessage() {}
 
 func (x *RemoveReplicationPolicyRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_objstore_proto_msgTypes[34]
+	mi := &file_objstore_proto_msgTypes[41]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2210,7 +2722,7 @@ func (x *RemoveReplicationPolicyRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RemoveReplicationPolicyRequest.ProtoReflect.Descriptor instead.
 func (*RemoveReplicationPolicyRequest) Descriptor() ([]byte, []int) {
-	return file_objstore_proto_rawDescGZIP(), []int{34}
+	return file_objstore_proto_rawDescGZIP(), []int{41}
 }
 
 func (x *RemoveReplicationPolicyRequest) GetId() string {
@@ -2233,7 +2745,7 @@ type RemoveReplicationPolicyResponse struct {
 
 func (x *RemoveReplicationPolicyResponse) Reset() {
 	*x = RemoveReplicationPolicyResponse{}
-	mi := &file_objstore_proto_msgTypes[35]
+	mi := &file_objstore_proto_msgTypes[42]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2245,7 +2757,7 @@ func (x *RemoveReplicationPolicyResponse) String() string {
 func (*RemoveReplicationPolicyResponse) ProtoMessage() {}
 
 func (x *RemoveReplicationPolicyResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_objstore_proto_msgTypes[35]
+	mi := &file_objstore_proto_msgTypes[42]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2258,7 +2770,7 @@ func (x *RemoveReplicationPolicyResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RemoveReplicationPolicyResponse.ProtoReflect.Descriptor instead.
 func (*RemoveReplicationPolicyResponse) Descriptor() ([]byte, []int) {
-	return file_objstore_proto_rawDescGZIP(), []int{35}
+	return file_objstore_proto_rawDescGZIP(), []int{42}
 }
 
 func (x *RemoveReplicationPolicyResponse) GetSuccess() bool {
@@ -2284,7 +2796,7 @@ type GetReplicationPoliciesRequest struct {
 
 func (x *GetReplicationPoliciesRequest) Reset() {
 	*x = GetReplicationPoliciesRequest{}
-	mi := &file_objstore_proto_msgTypes[36]
+	mi := &file_objstore_proto_msgTypes[43]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2296,7 +2808,7 @@ func (x *GetReplicationPoliciesRequest) String() string {
 func (*GetReplicationPoliciesRequest) ProtoMessage() {}
 
 func (x *GetReplicationPoliciesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_objstore_proto_msgTypes[36]
+	mi := &file_objstore_proto_msgTypes[43]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2309,7 +2821,7 @@ func (x *GetReplicationPoliciesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetReplicationPoliciesRequest.ProtoReflect.Descriptor instead.
 func (*GetReplicationPoliciesRequest) Descriptor() ([]byte, []int) {
-	return file_objstore_proto_rawDescGZIP(), []int{36}
+	return file_objstore_proto_rawDescGZIP(), []int{43}
 }
 
 // GetReplicationPoliciesResponse represents the response from a GetReplicationPolicies operation.
@@ -2323,7 +2835,7 @@ type GetReplicationPoliciesResponse struct {
 
 func (x *GetReplicationPoliciesResponse) Reset() {
 	*x = GetReplicationPoliciesResponse{}
-	mi := &file_objstore_proto_msgTypes[37]
+	mi := &file_objstore_proto_msgTypes[44]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2335,7 +2847,7 @@ func (x *GetReplicationPoliciesResponse) String() string {
 func (*GetReplicationPoliciesResponse) ProtoMessage() {}
 
 func (x *GetReplicationPoliciesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_objstore_proto_msgTypes[37]
+	mi := &file_objstore_proto_msgTypes[44]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2348,7 +2860,7 @@ func (x *GetReplicationPoliciesResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetReplicationPoliciesResponse.ProtoReflect.Descriptor instead.
 func (*GetReplicationPoliciesResponse) Descriptor() ([]byte, []int) {
-	return file_objstore_proto_rawDescGZIP(), []int{37}
+	return file_objstore_proto_rawDescGZIP(), []int{44}
 }
 
 func (x *GetReplicationPoliciesResponse) GetPolicies() []*ReplicationPolicy {
@@ -2369,7 +2881,7 @@ type GetReplicationPolicyRequest struct {
 
 func (x *GetReplicationPolicyRequest) Reset() {
 	*x = GetReplicationPolicyRequest{}
-	mi := &file_objstore_proto_msgTypes[38]
+	mi := &file_objstore_proto_msgTypes[45]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2381,7 +2893,7 @@ func (x *GetReplicationPolicyRequest) String() string {
 func (*GetReplicationPolicyRequest) ProtoMessage() {}
 
 func (x *GetReplicationPolicyRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_objstore_proto_msgTypes[38]
+	mi := &file_objstore_proto_msgTypes[45]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2394,7 +2906,7 @@ func (x *GetReplicationPolicyRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetReplicationPolicyRequest.ProtoReflect.Descriptor instead.
 func (*GetReplicationPolicyRequest) Descriptor() ([]byte, []int) {
-	return file_objstore_proto_rawDescGZIP(), []int{38}
+	return file_objstore_proto_rawDescGZIP(), []int{45}
 }
 
 func (x *GetReplicationPolicyRequest) GetId() string {
@@ -2415,7 +2927,7 @@ type GetReplicationPolicyResponse struct {
 
 func (x *GetReplicationPolicyResponse) Reset() {
 	*x = GetReplicationPolicyResponse{}
-	mi := &file_objstore_proto_msgTypes[39]
+	mi := &file_objstore_proto_msgTypes[46]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2427,7 +2939,7 @@ func (x *GetReplicationPolicyResponse) String() string {
 func (*GetReplicationPolicyResponse) ProtoMessage() {}
 
 func (x *GetReplicationPolicyResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_objstore_proto_msgTypes[39]
+	mi := &file_objstore_proto_msgTypes[46]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2440,7 +2952,7 @@ func (x *GetReplicationPolicyResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetReplicationPolicyResponse.ProtoReflect.Descriptor instead.
 func (*GetReplicationPolicyResponse) Descriptor() ([]byte, []int) {
-	return file_objstore_proto_rawDescGZIP(), []int{39}
+	return file_objstore_proto_rawDescGZIP(), []int{46}
 }
 
 func (x *GetReplicationPolicyResponse) GetPolicy() *ReplicationPolicy {
@@ -2465,7 +2977,7 @@ type TriggerReplicationRequest struct {
 
 func (x *TriggerReplicationRequest) Reset() {
 	*x = TriggerReplicationRequest{}
-	mi := &file_objstore_proto_msgTypes[40]
+	mi := &file_objstore_proto_msgTypes[47]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2477,7 +2989,7 @@ func (x *TriggerReplicationRequest) String() string {
 func (*TriggerReplicationRequest) ProtoMessage() {}
 
 func (x *TriggerReplicationRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_objstore_proto_msgTypes[40]
+	mi := &file_objstore_proto_msgTypes[47]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2490,7 +3002,7 @@ func (x *TriggerReplicationRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TriggerReplicationRequest.ProtoReflect.Descriptor instead.
 func (*TriggerReplicationRequest) Descriptor() ([]byte, []int) {
-	return file_objstore_proto_rawDescGZIP(), []int{40}
+	return file_objstore_proto_rawDescGZIP(), []int{47}
 }
 
 func (x *TriggerReplicationRequest) GetPolicyId() string {
@@ -2537,7 +3049,7 @@ type SyncResult struct {
 
 func (x *SyncResult) Reset() {
 	*x = SyncResult{}
-	mi := &file_objstore_proto_msgTypes[41]
+	mi := &file_objstore_proto_msgTypes[48]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2549,7 +3061,7 @@ func (x *SyncResult) String() string {
 func (*SyncResult) ProtoMessage() {}
 
 func (x *SyncResult) ProtoReflect() protoreflect.Message {
-	mi := &file_objstore_proto_msgTypes[41]
+	mi := &file_objstore_proto_msgTypes[48]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2562,7 +3074,7 @@ func (x *SyncResult) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SyncResult.ProtoReflect.Descriptor instead.
 func (*SyncResult) Descriptor() ([]byte, []int) {
-	return file_objstore_proto_rawDescGZIP(), []int{41}
+	return file_objstore_proto_rawDescGZIP(), []int{48}
 }
 
 func (x *SyncResult) GetPolicyId() string {
@@ -2629,7 +3141,7 @@ type TriggerReplicationResponse struct {
 
 func (x *TriggerReplicationResponse) Reset() {
 	*x = TriggerReplicationResponse{}
-	mi := &file_objstore_proto_msgTypes[42]
+	mi := &file_objstore_proto_msgTypes[49]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2641,7 +3153,7 @@ func (x *TriggerReplicationResponse) String() string {
 func (*TriggerReplicationResponse) ProtoMessage() {}
 
 func (x *TriggerReplicationResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_objstore_proto_msgTypes[42]
+	mi := &file_objstore_proto_msgTypes[49]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2654,7 +3166,7 @@ func (x *TriggerReplicationResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TriggerReplicationResponse.ProtoReflect.Descriptor instead.
 func (*TriggerReplicationResponse) Descriptor() ([]byte, []int) {
-	return file_objstore_proto_rawDescGZIP(), []int{42}
+	return file_objstore_proto_rawDescGZIP(), []int{49}
 }
 
 func (x *TriggerReplicationResponse) GetSuccess() bool {
@@ -2689,7 +3201,7 @@ type GetReplicationStatusRequest struct {
 
 func (x *GetReplicationStatusRequest) Reset() {
 	*x = GetReplicationStatusRequest{}
-	mi := &file_objstore_proto_msgTypes[43]
+	mi := &file_objstore_proto_msgTypes[50]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2701,7 +3213,7 @@ func (x *GetReplicationStatusRequest) String() string {
 func (*GetReplicationStatusRequest) ProtoMessage() {}
 
 func (x *GetReplicationStatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_objstore_proto_msgTypes[43]
+	mi := &file_objstore_proto_msgTypes[50]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2714,7 +3226,7 @@ func (x *GetReplicationStatusRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetReplicationStatusRequest.ProtoReflect.Descriptor instead.
 func (*GetReplicationStatusRequest) Descriptor() ([]byte, []int) {
-	return file_objstore_proto_rawDescGZIP(), []int{43}
+	return file_objstore_proto_rawDescGZIP(), []int{50}
 }
 
 func (x *GetReplicationStatusRequest) GetId() string {
@@ -2755,7 +3267,7 @@ type ReplicationStatus struct {
 
 func (x *ReplicationStatus) Reset() {
 	*x = ReplicationStatus{}
-	mi := &file_objstore_proto_msgTypes[44]
+	mi := &file_objstore_proto_msgTypes[51]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2767,7 +3279,7 @@ func (x *ReplicationStatus) String() string {
 func (*ReplicationStatus) ProtoMessage() {}
 
 func (x *ReplicationStatus) ProtoReflect() protoreflect.Message {
-	mi := &file_objstore_proto_msgTypes[44]
+	mi := &file_objstore_proto_msgTypes[51]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2780,7 +3292,7 @@ func (x *ReplicationStatus) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReplicationStatus.ProtoReflect.Descriptor instead.
 func (*ReplicationStatus) Descriptor() ([]byte, []int) {
-	return file_objstore_proto_rawDescGZIP(), []int{44}
+	return file_objstore_proto_rawDescGZIP(), []int{51}
 }
 
 func (x *ReplicationStatus) GetPolicyId() string {
@@ -2875,7 +3387,7 @@ type GetReplicationStatusResponse struct {
 
 func (x *GetReplicationStatusResponse) Reset() {
 	*x = GetReplicationStatusResponse{}
-	mi := &file_objstore_proto_msgTypes[45]
+	mi := &file_objstore_proto_msgTypes[52]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2887,7 +3399,7 @@ func (x *GetReplicationStatusResponse) String() string {
 func (*GetReplicationStatusResponse) ProtoMessage() {}
 
 func (x *GetReplicationStatusResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_objstore_proto_msgTypes[45]
+	mi := &file_objstore_proto_msgTypes[52]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2900,7 +3412,7 @@ func (x *GetReplicationStatusResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetReplicationStatusResponse.ProtoReflect.Descriptor instead.
 func (*GetReplicationStatusResponse) Descriptor() ([]byte, []int) {
-	return file_objstore_proto_rawDescGZIP(), []int{45}
+	return file_objstore_proto_rawDescGZIP(), []int{52}
 }
 
 func (x *GetReplicationStatusResponse) GetSuccess() bool {
@@ -2947,11 +3459,14 @@ const file_objstore_proto_rawDesc = "" +
 	"PutRequest\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x12\n" +
 	"\x04data\x18\x02 \x01(\fR\x04data\x121\n" +
-	"\bmetadata\x18\x03 \x01(\v2\x15.objstore.v1.MetadataR\bmetadata\"U\n" +
+	"\bmetadata\x18\x03 \x01(\v2\x15.objstore.v1.MetadataR\bmetadata\"\xc4\x01\n" +
 	"\vPutResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12\x12\n" +
-	"\x04etag\x18\x03 \x01(\tR\x04etag\"\x1e\n" +
+	"\x04etag\x18\x03 \x01(\tR\x04etag\x12\x18\n" +
+	"\aversion\x18\x04 \x01(\tR\aversion\x12\x12\n" +
+	"\x04size\x18\x05 \x01(\x03R\x04size\x12?\n" +
+	"\rlast_modified\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\flastModified\"\x1e\n" +
 	"\n" +
 	"GetRequest\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\"m\n" +
@@ -2993,10 +3508,13 @@ const file_objstore_proto_rawDesc = "" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\")\n" +
 	"\rHealthRequest\x12\x18\n" +
-	"\aservice\x18\x01 \x01(\tR\aservice\"\x9b\x01\n" +
+	"\aservice\x18\x01 \x01(\tR\aservice\"\xe3\x01\n" +
 	"\x0eHealthResponse\x12:\n" +
 	"\x06status\x18\x01 \x01(\x0e2\".objstore.v1.HealthResponse.StatusR\x06status\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\"3\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1f\n" +
+	"\vapi_version\x18\x03 \x01(\x05R\n" +
+	"apiVersion\x12%\n" +
+	"\x0eserver_version\x18\x04 \x01(\tR\rserverVersion\"3\n" +
 	"\x06Status\x12\v\n" +
 	"\aUNKNOWN\x10\x00\x12\v\n" +
 	"\aSERVING\x10\x01\x12\x0f\n" +
@@ -3010,7 +3528,41 @@ const file_objstore_proto_rawDesc = "" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"E\n" +
 	"\x0fArchiveResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\"\xdb\x02\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\x95\x02\n" +
+	"\x19RestoreFromArchiveRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x1f\n" +
+	"\vsource_type\x18\x02 \x01(\tR\n" +
+	"sourceType\x12c\n" +
+	"\x0fsource_settings\x18\x03 \x03(\v2:.objstore.v1.RestoreFromArchiveRequest.SourceSettingsEntryR\x0esourceSettings\x12\x1d\n" +
+	"\n" +
+	"source_key\x18\x04 \x01(\tR\tsourceKey\x1aA\n" +
+	"\x13SourceSettingsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"d\n" +
+	"\x1aRestoreFromArchiveResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x12\n" +
+	"\x04size\x18\x03 \x01(\x03R\x04size\";\n" +
+	"\rSearchRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"C\n" +
+	"\x0eSearchResponse\x121\n" +
+	"\aobjects\x18\x01 \x03(\v2\x17.objstore.v1.ObjectInfoR\aobjects\"?\n" +
+	"\x11GetChangesRequest\x12\x14\n" +
+	"\x05since\x18\x01 \x01(\x04R\x05since\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"\xa8\x01\n" +
+	"\x06Change\x12\x1a\n" +
+	"\bsequence\x18\x01 \x01(\x04R\bsequence\x12\x0e\n" +
+	"\x02op\x18\x02 \x01(\tR\x02op\x12\x10\n" +
+	"\x03key\x18\x03 \x01(\tR\x03key\x12\x12\n" +
+	"\x04etag\x18\x04 \x01(\tR\x04etag\x12\x12\n" +
+	"\x04size\x18\x05 \x01(\x03R\x04size\x128\n" +
+	"\ttimestamp\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"\x83\x01\n" +
+	"\x12GetChangesResponse\x12-\n" +
+	"\achanges\x18\x01 \x03(\v2\x13.objstore.v1.ChangeR\achanges\x12\x12\n" +
+	"\x04next\x18\x02 \x01(\x04R\x04next\x12\x16\n" +
+	"\x06latest\x18\x03 \x01(\x04R\x06latest\x12\x12\n" +
+	"\x04more\x18\x04 \x01(\bR\x04more\"\xdb\x02\n" +
 	"\x0fLifecyclePolicy\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
 	"\x06prefix\x18\x02 \x01(\tR\x06prefix\x12+\n" +
@@ -3132,7 +3684,7 @@ const file_objstore_proto_rawDesc = "" +
 	"\x0fReplicationMode\x12\x0f\n" +
 	"\vTRANSPARENT\x10\x00\x12\n" +
 	"\n" +
-	"\x06OPAQUE\x10\x012\xdb\f\n" +
+	"\x06OPAQUE\x10\x012\xd4\x0e\n" +
 	"\vObjectStore\x128\n" +
 	"\x03Put\x12\x17.objstore.v1.PutRequest\x1a\x18.objstore.v1.PutResponse\x12:\n" +
 	"\x03Get\x12\x17.objstore.v1.GetRequest\x1a\x18.objstore.v1.GetResponse0\x01\x12A\n" +
@@ -3142,7 +3694,11 @@ const file_objstore_proto_rawDesc = "" +
 	"\vGetMetadata\x12\x1f.objstore.v1.GetMetadataRequest\x1a\x1d.objstore.v1.MetadataResponse\x12Y\n" +
 	"\x0eUpdateMetadata\x12\".objstore.v1.UpdateMetadataRequest\x1a#.objstore.v1.UpdateMetadataResponse\x12A\n" +
 	"\x06Health\x12\x1a.objstore.v1.HealthRequest\x1a\x1b.objstore.v1.HealthResponse\x12D\n" +
-	"\aArchive\x12\x1b.objstore.v1.ArchiveRequest\x1a\x1c.objstore.v1.ArchiveResponse\x12J\n" +
+	"\aArchive\x12\x1b.objstore.v1.ArchiveRequest\x1a\x1c.objstore.v1.ArchiveResponse\x12e\n" +
+	"\x12RestoreFromArchive\x12&.objstore.v1.RestoreFromArchiveRequest\x1a'.objstore.v1.RestoreFromArchiveResponse\x12A\n" +
+	"\x06Search\x12\x1a.objstore.v1.SearchRequest\x1a\x1b.objstore.v1.SearchResponse\x12M\n" +
+	"\n" +
+	"GetChanges\x12\x1e.objstore.v1.GetChangesRequest\x1a\x1f.objstore.v1.GetChangesResponse\x12J\n" +
 	"\tAddPolicy\x12\x1d.objstore.v1.AddPolicyRequest\x1a\x1e.objstore.v1.AddPolicyResponse\x12S\n" +
 	"\fRemovePolicy\x12 .objstore.v1.RemovePolicyRequest\x1a!.objstore.v1.RemovePolicyResponse\x12P\n" +
 	"\vGetPolicies\x12\x1f.objstore.v1.GetPoliciesRequest\x1a .objstore.v1.GetPoliciesResponse\x12V\n" +
@@ -3167,7 +3723,7 @@ func file_objstore_proto_rawDescGZIP() []byte {
 }
 
 var file_objstore_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_objstore_proto_msgTypes = make([]protoimpl.MessageInfo, 51)
+var file_objstore_proto_msgTypes = make([]protoimpl.MessageInfo, 59)
 var file_objstore_proto_goTypes = []any{
 	(ReplicationMode)(0),                    // 0: objstore.v1.ReplicationMode
 	(HealthResponse_Status)(0),              // 1: objstore.v1.HealthResponse.Status
@@ -3191,110 +3747,129 @@ var file_objstore_proto_goTypes = []any{
 	(*HealthResponse)(nil),                  // 19: objstore.v1.HealthResponse
 	(*ArchiveRequest)(nil),                  // 20: objstore.v1.ArchiveRequest
 	(*ArchiveResponse)(nil),                 // 21: objstore.v1.ArchiveResponse
-	(*LifecyclePolicy)(nil),                 // 22: objstore.v1.LifecyclePolicy
-	(*AddPolicyRequest)(nil),                // 23: objstore.v1.AddPolicyRequest
-	(*AddPolicyResponse)(nil),               // 24: objstore.v1.AddPolicyResponse
-	(*RemovePolicyRequest)(nil),             // 25: objstore.v1.RemovePolicyRequest
-	(*RemovePolicyResponse)(nil),            // 26: objstore.v1.RemovePolicyResponse
-	(*GetPoliciesRequest)(nil),              // 27: objstore.v1.GetPoliciesRequest
-	(*GetPoliciesResponse)(nil),             // 28: objstore.v1.GetPoliciesResponse
-	(*ApplyPoliciesRequest)(nil),            // 29: objstore.v1.ApplyPoliciesRequest
-	(*ApplyPoliciesResponse)(nil),           // 30: objstore.v1.ApplyPoliciesResponse
-	(*EncryptionConfig)(nil),                // 31: objstore.v1.EncryptionConfig
-	(*EncryptionPolicy)(nil),                // 32: objstore.v1.EncryptionPolicy
-	(*ReplicationPolicy)(nil),               // 33: objstore.v1.ReplicationPolicy
-	(*AddReplicationPolicyRequest)(nil),     // 34: objstore.v1.AddReplicationPolicyRequest
-	(*AddReplicationPolicyResponse)(nil),    // 35: objstore.v1.AddReplicationPolicyResponse
-	(*RemoveReplicationPolicyRequest)(nil),  // 36: objstore.v1.RemoveReplicationPolicyRequest
-	(*RemoveReplicationPolicyResponse)(nil), // 37: objstore.v1.RemoveReplicationPolicyResponse
-	(*GetReplicationPoliciesRequest)(nil),   // 38: objstore.v1.GetReplicationPoliciesRequest
-	(*GetReplicationPoliciesResponse)(nil),  // 39: objstore.v1.GetReplicationPoliciesResponse
-	(*GetReplicationPolicyRequest)(nil),     // 40: objstore.v1.GetReplicationPolicyRequest
-	(*GetReplicationPolicyResponse)(nil),    // 41: objstore.v1.GetReplicationPolicyResponse
-	(*TriggerReplicationRequest)(nil),       // 42: objstore.v1.TriggerReplicationRequest
-	(*SyncResult)(nil),                      // 43: objstore.v1.SyncResult
-	(*TriggerReplicationResponse)(nil),      // 44: objstore.v1.TriggerReplicationResponse
-	(*GetReplicationStatusRequest)(nil),     // 45: objstore.v1.GetReplicationStatusRequest
-	(*ReplicationStatus)(nil),               // 46: objstore.v1.ReplicationStatus
-	(*GetReplicationStatusResponse)(nil),    // 47: objstore.v1.GetReplicationStatusResponse
-	nil,                                     // 48: objstore.v1.Metadata.CustomEntry
-	nil,                                     // 49: objstore.v1.ArchiveRequest.DestinationSettingsEntry
-	nil,                                     // 50: objstore.v1.LifecyclePolicy.DestinationSettingsEntry
-	nil,                                     // 51: objstore.v1.ReplicationPolicy.SourceSettingsEntry
-	nil,                                     // 52: objstore.v1.ReplicationPolicy.DestinationSettingsEntry
-	(*timestamppb.Timestamp)(nil),           // 53: google.protobuf.Timestamp
+	(*RestoreFromArchiveRequest)(nil),       // 22: objstore.v1.RestoreFromArchiveRequest
+	(*RestoreFromArchiveResponse)(nil),      // 23: objstore.v1.RestoreFromArchiveResponse
+	(*SearchRequest)(nil),                   // 24: objstore.v1.SearchRequest
+	(*SearchResponse)(nil),                  // 25: objstore.v1.SearchResponse
+	(*GetChangesRequest)(nil),               // 26: objstore.v1.GetChangesRequest
+	(*Change)(nil),                          // 27: objstore.v1.Change
+	(*GetChangesResponse)(nil),              // 28: objstore.v1.GetChangesResponse
+	(*LifecyclePolicy)(nil),                 // 29: objstore.v1.LifecyclePolicy
+	(*AddPolicyRequest)(nil),                // 30: objstore.v1.AddPolicyRequest
+	(*AddPolicyResponse)(nil),               // 31: objstore.v1.AddPolicyResponse
+	(*RemovePolicyRequest)(nil),             // 32: objstore.v1.RemovePolicyRequest
+	(*RemovePolicyResponse)(nil),            // 33: objstore.v1.RemovePolicyResponse
+	(*GetPoliciesRequest)(nil),              // 34: objstore.v1.GetPoliciesRequest
+	(*GetPoliciesResponse)(nil),             // 35: objstore.v1.GetPoliciesResponse
+	(*ApplyPoliciesRequest)(nil),            // 36: objstore.v1.ApplyPoliciesRequest
+	(*ApplyPoliciesResponse)(nil),           // 37: objstore.v1.ApplyPoliciesResponse
+	(*EncryptionConfig)(nil),                // 38: objstore.v1.EncryptionConfig
+	(*EncryptionPolicy)(nil),                // 39: objstore.v1.EncryptionPolicy
+	(*ReplicationPolicy)(nil),               // 40: objstore.v1.ReplicationPolicy
+	(*AddReplicationPolicyRequest)(nil),     // 41: objstore.v1.AddReplicationPolicyRequest
+	(*AddReplicationPolicyResponse)(nil),    // 42: objstore.v1.AddReplicationPolicyResponse
+	(*RemoveReplicationPolicyRequest)(nil),  // 43: objstore.v1.RemoveReplicationPolicyRequest
+	(*RemoveReplicationPolicyResponse)(nil), // 44: objstore.v1.RemoveReplicationPolicyResponse
+	(*GetReplicationPoliciesRequest)(nil),   // 45: objstore.v1.GetReplicationPoliciesRequest
+	(*GetReplicationPoliciesResponse)(nil),  // 46: objstore.v1.GetReplicationPoliciesResponse
+	(*GetReplicationPolicyRequest)(nil),     // 47: objstore.v1.GetReplicationPolicyRequest
+	(*GetReplicationPolicyResponse)(nil),    // 48: objstore.v1.GetReplicationPolicyResponse
+	(*TriggerReplicationRequest)(nil),       // 49: objstore.v1.TriggerReplicationRequest
+	(*SyncResult)(nil),                      // 50: objstore.v1.SyncResult
+	(*TriggerReplicationResponse)(nil),      // 51: objstore.v1.TriggerReplicationResponse
+	(*GetReplicationStatusRequest)(nil),     // 52: objstore.v1.GetReplicationStatusRequest
+	(*ReplicationStatus)(nil),               // 53: objstore.v1.ReplicationStatus
+	(*GetReplicationStatusResponse)(nil),    // 54: objstore.v1.GetReplicationStatusResponse
+	nil,                                     // 55: objstore.v1.Metadata.CustomEntry
+	nil,                                     // 56: objstore.v1.ArchiveRequest.DestinationSettingsEntry
+	nil,                                     // 57: objstore.v1.RestoreFromArchiveRequest.SourceSettingsEntry
+	nil,                                     // 58: objstore.v1.LifecyclePolicy.DestinationSettingsEntry
+	nil,                                     // 59: objstore.v1.ReplicationPolicy.SourceSettingsEntry
+	nil,                                     // 60: objstore.v1.ReplicationPolicy.DestinationSettingsEntry
+	(*timestamppb.Timestamp)(nil),           // 61: google.protobuf.Timestamp
 }
 var file_objstore_proto_depIdxs = []int32{
-	53, // 0: objstore.v1.Metadata.last_modified:type_name -> google.protobuf.Timestamp
-	48, // 1: objstore.v1.Metadata.custom:type_name -> objstore.v1.Metadata.CustomEntry
+	61, // 0: objstore.v1.Metadata.last_modified:type_name -> google.protobuf.Timestamp
+	55, // 1: objstore.v1.Metadata.custom:type_name -> objstore.v1.Metadata.CustomEntry
 	2,  // 2: objstore.v1.ObjectInfo.metadata:type_name -> objstore.v1.Metadata
 	2,  // 3: objstore.v1.PutRequest.metadata:type_name -> objstore.v1.Metadata
-	2,  // 4: objstore.v1.GetResponse.metadata:type_name -> objstore.v1.Metadata
-	3,  // 5: objstore.v1.ListResponse.objects:type_name -> objstore.v1.ObjectInfo
-	2,  // 6: objstore.v1.MetadataResponse.metadata:type_name -> objstore.v1.Metadata
-	2,  // 7: objstore.v1.UpdateMetadataRequest.metadata:type_name -> objstore.v1.Metadata
-	1,  // 8: objstore.v1.HealthResponse.status:type_name -> objstore.v1.HealthResponse.Status
-	49, // 9: objstore.v1.ArchiveRequest.destination_settings:type_name -> objstore.v1.ArchiveRequest.DestinationSettingsEntry
-	50, // 10: objstore.v1.LifecyclePolicy.destination_settings:type_name -> objstore.v1.LifecyclePolicy.DestinationSettingsEntry
-	22, // 11: objstore.v1.AddPolicyRequest.policy:type_name -> objstore.v1.LifecyclePolicy
-	22, // 12: objstore.v1.GetPoliciesResponse.policies:type_name -> objstore.v1.LifecyclePolicy
-	31, // 13: objstore.v1.EncryptionPolicy.backend:type_name -> objstore.v1.EncryptionConfig
-	31, // 14: objstore.v1.EncryptionPolicy.source:type_name -> objstore.v1.EncryptionConfig
-	31, // 15: objstore.v1.EncryptionPolicy.destination:type_name -> objstore.v1.EncryptionConfig
-	51, // 16: objstore.v1.ReplicationPolicy.source_settings:type_name -> objstore.v1.ReplicationPolicy.SourceSettingsEntry
-	52, // 17: objstore.v1.ReplicationPolicy.destination_settings:type_name -> objstore.v1.ReplicationPolicy.DestinationSettingsEntry
-	53, // 18: objstore.v1.ReplicationPolicy.last_sync_time:type_name -> google.protobuf.Timestamp
-	32, // 19: objstore.v1.ReplicationPolicy.encryption:type_name -> objstore.v1.EncryptionPolicy
-	0,  // 20: objstore.v1.ReplicationPolicy.replication_mode:type_name -> objstore.v1.ReplicationMode
-	33, // 21: objstore.v1.AddReplicationPolicyRequest.policy:type_name -> objstore.v1.ReplicationPolicy
-	33, // 22: objstore.v1.GetReplicationPoliciesResponse.policies:type_name -> objstore.v1.ReplicationPolicy
-	33, // 23: objstore.v1.GetReplicationPolicyResponse.policy:type_name -> objstore.v1.ReplicationPolicy
-	43, // 24: objstore.v1.TriggerReplicationResponse.result:type_name -> objstore.v1.SyncResult
-	53, // 25: objstore.v1.ReplicationStatus.last_sync_time:type_name -> google.protobuf.Timestamp
-	46, // 26: objstore.v1.GetReplicationStatusResponse.status:type_name -> objstore.v1.ReplicationStatus
-	4,  // 27: objstore.v1.ObjectStore.Put:input_type -> objstore.v1.PutRequest
-	6,  // 28: objstore.v1.ObjectStore.Get:input_type -> objstore.v1.GetRequest
-	8,  // 29: objstore.v1.ObjectStore.Delete:input_type -> objstore.v1.DeleteRequest
-	10, // 30: objstore.v1.ObjectStore.List:input_type -> objstore.v1.ListRequest
-	12, // 31: objstore.v1.ObjectStore.Exists:input_type -> objstore.v1.ExistsRequest
-	14, // 32: objstore.v1.ObjectStore.GetMetadata:input_type -> objstore.v1.GetMetadataRequest
-	16, // 33: objstore.v1.ObjectStore.UpdateMetadata:input_type -> objstore.v1.UpdateMetadataRequest
-	18, // 34: objstore.v1.ObjectStore.Health:input_type -> objstore.v1.HealthRequest
-	20, // 35: objstore.v1.ObjectStore.Archive:input_type -> objstore.v1.ArchiveRequest
-	23, // 36: objstore.v1.ObjectStore.AddPolicy:input_type -> objstore.v1.AddPolicyRequest
-	25, // 37: objstore.v1.ObjectStore.RemovePolicy:input_type -> objstore.v1.RemovePolicyRequest
-	27, // 38: objstore.v1.ObjectStore.GetPolicies:input_type -> objstore.v1.GetPoliciesRequest
-	29, // 39: objstore.v1.ObjectStore.ApplyPolicies:input_type -> objstore.v1.ApplyPoliciesRequest
-	34, // 40: objstore.v1.ObjectStore.AddReplicationPolicy:input_type -> objstore.v1.AddReplicationPolicyRequest
-	36, // 41: objstore.v1.ObjectStore.RemoveReplicationPolicy:input_type -> objstore.v1.RemoveReplicationPolicyRequest
-	38, // 42: objstore.v1.ObjectStore.GetReplicationPolicies:input_type -> objstore.v1.GetReplicationPoliciesRequest
-	40, // 43: objstore.v1.ObjectStore.GetReplicationPolicy:input_type -> objstore.v1.GetReplicationPolicyRequest
-	42, // 44: objstore.v1.ObjectStore.TriggerReplication:input_type -> objstore.v1.TriggerReplicationRequest
-	45, // 45: objstore.v1.ObjectStore.GetReplicationStatus:input_type -> objstore.v1.GetReplicationStatusRequest
-	5,  // 46: objstore.v1.ObjectStore.Put:output_type -> objstore.v1.PutResponse
-	7,  // 47: objstore.v1.ObjectStore.Get:output_type -> objstore.v1.GetResponse
-	9,  // 48: objstore.v1.ObjectStore.Delete:output_type -> objstore.v1.DeleteResponse
-	11, // 49: objstore.v1.ObjectStore.List:output_type -> objstore.v1.ListResponse
-	13, // 50: objstore.v1.ObjectStore.Exists:output_type -> objstore.v1.ExistsResponse
-	15, // 51: objstore.v1.ObjectStore.GetMetadata:output_type -> objstore.v1.MetadataResponse
-	17, // 52: objstore.v1.ObjectStore.UpdateMetadata:output_type -> objstore.v1.UpdateMetadataResponse
-	19, // 53: objstore.v1.ObjectStore.Health:output_type -> objstore.v1.HealthResponse
-	21, // 54: objstore.v1.ObjectStore.Archive:output_type -> objstore.v1.ArchiveResponse
-	24, // 55: objstore.v1.ObjectStore.AddPolicy:output_type -> objstore.v1.AddPolicyResponse
-	26, // 56: objstore.v1.ObjectStore.RemovePolicy:output_type -> objstore.v1.RemovePolicyResponse
-	28, // 57: objstore.v1.ObjectStore.GetPolicies:output_type -> objstore.v1.GetPoliciesResponse
-	30, // 58: objstore.v1.ObjectStore.ApplyPolicies:output_type -> objstore.v1.ApplyPoliciesResponse
-	35, // 59: objstore.v1.ObjectStore.AddReplicationPolicy:output_type -> objstore.v1.AddReplicationPolicyResponse
-	37, // 60: objstore.v1.ObjectStore.RemoveReplicationPolicy:output_type -> objstore.v1.RemoveReplicationPolicyResponse
-	39, // 61: objstore.v1.ObjectStore.GetReplicationPolicies:output_type -> objstore.v1.GetReplicationPoliciesResponse
-	41, // 62: objstore.v1.ObjectStore.GetReplicationPolicy:output_type -> objstore.v1.GetReplicationPolicyResponse
-	44, // 63: objstore.v1.ObjectStore.TriggerReplication:output_type -> objstore.v1.TriggerReplicationResponse
-	47, // 64: objstore.v1.ObjectStore.GetReplicationStatus:output_type -> objstore.v1.GetReplicationStatusResponse
-	46, // [46:65] is the sub-list for method output_type
-	27, // [27:46] is the sub-list for method input_type
-	27, // [27:27] is the sub-list for extension type_name
-	27, // [27:27] is the sub-list for extension extendee
-	0,  // [0:27] is the sub-list for field type_name
+	61, // 4: objstore.v1.PutResponse.last_modified:type_name -> google.protobuf.Timestamp
+	2,  // 5: objstore.v1.GetResponse.metadata:type_name -> objstore.v1.Metadata
+	3,  // 6: objstore.v1.ListResponse.objects:type_name -> objstore.v1.ObjectInfo
+	2,  // 7: objstore.v1.MetadataResponse.metadata:type_name -> objstore.v1.Metadata
+	2,  // 8: objstore.v1.UpdateMetadataRequest.metadata:type_name -> objstore.v1.Metadata
+	1,  // 9: objstore.v1.HealthResponse.status:type_name -> objstore.v1.HealthResponse.Status
+	56, // 10: objstore.v1.ArchiveRequest.destination_settings:type_name -> objstore.v1.ArchiveRequest.DestinationSettingsEntry
+	57, // 11: objstore.v1.RestoreFromArchiveRequest.source_settings:type_name -> objstore.v1.RestoreFromArchiveRequest.SourceSettingsEntry
+	3,  // 12: objstore.v1.SearchResponse.objects:type_name -> objstore.v1.ObjectInfo
+	61, // 13: objstore.v1.Change.timestamp:type_name -> google.protobuf.Timestamp
+	27, // 14: objstore.v1.GetChangesResponse.changes:type_name -> objstore.v1.Change
+	58, // 15: objstore.v1.LifecyclePolicy.destination_settings:type_name -> objstore.v1.LifecyclePolicy.DestinationSettingsEntry
+	29, // 16: objstore.v1.AddPolicyRequest.policy:type_name -> objstore.v1.LifecyclePolicy
+	29, // 17: objstore.v1.GetPoliciesResponse.policies:type_name -> objstore.v1.LifecyclePolicy
+	38, // 18: objstore.v1.EncryptionPolicy.backend:type_name -> objstore.v1.EncryptionConfig
+	38, // 19: objstore.v1.EncryptionPolicy.source:type_name -> objstore.v1.EncryptionConfig
+	38, // 20: objstore.v1.EncryptionPolicy.destination:type_name -> objstore.v1.EncryptionConfig
+	59, // 21: objstore.v1.ReplicationPolicy.source_settings:type_name -> objstore.v1.ReplicationPolicy.SourceSettingsEntry
+	60, // 22: objstore.v1.ReplicationPolicy.destination_settings:type_name -> objstore.v1.ReplicationPolicy.DestinationSettingsEntry
+	61, // 23: objstore.v1.ReplicationPolicy.last_sync_time:type_name -> google.protobuf.Timestamp
+	39, // 24: objstore.v1.ReplicationPolicy.encryption:type_name -> objstore.v1.EncryptionPolicy
+	0,  // 25: objstore.v1.ReplicationPolicy.replication_mode:type_name -> objstore.v1.ReplicationMode
+	40, // 26: objstore.v1.AddReplicationPolicyRequest.policy:type_name -> objstore.v1.ReplicationPolicy
+	40, // 27: objstore.v1.GetReplicationPoliciesResponse.policies:type_name -> objstore.v1.ReplicationPolicy
+	40, // 28: objstore.v1.GetReplicationPolicyResponse.policy:type_name -> objstore.v1.ReplicationPolicy
+	50, // 29: objstore.v1.TriggerReplicationResponse.result:type_name -> objstore.v1.SyncResult
+	61, // 30: objstore.v1.ReplicationStatus.last_sync_time:type_name -> google.protobuf.Timestamp
+	53, // 31: objstore.v1.GetReplicationStatusResponse.status:type_name -> objstore.v1.ReplicationStatus
+	4,  // 32: objstore.v1.ObjectStore.Put:input_type -> objstore.v1.PutRequest
+	6,  // 33: objstore.v1.ObjectStore.Get:input_type -> objstore.v1.GetRequest
+	8,  // 34: objstore.v1.ObjectStore.Delete:input_type -> objstore.v1.DeleteRequest
+	10, // 35: objstore.v1.ObjectStore.List:input_type -> objstore.v1.ListRequest
+	12, // 36: objstore.v1.ObjectStore.Exists:input_type -> objstore.v1.ExistsRequest
+	14, // 37: objstore.v1.ObjectStore.GetMetadata:input_type -> objstore.v1.GetMetadataRequest
+	16, // 38: objstore.v1.ObjectStore.UpdateMetadata:input_type -> objstore.v1.UpdateMetadataRequest
+	18, // 39: objstore.v1.ObjectStore.Health:input_type -> objstore.v1.HealthRequest
+	20, // 40: objstore.v1.ObjectStore.Archive:input_type -> objstore.v1.ArchiveRequest
+	22, // 41: objstore.v1.ObjectStore.RestoreFromArchive:input_type -> objstore.v1.RestoreFromArchiveRequest
+	24, // 42: objstore.v1.ObjectStore.Search:input_type -> objstore.v1.SearchRequest
+	26, // 43: objstore.v1.ObjectStore.GetChanges:input_type -> objstore.v1.GetChangesRequest
+	30, // 44: objstore.v1.ObjectStore.AddPolicy:input_type -> objstore.v1.AddPolicyRequest
+	32, // 45: objstore.v1.ObjectStore.RemovePolicy:input_type -> objstore.v1.RemovePolicyRequest
+	34, // 46: objstore.v1.ObjectStore.GetPolicies:input_type -> objstore.v1.GetPoliciesRequest
+	36, // 47: objstore.v1.ObjectStore.ApplyPolicies:input_type -> objstore.v1.ApplyPoliciesRequest
+	41, // 48: objstore.v1.ObjectStore.AddReplicationPolicy:input_type -> objstore.v1.AddReplicationPolicyRequest
+	43, // 49: objstore.v1.ObjectStore.RemoveReplicationPolicy:input_type -> objstore.v1.RemoveReplicationPolicyRequest
+	45, // 50: objstore.v1.ObjectStore.GetReplicationPolicies:input_type -> objstore.v1.GetReplicationPoliciesRequest
+	47, // 51: objstore.v1.ObjectStore.GetReplicationPolicy:input_type -> objstore.v1.GetReplicationPolicyRequest
+	49, // 52: objstore.v1.ObjectStore.TriggerReplication:input_type -> objstore.v1.TriggerReplicationRequest
+	52, // 53: objstore.v1.ObjectStore.GetReplicationStatus:input_type -> objstore.v1.GetReplicationStatusRequest
+	5,  // 54: objstore.v1.ObjectStore.Put:output_type -> objstore.v1.PutResponse
+	7,  // 55: objstore.v1.ObjectStore.Get:output_type -> objstore.v1.GetResponse
+	9,  // 56: objstore.v1.ObjectStore.Delete:output_type -> objstore.v1.DeleteResponse
+	11, // 57: objstore.v1.ObjectStore.List:output_type -> objstore.v1.ListResponse
+	13, // 58: objstore.v1.ObjectStore.Exists:output_type -> objstore.v1.ExistsResponse
+	15, // 59: objstore.v1.ObjectStore.GetMetadata:output_type -> objstore.v1.MetadataResponse
+	17, // 60: objstore.v1.ObjectStore.UpdateMetadata:output_type -> objstore.v1.UpdateMetadataResponse
+	19, // 61: objstore.v1.ObjectStore.Health:output_type -> objstore.v1.HealthResponse
+	21, // 62: objstore.v1.ObjectStore.Archive:output_type -> objstore.v1.ArchiveResponse
+	23, // 63: objstore.v1.ObjectStore.RestoreFromArchive:output_type -> objstore.v1.RestoreFromArchiveResponse
+	25, // 64: objstore.v1.ObjectStore.Search:output_type -> objstore.v1.SearchResponse
+	28, // 65: objstore.v1.ObjectStore.GetChanges:output_type -> objstore.v1.GetChangesResponse
+	31, // 66: objstore.v1.ObjectStore.AddPolicy:output_type -> objstore.v1.AddPolicyResponse
+	33, // 67: objstore.v1.ObjectStore.RemovePolicy:output_type -> objstore.v1.RemovePolicyResponse
+	35, // 68: objstore.v1.ObjectStore.GetPolicies:output_type -> objstore.v1.GetPoliciesResponse
+	37, // 69: objstore.v1.ObjectStore.ApplyPolicies:output_type -> objstore.v1.ApplyPoliciesResponse
+	42, // 70: objstore.v1.ObjectStore.AddReplicationPolicy:output_type -> objstore.v1.AddReplicationPolicyResponse
+	44, // 71: objstore.v1.ObjectStore.RemoveReplicationPolicy:output_type -> objstore.v1.RemoveReplicationPolicyResponse
+	46, // 72: objstore.v1.ObjectStore.GetReplicationPolicies:output_type -> objstore.v1.GetReplicationPoliciesResponse
+	48, // 73: objstore.v1.ObjectStore.GetReplicationPolicy:output_type -> objstore.v1.GetReplicationPolicyResponse
+	51, // 74: objstore.v1.ObjectStore.TriggerReplication:output_type -> objstore.v1.TriggerReplicationResponse
+	54, // 75: objstore.v1.ObjectStore.GetReplicationStatus:output_type -> objstore.v1.GetReplicationStatusResponse
+	54, // [54:76] is the sub-list for method output_type
+	32, // [32:54] is the sub-list for method input_type
+	32, // [32:32] is the sub-list for extension type_name
+	32, // [32:32] is the sub-list for extension extendee
+	0,  // [0:32] is the sub-list for field type_name
 }
 
 func init() { file_objstore_proto_init() }
@@ -3308,7 +3883,7 @@ func file_objstore_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_objstore_proto_rawDesc), len(file_objstore_proto_rawDesc)),
 			NumEnums:      2,
-			NumMessages:   51,
+			NumMessages:   59,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
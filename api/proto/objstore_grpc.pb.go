@@ -17,6 +17,13 @@
 // - protoc             v3.21.12
 // source: objstore.proto
 
+// Versioning: objstore.v1 only evolves in backwards-compatible ways. New
+// RPCs, messages and fields may be added, but existing field numbers, names
+// and types never change and removed fields are reserved. A breaking change
+// ships as a new package (objstore.v2) served alongside v1. Clients can read
+// the revision of the v1 surface a server implements from
+// HealthResponse.api_version.
+
 package objstorepb
 
 import (
@@ -41,6 +48,9 @@ const (
 	ObjectStore_UpdateMetadata_FullMethodName          = "/objstore.v1.ObjectStore/UpdateMetadata"
 	ObjectStore_Health_FullMethodName                  = "/objstore.v1.ObjectStore/Health"
 	ObjectStore_Archive_FullMethodName                 = "/objstore.v1.ObjectStore/Archive"
+	ObjectStore_RestoreFromArchive_FullMethodName      = "/objstore.v1.ObjectStore/RestoreFromArchive"
+	ObjectStore_Search_FullMethodName                  = "/objstore.v1.ObjectStore/Search"
+	ObjectStore_GetChanges_FullMethodName              = "/objstore.v1.ObjectStore/GetChanges"
 	ObjectStore_AddPolicy_FullMethodName               = "/objstore.v1.ObjectStore/AddPolicy"
 	ObjectStore_RemovePolicy_FullMethodName            = "/objstore.v1.ObjectStore/RemovePolicy"
 	ObjectStore_GetPolicies_FullMethodName             = "/objstore.v1.ObjectStore/GetPolicies"
@@ -77,6 +87,12 @@ type ObjectStoreClient interface {
 	Health(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (*HealthResponse, error)
 	// Archive copies an object to an archival storage backend.
 	Archive(ctx context.Context, in *ArchiveRequest, opts ...grpc.CallOption) (*ArchiveResponse, error)
+	// RestoreFromArchive copies an object back from a readable archival backend.
+	RestoreFromArchive(ctx context.Context, in *RestoreFromArchiveRequest, opts ...grpc.CallOption) (*RestoreFromArchiveResponse, error)
+	// Search finds objects whose key or metadata match a query.
+	Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error)
+	// GetChanges returns the changes made after a sequence number, oldest first.
+	GetChanges(ctx context.Context, in *GetChangesRequest, opts ...grpc.CallOption) (*GetChangesResponse, error)
 	// AddPolicy adds a new lifecycle policy.
 	AddPolicy(ctx context.Context, in *AddPolicyRequest, opts ...grpc.CallOption) (*AddPolicyResponse, error)
 	// RemovePolicy removes an existing lifecycle policy.
@@ -206,6 +222,36 @@ func (c *objectStoreClient) Archive(ctx context.Context, in *ArchiveRequest, opt
 	return out, nil
 }
 
+func (c *objectStoreClient) RestoreFromArchive(ctx context.Context, in *RestoreFromArchiveRequest, opts ...grpc.CallOption) (*RestoreFromArchiveResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RestoreFromArchiveResponse)
+	err := c.cc.Invoke(ctx, ObjectStore_RestoreFromArchive_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *objectStoreClient) Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchResponse)
+	err := c.cc.Invoke(ctx, ObjectStore_Search_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *objectStoreClient) GetChanges(ctx context.Context, in *GetChangesRequest, opts ...grpc.CallOption) (*GetChangesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetChangesResponse)
+	err := c.cc.Invoke(ctx, ObjectStore_GetChanges_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *objectStoreClient) AddPolicy(ctx context.Context, in *AddPolicyRequest, opts ...grpc.CallOption) (*AddPolicyResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(AddPolicyResponse)
@@ -330,6 +376,12 @@ type ObjectStoreServer interface {
 	Health(context.Context, *HealthRequest) (*HealthResponse, error)
 	// Archive copies an object to an archival storage backend.
 	Archive(context.Context, *ArchiveRequest) (*ArchiveResponse, error)
+	// RestoreFromArchive copies an object back from a readable archival backend.
+	RestoreFromArchive(context.Context, *RestoreFromArchiveRequest) (*RestoreFromArchiveResponse, error)
+	// Search finds objects whose key or metadata match a query.
+	Search(context.Context, *SearchRequest) (*SearchResponse, error)
+	// GetChanges returns the changes made after a sequence number, oldest first.
+	GetChanges(context.Context, *GetChangesRequest) (*GetChangesResponse, error)
 	// AddPolicy adds a new lifecycle policy.
 	AddPolicy(context.Context, *AddPolicyRequest) (*AddPolicyResponse, error)
 	// RemovePolicy removes an existing lifecycle policy.
@@ -387,6 +439,15 @@ func (UnimplementedObjectStoreServer) Health(context.Context, *HealthRequest) (*
 func (UnimplementedObjectStoreServer) Archive(context.Context, *ArchiveRequest) (*ArchiveResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Archive not implemented")
 }
+func (UnimplementedObjectStoreServer) RestoreFromArchive(context.Context, *RestoreFromArchiveRequest) (*RestoreFromArchiveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreFromArchive not implemented")
+}
+func (UnimplementedObjectStoreServer) Search(context.Context, *SearchRequest) (*SearchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Search not implemented")
+}
+func (UnimplementedObjectStoreServer) GetChanges(context.Context, *GetChangesRequest) (*GetChangesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetChanges not implemented")
+}
 func (UnimplementedObjectStoreServer) AddPolicy(context.Context, *AddPolicyRequest) (*AddPolicyResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AddPolicy not implemented")
 }
@@ -593,6 +654,60 @@ func _ObjectStore_Archive_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ObjectStore_RestoreFromArchive_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreFromArchiveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ObjectStoreServer).RestoreFromArchive(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ObjectStore_RestoreFromArchive_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ObjectStoreServer).RestoreFromArchive(ctx, req.(*RestoreFromArchiveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ObjectStore_Search_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ObjectStoreServer).Search(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ObjectStore_Search_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ObjectStoreServer).Search(ctx, req.(*SearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ObjectStore_GetChanges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetChangesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ObjectStoreServer).GetChanges(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ObjectStore_GetChanges_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ObjectStoreServer).GetChanges(ctx, req.(*GetChangesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ObjectStore_AddPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(AddPolicyRequest)
 	if err := dec(in); err != nil {
@@ -812,6 +927,18 @@ var ObjectStore_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Archive",
 			Handler:    _ObjectStore_Archive_Handler,
 		},
+		{
+			MethodName: "RestoreFromArchive",
+			Handler:    _ObjectStore_RestoreFromArchive_Handler,
+		},
+		{
+			MethodName: "Search",
+			Handler:    _ObjectStore_Search_Handler,
+		},
+		{
+			MethodName: "GetChanges",
+			Handler:    _ObjectStore_GetChanges_Handler,
+		},
 		{
 			MethodName: "AddPolicy",
 			Handler:    _ObjectStore_AddPolicy_Handler,
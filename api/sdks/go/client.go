@@ -76,11 +76,19 @@ type Metadata struct {
 	Custom          map[string]string
 }
 
-// PutResult contains the response from a Put operation.
+// PutResult contains the response from a Put operation. ETag, Version,
+// Size and LastModified describe the object written, so callers need no
+// follow-up GetMetadata; servers that predate them leave them zero.
 type PutResult struct {
 	Success bool
 	Message string
 	ETag    string
+
+	// Version is the object's version or generation on backends that keep
+	// them.
+	Version      string
+	Size         int64
+	LastModified time.Time
 }
 
 // GetResult contains the response from a Get operation.
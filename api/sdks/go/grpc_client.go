@@ -151,11 +151,17 @@ func (c *GRPCClient) Put(ctx context.Context, key string, data []byte, metadata
 			return nil, wrapGRPCError("put operation failed", err)
 		}
 
-		return &PutResult{
+		result := &PutResult{
 			Success: resp.Success,
 			Message: resp.Message,
 			ETag:    resp.Etag,
-		}, nil
+			Version: resp.Version,
+			Size:    resp.Size,
+		}
+		if resp.LastModified != nil {
+			result.LastModified = resp.LastModified.AsTime()
+		}
+		return result, nil
 	})
 }
 
@@ -110,6 +110,30 @@ func (m *MockObjectStoreClient) Archive(ctx context.Context, in *objstorepb.Arch
 	return args.Get(0).(*objstorepb.ArchiveResponse), args.Error(1)
 }
 
+func (m *MockObjectStoreClient) RestoreFromArchive(ctx context.Context, in *objstorepb.RestoreFromArchiveRequest, opts ...grpc.CallOption) (*objstorepb.RestoreFromArchiveResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*objstorepb.RestoreFromArchiveResponse), args.Error(1)
+}
+
+func (m *MockObjectStoreClient) Search(ctx context.Context, in *objstorepb.SearchRequest, opts ...grpc.CallOption) (*objstorepb.SearchResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*objstorepb.SearchResponse), args.Error(1)
+}
+
+func (m *MockObjectStoreClient) GetChanges(ctx context.Context, in *objstorepb.GetChangesRequest, opts ...grpc.CallOption) (*objstorepb.GetChangesResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*objstorepb.GetChangesResponse), args.Error(1)
+}
+
 func (m *MockObjectStoreClient) AddPolicy(ctx context.Context, in *objstorepb.AddPolicyRequest, opts ...grpc.CallOption) (*objstorepb.AddPolicyResponse, error) {
 	args := m.Called(ctx, in)
 	if args.Get(0) == nil {
@@ -15,12 +15,59 @@ package objstore
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"time"
 )
 
+// putFields describes the object written by a put, as returned by the REST,
+// QUIC and Unix servers.
+type putFields struct {
+	Message      string    `json:"message"`
+	ETag         string    `json:"etag"`
+	Version      string    `json:"version"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+// result returns the PutResult the fields describe, with etag used when
+// the body carries none.
+func (f *putFields) result(etag string) *PutResult {
+	if f.ETag != "" {
+		etag = f.ETag
+	}
+	return &PutResult{
+		Success:      true,
+		Message:      f.Message,
+		ETag:         etag,
+		Version:      f.Version,
+		Size:         f.Size,
+		LastModified: f.LastModified,
+	}
+}
+
+// putResponse is the body of a REST or QUIC put response. REST nests the
+// written object under data; QUIC returns it at the top level.
+type putResponse struct {
+	putFields
+	Data *putFields `json:"data,omitempty"`
+}
+
+// fields returns the description of the written object.
+func (r *putResponse) fields() *putFields {
+	if r.Data == nil {
+		return &r.putFields
+	}
+	fields := *r.Data
+	if fields.Message == "" {
+		fields.Message = r.Message
+	}
+	return &fields
+}
+
 // httpStatusError converts a non-success HTTP status code into an error that
 // wraps the canonical SDK sentinel for that status: 400 ErrInvalidArgument,
 // 401 ErrUnauthenticated, 403 ErrPermissionDenied, 404 ErrObjectNotFound,
@@ -131,8 +178,8 @@ func httpPutStream(ctx context.Context, client *http.Client, baseURL, key string
 		return nil, httpStatusError("PUT (stream)", resp.StatusCode)
 	}
 
-	return &PutResult{
-		Success: true,
-		ETag:    resp.Header.Get("ETag"),
-	}, nil
+	// Ignore decode errors so a missing body never masks success.
+	var result putResponse
+	_ = json.NewDecoder(resp.Body).Decode(&result)
+	return result.fields().result(resp.Header.Get("ETag")), nil
 }
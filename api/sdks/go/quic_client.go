@@ -115,18 +115,10 @@ func (c *QUICClient) Put(ctx context.Context, key string, data []byte, metadata
 		return nil, httpStatusError("PUT", resp.StatusCode)
 	}
 
-	var result struct {
-		Key     string `json:"key"`
-		Message string `json:"message"`
-	}
-	// The body has no etag; ignore decode errors so a missing/extra body never masks success.
+	// Ignore decode errors so a missing/extra body never masks success.
+	var result putResponse
 	_ = json.NewDecoder(resp.Body).Decode(&result)
-
-	return &PutResult{
-		Success: true,
-		Message: result.Message,
-		ETag:    resp.Header.Get("ETag"),
-	}, nil
+	return result.fields().result(resp.Header.Get("ETag")), nil
 }
 
 // Get retrieves an object via GET /objects/{key}.
@@ -112,30 +112,11 @@ func (c *RESTClient) Put(ctx context.Context, key string, data []byte, metadata
 		return nil, httpStatusError("PUT", resp.StatusCode)
 	}
 
-	var result struct {
-		Message string                 `json:"message"`
-		Data    map[string]interface{} `json:"data,omitempty"`
-	}
-
+	var result putResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return nil, err
 	}
-
-	// Try to get ETag from response header first
-	etag := resp.Header.Get("ETag")
-
-	// If not in header, check if it's in the response body data
-	if etag == "" && result.Data != nil {
-		if etagVal, ok := result.Data["etag"].(string); ok {
-			etag = etagVal
-		}
-	}
-
-	return &PutResult{
-		Success: true,
-		Message: result.Message,
-		ETag:    etag,
-	}, nil
+	return result.fields().result(resp.Header.Get("ETag")), nil
 }
 
 // Get retrieves an object.
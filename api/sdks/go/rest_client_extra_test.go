@@ -15,6 +15,7 @@ package objstore
 import (
 	"context"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -67,6 +68,32 @@ func TestRESTClientBadJSON(t *testing.T) {
 	})
 }
 
+func TestRESTClientPutResult(t *testing.T) {
+	ctx := context.Background()
+	modified := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	c := restServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		writeJSON(w, map[string]any{
+			"message": "object uploaded successfully",
+			"data": map[string]any{
+				"key": "k", "etag": "e1", "version": "v2", "size": 5,
+				"last_modified": modified.Format(time.RFC3339Nano),
+			},
+		})
+	})
+	res, err := c.Put(ctx, "k", []byte("hello"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "object uploaded successfully", res.Message)
+	assert.Equal(t, "e1", res.ETag)
+	assert.Equal(t, "v2", res.Version)
+	assert.Equal(t, int64(5), res.Size)
+	assert.True(t, res.LastModified.Equal(modified))
+
+	streamed, err := c.PutStream(ctx, "k", strings.NewReader("hello"), 5, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "v2", streamed.Version)
+}
+
 func TestRESTClientGetFullMetadata(t *testing.T) {
 	ctx := context.Background()
 	c := restServer(t, func(w http.ResponseWriter, r *http.Request) {
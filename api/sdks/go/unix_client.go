@@ -186,15 +186,11 @@ func (c *UnixClient) Put(ctx context.Context, key string, data []byte, metadata
 		params["metadata"] = rpcMetadataParams(metadata)
 	}
 
-	// The Unix server returns a generic success/message map for put; the
-	// payload carries nothing the SDK surfaces, so it is discarded.
-	if err := c.call(ctx, "put", params, nil); err != nil {
+	var result putFields
+	if err := c.call(ctx, "put", params, &result); err != nil {
 		return nil, err
 	}
-
-	return &PutResult{
-		Success: true,
-	}, nil
+	return result.result(""), nil
 }
 
 // Get retrieves an object.
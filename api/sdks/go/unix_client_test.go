@@ -19,6 +19,7 @@ import (
 	"path/filepath"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -179,7 +180,7 @@ func TestUnixClient_Put(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, payload, decoded)
 
-		return map[string]any{"success": true}, nil
+		return map[string]any{"status": "ok", "etag": "abc", "version": "v1", "size": len(payload), "last_modified": "2025-01-02T03:04:05Z"}, nil
 	})
 
 	client := newUnixClientForTest(t, sockPath)
@@ -188,6 +189,10 @@ func TestUnixClient_Put(t *testing.T) {
 	result, err := client.Put(context.Background(), "test-key", payload, nil)
 	require.NoError(t, err)
 	assert.True(t, result.Success)
+	assert.Equal(t, "abc", result.ETag)
+	assert.Equal(t, "v1", result.Version)
+	assert.Equal(t, int64(len(payload)), result.Size)
+	assert.True(t, result.LastModified.Equal(time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)))
 }
 
 // TestUnixClient_Put_EmptyKey validates key check.
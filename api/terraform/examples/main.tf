@@ -0,0 +1,78 @@
+terraform {
+  required_providers {
+    objstore = {
+      source = "jeremyhahn/objstore"
+    }
+  }
+}
+
+provider "objstore" {
+  endpoint = "https://objstore.example.com:8080"
+  # token is read from OBJSTORE_TOKEN when not set here
+}
+
+resource "objstore_lifecycle_policy" "cool_logs" {
+  id             = "cool-logs"
+  prefix         = "logs/"
+  retention_days = 30
+  action         = "transition"
+  storage_class  = "GLACIER"
+}
+
+resource "objstore_lifecycle_policy" "expire_logs" {
+  id             = "expire-logs"
+  prefix         = "logs/"
+  retention_days = 90
+  action         = "delete"
+}
+
+variable "backup_secret_key" {
+  type      = string
+  sensitive = true
+}
+
+resource "objstore_replication_policy" "backup" {
+  id             = "backup"
+  source_backend = "local"
+  source_settings = {
+    path = "/data"
+  }
+  destination_backend = "s3"
+  destination_settings = {
+    bucket     = "backups"
+    region     = "us-east-1"
+    secret_key = var.backup_secret_key
+  }
+  check_interval = "1h"
+}
+
+resource "objstore_quota" "acme" {
+  prefix    = "tenants/acme/"
+  max_bytes = 10737418240
+}
+
+resource "objstore_webhook" "thumbnails" {
+  id        = "thumbnails"
+  events    = ["s3:ObjectCreated:*"]
+  prefix    = "images/"
+  sink_type = "sqs"
+  sink_settings = {
+    queueUrl = "https://sqs.us-east-1.amazonaws.com/123456789012/thumbnails"
+    region   = "us-east-1"
+  }
+}
+
+resource "objstore_access_key" "ci" {
+  principal_id = "ci-deployer"
+  roles        = ["writer"]
+  description  = "CI uploads"
+}
+
+output "ci_access_key_id" {
+  value = objstore_access_key.ci.access_key_id
+}
+
+output "ci_secret_access_key" {
+  value     = objstore_access_key.ci.secret_access_key
+  sensitive = true
+}
@@ -0,0 +1,210 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package provider
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+)
+
+var (
+	_ resource.ResourceWithConfigure   = (*accessKeyResource)(nil)
+	_ resource.ResourceWithImportState = (*accessKeyResource)(nil)
+)
+
+// accessKeyResource manages objstore_access_key.
+type accessKeyResource struct {
+	client Client
+}
+
+// accessKeyModel is an objstore_access_key block.
+type accessKeyModel struct {
+	PrincipalID     types.String `tfsdk:"principal_id"`
+	Roles           types.List   `tfsdk:"roles"`
+	Description     types.String `tfsdk:"description"`
+	AccessKeyID     types.String `tfsdk:"access_key_id"`
+	SecretAccessKey types.String `tfsdk:"secret_access_key"`
+	CreatedAt       types.String `tfsdk:"created_at"`
+}
+
+// NewAccessKeyResource returns the objstore_access_key resource.
+func NewAccessKeyResource() resource.Resource {
+	return &accessKeyResource{}
+}
+
+// Metadata returns the resource type name.
+func (r *accessKeyResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_access_key"
+}
+
+// Schema defines the resource's attributes. Keys cannot be changed once
+// issued, so every configurable attribute replaces the key.
+func (r *accessKeyResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	keep := []planmodifier.String{stringplanmodifier.UseStateForUnknown()}
+	resp.Schema = schema.Schema{
+		Description: "A SigV4 access key issued by the server. The server must be configured with an access key store.",
+		Attributes: map[string]schema.Attribute{
+			"principal_id": schema.StringAttribute{
+				Description:   "Principal the key authenticates as. Changing it issues a new key.",
+				Required:      true,
+				PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
+			},
+			"roles": schema.ListAttribute{
+				Description:   "Roles of the principal. Changing them issues a new key.",
+				ElementType:   types.StringType,
+				Optional:      true,
+				PlanModifiers: []planmodifier.List{listplanmodifier.RequiresReplace()},
+			},
+			"description": schema.StringAttribute{
+				Description:   "What the key is for. Changing it issues a new key.",
+				Optional:      true,
+				PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
+			},
+			"access_key_id": schema.StringAttribute{
+				Description:   "Access key ID.",
+				Computed:      true,
+				PlanModifiers: keep,
+			},
+			"secret_access_key": schema.StringAttribute{
+				Description:   "Secret access key. The server returns it only when the key is created, so it is null for imported keys.",
+				Computed:      true,
+				Sensitive:     true,
+				PlanModifiers: keep,
+			},
+			"created_at": schema.StringAttribute{
+				Description:   "When the key was issued, in RFC 3339 format.",
+				Computed:      true,
+				PlanModifiers: keep,
+			},
+		},
+	}
+}
+
+// Configure stores the provider's client.
+func (r *accessKeyResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.client = configureClient(req, resp)
+}
+
+// Create issues the key and records its secret in state.
+func (r *accessKeyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan accessKeyModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	request := adapters.AccessKey{
+		PrincipalID: plan.PrincipalID.ValueString(),
+		Description: plan.Description.ValueString(),
+	}
+	if !plan.Roles.IsNull() {
+		resp.Diagnostics.Append(plan.Roles.ElementsAs(ctx, &request.Roles, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	key, err := r.client.CreateAccessKey(ctx, request)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to create access key", err.Error())
+		return
+	}
+	plan.AccessKeyID = types.StringValue(key.AccessKeyID)
+	plan.SecretAccessKey = types.StringValue(key.SecretAccessKey)
+	plan.CreatedAt = types.StringValue(key.CreatedAt.UTC().Format(time.RFC3339))
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Read refreshes the key from the server, removing it from state when it
+// has been revoked.
+func (r *accessKeyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state accessKeyModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	key, err := r.client.GetAccessKey(ctx, state.AccessKeyID.ValueString())
+	if isNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to read access key", err.Error())
+		return
+	}
+	model, diags := newAccessKeyModel(ctx, key, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, model)...)
+}
+
+// Update is never called with a change, since every configurable
+// attribute replaces the key; it keeps the planned state.
+func (r *accessKeyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan accessKeyModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Delete revokes the key.
+func (r *accessKeyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state accessKeyModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if err := r.client.DeleteAccessKey(ctx, state.AccessKeyID.ValueString()); err != nil && !isNotFound(err) {
+		resp.Diagnostics.AddError("Unable to delete access key", err.Error())
+	}
+}
+
+// ImportState imports a key by access key ID. Its secret is not available.
+func (r *accessKeyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("access_key_id"), req, resp)
+}
+
+// newAccessKeyModel converts a server key to a model. The server does not
+// return the secret, so prior's is kept.
+func newAccessKeyModel(ctx context.Context, key *adapters.AccessKey, prior accessKeyModel) (accessKeyModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	model := accessKeyModel{
+		PrincipalID:     types.StringValue(key.PrincipalID),
+		Roles:           types.ListNull(types.StringType),
+		Description:     optionalString(key.Description, prior.Description),
+		AccessKeyID:     types.StringValue(key.AccessKeyID),
+		SecretAccessKey: prior.SecretAccessKey,
+		CreatedAt:       types.StringValue(key.CreatedAt.UTC().Format(time.RFC3339)),
+	}
+	if len(key.Roles) > 0 || !prior.Roles.IsNull() {
+		model.Roles, diags = types.ListValueFrom(ctx, types.StringType, append([]string{}, key.Roles...))
+	}
+	if model.SecretAccessKey.IsUnknown() {
+		model.SecretAccessKey = types.StringNull()
+	}
+	return model, diags
+}
@@ -0,0 +1,241 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package provider
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/lifecycle"
+)
+
+var (
+	_ resource.ResourceWithConfigure      = (*lifecyclePolicyResource)(nil)
+	_ resource.ResourceWithImportState    = (*lifecyclePolicyResource)(nil)
+	_ resource.ResourceWithValidateConfig = (*lifecyclePolicyResource)(nil)
+)
+
+// lifecyclePolicyResource manages objstore_lifecycle_policy.
+type lifecyclePolicyResource struct {
+	client Client
+}
+
+// lifecyclePolicyModel is an objstore_lifecycle_policy block.
+type lifecyclePolicyModel struct {
+	ID            types.String `tfsdk:"id"`
+	Prefix        types.String `tfsdk:"prefix"`
+	RetentionDays types.Int64  `tfsdk:"retention_days"`
+	Action        types.String `tfsdk:"action"`
+	StorageClass  types.String `tfsdk:"storage_class"`
+}
+
+// NewLifecyclePolicyResource returns the objstore_lifecycle_policy resource.
+func NewLifecyclePolicyResource() resource.Resource {
+	return &lifecyclePolicyResource{}
+}
+
+// Metadata returns the resource type name.
+func (r *lifecyclePolicyResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_lifecycle_policy"
+}
+
+// Schema defines the resource's attributes.
+func (r *lifecyclePolicyResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "A lifecycle policy that deletes, archives or transitions objects under a prefix once they reach an age.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description:   "Policy ID. Changing it replaces the policy.",
+				Required:      true,
+				PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
+			},
+			"prefix": schema.StringAttribute{
+				Description: "Key prefix the policy applies to; empty for every object.",
+				Optional:    true,
+			},
+			"retention_days": schema.Int64Attribute{
+				Description: "Age in days after which the action is taken.",
+				Required:    true,
+			},
+			"action": schema.StringAttribute{
+				Description: "delete, archive or transition.",
+				Required:    true,
+			},
+			"storage_class": schema.StringAttribute{
+				Description: "Storage class objects move to when action is transition.",
+				Optional:    true,
+			},
+		},
+	}
+}
+
+// Configure stores the provider's client.
+func (r *lifecyclePolicyResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.client = configureClient(req, resp)
+}
+
+// ValidateConfig checks the policy at plan time, once its values are known.
+func (r *lifecyclePolicyResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var model lifecyclePolicyModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() || !model.known() {
+		return
+	}
+	if _, err := model.policy(); err != nil {
+		resp.Diagnostics.AddError("Invalid lifecycle policy", err.Error())
+	}
+}
+
+// Create adds the policy.
+func (r *lifecyclePolicyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan lifecyclePolicyModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	policy, err := plan.policy()
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid lifecycle policy", err.Error())
+		return
+	}
+	if err := r.client.AddPolicy(ctx, policy); err != nil {
+		resp.Diagnostics.AddError("Unable to create lifecycle policy", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Read refreshes the policy from the server, removing it from state when
+// it no longer exists.
+func (r *lifecyclePolicyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state lifecyclePolicyModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	policy, err := findLifecyclePolicy(ctx, r.client, state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to read lifecycle policy", err.Error())
+		return
+	}
+	if policy == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, newLifecyclePolicyModel(*policy, state))...)
+}
+
+// Update replaces the policy. Servers have no update call, so the policy
+// is removed and added again.
+func (r *lifecyclePolicyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan lifecyclePolicyModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	policy, err := plan.policy()
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid lifecycle policy", err.Error())
+		return
+	}
+	if err := r.client.RemovePolicy(ctx, policy.ID); err != nil && !isNotFound(err) {
+		resp.Diagnostics.AddError("Unable to update lifecycle policy", err.Error())
+		return
+	}
+	if err := r.client.AddPolicy(ctx, policy); err != nil {
+		resp.Diagnostics.AddError("Unable to update lifecycle policy", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Delete removes the policy.
+func (r *lifecyclePolicyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state lifecyclePolicyModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if err := r.client.RemovePolicy(ctx, state.ID.ValueString()); err != nil && !isNotFound(err) {
+		resp.Diagnostics.AddError("Unable to delete lifecycle policy", err.Error())
+	}
+}
+
+// ImportState imports a policy by ID.
+func (r *lifecyclePolicyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// known reports whether every attribute is known.
+func (m lifecyclePolicyModel) known() bool {
+	return !m.ID.IsUnknown() && !m.Prefix.IsUnknown() && !m.RetentionDays.IsUnknown() &&
+		!m.Action.IsUnknown() && !m.StorageClass.IsUnknown()
+}
+
+// policy converts the model to a validated lifecycle policy.
+func (m lifecyclePolicyModel) policy() (common.LifecyclePolicy, error) {
+	rule := lifecycle.Rule{
+		ID:            m.ID.ValueString(),
+		Prefix:        m.Prefix.ValueString(),
+		RetentionDays: int(m.RetentionDays.ValueInt64()),
+		Action:        m.Action.ValueString(),
+		StorageClass:  m.StorageClass.ValueString(),
+	}
+	return rule.Policy()
+}
+
+// newLifecyclePolicyModel converts a server policy to a model. Optional
+// attributes left out of prior stay null when the server reports them
+// empty, so they do not show as changes.
+func newLifecyclePolicyModel(policy common.LifecyclePolicy, prior lifecyclePolicyModel) lifecyclePolicyModel {
+	return lifecyclePolicyModel{
+		ID:            types.StringValue(policy.ID),
+		Prefix:        optionalString(policy.Prefix, prior.Prefix),
+		RetentionDays: types.Int64Value(int64(policy.Retention / (24 * time.Hour))),
+		Action:        types.StringValue(policy.Action),
+		StorageClass:  optionalString(policy.StorageClass, prior.StorageClass),
+	}
+}
+
+// findLifecyclePolicy returns the policy with the given ID, or nil when
+// the server has none.
+func findLifecyclePolicy(ctx context.Context, c Client, id string) (*common.LifecyclePolicy, error) {
+	policies, err := c.GetPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range policies {
+		if policies[i].ID == id {
+			return &policies[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// optionalString returns value, or null when value is empty and prior is
+// null.
+func optionalString(value string, prior types.String) types.String {
+	if value == "" && prior.IsNull() {
+		return types.StringNull()
+	}
+	return types.StringValue(value)
+}
@@ -0,0 +1,205 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package provider implements the objstore Terraform provider. Resources
+// manage lifecycle policies, replication policies, quotas, webhooks and
+// SigV4 access keys through a server's /api/v1 management endpoints, the
+// same ones used by the objstore CLI.
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/jeremyhahn/go-objstore/pkg/cli/client"
+	"github.com/jeremyhahn/go-objstore/pkg/reconcile"
+)
+
+// Environment variables read when the provider block leaves a setting out.
+const (
+	EnvEndpoint = "OBJSTORE_ENDPOINT"
+	EnvToken    = "OBJSTORE_TOKEN"
+)
+
+// Client is the management API the resources use. *client.RESTClient
+// implements it.
+type Client interface {
+	reconcile.PolicyStore
+	reconcile.ReplicationStore
+	reconcile.QuotaStore
+	reconcile.WebhookStore
+	client.AccessKeyManager
+}
+
+// objstoreProvider is the provider implementation.
+type objstoreProvider struct {
+	version string
+
+	// newClient builds the client from the provider configuration; tests
+	// replace it.
+	newClient func(*client.Config) (Client, error)
+}
+
+// providerModel is the provider block.
+type providerModel struct {
+	Endpoint           types.String `tfsdk:"endpoint"`
+	Token              types.String `tfsdk:"token"`
+	CAFile             types.String `tfsdk:"ca_file"`
+	InsecureSkipVerify types.Bool   `tfsdk:"insecure_skip_verify"`
+	Timeout            types.String `tfsdk:"timeout"`
+}
+
+// New returns a function that creates the provider, for providerserver.
+func New(version string) func() provider.Provider {
+	return func() provider.Provider {
+		return &objstoreProvider{
+			version: version,
+			newClient: func(config *client.Config) (Client, error) {
+				return client.NewRESTClient(config)
+			},
+		}
+	}
+}
+
+// Metadata returns the provider type name.
+func (p *objstoreProvider) Metadata(_ context.Context, _ provider.MetadataRequest, resp *provider.MetadataResponse) {
+	resp.TypeName = "objstore"
+	resp.Version = p.version
+}
+
+// Schema defines the provider block.
+func (p *objstoreProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp *provider.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages lifecycle policies, replication policies, quotas, webhooks and access keys on an objstore server.",
+		Attributes: map[string]schema.Attribute{
+			"endpoint": schema.StringAttribute{
+				Description: "URL of the server's REST API, such as https://objstore.example.com:8080. Defaults to $" + EnvEndpoint + ".",
+				Optional:    true,
+			},
+			"token": schema.StringAttribute{
+				Description: "Bearer token sent with every request. Defaults to $" + EnvToken + ".",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"ca_file": schema.StringAttribute{
+				Description: "PEM file of CA certificates trusted in addition to the system roots.",
+				Optional:    true,
+			},
+			"insecure_skip_verify": schema.BoolAttribute{
+				Description: "Skip server certificate verification. Development only.",
+				Optional:    true,
+			},
+			"timeout": schema.StringAttribute{
+				Description: "How long to wait for the server to respond to a request, such as 30s.",
+				Optional:    true,
+			},
+		},
+	}
+}
+
+// Configure builds the API client shared by the resources.
+func (p *objstoreProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	var config providerModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	clientConfig := &client.Config{
+		ServerURL:          stringOrEnv(config.Endpoint, EnvEndpoint),
+		Protocol:           "rest",
+		Token:              stringOrEnv(config.Token, EnvToken),
+		CAFile:             config.CAFile.ValueString(),
+		InsecureSkipVerify: config.InsecureSkipVerify.ValueBool(),
+	}
+	if clientConfig.ServerURL == "" {
+		resp.Diagnostics.AddAttributeError(path.Root("endpoint"), "Missing endpoint",
+			"Set endpoint in the provider block or the "+EnvEndpoint+" environment variable.")
+		return
+	}
+	if timeout := config.Timeout.ValueString(); timeout != "" {
+		d, err := time.ParseDuration(timeout)
+		if err != nil || d <= 0 {
+			resp.Diagnostics.AddAttributeError(path.Root("timeout"), "Invalid timeout",
+				fmt.Sprintf("%q is not a positive duration such as 30s.", timeout))
+			return
+		}
+		clientConfig.Timeout = d
+	}
+
+	c, err := p.newClient(clientConfig)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to create objstore client", err.Error())
+		return
+	}
+	resp.ResourceData = c
+}
+
+// Resources lists the resources the provider manages.
+func (p *objstoreProvider) Resources(context.Context) []func() resource.Resource {
+	return []func() resource.Resource{
+		NewLifecyclePolicyResource,
+		NewReplicationPolicyResource,
+		NewQuotaResource,
+		NewWebhookResource,
+		NewAccessKeyResource,
+	}
+}
+
+// DataSources lists the provider's data sources; there are none.
+func (p *objstoreProvider) DataSources(context.Context) []func() datasource.DataSource {
+	return nil
+}
+
+// stringOrEnv returns the configured value, or the environment variable
+// when it is not set.
+func stringOrEnv(value types.String, env string) string {
+	if !value.IsNull() && !value.IsUnknown() {
+		return value.ValueString()
+	}
+	return os.Getenv(env)
+}
+
+// configureClient extracts the provider's client in a resource's
+// Configure. It is nil while Terraform validates configuration before the
+// provider is configured.
+func configureClient(req resource.ConfigureRequest, resp *resource.ConfigureResponse) Client {
+	if req.ProviderData == nil {
+		return nil
+	}
+	c, ok := req.ProviderData.(Client)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data",
+			fmt.Sprintf("Expected an objstore client, got %T.", req.ProviderData))
+		return nil
+	}
+	return c
+}
+
+// isNotFound reports whether the server answered 404, such as when a
+// resource was deleted outside Terraform.
+func isNotFound(err error) bool {
+	return errors.Is(err, client.ErrServerError) &&
+		strings.HasPrefix(err.Error(), client.ErrServerError.Error()+" 404")
+}
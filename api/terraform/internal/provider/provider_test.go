@@ -0,0 +1,546 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/cli/client"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/events"
+	"github.com/jeremyhahn/go-objstore/pkg/overlay"
+)
+
+// fakeClient keeps the server's configuration in memory.
+type fakeClient struct {
+	policies    map[string]common.LifecyclePolicy
+	replication map[string]common.ReplicationPolicy
+	quotas      map[string]overlay.Quota
+	webhooks    map[string]events.Rule
+	accessKeys  map[string]adapters.AccessKey
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{
+		policies:    map[string]common.LifecyclePolicy{},
+		replication: map[string]common.ReplicationPolicy{},
+		quotas:      map[string]overlay.Quota{},
+		webhooks:    map[string]events.Rule{},
+		accessKeys:  map[string]adapters.AccessKey{},
+	}
+}
+
+var errNotFound = fmt.Errorf("%w 404: not found", client.ErrServerError)
+
+func (f *fakeClient) GetPolicies(context.Context) ([]common.LifecyclePolicy, error) {
+	policies := make([]common.LifecyclePolicy, 0, len(f.policies))
+	for _, p := range f.policies {
+		policies = append(policies, p)
+	}
+	return policies, nil
+}
+
+func (f *fakeClient) AddPolicy(_ context.Context, policy common.LifecyclePolicy) error {
+	f.policies[policy.ID] = policy
+	return nil
+}
+
+func (f *fakeClient) RemovePolicy(_ context.Context, id string) error {
+	if _, ok := f.policies[id]; !ok {
+		return errNotFound
+	}
+	delete(f.policies, id)
+	return nil
+}
+
+func (f *fakeClient) GetReplicationPolicies(context.Context) ([]common.ReplicationPolicy, error) {
+	policies := make([]common.ReplicationPolicy, 0, len(f.replication))
+	for _, p := range f.replication {
+		policies = append(policies, p)
+	}
+	return policies, nil
+}
+
+func (f *fakeClient) AddReplicationPolicy(_ context.Context, policy common.ReplicationPolicy) error {
+	f.replication[policy.ID] = policy
+	return nil
+}
+
+func (f *fakeClient) RemoveReplicationPolicy(_ context.Context, id string) error {
+	if _, ok := f.replication[id]; !ok {
+		return errNotFound
+	}
+	delete(f.replication, id)
+	return nil
+}
+
+func (f *fakeClient) ListQuotas(context.Context) (map[string]overlay.Quota, error) {
+	return maps.Clone(f.quotas), nil
+}
+
+func (f *fakeClient) SetQuota(_ context.Context, prefix string, quota overlay.Quota) error {
+	f.quotas[prefix] = quota
+	return nil
+}
+
+func (f *fakeClient) RemoveQuota(_ context.Context, prefix string) error {
+	if _, ok := f.quotas[prefix]; !ok {
+		return errNotFound
+	}
+	delete(f.quotas, prefix)
+	return nil
+}
+
+func (f *fakeClient) ListWebhooks(context.Context) ([]events.Rule, error) {
+	return slices.Collect(maps.Values(f.webhooks)), nil
+}
+
+func (f *fakeClient) SetWebhook(_ context.Context, rule events.Rule) error {
+	f.webhooks[rule.ID] = rule
+	return nil
+}
+
+func (f *fakeClient) DeleteWebhook(_ context.Context, id string) error {
+	if _, ok := f.webhooks[id]; !ok {
+		return errNotFound
+	}
+	delete(f.webhooks, id)
+	return nil
+}
+
+func (f *fakeClient) ListAccessKeys(context.Context) ([]adapters.AccessKey, error) {
+	return slices.Collect(maps.Values(f.accessKeys)), nil
+}
+
+func (f *fakeClient) CreateAccessKey(_ context.Context, key adapters.AccessKey) (*adapters.AccessKey, error) {
+	key.AccessKeyID = fmt.Sprintf("OSK%d", len(f.accessKeys)+1)
+	key.CreatedAt = time.Date(2025, 11, 5, 10, 0, 0, 0, time.UTC)
+	f.accessKeys[key.AccessKeyID] = key
+	key.SecretAccessKey = "secret-" + key.AccessKeyID
+	return &key, nil
+}
+
+func (f *fakeClient) GetAccessKey(_ context.Context, id string) (*adapters.AccessKey, error) {
+	key, ok := f.accessKeys[id]
+	if !ok {
+		return nil, errNotFound
+	}
+	return &key, nil
+}
+
+func (f *fakeClient) DeleteAccessKey(_ context.Context, id string) error {
+	if _, ok := f.accessKeys[id]; !ok {
+		return errNotFound
+	}
+	delete(f.accessKeys, id)
+	return nil
+}
+
+// harness drives a resource through the plugin framework's requests.
+type harness struct {
+	t      *testing.T
+	ctx    context.Context
+	res    resource.Resource
+	schema resource.SchemaResponse
+}
+
+func newHarness(t *testing.T, res resource.Resource, c Client) *harness {
+	t.Helper()
+	h := &harness{t: t, ctx: context.Background(), res: res}
+	res.Schema(h.ctx, resource.SchemaRequest{}, &h.schema)
+	if diags := h.schema.Schema.ValidateImplementation(h.ctx); diags.HasError() {
+		t.Fatalf("schema: %v", diags)
+	}
+	var resp resource.ConfigureResponse
+	res.(resource.ResourceWithConfigure).Configure(h.ctx, resource.ConfigureRequest{ProviderData: c}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Configure(): %v", resp.Diagnostics)
+	}
+	return h
+}
+
+// state encodes a model as resource state.
+func (h *harness) state(model any) tfsdk.State {
+	h.t.Helper()
+	state := tfsdk.State{Schema: h.schema.Schema}
+	if diags := state.Set(h.ctx, model); diags.HasError() {
+		h.t.Fatalf("State.Set(): %v", diags)
+	}
+	return state
+}
+
+func (h *harness) create(model any) tfsdk.State {
+	h.t.Helper()
+	plan := h.state(model)
+	resp := resource.CreateResponse{State: tfsdk.State{Schema: h.schema.Schema}}
+	h.res.Create(h.ctx, resource.CreateRequest{Plan: tfsdk.Plan{Schema: plan.Schema, Raw: plan.Raw}}, &resp)
+	if resp.Diagnostics.HasError() {
+		h.t.Fatalf("Create(): %v", resp.Diagnostics)
+	}
+	return resp.State
+}
+
+func (h *harness) update(prior tfsdk.State, model any) tfsdk.State {
+	h.t.Helper()
+	plan := h.state(model)
+	resp := resource.UpdateResponse{State: prior}
+	h.res.Update(h.ctx, resource.UpdateRequest{Plan: tfsdk.Plan{Schema: plan.Schema, Raw: plan.Raw}, State: prior}, &resp)
+	if resp.Diagnostics.HasError() {
+		h.t.Fatalf("Update(): %v", resp.Diagnostics)
+	}
+	return resp.State
+}
+
+// read refreshes state; the result is null when the resource is gone.
+func (h *harness) read(state tfsdk.State) tfsdk.State {
+	h.t.Helper()
+	resp := resource.ReadResponse{State: state}
+	h.res.Read(h.ctx, resource.ReadRequest{State: state}, &resp)
+	if resp.Diagnostics.HasError() {
+		h.t.Fatalf("Read(): %v", resp.Diagnostics)
+	}
+	return resp.State
+}
+
+func (h *harness) delete(state tfsdk.State) {
+	h.t.Helper()
+	resp := resource.DeleteResponse{State: state}
+	h.res.Delete(h.ctx, resource.DeleteRequest{State: state}, &resp)
+	if resp.Diagnostics.HasError() {
+		h.t.Fatalf("Delete(): %v", resp.Diagnostics)
+	}
+}
+
+func TestLifecyclePolicyResource(t *testing.T) {
+	c := newFakeClient()
+	h := newHarness(t, NewLifecyclePolicyResource(), c)
+
+	model := lifecyclePolicyModel{
+		ID:            types.StringValue("logs"),
+		Prefix:        types.StringValue("logs/"),
+		RetentionDays: types.Int64Value(30),
+		Action:        types.StringValue("delete"),
+		StorageClass:  types.StringNull(),
+	}
+	state := h.create(model)
+	if p := c.policies["logs"]; p.Retention != 30*24*time.Hour || p.Prefix != "logs/" {
+		t.Fatalf("server policy = %+v", p)
+	}
+
+	// A refresh with no changes on the server leaves state as it was
+	var refreshed lifecyclePolicyModel
+	h.read(state).Get(h.ctx, &refreshed)
+	if refreshed != model {
+		t.Errorf("Read() = %+v, want %+v", refreshed, model)
+	}
+
+	model.Action = types.StringValue(common.ActionTransition)
+	model.StorageClass = types.StringValue("GLACIER")
+	state = h.update(state, model)
+	if p := c.policies["logs"]; p.Action != common.ActionTransition || p.StorageClass != "GLACIER" {
+		t.Errorf("server policy after Update() = %+v", p)
+	}
+
+	// Changed outside Terraform, the drift shows in state
+	p := c.policies["logs"]
+	p.Retention = 90 * 24 * time.Hour
+	c.policies["logs"] = p
+	h.read(state).Get(h.ctx, &refreshed)
+	if refreshed.RetentionDays.ValueInt64() != 90 {
+		t.Errorf("Read() retention_days = %v, want 90", refreshed.RetentionDays)
+	}
+
+	h.delete(state)
+	if len(c.policies) != 0 {
+		t.Errorf("policies after Delete() = %v", c.policies)
+	}
+	if !h.read(state).Raw.IsNull() {
+		t.Error("Read() of a deleted policy kept it in state")
+	}
+	h.delete(state) // already gone
+}
+
+func TestLifecyclePolicyValidateConfig(t *testing.T) {
+	h := newHarness(t, NewLifecyclePolicyResource(), newFakeClient())
+	config := h.state(lifecyclePolicyModel{
+		ID:            types.StringValue("logs"),
+		Prefix:        types.StringNull(),
+		RetentionDays: types.Int64Value(30),
+		Action:        types.StringValue("shred"),
+		StorageClass:  types.StringNull(),
+	})
+	var resp resource.ValidateConfigResponse
+	h.res.(resource.ResourceWithValidateConfig).ValidateConfig(h.ctx,
+		resource.ValidateConfigRequest{Config: tfsdk.Config{Schema: config.Schema, Raw: config.Raw}}, &resp)
+	if !resp.Diagnostics.HasError() {
+		t.Error("ValidateConfig() accepted an unknown action")
+	}
+}
+
+func TestReplicationPolicyResource(t *testing.T) {
+	c := newFakeClient()
+	h := newHarness(t, NewReplicationPolicyResource(), c)
+
+	settings, _ := types.MapValueFrom(h.ctx, types.StringType, map[string]string{"bucket": "backups"})
+	model := replicationPolicyModel{
+		ID:                  types.StringValue("backup"),
+		SourceBackend:       types.StringValue("local"),
+		SourceSettings:      types.MapNull(types.StringType),
+		SourcePrefix:        types.StringNull(),
+		DestinationBackend:  types.StringValue("s3"),
+		DestinationSettings: settings,
+		CheckInterval:       types.StringValue("60m"),
+		Enabled:             types.BoolValue(true),
+		Mode:                types.StringValue("transparent"),
+		WriteOnce:           types.BoolValue(false),
+	}
+	state := h.create(model)
+	if p := c.replication["backup"]; p.CheckInterval != time.Hour || p.DestinationSettings["bucket"] != "backups" || !p.Enabled {
+		t.Fatalf("server policy = %+v", p)
+	}
+
+	// 60m and the server's 1h0m0s are the same interval
+	var refreshed replicationPolicyModel
+	h.read(state).Get(h.ctx, &refreshed)
+	if !refreshed.CheckInterval.Equal(model.CheckInterval) || !refreshed.SourceSettings.IsNull() ||
+		!refreshed.DestinationSettings.Equal(settings) {
+		t.Errorf("Read() = %+v, want %+v", refreshed, model)
+	}
+
+	model.Enabled = types.BoolValue(false)
+	model.Mode = types.StringValue("opaque")
+	state = h.update(state, model)
+	if p := c.replication["backup"]; p.Enabled || p.ReplicationMode != common.ReplicationModeOpaque {
+		t.Errorf("server policy after Update() = %+v", p)
+	}
+
+	h.delete(state)
+	if !h.read(state).Raw.IsNull() {
+		t.Error("Read() of a deleted policy kept it in state")
+	}
+
+	model.CheckInterval = types.StringValue("often")
+	if _, diags := model.policy(h.ctx); !diags.HasError() {
+		t.Error("policy() accepted an invalid check_interval")
+	}
+}
+
+func TestQuotaResource(t *testing.T) {
+	c := newFakeClient()
+	h := newHarness(t, NewQuotaResource(), c)
+
+	model := quotaModel{Prefix: types.StringValue("tenants/acme/"), MaxBytes: types.Int64Value(1024), MaxObjects: types.Int64Value(0)}
+	state := h.create(model)
+	if q := c.quotas["tenants/acme/"]; q.MaxBytes != 1024 {
+		t.Fatalf("server quota = %+v", q)
+	}
+
+	model.MaxObjects = types.Int64Value(10)
+	state = h.update(state, model)
+	if q := c.quotas["tenants/acme/"]; q.MaxObjects != 10 {
+		t.Errorf("server quota after Update() = %+v", q)
+	}
+
+	// Changed outside Terraform, the drift shows in state
+	c.quotas["tenants/acme/"] = overlay.Quota{MaxBytes: 2048, MaxObjects: 10}
+	var refreshed quotaModel
+	h.read(state).Get(h.ctx, &refreshed)
+	if refreshed.MaxBytes.ValueInt64() != 2048 {
+		t.Errorf("Read() max_bytes = %v, want 2048", refreshed.MaxBytes)
+	}
+
+	h.delete(state)
+	if len(c.quotas) != 0 || !h.read(state).Raw.IsNull() {
+		t.Errorf("quota not deleted: %v", c.quotas)
+	}
+	h.delete(state) // already gone
+
+	config := h.state(quotaModel{Prefix: types.StringValue("x/"), MaxBytes: types.Int64Value(-1), MaxObjects: types.Int64Null()})
+	var resp resource.ValidateConfigResponse
+	h.res.(resource.ResourceWithValidateConfig).ValidateConfig(h.ctx,
+		resource.ValidateConfigRequest{Config: tfsdk.Config{Schema: config.Schema, Raw: config.Raw}}, &resp)
+	if !resp.Diagnostics.HasError() {
+		t.Error("ValidateConfig() accepted a negative quota")
+	}
+}
+
+func TestWebhookResource(t *testing.T) {
+	c := newFakeClient()
+	h := newHarness(t, NewWebhookResource(), c)
+
+	settings, _ := types.MapValueFrom(h.ctx, types.StringType, map[string]string{"webhookUrl": "https://hooks.example.com/a"})
+	model := webhookModel{
+		ID:           types.StringValue("uploads"),
+		Events:       types.ListNull(types.StringType),
+		Prefix:       types.StringValue("uploads/"),
+		Suffix:       types.StringNull(),
+		SinkType:     types.StringValue("slack"),
+		SinkSettings: settings,
+	}
+	state := h.create(model)
+	if r := c.webhooks["uploads"]; r.Prefix != "uploads/" || r.Sink.Settings["webhookUrl"] != "https://hooks.example.com/a" {
+		t.Fatalf("server rule = %+v", r)
+	}
+
+	// A refresh with no changes on the server leaves state as it was
+	var refreshed webhookModel
+	h.read(state).Get(h.ctx, &refreshed)
+	if !refreshed.Events.IsNull() || !refreshed.Suffix.IsNull() || !refreshed.SinkSettings.Equal(settings) {
+		t.Errorf("Read() = %+v, want %+v", refreshed, model)
+	}
+
+	model.Events, _ = types.ListValueFrom(h.ctx, types.StringType, []string{"s3:ObjectCreated:*"})
+	state = h.update(state, model)
+	if r := c.webhooks["uploads"]; len(r.Events) != 1 {
+		t.Errorf("server rule after Update() = %+v", r)
+	}
+
+	h.delete(state)
+	if len(c.webhooks) != 0 || !h.read(state).Raw.IsNull() {
+		t.Errorf("webhook not deleted: %v", c.webhooks)
+	}
+
+	model.Events, _ = types.ListValueFrom(h.ctx, types.StringType, []string{"s3:Nothing"})
+	if _, diags := model.rule(h.ctx); !diags.HasError() {
+		t.Error("rule() accepted an unknown event")
+	}
+}
+
+func TestAccessKeyResource(t *testing.T) {
+	c := newFakeClient()
+	h := newHarness(t, NewAccessKeyResource(), c)
+
+	roles, _ := types.ListValueFrom(h.ctx, types.StringType, []string{"writer"})
+	state := h.create(accessKeyModel{
+		PrincipalID:     types.StringValue("ci"),
+		Roles:           roles,
+		Description:     types.StringNull(),
+		AccessKeyID:     types.StringUnknown(),
+		SecretAccessKey: types.StringUnknown(),
+		CreatedAt:       types.StringUnknown(),
+	})
+	var created accessKeyModel
+	state.Get(h.ctx, &created)
+	if created.AccessKeyID.ValueString() != "OSK1" || created.SecretAccessKey.ValueString() != "secret-OSK1" ||
+		created.CreatedAt.ValueString() != "2025-11-05T10:00:00Z" {
+		t.Fatalf("Create() state = %+v", created)
+	}
+
+	// The server does not return the secret, so refreshes keep it
+	var refreshed accessKeyModel
+	h.read(state).Get(h.ctx, &refreshed)
+	if !refreshed.SecretAccessKey.Equal(created.SecretAccessKey) || !refreshed.Roles.Equal(roles) || !refreshed.Description.IsNull() {
+		t.Errorf("Read() = %+v, want %+v", refreshed, created)
+	}
+
+	h.delete(state)
+	if len(c.accessKeys) != 0 || !h.read(state).Raw.IsNull() {
+		t.Errorf("access key not deleted: %v", c.accessKeys)
+	}
+	h.delete(state) // already revoked
+}
+
+func TestProviderConfigure(t *testing.T) {
+	ctx := context.Background()
+	p := New("test")().(*objstoreProvider)
+	var schemaResp provider.SchemaResponse
+	p.Schema(ctx, provider.SchemaRequest{}, &schemaResp)
+	if diags := schemaResp.Schema.ValidateImplementation(ctx); diags.HasError() {
+		t.Fatalf("schema: %v", diags)
+	}
+
+	configure := func(model providerModel) provider.ConfigureResponse {
+		state := tfsdk.State{Schema: schemaResp.Schema}
+		if diags := state.Set(ctx, model); diags.HasError() {
+			t.Fatal(diags)
+		}
+		var resp provider.ConfigureResponse
+		p.Configure(ctx, provider.ConfigureRequest{Config: tfsdk.Config{Schema: state.Schema, Raw: state.Raw}}, &resp)
+		return resp
+	}
+
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`{"policies":[],"count":0}`))
+	}))
+	defer server.Close()
+
+	t.Setenv(EnvEndpoint, server.URL)
+	t.Setenv(EnvToken, "secret")
+	resp := configure(providerModel{
+		Endpoint:           types.StringNull(),
+		Token:              types.StringNull(),
+		CAFile:             types.StringNull(),
+		InsecureSkipVerify: types.BoolNull(),
+		Timeout:            types.StringValue("5s"),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Configure() = %v", resp.Diagnostics)
+	}
+	c, ok := resp.ResourceData.(Client)
+	if !ok {
+		t.Fatalf("ResourceData = %T, want a Client", resp.ResourceData)
+	}
+	if _, err := c.GetPolicies(ctx); err != nil || auth != "Bearer secret" {
+		t.Errorf("GetPolicies() = %v, Authorization %q", err, auth)
+	}
+
+	t.Setenv(EnvEndpoint, "")
+	resp = configure(providerModel{
+		Endpoint:           types.StringNull(),
+		Token:              types.StringNull(),
+		CAFile:             types.StringNull(),
+		InsecureSkipVerify: types.BoolNull(),
+		Timeout:            types.StringNull(),
+	})
+	if !resp.Diagnostics.HasError() {
+		t.Error("Configure() without an endpoint succeeded")
+	}
+}
+
+func TestIsNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/policies/missing" {
+			http.Error(w, "policy not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	c, err := client.NewRESTClient(&client.Config{ServerURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.RemovePolicy(context.Background(), "missing"); !isNotFound(err) {
+		t.Errorf("isNotFound(%v) = false", err)
+	}
+	if err := c.RemovePolicy(context.Background(), "other"); err == nil || isNotFound(err) {
+		t.Errorf("isNotFound(%v) = true", err)
+	}
+}
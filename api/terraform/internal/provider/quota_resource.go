@@ -0,0 +1,175 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/jeremyhahn/go-objstore/pkg/overlay"
+)
+
+var (
+	_ resource.ResourceWithConfigure      = (*quotaResource)(nil)
+	_ resource.ResourceWithImportState    = (*quotaResource)(nil)
+	_ resource.ResourceWithValidateConfig = (*quotaResource)(nil)
+)
+
+// quotaResource manages objstore_quota.
+type quotaResource struct {
+	client Client
+}
+
+// quotaModel is an objstore_quota block.
+type quotaModel struct {
+	Prefix     types.String `tfsdk:"prefix"`
+	MaxBytes   types.Int64  `tfsdk:"max_bytes"`
+	MaxObjects types.Int64  `tfsdk:"max_objects"`
+}
+
+// NewQuotaResource returns the objstore_quota resource.
+func NewQuotaResource() resource.Resource {
+	return &quotaResource{}
+}
+
+// Metadata returns the resource type name.
+func (r *quotaResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_quota"
+}
+
+// Schema defines the resource's attributes.
+func (r *quotaResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "A limit on the data stored under a key prefix. The server must be started with --overlays; quotas set by Terraform last until the server restarts.",
+		Attributes: map[string]schema.Attribute{
+			"prefix": schema.StringAttribute{
+				Description:   "Key prefix the quota applies to. Changing it replaces the quota.",
+				Required:      true,
+				PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
+			},
+			"max_bytes": schema.Int64Attribute{
+				Description: "Total stored bytes under the prefix; 0, the default, is unlimited.",
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(0),
+			},
+			"max_objects": schema.Int64Attribute{
+				Description: "Objects under the prefix; 0, the default, is unlimited.",
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(0),
+			},
+		},
+	}
+}
+
+// Configure stores the provider's client.
+func (r *quotaResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.client = configureClient(req, resp)
+}
+
+// ValidateConfig rejects negative limits at plan time.
+func (r *quotaResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var model quotaModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if model.MaxBytes.ValueInt64() < 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("max_bytes"), "Invalid quota", "max_bytes cannot be negative.")
+	}
+	if model.MaxObjects.ValueInt64() < 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("max_objects"), "Invalid quota", "max_objects cannot be negative.")
+	}
+}
+
+// Create sets the quota.
+func (r *quotaResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan quotaModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if err := r.client.SetQuota(ctx, plan.Prefix.ValueString(), plan.quota()); err != nil {
+		resp.Diagnostics.AddError("Unable to create quota", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Read refreshes the quota from the server, removing it from state when it
+// no longer exists.
+func (r *quotaResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state quotaModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	quotas, err := r.client.ListQuotas(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to read quota", err.Error())
+		return
+	}
+	quota, ok := quotas[state.Prefix.ValueString()]
+	if !ok {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	state.MaxBytes = types.Int64Value(quota.MaxBytes)
+	state.MaxObjects = types.Int64Value(quota.MaxObjects)
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// Update sets the new limits.
+func (r *quotaResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan quotaModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if err := r.client.SetQuota(ctx, plan.Prefix.ValueString(), plan.quota()); err != nil {
+		resp.Diagnostics.AddError("Unable to update quota", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Delete removes the quota.
+func (r *quotaResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state quotaModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if err := r.client.RemoveQuota(ctx, state.Prefix.ValueString()); err != nil && !isNotFound(err) {
+		resp.Diagnostics.AddError("Unable to delete quota", err.Error())
+	}
+}
+
+// ImportState imports a quota by prefix.
+func (r *quotaResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("prefix"), req, resp)
+}
+
+// quota converts the model to the limits sent to the server.
+func (m quotaModel) quota() overlay.Quota {
+	return overlay.Quota{MaxBytes: m.MaxBytes.ValueInt64(), MaxObjects: m.MaxObjects.ValueInt64()}
+}
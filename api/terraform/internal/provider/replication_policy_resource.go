@@ -0,0 +1,323 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package provider
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/reconcile"
+)
+
+var (
+	_ resource.ResourceWithConfigure      = (*replicationPolicyResource)(nil)
+	_ resource.ResourceWithImportState    = (*replicationPolicyResource)(nil)
+	_ resource.ResourceWithValidateConfig = (*replicationPolicyResource)(nil)
+)
+
+// replicationPolicyResource manages objstore_replication_policy.
+type replicationPolicyResource struct {
+	client Client
+}
+
+// replicationPolicyModel is an objstore_replication_policy block.
+type replicationPolicyModel struct {
+	ID                  types.String `tfsdk:"id"`
+	SourceBackend       types.String `tfsdk:"source_backend"`
+	SourceSettings      types.Map    `tfsdk:"source_settings"`
+	SourcePrefix        types.String `tfsdk:"source_prefix"`
+	DestinationBackend  types.String `tfsdk:"destination_backend"`
+	DestinationSettings types.Map    `tfsdk:"destination_settings"`
+	CheckInterval       types.String `tfsdk:"check_interval"`
+	Enabled             types.Bool   `tfsdk:"enabled"`
+	Mode                types.String `tfsdk:"mode"`
+	WriteOnce           types.Bool   `tfsdk:"write_once"`
+}
+
+// NewReplicationPolicyResource returns the objstore_replication_policy
+// resource.
+func NewReplicationPolicyResource() resource.Resource {
+	return &replicationPolicyResource{}
+}
+
+// Metadata returns the resource type name.
+func (r *replicationPolicyResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_replication_policy"
+}
+
+// Schema defines the resource's attributes.
+func (r *replicationPolicyResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "A replication policy that copies objects from a source backend to a destination backend.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description:   "Policy ID. Changing it replaces the policy.",
+				Required:      true,
+				PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
+			},
+			"source_backend": schema.StringAttribute{
+				Description: "Source backend type, such as local or s3.",
+				Required:    true,
+			},
+			"source_settings": schema.MapAttribute{
+				Description: "Source backend settings. These can hold credentials and are kept out of plan output.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"source_prefix": schema.StringAttribute{
+				Description: "Only keys under this prefix are replicated.",
+				Optional:    true,
+			},
+			"destination_backend": schema.StringAttribute{
+				Description: "Destination backend type.",
+				Required:    true,
+			},
+			"destination_settings": schema.MapAttribute{
+				Description: "Destination backend settings, kept out of plan output.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"check_interval": schema.StringAttribute{
+				Description: "How often the source is checked for changes, such as 5m.",
+				Required:    true,
+			},
+			"enabled": schema.BoolAttribute{
+				Description: "Whether the policy runs. Defaults to true.",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+			},
+			"mode": schema.StringAttribute{
+				Description: "transparent (the default) or opaque.",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(string(common.ReplicationModeTransparent)),
+			},
+			"write_once": schema.BoolAttribute{
+				Description: "Copy each key once and never overwrite it at the destination.",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+		},
+	}
+}
+
+// Configure stores the provider's client.
+func (r *replicationPolicyResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.client = configureClient(req, resp)
+}
+
+// ValidateConfig checks the policy at plan time, once its values are known.
+func (r *replicationPolicyResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var model replicationPolicyModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() || !model.known() {
+		return
+	}
+	if _, diags := model.policy(ctx); diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+	}
+}
+
+// Create adds the policy.
+func (r *replicationPolicyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan replicationPolicyModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	policy, diags := plan.policy(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if err := r.client.AddReplicationPolicy(ctx, policy); err != nil {
+		resp.Diagnostics.AddError("Unable to create replication policy", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Read refreshes the policy from the server, removing it from state when
+// it no longer exists.
+func (r *replicationPolicyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state replicationPolicyModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	policy, err := findReplicationPolicy(ctx, r.client, state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to read replication policy", err.Error())
+		return
+	}
+	if policy == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	model, diags := newReplicationPolicyModel(ctx, *policy, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, model)...)
+}
+
+// Update replaces the policy. Servers have no update call, so the policy
+// is removed and added again, which resets its last sync time.
+func (r *replicationPolicyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan replicationPolicyModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	policy, diags := plan.policy(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if err := r.client.RemoveReplicationPolicy(ctx, policy.ID); err != nil && !isNotFound(err) {
+		resp.Diagnostics.AddError("Unable to update replication policy", err.Error())
+		return
+	}
+	if err := r.client.AddReplicationPolicy(ctx, policy); err != nil {
+		resp.Diagnostics.AddError("Unable to update replication policy", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Delete removes the policy.
+func (r *replicationPolicyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state replicationPolicyModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if err := r.client.RemoveReplicationPolicy(ctx, state.ID.ValueString()); err != nil && !isNotFound(err) {
+		resp.Diagnostics.AddError("Unable to delete replication policy", err.Error())
+	}
+}
+
+// ImportState imports a policy by ID.
+func (r *replicationPolicyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// known reports whether every attribute is known.
+func (m replicationPolicyModel) known() bool {
+	return !m.ID.IsUnknown() && !m.SourceBackend.IsUnknown() && !m.SourceSettings.IsUnknown() &&
+		!m.SourcePrefix.IsUnknown() && !m.DestinationBackend.IsUnknown() &&
+		!m.DestinationSettings.IsUnknown() && !m.CheckInterval.IsUnknown() &&
+		!m.Enabled.IsUnknown() && !m.Mode.IsUnknown() && !m.WriteOnce.IsUnknown()
+}
+
+// policy converts the model to a validated replication policy.
+func (m replicationPolicyModel) policy(ctx context.Context) (common.ReplicationPolicy, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	r := reconcile.Replication{
+		ID:                 m.ID.ValueString(),
+		SourceBackend:      m.SourceBackend.ValueString(),
+		SourcePrefix:       m.SourcePrefix.ValueString(),
+		DestinationBackend: m.DestinationBackend.ValueString(),
+		CheckInterval:      m.CheckInterval.ValueString(),
+		Mode:               common.ReplicationMode(m.Mode.ValueString()),
+		WriteOnce:          m.WriteOnce.ValueBool(),
+	}
+	if !m.Enabled.IsNull() {
+		enabled := m.Enabled.ValueBool()
+		r.Enabled = &enabled
+	}
+	if !m.SourceSettings.IsNull() {
+		diags.Append(m.SourceSettings.ElementsAs(ctx, &r.SourceSettings, false)...)
+	}
+	if !m.DestinationSettings.IsNull() {
+		diags.Append(m.DestinationSettings.ElementsAs(ctx, &r.DestinationSettings, false)...)
+	}
+	if diags.HasError() {
+		return common.ReplicationPolicy{}, diags
+	}
+	policy, err := r.Policy()
+	if err != nil {
+		diags.AddError("Invalid replication policy", err.Error())
+	}
+	return policy, diags
+}
+
+// newReplicationPolicyModel converts a server policy to a model, keeping
+// prior's spelling of the check interval when it is the same duration.
+func newReplicationPolicyModel(ctx context.Context, policy common.ReplicationPolicy, prior replicationPolicyModel) (replicationPolicyModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	mode := policy.ReplicationMode
+	if mode == "" {
+		mode = common.ReplicationModeTransparent
+	}
+	model := replicationPolicyModel{
+		ID:                 types.StringValue(policy.ID),
+		SourceBackend:      types.StringValue(policy.SourceBackend),
+		SourcePrefix:       optionalString(policy.SourcePrefix, prior.SourcePrefix),
+		DestinationBackend: types.StringValue(policy.DestinationBackend),
+		CheckInterval:      types.StringValue(policy.CheckInterval.String()),
+		Enabled:            types.BoolValue(policy.Enabled),
+		Mode:               types.StringValue(string(mode)),
+		WriteOnce:          types.BoolValue(policy.WriteOnce),
+	}
+	if d, err := time.ParseDuration(prior.CheckInterval.ValueString()); err == nil && d == policy.CheckInterval {
+		model.CheckInterval = prior.CheckInterval
+	}
+	var d diag.Diagnostics
+	model.SourceSettings, d = optionalMap(ctx, policy.SourceSettings, prior.SourceSettings)
+	diags.Append(d...)
+	model.DestinationSettings, d = optionalMap(ctx, policy.DestinationSettings, prior.DestinationSettings)
+	diags.Append(d...)
+	return model, diags
+}
+
+// findReplicationPolicy returns the policy with the given ID, or nil when
+// the server has none.
+func findReplicationPolicy(ctx context.Context, c Client, id string) (*common.ReplicationPolicy, error) {
+	policies, err := c.GetReplicationPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range policies {
+		if policies[i].ID == id {
+			return &policies[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// optionalMap returns value as a map, or null when it is empty and prior
+// is null.
+func optionalMap(ctx context.Context, value map[string]string, prior types.Map) (types.Map, diag.Diagnostics) {
+	if len(value) == 0 && prior.IsNull() {
+		return types.MapNull(types.StringType), nil
+	}
+	return types.MapValueFrom(ctx, types.StringType, value)
+}
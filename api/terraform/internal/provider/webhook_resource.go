@@ -0,0 +1,260 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/jeremyhahn/go-objstore/pkg/events"
+)
+
+var (
+	_ resource.ResourceWithConfigure      = (*webhookResource)(nil)
+	_ resource.ResourceWithImportState    = (*webhookResource)(nil)
+	_ resource.ResourceWithValidateConfig = (*webhookResource)(nil)
+)
+
+// webhookResource manages objstore_webhook.
+type webhookResource struct {
+	client Client
+}
+
+// webhookModel is an objstore_webhook block.
+type webhookModel struct {
+	ID           types.String `tfsdk:"id"`
+	Events       types.List   `tfsdk:"events"`
+	Prefix       types.String `tfsdk:"prefix"`
+	Suffix       types.String `tfsdk:"suffix"`
+	SinkType     types.String `tfsdk:"sink_type"`
+	SinkSettings types.Map    `tfsdk:"sink_settings"`
+}
+
+// NewWebhookResource returns the objstore_webhook resource.
+func NewWebhookResource() resource.Resource {
+	return &webhookResource{}
+}
+
+// Metadata returns the resource type name.
+func (r *webhookResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_webhook"
+}
+
+// Schema defines the resource's attributes.
+func (r *webhookResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "An event notification rule that sends matching object changes to a sink. The server must be started with --notifications; rules set by Terraform last until the server restarts.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description:   "Rule ID, reported as the configurationId of notifications. Changing it replaces the rule.",
+				Required:      true,
+				PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
+			},
+			"events": schema.ListAttribute{
+				Description: "Events sent, such as s3:ObjectCreated:*. Empty sends every event.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"prefix": schema.StringAttribute{
+				Description: "Only keys beginning with this prefix are sent.",
+				Optional:    true,
+			},
+			"suffix": schema.StringAttribute{
+				Description: "Only keys ending with this suffix are sent.",
+				Optional:    true,
+			},
+			"sink_type": schema.StringAttribute{
+				Description: "Sink notifications are delivered to: slack, teams, sns, sqs, eventbridge, pubsub, kafka or smtp.",
+				Required:    true,
+			},
+			"sink_settings": schema.MapAttribute{
+				Description: "Sink settings, such as a webhook URL or queue URL. These can hold credentials and are kept out of plan output.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Sensitive:   true,
+			},
+		},
+	}
+}
+
+// Configure stores the provider's client.
+func (r *webhookResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.client = configureClient(req, resp)
+}
+
+// ValidateConfig checks the rule at plan time, once its values are known.
+func (r *webhookResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var model webhookModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() || !model.known() {
+		return
+	}
+	if _, diags := model.rule(ctx); diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+	}
+}
+
+// Create adds the rule.
+func (r *webhookResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan webhookModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	rule, diags := plan.rule(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if err := r.client.SetWebhook(ctx, rule); err != nil {
+		resp.Diagnostics.AddError("Unable to create webhook", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Read refreshes the rule from the server, removing it from state when it
+// no longer exists.
+func (r *webhookResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state webhookModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	rule, err := findWebhook(ctx, r.client, state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to read webhook", err.Error())
+		return
+	}
+	if rule == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	model, diags := newWebhookModel(ctx, rule, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, model)...)
+}
+
+// Update replaces the rule.
+func (r *webhookResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan webhookModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	rule, diags := plan.rule(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if err := r.client.SetWebhook(ctx, rule); err != nil {
+		resp.Diagnostics.AddError("Unable to update webhook", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Delete removes the rule.
+func (r *webhookResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state webhookModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if err := r.client.DeleteWebhook(ctx, state.ID.ValueString()); err != nil && !isNotFound(err) {
+		resp.Diagnostics.AddError("Unable to delete webhook", err.Error())
+	}
+}
+
+// ImportState imports a rule by ID.
+func (r *webhookResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// known reports whether every attribute is known.
+func (m webhookModel) known() bool {
+	return !m.ID.IsUnknown() && !m.Events.IsUnknown() && !m.Prefix.IsUnknown() &&
+		!m.Suffix.IsUnknown() && !m.SinkType.IsUnknown() && !m.SinkSettings.IsUnknown()
+}
+
+// rule converts the model to a validated notification rule.
+func (m webhookModel) rule(ctx context.Context) (events.Rule, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	rule := events.Rule{
+		ID:     m.ID.ValueString(),
+		Prefix: m.Prefix.ValueString(),
+		Suffix: m.Suffix.ValueString(),
+		Sink:   events.SinkConfig{Type: m.SinkType.ValueString()},
+	}
+	if !m.Events.IsNull() {
+		diags.Append(m.Events.ElementsAs(ctx, &rule.Events, false)...)
+	}
+	if !m.SinkSettings.IsNull() {
+		diags.Append(m.SinkSettings.ElementsAs(ctx, &rule.Sink.Settings, false)...)
+	}
+	if diags.HasError() {
+		return events.Rule{}, diags
+	}
+	if err := (&events.Config{Rules: []events.Rule{rule}}).Validate(); err != nil {
+		diags.AddError("Invalid webhook", err.Error())
+	}
+	return rule, diags
+}
+
+// newWebhookModel converts a server rule to a model, keeping prior's null
+// optional attributes when the server reports them empty.
+func newWebhookModel(ctx context.Context, rule *events.Rule, prior webhookModel) (webhookModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	model := webhookModel{
+		ID:       types.StringValue(rule.ID),
+		Events:   types.ListNull(types.StringType),
+		Prefix:   optionalString(rule.Prefix, prior.Prefix),
+		Suffix:   optionalString(rule.Suffix, prior.Suffix),
+		SinkType: types.StringValue(rule.Sink.Type),
+	}
+	if len(rule.Events) > 0 || !prior.Events.IsNull() {
+		var d diag.Diagnostics
+		model.Events, d = types.ListValueFrom(ctx, types.StringType, append([]string{}, rule.Events...))
+		diags.Append(d...)
+	}
+	var d diag.Diagnostics
+	model.SinkSettings, d = optionalMap(ctx, rule.Sink.Settings, prior.SinkSettings)
+	diags.Append(d...)
+	return model, diags
+}
+
+// findWebhook returns the rule with the given ID, or nil when the server
+// has none.
+func findWebhook(ctx context.Context, c Client, id string) (*events.Rule, error) {
+	rules, err := c.ListWebhooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range rules {
+		if rules[i].ID == id {
+			return &rules[i], nil
+		}
+	}
+	return nil, nil
+}
@@ -20,7 +20,11 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
+	"github.com/jeremyhahn/go-objstore/pkg/changefeed"
+	"github.com/jeremyhahn/go-objstore/pkg/contentpolicy"
+	"github.com/jeremyhahn/go-objstore/pkg/keypolicy"
 	"github.com/jeremyhahn/go-objstore/pkg/objstore"
 	grpcserver "github.com/jeremyhahn/go-objstore/pkg/server/grpc"
 )
@@ -32,6 +36,20 @@ func main() {
 	storagePath := flag.String("path", "/tmp/objstore", "Storage path for local backend")
 	tlsCert := flag.String("tls-cert", "", "TLS certificate file")
 	tlsKey := flag.String("tls-key", "", "TLS key file")
+	reflection := flag.Bool("reflection", false, "Enable gRPC server reflection (grpcurl, grpcui)")
+	health := flag.Bool("health", true, "Enable the standard gRPC health checking service")
+	maxMsgSize := flag.Int("max-msg-size", 10*1024*1024, "Maximum gRPC message size in bytes")
+	keepAliveTime := flag.Duration("keepalive-time", 2*time.Hour, "Interval between server keepalive pings")
+	keepAliveTimeout := flag.Duration("keepalive-timeout", 20*time.Second, "Time to wait for a keepalive ping ack")
+	keepAliveMinTime := flag.Duration("keepalive-min-time", 5*time.Minute, "Minimum interval allowed between client keepalive pings")
+	keepAlivePermit := flag.Bool("keepalive-permit-without-stream", false, "Allow client keepalive pings without active streams")
+	maxConnAge := flag.Duration("max-connection-age", 0, "Close connections after this age so clients rebalance (0 = never)")
+	maxConnIdle := flag.Duration("max-connection-idle", 0, "Close connections idle for this long (0 = never)")
+	enableChanges := flag.Bool("changes", false, "Record changes in an ordered journal and serve the change feed API")
+	changesJournal := flag.String("changes-journal", "", "File to persist the change journal to (default: in memory)")
+	changesMaxEntries := flag.Int("changes-max-entries", changefeed.DefaultMaxEntries, "Changes retained for consumers to catch up on")
+	contentSniff := flag.Bool("content-sniff", true, "Detect and store the content type of objects uploaded without one from the key's extension or first 512 bytes")
+	keyPolicyFile := flag.String("key-policy", "", "YAML or JSON file of per-prefix key naming rules")
 
 	flag.Parse()
 
@@ -62,10 +80,59 @@ func main() {
 		slog.Info("Replication enabled", "policy_file", policyPath)
 	}
 
+	// Enable content type detection before the change feed so journaled
+	// writes carry the detected type.
+	if *contentSniff {
+		if err := objstore.EnableContentPolicy("", &contentpolicy.Policy{Sniff: true}); err != nil {
+			slog.Error("Failed to enable content type detection", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	// Enforce key naming rules before the change feed so rejected writes are
+	// never journaled.
+	if *keyPolicyFile != "" {
+		policy, err := keypolicy.LoadFile(*keyPolicyFile)
+		if err != nil {
+			slog.Error("Failed to load key policy", "error", err)
+			os.Exit(1)
+		}
+		if err := objstore.EnableKeyPolicy("", policy); err != nil {
+			slog.Error("Failed to enable key policy", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("Key policy enabled", "policy_file", *keyPolicyFile, "rules", len(policy.Rules))
+	}
+
+	// Enable the change feed after replication: the backend is wrapped by
+	// the journal.
+	var journal *changefeed.Journal
+	if *enableChanges {
+		if err := objstore.EnableChangeFeed("", &objstore.ChangeFeedConfig{
+			JournalPath: *changesJournal,
+			MaxEntries:  *changesMaxEntries,
+		}); err != nil {
+			slog.Error("Failed to enable change feed", "error", err)
+			os.Exit(1)
+		}
+		var err error
+		if journal, err = objstore.ChangeFeed(""); err != nil {
+			slog.Error("Failed to enable change feed", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("Change feed enabled", "journal_file", *changesJournal, "max_entries", *changesMaxEntries)
+	}
+
 	// Create server options
 	opts := []grpcserver.ServerOption{
 		grpcserver.WithAddress(*addr),
 		grpcserver.WithBackend(""), // Use default backend
+		grpcserver.WithReflection(*reflection),
+		grpcserver.WithHealthCheck(*health),
+		grpcserver.WithMaxMessageSize(*maxMsgSize),
+		grpcserver.WithKeepAlive(*keepAliveTime, *keepAliveTimeout),
+		grpcserver.WithKeepAliveEnforcement(*keepAliveMinTime, *keepAlivePermit),
+		grpcserver.WithConnectionAge(*maxConnIdle, *maxConnAge, 0),
 	}
 
 	// Add TLS if certificates provided
@@ -109,5 +176,10 @@ func main() {
 
 	slog.Info("Shutting down gRPC server")
 	server.Stop()
+	if journal != nil {
+		if err := journal.Close(); err != nil {
+			slog.Error("Failed to close change journal", "error", err)
+		}
+	}
 	slog.Info("Server stopped")
 }
@@ -0,0 +1,76 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Command objstore-operator runs objstore-server on Kubernetes from
+// ObjectStoreBackend resources and applies the LifecyclePolicy and
+// ReplicationPolicy resources that reference them.
+package main
+
+import (
+	"context"
+	"flag"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/kube"
+	"github.com/jeremyhahn/go-objstore/pkg/operator"
+	"github.com/jeremyhahn/go-objstore/pkg/version"
+)
+
+func main() {
+	namespace := flag.String("namespace", "", "Namespace to watch (default: all namespaces)")
+	resync := flag.Duration("resync", 30*time.Second, "Interval between reconcile passes")
+	serverImage := flag.String("server-image", "ghcr.io/jeremyhahn/objstore-server:"+version.Version, "objstore-server image of backends that do not set one")
+	kubeAPI := flag.String("kube-api", "", "Kubernetes API URL, such as http://127.0.0.1:8001 for kubectl proxy (default: the in-cluster service account)")
+	flag.Parse()
+
+	config := &kube.Config{Host: *kubeAPI}
+	if *kubeAPI == "" {
+		var err error
+		if config, err = kube.InClusterConfig(); err != nil {
+			slog.Error("Failed to load the in-cluster configuration; use -kube-api outside a cluster", "error", err)
+			os.Exit(1)
+		}
+	}
+	client, err := kube.NewClient(config)
+	if err != nil {
+		slog.Error("Failed to create the Kubernetes client", "error", err)
+		os.Exit(1)
+	}
+
+	op := operator.New(client, operator.Options{
+		Namespace:   *namespace,
+		ServerImage: *serverImage,
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	slog.Info("objstore operator started", "namespace", *namespace, "resync", *resync, "server_image", *serverImage)
+	ticker := time.NewTicker(*resync)
+	defer ticker.Stop()
+	for {
+		if err := op.Reconcile(ctx); err != nil && ctx.Err() == nil {
+			slog.Error("Reconcile failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			slog.Info("objstore operator stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
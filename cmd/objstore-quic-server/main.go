@@ -23,6 +23,8 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/jeremyhahn/go-objstore/pkg/contentpolicy"
+	"github.com/jeremyhahn/go-objstore/pkg/keypolicy"
 	"github.com/jeremyhahn/go-objstore/pkg/objstore"
 	quicserver "github.com/jeremyhahn/go-objstore/pkg/server/quic"
 )
@@ -39,6 +41,8 @@ var (
 	idleTimeout      = flag.Duration("idletimeout", 60*time.Second, "Idle timeout")
 	maxStreams       = flag.Int64("maxstreams", 100, "Maximum bidirectional streams per connection")
 	enableSelfSigned = flag.Bool("selfsigned", false, "Use self-signed certificate (for testing only)")
+	contentSniff     = flag.Bool("content-sniff", true, "Detect and store the content type of objects uploaded without one from the key's extension or first 512 bytes")
+	keyPolicyFile    = flag.String("key-policy", "", "YAML or JSON file of per-prefix key naming rules")
 )
 
 func main() {
@@ -71,6 +75,27 @@ func main() {
 		slog.Info("Replication enabled", "policy_file", policyPath)
 	}
 
+	if *contentSniff {
+		if err := objstore.EnableContentPolicy("", &contentpolicy.Policy{Sniff: true}); err != nil {
+			slog.Error("Failed to enable content type detection", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	// Enforce key naming rules
+	if *keyPolicyFile != "" {
+		policy, err := keypolicy.LoadFile(*keyPolicyFile)
+		if err != nil {
+			slog.Error("Failed to load key policy", "error", err)
+			os.Exit(1)
+		}
+		if err := objstore.EnableKeyPolicy("", policy); err != nil {
+			slog.Error("Failed to enable key policy", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("Key policy enabled", "policy_file", *keyPolicyFile, "rules", len(policy.Rules))
+	}
+
 	// Configure TLS
 	var tlsConfig *tls.Config
 	var err error
@@ -20,11 +20,22 @@ import (
 	"log/slog"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/jeremyhahn/go-objstore/pkg/access"
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/alias"
+	"github.com/jeremyhahn/go-objstore/pkg/changefeed"
+	"github.com/jeremyhahn/go-objstore/pkg/contentpolicy"
+	"github.com/jeremyhahn/go-objstore/pkg/events"
+	"github.com/jeremyhahn/go-objstore/pkg/keypolicy"
+	"github.com/jeremyhahn/go-objstore/pkg/locks"
+	"github.com/jeremyhahn/go-objstore/pkg/manifest"
 	"github.com/jeremyhahn/go-objstore/pkg/objstore"
 	restserver "github.com/jeremyhahn/go-objstore/pkg/server/rest"
+	"github.com/jeremyhahn/go-objstore/pkg/transform"
 )
 
 func main() {
@@ -34,6 +45,32 @@ func main() {
 	backend := flag.String("backend", "local", "Storage backend (local, s3, gcs, azure)")
 	storagePath := flag.String("path", "/tmp/objstore", "Storage path for local backend")
 	metricsPublic := flag.Bool("metrics-public", false, "Expose /metrics without authorization")
+	enableUI := flag.Bool("ui", false, "Serve the admin UI at /ui")
+	enableSearch := flag.Bool("search", false, "Index keys and metadata and serve the search API")
+	searchIndex := flag.String("search-index", "", "File to persist the search index to (default: in memory)")
+	enableAccess := flag.Bool("access-tracking", false, "Record when objects are last read and include read times in the analyze API")
+	accessSampleRate := flag.Float64("access-sample-rate", 1, "Fraction of reads recorded when tracking access (0-1)")
+	accessFile := flag.String("access-file", "", "File to persist last read times to (default: in memory)")
+	accessFlushInterval := flag.Duration("access-flush-interval", access.DefaultFlushInterval, "Time between saves of last read times to --access-file")
+	contentPolicyFile := flag.String("content-policy", "", "YAML or JSON file of per-prefix content type allow/deny rules")
+	contentSniff := flag.Bool("content-sniff", true, "Detect and store the content type of objects uploaded without one from the key's extension or first 512 bytes")
+	keyPolicyFile := flag.String("key-policy", "", "YAML or JSON file of per-prefix key naming rules")
+	enableChecksums := flag.Bool("checksums", false, "Record SHA-256 and MD5 digests of uploaded objects for change detection across backends")
+	enableRetention := flag.Bool("retention", false, "Enable legal holds and the deletion approval API")
+	protectedPrefixes := flag.String("protected-prefixes", "", "Comma-separated key prefixes whose deletes need a second principal's approval (implies --retention)")
+	retentionFile := flag.String("retention-file", "", "File to persist legal holds and deletion requests to (default: <path>/.retention.json)")
+	enableLocks := flag.Bool("locks", false, "Enable the advisory lock API")
+	locksPrefix := flag.String("locks-prefix", locks.DefaultPrefix, "Reserved key prefix lock objects are stored under")
+	enableManifests := flag.Bool("manifests", false, "Enable the dataset manifest API")
+	manifestsPrefix := flag.String("manifests-prefix", manifest.DefaultPrefix, "Reserved key prefix manifests are stored under")
+	enableAliases := flag.Bool("aliases", false, "Resolve object aliases and enable the alias API")
+	aliasesPrefix := flag.String("aliases-prefix", alias.DefaultPrefix, "Reserved key prefix aliases are stored under")
+	transformsFile := flag.String("transforms", "", "YAML or JSON file of transform presets that generate derived objects such as thumbnails")
+	notificationsFile := flag.String("notifications", "", "YAML or JSON file of event notification rules and sinks (SNS, SQS, EventBridge, Pub/Sub)")
+	enableChanges := flag.Bool("changes", false, "Record changes in an ordered journal and serve the change feed API")
+	changesJournal := flag.String("changes-journal", "", "File to persist the change journal to (default: in memory)")
+	changesMaxEntries := flag.Int("changes-max-entries", changefeed.DefaultMaxEntries, "Changes retained for consumers to catch up on")
+	accessKeysFile := flag.String("access-keys", "", "File of SigV4 access keys issued through the access key API, which this enables")
 
 	flag.Parse()
 
@@ -64,11 +101,206 @@ func main() {
 		slog.Info("Replication enabled", "policy_file", policyPath)
 	}
 
+	// Enable locks before search so lock objects are never indexed.
+	if *enableLocks {
+		if err := objstore.EnableLocks("", *locksPrefix); err != nil {
+			slog.Error("Failed to enable locks", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("Locks enabled", "prefix", *locksPrefix)
+	}
+
+	// Record content digests so replication and caches can compare objects
+	// across backends whose ETags differ.
+	if *enableChecksums {
+		if err := objstore.EnableChecksums(""); err != nil {
+			slog.Error("Failed to enable checksums", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("Checksums enabled")
+	}
+
+	// Enable the content policy before search so rejected objects are never
+	// indexed.
+	if *contentPolicyFile != "" || *contentSniff {
+		policy := &contentpolicy.Policy{}
+		if *contentPolicyFile != "" {
+			var err error
+			if policy, err = contentpolicy.LoadFile(*contentPolicyFile); err != nil {
+				slog.Error("Failed to load content policy", "error", err)
+				os.Exit(1)
+			}
+		}
+		policy.Sniff = policy.Sniff || *contentSniff
+		if err := objstore.EnableContentPolicy("", policy); err != nil {
+			slog.Error("Failed to enable content policy", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("Content policy enabled", "policy_file", *contentPolicyFile, "sniff", policy.Sniff, "rules", len(policy.Rules))
+	}
+
+	// Enforce key naming rules before search so rejected objects are never
+	// indexed.
+	if *keyPolicyFile != "" {
+		policy, err := keypolicy.LoadFile(*keyPolicyFile)
+		if err != nil {
+			slog.Error("Failed to load key policy", "error", err)
+			os.Exit(1)
+		}
+		if err := objstore.EnableKeyPolicy("", policy); err != nil {
+			slog.Error("Failed to enable key policy", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("Key policy enabled", "policy_file", *keyPolicyFile, "rules", len(policy.Rules))
+	}
+
+	// Enable retention before search so pending deletes keep their index entry.
+	if *enableRetention || *protectedPrefixes != "" {
+		statePath := *retentionFile
+		if statePath == "" {
+			statePath = *storagePath + "/.retention.json"
+		}
+		var prefixes []string
+		for _, prefix := range strings.Split(*protectedPrefixes, ",") {
+			if prefix = strings.TrimSpace(prefix); prefix != "" {
+				prefixes = append(prefixes, prefix)
+			}
+		}
+		if err := objstore.EnableRetention("", &objstore.RetentionConfig{
+			ProtectedPrefixes: prefixes,
+			StatePath:         statePath,
+		}); err != nil {
+			slog.Error("Failed to enable retention", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("Retention enabled", "state_file", statePath, "protected_prefixes", prefixes)
+	}
+
+	// Enable manifests before search so manifest documents are never indexed.
+	if *enableManifests {
+		if err := objstore.EnableManifests("", *manifestsPrefix); err != nil {
+			slog.Error("Failed to enable manifests", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("Manifests enabled", "prefix", *manifestsPrefix)
+	}
+
+	// Enable aliases before search so alias objects are never indexed.
+	if *enableAliases {
+		if err := objstore.EnableAliases("", *aliasesPrefix); err != nil {
+			slog.Error("Failed to enable aliases", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("Aliases enabled", "prefix", *aliasesPrefix)
+	}
+
+	// Enable transforms after the wrappers that can refuse a change, so
+	// on_put presets only run for writes that were made.
+	if *transformsFile != "" {
+		cfg, err := transform.LoadFile(*transformsFile)
+		if err != nil {
+			slog.Error("Failed to load transform configuration", "error", err)
+			os.Exit(1)
+		}
+		cfg.OnError = func(preset, key string, err error) {
+			slog.Warn("Failed to generate derived object", "preset", preset, "key", key, "error", err)
+		}
+		if err := objstore.EnableTransforms("", cfg); err != nil {
+			slog.Error("Failed to enable transforms", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("Transforms enabled", "config_file", *transformsFile, "presets", len(cfg.Presets))
+	}
+
+	// Enable notifications after the wrappers that can refuse a change, so
+	// only changes that were made are published.
+	var notifier *events.Notifier
+	if *notificationsFile != "" {
+		cfg, err := events.LoadFile(*notificationsFile)
+		if err != nil {
+			slog.Error("Failed to load notification configuration", "error", err)
+			os.Exit(1)
+		}
+		cfg.OnError = func(rule string, record *events.Record, err error) {
+			slog.Warn("Failed to deliver event notification", "rule", rule, "event", record.EventName,
+				"key", record.S3.Object.DecodedKey(), "error", err)
+		}
+		if err := objstore.EnableNotifications("", cfg); err != nil {
+			slog.Error("Failed to enable notifications", "error", err)
+			os.Exit(1)
+		}
+		if notifier, err = objstore.Notifications(""); err != nil {
+			slog.Error("Failed to enable notifications", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("Notifications enabled", "config_file", *notificationsFile, "rules", len(cfg.Rules))
+	}
+
+	// Enable the change feed after the wrappers that can refuse a change, so
+	// only changes that were made are recorded.
+	var journal *changefeed.Journal
+	if *enableChanges {
+		if err := objstore.EnableChangeFeed("", &objstore.ChangeFeedConfig{
+			JournalPath: *changesJournal,
+			MaxEntries:  *changesMaxEntries,
+		}); err != nil {
+			slog.Error("Failed to enable change feed", "error", err)
+			os.Exit(1)
+		}
+		var err error
+		if journal, err = objstore.ChangeFeed(""); err != nil {
+			slog.Error("Failed to enable change feed", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("Change feed enabled", "journal_file", *changesJournal, "max_entries", *changesMaxEntries)
+	}
+
+	// Enable search after replication: the backend is wrapped by the index.
+	if *enableSearch {
+		if err := objstore.EnableSearch("", &objstore.SearchConfig{
+			IndexPath: *searchIndex,
+		}); err != nil {
+			slog.Warn("Failed to enable search", "error", err)
+		} else {
+			slog.Info("Search enabled", "index_file", *searchIndex)
+		}
+	}
+
+	// Track reads after search and the wrappers that can refuse or redirect
+	// them, so only reads clients make are recorded.
+	var tracker *access.Tracker
+	if *enableAccess {
+		if err := objstore.EnableAccessTracking("", &access.Config{
+			SampleRate:    *accessSampleRate,
+			Path:          *accessFile,
+			FlushInterval: *accessFlushInterval,
+		}); err != nil {
+			slog.Error("Failed to enable access tracking", "error", err)
+			os.Exit(1)
+		}
+		tracker, _ = objstore.AccessTracker("")
+		slog.Info("Access tracking enabled", "sample_rate", *accessSampleRate, "access_file", *accessFile)
+	}
+
 	// Create server configuration
 	config := restserver.DefaultServerConfig()
 	config.Host = *host
 	config.Port = *port
 	config.MetricsPublic = *metricsPublic
+	config.EnableUI = *enableUI
+
+	// Requests signed with an issued access key authenticate as its
+	// principal; other requests are authenticated as before.
+	if *accessKeysFile != "" {
+		keys, err := adapters.NewAccessKeyStore(*accessKeysFile)
+		if err != nil {
+			slog.Error("Failed to load access keys", "error", err)
+			os.Exit(1)
+		}
+		config.AccessKeys = keys
+		config.Authenticator = adapters.NewCompositeAuthenticator(adapters.NewSigV4Authenticator(keys.LookupSecret), config.Authenticator)
+		slog.Info("Access keys enabled", "access_keys_file", *accessKeysFile)
+	}
 
 	// Create and start server (storage param is nil since handler uses facade)
 	server, err := restserver.NewServer(nil, config)
@@ -104,5 +336,20 @@ func main() {
 	if err := server.Shutdown(ctx); err != nil {
 		slog.Error("REST server shutdown error", "error", err)
 	}
+	if notifier != nil {
+		if err := notifier.Close(ctx); err != nil {
+			slog.Error("Failed to deliver queued event notifications", "error", err)
+		}
+	}
+	if journal != nil {
+		if err := journal.Close(); err != nil {
+			slog.Error("Failed to close change journal", "error", err)
+		}
+	}
+	if tracker != nil {
+		if err := tracker.Close(); err != nil {
+			slog.Error("Failed to save last read times", "error", err)
+		}
+	}
 	slog.Info("Server stopped")
 }
@@ -19,28 +19,81 @@ import (
 	"flag"
 	"fmt"
 	"log/slog"
+	"maps"
 	"os"
 	"os/signal"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
 
+	"github.com/jeremyhahn/go-objstore/pkg/access"
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/alias"
 	"github.com/jeremyhahn/go-objstore/pkg/audit"
+	"github.com/jeremyhahn/go-objstore/pkg/changefeed"
 	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/concurrency"
+	"github.com/jeremyhahn/go-objstore/pkg/contentpolicy"
+	"github.com/jeremyhahn/go-objstore/pkg/cost"
+	"github.com/jeremyhahn/go-objstore/pkg/envflag"
+	"github.com/jeremyhahn/go-objstore/pkg/events"
 	"github.com/jeremyhahn/go-objstore/pkg/factory"
+	"github.com/jeremyhahn/go-objstore/pkg/ha"
+	"github.com/jeremyhahn/go-objstore/pkg/jobs"
+	"github.com/jeremyhahn/go-objstore/pkg/keypolicy"
+	"github.com/jeremyhahn/go-objstore/pkg/kube"
+	"github.com/jeremyhahn/go-objstore/pkg/locks"
+	"github.com/jeremyhahn/go-objstore/pkg/manifest"
 	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+	"github.com/jeremyhahn/go-objstore/pkg/overlay"
+	"github.com/jeremyhahn/go-objstore/pkg/residency"
+	"github.com/jeremyhahn/go-objstore/pkg/scrub"
 	grpcserver "github.com/jeremyhahn/go-objstore/pkg/server/grpc"
 	mcpserver "github.com/jeremyhahn/go-objstore/pkg/server/mcp"
 	"github.com/jeremyhahn/go-objstore/pkg/server/middleware"
 	quicserver "github.com/jeremyhahn/go-objstore/pkg/server/quic"
 	restserver "github.com/jeremyhahn/go-objstore/pkg/server/rest"
 	unixserver "github.com/jeremyhahn/go-objstore/pkg/server/unix"
+	"github.com/jeremyhahn/go-objstore/pkg/share"
+	"github.com/jeremyhahn/go-objstore/pkg/stats"
+	"github.com/jeremyhahn/go-objstore/pkg/tasks"
+	"github.com/jeremyhahn/go-objstore/pkg/tombstone"
+	"github.com/jeremyhahn/go-objstore/pkg/transform"
+	"github.com/jeremyhahn/go-objstore/pkg/upload"
 )
 
+// envPrefix prefixes the environment variables that set flags: --rest-port
+// is OBJSTORE_REST_PORT.
+const envPrefix = "OBJSTORE_"
+
+// Leader election methods.
+const (
+	electionBackend    = "backend"
+	electionKubernetes = "kubernetes"
+)
+
+// leaderElector elects the instance that runs background jobs.
+type leaderElector interface {
+	Run(ctx context.Context)
+	IsLeader() bool
+}
+
 func main() {
 	// Backend configuration
 	backend := flag.String("backend", "local", "Storage backend (local, s3, gcs, azure)")
 	basePath := flag.String("path", "/tmp/objstore", "Base path for local storage")
+	backendPlugins := flag.String("backend-plugins", "", "Comma-separated Go plugins (.so) that register additional backend types")
+	backendSettings := make(map[string]string)
+	flag.Func("backend-setting", "Backend setting as key=value, such as bucket=backups (repeatable)", func(s string) error {
+		key, value, err := factory.ParseSetting(s)
+		if err != nil {
+			return err
+		}
+		backendSettings[key] = value
+		return nil
+	})
+	backendSettingsDir := flag.String("backend-settings-dir", "", "Directory of backend settings, one file per setting named after it, such as a mounted Kubernetes Secret")
 
 	// Server selection (all enabled by default)
 	enableGRPC := flag.Bool("grpc", true, "Enable gRPC server")
@@ -51,10 +104,13 @@ func main() {
 
 	// gRPC server flags
 	grpcAddr := flag.String("grpc-addr", ":50051", "gRPC server address")
+	grpcReflection := flag.Bool("grpc-reflection", false, "Enable gRPC server reflection (grpcurl, grpcui)")
+	grpcMaxMsgSize := flag.Int("grpc-max-msg-size", 10*1024*1024, "Maximum gRPC message size in bytes")
 
 	// REST server flags
 	restPort := flag.Int("rest-port", 8080, "REST server port")
 	metricsPublic := flag.Bool("metrics-public", false, "Expose /metrics without authorization")
+	enableUI := flag.Bool("ui", false, "Serve the admin UI at /ui on the REST server")
 
 	// QUIC server flags
 	quicAddr := flag.String("quic-addr", ":4433", "QUIC server address")
@@ -68,15 +124,97 @@ func main() {
 
 	// Unix socket server flags
 	unixSocket := flag.String("unix-socket", "/var/run/objstore.sock", "Unix socket path")
+	unixKeyPrefix := flag.String("unix-key-prefix", "", "Confine the Unix socket to keys under this prefix, such as tenants/acme/; management methods are refused")
+	sidecar := flag.Bool("sidecar", false, "Run as a pod sidecar: serve only the Unix socket (implies --unix and disables gRPC, REST, QUIC and MCP)")
 
 	// Cross-transport middleware flags
 	rateLimit := flag.Bool("rate-limit", false, "Enable rate limiting on all transports")
 	rateLimitRPS := flag.Float64("rate-limit-rps", 100, "Rate limit requests per second")
 	rateLimitBurst := flag.Int("rate-limit-burst", 200, "Rate limit burst size")
 	rateLimitPerClient := flag.Bool("rate-limit-per-client", false, "Rate limit per client instead of globally")
+	idempotency := flag.Bool("idempotency", false, "Deduplicate retried Put/Delete/Archive requests carrying an idempotency key (REST and gRPC)")
+	idempotencyWindow := flag.Duration("idempotency-window", 24*time.Hour, "How long completed idempotent requests are remembered")
 	enableAudit := flag.Bool("audit", true, "Enable audit logging on all transports")
+	auditChain := flag.String("audit-chain", "", "Also append audit events to this hash-chained, tamper-evident log file")
+	auditCheckpointInterval := flag.Int("audit-checkpoint-interval", audit.DefaultCheckpointInterval, "Audit events between checkpoints of the hash-chained log")
+	auditAnchorDir := flag.String("audit-anchor-dir", "", "Directory to anchor hash-chained audit log checkpoints in, outside the audit log's control")
+	enableSearch := flag.Bool("search", false, "Index keys and metadata and serve the search API")
+	searchIndex := flag.String("search-index", "", "File to persist the search index to (default: in memory)")
+	enableAccess := flag.Bool("access-tracking", false, "Record when objects are last read and include read times in the analyze API")
+	accessSampleRate := flag.Float64("access-sample-rate", 1, "Fraction of reads recorded when tracking access (0-1)")
+	accessFile := flag.String("access-file", "", "File to persist last read times to (default: in memory)")
+	accessFlushInterval := flag.Duration("access-flush-interval", access.DefaultFlushInterval, "Time between saves of last read times to --access-file")
+	contentPolicyFile := flag.String("content-policy", "", "YAML or JSON file of per-prefix content type allow/deny rules")
+	contentSniff := flag.Bool("content-sniff", true, "Detect and store the content type of objects uploaded without one from the key's extension or first 512 bytes")
+	keyPolicyFile := flag.String("key-policy", "", "YAML or JSON file of per-prefix key naming rules")
+	enableChecksums := flag.Bool("checksums", false, "Record SHA-256 and MD5 digests of uploaded objects for change detection across backends")
+	enableRetention := flag.Bool("retention", false, "Enable legal holds and the deletion approval API")
+	protectedPrefixes := flag.String("protected-prefixes", "", "Comma-separated key prefixes whose deletes need a second principal's approval (implies --retention)")
+	retentionFile := flag.String("retention-file", "", "File to persist legal holds and deletion requests to (default: <path>/.retention.json)")
+	enableLocks := flag.Bool("locks", false, "Enable the advisory lock API")
+	locksPrefix := flag.String("locks-prefix", locks.DefaultPrefix, "Reserved key prefix lock objects are stored under")
+	enableShares := flag.Bool("shares", false, "Enable public download links and the share link API")
+	sharesPrefix := flag.String("shares-prefix", share.DefaultPrefix, "Reserved key prefix share links are stored under")
+	enableUploads := flag.Bool("uploads", false, "Enable the chunked upload session API")
+	uploadsPrefix := flag.String("uploads-prefix", upload.DefaultPrefix, "Reserved key prefix upload sessions and their chunks are stored under")
+	uploadsInterval := flag.Duration("uploads-interval", time.Hour, "Time between removals of expired upload sessions")
+	reapUploadsAfter := flag.Duration("reap-uploads-after", 0, "Abort uploads left unfinished for this long: multipart uploads, objects staged by atomic puts and upload sessions (0 disables the reaper job)")
+	reapUploadsInterval := flag.Duration("reap-uploads-interval", time.Hour, "Time between runs of the reaper job")
+	enableManifests := flag.Bool("manifests", false, "Enable the dataset manifest API")
+	manifestsPrefix := flag.String("manifests-prefix", manifest.DefaultPrefix, "Reserved key prefix manifests are stored under")
+	enableAliases := flag.Bool("aliases", false, "Resolve object aliases and enable the alias API")
+	aliasesPrefix := flag.String("aliases-prefix", alias.DefaultPrefix, "Reserved key prefix aliases are stored under")
+	costFile := flag.String("cost", "", "YAML or JSON file of per-backend pricing and cost groups; meters usage and serves the cost report API")
+	residencyFile := flag.String("residency", "", "YAML or JSON file pinning key prefixes to allowed regions and backends")
+	overlaysFile := flag.String("overlays", "", "YAML or JSON file of per-prefix overlays (compression, storage class, replication policy, quota)")
+	transformsFile := flag.String("transforms", "", "YAML or JSON file of transform presets that generate derived objects such as thumbnails")
+	notificationsFile := flag.String("notifications", "", "YAML or JSON file of event notification rules and sinks (SNS, SQS, EventBridge, Pub/Sub)")
+	enableChanges := flag.Bool("changes", false, "Record changes in an ordered journal and serve the change feed API")
+	changesJournal := flag.String("changes-journal", "", "File to persist the change journal to (default: in memory)")
+	changesMaxEntries := flag.Int("changes-max-entries", changefeed.DefaultMaxEntries, "Changes retained for consumers to catch up on")
+	accessKeysFile := flag.String("access-keys", "", "File of SigV4 access keys issued through the access key API, which this enables")
+	enableStats := flag.Bool("stats", false, "Take periodic snapshots of object counts and sizes and serve the storage statistics API")
+	statsInterval := flag.Duration("stats-interval", stats.DefaultInterval, "Time between storage statistics snapshots; each lists every object")
+	statsRetention := flag.Duration("stats-retention", stats.DefaultRetention, "How long storage statistics snapshots are kept")
+	statsHistory := flag.String("stats-history", "", "File to persist storage statistics snapshots to (default: in memory)")
+	statsTopPrefixes := flag.Int("stats-top-prefixes", stats.DefaultTopPrefixes, "Largest prefixes recorded in each storage statistics snapshot")
+	haMode := flag.Bool("ha", false, "Share state with other instances through the backend so several servers can run behind a load balancer")
+	haNodeID := flag.String("ha-node-id", "", "Unique ID of this instance in HA mode and leader election (default: host name, the pod name on Kubernetes)")
+	haPrefix := flag.String("ha-prefix", ha.DefaultPrefix, "Reserved key prefix HA coordination state is stored under")
+	haLeaseTTL := flag.Duration("ha-lease-ttl", ha.DefaultLeaseTTL, "How long a leader keeps its lease without renewing it")
+	leaderElection := flag.String("leader-election", "", "How instances elect the one that runs background jobs: backend (a lease on the backend, the default in HA mode) or kubernetes (a coordination.k8s.io Lease)")
+	leaderElectionNamespace := flag.String("leader-election-namespace", "", "Namespace of the Kubernetes Lease (default: the pod's namespace)")
+	leaderElectionLease := flag.String("leader-election-lease", "objstore-server", "Name of the Kubernetes Lease; instances sharing it elect one leader")
+	adaptiveConcurrency := flag.Bool("adaptive-concurrency", false, "Adapt the number of operations in flight against the backend to its latency and throttling, queueing the rest")
+	adaptiveConcurrencyMax := flag.Int("adaptive-concurrency-max", concurrency.DefaultMax, "Highest number of backend operations in flight with --adaptive-concurrency")
+	adaptiveConcurrencyLatency := flag.Duration("adaptive-concurrency-latency", 0, "Treat backend operations slower than this as overload with --adaptive-concurrency (0 reacts to throttling and unavailability only)")
+	adaptiveConcurrencyMaxWait := flag.Duration("adaptive-concurrency-max-wait", 0, "How long an operation waits for a slot before failing as throttled (0 waits until the request ends)")
+	lifecycleInterval := flag.Duration("lifecycle-interval", 0, "Time between lifecycle policy runs on the default backend (0 disables the lifecycle job)")
+	replicationInterval := flag.Duration("replication-interval", 0, "Time between syncs of every enabled replication policy (0 disables the replication job)")
+	replicationLagAlert := flag.Duration("replication-lag-alert", 0, "Publish a Replication:LagExceeded notification after each replication job for policies that have not synced successfully for this long (0 disables)")
+	gcInterval := flag.Duration("gc-interval", time.Hour, "Time between deletions of expired HA coordination records")
+	jobsHistory := flag.String("jobs-history", "", "File to persist background job run history to (default: .jobs-history.json under --path, or the backend in HA mode)")
+	enableTombstones := flag.Bool("tombstones", false, "Defer deletes: hide deleted objects at once and remove them after a grace period, so replicas and caches cannot resurrect them")
+	tombstoneGrace := flag.Duration("tombstone-grace", tombstone.DefaultGrace, "How long deleted objects are kept before they are physically removed")
+	tombstoneRetention := flag.Duration("tombstone-retention", tombstone.DefaultRetention, "How long tombstones are kept after their objects are removed, refusing stale copies")
+	tombstoneInterval := flag.Duration("tombstone-interval", time.Hour, "Time between removals of deleted objects whose grace period has passed")
+	scrubInterval := flag.Duration("scrub-interval", 0, "Time between scrubs that reread every object and check it against its checksum, repairing corrupted objects from replication destinations (0 disables; requires --checksums)")
+	scrubRate := flag.Int64("scrub-rate", scrub.DefaultRate, "Bytes per second scrubs read objects at (negative: unlimited)")
+	enableTasks := flag.Bool("tasks", false, "Run asynchronous archives, replication syncs and notification redeliveries from a durable task queue")
+	tasksDir := flag.String("tasks-dir", "", "Directory to persist queued tasks to (default: .tasks under --path, or the backend in HA mode)")
+	tasksMaxAttempts := flag.Int("tasks-max-attempts", tasks.DefaultMaxAttempts, "Attempts of a task before it is dead-lettered")
+	tasksWorkers := flag.Int("tasks-workers", tasks.DefaultWorkers, "Tasks run at once")
 
 	flag.Parse()
+	if err := envflag.Apply(flag.CommandLine, envPrefix); err != nil {
+		slog.Error("Invalid configuration in the environment", "error", err)
+		os.Exit(1)
+	}
+
+	if *sidecar {
+		*enableUnix = true
+		*enableGRPC, *enableREST, *enableQUIC, *enableMCP = false, false, false, false
+	}
 
 	// Shared middleware configuration applied to every enabled transport.
 	rateLimitConfig := &middleware.RateLimitConfig{
@@ -84,14 +222,62 @@ func main() {
 		Burst:             *rateLimitBurst,
 		PerIP:             *rateLimitPerClient,
 	}
+	idempotencyConfig := middleware.DefaultIdempotencyConfig()
+	idempotencyConfig.Window = *idempotencyWindow
+	idempotencyConfig.Store = middleware.NewMemoryIdempotencyStore()
 	var auditLogger audit.AuditLogger
+	var chainLogger *audit.ChainedAuditLogger
 	if *enableAudit {
 		auditLogger = audit.NewDefaultAuditLogger()
+		if *auditChain != "" {
+			chainConfig := &audit.ChainConfig{
+				Path:               *auditChain,
+				CheckpointInterval: *auditCheckpointInterval,
+				Forward:            auditLogger,
+			}
+			if *auditAnchorDir != "" {
+				anchorStorage, err := factory.NewStorage("local", map[string]string{"path": *auditAnchorDir})
+				if err != nil {
+					slog.Error("Failed to open audit anchor directory", "error", err)
+					os.Exit(1)
+				}
+				chainConfig.Anchor = audit.NewStorageAnchor(anchorStorage, "")
+			}
+			var err error
+			chainLogger, err = audit.NewChainedAuditLogger(chainConfig)
+			if err != nil {
+				slog.Error("Failed to open hash-chained audit log", "error", err)
+				os.Exit(1)
+			}
+			auditLogger = chainLogger
+			slog.Info("Hash-chained audit log enabled", "path", *auditChain)
+		}
+	}
+
+	// Load plugins before creating the backend so --backend can name their types.
+	for _, path := range strings.Split(*backendPlugins, ",") {
+		if path = strings.TrimSpace(path); path == "" {
+			continue
+		}
+		if err := factory.LoadPlugin(path); err != nil {
+			slog.Error("Failed to load backend plugin", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("Backend plugin loaded", "path", path)
 	}
 
 	// Create storage backend
 	settings := make(map[string]string)
 	settings["path"] = *basePath
+	if *backendSettingsDir != "" {
+		fromDir, err := factory.LoadSettingsDir(*backendSettingsDir)
+		if err != nil {
+			slog.Error("Failed to read backend settings", "error", err)
+			os.Exit(1)
+		}
+		maps.Copy(settings, fromDir)
+	}
+	maps.Copy(settings, backendSettings)
 
 	storage, err := factory.NewStorage(*backend, settings)
 	if err != nil {
@@ -108,19 +294,596 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Limit the operations in flight against the backend first, so that
+	// every other wrapper's backend calls take a slot.
+	if *adaptiveConcurrency {
+		if err := objstore.EnableConcurrencyLimit("", &concurrency.Config{
+			Max:              *adaptiveConcurrencyMax,
+			LatencyThreshold: *adaptiveConcurrencyLatency,
+			MaxWait:          *adaptiveConcurrencyMaxWait,
+		}); err != nil {
+			slog.Error("Failed to enable adaptive concurrency", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("Adaptive concurrency enabled", "max", *adaptiveConcurrencyMax, "latency_threshold", *adaptiveConcurrencyLatency)
+	}
+
+	// In HA mode, coordinate with the other instances through the backend
+	// before any other wrapper is enabled, so none sees coordination objects.
+	// jobsCtx stops the leader election at shutdown.
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	replicationConfig := &objstore.ReplicationConfig{
+		PolicyFilePath:  *basePath + "/.replication-policies.json",
+		RunInBackground: false,
+	}
+	var (
+		cluster          *ha.Cluster
+		elector          leaderElector
+		nodeID           = *haNodeID
+		idempotencyStore *ha.IdempotencyStore
+	)
+	if *haMode {
+		var err error
+		cluster, err = objstore.EnableHA("", ha.Config{
+			NodeID:   *haNodeID,
+			Prefix:   *haPrefix,
+			LeaseTTL: *haLeaseTTL,
+		})
+		if err != nil {
+			slog.Error("Failed to enable HA mode", "error", err)
+			os.Exit(1)
+		}
+		nodeID = cluster.NodeID()
+
+		idempotencyStore = cluster.IdempotencyStore()
+		idempotencyConfig.Store = idempotencyStore
+
+		// Policies live on the backend; the leader runs the replication job
+		// for every instance.
+		replicationConfig.PolicyFilePath = ".replication-policies.json"
+		replicationConfig.FileSystem = cluster.FileSystem()
+		replicationConfig.Shared = true
+		slog.Info("HA mode enabled", "node_id", cluster.NodeID(), "prefix", cluster.Prefix(), "lease_ttl", *haLeaseTTL)
+	}
+
+	// Elect the instance that runs background jobs: with a lease on the
+	// backend in HA mode, or with a Lease object on Kubernetes.
+	switch *leaderElection {
+	case "", electionBackend:
+		if cluster != nil {
+			elector = cluster.Elector("jobs")
+		} else if *leaderElection == electionBackend {
+			slog.Error("--leader-election=backend requires --ha")
+			os.Exit(1)
+		}
+	case electionKubernetes:
+		config, err := kube.InClusterConfig()
+		if err != nil {
+			slog.Error("Kubernetes leader election needs to run in a pod", "error", err)
+			os.Exit(1)
+		}
+		client, err := kube.NewClient(config)
+		if err != nil {
+			slog.Error("Failed to create the Kubernetes client", "error", err)
+			os.Exit(1)
+		}
+		lease, err := kube.NewLeaseElector(client, kube.LeaseConfig{
+			Namespace: *leaderElectionNamespace,
+			Name:      *leaderElectionLease,
+			Identity:  nodeID,
+			Duration:  *haLeaseTTL,
+		})
+		if err != nil {
+			slog.Error("Failed to configure Kubernetes leader election", "error", err)
+			os.Exit(1)
+		}
+		elector, nodeID = lease, lease.Identity()
+		if cluster == nil {
+			slog.Warn("Leader election without --ha: each instance keeps its own policies and job history")
+		}
+		slog.Info("Kubernetes leader election enabled", "lease", *leaderElectionLease, "identity", nodeID)
+	default:
+		slog.Error("Invalid --leader-election (must be backend or kubernetes)", "leader_election", *leaderElection)
+		os.Exit(1)
+	}
+	if elector != nil {
+		go elector.Run(jobsCtx)
+	}
+
 	// Enable replication on the default backend so the replication API
 	// (policies, trigger, status) is fully functional. Backends that do not
 	// support a replication manager simply log a warning and continue.
-	replicationPolicyPath := *basePath + "/.replication-policies.json"
-	if err := objstore.EnableReplication("", &objstore.ReplicationConfig{
-		PolicyFilePath:  replicationPolicyPath,
-		RunInBackground: false,
-	}); err != nil {
+	replicationPolicyPath := replicationConfig.PolicyFilePath
+	if err := objstore.EnableReplication("", replicationConfig); err != nil {
 		slog.Warn("Failed to enable replication", "error", err)
 	} else {
 		slog.Info("Replication enabled", "policy_file", replicationPolicyPath)
 	}
 
+	// Meter costs beneath every other wrapper so requests and bytes are
+	// counted as they reach the backend.
+	var meter *cost.Meter
+	if *costFile != "" {
+		cfg, err := cost.LoadFile(*costFile)
+		if err != nil {
+			slog.Error("Failed to load cost configuration", "error", err)
+			os.Exit(1)
+		}
+		if err := objstore.EnableCost(cfg); err != nil {
+			slog.Error("Failed to enable cost metering", "error", err)
+			os.Exit(1)
+		}
+		meter, _ = objstore.Cost()
+		slog.Info("Cost metering enabled", "config_file", *costFile, "state_file", cfg.StatePath)
+	}
+
+	// Enable overlays first so every other wrapper sees objects as written
+	// by clients, before compression.
+	if *overlaysFile != "" {
+		cfg, err := overlay.LoadFile(*overlaysFile)
+		if err != nil {
+			slog.Error("Failed to load overlays", "error", err)
+			os.Exit(1)
+		}
+		if err := objstore.EnableOverlays("", cfg); err != nil {
+			slog.Error("Failed to enable overlays", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("Overlays enabled", "config_file", *overlaysFile, "overlays", len(cfg.Overlays))
+	}
+
+	if *residencyFile != "" {
+		cfg, err := residency.LoadFile(*residencyFile)
+		if err != nil {
+			slog.Error("Failed to load residency rules", "error", err)
+			os.Exit(1)
+		}
+		cfg.AuditLog = auditLogger
+		if err := objstore.EnableResidency(cfg); err != nil {
+			slog.Error("Failed to enable residency enforcement", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("Residency enforcement enabled", "config_file", *residencyFile, "rules", len(cfg.Rules))
+	}
+
+	// Defer deletes before retention, notifications and search, so they see
+	// deletes as requested and never see deleted objects. In HA mode
+	// tombstones live with the coordination state so every instance sees
+	// them.
+	if *enableTombstones {
+		tombstoneConfig := &tombstone.Config{
+			Grace:     *tombstoneGrace,
+			Retention: *tombstoneRetention,
+		}
+		if cluster != nil {
+			tombstoneConfig.Storage = cluster.Storage()
+			tombstoneConfig.Prefix = cluster.Prefix() + "tombstones/"
+			tombstoneConfig.Shared = true
+		}
+		if err := objstore.EnableTombstones("", tombstoneConfig); err != nil {
+			slog.Error("Failed to enable tombstones", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("Tombstones enabled", "grace", *tombstoneGrace, "retention", *tombstoneRetention)
+	}
+
+	// Enable locks before search so lock objects are never indexed.
+	if *enableLocks {
+		if err := objstore.EnableLocks("", *locksPrefix); err != nil {
+			slog.Error("Failed to enable locks", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("Locks enabled", "prefix", *locksPrefix)
+	}
+
+	// Share links are stored on the backend too, so enable them before
+	// search as well; instances sharing the backend share the links.
+	if *enableShares {
+		if err := objstore.EnableShares("", *sharesPrefix); err != nil {
+			slog.Error("Failed to enable share links", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("Share links enabled", "prefix", *sharesPrefix)
+	}
+
+	// Upload sessions are kept on the backend, so they survive restarts
+	// and any instance sharing the backend can continue them.
+	if *enableUploads {
+		if err := objstore.EnableUploads("", *uploadsPrefix); err != nil {
+			slog.Error("Failed to enable upload sessions", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("Upload sessions enabled", "prefix", *uploadsPrefix)
+	}
+
+	// Record content digests so replication and caches can compare objects
+	// across backends whose ETags differ.
+	if *enableChecksums {
+		if err := objstore.EnableChecksums(""); err != nil {
+			slog.Error("Failed to enable checksums", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("Checksums enabled")
+	}
+
+	// Enable the content policy before search so rejected objects are never
+	// indexed.
+	if *contentPolicyFile != "" || *contentSniff {
+		policy := &contentpolicy.Policy{}
+		if *contentPolicyFile != "" {
+			if policy, err = contentpolicy.LoadFile(*contentPolicyFile); err != nil {
+				slog.Error("Failed to load content policy", "error", err)
+				os.Exit(1)
+			}
+		}
+		policy.Sniff = policy.Sniff || *contentSniff
+		if err := objstore.EnableContentPolicy("", policy); err != nil {
+			slog.Error("Failed to enable content policy", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("Content policy enabled", "policy_file", *contentPolicyFile, "sniff", policy.Sniff, "rules", len(policy.Rules))
+	}
+
+	// Enforce key naming rules before search so rejected objects are never
+	// indexed.
+	if *keyPolicyFile != "" {
+		policy, err := keypolicy.LoadFile(*keyPolicyFile)
+		if err != nil {
+			slog.Error("Failed to load key policy", "error", err)
+			os.Exit(1)
+		}
+		if err := objstore.EnableKeyPolicy("", policy); err != nil {
+			slog.Error("Failed to enable key policy", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("Key policy enabled", "policy_file", *keyPolicyFile, "rules", len(policy.Rules))
+	}
+
+	// Enable retention before search so pending deletes keep their index entry.
+	if *enableRetention || *protectedPrefixes != "" {
+		// In HA mode the state is kept on the backend with the policies,
+		// unless a file is named.
+		retentionConfig := &objstore.RetentionConfig{StatePath: *retentionFile}
+		switch {
+		case retentionConfig.StatePath != "":
+		case cluster != nil:
+			retentionConfig.StatePath = ".retention.json"
+			retentionConfig.FileSystem = cluster.FileSystem()
+			retentionConfig.Shared = true
+		default:
+			retentionConfig.StatePath = *basePath + "/.retention.json"
+		}
+		var prefixes []string
+		for _, prefix := range strings.Split(*protectedPrefixes, ",") {
+			if prefix = strings.TrimSpace(prefix); prefix != "" {
+				prefixes = append(prefixes, prefix)
+			}
+		}
+		retentionConfig.ProtectedPrefixes = prefixes
+		if err := objstore.EnableRetention("", retentionConfig); err != nil {
+			slog.Error("Failed to enable retention", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("Retention enabled", "state_file", retentionConfig.StatePath, "shared", retentionConfig.Shared, "protected_prefixes", prefixes)
+	}
+
+	// Enable manifests before search so manifest documents are never indexed.
+	if *enableManifests {
+		if err := objstore.EnableManifests("", *manifestsPrefix); err != nil {
+			slog.Error("Failed to enable manifests", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("Manifests enabled", "prefix", *manifestsPrefix)
+	}
+
+	// Enable aliases before search so alias objects are never indexed.
+	if *enableAliases {
+		if err := objstore.EnableAliases("", *aliasesPrefix); err != nil {
+			slog.Error("Failed to enable aliases", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("Aliases enabled", "prefix", *aliasesPrefix)
+	}
+
+	// Enable transforms after the wrappers that can refuse a change, so
+	// on_put presets only run for writes that were made.
+	if *transformsFile != "" {
+		cfg, err := transform.LoadFile(*transformsFile)
+		if err != nil {
+			slog.Error("Failed to load transform configuration", "error", err)
+			os.Exit(1)
+		}
+		cfg.OnError = func(preset, key string, err error) {
+			slog.Warn("Failed to generate derived object", "preset", preset, "key", key, "error", err)
+		}
+		if err := objstore.EnableTransforms("", cfg); err != nil {
+			slog.Error("Failed to enable transforms", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("Transforms enabled", "config_file", *transformsFile, "presets", len(cfg.Presets))
+	}
+
+	// Enable the task queue before notifications, which hand failed
+	// deliveries to it. In HA mode tasks live on the backend and only the
+	// elected leader runs them.
+	var queue *tasks.Queue
+	if *enableTasks {
+		tasksConfig := &tasks.Config{
+			MaxAttempts: *tasksMaxAttempts,
+			Workers:     *tasksWorkers,
+		}
+		if cluster != nil {
+			tasksConfig.Storage = cluster.Storage()
+			tasksConfig.Prefix = cluster.Prefix() + "tasks/"
+			tasksConfig.Shared = true
+			tasksConfig.IsLeader = elector.IsLeader
+			tasksConfig.Node = nodeID
+		} else {
+			dir := *tasksDir
+			if dir == "" {
+				dir = *basePath
+			}
+			tasksStorage, err := factory.NewStorage("local", map[string]string{"path": dir})
+			if err != nil {
+				slog.Error("Failed to create task queue storage", "error", err)
+				os.Exit(1)
+			}
+			tasksConfig.Storage = tasksStorage
+		}
+		if queue, err = objstore.EnableTasks(tasksConfig); err != nil {
+			slog.Error("Failed to enable the task queue", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("Task queue enabled", "max_attempts", *tasksMaxAttempts, "workers", *tasksWorkers)
+	}
+
+	// Enable notifications after the wrappers that can refuse a change, so
+	// only changes that were made are published.
+	var notifier *events.Notifier
+	if *notificationsFile != "" {
+		cfg, err := events.LoadFile(*notificationsFile)
+		if err != nil {
+			slog.Error("Failed to load notification configuration", "error", err)
+			os.Exit(1)
+		}
+		cfg.Tasks = queue
+		cfg.OnError = func(rule string, record *events.Record, err error) {
+			slog.Warn("Failed to deliver event notification", "rule", rule, "event", record.EventName,
+				"key", record.S3.Object.DecodedKey(), "error", err)
+		}
+		if err := objstore.EnableNotifications("", cfg); err != nil {
+			slog.Error("Failed to enable notifications", "error", err)
+			os.Exit(1)
+		}
+		if notifier, err = objstore.Notifications(""); err != nil {
+			slog.Error("Failed to enable notifications", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("Notifications enabled", "config_file", *notificationsFile, "rules", len(cfg.Rules))
+	}
+
+	// Enable the change feed after the wrappers that can refuse a change, so
+	// only changes that were made are recorded.
+	var journal *changefeed.Journal
+	if *enableChanges {
+		if err := objstore.EnableChangeFeed("", &objstore.ChangeFeedConfig{
+			JournalPath: *changesJournal,
+			MaxEntries:  *changesMaxEntries,
+		}); err != nil {
+			slog.Error("Failed to enable change feed", "error", err)
+			os.Exit(1)
+		}
+		var err error
+		if journal, err = objstore.ChangeFeed(""); err != nil {
+			slog.Error("Failed to enable change feed", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("Change feed enabled", "journal_file", *changesJournal, "max_entries", *changesMaxEntries)
+	}
+
+	// Enable search after replication: the backend is wrapped by the index.
+	if *enableSearch {
+		if err := objstore.EnableSearch("", &objstore.SearchConfig{
+			IndexPath: *searchIndex,
+		}); err != nil {
+			slog.Warn("Failed to enable search", "error", err)
+		} else {
+			slog.Info("Search enabled", "index_file", *searchIndex)
+		}
+	}
+
+	// Track reads after search and the wrappers that can refuse or redirect
+	// them, so only reads clients make are recorded.
+	var tracker *access.Tracker
+	if *enableAccess {
+		if err := objstore.EnableAccessTracking("", &access.Config{
+			SampleRate:    *accessSampleRate,
+			Path:          *accessFile,
+			FlushInterval: *accessFlushInterval,
+		}); err != nil {
+			slog.Error("Failed to enable access tracking", "error", err)
+			os.Exit(1)
+		}
+		tracker, _ = objstore.AccessTracker("")
+		slog.Info("Access tracking enabled", "sample_rate", *accessSampleRate, "access_file", *accessFile)
+	}
+
+	// Take storage statistics last so snapshots list objects as clients see them.
+	var collector *stats.Collector
+	if *enableStats {
+		if err := objstore.EnableStats(&stats.Config{
+			Interval:    *statsInterval,
+			Retention:   *statsRetention,
+			TopPrefixes: *statsTopPrefixes,
+			HistoryPath: *statsHistory,
+			Manual:      true,
+		}); err != nil {
+			slog.Error("Failed to enable storage statistics", "error", err)
+			os.Exit(1)
+		}
+		collector, _ = objstore.StatsCollector()
+		slog.Info("Storage statistics enabled", "interval", *statsInterval, "history_file", *statsHistory)
+	}
+
+	// Run background work from one scheduler. In HA mode only the elected
+	// leader runs jobs and the run history is shared through the backend.
+	var backgroundJobs []jobs.Job
+	if *lifecycleInterval > 0 {
+		backgroundJobs = append(backgroundJobs, jobs.Job{
+			Name:     "lifecycle",
+			Interval: *lifecycleInterval,
+			Run: func(ctx context.Context) (string, error) {
+				n, err := objstore.ApplyPolicies(ctx, "")
+				return fmt.Sprintf("%d objects processed", n), err
+			},
+		})
+	}
+	if *replicationInterval > 0 {
+		backgroundJobs = append(backgroundJobs, jobs.Job{
+			Name:     "replication",
+			Interval: *replicationInterval,
+			Run: func(ctx context.Context) (string, error) {
+				manager, err := objstore.GetReplicationManager("")
+				if err != nil {
+					return "", err
+				}
+				result, err := manager.SyncAll(ctx)
+				if *replicationLagAlert > 0 {
+					lagging, lagErr := objstore.CheckReplicationLag(ctx, "", *replicationLagAlert)
+					if lagErr != nil {
+						slog.Warn("Failed to check replication lag", "error", lagErr)
+					}
+					for _, lag := range lagging {
+						slog.Warn("Replication policy is lagging", "policy", lag.PolicyID, "last_sync", lag.LastSync, "lag", lag.Lag)
+					}
+				}
+				if err != nil {
+					return "", err
+				}
+				return fmt.Sprintf("%d synced, %d deleted, %d failed", result.Synced, result.Deleted, result.Failed), nil
+			},
+		})
+	}
+	if collector != nil {
+		backgroundJobs = append(backgroundJobs, jobs.Job{
+			Name:     "inventory",
+			Interval: *statsInterval,
+			Run: func(ctx context.Context) (string, error) {
+				return "", collector.Collect(ctx)
+			},
+		})
+	}
+	if idempotencyStore != nil {
+		backgroundJobs = append(backgroundJobs, jobs.Job{
+			Name:     "gc",
+			Interval: *gcInterval,
+			Run: func(ctx context.Context) (string, error) {
+				removed, err := idempotencyStore.Purge(ctx)
+				return fmt.Sprintf("%d expired idempotency records removed", removed), err
+			},
+		})
+	}
+	if *enableTombstones {
+		backgroundJobs = append(backgroundJobs, jobs.Job{
+			Name:     "tombstones",
+			Interval: *tombstoneInterval,
+			Run: func(ctx context.Context) (string, error) {
+				purged, err := objstore.PurgeTombstones(ctx, "")
+				return fmt.Sprintf("%d deleted objects removed", purged), err
+			},
+		})
+	}
+	if *enableUploads {
+		backgroundJobs = append(backgroundJobs, jobs.Job{
+			Name:     "uploads",
+			Interval: *uploadsInterval,
+			Run: func(ctx context.Context) (string, error) {
+				manager, err := objstore.Uploads("")
+				if err != nil {
+					return "", err
+				}
+				purged, err := manager.Purge(ctx)
+				return fmt.Sprintf("%d expired upload sessions removed", purged), err
+			},
+		})
+	}
+	if *reapUploadsAfter > 0 {
+		backgroundJobs = append(backgroundJobs, jobs.Job{
+			Name:     "reaper",
+			Interval: *reapUploadsInterval,
+			Run: func(ctx context.Context) (string, error) {
+				result, err := objstore.ReapUploads(ctx, "", &objstore.ReapConfig{MaxAge: *reapUploadsAfter})
+				if result == nil {
+					return "", err
+				}
+				return fmt.Sprintf("%d of %d unfinished uploads aborted, %d bytes reclaimed, %d failed",
+					result.Aborted, result.InProgress, result.Reclaimed, result.Failed), err
+			},
+		})
+	}
+	if *scrubInterval > 0 {
+		if !*enableChecksums {
+			slog.Error("--scrub-interval requires --checksums")
+			os.Exit(1)
+		}
+		backgroundJobs = append(backgroundJobs, jobs.Job{
+			Name:     "scrub",
+			Interval: *scrubInterval,
+			Run: func(ctx context.Context) (string, error) {
+				result, err := objstore.Scrub(ctx, "", &objstore.ScrubConfig{
+					BytesPerSecond: *scrubRate,
+					OnCorruption: func(c scrub.Corruption) {
+						if c.Repaired {
+							slog.Warn("Repaired corrupted object", "key", c.Key, "replica", c.Replica)
+						} else {
+							slog.Error("Corrupted object could not be repaired", "key", c.Key, "error", c.Error)
+						}
+					},
+				})
+				if err != nil {
+					return "", err
+				}
+				message := fmt.Sprintf("%d verified, %d corrupted, %d repaired", result.Verified, result.Corrupted, result.Repaired)
+				if unrepaired := result.Corrupted - result.Repaired; unrepaired > 0 {
+					return message, fmt.Errorf("%d corrupted objects could not be repaired", unrepaired)
+				}
+				return message, nil
+			},
+		})
+	}
+	var scheduler *jobs.Scheduler
+	if len(backgroundJobs) > 0 {
+		jobsConfig := &jobs.Config{HistoryPath: *jobsHistory}
+		if jobsConfig.HistoryPath == "" {
+			jobsConfig.HistoryPath = *basePath + "/.jobs-history.json"
+		}
+		if elector != nil {
+			jobsConfig.Node = nodeID
+			jobsConfig.IsLeader = elector.IsLeader
+		}
+		if cluster != nil {
+			if *jobsHistory == "" {
+				jobsConfig.HistoryPath = ".jobs-history.json"
+				jobsConfig.FileSystem = cluster.FileSystem()
+				jobsConfig.Shared = true
+			}
+		}
+		var err error
+		scheduler, err = objstore.EnableJobs(jobsConfig)
+		if err != nil {
+			slog.Error("Failed to enable background jobs", "error", err)
+			os.Exit(1)
+		}
+		for _, job := range backgroundJobs {
+			if err := scheduler.Register(job); err != nil {
+				slog.Error("Failed to register background job", "job", job.Name, "error", err)
+				os.Exit(1)
+			}
+			slog.Info("Background job scheduled", "job", job.Name, "interval", job.Interval)
+		}
+	}
+
 	// Startup logging
 	slog.Info("Object Storage Server starting", "backend", *backend)
 	if *backend == "local" {
@@ -131,6 +894,9 @@ func main() {
 	}
 	if *enableREST {
 		slog.Info("Service enabled", "service", "rest", "addr", fmt.Sprintf("0.0.0.0:%d", *restPort))
+		if *enableUI {
+			slog.Info("Admin UI enabled", "url", fmt.Sprintf("http://localhost:%d/ui/", *restPort))
+		}
 	}
 	if *enableQUIC {
 		if *quicSelfSigned || (*quicTLSCert != "" && *quicTLSKey != "") {
@@ -143,7 +909,7 @@ func main() {
 		slog.Info("Service enabled", "service", "mcp", "mode", *mcpMode, "addr", *mcpAddr)
 	}
 	if *enableUnix {
-		slog.Info("Service enabled", "service", "unix", "socket", *unixSocket)
+		slog.Info("Service enabled", "service", "unix", "socket", *unixSocket, "key_prefix", *unixKeyPrefix)
 	}
 
 	// Channel for errors
@@ -166,10 +932,15 @@ func main() {
 	if *enableGRPC {
 		opts := []grpcserver.ServerOption{
 			grpcserver.WithAddress(*grpcAddr),
+			grpcserver.WithReflection(*grpcReflection),
+			grpcserver.WithMaxMessageSize(*grpcMaxMsgSize),
 		}
 		if *rateLimit {
 			opts = append(opts, grpcserver.WithRateLimit(true, rateLimitConfig))
 		}
+		if *idempotency {
+			opts = append(opts, grpcserver.WithIdempotency(true, idempotencyConfig))
+		}
 
 		server, err := grpcserver.NewServer(opts...)
 		if err != nil {
@@ -192,13 +963,29 @@ func main() {
 		config := restserver.DefaultServerConfig()
 		config.Port = *restPort
 		config.MetricsPublic = *metricsPublic
+		config.EnableUI = *enableUI
 		config.EnableRateLimit = *rateLimit
 		config.RateLimitConfig = rateLimitConfig
+		config.EnableIdempotency = *idempotency
+		config.IdempotencyConfig = idempotencyConfig
 		config.EnableAudit = *enableAudit
 		if auditLogger != nil {
 			config.AuditLogger = auditLogger
 		}
 
+		// Requests signed with an issued access key authenticate as its
+		// principal; other requests are authenticated as before.
+		if *accessKeysFile != "" {
+			keys, err := adapters.NewAccessKeyStore(*accessKeysFile)
+			if err != nil {
+				slog.Error("Failed to load access keys", "error", err)
+				os.Exit(1)
+			}
+			config.AccessKeys = keys
+			config.Authenticator = adapters.NewCompositeAuthenticator(adapters.NewSigV4Authenticator(keys.LookupSecret), config.Authenticator)
+			slog.Info("Access keys enabled", "access_keys_file", *accessKeysFile)
+		}
+
 		server, err := restserver.NewServer(storage, config)
 		if err != nil {
 			errChan <- fmt.Errorf("failed to create REST server: %w", err)
@@ -316,6 +1103,7 @@ func main() {
 		config := &unixserver.ServerConfig{
 			SocketPath:      *unixSocket,
 			Backend:         "default",
+			KeyPrefix:       *unixKeyPrefix,
 			EnableRateLimit: *rateLimit,
 			RateLimitConfig: rateLimitConfig,
 			EnableAudit:     *enableAudit,
@@ -352,6 +1140,9 @@ func main() {
 
 	slog.Info("Shutting down servers")
 
+	// Resign leadership so another instance takes over the jobs.
+	stopJobs()
+
 	// Bounded shutdown context.
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer shutdownCancel()
@@ -408,6 +1199,63 @@ func main() {
 		slog.Warn("Timed out waiting for servers to stop")
 	}
 
+	// Stop the task queue, interrupting running tasks; they are attempted
+	// again on restart.
+	if queue != nil {
+		if err := queue.Close(); err != nil {
+			slog.Error("Failed to stop the task queue", "error", err)
+		}
+	}
+
+	// Deliver queued event notifications.
+	if notifier != nil {
+		if err := notifier.Close(shutdownCtx); err != nil {
+			slog.Error("Failed to deliver queued event notifications", "error", err)
+		}
+	}
+
+	// Close the change journal.
+	if journal != nil {
+		if err := journal.Close(); err != nil {
+			slog.Error("Failed to close change journal", "error", err)
+		}
+	}
+
+	// Stop background jobs, cancelling any still running.
+	if scheduler != nil {
+		if err := scheduler.Close(); err != nil {
+			slog.Error("Failed to stop background jobs", "error", err)
+		}
+	}
+
+	// Stop taking storage statistics snapshots.
+	if collector != nil {
+		if err := collector.Close(); err != nil {
+			slog.Error("Failed to stop storage statistics", "error", err)
+		}
+	}
+
+	// Save last read times.
+	if tracker != nil {
+		if err := tracker.Close(); err != nil {
+			slog.Error("Failed to save last read times", "error", err)
+		}
+	}
+
+	// Persist metered usage.
+	if meter != nil {
+		if err := meter.Close(); err != nil {
+			slog.Error("Failed to save cost usage", "error", err)
+		}
+	}
+
+	// Checkpoint and close the hash-chained audit log.
+	if chainLogger != nil {
+		if err := chainLogger.Close(); err != nil {
+			slog.Error("Failed to close hash-chained audit log", "error", err)
+		}
+	}
+
 	// Remove Unix socket file if it still exists.
 	if *enableUnix {
 		if err := os.Remove(*unixSocket); err != nil && !os.IsNotExist(err) {
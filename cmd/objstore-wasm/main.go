@@ -0,0 +1,323 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build js && wasm
+
+// Command objstore-wasm is the js/wasm build of the objstore client. It lets
+// browser applications upload and download objects through an objstore
+// server's REST API, authenticating with a bearer token, and move data to
+// and from presigned URLs issued elsewhere.
+//
+// Loading the module defines a global "objstore" object; objstore.js wraps
+// it with a small promise-based API. Every method returns a Promise and
+// object data is exchanged as Uint8Array.
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"syscall/js"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/cli/client"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/version"
+)
+
+func main() {
+	js.Global().Set("objstore", js.ValueOf(map[string]any{
+		"version":   version.Version,
+		"newClient": js.FuncOf(newClient),
+		"putURL":    js.FuncOf(putURL),
+		"getURL":    js.FuncOf(getURL),
+	}))
+	if ready := js.Global().Get("__objstoreReady"); ready.Type() == js.TypeFunction {
+		ready.Invoke()
+	}
+	select {}
+}
+
+// newClient(config) returns a client for config.url. config.token is sent
+// as a bearer token. Browsers cannot open raw QUIC connections, so the REST
+// API is always used; a QUIC server that also serves HTTP is reached through
+// the browser's own HTTP/3 support.
+func newClient(_ js.Value, args []js.Value) any {
+	if len(args) < 1 || args[0].Type() != js.TypeObject {
+		return jsError(fmt.Errorf("%w: newClient expects a config object", common.ErrInvalidArgument))
+	}
+	config := args[0]
+	c, err := client.NewRESTClient(&client.Config{
+		ServerURL: stringField(config, "url"),
+		Token:     stringField(config, "token"),
+	})
+	if err != nil {
+		return jsError(err)
+	}
+
+	methods := map[string]func(args []js.Value) (any, error){
+		"put": func(args []js.Value) (any, error) {
+			data, err := bytesArg(args, 1)
+			if err != nil {
+				return nil, err
+			}
+			return nil, c.Put(context.Background(), stringArg(args, 0), bytes.NewReader(data), metadataArg(args, 2))
+		},
+		"get": func(args []js.Value) (any, error) {
+			rc, metadata, err := c.Get(context.Background(), stringArg(args, 0))
+			if err != nil {
+				return nil, err
+			}
+			defer func() { _ = rc.Close() }()
+			data, err := io.ReadAll(rc)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]any{"data": toUint8Array(data), "metadata": metadataValue(metadata)}, nil
+		},
+		"delete": func(args []js.Value) (any, error) {
+			return nil, c.Delete(context.Background(), stringArg(args, 0))
+		},
+		"exists": func(args []js.Value) (any, error) {
+			return c.Exists(context.Background(), stringArg(args, 0))
+		},
+		"list": func(args []js.Value) (any, error) {
+			opts := &common.ListOptions{Prefix: stringArg(args, 0)}
+			if len(args) > 1 && args[1].Type() == js.TypeObject {
+				opts.Delimiter = stringField(args[1], "delimiter")
+				opts.ContinueFrom = stringField(args[1], "continueFrom")
+				if v := args[1].Get("maxResults"); v.Type() == js.TypeNumber {
+					opts.MaxResults = v.Int()
+				}
+			}
+			result, err := c.List(context.Background(), opts)
+			if err != nil {
+				return nil, err
+			}
+			return listValue(result), nil
+		},
+		"getMetadata": func(args []js.Value) (any, error) {
+			metadata, err := c.GetMetadata(context.Background(), stringArg(args, 0))
+			if err != nil {
+				return nil, err
+			}
+			return metadataValue(metadata), nil
+		},
+		"updateMetadata": func(args []js.Value) (any, error) {
+			metadata := metadataArg(args, 1)
+			if metadata == nil {
+				return nil, fmt.Errorf("%w: metadata is required", common.ErrInvalidArgument)
+			}
+			return nil, c.UpdateMetadata(context.Background(), stringArg(args, 0), metadata)
+		},
+		"health": func(args []js.Value) (any, error) {
+			return nil, c.Health(context.Background())
+		},
+	}
+
+	obj := make(map[string]any, len(methods)+1)
+	for name, fn := range methods {
+		obj[name] = js.FuncOf(func(_ js.Value, args []js.Value) any {
+			return promise(func() (any, error) { return fn(args) })
+		})
+	}
+	obj["close"] = js.FuncOf(func(_ js.Value, _ []js.Value) any {
+		return promise(func() (any, error) { return nil, c.Close() })
+	})
+	return js.ValueOf(obj)
+}
+
+// putURL(url, data, contentType) uploads data to a presigned URL.
+func putURL(_ js.Value, args []js.Value) any {
+	return promise(func() (any, error) {
+		data, err := bytesArg(args, 1)
+		if err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodPut, stringArg(args, 0), bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		if contentType := stringArg(args, 2); contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		_, err = doURL(req)
+		return nil, err
+	})
+}
+
+// getURL(url) downloads a presigned URL and resolves to a Uint8Array.
+func getURL(_ js.Value, args []js.Value) any {
+	return promise(func() (any, error) {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, stringArg(args, 0), http.NoBody)
+		if err != nil {
+			return nil, err
+		}
+		data, err := doURL(req)
+		if err != nil {
+			return nil, err
+		}
+		return toUint8Array(data), nil
+	})
+}
+
+// urlClient serves presigned URL requests.
+var urlClient = &http.Client{Timeout: 5 * time.Minute}
+
+// doURL performs req and returns the response body, failing on any non-2xx
+// status.
+func doURL(req *http.Request) ([]byte, error) {
+	resp, err := urlClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("%w %d: %s", client.ErrServerError, resp.StatusCode, body)
+	}
+	return body, nil
+}
+
+// promise runs fn on a new goroutine, since blocking HTTP calls would
+// otherwise deadlock the JavaScript event loop, and settles a Promise with
+// its result.
+func promise(fn func() (any, error)) js.Value {
+	executor := js.FuncOf(func(_ js.Value, args []js.Value) any {
+		resolve, reject := args[0], args[1]
+		go func() {
+			value, err := fn()
+			if err != nil {
+				reject.Invoke(jsError(err))
+				return
+			}
+			resolve.Invoke(value)
+		}()
+		return nil
+	})
+	defer executor.Release()
+	return js.Global().Get("Promise").New(executor)
+}
+
+// jsError converts err to a JavaScript Error.
+func jsError(err error) js.Value {
+	return js.Global().Get("Error").New(err.Error())
+}
+
+func stringArg(args []js.Value, i int) string {
+	if i >= len(args) || args[i].Type() != js.TypeString {
+		return ""
+	}
+	return args[i].String()
+}
+
+func stringField(v js.Value, name string) string {
+	if field := v.Get(name); field.Type() == js.TypeString {
+		return field.String()
+	}
+	return ""
+}
+
+// bytesArg accepts a string, Uint8Array or ArrayBuffer.
+func bytesArg(args []js.Value, i int) ([]byte, error) {
+	if i >= len(args) {
+		return nil, fmt.Errorf("%w: data is required", common.ErrInvalidArgument)
+	}
+	v := args[i]
+	if v.Type() == js.TypeString {
+		return []byte(v.String()), nil
+	}
+	if v.InstanceOf(js.Global().Get("ArrayBuffer")) {
+		v = js.Global().Get("Uint8Array").New(v)
+	}
+	if !v.InstanceOf(js.Global().Get("Uint8Array")) {
+		return nil, fmt.Errorf("%w: data must be a string, Uint8Array or ArrayBuffer", common.ErrInvalidArgument)
+	}
+	data := make([]byte, v.Length())
+	js.CopyBytesToGo(data, v)
+	return data, nil
+}
+
+func toUint8Array(data []byte) js.Value {
+	array := js.Global().Get("Uint8Array").New(len(data))
+	js.CopyBytesToJS(array, data)
+	return array
+}
+
+// metadataArg reads {contentType, contentEncoding, storageClass, custom}.
+func metadataArg(args []js.Value, i int) *common.Metadata {
+	if i >= len(args) || args[i].Type() != js.TypeObject {
+		return nil
+	}
+	v := args[i]
+	metadata := &common.Metadata{
+		ContentType:     stringField(v, "contentType"),
+		ContentEncoding: stringField(v, "contentEncoding"),
+		StorageClass:    stringField(v, "storageClass"),
+	}
+	if custom := v.Get("custom"); custom.Type() == js.TypeObject {
+		keys := js.Global().Get("Object").Call("keys", custom)
+		metadata.Custom = make(map[string]string, keys.Length())
+		for j := 0; j < keys.Length(); j++ {
+			key := keys.Index(j).String()
+			metadata.Custom[key] = custom.Get(key).String()
+		}
+	}
+	return metadata
+}
+
+func metadataValue(metadata *common.Metadata) map[string]any {
+	if metadata == nil {
+		return map[string]any{}
+	}
+	custom := make(map[string]any, len(metadata.Custom))
+	for k, v := range metadata.Custom {
+		custom[k] = v
+	}
+	value := map[string]any{
+		"size":            metadata.Size,
+		"etag":            metadata.ETag,
+		"contentType":     metadata.ContentType,
+		"contentEncoding": metadata.ContentEncoding,
+		"storageClass":    metadata.StorageClass,
+		"custom":          custom,
+	}
+	if !metadata.LastModified.IsZero() {
+		value["lastModified"] = metadata.LastModified.UTC().Format(time.RFC3339)
+	}
+	return value
+}
+
+func listValue(result *common.ListResult) map[string]any {
+	objects := make([]any, 0, len(result.Objects))
+	for _, obj := range result.Objects {
+		entry := metadataValue(obj.Metadata)
+		entry["key"] = obj.Key
+		objects = append(objects, entry)
+	}
+	prefixes := make([]any, 0, len(result.CommonPrefixes))
+	for _, prefix := range result.CommonPrefixes {
+		prefixes = append(prefixes, prefix)
+	}
+	return map[string]any{
+		"objects":   objects,
+		"prefixes":  prefixes,
+		"nextToken": result.NextToken,
+		"truncated": result.Truncated,
+	}
+}
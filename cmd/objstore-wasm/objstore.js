@@ -0,0 +1,73 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// objstore.js loads objstore.wasm and returns its client API.
+//
+//   <script src="wasm_exec.js"></script>
+//   <script type="module">
+//     import { loadObjstore, uploadFile } from "./objstore.js";
+//
+//     const objstore = await loadObjstore("objstore.wasm");
+//     const client = objstore.newClient({ url: "https://objstore.example.com", token });
+//     await uploadFile(client, "uploads/photo.jpg", input.files[0]);
+//     const { data, metadata } = await client.get("uploads/photo.jpg");
+//   </script>
+//
+// Client methods: put(key, data, metadata), get(key), delete(key),
+// exists(key), list(prefix, {delimiter, maxResults, continueFrom}),
+// getMetadata(key), updateMetadata(key, metadata), health() and close().
+// Presigned URLs: objstore.putURL(url, data, contentType) and
+// objstore.getURL(url). Every call returns a Promise; data is a string,
+// Uint8Array or ArrayBuffer and is returned as a Uint8Array.
+
+let loading;
+
+// loadObjstore instantiates the module once and resolves to the global
+// objstore object. wasm_exec.js from the Go distribution must be loaded
+// first.
+export function loadObjstore(wasmURL = "objstore.wasm") {
+  if (!loading) {
+    loading = instantiate(wasmURL).catch((err) => {
+      loading = undefined;
+      throw err;
+    });
+  }
+  return loading;
+}
+
+async function instantiate(wasmURL) {
+  if (typeof Go === "undefined") {
+    throw new Error("wasm_exec.js must be loaded before objstore.js");
+  }
+  const go = new Go();
+  const ready = new Promise((resolve) => {
+    globalThis.__objstoreReady = resolve;
+  });
+
+  const response = fetch(wasmURL);
+  const { instance } = WebAssembly.instantiateStreaming
+    ? await WebAssembly.instantiateStreaming(response, go.importObject)
+    : await WebAssembly.instantiate(await (await response).arrayBuffer(), go.importObject);
+  go.run(instance);
+
+  await ready;
+  delete globalThis.__objstoreReady;
+  return globalThis.objstore;
+}
+
+// uploadFile stores a File or Blob, using its MIME type as the content type
+// unless metadata sets one.
+export async function uploadFile(client, key, file, metadata = {}) {
+  const data = new Uint8Array(await file.arrayBuffer());
+  return client.put(key, data, { contentType: file.type, ...metadata });
+}
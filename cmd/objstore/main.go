@@ -14,15 +14,28 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"github.com/jeremyhahn/go-objstore/pkg/access"
+	"github.com/jeremyhahn/go-objstore/pkg/backup"
 	"github.com/jeremyhahn/go-objstore/pkg/cli"
 	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/diff"
+	"github.com/jeremyhahn/go-objstore/pkg/lifecycle"
+	"github.com/jeremyhahn/go-objstore/pkg/retention"
+	"github.com/jeremyhahn/go-objstore/pkg/share"
 )
 
 var (
@@ -85,12 +98,24 @@ var putCmd = &cobra.Command{
 	Short: "Upload a file to object storage",
 	Long: `Upload a file to the object storage backend with the specified key.
 Use '-' as the source-file to read from stdin.
-You can also set metadata using flags: --content-type, --content-encoding, --custom.`,
+You can also set metadata using flags: --content-type, --content-encoding, --custom.
+Use --storage-class to select the backend storage class or access tier
+(e.g. STANDARD_IA or GLACIER_IR on S3, NEARLINE or COLDLINE on GCS, Cool on Azure).
+Use --expires-at or --expires-in to have lifecycle runs delete the object at
+a given time, whatever the policies of its prefix.
+Without --content-type, the content type is detected from the key's extension
+or the first 512 bytes of the data; --detect-content-type=false turns this off.
+With --atomic the upload is staged under a hidden key and only replaces the
+object once it is complete and matches its declared size and checksum-sha256,
+so readers never see a partially written object.`,
 	Example: `  objstore put file.txt myfile.txt                                    # Upload local file
   objstore put file.txt prefix/myfile.txt                             # Upload with prefix/path
   cat file.txt | objstore put - myfile.txt                            # Upload from stdin
   objstore put file.txt myfile.txt --content-type application/json    # Upload with content type
-  objstore put file.txt myfile.txt --custom author=me,version=1.0     # Upload with custom metadata`,
+  objstore put file.txt myfile.txt --custom author=me,version=1.0     # Upload with custom metadata
+  objstore put file.txt myfile.txt --storage-class STANDARD_IA        # Upload to a colder storage class
+  objstore put report.pdf tmp/report.pdf --expires-in 72h             # Delete after three days
+  objstore put app.tar.gz releases/app.tar.gz --atomic                # Never expose a partial upload`,
 	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		filePath := args[0]
@@ -100,6 +125,21 @@ You can also set metadata using flags: --content-type, --content-encoding, --cus
 		contentType, _ := cmd.Flags().GetString("content-type")         //nolint:errcheck // flags are validated by cobra
 		contentEncoding, _ := cmd.Flags().GetString("content-encoding") //nolint:errcheck // flags are validated by cobra
 		customFields, _ := cmd.Flags().GetStringToString("custom")      //nolint:errcheck // flags are validated by cobra
+		storageClass, _ := cmd.Flags().GetString("storage-class")       //nolint:errcheck // flags are validated by cobra
+		expiresAt, _ := cmd.Flags().GetString("expires-at")             //nolint:errcheck // flags are validated by cobra
+		expiresIn, _ := cmd.Flags().GetDuration("expires-in")           //nolint:errcheck // flags are validated by cobra
+
+		if expiresAt != "" || expiresIn > 0 {
+			expiry, err := cli.ExpiryTime(expiresAt, expiresIn, time.Now())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+				return err
+			}
+			if customFields == nil {
+				customFields = make(map[string]string)
+			}
+			customFields[common.MetaExpiresAt] = expiry
+		}
 
 		ctx, err := cli.NewCommandContext(globalConfig)
 		if err != nil {
@@ -108,7 +148,8 @@ You can also set metadata using flags: --content-type, --content-encoding, --cus
 		}
 		defer func() { _ = ctx.Close() }()
 
-		if err := ctx.PutCommandWithMetadata(key, filePath, contentType, contentEncoding, customFields); err != nil {
+		info, err := ctx.PutCommandWithInfo(key, filePath, contentType, contentEncoding, storageClass, customFields)
+		if err != nil {
 			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
 			return err
 		}
@@ -123,29 +164,1756 @@ You can also set metadata using flags: --content-type, --content-encoding, --cus
 
 		result := &cli.OperationResult{
 			Success: true,
-			Message: message,
+			Message: message,
+		}
+		if written := info.Metadata; written != nil {
+			data := map[string]any{"key": key, "etag": written.ETag, "size": written.Size}
+			if written.Version != "" {
+				data["version"] = written.Version
+			}
+			if !written.LastModified.IsZero() {
+				data["last_modified"] = written.LastModified
+			}
+			result.Data = data
+		}
+		fmt.Print(cli.FormatOperationResult(result, cli.OutputFormat(globalConfig.OutputFormat)))
+		return nil
+	},
+}
+
+// exportList streams the listing of prefix to path.
+func exportList(ctx *cli.CommandContext, prefix, path string, opts cli.ExportOptions, sorted bool) error {
+	format := cli.OutputFormat(globalConfig.OutputFormat)
+	if sorted {
+		fmt.Fprintln(os.Stderr, cli.FormatError(cli.ErrSortedExport, format))
+		return cli.ErrSortedExport
+	}
+	file, err := os.Create(path) // #nosec G304 -- User-provided path for CLI file operations, intended behavior
+	if err != nil {
+		fmt.Fprintln(os.Stderr, cli.FormatError(err, format))
+		return err
+	}
+	count, err := ctx.ExportListCommand(prefix, file, opts)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, cli.FormatError(err, format))
+		return err
+	}
+	fmt.Print(cli.FormatOperationResult(&cli.OperationResult{
+		Success: true,
+		Message: fmt.Sprintf("Exported %d object(s) to '%s'", count, path),
+	}, format))
+	return nil
+}
+
+var getCmd = &cobra.Command{
+	Use:   "get <key> [output-file]",
+	Short: "Download a file from object storage or get its metadata",
+	Long: `Download a file from the object storage backend or retrieve its metadata.
+If output-file is not specified or is '-', the content will be written to stdout.
+Use --metadata flag to retrieve only metadata instead of the file content.`,
+	Example: `  objstore get myfile.txt                        # Download to stdout
+  objstore get myfile.txt downloaded.txt         # Download to file
+  objstore get logs/2024/app.log -               # Download to stdout explicitly
+  objstore get myfile.txt --metadata             # Get metadata only
+  objstore get myfile.txt --metadata -o json     # Get metadata as JSON
+  objstore get big.iso big.iso --download-concurrency 8  # Parallel download`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key := args[0]
+		metadataOnly, _ := cmd.Flags().GetBool("metadata") //nolint:errcheck // flags are validated by cobra
+
+		ctx, err := cli.NewCommandContext(globalConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+		defer func() { _ = ctx.Close() }()
+
+		// If --metadata flag is set, return metadata only
+		if metadataOnly {
+			metadata, err := ctx.GetMetadataCommand(key)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+				return err
+			}
+			fmt.Print(cli.FormatMetadataResult(metadata, cli.OutputFormat(globalConfig.OutputFormat)))
+			return nil
+		}
+
+		// Otherwise, download the file
+		outputPath := ""
+		if len(args) > 1 {
+			outputPath = args[1]
+		}
+
+		if err := ctx.GetCommand(key, outputPath); err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+
+		// Only print success message if not writing to stdout
+		if outputPath != "" && outputPath != "-" {
+			result := &cli.OperationResult{
+				Success: true,
+				Message: fmt.Sprintf("Successfully downloaded '%s' to '%s'", key, outputPath),
+			}
+			fmt.Print(cli.FormatOperationResult(result, cli.OutputFormat(globalConfig.OutputFormat)))
+		}
+		return nil
+	},
+}
+
+var deleteCmd = &cobra.Command{
+	Use:   "delete <key>",
+	Short: "Delete an object from storage",
+	Long:  `Delete an object from the object storage backend.`,
+	Example: `  objstore delete myfile.txt                     # Delete a file
+  objstore delete logs/2024/app.log              # Delete file with prefix
+  objstore delete temp/                          # Delete a specific key (not recursive)`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key := args[0]
+
+		ctx, err := cli.NewCommandContext(globalConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+		defer func() { _ = ctx.Close() }()
+
+		message := fmt.Sprintf("Successfully deleted '%s'", key)
+		var pending *retention.PendingError
+		if err := ctx.DeleteCommand(key); errors.As(err, &pending) {
+			message = fmt.Sprintf("Deletion of '%s' is pending approval (request %s)", key, pending.Request.ID)
+		} else if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+
+		result := &cli.OperationResult{
+			Success: true,
+			Message: message,
+		}
+		fmt.Print(cli.FormatOperationResult(result, cli.OutputFormat(globalConfig.OutputFormat)))
+		return nil
+	},
+}
+
+var mvCmd = &cobra.Command{
+	Use:   "mv <source> <destination>",
+	Short: "Move an object, or every object under a prefix",
+	Long: `Move an object to a new key. The object is copied with its metadata,
+server-side where the backend supports it, and removed from its old key
+once the copy succeeded. An existing object at the destination is only
+replaced with --overwrite.
+
+With --recursive, every object whose key starts with the source prefix is
+moved to the same key under the destination prefix, --concurrency objects
+at a time. Objects that already exist under the destination with different
+content are skipped unless --overwrite is given. An interrupted move can be
+run again to finish it: objects already copied are recognized and only
+removed from their old keys. --dry-run reports what would be moved. Over the
+rest protocol the server moves the objects itself and only the totals are
+printed.`,
+	Example: `  objstore mv report.pdf reports/2025/report.pdf
+  objstore mv --recursive logs/2024/ archive/logs/2024/ --dry-run
+  objstore mv --recursive logs/2024/ archive/logs/2024/ --concurrency 32`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, to := args[0], args[1]
+		recursive, _ := cmd.Flags().GetBool("recursive")    //nolint:errcheck // flags are validated by cobra
+		dryRun, _ := cmd.Flags().GetBool("dry-run")         //nolint:errcheck // flags are validated by cobra
+		overwrite, _ := cmd.Flags().GetBool("overwrite")    //nolint:errcheck // flags are validated by cobra
+		concurrency, _ := cmd.Flags().GetInt("concurrency") //nolint:errcheck // flags are validated by cobra
+
+		ctx, err := cli.NewCommandContext(globalConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+		defer func() { _ = ctx.Close() }()
+
+		if !recursive {
+			if err := ctx.MoveCommand(from, to, overwrite); err != nil {
+				fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+				return err
+			}
+			result := &cli.OperationResult{
+				Success: true,
+				Message: fmt.Sprintf("Successfully moved '%s' to '%s'", from, to),
+			}
+			fmt.Print(cli.FormatOperationResult(result, cli.OutputFormat(globalConfig.OutputFormat)))
+			return nil
+		}
+
+		result, err := ctx.RenamePrefixCommand(from, to, &common.RenameOptions{
+			DryRun:      dryRun,
+			Overwrite:   overwrite,
+			Concurrency: concurrency,
+			Progress: func(event common.RenameEvent) {
+				if event.Err != nil {
+					fmt.Fprintf(os.Stderr, "%s %s -> %s: %v\n", event.Status, event.From, event.To, event.Err)
+					return
+				}
+				fmt.Fprintf(os.Stderr, "%s %s -> %s\n", event.Status, event.From, event.To)
+			},
+		})
+		if result != nil {
+			fmt.Print(cli.FormatRenameResult(from, to, result, cli.OutputFormat(globalConfig.OutputFormat)))
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+		return nil
+	},
+}
+
+var listCmd = &cobra.Command{
+	Use:   "list [prefix]",
+	Short: "List objects in storage",
+	Long: `List all objects in the object storage backend, optionally filtered by prefix.
+
+--sort orders objects by name, size or mtime (modification time), and
+--reverse reverses the order. --long prints one line per object with its
+storage class, size, modification time, checksum, encryption key ID and key,
+fetching metadata the listing does not include. --fields selects the fields
+to show from: key, size, modified, storage_class, content_type, etag,
+checksum and encryption_key_id. Sizes in these formats are in bytes unless
+--human-readable is given.
+
+Listings are read a page at a time and only the first page is shown unless
+--all is given. --output-file streams the listing to a file instead, as
+newline-delimited JSON or, for a .csv file, CSV, writing each page as it
+arrives so that listings of millions of keys never have to fit in memory.
+Exports are in key order and cannot be sorted.`,
+	Example: `  objstore list                                  # List all objects
+  objstore list logs/                            # List objects with 'logs/' prefix
+  objstore list logs/2024/                       # List objects in logs/2024/
+  objstore list -o json                          # List all objects as JSON
+  objstore list logs/ -o table                   # List with table format
+  objstore list -l --sort size -r --human-readable  # Largest objects first
+  objstore list --fields key,content_type -o table  # Selected fields only
+  objstore list --all --output-file keys.jsonl   # Export every key for reconciliation
+  objstore list data/ --all --output-file keys.csv --fields key,size,etag`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		prefix := ""
+		if len(args) > 0 {
+			prefix = args[0]
+		}
+
+		sortFlag, _ := cmd.Flags().GetString("sort")
+		reverse, _ := cmd.Flags().GetBool("reverse")
+		fieldsFlag, _ := cmd.Flags().GetString("fields")
+		long, _ := cmd.Flags().GetBool("long")
+		humanReadable, _ := cmd.Flags().GetBool("human-readable")
+		all, _ := cmd.Flags().GetBool("all")
+		outputFile, _ := cmd.Flags().GetString("output-file")
+		pageSize, _ := cmd.Flags().GetInt("page-size")
+
+		sortBy, err := common.ParseListSort(sortFlag)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+		fields, err := cli.ParseListFields(fieldsFlag)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+
+		ctx, err := cli.NewCommandContext(globalConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+		defer func() { _ = ctx.Close() }()
+
+		// Checksums and key IDs may need metadata the listing lacks
+		needMetadata := (long && len(fields) == 0) ||
+			slices.Contains(fields, cli.FieldChecksum) || slices.Contains(fields, cli.FieldEncryptionKeyID)
+		if outputFile != "" {
+			return exportList(ctx, prefix, outputFile, cli.ExportOptions{
+				Format:   cli.ExportFormatForFile(outputFile),
+				Fields:   fields,
+				PageSize: pageSize,
+				Long:     needMetadata,
+				All:      all,
+			}, sortBy != "" || reverse)
+		}
+		objects, err := ctx.ListObjectsCommand(prefix, cli.ListOptions{
+			SortBy:  sortBy,
+			Reverse: reverse,
+			Long:    needMetadata,
+			All:     all,
+		})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+
+		fmt.Print(cli.FormatListView(objects, cli.OutputFormat(globalConfig.OutputFormat), cli.ListView{
+			Fields:        fields,
+			Long:          long,
+			HumanReadable: humanReadable,
+		}))
+		return nil
+	},
+}
+
+var lsCmd = &cobra.Command{
+	Use:   "ls [prefix]",
+	Short: "Browse virtual directories",
+	Long: `List the folders and objects directly under a prefix, treating "/" in keys
+as a directory separator. Names are shown relative to the prefix; folders end
+in "/".
+
+--totals also counts the objects and bytes under each folder. This reads the
+listing of every object below the prefix, so it is slower on large trees.`,
+	Example: `  objstore ls                        # Top-level folders and objects
+  objstore ls logs/                  # Contents of logs/
+  objstore ls logs/ --totals         # With object counts and sizes per folder
+  objstore ls logs/ -o json          # As JSON`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		prefix := ""
+		if len(args) > 0 {
+			prefix = args[0]
+		}
+		totals, _ := cmd.Flags().GetBool("totals")
+		humanReadable, _ := cmd.Flags().GetBool("human-readable")
+
+		ctx, err := cli.NewCommandContext(globalConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+		defer func() { _ = ctx.Close() }()
+
+		result, err := ctx.BrowseCommand(prefix, totals)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+
+		fmt.Print(cli.FormatBrowseResult(result, cli.OutputFormat(globalConfig.OutputFormat), humanReadable))
+		return nil
+	},
+}
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search objects by key and metadata",
+	Long: `Search objects by key, content type and custom metadata.
+
+A query is a list of space-separated terms that must all match. A bare term
+matches words in the key, content type or any metadata value; a trailing *
+matches by prefix. field:value matches a custom metadata field (or
+content-type), and prefix:value restricts results to keys with that prefix.
+
+In local mode, --search-index keeps a persistent index that is updated by
+every put, delete and metadata change made through the CLI. Without it, a
+temporary index is built from a full listing. With --server, the server's
+search index is used (REST protocol only). With --e2e-keychain, the server
+can only match field:value terms for --e2e-deterministic-fields, which are
+encrypted before the query is sent.`,
+	Example: `  objstore search report                           # Keys or metadata containing "report"
+  objstore search "author:alice content-type:application/pdf"
+  objstore search "prefix:logs/2025/ error*"       # Combine filters
+  objstore --search-index .objstore-index.json search invoice --rebuild`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		query := strings.Join(args, " ")
+		limit, _ := cmd.Flags().GetInt("limit")
+		rebuild, _ := cmd.Flags().GetBool("rebuild")
+
+		ctx, err := cli.NewCommandContext(globalConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+		defer func() { _ = ctx.Close() }()
+
+		objects, err := ctx.SearchCommand(query, limit, rebuild)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+
+		fmt.Print(cli.FormatListResult(objects, cli.OutputFormat(globalConfig.OutputFormat)))
+		return nil
+	},
+}
+
+var existsCmd = &cobra.Command{
+	Use:   "exists <key>",
+	Short: "Check if an object exists",
+	Long: `Check if an object exists in the object storage backend.
+Returns exit code 0 if the object exists, 1 if it does not.`,
+	Example: `  objstore exists myfile.txt                     # Check if file exists
+  objstore exists logs/2024/app.log              # Check with prefix
+  if objstore exists myfile.txt; then            # Use in shell script
+    echo "File exists"
+  fi`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key := args[0]
+
+		ctx, err := cli.NewCommandContext(globalConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+		defer func() { _ = ctx.Close() }()
+
+		exists, err := ctx.ExistsCommand(key)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+
+		fmt.Print(cli.FormatExistsResult(key, exists, cli.OutputFormat(globalConfig.OutputFormat)))
+
+		// Return non-zero exit code if object doesn't exist
+		if !exists {
+			os.Exit(1)
+		}
+		return nil
+	},
+}
+
+var metaCmd = &cobra.Command{
+	Use:   "meta",
+	Short: "Manage object metadata",
+	Long:  `Change the metadata of existing objects without rewriting their data.`,
+	Example: `  objstore meta set reports/q1.pdf owner=finance
+  objstore meta set --prefix logs/ retention=short --storage-class STANDARD_IA`,
+}
+
+var metaSetCmd = &cobra.Command{
+	Use:   "set [key] [name=value...]",
+	Short: "Set metadata of an object or of every object under a prefix",
+	Long: `Set custom metadata fields, given as name=value, and the content type,
+content encoding or storage class of an object. Fields not mentioned keep
+their values; --unset removes fields.
+With --prefix, the change is applied to every object whose key starts with
+the prefix, and no key is given. A server applies it in a single request
+over the rest and quic protocols. Objects that already match are skipped.`,
+	Example: `  objstore meta set reports/q1.pdf owner=finance reviewed=true
+  objstore meta set reports/q1.pdf --unset draft
+  objstore meta set --prefix logs/ retention=short
+  objstore meta set --prefix datasets/2023/ --storage-class GLACIER_IR --content-type text/csv`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		prefix, _ := cmd.Flags().GetString("prefix")                    //nolint:errcheck // flags are validated by cobra
+		contentType, _ := cmd.Flags().GetString("content-type")         //nolint:errcheck // flags are validated by cobra
+		contentEncoding, _ := cmd.Flags().GetString("content-encoding") //nolint:errcheck // flags are validated by cobra
+		storageClass, _ := cmd.Flags().GetString("storage-class")       //nolint:errcheck // flags are validated by cobra
+		unset, _ := cmd.Flags().GetStringSlice("unset")                 //nolint:errcheck // flags are validated by cobra
+		batch := cmd.Flags().Changed("prefix")
+
+		key := ""
+		if !batch {
+			if len(args) == 0 {
+				fmt.Fprintln(os.Stderr, cli.FormatError(cli.ErrMetadataTargetRequired, cli.OutputFormat(globalConfig.OutputFormat)))
+				return cli.ErrMetadataTargetRequired
+			}
+			key, args = args[0], args[1:]
+		}
+		fields, err := cli.ParseMetadataFields(args)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+		patch := &common.MetadataPatch{
+			ContentType:     contentType,
+			ContentEncoding: contentEncoding,
+			StorageClass:    storageClass,
+			Set:             fields,
+			Remove:          unset,
+		}
+
+		ctx, err := cli.NewCommandContext(globalConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+		defer func() { _ = ctx.Close() }()
+
+		var message string
+		if batch {
+			updated, err := ctx.UpdateMetadataBatchCommand(prefix, patch)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+				return err
+			}
+			message = fmt.Sprintf("Updated metadata of %d objects under '%s'", updated, prefix)
+		} else {
+			if err := ctx.PatchMetadataCommand(key, patch); err != nil {
+				fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+				return err
+			}
+			message = fmt.Sprintf("Updated metadata of '%s'", key)
+		}
+
+		result := &cli.OperationResult{
+			Success: true,
+			Message: message,
+		}
+		fmt.Print(cli.FormatOperationResult(result, cli.OutputFormat(globalConfig.OutputFormat)))
+		return nil
+	},
+}
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <keyA> <keyB>",
+	Short: "Compare two objects",
+	Long: `Compare two objects, or two versions of one object. A key followed by @ and
+an RFC 3339 time refers to the version that was current at that time, on
+versioned backends.
+
+Text objects are compared line by line and printed as a unified diff, JSON
+documents structurally as a list of changes, and other objects byte by byte
+up to the first difference. With --server the comparison runs on the server
+(REST protocol only), so neither object is downloaded; objects larger than
+the server's limit are only compared byte by byte.`,
+	Example: `  objstore diff config.json config.json.bak
+  objstore diff config.json@2025-06-01T00:00:00Z config.json
+  objstore diff notes.txt other.txt --context 10
+  objstore diff a.bin b.bin --mode binary -o json`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		modeFlag, _ := cmd.Flags().GetString("mode")
+		contextLines, _ := cmd.Flags().GetInt("context")
+
+		mode, err := diff.ParseMode(modeFlag)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+
+		ctx, err := cli.NewCommandContext(globalConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+		defer func() { _ = ctx.Close() }()
+
+		result, err := ctx.DiffCommand(args[0], args[1], diff.Options{Mode: mode, Context: contextLines})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+
+		fmt.Print(cli.FormatDiffResult(result, cli.OutputFormat(globalConfig.OutputFormat)))
+		return nil
+	},
+}
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Inspect audit logs",
+	Long:  `Inspect audit logs written by objstore-server.`,
+}
+
+var auditVerifyCmd = &cobra.Command{
+	Use:   "verify <log-file>",
+	Short: "Verify a hash-chained audit log",
+	Long: `Verify a hash-chained audit log written with objstore-server --audit-chain.
+
+Every record's hash and every checkpoint in the log is checked. With
+--anchor-dir, the log must also match the checkpoints anchored there, which
+detects a log rewritten or truncated after they were anchored. The command
+fails if the log has been tampered with.`,
+	Example: `  objstore audit verify /var/log/objstore/audit.chain
+  objstore audit verify /var/log/objstore/audit.chain --anchor-dir /mnt/worm/audit
+  objstore audit verify /var/log/objstore/audit.chain -o json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		anchorDir, _ := cmd.Flags().GetString("anchor-dir")
+
+		result, err := cli.VerifyAuditChainCommand(args[0], anchorDir)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+
+		fmt.Print(cli.FormatAuditVerificationResult(result, cli.OutputFormat(globalConfig.OutputFormat)))
+		return nil
+	},
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Show current configuration",
+	Long:  `Display the current configuration settings, including backend and output format.`,
+	Example: `  objstore config                                # Show current config
+  objstore config -o json                        # Show config as JSON
+  objstore --backend s3 --backend-bucket mybucket config  # Preview config`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// For config command, we don't need to create storage backend
+		// Just display the configuration
+		fmt.Print(cli.DisplayConfig(globalConfig, globalConfig.OutputFormat))
+		return nil
+	},
+}
+
+var archiveCmd = &cobra.Command{
+	Use:   "archive <key> <destination-backend>",
+	Short: "Archive an object to archival storage",
+	Long: `Archive an object to archival storage (local, glacier, azurearchive).
+This copies the object to long-term archival storage.
+
+For local archiver, use --destination-path to specify the archive directory.
+This allows archiving to a different mount point (e.g., NFS backup server).`,
+	Example: `  objstore archive logs/old.log local --destination-path /mnt/backup        # Archive to local backup mount
+  objstore archive data.zip local --destination-path /mnt/nfs/backups       # Archive to NFS mount
+  objstore archive logs/old.log glacier                                     # Archive to AWS Glacier
+  objstore archive backups/2023.tar azurearchive                            # Archive to Azure Archive
+  objstore --backend s3 archive old-data.zip glacier                        # From S3 to Glacier`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key := args[0]
+		destinationBackend := args[1]
+
+		// Get destination-specific settings from flags
+		destinationSettings := make(map[string]string)
+
+		if destPath, _ := cmd.Flags().GetString("destination-path"); destPath != "" { //nolint:errcheck // Optional flag, error can be safely ignored
+			destinationSettings["path"] = destPath
+		}
+		if destBucket, _ := cmd.Flags().GetString("destination-bucket"); destBucket != "" { //nolint:errcheck // Optional flag, error can be safely ignored
+			destinationSettings["bucket"] = destBucket
+		}
+		if destRegion, _ := cmd.Flags().GetString("destination-region"); destRegion != "" { //nolint:errcheck // Optional flag, error can be safely ignored
+			destinationSettings["region"] = destRegion
+		}
+		if destKey, _ := cmd.Flags().GetString("destination-key"); destKey != "" { //nolint:errcheck // Optional flag, error can be safely ignored
+			destinationSettings["access_key_id"] = destKey
+		}
+		if destSecret, _ := cmd.Flags().GetString("destination-secret"); destSecret != "" { //nolint:errcheck // Optional flag, error can be safely ignored
+			destinationSettings["secret_access_key"] = destSecret
+		}
+		if destURL, _ := cmd.Flags().GetString("destination-url"); destURL != "" { //nolint:errcheck // Optional flag, error can be safely ignored
+			destinationSettings["endpoint"] = destURL
+		}
+
+		ctx, err := cli.NewCommandContext(globalConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+		defer func() { _ = ctx.Close() }()
+
+		if err := ctx.ArchiveCommandWithSettings(key, destinationBackend, destinationSettings); err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+
+		result := &cli.OperationResult{
+			Success: true,
+			Message: fmt.Sprintf("Successfully archived '%s' to %s", key, destinationBackend),
+		}
+		fmt.Print(cli.FormatOperationResult(result, cli.OutputFormat(globalConfig.OutputFormat)))
+		return nil
+	},
+}
+
+// Policy command group
+var policyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "Manage lifecycle policies",
+	Long: `Manage lifecycle policies for automatic object lifecycle management.
+
+Lifecycle policies allow you to automatically delete or archive objects after a specified retention period.`,
+	Example: `  objstore policy add cleanup-old-logs logs/ 30 delete    # Delete logs after 30 days
+  objstore policy add archive-backups backups/ 90 archive # Archive backups after 90 days
+  objstore policy list                                     # List all policies
+  objstore policy remove cleanup-old-logs                  # Remove a policy
+  objstore policy simulate inventory.jsonl                 # Preview policies against an inventory`,
+}
+
+var policyAddCmd = &cobra.Command{
+	Use:   "add <id> <prefix> <retention-days> <action>",
+	Short: "Add a lifecycle policy",
+	Long: `Add a lifecycle policy to automatically manage objects.
+
+The policy will apply to all objects matching the specified prefix.
+After the retention period (in days), objects will be deleted, archived or
+moved to another storage class.
+
+Actions:
+  delete     - Permanently delete objects after retention period
+  archive    - Move objects to archival storage after retention period
+  transition - Move objects to --storage-class after retention period`,
+	Example: `  objstore policy add cleanup-old-logs logs/ 30 delete           # Delete logs after 30 days
+  objstore policy add archive-reports reports/ 365 archive       # Archive reports after 1 year
+  objstore policy add temp-cleanup temp/ 1 delete                # Delete temp files after 1 day
+  objstore policy add monthly-archive data/monthly/ 90 archive   # Archive monthly data after 90 days
+  objstore policy add cool-reports reports/ 30 transition --storage-class STANDARD_IA`,
+	Args: cobra.ExactArgs(4),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id := args[0]
+		prefix := args[1]
+		retentionDays := args[2]
+		action := args[3]
+		storageClass, _ := cmd.Flags().GetString("storage-class") //nolint:errcheck // flags are validated by cobra
+
+		ctx, err := cli.NewCommandContext(globalConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+		defer func() { _ = ctx.Close() }()
+
+		if err := ctx.AddPolicyCommandWithStorageClass(id, prefix, retentionDays, action, storageClass); err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+
+		result := &cli.OperationResult{
+			Success: true,
+			Message: fmt.Sprintf("Successfully added policy '%s'", id),
+		}
+		fmt.Print(cli.FormatOperationResult(result, cli.OutputFormat(globalConfig.OutputFormat)))
+		return nil
+	},
+}
+
+var policyRemoveCmd = &cobra.Command{
+	Use:   "remove <id>",
+	Short: "Remove a lifecycle policy",
+	Long: `Remove a lifecycle policy by ID.
+
+This stops the policy from being applied to new objects. Existing objects are not affected.`,
+	Example: `  objstore policy remove cleanup-old-logs        # Remove policy by ID
+  objstore policy remove temp-cleanup            # Remove temp cleanup policy`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id := args[0]
+
+		ctx, err := cli.NewCommandContext(globalConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+		defer func() { _ = ctx.Close() }()
+
+		if err := ctx.RemovePolicyCommand(id); err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+
+		result := &cli.OperationResult{
+			Success: true,
+			Message: fmt.Sprintf("Successfully removed policy '%s'", id),
+		}
+		fmt.Print(cli.FormatOperationResult(result, cli.OutputFormat(globalConfig.OutputFormat)))
+		return nil
+	},
+}
+
+var policyListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all lifecycle policies",
+	Long: `List all configured lifecycle policies.
+
+Shows all active policies with their IDs, prefixes, retention periods, and actions.`,
+	Example: `  objstore policy list                           # List all policies
+  objstore policy list -o json                   # List policies as JSON
+  objstore policy list -o table                  # List policies in table format`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, err := cli.NewCommandContext(globalConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+		defer func() { _ = ctx.Close() }()
+
+		policies, err := ctx.ListPoliciesCommand()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+
+		fmt.Print(cli.FormatPoliciesResult(policies, cli.OutputFormat(globalConfig.OutputFormat)))
+		return nil
+	},
+}
+
+var policyApplyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Execute all lifecycle policies",
+	Long: `Execute all configured lifecycle policies now.
+
+This scans all objects and applies deletion or archival actions based on configured retention periods.
+Use this command in cron jobs for scheduled policy execution.`,
+	Example: `  objstore policy apply                          # Apply all policies
+  objstore policy apply --server http://localhost:8080  # Apply policies on remote server
+  # Cron job example (daily at 2 AM):
+  # 0 2 * * * /usr/local/bin/objstore policy apply`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, err := cli.NewCommandContext(globalConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+		defer func() { _ = ctx.Close() }()
+
+		if err := ctx.ApplyPoliciesCommand(); err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+
+		result := &cli.OperationResult{
+			Success: true,
+			Message: "Successfully applied all lifecycle policies",
+		}
+		fmt.Print(cli.FormatOperationResult(result, cli.OutputFormat(globalConfig.OutputFormat)))
+		return nil
+	},
+}
+
+var policySimulateCmd = &cobra.Command{
+	Use:   "simulate <inventory>",
+	Short: "Simulate lifecycle policies against an inventory",
+	Long: `Replay an inventory export against lifecycle policies and report, per month,
+the objects and bytes they would have deleted, archived or transitioned, and
+would in the months after the inventory was taken. Nothing is changed.
+
+The inventory is a JSON Lines or CSV file written by "objstore list --all --output-file".
+Proposed policies are read from a YAML or JSON file given with --policies;
+without it the current policies are simulated. Policy files list policies
+with id, prefix, retention_days, action and, for transitions, storage_class.`,
+	Example: `  objstore list --all --output-file inventory.jsonl
+  objstore policy simulate inventory.jsonl --policies proposed.yaml
+  objstore policy simulate inventory.csv --horizon 90d -o table`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inventory := args[0]
+		policies, _ := cmd.Flags().GetString("policies") //nolint:errcheck // flags are validated by cobra
+		horizon, _ := cmd.Flags().GetString("horizon")   //nolint:errcheck // flags are validated by cobra
+		snapshot, _ := cmd.Flags().GetString("as-of")    //nolint:errcheck // flags are validated by cobra
+
+		opts := &lifecycle.Options{}
+		if horizon != "" {
+			bounds, err := access.ParseBuckets(horizon)
+			if err != nil || len(bounds) != 1 {
+				err = fmt.Errorf("invalid horizon %q", horizon)
+				fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+				return err
+			}
+			opts.Horizon = bounds[0]
+		}
+		if snapshot != "" {
+			t, err := time.Parse(time.RFC3339, snapshot)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+				return err
+			}
+			opts.SnapshotTime = t
+		}
+
+		ctx, err := cli.NewCommandContext(globalConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+		defer func() { _ = ctx.Close() }()
+
+		report, err := ctx.SimulatePoliciesCommand(inventory, policies, opts)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+
+		fmt.Print(cli.FormatSimulationReport(report, cli.OutputFormat(globalConfig.OutputFormat)))
+		return nil
+	},
+}
+
+var applyCmd = &cobra.Command{
+	Use:   "apply -f <file>",
+	Short: "Bring the server to a declared configuration",
+	Long: `Reconcile the lifecycle policies, replication policies and aliases of a server
+with those declared in a YAML or JSON file, so they can be kept in version
+control and applied from CI.
+
+The changes are listed before they are made: "+" creates, "~" updates and "-"
+deletes. Only the sections present in the file are managed; an empty section
+deletes everything of its kind. With --dry-run nothing is changed. Requires
+--server.`,
+	Example: `  objstore --server http://localhost:8080 apply -f objstore.yaml --dry-run
+  objstore --server http://localhost:8080 apply -f objstore.yaml
+  objstore --server http://localhost:8080 apply -f objstore.yaml -o json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, _ := cmd.Flags().GetString("file")    //nolint:errcheck // flags are validated by cobra
+		dryRun, _ := cmd.Flags().GetBool("dry-run") //nolint:errcheck // flags are validated by cobra
+		if file == "" {
+			err := errors.New("a desired state file is required: set -f")
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+
+		ctx, err := cli.NewCommandContext(globalConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+		defer func() { _ = ctx.Close() }()
+
+		result, err := ctx.ApplyCommand(file, dryRun)
+		if result != nil {
+			fmt.Print(cli.FormatApplyResult(result, cli.OutputFormat(globalConfig.OutputFormat)))
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+		return nil
+	},
+}
+
+var healthCmd = &cobra.Command{
+	Use:   "health",
+	Short: "Check health status",
+	Long: `Check the health status of the object storage backend.
+
+Returns the backend status, version, and configuration information.`,
+	Example: `  objstore health                                # Check health status
+  objstore health -o json                        # Get health status as JSON
+  objstore --backend s3 health                   # Check S3 backend health`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, err := cli.NewCommandContext(globalConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+		defer func() { _ = ctx.Close() }()
+
+		health, err := ctx.HealthCommand()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+
+		fmt.Print(cli.FormatHealthResult(health, cli.OutputFormat(globalConfig.OutputFormat)))
+		return nil
+	},
+}
+
+var proxyCmd = &cobra.Command{
+	Use:   "proxy",
+	Short: "Run a local caching HTTP proxy",
+	Long: `Run a local HTTP proxy that serves objects from a disk cache.
+
+The proxy answers GET and HEAD on /objects/{key} (and /api/v1/objects/{key},
+so REST clients can point at it unchanged). Misses are fetched from the
+origin: the server given with --server, otherwise the configured backend.
+Cached objects are revalidated by ETag before they are served, so a repeated
+download costs one metadata request instead of a full transfer. The least
+recently used objects are evicted to keep the cache under --cache-size.
+
+Responses carry an X-Cache header of HIT, REVALIDATED, MISS, STALE (served
+from cache because the origin was unreachable) or BYPASS (too large to
+cache).`,
+	Example: `  objstore --server http://objstore:8080 proxy --cache-dir /var/cache/objstore
+  objstore --backend s3 --backend-bucket artifacts proxy --cache-size 50GiB
+  objstore proxy --listen 0.0.0.0:8081 --revalidate-after 5m`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		listen, _ := cmd.Flags().GetString("listen")
+		cacheDir, _ := cmd.Flags().GetString("cache-dir")
+		cacheSizeFlag, _ := cmd.Flags().GetString("cache-size")
+		revalidateAfter, _ := cmd.Flags().GetDuration("revalidate-after")
+
+		cacheSize, err := cli.ParseSize(cacheSizeFlag)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+
+		ctx, err := cli.NewCommandContext(globalConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+		defer func() { _ = ctx.Close() }()
+
+		handler, err := ctx.ProxyCommand(cacheDir, cacheSize, revalidateAfter)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+
+		server := &http.Server{
+			Addr:              listen,
+			Handler:           handler,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		signalCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		go func() {
+			<-signalCtx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			_ = server.Shutdown(shutdownCtx)
+		}()
+
+		fmt.Fprintf(os.Stderr, "Caching proxy listening on %s (cache %s)\n", listen, cacheDir)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	},
+}
+
+// Dedup command group
+var dedupCmd = &cobra.Command{
+	Use:   "dedup",
+	Short: "Inspect content-addressable deduplication",
+	Long: `Inspect the content-addressable storage (CAS) layer.
+
+With --dedup, objects are split into content-defined chunks that are stored
+once by hash, and each key holds a manifest of its chunks. Identical data
+across keys, such as near-identical build artifacts, is stored only once.`,
+	Example: `  objstore --dedup put app-1.2.3.tar.gz builds/app-1.2.3.tar.gz  # Store deduplicated
+  objstore dedup stats                                             # Show dedup savings`,
+}
+
+var dedupStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show deduplication statistics",
+	Long:  `Show the number of deduplicated objects, unique chunks and the space saved.`,
+	Example: `  objstore dedup stats                           # Show dedup savings
+  objstore dedup stats -o json                   # Get statistics as JSON`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, err := cli.NewCommandContext(globalConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+		defer func() { _ = ctx.Close() }()
+
+		stats, err := ctx.DedupStatsCommand()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+
+		fmt.Print(cli.FormatDedupStats(stats, cli.OutputFormat(globalConfig.OutputFormat)))
+		return nil
+	},
+}
+
+var dupesCmd = &cobra.Command{
+	Use:   "dupes [prefix]",
+	Short: "Report objects with the same content",
+	Long: `Group the objects under a prefix by content checksum and report every set
+of two or more identical objects, with the space that keeping a single copy
+of each would free. Nothing is deleted.
+
+Objects are compared by the SHA-256 digest recorded when checksums are
+enabled on the server, or by their MD5 ETag on backends that report one,
+so no data is read. Objects with neither are counted and left out, unless
+--hash is given to download and hash them.`,
+	Example: `  objstore dupes                                 # Report duplicates across the backend
+  objstore dupes datasets/ --hash                # Hash objects without a checksum too
+  objstore dupes -o json > dupes.json            # Get the duplicate sets as JSON`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		prefix := ""
+		if len(args) > 0 {
+			prefix = args[0]
+		}
+		hash, _ := cmd.Flags().GetBool("hash") //nolint:errcheck // flags are validated by cobra
+
+		ctx, err := cli.NewCommandContext(globalConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+		defer func() { _ = ctx.Close() }()
+
+		report, err := ctx.DuplicatesCommand(prefix, hash)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+
+		fmt.Print(cli.FormatDuplicateReport(report, cli.OutputFormat(globalConfig.OutputFormat)))
+		return nil
+	},
+}
+
+var analyzeCmd = &cobra.Command{
+	Use:   "analyze [prefix]",
+	Short: "Show how old objects are and when they were last read",
+	Long: `Count the objects under a prefix by the time since they were last modified
+and since they were last read, in total and by prefix, to choose lifecycle
+retention periods from data.
+
+Reads are recorded by a server started with --access-tracking. With --server
+its recorded reads are used (REST protocol only). In local mode, --access-file
+names the server's --access-file; without it only ages are reported. Objects
+not read since tracking started are counted as "never".`,
+	Example: `  objstore --server http://localhost:8080 analyze logs/
+  objstore analyze logs/ --depth 2 --buckets 7d,30d,90d -o table
+  objstore analyze --access-file /data/.access.json -o json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		prefix := ""
+		if len(args) > 0 {
+			prefix = args[0]
+		}
+		depth, _ := cmd.Flags().GetInt("depth")               //nolint:errcheck // flags are validated by cobra
+		buckets, _ := cmd.Flags().GetString("buckets")        //nolint:errcheck // flags are validated by cobra
+		accessFile, _ := cmd.Flags().GetString("access-file") //nolint:errcheck // flags are validated by cobra
+
+		opts := &access.AnalyzeOptions{Depth: depth}
+		if buckets != "" {
+			bounds, err := access.ParseBuckets(buckets)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+				return err
+			}
+			opts.Buckets = bounds
+		}
+
+		ctx, err := cli.NewCommandContext(globalConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+		defer func() { _ = ctx.Close() }()
+
+		report, err := ctx.AnalyzeCommand(prefix, opts, accessFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+
+		fmt.Print(cli.FormatAnalyzeReport(report, cli.OutputFormat(globalConfig.OutputFormat)))
+		return nil
+	},
+}
+
+// Keychain command group
+var keychainCmd = &cobra.Command{
+	Use:   "keychain",
+	Short: "Manage end-to-end encryption keys",
+	Long: `Manage the keychain used for end-to-end encryption.
+
+With --e2e-keychain, the CLI encrypts object data and custom metadata before
+it leaves the machine and decrypts it after download. The server and the
+backend only ever see ciphertext. Keep the keychain file safe: objects cannot
+be read without the key they were written with.`,
+	Example: `  objstore --e2e-keychain ~/.objstore-keys.json keychain generate team --default
+  objstore --e2e-keychain ~/.objstore-keys.json put secret.pdf docs/secret.pdf`,
+}
+
+var keychainGenerateCmd = &cobra.Command{
+	Use:   "generate <key-id>",
+	Short: "Generate a new encryption key",
+	Long: `Generate a random 256-bit key and add it to the keychain file, creating the
+file if it does not exist. The first key becomes the default; pass --default
+to make a later key the one new objects are encrypted with.`,
+	Example: `  objstore --e2e-keychain keys.json keychain generate team           # Add a key
+  objstore --e2e-keychain keys.json keychain generate team-2 --default # Rotate the default key`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		makeDefault, _ := cmd.Flags().GetBool("default")
+
+		info, err := cli.KeychainGenerateCommand(globalConfig.E2EKeychain, args[0], makeDefault)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+
+		fmt.Print(cli.FormatKeychainInfo(info, cli.OutputFormat(globalConfig.OutputFormat)))
+		return nil
+	},
+}
+
+var keychainListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the keys in the keychain",
+	Long:  `List the key IDs in the keychain file. The default key is marked with '*'.`,
+	Example: `  objstore --e2e-keychain keys.json keychain list          # List key IDs
+  objstore --e2e-keychain keys.json keychain list -o json  # Get key IDs as JSON`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		info, err := cli.KeychainListCommand(globalConfig.E2EKeychain)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+
+		fmt.Print(cli.FormatKeychainInfo(info, cli.OutputFormat(globalConfig.OutputFormat)))
+		return nil
+	},
+}
+
+var keychainEscrowCmd = &cobra.Command{
+	Use:   "escrow [recovery-public-key-file]",
+	Short: "Escrow data keys with an organization recovery key",
+	Long: `Turn on escrow mode: the data key of every object encrypted from now on is
+also wrapped with the public half of an organization recovery key. If the
+keychain is lost, the offline private half recovers the objects with
+'objstore recover'. Objects written before escrow was turned on are not
+escrowed. Pass --disable to turn escrow mode off.`,
+	Example: `  objstore keychain recovery-key /mnt/vault/recovery.json recovery.pub.json  # Done once per organization
+  objstore --e2e-keychain keys.json keychain escrow recovery.pub.json       # Escrow new objects
+  objstore --e2e-keychain keys.json keychain escrow --disable               # Stop escrowing`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		disable, _ := cmd.Flags().GetBool("disable")
+		if disable == (len(args) == 1) {
+			return errors.New("pass either a recovery public key file or --disable")
+		}
+		publicKeyPath := ""
+		if len(args) == 1 {
+			publicKeyPath = args[0]
+		}
+
+		info, err := cli.KeychainEscrowCommand(globalConfig.E2EKeychain, publicKeyPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+
+		fmt.Print(cli.FormatKeychainInfo(info, cli.OutputFormat(globalConfig.OutputFormat)))
+		return nil
+	},
+}
+
+var keychainRecoveryKeyCmd = &cobra.Command{
+	Use:   "recovery-key <private-key-file> <public-key-file>",
+	Short: "Generate an organization recovery key",
+	Long: `Generate an X25519 recovery key for escrow mode. The private key file
+decrypts every escrowed object: move it offline, for example to removable
+media in a safe. The public key file is handed to 'keychain escrow'.
+Existing files are never overwritten.`,
+	Example: `  objstore keychain recovery-key /mnt/vault/recovery.json recovery.pub.json`,
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		info, err := cli.RecoveryKeyGenerateCommand(args[0], args[1])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+
+		fmt.Print(cli.FormatRecoveryKeyInfo(info, cli.OutputFormat(globalConfig.OutputFormat)))
+		return nil
+	},
+}
+
+var recoverCmd = &cobra.Command{
+	Use:   "recover <prefix> <directory>",
+	Short: "Recover end-to-end encrypted objects with the escrow recovery key",
+	Long: `Decrypt every object under a prefix into a local directory when the keychain
+that encrypted them is lost. Objects are read with --e2e-keychain when it
+holds their key and with the --e2e-recovery-key escrow key otherwise.
+Objects that cannot be decrypted, such as objects written before escrow
+was turned on, are reported and skipped.`,
+	Example: `  objstore --e2e-recovery-key /mnt/vault/recovery.json recover hr/ ./recovered       # Recover a prefix
+  objstore --e2e-recovery-key /mnt/vault/recovery.json recover "" ./recovered -o json # Recover everything`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, err := cli.NewCommandContext(globalConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+		defer func() { _ = ctx.Close() }()
+
+		report, err := ctx.RecoverCommand(args[0], args[1])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+
+		fmt.Print(cli.FormatRecoveryReport(report, cli.OutputFormat(globalConfig.OutputFormat)))
+		return nil
+	},
+}
+
+// Signing command group
+var signingCmd = &cobra.Command{
+	Use:   "signing",
+	Short: "Manage object signing keys",
+	Long: `Sign objects when they are written and verify them when they are read.
+
+With --sign-key, put signs a manifest of every object (its key, size and
+SHA-256 digest) with an ed25519 key and stores the signature as metadata.
+With --verify-keys, get refuses objects that are unsigned, signed by an
+untrusted key, or modified since they were signed, so consumers can trust
+objects even if the backend is compromised.`,
+	Example: `  objstore signing generate-key release.pem release.pub
+  objstore --sign-key release.pem put app.tgz releases/app.tgz
+  objstore --verify-keys release.pub get releases/app.tgz app.tgz`,
+}
+
+var signingGenerateKeyCmd = &cobra.Command{
+	Use:   "generate-key <private-key-file> <public-key-file>",
+	Short: "Generate an ed25519 signing key",
+	Long: `Generate an ed25519 signing key. The private key file is passed to
+--sign-key by producers and the public key file to --verify-keys by
+consumers. Keys are PEM files compatible with OpenSSL. Existing files are
+never overwritten.`,
+	Example: `  objstore signing generate-key release.pem release.pub`,
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		info, err := cli.SigningKeyGenerateCommand(args[0], args[1])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+
+		fmt.Print(cli.FormatSigningKeyInfo(info, cli.OutputFormat(globalConfig.OutputFormat)))
+		return nil
+	},
+}
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify <key>",
+	Short: "Verify the signature of an object",
+	Long: `Read an object and check it against its signed manifest with the
+--verify-keys public keys. The manifest is printed when the object is
+intact; otherwise the command fails.`,
+	Example: `  objstore --verify-keys release.pub verify releases/app.tgz
+  objstore --verify-keys release.pub,ci.pub verify releases/app.tgz -o json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, err := cli.NewCommandContext(globalConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+		defer func() { _ = ctx.Close() }()
+
+		manifest, err := ctx.VerifyCommand(args[0])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+
+		fmt.Print(cli.FormatManifest(manifest, cli.OutputFormat(globalConfig.OutputFormat)))
+		return nil
+	},
+}
+
+// Admin command group
+var adminCmd = &cobra.Command{
+	Use:   "admin",
+	Short: "Back up and restore objstore's control-plane state",
+	Long: `Back up and restore the state objstore keeps next to the data:
+lifecycle and replication policies, legal holds and deletion requests in the
+data directory, and configuration files such as overlays with quotas,
+residency rules, cost groups or notification rules.`,
+	Example: `  objstore --backend-path /data admin backup state.tgz --config-file /etc/objstore/overlays.yaml
+  objstore --backend-path /data admin restore state.tgz --config-dir /etc/objstore`,
+}
+
+var adminBackupCmd = &cobra.Command{
+	Use:   "backup [archive]",
+	Short: "Export the control-plane state to a single archive",
+	Long: `Export the control-plane state to a gzip-compressed tar archive. The
+archive is written to a file or, with --key, stored as an object in the
+configured backend. Its manifest records every file's path, size and
+SHA-256 digest.
+
+State files are read from --state-dir, which defaults to the local
+backend's --backend-path. Configuration files are added with --config-file.`,
+	Example: `  objstore --backend-path /data admin backup state.tgz
+  objstore --backend-path /data admin backup --config-file overlays.yaml --config-file residency.yaml state.tgz
+  objstore --backend s3 --backend-bucket backups admin backup --state-dir /data --key objstore/state.tgz`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		archivePath := ""
+		if len(args) > 0 {
+			archivePath = args[0]
+		}
+		key, _ := cmd.Flags().GetString("key")                      //nolint:errcheck
+		stateDir, _ := cmd.Flags().GetString("state-dir")           //nolint:errcheck
+		configFiles, _ := cmd.Flags().GetStringSlice("config-file") //nolint:errcheck
+
+		ctx, err := cli.NewCommandContext(globalConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+		defer func() { _ = ctx.Close() }()
+
+		manifest, err := ctx.AdminBackupCommand(archivePath, key, backup.Sources{StateDir: stateDir, ConfigFiles: configFiles})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+
+		fmt.Print(cli.FormatBackupManifest(manifest, cli.OutputFormat(globalConfig.OutputFormat)))
+		return nil
+	},
+}
+
+var adminRestoreCmd = &cobra.Command{
+	Use:   "restore [archive]",
+	Short: "Restore the control-plane state from an archive",
+	Long: `Restore the control-plane state from an archive file or, with --key,
+from an object in the configured backend. Every file is checked against the
+archive's manifest first, and either all files are restored or none are.
+
+Files are written to the paths they were backed up from. On a new node use
+--state-dir and --config-dir to restore them elsewhere. Stop the server
+before restoring; it reads these files at startup.`,
+	Example: `  objstore admin restore state.tgz
+  objstore admin restore state.tgz --state-dir /data --config-dir /etc/objstore
+  objstore --backend s3 --backend-bucket backups admin restore --key objstore/state.tgz --state-dir /data`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		archivePath := ""
+		if len(args) > 0 {
+			archivePath = args[0]
+		}
+		key, _ := cmd.Flags().GetString("key")              //nolint:errcheck
+		stateDir, _ := cmd.Flags().GetString("state-dir")   //nolint:errcheck
+		configDir, _ := cmd.Flags().GetString("config-dir") //nolint:errcheck
+
+		ctx, err := cli.NewCommandContext(globalConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+		defer func() { _ = ctx.Close() }()
+
+		files, err := ctx.AdminRestoreCommand(archivePath, key, backup.Targets{StateDir: stateDir, ConfigDir: configDir})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+
+		fmt.Print(cli.FormatRestoredFiles(files, cli.OutputFormat(globalConfig.OutputFormat)))
+		return nil
+	},
+}
+
+// Cost command group
+var costCmd = &cobra.Command{
+	Use:   "cost",
+	Short: "Estimate storage costs",
+	Long: `Estimate what storage costs before the provider's bill arrives.
+
+A server started with --cost meters requests, stored bytes and egress per
+backend and key group, and prices them with its pricing tables.`,
+}
+
+var costReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Show the estimated cost of a month",
+	Long: `Show the storage, request and egress cost of a month by backend and group,
+and for the current month a projection for the whole month.
+
+With --server, the server's metered usage is reported (REST protocol only).
+In local mode, --cost-config names the server's cost configuration; the usage
+in its state file is priced.`,
+	Example: `  objstore --server http://localhost:8080 cost report
+  objstore --server http://localhost:8080 cost report --month 2025-10 -o json
+  objstore cost report --cost-config /etc/objstore/cost.yaml -o table`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		month, _ := cmd.Flags().GetString("month")
+		configPath, _ := cmd.Flags().GetString("cost-config")
+
+		ctx, err := cli.NewCommandContext(globalConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+		defer func() { _ = ctx.Close() }()
+
+		report, err := ctx.CostReportCommand(month, configPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+
+		fmt.Print(cli.FormatCostReport(report, cli.OutputFormat(globalConfig.OutputFormat)))
+		return nil
+	},
+}
+
+// Jobs command group
+var jobsCmd = &cobra.Command{
+	Use:   "jobs",
+	Short: "Show background job status",
+	Long: `Show the status of the server's background jobs, such as lifecycle,
+replication, inventory and garbage collection.
+
+The server runs every job from one scheduler. With high availability only the
+elected leader runs jobs, and run history is shared by the cluster. Requires
+--server with the REST protocol.`,
+	Example: `  objstore --server http://localhost:8080 jobs list
+  objstore --server http://localhost:8080 jobs show lifecycle`,
+}
+
+var jobsListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List background jobs",
+	Long:    `List background jobs with their interval, the outcome of their last run and when they next run.`,
+	Example: `  objstore jobs list -o table`,
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, err := cli.NewCommandContext(globalConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+		defer func() { _ = ctx.Close() }()
+
+		list, err := ctx.JobsListCommand()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+
+		fmt.Print(cli.FormatJobsResult(list, cli.OutputFormat(globalConfig.OutputFormat)))
+		return nil
+	},
+}
+
+var jobsShowCmd = &cobra.Command{
+	Use:     "show <name>",
+	Short:   "Show a background job and its run history",
+	Long:    `Show a background job with its recent runs, newest first: the node that ran it, how long it took and its outcome.`,
+	Example: `  objstore jobs show replication -o json`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, err := cli.NewCommandContext(globalConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+		defer func() { _ = ctx.Close() }()
+
+		status, err := ctx.JobShowCommand(args[0])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+
+		fmt.Print(cli.FormatJobResult(status, cli.OutputFormat(globalConfig.OutputFormat)))
+		return nil
+	},
+}
+
+// Tasks command group
+var tasksCmd = &cobra.Command{
+	Use:   "tasks",
+	Short: "Inspect and retry queued tasks",
+	Long: `Inspect the server's durable task queue and retry dead-lettered tasks.
+
+Asynchronous archives, replication syncs and failed notification deliveries
+are queued as tasks and retried with exponential backoff. A task that fails
+every attempt is dead-lettered until it is retried. Requires --server with the
+REST protocol.`,
+	Example: `  objstore --server http://localhost:8080 tasks list --state dead
+  objstore --server http://localhost:8080 tasks retry 0f3c9a1e6b2d4c5f8a7e9d0b1c2f3a4e`,
+}
+
+var tasksListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List queued tasks",
+	Long:    `List queued tasks with their state, attempts and last error.`,
+	Example: `  objstore tasks list --state dead -o table`,
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, err := cli.NewCommandContext(globalConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+		defer func() { _ = ctx.Close() }()
+
+		state, _ := cmd.Flags().GetString("state")
+		list, err := ctx.TasksListCommand(state)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+
+		fmt.Print(cli.FormatTasksResult(list, cli.OutputFormat(globalConfig.OutputFormat)))
+		return nil
+	},
+}
+
+var tasksRetryCmd = &cobra.Command{
+	Use:     "retry <id>",
+	Short:   "Retry a dead-lettered task",
+	Long:    `Requeue a dead-lettered task with a fresh set of attempts.`,
+	Example: `  objstore tasks retry 0f3c9a1e6b2d4c5f8a7e9d0b1c2f3a4e`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, err := cli.NewCommandContext(globalConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+		defer func() { _ = ctx.Close() }()
+
+		task, err := ctx.TaskRetryCommand(args[0])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+
+		fmt.Print(cli.FormatTaskResult(task, cli.OutputFormat(globalConfig.OutputFormat)))
+		return nil
+	},
+}
+
+// Share link command group
+var shareCmd = &cobra.Command{
+	Use:   "share",
+	Short: "Manage public download links",
+	Long: `Create, list and revoke public download links to objects.
+
+Anyone holding a link can download its object from the server without
+credentials until the link expires, reaches its download limit or is
+revoked. Links can require a password and cap their download bandwidth.
+Requires --server with the REST protocol and a server started with --shares.`,
+	Example: `  objstore --server http://localhost:8080 share create reports/q1.pdf --ttl 48h --max-downloads 5
+  objstore --server http://localhost:8080 share list -o table
+  objstore --server http://localhost:8080 share revoke 3f2a9c1e5b7d4e6f8a0b1c2d3e4f5a6b`,
+}
+
+var shareCreateCmd = &cobra.Command{
+	Use:   "create <key>",
+	Short: "Create a download link to an object",
+	Long: `Create a public download link to an object and print its URL. The URL
+carries the link's token and is only shown once.`,
+	Example: `  objstore share create reports/q1.pdf --ttl 48h --max-downloads 5
+  objstore share create builds/app.tar.gz --password "correct horse" --bandwidth 1MiB`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ttl, _ := cmd.Flags().GetDuration("ttl")
+		maxDownloads, _ := cmd.Flags().GetInt("max-downloads")
+		password, _ := cmd.Flags().GetString("password")
+		bandwidthFlag, _ := cmd.Flags().GetString("bandwidth")
+
+		opts := share.Options{TTL: ttl, MaxDownloads: maxDownloads, Password: password}
+		if bandwidthFlag != "" {
+			bandwidth, err := cli.ParseSize(bandwidthFlag)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+				return err
+			}
+			opts.BytesPerSecond = bandwidth
+		}
+
+		ctx, err := cli.NewCommandContext(globalConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+		defer func() { _ = ctx.Close() }()
+
+		link, err := ctx.ShareCreateCommand(args[0], opts)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+
+		fmt.Print(cli.FormatSharedLinkResult(link, cli.OutputFormat(globalConfig.OutputFormat)))
+		return nil
+	},
+}
+
+var shareListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List download links",
+	Long:    `List download links with their status, download counts and bytes served.`,
+	Example: `  objstore share list -o table`,
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, err := cli.NewCommandContext(globalConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+		defer func() { _ = ctx.Close() }()
+
+		links, err := ctx.SharesListCommand()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+
+		fmt.Print(cli.FormatSharesResult(links, cli.OutputFormat(globalConfig.OutputFormat)))
+		return nil
+	},
+}
+
+var shareRevokeCmd = &cobra.Command{
+	Use:     "revoke <id>",
+	Short:   "Revoke a download link",
+	Long:    `Revoke a download link by its ID, as shown by share list.`,
+	Example: `  objstore share revoke 3f2a9c1e5b7d4e6f8a0b1c2d3e4f5a6b`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, err := cli.NewCommandContext(globalConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+		defer func() { _ = ctx.Close() }()
+
+		if err := ctx.ShareRevokeCommand(args[0]); err != nil {
+			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
+			return err
+		}
+
+		result := &cli.OperationResult{
+			Success: true,
+			Message: fmt.Sprintf("Revoked share link '%s'", args[0]),
 		}
 		fmt.Print(cli.FormatOperationResult(result, cli.OutputFormat(globalConfig.OutputFormat)))
 		return nil
 	},
 }
 
-var getCmd = &cobra.Command{
-	Use:   "get <key> [output-file]",
-	Short: "Download a file from object storage or get its metadata",
-	Long: `Download a file from the object storage backend or retrieve its metadata.
-If output-file is not specified or is '-', the content will be written to stdout.
-Use --metadata flag to retrieve only metadata instead of the file content.`,
-	Example: `  objstore get myfile.txt                        # Download to stdout
-  objstore get myfile.txt downloaded.txt         # Download to file
-  objstore get logs/2024/app.log -               # Download to stdout explicitly
-  objstore get myfile.txt --metadata             # Get metadata only
-  objstore get myfile.txt --metadata -o json     # Get metadata as JSON`,
-	Args: cobra.RangeArgs(1, 2),
-	RunE: func(cmd *cobra.Command, args []string) error {
-		key := args[0]
-		metadataOnly, _ := cmd.Flags().GetBool("metadata") //nolint:errcheck // flags are validated by cobra
+// Alias command group
+var aliasCmd = &cobra.Command{
+	Use:   "alias",
+	Short: "Manage object aliases",
+	Long: `Set, resolve, list and remove aliases.
+
+An alias is a small object naming another, such as latest pointing at
+releases/v1.4.2/app.tar.gz. Reading an alias name that holds no object
+reads its target, so a well-known name can follow new releases without
+copying them. Aliases point at objects, never at other aliases. With
+--server, requires the REST protocol and a server started with --aliases.`,
+	Example: `  objstore alias set latest releases/v1.4.2/app.tar.gz
+  objstore get latest app.tar.gz
+  objstore alias list releases/ -o table`,
+}
 
+var aliasSetCmd = &cobra.Command{
+	Use:   "set <name> <target>",
+	Short: "Point an alias at an object",
+	Long: `Point an alias at an existing object, creating the alias or moving it.
+No object may exist at the alias name.`,
+	Example: `  objstore alias set latest releases/v1.4.2/app.tar.gz`,
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx, err := cli.NewCommandContext(globalConfig)
 		if err != nil {
 			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
@@ -153,51 +1921,24 @@ Use --metadata flag to retrieve only metadata instead of the file content.`,
 		}
 		defer func() { _ = ctx.Close() }()
 
-		// If --metadata flag is set, return metadata only
-		if metadataOnly {
-			metadata, err := ctx.GetMetadataCommand(key)
-			if err != nil {
-				fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
-				return err
-			}
-			fmt.Print(cli.FormatMetadataResult(metadata, cli.OutputFormat(globalConfig.OutputFormat)))
-			return nil
-		}
-
-		// Otherwise, download the file
-		outputPath := ""
-		if len(args) > 1 {
-			outputPath = args[1]
-		}
-
-		if err := ctx.GetCommand(key, outputPath); err != nil {
+		a, err := ctx.AliasSetCommand(args[0], args[1])
+		if err != nil {
 			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
 			return err
 		}
 
-		// Only print success message if not writing to stdout
-		if outputPath != "" && outputPath != "-" {
-			result := &cli.OperationResult{
-				Success: true,
-				Message: fmt.Sprintf("Successfully downloaded '%s' to '%s'", key, outputPath),
-			}
-			fmt.Print(cli.FormatOperationResult(result, cli.OutputFormat(globalConfig.OutputFormat)))
-		}
+		fmt.Print(cli.FormatAliasResult(a, cli.OutputFormat(globalConfig.OutputFormat)))
 		return nil
 	},
 }
 
-var deleteCmd = &cobra.Command{
-	Use:   "delete <key>",
-	Short: "Delete an object from storage",
-	Long:  `Delete an object from the object storage backend.`,
-	Example: `  objstore delete myfile.txt                     # Delete a file
-  objstore delete logs/2024/app.log              # Delete file with prefix
-  objstore delete temp/                          # Delete a specific key (not recursive)`,
-	Args: cobra.ExactArgs(1),
+var aliasGetCmd = &cobra.Command{
+	Use:     "get <name>",
+	Short:   "Resolve an alias",
+	Long:    `Print the object an alias points at.`,
+	Example: `  objstore alias get latest`,
+	Args:    cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		key := args[0]
-
 		ctx, err := cli.NewCommandContext(globalConfig)
 		if err != nil {
 			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
@@ -205,30 +1946,23 @@ var deleteCmd = &cobra.Command{
 		}
 		defer func() { _ = ctx.Close() }()
 
-		if err := ctx.DeleteCommand(key); err != nil {
+		a, err := ctx.AliasGetCommand(args[0])
+		if err != nil {
 			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
 			return err
 		}
 
-		result := &cli.OperationResult{
-			Success: true,
-			Message: fmt.Sprintf("Successfully deleted '%s'", key),
-		}
-		fmt.Print(cli.FormatOperationResult(result, cli.OutputFormat(globalConfig.OutputFormat)))
+		fmt.Print(cli.FormatAliasResult(a, cli.OutputFormat(globalConfig.OutputFormat)))
 		return nil
 	},
 }
 
-var listCmd = &cobra.Command{
-	Use:   "list [prefix]",
-	Short: "List objects in storage",
-	Long:  `List all objects in the object storage backend, optionally filtered by prefix.`,
-	Example: `  objstore list                                  # List all objects
-  objstore list logs/                            # List objects with 'logs/' prefix
-  objstore list logs/2024/                       # List objects in logs/2024/
-  objstore list -o json                          # List all objects as JSON
-  objstore list logs/ -o table                   # List with table format`,
-	Args: cobra.MaximumNArgs(1),
+var aliasListCmd = &cobra.Command{
+	Use:     "list [prefix]",
+	Short:   "List aliases",
+	Long:    `List aliases and their targets, optionally only those whose names start with prefix.`,
+	Example: `  objstore alias list releases/ -o table`,
+	Args:    cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		prefix := ""
 		if len(args) > 0 {
@@ -242,31 +1976,24 @@ var listCmd = &cobra.Command{
 		}
 		defer func() { _ = ctx.Close() }()
 
-		objects, err := ctx.ListCommand(prefix)
+		aliases, err := ctx.AliasListCommand(prefix)
 		if err != nil {
 			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
 			return err
 		}
 
-		fmt.Print(cli.FormatListResult(objects, cli.OutputFormat(globalConfig.OutputFormat)))
+		fmt.Print(cli.FormatAliasesResult(aliases, cli.OutputFormat(globalConfig.OutputFormat)))
 		return nil
 	},
 }
 
-var existsCmd = &cobra.Command{
-	Use:   "exists <key>",
-	Short: "Check if an object exists",
-	Long: `Check if an object exists in the object storage backend.
-Returns exit code 0 if the object exists, 1 if it does not.`,
-	Example: `  objstore exists myfile.txt                     # Check if file exists
-  objstore exists logs/2024/app.log              # Check with prefix
-  if objstore exists myfile.txt; then            # Use in shell script
-    echo "File exists"
-  fi`,
-	Args: cobra.ExactArgs(1),
+var aliasRmCmd = &cobra.Command{
+	Use:     "rm <name>",
+	Short:   "Remove an alias",
+	Long:    `Remove an alias. The object it points at is not changed.`,
+	Example: `  objstore alias rm latest`,
+	Args:    cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		key := args[0]
-
 		ctx, err := cli.NewCommandContext(globalConfig)
 		if err != nil {
 			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
@@ -274,76 +2001,47 @@ Returns exit code 0 if the object exists, 1 if it does not.`,
 		}
 		defer func() { _ = ctx.Close() }()
 
-		exists, err := ctx.ExistsCommand(key)
-		if err != nil {
+		if err := ctx.AliasDeleteCommand(args[0]); err != nil {
 			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
 			return err
 		}
 
-		fmt.Print(cli.FormatExistsResult(key, exists, cli.OutputFormat(globalConfig.OutputFormat)))
-
-		// Return non-zero exit code if object doesn't exist
-		if !exists {
-			os.Exit(1)
+		result := &cli.OperationResult{
+			Success: true,
+			Message: fmt.Sprintf("Removed alias '%s'", args[0]),
 		}
+		fmt.Print(cli.FormatOperationResult(result, cli.OutputFormat(globalConfig.OutputFormat)))
 		return nil
 	},
 }
 
-var configCmd = &cobra.Command{
-	Use:   "config",
-	Short: "Show current configuration",
-	Long:  `Display the current configuration settings, including backend and output format.`,
-	Example: `  objstore config                                # Show current config
-  objstore config -o json                        # Show config as JSON
-  objstore --backend s3 --backend-bucket mybucket config  # Preview config`,
-	Args: cobra.NoArgs,
-	RunE: func(cmd *cobra.Command, args []string) error {
-		// For config command, we don't need to create storage backend
-		// Just display the configuration
-		fmt.Print(cli.DisplayConfig(globalConfig, globalConfig.OutputFormat))
-		return nil
-	},
+// Deletion approval command group
+var deletionsCmd = &cobra.Command{
+	Use:   "deletions",
+	Short: "Review deletions of protected objects",
+	Long: `Review deletions of objects under protected prefixes.
+
+When the server protects a prefix, deleting an object under it creates a
+pending deletion request instead. The object is kept until a different
+principal approves the request (two-person rule). Requires --server with the
+REST protocol.`,
+	Example: `  objstore --server http://localhost:8080 deletions list
+  objstore --server http://localhost:8080 deletions approve <id>
+  objstore --server http://localhost:8080 deletions reject <id> --reason "retention period not over"`,
 }
 
-var archiveCmd = &cobra.Command{
-	Use:   "archive <key> <destination-backend>",
-	Short: "Archive an object to archival storage",
-	Long: `Archive an object to archival storage (local, glacier, azurearchive).
-This copies the object to long-term archival storage.
-
-For local archiver, use --destination-path to specify the archive directory.
-This allows archiving to a different mount point (e.g., NFS backup server).`,
-	Example: `  objstore archive logs/old.log local --destination-path /mnt/backup        # Archive to local backup mount
-  objstore archive data.zip local --destination-path /mnt/nfs/backups       # Archive to NFS mount
-  objstore archive logs/old.log glacier                                     # Archive to AWS Glacier
-  objstore archive backups/2023.tar azurearchive                            # Archive to Azure Archive
-  objstore --backend s3 archive old-data.zip glacier                        # From S3 to Glacier`,
-	Args: cobra.ExactArgs(2),
+var deletionsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List deletion requests",
+	Long:  `List deletion requests, oldest first. Only pending requests are shown unless --status is set.`,
+	Example: `  objstore deletions list                        # Pending requests
+  objstore deletions list --status all           # Every request
+  objstore deletions list -o table               # List as table`,
+	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		key := args[0]
-		destinationBackend := args[1]
-
-		// Get destination-specific settings from flags
-		destinationSettings := make(map[string]string)
-
-		if destPath, _ := cmd.Flags().GetString("destination-path"); destPath != "" { //nolint:errcheck // Optional flag, error can be safely ignored
-			destinationSettings["path"] = destPath
-		}
-		if destBucket, _ := cmd.Flags().GetString("destination-bucket"); destBucket != "" { //nolint:errcheck // Optional flag, error can be safely ignored
-			destinationSettings["bucket"] = destBucket
-		}
-		if destRegion, _ := cmd.Flags().GetString("destination-region"); destRegion != "" { //nolint:errcheck // Optional flag, error can be safely ignored
-			destinationSettings["region"] = destRegion
-		}
-		if destKey, _ := cmd.Flags().GetString("destination-key"); destKey != "" { //nolint:errcheck // Optional flag, error can be safely ignored
-			destinationSettings["access_key_id"] = destKey
-		}
-		if destSecret, _ := cmd.Flags().GetString("destination-secret"); destSecret != "" { //nolint:errcheck // Optional flag, error can be safely ignored
-			destinationSettings["secret_access_key"] = destSecret
-		}
-		if destURL, _ := cmd.Flags().GetString("destination-url"); destURL != "" { //nolint:errcheck // Optional flag, error can be safely ignored
-			destinationSettings["endpoint"] = destURL
+		status, _ := cmd.Flags().GetString("status")
+		if status == "all" {
+			status = ""
 		}
 
 		ctx, err := cli.NewCommandContext(globalConfig)
@@ -353,55 +2051,24 @@ This allows archiving to a different mount point (e.g., NFS backup server).`,
 		}
 		defer func() { _ = ctx.Close() }()
 
-		if err := ctx.ArchiveCommandWithSettings(key, destinationBackend, destinationSettings); err != nil {
+		requests, err := ctx.ListDeletionRequestsCommand(status)
+		if err != nil {
 			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
 			return err
 		}
 
-		result := &cli.OperationResult{
-			Success: true,
-			Message: fmt.Sprintf("Successfully archived '%s' to %s", key, destinationBackend),
-		}
-		fmt.Print(cli.FormatOperationResult(result, cli.OutputFormat(globalConfig.OutputFormat)))
+		fmt.Print(cli.FormatDeletionRequestsResult(requests, cli.OutputFormat(globalConfig.OutputFormat)))
 		return nil
 	},
 }
 
-// Policy command group
-var policyCmd = &cobra.Command{
-	Use:   "policy",
-	Short: "Manage lifecycle policies",
-	Long: `Manage lifecycle policies for automatic object lifecycle management.
-
-Lifecycle policies allow you to automatically delete or archive objects after a specified retention period.`,
-	Example: `  objstore policy add cleanup-old-logs logs/ 30 delete    # Delete logs after 30 days
-  objstore policy add archive-backups backups/ 90 archive # Archive backups after 90 days
-  objstore policy list                                     # List all policies
-  objstore policy remove cleanup-old-logs                  # Remove a policy`,
-}
-
-var policyAddCmd = &cobra.Command{
-	Use:   "add <id> <prefix> <retention-days> <action>",
-	Short: "Add a lifecycle policy",
-	Long: `Add a lifecycle policy to automatically manage objects.
-
-The policy will apply to all objects matching the specified prefix.
-After the retention period (in days), objects will be either deleted or archived.
-
-Actions:
-  delete  - Permanently delete objects after retention period
-  archive - Move objects to archival storage after retention period`,
-	Example: `  objstore policy add cleanup-old-logs logs/ 30 delete           # Delete logs after 30 days
-  objstore policy add archive-reports reports/ 365 archive       # Archive reports after 1 year
-  objstore policy add temp-cleanup temp/ 1 delete                # Delete temp files after 1 day
-  objstore policy add monthly-archive data/monthly/ 90 archive   # Archive monthly data after 90 days`,
-	Args: cobra.ExactArgs(4),
+var deletionsApproveCmd = &cobra.Command{
+	Use:     "approve <id>",
+	Short:   "Approve a deletion request",
+	Long:    `Approve a pending deletion request and delete the object. You cannot approve a request you made.`,
+	Example: `  objstore deletions approve 4c1d2f0e-8a8b-4d7e-9d55-2f5b0c6f1a3e`,
+	Args:    cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		id := args[0]
-		prefix := args[1]
-		retentionDays := args[2]
-		action := args[3]
-
 		ctx, err := cli.NewCommandContext(globalConfig)
 		if err != nil {
 			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
@@ -409,31 +2076,29 @@ Actions:
 		}
 		defer func() { _ = ctx.Close() }()
 
-		if err := ctx.AddPolicyCommand(id, prefix, retentionDays, action); err != nil {
+		req, err := ctx.ApproveDeletionCommand(args[0])
+		if err != nil {
 			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
 			return err
 		}
 
 		result := &cli.OperationResult{
 			Success: true,
-			Message: fmt.Sprintf("Successfully added policy '%s'", id),
+			Message: fmt.Sprintf("Approved deletion request '%s'; deleted '%s'", req.ID, req.Key),
 		}
 		fmt.Print(cli.FormatOperationResult(result, cli.OutputFormat(globalConfig.OutputFormat)))
 		return nil
 	},
 }
 
-var policyRemoveCmd = &cobra.Command{
-	Use:   "remove <id>",
-	Short: "Remove a lifecycle policy",
-	Long: `Remove a lifecycle policy by ID.
-
-This stops the policy from being applied to new objects. Existing objects are not affected.`,
-	Example: `  objstore policy remove cleanup-old-logs        # Remove policy by ID
-  objstore policy remove temp-cleanup            # Remove temp cleanup policy`,
-	Args: cobra.ExactArgs(1),
+var deletionsRejectCmd = &cobra.Command{
+	Use:     "reject <id>",
+	Short:   "Reject a deletion request",
+	Long:    `Reject a pending deletion request. The object is kept.`,
+	Example: `  objstore deletions reject 4c1d2f0e-8a8b-4d7e-9d55-2f5b0c6f1a3e --reason "retention period not over"`,
+	Args:    cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		id := args[0]
+		reason, _ := cmd.Flags().GetString("reason")
 
 		ctx, err := cli.NewCommandContext(globalConfig)
 		if err != nil {
@@ -442,31 +2107,42 @@ This stops the policy from being applied to new objects. Existing objects are no
 		}
 		defer func() { _ = ctx.Close() }()
 
-		if err := ctx.RemovePolicyCommand(id); err != nil {
+		req, err := ctx.RejectDeletionCommand(args[0], reason)
+		if err != nil {
 			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
 			return err
 		}
 
 		result := &cli.OperationResult{
 			Success: true,
-			Message: fmt.Sprintf("Successfully removed policy '%s'", id),
+			Message: fmt.Sprintf("Rejected deletion request '%s'; kept '%s'", req.ID, req.Key),
 		}
 		fmt.Print(cli.FormatOperationResult(result, cli.OutputFormat(globalConfig.OutputFormat)))
 		return nil
 	},
 }
 
-var policyListCmd = &cobra.Command{
-	Use:   "list",
-	Short: "List all lifecycle policies",
-	Long: `List all configured lifecycle policies.
+// Legal hold command group
+var holdCmd = &cobra.Command{
+	Use:   "hold",
+	Short: "Manage legal holds",
+	Long: `Manage legal holds. An object under legal hold cannot be deleted or
+overwritten until the hold is released. Requires --server with the REST
+protocol.`,
+	Example: `  objstore --server http://localhost:8080 hold place records/2025/ledger.csv --reason "litigation 2025-17"
+  objstore --server http://localhost:8080 hold list
+  objstore --server http://localhost:8080 hold release records/2025/ledger.csv`,
+}
 
-Shows all active policies with their IDs, prefixes, retention periods, and actions.`,
-	Example: `  objstore policy list                           # List all policies
-  objstore policy list -o json                   # List policies as JSON
-  objstore policy list -o table                  # List policies in table format`,
-	Args: cobra.NoArgs,
+var holdPlaceCmd = &cobra.Command{
+	Use:     "place <key>",
+	Short:   "Place a legal hold on an object",
+	Long:    `Place a legal hold on an existing object.`,
+	Example: `  objstore hold place records/2025/ledger.csv --reason "litigation 2025-17"`,
+	Args:    cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		reason, _ := cmd.Flags().GetString("reason")
+
 		ctx, err := cli.NewCommandContext(globalConfig)
 		if err != nil {
 			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
@@ -474,29 +2150,26 @@ Shows all active policies with their IDs, prefixes, retention periods, and actio
 		}
 		defer func() { _ = ctx.Close() }()
 
-		policies, err := ctx.ListPoliciesCommand()
-		if err != nil {
+		if _, err := ctx.PlaceLegalHoldCommand(args[0], reason); err != nil {
 			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
 			return err
 		}
 
-		fmt.Print(cli.FormatPoliciesResult(policies, cli.OutputFormat(globalConfig.OutputFormat)))
+		result := &cli.OperationResult{
+			Success: true,
+			Message: fmt.Sprintf("Placed legal hold on '%s'", args[0]),
+		}
+		fmt.Print(cli.FormatOperationResult(result, cli.OutputFormat(globalConfig.OutputFormat)))
 		return nil
 	},
 }
 
-var policyApplyCmd = &cobra.Command{
-	Use:   "apply",
-	Short: "Execute all lifecycle policies",
-	Long: `Execute all configured lifecycle policies now.
-
-This scans all objects and applies deletion or archival actions based on configured retention periods.
-Use this command in cron jobs for scheduled policy execution.`,
-	Example: `  objstore policy apply                          # Apply all policies
-  objstore policy apply --server http://localhost:8080  # Apply policies on remote server
-  # Cron job example (daily at 2 AM):
-  # 0 2 * * * /usr/local/bin/objstore policy apply`,
-	Args: cobra.NoArgs,
+var holdReleaseCmd = &cobra.Command{
+	Use:     "release <key>",
+	Short:   "Release a legal hold",
+	Long:    `Release the legal hold on an object.`,
+	Example: `  objstore hold release records/2025/ledger.csv`,
+	Args:    cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx, err := cli.NewCommandContext(globalConfig)
 		if err != nil {
@@ -505,29 +2178,26 @@ Use this command in cron jobs for scheduled policy execution.`,
 		}
 		defer func() { _ = ctx.Close() }()
 
-		if err := ctx.ApplyPoliciesCommand(); err != nil {
+		if err := ctx.ReleaseLegalHoldCommand(args[0]); err != nil {
 			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
 			return err
 		}
 
 		result := &cli.OperationResult{
 			Success: true,
-			Message: "Successfully applied all lifecycle policies",
+			Message: fmt.Sprintf("Released legal hold on '%s'", args[0]),
 		}
 		fmt.Print(cli.FormatOperationResult(result, cli.OutputFormat(globalConfig.OutputFormat)))
 		return nil
 	},
 }
 
-var healthCmd = &cobra.Command{
-	Use:   "health",
-	Short: "Check health status",
-	Long: `Check the health status of the object storage backend.
-
-Returns the backend status, version, and configuration information.`,
-	Example: `  objstore health                                # Check health status
-  objstore health -o json                        # Get health status as JSON
-  objstore --backend s3 health                   # Check S3 backend health`,
+var holdListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List legal holds",
+	Long:  `List the objects under legal hold.`,
+	Example: `  objstore hold list                             # List holds
+  objstore hold list -o json                     # List as JSON`,
 	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx, err := cli.NewCommandContext(globalConfig)
@@ -537,13 +2207,13 @@ Returns the backend status, version, and configuration information.`,
 		}
 		defer func() { _ = ctx.Close() }()
 
-		health, err := ctx.HealthCommand()
+		holds, err := ctx.ListLegalHoldsCommand()
 		if err != nil {
 			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
 			return err
 		}
 
-		fmt.Print(cli.FormatHealthResult(health, cli.OutputFormat(globalConfig.OutputFormat)))
+		fmt.Print(cli.FormatLegalHoldsResult(holds, cli.OutputFormat(globalConfig.OutputFormat)))
 		return nil
 	},
 }
@@ -568,10 +2238,18 @@ var replicationAddCmd = &cobra.Command{
 	Long: `Add a replication policy to automatically replicate objects between backends.
 
 Source and destination backends can be: local, s3, minio, gcs, azure.
-Use --source-* and --dest-* flags to configure backend-specific settings.`,
+Use --source-* and --dest-* flags to configure backend-specific settings.
+
+With --write-once the destination only ever gains versions: each source
+version is copied to "<key>@<timestamp>-<etag>", existing copies are never
+overwritten and deletes are not propagated. Combined with S3 Object Lock on
+the destination this keeps an immutable copy that survives ransomware on
+the source.`,
 	Example: `  objstore replication add backup-to-s3 local s3 --dest-bucket my-bucket --interval 1h
   objstore replication add mirror minio s3 --source-bucket src --dest-bucket dst
-  objstore replication add logs-archive local glacier --prefix logs/ --interval 24h`,
+  objstore replication add logs-archive local glacier --prefix logs/ --interval 24h
+  objstore replication add vault local s3 --dest-bucket vault --write-once \
+    --dest-object-lock-mode COMPLIANCE --dest-object-lock-days 90`,
 	Args: cobra.ExactArgs(3),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		id := args[0]
@@ -600,6 +2278,12 @@ Use --source-* and --dest-* flags to configure backend-specific settings.`,
 		if v, _ := cmd.Flags().GetString("dest-path"); v != "" { //nolint:errcheck
 			destSettings["path"] = v
 		}
+		if v, _ := cmd.Flags().GetString("dest-object-lock-mode"); v != "" { //nolint:errcheck
+			destSettings["objectLockMode"] = v
+		}
+		if v, _ := cmd.Flags().GetString("dest-object-lock-days"); v != "" { //nolint:errcheck
+			destSettings["objectLockDays"] = v
+		}
 
 		prefix, _ := cmd.Flags().GetString("prefix")          //nolint:errcheck
 		intervalStr, _ := cmd.Flags().GetString("interval")   //nolint:errcheck
@@ -607,6 +2291,7 @@ Use --source-* and --dest-* flags to configure backend-specific settings.`,
 		backendKey, _ := cmd.Flags().GetString("backend-key") //nolint:errcheck
 		sourceDEK, _ := cmd.Flags().GetString("source-dek")   //nolint:errcheck
 		destDEK, _ := cmd.Flags().GetString("dest-dek")       //nolint:errcheck
+		writeOnce, _ := cmd.Flags().GetBool("write-once")     //nolint:errcheck
 
 		// Parse interval
 		interval, err := time.ParseDuration(intervalStr)
@@ -626,6 +2311,7 @@ Use --source-* and --dest-* flags to configure backend-specific settings.`,
 			sourceSettings, destSettings,
 			prefix, interval, mode,
 			backendKey, sourceDEK, destDEK,
+			writeOnce,
 		); err != nil {
 			fmt.Fprintln(os.Stderr, cli.FormatError(err, cli.OutputFormat(globalConfig.OutputFormat)))
 			return err
@@ -831,6 +2517,14 @@ Use "{{.CommandPath}} [command] --help" for more information about a command.{{e
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.objstore.yaml)")
 	rootCmd.PersistentFlags().String("server", "", "server URL for remote operations (e.g., http://localhost:8080)")
 	rootCmd.PersistentFlags().String("server-protocol", "rest", "server protocol: rest, grpc, or quic")
+	rootCmd.PersistentFlags().String("sigv4-access-key", "", "access key ID for signing REST/QUIC requests with AWS SigV4")
+	rootCmd.PersistentFlags().String("sigv4-secret-key", "", "secret access key for signing REST/QUIC requests with AWS SigV4")
+	rootCmd.PersistentFlags().String("sigv4-region", "us-east-1", "region in the SigV4 credential scope")
+	rootCmd.PersistentFlags().Duration("timeout", 30*time.Second, "time to wait for the server to respond to a request; object transfers are not bounded (negative disables)")
+	rootCmd.PersistentFlags().Int("retries", 2, "times a server request that failed transiently is retried (0 disables)")
+	rootCmd.PersistentFlags().Duration("retry-backoff", 200*time.Millisecond, "delay before the first retry, doubled for each further retry")
+	rootCmd.PersistentFlags().String("ca-cert", "", "PEM file of CA certificates to trust for the server in addition to the system roots")
+	rootCmd.PersistentFlags().Bool("insecure-skip-verify", false, "skip verification of the server certificate (development only)")
 	rootCmd.PersistentFlags().String("backend", "local", "storage backend (local, s3, minio, gcs, azure)")
 	rootCmd.PersistentFlags().String("backend-path", "./storage", "path for local backend")
 	rootCmd.PersistentFlags().String("backend-bucket", "", "bucket name for cloud backends")
@@ -839,14 +2533,83 @@ Use "{{.CommandPath}} [command] --help" for more information about a command.{{e
 	rootCmd.PersistentFlags().String("backend-secret", "", "secret key for cloud backends")
 	rootCmd.PersistentFlags().String("backend-url", "", "custom endpoint URL for cloud backends")
 	rootCmd.PersistentFlags().StringP("output-format", "o", "text", "output format (text, json, table)")
+	rootCmd.PersistentFlags().Bool("dedup", false, "store objects through the content-addressable dedup layer (local mode)")
+	rootCmd.PersistentFlags().String("search-index", "", "file to persist the search index to; writes keep it up to date (local mode)")
+	rootCmd.PersistentFlags().String("e2e-keychain", "", "keychain file to encrypt objects with end to end; the server only stores ciphertext")
+	rootCmd.PersistentFlags().String("e2e-recovery-key", "", "escrow recovery key file to read objects whose keychain key is lost")
+	rootCmd.PersistentFlags().String("sign-key", "", "ed25519 private key file to sign written objects with")
+	rootCmd.PersistentFlags().StringSlice("verify-keys", nil, "ed25519 public key files trusted to sign objects; reads of other objects fail")
+	rootCmd.PersistentFlags().StringSlice("e2e-deterministic-fields", nil, "custom metadata fields to encrypt deterministically so the server can match them exactly (requires --e2e-accept-equality-leak)")
+	rootCmd.PersistentFlags().Bool("e2e-accept-equality-leak", false, "accept that deterministic fields reveal which objects share a value, value frequencies and lengths")
 
 	// get command flags
 	getCmd.Flags().Bool("metadata", false, "retrieve only metadata (not file content)")
+	getCmd.Flags().Int("download-part-size", 8, "part size in MiB for parallel downloads of large objects")
+	getCmd.Flags().Int("download-concurrency", 4, "number of parts downloaded in parallel (1 disables parallel downloads)")
+
+	// search command flags
+	listCmd.Flags().String("sort", "", "sort objects by name, size or mtime")
+	listCmd.Flags().BoolP("reverse", "r", false, "reverse the sort order")
+	listCmd.Flags().String("fields", "", "comma-separated fields to show (key, size, modified, storage_class, content_type, etag, checksum, encryption_key_id)")
+	listCmd.Flags().BoolP("long", "l", false, "show storage class, size, modification time, checksum and encryption key ID")
+	listCmd.Flags().Bool("human-readable", false, "show sizes in KiB, MiB, ... with --long or --fields")
+	listCmd.Flags().Bool("all", false, "follow pagination until every object under the prefix is listed")
+	listCmd.Flags().String("output-file", "", "stream the listing to this file as JSON lines, or CSV for a .csv file")
+	listCmd.Flags().Int("page-size", 0, "objects to request per page (backend default if 0)")
+
+	lsCmd.Flags().Bool("totals", false, "count the objects and bytes under each folder")
+	lsCmd.Flags().Bool("human-readable", false, "show sizes in KiB, MiB, ...")
+
+	searchCmd.Flags().Int("limit", 100, "maximum number of results")
+	searchCmd.Flags().Bool("rebuild", false, "rebuild the search index from a full listing first")
+
+	dupesCmd.Flags().Bool("hash", false, "download and hash objects that have no recorded checksum or MD5 ETag")
+
+	applyCmd.Flags().StringP("file", "f", "", "YAML or JSON file declaring the desired configuration")
+	applyCmd.Flags().Bool("dry-run", false, "show the changes without making them")
+
+	analyzeCmd.Flags().Int("depth", 1, "key segments below the prefix to group objects by (negative for totals only)")
+	analyzeCmd.Flags().String("buckets", "", "comma-separated bucket bounds such as 7d,30d,90d (default 1d,7d,30d,90d,180d,365d)")
+	analyzeCmd.Flags().String("access-file", "", "access state file of a server started with --access-tracking (local mode)")
+
+	// keychain command flags
+	keychainGenerateCmd.Flags().Bool("default", false, "make the new key the default for new objects")
+	keychainEscrowCmd.Flags().Bool("disable", false, "turn escrow mode off")
+
+	// proxy command flags
+	proxyCmd.Flags().String("listen", "127.0.0.1:8081", "address the proxy listens on")
+	proxyCmd.Flags().String("cache-dir", ".objstore-cache", "directory for cached objects")
+	proxyCmd.Flags().String("cache-size", "10GiB", "maximum cache size (e.g. 512MiB, 50GB)")
+	proxyCmd.Flags().Duration("revalidate-after", 0, "serve cached objects without revalidating for this long (0 revalidates every request)")
 
 	// put command flags for metadata
 	putCmd.Flags().String("content-type", "", "content type for the object")
 	putCmd.Flags().String("content-encoding", "", "content encoding for the object")
 	putCmd.Flags().StringToString("custom", map[string]string{}, "custom metadata fields (key=value pairs)")
+	putCmd.Flags().String("storage-class", "", "storage class or access tier for the object (backend default if empty)")
+	putCmd.Flags().String("expires-at", "", "RFC 3339 time after which lifecycle runs delete the object")
+	putCmd.Flags().Duration("expires-in", 0, "delete the object this long after the upload (alternative to --expires-at)")
+	putCmd.Flags().Bool("detect-content-type", true, "detect the content type from the key's extension or the data when --content-type is not given")
+	putCmd.Flags().Bool("atomic", false, "stage the upload and replace the object only once it is complete and verified")
+	policyAddCmd.Flags().String("storage-class", "", "target storage class for the transition action")
+	policySimulateCmd.Flags().String("policies", "", "YAML or JSON file of proposed policies (default: the current policies)")
+	policySimulateCmd.Flags().String("horizon", "365d", "how far past the inventory to project, such as 90d or 720h")
+	policySimulateCmd.Flags().String("as-of", "", "RFC 3339 time the inventory was taken (default: its newest object)")
+
+	mvCmd.Flags().BoolP("recursive", "r", false, "move every object under the source prefix to the destination prefix")
+	mvCmd.Flags().Bool("dry-run", false, "with --recursive, report what would be moved without moving anything")
+	mvCmd.Flags().Bool("overwrite", false, "replace objects that already exist at the destination")
+	mvCmd.Flags().Int("concurrency", common.DefaultRenameConcurrency, "with --recursive, number of objects moved in parallel")
+
+	metaSetCmd.Flags().String("prefix", "", "apply the change to every object whose key starts with this prefix")
+	metaSetCmd.Flags().String("content-type", "", "new content type")
+	metaSetCmd.Flags().String("content-encoding", "", "new content encoding")
+	metaSetCmd.Flags().String("storage-class", "", "new storage class or access tier")
+	metaSetCmd.Flags().StringSlice("unset", nil, "custom metadata fields to remove")
+	metaCmd.AddCommand(metaSetCmd)
+
+	diffCmd.Flags().String("mode", "", "comparison: text, json or binary (default: detected from the content)")
+	diffCmd.Flags().Int("context", 0, "lines of context around text changes (default: 3)")
 
 	// archive command flags for destination settings
 	archiveCmd.Flags().String("destination-path", "", "path for local archiver (e.g., /mnt/backup)")
@@ -861,6 +2624,7 @@ Use "{{.CommandPath}} [command] --help" for more information about a command.{{e
 	policyCmd.AddCommand(policyListCmd)
 	policyCmd.AddCommand(policyRemoveCmd)
 	policyCmd.AddCommand(policyApplyCmd)
+	policyCmd.AddCommand(policySimulateCmd)
 
 	// Replication add command flags
 	replicationAddCmd.Flags().String("source-bucket", "", "source bucket name")
@@ -875,6 +2639,70 @@ Use "{{.CommandPath}} [command] --help" for more information about a command.{{e
 	replicationAddCmd.Flags().String("backend-key", "", "encryption key for backend storage")
 	replicationAddCmd.Flags().String("source-dek", "", "data encryption key for source")
 	replicationAddCmd.Flags().String("dest-dek", "", "data encryption key for destination")
+	replicationAddCmd.Flags().Bool("write-once", false, "keep every source version on the destination and never propagate deletes")
+	replicationAddCmd.Flags().String("dest-object-lock-mode", "", "S3 Object Lock mode for the destination: GOVERNANCE or COMPLIANCE")
+	replicationAddCmd.Flags().String("dest-object-lock-days", "", "S3 Object Lock retention in days for the destination")
+
+	// Deletion approval and legal hold flags and subcommands
+	deletionsListCmd.Flags().String("status", "pending", "only list requests with this status: pending, approved, rejected, or all")
+	deletionsRejectCmd.Flags().String("reason", "", "reason recorded with the rejection")
+	holdPlaceCmd.Flags().String("reason", "", "reason recorded with the hold")
+	deletionsCmd.AddCommand(deletionsListCmd)
+	deletionsCmd.AddCommand(deletionsApproveCmd)
+	deletionsCmd.AddCommand(deletionsRejectCmd)
+	holdCmd.AddCommand(holdPlaceCmd)
+	holdCmd.AddCommand(holdReleaseCmd)
+	holdCmd.AddCommand(holdListCmd)
+
+	// Audit subcommands
+	auditVerifyCmd.Flags().String("anchor-dir", "", "directory of checkpoints anchored by objstore-server --audit-anchor-dir")
+	auditCmd.AddCommand(auditVerifyCmd)
+
+	// Add dedup subcommands
+	dedupCmd.AddCommand(dedupStatsCmd)
+	keychainCmd.AddCommand(keychainGenerateCmd)
+	keychainCmd.AddCommand(keychainListCmd)
+	keychainCmd.AddCommand(keychainEscrowCmd)
+	keychainCmd.AddCommand(keychainRecoveryKeyCmd)
+
+	signingCmd.AddCommand(signingGenerateKeyCmd)
+
+	// Admin subcommands
+	adminBackupCmd.Flags().String("key", "", "store the archive as this object in the configured backend")
+	adminBackupCmd.Flags().String("state-dir", "", "data directory holding the state files (default: --backend-path for the local backend)")
+	adminBackupCmd.Flags().StringSlice("config-file", nil, "configuration file to include (repeatable)")
+	adminRestoreCmd.Flags().String("key", "", "read the archive from this object in the configured backend")
+	adminRestoreCmd.Flags().String("state-dir", "", "directory to restore state files to (default: their original directory)")
+	adminRestoreCmd.Flags().String("config-dir", "", "directory to restore configuration files to (default: their original paths)")
+	adminCmd.AddCommand(adminBackupCmd)
+	adminCmd.AddCommand(adminRestoreCmd)
+
+	// Cost subcommands
+	costReportCmd.Flags().String("month", "", "month to report as YYYY-MM (default: current month)")
+	costReportCmd.Flags().String("cost-config", "", "cost configuration whose state file is priced in local mode")
+	costCmd.AddCommand(costReportCmd)
+
+	// Jobs subcommands
+	jobsCmd.AddCommand(jobsListCmd)
+	jobsCmd.AddCommand(jobsShowCmd)
+
+	// Tasks subcommands
+	tasksListCmd.Flags().String("state", "", "only list tasks in this state: pending, running, succeeded, or dead")
+	tasksCmd.AddCommand(tasksListCmd)
+	tasksCmd.AddCommand(tasksRetryCmd)
+
+	// Share link subcommands
+	shareCreateCmd.Flags().Duration("ttl", share.DefaultTTL, "how long the link stays valid")
+	shareCreateCmd.Flags().Int("max-downloads", 0, "downloads allowed through the link (0 for no limit)")
+	shareCreateCmd.Flags().String("password", "", "password downloads must present")
+	shareCreateCmd.Flags().String("bandwidth", "", "bandwidth cap per second for downloads, such as 1MiB (default: none)")
+	shareCmd.AddCommand(shareCreateCmd)
+	shareCmd.AddCommand(shareListCmd)
+	shareCmd.AddCommand(shareRevokeCmd)
+	aliasCmd.AddCommand(aliasSetCmd)
+	aliasCmd.AddCommand(aliasGetCmd)
+	aliasCmd.AddCommand(aliasListCmd)
+	aliasCmd.AddCommand(aliasRmCmd)
 
 	// Add replication subcommands
 	replicationCmd.AddCommand(replicationAddCmd)
@@ -888,13 +2716,36 @@ Use "{{.CommandPath}} [command] --help" for more information about a command.{{e
 	rootCmd.AddCommand(putCmd)
 	rootCmd.AddCommand(getCmd)
 	rootCmd.AddCommand(deleteCmd)
+	rootCmd.AddCommand(mvCmd)
 	rootCmd.AddCommand(listCmd)
+	rootCmd.AddCommand(lsCmd)
+	rootCmd.AddCommand(searchCmd)
 	rootCmd.AddCommand(existsCmd)
+	rootCmd.AddCommand(metaCmd)
+	rootCmd.AddCommand(diffCmd)
 	rootCmd.AddCommand(configCmd)
 	rootCmd.AddCommand(archiveCmd)
 	rootCmd.AddCommand(policyCmd)
 	rootCmd.AddCommand(replicationCmd)
+	rootCmd.AddCommand(dedupCmd)
+	rootCmd.AddCommand(dupesCmd)
+	rootCmd.AddCommand(analyzeCmd)
+	rootCmd.AddCommand(applyCmd)
+	rootCmd.AddCommand(keychainCmd)
+	rootCmd.AddCommand(recoverCmd)
+	rootCmd.AddCommand(signingCmd)
+	rootCmd.AddCommand(verifyCmd)
+	rootCmd.AddCommand(adminCmd)
+	rootCmd.AddCommand(costCmd)
+	rootCmd.AddCommand(jobsCmd)
+	rootCmd.AddCommand(tasksCmd)
+	rootCmd.AddCommand(shareCmd)
+	rootCmd.AddCommand(aliasCmd)
+	rootCmd.AddCommand(deletionsCmd)
+	rootCmd.AddCommand(holdCmd)
+	rootCmd.AddCommand(auditCmd)
 	rootCmd.AddCommand(healthCmd)
+	rootCmd.AddCommand(proxyCmd)
 
 	// Apply usage template to all commands to ensure examples always show
 	for _, cmd := range rootCmd.Commands() {
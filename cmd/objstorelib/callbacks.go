@@ -0,0 +1,75 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package main
+
+// #include <stdint.h>
+// #include <stdlib.h>
+//
+// typedef int (*ObjstoreListCallback)(void *userData, char *key, int64_t size, int64_t lastModified, char *etag);
+// typedef int (*ObjstorePolicyCallback)(void *userData, char *id, char *prefix, int64_t retentionSeconds, char *action, char *storageClass);
+//
+// static int objstoreCallList(ObjstoreListCallback cb, void *userData, char *key, int64_t size, int64_t lastModified, char *etag) {
+// 	return cb(userData, key, size, lastModified, etag);
+// }
+//
+// static int objstoreCallPolicy(ObjstorePolicyCallback cb, void *userData, char *id, char *prefix, int64_t retentionSeconds, char *action, char *storageClass) {
+// 	return cb(userData, id, prefix, retentionSeconds, action, storageClass);
+// }
+import "C"
+import (
+	"time"
+	"unsafe"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// Go cannot call C function pointers directly, so callbacks are invoked
+// through the static helpers above. Files with //export directives may only
+// declare C functions in their preamble, which is why these live here.
+
+// callList passes one listed object to cb and reports whether to continue.
+func callList(cb C.ObjstoreListCallback, userData unsafe.Pointer, obj *common.ObjectInfo) bool {
+	key := C.CString(obj.Key)
+	defer C.free(unsafe.Pointer(key))
+
+	var size, modified int64
+	var etag string
+	if obj.Metadata != nil {
+		size = obj.Metadata.Size
+		etag = obj.Metadata.ETag
+		if !obj.Metadata.LastModified.IsZero() {
+			modified = obj.Metadata.LastModified.Unix()
+		}
+	}
+	cEtag := C.CString(etag)
+	defer C.free(unsafe.Pointer(cEtag))
+
+	return C.objstoreCallList(cb, userData, key, C.int64_t(size), C.int64_t(modified), cEtag) == 0
+}
+
+// callPolicy passes one lifecycle policy to cb and reports whether to
+// continue.
+func callPolicy(cb C.ObjstorePolicyCallback, userData unsafe.Pointer, policy common.LifecyclePolicy) bool {
+	id := C.CString(policy.ID)
+	defer C.free(unsafe.Pointer(id))
+	prefix := C.CString(policy.Prefix)
+	defer C.free(unsafe.Pointer(prefix))
+	action := C.CString(policy.Action)
+	defer C.free(unsafe.Pointer(action))
+	class := C.CString(policy.StorageClass)
+	defer C.free(unsafe.Pointer(class))
+
+	retention := int64(policy.Retention / time.Second)
+	return C.objstoreCallPolicy(cb, userData, id, prefix, C.int64_t(retention), action, class) == 0
+}
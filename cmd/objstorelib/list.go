@@ -0,0 +1,73 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package main
+
+// #include <stdint.h>
+//
+// // ObjstoreListCallback receives one object per call. lastModified is in
+// // Unix seconds, or 0 when unknown. The strings are only valid for the
+// // duration of the call. Return 0 to continue listing, non-zero to stop.
+// typedef int (*ObjstoreListCallback)(void *userData, char *key, int64_t size, int64_t lastModified, char *etag);
+import "C"
+import (
+	"context"
+	"unsafe"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// listPageSize is the number of objects requested per backend call.
+const listPageSize = 1000
+
+//export ObjstoreList
+func ObjstoreList(handle C.int, prefix *C.char, callback C.ObjstoreListCallback, userData unsafe.Pointer) C.int {
+	storage, err := getCommonStorage(int(handle))
+	if err != nil {
+		setLastError(err)
+		return -1
+	}
+	if callback == nil {
+		setLastError(common.ErrInvalidArgument)
+		return -1
+	}
+
+	ctx := context.Background()
+	opts := &common.ListOptions{MaxResults: listPageSize}
+	if prefix != nil {
+		opts.Prefix = C.GoString(prefix)
+	}
+
+	count := 0
+	for {
+		result, err := storage.ListWithOptions(ctx, opts)
+		if err != nil {
+			setLastError(err)
+			return -1
+		}
+		for _, obj := range result.Objects {
+			count++
+			if !callList(callback, userData, obj) {
+				setLastError(nil)
+				return C.int(count)
+			}
+		}
+		if !result.Truncated || result.NextToken == "" {
+			break
+		}
+		opts.ContinueFrom = result.NextToken
+	}
+
+	setLastError(nil)
+	return C.int(count)
+}
@@ -15,18 +15,28 @@ package main
 
 // #include <stdlib.h>
 // #include <string.h>
+//
+// // OBJSTORE_ABI_VERSION is incremented whenever an exported function or type
+// // changes incompatibly. Compare it with ObjstoreABIVersion() at startup.
+// #define OBJSTORE_ABI_VERSION 2
 import "C"
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"sync"
 	"unsafe"
 
+	"github.com/jeremyhahn/go-objstore/pkg/common"
 	"github.com/jeremyhahn/go-objstore/pkg/factory"
+	"github.com/jeremyhahn/go-objstore/pkg/version"
 )
 
+// abiVersion must match OBJSTORE_ABI_VERSION above.
+const abiVersion = 2
+
 // Errors for C API
 var (
 	// ErrInvalidHandle is returned when an invalid storage handle is provided
@@ -34,6 +44,12 @@ var (
 
 	// ErrBufferTooSmall is returned when the provided buffer is too small
 	ErrBufferTooSmall = errors.New("buffer too small")
+
+	// ErrInvalidStream is returned when an invalid stream handle is provided
+	ErrInvalidStream = errors.New("invalid stream handle")
+
+	// ErrWriterAborted is returned to a backend whose upload was aborted
+	ErrWriterAborted = errors.New("writer aborted")
 )
 
 // NewInvalidHandleError creates a new invalid handle error
@@ -75,6 +91,19 @@ func getStorage(handle int) (any, error) {
 	return storage, nil
 }
 
+// getCommonStorage retrieves a storage backend by handle ID
+func getCommonStorage(handle int) (common.Storage, error) {
+	storage, err := getStorage(handle)
+	if err != nil {
+		return nil, err
+	}
+	s, ok := storage.(common.Storage)
+	if !ok {
+		return nil, NewInvalidHandleError(handle)
+	}
+	return s, nil
+}
+
 // unregisterStorage removes a storage object from the registry
 func unregisterStorage(handle int) {
 	storageMutex.Lock()
@@ -95,7 +124,12 @@ func setLastError(err error) {
 
 //export ObjstoreVersion
 func ObjstoreVersion() *C.char {
-	return C.CString("go-objstore v0.1.0")
+	return C.CString("go-objstore v" + version.Version)
+}
+
+//export ObjstoreABIVersion
+func ObjstoreABIVersion() C.int {
+	return abiVersion
 }
 
 //export ObjstoreGetLastError
@@ -227,6 +261,27 @@ func ObjstoreDelete(handle C.int, key *C.char) C.int {
 	return 0
 }
 
+//export ObjstoreExists
+func ObjstoreExists(handle C.int, key *C.char) C.int {
+	storage, err := getCommonStorage(int(handle))
+	if err != nil {
+		setLastError(err)
+		return -1
+	}
+
+	exists, err := storage.Exists(context.Background(), C.GoString(key))
+	if err != nil {
+		setLastError(err)
+		return -1
+	}
+
+	setLastError(nil)
+	if exists {
+		return 1
+	}
+	return 0
+}
+
 //export ObjstoreClose
 func ObjstoreClose(handle C.int) {
 	unregisterStorage(int(handle))
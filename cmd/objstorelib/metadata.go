@@ -0,0 +1,160 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package main
+
+// #include <stdint.h>
+// #include <stdlib.h>
+//
+// // ObjstoreMetadata describes an object. Strings filled in by
+// // ObjstoreGetMetadata are owned by the caller and released with
+// // ObjstoreFreeMetadata. ObjstoreUpdateMetadata only reads contentType,
+// // contentEncoding and the custom pairs.
+// typedef struct {
+// 	int64_t size;
+// 	int64_t lastModified;
+// 	char *etag;
+// 	char *contentType;
+// 	char *contentEncoding;
+// 	char *storageClass;
+// 	int customCount;
+// 	char **customKeys;
+// 	char **customValues;
+// } ObjstoreMetadata;
+import "C"
+import (
+	"context"
+	"sort"
+	"unsafe"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+//export ObjstoreGetMetadata
+func ObjstoreGetMetadata(handle C.int, key *C.char, out *C.ObjstoreMetadata) C.int {
+	storage, err := getCommonStorage(int(handle))
+	if err != nil {
+		setLastError(err)
+		return -1
+	}
+	if out == nil {
+		setLastError(common.ErrInvalidArgument)
+		return -1
+	}
+
+	metadata, err := storage.GetMetadata(context.Background(), C.GoString(key))
+	if err != nil {
+		setLastError(err)
+		return -1
+	}
+	if metadata == nil {
+		metadata = &common.Metadata{}
+	}
+
+	*out = C.ObjstoreMetadata{
+		size:            C.int64_t(metadata.Size),
+		etag:            C.CString(metadata.ETag),
+		contentType:     C.CString(metadata.ContentType),
+		contentEncoding: C.CString(metadata.ContentEncoding),
+		storageClass:    C.CString(metadata.StorageClass),
+	}
+	if !metadata.LastModified.IsZero() {
+		out.lastModified = C.int64_t(metadata.LastModified.Unix())
+	}
+
+	if n := len(metadata.Custom); n > 0 {
+		names := make([]string, 0, n)
+		for name := range metadata.Custom {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		ptrSize := C.size_t(unsafe.Sizeof((*C.char)(nil)))
+		out.customCount = C.int(n)
+		out.customKeys = (**C.char)(C.malloc(C.size_t(n) * ptrSize))
+		out.customValues = (**C.char)(C.malloc(C.size_t(n) * ptrSize))
+		keys := unsafe.Slice(out.customKeys, n)
+		values := unsafe.Slice(out.customValues, n)
+		for i, name := range names {
+			keys[i] = C.CString(name)
+			values[i] = C.CString(metadata.Custom[name])
+		}
+	}
+
+	setLastError(nil)
+	return 0
+}
+
+//export ObjstoreUpdateMetadata
+func ObjstoreUpdateMetadata(handle C.int, key *C.char, metadata *C.ObjstoreMetadata) C.int {
+	storage, err := getCommonStorage(int(handle))
+	if err != nil {
+		setLastError(err)
+		return -1
+	}
+	if metadata == nil {
+		setLastError(common.ErrInvalidArgument)
+		return -1
+	}
+
+	update := &common.Metadata{
+		ContentType:     goStringOrEmpty(metadata.contentType),
+		ContentEncoding: goStringOrEmpty(metadata.contentEncoding),
+	}
+	if n := int(metadata.customCount); n > 0 {
+		keys := unsafe.Slice(metadata.customKeys, n)
+		values := unsafe.Slice(metadata.customValues, n)
+		update.Custom = make(map[string]string, n)
+		for i := range n {
+			update.Custom[C.GoString(keys[i])] = goStringOrEmpty(values[i])
+		}
+	}
+
+	if err := storage.UpdateMetadata(context.Background(), C.GoString(key), update); err != nil {
+		setLastError(err)
+		return -1
+	}
+
+	setLastError(nil)
+	return 0
+}
+
+//export ObjstoreFreeMetadata
+func ObjstoreFreeMetadata(metadata *C.ObjstoreMetadata) {
+	if metadata == nil {
+		return
+	}
+	C.free(unsafe.Pointer(metadata.etag))
+	C.free(unsafe.Pointer(metadata.contentType))
+	C.free(unsafe.Pointer(metadata.contentEncoding))
+	C.free(unsafe.Pointer(metadata.storageClass))
+	if n := int(metadata.customCount); n > 0 {
+		keys := unsafe.Slice(metadata.customKeys, n)
+		values := unsafe.Slice(metadata.customValues, n)
+		for i := range n {
+			C.free(unsafe.Pointer(keys[i]))
+			C.free(unsafe.Pointer(values[i]))
+		}
+	}
+	C.free(unsafe.Pointer(metadata.customKeys))
+	C.free(unsafe.Pointer(metadata.customValues))
+	*metadata = C.ObjstoreMetadata{}
+}
+
+// goStringOrEmpty converts a C string that may be NULL.
+func goStringOrEmpty(s *C.char) string {
+	if s == nil {
+		return ""
+	}
+	return C.GoString(s)
+}
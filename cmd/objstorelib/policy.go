@@ -0,0 +1,106 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package main
+
+// #include <stdint.h>
+//
+// // ObjstorePolicyCallback receives one lifecycle policy per call. The
+// // strings are only valid for the duration of the call. Return 0 to
+// // continue, non-zero to stop.
+// typedef int (*ObjstorePolicyCallback)(void *userData, char *id, char *prefix, int64_t retentionSeconds, char *action, char *storageClass);
+import "C"
+import (
+	"time"
+	"unsafe"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+//export ObjstoreAddPolicy
+func ObjstoreAddPolicy(handle C.int, id *C.char, prefix *C.char, retentionSeconds C.int64_t, action *C.char, destinationHandle C.int, storageClass *C.char) C.int {
+	storage, err := getCommonStorage(int(handle))
+	if err != nil {
+		setLastError(err)
+		return -1
+	}
+
+	policy := common.LifecyclePolicy{
+		ID:           C.GoString(id),
+		Prefix:       goStringOrEmpty(prefix),
+		Retention:    time.Duration(retentionSeconds) * time.Second,
+		Action:       C.GoString(action),
+		StorageClass: goStringOrEmpty(storageClass),
+	}
+	// Archive destinations are other storage handles; 0 means none.
+	if destinationHandle > 0 {
+		destination, err := getCommonStorage(int(destinationHandle))
+		if err != nil {
+			setLastError(err)
+			return -1
+		}
+		policy.Destination = destination
+	}
+
+	if err := storage.AddPolicy(policy); err != nil {
+		setLastError(err)
+		return -1
+	}
+	setLastError(nil)
+	return 0
+}
+
+//export ObjstoreRemovePolicy
+func ObjstoreRemovePolicy(handle C.int, id *C.char) C.int {
+	storage, err := getCommonStorage(int(handle))
+	if err != nil {
+		setLastError(err)
+		return -1
+	}
+
+	if err := storage.RemovePolicy(C.GoString(id)); err != nil {
+		setLastError(err)
+		return -1
+	}
+	setLastError(nil)
+	return 0
+}
+
+//export ObjstoreGetPolicies
+func ObjstoreGetPolicies(handle C.int, callback C.ObjstorePolicyCallback, userData unsafe.Pointer) C.int {
+	storage, err := getCommonStorage(int(handle))
+	if err != nil {
+		setLastError(err)
+		return -1
+	}
+	if callback == nil {
+		setLastError(common.ErrInvalidArgument)
+		return -1
+	}
+
+	policies, err := storage.GetPolicies()
+	if err != nil {
+		setLastError(err)
+		return -1
+	}
+
+	count := 0
+	for _, policy := range policies {
+		count++
+		if !callPolicy(callback, userData, policy) {
+			break
+		}
+	}
+	setLastError(nil)
+	return C.int(count)
+}
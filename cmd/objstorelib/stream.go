@@ -0,0 +1,229 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package main
+
+import "C"
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"unsafe"
+)
+
+// Stream handle registry for open readers and writers
+var (
+	streamRegistry = make(map[int]any)
+	streamCounter  = 0
+	streamMutex    sync.Mutex
+)
+
+// streamWriter uploads everything written to it with a single Put.
+type streamWriter struct {
+	pw   *io.PipeWriter
+	done chan error
+}
+
+// registerStream stores a reader or writer and returns a handle ID
+func registerStream(stream any) int {
+	streamMutex.Lock()
+	defer streamMutex.Unlock()
+	streamCounter++
+	streamRegistry[streamCounter] = stream
+	return streamCounter
+}
+
+// getStream retrieves a reader or writer by handle ID
+func getStream(handle int) (any, error) {
+	streamMutex.Lock()
+	defer streamMutex.Unlock()
+	stream, ok := streamRegistry[handle]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidStream, handle)
+	}
+	return stream, nil
+}
+
+// takeStream removes a reader or writer from the registry and returns it
+func takeStream(handle int) (any, error) {
+	streamMutex.Lock()
+	defer streamMutex.Unlock()
+	stream, ok := streamRegistry[handle]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidStream, handle)
+	}
+	delete(streamRegistry, handle)
+	return stream, nil
+}
+
+//export ObjstoreOpenReader
+func ObjstoreOpenReader(handle C.int, key *C.char) C.int {
+	storage, err := getCommonStorage(int(handle))
+	if err != nil {
+		setLastError(err)
+		return -1
+	}
+
+	reader, err := storage.GetWithContext(context.Background(), C.GoString(key))
+	if err != nil {
+		setLastError(err)
+		return -1
+	}
+
+	setLastError(nil)
+	return C.int(registerStream(reader))
+}
+
+//export ObjstoreReadChunk
+func ObjstoreReadChunk(stream C.int, buffer *C.char, bufferSize C.int) C.int {
+	s, err := getStream(int(stream))
+	if err != nil {
+		setLastError(err)
+		return -1
+	}
+	reader, ok := s.(io.ReadCloser)
+	if !ok {
+		setLastError(fmt.Errorf("%w: %d is not a reader", ErrInvalidStream, stream))
+		return -1
+	}
+	if bufferSize <= 0 {
+		setLastError(NewBufferTooSmallError(1, int(bufferSize)))
+		return -1
+	}
+
+	// Fill the buffer so that a short read always means end of object.
+	goBuffer := unsafe.Slice((*byte)(unsafe.Pointer(buffer)), bufferSize)
+	n, err := io.ReadFull(reader, goBuffer)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		setLastError(err)
+		return -1
+	}
+
+	setLastError(nil)
+	return C.int(n)
+}
+
+//export ObjstoreCloseReader
+func ObjstoreCloseReader(stream C.int) C.int {
+	s, err := takeStream(int(stream))
+	if err != nil {
+		setLastError(err)
+		return -1
+	}
+	reader, ok := s.(io.ReadCloser)
+	if !ok {
+		setLastError(fmt.Errorf("%w: %d is not a reader", ErrInvalidStream, stream))
+		return -1
+	}
+
+	if err := reader.Close(); err != nil {
+		setLastError(err)
+		return -1
+	}
+	setLastError(nil)
+	return 0
+}
+
+//export ObjstoreOpenWriter
+func ObjstoreOpenWriter(handle C.int, key *C.char) C.int {
+	storage, err := getCommonStorage(int(handle))
+	if err != nil {
+		setLastError(err)
+		return -1
+	}
+
+	goKey := C.GoString(key)
+	pr, pw := io.Pipe()
+	w := &streamWriter{pw: pw, done: make(chan error, 1)}
+	go func() {
+		err := storage.PutWithContext(context.Background(), goKey, pr)
+		// Unblock pending writes if the backend stopped reading early.
+		if err != nil {
+			_ = pr.CloseWithError(err)
+		} else {
+			_ = pr.Close()
+		}
+		w.done <- err
+	}()
+
+	setLastError(nil)
+	return C.int(registerStream(w))
+}
+
+//export ObjstoreWriteChunk
+func ObjstoreWriteChunk(stream C.int, data *C.char, dataLen C.int) C.int {
+	s, err := getStream(int(stream))
+	if err != nil {
+		setLastError(err)
+		return -1
+	}
+	w, ok := s.(*streamWriter)
+	if !ok {
+		setLastError(fmt.Errorf("%w: %d is not a writer", ErrInvalidStream, stream))
+		return -1
+	}
+
+	// The pipe returns only once the backend has consumed the chunk, so the
+	// C buffer can be read in place.
+	if dataLen > 0 {
+		chunk := unsafe.Slice((*byte)(unsafe.Pointer(data)), dataLen)
+		if _, err := w.pw.Write(chunk); err != nil {
+			setLastError(err)
+			return -1
+		}
+	}
+
+	setLastError(nil)
+	return 0
+}
+
+//export ObjstoreCloseWriter
+func ObjstoreCloseWriter(stream C.int) C.int {
+	return finishWriter(stream, nil)
+}
+
+//export ObjstoreAbortWriter
+func ObjstoreAbortWriter(stream C.int) C.int {
+	return finishWriter(stream, ErrWriterAborted)
+}
+
+// finishWriter ends an upload, committing it when abort is nil, and returns
+// the backend's result.
+func finishWriter(stream C.int, abort error) C.int {
+	s, err := takeStream(int(stream))
+	if err != nil {
+		setLastError(err)
+		return -1
+	}
+	w, ok := s.(*streamWriter)
+	if !ok {
+		setLastError(fmt.Errorf("%w: %d is not a writer", ErrInvalidStream, stream))
+		return -1
+	}
+
+	if abort != nil {
+		_ = w.pw.CloseWithError(abort)
+		<-w.done
+		setLastError(nil)
+		return 0
+	}
+
+	_ = w.pw.Close()
+	if err := <-w.done; err != nil {
+		setLastError(err)
+		return -1
+	}
+	setLastError(nil)
+	return 0
+}
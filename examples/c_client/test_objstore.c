@@ -8,6 +8,7 @@
  * 4. Deleting data from storage
  * 5. Proper error handling
  * 6. Resource cleanup
+ * 7. Listing, metadata, streaming and lifecycle policies
  */
 
 #include <stdio.h>
@@ -320,6 +321,230 @@ static int test_binary_data(int handle) {
     return 0;
 }
 
+/* Test 10: ABI version check */
+static int test_abi_version(void) {
+    PRINT_TEST("ABI Version Check");
+
+    int abi = ObjstoreABIVersion();
+    if (abi != OBJSTORE_ABI_VERSION) {
+        PRINT_FAIL("Library ABI does not match header");
+        printf("       Header: %d, Library: %d\n", OBJSTORE_ABI_VERSION, abi);
+        return -1;
+    }
+
+    printf("       ABI version: %d\n", abi);
+    PRINT_PASS("ABI version matches header");
+    return 0;
+}
+
+/* Test 11: Exists */
+static int test_exists(int handle) {
+    PRINT_TEST("Exists Operation");
+
+    if (ObjstoreExists(handle, "data/file2.txt") != 1) {
+        print_objstore_error("Existing object not reported");
+        return -1;
+    }
+    if (ObjstoreExists(handle, "data/missing.txt") != 0) {
+        print_objstore_error("Missing object reported as existing");
+        return -1;
+    }
+
+    PRINT_PASS("Exists operation successful");
+    return 0;
+}
+
+/* list_counter collects results from ObjstoreList */
+struct list_counter {
+    int count;
+    long long total_size;
+};
+
+static int count_objects(void *user_data, char *key, int64_t size, int64_t last_modified, char *etag) {
+    struct list_counter *counter = user_data;
+    (void)last_modified;
+    (void)etag;
+    counter->count++;
+    counter->total_size += size;
+    printf("       Listed: %s (%lld bytes)\n", key, (long long)size);
+    return 0;
+}
+
+static int stop_after_first(void *user_data, char *key, int64_t size, int64_t last_modified, char *etag) {
+    (void)key;
+    (void)size;
+    (void)last_modified;
+    (void)etag;
+    ((struct list_counter *)user_data)->count++;
+    return 1;
+}
+
+/* Test 12: List with callbacks */
+static int test_list(int handle) {
+    PRINT_TEST("List Operation");
+
+    struct list_counter counter = {0, 0};
+    int result = ObjstoreList(handle, "data/", count_objects, &counter);
+    if (result != 2 || counter.count != 2) {
+        print_objstore_error("Expected two objects under data/");
+        return -1;
+    }
+    if (counter.total_size != 36) {
+        PRINT_FAIL("Unexpected total size");
+        return -1;
+    }
+
+    struct list_counter first = {0, 0};
+    result = ObjstoreList(handle, "", stop_after_first, &first);
+    if (result != 1 || first.count != 1) {
+        PRINT_FAIL("Callback could not stop the listing");
+        return -1;
+    }
+
+    PRINT_PASS("List operation successful");
+    return 0;
+}
+
+/* Test 13: Metadata */
+static int test_metadata(int handle) {
+    PRINT_TEST("Metadata Operations");
+
+    const char *key = "data/file3.txt";
+    char *custom_keys[] = {"author"};
+    char *custom_values[] = {"alice"};
+    ObjstoreMetadata update;
+    memset(&update, 0, sizeof(update));
+    update.contentType = "text/plain";
+    update.customCount = 1;
+    update.customKeys = custom_keys;
+    update.customValues = custom_values;
+
+    if (ObjstoreUpdateMetadata(handle, (char *)key, &update) != 0) {
+        print_objstore_error("Failed to update metadata");
+        return -1;
+    }
+
+    ObjstoreMetadata md;
+    if (ObjstoreGetMetadata(handle, (char *)key, &md) != 0) {
+        print_objstore_error("Failed to get metadata");
+        return -1;
+    }
+
+    int ok = md.size == 18 &&
+             strcmp(md.contentType, "text/plain") == 0 &&
+             md.customCount == 1 &&
+             strcmp(md.customKeys[0], "author") == 0 &&
+             strcmp(md.customValues[0], "alice") == 0;
+    printf("       Size: %lld, Content-Type: %s, ETag: %s\n",
+           (long long)md.size, md.contentType, md.etag);
+    ObjstoreFreeMetadata(&md);
+    if (!ok) {
+        PRINT_FAIL("Metadata does not match update");
+        return -1;
+    }
+
+    PRINT_PASS("Metadata operations successful");
+    return 0;
+}
+
+/* Test 14: Streaming read and write */
+static int test_streaming(int handle) {
+    PRINT_TEST("Streaming Read and Write");
+
+    const char *key = "stream/large.bin";
+    char chunk[4096];
+    int chunks = 64;
+
+    int writer = ObjstoreOpenWriter(handle, (char *)key);
+    if (writer < 0) {
+        print_objstore_error("Failed to open writer");
+        return -1;
+    }
+    for (int i = 0; i < chunks; i++) {
+        memset(chunk, 'a' + (i % 26), sizeof(chunk));
+        if (ObjstoreWriteChunk(writer, chunk, sizeof(chunk)) != 0) {
+            print_objstore_error("Failed to write chunk");
+            ObjstoreAbortWriter(writer);
+            return -1;
+        }
+    }
+    if (ObjstoreCloseWriter(writer) != 0) {
+        print_objstore_error("Failed to close writer");
+        return -1;
+    }
+
+    int reader = ObjstoreOpenReader(handle, (char *)key);
+    if (reader < 0) {
+        print_objstore_error("Failed to open reader");
+        return -1;
+    }
+    long long total = 0;
+    int n;
+    while ((n = ObjstoreReadChunk(reader, chunk, sizeof(chunk))) > 0) {
+        if (chunk[0] != 'a' + (int)((total / (long long)sizeof(chunk)) % 26)) {
+            PRINT_FAIL("Streamed data corrupted");
+            ObjstoreCloseReader(reader);
+            return -1;
+        }
+        total += n;
+    }
+    ObjstoreCloseReader(reader);
+    if (n < 0 || total != (long long)chunks * (long long)sizeof(chunk)) {
+        print_objstore_error("Streamed size mismatch");
+        return -1;
+    }
+    printf("       Streamed %lld bytes in %d byte chunks\n", total, (int)sizeof(chunk));
+
+    /* An aborted writer must not store anything */
+    writer = ObjstoreOpenWriter(handle, "stream/aborted.bin");
+    ObjstoreWriteChunk(writer, chunk, sizeof(chunk));
+    ObjstoreAbortWriter(writer);
+    if (ObjstoreExists(handle, "stream/aborted.bin") != 0) {
+        PRINT_FAIL("Aborted writer stored an object");
+        return -1;
+    }
+
+    PRINT_PASS("Streaming read and write successful");
+    return 0;
+}
+
+static int count_policies(void *user_data, char *id, char *prefix, int64_t retention_seconds, char *action, char *storage_class) {
+    (void)storage_class;
+    (*(int *)user_data)++;
+    printf("       Policy: %s prefix=%s retention=%llds action=%s\n",
+           id, prefix, (long long)retention_seconds, action);
+    return 0;
+}
+
+/* Test 15: Lifecycle policies */
+static int test_policies(int handle) {
+    PRINT_TEST("Lifecycle Policies");
+
+    if (ObjstoreAddPolicy(handle, "expire-tmp", "tmp/", 86400, "delete", 0, NULL) != 0) {
+        print_objstore_error("Failed to add policy");
+        return -1;
+    }
+
+    int count = 0;
+    if (ObjstoreGetPolicies(handle, count_policies, &count) != 1 || count != 1) {
+        print_objstore_error("Expected one policy");
+        return -1;
+    }
+
+    if (ObjstoreRemovePolicy(handle, "expire-tmp") != 0) {
+        print_objstore_error("Failed to remove policy");
+        return -1;
+    }
+    count = 0;
+    if (ObjstoreGetPolicies(handle, count_policies, &count) != 0 || count != 0) {
+        print_objstore_error("Policy still present after removal");
+        return -1;
+    }
+
+    PRINT_PASS("Lifecycle policies successful");
+    return 0;
+}
+
 int main(void) {
     int handle = -1;
     int failed_tests = 0;
@@ -361,6 +586,24 @@ int main(void) {
     if (test_binary_data(handle) != 0) failed_tests++;
     printf("\n");
 
+    if (test_abi_version() != 0) failed_tests++;
+    printf("\n");
+
+    if (test_exists(handle) != 0) failed_tests++;
+    printf("\n");
+
+    if (test_list(handle) != 0) failed_tests++;
+    printf("\n");
+
+    if (test_metadata(handle) != 0) failed_tests++;
+    printf("\n");
+
+    if (test_streaming(handle) != 0) failed_tests++;
+    printf("\n");
+
+    if (test_policies(handle) != 0) failed_tests++;
+    printf("\n");
+
 cleanup:
     /* Cleanup */
     if (handle >= 0) {
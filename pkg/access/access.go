@@ -0,0 +1,318 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package access records when objects were last read and reports how the
+// objects under a prefix are distributed by age and by time since their
+// last read, so lifecycle retention periods can be chosen from data
+// instead of guesses.
+//
+// A Tracker keeps the last read time of each key in memory, optionally
+// persisted to a JSON file. Storage wraps a backend and records its reads
+// in a Tracker, sampling them when only an approximation is needed.
+// Analyze lists a prefix and counts its objects in age buckets.
+package access
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DefaultFlushInterval is the time between saves of the recorded reads
+// when Config.FlushInterval is zero.
+const DefaultFlushInterval = 5 * time.Minute
+
+var (
+	// ErrInvalidConfig is returned when an access tracking configuration
+	// is malformed.
+	ErrInvalidConfig = errors.New("invalid access tracking configuration")
+
+	// ErrStateCorrupt is returned when a persisted access state cannot be
+	// decoded.
+	ErrStateCorrupt = errors.New("access state is corrupt")
+)
+
+// Config configures a Tracker.
+type Config struct {
+	// SampleRate is the fraction of reads recorded, between 0 and 1. Zero
+	// records every read. A key read often is still recorded soon after
+	// each read, so sampling mostly skips work on hot keys while keeping
+	// the last read time of rarely read keys close.
+	SampleRate float64
+
+	// Path is the file recorded reads are persisted to. If empty, they are
+	// kept in memory and lost on restart.
+	Path string
+
+	// FlushInterval is the time between saves to Path.
+	FlushInterval time.Duration
+}
+
+// Validate checks that the sample rate is between 0 and 1 and the flush
+// interval is not negative.
+func (c *Config) Validate() error {
+	if c.SampleRate < 0 || c.SampleRate > 1 {
+		return fmt.Errorf("%w: sample rate must be between 0 and 1", ErrInvalidConfig)
+	}
+	if c.FlushInterval < 0 {
+		return fmt.Errorf("%w: flush interval must not be negative", ErrInvalidConfig)
+	}
+	return nil
+}
+
+// state is the persisted form of a Tracker.
+type state struct {
+	Since    time.Time            `json:"since"`
+	Accessed map[string]time.Time `json:"accessed"`
+}
+
+// Tracker records the last read time of each key. It is safe for
+// concurrent use.
+type Tracker struct {
+	cfg Config
+
+	mu       sync.RWMutex
+	since    time.Time
+	accessed map[string]time.Time
+	dirty    bool
+
+	// saveMu serializes saves.
+	saveMu sync.Mutex
+
+	startOnce sync.Once
+	closeOnce sync.Once
+	stop      chan struct{}
+	done      chan struct{}
+
+	// now and sample are replaced by tests.
+	now    func() time.Time
+	sample func() float64
+}
+
+// NewTracker creates a Tracker, loading the reads persisted to cfg.Path if
+// it exists. Call Start to save recorded reads in the background.
+func NewTracker(cfg *Config) (*Tracker, error) {
+	c := Config{}
+	if cfg != nil {
+		c = *cfg
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	if c.SampleRate == 0 {
+		c.SampleRate = 1
+	}
+	if c.FlushInterval == 0 {
+		c.FlushInterval = DefaultFlushInterval
+	}
+
+	t := &Tracker{
+		cfg:      c,
+		since:    time.Now().UTC(),
+		accessed: make(map[string]time.Time),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+		now:      time.Now,
+		sample:   rand.Float64,
+	}
+	if c.Path == "" {
+		return t, nil
+	}
+	data, err := os.ReadFile(c.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		// Save the start of tracking even if nothing is read before the
+		// next restart.
+		t.dirty = true
+		return t, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read access state: %w", err)
+	}
+	var s state
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrStateCorrupt, err)
+	}
+	if !s.Since.IsZero() {
+		t.since = s.Since
+	}
+	if s.Accessed != nil {
+		t.accessed = s.Accessed
+	}
+	return t, nil
+}
+
+// Load returns the reads persisted to path without tracking new ones, for
+// reports made outside the server that records them.
+func Load(path string) (*Tracker, error) {
+	if path == "" {
+		return nil, fmt.Errorf("%w: path is empty", ErrInvalidConfig)
+	}
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("failed to read access state: %w", err)
+	}
+	return NewTracker(&Config{Path: path})
+}
+
+// Config returns the configuration of t, with defaults applied.
+func (t *Tracker) Config() Config {
+	return t.cfg
+}
+
+// Since returns when tracking started. Keys not read since then have no
+// recorded read.
+func (t *Tracker) Since() time.Time {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.since
+}
+
+// Len returns the number of keys with a recorded read.
+func (t *Tracker) Len() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return len(t.accessed)
+}
+
+// Record records a read of key now, subject to the sample rate.
+func (t *Tracker) Record(key string) {
+	if t.cfg.SampleRate < 1 && t.sample() >= t.cfg.SampleRate {
+		return
+	}
+	now := t.now().UTC()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if last, ok := t.accessed[key]; ok && !now.After(last) {
+		return
+	}
+	t.accessed[key] = now
+	t.dirty = true
+}
+
+// Forget drops the recorded read of key, after it is deleted.
+func (t *Tracker) Forget(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.accessed[key]; ok {
+		delete(t.accessed, key)
+		t.dirty = true
+	}
+}
+
+// LastAccess returns the last recorded read of key, and false when none
+// was recorded since tracking started.
+func (t *Tracker) LastAccess(key string) (time.Time, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	last, ok := t.accessed[key]
+	return last, ok
+}
+
+// Start saves the recorded reads every flush interval until t is closed.
+// It does nothing when no path is configured.
+func (t *Tracker) Start() {
+	if t.cfg.Path == "" {
+		return
+	}
+	t.startOnce.Do(func() {
+		go t.run()
+	})
+}
+
+func (t *Tracker) run() {
+	defer close(t.done)
+
+	ticker := time.NewTicker(t.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-t.stop:
+			return
+		case <-ticker.C:
+			_ = t.Flush() // #nosec G104 -- A failed save is retried at the next interval
+		}
+	}
+}
+
+// Close stops background saves and saves the recorded reads a last time.
+func (t *Tracker) Close() error {
+	var err error
+	t.closeOnce.Do(func() {
+		close(t.stop)
+		started := true
+		t.startOnce.Do(func() { started = false })
+		if started {
+			<-t.done
+		}
+		err = t.Flush()
+	})
+	return err
+}
+
+// Flush saves the recorded reads to the configured path if they changed
+// since the last save. It does nothing when no path is configured.
+func (t *Tracker) Flush() error {
+	if t.cfg.Path == "" {
+		return nil
+	}
+	t.saveMu.Lock()
+	defer t.saveMu.Unlock()
+
+	t.mu.Lock()
+	if !t.dirty {
+		t.mu.Unlock()
+		return nil
+	}
+	data, err := json.Marshal(state{Since: t.since, Accessed: t.accessed})
+	t.dirty = false
+	t.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode access state: %w", err)
+	}
+
+	if err := writeFile(t.cfg.Path, data); err != nil {
+		t.mu.Lock()
+		t.dirty = true
+		t.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// writeFile replaces path with data through a temporary file, so a crash
+// never leaves a partial state behind.
+func writeFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".access-state-*")
+	if err != nil {
+		return fmt.Errorf("failed to save access state: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to save access state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to save access state: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to save access state: %w", err)
+	}
+	return nil
+}
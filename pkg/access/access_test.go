@@ -0,0 +1,212 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package access
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+func TestConfigValidate(t *testing.T) {
+	for _, cfg := range []Config{{SampleRate: -0.1}, {SampleRate: 1.5}, {FlushInterval: -time.Second}} {
+		if _, err := NewTracker(&cfg); !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("NewTracker(%+v) error = %v, want ErrInvalidConfig", cfg, err)
+		}
+	}
+}
+
+func TestTrackerSampling(t *testing.T) {
+	tracker, err := NewTracker(&Config{SampleRate: 0.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	draw := 0.9
+	tracker.sample = func() float64 { return draw }
+	tracker.Record("skipped")
+	if _, ok := tracker.LastAccess("skipped"); ok {
+		t.Error("read above the sample rate was recorded")
+	}
+	draw = 0.1
+	tracker.Record("recorded")
+	if _, ok := tracker.LastAccess("recorded"); !ok {
+		t.Error("read below the sample rate was not recorded")
+	}
+}
+
+func TestTrackerPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access.json")
+	tracker, err := NewTracker(&Config{Path: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	read := time.Date(2025, 11, 1, 12, 0, 0, 0, time.UTC)
+	tracker.now = func() time.Time { return read }
+	tracker.Record("a.txt")
+	tracker.Record("b.txt")
+	tracker.Forget("b.txt")
+	tracker.Start()
+	if err := tracker.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if last, ok := loaded.LastAccess("a.txt"); !ok || !last.Equal(read) {
+		t.Errorf("LastAccess(a.txt) = %v, %v, want %v", last, ok, read)
+	}
+	if loaded.Len() != 1 || !loaded.Since().Equal(tracker.Since()) {
+		t.Errorf("loaded %d keys since %v, want 1 since %v", loaded.Len(), loaded.Since(), tracker.Since())
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Load() of a missing file succeeded")
+	}
+	if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); !errors.Is(err, ErrStateCorrupt) {
+		t.Errorf("Load() of a corrupt file error = %v, want ErrStateCorrupt", err)
+	}
+}
+
+func TestStorage(t *testing.T) {
+	tracker, err := NewTracker(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewStorage(memory.New(), tracker)
+	ctx := context.Background()
+	for _, key := range []string{"a.txt", "b.txt"} {
+		if err := s.PutWithContext(ctx, key, strings.NewReader("hello")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if tracker.Len() != 0 {
+		t.Errorf("writes were recorded as reads: %d", tracker.Len())
+	}
+
+	rc, err := s.Get("a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = rc.Close()
+	rc, err = s.GetRange(ctx, "b.txt", 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := io.ReadAll(rc); string(data) != "el" {
+		t.Errorf("GetRange() = %q, want %q", data, "el")
+	}
+	_ = rc.Close()
+	if _, err := s.Get("missing.txt"); err == nil {
+		t.Error("Get() of a missing key succeeded")
+	}
+	if tracker.Len() != 2 {
+		t.Errorf("recorded %d reads, want 2", tracker.Len())
+	}
+
+	if err := s.Delete("a.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := tracker.LastAccess("a.txt"); ok {
+		t.Error("read of a deleted key is still recorded")
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	backend := memory.New()
+	ctx := context.Background()
+	for _, key := range []string{"logs/2025/a.log", "logs/2025/b.log", "images/cat.png", "readme.txt"} {
+		if err := backend.PutWithContext(ctx, key, strings.NewReader("0123456789")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tracker, err := NewTracker(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	tracker.now = func() time.Time { return now.Add(-60 * 24 * time.Hour) }
+	tracker.Record("logs/2025/a.log")
+	tracker.now = func() time.Time { return now }
+	tracker.Record("images/cat.png")
+
+	// Measured 40 days on, every object is between 30 and 90 days old.
+	report, err := Analyze(ctx, backend, tracker, "", &AnalyzeOptions{Now: now.Add(40 * 24 * time.Hour)})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	total := report.Total
+	if total.Objects != 4 || total.Bytes != 40 || report.TrackingSince == nil {
+		t.Fatalf("Total = %+v, tracking since %v", total, report.TrackingSince)
+	}
+	if len(total.Age) != len(DefaultBuckets)+1 || total.Age[3].Label != "30d-90d" || total.Age[3].Objects != 4 {
+		t.Errorf("Age = %+v, want every object in 30d-90d", total.Age)
+	}
+	if last := total.Access[len(total.Access)-1]; last.Label != NeverLabel || last.Objects != 2 {
+		t.Errorf("never read = %+v, want 2 objects", last)
+	}
+	if total.Access[3].Objects != 1 || total.Access[4].Objects != 1 {
+		t.Errorf("Access = %+v, want one read in 30d-90d and one in 90d-180d", total.Access)
+	}
+
+	var prefixes []string
+	for _, dist := range report.Prefixes {
+		prefixes = append(prefixes, dist.Prefix)
+	}
+	if strings.Join(prefixes, ",") != ",images/,logs/" {
+		t.Errorf("Prefixes = %v, want root, images/ and logs/", prefixes)
+	}
+
+	report, err = Analyze(ctx, backend, nil, "logs/", &AnalyzeOptions{Depth: -1, Buckets: []time.Duration{time.Hour}})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	if report.Total.Objects != 2 || len(report.Prefixes) != 0 || report.Total.Access != nil || report.TrackingSince != nil {
+		t.Errorf("report = %+v, want two objects without prefixes or reads", report)
+	}
+	if report.Total.Age[0].Label != "0-1h" || report.Total.Age[0].Objects != 2 {
+		t.Errorf("Age = %+v, want both objects under an hour old", report.Total.Age)
+	}
+
+	if _, err := Analyze(ctx, backend, nil, "", &AnalyzeOptions{Buckets: []time.Duration{time.Hour, time.Minute}}); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("Analyze() with unsorted buckets error = %v, want ErrInvalidConfig", err)
+	}
+}
+
+func TestParseBuckets(t *testing.T) {
+	bounds, err := ParseBuckets("12h, 7d,30d")
+	if err != nil {
+		t.Fatalf("ParseBuckets() error = %v", err)
+	}
+	want := []time.Duration{12 * time.Hour, 7 * 24 * time.Hour, 30 * 24 * time.Hour}
+	if len(bounds) != len(want) || bounds[0] != want[0] || bounds[1] != want[1] || bounds[2] != want[2] {
+		t.Errorf("ParseBuckets() = %v, want %v", bounds, want)
+	}
+	for _, s := range []string{"", "7d,1d", "xd", "0d", "soon"} {
+		if _, err := ParseBuckets(s); !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("ParseBuckets(%q) error = %v, want ErrInvalidConfig", s, err)
+		}
+	}
+}
@@ -0,0 +1,306 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package access
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// DefaultBuckets are the upper bounds of the age buckets objects are
+// counted in when AnalyzeOptions.Buckets is empty. Objects older than the
+// last bound are counted in a final, open-ended bucket.
+var DefaultBuckets = []time.Duration{
+	24 * time.Hour,
+	7 * 24 * time.Hour,
+	30 * 24 * time.Hour,
+	90 * 24 * time.Hour,
+	180 * 24 * time.Hour,
+	365 * 24 * time.Hour,
+}
+
+// NeverLabel labels the access bucket of objects with no recorded read.
+const NeverLabel = "never"
+
+// AnalyzeOptions control Analyze.
+type AnalyzeOptions struct {
+	// Depth is the number of "/"-separated key segments below the analyzed
+	// prefix that objects are grouped by in Report.Prefixes: 1 groups
+	// "logs/2025/a.log" under "logs/" when analyzing the whole backend.
+	// Zero groups by one segment; a negative value reports totals only.
+	Depth int
+
+	// Buckets are the ascending upper bounds of the age buckets. If empty,
+	// DefaultBuckets are used.
+	Buckets []time.Duration
+
+	// Now is the time ages are measured from. If zero, the current time
+	// is used.
+	Now time.Time
+}
+
+// Bucket counts the objects whose age falls in a range.
+type Bucket struct {
+	// Label names the range, such as "7d-30d", "365d+" or "never".
+	Label string `json:"label"`
+
+	Objects int64 `json:"objects"`
+	Bytes   int64 `json:"bytes"`
+}
+
+// Distribution is the number and size of a set of objects, counted by
+// the time since they were last modified and since they were last read.
+type Distribution struct {
+	// Prefix is the common prefix of the objects.
+	Prefix string `json:"prefix"`
+
+	Objects int64 `json:"objects"`
+	Bytes   int64 `json:"bytes"`
+
+	// Age buckets the objects by the time since they were last modified.
+	Age []Bucket `json:"age"`
+
+	// Access buckets the objects by the time since they were last read,
+	// with a final "never" bucket for objects not read since tracking
+	// started. It is empty when reads are not tracked.
+	Access []Bucket `json:"access,omitempty"`
+}
+
+// Report is the age and access distribution of the objects under a
+// prefix.
+type Report struct {
+	// GeneratedAt is the time ages are measured from.
+	GeneratedAt time.Time `json:"generated_at"`
+
+	// TrackingSince is when read tracking started, or nil when reads are
+	// not tracked. Objects last read before then count as never read.
+	TrackingSince *time.Time `json:"tracking_since,omitempty"`
+
+	// Total is the distribution of all the objects under the prefix.
+	Total Distribution `json:"total"`
+
+	// Prefixes are the distributions of the objects grouped by prefix, in
+	// prefix order.
+	Prefixes []Distribution `json:"prefixes"`
+}
+
+// Analyze lists the objects under prefix and counts them by age and, when
+// tracker is not nil, by the time since their last recorded read.
+func Analyze(ctx context.Context, storage common.Storage, tracker *Tracker, prefix string, opts *AnalyzeOptions) (*Report, error) {
+	if opts == nil {
+		opts = &AnalyzeOptions{}
+	}
+	bounds := opts.Buckets
+	if len(bounds) == 0 {
+		bounds = DefaultBuckets
+	}
+	if !slices.IsSorted(bounds) || bounds[0] <= 0 {
+		return nil, fmt.Errorf("%w: buckets must be positive and ascending", ErrInvalidConfig)
+	}
+	depth := opts.Depth
+	if depth == 0 {
+		depth = 1
+	}
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	labels := bucketLabels(bounds)
+	report := &Report{
+		GeneratedAt: now.UTC(),
+		Total:       newDistribution(prefix, labels, tracker != nil),
+		Prefixes:    []Distribution{},
+	}
+	if tracker != nil {
+		since := tracker.Since()
+		report.TrackingSince = &since
+	}
+	groups := make(map[string]*Distribution)
+
+	listOpts := &common.ListOptions{Prefix: prefix, MaxResults: 1000}
+	for {
+		page, err := storage.ListWithOptions(ctx, listOpts)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range page.Objects {
+			if obj == nil || strings.HasPrefix(obj.Key, common.StagingPrefix) {
+				continue
+			}
+			metadata := obj.Metadata
+			if metadata == nil || metadata.LastModified.IsZero() {
+				metadata, err = storage.GetMetadata(ctx, obj.Key)
+				if errors.Is(err, common.ErrNotFound) {
+					// Deleted since it was listed
+					continue
+				}
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			age := bucketIndex(bounds, now.Sub(metadata.LastModified))
+			access := -1
+			if tracker != nil {
+				access = len(labels) // never
+				if last, ok := tracker.LastAccess(obj.Key); ok {
+					access = bucketIndex(bounds, now.Sub(last))
+				}
+			}
+
+			report.Total.add(metadata.Size, age, access)
+			if depth > 0 {
+				group := groupPrefix(prefix, obj.Key, depth)
+				dist, ok := groups[group]
+				if !ok {
+					d := newDistribution(group, labels, tracker != nil)
+					dist = &d
+					groups[group] = dist
+				}
+				dist.add(metadata.Size, age, access)
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if page.NextToken == "" || page.NextToken == listOpts.ContinueFrom {
+			break
+		}
+		listOpts.ContinueFrom = page.NextToken
+	}
+
+	for _, dist := range groups {
+		report.Prefixes = append(report.Prefixes, *dist)
+	}
+	slices.SortFunc(report.Prefixes, func(a, b Distribution) int {
+		return strings.Compare(a.Prefix, b.Prefix)
+	})
+	return report, nil
+}
+
+func newDistribution(prefix string, labels []string, tracked bool) Distribution {
+	dist := Distribution{Prefix: prefix, Age: make([]Bucket, len(labels))}
+	for i, label := range labels {
+		dist.Age[i].Label = label
+	}
+	if tracked {
+		dist.Access = make([]Bucket, len(labels)+1)
+		copy(dist.Access, dist.Age)
+		dist.Access[len(labels)].Label = NeverLabel
+	}
+	return dist
+}
+
+// add counts an object of size bytes in the age bucket age and, unless it
+// is negative, the access bucket access.
+func (d *Distribution) add(size int64, age, access int) {
+	d.Objects++
+	d.Bytes += size
+	d.Age[age].Objects++
+	d.Age[age].Bytes += size
+	if access >= 0 {
+		d.Access[access].Objects++
+		d.Access[access].Bytes += size
+	}
+}
+
+// bucketIndex returns the index of the first bound above elapsed, or the
+// open-ended bucket after the last bound.
+func bucketIndex(bounds []time.Duration, elapsed time.Duration) int {
+	i, _ := slices.BinarySearchFunc(bounds, elapsed, func(bound, elapsed time.Duration) int {
+		if bound <= elapsed {
+			return -1
+		}
+		return 1
+	})
+	return i
+}
+
+// bucketLabels names the ranges between bounds: "0-1d", "1d-7d", ...,
+// and a final "365d+".
+func bucketLabels(bounds []time.Duration) []string {
+	labels := make([]string, 0, len(bounds)+1)
+	lower := "0"
+	for _, bound := range bounds {
+		upper := formatBound(bound)
+		labels = append(labels, lower+"-"+upper)
+		lower = upper
+	}
+	return append(labels, lower+"+")
+}
+
+// formatBound formats whole days as "30d", whole hours as "12h" and
+// other durations as time.Duration does.
+func formatBound(d time.Duration) string {
+	const day = 24 * time.Hour
+	switch {
+	case d%day == 0:
+		return fmt.Sprintf("%dd", d/day)
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	}
+	return d.String()
+}
+
+// ParseBuckets parses a comma-separated list of ascending bucket bounds,
+// each a whole number of days such as "30d" or a time.Duration such as
+// "12h".
+func ParseBuckets(s string) ([]time.Duration, error) {
+	var bounds []time.Duration
+	for field := range strings.SplitSeq(s, ",") {
+		field = strings.TrimSpace(field)
+		var bound time.Duration
+		if days, ok := strings.CutSuffix(field, "d"); ok {
+			n, err := strconv.Atoi(days)
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid bucket %q", ErrInvalidConfig, field)
+			}
+			bound = time.Duration(n) * 24 * time.Hour
+		} else {
+			d, err := time.ParseDuration(field)
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid bucket %q", ErrInvalidConfig, field)
+			}
+			bound = d
+		}
+		if bound <= 0 || (len(bounds) > 0 && bound <= bounds[len(bounds)-1]) {
+			return nil, fmt.Errorf("%w: buckets must be positive and ascending", ErrInvalidConfig)
+		}
+		bounds = append(bounds, bound)
+	}
+	return bounds, nil
+}
+
+// groupPrefix returns prefix followed by up to depth segments of the rest
+// of key, including the trailing "/", or prefix alone for keys with no
+// further segment.
+func groupPrefix(prefix, key string, depth int) string {
+	end := len(prefix)
+	for range depth {
+		i := strings.IndexByte(key[end:], '/')
+		if i < 0 {
+			break
+		}
+		end += i + 1
+	}
+	return key[:end]
+}
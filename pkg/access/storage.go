@@ -0,0 +1,105 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package access
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// Storage wraps a backend and records every successful read in a Tracker.
+// Deletes drop the recorded read; other operations pass through unchanged.
+type Storage struct {
+	common.Storage
+	tracker *Tracker
+}
+
+// NewStorage returns underlying wrapped so that reads are recorded in
+// tracker.
+func NewStorage(underlying common.Storage, tracker *Tracker) *Storage {
+	return &Storage{Storage: underlying, tracker: tracker}
+}
+
+// Tracker returns the tracker reads are recorded in.
+func (s *Storage) Tracker() *Tracker {
+	return s.tracker
+}
+
+// Underlying returns the wrapped backend.
+func (s *Storage) Underlying() common.Storage {
+	return s.Storage
+}
+
+// Get retrieves an object and records the read.
+func (s *Storage) Get(key string) (io.ReadCloser, error) {
+	return s.GetWithContext(context.Background(), key)
+}
+
+// GetWithContext retrieves an object and records the read.
+func (s *Storage) GetWithContext(ctx context.Context, key string) (io.ReadCloser, error) {
+	rc, err := s.Storage.GetWithContext(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	s.tracker.Record(key)
+	return rc, nil
+}
+
+// GetRange reads a byte range from the wrapped backend, falling back to
+// discarding the leading bytes of a full read when it cannot read ranges,
+// and records the read.
+func (s *Storage) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	if rr, ok := s.Storage.(common.RangeReader); ok {
+		rc, err := rr.GetRange(ctx, key, offset, length)
+		if err != nil {
+			return nil, err
+		}
+		s.tracker.Record(key)
+		return rc, nil
+	}
+	rc, err := s.GetWithContext(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return common.SliceRange(rc, offset, length)
+}
+
+// GetAsOf reads an earlier version of an object from the wrapped backend.
+// It is not recorded: reading an old version says nothing about how the
+// current object is used.
+func (s *Storage) GetAsOf(ctx context.Context, key string, at time.Time) (io.ReadCloser, *common.Metadata, error) {
+	return common.GetAsOf(ctx, s.Storage, key, at)
+}
+
+// PutWithInfo stores an object with metadata and returns what was stored.
+func (s *Storage) PutWithInfo(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) (*common.ObjectInfo, error) {
+	return common.PutWithInfo(ctx, s.Storage, key, data, metadata)
+}
+
+// Delete removes an object and drops its recorded read.
+func (s *Storage) Delete(key string) error {
+	return s.DeleteWithContext(context.Background(), key)
+}
+
+// DeleteWithContext removes an object and drops its recorded read.
+func (s *Storage) DeleteWithContext(ctx context.Context, key string) error {
+	if err := s.Storage.DeleteWithContext(ctx, key); err != nil {
+		return err
+	}
+	s.tracker.Forget(key)
+	return nil
+}
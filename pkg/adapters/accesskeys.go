@@ -0,0 +1,221 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package adapters
+
+import (
+	"cmp"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// accessKeyIDPrefix begins every issued access key ID, so keys issued by
+	// the store are told apart from keys configured elsewhere.
+	accessKeyIDPrefix = "OSK"
+
+	// accessKeySecretBytes is the entropy of a secret access key.
+	accessKeySecretBytes = 30
+
+	// accessKeyPrincipalType is the Type of principals authenticated by an
+	// issued key.
+	accessKeyPrincipalType = "access-key"
+)
+
+var (
+	// ErrAccessKeyNotFound is returned for an access key the store did not
+	// issue or has revoked.
+	ErrAccessKeyNotFound = errors.New("access key not found")
+
+	// ErrInvalidAccessKey is returned when an access key request is
+	// incomplete.
+	ErrInvalidAccessKey = errors.New("invalid access key")
+)
+
+// AccessKey is a SigV4 access key issued by an AccessKeyStore.
+type AccessKey struct {
+	AccessKeyID string `json:"access_key_id"`
+
+	// SecretAccessKey is only returned when the key is created; List and
+	// Get leave it empty.
+	SecretAccessKey string `json:"secret_access_key,omitempty"`
+
+	// PrincipalID and Roles are the principal the key authenticates as.
+	PrincipalID string   `json:"principal_id"`
+	Roles       []string `json:"roles,omitempty"`
+
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// AccessKeyStore issues and revokes SigV4 access keys at run time. Pass
+// its LookupSecret to NewSigV4Authenticator so requests signed with the
+// keys authenticate. Keys are kept in memory, and in a JSON file when the
+// store has a path; the file holds the secrets and is written with mode
+// 0600.
+type AccessKeyStore struct {
+	mu   sync.RWMutex
+	keys map[string]AccessKey
+	path string
+}
+
+// NewAccessKeyStore creates an access key store persisted to path, loading
+// the keys already there. An empty path keeps keys in memory only.
+func NewAccessKeyStore(path string) (*AccessKeyStore, error) {
+	s := &AccessKeyStore{keys: make(map[string]AccessKey), path: path}
+	if path == "" {
+		return s, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read access keys: %w", err)
+	}
+	var keys []AccessKey
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("failed to parse access keys %s: %w", path, err)
+	}
+	for _, key := range keys {
+		s.keys[key.AccessKeyID] = key
+	}
+	return s, nil
+}
+
+// Create issues a key for the principal and roles of key, generating its
+// ID and secret. The returned key is the only one carrying the secret.
+func (s *AccessKeyStore) Create(key AccessKey) (*AccessKey, error) {
+	if key.PrincipalID == "" {
+		return nil, fmt.Errorf("%w: principal_id is required", ErrInvalidAccessKey)
+	}
+	secret := make([]byte, accessKeySecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate secret access key: %w", err)
+	}
+	key.AccessKeyID = accessKeyIDPrefix + rand.Text()[:17]
+	key.SecretAccessKey = base64.RawStdEncoding.EncodeToString(secret)
+	key.Roles = slices.Clone(key.Roles)
+	key.CreatedAt = time.Now().UTC().Truncate(time.Second)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys[key.AccessKeyID] = key
+	if err := s.save(); err != nil {
+		delete(s.keys, key.AccessKeyID)
+		return nil, err
+	}
+	return &key, nil
+}
+
+// List returns the issued keys, without their secrets, by ID.
+func (s *AccessKeyStore) List() []AccessKey {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	keys := make([]AccessKey, 0, len(s.keys))
+	for _, key := range s.keys {
+		keys = append(keys, withoutSecret(key))
+	}
+	slices.SortFunc(keys, func(a, b AccessKey) int { return cmp.Compare(a.AccessKeyID, b.AccessKeyID) })
+	return keys
+}
+
+// Get returns an issued key without its secret.
+func (s *AccessKeyStore) Get(id string) (*AccessKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	key, ok := s.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrAccessKeyNotFound, id)
+	}
+	key = withoutSecret(key)
+	return &key, nil
+}
+
+// Delete revokes a key; requests signed with it no longer authenticate.
+func (s *AccessKeyStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key, ok := s.keys[id]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrAccessKeyNotFound, id)
+	}
+	delete(s.keys, id)
+	if err := s.save(); err != nil {
+		s.keys[id] = key
+		return err
+	}
+	return nil
+}
+
+// LookupSecret returns the secret and principal of an issued key, for
+// SigV4Authenticator.LookupSecret.
+func (s *AccessKeyStore) LookupSecret(_ context.Context, accessKeyID string) (string, *Principal, error) {
+	s.mu.RLock()
+	key, ok := s.keys[accessKeyID]
+	s.mu.RUnlock()
+	if !ok {
+		return "", nil, fmt.Errorf("%w: unknown access key", ErrInvalidCredentials)
+	}
+	return key.SecretAccessKey, &Principal{
+		ID:    key.PrincipalID,
+		Name:  key.PrincipalID,
+		Type:  accessKeyPrincipalType,
+		Roles: slices.Clone(key.Roles),
+	}, nil
+}
+
+// save writes the keys to the store's file, replacing it atomically. The
+// caller holds mu.
+func (s *AccessKeyStore) save() error {
+	if s.path == "" {
+		return nil
+	}
+	keys := slices.SortedFunc(maps.Values(s.keys), func(a, b AccessKey) int { return cmp.Compare(a.AccessKeyID, b.AccessKeyID) })
+	data, err := json.MarshalIndent(keys, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode access keys: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".access-keys-*")
+	if err != nil {
+		return fmt.Errorf("failed to save access keys: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save access keys: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save access keys: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to save access keys: %w", err)
+	}
+	return nil
+}
+
+func withoutSecret(key AccessKey) AccessKey {
+	key.SecretAccessKey = ""
+	key.Roles = slices.Clone(key.Roles)
+	return key
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package adapters
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAccessKeyStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access-keys.json")
+	store, err := NewAccessKeyStore(path)
+	if err != nil {
+		t.Fatalf("NewAccessKeyStore() error = %v", err)
+	}
+
+	if _, err := store.Create(AccessKey{}); !errors.Is(err, ErrInvalidAccessKey) {
+		t.Errorf("Create() without principal error = %v, want ErrInvalidAccessKey", err)
+	}
+	key, err := store.Create(AccessKey{PrincipalID: "ci", Roles: []string{"writer"}, Description: "deploys"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if key.AccessKeyID == "" || len(key.SecretAccessKey) != 40 {
+		t.Fatalf("Create() = %+v", key)
+	}
+
+	keys := store.List()
+	if len(keys) != 1 || keys[0].AccessKeyID != key.AccessKeyID || keys[0].SecretAccessKey != "" || keys[0].Description != "deploys" {
+		t.Errorf("List() = %+v", keys)
+	}
+	if got, err := store.Get(key.AccessKeyID); err != nil || got.SecretAccessKey != "" || got.PrincipalID != "ci" {
+		t.Errorf("Get() = %+v, %v", got, err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("key file = %v, %v; want mode 0600", info, err)
+	}
+
+	// Requests signed with the key authenticate as its principal
+	auth := NewSigV4Authenticator(store.LookupSecret)
+	req := httptest.NewRequest("GET", "http://objstore.example.com/api/v1/objects/a", nil)
+	SignHTTPRequestV4(req, SigV4Credentials{AccessKeyID: key.AccessKeyID, SecretAccessKey: key.SecretAccessKey},
+		"us-east-1", "objstore", SigV4UnsignedPayload, time.Now())
+	principal, err := auth.AuthenticateHTTP(context.Background(), req)
+	if err != nil || principal.ID != "ci" || principal.Roles[0] != "writer" {
+		t.Fatalf("AuthenticateHTTP() = %+v, %v", principal, err)
+	}
+
+	// The keys survive a restart
+	reloaded, err := NewAccessKeyStore(path)
+	if err != nil {
+		t.Fatalf("NewAccessKeyStore() reload error = %v", err)
+	}
+	if secret, _, err := reloaded.LookupSecret(context.Background(), key.AccessKeyID); err != nil || secret != key.SecretAccessKey {
+		t.Errorf("LookupSecret() after reload = %q, %v", secret, err)
+	}
+
+	if err := store.Delete(key.AccessKeyID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := store.Delete(key.AccessKeyID); !errors.Is(err, ErrAccessKeyNotFound) {
+		t.Errorf("Delete() of a revoked key error = %v, want ErrAccessKeyNotFound", err)
+	}
+	if _, err := store.Get(key.AccessKeyID); !errors.Is(err, ErrAccessKeyNotFound) {
+		t.Errorf("Get() of a revoked key error = %v, want ErrAccessKeyNotFound", err)
+	}
+	if _, err := auth.AuthenticateHTTP(context.Background(), req); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("AuthenticateHTTP() with a revoked key error = %v, want ErrInvalidCredentials", err)
+	}
+	if reloaded, err := NewAccessKeyStore(path); err != nil || len(reloaded.List()) != 0 {
+		t.Errorf("keys after Delete() and reload = %v, %v", reloaded, err)
+	}
+}
+
+func TestAccessKeyStoreInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access-keys.json")
+	if err := os.WriteFile(path, []byte("not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewAccessKeyStore(path); err == nil {
+		t.Error("NewAccessKeyStore() accepted an invalid file")
+	}
+}
@@ -84,6 +84,33 @@ const (
 	// ResourceReplication identifies replication-configuration resources.
 	ResourceReplication = "replication"
 
+	// ResourceRetention identifies legal holds and deletion approval requests.
+	ResourceRetention = "retention"
+
+	// ResourceManifest identifies dataset manifests.
+	ResourceManifest = "manifest"
+
+	// ResourceCost identifies cost reports.
+	ResourceCost = "cost"
+
+	// ResourceJobs identifies background job status.
+	ResourceJobs = "jobs"
+
+	// ResourceTasks identifies the durable task queue.
+	ResourceTasks = "tasks"
+
+	// ResourceShares identifies share links.
+	ResourceShares = "shares"
+
+	// ResourceQuota identifies the quotas of key prefixes.
+	ResourceQuota = "quota"
+
+	// ResourceWebhook identifies event notification rules.
+	ResourceWebhook = "webhook"
+
+	// ResourceAccessKey identifies SigV4 access keys issued by the server.
+	ResourceAccessKey = "access-key"
+
 	// ResourceObject identifies the object resource category. It is used when a
 	// concrete object key is unavailable (e.g., a gRPC interceptor that only has
 	// the method name) so authorization can still be scoped to the object plane.
@@ -122,6 +149,16 @@ type Principal struct {
 	// server code does not read or enforce TenantID; that is the injected
 	// authenticator's responsibility.
 	TenantID string
+
+	// Scope, when set, limits the principal to a key prefix and a set of
+	// actions. It is set for principals authenticated by a TokenService
+	// token and enforced by ScopedAuthorizer.
+	Scope *TokenScope
+
+	// OnBehalfOf is the end-user identity a privileged service is acting
+	// for, set by OnBehalfOfAuthenticator. It is recorded for attribution
+	// and does not change how the principal is authorized.
+	OnBehalfOf string
 }
 
 // HasRole checks if the principal has the specified role.
@@ -134,6 +171,20 @@ func (p *Principal) HasRole(role string) bool {
 	return false
 }
 
+// Identity returns the identity a request in ctx is attributed to: the end
+// user a service acts for, or else the principal's ID. It is empty when ctx
+// carries no principal.
+func Identity(ctx context.Context) string {
+	p, ok := ctx.Value(PrincipalContextKey{}).(*Principal)
+	if !ok || p == nil {
+		return ""
+	}
+	if p.OnBehalfOf != "" {
+		return p.OnBehalfOf
+	}
+	return p.ID
+}
+
 // Authenticator defines the interface for pluggable authentication implementations.
 // Applications can implement this interface to integrate their native authentication
 // mechanisms (e.g., OAuth, JWT, API keys, mTLS, custom).
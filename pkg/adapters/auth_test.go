@@ -59,6 +59,20 @@ func TestPrincipal_HasRole(t *testing.T) {
 	}
 }
 
+func TestIdentity(t *testing.T) {
+	if got := Identity(context.Background()); got != "" {
+		t.Errorf("Identity without principal = %q, want empty", got)
+	}
+	ctx := context.WithValue(context.Background(), PrincipalContextKey{}, &Principal{ID: "svc"})
+	if got := Identity(ctx); got != "svc" {
+		t.Errorf("Identity = %q, want svc", got)
+	}
+	ctx = context.WithValue(context.Background(), PrincipalContextKey{}, &Principal{ID: "svc", OnBehalfOf: "alice"})
+	if got := Identity(ctx); got != "alice" {
+		t.Errorf("Identity on behalf of = %q, want alice", got)
+	}
+}
+
 func TestNoOpAuthenticator(t *testing.T) {
 	auth := NewNoOpAuthenticator()
 	ctx := context.Background()
@@ -0,0 +1,464 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package adapters
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	jose "github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// oidcDiscoveryPath is appended to the issuer to locate its metadata.
+	oidcDiscoveryPath = "/.well-known/openid-configuration"
+
+	// oidcDefaultRefreshInterval is how long a fetched key set is trusted.
+	oidcDefaultRefreshInterval = time.Hour
+
+	// oidcMinRefreshInterval rate-limits key set refetches, whether they are
+	// triggered by a stale key set or by a token signed with an unknown key
+	// ID, and whether the previous attempt succeeded or failed.
+	oidcMinRefreshInterval = time.Minute
+
+	// oidcDefaultClockSkew is the default leeway for exp, nbf and iat.
+	oidcDefaultClockSkew = time.Minute
+
+	// oidcMaxResponseSize bounds discovery and key set responses.
+	oidcMaxResponseSize = 1 << 20
+)
+
+// oidcDefaultAlgorithms are the signature algorithms accepted by default.
+var oidcDefaultAlgorithms = []jose.SignatureAlgorithm{
+	jose.RS256, jose.RS384, jose.RS512,
+	jose.PS256, jose.PS384, jose.PS512,
+	jose.ES256, jose.ES384, jose.ES512,
+	jose.EdDSA,
+}
+
+// OIDCConfig configures an OpenID Connect provider (Keycloak, Okta, Azure AD,
+// ...) whose tokens authenticate API requests.
+type OIDCConfig struct {
+	// Issuer is the provider's issuer URL; tokens must carry it in "iss"
+	// and provider metadata is discovered beneath it.
+	Issuer string
+
+	// Audiences lists the accepted "aud" values (API identifier or client
+	// IDs, including the admin UI's); a token must name at least one.
+	Audiences []string
+
+	// JWKSURL overrides the key set URL from discovery.
+	JWKSURL string
+
+	// RoleClaims lists the claims holding role or group names, as dotted
+	// paths into nested objects, e.g. "groups" (Okta, Azure AD) or
+	// "realm_access.roles" (Keycloak) (default: "groups").
+	RoleClaims []string
+
+	// RoleMapping maps claim values to objstore roles. When set, only mapped
+	// values grant roles; when empty, claim values are used as role names.
+	RoleMapping map[string][]string
+
+	// DefaultRoles are granted to every authenticated principal.
+	DefaultRoles []string
+
+	// NameClaim is the claim used for the principal name
+	// (default: "preferred_username", falling back to "email" and "sub").
+	NameClaim string
+
+	// TenantClaim, when set, populates Principal.TenantID (e.g. "tid").
+	TenantClaim string
+
+	// ClockSkew is the leeway for exp, nbf and iat checks (default: 1m).
+	ClockSkew time.Duration
+
+	// RefreshInterval is how long a fetched key set is trusted (default: 1h).
+	RefreshInterval time.Duration
+
+	// HTTPClient fetches discovery metadata and keys (default: 10s timeout).
+	HTTPClient *http.Client
+}
+
+// OIDCAuthenticator authenticates bearer tokens issued by an OpenID Connect
+// provider. Tokens must be signed by a key from the provider's JWKS, name the
+// configured issuer and one of the audiences, and be unexpired. Role claims
+// become Principal.Roles, so the provider's groups feed RBACAuthorizer.
+type OIDCAuthenticator struct {
+	config OIDCConfig
+	client *http.Client
+
+	mu          sync.Mutex
+	jwksURL     string
+	keys        *jose.JSONWebKeySet
+	fetchedAt   time.Time
+	attemptedAt time.Time     // start of the last refresh, successful or not
+	refreshErr  error         // why the last refresh failed, if it did
+	refreshing  chan struct{} // closed when the refresh in flight finishes
+
+	// now returns the current time; overridden in tests.
+	now func() time.Time
+}
+
+// NewOIDCAuthenticator creates an OIDC authenticator. Provider metadata and
+// keys are fetched lazily on the first request.
+func NewOIDCAuthenticator(config *OIDCConfig) (*OIDCAuthenticator, error) {
+	if config == nil || config.Issuer == "" {
+		return nil, errors.New("oidc: issuer is required")
+	}
+	if len(config.Audiences) == 0 {
+		return nil, errors.New("oidc: at least one audience is required")
+	}
+
+	cfg := *config
+	if len(cfg.RoleClaims) == 0 {
+		cfg.RoleClaims = []string{"groups"}
+	}
+	if cfg.NameClaim == "" {
+		cfg.NameClaim = "preferred_username"
+	}
+	if cfg.ClockSkew <= 0 {
+		cfg.ClockSkew = oidcDefaultClockSkew
+	}
+	if cfg.RefreshInterval <= 0 {
+		cfg.RefreshInterval = oidcDefaultRefreshInterval
+	}
+	client := cfg.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return &OIDCAuthenticator{
+		config:  cfg,
+		client:  client,
+		jwksURL: cfg.JWKSURL,
+		now:     time.Now,
+	}, nil
+}
+
+// AuthenticateHTTP authenticates using the bearer token in the Authorization header.
+func (a *OIDCAuthenticator) AuthenticateHTTP(ctx context.Context, req *http.Request) (*Principal, error) {
+	token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return nil, ErrMissingCredentials
+	}
+	return a.Authenticate(ctx, token)
+}
+
+// AuthenticateGRPC authenticates using the bearer token in the authorization metadata.
+func (a *OIDCAuthenticator) AuthenticateGRPC(ctx context.Context, md metadata.MD) (*Principal, error) {
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return nil, ErrMissingCredentials
+	}
+	token, ok := strings.CutPrefix(values[0], "Bearer ")
+	if !ok || token == "" {
+		return nil, ErrMissingCredentials
+	}
+	return a.Authenticate(ctx, token)
+}
+
+// AuthenticateMTLS is not supported for OIDC auth.
+func (a *OIDCAuthenticator) AuthenticateMTLS(ctx context.Context, state *tls.ConnectionState) (*Principal, error) {
+	return nil, ErrMTLSNotSupported
+}
+
+// Authenticate validates a raw OIDC token and returns its principal.
+func (a *OIDCAuthenticator) Authenticate(ctx context.Context, raw string) (*Principal, error) {
+	token, err := jwt.ParseSigned(raw, oidcDefaultAlgorithms)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed token: %v", ErrInvalidCredentials, err)
+	}
+	if len(token.Headers) != 1 {
+		return nil, fmt.Errorf("%w: token must have exactly one signature", ErrInvalidCredentials)
+	}
+
+	key, err := a.key(ctx, token.Headers[0].KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	var std jwt.Claims
+	var claims map[string]any
+	if err := token.Claims(key, &std, &claims); err != nil {
+		return nil, fmt.Errorf("%w: invalid token signature: %v", ErrInvalidCredentials, err)
+	}
+	if std.Expiry == nil {
+		return nil, fmt.Errorf("%w: token has no expiry", ErrInvalidCredentials)
+	}
+	if err := std.ValidateWithLeeway(jwt.Expected{
+		Issuer:      a.config.Issuer,
+		AnyAudience: jwt.Audience(a.config.Audiences),
+		Time:        a.now(),
+	}, a.config.ClockSkew); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCredentials, err)
+	}
+	if std.Subject == "" {
+		return nil, fmt.Errorf("%w: token has no subject", ErrInvalidCredentials)
+	}
+
+	return a.principal(std, claims), nil
+}
+
+// principal builds the principal for validated claims.
+func (a *OIDCAuthenticator) principal(std jwt.Claims, claims map[string]any) *Principal {
+	name := std.Subject
+	for _, claim := range []string{a.config.NameClaim, "email"} {
+		if v, ok := claimValue(claims, claim).(string); ok && v != "" {
+			name = v
+			break
+		}
+	}
+
+	attributes := map[string]any{"issuer": std.Issuer}
+	if email, ok := claims["email"].(string); ok {
+		attributes["email"] = email
+	}
+
+	principal := &Principal{
+		ID:         std.Subject,
+		Name:       name,
+		Type:       "user",
+		Roles:      a.roles(claims),
+		Attributes: attributes,
+	}
+	if a.config.TenantClaim != "" {
+		principal.TenantID, _ = claimValue(claims, a.config.TenantClaim).(string)
+	}
+	return principal
+}
+
+// roles maps the configured role claims to objstore roles.
+func (a *OIDCAuthenticator) roles(claims map[string]any) []string {
+	seen := make(map[string]struct{})
+	var roles []string
+	add := func(role string) {
+		if _, ok := seen[role]; !ok && role != "" {
+			seen[role] = struct{}{}
+			roles = append(roles, role)
+		}
+	}
+
+	for _, claim := range a.config.RoleClaims {
+		for _, value := range claimStrings(claimValue(claims, claim)) {
+			if len(a.config.RoleMapping) == 0 {
+				add(value)
+				continue
+			}
+			for _, role := range a.config.RoleMapping[value] {
+				add(role)
+			}
+		}
+	}
+	for _, role := range a.config.DefaultRoles {
+		add(role)
+	}
+	return roles
+}
+
+// claimValue resolves a dotted claim path such as "realm_access.roles".
+func claimValue(claims map[string]any, path string) any {
+	var value any = claims
+	for _, part := range strings.Split(path, ".") {
+		m, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = m[part]
+	}
+	return value
+}
+
+// claimStrings returns the string values of a claim holding a string or a
+// list of strings.
+func claimStrings(value any) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// key returns the verification key for kid, refreshing the key set when it
+// is stale or does not contain kid. Refreshes are rate-limited to one per
+// oidcMinRefreshInterval, so tokens with made-up key IDs cannot flood the
+// provider, and a failed refresh keeps the cached keys in use. Requests
+// whose key is cached never wait for a refresh.
+func (a *OIDCAuthenticator) key(ctx context.Context, kid string) (*jose.JSONWebKey, error) {
+	a.mu.Lock()
+	for {
+		now := a.now()
+		cached := findKey(a.keys, kid)
+		stale := a.keys == nil || now.Sub(a.fetchedAt) > a.config.RefreshInterval
+		if cached != nil && (!stale || a.refreshing != nil) {
+			a.mu.Unlock()
+			return cached, nil
+		}
+
+		if done := a.refreshing; done != nil {
+			// Wait for the refresh in flight rather than starting another.
+			a.mu.Unlock()
+			select {
+			case <-done:
+			case <-ctx.Done():
+				return nil, fmt.Errorf("%w: %v", ErrUnauthorized, ctx.Err())
+			}
+			a.mu.Lock()
+			if key := findKey(a.keys, kid); key != nil || a.keys == nil {
+				a.mu.Unlock()
+				return a.keyResult(key, kid)
+			}
+			continue
+		}
+
+		if !a.attemptedAt.IsZero() && now.Sub(a.attemptedAt) < oidcMinRefreshInterval {
+			a.mu.Unlock()
+			return a.keyResult(cached, kid)
+		}
+
+		done := make(chan struct{})
+		a.refreshing, a.attemptedAt = done, now
+		jwksURL := a.jwksURL
+		a.mu.Unlock()
+
+		// The refresh outlives a canceled request: its result serves every
+		// request waiting for it.
+		refreshCtx := context.WithoutCancel(ctx)
+		if cached != nil {
+			go a.refresh(refreshCtx, jwksURL, now, done)
+			return cached, nil
+		}
+		a.refresh(refreshCtx, jwksURL, now, done)
+		a.mu.Lock()
+	}
+}
+
+// refresh fetches the key set and, on success, replaces the cached one. It
+// closes done once finished.
+func (a *OIDCAuthenticator) refresh(ctx context.Context, jwksURL string, now time.Time, done chan struct{}) {
+	keys, jwksURL, err := a.fetchKeys(ctx, jwksURL)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.refreshing, a.refreshErr = nil, err
+	if err == nil {
+		a.jwksURL, a.keys, a.fetchedAt = jwksURL, keys, now
+	}
+	close(done)
+}
+
+// keyResult returns key, or the error explaining why no key for kid is
+// available: the failed refresh when no key set was ever fetched, otherwise
+// an unknown key.
+func (a *OIDCAuthenticator) keyResult(key *jose.JSONWebKey, kid string) (*jose.JSONWebKey, error) {
+	if key != nil {
+		return key, nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.keys == nil && a.refreshErr != nil {
+		return nil, a.refreshErr
+	}
+	return nil, fmt.Errorf("%w: token signed with unknown key %q", ErrInvalidCredentials, kid)
+}
+
+// findKey returns the signing key for kid, or the only signing key when kid
+// is empty.
+func findKey(set *jose.JSONWebKeySet, kid string) *jose.JSONWebKey {
+	if set == nil {
+		return nil
+	}
+	var match *jose.JSONWebKey
+	for i := range set.Keys {
+		key := &set.Keys[i]
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+		if kid != "" && key.KeyID == kid {
+			return key
+		}
+		if kid == "" {
+			if match != nil {
+				return nil
+			}
+			match = key
+		}
+	}
+	return match
+}
+
+// fetchKeys fetches the key set from jwksURL, discovering the URL first
+// when it is empty, and returns the keys and the URL they came from.
+func (a *OIDCAuthenticator) fetchKeys(ctx context.Context, jwksURL string) (*jose.JSONWebKeySet, string, error) {
+	if jwksURL == "" {
+		var discovery struct {
+			Issuer  string `json:"issuer"`
+			JWKSURI string `json:"jwks_uri"`
+		}
+		if err := a.getJSON(ctx, strings.TrimSuffix(a.config.Issuer, "/")+oidcDiscoveryPath, &discovery); err != nil {
+			return nil, "", err
+		}
+		if discovery.Issuer != a.config.Issuer {
+			return nil, "", fmt.Errorf("%w: discovery issuer %q does not match %q", ErrUnauthorized, discovery.Issuer, a.config.Issuer)
+		}
+		if discovery.JWKSURI == "" {
+			return nil, "", fmt.Errorf("%w: provider metadata has no jwks_uri", ErrUnauthorized)
+		}
+		jwksURL = discovery.JWKSURI
+	}
+
+	var keys jose.JSONWebKeySet
+	if err := a.getJSON(ctx, jwksURL, &keys); err != nil {
+		return nil, "", err
+	}
+	return &keys, jwksURL, nil
+}
+
+// getJSON fetches url and decodes the JSON response into dest.
+func (a *OIDCAuthenticator) getJSON(ctx context.Context, url string, dest any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnauthorized, err)
+	}
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: fetching %s: %v", ErrUnauthorized, url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: fetching %s: status %d", ErrUnauthorized, url, resp.StatusCode)
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, oidcMaxResponseSize)).Decode(dest); err != nil {
+		return fmt.Errorf("%w: decoding %s: %v", ErrUnauthorized, url, err)
+	}
+	return nil
+}
@@ -0,0 +1,395 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package adapters
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	jose "github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"google.golang.org/grpc/metadata"
+)
+
+// testOIDCProvider is an httptest OIDC provider serving discovery metadata
+// and a JWKS, and minting tokens signed by its current key.
+type testOIDCProvider struct {
+	server    *httptest.Server
+	key       any
+	kid       string
+	alg       jose.SignatureAlgorithm
+	keys      jose.JSONWebKeySet
+	jwksFetch atomic.Int32
+	jwksDown  atomic.Bool
+}
+
+func newTestOIDCProvider(t *testing.T) *testOIDCProvider {
+	t.Helper()
+	p := &testOIDCProvider{}
+	p.rotate(t, "key-1")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(oidcDiscoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":   p.server.URL,
+			"jwks_uri": p.server.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		p.jwksFetch.Add(1)
+		if p.jwksDown.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(p.keys)
+	})
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+	return p
+}
+
+// rotate replaces the signing key with a new RSA key published under kid.
+func (p *testOIDCProvider) rotate(t *testing.T, kid string) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.key, p.kid, p.alg = key, kid, jose.RS256
+	p.keys.Keys = append(p.keys.Keys, jose.JSONWebKey{Key: &key.PublicKey, KeyID: kid, Algorithm: string(jose.RS256), Use: "sig"})
+}
+
+func (p *testOIDCProvider) token(t *testing.T, std jwt.Claims, extra map[string]any) string {
+	t.Helper()
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: p.alg, Key: jose.JSONWebKey{Key: p.key, KeyID: p.kid}},
+		(&jose.SignerOptions{}).WithType("JWT"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := jwt.Signed(signer).Claims(std).Claims(extra).Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+func (p *testOIDCProvider) claims(audience string) jwt.Claims {
+	now := time.Now()
+	return jwt.Claims{
+		Issuer:   p.server.URL,
+		Subject:  "user-1",
+		Audience: jwt.Audience{audience},
+		IssuedAt: jwt.NewNumericDate(now),
+		Expiry:   jwt.NewNumericDate(now.Add(time.Hour)),
+	}
+}
+
+func TestNewOIDCAuthenticator_Validation(t *testing.T) {
+	if _, err := NewOIDCAuthenticator(nil); err == nil {
+		t.Error("expected error for nil config")
+	}
+	if _, err := NewOIDCAuthenticator(&OIDCConfig{Audiences: []string{"api"}}); err == nil {
+		t.Error("expected error without issuer")
+	}
+	if _, err := NewOIDCAuthenticator(&OIDCConfig{Issuer: "https://idp"}); err == nil {
+		t.Error("expected error without audience")
+	}
+}
+
+func TestOIDCAuthenticator_Authenticate(t *testing.T) {
+	p := newTestOIDCProvider(t)
+	auth, err := NewOIDCAuthenticator(&OIDCConfig{
+		Issuer:      p.server.URL,
+		Audiences:   []string{"objstore-api", "objstore-ui"},
+		RoleClaims:  []string{"groups", "realm_access.roles"},
+		TenantClaim: "tid",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token := p.token(t, p.claims("objstore-ui"), map[string]any{
+		"preferred_username": "alice",
+		"email":              "alice@example.com",
+		"groups":             []string{"editors"},
+		"realm_access":       map[string]any{"roles": []string{"admin", "editors"}},
+		"tid":                "tenant-a",
+	})
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/objects", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	principal, err := auth.AuthenticateHTTP(context.Background(), req)
+	if err != nil {
+		t.Fatalf("AuthenticateHTTP() error = %v", err)
+	}
+	if principal.ID != "user-1" || principal.Name != "alice" || principal.TenantID != "tenant-a" {
+		t.Errorf("principal = %+v", principal)
+	}
+	if want := []string{"editors", "admin"}; !reflect.DeepEqual(principal.Roles, want) {
+		t.Errorf("Roles = %v, want %v", principal.Roles, want)
+	}
+	if principal.Attributes["email"] != "alice@example.com" {
+		t.Errorf("email attribute = %v", principal.Attributes["email"])
+	}
+
+	md := metadata.Pairs("authorization", "Bearer "+token)
+	if _, err := auth.AuthenticateGRPC(context.Background(), md); err != nil {
+		t.Errorf("AuthenticateGRPC() error = %v", err)
+	}
+}
+
+func TestOIDCAuthenticator_RoleMapping(t *testing.T) {
+	p := newTestOIDCProvider(t)
+	auth, err := NewOIDCAuthenticator(&OIDCConfig{
+		Issuer:    p.server.URL,
+		Audiences: []string{"api"},
+		RoleMapping: map[string][]string{
+			"8d3f-storage-admins": {"admin"},
+			"8d3f-storage-users":  {"reader", "editor"},
+		},
+		DefaultRoles: []string{"reader"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token := p.token(t, p.claims("api"), map[string]any{
+		"groups": []string{"8d3f-storage-users", "unmapped-group"},
+	})
+	principal, err := auth.Authenticate(context.Background(), token)
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	roles := append([]string(nil), principal.Roles...)
+	sort.Strings(roles)
+	if want := []string{"editor", "reader"}; !reflect.DeepEqual(roles, want) {
+		t.Errorf("Roles = %v, want %v", roles, want)
+	}
+	if principal.Name != "user-1" {
+		t.Errorf("Name = %q, want subject fallback", principal.Name)
+	}
+
+	// Mapped roles feed the RBAC authorizer.
+	authz := NewRBACAuthorizer(map[string][]string{"editor": {ActionWrite}})
+	if err := authz.Authorize(context.Background(), principal, ActionWrite, "k"); err != nil {
+		t.Errorf("Authorize(write) error = %v", err)
+	}
+	if err := authz.Authorize(context.Background(), principal, ActionAdmin, ResourcePolicy); err == nil {
+		t.Error("Authorize(admin) succeeded for unmapped group")
+	}
+}
+
+func TestOIDCAuthenticator_Rejects(t *testing.T) {
+	p := newTestOIDCProvider(t)
+	auth, err := NewOIDCAuthenticator(&OIDCConfig{Issuer: p.server.URL, Audiences: []string{"api"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other := newTestOIDCProvider(t)
+	expired := p.claims("api")
+	expired.Expiry = jwt.NewNumericDate(time.Now().Add(-time.Hour))
+	noExpiry := p.claims("api")
+	noExpiry.Expiry = nil
+	wrongIssuer := p.claims("api")
+	wrongIssuer.Issuer = "https://evil.example"
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"malformed", "not-a-jwt"},
+		{"wrong audience", p.token(t, p.claims("other"), nil)},
+		{"expired", p.token(t, expired, nil)},
+		{"no expiry", p.token(t, noExpiry, nil)},
+		{"wrong issuer", p.token(t, wrongIssuer, nil)},
+		{"foreign key", other.token(t, p.claims("api"), nil)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := auth.Authenticate(context.Background(), tt.token); !errors.Is(err, ErrInvalidCredentials) {
+				t.Errorf("Authenticate() error = %v, want %v", err, ErrInvalidCredentials)
+			}
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if _, err := auth.AuthenticateHTTP(context.Background(), req); !errors.Is(err, ErrMissingCredentials) {
+		t.Errorf("AuthenticateHTTP() without token error = %v, want %v", err, ErrMissingCredentials)
+	}
+	if _, err := auth.AuthenticateMTLS(context.Background(), nil); !errors.Is(err, ErrMTLSNotSupported) {
+		t.Errorf("AuthenticateMTLS() error = %v, want %v", err, ErrMTLSNotSupported)
+	}
+}
+
+func TestOIDCAuthenticator_KeyRotation(t *testing.T) {
+	p := newTestOIDCProvider(t)
+	auth, err := NewOIDCAuthenticator(&OIDCConfig{Issuer: p.server.URL, Audiences: []string{"api"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	auth.now = func() time.Time { return now }
+
+	if _, err := auth.Authenticate(context.Background(), p.token(t, p.claims("api"), nil)); err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if _, err := auth.Authenticate(context.Background(), p.token(t, p.claims("api"), nil)); err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if got := p.jwksFetch.Load(); got != 1 {
+		t.Errorf("JWKS fetched %d times, want 1 (cached)", got)
+	}
+
+	// A token from a rotated key is rejected until the refetch rate limit
+	// allows the key set to be refreshed.
+	p.rotate(t, "key-2")
+	rotated := p.token(t, p.claims("api"), nil)
+	if _, err := auth.Authenticate(context.Background(), rotated); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Authenticate() with new key before refresh window error = %v", err)
+	}
+	now = now.Add(2 * oidcMinRefreshInterval)
+	if _, err := auth.Authenticate(context.Background(), rotated); err != nil {
+		t.Errorf("Authenticate() after rotation error = %v", err)
+	}
+	if got := p.jwksFetch.Load(); got != 2 {
+		t.Errorf("JWKS fetched %d times, want 2", got)
+	}
+}
+
+// waitForRefresh waits until no key set refresh is in flight.
+func waitForRefresh(t *testing.T, auth *OIDCAuthenticator) {
+	t.Helper()
+	for range 1000 {
+		auth.mu.Lock()
+		done := auth.refreshing == nil
+		auth.mu.Unlock()
+		if done {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("key set refresh did not finish")
+}
+
+func TestOIDCAuthenticator_RefreshRateLimit(t *testing.T) {
+	p := newTestOIDCProvider(t)
+	auth, err := NewOIDCAuthenticator(&OIDCConfig{Issuer: p.server.URL, Audiences: []string{"api"}, RefreshInterval: 10 * time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	auth.now = func() time.Time { return now }
+
+	valid := p.token(t, p.claims("api"), nil)
+	if _, err := auth.Authenticate(context.Background(), valid); err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+
+	// Tokens naming unknown keys trigger at most one refetch per interval.
+	now = now.Add(2 * oidcMinRefreshInterval)
+	p.rotate(t, "unknown")
+	forged := p.token(t, p.claims("api"), nil)
+	p.keys.Keys = p.keys.Keys[:1]
+	for range 10 {
+		if _, err := auth.Authenticate(context.Background(), forged); !errors.Is(err, ErrInvalidCredentials) {
+			t.Errorf("Authenticate() with unknown key error = %v", err)
+		}
+	}
+	if got := p.jwksFetch.Load(); got != 2 {
+		t.Errorf("JWKS fetched %d times, want 2", got)
+	}
+
+	// While the provider is down, the cached keys keep serving past the
+	// refresh interval and failed refreshes are rate-limited too.
+	p.jwksDown.Store(true)
+	now = now.Add(20 * time.Minute)
+	for range 10 {
+		if _, err := auth.Authenticate(context.Background(), valid); err != nil {
+			t.Errorf("Authenticate() with cached key during outage error = %v", err)
+		}
+		waitForRefresh(t, auth)
+	}
+	if got := p.jwksFetch.Load(); got != 3 {
+		t.Errorf("JWKS fetched %d times during outage, want 3", got)
+	}
+
+	// Once the provider recovers, the next interval refreshes the keys.
+	p.jwksDown.Store(false)
+	now = now.Add(2 * oidcMinRefreshInterval)
+	if _, err := auth.Authenticate(context.Background(), valid); err != nil {
+		t.Errorf("Authenticate() after recovery error = %v", err)
+	}
+	waitForRefresh(t, auth)
+	if got := p.jwksFetch.Load(); got != 4 {
+		t.Errorf("JWKS fetched %d times after recovery, want 4", got)
+	}
+	auth.mu.Lock()
+	fetchedAt := auth.fetchedAt
+	auth.mu.Unlock()
+	if !fetchedAt.Equal(now) {
+		t.Errorf("key set fetched at %v, want %v", fetchedAt, now)
+	}
+}
+
+func TestOIDCAuthenticator_ProviderDown(t *testing.T) {
+	p := newTestOIDCProvider(t)
+	p.jwksDown.Store(true)
+	auth, err := NewOIDCAuthenticator(&OIDCConfig{Issuer: p.server.URL, Audiences: []string{"api"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token := p.token(t, p.claims("api"), nil)
+	for range 5 {
+		if _, err := auth.Authenticate(context.Background(), token); !errors.Is(err, ErrUnauthorized) {
+			t.Errorf("Authenticate() with provider down error = %v, want %v", err, ErrUnauthorized)
+		}
+	}
+	if got := p.jwksFetch.Load(); got != 1 {
+		t.Errorf("JWKS fetched %d times, want 1 (rate-limited)", got)
+	}
+}
+
+func TestOIDCAuthenticator_ECKeyWithoutKID(t *testing.T) {
+	p := newTestOIDCProvider(t)
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.key, p.kid, p.alg = key, "", jose.ES256
+	p.keys.Keys = []jose.JSONWebKey{{Key: &key.PublicKey, Algorithm: string(jose.ES256), Use: "sig"}}
+
+	auth, err := NewOIDCAuthenticator(&OIDCConfig{Issuer: p.server.URL, Audiences: []string{"api"}, JWKSURL: p.server.URL + "/keys"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := auth.Authenticate(context.Background(), p.token(t, p.claims("api"), nil)); err != nil {
+		t.Errorf("Authenticate() error = %v", err)
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package adapters
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// OnBehalfOfHeader is the HTTP header naming the end user a privileged
+	// service is acting for.
+	OnBehalfOfHeader = "X-On-Behalf-Of"
+
+	// OnBehalfOfMetadataKey is the gRPC metadata key equivalent of
+	// OnBehalfOfHeader.
+	OnBehalfOfMetadataKey = "x-on-behalf-of"
+
+	// ActionImpersonate is the action a principal must be granted to act on
+	// behalf of another identity. The resource is that identity.
+	ActionImpersonate = "impersonate"
+
+	// maxOnBehalfOfLength bounds the identity recorded in logs.
+	maxOnBehalfOfLength = 256
+)
+
+// OnBehalfOfAuthenticator lets privileged services, such as multi-tenant
+// frontends proxying through objstore, name the end user each request is made
+// for. It wraps another authenticator: the caller is authenticated as usual,
+// and when the request carries OnBehalfOfHeader (or OnBehalfOfMetadataKey on
+// gRPC) the authorizer must grant the caller ActionImpersonate on the named
+// identity. The identity is then recorded in Principal.OnBehalfOf, which audit
+// and access logs report alongside the caller.
+//
+// Authorization of the request itself is unchanged: it is still evaluated
+// for the calling service, which remains accountable for what it does on the
+// end user's behalf.
+type OnBehalfOfAuthenticator struct {
+	next       Authenticator
+	authorizer Authorizer
+}
+
+// NewOnBehalfOfAuthenticator wraps next with on-behalf-of support. authorizer
+// decides which principals may impersonate which identities; with
+// RBACAuthorizer, grant "impersonate" to service roles.
+func NewOnBehalfOfAuthenticator(next Authenticator, authorizer Authorizer) *OnBehalfOfAuthenticator {
+	return &OnBehalfOfAuthenticator{next: next, authorizer: authorizer}
+}
+
+// AuthenticateHTTP authenticates the request and applies its X-On-Behalf-Of header.
+func (a *OnBehalfOfAuthenticator) AuthenticateHTTP(ctx context.Context, req *http.Request) (*Principal, error) {
+	principal, err := a.next.AuthenticateHTTP(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return a.actAs(ctx, principal, req.Header.Values(OnBehalfOfHeader))
+}
+
+// AuthenticateGRPC authenticates the call and applies its x-on-behalf-of metadata.
+func (a *OnBehalfOfAuthenticator) AuthenticateGRPC(ctx context.Context, md metadata.MD) (*Principal, error) {
+	principal, err := a.next.AuthenticateGRPC(ctx, md)
+	if err != nil {
+		return nil, err
+	}
+	return a.actAs(ctx, principal, md.Get(OnBehalfOfMetadataKey))
+}
+
+// AuthenticateMTLS authenticates the certificate and applies the
+// x-on-behalf-of metadata of the incoming gRPC call, if any.
+func (a *OnBehalfOfAuthenticator) AuthenticateMTLS(ctx context.Context, state *tls.ConnectionState) (*Principal, error) {
+	principal, err := a.next.AuthenticateMTLS(ctx, state)
+	if err != nil {
+		return nil, err
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	return a.actAs(ctx, principal, md.Get(OnBehalfOfMetadataKey))
+}
+
+// actAs validates the requested identity and returns a copy of principal
+// acting on its behalf. Requests without one return principal unchanged.
+func (a *OnBehalfOfAuthenticator) actAs(ctx context.Context, principal *Principal, values []string) (*Principal, error) {
+	if len(values) == 0 {
+		return principal, nil
+	}
+	if len(values) > 1 {
+		return nil, fmt.Errorf("%w: multiple %s values", ErrInvalidCredentials, OnBehalfOfHeader)
+	}
+	identity := strings.TrimSpace(values[0])
+	if err := validateOnBehalfOf(identity); err != nil {
+		return nil, err
+	}
+	if principal.Scope != nil {
+		return nil, fmt.Errorf("%w: scoped tokens cannot act on behalf of others", ErrInsufficientPermissions)
+	}
+	if err := a.authorizer.Authorize(ctx, principal, ActionImpersonate, identity); err != nil {
+		return nil, fmt.Errorf("%w: %s may not act on behalf of %q", ErrInsufficientPermissions, principal.ID, identity)
+	}
+
+	acting := *principal
+	acting.OnBehalfOf = identity
+	return &acting, nil
+}
+
+// validateOnBehalfOf rejects identities that are empty, oversized or contain
+// control characters, which would otherwise be written verbatim to logs.
+func validateOnBehalfOf(identity string) error {
+	if identity == "" {
+		return fmt.Errorf("%w: empty %s", ErrInvalidCredentials, OnBehalfOfHeader)
+	}
+	if len(identity) > maxOnBehalfOfLength {
+		return fmt.Errorf("%w: %s exceeds %d bytes", ErrInvalidCredentials, OnBehalfOfHeader, maxOnBehalfOfLength)
+	}
+	if strings.IndexFunc(identity, unicode.IsControl) >= 0 {
+		return fmt.Errorf("%w: %s contains control characters", ErrInvalidCredentials, OnBehalfOfHeader)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package adapters
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// staticAuthenticator authenticates every request as principal.
+type staticAuthenticator struct {
+	principal *Principal
+}
+
+func (a staticAuthenticator) AuthenticateHTTP(context.Context, *http.Request) (*Principal, error) {
+	return a.principal, nil
+}
+
+func (a staticAuthenticator) AuthenticateGRPC(context.Context, metadata.MD) (*Principal, error) {
+	return a.principal, nil
+}
+
+func (a staticAuthenticator) AuthenticateMTLS(context.Context, *tls.ConnectionState) (*Principal, error) {
+	return a.principal, nil
+}
+
+func newOnBehalfOfTest(principal *Principal) *OnBehalfOfAuthenticator {
+	return NewOnBehalfOfAuthenticator(staticAuthenticator{principal},
+		NewRBACAuthorizer(map[string][]string{"frontend": {ActionRead, ActionImpersonate}}))
+}
+
+func onBehalfOfRequest(values ...string) *http.Request {
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	for _, v := range values {
+		req.Header.Add(OnBehalfOfHeader, v)
+	}
+	return req
+}
+
+func TestOnBehalfOfAuthenticator_HTTP(t *testing.T) {
+	service := &Principal{ID: "svc", Roles: []string{"frontend"}}
+	auth := newOnBehalfOfTest(service)
+
+	principal, err := auth.AuthenticateHTTP(context.Background(), onBehalfOfRequest("alice@example.com"))
+	if err != nil {
+		t.Fatalf("AuthenticateHTTP: %v", err)
+	}
+	if principal.ID != "svc" || principal.OnBehalfOf != "alice@example.com" {
+		t.Errorf("principal = %s on behalf of %q, want svc on behalf of alice@example.com", principal.ID, principal.OnBehalfOf)
+	}
+	if service.OnBehalfOf != "" {
+		t.Error("authenticated principal was modified in place")
+	}
+
+	principal, err = auth.AuthenticateHTTP(context.Background(), onBehalfOfRequest())
+	if err != nil || principal != service {
+		t.Errorf("without header = %v, %v; want the unchanged principal", principal, err)
+	}
+}
+
+func TestOnBehalfOfAuthenticator_Rejects(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal *Principal
+		values    []string
+		want      error
+	}{
+		{"not permitted", &Principal{ID: "reader", Roles: []string{"reader"}}, []string{"alice"}, ErrInsufficientPermissions},
+		{"scoped token", &Principal{ID: "svc", Roles: []string{"frontend"}, Scope: &TokenScope{Actions: []string{ActionRead}}}, []string{"alice"}, ErrInsufficientPermissions},
+		{"empty", &Principal{ID: "svc", Roles: []string{"frontend"}}, []string{" "}, ErrInvalidCredentials},
+		{"control characters", &Principal{ID: "svc", Roles: []string{"frontend"}}, []string{"alice\nforged=1"}, ErrInvalidCredentials},
+		{"too long", &Principal{ID: "svc", Roles: []string{"frontend"}}, []string{strings.Repeat("a", 257)}, ErrInvalidCredentials},
+		{"repeated", &Principal{ID: "svc", Roles: []string{"frontend"}}, []string{"alice", "bob"}, ErrInvalidCredentials},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newOnBehalfOfTest(tt.principal).AuthenticateHTTP(context.Background(), onBehalfOfRequest(tt.values...))
+			if !errors.Is(err, tt.want) {
+				t.Errorf("AuthenticateHTTP error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestOnBehalfOfAuthenticator_GRPC(t *testing.T) {
+	auth := newOnBehalfOfTest(&Principal{ID: "svc", Roles: []string{"frontend"}})
+	md := metadata.Pairs(OnBehalfOfMetadataKey, "alice")
+
+	principal, err := auth.AuthenticateGRPC(context.Background(), md)
+	if err != nil || principal.OnBehalfOf != "alice" {
+		t.Errorf("AuthenticateGRPC = %v, %v; want on behalf of alice", principal, err)
+	}
+
+	// mTLS authentication reads the identity from the incoming call metadata.
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+	principal, err = auth.AuthenticateMTLS(ctx, &tls.ConnectionState{})
+	if err != nil || principal.OnBehalfOf != "alice" {
+		t.Errorf("AuthenticateMTLS = %v, %v; want on behalf of alice", principal, err)
+	}
+}
@@ -0,0 +1,319 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package adapters
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// POST policy condition operators.
+const (
+	// PostPolicyEq requires a form field to equal a value.
+	PostPolicyEq = "eq"
+
+	// PostPolicyStartsWith requires a form field to start with a value; an
+	// empty value allows anything.
+	PostPolicyStartsWith = "starts-with"
+
+	// PostPolicyContentLengthRange bounds the size of the uploaded file.
+	PostPolicyContentLengthRange = "content-length-range"
+)
+
+// POST policy form fields.
+const (
+	postPolicyField     = "policy"
+	postAlgorithmField  = "x-amz-algorithm"
+	postCredentialField = "x-amz-credential"
+	postDateField       = "x-amz-date"
+	postSignatureField  = "x-amz-signature"
+	postFileField       = "file"
+	postIgnorePrefix    = "x-ignore-"
+
+	// postBucketField names the bucket in S3 policies. Buckets have no
+	// counterpart here, so conditions on it are ignored.
+	postBucketField = "bucket"
+)
+
+// ErrPostPolicyViolation is returned when a form upload does not meet its
+// POST policy.
+var ErrPostPolicyViolation = errors.New("form upload does not meet its POST policy")
+
+// PostPolicy is an S3-style browser upload policy: the conditions a form
+// upload must meet, signed by a SigV4 key holder and handed to a browser,
+// which then uploads directly with the signed form fields.
+type PostPolicy struct {
+	// Expiration is when the policy stops being accepted.
+	Expiration time.Time
+
+	// Conditions are the rules the form fields and file must meet.
+	Conditions []PostPolicyCondition
+}
+
+// PostPolicyCondition is one rule of a POST policy.
+type PostPolicyCondition struct {
+	// Op is PostPolicyEq, PostPolicyStartsWith or
+	// PostPolicyContentLengthRange.
+	Op string
+
+	// Field is the lower-case form field name, without S3's "$" prefix.
+	Field string
+
+	// Value is the value to match.
+	Value string
+
+	// Min and Max bound the file size of a content-length-range condition.
+	Min, Max int64
+}
+
+// PostPolicyEquals returns a condition requiring field to equal value.
+func PostPolicyEquals(field, value string) PostPolicyCondition {
+	return PostPolicyCondition{Op: PostPolicyEq, Field: strings.ToLower(field), Value: value}
+}
+
+// PostPolicyPrefix returns a condition requiring field to start with prefix.
+func PostPolicyPrefix(field, prefix string) PostPolicyCondition {
+	return PostPolicyCondition{Op: PostPolicyStartsWith, Field: strings.ToLower(field), Value: prefix}
+}
+
+// PostPolicySize returns a condition bounding the file size to [min, max]
+// bytes.
+func PostPolicySize(minSize, maxSize int64) PostPolicyCondition {
+	return PostPolicyCondition{Op: PostPolicyContentLengthRange, Min: minSize, Max: maxSize}
+}
+
+// postPolicyJSON is the wire form of a PostPolicy.
+type postPolicyJSON struct {
+	Expiration string            `json:"expiration"`
+	Conditions []json.RawMessage `json:"conditions"`
+}
+
+// MarshalJSON encodes the policy in S3's policy document format.
+func (p PostPolicy) MarshalJSON() ([]byte, error) {
+	doc := postPolicyJSON{Expiration: p.Expiration.UTC().Format(time.RFC3339)}
+	for _, c := range p.Conditions {
+		var item any
+		switch c.Op {
+		case PostPolicyEq, PostPolicyStartsWith:
+			item = []string{c.Op, "$" + c.Field, c.Value}
+		case PostPolicyContentLengthRange:
+			item = []any{c.Op, c.Min, c.Max}
+		default:
+			return nil, fmt.Errorf("%w: unsupported condition %q", ErrInvalidCredentials, c.Op)
+		}
+		raw, err := json.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+		doc.Conditions = append(doc.Conditions, raw)
+	}
+	return json.Marshal(doc)
+}
+
+// UnmarshalJSON decodes an S3 policy document. Conditions may be objects
+// ({"key": "uploads/a.png"}) or arrays (["starts-with", "$key", "uploads/"]).
+func (p *PostPolicy) UnmarshalJSON(data []byte) error {
+	var doc postPolicyJSON
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	expiration, err := time.Parse(time.RFC3339, doc.Expiration)
+	if err != nil {
+		return fmt.Errorf("invalid policy expiration %q", doc.Expiration)
+	}
+	p.Expiration = expiration
+	p.Conditions = p.Conditions[:0]
+	for _, raw := range doc.Conditions {
+		var exact map[string]string
+		if err := json.Unmarshal(raw, &exact); err == nil {
+			for field, value := range exact {
+				p.Conditions = append(p.Conditions, PostPolicyEquals(strings.TrimPrefix(field, "$"), value))
+			}
+			continue
+		}
+		var items []any
+		if err := json.Unmarshal(raw, &items); err != nil || len(items) != 3 {
+			return fmt.Errorf("invalid policy condition %s", raw)
+		}
+		op, _ := items[0].(string)
+		switch op = strings.ToLower(op); op {
+		case PostPolicyEq, PostPolicyStartsWith:
+			field, ok1 := items[1].(string)
+			value, ok2 := items[2].(string)
+			if !ok1 || !ok2 || !strings.HasPrefix(field, "$") {
+				return fmt.Errorf("invalid policy condition %s", raw)
+			}
+			p.Conditions = append(p.Conditions, PostPolicyCondition{Op: op, Field: strings.ToLower(field[1:]), Value: value})
+		case PostPolicyContentLengthRange:
+			minSize, err1 := policyInt(items[1])
+			maxSize, err2 := policyInt(items[2])
+			if err1 != nil || err2 != nil || minSize < 0 || maxSize < minSize {
+				return fmt.Errorf("invalid policy condition %s", raw)
+			}
+			p.Conditions = append(p.Conditions, PostPolicySize(minSize, maxSize))
+		default:
+			return fmt.Errorf("unsupported policy condition %s", raw)
+		}
+	}
+	return nil
+}
+
+// policyInt reads a size bound, which policies write as a number or a
+// string.
+func policyInt(v any) (int64, error) {
+	switch n := v.(type) {
+	case float64:
+		return int64(n), nil
+	case string:
+		return strconv.ParseInt(n, 10, 64)
+	default:
+		return 0, fmt.Errorf("invalid size %v", v)
+	}
+}
+
+// Check reports whether the form fields, keyed by lower-case name, meet the
+// policy. Every condition must hold, and every field must be named by a
+// condition, except the policy and signature themselves, the file and
+// fields starting with "x-ignore-". Conditions on the S3 bucket are
+// ignored. The file size is checked separately against SizeRange.
+func (p *PostPolicy) Check(fields map[string]string) error {
+	covered := make(map[string]bool, len(p.Conditions))
+	for _, c := range p.Conditions {
+		if c.Op == PostPolicyContentLengthRange || c.Field == postBucketField {
+			continue
+		}
+		covered[c.Field] = true
+		value := fields[c.Field]
+		if c.Op == PostPolicyEq && value != c.Value {
+			return fmt.Errorf("%w: %s must be %q", ErrPostPolicyViolation, c.Field, c.Value)
+		}
+		if c.Op == PostPolicyStartsWith && !strings.HasPrefix(value, c.Value) {
+			return fmt.Errorf("%w: %s must start with %q", ErrPostPolicyViolation, c.Field, c.Value)
+		}
+	}
+	for name := range fields {
+		switch {
+		case name == postPolicyField, name == postSignatureField, name == postFileField,
+			strings.HasPrefix(name, postIgnorePrefix), covered[name]:
+		default:
+			return fmt.Errorf("%w: field %s is not allowed by the policy", ErrPostPolicyViolation, name)
+		}
+	}
+	return nil
+}
+
+// SizeRange returns the bounds of the policy's content-length-range
+// conditions, intersected, and whether it has any.
+func (p *PostPolicy) SizeRange() (minSize, maxSize int64, ok bool) {
+	for _, c := range p.Conditions {
+		if c.Op != PostPolicyContentLengthRange {
+			continue
+		}
+		if !ok || c.Min > minSize {
+			minSize = c.Min
+		}
+		if !ok || c.Max < maxSize {
+			maxSize = c.Max
+		}
+		ok = true
+	}
+	return minSize, maxSize, ok
+}
+
+// SignPostPolicy signs policy with creds for region and service at time t,
+// for a browser to upload with. It adds conditions for the signing fields
+// and returns them with the encoded policy and its signature; the form
+// posts them next to the fields the policy's conditions name, such as key,
+// with the file last.
+func SignPostPolicy(policy PostPolicy, creds SigV4Credentials, region, service string, t time.Time) (map[string]string, error) {
+	t = t.UTC()
+	fields := map[string]string{
+		postAlgorithmField:  SigV4Algorithm,
+		postCredentialField: creds.AccessKeyID + "/" + sigV4Scope(t, region, service),
+		postDateField:       t.Format(sigV4TimeFormat),
+	}
+	policy.Conditions = append([]PostPolicyCondition{
+		PostPolicyEquals(postAlgorithmField, fields[postAlgorithmField]),
+		PostPolicyEquals(postCredentialField, fields[postCredentialField]),
+		PostPolicyEquals(postDateField, fields[postDateField]),
+	}, policy.Conditions...)
+
+	doc, err := json.Marshal(policy)
+	if err != nil {
+		return nil, err
+	}
+	encoded := base64.StdEncoding.EncodeToString(doc)
+	fields[postPolicyField] = encoded
+	fields[postSignatureField] = hex.EncodeToString(hmacSHA256(sigV4SigningKey(creds.SecretAccessKey, t, region, service), encoded))
+	return fields, nil
+}
+
+// VerifyPostPolicy verifies the signature and expiry of a browser form
+// upload's POST policy, as signed by SignPostPolicy or an AWS SDK, and
+// returns the principal of the signing key with the decoded policy. fields
+// are the form fields keyed by lower-case name. Whether the form meets the
+// policy is checked separately with PostPolicy.Check.
+func (a *SigV4Authenticator) VerifyPostPolicy(ctx context.Context, fields map[string]string) (*Principal, *PostPolicy, error) {
+	if algorithm := fields[postAlgorithmField]; algorithm != SigV4Algorithm {
+		if algorithm == "" && fields[postPolicyField] == "" {
+			return nil, nil, ErrMissingCredentials
+		}
+		return nil, nil, fmt.Errorf("%w: unsupported signature algorithm", ErrInvalidCredentials)
+	}
+	encoded, signature := fields[postPolicyField], fields[postSignatureField]
+	if encoded == "" || signature == "" || fields[postCredentialField] == "" {
+		return nil, nil, fmt.Errorf("%w: incomplete POST policy signature", ErrInvalidCredentials)
+	}
+
+	var parsed sigV4Request
+	if err := a.parseScope(fields[postCredentialField], &parsed); err != nil {
+		return nil, nil, err
+	}
+	if err := parseAmzDate(fields[postDateField], &parsed); err != nil {
+		return nil, nil, err
+	}
+
+	if a.LookupSecret == nil {
+		return nil, nil, fmt.Errorf("%w: no SigV4 credential lookup configured", ErrUnauthorized)
+	}
+	secret, principal, err := a.LookupSecret(ctx, parsed.accessKeyID)
+	if err != nil {
+		return nil, nil, err
+	}
+	expected := hex.EncodeToString(hmacSHA256(sigV4SigningKey(secret, parsed.amzDate, parsed.region, parsed.service), encoded))
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return nil, nil, fmt.Errorf("%w: signature does not match", ErrInvalidCredentials)
+	}
+
+	doc, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: policy is not base64", ErrInvalidCredentials)
+	}
+	var policy PostPolicy
+	if err := json.Unmarshal(doc, &policy); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidCredentials, err)
+	}
+	if a.clock().After(policy.Expiration) {
+		return nil, nil, fmt.Errorf("%w: POST policy has expired", ErrInvalidCredentials)
+	}
+	return principal, &policy, nil
+}
@@ -0,0 +1,230 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// lowerFields returns fields keyed by lower-case name, as form uploads
+// are.
+func lowerFields(fields map[string]string) map[string]string {
+	lower := make(map[string]string, len(fields))
+	for name, value := range fields {
+		lower[strings.ToLower(name)] = value
+	}
+	return lower
+}
+
+func signTestPostPolicy(t *testing.T, policy PostPolicy) map[string]string {
+	t.Helper()
+	fields, err := SignPostPolicy(policy, SigV4Credentials{AccessKeyID: testSigV4AccessKey, SecretAccessKey: testSigV4Secret}, "us-east-1", "s3", time.Now())
+	if err != nil {
+		t.Fatalf("SignPostPolicy: %v", err)
+	}
+	return fields
+}
+
+func TestPostPolicy_SignVerifyRoundTrip(t *testing.T) {
+	policy := PostPolicy{
+		Expiration: time.Now().Add(time.Hour),
+		Conditions: []PostPolicyCondition{
+			PostPolicyPrefix("key", "uploads/"),
+			PostPolicyPrefix("Content-Type", "image/"),
+			PostPolicySize(1, 1024),
+		},
+	}
+	fields := signTestPostPolicy(t, policy)
+	fields["key"] = "uploads/cat.png"
+	fields["content-type"] = "image/png"
+
+	principal, verified, err := newTestSigV4Authenticator().VerifyPostPolicy(context.Background(), fields)
+	if err != nil {
+		t.Fatalf("VerifyPostPolicy: %v", err)
+	}
+	if principal.ID != "svc" {
+		t.Errorf("principal = %q, want svc", principal.ID)
+	}
+	if err := verified.Check(fields); err != nil {
+		t.Errorf("Check: %v", err)
+	}
+	if minSize, maxSize, ok := verified.SizeRange(); !ok || minSize != 1 || maxSize != 1024 {
+		t.Errorf("SizeRange = %d, %d, %v", minSize, maxSize, ok)
+	}
+}
+
+func TestPostPolicy_AWSSDKPresignPost(t *testing.T) {
+	client := s3.New(s3.Options{
+		Region: "us-east-1",
+		Credentials: aws.NewCredentialsCache(aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+			return aws.Credentials{AccessKeyID: testSigV4AccessKey, SecretAccessKey: testSigV4Secret}, nil
+		})),
+	})
+	presigned, err := s3.NewPresignClient(client).PresignPostObject(context.Background(), &s3.PutObjectInput{
+		Bucket: aws.String("objstore"),
+		Key:    aws.String("uploads/report.csv"),
+	}, func(o *s3.PresignPostOptions) {
+		o.Conditions = []any{[]any{"content-length-range", 1, 100}}
+	})
+	if err != nil {
+		t.Fatalf("PresignPostObject: %v", err)
+	}
+
+	fields := lowerFields(presigned.Values)
+	_, policy, err := newTestSigV4Authenticator().VerifyPostPolicy(context.Background(), fields)
+	if err != nil {
+		t.Fatalf("VerifyPostPolicy: %v", err)
+	}
+	if err := policy.Check(fields); err != nil {
+		t.Errorf("Check: %v", err)
+	}
+	fields["key"] = "elsewhere.csv"
+	if err := policy.Check(fields); !errors.Is(err, ErrPostPolicyViolation) {
+		t.Errorf("Check with other key = %v, want ErrPostPolicyViolation", err)
+	}
+	if _, maxSize, ok := policy.SizeRange(); !ok || maxSize != 100 {
+		t.Errorf("SizeRange max = %d, %v", maxSize, ok)
+	}
+}
+
+func TestPostPolicy_VerifyRejects(t *testing.T) {
+	valid := func() map[string]string {
+		return signTestPostPolicy(t, PostPolicy{Expiration: time.Now().Add(time.Hour)})
+	}
+	tests := []struct {
+		name   string
+		auth   func() *SigV4Authenticator
+		fields func() map[string]string
+		want   error
+	}{
+		{"missing", newTestSigV4Authenticator, func() map[string]string { return map[string]string{} }, ErrMissingCredentials},
+		{"algorithm", newTestSigV4Authenticator, func() map[string]string {
+			f := valid()
+			f["x-amz-algorithm"] = "AWS4-HMAC-SHA1"
+			return f
+		}, ErrInvalidCredentials},
+		{"signature", newTestSigV4Authenticator, func() map[string]string {
+			f := valid()
+			f["x-amz-signature"] = strings.Repeat("0", 64)
+			return f
+		}, ErrInvalidCredentials},
+		{"tampered policy", newTestSigV4Authenticator, func() map[string]string {
+			f := valid()
+			f["policy"] = signTestPostPolicy(t, PostPolicy{Expiration: time.Now().Add(48 * time.Hour)})["policy"]
+			return f
+		}, ErrInvalidCredentials},
+		{"expired", newTestSigV4Authenticator, func() map[string]string {
+			return signTestPostPolicy(t, PostPolicy{Expiration: time.Now().Add(-time.Minute)})
+		}, ErrInvalidCredentials},
+		{"unknown key", newTestSigV4Authenticator, func() map[string]string {
+			fields, _ := SignPostPolicy(PostPolicy{Expiration: time.Now().Add(time.Hour)}, SigV4Credentials{AccessKeyID: "nobody", SecretAccessKey: "x"}, "us-east-1", "s3", time.Now())
+			return fields
+		}, ErrInvalidCredentials},
+		{"region", func() *SigV4Authenticator {
+			a := newTestSigV4Authenticator()
+			a.Region = "eu-west-1"
+			return a
+		}, valid, ErrInvalidCredentials},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := tt.auth().VerifyPostPolicy(context.Background(), tt.fields())
+			if !errors.Is(err, tt.want) {
+				t.Errorf("VerifyPostPolicy() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostPolicy_Check(t *testing.T) {
+	policy := PostPolicy{Conditions: []PostPolicyCondition{
+		PostPolicyEquals("bucket", "ignored"),
+		PostPolicyPrefix("key", "uploads/"),
+		PostPolicyEquals("content-type", "text/plain"),
+		PostPolicyPrefix("x-amz-meta-tag", ""),
+	}}
+	tests := []struct {
+		name   string
+		fields map[string]string
+		ok     bool
+	}{
+		{"allowed", map[string]string{"key": "uploads/a.txt", "content-type": "text/plain", "x-ignore-csrf": "1", "policy": "p"}, true},
+		{"optional field", map[string]string{"key": "uploads/a.txt", "content-type": "text/plain", "x-amz-meta-tag": "any"}, true},
+		{"key prefix", map[string]string{"key": "other/a.txt", "content-type": "text/plain"}, false},
+		{"content type", map[string]string{"key": "uploads/a.txt", "content-type": "text/html"}, false},
+		{"missing field", map[string]string{"key": "uploads/a.txt"}, false},
+		{"unlisted field", map[string]string{"key": "uploads/a.txt", "content-type": "text/plain", "acl": "public-read"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Check(tt.fields)
+			if tt.ok && err != nil {
+				t.Errorf("Check() = %v, want nil", err)
+			}
+			if !tt.ok && !errors.Is(err, ErrPostPolicyViolation) {
+				t.Errorf("Check() = %v, want ErrPostPolicyViolation", err)
+			}
+		})
+	}
+}
+
+func TestPostPolicy_JSON(t *testing.T) {
+	doc := `{"expiration":"2030-01-01T00:00:00Z","conditions":[
+		{"bucket":"b"},
+		["starts-with","$Key","uploads/"],
+		["eq","$success_action_status","201"],
+		["content-length-range","10",20],
+		["content-length-range",0,15]]}`
+	var policy PostPolicy
+	if err := json.Unmarshal([]byte(doc), &policy); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if len(policy.Conditions) != 5 || policy.Conditions[1] != PostPolicyPrefix("key", "uploads/") {
+		t.Fatalf("conditions = %+v", policy.Conditions)
+	}
+	if minSize, maxSize, _ := policy.SizeRange(); minSize != 10 || maxSize != 15 {
+		t.Errorf("SizeRange = %d, %d, want 10, 15", minSize, maxSize)
+	}
+
+	data, err := json.Marshal(policy)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var again PostPolicy
+	if err := json.Unmarshal(data, &again); err != nil {
+		t.Fatalf("Unmarshal round trip: %v", err)
+	}
+	if !again.Expiration.Equal(policy.Expiration) || len(again.Conditions) != len(policy.Conditions) {
+		t.Errorf("round trip = %+v, want %+v", again, policy)
+	}
+
+	for _, bad := range []string{
+		`{"expiration":"tomorrow","conditions":[]}`,
+		`{"expiration":"2030-01-01T00:00:00Z","conditions":[["in","$key","a"]]}`,
+		`{"expiration":"2030-01-01T00:00:00Z","conditions":[["content-length-range",5,1]]}`,
+		`{"expiration":"2030-01-01T00:00:00Z","conditions":[["eq","key","a"]]}`,
+	} {
+		if err := json.Unmarshal([]byte(bad), &policy); err == nil {
+			t.Errorf("Unmarshal(%s) succeeded, want error", bad)
+		}
+	}
+}
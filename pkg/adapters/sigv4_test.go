@@ -0,0 +1,264 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package adapters
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+)
+
+const (
+	testSigV4AccessKey = "AKIDEXAMPLE"
+	testSigV4Secret    = "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"
+)
+
+// sigV4TestServer serves requests authenticated by auth, echoing the body.
+func sigV4TestServer(t *testing.T, auth *SigV4Authenticator) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, err := auth.AuthenticateHTTP(r.Context(), r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		_, _ = io.WriteString(w, principal.ID+":"+string(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newTestSigV4Authenticator() *SigV4Authenticator {
+	return NewStaticSigV4Authenticator(map[string]SigV4Key{
+		testSigV4AccessKey: {SecretAccessKey: testSigV4Secret, Principal: Principal{ID: "svc", Type: "service"}},
+	})
+}
+
+func payloadHash(body string) string {
+	sum := sha256.Sum256([]byte(body))
+	return hex.EncodeToString(sum[:])
+}
+
+func doSigV4(t *testing.T, req *http.Request) (int, string) {
+	t.Helper()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body)
+}
+
+func TestSigV4Authenticator_AWSSDKSigner(t *testing.T) {
+	srv := sigV4TestServer(t, newTestSigV4Authenticator())
+	creds := aws.Credentials{AccessKeyID: testSigV4AccessKey, SecretAccessKey: testSigV4Secret}
+
+	tests := []struct {
+		name    string
+		service string
+		path    string
+		body    string
+		hash    string
+		header  bool // send X-Amz-Content-Sha256, as the S3 SDK does
+	}{
+		{"s3 signed payload", "s3", "/api/v1/objects/dir/a%20b.txt", "hello", payloadHash("hello"), true},
+		{"s3 unsigned payload", "s3", "/api/v1/objects/x", "hello", SigV4UnsignedPayload, true},
+		{"generic service hashes body", "execute-api", "/api/v1/objects/a%20b.txt", "hello", payloadHash("hello"), false},
+		{"empty body", "s3", "/api/v1/objects", "", sigV4EmptyPayloadHash, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodPut, srv.URL+tt.path+"?prefix=a+b&max=10", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "text/plain")
+			if tt.header {
+				req.Header.Set(headerAmzContentSHA256, tt.hash)
+			}
+			signer := v4.NewSigner(func(o *v4.SignerOptions) {
+				o.DisableURIPathEscaping = tt.service == "s3"
+			})
+			if err := signer.SignHTTP(context.Background(), creds, req, tt.hash, tt.service, "us-east-1", time.Now()); err != nil {
+				t.Fatalf("SignHTTP: %v", err)
+			}
+
+			status, body := doSigV4(t, req)
+			if status != http.StatusOK {
+				t.Fatalf("status = %d (%s), want 200", status, body)
+			}
+			if body != "svc:"+tt.body {
+				t.Errorf("body = %q", body)
+			}
+		})
+	}
+}
+
+func TestSigV4Authenticator_AWSSDKPresign(t *testing.T) {
+	srv := sigV4TestServer(t, newTestSigV4Authenticator())
+	creds := aws.Credentials{AccessKeyID: testSigV4AccessKey, SecretAccessKey: testSigV4Secret}
+	signer := v4.NewSigner(func(o *v4.SignerOptions) { o.DisableURIPathEscaping = true })
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/api/v1/objects/key.txt?X-Amz-Expires=300", http.NoBody)
+	signed, _, err := signer.PresignHTTP(context.Background(), creds, req, SigV4UnsignedPayload, "s3", "us-east-1", time.Now())
+	if err != nil {
+		t.Fatalf("PresignHTTP: %v", err)
+	}
+
+	presigned, _ := http.NewRequest(http.MethodGet, signed, http.NoBody)
+	if status, body := doSigV4(t, presigned); status != http.StatusOK {
+		t.Fatalf("status = %d (%s), want 200", status, body)
+	}
+
+	// Presigned URLs stop working once they expire.
+	auth := newTestSigV4Authenticator()
+	auth.now = func() time.Time { return time.Now().Add(10 * time.Minute) }
+	expired := sigV4TestServer(t, auth)
+	late, _ := http.NewRequest(http.MethodGet, strings.Replace(signed, srv.URL, expired.URL, 1), http.NoBody)
+	if status, _ := doSigV4(t, late); status != http.StatusUnauthorized {
+		t.Errorf("expired presigned URL status = %d, want 401", status)
+	}
+}
+
+func TestSignHTTPRequestV4_RoundTrip(t *testing.T) {
+	srv := sigV4TestServer(t, newTestSigV4Authenticator())
+	creds := SigV4Credentials{AccessKeyID: testSigV4AccessKey, SecretAccessKey: testSigV4Secret}
+
+	req, _ := http.NewRequest(http.MethodPost, srv.URL+"/api/v1/archive?a=1", strings.NewReader(`{"key":"k"}`))
+	req.Header.Set("Content-Type", "application/json")
+	SignHTTPRequestV4(req, creds, "us-east-1", "s3", payloadHash(`{"key":"k"}`), time.Now())
+
+	if status, body := doSigV4(t, req); status != http.StatusOK {
+		t.Fatalf("status = %d (%s), want 200", status, body)
+	}
+}
+
+func TestSigV4Authenticator_Rejects(t *testing.T) {
+	creds := SigV4Credentials{AccessKeyID: testSigV4AccessKey, SecretAccessKey: testSigV4Secret}
+	newSigned := func(method, body string, mutate func(*http.Request)) *http.Request {
+		req := httptest.NewRequest(method, "http://objstore.local/api/v1/objects/k", strings.NewReader(body))
+		SignHTTPRequestV4(req, creds, "us-east-1", "s3", payloadHash(body), time.Now())
+		if mutate != nil {
+			mutate(req)
+		}
+		return req
+	}
+
+	tests := []struct {
+		name    string
+		auth    func(*SigV4Authenticator)
+		req     *http.Request
+		wantErr error
+	}{
+		{"no credentials", nil, httptest.NewRequest(http.MethodGet, "/", nil), ErrMissingCredentials},
+		{"tampered method", nil, newSigned(http.MethodGet, "", func(r *http.Request) { r.Method = http.MethodDelete }), ErrInvalidCredentials},
+		{"tampered header", nil, newSigned(http.MethodPut, "x", func(r *http.Request) {
+			r.Header.Set(headerAmzDate, time.Now().Add(time.Second).UTC().Format(sigV4TimeFormat))
+		}), ErrInvalidCredentials},
+		{"wrong secret", nil, newSigned(http.MethodGet, "", func(r *http.Request) {
+			SignHTTPRequestV4(r, SigV4Credentials{AccessKeyID: testSigV4AccessKey, SecretAccessKey: "wrong"}, "us-east-1", "s3", sigV4EmptyPayloadHash, time.Now())
+		}), ErrInvalidCredentials},
+		{"unknown access key", nil, newSigned(http.MethodGet, "", func(r *http.Request) {
+			SignHTTPRequestV4(r, SigV4Credentials{AccessKeyID: "OTHER", SecretAccessKey: testSigV4Secret}, "us-east-1", "s3", sigV4EmptyPayloadHash, time.Now())
+		}), ErrInvalidCredentials},
+		{"clock skew", func(a *SigV4Authenticator) { a.now = func() time.Time { return time.Now().Add(time.Hour) } }, newSigned(http.MethodGet, "", nil), ErrInvalidCredentials},
+		{"region not accepted", func(a *SigV4Authenticator) { a.Region = "eu-west-1" }, newSigned(http.MethodGet, "", nil), ErrInvalidCredentials},
+		{"streaming payload", nil, newSigned(http.MethodPut, "", func(r *http.Request) {
+			SignHTTPRequestV4(r, creds, "us-east-1", "s3", "STREAMING-AWS4-HMAC-SHA256-PAYLOAD", time.Now())
+		}), ErrInvalidCredentials},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth := newTestSigV4Authenticator()
+			if tt.auth != nil {
+				tt.auth(auth)
+			}
+			_, err := auth.AuthenticateHTTP(context.Background(), tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("AuthenticateHTTP() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSigV4Authenticator_PayloadMismatch(t *testing.T) {
+	creds := SigV4Credentials{AccessKeyID: testSigV4AccessKey, SecretAccessKey: testSigV4Secret}
+	for _, limit := range []int64{0, 4} {
+		req := httptest.NewRequest(http.MethodPut, "http://objstore.local/api/v1/objects/k", strings.NewReader("tampered"))
+		SignHTTPRequestV4(req, creds, "us-east-1", "s3", payloadHash("original"), time.Now())
+
+		auth := newTestSigV4Authenticator()
+		auth.MaxBufferedBody = limit
+		auth.SpoolDir = t.TempDir()
+		if _, err := auth.AuthenticateHTTP(context.Background(), req); !errors.Is(err, ErrInvalidCredentials) {
+			t.Errorf("AuthenticateHTTP(limit %d) error = %v, want %v", limit, err, ErrInvalidCredentials)
+		}
+		if entries, _ := os.ReadDir(auth.SpoolDir); len(entries) != 0 {
+			t.Errorf("AuthenticateHTTP(limit %d) left %d spool files", limit, len(entries))
+		}
+	}
+}
+
+func TestSigV4Authenticator_SpoolsLargeBodies(t *testing.T) {
+	creds := SigV4Credentials{AccessKeyID: testSigV4AccessKey, SecretAccessKey: testSigV4Secret}
+	body := strings.Repeat("spooled ", 16)
+	req := httptest.NewRequest(http.MethodPut, "http://objstore.local/api/v1/objects/k", strings.NewReader(body))
+	SignHTTPRequestV4(req, creds, "us-east-1", "s3", payloadHash(body), time.Now())
+
+	auth := newTestSigV4Authenticator()
+	auth.MaxBufferedBody = 8
+	auth.SpoolDir = t.TempDir()
+	if _, err := auth.AuthenticateHTTP(context.Background(), req); err != nil {
+		t.Fatalf("AuthenticateHTTP() error = %v", err)
+	}
+	if entries, _ := os.ReadDir(auth.SpoolDir); len(entries) != 1 {
+		t.Errorf("expected the body to be spooled, found %d files", len(entries))
+	}
+	got, err := io.ReadAll(req.Body)
+	if err != nil || string(got) != body {
+		t.Errorf("spooled body = %q, %v", got, err)
+	}
+	if err := req.Body.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if entries, _ := os.ReadDir(auth.SpoolDir); len(entries) != 0 {
+		t.Errorf("Close() left %d spool files", len(entries))
+	}
+}
+
+func TestSigV4Authenticator_UnsupportedTransports(t *testing.T) {
+	auth := newTestSigV4Authenticator()
+	if _, err := auth.AuthenticateGRPC(context.Background(), nil); !errors.Is(err, ErrMissingCredentials) {
+		t.Errorf("AuthenticateGRPC() error = %v, want %v", err, ErrMissingCredentials)
+	}
+	if _, err := auth.AuthenticateMTLS(context.Background(), nil); !errors.Is(err, ErrMTLSNotSupported) {
+		t.Errorf("AuthenticateMTLS() error = %v, want %v", err, ErrMTLSNotSupported)
+	}
+}
@@ -0,0 +1,313 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package adapters
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	jose "github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// tokenDefaultIssuer is the "iss" claim of scoped tokens.
+	tokenDefaultIssuer = "go-objstore"
+
+	// tokenDefaultTTL is the lifetime of a token when none is requested.
+	tokenDefaultTTL = 15 * time.Minute
+
+	// tokenDefaultMaxTTL is the longest lifetime a token may be issued for.
+	tokenDefaultMaxTTL = 12 * time.Hour
+
+	// tokenMinKeySize is the minimum HMAC key length in bytes.
+	tokenMinKeySize = 32
+
+	// tokenClockSkew is the leeway for exp, nbf and iat checks across servers.
+	tokenClockSkew = 30 * time.Second
+)
+
+// ErrInvalidTokenScope is returned when a requested token scope or lifetime
+// is not allowed.
+var ErrInvalidTokenScope = errors.New("invalid token scope")
+
+// tokenActions are the actions a scoped token may carry. Admin is excluded:
+// scoped tokens hand out data access, not control of the server.
+var tokenActions = []string{ActionRead, ActionWrite, ActionDelete, ActionList}
+
+// TokenScope restricts a principal to a subset of its permissions: the listed
+// actions on object keys beginning with Prefix.
+type TokenScope struct {
+	// Prefix is the key prefix the token is limited to; empty allows every key.
+	Prefix string `json:"prefix"`
+
+	// Actions lists the permitted actions (read, write, delete, list).
+	Actions []string `json:"actions"`
+}
+
+// Allows reports whether the scope permits action on resource. The resource
+// is an object key, or the prefix of a list. Category resources, and the
+// ResourceObject placeholder used when a server cannot see the key, are only
+// allowed by unprefixed scopes.
+func (s *TokenScope) Allows(action, resource string) bool {
+	if !slices.Contains(s.Actions, action) {
+		return false
+	}
+	if s.Prefix == "" {
+		return true
+	}
+	switch resource {
+	case ResourceObject, ResourcePolicy, ResourceReplication, ResourceRetention, ResourceManifest, ResourceCost, ResourceJobs, ResourceTasks, ResourceShares,
+		ResourceQuota, ResourceWebhook, ResourceAccessKey:
+		return false
+	}
+	return strings.HasPrefix(resource, s.Prefix)
+}
+
+// validate checks that the scope only names token actions.
+func (s *TokenScope) validate() error {
+	if len(s.Actions) == 0 {
+		return fmt.Errorf("%w: at least one action is required", ErrInvalidTokenScope)
+	}
+	for _, action := range s.Actions {
+		if !slices.Contains(tokenActions, action) {
+			return fmt.Errorf("%w: action %q is not allowed (want one of %s)",
+				ErrInvalidTokenScope, action, strings.Join(tokenActions, ", "))
+		}
+	}
+	return nil
+}
+
+// TokenServiceConfig configures a TokenService.
+type TokenServiceConfig struct {
+	// Key is the HMAC-SHA256 signing key, at least 32 bytes. Every server
+	// that should accept the tokens must be configured with the same key.
+	Key []byte
+
+	// Issuer is the "iss" claim written and required (default: "go-objstore").
+	Issuer string
+
+	// DefaultTTL is the lifetime of a token when none is requested
+	// (default: 15m).
+	DefaultTTL time.Duration
+
+	// MaxTTL is the longest lifetime a token may be issued for (default: 12h).
+	MaxTTL time.Duration
+}
+
+// TokenService mints and validates short-lived scoped tokens. An
+// authenticated principal exchanges its own credentials for a token limited
+// to a key prefix, a set of actions and an expiry, which can be handed to an
+// untrusted worker or browser instead of long-lived credentials.
+//
+// Tokens are stateless signed JWTs, so any server configured with the same
+// key validates them. A token authenticates as the principal that minted it,
+// with its roles as of minting, and carries the scope in Principal.Scope;
+// servers enforce the scope with ScopedAuthorizer on top of their authorizer,
+// so a token never grants more than its minter held.
+type TokenService struct {
+	config TokenServiceConfig
+	signer jose.Signer
+
+	// now returns the current time; overridden in tests.
+	now func() time.Time
+}
+
+// tokenClaims are the private claims of a scoped token.
+type tokenClaims struct {
+	Name   string     `json:"name,omitempty"`
+	Type   string     `json:"principal_type,omitempty"`
+	Roles  []string   `json:"roles,omitempty"`
+	Tenant string     `json:"tenant,omitempty"`
+	Scope  TokenScope `json:"scope"`
+}
+
+// NewTokenService creates a token service signing with config.Key.
+func NewTokenService(config *TokenServiceConfig) (*TokenService, error) {
+	if config == nil || len(config.Key) < tokenMinKeySize {
+		return nil, fmt.Errorf("token service: key must be at least %d bytes", tokenMinKeySize)
+	}
+
+	cfg := *config
+	if cfg.Issuer == "" {
+		cfg.Issuer = tokenDefaultIssuer
+	}
+	if cfg.DefaultTTL <= 0 {
+		cfg.DefaultTTL = tokenDefaultTTL
+	}
+	if cfg.MaxTTL <= 0 {
+		cfg.MaxTTL = tokenDefaultMaxTTL
+	}
+	if cfg.DefaultTTL > cfg.MaxTTL {
+		cfg.DefaultTTL = cfg.MaxTTL
+	}
+
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.HS256, Key: cfg.Key},
+		(&jose.SignerOptions{}).WithType("JWT"))
+	if err != nil {
+		return nil, fmt.Errorf("token service: %w", err)
+	}
+	return &TokenService{config: cfg, signer: signer, now: time.Now}, nil
+}
+
+// Issue mints a token for principal limited to scope. A zero ttl uses the
+// default lifetime. Principals authenticated by a scoped token cannot mint
+// further tokens. Issue does not consult an authorizer; callers should check
+// that the principal holds each requested action on the prefix.
+func (s *TokenService) Issue(principal *Principal, scope TokenScope, ttl time.Duration) (string, time.Time, error) {
+	if principal == nil {
+		return "", time.Time{}, ErrUnauthorized
+	}
+	if principal.Scope != nil {
+		return "", time.Time{}, fmt.Errorf("%w: scoped tokens cannot issue tokens", ErrInsufficientPermissions)
+	}
+	if err := scope.validate(); err != nil {
+		return "", time.Time{}, err
+	}
+	if ttl == 0 {
+		ttl = s.config.DefaultTTL
+	}
+	if ttl < 0 {
+		return "", time.Time{}, fmt.Errorf("%w: lifetime must be positive", ErrInvalidTokenScope)
+	}
+	if ttl > s.config.MaxTTL {
+		return "", time.Time{}, fmt.Errorf("%w: lifetime exceeds the maximum of %s", ErrInvalidTokenScope, s.config.MaxTTL)
+	}
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", time.Time{}, fmt.Errorf("token service: %w", err)
+	}
+
+	now := s.now()
+	expires := now.Add(ttl).Truncate(time.Second)
+	std := jwt.Claims{
+		Issuer:    s.config.Issuer,
+		Subject:   principal.ID,
+		ID:        hex.EncodeToString(id),
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+		Expiry:    jwt.NewNumericDate(expires),
+	}
+	private := tokenClaims{
+		Name:   principal.Name,
+		Type:   principal.Type,
+		Roles:  principal.Roles,
+		Tenant: principal.TenantID,
+		Scope:  scope,
+	}
+	token, err := jwt.Signed(s.signer).Claims(std).Claims(private).Serialize()
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("token service: %w", err)
+	}
+	return token, expires, nil
+}
+
+// AuthenticateHTTP authenticates using the bearer token in the Authorization header.
+func (s *TokenService) AuthenticateHTTP(ctx context.Context, req *http.Request) (*Principal, error) {
+	token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return nil, ErrMissingCredentials
+	}
+	return s.Authenticate(ctx, token)
+}
+
+// AuthenticateGRPC authenticates using the bearer token in the authorization metadata.
+func (s *TokenService) AuthenticateGRPC(ctx context.Context, md metadata.MD) (*Principal, error) {
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return nil, ErrMissingCredentials
+	}
+	token, ok := strings.CutPrefix(values[0], "Bearer ")
+	if !ok || token == "" {
+		return nil, ErrMissingCredentials
+	}
+	return s.Authenticate(ctx, token)
+}
+
+// AuthenticateMTLS is not supported for scoped tokens.
+func (s *TokenService) AuthenticateMTLS(ctx context.Context, state *tls.ConnectionState) (*Principal, error) {
+	return nil, ErrMTLSNotSupported
+}
+
+// Authenticate validates a raw scoped token and returns its principal.
+func (s *TokenService) Authenticate(ctx context.Context, raw string) (*Principal, error) {
+	token, err := jwt.ParseSigned(raw, []jose.SignatureAlgorithm{jose.HS256})
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed token: %v", ErrInvalidCredentials, err)
+	}
+
+	var std jwt.Claims
+	var private tokenClaims
+	if err := token.Claims(s.config.Key, &std, &private); err != nil {
+		return nil, fmt.Errorf("%w: invalid token signature: %v", ErrInvalidCredentials, err)
+	}
+	if std.Expiry == nil {
+		return nil, fmt.Errorf("%w: token has no expiry", ErrInvalidCredentials)
+	}
+	if err := std.ValidateWithLeeway(jwt.Expected{
+		Issuer: s.config.Issuer,
+		Time:   s.now(),
+	}, tokenClockSkew); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCredentials, err)
+	}
+	if err := private.Scope.validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCredentials, err)
+	}
+
+	scope := private.Scope
+	return &Principal{
+		ID:         std.Subject,
+		Name:       private.Name,
+		Type:       private.Type,
+		Roles:      private.Roles,
+		TenantID:   private.Tenant,
+		Scope:      &scope,
+		Attributes: map[string]any{"token_id": std.ID},
+	}, nil
+}
+
+// ScopedAuthorizer enforces the scope of principals authenticated by a
+// scoped token before delegating to the wrapped authorizer. Principals
+// without a scope are passed through unchanged.
+type ScopedAuthorizer struct {
+	next Authorizer
+}
+
+// NewScopedAuthorizer wraps next with token scope enforcement. Wrapping an
+// already scoped authorizer returns it unchanged.
+func NewScopedAuthorizer(next Authorizer) *ScopedAuthorizer {
+	if scoped, ok := next.(*ScopedAuthorizer); ok {
+		return scoped
+	}
+	return &ScopedAuthorizer{next: next}
+}
+
+// Authorize denies actions outside the principal's token scope, then defers
+// to the wrapped authorizer.
+func (a *ScopedAuthorizer) Authorize(ctx context.Context, principal *Principal, action, resource string) error {
+	if principal != nil && principal.Scope != nil && !principal.Scope.Allows(action, resource) {
+		return fmt.Errorf("%w: %s on %q is outside the token scope", ErrInsufficientPermissions, action, resource)
+	}
+	return a.next.Authorize(ctx, principal, action, resource)
+}
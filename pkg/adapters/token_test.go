@@ -0,0 +1,219 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package adapters
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+)
+
+var testTokenKey = []byte("0123456789abcdef0123456789abcdef")
+
+func newTestTokenService(t *testing.T) *TokenService {
+	t.Helper()
+	service, err := NewTokenService(&TokenServiceConfig{Key: testTokenKey})
+	if err != nil {
+		t.Fatalf("NewTokenService: %v", err)
+	}
+	return service
+}
+
+func TestNewTokenService_Validation(t *testing.T) {
+	if _, err := NewTokenService(nil); err == nil {
+		t.Error("nil config: expected error")
+	}
+	if _, err := NewTokenService(&TokenServiceConfig{Key: []byte("short")}); err == nil {
+		t.Error("short key: expected error")
+	}
+}
+
+func TestTokenService_IssueAndAuthenticate(t *testing.T) {
+	service := newTestTokenService(t)
+	minter := &Principal{ID: "alice", Name: "Alice", Type: "user", Roles: []string{"editor"}, TenantID: "acme"}
+	scope := TokenScope{Prefix: "uploads/alice/", Actions: []string{ActionRead, ActionWrite}}
+
+	token, expires, err := service.Issue(minter, scope, time.Minute)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	if d := time.Until(expires); d <= 0 || d > time.Minute {
+		t.Errorf("expires in %s, want within 1m", d)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	principal, err := service.AuthenticateHTTP(context.Background(), req)
+	if err != nil {
+		t.Fatalf("AuthenticateHTTP: %v", err)
+	}
+	if principal.ID != "alice" || principal.Name != "Alice" || principal.Type != "user" || principal.TenantID != "acme" {
+		t.Errorf("principal = %+v, want alice/Alice/user/acme", principal)
+	}
+	if !principal.HasRole("editor") {
+		t.Errorf("roles = %v, want editor", principal.Roles)
+	}
+	if principal.Scope == nil || principal.Scope.Prefix != scope.Prefix || len(principal.Scope.Actions) != 2 {
+		t.Errorf("scope = %+v, want %+v", principal.Scope, scope)
+	}
+
+	md := metadata.Pairs("authorization", "Bearer "+token)
+	if _, err := service.AuthenticateGRPC(context.Background(), md); err != nil {
+		t.Errorf("AuthenticateGRPC: %v", err)
+	}
+
+	// A second service sharing the key accepts the token.
+	other := newTestTokenService(t)
+	if _, err := other.Authenticate(context.Background(), token); err != nil {
+		t.Errorf("Authenticate on another server: %v", err)
+	}
+}
+
+func TestTokenService_IssueRejects(t *testing.T) {
+	service := newTestTokenService(t)
+	minter := &Principal{ID: "alice"}
+	read := TokenScope{Actions: []string{ActionRead}}
+
+	tests := []struct {
+		name      string
+		principal *Principal
+		scope     TokenScope
+		ttl       time.Duration
+		want      error
+	}{
+		{"no principal", nil, read, 0, ErrUnauthorized},
+		{"scoped principal", &Principal{ID: "alice", Scope: &read}, read, 0, ErrInsufficientPermissions},
+		{"no actions", minter, TokenScope{}, 0, ErrInvalidTokenScope},
+		{"admin action", minter, TokenScope{Actions: []string{ActionAdmin}}, 0, ErrInvalidTokenScope},
+		{"negative ttl", minter, read, -time.Second, ErrInvalidTokenScope},
+		{"ttl over max", minter, read, 13 * time.Hour, ErrInvalidTokenScope},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := service.Issue(tt.principal, tt.scope, tt.ttl); !errors.Is(err, tt.want) {
+				t.Errorf("Issue error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenService_AuthenticateRejects(t *testing.T) {
+	service := newTestTokenService(t)
+	token, _, err := service.Issue(&Principal{ID: "alice"}, TokenScope{Actions: []string{ActionRead}}, time.Minute)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+
+	forged, err := NewTokenService(&TokenServiceConfig{Key: []byte(strings.Repeat("x", 32))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	forgedToken, _, _ := forged.Issue(&Principal{ID: "mallory"}, TokenScope{Actions: []string{ActionRead}}, time.Minute)
+
+	otherIssuer, err := NewTokenService(&TokenServiceConfig{Key: testTokenKey, Issuer: "elsewhere"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherIssuerToken, _, _ := otherIssuer.Issue(&Principal{ID: "alice"}, TokenScope{Actions: []string{ActionRead}}, time.Minute)
+
+	tests := map[string]string{
+		"malformed":     "not-a-token",
+		"wrong key":     forgedToken,
+		"wrong issuer":  otherIssuerToken,
+		"tampered body": token[:strings.Index(token, ".")+2] + "x" + token[strings.Index(token, ".")+3:],
+	}
+	for name, raw := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := service.Authenticate(context.Background(), raw); !errors.Is(err, ErrInvalidCredentials) {
+				t.Errorf("Authenticate error = %v, want ErrInvalidCredentials", err)
+			}
+		})
+	}
+
+	t.Run("expired", func(t *testing.T) {
+		service.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+		defer func() { service.now = time.Now }()
+		if _, err := service.Authenticate(context.Background(), token); !errors.Is(err, ErrInvalidCredentials) {
+			t.Errorf("Authenticate error = %v, want ErrInvalidCredentials", err)
+		}
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/", nil)
+		if _, err := service.AuthenticateHTTP(context.Background(), req); !errors.Is(err, ErrMissingCredentials) {
+			t.Errorf("AuthenticateHTTP error = %v, want ErrMissingCredentials", err)
+		}
+	})
+}
+
+func TestTokenScope_Allows(t *testing.T) {
+	scope := &TokenScope{Prefix: "logs/", Actions: []string{ActionRead, ActionList}}
+	tests := []struct {
+		action, resource string
+		want             bool
+	}{
+		{ActionRead, "logs/app.log", true},
+		{ActionList, "logs/2025/", true},
+		{ActionWrite, "logs/app.log", false},
+		{ActionRead, "secrets/key", false},
+		{ActionList, "", false},
+		{ActionRead, ResourceObject, false},
+		{ActionRead, ResourcePolicy, false},
+	}
+	for _, tt := range tests {
+		if got := scope.Allows(tt.action, tt.resource); got != tt.want {
+			t.Errorf("Allows(%q, %q) = %v, want %v", tt.action, tt.resource, got, tt.want)
+		}
+	}
+
+	unprefixed := &TokenScope{Actions: []string{ActionList}}
+	if !unprefixed.Allows(ActionList, "") || !unprefixed.Allows(ActionList, ResourceObject) {
+		t.Error("unprefixed scope should allow list of everything")
+	}
+}
+
+func TestScopedAuthorizer(t *testing.T) {
+	rbac := NewRBACAuthorizer(map[string][]string{"reader": {ActionRead, ActionList}})
+	authz := NewScopedAuthorizer(rbac)
+	if NewScopedAuthorizer(authz) != authz {
+		t.Error("wrapping a ScopedAuthorizer should return it unchanged")
+	}
+	ctx := context.Background()
+
+	unscoped := &Principal{ID: "r", Roles: []string{"reader"}}
+	if err := authz.Authorize(ctx, unscoped, ActionRead, "any/key"); err != nil {
+		t.Errorf("unscoped read: %v", err)
+	}
+
+	// The scope narrows the roles, and the roles bound the scope.
+	scoped := &Principal{ID: "r", Roles: []string{"reader"},
+		Scope: &TokenScope{Prefix: "public/", Actions: []string{ActionRead, ActionWrite}}}
+	if err := authz.Authorize(ctx, scoped, ActionRead, "public/a"); err != nil {
+		t.Errorf("scoped read in prefix: %v", err)
+	}
+	for _, c := range []struct{ action, resource string }{
+		{ActionRead, "private/a"},
+		{ActionList, "public/"},
+		{ActionWrite, "public/a"},
+	} {
+		if err := authz.Authorize(ctx, scoped, c.action, c.resource); !errors.Is(err, ErrInsufficientPermissions) {
+			t.Errorf("scoped %s on %q = %v, want ErrInsufficientPermissions", c.action, c.resource, err)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package alias implements symbolic references to objects, such as
+// "latest" pointing at "releases/v1.4.2/app.tar.gz", so a well-known name
+// can be moved between large objects without copying them.
+//
+// An alias is a small JSON object under a reserved prefix (DefaultPrefix)
+// naming its target. Storage resolves aliases transparently: reading a key
+// that holds no object reads the target of the alias of that name instead.
+// Objects always take precedence, so an object written at an alias's name
+// shadows the alias. Aliases point at objects, never at other aliases, so
+// resolution takes one step and cannot loop.
+package alias
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// DefaultPrefix is the key prefix alias objects are stored under when none
+// is configured.
+const DefaultPrefix = ".aliases/"
+
+// maxAliasSize bounds the alias objects read back.
+const maxAliasSize = 64 * 1024
+
+var (
+	// ErrAliasNotFound is returned for a name without an alias.
+	ErrAliasNotFound = fmt.Errorf("alias %w", common.ErrNotFound)
+
+	// ErrNameInUse is returned when setting an alias whose name holds an
+	// object, which would shadow it.
+	ErrNameInUse = fmt.Errorf("%w: an object exists at the alias name", common.ErrAlreadyExists)
+
+	// ErrInvalidTarget is returned when an alias would point at itself, at
+	// another alias or at a key without an object.
+	ErrInvalidTarget = fmt.Errorf("%w: alias target must be an existing object", common.ErrInvalidArgument)
+)
+
+// Alias is a named reference to an object.
+type Alias struct {
+	Name      string    `json:"name"`
+	Target    string    `json:"target"`
+	UpdatedAt time.Time `json:"updated_at"`
+	UpdatedBy string    `json:"updated_by,omitempty"`
+}
+
+// Manager sets, resolves and removes aliases.
+type Manager struct {
+	storage common.Storage
+	prefix  string
+	now     func() time.Time
+}
+
+// NewManager returns a Manager for aliases of objects in storage, stored
+// under prefix, or DefaultPrefix if prefix is empty.
+func NewManager(storage common.Storage, prefix string) (*Manager, error) {
+	if prefix == "" {
+		prefix = DefaultPrefix
+	}
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	if err := common.ValidateKey(prefix + "alias"); err != nil {
+		return nil, fmt.Errorf("invalid alias prefix %q: %w", prefix, err)
+	}
+	return &Manager{storage: storage, prefix: prefix, now: time.Now}, nil
+}
+
+// Prefix returns the key prefix alias objects are stored under.
+func (m *Manager) Prefix() string {
+	return m.prefix
+}
+
+// Reserved reports whether key is in the alias namespace.
+func (m *Manager) Reserved(key string) bool {
+	return common.IsReserved(key, m.prefix)
+}
+
+// Set points the alias name at target, creating the alias or moving an
+// existing one. The target must be an existing object, and no object may
+// exist at name.
+func (m *Manager) Set(ctx context.Context, name, target string) (*Alias, error) {
+	if err := m.validate(name); err != nil {
+		return nil, err
+	}
+	if err := m.validate(target); err != nil {
+		return nil, err
+	}
+	if name == target {
+		return nil, fmt.Errorf("%w: %s points at itself", ErrInvalidTarget, name)
+	}
+
+	if exists, err := m.storage.Exists(ctx, name); err != nil {
+		return nil, err
+	} else if exists {
+		return nil, fmt.Errorf("%w: %s", ErrNameInUse, name)
+	}
+	exists, err := m.storage.Exists(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		if _, err := m.read(ctx, target); err == nil {
+			return nil, fmt.Errorf("%w: %s is an alias", ErrInvalidTarget, target)
+		}
+		return nil, fmt.Errorf("%w: %s not found", ErrInvalidTarget, target)
+	}
+
+	alias := &Alias{Name: name, Target: target, UpdatedAt: m.now().UTC(), UpdatedBy: adapters.Identity(ctx)}
+	data, err := json.Marshal(alias)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.storage.PutWithMetadata(ctx, m.prefix+name, bytes.NewReader(data), &common.Metadata{
+		ContentType: "application/json",
+	}); err != nil {
+		return nil, err
+	}
+	return alias, nil
+}
+
+// Resolve returns the alias name.
+func (m *Manager) Resolve(ctx context.Context, name string) (*Alias, error) {
+	if err := m.validate(name); err != nil {
+		return nil, err
+	}
+	return m.read(ctx, name)
+}
+
+// Delete removes the alias name. Its target is left as is.
+func (m *Manager) Delete(ctx context.Context, name string) error {
+	if _, err := m.Resolve(ctx, name); err != nil {
+		return err
+	}
+	return m.storage.DeleteWithContext(ctx, m.prefix+name)
+}
+
+// List returns the aliases whose names start with prefix, ordered by name.
+func (m *Manager) List(ctx context.Context, prefix string) ([]Alias, error) {
+	keys, err := m.storage.ListWithContext(ctx, m.prefix+prefix)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(keys)
+
+	aliases := make([]Alias, 0, len(keys))
+	for _, key := range keys {
+		alias, err := m.read(ctx, strings.TrimPrefix(key, m.prefix))
+		if errors.Is(err, common.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		aliases = append(aliases, *alias)
+	}
+	return aliases, nil
+}
+
+func (m *Manager) validate(key string) error {
+	if err := common.ValidateKey(key); err != nil {
+		return err
+	}
+	if m.Reserved(key) {
+		return fmt.Errorf("%w: %s", common.ErrReservedKey, key)
+	}
+	return nil
+}
+
+// read returns the alias name, or ErrAliasNotFound if there is none.
+func (m *Manager) read(ctx context.Context, name string) (*Alias, error) {
+	rc, err := m.storage.GetWithContext(ctx, m.prefix+name)
+	if errors.Is(err, common.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrAliasNotFound, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(io.LimitReader(rc, maxAliasSize))
+	_ = rc.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	var alias Alias
+	if err := json.Unmarshal(data, &alias); err != nil {
+		return nil, fmt.Errorf("failed to decode alias %s: %w", name, err)
+	}
+	if alias.Name != name || alias.Target == "" {
+		return nil, fmt.Errorf("%w: %s", ErrAliasNotFound, name)
+	}
+	return &alias, nil
+}
@@ -0,0 +1,219 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package alias
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+func newTestStorage(t *testing.T, objects map[string]string) *Storage {
+	t.Helper()
+	backend := memory.New()
+	for key, data := range objects {
+		if err := backend.Put(key, strings.NewReader(data)); err != nil {
+			t.Fatalf("Put(%s): %v", key, err)
+		}
+	}
+	manager, err := NewManager(backend, "")
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	return NewStorage(backend, manager)
+}
+
+func read(t *testing.T, s common.Storage, key string) string {
+	t.Helper()
+	rc, err := s.GetWithContext(context.Background(), key)
+	if err != nil {
+		t.Fatalf("Get(%s): %v", key, err)
+	}
+	defer func() { _ = rc.Close() }()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatalf("ReadAll(%s): %v", key, err)
+	}
+	return string(data)
+}
+
+func TestSetAndResolve(t *testing.T) {
+	ctx := context.WithValue(context.Background(), adapters.PrincipalContextKey{}, &adapters.Principal{ID: "ci"})
+	s := newTestStorage(t, map[string]string{
+		"releases/v1.4.1/app.tar.gz": "old",
+		"releases/v1.4.2/app.tar.gz": "new",
+	})
+	manager := s.Manager()
+
+	alias, err := manager.Set(ctx, "latest", "releases/v1.4.1/app.tar.gz")
+	if err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if alias.UpdatedBy != "ci" || alias.UpdatedAt.IsZero() {
+		t.Errorf("alias = %+v, want updated by ci", alias)
+	}
+	if got := read(t, s, "latest"); got != "old" {
+		t.Errorf("Get(latest) = %q, want old", got)
+	}
+
+	if _, err := manager.Set(ctx, "latest", "releases/v1.4.2/app.tar.gz"); err != nil {
+		t.Fatalf("Set moved: %v", err)
+	}
+	resolved, err := manager.Resolve(ctx, "latest")
+	if err != nil || resolved.Target != "releases/v1.4.2/app.tar.gz" {
+		t.Fatalf("Resolve = %+v, %v", resolved, err)
+	}
+	if got := read(t, s, "latest"); got != "new" {
+		t.Errorf("Get(latest) = %q, want new", got)
+	}
+
+	metadata, err := s.GetMetadata(ctx, "latest")
+	if err != nil || metadata.Size != 3 {
+		t.Errorf("GetMetadata(latest) = %+v, %v", metadata, err)
+	}
+	if exists, err := s.Exists(ctx, "latest"); err != nil || !exists {
+		t.Errorf("Exists(latest) = %v, %v", exists, err)
+	}
+	rc, err := s.GetRange(ctx, "latest", 1, 2)
+	if err != nil {
+		t.Fatalf("GetRange: %v", err)
+	}
+	data, _ := io.ReadAll(rc)
+	_ = rc.Close()
+	if string(data) != "ew" {
+		t.Errorf("GetRange(latest) = %q, want ew", data)
+	}
+}
+
+func TestSetRejectsInvalidAliases(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStorage(t, map[string]string{"a": "1", "b": "2"})
+	manager := s.Manager()
+	if _, err := manager.Set(ctx, "to-a", "a"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	tests := []struct {
+		name, alias, target string
+		want                error
+	}{
+		{"target missing", "x", "missing", ErrInvalidTarget},
+		{"target is alias", "x", "to-a", ErrInvalidTarget},
+		{"self", "x", "x", ErrInvalidTarget},
+		{"name holds object", "b", "a", ErrNameInUse},
+		{"reserved name", DefaultPrefix + "x", "a", common.ErrReservedKey},
+		{"invalid name", "../x", "a", common.ErrInvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := manager.Set(ctx, tt.alias, tt.target); !errors.Is(err, tt.want) {
+				t.Errorf("Set(%s, %s) = %v, want %v", tt.alias, tt.target, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestObjectsShadowAliases(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStorage(t, map[string]string{"target": "aliased"})
+	if _, err := s.Manager().Set(ctx, "latest", "target"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := s.PutWithContext(ctx, "latest", strings.NewReader("object")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if got := read(t, s, "latest"); got != "object" {
+		t.Errorf("Get(latest) = %q, want object", got)
+	}
+}
+
+func TestDanglingAndDeletedAliases(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStorage(t, map[string]string{"target": "data"})
+	manager := s.Manager()
+	if _, err := manager.Set(ctx, "latest", "target"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	if err := s.DeleteWithContext(ctx, "target"); err != nil {
+		t.Fatalf("Delete target: %v", err)
+	}
+	if _, err := s.GetWithContext(ctx, "latest"); !errors.Is(err, common.ErrNotFound) {
+		t.Errorf("Get dangling alias = %v, want ErrNotFound", err)
+	}
+	if exists, err := s.Exists(ctx, "latest"); err != nil || exists {
+		t.Errorf("Exists dangling alias = %v, %v", exists, err)
+	}
+
+	if err := manager.Delete(ctx, "latest"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := manager.Resolve(ctx, "latest"); !errors.Is(err, ErrAliasNotFound) {
+		t.Errorf("Resolve deleted = %v, want ErrAliasNotFound", err)
+	}
+	if err := manager.Delete(ctx, "latest"); !errors.Is(err, ErrAliasNotFound) {
+		t.Errorf("Delete missing = %v, want ErrAliasNotFound", err)
+	}
+}
+
+func TestList(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStorage(t, map[string]string{"a": "1"})
+	manager := s.Manager()
+	for _, name := range []string{"web/stable", "app/latest", "app/stable"} {
+		if _, err := manager.Set(ctx, name, "a"); err != nil {
+			t.Fatalf("Set(%s): %v", name, err)
+		}
+	}
+
+	aliases, err := manager.List(ctx, "app/")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	var names []string
+	for _, a := range aliases {
+		names = append(names, a.Name)
+	}
+	if got := strings.Join(names, ","); got != "app/latest,app/stable" {
+		t.Errorf("List(app/) = %s", got)
+	}
+}
+
+func TestStorageReservesNamespace(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStorage(t, map[string]string{"a": "1"})
+	if _, err := s.Manager().Set(ctx, "latest", "a"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	key := DefaultPrefix + "latest"
+	if err := s.PutWithContext(ctx, key, strings.NewReader("{}")); !errors.Is(err, common.ErrReservedKey) {
+		t.Errorf("Put reserved = %v, want common.ErrReservedKey", err)
+	}
+	if err := s.DeleteWithContext(ctx, key); !errors.Is(err, common.ErrReservedKey) {
+		t.Errorf("Delete reserved = %v, want common.ErrReservedKey", err)
+	}
+	if err := s.Compose(ctx, key, "a"); !errors.Is(err, common.ErrReservedKey) {
+		t.Errorf("Compose reserved = %v, want common.ErrReservedKey", err)
+	}
+	if !strings.Contains(read(t, s, key), `"target":"a"`) {
+		t.Error("alias object not readable")
+	}
+}
@@ -0,0 +1,225 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package alias
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// Storage wraps a backend, resolves aliases on reads and reserves a
+// Manager's alias namespace. Reading a key that holds no object reads the
+// target of the alias of that name; writes and deletes of keys under the
+// alias prefix fail with common.ErrReservedKey, so aliases only change through the
+// Manager. Alias objects stay readable and listable as plain JSON objects.
+type Storage struct {
+	common.Storage
+	manager *Manager
+}
+
+// NewStorage returns underlying wrapped so that manager's aliases resolve
+// and its alias prefix is reserved.
+func NewStorage(underlying common.Storage, manager *Manager) *Storage {
+	return &Storage{Storage: underlying, manager: manager}
+}
+
+// Manager returns the alias manager whose aliases s resolves.
+func (s *Storage) Manager() *Manager {
+	return s.manager
+}
+
+// Underlying returns the wrapped backend.
+func (s *Storage) Underlying() common.Storage {
+	return s.Storage
+}
+
+func (s *Storage) check(key string) error {
+	if s.manager.Reserved(key) {
+		return fmt.Errorf("%w: %s", common.ErrReservedKey, key)
+	}
+	return nil
+}
+
+// resolve returns the target of the alias key after a read of key found no
+// object, or err if there is no such alias.
+func (s *Storage) resolve(ctx context.Context, key string, err error) (string, error) {
+	if !errors.Is(err, common.ErrNotFound) || s.manager.Reserved(key) {
+		return "", err
+	}
+	alias, aliasErr := s.manager.read(ctx, key)
+	if aliasErr != nil {
+		return "", err
+	}
+	return alias.Target, nil
+}
+
+// Put stores an object outside the alias namespace.
+func (s *Storage) Put(key string, data io.Reader) error {
+	return s.PutWithContext(context.Background(), key, data)
+}
+
+// PutWithContext stores an object outside the alias namespace.
+func (s *Storage) PutWithContext(ctx context.Context, key string, data io.Reader) error {
+	if err := s.check(key); err != nil {
+		return err
+	}
+	return s.Storage.PutWithContext(ctx, key, data)
+}
+
+// PutWithMetadata stores an object with metadata outside the alias
+// namespace.
+func (s *Storage) PutWithMetadata(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) error {
+	if err := s.check(key); err != nil {
+		return err
+	}
+	return s.Storage.PutWithMetadata(ctx, key, data, metadata)
+}
+
+// PutWithInfo stores an object with metadata outside the alias namespace and
+// returns what was stored.
+func (s *Storage) PutWithInfo(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) (*common.ObjectInfo, error) {
+	if err := s.check(key); err != nil {
+		return nil, err
+	}
+	return common.PutWithInfo(ctx, s.Storage, key, data, metadata)
+}
+
+// Get retrieves an object, or the target of the alias key.
+func (s *Storage) Get(key string) (io.ReadCloser, error) {
+	return s.GetWithContext(context.Background(), key)
+}
+
+// GetWithContext retrieves an object, or the target of the alias key.
+func (s *Storage) GetWithContext(ctx context.Context, key string) (io.ReadCloser, error) {
+	rc, err := s.Storage.GetWithContext(ctx, key)
+	if err == nil {
+		return rc, nil
+	}
+	target, err := s.resolve(ctx, key, err)
+	if err != nil {
+		return nil, err
+	}
+	return s.Storage.GetWithContext(ctx, target)
+}
+
+// GetRange reads a byte range of an object, or of the target of the alias
+// key, falling back to discarding the leading bytes of a full read when the
+// wrapped backend cannot read ranges.
+func (s *Storage) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	rr, ok := s.Storage.(common.RangeReader)
+	if !ok {
+		rc, err := s.GetWithContext(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		return common.SliceRange(rc, offset, length)
+	}
+	rc, err := rr.GetRange(ctx, key, offset, length)
+	if err == nil {
+		return rc, nil
+	}
+	target, err := s.resolve(ctx, key, err)
+	if err != nil {
+		return nil, err
+	}
+	return rr.GetRange(ctx, target, offset, length)
+}
+
+// GetAsOf reads an earlier version of an object, or of the current target
+// of the alias key.
+func (s *Storage) GetAsOf(ctx context.Context, key string, at time.Time) (io.ReadCloser, *common.Metadata, error) {
+	rc, metadata, err := common.GetAsOf(ctx, s.Storage, key, at)
+	if err == nil {
+		return rc, metadata, nil
+	}
+	target, err := s.resolve(ctx, key, err)
+	if err != nil {
+		return nil, nil, err
+	}
+	return common.GetAsOf(ctx, s.Storage, target, at)
+}
+
+// GetMetadata retrieves the metadata of an object, or of the target of the
+// alias key.
+func (s *Storage) GetMetadata(ctx context.Context, key string) (*common.Metadata, error) {
+	metadata, err := s.Storage.GetMetadata(ctx, key)
+	if err == nil {
+		return metadata, nil
+	}
+	target, err := s.resolve(ctx, key, err)
+	if err != nil {
+		return nil, err
+	}
+	return s.Storage.GetMetadata(ctx, target)
+}
+
+// Exists reports whether an object exists at key, or key is an alias whose
+// target exists.
+func (s *Storage) Exists(ctx context.Context, key string) (bool, error) {
+	exists, err := s.Storage.Exists(ctx, key)
+	if err != nil || exists || s.manager.Reserved(key) {
+		return exists, err
+	}
+	alias, err := s.manager.read(ctx, key)
+	if err != nil {
+		return false, nil
+	}
+	return s.Storage.Exists(ctx, alias.Target)
+}
+
+// UpdateMetadata updates the metadata of an object outside the alias
+// namespace.
+func (s *Storage) UpdateMetadata(ctx context.Context, key string, metadata *common.Metadata) error {
+	if err := s.check(key); err != nil {
+		return err
+	}
+	return s.Storage.UpdateMetadata(ctx, key, metadata)
+}
+
+// Delete removes an object outside the alias namespace.
+func (s *Storage) Delete(key string) error {
+	return s.DeleteWithContext(context.Background(), key)
+}
+
+// DeleteWithContext removes an object outside the alias namespace. Aliases
+// are removed with Manager.Delete; deleting an alias's target leaves the
+// alias dangling.
+func (s *Storage) DeleteWithContext(ctx context.Context, key string) error {
+	if err := s.check(key); err != nil {
+		return err
+	}
+	return s.Storage.DeleteWithContext(ctx, key)
+}
+
+// Append adds data to the end of an object outside the alias namespace.
+func (s *Storage) Append(ctx context.Context, key string, data io.Reader) error {
+	if err := s.check(key); err != nil {
+		return err
+	}
+	return common.Append(ctx, s.Storage, key, data)
+}
+
+// Compose concatenates srcKeys into destKey, which must be outside the
+// alias namespace.
+func (s *Storage) Compose(ctx context.Context, destKey string, srcKeys ...string) error {
+	if err := s.check(destKey); err != nil {
+		return err
+	}
+	return common.Compose(ctx, s.Storage, destKey, srcKeys...)
+}
@@ -0,0 +1,366 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build alioss
+
+package alioss
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/transport"
+
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+// Constants
+const (
+	actionDelete     = "delete"
+	actionArchive    = "archive"
+	actionTransition = common.ActionTransition
+)
+
+// Signature versions selected with the "authVersion" setting.
+const (
+	AuthV1 = "v1"
+	AuthV4 = "v4"
+)
+
+const (
+	// DefaultPartSize is the size of the parts objects are uploaded in.
+	// Objects that fit in one part are uploaded with a single request.
+	DefaultPartSize = 8 * 1024 * 1024
+
+	// MinPartSize is the smallest part OSS accepts, except for the last.
+	MinPartSize = 100 * 1024
+
+	// MaxPartSize is the largest part OSS accepts.
+	MaxPartSize = 5 * 1024 * 1024 * 1024
+)
+
+// storageClassArchive is the class archive policies transition objects to.
+const storageClassArchive = string(oss.StorageArchive)
+
+// storageClasses are the OSS storage classes that can be selected per
+// object or as a lifecycle transition target.
+var storageClasses = []string{
+	string(oss.StorageStandard), string(oss.StorageIA), storageClassArchive,
+	string(oss.StorageColdArchive), string(oss.StorageDeepColdArchive),
+}
+
+// OSS is a storage backend that stores files in Alibaba Cloud OSS.
+type OSS struct {
+	api                ossAPI
+	bucket             string
+	partSize           int64
+	timeouts           *transport.Timeouts
+	policiesMutex      sync.RWMutex
+	replicationManager common.ReplicationManager
+}
+
+// New creates a new OSS storage backend.
+func New() common.Storage {
+	return &OSS{}
+}
+
+// Configure sets up the backend with the necessary settings.
+// Required settings:
+//   - bucket: the OSS bucket name
+//   - region: the region ID (e.g., "cn-hangzhou"), used to derive the
+//     endpoint and to sign requests
+//
+// Optional settings:
+//   - endpoint: overrides the regional endpoint (e.g., an accelerated or
+//     private endpoint); region may then be omitted with authVersion v1
+//   - internal: "true" selects the region's VPC (internal) endpoint
+//   - authVersion: request signature version, "v4" (default) or "v1"
+//   - credentials: static, environment, ecs-ram-role or file, see
+//     CredentialsStatic and the other constants; defaults to static when
+//     accessKeyId is set and environment otherwise
+//   - accessKeyId, accessKeySecret, securityToken: static credentials
+//   - ramRole: the RAM role for ecs-ram-role credentials
+//   - credentialsFile: the file for file credentials
+//   - partSize: multipart upload part size in bytes (default 8 MiB)
+//   - http*: HTTP transport tuning, see the transport package
+//   - timeout, getTimeout, ...: per-operation timeouts, see the transport
+//     package
+func (o *OSS) Configure(settings map[string]string) error {
+	o.bucket = settings["bucket"]
+	if o.bucket == "" {
+		return common.ErrBucketNotSet
+	}
+	partSize, err := parsePartSize(settings["partSize"])
+	if err != nil {
+		return err
+	}
+	o.partSize = partSize
+	timeouts, err := transport.ParseTimeouts(settings)
+	if err != nil {
+		return err
+	}
+	o.timeouts = timeouts
+
+	authVersion := settings["authVersion"]
+	if authVersion == "" {
+		authVersion = AuthV4
+	}
+	if authVersion != AuthV1 && authVersion != AuthV4 {
+		return fmt.Errorf("%w: unknown authVersion %q", common.ErrInvalidArgument, authVersion)
+	}
+	region := strings.TrimPrefix(settings["region"], "oss-")
+	if region == "" && authVersion == AuthV4 {
+		return common.ErrRegionNotSet
+	}
+	endpoint, err := endpointFor(settings, region)
+	if err != nil {
+		return err
+	}
+
+	httpCfg, err := transport.ParseSettings(settings)
+	if err != nil {
+		return err
+	}
+	identity := settings["accessKeyId"] + "|" + settings["ramRole"] + "|" + settings["credentialsFile"] + "|" + endpoint
+	httpClient := transport.Default.Client("oss", identity, httpCfg)
+	provider, err := newCredentialsProvider(settings, httpClient)
+	if err != nil {
+		return err
+	}
+
+	opts := []oss.ClientOption{oss.HTTPClient(httpClient), oss.SetCredentialsProvider(provider)}
+	if authVersion == AuthV4 {
+		opts = append(opts, oss.AuthVersion(oss.AuthV4), oss.Region(region))
+	}
+	client, err := oss.New(endpoint, "", "", opts...)
+	if err != nil {
+		return fmt.Errorf("%w: %v", common.ErrInvalidArgument, err)
+	}
+	bucket, err := client.Bucket(o.bucket)
+	if err != nil {
+		return fmt.Errorf("%w: %v", common.ErrInvalidArgument, err)
+	}
+	o.api = &sdkAPI{client: client, bucket: bucket}
+	return nil
+}
+
+// parsePartSize parses the partSize setting.
+func parsePartSize(value string) (int64, error) {
+	if value == "" {
+		return DefaultPartSize, nil
+	}
+	size, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || size < MinPartSize || size > MaxPartSize {
+		return 0, fmt.Errorf("%w: partSize must be between %d and %d bytes", common.ErrInvalidArgument, MinPartSize, MaxPartSize)
+	}
+	return size, nil
+}
+
+// Put stores an object in the backend.
+func (o *OSS) Put(key string, data io.Reader) error {
+	return o.PutWithContext(context.Background(), key, data)
+}
+
+// Get retrieves an object from the backend.
+func (o *OSS) Get(key string) (io.ReadCloser, error) {
+	return o.GetWithContext(context.Background(), key)
+}
+
+// Delete removes an object from the backend.
+func (o *OSS) Delete(key string) error {
+	return o.DeleteWithContext(context.Background(), key)
+}
+
+// List returns a list of keys that start with the given prefix.
+func (o *OSS) List(prefix string) ([]string, error) {
+	return o.ListWithContext(context.Background(), prefix)
+}
+
+// Archive copies an object to another backend for archival.
+func (o *OSS) Archive(key string, destination common.Archiver) error {
+	if destination == nil {
+		return common.ErrArchiveDestinationNil
+	}
+	rc, err := o.Get(key)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rc.Close() }()
+
+	return destination.Put(key, rc)
+}
+
+// AddPolicy adds a new lifecycle policy by configuring OSS bucket lifecycle
+// rules. A rule with the same ID is replaced.
+func (o *OSS) AddPolicy(policy common.LifecyclePolicy) error {
+	if policy.ID == "" {
+		return common.ErrInvalidPolicy
+	}
+	if policy.Action != actionDelete && policy.Action != actionArchive && policy.Action != actionTransition {
+		return common.ErrInvalidPolicy
+	}
+	transitionClass := storageClassArchive
+	if policy.Action == actionTransition {
+		class, err := common.NormalizeStorageClass(policy.StorageClass, storageClasses)
+		if err != nil {
+			return err
+		}
+		if class == "" {
+			return fmt.Errorf("%w: transition policy requires a storage class", common.ErrInvalidArgument)
+		}
+		transitionClass = class
+	}
+
+	o.policiesMutex.Lock()
+	defer o.policiesMutex.Unlock()
+
+	ctx, cancel := o.timeouts.Context(context.Background(), transport.OpMetadata)
+	defer cancel()
+	existing, err := o.lifecycleRules(ctx)
+	if err != nil {
+		return err
+	}
+	rules := make([]oss.LifecycleRule, 0, len(existing)+1)
+	for _, rule := range existing {
+		if rule.ID != policy.ID {
+			rules = append(rules, rule)
+		}
+	}
+
+	// Convert retention duration to days (minimum 1 day)
+	days := int(policy.Retention.Hours() / 24)
+	if days < 1 {
+		days = 1
+	}
+	rule := oss.LifecycleRule{ID: policy.ID, Prefix: policy.Prefix, Status: "Enabled"}
+	if policy.Action == actionDelete {
+		rule.Expiration = &oss.LifecycleExpiration{Days: days}
+	} else {
+		rule.Transitions = []oss.LifecycleTransition{{Days: days, StorageClass: oss.StorageClassType(transitionClass)}}
+	}
+	rules = append(rules, rule)
+
+	return translateError(o.api.SetLifecycle(ctx, rules), "")
+}
+
+// RemovePolicy removes a lifecycle policy by updating OSS bucket lifecycle
+// rules.
+func (o *OSS) RemovePolicy(id string) error {
+	o.policiesMutex.Lock()
+	defer o.policiesMutex.Unlock()
+
+	ctx, cancel := o.timeouts.Context(context.Background(), transport.OpMetadata)
+	defer cancel()
+	existing, err := o.lifecycleRules(ctx)
+	if err != nil {
+		return err
+	}
+	rules := make([]oss.LifecycleRule, 0, len(existing))
+	for _, rule := range existing {
+		if rule.ID != id {
+			rules = append(rules, rule)
+		}
+	}
+	if len(rules) == len(existing) {
+		return nil
+	}
+	// OSS rejects an empty lifecycle configuration, so the last rule is
+	// removed by deleting the configuration.
+	if len(rules) == 0 {
+		return translateError(o.api.DeleteLifecycle(ctx), "")
+	}
+	return translateError(o.api.SetLifecycle(ctx, rules), "")
+}
+
+// GetPolicies returns all lifecycle policies by fetching OSS bucket
+// lifecycle rules. Disabled rules and rules that are neither an expiration
+// nor a transition are skipped.
+func (o *OSS) GetPolicies() ([]common.LifecyclePolicy, error) {
+	o.policiesMutex.RLock()
+	defer o.policiesMutex.RUnlock()
+
+	ctx, cancel := o.timeouts.Context(context.Background(), transport.OpMetadata)
+	defer cancel()
+	rules, err := o.lifecycleRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	policies := make([]common.LifecyclePolicy, 0, len(rules))
+	for _, rule := range rules {
+		if rule.Status != "Enabled" {
+			continue
+		}
+		policy := common.LifecyclePolicy{ID: rule.ID, Prefix: rule.Prefix}
+		switch {
+		case rule.Expiration != nil && rule.Expiration.Days > 0:
+			policy.Action = actionDelete
+			policy.Retention = time.Duration(rule.Expiration.Days) * 24 * time.Hour
+		case len(rule.Transitions) > 0 && rule.Transitions[0].Days > 0:
+			policy.Action = actionArchive
+			if class := string(rule.Transitions[0].StorageClass); class != storageClassArchive {
+				policy.Action = actionTransition
+				policy.StorageClass = class
+			}
+			policy.Retention = time.Duration(rule.Transitions[0].Days) * 24 * time.Hour
+		default:
+			// Skip rules we don't understand
+			continue
+		}
+		policies = append(policies, policy)
+	}
+	return policies, nil
+}
+
+// lifecycleRules returns the bucket's lifecycle rules; a bucket without a
+// lifecycle configuration has none.
+func (o *OSS) lifecycleRules(ctx context.Context) ([]oss.LifecycleRule, error) {
+	rules, err := o.api.GetLifecycle(ctx)
+	if err != nil {
+		if isNoSuchLifecycle(err) {
+			return nil, nil
+		}
+		return nil, translateError(err, "")
+	}
+	return rules, nil
+}
+
+// isNoSuchLifecycle reports whether err says the bucket has no lifecycle
+// configuration.
+func isNoSuchLifecycle(err error) bool {
+	var serr oss.ServiceError
+	return errors.As(err, &serr) && serr.Code == "NoSuchLifecycle"
+}
+
+// GetReplicationManager returns the replication manager for this backend.
+// This method implements the common.ReplicationCapable interface.
+func (o *OSS) GetReplicationManager() (common.ReplicationManager, error) {
+	if o.replicationManager == nil {
+		return nil, common.ErrReplicationNotSupported
+	}
+	return o.replicationManager, nil
+}
+
+// SetReplicationManager allows manually setting a replication manager.
+// This is useful for testing or when you want to share a replication manager
+// across multiple backends.
+func (o *OSS) SetReplicationManager(rm common.ReplicationManager) {
+	o.replicationManager = rm
+}
@@ -0,0 +1,218 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build alioss
+
+package alioss
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+// objectAttrs are the attributes of an object as stored by OSS.
+type objectAttrs struct {
+	Size            int64
+	ETag            string
+	LastModified    time.Time
+	ContentType     string
+	ContentEncoding string
+	StorageClass    string
+	Meta            map[string]string
+}
+
+// listPage is one page of a bucket listing.
+type listPage struct {
+	Objects        []oss.ObjectProperties
+	CommonPrefixes []string
+	NextToken      string
+}
+
+// ossAPI is the subset of OSS operations the backend uses. It takes plain
+// values rather than SDK options so tests can substitute an in-memory fake.
+type ossAPI interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, attrs *objectAttrs) (string, error)
+	Get(ctx context.Context, key, byteRange string) (io.ReadCloser, error)
+	Head(ctx context.Context, key string) (*objectAttrs, error)
+	Copy(ctx context.Context, key string, attrs *objectAttrs) error
+	Delete(ctx context.Context, key string) error
+	List(ctx context.Context, prefix, delimiter, token string, limit int) (*listPage, error)
+
+	InitiateMultipart(ctx context.Context, key string, attrs *objectAttrs) (string, error)
+	UploadPart(ctx context.Context, key, uploadID string, r io.Reader, size int64, part int) (string, error)
+	UploadPartCopy(ctx context.Context, key, uploadID, source string, offset, size int64, part int) (string, error)
+	CompleteMultipart(ctx context.Context, key, uploadID string, parts []oss.UploadPart) (string, error)
+	AbortMultipart(ctx context.Context, key, uploadID string) error
+
+	GetLifecycle(ctx context.Context) ([]oss.LifecycleRule, error)
+	SetLifecycle(ctx context.Context, rules []oss.LifecycleRule) error
+	DeleteLifecycle(ctx context.Context) error
+}
+
+// sdkAPI implements ossAPI with the OSS SDK.
+type sdkAPI struct {
+	client *oss.Client
+	bucket *oss.Bucket
+}
+
+// options returns the SDK options that carry ctx and attrs.
+func options(ctx context.Context, attrs *objectAttrs) []oss.Option {
+	opts := []oss.Option{oss.WithContext(ctx)}
+	if attrs == nil {
+		return opts
+	}
+	if attrs.ContentType != "" {
+		opts = append(opts, oss.ContentType(attrs.ContentType))
+	}
+	if attrs.ContentEncoding != "" {
+		opts = append(opts, oss.ContentEncoding(attrs.ContentEncoding))
+	}
+	if attrs.StorageClass != "" {
+		opts = append(opts, oss.ObjectStorageClass(oss.StorageClassType(attrs.StorageClass)))
+	}
+	for k, v := range attrs.Meta {
+		opts = append(opts, oss.Meta(k, v))
+	}
+	return opts
+}
+
+func (a *sdkAPI) Put(ctx context.Context, key string, r io.Reader, size int64, attrs *objectAttrs) (string, error) {
+	var header http.Header
+	opts := append(options(ctx, attrs), oss.ContentLength(size), oss.GetResponseHeader(&header))
+	if err := a.bucket.PutObject(key, r, opts...); err != nil {
+		return "", err
+	}
+	return strings.Trim(header.Get(oss.HTTPHeaderEtag), `"`), nil
+}
+
+func (a *sdkAPI) Get(ctx context.Context, key, byteRange string) (io.ReadCloser, error) {
+	opts := []oss.Option{oss.WithContext(ctx)}
+	if byteRange != "" {
+		// Without the standard behavior header OSS ignores a range that
+		// ends past the object and returns the whole object.
+		opts = append(opts, oss.NormalizedRange(strings.TrimPrefix(byteRange, "bytes=")), oss.RangeBehavior("standard"))
+	}
+	return a.bucket.GetObject(key, opts...)
+}
+
+func (a *sdkAPI) Head(ctx context.Context, key string) (*objectAttrs, error) {
+	header, err := a.bucket.GetObjectDetailedMeta(key, oss.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return attrsFromHeader(header), nil
+}
+
+func (a *sdkAPI) Copy(ctx context.Context, key string, attrs *objectAttrs) error {
+	opts := append(options(ctx, attrs), oss.MetadataDirective(oss.MetaReplace))
+	_, err := a.bucket.CopyObject(key, key, opts...)
+	return err
+}
+
+func (a *sdkAPI) Delete(ctx context.Context, key string) error {
+	return a.bucket.DeleteObject(key, oss.WithContext(ctx))
+}
+
+func (a *sdkAPI) List(ctx context.Context, prefix, delimiter, token string, limit int) (*listPage, error) {
+	opts := []oss.Option{oss.WithContext(ctx), oss.Prefix(prefix), oss.MaxKeys(limit)}
+	if delimiter != "" {
+		opts = append(opts, oss.Delimiter(delimiter))
+	}
+	if token != "" {
+		opts = append(opts, oss.ContinuationToken(token))
+	}
+	result, err := a.bucket.ListObjectsV2(opts...)
+	if err != nil {
+		return nil, err
+	}
+	page := &listPage{Objects: result.Objects, CommonPrefixes: result.CommonPrefixes}
+	if result.IsTruncated {
+		page.NextToken = result.NextContinuationToken
+	}
+	return page, nil
+}
+
+func (a *sdkAPI) upload(key, uploadID string) oss.InitiateMultipartUploadResult {
+	return oss.InitiateMultipartUploadResult{Bucket: a.bucket.BucketName, Key: key, UploadID: uploadID}
+}
+
+func (a *sdkAPI) InitiateMultipart(ctx context.Context, key string, attrs *objectAttrs) (string, error) {
+	imur, err := a.bucket.InitiateMultipartUpload(key, options(ctx, attrs)...)
+	return imur.UploadID, err
+}
+
+func (a *sdkAPI) UploadPart(ctx context.Context, key, uploadID string, r io.Reader, size int64, part int) (string, error) {
+	result, err := a.bucket.UploadPart(a.upload(key, uploadID), r, size, part, oss.WithContext(ctx))
+	return result.ETag, err
+}
+
+func (a *sdkAPI) UploadPartCopy(ctx context.Context, key, uploadID, source string, offset, size int64, part int) (string, error) {
+	result, err := a.bucket.UploadPartCopy(a.upload(key, uploadID), a.bucket.BucketName, source, offset, size, part, oss.WithContext(ctx))
+	return result.ETag, err
+}
+
+func (a *sdkAPI) CompleteMultipart(ctx context.Context, key, uploadID string, parts []oss.UploadPart) (string, error) {
+	result, err := a.bucket.CompleteMultipartUpload(a.upload(key, uploadID), parts, oss.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	return strings.Trim(result.ETag, `"`), nil
+}
+
+func (a *sdkAPI) AbortMultipart(ctx context.Context, key, uploadID string) error {
+	return a.bucket.AbortMultipartUpload(a.upload(key, uploadID), oss.WithContext(ctx))
+}
+
+func (a *sdkAPI) GetLifecycle(ctx context.Context) ([]oss.LifecycleRule, error) {
+	result, err := a.client.GetBucketLifecycle(a.bucket.BucketName, oss.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return result.Rules, nil
+}
+
+func (a *sdkAPI) SetLifecycle(ctx context.Context, rules []oss.LifecycleRule) error {
+	return a.client.SetBucketLifecycle(a.bucket.BucketName, rules, oss.WithContext(ctx))
+}
+
+func (a *sdkAPI) DeleteLifecycle(ctx context.Context) error {
+	return a.client.DeleteBucketLifecycle(a.bucket.BucketName, oss.WithContext(ctx))
+}
+
+// attrsFromHeader parses the response headers of a HEAD request. Custom
+// metadata keys are lower-cased because OSS does not preserve their case.
+func attrsFromHeader(header http.Header) *objectAttrs {
+	attrs := &objectAttrs{
+		ETag:            strings.Trim(header.Get(oss.HTTPHeaderEtag), `"`),
+		ContentType:     header.Get(oss.HTTPHeaderContentType),
+		ContentEncoding: header.Get(oss.HTTPHeaderContentEncoding),
+		StorageClass:    header.Get(oss.HTTPHeaderOssStorageClass),
+		Meta:            map[string]string{},
+	}
+	attrs.Size, _ = strconv.ParseInt(header.Get(oss.HTTPHeaderContentLength), 10, 64)
+	attrs.LastModified, _ = http.ParseTime(header.Get(oss.HTTPHeaderLastModified))
+	for name, values := range header {
+		if len(values) == 0 || len(name) <= len(oss.HTTPHeaderOssMetaPrefix) ||
+			!strings.EqualFold(name[:len(oss.HTTPHeaderOssMetaPrefix)], oss.HTTPHeaderOssMetaPrefix) {
+			continue
+		}
+		attrs.Meta[strings.ToLower(name[len(oss.HTTPHeaderOssMetaPrefix):])] = values[0]
+	}
+	return attrs
+}
@@ -0,0 +1,198 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build alioss
+
+package alioss
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+func TestOSS_Configure(t *testing.T) {
+	base := map[string]string{
+		"bucket":          "bucket",
+		"region":          "cn-hangzhou",
+		"accessKeyId":     "id",
+		"accessKeySecret": "secret",
+	}
+	with := func(changes map[string]string) map[string]string {
+		settings := map[string]string{}
+		for k, v := range base {
+			settings[k] = v
+		}
+		for k, v := range changes {
+			if v == "" {
+				delete(settings, k)
+			} else {
+				settings[k] = v
+			}
+		}
+		return settings
+	}
+	tests := []struct {
+		name     string
+		settings map[string]string
+		want     error
+	}{
+		{"valid", base, nil},
+		{"region with oss prefix", with(map[string]string{"region": "oss-cn-beijing"}), nil},
+		{"v1 with endpoint and no region", with(map[string]string{"region": "", "authVersion": "v1", "endpoint": "https://oss.example.com"}), nil},
+		{"missing bucket", with(map[string]string{"bucket": ""}), common.ErrBucketNotSet},
+		{"missing region", with(map[string]string{"region": ""}), common.ErrRegionNotSet},
+		{"v1 without endpoint or region", with(map[string]string{"region": "", "authVersion": "v1"}), common.ErrRegionNotSet},
+		{"unknown auth version", with(map[string]string{"authVersion": "v2"}), common.ErrInvalidArgument},
+		{"part size too small", with(map[string]string{"partSize": "1024"}), common.ErrInvalidArgument},
+		{"part size not a number", with(map[string]string{"partSize": "big"}), common.ErrInvalidArgument},
+		{"missing secret", with(map[string]string{"accessKeySecret": ""}), common.ErrSecretKeyNotSet},
+		{"static without id", with(map[string]string{"credentials": "static", "accessKeyId": ""}), common.ErrAccessKeyNotSet},
+		{"ram role without role", with(map[string]string{"credentials": "ecs-ram-role"}), common.ErrInvalidArgument},
+		{"unknown credentials", with(map[string]string{"credentials": "magic"}), common.ErrInvalidArgument},
+		{"bad timeout", with(map[string]string{"timeout": "soon"}), common.ErrInvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &OSS{}
+			err := o.Configure(tt.settings)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("Configure() error = %v", err)
+				}
+				if o.api == nil || o.partSize != DefaultPartSize {
+					t.Errorf("Configure() left api = %v, partSize = %d", o.api, o.partSize)
+				}
+				return
+			}
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Configure() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestOSS_ConfigureEnvironmentCredentials(t *testing.T) {
+	settings := map[string]string{"bucket": "bucket", "region": "cn-hangzhou"}
+	t.Setenv("OSS_ACCESS_KEY_ID", "")
+	t.Setenv("OSS_ACCESS_KEY_SECRET", "")
+	if err := (&OSS{}).Configure(settings); !errors.Is(err, common.ErrAccessKeyNotSet) {
+		t.Errorf("Configure() without environment credentials error = %v, want ErrAccessKeyNotSet", err)
+	}
+	t.Setenv("OSS_ACCESS_KEY_ID", "id")
+	t.Setenv("OSS_ACCESS_KEY_SECRET", "secret")
+	if err := (&OSS{}).Configure(settings); err != nil {
+		t.Errorf("Configure() with environment credentials error = %v", err)
+	}
+}
+
+func TestEndpointFor(t *testing.T) {
+	tests := []struct {
+		settings map[string]string
+		region   string
+		want     string
+	}{
+		{map[string]string{}, "cn-hangzhou", "https://oss-cn-hangzhou.aliyuncs.com"},
+		{map[string]string{"internal": "true"}, "ap-southeast-1", "https://oss-ap-southeast-1-internal.aliyuncs.com"},
+		{map[string]string{"endpoint": "https://oss-accelerate.aliyuncs.com"}, "cn-hangzhou", "https://oss-accelerate.aliyuncs.com"},
+	}
+	for _, tt := range tests {
+		if got, err := endpointFor(tt.settings, tt.region); err != nil || got != tt.want {
+			t.Errorf("endpointFor(%v, %q) = %q, %v, want %q", tt.settings, tt.region, got, err, tt.want)
+		}
+	}
+}
+
+func TestFileProvider(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "oss.env")
+	if err := os.WriteFile(path, []byte("accessKeyId=id1\naccessKeySecret=secret1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	provider, err := newCredentialsProvider(map[string]string{"credentials": "file", "credentialsFile": path}, http.DefaultClient)
+	if err != nil {
+		t.Fatalf("newCredentialsProvider() error = %v", err)
+	}
+	creds := provider.GetCredentials()
+	if creds.GetAccessKeyID() != "id1" || creds.GetAccessKeySecret() != "secret1" || creds.GetSecurityToken() != "" {
+		t.Errorf("credentials = %+v", creds)
+	}
+
+	empty := filepath.Join(t.TempDir(), "empty.env")
+	if err := os.WriteFile(empty, []byte("region=cn-hangzhou\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newCredentialsProvider(map[string]string{"credentials": "file", "credentialsFile": empty}, http.DefaultClient); err == nil {
+		t.Error("newCredentialsProvider() accepted a file without keys")
+	}
+}
+
+func TestRAMRoleProvider(t *testing.T) {
+	var requests atomic.Int32
+	var fail atomic.Bool
+	expires := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if fail.Load() || r.URL.Path != "/latest/meta-data/ram/security-credentials/app-role" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"Code":"Success","AccessKeyId":"STS.id","AccessKeySecret":"secret",` +
+			`"SecurityToken":"token","Expiration":"2030-01-01T12:00:00Z"}`))
+	}))
+	defer srv.Close()
+	old := ecsMetadataURL
+	ecsMetadataURL = srv.URL + "/latest/meta-data/ram/security-credentials/"
+	defer func() { ecsMetadataURL = old }()
+
+	now := expires.Add(-time.Hour)
+	provider, err := newCredentialsProvider(map[string]string{"credentials": "ecs-ram-role", "ramRole": "app-role"}, srv.Client())
+	if err != nil {
+		t.Fatalf("newCredentialsProvider() error = %v", err)
+	}
+	p := provider.(*ramRoleProvider)
+	p.now = func() time.Time { return now }
+
+	creds, err := p.GetCredentialsE()
+	if err != nil {
+		t.Fatalf("GetCredentialsE() error = %v", err)
+	}
+	if creds.GetAccessKeyID() != "STS.id" || creds.GetSecurityToken() != "token" {
+		t.Errorf("credentials = %+v", creds)
+	}
+	_, _ = p.GetCredentialsE()
+	if n := requests.Load(); n != 1 {
+		t.Errorf("metadata requests = %d, want cached credentials reused", n)
+	}
+
+	// Close to expiry the credentials are refreshed; a failed refresh keeps
+	// serving them until they actually expire.
+	now = expires.Add(-time.Minute)
+	fail.Store(true)
+	if creds, err := p.GetCredentialsE(); err != nil || creds.GetAccessKeyID() != "STS.id" {
+		t.Errorf("GetCredentialsE() during failed refresh = %+v, %v", creds, err)
+	}
+	now = expires.Add(time.Minute)
+	if _, err := p.GetCredentialsE(); !errors.Is(err, common.ErrUnauthenticated) {
+		t.Errorf("GetCredentialsE() after expiry error = %v, want ErrUnauthenticated", err)
+	}
+	if n := requests.Load(); n != 3 {
+		t.Errorf("metadata requests = %d, want 3", n)
+	}
+}
@@ -0,0 +1,244 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build alioss
+
+package alioss
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/credfile"
+
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+// Credential providers selected with the "credentials" setting.
+const (
+	// CredentialsStatic uses the accessKeyId, accessKeySecret and optional
+	// securityToken settings. A security token makes them temporary STS
+	// credentials.
+	CredentialsStatic = "static"
+
+	// CredentialsEnvironment reads OSS_ACCESS_KEY_ID, OSS_ACCESS_KEY_SECRET
+	// and OSS_SESSION_TOKEN on every request.
+	CredentialsEnvironment = "environment"
+
+	// CredentialsECSRAMRole fetches STS credentials for the RAM role
+	// attached to the ECS instance, named by ramRole, from the instance
+	// metadata service and refreshes them before they expire.
+	CredentialsECSRAMRole = "ecs-ram-role"
+
+	// CredentialsFile reads accessKeyId, accessKeySecret and securityToken
+	// from credentialsFile and re-reads it when it changes.
+	CredentialsFile = "file"
+)
+
+// ecsMetadataURL is where the ECS instance metadata service serves RAM role
+// credentials.
+var ecsMetadataURL = "http://100.100.100.200/latest/meta-data/ram/security-credentials/"
+
+// credentialsRefreshMargin is how long before expiry RAM role credentials
+// are refreshed.
+const credentialsRefreshMargin = 5 * time.Minute
+
+// credentials is a set of OSS credentials.
+type credentials struct {
+	id, secret, token string
+}
+
+func (c credentials) GetAccessKeyID() string     { return c.id }
+func (c credentials) GetAccessKeySecret() string { return c.secret }
+func (c credentials) GetSecurityToken() string   { return c.token }
+
+// newCredentialsProvider returns the credentials provider selected by
+// settings. Without a "credentials" setting, static credentials are used
+// when accessKeyId is set and the environment otherwise.
+func newCredentialsProvider(settings map[string]string, client *http.Client) (oss.CredentialsProvider, error) {
+	provider := settings["credentials"]
+	if provider == "" {
+		provider = CredentialsEnvironment
+		if settings["accessKeyId"] != "" {
+			provider = CredentialsStatic
+		}
+	}
+
+	switch provider {
+	case CredentialsStatic:
+		if settings["accessKeyId"] == "" {
+			return nil, common.ErrAccessKeyNotSet
+		}
+		if settings["accessKeySecret"] == "" {
+			return nil, common.ErrSecretKeyNotSet
+		}
+		return staticProvider{credentials{settings["accessKeyId"], settings["accessKeySecret"], settings["securityToken"]}}, nil
+	case CredentialsEnvironment:
+		if os.Getenv("OSS_ACCESS_KEY_ID") == "" || os.Getenv("OSS_ACCESS_KEY_SECRET") == "" {
+			return nil, fmt.Errorf("%w: OSS_ACCESS_KEY_ID and OSS_ACCESS_KEY_SECRET must be set", common.ErrAccessKeyNotSet)
+		}
+		return environmentProvider{}, nil
+	case CredentialsECSRAMRole:
+		if settings["ramRole"] == "" {
+			return nil, fmt.Errorf("%w: ecs-ram-role credentials need ramRole", common.ErrInvalidArgument)
+		}
+		return &ramRoleProvider{role: settings["ramRole"], client: client, now: time.Now}, nil
+	case CredentialsFile:
+		file, err := credfile.Open(settings["credentialsFile"])
+		if err != nil {
+			return nil, err
+		}
+		p := &fileProvider{file: file}
+		if _, err := p.GetCredentialsE(); err != nil {
+			return nil, err
+		}
+		return p, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown credentials provider %q", common.ErrInvalidArgument, provider)
+	}
+}
+
+// staticProvider serves fixed credentials.
+type staticProvider struct{ creds credentials }
+
+func (p staticProvider) GetCredentials() oss.Credentials { return p.creds }
+
+// environmentProvider serves the credentials in the environment.
+type environmentProvider struct{}
+
+func (environmentProvider) GetCredentials() oss.Credentials {
+	return credentials{os.Getenv("OSS_ACCESS_KEY_ID"), os.Getenv("OSS_ACCESS_KEY_SECRET"), os.Getenv("OSS_SESSION_TOKEN")}
+}
+
+// fileProvider serves credentials from a credentials file, re-reading it
+// whenever it changes.
+type fileProvider struct {
+	file *credfile.File
+}
+
+func (p *fileProvider) GetCredentials() oss.Credentials {
+	creds, _ := p.GetCredentialsE() // #nosec G104 -- The SDK calls GetCredentialsE; this satisfies the interface
+	return creds
+}
+
+// GetCredentialsE returns the credentials in the file. A file that cannot
+// be re-read keeps the previous credentials.
+func (p *fileProvider) GetCredentialsE() (oss.Credentials, error) {
+	_ = p.file.Refresh() // #nosec G104 -- A failed reload keeps the current credentials
+	creds := credentials{
+		id:     p.file.Lookup("accessKeyId", "OSS_ACCESS_KEY_ID"),
+		secret: p.file.Lookup("accessKeySecret", "OSS_ACCESS_KEY_SECRET"),
+		token:  p.file.Lookup("securityToken", "OSS_SESSION_TOKEN"),
+	}
+	if creds.id == "" || creds.secret == "" {
+		return creds, fmt.Errorf("%w: %s has no accessKeyId and accessKeySecret", credfile.ErrInvalidFile, p.file.Path())
+	}
+	return creds, nil
+}
+
+// ramRoleProvider serves the STS credentials of an ECS instance RAM role.
+type ramRoleProvider struct {
+	role   string
+	client *http.Client
+	now    func() time.Time
+
+	mu      sync.Mutex
+	creds   credentials
+	expires time.Time
+}
+
+func (p *ramRoleProvider) GetCredentials() oss.Credentials {
+	creds, _ := p.GetCredentialsE() // #nosec G104 -- The SDK calls GetCredentialsE; this satisfies the interface
+	return creds
+}
+
+// GetCredentialsE returns the cached role credentials, fetching new ones
+// when they are about to expire.
+func (p *ramRoleProvider) GetCredentialsE() (oss.Credentials, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.creds.id != "" && p.now().Add(credentialsRefreshMargin).Before(p.expires) {
+		return p.creds, nil
+	}
+	creds, expires, err := p.fetch()
+	if err != nil {
+		if p.creds.id != "" && p.now().Before(p.expires) {
+			return p.creds, nil
+		}
+		return p.creds, err
+	}
+	p.creds, p.expires = creds, expires
+	return creds, nil
+}
+
+// fetch requests the role credentials from the instance metadata service.
+func (p *ramRoleProvider) fetch() (credentials, time.Time, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ecsMetadataURL+p.role, nil)
+	if err != nil {
+		return credentials{}, time.Time{}, err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return credentials{}, time.Time{}, fmt.Errorf("%w: ECS metadata service: %v", common.ErrUnauthenticated, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil {
+		return credentials{}, time.Time{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return credentials{}, time.Time{}, fmt.Errorf("%w: ECS metadata service returned %s for role %q",
+			common.ErrUnauthenticated, resp.Status, p.role)
+	}
+	var doc struct {
+		Code            string
+		AccessKeyID     string `json:"AccessKeyId"`
+		AccessKeySecret string
+		SecurityToken   string
+		Expiration      time.Time
+	}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return credentials{}, time.Time{}, fmt.Errorf("%w: ECS metadata service: %v", common.ErrUnauthenticated, err)
+	}
+	if !strings.EqualFold(doc.Code, "Success") || doc.AccessKeyID == "" {
+		return credentials{}, time.Time{}, fmt.Errorf("%w: ECS metadata service returned no credentials for role %q (code %q)",
+			common.ErrUnauthenticated, p.role, doc.Code)
+	}
+	return credentials{doc.AccessKeyID, doc.AccessKeySecret, doc.SecurityToken}, doc.Expiration, nil
+}
+
+// endpointFor returns the OSS endpoint for settings: the endpoint setting,
+// or the public or, with internal=true, the VPC endpoint of region.
+func endpointFor(settings map[string]string, region string) (string, error) {
+	if endpoint := settings["endpoint"]; endpoint != "" {
+		return endpoint, nil
+	}
+	if region == "" {
+		return "", common.ErrRegionNotSet
+	}
+	host := "oss-" + region
+	if settings["internal"] == "true" {
+		host += "-internal"
+	}
+	return "https://" + host + ".aliyuncs.com", nil
+}
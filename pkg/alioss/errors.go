@@ -0,0 +1,78 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build alioss
+
+package alioss
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+// translateError maps an OSS error for key to the common error taxonomy.
+// Errors that are neither OSS service errors nor network errors are
+// returned unchanged.
+func translateError(err error, key string) error {
+	if err == nil {
+		return nil
+	}
+	var serr oss.ServiceError
+	if errors.As(err, &serr) {
+		return common.ProviderError(sentinelFor(serr.Code, serr.StatusCode), key, err)
+	}
+	var status oss.UnexpectedStatusCodeError
+	if errors.As(err, &status) {
+		return common.ProviderError(common.ErrorForStatus(status.Got()), key, err)
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	var uerr *url.Error
+	var nerr net.Error
+	if errors.As(err, &uerr) || errors.As(err, &nerr) {
+		return common.ProviderError(common.ErrBackendUnavailable, key, err)
+	}
+	return err
+}
+
+// sentinelFor returns the common sentinel for an OSS error code, falling
+// back to the HTTP status.
+func sentinelFor(code string, status int) error {
+	switch code {
+	case "NoSuchKey", "NoSuchBucket", "NoSuchUpload", "NoSuchLifecycle":
+		return common.ErrNotFound
+	case "BucketAlreadyExists", "FileAlreadyExists", "ObjectAlreadyExists":
+		return common.ErrAlreadyExists
+	case "AccessDenied", "InvalidAccessKeyId", "SignatureDoesNotMatch", "SecurityTokenExpired",
+		"InvalidSecurityToken", "UserDisable":
+		return common.ErrAccessDenied
+	case "PreconditionFailed":
+		return common.ErrPreconditionFailed
+	case "QpsLimitExceeded", "DownloadTrafficRateLimitExceeded", "UploadTrafficRateLimitExceeded",
+		"TooManyBuckets":
+		return common.ErrQuotaExceeded
+	case "InvalidArgument", "InvalidObjectName", "InvalidBucketName", "InvalidPart", "InvalidPartOrder",
+		"EntityTooSmall", "EntityTooLarge", "InvalidDigest", "MalformedXML":
+		return common.ErrInvalidArgument
+	case "InternalError", "ServiceUnavailable", "RequestTimeout":
+		return common.ErrBackendUnavailable
+	}
+	return common.ErrorForStatus(status)
+}
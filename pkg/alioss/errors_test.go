@@ -0,0 +1,74 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build alioss
+
+package alioss
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+func TestTranslateError(t *testing.T) {
+	svcErr := func(status int, code string) error {
+		return oss.ServiceError{Code: code, StatusCode: status}
+	}
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"no such key", svcErr(http.StatusNotFound, "NoSuchKey"), common.ErrKeyNotFound},
+		{"no such bucket", svcErr(http.StatusNotFound, "NoSuchBucket"), common.ErrNotFound},
+		{"access denied", svcErr(http.StatusForbidden, "AccessDenied"), common.ErrAccessDenied},
+		{"expired token", svcErr(http.StatusForbidden, "SecurityTokenExpired"), common.ErrAccessDenied},
+		{"precondition", svcErr(http.StatusPreconditionFailed, "PreconditionFailed"), common.ErrPreconditionFailed},
+		{"qps limit", svcErr(http.StatusServiceUnavailable, "QpsLimitExceeded"), common.ErrQuotaExceeded},
+		{"part too small", svcErr(http.StatusBadRequest, "EntityTooSmall"), common.ErrInvalidArgument},
+		{"exists", svcErr(http.StatusConflict, "FileAlreadyExists"), common.ErrAlreadyExists},
+		{"internal", svcErr(http.StatusInternalServerError, "InternalError"), common.ErrBackendUnavailable},
+		{"status fallback", svcErr(http.StatusTooManyRequests, "Unknown"), common.ErrQuotaExceeded},
+		{"network", &url.Error{Op: "Get", URL: "https://oss", Err: errors.New("connection refused")}, common.ErrBackendUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := translateError(tt.err, "a/key")
+			if !errors.Is(got, tt.want) {
+				t.Errorf("translateError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+			if !errors.Is(got, tt.err) {
+				t.Errorf("translateError(%v) dropped the OSS error from the chain", tt.err)
+			}
+		})
+	}
+
+	canceled := &url.Error{Op: "Get", URL: "https://oss", Err: context.Canceled}
+	if got := translateError(canceled, "k"); errors.Is(got, common.ErrBackendUnavailable) || !errors.Is(got, context.Canceled) {
+		t.Errorf("translateError(canceled) = %v, want the cancellation unchanged", got)
+	}
+	plain := errors.New("not an OSS error")
+	if got := translateError(plain, "k"); got != plain {
+		t.Errorf("translateError(plain) = %v, want unchanged", got)
+	}
+	if got := translateError(nil, "k"); got != nil {
+		t.Errorf("translateError(nil) = %v, want nil", got)
+	}
+}
@@ -0,0 +1,343 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build alioss
+
+package alioss
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+// fakeObject is an object stored by fakeAPI.
+type fakeObject struct {
+	data  []byte
+	attrs objectAttrs
+}
+
+// fakeUpload is a multipart upload in progress.
+type fakeUpload struct {
+	key   string
+	attrs objectAttrs
+	parts map[int][]byte
+}
+
+// fakeAPI is an in-memory ossAPI. Like OSS it lower-cases custom metadata
+// keys and answers missing objects with NoSuchKey.
+type fakeAPI struct {
+	mu        sync.Mutex
+	objects   map[string]*fakeObject
+	uploads   map[string]*fakeUpload
+	lifecycle []oss.LifecycleRule
+	nextID    int
+
+	// failPart fails the upload of this part number when non-zero.
+	failPart int
+	// putCalls and partCalls count single and part uploads.
+	putCalls, partCalls, copyCalls, partCopyCalls, aborts int
+}
+
+func newFakeAPI() *fakeAPI {
+	return &fakeAPI{objects: map[string]*fakeObject{}, uploads: map[string]*fakeUpload{}}
+}
+
+// newTestOSS returns an OSS backed by a fresh fakeAPI.
+func newTestOSS() (*OSS, *fakeAPI) {
+	api := newFakeAPI()
+	return &OSS{api: api, bucket: "bucket", partSize: DefaultPartSize}, api
+}
+
+func notFound(code string) error {
+	return oss.ServiceError{Code: code, StatusCode: http.StatusNotFound, Message: "not found"}
+}
+
+func storedAttrs(attrs *objectAttrs, size int64) objectAttrs {
+	stored := objectAttrs{Size: size, LastModified: time.Now(), Meta: map[string]string{}, StorageClass: string(oss.StorageStandard)}
+	if attrs != nil {
+		stored.ContentType = attrs.ContentType
+		stored.ContentEncoding = attrs.ContentEncoding
+		if attrs.StorageClass != "" {
+			stored.StorageClass = attrs.StorageClass
+		}
+		for k, v := range attrs.Meta {
+			stored.Meta[strings.ToLower(k)] = v
+		}
+	}
+	return stored
+}
+
+func (f *fakeAPI) store(key string, data []byte, attrs objectAttrs) {
+	attrs.ETag = fmt.Sprintf("etag-%d", len(f.objects)+f.putCalls+f.partCalls)
+	f.objects[key] = &fakeObject{data: data, attrs: attrs}
+}
+
+func (f *fakeAPI) Put(ctx context.Context, key string, r io.Reader, size int64, attrs *objectAttrs) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	if int64(len(data)) != size {
+		return "", fmt.Errorf("put %d bytes with content length %d", len(data), size)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.putCalls++
+	f.store(key, data, storedAttrs(attrs, size))
+	return f.objects[key].attrs.ETag, nil
+}
+
+func (f *fakeAPI) Get(ctx context.Context, key, byteRange string) (io.ReadCloser, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	obj, ok := f.objects[key]
+	if !ok {
+		return nil, notFound("NoSuchKey")
+	}
+	data := obj.data
+	if byteRange != "" {
+		start, end, _ := strings.Cut(strings.TrimPrefix(byteRange, "bytes="), "-")
+		from, _ := strconv.Atoi(start)
+		to := len(data) - 1
+		if end != "" {
+			to, _ = strconv.Atoi(end)
+		}
+		data = data[min(from, len(data)):min(to+1, len(data))]
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (f *fakeAPI) Head(ctx context.Context, key string) (*objectAttrs, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	obj, ok := f.objects[key]
+	if !ok {
+		return nil, notFound("NoSuchKey")
+	}
+	attrs := obj.attrs
+	return &attrs, nil
+}
+
+func (f *fakeAPI) Copy(ctx context.Context, key string, attrs *objectAttrs) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	obj, ok := f.objects[key]
+	if !ok {
+		return notFound("NoSuchKey")
+	}
+	f.copyCalls++
+	f.store(key, obj.data, storedAttrs(attrs, int64(len(obj.data))))
+	return nil
+}
+
+func (f *fakeAPI) Delete(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.objects, key)
+	return nil
+}
+
+func (f *fakeAPI) List(ctx context.Context, prefix, delimiter, token string, limit int) (*listPage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	keys := make([]string, 0, len(f.objects))
+	for key := range f.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	page := &listPage{}
+	seen := map[string]bool{}
+	for _, key := range keys {
+		if !strings.HasPrefix(key, prefix) || key <= token {
+			continue
+		}
+		if len(page.Objects)+len(page.CommonPrefixes) == limit {
+			page.NextToken = page.lastKey()
+			break
+		}
+		if delimiter != "" {
+			if i := strings.Index(key[len(prefix):], delimiter); i >= 0 {
+				common := key[:len(prefix)+i+len(delimiter)]
+				if !seen[common] {
+					seen[common] = true
+					page.CommonPrefixes = append(page.CommonPrefixes, common)
+				}
+				continue
+			}
+		}
+		obj := f.objects[key]
+		page.Objects = append(page.Objects, oss.ObjectProperties{
+			Key: key, Size: obj.attrs.Size, ETag: `"` + obj.attrs.ETag + `"`,
+			LastModified: obj.attrs.LastModified, StorageClass: obj.attrs.StorageClass,
+		})
+	}
+	return page, nil
+}
+
+// lastKey returns the key the page ends at.
+func (p *listPage) lastKey() string {
+	last := ""
+	if n := len(p.Objects); n > 0 {
+		last = p.Objects[n-1].Key
+	}
+	if n := len(p.CommonPrefixes); n > 0 && p.CommonPrefixes[n-1] > last {
+		last = p.CommonPrefixes[n-1] + "\xff"
+	}
+	return last
+}
+
+func (f *fakeAPI) InitiateMultipart(ctx context.Context, key string, attrs *objectAttrs) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.nextID++
+	id := fmt.Sprintf("upload-%d", f.nextID)
+	f.uploads[id] = &fakeUpload{key: key, attrs: storedAttrs(attrs, 0), parts: map[int][]byte{}}
+	return id, nil
+}
+
+func (f *fakeAPI) UploadPart(ctx context.Context, key, uploadID string, r io.Reader, size int64, part int) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.partCalls++
+	if part == f.failPart {
+		return "", oss.ServiceError{Code: "InternalError", StatusCode: http.StatusInternalServerError}
+	}
+	upload, ok := f.uploads[uploadID]
+	if !ok || upload.key != key {
+		return "", notFound("NoSuchUpload")
+	}
+	if int64(len(data)) != size {
+		return "", fmt.Errorf("part of %d bytes with content length %d", len(data), size)
+	}
+	upload.parts[part] = data
+	return fmt.Sprintf("part-%d", part), nil
+}
+
+func (f *fakeAPI) UploadPartCopy(ctx context.Context, key, uploadID, source string, offset, size int64, part int) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.partCopyCalls++
+	upload, ok := f.uploads[uploadID]
+	if !ok || upload.key != key {
+		return "", notFound("NoSuchUpload")
+	}
+	obj, ok := f.objects[source]
+	if !ok {
+		return "", notFound("NoSuchKey")
+	}
+	upload.parts[part] = obj.data[offset : offset+size]
+	return fmt.Sprintf("part-%d", part), nil
+}
+
+func (f *fakeAPI) CompleteMultipart(ctx context.Context, key, uploadID string, parts []oss.UploadPart) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	upload, ok := f.uploads[uploadID]
+	if !ok || upload.key != key {
+		return "", notFound("NoSuchUpload")
+	}
+	var data []byte
+	for i, part := range parts {
+		if part.PartNumber != i+1 || part.ETag != fmt.Sprintf("part-%d", part.PartNumber) {
+			return "", oss.ServiceError{Code: "InvalidPart", StatusCode: http.StatusBadRequest}
+		}
+		data = append(data, upload.parts[part.PartNumber]...)
+	}
+	delete(f.uploads, uploadID)
+	attrs := upload.attrs
+	attrs.Size = int64(len(data))
+	f.store(key, data, attrs)
+	return f.objects[key].attrs.ETag, nil
+}
+
+func (f *fakeAPI) AbortMultipart(ctx context.Context, key, uploadID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.aborts++
+	if _, ok := f.uploads[uploadID]; !ok {
+		return notFound("NoSuchUpload")
+	}
+	delete(f.uploads, uploadID)
+	return nil
+}
+
+func (f *fakeAPI) GetLifecycle(ctx context.Context) ([]oss.LifecycleRule, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.lifecycle == nil {
+		return nil, notFound("NoSuchLifecycle")
+	}
+	return append([]oss.LifecycleRule(nil), f.lifecycle...), nil
+}
+
+func (f *fakeAPI) SetLifecycle(ctx context.Context, rules []oss.LifecycleRule) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(rules) == 0 {
+		return oss.ServiceError{Code: "MalformedXML", StatusCode: http.StatusBadRequest}
+	}
+	f.lifecycle = append([]oss.LifecycleRule(nil), rules...)
+	return nil
+}
+
+func (f *fakeAPI) DeleteLifecycle(ctx context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.lifecycle = nil
+	return nil
+}
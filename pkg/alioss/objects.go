@@ -0,0 +1,259 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build alioss
+
+package alioss
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/transport"
+)
+
+// defaultMaxKeys is the page size of listings, the OSS maximum.
+const defaultMaxKeys = 1000
+
+// PutWithContext stores an object in the backend with context support.
+func (o *OSS) PutWithContext(ctx context.Context, key string, data io.Reader) error {
+	return o.PutWithMetadata(ctx, key, data, nil)
+}
+
+// PutWithMetadata stores an object with associated metadata. Objects larger
+// than the part size are uploaded in parts.
+func (o *OSS) PutWithMetadata(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) error {
+	_, err := o.PutWithInfo(ctx, key, data, metadata)
+	return err
+}
+
+// PutWithInfo stores an object like PutWithMetadata and returns the ETag
+// OSS assigned it, without reading its metadata back.
+func (o *OSS) PutWithInfo(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) (*common.ObjectInfo, error) {
+	if err := common.ValidateKey(key); err != nil {
+		return nil, err
+	}
+	attrs, err := attrsFor(metadata)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := o.timeouts.Context(ctx, transport.OpPut)
+	defer cancel()
+	etag, size, err := o.upload(ctx, key, data, attrs)
+	if err != nil {
+		return nil, translateError(err, key)
+	}
+
+	info := &common.Metadata{Size: size, LastModified: time.Now().UTC(), ETag: etag}
+	if metadata != nil {
+		info.ContentType = metadata.ContentType
+		info.ContentEncoding = metadata.ContentEncoding
+		info.StorageClass = metadata.StorageClass
+		info.Custom = maps.Clone(metadata.Custom)
+	}
+	return &common.ObjectInfo{Key: key, Metadata: info}, nil
+}
+
+// GetWithContext retrieves an object from the backend with context support.
+func (o *OSS) GetWithContext(ctx context.Context, key string) (io.ReadCloser, error) {
+	if err := common.ValidateKey(key); err != nil {
+		return nil, err
+	}
+	ctx, cancel := o.timeouts.Context(ctx, transport.OpGet)
+	rc, err := o.api.Get(ctx, key, "")
+	if err != nil {
+		cancel()
+		return nil, translateError(err, key)
+	}
+	return transport.CancelOnClose(rc, cancel), nil
+}
+
+// GetRange retrieves length bytes of an object starting at offset. A negative
+// length reads to the end of the object.
+func (o *OSS) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	if err := common.ValidateKey(key); err != nil {
+		return nil, err
+	}
+	if offset < 0 {
+		return nil, fmt.Errorf("%w: negative range offset %d", common.ErrInvalidArgument, offset)
+	}
+	ctx, cancel := o.timeouts.Context(ctx, transport.OpGet)
+	rc, err := o.api.Get(ctx, key, common.RangeHeader(offset, length))
+	if err != nil {
+		cancel()
+		return nil, translateError(err, key)
+	}
+	return transport.CancelOnClose(rc, cancel), nil
+}
+
+// GetMetadata retrieves only the metadata for an object.
+func (o *OSS) GetMetadata(ctx context.Context, key string) (*common.Metadata, error) {
+	if err := common.ValidateKey(key); err != nil {
+		return nil, err
+	}
+	ctx, cancel := o.timeouts.Context(ctx, transport.OpMetadata)
+	defer cancel()
+	attrs, err := o.api.Head(ctx, key)
+	if err != nil {
+		return nil, translateError(err, key)
+	}
+	return &common.Metadata{
+		ContentType:     attrs.ContentType,
+		ContentEncoding: attrs.ContentEncoding,
+		Size:            attrs.Size,
+		LastModified:    attrs.LastModified,
+		ETag:            attrs.ETag,
+		StorageClass:    attrs.StorageClass,
+		Custom:          attrs.Meta,
+	}, nil
+}
+
+// UpdateMetadata updates the metadata for an existing object.
+// Matching the other backends, the object's metadata is replaced rather
+// than merged. OSS has no metadata update request: objects up to 1 GiB are
+// copied onto themselves, larger ones with a multipart copy, so the data is
+// never transferred through the client. The storage class is kept unless
+// metadata names one. A missing object yields an error wrapping
+// common.ErrKeyNotFound.
+func (o *OSS) UpdateMetadata(ctx context.Context, key string, metadata *common.Metadata) error {
+	if err := common.ValidateKey(key); err != nil {
+		return err
+	}
+	attrs, err := attrsFor(metadata)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := o.timeouts.Context(ctx, transport.OpMetadata)
+	defer cancel()
+	current, err := o.api.Head(ctx, key)
+	if err != nil {
+		return translateError(err, key)
+	}
+	if attrs.StorageClass == "" {
+		attrs.StorageClass = current.StorageClass
+	}
+	if current.Size <= maxCopySize {
+		return translateError(o.api.Copy(ctx, key, attrs), key)
+	}
+	return translateError(o.copyParts(ctx, key, current.Size, attrs), key)
+}
+
+// DeleteWithContext removes an object from the backend with context support.
+// OSS reports success for keys that do not exist.
+func (o *OSS) DeleteWithContext(ctx context.Context, key string) error {
+	if err := common.ValidateKey(key); err != nil {
+		return err
+	}
+	ctx, cancel := o.timeouts.Context(ctx, transport.OpDelete)
+	defer cancel()
+	return translateError(o.api.Delete(ctx, key), key)
+}
+
+// Exists checks if an object exists in the backend.
+// Returns false,nil only for a not-found condition; propagates all other errors.
+func (o *OSS) Exists(ctx context.Context, key string) (bool, error) {
+	if err := common.ValidateKey(key); err != nil {
+		return false, err
+	}
+	ctx, cancel := o.timeouts.Context(ctx, transport.OpMetadata)
+	defer cancel()
+	if _, err := o.api.Head(ctx, key); err != nil {
+		if err = translateError(err, key); errors.Is(err, common.ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// ListWithContext returns a list of keys with context support.
+func (o *OSS) ListWithContext(ctx context.Context, prefix string) ([]string, error) {
+	ctx, cancel := o.timeouts.Context(ctx, transport.OpList)
+	defer cancel()
+	var keys []string
+	token := ""
+	for {
+		page, err := o.api.List(ctx, prefix, "", token, defaultMaxKeys)
+		if err != nil {
+			return nil, translateError(err, "")
+		}
+		for _, obj := range page.Objects {
+			keys = append(keys, obj.Key)
+		}
+		if page.NextToken == "" {
+			return keys, nil
+		}
+		token = page.NextToken
+	}
+}
+
+// ListWithOptions returns a paginated list of objects with full metadata.
+func (o *OSS) ListWithOptions(ctx context.Context, opts *common.ListOptions) (*common.ListResult, error) {
+	if opts == nil {
+		opts = &common.ListOptions{}
+	}
+	ctx, cancel := o.timeouts.Context(ctx, transport.OpList)
+	defer cancel()
+	maxKeys := opts.MaxResults
+	if maxKeys <= 0 || maxKeys > defaultMaxKeys {
+		maxKeys = defaultMaxKeys
+	}
+	page, err := o.api.List(ctx, opts.Prefix, opts.Delimiter, opts.ContinueFrom, maxKeys)
+	if err != nil {
+		return nil, translateError(err, "")
+	}
+
+	result := &common.ListResult{
+		Objects:        make([]*common.ObjectInfo, 0, len(page.Objects)),
+		CommonPrefixes: page.CommonPrefixes,
+		NextToken:      page.NextToken,
+		Truncated:      page.NextToken != "",
+	}
+	if result.CommonPrefixes == nil {
+		result.CommonPrefixes = []string{}
+	}
+	for _, obj := range page.Objects {
+		result.Objects = append(result.Objects, &common.ObjectInfo{
+			Key: obj.Key,
+			Metadata: &common.Metadata{
+				Size:         obj.Size,
+				LastModified: obj.LastModified,
+				ETag:         trimETag(obj.ETag),
+				StorageClass: obj.StorageClass,
+			},
+		})
+	}
+	return result, nil
+}
+
+// attrsFor returns the object attributes that store metadata.
+func attrsFor(metadata *common.Metadata) (*objectAttrs, error) {
+	attrs := &objectAttrs{}
+	if metadata == nil {
+		return attrs, nil
+	}
+	class, err := common.NormalizeStorageClass(metadata.StorageClass, storageClasses)
+	if err != nil {
+		return nil, err
+	}
+	attrs.ContentType = metadata.ContentType
+	attrs.ContentEncoding = metadata.ContentEncoding
+	attrs.StorageClass = class
+	attrs.Meta = metadata.Custom
+	return attrs, nil
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build alioss
+
+package alioss
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+const (
+	// maxParts is the most parts a multipart upload may have.
+	maxParts = 10000
+
+	// abortTimeout bounds the request that aborts a failed multipart
+	// upload, which runs even when the upload's context was canceled.
+	abortTimeout = 30 * time.Second
+)
+
+// maxCopySize is the largest object CopyObject can copy; larger objects are
+// copied part by part. It is a variable so tests can lower it.
+var maxCopySize int64 = 1024 * 1024 * 1024
+
+// upload stores data under key and returns the stored object's ETag and
+// size. Data that fits in one part is stored with a single request. Larger
+// data is uploaded in parts of o.partSize, each buffered in memory so its
+// length is known, and the upload is aborted if any part fails so no
+// orphaned parts are left behind.
+func (o *OSS) upload(ctx context.Context, key string, data io.Reader, attrs *objectAttrs) (string, int64, error) {
+	buf := make([]byte, o.partSize)
+	n, err := io.ReadFull(data, buf)
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		etag, err := o.api.Put(ctx, key, bytes.NewReader(buf[:n]), int64(n), attrs)
+		return etag, int64(n), err
+	}
+	if err != nil {
+		return "", 0, err
+	}
+
+	uploadID, err := o.api.InitiateMultipart(ctx, key, attrs)
+	if err != nil {
+		return "", 0, err
+	}
+	var parts []oss.UploadPart
+	var size int64
+	for number := 1; n > 0; number++ {
+		if number > maxParts {
+			err = fmt.Errorf("%w: object exceeds %d parts of %d bytes", common.ErrInvalidArgument, maxParts, o.partSize)
+			break
+		}
+		var etag string
+		if etag, err = o.api.UploadPart(ctx, key, uploadID, bytes.NewReader(buf[:n]), int64(n), number); err != nil {
+			break
+		}
+		parts = append(parts, oss.UploadPart{PartNumber: number, ETag: etag})
+		size += int64(n)
+		if n, err = io.ReadFull(data, buf); errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			err = nil
+		} else if err != nil {
+			break
+		}
+	}
+	var etag string
+	if err == nil {
+		etag, err = o.api.CompleteMultipart(ctx, key, uploadID, parts)
+	}
+	if err != nil {
+		o.abort(ctx, key, uploadID)
+		return "", 0, err
+	}
+	return etag, size, nil
+}
+
+// copyParts replaces the metadata of the size byte object under key with
+// attrs by copying it onto itself part by part.
+func (o *OSS) copyParts(ctx context.Context, key string, size int64, attrs *objectAttrs) error {
+	uploadID, err := o.api.InitiateMultipart(ctx, key, attrs)
+	if err != nil {
+		return err
+	}
+	var parts []oss.UploadPart
+	for offset, number := int64(0), 1; offset < size; offset, number = offset+maxCopySize, number+1 {
+		length := min(maxCopySize, size-offset)
+		var etag string
+		if etag, err = o.api.UploadPartCopy(ctx, key, uploadID, key, offset, length, number); err != nil {
+			break
+		}
+		parts = append(parts, oss.UploadPart{PartNumber: number, ETag: etag})
+	}
+	if err == nil {
+		_, err = o.api.CompleteMultipart(ctx, key, uploadID, parts)
+	}
+	if err != nil {
+		o.abort(ctx, key, uploadID)
+	}
+	return err
+}
+
+// abort aborts a failed multipart upload. Failures are ignored; the bucket
+// lifecycle can clean up parts that are left behind.
+func (o *OSS) abort(ctx context.Context, key, uploadID string) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), abortTimeout)
+	defer cancel()
+	_ = o.api.AbortMultipart(ctx, key, uploadID) // #nosec G104 -- Best effort; the upload already failed
+}
+
+// trimETag removes the quotes OSS puts around ETags in listings.
+func trimETag(etag string) string {
+	return strings.Trim(etag, `"`)
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// StorageAnchor anchors audit checkpoints as JSON objects in a storage
+// backend, ideally one the audited server cannot overwrite, such as a
+// bucket with object lock.
+type StorageAnchor struct {
+	storage common.Storage
+	prefix  string
+}
+
+// NewStorageAnchor returns an anchor writing checkpoints under prefix
+func NewStorageAnchor(storage common.Storage, prefix string) *StorageAnchor {
+	return &StorageAnchor{storage: storage, prefix: prefix}
+}
+
+// Anchor writes the checkpoint to <prefix><last record, zero padded>.json
+func (a *StorageAnchor) Anchor(ctx context.Context, checkpoint *Checkpoint) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+	return a.storage.PutWithMetadata(ctx, a.key(checkpoint.Last), bytes.NewReader(data), &common.Metadata{
+		ContentType: "application/json",
+	})
+}
+
+// Checkpoints returns the anchored checkpoints in log order
+func (a *StorageAnchor) Checkpoints(ctx context.Context) ([]Checkpoint, error) {
+	keys, err := a.storage.ListWithContext(ctx, a.prefix)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(keys)
+
+	checkpoints := make([]Checkpoint, 0, len(keys))
+	for _, key := range keys {
+		reader, err := a.storage.GetWithContext(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(reader)
+		_ = reader.Close()
+		if err != nil {
+			return nil, err
+		}
+		var checkpoint Checkpoint
+		if err := json.Unmarshal(data, &checkpoint); err != nil {
+			return nil, fmt.Errorf("%w: anchored checkpoint %s: %v", ErrChainTampered, key, err)
+		}
+		checkpoints = append(checkpoints, checkpoint)
+	}
+	return checkpoints, nil
+}
+
+func (a *StorageAnchor) key(last uint64) string {
+	return fmt.Sprintf("%s%020d.json", a.prefix, last)
+}
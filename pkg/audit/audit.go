@@ -50,6 +50,9 @@ const (
 	// EventObjectArchived indicates an object was archived
 	EventObjectArchived EventType = "OBJECT_ARCHIVED"
 
+	// EventObjectRestored indicates an object was restored from an archive
+	EventObjectRestored EventType = "OBJECT_RESTORED"
+
 	// EventPolicyChanged indicates a lifecycle policy was changed
 	EventPolicyChanged EventType = "POLICY_CHANGED"
 
@@ -61,6 +64,35 @@ const (
 
 	// EventListObjects indicates objects were listed
 	EventListObjects EventType = "LIST_OBJECTS"
+
+	// EventDeletionRequested indicates a protected object's deletion was
+	// queued for approval
+	EventDeletionRequested EventType = "DELETION_REQUESTED"
+
+	// EventDeletionApproved indicates a pending deletion was approved and
+	// executed
+	EventDeletionApproved EventType = "DELETION_APPROVED"
+
+	// EventDeletionRejected indicates a pending deletion was rejected
+	EventDeletionRejected EventType = "DELETION_REJECTED"
+
+	// EventLegalHoldPlaced indicates a legal hold was placed on an object
+	EventLegalHoldPlaced EventType = "LEGAL_HOLD_PLACED"
+
+	// EventLegalHoldReleased indicates a legal hold was released
+	EventLegalHoldReleased EventType = "LEGAL_HOLD_RELEASED"
+
+	// EventBackendFailover indicates traffic moved from an unhealthy
+	// backend to its secondary
+	EventBackendFailover EventType = "BACKEND_FAILOVER"
+
+	// EventBackendFailback indicates traffic returned to a backend that
+	// became healthy again
+	EventBackendFailback EventType = "BACKEND_FAILBACK"
+
+	// EventResidencyViolation indicates a write or replication policy was
+	// rejected for moving data outside its allowed regions or backends
+	EventResidencyViolation EventType = "RESIDENCY_VIOLATION"
 )
 
 // Result represents the outcome of an audited operation
@@ -88,6 +120,9 @@ type AuditEvent struct {
 	// Principal is the name of the authenticated user/service
 	Principal string `json:"principal,omitempty"`
 
+	// OnBehalfOf is the end user a privileged service acted for, if any
+	OnBehalfOf string `json:"on_behalf_of,omitempty"`
+
 	// Resource identifies the target resource (bucket, key, etc.)
 	Resource string `json:"resource,omitempty"`
 
@@ -267,6 +302,9 @@ func (a *DefaultAuditLogger) LogEvent(ctx context.Context, event *AuditEvent) er
 	if event.Principal != "" {
 		attrs = append(attrs, slog.String("principal", event.Principal))
 	}
+	if event.OnBehalfOf != "" {
+		attrs = append(attrs, slog.String("on_behalf_of", event.OnBehalfOf))
+	}
 	if event.Resource != "" {
 		attrs = append(attrs, slog.String("resource", event.Resource))
 	}
@@ -310,6 +348,31 @@ func (a *DefaultAuditLogger) LogEvent(ctx context.Context, event *AuditEvent) er
 
 // LogAuthFailure logs authentication failures
 func (a *DefaultAuditLogger) LogAuthFailure(ctx context.Context, userID, principal, ipAddress, requestID, reason string) error {
+	return a.LogEvent(ctx, authFailureEvent(userID, principal, ipAddress, requestID, reason))
+}
+
+// LogAuthSuccess logs successful authentication
+func (a *DefaultAuditLogger) LogAuthSuccess(ctx context.Context, userID, principal, ipAddress, requestID string) error {
+	return a.LogEvent(ctx, authSuccessEvent(userID, principal, ipAddress, requestID))
+}
+
+// LogObjectAccess logs object read operations
+func (a *DefaultAuditLogger) LogObjectAccess(ctx context.Context, userID, principal, bucket, key, ipAddress, requestID string, result Result, err error) error {
+	return a.LogEvent(ctx, objectAccessEvent(userID, principal, bucket, key, ipAddress, requestID, result, err))
+}
+
+// LogObjectMutation logs object create/update/delete operations
+func (a *DefaultAuditLogger) LogObjectMutation(ctx context.Context, eventType EventType, userID, principal, bucket, key, ipAddress, requestID string, bytesTransferred int64, result Result, err error) error {
+	return a.LogEvent(ctx, objectMutationEvent(eventType, userID, principal, bucket, key, ipAddress, requestID, bytesTransferred, result, err))
+}
+
+// LogPolicyChange logs lifecycle policy modifications
+func (a *DefaultAuditLogger) LogPolicyChange(ctx context.Context, userID, principal, bucket, policyID, ipAddress, requestID string, result Result, err error) error {
+	return a.LogEvent(ctx, policyChangeEvent(userID, principal, bucket, policyID, ipAddress, requestID, result, err))
+}
+
+// authFailureEvent builds the event for an authentication failure
+func authFailureEvent(userID, principal, ipAddress, requestID, reason string) *AuditEvent {
 	event := &AuditEvent{
 		Timestamp:    time.Now(),
 		EventType:    EventAuthFailure,
@@ -321,11 +384,11 @@ func (a *DefaultAuditLogger) LogAuthFailure(ctx context.Context, userID, princip
 		IPAddress:    ipAddress,
 		RequestID:    requestID,
 	}
-	return a.LogEvent(ctx, event)
+	return event
 }
 
-// LogAuthSuccess logs successful authentication
-func (a *DefaultAuditLogger) LogAuthSuccess(ctx context.Context, userID, principal, ipAddress, requestID string) error {
+// authSuccessEvent builds the event for a successful authentication
+func authSuccessEvent(userID, principal, ipAddress, requestID string) *AuditEvent {
 	event := &AuditEvent{
 		Timestamp: time.Now(),
 		EventType: EventAuthSuccess,
@@ -336,11 +399,11 @@ func (a *DefaultAuditLogger) LogAuthSuccess(ctx context.Context, userID, princip
 		IPAddress: ipAddress,
 		RequestID: requestID,
 	}
-	return a.LogEvent(ctx, event)
+	return event
 }
 
-// LogObjectAccess logs object read operations
-func (a *DefaultAuditLogger) LogObjectAccess(ctx context.Context, userID, principal, bucket, key, ipAddress, requestID string, result Result, err error) error {
+// objectAccessEvent builds the event for an object read
+func objectAccessEvent(userID, principal, bucket, key, ipAddress, requestID string, result Result, err error) *AuditEvent {
 	event := &AuditEvent{
 		Timestamp: time.Now(),
 		EventType: EventObjectAccessed,
@@ -359,11 +422,11 @@ func (a *DefaultAuditLogger) LogObjectAccess(ctx context.Context, userID, princi
 		event.ErrorMessage = err.Error()
 	}
 
-	return a.LogEvent(ctx, event)
+	return event
 }
 
-// LogObjectMutation logs object create/update/delete operations
-func (a *DefaultAuditLogger) LogObjectMutation(ctx context.Context, eventType EventType, userID, principal, bucket, key, ipAddress, requestID string, bytesTransferred int64, result Result, err error) error {
+// objectMutationEvent builds the event for an object create/update/delete
+func objectMutationEvent(eventType EventType, userID, principal, bucket, key, ipAddress, requestID string, bytesTransferred int64, result Result, err error) *AuditEvent {
 	action := "modify_object"
 	switch eventType {
 	case EventObjectCreated:
@@ -393,11 +456,11 @@ func (a *DefaultAuditLogger) LogObjectMutation(ctx context.Context, eventType Ev
 		event.ErrorMessage = err.Error()
 	}
 
-	return a.LogEvent(ctx, event)
+	return event
 }
 
-// LogPolicyChange logs lifecycle policy modifications
-func (a *DefaultAuditLogger) LogPolicyChange(ctx context.Context, userID, principal, bucket, policyID, ipAddress, requestID string, result Result, err error) error {
+// policyChangeEvent builds the event for a lifecycle policy modification
+func policyChangeEvent(userID, principal, bucket, policyID, ipAddress, requestID string, result Result, err error) *AuditEvent {
 	event := &AuditEvent{
 		Timestamp: time.Now(),
 		EventType: EventPolicyChanged,
@@ -418,7 +481,7 @@ func (a *DefaultAuditLogger) LogPolicyChange(ctx context.Context, userID, princi
 		event.ErrorMessage = err.Error()
 	}
 
-	return a.LogEvent(ctx, event)
+	return event
 }
 
 // SetLevel sets the minimum audit level
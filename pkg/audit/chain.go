@@ -0,0 +1,493 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+)
+
+// DefaultCheckpointInterval is the number of events between checkpoints
+// when ChainConfig.CheckpointInterval is zero.
+const DefaultCheckpointInterval = 1000
+
+var (
+	// ErrChainTampered is returned when a hash-chained audit log, or its
+	// anchored checkpoints, do not verify.
+	ErrChainTampered = errors.New("audit chain tampered")
+
+	// ErrInvalidChainConfig is returned for an unusable ChainConfig.
+	ErrInvalidChainConfig = errors.New("invalid audit chain configuration")
+)
+
+// Record kinds mixed into record hashes so an event cannot be replayed as a
+// checkpoint or the other way round.
+const (
+	kindEvent      byte = 'e'
+	kindCheckpoint byte = 'c'
+)
+
+// ChainRecord is one line of a hash-chained audit log. Each record's hash
+// covers the previous record's hash, so changing, removing or reordering a
+// record breaks every hash after it. Exactly one of Event and Checkpoint is
+// set.
+type ChainRecord struct {
+	Seq        uint64          `json:"seq"`
+	PrevHash   string          `json:"prev_hash"`
+	Hash       string          `json:"hash"`
+	Event      json.RawMessage `json:"event,omitempty"`
+	Checkpoint json.RawMessage `json:"checkpoint,omitempty"`
+}
+
+// Checkpoint commits to the event records First through Last: Head is the
+// hash of record Last and Root is the Merkle root of the hashes of records
+// First through Last. Checkpoints are appended to the log and published to
+// a CheckpointAnchor; a log rewritten after a checkpoint was anchored no
+// longer matches it.
+type Checkpoint struct {
+	First     uint64    `json:"first"`
+	Last      uint64    `json:"last"`
+	Head      string    `json:"head"`
+	Root      string    `json:"root"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// CheckpointAnchor publishes checkpoints to a store outside the audit log's
+// control, such as a write-once bucket.
+type CheckpointAnchor interface {
+	Anchor(ctx context.Context, checkpoint *Checkpoint) error
+}
+
+// ChainConfig configures a ChainedAuditLogger
+type ChainConfig struct {
+	// Path is the append-only log file. An existing log is verified and
+	// extended.
+	Path string
+
+	// CheckpointInterval is the number of events between checkpoints.
+	// Zero uses DefaultCheckpointInterval.
+	CheckpointInterval int
+
+	// Anchor, if set, receives every checkpoint.
+	Anchor CheckpointAnchor
+
+	// Forward, if set, also receives every event, e.g. a logger writing
+	// to stdout.
+	Forward AuditLogger
+}
+
+// ChainedAuditLogger is an AuditLogger that appends events to a
+// tamper-evident, hash-chained log file.
+type ChainedAuditLogger struct {
+	mu       sync.Mutex
+	config   ChainConfig
+	file     *os.File
+	interval int
+	level    adapters.LogLevel
+
+	// chain is the state of the log written so far.
+	chain chainState
+}
+
+// chainState tracks a chain while it is written or verified.
+type chainState struct {
+	seq    uint64
+	head   []byte
+	first  uint64
+	leaves [][]byte
+	hashes [][]byte
+	// checkpoints are those recorded in the log.
+	checkpoints []Checkpoint
+}
+
+// NewChainedAuditLogger opens or creates the log at config.Path. An
+// existing log that does not verify is refused with ErrChainTampered
+// rather than extended.
+func NewChainedAuditLogger(config *ChainConfig) (*ChainedAuditLogger, error) {
+	if config == nil || config.Path == "" {
+		return nil, fmt.Errorf("%w: path is required", ErrInvalidChainConfig)
+	}
+	if config.CheckpointInterval < 0 {
+		return nil, fmt.Errorf("%w: checkpoint interval must not be negative", ErrInvalidChainConfig)
+	}
+	interval := config.CheckpointInterval
+	if interval == 0 {
+		interval = DefaultCheckpointInterval
+	}
+
+	file, err := os.OpenFile(config.Path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	state := newChainState()
+	if err := state.read(file); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	// Only the tail is needed to extend the chain.
+	state.hashes = nil
+	state.checkpoints = nil
+
+	return &ChainedAuditLogger{
+		config:   *config,
+		file:     file,
+		interval: interval,
+		level:    adapters.InfoLevel,
+		chain:    *state,
+	}, nil
+}
+
+// LogEvent appends an event to the chain, checkpointing every
+// CheckpointInterval events
+func (c *ChainedAuditLogger) LogEvent(ctx context.Context, event *AuditEvent) error {
+	if event == nil {
+		return nil
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	err = c.appendLocked(kindEvent, body)
+	if err == nil && len(c.chain.leaves) >= c.interval {
+		_, err = c.checkpointLocked(ctx)
+	}
+	c.mu.Unlock()
+
+	if c.config.Forward != nil {
+		if ferr := c.config.Forward.LogEvent(ctx, event); err == nil {
+			err = ferr
+		}
+	}
+	return err
+}
+
+// Checkpoint checkpoints the events written since the last checkpoint and
+// anchors it. It returns nil if there are none.
+func (c *ChainedAuditLogger) Checkpoint(ctx context.Context) (*Checkpoint, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.checkpointLocked(ctx)
+}
+
+// Close checkpoints outstanding events and closes the log.
+func (c *ChainedAuditLogger) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.file == nil {
+		return nil
+	}
+	_, err := c.checkpointLocked(context.Background())
+	if cerr := c.file.Close(); err == nil {
+		err = cerr
+	}
+	c.file = nil
+	return err
+}
+
+// appendLocked writes one record. The chain only advances once the record
+// is written.
+func (c *ChainedAuditLogger) appendLocked(kind byte, body []byte) error {
+	if c.file == nil {
+		return os.ErrClosed
+	}
+	seq := c.chain.seq + 1
+	hash := recordHash(c.chain.head, seq, kind, body)
+	record := ChainRecord{
+		Seq:      seq,
+		PrevHash: hex.EncodeToString(c.chain.head),
+		Hash:     hex.EncodeToString(hash),
+	}
+	if kind == kindCheckpoint {
+		record.Checkpoint = body
+	} else {
+		record.Event = body
+	}
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if _, err := c.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+
+	c.chain.seq = seq
+	c.chain.head = hash
+	if kind == kindEvent {
+		c.chain.leaves = append(c.chain.leaves, hash)
+	}
+	return nil
+}
+
+func (c *ChainedAuditLogger) checkpointLocked(ctx context.Context) (*Checkpoint, error) {
+	if len(c.chain.leaves) == 0 {
+		return nil, nil
+	}
+	checkpoint := &Checkpoint{
+		First:     c.chain.first,
+		Last:      c.chain.seq,
+		Head:      hex.EncodeToString(c.chain.head),
+		Root:      hex.EncodeToString(merkleRoot(c.chain.leaves)),
+		Timestamp: time.Now().UTC(),
+	}
+	body, err := json.Marshal(checkpoint)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.appendLocked(kindCheckpoint, body); err != nil {
+		return nil, err
+	}
+	c.chain.leaves = nil
+	c.chain.first = c.chain.seq + 1
+
+	if err := c.file.Sync(); err != nil {
+		return checkpoint, err
+	}
+	if c.config.Anchor != nil {
+		if err := c.config.Anchor.Anchor(ctx, checkpoint); err != nil {
+			return checkpoint, fmt.Errorf("anchor audit checkpoint %d: %w", checkpoint.Last, err)
+		}
+	}
+	return checkpoint, nil
+}
+
+// LogAuthFailure logs authentication failures
+func (c *ChainedAuditLogger) LogAuthFailure(ctx context.Context, userID, principal, ipAddress, requestID, reason string) error {
+	return c.LogEvent(ctx, authFailureEvent(userID, principal, ipAddress, requestID, reason))
+}
+
+// LogAuthSuccess logs successful authentication
+func (c *ChainedAuditLogger) LogAuthSuccess(ctx context.Context, userID, principal, ipAddress, requestID string) error {
+	return c.LogEvent(ctx, authSuccessEvent(userID, principal, ipAddress, requestID))
+}
+
+// LogObjectAccess logs object read operations
+func (c *ChainedAuditLogger) LogObjectAccess(ctx context.Context, userID, principal, bucket, key, ipAddress, requestID string, result Result, err error) error {
+	return c.LogEvent(ctx, objectAccessEvent(userID, principal, bucket, key, ipAddress, requestID, result, err))
+}
+
+// LogObjectMutation logs object create/update/delete operations
+func (c *ChainedAuditLogger) LogObjectMutation(ctx context.Context, eventType EventType, userID, principal, bucket, key, ipAddress, requestID string, bytesTransferred int64, result Result, err error) error {
+	return c.LogEvent(ctx, objectMutationEvent(eventType, userID, principal, bucket, key, ipAddress, requestID, bytesTransferred, result, err))
+}
+
+// LogPolicyChange logs lifecycle policy modifications
+func (c *ChainedAuditLogger) LogPolicyChange(ctx context.Context, userID, principal, bucket, policyID, ipAddress, requestID string, result Result, err error) error {
+	return c.LogEvent(ctx, policyChangeEvent(userID, principal, bucket, policyID, ipAddress, requestID, result, err))
+}
+
+// SetLevel sets the minimum audit level
+func (c *ChainedAuditLogger) SetLevel(level adapters.LogLevel) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.level = level
+}
+
+// GetLevel returns the current audit level
+func (c *ChainedAuditLogger) GetLevel() adapters.LogLevel {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.level
+}
+
+// ChainVerification summarizes a verified audit log
+type ChainVerification struct {
+	// Records is the number of records, events and checkpoints, in the log.
+	Records uint64 `json:"records"`
+
+	// Head is the hash of the last record.
+	Head string `json:"head"`
+
+	// Checkpoints are the checkpoints recorded in the log.
+	Checkpoints []Checkpoint `json:"checkpoints"`
+
+	// Anchored is the number of anchored checkpoints the log matched.
+	Anchored int `json:"anchored"`
+
+	// Unanchored is the number of events after the last checkpoint.
+	Unanchored int `json:"unanchored"`
+}
+
+// VerifyChain reads a hash-chained audit log and checks every record's
+// hash, the checkpoints recorded in it, and the anchored checkpoints, which
+// detect a log rewritten or truncated after they were anchored. Events
+// after the last anchored checkpoint are only protected by the chain
+// itself. Failures wrap ErrChainTampered.
+func VerifyChain(r io.Reader, anchored []Checkpoint) (*ChainVerification, error) {
+	state := newChainState()
+	if err := state.read(r); err != nil {
+		return nil, err
+	}
+
+	for i := range anchored {
+		if err := state.verifyAnchored(&anchored[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return &ChainVerification{
+		Records:     state.seq,
+		Head:        hex.EncodeToString(state.head),
+		Checkpoints: state.checkpoints,
+		Anchored:    len(anchored),
+		Unanchored:  len(state.leaves),
+	}, nil
+}
+
+func newChainState() *chainState {
+	return &chainState{head: make([]byte, sha256.Size), first: 1}
+}
+
+// read verifies and consumes the records in r.
+func (s *chainState) read(r io.Reader) error {
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			if verr := s.add(line); verr != nil {
+				return verr
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// add verifies one record against the chain so far and appends it.
+func (s *chainState) add(line []byte) error {
+	seq := s.seq + 1
+	var record ChainRecord
+	if err := json.Unmarshal(line, &record); err != nil {
+		return fmt.Errorf("%w: record %d: malformed: %v", ErrChainTampered, seq, err)
+	}
+	if record.Seq != seq {
+		return fmt.Errorf("%w: record %d: found sequence number %d", ErrChainTampered, seq, record.Seq)
+	}
+	if record.PrevHash != hex.EncodeToString(s.head) {
+		return fmt.Errorf("%w: record %d: previous hash does not match", ErrChainTampered, seq)
+	}
+
+	kind, body := kindEvent, []byte(record.Event)
+	switch {
+	case len(record.Event) > 0 && len(record.Checkpoint) > 0, len(record.Event) == 0 && len(record.Checkpoint) == 0:
+		return fmt.Errorf("%w: record %d: must hold an event or a checkpoint", ErrChainTampered, seq)
+	case len(record.Checkpoint) > 0:
+		kind, body = kindCheckpoint, record.Checkpoint
+	}
+	hash := recordHash(s.head, seq, kind, body)
+	if record.Hash != hex.EncodeToString(hash) {
+		return fmt.Errorf("%w: record %d: hash does not match", ErrChainTampered, seq)
+	}
+
+	if kind == kindCheckpoint {
+		var checkpoint Checkpoint
+		if err := json.Unmarshal(body, &checkpoint); err != nil {
+			return fmt.Errorf("%w: record %d: malformed checkpoint: %v", ErrChainTampered, seq, err)
+		}
+		if checkpoint.First != s.first || checkpoint.Last != s.seq || len(s.leaves) == 0 ||
+			checkpoint.Head != hex.EncodeToString(s.head) ||
+			checkpoint.Root != hex.EncodeToString(merkleRoot(s.leaves)) {
+			return fmt.Errorf("%w: record %d: checkpoint does not match records %d-%d", ErrChainTampered, seq, s.first, s.seq)
+		}
+		s.checkpoints = append(s.checkpoints, checkpoint)
+		s.leaves = nil
+		s.first = seq + 1
+	} else {
+		s.leaves = append(s.leaves, hash)
+	}
+	s.seq = seq
+	s.head = hash
+	s.hashes = append(s.hashes, hash)
+	return nil
+}
+
+// verifyAnchored checks an anchored checkpoint against the records read.
+func (s *chainState) verifyAnchored(checkpoint *Checkpoint) error {
+	if checkpoint.First == 0 || checkpoint.First > checkpoint.Last {
+		return fmt.Errorf("%w: anchored checkpoint %d: invalid range %d-%d", ErrChainTampered, checkpoint.Last, checkpoint.First, checkpoint.Last)
+	}
+	if checkpoint.Last > s.seq {
+		return fmt.Errorf("%w: anchored checkpoint %d: log ends at record %d", ErrChainTampered, checkpoint.Last, s.seq)
+	}
+	if hex.EncodeToString(s.hashes[checkpoint.Last-1]) != checkpoint.Head {
+		return fmt.Errorf("%w: anchored checkpoint %d: head does not match", ErrChainTampered, checkpoint.Last)
+	}
+	if hex.EncodeToString(merkleRoot(s.hashes[checkpoint.First-1:checkpoint.Last])) != checkpoint.Root {
+		return fmt.Errorf("%w: anchored checkpoint %d: root does not match", ErrChainTampered, checkpoint.Last)
+	}
+	return nil
+}
+
+// recordHash is SHA-256(prev || seq || kind || body).
+func recordHash(prev []byte, seq uint64, kind byte, body []byte) []byte {
+	h := sha256.New()
+	h.Write(prev)
+	var n [8]byte
+	binary.BigEndian.PutUint64(n[:], seq)
+	h.Write(n[:])
+	h.Write([]byte{kind})
+	h.Write(body)
+	return h.Sum(nil)
+}
+
+// merkleRoot returns the Merkle root of leaves, hashing leaves and interior
+// nodes with distinct prefixes and promoting an unpaired node unchanged.
+func merkleRoot(leaves [][]byte) []byte {
+	if len(leaves) == 0 {
+		return nil
+	}
+	level := make([][]byte, len(leaves))
+	for i, leaf := range leaves {
+		sum := sha256.Sum256(append([]byte{0}, leaf...))
+		level[i] = sum[:]
+	}
+	for len(level) > 1 {
+		next := make([][]byte, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				next = append(next, level[i])
+				continue
+			}
+			node := make([]byte, 0, 1+2*sha256.Size)
+			node = append(node, 1)
+			node = append(node, level[i]...)
+			node = append(node, level[i+1]...)
+			sum := sha256.Sum256(node)
+			next = append(next, sum[:])
+		}
+		level = next
+	}
+	return level[0]
+}
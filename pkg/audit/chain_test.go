@@ -0,0 +1,212 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+func newTestChain(t *testing.T, path string, interval int, anchor CheckpointAnchor) *ChainedAuditLogger {
+	t.Helper()
+	logger, err := NewChainedAuditLogger(&ChainConfig{Path: path, CheckpointInterval: interval, Anchor: anchor})
+	if err != nil {
+		t.Fatalf("NewChainedAuditLogger() error = %v", err)
+	}
+	return logger
+}
+
+func verifyFile(t *testing.T, path string, anchored []Checkpoint) (*ChainVerification, error) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return VerifyChain(bytes.NewReader(data), anchored)
+}
+
+func TestChainedAuditLogger(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "audit.log")
+	anchor := NewStorageAnchor(memory.New(), "checkpoints/")
+
+	logger := newTestChain(t, path, 3, anchor)
+	for i := 0; i < 4; i++ {
+		if err := logger.LogObjectAccess(ctx, "alice", "Alice", "default", "a.txt", "", "", ResultSuccess, nil); err != nil {
+			t.Fatalf("LogObjectAccess() error = %v", err)
+		}
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := logger.LogEvent(ctx, &AuditEvent{Action: "late"}); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("LogEvent() after Close error = %v, want os.ErrClosed", err)
+	}
+
+	// Reopening extends the same chain.
+	logger = newTestChain(t, path, 3, anchor)
+	if err := logger.LogAuthSuccess(ctx, "bob", "Bob", "", ""); err != nil {
+		t.Fatalf("LogAuthSuccess() error = %v", err)
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	anchored, err := anchor.Checkpoints(ctx)
+	if err != nil {
+		t.Fatalf("Checkpoints() error = %v", err)
+	}
+	// Events 1-3, then event 5 on close, then event 7 on the second close.
+	if len(anchored) != 3 || anchored[0].First != 1 || anchored[0].Last != 3 || anchored[1].First != 5 || anchored[2].Last != 7 {
+		t.Fatalf("anchored checkpoints = %+v", anchored)
+	}
+
+	result, err := verifyFile(t, path, anchored)
+	if err != nil {
+		t.Fatalf("VerifyChain() error = %v", err)
+	}
+	if result.Records != 8 || len(result.Checkpoints) != 3 || result.Anchored != 3 || result.Unanchored != 0 {
+		t.Errorf("VerifyChain() = %+v", result)
+	}
+}
+
+func TestVerifyChainDetectsTampering(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "audit.log")
+	anchor := NewStorageAnchor(memory.New(), "")
+
+	logger := newTestChain(t, path, 2, anchor)
+	for _, user := range []string{"alice", "bob", "carol", "dave", "erin"} {
+		if err := logger.LogAuthFailure(ctx, user, user, "", "", "bad password"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := logger.Checkpoint(ctx); err != nil {
+		t.Fatalf("Checkpoint() error = %v", err)
+	}
+	if cp, err := logger.Checkpoint(ctx); cp != nil || err != nil {
+		t.Errorf("Checkpoint() with nothing new = %v, %v", cp, err)
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+	anchored, err := anchor.Checkpoints(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.SplitAfter(strings.TrimSuffix(string(data), "\n"), "\n")
+
+	tests := []struct {
+		name string
+		log  string
+	}{
+		{"edited event", strings.Replace(string(data), `"user_id":"bob"`, `"user_id":"eve"`, 1)},
+		{"deleted record", strings.Join(append(append([]string{}, lines[:1]...), lines[2:]...), "")},
+		{"reordered records", lines[1] + lines[0] + strings.Join(lines[2:], "")},
+		{"truncated log", strings.Join(lines[:4], "")},
+		{"malformed record", string(data) + "{not json}\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := VerifyChain(strings.NewReader(tt.log), anchored); !errors.Is(err, ErrChainTampered) {
+				t.Errorf("VerifyChain() error = %v, want ErrChainTampered", err)
+			}
+		})
+	}
+
+	// A log rewritten from scratch chains correctly but no longer matches
+	// the anchored checkpoints.
+	rewritten := filepath.Join(t.TempDir(), "audit.log")
+	forger := newTestChain(t, rewritten, 2, nil)
+	for _, user := range []string{"alice", "mallory", "carol", "dave", "erin"} {
+		if err := forger.LogAuthFailure(ctx, user, user, "", "", "bad password"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := forger.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := verifyFile(t, rewritten, nil); err != nil {
+		t.Fatalf("VerifyChain() of the forged log alone error = %v", err)
+	}
+	if _, err := verifyFile(t, rewritten, anchored); !errors.Is(err, ErrChainTampered) {
+		t.Errorf("VerifyChain() of the forged log error = %v, want ErrChainTampered", err)
+	}
+
+	// A tampered log is not extended.
+	if err := os.WriteFile(path, []byte(tests[0].log), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewChainedAuditLogger(&ChainConfig{Path: path}); !errors.Is(err, ErrChainTampered) {
+		t.Errorf("NewChainedAuditLogger() of a tampered log error = %v, want ErrChainTampered", err)
+	}
+}
+
+func TestChainedAuditLoggerConfig(t *testing.T) {
+	if _, err := NewChainedAuditLogger(nil); !errors.Is(err, ErrInvalidChainConfig) {
+		t.Errorf("NewChainedAuditLogger(nil) error = %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "audit.log")
+	if _, err := NewChainedAuditLogger(&ChainConfig{Path: path, CheckpointInterval: -1}); !errors.Is(err, ErrInvalidChainConfig) {
+		t.Errorf("negative interval error = %v", err)
+	}
+
+	forward := &countingLogger{AuditLogger: NewNoOpAuditLogger()}
+	logger, err := NewChainedAuditLogger(&ChainConfig{Path: path, Forward: forward})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = logger.Close() }()
+	if err := logger.LogPolicyChange(context.Background(), "alice", "Alice", "default", "p1", "", "", ResultSuccess, nil); err != nil {
+		t.Fatal(err)
+	}
+	if forward.count != 1 {
+		t.Errorf("forwarded %d events, want 1", forward.count)
+	}
+}
+
+type countingLogger struct {
+	AuditLogger
+	count int
+}
+
+func (c *countingLogger) LogEvent(_ context.Context, _ *AuditEvent) error {
+	c.count++
+	return nil
+}
+
+func TestMerkleRoot(t *testing.T) {
+	leaves := [][]byte{{1}, {2}, {3}}
+	root := merkleRoot(leaves)
+	if len(root) != 32 {
+		t.Fatalf("root length = %d", len(root))
+	}
+	if bytes.Equal(root, merkleRoot([][]byte{{1}, {3}, {2}})) {
+		t.Error("reordered leaves produced the same root")
+	}
+	if merkleRoot(nil) != nil {
+		t.Error("root of no leaves should be nil")
+	}
+}
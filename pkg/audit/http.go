@@ -104,18 +104,14 @@ func LogRPC(ctx context.Context, auditLogger AuditLogger, transport, method stri
 		errorMessage = rpcErr.Error()
 	}
 
-	principalName := ""
-	userID := ""
-	if principal != nil {
-		principalName = principal.Name
-		userID = principal.ID
-	}
+	userID, principalName, onBehalfOf := principalFields(principal)
 
 	event := &AuditEvent{
 		Timestamp:    start,
 		EventType:    determineRPCEventType(method),
 		UserID:       userID,
 		Principal:    principalName,
+		OnBehalfOf:   onBehalfOf,
 		Action:       transport + " " + method,
 		Result:       result,
 		ErrorMessage: errorMessage,
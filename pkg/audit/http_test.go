@@ -183,3 +183,17 @@ func TestDetermineRPCEventType(t *testing.T) {
 		}
 	}
 }
+
+func TestLogRPC_OnBehalfOf(t *testing.T) {
+	logger := newRecordingAuditLogger()
+	principal := &adapters.Principal{ID: "svc", Name: "frontend", OnBehalfOf: "user-42"}
+	LogRPC(context.Background(), logger, "unix", "put", principal, time.Now(), nil)
+
+	event := logger.last()
+	if event == nil {
+		t.Fatal("no event recorded")
+	}
+	if event.UserID != "svc" || event.Principal != "frontend" || event.OnBehalfOf != "user-42" {
+		t.Errorf("event identity = %q/%q/%q, want svc/frontend/user-42", event.UserID, event.Principal, event.OnBehalfOf)
+	}
+}
@@ -56,6 +56,41 @@ const (
 	RequestStartTimeKey contextKey = "request_start_time"
 )
 
+// principalSlotKey is the context key for the *principalSlot that lets an
+// authentication interceptor running inside an audit interceptor report the
+// principal it authenticated.
+type principalSlotKey struct{}
+
+// principalSlot holds the principal reported by SetPrincipal.
+type principalSlot struct {
+	principal *adapters.Principal
+}
+
+// SetPrincipal records the authenticated principal for the audit interceptor
+// enclosing ctx. gRPC interceptors run in order, so the audit interceptor
+// cannot see the context the authentication interceptor derives; this is how
+// the principal reaches the audit record. It is a no-op outside an audit
+// interceptor.
+func SetPrincipal(ctx context.Context, principal *adapters.Principal) {
+	if slot, ok := ctx.Value(principalSlotKey{}).(*principalSlot); ok {
+		slot.principal = principal
+	}
+}
+
+// principalFields extracts the audit identity of a principal stored as
+// *adapters.Principal or adapters.Principal.
+func principalFields(value any) (userID, name, onBehalfOf string) {
+	switch p := value.(type) {
+	case *adapters.Principal:
+		if p != nil {
+			return p.ID, p.Name, p.OnBehalfOf
+		}
+	case adapters.Principal:
+		return p.ID, p.Name, p.OnBehalfOf
+	}
+	return "", "", ""
+}
+
 // GetAuditLogger retrieves the audit logger from the context
 func GetAuditLogger(ctx context.Context) AuditLogger {
 	if logger, ok := ctx.Value(AuditLoggerKey).(AuditLogger); ok {
@@ -111,20 +146,8 @@ func AuditMiddleware(auditLogger AuditLogger) gin.HandlerFunc {
 
 		// Extract principal from context if available.
 		// REST middleware stores *adapters.Principal; accept both pointer and value.
-		principal := ""
-		userID := ""
-		if principalValue, exists := c.Get("principal"); exists {
-			switch p := principalValue.(type) {
-			case *adapters.Principal:
-				if p != nil {
-					principal = p.Name
-					userID = p.ID
-				}
-			case adapters.Principal:
-				principal = p.Name
-				userID = p.ID
-			}
-		}
+		principalValue, _ := c.Get("principal")
+		userID, principal, onBehalfOf := principalFields(principalValue)
 
 		// Determine event type based on method and path
 		eventType := determineEventType(method, path)
@@ -148,6 +171,7 @@ func AuditMiddleware(auditLogger AuditLogger) gin.HandlerFunc {
 			EventType:    eventType,
 			UserID:       userID,
 			Principal:    principal,
+			OnBehalfOf:   onBehalfOf,
 			Bucket:       bucket,
 			Key:          key,
 			Action:       method + " " + path,
@@ -193,27 +217,18 @@ func AuditUnaryInterceptor(auditLogger AuditLogger) grpc.UnaryServerInterceptor
 		// Extract client IP
 		clientIP := extractClientIP(ctx)
 
-		// Extract principal if available (set by the authentication interceptor).
-		// The gRPC auth interceptor stores *adapters.Principal under
-		// adapters.PrincipalContextKey{}; accept both pointer and value forms.
-		principal := ""
-		userID := ""
-		if principalValue := ctx.Value(adapters.PrincipalContextKey{}); principalValue != nil {
-			switch p := principalValue.(type) {
-			case *adapters.Principal:
-				if p != nil {
-					principal = p.Name
-					userID = p.ID
-				}
-			case adapters.Principal:
-				principal = p.Name
-				userID = p.ID
-			}
-		}
+		// Let the authentication interceptor, which runs inside this one,
+		// report the principal
+		slot := &principalSlot{}
+		ctx = context.WithValue(ctx, principalSlotKey{}, slot)
 
 		// Call the handler
 		resp, err := handler(ctx, req)
 
+		// Extract the principal reported by authentication, falling back to
+		// one already in the context; accept both pointer and value forms.
+		userID, principal, onBehalfOf := slotPrincipal(ctx, slot)
+
 		// Calculate duration
 		duration := time.Since(startTime)
 
@@ -241,6 +256,7 @@ func AuditUnaryInterceptor(auditLogger AuditLogger) grpc.UnaryServerInterceptor
 			EventType:    eventType,
 			UserID:       userID,
 			Principal:    principal,
+			OnBehalfOf:   onBehalfOf,
 			Bucket:       bucket,
 			Key:          key,
 			Action:       info.FullMethod,
@@ -286,21 +302,9 @@ func AuditStreamInterceptor(auditLogger AuditLogger) grpc.StreamServerIntercepto
 		// Extract client IP
 		clientIP := extractClientIP(ctx)
 
-		// Extract principal if available (set by the authentication interceptor).
-		principal := ""
-		userID := ""
-		if principalValue := ctx.Value(adapters.PrincipalContextKey{}); principalValue != nil {
-			switch p := principalValue.(type) {
-			case *adapters.Principal:
-				if p != nil {
-					principal = p.Name
-					userID = p.ID
-				}
-			case adapters.Principal:
-				principal = p.Name
-				userID = p.ID
-			}
-		}
+		// Let the authentication interceptor report the principal
+		slot := &principalSlot{}
+		ctx = context.WithValue(ctx, principalSlotKey{}, slot)
 
 		// Wrap the stream with our context
 		wrappedStream := &auditServerStream{
@@ -311,6 +315,9 @@ func AuditStreamInterceptor(auditLogger AuditLogger) grpc.StreamServerIntercepto
 		// Call the handler
 		err := handler(srv, wrappedStream)
 
+		// Extract the principal reported by authentication
+		userID, principal, onBehalfOf := slotPrincipal(ctx, slot)
+
 		// Calculate duration
 		duration := time.Since(startTime)
 
@@ -335,6 +342,7 @@ func AuditStreamInterceptor(auditLogger AuditLogger) grpc.StreamServerIntercepto
 			EventType:    eventType,
 			UserID:       userID,
 			Principal:    principal,
+			OnBehalfOf:   onBehalfOf,
 			Action:       info.FullMethod,
 			Result:       result,
 			ErrorMessage: errorMessage,
@@ -354,6 +362,15 @@ func AuditStreamInterceptor(auditLogger AuditLogger) grpc.StreamServerIntercepto
 	}
 }
 
+// slotPrincipal returns the identity reported to slot by SetPrincipal, or of
+// the principal already stored in ctx.
+func slotPrincipal(ctx context.Context, slot *principalSlot) (userID, name, onBehalfOf string) {
+	if slot.principal != nil {
+		return principalFields(slot.principal)
+	}
+	return principalFields(ctx.Value(adapters.PrincipalContextKey{}))
+}
+
 // auditServerStream wraps grpc.ServerStream to provide a custom context
 type auditServerStream struct {
 	grpc.ServerStream
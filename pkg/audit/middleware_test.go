@@ -834,3 +834,51 @@ func TestExtractGRPCResourceInfo(t *testing.T) {
 		})
 	}
 }
+
+// TestAuditInterceptors_SetPrincipal verifies that a principal reported by an
+// inner authentication interceptor, including who it acts on behalf of,
+// reaches the audit record.
+func TestAuditInterceptors_SetPrincipal(t *testing.T) {
+	principal := &adapters.Principal{ID: "svc-1", Name: "frontend", OnBehalfOf: "alice@example.com"}
+	check := func(t *testing.T, logger *recordingAuditLogger) {
+		t.Helper()
+		event := logger.last()
+		if event == nil {
+			t.Fatal("no audit event recorded")
+		}
+		if event.UserID != "svc-1" || event.Principal != "frontend" || event.OnBehalfOf != "alice@example.com" {
+			t.Errorf("event identity = %q/%q/%q, want svc-1/frontend/alice@example.com",
+				event.UserID, event.Principal, event.OnBehalfOf)
+		}
+	}
+
+	t.Run("unary", func(t *testing.T) {
+		logger := newRecordingAuditLogger()
+		handler := func(ctx context.Context, req any) (any, error) {
+			SetPrincipal(ctx, principal)
+			return "ok", nil
+		}
+		info := &grpc.UnaryServerInfo{FullMethod: "/objstore.ObjectStore/Put"}
+		if _, err := AuditUnaryInterceptor(logger)(context.Background(), nil, info, handler); err != nil {
+			t.Fatalf("interceptor: %v", err)
+		}
+		check(t, logger)
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		logger := newRecordingAuditLogger()
+		handler := func(srv any, ss grpc.ServerStream) error {
+			SetPrincipal(ss.Context(), principal)
+			return nil
+		}
+		info := &grpc.StreamServerInfo{FullMethod: "/objstore.ObjectStore/Get"}
+		if err := AuditStreamInterceptor(logger)(nil, &mockServerStream{ctx: context.Background()}, info, handler); err != nil {
+			t.Fatalf("interceptor: %v", err)
+		}
+		check(t, logger)
+	})
+
+	t.Run("outside an audit interceptor", func(t *testing.T) {
+		SetPrincipal(context.Background(), principal) // must not panic
+	})
+}
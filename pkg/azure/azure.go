@@ -21,12 +21,15 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net/http"
 	"net/url"
 	"sync"
 	"time"
 
 	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/transport"
 
+	"github.com/Azure/azure-pipeline-go/pipeline"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/storage/armstorage"
 	"github.com/Azure/azure-storage-blob-go/azblob"
@@ -46,6 +49,8 @@ type BlobProperties struct {
 	ContentEncoding string
 	LastModified    time.Time
 	ETag            string
+	AccessTier      string
+	BlobType        string
 	Metadata        map[string]string
 }
 
@@ -64,25 +69,104 @@ type ContainerAPI interface {
 	ListBlobsFlat(ctx context.Context, prefix string) ([]string, error)
 }
 
+// tierBlob is implemented by blobs whose access tier can be changed. It is
+// kept separate from BlobAPI so test doubles need not implement it.
+type tierBlob interface {
+	SetTier(ctx context.Context, tier string) error
+}
+
+// uploadResult describes a completed block blob upload as reported by Azure.
+// VersionID is empty unless blob versioning is enabled on the account.
+type uploadResult struct {
+	ETag         string
+	LastModified time.Time
+	VersionID    string
+}
+
+// resultBlob is implemented by blobs whose uploads report the ETag,
+// modification time and version ID of the written blob. It is kept separate
+// from BlobAPI so test doubles need not implement it.
+type resultBlob interface {
+	UploadWithResult(ctx context.Context, r io.Reader) (*uploadResult, error)
+}
+
+// rangeBlob is implemented by blobs that support ranged downloads. It is kept
+// separate from BlobAPI so test doubles need not implement it.
+type rangeBlob interface {
+	NewRangeReader(ctx context.Context, offset, count int64) (io.ReadCloser, error)
+}
+
+// appendBlob is implemented by blobs that can be created and extended as
+// append blobs. It is kept separate from BlobAPI so test doubles need not
+// implement it.
+type appendBlob interface {
+	CreateAppendBlob(ctx context.Context) error
+	AppendBlock(ctx context.Context, block io.ReadSeeker) error
+}
+
+// snapshotContainer is implemented by containers that can list the
+// snapshots of a blob. It is kept separate from ContainerAPI so test
+// doubles need not implement it.
+type snapshotContainer interface {
+	ListSnapshots(ctx context.Context, name string) ([]blobSnapshot, error)
+}
+
+// snapshotBlob is implemented by blobs that can address one of their
+// snapshots. It is kept separate from BlobAPI so test doubles need not
+// implement it.
+type snapshotBlob interface {
+	Snapshot(snapshot string) BlobAPI
+}
+
+// blobSnapshot identifies a snapshot of a blob and when the content it
+// captured was written.
+type blobSnapshot struct {
+	Snapshot     string
+	LastModified time.Time
+}
+
 type containerWrapper struct{ azblob.ContainerURL }
 type blobWrapper struct{ azblob.BlockBlobURL }
 
 // Constants
 const (
-	actionDelete  = "delete"
-	actionArchive = "archive"
+	actionDelete     = "delete"
+	actionArchive    = "archive"
+	actionTransition = common.ActionTransition
+)
+
+// Access tiers that can be selected per blob or as a lifecycle transition
+// target.
+const (
+	tierHot     = "Hot"
+	tierCool    = "Cool"
+	tierCold    = "Cold"
+	tierArchive = "Archive"
 )
 
+var accessTiers = []string{tierHot, tierCool, tierCold, tierArchive}
+
+// blobTypeAppend is the BlobType of blobs that support AppendBlock.
+const blobTypeAppend = string(azblob.BlobAppendBlob)
+
 // Error variables
 var (
-	ErrLifecycleNotAvailable = fmt.Errorf("lifecycle management not available: subscriptionID and resourceGroup required in configuration")
+	ErrLifecycleNotAvailable  = fmt.Errorf("lifecycle management not available: subscriptionID and resourceGroup required in configuration")
+	ErrAccessTierNotSupported = fmt.Errorf("blob does not support access tiers")
 )
 
 // Function variables to enable unit testing without real network I/O.
 var (
-	azureUploadFn = func(ctx context.Context, r io.Reader, b azblob.BlockBlobURL) error {
-		_, err := azblob.UploadStreamToBlockBlob(ctx, r, b, azblob.UploadStreamToBlockBlobOptions{})
-		return err
+	azureUploadFn = func(ctx context.Context, r io.Reader, b azblob.BlockBlobURL) (*uploadResult, error) {
+		resp, err := azblob.UploadStreamToBlockBlob(ctx, r, b, azblob.UploadStreamToBlockBlobOptions{})
+		if err != nil {
+			return nil, err
+		}
+		result := &uploadResult{ETag: string(resp.ETag()), LastModified: resp.LastModified()}
+		if commit, ok := resp.(*azblob.BlockBlobCommitBlockListResponse); ok {
+			result.VersionID = commit.VersionID()
+		}
+		return result, nil
 	}
 	azureDownloadFn = func(ctx context.Context, b azblob.BlockBlobURL) (io.ReadCloser, error) {
 		resp, err := b.Download(ctx, 0, azblob.CountToEnd, azblob.BlobAccessConditions{}, false, azblob.ClientProvidedKeyOptions{})
@@ -91,6 +175,13 @@ var (
 		}
 		return resp.Body(azblob.RetryReaderOptions{}), nil
 	}
+	azureDownloadRangeFn = func(ctx context.Context, b azblob.BlockBlobURL, offset, count int64) (io.ReadCloser, error) {
+		resp, err := b.Download(ctx, offset, count, azblob.BlobAccessConditions{}, false, azblob.ClientProvidedKeyOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return resp.Body(azblob.RetryReaderOptions{}), nil
+	}
 	azureDeleteFn = func(ctx context.Context, b azblob.BlockBlobURL) error {
 		_, err := b.Delete(ctx, azblob.DeleteSnapshotsOptionNone, azblob.BlobAccessConditions{})
 		return err
@@ -106,6 +197,8 @@ var (
 			ContentEncoding: resp.ContentEncoding(),
 			LastModified:    resp.LastModified(),
 			ETag:            string(resp.ETag()),
+			AccessTier:      resp.AccessTier(),
+			BlobType:        string(resp.BlobType()),
 			Metadata:        resp.NewMetadata(),
 		}, nil
 	}
@@ -117,6 +210,18 @@ var (
 		_, err := b.SetHTTPHeaders(ctx, headers, azblob.BlobAccessConditions{})
 		return err
 	}
+	azureSetTierFn = func(ctx context.Context, b azblob.BlockBlobURL, tier string) error {
+		_, err := b.SetTier(ctx, azblob.AccessTierType(tier), azblob.LeaseAccessConditions{}, azblob.RehydratePriorityNone)
+		return err
+	}
+	azureCreateAppendBlobFn = func(ctx context.Context, b azblob.AppendBlobURL) error {
+		_, err := b.Create(ctx, azblob.BlobHTTPHeaders{}, azblob.Metadata{}, azblob.BlobAccessConditions{}, azblob.BlobTagsMap{}, azblob.ClientProvidedKeyOptions{}, azblob.ImmutabilityPolicyOptions{})
+		return err
+	}
+	azureAppendBlockFn = func(ctx context.Context, b azblob.AppendBlobURL, block io.ReadSeeker) error {
+		_, err := b.AppendBlock(ctx, block, azblob.AppendBlobAccessConditions{}, nil, azblob.ClientProvidedKeyOptions{})
+		return err
+	}
 	azureListFn = func(ctx context.Context, c azblob.ContainerURL, prefix string) ([]string, error) {
 		// Pre-allocate with reasonable capacity to reduce allocations
 		keys := make([]string, 0, 100)
@@ -139,6 +244,33 @@ var (
 
 		return keys, nil
 	}
+	azureListSnapshotsFn = func(ctx context.Context, c azblob.ContainerURL, name string) ([]blobSnapshot, error) {
+		var snapshots []blobSnapshot
+		marker := azblob.Marker{}
+
+		for marker.NotDone() {
+			listBlob, err := c.ListBlobsFlatSegment(ctx, marker, azblob.ListBlobsSegmentOptions{
+				Prefix:  name,
+				Details: azblob.BlobListingDetails{Snapshots: true},
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			for _, blob := range listBlob.Segment.BlobItems {
+				if blob.Name == name && blob.Snapshot != "" {
+					snapshots = append(snapshots, blobSnapshot{
+						Snapshot:     blob.Snapshot,
+						LastModified: blob.Properties.LastModified,
+					})
+				}
+			}
+
+			marker = listBlob.NextMarker
+		}
+
+		return snapshots, nil
+	}
 )
 
 func (c containerWrapper) NewBlockBlob(name string) BlobAPI {
@@ -149,12 +281,23 @@ func (c containerWrapper) ListBlobsFlat(ctx context.Context, prefix string) ([]s
 	return azureListFn(ctx, c.ContainerURL, prefix)
 }
 
+func (c containerWrapper) ListSnapshots(ctx context.Context, name string) ([]blobSnapshot, error) {
+	return azureListSnapshotsFn(ctx, c.ContainerURL, name)
+}
+
 func (b blobWrapper) UploadFromReader(ctx context.Context, r io.Reader) error {
+	_, err := azureUploadFn(ctx, r, b.BlockBlobURL)
+	return err
+}
+func (b blobWrapper) UploadWithResult(ctx context.Context, r io.Reader) (*uploadResult, error) {
 	return azureUploadFn(ctx, r, b.BlockBlobURL)
 }
 func (b blobWrapper) NewReader(ctx context.Context) (io.ReadCloser, error) {
 	return azureDownloadFn(ctx, b.BlockBlobURL)
 }
+func (b blobWrapper) NewRangeReader(ctx context.Context, offset, count int64) (io.ReadCloser, error) {
+	return azureDownloadRangeFn(ctx, b.BlockBlobURL, offset, count)
+}
 func (b blobWrapper) Delete(ctx context.Context) error {
 	return azureDeleteFn(ctx, b.BlockBlobURL)
 }
@@ -167,6 +310,18 @@ func (b blobWrapper) SetMetadata(ctx context.Context, metadata map[string]string
 func (b blobWrapper) SetHTTPHeaders(ctx context.Context, headers azblob.BlobHTTPHeaders) error {
 	return azureSetHTTPHeadersFn(ctx, b.BlockBlobURL, headers)
 }
+func (b blobWrapper) SetTier(ctx context.Context, tier string) error {
+	return azureSetTierFn(ctx, b.BlockBlobURL, tier)
+}
+func (b blobWrapper) CreateAppendBlob(ctx context.Context) error {
+	return azureCreateAppendBlobFn(ctx, b.ToAppendBlobURL())
+}
+func (b blobWrapper) AppendBlock(ctx context.Context, block io.ReadSeeker) error {
+	return azureAppendBlockFn(ctx, b.ToAppendBlobURL(), block)
+}
+func (b blobWrapper) Snapshot(snapshot string) BlobAPI {
+	return blobWrapper{b.BlockBlobURL.WithSnapshot(snapshot)}
+}
 
 // Azure is a storage backend that stores files in Azure Blob Storage.
 type Azure struct {
@@ -179,6 +334,7 @@ type Azure struct {
 	resourceGroup      string
 	accountName        string
 	containerName      string
+	timeouts           *transport.Timeouts
 	policiesMutex      sync.RWMutex
 	replicationManager common.ReplicationManager
 }
@@ -200,17 +356,24 @@ func New() common.Storage {
 //
 // Optional settings:
 //   - endpoint: Custom endpoint URL (for Azurite, etc.)
+//   - http*: HTTP transport tuning, see the transport package
+//   - timeout, getTimeout, ...: per-operation timeouts, see the transport package
 func (a *Azure) Configure(settings map[string]string) error {
+	timeouts, err := transport.ParseTimeouts(settings)
+	if err != nil {
+		return err
+	}
+	a.timeouts = timeouts
+
 	if a.TestContainerURL.URL().Host != "" { // If TestContainerURL is set, use it
 		a.container = containerWrapper{a.TestContainerURL}
 		return nil
 	}
 
 	accountName := settings["accountName"]
-	accountKey := settings["accountKey"]
 	containerName := settings["containerName"]
 
-	if accountName == "" || accountKey == "" || containerName == "" {
+	if accountName == "" || containerName == "" {
 		return common.ErrAccountNotSet
 	}
 
@@ -221,12 +384,20 @@ func (a *Azure) Configure(settings map[string]string) error {
 	a.resourceGroup = settings["resourceGroup"]
 
 	// Set up blob operations client
-	credential, err := azblob.NewSharedKeyCredential(accountName, accountKey)
+	auth, err := newAuthFactory(settings)
+	if err != nil {
+		return err
+	}
+
+	httpCfg, err := transport.ParseSettings(settings)
 	if err != nil {
 		return err
 	}
+	identity := accountName + "|" + settings["accountKey"] + "|" + settings["endpoint"] +
+		"|" + settings["credentials"] + "|" + settings["credentialsFile"] + "|" + settings["clientId"]
+	httpClient := transport.Default.Client("azure", identity, httpCfg)
 
-	p := azblob.NewPipeline(credential, azblob.PipelineOptions{})
+	p := newAuthPipeline(auth, azblob.PipelineOptions{HTTPSender: newHTTPSender(httpClient)})
 
 	var u *url.URL
 	var parseErr error
@@ -262,16 +433,27 @@ func (a *Azure) Configure(settings map[string]string) error {
 	return nil
 }
 
+// newHTTPSender returns a pipeline factory that sends requests through the
+// pooled client instead of the SDK's default client, so containers sharing an
+// account also share connections.
+func newHTTPSender(client *http.Client) pipeline.Factory {
+	return pipeline.FactoryFunc(func(_ pipeline.Policy, _ *pipeline.PolicyOptions) pipeline.PolicyFunc {
+		return func(ctx context.Context, request pipeline.Request) (pipeline.Response, error) {
+			resp, err := client.Do(request.WithContext(transport.Default.WithTrace(ctx, "azure")))
+			if err != nil {
+				err = pipeline.NewError(err, "HTTP request failed")
+			}
+			return pipeline.NewHTTPResponse(resp), err
+		}
+	})
+}
+
 // Put stores an object in the backend.
 func (a *Azure) Put(key string, data io.Reader) error {
 	if a.container == nil {
 		return common.ErrNotConfigured
 	}
-	if err := common.ValidateKey(key); err != nil {
-		return err
-	}
-	blob := a.container.NewBlockBlob(key)
-	return blob.UploadFromReader(context.Background(), data)
+	return a.PutWithContext(context.Background(), key, data)
 }
 
 // Get retrieves an object from the backend.
@@ -279,11 +461,7 @@ func (a *Azure) Get(key string) (io.ReadCloser, error) {
 	if a.container == nil {
 		return nil, common.ErrNotConfigured
 	}
-	if err := common.ValidateKey(key); err != nil {
-		return nil, err
-	}
-	blob := a.container.NewBlockBlob(key)
-	return blob.NewReader(context.Background())
+	return a.GetWithContext(context.Background(), key)
 }
 
 // Delete removes an object from the backend.
@@ -291,11 +469,7 @@ func (a *Azure) Delete(key string) error {
 	if a.container == nil {
 		return common.ErrNotConfigured
 	}
-	if err := common.ValidateKey(key); err != nil {
-		return err
-	}
-	blob := a.container.NewBlockBlob(key)
-	return blob.Delete(context.Background())
+	return a.DeleteWithContext(context.Background(), key)
 }
 
 // List returns a list of keys that start with the given prefix.
@@ -303,7 +477,7 @@ func (a *Azure) List(prefix string) ([]string, error) {
 	if a.container == nil {
 		return nil, common.ErrNotConfigured
 	}
-	return a.container.ListBlobsFlat(context.Background(), prefix)
+	return a.ListWithContext(context.Background(), prefix)
 }
 
 func (a *Azure) Archive(key string, destination common.Archiver) error {
@@ -321,9 +495,19 @@ func (a *Azure) AddPolicy(policy common.LifecyclePolicy) error {
 	if policy.ID == "" {
 		return common.ErrInvalidPolicy
 	}
-	if policy.Action != actionDelete && policy.Action != actionArchive {
+	if policy.Action != actionDelete && policy.Action != actionArchive && policy.Action != actionTransition {
 		return common.ErrInvalidPolicy
 	}
+	tier := tierArchive
+	if policy.Action == actionTransition {
+		var err error
+		if tier, err = common.NormalizeStorageClass(policy.StorageClass, accessTiers); err != nil {
+			return err
+		}
+		if tier == "" {
+			return fmt.Errorf("%w: transition policy requires a storage class", common.ErrInvalidArgument)
+		}
+	}
 
 	// Check if management client is available
 	if a.mgmtClient == nil {
@@ -333,7 +517,8 @@ func (a *Azure) AddPolicy(policy common.LifecyclePolicy) error {
 	a.policiesMutex.Lock()
 	defer a.policiesMutex.Unlock()
 
-	ctx := context.Background()
+	ctx, cancel := a.timeouts.Context(context.Background(), transport.OpMetadata)
+	defer cancel()
 
 	// Get existing management policy
 	existing, err := a.mgmtClient.Get(ctx, a.resourceGroup, a.accountName, armstorage.ManagementPolicyNameDefault, nil)
@@ -381,14 +566,22 @@ func (a *Azure) AddPolicy(policy common.LifecyclePolicy) error {
 				},
 			},
 		}
-	} else if policy.Action == "archive" {
-		newRule.Definition.Actions = &armstorage.ManagementPolicyAction{
-			BaseBlob: &armstorage.ManagementPolicyBaseBlob{
-				TierToArchive: &armstorage.DateAfterModification{
-					DaysAfterModificationGreaterThan: &daysAfterModification,
-				},
-			},
+	} else {
+		after := &armstorage.DateAfterModification{
+			DaysAfterModificationGreaterThan: &daysAfterModification,
 		}
+		baseBlob := &armstorage.ManagementPolicyBaseBlob{}
+		switch tier {
+		case tierHot:
+			baseBlob.TierToHot = after
+		case tierCool:
+			baseBlob.TierToCool = after
+		case tierCold:
+			baseBlob.TierToCold = after
+		default:
+			baseBlob.TierToArchive = after
+		}
+		newRule.Definition.Actions = &armstorage.ManagementPolicyAction{BaseBlob: baseBlob}
 	}
 
 	rules = append(rules, newRule)
@@ -416,7 +609,8 @@ func (a *Azure) RemovePolicy(id string) error {
 	a.policiesMutex.Lock()
 	defer a.policiesMutex.Unlock()
 
-	ctx := context.Background()
+	ctx, cancel := a.timeouts.Context(context.Background(), transport.OpMetadata)
+	defer cancel()
 
 	// Get existing management policy
 	existing, err := a.mgmtClient.Get(ctx, a.resourceGroup, a.accountName, armstorage.ManagementPolicyNameDefault, nil)
@@ -466,7 +660,8 @@ func (a *Azure) GetPolicies() ([]common.LifecyclePolicy, error) {
 	a.policiesMutex.RLock()
 	defer a.policiesMutex.RUnlock()
 
-	ctx := context.Background()
+	ctx, cancel := a.timeouts.Context(context.Background(), transport.OpMetadata)
+	defer cancel()
 
 	// Get management policy
 	result, err := a.mgmtClient.Get(ctx, a.resourceGroup, a.accountName, armstorage.ManagementPolicyNameDefault, nil)
@@ -513,6 +708,10 @@ func (a *Azure) GetPolicies() ([]common.LifecyclePolicy, error) {
 			} else if rule.Definition.Actions.BaseBlob.TierToArchive != nil && rule.Definition.Actions.BaseBlob.TierToArchive.DaysAfterModificationGreaterThan != nil {
 				policy.Action = "archive"
 				policy.Retention = time.Duration(*rule.Definition.Actions.BaseBlob.TierToArchive.DaysAfterModificationGreaterThan) * 24 * time.Hour
+			} else if tier, after := transitionTier(rule.Definition.Actions.BaseBlob); after != nil {
+				policy.Action = actionTransition
+				policy.StorageClass = tier
+				policy.Retention = time.Duration(*after.DaysAfterModificationGreaterThan) * 24 * time.Hour
 			} else {
 				continue // Skip rules we don't understand
 			}
@@ -526,6 +725,24 @@ func (a *Azure) GetPolicies() ([]common.LifecyclePolicy, error) {
 	return policies, nil
 }
 
+// transitionTier returns the tier and schedule of a non-archive tiering
+// action, or a nil schedule if the rule has none.
+func transitionTier(baseBlob *armstorage.ManagementPolicyBaseBlob) (string, *armstorage.DateAfterModification) {
+	for _, t := range []struct {
+		tier  string
+		after *armstorage.DateAfterModification
+	}{
+		{tierCool, baseBlob.TierToCool},
+		{tierCold, baseBlob.TierToCold},
+		{tierHot, baseBlob.TierToHot},
+	} {
+		if t.after != nil && t.after.DaysAfterModificationGreaterThan != nil {
+			return t.tier, t.after
+		}
+	}
+	return "", nil
+}
+
 // GetReplicationManager returns the replication manager for this backend.
 // This method implements the common.ReplicationCapable interface.
 func (a *Azure) GetReplicationManager() (common.ReplicationManager, error) {
@@ -794,36 +794,62 @@ func TestAzure_GetPolicies_NilPrefixMatch(t *testing.T) {
 }
 
 // ---------------------------------------------------------------------------
-// mapNotFound helper
+// translateError helper
 // ---------------------------------------------------------------------------
 
-// TestAzure_MapNotFound_NonStorageError covers the pass-through branch where
-// the error is not an azblob.StorageError.
-func TestAzure_MapNotFound_NonStorageError(t *testing.T) {
+// TestAzure_TranslateError_NonStorageError covers the pass-through branch
+// where the error is not an azblob.StorageError.
+func TestAzure_TranslateError_NonStorageError(t *testing.T) {
 	plainErr := errors.New("plain error")
-	got := mapNotFound(plainErr, "key")
+	got := translateError(plainErr, "key")
 	if got != plainErr {
 		t.Fatalf("expected same error, got %v", got)
 	}
 }
 
-// TestAzure_MapNotFound_BlobNotFound covers mapping BlobNotFound to ErrKeyNotFound.
-func TestAzure_MapNotFound_BlobNotFound(t *testing.T) {
+// TestAzure_TranslateError_BlobNotFound covers mapping BlobNotFound to ErrKeyNotFound.
+func TestAzure_TranslateError_BlobNotFound(t *testing.T) {
 	stgErr := &fakeStorageError{code: azblob.ServiceCodeBlobNotFound}
-	got := mapNotFound(stgErr, "mykey")
+	got := translateError(stgErr, "mykey")
 	if !errors.Is(got, common.ErrKeyNotFound) {
 		t.Fatalf("expected ErrKeyNotFound, got %v", got)
 	}
 }
 
-// TestAzure_MapNotFound_OtherStorageError covers a StorageError that is NOT
-// BlobNotFound — should be returned unchanged.
-func TestAzure_MapNotFound_OtherStorageError(t *testing.T) {
-	stgErr := &fakeStorageError{code: azblob.ServiceCodeContainerAlreadyExists}
-	got := mapNotFound(stgErr, "key")
-	if errors.Is(got, common.ErrKeyNotFound) {
-		t.Fatal("non-BlobNotFound StorageError must not map to ErrKeyNotFound")
-	}
+// TestAzure_TranslateError_ServiceCodes covers the remaining service code
+// mappings; the storage error must stay in the chain.
+func TestAzure_TranslateError_ServiceCodes(t *testing.T) {
+	tests := []struct {
+		code azblob.ServiceCodeType
+		want error
+	}{
+		{azblob.ServiceCodeContainerNotFound, common.ErrNotFound},
+		{azblob.ServiceCodeContainerAlreadyExists, common.ErrAlreadyExists},
+		{azblob.ServiceCodeBlobAlreadyExists, common.ErrAlreadyExists},
+		{azblob.ServiceCodeAuthenticationFailed, common.ErrAccessDenied},
+		{"AuthorizationFailure", common.ErrAccessDenied},
+		{azblob.ServiceCodeConditionNotMet, common.ErrPreconditionFailed},
+		{azblob.ServiceCodeServerBusy, common.ErrQuotaExceeded},
+		{azblob.ServiceCodeOperationTimedOut, common.ErrBackendUnavailable},
+	}
+	for _, tt := range tests {
+		stgErr := &fakeStorageError{code: tt.code}
+		got := translateError(stgErr, "key")
+		if !errors.Is(got, tt.want) {
+			t.Errorf("translateError(%s) = %v, want %v", tt.code, got, tt.want)
+		}
+		var target azblob.StorageError
+		if !errors.As(got, &target) {
+			t.Errorf("translateError(%s) dropped the storage error from the chain", tt.code)
+		}
+	}
+}
+
+// TestAzure_TranslateError_UnknownCode covers an unmapped service code with
+// no response, which is wrapped as-is.
+func TestAzure_TranslateError_UnknownCode(t *testing.T) {
+	stgErr := &fakeStorageError{code: azblob.ServiceCodeInvalidBlobType}
+	got := translateError(stgErr, "key")
 	if got != stgErr {
 		t.Fatalf("expected unchanged error, got %v", got)
 	}
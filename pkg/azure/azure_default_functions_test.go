@@ -46,8 +46,8 @@ func TestDefaultFunctions_Compile(t *testing.T) {
 
 	// Test that we can stub and restore them
 	oldUp := azureUploadFn
-	azureUploadFn = func(ctx context.Context, r io.Reader, b azblob.BlockBlobURL) error {
-		return nil
+	azureUploadFn = func(ctx context.Context, r io.Reader, b azblob.BlockBlobURL) (*uploadResult, error) {
+		return nil, nil
 	}
 	azureUploadFn = oldUp
 
@@ -16,26 +16,19 @@
 package azure
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/transport"
 
 	"github.com/Azure/azure-storage-blob-go/azblob"
 )
 
-// mapNotFound translates an Azure BlobNotFound storage error into an error
-// wrapping common.ErrKeyNotFound; all other errors are returned unchanged.
-func mapNotFound(err error, key string) error {
-	var stgErr azblob.StorageError
-	if errors.As(err, &stgErr) && stgErr.ServiceCode() == azblob.ServiceCodeBlobNotFound {
-		return fmt.Errorf("%w: %s", common.ErrKeyNotFound, key)
-	}
-	return err
-}
-
 // PutWithContext stores an object in the backend with context support.
 func (a *Azure) PutWithContext(ctx context.Context, key string, data io.Reader) error {
 	return a.PutWithMetadata(ctx, key, data, nil)
@@ -43,11 +36,93 @@ func (a *Azure) PutWithContext(ctx context.Context, key string, data io.Reader)
 
 // PutWithMetadata stores an object with associated metadata.
 func (a *Azure) PutWithMetadata(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) error {
+	_, _, err := a.put(ctx, key, data, metadata)
+	return err
+}
+
+// PutWithInfo stores an object with associated metadata and returns the
+// ETag, modification time and version ID Azure reported for the upload,
+// with the size written. Blobs that report no upload result yield only the
+// size and the time the write completed.
+func (a *Azure) PutWithInfo(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) (*common.ObjectInfo, error) {
+	size, result, err := a.put(ctx, key, data, metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &common.Metadata{Size: size, LastModified: time.Now().UTC()}
+	if metadata != nil {
+		info.StorageClass = metadata.StorageClass
+	}
+	if result != nil {
+		info.ETag = result.ETag
+		info.Version = result.VersionID
+		if !result.LastModified.IsZero() {
+			info.LastModified = result.LastModified
+		}
+	}
+	return &common.ObjectInfo{Key: key, Metadata: info}, nil
+}
+
+// put uploads data to key and moves it to the requested access tier. It
+// returns the number of bytes uploaded and, when the blob reports one, the
+// upload result.
+func (a *Azure) put(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) (int64, *uploadResult, error) {
 	if err := common.ValidateKey(key); err != nil {
-		return err
+		return 0, nil, err
+	}
+	var tier string
+	if metadata != nil {
+		var err error
+		if tier, err = common.NormalizeStorageClass(metadata.StorageClass, accessTiers); err != nil {
+			return 0, nil, err
+		}
 	}
+	ctx, cancel := a.timeouts.Context(ctx, transport.OpPut)
+	defer cancel()
 	blob := a.container.NewBlockBlob(key)
-	return blob.UploadFromReader(ctx, data)
+	counter := &countingReader{r: data}
+	var result *uploadResult
+	var err error
+	if rb, ok := blob.(resultBlob); ok {
+		result, err = rb.UploadWithResult(ctx, counter)
+	} else {
+		err = blob.UploadFromReader(ctx, counter)
+	}
+	if err != nil {
+		return 0, nil, translateError(err, key)
+	}
+	if err := setTier(ctx, blob, key, tier); err != nil {
+		return 0, nil, err
+	}
+	return counter.n, result, nil
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// setTier moves blob to tier. An empty tier leaves the account default.
+func setTier(ctx context.Context, blob BlobAPI, key, tier string) error {
+	if tier == "" {
+		return nil
+	}
+	tb, ok := blob.(tierBlob)
+	if !ok {
+		return ErrAccessTierNotSupported
+	}
+	if err := tb.SetTier(ctx, tier); err != nil {
+		return translateError(err, key)
+	}
+	return nil
 }
 
 // GetWithContext retrieves an object from the backend with context support.
@@ -55,8 +130,45 @@ func (a *Azure) GetWithContext(ctx context.Context, key string) (io.ReadCloser,
 	if err := common.ValidateKey(key); err != nil {
 		return nil, err
 	}
+	ctx, cancel := a.timeouts.Context(ctx, transport.OpGet)
 	blob := a.container.NewBlockBlob(key)
-	return blob.NewReader(ctx)
+	rc, err := blob.NewReader(ctx)
+	if err != nil {
+		cancel()
+		return nil, translateError(err, key)
+	}
+	return transport.CancelOnClose(rc, cancel), nil
+}
+
+// GetRange retrieves length bytes of an object starting at offset. A negative
+// length reads to the end of the object.
+func (a *Azure) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	if err := common.ValidateKey(key); err != nil {
+		return nil, err
+	}
+	if offset < 0 {
+		return nil, fmt.Errorf("%w: negative range offset %d", common.ErrInvalidArgument, offset)
+	}
+	ctx, cancel := a.timeouts.Context(ctx, transport.OpGet)
+	blob := a.container.NewBlockBlob(key)
+	if rb, ok := blob.(rangeBlob); ok {
+		count := length
+		if count < 0 {
+			count = azblob.CountToEnd
+		}
+		rc, err := rb.NewRangeReader(ctx, offset, count)
+		if err != nil {
+			cancel()
+			return nil, translateError(err, key)
+		}
+		return transport.CancelOnClose(rc, cancel), nil
+	}
+	rc, err := blob.NewReader(ctx)
+	if err != nil {
+		cancel()
+		return nil, translateError(err, key)
+	}
+	return common.SliceRange(transport.CancelOnClose(rc, cancel), offset, length)
 }
 
 // GetMetadata retrieves only the metadata for an object.
@@ -66,17 +178,25 @@ func (a *Azure) GetMetadata(ctx context.Context, key string) (*common.Metadata,
 	if err := common.ValidateKey(key); err != nil {
 		return nil, err
 	}
+	ctx, cancel := a.timeouts.Context(ctx, transport.OpMetadata)
+	defer cancel()
 	blob := a.container.NewBlockBlob(key)
 	props, err := blob.GetProperties(ctx)
 	if err != nil {
-		return nil, mapNotFound(err, key)
+		return nil, translateError(err, key)
 	}
+	return propertiesMetadata(props), nil
+}
+
+// propertiesMetadata converts blob properties to common.Metadata.
+func propertiesMetadata(props *BlobProperties) *common.Metadata {
 	metadata := &common.Metadata{
 		ContentType:     props.ContentType,
 		ContentEncoding: props.ContentEncoding,
 		Size:            props.Size,
 		LastModified:    props.LastModified,
 		ETag:            props.ETag,
+		StorageClass:    props.AccessTier,
 	}
 	if len(props.Metadata) > 0 {
 		metadata.Custom = make(map[string]string, len(props.Metadata))
@@ -84,7 +204,7 @@ func (a *Azure) GetMetadata(ctx context.Context, key string) (*common.Metadata,
 			metadata.Custom[k] = v
 		}
 	}
-	return metadata, nil
+	return metadata
 }
 
 // UpdateMetadata updates the metadata for an existing object.
@@ -99,18 +219,24 @@ func (a *Azure) UpdateMetadata(ctx context.Context, key string, metadata *common
 	if metadata == nil {
 		metadata = &common.Metadata{}
 	}
+	tier, err := common.NormalizeStorageClass(metadata.StorageClass, accessTiers)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := a.timeouts.Context(ctx, transport.OpMetadata)
+	defer cancel()
 	blob := a.container.NewBlockBlob(key)
 	if err := blob.SetMetadata(ctx, metadata.Custom); err != nil {
-		return mapNotFound(err, key)
+		return translateError(err, key)
 	}
 	headers := azblob.BlobHTTPHeaders{
 		ContentType:     metadata.ContentType,
 		ContentEncoding: metadata.ContentEncoding,
 	}
 	if err := blob.SetHTTPHeaders(ctx, headers); err != nil {
-		return mapNotFound(err, key)
+		return translateError(err, key)
 	}
-	return nil
+	return setTier(ctx, blob, key, tier)
 }
 
 // DeleteWithContext removes an object from the backend with context support.
@@ -118,19 +244,23 @@ func (a *Azure) DeleteWithContext(ctx context.Context, key string) error {
 	if err := common.ValidateKey(key); err != nil {
 		return err
 	}
+	ctx, cancel := a.timeouts.Context(ctx, transport.OpDelete)
+	defer cancel()
 	blob := a.container.NewBlockBlob(key)
-	return blob.Delete(ctx)
+	return translateError(blob.Delete(ctx), key)
 }
 
 // Exists checks if an object exists in the backend.
-// Returns false,nil only for a BlobNotFound condition; propagates all other errors.
+// Returns false,nil only for a not-found condition; propagates all other errors.
 func (a *Azure) Exists(ctx context.Context, key string) (bool, error) {
 	if err := common.ValidateKey(key); err != nil {
 		return false, err
 	}
+	ctx, cancel := a.timeouts.Context(ctx, transport.OpMetadata)
+	defer cancel()
 	blob := a.container.NewBlockBlob(key)
 	if _, err := blob.GetProperties(ctx); err != nil {
-		if errors.Is(mapNotFound(err, key), common.ErrKeyNotFound) {
+		if err = translateError(err, key); errors.Is(err, common.ErrNotFound) {
 			return false, nil
 		}
 		return false, err
@@ -140,7 +270,13 @@ func (a *Azure) Exists(ctx context.Context, key string) (bool, error) {
 
 // ListWithContext returns a list of keys with context support.
 func (a *Azure) ListWithContext(ctx context.Context, prefix string) ([]string, error) {
-	return a.container.ListBlobsFlat(ctx, prefix)
+	ctx, cancel := a.timeouts.Context(ctx, transport.OpList)
+	defer cancel()
+	keys, err := a.container.ListBlobsFlat(ctx, prefix)
+	if err != nil {
+		return nil, translateError(err, "")
+	}
+	return keys, nil
 }
 
 // ListWithOptions returns a paginated list of objects with full metadata.
@@ -150,10 +286,13 @@ func (a *Azure) ListWithOptions(ctx context.Context, opts *common.ListOptions) (
 		opts = &common.ListOptions{}
 	}
 
+	ctx, cancel := a.timeouts.Context(ctx, transport.OpList)
+	defer cancel()
+
 	// Use the existing list method
 	keys, err := a.container.ListBlobsFlat(ctx, opts.Prefix)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "")
 	}
 
 	result := &common.ListResult{
@@ -201,3 +340,48 @@ func (a *Azure) ListWithOptions(ctx context.Context, opts *common.ListOptions) (
 
 	return result, nil
 }
+
+// Append adds data to the end of the blob stored under key. A missing blob is
+// created as an append blob and extended in 4 MiB blocks, so its existing
+// contents are never transferred. Block blobs cannot be appended to and are
+// rewritten with common.EmulateAppend instead.
+func (a *Azure) Append(ctx context.Context, key string, data io.Reader) error {
+	if err := common.ValidateKey(key); err != nil {
+		return err
+	}
+	blob := a.container.NewBlockBlob(key)
+	ab, ok := blob.(appendBlob)
+	if !ok {
+		return common.EmulateAppend(ctx, a, key, data)
+	}
+	ctx, cancel := a.timeouts.Context(ctx, transport.OpPut)
+	defer cancel()
+
+	props, err := blob.GetProperties(ctx)
+	switch err = translateError(err, key); {
+	case errors.Is(err, common.ErrKeyNotFound):
+		if err := ab.CreateAppendBlob(ctx); err != nil {
+			return translateError(err, key)
+		}
+	case err != nil:
+		return err
+	case props.BlobType != blobTypeAppend:
+		return common.EmulateAppend(ctx, a, key, data)
+	}
+
+	buf := make([]byte, azblob.AppendBlobMaxAppendBlockBytes)
+	for {
+		n, err := io.ReadFull(data, buf)
+		if n > 0 {
+			if err := ab.AppendBlock(ctx, bytes.NewReader(buf[:n])); err != nil {
+				return translateError(err, key)
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
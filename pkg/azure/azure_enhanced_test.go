@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -160,6 +161,61 @@ func TestAzure_PutWithMetadata(t *testing.T) {
 	}
 }
 
+// resultMockBlob is a mockBlob whose uploads report a result.
+type resultMockBlob struct {
+	mockBlob
+	result *uploadResult
+}
+
+func (m *resultMockBlob) UploadWithResult(ctx context.Context, r io.Reader) (*uploadResult, error) {
+	if err := m.UploadFromReader(ctx, r); err != nil {
+		return nil, err
+	}
+	return m.result, nil
+}
+
+func TestAzure_PutWithInfo(t *testing.T) {
+	modified := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	drain := func(_ context.Context, r io.Reader) error {
+		_, err := io.Copy(io.Discard, r)
+		return err
+	}
+	blobs := map[string]BlobAPI{
+		"key": &resultMockBlob{
+			mockBlob: mockBlob{uploadFn: drain},
+			result:   &uploadResult{ETag: `"0x8DD"`, LastModified: modified, VersionID: "2025-06-01T12:00:00.0000000Z"},
+		},
+		"plain": &mockBlob{uploadFn: drain},
+		"bad":   &mockBlob{uploadFn: func(context.Context, io.Reader) error { return errTestPutError }},
+	}
+	a := &Azure{container: &mockContainerEnhanced{newBlockBlobFn: func(name string) BlobAPI { return blobs[name] }}}
+	ctx := context.Background()
+
+	info, err := a.PutWithInfo(ctx, "key", strings.NewReader("data"), nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if info.Key != "key" || info.Metadata.Size != 4 || info.Metadata.ETag != `"0x8DD"` || info.Metadata.Version != "2025-06-01T12:00:00.0000000Z" {
+		t.Fatalf("unexpected info: %+v %+v", info, info.Metadata)
+	}
+	if !info.Metadata.LastModified.Equal(modified) {
+		t.Fatalf("expected LastModified %v, got %v", modified, info.Metadata.LastModified)
+	}
+
+	// Blobs that report no upload result still yield the size written.
+	info, err = a.PutWithInfo(ctx, "plain", strings.NewReader("abc"), nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if info.Metadata.Size != 3 || info.Metadata.ETag != "" || info.Metadata.LastModified.IsZero() {
+		t.Fatalf("unexpected metadata: %+v", info.Metadata)
+	}
+
+	if _, err := a.PutWithInfo(ctx, "bad", strings.NewReader("data"), nil); !errors.Is(err, errTestPutError) {
+		t.Fatalf("expected put error, got %v", err)
+	}
+}
+
 // TestAzure_GetWithContext tests context-aware get operation
 func TestAzure_GetWithContext(t *testing.T) {
 	mockCont := &mockContainerEnhanced{
@@ -43,7 +43,7 @@ func TestAzure_FunctionVariables(t *testing.T) {
 
 		// Call the function variable
 		// This will likely fail with network error, but that's okay - we're just covering the code
-		_ = azureUploadFn(context.Background(), strings.NewReader("test"), blobURL)
+		_, _ = azureUploadFn(context.Background(), strings.NewReader("test"), blobURL)
 	})
 
 	t.Run("azureDownloadFn", func(t *testing.T) {
@@ -21,12 +21,18 @@ import (
 	"errors"
 	"io"
 	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/credfile"
 
+	"github.com/Azure/azure-pipeline-go/pipeline"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/storage/armstorage"
 	"github.com/Azure/azure-storage-blob-go/azblob"
 )
@@ -786,6 +792,49 @@ func TestAzure_AddPolicy_Archive(t *testing.T) {
 	}
 }
 
+func TestAzure_AddPolicy_Transition(t *testing.T) {
+	mockMgmt := &mockManagementPoliciesClient{}
+	a := &Azure{
+		mgmtClient:     mockMgmt,
+		subscriptionID: "test-sub",
+		resourceGroup:  "test-rg",
+		accountName:    "testaccount",
+		containerName:  "testcontainer",
+	}
+
+	policy := common.LifecyclePolicy{
+		ID:           "cool-policy",
+		Prefix:       "reports/",
+		Retention:    30 * 24 * time.Hour,
+		Action:       common.ActionTransition,
+		StorageClass: "cool",
+	}
+	if err := a.AddPolicy(policy); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	baseBlob := mockMgmt.policy.Properties.Policy.Rules[0].Definition.Actions.BaseBlob
+	if baseBlob.TierToCool == nil || baseBlob.TierToArchive != nil {
+		t.Fatalf("expected only TierToCool to be set, got %+v", baseBlob)
+	}
+
+	policies, err := a.GetPolicies()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(policies) != 1 {
+		t.Fatalf("expected 1 policy, got %d", len(policies))
+	}
+	if policies[0].Action != common.ActionTransition || policies[0].StorageClass != "Cool" {
+		t.Fatalf("expected transition to Cool, got %s %s", policies[0].Action, policies[0].StorageClass)
+	}
+
+	policy.StorageClass = "NEARLINE"
+	if err := a.AddPolicy(policy); !errors.Is(err, common.ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument, got %v", err)
+	}
+}
+
 func TestAzure_AddPolicy_InvalidID(t *testing.T) {
 	mockMgmt := &mockManagementPoliciesClient{}
 	a := &Azure{
@@ -1063,3 +1112,185 @@ func TestAzure_List_NotConfigured(t *testing.T) {
 		t.Fatalf("List() error = %v, want error containing 'not configured'", err)
 	}
 }
+
+// appendMemBlob is a memBlob that can also act as an append blob.
+type appendMemBlob struct {
+	*memBlob
+	blobType string
+	blocks   int
+}
+
+func (m *appendMemBlob) GetProperties(ctx context.Context) (*BlobProperties, error) {
+	props, err := m.memBlob.GetProperties(ctx)
+	if err != nil {
+		return nil, err
+	}
+	props.BlobType = m.blobType
+	return props, nil
+}
+
+func (m *appendMemBlob) CreateAppendBlob(_ context.Context) error {
+	m.data, m.blobType = []byte{}, blobTypeAppend
+	return nil
+}
+
+func (m *appendMemBlob) AppendBlock(_ context.Context, block io.ReadSeeker) error {
+	b, _ := io.ReadAll(block)
+	m.data = append(m.data, b...)
+	m.blocks++
+	return nil
+}
+
+type appendContainer struct{ blobs map[string]*appendMemBlob }
+
+func (c appendContainer) NewBlockBlob(name string) BlobAPI {
+	if c.blobs[name] == nil {
+		c.blobs[name] = &appendMemBlob{memBlob: &memBlob{}}
+	}
+	return c.blobs[name]
+}
+
+func (c appendContainer) ListBlobsFlat(context.Context, string) ([]string, error) {
+	return nil, nil
+}
+
+func TestAzure_Append(t *testing.T) {
+	ctx := context.Background()
+	container := appendContainer{blobs: map[string]*appendMemBlob{
+		"app.log":   {memBlob: &memBlob{data: []byte("a")}, blobType: blobTypeAppend},
+		"block.log": {memBlob: &memBlob{data: []byte("a")}, blobType: "BlockBlob"},
+	}}
+	a := &Azure{container: container}
+
+	// Append blobs are extended in blocks of at most 4 MiB.
+	data := bytes.Repeat([]byte("x"), azblob.AppendBlobMaxAppendBlockBytes+1)
+	if err := a.Append(ctx, "app.log", bytes.NewReader(data)); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	blob := container.blobs["app.log"]
+	if blob.blocks != 2 || len(blob.data) != len(data)+1 {
+		t.Errorf("Append() wrote %d bytes in %d blocks, want %d bytes in 2 blocks", len(blob.data), blob.blocks, len(data)+1)
+	}
+
+	// Block blobs cannot be appended to and are rewritten instead.
+	if err := a.Append(ctx, "block.log", strings.NewReader("b")); err != nil {
+		t.Fatalf("Append() to block blob error = %v", err)
+	}
+	blob = container.blobs["block.log"]
+	if string(blob.data) != "ab" || blob.blocks != 0 {
+		t.Errorf("Append() to block blob = %q in %d blocks, want %q rewritten", blob.data, blob.blocks, "ab")
+	}
+
+	if err := a.Append(ctx, "", strings.NewReader("x")); err == nil {
+		t.Error("Append() with empty key should fail")
+	}
+}
+
+// testAccountKey is a well-formed, base64 encoded account key.
+const testAccountKey = "MDEyMzQ1Njc4OTAxMjM0NTY3ODkwMTIzNDU2Nzg5MDEyMzQ1Njc4OTAxMjM0NTY3ODkwMTIzNDU2Nzg5MDEyMzQ1Njc4OTAxMjM0NTY3ODkwMTIzNDU2Nzg5MA=="
+
+func TestAzure_Configure_CredentialProviders(t *testing.T) {
+	base := map[string]string{"accountName": "account", "containerName": "container"}
+	with := func(extra map[string]string) map[string]string {
+		settings := map[string]string{}
+		for k, v := range base {
+			settings[k] = v
+		}
+		for k, v := range extra {
+			settings[k] = v
+		}
+		return settings
+	}
+
+	keyFile := filepath.Join(t.TempDir(), "azure.env")
+	if err := os.WriteFile(keyFile, []byte("AZURE_STORAGE_KEY="+testAccountKey+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for _, extra := range []map[string]string{
+		{"credentials": CredentialsManagedIdentity, "clientId": "00000000-0000-0000-0000-000000000000"},
+		{"credentials": CredentialsFile, "credentialsFile": keyFile},
+	} {
+		if err := (&Azure{}).Configure(with(extra)); err != nil {
+			t.Errorf("Configure(%v) error = %v", extra, err)
+		}
+	}
+
+	for _, extra := range []map[string]string{
+		{"credentials": "vault"},
+		{"credentials": CredentialsSharedKey},
+		{"credentials": CredentialsFile},
+	} {
+		if err := (&Azure{}).Configure(with(extra)); err == nil {
+			t.Errorf("Configure(%v) succeeded, want an error", extra)
+		}
+	}
+}
+
+func TestAzure_SharedKeyFileRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "azure.json")
+	if err := os.WriteFile(path, []byte(`{"accountKey": "`+testAccountKey+`"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	file, err := credfile.Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	file.SetCheckInterval(0)
+	f := &sharedKeyFileFactory{file: file, accountName: "account"}
+
+	first, err := f.credential()
+	if err != nil {
+		t.Fatalf("credential() error = %v", err)
+	}
+	if again, _ := f.credential(); again != first {
+		t.Fatal("credential() rebuilt the credential of an unchanged key")
+	}
+
+	rotated := strings.Repeat("B", 86) + "=="
+	if err := os.WriteFile(path, []byte(`{"accountKey": "`+rotated+`", "accountName": "account"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	second, err := f.credential()
+	if err != nil || second == first {
+		t.Fatalf("credential() after rotation = %v, %v, want a new credential", second, err)
+	}
+
+	if err := os.WriteFile(path, []byte(`{}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if kept, err := f.credential(); err != nil || kept != second {
+		t.Fatalf("credential() without a key = %v, %v, want the previous credential", kept, err)
+	}
+}
+
+// staticToken is a token credential that always returns the same token.
+type staticToken string
+
+func (s staticToken) GetToken(context.Context, policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{Token: string(s), ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+func TestAzure_TokenFactory(t *testing.T) {
+	var got string
+	next := pipeline.PolicyFunc(func(_ context.Context, request pipeline.Request) (pipeline.Response, error) {
+		got = request.Header.Get("Authorization")
+		return nil, nil
+	})
+	p := (&tokenFactory{cred: staticToken("t0k3n")}).New(next, nil)
+
+	request, err := pipeline.NewRequest("GET", url.URL{Scheme: "https", Host: "account.blob.core.windows.net"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Do(context.Background(), request); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if got != "Bearer t0k3n" {
+		t.Fatalf("Authorization = %q", got)
+	}
+
+	insecure, _ := pipeline.NewRequest("GET", url.URL{Scheme: "http", Host: "127.0.0.1"}, nil)
+	if _, err := p.Do(context.Background(), insecure); !errors.Is(err, common.ErrInvalidArgument) {
+		t.Fatalf("Do() over http error = %v, want ErrInvalidArgument", err)
+	}
+}
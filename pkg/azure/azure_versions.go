@@ -0,0 +1,102 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build azureblob
+
+package azure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/transport"
+)
+
+// GetAsOf reads a blob as it was at at. The current blob is returned if
+// it was last written at or before at; otherwise the newest snapshot of
+// content written at or before at is. Changes that were overwritten
+// before any snapshot captured them cannot be recovered.
+func (a *Azure) GetAsOf(ctx context.Context, key string, at time.Time) (io.ReadCloser, *common.Metadata, error) {
+	if err := common.ValidateKey(key); err != nil {
+		return nil, nil, err
+	}
+
+	blob := a.container.NewBlockBlob(key)
+	metadata, err := a.GetMetadata(ctx, key)
+	if err != nil && !errors.Is(err, common.ErrNotFound) {
+		return nil, nil, err
+	}
+	if err != nil || metadata.LastModified.After(at) {
+		snapshot, err := a.snapshotAsOf(ctx, key, at)
+		if err != nil {
+			return nil, nil, err
+		}
+		sb, ok := blob.(snapshotBlob)
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: blob snapshots are not addressable", common.ErrInternal)
+		}
+		blob = sb.Snapshot(snapshot)
+		props, err := blob.GetProperties(ctx)
+		if err != nil {
+			return nil, nil, translateError(err, key)
+		}
+		metadata = propertiesMetadata(props)
+	}
+
+	ctx, cancel := a.timeouts.Context(ctx, transport.OpGet)
+	rc, err := blob.NewReader(ctx)
+	if err != nil {
+		cancel()
+		return nil, nil, translateError(err, key)
+	}
+	return transport.CancelOnClose(rc, cancel), metadata, nil
+}
+
+// snapshotAsOf returns the newest snapshot of key whose content was
+// written at or before at.
+func (a *Azure) snapshotAsOf(ctx context.Context, key string, at time.Time) (string, error) {
+	notFound := fmt.Errorf("%w: %s as of %s", common.ErrKeyNotFound, key, at.Format(time.RFC3339))
+	lister, ok := a.container.(snapshotContainer)
+	if !ok {
+		return "", notFound
+	}
+
+	ctx, cancel := a.timeouts.Context(ctx, transport.OpList)
+	defer cancel()
+	snapshots, err := lister.ListSnapshots(ctx, key)
+	if err != nil {
+		return "", translateError(err, key)
+	}
+
+	var best *blobSnapshot
+	for i := range snapshots {
+		s := &snapshots[i]
+		if s.LastModified.After(at) {
+			continue
+		}
+		// Snapshot IDs are timestamps, so on equal content the later
+		// snapshot is preferred.
+		if best == nil || s.LastModified.After(best.LastModified) ||
+			(s.LastModified.Equal(best.LastModified) && s.Snapshot > best.Snapshot) {
+			best = s
+		}
+	}
+	if best == nil {
+		return "", notFound
+	}
+	return best.Snapshot, nil
+}
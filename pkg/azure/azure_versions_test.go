@@ -0,0 +1,107 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build azureblob
+
+package azure
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// snapshotMockContainer lists fixed snapshots of one blob.
+type snapshotMockContainer struct {
+	mockContainerEnhanced
+	snapshots []blobSnapshot
+}
+
+func (c *snapshotMockContainer) ListSnapshots(context.Context, string) ([]blobSnapshot, error) {
+	return c.snapshots, nil
+}
+
+// snapshotMockBlob serves the current content or that of a snapshot.
+type snapshotMockBlob struct {
+	mockBlob
+	snapshots map[string]string
+}
+
+func (b *snapshotMockBlob) Snapshot(snapshot string) BlobAPI {
+	data := b.snapshots[snapshot]
+	return &mockBlob{
+		readFn: func(context.Context) (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader(data)), nil
+		},
+		getPropertiesFn: func(context.Context) (*BlobProperties, error) {
+			return &BlobProperties{Size: int64(len(data))}, nil
+		},
+	}
+}
+
+func TestAzure_GetAsOf(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2025, 6, d, 0, 0, 0, 0, time.UTC)
+	}
+	blob := &snapshotMockBlob{
+		mockBlob: mockBlob{
+			readFn: func(context.Context) (io.ReadCloser, error) {
+				return io.NopCloser(strings.NewReader("current")), nil
+			},
+			getPropertiesFn: func(context.Context) (*BlobProperties, error) {
+				return &BlobProperties{Size: 7, LastModified: day(5)}, nil
+			},
+		},
+		snapshots: map[string]string{"s1": "first", "s2": "second"},
+	}
+	container := &snapshotMockContainer{
+		mockContainerEnhanced: mockContainerEnhanced{
+			newBlockBlobFn: func(string) BlobAPI { return blob },
+		},
+		snapshots: []blobSnapshot{
+			{Snapshot: "s1", LastModified: day(1)},
+			{Snapshot: "s2", LastModified: day(3)},
+		},
+	}
+	a := &Azure{container: container}
+	ctx := context.Background()
+
+	tests := []struct {
+		at   time.Time
+		want string
+	}{
+		{day(2), "first"},
+		{day(4), "second"},
+		{day(5), "current"},
+	}
+	for _, tt := range tests {
+		reader, metadata, err := a.GetAsOf(ctx, "config.json", tt.at)
+		if err != nil {
+			t.Fatalf("GetAsOf(%v) error = %v", tt.at, err)
+		}
+		data, _ := io.ReadAll(reader)
+		_ = reader.Close()
+		if string(data) != tt.want || metadata.Size != int64(len(tt.want)) {
+			t.Errorf("GetAsOf(%v) = %q, %+v, want %q", tt.at, data, metadata, tt.want)
+		}
+	}
+
+	if _, _, err := a.GetAsOf(ctx, "config.json", day(0)); !errors.Is(err, common.ErrKeyNotFound) {
+		t.Errorf("GetAsOf() before the first snapshot error = %v, want ErrKeyNotFound", err)
+	}
+}
@@ -28,7 +28,9 @@ import (
 func TestAzure_Wrappers_Coverage(t *testing.T) {
 	// Stub wrapper functions to avoid network
 	oldUp, oldDn, oldDel := azureUploadFn, azureDownloadFn, azureDeleteFn
-	azureUploadFn = func(_ context.Context, _ io.Reader, _ azblob.BlockBlobURL) error { return nil }
+	azureUploadFn = func(_ context.Context, _ io.Reader, _ azblob.BlockBlobURL) (*uploadResult, error) {
+		return &uploadResult{ETag: `"0x8D"`, VersionID: "2025-06-01T12:00:00.0000000Z"}, nil
+	}
 	azureDownloadFn = func(_ context.Context, _ azblob.BlockBlobURL) (io.ReadCloser, error) {
 		return io.NopCloser(bytes.NewBufferString("ok")), nil
 	}
@@ -43,6 +45,9 @@ func TestAzure_Wrappers_Coverage(t *testing.T) {
 	if err := bw.UploadFromReader(nil, bytes.NewBufferString("d")); err != nil {
 		t.Fatalf("upload stubbed err: %v", err)
 	}
+	if result, err := bw.UploadWithResult(nil, bytes.NewBufferString("d")); err != nil || result.VersionID == "" {
+		t.Fatalf("upload with result stubbed: %+v, %v", result, err)
+	}
 	rc, err := bw.NewReader(nil)
 	if err != nil {
 		t.Fatalf("download stubbed err: %v", err)
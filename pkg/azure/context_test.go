@@ -0,0 +1,128 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build azureblob
+
+package azure
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// promptly bounds how long an aborted call may take to return.
+const promptly = 5 * time.Second
+
+// newStalledAzure returns an Azure backend pointed at a server that never
+// finishes a response. GET requests receive headers and a partial body first.
+func newStalledAzure(t *testing.T, extra map[string]string) *Azure {
+	t.Helper()
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.Header().Set("Content-Length", "1048576")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte("partial"))
+			w.(http.Flusher).Flush()
+		}
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	t.Cleanup(func() {
+		close(release)
+		srv.Close()
+	})
+
+	settings := map[string]string{
+		"accountName":     "devstoreaccount1",
+		"accountKey":      "a2V5",
+		"containerName":   "c",
+		"endpoint":        srv.URL,
+		"httpShareClient": "false",
+	}
+	for k, v := range extra {
+		settings[k] = v
+	}
+	a := &Azure{}
+	if err := a.Configure(settings); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+	return a
+}
+
+func TestAzure_PutWithContext_CancelAbortsUpload(t *testing.T) {
+	a := newStalledAzure(t, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	err := a.PutWithContext(ctx, "k", bytes.NewReader(make([]byte, 1<<20)))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("PutWithContext() error = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > promptly {
+		t.Fatalf("PutWithContext() returned after %v", elapsed)
+	}
+}
+
+func TestAzure_GetWithContext_CancelAbortsDownload(t *testing.T) {
+	a := newStalledAzure(t, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	rc, err := a.GetWithContext(ctx, "k")
+	if err != nil {
+		t.Fatalf("GetWithContext() error = %v", err)
+	}
+	defer func() { _ = rc.Close() }()
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	if _, err := io.ReadAll(rc); err == nil {
+		t.Fatal("reading the body of a canceled download succeeded")
+	}
+	if elapsed := time.Since(start); elapsed > promptly {
+		t.Fatalf("body read returned after %v", elapsed)
+	}
+}
+
+func TestAzure_PutTimeout_Setting(t *testing.T) {
+	a := newStalledAzure(t, map[string]string{"putTimeout": "100ms"})
+
+	start := time.Now()
+	err := a.Put("k", bytes.NewReader([]byte("data")))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Put() error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > promptly {
+		t.Fatalf("Put() returned after %v", elapsed)
+	}
+}
+
+func TestAzure_Configure_InvalidTimeout(t *testing.T) {
+	a := &Azure{}
+	err := a.Configure(map[string]string{"getTimeout": "later"})
+	if !errors.Is(err, common.ErrInvalidArgument) {
+		t.Fatalf("Configure() error = %v, want ErrInvalidArgument", err)
+	}
+}
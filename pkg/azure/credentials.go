@@ -0,0 +1,192 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build azureblob
+
+package azure
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/credfile"
+
+	"github.com/Azure/azure-pipeline-go/pipeline"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-storage-blob-go/azblob"
+)
+
+// Credential providers selected with the "credentials" setting.
+const (
+	// CredentialsSharedKey signs requests with the accountKey setting.
+	CredentialsSharedKey = "shared-key"
+
+	// CredentialsManagedIdentity uses the managed identity of the Azure
+	// host, or the user-assigned identity named by clientId.
+	CredentialsManagedIdentity = "managed-identity"
+
+	// CredentialsWorkloadIdentity uses AKS Workload Identity, configured
+	// by the AZURE_CLIENT_ID, AZURE_TENANT_ID and AZURE_FEDERATED_TOKEN_FILE
+	// environment variables.
+	CredentialsWorkloadIdentity = "workload-identity"
+
+	// CredentialsDefault uses the azidentity default chain: environment,
+	// workload identity, managed identity, then the Azure CLI.
+	CredentialsDefault = "default"
+
+	// CredentialsFile signs requests with the accountKey read from
+	// credentialsFile and re-reads it when it changes.
+	CredentialsFile = "file"
+)
+
+// storageScope is the OAuth scope of Azure Storage tokens.
+const storageScope = "https://storage.azure.com/.default"
+
+// newAuthFactory returns the pipeline factory that authenticates requests
+// with the credentials selected by settings.
+func newAuthFactory(settings map[string]string) (pipeline.Factory, error) {
+	accountName := settings["accountName"]
+	switch provider := settings["credentials"]; provider {
+	case "", CredentialsSharedKey:
+		if settings["accountKey"] == "" {
+			return nil, common.ErrAccountNotSet
+		}
+		return azblob.NewSharedKeyCredential(accountName, settings["accountKey"])
+	case CredentialsFile:
+		file, err := credfile.Open(settings["credentialsFile"])
+		if err != nil {
+			return nil, err
+		}
+		f := &sharedKeyFileFactory{file: file, accountName: accountName}
+		if _, err := f.credential(); err != nil {
+			return nil, err
+		}
+		return f, nil
+	case CredentialsManagedIdentity:
+		opts := &azidentity.ManagedIdentityCredentialOptions{}
+		if id := settings["clientId"]; id != "" {
+			opts.ID = azidentity.ClientID(id)
+		}
+		cred, err := azidentity.NewManagedIdentityCredential(opts)
+		if err != nil {
+			return nil, err
+		}
+		return &tokenFactory{cred: cred}, nil
+	case CredentialsWorkloadIdentity:
+		cred, err := azidentity.NewWorkloadIdentityCredential(&azidentity.WorkloadIdentityCredentialOptions{ClientID: settings["clientId"]})
+		if err != nil {
+			return nil, err
+		}
+		return &tokenFactory{cred: cred}, nil
+	case CredentialsDefault:
+		cred, err := azidentity.NewDefaultAzureCredential(nil)
+		if err != nil {
+			return nil, err
+		}
+		return &tokenFactory{cred: cred}, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown credentials provider %q", common.ErrInvalidArgument, provider)
+	}
+}
+
+// newAuthPipeline mirrors azblob.NewPipeline with auth in place of the
+// credential, which azblob only accepts from its own constructors.
+func newAuthPipeline(auth pipeline.Factory, o azblob.PipelineOptions) pipeline.Pipeline {
+	if credential, ok := auth.(azblob.Credential); ok {
+		return azblob.NewPipeline(credential, o)
+	}
+	f := []pipeline.Factory{
+		azblob.NewTelemetryPolicyFactory(o.Telemetry),
+		azblob.NewUniqueRequestIDPolicyFactory(),
+		azblob.NewRetryPolicyFactory(o.Retry),
+		auth,
+		azblob.NewRequestLogPolicyFactory(o.RequestLog),
+		pipeline.MethodFactoryMarker(),
+	}
+	return pipeline.NewPipeline(f, pipeline.Options{HTTPSender: o.HTTPSender, Log: o.Log})
+}
+
+// tokenFactory authorizes requests with Microsoft Entra ID tokens. The
+// azidentity credentials cache tokens and renew them before they expire.
+type tokenFactory struct {
+	cred azcore.TokenCredential
+}
+
+// New returns the policy that adds a bearer token to each request.
+func (f *tokenFactory) New(next pipeline.Policy, _ *pipeline.PolicyOptions) pipeline.Policy {
+	return pipeline.PolicyFunc(func(ctx context.Context, request pipeline.Request) (pipeline.Response, error) {
+		if request.URL.Scheme != "https" {
+			return nil, fmt.Errorf("%w: token credentials require an https endpoint", common.ErrInvalidArgument)
+		}
+		token, err := f.cred.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{storageScope}})
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", common.ErrUnauthenticated, err)
+		}
+		request.Header.Set("Authorization", "Bearer "+token.Token)
+		return next.Do(ctx, request)
+	})
+}
+
+// sharedKeyFileFactory signs requests with the account key in a
+// credentials file, switching to the new key whenever the file changes.
+type sharedKeyFileFactory struct {
+	file        *credfile.File
+	accountName string
+
+	mu      sync.Mutex
+	version uint64
+	cred    *azblob.SharedKeyCredential
+}
+
+// New returns the policy that signs each request with the current key.
+func (f *sharedKeyFileFactory) New(next pipeline.Policy, po *pipeline.PolicyOptions) pipeline.Policy {
+	return pipeline.PolicyFunc(func(ctx context.Context, request pipeline.Request) (pipeline.Response, error) {
+		cred, err := f.credential()
+		if err != nil {
+			return nil, err
+		}
+		return cred.New(next, po).Do(ctx, request)
+	})
+}
+
+// credential returns the shared key credential of the current key,
+// rebuilding it when the file changed. A key that fails to load keeps the
+// previous credential.
+func (f *sharedKeyFileFactory) credential() (*azblob.SharedKeyCredential, error) {
+	_ = f.file.Refresh() // #nosec G104 -- A failed reload keeps the current key
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	version := f.file.Version()
+	if f.cred != nil && version == f.version {
+		return f.cred, nil
+	}
+	var cred *azblob.SharedKeyCredential
+	err := common.ErrAccountNotSet
+	if key := f.file.Lookup("accountKey", "AZURE_STORAGE_KEY"); key != "" {
+		cred, err = azblob.NewSharedKeyCredential(f.accountName, key)
+	}
+	if err != nil {
+		if f.cred != nil {
+			return f.cred, nil
+		}
+		return nil, fmt.Errorf("%w: %s: %v", credfile.ErrInvalidFile, f.file.Path(), err)
+	}
+	f.cred, f.version = cred, version
+	return f.cred, nil
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build azureblob
+
+package azure
+
+import (
+	"errors"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+
+	"github.com/Azure/azure-storage-blob-go/azblob"
+)
+
+// translateError maps an Azure storage error for key to the common error
+// taxonomy. Errors that are not storage errors are returned unchanged.
+func translateError(err error, key string) error {
+	var stgErr azblob.StorageError
+	if err == nil || !errors.As(err, &stgErr) {
+		return err
+	}
+	status := 0
+	if resp := stgErr.Response(); resp != nil {
+		status = resp.StatusCode
+	}
+	return common.ProviderError(sentinelFor(stgErr.ServiceCode(), status), key, err)
+}
+
+// sentinelFor returns the common sentinel for an Azure service code, falling
+// back to the HTTP status.
+func sentinelFor(code azblob.ServiceCodeType, status int) error {
+	switch code {
+	case azblob.ServiceCodeBlobNotFound, azblob.ServiceCodeContainerNotFound, azblob.ServiceCodeResourceNotFound:
+		return common.ErrNotFound
+	case azblob.ServiceCodeBlobAlreadyExists, azblob.ServiceCodeContainerAlreadyExists, azblob.ServiceCodeResourceAlreadyExists:
+		return common.ErrAlreadyExists
+	case azblob.ServiceCodeAuthenticationFailed, azblob.ServiceCodeInsufficientAccountPermissions,
+		azblob.ServiceCodeInvalidAuthenticationInfo, azblob.ServiceCodeNoAuthenticationInformation,
+		azblob.ServiceCodeAccountIsDisabled, "AuthorizationFailure", "AuthorizationPermissionMismatch":
+		return common.ErrAccessDenied
+	case azblob.ServiceCodeConditionNotMet, azblob.ServiceCodeAppendPositionConditionNotMet,
+		azblob.ServiceCodeMaxBlobSizeConditionNotMet, azblob.ServiceCodeSourceConditionNotMet,
+		azblob.ServiceCodeTargetConditionNotMet:
+		return common.ErrPreconditionFailed
+	case azblob.ServiceCodeServerBusy:
+		return common.ErrQuotaExceeded
+	case azblob.ServiceCodeInternalError, azblob.ServiceCodeOperationTimedOut:
+		return common.ErrBackendUnavailable
+	}
+	return common.ErrorForStatus(status)
+}
@@ -106,5 +106,5 @@ func (a *AzureArchive) Put(key string, data io.Reader) error {
 	if err != nil {
 		return err
 	}
-	return blob.UploadFromReader(context.Background(), bytes.NewReader(buf))
+	return translateError(blob.UploadFromReader(context.Background(), bytes.NewReader(buf)), key)
 }
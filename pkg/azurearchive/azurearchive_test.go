@@ -20,8 +20,13 @@ import (
 	"context"
 	"errors"
 	"io"
+	"net/http"
 	"strings"
 	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+
+	"github.com/Azure/azure-storage-blob-go/azblob"
 )
 
 // Mocks implementing the small interfaces for isolated unit tests.
@@ -282,3 +287,42 @@ func TestAzureArchive_Configure_BadEndpoint(t *testing.T) {
 		t.Fatal("Configure() expected error for bad endpoint, got nil")
 	}
 }
+
+// fakeStorageError implements azblob.StorageError for translation tests.
+type fakeStorageError struct {
+	code   azblob.ServiceCodeType
+	status int
+}
+
+func (f *fakeStorageError) Error() string   { return string(f.code) }
+func (f *fakeStorageError) Timeout() bool   { return false }
+func (f *fakeStorageError) Temporary() bool { return false }
+func (f *fakeStorageError) Response() *http.Response {
+	if f.status == 0 {
+		return nil
+	}
+	return &http.Response{StatusCode: f.status}
+}
+func (f *fakeStorageError) ServiceCode() azblob.ServiceCodeType { return f.code }
+
+func TestAzureArchive_Put_TranslatesError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want error
+	}{
+		{&fakeStorageError{code: azblob.ServiceCodeContainerNotFound}, common.ErrNotFound},
+		{&fakeStorageError{code: azblob.ServiceCodeAuthenticationFailed}, common.ErrAccessDenied},
+		{&fakeStorageError{code: azblob.ServiceCodeServerBusy}, common.ErrQuotaExceeded},
+		{&fakeStorageError{code: "SomethingNew", status: http.StatusBadGateway}, common.ErrBackendUnavailable},
+	}
+	for _, tt := range tests {
+		a := &AzureArchive{container: mockContainer{b: &mockBlob{uploadErr: tt.err}}}
+		err := a.Put("k", bytes.NewBufferString("data"))
+		if !errors.Is(err, tt.want) {
+			t.Errorf("Put() error = %v, want %v", err, tt.want)
+		}
+		if !errors.Is(err, tt.err) {
+			t.Errorf("Put() error = %v dropped the storage error from the chain", err)
+		}
+	}
+}
@@ -0,0 +1,394 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package backup exports objstore's control-plane state to a single archive
+// and restores it, typically on a new node. The state is the set of files
+// objstore keeps next to the data: lifecycle and replication policies, legal
+// holds and deletion requests (see StateFiles), plus any configuration files
+// named explicitly, such as overlays with quotas, residency rules, cost
+// groups or notification rules.
+//
+// An archive is a gzip-compressed tar file. Its first entry, manifest.json,
+// lists every file with its original path, size and SHA-256 digest; Open
+// rejects archives whose content does not match. Restore writes all files
+// or none of them, so it should run while the server is stopped.
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// Version is the archive format version written by Create.
+const Version = 1
+
+const (
+	manifestName = "manifest.json"
+
+	// statePrefix and configPrefix are the archive directories of state
+	// files and configuration files.
+	statePrefix  = "state/"
+	configPrefix = "config/"
+
+	// maxFileSize bounds a single file read from an archive. Control-plane
+	// state is small; anything larger is not an objstore backup.
+	maxFileSize = 256 << 20
+)
+
+// StateFiles are the control-plane files objstore keeps in its data
+// directory. Files that do not exist are skipped.
+var StateFiles = []string{
+	".lifecycle-policies.json",
+	".replication-policies.json",
+	".retention.json",
+}
+
+var (
+	// ErrInvalidArchive is returned when an archive is malformed, is
+	// missing a file listed in its manifest, or contains files its manifest
+	// does not list.
+	ErrInvalidArchive = fmt.Errorf("%w: invalid backup archive", common.ErrInvalidArgument)
+
+	// ErrChecksumMismatch is returned when a file in an archive does not
+	// match the digest recorded in the manifest.
+	ErrChecksumMismatch = fmt.Errorf("%w: backup file checksum mismatch", common.ErrInvalidArgument)
+
+	// ErrUnsupportedVersion is returned for archives written by a newer
+	// format version.
+	ErrUnsupportedVersion = fmt.Errorf("%w: unsupported backup version", common.ErrInvalidArgument)
+)
+
+// File describes one file in an archive.
+type File struct {
+	// Name is the file's name in the archive: state/<name> or
+	// config/<name>.
+	Name string `json:"name"`
+	// Path is where the file was read from, and where Restore writes it
+	// unless a target directory is given.
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Manifest is the table of contents of an archive.
+type Manifest struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	Files     []File    `json:"files"`
+}
+
+// Sources selects the files Create exports.
+type Sources struct {
+	// StateDir is the data directory holding the StateFiles. Empty skips
+	// them.
+	StateDir string
+	// ConfigFiles are additional configuration files. Their base names
+	// must be unique.
+	ConfigFiles []string
+}
+
+// Targets selects where Restore writes files. Empty fields restore files
+// to the paths they were exported from.
+type Targets struct {
+	// StateDir replaces the directory of state files.
+	StateDir string
+	// ConfigDir replaces the directory of configuration files.
+	ConfigDir string
+}
+
+// Create writes an archive of the files selected by src to w and returns
+// its manifest.
+func Create(w io.Writer, src Sources) (*Manifest, error) {
+	type entry struct {
+		file File
+		data []byte
+	}
+	var entries []entry
+	add := func(name, p string, required bool) error {
+		data, err := os.ReadFile(p)
+		if errors.Is(err, os.ErrNotExist) && !required {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", p, err)
+		}
+		abs, err := filepath.Abs(p)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(data)
+		entries = append(entries, entry{
+			file: File{Name: name, Path: abs, Size: int64(len(data)), SHA256: hex.EncodeToString(sum[:])},
+			data: data,
+		})
+		return nil
+	}
+
+	if src.StateDir != "" {
+		for _, name := range StateFiles {
+			if err := add(statePrefix+name, filepath.Join(src.StateDir, name), false); err != nil {
+				return nil, err
+			}
+		}
+	}
+	seen := make(map[string]bool)
+	for _, p := range src.ConfigFiles {
+		base := filepath.Base(p)
+		if seen[base] {
+			return nil, fmt.Errorf("%w: duplicate configuration file name %q", common.ErrInvalidArgument, base)
+		}
+		seen[base] = true
+		if err := add(configPrefix+base, p, true); err != nil {
+			return nil, err
+		}
+	}
+
+	manifest := &Manifest{Version: Version, CreatedAt: time.Now().UTC(), Files: []File{}}
+	for _, e := range entries {
+		manifest.Files = append(manifest.Files, e.file)
+	}
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	write := func(name string, data []byte) error {
+		hdr := &tar.Header{
+			Name:    name,
+			Mode:    0o600,
+			Size:    int64(len(data)),
+			ModTime: manifest.CreatedAt,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+	if err := write(manifestName, manifestData); err != nil {
+		return nil, fmt.Errorf("failed to write backup: %w", err)
+	}
+	for _, e := range entries {
+		if err := write(e.file.Name, e.data); err != nil {
+			return nil, fmt.Errorf("failed to write backup: %w", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write backup: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write backup: %w", err)
+	}
+	return manifest, nil
+}
+
+// Archive is a verified backup read by Open.
+type Archive struct {
+	Manifest Manifest
+	data     map[string][]byte
+}
+
+// Open reads the archive in r and verifies every file against its
+// manifest.
+func Open(r io.Reader) (*Archive, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+	defer func() { _ = gz.Close() }()
+	tr := tar.NewReader(gz)
+
+	var manifest *Manifest
+	data := make(map[string][]byte)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+		}
+		if hdr.Typeflag != tar.TypeReg || hdr.Size > maxFileSize {
+			return nil, fmt.Errorf("%w: unexpected entry %q", ErrInvalidArchive, hdr.Name)
+		}
+		content, err := io.ReadAll(io.LimitReader(tr, maxFileSize))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+		}
+		if manifest == nil {
+			if hdr.Name != manifestName {
+				return nil, fmt.Errorf("%w: missing %s", ErrInvalidArchive, manifestName)
+			}
+			manifest = &Manifest{}
+			if err := json.Unmarshal(content, manifest); err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+			}
+			if manifest.Version > Version {
+				return nil, fmt.Errorf("%w: %d", ErrUnsupportedVersion, manifest.Version)
+			}
+			continue
+		}
+		if _, dup := data[hdr.Name]; dup {
+			return nil, fmt.Errorf("%w: duplicate entry %q", ErrInvalidArchive, hdr.Name)
+		}
+		data[hdr.Name] = content
+	}
+	if manifest == nil {
+		return nil, fmt.Errorf("%w: missing %s", ErrInvalidArchive, manifestName)
+	}
+
+	for _, f := range manifest.Files {
+		if !validName(f.Name) {
+			return nil, fmt.Errorf("%w: invalid file name %q", ErrInvalidArchive, f.Name)
+		}
+		content, ok := data[f.Name]
+		if !ok {
+			return nil, fmt.Errorf("%w: missing %s", ErrInvalidArchive, f.Name)
+		}
+		sum := sha256.Sum256(content)
+		if int64(len(content)) != f.Size || hex.EncodeToString(sum[:]) != f.SHA256 {
+			return nil, fmt.Errorf("%w: %s", ErrChecksumMismatch, f.Name)
+		}
+	}
+	if len(data) != len(manifest.Files) {
+		return nil, fmt.Errorf("%w: files not listed in the manifest", ErrInvalidArchive)
+	}
+	return &Archive{Manifest: *manifest, data: data}, nil
+}
+
+// validName reports whether name is a single file under the state or
+// config directory of an archive.
+func validName(name string) bool {
+	for _, prefix := range []string{statePrefix, configPrefix} {
+		if base, ok := strings.CutPrefix(name, prefix); ok {
+			return base != "" && base != "." && base != ".." && path.Base(base) == base && !strings.Contains(base, `\`)
+		}
+	}
+	return false
+}
+
+// Restore writes every file of the archive to its original path, or into
+// the directories given by t, and returns the files with the paths they
+// were written to. Files are staged next to their destinations first and
+// then moved into place; if any step fails, files already replaced are put
+// back, so either every file is restored or none is.
+func (a *Archive) Restore(t Targets) ([]File, error) {
+	restored := make([]File, len(a.Manifest.Files))
+	for i, f := range a.Manifest.Files {
+		dest := f.Path
+		base := strings.TrimPrefix(strings.TrimPrefix(f.Name, statePrefix), configPrefix)
+		switch {
+		case strings.HasPrefix(f.Name, statePrefix) && t.StateDir != "":
+			dest = filepath.Join(t.StateDir, base)
+		case strings.HasPrefix(f.Name, configPrefix) && t.ConfigDir != "":
+			dest = filepath.Join(t.ConfigDir, base)
+		case dest == "" || !filepath.IsAbs(dest):
+			return nil, fmt.Errorf("%w: no restore path for %s", common.ErrInvalidArgument, f.Name)
+		}
+		f.Path = dest
+		restored[i] = f
+	}
+
+	// Stage every file before touching any destination.
+	staged := make([]string, len(restored))
+	cleanup := func() {
+		for _, tmp := range staged {
+			if tmp != "" {
+				_ = os.Remove(tmp)
+			}
+		}
+	}
+	for i, f := range restored {
+		tmp, err := stage(f.Path, a.data[f.Name])
+		if err != nil {
+			cleanup()
+			return nil, fmt.Errorf("failed to restore %s: %w", f.Path, err)
+		}
+		staged[i] = tmp
+	}
+
+	// Keep the current content so a failed move can be undone.
+	type previous struct {
+		data   []byte
+		exists bool
+	}
+	prev := make([]previous, len(restored))
+	for i, f := range restored {
+		data, err := os.ReadFile(f.Path)
+		switch {
+		case err == nil:
+			prev[i] = previous{data: data, exists: true}
+		case !errors.Is(err, os.ErrNotExist):
+			cleanup()
+			return nil, fmt.Errorf("failed to restore %s: %w", f.Path, err)
+		}
+	}
+
+	for i, f := range restored {
+		if err := os.Rename(staged[i], f.Path); err != nil {
+			cleanup()
+			for j := 0; j < i; j++ {
+				if prev[j].exists {
+					_ = os.WriteFile(restored[j].Path, prev[j].data, 0o600)
+				} else {
+					_ = os.Remove(restored[j].Path)
+				}
+			}
+			return nil, fmt.Errorf("failed to restore %s: %w", f.Path, err)
+		}
+		staged[i] = ""
+	}
+	return restored, nil
+}
+
+// stage writes data to a temporary file in the directory of dest.
+func stage(dest string, data []byte) (string, error) {
+	dir := filepath.Dir(dest)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return "", err
+	}
+	tmp, err := os.CreateTemp(dir, ".restore-*")
+	if err != nil {
+		return "", err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return "", err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return "", err
+	}
+	return tmp.Name(), nil
+}
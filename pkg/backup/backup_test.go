@@ -0,0 +1,180 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// newBackup returns an archive of a data directory with lifecycle and
+// replication policies and one configuration file.
+func newBackup(t *testing.T) []byte {
+	t.Helper()
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, ".lifecycle-policies.json"), `{"lifecycle":1}`)
+	writeFile(t, filepath.Join(dir, ".replication-policies.json"), `{"replication":1}`)
+	overlays := filepath.Join(t.TempDir(), "overlays.yaml")
+	writeFile(t, overlays, "overlays: []\n")
+
+	var buf bytes.Buffer
+	m, err := Create(&buf, Sources{StateDir: dir, ConfigFiles: []string{overlays}})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if m.Version != Version || len(m.Files) != 3 {
+		t.Fatalf("unexpected manifest: %+v", m)
+	}
+	return buf.Bytes()
+}
+
+func TestCreateAndRestore(t *testing.T) {
+	archive, err := Open(bytes.NewReader(newBackup(t)))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	stateDir := filepath.Join(t.TempDir(), "data")
+	configDir := t.TempDir()
+	writeFile(t, filepath.Join(configDir, "overlays.yaml"), "old\n")
+	files, err := archive.Restore(Targets{StateDir: stateDir, ConfigDir: configDir})
+	if err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if len(files) != 3 {
+		t.Fatalf("restored %d files, want 3", len(files))
+	}
+	if got := readFile(t, filepath.Join(stateDir, ".lifecycle-policies.json")); got != `{"lifecycle":1}` {
+		t.Errorf("lifecycle policies = %q", got)
+	}
+	if got := readFile(t, filepath.Join(stateDir, ".replication-policies.json")); got != `{"replication":1}` {
+		t.Errorf("replication policies = %q", got)
+	}
+	if got := readFile(t, filepath.Join(configDir, "overlays.yaml")); got != "overlays: []\n" {
+		t.Errorf("overlays = %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(stateDir, ".retention.json")); !os.IsNotExist(err) {
+		t.Errorf("missing state file was restored: %v", err)
+	}
+}
+
+func TestRestoreIsAllOrNothing(t *testing.T) {
+	archive, err := Open(bytes.NewReader(newBackup(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stateDir := t.TempDir()
+	writeFile(t, filepath.Join(stateDir, ".lifecycle-policies.json"), "current")
+	// A directory in place of a state file makes its move fail.
+	if err := os.Mkdir(filepath.Join(stateDir, ".replication-policies.json"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(stateDir, ".replication-policies.json", "x"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := archive.Restore(Targets{StateDir: stateDir, ConfigDir: t.TempDir()}); err == nil {
+		t.Fatal("expected restore to fail")
+	}
+	if got := readFile(t, filepath.Join(stateDir, ".lifecycle-policies.json")); got != "current" {
+		t.Errorf("lifecycle policies were not rolled back: %q", got)
+	}
+	entries, err := os.ReadDir(stateDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("staged files left behind: %v", entries)
+	}
+}
+
+func TestOpenRejectsTampering(t *testing.T) {
+	data := newBackup(t)
+
+	// Rewrite the archive with one file's content changed.
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+	var out bytes.Buffer
+	gw := gzip.NewWriter(&out)
+	tw := tar.NewWriter(gw)
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			break
+		}
+		content := new(bytes.Buffer)
+		if _, err := content.ReadFrom(tr); err != nil {
+			t.Fatal(err)
+		}
+		if hdr.Name == "state/.lifecycle-policies.json" {
+			content = bytes.NewBufferString(`{"lifecycle":2}`)
+			hdr.Size = int64(content.Len())
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(content.Bytes()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_ = tw.Close()
+	_ = gw.Close()
+
+	if _, err := Open(&out); !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("expected ErrChecksumMismatch, got %v", err)
+	}
+	if _, err := Open(bytes.NewReader([]byte("not a backup"))); !errors.Is(err, common.ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArchive, got %v", err)
+	}
+}
+
+func TestCreateRejectsDuplicateConfigNames(t *testing.T) {
+	a := filepath.Join(t.TempDir(), "rules.yaml")
+	b := filepath.Join(t.TempDir(), "rules.yaml")
+	writeFile(t, a, "a")
+	writeFile(t, b, "b")
+	if _, err := Create(&bytes.Buffer{}, Sources{ConfigFiles: []string{a, b}}); !errors.Is(err, common.ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument, got %v", err)
+	}
+	if _, err := Create(&bytes.Buffer{}, Sources{ConfigFiles: []string{filepath.Join(t.TempDir(), "missing.yaml")}}); err == nil {
+		t.Fatal("expected an error for a missing configuration file")
+	}
+}
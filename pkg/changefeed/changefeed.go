@@ -0,0 +1,345 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package changefeed keeps an ordered, replayable journal of the changes
+// made to a backend, so consumers can process objects incrementally instead
+// of diffing full listings.
+//
+// Every change is assigned the next sequence number. A consumer remembers
+// the last sequence it processed and asks for the changes since then; it
+// may stay offline for as long as the journal retains those changes. Once
+// they have been trimmed GetChanges returns ErrSequenceExpired and the
+// consumer must resynchronize from a full listing.
+//
+// The journal can be persisted to a JSON Lines file so it survives restarts.
+package changefeed
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// Change operations.
+const (
+	// OpPut records an object written by a put, append or compose.
+	OpPut = "put"
+
+	// OpUpdateMetadata records a metadata update.
+	OpUpdateMetadata = "update_metadata"
+
+	// OpDelete records a deleted object.
+	OpDelete = "delete"
+)
+
+const (
+	// DefaultMaxEntries is the number of changes retained when no limit is
+	// configured.
+	DefaultMaxEntries = 100000
+
+	// DefaultLimit is the number of changes returned when no limit is given.
+	DefaultLimit = 1000
+
+	// MaxLimit is the largest number of changes returned by one call.
+	MaxLimit = 10000
+)
+
+var (
+	// ErrSequenceExpired is returned when the changes following a sequence
+	// number are no longer retained.
+	ErrSequenceExpired = fmt.Errorf("%w: changes since sequence are no longer retained", common.ErrPreconditionFailed)
+
+	// ErrJournalCorrupt is returned when a persisted journal cannot be
+	// decoded.
+	ErrJournalCorrupt = errors.New("change journal is corrupt")
+
+	// ErrClosed is returned when recording to a closed journal.
+	ErrClosed = errors.New("change journal is closed")
+)
+
+// Change is one entry of the journal.
+type Change struct {
+	Sequence  uint64    `json:"sequence"`
+	Op        string    `json:"op"`
+	Key       string    `json:"key"`
+	ETag      string    `json:"etag,omitempty"`
+	Size      int64     `json:"size,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Page is the result of GetChanges.
+type Page struct {
+	// Changes are the changes after the requested sequence, oldest first.
+	Changes []Change `json:"changes"`
+
+	// Next is the sequence to request the following page with: the
+	// sequence of the last change returned, or the requested sequence when
+	// there were none.
+	Next uint64 `json:"next"`
+
+	// Latest is the sequence of the most recent change.
+	Latest uint64 `json:"latest"`
+
+	// More reports whether changes after Next are already available.
+	More bool `json:"more"`
+}
+
+// Journal is an append-only log of changes. It is safe for concurrent use.
+type Journal struct {
+	mu         sync.Mutex
+	path       string
+	file       *os.File
+	maxEntries int
+	closed     bool
+
+	// changes are the retained changes, oldest first; sequences are
+	// consecutive.
+	changes []Change
+
+	// latest is the sequence of the most recent change, retained or not.
+	latest uint64
+
+	// lines is the number of changes in the file, which is compacted when
+	// it grows to twice maxEntries.
+	lines int
+
+	now func() time.Time
+}
+
+// NewJournal creates a Journal retaining up to maxEntries changes; a
+// non-positive maxEntries uses DefaultMaxEntries. When path is non-empty the
+// journal is loaded from that file if it exists and every change is
+// appended to it.
+func NewJournal(path string, maxEntries int) (*Journal, error) {
+	if maxEntries <= 0 {
+		maxEntries = DefaultMaxEntries
+	}
+	j := &Journal{path: path, maxEntries: maxEntries, now: time.Now}
+	if path == "" {
+		return j, nil
+	}
+
+	if err := j.load(); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600) // #nosec G304 -- journal path is operator configuration
+	if err != nil {
+		return nil, fmt.Errorf("failed to open change journal: %w", err)
+	}
+	j.file = file
+	return j, nil
+}
+
+// load reads the persisted journal. A torn final line, left by a crash
+// while appending, is dropped; any other undecodable line is corruption.
+func (j *Journal) load() error {
+	data, err := os.ReadFile(j.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read change journal: %w", err)
+	}
+
+	lines := bytes.Split(data, []byte{'\n'})
+	torn := len(lines[len(lines)-1]) > 0
+	lines = lines[:len(lines)-1]
+
+	changes := make([]Change, 0, len(lines))
+	for _, line := range lines {
+		var change Change
+		if err := json.Unmarshal(line, &change); err != nil {
+			return fmt.Errorf("%w: %v", ErrJournalCorrupt, err)
+		}
+		if n := len(changes); n > 0 && change.Sequence != changes[n-1].Sequence+1 {
+			return fmt.Errorf("%w: sequence %d follows %d", ErrJournalCorrupt, change.Sequence, changes[n-1].Sequence)
+		}
+		changes = append(changes, change)
+	}
+
+	j.lines = len(changes)
+	if n := len(changes); n > 0 {
+		j.latest = changes[n-1].Sequence
+	}
+	if len(changes) > j.maxEntries {
+		changes = changes[len(changes)-j.maxEntries:]
+	}
+	j.changes = changes
+	// Rewrite the file when a torn line or trimmed changes would
+	// otherwise stay in it.
+	if torn || j.lines != len(changes) {
+		return j.compactLocked()
+	}
+	return nil
+}
+
+// Record appends a change for key, assigning it the next sequence number
+// and the current time, and returns the recorded change.
+func (j *Journal) Record(op, key, etag string, size int64) (Change, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.closed {
+		return Change{}, ErrClosed
+	}
+
+	change := Change{
+		Sequence:  j.latest + 1,
+		Op:        op,
+		Key:       key,
+		ETag:      etag,
+		Size:      size,
+		Timestamp: j.now().UTC(),
+	}
+	if j.file != nil {
+		line, err := json.Marshal(change)
+		if err != nil {
+			return Change{}, fmt.Errorf("failed to encode change: %w", err)
+		}
+		if _, err := j.file.Write(append(line, '\n')); err != nil {
+			return Change{}, fmt.Errorf("failed to write change journal: %w", err)
+		}
+		j.lines++
+	}
+
+	j.latest = change.Sequence
+	j.changes = append(j.changes, change)
+	if len(j.changes) > j.maxEntries {
+		// Copy rather than reslice so trimmed changes can be collected.
+		j.changes = append([]Change(nil), j.changes[len(j.changes)-j.maxEntries:]...)
+	}
+	if j.file != nil && j.lines >= 2*j.maxEntries {
+		if err := j.compactLocked(); err != nil {
+			return Change{}, err
+		}
+	}
+	return change, nil
+}
+
+// GetChanges returns up to limit changes with a sequence greater than
+// since, oldest first. A non-positive limit uses DefaultLimit, and limits
+// above MaxLimit are capped. ErrSequenceExpired is returned when changes
+// after since have already been trimmed from the journal.
+func (j *Journal) GetChanges(since uint64, limit int) (*Page, error) {
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	oldest := j.latest + 1
+	if len(j.changes) > 0 {
+		oldest = j.changes[0].Sequence
+	}
+	if since+1 < oldest {
+		return nil, fmt.Errorf("%w: %d (oldest retained is %d)", ErrSequenceExpired, since, oldest)
+	}
+
+	start := sort.Search(len(j.changes), func(i int) bool { return j.changes[i].Sequence > since })
+	end := start + limit
+	if end > len(j.changes) {
+		end = len(j.changes)
+	}
+	page := &Page{
+		Changes: append([]Change{}, j.changes[start:end]...),
+		Next:    since,
+		Latest:  j.latest,
+		More:    end < len(j.changes),
+	}
+	if n := len(page.Changes); n > 0 {
+		page.Next = page.Changes[n-1].Sequence
+	}
+	return page, nil
+}
+
+// Latest returns the sequence of the most recent change, or 0 when nothing
+// has been recorded.
+func (j *Journal) Latest() uint64 {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.latest
+}
+
+// Close closes the journal file. Recording to a closed journal fails;
+// reading still works.
+func (j *Journal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.closed {
+		return nil
+	}
+	j.closed = true
+	if j.file == nil {
+		return nil
+	}
+	return j.file.Close()
+}
+
+// compactLocked rewrites the journal file with only the retained changes.
+// The most recent change is always retained, so the sequence continues
+// after a restart.
+func (j *Journal) compactLocked() error {
+	var buf bytes.Buffer
+	for _, change := range j.changes {
+		line, err := json.Marshal(change)
+		if err != nil {
+			return fmt.Errorf("failed to encode change: %w", err)
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(j.path), ".changes-*")
+	if err != nil {
+		return fmt.Errorf("failed to compact change journal: %w", err)
+	}
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to compact change journal: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to compact change journal: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), j.path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to compact change journal: %w", err)
+	}
+	j.lines = len(j.changes)
+
+	if j.file == nil {
+		return nil
+	}
+	_ = j.file.Close()
+	file, err := os.OpenFile(j.path, os.O_WRONLY|os.O_APPEND, 0o600) // #nosec G304 -- journal path is operator configuration
+	if err != nil {
+		// Without a file, recorded changes would silently stop being
+		// persisted.
+		j.file, j.closed = nil, true
+		return fmt.Errorf("failed to reopen change journal: %w", err)
+	}
+	j.file = file
+	return nil
+}
@@ -0,0 +1,202 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package changefeed
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+func TestStorageRecordsChanges(t *testing.T) {
+	journal, err := NewJournal("", 0)
+	if err != nil {
+		t.Fatalf("NewJournal: %v", err)
+	}
+	storage := NewStorage(memory.New(), journal)
+	ctx := context.Background()
+
+	if err := storage.PutWithContext(ctx, "a.txt", strings.NewReader("hello")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if err := storage.UpdateMetadata(ctx, "a.txt", &common.Metadata{ContentType: "text/plain"}); err != nil {
+		t.Fatalf("UpdateMetadata: %v", err)
+	}
+	if err := storage.Append(ctx, "a.txt", strings.NewReader(" world")); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	if err := storage.Compose(ctx, "b.txt", "a.txt", "a.txt"); err != nil {
+		t.Fatalf("Compose: %v", err)
+	}
+	if err := storage.DeleteWithContext(ctx, "a.txt"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := storage.DeleteWithContext(ctx, "missing"); err == nil {
+		t.Fatal("Delete of a missing key succeeded")
+	}
+
+	page, err := journal.GetChanges(0, 0)
+	if err != nil {
+		t.Fatalf("GetChanges: %v", err)
+	}
+	want := []struct {
+		op   string
+		key  string
+		size int64
+	}{
+		{OpPut, "a.txt", 5},
+		{OpUpdateMetadata, "a.txt", 5},
+		{OpPut, "a.txt", 11},
+		{OpPut, "b.txt", 22},
+		{OpDelete, "a.txt", 0},
+	}
+	if len(page.Changes) != len(want) {
+		t.Fatalf("got %d changes, want %d: %+v", len(page.Changes), len(want), page.Changes)
+	}
+	for i, w := range want {
+		c := page.Changes[i]
+		if c.Sequence != uint64(i+1) || c.Op != w.op || c.Key != w.key || c.Size != w.size {
+			t.Errorf("change %d = %+v, want %s %s size %d", i, c, w.op, w.key, w.size)
+		}
+		if c.Timestamp.IsZero() || (w.op != OpDelete && c.ETag == "") {
+			t.Errorf("change %d lacks timestamp or ETag: %+v", i, c)
+		}
+	}
+	if page.Next != 5 || page.Latest != 5 || page.More {
+		t.Errorf("page next %d latest %d more %v", page.Next, page.Latest, page.More)
+	}
+}
+
+func TestGetChangesPaging(t *testing.T) {
+	journal, err := NewJournal("", 3)
+	if err != nil {
+		t.Fatalf("NewJournal: %v", err)
+	}
+	page, err := journal.GetChanges(0, 10)
+	if err != nil || len(page.Changes) != 0 || page.Next != 0 || page.Latest != 0 {
+		t.Fatalf("empty journal = %+v, %v", page, err)
+	}
+
+	for _, key := range []string{"a", "b", "c", "d", "e"} {
+		if _, err := journal.Record(OpPut, key, "", 1); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+
+	// Only the last three changes are retained.
+	if _, err := journal.GetChanges(0, 10); !errors.Is(err, ErrSequenceExpired) {
+		t.Errorf("GetChanges(0) = %v, want ErrSequenceExpired", err)
+	}
+	if _, err := journal.GetChanges(1, 10); !errors.Is(err, ErrSequenceExpired) {
+		t.Errorf("GetChanges(1) = %v, want ErrSequenceExpired", err)
+	}
+
+	page, err = journal.GetChanges(2, 2)
+	if err != nil {
+		t.Fatalf("GetChanges(2): %v", err)
+	}
+	if len(page.Changes) != 2 || page.Changes[0].Key != "c" || page.Next != 4 || !page.More || page.Latest != 5 {
+		t.Errorf("first page = %+v", page)
+	}
+	page, err = journal.GetChanges(page.Next, 2)
+	if err != nil {
+		t.Fatalf("GetChanges(4): %v", err)
+	}
+	if len(page.Changes) != 1 || page.Changes[0].Key != "e" || page.Next != 5 || page.More {
+		t.Errorf("second page = %+v", page)
+	}
+	page, err = journal.GetChanges(5, 2)
+	if err != nil || len(page.Changes) != 0 || page.Next != 5 {
+		t.Errorf("caught-up page = %+v, %v", page, err)
+	}
+}
+
+func TestJournalPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "changes.jsonl")
+	journal, err := NewJournal(path, 4)
+	if err != nil {
+		t.Fatalf("NewJournal: %v", err)
+	}
+	for i := 0; i < 9; i++ {
+		if _, err := journal.Record(OpPut, "k", "e", int64(i)); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+	if err := journal.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if _, err := journal.Record(OpPut, "k", "", 0); !errors.Is(err, ErrClosed) {
+		t.Errorf("Record after Close = %v, want ErrClosed", err)
+	}
+
+	// Simulate a crash part way through appending a change.
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString(`{"sequence":10,"op":"pu`); err != nil {
+		t.Fatal(err)
+	}
+	_ = f.Close()
+
+	journal, err = NewJournal(path, 4)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer func() { _ = journal.Close() }()
+	if journal.Latest() != 9 {
+		t.Errorf("Latest = %d, want 9", journal.Latest())
+	}
+	change, err := journal.Record(OpDelete, "k", "", 0)
+	if err != nil || change.Sequence != 10 {
+		t.Fatalf("Record after reopen = %+v, %v", change, err)
+	}
+	page, err := journal.GetChanges(6, 0)
+	if err != nil {
+		t.Fatalf("GetChanges: %v", err)
+	}
+	if len(page.Changes) != 4 || page.Changes[0].Sequence != 7 || page.Changes[3].Op != OpDelete {
+		t.Errorf("changes after reopen = %+v", page.Changes)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines > 8 {
+		t.Errorf("journal file has %d lines, want it compacted", lines)
+	}
+}
+
+func TestJournalCorrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "changes.jsonl")
+	if err := os.WriteFile(path, []byte("{\"sequence\":1}\nnot json\n{\"sequence\":3}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewJournal(path, 0); !errors.Is(err, ErrJournalCorrupt) {
+		t.Errorf("NewJournal = %v, want ErrJournalCorrupt", err)
+	}
+	if err := os.WriteFile(path, []byte("{\"sequence\":1}\n{\"sequence\":3}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewJournal(path, 0); !errors.Is(err, ErrJournalCorrupt) {
+		t.Errorf("NewJournal with a gap = %v, want ErrJournalCorrupt", err)
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package changefeed
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// Storage wraps a backend and records every successful change made through
+// it in a Journal. Reads, listings and lifecycle operations pass through
+// unchanged.
+type Storage struct {
+	common.Storage
+	journal *Journal
+}
+
+// NewStorage returns underlying wrapped so that its changes are recorded
+// in journal.
+func NewStorage(underlying common.Storage, journal *Journal) *Storage {
+	return &Storage{Storage: underlying, journal: journal}
+}
+
+// Journal returns the journal s records changes in.
+func (s *Storage) Journal() *Journal {
+	return s.journal
+}
+
+// Underlying returns the wrapped backend.
+func (s *Storage) Underlying() common.Storage {
+	return s.Storage
+}
+
+// record journals op for key with the object's current size and ETag, if
+// they can be read.
+func (s *Storage) record(ctx context.Context, op, key string) error {
+	var size int64
+	var etag string
+	if metadata, err := s.Storage.GetMetadata(ctx, key); err == nil && metadata != nil {
+		size, etag = metadata.Size, metadata.ETag
+	}
+	_, err := s.journal.Record(op, key, etag, size)
+	return err
+}
+
+// GetRange reads a byte range of an object, falling back to discarding
+// the leading bytes of a full read when the wrapped backend cannot read
+// ranges.
+func (s *Storage) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	if rr, ok := s.Storage.(common.RangeReader); ok {
+		return rr.GetRange(ctx, key, offset, length)
+	}
+	rc, err := s.Storage.GetWithContext(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return common.SliceRange(rc, offset, length)
+}
+
+// GetAsOf reads an earlier version of an object from the wrapped backend.
+func (s *Storage) GetAsOf(ctx context.Context, key string, at time.Time) (io.ReadCloser, *common.Metadata, error) {
+	return common.GetAsOf(ctx, s.Storage, key, at)
+}
+
+// Put stores an object and records a put.
+func (s *Storage) Put(key string, data io.Reader) error {
+	return s.PutWithContext(context.Background(), key, data)
+}
+
+// PutWithContext stores an object and records a put.
+func (s *Storage) PutWithContext(ctx context.Context, key string, data io.Reader) error {
+	if err := s.Storage.PutWithContext(ctx, key, data); err != nil {
+		return err
+	}
+	return s.record(ctx, OpPut, key)
+}
+
+// PutWithMetadata stores an object with metadata and records a put.
+func (s *Storage) PutWithMetadata(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) error {
+	if err := s.Storage.PutWithMetadata(ctx, key, data, metadata); err != nil {
+		return err
+	}
+	return s.record(ctx, OpPut, key)
+}
+
+// PutWithInfo stores an object with metadata, records a put and returns
+// what was stored.
+func (s *Storage) PutWithInfo(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) (*common.ObjectInfo, error) {
+	info, err := common.PutWithInfo(ctx, s.Storage, key, data, metadata)
+	if err != nil {
+		return nil, err
+	}
+	return info, s.record(ctx, OpPut, key)
+}
+
+// UpdateMetadata updates an object's metadata and records the update.
+func (s *Storage) UpdateMetadata(ctx context.Context, key string, metadata *common.Metadata) error {
+	if err := s.Storage.UpdateMetadata(ctx, key, metadata); err != nil {
+		return err
+	}
+	return s.record(ctx, OpUpdateMetadata, key)
+}
+
+// Append adds data to the end of an object and records a put.
+func (s *Storage) Append(ctx context.Context, key string, data io.Reader) error {
+	if err := common.Append(ctx, s.Storage, key, data); err != nil {
+		return err
+	}
+	return s.record(ctx, OpPut, key)
+}
+
+// Compose concatenates srcKeys into destKey and records a put of destKey.
+func (s *Storage) Compose(ctx context.Context, destKey string, srcKeys ...string) error {
+	if err := common.Compose(ctx, s.Storage, destKey, srcKeys...); err != nil {
+		return err
+	}
+	return s.record(ctx, OpPut, destKey)
+}
+
+// Delete removes an object and records the delete.
+func (s *Storage) Delete(key string) error {
+	return s.DeleteWithContext(context.Background(), key)
+}
+
+// DeleteWithContext removes an object and records the delete.
+func (s *Storage) DeleteWithContext(ctx context.Context, key string) error {
+	if err := s.Storage.DeleteWithContext(ctx, key); err != nil {
+		return err
+	}
+	_, err := s.journal.Record(OpDelete, key, "", 0)
+	return err
+}
@@ -0,0 +1,191 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package checksum records a digest of every object written to a backend,
+// giving it a strong validator that does not depend on how the backend
+// derives its ETags.
+//
+// ETags cannot be compared across providers: S3 reports the MD5 of a
+// single-part upload but a digest of digests for a multipart one, and
+// local, GCS and Azure ETags are not content digests at all. Storage
+// hashes the content of each Put and stores its SHA-256 and MD5 digests as
+// custom metadata (common.MetaChecksumSHA256, common.MetaChecksumMD5).
+// Replication, the caching proxy and the CLI compare objects with
+// common.SameContent and common.StrongValidator, which prefer these digests
+// over ETags.
+//
+//	storage := checksum.NewStorage(backend)
+package checksum
+
+import (
+	"context"
+	"crypto/md5" // #nosec G501 -- MD5 matches single-part S3 ETags; it is not used for security
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// ErrMismatch is returned by Verify when an object's content does not
+// match its recorded digest.
+var ErrMismatch = fmt.Errorf("%w: object content does not match its checksum", common.ErrPreconditionFailed)
+
+// Storage records content digests for objects written to an underlying
+// backend. Appends and composes rewrite the object through Storage, so the
+// digests of the result are recorded too. Reads, listings and other
+// operations pass through.
+type Storage struct {
+	common.Storage
+}
+
+// NewStorage returns underlying with digests recorded on every write.
+func NewStorage(underlying common.Storage) *Storage {
+	return &Storage{Storage: underlying}
+}
+
+// Underlying returns the wrapped backend.
+func (s *Storage) Underlying() common.Storage {
+	return s.Storage
+}
+
+// GetAsOf reads an earlier version of an object from the wrapped backend.
+func (s *Storage) GetAsOf(ctx context.Context, key string, at time.Time) (io.ReadCloser, *common.Metadata, error) {
+	return common.GetAsOf(ctx, s.Storage, key, at)
+}
+
+// Put hashes and stores an object.
+func (s *Storage) Put(key string, data io.Reader) error {
+	return s.PutWithMetadata(context.Background(), key, data, nil)
+}
+
+// PutWithContext hashes and stores an object.
+func (s *Storage) PutWithContext(ctx context.Context, key string, data io.Reader) error {
+	return s.PutWithMetadata(ctx, key, data, nil)
+}
+
+// PutWithMetadata hashes and stores an object, adding its digests to its
+// metadata. The digests must be known before the object is stored, so data
+// is spooled to a temporary file first; the caller's metadata is not
+// modified.
+func (s *Storage) PutWithMetadata(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) error {
+	return s.put(data, metadata, func(content io.Reader, stamped *common.Metadata) error {
+		return s.Storage.PutWithMetadata(ctx, key, content, stamped)
+	})
+}
+
+// PutWithInfo hashes and stores an object like PutWithMetadata and returns
+// what was stored.
+func (s *Storage) PutWithInfo(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) (info *common.ObjectInfo, err error) {
+	err = s.put(data, metadata, func(content io.Reader, stamped *common.Metadata) error {
+		info, err = common.PutWithInfo(ctx, s.Storage, key, content, stamped)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// put spools and hashes data, then writes it with metadata stamped with
+// its digests.
+func (s *Storage) put(data io.Reader, metadata *common.Metadata, write func(io.Reader, *common.Metadata) error) error {
+	spool, err := os.CreateTemp("", "objstore-checksum-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = spool.Close()
+		_ = os.Remove(spool.Name())
+	}()
+
+	sha, sum := sha256.New(), md5.New() // #nosec G401 -- see import
+	size, err := io.Copy(io.MultiWriter(spool, sha, sum), data)
+	if err == nil {
+		_, err = spool.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		return err
+	}
+
+	stamped := &common.Metadata{}
+	if metadata != nil {
+		*stamped = *metadata
+	}
+	stamped.Size = size
+	stamped.Custom = maps.Clone(stamped.Custom)
+	if stamped.Custom == nil {
+		stamped.Custom = make(map[string]string, 3)
+	}
+	stamped.Custom[common.MetaChecksumSHA256] = hex.EncodeToString(sha.Sum(nil))
+	stamped.Custom[common.MetaChecksumMD5] = hex.EncodeToString(sum.Sum(nil))
+	stamped.Custom[common.MetaChecksumSize] = strconv.FormatInt(size, 10)
+	return write(spool, stamped)
+}
+
+// UpdateMetadata replaces the metadata of an object, keeping its recorded
+// digests.
+func (s *Storage) UpdateMetadata(ctx context.Context, key string, metadata *common.Metadata) error {
+	if metadata == nil {
+		return s.Storage.UpdateMetadata(ctx, key, metadata)
+	}
+	stored, err := s.Storage.GetMetadata(ctx, key)
+	if err != nil {
+		return err
+	}
+	update := *metadata
+	update.Custom = maps.Clone(metadata.Custom)
+	if update.Custom == nil {
+		update.Custom = make(map[string]string, 3)
+	}
+	for _, name := range []string{common.MetaChecksumSHA256, common.MetaChecksumMD5, common.MetaChecksumSize} {
+		if v := common.CustomField(stored.Custom, name); v != "" {
+			update.Custom[name] = v
+		}
+	}
+	return s.Storage.UpdateMetadata(ctx, key, &update)
+}
+
+// Verify reads the object stored under key and checks it against its
+// recorded SHA-256 digest. Objects without a digest cannot be verified and
+// fail with common.ErrPreconditionFailed.
+func (s *Storage) Verify(ctx context.Context, key string) error {
+	metadata, err := s.Storage.GetMetadata(ctx, key)
+	if err != nil {
+		return err
+	}
+	want, ok := common.Validators(metadata)[common.ValidatorSHA256]
+	if !ok {
+		return fmt.Errorf("%w: object %s has no checksum", common.ErrPreconditionFailed, key)
+	}
+	rc, err := s.Storage.GetWithContext(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rc.Close() }()
+	h := sha256.New()
+	if _, err := io.Copy(h, rc); err != nil {
+		return err
+	}
+	if hex.EncodeToString(h.Sum(nil)) != want {
+		return fmt.Errorf("%w: %s", ErrMismatch, key)
+	}
+	return nil
+}
+
+var _ common.Storage = (*Storage)(nil)
@@ -0,0 +1,116 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package checksum
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+// Digests of "hello".
+const (
+	helloSHA256 = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	helloMD5    = "5d41402abc4b2a76b9719d911017c592"
+)
+
+func TestPutRecordsDigests(t *testing.T) {
+	ctx := context.Background()
+	backend := memory.New()
+	s := NewStorage(backend)
+
+	meta := &common.Metadata{ContentType: "text/plain", Custom: map[string]string{"owner": "ops"}}
+	if err := s.PutWithMetadata(ctx, "a.txt", strings.NewReader("hello"), meta); err != nil {
+		t.Fatalf("PutWithMetadata: %v", err)
+	}
+	if len(meta.Custom) != 1 {
+		t.Errorf("caller's metadata was modified: %v", meta.Custom)
+	}
+
+	stored, err := backend.GetMetadata(ctx, "a.txt")
+	if err != nil {
+		t.Fatalf("GetMetadata: %v", err)
+	}
+	if got := stored.Custom[common.MetaChecksumSHA256]; got != helloSHA256 {
+		t.Errorf("sha256 = %q, want %q", got, helloSHA256)
+	}
+	if got := stored.Custom[common.MetaChecksumMD5]; got != helloMD5 {
+		t.Errorf("md5 = %q, want %q", got, helloMD5)
+	}
+	if stored.ContentType != "text/plain" || stored.Custom["owner"] != "ops" {
+		t.Errorf("caller's metadata was not stored: %+v", stored)
+	}
+	if got := common.StrongValidator(stored); got != "sha256:"+helloSHA256 {
+		t.Errorf("StrongValidator = %q", got)
+	}
+
+	// Updating the metadata keeps the digests.
+	if err := s.UpdateMetadata(ctx, "a.txt", &common.Metadata{ContentType: "text/markdown"}); err != nil {
+		t.Fatalf("UpdateMetadata: %v", err)
+	}
+	stored, _ = backend.GetMetadata(ctx, "a.txt")
+	if stored.Custom[common.MetaChecksumSHA256] != helloSHA256 {
+		t.Errorf("UpdateMetadata dropped the digest: %v", stored.Custom)
+	}
+}
+
+func TestAppendRecomputesDigests(t *testing.T) {
+	ctx := context.Background()
+	backend := memory.New()
+	s := NewStorage(backend)
+
+	if err := s.Put("log", strings.NewReader("hel")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if err := common.Append(ctx, s, "log", strings.NewReader("lo")); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	stored, _ := backend.GetMetadata(ctx, "log")
+	if got := common.StrongValidator(stored); got != "sha256:"+helloSHA256 {
+		t.Errorf("StrongValidator after append = %q", got)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	ctx := context.Background()
+	backend := memory.New()
+	s := NewStorage(backend)
+
+	if err := s.Put("a.txt", strings.NewReader("hello")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if err := s.Verify(ctx, "a.txt"); err != nil {
+		t.Errorf("Verify: %v", err)
+	}
+
+	// Replace the content behind the wrapper's back, keeping the digests.
+	stored, _ := backend.GetMetadata(ctx, "a.txt")
+	if err := backend.PutWithMetadata(ctx, "a.txt", strings.NewReader("HELLO"), stored); err != nil {
+		t.Fatalf("PutWithMetadata: %v", err)
+	}
+	if err := s.Verify(ctx, "a.txt"); !errors.Is(err, ErrMismatch) {
+		t.Errorf("Verify of modified object = %v, want ErrMismatch", err)
+	}
+
+	if err := backend.Put("plain", strings.NewReader("hello")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if err := s.Verify(ctx, "plain"); !errors.Is(err, common.ErrPreconditionFailed) {
+		t.Errorf("Verify without a digest = %v, want ErrPreconditionFailed", err)
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package checksum
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// DuplicateOptions control FindDuplicates.
+type DuplicateOptions struct {
+	// Hash reads and hashes objects that have no strong validator, so
+	// they are compared too. Without it they are only counted as
+	// unchecked.
+	Hash bool
+}
+
+// DuplicateSet is a group of objects with the same content.
+type DuplicateSet struct {
+	// Checksum is the strong validator the objects share, as
+	// "<algorithm>:<hex digest>".
+	Checksum string `json:"checksum"`
+
+	// Size is the size of each object in bytes.
+	Size int64 `json:"size"`
+
+	// Keys are the keys of the objects, in key order.
+	Keys []string `json:"keys"`
+
+	// Savings is the number of bytes freed by keeping only one of the
+	// objects.
+	Savings int64 `json:"savings"`
+}
+
+// DuplicateReport lists the duplicate sets found under a prefix.
+type DuplicateReport struct {
+	// Objects is the number of objects examined.
+	Objects int64 `json:"objects"`
+
+	// Unchecked is the number of objects that have no strong validator
+	// and were not compared.
+	Unchecked int64 `json:"unchecked"`
+
+	// Sets are the duplicate sets, largest savings first.
+	Sets []DuplicateSet `json:"sets"`
+
+	// Savings is the total of the sets' savings.
+	Savings int64 `json:"savings"`
+}
+
+// FindDuplicates groups the objects under prefix by their content and
+// reports every group of two or more objects. Objects are compared by
+// their strong validators (see common.StrongValidator): the digests
+// Storage records when they are written, or the MD5 ETags of backends that
+// report them. Objects with neither are hashed when opts.Hash is set and
+// counted as unchecked otherwise; no other object data is read.
+func FindDuplicates(ctx context.Context, storage common.Storage, prefix string, opts *DuplicateOptions) (*DuplicateReport, error) {
+	if opts == nil {
+		opts = &DuplicateOptions{}
+	}
+	report := &DuplicateReport{Sets: []DuplicateSet{}}
+	groups := make(map[string]*DuplicateSet)
+	listOpts := &common.ListOptions{Prefix: prefix, MaxResults: 1000}
+	for {
+		page, err := storage.ListWithOptions(ctx, listOpts)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range page.Objects {
+			if obj == nil || strings.HasPrefix(obj.Key, common.StagingPrefix) {
+				continue
+			}
+			metadata := obj.Metadata
+			if metadata == nil || metadata.Custom == nil {
+				// Listings do not include custom metadata, where
+				// recorded digests are kept.
+				if metadata, err = storage.GetMetadata(ctx, obj.Key); err != nil {
+					return nil, err
+				}
+			}
+			report.Objects++
+			validator := common.StrongValidator(metadata)
+			if validator == "" && opts.Hash {
+				if validator, err = hashObject(ctx, storage, obj.Key); err != nil {
+					return nil, err
+				}
+			}
+			if validator == "" {
+				report.Unchecked++
+				continue
+			}
+			set, ok := groups[validator]
+			if !ok {
+				set = &DuplicateSet{Checksum: validator, Size: metadata.Size}
+				groups[validator] = set
+			}
+			set.Keys = append(set.Keys, obj.Key)
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if page.NextToken == "" || page.NextToken == listOpts.ContinueFrom {
+			break
+		}
+		listOpts.ContinueFrom = page.NextToken
+	}
+
+	for _, set := range groups {
+		if len(set.Keys) < 2 {
+			continue
+		}
+		slices.Sort(set.Keys)
+		set.Savings = set.Size * int64(len(set.Keys)-1)
+		report.Savings += set.Savings
+		report.Sets = append(report.Sets, *set)
+	}
+	slices.SortFunc(report.Sets, func(a, b DuplicateSet) int {
+		if c := cmp.Compare(b.Savings, a.Savings); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Keys[0], b.Keys[0])
+	})
+	return report, nil
+}
+
+// hashObject reads the object stored under key and returns its SHA-256
+// digest as a strong validator.
+func hashObject(ctx context.Context, storage common.Storage, key string) (string, error) {
+	rc, err := storage.GetWithContext(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = rc.Close() }()
+	h := sha256.New()
+	if _, err := io.Copy(h, rc); err != nil {
+		return "", err
+	}
+	return common.ValidatorSHA256 + ":" + hex.EncodeToString(h.Sum(nil)), nil
+}
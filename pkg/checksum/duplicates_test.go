@@ -0,0 +1,73 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package checksum
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+func TestFindDuplicates(t *testing.T) {
+	ctx := context.Background()
+	backend := memory.New()
+	s := NewStorage(backend)
+	for key, data := range map[string]string{
+		"docs/a.txt":        "hello",
+		"docs/copy/a.txt":   "hello",
+		"docs/b.txt":        "hello",
+		"docs/big.bin":      "0123456789",
+		"docs/big-copy.bin": "0123456789",
+		"docs/unique.txt":   "unique",
+		"other/a.txt":       "hello",
+	} {
+		if err := s.PutWithContext(ctx, key, strings.NewReader(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Written behind the wrapper's back, so without a digest.
+	if err := backend.PutWithContext(ctx, "docs/raw.txt", strings.NewReader("hello")); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := FindDuplicates(ctx, s, "docs/", nil)
+	if err != nil {
+		t.Fatalf("FindDuplicates: %v", err)
+	}
+	if report.Objects != 7 || report.Unchecked != 1 || len(report.Sets) != 2 {
+		t.Fatalf("report = %+v, want 7 objects, 1 unchecked and 2 sets", report)
+	}
+	hello := report.Sets[0]
+	if hello.Checksum != "sha256:"+helloSHA256 || hello.Size != 5 || hello.Savings != 10 ||
+		!slices.Equal(hello.Keys, []string{"docs/a.txt", "docs/b.txt", "docs/copy/a.txt"}) {
+		t.Errorf("first set = %+v, want the three copies of hello", hello)
+	}
+	if big := report.Sets[1]; big.Savings != 10 || len(big.Keys) != 2 {
+		t.Errorf("second set = %+v, want the two copies of big.bin", big)
+	}
+	if report.Savings != 20 {
+		t.Errorf("savings = %d, want 20", report.Savings)
+	}
+
+	report, err = FindDuplicates(ctx, s, "docs/", &DuplicateOptions{Hash: true})
+	if err != nil {
+		t.Fatalf("FindDuplicates with Hash: %v", err)
+	}
+	if report.Unchecked != 0 || len(report.Sets[0].Keys) != 4 || report.Savings != 25 {
+		t.Errorf("report with Hash = %+v, want docs/raw.txt hashed into the hello set", report)
+	}
+}
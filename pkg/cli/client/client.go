@@ -15,18 +15,33 @@ package client
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"io"
+	"net/http"
+	"time"
 
+	"github.com/jeremyhahn/go-objstore/pkg/access"
 	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/alias"
 	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/cost"
+	"github.com/jeremyhahn/go-objstore/pkg/diff"
+	"github.com/jeremyhahn/go-objstore/pkg/events"
+	"github.com/jeremyhahn/go-objstore/pkg/jobs"
+	"github.com/jeremyhahn/go-objstore/pkg/overlay"
 	"github.com/jeremyhahn/go-objstore/pkg/replication"
+	"github.com/jeremyhahn/go-objstore/pkg/retention"
+	"github.com/jeremyhahn/go-objstore/pkg/search"
+	"github.com/jeremyhahn/go-objstore/pkg/share"
+	"github.com/jeremyhahn/go-objstore/pkg/tasks"
 )
 
 // Client defines the interface for remote object storage operations.
 // Implementations provide access to REST, gRPC, and QUIC servers.
 type Client interface {
 	// Object operations
-	Put(ctx context.Context, key string, reader io.Reader, metadata *common.Metadata) error
+	Put(ctx context.Context, key string, reader io.Reader, metadata *common.Metadata) (*common.ObjectInfo, error)
 	Get(ctx context.Context, key string) (io.ReadCloser, *common.Metadata, error)
 	Delete(ctx context.Context, key string) error
 	Exists(ctx context.Context, key string) (bool, error)
@@ -60,6 +75,141 @@ type Client interface {
 	Close() error
 }
 
+// MetadataBatchUpdater is implemented by clients whose server can apply a
+// metadata patch to every object under a prefix in one request.
+type MetadataBatchUpdater interface {
+	UpdateMetadataBatch(ctx context.Context, prefix string, patch *common.MetadataPatch) (int, error)
+}
+
+// AtomicPutter is implemented by clients whose server stages and verifies
+// an upload before it replaces the object, so readers never observe a
+// partial write.
+type AtomicPutter interface {
+	PutAtomic(ctx context.Context, key string, reader io.Reader, metadata *common.Metadata) error
+}
+
+// Renamer is implemented by clients whose server moves every object under
+// a prefix itself, copying server-side instead of through the client.
+type Renamer interface {
+	RenamePrefix(ctx context.Context, prefix, newPrefix string, opts *common.RenameOptions) (*common.RenameResult, error)
+}
+
+// Browser is implemented by clients whose server lists virtual directories
+// in one request.
+type Browser interface {
+	Browse(ctx context.Context, prefix string, opts *common.BrowseOptions) (*common.BrowseResult, error)
+}
+
+// Searcher is implemented by clients whose server exposes the search API.
+type Searcher interface {
+	Search(ctx context.Context, query string, limit int) ([]search.Document, error)
+}
+
+// Analyzer is implemented by clients whose server exposes the age and
+// access distribution API.
+type Analyzer interface {
+	Analyze(ctx context.Context, prefix string, opts *access.AnalyzeOptions) (*access.Report, error)
+}
+
+// CostReporter is implemented by clients whose server exposes the cost
+// report API.
+type CostReporter interface {
+	CostReport(ctx context.Context, month string) (*cost.Report, error)
+}
+
+// JobsViewer is implemented by clients whose server exposes the background
+// job status API.
+type JobsViewer interface {
+	ListJobs(ctx context.Context) ([]jobs.Status, error)
+	GetJob(ctx context.Context, name string) (*jobs.Status, error)
+}
+
+// TaskManager is implemented by clients whose server exposes the durable
+// task queue API.
+type TaskManager interface {
+	ListTasks(ctx context.Context, state tasks.State) ([]tasks.Task, error)
+	RetryTask(ctx context.Context, id string) (*tasks.Task, error)
+}
+
+// Differ is implemented by clients whose server exposes the object diff
+// API.
+type Differ interface {
+	Diff(ctx context.Context, refA, refB string, opts diff.Options) (*diff.Result, error)
+}
+
+// RetentionManager is implemented by clients whose server exposes the legal
+// hold and deletion approval API.
+type RetentionManager interface {
+	ListDeletionRequests(ctx context.Context, status retention.Status) ([]retention.DeletionRequest, error)
+	ApproveDeletion(ctx context.Context, id string) (*retention.DeletionRequest, error)
+	RejectDeletion(ctx context.Context, id, reason string) (*retention.DeletionRequest, error)
+	ListLegalHolds(ctx context.Context) ([]retention.Hold, error)
+	PlaceLegalHold(ctx context.Context, key, reason string) (*retention.Hold, error)
+	ReleaseLegalHold(ctx context.Context, key string) error
+}
+
+// ShareManager is implemented by clients whose server exposes the share
+// link API.
+type ShareManager interface {
+	// CreateShare creates a share link to key and returns it with its
+	// download URL, which is only available here.
+	CreateShare(ctx context.Context, key string, opts share.Options) (*share.Link, string, error)
+	ListShares(ctx context.Context) ([]share.Link, error)
+	RevokeShare(ctx context.Context, id string) error
+}
+
+// AliasManager is implemented by clients whose server exposes the object
+// alias API.
+type AliasManager interface {
+	SetAlias(ctx context.Context, name, target string) (*alias.Alias, error)
+	ResolveAlias(ctx context.Context, name string) (*alias.Alias, error)
+	ListAliases(ctx context.Context, prefix string) ([]alias.Alias, error)
+	DeleteAlias(ctx context.Context, name string) error
+}
+
+// QuotaManager is implemented by clients whose server exposes the quota
+// API.
+type QuotaManager interface {
+	ListQuotas(ctx context.Context) (map[string]overlay.Quota, error)
+	SetQuota(ctx context.Context, prefix string, quota overlay.Quota) error
+	RemoveQuota(ctx context.Context, prefix string) error
+}
+
+// WebhookManager is implemented by clients whose server exposes the
+// webhook API, which manages event notification rules.
+type WebhookManager interface {
+	ListWebhooks(ctx context.Context) ([]events.Rule, error)
+	SetWebhook(ctx context.Context, rule events.Rule) error
+	DeleteWebhook(ctx context.Context, id string) error
+}
+
+// AccessKeyManager is implemented by clients whose server exposes the
+// access key API, which issues and revokes SigV4 access keys.
+type AccessKeyManager interface {
+	ListAccessKeys(ctx context.Context) ([]adapters.AccessKey, error)
+	CreateAccessKey(ctx context.Context, key adapters.AccessKey) (*adapters.AccessKey, error)
+	GetAccessKey(ctx context.Context, id string) (*adapters.AccessKey, error)
+	DeleteAccessKey(ctx context.Context, id string) error
+}
+
+// Optional returns c as the optional server API T, such as Searcher. It
+// looks through clients that only change how objects are read and written,
+// such as WithSigning, which implement Unwrap() Client.
+func Optional[T any](c Client) (T, bool) {
+	for c != nil {
+		if api, ok := c.(T); ok {
+			return api, true
+		}
+		wrapper, ok := c.(interface{ Unwrap() Client })
+		if !ok {
+			break
+		}
+		c = wrapper.Unwrap()
+	}
+	var zero T
+	return zero, false
+}
+
 // Config holds configuration for creating a client
 type Config struct {
 	ServerURL  string
@@ -68,6 +218,145 @@ type Config struct {
 	UnixSocket string // Path to Unix socket (for unix protocol)
 
 	// InsecureSkipVerify disables server certificate verification for
-	// TLS-based protocols (QUIC). Testing only.
+	// REST over HTTPS, QUIC and gRPC. Development only.
 	InsecureSkipVerify bool
+
+	// CAFile is a PEM file of CA certificates trusted in addition to the
+	// system roots, for servers with certificates from a private CA. gRPC
+	// connects with TLS when CAFile or InsecureSkipVerify is set.
+	CAFile string
+
+	// Timeout bounds the wait for the server to respond once a request has
+	// been sent, for every attempt (0 = DefaultTimeout, negative = no
+	// timeout). Sending and receiving object data is not bounded, so large
+	// transfers are not cut short.
+	Timeout time.Duration
+
+	// Retry retries requests that fail transiently. Nil disables retries.
+	Retry *RetryPolicy
+
+	// Token is sent as "Authorization: Bearer <token>" by the REST and QUIC
+	// clients when set.
+	Token string
+
+	// SigV4 signs REST and QUIC requests with AWS Signature Version 4 when
+	// set. It takes precedence over Token.
+	SigV4 *SigV4Config
+}
+
+// SigV4Config holds the credentials and scope used to sign requests with
+// AWS Signature Version 4.
+type SigV4Config struct {
+	adapters.SigV4Credentials
+
+	// Region is the credential scope region (default: "us-east-1").
+	Region string
+
+	// Service is the credential scope service (default: "s3").
+	Service string
+}
+
+// newUploadRequest returns a PUT request streaming reader as its body.
+// Readers of known length, such as bytes.Reader, are sent with a
+// Content-Length and can be retried; others are sent with chunked transfer
+// encoding as they are read, so objects are never held in memory.
+func newUploadRequest(ctx context.Context, url string, reader io.Reader) (*http.Request, error) {
+	body := reader
+	switch {
+	case reader == nil:
+		body = http.NoBody
+	case isCloser(reader):
+		// The transport closes request bodies, but reader belongs to the
+		// caller.
+		body = io.NopCloser(reader)
+	}
+	return http.NewRequestWithContext(ctx, http.MethodPut, url, body)
+}
+
+func isCloser(r io.Reader) bool {
+	_, ok := r.(io.Closer)
+	return ok
+}
+
+// authTransport wraps base with the authentication configured in config.
+func authTransport(config *Config, base http.RoundTripper) http.RoundTripper {
+	return withBearerToken(config.Token, withSigV4(config.SigV4, base))
+}
+
+// bearerTransport adds an Authorization header to every request.
+type bearerTransport struct {
+	token string
+	base  http.RoundTripper
+}
+
+// withBearerToken wraps base so requests carry token. It returns base
+// unchanged when token is empty; a nil base means http.DefaultTransport.
+func withBearerToken(token string, base http.RoundTripper) http.RoundTripper {
+	if token == "" {
+		return base
+	}
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &bearerTransport{token: token, base: base}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *bearerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+t.token)
+	return t.base.RoundTrip(req)
+}
+
+// sigV4Transport signs every request with AWS Signature Version 4.
+type sigV4Transport struct {
+	config SigV4Config
+	base   http.RoundTripper
+}
+
+// withSigV4 wraps base so requests are signed with config. It returns base
+// unchanged when config is nil; a nil base means http.DefaultTransport.
+func withSigV4(config *SigV4Config, base http.RoundTripper) http.RoundTripper {
+	if config == nil {
+		return base
+	}
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	signed := *config
+	if signed.Region == "" {
+		signed.Region = "us-east-1"
+	}
+	if signed.Service == "" {
+		signed.Service = "s3"
+	}
+	return &sigV4Transport{config: signed, base: base}
+}
+
+// RoundTrip implements http.RoundTripper. Bodies that can be replayed via
+// GetBody are hashed into the signature; others are sent as UNSIGNED-PAYLOAD.
+func (t *sigV4Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+
+	payloadHash := adapters.SigV4UnsignedPayload
+	switch {
+	case req.Body == nil || req.Body == http.NoBody:
+		sum := sha256.Sum256(nil)
+		payloadHash = hex.EncodeToString(sum[:])
+	case req.GetBody != nil:
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		h := sha256.New()
+		_, err = io.Copy(h, body)
+		_ = body.Close()
+		if err != nil {
+			return nil, err
+		}
+		payloadHash = hex.EncodeToString(h.Sum(nil))
+	}
+
+	adapters.SignHTTPRequestV4(req, t.config.SigV4Credentials, t.config.Region, t.config.Service, payloadHash, time.Now())
+	return t.base.RoundTrip(req)
 }
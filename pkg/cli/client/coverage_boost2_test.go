@@ -46,7 +46,7 @@ func restClientWithBadURL() *RESTClient {
 
 func TestRESTClient_Put_NewRequestError(t *testing.T) {
 	c := restClientWithBadURL()
-	err := c.Put(context.Background(), "key", strings.NewReader("data"), nil)
+	_, err := c.Put(context.Background(), "key", strings.NewReader("data"), nil)
 	if err == nil {
 		t.Fatal("expected error from bad URL in Put")
 	}
@@ -167,7 +167,7 @@ func TestRESTClient_Put_EmptyBodyError(t *testing.T) {
 	})
 	defer srv.Close()
 	c := newRESTClient(srv.URL)
-	err := c.Put(context.Background(), "k", strings.NewReader("d"), nil)
+	_, err := c.Put(context.Background(), "k", strings.NewReader("d"), nil)
 	if err == nil {
 		t.Fatal("expected error")
 	}
@@ -393,7 +393,7 @@ func quicClientWithBadURL(t *testing.T) *QUICClient {
 
 func TestQUICClient_Put_NewRequestError(t *testing.T) {
 	c := quicClientWithBadURL(t)
-	err := c.Put(context.Background(), "k", strings.NewReader("d"), nil)
+	_, err := c.Put(context.Background(), "k", strings.NewReader("d"), nil)
 	if err == nil {
 		t.Fatal("expected error from bad URL in Put")
 	}
@@ -554,7 +554,7 @@ func TestQUICClient_Put_EmptyBodyError(t *testing.T) {
 	}))
 	defer srv.Close()
 	c := newQUICTestClient(t, srv.URL)
-	err := c.Put(context.Background(), "k", strings.NewReader("d"), nil)
+	_, err := c.Put(context.Background(), "k", strings.NewReader("d"), nil)
 	if err == nil {
 		t.Fatal("expected error")
 	}
@@ -907,7 +907,7 @@ func TestGRPCClient_Put_ContextCanceled(t *testing.T) {
 	defer cleanup()
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel()
-	err := client.Put(ctx, "k", strings.NewReader("data"), nil)
+	_, err := client.Put(ctx, "k", strings.NewReader("data"), nil)
 	if err == nil {
 		t.Fatal("expected error")
 	}
@@ -0,0 +1,152 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package client
+
+import (
+	"context"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/e2ee"
+	"github.com/jeremyhahn/go-objstore/pkg/search"
+)
+
+// encryptedClient encrypts objects end to end before they are sent to the
+// server and decrypts them after they are read.
+type encryptedClient struct {
+	Client
+	keychain *e2ee.Keychain
+}
+
+// WithEndToEndEncryption returns a Client that encrypts the content and
+// metadata of objects with keychain before sending them through c, so the
+// server only ever stores ciphertext. Objects not written through an
+// encrypting client fail to read with e2ee.ErrNotEncrypted. Listings
+// report stored sizes, and the server-side cost and retention APIs of c
+// are not available through the returned client. When c is a Searcher, so
+// is the returned client; it can only match fields the keychain encrypts
+// deterministically.
+func WithEndToEndEncryption(c Client, keychain *e2ee.Keychain) Client {
+	encrypted := &encryptedClient{Client: c, keychain: keychain}
+	if searcher, ok := c.(Searcher); ok {
+		return &encryptedSearchClient{encryptedClient: encrypted, searcher: searcher}
+	}
+	return encrypted
+}
+
+// encryptedSearchClient is an encryptedClient whose server exposes the
+// search API.
+type encryptedSearchClient struct {
+	*encryptedClient
+	searcher Searcher
+}
+
+// Search runs query on the server. Terms of the form "field:value" for a
+// deterministically encrypted field are rewritten to match its stored
+// value; other metadata is sealed and cannot be matched. Result sizes are
+// plaintext sizes.
+func (c *encryptedSearchClient) Search(ctx context.Context, query string, limit int) ([]search.Document, error) {
+	terms := strings.Fields(query)
+	for i, term := range terms {
+		name, value, ok := strings.Cut(term, ":")
+		if !ok || strings.HasSuffix(value, "*") || !slices.Contains(c.keychain.DeterministicFields(), strings.ToLower(name)) {
+			continue
+		}
+		encrypted, err := c.keychain.EncryptField(name, value)
+		if err != nil {
+			return nil, err
+		}
+		terms[i] = name + ":" + encrypted
+	}
+	docs, err := c.searcher.Search(ctx, strings.Join(terms, " "), limit)
+	if err != nil {
+		return nil, err
+	}
+	for i := range docs {
+		if e2ee.IsEncrypted(&common.Metadata{Custom: docs[i].Custom}) {
+			docs[i].Size = e2ee.PlaintextSize(docs[i].Size)
+		}
+	}
+	return docs, nil
+}
+
+// Put encrypts and uploads an object. The size returned is that of the
+// plaintext.
+func (c *encryptedClient) Put(ctx context.Context, key string, reader io.Reader, metadata *common.Metadata) (*common.ObjectInfo, error) {
+	sealed, stored, err := c.keychain.Seal(reader, metadata)
+	if err != nil {
+		return nil, err
+	}
+	info, err := c.Client.Put(ctx, key, sealed, stored)
+	if err != nil {
+		return nil, err
+	}
+	if info.Metadata != nil && info.Metadata.Size > 0 {
+		info.Metadata.Size = e2ee.PlaintextSize(info.Metadata.Size)
+	}
+	return info, nil
+}
+
+// Get downloads and decrypts an object.
+func (c *encryptedClient) Get(ctx context.Context, key string) (io.ReadCloser, *common.Metadata, error) {
+	rc, stored, err := c.Client.Get(ctx, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Not every protocol returns custom metadata with the content.
+	if !e2ee.IsEncrypted(stored) {
+		if stored, err = c.Client.GetMetadata(ctx, key); err != nil {
+			_ = rc.Close()
+			return nil, nil, err
+		}
+	}
+	metadata, err := c.keychain.OpenMetadata(stored)
+	if err != nil {
+		_ = rc.Close()
+		return nil, nil, err
+	}
+	plain, err := c.keychain.Open(rc, stored)
+	if err != nil {
+		_ = rc.Close()
+		return nil, nil, err
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{plain, rc}, metadata, nil
+}
+
+// GetMetadata returns the decrypted metadata of an object.
+func (c *encryptedClient) GetMetadata(ctx context.Context, key string) (*common.Metadata, error) {
+	stored, err := c.Client.GetMetadata(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return c.keychain.OpenMetadata(stored)
+}
+
+// UpdateMetadata replaces the encrypted metadata of an object.
+func (c *encryptedClient) UpdateMetadata(ctx context.Context, key string, metadata *common.Metadata) error {
+	stored, err := c.Client.GetMetadata(ctx, key)
+	if err != nil {
+		return err
+	}
+	updated, err := c.keychain.Reseal(stored, metadata)
+	if err != nil {
+		return err
+	}
+	return c.Client.UpdateMetadata(ctx, key, updated)
+}
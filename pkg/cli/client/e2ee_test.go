@@ -0,0 +1,141 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/e2ee"
+	"github.com/jeremyhahn/go-objstore/pkg/search"
+)
+
+func TestWithEndToEndEncryption(t *testing.T) {
+	ts, backend := newTokenServer(t)
+	ctx := context.Background()
+
+	keychain := e2ee.NewKeychain()
+	if err := keychain.GenerateKey("laptop"); err != nil {
+		t.Fatal(err)
+	}
+	rest, err := NewRESTClient(&Config{ServerURL: ts.URL, Token: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := WithEndToEndEncryption(rest, keychain)
+
+	data := bytes.Repeat([]byte("confidential "), 10000)
+	meta := &common.Metadata{ContentType: "text/plain", Custom: map[string]string{"owner": "alice"}}
+	if _, err := c.Put(ctx, "docs/plan.txt", bytes.NewReader(data), meta); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	// The server holds only ciphertext and opaque metadata.
+	rc, err := backend.Get("docs/plan.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, _ := io.ReadAll(rc)
+	rc.Close()
+	if bytes.Contains(stored, []byte("confidential")) {
+		t.Error("server stored plaintext content")
+	}
+	storedMeta, err := backend.GetMetadata(ctx, "docs/plan.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if storedMeta.ContentType == "text/plain" || storedMeta.Custom["owner"] != "" {
+		t.Errorf("server stored plaintext metadata %+v", storedMeta)
+	}
+
+	rc, got, err := c.Get(ctx, "docs/plan.txt")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	plain, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil || !bytes.Equal(plain, data) {
+		t.Fatalf("Get() returned %d bytes, %v; want the plaintext", len(plain), err)
+	}
+	if got.ContentType != "text/plain" || got.Custom["owner"] != "alice" || got.Size != int64(len(data)) {
+		t.Errorf("Get() metadata = %+v", got)
+	}
+
+	if err := c.UpdateMetadata(ctx, "docs/plan.txt", &common.Metadata{ContentType: "text/markdown"}); err != nil {
+		t.Fatalf("UpdateMetadata() error = %v", err)
+	}
+	if got, err := c.GetMetadata(ctx, "docs/plan.txt"); err != nil || got.ContentType != "text/markdown" {
+		t.Errorf("GetMetadata() = %+v, %v", got, err)
+	}
+
+	// Objects written without encryption are refused rather than trusted.
+	if _, err := rest.Put(ctx, "plain.txt", strings.NewReader("plain"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := c.Get(ctx, "plain.txt"); !errors.Is(err, e2ee.ErrNotEncrypted) {
+		t.Errorf("Get() of unencrypted object error = %v, want ErrNotEncrypted", err)
+	}
+}
+
+// recordingSearcher is a Client whose server search records queries.
+type recordingSearcher struct {
+	Client
+	query string
+	docs  []search.Document
+}
+
+func (s *recordingSearcher) Search(ctx context.Context, query string, limit int) ([]search.Document, error) {
+	s.query = query
+	return s.docs, nil
+}
+
+func TestEndToEndEncryptionSearch(t *testing.T) {
+	keychain := e2ee.NewKeychain()
+	if err := keychain.GenerateKey("laptop"); err != nil {
+		t.Fatal(err)
+	}
+	if err := keychain.SetDeterministic(e2ee.DeterministicConfig{Fields: []string{"case"}, AcceptEqualityLeak: true}); err != nil {
+		t.Fatal(err)
+	}
+	_, stored, err := keychain.Seal(strings.NewReader("x"), &common.Metadata{Custom: map[string]string{"case": "c-42"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &recordingSearcher{docs: []search.Document{{Key: "a", Size: e2ee.CiphertextSize(1), Custom: stored.Custom}}}
+
+	c, ok := WithEndToEndEncryption(server, keychain).(Searcher)
+	if !ok {
+		t.Fatal("encrypting client of a Searcher is not a Searcher")
+	}
+	docs, err := c.Search(context.Background(), "case:c-42 prefix:docs/ case:c*", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "case:" + stored.Custom["case"] + " prefix:docs/ case:c*"
+	if server.query != want {
+		t.Errorf("server query = %q, want %q", server.query, want)
+	}
+	if len(docs) != 1 || docs[0].Size != 1 {
+		t.Errorf("Search() = %+v, want plaintext sizes", docs)
+	}
+
+	if _, ok := WithEndToEndEncryption(&struct{ Client }{}, keychain).(Searcher); ok {
+		t.Error("encrypting client of a non-Searcher is a Searcher")
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// APIError is a failed REST or QUIC request as the server reported it.
+// Servers answer errors with a JSON body carrying a machine-readable Code
+// (such as "NOT_FOUND"), so callers can branch on it with errors.As instead
+// of parsing Message. Code is empty when the body is not such a body, as
+// from older servers and proxies; Message then holds the body text. It
+// wraps ErrServerError.
+type APIError struct {
+	Status    int            `json:"status"`
+	Code      string         `json:"code"`
+	Message   string         `json:"message"`
+	RequestID string         `json:"request_id,omitempty"`
+	Retryable bool           `json:"retryable"`
+	Details   map[string]any `json:"details,omitempty"`
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("%s %d", ErrServerError, e.Status)
+	}
+	return fmt.Sprintf("%s %d: %s", ErrServerError, e.Status, e.Message)
+}
+
+func (e *APIError) Unwrap() error {
+	return ErrServerError
+}
+
+// statusError returns the APIError for resp, a failed response, decoded
+// from its body.
+func statusError(resp *http.Response) error {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil || len(body) == 0 {
+		return &APIError{Status: resp.StatusCode}
+	}
+	var apiErr APIError
+	if err := json.Unmarshal(body, &apiErr); err != nil || apiErr.Code == "" {
+		return &APIError{Status: resp.StatusCode, Message: strings.TrimSpace(string(body))}
+	}
+	apiErr.Status = resp.StatusCode
+	return &apiErr
+}
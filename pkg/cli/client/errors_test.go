@@ -0,0 +1,74 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+func TestAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/objects/missing.txt":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"Not Found","code":"NOT_FOUND","status":404,"message":"object not found","request_id":"req-1","retryable":false}`))
+		case "/api/v1/objects/busy.txt":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"error":"Service Unavailable","code":"UNAVAILABLE","status":503,"message":"service unavailable","retryable":true,"details":{"backend":"s3"}}`))
+		default:
+			// A proxy or a server that predates structured errors.
+			http.Error(w, "bad gateway", http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClient(&Config{ServerURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	_, _, err = client.Get(ctx, "missing.txt")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Get() error = %v, want an APIError", err)
+	}
+	if apiErr.Status != http.StatusNotFound || apiErr.Code != "NOT_FOUND" || apiErr.RequestID != "req-1" || apiErr.Retryable {
+		t.Errorf("Get() APIError = %+v", apiErr)
+	}
+	if !errors.Is(err, common.ErrKeyNotFound) || !errors.Is(err, ErrServerError) {
+		t.Errorf("Get() error = %v, want it to wrap ErrKeyNotFound and ErrServerError", err)
+	}
+
+	err = client.Delete(ctx, "busy.txt")
+	if !errors.As(err, &apiErr) || apiErr.Code != "UNAVAILABLE" || !apiErr.Retryable || apiErr.Details["backend"] != "s3" {
+		t.Errorf("Delete() error = %#v", err)
+	}
+	if want := "server returned error 503: service unavailable"; err.Error() != want {
+		t.Errorf("Delete() error = %q, want %q", err, want)
+	}
+
+	err = client.Delete(ctx, "other.txt")
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusBadGateway || apiErr.Code != "" || apiErr.Message != "bad gateway" {
+		t.Errorf("Delete() unstructured error = %#v", err)
+	}
+}
@@ -35,6 +35,10 @@ var (
 	ErrNoStatus = errors.New("no status returned")
 	// ErrServerError is returned when server returns non-success status
 	ErrServerError = errors.New("server returned error")
+	// ErrTimeout is returned when the server does not respond in time
+	ErrTimeout = errors.New("server did not respond in time")
+	// ErrInvalidCACert is returned when the CA file holds no certificates
+	ErrInvalidCACert = errors.New("invalid CA certificate")
 )
 
 // NewClient creates a new client based on the protocol specified in the config
@@ -15,15 +15,22 @@ package client
 
 import (
 	"context"
+	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"time"
 
 	objstorepb "github.com/jeremyhahn/go-objstore/api/proto"
 	"github.com/jeremyhahn/go-objstore/pkg/common"
 	"github.com/jeremyhahn/go-objstore/pkg/replication"
+	"github.com/jeremyhahn/go-objstore/pkg/search"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -39,9 +46,24 @@ func NewGRPCClient(config *Config) (*GRPCClient, error) {
 		return nil, ErrServerURLRequired
 	}
 
-	// Note: TLS configuration can be added via DialOption parameters
+	creds := insecure.NewCredentials()
+	if config.CAFile != "" || config.InsecureSkipVerify {
+		tlsConfig, err := clientTLSConfig(config, tls.VersionTLS12)
+		if err != nil {
+			return nil, err
+		}
+		creds = credentials.NewTLS(tlsConfig)
+	}
 	opts := []grpc.DialOption{
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithTransportCredentials(creds),
+	}
+	if timeout := requestTimeout(config); timeout > 0 {
+		opts = append(opts,
+			grpc.WithUnaryInterceptor(unaryTimeout(timeout)),
+			grpc.WithStreamInterceptor(streamTimeout(timeout)))
+	}
+	if config.Retry != nil && config.Retry.MaxAttempts > 1 {
+		opts = append(opts, grpc.WithDefaultServiceConfig(retryServiceConfig(*config.Retry)))
 	}
 
 	conn, err := grpc.NewClient(config.ServerURL, opts...)
@@ -55,12 +77,78 @@ func NewGRPCClient(config *Config) (*GRPCClient, error) {
 	}, nil
 }
 
-// Put uploads an object
-func (c *GRPCClient) Put(ctx context.Context, key string, reader io.Reader, metadata *common.Metadata) error {
-	// Read all data
+// unaryTimeout bounds every unary call by timeout.
+func unaryTimeout(timeout time.Duration) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// streamTimeout bounds the wait for the first message of every streaming
+// call by timeout. The rest of the stream is not bounded, so large objects
+// are not cut short.
+func streamTimeout(timeout time.Duration) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx, cancel := context.WithCancel(ctx)
+		timer := time.AfterFunc(timeout, cancel)
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			timer.Stop()
+			cancel()
+			return nil, err
+		}
+		return &timeoutStream{ClientStream: stream, timer: timer, cancel: cancel}, nil
+	}
+}
+
+// timeoutStream stops its timer once the first message has arrived and
+// releases its context once the stream has ended.
+type timeoutStream struct {
+	grpc.ClientStream
+	timer  *time.Timer
+	cancel context.CancelFunc
+}
+
+// RecvMsg implements grpc.ClientStream.
+func (s *timeoutStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	s.timer.Stop()
+	if err != nil {
+		s.cancel()
+	}
+	return err
+}
+
+// retryServiceConfig returns a service config that retries calls failing
+// with UNAVAILABLE according to policy.
+func retryServiceConfig(policy RetryPolicy) string {
+	policy = policy.withDefaults()
+	seconds := func(d time.Duration) string { return fmt.Sprintf("%.3fs", d.Seconds()) }
+	config := map[string]any{
+		"methodConfig": []any{map[string]any{
+			"name": []any{map[string]any{"service": objstorepb.ObjectStore_ServiceDesc.ServiceName}},
+			"retryPolicy": map[string]any{
+				"maxAttempts":          min(policy.MaxAttempts, 5),
+				"initialBackoff":       seconds(policy.InitialBackoff),
+				"maxBackoff":           seconds(policy.MaxBackoff),
+				"backoffMultiplier":    2,
+				"retryableStatusCodes": []string{"UNAVAILABLE"},
+			},
+		}},
+	}
+	data, _ := json.Marshal(config)
+	return string(data)
+}
+
+// Put uploads an object. The Put RPC is unary, so unlike the REST and QUIC
+// clients the object is read into memory and is limited by the server's
+// maximum message size.
+func (c *GRPCClient) Put(ctx context.Context, key string, reader io.Reader, metadata *common.Metadata) (*common.ObjectInfo, error) {
 	data, err := io.ReadAll(reader)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	req := &objstorepb.PutRequest{
@@ -72,59 +160,75 @@ func (c *GRPCClient) Put(ctx context.Context, key string, reader io.Reader, meta
 		req.Metadata = metadataToProto(metadata)
 	}
 
-	_, err = c.client.Put(ctx, req)
-	return err
+	resp, err := c.client.Put(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	written := &common.Metadata{
+		ETag:    resp.Etag,
+		Version: resp.Version,
+		Size:    resp.Size,
+	}
+	if resp.LastModified != nil {
+		written.LastModified = resp.LastModified.AsTime()
+	}
+	return &common.ObjectInfo{Key: key, Metadata: written}, nil
 }
 
-// Get retrieves an object
+// Get retrieves an object. The returned reader streams the object's chunks
+// as they are read; closing it ends the call.
 func (c *GRPCClient) Get(ctx context.Context, key string) (io.ReadCloser, *common.Metadata, error) {
 	req := &objstorepb.GetRequest{
 		Key: key,
 	}
 
+	ctx, cancel := context.WithCancel(ctx)
 	stream, err := c.client.Get(ctx, req)
 	if err != nil {
-		return nil, nil, err
+		cancel()
+		return nil, nil, keyError(key, err)
 	}
 
 	// Receive first chunk to get metadata
 	firstChunk, err := stream.Recv()
 	if err != nil {
-		return nil, nil, err
+		cancel()
+		return nil, nil, keyError(key, err)
 	}
 
 	metadata := protoToMetadata(firstChunk.Metadata)
+	return &grpcObjectReader{stream: stream, buf: firstChunk.Data, cancel: cancel}, metadata, nil
+}
 
-	// Create a pipe to stream the data
-	pr, pw := io.Pipe()
-
-	go func() {
-		defer func() { _ = pw.Close() }()
+// grpcObjectReader reads an object from a Get stream.
+type grpcObjectReader struct {
+	stream objstorepb.ObjectStore_GetClient
+	buf    []byte
+	err    error
+	cancel context.CancelFunc
+}
 
-		// Write first chunk
-		if len(firstChunk.Data) > 0 {
-			if _, err := pw.Write(firstChunk.Data); err != nil {
-				return
-			}
+func (r *grpcObjectReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.err != nil {
+			return 0, r.err
 		}
-
-		// Stream remaining chunks
-		for {
-			chunk, err := stream.Recv()
-			if err == io.EOF {
-				break
-			}
-			if err != nil {
-				pw.CloseWithError(err)
-				return
-			}
-			if _, err := pw.Write(chunk.Data); err != nil {
-				return
-			}
+		chunk, err := r.stream.Recv()
+		if err != nil {
+			r.err = err
+			continue
 		}
-	}()
+		r.buf = chunk.Data
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
 
-	return pr, metadata, nil
+// Close ends the call, discarding any unread chunks.
+func (r *grpcObjectReader) Close() error {
+	r.cancel()
+	return nil
 }
 
 // Delete removes an object
@@ -195,12 +299,21 @@ func (c *GRPCClient) GetMetadata(ctx context.Context, key string) (*common.Metad
 
 	resp, err := c.client.GetMetadata(ctx, req)
 	if err != nil {
-		return nil, err
+		return nil, keyError(key, err)
 	}
 
 	return protoToMetadata(resp.Metadata), nil
 }
 
+// keyError wraps a NotFound status for key with common.ErrKeyNotFound so
+// callers can test it with errors.Is.
+func keyError(key string, err error) error {
+	if status.Code(err) == codes.NotFound {
+		return common.ProviderError(common.ErrNotFound, key, err)
+	}
+	return err
+}
+
 // UpdateMetadata updates object metadata
 func (c *GRPCClient) UpdateMetadata(ctx context.Context, key string, metadata *common.Metadata) error {
 	req := &objstorepb.UpdateMetadataRequest{
@@ -224,6 +337,46 @@ func (c *GRPCClient) Archive(ctx context.Context, key, destinationType string, d
 	return err
 }
 
+// RestoreFromArchive copies sourceKey from a readable archival backend back
+// to key on the server. An empty sourceKey restores the object stored under
+// key.
+func (c *GRPCClient) RestoreFromArchive(ctx context.Context, key, sourceKey, sourceType string, sourceSettings map[string]string) error {
+	req := &objstorepb.RestoreFromArchiveRequest{
+		Key:            key,
+		SourceKey:      sourceKey,
+		SourceType:     sourceType,
+		SourceSettings: sourceSettings,
+	}
+
+	_, err := c.client.RestoreFromArchive(ctx, req)
+	return err
+}
+
+// Search finds objects whose key or metadata match query
+func (c *GRPCClient) Search(ctx context.Context, query string, limit int) ([]search.Document, error) {
+	resp, err := c.client.Search(ctx, &objstorepb.SearchRequest{
+		Query: query,
+		Limit: int32(min(limit, math.MaxInt32)), // #nosec G115 -- clamped above
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make([]search.Document, 0, len(resp.Objects))
+	for _, obj := range resp.Objects {
+		doc := search.Document{Key: obj.Key}
+		if md := protoToMetadata(obj.Metadata); md != nil {
+			doc.Size = md.Size
+			doc.ETag = md.ETag
+			doc.ContentType = md.ContentType
+			doc.LastModified = md.LastModified
+			doc.Custom = md.Custom
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
+
 // AddPolicy adds a lifecycle policy
 func (c *GRPCClient) AddPolicy(ctx context.Context, policy common.LifecyclePolicy) error {
 	req := &objstorepb.AddPolicyRequest{
@@ -25,6 +25,8 @@ import (
 	objstorepb "github.com/jeremyhahn/go-objstore/api/proto"
 	"github.com/jeremyhahn/go-objstore/pkg/common"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -39,7 +41,12 @@ type mockGRPCServer struct {
 }
 
 func (s *mockGRPCServer) Put(ctx context.Context, req *objstorepb.PutRequest) (*objstorepb.PutResponse, error) {
-	return &objstorepb.PutResponse{}, nil
+	return &objstorepb.PutResponse{
+		Success:      true,
+		Etag:         "abc",
+		Size:         int64(len(req.Data)),
+		LastModified: timestamppb.Now(),
+	}, nil
 }
 
 func (s *mockGRPCServer) Get(req *objstorepb.GetRequest, stream objstorepb.ObjectStore_GetServer) error {
@@ -143,6 +150,26 @@ func (s *mockGRPCServer) ApplyPolicies(ctx context.Context, req *objstorepb.Appl
 	}, nil
 }
 
+func (s *mockGRPCServer) RestoreFromArchive(ctx context.Context, req *objstorepb.RestoreFromArchiveRequest) (*objstorepb.RestoreFromArchiveResponse, error) {
+	if req.SourceType == "glacier" {
+		return nil, status.Error(codes.FailedPrecondition, "archive-only")
+	}
+	return &objstorepb.RestoreFromArchiveResponse{Success: true}, nil
+}
+
+func (s *mockGRPCServer) Search(ctx context.Context, req *objstorepb.SearchRequest) (*objstorepb.SearchResponse, error) {
+	return &objstorepb.SearchResponse{
+		Objects: []*objstorepb.ObjectInfo{{
+			Key: "docs/report.txt",
+			Metadata: &objstorepb.Metadata{
+				ContentType: "text/plain",
+				Size:        4,
+				Custom:      map[string]string{"author": "alice"},
+			},
+		}},
+	}, nil
+}
+
 func (s *mockGRPCServer) Health(ctx context.Context, req *objstorepb.HealthRequest) (*objstorepb.HealthResponse, error) {
 	return &objstorepb.HealthResponse{
 		Status:  objstorepb.HealthResponse_SERVING,
@@ -257,9 +284,12 @@ func TestGRPCClient_Put(t *testing.T) {
 	client, cleanup := createGRPCTestClient(t)
 	defer cleanup()
 
-	err := client.Put(context.Background(), "test.txt", strings.NewReader("hello"), nil)
+	info, err := client.Put(context.Background(), "test.txt", strings.NewReader("hello"), nil)
 	if err != nil {
-		t.Errorf("Put failed: %v", err)
+		t.Fatalf("Put failed: %v", err)
+	}
+	if info.Key != "test.txt" || info.Metadata.ETag != "abc" || info.Metadata.Size != 5 || info.Metadata.LastModified.IsZero() {
+		t.Errorf("Put() = %+v, %+v", info, info.Metadata)
 	}
 }
 
@@ -272,7 +302,7 @@ func TestGRPCClient_PutWithMetadata(t *testing.T) {
 		Custom:      map[string]string{"author": "test"},
 	}
 
-	err := client.Put(context.Background(), "test.txt", strings.NewReader("hello"), metadata)
+	_, err := client.Put(context.Background(), "test.txt", strings.NewReader("hello"), metadata)
 	if err != nil {
 		t.Errorf("Put with metadata failed: %v", err)
 	}
@@ -390,6 +420,35 @@ func TestGRPCClient_Archive(t *testing.T) {
 	}
 }
 
+func TestGRPCClient_RestoreFromArchive(t *testing.T) {
+	client, cleanup := createGRPCTestClient(t)
+	defer cleanup()
+
+	err := client.RestoreFromArchive(context.Background(), "test.txt", "", "local", map[string]string{"path": "/archive"})
+	if err != nil {
+		t.Errorf("RestoreFromArchive failed: %v", err)
+	}
+
+	err = client.RestoreFromArchive(context.Background(), "test.txt", "", "glacier", nil)
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("RestoreFromArchive(glacier) error = %v, want FailedPrecondition", err)
+	}
+}
+
+func TestGRPCClient_Search(t *testing.T) {
+	client, cleanup := createGRPCTestClient(t)
+	defer cleanup()
+
+	var searcher Searcher = client
+	docs, err := searcher.Search(context.Background(), "author:alice", 10)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(docs) != 1 || docs[0].Key != "docs/report.txt" || docs[0].Size != 4 || docs[0].Custom["author"] != "alice" {
+		t.Errorf("Search = %+v", docs)
+	}
+}
+
 func TestGRPCClient_Policies(t *testing.T) {
 	client, cleanup := createGRPCTestClient(t)
 	defer cleanup()
@@ -647,7 +706,7 @@ func TestGRPCClient_Put_ReadError(t *testing.T) {
 
 	// Create a reader that always returns an error
 	errorReader := &errorReader{}
-	err := client.Put(context.Background(), "test.txt", errorReader, nil)
+	_, err := client.Put(context.Background(), "test.txt", errorReader, nil)
 	if err == nil {
 		t.Error("expected error from errorReader")
 	}
@@ -40,17 +40,17 @@ type QUICClient struct {
 }
 
 // NewQUICClient creates a new QUIC client speaking genuine HTTP/3 over UDP.
-// Server certificates are verified against the system root pool, which honors
-// the standard SSL_CERT_FILE/SSL_CERT_DIR overrides for custom CAs. Set
-// Config.InsecureSkipVerify to skip verification (testing only).
+// Server certificates are verified against the system root pool and
+// Config.CAFile. Set Config.InsecureSkipVerify to skip verification
+// (development only).
 func NewQUICClient(config *Config) (*QUICClient, error) {
 	if config.ServerURL == "" {
 		return nil, ErrServerURLRequired
 	}
 
-	tlsConfig := &tls.Config{
-		MinVersion:         tls.VersionTLS13,
-		InsecureSkipVerify: config.InsecureSkipVerify, // #nosec G402 -- testing-only opt-in, defaults to false
+	tlsConfig, err := clientTLSConfig(config, tls.VersionTLS13)
+	if err != nil {
+		return nil, err
 	}
 
 	transport := &http3.Transport{
@@ -58,8 +58,7 @@ func NewQUICClient(config *Config) (*QUICClient, error) {
 	}
 
 	httpClient := &http.Client{
-		Transport: transport,
-		Timeout:   30 * time.Second,
+		Transport: withRetry(config.Retry, withTimeout(requestTimeout(config), authTransport(config, transport))),
 	}
 
 	return &QUICClient{
@@ -69,19 +68,16 @@ func NewQUICClient(config *Config) (*QUICClient, error) {
 	}, nil
 }
 
-// Put uploads an object
-func (c *QUICClient) Put(ctx context.Context, key string, reader io.Reader, metadata *common.Metadata) error {
-	url := fmt.Sprintf("%s/objects/%s", c.baseURL, key)
+// headerMetaPrefix prefixes the headers carrying custom metadata.
+const headerMetaPrefix = "X-Meta-"
 
-	// Read all data
-	data, err := io.ReadAll(reader)
-	if err != nil {
-		return err
-	}
+// Put uploads an object and returns what the server stored.
+func (c *QUICClient) Put(ctx context.Context, key string, reader io.Reader, metadata *common.Metadata) (*common.ObjectInfo, error) {
+	url := fmt.Sprintf("%s/objects/%s", c.baseURL, key)
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, bytes.NewReader(data))
+	req, err := newUploadRequest(ctx, url, reader)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Add metadata as headers if provided
@@ -92,27 +88,25 @@ func (c *QUICClient) Put(ctx context.Context, key string, reader io.Reader, meta
 		if metadata.ContentEncoding != "" {
 			req.Header.Set("Content-Encoding", metadata.ContentEncoding)
 		}
-		// Add custom metadata as X-Custom-* headers
+		// Add custom metadata as X-Meta-* headers
 		for k, v := range metadata.Custom {
-			req.Header.Set(fmt.Sprintf("X-Custom-%s", k), v)
+			req.Header.Set(headerMetaPrefix+k, v)
 		}
 	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		body, err := io.ReadAll(resp.Body)
-		if err == nil && len(body) > 0 {
-			return fmt.Errorf("%w %d: %s", ErrServerError, resp.StatusCode, string(body))
-		}
-		return fmt.Errorf("%w %d", ErrServerError, resp.StatusCode)
+		return nil, statusError(resp)
 	}
 
-	return nil
+	var result putResult
+	_ = json.NewDecoder(resp.Body).Decode(&result)
+	return result.info(key, resp.Header), nil
 }
 
 // Get retrieves an object
@@ -131,11 +125,7 @@ func (c *QUICClient) Get(ctx context.Context, key string) (io.ReadCloser, *commo
 
 	if resp.StatusCode != http.StatusOK {
 		defer func() { _ = resp.Body.Close() }()
-		body, err := io.ReadAll(resp.Body)
-		if err == nil && len(body) > 0 {
-			return nil, nil, fmt.Errorf("%w %d: %s", ErrServerError, resp.StatusCode, string(body))
-		}
-		return nil, nil, fmt.Errorf("%w %d", ErrServerError, resp.StatusCode)
+		return nil, nil, statusError(resp)
 	}
 
 	// Extract metadata from headers
@@ -152,10 +142,10 @@ func (c *QUICClient) Get(ctx context.Context, key string) (io.ReadCloser, *commo
 		}
 	}
 
-	// Extract custom metadata from X-Custom-* headers
+	// Extract custom metadata from X-Meta-* headers
 	for k, v := range resp.Header {
-		if strings.HasPrefix(k, "X-Custom-") {
-			customKey := strings.TrimPrefix(k, "X-Custom-")
+		if strings.HasPrefix(k, headerMetaPrefix) {
+			customKey := strings.TrimPrefix(k, headerMetaPrefix)
 			if len(v) > 0 {
 				metadata.Custom[customKey] = v[0]
 			}
@@ -181,11 +171,7 @@ func (c *QUICClient) Delete(ctx context.Context, key string) error {
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		body, err := io.ReadAll(resp.Body)
-		if err == nil && len(body) > 0 {
-			return fmt.Errorf("%w %d: %s", ErrServerError, resp.StatusCode, string(body))
-		}
-		return fmt.Errorf("%w %d", ErrServerError, resp.StatusCode)
+		return statusError(resp)
 	}
 
 	return nil
@@ -228,6 +214,12 @@ func (c *QUICClient) List(ctx context.Context, opts *common.ListOptions) (*commo
 		if opts.ContinueFrom != "" {
 			params.Set("continue_from", opts.ContinueFrom)
 		}
+		if opts.SortBy != "" {
+			params.Set("sort", string(opts.SortBy))
+			if opts.SortDescending {
+				params.Set("order", "desc")
+			}
+		}
 	}
 
 	if len(params) > 0 {
@@ -246,11 +238,7 @@ func (c *QUICClient) List(ctx context.Context, opts *common.ListOptions) (*commo
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		body, err := io.ReadAll(resp.Body)
-		if err == nil && len(body) > 0 {
-			return nil, fmt.Errorf("%w %d: %s", ErrServerError, resp.StatusCode, string(body))
-		}
-		return nil, fmt.Errorf("%w %d", ErrServerError, resp.StatusCode)
+		return nil, statusError(resp)
 	}
 
 	var result common.ListResult
@@ -277,7 +265,7 @@ func (c *QUICClient) GetMetadata(ctx context.Context, key string) (*common.Metad
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("%w %d", ErrServerError, resp.StatusCode)
+		return nil, statusError(resp)
 	}
 
 	// Extract metadata from headers
@@ -294,10 +282,10 @@ func (c *QUICClient) GetMetadata(ctx context.Context, key string) (*common.Metad
 		}
 	}
 
-	// Extract custom metadata from X-Custom-* headers
+	// Extract custom metadata from X-Meta-* headers
 	for k, v := range resp.Header {
-		if strings.HasPrefix(k, "X-Custom-") {
-			customKey := strings.TrimPrefix(k, "X-Custom-")
+		if strings.HasPrefix(k, headerMetaPrefix) {
+			customKey := strings.TrimPrefix(k, headerMetaPrefix)
 			if len(v) > 0 {
 				metadata.Custom[customKey] = v[0]
 			}
@@ -311,23 +299,23 @@ func (c *QUICClient) GetMetadata(ctx context.Context, key string) (*common.Metad
 func (c *QUICClient) UpdateMetadata(ctx context.Context, key string, metadata *common.Metadata) error {
 	url := fmt.Sprintf("%s/objects/%s", c.baseURL, key)
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, url, http.NoBody)
+	if metadata == nil {
+		metadata = &common.Metadata{}
+	}
+	body, err := json.Marshal(map[string]any{
+		"content_type":     metadata.ContentType,
+		"content_encoding": metadata.ContentEncoding,
+		"custom":           metadata.Custom,
+	})
 	if err != nil {
 		return err
 	}
 
-	// Set metadata as headers
-	if metadata != nil {
-		if metadata.ContentType != "" {
-			req.Header.Set("Content-Type", metadata.ContentType)
-		}
-		if metadata.ContentEncoding != "" {
-			req.Header.Set("Content-Encoding", metadata.ContentEncoding)
-		}
-		for k, v := range metadata.Custom {
-			req.Header.Set(fmt.Sprintf("X-Custom-%s", k), v)
-		}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, url, bytes.NewReader(body))
+	if err != nil {
+		return err
 	}
+	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -336,11 +324,7 @@ func (c *QUICClient) UpdateMetadata(ctx context.Context, key string, metadata *c
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		body, err := io.ReadAll(resp.Body)
-		if err == nil && len(body) > 0 {
-			return fmt.Errorf("%w %d: %s", ErrServerError, resp.StatusCode, string(body))
-		}
-		return fmt.Errorf("%w %d", ErrServerError, resp.StatusCode)
+		return statusError(resp)
 	}
 
 	return nil
@@ -374,11 +358,7 @@ func (c *QUICClient) Archive(ctx context.Context, key, destinationType string, d
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		body, err := io.ReadAll(resp.Body)
-		if err == nil && len(body) > 0 {
-			return fmt.Errorf("%w %d: %s", ErrServerError, resp.StatusCode, string(body))
-		}
-		return fmt.Errorf("%w %d", ErrServerError, resp.StatusCode)
+		return statusError(resp)
 	}
 
 	return nil
@@ -413,11 +393,7 @@ func (c *QUICClient) AddPolicy(ctx context.Context, policy common.LifecyclePolic
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		body, err := io.ReadAll(resp.Body)
-		if err == nil && len(body) > 0 {
-			return fmt.Errorf("%w %d: %s", ErrServerError, resp.StatusCode, string(body))
-		}
-		return fmt.Errorf("%w %d", ErrServerError, resp.StatusCode)
+		return statusError(resp)
 	}
 
 	return nil
@@ -439,11 +415,7 @@ func (c *QUICClient) RemovePolicy(ctx context.Context, policyID string) error {
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		body, err := io.ReadAll(resp.Body)
-		if err == nil && len(body) > 0 {
-			return fmt.Errorf("%w %d: %s", ErrServerError, resp.StatusCode, string(body))
-		}
-		return fmt.Errorf("%w %d", ErrServerError, resp.StatusCode)
+		return statusError(resp)
 	}
 
 	return nil
@@ -465,11 +437,7 @@ func (c *QUICClient) GetPolicies(ctx context.Context) ([]common.LifecyclePolicy,
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		body, err := io.ReadAll(resp.Body)
-		if err == nil && len(body) > 0 {
-			return nil, fmt.Errorf("%w %d: %s", ErrServerError, resp.StatusCode, string(body))
-		}
-		return nil, fmt.Errorf("%w %d", ErrServerError, resp.StatusCode)
+		return nil, statusError(resp)
 	}
 
 	var result struct {
@@ -514,11 +482,7 @@ func (c *QUICClient) ApplyPolicies(ctx context.Context) (policiesCount int, obje
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		body, err := io.ReadAll(resp.Body)
-		if err == nil && len(body) > 0 {
-			return 0, 0, fmt.Errorf("%w %d: %s", ErrServerError, resp.StatusCode, string(body))
-		}
-		return 0, 0, fmt.Errorf("%w %d", ErrServerError, resp.StatusCode)
+		return 0, 0, statusError(resp)
 	}
 
 	var result struct {
@@ -532,6 +496,75 @@ func (c *QUICClient) ApplyPolicies(ctx context.Context) (policiesCount int, obje
 	return result.PoliciesCount, result.ObjectsProcessed, nil
 }
 
+// UpdateMetadataBatch applies patch to the metadata of every object under
+// prefix and returns the number of objects changed
+func (c *QUICClient) UpdateMetadataBatch(ctx context.Context, prefix string, patch *common.MetadataPatch) (int, error) {
+	body, err := json.Marshal(patch)
+	if err != nil {
+		return 0, err
+	}
+
+	endpoint := fmt.Sprintf("%s/objects?prefix=%s", c.baseURL, url.QueryEscape(prefix))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, statusError(resp)
+	}
+
+	var result struct {
+		Updated int `json:"updated"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, err
+	}
+	return result.Updated, nil
+}
+
+// Browse returns the folders and objects directly under prefix
+func (c *QUICClient) Browse(ctx context.Context, prefix string, opts *common.BrowseOptions) (*common.BrowseResult, error) {
+	params := url.Values{}
+	params.Set("prefix", prefix)
+	if opts != nil {
+		if opts.Delimiter != "" {
+			params.Set("delimiter", opts.Delimiter)
+		}
+		if opts.Totals {
+			params.Set("totals", "true")
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/browse?"+params.Encode(), http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError(resp)
+	}
+
+	var result common.BrowseResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
 // Health checks server health
 func (c *QUICClient) Health(ctx context.Context) error {
 	url := fmt.Sprintf("%s/health", c.baseURL)
@@ -548,11 +581,7 @@ func (c *QUICClient) Health(ctx context.Context) error {
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		body, err := io.ReadAll(resp.Body)
-		if err == nil && len(body) > 0 {
-			return fmt.Errorf("%w %d: %s", ErrServerError, resp.StatusCode, string(body))
-		}
-		return fmt.Errorf("%w %d", ErrServerError, resp.StatusCode)
+		return statusError(resp)
 	}
 
 	return nil
@@ -588,11 +617,7 @@ func (c *QUICClient) AddReplicationPolicy(ctx context.Context, policy common.Rep
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		body, err := io.ReadAll(resp.Body)
-		if err == nil && len(body) > 0 {
-			return fmt.Errorf("%w %d: %s", ErrServerError, resp.StatusCode, string(body))
-		}
-		return fmt.Errorf("%w %d", ErrServerError, resp.StatusCode)
+		return statusError(resp)
 	}
 
 	return nil
@@ -613,11 +638,7 @@ func (c *QUICClient) RemoveReplicationPolicy(ctx context.Context, policyID strin
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		body, err := io.ReadAll(resp.Body)
-		if err == nil && len(body) > 0 {
-			return fmt.Errorf("%w %d: %s", ErrServerError, resp.StatusCode, string(body))
-		}
-		return fmt.Errorf("%w %d", ErrServerError, resp.StatusCode)
+		return statusError(resp)
 	}
 
 	return nil
@@ -638,11 +659,7 @@ func (c *QUICClient) GetReplicationPolicy(ctx context.Context, policyID string)
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		body, err := io.ReadAll(resp.Body)
-		if err == nil && len(body) > 0 {
-			return nil, fmt.Errorf("%w %d: %s", ErrServerError, resp.StatusCode, string(body))
-		}
-		return nil, fmt.Errorf("%w %d", ErrServerError, resp.StatusCode)
+		return nil, statusError(resp)
 	}
 
 	var policy common.ReplicationPolicy
@@ -668,11 +685,7 @@ func (c *QUICClient) GetReplicationPolicies(ctx context.Context) ([]common.Repli
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		body, err := io.ReadAll(resp.Body)
-		if err == nil && len(body) > 0 {
-			return nil, fmt.Errorf("%w %d: %s", ErrServerError, resp.StatusCode, string(body))
-		}
-		return nil, fmt.Errorf("%w %d", ErrServerError, resp.StatusCode)
+		return nil, statusError(resp)
 	}
 
 	var policies []common.ReplicationPolicy
@@ -703,11 +716,7 @@ func (c *QUICClient) TriggerReplication(ctx context.Context, policyID string) (*
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		body, err := io.ReadAll(resp.Body)
-		if err == nil && len(body) > 0 {
-			return nil, fmt.Errorf("%w %d: %s", ErrServerError, resp.StatusCode, string(body))
-		}
-		return nil, fmt.Errorf("%w %d", ErrServerError, resp.StatusCode)
+		return nil, statusError(resp)
 	}
 
 	var result common.SyncResult
@@ -733,11 +742,7 @@ func (c *QUICClient) GetReplicationStatus(ctx context.Context, policyID string)
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		body, err := io.ReadAll(resp.Body)
-		if err == nil && len(body) > 0 {
-			return nil, fmt.Errorf("%w %d: %s", ErrServerError, resp.StatusCode, string(body))
-		}
-		return nil, fmt.Errorf("%w %d", ErrServerError, resp.StatusCode)
+		return nil, statusError(resp)
 	}
 
 	var status replication.ReplicationStatus
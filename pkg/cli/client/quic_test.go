@@ -15,6 +15,7 @@ package client
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -30,15 +31,20 @@ func TestQUICClient_Put(t *testing.T) {
 		if r.Method != http.MethodPut {
 			t.Errorf("expected PUT, got %s", r.Method)
 		}
+		w.Header().Set("ETag", "abc")
 		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"key":"test.txt","size":5}`))
 	}))
 	defer server.Close()
 
 	client := newQUICTestClient(t, server.URL)
 
-	err := client.Put(context.Background(), "test.txt", strings.NewReader("hello"), nil)
+	info, err := client.Put(context.Background(), "test.txt", strings.NewReader("hello"), nil)
 	if err != nil {
-		t.Errorf("Put failed: %v", err)
+		t.Fatalf("Put failed: %v", err)
+	}
+	if info.Metadata.ETag != "abc" || info.Metadata.Size != 5 {
+		t.Errorf("Put() = %+v", info.Metadata)
 	}
 }
 
@@ -50,8 +56,8 @@ func TestQUICClient_PutWithMetadata(t *testing.T) {
 		if ce := r.Header.Get("Content-Encoding"); ce != "gzip" {
 			t.Errorf("expected Content-Encoding gzip, got %s", ce)
 		}
-		if custom := r.Header.Get("X-Custom-Author"); custom != "test" {
-			t.Errorf("expected X-Custom-Author test, got %s", custom)
+		if custom := r.Header.Get("X-Meta-Author"); custom != "test" {
+			t.Errorf("expected X-Meta-Author test, got %s", custom)
 		}
 		w.WriteHeader(http.StatusCreated)
 	}))
@@ -65,7 +71,7 @@ func TestQUICClient_PutWithMetadata(t *testing.T) {
 		Custom:          map[string]string{"author": "test"},
 	}
 
-	err := client.Put(context.Background(), "test.txt", strings.NewReader("hello"), metadata)
+	_, err := client.Put(context.Background(), "test.txt", strings.NewReader("hello"), metadata)
 	if err != nil {
 		t.Errorf("Put with metadata failed: %v", err)
 	}
@@ -76,7 +82,7 @@ func TestQUICClient_Get(t *testing.T) {
 	server := newHTTP3TestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "text/plain")
 		w.Header().Set("ETag", "abc123")
-		w.Header().Set("X-Custom-Version", "1.0")
+		w.Header().Set("X-Meta-Version", "1.0")
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte(content))
 	}))
@@ -229,7 +235,7 @@ func TestQUICClient_GetMetadata(t *testing.T) {
 		w.Header().Set("Content-Length", "50")
 		w.Header().Set("Content-Encoding", "gzip")
 		w.Header().Set("ETag", "xyz789")
-		w.Header().Set("X-Custom-Author", "alice")
+		w.Header().Set("X-Meta-Author", "alice")
 		w.WriteHeader(http.StatusOK)
 	}))
 	defer server.Close()
@@ -267,14 +273,22 @@ func TestQUICClient_UpdateMetadata(t *testing.T) {
 		if r.Method != http.MethodPatch {
 			t.Errorf("expected PATCH, got %s", r.Method)
 		}
-		if ct := r.Header.Get("Content-Type"); ct != "text/plain" {
-			t.Errorf("expected Content-Type text/plain, got %s", ct)
+		var body struct {
+			ContentType     string            `json:"content_type"`
+			ContentEncoding string            `json:"content_encoding"`
+			Custom          map[string]string `json:"custom"`
 		}
-		if ce := r.Header.Get("Content-Encoding"); ce != "gzip" {
-			t.Errorf("expected Content-Encoding gzip, got %s", ce)
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("failed to decode body: %v", err)
+		}
+		if body.ContentType != "text/plain" {
+			t.Errorf("expected content_type text/plain, got %s", body.ContentType)
+		}
+		if body.ContentEncoding != "gzip" {
+			t.Errorf("expected content_encoding gzip, got %s", body.ContentEncoding)
 		}
-		if custom := r.Header.Get("X-Custom-Version"); custom != "2.0" {
-			t.Errorf("expected X-Custom-Version 2.0, got %s", custom)
+		if body.Custom["version"] != "2.0" {
+			t.Errorf("expected custom version 2.0, got %s", body.Custom["version"])
 		}
 		w.WriteHeader(http.StatusOK)
 	}))
@@ -424,7 +438,7 @@ func TestQUICClient_Put_Error(t *testing.T) {
 	defer server.Close()
 
 	client := newQUICTestClient(t, server.URL)
-	err := client.Put(context.Background(), "test.txt", strings.NewReader("data"), nil)
+	_, err := client.Put(context.Background(), "test.txt", strings.NewReader("data"), nil)
 	if err == nil {
 		t.Error("expected error on server failure")
 	}
@@ -1040,7 +1054,7 @@ func TestQUICClient_Put_ErrorWithBody(t *testing.T) {
 	defer server.Close()
 
 	client := newQUICTestClient(t, server.URL)
-	err := client.Put(context.Background(), "test.txt", strings.NewReader("data"), nil)
+	_, err := client.Put(context.Background(), "test.txt", strings.NewReader("data"), nil)
 	if err == nil || !strings.Contains(err.Error(), "internal server error") {
 		t.Errorf("expected error with body, got %v", err)
 	}
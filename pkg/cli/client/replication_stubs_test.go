@@ -159,7 +159,7 @@ func TestRESTClient_GetReplicationPoliciesSuccess(t *testing.T) {
 	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(`[{"id":"policy1","source_backend":"local","destination_backend":"local"}]`))
+		w.Write([]byte(`{"policies":[{"id":"policy1","source_backend":"local","destination_backend":"local"}],"count":1}`))
 	})
 	defer server.Close()
 
@@ -664,7 +664,7 @@ func TestRESTClient_Put_ErrorWithBody(t *testing.T) {
 	defer server.Close()
 
 	client := newRESTClient(server.URL)
-	err := client.Put(context.Background(), "test.txt", strings.NewReader("data"), nil)
+	_, err := client.Put(context.Background(), "test.txt", strings.NewReader("data"), nil)
 	if err == nil {
 		t.Error("Expected error, got nil")
 	}
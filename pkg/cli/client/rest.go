@@ -16,6 +16,7 @@ package client
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -25,8 +26,20 @@ import (
 	"strings"
 	"time"
 
+	"github.com/jeremyhahn/go-objstore/pkg/access"
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/alias"
 	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/cost"
+	"github.com/jeremyhahn/go-objstore/pkg/diff"
+	"github.com/jeremyhahn/go-objstore/pkg/events"
+	"github.com/jeremyhahn/go-objstore/pkg/jobs"
+	"github.com/jeremyhahn/go-objstore/pkg/overlay"
 	"github.com/jeremyhahn/go-objstore/pkg/replication"
+	"github.com/jeremyhahn/go-objstore/pkg/retention"
+	"github.com/jeremyhahn/go-objstore/pkg/search"
+	"github.com/jeremyhahn/go-objstore/pkg/share"
+	"github.com/jeremyhahn/go-objstore/pkg/tasks"
 )
 
 // RESTClient implements the Client interface for REST API servers
@@ -41,11 +54,16 @@ func NewRESTClient(config *Config) (*RESTClient, error) {
 		return nil, ErrServerURLRequired
 	}
 
-	httpClient := &http.Client{
-		Timeout: 30 * time.Second,
+	tlsConfig, err := clientTLSConfig(config, tls.VersionTLS12)
+	if err != nil {
+		return nil, err
 	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
 
-	// Note: TLS configuration can be added via http.Client customization
+	httpClient := &http.Client{
+		Transport: withRetry(config.Retry, withTimeout(requestTimeout(config), authTransport(config, transport))),
+	}
 
 	return &RESTClient{
 		baseURL:    strings.TrimSuffix(config.ServerURL, "/"),
@@ -53,19 +71,25 @@ func NewRESTClient(config *Config) (*RESTClient, error) {
 	}, nil
 }
 
-// Put uploads an object
-func (c *RESTClient) Put(ctx context.Context, key string, reader io.Reader, metadata *common.Metadata) error {
-	url := fmt.Sprintf("%s/api/v1/objects/%s", c.baseURL, key)
+// headerObjectMetadata carries custom metadata as a JSON object.
+const headerObjectMetadata = "X-Object-Metadata"
 
-	// Read all data
-	data, err := io.ReadAll(reader)
-	if err != nil {
-		return err
-	}
+// Put uploads an object and returns what the server stored.
+func (c *RESTClient) Put(ctx context.Context, key string, reader io.Reader, metadata *common.Metadata) (*common.ObjectInfo, error) {
+	return c.put(ctx, key, fmt.Sprintf("%s/api/v1/objects/%s", c.baseURL, key), reader, metadata)
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, bytes.NewReader(data))
+// PutAtomic stores an object so that readers never observe a partial
+// write: the server stages and verifies the upload before promoting it.
+func (c *RESTClient) PutAtomic(ctx context.Context, key string, reader io.Reader, metadata *common.Metadata) error {
+	_, err := c.put(ctx, key, fmt.Sprintf("%s/api/v1/objects/%s?atomic=true", c.baseURL, key), reader, metadata)
+	return err
+}
+
+func (c *RESTClient) put(ctx context.Context, key, url string, reader io.Reader, metadata *common.Metadata) (*common.ObjectInfo, error) {
+	req, err := newUploadRequest(ctx, url, reader)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Add metadata as headers if provided
@@ -76,27 +100,57 @@ func (c *RESTClient) Put(ctx context.Context, key string, reader io.Reader, meta
 		if metadata.ContentEncoding != "" {
 			req.Header.Set("Content-Encoding", metadata.ContentEncoding)
 		}
-		// Add custom metadata as X-Custom-* headers
-		for k, v := range metadata.Custom {
-			req.Header.Set(fmt.Sprintf("X-Custom-%s", k), v)
+		if metadata.StorageClass != "" {
+			req.Header.Set("X-Storage-Class", metadata.StorageClass)
+		}
+		// Custom metadata is carried as a JSON object in X-Object-Metadata.
+		if len(metadata.Custom) > 0 {
+			custom, err := json.Marshal(metadata.Custom)
+			if err != nil {
+				return nil, err
+			}
+			req.Header.Set(headerObjectMetadata, string(custom))
 		}
 	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		body, err := io.ReadAll(resp.Body)
-		if err == nil && len(body) > 0 {
-			return fmt.Errorf("%w %d: %s", ErrServerError, resp.StatusCode, string(body))
-		}
-		return fmt.Errorf("%w %d", ErrServerError, resp.StatusCode)
+		return nil, statusError(resp)
 	}
 
-	return nil
+	var result struct {
+		Data putResult `json:"data"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&result)
+	return result.Data.info(key, resp.Header), nil
+}
+
+// putResult describes the object written in REST and QUIC put responses.
+type putResult struct {
+	ETag         string    `json:"etag"`
+	Version      string    `json:"version"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+// info returns the written object. Servers that predate put results only
+// report the ETag, in the response header.
+func (r *putResult) info(key string, header http.Header) *common.ObjectInfo {
+	metadata := &common.Metadata{
+		ETag:         r.ETag,
+		Version:      r.Version,
+		Size:         r.Size,
+		LastModified: r.LastModified,
+	}
+	if metadata.ETag == "" {
+		metadata.ETag = header.Get("ETag")
+	}
+	return &common.ObjectInfo{Key: key, Metadata: metadata}
 }
 
 // Get retrieves an object
@@ -115,11 +169,7 @@ func (c *RESTClient) Get(ctx context.Context, key string) (io.ReadCloser, *commo
 
 	if resp.StatusCode != http.StatusOK {
 		defer func() { _ = resp.Body.Close() }()
-		body, err := io.ReadAll(resp.Body)
-		if err == nil && len(body) > 0 {
-			return nil, nil, fmt.Errorf("%w %d: %s", ErrServerError, resp.StatusCode, string(body))
-		}
-		return nil, nil, fmt.Errorf("%w %d", ErrServerError, resp.StatusCode)
+		return nil, nil, keyStatusError(key, resp)
 	}
 
 	// Extract metadata from headers
@@ -127,6 +177,7 @@ func (c *RESTClient) Get(ctx context.Context, key string) (io.ReadCloser, *commo
 		ContentType:     resp.Header.Get("Content-Type"),
 		ContentEncoding: resp.Header.Get("Content-Encoding"),
 		ETag:            resp.Header.Get("ETag"),
+		StorageClass:    resp.Header.Get("X-Storage-Class"),
 		Custom:          make(map[string]string),
 	}
 
@@ -136,13 +187,10 @@ func (c *RESTClient) Get(ctx context.Context, key string) (io.ReadCloser, *commo
 		}
 	}
 
-	// Extract custom metadata from X-Custom-* headers
-	for k, v := range resp.Header {
-		if strings.HasPrefix(k, "X-Custom-") {
-			customKey := strings.TrimPrefix(k, "X-Custom-")
-			if len(v) > 0 {
-				metadata.Custom[customKey] = v[0]
-			}
+	if custom := resp.Header.Get(headerObjectMetadata); custom != "" {
+		if err := json.Unmarshal([]byte(custom), &metadata.Custom); err != nil {
+			_ = resp.Body.Close()
+			return nil, nil, fmt.Errorf("invalid %s header: %w", headerObjectMetadata, err)
 		}
 	}
 
@@ -164,12 +212,17 @@ func (c *RESTClient) Delete(ctx context.Context, key string) error {
 	}
 	defer func() { _ = resp.Body.Close() }()
 
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		body, err := io.ReadAll(resp.Body)
-		if err == nil && len(body) > 0 {
-			return fmt.Errorf("%w %d: %s", ErrServerError, resp.StatusCode, string(body))
+	// A protected object is kept until the deletion request is approved.
+	if resp.StatusCode == http.StatusAccepted {
+		var pending retention.PendingError
+		if err := json.NewDecoder(resp.Body).Decode(&pending.Request); err != nil {
+			return err
 		}
-		return fmt.Errorf("%w %d", ErrServerError, resp.StatusCode)
+		return &pending
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return statusError(resp)
 	}
 
 	return nil
@@ -212,6 +265,12 @@ func (c *RESTClient) List(ctx context.Context, opts *common.ListOptions) (*commo
 		if opts.ContinueFrom != "" {
 			params.Set("continue_from", opts.ContinueFrom)
 		}
+		if opts.SortBy != "" {
+			params.Set("sort", string(opts.SortBy))
+			if opts.SortDescending {
+				params.Set("order", "desc")
+			}
+		}
 	}
 
 	if len(params) > 0 {
@@ -230,19 +289,144 @@ func (c *RESTClient) List(ctx context.Context, opts *common.ListOptions) (*commo
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		body, err := io.ReadAll(resp.Body)
-		if err == nil && len(body) > 0 {
-			return nil, fmt.Errorf("%w %d: %s", ErrServerError, resp.StatusCode, string(body))
+		return nil, statusError(resp)
+	}
+
+	var page restListResponse
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, err
+	}
+
+	result := page.ListResult
+	result.Objects = make([]*common.ObjectInfo, 0, len(page.Objects))
+	for _, obj := range page.Objects {
+		result.Objects = append(result.Objects, obj.objectInfo())
+	}
+	return &result, nil
+}
+
+// restListResponse is a page of objects as listed by the REST server.
+type restListResponse struct {
+	common.ListResult
+	Objects []restObject `json:"objects"`
+}
+
+// restObject is an object as listed by the REST server, which flattens its
+// metadata.
+type restObject struct {
+	Key          string            `json:"key"`
+	Size         int64             `json:"size"`
+	Modified     time.Time         `json:"modified"`
+	ETag         string            `json:"etag"`
+	ContentType  string            `json:"content_type"`
+	StorageClass string            `json:"storage_class"`
+	Metadata     map[string]string `json:"metadata"`
+}
+
+func (o *restObject) objectInfo() *common.ObjectInfo {
+	return &common.ObjectInfo{
+		Key: o.Key,
+		Metadata: &common.Metadata{
+			ContentType:  o.ContentType,
+			Size:         o.Size,
+			LastModified: o.Modified,
+			ETag:         o.ETag,
+			StorageClass: o.StorageClass,
+			Custom:       o.Metadata,
+		},
+	}
+}
+
+// Browse returns the folders and objects directly under prefix
+func (c *RESTClient) Browse(ctx context.Context, prefix string, opts *common.BrowseOptions) (*common.BrowseResult, error) {
+	params := url.Values{}
+	params.Set("prefix", prefix)
+	if opts != nil {
+		if opts.Delimiter != "" {
+			params.Set("delimiter", opts.Delimiter)
 		}
-		return nil, fmt.Errorf("%w %d", ErrServerError, resp.StatusCode)
+		if opts.Totals {
+			params.Set("totals", "true")
+		}
+	}
+
+	var resp struct {
+		Prefix       string          `json:"prefix"`
+		Folders      []common.Folder `json:"folders"`
+		Objects      []restObject    `json:"objects"`
+		TotalObjects int64           `json:"total_objects"`
+		TotalBytes   int64           `json:"total_bytes"`
+	}
+	if err := c.jsonRequest(ctx, http.MethodGet, "/api/v1/browse?"+params.Encode(), nil, &resp); err != nil {
+		return nil, err
+	}
+
+	result := &common.BrowseResult{
+		Prefix:       resp.Prefix,
+		Folders:      resp.Folders,
+		Objects:      make([]*common.ObjectInfo, 0, len(resp.Objects)),
+		TotalObjects: resp.TotalObjects,
+		TotalBytes:   resp.TotalBytes,
+	}
+	for _, obj := range resp.Objects {
+		result.Objects = append(result.Objects, obj.objectInfo())
 	}
+	return result, nil
+}
 
-	var result common.ListResult
+// Search finds objects whose key or metadata match query
+func (c *RESTClient) Search(ctx context.Context, query string, limit int) ([]search.Document, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+	urlStr := fmt.Sprintf("%s/api/v1/search?%s", c.baseURL, params.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, urlStr, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError(resp)
+	}
+
+	var result struct {
+		Objects []struct {
+			Key         string            `json:"key"`
+			Size        int64             `json:"size"`
+			Modified    string            `json:"modified"`
+			ETag        string            `json:"etag"`
+			ContentType string            `json:"content_type"`
+			Metadata    map[string]string `json:"metadata"`
+		} `json:"objects"`
+	}
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return nil, err
 	}
 
-	return &result, nil
+	docs := make([]search.Document, 0, len(result.Objects))
+	for _, obj := range result.Objects {
+		doc := search.Document{
+			Key:         obj.Key,
+			Size:        obj.Size,
+			ETag:        obj.ETag,
+			ContentType: obj.ContentType,
+			Custom:      obj.Metadata,
+		}
+		if obj.Modified != "" {
+			doc.LastModified, _ = time.Parse(time.RFC3339, obj.Modified)
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
 }
 
 // GetMetadata retrieves object metadata
@@ -261,17 +445,26 @@ func (c *RESTClient) GetMetadata(ctx context.Context, key string) (*common.Metad
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		body, err := io.ReadAll(resp.Body)
-		if err == nil && len(body) > 0 {
-			return nil, fmt.Errorf("%w %d: %s", ErrServerError, resp.StatusCode, string(body))
-		}
-		return nil, fmt.Errorf("%w %d", ErrServerError, resp.StatusCode)
+		return nil, keyStatusError(key, resp)
 	}
 
-	var metadata common.Metadata
-	if err := json.NewDecoder(resp.Body).Decode(&metadata); err != nil {
+	// The server returns custom metadata as "metadata" and the modification
+	// time as "modified".
+	var body struct {
+		common.Metadata
+		Modified string            `json:"modified"`
+		Fields   map[string]string `json:"metadata"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
 		return nil, err
 	}
+	metadata := body.Metadata
+	if len(body.Fields) > 0 {
+		metadata.Custom = body.Fields
+	}
+	if body.Modified != "" {
+		metadata.LastModified, _ = time.Parse(time.RFC3339, body.Modified)
+	}
 
 	return &metadata, nil
 }
@@ -298,16 +491,27 @@ func (c *RESTClient) UpdateMetadata(ctx context.Context, key string, metadata *c
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		body, err := io.ReadAll(resp.Body)
-		if err == nil && len(body) > 0 {
-			return fmt.Errorf("%w %d: %s", ErrServerError, resp.StatusCode, string(body))
-		}
-		return fmt.Errorf("%w %d", ErrServerError, resp.StatusCode)
+		return statusError(resp)
 	}
 
 	return nil
 }
 
+// UpdateMetadataBatch applies patch to the metadata of every object under
+// prefix and returns the number of objects changed
+func (c *RESTClient) UpdateMetadataBatch(ctx context.Context, prefix string, patch *common.MetadataPatch) (int, error) {
+	var resp struct {
+		Data struct {
+			Updated int `json:"updated"`
+		} `json:"data"`
+	}
+	path := "/api/v1/objects?prefix=" + url.QueryEscape(prefix)
+	if err := c.jsonRequest(ctx, http.MethodPatch, path, patch, &resp); err != nil {
+		return 0, err
+	}
+	return resp.Data.Updated, nil
+}
+
 // Archive archives an object
 func (c *RESTClient) Archive(ctx context.Context, key, destinationType string, destinationSettings map[string]string) error {
 	url := fmt.Sprintf("%s/api/v1/archive", c.baseURL)
@@ -336,11 +540,7 @@ func (c *RESTClient) Archive(ctx context.Context, key, destinationType string, d
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		body, err := io.ReadAll(resp.Body)
-		if err == nil && len(body) > 0 {
-			return fmt.Errorf("%w %d: %s", ErrServerError, resp.StatusCode, string(body))
-		}
-		return fmt.Errorf("%w %d", ErrServerError, resp.StatusCode)
+		return statusError(resp)
 	}
 
 	return nil
@@ -350,7 +550,20 @@ func (c *RESTClient) Archive(ctx context.Context, key, destinationType string, d
 func (c *RESTClient) AddPolicy(ctx context.Context, policy common.LifecyclePolicy) error {
 	url := fmt.Sprintf("%s/api/v1/policies", c.baseURL)
 
-	data, err := json.Marshal(policy)
+	// The server expects retention in seconds and snake_case field names.
+	data, err := json.Marshal(struct {
+		ID               string `json:"id"`
+		Prefix           string `json:"prefix,omitempty"`
+		RetentionSeconds int64  `json:"retention_seconds"`
+		Action           string `json:"action"`
+		StorageClass     string `json:"storage_class,omitempty"`
+	}{
+		ID:               policy.ID,
+		Prefix:           policy.Prefix,
+		RetentionSeconds: int64(policy.Retention.Seconds()),
+		Action:           policy.Action,
+		StorageClass:     policy.StorageClass,
+	})
 	if err != nil {
 		return err
 	}
@@ -368,11 +581,7 @@ func (c *RESTClient) AddPolicy(ctx context.Context, policy common.LifecyclePolic
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		body, err := io.ReadAll(resp.Body)
-		if err == nil && len(body) > 0 {
-			return fmt.Errorf("%w %d: %s", ErrServerError, resp.StatusCode, string(body))
-		}
-		return fmt.Errorf("%w %d", ErrServerError, resp.StatusCode)
+		return statusError(resp)
 	}
 
 	return nil
@@ -394,11 +603,7 @@ func (c *RESTClient) RemovePolicy(ctx context.Context, policyID string) error {
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		body, err := io.ReadAll(resp.Body)
-		if err == nil && len(body) > 0 {
-			return fmt.Errorf("%w %d: %s", ErrServerError, resp.StatusCode, string(body))
-		}
-		return fmt.Errorf("%w %d", ErrServerError, resp.StatusCode)
+		return statusError(resp)
 	}
 
 	return nil
@@ -420,11 +625,7 @@ func (c *RESTClient) GetPolicies(ctx context.Context) ([]common.LifecyclePolicy,
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		body, err := io.ReadAll(resp.Body)
-		if err == nil && len(body) > 0 {
-			return nil, fmt.Errorf("%w %d: %s", ErrServerError, resp.StatusCode, string(body))
-		}
-		return nil, fmt.Errorf("%w %d", ErrServerError, resp.StatusCode)
+		return nil, statusError(resp)
 	}
 
 	// The server wraps the list: {"policies": [...], "count": n} with
@@ -436,6 +637,7 @@ func (c *RESTClient) GetPolicies(ctx context.Context) ([]common.LifecyclePolicy,
 			RetentionSeconds int64  `json:"retention_seconds"`
 			Action           string `json:"action"`
 			DestinationType  string `json:"destination_type"`
+			StorageClass     string `json:"storage_class"`
 		} `json:"policies"`
 		Count int `json:"count"`
 	}
@@ -446,10 +648,11 @@ func (c *RESTClient) GetPolicies(ctx context.Context) ([]common.LifecyclePolicy,
 	policies := make([]common.LifecyclePolicy, 0, len(wrapped.Policies))
 	for _, p := range wrapped.Policies {
 		policies = append(policies, common.LifecyclePolicy{
-			ID:        p.ID,
-			Prefix:    p.Prefix,
-			Retention: time.Duration(p.RetentionSeconds) * time.Second,
-			Action:    p.Action,
+			ID:           p.ID,
+			Prefix:       p.Prefix,
+			Retention:    time.Duration(p.RetentionSeconds) * time.Second,
+			Action:       p.Action,
+			StorageClass: p.StorageClass,
 		})
 	}
 
@@ -472,11 +675,7 @@ func (c *RESTClient) ApplyPolicies(ctx context.Context) (policiesCount int, obje
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		body, err := io.ReadAll(resp.Body)
-		if err == nil && len(body) > 0 {
-			return 0, 0, fmt.Errorf("%w %d: %s", ErrServerError, resp.StatusCode, string(body))
-		}
-		return 0, 0, fmt.Errorf("%w %d", ErrServerError, resp.StatusCode)
+		return 0, 0, statusError(resp)
 	}
 
 	var result struct {
@@ -494,7 +693,7 @@ func (c *RESTClient) ApplyPolicies(ctx context.Context) (policiesCount int, obje
 func (c *RESTClient) AddReplicationPolicy(ctx context.Context, policy common.ReplicationPolicy) error {
 	url := fmt.Sprintf("%s/api/v1/replication/policies", c.baseURL)
 
-	data, err := json.Marshal(policy)
+	data, err := json.Marshal(newReplicationPolicyWire(policy))
 	if err != nil {
 		return err
 	}
@@ -512,11 +711,7 @@ func (c *RESTClient) AddReplicationPolicy(ctx context.Context, policy common.Rep
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		body, err := io.ReadAll(resp.Body)
-		if err == nil && len(body) > 0 {
-			return fmt.Errorf("%w %d: %s", ErrServerError, resp.StatusCode, string(body))
-		}
-		return fmt.Errorf("%w %d", ErrServerError, resp.StatusCode)
+		return statusError(resp)
 	}
 
 	return nil
@@ -538,11 +733,7 @@ func (c *RESTClient) RemoveReplicationPolicy(ctx context.Context, policyID strin
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		body, err := io.ReadAll(resp.Body)
-		if err == nil && len(body) > 0 {
-			return fmt.Errorf("%w %d: %s", ErrServerError, resp.StatusCode, string(body))
-		}
-		return fmt.Errorf("%w %d", ErrServerError, resp.StatusCode)
+		return statusError(resp)
 	}
 
 	return nil
@@ -564,18 +755,15 @@ func (c *RESTClient) GetReplicationPolicy(ctx context.Context, policyID string)
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		body, err := io.ReadAll(resp.Body)
-		if err == nil && len(body) > 0 {
-			return nil, fmt.Errorf("%w %d: %s", ErrServerError, resp.StatusCode, string(body))
-		}
-		return nil, fmt.Errorf("%w %d", ErrServerError, resp.StatusCode)
+		return nil, statusError(resp)
 	}
 
-	var policy common.ReplicationPolicy
-	if err := json.NewDecoder(resp.Body).Decode(&policy); err != nil {
+	var wire replicationPolicyWire
+	if err := json.NewDecoder(resp.Body).Decode(&wire); err != nil {
 		return nil, err
 	}
 
+	policy := wire.policy()
 	return &policy, nil
 }
 
@@ -595,21 +783,79 @@ func (c *RESTClient) GetReplicationPolicies(ctx context.Context) ([]common.Repli
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		body, err := io.ReadAll(resp.Body)
-		if err == nil && len(body) > 0 {
-			return nil, fmt.Errorf("%w %d: %s", ErrServerError, resp.StatusCode, string(body))
-		}
-		return nil, fmt.Errorf("%w %d", ErrServerError, resp.StatusCode)
+		return nil, statusError(resp)
 	}
 
-	var policies []common.ReplicationPolicy
-	if err := json.NewDecoder(resp.Body).Decode(&policies); err != nil {
+	// The server wraps the list: {"policies": [...], "count": n}
+	var wrapped struct {
+		Policies []replicationPolicyWire `json:"policies"`
+		Count    int                     `json:"count"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&wrapped); err != nil {
 		return nil, err
 	}
 
+	policies := make([]common.ReplicationPolicy, 0, len(wrapped.Policies))
+	for _, wire := range wrapped.Policies {
+		policies = append(policies, wire.policy())
+	}
 	return policies, nil
 }
 
+// replicationPolicyWire is a replication policy as the server sends and
+// accepts it: the check interval in seconds and the last sync time as
+// RFC 3339.
+type replicationPolicyWire struct {
+	ID                   string                   `json:"id"`
+	SourceBackend        string                   `json:"source_backend"`
+	SourceSettings       map[string]string        `json:"source_settings,omitempty"`
+	SourcePrefix         string                   `json:"source_prefix,omitempty"`
+	DestinationBackend   string                   `json:"destination_backend"`
+	DestinationSettings  map[string]string        `json:"destination_settings,omitempty"`
+	CheckIntervalSeconds int64                    `json:"check_interval_seconds"`
+	LastSyncTime         string                   `json:"last_sync_time,omitempty"`
+	Enabled              bool                     `json:"enabled"`
+	ReplicationMode      common.ReplicationMode   `json:"replication_mode,omitempty"`
+	Encryption           *common.EncryptionPolicy `json:"encryption,omitempty"`
+	WriteOnce            bool                     `json:"write_once,omitempty"`
+}
+
+func newReplicationPolicyWire(policy common.ReplicationPolicy) replicationPolicyWire {
+	return replicationPolicyWire{
+		ID:                   policy.ID,
+		SourceBackend:        policy.SourceBackend,
+		SourceSettings:       policy.SourceSettings,
+		SourcePrefix:         policy.SourcePrefix,
+		DestinationBackend:   policy.DestinationBackend,
+		DestinationSettings:  policy.DestinationSettings,
+		CheckIntervalSeconds: int64(policy.CheckInterval.Seconds()),
+		Enabled:              policy.Enabled,
+		ReplicationMode:      policy.ReplicationMode,
+		Encryption:           policy.Encryption,
+		WriteOnce:            policy.WriteOnce,
+	}
+}
+
+func (w replicationPolicyWire) policy() common.ReplicationPolicy {
+	policy := common.ReplicationPolicy{
+		ID:                  w.ID,
+		SourceBackend:       w.SourceBackend,
+		SourceSettings:      w.SourceSettings,
+		SourcePrefix:        w.SourcePrefix,
+		DestinationBackend:  w.DestinationBackend,
+		DestinationSettings: w.DestinationSettings,
+		CheckInterval:       time.Duration(w.CheckIntervalSeconds) * time.Second,
+		Enabled:             w.Enabled,
+		ReplicationMode:     w.ReplicationMode,
+		Encryption:          w.Encryption,
+		WriteOnce:           w.WriteOnce,
+	}
+	if w.LastSyncTime != "" {
+		policy.LastSyncTime, _ = time.Parse(time.RFC3339, w.LastSyncTime) //nolint:errcheck // zero when the server sends no valid time
+	}
+	return policy
+}
+
 // TriggerReplication triggers a replication sync
 func (c *RESTClient) TriggerReplication(ctx context.Context, policyID string) (*common.SyncResult, error) {
 	urlStr := fmt.Sprintf("%s/api/v1/replication/trigger", c.baseURL)
@@ -631,11 +877,7 @@ func (c *RESTClient) TriggerReplication(ctx context.Context, policyID string) (*
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		body, err := io.ReadAll(resp.Body)
-		if err == nil && len(body) > 0 {
-			return nil, fmt.Errorf("%w %d: %s", ErrServerError, resp.StatusCode, string(body))
-		}
-		return nil, fmt.Errorf("%w %d", ErrServerError, resp.StatusCode)
+		return nil, statusError(resp)
 	}
 
 	var result common.SyncResult
@@ -662,11 +904,7 @@ func (c *RESTClient) GetReplicationStatus(ctx context.Context, policyID string)
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		body, err := io.ReadAll(resp.Body)
-		if err == nil && len(body) > 0 {
-			return nil, fmt.Errorf("%w %d: %s", ErrServerError, resp.StatusCode, string(body))
-		}
-		return nil, fmt.Errorf("%w %d", ErrServerError, resp.StatusCode)
+		return nil, statusError(resp)
 	}
 
 	var status replication.ReplicationStatus
@@ -677,6 +915,402 @@ func (c *RESTClient) GetReplicationStatus(ctx context.Context, policyID string)
 	return &status, nil
 }
 
+// ListDeletionRequests lists deletion requests, all of them when status is
+// empty
+func (c *RESTClient) ListDeletionRequests(ctx context.Context, status retention.Status) ([]retention.DeletionRequest, error) {
+	path := "/api/v1/deletions"
+	if status != "" {
+		path += "?status=" + url.QueryEscape(string(status))
+	}
+	var result struct {
+		Requests []retention.DeletionRequest `json:"requests"`
+	}
+	if err := c.jsonRequest(ctx, http.MethodGet, path, nil, &result); err != nil {
+		return nil, err
+	}
+	return result.Requests, nil
+}
+
+// ApproveDeletion approves a pending deletion request
+func (c *RESTClient) ApproveDeletion(ctx context.Context, id string) (*retention.DeletionRequest, error) {
+	var req retention.DeletionRequest
+	if err := c.jsonRequest(ctx, http.MethodPost, "/api/v1/deletions/"+url.PathEscape(id)+"/approve", nil, &req); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+// RejectDeletion rejects a pending deletion request
+func (c *RESTClient) RejectDeletion(ctx context.Context, id, reason string) (*retention.DeletionRequest, error) {
+	var req retention.DeletionRequest
+	body := map[string]string{"reason": reason}
+	if err := c.jsonRequest(ctx, http.MethodPost, "/api/v1/deletions/"+url.PathEscape(id)+"/reject", body, &req); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+// ListLegalHolds lists the objects under legal hold
+func (c *RESTClient) ListLegalHolds(ctx context.Context) ([]retention.Hold, error) {
+	var result struct {
+		Holds []retention.Hold `json:"holds"`
+	}
+	if err := c.jsonRequest(ctx, http.MethodGet, "/api/v1/holds", nil, &result); err != nil {
+		return nil, err
+	}
+	return result.Holds, nil
+}
+
+// PlaceLegalHold places a legal hold on an object
+func (c *RESTClient) PlaceLegalHold(ctx context.Context, key, reason string) (*retention.Hold, error) {
+	var hold retention.Hold
+	body := map[string]string{"reason": reason}
+	if err := c.jsonRequest(ctx, http.MethodPut, "/api/v1/holds/"+key, body, &hold); err != nil {
+		return nil, err
+	}
+	return &hold, nil
+}
+
+// ReleaseLegalHold releases the legal hold on an object
+func (c *RESTClient) ReleaseLegalHold(ctx context.Context, key string) error {
+	return c.jsonRequest(ctx, http.MethodDelete, "/api/v1/holds/"+key, nil, nil)
+}
+
+// CreateShare creates a share link to key and returns it with its download
+// URL
+func (c *RESTClient) CreateShare(ctx context.Context, key string, opts share.Options) (*share.Link, string, error) {
+	body := map[string]any{
+		"ttl_seconds":      int64(opts.TTL / time.Second),
+		"max_downloads":    opts.MaxDownloads,
+		"bytes_per_second": opts.BytesPerSecond,
+	}
+	if opts.Password != "" {
+		body["password"] = opts.Password
+	}
+	var created struct {
+		share.Link
+		URL string `json:"url"`
+	}
+	if err := c.jsonRequest(ctx, http.MethodPost, "/api/v1/shares/objects/"+key, body, &created); err != nil {
+		return nil, "", err
+	}
+	return &created.Link, created.URL, nil
+}
+
+// ListShares lists the server's share links with their download accounting
+func (c *RESTClient) ListShares(ctx context.Context) ([]share.Link, error) {
+	var result struct {
+		Shares []share.Link `json:"shares"`
+	}
+	if err := c.jsonRequest(ctx, http.MethodGet, "/api/v1/shares", nil, &result); err != nil {
+		return nil, err
+	}
+	return result.Shares, nil
+}
+
+// RevokeShare revokes a share link
+func (c *RESTClient) RevokeShare(ctx context.Context, id string) error {
+	return c.jsonRequest(ctx, http.MethodDelete, "/api/v1/shares/"+url.PathEscape(id), nil, nil)
+}
+
+// SetAlias points an alias at an object
+func (c *RESTClient) SetAlias(ctx context.Context, name, target string) (*alias.Alias, error) {
+	var a alias.Alias
+	body := map[string]string{"target": target}
+	if err := c.jsonRequest(ctx, http.MethodPut, "/api/v1/aliases/"+name, body, &a); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// ResolveAlias returns an alias with the key of its target
+func (c *RESTClient) ResolveAlias(ctx context.Context, name string) (*alias.Alias, error) {
+	var a alias.Alias
+	if err := c.jsonRequest(ctx, http.MethodGet, "/api/v1/aliases/"+name, nil, &a); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// ListAliases lists the aliases whose names start with prefix
+func (c *RESTClient) ListAliases(ctx context.Context, prefix string) ([]alias.Alias, error) {
+	path := "/api/v1/aliases"
+	if prefix != "" {
+		path += "?prefix=" + url.QueryEscape(prefix)
+	}
+	var result struct {
+		Aliases []alias.Alias `json:"aliases"`
+	}
+	if err := c.jsonRequest(ctx, http.MethodGet, path, nil, &result); err != nil {
+		return nil, err
+	}
+	return result.Aliases, nil
+}
+
+// DeleteAlias removes an alias
+func (c *RESTClient) DeleteAlias(ctx context.Context, name string) error {
+	return c.jsonRequest(ctx, http.MethodDelete, "/api/v1/aliases/"+name, nil, nil)
+}
+
+// quota is a quota as the REST API encodes it.
+type quota struct {
+	Prefix     string `json:"prefix,omitempty"`
+	MaxBytes   int64  `json:"max_bytes,omitempty"`
+	MaxObjects int64  `json:"max_objects,omitempty"`
+}
+
+// ListQuotas returns the quotas of the server's key prefixes
+func (c *RESTClient) ListQuotas(ctx context.Context) (map[string]overlay.Quota, error) {
+	var result struct {
+		Quotas []quota `json:"quotas"`
+	}
+	if err := c.jsonRequest(ctx, http.MethodGet, "/api/v1/quotas", nil, &result); err != nil {
+		return nil, err
+	}
+	quotas := make(map[string]overlay.Quota, len(result.Quotas))
+	for _, q := range result.Quotas {
+		quotas[q.Prefix] = overlay.Quota{MaxBytes: q.MaxBytes, MaxObjects: q.MaxObjects}
+	}
+	return quotas, nil
+}
+
+// SetQuota limits the data stored under prefix
+func (c *RESTClient) SetQuota(ctx context.Context, prefix string, limits overlay.Quota) error {
+	body := quota{MaxBytes: limits.MaxBytes, MaxObjects: limits.MaxObjects}
+	return c.jsonRequest(ctx, http.MethodPut, "/api/v1/quotas/"+prefix, body, nil)
+}
+
+// RemoveQuota removes the quota of prefix
+func (c *RESTClient) RemoveQuota(ctx context.Context, prefix string) error {
+	return c.jsonRequest(ctx, http.MethodDelete, "/api/v1/quotas/"+prefix, nil, nil)
+}
+
+// ListWebhooks returns the server's event notification rules
+func (c *RESTClient) ListWebhooks(ctx context.Context) ([]events.Rule, error) {
+	var result struct {
+		Webhooks []events.Rule `json:"webhooks"`
+	}
+	if err := c.jsonRequest(ctx, http.MethodGet, "/api/v1/webhooks", nil, &result); err != nil {
+		return nil, err
+	}
+	return result.Webhooks, nil
+}
+
+// SetWebhook creates or replaces the event notification rule with the ID
+// of rule
+func (c *RESTClient) SetWebhook(ctx context.Context, rule events.Rule) error {
+	return c.jsonRequest(ctx, http.MethodPut, "/api/v1/webhooks/"+url.PathEscape(rule.ID), rule, nil)
+}
+
+// DeleteWebhook removes an event notification rule
+func (c *RESTClient) DeleteWebhook(ctx context.Context, id string) error {
+	return c.jsonRequest(ctx, http.MethodDelete, "/api/v1/webhooks/"+url.PathEscape(id), nil, nil)
+}
+
+// ListAccessKeys returns the SigV4 access keys issued by the server,
+// without their secrets
+func (c *RESTClient) ListAccessKeys(ctx context.Context) ([]adapters.AccessKey, error) {
+	var result struct {
+		AccessKeys []adapters.AccessKey `json:"access_keys"`
+	}
+	if err := c.jsonRequest(ctx, http.MethodGet, "/api/v1/access-keys", nil, &result); err != nil {
+		return nil, err
+	}
+	return result.AccessKeys, nil
+}
+
+// CreateAccessKey issues an access key for the principal and roles of key.
+// The returned key carries the secret, which the server does not return
+// again.
+func (c *RESTClient) CreateAccessKey(ctx context.Context, key adapters.AccessKey) (*adapters.AccessKey, error) {
+	body := struct {
+		PrincipalID string   `json:"principal_id"`
+		Roles       []string `json:"roles,omitempty"`
+		Description string   `json:"description,omitempty"`
+	}{key.PrincipalID, key.Roles, key.Description}
+	var created adapters.AccessKey
+	if err := c.jsonRequest(ctx, http.MethodPost, "/api/v1/access-keys", body, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// GetAccessKey returns an issued access key without its secret
+func (c *RESTClient) GetAccessKey(ctx context.Context, id string) (*adapters.AccessKey, error) {
+	var key adapters.AccessKey
+	if err := c.jsonRequest(ctx, http.MethodGet, "/api/v1/access-keys/"+url.PathEscape(id), nil, &key); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// DeleteAccessKey revokes an access key
+func (c *RESTClient) DeleteAccessKey(ctx context.Context, id string) error {
+	return c.jsonRequest(ctx, http.MethodDelete, "/api/v1/access-keys/"+url.PathEscape(id), nil, nil)
+}
+
+// jsonRequest sends an API request with an optional JSON body and decodes
+// a successful JSON response into out, if non-nil.
+func (c *RESTClient) jsonRequest(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader = http.NoBody
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusNoContent {
+		return statusError(resp)
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// Analyze returns the age and access distribution of the objects under
+// prefix. Only opts.Depth and opts.Buckets are sent; the server measures
+// ages from its own clock
+func (c *RESTClient) Analyze(ctx context.Context, prefix string, opts *access.AnalyzeOptions) (*access.Report, error) {
+	query := url.Values{}
+	if prefix != "" {
+		query.Set("prefix", prefix)
+	}
+	if opts != nil && opts.Depth != 0 {
+		query.Set("depth", strconv.Itoa(opts.Depth))
+	}
+	if opts != nil && len(opts.Buckets) > 0 {
+		bounds := make([]string, len(opts.Buckets))
+		for i, bound := range opts.Buckets {
+			bounds[i] = bound.String()
+		}
+		query.Set("buckets", strings.Join(bounds, ","))
+	}
+	path := "/api/v1/analyze"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	var report access.Report
+	if err := c.jsonRequest(ctx, http.MethodGet, path, nil, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// CostReport returns the server's cost estimate for month (YYYY-MM), the
+// current month when empty
+func (c *RESTClient) CostReport(ctx context.Context, month string) (*cost.Report, error) {
+	path := "/api/v1/cost"
+	if month != "" {
+		path += "?" + url.Values{"month": {month}}.Encode()
+	}
+	var report cost.Report
+	if err := c.jsonRequest(ctx, http.MethodGet, path, nil, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// ListJobs returns the status of the server's background jobs
+func (c *RESTClient) ListJobs(ctx context.Context) ([]jobs.Status, error) {
+	var resp struct {
+		Jobs []jobs.Status `json:"jobs"`
+	}
+	if err := c.jsonRequest(ctx, http.MethodGet, "/api/v1/jobs", nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Jobs, nil
+}
+
+// GetJob returns the status of a background job with its run history
+func (c *RESTClient) GetJob(ctx context.Context, name string) (*jobs.Status, error) {
+	var status jobs.Status
+	if err := c.jsonRequest(ctx, http.MethodGet, "/api/v1/jobs/"+url.PathEscape(name), nil, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// ListTasks returns the server's queued tasks, optionally filtered by state
+func (c *RESTClient) ListTasks(ctx context.Context, state tasks.State) ([]tasks.Task, error) {
+	path := "/api/v1/tasks"
+	if state != "" {
+		path += "?state=" + url.QueryEscape(string(state))
+	}
+	var resp struct {
+		Tasks []tasks.Task `json:"tasks"`
+	}
+	if err := c.jsonRequest(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Tasks, nil
+}
+
+// RetryTask requeues a dead-lettered task
+func (c *RESTClient) RetryTask(ctx context.Context, id string) (*tasks.Task, error) {
+	var task tasks.Task
+	if err := c.jsonRequest(ctx, http.MethodPost, "/api/v1/tasks/"+url.PathEscape(id)+"/retry", nil, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// Diff compares two objects on the server. Each reference is a key,
+// optionally followed by @ and an RFC 3339 time.
+func (c *RESTClient) Diff(ctx context.Context, refA, refB string, opts diff.Options) (*diff.Result, error) {
+	query := url.Values{"a": {refA}, "b": {refB}}
+	if opts.Mode != diff.ModeAuto {
+		query.Set("mode", string(opts.Mode))
+	}
+	if opts.Context > 0 {
+		query.Set("context", strconv.Itoa(opts.Context))
+	}
+	var result diff.Result
+	if err := c.jsonRequest(ctx, http.MethodGet, "/api/v1/diff?"+query.Encode(), nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// RenamePrefix moves every object under prefix to newPrefix on the server.
+// opts.Progress is not called; the server only reports the totals.
+func (c *RESTClient) RenamePrefix(ctx context.Context, prefix, newPrefix string, opts *common.RenameOptions) (*common.RenameResult, error) {
+	query := url.Values{"prefix": {prefix}, "new_prefix": {newPrefix}}
+	if opts != nil {
+		if opts.DryRun {
+			query.Set("dry_run", "true")
+		}
+		if opts.Overwrite {
+			query.Set("overwrite", "true")
+		}
+		if opts.Concurrency > 0 {
+			query.Set("concurrency", strconv.Itoa(opts.Concurrency))
+		}
+	}
+	var result common.RenameResult
+	if err := c.jsonRequest(ctx, http.MethodPost, "/api/v1/rename?"+query.Encode(), nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
 // Health checks server health
 func (c *RESTClient) Health(ctx context.Context) error {
 	url := fmt.Sprintf("%s/health", c.baseURL)
@@ -693,11 +1327,7 @@ func (c *RESTClient) Health(ctx context.Context) error {
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		body, err := io.ReadAll(resp.Body)
-		if err == nil && len(body) > 0 {
-			return fmt.Errorf("%w %d: %s", ErrServerError, resp.StatusCode, string(body))
-		}
-		return fmt.Errorf("%w %d", ErrServerError, resp.StatusCode)
+		return statusError(resp)
 	}
 
 	return nil
@@ -708,3 +1338,10 @@ func (c *RESTClient) Close() error {
 	// HTTP client doesn't need explicit closing
 	return nil
 }
+
+// keyStatusError returns the error for a failed request on key, wrapping
+// the canonical sentinel for its status (common.ErrKeyNotFound for a 404)
+// so callers can test it with errors.Is.
+func keyStatusError(key string, resp *http.Response) error {
+	return common.ProviderError(common.ErrorForStatus(resp.StatusCode), key, statusError(resp))
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/retention"
+)
+
+func TestRESTClient_Retention(t *testing.T) {
+	pending := `{"id":"req-1","key":"records/a.csv","status":"pending","requested_by":"alice","requested_at":"2025-11-05T10:00:00Z"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := r.Method + " " + r.URL.RequestURI()
+		switch route {
+		case "DELETE /api/v1/objects/records/a.csv":
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = io.WriteString(w, pending)
+		case "GET /api/v1/deletions?status=pending":
+			_, _ = io.WriteString(w, `{"requests":[`+pending+`],"count":1}`)
+		case "POST /api/v1/deletions/req-1/approve":
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = io.WriteString(w, `{"error":"permission denied"}`)
+		case "POST /api/v1/deletions/req-1/reject":
+			var body map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			_, _ = io.WriteString(w, `{"id":"req-1","key":"records/a.csv","status":"rejected","requested_at":"2025-11-05T10:00:00Z","reason":"`+body["reason"]+`"}`)
+		case "PUT /api/v1/holds/records/a.csv":
+			_, _ = io.WriteString(w, `{"key":"records/a.csv","placed_by":"carol","placed_at":"2025-11-05T10:00:00Z"}`)
+		case "GET /api/v1/holds":
+			_, _ = io.WriteString(w, `{"holds":[{"key":"records/a.csv","placed_at":"2025-11-05T10:00:00Z"}],"count":1}`)
+		case "DELETE /api/v1/holds/records/a.csv":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s", route)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClient(&Config{ServerURL: server.URL})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	var queued *retention.PendingError
+	if err := client.Delete(ctx, "records/a.csv"); !errors.As(err, &queued) || queued.Request.ID != "req-1" {
+		t.Fatalf("Delete() error = %v, want pending request req-1", err)
+	}
+	requests, err := client.ListDeletionRequests(ctx, retention.StatusPending)
+	if err != nil || len(requests) != 1 || requests[0].RequestedBy != "alice" {
+		t.Errorf("ListDeletionRequests() = %+v, %v", requests, err)
+	}
+	if _, err := client.ApproveDeletion(ctx, "req-1"); err == nil {
+		t.Error("ApproveDeletion() succeeded on 403")
+	}
+	rejected, err := client.RejectDeletion(ctx, "req-1", "keep")
+	if err != nil || rejected.Status != retention.StatusRejected || rejected.Reason != "keep" {
+		t.Errorf("RejectDeletion() = %+v, %v", rejected, err)
+	}
+	hold, err := client.PlaceLegalHold(ctx, "records/a.csv", "")
+	if err != nil || hold.PlacedBy != "carol" {
+		t.Errorf("PlaceLegalHold() = %+v, %v", hold, err)
+	}
+	holds, err := client.ListLegalHolds(ctx)
+	if err != nil || len(holds) != 1 {
+		t.Errorf("ListLegalHolds() = %+v, %v", holds, err)
+	}
+	if err := client.ReleaseLegalHold(ctx, "records/a.csv"); err != nil {
+		t.Errorf("ReleaseLegalHold() error = %v", err)
+	}
+}
@@ -23,7 +23,14 @@ import (
 	"testing"
 	"time"
 
+	"github.com/jeremyhahn/go-objstore/pkg/access"
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
 	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/events"
+	"github.com/jeremyhahn/go-objstore/pkg/jobs"
+	"github.com/jeremyhahn/go-objstore/pkg/overlay"
+	"github.com/jeremyhahn/go-objstore/pkg/share"
+	"github.com/jeremyhahn/go-objstore/pkg/tasks"
 )
 
 func TestRESTClient_Put(t *testing.T) {
@@ -35,6 +42,7 @@ func TestRESTClient_Put(t *testing.T) {
 			t.Errorf("unexpected path: %s", r.URL.Path)
 		}
 		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"message":"object uploaded successfully","data":{"key":"test.txt","etag":"abc","version":"v1","size":5,"last_modified":"2025-01-02T03:04:05Z"}}`))
 	}))
 	defer server.Close()
 
@@ -43,9 +51,33 @@ func TestRESTClient_Put(t *testing.T) {
 		t.Fatalf("failed to create client: %v", err)
 	}
 
-	err = client.Put(context.Background(), "test.txt", strings.NewReader("hello"), nil)
+	info, err := client.Put(context.Background(), "test.txt", strings.NewReader("hello"), nil)
 	if err != nil {
-		t.Errorf("Put failed: %v", err)
+		t.Fatalf("Put failed: %v", err)
+	}
+	written := info.Metadata
+	if info.Key != "test.txt" || written.ETag != "abc" || written.Version != "v1" || written.Size != 5 ||
+		!written.LastModified.Equal(time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("Put() = %+v, %+v", info, written)
+	}
+}
+
+func TestRESTClient_PutAtomic(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/objects/test.txt" || r.URL.Query().Get("atomic") != "true" {
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClient(&Config{ServerURL: server.URL})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	if err := client.PutAtomic(context.Background(), "test.txt", strings.NewReader("hello"), nil); err != nil {
+		t.Errorf("PutAtomic failed: %v", err)
 	}
 }
 
@@ -54,8 +86,8 @@ func TestRESTClient_PutWithMetadata(t *testing.T) {
 		if ct := r.Header.Get("Content-Type"); ct != "text/plain" {
 			t.Errorf("expected Content-Type text/plain, got %s", ct)
 		}
-		if custom := r.Header.Get("X-Custom-Author"); custom != "test" {
-			t.Errorf("expected X-Custom-Author test, got %s", custom)
+		if custom := r.Header.Get("X-Object-Metadata"); custom != `{"author":"test"}` {
+			t.Errorf("expected X-Object-Metadata {\"author\":\"test\"}, got %s", custom)
 		}
 		w.WriteHeader(http.StatusCreated)
 	}))
@@ -71,7 +103,7 @@ func TestRESTClient_PutWithMetadata(t *testing.T) {
 		Custom:      map[string]string{"author": "test"},
 	}
 
-	err = client.Put(context.Background(), "test.txt", strings.NewReader("hello"), metadata)
+	_, err = client.Put(context.Background(), "test.txt", strings.NewReader("hello"), metadata)
 	if err != nil {
 		t.Errorf("Put with metadata failed: %v", err)
 	}
@@ -84,7 +116,7 @@ func TestRESTClient_Get(t *testing.T) {
 			t.Errorf("expected GET, got %s", r.Method)
 		}
 		w.Header().Set("Content-Type", "text/plain")
-		w.Header().Set("X-Custom-Version", "1.0")
+		w.Header().Set("X-Object-Metadata", `{"Version":"1.0"}`)
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte(content))
 	}))
@@ -203,6 +235,35 @@ func TestRESTClient_List(t *testing.T) {
 	}
 }
 
+func TestRESTClient_ListMetadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("sort") != "size" || r.URL.Query().Get("order") != "desc" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"objects":[{"key":"a.txt","size":12,"modified":"2025-11-05T10:00:00Z","etag":"abc","content_type":"text/plain","storage_class":"COLD","metadata":{"owner":"ops"}}],"next_token":"n","truncated":true}`))
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClient(&Config{ServerURL: server.URL})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	result, err := client.List(context.Background(), &common.ListOptions{SortBy: common.ListSortSize, SortDescending: true})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(result.Objects) != 1 || !result.Truncated {
+		t.Fatalf("List() = %+v", result)
+	}
+	metadata := result.Objects[0].Metadata
+	if metadata.Size != 12 || metadata.ContentType != "text/plain" || metadata.StorageClass != "COLD" ||
+		metadata.ETag != "abc" || metadata.Custom["owner"] != "ops" ||
+		!metadata.LastModified.Equal(time.Date(2025, 11, 5, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("List() metadata = %+v", metadata)
+	}
+}
+
 func TestRESTClient_GetMetadata(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
@@ -210,7 +271,7 @@ func TestRESTClient_GetMetadata(t *testing.T) {
 		}
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(`{"content_type":"text/plain","size":100}`))
+		w.Write([]byte(`{"key":"test.txt","content_type":"text/plain","size":100,"modified":"2025-11-05T10:00:00Z","metadata":{"author":"alice"}}`))
 	}))
 	defer server.Close()
 
@@ -231,6 +292,14 @@ func TestRESTClient_GetMetadata(t *testing.T) {
 	if metadata.Size != 100 {
 		t.Errorf("expected size 100, got %d", metadata.Size)
 	}
+
+	if metadata.Custom["author"] != "alice" {
+		t.Errorf("expected author alice, got %s", metadata.Custom["author"])
+	}
+
+	if want := time.Date(2025, 11, 5, 10, 0, 0, 0, time.UTC); !metadata.LastModified.Equal(want) {
+		t.Errorf("expected last modified %v, got %v", want, metadata.LastModified)
+	}
 }
 
 func TestRESTClient_UpdateMetadata(t *testing.T) {
@@ -254,6 +323,85 @@ func TestRESTClient_UpdateMetadata(t *testing.T) {
 	}
 }
 
+func TestRESTClient_UpdateMetadataBatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch || r.URL.Path != "/api/v1/objects" || r.URL.Query().Get("prefix") != "logs/2025 q1/" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		var patch common.MetadataPatch
+		if err := json.NewDecoder(r.Body).Decode(&patch); err != nil || patch.Set["tier"] != "cold" || patch.Remove[0] != "draft" {
+			t.Errorf("unexpected patch %+v, %v", patch, err)
+		}
+		_, _ = w.Write([]byte(`{"message":"metadata updated successfully","data":{"updated":7}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClient(&Config{ServerURL: server.URL})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	updated, err := client.UpdateMetadataBatch(context.Background(), "logs/2025 q1/", &common.MetadataPatch{
+		Set:    map[string]string{"tier": "cold"},
+		Remove: []string{"draft"},
+	})
+	if err != nil || updated != 7 {
+		t.Errorf("UpdateMetadataBatch() = %d, %v, want 7", updated, err)
+	}
+}
+
+func TestRESTClient_RenamePrefix(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/rename" || query.Get("prefix") != "logs/2025 q1/" ||
+			query.Get("new_prefix") != "archive/" || query.Get("dry_run") != "true" || query.Get("concurrency") != "4" || query.Has("overwrite") {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		_, _ = w.Write([]byte(`{"moved":5,"skipped":1,"failed":0,"dry_run":true}`))
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClient(&Config{ServerURL: server.URL})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	result, err := client.RenamePrefix(context.Background(), "logs/2025 q1/", "archive/", &common.RenameOptions{DryRun: true, Concurrency: 4})
+	if err != nil || result.Moved != 5 || result.Skipped != 1 || !result.DryRun {
+		t.Errorf("RenamePrefix() = %+v, %v", result, err)
+	}
+}
+
+func TestRESTClient_Browse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if r.Method != http.MethodGet || r.URL.Path != "/api/v1/browse" || query.Get("prefix") != "logs/" || query.Get("totals") != "true" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		_, _ = w.Write([]byte(`{"prefix":"logs/","folders":[{"prefix":"logs/2025/","objects":3,"bytes":300}],` +
+			`"objects":[{"key":"logs/a.log","size":10,"modified":"2025-01-02T03:04:05Z","content_type":"text/plain"}],` +
+			`"total_objects":4,"total_bytes":310}`))
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClient(&Config{ServerURL: server.URL})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	result, err := client.Browse(context.Background(), "logs/", &common.BrowseOptions{Totals: true})
+	if err != nil {
+		t.Fatalf("Browse() error = %v", err)
+	}
+	if len(result.Folders) != 1 || result.Folders[0].Objects != 3 || result.Folders[0].Bytes != 300 {
+		t.Errorf("Browse() folders = %+v", result.Folders)
+	}
+	if len(result.Objects) != 1 || result.Objects[0].Metadata.Size != 10 || result.Objects[0].Metadata.ContentType != "text/plain" ||
+		result.Objects[0].Metadata.LastModified.IsZero() {
+		t.Errorf("Browse() objects = %+v", result.Objects)
+	}
+	if result.TotalObjects != 4 || result.TotalBytes != 310 {
+		t.Errorf("Browse() totals = %d, %d", result.TotalObjects, result.TotalBytes)
+	}
+}
+
 func TestRESTClient_Archive(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
@@ -387,7 +535,7 @@ func TestRESTClient_Errors(t *testing.T) {
 		t.Fatalf("failed to create client: %v", err)
 	}
 
-	err = client.Put(context.Background(), "test.txt", strings.NewReader("data"), nil)
+	_, err = client.Put(context.Background(), "test.txt", strings.NewReader("data"), nil)
 	if err == nil {
 		t.Error("expected error on server failure")
 	}
@@ -427,7 +575,7 @@ func TestRESTClient_Put_Error(t *testing.T) {
 	defer server.Close()
 
 	client, _ := NewRESTClient(&Config{ServerURL: server.URL})
-	err := client.Put(context.Background(), "test.txt", strings.NewReader("data"), nil)
+	_, err := client.Put(context.Background(), "test.txt", strings.NewReader("data"), nil)
 	if err == nil {
 		t.Error("expected error on server failure")
 	}
@@ -690,7 +838,7 @@ func TestRESTClient_Put_WithContentEncoding(t *testing.T) {
 	metadata := &common.Metadata{
 		ContentEncoding: "gzip",
 	}
-	err := client.Put(context.Background(), "test.txt", strings.NewReader("data"), metadata)
+	_, err := client.Put(context.Background(), "test.txt", strings.NewReader("data"), metadata)
 	if err != nil {
 		t.Errorf("Put failed: %v", err)
 	}
@@ -835,7 +983,7 @@ func TestRESTClient_Put_StatusOK(t *testing.T) {
 	defer server.Close()
 
 	client, _ := NewRESTClient(&Config{ServerURL: server.URL})
-	err := client.Put(context.Background(), "test.txt", strings.NewReader("data"), nil)
+	_, err := client.Put(context.Background(), "test.txt", strings.NewReader("data"), nil)
 	if err != nil {
 		t.Errorf("Put with StatusOK failed: %v", err)
 	}
@@ -848,7 +996,7 @@ func TestQUICClient_Put_StatusOK(t *testing.T) {
 	defer server.Close()
 
 	client := newQUICTestClient(t, server.URL)
-	err := client.Put(context.Background(), "test.txt", strings.NewReader("data"), nil)
+	_, err := client.Put(context.Background(), "test.txt", strings.NewReader("data"), nil)
 	if err != nil {
 		t.Errorf("Put with StatusOK failed: %v", err)
 	}
@@ -1028,7 +1176,7 @@ func TestRESTClient_Put_ReadError(t *testing.T) {
 
 	// Create a reader that errors
 	reader := &errorReader{}
-	err := client.Put(context.Background(), "test.txt", reader, nil)
+	_, err := client.Put(context.Background(), "test.txt", reader, nil)
 	if err == nil {
 		t.Error("expected error from reader failure")
 	}
@@ -1044,7 +1192,7 @@ func TestQUICClient_Put_ReadError(t *testing.T) {
 
 	// Create a reader that errors
 	reader := &errorReader{}
-	err := client.Put(context.Background(), "test.txt", reader, nil)
+	_, err := client.Put(context.Background(), "test.txt", reader, nil)
 	if err == nil {
 		t.Error("expected error from reader failure")
 	}
@@ -1184,10 +1332,10 @@ func TestRESTClient_GetReplicationPolicies(t *testing.T) {
 		}
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(`[
-			{"id":"policy1","source_backend":"local","destination_backend":"s3"},
+		w.Write([]byte(`{"policies":[
+			{"id":"policy1","source_backend":"local","destination_backend":"s3","check_interval_seconds":300},
 			{"id":"policy2","source_backend":"s3","destination_backend":"local"}
-		]`))
+		],"count":2}`))
 	}))
 	defer server.Close()
 
@@ -1199,8 +1347,26 @@ func TestRESTClient_GetReplicationPolicies(t *testing.T) {
 	if len(policies) != 2 {
 		t.Errorf("expected 2 policies, got %d", len(policies))
 	}
-	if policies[0].ID != "policy1" {
-		t.Errorf("expected policy1, got %s", policies[0].ID)
+	if policies[0].ID != "policy1" || policies[0].CheckInterval != 5*time.Minute {
+		t.Errorf("expected policy1 checked every 5m, got %+v", policies[0])
+	}
+}
+
+func TestRESTClient_AddReplicationPolicy_WireFormat(t *testing.T) {
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client, _ := NewRESTClient(&Config{ServerURL: server.URL})
+	policy := common.ReplicationPolicy{ID: "p", SourceBackend: "local", DestinationBackend: "s3", CheckInterval: time.Minute}
+	if err := client.AddReplicationPolicy(context.Background(), policy); err != nil {
+		t.Fatalf("AddReplicationPolicy failed: %v", err)
+	}
+	if body["check_interval_seconds"] != float64(60) {
+		t.Errorf("request body = %v, want check_interval_seconds 60", body)
 	}
 }
 
@@ -1341,7 +1507,7 @@ func TestRESTClient_RemoveReplicationPolicy_WithStatusOK(t *testing.T) {
 func TestRESTClient_Put_RequestError(t *testing.T) {
 	client, _ := NewRESTClient(&Config{ServerURL: "http://localhost:0"})
 
-	err := client.Put(context.Background(), "test.txt", strings.NewReader("data"), nil)
+	_, err := client.Put(context.Background(), "test.txt", strings.NewReader("data"), nil)
 	if err == nil {
 		t.Error("expected error from connection failure")
 	}
@@ -1504,7 +1670,7 @@ func TestQUICClient_Get_RequestError(t *testing.T) {
 func TestQUICClient_Put_RequestError(t *testing.T) {
 	client, _ := NewQUICClient(&Config{ServerURL: "http://localhost:0"})
 
-	err := client.Put(context.Background(), "test.txt", strings.NewReader("data"), nil)
+	_, err := client.Put(context.Background(), "test.txt", strings.NewReader("data"), nil)
 	if err == nil {
 		t.Error("expected error from connection failure")
 	}
@@ -1554,3 +1720,349 @@ func TestQUICClient_RemoveReplicationPolicy_RequestError(t *testing.T) {
 		t.Error("expected error from connection failure")
 	}
 }
+
+func TestRESTClient_Analyze(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/api/v1/analyze" || q.Get("prefix") != "logs/" || q.Get("depth") != "2" || q.Get("buckets") != "168h0m0s" {
+			w.WriteHeader(http.StatusNotImplemented)
+			return
+		}
+		_, _ = io.WriteString(w, `{"total":{"prefix":"logs/","objects":3,"bytes":30,"age":[{"label":"0-7d","objects":3,"bytes":30},{"label":"7d+"}]},"prefixes":[]}`)
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClient(&Config{ServerURL: server.URL})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	var analyzer Analyzer = client
+	report, err := analyzer.Analyze(context.Background(), "logs/", &access.AnalyzeOptions{Depth: 2, Buckets: []time.Duration{7 * 24 * time.Hour}})
+	if err != nil || report.Total.Objects != 3 || len(report.Total.Age) != 2 {
+		t.Errorf("Analyze() = %+v, %v", report, err)
+	}
+	if _, err := client.Analyze(context.Background(), "", nil); err == nil {
+		t.Error("Analyze() succeeded on 501")
+	}
+}
+
+func TestRESTClient_CostReport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.RequestURI() {
+		case "/api/v1/cost?month=2025-11":
+			_, _ = io.WriteString(w, `{"month":"2025-11","currency":"USD","lines":[{"backend":"default","group":"logs/","cost":{"total":1.5}}],"total":{"total":1.5}}`)
+		default:
+			w.WriteHeader(http.StatusNotImplemented)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClient(&Config{ServerURL: server.URL})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	var reporter CostReporter = client
+	report, err := reporter.CostReport(context.Background(), "2025-11")
+	if err != nil || report.Month != "2025-11" || len(report.Lines) != 1 || report.Total.Total != 1.5 {
+		t.Errorf("CostReport() = %+v, %v", report, err)
+	}
+	if _, err := client.CostReport(context.Background(), ""); err == nil {
+		t.Error("CostReport() succeeded on 501")
+	}
+}
+
+func TestRESTClient_Jobs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/jobs":
+			_, _ = io.WriteString(w, `{"jobs":[{"name":"lifecycle","interval_seconds":3600,"running":false}]}`)
+		case "/api/v1/jobs/lifecycle":
+			_, _ = io.WriteString(w, `{"name":"lifecycle","interval_seconds":3600,"history":[{"job":"lifecycle","outcome":"succeeded"}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClient(&Config{ServerURL: server.URL})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	var viewer JobsViewer = client
+	list, err := viewer.ListJobs(context.Background())
+	if err != nil || len(list) != 1 || list[0].Name != "lifecycle" || list[0].IntervalSeconds != 3600 {
+		t.Errorf("ListJobs() = %+v, %v", list, err)
+	}
+	status, err := viewer.GetJob(context.Background(), "lifecycle")
+	if err != nil || len(status.History) != 1 || status.History[0].Outcome != jobs.OutcomeSucceeded {
+		t.Errorf("GetJob() = %+v, %v", status, err)
+	}
+	if _, err := viewer.GetJob(context.Background(), "missing"); err == nil {
+		t.Error("GetJob() succeeded for a missing job")
+	}
+}
+
+func TestRESTClient_Tasks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/tasks":
+			if r.URL.Query().Get("state") != "dead" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_, _ = io.WriteString(w, `{"tasks":[{"id":"t1","type":"archive","state":"dead","attempts":8}]}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/tasks/t1/retry":
+			_, _ = io.WriteString(w, `{"id":"t1","type":"archive","state":"pending"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClient(&Config{ServerURL: server.URL})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	var manager TaskManager = client
+	list, err := manager.ListTasks(context.Background(), tasks.StateDead)
+	if err != nil || len(list) != 1 || list[0].ID != "t1" || list[0].Attempts != 8 {
+		t.Errorf("ListTasks() = %+v, %v", list, err)
+	}
+	task, err := manager.RetryTask(context.Background(), "t1")
+	if err != nil || task.State != tasks.StatePending {
+		t.Errorf("RetryTask() = %+v, %v", task, err)
+	}
+	if _, err := manager.RetryTask(context.Background(), "missing"); err == nil {
+		t.Error("RetryTask() succeeded for a missing task")
+	}
+}
+
+func TestRESTClient_Shares(t *testing.T) {
+	link := `{"id":"3f2a9c1e5b7d4e6f8a0b1c2d3e4f5a6b","key":"reports/q1.pdf","status":"active","created_at":"2025-11-05T10:00:00Z","expires_at":"2025-11-06T10:00:00Z","max_downloads":10,"password_protected":true,"downloads":3,"bytes_served":27}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/shares/objects/reports/q1.pdf":
+			var body map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if body["ttl_seconds"] != float64(3600) || body["max_downloads"] != float64(10) || body["password"] != "secret" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+			_, _ = io.WriteString(w, strings.TrimSuffix(link, "}")+`,"token":"tok","url":"http://objstore/share/tok"}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/shares":
+			_, _ = io.WriteString(w, `{"shares":[`+link+`],"count":1}`)
+		case r.Method == http.MethodDelete && r.URL.Path == "/api/v1/shares/3f2a9c1e5b7d4e6f8a0b1c2d3e4f5a6b":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClient(&Config{ServerURL: server.URL})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	var manager ShareManager = client
+	ctx := context.Background()
+	created, shareURL, err := manager.CreateShare(ctx, "reports/q1.pdf", share.Options{TTL: time.Hour, MaxDownloads: 10, Password: "secret"})
+	if err != nil || shareURL != "http://objstore/share/tok" || !created.Protected || created.Downloads != 3 {
+		t.Fatalf("CreateShare() = %+v, %q, %v", created, shareURL, err)
+	}
+	list, err := manager.ListShares(ctx)
+	if err != nil || len(list) != 1 || list[0].Key != "reports/q1.pdf" || list[0].BytesServed != 27 {
+		t.Errorf("ListShares() = %+v, %v", list, err)
+	}
+	if err := manager.RevokeShare(ctx, created.ID); err != nil {
+		t.Errorf("RevokeShare() error = %v", err)
+	}
+	if err := manager.RevokeShare(ctx, "missing"); err == nil {
+		t.Error("RevokeShare() succeeded for a missing link")
+	}
+}
+
+func TestRESTClient_Aliases(t *testing.T) {
+	latest := `{"name":"releases/latest","target":"releases/v1.4.2/app.tar.gz","updated_at":"2025-11-05T10:00:00Z","updated_by":"ci"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPut && r.URL.Path == "/api/v1/aliases/releases/latest":
+			var body map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if body["target"] != "releases/v1.4.2/app.tar.gz" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_, _ = io.WriteString(w, latest)
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/aliases/releases/latest":
+			_, _ = io.WriteString(w, latest)
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/aliases" && r.URL.Query().Get("prefix") == "releases/":
+			_, _ = io.WriteString(w, `{"aliases":[`+latest+`],"count":1}`)
+		case r.Method == http.MethodDelete && r.URL.Path == "/api/v1/aliases/releases/latest":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClient(&Config{ServerURL: server.URL})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	var manager AliasManager = client
+	ctx := context.Background()
+	set, err := manager.SetAlias(ctx, "releases/latest", "releases/v1.4.2/app.tar.gz")
+	if err != nil || set.UpdatedBy != "ci" {
+		t.Fatalf("SetAlias() = %+v, %v", set, err)
+	}
+	resolved, err := manager.ResolveAlias(ctx, "releases/latest")
+	if err != nil || resolved.Target != "releases/v1.4.2/app.tar.gz" {
+		t.Errorf("ResolveAlias() = %+v, %v", resolved, err)
+	}
+	list, err := manager.ListAliases(ctx, "releases/")
+	if err != nil || len(list) != 1 || list[0].Name != "releases/latest" {
+		t.Errorf("ListAliases() = %+v, %v", list, err)
+	}
+	if err := manager.DeleteAlias(ctx, "releases/latest"); err != nil {
+		t.Errorf("DeleteAlias() error = %v", err)
+	}
+	if _, err := manager.ResolveAlias(ctx, "missing"); err == nil {
+		t.Error("ResolveAlias() succeeded for a missing alias")
+	}
+}
+
+func TestRESTClient_Quotas(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/quotas":
+			_, _ = io.WriteString(w, `{"quotas":[{"prefix":"tenants/acme/","max_bytes":1024}],"count":1}`)
+		case r.Method == http.MethodPut && r.URL.Path == "/api/v1/quotas/tenants/acme/":
+			var body map[string]int64
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if body["max_objects"] != 10 {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_, _ = io.WriteString(w, `{"prefix":"tenants/acme/","max_objects":10}`)
+		case r.Method == http.MethodDelete && r.URL.Path == "/api/v1/quotas/tenants/acme/":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClient(&Config{ServerURL: server.URL})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	var manager QuotaManager = client
+	ctx := context.Background()
+	quotas, err := manager.ListQuotas(ctx)
+	if err != nil || len(quotas) != 1 || quotas["tenants/acme/"].MaxBytes != 1024 {
+		t.Errorf("ListQuotas() = %+v, %v", quotas, err)
+	}
+	if err := manager.SetQuota(ctx, "tenants/acme/", overlay.Quota{MaxObjects: 10}); err != nil {
+		t.Errorf("SetQuota() error = %v", err)
+	}
+	if err := manager.RemoveQuota(ctx, "tenants/acme/"); err != nil {
+		t.Errorf("RemoveQuota() error = %v", err)
+	}
+	if err := manager.RemoveQuota(ctx, "missing/"); err == nil {
+		t.Error("RemoveQuota() succeeded for a missing quota")
+	}
+}
+
+func TestRESTClient_Webhooks(t *testing.T) {
+	uploads := `{"id":"uploads","events":["s3:ObjectCreated:*"],"prefix":"uploads/","sink":{"type":"slack","settings":{"webhookUrl":"https://hooks.example.com/x"}}}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/webhooks":
+			_, _ = io.WriteString(w, `{"webhooks":[`+uploads+`],"count":1}`)
+		case r.Method == http.MethodPut && r.URL.Path == "/api/v1/webhooks/uploads":
+			var rule events.Rule
+			_ = json.NewDecoder(r.Body).Decode(&rule)
+			if rule.Sink.Type != "slack" || rule.Prefix != "uploads/" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_, _ = io.WriteString(w, uploads)
+		case r.Method == http.MethodDelete && r.URL.Path == "/api/v1/webhooks/uploads":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClient(&Config{ServerURL: server.URL})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	var manager WebhookManager = client
+	ctx := context.Background()
+	rules, err := manager.ListWebhooks(ctx)
+	if err != nil || len(rules) != 1 || rules[0].Sink.Settings["webhookUrl"] != "https://hooks.example.com/x" {
+		t.Errorf("ListWebhooks() = %+v, %v", rules, err)
+	}
+	if err := manager.SetWebhook(ctx, rules[0]); err != nil {
+		t.Errorf("SetWebhook() error = %v", err)
+	}
+	if err := manager.DeleteWebhook(ctx, "uploads"); err != nil {
+		t.Errorf("DeleteWebhook() error = %v", err)
+	}
+	if err := manager.DeleteWebhook(ctx, "missing"); err == nil {
+		t.Error("DeleteWebhook() succeeded for a missing webhook")
+	}
+}
+
+func TestRESTClient_AccessKeys(t *testing.T) {
+	key := `{"access_key_id":"OSKTEST","principal_id":"ci","roles":["writer"],"created_at":"2025-11-05T10:00:00Z"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/access-keys":
+			_, _ = io.WriteString(w, `{"access_keys":[`+key+`],"count":1}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/access-keys":
+			var body map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if body["principal_id"] != "ci" || body["access_key_id"] != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+			_, _ = io.WriteString(w, `{"access_key_id":"OSKTEST","secret_access_key":"s3cr3t","principal_id":"ci","created_at":"2025-11-05T10:00:00Z"}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/access-keys/OSKTEST":
+			_, _ = io.WriteString(w, key)
+		case r.Method == http.MethodDelete && r.URL.Path == "/api/v1/access-keys/OSKTEST":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClient(&Config{ServerURL: server.URL})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	var manager AccessKeyManager = client
+	ctx := context.Background()
+	created, err := manager.CreateAccessKey(ctx, adapters.AccessKey{PrincipalID: "ci"})
+	if err != nil || created.SecretAccessKey != "s3cr3t" || created.CreatedAt.IsZero() {
+		t.Errorf("CreateAccessKey() = %+v, %v", created, err)
+	}
+	keys, err := manager.ListAccessKeys(ctx)
+	if err != nil || len(keys) != 1 || keys[0].Roles[0] != "writer" {
+		t.Errorf("ListAccessKeys() = %+v, %v", keys, err)
+	}
+	if got, err := manager.GetAccessKey(ctx, "OSKTEST"); err != nil || got.PrincipalID != "ci" {
+		t.Errorf("GetAccessKey() = %+v, %v", got, err)
+	}
+	if err := manager.DeleteAccessKey(ctx, "OSKTEST"); err != nil {
+		t.Errorf("DeleteAccessKey() error = %v", err)
+	}
+	if _, err := manager.GetAccessKey(ctx, "missing"); err == nil {
+		t.Error("GetAccessKey() succeeded for a missing key")
+	}
+}
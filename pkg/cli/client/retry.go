@@ -0,0 +1,311 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Request timeout and retry defaults.
+const (
+	// DefaultTimeout bounds the wait for a response when Config.Timeout is
+	// zero.
+	DefaultTimeout = 30 * time.Second
+
+	// DefaultRetryBackoff is the delay before the first retry when
+	// RetryPolicy.InitialBackoff is zero. It doubles with every retry.
+	DefaultRetryBackoff = 200 * time.Millisecond
+
+	// DefaultMaxRetryBackoff caps the delay between retries when
+	// RetryPolicy.MaxBackoff is zero.
+	DefaultMaxRetryBackoff = 5 * time.Second
+)
+
+// RetryPolicy configures how requests that fail transiently are retried.
+// Only idempotent requests are retried: REST and QUIC requests with a GET,
+// HEAD, PUT, DELETE or OPTIONS method whose body can be replayed, after a
+// connection error or a 429, 502, 503 or 504 response, and gRPC calls that
+// fail with UNAVAILABLE before the server responded.
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts including the first; values
+	// below 2 disable retries. gRPC caps it at 5.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry, doubled for every
+	// further retry up to MaxBackoff. A Retry-After header on the response
+	// lengthens the delay, but not beyond MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// withDefaults returns p with its zero backoffs set to their defaults.
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = DefaultRetryBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = DefaultMaxRetryBackoff
+	}
+	if p.MaxBackoff < p.InitialBackoff {
+		p.MaxBackoff = p.InitialBackoff
+	}
+	return p
+}
+
+// backoff returns the delay before retry number n, counted from 1.
+func (p RetryPolicy) backoff(n int) time.Duration {
+	delay := p.InitialBackoff
+	for i := 1; i < n && delay < p.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, p.MaxBackoff)
+}
+
+// requestTimeout returns the response timeout for config: DefaultTimeout
+// when Config.Timeout is zero, and none when it is negative.
+func requestTimeout(config *Config) time.Duration {
+	switch {
+	case config.Timeout < 0:
+		return 0
+	case config.Timeout == 0:
+		return DefaultTimeout
+	default:
+		return config.Timeout
+	}
+}
+
+// clientTLSConfig returns the TLS settings for connecting to the server:
+// the CA in Config.CAFile added to the system roots, and no verification at
+// all with Config.InsecureSkipVerify.
+func clientTLSConfig(config *Config, minVersion uint16) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         minVersion,
+		InsecureSkipVerify: config.InsecureSkipVerify, // #nosec G402 -- development-only opt-in, defaults to false
+	}
+	if config.CAFile == "" {
+		return tlsConfig, nil
+	}
+
+	pem, err := os.ReadFile(config.CAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("%w: no certificates found in %s", ErrInvalidCACert, config.CAFile)
+	}
+	tlsConfig.RootCAs = pool
+	return tlsConfig, nil
+}
+
+// timeoutTransport fails requests whose response does not start within
+// timeout of the request being sent. Sending the request body and reading
+// the response body are not bounded, so large transfers are not cut short.
+type timeoutTransport struct {
+	timeout time.Duration
+	base    http.RoundTripper
+}
+
+// withTimeout wraps base so responses must start within timeout. It returns
+// base unchanged when timeout is not positive; a nil base means
+// http.DefaultTransport.
+func withTimeout(timeout time.Duration, base http.RoundTripper) http.RoundTripper {
+	if timeout <= 0 {
+		return base
+	}
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &timeoutTransport{timeout: timeout, base: base}
+}
+
+// RoundTrip implements http.RoundTripper. The timer starts once the request
+// body has been read to its end, or right away for requests without one.
+func (t *timeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithCancel(req.Context())
+
+	var mu sync.Mutex
+	var timer *time.Timer
+	returned := false
+	start := func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if timer == nil && !returned {
+			timer = time.AfterFunc(t.timeout, cancel)
+		}
+	}
+
+	req = req.WithContext(ctx)
+	if req.Body == nil || req.Body == http.NoBody {
+		start()
+	} else {
+		req.Body = &eofNotifier{ReadCloser: req.Body, onEOF: start}
+	}
+
+	resp, err := t.base.RoundTrip(req)
+
+	mu.Lock()
+	returned = true
+	fired := timer != nil && !timer.Stop()
+	mu.Unlock()
+
+	if fired {
+		if resp != nil {
+			_ = resp.Body.Close()
+		}
+		cancel()
+		return nil, fmt.Errorf("%w: no response within %s", ErrTimeout, t.timeout)
+	}
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// eofNotifier calls onEOF once its reader is exhausted.
+type eofNotifier struct {
+	io.ReadCloser
+	onEOF func()
+}
+
+func (r *eofNotifier) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if err == io.EOF {
+		r.onEOF()
+	}
+	return n, err
+}
+
+// cancelOnClose releases a request's context when its response body is
+// closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// retryTransport retries idempotent requests that fail transiently.
+type retryTransport struct {
+	policy RetryPolicy
+	base   http.RoundTripper
+}
+
+// withRetry wraps base so requests are retried according to policy. It
+// returns base unchanged when policy is nil or allows a single attempt; a
+// nil base means http.DefaultTransport.
+func withRetry(policy *RetryPolicy, base http.RoundTripper) http.RoundTripper {
+	if policy == nil || policy.MaxAttempts < 2 {
+		return base
+	}
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &retryTransport{policy: policy.withDefaults(), base: base}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !replayable(req) {
+		return t.base.RoundTrip(req)
+	}
+
+	for attempt := 1; ; attempt++ {
+		attemptReq := req
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attemptReq = req.Clone(req.Context())
+			attemptReq.Body = body
+		}
+
+		resp, err := t.base.RoundTrip(attemptReq)
+		if attempt == t.policy.MaxAttempts || !retryable(req.Context(), resp, err) {
+			return resp, err
+		}
+
+		delay := t.policy.backoff(attempt)
+		if resp != nil {
+			if after := retryAfter(resp); after > delay {
+				delay = min(after, t.policy.MaxBackoff)
+			}
+			// Drain the body so the connection can be reused.
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+			_ = resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// replayable reports whether req is idempotent and its body can be sent
+// again.
+func replayable(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+	default:
+		return false
+	}
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// retryable reports whether an attempt that returned resp and err failed
+// transiently.
+func retryable(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		return ctx.Err() == nil && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryAfter returns the delay requested by a Retry-After header given in
+// seconds, or zero.
+func retryAfter(resp *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,269 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRESTClient_RetriesTransientFailures(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "hello" {
+			t.Errorf("attempt %d: expected the body to be replayed, got %q", attempts.Load()+1, body)
+		}
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClient(&Config{
+		ServerURL: server.URL,
+		Retry:     &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond},
+	})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	if _, err := client.Put(context.Background(), "test.txt", strings.NewReader("hello"), nil); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if attempts.Load() != 3 {
+		t.Errorf("expected 3 attempts, got %d", attempts.Load())
+	}
+}
+
+func TestRESTClient_RetryLimits(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClient(&Config{
+		ServerURL: server.URL,
+		Retry:     &RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond},
+	})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	if err := client.Delete(context.Background(), "test.txt"); err == nil {
+		t.Error("expected Delete to fail once retries are exhausted")
+	}
+	if attempts.Load() != 2 {
+		t.Errorf("expected 2 attempts, got %d", attempts.Load())
+	}
+
+	// POST is not idempotent and is never retried.
+	attempts.Store(0)
+	if _, err := client.RetryTask(context.Background(), "task-1"); err == nil {
+		t.Error("expected RetryTask to fail")
+	}
+	if attempts.Load() != 1 {
+		t.Errorf("expected 1 attempt for POST, got %d", attempts.Load())
+	}
+}
+
+func TestRESTClient_NoRetryWithoutPolicy(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClient(&Config{ServerURL: server.URL})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	_, _, _ = client.Get(context.Background(), "test.txt")
+	if attempts.Load() != 1 {
+		t.Errorf("expected 1 attempt, got %d", attempts.Load())
+	}
+}
+
+func TestRESTClient_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClient(&Config{ServerURL: server.URL, Timeout: 20 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	if _, err := client.Exists(context.Background(), "test.txt"); !errors.Is(err, ErrTimeout) {
+		t.Errorf("expected ErrTimeout, got %v", err)
+	}
+}
+
+func TestRESTClient_TimeoutDoesNotBoundTransfers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			_, _ = io.Copy(io.Discard, r.Body)
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		// Respond at once, then send the body slower than the timeout.
+		w.WriteHeader(http.StatusOK)
+		for range 3 {
+			_, _ = w.Write([]byte("chunk"))
+			w.(http.Flusher).Flush()
+			time.Sleep(30 * time.Millisecond)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClient(&Config{ServerURL: server.URL, Timeout: 20 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	reader, _, err := client.Get(context.Background(), "test.txt")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	data, err := io.ReadAll(reader)
+	_ = reader.Close()
+	if err != nil || string(data) != "chunkchunkchunk" {
+		t.Errorf("expected the whole body, got %q, %v", data, err)
+	}
+
+	// An upload slower than the timeout is not cut short either.
+	body, feed := io.Pipe()
+	go func() {
+		for range 3 {
+			_, _ = feed.Write([]byte("chunk"))
+			time.Sleep(30 * time.Millisecond)
+		}
+		_ = feed.Close()
+	}()
+	if _, err := client.Put(context.Background(), "test.txt", body, nil); err != nil {
+		t.Errorf("Put failed: %v", err)
+	}
+}
+
+func TestRESTClient_CAFile(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// The test server's certificate is not trusted by default.
+	client, err := NewRESTClient(&Config{ServerURL: server.URL})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	if err := client.Health(context.Background()); err == nil {
+		t.Error("expected certificate verification to fail")
+	}
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, certPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	client, err = NewRESTClient(&Config{ServerURL: server.URL, CAFile: caFile})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	if err := client.Health(context.Background()); err != nil {
+		t.Errorf("expected the CA file to be trusted, got %v", err)
+	}
+
+	client, err = NewRESTClient(&Config{ServerURL: server.URL, InsecureSkipVerify: true})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	if err := client.Health(context.Background()); err != nil {
+		t.Errorf("expected verification to be skipped, got %v", err)
+	}
+}
+
+func TestClientTLSConfig_InvalidCAFile(t *testing.T) {
+	if _, err := NewRESTClient(&Config{ServerURL: "https://localhost", CAFile: filepath.Join(t.TempDir(), "missing.pem")}); err == nil {
+		t.Error("expected an error for a missing CA file")
+	}
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for _, protocol := range []string{"rest", "grpc", "quic"} {
+		_, err := NewClient(&Config{ServerURL: "localhost:8443", Protocol: protocol, CAFile: caFile})
+		if !errors.Is(err, ErrInvalidCACert) {
+			t.Errorf("%s: expected ErrInvalidCACert, got %v", protocol, err)
+		}
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}.withDefaults()
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second}
+	for i, w := range want {
+		if got := policy.backoff(i + 1); got != w {
+			t.Errorf("backoff(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+
+	defaults := RetryPolicy{}.withDefaults()
+	if defaults.InitialBackoff != DefaultRetryBackoff || defaults.MaxBackoff != DefaultMaxRetryBackoff {
+		t.Errorf("unexpected defaults %+v", defaults)
+	}
+}
+
+func TestRetryServiceConfig(t *testing.T) {
+	var config struct {
+		MethodConfig []struct {
+			RetryPolicy struct {
+				MaxAttempts          int      `json:"maxAttempts"`
+				InitialBackoff       string   `json:"initialBackoff"`
+				RetryableStatusCodes []string `json:"retryableStatusCodes"`
+			} `json:"retryPolicy"`
+		} `json:"methodConfig"`
+	}
+	if err := json.Unmarshal([]byte(retryServiceConfig(RetryPolicy{MaxAttempts: 10, InitialBackoff: 250 * time.Millisecond})), &config); err != nil {
+		t.Fatal(err)
+	}
+	if len(config.MethodConfig) != 1 {
+		t.Fatalf("expected one method config, got %+v", config)
+	}
+	policy := config.MethodConfig[0].RetryPolicy
+	if policy.MaxAttempts != 5 || policy.InitialBackoff != "0.250s" || len(policy.RetryableStatusCodes) != 1 {
+		t.Errorf("unexpected retry policy %+v", policy)
+	}
+
+	client, err := NewGRPCClient(&Config{ServerURL: "localhost:50051", Retry: &RetryPolicy{MaxAttempts: 3}})
+	if err != nil {
+		t.Fatalf("expected the service config to be accepted, got %v", err)
+	}
+	_ = client.Close()
+}
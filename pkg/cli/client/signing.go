@@ -0,0 +1,97 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package client
+
+import (
+	"context"
+	"io"
+	"maps"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/signing"
+)
+
+// signedClient signs objects before they are sent to the server and
+// verifies them after they are read.
+type signedClient struct {
+	Client
+	signer   *signing.Signer
+	verifier *signing.Verifier
+}
+
+// WithSigning returns a Client that signs the objects it uploads with
+// signer and verifies the objects it downloads with verifier. Either may be
+// nil to only sign or only verify. The optional server APIs of c stay
+// available through Optional.
+func WithSigning(c Client, signer *signing.Signer, verifier *signing.Verifier) Client {
+	return &signedClient{Client: c, signer: signer, verifier: verifier}
+}
+
+// Unwrap returns the client c wraps.
+func (c *signedClient) Unwrap() Client {
+	return c.Client
+}
+
+// Put signs and uploads an object.
+func (c *signedClient) Put(ctx context.Context, key string, reader io.Reader, metadata *common.Metadata) (*common.ObjectInfo, error) {
+	if c.signer == nil {
+		return c.Client.Put(ctx, key, reader, metadata)
+	}
+	content, signed, err := c.signer.Sign(key, reader, metadata)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = content.Close() }()
+	return c.Client.Put(ctx, key, content, signed)
+}
+
+// Get downloads and verifies an object.
+func (c *signedClient) Get(ctx context.Context, key string) (io.ReadCloser, *common.Metadata, error) {
+	rc, metadata, err := c.Client.Get(ctx, key)
+	if err != nil || c.verifier == nil {
+		return rc, metadata, err
+	}
+	// Not every protocol returns custom metadata with the content.
+	if !signing.IsSigned(metadata) {
+		if metadata, err = c.Client.GetMetadata(ctx, key); err != nil {
+			_ = rc.Close()
+			return nil, nil, err
+		}
+	}
+	verified, err := c.verifier.Open(key, rc, metadata)
+	if err != nil {
+		_ = rc.Close()
+		return nil, nil, err
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{verified, rc}, metadata, nil
+}
+
+// UpdateMetadata replaces the metadata of an object, keeping its
+// signature.
+func (c *signedClient) UpdateMetadata(ctx context.Context, key string, metadata *common.Metadata) error {
+	stored, err := c.Client.GetMetadata(ctx, key)
+	if err != nil {
+		return err
+	}
+	if metadata != nil {
+		update := *metadata
+		update.Custom = maps.Clone(metadata.Custom)
+		signing.KeepSignature(stored, &update)
+		metadata = &update
+	}
+	return c.Client.UpdateMetadata(ctx, key, metadata)
+}
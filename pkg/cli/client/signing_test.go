@@ -0,0 +1,78 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package client
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/signing"
+)
+
+func TestWithSigning(t *testing.T) {
+	ts, backend := newTokenServer(t)
+	ctx := context.Background()
+
+	key, err := signing.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rest, err := NewRESTClient(&Config{ServerURL: ts.URL, Token: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := WithSigning(rest, signing.NewSigner(key), signing.NewVerifier(key.Public().(ed25519.PublicKey)))
+	if _, ok := Optional[Searcher](c); !ok {
+		t.Error("Optional[Searcher] does not look through the signing client")
+	}
+
+	if _, err := c.Put(ctx, "releases/app.tgz", strings.NewReader("release 1.0"), nil); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if err := c.UpdateMetadata(ctx, "releases/app.tgz", &common.Metadata{ContentType: "application/gzip"}); err != nil {
+		t.Fatalf("UpdateMetadata() error = %v", err)
+	}
+	rc, _, err := c.Get(ctx, "releases/app.tgz")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	got, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil || string(got) != "release 1.0" {
+		t.Fatalf("Get() = %q, %v", got, err)
+	}
+
+	// The server's copy is replaced behind the client's back.
+	stored, err := backend.GetMetadata(ctx, "releases/app.tgz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := backend.PutWithMetadata(ctx, "releases/app.tgz", strings.NewReader("release 6.6"), stored); err != nil {
+		t.Fatal(err)
+	}
+	rc, _, err = c.Get(ctx, "releases/app.tgz")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	_, err = io.ReadAll(rc)
+	rc.Close()
+	if !errors.Is(err, signing.ErrTampered) {
+		t.Errorf("reading a replaced object: error = %v, want ErrTampered", err)
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package client
+
+import (
+	"context"
+	"io"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// Storage adapts a Client to common.Storage so that a remote objstore server
+// can be registered with the objstore facade, wrapped by storagefs, or used
+// anywhere else a backend is expected. This is how the facade runs in
+// environments without local backends, such as the js/wasm build.
+type Storage struct {
+	client Client
+}
+
+// NewStorage returns a common.Storage that forwards every call to c.
+func NewStorage(c Client) *Storage {
+	return &Storage{client: c}
+}
+
+// Client returns the wrapped client.
+func (s *Storage) Client() Client {
+	return s.client
+}
+
+// Configure is a no-op; the remote server owns its backend configuration.
+func (s *Storage) Configure(settings map[string]string) error {
+	return nil
+}
+
+// Put stores an object on the server.
+func (s *Storage) Put(key string, data io.Reader) error {
+	_, err := s.client.Put(context.Background(), key, data, nil)
+	return err
+}
+
+// PutWithContext stores an object on the server.
+func (s *Storage) PutWithContext(ctx context.Context, key string, data io.Reader) error {
+	_, err := s.client.Put(ctx, key, data, nil)
+	return err
+}
+
+// PutWithMetadata stores an object with metadata on the server.
+func (s *Storage) PutWithMetadata(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) error {
+	_, err := s.client.Put(ctx, key, data, metadata)
+	return err
+}
+
+// PutWithInfo stores an object with metadata on the server and returns
+// what the server reported storing.
+func (s *Storage) PutWithInfo(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) (*common.ObjectInfo, error) {
+	return s.client.Put(ctx, key, data, metadata)
+}
+
+// Get retrieves an object from the server.
+func (s *Storage) Get(key string) (io.ReadCloser, error) {
+	return s.GetWithContext(context.Background(), key)
+}
+
+// GetWithContext retrieves an object from the server.
+func (s *Storage) GetWithContext(ctx context.Context, key string) (io.ReadCloser, error) {
+	rc, _, err := s.client.Get(ctx, key)
+	return rc, err
+}
+
+// GetMetadata retrieves an object's metadata from the server.
+func (s *Storage) GetMetadata(ctx context.Context, key string) (*common.Metadata, error) {
+	return s.client.GetMetadata(ctx, key)
+}
+
+// UpdateMetadata updates an object's metadata on the server.
+func (s *Storage) UpdateMetadata(ctx context.Context, key string, metadata *common.Metadata) error {
+	return s.client.UpdateMetadata(ctx, key, metadata)
+}
+
+// Delete removes an object from the server.
+func (s *Storage) Delete(key string) error {
+	return s.client.Delete(context.Background(), key)
+}
+
+// DeleteWithContext removes an object from the server.
+func (s *Storage) DeleteWithContext(ctx context.Context, key string) error {
+	return s.client.Delete(ctx, key)
+}
+
+// Exists reports whether an object exists on the server.
+func (s *Storage) Exists(ctx context.Context, key string) (bool, error) {
+	return s.client.Exists(ctx, key)
+}
+
+// List returns every key on the server that starts with prefix.
+func (s *Storage) List(prefix string) ([]string, error) {
+	return s.ListWithContext(context.Background(), prefix)
+}
+
+// ListWithContext returns every key on the server that starts with prefix,
+// following pagination tokens.
+func (s *Storage) ListWithContext(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	opts := &common.ListOptions{Prefix: prefix}
+	for {
+		result, err := s.client.List(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range result.Objects {
+			keys = append(keys, obj.Key)
+		}
+		if !result.Truncated || result.NextToken == "" {
+			return keys, nil
+		}
+		opts.ContinueFrom = result.NextToken
+	}
+}
+
+// ListWithOptions returns one page of objects from the server.
+func (s *Storage) ListWithOptions(ctx context.Context, opts *common.ListOptions) (*common.ListResult, error) {
+	return s.client.List(ctx, opts)
+}
+
+// Archive copies an object from the server to destination. The data passes
+// through this process; use Client.Archive to have the server copy it to a
+// backend it is configured for.
+func (s *Storage) Archive(key string, destination common.Archiver) error {
+	rc, _, err := s.client.Get(context.Background(), key)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rc.Close() }()
+	return destination.Put(key, rc)
+}
+
+// AddPolicy adds a lifecycle policy on the server.
+func (s *Storage) AddPolicy(policy common.LifecyclePolicy) error {
+	return s.client.AddPolicy(context.Background(), policy)
+}
+
+// RemovePolicy removes a lifecycle policy from the server.
+func (s *Storage) RemovePolicy(id string) error {
+	return s.client.RemovePolicy(context.Background(), id)
+}
+
+// GetPolicies returns the server's lifecycle policies.
+func (s *Storage) GetPolicies() ([]common.LifecyclePolicy, error) {
+	return s.client.GetPolicies(context.Background())
+}
@@ -0,0 +1,205 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+	"github.com/jeremyhahn/go-objstore/pkg/server/rest"
+)
+
+// newTokenServer starts a REST server over a memory backend, registered
+// with the facade as "mem", that accepts only the bearer token "secret".
+func newTokenServer(t *testing.T) (*httptest.Server, common.Storage) {
+	t.Helper()
+	backend := memory.New()
+	objstore.Reset()
+	t.Cleanup(objstore.Reset)
+	if err := objstore.Initialize(&objstore.FacadeConfig{
+		Backends:       map[string]common.Storage{"mem": backend},
+		DefaultBackend: "mem",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	config := rest.DefaultServerConfig()
+	config.EnableLogging = false
+	config.EnableAudit = false
+	config.Authenticator = adapters.NewBearerTokenAuthenticator(func(ctx context.Context, token string) (*adapters.Principal, error) {
+		if token != "secret" {
+			return nil, errors.New("invalid token")
+		}
+		return &adapters.Principal{ID: "browser", Type: "user"}, nil
+	})
+	server, err := rest.NewServer(backend, config)
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	ts := httptest.NewServer(server.Router())
+	t.Cleanup(ts.Close)
+	return ts, backend
+}
+
+func TestRESTClient_Token(t *testing.T) {
+	ts, _ := newTokenServer(t)
+	ctx := context.Background()
+
+	anonymous, err := NewRESTClient(&Config{ServerURL: ts.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := anonymous.Put(ctx, "a.txt", strings.NewReader("a"), nil); err == nil {
+		t.Error("Put() without token succeeded")
+	}
+
+	authorized, err := NewRESTClient(&Config{ServerURL: ts.URL, Token: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := authorized.Put(ctx, "a.txt", strings.NewReader("a"), nil); err != nil {
+		t.Errorf("Put() with token error = %v", err)
+	}
+}
+
+func TestRESTClient_SigV4(t *testing.T) {
+	backend := memory.New()
+	objstore.Reset()
+	t.Cleanup(objstore.Reset)
+	if err := objstore.Initialize(&objstore.FacadeConfig{
+		Backends:       map[string]common.Storage{"mem": backend},
+		DefaultBackend: "mem",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	config := rest.DefaultServerConfig()
+	config.EnableLogging = false
+	config.EnableAudit = false
+	config.Authenticator = adapters.NewStaticSigV4Authenticator(map[string]adapters.SigV4Key{
+		"AKIDCLI": {SecretAccessKey: "cli-secret", Principal: adapters.Principal{ID: "cli", Type: "service"}},
+	})
+	server, err := rest.NewServer(backend, config)
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	ts := httptest.NewServer(server.Router())
+	t.Cleanup(ts.Close)
+	ctx := context.Background()
+
+	signed, err := NewRESTClient(&Config{ServerURL: ts.URL, SigV4: &SigV4Config{
+		SigV4Credentials: adapters.SigV4Credentials{AccessKeyID: "AKIDCLI", SecretAccessKey: "cli-secret"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := signed.Put(ctx, "dir/a.txt", strings.NewReader("signed"), &common.Metadata{ContentType: "text/plain"}); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	result, err := signed.List(ctx, &common.ListOptions{Prefix: "dir/"})
+	if err != nil || len(result.Objects) != 1 {
+		t.Fatalf("List() = %+v, %v", result, err)
+	}
+	if err := signed.Delete(ctx, "dir/a.txt"); err != nil {
+		t.Errorf("Delete() error = %v", err)
+	}
+
+	wrongSecret, err := NewRESTClient(&Config{ServerURL: ts.URL, SigV4: &SigV4Config{
+		SigV4Credentials: adapters.SigV4Credentials{AccessKeyID: "AKIDCLI", SecretAccessKey: "wrong"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wrongSecret.Put(ctx, "b.txt", strings.NewReader("b"), nil); err == nil {
+		t.Error("Put() with wrong secret succeeded")
+	}
+}
+
+func TestStorage_RemoteBackend(t *testing.T) {
+	ts, backend := newTokenServer(t)
+	c, err := NewRESTClient(&Config{ServerURL: ts.URL, Token: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var storage common.Storage = NewStorage(c)
+	ctx := context.Background()
+
+	if err := storage.PutWithMetadata(ctx, "docs/readme.md", strings.NewReader("# hi"), &common.Metadata{ContentType: "text/markdown"}); err != nil {
+		t.Fatalf("PutWithMetadata() error = %v", err)
+	}
+	if exists, err := backend.Exists(ctx, "docs/readme.md"); err != nil || !exists {
+		t.Fatalf("object not stored on server: %v, %v", exists, err)
+	}
+
+	metadata, err := storage.GetMetadata(ctx, "docs/readme.md")
+	if err != nil || metadata.ContentType != "text/markdown" || metadata.Size != 4 {
+		t.Errorf("GetMetadata() = %+v, %v", metadata, err)
+	}
+
+	for _, key := range []string{"docs/a", "docs/b", "other"} {
+		if err := storage.Put(key, strings.NewReader(key)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	keys, err := storage.List("docs/")
+	if err != nil || len(keys) != 3 {
+		t.Errorf("List() = %v, %v", keys, err)
+	}
+
+	archive := memory.New()
+	if err := storage.Archive("other", archive); err != nil {
+		t.Fatalf("Archive() error = %v", err)
+	}
+	if exists, _ := archive.Exists(ctx, "other"); !exists {
+		t.Error("Archive() did not copy the object")
+	}
+
+	if err := storage.Delete("other"); err != nil {
+		t.Fatal(err)
+	}
+	if exists, err := storage.Exists(ctx, "other"); err != nil || exists {
+		t.Errorf("Exists() after Delete() = %v, %v", exists, err)
+	}
+
+	// The adapter lets the facade address the server like any backend.
+	objstore.Reset()
+	if err := objstore.Initialize(&objstore.FacadeConfig{
+		Backends:       map[string]common.Storage{"mem": backend, "remote": storage},
+		DefaultBackend: "mem",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if _, err := objstore.GetToWriter(ctx, "remote:docs/readme.md", &buf); err != nil || buf.String() != "# hi" {
+		t.Errorf("facade GetToWriter() = %q, %v", buf.String(), err)
+	}
+	rc, err := objstore.GetWithContext(ctx, "remote:docs/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(rc)
+	_ = rc.Close()
+	if string(data) != "docs/a" {
+		t.Errorf("facade Get() = %q", data)
+	}
+}
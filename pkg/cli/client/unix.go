@@ -45,8 +45,7 @@ func NewUnixSocketClient(config *Config) (*RESTClient, error) {
 	}
 
 	httpClient := &http.Client{
-		Transport: transport,
-		Timeout:   30 * time.Second,
+		Transport: withRetry(config.Retry, withTimeout(requestTimeout(config), transport)),
 	}
 
 	// Use localhost as the base URL - the actual connection goes to the socket
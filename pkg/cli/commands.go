@@ -15,15 +15,24 @@ package cli
 
 import (
 	"context"
+	"crypto/ed25519"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"strings"
 	"time"
 
+	"github.com/jeremyhahn/go-objstore/pkg/alias"
 	"github.com/jeremyhahn/go-objstore/pkg/cli/client"
 	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/contentpolicy"
+	"github.com/jeremyhahn/go-objstore/pkg/dedup"
+	"github.com/jeremyhahn/go-objstore/pkg/download"
+	"github.com/jeremyhahn/go-objstore/pkg/e2ee"
 	"github.com/jeremyhahn/go-objstore/pkg/factory"
+	"github.com/jeremyhahn/go-objstore/pkg/search"
+	"github.com/jeremyhahn/go-objstore/pkg/signing"
 	"github.com/jeremyhahn/go-objstore/pkg/version"
 )
 
@@ -37,6 +46,9 @@ type CommandContext struct {
 	Storage common.Storage
 	Client  client.Client
 	Config  *Config
+
+	// verifier verifies signed objects when VerifyKeys are configured.
+	verifier *signing.Verifier
 }
 
 // NewCommandContext creates a new command context from the configuration.
@@ -50,17 +62,46 @@ func NewCommandContext(cfg *Config) (*CommandContext, error) {
 		Config: cfg,
 	}
 
+	var keychain *e2ee.Keychain
+	if cfg.E2EKeychain != "" {
+		var err error
+		if keychain, err = e2ee.LoadKeychain(cfg.E2EKeychain); err != nil {
+			return nil, fmt.Errorf("failed to load keychain: %w", err)
+		}
+		if err := keychain.SetDeterministic(cfg.deterministicConfig()); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.E2ERecoveryKey != "" {
+		recovery, err := e2ee.LoadRecoveryKey(cfg.E2ERecoveryKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load recovery key: %w", err)
+		}
+		if keychain == nil {
+			keychain = e2ee.NewKeychain()
+		}
+		keychain.SetRecoveryKey(recovery)
+	}
+
+	signer, verifier, err := loadSigningKeys(cfg)
+	if err != nil {
+		return nil, err
+	}
+	ctx.verifier = verifier
+
 	// Check if using remote server
 	if cfg.Server != "" {
 		// Create remote client
-		clientConfig := &client.Config{
-			ServerURL: cfg.Server,
-			Protocol:  cfg.ServerProtocol,
-		}
-		remoteClient, err := client.NewClient(clientConfig)
+		remoteClient, err := client.NewClient(cfg.GetClientConfig())
 		if err != nil {
 			return nil, fmt.Errorf("failed to create remote client: %w", err)
 		}
+		if keychain != nil {
+			remoteClient = client.WithEndToEndEncryption(remoteClient, keychain)
+		}
+		if signer != nil || verifier != nil {
+			remoteClient = client.WithSigning(remoteClient, signer, verifier)
+		}
 		ctx.Client = remoteClient
 	} else {
 		// Create local storage backend
@@ -69,12 +110,60 @@ func NewCommandContext(cfg *Config) (*CommandContext, error) {
 		if err != nil {
 			return nil, err
 		}
+		if keychain != nil {
+			storage = e2ee.NewStorage(storage, keychain)
+		}
+		// Aliases resolve beneath dedup, signing and search, so alias
+		// objects are never deduplicated, signed or indexed.
+		aliases, err := alias.NewManager(storage, "")
+		if err != nil {
+			return nil, err
+		}
+		storage = alias.NewStorage(storage, aliases)
+		if cfg.Dedup {
+			storage = dedup.New(storage, nil)
+		}
+		if signer != nil || verifier != nil {
+			storage = signing.NewStorage(storage, signer, verifier)
+		}
+		if cfg.SearchIndex != "" {
+			index, err := search.NewIndex(cfg.SearchIndex)
+			if err != nil {
+				return nil, err
+			}
+			storage = search.NewStorage(storage, index)
+		}
 		ctx.Storage = storage
 	}
 
 	return ctx, nil
 }
 
+// loadSigningKeys returns the signer and verifier configured by SignKey
+// and VerifyKeys; each is nil when not configured.
+func loadSigningKeys(cfg *Config) (*signing.Signer, *signing.Verifier, error) {
+	var signer *signing.Signer
+	if cfg.SignKey != "" {
+		key, err := signing.LoadPrivateKey(cfg.SignKey)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load signing key: %w", err)
+		}
+		signer = signing.NewSigner(key)
+	}
+	if len(cfg.VerifyKeys) == 0 {
+		return signer, nil, nil
+	}
+	keys := make([]ed25519.PublicKey, 0, len(cfg.VerifyKeys))
+	for _, path := range cfg.VerifyKeys {
+		key, err := signing.LoadPublicKey(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load verification key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+	return signer, signing.NewVerifier(keys...), nil
+}
+
 // Close closes the command context and cleans up resources.
 func (ctx *CommandContext) Close() error {
 	if ctx.Client != nil {
@@ -88,12 +177,39 @@ func (ctx *CommandContext) Close() error {
 // PutCommand uploads a file to the object store.
 // If filePath is empty or "-", reads from stdin.
 func (ctx *CommandContext) PutCommand(key, filePath string) error {
-	return ctx.PutCommandWithMetadata(key, filePath, "", "", nil)
+	return ctx.PutCommandWithMetadata(key, filePath, "", "", "", nil)
+}
+
+// ExpiryTime returns the value of the common.MetaExpiresAt field for an
+// upload that expires at expiresAt (RFC 3339) or expiresIn after now.
+// Exactly one of them must be set.
+func ExpiryTime(expiresAt string, expiresIn time.Duration, now time.Time) (string, error) {
+	switch {
+	case expiresAt != "" && expiresIn == 0:
+		t, err := common.ParseExpiresAt(expiresAt)
+		if err != nil {
+			return "", ErrInvalidExpiry
+		}
+		return t.UTC().Format(time.RFC3339), nil
+	case expiresAt == "" && expiresIn > 0:
+		return now.Add(expiresIn).UTC().Format(time.RFC3339), nil
+	default:
+		return "", ErrInvalidExpiry
+	}
 }
 
 // PutCommandWithMetadata uploads a file to the object store with custom metadata.
-// If filePath is empty or "-", reads from stdin.
-func (ctx *CommandContext) PutCommandWithMetadata(key, filePath, contentType, contentEncoding string, customFields map[string]string) error {
+// If filePath is empty or "-", reads from stdin. An empty storageClass uses
+// the backend default.
+func (ctx *CommandContext) PutCommandWithMetadata(key, filePath, contentType, contentEncoding, storageClass string, customFields map[string]string) error {
+	_, err := ctx.PutCommandWithInfo(key, filePath, contentType, contentEncoding, storageClass, customFields)
+	return err
+}
+
+// PutCommandWithInfo is PutCommandWithMetadata returning what was stored:
+// the object's ETag, version, size and modification time where the
+// backend reports them. Atomic uploads report only the key.
+func (ctx *CommandContext) PutCommandWithInfo(key, filePath, contentType, contentEncoding, storageClass string, customFields map[string]string) (*common.ObjectInfo, error) {
 	var reader io.Reader
 	var metadata *common.Metadata
 
@@ -108,14 +224,14 @@ func (ctx *CommandContext) PutCommandWithMetadata(key, filePath, contentType, co
 		// Open the file
 		file, err := os.Open(filePath) // #nosec G304 -- User-provided path for CLI file operations, intended behavior
 		if err != nil {
-			return err
+			return nil, err
 		}
 		defer func() { _ = file.Close() }()
 
 		// Get file info for metadata
 		fileInfo, err := file.Stat()
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		reader = file
@@ -125,12 +241,25 @@ func (ctx *CommandContext) PutCommandWithMetadata(key, filePath, contentType, co
 	}
 
 	// Add custom metadata if provided
+	if contentType == "" && (ctx.Config == nil || !ctx.Config.SkipContentTypeDetection) {
+		// The key's extension decides what the object is served as; the
+		// local file's is used when the key has none.
+		name := key
+		if contentpolicy.TypeByExtension(key) == "" && filePath != "" && filePath != "-" {
+			name = filePath
+		}
+		var err error
+		if contentType, reader, err = contentpolicy.DetectReader(name, reader); err != nil {
+			return nil, err
+		}
+	}
 	if contentType != "" {
 		metadata.ContentType = contentType
 	}
 	if contentEncoding != "" {
 		metadata.ContentEncoding = contentEncoding
 	}
+	metadata.StorageClass = storageClass
 	if len(customFields) > 0 {
 		if metadata.Custom == nil {
 			metadata.Custom = make(map[string]string)
@@ -143,56 +272,58 @@ func (ctx *CommandContext) PutCommandWithMetadata(key, filePath, contentType, co
 	// Upload the data
 	ctxBg := context.Background()
 
+	if ctx.Config != nil && ctx.Config.AtomicPut {
+		if err := ctx.putAtomic(ctxBg, key, reader, metadata); err != nil {
+			return nil, err
+		}
+		return &common.ObjectInfo{Key: key}, nil
+	}
+
 	if ctx.Client != nil {
 		// Use remote client
 		return ctx.Client.Put(ctxBg, key, reader, metadata)
 	}
 
 	// Use local storage
-	if err := ctx.Storage.PutWithMetadata(ctxBg, key, reader, metadata); err != nil {
-		return err
-	}
+	return common.PutWithInfo(ctxBg, ctx.Storage, key, reader, metadata)
+}
 
-	return nil
+// putAtomic stages, verifies and then promotes an upload. A server that
+// supports atomic uploads does this itself; otherwise it is done through the
+// client, which costs a read back and a copy and stages the upload next to
+// key, since servers reserve the staging namespace. Clients that wrap
+// another, such as end-to-end encryption, always take the client path so
+// the data still passes through them.
+func (ctx *CommandContext) putAtomic(c context.Context, key string, reader io.Reader, metadata *common.Metadata) error {
+	if ctx.Client == nil {
+		return common.PutAtomic(c, ctx.Storage, key, reader, metadata)
+	}
+	if putter, ok := ctx.Client.(client.AtomicPutter); ok {
+		return putter.PutAtomic(c, key, reader, metadata)
+	}
+	return common.PutAtomicBeside(c, client.NewStorage(ctx.Client), key, reader, metadata)
 }
 
 // GetCommand downloads a file from the object store.
 func (ctx *CommandContext) GetCommand(key, outputPath string) error {
 	ctxBg := context.Background()
 
-	var reader io.ReadCloser
-	var err error
+	if ctx.Client == nil {
+		return ctx.downloadLocal(ctxBg, key, outputPath)
+	}
 
-	// Retrieve the object
-	if ctx.Client != nil {
-		// Use remote client
-		reader, _, err = ctx.Client.Get(ctxBg, key)
-		if err != nil {
-			return err
-		}
-	} else {
-		// Use local storage
-		reader, err = ctx.Storage.GetWithContext(ctxBg, key)
-		if err != nil {
-			return err
-		}
+	// Use remote client
+	reader, _, err := ctx.Client.Get(ctxBg, key)
+	if err != nil {
+		return err
 	}
 	defer func() { _ = reader.Close() }()
 
-	// Determine output destination
-	var writer io.Writer
-	if outputPath == "" || outputPath == "-" {
-		// Write to stdout
-		writer = os.Stdout
-	} else {
-		// Write to file
-		file, err := os.Create(outputPath) // #nosec G304 -- User-provided path for CLI file operations, intended behavior
-		if err != nil {
-			return err
-		}
-		defer func() { _ = file.Close() }()
-		writer = file
+	writer, closeFn, err := openOutput(outputPath)
+	if err != nil {
+		return err
 	}
+	defer closeFn()
 
 	// Copy the data
 	if _, err := io.Copy(writer, reader); err != nil {
@@ -202,6 +333,46 @@ func (ctx *CommandContext) GetCommand(key, outputPath string) error {
 	return nil
 }
 
+// downloadLocal writes an object from local storage to outputPath, fetching
+// large objects as parallel ranged parts when the backend supports it.
+func (ctx *CommandContext) downloadLocal(ctxBg context.Context, key, outputPath string) error {
+	// Check the key first so a missing object does not leave an empty file.
+	exists, err := ctx.Storage.Exists(ctxBg, key)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%w: %s", common.ErrKeyNotFound, key)
+	}
+
+	writer, closeFn, err := openOutput(outputPath)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	var opts *download.Options
+	if ctx.Config != nil {
+		opts = ctx.Config.GetDownloadOptions()
+	}
+	_, err = download.NewManager(opts).Download(ctxBg, ctx.Storage, key, writer)
+	return err
+}
+
+// openOutput returns the destination for downloaded content: stdout when
+// outputPath is empty or "-", otherwise a newly created file.
+func openOutput(outputPath string) (io.Writer, func(), error) {
+	if outputPath == "" || outputPath == "-" {
+		// Hide os.Stdout's WriteAt so pipes receive parts in order.
+		return struct{ io.Writer }{os.Stdout}, func() {}, nil
+	}
+	file, err := os.Create(outputPath) // #nosec G304 -- User-provided path for CLI file operations, intended behavior
+	if err != nil {
+		return nil, nil, err
+	}
+	return file, func() { _ = file.Close() }, nil
+}
+
 // DeleteCommand deletes an object from the object store.
 func (ctx *CommandContext) DeleteCommand(key string) error {
 	ctxBg := context.Background()
@@ -221,31 +392,100 @@ func (ctx *CommandContext) DeleteCommand(key string) error {
 
 // ListCommand lists objects in the object store with the given prefix.
 func (ctx *CommandContext) ListCommand(prefix string) ([]ObjectInfo, error) {
+	return ctx.ListObjectsCommand(prefix, ListOptions{})
+}
+
+// ListOptions controls the order of ListObjectsCommand and the metadata it
+// fetches.
+type ListOptions struct {
+	// SortBy orders the objects by name, size or modification time.
+	SortBy common.ListSort
+
+	// Reverse reverses the order.
+	Reverse bool
+
+	// Long fetches the metadata of objects listed without custom metadata,
+	// so their checksums and encryption key IDs are known. This costs one
+	// request per such object.
+	Long bool
+
+	// All follows continuation tokens until the listing is complete rather
+	// than returning the first page.
+	All bool
+}
+
+// ListObjectsCommand lists objects under prefix in the order given by opts.
+func (ctx *CommandContext) ListObjectsCommand(prefix string, opts ListOptions) ([]ObjectInfo, error) {
+	if _, err := common.ParseListSort(string(opts.SortBy)); err != nil {
+		return nil, err
+	}
 	ctxBg := context.Background()
 
 	// List objects
-	opts := &common.ListOptions{
-		Prefix: prefix,
+	listOpts := &common.ListOptions{
+		Prefix:         prefix,
+		SortBy:         opts.SortBy,
+		SortDescending: opts.Reverse,
 	}
 
-	var result *common.ListResult
-	var err error
-
-	if ctx.Client != nil {
-		// Use remote client
-		result, err = ctx.Client.List(ctxBg, opts)
-	} else {
-		// Use local storage
-		result, err = ctx.Storage.ListWithOptions(ctxBg, opts)
+	storage := ctx.objectStorage()
+	result := &common.ListResult{}
+	for {
+		page, err := storage.ListWithOptions(ctxBg, listOpts)
+		if err != nil {
+			return nil, err
+		}
+		if page == nil {
+			break
+		}
+		if opts.Long {
+			if err := fillMetadata(ctxBg, storage, page.Objects); err != nil {
+				return nil, err
+			}
+		}
+		result.Objects = append(result.Objects, page.Objects...)
+		if !opts.All || page.NextToken == "" || page.NextToken == listOpts.ContinueFrom {
+			break
+		}
+		listOpts.ContinueFrom = page.NextToken
 	}
-
-	if err != nil {
+	// Servers sort each page, but local storage and older servers do not.
+	if err := common.SortObjects(result.Objects, opts.SortBy, opts.Reverse); err != nil {
 		return nil, err
 	}
 
 	return ConvertListResultToObjectInfo(result), nil
 }
 
+// objectStorage returns the storage listings and moves work on: the local
+// backend, or the server adapted to common.Storage.
+func (ctx *CommandContext) objectStorage() common.Storage {
+	if ctx.Client != nil {
+		return client.NewStorage(ctx.Client)
+	}
+	return ctx.Storage
+}
+
+// fillMetadata fetches the metadata of the objects listed without custom
+// metadata, so their checksums and encryption key IDs are known.
+func fillMetadata(ctx context.Context, storage common.Storage, objects []*common.ObjectInfo) error {
+	for _, obj := range objects {
+		if obj == nil || (obj.Metadata != nil && obj.Metadata.Custom != nil) {
+			continue
+		}
+		metadata, err := storage.GetMetadata(ctx, obj.Key)
+		if errors.Is(err, common.ErrNotFound) {
+			// Deleted since it was listed, or stored without metadata
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get metadata of %s: %w", obj.Key, err)
+		}
+		obj.Metadata = metadata
+	}
+	return nil
+}
+
 // ExistsCommand checks if an object exists in the object store.
 func (ctx *CommandContext) ExistsCommand(key string) (bool, error) {
 	ctxBg := context.Background()
@@ -314,6 +554,12 @@ func (ctx *CommandContext) ArchiveCommandWithSettings(key, destinationBackend st
 // the storage backend region as the region fallback. See newPolicyArchiver
 // for the validation rules.
 func (ctx *CommandContext) AddPolicyCommand(id, prefix, retentionDays, action string) error {
+	return ctx.AddPolicyCommandWithStorageClass(id, prefix, retentionDays, action, "")
+}
+
+// AddPolicyCommandWithStorageClass adds a lifecycle policy. storageClass is
+// the target class of a "transition" action and is ignored otherwise.
+func (ctx *CommandContext) AddPolicyCommandWithStorageClass(id, prefix, retentionDays, action, storageClass string) error {
 	// Parse retention days
 	var retentionSeconds int64
 	if _, err := fmt.Sscanf(retentionDays, "%d", &retentionSeconds); err != nil {
@@ -329,6 +575,12 @@ func (ctx *CommandContext) AddPolicyCommand(id, prefix, retentionDays, action st
 		Retention: time.Duration(retentionSeconds) * time.Second,
 		Action:    action,
 	}
+	if action == common.ActionTransition {
+		if storageClass == "" {
+			return ErrStorageClassRequired
+		}
+		policy.StorageClass = storageClass
+	}
 
 	ctxBg := context.Background()
 
@@ -423,16 +675,16 @@ func (ctx *CommandContext) ApplyPoliciesCommand() error {
 		return err
 	}
 
-	if len(policies) == 0 {
-		return nil // No policies to apply
-	}
-
 	// Apply policies based on backend type
 	switch ctx.Config.Backend {
 	case BackendLocal:
-		// For local backend, we can apply policies directly
+		// For local backend, we can apply policies directly, and delete
+		// expired objects even without policies
 		return ctx.applyLocalPolicies(policies)
 	default:
+		if len(policies) == 0 {
+			return nil // No policies to apply
+		}
 		// For cloud backends, policies are managed by the cloud provider
 		return fmt.Errorf("%w: %s", ErrPolicyManagedByProvider, ctx.Config.Backend)
 	}
@@ -442,6 +694,12 @@ func (ctx *CommandContext) ApplyPoliciesCommand() error {
 func (ctx *CommandContext) applyLocalPolicies(policies []common.LifecyclePolicy) error {
 	ctxBg := context.Background()
 
+	// Objects past their recorded expiry are deleted whatever the policies
+	// say
+	if _, err := common.DeleteExpired(ctxBg, ctx.Storage, time.Now()); err != nil {
+		fmt.Fprintf(os.Stderr, "Error deleting expired objects: %v\n", err)
+	}
+
 	// List all objects
 	opts := &common.ListOptions{
 		Prefix: "",
@@ -483,6 +741,10 @@ func (ctx *CommandContext) applyLocalPolicies(policies []common.LifecyclePolicy)
 						fmt.Fprintf(os.Stderr, "Error archiving %s: %v\n", obj.Key, err)
 					}
 				}
+			case common.ActionTransition:
+				if err := ctx.transitionObject(ctxBg, obj.Key, policy.StorageClass); err != nil {
+					fmt.Fprintf(os.Stderr, "Error transitioning %s: %v\n", obj.Key, err)
+				}
 			}
 		}
 	}
@@ -490,6 +752,19 @@ func (ctx *CommandContext) applyLocalPolicies(policies []common.LifecyclePolicy)
 	return nil
 }
 
+// transitionObject moves key to storageClass, preserving its other metadata.
+func (ctx *CommandContext) transitionObject(ctxBg context.Context, key, storageClass string) error {
+	metadata, err := ctx.Storage.GetMetadata(ctxBg, key)
+	if err != nil {
+		return err
+	}
+	if metadata.StorageClass == storageClass {
+		return nil
+	}
+	metadata.StorageClass = storageClass
+	return ctx.Storage.UpdateMetadata(ctxBg, key, metadata)
+}
+
 // GetMetadataCommand retrieves metadata for an object.
 func (ctx *CommandContext) GetMetadataCommand(key string) (*common.Metadata, error) {
 	ctxBg := context.Background()
@@ -0,0 +1,84 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package cli
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/backup"
+)
+
+func TestAdminBackupAndRestoreCommands(t *testing.T) {
+	dir := t.TempDir()
+	backendPath := filepath.Join(dir, "data")
+	ctx, err := NewCommandContext(&Config{Backend: BackendLocal, BackendPath: backendPath, OutputFormat: "text"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.AddPolicyCommand("expire-logs", "logs/", "30", "delete"); err != nil {
+		t.Fatalf("AddPolicyCommand: %v", err)
+	}
+	overlays := filepath.Join(dir, "overlays.yaml")
+	if err := os.WriteFile(overlays, []byte("overlays: []\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ctx.AdminBackupCommand("", "", backup.Sources{}); !errors.Is(err, ErrBackupLocationRequired) {
+		t.Errorf("backup without a location: error = %v, want ErrBackupLocationRequired", err)
+	}
+
+	// Back up to a file and, as an object, to the backend itself.
+	archivePath := filepath.Join(dir, "state.tgz")
+	manifest, err := ctx.AdminBackupCommand(archivePath, "", backup.Sources{ConfigFiles: []string{overlays}})
+	if err != nil {
+		t.Fatalf("AdminBackupCommand: %v", err)
+	}
+	if len(manifest.Files) != 2 {
+		t.Fatalf("backed up %d files, want the lifecycle policies and overlays", len(manifest.Files))
+	}
+	if !strings.Contains(FormatBackupManifest(manifest, FormatText), "Backed up 2 files") {
+		t.Errorf("unexpected output: %s", FormatBackupManifest(manifest, FormatText))
+	}
+	if _, err := ctx.AdminBackupCommand("", "backups/state.tgz", backup.Sources{}); err != nil {
+		t.Fatalf("AdminBackupCommand to a key: %v", err)
+	}
+
+	// Restore both onto a new node.
+	for _, tc := range []struct{ archive, key string }{{archivePath, ""}, {"", "backups/state.tgz"}} {
+		stateDir := filepath.Join(t.TempDir(), "data")
+		files, err := ctx.AdminRestoreCommand(tc.archive, tc.key, backup.Targets{StateDir: stateDir, ConfigDir: t.TempDir()})
+		if err != nil {
+			t.Fatalf("AdminRestoreCommand(%q, %q): %v", tc.archive, tc.key, err)
+		}
+		if len(files) == 0 {
+			t.Fatalf("AdminRestoreCommand(%q, %q) restored nothing", tc.archive, tc.key)
+		}
+
+		restored, err := NewCommandContext(&Config{Backend: BackendLocal, BackendPath: stateDir, OutputFormat: "text"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		policies, err := restored.ListPoliciesCommand()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(policies) != 1 || policies[0].ID != "expire-logs" {
+			t.Errorf("restored policies = %+v", policies)
+		}
+	}
+}
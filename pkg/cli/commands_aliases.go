@@ -0,0 +1,153 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/alias"
+	"github.com/jeremyhahn/go-objstore/pkg/cli/client"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// localAliases adapts an alias.Manager to the client alias API, so alias
+// commands work the same in local and remote mode.
+type localAliases struct {
+	manager *alias.Manager
+}
+
+func (l localAliases) SetAlias(ctx context.Context, name, target string) (*alias.Alias, error) {
+	return l.manager.Set(ctx, name, target)
+}
+
+func (l localAliases) ResolveAlias(ctx context.Context, name string) (*alias.Alias, error) {
+	return l.manager.Resolve(ctx, name)
+}
+
+func (l localAliases) ListAliases(ctx context.Context, prefix string) ([]alias.Alias, error) {
+	return l.manager.List(ctx, prefix)
+}
+
+func (l localAliases) DeleteAlias(ctx context.Context, name string) error {
+	return l.manager.Delete(ctx, name)
+}
+
+// aliasesClient returns the alias API: the server's in remote mode, or the
+// manager of the local backend's aliases.
+func (ctx *CommandContext) aliasesClient() (client.AliasManager, error) {
+	if ctx.Client != nil {
+		manager, ok := client.Optional[client.AliasManager](ctx.Client)
+		if !ok {
+			return nil, ErrAliasesNotSupported
+		}
+		return manager, nil
+	}
+
+	for s := ctx.Storage; s != nil; {
+		if resolver, ok := s.(*alias.Storage); ok {
+			return localAliases{resolver.Manager()}, nil
+		}
+		wrapper, ok := s.(interface{ Underlying() common.Storage })
+		if !ok {
+			break
+		}
+		s = wrapper.Underlying()
+	}
+	manager, err := alias.NewManager(ctx.Storage, "")
+	if err != nil {
+		return nil, err
+	}
+	return localAliases{manager}, nil
+}
+
+// AliasSetCommand points an alias at an object
+func (ctx *CommandContext) AliasSetCommand(name, target string) (*alias.Alias, error) {
+	manager, err := ctx.aliasesClient()
+	if err != nil {
+		return nil, err
+	}
+	return manager.SetAlias(context.Background(), name, target)
+}
+
+// AliasGetCommand resolves an alias to the key of its target
+func (ctx *CommandContext) AliasGetCommand(name string) (*alias.Alias, error) {
+	manager, err := ctx.aliasesClient()
+	if err != nil {
+		return nil, err
+	}
+	return manager.ResolveAlias(context.Background(), name)
+}
+
+// AliasListCommand lists the aliases whose names start with prefix
+func (ctx *CommandContext) AliasListCommand(prefix string) ([]alias.Alias, error) {
+	manager, err := ctx.aliasesClient()
+	if err != nil {
+		return nil, err
+	}
+	return manager.ListAliases(context.Background(), prefix)
+}
+
+// AliasDeleteCommand removes an alias
+func (ctx *CommandContext) AliasDeleteCommand(name string) error {
+	manager, err := ctx.aliasesClient()
+	if err != nil {
+		return err
+	}
+	return manager.DeleteAlias(context.Background(), name)
+}
+
+// FormatAliasesResult formats aliases for output
+func FormatAliasesResult(aliases []alias.Alias, format OutputFormat) string {
+	switch format {
+	case FormatJSON:
+		return formatJSON(aliases)
+	case FormatTable:
+		if len(aliases) == 0 {
+			return "No aliases\n"
+		}
+		rows := make([][]string, len(aliases))
+		for i, a := range aliases {
+			rows[i] = []string{a.Name, a.Target, a.UpdatedAt.Format("2006-01-02 15:04"), orUnknown(a.UpdatedBy)}
+		}
+		return formatBoxTable([]string{"Alias", "Target", "Updated", "By"}, rows)
+	default:
+		if len(aliases) == 0 {
+			return "No aliases\n"
+		}
+		var output strings.Builder
+		for _, a := range aliases {
+			output.WriteString(fmt.Sprintf("%s -> %s\n", a.Name, a.Target))
+		}
+		return output.String()
+	}
+}
+
+// FormatAliasResult formats one alias for output
+func FormatAliasResult(a *alias.Alias, format OutputFormat) string {
+	switch format {
+	case FormatJSON:
+		return formatJSON(a)
+	case FormatTable:
+		return FormatAliasesResult([]alias.Alias{*a}, format)
+	default:
+		updated := a.UpdatedAt.Format(time.RFC3339)
+		if a.UpdatedBy != "" {
+			updated += " by " + a.UpdatedBy
+		}
+		return fmt.Sprintf("%s -> %s\n  Updated: %s\n", a.Name, a.Target, updated)
+	}
+}
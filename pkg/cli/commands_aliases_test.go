@@ -0,0 +1,81 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/alias"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+func TestAliasCommands_LocalMode(t *testing.T) {
+	backend := memory.New()
+	if err := backend.PutWithContext(context.Background(), "releases/v2/app", strings.NewReader("v2")); err != nil {
+		t.Fatal(err)
+	}
+	manager, err := alias.NewManager(backend, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := &CommandContext{Storage: alias.NewStorage(backend, manager), Config: &Config{}}
+
+	set, err := ctx.AliasSetCommand("latest", "releases/v2/app")
+	if err != nil {
+		t.Fatalf("AliasSetCommand() error = %v", err)
+	}
+	if got := FormatAliasResult(set, FormatText); !strings.HasPrefix(got, "latest -> releases/v2/app\n") {
+		t.Errorf("text output = %q", got)
+	}
+
+	out := filepath.Join(t.TempDir(), "app")
+	if err := ctx.GetCommand("latest", out); err != nil {
+		t.Fatalf("GetCommand(latest) error = %v", err)
+	}
+	if data, _ := os.ReadFile(out); string(data) != "v2" {
+		t.Errorf("GetCommand(latest) = %q, want v2", data)
+	}
+
+	resolved, err := ctx.AliasGetCommand("latest")
+	if err != nil || resolved.Target != "releases/v2/app" {
+		t.Errorf("AliasGetCommand() = %+v, %v", resolved, err)
+	}
+	aliases, err := ctx.AliasListCommand("")
+	if err != nil || len(aliases) != 1 {
+		t.Fatalf("AliasListCommand() = %+v, %v", aliases, err)
+	}
+	if table := FormatAliasesResult(aliases, FormatTable); !strings.Contains(table, "│ latest") {
+		t.Errorf("table output missing alias row:\n%s", table)
+	}
+	var decoded []alias.Alias
+	if err := json.Unmarshal([]byte(FormatAliasesResult(aliases, FormatJSON)), &decoded); err != nil || decoded[0].Target != "releases/v2/app" {
+		t.Errorf("JSON output = %+v, %v", decoded, err)
+	}
+
+	if err := ctx.AliasDeleteCommand("latest"); err != nil {
+		t.Fatalf("AliasDeleteCommand() error = %v", err)
+	}
+	if _, err := ctx.AliasGetCommand("latest"); !errors.Is(err, alias.ErrAliasNotFound) {
+		t.Errorf("AliasGetCommand() after delete = %v, want ErrAliasNotFound", err)
+	}
+	if got := FormatAliasesResult(nil, FormatText); got != "No aliases\n" {
+		t.Errorf("empty output = %q", got)
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/access"
+	"github.com/jeremyhahn/go-objstore/pkg/cli/client"
+)
+
+// heatmapWidth is the width of the bars in text analysis reports.
+const heatmapWidth = 30
+
+// AnalyzeCommand reports how the objects under prefix are distributed by
+// age and by the time since they were last read. With a server, the
+// server's recorded reads are used. In local mode the reads persisted to
+// accessFile, the state file of a server's access tracking, are used; when
+// it is empty only ages are reported.
+func (ctx *CommandContext) AnalyzeCommand(prefix string, opts *access.AnalyzeOptions, accessFile string) (*access.Report, error) {
+	if ctx.Client != nil {
+		analyzer, ok := client.Optional[client.Analyzer](ctx.Client)
+		if !ok {
+			return nil, ErrAnalyzeNotSupported
+		}
+		return analyzer.Analyze(context.Background(), prefix, opts)
+	}
+
+	var tracker *access.Tracker
+	if accessFile != "" {
+		var err error
+		if tracker, err = access.Load(accessFile); err != nil {
+			return nil, err
+		}
+	}
+	return access.Analyze(context.Background(), ctx.Storage, tracker, prefix, opts)
+}
+
+// FormatAnalyzeReport formats an age and access report for output
+func FormatAnalyzeReport(report *access.Report, format OutputFormat) string {
+	switch format {
+	case FormatJSON:
+		return formatJSON(report)
+	case FormatTable:
+		return formatAnalyzeSummary(report) + formatAnalyzeTable(report)
+	default:
+		return formatAnalyzeText(report)
+	}
+}
+
+func formatAnalyzeSummary(report *access.Report) string {
+	total := report.Total
+	summary := fmt.Sprintf("%s: %d objects, %s", groupLabel(total.Prefix), total.Objects, formatSize(total.Bytes))
+	if report.TrackingSince != nil {
+		summary += fmt.Sprintf(" (reads tracked since %s)", report.TrackingSince.Format(time.DateOnly))
+	} else {
+		summary += " (reads not tracked)"
+	}
+	return summary + "\n"
+}
+
+func formatAnalyzeText(report *access.Report) string {
+	var output strings.Builder
+	output.WriteString(formatAnalyzeSummary(report))
+	output.WriteString("\nAge since last modified:\n")
+	writeHeatmap(&output, report.Total.Age, report.Total.Bytes)
+	if report.Total.Access != nil {
+		output.WriteString("\nTime since last read:\n")
+		writeHeatmap(&output, report.Total.Access, report.Total.Bytes)
+	}
+	if len(report.Prefixes) > 0 {
+		output.WriteString("\nBy prefix:\n")
+		output.WriteString(formatAnalyzeTable(report))
+	}
+	return output.String()
+}
+
+// writeHeatmap writes a line per bucket with a bar proportional to its
+// share of total bytes.
+func writeHeatmap(output *strings.Builder, buckets []access.Bucket, total int64) {
+	for _, b := range buckets {
+		width := 0
+		if total > 0 {
+			width = int(b.Bytes * heatmapWidth / total)
+		}
+		if width == 0 && b.Bytes > 0 {
+			width = 1
+		}
+		output.WriteString(fmt.Sprintf("  %-10s %8d objects %10s  %s\n",
+			b.Label, b.Objects, formatSize(b.Bytes), strings.Repeat("█", width)))
+	}
+}
+
+// formatAnalyzeTable renders the objects of each prefix by time since last
+// read, or by age when reads are not tracked.
+func formatAnalyzeTable(report *access.Report) string {
+	distributions := report.Prefixes
+	if len(distributions) == 0 {
+		distributions = []access.Distribution{report.Total}
+	}
+	buckets := func(d access.Distribution) []access.Bucket { return d.Age }
+	if report.Total.Access != nil {
+		buckets = func(d access.Distribution) []access.Bucket { return d.Access }
+	}
+
+	header := []string{"Prefix", "Objects", "Size"}
+	for _, b := range buckets(report.Total) {
+		header = append(header, b.Label)
+	}
+	rows := make([][]string, 0, len(distributions))
+	for _, d := range distributions {
+		row := []string{groupLabel(d.Prefix), fmt.Sprintf("%d", d.Objects), formatSize(d.Bytes)}
+		for _, b := range buckets(d) {
+			row = append(row, fmt.Sprintf("%d", b.Objects))
+		}
+		rows = append(rows, row)
+	}
+	return formatBoxTable(header, rows)
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package cli
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/access"
+)
+
+func TestAnalyzeCommand(t *testing.T) {
+	ctx := newExportTestContext(t, 3)
+
+	// Without an access file only ages are reported
+	report, err := ctx.AnalyzeCommand("", nil, "")
+	if err != nil {
+		t.Fatalf("AnalyzeCommand() error = %v", err)
+	}
+	if report.Total.Objects != 3 || report.Total.Access != nil || len(report.Prefixes) != 1 {
+		t.Errorf("report = %+v, want 3 objects under one prefix without reads", report)
+	}
+	if out := FormatAnalyzeReport(report, FormatText); !strings.Contains(out, "reads not tracked") || !strings.Contains(out, "0-1d") {
+		t.Errorf("FormatAnalyzeReport() = %q, want ages without reads", out)
+	}
+
+	// A server's saved reads are picked up from its access file
+	path := filepath.Join(t.TempDir(), "access.json")
+	tracker, err := access.NewTracker(&access.Config{Path: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracker.Record("data/001.bin")
+	if err := tracker.Close(); err != nil {
+		t.Fatal(err)
+	}
+	report, err = ctx.AnalyzeCommand("data/", &access.AnalyzeOptions{Depth: -1}, path)
+	if err != nil {
+		t.Fatalf("AnalyzeCommand() error = %v", err)
+	}
+	never := report.Total.Access[len(report.Total.Access)-1]
+	if report.Total.Access[0].Objects != 1 || never.Objects != 2 {
+		t.Errorf("Access = %+v, want one read today and two never read", report.Total.Access)
+	}
+	for _, format := range []OutputFormat{FormatText, FormatTable} {
+		if out := FormatAnalyzeReport(report, format); !strings.Contains(out, access.NeverLabel) {
+			t.Errorf("FormatAnalyzeReport(%s) = %q, want the never read bucket", format, out)
+		}
+	}
+
+	if _, err := ctx.AnalyzeCommand("", nil, filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("AnalyzeCommand() with a missing access file succeeded")
+	}
+}
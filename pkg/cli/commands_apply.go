@@ -0,0 +1,140 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jeremyhahn/go-objstore/pkg/cli/client"
+	"github.com/jeremyhahn/go-objstore/pkg/reconcile"
+)
+
+// ApplyResult is the outcome of applying a desired state file.
+type ApplyResult struct {
+	Plan *reconcile.Plan `json:"plan"`
+
+	// DryRun is set when the plan was only computed.
+	DryRun bool `json:"dry_run"`
+
+	// Applied counts the changes made.
+	Applied int `json:"applied"`
+}
+
+// ApplyCommand brings the server to the desired state declared in the
+// file at path: it computes the lifecycle policies, replication policies,
+// quotas, webhooks and aliases to create, update and delete and, unless dryRun is set,
+// makes those changes. When a change fails, the result reports the ones
+// made before it along with the error.
+func (ctx *CommandContext) ApplyCommand(path string, dryRun bool) (*ApplyResult, error) {
+	if ctx.Client == nil {
+		return nil, ErrApplyRequiresServer
+	}
+	file, err := reconcile.LoadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	target := reconcile.Target{Policies: ctx.Client, Replication: ctx.Client}
+	if quotas, ok := client.Optional[client.QuotaManager](ctx.Client); ok {
+		target.Quotas = quotas
+	}
+	if webhooks, ok := client.Optional[client.WebhookManager](ctx.Client); ok {
+		target.Webhooks = webhooks
+	}
+	if aliases, ok := client.Optional[client.AliasManager](ctx.Client); ok {
+		target.Aliases = aliases
+	}
+	plan, err := reconcile.Diff(context.Background(), target, file)
+	if err != nil {
+		return nil, err
+	}
+	result := &ApplyResult{Plan: plan, DryRun: dryRun}
+	if dryRun {
+		return result, nil
+	}
+	result.Applied, err = reconcile.Apply(context.Background(), target, plan)
+	return result, err
+}
+
+// FormatApplyResult formats the plan and outcome of an apply for output
+func FormatApplyResult(result *ApplyResult, format OutputFormat) string {
+	switch format {
+	case FormatJSON:
+		return formatJSON(result)
+	case FormatTable:
+		return formatApplyTable(result) + formatApplySummary(result)
+	default:
+		return formatApplyText(result) + formatApplySummary(result)
+	}
+}
+
+// applySymbols mark the operation of each change in text output.
+var applySymbols = map[string]string{
+	reconcile.OpCreate: "+",
+	reconcile.OpUpdate: "~",
+	reconcile.OpDelete: "-",
+}
+
+func formatApplyText(result *ApplyResult) string {
+	var output strings.Builder
+	for _, change := range result.Plan.Changes {
+		output.WriteString(fmt.Sprintf("%s %s %s\n", applySymbols[change.Op], change.Kind, change.ID))
+		for _, field := range change.Fields {
+			output.WriteString(fmt.Sprintf("    %s: %s -> %s\n", field.Field, quoteEmpty(field.From), quoteEmpty(field.To)))
+		}
+	}
+	return output.String()
+}
+
+func formatApplyTable(result *ApplyResult) string {
+	if result.Plan.Empty() {
+		return ""
+	}
+	header := []string{"Op", "Kind", "ID", "Changes"}
+	rows := make([][]string, 0, len(result.Plan.Changes))
+	for _, change := range result.Plan.Changes {
+		fields := make([]string, 0, len(change.Fields))
+		for _, field := range change.Fields {
+			fields = append(fields, fmt.Sprintf("%s: %s -> %s", field.Field, quoteEmpty(field.From), quoteEmpty(field.To)))
+		}
+		rows = append(rows, []string{change.Op, change.Kind, change.ID, strings.Join(fields, "; ")})
+	}
+	return formatBoxTable(header, rows)
+}
+
+func formatApplySummary(result *ApplyResult) string {
+	if result.Plan.Empty() {
+		return "No changes: the server is in the desired state.\n"
+	}
+	counts := make(map[string]int)
+	for _, change := range result.Plan.Changes {
+		counts[change.Op]++
+	}
+	summary := fmt.Sprintf("Plan: %d to create, %d to update, %d to delete.",
+		counts[reconcile.OpCreate], counts[reconcile.OpUpdate], counts[reconcile.OpDelete])
+	if result.DryRun {
+		return summary + " Nothing was changed (dry run).\n"
+	}
+	return summary + fmt.Sprintf(" Applied %d of %d changes.\n", result.Applied, len(result.Plan.Changes))
+}
+
+// quoteEmpty shows an empty value as "".
+func quoteEmpty(s string) string {
+	if s == "" {
+		return `""`
+	}
+	return s
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package cli
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/cli/client"
+)
+
+func TestApplyCommand(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && r.URL.Path == "/api/v1/policies" {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"policies":[
+				{"id":"temp","prefix":"tmp/","retention_seconds":86400,"action":"delete"},
+				{"id":"logs","prefix":"logs/","retention_seconds":604800,"action":"delete"}
+			],"count":2}`))
+			return
+		}
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+	defer server.Close()
+
+	remote, err := client.NewClient(&client.Config{ServerURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := &CommandContext{Client: remote, Config: &Config{}}
+
+	path := filepath.Join(t.TempDir(), "objstore.yaml")
+	data := "policies:\n  - id: logs\n    prefix: logs/\n    retention_days: 30\n    action: delete\n"
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ctx.ApplyCommand(path, true)
+	if err != nil {
+		t.Fatalf("ApplyCommand() dry run error = %v", err)
+	}
+	if len(result.Plan.Changes) != 2 || len(requests) != 0 {
+		t.Fatalf("dry run plan = %+v, requests %v, want two changes and no requests", result.Plan.Changes, requests)
+	}
+	out := FormatApplyResult(result, FormatText)
+	for _, want := range []string{"~ policy logs", "retention: 168h0m0s -> 720h0m0s", "- policy temp", "dry run"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("FormatApplyResult() = %q, want %q", out, want)
+		}
+	}
+
+	result, err = ctx.ApplyCommand(path, false)
+	if err != nil || result.Applied != 2 {
+		t.Fatalf("ApplyCommand() = %+v, %v", result, err)
+	}
+	want := []string{"DELETE /api/v1/policies/logs", "POST /api/v1/policies", "DELETE /api/v1/policies/temp"}
+	if !slices.Equal(requests, want) {
+		t.Errorf("requests = %v, want %v", requests, want)
+	}
+	for _, format := range []OutputFormat{FormatTable, FormatJSON} {
+		if out := FormatApplyResult(result, format); !strings.Contains(out, "temp") {
+			t.Errorf("FormatApplyResult(%s) = %q", format, out)
+		}
+	}
+}
+
+func TestApplyCommand_LocalMode(t *testing.T) {
+	ctx := &CommandContext{Config: &Config{}}
+	if _, err := ctx.ApplyCommand("objstore.yaml", true); !errors.Is(err, ErrApplyRequiresServer) {
+		t.Errorf("expected ErrApplyRequiresServer, got %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/audit"
+	"github.com/jeremyhahn/go-objstore/pkg/factory"
+)
+
+func TestVerifyAuditChainCommand(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "audit.chain")
+	anchorDir := filepath.Join(dir, "anchors")
+
+	anchorStorage, err := factory.NewStorage("local", map[string]string{"path": anchorDir})
+	if err != nil {
+		t.Fatalf("NewStorage: %v", err)
+	}
+	logger, err := audit.NewChainedAuditLogger(&audit.ChainConfig{
+		Path:               logPath,
+		CheckpointInterval: 2,
+		Anchor:             audit.NewStorageAnchor(anchorStorage, ""),
+	})
+	if err != nil {
+		t.Fatalf("NewChainedAuditLogger: %v", err)
+	}
+	for _, user := range []string{"alice", "bob", "carol"} {
+		if err := logger.LogAuthSuccess(context.Background(), user, user, "", ""); err != nil {
+			t.Fatalf("LogAuthSuccess: %v", err)
+		}
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	result, err := VerifyAuditChainCommand(logPath, anchorDir)
+	if err != nil {
+		t.Fatalf("VerifyAuditChainCommand: %v", err)
+	}
+	if result.Records != 5 || result.Anchored != 2 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if out := FormatAuditVerificationResult(result, FormatText); !strings.Contains(out, "Checkpoints: 2 (2 anchored)") {
+		t.Errorf("unexpected text output: %q", out)
+	}
+	if out := FormatAuditVerificationResult(result, FormatJSON); !strings.Contains(out, `"records": 5`) {
+		t.Errorf("unexpected JSON output: %q", out)
+	}
+
+	if _, err := VerifyAuditChainCommand(logPath, filepath.Join(dir, "missing")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected a missing anchor directory to fail, got %v", err)
+	}
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tampered := strings.Replace(string(data), `"user_id":"bob"`, `"user_id":"eve"`, 1)
+	if err := os.WriteFile(logPath, []byte(tampered), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := VerifyAuditChainCommand(logPath, anchorDir); !errors.Is(err, audit.ErrChainTampered) {
+		t.Errorf("expected ErrChainTampered, got %v", err)
+	}
+}
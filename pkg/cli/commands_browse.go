@@ -0,0 +1,37 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package cli
+
+import (
+	"context"
+
+	"github.com/jeremyhahn/go-objstore/pkg/cli/client"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// BrowseCommand returns the folders and objects directly under prefix.
+// Servers that support it browse in a single request; otherwise, and in
+// local mode, the children are grouped from a delimiter listing. With
+// totals, each folder counts the objects and bytes below it.
+func (ctx *CommandContext) BrowseCommand(prefix string, totals bool) (*common.BrowseResult, error) {
+	ctxBg := context.Background()
+	opts := &common.BrowseOptions{Totals: totals}
+	if ctx.Client != nil {
+		if browser, ok := client.Optional[client.Browser](ctx.Client); ok {
+			return browser.Browse(ctxBg, prefix, opts)
+		}
+		return common.Browse(ctxBg, client.NewStorage(ctx.Client), prefix, opts)
+	}
+	return common.Browse(ctxBg, ctx.Storage, prefix, opts)
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+func TestBrowseCommand_LocalMode(t *testing.T) {
+	storage := memory.New()
+	for _, key := range []string{"logs/a.log", "logs/2025/b.log", "logs/2025/c.log"} {
+		if err := storage.PutWithContext(context.Background(), key, strings.NewReader(key)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ctx := &CommandContext{Storage: storage, Config: &Config{}}
+
+	result, err := ctx.BrowseCommand("logs/", true)
+	if err != nil {
+		t.Fatalf("BrowseCommand() error = %v", err)
+	}
+	if len(result.Folders) != 1 || result.Folders[0].Objects != 2 || len(result.Objects) != 1 {
+		t.Fatalf("BrowseCommand() = %+v", result)
+	}
+
+	text := FormatBrowseResult(result, FormatText, false)
+	for _, want := range []string{"NAME", "2025/", "a.log", "Total: 1 folder(s), 3 object(s)"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text output missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "logs/") {
+		t.Errorf("text output names children with the browsed prefix:\n%s", text)
+	}
+	if table := FormatBrowseResult(result, FormatTable, true); !strings.Contains(table, "│ 2025/") {
+		t.Errorf("table output missing folder row:\n%s", table)
+	}
+	var decoded common.BrowseResult
+	if err := json.Unmarshal([]byte(FormatBrowseResult(result, FormatJSON, false)), &decoded); err != nil || decoded.TotalObjects != 3 {
+		t.Errorf("JSON output = %+v, %v", decoded, err)
+	}
+
+	empty, err := ctx.BrowseCommand("missing/", false)
+	if err != nil {
+		t.Fatalf("BrowseCommand(missing) error = %v", err)
+	}
+	if got := FormatBrowseResult(empty, FormatText, false); got != "No objects found\n" {
+		t.Errorf("empty output = %q", got)
+	}
+}
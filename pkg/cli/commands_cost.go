@@ -0,0 +1,123 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jeremyhahn/go-objstore/pkg/cli/client"
+	"github.com/jeremyhahn/go-objstore/pkg/cost"
+)
+
+// CostReportCommand estimates the cost of month (YYYY-MM, the current month
+// when empty). With a server, the server's metered usage is reported. In
+// local mode the usage persisted to the state file of the cost
+// configuration at configPath is priced instead.
+func (ctx *CommandContext) CostReportCommand(month, configPath string) (*cost.Report, error) {
+	if ctx.Client != nil {
+		reporter, ok := client.Optional[client.CostReporter](ctx.Client)
+		if !ok {
+			return nil, ErrCostNotSupported
+		}
+		return reporter.CostReport(context.Background(), month)
+	}
+
+	if configPath == "" {
+		return nil, ErrCostConfigRequired
+	}
+	cfg, err := cost.LoadFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.StatePath == "" {
+		return nil, ErrCostConfigRequired
+	}
+	meter, err := cost.NewMeter(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return meter.Report(month)
+}
+
+// FormatCostReport formats a cost report for output.
+func FormatCostReport(report *cost.Report, format OutputFormat) string {
+	switch format {
+	case FormatJSON:
+		return formatJSON(report)
+	case FormatTable:
+		return formatCostReportTable(report)
+	default:
+		return formatCostReportText(report)
+	}
+}
+
+// groupLabel names the group of keys without a top-level prefix.
+func groupLabel(group string) string {
+	if group == "" {
+		return "(root)"
+	}
+	return group
+}
+
+func formatCostReportText(report *cost.Report) string {
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("Cost Report: %s (%s)\n", report.Month, report.Currency))
+	output.WriteString(fmt.Sprintf("  Total: %.2f\n", report.Total.Total))
+	output.WriteString(fmt.Sprintf("  Projected: %.2f\n", report.Projected.Total))
+	if len(report.Lines) == 0 {
+		output.WriteString("\nNo usage recorded\n")
+		return output.String()
+	}
+
+	output.WriteString("\nBy group:\n")
+	for _, g := range report.Groups {
+		output.WriteString(fmt.Sprintf("  %s: %.2f (projected %.2f)\n", groupLabel(g.Group), g.Cost.Total, g.Projected.Total))
+	}
+
+	output.WriteString("\nBy backend and group:\n")
+	for i := range report.Lines {
+		l := &report.Lines[i]
+		output.WriteString(fmt.Sprintf("%s %s\n", l.Backend, groupLabel(l.Group)))
+		output.WriteString(fmt.Sprintf("  Stored: %s (%.3f GiB-months)\n", formatSize(l.StoredBytes), l.GBMonths))
+		output.WriteString(fmt.Sprintf("  Requests: %d write, %d read, %d list, %d delete\n",
+			l.Requests[cost.RequestWrite], l.Requests[cost.RequestRead], l.Requests[cost.RequestList], l.Requests[cost.RequestDelete]))
+		output.WriteString(fmt.Sprintf("  Egress: %s\n", formatSize(l.EgressBytes)))
+		output.WriteString(fmt.Sprintf("  Cost: %.2f storage + %.2f requests + %.2f egress = %.2f\n",
+			l.Cost.Storage, l.Cost.Requests, l.Cost.Egress, l.Cost.Total))
+		output.WriteString(fmt.Sprintf("  Projected: %.2f\n", l.Projected.Total))
+	}
+	return output.String()
+}
+
+func formatCostReportTable(report *cost.Report) string {
+	var output strings.Builder
+	output.WriteString("┌──────────────┬──────────────────────┬────────────┬────────────┬────────────┬────────────┬────────────┐\n")
+	output.WriteString("│ Backend      │ Group                │ Storage    │ Requests   │ Egress     │ Total      │ Projected  │\n")
+	output.WriteString("├──────────────┼──────────────────────┼────────────┼────────────┼────────────┼────────────┼────────────┤\n")
+	for i := range report.Lines {
+		l := &report.Lines[i]
+		output.WriteString(fmt.Sprintf("│ %-12s │ %-20s │ %10.2f │ %10.2f │ %10.2f │ %10.2f │ %10.2f │\n",
+			truncateString(l.Backend, 12),
+			truncateString(groupLabel(l.Group), 20),
+			l.Cost.Storage, l.Cost.Requests, l.Cost.Egress, l.Cost.Total, l.Projected.Total))
+	}
+	output.WriteString("├──────────────┴──────────────────────┼────────────┼────────────┼────────────┼────────────┼────────────┤\n")
+	output.WriteString(fmt.Sprintf("│ %-35s │ %10.2f │ %10.2f │ %10.2f │ %10.2f │ %10.2f │\n",
+		fmt.Sprintf("Total %s %s", report.Month, report.Currency),
+		report.Total.Storage, report.Total.Requests, report.Total.Egress, report.Total.Total, report.Projected.Total))
+	output.WriteString("└─────────────────────────────────────┴────────────┴────────────┴────────────┴────────────┴────────────┘\n")
+	return output.String()
+}
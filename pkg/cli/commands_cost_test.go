@@ -0,0 +1,79 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/cost"
+)
+
+func TestCostReportCommand(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "cost.yaml")
+	statePath := filepath.Join(dir, "cost.json")
+	config := "defaultPricing:\n  writePer1000: 10\ngroups:\n  - name: analytics\n    prefixes: [reports/]\nstatePath: " + statePath + "\n"
+	if err := os.WriteFile(configPath, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Usage recorded by a server and persisted to the state file.
+	costConfig, err := cost.LoadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	meter, err := cost.NewMeter(costConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	meter.Request("default", "reports/q1.csv", cost.RequestWrite)
+	meter.Resize("default", "reports/q1.csv", 2048)
+	if err := meter.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, err := NewCommandContext(&Config{Backend: BackendLocal, BackendPath: dir, OutputFormat: "text"})
+	if err != nil {
+		t.Fatalf("NewCommandContext: %v", err)
+	}
+	defer ctx.Close()
+
+	if _, err := ctx.CostReportCommand("", ""); !errors.Is(err, ErrCostConfigRequired) {
+		t.Errorf("CostReportCommand() without config error = %v, want ErrCostConfigRequired", err)
+	}
+	report, err := ctx.CostReportCommand("", configPath)
+	if err != nil {
+		t.Fatalf("CostReportCommand: %v", err)
+	}
+	if len(report.Lines) != 1 || report.Lines[0].Group != "analytics" || report.Lines[0].StoredBytes != 2048 || report.Total.Requests != 0.01 {
+		t.Errorf("report = %+v", report)
+	}
+
+	text := FormatCostReport(report, FormatText)
+	if !strings.Contains(text, "analytics") || !strings.Contains(text, "1 write") {
+		t.Errorf("text output = %q", text)
+	}
+	if table := FormatCostReport(report, FormatTable); !strings.Contains(table, "analytics") {
+		t.Errorf("table output = %q", table)
+	}
+	var decoded cost.Report
+	if err := json.Unmarshal([]byte(FormatCostReport(report, FormatJSON)), &decoded); err != nil || decoded.Month != report.Month {
+		t.Errorf("json output = %+v, %v", decoded, err)
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jeremyhahn/go-objstore/pkg/dedup"
+	"github.com/jeremyhahn/go-objstore/pkg/search"
+)
+
+// DedupStatsCommand reports deduplication statistics for the configured
+// backend. The statistics are read from the CAS index, so they are available
+// whether or not --dedup is set for this invocation.
+func (ctx *CommandContext) DedupStatsCommand() (*dedup.Stats, error) {
+	if ctx.Client != nil || ctx.Storage == nil {
+		return nil, ErrDedupRequiresLocal
+	}
+
+	storage := ctx.Storage
+	if indexed, ok := storage.(*search.Storage); ok {
+		storage = indexed.Underlying()
+	}
+	store, ok := storage.(*dedup.Store)
+	if !ok {
+		store = dedup.New(storage, nil)
+	}
+	return store.Stats(context.Background())
+}
+
+// FormatDedupStats formats deduplication statistics for output.
+func FormatDedupStats(stats *dedup.Stats, format OutputFormat) string {
+	switch format {
+	case FormatJSON:
+		return formatJSON(stats)
+	case FormatTable:
+		return formatDedupStatsTable(stats)
+	default:
+		return formatDedupStatsText(stats)
+	}
+}
+
+func formatDedupStatsText(stats *dedup.Stats) string {
+	var output strings.Builder
+	output.WriteString("Dedup Statistics:\n")
+	output.WriteString(fmt.Sprintf("  Objects: %d\n", stats.Objects))
+	output.WriteString(fmt.Sprintf("  Logical Size: %s\n", formatSize(stats.LogicalBytes)))
+	output.WriteString(fmt.Sprintf("  Unique Chunks: %d\n", stats.Chunks))
+	output.WriteString(fmt.Sprintf("  Stored Size: %s\n", formatSize(stats.StoredBytes)))
+	output.WriteString(fmt.Sprintf("  Saved: %s\n", formatSize(stats.SavedBytes)))
+	output.WriteString(fmt.Sprintf("  Dedup Ratio: %.2fx\n", stats.Ratio))
+	return output.String()
+}
+
+func formatDedupStatsTable(stats *dedup.Stats) string {
+	rows := [][2]string{
+		{"Objects", fmt.Sprintf("%d", stats.Objects)},
+		{"Logical Size", formatSize(stats.LogicalBytes)},
+		{"Unique Chunks", fmt.Sprintf("%d", stats.Chunks)},
+		{"Stored Size", formatSize(stats.StoredBytes)},
+		{"Saved", formatSize(stats.SavedBytes)},
+		{"Dedup Ratio", fmt.Sprintf("%.2fx", stats.Ratio)},
+	}
+
+	var output strings.Builder
+	output.WriteString("┌──────────────────────┬──────────────────────┐\n")
+	output.WriteString("│ Dedup Statistics                            │\n")
+	output.WriteString("├──────────────────────┼──────────────────────┤\n")
+	for _, row := range rows {
+		output.WriteString(fmt.Sprintf("│ %-20s │ %-20s │\n", row[0], row[1]))
+	}
+	output.WriteString("└──────────────────────┴──────────────────────┘\n")
+	return output.String()
+}
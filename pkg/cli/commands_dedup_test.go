@@ -0,0 +1,83 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/dedup"
+)
+
+func TestDedupStatsCommand(t *testing.T) {
+	cfg := &Config{
+		Backend:      BackendLocal,
+		BackendPath:  t.TempDir(),
+		OutputFormat: "text",
+		Dedup:        true,
+	}
+	ctx, err := NewCommandContext(cfg)
+	if err != nil {
+		t.Fatalf("NewCommandContext: %v", err)
+	}
+	defer ctx.Close()
+
+	if _, ok := ctx.Storage.(*dedup.Store); !ok {
+		t.Fatalf("expected dedup store, got %T", ctx.Storage)
+	}
+
+	data := bytes.Repeat([]byte("artifact"), 8*1024)
+	for _, key := range []string{"a.bin", "b.bin"} {
+		if err := ctx.Storage.Put(key, bytes.NewReader(data)); err != nil {
+			t.Fatalf("Put %s: %v", key, err)
+		}
+	}
+
+	// Stats are read from the index even without --dedup.
+	cfg.Dedup = false
+	plain, err := NewCommandContext(cfg)
+	if err != nil {
+		t.Fatalf("NewCommandContext: %v", err)
+	}
+	defer plain.Close()
+
+	stats, err := plain.DedupStatsCommand()
+	if err != nil {
+		t.Fatalf("DedupStatsCommand: %v", err)
+	}
+	if stats.Objects != 2 || stats.LogicalBytes != int64(2*len(data)) || stats.StoredBytes >= stats.LogicalBytes {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	if out := FormatDedupStats(stats, FormatText); !strings.Contains(out, "Objects: 2") {
+		t.Errorf("unexpected text output: %s", out)
+	}
+	if out := FormatDedupStats(stats, FormatTable); !strings.Contains(out, "Dedup Ratio") {
+		t.Errorf("unexpected table output: %s", out)
+	}
+	var decoded dedup.Stats
+	if err := json.Unmarshal([]byte(FormatDedupStats(stats, FormatJSON)), &decoded); err != nil || decoded != *stats {
+		t.Errorf("unexpected JSON output: %+v, %v", decoded, err)
+	}
+}
+
+func TestDedupStatsCommand_RemoteMode(t *testing.T) {
+	ctx := &CommandContext{Client: &mockClient{}, Config: &Config{}}
+	if _, err := ctx.DedupStatsCommand(); !errors.Is(err, ErrDedupRequiresLocal) {
+		t.Errorf("expected ErrDedupRequiresLocal, got %v", err)
+	}
+}
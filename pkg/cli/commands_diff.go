@@ -0,0 +1,111 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jeremyhahn/go-objstore/pkg/cli/client"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/diff"
+)
+
+// DiffCommand compares two objects, each a key optionally followed by @
+// and an RFC 3339 time to compare the version current then. With a server
+// the objects are compared there, so neither is downloaded; in local mode
+// they are compared here.
+func (ctx *CommandContext) DiffCommand(refA, refB string, opts diff.Options) (*diff.Result, error) {
+	ctxBg := context.Background()
+	if ctx.Client != nil {
+		differ, ok := client.Optional[client.Differ](ctx.Client)
+		if !ok {
+			return nil, ErrDiffNotSupported
+		}
+		return differ.Diff(ctxBg, refA, refB, opts)
+	}
+
+	a, err := ctx.openVersion(ctxBg, refA)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = a.Close() }()
+	b, err := ctx.openVersion(ctxBg, refB)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = b.Close() }()
+
+	return diff.Compare(diff.Source{Name: refA, Reader: a}, diff.Source{Name: refB, Reader: b}, opts)
+}
+
+// openVersion opens a local object, or the version of it current at the
+// time following an @.
+func (ctx *CommandContext) openVersion(ctxBg context.Context, ref string) (io.ReadCloser, error) {
+	key, at, ok := diff.SplitVersion(ref)
+	if !ok {
+		return ctx.Storage.GetWithContext(ctxBg, key)
+	}
+	rc, _, err := common.GetAsOf(ctxBg, ctx.Storage, key, at)
+	if errors.Is(err, common.ErrVersionsNotSupported) {
+		return nil, ErrVersionsNotSupported
+	}
+	return rc, err
+}
+
+// FormatDiffResult formats a comparison for output. Text differences are
+// printed as a unified diff, JSON differences one per line and binary
+// differences as the first differing offset.
+func FormatDiffResult(result *diff.Result, format OutputFormat) string {
+	if format == FormatJSON {
+		return formatJSON(result)
+	}
+
+	var output strings.Builder
+	switch {
+	case result.Identical:
+		output.WriteString("Objects are identical\n")
+	case result.Mode == diff.ModeText:
+		output.WriteString(result.Unified)
+	case result.Mode == diff.ModeJSON:
+		for _, c := range result.Changes {
+			switch c.Op {
+			case diff.OpAdd:
+				output.WriteString(fmt.Sprintf("+ %s: %s\n", pointer(c.Path), c.New))
+			case diff.OpRemove:
+				output.WriteString(fmt.Sprintf("- %s: %s\n", pointer(c.Path), c.Old))
+			default:
+				output.WriteString(fmt.Sprintf("~ %s: %s -> %s\n", pointer(c.Path), c.Old, c.New))
+			}
+		}
+	case result.FirstDifference != nil:
+		output.WriteString(fmt.Sprintf("Objects differ at byte %d (sizes %d and %d)\n",
+			*result.FirstDifference, result.SizeA, result.SizeB))
+	}
+	if result.Truncated {
+		output.WriteString("(more differences were found than are shown)\n")
+	}
+	return output.String()
+}
+
+// pointer returns path for display, "/" for the whole document.
+func pointer(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return path
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/diff"
+)
+
+func TestDiffCommand_LocalMode(t *testing.T) {
+	ctx, err := NewCommandContext(&Config{Backend: BackendLocal, BackendPath: t.TempDir(), OutputFormat: "text"})
+	if err != nil {
+		t.Fatalf("NewCommandContext: %v", err)
+	}
+	defer ctx.Close()
+
+	objects := map[string]string{
+		"a.txt":  "one\ntwo\nthree\n",
+		"b.txt":  "one\n2\nthree\n",
+		"a.json": `{"name":"x","size":1}`,
+		"b.json": `{"name":"y","size":1,"tags":[]}`,
+	}
+	for key, data := range objects {
+		if err := ctx.Storage.PutWithContext(context.Background(), key, strings.NewReader(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := ctx.DiffCommand("a.txt", "b.txt", diff.Options{})
+	if err != nil {
+		t.Fatalf("DiffCommand: %v", err)
+	}
+	if text := FormatDiffResult(result, FormatText); !strings.Contains(text, "-two\n+2\n") {
+		t.Errorf("text output = %q", text)
+	}
+
+	result, err = ctx.DiffCommand("a.json", "b.json", diff.Options{})
+	if err != nil {
+		t.Fatalf("DiffCommand: %v", err)
+	}
+	text := FormatDiffResult(result, FormatText)
+	if !strings.Contains(text, `~ /name: "x" -> "y"`) || !strings.Contains(text, "+ /tags: []") {
+		t.Errorf("json changes output = %q", text)
+	}
+	var decoded diff.Result
+	if err := json.Unmarshal([]byte(FormatDiffResult(result, FormatJSON)), &decoded); err != nil || len(decoded.Changes) != 2 {
+		t.Errorf("json output = %+v, %v", decoded, err)
+	}
+
+	result, err = ctx.DiffCommand("a.txt", "a.txt", diff.Options{})
+	if err != nil || FormatDiffResult(result, FormatText) != "Objects are identical\n" {
+		t.Errorf("identical = %+v, %v", result, err)
+	}
+
+	if _, err := ctx.DiffCommand("a.txt@2025-01-01T00:00:00Z", "b.txt", diff.Options{}); !errors.Is(err, ErrVersionsNotSupported) {
+		t.Errorf("DiffCommand() of a version error = %v, want ErrVersionsNotSupported", err)
+	}
+}
+
+func TestDiffCommand_RemoteMode(t *testing.T) {
+	ctx := &CommandContext{Client: &mockClient{}, Config: &Config{}}
+	if _, err := ctx.DiffCommand("a", "b", diff.Options{}); !errors.Is(err, ErrDiffNotSupported) {
+		t.Errorf("expected ErrDiffNotSupported, got %v", err)
+	}
+}
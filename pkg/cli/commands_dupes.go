@@ -0,0 +1,82 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jeremyhahn/go-objstore/pkg/checksum"
+)
+
+// DuplicatesCommand reports the objects under prefix that have the same
+// content. With hash, objects without a recorded checksum or MD5 ETag are
+// downloaded and hashed so they are compared too.
+func (ctx *CommandContext) DuplicatesCommand(prefix string, hash bool) (*checksum.DuplicateReport, error) {
+	return checksum.FindDuplicates(context.Background(), ctx.objectStorage(), prefix, &checksum.DuplicateOptions{Hash: hash})
+}
+
+// FormatDuplicateReport formats a duplicate report for output
+func FormatDuplicateReport(report *checksum.DuplicateReport, format OutputFormat) string {
+	switch format {
+	case FormatJSON:
+		return formatJSON(report)
+	case FormatTable:
+		if len(report.Sets) == 0 {
+			return formatDuplicateSummary(report)
+		}
+		// One row per key; the set's columns are only filled on its first.
+		var rows [][]string
+		for _, set := range report.Sets {
+			for i, key := range set.Keys {
+				row := []string{"", "", "", "", key}
+				if i == 0 {
+					row = []string{shortChecksum(set.Checksum), formatSize(set.Size), fmt.Sprintf("%d", len(set.Keys)), formatSize(set.Savings), key}
+				}
+				rows = append(rows, row)
+			}
+		}
+		return formatDuplicateSummary(report) + formatBoxTable([]string{"Checksum", "Size", "Copies", "Savings", "Key"}, rows)
+	default:
+		var output strings.Builder
+		output.WriteString(formatDuplicateSummary(report))
+		for _, set := range report.Sets {
+			output.WriteString(fmt.Sprintf("\n%s (%d copies of %s, %s reclaimable)\n",
+				set.Checksum, len(set.Keys), formatSize(set.Size), formatSize(set.Savings)))
+			for _, key := range set.Keys {
+				output.WriteString("  " + key + "\n")
+			}
+		}
+		return output.String()
+	}
+}
+
+func formatDuplicateSummary(report *checksum.DuplicateReport) string {
+	summary := fmt.Sprintf("%d duplicate set(s), %s reclaimable (%d objects examined",
+		len(report.Sets), formatSize(report.Savings), report.Objects)
+	if report.Unchecked > 0 {
+		summary += fmt.Sprintf(", %d without a checksum not compared; use --hash", report.Unchecked)
+	}
+	return summary + ")\n"
+}
+
+// shortChecksum abbreviates a validator for tables.
+func shortChecksum(validator string) string {
+	algorithm, sum, _ := strings.Cut(validator, ":")
+	if len(sum) > 12 {
+		sum = sum[:12]
+	}
+	return algorithm + ":" + sum
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package cli
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestDuplicatesCommand(t *testing.T) {
+	ctx := newExportTestContext(t, 2)
+	for _, key := range []string{"copies/a.bin", "copies/b.bin"} {
+		if err := ctx.Storage.PutWithContext(context.Background(), key, strings.NewReader("data/000.bin")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Memory ETags are not digests, so nothing can be compared unhashed.
+	report, err := ctx.DuplicatesCommand("", false)
+	if err != nil {
+		t.Fatalf("DuplicatesCommand() error = %v", err)
+	}
+	if report.Objects != 4 || report.Unchecked != 4 || len(report.Sets) != 0 {
+		t.Errorf("report = %+v, want 4 unchecked objects", report)
+	}
+	if out := FormatDuplicateReport(report, FormatText); !strings.Contains(out, "use --hash") {
+		t.Errorf("FormatDuplicateReport() = %q, want a hint to use --hash", out)
+	}
+
+	report, err = ctx.DuplicatesCommand("", true)
+	if err != nil {
+		t.Fatalf("DuplicatesCommand() with hash error = %v", err)
+	}
+	if len(report.Sets) != 1 || len(report.Sets[0].Keys) != 3 || report.Savings != 24 {
+		t.Fatalf("report = %+v, want one set of 3 copies", report)
+	}
+	for _, format := range []OutputFormat{FormatText, FormatTable} {
+		if out := FormatDuplicateReport(report, format); !strings.Contains(out, "copies/b.bin") {
+			t.Errorf("FormatDuplicateReport(%s) = %q, want the keys listed", format, out)
+		}
+	}
+}
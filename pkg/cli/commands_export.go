@@ -0,0 +1,174 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// ExportFormat is a file format listings are exported in.
+type ExportFormat string
+
+const (
+	// ExportJSONL writes one JSON object per line.
+	ExportJSONL ExportFormat = "jsonl"
+
+	// ExportCSV writes a header row followed by one row per object.
+	ExportCSV ExportFormat = "csv"
+)
+
+// ExportFormatForFile returns the export format for path: ExportCSV for a
+// .csv file and ExportJSONL for anything else.
+func ExportFormatForFile(path string) ExportFormat {
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		return ExportCSV
+	}
+	return ExportJSONL
+}
+
+// ExportOptions control ExportListCommand.
+type ExportOptions struct {
+	// Format is the file format written; empty writes ExportJSONL.
+	Format ExportFormat
+
+	// Fields selects the ListFields written, in order. Empty writes all of
+	// them.
+	Fields []string
+
+	// PageSize is the number of objects requested per page; 0 uses the
+	// backend default.
+	PageSize int
+
+	// Long fetches the metadata of objects listed without custom metadata,
+	// as ListOptions.Long does.
+	Long bool
+
+	// All follows continuation tokens until the listing is complete rather
+	// than exporting the first page.
+	All bool
+}
+
+// ExportListCommand writes the objects under prefix to w in key order, one
+// per line, and returns how many it wrote. Listings are read and written a
+// page at a time, so exports of millions of keys run in constant memory.
+func (ctx *CommandContext) ExportListCommand(prefix string, w io.Writer, opts ExportOptions) (int64, error) {
+	fields := opts.Fields
+	if len(fields) == 0 {
+		fields = ListFields
+	}
+	ctxBg := context.Background()
+
+	buffered := bufio.NewWriter(w)
+	var writeObject func(obj ObjectInfo) error
+	switch opts.Format {
+	case ExportCSV:
+		records := csv.NewWriter(buffered)
+		if err := records.Write(fields); err != nil {
+			return 0, err
+		}
+		writeObject = func(obj ObjectInfo) error {
+			row := make([]string, len(fields))
+			for i, field := range fields {
+				row[i] = exportFieldValue(obj, field)
+			}
+			if err := records.Write(row); err != nil {
+				return err
+			}
+			records.Flush()
+			return records.Error()
+		}
+	case ExportJSONL, "":
+		encoder := json.NewEncoder(buffered)
+		writeObject = func(obj ObjectInfo) error {
+			record := make(map[string]any, len(fields))
+			for _, field := range fields {
+				switch field {
+				case FieldSize:
+					record[field] = obj.Size
+				default:
+					record[field] = exportFieldValue(obj, field)
+				}
+			}
+			return encoder.Encode(record)
+		}
+	default:
+		return 0, ErrInvalidExportFormat
+	}
+
+	storage := ctx.objectStorage()
+	listOpts := &common.ListOptions{Prefix: prefix, MaxResults: opts.PageSize}
+	var count int64
+	for {
+		page, err := storage.ListWithOptions(ctxBg, listOpts)
+		if err != nil {
+			return count, err
+		}
+		if page == nil {
+			break
+		}
+		if opts.Long {
+			if err := fillMetadata(ctxBg, storage, page.Objects); err != nil {
+				return count, err
+			}
+		}
+		for _, obj := range ConvertListResultToObjectInfo(page) {
+			if err := writeObject(obj); err != nil {
+				return count, err
+			}
+			count++
+		}
+		if !opts.All || page.NextToken == "" || page.NextToken == listOpts.ContinueFrom {
+			break
+		}
+		listOpts.ContinueFrom = page.NextToken
+	}
+	return count, buffered.Flush()
+}
+
+// exportFieldValue returns the text of a field of obj, or "" when it is
+// unknown.
+func exportFieldValue(obj ObjectInfo, field string) string {
+	switch field {
+	case FieldKey:
+		return obj.Key
+	case FieldSize:
+		return strconv.FormatInt(obj.Size, 10)
+	case FieldModified:
+		if obj.LastModified.IsZero() {
+			return ""
+		}
+		return obj.LastModified.UTC().Format(time.RFC3339Nano)
+	case FieldStorageClass:
+		return obj.StorageClass
+	case FieldContentType:
+		return obj.ContentType
+	case FieldETag:
+		return obj.ETag
+	case FieldChecksum:
+		return obj.Checksum
+	case FieldEncryptionKeyID:
+		return obj.EncryptionKeyID
+	}
+	return ""
+}
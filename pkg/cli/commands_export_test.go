@@ -0,0 +1,101 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+func newExportTestContext(t *testing.T, n int) *CommandContext {
+	t.Helper()
+	storage := memory.New()
+	for i := range n {
+		key := fmt.Sprintf("data/%03d.bin", i)
+		if err := storage.PutWithContext(context.Background(), key, strings.NewReader(key)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return &CommandContext{Storage: storage, Config: &Config{}}
+}
+
+func TestExportListCommand_JSONL(t *testing.T) {
+	ctx := newExportTestContext(t, 25)
+
+	var out bytes.Buffer
+	count, err := ctx.ExportListCommand("data/", &out, ExportOptions{PageSize: 10, All: true})
+	if err != nil {
+		t.Fatalf("ExportListCommand() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if count != 25 || len(lines) != 25 {
+		t.Fatalf("exported %d objects in %d lines, want 25", count, len(lines))
+	}
+	var first map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatalf("line 1 is not JSON: %v", err)
+	}
+	if first[FieldKey] != "data/000.bin" || first[FieldSize] != float64(len("data/000.bin")) {
+		t.Errorf("line 1 = %v", first)
+	}
+	if !strings.Contains(lines[24], `"data/024.bin"`) {
+		t.Errorf("last line = %s, want data/024.bin", lines[24])
+	}
+
+	out.Reset()
+	if count, err := ctx.ExportListCommand("data/", &out, ExportOptions{PageSize: 10}); err != nil || count != 10 {
+		t.Errorf("ExportListCommand() without All = %d, %v, want the first page of 10", count, err)
+	}
+}
+
+func TestExportListCommand_CSV(t *testing.T) {
+	ctx := newExportTestContext(t, 3)
+
+	var out bytes.Buffer
+	_, err := ctx.ExportListCommand("", &out, ExportOptions{
+		Format: ExportFormatForFile("keys.CSV"),
+		Fields: []string{FieldKey, FieldSize, FieldChecksum},
+		All:    true,
+	})
+	if err != nil {
+		t.Fatalf("ExportListCommand() error = %v", err)
+	}
+	want := "key,size,checksum\ndata/000.bin,12,\ndata/001.bin,12,\ndata/002.bin,12,\n"
+	if out.String() != want {
+		t.Errorf("CSV = %q, want %q", out.String(), want)
+	}
+
+	if _, err := ctx.ExportListCommand("", &out, ExportOptions{Format: "xml"}); !errors.Is(err, ErrInvalidExportFormat) {
+		t.Errorf("ExportListCommand() with xml error = %v, want ErrInvalidExportFormat", err)
+	}
+}
+
+func TestListObjectsCommand_All(t *testing.T) {
+	ctx := newExportTestContext(t, 3)
+
+	objects, err := ctx.ListObjectsCommand("data/", ListOptions{All: true})
+	if err != nil {
+		t.Fatalf("ListObjectsCommand() error = %v", err)
+	}
+	if len(objects) != 3 {
+		t.Errorf("ListObjectsCommand() = %d objects, want 3", len(objects))
+	}
+}
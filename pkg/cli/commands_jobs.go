@@ -0,0 +1,198 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/cli/client"
+	"github.com/jeremyhahn/go-objstore/pkg/jobs"
+)
+
+// jobsClient returns the remote client's jobs API. Background jobs run in
+// the server, so local mode is not supported.
+func (ctx *CommandContext) jobsClient() (client.JobsViewer, error) {
+	if ctx.Client == nil {
+		return nil, ErrJobsNotSupported
+	}
+	viewer, ok := client.Optional[client.JobsViewer](ctx.Client)
+	if !ok {
+		return nil, ErrJobsNotSupported
+	}
+	return viewer, nil
+}
+
+// JobsListCommand lists the server's background jobs with their last and
+// next run times
+func (ctx *CommandContext) JobsListCommand() ([]jobs.Status, error) {
+	viewer, err := ctx.jobsClient()
+	if err != nil {
+		return nil, err
+	}
+	return viewer.ListJobs(context.Background())
+}
+
+// JobShowCommand returns a background job with its run history
+func (ctx *CommandContext) JobShowCommand(name string) (*jobs.Status, error) {
+	viewer, err := ctx.jobsClient()
+	if err != nil {
+		return nil, err
+	}
+	return viewer.GetJob(context.Background(), name)
+}
+
+// FormatJobsResult formats a list of background jobs for output
+func FormatJobsResult(list []jobs.Status, format OutputFormat) string {
+	switch format {
+	case FormatJSON:
+		return formatJSON(list)
+	case FormatTable:
+		return formatJobsTable(list)
+	default:
+		return formatJobsText(list)
+	}
+}
+
+// FormatJobResult formats a background job and its run history for output
+func FormatJobResult(status *jobs.Status, format OutputFormat) string {
+	switch format {
+	case FormatJSON:
+		return formatJSON(status)
+	case FormatTable:
+		return formatJobsTable([]jobs.Status{*status}) + formatJobRunsTable(status.History)
+	default:
+		return formatJobText(status)
+	}
+}
+
+// jobState summarizes whether a job is running or the outcome of its last run.
+func jobState(status *jobs.Status) string {
+	switch {
+	case status.Running:
+		return "running"
+	case status.LastRun == nil:
+		return "never run"
+	default:
+		return string(status.LastRun.Outcome)
+	}
+}
+
+// jobTime formats a run time, or "-" when it is unset.
+func jobTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
+
+// runSummary describes a run's outcome with its message or error.
+func runSummary(run *jobs.Run) string {
+	summary := string(run.Outcome)
+	switch {
+	case run.Error != "":
+		summary += ": " + run.Error
+	case run.Message != "":
+		summary += ": " + run.Message
+	}
+	return summary
+}
+
+func formatJobsText(list []jobs.Status) string {
+	if len(list) == 0 {
+		return "No background jobs\n"
+	}
+
+	var output strings.Builder
+	for i := range list {
+		s := &list[i]
+		output.WriteString(fmt.Sprintf("%s (every %s)\n", s.Name, time.Duration(s.IntervalSeconds)*time.Second))
+		output.WriteString(fmt.Sprintf("  State: %s\n", jobState(s)))
+		if s.LastRun != nil {
+			output.WriteString(fmt.Sprintf("  Last run: %s on %s (%s)\n", jobTime(s.LastRun.StartedAt), orUnknown(s.LastRun.Node), runSummary(s.LastRun)))
+		}
+		output.WriteString(fmt.Sprintf("  Next run: %s\n", jobTime(s.NextRun)))
+		output.WriteString("\n")
+	}
+	return output.String()
+}
+
+func formatJobText(status *jobs.Status) string {
+	var output strings.Builder
+	output.WriteString(formatJobsText([]jobs.Status{*status}))
+	if len(status.History) == 0 {
+		output.WriteString("No runs recorded\n")
+		return output.String()
+	}
+
+	output.WriteString("History:\n")
+	for i := range status.History {
+		r := &status.History[i]
+		output.WriteString(fmt.Sprintf("  %s on %s, %s: %s\n",
+			jobTime(r.StartedAt), orUnknown(r.Node), r.Duration().Round(time.Millisecond), runSummary(r)))
+	}
+	return output.String()
+}
+
+func formatJobsTable(list []jobs.Status) string {
+	if len(list) == 0 {
+		return "No background jobs\n"
+	}
+
+	var output strings.Builder
+	output.WriteString("┌──────────────┬────────────┬────────────┬──────────────────┬──────────────────┐\n")
+	output.WriteString("│ Job          │ Interval   │ State      │ Last Run         │ Next Run         │\n")
+	output.WriteString("├──────────────┼────────────┼────────────┼──────────────────┼──────────────────┤\n")
+	for i := range list {
+		s := &list[i]
+		last := "-"
+		if s.LastRun != nil {
+			last = s.LastRun.StartedAt.Format("2006-01-02 15:04")
+		}
+		next := "-"
+		if !s.NextRun.IsZero() {
+			next = s.NextRun.Format("2006-01-02 15:04")
+		}
+		output.WriteString(fmt.Sprintf("│ %-12s │ %-10s │ %-10s │ %-16s │ %-16s │\n",
+			truncateString(s.Name, 12),
+			truncateString((time.Duration(s.IntervalSeconds)*time.Second).String(), 10),
+			truncateString(jobState(s), 10),
+			last, next))
+	}
+	output.WriteString("└──────────────┴────────────┴────────────┴──────────────────┴──────────────────┘\n")
+	return output.String()
+}
+
+func formatJobRunsTable(runs []jobs.Run) string {
+	if len(runs) == 0 {
+		return "No runs recorded\n"
+	}
+
+	var output strings.Builder
+	output.WriteString("┌──────────────────┬──────────────┬────────────┬──────────────────────────────────┐\n")
+	output.WriteString("│ Started          │ Node         │ Duration   │ Outcome                          │\n")
+	output.WriteString("├──────────────────┼──────────────┼────────────┼──────────────────────────────────┤\n")
+	for i := range runs {
+		r := &runs[i]
+		output.WriteString(fmt.Sprintf("│ %-16s │ %-12s │ %-10s │ %-32s │\n",
+			r.StartedAt.Format("2006-01-02 15:04"),
+			truncateString(orUnknown(r.Node), 12),
+			truncateString(r.Duration().Round(time.Millisecond).String(), 10),
+			truncateString(runSummary(r), 32)))
+	}
+	output.WriteString("└──────────────────┴──────────────┴────────────┴──────────────────────────────────┘\n")
+	return output.String()
+}
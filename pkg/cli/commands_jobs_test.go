@@ -0,0 +1,60 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package cli
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/jobs"
+)
+
+func TestJobsCommands_LocalMode(t *testing.T) {
+	ctx := &CommandContext{Config: &Config{}}
+	if _, err := ctx.JobsListCommand(); !errors.Is(err, ErrJobsNotSupported) {
+		t.Errorf("expected ErrJobsNotSupported, got %v", err)
+	}
+	if _, err := ctx.JobShowCommand("lifecycle"); !errors.Is(err, ErrJobsNotSupported) {
+		t.Errorf("expected ErrJobsNotSupported, got %v", err)
+	}
+}
+
+func TestFormatJobsResults(t *testing.T) {
+	at := time.Date(2025, 11, 5, 10, 0, 0, 0, time.UTC)
+	run := jobs.Run{
+		Job: "lifecycle", Node: "node-a", StartedAt: at, FinishedAt: at.Add(2 * time.Second),
+		Outcome: jobs.OutcomeFailed, Error: "backend unavailable",
+	}
+	status := jobs.Status{
+		Name: "lifecycle", IntervalSeconds: 3600, LastRun: &run, NextRun: at.Add(time.Hour),
+		History: []jobs.Run{run},
+	}
+
+	for _, format := range []OutputFormat{FormatText, FormatTable, FormatJSON} {
+		if out := FormatJobsResult([]jobs.Status{status}, format); !strings.Contains(out, "lifecycle") || !strings.Contains(out, "failed") {
+			t.Errorf("FormatJobsResult(%s) = %q", format, out)
+		}
+		if out := FormatJobResult(&status, format); !strings.Contains(out, "node-a") || !strings.Contains(out, "backend unavailable") {
+			t.Errorf("FormatJobResult(%s) = %q", format, out)
+		}
+	}
+	if out := FormatJobsResult(nil, FormatText); out == "" {
+		t.Error("expected a message for no jobs")
+	}
+	if out := FormatJobResult(&jobs.Status{Name: "gc"}, FormatText); !strings.Contains(out, "never run") {
+		t.Errorf("FormatJobResult() for a job that never ran = %q", out)
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"github.com/jeremyhahn/go-objstore/pkg/e2ee"
+)
+
+// KeychainInfo describes an end-to-end encryption keychain. Key material
+// is never included.
+type KeychainInfo struct {
+	Path    string   `json:"path"`
+	Default string   `json:"default"`
+	Keys    []string `json:"keys"`
+	Escrow  string   `json:"escrow,omitempty"`
+}
+
+// KeychainGenerateCommand adds a new random key to the keychain file at
+// path, creating the keychain if it does not exist. The key becomes the
+// default when makeDefault is set or it is the keychain's first key.
+func KeychainGenerateCommand(path, keyID string, makeDefault bool) (*KeychainInfo, error) {
+	if path == "" {
+		return nil, ErrKeychainRequired
+	}
+	keychain, err := e2ee.LoadKeychain(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		keychain, err = e2ee.NewKeychain(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := keychain.GenerateKey(keyID); err != nil {
+		return nil, err
+	}
+	if makeDefault {
+		if err := keychain.SetDefault(keyID); err != nil {
+			return nil, err
+		}
+	}
+	if err := keychain.Save(path); err != nil {
+		return nil, err
+	}
+	return keychainInfo(path, keychain), nil
+}
+
+// KeychainListCommand describes the keychain file at path.
+func KeychainListCommand(path string) (*KeychainInfo, error) {
+	if path == "" {
+		return nil, ErrKeychainRequired
+	}
+	keychain, err := e2ee.LoadKeychain(path)
+	if err != nil {
+		return nil, err
+	}
+	return keychainInfo(path, keychain), nil
+}
+
+func keychainInfo(path string, keychain *e2ee.Keychain) *KeychainInfo {
+	return &KeychainInfo{
+		Path:    path,
+		Default: keychain.DefaultKeyID(),
+		Keys:    keychain.KeyIDs(),
+		Escrow:  keychain.EscrowKeyID(),
+	}
+}
+
+// FormatKeychainInfo formats a keychain description for output.
+func FormatKeychainInfo(info *KeychainInfo, format OutputFormat) string {
+	if format == FormatJSON {
+		return formatJSON(info)
+	}
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("Keychain: %s\n", info.Path))
+	for _, id := range info.Keys {
+		marker := " "
+		if id == info.Default {
+			marker = "*"
+		}
+		output.WriteString(fmt.Sprintf("  %s %s\n", marker, id))
+	}
+	if info.Escrow != "" {
+		output.WriteString(fmt.Sprintf("Escrow: recovery key %s\n", info.Escrow))
+	}
+	return output.String()
+}
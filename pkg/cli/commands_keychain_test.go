@@ -0,0 +1,101 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package cli
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/e2ee"
+)
+
+func TestKeychainCommands(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keychain.json")
+
+	if _, err := KeychainGenerateCommand("", "k1", false); !errors.Is(err, ErrKeychainRequired) {
+		t.Errorf("KeychainGenerateCommand() without path error = %v, want ErrKeychainRequired", err)
+	}
+	if _, err := KeychainGenerateCommand(path, "2025", false); err != nil {
+		t.Fatalf("KeychainGenerateCommand: %v", err)
+	}
+	info, err := KeychainGenerateCommand(path, "2026", true)
+	if err != nil {
+		t.Fatalf("KeychainGenerateCommand: %v", err)
+	}
+	if info.Default != "2026" || strings.Join(info.Keys, ",") != "2025,2026" {
+		t.Errorf("unexpected keychain: %+v", info)
+	}
+	if _, err := KeychainGenerateCommand(path, "2026", false); !errors.Is(err, e2ee.ErrKeyExists) {
+		t.Errorf("duplicate key error = %v, want ErrKeyExists", err)
+	}
+
+	listed, err := KeychainListCommand(path)
+	if err != nil {
+		t.Fatalf("KeychainListCommand: %v", err)
+	}
+	if out := FormatKeychainInfo(listed, FormatText); !strings.Contains(out, "* 2026") || !strings.Contains(out, "  2025") {
+		t.Errorf("unexpected text output: %s", out)
+	}
+	if out := FormatKeychainInfo(listed, FormatJSON); !strings.Contains(out, `"default": "2026"`) {
+		t.Errorf("unexpected JSON output: %s", out)
+	}
+}
+
+func TestCommandContextEndToEndEncryption(t *testing.T) {
+	keychainPath := filepath.Join(t.TempDir(), "keychain.json")
+	if _, err := KeychainGenerateCommand(keychainPath, "laptop", false); err != nil {
+		t.Fatal(err)
+	}
+	backendPath := t.TempDir()
+	ctx, err := NewCommandContext(&Config{
+		Backend:      BackendLocal,
+		BackendPath:  backendPath,
+		OutputFormat: "text",
+		E2EKeychain:  keychainPath,
+	})
+	if err != nil {
+		t.Fatalf("NewCommandContext: %v", err)
+	}
+	defer ctx.Close()
+
+	input := filepath.Join(t.TempDir(), "in.txt")
+	if err := os.WriteFile(input, []byte("top secret"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.PutCommand("notes.txt", input); err != nil {
+		t.Fatalf("PutCommand: %v", err)
+	}
+	stored, err := os.ReadFile(filepath.Join(backendPath, "notes.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(stored), "top secret") {
+		t.Error("backend stored plaintext")
+	}
+
+	output := filepath.Join(t.TempDir(), "out.txt")
+	if err := ctx.GetCommand("notes.txt", output); err != nil {
+		t.Fatalf("GetCommand: %v", err)
+	}
+	if got, _ := os.ReadFile(output); string(got) != "top secret" {
+		t.Errorf("GetCommand wrote %q, want the plaintext", got)
+	}
+
+	if _, err := NewCommandContext(&Config{Backend: BackendLocal, BackendPath: backendPath, OutputFormat: "text", E2EKeychain: keychainPath + ".missing"}); err == nil {
+		t.Error("NewCommandContext succeeded with a missing keychain")
+	}
+}
@@ -15,6 +15,7 @@ package cli
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -295,6 +296,26 @@ func TestAddPolicyCommand(t *testing.T) {
 	}
 }
 
+func TestAddPolicyCommandWithStorageClass(t *testing.T) {
+	storage := newMockLifecycleStorage()
+	ctx := &CommandContext{
+		Storage: storage,
+		Config:  &Config{Backend: "local"},
+	}
+
+	if err := ctx.AddPolicyCommandWithStorageClass("cool", "reports/", "30", common.ActionTransition, ""); !errors.Is(err, ErrStorageClassRequired) {
+		t.Fatalf("expected ErrStorageClassRequired, got %v", err)
+	}
+
+	if err := ctx.AddPolicyCommandWithStorageClass("cool", "reports/", "30", common.ActionTransition, "STANDARD_IA"); err != nil {
+		t.Fatalf("AddPolicyCommandWithStorageClass() error = %v", err)
+	}
+	policies, _ := storage.GetPolicies()
+	if len(policies) != 1 || policies[0].StorageClass != "STANDARD_IA" {
+		t.Fatalf("expected transition policy to STANDARD_IA, got %+v", policies)
+	}
+}
+
 // TestRemovePolicyCommand tests the remove policy command
 func TestRemovePolicyCommand(t *testing.T) {
 	tests := []struct {
@@ -765,3 +786,57 @@ func TestApplyPoliciesCommand(t *testing.T) {
 		})
 	}
 }
+
+// agedListStorage lists objects with their stored metadata so lifecycle
+// retention can be exercised.
+type agedListStorage struct {
+	*mockLifecycleStorage
+}
+
+func (m *agedListStorage) ListWithOptions(ctx context.Context, opts *common.ListOptions) (*common.ListResult, error) {
+	result := &common.ListResult{}
+	for key, metadata := range m.metadata {
+		result.Objects = append(result.Objects, &common.ObjectInfo{Key: key, Metadata: metadata})
+	}
+	return result, nil
+}
+
+func TestApplyPoliciesCommand_Transition(t *testing.T) {
+	storage := &agedListStorage{newMockLifecycleStorage()}
+	storage.data["reports/old.txt"] = []byte("old content")
+	storage.metadata["reports/old.txt"] = &common.Metadata{
+		Size:         11,
+		ContentType:  "text/plain",
+		LastModified: time.Now().Add(-48 * time.Hour),
+	}
+	storage.data["reports/new.txt"] = []byte("new content")
+	storage.metadata["reports/new.txt"] = &common.Metadata{
+		Size:         11,
+		LastModified: time.Now(),
+	}
+	storage.policies = []common.LifecyclePolicy{
+		{
+			ID:           "cool-reports",
+			Prefix:       "reports/",
+			Retention:    24 * time.Hour,
+			Action:       common.ActionTransition,
+			StorageClass: "STANDARD_IA",
+		},
+	}
+
+	ctx := &CommandContext{
+		Storage: storage,
+		Config:  &Config{Backend: "local"},
+	}
+	if err := ctx.ApplyPoliciesCommand(); err != nil {
+		t.Fatalf("ApplyPoliciesCommand() error = %v", err)
+	}
+
+	old := storage.metadata["reports/old.txt"]
+	if old.StorageClass != "STANDARD_IA" || old.ContentType != "text/plain" {
+		t.Errorf("old object metadata = %+v, want STANDARD_IA with content type kept", old)
+	}
+	if class := storage.metadata["reports/new.txt"].StorageClass; class != "" {
+		t.Errorf("new object storage class = %q, want unchanged", class)
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jeremyhahn/go-objstore/pkg/cli/client"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// ParseMetadataFields parses custom metadata fields given as name=value
+// arguments.
+func ParseMetadataFields(args []string) (map[string]string, error) {
+	fields := make(map[string]string, len(args))
+	for _, arg := range args {
+		name, value, ok := strings.Cut(arg, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidMetadataField, arg)
+		}
+		fields[name] = value
+	}
+	return fields, nil
+}
+
+// PatchMetadataCommand applies patch to the metadata of the object stored
+// under key. The object's data is not rewritten.
+func (ctx *CommandContext) PatchMetadataCommand(key string, patch *common.MetadataPatch) error {
+	if err := patch.Validate(); err != nil {
+		return err
+	}
+	ctxBg := context.Background()
+
+	storage := ctx.Storage
+	if ctx.Client != nil {
+		storage = client.NewStorage(ctx.Client)
+	}
+	stored, err := storage.GetMetadata(ctxBg, key)
+	if err != nil {
+		return err
+	}
+	patched, changed := patch.Apply(stored)
+	if !changed {
+		return nil
+	}
+	return storage.UpdateMetadata(ctxBg, key, patched)
+}
+
+// UpdateMetadataBatchCommand applies patch to the metadata of every object
+// under prefix and returns the number of objects changed. Servers that
+// support it apply the patch in a single request; otherwise, and in local
+// mode, the objects are listed and updated one by one. Data is never
+// rewritten.
+func (ctx *CommandContext) UpdateMetadataBatchCommand(prefix string, patch *common.MetadataPatch) (int, error) {
+	ctxBg := context.Background()
+	if ctx.Client != nil {
+		if updater, ok := client.Optional[client.MetadataBatchUpdater](ctx.Client); ok {
+			if err := patch.Validate(); err != nil {
+				return 0, err
+			}
+			return updater.UpdateMetadataBatch(ctxBg, prefix, patch)
+		}
+		return common.UpdateMetadataBatch(ctxBg, client.NewStorage(ctx.Client), prefix, patch)
+	}
+	return common.UpdateMetadataBatch(ctxBg, ctx.Storage, prefix, patch)
+}
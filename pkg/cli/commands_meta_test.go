@@ -0,0 +1,84 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+func TestParseMetadataFields(t *testing.T) {
+	fields, err := ParseMetadataFields([]string{"tier=cold", "note=a=b", "empty="})
+	if err != nil {
+		t.Fatalf("ParseMetadataFields() error = %v", err)
+	}
+	if fields["tier"] != "cold" || fields["note"] != "a=b" || fields["empty"] != "" || len(fields) != 3 {
+		t.Errorf("ParseMetadataFields() = %v", fields)
+	}
+
+	for _, arg := range []string{"tier", "=cold"} {
+		if _, err := ParseMetadataFields([]string{arg}); !errors.Is(err, ErrInvalidMetadataField) {
+			t.Errorf("ParseMetadataFields(%q) = %v, want ErrInvalidMetadataField", arg, err)
+		}
+	}
+}
+
+func TestMetadataCommands_LocalMode(t *testing.T) {
+	bg := context.Background()
+	storage := memory.New()
+	for _, key := range []string{"logs/a.log", "logs/b.log", "data/c.csv"} {
+		if err := storage.PutWithMetadata(bg, key, strings.NewReader(key), &common.Metadata{
+			Custom: map[string]string{"draft": "true"},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ctx := &CommandContext{Storage: storage, Config: &Config{}}
+
+	updated, err := ctx.UpdateMetadataBatchCommand("logs/", &common.MetadataPatch{
+		Set:    map[string]string{"tier": "cold"},
+		Remove: []string{"draft"},
+	})
+	if err != nil || updated != 2 {
+		t.Fatalf("UpdateMetadataBatchCommand() = %d, %v, want 2", updated, err)
+	}
+	if err := ctx.PatchMetadataCommand("data/c.csv", &common.MetadataPatch{ContentType: "text/csv"}); err != nil {
+		t.Fatalf("PatchMetadataCommand() error = %v", err)
+	}
+
+	for key, tier := range map[string]string{"logs/a.log": "cold", "logs/b.log": "cold", "data/c.csv": ""} {
+		metadata, err := storage.GetMetadata(bg, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if metadata.Custom["tier"] != tier {
+			t.Errorf("%s: custom = %v, want tier %q", key, metadata.Custom, tier)
+		}
+		if _, ok := metadata.Custom["draft"]; ok == (tier != "") {
+			t.Errorf("%s: custom = %v", key, metadata.Custom)
+		}
+	}
+	if metadata, _ := storage.GetMetadata(bg, "data/c.csv"); metadata.ContentType != "text/csv" {
+		t.Errorf("content type = %q, want text/csv", metadata.ContentType)
+	}
+
+	if err := ctx.PatchMetadataCommand("data/c.csv", &common.MetadataPatch{}); !errors.Is(err, common.ErrInvalidArgument) {
+		t.Errorf("PatchMetadataCommand(empty patch) = %v, want ErrInvalidArgument", err)
+	}
+}
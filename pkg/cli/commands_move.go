@@ -0,0 +1,74 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jeremyhahn/go-objstore/pkg/cli/client"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// MoveCommand moves the object stored under from to to. The object is
+// copied with its metadata, server-side where the backend supports it, and
+// removed from from once the copy succeeded. An existing object at to is
+// only replaced when overwrite is set.
+func (ctx *CommandContext) MoveCommand(from, to string, overwrite bool) error {
+	ctxBg := context.Background()
+	storage := ctx.objectStorage()
+	if !overwrite {
+		exists, err := storage.Exists(ctxBg, to)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return fmt.Errorf("%w: %s", common.ErrAlreadyExists, to)
+		}
+	}
+	if err := common.Compose(ctxBg, storage, to, from); err != nil {
+		return err
+	}
+	return storage.DeleteWithContext(ctxBg, from)
+}
+
+// RenamePrefixCommand moves every object under from to the same key under
+// to. Servers that support it move the objects themselves in a single
+// request, reporting only the totals; otherwise, and in local mode, the
+// objects are moved from here and opts.Progress is called for each.
+func (ctx *CommandContext) RenamePrefixCommand(from, to string, opts *common.RenameOptions) (*common.RenameResult, error) {
+	ctxBg := context.Background()
+	if ctx.Client == nil {
+		return common.RenamePrefix(ctxBg, ctx.Storage, from, to, opts)
+	}
+	// Wrapping clients such as signing bind an object to its key, so only
+	// a client talking to the server directly may move objects there.
+	if renamer, ok := ctx.Client.(client.Renamer); ok {
+		return renamer.RenamePrefix(ctxBg, from, to, opts)
+	}
+	return common.RenamePrefix(ctxBg, client.NewStorage(ctx.Client), from, to, opts)
+}
+
+// FormatRenameResult formats the totals of a prefix rename for output
+func FormatRenameResult(from, to string, result *common.RenameResult, format OutputFormat) string {
+	if format == FormatJSON {
+		return formatJSON(result)
+	}
+	verb := "Moved"
+	if result.DryRun {
+		verb = "Would move"
+	}
+	return fmt.Sprintf("%s %d objects from '%s' to '%s' (%d skipped, %d failed)\n",
+		verb, result.Moved, from, to, result.Skipped, result.Failed)
+}
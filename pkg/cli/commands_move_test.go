@@ -0,0 +1,65 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+func TestMoveCommand(t *testing.T) {
+	ctx := newExportTestContext(t, 2)
+
+	if err := ctx.MoveCommand("data/000.bin", "data/001.bin", false); !errors.Is(err, common.ErrAlreadyExists) {
+		t.Errorf("MoveCommand() onto an existing object error = %v, want ErrAlreadyExists", err)
+	}
+	if err := ctx.MoveCommand("data/000.bin", "moved/000.bin", false); err != nil {
+		t.Fatalf("MoveCommand() error = %v", err)
+	}
+	if exists, _ := ctx.Storage.Exists(context.Background(), "data/000.bin"); exists {
+		t.Error("data/000.bin still exists after the move")
+	}
+	if exists, _ := ctx.Storage.Exists(context.Background(), "moved/000.bin"); !exists {
+		t.Error("moved/000.bin does not exist after the move")
+	}
+	if err := ctx.MoveCommand("moved/000.bin", "data/001.bin", true); err != nil {
+		t.Errorf("MoveCommand() with overwrite error = %v", err)
+	}
+}
+
+func TestRenamePrefixCommand(t *testing.T) {
+	ctx := newExportTestContext(t, 12)
+
+	var moved []string
+	result, err := ctx.RenamePrefixCommand("data/", "archive/data/", &common.RenameOptions{
+		Progress: func(event common.RenameEvent) { moved = append(moved, event.To) },
+	})
+	if err != nil {
+		t.Fatalf("RenamePrefixCommand() error = %v", err)
+	}
+	if result.Moved != 12 || len(moved) != 12 {
+		t.Errorf("result = %+v with %d events, want 12 moved", result, len(moved))
+	}
+	if out := FormatRenameResult("data/", "archive/data/", result, FormatText); !strings.HasPrefix(out, "Moved 12 objects") {
+		t.Errorf("FormatRenameResult() = %q", out)
+	}
+	objects, err := ctx.ListObjectsCommand("archive/", ListOptions{All: true})
+	if err != nil || len(objects) != 12 {
+		t.Errorf("listed %d objects under archive/, %v, want 12", len(objects), err)
+	}
+}
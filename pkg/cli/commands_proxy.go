@@ -0,0 +1,73 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/proxy"
+)
+
+// sizeUnits maps size suffixes to their multipliers. Both decimal (KB) and
+// binary (KiB) suffixes are accepted.
+var sizeUnits = []struct {
+	suffix     string
+	multiplier int64
+}{
+	{"KIB", 1 << 10}, {"MIB", 1 << 20}, {"GIB", 1 << 30}, {"TIB", 1 << 40},
+	{"KB", 1e3}, {"MB", 1e6}, {"GB", 1e9}, {"TB", 1e12},
+	{"K", 1 << 10}, {"M", 1 << 20}, {"G", 1 << 30}, {"T", 1 << 40},
+	{"B", 1},
+}
+
+// ParseSize parses a byte size such as "512MiB", "10GB" or "1048576".
+// Single-letter suffixes (K, M, G, T) are binary.
+func ParseSize(s string) (int64, error) {
+	value := strings.ToUpper(strings.TrimSpace(s))
+	multiplier := int64(1)
+	for _, unit := range sizeUnits {
+		if strings.HasSuffix(value, unit.suffix) {
+			value = strings.TrimSpace(strings.TrimSuffix(value, unit.suffix))
+			multiplier = unit.multiplier
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidSize, s)
+	}
+	return int64(n * float64(multiplier)), nil
+}
+
+// ProxyCommand returns a caching proxy for the configured origin: the
+// remote server when --server is set, otherwise the configured backend.
+// Objects are cached in cacheDir up to cacheSize bytes and served without
+// contacting the origin for revalidateAfter after they were last validated.
+func (ctx *CommandContext) ProxyCommand(cacheDir string, cacheSize int64, revalidateAfter time.Duration) (*proxy.Proxy, error) {
+	cache, err := proxy.NewDiskCache(cacheDir, cacheSize)
+	if err != nil {
+		return nil, err
+	}
+
+	var origin proxy.Origin
+	if ctx.Client != nil {
+		origin = ctx.Client
+	} else {
+		origin = proxy.StorageOrigin(ctx.Storage)
+	}
+	return proxy.New(origin, cache, proxy.Config{RevalidateAfter: revalidateAfter}), nil
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package cli
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int64
+	}{
+		{"1048576", 1 << 20},
+		{"512MiB", 512 << 20},
+		{"10GB", 10e9},
+		{"2g", 2 << 30},
+		{"1.5 KiB", 1536},
+		{"100B", 100},
+	}
+	for _, tt := range tests {
+		got, err := ParseSize(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseSize(%q) = %d, %v; want %d", tt.in, got, err, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "MiB", "-1G", "ten"} {
+		if _, err := ParseSize(in); !errors.Is(err, ErrInvalidSize) {
+			t.Errorf("ParseSize(%q) error = %v, want ErrInvalidSize", in, err)
+		}
+	}
+}
+
+func TestProxyCommand_Local(t *testing.T) {
+	dir := t.TempDir()
+	ctx, err := NewCommandContext(&Config{Backend: BackendLocal, BackendPath: filepath.Join(dir, "data"), OutputFormat: "text"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = ctx.Close() }()
+
+	src := filepath.Join(dir, "artifact.bin")
+	if err := os.WriteFile(src, []byte("artifact"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.PutCommand("artifact.bin", src); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := ctx.ProxyCommand(filepath.Join(dir, "cache"), 1<<20, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	p.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/objects/artifact.bin", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "artifact" {
+		t.Errorf("got %d %q", rec.Code, rec.Body.String())
+	}
+}
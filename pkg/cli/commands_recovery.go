@@ -0,0 +1,176 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/e2ee"
+)
+
+// RecoveryKeyInfo describes a generated escrow recovery key.
+type RecoveryKeyInfo struct {
+	ID         string `json:"id"`
+	PrivateKey string `json:"private_key"`
+	PublicKey  string `json:"public_key"`
+}
+
+// RecoveryReport lists the objects a recovery wrote and the ones it could
+// not decrypt.
+type RecoveryReport struct {
+	Directory string            `json:"directory"`
+	Recovered []string          `json:"recovered"`
+	Failed    map[string]string `json:"failed,omitempty"`
+}
+
+// RecoveryKeyGenerateCommand generates an organization recovery key. The
+// full key is written to privatePath, which belongs offline, and its public
+// half to publicPath, for `keychain escrow`. Existing files are never
+// overwritten.
+func RecoveryKeyGenerateCommand(privatePath, publicPath string) (*RecoveryKeyInfo, error) {
+	for _, path := range []string{privatePath, publicPath} {
+		if _, err := os.Stat(path); err == nil {
+			return nil, fmt.Errorf("%w: %s", ErrFileExists, path)
+		}
+	}
+	recovery, err := e2ee.GenerateRecoveryKey()
+	if err != nil {
+		return nil, err
+	}
+	if err := recovery.Save(privatePath); err != nil {
+		return nil, err
+	}
+	if err := recovery.SavePublic(publicPath); err != nil {
+		return nil, err
+	}
+	return &RecoveryKeyInfo{ID: recovery.ID(), PrivateKey: privatePath, PublicKey: publicPath}, nil
+}
+
+// KeychainEscrowCommand turns on escrow mode for the keychain at path with
+// the recovery public key in publicKeyPath, or turns it off when
+// publicKeyPath is empty.
+func KeychainEscrowCommand(path, publicKeyPath string) (*KeychainInfo, error) {
+	if path == "" {
+		return nil, ErrKeychainRequired
+	}
+	keychain, err := e2ee.LoadKeychain(path)
+	if err != nil {
+		return nil, err
+	}
+	if publicKeyPath == "" {
+		keychain.DisableEscrow()
+	} else {
+		public, err := e2ee.LoadRecoveryPublicKey(publicKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		if err := keychain.EnableEscrow(public); err != nil {
+			return nil, err
+		}
+	}
+	if err := keychain.Save(path); err != nil {
+		return nil, err
+	}
+	return keychainInfo(path, keychain), nil
+}
+
+// RecoverCommand decrypts every object under prefix into directory, using
+// the recovery key (--e2e-recovery-key) for objects whose keychain key is
+// not available. Objects that fail are reported and skipped.
+func (ctx *CommandContext) RecoverCommand(prefix, directory string) (*RecoveryReport, error) {
+	if ctx.Config == nil || ctx.Config.E2ERecoveryKey == "" {
+		return nil, ErrRecoveryKeyRequired
+	}
+	root, err := filepath.Abs(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &RecoveryReport{Directory: root, Recovered: []string{}}
+	opts := &common.ListOptions{Prefix: prefix}
+	for {
+		var result *common.ListResult
+		if ctx.Client != nil {
+			result, err = ctx.Client.List(context.Background(), opts)
+		} else {
+			result, err = ctx.Storage.ListWithOptions(context.Background(), opts)
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range result.Objects {
+			if err := ctx.recoverObject(root, obj.Key); err != nil {
+				if report.Failed == nil {
+					report.Failed = make(map[string]string)
+				}
+				report.Failed[obj.Key] = err.Error()
+				continue
+			}
+			report.Recovered = append(report.Recovered, obj.Key)
+		}
+		if result.NextToken == "" {
+			return report, nil
+		}
+		opts.ContinueFrom = result.NextToken
+	}
+}
+
+// recoverObject decrypts key to its path below root.
+func (ctx *CommandContext) recoverObject(root, key string) error {
+	path := filepath.Join(root, filepath.FromSlash(key))
+	if !strings.HasPrefix(path, root+string(filepath.Separator)) {
+		return fmt.Errorf("%w: key escapes the recovery directory", common.ErrInvalidArgument)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	if err := ctx.GetCommand(key, path); err != nil {
+		_ = os.Remove(path)
+		return err
+	}
+	return nil
+}
+
+// FormatRecoveryKeyInfo formats a generated recovery key for output.
+func FormatRecoveryKeyInfo(info *RecoveryKeyInfo, format OutputFormat) string {
+	if format == FormatJSON {
+		return formatJSON(info)
+	}
+	return fmt.Sprintf("Recovery key %s\n  Private key: %s (store offline)\n  Public key:  %s\n",
+		info.ID, info.PrivateKey, info.PublicKey)
+}
+
+// FormatRecoveryReport formats a recovery report for output.
+func FormatRecoveryReport(report *RecoveryReport, format OutputFormat) string {
+	if format == FormatJSON {
+		return formatJSON(report)
+	}
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("Recovered %d objects to %s\n", len(report.Recovered), report.Directory))
+	failed := make([]string, 0, len(report.Failed))
+	for key := range report.Failed {
+		failed = append(failed, key)
+	}
+	slices.Sort(failed)
+	for _, key := range failed {
+		output.WriteString(fmt.Sprintf("  failed %s: %s\n", key, report.Failed[key]))
+	}
+	return output.String()
+}
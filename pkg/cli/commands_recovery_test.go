@@ -0,0 +1,91 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package cli
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecoverCommand(t *testing.T) {
+	dir := t.TempDir()
+	keychainPath := filepath.Join(dir, "keychain.json")
+	privatePath := filepath.Join(dir, "offline", "recovery.json")
+	publicPath := filepath.Join(dir, "recovery.pub.json")
+
+	recovery, err := RecoveryKeyGenerateCommand(privatePath, publicPath)
+	if err != nil {
+		t.Fatalf("RecoveryKeyGenerateCommand: %v", err)
+	}
+	if _, err := RecoveryKeyGenerateCommand(privatePath, publicPath); !errors.Is(err, ErrFileExists) {
+		t.Errorf("regenerating over an existing key: error = %v, want ErrFileExists", err)
+	}
+	if _, err := KeychainGenerateCommand(keychainPath, "laptop", false); err != nil {
+		t.Fatal(err)
+	}
+	info, err := KeychainEscrowCommand(keychainPath, publicPath)
+	if err != nil {
+		t.Fatalf("KeychainEscrowCommand: %v", err)
+	}
+	if info.Escrow != recovery.ID {
+		t.Errorf("keychain escrow = %q, want %q", info.Escrow, recovery.ID)
+	}
+	if out := FormatKeychainInfo(info, FormatText); !strings.Contains(out, "Escrow: recovery key "+recovery.ID) {
+		t.Errorf("unexpected text output: %s", out)
+	}
+
+	backendPath := filepath.Join(dir, "backend")
+	ctx, err := NewCommandContext(&Config{Backend: BackendLocal, BackendPath: backendPath, OutputFormat: "text", E2EKeychain: keychainPath})
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := filepath.Join(dir, "in.txt")
+	if err := os.WriteFile(input, []byte("payroll"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"hr/2026/payroll.csv", "hr/readme.txt"} {
+		if err := ctx.PutCommand(key, input); err != nil {
+			t.Fatalf("PutCommand: %v", err)
+		}
+	}
+	if _, err := ctx.RecoverCommand("hr/", dir); !errors.Is(err, ErrRecoveryKeyRequired) {
+		t.Errorf("RecoverCommand without recovery key: error = %v, want ErrRecoveryKeyRequired", err)
+	}
+
+	// The keychain is lost.
+	if err := os.Remove(keychainPath); err != nil {
+		t.Fatal(err)
+	}
+	ctx, err = NewCommandContext(&Config{Backend: BackendLocal, BackendPath: backendPath, OutputFormat: "text", E2ERecoveryKey: privatePath})
+	if err != nil {
+		t.Fatalf("NewCommandContext with recovery key: %v", err)
+	}
+	out := filepath.Join(dir, "recovered")
+	report, err := ctx.RecoverCommand("hr/", out)
+	if err != nil {
+		t.Fatalf("RecoverCommand: %v", err)
+	}
+	if len(report.Recovered) != 2 || len(report.Failed) != 0 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if got, _ := os.ReadFile(filepath.Join(out, "hr", "2026", "payroll.csv")); string(got) != "payroll" {
+		t.Errorf("recovered %q, want the plaintext", got)
+	}
+	if text := FormatRecoveryReport(report, FormatText); !strings.Contains(text, "Recovered 2 objects") {
+		t.Errorf("unexpected report output: %s", text)
+	}
+}
@@ -31,6 +31,7 @@ func (ctx *CommandContext) AddReplicationPolicyCommand(
 	interval time.Duration,
 	mode string,
 	backendKey, sourceDEK, destDEK string,
+	writeOnce bool,
 ) error {
 	// Build the policy
 	policy := common.ReplicationPolicy{
@@ -43,6 +44,7 @@ func (ctx *CommandContext) AddReplicationPolicyCommand(
 		CheckInterval:       interval,
 		Enabled:             true,
 		ReplicationMode:     common.ReplicationMode(mode),
+		WriteOnce:           writeOnce,
 	}
 
 	// Add encryption config if any keys are specified
@@ -205,6 +207,9 @@ func formatReplicationPoliciesText(policies []common.ReplicationPolicy) string {
 		}
 		output.WriteString(fmt.Sprintf("  Destination: %s\n", p.DestinationBackend))
 		output.WriteString(fmt.Sprintf("  Mode: %s\n", p.ReplicationMode))
+		if p.WriteOnce {
+			output.WriteString("  Write Once: true\n")
+		}
 		output.WriteString(fmt.Sprintf("  Enabled: %v\n", p.Enabled))
 		output.WriteString(fmt.Sprintf("  Check Interval: %s\n", p.CheckInterval))
 		if !p.LastSyncTime.IsZero() {
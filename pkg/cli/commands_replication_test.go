@@ -32,9 +32,9 @@ type MockReplicationClient struct {
 }
 
 // Object operations (required by Client interface)
-func (m *MockReplicationClient) Put(ctx context.Context, key string, reader io.Reader, metadata *common.Metadata) error {
+func (m *MockReplicationClient) Put(ctx context.Context, key string, reader io.Reader, metadata *common.Metadata) (*common.ObjectInfo, error) {
 	args := m.Called(ctx, key, reader, metadata)
-	return args.Error(0)
+	return &common.ObjectInfo{Key: key, Metadata: &common.Metadata{}}, args.Error(0)
 }
 
 func (m *MockReplicationClient) Get(ctx context.Context, key string) (io.ReadCloser, *common.Metadata, error) {
@@ -229,6 +229,7 @@ func TestAddReplicationPolicyCommand(t *testing.T) {
 		backendKey     string
 		sourceDEK      string
 		destDEK        string
+		writeOnce      bool
 		setupMock      func(*MockReplicationClient)
 		expectError    bool
 		expectedError  error
@@ -301,6 +302,23 @@ func TestAddReplicationPolicyCommand(t *testing.T) {
 			},
 			expectError: false,
 		},
+		{
+			name:           "write-once destination",
+			id:             "vault-policy",
+			sourceBackend:  "local",
+			destBackend:    "s3",
+			sourceSettings: map[string]string{"path": "/data"},
+			destSettings:   map[string]string{"bucket": "vault", "objectLockMode": "COMPLIANCE", "objectLockDays": "90"},
+			interval:       time.Hour,
+			mode:           "transparent",
+			writeOnce:      true,
+			setupMock: func(m *MockReplicationClient) {
+				m.On("AddReplicationPolicy", mock.Anything, mock.MatchedBy(func(p common.ReplicationPolicy) bool {
+					return p.WriteOnce && p.DestinationSettings["objectLockMode"] == "COMPLIANCE"
+				})).Return(nil)
+			},
+			expectError: false,
+		},
 		{
 			name:           "client returns error",
 			id:             "fail-policy",
@@ -334,6 +352,7 @@ func TestAddReplicationPolicyCommand(t *testing.T) {
 				tt.sourceSettings, tt.destSettings,
 				tt.prefix, tt.interval, tt.mode,
 				tt.backendKey, tt.sourceDEK, tt.destDEK,
+				tt.writeOnce,
 			)
 
 			if tt.expectError {
@@ -363,6 +382,7 @@ func TestAddReplicationPolicyCommand_LocalStorage(t *testing.T) {
 		map[string]string{"bucket": "backup"},
 		"", 5*time.Minute, "transparent",
 		"", "", "",
+		false,
 	)
 
 	assert.ErrorIs(t, err, common.ErrReplicationNotSupported)
@@ -0,0 +1,217 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/cli/client"
+	"github.com/jeremyhahn/go-objstore/pkg/retention"
+)
+
+// retentionClient returns the remote client's retention API. Legal holds and
+// deletion requests live on the server, so local mode is not supported.
+func (ctx *CommandContext) retentionClient() (client.RetentionManager, error) {
+	if ctx.Client == nil {
+		return nil, ErrRetentionNotSupported
+	}
+	manager, ok := client.Optional[client.RetentionManager](ctx.Client)
+	if !ok {
+		return nil, ErrRetentionNotSupported
+	}
+	return manager, nil
+}
+
+// ListDeletionRequestsCommand lists deletion requests, all of them when
+// status is empty
+func (ctx *CommandContext) ListDeletionRequestsCommand(status string) ([]retention.DeletionRequest, error) {
+	manager, err := ctx.retentionClient()
+	if err != nil {
+		return nil, err
+	}
+	return manager.ListDeletionRequests(context.Background(), retention.Status(status))
+}
+
+// ApproveDeletionCommand approves a pending deletion request, deleting the object
+func (ctx *CommandContext) ApproveDeletionCommand(id string) (*retention.DeletionRequest, error) {
+	manager, err := ctx.retentionClient()
+	if err != nil {
+		return nil, err
+	}
+	return manager.ApproveDeletion(context.Background(), id)
+}
+
+// RejectDeletionCommand rejects a pending deletion request, keeping the object
+func (ctx *CommandContext) RejectDeletionCommand(id, reason string) (*retention.DeletionRequest, error) {
+	manager, err := ctx.retentionClient()
+	if err != nil {
+		return nil, err
+	}
+	return manager.RejectDeletion(context.Background(), id, reason)
+}
+
+// ListLegalHoldsCommand lists the objects under legal hold
+func (ctx *CommandContext) ListLegalHoldsCommand() ([]retention.Hold, error) {
+	manager, err := ctx.retentionClient()
+	if err != nil {
+		return nil, err
+	}
+	return manager.ListLegalHolds(context.Background())
+}
+
+// PlaceLegalHoldCommand places a legal hold on an object
+func (ctx *CommandContext) PlaceLegalHoldCommand(key, reason string) (*retention.Hold, error) {
+	manager, err := ctx.retentionClient()
+	if err != nil {
+		return nil, err
+	}
+	return manager.PlaceLegalHold(context.Background(), key, reason)
+}
+
+// ReleaseLegalHoldCommand releases the legal hold on an object
+func (ctx *CommandContext) ReleaseLegalHoldCommand(key string) error {
+	manager, err := ctx.retentionClient()
+	if err != nil {
+		return err
+	}
+	return manager.ReleaseLegalHold(context.Background(), key)
+}
+
+// FormatDeletionRequestsResult formats deletion requests for output
+func FormatDeletionRequestsResult(requests []retention.DeletionRequest, format OutputFormat) string {
+	switch format {
+	case FormatJSON:
+		return formatJSON(requests)
+	case FormatTable:
+		return formatDeletionRequestsTable(requests)
+	default:
+		return formatDeletionRequestsText(requests)
+	}
+}
+
+// FormatLegalHoldsResult formats legal holds for output
+func FormatLegalHoldsResult(holds []retention.Hold, format OutputFormat) string {
+	switch format {
+	case FormatJSON:
+		return formatJSON(holds)
+	case FormatTable:
+		return formatLegalHoldsTable(holds)
+	default:
+		return formatLegalHoldsText(holds)
+	}
+}
+
+func formatDeletionRequestsText(requests []retention.DeletionRequest) string {
+	if len(requests) == 0 {
+		return "No deletion requests\n"
+	}
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("Total requests: %d\n\n", len(requests)))
+
+	for i := range requests {
+		r := &requests[i]
+		output.WriteString(fmt.Sprintf("ID: %s\n", r.ID))
+		output.WriteString(fmt.Sprintf("  Key: %s\n", r.Key))
+		output.WriteString(fmt.Sprintf("  Status: %s\n", r.Status))
+		output.WriteString(fmt.Sprintf("  Requested: %s by %s\n", r.RequestedAt.Format(time.RFC3339), orUnknown(r.RequestedBy)))
+		if r.DecidedAt != nil {
+			output.WriteString(fmt.Sprintf("  Decided: %s by %s\n", r.DecidedAt.Format(time.RFC3339), orUnknown(r.DecidedBy)))
+		}
+		if r.Reason != "" {
+			output.WriteString(fmt.Sprintf("  Reason: %s\n", r.Reason))
+		}
+		output.WriteString("\n")
+	}
+
+	return output.String()
+}
+
+func formatDeletionRequestsTable(requests []retention.DeletionRequest) string {
+	if len(requests) == 0 {
+		return "No deletion requests\n"
+	}
+
+	var output strings.Builder
+	output.WriteString("┌──────────────────────────────────────┬──────────────────────────┬──────────┬──────────────┬──────────────────┐\n")
+	output.WriteString("│ ID                                   │ Key                      │ Status   │ Requested By │ Requested At     │\n")
+	output.WriteString("├──────────────────────────────────────┼──────────────────────────┼──────────┼──────────────┼──────────────────┤\n")
+
+	for i := range requests {
+		r := &requests[i]
+		output.WriteString(fmt.Sprintf("│ %-36s │ %-24s │ %-8s │ %-12s │ %-16s │\n",
+			truncateString(r.ID, 36),
+			truncateString(r.Key, 24),
+			r.Status,
+			truncateString(orUnknown(r.RequestedBy), 12),
+			r.RequestedAt.Format("2006-01-02 15:04")))
+	}
+
+	output.WriteString("└──────────────────────────────────────┴──────────────────────────┴──────────┴──────────────┴──────────────────┘\n")
+	return output.String()
+}
+
+func formatLegalHoldsText(holds []retention.Hold) string {
+	if len(holds) == 0 {
+		return "No legal holds\n"
+	}
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("Total holds: %d\n\n", len(holds)))
+
+	for i := range holds {
+		h := &holds[i]
+		output.WriteString(fmt.Sprintf("Key: %s\n", h.Key))
+		output.WriteString(fmt.Sprintf("  Placed: %s by %s\n", h.PlacedAt.Format(time.RFC3339), orUnknown(h.PlacedBy)))
+		if h.Reason != "" {
+			output.WriteString(fmt.Sprintf("  Reason: %s\n", h.Reason))
+		}
+		output.WriteString("\n")
+	}
+
+	return output.String()
+}
+
+func formatLegalHoldsTable(holds []retention.Hold) string {
+	if len(holds) == 0 {
+		return "No legal holds\n"
+	}
+
+	var output strings.Builder
+	output.WriteString("┌──────────────────────────────────┬──────────────┬──────────────────┬──────────────────────────┐\n")
+	output.WriteString("│ Key                              │ Placed By    │ Placed At        │ Reason                   │\n")
+	output.WriteString("├──────────────────────────────────┼──────────────┼──────────────────┼──────────────────────────┤\n")
+
+	for i := range holds {
+		h := &holds[i]
+		output.WriteString(fmt.Sprintf("│ %-32s │ %-12s │ %-16s │ %-24s │\n",
+			truncateString(h.Key, 32),
+			truncateString(orUnknown(h.PlacedBy), 12),
+			h.PlacedAt.Format("2006-01-02 15:04"),
+			truncateString(h.Reason, 24)))
+	}
+
+	output.WriteString("└──────────────────────────────────┴──────────────┴──────────────────┴──────────────────────────┘\n")
+	return output.String()
+}
+
+func orUnknown(s string) string {
+	if s == "" {
+		return "unknown"
+	}
+	return s
+}
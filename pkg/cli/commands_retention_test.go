@@ -0,0 +1,53 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package cli
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/retention"
+)
+
+func TestRetentionCommands_LocalMode(t *testing.T) {
+	ctx := &CommandContext{Config: &Config{}}
+	if _, err := ctx.ListDeletionRequestsCommand(""); !errors.Is(err, ErrRetentionNotSupported) {
+		t.Errorf("expected ErrRetentionNotSupported, got %v", err)
+	}
+	if err := ctx.ReleaseLegalHoldCommand("records/a.csv"); !errors.Is(err, ErrRetentionNotSupported) {
+		t.Errorf("expected ErrRetentionNotSupported, got %v", err)
+	}
+}
+
+func TestFormatRetentionResults(t *testing.T) {
+	at := time.Date(2025, 11, 5, 10, 0, 0, 0, time.UTC)
+	requests := []retention.DeletionRequest{{
+		ID: "req-1", Key: "records/a.csv", Status: retention.StatusPending, RequestedBy: "alice", RequestedAt: at,
+	}}
+	holds := []retention.Hold{{Key: "records/a.csv", PlacedAt: at, Reason: "litigation"}}
+
+	for _, format := range []OutputFormat{FormatText, FormatTable, FormatJSON} {
+		if out := FormatDeletionRequestsResult(requests, format); !strings.Contains(out, "req-1") || !strings.Contains(out, "alice") {
+			t.Errorf("FormatDeletionRequestsResult(%s) = %q", format, out)
+		}
+		if out := FormatLegalHoldsResult(holds, format); !strings.Contains(out, "records/a.csv") || !strings.Contains(out, "litigation") {
+			t.Errorf("FormatLegalHoldsResult(%s) = %q", format, out)
+		}
+	}
+	if out := FormatDeletionRequestsResult(nil, FormatText); out == "" {
+		t.Error("expected a message for no deletion requests")
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package cli
+
+import (
+	"context"
+
+	"github.com/jeremyhahn/go-objstore/pkg/cli/client"
+	"github.com/jeremyhahn/go-objstore/pkg/search"
+)
+
+// SearchCommand finds objects whose key or metadata match query.
+//
+// In local mode with search-index set, the persisted index is used and is
+// rebuilt from a full listing when rebuild is true or it is still empty.
+// Without search-index, a temporary index is built from a full listing for
+// this invocation. In remote mode the server's search API is used.
+func (ctx *CommandContext) SearchCommand(query string, limit int, rebuild bool) ([]ObjectInfo, error) {
+	ctxBg := context.Background()
+
+	var docs []search.Document
+	var err error
+
+	if ctx.Client != nil {
+		searcher, ok := client.Optional[client.Searcher](ctx.Client)
+		if !ok {
+			return nil, ErrSearchNotSupported
+		}
+		docs, err = searcher.Search(ctxBg, query, limit)
+	} else {
+		indexed, ok := ctx.Storage.(*search.Storage)
+		if !ok {
+			index, _ := search.NewIndex("")
+			indexed = search.NewStorage(ctx.Storage, index)
+			rebuild = true
+		}
+		if rebuild || indexed.Index().Len() == 0 {
+			if err := indexed.Index().Rebuild(ctxBg, indexed.Underlying()); err != nil {
+				return nil, err
+			}
+		}
+		docs, err = indexed.Search(query, limit)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]ObjectInfo, 0, len(docs))
+	for _, doc := range docs {
+		objects = append(objects, ObjectInfo{
+			Key:          doc.Key,
+			Size:         doc.Size,
+			LastModified: doc.LastModified,
+		})
+	}
+	return objects, nil
+}
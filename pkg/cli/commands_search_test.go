@@ -0,0 +1,90 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/search"
+)
+
+func TestSearchCommand(t *testing.T) {
+	cfg := &Config{
+		Backend:      BackendLocal,
+		BackendPath:  t.TempDir(),
+		OutputFormat: "text",
+		SearchIndex:  filepath.Join(t.TempDir(), "index.json"),
+	}
+	ctx, err := NewCommandContext(cfg)
+	if err != nil {
+		t.Fatalf("NewCommandContext: %v", err)
+	}
+	defer ctx.Close()
+
+	if _, ok := ctx.Storage.(*search.Storage); !ok {
+		t.Fatalf("expected search storage, got %T", ctx.Storage)
+	}
+	file := filepath.Join(t.TempDir(), "invoice.pdf")
+	if err := os.WriteFile(file, []byte("pdf"), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if err := ctx.PutCommandWithMetadata("invoices/2025-01.pdf", file, "application/pdf", "", "", map[string]string{"customer": "acme"}); err != nil {
+		t.Fatalf("PutCommandWithMetadata: %v", err)
+	}
+	if err := ctx.Storage.PutWithMetadata(context.Background(), "notes/todo.txt", strings.NewReader("todo"), &common.Metadata{}); err != nil {
+		t.Fatalf("PutWithMetadata: %v", err)
+	}
+
+	// A later invocation reads the persisted index.
+	next, err := NewCommandContext(cfg)
+	if err != nil {
+		t.Fatalf("NewCommandContext: %v", err)
+	}
+	defer next.Close()
+	objects, err := next.SearchCommand("customer:acme", 0, false)
+	if err != nil {
+		t.Fatalf("SearchCommand: %v", err)
+	}
+	if len(objects) != 1 || objects[0].Key != "invoices/2025-01.pdf" || objects[0].Size != 3 {
+		t.Errorf("unexpected results: %+v", objects)
+	}
+
+	// Without an index file, a temporary index is built from a listing.
+	cfg.SearchIndex = ""
+	plain, err := NewCommandContext(cfg)
+	if err != nil {
+		t.Fatalf("NewCommandContext: %v", err)
+	}
+	defer plain.Close()
+	objects, err = plain.SearchCommand("todo", 0, false)
+	if err != nil {
+		t.Fatalf("SearchCommand: %v", err)
+	}
+	if len(objects) != 1 || objects[0].Key != "notes/todo.txt" {
+		t.Errorf("unexpected results: %+v", objects)
+	}
+}
+
+func TestSearchCommand_RemoteMode(t *testing.T) {
+	ctx := &CommandContext{Client: &mockClient{}, Config: &Config{}}
+	if _, err := ctx.SearchCommand("report", 0, false); !errors.Is(err, ErrSearchNotSupported) {
+		t.Errorf("expected ErrSearchNotSupported, got %v", err)
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/cli/client"
+	"github.com/jeremyhahn/go-objstore/pkg/share"
+)
+
+// SharedLink is a newly created share link with its download URL, which
+// the server only returns when the link is created.
+type SharedLink struct {
+	share.Link
+	URL string `json:"url"`
+}
+
+// sharesClient returns the remote client's share link API. Links are served
+// by the server, so local mode is not supported.
+func (ctx *CommandContext) sharesClient() (client.ShareManager, error) {
+	if ctx.Client == nil {
+		return nil, ErrSharesNotSupported
+	}
+	manager, ok := client.Optional[client.ShareManager](ctx.Client)
+	if !ok {
+		return nil, ErrSharesNotSupported
+	}
+	return manager, nil
+}
+
+// ShareCreateCommand creates a public download link to an object
+func (ctx *CommandContext) ShareCreateCommand(key string, opts share.Options) (*SharedLink, error) {
+	manager, err := ctx.sharesClient()
+	if err != nil {
+		return nil, err
+	}
+	link, url, err := manager.CreateShare(context.Background(), key, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &SharedLink{Link: *link, URL: url}, nil
+}
+
+// SharesListCommand lists the server's share links with their download
+// accounting
+func (ctx *CommandContext) SharesListCommand() ([]share.Link, error) {
+	manager, err := ctx.sharesClient()
+	if err != nil {
+		return nil, err
+	}
+	return manager.ListShares(context.Background())
+}
+
+// ShareRevokeCommand revokes a share link
+func (ctx *CommandContext) ShareRevokeCommand(id string) error {
+	manager, err := ctx.sharesClient()
+	if err != nil {
+		return err
+	}
+	return manager.RevokeShare(context.Background(), id)
+}
+
+// FormatSharedLinkResult formats a newly created share link for output
+func FormatSharedLinkResult(link *SharedLink, format OutputFormat) string {
+	switch format {
+	case FormatJSON:
+		return formatJSON(link)
+	case FormatTable:
+		return formatSharesTable([]share.Link{link.Link}) + link.URL + "\n"
+	default:
+		return link.URL + "\n" + formatSharesText([]share.Link{link.Link})
+	}
+}
+
+// FormatSharesResult formats share links for output
+func FormatSharesResult(links []share.Link, format OutputFormat) string {
+	switch format {
+	case FormatJSON:
+		return formatJSON(links)
+	case FormatTable:
+		return formatSharesTable(links)
+	default:
+		return formatSharesText(links)
+	}
+}
+
+// shareDownloads describes a link's downloads against its limit.
+func shareDownloads(link *share.Link) string {
+	if link.MaxDownloads == 0 {
+		return strconv.Itoa(link.Downloads)
+	}
+	return fmt.Sprintf("%d/%d", link.Downloads, link.MaxDownloads)
+}
+
+func formatSharesText(links []share.Link) string {
+	if len(links) == 0 {
+		return "No share links\n"
+	}
+
+	now := time.Now()
+	var output strings.Builder
+	for i := range links {
+		l := &links[i]
+		output.WriteString(fmt.Sprintf("%s (%s)\n", l.ID, l.Status(now)))
+		output.WriteString(fmt.Sprintf("  Key: %s\n", l.Key))
+		output.WriteString(fmt.Sprintf("  Created: %s by %s\n", l.CreatedAt.Format(time.RFC3339), orUnknown(l.CreatedBy)))
+		output.WriteString(fmt.Sprintf("  Expires: %s\n", l.ExpiresAt.Format(time.RFC3339)))
+		output.WriteString(fmt.Sprintf("  Downloads: %s (%s served)\n", shareDownloads(l), formatSize(l.BytesServed)))
+		if l.LastDownload != nil {
+			output.WriteString(fmt.Sprintf("  Last download: %s\n", l.LastDownload.Format(time.RFC3339)))
+		}
+		if l.BytesPerSecond > 0 {
+			output.WriteString(fmt.Sprintf("  Bandwidth: %s/s\n", formatSize(l.BytesPerSecond)))
+		}
+		if l.Protected {
+			output.WriteString("  Password protected\n")
+		}
+		output.WriteString("\n")
+	}
+	return output.String()
+}
+
+func formatSharesTable(links []share.Link) string {
+	if len(links) == 0 {
+		return "No share links\n"
+	}
+
+	now := time.Now()
+	var output strings.Builder
+	output.WriteString("┌──────────────────────────────────┬──────────────────────────────────┬───────────┬───────────┬────────────┬──────────────────┐\n")
+	output.WriteString("│ ID                               │ Key                              │ Status    │ Downloads │ Served     │ Expires          │\n")
+	output.WriteString("├──────────────────────────────────┼──────────────────────────────────┼───────────┼───────────┼────────────┼──────────────────┤\n")
+	for i := range links {
+		l := &links[i]
+		output.WriteString(fmt.Sprintf("│ %-32s │ %-32s │ %-9s │ %-9s │ %-10s │ %-16s │\n",
+			truncateString(l.ID, 32),
+			truncateString(l.Key, 32),
+			truncateString(string(l.Status(now)), 9),
+			truncateString(shareDownloads(l), 9),
+			formatSize(l.BytesServed),
+			l.ExpiresAt.Format("2006-01-02 15:04")))
+	}
+	output.WriteString("└──────────────────────────────────┴──────────────────────────────────┴───────────┴───────────┴────────────┴──────────────────┘\n")
+	return output.String()
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package cli
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/share"
+)
+
+func TestShareCommands_LocalMode(t *testing.T) {
+	ctx := &CommandContext{Config: &Config{}}
+	if _, err := ctx.ShareCreateCommand("reports/q1.pdf", share.Options{}); !errors.Is(err, ErrSharesNotSupported) {
+		t.Errorf("expected ErrSharesNotSupported, got %v", err)
+	}
+	if _, err := ctx.SharesListCommand(); !errors.Is(err, ErrSharesNotSupported) {
+		t.Errorf("expected ErrSharesNotSupported, got %v", err)
+	}
+	if err := ctx.ShareRevokeCommand("3f2a9c1e"); !errors.Is(err, ErrSharesNotSupported) {
+		t.Errorf("expected ErrSharesNotSupported, got %v", err)
+	}
+}
+
+func TestFormatSharesResults(t *testing.T) {
+	now := time.Now()
+	link := share.Link{
+		ID: "3f2a9c1e5b7d4e6f8a0b1c2d3e4f5a6b", Key: "reports/q1.pdf", CreatedBy: "alice",
+		CreatedAt: now, ExpiresAt: now.Add(time.Hour), MaxDownloads: 2, Downloads: 2, BytesServed: 2048,
+	}
+
+	for _, format := range []OutputFormat{FormatText, FormatTable, FormatJSON} {
+		if out := FormatSharesResult([]share.Link{link}, format); !strings.Contains(out, link.ID) || !strings.Contains(out, "reports/q1.pdf") {
+			t.Errorf("FormatSharesResult(%s) = %q", format, out)
+		}
+		created := &SharedLink{Link: link, URL: "https://objstore/share/tok"}
+		if out := FormatSharedLinkResult(created, format); !strings.Contains(out, "https://objstore/share/tok") {
+			t.Errorf("FormatSharedLinkResult(%s) = %q", format, out)
+		}
+	}
+	if out := FormatSharesResult([]share.Link{link}, FormatText); !strings.Contains(out, "exhausted") || !strings.Contains(out, "2/2") {
+		t.Errorf("expected status and download count, got %q", out)
+	}
+	if out := FormatSharesResult(nil, FormatText); out == "" {
+		t.Error("expected a message for no share links")
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package cli
+
+import (
+	"context"
+	"crypto/ed25519"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/signing"
+)
+
+// SigningKeyInfo describes a generated signing key pair.
+type SigningKeyInfo struct {
+	ID         string `json:"id"`
+	PrivateKey string `json:"private_key"`
+	PublicKey  string `json:"public_key"`
+}
+
+// SigningKeyGenerateCommand generates an ed25519 signing key. The private
+// key is written to privatePath, for --sign-key, and the public key to
+// publicPath, for the --verify-keys of consumers. Existing files are never
+// overwritten.
+func SigningKeyGenerateCommand(privatePath, publicPath string) (*SigningKeyInfo, error) {
+	for _, path := range []string{privatePath, publicPath} {
+		if _, err := os.Stat(path); err == nil {
+			return nil, fmt.Errorf("%w: %s", ErrFileExists, path)
+		}
+	}
+	key, err := signing.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	public := key.Public().(ed25519.PublicKey)
+	if err := signing.SavePrivateKey(privatePath, key); err != nil {
+		return nil, err
+	}
+	if err := signing.SavePublicKey(publicPath, public); err != nil {
+		return nil, err
+	}
+	return &SigningKeyInfo{ID: signing.KeyID(public), PrivateKey: privatePath, PublicKey: publicPath}, nil
+}
+
+// VerifyCommand reads the object stored under key, checking its content
+// against its signed manifest, and returns the manifest.
+func (ctx *CommandContext) VerifyCommand(key string) (*signing.Manifest, error) {
+	if ctx.verifier == nil {
+		return nil, ErrVerifyKeysRequired
+	}
+	ctxBg := context.Background()
+
+	// Reads verify the signature and content when --verify-keys is set.
+	if ctx.Client != nil {
+		reader, metadata, err := ctx.Client.Get(ctxBg, key)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = reader.Close() }()
+		if _, err := io.Copy(io.Discard, reader); err != nil {
+			return nil, err
+		}
+		return ctx.verifier.Verify(key, metadata)
+	}
+
+	reader, err := ctx.Storage.GetWithContext(ctxBg, key)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = reader.Close() }()
+	if _, err := io.Copy(io.Discard, reader); err != nil {
+		return nil, err
+	}
+	metadata, err := ctx.Storage.GetMetadata(ctxBg, key)
+	if err != nil {
+		return nil, err
+	}
+	return ctx.verifier.Verify(key, metadata)
+}
+
+// FormatSigningKeyInfo formats a generated signing key for output.
+func FormatSigningKeyInfo(info *SigningKeyInfo, format OutputFormat) string {
+	if format == FormatJSON {
+		return formatJSON(info)
+	}
+	return fmt.Sprintf("Signing key %s\n  Private key: %s (for --sign-key)\n  Public key:  %s (for --verify-keys)\n",
+		info.ID, info.PrivateKey, info.PublicKey)
+}
+
+// FormatManifest formats a verified manifest for output.
+func FormatManifest(m *signing.Manifest, format OutputFormat) string {
+	if format == FormatJSON {
+		return formatJSON(m)
+	}
+	return fmt.Sprintf("Verified %s\n  Size:      %d\n  SHA-256:   %s\n  Signed by: %s\n  Signed at: %s\n",
+		m.Key, m.Size, m.SHA256, m.KeyID, m.SignedAt.Format(time.RFC3339))
+}
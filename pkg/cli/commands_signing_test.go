@@ -0,0 +1,72 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package cli
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/signing"
+)
+
+func TestVerifyCommand(t *testing.T) {
+	dir := t.TempDir()
+	privatePath := filepath.Join(dir, "release.pem")
+	publicPath := filepath.Join(dir, "release.pub")
+	key, err := SigningKeyGenerateCommand(privatePath, publicPath)
+	if err != nil {
+		t.Fatalf("SigningKeyGenerateCommand: %v", err)
+	}
+	if _, err := SigningKeyGenerateCommand(privatePath, publicPath); !errors.Is(err, ErrFileExists) {
+		t.Errorf("regenerating over an existing key: error = %v, want ErrFileExists", err)
+	}
+
+	backendPath := filepath.Join(dir, "backend")
+	producer, err := NewCommandContext(&Config{Backend: BackendLocal, BackendPath: backendPath, OutputFormat: "text", SignKey: privatePath})
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := filepath.Join(dir, "app.tgz")
+	if err := os.WriteFile(input, []byte("release 1.0"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := producer.PutCommand("releases/app.tgz", input); err != nil {
+		t.Fatalf("PutCommand: %v", err)
+	}
+	if _, err := producer.VerifyCommand("releases/app.tgz"); !errors.Is(err, ErrVerifyKeysRequired) {
+		t.Errorf("VerifyCommand without keys: error = %v, want ErrVerifyKeysRequired", err)
+	}
+
+	consumer, err := NewCommandContext(&Config{Backend: BackendLocal, BackendPath: backendPath, OutputFormat: "text", VerifyKeys: []string{publicPath}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifest, err := consumer.VerifyCommand("releases/app.tgz")
+	if err != nil {
+		t.Fatalf("VerifyCommand: %v", err)
+	}
+	if manifest.KeyID != key.ID || manifest.Size != int64(len("release 1.0")) {
+		t.Errorf("manifest = %+v", manifest)
+	}
+
+	// The backend is compromised and the artifact replaced.
+	if err := os.WriteFile(filepath.Join(backendPath, "releases", "app.tgz"), []byte("release 6.6"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := consumer.VerifyCommand("releases/app.tgz"); !errors.Is(err, signing.ErrTampered) {
+		t.Errorf("VerifyCommand after tampering: error = %v, want ErrTampered", err)
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/lifecycle"
+)
+
+// SimulatePoliciesCommand replays the inventory export at inventoryPath,
+// as written by `list --all --output-file`, against the lifecycle policies
+// in policiesPath, or against the current policies when it is empty, and
+// reports what they would delete, archive or transition each month.
+// Nothing is changed.
+func (ctx *CommandContext) SimulatePoliciesCommand(inventoryPath, policiesPath string, opts *lifecycle.Options) (*lifecycle.Report, error) {
+	var policies []common.LifecyclePolicy
+	var err error
+	if policiesPath != "" {
+		policies, err = lifecycle.LoadFile(policiesPath)
+	} else {
+		policies, err = ctx.ListPoliciesCommand()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(inventoryPath) // #nosec G304 -- path supplied by the operator
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+	return lifecycle.Simulate(file, policies, opts)
+}
+
+// FormatSimulationReport formats a lifecycle simulation for output
+func FormatSimulationReport(report *lifecycle.Report, format OutputFormat) string {
+	switch format {
+	case FormatJSON:
+		return formatJSON(report)
+	case FormatTable:
+		return formatSimulationSummary(report) + formatSimulationMonths(report) + formatSimulationPolicies(report)
+	default:
+		return formatSimulationText(report)
+	}
+}
+
+func formatSimulationSummary(report *lifecycle.Report) string {
+	return fmt.Sprintf("%d objects (%s) as of %s; %d objects (%s) retained through %s\n",
+		report.Inventory.Objects, formatSize(report.Inventory.Bytes), report.SnapshotTime.Format(time.DateOnly),
+		report.Retained.Objects, formatSize(report.Retained.Bytes), report.Until.Format(time.DateOnly))
+}
+
+func formatSimulationText(report *lifecycle.Report) string {
+	var output strings.Builder
+	output.WriteString(formatSimulationSummary(report))
+	if len(report.Months) == 0 {
+		output.WriteString("\nNo objects would be affected.\n")
+	} else {
+		output.WriteString("\nBy month (* projected):\n")
+		for _, m := range report.Months {
+			marker := " "
+			if m.Projected {
+				marker = "*"
+			}
+			output.WriteString(fmt.Sprintf("  %s%s  deleted %s  archived %s  transitioned %s\n",
+				m.Month, marker, formatTally(m.Deleted), formatTally(m.Archived), formatTally(m.Transitioned)))
+		}
+	}
+	if len(report.Policies) > 0 {
+		output.WriteString("\nBy policy:\n")
+		output.WriteString(formatSimulationPolicies(report))
+	}
+	return output.String()
+}
+
+func formatSimulationMonths(report *lifecycle.Report) string {
+	if len(report.Months) == 0 {
+		return ""
+	}
+	header := []string{"Month", "Projected", "Deleted", "Archived", "Transitioned"}
+	rows := make([][]string, 0, len(report.Months))
+	for _, m := range report.Months {
+		rows = append(rows, []string{m.Month, fmt.Sprintf("%t", m.Projected),
+			formatTally(m.Deleted), formatTally(m.Archived), formatTally(m.Transitioned)})
+	}
+	return formatBoxTable(header, rows)
+}
+
+func formatSimulationPolicies(report *lifecycle.Report) string {
+	if len(report.Policies) == 0 {
+		return ""
+	}
+	header := []string{"Policy", "Prefix", "Retention", "Action", "Matched", "Applied"}
+	rows := make([][]string, 0, len(report.Policies))
+	for _, p := range report.Policies {
+		rows = append(rows, []string{p.ID, groupLabel(p.Prefix), fmt.Sprintf("%gd", p.RetentionDays),
+			p.Action, formatTally(p.Matched), formatTally(p.Applied)})
+	}
+	return formatBoxTable(header, rows)
+}
+
+// formatTally formats a count of objects and their size as "3 (1.2 KB)".
+func formatTally(t lifecycle.Tally) string {
+	return fmt.Sprintf("%d (%s)", t.Objects, formatSize(t.Bytes))
+}
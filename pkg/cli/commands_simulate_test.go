@@ -0,0 +1,77 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/lifecycle"
+)
+
+func TestSimulatePoliciesCommand(t *testing.T) {
+	ctx := newExportTestContext(t, 3)
+	dir := t.TempDir()
+
+	inventory := filepath.Join(dir, "inventory.jsonl")
+	file, err := os.Create(inventory)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ctx.ExportListCommand("", file, ExportOptions{All: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	policies := filepath.Join(dir, "policies.yaml")
+	data := "policies:\n  - id: expire-data\n    prefix: data/\n    retention_days: 30\n    action: delete\n"
+	if err := os.WriteFile(policies, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// The objects were written today, so they expire in a month
+	report, err := ctx.SimulatePoliciesCommand(inventory, policies, &lifecycle.Options{SnapshotTime: time.Now()})
+	if err != nil {
+		t.Fatalf("SimulatePoliciesCommand() error = %v", err)
+	}
+	if report.Inventory.Objects != 3 || report.Retained.Objects != 0 || len(report.Months) != 1 || !report.Months[0].Projected {
+		t.Errorf("report = %+v, want 3 objects deleted in one projected month", report)
+	}
+	for _, format := range []OutputFormat{FormatText, FormatTable, FormatJSON} {
+		if out := FormatSimulationReport(report, format); !strings.Contains(out, "expire-data") {
+			t.Errorf("FormatSimulationReport(%s) = %q, want the policy", format, out)
+		}
+	}
+
+	// Without a policy file the current policies are simulated
+	report, err = ctx.SimulatePoliciesCommand(inventory, "", nil)
+	if err != nil {
+		t.Fatalf("SimulatePoliciesCommand() error = %v", err)
+	}
+	if report.Retained.Objects != 3 || len(report.Policies) != 0 {
+		t.Errorf("report = %+v, want every object retained without policies", report)
+	}
+	if out := FormatSimulationReport(report, FormatText); !strings.Contains(out, "No objects would be affected") {
+		t.Errorf("FormatSimulationReport() = %q", out)
+	}
+
+	if _, err := ctx.SimulatePoliciesCommand(filepath.Join(dir, "missing.jsonl"), policies, nil); err == nil {
+		t.Error("SimulatePoliciesCommand() with a missing inventory succeeded")
+	}
+}
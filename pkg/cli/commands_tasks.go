@@ -0,0 +1,131 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jeremyhahn/go-objstore/pkg/cli/client"
+	"github.com/jeremyhahn/go-objstore/pkg/tasks"
+)
+
+// tasksClient returns the remote client's task queue API. The queue lives in
+// the server, so local mode is not supported.
+func (ctx *CommandContext) tasksClient() (client.TaskManager, error) {
+	if ctx.Client == nil {
+		return nil, ErrTasksNotSupported
+	}
+	manager, ok := client.Optional[client.TaskManager](ctx.Client)
+	if !ok {
+		return nil, ErrTasksNotSupported
+	}
+	return manager, nil
+}
+
+// TasksListCommand lists the server's queued tasks, optionally filtered by
+// state
+func (ctx *CommandContext) TasksListCommand(state string) ([]tasks.Task, error) {
+	manager, err := ctx.tasksClient()
+	if err != nil {
+		return nil, err
+	}
+	return manager.ListTasks(context.Background(), tasks.State(state))
+}
+
+// TaskRetryCommand requeues a dead-lettered task for another round of
+// attempts
+func (ctx *CommandContext) TaskRetryCommand(id string) (*tasks.Task, error) {
+	manager, err := ctx.tasksClient()
+	if err != nil {
+		return nil, err
+	}
+	return manager.RetryTask(context.Background(), id)
+}
+
+// FormatTasksResult formats a list of queued tasks for output
+func FormatTasksResult(list []tasks.Task, format OutputFormat) string {
+	switch format {
+	case FormatJSON:
+		return formatJSON(list)
+	case FormatTable:
+		return formatTasksTable(list)
+	default:
+		return formatTasksText(list)
+	}
+}
+
+// FormatTaskResult formats a single task for output
+func FormatTaskResult(task *tasks.Task, format OutputFormat) string {
+	switch format {
+	case FormatJSON:
+		return formatJSON(task)
+	case FormatTable:
+		return formatTasksTable([]tasks.Task{*task})
+	default:
+		return formatTasksText([]tasks.Task{*task})
+	}
+}
+
+func formatTasksText(list []tasks.Task) string {
+	if len(list) == 0 {
+		return "No tasks\n"
+	}
+
+	var output strings.Builder
+	for i := range list {
+		t := &list[i]
+		output.WriteString(fmt.Sprintf("%s (%s)\n", t.ID, t.Type))
+		output.WriteString(fmt.Sprintf("  State: %s\n", t.State))
+		output.WriteString(fmt.Sprintf("  Attempts: %d of %d\n", t.Attempts, t.MaxAttempts))
+		if t.State == tasks.StatePending {
+			output.WriteString(fmt.Sprintf("  Next attempt: %s\n", jobTime(t.NextAttempt)))
+		}
+		if t.LastError != "" {
+			output.WriteString(fmt.Sprintf("  Last error: %s\n", t.LastError))
+		}
+		output.WriteString(fmt.Sprintf("  Created: %s\n", jobTime(t.CreatedAt)))
+		output.WriteString(fmt.Sprintf("  Updated: %s\n", jobTime(t.UpdatedAt)))
+		output.WriteString("\n")
+	}
+	return output.String()
+}
+
+func formatTasksTable(list []tasks.Task) string {
+	if len(list) == 0 {
+		return "No tasks\n"
+	}
+
+	var output strings.Builder
+	output.WriteString("┌──────────────────────────────────┬──────────────────┬───────────┬──────────┬──────────────────┬──────────────────────────────────┐\n")
+	output.WriteString("│ ID                               │ Type             │ State     │ Attempts │ Updated          │ Last Error                       │\n")
+	output.WriteString("├──────────────────────────────────┼──────────────────┼───────────┼──────────┼──────────────────┼──────────────────────────────────┤\n")
+	for i := range list {
+		t := &list[i]
+		lastErr := t.LastError
+		if lastErr == "" {
+			lastErr = "-"
+		}
+		output.WriteString(fmt.Sprintf("│ %-32s │ %-16s │ %-9s │ %-8s │ %-16s │ %-32s │\n",
+			truncateString(t.ID, 32),
+			truncateString(t.Type, 16),
+			truncateString(string(t.State), 9),
+			fmt.Sprintf("%d/%d", t.Attempts, t.MaxAttempts),
+			t.UpdatedAt.Format("2006-01-02 15:04"),
+			truncateString(lastErr, 32)))
+	}
+	output.WriteString("└──────────────────────────────────┴──────────────────┴───────────┴──────────┴──────────────────┴──────────────────────────────────┘\n")
+	return output.String()
+}
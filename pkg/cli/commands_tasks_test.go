@@ -0,0 +1,53 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package cli
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/tasks"
+)
+
+func TestTasksCommands_LocalMode(t *testing.T) {
+	ctx := &CommandContext{Config: &Config{}}
+	if _, err := ctx.TasksListCommand(""); !errors.Is(err, ErrTasksNotSupported) {
+		t.Errorf("expected ErrTasksNotSupported, got %v", err)
+	}
+	if _, err := ctx.TaskRetryCommand("t1"); !errors.Is(err, ErrTasksNotSupported) {
+		t.Errorf("expected ErrTasksNotSupported, got %v", err)
+	}
+}
+
+func TestFormatTasksResults(t *testing.T) {
+	at := time.Date(2025, 11, 5, 10, 0, 0, 0, time.UTC)
+	task := tasks.Task{
+		ID: "0f3c9a1e", Type: "archive", State: tasks.StateDead, Attempts: 8, MaxAttempts: 8,
+		LastError: "glacier unavailable", CreatedAt: at, UpdatedAt: at.Add(time.Hour),
+	}
+
+	for _, format := range []OutputFormat{FormatText, FormatTable, FormatJSON} {
+		if out := FormatTasksResult([]tasks.Task{task}, format); !strings.Contains(out, "0f3c9a1e") || !strings.Contains(out, "dead") {
+			t.Errorf("FormatTasksResult(%s) = %q", format, out)
+		}
+		if out := FormatTaskResult(&task, format); !strings.Contains(out, "glacier unavailable") {
+			t.Errorf("FormatTaskResult(%s) = %q", format, out)
+		}
+	}
+	if out := FormatTasksResult(nil, FormatText); out == "" {
+		t.Error("expected a message for no tasks")
+	}
+}
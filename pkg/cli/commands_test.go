@@ -17,12 +17,18 @@ import (
 	"context"
 	"errors"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/jeremyhahn/go-objstore/pkg/cli/client"
 	"github.com/jeremyhahn/go-objstore/pkg/common"
 	"github.com/jeremyhahn/go-objstore/pkg/replication"
 )
@@ -177,11 +183,11 @@ type mockClient struct {
 	metadataError error
 }
 
-func (m *mockClient) Put(ctx context.Context, key string, reader io.Reader, metadata *common.Metadata) error {
+func (m *mockClient) Put(ctx context.Context, key string, reader io.Reader, metadata *common.Metadata) (*common.ObjectInfo, error) {
 	if m.putError != nil {
-		return m.putError
+		return nil, m.putError
 	}
-	return nil
+	return &common.ObjectInfo{Key: key, Metadata: &common.Metadata{}}, nil
 }
 
 func (m *mockClient) Get(ctx context.Context, key string) (io.ReadCloser, *common.Metadata, error) {
@@ -361,6 +367,69 @@ func TestCommandContext_PutCommand(t *testing.T) {
 	})
 }
 
+func TestCommandContext_PutCommandDetectsContentType(t *testing.T) {
+	tmpDir := t.TempDir()
+	page := filepath.Join(tmpDir, "page.html")
+	if err := os.WriteFile(page, []byte("plain text"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	blob := filepath.Join(tmpDir, "blob")
+	if err := os.WriteFile(blob, []byte("%PDF-1.7 body"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	storage := newMockStorage()
+	ctx := &CommandContext{Storage: storage, Config: &Config{}}
+	tests := []struct {
+		key, file, contentType, want string
+	}{
+		{"docs/readme.md", page, "", "text/markdown; charset=utf-8"}, // the key's extension wins
+		{"docs/page", page, "", "text/html; charset=utf-8"},          // then the file's
+		{"docs/blob", blob, "", "application/pdf"},                   // then the data
+		{"docs/other", page, "application/custom", "application/custom"},
+	}
+	for _, tt := range tests {
+		if err := ctx.PutCommandWithMetadata(tt.key, tt.file, tt.contentType, "", "", nil); err != nil {
+			t.Fatalf("PutCommandWithMetadata(%s): %v", tt.key, err)
+		}
+		if got := storage.metadata[tt.key].ContentType; got != tt.want {
+			t.Errorf("%s: content type = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+	if string(storage.data["docs/blob"]) != "%PDF-1.7 body" {
+		t.Errorf("sniffed data not stored intact: %q", storage.data["docs/blob"])
+	}
+
+	ctx.Config.SkipContentTypeDetection = true
+	if err := ctx.PutCommand("docs/plain.html", page); err != nil {
+		t.Fatal(err)
+	}
+	if got := storage.metadata["docs/plain.html"].ContentType; got != "" {
+		t.Errorf("content type with detection disabled = %q, want none", got)
+	}
+}
+
+func TestCommandContext_PutCommandAtomic(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "app.tar.gz")
+	if err := os.WriteFile(file, []byte("release"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	storage := newMockStorage()
+	ctx := &CommandContext{Storage: storage, Config: &Config{AtomicPut: true}}
+	if err := ctx.PutCommand("releases/app.tar.gz", file); err != nil {
+		t.Fatalf("PutCommand: %v", err)
+	}
+	if got := string(storage.data["releases/app.tar.gz"]); got != "release" {
+		t.Errorf("content = %q, want release", got)
+	}
+	for key := range storage.data {
+		if strings.HasPrefix(key, common.StagingPrefix) {
+			t.Errorf("staged object %s left behind", key)
+		}
+	}
+}
+
 func TestCommandContext_GetCommand(t *testing.T) {
 	t.Run("successful get to file", func(t *testing.T) {
 		storage := newMockStorage()
@@ -539,6 +608,37 @@ func TestCommandContext_ListCommand(t *testing.T) {
 	})
 }
 
+func TestCommandContext_ListObjectsCommand(t *testing.T) {
+	storage := newMockStorage()
+	for key, data := range map[string]string{"a.txt": "aa", "b.txt": "bbbb", "c.txt": "c"} {
+		storage.data[key] = []byte(data)
+		storage.metadata[key] = &common.Metadata{
+			Size:   int64(len(data)),
+			Custom: map[string]string{"encryption_key_id": "key-" + key},
+		}
+	}
+	ctx := &CommandContext{Storage: storage, Config: &Config{OutputFormat: "text"}}
+
+	objects, err := ctx.ListObjectsCommand("", ListOptions{SortBy: common.ListSortSize, Reverse: true, Long: true})
+	if err != nil {
+		t.Fatalf("ListObjectsCommand failed: %v", err)
+	}
+	var keys []string
+	for _, obj := range objects {
+		keys = append(keys, obj.Key)
+		if obj.EncryptionKeyID != "key-"+obj.Key {
+			t.Errorf("%s: encryption key ID = %q", obj.Key, obj.EncryptionKeyID)
+		}
+	}
+	if strings.Join(keys, ",") != "b.txt,a.txt,c.txt" {
+		t.Errorf("ListObjectsCommand() sorted by size = %v", keys)
+	}
+
+	if _, err := ctx.ListObjectsCommand("", ListOptions{SortBy: "owner"}); !errors.Is(err, common.ErrInvalidArgument) {
+		t.Errorf("ListObjectsCommand(sort owner) = %v, want ErrInvalidArgument", err)
+	}
+}
+
 func TestCommandContext_ExistsCommand(t *testing.T) {
 	t.Run("object exists", func(t *testing.T) {
 		storage := newMockStorage()
@@ -975,7 +1075,7 @@ func TestPutCommandWithMetadata_EdgeCases(t *testing.T) {
 		"key2": "value2",
 	}
 
-	err = ctx.PutCommandWithMetadata("test-full-metadata.json", tmpFile.Name(), "application/json", "gzip", customFields)
+	err = ctx.PutCommandWithMetadata("test-full-metadata.json", tmpFile.Name(), "application/json", "gzip", "STANDARD_IA", customFields)
 	if err != nil {
 		t.Errorf("PutCommandWithMetadata failed: %v", err)
 	}
@@ -987,6 +1087,9 @@ func TestPutCommandWithMetadata_EdgeCases(t *testing.T) {
 	if storage.metadata["test-full-metadata.json"].ContentEncoding != "gzip" {
 		t.Error("ContentEncoding not set correctly")
 	}
+	if storage.metadata["test-full-metadata.json"].StorageClass != "STANDARD_IA" {
+		t.Error("StorageClass not set correctly")
+	}
 }
 
 func TestNewCommandContext_ErrorPaths(t *testing.T) {
@@ -1085,3 +1188,138 @@ func (m *mockClient) TriggerReplication(ctx context.Context, policyID string) (*
 func (m *mockClient) GetReplicationStatus(ctx context.Context, policyID string) (*replication.ReplicationStatus, error) {
 	return nil, nil
 }
+
+func TestExpiryTime(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	if got, err := ExpiryTime("", 72*time.Hour, now); err != nil || got != "2025-06-04T12:00:00Z" {
+		t.Errorf("ExpiryTime(expires-in) = %q, %v", got, err)
+	}
+	if got, err := ExpiryTime("2025-07-01T02:00:00+02:00", 0, now); err != nil || got != "2025-07-01T00:00:00Z" {
+		t.Errorf("ExpiryTime(expires-at) = %q, %v", got, err)
+	}
+	for _, tc := range []struct {
+		at string
+		in time.Duration
+	}{{"tomorrow", 0}, {"2025-07-01T00:00:00Z", time.Hour}, {"", 0}} {
+		if _, err := ExpiryTime(tc.at, tc.in, now); !errors.Is(err, ErrInvalidExpiry) {
+			t.Errorf("ExpiryTime(%q, %v) error = %v, want ErrInvalidExpiry", tc.at, tc.in, err)
+		}
+	}
+}
+
+// streamSize returns the payload size of the streaming tests: 2 GiB, or
+// 64 MiB with -short.
+func streamSize() int64 {
+	if testing.Short() {
+		return 64 << 20
+	}
+	return 2 << 30
+}
+
+// peakHeap samples the heap in use until stop is closed and returns the
+// highest sample.
+func peakHeap(stop <-chan struct{}) <-chan uint64 {
+	result := make(chan uint64, 1)
+	go func() {
+		var peak uint64
+		ticker := time.NewTicker(5 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			var stats runtime.MemStats
+			runtime.ReadMemStats(&stats)
+			peak = max(peak, stats.HeapInuse)
+			select {
+			case <-stop:
+				result <- peak
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return result
+}
+
+// zeroReader is an endless stream of zeros.
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+// TestRemoteStreamingConstantMemory puts and gets a multi-GB object
+// through the CLI commands and a REST client, asserting the heap stays
+// flat: the object is streamed, never held in memory.
+func TestRemoteStreamingConstantMemory(t *testing.T) {
+	size := streamSize()
+	const maxGrowth = 64 << 20
+
+	var received atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPut:
+			if r.ContentLength != -1 {
+				t.Errorf("expected a chunked upload, got Content-Length %d", r.ContentLength)
+			}
+			n, _ := io.Copy(io.Discard, r.Body)
+			received.Store(n)
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodGet:
+			w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+			_, _ = io.Copy(w, io.LimitReader(zeroReader{}, size))
+		}
+	}))
+	defer server.Close()
+
+	remote, err := client.NewClient(&client.Config{ServerURL: server.URL})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	ctx := &CommandContext{Client: remote}
+
+	runtime.GC()
+	var baseline runtime.MemStats
+	runtime.ReadMemStats(&baseline)
+
+	t.Run("put", func(t *testing.T) {
+		stdin, feed, err := os.Pipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		oldStdin := os.Stdin
+		os.Stdin = stdin
+		defer func() { os.Stdin = oldStdin; _ = stdin.Close() }()
+		go func() {
+			_, _ = io.Copy(feed, io.LimitReader(zeroReader{}, size))
+			_ = feed.Close()
+		}()
+
+		stop := make(chan struct{})
+		peak := peakHeap(stop)
+		err = ctx.PutCommand("large.bin", "-")
+		close(stop)
+		if err != nil {
+			t.Fatalf("PutCommand() error = %v", err)
+		}
+		if received.Load() != size {
+			t.Errorf("server received %d bytes, want %d", received.Load(), size)
+		}
+		if growth := int64(<-peak) - int64(baseline.HeapInuse); growth > maxGrowth {
+			t.Errorf("heap grew by %d bytes uploading %d", growth, size)
+		}
+	})
+
+	t.Run("get", func(t *testing.T) {
+		stop := make(chan struct{})
+		peak := peakHeap(stop)
+		err := ctx.GetCommand("large.bin", os.DevNull)
+		close(stop)
+		if err != nil {
+			t.Fatalf("GetCommand() error = %v", err)
+		}
+		if growth := int64(<-peak) - int64(baseline.HeapInuse); growth > maxGrowth {
+			t.Errorf("heap grew by %d bytes downloading %d", growth, size)
+		}
+	})
+}
@@ -18,7 +18,12 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/cli/client"
+	"github.com/jeremyhahn/go-objstore/pkg/download"
+	"github.com/jeremyhahn/go-objstore/pkg/e2ee"
 	"github.com/spf13/viper"
 )
 
@@ -35,6 +40,22 @@ type Config struct {
 	Server         string // Server URL for remote operations (e.g., http://localhost:8080)
 	ServerProtocol string // Server protocol: rest, grpc, or quic
 
+	// SigV4 request signing for remote REST/QUIC operations. Requests are
+	// signed when SigV4AccessKey is set.
+	SigV4AccessKey string
+	SigV4SecretKey string
+	SigV4Region    string
+
+	// Connection settings for remote operations. Timeout bounds the wait
+	// for each response (0 = client default, negative = none); Retries is
+	// how many times a request that failed transiently is retried, waiting
+	// RetryBackoff before the first retry and doubling it after each.
+	Timeout            time.Duration
+	Retries            int
+	RetryBackoff       time.Duration
+	CACert             string // PEM file of extra trusted CA certificates
+	InsecureSkipVerify bool   // Skip server certificate verification (development only)
+
 	// Encryption settings
 	EncryptionEnabled     bool
 	EncryptionKeyID       string
@@ -42,9 +63,53 @@ type Config struct {
 	EncryptionBackendPath string
 	EncryptionKMSPath     string
 
+	// E2EKeychain is the keychain file objects are encrypted with end to
+	// end. When set, objects and their metadata are encrypted before they
+	// leave the CLI and decrypted after they are read.
+	E2EKeychain string
+
+	// E2ERecoveryKey is an escrow recovery key file. It lets commands read
+	// end-to-end encrypted objects whose keychain key is not available.
+	E2ERecoveryKey string
+
+	// E2EDeterministicFields are custom metadata fields encrypted
+	// deterministically so the server can match them exactly. They require
+	// E2EAcceptEqualityLeak; see e2ee.DeterministicConfig for what the
+	// server learns.
+	E2EDeterministicFields []string
+	E2EAcceptEqualityLeak  bool
+
+	// SignKey is an ed25519 private key file objects are signed with when
+	// they are written. VerifyKeys are the public key files trusted to sign
+	// objects; when set, objects are verified when they are read.
+	SignKey    string
+	VerifyKeys []string
+
 	// Archiver settings used by archive lifecycle policies in local mode.
 	ArchiveVaultName string // AWS Glacier vault name (required for archive policies)
 	ArchiveRegion    string // AWS region for the archiver (falls back to BackendRegion)
+
+	// Dedup stores objects through the content-addressable dedup layer in
+	// local mode.
+	Dedup bool
+
+	// SearchIndex is the file the local-mode search index is persisted to.
+	// When set, writes made through the CLI keep the index up to date.
+	SearchIndex string
+
+	// Download settings used by get in local mode.
+	DownloadPartSizeMB  int // Size of each ranged part in MiB (0 = default)
+	DownloadConcurrency int // Parts fetched in parallel (0 = default, 1 = disabled)
+
+	// SkipContentTypeDetection leaves the content type of objects put
+	// without one unset instead of detecting it from the key's extension
+	// or the first bytes of the data.
+	SkipContentTypeDetection bool
+
+	// AtomicPut stages each upload under a hidden key and promotes it only
+	// once it is complete and verified, so readers never observe a partially
+	// written object.
+	AtomicPut bool
 }
 
 // InitConfig initializes the configuration using Viper.
@@ -56,6 +121,7 @@ func InitConfig(cfgFile string) (*viper.Viper, error) {
 	v.SetDefault("backend", "local")
 	v.SetDefault("backend-path", "./storage")
 	v.SetDefault("output-format", "text")
+	v.SetDefault("detect-content-type", true)
 
 	// Set config file search paths
 	if cfgFile != "" {
@@ -101,8 +167,36 @@ func GetConfig(v *viper.Viper) *Config {
 		Server:         v.GetString("server"),
 		ServerProtocol: v.GetString("server-protocol"),
 
+		SigV4AccessKey: v.GetString("sigv4-access-key"),
+		SigV4SecretKey: v.GetString("sigv4-secret-key"),
+		SigV4Region:    v.GetString("sigv4-region"),
+
+		Timeout:            v.GetDuration("timeout"),
+		Retries:            v.GetInt("retries"),
+		RetryBackoff:       v.GetDuration("retry-backoff"),
+		CACert:             v.GetString("ca-cert"),
+		InsecureSkipVerify: v.GetBool("insecure-skip-verify"),
+
+		E2EKeychain:    v.GetString("e2e-keychain"),
+		E2ERecoveryKey: v.GetString("e2e-recovery-key"),
+
+		E2EDeterministicFields: v.GetStringSlice("e2e-deterministic-fields"),
+		E2EAcceptEqualityLeak:  v.GetBool("e2e-accept-equality-leak"),
+
+		SignKey:    v.GetString("sign-key"),
+		VerifyKeys: v.GetStringSlice("verify-keys"),
+
 		ArchiveVaultName: v.GetString("archive-vault-name"),
 		ArchiveRegion:    v.GetString("archive-region"),
+
+		Dedup:       v.GetBool("dedup"),
+		SearchIndex: v.GetString("search-index"),
+
+		DownloadPartSizeMB:  v.GetInt("download-part-size"),
+		DownloadConcurrency: v.GetInt("download-concurrency"),
+
+		SkipContentTypeDetection: !v.GetBool("detect-content-type"),
+		AtomicPut:                v.GetBool("atomic"),
 	}
 }
 
@@ -176,6 +270,42 @@ func (c *Config) GetArchiverSettings() map[string]string {
 	return settings
 }
 
+// GetClientConfig returns the client configuration for remote operations
+// against cfg.Server.
+func (c *Config) GetClientConfig() *client.Config {
+	clientConfig := &client.Config{
+		ServerURL:          c.Server,
+		Protocol:           c.ServerProtocol,
+		CAFile:             c.CACert,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+		Timeout:            c.Timeout,
+	}
+	if c.Retries > 0 {
+		clientConfig.Retry = &client.RetryPolicy{
+			MaxAttempts:    c.Retries + 1,
+			InitialBackoff: c.RetryBackoff,
+		}
+	}
+	if c.SigV4AccessKey != "" {
+		clientConfig.SigV4 = &client.SigV4Config{
+			SigV4Credentials: adapters.SigV4Credentials{
+				AccessKeyID:     c.SigV4AccessKey,
+				SecretAccessKey: c.SigV4SecretKey,
+			},
+			Region: c.SigV4Region,
+		}
+	}
+	return clientConfig
+}
+
+// GetDownloadOptions returns the parallel download options for get.
+func (c *Config) GetDownloadOptions() *download.Options {
+	return &download.Options{
+		PartSize:    int64(c.DownloadPartSizeMB) * 1024 * 1024,
+		Concurrency: c.DownloadConcurrency,
+	}
+}
+
 // DisplayConfig formats and displays the current configuration.
 func DisplayConfig(cfg *Config, format string) string {
 	switch format {
@@ -330,5 +460,23 @@ func ValidateConfig(cfg *Config) error {
 		return ErrUnsupportedOutputFormat
 	}
 
+	if len(cfg.E2EDeterministicFields) > 0 {
+		if cfg.E2EKeychain == "" {
+			return ErrDeterministicRequiresKeychain
+		}
+		if err := cfg.deterministicConfig().Validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
+
+// deterministicConfig returns the deterministic encryption settings of the
+// end-to-end keychain.
+func (c *Config) deterministicConfig() e2ee.DeterministicConfig {
+	return e2ee.DeterministicConfig{
+		Fields:             c.E2EDeterministicFields,
+		AcceptEqualityLeak: c.E2EAcceptEqualityLeak,
+	}
+}
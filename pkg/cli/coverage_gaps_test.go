@@ -18,6 +18,7 @@ import (
 	"errors"
 	"io"
 	"os"
+	"runtime"
 	"strings"
 	"testing"
 	"time"
@@ -143,6 +144,9 @@ func TestNewCommandContext_LocalStorageCreateError(t *testing.T) {
 	if os.Getuid() == 0 {
 		t.Skip("skipping unwritable-path test when running as root")
 	}
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping unwritable-path test on Windows, where file modes do not deny writes")
+	}
 	// Create a temp dir with no write permission so MkdirAll on a subdirectory fails.
 	parent := t.TempDir()
 	if err := os.Chmod(parent, 0500); err != nil {
@@ -195,7 +199,7 @@ func TestPutCommandWithMetadata_ClientError(t *testing.T) {
 		Client: mc,
 		Config: &Config{OutputFormat: "text"},
 	}
-	err := ctx.PutCommandWithMetadata("key", "-", "text/plain", "gzip",
+	err := ctx.PutCommandWithMetadata("key", "-", "text/plain", "gzip", "",
 		map[string]string{"x": "y"})
 	if !errors.Is(err, want) {
 		t.Errorf("expected put error, got %v", err)
@@ -237,7 +241,7 @@ func TestPutCommandWithMetadata_StoragePutError(t *testing.T) {
 	defer func() { os.Stdin = oldStdin }()
 	w.Close() // EOF immediately
 
-	if err := ctx.PutCommandWithMetadata("k", "", "", "", nil); !errors.Is(err, want) {
+	if err := ctx.PutCommandWithMetadata("k", "", "", "", "", nil); !errors.Is(err, want) {
 		t.Errorf("expected put storage error, got %v", err)
 	}
 }
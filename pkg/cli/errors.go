@@ -56,8 +56,120 @@ var (
 	// added in local mode without a configured glacier vault.
 	ErrArchiveVaultRequired = errors.New("archive policies require a glacier vault: set archive-vault-name (and optionally archive-region) in the CLI configuration")
 
+	// ErrInvalidExpiry is returned for an upload expiry that is not an
+	// RFC 3339 time, or that is given both as a time and as a duration.
+	ErrInvalidExpiry = fmt.Errorf("%w: set either --expires-at to an RFC 3339 time or --expires-in to a positive duration", common.ErrInvalidArgument)
+
+	// ErrInvalidMetadataField is returned for a custom metadata argument
+	// that is not of the form name=value.
+	ErrInvalidMetadataField = fmt.Errorf("%w: metadata fields must be given as name=value", common.ErrInvalidArgument)
+
+	// ErrMetadataTargetRequired is returned when metadata is set without a
+	// key or a prefix to apply it to.
+	ErrMetadataTargetRequired = fmt.Errorf("%w: give the key of an object or --prefix", common.ErrInvalidArgument)
+
+	// ErrInvalidListField is returned for a list field that is not one of
+	// ListFields.
+	ErrInvalidListField = fmt.Errorf("%w: unknown list field", common.ErrInvalidArgument)
+
+	// ErrInvalidExportFormat is returned for a listing export format other
+	// than ExportJSONL or ExportCSV.
+	ErrInvalidExportFormat = fmt.Errorf("%w: export format must be jsonl or csv", common.ErrInvalidArgument)
+
+	// ErrSortedExport is returned when a listing exported to a file is to be
+	// sorted. Exports are streamed in key order, a page at a time.
+	ErrSortedExport = fmt.Errorf("%w: --output-file writes objects in key order; drop --sort and --reverse", common.ErrInvalidArgument)
+
+	// ErrStorageClassRequired is returned when a transition lifecycle policy
+	// is added without a target storage class.
+	ErrStorageClassRequired = errors.New("transition policies require a target storage class: set --storage-class")
+
 	// ErrReplicationRequiresServer is returned when a replication command is
 	// run in local mode. It wraps common.ErrReplicationNotSupported so callers
 	// can still match the typed error with errors.Is.
 	ErrReplicationRequiresServer = fmt.Errorf("%w in local CLI mode: connect to an objstore server with --server to manage replication", common.ErrReplicationNotSupported)
+
+	// ErrApplyRequiresServer is returned when a desired state file is
+	// applied in local mode.
+	ErrApplyRequiresServer = errors.New("apply requires an objstore server (--server)")
+
+	// ErrDedupRequiresLocal is returned when a dedup command is run against
+	// a remote server. Dedup statistics are read from the backend directly.
+	ErrDedupRequiresLocal = errors.New("dedup commands are only available in local CLI mode")
+
+	// ErrDeterministicRequiresKeychain is returned when deterministic
+	// fields are configured without an end-to-end keychain.
+	ErrDeterministicRequiresKeychain = errors.New("--e2e-deterministic-fields requires --e2e-keychain")
+
+	// ErrSearchNotSupported is returned when search is run against a server
+	// protocol whose client does not expose the search API.
+	ErrSearchNotSupported = errors.New("search is only supported over the rest protocol")
+
+	// ErrCostNotSupported is returned when a cost report is requested from
+	// a server protocol whose client does not expose the cost API.
+	ErrCostNotSupported = errors.New("cost reports are only supported over the rest protocol")
+
+	// ErrCostConfigRequired is returned when a cost report is requested in
+	// local mode without a cost configuration naming a state file.
+	ErrCostConfigRequired = errors.New("local cost reports require --cost-config naming a configuration with a statePath")
+
+	// ErrAnalyzeNotSupported is returned when an age and access analysis is
+	// requested from a server protocol whose client does not expose the
+	// analyze API.
+	ErrAnalyzeNotSupported = errors.New("analysis is only supported over the rest protocol")
+
+	// ErrRetentionNotSupported is returned when a legal hold or deletion
+	// approval command is run in local mode, or against a server protocol
+	// whose client does not expose the retention API.
+	ErrRetentionNotSupported = errors.New("legal holds and deletion approvals require an objstore server over the rest protocol (--server)")
+
+	// ErrJobsNotSupported is returned when job status is requested in local
+	// mode, or from a server protocol whose client does not expose the jobs
+	// API.
+	ErrJobsNotSupported = errors.New("job status requires an objstore server over the rest protocol (--server)")
+
+	// ErrDiffNotSupported is returned when two objects are compared on a
+	// server protocol whose client does not expose the diff API.
+	ErrDiffNotSupported = errors.New("diffs are only supported over the rest protocol")
+
+	// ErrVersionsNotSupported is returned when an earlier version of an
+	// object is requested in local mode from a backend that keeps none.
+	ErrVersionsNotSupported = errors.New("the backend does not keep earlier versions of objects")
+
+	// ErrTasksNotSupported is returned when a task queue command is run in
+	// local mode, or against a server protocol whose client does not expose
+	// the tasks API.
+	ErrTasksNotSupported = errors.New("the task queue requires an objstore server over the rest protocol (--server)")
+
+	// ErrSharesNotSupported is returned when a share link command is run in
+	// local mode, or against a server protocol whose client does not expose
+	// the share link API.
+	ErrSharesNotSupported = errors.New("share links require an objstore server over the rest protocol (--server)")
+
+	// ErrAliasesNotSupported is returned when an alias command is run
+	// against a server protocol whose client does not expose the alias API.
+	ErrAliasesNotSupported = errors.New("remote aliases require an objstore server over the rest protocol")
+
+	// ErrInvalidSize is returned when a size flag such as --cache-size cannot
+	// be parsed.
+	ErrInvalidSize = errors.New("invalid size")
+
+	// ErrKeychainRequired is returned when a keychain command is run
+	// without --e2e-keychain.
+	ErrKeychainRequired = errors.New("keychain commands require --e2e-keychain naming the keychain file")
+
+	// ErrRecoveryKeyRequired is returned when recover is run without
+	// --e2e-recovery-key.
+	ErrRecoveryKeyRequired = errors.New("recover requires --e2e-recovery-key naming the recovery key file")
+
+	// ErrVerifyKeysRequired is returned when verify is run without
+	// --verify-keys.
+	ErrVerifyKeysRequired = errors.New("verify requires --verify-keys naming the trusted public keys")
+
+	// ErrBackupLocationRequired is returned when admin backup or restore is
+	// given neither an archive file nor --key, or both.
+	ErrBackupLocationRequired = errors.New("admin backup and restore need either an archive file or --key, not both")
+
+	// ErrFileExists is returned instead of overwriting key material.
+	ErrFileExists = errors.New("file already exists")
 )
@@ -44,8 +44,8 @@ func setupCLI(t *testing.T) *testCLI {
 	configDir := t.TempDir()
 	configPath := filepath.Join(configDir, "config.yaml")
 	configContent := `backend: local
-backend_path: ` + backendDir + `
-output_format: json
+backend-path: ` + backendDir + `
+output-format: json
 `
 	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
 		t.Fatalf("Failed to create config file: %v", err)
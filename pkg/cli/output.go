@@ -15,11 +15,18 @@ package cli
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
+	"text/tabwriter"
 	"time"
 
+	"github.com/jeremyhahn/go-objstore/pkg/cli/client"
 	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/e2ee"
+	"github.com/jeremyhahn/go-objstore/pkg/overlay"
 )
 
 // OutputFormat defines the output format type.
@@ -33,10 +40,57 @@ const (
 
 // ObjectInfo holds information about an object for output formatting.
 type ObjectInfo struct {
-	Key          string    `json:"key"`
-	Size         int64     `json:"size"`
-	LastModified time.Time `json:"last_modified"`
-	StorageClass string    `json:"storage_class,omitempty"`
+	Key             string    `json:"key"`
+	Size            int64     `json:"size"`
+	LastModified    time.Time `json:"last_modified"`
+	StorageClass    string    `json:"storage_class,omitempty"`
+	ContentType     string    `json:"content_type,omitempty"`
+	ETag            string    `json:"etag,omitempty"`
+	Checksum        string    `json:"checksum,omitempty"`
+	EncryptionKeyID string    `json:"encryption_key_id,omitempty"`
+}
+
+// Fields of ObjectInfo that can be selected for list output.
+const (
+	FieldKey             = "key"
+	FieldSize            = "size"
+	FieldModified        = "modified"
+	FieldStorageClass    = "storage_class"
+	FieldContentType     = "content_type"
+	FieldETag            = "etag"
+	FieldChecksum        = "checksum"
+	FieldEncryptionKeyID = "encryption_key_id"
+)
+
+// ListFields are the fields FormatListView can show, in their default
+// order.
+var ListFields = []string{
+	FieldKey, FieldSize, FieldModified, FieldStorageClass,
+	FieldContentType, FieldETag, FieldChecksum, FieldEncryptionKeyID,
+}
+
+// LongListFields are the fields of the long list format.
+var LongListFields = []string{
+	FieldStorageClass, FieldSize, FieldModified, FieldChecksum, FieldEncryptionKeyID, FieldKey,
+}
+
+// encryptionKeyFields are the custom metadata fields in which the
+// encryption layers record the ID of the key an object is encrypted with.
+var encryptionKeyFields = []string{
+	"encryption_key_id", "at_rest_encryption_key_id", overlay.MetaKeyID, e2ee.MetaKeyID,
+}
+
+// ListView selects the fields and size format of FormatListView.
+type ListView struct {
+	// Fields are the fields to show, in order. Empty selects
+	// LongListFields in long format and the default layout otherwise.
+	Fields []string
+
+	// Long shows one line per object with LongListFields.
+	Long bool
+
+	// HumanReadable shows sizes in binary units instead of bytes.
+	HumanReadable bool
 }
 
 // OperationResult holds the result of an operation.
@@ -45,6 +99,15 @@ type OperationResult struct {
 	Message string `json:"message,omitempty"`
 	Error   string `json:"error,omitempty"`
 	Data    any    `json:"data,omitempty"`
+
+	// Code is the machine-readable code of a failure (such as "NOT_FOUND")
+	// and RequestID the server's ID of the failed request. Retryable is
+	// set when retrying the operation unchanged may succeed, and Details
+	// holds error-specific fields the server reported.
+	Code      string         `json:"code,omitempty"`
+	RequestID string         `json:"request_id,omitempty"`
+	Retryable bool           `json:"retryable,omitempty"`
+	Details   map[string]any `json:"details,omitempty"`
 }
 
 // FormatOperationResult formats an operation result in the specified format.
@@ -81,15 +144,47 @@ func FormatExistsResult(key string, exists bool, format OutputFormat) string {
 	return FormatOperationResult(result, format)
 }
 
-// FormatError formats an error message in the specified format.
+// FormatError formats an error message in the specified format. An error
+// a server reported (client.APIError) is shown with its code and request
+// ID; other errors wrapping a canonical sentinel (see common.Classify) with
+// the code of their class, so scripts can branch on the code.
 func FormatError(err error, format OutputFormat) string {
 	result := &OperationResult{
 		Success: false,
 		Error:   err.Error(),
 	}
+	var apiErr *client.APIError
+	if errors.As(err, &apiErr) && apiErr.Code != "" {
+		result.Code = apiErr.Code
+		result.RequestID = apiErr.RequestID
+		result.Retryable = apiErr.Retryable
+		result.Details = apiErr.Details
+	} else if code := common.Classify(err); code != common.CodeInternal {
+		result.Code = code.String()
+		result.Retryable = code.Retryable()
+		if apiErr != nil {
+			result.RequestID = apiErr.RequestID
+		}
+	}
 	return FormatOperationResult(result, format)
 }
 
+// errorFields returns the labelled code, request ID and retryability lines
+// of a failed result.
+func errorFields(result *OperationResult) []string {
+	var fields []string
+	if result.Code != "" {
+		fields = append(fields, "Code: "+result.Code)
+	}
+	if result.RequestID != "" {
+		fields = append(fields, "Request ID: "+result.RequestID)
+	}
+	if result.Retryable {
+		fields = append(fields, "Retryable: yes")
+	}
+	return fields
+}
+
 func formatResultText(result *OperationResult) string {
 	if result.Success {
 		if result.Message != "" {
@@ -97,7 +192,11 @@ func formatResultText(result *OperationResult) string {
 		}
 		return "Operation completed successfully\n"
 	}
-	return fmt.Sprintf("Error: %s\n", result.Error)
+	output := fmt.Sprintf("Error: %s\n", result.Error)
+	for _, field := range errorFields(result) {
+		output += field + "\n"
+	}
+	return output
 }
 
 func formatResultTable(result *OperationResult) string {
@@ -122,6 +221,9 @@ func formatResultTable(result *OperationResult) string {
 	output += "├────────────────────────────────────────────────────────┤\n"
 	output += fmt.Sprintf("│ Status: %-47s │\n", "FAILED")
 	lines := wrapText(result.Error, 47)
+	for _, field := range errorFields(result) {
+		lines = append(lines, wrapText(field, 47)...)
+	}
 	for _, line := range lines {
 		output += fmt.Sprintf("│ %-54s │\n", line)
 	}
@@ -178,6 +280,175 @@ func formatListTable(objects []ObjectInfo) string {
 	return output
 }
 
+// ParseListFields parses a comma-separated list of ListFields.
+func ParseListFields(s string) ([]string, error) {
+	var fields []string
+	for field := range strings.SplitSeq(s, ",") {
+		field = strings.ToLower(strings.TrimSpace(field))
+		if field == "" {
+			continue
+		}
+		if !slices.Contains(ListFields, field) {
+			return nil, fmt.Errorf("%w: %q (want %s)", ErrInvalidListField, field, strings.Join(ListFields, ", "))
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// FormatListView formats a list of objects with the fields selected by
+// view. A zero view formats it as FormatListResult does.
+func FormatListView(objects []ObjectInfo, format OutputFormat, view ListView) string {
+	fields := view.Fields
+	if len(fields) == 0 {
+		if !view.Long {
+			return FormatListResult(objects, format)
+		}
+		fields = LongListFields
+	}
+
+	switch format {
+	case FormatJSON:
+		selected := make([]map[string]any, len(objects))
+		for i, obj := range objects {
+			selected[i] = make(map[string]any, len(fields))
+			for _, field := range fields {
+				switch field {
+				case FieldSize:
+					selected[i][field] = obj.Size
+				case FieldModified:
+					selected[i][field] = obj.LastModified
+				default:
+					selected[i][field] = listFieldValue(obj, field, view.HumanReadable)
+				}
+			}
+		}
+		return formatJSON(map[string]any{"count": len(objects), "objects": selected})
+	case FormatTable:
+		if len(objects) == 0 {
+			return "No objects found\n"
+		}
+		return formatListViewTable(objects, fields, view.HumanReadable)
+	default:
+		if len(objects) == 0 {
+			return "No objects found\n"
+		}
+		var output strings.Builder
+		w := tabwriter.NewWriter(&output, 0, 0, 2, ' ', 0)
+		if len(view.Fields) > 0 {
+			header := make([]string, len(fields))
+			for i, field := range fields {
+				header[i] = strings.ToUpper(field)
+			}
+			_, _ = fmt.Fprintln(w, strings.Join(header, "\t"))
+		}
+		for _, obj := range objects {
+			row := make([]string, len(fields))
+			for i, field := range fields {
+				row[i] = listFieldValue(obj, field, view.HumanReadable)
+			}
+			_, _ = fmt.Fprintln(w, strings.Join(row, "\t"))
+		}
+		_ = w.Flush()
+		return output.String()
+	}
+}
+
+// formatListViewTable draws a table of the given fields, truncating each
+// value to maxListColumnWidth.
+func formatListViewTable(objects []ObjectInfo, fields []string, humanReadable bool) string {
+	header := make([]string, len(fields))
+	for i, field := range fields {
+		header[i] = strings.ToUpper(field)
+	}
+	rows := make([][]string, len(objects))
+	for r, obj := range objects {
+		rows[r] = make([]string, len(fields))
+		for i, field := range fields {
+			rows[r][i] = listFieldValue(obj, field, humanReadable)
+		}
+	}
+	return formatBoxTable(header, rows) + fmt.Sprintf("Total: %d object(s)\n", len(objects))
+}
+
+// maxListColumnWidth bounds the width of a column drawn by formatBoxTable.
+const maxListColumnWidth = 64
+
+// formatBoxTable draws rows under header, sizing each column to its widest
+// value up to maxListColumnWidth.
+func formatBoxTable(header []string, rows [][]string) string {
+	widths := make([]int, len(header))
+	for i, title := range header {
+		widths[i] = len(title)
+	}
+	cells := make([][]string, len(rows))
+	for r, row := range rows {
+		cells[r] = make([]string, len(row))
+		for i, value := range row {
+			cells[r][i] = truncate(value, maxListColumnWidth)
+			widths[i] = max(widths[i], len(cells[r][i]))
+		}
+	}
+
+	border := func(left, middle, right string) string {
+		parts := make([]string, len(widths))
+		for i, width := range widths {
+			parts[i] = strings.Repeat("─", width+2)
+		}
+		return left + strings.Join(parts, middle) + right + "\n"
+	}
+	line := func(values []string) string {
+		padded := make([]string, len(values))
+		for i, value := range values {
+			padded[i] = fmt.Sprintf(" %-*s ", widths[i], value)
+		}
+		return "│" + strings.Join(padded, "│") + "│\n"
+	}
+
+	var output strings.Builder
+	output.WriteString(border("┌", "┬", "┐"))
+	output.WriteString(line(header))
+	output.WriteString(border("├", "┼", "┤"))
+	for _, row := range cells {
+		output.WriteString(line(row))
+	}
+	output.WriteString(border("└", "┴", "┘"))
+	return output.String()
+}
+
+// listFieldValue returns the text of a field of obj, or "-" when it is
+// unknown.
+func listFieldValue(obj ObjectInfo, field string, humanReadable bool) string {
+	var value string
+	switch field {
+	case FieldKey:
+		value = obj.Key
+	case FieldSize:
+		if humanReadable {
+			return formatSize(obj.Size)
+		}
+		return strconv.FormatInt(obj.Size, 10)
+	case FieldModified:
+		if !obj.LastModified.IsZero() {
+			value = obj.LastModified.Format(time.RFC3339)
+		}
+	case FieldStorageClass:
+		value = obj.StorageClass
+	case FieldContentType:
+		value = obj.ContentType
+	case FieldETag:
+		value = obj.ETag
+	case FieldChecksum:
+		value = obj.Checksum
+	case FieldEncryptionKeyID:
+		value = obj.EncryptionKeyID
+	}
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
 func formatListJSON(objects []ObjectInfo) string {
 	result := map[string]any{
 		"count":   len(objects),
@@ -194,24 +465,34 @@ func ConvertListResultToObjectInfo(result *common.ListResult) []ObjectInfo {
 
 	objects := make([]ObjectInfo, len(result.Objects))
 	for i, obj := range result.Objects {
-		var size int64
-		var lastModified time.Time
-		var storageClass string
+		objects[i] = ObjectInfo{Key: obj.Key}
+		if obj.Metadata == nil {
+			continue
+		}
 
-		if obj.Metadata != nil {
-			size = obj.Metadata.Size
-			lastModified = obj.Metadata.LastModified
-			// Storage class is typically in custom metadata
-			if obj.Metadata.Custom != nil {
-				storageClass = obj.Metadata.Custom["storage_class"]
+		metadata := obj.Metadata
+		objects[i].Size = metadata.Size
+		objects[i].LastModified = metadata.LastModified
+		objects[i].StorageClass = metadata.StorageClass
+		// Older servers carried the storage class in custom metadata
+		if objects[i].StorageClass == "" && metadata.Custom != nil {
+			objects[i].StorageClass = metadata.Custom["storage_class"]
+		}
+		objects[i].ContentType = metadata.ContentType
+		objects[i].ETag = common.NormalizeETag(metadata.ETag)
+
+		validators := common.Validators(metadata)
+		for _, algorithm := range []string{common.ValidatorSHA256, common.ValidatorMD5} {
+			if sum := validators[algorithm]; sum != "" {
+				objects[i].Checksum = algorithm + ":" + sum
+				break
 			}
 		}
-
-		objects[i] = ObjectInfo{
-			Key:          obj.Key,
-			Size:         size,
-			LastModified: lastModified,
-			StorageClass: storageClass,
+		for _, field := range encryptionKeyFields {
+			if keyID := common.CustomField(metadata.Custom, field); keyID != "" {
+				objects[i].EncryptionKeyID = keyID
+				break
+			}
 		}
 	}
 	return objects
@@ -295,6 +576,9 @@ func formatPoliciesText(policies []common.LifecyclePolicy) string {
 		output += fmt.Sprintf("  Prefix: %s\n", policy.Prefix)
 		output += fmt.Sprintf("  Retention: %s\n", formatDuration(policy.Retention))
 		output += fmt.Sprintf("  Action: %s\n", policy.Action)
+		if policy.StorageClass != "" {
+			output += fmt.Sprintf("  Storage Class: %s\n", policy.StorageClass)
+		}
 		output += "\n"
 	}
 	return output
@@ -326,19 +610,21 @@ func formatPoliciesTable(policies []common.LifecyclePolicy) string {
 func formatPoliciesJSON(policies []common.LifecyclePolicy) string {
 	// Convert policies to a JSON-friendly format
 	type policyJSON struct {
-		ID        string `json:"id"`
-		Prefix    string `json:"prefix"`
-		Retention string `json:"retention"`
-		Action    string `json:"action"`
+		ID           string `json:"id"`
+		Prefix       string `json:"prefix"`
+		Retention    string `json:"retention"`
+		Action       string `json:"action"`
+		StorageClass string `json:"storage_class,omitempty"`
 	}
 
 	jsonPolicies := make([]policyJSON, len(policies))
 	for i, policy := range policies {
 		jsonPolicies[i] = policyJSON{
-			ID:        policy.ID,
-			Prefix:    policy.Prefix,
-			Retention: formatDuration(policy.Retention),
-			Action:    policy.Action,
+			ID:           policy.ID,
+			Prefix:       policy.Prefix,
+			Retention:    formatDuration(policy.Retention),
+			Action:       policy.Action,
+			StorageClass: policy.StorageClass,
 		}
 	}
 
@@ -474,3 +760,56 @@ func formatHealthTable(health map[string]any) string {
 	output += "└──────────────────────┴────────────────────────────────────────┘\n"
 	return output
 }
+
+// FormatBrowseResult formats the content of a virtual directory. Folders
+// and objects are named relative to the browsed prefix; folders show their
+// object count and size when totals were requested and "-" otherwise.
+func FormatBrowseResult(result *common.BrowseResult, format OutputFormat, humanReadable bool) string {
+	if format == FormatJSON {
+		return formatJSON(result)
+	}
+	if len(result.Folders) == 0 && len(result.Objects) == 0 {
+		return "No objects found\n"
+	}
+
+	size := func(n int64) string {
+		if humanReadable {
+			return formatSize(n)
+		}
+		return strconv.FormatInt(n, 10)
+	}
+	header := []string{"SIZE", "MODIFIED", "OBJECTS", "NAME"}
+	rows := make([][]string, 0, len(result.Folders)+len(result.Objects))
+	for _, folder := range result.Folders {
+		row := []string{"-", "-", "-", strings.TrimPrefix(folder.Prefix, result.Prefix)}
+		if folder.Objects > 0 {
+			row[0] = size(folder.Bytes)
+			row[2] = strconv.FormatInt(folder.Objects, 10)
+		}
+		rows = append(rows, row)
+	}
+	for _, obj := range result.Objects {
+		row := []string{"0", "-", "1", strings.TrimPrefix(obj.Key, result.Prefix)}
+		if obj.Metadata != nil {
+			row[0] = size(obj.Metadata.Size)
+			if !obj.Metadata.LastModified.IsZero() {
+				row[1] = obj.Metadata.LastModified.Format(time.RFC3339)
+			}
+		}
+		rows = append(rows, row)
+	}
+	footer := fmt.Sprintf("Total: %d folder(s), %d object(s), %s\n",
+		len(result.Folders), result.TotalObjects, size(result.TotalBytes))
+
+	if format == FormatTable {
+		return formatBoxTable(header, rows) + footer
+	}
+	var output strings.Builder
+	w := tabwriter.NewWriter(&output, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, row := range rows {
+		_, _ = fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	_ = w.Flush()
+	return output.String() + footer
+}
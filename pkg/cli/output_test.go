@@ -14,10 +14,14 @@
 package cli
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/jeremyhahn/go-objstore/pkg/cli/client"
 	"github.com/jeremyhahn/go-objstore/pkg/common"
 	"github.com/jeremyhahn/go-objstore/pkg/version"
 )
@@ -279,6 +283,40 @@ func TestFormatError(t *testing.T) {
 			t.Error("Expected error message in table")
 		}
 	})
+
+	t.Run("server error code", func(t *testing.T) {
+		err := fmt.Errorf("delete: %w", &client.APIError{
+			Status:    503,
+			Code:      "UNAVAILABLE",
+			Message:   "service unavailable",
+			RequestID: "req-1",
+			Retryable: true,
+		})
+		var result OperationResult
+		if jsonErr := json.Unmarshal([]byte(FormatError(err, FormatJSON)), &result); jsonErr != nil {
+			t.Fatal(jsonErr)
+		}
+		if result.Code != "UNAVAILABLE" || result.RequestID != "req-1" || !result.Retryable {
+			t.Errorf("FormatError() JSON = %+v", result)
+		}
+		text := FormatError(err, FormatText)
+		for _, want := range []string{"Code: UNAVAILABLE", "Request ID: req-1", "Retryable: yes"} {
+			if !strings.Contains(text, want) {
+				t.Errorf("FormatError() text = %q, want it to contain %q", text, want)
+			}
+		}
+	})
+
+	t.Run("classified error code", func(t *testing.T) {
+		err := fmt.Errorf("get: %w", common.ErrKeyNotFound)
+		output := FormatError(err, FormatJSON)
+		if !strings.Contains(output, `"code": "NOT_FOUND"`) || strings.Contains(output, "retryable") {
+			t.Errorf("FormatError() = %s, want a non-retryable NOT_FOUND code", output)
+		}
+		if output := FormatError(&testError{msg: "test error"}, FormatJSON); strings.Contains(output, `"code"`) {
+			t.Errorf("FormatError() of an unclassified error = %s, want no code", output)
+		}
+	})
 }
 
 func TestConvertListResultToObjectInfo(t *testing.T) {
@@ -318,6 +356,7 @@ func TestConvertListResultToObjectInfo(t *testing.T) {
 					Metadata: &common.Metadata{
 						Size:         2048,
 						LastModified: now.Add(time.Hour),
+						StorageClass: "GLACIER_IR",
 					},
 				},
 			},
@@ -338,9 +377,90 @@ func TestConvertListResultToObjectInfo(t *testing.T) {
 		if objects[1].Key != "test/file2.txt" {
 			t.Error("Incorrect key for second object")
 		}
+		if objects[1].StorageClass != "GLACIER_IR" {
+			t.Error("Incorrect storage class for second object")
+		}
+	})
+
+	t.Run("checksum and key ID", func(t *testing.T) {
+		sum := strings.Repeat("ab", 32)
+		objects := ConvertListResultToObjectInfo(&common.ListResult{
+			Objects: []*common.ObjectInfo{{
+				Key: "enc.bin",
+				Metadata: &common.Metadata{
+					Size:        7,
+					ContentType: "application/octet-stream",
+					ETag:        `"etag-1"`,
+					Custom: map[string]string{
+						common.MetaChecksumSHA256:   sum,
+						common.MetaChecksumSize:     "7",
+						"At_Rest_Encryption_Key_Id": "key-2025",
+					},
+				},
+			}},
+		})
+		obj := objects[0]
+		if obj.Checksum != "sha256:"+sum || obj.EncryptionKeyID != "key-2025" ||
+			obj.ETag != "etag-1" || obj.ContentType != "application/octet-stream" {
+			t.Errorf("ConvertListResultToObjectInfo() = %+v", obj)
+		}
 	})
 }
 
+func TestParseListFields(t *testing.T) {
+	fields, err := ParseListFields(" Key, size,,checksum ")
+	if err != nil || strings.Join(fields, ",") != "key,size,checksum" {
+		t.Errorf("ParseListFields() = %v, %v", fields, err)
+	}
+	if _, err := ParseListFields("key,owner"); !errors.Is(err, ErrInvalidListField) {
+		t.Errorf("ParseListFields(owner) = %v, want ErrInvalidListField", err)
+	}
+}
+
+func TestFormatListView(t *testing.T) {
+	modified := time.Date(2025, 11, 5, 10, 0, 0, 0, time.UTC)
+	objects := []ObjectInfo{
+		{Key: "logs/big.log", Size: 3 * 1024 * 1024, LastModified: modified, StorageClass: "COLD", Checksum: "sha256:abc"},
+		{Key: "logs/small.log", Size: 10, LastModified: modified},
+	}
+
+	if got := FormatListView(objects, FormatText, ListView{}); got != FormatListResult(objects, FormatText) {
+		t.Errorf("FormatListView(zero view) differs from FormatListResult:\n%s", got)
+	}
+
+	long := FormatListView(objects, FormatText, ListView{Long: true})
+	want := "COLD  3145728  2025-11-05T10:00:00Z  sha256:abc  -  logs/big.log\n" +
+		"-     10       2025-11-05T10:00:00Z  -           -  logs/small.log\n"
+	if long != want {
+		t.Errorf("FormatListView(long) =\n%s\nwant\n%s", long, want)
+	}
+
+	human := FormatListView(objects, FormatText, ListView{Fields: []string{FieldKey, FieldSize}, HumanReadable: true})
+	if !strings.HasPrefix(human, "KEY") || !strings.Contains(human, "3.0 MiB") || !strings.Contains(human, "10 B") {
+		t.Errorf("FormatListView(fields, human) =\n%s", human)
+	}
+
+	table := FormatListView(objects, FormatTable, ListView{Fields: []string{FieldKey, FieldStorageClass}})
+	if !strings.Contains(table, "│ KEY            │ STORAGE_CLASS │") || !strings.Contains(table, "│ logs/big.log   │ COLD          │") {
+		t.Errorf("FormatListView(table) =\n%s", table)
+	}
+
+	var parsed struct {
+		Count   int              `json:"count"`
+		Objects []map[string]any `json:"objects"`
+	}
+	if err := json.Unmarshal([]byte(FormatListView(objects, FormatJSON, ListView{Fields: []string{FieldKey, FieldSize}})), &parsed); err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Count != 2 || len(parsed.Objects[0]) != 2 || parsed.Objects[0]["size"] != float64(3*1024*1024) {
+		t.Errorf("FormatListView(json) = %+v", parsed)
+	}
+
+	if got := FormatListView(nil, FormatTable, ListView{Long: true}); got != "No objects found\n" {
+		t.Errorf("FormatListView(empty) = %q", got)
+	}
+}
+
 func TestFormatSize(t *testing.T) {
 	tests := []struct {
 		size     int64
@@ -0,0 +1,165 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// Append adds data to the end of the object stored under key, creating it if
+// needed. Backends implementing Appender append natively; all others are
+// handled by EmulateAppend.
+func Append(ctx context.Context, storage Storage, key string, data io.Reader) error {
+	if appender, ok := storage.(Appender); ok {
+		return appender.Append(ctx, key, data)
+	}
+	return EmulateAppend(ctx, storage, key, data)
+}
+
+// Compose writes the concatenation of srcKeys to destKey. Backends
+// implementing Composer concatenate server-side; all others are handled by
+// EmulateCompose.
+func Compose(ctx context.Context, storage Storage, destKey string, srcKeys ...string) error {
+	if composer, ok := storage.(Composer); ok {
+		return composer.Compose(ctx, destKey, srcKeys...)
+	}
+	return EmulateCompose(ctx, storage, destKey, srcKeys...)
+}
+
+// EmulateAppend appends by rewriting the object: the current contents are
+// streamed back followed by data. The object keeps its content type,
+// encoding, storage class and custom metadata. The rewrite is not atomic
+// with respect to concurrent writers of the same key.
+func EmulateAppend(ctx context.Context, storage Storage, key string, data io.Reader) error {
+	if err := ValidateKey(key); err != nil {
+		return err
+	}
+	exists, err := storage.Exists(ctx, key)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return storage.PutWithMetadata(ctx, key, data, nil)
+	}
+
+	metadata, err := storage.GetMetadata(ctx, key)
+	if err != nil {
+		return err
+	}
+	current, err := storage.GetWithContext(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = current.Close() }()
+	return storage.PutWithMetadata(ctx, key, io.MultiReader(current, data), carryMetadata(metadata))
+}
+
+// EmulateCompose concatenates srcKeys by streaming each source in turn into
+// a new upload of destKey. The result takes its content type, encoding,
+// storage class and custom metadata from the first source.
+func EmulateCompose(ctx context.Context, storage Storage, destKey string, srcKeys ...string) error {
+	if err := ValidateComposeKeys(destKey, srcKeys); err != nil {
+		return err
+	}
+	metadata, err := storage.GetMetadata(ctx, srcKeys[0])
+	if err != nil {
+		return err
+	}
+
+	r := &concatReader{ctx: ctx, storage: storage, keys: srcKeys}
+	defer func() { _ = r.Close() }()
+	return storage.PutWithMetadata(ctx, destKey, r, carryMetadata(metadata))
+}
+
+// ValidateComposeKeys checks the destination and source keys of a Compose
+// call. At least one source is required.
+func ValidateComposeKeys(destKey string, srcKeys []string) error {
+	if len(srcKeys) == 0 {
+		return fmt.Errorf("%w: compose requires at least one source key", ErrInvalidArgument)
+	}
+	if err := ValidateKey(destKey); err != nil {
+		return err
+	}
+	for _, key := range srcKeys {
+		if err := ValidateKey(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// carryMetadata returns the user-controlled fields of metadata for a
+// rewrite. Size, ETag and modification time are recomputed by the backend.
+func carryMetadata(metadata *Metadata) *Metadata {
+	if metadata == nil {
+		return nil
+	}
+	carried := &Metadata{
+		ContentType:     metadata.ContentType,
+		ContentEncoding: metadata.ContentEncoding,
+		StorageClass:    metadata.StorageClass,
+	}
+	if len(metadata.Custom) > 0 {
+		carried.Custom = make(map[string]string, len(metadata.Custom))
+		for k, v := range metadata.Custom {
+			carried.Custom[k] = v
+		}
+	}
+	return carried
+}
+
+// concatReader reads a list of objects back to back, opening each one only
+// when the previous one is exhausted.
+type concatReader struct {
+	ctx     context.Context
+	storage Storage
+	keys    []string
+	current io.ReadCloser
+}
+
+func (r *concatReader) Read(p []byte) (int, error) {
+	for {
+		if r.current == nil {
+			if len(r.keys) == 0 {
+				return 0, io.EOF
+			}
+			rc, err := r.storage.GetWithContext(r.ctx, r.keys[0])
+			if err != nil {
+				return 0, fmt.Errorf("failed to read compose source %s: %w", r.keys[0], err)
+			}
+			r.current, r.keys = rc, r.keys[1:]
+		}
+		n, err := r.current.Read(p)
+		if err == io.EOF {
+			_ = r.current.Close()
+			r.current = nil
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+		return n, err
+	}
+}
+
+func (r *concatReader) Close() error {
+	if r.current == nil {
+		return nil
+	}
+	err := r.current.Close()
+	r.current = nil
+	return err
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package common_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+func readObject(t *testing.T, storage common.Storage, key string) string {
+	t.Helper()
+	rc, err := storage.Get(key)
+	if err != nil {
+		t.Fatalf("Get(%s) error = %v", key, err)
+	}
+	defer func() { _ = rc.Close() }()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatalf("read %s: %v", key, err)
+	}
+	return string(data)
+}
+
+func TestEmulateAppend(t *testing.T) {
+	ctx := context.Background()
+	storage := memory.New()
+
+	if err := common.EmulateAppend(ctx, storage, "app.log", strings.NewReader("one\n")); err != nil {
+		t.Fatalf("EmulateAppend() on missing key error = %v", err)
+	}
+	metadata := &common.Metadata{ContentType: "text/plain", Custom: map[string]string{"source": "app"}}
+	if err := storage.UpdateMetadata(ctx, "app.log", metadata); err != nil {
+		t.Fatalf("UpdateMetadata() error = %v", err)
+	}
+	if err := common.EmulateAppend(ctx, storage, "app.log", strings.NewReader("two\n")); err != nil {
+		t.Fatalf("EmulateAppend() error = %v", err)
+	}
+
+	if got := readObject(t, storage, "app.log"); got != "one\ntwo\n" {
+		t.Errorf("content = %q, want %q", got, "one\ntwo\n")
+	}
+	got, err := storage.GetMetadata(ctx, "app.log")
+	if err != nil {
+		t.Fatalf("GetMetadata() error = %v", err)
+	}
+	if got.ContentType != "text/plain" || got.Custom["source"] != "app" || got.Size != 8 {
+		t.Errorf("metadata = %+v, want content type, custom metadata and size kept", got)
+	}
+
+	if err := common.EmulateAppend(ctx, storage, "../escape", strings.NewReader("x")); err == nil {
+		t.Error("EmulateAppend() with invalid key should fail")
+	}
+}
+
+func TestEmulateCompose(t *testing.T) {
+	ctx := context.Background()
+	storage := memory.New()
+	_ = storage.PutWithMetadata(ctx, "part-1", strings.NewReader("aaa"), &common.Metadata{ContentType: "text/csv"})
+	_ = storage.Put("part-2", strings.NewReader("bbb"))
+	_ = storage.Put("part-3", strings.NewReader("ccc"))
+
+	if err := common.EmulateCompose(ctx, storage, "all.csv", "part-1", "part-2", "part-3"); err != nil {
+		t.Fatalf("EmulateCompose() error = %v", err)
+	}
+	if got := readObject(t, storage, "all.csv"); got != "aaabbbccc" {
+		t.Errorf("content = %q, want %q", got, "aaabbbccc")
+	}
+	if md, _ := storage.GetMetadata(ctx, "all.csv"); md.ContentType != "text/csv" {
+		t.Errorf("content type = %q, want first source's text/csv", md.ContentType)
+	}
+
+	// The destination may be one of the sources.
+	if err := common.EmulateCompose(ctx, storage, "part-1", "part-1", "part-2"); err != nil {
+		t.Fatalf("EmulateCompose() into a source error = %v", err)
+	}
+	if got := readObject(t, storage, "part-1"); got != "aaabbb" {
+		t.Errorf("content = %q, want %q", got, "aaabbb")
+	}
+
+	err := common.EmulateCompose(ctx, storage, "out", "part-2", "missing")
+	if !errors.Is(err, common.ErrKeyNotFound) {
+		t.Errorf("EmulateCompose() with missing source error = %v, want ErrKeyNotFound", err)
+	}
+	if exists, _ := storage.Exists(ctx, "out"); exists {
+		t.Error("failed compose should not create the destination")
+	}
+
+	if err := common.EmulateCompose(ctx, storage, "out"); !errors.Is(err, common.ErrInvalidArgument) {
+		t.Errorf("EmulateCompose() without sources error = %v, want ErrInvalidArgument", err)
+	}
+}
+
+// plainStorage hides the native Append and Compose of the wrapped backend.
+type plainStorage struct {
+	common.Storage
+}
+
+func TestAppendAndComposeFallback(t *testing.T) {
+	ctx := context.Background()
+	native := memory.New()
+	storage := plainStorage{native}
+	if _, ok := common.Storage(storage).(common.Appender); ok {
+		t.Fatal("plainStorage should not implement Appender")
+	}
+
+	for _, s := range []common.Storage{native, storage} {
+		_ = s.Put("a", strings.NewReader("1"))
+		if err := common.Append(ctx, s, "a", strings.NewReader("2")); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+		if err := common.Compose(ctx, s, "b", "a", "a"); err != nil {
+			t.Fatalf("Compose() error = %v", err)
+		}
+		if got := readObject(t, s, "b"); got != "1212" {
+			t.Errorf("content = %q, want %q", got, "1212")
+		}
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package common
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// GetAsOf reads key as it was at the given time. It returns
+// ErrVersionsNotSupported unless storage implements AsOfReader. Wrappers
+// implement AsOfReader by applying their read path to GetAsOf on the storage
+// they wrap, so a wrapper that cannot do so makes the whole chain
+// unversioned rather than being skipped.
+func GetAsOf(ctx context.Context, storage Storage, key string, at time.Time) (io.ReadCloser, *Metadata, error) {
+	reader, ok := storage.(AsOfReader)
+	if !ok {
+		return nil, nil, ErrVersionsNotSupported
+	}
+	return reader.GetAsOf(ctx, key, at)
+}
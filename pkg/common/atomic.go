@@ -0,0 +1,181 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package common
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// StagingPrefix is the key prefix PutAtomic stages uploads under before
+// promoting them to their final key. Storage wrapped by NewStagingStorage
+// reserves it.
+const StagingPrefix = ".staging/"
+
+// stagedIDLength is the length of the hex ID PutAtomic names staged
+// objects with.
+const stagedIDLength = 32
+
+// stagingAccess is the context key that lets operations through the
+// reservation of NewStagingStorage.
+type stagingAccess struct{}
+
+// NewStagingStorage returns underlying wrapped so that the staging namespace
+// is reserved: clients can neither write nor list objects under
+// StagingPrefix. PutAtomic, and the upload reaper cleaning up after it,
+// reach the namespace with contexts returned by WithStagingAccess.
+func NewStagingStorage(underlying Storage) *ReservedStorage {
+	return &ReservedStorage{Storage: underlying, prefix: StagingPrefix, access: stagingAccess{}}
+}
+
+// WithStagingAccess returns ctx marked so that operations made with it pass
+// the reservation of NewStagingStorage.
+func WithStagingAccess(ctx context.Context) context.Context {
+	return context.WithValue(ctx, stagingAccess{}, true)
+}
+
+// IsStagedKey reports whether key names an object staged by PutAtomic.
+func IsStagedKey(key string) bool {
+	id, ok := strings.CutPrefix(key, StagingPrefix)
+	if !ok || len(id) != stagedIDLength || strings.ToLower(id) != id {
+		return false
+	}
+	_, err := hex.DecodeString(id)
+	return err == nil
+}
+
+// ErrStagedContentMismatch is returned by PutAtomic when the staged object
+// does not match the uploaded data, or the data does not match the size or
+// SHA-256 digest declared in its metadata. It wraps ErrPreconditionFailed.
+var ErrStagedContentMismatch = fmt.Errorf("%w: staged content does not match the upload", ErrPreconditionFailed)
+
+// PutAtomic stores data under key so that readers never observe a partial
+// object, even on backends without atomic rename. The data is first
+// uploaded to a hidden key under StagingPrefix and read back; only when the
+// staged object matches what was sent, and the size and SHA-256 digest
+// declared in metadata (metadata.Size and the MetaChecksumSHA256 custom
+// field) if any, is it promoted to key with Compose, which copies
+// server-side on backends implementing Composer. The staged object is
+// removed whether or not the upload succeeds. A failed PutAtomic leaves any
+// existing object at key untouched.
+func PutAtomic(ctx context.Context, storage Storage, key string, data io.Reader, metadata *Metadata) error {
+	if err := ValidateKey(key); err != nil {
+		return err
+	}
+	if IsReserved(key, StagingPrefix) {
+		return fmt.Errorf("%w: %s is in the staging namespace", ErrInvalidArgument, key)
+	}
+	id, err := stagedID()
+	if err != nil {
+		return err
+	}
+	return putStaged(WithStagingAccess(ctx), storage, key, StagingPrefix+id, data, metadata)
+}
+
+// PutAtomicBeside is PutAtomic for storage whose staging namespace is out of
+// reach, such as a remote server that reserves it. The upload is staged
+// next to key instead, where listings show it until it is promoted or
+// removed, and where the upload reaper does not look for it.
+func PutAtomicBeside(ctx context.Context, storage Storage, key string, data io.Reader, metadata *Metadata) error {
+	if err := ValidateKey(key); err != nil {
+		return err
+	}
+	id, err := stagedID()
+	if err != nil {
+		return err
+	}
+	return putStaged(ctx, storage, key, key+".staging-"+id, data, metadata)
+}
+
+// stagedID returns a random ID to name a staged object with.
+func stagedID() (string, error) {
+	id := make([]byte, stagedIDLength/2)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
+
+// putStaged uploads data to staging, checks it and promotes it to key.
+func putStaged(ctx context.Context, storage Storage, key, staging string, data io.Reader, metadata *Metadata) error {
+	defer func() {
+		// The staged object must go even if ctx was canceled mid-upload.
+		_ = storage.DeleteWithContext(context.WithoutCancel(ctx), staging)
+	}()
+
+	// Backends may fill in metadata during the upload, so the declared
+	// size and digest are taken first.
+	var wantSize int64
+	var wantSum string
+	if metadata != nil {
+		wantSize = metadata.Size
+		wantSum = strings.ToLower(CustomField(metadata.Custom, MetaChecksumSHA256))
+	}
+
+	hash := sha256.New()
+	counter := &countingReader{r: io.TeeReader(data, hash)}
+	if err := storage.PutWithMetadata(ctx, staging, counter, metadata); err != nil {
+		return err
+	}
+	sum := hex.EncodeToString(hash.Sum(nil))
+
+	if wantSize > 0 && wantSize != counter.n {
+		return fmt.Errorf("%w: uploaded %d bytes, expected %d", ErrStagedContentMismatch, counter.n, wantSize)
+	}
+	if wantSum != "" && wantSum != sum {
+		return fmt.Errorf("%w: uploaded sha256 %s, expected %s", ErrStagedContentMismatch, sum, wantSum)
+	}
+	if err := verifyStaged(ctx, storage, staging, sum, counter.n); err != nil {
+		return err
+	}
+
+	return Compose(ctx, storage, key, staging)
+}
+
+// verifyStaged reads back the staged object and checks it against the
+// digest and size of the data sent.
+func verifyStaged(ctx context.Context, storage Storage, staging, sum string, size int64) error {
+	rc, err := storage.GetWithContext(ctx, staging)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rc.Close() }()
+
+	hash := sha256.New()
+	n, err := io.Copy(hash, rc)
+	if err != nil {
+		return err
+	}
+	if n != size || hex.EncodeToString(hash.Sum(nil)) != sum {
+		return fmt.Errorf("%w: staged object is %d bytes, uploaded %d", ErrStagedContentMismatch, n, size)
+	}
+	return nil
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
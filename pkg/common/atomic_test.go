@@ -0,0 +1,116 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package common_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+// brokenReader returns data and then fails, like a dropped connection.
+type brokenReader struct {
+	data io.Reader
+}
+
+func (r *brokenReader) Read(p []byte) (int, error) {
+	n, err := r.data.Read(p)
+	if err == io.EOF {
+		return n, errors.New("connection reset")
+	}
+	return n, err
+}
+
+func assertNoStaging(t *testing.T, storage common.Storage) {
+	t.Helper()
+	keys, err := storage.ListWithContext(context.Background(), common.StagingPrefix)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(keys) != 0 {
+		t.Errorf("staged objects left behind: %v", keys)
+	}
+}
+
+func TestPutAtomic(t *testing.T) {
+	ctx := context.Background()
+	storage := memory.New()
+
+	sum := sha256.Sum256([]byte("release"))
+	metadata := &common.Metadata{
+		ContentType: "application/gzip",
+		Size:        7,
+		Custom:      map[string]string{common.MetaChecksumSHA256: hex.EncodeToString(sum[:])},
+	}
+	if err := common.PutAtomic(ctx, storage, "app.tar.gz", strings.NewReader("release"), metadata); err != nil {
+		t.Fatalf("PutAtomic() error = %v", err)
+	}
+	if got := readObject(t, storage, "app.tar.gz"); got != "release" {
+		t.Errorf("content = %q, want %q", got, "release")
+	}
+	got, err := storage.GetMetadata(ctx, "app.tar.gz")
+	if err != nil {
+		t.Fatalf("GetMetadata() error = %v", err)
+	}
+	if got.ContentType != "application/gzip" {
+		t.Errorf("ContentType = %q, want application/gzip", got.ContentType)
+	}
+	assertNoStaging(t, storage)
+}
+
+func TestPutAtomicFailureKeepsExistingObject(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		data     io.Reader
+		metadata *common.Metadata
+		want     error
+	}{
+		{"checksum mismatch", strings.NewReader("new"),
+			&common.Metadata{Custom: map[string]string{common.MetaChecksumSHA256: strings.Repeat("0", 64)}}, common.ErrPreconditionFailed},
+		{"short upload", strings.NewReader("new"), &common.Metadata{Size: 10}, common.ErrStagedContentMismatch},
+		{"broken upload", &brokenReader{data: strings.NewReader("new")}, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := memory.New()
+			if err := storage.Put("app", strings.NewReader("old")); err != nil {
+				t.Fatal(err)
+			}
+			err := common.PutAtomic(ctx, storage, "app", tt.data, tt.metadata)
+			if err == nil || (tt.want != nil && !errors.Is(err, tt.want)) {
+				t.Errorf("PutAtomic() error = %v, want %v", err, tt.want)
+			}
+			if got := readObject(t, storage, "app"); got != "old" {
+				t.Errorf("content = %q, want old", got)
+			}
+			assertNoStaging(t, storage)
+		})
+	}
+}
+
+func TestPutAtomicRejectsStagingKeys(t *testing.T) {
+	err := common.PutAtomic(context.Background(), memory.New(), common.StagingPrefix+"x", strings.NewReader("x"), nil)
+	if !errors.Is(err, common.ErrInvalidArgument) {
+		t.Errorf("PutAtomic() error = %v, want ErrInvalidArgument", err)
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package common
+
+// BatchOpType identifies the change a BatchOp makes.
+type BatchOpType int
+
+const (
+	// BatchPut stores Data with Metadata under Key.
+	BatchPut BatchOpType = iota
+
+	// BatchDelete removes Key, which must exist.
+	BatchDelete
+
+	// BatchUpdateMetadata replaces the metadata of Key, which must exist.
+	BatchUpdateMetadata
+)
+
+// String returns the name of the operation type.
+func (t BatchOpType) String() string {
+	switch t {
+	case BatchPut:
+		return "put"
+	case BatchDelete:
+		return "delete"
+	case BatchUpdateMetadata:
+		return "update metadata"
+	}
+	return "unknown"
+}
+
+// BatchOp is one change in a batch applied by a BatchWriter.
+type BatchOp struct {
+	Type     BatchOpType
+	Key      string
+	Data     []byte
+	Metadata *Metadata
+}
+
+// BatchPrecondition requires an object to be in a given state before a
+// batch is applied.
+type BatchPrecondition struct {
+	Key string
+
+	// ETag is the ETag the object must have. Empty requires the object not
+	// to exist.
+	ETag string
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package common
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+// DefaultBrowseDelimiter separates the levels of virtual directories when
+// BrowseOptions.Delimiter is empty.
+const DefaultBrowseDelimiter = "/"
+
+// BrowseOptions controls Browse.
+type BrowseOptions struct {
+	// Delimiter separates the levels of virtual directories. Empty means
+	// DefaultBrowseDelimiter.
+	Delimiter string
+
+	// Totals counts the objects and bytes under each folder. This lists
+	// every object under the prefix instead of its immediate children only.
+	Totals bool
+}
+
+// Folder is a virtual directory: the keys that share a prefix ending in the
+// delimiter.
+type Folder struct {
+	// Prefix is the folder's full prefix, ending in the delimiter.
+	Prefix string `json:"prefix"`
+
+	// Objects and Bytes are the number and total size of the objects
+	// under Prefix at any depth. They are only set when totals were
+	// requested.
+	Objects int64 `json:"objects"`
+	Bytes   int64 `json:"bytes"`
+}
+
+// BrowseResult is the content of a virtual directory.
+type BrowseResult struct {
+	// Prefix is the browsed prefix.
+	Prefix string `json:"prefix"`
+
+	// Folders are the virtual directories directly under Prefix, sorted by
+	// prefix.
+	Folders []Folder `json:"folders"`
+
+	// Objects are the objects directly under Prefix, sorted by key.
+	Objects []*ObjectInfo `json:"objects"`
+
+	// TotalObjects and TotalBytes count the objects directly under Prefix
+	// and, when totals were requested, those in its folders.
+	TotalObjects int64 `json:"total_objects"`
+	TotalBytes   int64 `json:"total_bytes"`
+}
+
+// Browse returns the immediate children of prefix: the objects directly
+// under it and the folders one delimiter deeper. Without totals it pages
+// through a delimiter listing, so its cost is that of the children rather
+// than of everything below them; backends that ignore the delimiter are
+// grouped client-side. A prefix that does not end in the delimiter is
+// browsed as given, so "logs/2025" lists "logs/2025/" and "logs/2025.tar".
+func Browse(ctx context.Context, storage Storage, prefix string, opts *BrowseOptions) (*BrowseResult, error) {
+	if opts == nil {
+		opts = &BrowseOptions{}
+	}
+	delimiter := opts.Delimiter
+	if delimiter == "" {
+		delimiter = DefaultBrowseDelimiter
+	}
+
+	result := &BrowseResult{Prefix: prefix, Folders: []Folder{}, Objects: []*ObjectInfo{}}
+	folders := make(map[string]*Folder)
+	folder := func(p string) *Folder {
+		f, ok := folders[p]
+		if !ok {
+			f = &Folder{Prefix: p}
+			folders[p] = f
+		}
+		return f
+	}
+
+	listOpts := &ListOptions{Prefix: prefix}
+	if !opts.Totals {
+		listOpts.Delimiter = delimiter
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		page, err := storage.ListWithOptions(ctx, listOpts)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range page.CommonPrefixes {
+			folder(p)
+		}
+		for _, obj := range page.Objects {
+			if obj == nil || !strings.HasPrefix(obj.Key, prefix) {
+				continue
+			}
+			var size int64
+			if obj.Metadata != nil {
+				size = obj.Metadata.Size
+			}
+			rest := obj.Key[len(prefix):]
+			if i := strings.Index(rest, delimiter); i >= 0 {
+				f := folder(prefix + rest[:i+len(delimiter)])
+				if opts.Totals {
+					f.Objects++
+					f.Bytes += size
+					result.TotalObjects++
+					result.TotalBytes += size
+				}
+				continue
+			}
+			result.Objects = append(result.Objects, obj)
+			result.TotalObjects++
+			result.TotalBytes += size
+		}
+		if page.NextToken == "" || page.NextToken == listOpts.ContinueFrom {
+			break
+		}
+		listOpts.ContinueFrom = page.NextToken
+	}
+
+	for _, f := range folders {
+		result.Folders = append(result.Folders, *f)
+	}
+	sort.Slice(result.Folders, func(i, j int) bool { return result.Folders[i].Prefix < result.Folders[j].Prefix })
+	sort.Slice(result.Objects, func(i, j int) bool { return result.Objects[i].Key < result.Objects[j].Key })
+	return result, nil
+}
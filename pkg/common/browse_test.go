@@ -0,0 +1,74 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package common_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+func TestBrowse(t *testing.T) {
+	ctx := context.Background()
+	storage := memory.New()
+	for _, key := range []string{"logs/a.log", "logs/2025/b.log", "logs/2025/01/c.log", "logs/2024/d.log", "data/e.csv", "top"} {
+		if err := storage.PutWithContext(ctx, key, strings.NewReader(key)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := common.Browse(ctx, storage, "logs/", nil)
+	if err != nil {
+		t.Fatalf("Browse() error = %v", err)
+	}
+	if len(result.Folders) != 2 || result.Folders[0].Prefix != "logs/2024/" || result.Folders[1].Prefix != "logs/2025/" {
+		t.Fatalf("Browse() folders = %+v, want logs/2024/ and logs/2025/", result.Folders)
+	}
+	if result.Folders[1].Objects != 0 {
+		t.Errorf("Browse() counted folder objects without totals: %+v", result.Folders[1])
+	}
+	if len(result.Objects) != 1 || result.Objects[0].Key != "logs/a.log" {
+		t.Fatalf("Browse() objects = %v, want logs/a.log", result.Objects)
+	}
+	if result.TotalObjects != 1 || result.TotalBytes != int64(len("logs/a.log")) {
+		t.Errorf("Browse() totals = %d objects, %d bytes", result.TotalObjects, result.TotalBytes)
+	}
+
+	result, err = common.Browse(ctx, storage, "logs/", &common.BrowseOptions{Totals: true})
+	if err != nil {
+		t.Fatalf("Browse(totals) error = %v", err)
+	}
+	want := common.Folder{
+		Prefix:  "logs/2025/",
+		Objects: 2,
+		Bytes:   int64(len("logs/2025/b.log") + len("logs/2025/01/c.log")),
+	}
+	if len(result.Folders) != 2 || result.Folders[1] != want {
+		t.Errorf("Browse(totals) folders = %+v, want %+v", result.Folders, want)
+	}
+	if result.TotalObjects != 4 {
+		t.Errorf("Browse(totals) total objects = %d, want 4", result.TotalObjects)
+	}
+
+	result, err = common.Browse(ctx, storage, "", nil)
+	if err != nil {
+		t.Fatalf("Browse(root) error = %v", err)
+	}
+	if len(result.Folders) != 2 || len(result.Objects) != 1 || result.Objects[0].Key != "top" {
+		t.Errorf("Browse(root) = %d folders, objects %v", len(result.Folders), result.Objects)
+	}
+}
@@ -0,0 +1,268 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package common
+
+import (
+	"crypto/md5" // #nosec G501 -- SSE-C transmits the key digest as MD5
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Request and response headers of server-side encryption with
+// customer-provided keys (SSE-C), as defined by Amazon S3.
+const (
+	HeaderSSECustomerAlgorithm = "X-Amz-Server-Side-Encryption-Customer-Algorithm"
+	HeaderSSECustomerKey       = "X-Amz-Server-Side-Encryption-Customer-Key"
+	HeaderSSECustomerKeyMD5    = "X-Amz-Server-Side-Encryption-Customer-Key-MD5"
+)
+
+// SSECustomerAlgorithm is the only SSE-C algorithm, as named by S3.
+const SSECustomerAlgorithm = "AES256"
+
+// Custom metadata stored with objects encrypted with a customer-provided
+// key. The customer key itself is never stored: the object's random data
+// key is stored wrapped by it.
+const (
+	MetaSSECAlgorithm = "sse-c-algorithm"
+	MetaSSECDataKey   = "sse-c-data-key"
+)
+
+// customerKeySize is the size of a customer-provided key (AES-256).
+const customerKeySize = 32
+
+var (
+	// ErrInvalidCustomerKey is returned for incomplete or malformed SSE-C
+	// headers, including a key that does not match its MD5 digest.
+	ErrInvalidCustomerKey = fmt.Errorf("%w: invalid customer-provided encryption key", ErrInvalidArgument)
+
+	// ErrCustomerKeyRequired is returned when reading an object encrypted
+	// with a customer-provided key without providing one.
+	ErrCustomerKeyRequired = fmt.Errorf("%w: object is encrypted with a customer-provided key", ErrInvalidArgument)
+
+	// ErrNotCustomerKeyEncrypted is returned when a customer-provided key is
+	// given for an object that was not encrypted with one.
+	ErrNotCustomerKeyEncrypted = fmt.Errorf("%w: object is not encrypted with a customer-provided key", ErrInvalidArgument)
+
+	// ErrCustomerKeyMismatch is returned when the customer-provided key is
+	// not the key the object was encrypted with.
+	ErrCustomerKeyMismatch = fmt.Errorf("%w: customer-provided key does not match the object", ErrPermissionDenied)
+)
+
+// CustomerKey is an encryption key provided by the client with a request
+// (SSE-C). It lives only for the request: objects are encrypted with their
+// own data key, which is stored wrapped by the customer key, so reading
+// the object back requires the same key.
+type CustomerKey struct {
+	key    []byte
+	keyMD5 string
+}
+
+// NewCustomerKey returns the customer key for 32 raw key bytes.
+func NewCustomerKey(key []byte) (*CustomerKey, error) {
+	if len(key) != customerKeySize {
+		return nil, fmt.Errorf("%w: key must be %d bytes", ErrInvalidCustomerKey, customerKeySize)
+	}
+	sum := md5.Sum(key) // #nosec G401 -- integrity check of the transmitted key, as in S3
+	return &CustomerKey{
+		key:    append([]byte(nil), key...),
+		keyMD5: base64.StdEncoding.EncodeToString(sum[:]),
+	}, nil
+}
+
+// ParseCustomerKey returns the customer key of the values of the SSE-C
+// headers: the algorithm, which must be AES256, the base64 key and the
+// base64 MD5 digest of the key.
+func ParseCustomerKey(algorithm, key, keyMD5 string) (*CustomerKey, error) {
+	if algorithm != SSECustomerAlgorithm {
+		return nil, fmt.Errorf("%w: algorithm must be %s", ErrInvalidCustomerKey, SSECustomerAlgorithm)
+	}
+	raw, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: key is not base64", ErrInvalidCustomerKey)
+	}
+	k, err := NewCustomerKey(raw)
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(keyMD5), []byte(k.keyMD5)) != 1 {
+		return nil, fmt.Errorf("%w: key MD5 does not match the key", ErrInvalidCustomerKey)
+	}
+	return k, nil
+}
+
+// CustomerKeyFromHeaders returns the customer key carried in the SSE-C
+// headers of a request, or nil if the request has none.
+func CustomerKeyFromHeaders(h http.Header) (*CustomerKey, error) {
+	algorithm := h.Get(HeaderSSECustomerAlgorithm)
+	key := h.Get(HeaderSSECustomerKey)
+	keyMD5 := h.Get(HeaderSSECustomerKeyMD5)
+	if algorithm == "" && key == "" && keyMD5 == "" {
+		return nil, nil
+	}
+	return ParseCustomerKey(algorithm, key, keyMD5)
+}
+
+// SetHeaders sets the SSE-C headers of k on h, for a request that uses
+// the key or a response that acknowledges it. The key itself is only set
+// on requests.
+func (k *CustomerKey) SetHeaders(h http.Header, request bool) {
+	h.Set(HeaderSSECustomerAlgorithm, SSECustomerAlgorithm)
+	h.Set(HeaderSSECustomerKeyMD5, k.keyMD5)
+	if request {
+		h.Set(HeaderSSECustomerKey, base64.StdEncoding.EncodeToString(k.key))
+	}
+}
+
+// KeyMD5 returns the base64 MD5 digest of the key, which identifies it in
+// responses and logs without revealing it.
+func (k *CustomerKey) KeyMD5() string {
+	return k.keyMD5
+}
+
+// Seal encrypts an object for storage. It adds the SSE-C fields to
+// metadata, which must not be nil, and returns the content to store,
+// encrypted as it is read.
+func (k *CustomerKey) Seal(data io.Reader, metadata *Metadata) (io.Reader, error) {
+	dataKey := make([]byte, customerKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, err
+	}
+	wrapped, err := k.wrap(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := NewAESGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	if metadata.Custom == nil {
+		metadata.Custom = make(map[string]string)
+	}
+	metadata.Custom[MetaSSECAlgorithm] = SSECustomerAlgorithm
+	metadata.Custom[MetaSSECDataKey] = wrapped
+	if metadata.Size > 0 {
+		metadata.Size = SegmentedCiphertextSize(metadata.Size)
+	}
+	return NewSegmentSealer(aead, data), nil
+}
+
+// Open returns the decrypted content of an object, given its stored
+// content and metadata. It fails with ErrCustomerKeyMismatch if the object
+// was encrypted with another key, and reads fail with ErrDecrypt if the
+// stored content was modified.
+func (k *CustomerKey) Open(data io.Reader, stored *Metadata) (io.Reader, error) {
+	if !IsCustomerKeyEncrypted(stored) {
+		return nil, ErrNotCustomerKeyEncrypted
+	}
+	dataKey, err := k.unwrap(stored.Custom[MetaSSECDataKey])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := NewAESGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return NewSegmentOpener(aead, data), nil
+}
+
+// wrap encrypts a data key with the customer key.
+func (k *CustomerKey) wrap(dataKey []byte) (string, error) {
+	aead, err := NewAESGCM(k.key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, dataKey, nil)), nil
+}
+
+// unwrap decrypts a data key wrapped by wrap. A different customer key
+// fails authentication.
+func (k *CustomerKey) unwrap(wrapped string) ([]byte, error) {
+	aead, err := NewAESGCM(k.key)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := base64.StdEncoding.DecodeString(wrapped)
+	if err != nil || len(raw) < aead.NonceSize() {
+		return nil, fmt.Errorf("%w: malformed data key", ErrDecrypt)
+	}
+	dataKey, err := aead.Open(nil, raw[:aead.NonceSize()], raw[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, ErrCustomerKeyMismatch
+	}
+	return dataKey, nil
+}
+
+// IsCustomerKeyEncrypted reports whether metadata belongs to an object
+// encrypted with a customer-provided key.
+func IsCustomerKeyEncrypted(metadata *Metadata) bool {
+	return metadata != nil && metadata.Custom[MetaSSECDataKey] != ""
+}
+
+// CheckCustomerKey checks that key, which may be nil, may read the object
+// stored with metadata: an encrypted object needs the key it was encrypted
+// with and a plain object must not get a key. Use it for requests, such as
+// HEAD, that return the metadata but not the content of an object.
+func CheckCustomerKey(metadata *Metadata, key *CustomerKey) error {
+	encrypted := IsCustomerKeyEncrypted(metadata)
+	switch {
+	case encrypted && key == nil:
+		return ErrCustomerKeyRequired
+	case !encrypted && key != nil:
+		return ErrNotCustomerKeyEncrypted
+	case !encrypted:
+		return nil
+	}
+	_, err := key.unwrap(metadata.Custom[MetaSSECDataKey])
+	return err
+}
+
+// CustomerKeyMetadata returns the metadata of an object encrypted with a
+// customer-provided key as clients see it: the size is the plaintext size
+// and the SSE-C fields are removed. Other metadata is returned unchanged.
+func CustomerKeyMetadata(stored *Metadata) *Metadata {
+	if !IsCustomerKeyEncrypted(stored) {
+		return stored
+	}
+	visible := *stored
+	visible.Size = SegmentedPlaintextSize(stored.Size)
+	visible.Custom = make(map[string]string, len(stored.Custom))
+	for name, value := range stored.Custom {
+		if name != MetaSSECAlgorithm && name != MetaSSECDataKey {
+			visible.Custom[name] = value
+		}
+	}
+	return &visible
+}
+
+// KeepCustomerKeyFields copies the SSE-C fields of stored into update, a
+// replacement for the metadata of the same object, so that updating the
+// metadata of an encrypted object does not make it unreadable.
+func KeepCustomerKeyFields(stored, update *Metadata) {
+	if !IsCustomerKeyEncrypted(stored) || update == nil {
+		return
+	}
+	if update.Custom == nil {
+		update.Custom = make(map[string]string)
+	}
+	update.Custom[MetaSSECAlgorithm] = stored.Custom[MetaSSECAlgorithm]
+	update.Custom[MetaSSECDataKey] = stored.Custom[MetaSSECDataKey]
+}
@@ -0,0 +1,175 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package common
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func testCustomerKey(t *testing.T, fill byte) *CustomerKey {
+	t.Helper()
+	key, err := NewCustomerKey(bytes.Repeat([]byte{fill}, 32))
+	if err != nil {
+		t.Fatalf("NewCustomerKey() error = %v", err)
+	}
+	return key
+}
+
+func TestParseCustomerKey(t *testing.T) {
+	raw := bytes.Repeat([]byte{7}, 32)
+	sum := md5.Sum(raw)
+	key := base64.StdEncoding.EncodeToString(raw)
+	keyMD5 := base64.StdEncoding.EncodeToString(sum[:])
+
+	k, err := ParseCustomerKey(SSECustomerAlgorithm, key, keyMD5)
+	if err != nil {
+		t.Fatalf("ParseCustomerKey() error = %v", err)
+	}
+	if k.KeyMD5() != keyMD5 {
+		t.Errorf("KeyMD5() = %q, want %q", k.KeyMD5(), keyMD5)
+	}
+
+	short := base64.StdEncoding.EncodeToString(raw[:16])
+	for name, args := range map[string][3]string{
+		"algorithm":  {"aws:kms", key, keyMD5},
+		"not base64": {SSECustomerAlgorithm, "!!", keyMD5},
+		"short key":  {SSECustomerAlgorithm, short, keyMD5},
+		"wrong md5":  {SSECustomerAlgorithm, key, short},
+		"no md5":     {SSECustomerAlgorithm, key, ""},
+	} {
+		if _, err := ParseCustomerKey(args[0], args[1], args[2]); !errors.Is(err, ErrInvalidCustomerKey) {
+			t.Errorf("%s: ParseCustomerKey() error = %v, want ErrInvalidCustomerKey", name, err)
+		}
+	}
+}
+
+func TestCustomerKeyHeaders(t *testing.T) {
+	h := http.Header{}
+	if k, err := CustomerKeyFromHeaders(h); k != nil || err != nil {
+		t.Fatalf("CustomerKeyFromHeaders(empty) = %v, %v, want nil, nil", k, err)
+	}
+
+	key := testCustomerKey(t, 1)
+	key.SetHeaders(h, true)
+	parsed, err := CustomerKeyFromHeaders(h)
+	if err != nil || parsed.KeyMD5() != key.KeyMD5() {
+		t.Fatalf("CustomerKeyFromHeaders() = %v, %v", parsed, err)
+	}
+
+	response := http.Header{}
+	key.SetHeaders(response, false)
+	if response.Get(HeaderSSECustomerKey) != "" {
+		t.Error("SetHeaders() set the key on a response")
+	}
+
+	h.Del(HeaderSSECustomerKeyMD5)
+	if _, err := CustomerKeyFromHeaders(h); !errors.Is(err, ErrInvalidCustomerKey) {
+		t.Errorf("CustomerKeyFromHeaders() without MD5 error = %v, want ErrInvalidCustomerKey", err)
+	}
+}
+
+func TestCustomerKeySealOpen(t *testing.T) {
+	key := testCustomerKey(t, 1)
+	data := bytes.Repeat([]byte("secret "), EncryptionSegmentSize/3)
+
+	metadata := &Metadata{ContentType: "text/plain", Custom: map[string]string{"owner": "alice"}}
+	sealed, err := key.Seal(bytes.NewReader(data), metadata)
+	if err != nil {
+		t.Fatalf("Seal() error = %v", err)
+	}
+	stored, err := io.ReadAll(sealed)
+	if err != nil {
+		t.Fatalf("reading sealed content: %v", err)
+	}
+	if bytes.Contains(stored, []byte("secret")) {
+		t.Fatal("stored content contains plaintext")
+	}
+	metadata.Size = int64(len(stored))
+	if !IsCustomerKeyEncrypted(metadata) {
+		t.Fatal("IsCustomerKeyEncrypted() = false after Seal()")
+	}
+
+	opened, err := key.Open(bytes.NewReader(stored), metadata)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	got, err := io.ReadAll(opened)
+	if err != nil || !bytes.Equal(got, data) {
+		t.Fatalf("Open() content mismatch (err = %v)", err)
+	}
+
+	visible := CustomerKeyMetadata(metadata)
+	if visible.Size != int64(len(data)) {
+		t.Errorf("CustomerKeyMetadata() size = %d, want %d", visible.Size, len(data))
+	}
+	if len(visible.Custom) != 1 || visible.Custom["owner"] != "alice" {
+		t.Errorf("CustomerKeyMetadata() custom = %v", visible.Custom)
+	}
+	if !IsCustomerKeyEncrypted(metadata) {
+		t.Error("CustomerKeyMetadata() modified the stored metadata")
+	}
+
+	other := testCustomerKey(t, 2)
+	if _, err := other.Open(bytes.NewReader(stored), metadata); !errors.Is(err, ErrCustomerKeyMismatch) {
+		t.Errorf("Open() with another key error = %v, want ErrCustomerKeyMismatch", err)
+	}
+
+	stored[len(stored)-1] ^= 1
+	opened, _ = key.Open(bytes.NewReader(stored), metadata)
+	if _, err := io.ReadAll(opened); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("reading modified content error = %v, want ErrDecrypt", err)
+	}
+}
+
+func TestCheckCustomerKey(t *testing.T) {
+	key := testCustomerKey(t, 1)
+	encrypted := &Metadata{}
+	if _, err := key.Seal(bytes.NewReader(nil), encrypted); err != nil {
+		t.Fatalf("Seal() error = %v", err)
+	}
+	plain := &Metadata{Custom: map[string]string{"owner": "alice"}}
+
+	tests := []struct {
+		name     string
+		metadata *Metadata
+		key      *CustomerKey
+		want     error
+	}{
+		{"plain without key", plain, nil, nil},
+		{"plain with key", plain, key, ErrNotCustomerKeyEncrypted},
+		{"encrypted without key", encrypted, nil, ErrCustomerKeyRequired},
+		{"encrypted with key", encrypted, key, nil},
+		{"encrypted with another key", encrypted, testCustomerKey(t, 2), ErrCustomerKeyMismatch},
+	}
+	for _, tt := range tests {
+		if err := CheckCustomerKey(tt.metadata, tt.key); !errors.Is(err, tt.want) {
+			t.Errorf("%s: CheckCustomerKey() error = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+	if Classify(ErrCustomerKeyMismatch) != CodePermissionDenied || Classify(ErrCustomerKeyRequired) != CodeInvalidArgument {
+		t.Error("customer key errors are not classified as permission denied and invalid argument")
+	}
+
+	update := &Metadata{Custom: map[string]string{"owner": "bob"}}
+	KeepCustomerKeyFields(encrypted, update)
+	if err := CheckCustomerKey(update, key); err != nil {
+		t.Errorf("CheckCustomerKey() after KeepCustomerKeyFields() error = %v", err)
+	}
+}
@@ -16,6 +16,7 @@ package common
 import (
 	"context"
 	"io"
+	"time"
 )
 
 // readCloser combines an io.Reader with a list of Closers to be closed when Close is called.
@@ -110,6 +111,32 @@ func (e *encryptedStorage) PutWithMetadata(ctx context.Context, key string, data
 	return e.underlying.PutWithMetadata(ctx, key, encryptedData, metadata)
 }
 
+// PutWithInfo encrypts data, stores it with metadata and returns what was
+// stored
+func (e *encryptedStorage) PutWithInfo(ctx context.Context, key string, data io.Reader, metadata *Metadata) (*ObjectInfo, error) {
+	encrypter, err := e.encrypterFactory.GetEncrypter(e.defaultKeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	encryptedData, err := encrypter.Encrypt(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = encryptedData.Close() }()
+
+	if metadata == nil {
+		metadata = &Metadata{}
+	}
+	if metadata.Custom == nil {
+		metadata.Custom = make(map[string]string)
+	}
+	metadata.Custom["encryption_algorithm"] = encrypter.Algorithm()
+	metadata.Custom["encryption_key_id"] = encrypter.KeyID()
+
+	return PutWithInfo(ctx, e.underlying, key, encryptedData, metadata)
+}
+
 // Get retrieves and decrypts data from the underlying storage
 func (e *encryptedStorage) Get(key string) (io.ReadCloser, error) {
 	return e.GetWithContext(context.Background(), key)
@@ -121,13 +148,8 @@ func (e *encryptedStorage) Get(key string) (io.ReadCloser, error) {
 func (e *encryptedStorage) GetWithContext(ctx context.Context, key string) (io.ReadCloser, error) {
 	// Get metadata to determine which key was used for encryption
 	metadata, err := e.underlying.GetMetadata(ctx, key)
-	var keyID string
-	if err == nil && metadata != nil && metadata.Custom != nil {
-		keyID = metadata.Custom["encryption_key_id"]
-	}
-	// If no key ID found in metadata, use default
-	if keyID == "" {
-		keyID = e.defaultKeyID
+	if err != nil {
+		metadata = nil
 	}
 
 	// Get the encrypted data
@@ -135,6 +157,34 @@ func (e *encryptedStorage) GetWithContext(ctx context.Context, key string) (io.R
 	if err != nil {
 		return nil, err
 	}
+	return e.decrypt(ctx, encryptedData, metadata)
+}
+
+// GetAsOf retrieves and decrypts an earlier version of an object, using the
+// key recorded in that version's metadata.
+func (e *encryptedStorage) GetAsOf(ctx context.Context, key string, at time.Time) (io.ReadCloser, *Metadata, error) {
+	encryptedData, metadata, err := GetAsOf(ctx, e.underlying, key, at)
+	if err != nil {
+		return nil, nil, err
+	}
+	rc, err := e.decrypt(ctx, encryptedData, metadata)
+	if err != nil {
+		return nil, nil, err
+	}
+	return rc, metadata, nil
+}
+
+// decrypt decrypts encryptedData with the key named in metadata, or the
+// default key when metadata names none. It closes encryptedData on error.
+func (e *encryptedStorage) decrypt(ctx context.Context, encryptedData io.ReadCloser, metadata *Metadata) (io.ReadCloser, error) {
+	var keyID string
+	if metadata != nil && metadata.Custom != nil {
+		keyID = metadata.Custom["encryption_key_id"]
+	}
+	// If no key ID found in metadata, use default
+	if keyID == "" {
+		keyID = e.defaultKeyID
+	}
 
 	// Get encrypter for decryption — close encryptedData on any error path.
 	encrypter, err := e.encrypterFactory.GetEncrypter(keyID)
@@ -16,7 +16,9 @@ package common
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io/fs"
+	"net/http"
 )
 
 var (
@@ -69,15 +71,21 @@ var (
 	// ErrBufferTooSmall is returned when a buffer is too small for the requested operation.
 	ErrBufferTooSmall = errors.New("buffer too small")
 
-	// ErrKeyNotFound is returned when a key is not found in storage.
-	ErrKeyNotFound = errors.New("key not found")
+	// ErrKeyNotFound is returned when a key is not found in storage. It wraps
+	// ErrNotFound.
+	ErrKeyNotFound = fmt.Errorf("key %w", ErrNotFound)
 
-	// ErrMetadataNotFound is returned when metadata is not found for a key.
-	ErrMetadataNotFound = errors.New("metadata not found for key")
+	// ErrMetadataNotFound is returned when metadata is not found for a key. It
+	// wraps ErrNotFound.
+	ErrMetadataNotFound = fmt.Errorf("metadata %w for key", ErrNotFound)
 
 	// ErrInternal is returned for internal errors during operations.
 	ErrInternal = errors.New("internal error")
 
+	// ErrVersionsNotSupported is returned by GetAsOf when the storage keeps
+	// only the current version of each object.
+	ErrVersionsNotSupported = errors.New("backend does not support reading earlier versions")
+
 	// Lifecycle policy errors
 
 	// ErrPolicyNil is returned when a policy is nil.
@@ -88,12 +96,19 @@ var (
 
 	// Replication policy errors
 
-	// ErrPolicyNotFound is returned when a replication policy is not found.
-	ErrPolicyNotFound = errors.New("replication policy not found")
+	// ErrPolicyNotFound is returned when a replication policy is not found. It
+	// wraps ErrNotFound.
+	ErrPolicyNotFound = fmt.Errorf("replication policy %w", ErrNotFound)
 
 	// Canonical cross-transport sentinels. Backends and services wrap these so
 	// every transport (REST, gRPC, QUIC, MCP, unix) maps errors consistently
-	// via Classify.
+	// via Classify. Backends translate provider errors into them (see
+	// ProviderError), so callers never need to match provider-specific errors.
+
+	// ErrNotFound is returned when an object, metadata entry or policy does
+	// not exist. ErrKeyNotFound, ErrMetadataNotFound and ErrPolicyNotFound
+	// wrap it, so errors.Is(err, ErrNotFound) matches all of them.
+	ErrNotFound = errors.New("not found")
 
 	// ErrAlreadyExists is returned when a resource already exists.
 	ErrAlreadyExists = errors.New("already exists")
@@ -112,6 +127,28 @@ var (
 
 	// ErrUnavailable is returned when a backend or dependency is unavailable.
 	ErrUnavailable = errors.New("unavailable")
+
+	// ErrPreconditionFailed is returned when a conditional request's
+	// precondition (If-Match, If-None-Match, generation or lease) does not hold.
+	ErrPreconditionFailed = errors.New("precondition failed")
+
+	// ErrReservedKey is returned for object operations on keys in a
+	// namespace reserved for internal objects, such as locks or upload
+	// sessions. It wraps ErrPermissionDenied.
+	ErrReservedKey = fmt.Errorf("%w: key is in a reserved namespace", ErrPermissionDenied)
+
+	// ErrAccessDenied is returned when the storage provider rejects the
+	// backend's credentials. It is ErrPermissionDenied under the name storage
+	// providers use.
+	ErrAccessDenied = ErrPermissionDenied
+
+	// ErrQuotaExceeded is returned when the storage provider throttles the
+	// backend or a storage quota is exhausted. It is ErrResourceExhausted.
+	ErrQuotaExceeded = ErrResourceExhausted
+
+	// ErrBackendUnavailable is returned when the storage provider cannot be
+	// reached or fails transiently; retrying may succeed. It is ErrUnavailable.
+	ErrBackendUnavailable = ErrUnavailable
 )
 
 // ErrorCode is the canonical classification of an error, independent of
@@ -141,8 +178,47 @@ const (
 	CodeCanceled
 	// CodeDeadlineExceeded classifies timeouts.
 	CodeDeadlineExceeded
+	// CodePreconditionFailed classifies failed conditional requests.
+	CodePreconditionFailed
 )
 
+// errorCodeNames are the machine-readable names of the codes, as reported in
+// the code field of REST and QUIC error bodies.
+var errorCodeNames = [...]string{
+	CodeInternal:           "INTERNAL",
+	CodeNotFound:           "NOT_FOUND",
+	CodeAlreadyExists:      "ALREADY_EXISTS",
+	CodeInvalidArgument:    "INVALID_ARGUMENT",
+	CodePermissionDenied:   "PERMISSION_DENIED",
+	CodeUnauthenticated:    "UNAUTHENTICATED",
+	CodeResourceExhausted:  "RESOURCE_EXHAUSTED",
+	CodeUnavailable:        "UNAVAILABLE",
+	CodeCanceled:           "CANCELED",
+	CodeDeadlineExceeded:   "DEADLINE_EXCEEDED",
+	CodePreconditionFailed: "PRECONDITION_FAILED",
+}
+
+// String returns the machine-readable name of the code, such as
+// "NOT_FOUND". Unknown codes are reported as "INTERNAL".
+func (c ErrorCode) String() string {
+	if c < 0 || int(c) >= len(errorCodeNames) {
+		return errorCodeNames[CodeInternal]
+	}
+	return errorCodeNames[c]
+}
+
+// Retryable reports whether a request that failed with the code may succeed
+// when retried unchanged: throttling, unavailability and timeouts are
+// transient, every other class of failure is not.
+func (c ErrorCode) Retryable() bool {
+	switch c {
+	case CodeResourceExhausted, CodeUnavailable, CodeDeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
+
 // Classify maps an error to its canonical ErrorCode. Matching uses errors.Is
 // exclusively, so producers must wrap (or be) the canonical sentinels above,
 // the std fs sentinels, or the context errors for classification to work.
@@ -152,9 +228,7 @@ func Classify(err error) ErrorCode {
 	}
 
 	switch {
-	case errors.Is(err, ErrKeyNotFound),
-		errors.Is(err, ErrMetadataNotFound),
-		errors.Is(err, ErrPolicyNotFound),
+	case errors.Is(err, ErrNotFound),
 		// Raw filesystem not-found errors leaked by backends.
 		errors.Is(err, fs.ErrNotExist):
 		return CodeNotFound
@@ -172,6 +246,8 @@ func Classify(err error) ErrorCode {
 		return CodeResourceExhausted
 	case errors.Is(err, ErrUnavailable):
 		return CodeUnavailable
+	case errors.Is(err, ErrPreconditionFailed):
+		return CodePreconditionFailed
 	case errors.Is(err, context.Canceled):
 		return CodeCanceled
 	case errors.Is(err, context.DeadlineExceeded):
@@ -180,3 +256,50 @@ func Classify(err error) ErrorCode {
 		return CodeInternal
 	}
 }
+
+// ErrorForStatus returns the canonical sentinel for an HTTP status code
+// reported by a storage provider, or nil when the status has no canonical
+// equivalent. Backends use it as the fallback after matching provider error
+// codes.
+func ErrorForStatus(status int) error {
+	switch {
+	case status == http.StatusNotFound, status == http.StatusGone:
+		return ErrNotFound
+	case status == http.StatusConflict:
+		return ErrAlreadyExists
+	case status == http.StatusUnauthorized, status == http.StatusForbidden:
+		return ErrAccessDenied
+	case status == http.StatusPreconditionFailed:
+		return ErrPreconditionFailed
+	case status == http.StatusTooManyRequests:
+		return ErrQuotaExceeded
+	case status == http.StatusRequestTimeout, status >= http.StatusInternalServerError:
+		return ErrBackendUnavailable
+	default:
+		return nil
+	}
+}
+
+// ProviderError wraps err, a storage provider error for key, with sentinel.
+// A not-found error for a key wraps ErrKeyNotFound. The provider error stays
+// in the chain for callers that need its details. A nil sentinel returns err
+// unchanged.
+func ProviderError(sentinel error, key string, err error) error {
+	if sentinel == nil || err == nil {
+		return err
+	}
+	if sentinel == ErrNotFound && key != "" {
+		sentinel = ErrKeyNotFound
+	}
+	if key == "" {
+		return fmt.Errorf("%w: %w", sentinel, err)
+	}
+	return fmt.Errorf("%w: %s: %w", sentinel, key, err)
+}
+
+// LostRace reports whether err is a failed conditional write. Some services
+// report a conflicting conditional write as a conflict instead of a failed
+// precondition.
+func LostRace(err error) bool {
+	return errors.Is(err, ErrPreconditionFailed) || errors.Is(err, ErrAlreadyExists)
+}
@@ -55,6 +55,11 @@ func TestClassify(t *testing.T) {
 		{"unauthenticated", ErrUnauthenticated, CodeUnauthenticated},
 		{"resource exhausted", ErrResourceExhausted, CodeResourceExhausted},
 		{"unavailable", ErrUnavailable, CodeUnavailable},
+		{"not found", ErrNotFound, CodeNotFound},
+		{"precondition failed", ErrPreconditionFailed, CodePreconditionFailed},
+		{"access denied", ErrAccessDenied, CodePermissionDenied},
+		{"quota exceeded", ErrQuotaExceeded, CodeResourceExhausted},
+		{"backend unavailable", ErrBackendUnavailable, CodeUnavailable},
 		{"canceled", context.Canceled, CodeCanceled},
 		{"deadline", context.DeadlineExceeded, CodeDeadlineExceeded},
 		{"wrapped deadline", fmt.Errorf("op: %w", context.DeadlineExceeded), CodeDeadlineExceeded},
@@ -90,3 +95,118 @@ func TestClassify(t *testing.T) {
 		})
 	}
 }
+
+// TestNotFoundHierarchy pins that the specific not-found sentinels wrap
+// ErrNotFound without changing their messages.
+func TestNotFoundHierarchy(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{ErrKeyNotFound, "key not found"},
+		{ErrMetadataNotFound, "metadata not found for key"},
+		{ErrPolicyNotFound, "replication policy not found"},
+	}
+	for _, tt := range tests {
+		if !errors.Is(tt.err, ErrNotFound) {
+			t.Errorf("errors.Is(%v, ErrNotFound) = false, want true", tt.err)
+		}
+		if tt.err.Error() != tt.want {
+			t.Errorf("message = %q, want %q", tt.err.Error(), tt.want)
+		}
+	}
+	if errors.Is(ErrNotFound, ErrKeyNotFound) {
+		t.Error("errors.Is(ErrNotFound, ErrKeyNotFound) = true, want false")
+	}
+}
+
+func TestErrorForStatus(t *testing.T) {
+	tests := []struct {
+		status int
+		want   error
+	}{
+		{200, nil},
+		{304, nil},
+		{400, nil},
+		{401, ErrAccessDenied},
+		{403, ErrAccessDenied},
+		{404, ErrNotFound},
+		{408, ErrBackendUnavailable},
+		{409, ErrAlreadyExists},
+		{410, ErrNotFound},
+		{412, ErrPreconditionFailed},
+		{429, ErrQuotaExceeded},
+		{500, ErrBackendUnavailable},
+		{503, ErrBackendUnavailable},
+	}
+	for _, tt := range tests {
+		if got := ErrorForStatus(tt.status); got != tt.want {
+			t.Errorf("ErrorForStatus(%d) = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestProviderError(t *testing.T) {
+	providerErr := errors.New("NoSuchKey: the specified key does not exist")
+
+	err := ProviderError(ErrNotFound, "a/b", providerErr)
+	if !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("errors.Is(%v, ErrKeyNotFound) = false, want true", err)
+	}
+	if !errors.Is(err, providerErr) {
+		t.Errorf("provider error dropped from chain: %v", err)
+	}
+	if got, want := err.Error(), "key not found: a/b: "+providerErr.Error(); got != want {
+		t.Errorf("message = %q, want %q", got, want)
+	}
+
+	err = ProviderError(ErrNotFound, "", providerErr)
+	if errors.Is(err, ErrKeyNotFound) || !errors.Is(err, ErrNotFound) {
+		t.Errorf("keyless not-found = %v, want ErrNotFound only", err)
+	}
+
+	if err := ProviderError(nil, "k", providerErr); err != providerErr {
+		t.Errorf("ProviderError(nil, ...) = %v, want provider error unchanged", err)
+	}
+	if err := ProviderError(ErrAccessDenied, "k", nil); err != nil {
+		t.Errorf("ProviderError(_, _, nil) = %v, want nil", err)
+	}
+}
+
+func TestErrorCodeString(t *testing.T) {
+	tests := []struct {
+		code      ErrorCode
+		want      string
+		retryable bool
+	}{
+		{CodeInternal, "INTERNAL", false},
+		{CodeNotFound, "NOT_FOUND", false},
+		{CodeAlreadyExists, "ALREADY_EXISTS", false},
+		{CodeInvalidArgument, "INVALID_ARGUMENT", false},
+		{CodePermissionDenied, "PERMISSION_DENIED", false},
+		{CodeUnauthenticated, "UNAUTHENTICATED", false},
+		{CodeResourceExhausted, "RESOURCE_EXHAUSTED", true},
+		{CodeUnavailable, "UNAVAILABLE", true},
+		{CodeCanceled, "CANCELED", false},
+		{CodeDeadlineExceeded, "DEADLINE_EXCEEDED", true},
+		{CodePreconditionFailed, "PRECONDITION_FAILED", false},
+		{ErrorCode(99), "INTERNAL", false},
+	}
+	for _, tt := range tests {
+		if got := tt.code.String(); got != tt.want {
+			t.Errorf("ErrorCode(%d).String() = %q, want %q", int(tt.code), got, tt.want)
+		}
+		if got := tt.code.Retryable(); got != tt.retryable {
+			t.Errorf("%s.Retryable() = %v, want %v", tt.code, got, tt.retryable)
+		}
+	}
+}
+
+func TestLostRace(t *testing.T) {
+	if !LostRace(ErrPreconditionFailed) || !LostRace(fmt.Errorf("put: %w", ErrAlreadyExists)) {
+		t.Fatal("failed conditional writes should count as a lost race")
+	}
+	if LostRace(ErrNotFound) || LostRace(nil) {
+		t.Fatal("other errors should not count as a lost race")
+	}
+}
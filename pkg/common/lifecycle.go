@@ -14,9 +14,17 @@
 package common
 
 import (
+	"context"
+	"fmt"
+	"strings"
 	"time"
 )
 
+// MetaExpiresAt is the custom metadata field holding an object's expiry
+// time in RFC 3339 format. Lifecycle runs delete an object once its expiry
+// has passed, whatever the policies of its prefix say.
+const MetaExpiresAt = "x-objstore-expires-at"
+
 var (
 	ErrInvalidPolicy         = ErrPolicyNil // Alias for backward compatibility
 	ErrLifecycleNotSupported = ErrInvalidLifecycleManagerType
@@ -31,11 +39,14 @@ type LifecyclePolicy struct {
 	// Retention is the duration for which the object is retained.
 	Retention time.Duration
 	// Action is the action to be taken after the retention period.
-	// It can be "delete" or "archive".
+	// It can be "delete", "archive" or "transition".
 	Action string
 	// Destination specifies where to archive to when Action=="archive".
 	// For non-archive actions, this is ignored.
 	Destination Archiver
+	// StorageClass is the storage class objects move to when
+	// Action=="transition". For other actions, this is ignored.
+	StorageClass string
 }
 
 // LifecycleManager is the interface for managing lifecycle policies.
@@ -47,3 +58,71 @@ type LifecycleManager interface {
 	// GetPolicies returns all the lifecycle policies.
 	GetPolicies() ([]LifecyclePolicy, error)
 }
+
+// ParseExpiresAt parses an expiry time as stored in MetaExpiresAt.
+func ParseExpiresAt(value string) (time.Time, error) {
+	return time.Parse(time.RFC3339, strings.TrimSpace(value))
+}
+
+// ExpiresAt returns the expiry time recorded in the custom metadata of an
+// object. ok is false when the object has none or it cannot be parsed.
+func ExpiresAt(metadata *Metadata) (expiresAt time.Time, ok bool) {
+	if metadata == nil {
+		return time.Time{}, false
+	}
+	value := CustomField(metadata.Custom, MetaExpiresAt)
+	if value == "" {
+		return time.Time{}, false
+	}
+	expiresAt, err := ParseExpiresAt(value)
+	return expiresAt, err == nil
+}
+
+// Expired reports whether an object's recorded expiry time is before now.
+func Expired(metadata *Metadata, now time.Time) bool {
+	expiresAt, ok := ExpiresAt(metadata)
+	return ok && !now.Before(expiresAt)
+}
+
+// DeleteExpired deletes every object of storage whose recorded expiry time
+// is not after now and returns the number deleted. Objects that cannot be
+// deleted are skipped and the first such error is returned after the pass,
+// so they are retried on the next one.
+func DeleteExpired(ctx context.Context, storage Storage, now time.Time) (int, error) {
+	deleted := 0
+	var firstErr error
+	opts := &ListOptions{}
+	for {
+		result, err := storage.ListWithOptions(ctx, opts)
+		if err != nil {
+			return deleted, err
+		}
+		for _, obj := range result.Objects {
+			if obj == nil {
+				continue
+			}
+			metadata := obj.Metadata
+			if metadata == nil || metadata.Custom == nil {
+				// Listings of some backends omit custom metadata.
+				if stored, err := storage.GetMetadata(ctx, obj.Key); err == nil {
+					metadata = stored
+				}
+			}
+			if !Expired(metadata, now) {
+				continue
+			}
+			if err := storage.DeleteWithContext(ctx, obj.Key); err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("failed to delete expired object %s: %w", obj.Key, err)
+				}
+				continue
+			}
+			deleted++
+		}
+		if result.NextToken == "" || result.NextToken == opts.ContinueFrom {
+			break
+		}
+		opts.ContinueFrom = result.NextToken
+	}
+	return deleted, firstErr
+}
@@ -14,10 +14,14 @@
 package common_test
 
 import (
+	"context"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
 )
 
 func TestLifecycleManager_AddPolicy(t *testing.T) {
@@ -137,3 +141,69 @@ func TestLifecycleManager_GetPolicies(t *testing.T) {
 		t.Errorf("Expected nil policies, got %v", policies)
 	}
 }
+
+func TestExpiresAt(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	meta := func(value string) *common.Metadata {
+		return &common.Metadata{Custom: map[string]string{"X-Objstore-Expires-At": value}}
+	}
+
+	if at, ok := common.ExpiresAt(meta("2025-06-01T13:00:00+01:00")); !ok || !at.Equal(now) {
+		t.Errorf("ExpiresAt() = %v, %v", at, ok)
+	}
+	if !common.Expired(meta("2025-06-01T12:00:00Z"), now) {
+		t.Error("Expected an object expiring now to be expired")
+	}
+	if common.Expired(meta("2025-06-01T12:00:01Z"), now) {
+		t.Error("Expected an object expiring later not to be expired")
+	}
+	if common.Expired(meta("soon"), now) || common.Expired(&common.Metadata{}, now) || common.Expired(nil, now) {
+		t.Error("Expected objects without a valid expiry not to be expired")
+	}
+
+	if err := common.ValidateMetadata(map[string]string{common.MetaExpiresAt: "soon"}); err == nil {
+		t.Error("Expected ValidateMetadata to reject a malformed expiry")
+	}
+	if err := common.ValidateMetadata(map[string]string{common.MetaExpiresAt: "2025-06-01T12:00:00Z"}); err != nil {
+		t.Errorf("ValidateMetadata() error = %v", err)
+	}
+}
+
+// undeletable fails deletes of one key.
+type undeletable struct {
+	common.Storage
+	key string
+}
+
+func (s undeletable) DeleteWithContext(ctx context.Context, key string) error {
+	if key == s.key {
+		return errors.New("permission denied")
+	}
+	return s.Storage.DeleteWithContext(ctx, key)
+}
+
+func TestDeleteExpired(t *testing.T) {
+	ctx := context.Background()
+	backend := memory.New()
+	now := time.Now()
+	expired := &common.Metadata{Custom: map[string]string{common.MetaExpiresAt: now.Add(-time.Hour).Format(time.RFC3339)}}
+	later := &common.Metadata{Custom: map[string]string{common.MetaExpiresAt: now.Add(time.Hour).Format(time.RFC3339)}}
+	for key, metadata := range map[string]*common.Metadata{"a.txt": expired, "b.txt": expired, "c.txt": later, "d.txt": nil} {
+		if err := backend.PutWithMetadata(ctx, key, strings.NewReader(key), metadata); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A failed delete is reported after the other expired objects are gone.
+	deleted, err := common.DeleteExpired(ctx, undeletable{Storage: backend, key: "a.txt"}, now)
+	if deleted != 1 || err == nil || !strings.Contains(err.Error(), "a.txt") {
+		t.Fatalf("DeleteExpired() = %d, %v, want 1 and an error naming a.txt", deleted, err)
+	}
+	if deleted, err := common.DeleteExpired(ctx, backend, now); deleted != 1 || err != nil {
+		t.Fatalf("DeleteExpired() retry = %d, %v, want 1", deleted, err)
+	}
+	keys, _ := backend.List("")
+	if len(keys) != 2 {
+		t.Errorf("Expected c.txt and d.txt to remain, got %v", keys)
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package common
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ListSort is the order of the objects in a listing.
+type ListSort string
+
+// Listing orders.
+const (
+	ListSortName    ListSort = "name"
+	ListSortSize    ListSort = "size"
+	ListSortModTime ListSort = "mtime"
+)
+
+// ParseListSort parses a listing order. The empty string is the backend's
+// order.
+func ParseListSort(s string) (ListSort, error) {
+	switch sort := ListSort(strings.ToLower(strings.TrimSpace(s))); sort {
+	case "", ListSortName, ListSortSize, ListSortModTime:
+		return sort, nil
+	default:
+		return "", fmt.Errorf("%w: unknown sort order %q (want name, size or mtime)", ErrInvalidArgument, s)
+	}
+}
+
+// SortObjects sorts objects by the given order, breaking ties by key.
+// Objects without metadata sort as zero-sized and never modified. An empty order
+// leaves objects unchanged.
+func SortObjects(objects []*ObjectInfo, by ListSort, descending bool) error {
+	if _, err := ParseListSort(string(by)); err != nil {
+		return err
+	}
+	if by == "" {
+		return nil
+	}
+	slices.SortStableFunc(objects, func(a, b *ObjectInfo) int {
+		c := 0
+		switch by {
+		case ListSortSize:
+			c = cmp.Compare(objectSize(a), objectSize(b))
+		case ListSortModTime:
+			c = objectModTime(a).Compare(objectModTime(b))
+		}
+		if c == 0 {
+			c = strings.Compare(objectKey(a), objectKey(b))
+		}
+		if descending {
+			return -c
+		}
+		return c
+	})
+	return nil
+}
+
+func objectKey(obj *ObjectInfo) string {
+	if obj == nil {
+		return ""
+	}
+	return obj.Key
+}
+
+func objectSize(obj *ObjectInfo) int64 {
+	if obj == nil || obj.Metadata == nil {
+		return 0
+	}
+	return obj.Metadata.Size
+}
+
+func objectModTime(obj *ObjectInfo) time.Time {
+	if obj == nil || obj.Metadata == nil {
+		return time.Time{}
+	}
+	return obj.Metadata.LastModified
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package common_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+func TestSortObjects(t *testing.T) {
+	now := time.Now()
+	object := func(key string, size int64, age time.Duration) *common.ObjectInfo {
+		return &common.ObjectInfo{Key: key, Metadata: &common.Metadata{Size: size, LastModified: now.Add(-age)}}
+	}
+	keys := func(objects []*common.ObjectInfo) []string {
+		result := make([]string, len(objects))
+		for i, obj := range objects {
+			result[i] = obj.Key
+		}
+		return result
+	}
+
+	tests := []struct {
+		by         common.ListSort
+		descending bool
+		want       []string
+	}{
+		{"", false, []string{"b", "c", "a", "d"}},
+		{common.ListSortName, false, []string{"a", "b", "c", "d"}},
+		{common.ListSortName, true, []string{"d", "c", "b", "a"}},
+		{common.ListSortSize, false, []string{"d", "a", "b", "c"}},
+		{common.ListSortSize, true, []string{"c", "b", "a", "d"}},
+		{common.ListSortModTime, false, []string{"d", "c", "a", "b"}},
+		{common.ListSortModTime, true, []string{"b", "a", "c", "d"}},
+	}
+	for _, tt := range tests {
+		objects := []*common.ObjectInfo{
+			object("b", 10, time.Minute),
+			object("c", 30, 2*time.Hour),
+			object("a", 10, time.Hour),
+			{Key: "d"},
+		}
+		if err := common.SortObjects(objects, tt.by, tt.descending); err != nil {
+			t.Fatalf("SortObjects(%q) error = %v", tt.by, err)
+		}
+		got := keys(objects)
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("SortObjects(%q, %v) = %v, want %v", tt.by, tt.descending, got, tt.want)
+				break
+			}
+		}
+	}
+
+	if err := common.SortObjects(nil, "owner", false); !errors.Is(err, common.ErrInvalidArgument) {
+		t.Errorf("SortObjects(owner) = %v, want ErrInvalidArgument", err)
+	}
+	if sort, err := common.ParseListSort(" MTime "); err != nil || sort != common.ListSortModTime {
+		t.Errorf("ParseListSort() = %q, %v", sort, err)
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"maps"
+)
+
+// MetadataPatch describes a change to the metadata of existing objects.
+// Empty fields leave the corresponding metadata unchanged.
+type MetadataPatch struct {
+	ContentType     string `json:"content_type,omitempty"`
+	ContentEncoding string `json:"content_encoding,omitempty"`
+	StorageClass    string `json:"storage_class,omitempty"`
+
+	// Set adds or replaces custom metadata fields.
+	Set map[string]string `json:"set,omitempty"`
+
+	// Remove deletes custom metadata fields.
+	Remove []string `json:"remove,omitempty"`
+}
+
+// Validate reports whether p changes anything and its custom fields are
+// valid metadata.
+func (p *MetadataPatch) Validate() error {
+	if p == nil || (p.ContentType == "" && p.ContentEncoding == "" && p.StorageClass == "" && len(p.Set) == 0 && len(p.Remove) == 0) {
+		return fmt.Errorf("%w: metadata patch is empty", ErrInvalidArgument)
+	}
+	if err := ValidateMetadata(p.Set); err != nil {
+		return err
+	}
+	for _, name := range p.Remove {
+		if name == "" {
+			return fmt.Errorf("%w: metadata field to remove is empty", ErrInvalidArgument)
+		}
+		if _, ok := p.Set[name]; ok {
+			return fmt.Errorf("%w: metadata field %q is both set and removed", ErrInvalidArgument, name)
+		}
+	}
+	return nil
+}
+
+// Apply returns a copy of metadata with p applied and whether it differs
+// from metadata. SSE-C fields are kept so the object stays readable.
+func (p *MetadataPatch) Apply(metadata *Metadata) (*Metadata, bool) {
+	patched := &Metadata{}
+	if metadata != nil {
+		*patched = *metadata
+		patched.Custom = maps.Clone(metadata.Custom)
+	}
+	changed := false
+	update := func(field *string, value string) {
+		if value != "" && *field != value {
+			*field = value
+			changed = true
+		}
+	}
+	update(&patched.ContentType, p.ContentType)
+	update(&patched.ContentEncoding, p.ContentEncoding)
+	update(&patched.StorageClass, p.StorageClass)
+
+	for _, name := range p.Remove {
+		if _, ok := patched.Custom[name]; ok {
+			delete(patched.Custom, name)
+			changed = true
+		}
+	}
+	for name, value := range p.Set {
+		if current, ok := patched.Custom[name]; ok && current == value {
+			continue
+		}
+		if patched.Custom == nil {
+			patched.Custom = make(map[string]string, len(p.Set))
+		}
+		patched.Custom[name] = value
+		changed = true
+	}
+	KeepCustomerKeyFields(metadata, patched)
+	return patched, changed
+}
+
+// UpdateMetadataBatch applies patch to the metadata of every object whose
+// key starts with prefix and returns the number of objects changed. Objects
+// the patch would not change are skipped, and data is never rewritten: each
+// object's metadata is replaced with UpdateMetadata, which is a
+// metadata-only copy on backends such as S3. A failure does not stop the
+// batch; the first one is returned once every object has been visited.
+func UpdateMetadataBatch(ctx context.Context, storage Storage, prefix string, patch *MetadataPatch) (int, error) {
+	if err := patch.Validate(); err != nil {
+		return 0, err
+	}
+
+	updated := 0
+	var firstErr error
+	fail := func(key string, err error) {
+		if firstErr == nil {
+			firstErr = fmt.Errorf("failed to update metadata of %s: %w", key, err)
+		}
+	}
+	opts := &ListOptions{Prefix: prefix}
+	for {
+		if err := ctx.Err(); err != nil {
+			return updated, err
+		}
+		result, err := storage.ListWithOptions(ctx, opts)
+		if err != nil {
+			return updated, err
+		}
+		for _, obj := range result.Objects {
+			if obj == nil {
+				continue
+			}
+			// Listings of some backends omit custom metadata.
+			stored, err := storage.GetMetadata(ctx, obj.Key)
+			if err != nil {
+				fail(obj.Key, err)
+				continue
+			}
+			patched, changed := patch.Apply(stored)
+			if !changed {
+				continue
+			}
+			if err := storage.UpdateMetadata(ctx, obj.Key, patched); err != nil {
+				fail(obj.Key, err)
+				continue
+			}
+			updated++
+		}
+		if result.NextToken == "" || result.NextToken == opts.ContinueFrom {
+			break
+		}
+		opts.ContinueFrom = result.NextToken
+	}
+	return updated, firstErr
+}
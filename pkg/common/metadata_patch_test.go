@@ -0,0 +1,116 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package common_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+func TestMetadataPatch_Validate(t *testing.T) {
+	invalid := []*common.MetadataPatch{
+		nil,
+		{},
+		{Remove: []string{""}},
+		{Set: map[string]string{"tier": "cold"}, Remove: []string{"tier"}},
+		{Set: map[string]string{"bad\nkey": "x"}},
+	}
+	for _, patch := range invalid {
+		if err := patch.Validate(); !errors.Is(err, common.ErrInvalidArgument) {
+			t.Errorf("Validate(%+v) = %v, want ErrInvalidArgument", patch, err)
+		}
+	}
+	if err := (&common.MetadataPatch{StorageClass: "COLD"}).Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+}
+
+func TestMetadataPatch_Apply(t *testing.T) {
+	stored := &common.Metadata{
+		ContentType: "text/plain",
+		Size:        5,
+		Custom:      map[string]string{"owner": "ops", "draft": "true"},
+	}
+	patch := &common.MetadataPatch{
+		StorageClass: "COLD",
+		Set:          map[string]string{"owner": "data"},
+		Remove:       []string{"draft"},
+	}
+
+	patched, changed := patch.Apply(stored)
+	if !changed {
+		t.Fatal("Apply() reported no change")
+	}
+	if patched.ContentType != "text/plain" || patched.Size != 5 || patched.StorageClass != "COLD" {
+		t.Errorf("Apply() = %+v", patched)
+	}
+	if len(patched.Custom) != 1 || patched.Custom["owner"] != "data" {
+		t.Errorf("Apply() custom = %v", patched.Custom)
+	}
+	if stored.Custom["owner"] != "ops" || stored.Custom["draft"] != "true" {
+		t.Errorf("Apply() modified the stored metadata: %v", stored.Custom)
+	}
+
+	if _, changed := patch.Apply(patched); changed {
+		t.Error("Apply() reported a change for metadata it already matches")
+	}
+}
+
+func TestUpdateMetadataBatch(t *testing.T) {
+	ctx := context.Background()
+	storage := memory.New()
+	for _, key := range []string{"logs/a.log", "logs/b.log", "logs/2025/c.log", "data/d.csv"} {
+		if err := storage.PutWithMetadata(ctx, key, strings.NewReader(key), &common.Metadata{
+			Custom: map[string]string{"owner": "ops"},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := storage.UpdateMetadata(ctx, "logs/b.log", &common.Metadata{
+		Custom: map[string]string{"owner": "ops", "tier": "cold"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	patch := &common.MetadataPatch{Set: map[string]string{"tier": "cold"}}
+	updated, err := common.UpdateMetadataBatch(ctx, storage, "logs/", patch)
+	if err != nil {
+		t.Fatalf("UpdateMetadataBatch() error = %v", err)
+	}
+	// logs/b.log already matches and is skipped.
+	if updated != 2 {
+		t.Errorf("UpdateMetadataBatch() updated %d objects, want 2", updated)
+	}
+	for key, want := range map[string]string{"logs/a.log": "cold", "logs/b.log": "cold", "logs/2025/c.log": "cold", "data/d.csv": ""} {
+		metadata, err := storage.GetMetadata(ctx, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if metadata.Custom["tier"] != want || metadata.Custom["owner"] != "ops" {
+			t.Errorf("%s: custom = %v, want tier %q", key, metadata.Custom, want)
+		}
+		if got := readObject(t, storage, key); got != key {
+			t.Errorf("%s: data = %q, want it unchanged", key, got)
+		}
+	}
+
+	if _, err := common.UpdateMetadataBatch(ctx, storage, "logs/", &common.MetadataPatch{}); !errors.Is(err, common.ErrInvalidArgument) {
+		t.Errorf("UpdateMetadataBatch(empty patch) = %v, want ErrInvalidArgument", err)
+	}
+}
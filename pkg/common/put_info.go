@@ -0,0 +1,48 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package common
+
+import (
+	"context"
+	"io"
+)
+
+// PutWithInfo stores data under key and returns what was stored. Backends
+// implementing InfoWriter report it with the write. For all others the
+// result is best-effort: the object's metadata is read back after writing,
+// which may describe a later write of the same key. When that read fails the
+// write still succeeded, and the returned info only holds the size of the
+// data written. Wrappers implement InfoWriter by calling PutWithInfo on the
+// storage they wrap, so what the backend reports reaches the caller.
+func PutWithInfo(ctx context.Context, storage Storage, key string, data io.Reader, metadata *Metadata) (*ObjectInfo, error) {
+	if writer, ok := storage.(InfoWriter); ok {
+		return writer.PutWithInfo(ctx, key, data, metadata)
+	}
+
+	counter := &countingReader{r: data}
+	var err error
+	if metadata != nil {
+		err = storage.PutWithMetadata(ctx, key, counter, metadata)
+	} else {
+		err = storage.PutWithContext(ctx, key, counter)
+	}
+	if err != nil {
+		return nil, err
+	}
+	stored, err := storage.GetMetadata(ctx, key)
+	if err != nil || stored == nil {
+		return &ObjectInfo{Key: key, Metadata: &Metadata{Size: counter.n}}, nil
+	}
+	return &ObjectInfo{Key: key, Metadata: stored}, nil
+}
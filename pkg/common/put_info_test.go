@@ -0,0 +1,65 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package common_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+// unreadableStorage fails metadata reads.
+type unreadableStorage struct {
+	common.Storage
+}
+
+func (s unreadableStorage) GetMetadata(ctx context.Context, key string) (*common.Metadata, error) {
+	return nil, errors.New("timeout")
+}
+
+func TestPutWithInfo(t *testing.T) {
+	ctx := context.Background()
+	backend := memory.New()
+
+	for name, storage := range map[string]common.Storage{
+		"InfoWriter":    backend,
+		"metadata read": plainStorage{backend},
+	} {
+		info, err := common.PutWithInfo(ctx, storage, "a.txt", strings.NewReader("data"), &common.Metadata{ContentType: "text/plain"})
+		if err != nil {
+			t.Fatalf("%s: PutWithInfo() error = %v", name, err)
+		}
+		stored, _ := backend.GetMetadata(ctx, "a.txt")
+		if info.Key != "a.txt" || info.Metadata.ETag != stored.ETag || info.Metadata.Size != 4 || info.Metadata.ContentType != "text/plain" {
+			t.Errorf("%s: PutWithInfo() = %+v, want %+v", name, info.Metadata, stored)
+		}
+	}
+
+	// A failed metadata read does not fail the write.
+	storage := unreadableStorage{backend}
+	info, err := common.PutWithInfo(ctx, storage, "b.txt", strings.NewReader("hello"), nil)
+	if err != nil {
+		t.Fatalf("PutWithInfo() error = %v", err)
+	}
+	if info.Metadata.Size != 5 || info.Metadata.ETag != "" {
+		t.Errorf("PutWithInfo() after a failed read = %+v, want only the size", info.Metadata)
+	}
+	if ok, _ := backend.Exists(ctx, "b.txt"); !ok {
+		t.Error("the object was not stored")
+	}
+}
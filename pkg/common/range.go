@@ -0,0 +1,127 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// rangeReadCloser limits reads to a range while closing the underlying stream.
+type rangeReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// SliceRange narrows a full-object stream to the byte range starting at
+// offset with the given length (negative = to the end). It discards the
+// leading bytes, so it is only meant for backends that cannot seek natively,
+// for example because the data is encrypted at rest. The returned reader
+// closes rc.
+func SliceRange(rc io.ReadCloser, offset, length int64) (io.ReadCloser, error) {
+	if offset < 0 {
+		_ = rc.Close()
+		return nil, fmt.Errorf("%w: negative range offset %d", ErrInvalidArgument, offset)
+	}
+	if offset > 0 {
+		if _, err := io.CopyN(io.Discard, rc, offset); err != nil && err != io.EOF {
+			_ = rc.Close()
+			return nil, err
+		}
+	}
+	if length < 0 {
+		return rc, nil
+	}
+	return &rangeReadCloser{Reader: io.LimitReader(rc, length), Closer: rc}, nil
+}
+
+// RangeHeader formats an HTTP Range header value for offset and length
+// (negative length = to the end of the object).
+func RangeHeader(offset, length int64) string {
+	if length < 0 {
+		return fmt.Sprintf("bytes=%d-", offset)
+	}
+	return fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)
+}
+
+// rangeReadSeeker serves reads of one object through ranged requests so it
+// can be used wherever an io.ReadSeeker is expected.
+type rangeReadSeeker struct {
+	ctx    context.Context
+	reader RangeReader
+	key    string
+	size   int64
+	offset int64
+	body   io.ReadCloser
+}
+
+// NewRangeReadSeeker returns an io.ReadSeekCloser over the object stored under
+// key, whose size must be known. Each read after a seek opens a new ranged
+// request starting at the current offset, so seeking is cheap and only the
+// bytes actually read are transferred.
+func NewRangeReadSeeker(ctx context.Context, reader RangeReader, key string, size int64) io.ReadSeekCloser {
+	return &rangeReadSeeker{ctx: ctx, reader: reader, key: key, size: size}
+}
+
+func (r *rangeReadSeeker) Read(p []byte) (int, error) {
+	if r.offset >= r.size {
+		return 0, io.EOF
+	}
+	if r.body == nil {
+		body, err := r.reader.GetRange(r.ctx, r.key, r.offset, r.size-r.offset)
+		if err != nil {
+			return 0, err
+		}
+		r.body = body
+	}
+	n, err := r.body.Read(p)
+	r.offset += int64(n)
+	if err == io.EOF && r.offset < r.size {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+func (r *rangeReadSeeker) Seek(offset int64, whence int) (int64, error) {
+	var abs int64
+	switch whence {
+	case io.SeekStart:
+		abs = offset
+	case io.SeekCurrent:
+		abs = r.offset + offset
+	case io.SeekEnd:
+		abs = r.size + offset
+	default:
+		return 0, fmt.Errorf("%w: invalid whence %d", ErrInvalidArgument, whence)
+	}
+	if abs < 0 {
+		return 0, fmt.Errorf("%w: negative seek position %d", ErrInvalidArgument, abs)
+	}
+	if abs != r.offset && r.body != nil {
+		_ = r.body.Close()
+		r.body = nil
+	}
+	r.offset = abs
+	return abs, nil
+}
+
+func (r *rangeReadSeeker) Close() error {
+	if r.body == nil {
+		return nil
+	}
+	err := r.body.Close()
+	r.body = nil
+	return err
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package common_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+func TestRangeReadSeeker(t *testing.T) {
+	storage := memory.New()
+	_ = storage.Put("digits", strings.NewReader("0123456789"))
+	rs := common.NewRangeReadSeeker(context.Background(), storage.(common.RangeReader), "digits", 10)
+	defer func() { _ = rs.Close() }()
+
+	buf := make([]byte, 3)
+	if _, err := io.ReadFull(rs, buf); err != nil || string(buf) != "012" {
+		t.Fatalf("Read = %q, %v; want %q", buf, err, "012")
+	}
+
+	tests := []struct {
+		offset int64
+		whence int
+		pos    int64
+		want   string
+	}{
+		{2, io.SeekCurrent, 5, "5678"},
+		{-3, io.SeekEnd, 7, "789"},
+		{1, io.SeekStart, 1, "1234"},
+	}
+	for _, tt := range tests {
+		pos, err := rs.Seek(tt.offset, tt.whence)
+		if err != nil || pos != tt.pos {
+			t.Fatalf("Seek(%d, %d) = %d, %v; want %d", tt.offset, tt.whence, pos, err, tt.pos)
+		}
+		got := make([]byte, len(tt.want))
+		if _, err := io.ReadFull(rs, got); err != nil || string(got) != tt.want {
+			t.Errorf("Read after Seek(%d, %d) = %q, %v; want %q", tt.offset, tt.whence, got, err, tt.want)
+		}
+	}
+
+	if _, err := rs.Seek(0, io.SeekEnd); err != nil {
+		t.Fatalf("Seek to end: %v", err)
+	}
+	if n, err := rs.Read(buf); n != 0 || err != io.EOF {
+		t.Errorf("Read at end = %d, %v; want 0, EOF", n, err)
+	}
+	if _, err := rs.Seek(-11, io.SeekEnd); !errors.Is(err, common.ErrInvalidArgument) {
+		t.Errorf("Seek before start error = %v, want ErrInvalidArgument", err)
+	}
+}
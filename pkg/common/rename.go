@@ -0,0 +1,251 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package common
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// DefaultRenameConcurrency is the number of objects RenamePrefix moves in
+// parallel when RenameOptions.Concurrency is not set.
+const DefaultRenameConcurrency = 8
+
+// RenameStatus is the outcome of moving one object.
+type RenameStatus string
+
+const (
+	// RenameMoved means the object was copied to its new key and removed
+	// from its old one. In a dry run it means it would be.
+	RenameMoved RenameStatus = "moved"
+
+	// RenameSkipped means a different object already exists at the new key
+	// and was left alone, as was the object at the old key.
+	RenameSkipped RenameStatus = "skipped"
+
+	// RenameFailed means the copy or the delete failed.
+	RenameFailed RenameStatus = "failed"
+)
+
+// RenameEvent reports the outcome of moving one object.
+type RenameEvent struct {
+	From   string
+	To     string
+	Status RenameStatus
+	Err    error
+}
+
+// RenameOptions control RenamePrefix.
+type RenameOptions struct {
+	// DryRun reports what would be moved without copying or deleting
+	// anything.
+	DryRun bool
+
+	// Overwrite replaces objects that already exist at a new key with
+	// different content. Without it such objects are skipped.
+	Overwrite bool
+
+	// Concurrency is the number of objects moved in parallel; 0 uses
+	// DefaultRenameConcurrency.
+	Concurrency int
+
+	// Progress, if set, is called once per object as it is moved, skipped
+	// or fails. Calls are serialized.
+	Progress func(RenameEvent)
+}
+
+// RenameResult counts the objects a RenamePrefix moved, skipped and failed
+// to move.
+type RenameResult struct {
+	Moved   int  `json:"moved"`
+	Skipped int  `json:"skipped"`
+	Failed  int  `json:"failed"`
+	DryRun  bool `json:"dry_run,omitempty"`
+}
+
+// RenamePrefix moves every object whose key starts with oldPrefix to the
+// same key under newPrefix. Each object is copied with Compose, which
+// copies server-side on backends implementing Composer and keeps the
+// object's metadata, and is deleted from its old key only once the copy
+// has succeeded, so an interrupted rename never loses data. Running it
+// again resumes it: objects already moved are no longer under oldPrefix,
+// and objects that were copied but not yet deleted are recognized by their
+// content and just removed from their old key. A failure does not stop the
+// rename; the first one is returned once every object has been visited.
+func RenamePrefix(ctx context.Context, storage Storage, oldPrefix, newPrefix string, opts *RenameOptions) (*RenameResult, error) {
+	if opts == nil {
+		opts = &RenameOptions{}
+	}
+	if oldPrefix == "" || newPrefix == "" {
+		return nil, fmt.Errorf("%w: rename requires a source and a destination prefix", ErrInvalidArgument)
+	}
+	if err := ValidateKey(oldPrefix); err != nil {
+		return nil, err
+	}
+	if err := ValidateKey(newPrefix); err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(newPrefix, oldPrefix) || strings.HasPrefix(oldPrefix, newPrefix) {
+		return nil, fmt.Errorf("%w: cannot rename %q to %q: one prefix contains the other", ErrInvalidArgument, oldPrefix, newPrefix)
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultRenameConcurrency
+	}
+
+	result := &RenameResult{DryRun: opts.DryRun}
+	var firstErr error
+	var mu sync.Mutex
+	record := func(event RenameEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch event.Status {
+		case RenameMoved:
+			result.Moved++
+		case RenameSkipped:
+			result.Skipped++
+		case RenameFailed:
+			result.Failed++
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to move %s to %s: %w", event.From, event.To, event.Err)
+			}
+		}
+		if opts.Progress != nil {
+			opts.Progress(event)
+		}
+	}
+
+	// Backends list in key order. Keys at or before the last one handled
+	// are ignored, so a backend whose continuation token was a key that
+	// has since been moved away and that restarts the listing cannot make
+	// an object be handled twice.
+	var last string
+	listOpts := &ListOptions{Prefix: oldPrefix}
+	for {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		page, err := storage.ListWithOptions(ctx, listOpts)
+		if err != nil {
+			return result, err
+		}
+
+		keys := make(chan string)
+		var wg sync.WaitGroup
+		for range concurrency {
+			wg.Go(func() {
+				for from := range keys {
+					to := newPrefix + strings.TrimPrefix(from, oldPrefix)
+					status, err := moveObject(ctx, storage, from, to, opts)
+					record(RenameEvent{From: from, To: to, Status: status, Err: err})
+				}
+			})
+		}
+		for _, obj := range page.Objects {
+			if obj == nil || obj.Key <= last || !strings.HasPrefix(obj.Key, oldPrefix) {
+				continue
+			}
+			last = obj.Key
+			keys <- obj.Key
+		}
+		close(keys)
+		wg.Wait()
+
+		if page.NextToken == "" || page.NextToken == listOpts.ContinueFrom {
+			break
+		}
+		listOpts.ContinueFrom = page.NextToken
+	}
+	return result, firstErr
+}
+
+// moveObject copies from to to and deletes from.
+func moveObject(ctx context.Context, storage Storage, from, to string, opts *RenameOptions) (RenameStatus, error) {
+	exists, err := storage.Exists(ctx, to)
+	if err != nil {
+		return RenameFailed, err
+	}
+	copied := false
+	if exists {
+		same, err := sameObject(ctx, storage, from, to)
+		if err != nil {
+			return RenameFailed, err
+		}
+		if !same && !opts.Overwrite {
+			return RenameSkipped, nil
+		}
+		// An identical object is a copy left by an interrupted rename.
+		copied = same
+	}
+	if opts.DryRun {
+		return RenameMoved, nil
+	}
+
+	if !copied {
+		if err := Compose(ctx, storage, to, from); err != nil {
+			return RenameFailed, err
+		}
+	}
+	if err := storage.DeleteWithContext(ctx, from); err != nil && !errors.Is(err, ErrNotFound) {
+		return RenameFailed, err
+	}
+	return RenameMoved, nil
+}
+
+// sameObject reports whether the objects at a and b have the same content,
+// comparing their validators when both have one and their data otherwise.
+func sameObject(ctx context.Context, storage Storage, a, b string) (bool, error) {
+	ma, err := storage.GetMetadata(ctx, a)
+	if err != nil {
+		return false, err
+	}
+	mb, err := storage.GetMetadata(ctx, b)
+	if err != nil {
+		return false, err
+	}
+	if same, known := SameContent(ma, mb); known {
+		return same, nil
+	}
+	if ma != nil && mb != nil && ma.Size != mb.Size {
+		return false, nil
+	}
+
+	sum := func(key string) ([]byte, error) {
+		rc, err := storage.GetWithContext(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = rc.Close() }()
+		hash := sha256.New()
+		if _, err := io.Copy(hash, rc); err != nil {
+			return nil, err
+		}
+		return hash.Sum(nil), nil
+	}
+	sa, err := sum(a)
+	if err != nil {
+		return false, err
+	}
+	sb, err := sum(b)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(sa, sb), nil
+}
@@ -0,0 +1,130 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package common_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+func putObjects(t *testing.T, storage common.Storage, objects map[string]string) {
+	t.Helper()
+	for key, data := range objects {
+		if err := storage.PutWithMetadata(context.Background(), key, strings.NewReader(data), &common.Metadata{ContentType: "text/plain"}); err != nil {
+			t.Fatalf("Put(%s) error = %v", key, err)
+		}
+	}
+}
+
+func TestRenamePrefix(t *testing.T) {
+	ctx := context.Background()
+	storage := memory.New()
+	objects := map[string]string{"other/keep": "keep"}
+	for i := range 1500 {
+		objects[fmt.Sprintf("old/%04d", i)] = fmt.Sprint(i)
+	}
+	putObjects(t, storage, objects)
+
+	var events int
+	result, err := common.RenamePrefix(ctx, storage, "old/", "new/", &common.RenameOptions{
+		Concurrency: 4,
+		Progress:    func(common.RenameEvent) { events++ },
+	})
+	if err != nil {
+		t.Fatalf("RenamePrefix() error = %v", err)
+	}
+	if result.Moved != 1500 || result.Skipped != 0 || result.Failed != 0 || events != 1500 {
+		t.Errorf("result = %+v after %d events, want 1500 moved", result, events)
+	}
+	if keys, _ := storage.List("old/"); len(keys) != 0 {
+		t.Errorf("%d objects left under old/", len(keys))
+	}
+	if got := readObject(t, storage, "new/1234"); got != "1234" {
+		t.Errorf("new/1234 = %q, want 1234", got)
+	}
+	metadata, err := storage.GetMetadata(ctx, "new/0000")
+	if err != nil || metadata.ContentType != "text/plain" {
+		t.Errorf("metadata = %+v, %v, want the content type kept", metadata, err)
+	}
+	if got := readObject(t, storage, "other/keep"); got != "keep" {
+		t.Errorf("other/keep = %q", got)
+	}
+}
+
+func TestRenamePrefixDryRunAndConflicts(t *testing.T) {
+	ctx := context.Background()
+	storage := memory.New()
+	putObjects(t, storage, map[string]string{
+		"old/a": "a",
+		"old/b": "b",
+		"old/c": "c",
+		"new/b": "b", // copied by an interrupted rename
+		"new/c": "other",
+	})
+
+	result, err := common.RenamePrefix(ctx, storage, "old/", "new/", &common.RenameOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("RenamePrefix() dry run error = %v", err)
+	}
+	if result.Moved != 2 || result.Skipped != 1 || !result.DryRun {
+		t.Errorf("dry run result = %+v, want 2 moved and 1 skipped", result)
+	}
+	if keys, _ := storage.List("old/"); len(keys) != 3 {
+		t.Errorf("dry run moved objects: %v left under old/", keys)
+	}
+
+	result, err = common.RenamePrefix(ctx, storage, "old/", "new/", nil)
+	if err != nil {
+		t.Fatalf("RenamePrefix() error = %v", err)
+	}
+	if result.Moved != 2 || result.Skipped != 1 {
+		t.Errorf("result = %+v, want 2 moved and 1 skipped", result)
+	}
+	if got := readObject(t, storage, "new/c"); got != "other" {
+		t.Errorf("new/c = %q, want the conflicting object kept", got)
+	}
+	if got := readObject(t, storage, "old/c"); got != "c" {
+		t.Errorf("old/c = %q, want the skipped object kept", got)
+	}
+
+	result, err = common.RenamePrefix(ctx, storage, "old/", "new/", &common.RenameOptions{Overwrite: true})
+	if err != nil || result.Moved != 1 {
+		t.Fatalf("RenamePrefix() with overwrite = %+v, %v", result, err)
+	}
+	if got := readObject(t, storage, "new/c"); got != "c" {
+		t.Errorf("new/c = %q, want it overwritten", got)
+	}
+}
+
+func TestRenamePrefixRejectsOverlappingPrefixes(t *testing.T) {
+	storage := memory.New()
+	for _, tt := range []struct{ from, to string }{
+		{"a/", "a/b/"},
+		{"a/b/", "a/"},
+		{"", "b/"},
+		{"a/", ""},
+		{"../a/", "b/"},
+	} {
+		_, err := common.RenamePrefix(context.Background(), storage, tt.from, tt.to, nil)
+		if !errors.Is(err, common.ErrInvalidArgument) {
+			t.Errorf("RenamePrefix(%q, %q) error = %v, want ErrInvalidArgument", tt.from, tt.to, err)
+		}
+	}
+}
@@ -64,6 +64,13 @@ type ReplicationPolicy struct {
 	Enabled             bool              `json:"enabled"`
 	ReplicationMode     ReplicationMode   `json:"replication_mode"`
 	Encryption          *EncryptionPolicy `json:"encryption,omitempty"`
+
+	// WriteOnce makes the destination an append-only copy: every source
+	// version is written under its own versioned key and never
+	// overwritten, and deletes are not propagated. Combined with object
+	// lock on the destination, it keeps an immutable copy that ransomware
+	// on the source cannot destroy.
+	WriteOnce bool `json:"write_once,omitempty"`
 }
 
 // SyncResult contains the results of a sync operation.
@@ -0,0 +1,261 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// IsReserved reports whether key is in the namespace under prefix, which
+// ends in "/": it is either under the prefix or names the prefix itself.
+func IsReserved(key, prefix string) bool {
+	return strings.HasPrefix(key, prefix) || key+"/" == prefix
+}
+
+// ReservedStorage wraps a backend and reserves a namespace of internal
+// objects: object operations on keys under the prefix fail with
+// ErrReservedKey and listings leave them out, so clients can only change
+// them through the component that owns the namespace.
+type ReservedStorage struct {
+	Storage
+	prefix string
+
+	// access, if set, is the context key that lets operations made with a
+	// context carrying it reach the namespace.
+	access any
+}
+
+// NewReservedStorage returns underlying wrapped so that keys under prefix
+// are reserved.
+func NewReservedStorage(underlying Storage, prefix string) *ReservedStorage {
+	return &ReservedStorage{Storage: underlying, prefix: prefix}
+}
+
+// Prefix returns the reserved key prefix.
+func (s *ReservedStorage) Prefix() string {
+	return s.prefix
+}
+
+// Reserved reports whether key is in the reserved namespace.
+func (s *ReservedStorage) Reserved(key string) bool {
+	return IsReserved(key, s.prefix)
+}
+
+// Underlying returns the wrapped backend.
+func (s *ReservedStorage) Underlying() Storage {
+	return s.Storage
+}
+
+// allowed reports whether operations made with ctx may reach the namespace.
+func (s *ReservedStorage) allowed(ctx context.Context) bool {
+	return s.access != nil && ctx.Value(s.access) != nil
+}
+
+func (s *ReservedStorage) check(ctx context.Context, key string) error {
+	if s.Reserved(key) && !s.allowed(ctx) {
+		return fmt.Errorf("%w: %s", ErrReservedKey, key)
+	}
+	return nil
+}
+
+// Put stores an object outside the reserved namespace.
+func (s *ReservedStorage) Put(key string, data io.Reader) error {
+	return s.PutWithContext(context.Background(), key, data)
+}
+
+// PutWithContext stores an object outside the reserved namespace.
+func (s *ReservedStorage) PutWithContext(ctx context.Context, key string, data io.Reader) error {
+	if err := s.check(ctx, key); err != nil {
+		return err
+	}
+	return s.Storage.PutWithContext(ctx, key, data)
+}
+
+// PutWithMetadata stores an object with metadata outside the reserved
+// namespace.
+func (s *ReservedStorage) PutWithMetadata(ctx context.Context, key string, data io.Reader, metadata *Metadata) error {
+	if err := s.check(ctx, key); err != nil {
+		return err
+	}
+	return s.Storage.PutWithMetadata(ctx, key, data, metadata)
+}
+
+// PutWithInfo stores an object with metadata outside the reserved
+// namespace and returns what was stored.
+func (s *ReservedStorage) PutWithInfo(ctx context.Context, key string, data io.Reader, metadata *Metadata) (*ObjectInfo, error) {
+	if err := s.check(ctx, key); err != nil {
+		return nil, err
+	}
+	return PutWithInfo(ctx, s.Storage, key, data, metadata)
+}
+
+// Get retrieves an object outside the reserved namespace.
+func (s *ReservedStorage) Get(key string) (io.ReadCloser, error) {
+	return s.GetWithContext(context.Background(), key)
+}
+
+// GetWithContext retrieves an object outside the reserved namespace.
+func (s *ReservedStorage) GetWithContext(ctx context.Context, key string) (io.ReadCloser, error) {
+	if err := s.check(ctx, key); err != nil {
+		return nil, err
+	}
+	return s.Storage.GetWithContext(ctx, key)
+}
+
+// GetAsOf reads an earlier version of an object outside the reserved
+// namespace.
+func (s *ReservedStorage) GetAsOf(ctx context.Context, key string, at time.Time) (io.ReadCloser, *Metadata, error) {
+	if err := s.check(ctx, key); err != nil {
+		return nil, nil, err
+	}
+	return GetAsOf(ctx, s.Storage, key, at)
+}
+
+// GetRange reads a byte range of an object outside the reserved namespace,
+// falling back to discarding the leading bytes of a full read when the
+// wrapped backend cannot read ranges.
+func (s *ReservedStorage) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	if err := s.check(ctx, key); err != nil {
+		return nil, err
+	}
+	if rr, ok := s.Storage.(RangeReader); ok {
+		return rr.GetRange(ctx, key, offset, length)
+	}
+	rc, err := s.Storage.GetWithContext(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return SliceRange(rc, offset, length)
+}
+
+// GetMetadata retrieves the metadata of an object outside the reserved
+// namespace.
+func (s *ReservedStorage) GetMetadata(ctx context.Context, key string) (*Metadata, error) {
+	if err := s.check(ctx, key); err != nil {
+		return nil, err
+	}
+	return s.Storage.GetMetadata(ctx, key)
+}
+
+// UpdateMetadata updates the metadata of an object outside the reserved
+// namespace.
+func (s *ReservedStorage) UpdateMetadata(ctx context.Context, key string, metadata *Metadata) error {
+	if err := s.check(ctx, key); err != nil {
+		return err
+	}
+	return s.Storage.UpdateMetadata(ctx, key, metadata)
+}
+
+// Delete removes an object outside the reserved namespace.
+func (s *ReservedStorage) Delete(key string) error {
+	return s.DeleteWithContext(context.Background(), key)
+}
+
+// DeleteWithContext removes an object outside the reserved namespace.
+func (s *ReservedStorage) DeleteWithContext(ctx context.Context, key string) error {
+	if err := s.check(ctx, key); err != nil {
+		return err
+	}
+	return s.Storage.DeleteWithContext(ctx, key)
+}
+
+// Exists reports whether an object outside the reserved namespace exists.
+func (s *ReservedStorage) Exists(ctx context.Context, key string) (bool, error) {
+	if err := s.check(ctx, key); err != nil {
+		return false, err
+	}
+	return s.Storage.Exists(ctx, key)
+}
+
+// Archive copies an object outside the reserved namespace to destination.
+func (s *ReservedStorage) Archive(key string, destination Archiver) error {
+	if err := s.check(context.Background(), key); err != nil {
+		return err
+	}
+	return s.Storage.Archive(key, destination)
+}
+
+// Append adds data to the end of an object outside the reserved namespace.
+func (s *ReservedStorage) Append(ctx context.Context, key string, data io.Reader) error {
+	if err := s.check(ctx, key); err != nil {
+		return err
+	}
+	return Append(ctx, s.Storage, key, data)
+}
+
+// Compose concatenates srcKeys into destKey. No key may be in the reserved
+// namespace.
+func (s *ReservedStorage) Compose(ctx context.Context, destKey string, srcKeys ...string) error {
+	if err := s.check(ctx, destKey); err != nil {
+		return err
+	}
+	for _, key := range srcKeys {
+		if err := s.check(ctx, key); err != nil {
+			return err
+		}
+	}
+	return Compose(ctx, s.Storage, destKey, srcKeys...)
+}
+
+// List returns the keys starting with prefix, leaving out reserved keys.
+func (s *ReservedStorage) List(prefix string) ([]string, error) {
+	return s.ListWithContext(context.Background(), prefix)
+}
+
+// ListWithContext returns the keys starting with prefix, leaving out
+// reserved keys.
+func (s *ReservedStorage) ListWithContext(ctx context.Context, prefix string) ([]string, error) {
+	keys, err := s.Storage.ListWithContext(ctx, prefix)
+	if err != nil || s.allowed(ctx) {
+		return keys, err
+	}
+	filtered := keys[:0]
+	for _, key := range keys {
+		if !s.Reserved(key) {
+			filtered = append(filtered, key)
+		}
+	}
+	return filtered, nil
+}
+
+// ListWithOptions lists objects, leaving out reserved objects and the
+// reserved prefix itself. Pages that contained reserved objects come back
+// short.
+func (s *ReservedStorage) ListWithOptions(ctx context.Context, opts *ListOptions) (*ListResult, error) {
+	result, err := s.Storage.ListWithOptions(ctx, opts)
+	if err != nil || result == nil || s.allowed(ctx) {
+		return result, err
+	}
+	objects := result.Objects[:0]
+	for _, obj := range result.Objects {
+		if obj != nil && s.Reserved(obj.Key) {
+			continue
+		}
+		objects = append(objects, obj)
+	}
+	result.Objects = objects
+
+	prefixes := result.CommonPrefixes[:0]
+	for _, p := range result.CommonPrefixes {
+		if !s.Reserved(p) {
+			prefixes = append(prefixes, p)
+		}
+	}
+	result.CommonPrefixes = prefixes
+	return result, nil
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package common_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+func TestIsReserved(t *testing.T) {
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{".locks/a", true},
+		{".locks", true},
+		{".locks/", true},
+		{".lockstep", false},
+		{"data/.locks/a", false},
+	}
+	for _, tt := range tests {
+		if got := common.IsReserved(tt.key, ".locks/"); got != tt.want {
+			t.Errorf("IsReserved(%q) = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestReservedStorage(t *testing.T) {
+	ctx := context.Background()
+	backend := memory.New()
+	if err := backend.Put(".internal/state", strings.NewReader("x")); err != nil {
+		t.Fatal(err)
+	}
+	storage := common.NewReservedStorage(backend, ".internal/")
+
+	for name, op := range map[string]func() error{
+		"put":      func() error { return storage.Put(".internal/a", strings.NewReader("y")) },
+		"get":      func() error { _, err := storage.Get(".internal/state"); return err },
+		"range":    func() error { _, err := storage.GetRange(ctx, ".internal/state", 0, 1); return err },
+		"metadata": func() error { _, err := storage.GetMetadata(ctx, ".internal/state"); return err },
+		"delete":   func() error { return storage.Delete(".internal/state") },
+		"exists":   func() error { _, err := storage.Exists(ctx, ".internal/state"); return err },
+		"compose":  func() error { return storage.Compose(ctx, "out", "a", ".internal/state") },
+		"info": func() error {
+			_, err := storage.PutWithInfo(ctx, ".internal/a", strings.NewReader("y"), nil)
+			return err
+		},
+	} {
+		err := op()
+		if !errors.Is(err, common.ErrReservedKey) || !errors.Is(err, common.ErrPermissionDenied) {
+			t.Errorf("%s: expected ErrReservedKey, got %v", name, err)
+		}
+	}
+
+	info, err := storage.PutWithInfo(ctx, "visible", strings.NewReader("v"), nil)
+	if err != nil {
+		t.Fatalf("PutWithInfo outside the namespace: %v", err)
+	}
+	if info.Metadata.ETag == "" || info.Metadata.Size != 1 {
+		t.Errorf("PutWithInfo = %+v, want the backend's report", info.Metadata)
+	}
+	keys, err := storage.List("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || keys[0] != "visible" {
+		t.Fatalf("List = %v, want [visible]", keys)
+	}
+	result, err := storage.ListWithOptions(ctx, &common.ListOptions{Delimiter: "/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Objects) != 1 || len(result.CommonPrefixes) != 0 {
+		t.Fatalf("ListWithOptions = %+v, %v", result.Objects, result.CommonPrefixes)
+	}
+	if storage.Underlying() != backend {
+		t.Fatal("Underlying does not return the wrapped backend")
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package common
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// EncryptionSegmentSize is the plaintext size of each segment written by
+// NewSegmentSealer. Every segment carries its own authentication tag.
+const EncryptionSegmentSize = 64 * 1024
+
+// segmentTagSize is the size of a GCM authentication tag.
+const segmentTagSize = 16
+
+// ErrDecrypt is returned when encrypted content fails authentication: it
+// was modified, truncated, reordered or encrypted with a different key.
+var ErrDecrypt = errors.New("decryption failed")
+
+// NewAESGCM returns AES-GCM with key, which must be 16, 24 or 32 bytes.
+func NewAESGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// segmentNonce returns the nonce of segment counter. The final segment is
+// marked, so dropping trailing segments fails authentication. The first
+// nonce byte is always zero; callers may seal other values under the same
+// key with nonces whose first byte is not. Nonces repeat across streams, so
+// every stream must be sealed with its own key.
+func segmentNonce(counter uint64, final bool) []byte {
+	n := make([]byte, 12)
+	if final {
+		n[1] = 1
+	}
+	binary.BigEndian.PutUint64(n[4:], counter)
+	return n
+}
+
+// SegmentedCiphertextSize returns the size of the output of
+// NewSegmentSealer for plaintextSize bytes of input.
+func SegmentedCiphertextSize(plaintextSize int64) int64 {
+	segments := (plaintextSize + EncryptionSegmentSize - 1) / EncryptionSegmentSize
+	if segments == 0 {
+		segments = 1
+	}
+	return plaintextSize + segments*int64(segmentTagSize)
+}
+
+// SegmentedPlaintextSize returns the size of the plaintext that
+// ciphertextSize bytes of NewSegmentSealer output decrypt to.
+func SegmentedPlaintextSize(ciphertextSize int64) int64 {
+	segments := (ciphertextSize + EncryptionSegmentSize + segmentTagSize - 1) / (EncryptionSegmentSize + segmentTagSize)
+	if size := ciphertextSize - segments*segmentTagSize; size > 0 {
+		return size
+	}
+	return 0
+}
+
+// NewSegmentSealer returns a reader that encrypts src with aead as it is
+// read, in authenticated segments of EncryptionSegmentSize bytes. aead must
+// use a key that seals no other stream.
+func NewSegmentSealer(aead cipher.AEAD, src io.Reader) io.Reader {
+	return &sealReader{
+		aead:  aead,
+		src:   bufio.NewReaderSize(src, EncryptionSegmentSize+1),
+		plain: make([]byte, EncryptionSegmentSize),
+		out:   make([]byte, 0, EncryptionSegmentSize+segmentTagSize),
+	}
+}
+
+// NewSegmentOpener returns a reader that decrypts and authenticates the
+// output of NewSegmentSealer as it is read. Reads fail with ErrDecrypt when
+// src has been modified or truncated.
+func NewSegmentOpener(aead cipher.AEAD, src io.Reader) io.Reader {
+	return &openReader{
+		aead:   aead,
+		src:    bufio.NewReaderSize(src, EncryptionSegmentSize+segmentTagSize+1),
+		sealed: make([]byte, EncryptionSegmentSize+segmentTagSize),
+		out:    make([]byte, 0, EncryptionSegmentSize),
+	}
+}
+
+// sealReader encrypts src in segments as it is read.
+type sealReader struct {
+	aead    cipher.AEAD
+	src     *bufio.Reader
+	plain   []byte
+	out     []byte
+	pending []byte
+	counter uint64
+	done    bool
+	err     error
+}
+
+func (r *sealReader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		if r.done {
+			return 0, io.EOF
+		}
+		r.err = r.next()
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+// next encrypts the next segment. A segment is final when the source ends
+// within or right after it.
+func (r *sealReader) next() error {
+	n, err := io.ReadFull(r.src, r.plain)
+	switch {
+	case errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF):
+		r.done = true
+	case err != nil:
+		return err
+	default:
+		if _, err := r.src.Peek(1); errors.Is(err, io.EOF) {
+			r.done = true
+		} else if err != nil {
+			return err
+		}
+	}
+	r.pending = r.aead.Seal(r.out[:0], segmentNonce(r.counter, r.done), r.plain[:n], nil)
+	r.counter++
+	return nil
+}
+
+// openReader decrypts and authenticates src segment by segment as it is
+// read.
+type openReader struct {
+	aead    cipher.AEAD
+	src     *bufio.Reader
+	sealed  []byte
+	out     []byte
+	pending []byte
+	counter uint64
+	done    bool
+	err     error
+}
+
+func (r *openReader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		if r.done {
+			return 0, io.EOF
+		}
+		r.err = r.next()
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+func (r *openReader) next() error {
+	n, err := io.ReadFull(r.src, r.sealed)
+	final := false
+	switch {
+	case errors.Is(err, io.EOF):
+		// The previous segment was not final: the stream was truncated.
+		return ErrDecrypt
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		final = true
+	case err != nil:
+		return err
+	default:
+		if _, err := r.src.Peek(1); errors.Is(err, io.EOF) {
+			final = true
+		} else if err != nil {
+			return err
+		}
+	}
+	plain, err := r.aead.Open(r.out[:0], segmentNonce(r.counter, final), r.sealed[:n], nil)
+	if err != nil {
+		return ErrDecrypt
+	}
+	r.pending = plain
+	r.counter++
+	r.done = final
+	return nil
+}
@@ -16,6 +16,7 @@ package common
 import (
 	"context"
 	"io"
+	"time"
 )
 
 // Storage is the common interface for all storage backends.
@@ -75,3 +76,104 @@ type ReplicationCapable interface {
 	// GetReplicationManager returns the replication manager if supported.
 	GetReplicationManager() (ReplicationManager, error)
 }
+
+// RangeReader is implemented by backends that can read a byte range of an
+// object without transferring the whole object. It enables parallel ranged
+// downloads of large objects.
+type RangeReader interface {
+	// GetRange returns up to length bytes of the object starting at offset.
+	// A negative length reads to the end of the object.
+	GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error)
+}
+
+// AsOfReader is implemented by versioned backends that can read an object
+// as it was at an earlier time.
+type AsOfReader interface {
+	// GetAsOf returns the content and metadata of the version of key that
+	// was current at at. It returns an error wrapping ErrKeyNotFound if the
+	// key did not exist then or had been deleted.
+	GetAsOf(ctx context.Context, key string, at time.Time) (io.ReadCloser, *Metadata, error)
+}
+
+// Appender is implemented by backends that can add data to the end of an
+// object without rewriting it. Use Append to fall back to a rewrite on
+// backends that cannot.
+type Appender interface {
+	// Append adds data to the end of the object stored under key, creating
+	// the object if it does not exist.
+	Append(ctx context.Context, key string, data io.Reader) error
+}
+
+// Composer is implemented by backends that can concatenate objects
+// server-side. Use Compose to fall back to a copy on backends that cannot.
+type Composer interface {
+	// Compose writes the concatenation of srcKeys, in order, to destKey.
+	// destKey may also appear in srcKeys.
+	Compose(ctx context.Context, destKey string, srcKeys ...string) error
+}
+
+// InfoWriter is implemented by backends that report what a write stored,
+// sparing writers a metadata read to learn the ETag or version of the
+// object they just wrote. Use PutWithInfo to fall back to that read on
+// backends that do not.
+type InfoWriter interface {
+	// PutWithInfo stores an object like PutWithMetadata and returns its key
+	// and the metadata recorded for it: its ETag, version, size and
+	// modification time where the backend has them.
+	PutWithInfo(ctx context.Context, key string, data io.Reader, metadata *Metadata) (*ObjectInfo, error)
+}
+
+// MultipartUpload is a multipart upload started on a backend that was
+// neither completed nor aborted, such as one left behind by a crashed
+// writer. Its parts are stored, and usually billed, until it is aborted.
+type MultipartUpload struct {
+	Key       string
+	UploadID  string
+	Initiated time.Time
+
+	// Size is the total size of the parts uploaded so far.
+	Size int64
+}
+
+// MultipartAborter is implemented by backends that keep the parts of
+// unfinished multipart uploads until they are aborted.
+type MultipartAborter interface {
+	// ListMultipartUploads returns the unfinished multipart uploads of
+	// keys starting with prefix.
+	ListMultipartUploads(ctx context.Context, prefix string) ([]MultipartUpload, error)
+
+	// AbortMultipartUpload aborts an upload and deletes its parts.
+	AbortMultipartUpload(ctx context.Context, key, uploadID string) error
+}
+
+// ConditionalWriter is implemented by backends that can write and delete an
+// object atomically on a condition on its current ETag, the building block
+// for advisory locks (see pkg/locks).
+type ConditionalWriter interface {
+	// PutIfMatch stores the object only if its current ETag is etag or, when
+	// etag is empty, only if it does not exist. Otherwise it returns an error
+	// wrapping ErrPreconditionFailed.
+	PutIfMatch(ctx context.Context, key string, data io.Reader, metadata *Metadata, etag string) error
+
+	// DeleteIfMatch deletes the object only if its current ETag is etag.
+	// Otherwise it returns an error wrapping ErrPreconditionFailed, or
+	// ErrKeyNotFound if the object does not exist.
+	DeleteIfMatch(ctx context.Context, key, etag string) error
+}
+
+// ProcessLocal is implemented by backends whose conditional writes are
+// only atomic within the process that makes them. Features that coordinate
+// several processes through a backend refuse those that report true.
+type ProcessLocal interface {
+	ProcessLocal() bool
+}
+
+// BatchWriter is implemented by backends that can apply several changes
+// atomically: either every operation is applied or none is.
+type BatchWriter interface {
+	// ApplyBatch checks preconditions against the current state and, if
+	// they all hold, applies ops in order. A failed precondition returns an
+	// error wrapping ErrPreconditionFailed; any failure leaves the backend
+	// unchanged.
+	ApplyBatch(ctx context.Context, preconditions []BatchPrecondition, ops []BatchOp) error
+}
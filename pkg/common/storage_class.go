@@ -0,0 +1,39 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package common
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ActionTransition is the lifecycle action that moves objects older than the
+// retention period to LifecyclePolicy.StorageClass.
+const ActionTransition = "transition"
+
+// NormalizeStorageClass matches class case-insensitively against the storage
+// classes a backend supports and returns the backend's spelling. An empty
+// class selects the backend default and is returned unchanged.
+func NormalizeStorageClass(class string, supported []string) (string, error) {
+	if class == "" {
+		return "", nil
+	}
+	for _, s := range supported {
+		if strings.EqualFold(class, s) {
+			return s, nil
+		}
+	}
+	return "", fmt.Errorf("%w: unsupported storage class %q (supported: %s)",
+		ErrInvalidArgument, class, strings.Join(supported, ", "))
+}
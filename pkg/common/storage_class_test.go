@@ -0,0 +1,54 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package common
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizeStorageClass(t *testing.T) {
+	supported := []string{"STANDARD", "STANDARD_IA", "Cool"}
+
+	tests := []struct {
+		name    string
+		class   string
+		want    string
+		wantErr bool
+	}{
+		{name: "empty selects default", class: "", want: ""},
+		{name: "exact match", class: "STANDARD_IA", want: "STANDARD_IA"},
+		{name: "case insensitive", class: "standard_ia", want: "STANDARD_IA"},
+		{name: "backend spelling", class: "COOL", want: "Cool"},
+		{name: "unsupported", class: "GLACIER", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeStorageClass(tt.class, supported)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidArgument) {
+					t.Fatalf("expected ErrInvalidArgument, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -34,6 +34,15 @@ type Metadata struct {
 	// ETag is the entity tag for the object (used for versioning/caching)
 	ETag string `json:"etag,omitempty"`
 
+	// Version identifies this version of the object on backends that keep
+	// versions or generations (an S3 or Azure version ID, a GCS generation).
+	// It is only reported by writes.
+	Version string `json:"version,omitempty"`
+
+	// StorageClass is the backend storage class or access tier of the object
+	// (e.g., "STANDARD_IA", "NEARLINE", "Cool"). Empty means the backend default.
+	StorageClass string `json:"storage_class,omitempty"`
+
 	// Custom is a map of custom metadata key-value pairs
 	Custom map[string]string `json:"custom,omitempty"`
 }
@@ -63,6 +72,14 @@ type ListOptions struct {
 	// ContinueFrom is a pagination token from a previous ListResult
 	// Empty string means start from the beginning
 	ContinueFrom string
+
+	// SortBy orders the objects of each page by name, size or modification
+	// time. Backends list in key order and pages are still taken in that
+	// order; empty keeps it
+	SortBy ListSort
+
+	// SortDescending reverses the order selected by SortBy
+	SortDescending bool
 }
 
 // ListResult contains the results of a list operation.
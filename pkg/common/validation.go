@@ -179,9 +179,9 @@ func ValidateKey(key string) error {
 		}
 	}
 
-	// Check for absolute paths (Unix-style)
-	// Only do filepath.IsAbs if no backslashes (Windows check already done above)
-	if !hasBackslash && filepath.IsAbs(key) {
+	// Check for absolute paths. A leading separator is checked explicitly
+	// because filepath.IsAbs does not consider "/etc" absolute on Windows.
+	if key[0] == '/' || key[0] == '\\' || (!hasBackslash && filepath.IsAbs(key)) {
 		return &ValidationError{
 			Field:   fieldKey,
 			Message: "key cannot be an absolute path",
@@ -282,6 +282,15 @@ func ValidateMetadata(metadata map[string]string) error {
 				Message: "metadata value must be valid UTF-8",
 			}
 		}
+
+		if strings.EqualFold(key, MetaExpiresAt) {
+			if _, err := ParseExpiresAt(value); err != nil {
+				return &ValidationError{
+					Field:   "metadata.value",
+					Message: fmt.Sprintf("metadata value for key '%s' must be an RFC 3339 time", key),
+				}
+			}
+		}
 	}
 
 	return nil
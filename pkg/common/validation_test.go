@@ -79,6 +79,12 @@ func TestValidateKey(t *testing.T) {
 			wantErr: true,
 			errMsg:  "absolute path",
 		},
+		{
+			name:    "rooted windows path",
+			key:     "\\Windows\\System32",
+			wantErr: true,
+			errMsg:  "absolute path",
+		},
 		{
 			name:    "windows path traversal",
 			key:     "path\\..\\file.txt",
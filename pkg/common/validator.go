@@ -0,0 +1,158 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package common
+
+import (
+	"encoding/hex"
+	"maps"
+	"strconv"
+	"strings"
+)
+
+// Custom metadata recording digests of an object's content. Backends derive
+// their ETags differently (an S3 multipart ETag is not an MD5, and local and
+// Azure ETags are not digests at all), so a digest recorded with the object
+// is what lets two backends agree that they hold the same content. The
+// digests describe content of MetaChecksumSize bytes and are ignored once
+// the object's size no longer matches, for example after an append.
+// Servers may change the case of the names, so they are looked up
+// case-insensitively.
+const (
+	MetaChecksumSHA256 = "checksum-sha256"
+	MetaChecksumMD5    = "checksum-md5"
+	MetaChecksumSize   = "checksum-size"
+)
+
+// Validator algorithms, in order of preference.
+const (
+	ValidatorSHA256 = "sha256"
+	ValidatorMD5    = "md5"
+)
+
+// NormalizeETag returns etag without the weak prefix and quotes, in lower
+// case.
+func NormalizeETag(etag string) string {
+	etag = strings.TrimPrefix(strings.TrimSpace(etag), "W/")
+	return strings.ToLower(strings.Trim(etag, `"`))
+}
+
+// Validators returns the strong validators of an object by algorithm: the
+// digests recorded in its custom metadata, and the MD5 its ETag is when the
+// backend reports single-part MD5 ETags. The result is empty when nothing
+// reliable identifies the content.
+func Validators(metadata *Metadata) map[string]string {
+	validators := make(map[string]string, 2)
+	if metadata == nil {
+		return validators
+	}
+	if size, err := strconv.ParseInt(CustomField(metadata.Custom, MetaChecksumSize), 10, 64); err == nil && size == metadata.Size {
+		if sum := strings.ToLower(CustomField(metadata.Custom, MetaChecksumSHA256)); isDigest(sum, 32) {
+			validators[ValidatorSHA256] = sum
+		}
+		if sum := strings.ToLower(CustomField(metadata.Custom, MetaChecksumMD5)); isDigest(sum, 16) {
+			validators[ValidatorMD5] = sum
+		}
+	}
+	if _, ok := validators[ValidatorMD5]; !ok {
+		if etag := NormalizeETag(metadata.ETag); isDigest(etag, 16) {
+			validators[ValidatorMD5] = etag
+		}
+	}
+	return validators
+}
+
+// StrongValidator returns the preferred strong validator of an object as
+// "<algorithm>:<hex digest>", or "" when it has none.
+func StrongValidator(metadata *Metadata) string {
+	validators := Validators(metadata)
+	for _, algorithm := range []string{ValidatorSHA256, ValidatorMD5} {
+		if sum, ok := validators[algorithm]; ok {
+			return algorithm + ":" + sum
+		}
+	}
+	return ""
+}
+
+// SameContent compares two objects by the strongest validator both have.
+// known is false when they share none, and the caller has to fall back to
+// weaker signals such as size and modification time.
+func SameContent(a, b *Metadata) (same, known bool) {
+	va, vb := Validators(a), Validators(b)
+	for _, algorithm := range []string{ValidatorSHA256, ValidatorMD5} {
+		sa, okA := va[algorithm]
+		sb, okB := vb[algorithm]
+		if okA && okB {
+			return sa == sb, true
+		}
+	}
+	return false, false
+}
+
+// WithValidators returns a copy of metadata that records the object's
+// strong validators as custom metadata, so they survive a copy to a backend
+// that derives its ETags differently. metadata is returned unchanged when
+// it has no validators.
+func WithValidators(metadata *Metadata) *Metadata {
+	validators := Validators(metadata)
+	if len(validators) == 0 {
+		return metadata
+	}
+	stamped := *metadata
+	stamped.Custom = maps.Clone(metadata.Custom)
+	if stamped.Custom == nil {
+		stamped.Custom = make(map[string]string, 3)
+	}
+	for _, name := range []string{MetaChecksumSHA256, MetaChecksumMD5, MetaChecksumSize} {
+		deleteCustomField(stamped.Custom, name)
+	}
+	if sum, ok := validators[ValidatorSHA256]; ok {
+		stamped.Custom[MetaChecksumSHA256] = sum
+	}
+	if sum, ok := validators[ValidatorMD5]; ok {
+		stamped.Custom[MetaChecksumMD5] = sum
+	}
+	stamped.Custom[MetaChecksumSize] = strconv.FormatInt(metadata.Size, 10)
+	return &stamped
+}
+
+// isDigest reports whether s is the hex encoding of a size-byte digest.
+func isDigest(s string, size int) bool {
+	if len(s) != hex.EncodedLen(size) {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+// CustomField returns the custom metadata field name, ignoring case.
+func CustomField(custom map[string]string, name string) string {
+	if v, ok := custom[name]; ok {
+		return v
+	}
+	for k, v := range custom {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return ""
+}
+
+// deleteCustomField removes the custom metadata field name in any case.
+func deleteCustomField(custom map[string]string, name string) {
+	for k := range custom {
+		if strings.EqualFold(k, name) {
+			delete(custom, k)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package common
+
+import "testing"
+
+func TestNormalizeETag(t *testing.T) {
+	tests := map[string]string{
+		`"ABC"`:   "abc",
+		`W/"abc"`: "abc",
+		"abc-2":   "abc-2",
+		"":        "",
+	}
+	for in, want := range tests {
+		if got := NormalizeETag(in); got != want {
+			t.Errorf("NormalizeETag(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestSameContent(t *testing.T) {
+	const (
+		md5A = "5d41402abc4b2a76b9719d911017c592"
+		md5B = "7d793037a0760186574b0282f2f435e7"
+		shaA = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	)
+	singlePart := &Metadata{Size: 5, ETag: `"` + md5A + `"`}
+	multipart := &Metadata{Size: 5, ETag: `"` + md5B + `-2"`}
+	local := &Metadata{Size: 5, ETag: "1700000000-5"}
+	recorded := &Metadata{Size: 5, ETag: "1700000000-5", Custom: map[string]string{
+		"Checksum-MD5":     md5A,
+		MetaChecksumSHA256: shaA,
+		MetaChecksumSize:   "5",
+	}}
+
+	tests := []struct {
+		name        string
+		a, b        *Metadata
+		same, known bool
+	}{
+		{"single-part ETag against recorded digest", singlePart, recorded, true, true},
+		{"multipart ETag is not a digest", multipart, recorded, false, false},
+		{"local ETag is not a digest", local, singlePart, false, false},
+		{"different digests", singlePart, &Metadata{Size: 5, ETag: md5B}, false, true},
+		{"stale digest is ignored", &Metadata{Size: 7, Custom: recorded.Custom}, recorded, false, false},
+		{"nil metadata", nil, recorded, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			same, known := SameContent(tt.a, tt.b)
+			if same != tt.same || known != tt.known {
+				t.Errorf("SameContent() = %v, %v; want %v, %v", same, known, tt.same, tt.known)
+			}
+		})
+	}
+
+	if got := StrongValidator(recorded); got != "sha256:"+shaA {
+		t.Errorf("StrongValidator() = %q", got)
+	}
+	if got := StrongValidator(multipart); got != "" {
+		t.Errorf("StrongValidator(multipart) = %q, want none", got)
+	}
+}
+
+func TestWithValidators(t *testing.T) {
+	const md5A = "5d41402abc4b2a76b9719d911017c592"
+	src := &Metadata{Size: 5, ETag: `"` + md5A + `"`, Custom: map[string]string{"owner": "ops"}}
+	stamped := WithValidators(src)
+	if stamped == src || len(src.Custom) != 1 {
+		t.Fatal("WithValidators modified its argument")
+	}
+	if stamped.Custom[MetaChecksumMD5] != md5A || stamped.Custom[MetaChecksumSize] != "5" || stamped.Custom["owner"] != "ops" {
+		t.Errorf("stamped custom metadata = %v", stamped.Custom)
+	}
+
+	// A copy stored under a backend-specific ETag still matches the source.
+	copied := &Metadata{Size: 5, ETag: "1700000000-5", Custom: stamped.Custom}
+	if same, known := SameContent(src, copied); !same || !known {
+		t.Errorf("SameContent(source, copy) = %v, %v", same, known)
+	}
+
+	plain := &Metadata{Size: 5, ETag: "1700000000-5"}
+	if WithValidators(plain) != plain {
+		t.Error("WithValidators without validators should return its argument")
+	}
+}
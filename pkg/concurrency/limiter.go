@@ -0,0 +1,312 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package concurrency limits the operations in flight against a backend
+// with an adaptive, AIMD (additive increase, multiplicative decrease)
+// limit, the way TCP adapts its congestion window.
+//
+// Every operation that succeeds within the latency threshold while the
+// limit is in use raises it by about one per limit's worth of operations.
+// An operation that the provider throttles (common.ErrResourceExhausted),
+// that finds it unavailable (common.ErrUnavailable) or that exceeds the
+// latency threshold cuts the limit by the backoff factor, at most once per
+// round of operations in flight. Operations over the limit wait for a
+// slot, so when a provider throttles, callers and their retries queue
+// here instead of adding to the overload.
+//
+// Limits, operations in flight and decreases are recorded in the
+// process-wide Default registry, which the metrics endpoint renders.
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+const (
+	// DefaultInitial is the limit a Limiter starts with.
+	DefaultInitial = 16
+
+	// DefaultMin is the lowest limit.
+	DefaultMin = 1
+
+	// DefaultMax is the highest limit.
+	DefaultMax = 256
+
+	// DefaultBackoff is the factor the limit is multiplied by on overload.
+	DefaultBackoff = 0.5
+)
+
+// ErrLimitExceeded is returned when an operation waited MaxWait without
+// getting a slot. It is retryable: it wraps common.ErrResourceExhausted.
+var ErrLimitExceeded = fmt.Errorf("%w: backend concurrency limit reached", common.ErrResourceExhausted)
+
+// ErrInvalidConfig is returned for a malformed limiter configuration.
+var ErrInvalidConfig = fmt.Errorf("%w: invalid concurrency limit config", common.ErrInvalidArgument)
+
+// Config configures a Limiter. Zero values use the defaults.
+type Config struct {
+	// Initial, Min and Max bound the number of operations in flight:
+	// the limit starts at Initial and stays within [Min, Max].
+	Initial int
+	Min     int
+	Max     int
+
+	// LatencyThreshold treats operations slower than it as a sign of
+	// overload. Zero reacts to errors only.
+	LatencyThreshold time.Duration
+
+	// Backoff is the factor in (0, 1) the limit is multiplied by on
+	// overload (default: DefaultBackoff).
+	Backoff float64
+
+	// MaxWait is how long an operation waits for a slot before failing
+	// with ErrLimitExceeded. Zero waits until the operation's context is
+	// done.
+	MaxWait time.Duration
+}
+
+// withDefaults returns a copy of c with zero values replaced by defaults,
+// or an error wrapping ErrInvalidConfig.
+func (c *Config) withDefaults() (Config, error) {
+	var cfg Config
+	if c != nil {
+		cfg = *c
+	}
+	if cfg.Min == 0 {
+		cfg.Min = DefaultMin
+	}
+	if cfg.Max == 0 {
+		cfg.Max = max(DefaultMax, cfg.Min)
+	}
+	if cfg.Initial == 0 {
+		cfg.Initial = min(max(DefaultInitial, cfg.Min), cfg.Max)
+	}
+	if cfg.Backoff == 0 {
+		cfg.Backoff = DefaultBackoff
+	}
+	switch {
+	case cfg.Min < 1 || cfg.Max < cfg.Min:
+		return cfg, fmt.Errorf("%w: limits must satisfy 1 <= min (%d) <= max (%d)", ErrInvalidConfig, cfg.Min, cfg.Max)
+	case cfg.Initial < cfg.Min || cfg.Initial > cfg.Max:
+		return cfg, fmt.Errorf("%w: initial limit %d outside [%d, %d]", ErrInvalidConfig, cfg.Initial, cfg.Min, cfg.Max)
+	case cfg.Backoff <= 0 || cfg.Backoff >= 1:
+		return cfg, fmt.Errorf("%w: backoff %g outside (0, 1)", ErrInvalidConfig, cfg.Backoff)
+	case cfg.LatencyThreshold < 0 || cfg.MaxWait < 0:
+		return cfg, fmt.Errorf("%w: negative latency threshold or wait", ErrInvalidConfig)
+	}
+	return cfg, nil
+}
+
+// Limiter bounds the operations in flight against one backend with an
+// adaptive limit. It is safe for concurrent use.
+type Limiter struct {
+	backend string
+	cfg     Config
+
+	mu       sync.Mutex
+	limit    float64
+	inflight int
+	epoch    uint64          // incremented on every decrease
+	waiters  []chan struct{} // operations waiting for a slot, oldest first
+}
+
+// NewLimiter returns a limiter for backend, registered in Default.
+func NewLimiter(backend string, cfg *Config) (*Limiter, error) {
+	c, err := cfg.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+	l := &Limiter{backend: backend, cfg: c, limit: float64(c.Initial)}
+	Default.register(l)
+	return l, nil
+}
+
+// Backend returns the name of the limited backend.
+func (l *Limiter) Backend() string {
+	return l.backend
+}
+
+// Limit returns the current number of operations allowed in flight.
+func (l *Limiter) Limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return int(l.limit)
+}
+
+// InFlight returns the number of operations in flight and waiting.
+func (l *Limiter) InFlight() (inflight, waiting int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.inflight, len(l.waiters)
+}
+
+// Token is a slot held by an operation in flight.
+type Token struct {
+	l         *Limiter
+	start     time.Time
+	epoch     uint64
+	saturated bool // whether the limit was in use when the slot was taken
+
+	mu       sync.Mutex
+	observed bool
+	released bool
+}
+
+// Acquire waits for a slot and returns it. It fails with ctx's error when
+// ctx is done first, and with ErrLimitExceeded after MaxWait.
+func (l *Limiter) Acquire(ctx context.Context) (*Token, error) {
+	l.mu.Lock()
+	if len(l.waiters) == 0 && l.inflight < int(l.limit) {
+		t := l.grantLocked()
+		l.mu.Unlock()
+		return t, nil
+	}
+	ready := make(chan struct{})
+	l.waiters = append(l.waiters, ready)
+	l.mu.Unlock()
+
+	var timeout <-chan time.Time
+	if l.cfg.MaxWait > 0 {
+		timer := time.NewTimer(l.cfg.MaxWait)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	var err error
+	select {
+	case <-ready:
+	case <-ctx.Done():
+		err = ctx.Err()
+	case <-timeout:
+		err = ErrLimitExceeded
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err == nil || !l.dequeueLocked(ready) {
+		// The slot was granted, possibly while giving up.
+		if err != nil {
+			l.releaseLocked()
+			return nil, err
+		}
+		return l.grantedLocked(), nil
+	}
+	if errors.Is(err, ErrLimitExceeded) {
+		Default.recordRejection(l.backend)
+	}
+	return nil, err
+}
+
+// grantLocked takes a slot.
+func (l *Limiter) grantLocked() *Token {
+	l.inflight++
+	return l.grantedLocked()
+}
+
+// grantedLocked returns the token of a slot already counted in flight.
+func (l *Limiter) grantedLocked() *Token {
+	return &Token{
+		l:         l,
+		start:     time.Now(),
+		epoch:     l.epoch,
+		saturated: float64(l.inflight) >= l.limit/2,
+	}
+}
+
+// dequeueLocked removes a waiter that has not been granted a slot,
+// reporting whether it was still waiting.
+func (l *Limiter) dequeueLocked(ready chan struct{}) bool {
+	for i, w := range l.waiters {
+		if w == ready {
+			l.waiters = append(l.waiters[:i], l.waiters[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// releaseLocked frees a slot and hands free slots to waiters.
+func (l *Limiter) releaseLocked() {
+	l.inflight--
+	l.wakeLocked()
+}
+
+// wakeLocked grants slots to waiters while the limit allows.
+func (l *Limiter) wakeLocked() {
+	for len(l.waiters) > 0 && l.inflight < int(l.limit) {
+		l.inflight++
+		close(l.waiters[0])
+		l.waiters = l.waiters[1:]
+	}
+}
+
+// Observe adjusts the limit to the outcome of the operation, once: err is
+// its error, and its latency is the time since the slot was taken.
+func (t *Token) Observe(err error) {
+	t.mu.Lock()
+	if t.observed {
+		t.mu.Unlock()
+		return
+	}
+	t.observed = true
+	t.mu.Unlock()
+
+	l := t.l
+	latency := time.Since(t.start)
+	overloaded := errors.Is(err, common.ErrResourceExhausted) || errors.Is(err, common.ErrUnavailable) ||
+		(l.cfg.LatencyThreshold > 0 && latency > l.cfg.LatencyThreshold)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	switch {
+	case overloaded:
+		// Operations already in flight when the limit was cut saw the
+		// same overload; cutting again for them would collapse the limit.
+		if t.epoch != l.epoch {
+			return
+		}
+		l.limit = max(float64(l.cfg.Min), l.limit*l.cfg.Backoff)
+		l.epoch++
+		Default.recordDecrease(l.backend)
+	case err == nil && t.saturated:
+		l.limit = min(float64(l.cfg.Max), l.limit+1/l.limit)
+		l.wakeLocked()
+	}
+}
+
+// Release frees the slot, once.
+func (t *Token) Release() {
+	t.mu.Lock()
+	if t.released {
+		t.mu.Unlock()
+		return
+	}
+	t.released = true
+	t.mu.Unlock()
+
+	t.l.mu.Lock()
+	defer t.l.mu.Unlock()
+	t.l.releaseLocked()
+}
+
+// Done observes the outcome of the operation and frees its slot.
+func (t *Token) Done(err error) {
+	t.Observe(err)
+	t.Release()
+}
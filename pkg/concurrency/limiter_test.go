@@ -0,0 +1,226 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+func TestLimiterAIMD(t *testing.T) {
+	l, err := NewLimiter("aimd", &Config{Initial: 4, Max: 8})
+	if err != nil {
+		t.Fatalf("NewLimiter() error = %v", err)
+	}
+	ctx := context.Background()
+	round := func(n int) {
+		tokens := make([]*Token, n)
+		for i := range tokens {
+			if tokens[i], err = l.Acquire(ctx); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for _, token := range tokens {
+			token.Done(nil)
+		}
+	}
+
+	// A full round of successes raises the limit by about one.
+	round(4)
+	if got := l.Limit(); got != 4 {
+		t.Fatalf("Limit() after one round = %d, want 4 (just under 5)", got)
+	}
+	round(4)
+	if got := l.Limit(); got != 5 {
+		t.Fatalf("Limit() after two rounds = %d, want 5", got)
+	}
+
+	// Operations well under the limit do not raise it.
+	for range 20 {
+		token, _ := l.Acquire(ctx)
+		token.Done(nil)
+	}
+	if got := l.Limit(); got != 5 {
+		t.Fatalf("Limit() after idle operations = %d, want 5", got)
+	}
+
+	// Throttling halves it once for the operations in flight together.
+	a, _ := l.Acquire(ctx)
+	b, _ := l.Acquire(ctx)
+	throttled := fmt.Errorf("%w: slow down", common.ErrResourceExhausted)
+	a.Done(throttled)
+	b.Done(throttled)
+	if got := l.Limit(); got != 2 {
+		t.Fatalf("Limit() after throttling = %d, want 2", got)
+	}
+	c, _ := l.Acquire(ctx)
+	c.Done(common.ErrUnavailable)
+	if got := l.Limit(); got != 1 {
+		t.Fatalf("Limit() after a later failure = %d, want 1", got)
+	}
+	d, _ := l.Acquire(ctx)
+	d.Done(common.ErrUnavailable)
+	if got := l.Limit(); got != 1 {
+		t.Fatalf("Limit() = %d, want the minimum 1", got)
+	}
+
+	// Other errors say nothing about load.
+	e, _ := l.Acquire(ctx)
+	e.Done(common.ErrNotFound)
+	if inflight, waiting := l.InFlight(); inflight != 0 || waiting != 0 {
+		t.Fatalf("InFlight() = %d, %d", inflight, waiting)
+	}
+}
+
+func TestLimiterLatency(t *testing.T) {
+	l, err := NewLimiter("latency", &Config{Initial: 8, LatencyThreshold: time.Millisecond, Backoff: 0.75})
+	if err != nil {
+		t.Fatalf("NewLimiter() error = %v", err)
+	}
+	token, _ := l.Acquire(context.Background())
+	time.Sleep(5 * time.Millisecond)
+	token.Done(nil)
+	if got := l.Limit(); got != 6 {
+		t.Fatalf("Limit() after a slow operation = %d, want 6", got)
+	}
+}
+
+func TestLimiterWait(t *testing.T) {
+	l, err := NewLimiter("wait", &Config{Initial: 1, Max: 1, MaxWait: 20 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewLimiter() error = %v", err)
+	}
+	stats := func() Stats {
+		for _, s := range Default.Stats() {
+			if s.Backend == "wait" {
+				return s
+			}
+		}
+		return Stats{}
+	}
+	rejections := stats().Rejections
+	held, err := l.Acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := l.Acquire(context.Background()); !errors.Is(err, ErrLimitExceeded) || !errors.Is(err, common.ErrResourceExhausted) {
+		t.Fatalf("Acquire() over the limit error = %v, want ErrLimitExceeded", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := l.Acquire(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Acquire() with a cancelled context error = %v", err)
+	}
+
+	// A waiter gets the slot when it is released.
+	acquired := make(chan error, 1)
+	go func() {
+		token, err := l.Acquire(context.Background())
+		if err == nil {
+			token.Done(nil)
+		}
+		acquired <- err
+	}()
+	for {
+		if _, waiting := l.InFlight(); waiting == 1 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	held.Release()
+	held.Release()
+	if err := <-acquired; err != nil {
+		t.Fatalf("waiting Acquire() error = %v", err)
+	}
+	if inflight, waiting := l.InFlight(); inflight != 0 || waiting != 0 {
+		t.Fatalf("InFlight() = %d, %d", inflight, waiting)
+	}
+
+	if s := stats(); s.Limit != 1 || s.Rejections != rejections+1 {
+		t.Errorf("Stats() = %+v, want limit 1 and one more rejection", s)
+	}
+}
+
+func TestNewLimiterInvalid(t *testing.T) {
+	for _, cfg := range []*Config{
+		{Min: -1},
+		{Min: 4, Max: 2},
+		{Initial: 300},
+		{Backoff: 1.5},
+		{LatencyThreshold: -time.Second},
+	} {
+		if _, err := NewLimiter("invalid", cfg); !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("NewLimiter(%+v) error = %v, want ErrInvalidConfig", cfg, err)
+		}
+	}
+}
+
+// throttledStorage fails writes as a throttling provider does.
+type throttledStorage struct {
+	common.Storage
+}
+
+func (s *throttledStorage) PutWithContext(ctx context.Context, key string, data io.Reader) error {
+	return fmt.Errorf("%w: SlowDown", common.ErrResourceExhausted)
+}
+
+func TestStorage(t *testing.T) {
+	l, err := NewLimiter("storage", &Config{Initial: 4})
+	if err != nil {
+		t.Fatalf("NewLimiter() error = %v", err)
+	}
+	backend := memory.New()
+	s := NewStorage(backend, l)
+	ctx := context.Background()
+	if s.Underlying() != backend || s.Limiter() != l {
+		t.Fatal("Underlying() or Limiter() does not return what was wrapped")
+	}
+
+	if err := s.Put("a.txt", strings.NewReader("a")); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := s.Exists(ctx, "a.txt"); !ok || err != nil {
+		t.Fatalf("Exists() = %v, %v", ok, err)
+	}
+
+	// A read holds its slot until its content is closed.
+	rc, err := s.Get("a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inflight, _ := l.InFlight(); inflight != 1 {
+		t.Fatalf("InFlight() while reading = %d, want 1", inflight)
+	}
+	_ = rc.Close()
+	if inflight, _ := l.InFlight(); inflight != 0 {
+		t.Fatalf("InFlight() after closing = %d, want 0", inflight)
+	}
+
+	throttled := NewStorage(&throttledStorage{Storage: backend}, l)
+	if err := throttled.Put("b.txt", strings.NewReader("b")); !errors.Is(err, common.ErrResourceExhausted) {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if got := l.Limit(); got != 2 {
+		t.Fatalf("Limit() after throttling = %d, want 2", got)
+	}
+}
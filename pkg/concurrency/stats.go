@@ -0,0 +1,110 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package concurrency
+
+import (
+	"sort"
+	"sync"
+)
+
+// Stats describes the concurrency limit of one backend.
+type Stats struct {
+	// Backend is the name of the limited backend.
+	Backend string
+
+	// Limit is the number of operations currently allowed in flight,
+	// InFlight those running and Waiting those queued for a slot.
+	Limit    int
+	InFlight int
+	Waiting  int
+
+	// Decreases counts the times the limit was cut on overload, and
+	// Rejections the operations that gave up waiting for a slot.
+	Decreases  uint64
+	Rejections uint64
+}
+
+// Registry tracks the limiters of backends. It is safe for concurrent use.
+type Registry struct {
+	mu       sync.Mutex
+	limiters map[string]*Limiter
+	counters map[string]*Stats
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{limiters: make(map[string]*Limiter), counters: make(map[string]*Stats)}
+}
+
+// Default is the process-wide registry limiters register in.
+var Default = NewRegistry()
+
+// register adds l, replacing an earlier limiter of its backend but keeping
+// the backend's counters.
+func (r *Registry) register(l *Limiter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.limiters[l.backend] = l
+	if _, ok := r.counters[l.backend]; !ok {
+		r.counters[l.backend] = &Stats{Backend: l.backend}
+	}
+}
+
+// update applies fn to the counters of backend, creating them if needed.
+func (r *Registry) update(backend string, fn func(*Stats)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.counters[backend]
+	if !ok {
+		s = &Stats{Backend: backend}
+		r.counters[backend] = s
+	}
+	fn(s)
+}
+
+// recordDecrease records a cut of the limit of backend.
+func (r *Registry) recordDecrease(backend string) {
+	r.update(backend, func(s *Stats) { s.Decreases++ })
+}
+
+// recordRejection records an operation that gave up waiting for a slot.
+func (r *Registry) recordRejection(backend string) {
+	r.update(backend, func(s *Stats) { s.Rejections++ })
+}
+
+// Stats returns the statistics of every registered backend, sorted by
+// backend.
+func (r *Registry) Stats() []Stats {
+	r.mu.Lock()
+	limiters := make([]*Limiter, 0, len(r.limiters))
+	counters := make(map[string]Stats, len(r.counters))
+	for _, l := range r.limiters {
+		limiters = append(limiters, l)
+	}
+	for name, s := range r.counters {
+		counters[name] = *s
+	}
+	r.mu.Unlock()
+
+	out := make([]Stats, 0, len(limiters))
+	for _, l := range limiters {
+		s := counters[l.backend]
+		s.Backend = l.backend
+		s.Limit = l.Limit()
+		s.InFlight, s.Waiting = l.InFlight()
+		out = append(out, s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Backend < out[j].Backend })
+	return out
+}
@@ -0,0 +1,211 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package concurrency
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// Storage wraps a backend so that object operations take a slot of a
+// Limiter. A read holds its slot until its content is closed, but only the
+// time to the first byte counts as its latency. Other operations pass
+// through unchanged.
+type Storage struct {
+	common.Storage
+	limiter *Limiter
+}
+
+// NewStorage returns underlying wrapped so that its operations are limited
+// by limiter.
+func NewStorage(underlying common.Storage, limiter *Limiter) *Storage {
+	return &Storage{Storage: underlying, limiter: limiter}
+}
+
+// Limiter returns the limiter operations take slots of.
+func (s *Storage) Limiter() *Limiter {
+	return s.limiter
+}
+
+// Underlying returns the wrapped backend.
+func (s *Storage) Underlying() common.Storage {
+	return s.Storage
+}
+
+// do runs op in a slot.
+func (s *Storage) do(ctx context.Context, op func() error) error {
+	token, err := s.limiter.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	err = op()
+	token.Done(err)
+	return err
+}
+
+// read runs a read in a slot that is freed when its content is closed.
+func (s *Storage) read(ctx context.Context, op func() (io.ReadCloser, error)) (io.ReadCloser, error) {
+	token, err := s.limiter.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rc, err := op()
+	token.Observe(err)
+	if err != nil {
+		token.Release()
+		return nil, err
+	}
+	return &releaseOnClose{ReadCloser: rc, token: token}, nil
+}
+
+// releaseOnClose frees a read's slot when its content is closed.
+type releaseOnClose struct {
+	io.ReadCloser
+	token *Token
+}
+
+func (r *releaseOnClose) Close() error {
+	err := r.ReadCloser.Close()
+	r.token.Release()
+	return err
+}
+
+// Put stores an object in a slot.
+func (s *Storage) Put(key string, data io.Reader) error {
+	return s.PutWithContext(context.Background(), key, data)
+}
+
+// PutWithContext stores an object in a slot.
+func (s *Storage) PutWithContext(ctx context.Context, key string, data io.Reader) error {
+	return s.do(ctx, func() error { return s.Storage.PutWithContext(ctx, key, data) })
+}
+
+// PutWithMetadata stores an object with metadata in a slot.
+func (s *Storage) PutWithMetadata(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) error {
+	return s.do(ctx, func() error { return s.Storage.PutWithMetadata(ctx, key, data, metadata) })
+}
+
+// PutWithInfo stores an object with metadata in a slot and returns what
+// was stored.
+func (s *Storage) PutWithInfo(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) (info *common.ObjectInfo, err error) {
+	err = s.do(ctx, func() error {
+		info, err = common.PutWithInfo(ctx, s.Storage, key, data, metadata)
+		return err
+	})
+	return info, err
+}
+
+// Get retrieves an object in a slot.
+func (s *Storage) Get(key string) (io.ReadCloser, error) {
+	return s.GetWithContext(context.Background(), key)
+}
+
+// GetWithContext retrieves an object in a slot.
+func (s *Storage) GetWithContext(ctx context.Context, key string) (io.ReadCloser, error) {
+	return s.read(ctx, func() (io.ReadCloser, error) { return s.Storage.GetWithContext(ctx, key) })
+}
+
+// GetRange reads a byte range in a slot, falling back to discarding the
+// leading bytes of a full read when the backend cannot read ranges.
+func (s *Storage) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	rr, ok := s.Storage.(common.RangeReader)
+	if !ok {
+		rc, err := s.GetWithContext(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		return common.SliceRange(rc, offset, length)
+	}
+	return s.read(ctx, func() (io.ReadCloser, error) { return rr.GetRange(ctx, key, offset, length) })
+}
+
+// GetAsOf reads an earlier version of an object in a slot.
+func (s *Storage) GetAsOf(ctx context.Context, key string, at time.Time) (io.ReadCloser, *common.Metadata, error) {
+	var metadata *common.Metadata
+	rc, err := s.read(ctx, func() (rc io.ReadCloser, err error) {
+		rc, metadata, err = common.GetAsOf(ctx, s.Storage, key, at)
+		return rc, err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return rc, metadata, nil
+}
+
+// GetMetadata retrieves an object's metadata in a slot.
+func (s *Storage) GetMetadata(ctx context.Context, key string) (*common.Metadata, error) {
+	var metadata *common.Metadata
+	err := s.do(ctx, func() error {
+		var err error
+		metadata, err = s.Storage.GetMetadata(ctx, key)
+		return err
+	})
+	return metadata, err
+}
+
+// UpdateMetadata updates an object's metadata in a slot.
+func (s *Storage) UpdateMetadata(ctx context.Context, key string, metadata *common.Metadata) error {
+	return s.do(ctx, func() error { return s.Storage.UpdateMetadata(ctx, key, metadata) })
+}
+
+// Delete removes an object in a slot.
+func (s *Storage) Delete(key string) error {
+	return s.DeleteWithContext(context.Background(), key)
+}
+
+// DeleteWithContext removes an object in a slot.
+func (s *Storage) DeleteWithContext(ctx context.Context, key string) error {
+	return s.do(ctx, func() error { return s.Storage.DeleteWithContext(ctx, key) })
+}
+
+// Exists checks whether an object exists in a slot.
+func (s *Storage) Exists(ctx context.Context, key string) (bool, error) {
+	var exists bool
+	err := s.do(ctx, func() error {
+		var err error
+		exists, err = s.Storage.Exists(ctx, key)
+		return err
+	})
+	return exists, err
+}
+
+// List lists keys in a slot.
+func (s *Storage) List(prefix string) ([]string, error) {
+	return s.ListWithContext(context.Background(), prefix)
+}
+
+// ListWithContext lists keys in a slot.
+func (s *Storage) ListWithContext(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	err := s.do(ctx, func() error {
+		var err error
+		keys, err = s.Storage.ListWithContext(ctx, prefix)
+		return err
+	})
+	return keys, err
+}
+
+// ListWithOptions lists a page of objects in a slot.
+func (s *Storage) ListWithOptions(ctx context.Context, opts *common.ListOptions) (*common.ListResult, error) {
+	var result *common.ListResult
+	err := s.do(ctx, func() error {
+		var err error
+		result, err = s.Storage.ListWithOptions(ctx, opts)
+		return err
+	})
+	return result, err
+}
@@ -0,0 +1,284 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package contentpolicy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"elf", []byte("\x7fELF\x02\x01\x01\x00"), TypeELF},
+		{"pe", []byte("MZ\x90\x00\x03\x00"), TypePE},
+		{"mach-o", []byte{0xcf, 0xfa, 0xed, 0xfe, 0x07}, TypeMachO},
+		{"shebang", []byte("#!/bin/sh\necho hi\n"), TypeShellScript},
+		{"png", pngHeader, "image/png"},
+		{"text", []byte("hello world"), "text/plain; charset=utf-8"},
+		{"empty", nil, "text/plain; charset=utf-8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Detect(tt.data); got != tt.want {
+				t.Errorf("Detect() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectReader(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"report.PDF", []byte("hello"), "application/pdf"},
+		{"data/rows.csv", []byte("a,b\n1,2\n"), "text/csv; charset=utf-8"},
+		{"site/app.webmanifest", []byte("{}"), "application/manifest+json"},
+		{"images/logo", pngHeader, "image/png"},
+		{"bin/tool", []byte("\x7fELF\x02\x01\x01\x00"), TypeELF},
+		{"notes.unknownext", []byte("plain words"), "text/plain; charset=utf-8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := append(append([]byte{}, tt.data...), bytes.Repeat([]byte{'x'}, 1024)...)
+			got, r, err := DetectReader(tt.name, bytes.NewReader(body))
+			if err != nil {
+				t.Fatalf("DetectReader() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("DetectReader() = %q, want %q", got, tt.want)
+			}
+			replayed, err := io.ReadAll(r)
+			if err != nil || !bytes.Equal(replayed, body) {
+				t.Errorf("reader yielded %d bytes, want %d (%v)", len(replayed), len(body), err)
+			}
+		})
+	}
+
+	if got := TypeByExtension("README"); got != "" {
+		t.Errorf("TypeByExtension(README) = %q, want empty", got)
+	}
+}
+
+func TestPolicyCheck(t *testing.T) {
+	policy := &Policy{Rules: []Rule{
+		{Prefix: "uploads/", Deny: []string{TypeELF, TypePE, TypeShellScript}},
+		{Prefix: "uploads/images/", Allow: []string{"image/*"}, Deny: []string{"image/svg+xml"}},
+	}}
+	if err := policy.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	tests := []struct {
+		name              string
+		key               string
+		declared, sniffed string
+		wantErr           bool
+	}{
+		{"no rule", "other/tool", "", TypeELF, false},
+		{"denied sniffed", "uploads/tool", "", TypeELF, true},
+		{"denied despite declared", "uploads/tool", "image/png", TypePE, true},
+		{"not denied", "uploads/notes.txt", "", "text/plain; charset=utf-8", false},
+		{"allowed", "uploads/images/a.png", "image/png", "image/png", false},
+		{"allowed with parameters", "uploads/images/a.png", "IMAGE/PNG; q=1", "", false},
+		{"not allowed", "uploads/images/a.txt", "", "text/plain; charset=utf-8", true},
+		{"deny wins over allow", "uploads/images/a.svg", "image/svg+xml", "", true},
+		{"longest prefix only", "uploads/images/tool", "image/png", TypeELF, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Check(tt.key, tt.declared, tt.sniffed)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && (!errors.Is(err, ErrContentTypeRejected) || !errors.Is(err, common.ErrInvalidArgument)) {
+				t.Errorf("Check() error = %v, want ErrContentTypeRejected and ErrInvalidArgument", err)
+			}
+		})
+	}
+}
+
+func TestPolicyValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  Policy
+		wantErr bool
+	}{
+		{"wildcards", Policy{Rules: []Rule{{Allow: []string{"*/*", "image/*", "text/plain; charset=utf-8"}}}}, false},
+		{"missing subtype", Policy{Rules: []Rule{{Deny: []string{"image"}}}}, true},
+		{"partial wildcard", Policy{Rules: []Rule{{Deny: []string{"image/p*"}}}}, true},
+		{"wildcard type", Policy{Rules: []Rule{{Deny: []string{"*/png"}}}}, true},
+		{"duplicate prefix", Policy{Rules: []Rule{{Prefix: "a/"}, {Prefix: "a/"}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidPolicy) {
+				t.Errorf("Validate() error = %v, want ErrInvalidPolicy", err)
+			}
+		})
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+	yamlFile := filepath.Join(dir, "policy.yaml")
+	if err := os.WriteFile(yamlFile, []byte(`sniff: true
+rules:
+  - prefix: uploads/
+    deny: [application/x-executable]
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	policy, err := LoadFile(yamlFile)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if !policy.Sniff || len(policy.Rules) != 1 || policy.Rules[0].Deny[0] != TypeELF {
+		t.Errorf("LoadFile() = %+v", policy)
+	}
+
+	jsonFile := filepath.Join(dir, "policy.json")
+	if err := os.WriteFile(jsonFile, []byte(`{"rules": [{"prefix": "img/", "allow": ["image/*"]}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if policy, err = LoadFile(jsonFile); err != nil || policy.Sniff || policy.Rules[0].Allow[0] != "image/*" {
+		t.Errorf("LoadFile() = %+v, %v", policy, err)
+	}
+
+	badFile := filepath.Join(dir, "bad.yaml")
+	if err := os.WriteFile(badFile, []byte("rules:\n  - allow: [nope]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{badFile, filepath.Join(dir, "missing.yaml")} {
+		if _, err := LoadFile(name); !errors.Is(err, ErrInvalidPolicy) {
+			t.Errorf("LoadFile(%s) error = %v, want ErrInvalidPolicy", filepath.Base(name), err)
+		}
+	}
+}
+
+func TestStorage(t *testing.T) {
+	ctx := context.Background()
+	backend := memory.New()
+	s := NewStorage(backend, &Policy{
+		Sniff: true,
+		Rules: []Rule{{Prefix: "uploads/", Deny: []string{TypeELF, TypeShellScript}}},
+	})
+	if s.Underlying() != backend {
+		t.Error("Underlying() did not return the wrapped backend")
+	}
+
+	// A missing content type is filled from the sniffed bytes and the full
+	// body is stored.
+	body := append(append([]byte{}, pngHeader...), bytes.Repeat([]byte{0}, 1024)...)
+	if err := s.Put("images/a.png", bytes.NewReader(body)); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	assertObject(t, backend, "images/a.png", body, "image/png")
+
+	// A declared content type is kept and the caller's metadata untouched.
+	metadata := &common.Metadata{Custom: map[string]string{"owner": "ops"}}
+	if err := s.PutWithMetadata(ctx, "notes.txt", strings.NewReader("hello"), metadata); err != nil {
+		t.Fatalf("PutWithMetadata() error = %v", err)
+	}
+	if metadata.ContentType != "" {
+		t.Errorf("caller metadata modified: %+v", metadata)
+	}
+	assertObject(t, backend, "notes.txt", []byte("hello"), "text/plain; charset=utf-8")
+	if err := s.PutWithMetadata(ctx, "data.bin", strings.NewReader("hello"), &common.Metadata{ContentType: "application/custom"}); err != nil {
+		t.Fatalf("PutWithMetadata() error = %v", err)
+	}
+	assertObject(t, backend, "data.bin", []byte("hello"), "application/custom")
+
+	// The key's extension takes precedence over the sniffed bytes, and the
+	// type filled in from it is checked like a declared one.
+	if err := s.Put("reports/q1.csv", strings.NewReader("region,total\n")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	assertObject(t, backend, "reports/q1.csv", []byte("region,total\n"), "text/csv; charset=utf-8")
+	err := s.PutWithContext(ctx, "uploads/run.sh", strings.NewReader("echo hi\n"))
+	if !errors.Is(err, ErrContentTypeRejected) {
+		t.Fatalf("PutWithContext() error = %v, want ErrContentTypeRejected", err)
+	}
+
+	// Denied content is rejected before reaching the backend.
+	err = s.PutWithContext(ctx, "uploads/tool", strings.NewReader("\x7fELF\x02\x01\x01"))
+	if !errors.Is(err, ErrContentTypeRejected) {
+		t.Fatalf("PutWithContext() error = %v, want ErrContentTypeRejected", err)
+	}
+	if exists, _ := backend.Exists(ctx, "uploads/tool"); exists {
+		t.Error("rejected object was stored")
+	}
+}
+
+func TestStorage_NoSniff(t *testing.T) {
+	ctx := context.Background()
+	backend := memory.New()
+	s := NewStorage(backend, &Policy{Rules: []Rule{{Prefix: "uploads/", Deny: []string{TypeELF}}}})
+
+	if err := s.PutWithContext(ctx, "images/a.png", bytes.NewReader(pngHeader)); err != nil {
+		t.Fatalf("PutWithContext() error = %v", err)
+	}
+	assertObject(t, backend, "images/a.png", pngHeader, "")
+
+	// Keys under a rule are still sniffed for validation, but the content
+	// type is not filled in.
+	if err := s.PutWithContext(ctx, "uploads/a.png", bytes.NewReader(pngHeader)); err != nil {
+		t.Fatalf("PutWithContext() error = %v", err)
+	}
+	assertObject(t, backend, "uploads/a.png", pngHeader, "")
+}
+
+func assertObject(t *testing.T, backend common.Storage, key string, want []byte, contentType string) {
+	t.Helper()
+	ctx := context.Background()
+	rc, err := backend.GetWithContext(ctx, key)
+	if err != nil {
+		t.Fatalf("GetWithContext(%s) error = %v", key, err)
+	}
+	defer func() { _ = rc.Close() }()
+	got, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s: stored %d bytes, want %d", key, len(got), len(want))
+	}
+	metadata, err := backend.GetMetadata(ctx, key)
+	if err != nil {
+		t.Fatalf("GetMetadata(%s) error = %v", key, err)
+	}
+	if metadata.ContentType != contentType {
+		t.Errorf("%s: content type = %q, want %q", key, metadata.ContentType, contentType)
+	}
+}
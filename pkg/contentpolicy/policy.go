@@ -0,0 +1,175 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package contentpolicy sniffs the content type of uploaded objects and
+// enforces per-prefix allow and deny lists of content types.
+package contentpolicy
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"os"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+var (
+	// ErrContentTypeRejected is returned when an object's content type is not
+	// permitted under its key prefix. Errors wrapping it also wrap
+	// common.ErrInvalidArgument.
+	ErrContentTypeRejected = errors.New("content type rejected")
+
+	// ErrInvalidPolicy is returned when a policy cannot be loaded or is
+	// malformed.
+	ErrInvalidPolicy = errors.New("invalid content policy")
+)
+
+// Rule restricts the content types that may be stored under Prefix. Entries
+// are media types such as "image/png" or wildcards such as "image/*".
+type Rule struct {
+	// Prefix selects the keys the rule applies to. An empty prefix matches
+	// every key.
+	Prefix string `yaml:"prefix" json:"prefix"`
+
+	// Allow, when non-empty, lists the only content types accepted.
+	Allow []string `yaml:"allow,omitempty" json:"allow,omitempty"`
+
+	// Deny lists content types that are always rejected.
+	Deny []string `yaml:"deny,omitempty" json:"deny,omitempty"`
+}
+
+// Policy configures content type sniffing and validation.
+type Policy struct {
+	// Sniff fills in the content type of objects stored without one from
+	// their key's extension, or from their first bytes when the extension
+	// is not recognized.
+	Sniff bool `yaml:"sniff" json:"sniff"`
+
+	// Rules are matched by longest prefix; only the most specific rule
+	// applies to a key.
+	Rules []Rule `yaml:"rules" json:"rules"`
+}
+
+// LoadFile reads a policy from a YAML or JSON file.
+func LoadFile(filename string) (*Policy, error) {
+	data, err := os.ReadFile(filename) // #nosec G304 -- path supplied by the operator
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPolicy, err)
+	}
+	var policy Policy
+	if err := yaml.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidPolicy, filename, err)
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// Validate checks that every rule's content types are well formed and that
+// no prefix is configured twice.
+func (p *Policy) Validate() error {
+	seen := make(map[string]bool, len(p.Rules))
+	for _, rule := range p.Rules {
+		if seen[rule.Prefix] {
+			return fmt.Errorf("%w: duplicate rule for prefix %q", ErrInvalidPolicy, rule.Prefix)
+		}
+		seen[rule.Prefix] = true
+		for _, pattern := range append(append([]string{}, rule.Allow...), rule.Deny...) {
+			if !validPattern(pattern) {
+				return fmt.Errorf("%w: prefix %q: malformed content type %q", ErrInvalidPolicy, rule.Prefix, pattern)
+			}
+		}
+	}
+	return nil
+}
+
+// rule returns the most specific rule matching key, or nil when none does.
+func (p *Policy) rule(key string) *Rule {
+	var match *Rule
+	for i := range p.Rules {
+		rule := &p.Rules[i]
+		if !strings.HasPrefix(key, rule.Prefix) {
+			continue
+		}
+		if match == nil || len(rule.Prefix) > len(match.Prefix) {
+			match = rule
+		}
+	}
+	return match
+}
+
+// Check reports whether an object stored under key may have the declared
+// and sniffed content types; either may be empty. The declared type, or the
+// sniffed type when none was declared, must be allowed, and neither may be
+// denied, so an executable labelled as an image is still rejected.
+func (p *Policy) Check(key, declared, sniffed string) error {
+	rule := p.rule(key)
+	if rule == nil {
+		return nil
+	}
+	for _, contentType := range []string{declared, sniffed} {
+		if contentType != "" && matchAny(rule.Deny, contentType) {
+			return rejected(key, rule, contentType)
+		}
+	}
+	effective := declared
+	if effective == "" {
+		effective = sniffed
+	}
+	if len(rule.Allow) > 0 && !matchAny(rule.Allow, effective) {
+		return rejected(key, rule, effective)
+	}
+	return nil
+}
+
+func rejected(key string, rule *Rule, contentType string) error {
+	return fmt.Errorf("%w: %w: %q is not permitted for %s (rule prefix %q)",
+		common.ErrInvalidArgument, ErrContentTypeRejected, contentType, key, rule.Prefix)
+}
+
+// matchAny reports whether contentType matches one of patterns. Parameters
+// such as charset are ignored and the comparison is case-insensitive.
+func matchAny(patterns []string, contentType string) bool {
+	mediaType := normalize(contentType)
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(normalize(pattern), mediaType); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func normalize(contentType string) string {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		return mediaType
+	}
+	return strings.ToLower(strings.TrimSpace(contentType))
+}
+
+// validPattern accepts "type/subtype", "type/*" and "*/*".
+func validPattern(pattern string) bool {
+	kind, sub, ok := strings.Cut(normalize(pattern), "/")
+	if !ok || kind == "" || sub == "" || strings.ContainsAny(kind+sub, "/[]\\?") {
+		return false
+	}
+	if kind == "*" && sub != "*" {
+		return false
+	}
+	return (kind == "*" || !strings.Contains(kind, "*")) && (sub == "*" || !strings.Contains(sub, "*"))
+}
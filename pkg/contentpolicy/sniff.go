@@ -0,0 +1,167 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package contentpolicy
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// sniffLen is the number of leading bytes inspected by Detect.
+const sniffLen = 512
+
+// Content types reported for executables, which http.DetectContentType
+// classifies only as application/octet-stream.
+const (
+	TypeELF         = "application/x-executable"
+	TypePE          = "application/vnd.microsoft.portable-executable"
+	TypeMachO       = "application/x-mach-binary"
+	TypeShellScript = "text/x-shellscript"
+)
+
+var executableSignatures = []struct {
+	magic       []byte
+	contentType string
+}{
+	{[]byte("\x7fELF"), TypeELF},
+	{[]byte("MZ"), TypePE},
+	{[]byte{0xfe, 0xed, 0xfa, 0xce}, TypeMachO},
+	{[]byte{0xfe, 0xed, 0xfa, 0xcf}, TypeMachO},
+	{[]byte{0xce, 0xfa, 0xed, 0xfe}, TypeMachO},
+	{[]byte{0xcf, 0xfa, 0xed, 0xfe}, TypeMachO},
+	{[]byte("#!"), TypeShellScript},
+}
+
+// Detect returns the content type of data, which need only hold the first
+// 512 bytes of an object. It recognizes ELF, PE and Mach-O executables and
+// scripts with a shebang line in addition to the types known to
+// http.DetectContentType.
+func Detect(data []byte) string {
+	for _, sig := range executableSignatures {
+		if bytes.HasPrefix(data, sig.magic) {
+			return sig.contentType
+		}
+	}
+	return http.DetectContentType(data)
+}
+
+// typesByExtension covers common object types independently of the host's
+// mime.types, which minimal container images lack.
+var typesByExtension = map[string]string{
+	".7z":          "application/x-7z-compressed",
+	".avif":        "image/avif",
+	".bmp":         "image/bmp",
+	".bz2":         "application/x-bzip2",
+	".css":         "text/css; charset=utf-8",
+	".csv":         "text/csv; charset=utf-8",
+	".doc":         "application/msword",
+	".docx":        "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	".epub":        "application/epub+zip",
+	".flac":        "audio/flac",
+	".gif":         "image/gif",
+	".gz":          "application/gzip",
+	".htm":         "text/html; charset=utf-8",
+	".html":        "text/html; charset=utf-8",
+	".ico":         "image/x-icon",
+	".ics":         "text/calendar; charset=utf-8",
+	".jpeg":        "image/jpeg",
+	".jpg":         "image/jpeg",
+	".js":          "text/javascript; charset=utf-8",
+	".json":        "application/json",
+	".jsonl":       "application/x-ndjson",
+	".m4a":         "audio/mp4",
+	".md":          "text/markdown; charset=utf-8",
+	".mjs":         "text/javascript; charset=utf-8",
+	".mkv":         "video/x-matroska",
+	".mov":         "video/quicktime",
+	".mp3":         "audio/mpeg",
+	".mp4":         "video/mp4",
+	".ndjson":      "application/x-ndjson",
+	".oga":         "audio/ogg",
+	".ogg":         "audio/ogg",
+	".ogv":         "video/ogg",
+	".otf":         "font/otf",
+	".parquet":     "application/vnd.apache.parquet",
+	".pdf":         "application/pdf",
+	".png":         "image/png",
+	".ppt":         "application/vnd.ms-powerpoint",
+	".pptx":        "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+	".rtf":         "application/rtf",
+	".sh":          TypeShellScript,
+	".svg":         "image/svg+xml",
+	".tar":         "application/x-tar",
+	".tgz":         "application/gzip",
+	".tif":         "image/tiff",
+	".tiff":        "image/tiff",
+	".toml":        "application/toml",
+	".tsv":         "text/tab-separated-values; charset=utf-8",
+	".ttf":         "font/ttf",
+	".txt":         "text/plain; charset=utf-8",
+	".wasm":        "application/wasm",
+	".wav":         "audio/wav",
+	".weba":        "audio/webm",
+	".webm":        "video/webm",
+	".webmanifest": "application/manifest+json",
+	".webp":        "image/webp",
+	".woff":        "font/woff",
+	".woff2":       "font/woff2",
+	".xls":         "application/vnd.ms-excel",
+	".xlsx":        "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	".xml":         "application/xml",
+	".xz":          "application/x-xz",
+	".yaml":        "application/yaml",
+	".yml":         "application/yaml",
+	".zip":         "application/zip",
+	".zst":         "application/zstd",
+}
+
+// TypeByExtension returns the content type for the extension of name, a key
+// or file name, or an empty string when the extension is not recognized.
+func TypeByExtension(name string) string {
+	ext := strings.ToLower(path.Ext(name))
+	if ext == "" {
+		return ""
+	}
+	if contentType, ok := typesByExtension[ext]; ok {
+		return contentType
+	}
+	return mime.TypeByExtension(ext)
+}
+
+// DetectReader returns the content type of an object named name whose data
+// is read from r, and a reader that yields all of r's data. The type comes
+// from name's extension when it is recognized; otherwise the first 512
+// bytes are inspected with Detect.
+func DetectReader(name string, r io.Reader) (string, io.Reader, error) {
+	if contentType := TypeByExtension(name); contentType != "" {
+		return contentType, r, nil
+	}
+	return sniff(r)
+}
+
+// sniff detects the content type of r and returns a reader that replays the
+// inspected bytes followed by the rest of r.
+func sniff(r io.Reader) (string, io.Reader, error) {
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", nil, err
+	}
+	head = head[:n]
+	return Detect(head), io.MultiReader(bytes.NewReader(head), r), nil
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package contentpolicy
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// Storage wraps a backend and applies a Policy to every Put. Appends and
+// composes are passed through unchecked, as are reads, listings and
+// lifecycle operations.
+type Storage struct {
+	common.Storage
+	policy *Policy
+}
+
+// NewStorage returns underlying wrapped so that writes are checked against
+// policy.
+func NewStorage(underlying common.Storage, policy *Policy) *Storage {
+	return &Storage{Storage: underlying, policy: policy}
+}
+
+// Policy returns the policy enforced by s.
+func (s *Storage) Policy() *Policy {
+	return s.policy
+}
+
+// Underlying returns the wrapped backend.
+func (s *Storage) Underlying() common.Storage {
+	return s.Storage
+}
+
+// GetRange reads a byte range from the wrapped backend, falling back to
+// discarding the leading bytes of a full read when it cannot read ranges.
+func (s *Storage) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	if rr, ok := s.Storage.(common.RangeReader); ok {
+		return rr.GetRange(ctx, key, offset, length)
+	}
+	rc, err := s.Storage.GetWithContext(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return common.SliceRange(rc, offset, length)
+}
+
+// GetAsOf reads an earlier version of an object from the wrapped backend.
+func (s *Storage) GetAsOf(ctx context.Context, key string, at time.Time) (io.ReadCloser, *common.Metadata, error) {
+	return common.GetAsOf(ctx, s.Storage, key, at)
+}
+
+// Put checks and stores an object.
+func (s *Storage) Put(key string, data io.Reader) error {
+	return s.PutWithContext(context.Background(), key, data)
+}
+
+// PutWithContext checks and stores an object. When sniffing is enabled the
+// object is stored with its detected content type.
+func (s *Storage) PutWithContext(ctx context.Context, key string, data io.Reader) error {
+	if !s.policy.Sniff && s.policy.rule(key) == nil {
+		return s.Storage.PutWithContext(ctx, key, data)
+	}
+	return s.PutWithMetadata(ctx, key, data, nil)
+}
+
+// PutWithMetadata checks and stores an object with metadata. When sniffing
+// is enabled and metadata carries no content type, the type for the key's
+// extension, or else the detected type, is stored and checked as if it had
+// been declared; the caller's metadata is not modified.
+func (s *Storage) PutWithMetadata(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) error {
+	data, metadata, err := s.check(key, data, metadata)
+	if err != nil {
+		return err
+	}
+	return s.Storage.PutWithMetadata(ctx, key, data, metadata)
+}
+
+// PutWithInfo checks and stores an object with metadata like
+// PutWithMetadata and returns what was stored.
+func (s *Storage) PutWithInfo(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) (*common.ObjectInfo, error) {
+	data, metadata, err := s.check(key, data, metadata)
+	if err != nil {
+		return nil, err
+	}
+	return common.PutWithInfo(ctx, s.Storage, key, data, metadata)
+}
+
+// check checks an object against the policy and returns the content and
+// metadata to store.
+func (s *Storage) check(key string, data io.Reader, metadata *common.Metadata) (io.Reader, *common.Metadata, error) {
+	declared := ""
+	if metadata != nil {
+		declared = metadata.ContentType
+	}
+	fill := s.policy.Sniff && declared == ""
+	if !fill && s.policy.rule(key) == nil {
+		return data, metadata, nil
+	}
+
+	sniffed, data, err := sniff(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	if fill {
+		if declared = TypeByExtension(key); declared == "" {
+			declared = sniffed
+		}
+	}
+	if err := s.policy.Check(key, declared, sniffed); err != nil {
+		return nil, nil, err
+	}
+	if fill {
+		filled := common.Metadata{}
+		if metadata != nil {
+			filled = *metadata
+		}
+		filled.ContentType = declared
+		metadata = &filled
+	}
+	return data, metadata, nil
+}
+
+// Append adds data to the end of an object.
+func (s *Storage) Append(ctx context.Context, key string, data io.Reader) error {
+	return common.Append(ctx, s.Storage, key, data)
+}
+
+// Compose concatenates srcKeys into destKey.
+func (s *Storage) Compose(ctx context.Context, destKey string, srcKeys ...string) error {
+	return common.Compose(ctx, s.Storage, destKey, srcKeys...)
+}
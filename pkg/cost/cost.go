@@ -0,0 +1,666 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package cost meters the requests, stored bytes and egress of each backend
+// and turns them into monthly cost estimates using configurable pricing
+// tables, so spend can be attributed to key prefixes and teams before the
+// provider's bill arrives.
+//
+// A Meter accumulates usage per calendar month (UTC), backend and group.
+// Stored bytes are integrated over time into byte-days, so an object kept
+// for half a month costs half a month of storage. Storage wraps a backend
+// and records every operation made through it.
+package cost
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// Request classes. Providers price writes and listings (class A) higher
+// than reads (class B); deletes are usually free.
+const (
+	RequestWrite  = "write"
+	RequestRead   = "read"
+	RequestList   = "list"
+	RequestDelete = "delete"
+)
+
+// DefaultFlushInterval is how often usage is written to the state file
+// when Config.FlushInterval is zero.
+const DefaultFlushInterval = time.Minute
+
+// monthLayout formats the months reports are requested for.
+const monthLayout = "2006-01"
+
+// gib is the size of the GB prices are quoted per. Cloud providers bill
+// binary gigabytes.
+const gib = 1 << 30
+
+var (
+	// ErrInvalidConfig is returned when a cost configuration cannot be
+	// loaded or is malformed.
+	ErrInvalidConfig = errors.New("invalid cost configuration")
+
+	// ErrInvalidMonth is returned when a report is requested for a month
+	// that is malformed or has not started yet.
+	ErrInvalidMonth = errors.New("invalid report month")
+
+	// ErrStateCorrupt is returned when a persisted meter state cannot be
+	// decoded.
+	ErrStateCorrupt = errors.New("cost state is corrupt")
+)
+
+// Pricing is the price list of one backend, in Config.Currency.
+type Pricing struct {
+	// StorageGBMonth is the price of storing one GiB for a month.
+	StorageGBMonth float64 `yaml:"storageGBMonth" json:"storage_gb_month"`
+
+	// WritePer1000 is the price of 1000 puts, appends, composes and
+	// metadata updates.
+	WritePer1000 float64 `yaml:"writePer1000" json:"write_per_1000"`
+
+	// ReadPer1000 is the price of 1000 gets, metadata reads and existence
+	// checks.
+	ReadPer1000 float64 `yaml:"readPer1000" json:"read_per_1000"`
+
+	// ListPer1000 is the price of 1000 listings.
+	ListPer1000 float64 `yaml:"listPer1000" json:"list_per_1000"`
+
+	// DeletePer1000 is the price of 1000 deletes.
+	DeletePer1000 float64 `yaml:"deletePer1000" json:"delete_per_1000"`
+
+	// EgressGB is the price of reading one GiB out of the backend.
+	EgressGB float64 `yaml:"egressGB" json:"egress_gb"`
+}
+
+// Group attributes the keys under its prefixes to a team or cost center.
+type Group struct {
+	Name     string   `yaml:"name" json:"name"`
+	Prefixes []string `yaml:"prefixes" json:"prefixes"`
+}
+
+// Config configures metering and pricing.
+type Config struct {
+	// Currency labels the amounts in reports (default: "USD").
+	Currency string `yaml:"currency" json:"currency"`
+
+	// Pricing maps facade backend names to their price lists.
+	Pricing map[string]Pricing `yaml:"pricing" json:"pricing"`
+
+	// DefaultPricing prices backends missing from Pricing.
+	DefaultPricing Pricing `yaml:"defaultPricing" json:"default_pricing"`
+
+	// Groups attribute keys to teams by longest matching prefix. Keys
+	// matching no group are attributed to their top-level prefix, such as
+	// "logs/", or to "" when they have none.
+	Groups []Group `yaml:"groups" json:"groups"`
+
+	// StatePath is the file usage is persisted to. If empty, usage is kept
+	// in memory and lost on restart.
+	StatePath string `yaml:"statePath" json:"state_path"`
+
+	// FlushInterval is the minimum time between writes of the state file.
+	// Zero uses DefaultFlushInterval.
+	FlushInterval time.Duration `yaml:"flushInterval" json:"flush_interval"`
+}
+
+// LoadFile reads a cost configuration from a YAML or JSON file.
+func LoadFile(filename string) (*Config, error) {
+	data, err := os.ReadFile(filename) // #nosec G304 -- path supplied by the operator
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidConfig, filename, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// Validate checks that no price is negative and that groups are named and
+// do not claim the same prefix.
+func (c *Config) Validate() error {
+	if c.FlushInterval < 0 {
+		return fmt.Errorf("%w: negative flush interval", ErrInvalidConfig)
+	}
+	if err := c.DefaultPricing.validate(); err != nil {
+		return fmt.Errorf("%w: default pricing: %w", ErrInvalidConfig, err)
+	}
+	for backend, pricing := range c.Pricing {
+		if err := pricing.validate(); err != nil {
+			return fmt.Errorf("%w: pricing for backend %q: %w", ErrInvalidConfig, backend, err)
+		}
+	}
+	names := make(map[string]bool, len(c.Groups))
+	prefixes := make(map[string]string)
+	for _, group := range c.Groups {
+		if group.Name == "" {
+			return fmt.Errorf("%w: group without a name", ErrInvalidConfig)
+		}
+		if names[group.Name] {
+			return fmt.Errorf("%w: duplicate group %q", ErrInvalidConfig, group.Name)
+		}
+		names[group.Name] = true
+		for _, prefix := range group.Prefixes {
+			if other, ok := prefixes[prefix]; ok {
+				return fmt.Errorf("%w: prefix %q is in groups %q and %q", ErrInvalidConfig, prefix, other, group.Name)
+			}
+			prefixes[prefix] = group.Name
+		}
+	}
+	return nil
+}
+
+func (p *Pricing) validate() error {
+	for _, price := range []float64{p.StorageGBMonth, p.WritePer1000, p.ReadPer1000, p.ListPer1000, p.DeletePer1000, p.EgressGB} {
+		if price < 0 || math.IsNaN(price) || math.IsInf(price, 0) {
+			return fmt.Errorf("invalid price %v", price)
+		}
+	}
+	return nil
+}
+
+// pricing returns the price list of backend.
+func (c *Config) pricing(backend string) Pricing {
+	if pricing, ok := c.Pricing[backend]; ok {
+		return pricing
+	}
+	return c.DefaultPricing
+}
+
+// Group returns the group key is attributed to.
+func (c *Config) Group(key string) string {
+	name, length := "", -1
+	for _, group := range c.Groups {
+		for _, prefix := range group.Prefixes {
+			if strings.HasPrefix(key, prefix) && len(prefix) > length {
+				name, length = group.Name, len(prefix)
+			}
+		}
+	}
+	if length >= 0 {
+		return name
+	}
+	if i := strings.IndexByte(key, '/'); i >= 0 {
+		return key[:i+1]
+	}
+	return ""
+}
+
+// Usage is the metered activity of one group on one backend in a month.
+type Usage struct {
+	// Requests counts requests by class.
+	Requests map[string]int64 `json:"requests,omitempty"`
+
+	// EgressBytes is the number of bytes read out of the backend.
+	EgressBytes int64 `json:"egress_bytes,omitempty"`
+
+	// ByteDays is the stored bytes integrated over the days of the month.
+	ByteDays float64 `json:"byte_days,omitempty"`
+}
+
+// state is the persisted form of a Meter.
+type state struct {
+	// Accrued is the time stored bytes have been integrated up to.
+	Accrued time.Time `json:"accrued"`
+
+	// Stored holds the bytes currently stored, by backend and group.
+	Stored map[string]map[string]int64 `json:"stored"`
+
+	// Months holds usage by month, backend and group.
+	Months map[string]map[string]map[string]*Usage `json:"months"`
+}
+
+// Meter accumulates usage and prices it. It is safe for concurrent use.
+type Meter struct {
+	cfg *Config
+
+	mu    sync.Mutex
+	state state
+	saved time.Time
+	dirty bool
+
+	// now is replaced by tests.
+	now func() time.Time
+}
+
+// NewMeter creates a Meter for cfg, loading the usage persisted to
+// cfg.StatePath if it exists.
+func NewMeter(cfg *Config) (*Meter, error) {
+	m := &Meter{
+		cfg: cfg,
+		state: state{
+			Stored: make(map[string]map[string]int64),
+			Months: make(map[string]map[string]map[string]*Usage),
+		},
+		now: time.Now,
+	}
+	if cfg.StatePath != "" {
+		data, err := os.ReadFile(cfg.StatePath)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+		case err != nil:
+			return nil, fmt.Errorf("failed to read cost state: %w", err)
+		default:
+			if err := json.Unmarshal(data, &m.state); err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrStateCorrupt, err)
+			}
+			if m.state.Stored == nil {
+				m.state.Stored = make(map[string]map[string]int64)
+			}
+			if m.state.Months == nil {
+				m.state.Months = make(map[string]map[string]map[string]*Usage)
+			}
+		}
+	}
+	m.saved = m.now()
+	return m, nil
+}
+
+// Config returns the configuration of m.
+func (m *Meter) Config() *Config {
+	return m.cfg
+}
+
+// Tracks reports whether m knows the stored bytes of backend, from an
+// earlier Scan or from recorded writes.
+func (m *Meter) Tracks(backend string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.state.Stored[backend]
+	return ok
+}
+
+// Scan replaces the stored bytes of backend with a full listing of
+// storage.
+func (m *Meter) Scan(ctx context.Context, backend string, storage common.Storage) error {
+	stored := make(map[string]int64)
+	opts := &common.ListOptions{MaxResults: 1000}
+	for {
+		result, err := storage.ListWithOptions(ctx, opts)
+		if err != nil {
+			return fmt.Errorf("failed to list objects: %w", err)
+		}
+		for _, obj := range result.Objects {
+			if obj.Metadata != nil {
+				stored[m.cfg.Group(obj.Key)] += obj.Metadata.Size
+			}
+		}
+		if !result.Truncated || result.NextToken == "" {
+			break
+		}
+		opts.ContinueFrom = result.NextToken
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.accrueLocked(m.now())
+	m.state.Stored[backend] = stored
+	return m.changedLocked()
+}
+
+// Request records a request of class against key.
+func (m *Meter) Request(backend, key, class string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	usage := m.usageLocked(m.now(), backend, m.cfg.Group(key))
+	if usage.Requests == nil {
+		usage.Requests = make(map[string]int64)
+	}
+	usage.Requests[class]++
+	_ = m.changedLocked() // #nosec G104 -- metering must not fail requests; Flush reports write errors
+}
+
+// Egress records n bytes of key read out of backend.
+func (m *Meter) Egress(backend, key string, n int64) {
+	if n <= 0 {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.usageLocked(m.now(), backend, m.cfg.Group(key)).EgressBytes += n
+	_ = m.changedLocked() // #nosec G104 -- metering must not fail requests; Flush reports write errors
+}
+
+// Resize records that the bytes stored under key in backend changed by
+// delta.
+func (m *Meter) Resize(backend, key string, delta int64) {
+	if delta == 0 {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.accrueLocked(m.now())
+	stored, ok := m.state.Stored[backend]
+	if !ok {
+		stored = make(map[string]int64)
+		m.state.Stored[backend] = stored
+	}
+	group := m.cfg.Group(key)
+	stored[group] = max(stored[group]+delta, 0)
+	_ = m.changedLocked() // #nosec G104 -- metering must not fail requests; Flush reports write errors
+}
+
+// Flush writes the usage to the state file, if any.
+func (m *Meter) Flush() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.accrueLocked(m.now())
+	return m.saveLocked()
+}
+
+// Close flushes the usage to the state file.
+func (m *Meter) Close() error {
+	return m.Flush()
+}
+
+// usageLocked returns the usage of group on backend in the month of t.
+func (m *Meter) usageLocked(t time.Time, backend, group string) *Usage {
+	month := t.UTC().Format(monthLayout)
+	backends, ok := m.state.Months[month]
+	if !ok {
+		backends = make(map[string]map[string]*Usage)
+		m.state.Months[month] = backends
+	}
+	groups, ok := backends[backend]
+	if !ok {
+		groups = make(map[string]*Usage)
+		backends[backend] = groups
+	}
+	usage, ok := groups[group]
+	if !ok {
+		usage = &Usage{}
+		groups[group] = usage
+	}
+	return usage
+}
+
+// accrueLocked integrates the stored bytes from the last accrual up to
+// now, splitting the time at month boundaries.
+func (m *Meter) accrueLocked(now time.Time) {
+	from := m.state.Accrued
+	m.state.Accrued = now
+	if from.IsZero() || !now.After(from) {
+		return
+	}
+	for from.Before(now) {
+		start := monthStart(from)
+		until := start.AddDate(0, 1, 0)
+		if until.After(now) {
+			until = now
+		}
+		days := until.Sub(from).Hours() / 24
+		for backend, groups := range m.state.Stored {
+			for group, bytes := range groups {
+				if bytes > 0 {
+					m.usageLocked(from, backend, group).ByteDays += float64(bytes) * days
+				}
+			}
+		}
+		from = until
+	}
+}
+
+// changedLocked marks the state dirty and writes it when the flush
+// interval has passed.
+func (m *Meter) changedLocked() error {
+	m.dirty = true
+	interval := m.cfg.FlushInterval
+	if interval == 0 {
+		interval = DefaultFlushInterval
+	}
+	if m.now().Sub(m.saved) < interval {
+		return nil
+	}
+	return m.saveLocked()
+}
+
+// saveLocked writes the state to its file, if any, replacing the previous
+// copy atomically.
+func (m *Meter) saveLocked() error {
+	if m.cfg.StatePath == "" || !m.dirty {
+		return nil
+	}
+	m.saved = m.now()
+
+	data, err := json.Marshal(&m.state)
+	if err != nil {
+		return fmt.Errorf("failed to encode cost state: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(m.cfg.StatePath), ".cost-state-*")
+	if err != nil {
+		return fmt.Errorf("failed to write cost state: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cost state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cost state: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), m.cfg.StatePath); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cost state: %w", err)
+	}
+	m.dirty = false
+	return nil
+}
+
+// monthStart returns the first instant of the UTC month containing t.
+func monthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// Cost is an amount broken down by what it is charged for.
+type Cost struct {
+	Storage  float64 `json:"storage"`
+	Requests float64 `json:"requests"`
+	Egress   float64 `json:"egress"`
+	Total    float64 `json:"total"`
+}
+
+func (c *Cost) add(other Cost) {
+	c.Storage += other.Storage
+	c.Requests += other.Requests
+	c.Egress += other.Egress
+	c.Total += other.Total
+}
+
+// Line is the usage and cost of one group on one backend.
+type Line struct {
+	Backend     string           `json:"backend"`
+	Group       string           `json:"group"`
+	Requests    map[string]int64 `json:"requests,omitempty"`
+	EgressBytes int64            `json:"egress_bytes"`
+
+	// StoredBytes is the number of bytes currently stored. It is zero in
+	// reports for past months.
+	StoredBytes int64 `json:"stored_bytes"`
+
+	// GBMonths is the storage used in the month so far, in GiB-months.
+	GBMonths float64 `json:"gb_months"`
+
+	// Cost is the cost incurred in the month so far.
+	Cost Cost `json:"cost"`
+
+	// Projected estimates the cost of the whole month, assuming the
+	// current stored bytes are kept and requests and egress continue at
+	// their average rate. It equals Cost for past months.
+	Projected Cost `json:"projected"`
+}
+
+// GroupCost is the cost of one group across all backends.
+type GroupCost struct {
+	Group     string `json:"group"`
+	Cost      Cost   `json:"cost"`
+	Projected Cost   `json:"projected"`
+}
+
+// Report is the estimated cost of a month.
+type Report struct {
+	Month       string      `json:"month"`
+	Currency    string      `json:"currency"`
+	GeneratedAt time.Time   `json:"generated_at"`
+	Lines       []Line      `json:"lines"`
+	Groups      []GroupCost `json:"groups"`
+	Total       Cost        `json:"total"`
+	Projected   Cost        `json:"projected"`
+}
+
+// Report estimates the cost of month, formatted as "2006-01". An empty
+// month reports the current month.
+func (m *Meter) Report(month string) (*Report, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now().UTC()
+	current := monthStart(now)
+	start := current
+	if month != "" {
+		t, err := time.Parse(monthLayout, month)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q is not formatted as YYYY-MM", ErrInvalidMonth, month)
+		}
+		if t.After(current) {
+			return nil, fmt.Errorf("%w: %s has not started", ErrInvalidMonth, month)
+		}
+		start = t
+	}
+	m.accrueLocked(now)
+
+	end := start.AddDate(0, 1, 0)
+	monthDays := end.Sub(start).Hours() / 24
+	isCurrent := start.Equal(current)
+	elapsed := monthDays
+	if isCurrent {
+		elapsed = now.Sub(start).Hours() / 24
+	}
+
+	currency := m.cfg.Currency
+	if currency == "" {
+		currency = "USD"
+	}
+	report := &Report{
+		Month:       start.Format(monthLayout),
+		Currency:    currency,
+		GeneratedAt: now,
+		Lines:       []Line{},
+		Groups:      []GroupCost{},
+	}
+
+	// Collect the backend and group pairs with usage in the month, and for
+	// the current month those storing data.
+	lines := make(map[[2]string]*Line)
+	line := func(backend, group string) *Line {
+		k := [2]string{backend, group}
+		if l, ok := lines[k]; ok {
+			return l
+		}
+		l := &Line{Backend: backend, Group: group}
+		lines[k] = l
+		return l
+	}
+	for backend, groups := range m.state.Months[report.Month] {
+		for group, usage := range groups {
+			l := line(backend, group)
+			l.EgressBytes = usage.EgressBytes
+			l.GBMonths = usage.ByteDays / monthDays / gib
+			if len(usage.Requests) > 0 {
+				l.Requests = make(map[string]int64, len(usage.Requests))
+				for class, n := range usage.Requests {
+					l.Requests[class] = n
+				}
+			}
+		}
+	}
+	if isCurrent {
+		for backend, groups := range m.state.Stored {
+			for group, bytes := range groups {
+				if bytes > 0 {
+					line(backend, group).StoredBytes = bytes
+				}
+			}
+		}
+	}
+
+	byGroup := make(map[string]*GroupCost)
+	for _, l := range lines {
+		pricing := m.cfg.pricing(l.Backend)
+		l.Cost = pricing.cost(l.GBMonths, l.Requests, l.EgressBytes)
+		l.Projected = l.Cost
+		if isCurrent && elapsed > 0 {
+			scale := monthDays / elapsed
+			remaining := float64(l.StoredBytes) * (monthDays - elapsed) / monthDays / gib
+			l.Projected.Storage = l.Cost.Storage + remaining*pricing.StorageGBMonth
+			l.Projected.Requests = l.Cost.Requests * scale
+			l.Projected.Egress = l.Cost.Egress * scale
+			l.Projected.Total = l.Projected.Storage + l.Projected.Requests + l.Projected.Egress
+		}
+		report.Lines = append(report.Lines, *l)
+		report.Total.add(l.Cost)
+		report.Projected.add(l.Projected)
+
+		g, ok := byGroup[l.Group]
+		if !ok {
+			g = &GroupCost{Group: l.Group}
+			byGroup[l.Group] = g
+		}
+		g.Cost.add(l.Cost)
+		g.Projected.add(l.Projected)
+	}
+	sort.Slice(report.Lines, func(i, j int) bool {
+		if report.Lines[i].Backend != report.Lines[j].Backend {
+			return report.Lines[i].Backend < report.Lines[j].Backend
+		}
+		return report.Lines[i].Group < report.Lines[j].Group
+	})
+	for _, g := range byGroup {
+		report.Groups = append(report.Groups, *g)
+	}
+	sort.Slice(report.Groups, func(i, j int) bool { return report.Groups[i].Group < report.Groups[j].Group })
+	return report, nil
+}
+
+// cost prices usage.
+func (p *Pricing) cost(gbMonths float64, requests map[string]int64, egressBytes int64) Cost {
+	c := Cost{
+		Storage: gbMonths * p.StorageGBMonth,
+		Requests: (float64(requests[RequestWrite])*p.WritePer1000 +
+			float64(requests[RequestRead])*p.ReadPer1000 +
+			float64(requests[RequestList])*p.ListPer1000 +
+			float64(requests[RequestDelete])*p.DeletePer1000) / 1000,
+		Egress: float64(egressBytes) / gib * p.EgressGB,
+	}
+	c.Total = c.Storage + c.Requests + c.Egress
+	return c
+}
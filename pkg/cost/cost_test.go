@@ -0,0 +1,276 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package cost
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+// clock is a settable time source.
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time { return c.t }
+
+func newTestMeter(t *testing.T, cfg *Config, c *clock) *Meter {
+	t.Helper()
+	m, err := NewMeter(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.now = c.now
+	return m
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cost.yaml")
+	data := `currency: EUR
+pricing:
+  s3:
+    storageGBMonth: 0.023
+    writePer1000: 0.005
+defaultPricing:
+  storageGBMonth: 0.01
+groups:
+  - name: analytics
+    prefixes: [analytics/, reports/]
+flushInterval: 30s
+`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if cfg.pricing("s3").StorageGBMonth != 0.023 || cfg.pricing("local").StorageGBMonth != 0.01 || cfg.FlushInterval != 30*time.Second {
+		t.Errorf("LoadFile() = %+v", cfg)
+	}
+
+	for _, bad := range []*Config{
+		{DefaultPricing: Pricing{EgressGB: -1}},
+		{Groups: []Group{{Prefixes: []string{"a/"}}}},
+		{Groups: []Group{{Name: "a", Prefixes: []string{"x/"}}, {Name: "b", Prefixes: []string{"x/"}}}},
+	} {
+		if err := bad.Validate(); !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("Validate(%+v) error = %v, want ErrInvalidConfig", bad, err)
+		}
+	}
+}
+
+func TestGroup(t *testing.T) {
+	cfg := &Config{Groups: []Group{
+		{Name: "analytics", Prefixes: []string{"analytics/", "shared/reports/"}},
+		{Name: "platform", Prefixes: []string{"shared/"}},
+	}}
+	tests := map[string]string{
+		"analytics/q1.parquet":   "analytics",
+		"shared/reports/q1.pdf":  "analytics",
+		"shared/config.yaml":     "platform",
+		"logs/2025/11/05.log":    "logs/",
+		"readme.txt":             "",
+		"shared-other/file":      "shared-other/",
+		"analytics-tmp/file.csv": "analytics-tmp/",
+	}
+	for key, want := range tests {
+		if got := cfg.Group(key); got != want {
+			t.Errorf("Group(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestReport(t *testing.T) {
+	c := &clock{t: time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC)}
+	m := newTestMeter(t, &Config{
+		Pricing: map[string]Pricing{"s3": {
+			StorageGBMonth: 30,
+			WritePer1000:   5,
+			ReadPer1000:    1,
+			EgressGB:       2,
+		}},
+	}, c)
+
+	// One GiB stored under logs/ for the first ten days of November.
+	m.Resize("s3", "logs/a", gib)
+	for range 1000 {
+		m.Request("s3", "logs/a", RequestWrite)
+	}
+	m.Egress("s3", "logs/a", gib/2)
+	c.t = c.t.AddDate(0, 0, 10)
+
+	report, err := m.Report("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Month != "2025-11" || report.Currency != "USD" || len(report.Lines) != 1 {
+		t.Fatalf("Report() = %+v", report)
+	}
+	line := report.Lines[0]
+	if line.Backend != "s3" || line.Group != "logs/" || line.StoredBytes != gib || line.Requests[RequestWrite] != 1000 {
+		t.Errorf("line = %+v", line)
+	}
+	// 10 of 30 days of 1 GiB at 30/GiB-month, 1000 writes at 5/1000 and
+	// 0.5 GiB egress at 2/GiB.
+	if !near(line.Cost.Storage, 10) || !near(line.Cost.Requests, 5) || !near(line.Cost.Egress, 1) || !near(report.Total.Total, 16) {
+		t.Errorf("cost = %+v", line.Cost)
+	}
+	// The GiB stays stored for the rest of the month; requests and egress
+	// continue at the same rate.
+	if !near(line.Projected.Storage, 30) || !near(line.Projected.Requests, 15) || !near(line.Projected.Egress, 3) {
+		t.Errorf("projected = %+v", line.Projected)
+	}
+	if len(report.Groups) != 1 || !near(report.Groups[0].Projected.Total, 48) {
+		t.Errorf("groups = %+v", report.Groups)
+	}
+
+	// Deleting the object stops storage charges; the month keeps what
+	// accrued.
+	m.Resize("s3", "logs/a", -gib)
+	c.t = time.Date(2025, 12, 15, 0, 0, 0, 0, time.UTC)
+	november, err := m.Report("2025-11")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !near(november.Total.Storage, 10) || november.Total != november.Projected || november.Lines[0].StoredBytes != 0 {
+		t.Errorf("past month report = %+v", november)
+	}
+	december, _ := m.Report("")
+	if december.Total.Total != 0 {
+		t.Errorf("current month total = %+v", december.Total)
+	}
+
+	for _, month := range []string{"november", "2026-01"} {
+		if _, err := m.Report(month); !errors.Is(err, ErrInvalidMonth) {
+			t.Errorf("Report(%q) error = %v, want ErrInvalidMonth", month, err)
+		}
+	}
+}
+
+func TestStoredBytesAccrueAcrossMonths(t *testing.T) {
+	c := &clock{t: time.Date(2025, 10, 31, 12, 0, 0, 0, time.UTC)}
+	m := newTestMeter(t, &Config{DefaultPricing: Pricing{StorageGBMonth: 1}}, c)
+	m.Resize("local", "data/x", gib)
+	c.t = time.Date(2025, 11, 1, 12, 0, 0, 0, time.UTC)
+
+	october, _ := m.Report("2025-10")
+	november, _ := m.Report("2025-11")
+	if !near(october.Lines[0].GBMonths, 0.5/31) || !near(november.Lines[0].GBMonths, 0.5/30) {
+		t.Errorf("GBMonths = %v, %v", october.Lines[0].GBMonths, november.Lines[0].GBMonths)
+	}
+}
+
+func TestStatePersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cost.json")
+	cfg := &Config{StatePath: path, FlushInterval: time.Hour}
+	c := &clock{t: time.Now()}
+	m := newTestMeter(t, cfg, c)
+	m.Resize("local", "a/b", 100)
+	m.Request("local", "a/b", RequestRead)
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("state written before the flush interval: %v", err)
+	}
+	if err := m.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened := newTestMeter(t, cfg, c)
+	if !reopened.Tracks("local") || reopened.Tracks("s3") {
+		t.Error("reopened meter lost the tracked backends")
+	}
+	report, _ := reopened.Report("")
+	if len(report.Lines) != 1 || report.Lines[0].StoredBytes != 100 || report.Lines[0].Requests[RequestRead] != 1 {
+		t.Errorf("reopened report = %+v", report.Lines)
+	}
+
+	if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewMeter(cfg); !errors.Is(err, ErrStateCorrupt) {
+		t.Errorf("NewMeter() error = %v, want ErrStateCorrupt", err)
+	}
+}
+
+func TestStorage(t *testing.T) {
+	ctx := context.Background()
+	backend := memory.New()
+	if err := backend.Put("team/existing", strings.NewReader("1234567890")); err != nil {
+		t.Fatal(err)
+	}
+	c := &clock{t: time.Now()}
+	m := newTestMeter(t, &Config{}, c)
+	if err := m.Scan(ctx, "mem", backend); err != nil {
+		t.Fatal(err)
+	}
+	s := NewStorage(backend, "mem", m)
+
+	if err := s.Put("team/a", strings.NewReader("12345")); err != nil {
+		t.Fatal(err)
+	}
+	// Overwriting replaces the old size.
+	if err := s.Put("team/a", strings.NewReader("123")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Append(ctx, "team/a", strings.NewReader("45")); err != nil {
+		t.Fatal(err)
+	}
+	rc, err := s.GetWithContext(ctx, "team/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = io.ReadAll(rc)
+	_ = rc.Close()
+	rc, err = s.GetRange(ctx, "team/existing", 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = io.ReadAll(rc)
+	_ = rc.Close()
+	if _, err := s.List("team/"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete("team/existing"); err != nil {
+		t.Fatal(err)
+	}
+
+	report, _ := m.Report("")
+	if len(report.Lines) != 1 {
+		t.Fatalf("lines = %+v", report.Lines)
+	}
+	line := report.Lines[0]
+	if line.StoredBytes != 5 {
+		t.Errorf("StoredBytes = %d, want 5", line.StoredBytes)
+	}
+	want := map[string]int64{RequestWrite: 3, RequestRead: 2, RequestList: 1, RequestDelete: 1}
+	for class, n := range want {
+		if line.Requests[class] != n {
+			t.Errorf("requests[%s] = %d, want %d", class, line.Requests[class], n)
+		}
+	}
+	if line.EgressBytes < 8 {
+		t.Errorf("EgressBytes = %d, want at least 8", line.EgressBytes)
+	}
+}
@@ -0,0 +1,268 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package cost
+
+import (
+	"context"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// Storage wraps the facade backend named name and records the requests,
+// egress and stored bytes of every operation made through it in a Meter.
+// The metadata reads it makes to size overwritten and deleted objects are
+// not metered.
+type Storage struct {
+	common.Storage
+	name  string
+	meter *Meter
+}
+
+// NewStorage returns underlying, the facade backend named name, wrapped to
+// record its usage in meter.
+func NewStorage(underlying common.Storage, name string, meter *Meter) *Storage {
+	return &Storage{Storage: underlying, name: name, meter: meter}
+}
+
+// Meter returns the meter usage is recorded in.
+func (s *Storage) Meter() *Meter {
+	return s.meter
+}
+
+// Underlying returns the wrapped backend.
+func (s *Storage) Underlying() common.Storage {
+	return s.Storage
+}
+
+// Put stores an object and records the write and the bytes it adds.
+func (s *Storage) Put(key string, data io.Reader) error {
+	return s.PutWithContext(context.Background(), key, data)
+}
+
+// PutWithContext stores an object and records the write and the bytes it
+// adds.
+func (s *Storage) PutWithContext(ctx context.Context, key string, data io.Reader) error {
+	return s.put(ctx, key, data, func(r io.Reader) error {
+		return s.Storage.PutWithContext(ctx, key, r)
+	})
+}
+
+// PutWithMetadata stores an object with metadata and records the write and
+// the bytes it adds.
+func (s *Storage) PutWithMetadata(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) error {
+	return s.put(ctx, key, data, func(r io.Reader) error {
+		return s.Storage.PutWithMetadata(ctx, key, r, metadata)
+	})
+}
+
+// PutWithInfo stores an object with metadata, records the write and the
+// bytes it adds, and returns what was stored.
+func (s *Storage) PutWithInfo(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) (info *common.ObjectInfo, err error) {
+	err = s.put(ctx, key, data, func(r io.Reader) error {
+		info, err = common.PutWithInfo(ctx, s.Storage, key, r, metadata)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+func (s *Storage) put(ctx context.Context, key string, data io.Reader, put func(io.Reader) error) error {
+	old := s.size(ctx, key)
+	counter := &countingReader{Reader: data}
+	s.meter.Request(s.name, key, RequestWrite)
+	if err := put(counter); err != nil {
+		return err
+	}
+	s.meter.Resize(s.name, key, counter.n.Load()-old)
+	return nil
+}
+
+// Get retrieves an object, recording the read and the bytes returned.
+func (s *Storage) Get(key string) (io.ReadCloser, error) {
+	return s.GetWithContext(context.Background(), key)
+}
+
+// GetWithContext retrieves an object, recording the read and the bytes
+// returned.
+func (s *Storage) GetWithContext(ctx context.Context, key string) (io.ReadCloser, error) {
+	s.meter.Request(s.name, key, RequestRead)
+	rc, err := s.Storage.GetWithContext(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return s.egress(key, rc), nil
+}
+
+// GetRange reads a byte range from the wrapped backend, falling back to
+// discarding the leading bytes of a full read when it cannot read ranges.
+// Only the bytes read from the backend are counted as egress.
+func (s *Storage) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	s.meter.Request(s.name, key, RequestRead)
+	if rr, ok := s.Storage.(common.RangeReader); ok {
+		rc, err := rr.GetRange(ctx, key, offset, length)
+		if err != nil {
+			return nil, err
+		}
+		return s.egress(key, rc), nil
+	}
+	rc, err := s.Storage.GetWithContext(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return common.SliceRange(s.egress(key, rc), offset, length)
+}
+
+// GetAsOf reads an earlier version of an object, recording the read and
+// the bytes returned.
+func (s *Storage) GetAsOf(ctx context.Context, key string, at time.Time) (io.ReadCloser, *common.Metadata, error) {
+	s.meter.Request(s.name, key, RequestRead)
+	rc, metadata, err := common.GetAsOf(ctx, s.Storage, key, at)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.egress(key, rc), metadata, nil
+}
+
+// GetMetadata retrieves an object's metadata and records the read.
+func (s *Storage) GetMetadata(ctx context.Context, key string) (*common.Metadata, error) {
+	s.meter.Request(s.name, key, RequestRead)
+	return s.Storage.GetMetadata(ctx, key)
+}
+
+// UpdateMetadata updates an object's metadata and records the write.
+func (s *Storage) UpdateMetadata(ctx context.Context, key string, metadata *common.Metadata) error {
+	s.meter.Request(s.name, key, RequestWrite)
+	return s.Storage.UpdateMetadata(ctx, key, metadata)
+}
+
+// Exists checks whether an object exists and records the read.
+func (s *Storage) Exists(ctx context.Context, key string) (bool, error) {
+	s.meter.Request(s.name, key, RequestRead)
+	return s.Storage.Exists(ctx, key)
+}
+
+// Delete removes an object and records the delete and the bytes it frees.
+func (s *Storage) Delete(key string) error {
+	return s.DeleteWithContext(context.Background(), key)
+}
+
+// DeleteWithContext removes an object and records the delete and the bytes
+// it frees.
+func (s *Storage) DeleteWithContext(ctx context.Context, key string) error {
+	old := s.size(ctx, key)
+	s.meter.Request(s.name, key, RequestDelete)
+	if err := s.Storage.DeleteWithContext(ctx, key); err != nil {
+		return err
+	}
+	s.meter.Resize(s.name, key, -old)
+	return nil
+}
+
+// List lists keys under prefix and records the listing.
+func (s *Storage) List(prefix string) ([]string, error) {
+	return s.ListWithContext(context.Background(), prefix)
+}
+
+// ListWithContext lists keys under prefix and records the listing.
+func (s *Storage) ListWithContext(ctx context.Context, prefix string) ([]string, error) {
+	s.meter.Request(s.name, prefix, RequestList)
+	return s.Storage.ListWithContext(ctx, prefix)
+}
+
+// ListWithOptions lists a page of objects and records the listing.
+func (s *Storage) ListWithOptions(ctx context.Context, opts *common.ListOptions) (*common.ListResult, error) {
+	prefix := ""
+	if opts != nil {
+		prefix = opts.Prefix
+	}
+	s.meter.Request(s.name, prefix, RequestList)
+	return s.Storage.ListWithOptions(ctx, opts)
+}
+
+// Append adds data to the end of an object and records the write and the
+// bytes it adds.
+func (s *Storage) Append(ctx context.Context, key string, data io.Reader) error {
+	old := s.size(ctx, key)
+	s.meter.Request(s.name, key, RequestWrite)
+	if err := common.Append(ctx, s.Storage, key, data); err != nil {
+		return err
+	}
+	s.meter.Resize(s.name, key, s.size(ctx, key)-old)
+	return nil
+}
+
+// Compose concatenates srcKeys into destKey and records the write and the
+// bytes it adds.
+func (s *Storage) Compose(ctx context.Context, destKey string, srcKeys ...string) error {
+	old := s.size(ctx, destKey)
+	s.meter.Request(s.name, destKey, RequestWrite)
+	if err := common.Compose(ctx, s.Storage, destKey, srcKeys...); err != nil {
+		return err
+	}
+	s.meter.Resize(s.name, destKey, s.size(ctx, destKey)-old)
+	return nil
+}
+
+// size returns the stored size of key, or 0 when it does not exist.
+func (s *Storage) size(ctx context.Context, key string) int64 {
+	metadata, err := s.Storage.GetMetadata(ctx, key)
+	if err != nil || metadata == nil {
+		return 0
+	}
+	return metadata.Size
+}
+
+// egress wraps rc to record the bytes read from it.
+func (s *Storage) egress(key string, rc io.ReadCloser) io.ReadCloser {
+	return &egressReader{ReadCloser: rc, report: func(n int64) { s.meter.Egress(s.name, key, n) }}
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	io.Reader
+	n atomic.Int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.n.Add(int64(n))
+	return n, err
+}
+
+// egressReader reports the bytes read through it when closed.
+type egressReader struct {
+	io.ReadCloser
+	n      int64
+	report func(int64)
+	closed bool
+}
+
+func (r *egressReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+func (r *egressReader) Close() error {
+	if !r.closed {
+		r.closed = true
+		r.report(r.n)
+	}
+	return r.ReadCloser.Close()
+}
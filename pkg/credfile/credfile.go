@@ -0,0 +1,193 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package credfile reads backend credentials from a file and re-reads it
+// when it changes, so credentials rotated on disk (for example a mounted
+// Kubernetes secret) are picked up without restarting the process.
+//
+// A file holds either a JSON object or KEY=VALUE lines. Blank lines, lines
+// starting with # or ; and [section] headers are ignored, so an AWS shared
+// credentials file with a single profile can be used as is.
+package credfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// DefaultCheckInterval is how often a File checks whether it changed on
+// disk.
+const DefaultCheckInterval = time.Second
+
+// ErrInvalidFile is returned for a credentials file that cannot be parsed.
+var ErrInvalidFile = fmt.Errorf("%w: invalid credentials file", common.ErrInvalidArgument)
+
+// File is a credentials file that is re-read when its modification time or
+// size changes. It is safe for concurrent use.
+type File struct {
+	path          string
+	checkInterval time.Duration
+
+	mu        sync.Mutex
+	checkedAt time.Time
+	modTime   time.Time
+	size      int64
+	data      []byte
+	values    map[string]string
+	version   uint64
+}
+
+// Open reads the credentials file at path.
+func Open(path string) (*File, error) {
+	if path == "" {
+		return nil, fmt.Errorf("%w: no path", ErrInvalidFile)
+	}
+	f := &File{path: path, checkInterval: DefaultCheckInterval}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.loadLocked(time.Now()); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// SetCheckInterval sets how often the file checks whether it changed on
+// disk. Zero checks on every Refresh.
+func (f *File) SetCheckInterval(d time.Duration) {
+	f.mu.Lock()
+	f.checkInterval = d
+	f.mu.Unlock()
+}
+
+// Path returns the path of the file.
+func (f *File) Path() string {
+	return f.path
+}
+
+// Refresh re-reads the file if it changed on disk since it was last read,
+// checking at most once per check interval. A file that cannot be
+// read or parsed keeps the previous credentials and returns the error, so
+// a rotation caught halfway through is retried on the next check.
+func (f *File) Refresh() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(f.checkedAt) < f.checkInterval {
+		return nil
+	}
+	f.checkedAt = now
+
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return err
+	}
+	if info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return nil
+	}
+	return f.loadLocked(now)
+}
+
+// loadLocked reads and parses the file. The caller must hold f.mu.
+func (f *File) loadLocked(now time.Time) error {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return err
+	}
+	values, err := Parse(data)
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, f.path)
+	}
+
+	f.checkedAt = now
+	f.modTime, f.size = info.ModTime(), info.Size()
+	f.data, f.values = data, values
+	f.version++
+	return nil
+}
+
+// Version returns a number that increases every time the file is re-read
+// with new content.
+func (f *File) Version() uint64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.version
+}
+
+// Data returns the raw content of the file as last read.
+func (f *File) Data() []byte {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.data
+}
+
+// Lookup returns the value of the first of keys present in the file,
+// matched case-insensitively, or "" if none is.
+func (f *File) Lookup(keys ...string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, key := range keys {
+		if v, ok := f.values[strings.ToLower(key)]; ok {
+			return v
+		}
+	}
+	return ""
+}
+
+// Parse parses a JSON object or KEY=VALUE lines into values keyed by the
+// lower-cased key. Non-string JSON values are kept in their JSON encoding.
+func Parse(data []byte) (map[string]string, error) {
+	trimmed := bytes.TrimSpace(data)
+	values := make(map[string]string)
+
+	if bytes.HasPrefix(trimmed, []byte("{")) {
+		var raw map[string]json.RawMessage
+		if err := json.Unmarshal(trimmed, &raw); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+		}
+		for k, v := range raw {
+			var s string
+			if err := json.Unmarshal(v, &s); err != nil {
+				s = string(v)
+			}
+			values[strings.ToLower(k)] = s
+		}
+		return values, nil
+	}
+
+	for i, line := range strings.Split(string(trimmed), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == '#' || line[0] == ';' || line[0] == '[' {
+			continue
+		}
+		k, v, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("%w: line %d is not KEY=VALUE", ErrInvalidFile, i+1)
+		}
+		k = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(k), "export "))
+		v = strings.Trim(strings.TrimSpace(v), `"'`)
+		values[strings.ToLower(k)] = v
+	}
+	return values, nil
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package credfile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want map[string]string
+	}{
+		{
+			name: "json",
+			data: `{"accessKey": "AK", "Port": 443}`,
+			want: map[string]string{"accesskey": "AK", "port": "443"},
+		},
+		{
+			name: "env",
+			data: "# rotated daily\nexport ACCESS_KEY=\"AK\"\nSECRET_KEY='SK'\n\n",
+			want: map[string]string{"access_key": "AK", "secret_key": "SK"},
+		},
+		{
+			name: "aws shared credentials",
+			data: "[default]\n; comment\naws_access_key_id = AK\naws_secret_access_key = SK\n",
+			want: map[string]string{"aws_access_key_id": "AK", "aws_secret_access_key": "SK"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Parse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	for _, data := range []string{`{"accessKey":`, "accessKey"} {
+		if _, err := Parse([]byte(data)); !errors.Is(err, ErrInvalidFile) || !errors.Is(err, common.ErrInvalidArgument) {
+			t.Fatalf("Parse(%q) error = %v, want ErrInvalidFile", data, err)
+		}
+	}
+}
+
+func TestFile_Refresh(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials")
+	write := func(data string, mtime time.Time) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	start := time.Now().Add(-time.Hour)
+	write("ACCESS_KEY=one", start)
+
+	f, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	f.SetCheckInterval(0)
+	if got := f.Lookup("accessKey", "access_key"); got != "one" {
+		t.Fatalf("Lookup() = %q, want one", got)
+	}
+	if got := f.Lookup("missing"); got != "" {
+		t.Fatalf("Lookup(missing) = %q, want empty", got)
+	}
+	version := f.Version()
+
+	if err := f.Refresh(); err != nil || f.Version() != version {
+		t.Fatalf("Refresh() of an unchanged file = %v, version %d, want no reload", err, f.Version())
+	}
+
+	write("ACCESS_KEY=two", start.Add(time.Minute))
+	if err := f.Refresh(); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if got := f.Lookup("ACCESS_KEY"); got != "two" || f.Version() == version {
+		t.Fatalf("after rotation Lookup() = %q, version %d, want two and a new version", got, f.Version())
+	}
+	if string(f.Data()) != "ACCESS_KEY=two" {
+		t.Fatalf("Data() = %q", f.Data())
+	}
+
+	version = f.Version()
+	write("not a credentials file", start.Add(2*time.Minute))
+	if err := f.Refresh(); !errors.Is(err, ErrInvalidFile) {
+		t.Fatalf("Refresh() of a broken file error = %v, want ErrInvalidFile", err)
+	}
+	if got := f.Lookup("ACCESS_KEY"); got != "two" || f.Version() != version {
+		t.Fatalf("after a failed reload Lookup() = %q, want the previous value", got)
+	}
+
+	f.SetCheckInterval(time.Hour)
+	write("ACCESS_KEY=three", start.Add(3*time.Minute))
+	if err := f.Refresh(); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if got := f.Lookup("ACCESS_KEY"); got != "two" {
+		t.Fatalf("Lookup() within the check interval = %q, want two", got)
+	}
+}
+
+func TestOpen_Errors(t *testing.T) {
+	if _, err := Open(""); !errors.Is(err, ErrInvalidFile) {
+		t.Fatalf("Open(\"\") error = %v, want ErrInvalidFile", err)
+	}
+	if _, err := Open(filepath.Join(t.TempDir(), "missing")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Open(missing) error = %v, want os.ErrNotExist", err)
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package dedup
+
+import (
+	"io"
+	"math/bits"
+)
+
+// gear is the table of pseudo-random values driving the rolling hash. It is
+// generated from a fixed seed so chunk boundaries are stable across
+// processes and releases.
+var gear = func() (t [256]uint64) {
+	x := uint64(0x9e3779b97f4a7c15)
+	for i := range t {
+		// splitmix64
+		x += 0x9e3779b97f4a7c15
+		z := x
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		t[i] = z ^ (z >> 31)
+	}
+	return t
+}()
+
+// chunker splits a stream into content-defined chunks using a gear rolling
+// hash. Boundaries depend only on the surrounding bytes, so an insertion or
+// deletion only changes the chunks around the edit and the rest of a
+// near-identical object still deduplicates.
+type chunker struct {
+	r       io.Reader
+	buf     []byte
+	n       int
+	eof     bool
+	minSize int
+	maxSize int
+	shift   uint
+}
+
+// newChunker creates a chunker over r. Once minSize bytes have been
+// consumed a boundary is cut with probability 1/avgSize per byte (avgSize is
+// rounded down to a power of two); chunks never exceed maxSize.
+func newChunker(r io.Reader, minSize, avgSize, maxSize int) *chunker {
+	return &chunker{
+		r:       r,
+		buf:     make([]byte, maxSize),
+		minSize: minSize,
+		maxSize: maxSize,
+		shift:   uint(64 - (bits.Len(uint(avgSize)) - 1)),
+	}
+}
+
+// Next returns the next chunk, or io.EOF once the stream is exhausted.
+func (c *chunker) Next() ([]byte, error) {
+	for !c.eof && c.n < c.maxSize {
+		m, err := c.r.Read(c.buf[c.n:])
+		c.n += m
+		if err == io.EOF {
+			c.eof = true
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	if c.n == 0 {
+		return nil, io.EOF
+	}
+
+	cut := c.boundary(c.buf[:c.n])
+	chunk := make([]byte, cut)
+	copy(chunk, c.buf[:cut])
+	c.n = copy(c.buf, c.buf[cut:c.n])
+	return chunk, nil
+}
+
+// boundary returns the length of the first chunk in data.
+func (c *chunker) boundary(data []byte) int {
+	if len(data) <= c.minSize {
+		return len(data)
+	}
+	var h uint64
+	for i := c.minSize; i < len(data); i++ {
+		h = (h << 1) + gear[data[i]]
+		if h>>c.shift == 0 {
+			return i + 1
+		}
+	}
+	return len(data)
+}
@@ -0,0 +1,791 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package dedup provides a content-addressable storage (CAS) layer that
+// deduplicates identical data across keys.
+//
+// Objects written through a Store are split into content-defined chunks.
+// Each chunk is stored once under its SHA-256 hash and the object key holds
+// a small JSON manifest listing its chunks. A reference-counted index tracks
+// how many manifests use each chunk, and chunks are deleted when their last
+// reference goes away. Objects that were written without the CAS layer are
+// read and deleted as-is, so dedup can be enabled on an existing backend.
+//
+// The index is cached in memory and persisted to the underlying storage
+// after every write, so a backend should only have one writer process at a
+// time when the CAS layer is in use.
+package dedup
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+const (
+	// Prefix is the key prefix reserved for chunks and the index. Keys under
+	// it are hidden from listings.
+	Prefix = ".cas/"
+
+	chunkPrefix = Prefix + "chunks/"
+	indexKey    = Prefix + "index.json"
+
+	// Metadata fields recorded on manifest objects.
+	metaManifest = "cas_manifest"
+	metaSize     = "cas_size"
+
+	manifestVersion = "1"
+
+	// DefaultMinChunkSize is the smallest chunk cut by the chunker (16 KiB).
+	DefaultMinChunkSize = 16 * 1024
+
+	// DefaultAvgChunkSize is the target average chunk size (64 KiB).
+	DefaultAvgChunkSize = 64 * 1024
+
+	// DefaultMaxChunkSize is the largest chunk cut by the chunker (256 KiB).
+	DefaultMaxChunkSize = 256 * 1024
+)
+
+var (
+	// ErrChunkCorrupt is returned when a chunk read back from the underlying
+	// storage does not match its hash.
+	ErrChunkCorrupt = errors.New("cas chunk does not match its hash")
+
+	// ErrInvalidManifest is returned when a manifest object cannot be decoded.
+	ErrInvalidManifest = errors.New("invalid cas manifest")
+)
+
+// Options configures the chunker of a Store.
+type Options struct {
+	// MinChunkSize is the smallest chunk size in bytes. Zero uses
+	// DefaultMinChunkSize.
+	MinChunkSize int
+
+	// AvgChunkSize is the target average chunk size in bytes, rounded down
+	// to a power of two. Zero uses DefaultAvgChunkSize.
+	AvgChunkSize int
+
+	// MaxChunkSize is the largest chunk size in bytes. Zero uses
+	// DefaultMaxChunkSize.
+	MaxChunkSize int
+}
+
+// Stats reports how effective deduplication is for a Store.
+type Stats struct {
+	// Objects is the number of objects stored as manifests.
+	Objects int64 `json:"objects"`
+
+	// LogicalBytes is the total size of those objects as seen by callers.
+	LogicalBytes int64 `json:"logical_bytes"`
+
+	// Chunks is the number of unique chunks stored.
+	Chunks int64 `json:"chunks"`
+
+	// StoredBytes is the total size of the unique chunks.
+	StoredBytes int64 `json:"stored_bytes"`
+
+	// SavedBytes is LogicalBytes minus StoredBytes.
+	SavedBytes int64 `json:"saved_bytes"`
+
+	// Ratio is LogicalBytes divided by StoredBytes (1 when nothing is stored).
+	Ratio float64 `json:"ratio"`
+}
+
+// manifest lists the chunks that make up an object.
+type manifest struct {
+	Version string      `json:"version"`
+	Size    int64       `json:"size"`
+	Chunks  []chunkInfo `json:"chunks"`
+}
+
+// chunkInfo identifies one chunk of a manifest.
+type chunkInfo struct {
+	Hash string `json:"hash"`
+	Size int64  `json:"size"`
+}
+
+// chunkRef is the index entry for a stored chunk.
+type chunkRef struct {
+	Size int64 `json:"size"`
+	Refs int64 `json:"refs"`
+}
+
+// index is the persisted reference count table.
+type index struct {
+	Objects      int64                `json:"objects"`
+	LogicalBytes int64                `json:"logical_bytes"`
+	Chunks       map[string]*chunkRef `json:"chunks"`
+}
+
+// Store wraps a Storage with content-addressable deduplication. It
+// implements common.Storage and is safe for concurrent use.
+type Store struct {
+	underlying common.Storage
+	minSize    int
+	avgSize    int
+	maxSize    int
+
+	// keys serializes writes and deletes of the same key, so the manifest
+	// an overwrite or delete replaces is released exactly once.
+	keys keyLocks
+
+	mu      sync.Mutex
+	idx     *index
+	pending map[string]*pendingChunk // chunks being written, by hash
+}
+
+// pendingChunk is a chunk write in flight. done is closed once the write
+// finished; the chunk is stored unless err is set.
+type pendingChunk struct {
+	done chan struct{}
+	err  error
+}
+
+// keyLocks is a set of mutexes by key, created on first use and dropped
+// when no goroutine holds or waits for them.
+type keyLocks struct {
+	mu    sync.Mutex
+	locks map[string]*keyLock
+}
+
+// keyLock is the mutex of one key and the number of goroutines using it.
+type keyLock struct {
+	sync.Mutex
+	users int
+}
+
+// lock locks key and returns the function that unlocks it.
+func (l *keyLocks) lock(key string) func() {
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = make(map[string]*keyLock)
+	}
+	kl := l.locks[key]
+	if kl == nil {
+		kl = &keyLock{}
+		l.locks[key] = kl
+	}
+	kl.users++
+	l.mu.Unlock()
+
+	kl.Lock()
+	return func() {
+		kl.Unlock()
+		l.mu.Lock()
+		if kl.users--; kl.users == 0 {
+			delete(l.locks, key)
+		}
+		l.mu.Unlock()
+	}
+}
+
+// New creates a Store over underlying. A nil opts uses the defaults.
+func New(underlying common.Storage, opts *Options) *Store {
+	if opts == nil {
+		opts = &Options{}
+	}
+	s := &Store{
+		underlying: underlying,
+		minSize:    opts.MinChunkSize,
+		avgSize:    opts.AvgChunkSize,
+		maxSize:    opts.MaxChunkSize,
+		pending:    make(map[string]*pendingChunk),
+	}
+	if s.minSize <= 0 {
+		s.minSize = DefaultMinChunkSize
+	}
+	if s.avgSize <= 0 {
+		s.avgSize = DefaultAvgChunkSize
+	}
+	if s.maxSize <= 0 {
+		s.maxSize = DefaultMaxChunkSize
+	}
+	if s.maxSize < s.minSize {
+		s.maxSize = s.minSize
+	}
+	return s
+}
+
+// Underlying returns the wrapped storage.
+func (s *Store) Underlying() common.Storage {
+	return s.underlying
+}
+
+// Configure passes through configuration to the underlying storage.
+func (s *Store) Configure(settings map[string]string) error {
+	return s.underlying.Configure(settings)
+}
+
+// Put stores data deduplicated under key.
+func (s *Store) Put(key string, data io.Reader) error {
+	return s.PutWithMetadata(context.Background(), key, data, nil)
+}
+
+// PutWithContext stores data deduplicated under key with context support.
+func (s *Store) PutWithContext(ctx context.Context, key string, data io.Reader) error {
+	return s.PutWithMetadata(ctx, key, data, nil)
+}
+
+// PutWithMetadata splits data into chunks, stores the chunks that are not
+// already present and writes a manifest with metadata under key. Replacing
+// an existing object releases the chunks it referenced. Writes and deletes
+// of the same key are serialized.
+func (s *Store) PutWithMetadata(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) error {
+	if err := common.ValidateKey(key); err != nil {
+		return err
+	}
+	if strings.HasPrefix(key, Prefix) {
+		return fmt.Errorf("%w: keys under %q are reserved", common.ErrInvalidArgument, Prefix)
+	}
+	defer s.keys.lock(key)()
+
+	old, err := s.readManifest(ctx, key)
+	if err != nil && !errors.Is(err, common.ErrKeyNotFound) {
+		return err
+	}
+
+	m := &manifest{Version: manifestVersion}
+	var acquired []string
+	c := newChunker(data, s.minSize, s.avgSize, s.maxSize)
+	for {
+		chunk, err := c.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			_ = s.release(ctx, acquired)
+			return err
+		}
+
+		sum := sha256.Sum256(chunk)
+		h := hex.EncodeToString(sum[:])
+		if err := s.acquire(ctx, h, chunk); err != nil {
+			_ = s.release(ctx, acquired)
+			return err
+		}
+		acquired = append(acquired, h)
+		m.Chunks = append(m.Chunks, chunkInfo{Hash: h, Size: int64(len(chunk))})
+		m.Size += int64(len(chunk))
+	}
+
+	body, err := json.Marshal(m)
+	if err != nil {
+		_ = s.release(ctx, acquired)
+		return err
+	}
+	meta := &common.Metadata{}
+	if metadata != nil {
+		copied := *metadata
+		meta = &copied
+	}
+	custom := make(map[string]string, len(meta.Custom)+2)
+	for k, v := range meta.Custom {
+		custom[k] = v
+	}
+	custom[metaManifest] = manifestVersion
+	custom[metaSize] = strconv.FormatInt(m.Size, 10)
+	meta.Custom = custom
+	meta.Size = int64(len(body))
+
+	if err := s.underlying.PutWithMetadata(ctx, key, bytes.NewReader(body), meta); err != nil {
+		_ = s.release(ctx, acquired)
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.loadLocked(ctx); err != nil {
+		return err
+	}
+	s.idx.Objects++
+	s.idx.LogicalBytes += m.Size
+	if old != nil {
+		s.idx.Objects--
+		s.idx.LogicalBytes -= old.Size
+		if err := s.releaseLocked(ctx, old.hashes()); err != nil {
+			return err
+		}
+	}
+	return s.saveLocked(ctx)
+}
+
+// Get retrieves the object stored under key.
+func (s *Store) Get(key string) (io.ReadCloser, error) {
+	return s.GetWithContext(context.Background(), key)
+}
+
+// GetWithContext reassembles the object stored under key from its chunks.
+// Every chunk is verified against its hash while it is read. Objects that
+// were not written through the CAS layer are returned unchanged.
+func (s *Store) GetWithContext(ctx context.Context, key string) (io.ReadCloser, error) {
+	m, err := s.readManifest(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if m == nil {
+		return s.underlying.GetWithContext(ctx, key)
+	}
+	return &chunkReader{ctx: ctx, store: s, chunks: m.Chunks}, nil
+}
+
+// GetMetadata returns the metadata of key with Size set to the logical
+// object size.
+func (s *Store) GetMetadata(ctx context.Context, key string) (*common.Metadata, error) {
+	meta, err := s.underlying.GetMetadata(ctx, key)
+	if err != nil || meta == nil {
+		return meta, err
+	}
+	return logicalMetadata(meta), nil
+}
+
+// UpdateMetadata updates the metadata of key, preserving the fields that
+// mark it as a manifest.
+func (s *Store) UpdateMetadata(ctx context.Context, key string, metadata *common.Metadata) error {
+	current, err := s.underlying.GetMetadata(ctx, key)
+	if err != nil {
+		return err
+	}
+	if customValue(current, metaManifest) == "" || metadata == nil {
+		return s.underlying.UpdateMetadata(ctx, key, metadata)
+	}
+
+	updated := *metadata
+	updated.Custom = make(map[string]string, len(metadata.Custom)+2)
+	for k, v := range metadata.Custom {
+		updated.Custom[k] = v
+	}
+	updated.Custom[metaManifest] = customValue(current, metaManifest)
+	updated.Custom[metaSize] = customValue(current, metaSize)
+	return s.underlying.UpdateMetadata(ctx, key, &updated)
+}
+
+// Delete removes key and releases the chunks it referenced.
+func (s *Store) Delete(key string) error {
+	return s.DeleteWithContext(context.Background(), key)
+}
+
+// DeleteWithContext removes key and releases the chunks it referenced.
+// Chunks that are no longer referenced by any object are deleted.
+func (s *Store) DeleteWithContext(ctx context.Context, key string) error {
+	defer s.keys.lock(key)()
+	m, err := s.readManifest(ctx, key)
+	if err != nil {
+		return err
+	}
+	if err := s.underlying.DeleteWithContext(ctx, key); err != nil {
+		return err
+	}
+	if m == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.loadLocked(ctx); err != nil {
+		return err
+	}
+	s.idx.Objects--
+	s.idx.LogicalBytes -= m.Size
+	if err := s.releaseLocked(ctx, m.hashes()); err != nil {
+		return err
+	}
+	return s.saveLocked(ctx)
+}
+
+// Exists checks if an object exists in the underlying storage.
+func (s *Store) Exists(ctx context.Context, key string) (bool, error) {
+	return s.underlying.Exists(ctx, key)
+}
+
+// List returns the keys that start with prefix, excluding CAS internals.
+func (s *Store) List(prefix string) ([]string, error) {
+	return s.ListWithContext(context.Background(), prefix)
+}
+
+// ListWithContext returns the keys that start with prefix, excluding CAS
+// internals.
+func (s *Store) ListWithContext(ctx context.Context, prefix string) ([]string, error) {
+	keys, err := s.underlying.ListWithContext(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	out := keys[:0]
+	for _, k := range keys {
+		if !strings.HasPrefix(k, Prefix) {
+			out = append(out, k)
+		}
+	}
+	return out, nil
+}
+
+// ListWithOptions returns a page of objects, excluding CAS internals and
+// reporting logical object sizes. Pages may be shorter than MaxResults when
+// CAS internals were filtered out.
+func (s *Store) ListWithOptions(ctx context.Context, opts *common.ListOptions) (*common.ListResult, error) {
+	result, err := s.underlying.ListWithOptions(ctx, opts)
+	if err != nil || result == nil {
+		return result, err
+	}
+	objects := result.Objects[:0]
+	for _, obj := range result.Objects {
+		if obj == nil || strings.HasPrefix(obj.Key, Prefix) {
+			continue
+		}
+		if obj.Metadata != nil {
+			obj.Metadata = logicalMetadata(obj.Metadata)
+		}
+		objects = append(objects, obj)
+	}
+	result.Objects = objects
+
+	prefixes := result.CommonPrefixes[:0]
+	for _, p := range result.CommonPrefixes {
+		if !strings.HasPrefix(p, Prefix) {
+			prefixes = append(prefixes, p)
+		}
+	}
+	result.CommonPrefixes = prefixes
+	return result, nil
+}
+
+// Archive copies the reassembled object to destination.
+func (s *Store) Archive(key string, destination common.Archiver) error {
+	if destination == nil {
+		return common.ErrArchiveDestinationNil
+	}
+	rc, err := s.Get(key)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rc.Close() }()
+	return destination.Put(key, rc)
+}
+
+// AddPolicy adds a lifecycle policy to the underlying storage.
+func (s *Store) AddPolicy(policy common.LifecyclePolicy) error {
+	return s.underlying.AddPolicy(policy)
+}
+
+// RemovePolicy removes a lifecycle policy from the underlying storage.
+func (s *Store) RemovePolicy(id string) error {
+	return s.underlying.RemovePolicy(id)
+}
+
+// GetPolicies returns the lifecycle policies of the underlying storage.
+func (s *Store) GetPolicies() ([]common.LifecyclePolicy, error) {
+	return s.underlying.GetPolicies()
+}
+
+// Stats returns deduplication statistics from the index.
+func (s *Store) Stats(ctx context.Context) (*Stats, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.loadLocked(ctx); err != nil {
+		return nil, err
+	}
+
+	st := &Stats{
+		Objects:      s.idx.Objects,
+		LogicalBytes: s.idx.LogicalBytes,
+		Chunks:       int64(len(s.idx.Chunks)),
+		Ratio:        1,
+	}
+	for _, ref := range s.idx.Chunks {
+		st.StoredBytes += ref.Size
+	}
+	st.SavedBytes = st.LogicalBytes - st.StoredBytes
+	if st.StoredBytes > 0 {
+		st.Ratio = float64(st.LogicalBytes) / float64(st.StoredBytes)
+	}
+	return st, nil
+}
+
+// acquire takes a reference on chunk h, writing the chunk if it is not
+// stored yet. The reference is recorded before the write so a concurrent
+// delete can never drop a chunk that is about to be used; concurrent
+// acquirers of the same chunk wait for that write and take their reference
+// only once the chunk is stored.
+func (s *Store) acquire(ctx context.Context, h string, chunk []byte) error {
+	s.mu.Lock()
+	if err := s.loadLocked(ctx); err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	for {
+		p, ok := s.pending[h]
+		if !ok {
+			break
+		}
+		s.mu.Unlock()
+		select {
+		case <-p.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		s.mu.Lock()
+	}
+	if ref, ok := s.idx.Chunks[h]; ok {
+		ref.Refs++
+		s.mu.Unlock()
+		return nil
+	}
+	p := &pendingChunk{done: make(chan struct{})}
+	s.pending[h] = p
+	s.idx.Chunks[h] = &chunkRef{Size: int64(len(chunk)), Refs: 1}
+	s.mu.Unlock()
+
+	p.err = s.underlying.PutWithContext(ctx, chunkKey(h), bytes.NewReader(chunk))
+
+	s.mu.Lock()
+	if p.err != nil {
+		// Acquirers of h waited for this write, so the reference taken above
+		// is the only one.
+		delete(s.idx.Chunks, h)
+	}
+	delete(s.pending, h)
+	close(p.done)
+	s.mu.Unlock()
+	if p.err != nil {
+		return fmt.Errorf("failed to store chunk %s: %w", h, p.err)
+	}
+	return nil
+}
+
+// release drops one reference for each hash.
+func (s *Store) release(ctx context.Context, hashes []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.releaseLocked(ctx, hashes)
+}
+
+// releaseLocked drops one reference for each hash and deletes chunks that
+// are no longer referenced. The caller must hold s.mu.
+func (s *Store) releaseLocked(ctx context.Context, hashes []string) error {
+	if err := s.loadLocked(ctx); err != nil {
+		return err
+	}
+	var first error
+	for _, h := range hashes {
+		ref, ok := s.idx.Chunks[h]
+		if !ok {
+			continue
+		}
+		ref.Refs--
+		if ref.Refs > 0 {
+			continue
+		}
+		delete(s.idx.Chunks, h)
+		if err := s.underlying.DeleteWithContext(ctx, chunkKey(h)); err != nil && !errors.Is(err, common.ErrKeyNotFound) && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// loadLocked reads the index from the underlying storage on first use. The
+// caller must hold s.mu.
+func (s *Store) loadLocked(ctx context.Context) error {
+	if s.idx != nil {
+		return nil
+	}
+	idx := &index{Chunks: make(map[string]*chunkRef)}
+
+	exists, err := s.underlying.Exists(ctx, indexKey)
+	if err != nil {
+		return err
+	}
+	if exists {
+		rc, err := s.underlying.GetWithContext(ctx, indexKey)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = rc.Close() }()
+		if err := json.NewDecoder(rc).Decode(idx); err != nil {
+			return fmt.Errorf("failed to decode cas index: %w", err)
+		}
+		if idx.Chunks == nil {
+			idx.Chunks = make(map[string]*chunkRef)
+		}
+	}
+	s.idx = idx
+	return nil
+}
+
+// saveLocked persists the index. The caller must hold s.mu.
+func (s *Store) saveLocked(ctx context.Context) error {
+	body, err := json.Marshal(s.idx)
+	if err != nil {
+		return err
+	}
+	return s.underlying.PutWithContext(ctx, indexKey, bytes.NewReader(body))
+}
+
+// readManifest returns the manifest stored under key, or nil when key holds
+// an object that was not written through the CAS layer. A missing key is
+// reported as common.ErrKeyNotFound regardless of the backend.
+func (s *Store) readManifest(ctx context.Context, key string) (*manifest, error) {
+	exists, err := s.underlying.Exists(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", common.ErrKeyNotFound, key)
+	}
+
+	meta, err := s.underlying.GetMetadata(ctx, key)
+	if errors.Is(err, common.ErrMetadataNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if customValue(meta, metaManifest) == "" {
+		return nil, nil
+	}
+
+	rc, err := s.underlying.GetWithContext(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rc.Close() }()
+
+	m := &manifest{}
+	if err := json.NewDecoder(rc).Decode(m); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidManifest, key, err)
+	}
+	if m.Version != manifestVersion {
+		return nil, fmt.Errorf("%w: %s: unsupported version %q", ErrInvalidManifest, key, m.Version)
+	}
+	return m, nil
+}
+
+// hashes returns the chunk hashes of m in order.
+func (m *manifest) hashes() []string {
+	out := make([]string, len(m.Chunks))
+	for i, c := range m.Chunks {
+		out[i] = c.Hash
+	}
+	return out
+}
+
+// chunkKey returns the storage key of the chunk with hash h. Chunks are
+// fanned out by the first two hex digits to keep directories small on
+// filesystem backends.
+func chunkKey(h string) string {
+	return chunkPrefix + h[:2] + "/" + h
+}
+
+// customValue returns the custom metadata field name. The lookup ignores
+// case because some backends (S3) canonicalize user metadata keys.
+func customValue(meta *common.Metadata, name string) string {
+	if meta == nil {
+		return ""
+	}
+	if v, ok := meta.Custom[name]; ok {
+		return v
+	}
+	for k, v := range meta.Custom {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return ""
+}
+
+// logicalMetadata returns a copy of meta with the CAS fields removed and
+// Size set to the logical object size.
+func logicalMetadata(meta *common.Metadata) *common.Metadata {
+	if customValue(meta, metaManifest) == "" {
+		return meta
+	}
+	out := *meta
+	out.Custom = make(map[string]string, len(meta.Custom))
+	for k, v := range meta.Custom {
+		if !strings.EqualFold(k, metaManifest) && !strings.EqualFold(k, metaSize) {
+			out.Custom[k] = v
+		}
+	}
+	if size, err := strconv.ParseInt(customValue(meta, metaSize), 10, 64); err == nil {
+		out.Size = size
+	}
+	return &out
+}
+
+// chunkReader streams the chunks of a manifest in order, verifying each one
+// against its hash.
+type chunkReader struct {
+	ctx    context.Context
+	store  *Store
+	chunks []chunkInfo
+
+	cur  io.ReadCloser
+	hash hash.Hash
+	want string
+}
+
+// Read implements io.Reader.
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for {
+		if r.cur == nil {
+			if len(r.chunks) == 0 {
+				return 0, io.EOF
+			}
+			next := r.chunks[0]
+			r.chunks = r.chunks[1:]
+			rc, err := r.store.underlying.GetWithContext(r.ctx, chunkKey(next.Hash))
+			if err != nil {
+				return 0, fmt.Errorf("failed to read chunk %s: %w", next.Hash, err)
+			}
+			r.cur, r.hash, r.want = rc, sha256.New(), next.Hash
+		}
+
+		n, err := r.cur.Read(p)
+		r.hash.Write(p[:n])
+		if err == io.EOF {
+			_ = r.cur.Close()
+			r.cur = nil
+			if hex.EncodeToString(r.hash.Sum(nil)) != r.want {
+				return n, fmt.Errorf("%w: %s", ErrChunkCorrupt, r.want)
+			}
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+		return n, err
+	}
+}
+
+// Close releases the chunk currently being read.
+func (r *chunkReader) Close() error {
+	if r.cur == nil {
+		return nil
+	}
+	err := r.cur.Close()
+	r.cur = nil
+	return err
+}
+
+// Ensure Store implements Storage interface at compile time
+var _ common.Storage = (*Store)(nil)
@@ -0,0 +1,403 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package dedup
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+// smallOptions keeps chunks small so tests exercise many of them.
+var smallOptions = &Options{MinChunkSize: 256, AvgChunkSize: 1024, MaxChunkSize: 4096}
+
+func randomData(seed int64, n int) []byte {
+	data := make([]byte, n)
+	rand.New(rand.NewSource(seed)).Read(data)
+	return data
+}
+
+func readAll(t *testing.T, s common.Storage, key string) []byte {
+	t.Helper()
+	rc, err := s.Get(key)
+	if err != nil {
+		t.Fatalf("Get(%s): %v", key, err)
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatalf("ReadAll(%s): %v", key, err)
+	}
+	return data
+}
+
+func chunkCount(t *testing.T, underlying common.Storage) int {
+	t.Helper()
+	keys, err := underlying.List(chunkPrefix)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	return len(keys)
+}
+
+func TestChunker_BoundariesResync(t *testing.T) {
+	data := randomData(1, 64*1024)
+	edited := append(append(append([]byte{}, data[:1000]...), []byte("inserted bytes")...), data[1000:]...)
+
+	split := func(b []byte) map[string]bool {
+		out := make(map[string]bool)
+		c := newChunker(bytes.NewReader(b), 256, 1024, 4096)
+		for {
+			chunk, err := c.Next()
+			if err == io.EOF {
+				return out
+			}
+			if err != nil {
+				t.Fatalf("Next: %v", err)
+			}
+			if len(chunk) > 4096 {
+				t.Fatalf("chunk of %d bytes exceeds max", len(chunk))
+			}
+			out[string(chunk)] = true
+		}
+	}
+
+	a, b := split(data), split(edited)
+	shared := 0
+	for k := range a {
+		if b[k] {
+			shared++
+		}
+	}
+	if shared < len(a)-3 {
+		t.Errorf("expected an insertion to change at most a few chunks, %d of %d shared", shared, len(a))
+	}
+}
+
+func TestStore_DeduplicatesNearIdenticalObjects(t *testing.T) {
+	underlying := memory.New()
+	s := New(underlying, smallOptions)
+
+	base := randomData(2, 128*1024)
+	variant := append([]byte{}, base...)
+	copy(variant[50000:], "build-id: 0xdeadbeef")
+
+	if err := s.Put("artifacts/v1.bin", bytes.NewReader(base)); err != nil {
+		t.Fatalf("Put v1: %v", err)
+	}
+	if err := s.Put("artifacts/v2.bin", bytes.NewReader(variant)); err != nil {
+		t.Fatalf("Put v2: %v", err)
+	}
+	if err := s.Put("artifacts/copy.bin", bytes.NewReader(base)); err != nil {
+		t.Fatalf("Put copy: %v", err)
+	}
+
+	if !bytes.Equal(readAll(t, s, "artifacts/v1.bin"), base) {
+		t.Error("v1 content mismatch")
+	}
+	if !bytes.Equal(readAll(t, s, "artifacts/v2.bin"), variant) {
+		t.Error("v2 content mismatch")
+	}
+
+	stats, err := s.Stats(context.Background())
+	if err != nil {
+		t.Fatalf("Stats: %v", err)
+	}
+	if stats.Objects != 3 || stats.LogicalBytes != int64(3*len(base)) {
+		t.Errorf("unexpected object totals: %+v", stats)
+	}
+	if stats.StoredBytes > int64(len(base))+16*1024 {
+		t.Errorf("expected near-identical objects to share chunks, stored %d bytes", stats.StoredBytes)
+	}
+	if stats.Ratio < 2.5 || stats.SavedBytes != stats.LogicalBytes-stats.StoredBytes {
+		t.Errorf("unexpected ratio: %+v", stats)
+	}
+	if got := chunkCount(t, underlying); int64(got) != stats.Chunks {
+		t.Errorf("index reports %d chunks, storage holds %d", stats.Chunks, got)
+	}
+}
+
+func TestStore_DeleteReleasesChunks(t *testing.T) {
+	underlying := memory.New()
+	s := New(underlying, smallOptions)
+	data := randomData(3, 32*1024)
+
+	for _, key := range []string{"a", "b"} {
+		if err := s.Put(key, bytes.NewReader(data)); err != nil {
+			t.Fatalf("Put %s: %v", key, err)
+		}
+	}
+	before := chunkCount(t, underlying)
+
+	if err := s.Delete("a"); err != nil {
+		t.Fatalf("Delete a: %v", err)
+	}
+	if got := chunkCount(t, underlying); got != before {
+		t.Errorf("chunks still referenced by b were deleted: %d -> %d", before, got)
+	}
+	if !bytes.Equal(readAll(t, s, "b"), data) {
+		t.Error("b content mismatch after deleting a")
+	}
+
+	if err := s.Delete("b"); err != nil {
+		t.Fatalf("Delete b: %v", err)
+	}
+	if got := chunkCount(t, underlying); got != 0 {
+		t.Errorf("expected all chunks released, %d left", got)
+	}
+	if err := s.Delete("b"); !errors.Is(err, common.ErrKeyNotFound) {
+		t.Errorf("expected ErrKeyNotFound, got %v", err)
+	}
+}
+
+func TestStore_OverwriteReleasesOldChunks(t *testing.T) {
+	underlying := memory.New()
+	s := New(underlying, smallOptions)
+
+	if err := s.Put("key", bytes.NewReader(randomData(4, 16*1024))); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	replacement := randomData(5, 8*1024)
+	if err := s.Put("key", bytes.NewReader(replacement)); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	stats, err := s.Stats(context.Background())
+	if err != nil {
+		t.Fatalf("Stats: %v", err)
+	}
+	if stats.Objects != 1 || stats.StoredBytes != int64(len(replacement)) {
+		t.Errorf("old chunks were not released: %+v", stats)
+	}
+}
+
+func TestStore_ConcurrentOverwriteAndDelete(t *testing.T) {
+	underlying := memory.New()
+	s := New(underlying, smallOptions)
+	data := randomData(9, 32*1024)
+
+	// b shares every chunk with the object churned under a.
+	if err := s.Put("b", bytes.NewReader(data)); err != nil {
+		t.Fatalf("Put b: %v", err)
+	}
+	var wg sync.WaitGroup
+	for i := range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if i%2 == 0 {
+				_ = s.Delete("a")
+				return
+			}
+			if err := s.Put("a", bytes.NewReader(data)); err != nil {
+				t.Errorf("Put a: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if !bytes.Equal(readAll(t, s, "b"), data) {
+		t.Fatal("b content mismatch after concurrent writes of a")
+	}
+	_ = s.Delete("a")
+	if err := s.Delete("b"); err != nil {
+		t.Fatalf("Delete b: %v", err)
+	}
+	if got := chunkCount(t, underlying); got != 0 {
+		t.Errorf("expected all chunks released, %d left", got)
+	}
+	stats, err := s.Stats(context.Background())
+	if err != nil {
+		t.Fatalf("Stats: %v", err)
+	}
+	if stats.Objects != 0 || stats.LogicalBytes != 0 || stats.Chunks != 0 {
+		t.Errorf("index out of balance: %+v", stats)
+	}
+}
+
+// failingChunkStorage fails the first chunk write once released.
+type failingChunkStorage struct {
+	common.Storage
+	entered chan struct{}
+	release chan struct{}
+	once    sync.Once
+}
+
+func (f *failingChunkStorage) PutWithContext(ctx context.Context, key string, data io.Reader) error {
+	if strings.HasPrefix(key, chunkPrefix) {
+		fail := false
+		f.once.Do(func() { fail = true })
+		if fail {
+			close(f.entered)
+			<-f.release
+			return errors.New("chunk write failed")
+		}
+	}
+	return f.Storage.PutWithContext(ctx, key, data)
+}
+
+func TestStore_ConcurrentAcquireWaitsForChunkWrite(t *testing.T) {
+	underlying := &failingChunkStorage{
+		Storage: memory.New(),
+		entered: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+	s := New(underlying, smallOptions)
+	data := randomData(10, 1024)
+
+	first := make(chan error, 1)
+	go func() { first <- s.Put("a", bytes.NewReader(data)) }()
+	<-underlying.entered
+
+	second := make(chan error, 1)
+	go func() { second <- s.Put("b", bytes.NewReader(data)) }()
+	time.Sleep(20 * time.Millisecond)
+	close(underlying.release)
+
+	if err := <-first; err == nil {
+		t.Error("Put a: expected the failed chunk write to be reported")
+	}
+	if err := <-second; err != nil {
+		t.Fatalf("Put b: %v", err)
+	}
+	if !bytes.Equal(readAll(t, s, "b"), data) {
+		t.Error("b content mismatch")
+	}
+}
+
+func TestStore_IndexPersists(t *testing.T) {
+	underlying := memory.New()
+	data := randomData(6, 16*1024)
+	if err := New(underlying, smallOptions).Put("key", bytes.NewReader(data)); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	// A fresh Store over the same backend sees the existing references.
+	s := New(underlying, smallOptions)
+	if err := s.Put("other", bytes.NewReader(data)); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	stats, err := s.Stats(context.Background())
+	if err != nil {
+		t.Fatalf("Stats: %v", err)
+	}
+	if stats.Objects != 2 || stats.StoredBytes != int64(len(data)) {
+		t.Errorf("index was not reloaded: %+v", stats)
+	}
+}
+
+func TestStore_MetadataAndListing(t *testing.T) {
+	underlying := memory.New()
+	s := New(underlying, smallOptions)
+	data := randomData(7, 10*1024)
+	ctx := context.Background()
+
+	err := s.PutWithMetadata(ctx, "docs/report.pdf", bytes.NewReader(data), &common.Metadata{
+		ContentType: "application/pdf",
+		Custom:      map[string]string{"author": "ci"},
+	})
+	if err != nil {
+		t.Fatalf("PutWithMetadata: %v", err)
+	}
+
+	meta, err := s.GetMetadata(ctx, "docs/report.pdf")
+	if err != nil {
+		t.Fatalf("GetMetadata: %v", err)
+	}
+	if meta.Size != int64(len(data)) || meta.ContentType != "application/pdf" {
+		t.Errorf("unexpected metadata: %+v", meta)
+	}
+	if meta.Custom["author"] != "ci" || meta.Custom[metaManifest] != "" {
+		t.Errorf("unexpected custom metadata: %v", meta.Custom)
+	}
+
+	if err := s.UpdateMetadata(ctx, "docs/report.pdf", &common.Metadata{Custom: map[string]string{"author": "release"}}); err != nil {
+		t.Fatalf("UpdateMetadata: %v", err)
+	}
+	if !bytes.Equal(readAll(t, s, "docs/report.pdf"), data) {
+		t.Error("content mismatch after UpdateMetadata")
+	}
+
+	keys, err := s.List("")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(keys) != 1 || keys[0] != "docs/report.pdf" {
+		t.Errorf("expected CAS internals to be hidden, got %v", keys)
+	}
+
+	result, err := s.ListWithOptions(ctx, &common.ListOptions{})
+	if err != nil {
+		t.Fatalf("ListWithOptions: %v", err)
+	}
+	if len(result.Objects) != 1 || result.Objects[0].Metadata.Size != int64(len(data)) {
+		t.Errorf("unexpected listing: %+v", result.Objects)
+	}
+
+	if err := s.Put(indexKey, strings.NewReader("x")); !errors.Is(err, common.ErrInvalidArgument) {
+		t.Errorf("expected reserved prefix to be rejected, got %v", err)
+	}
+}
+
+func TestStore_RawObjectsPassThrough(t *testing.T) {
+	underlying := memory.New()
+	if err := underlying.Put("legacy.txt", strings.NewReader("written before dedup")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	s := New(underlying, nil)
+	if got := string(readAll(t, s, "legacy.txt")); got != "written before dedup" {
+		t.Errorf("unexpected content %q", got)
+	}
+	if err := s.Delete("legacy.txt"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := s.Get("legacy.txt"); !errors.Is(err, common.ErrKeyNotFound) {
+		t.Errorf("expected ErrKeyNotFound, got %v", err)
+	}
+}
+
+func TestStore_DetectsCorruptChunk(t *testing.T) {
+	underlying := memory.New()
+	s := New(underlying, smallOptions)
+	if err := s.Put("key", bytes.NewReader(randomData(8, 4096))); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	keys, err := underlying.List(chunkPrefix)
+	if err != nil || len(keys) == 0 {
+		t.Fatalf("List: %v %v", keys, err)
+	}
+	if err := underlying.Put(keys[0], strings.NewReader("tampered")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	rc, err := s.Get("key")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	defer rc.Close()
+	if _, err := io.ReadAll(rc); !errors.Is(err, ErrChunkCorrupt) {
+		t.Errorf("expected ErrChunkCorrupt, got %v", err)
+	}
+}
@@ -0,0 +1,452 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package delta implements rsync-style delta transfer between two versions
+// of an object.
+//
+// The receiver's current version (the base) is summarized as a Signature:
+// a weak rolling checksum and a SHA-256 hash per fixed-size block. Diff
+// scans the new version with the rolling checksum and produces a list of
+// Ops that either copy a range of the base or take a range of the new
+// version. A backend implementing Patcher rebuilds the object from those
+// ops, so only the literal ranges have to be transferred.
+package delta
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// DefaultBlockSize is the signature block size (1 MiB). Smaller blocks find
+// more matches at the cost of a larger signature.
+const DefaultBlockSize = 1024 * 1024
+
+// DefaultThreshold is the minimum object size for a delta transfer (16 MiB).
+// Below it, computing signatures costs more than sending the whole object.
+const DefaultThreshold = 16 * 1024 * 1024
+
+// Setting keys understood by ParseSettings. All are optional.
+const (
+	// SettingEnabled turns delta transfer on or off ("true" or "false").
+	SettingEnabled = "deltaSync"
+
+	// SettingBlockSize is the signature block size in bytes.
+	SettingBlockSize = "deltaBlockSize"
+
+	// SettingThreshold is the minimum object size in bytes.
+	SettingThreshold = "deltaThreshold"
+)
+
+var (
+	// ErrBaseChanged is returned by a Patcher when the base object no longer
+	// matches the signature the ops were computed against. It wraps
+	// common.ErrPreconditionFailed.
+	ErrBaseChanged = fmt.Errorf("delta base object changed: %w", common.ErrPreconditionFailed)
+
+	// ErrNotSupported is returned by a Patcher that cannot apply ops to a
+	// particular object, for example because it is encrypted at rest.
+	// Callers fall back to a full transfer.
+	ErrNotSupported = errors.New("delta transfer not supported")
+)
+
+// Options configures when and how delta transfer is used.
+type Options struct {
+	// Disabled turns delta transfer off; every object is sent in full.
+	Disabled bool
+
+	// BlockSize is the signature block size. Zero uses DefaultBlockSize.
+	BlockSize int
+
+	// Threshold is the minimum object size for a delta transfer. Zero uses
+	// DefaultThreshold.
+	Threshold int64
+}
+
+// ParseSettings builds Options from a settings map such as a replication
+// policy's destination settings. Malformed values are rejected with an error
+// wrapping common.ErrInvalidArgument.
+func ParseSettings(settings map[string]string) (*Options, error) {
+	opts := &Options{}
+	if v := settings[SettingEnabled]; v != "" {
+		enabled, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s must be true or false, got %q", common.ErrInvalidArgument, SettingEnabled, v)
+		}
+		opts.Disabled = !enabled
+	}
+	if v := settings[SettingBlockSize]; v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("%w: %s must be a positive integer, got %q", common.ErrInvalidArgument, SettingBlockSize, v)
+		}
+		opts.BlockSize = n
+	}
+	if v := settings[SettingThreshold]; v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("%w: %s must be a non-negative integer, got %q", common.ErrInvalidArgument, SettingThreshold, v)
+		}
+		opts.Threshold = n
+	}
+	return opts, nil
+}
+
+// Applies reports whether an object of the given size should be sent as a
+// delta.
+func (o *Options) Applies(size int64) bool {
+	if o == nil || o.Disabled {
+		return false
+	}
+	threshold := o.Threshold
+	if threshold == 0 {
+		threshold = DefaultThreshold
+	}
+	return size >= threshold
+}
+
+// OpKind identifies the source of an Op's bytes.
+type OpKind int
+
+const (
+	// OpCopy copies Length bytes from the base at Offset.
+	OpCopy OpKind = iota
+
+	// OpLiteral takes Length bytes from the new version at Offset.
+	OpLiteral
+)
+
+// Op is one step of rebuilding the new version.
+type Op struct {
+	Kind   OpKind
+	Offset int64
+	Length int64
+}
+
+// Source opens a byte range of the new version.
+type Source func(ctx context.Context, offset, length int64) (io.ReadCloser, error)
+
+// Patcher is implemented by backends that can rebuild an object from its
+// current version and a list of ops without receiving the unchanged bytes.
+type Patcher interface {
+	// ApplyDelta replaces the object stored under key with the version
+	// described by ops. OpCopy ranges are read from the current object;
+	// OpLiteral ranges are read from src. baseETag is the ETag of the
+	// version the ops were computed against; implementations return
+	// ErrBaseChanged when they can tell the object has changed since.
+	ApplyDelta(ctx context.Context, key, baseETag string, ops []Op, src Source, metadata *common.Metadata) error
+}
+
+// block is the signature of one block of the base.
+type block struct {
+	weak   uint32
+	strong [sha256.Size]byte
+}
+
+// Signature summarizes a base object for Diff.
+type Signature struct {
+	BlockSize int
+	Size      int64
+
+	blocks []block
+	index  map[uint32][]int
+}
+
+// NewSignature computes the signature of the base read from r. A
+// non-positive blockSize uses DefaultBlockSize.
+func NewSignature(r io.Reader, blockSize int) (*Signature, error) {
+	if blockSize <= 0 {
+		blockSize = DefaultBlockSize
+	}
+	sig := &Signature{BlockSize: blockSize, index: make(map[uint32][]int)}
+	buf := make([]byte, blockSize)
+	for {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			b := block{weak: weakSum(buf[:n]), strong: sha256.Sum256(buf[:n])}
+			sig.index[b.weak] = append(sig.index[b.weak], len(sig.blocks))
+			sig.blocks = append(sig.blocks, b)
+			sig.Size += int64(n)
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return sig, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// blockLen returns the length of block i of the base.
+func (s *Signature) blockLen(i int) int {
+	if i == len(s.blocks)-1 {
+		if rem := int(s.Size % int64(s.BlockSize)); rem != 0 {
+			return rem
+		}
+	}
+	return s.BlockSize
+}
+
+// match returns the base block whose content equals window, or -1.
+func (s *Signature) match(weak uint32, window []byte) int {
+	candidates := s.index[weak]
+	if len(candidates) == 0 {
+		return -1
+	}
+	strong := sha256.Sum256(window)
+	for _, i := range candidates {
+		if s.blockLen(i) == len(window) && s.blocks[i].strong == strong {
+			return i
+		}
+	}
+	return -1
+}
+
+// Diff scans the new version read from r against sig and returns the ops
+// that rebuild it. Adjacent ops of the same kind are merged.
+func Diff(sig *Signature, r io.Reader) ([]Op, error) {
+	d := &differ{sig: sig}
+	bs := sig.BlockSize
+	buf := make([]byte, 0, 4*bs)
+	var (
+		pos   int64 // offset of buf[0] in the new version
+		i     int   // start of the rolling window in buf
+		a, b  uint32
+		valid bool // a and b describe buf[i:i+bs]
+		eof   bool
+	)
+
+	for {
+		if len(buf)-i < bs && !eof {
+			// Compact and refill so a full window is available.
+			n := copy(buf, buf[i:])
+			pos += int64(i)
+			buf, i = buf[:n], 0
+			for len(buf) < cap(buf) && !eof {
+				m, err := r.Read(buf[len(buf):cap(buf)])
+				buf = buf[:len(buf)+m]
+				if err == io.EOF {
+					eof = true
+				} else if err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		if len(buf)-i < bs {
+			// Tail shorter than a block: it can only match the base's
+			// final short block.
+			if rest := buf[i:]; len(rest) > 0 {
+				if blk := sig.match(weakSum(rest), rest); blk >= 0 {
+					d.copyBlock(blk)
+				} else {
+					d.literal(pos+int64(i), int64(len(rest)))
+				}
+			}
+			return d.ops, nil
+		}
+
+		if !valid {
+			a, b = weakParts(buf[i : i+bs])
+			valid = true
+		}
+		if blk := sig.match(a|b<<16, buf[i:i+bs]); blk >= 0 {
+			d.copyBlock(blk)
+			i += bs
+			valid = false
+			continue
+		}
+
+		d.literal(pos+int64(i), 1)
+		if i+bs < len(buf) {
+			out, in := uint32(buf[i]), uint32(buf[i+bs])
+			a = (a - out + in) & 0xffff
+			b = (b - uint32(bs)*out + a) & 0xffff
+		} else {
+			valid = false
+		}
+		i++
+	}
+}
+
+// differ accumulates ops, merging adjacent ranges.
+type differ struct {
+	sig *Signature
+	ops []Op
+}
+
+func (d *differ) copyBlock(blk int) {
+	off := int64(blk) * int64(d.sig.BlockSize)
+	d.add(Op{Kind: OpCopy, Offset: off, Length: int64(d.sig.blockLen(blk))})
+}
+
+func (d *differ) literal(off, length int64) {
+	d.add(Op{Kind: OpLiteral, Offset: off, Length: length})
+}
+
+func (d *differ) add(op Op) {
+	if n := len(d.ops); n > 0 {
+		last := &d.ops[n-1]
+		if last.Kind == op.Kind && last.Offset+last.Length == op.Offset {
+			last.Length += op.Length
+			return
+		}
+	}
+	d.ops = append(d.ops, op)
+}
+
+// Stats summarizes ops.
+type Stats struct {
+	// CopiedBytes is the number of bytes reused from the base.
+	CopiedBytes int64
+
+	// LiteralBytes is the number of bytes that must be transferred.
+	LiteralBytes int64
+}
+
+// Summarize returns the copied and literal byte counts of ops.
+func Summarize(ops []Op) Stats {
+	var st Stats
+	for _, op := range ops {
+		if op.Kind == OpCopy {
+			st.CopiedBytes += op.Length
+		} else {
+			st.LiteralBytes += op.Length
+		}
+	}
+	return st
+}
+
+// NewReader returns a reader over the new version, reading OpCopy ranges
+// from base and OpLiteral ranges from src. Backends without a native way to
+// apply ops use it to stream the rebuilt object into a regular Put.
+func NewReader(ctx context.Context, base io.ReaderAt, ops []Op, src Source) io.ReadCloser {
+	return &patchReader{ctx: ctx, base: base, ops: ops, src: src}
+}
+
+// patchReader streams the rebuilt object one op at a time.
+type patchReader struct {
+	ctx  context.Context
+	base io.ReaderAt
+	ops  []Op
+	src  Source
+
+	cur    io.Reader
+	closer io.Closer
+}
+
+// Read implements io.Reader.
+func (p *patchReader) Read(buf []byte) (int, error) {
+	for {
+		if p.cur == nil {
+			if len(p.ops) == 0 {
+				return 0, io.EOF
+			}
+			op := p.ops[0]
+			p.ops = p.ops[1:]
+			if op.Kind == OpCopy {
+				p.cur = io.NewSectionReader(p.base, op.Offset, op.Length)
+			} else {
+				rc, err := p.src(p.ctx, op.Offset, op.Length)
+				if err != nil {
+					return 0, err
+				}
+				p.cur, p.closer = io.LimitReader(rc, op.Length), rc
+			}
+		}
+
+		n, err := p.cur.Read(buf)
+		if err == io.EOF {
+			p.closeCurrent()
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+		return n, err
+	}
+}
+
+// Close releases the literal range currently being read.
+func (p *patchReader) Close() error {
+	p.closeCurrent()
+	return nil
+}
+
+func (p *patchReader) closeCurrent() {
+	if p.closer != nil {
+		_ = p.closer.Close()
+	}
+	p.cur, p.closer = nil, nil
+}
+
+// ReadRange reads the whole range of op from base or src into memory.
+// Backends that assemble parts in memory use it.
+func ReadRange(ctx context.Context, base io.ReaderAt, op Op, src Source) ([]byte, error) {
+	buf := make([]byte, op.Length)
+	if op.Kind == OpCopy {
+		if _, err := base.ReadAt(buf, op.Offset); err != nil && err != io.EOF {
+			return nil, err
+		}
+		return buf, nil
+	}
+	rc, err := src(ctx, op.Offset, op.Length)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rc.Close() }()
+	if _, err := io.ReadFull(rc, buf); err != nil {
+		return nil, fmt.Errorf("failed to read literal range at %d: %w", op.Offset, err)
+	}
+	return buf, nil
+}
+
+// BytesSource serves literal ranges from an in-memory new version.
+func BytesSource(data []byte) Source {
+	return func(_ context.Context, offset, length int64) (io.ReadCloser, error) {
+		if offset < 0 || offset+length > int64(len(data)) {
+			return nil, fmt.Errorf("%w: range %d+%d outside %d bytes", common.ErrInvalidArgument, offset, length, len(data))
+		}
+		return io.NopCloser(bytes.NewReader(data[offset : offset+length])), nil
+	}
+}
+
+// RangeSource serves literal ranges from an object of a backend that
+// supports ranged reads.
+func RangeSource(rr common.RangeReader, key string) Source {
+	return func(ctx context.Context, offset, length int64) (io.ReadCloser, error) {
+		return rr.GetRange(ctx, key, offset, length)
+	}
+}
+
+// weakParts returns the two halves of the rsync weak checksum of data.
+func weakParts(data []byte) (a, b uint32) {
+	n := uint32(len(data))
+	for i, c := range data {
+		a += uint32(c)
+		b += (n - uint32(i)) * uint32(c)
+	}
+	return a & 0xffff, b & 0xffff
+}
+
+// weakSum returns the rsync weak checksum of data.
+func weakSum(data []byte) uint32 {
+	a, b := weakParts(data)
+	return a | b<<16
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package delta
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+func randomData(seed int64, n int) []byte {
+	data := make([]byte, n)
+	rand.New(rand.NewSource(seed)).Read(data)
+	return data
+}
+
+func rebuild(t *testing.T, base, target []byte, blockSize int) ([]Op, []byte) {
+	t.Helper()
+	sig, err := NewSignature(bytes.NewReader(base), blockSize)
+	if err != nil {
+		t.Fatalf("NewSignature: %v", err)
+	}
+	ops, err := Diff(sig, bytes.NewReader(target))
+	if err != nil {
+		t.Fatalf("Diff: %v", err)
+	}
+	rc := NewReader(context.Background(), bytes.NewReader(base), ops, BytesSource(target))
+	defer rc.Close()
+	got, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	return ops, got
+}
+
+func TestDiff_RoundTrip(t *testing.T) {
+	base := randomData(1, 64*1024+300)
+
+	inserted := append(append(append([]byte{}, base[:10000]...), []byte("some inserted bytes")...), base[10000:]...)
+	removed := append(append([]byte{}, base[:20000]...), base[25000:]...)
+	modified := append([]byte{}, base...)
+	copy(modified[40000:], "overwritten")
+
+	tests := []struct {
+		name        string
+		target      []byte
+		maxLiteral  int64
+		wantCopying bool
+	}{
+		{"identical", base, 0, true},
+		{"insertion", inserted, 2048, true},
+		{"removal", removed, 2048, true},
+		{"modification", modified, 2048, true},
+		{"unrelated", randomData(2, 8000), 8000, false},
+		{"empty", nil, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ops, got := rebuild(t, base, tt.target, 1024)
+			if !bytes.Equal(got, tt.target) {
+				t.Fatalf("rebuilt %d bytes do not match target of %d bytes", len(got), len(tt.target))
+			}
+			stats := Summarize(ops)
+			if stats.CopiedBytes+stats.LiteralBytes != int64(len(tt.target)) {
+				t.Errorf("ops cover %d bytes, want %d", stats.CopiedBytes+stats.LiteralBytes, len(tt.target))
+			}
+			if stats.LiteralBytes > tt.maxLiteral {
+				t.Errorf("expected at most %d literal bytes, got %d", tt.maxLiteral, stats.LiteralBytes)
+			}
+			if tt.wantCopying != (stats.CopiedBytes > 0) {
+				t.Errorf("unexpected copied bytes %d", stats.CopiedBytes)
+			}
+		})
+	}
+}
+
+func TestDiff_EmptyBase(t *testing.T) {
+	target := randomData(3, 5000)
+	ops, got := rebuild(t, nil, target, 1024)
+	if !bytes.Equal(got, target) {
+		t.Fatal("rebuilt data does not match target")
+	}
+	if len(ops) != 1 || ops[0].Kind != OpLiteral || ops[0].Length != int64(len(target)) {
+		t.Errorf("expected a single literal op, got %+v", ops)
+	}
+}
+
+func TestParseSettings(t *testing.T) {
+	opts, err := ParseSettings(map[string]string{
+		SettingBlockSize: "4096",
+		SettingThreshold: "100",
+	})
+	if err != nil {
+		t.Fatalf("ParseSettings: %v", err)
+	}
+	if opts.Disabled || opts.BlockSize != 4096 || opts.Threshold != 100 {
+		t.Errorf("unexpected options: %+v", opts)
+	}
+	if opts.Applies(99) || !opts.Applies(100) {
+		t.Error("threshold not applied")
+	}
+
+	opts, err = ParseSettings(map[string]string{SettingEnabled: "false"})
+	if err != nil {
+		t.Fatalf("ParseSettings: %v", err)
+	}
+	if opts.Applies(1 << 40) {
+		t.Error("expected delta sync to be disabled")
+	}
+	if (&Options{}).Applies(DefaultThreshold-1) || !(&Options{}).Applies(DefaultThreshold) {
+		t.Error("default threshold not applied")
+	}
+
+	for _, settings := range []map[string]string{
+		{SettingEnabled: "maybe"},
+		{SettingBlockSize: "0"},
+		{SettingThreshold: "-1"},
+	} {
+		if _, err := ParseSettings(settings); !errors.Is(err, common.ErrInvalidArgument) {
+			t.Errorf("expected ErrInvalidArgument for %v, got %v", settings, err)
+		}
+	}
+}
+
+func TestReadRange(t *testing.T) {
+	base := []byte("the quick brown fox")
+	target := []byte("jumps over")
+
+	got, err := ReadRange(context.Background(), bytes.NewReader(base), Op{Kind: OpCopy, Offset: 4, Length: 5}, BytesSource(target))
+	if err != nil || string(got) != "quick" {
+		t.Errorf("copy range: %q %v", got, err)
+	}
+	got, err = ReadRange(context.Background(), bytes.NewReader(base), Op{Kind: OpLiteral, Offset: 6, Length: 4}, BytesSource(target))
+	if err != nil || string(got) != "over" {
+		t.Errorf("literal range: %q %v", got, err)
+	}
+	if _, err := ReadRange(context.Background(), nil, Op{Kind: OpLiteral, Offset: 8, Length: 4}, BytesSource(target)); !errors.Is(err, common.ErrInvalidArgument) {
+		t.Errorf("expected ErrInvalidArgument, got %v", err)
+	}
+}
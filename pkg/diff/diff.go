@@ -0,0 +1,297 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package diff compares two objects. Text is compared line by line and the
+// differences are returned as a unified diff; JSON documents are compared
+// structurally, reporting each added, removed or replaced value by its JSON
+// Pointer path; anything else is compared byte by byte, reporting the first
+// offset at which the objects differ.
+//
+// Binary comparison streams both objects. Text and JSON comparison holds
+// both in memory, so it is bounded by Options.MaxSize; in ModeAuto, objects
+// larger than that are compared as binary instead.
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// Default options.
+const (
+	DefaultMaxSize    = 16 << 20
+	DefaultMaxChanges = 1000
+	DefaultContext    = 3
+)
+
+// Mode selects how objects are compared.
+type Mode string
+
+// Comparison modes.
+const (
+	// ModeAuto compares JSON documents as JSON, other UTF-8 text as text
+	// and anything else as binary.
+	ModeAuto   Mode = ""
+	ModeText   Mode = "text"
+	ModeJSON   Mode = "json"
+	ModeBinary Mode = "binary"
+)
+
+var (
+	// ErrInvalidMode is returned for an unknown comparison mode.
+	ErrInvalidMode = fmt.Errorf("%w: diff mode must be text, json or binary", common.ErrInvalidArgument)
+
+	// ErrNotJSON is returned when a JSON comparison is requested for an
+	// object that is not a JSON document.
+	ErrNotJSON = fmt.Errorf("%w: object is not a JSON document", common.ErrInvalidArgument)
+
+	// ErrTooLarge is returned when a text or JSON comparison is requested
+	// for an object larger than Options.MaxSize. It wraps
+	// common.ErrResourceExhausted.
+	ErrTooLarge = fmt.Errorf("%w: object too large to diff", common.ErrResourceExhausted)
+)
+
+// ParseMode parses a comparison mode; the empty string is ModeAuto.
+func ParseMode(s string) (Mode, error) {
+	switch mode := Mode(strings.ToLower(strings.TrimSpace(s))); mode {
+	case ModeAuto, ModeText, ModeJSON, ModeBinary:
+		return mode, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrInvalidMode, s)
+}
+
+// SplitVersion splits a reference of the form key@time, where time is an
+// RFC 3339 time, into the key and the time. A reference without a valid
+// time suffix is returned whole, so keys may themselves contain "@".
+func SplitVersion(ref string) (key string, at time.Time, ok bool) {
+	i := strings.LastIndexByte(ref, '@')
+	if i <= 0 {
+		return ref, time.Time{}, false
+	}
+	at, err := time.Parse(time.RFC3339, ref[i+1:])
+	if err != nil {
+		return ref, time.Time{}, false
+	}
+	return ref[:i], at, true
+}
+
+// Options configures a comparison. Zero fields take their defaults.
+type Options struct {
+	// Mode selects the comparison (default ModeAuto).
+	Mode Mode
+
+	// Context is the number of unchanged lines shown around each change
+	// in a text diff (default DefaultContext).
+	Context int
+
+	// MaxSize bounds the size of each object in a text or JSON
+	// comparison (default DefaultMaxSize).
+	MaxSize int64
+
+	// MaxChanges bounds the changed lines of a text diff and the changes
+	// of a JSON diff that are reported (default DefaultMaxChanges).
+	MaxChanges int
+}
+
+func (o Options) withDefaults() Options {
+	if o.Context <= 0 {
+		o.Context = DefaultContext
+	}
+	if o.MaxSize <= 0 {
+		o.MaxSize = DefaultMaxSize
+	}
+	if o.MaxChanges <= 0 {
+		o.MaxChanges = DefaultMaxChanges
+	}
+	return o
+}
+
+// Source is one side of a comparison.
+type Source struct {
+	// Name labels the object in a unified diff.
+	Name   string
+	Reader io.Reader
+}
+
+// Op is the kind of a JSON change.
+type Op string
+
+// JSON change kinds, named as in JSON Patch.
+const (
+	OpAdd     Op = "add"
+	OpRemove  Op = "remove"
+	OpReplace Op = "replace"
+)
+
+// Change is a difference between two JSON documents.
+type Change struct {
+	Op Op `json:"op"`
+	// Path is the JSON Pointer of the value; "" is the whole document.
+	Path string          `json:"path"`
+	Old  json.RawMessage `json:"old,omitempty"`
+	New  json.RawMessage `json:"new,omitempty"`
+}
+
+// Result is the outcome of a comparison.
+type Result struct {
+	Mode      Mode  `json:"mode"`
+	Identical bool  `json:"identical"`
+	SizeA     int64 `json:"size_a"`
+	SizeB     int64 `json:"size_b"`
+
+	// Unified is the unified diff of a text comparison.
+	Unified string `json:"unified,omitempty"`
+
+	// Changes are the differences found by a JSON comparison.
+	Changes []Change `json:"changes,omitempty"`
+
+	// FirstDifference is the offset of the first differing byte of a
+	// binary comparison; when one object is a prefix of the other it is
+	// the length of the shorter one.
+	FirstDifference *int64 `json:"first_difference,omitempty"`
+
+	// Truncated reports that more than Options.MaxChanges differences
+	// were found and only the first were kept.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// Compare compares a with b.
+func Compare(a, b Source, opts Options) (*Result, error) {
+	if _, err := ParseMode(string(opts.Mode)); err != nil {
+		return nil, err
+	}
+	opts = opts.withDefaults()
+	if opts.Mode == ModeBinary {
+		return compareBinary(a.Reader, b.Reader)
+	}
+
+	dataA, overA, err := readLimited(a.Reader, opts.MaxSize)
+	if err != nil {
+		return nil, err
+	}
+	dataB, overB, err := readLimited(b.Reader, opts.MaxSize)
+	if err != nil {
+		return nil, err
+	}
+	if overA || overB {
+		if opts.Mode != ModeAuto {
+			return nil, fmt.Errorf("%w: the limit is %d bytes", ErrTooLarge, opts.MaxSize)
+		}
+		return compareBinary(
+			io.MultiReader(bytes.NewReader(dataA), a.Reader),
+			io.MultiReader(bytes.NewReader(dataB), b.Reader))
+	}
+
+	mode := opts.Mode
+	if mode == ModeAuto {
+		mode = detect(dataA, dataB)
+	}
+	switch mode {
+	case ModeJSON:
+		return compareJSON(dataA, dataB, opts)
+	case ModeText:
+		return compareText(a.Name, dataA, b.Name, dataB, opts), nil
+	default:
+		return compareBinary(bytes.NewReader(dataA), bytes.NewReader(dataB))
+	}
+}
+
+// readLimited reads up to limit bytes of r, reporting whether there was more.
+// The byte read past limit stays in the returned data.
+func readLimited(r io.Reader, limit int64) ([]byte, bool, error) {
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, false, err
+	}
+	return data, int64(len(data)) > limit, nil
+}
+
+// detect picks the comparison mode for two objects.
+func detect(a, b []byte) Mode {
+	switch {
+	case isJSONDocument(a) && isJSONDocument(b):
+		return ModeJSON
+	case isText(a) && isText(b):
+		return ModeText
+	default:
+		return ModeBinary
+	}
+}
+
+// isJSONDocument reports whether data is a JSON object or array.
+func isJSONDocument(data []byte) bool {
+	trimmed := bytes.TrimSpace(data)
+	return len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') && json.Valid(trimmed)
+}
+
+func isText(data []byte) bool {
+	return utf8.Valid(data) && bytes.IndexByte(data, 0) < 0
+}
+
+// compareBinary streams a and b and reports where they first differ.
+func compareBinary(a, b io.Reader) (*Result, error) {
+	const chunkSize = 32 << 10
+	result := &Result{Mode: ModeBinary}
+	bufA := make([]byte, chunkSize)
+	bufB := make([]byte, chunkSize)
+	first := int64(-1)
+	for {
+		na, err := readChunk(a, bufA)
+		if err != nil {
+			return nil, err
+		}
+		nb, err := readChunk(b, bufB)
+		if err != nil {
+			return nil, err
+		}
+		if first < 0 {
+			n := min(na, nb)
+			for i := 0; i < n; i++ {
+				if bufA[i] != bufB[i] {
+					first = result.SizeA + int64(i)
+					break
+				}
+			}
+			if first < 0 && na != nb {
+				first = result.SizeA + int64(n)
+			}
+		}
+		result.SizeA += int64(na)
+		result.SizeB += int64(nb)
+		if na < chunkSize && nb < chunkSize {
+			break
+		}
+	}
+	result.Identical = first < 0
+	if !result.Identical {
+		result.FirstDifference = &first
+	}
+	return result, nil
+}
+
+// readChunk fills buf unless r ends first.
+func readChunk(r io.Reader, buf []byte) (int, error) {
+	n, err := io.ReadFull(r, buf)
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		err = nil
+	}
+	return n, err
+}
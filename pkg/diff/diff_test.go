@@ -0,0 +1,245 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package diff
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+func compare(t *testing.T, a, b string, opts Options) *Result {
+	t.Helper()
+	result, err := Compare(
+		Source{Name: "a", Reader: strings.NewReader(a)},
+		Source{Name: "b", Reader: strings.NewReader(b)},
+		opts)
+	if err != nil {
+		t.Fatalf("Compare() error = %v", err)
+	}
+	return result
+}
+
+func TestCompare_Text(t *testing.T) {
+	a := "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\n"
+	b := "one\ntwo\nTHREE\nfour\nfive\nsix\nseven\neight\nnine\nten\neleven\n"
+	result := compare(t, a, b, Options{})
+	if result.Mode != ModeText || result.Identical {
+		t.Fatalf("Expected a text difference, got %+v", result)
+	}
+	want := `--- a
++++ b
+@@ -1,6 +1,6 @@
+ one
+ two
+-three
++THREE
+ four
+ five
+ six
+@@ -8,3 +8,4 @@
+ eight
+ nine
+ ten
++eleven
+`
+	if result.Unified != want {
+		t.Errorf("Unified =\n%s\nwant\n%s", result.Unified, want)
+	}
+
+	// Changes within twice the context share a hunk.
+	result = compare(t, a, b, Options{Context: 4})
+	if n := strings.Count(result.Unified, "@@ -"); n != 1 {
+		t.Errorf("Expected one hunk with more context, got %d:\n%s", n, result.Unified)
+	}
+
+	if result := compare(t, a, a, Options{}); !result.Identical || result.Unified != "" {
+		t.Errorf("Expected identical texts, got %+v", result)
+	}
+}
+
+func TestCompare_TextEdges(t *testing.T) {
+	tests := []struct {
+		name, a, b, want string
+	}{
+		{"from empty", "", "x\n", "--- a\n+++ b\n@@ -0,0 +1 @@\n+x\n"},
+		{"to empty", "x\n", "", "--- a\n+++ b\n@@ -1 +0,0 @@\n-x\n"},
+		{"missing newline", "x\n", "x", "--- a\n+++ b\n@@ -1 +1 @@\n-x\n+x\n\\ No newline at end of file\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := compare(t, tt.a, tt.b, Options{Mode: ModeText})
+			if result.Unified != tt.want {
+				t.Errorf("Unified =\n%q\nwant\n%q", result.Unified, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompare_TextTruncated(t *testing.T) {
+	var a, b strings.Builder
+	for i := 0; i < 50; i++ {
+		fmt.Fprintf(&a, "a%d\n", i)
+		fmt.Fprintf(&b, "b%d\n", i)
+	}
+	result := compare(t, a.String(), b.String(), Options{MaxChanges: 10})
+	if !result.Truncated {
+		t.Fatal("Expected a truncated diff")
+	}
+	changed := 0
+	for _, line := range strings.Split(result.Unified, "\n")[2:] {
+		if strings.HasPrefix(line, "-") || strings.HasPrefix(line, "+") {
+			changed++
+		}
+	}
+	if changed != 10 {
+		t.Errorf("Expected 10 changed lines, got %d", changed)
+	}
+	if !strings.Contains(result.Unified, "@@ -1,10 +0,0 @@") {
+		t.Errorf("Expected the hunk header to count the lines shown:\n%s", result.Unified)
+	}
+}
+
+func TestCompare_JSON(t *testing.T) {
+	a := `{"name":"api","replicas":2,"ports":[80,443],"env":{"a/b":"1","debug":true},"ratio":1}`
+	b := `{"name":"api","replicas":3,"ports":[80],"env":{"a/b":"2"},"ratio":1.0,"tier":"gold"}`
+	result := compare(t, a, b, Options{})
+	if result.Mode != ModeJSON || result.Identical {
+		t.Fatalf("Expected a JSON difference, got %+v", result)
+	}
+	var got []string
+	for _, c := range result.Changes {
+		got = append(got, fmt.Sprintf("%s %s %s %s", c.Op, c.Path, string(c.Old), string(c.New)))
+	}
+	want := []string{
+		"replace /env/a~1b \"1\" \"2\"",
+		"remove /env/debug true ",
+		"remove /ports/1 443 ",
+		"replace /replicas 2 3",
+		"add /tier  \"gold\"",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Changes =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if result := compare(t, a, b, Options{MaxChanges: 2}); len(result.Changes) != 2 || !result.Truncated {
+		t.Errorf("Expected 2 changes and truncation, got %+v", result)
+	}
+	if result := compare(t, `[1, {"x": null}]`, "[1,{\"x\":null}]\n", Options{}); !result.Identical {
+		t.Errorf("Expected formatting to be ignored, got %+v", result.Changes)
+	}
+
+	_, err := Compare(Source{Reader: strings.NewReader("{")}, Source{Reader: strings.NewReader("{}")}, Options{Mode: ModeJSON})
+	if !errors.Is(err, ErrNotJSON) || !errors.Is(err, common.ErrInvalidArgument) {
+		t.Errorf("Expected ErrNotJSON, got %v", err)
+	}
+}
+
+func TestCompare_Binary(t *testing.T) {
+	tests := []struct {
+		name  string
+		a, b  []byte
+		first int64
+	}{
+		{"identical", bytes.Repeat([]byte{1}, 100000), bytes.Repeat([]byte{1}, 100000), -1},
+		{"differs", []byte{0, 1, 2, 3}, []byte{0, 1, 9, 3}, 2},
+		{"prefix", bytes.Repeat([]byte{0}, 70000), bytes.Repeat([]byte{0}, 70001), 70000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Compare(Source{Reader: bytes.NewReader(tt.a)}, Source{Reader: bytes.NewReader(tt.b)}, Options{Mode: ModeBinary})
+			if err != nil {
+				t.Fatalf("Compare() error = %v", err)
+			}
+			if result.Mode != ModeBinary || result.SizeA != int64(len(tt.a)) || result.SizeB != int64(len(tt.b)) {
+				t.Errorf("Compare() = %+v", result)
+			}
+			switch {
+			case tt.first < 0 && !result.Identical:
+				t.Errorf("Expected identical objects, got %d", *result.FirstDifference)
+			case tt.first >= 0 && (result.FirstDifference == nil || *result.FirstDifference != tt.first):
+				t.Errorf("FirstDifference = %v, want %d", result.FirstDifference, tt.first)
+			}
+		})
+	}
+}
+
+func TestCompare_MaxSize(t *testing.T) {
+	a, b := strings.Repeat("a\n", 100), strings.Repeat("a\n", 99)+"b\n"
+
+	// Auto mode falls back to a streamed binary comparison.
+	result := compare(t, a, b, Options{MaxSize: 64})
+	if result.Mode != ModeBinary || result.FirstDifference == nil || *result.FirstDifference != 198 {
+		t.Errorf("Expected a binary comparison, got %+v", result)
+	}
+
+	_, err := Compare(Source{Reader: strings.NewReader(a)}, Source{Reader: strings.NewReader(b)}, Options{Mode: ModeText, MaxSize: 64})
+	if !errors.Is(err, ErrTooLarge) || !errors.Is(err, common.ErrResourceExhausted) {
+		t.Errorf("Expected ErrTooLarge, got %v", err)
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	for _, s := range []string{"", "text", "JSON", "binary"} {
+		if _, err := ParseMode(s); err != nil {
+			t.Errorf("ParseMode(%q) error = %v", s, err)
+		}
+	}
+	if _, err := ParseMode("xml"); !errors.Is(err, ErrInvalidMode) {
+		t.Errorf("Expected ErrInvalidMode, got %v", err)
+	}
+}
+
+func TestSplitVersion(t *testing.T) {
+	key, at, ok := SplitVersion("config/app.yaml@2025-06-01T12:00:00Z")
+	if !ok || key != "config/app.yaml" || !at.Equal(time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("SplitVersion() = %q, %v, %v", key, at, ok)
+	}
+	for _, ref := range []string{"users/me@example.com", "@2025-06-01T12:00:00Z", "plain.txt"} {
+		if key, _, ok := SplitVersion(ref); ok || key != ref {
+			t.Errorf("SplitVersion(%q) = %q, %v, want the reference unchanged", ref, key, ok)
+		}
+	}
+}
+
+func TestLineEdits(t *testing.T) {
+	texts := []string{"", "a", "a b", "b a", "a b c a b b a", "c b a b a c", "x a y b z", "a a a", "b"}
+	for _, from := range texts {
+		for _, to := range texts {
+			a, b := strings.Fields(from), strings.Fields(to)
+			for _, maxEdits := range []int{1, 100} {
+				var gotA, gotB []string
+				for _, e := range lineEdits(a, b, maxEdits) {
+					if e.kind == editEqual && a[e.a] != b[e.b] {
+						t.Errorf("lineEdits(%q, %q, %d) keeps %q as %q", from, to, maxEdits, a[e.a], b[e.b])
+					}
+					if e.kind != editInsert {
+						gotA = append(gotA, a[e.a])
+					}
+					if e.kind != editDelete {
+						gotB = append(gotB, b[e.b])
+					}
+				}
+				if strings.Join(gotA, " ") != from || strings.Join(gotB, " ") != to {
+					t.Errorf("lineEdits(%q, %q, %d) rebuilds %q, %q", from, to, maxEdits, gotA, gotB)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// compareJSON compares two JSON documents value by value. Objects are
+// compared by member name and arrays by index.
+func compareJSON(a, b []byte, opts Options) (*Result, error) {
+	valueA, err := decodeJSON(a)
+	if err != nil {
+		return nil, err
+	}
+	valueB, err := decodeJSON(b)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &jsonDiff{limit: opts.MaxChanges}
+	d.walk("", valueA, valueB)
+	return &Result{
+		Mode:      ModeJSON,
+		Identical: len(d.changes) == 0,
+		SizeA:     int64(len(a)),
+		SizeB:     int64(len(b)),
+		Changes:   d.changes,
+		Truncated: d.truncated,
+	}, nil
+}
+
+// decodeJSON decodes a single JSON value, keeping numbers as written.
+func decodeJSON(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotJSON, err)
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("%w: more than one value", ErrNotJSON)
+	}
+	return value, nil
+}
+
+type jsonDiff struct {
+	limit     int
+	changes   []Change
+	truncated bool
+}
+
+func (d *jsonDiff) walk(path string, a, b any) {
+	if d.truncated {
+		return
+	}
+	switch va := a.(type) {
+	case map[string]any:
+		if vb, ok := b.(map[string]any); ok {
+			d.walkObject(path, va, vb)
+			return
+		}
+	case []any:
+		if vb, ok := b.([]any); ok {
+			d.walkArray(path, va, vb)
+			return
+		}
+	}
+	if !jsonEqual(a, b) {
+		d.add(Change{Op: OpReplace, Path: path, Old: marshal(a), New: marshal(b)})
+	}
+}
+
+func (d *jsonDiff) walkObject(path string, a, b map[string]any) {
+	names := make([]string, 0, len(a)+len(b))
+	for name := range a {
+		names = append(names, name)
+	}
+	for name := range b {
+		if _, ok := a[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		member := path + "/" + escapePointer(name)
+		va, inA := a[name]
+		vb, inB := b[name]
+		switch {
+		case !inB:
+			d.add(Change{Op: OpRemove, Path: member, Old: marshal(va)})
+		case !inA:
+			d.add(Change{Op: OpAdd, Path: member, New: marshal(vb)})
+		default:
+			d.walk(member, va, vb)
+		}
+	}
+}
+
+func (d *jsonDiff) walkArray(path string, a, b []any) {
+	for i := 0; i < max(len(a), len(b)); i++ {
+		element := path + "/" + strconv.Itoa(i)
+		switch {
+		case i >= len(b):
+			d.add(Change{Op: OpRemove, Path: element, Old: marshal(a[i])})
+		case i >= len(a):
+			d.add(Change{Op: OpAdd, Path: element, New: marshal(b[i])})
+		default:
+			d.walk(element, a[i], b[i])
+		}
+	}
+}
+
+func (d *jsonDiff) add(change Change) {
+	if len(d.changes) == d.limit {
+		d.truncated = true
+		return
+	}
+	d.changes = append(d.changes, change)
+}
+
+// jsonEqual compares two scalar or mismatched JSON values. Numbers are
+// equal when they denote the same value, so 1 and 1.0 are.
+func jsonEqual(a, b any) bool {
+	na, okA := a.(json.Number)
+	nb, okB := b.(json.Number)
+	if okA && okB {
+		if na == nb {
+			return true
+		}
+		fa, errA := na.Float64()
+		fb, errB := nb.Float64()
+		return errA == nil && errB == nil && fa == fb
+	}
+	return bytes.Equal(marshal(a), marshal(b))
+}
+
+func marshal(value any) json.RawMessage {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// escapePointer escapes a member name for a JSON Pointer (RFC 6901).
+func escapePointer(name string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+}
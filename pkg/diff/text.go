@@ -0,0 +1,251 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package diff
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+type editKind int
+
+const (
+	editEqual editKind = iota
+	editDelete
+	editInsert
+)
+
+// edit is one step of an edit script: line a of the old text is kept or
+// deleted, or line b of the new text is inserted.
+type edit struct {
+	kind editKind
+	a, b int
+}
+
+// compareText compares a and b line by line.
+func compareText(nameA string, a []byte, nameB string, b []byte, opts Options) *Result {
+	result := &Result{Mode: ModeText, SizeA: int64(len(a)), SizeB: int64(len(b))}
+	if bytes.Equal(a, b) {
+		result.Identical = true
+		return result
+	}
+	linesA, linesB := splitLines(a), splitLines(b)
+	edits := lineEdits(linesA, linesB, opts.MaxChanges)
+	result.Unified, result.Truncated = unified(nameA, linesA, nameB, linesB, edits, opts)
+	return result
+}
+
+// splitLines splits data after each newline. A last line without one is
+// kept as is, so it differs from the same line with a newline.
+func splitLines(data []byte) []string {
+	if len(data) == 0 {
+		return nil
+	}
+	lines := strings.SplitAfter(string(data), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// lineEdits returns an edit script turning a into b. Common leading and
+// trailing lines are matched first and the rest is diffed with Myers'
+// algorithm. When that needs more than maxEdits inserted and deleted
+// lines, the remaining lines of a are all deleted and those of b inserted
+// instead, which bounds the time and memory spent on unrelated texts.
+func lineEdits(a, b []string, maxEdits int) []edit {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	edits := make([]edit, 0, len(a)+len(b)-prefix-suffix)
+	for i := 0; i < prefix; i++ {
+		edits = append(edits, edit{editEqual, i, i})
+	}
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if mid, ok := myers(midA, midB, maxEdits); ok {
+		for _, e := range mid {
+			edits = append(edits, edit{e.kind, e.a + prefix, e.b + prefix})
+		}
+	} else {
+		for i := range midA {
+			edits = append(edits, edit{editDelete, prefix + i, prefix})
+		}
+		for j := range midB {
+			edits = append(edits, edit{editInsert, len(a) - suffix, prefix + j})
+		}
+	}
+	for i := 0; i < suffix; i++ {
+		edits = append(edits, edit{editEqual, len(a) - suffix + i, len(b) - suffix + i})
+	}
+	return edits
+}
+
+// myers returns a shortest edit script turning a into b, or false if it
+// needs more than maxD inserted and deleted lines.
+func myers(a, b []string, maxD int) ([]edit, bool) {
+	n, m := len(a), len(b)
+	// v[off+k] is the furthest x reached on diagonal k = x - y.
+	off := maxD + 1
+	v := make([]int, 2*maxD+3)
+	var trace [][]int
+	for d := 0; d <= maxD; d++ {
+		// Keep the endpoints reached with d-1 edits for backtracking;
+		// trace[d][k+d+1] is diagonal k.
+		snapshot := make([]int, 2*d+3)
+		copy(snapshot, v[off-d-1:off+d+2])
+		trace = append(trace, snapshot)
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[off+k-1] < v[off+k+1]) {
+				x = v[off+k+1]
+			} else {
+				x = v[off+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[off+k] = x
+			if x >= n && y >= m {
+				return backtrack(trace, n, m), true
+			}
+		}
+	}
+	return nil, false
+}
+
+// backtrack follows trace back from (n, m) and returns the edit script.
+func backtrack(trace [][]int, n, m int) []edit {
+	var edits []edit
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		at := func(k int) int { return v[k+d+1] }
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+			edits = append(edits, edit{editEqual, x, y})
+		}
+		if d > 0 {
+			if x == prevX {
+				edits = append(edits, edit{editInsert, x, prevY})
+			} else {
+				edits = append(edits, edit{editDelete, prevX, y})
+			}
+		}
+		x, y = prevX, prevY
+	}
+	slices.Reverse(edits)
+	return edits
+}
+
+// unified formats edits as a unified diff with opts.Context lines of
+// context, stopping after opts.MaxChanges changed lines.
+func unified(nameA string, a []string, nameB string, b []string, edits []edit, opts Options) (string, bool) {
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", nameA, nameB)
+
+	// posA[i] and posB[i] count the lines of a and b before edit i.
+	posA := make([]int, len(edits)+1)
+	posB := make([]int, len(edits)+1)
+	var changed []int
+	for i, e := range edits {
+		posA[i+1], posB[i+1] = posA[i], posB[i]
+		if e.kind != editInsert {
+			posA[i+1]++
+		}
+		if e.kind != editDelete {
+			posB[i+1]++
+		}
+		if e.kind != editEqual {
+			changed = append(changed, i)
+		}
+	}
+
+	emitted := 0
+	truncated := false
+	for i := 0; i < len(changed) && !truncated; {
+		// Changes separated by at most twice the context share a hunk.
+		j := i
+		for j+1 < len(changed) && changed[j+1]-changed[j]-1 <= 2*opts.Context {
+			j++
+		}
+		start := max(changed[i]-opts.Context, 0)
+		end := min(changed[j]+1+opts.Context, len(edits))
+
+		var body strings.Builder
+		countA, countB := 0, 0
+		for _, e := range edits[start:end] {
+			if e.kind == editEqual {
+				writeLine(&body, ' ', a[e.a])
+				countA++
+				countB++
+				continue
+			}
+			if emitted == opts.MaxChanges {
+				truncated = true
+				break
+			}
+			emitted++
+			if e.kind == editDelete {
+				writeLine(&body, '-', a[e.a])
+				countA++
+			} else {
+				writeLine(&body, '+', b[e.b])
+				countB++
+			}
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(posA[start], countA), hunkRange(posB[start], countB))
+		out.WriteString(body.String())
+		i = j + 1
+	}
+	return out.String(), truncated
+}
+
+// hunkRange formats the line range of a hunk that follows line start.
+func hunkRange(start, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	default:
+		return fmt.Sprintf("%d,%d", start+1, count)
+	}
+}
+
+func writeLine(out *strings.Builder, prefix byte, line string) {
+	out.WriteByte(prefix)
+	out.WriteString(line)
+	if !strings.HasSuffix(line, "\n") {
+		out.WriteString("\n\\ No newline at end of file\n")
+	}
+}
@@ -0,0 +1,365 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package download fetches large objects as concurrent ranged parts and
+// reassembles them in order. Backends opt in by implementing
+// common.RangeReader; everything else (and objects below the threshold) is
+// streamed with a single Get.
+package download
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+const (
+	// DefaultPartSize is the size of each ranged part (8 MiB).
+	DefaultPartSize int64 = 8 * 1024 * 1024
+
+	// DefaultConcurrency is the number of parts fetched in parallel.
+	DefaultConcurrency = 4
+)
+
+// Setting keys understood by ParseSettings. Both are optional.
+const (
+	// SettingPartSize is the ranged part size in bytes.
+	SettingPartSize = "downloadPartSize"
+
+	// SettingConcurrency is the number of parts fetched in parallel.
+	SettingConcurrency = "downloadConcurrency"
+)
+
+// ErrObjectChanged is returned when the object is overwritten or deleted
+// while its parts are being fetched. It wraps common.ErrPreconditionFailed.
+var ErrObjectChanged = fmt.Errorf("%w: object changed during download", common.ErrPreconditionFailed)
+
+// Options configures a Manager.
+type Options struct {
+	// PartSize is the size of each ranged request in bytes.
+	// Zero uses DefaultPartSize.
+	PartSize int64
+
+	// Concurrency is the number of parts fetched in parallel. Zero uses
+	// DefaultConcurrency; 1 disables parallel downloads.
+	Concurrency int
+
+	// Threshold is the minimum object size for a parallel download. Smaller
+	// objects use a single Get. Zero uses twice the part size.
+	Threshold int64
+}
+
+// ParseSettings builds Options from a settings map such as a replication
+// policy's source settings. Malformed values are rejected with an error
+// wrapping common.ErrInvalidArgument.
+func ParseSettings(settings map[string]string) (*Options, error) {
+	opts := &Options{}
+	if v := settings[SettingPartSize]; v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("%w: %s must be a positive integer, got %q", common.ErrInvalidArgument, SettingPartSize, v)
+		}
+		opts.PartSize = n
+	}
+	if v := settings[SettingConcurrency]; v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("%w: %s must be a positive integer, got %q", common.ErrInvalidArgument, SettingConcurrency, v)
+		}
+		opts.Concurrency = n
+	}
+	return opts, nil
+}
+
+// Manager downloads objects using parallel ranged reads. It is safe for
+// concurrent use.
+type Manager struct {
+	partSize    int64
+	concurrency int
+	threshold   int64
+}
+
+// NewManager creates a Manager. A nil opts uses the defaults.
+func NewManager(opts *Options) *Manager {
+	if opts == nil {
+		opts = &Options{}
+	}
+	m := &Manager{
+		partSize:    opts.PartSize,
+		concurrency: opts.Concurrency,
+		threshold:   opts.Threshold,
+	}
+	if m.partSize <= 0 {
+		m.partSize = DefaultPartSize
+	}
+	if m.concurrency <= 0 {
+		m.concurrency = DefaultConcurrency
+	}
+	if m.threshold <= 0 {
+		m.threshold = 2 * m.partSize
+	}
+	return m
+}
+
+// Download writes the object stored under key to w and returns the number of
+// bytes written. Parts are written strictly in order; when w implements
+// io.WriterAt, parts are written at their offsets as they arrive instead, so
+// callers writing to a non-seekable *os.File such as os.Stdout must hide the
+// WriteAt method. At most Concurrency parts are buffered in memory at any
+// time.
+//
+// Every part is checked against the ETag, size and modification time read
+// before the download starts, and is discarded before it is written if the
+// object has changed since; the download then fails with an error wrapping
+// ErrObjectChanged rather than mixing bytes from two versions.
+func (m *Manager) Download(ctx context.Context, storage common.Storage, key string, w io.Writer) (int64, error) {
+	rr, ok := storage.(common.RangeReader)
+	if !ok || m.concurrency < 2 {
+		return m.single(ctx, storage, key, w)
+	}
+
+	meta, err := storage.GetMetadata(ctx, key)
+	if err != nil || meta == nil || meta.Size < m.threshold {
+		// Without a reliable size there is nothing to split; let Get report
+		// any real error such as a missing key.
+		return m.single(ctx, storage, key, w)
+	}
+
+	src := &source{storage: storage, rr: rr, key: key, meta: meta}
+	if wa, ok := w.(io.WriterAt); ok {
+		return m.parallelAt(ctx, src, wa)
+	}
+	return m.parallelOrdered(ctx, src, w)
+}
+
+// Reader returns a stream of the object stored under key that is filled by a
+// parallel download in the background. Closing the reader aborts the
+// download.
+func (m *Manager) Reader(ctx context.Context, storage common.Storage, key string) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		_, err := m.Download(ctx, storage, key, pw)
+		_ = pw.CloseWithError(err)
+	}()
+	return pr
+}
+
+// single streams the object with one Get.
+func (m *Manager) single(ctx context.Context, storage common.Storage, key string, w io.Writer) (int64, error) {
+	rc, err := storage.GetWithContext(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = rc.Close() }()
+	return io.Copy(w, rc)
+}
+
+// part identifies one ranged request.
+type part struct {
+	index  int
+	offset int64
+	length int64
+}
+
+// parts splits size bytes into part-sized ranges.
+func (m *Manager) parts(size int64) []part {
+	n := int((size + m.partSize - 1) / m.partSize)
+	out := make([]part, n)
+	for i := range out {
+		off := int64(i) * m.partSize
+		out[i] = part{index: i, offset: off, length: min(m.partSize, size-off)}
+	}
+	return out
+}
+
+// source is the object being downloaded, pinned to the metadata read
+// before its parts were planned.
+type source struct {
+	storage common.Storage
+	rr      common.RangeReader
+	key     string
+	meta    *common.Metadata
+}
+
+// fetch reads one part fully into memory, then checks that the object is
+// still the version its parts were planned from; a part of an object that
+// has since been deleted reports ErrObjectChanged too. RangeReader has no
+// conditional form, so the check follows the read: any overwrite before or
+// during the read leaves metadata that no longer matches.
+func (s *source) fetch(ctx context.Context, p part) ([]byte, error) {
+	rc, err := s.rr.GetRange(ctx, s.key, p.offset, p.length)
+	if errors.Is(err, common.ErrNotFound) {
+		return nil, fmt.Errorf("part %d: %w: %s", p.index, ErrObjectChanged, s.key)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("part %d: %w", p.index, err)
+	}
+	defer func() { _ = rc.Close() }()
+
+	buf := make([]byte, p.length)
+	if _, err := io.ReadFull(rc, buf); err != nil {
+		return nil, fmt.Errorf("part %d: %w", p.index, err)
+	}
+
+	current, err := s.storage.GetMetadata(ctx, s.key)
+	if errors.Is(err, common.ErrNotFound) || (err == nil && !s.unchanged(current)) {
+		return nil, fmt.Errorf("part %d: %w: %s", p.index, ErrObjectChanged, s.key)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("part %d: %w", p.index, err)
+	}
+	return buf, nil
+}
+
+// unchanged reports whether current describes the version the download
+// started from.
+func (s *source) unchanged(current *common.Metadata) bool {
+	return current != nil &&
+		current.ETag == s.meta.ETag &&
+		current.Size == s.meta.Size &&
+		current.LastModified.Equal(s.meta.LastModified)
+}
+
+// parallelAt downloads parts concurrently and writes each at its offset.
+func (m *Manager) parallelAt(ctx context.Context, src *source, w io.WriterAt) (int64, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	jobs := make(chan part)
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	fail := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	for i := 0; i < m.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for p := range jobs {
+				buf, err := src.fetch(ctx, p)
+				if err == nil {
+					_, err = w.WriteAt(buf, p.offset)
+				}
+				if err != nil {
+					fail(err)
+					return
+				}
+			}
+		}()
+	}
+
+dispatch:
+	for _, p := range m.parts(src.meta.Size) {
+		select {
+		case jobs <- p:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return 0, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	return src.meta.Size, nil
+}
+
+// result carries a fetched part or its error to the ordered writer.
+type result struct {
+	data []byte
+	err  error
+}
+
+// parallelOrdered downloads parts concurrently and writes them to w in order.
+// A window of Concurrency parts bounds memory: part i+Concurrency is only
+// dispatched once part i has been written.
+func (m *Manager) parallelOrdered(ctx context.Context, src *source, w io.Writer) (int64, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	parts := m.parts(src.meta.Size)
+	results := make([]chan result, len(parts))
+	for i := range results {
+		results[i] = make(chan result, 1)
+	}
+
+	window := make(chan struct{}, m.concurrency)
+	jobs := make(chan part)
+	var wg sync.WaitGroup
+	for i := 0; i < m.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for p := range jobs {
+				data, err := src.fetch(ctx, p)
+				results[p.index] <- result{data: data, err: err}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for _, p := range parts {
+			select {
+			case window <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case jobs <- p:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	var written int64
+	for i := range parts {
+		var r result
+		select {
+		case r = <-results[i]:
+		case <-ctx.Done():
+			return written, ctx.Err()
+		}
+		if r.err != nil {
+			return written, r.err
+		}
+		n, err := w.Write(r.data)
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+		<-window
+	}
+	return written, nil
+}
@@ -0,0 +1,270 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package download
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+// countingStorage records how many ranged reads were issued and can inject a
+// failure for one offset.
+type countingStorage struct {
+	common.Storage
+	ranges  atomic.Int32
+	failAt  int64
+	failErr error
+}
+
+func (s *countingStorage) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	s.ranges.Add(1)
+	if s.failErr != nil && offset == s.failAt {
+		return nil, s.failErr
+	}
+	return s.Storage.(common.RangeReader).GetRange(ctx, key, offset, length)
+}
+
+// plainStorage hides the RangeReader implementation of the wrapped storage.
+type plainStorage struct {
+	common.Storage
+}
+
+func newStorage(t *testing.T, key string, size int) (*countingStorage, []byte) {
+	t.Helper()
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	s := memory.New()
+	if err := s.Put(key, bytes.NewReader(data)); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	return &countingStorage{Storage: s}, data
+}
+
+func TestDownload_Ordered(t *testing.T) {
+	s, data := newStorage(t, "big", 10*1024+17)
+	m := NewManager(&Options{PartSize: 1024, Concurrency: 3})
+
+	var buf bytes.Buffer
+	n, err := m.Download(context.Background(), s, "big", &buf)
+	if err != nil {
+		t.Fatalf("Download: %v", err)
+	}
+	if n != int64(len(data)) || !bytes.Equal(buf.Bytes(), data) {
+		t.Fatalf("content mismatch: wrote %d of %d bytes", n, len(data))
+	}
+	if got := s.ranges.Load(); got != 11 {
+		t.Errorf("expected 11 ranged reads, got %d", got)
+	}
+}
+
+func TestDownload_WriterAt(t *testing.T) {
+	s, data := newStorage(t, "big", 8*1024)
+	m := NewManager(&Options{PartSize: 1000, Concurrency: 4})
+
+	f, err := os.Create(filepath.Join(t.TempDir(), "out"))
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	defer f.Close()
+
+	if _, err := m.Download(context.Background(), s, "big", f); err != nil {
+		t.Fatalf("Download: %v", err)
+	}
+	got, err := os.ReadFile(f.Name())
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatal("content mismatch")
+	}
+}
+
+func TestDownload_SmallObjectUsesSingleGet(t *testing.T) {
+	s, data := newStorage(t, "small", 1500)
+	m := NewManager(&Options{PartSize: 1024})
+
+	var buf bytes.Buffer
+	if _, err := m.Download(context.Background(), s, "small", &buf); err != nil {
+		t.Fatalf("Download: %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), data) {
+		t.Fatal("content mismatch")
+	}
+	if got := s.ranges.Load(); got != 0 {
+		t.Errorf("expected no ranged reads, got %d", got)
+	}
+}
+
+func TestDownload_FallbackWithoutRangeReader(t *testing.T) {
+	s, data := newStorage(t, "big", 4096)
+	m := NewManager(&Options{PartSize: 512})
+
+	var buf bytes.Buffer
+	if _, err := m.Download(context.Background(), plainStorage{s.Storage}, "big", &buf); err != nil {
+		t.Fatalf("Download: %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), data) {
+		t.Fatal("content mismatch")
+	}
+}
+
+func TestDownload_MissingKey(t *testing.T) {
+	s, _ := newStorage(t, "big", 16)
+	_, err := NewManager(nil).Download(context.Background(), s, "missing", io.Discard)
+	if !errors.Is(err, common.ErrKeyNotFound) {
+		t.Fatalf("expected ErrKeyNotFound, got %v", err)
+	}
+}
+
+func TestDownload_PartError(t *testing.T) {
+	boom := errors.New("boom")
+	for name, w := range map[string]func(t *testing.T) io.Writer{
+		"ordered": func(*testing.T) io.Writer { return &bytes.Buffer{} },
+		"writerAt": func(t *testing.T) io.Writer {
+			f, err := os.Create(filepath.Join(t.TempDir(), "out"))
+			if err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+			t.Cleanup(func() { _ = f.Close() })
+			return f
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			s, _ := newStorage(t, "big", 8*1024)
+			s.failAt, s.failErr = 3*1024, boom
+			m := NewManager(&Options{PartSize: 1024, Concurrency: 2})
+
+			if _, err := m.Download(context.Background(), s, "big", w(t)); !errors.Is(err, boom) {
+				t.Fatalf("expected injected error, got %v", err)
+			}
+		})
+	}
+}
+
+// overwritingStorage replaces the object with new content just before the
+// ranged read at overwriteAt, as a concurrent writer would.
+type overwritingStorage struct {
+	*countingStorage
+	overwriteAt int64
+	content     []byte
+	once        sync.Once
+}
+
+func (s *overwritingStorage) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	if offset == s.overwriteAt {
+		s.once.Do(func() {
+			_ = s.Storage.Put(key, bytes.NewReader(s.content))
+		})
+	}
+	return s.countingStorage.GetRange(ctx, key, offset, length)
+}
+
+func TestDownload_ObjectOverwritten(t *testing.T) {
+	for name, w := range map[string]func(t *testing.T) io.Writer{
+		"ordered": func(*testing.T) io.Writer { return &bytes.Buffer{} },
+		"writerAt": func(t *testing.T) io.Writer {
+			f, err := os.Create(filepath.Join(t.TempDir(), "out"))
+			if err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+			t.Cleanup(func() { _ = f.Close() })
+			return f
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			s, data := newStorage(t, "big", 8*1024)
+			replacement := bytes.Repeat([]byte{0xff}, len(data))
+			store := &overwritingStorage{countingStorage: s, overwriteAt: 4 * 1024, content: replacement}
+			m := NewManager(&Options{PartSize: 1024, Concurrency: 2})
+
+			out := w(t)
+			_, err := m.Download(context.Background(), store, "big", out)
+			if !errors.Is(err, ErrObjectChanged) || !errors.Is(err, common.ErrPreconditionFailed) {
+				t.Fatalf("expected ErrObjectChanged, got %v", err)
+			}
+			if buf, ok := out.(*bytes.Buffer); ok && bytes.Contains(buf.Bytes(), replacement[:1024]) {
+				t.Error("bytes of the new version were written")
+			}
+		})
+	}
+}
+
+func TestDownload_ObjectDeleted(t *testing.T) {
+	s, _ := newStorage(t, "big", 8*1024)
+	ds := &deletingStorage{countingStorage: s, deleteAt: 2 * 1024}
+	_, err := NewManager(&Options{PartSize: 1024, Concurrency: 2}).Download(context.Background(), ds, "big", &bytes.Buffer{})
+	if !errors.Is(err, ErrObjectChanged) {
+		t.Fatalf("expected ErrObjectChanged, got %v", err)
+	}
+}
+
+// deletingStorage deletes the object after serving the ranged read at
+// deleteAt.
+type deletingStorage struct {
+	*countingStorage
+	deleteAt int64
+}
+
+func (s *deletingStorage) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	rc, err := s.countingStorage.GetRange(ctx, key, offset, length)
+	if err == nil && offset == s.deleteAt {
+		_ = s.Storage.Delete(key)
+	}
+	return rc, err
+}
+
+func TestReader(t *testing.T) {
+	s, data := newStorage(t, "big", 5000)
+	rc := NewManager(&Options{PartSize: 1000, Concurrency: 2}).Reader(context.Background(), s, "big")
+	defer rc.Close()
+
+	got, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatal("content mismatch")
+	}
+}
+
+func TestParseSettings(t *testing.T) {
+	opts, err := ParseSettings(map[string]string{SettingPartSize: "1048576", SettingConcurrency: "8"})
+	if err != nil {
+		t.Fatalf("ParseSettings: %v", err)
+	}
+	if opts.PartSize != 1<<20 || opts.Concurrency != 8 {
+		t.Errorf("unexpected options: %+v", opts)
+	}
+
+	for _, settings := range []map[string]string{
+		{SettingPartSize: "big"},
+		{SettingConcurrency: "0"},
+	} {
+		if _, err := ParseSettings(settings); !errors.Is(err, common.ErrInvalidArgument) {
+			t.Errorf("ParseSettings(%v) = %v, want ErrInvalidArgument", settings, err)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package e2ee
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// deterministicInfo binds deterministic field key derivation to this use.
+const deterministicInfo = "go-objstore e2ee deterministic v1"
+
+// sivSize is the size of the synthetic IV that prefixes every
+// deterministically encrypted value.
+const sivSize = 16
+
+var (
+	// ErrEqualityLeakNotAccepted is returned by DeterministicConfig.Validate
+	// when fields are selected without accepting what deterministic
+	// encryption reveals.
+	ErrEqualityLeakNotAccepted = fmt.Errorf("%w: deterministic encryption reveals which objects share a field value and must be accepted explicitly", common.ErrInvalidArgument)
+
+	// ErrNotDeterministic is returned when encrypting a field that is not
+	// configured for deterministic encryption.
+	ErrNotDeterministic = fmt.Errorf("%w: field is not encrypted deterministically", common.ErrInvalidArgument)
+)
+
+// DeterministicConfig selects custom metadata fields that are encrypted
+// deterministically, so a server can match them exactly without learning
+// their values. Selected fields are still sealed in the metadata envelope;
+// in addition, each is stored in the clear metadata under its own name with
+// a value that depends only on the field name, the value and the keychain
+// key. Searching the server for "name:" followed by the output of
+// Keychain.EncryptField finds the objects whose field has that value.
+//
+// Threat model: a server that sees the stored metadata cannot decrypt
+// deterministic values, but it learns
+//
+//   - the names of the selected fields, which are stored in the clear;
+//   - which objects share a value, and how often each value occurs, which
+//     reveals low-cardinality values (such as a status or a department) to
+//     anyone who knows their distribution;
+//   - the length of each value;
+//   - when a known plaintext is written, every other object holding it.
+//
+// Only select identifiers that need exact-match search, never free text or
+// secrets, and prefer high-entropy values. Values encrypted with different
+// keychain keys differ, so rotating the default key splits search results
+// by key until objects are rewritten.
+type DeterministicConfig struct {
+	// Fields are the names of the custom metadata fields to encrypt
+	// deterministically. Names are matched ignoring case.
+	Fields []string
+
+	// AcceptEqualityLeak acknowledges the threat model above. Validate
+	// fails without it when Fields is not empty.
+	AcceptEqualityLeak bool
+}
+
+// Validate checks the configuration. Field names must be non-empty, unique
+// and must not use the reserved "e2ee-" prefix.
+func (c DeterministicConfig) Validate() error {
+	if len(c.Fields) == 0 {
+		return nil
+	}
+	if !c.AcceptEqualityLeak {
+		return ErrEqualityLeakNotAccepted
+	}
+	seen := make(map[string]bool, len(c.Fields))
+	for _, name := range c.Fields {
+		lower := strings.ToLower(name)
+		switch {
+		case strings.TrimSpace(name) == "":
+			return fmt.Errorf("%w: empty deterministic field name", common.ErrInvalidArgument)
+		case strings.HasPrefix(lower, "e2ee-"):
+			return fmt.Errorf("%w: deterministic field %q uses the reserved e2ee- prefix", common.ErrInvalidArgument, name)
+		case seen[lower]:
+			return fmt.Errorf("%w: deterministic field %q is listed twice", common.ErrInvalidArgument, name)
+		}
+		seen[lower] = true
+	}
+	return nil
+}
+
+// SetDeterministic selects the custom metadata fields that objects sealed
+// from now on encrypt deterministically. An empty configuration turns
+// deterministic encryption off.
+func (k *Keychain) SetDeterministic(cfg DeterministicConfig) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	fields := make([]string, 0, len(cfg.Fields))
+	for _, name := range cfg.Fields {
+		fields = append(fields, strings.ToLower(name))
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.deterministic = fields
+	return nil
+}
+
+// DeterministicFields returns the lower-case names of the fields encrypted
+// deterministically.
+func (k *Keychain) DeterministicFields() []string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return slices.Clone(k.deterministic)
+}
+
+// EncryptField returns value encrypted deterministically for the field
+// name with the default key, as it is stored with objects sealed by this
+// keychain.
+func (k *Keychain) EncryptField(name, value string) (string, error) {
+	if !k.isDeterministic(name) {
+		return "", fmt.Errorf("%w: %s", ErrNotDeterministic, name)
+	}
+	_, kek, err := k.key("")
+	if err != nil {
+		return "", err
+	}
+	return encryptDeterministic(kek, name, value)
+}
+
+// DecryptField decrypts a value returned by EncryptField. It fails with
+// ErrDecrypt when the value was encrypted for another field or key.
+func (k *Keychain) DecryptField(name, encrypted string) (string, error) {
+	_, kek, err := k.key("")
+	if err != nil {
+		return "", err
+	}
+	return decryptDeterministic(kek, name, encrypted)
+}
+
+// isDeterministic reports whether the field name is encrypted
+// deterministically.
+func (k *Keychain) isDeterministic(name string) bool {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return slices.Contains(k.deterministic, strings.ToLower(name))
+}
+
+// sealDeterministic stores the deterministic fields of metadata in stored,
+// encrypted with kek.
+func (k *Keychain) sealDeterministic(kek []byte, stored, metadata *common.Metadata) error {
+	if metadata == nil {
+		return nil
+	}
+	for name, value := range metadata.Custom {
+		if !k.isDeterministic(name) {
+			continue
+		}
+		encrypted, err := encryptDeterministic(kek, name, value)
+		if err != nil {
+			return err
+		}
+		stored.Custom[name] = encrypted
+	}
+	return nil
+}
+
+// encryptDeterministic encrypts value with an SIV construction: the IV is
+// an HMAC-SHA256 of the field name and value, and the value is encrypted
+// with AES-256-CTR under that IV. Equal inputs give equal outputs, and the
+// IV authenticates the decrypted value. The output is lower-case hex so
+// case-insensitive indexes match it exactly.
+func encryptDeterministic(kek []byte, name, value string) (string, error) {
+	if size := 2 * (sivSize + len(value)); size > common.MaxMetadataValueLength {
+		return "", fmt.Errorf("%w: deterministic field %q encrypts to %d bytes, more than %d", common.ErrInvalidArgument, name, size, common.MaxMetadataValueLength)
+	}
+	macKey, block, err := deterministicKeys(kek)
+	if err != nil {
+		return "", err
+	}
+	out := make([]byte, sivSize+len(value))
+	copy(out, syntheticIV(macKey, name, []byte(value)))
+	cipher.NewCTR(block, out[:sivSize]).XORKeyStream(out[sivSize:], []byte(value))
+	return hex.EncodeToString(out), nil
+}
+
+// decryptDeterministic decrypts a value encrypted by encryptDeterministic.
+func decryptDeterministic(kek []byte, name, encrypted string) (string, error) {
+	raw, err := hex.DecodeString(strings.ToLower(encrypted))
+	if err != nil || len(raw) < sivSize {
+		return "", ErrDecrypt
+	}
+	macKey, block, err := deterministicKeys(kek)
+	if err != nil {
+		return "", err
+	}
+	iv, sealed := raw[:sivSize], raw[sivSize:]
+	value := make([]byte, len(sealed))
+	cipher.NewCTR(block, iv).XORKeyStream(value, sealed)
+	if !hmac.Equal(iv, syntheticIV(macKey, name, value)) {
+		return "", ErrDecrypt
+	}
+	return string(value), nil
+}
+
+// deterministicKeys derives the MAC key and cipher of deterministic
+// encryption from a keychain key.
+func deterministicKeys(kek []byte) ([]byte, cipher.Block, error) {
+	keys, err := hkdf.Key(sha256.New, kek, nil, deterministicInfo, 2*KeySize)
+	if err != nil {
+		return nil, nil, err
+	}
+	block, err := aes.NewCipher(keys[KeySize:])
+	if err != nil {
+		return nil, nil, err
+	}
+	return keys[:KeySize], block, nil
+}
+
+// syntheticIV returns the IV of value in the field name. The name is
+// lower-cased because servers may change its case.
+func syntheticIV(macKey []byte, name string, value []byte) []byte {
+	mac := hmac.New(sha256.New, macKey)
+	mac.Write([]byte(strings.ToLower(name)))
+	mac.Write([]byte{0})
+	mac.Write(value)
+	return mac.Sum(nil)[:sivSize]
+}
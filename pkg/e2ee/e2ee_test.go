@@ -0,0 +1,464 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package e2ee
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+func newKeychain(t *testing.T, ids ...string) *Keychain {
+	t.Helper()
+	k := NewKeychain()
+	for _, id := range ids {
+		if err := k.GenerateKey(id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return k
+}
+
+func randomBytes(t *testing.T, n int) []byte {
+	t.Helper()
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func readAll(t *testing.T, s common.Storage, key string) ([]byte, error) {
+	t.Helper()
+	rc, err := s.GetWithContext(context.Background(), key)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+func TestStorageRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	backend := memory.New()
+	s := NewStorage(backend, newKeychain(t, "k1"))
+
+	for _, size := range []int{0, 1, segmentSize - 1, segmentSize, segmentSize + 1, 3*segmentSize + 17} {
+		data := randomBytes(t, size)
+		key := "obj"
+		if err := s.PutWithMetadata(ctx, key, bytes.NewReader(data), &common.Metadata{Size: int64(size)}); err != nil {
+			t.Fatalf("size %d: Put() error = %v", size, err)
+		}
+		got, err := readAll(t, s, key)
+		if err != nil {
+			t.Fatalf("size %d: Get() error = %v", size, err)
+		}
+		if !bytes.Equal(got, data) {
+			t.Fatalf("size %d: Get() returned %d different bytes", size, len(got))
+		}
+
+		stored, err := readAll(t, backend, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if int64(len(stored)) != CiphertextSize(int64(size)) {
+			t.Errorf("size %d: stored %d bytes, CiphertextSize = %d", size, len(stored), CiphertextSize(int64(size)))
+		}
+		if PlaintextSize(int64(len(stored))) != int64(size) {
+			t.Errorf("size %d: PlaintextSize(%d) = %d", size, len(stored), PlaintextSize(int64(len(stored))))
+		}
+		// Short plaintexts can occur in ciphertext by chance.
+		if size >= 16 && bytes.Contains(stored, data[:min(size, 64)]) {
+			t.Errorf("size %d: stored content contains plaintext", size)
+		}
+	}
+}
+
+func TestStorageMetadata(t *testing.T) {
+	ctx := context.Background()
+	backend := memory.New()
+	s := NewStorage(backend, newKeychain(t, "k1"))
+
+	long := strings.Repeat("x", 3*common.MaxMetadataValueLength)
+	meta := &common.Metadata{
+		ContentType:     "application/pdf",
+		ContentEncoding: "gzip",
+		StorageClass:    "COLD",
+		Custom:          map[string]string{"patient": "alice", "notes": long},
+	}
+	if err := s.PutWithMetadata(ctx, "report.pdf", strings.NewReader("secret"), meta); err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err := backend.GetMetadata(ctx, "report.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.ContentType != storedContentType || stored.StorageClass != "COLD" {
+		t.Errorf("stored metadata = %+v, want opaque content type and storage class kept", stored)
+	}
+	if err := common.ValidateMetadata(stored.Custom); err != nil {
+		t.Errorf("stored custom metadata invalid: %v", err)
+	}
+	for k, v := range stored.Custom {
+		if strings.Contains(v, "alice") || strings.Contains(v, "application/pdf") || k == "patient" {
+			t.Errorf("stored metadata %s=%s leaks plaintext", k, v)
+		}
+	}
+
+	got, err := s.GetMetadata(ctx, "report.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ContentType != "application/pdf" || got.ContentEncoding != "gzip" ||
+		got.Custom["patient"] != "alice" || got.Custom["notes"] != long || got.Size != 6 {
+		t.Errorf("GetMetadata() = %+v", got)
+	}
+
+	if err := s.UpdateMetadata(ctx, "report.pdf", &common.Metadata{ContentType: "text/plain", Custom: map[string]string{"patient": "bob"}}); err != nil {
+		t.Fatal(err)
+	}
+	got, err = s.GetMetadata(ctx, "report.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ContentType != "text/plain" || got.Custom["patient"] != "bob" || got.StorageClass != "COLD" {
+		t.Errorf("GetMetadata() after update = %+v", got)
+	}
+	if data, err := readAll(t, s, "report.pdf"); err != nil || string(data) != "secret" {
+		t.Errorf("Get() after update = %q, %v", data, err)
+	}
+}
+
+func TestOpenMetadataCanonicalizedNames(t *testing.T) {
+	k := newKeychain(t, "k1")
+	_, stored, err := k.Seal(strings.NewReader("x"), &common.Metadata{ContentType: "text/csv"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Servers that carry custom metadata in HTTP headers return canonical
+	// header names.
+	canonical := *stored
+	canonical.Custom = map[string]string{}
+	for name, v := range stored.Custom {
+		canonical.Custom[http.CanonicalHeaderKey(name)] = v
+	}
+	got, err := k.OpenMetadata(&canonical)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ContentType != "text/csv" {
+		t.Errorf("ContentType = %q, want text/csv", got.ContentType)
+	}
+}
+
+func TestTampering(t *testing.T) {
+	ctx := context.Background()
+	data := randomBytes(t, 2*segmentSize+100)
+
+	tests := []struct {
+		name   string
+		modify func([]byte) []byte
+	}{
+		{"flipped byte", func(b []byte) []byte { b[len(b)/2] ^= 1; return b }},
+		{"truncated segment", func(b []byte) []byte { return b[:len(b)-10] }},
+		{"dropped final segment", func(b []byte) []byte { return b[:2*(segmentSize+tagSize)] }},
+		{"swapped segments", func(b []byte) []byte {
+			seg := segmentSize + tagSize
+			out := append([]byte{}, b[seg:2*seg]...)
+			out = append(out, b[:seg]...)
+			return append(out, b[2*seg:]...)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := memory.New()
+			s := NewStorage(backend, newKeychain(t, "k1"))
+			if err := s.Put("obj", bytes.NewReader(data)); err != nil {
+				t.Fatal(err)
+			}
+			stored, _ := readAll(t, backend, "obj")
+			meta, _ := backend.GetMetadata(ctx, "obj")
+			if err := backend.PutWithMetadata(ctx, "obj", bytes.NewReader(tt.modify(stored)), meta); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := readAll(t, s, "obj"); !errors.Is(err, ErrDecrypt) {
+				t.Errorf("Get() error = %v, want ErrDecrypt", err)
+			}
+		})
+	}
+}
+
+func TestKeys(t *testing.T) {
+	ctx := context.Background()
+	backend := memory.New()
+	k := newKeychain(t, "2025")
+	s := NewStorage(backend, k)
+	if err := s.Put("old", strings.NewReader("old data")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Rotating the default key leaves existing objects readable.
+	if err := k.GenerateKey("2026"); err != nil {
+		t.Fatal(err)
+	}
+	if err := k.SetDefault("2026"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put("new", strings.NewReader("new data")); err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string]string{"old": "old data", "new": "new data"} {
+		if got, err := readAll(t, s, key); err != nil || string(got) != want {
+			t.Errorf("Get(%s) = %q, %v", key, got, err)
+		}
+	}
+	if meta, _ := backend.GetMetadata(ctx, "new"); meta.Custom[MetaKeyID] != "2026" {
+		t.Errorf("new object key ID = %q, want 2026", meta.Custom[MetaKeyID])
+	}
+
+	// Another keychain cannot read the objects, even with the same key ID.
+	other := NewStorage(backend, newKeychain(t, "2025"))
+	if _, err := readAll(t, other, "old"); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("Get() with another key error = %v, want ErrDecrypt", err)
+	}
+	if _, err := readAll(t, NewStorage(backend, newKeychain(t, "x")), "new"); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("Get() with missing key error = %v, want ErrKeyNotFound", err)
+	}
+
+	if err := backend.Put("plain", strings.NewReader("plain")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readAll(t, s, "plain"); !errors.Is(err, ErrNotEncrypted) {
+		t.Errorf("Get() of unencrypted object error = %v, want ErrNotEncrypted", err)
+	}
+
+	if _, _, err := NewKeychain().Seal(strings.NewReader("x"), nil); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("Seal() with empty keychain error = %v, want ErrKeyNotFound", err)
+	}
+	if err := k.GenerateKey("2026"); !errors.Is(err, ErrKeyExists) {
+		t.Errorf("GenerateKey() duplicate error = %v, want ErrKeyExists", err)
+	}
+	if err := k.AddKey("short", []byte("short")); !errors.Is(err, ErrInvalidKeychain) {
+		t.Errorf("AddKey() short key error = %v, want ErrInvalidKeychain", err)
+	}
+}
+
+func TestKeychainSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys", "keychain.json")
+	k := newKeychain(t, "a", "b")
+	if err := k.SetDefault("b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := k.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("keychain permissions = %o, want 600", perm)
+	}
+
+	loaded, err := LoadKeychain(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.DefaultKeyID() != "b" || strings.Join(loaded.KeyIDs(), ",") != "a,b" {
+		t.Errorf("loaded keychain default %q, keys %v", loaded.DefaultKeyID(), loaded.KeyIDs())
+	}
+
+	// Objects sealed with the saved keychain open with the loaded one.
+	sealed, stored, err := k.Seal(strings.NewReader("hello"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain, err := loaded.Open(sealed, stored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := io.ReadAll(plain); string(got) != "hello" {
+		t.Errorf("Open() = %q, want hello", got)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"version":9}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadKeychain(path); !errors.Is(err, ErrInvalidKeychain) {
+		t.Errorf("LoadKeychain() error = %v, want ErrInvalidKeychain", err)
+	}
+}
+
+func TestEscrowRecovery(t *testing.T) {
+	dir := t.TempDir()
+	recovery, err := GenerateRecoveryKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := recovery.Save(filepath.Join(dir, "recovery.json")); err != nil {
+		t.Fatal(err)
+	}
+	if err := recovery.SavePublic(filepath.Join(dir, "recovery.pub.json")); err != nil {
+		t.Fatal(err)
+	}
+	public, err := LoadRecoveryPublicKey(filepath.Join(dir, "recovery.pub.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadRecoveryKey(filepath.Join(dir, "recovery.pub.json")); !errors.Is(err, ErrInvalidKeychain) {
+		t.Errorf("LoadRecoveryKey(public file) error = %v, want ErrInvalidKeychain", err)
+	}
+
+	// The escrow setting survives saving the keychain.
+	k := newKeychain(t, "a")
+	if err := k.EnableEscrow(public); err != nil {
+		t.Fatal(err)
+	}
+	if err := k.Save(filepath.Join(dir, "keychain.json")); err != nil {
+		t.Fatal(err)
+	}
+	if k, err = LoadKeychain(filepath.Join(dir, "keychain.json")); err != nil {
+		t.Fatal(err)
+	}
+	if k.EscrowKeyID() != recovery.ID() {
+		t.Fatalf("EscrowKeyID() = %q, want %q", k.EscrowKeyID(), recovery.ID())
+	}
+
+	backend := memory.New()
+	s := NewStorage(backend, k)
+	data := randomBytes(t, segmentSize+10)
+	metadata := &common.Metadata{ContentType: "text/csv", Custom: map[string]string{"owner": "alice"}}
+	if err := s.PutWithMetadata(context.Background(), "escrowed", bytes.NewReader(data), metadata); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.UpdateMetadata(context.Background(), "escrowed", &common.Metadata{Custom: map[string]string{"owner": "bob"}}); err != nil {
+		t.Fatal(err)
+	}
+	k.DisableEscrow()
+	if err := s.Put("plain", strings.NewReader("not escrowed")); err != nil {
+		t.Fatal(err)
+	}
+
+	// The keychain is lost: a keychain holding only the recovery key
+	// reads escrowed objects.
+	offline, err := LoadRecoveryKey(filepath.Join(dir, "recovery.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lost := NewKeychain()
+	lost.SetRecoveryKey(offline)
+	recovered := NewStorage(backend, lost)
+	got, err := readAll(t, recovered, "escrowed")
+	if err != nil || !bytes.Equal(got, data) {
+		t.Fatalf("recovered content mismatch (err = %v)", err)
+	}
+	meta, err := recovered.GetMetadata(context.Background(), "escrowed")
+	if err != nil || meta.Custom["owner"] != "bob" {
+		t.Errorf("recovered metadata = %+v, %v", meta, err)
+	}
+
+	if _, err := readAll(t, recovered, "plain"); !errors.Is(err, ErrNotEscrowed) {
+		t.Errorf("recovering an object that is not escrowed: error = %v, want ErrNotEscrowed", err)
+	}
+	other, err := GenerateRecoveryKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	lost.SetRecoveryKey(other)
+	if _, err := readAll(t, recovered, "escrowed"); !errors.Is(err, ErrNotEscrowed) {
+		t.Errorf("recovering with another recovery key: error = %v, want ErrNotEscrowed", err)
+	}
+	lost.SetRecoveryKey(nil)
+	if _, err := readAll(t, recovered, "escrowed"); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("reading without keys: error = %v, want ErrKeyNotFound", err)
+	}
+}
+
+func TestDeterministicFields(t *testing.T) {
+	k := newKeychain(t, "a")
+	if err := k.SetDeterministic(DeterministicConfig{Fields: []string{"patient-id"}}); !errors.Is(err, ErrEqualityLeakNotAccepted) {
+		t.Errorf("SetDeterministic() without accepting the leak: error = %v", err)
+	}
+	for _, fields := range [][]string{{""}, {"e2ee-key-id"}, {"id", "ID"}} {
+		if err := k.SetDeterministic(DeterministicConfig{Fields: fields, AcceptEqualityLeak: true}); !errors.Is(err, common.ErrInvalidArgument) {
+			t.Errorf("SetDeterministic(%q) error = %v, want ErrInvalidArgument", fields, err)
+		}
+	}
+	if err := k.SetDeterministic(DeterministicConfig{Fields: []string{"Patient-ID"}, AcceptEqualityLeak: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	backend := memory.New()
+	s := NewStorage(backend, k)
+	ctx := context.Background()
+	for key, id := range map[string]string{"a": "p-1", "b": "p-1", "c": "p-2"} {
+		metadata := &common.Metadata{Custom: map[string]string{"patient-id": id, "note": "private"}}
+		if err := s.PutWithMetadata(ctx, key, strings.NewReader(key), metadata); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	token, err := k.EncryptField("patient-id", "p-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != strings.ToLower(token) || strings.Contains(token, "p-1") {
+		t.Errorf("EncryptField() = %q, want opaque lower-case hex", token)
+	}
+	for key, want := range map[string]bool{"a": true, "b": true, "c": false} {
+		stored, err := backend.GetMetadata(ctx, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := stored.Custom["patient-id"] == token; got != want {
+			t.Errorf("%s: stored patient-id matches token = %v, want %v", key, got, want)
+		}
+		if stored.Custom["note"] != "" {
+			t.Errorf("%s: other fields stored in the clear: %+v", key, stored.Custom)
+		}
+	}
+	if got, err := k.DecryptField("patient-id", token); err != nil || got != "p-1" {
+		t.Errorf("DecryptField() = %q, %v", got, err)
+	}
+	if _, err := k.DecryptField("other", token); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("DecryptField(other field) error = %v, want ErrDecrypt", err)
+	}
+	if _, err := k.EncryptField("note", "x"); !errors.Is(err, ErrNotDeterministic) {
+		t.Errorf("EncryptField(note) error = %v, want ErrNotDeterministic", err)
+	}
+
+	// Metadata updates re-encrypt the field, and reads return plaintext.
+	if err := s.UpdateMetadata(ctx, "c", &common.Metadata{Custom: map[string]string{"patient-id": "p-1"}}); err != nil {
+		t.Fatal(err)
+	}
+	stored, err := backend.GetMetadata(ctx, "c")
+	if err != nil || stored.Custom["patient-id"] != token {
+		t.Errorf("updated stored metadata = %+v, %v", stored, err)
+	}
+	meta, err := s.GetMetadata(ctx, "a")
+	if err != nil || meta.Custom["patient-id"] != "p-1" || meta.Custom["note"] != "private" {
+		t.Errorf("GetMetadata() = %+v, %v", meta, err)
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package e2ee
+
+import (
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// escrowInfo binds escrow key derivation to this use.
+const escrowInfo = "go-objstore e2ee escrow v1"
+
+// recoveryKeyFile is the JSON form of a recovery key. Files written by
+// SavePublic have no private key.
+type recoveryKeyFile struct {
+	Version int    `json:"version"`
+	ID      string `json:"id"`
+	Public  []byte `json:"public"`
+	Private []byte `json:"private,omitempty"`
+}
+
+// RecoveryKey is an organization recovery key for escrow mode. Keychains
+// in escrow mode hold only its public half and wrap every data key with
+// it; the private half is kept offline and decrypts the objects of any
+// such keychain if the keychain is lost. RecoveryKey is an X25519 key
+// pair.
+type RecoveryKey struct {
+	private *ecdh.PrivateKey
+}
+
+// GenerateRecoveryKey returns a new random recovery key.
+func GenerateRecoveryKey() (*RecoveryKey, error) {
+	private, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &RecoveryKey{private: private}, nil
+}
+
+// LoadRecoveryKey reads a recovery key saved with Save.
+func LoadRecoveryKey(path string) (*RecoveryKey, error) {
+	file, err := readRecoveryKeyFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if file.Private == nil {
+		return nil, fmt.Errorf("%w: %s: no private recovery key", ErrInvalidKeychain, path)
+	}
+	private, err := ecdh.X25519().NewPrivateKey(file.Private)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidKeychain, path, err)
+	}
+	return &RecoveryKey{private: private}, nil
+}
+
+// LoadRecoveryPublicKey reads the public half of a recovery key from a
+// file written by Save or SavePublic, for Keychain.EnableEscrow.
+func LoadRecoveryPublicKey(path string) ([]byte, error) {
+	file, err := readRecoveryKeyFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ecdh.X25519().NewPublicKey(file.Public); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidKeychain, path, err)
+	}
+	return file.Public, nil
+}
+
+func readRecoveryKeyFile(path string) (*recoveryKeyFile, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path supplied by the user
+	if err != nil {
+		return nil, err
+	}
+	var file recoveryKeyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidKeychain, path, err)
+	}
+	if file.Version != keychainVersion {
+		return nil, fmt.Errorf("%w: %s: unsupported version %d", ErrInvalidKeychain, path, file.Version)
+	}
+	return &file, nil
+}
+
+// Save writes the recovery key, including its private half, to path,
+// readable only by its owner. Keep the file offline.
+func (r *RecoveryKey) Save(path string) error {
+	return r.save(path, true)
+}
+
+// SavePublic writes only the public half of the recovery key to path, for
+// distribution to the keychains that escrow with it.
+func (r *RecoveryKey) SavePublic(path string) error {
+	return r.save(path, false)
+}
+
+func (r *RecoveryKey) save(path string, private bool) error {
+	file := recoveryKeyFile{
+		Version: keychainVersion,
+		ID:      r.ID(),
+		Public:  r.PublicKey(),
+	}
+	if private {
+		file.Private = r.private.Bytes()
+	}
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writePrivateFile(path, data)
+}
+
+// PublicKey returns the public half of the recovery key.
+func (r *RecoveryKey) PublicKey() []byte {
+	return r.private.PublicKey().Bytes()
+}
+
+// ID returns a short fingerprint of the recovery key, stored with escrowed
+// objects to tell which recovery key they need.
+func (r *RecoveryKey) ID() string {
+	return recoveryKeyID(r.private.PublicKey())
+}
+
+func recoveryKeyID(pub *ecdh.PublicKey) string {
+	sum := sha256.Sum256(pub.Bytes())
+	return hex.EncodeToString(sum[:8])
+}
+
+// recover unwraps the escrowed data key of the object stored with
+// metadata.
+func (r *RecoveryKey) recover(stored *common.Metadata) ([]byte, error) {
+	escrowed := field(stored.Custom, MetaEscrowDataKey)
+	if escrowed == "" {
+		return nil, ErrNotEscrowed
+	}
+	if id := field(stored.Custom, MetaEscrowKeyID); id != r.ID() {
+		return nil, fmt.Errorf("%w: object is escrowed with recovery key %s, not %s", ErrNotEscrowed, id, r.ID())
+	}
+	raw, err := base64.StdEncoding.DecodeString(escrowed)
+	if err != nil || len(raw) < 32 {
+		return nil, ErrDecrypt
+	}
+	ephemeral, err := ecdh.X25519().NewPublicKey(raw[:32])
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	shared, err := r.private.ECDH(ephemeral)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	aead, err := escrowAEAD(shared, ephemeral, r.private.PublicKey())
+	if err != nil {
+		return nil, err
+	}
+	dataKey, err := aead.Open(nil, make([]byte, aead.NonceSize()), raw[32:], nil)
+	if err != nil || len(dataKey) != KeySize {
+		return nil, ErrDecrypt
+	}
+	return dataKey, nil
+}
+
+// escrowKey wraps dataKey for the recovery key pub with an ephemeral
+// X25519 key: base64(ephemeral public key || AES-256-GCM(dataKey)).
+func escrowKey(pub *ecdh.PublicKey, dataKey []byte) (string, error) {
+	ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return "", err
+	}
+	shared, err := ephemeral.ECDH(pub)
+	if err != nil {
+		return "", err
+	}
+	aead, err := escrowAEAD(shared, ephemeral.PublicKey(), pub)
+	if err != nil {
+		return "", err
+	}
+	// The wrapping key is used once, so a zero nonce is safe.
+	sealed := aead.Seal(ephemeral.PublicKey().Bytes(), make([]byte, aead.NonceSize()), dataKey, nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// escrowAEAD derives the wrapping cipher of one escrowed data key from
+// the X25519 shared secret of an ephemeral key and the recovery key. Both
+// public keys are bound into the derivation.
+func escrowAEAD(shared []byte, ephemeral, recipient *ecdh.PublicKey) (cipher.AEAD, error) {
+	info := escrowInfo + string(ephemeral.Bytes()) + string(recipient.Bytes())
+	key, err := hkdf.Key(sha256.New, shared, nil, info, KeySize)
+	if err != nil {
+		return nil, err
+	}
+	return newAEAD(key)
+}
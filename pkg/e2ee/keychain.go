@@ -0,0 +1,314 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package e2ee encrypts objects end to end: clients encrypt objects and
+// their metadata with keys held in a local keychain before sending them to
+// a remote objstore-server, or any other backend, and decrypt them after
+// reading them back. The server stores ciphertext and an opaque metadata
+// envelope, so its operator never sees plaintext content, content types or
+// custom metadata.
+//
+// Every object is encrypted with its own random data key, which is stored
+// with the object wrapped by a keychain key. Content is encrypted in
+// authenticated segments, so objects stream in both directions and a
+// truncated, reordered or modified object fails to decrypt.
+//
+// Custom metadata fields selected with DeterministicConfig are also
+// stored deterministically encrypted, so servers can match them exactly.
+//
+// In escrow mode the data key is also wrapped with the public half of an
+// organization RecoveryKey, whose private half is kept offline. If a
+// keychain is lost, the recovery key decrypts its objects.
+//
+//	kc, err := e2ee.LoadKeychain(os.ExpandEnv("$HOME/.objstore/keychain.json"))
+//	...
+//	storage := e2ee.NewStorage(client.NewStorage(remote), kc)
+package e2ee
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// KeySize is the size in bytes of keychain keys and data keys (AES-256).
+const KeySize = 32
+
+// keychainVersion is the version of the keychain file format.
+const keychainVersion = 1
+
+var (
+	// ErrKeyNotFound is returned when the keychain has no key with the
+	// requested ID, or no default key.
+	ErrKeyNotFound = fmt.Errorf("keychain key %w", common.ErrNotFound)
+
+	// ErrKeyExists is returned when adding a key whose ID is already used.
+	ErrKeyExists = fmt.Errorf("keychain key %w", common.ErrAlreadyExists)
+
+	// ErrInvalidKeychain is returned for a malformed keychain file or key.
+	ErrInvalidKeychain = fmt.Errorf("%w: invalid keychain", common.ErrInvalidArgument)
+
+	// ErrNotEncrypted is returned when reading an object that was not
+	// stored end-to-end encrypted.
+	ErrNotEncrypted = fmt.Errorf("%w: object is not end-to-end encrypted", common.ErrPreconditionFailed)
+
+	// ErrNotEscrowed is returned when recovering an object whose data key
+	// was not escrowed with the recovery key.
+	ErrNotEscrowed = fmt.Errorf("%w: object is not escrowed with the recovery key", common.ErrPreconditionFailed)
+
+	// ErrDecrypt is returned when an object, its metadata or its data key
+	// fails authentication: it was modified, truncated, or encrypted with a
+	// different key. It is common.ErrDecrypt.
+	ErrDecrypt = common.ErrDecrypt
+)
+
+// keychainFile is the JSON form of a keychain.
+type keychainFile struct {
+	Version int               `json:"version"`
+	Default string            `json:"default"`
+	Keys    map[string][]byte `json:"keys"`
+	Escrow  []byte            `json:"escrow,omitempty"`
+}
+
+// Keychain holds the keys objects are encrypted with. Keys are never sent
+// to a server; losing the keychain makes the objects unreadable.
+// Keychains are safe for concurrent use.
+type Keychain struct {
+	mu         sync.RWMutex
+	defaultKey string
+	keys       map[string][]byte
+	escrow     *ecdh.PublicKey
+	recovery   *RecoveryKey
+
+	// deterministic holds the lower-case names of the custom metadata
+	// fields encrypted deterministically (see DeterministicConfig).
+	deterministic []string
+}
+
+// NewKeychain returns an empty keychain.
+func NewKeychain() *Keychain {
+	return &Keychain{keys: make(map[string][]byte)}
+}
+
+// LoadKeychain reads a keychain saved with Save.
+func LoadKeychain(path string) (*Keychain, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path supplied by the user
+	if err != nil {
+		return nil, err
+	}
+	var file keychainFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidKeychain, path, err)
+	}
+	if file.Version != keychainVersion {
+		return nil, fmt.Errorf("%w: %s: unsupported version %d", ErrInvalidKeychain, path, file.Version)
+	}
+	k := NewKeychain()
+	for id, key := range file.Keys {
+		if err := k.AddKey(id, key); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	if file.Default != "" {
+		if err := k.SetDefault(file.Default); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	if file.Escrow != nil {
+		if err := k.EnableEscrow(file.Escrow); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return k, nil
+}
+
+// Save writes the keychain to path, readable only by its owner. The file
+// is replaced atomically.
+func (k *Keychain) Save(path string) error {
+	k.mu.RLock()
+	file := keychainFile{
+		Version: keychainVersion,
+		Default: k.defaultKey,
+		Keys:    k.keys,
+	}
+	if k.escrow != nil {
+		file.Escrow = k.escrow.Bytes()
+	}
+	data, err := json.MarshalIndent(file, "", "  ")
+	k.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	return writePrivateFile(path, data)
+}
+
+// writePrivateFile replaces the file at path with data, readable only by
+// its owner.
+func writePrivateFile(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, ".keychain-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// GenerateKey adds a new random key. The first key added becomes the
+// default key.
+func (k *Keychain) GenerateKey(id string) error {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return err
+	}
+	return k.AddKey(id, key)
+}
+
+// AddKey adds a key. The first key added becomes the default key.
+func (k *Keychain) AddKey(id string, key []byte) error {
+	if id == "" {
+		return fmt.Errorf("%w: empty key ID", ErrInvalidKeychain)
+	}
+	if len(key) != KeySize {
+		return fmt.Errorf("%w: key %q is %d bytes, want %d", ErrInvalidKeychain, id, len(key), KeySize)
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if _, ok := k.keys[id]; ok {
+		return fmt.Errorf("%w: %s", ErrKeyExists, id)
+	}
+	k.keys[id] = slices.Clone(key)
+	if k.defaultKey == "" {
+		k.defaultKey = id
+	}
+	return nil
+}
+
+// SetDefault selects the key new objects are encrypted with. Objects keep
+// the key they were encrypted with, so older keys must stay in the
+// keychain while objects use them.
+func (k *Keychain) SetDefault(id string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if _, ok := k.keys[id]; !ok {
+		return fmt.Errorf("%w: %s", ErrKeyNotFound, id)
+	}
+	k.defaultKey = id
+	return nil
+}
+
+// DefaultKeyID returns the ID of the key new objects are encrypted with.
+func (k *Keychain) DefaultKeyID() string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.defaultKey
+}
+
+// KeyIDs returns the IDs of the keys in the keychain, sorted.
+func (k *Keychain) KeyIDs() []string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	ids := make([]string, 0, len(k.keys))
+	for id := range k.keys {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// EnableEscrow turns on escrow mode: the data keys of objects encrypted
+// from now on are also wrapped with publicKey, the public half of a
+// RecoveryKey, so they can be recovered without the keychain.
+func (k *Keychain) EnableEscrow(publicKey []byte) error {
+	pub, err := ecdh.X25519().NewPublicKey(publicKey)
+	if err != nil {
+		return fmt.Errorf("%w: recovery public key: %w", ErrInvalidKeychain, err)
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.escrow = pub
+	return nil
+}
+
+// DisableEscrow turns off escrow mode. Objects already escrowed stay
+// recoverable.
+func (k *Keychain) DisableEscrow() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.escrow = nil
+}
+
+// EscrowKeyID returns the ID of the recovery key data keys are escrowed
+// with, or "" when escrow mode is off.
+func (k *Keychain) EscrowKeyID() string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	if k.escrow == nil {
+		return ""
+	}
+	return recoveryKeyID(k.escrow)
+}
+
+// SetRecoveryKey lets the keychain read objects whose key it does not
+// hold by unwrapping their escrowed data key with recovery. The recovery
+// key is never saved with the keychain.
+func (k *Keychain) SetRecoveryKey(recovery *RecoveryKey) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.recovery = recovery
+}
+
+// escrowKey returns the escrow public key, or nil when escrow mode is off.
+func (k *Keychain) escrowKey() *ecdh.PublicKey {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.escrow
+}
+
+// recoveryKey returns the recovery key set with SetRecoveryKey, or nil.
+func (k *Keychain) recoveryKey() *RecoveryKey {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.recovery
+}
+
+// key returns the key with the given ID; an empty ID selects the default.
+func (k *Keychain) key(id string) (string, []byte, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	if id == "" {
+		id = k.defaultKey
+	}
+	key, ok := k.keys[id]
+	if !ok {
+		return "", nil, fmt.Errorf("%w: %q", ErrKeyNotFound, id)
+	}
+	return id, key, nil
+}
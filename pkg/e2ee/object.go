@@ -0,0 +1,304 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package e2ee
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// Algorithm identifies the format of end-to-end encrypted objects.
+const Algorithm = "AES-256-GCM-SEGMENTED"
+
+// Custom metadata stored with encrypted objects in place of their own
+// metadata. The envelope is split over MetaEnvelope, MetaEnvelope-1, ...
+// when it exceeds common.MaxMetadataValueLength. Servers may change the
+// case of the names, so they are looked up case-insensitively.
+const (
+	MetaAlgorithm = "e2ee-algorithm"
+	MetaKeyID     = "e2ee-key-id"
+	MetaDataKey   = "e2ee-data-key"
+	MetaEnvelope  = "e2ee-envelope"
+
+	// MetaEscrowKeyID and MetaEscrowDataKey hold the data key wrapped
+	// with a recovery key in escrow mode.
+	MetaEscrowKeyID   = "e2ee-escrow-key-id"
+	MetaEscrowDataKey = "e2ee-escrow-data-key"
+)
+
+// storedContentType is the content type of every encrypted object.
+const storedContentType = "application/octet-stream"
+
+// envelope is the metadata encrypted with an object.
+type envelope struct {
+	ContentType     string            `json:"content_type,omitempty"`
+	ContentEncoding string            `json:"content_encoding,omitempty"`
+	Custom          map[string]string `json:"custom,omitempty"`
+}
+
+// Seal encrypts an object for storage with the default key. It returns
+// the content to store, which is encrypted as it is read, and the metadata
+// to store it with. The content type, content encoding and custom fields
+// of metadata are encrypted into the stored metadata; the storage class is
+// kept in the clear because the server acts on it, and custom fields
+// selected with SetDeterministic are also stored deterministically
+// encrypted. metadata may be nil.
+func (k *Keychain) Seal(data io.Reader, metadata *common.Metadata) (io.Reader, *common.Metadata, error) {
+	keyID, kek, err := k.key("")
+	if err != nil {
+		return nil, nil, err
+	}
+	dataKey := make([]byte, KeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, nil, err
+	}
+	wrapped, err := wrapKey(kek, keyID, dataKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stored := &common.Metadata{
+		ContentType: storedContentType,
+		Custom: map[string]string{
+			MetaAlgorithm: Algorithm,
+			MetaKeyID:     keyID,
+			MetaDataKey:   wrapped,
+		},
+	}
+	if escrow := k.escrowKey(); escrow != nil {
+		escrowed, err := escrowKey(escrow, dataKey)
+		if err != nil {
+			return nil, nil, err
+		}
+		stored.Custom[MetaEscrowKeyID] = recoveryKeyID(escrow)
+		stored.Custom[MetaEscrowDataKey] = escrowed
+	}
+	if err := k.sealDeterministic(kek, stored, metadata); err != nil {
+		return nil, nil, err
+	}
+	if metadata != nil {
+		stored.StorageClass = metadata.StorageClass
+		if metadata.Size > 0 {
+			stored.Size = CiphertextSize(metadata.Size)
+		}
+	}
+	if err := sealEnvelope(aead, stored, metadata); err != nil {
+		return nil, nil, err
+	}
+	return newSealReader(aead, data), stored, nil
+}
+
+// Reseal returns stored, the metadata of an encrypted object, with its
+// envelope replaced by metadata. It is used to update the metadata of an
+// object without rewriting it.
+func (k *Keychain) Reseal(stored, metadata *common.Metadata) (*common.Metadata, error) {
+	aead, err := k.dataKey(stored)
+	if err != nil {
+		return nil, err
+	}
+	updated := &common.Metadata{
+		ContentType:  storedContentType,
+		StorageClass: stored.StorageClass,
+		Custom: map[string]string{
+			MetaAlgorithm: Algorithm,
+			MetaKeyID:     field(stored.Custom, MetaKeyID),
+			MetaDataKey:   field(stored.Custom, MetaDataKey),
+		},
+	}
+	for _, name := range []string{MetaEscrowKeyID, MetaEscrowDataKey} {
+		if v := field(stored.Custom, name); v != "" {
+			updated.Custom[name] = v
+		}
+	}
+	if metadata != nil && metadata.StorageClass != "" {
+		updated.StorageClass = metadata.StorageClass
+	}
+	// Objects read with a recovery key keep their deterministic fields in
+	// the envelope only.
+	if _, kek, err := k.key(field(stored.Custom, MetaKeyID)); err == nil {
+		if err := k.sealDeterministic(kek, updated, metadata); err != nil {
+			return nil, err
+		}
+	}
+	if err := sealEnvelope(aead, updated, metadata); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// Open returns the decrypted content of an encrypted object, given its
+// stored content and metadata. Reads fail with ErrDecrypt when the content
+// has been modified or truncated.
+func (k *Keychain) Open(data io.Reader, stored *common.Metadata) (io.Reader, error) {
+	aead, err := k.dataKey(stored)
+	if err != nil {
+		return nil, err
+	}
+	return newOpenReader(aead, data), nil
+}
+
+// OpenMetadata returns the metadata an encrypted object was stored with,
+// given the metadata the server returned for it.
+func (k *Keychain) OpenMetadata(stored *common.Metadata) (*common.Metadata, error) {
+	aead, err := k.dataKey(stored)
+	if err != nil {
+		return nil, err
+	}
+	var sealed strings.Builder
+	sealed.WriteString(field(stored.Custom, MetaEnvelope))
+	for i := 1; ; i++ {
+		part := field(stored.Custom, MetaEnvelope+"-"+strconv.Itoa(i))
+		if part == "" {
+			break
+		}
+		sealed.WriteString(part)
+	}
+	raw, err := base64.StdEncoding.DecodeString(sealed.String())
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	plain, err := aead.Open(nil, nonce(domainMetadata, 0, true), raw, nil)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	var env envelope
+	if err := json.Unmarshal(plain, &env); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDecrypt, err)
+	}
+	return &common.Metadata{
+		ContentType:     env.ContentType,
+		ContentEncoding: env.ContentEncoding,
+		Size:            PlaintextSize(stored.Size),
+		LastModified:    stored.LastModified,
+		ETag:            stored.ETag,
+		StorageClass:    stored.StorageClass,
+		Custom:          env.Custom,
+	}, nil
+}
+
+// IsEncrypted reports whether metadata is that of an end-to-end encrypted
+// object.
+func IsEncrypted(metadata *common.Metadata) bool {
+	return metadata != nil && field(metadata.Custom, MetaDataKey) != ""
+}
+
+// dataKey unwraps the data key of the object stored with metadata.
+func (k *Keychain) dataKey(stored *common.Metadata) (cipher.AEAD, error) {
+	if !IsEncrypted(stored) {
+		return nil, ErrNotEncrypted
+	}
+	if alg := field(stored.Custom, MetaAlgorithm); alg != Algorithm {
+		return nil, fmt.Errorf("%w: unsupported algorithm %q", ErrDecrypt, alg)
+	}
+	var dataKey []byte
+	keyID, kek, err := k.key(field(stored.Custom, MetaKeyID))
+	switch recovery := k.recoveryKey(); {
+	case err == nil:
+		dataKey, err = unwrapKey(kek, keyID, field(stored.Custom, MetaDataKey))
+	case errors.Is(err, ErrKeyNotFound) && recovery != nil:
+		// The keychain was lost or never held this key: fall back to the
+		// escrowed copy of the data key.
+		dataKey, err = recovery.recover(stored)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newAEAD(dataKey)
+}
+
+// sealEnvelope encrypts the envelope of metadata into stored.
+func sealEnvelope(aead cipher.AEAD, stored, metadata *common.Metadata) error {
+	var env envelope
+	if metadata != nil {
+		env = envelope{
+			ContentType:     metadata.ContentType,
+			ContentEncoding: metadata.ContentEncoding,
+			Custom:          metadata.Custom,
+		}
+	}
+	plain, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+	sealed := base64.StdEncoding.EncodeToString(aead.Seal(nil, nonce(domainMetadata, 0, true), plain, nil))
+	for i := 0; len(sealed) > 0; i++ {
+		n := min(len(sealed), common.MaxMetadataValueLength)
+		name := MetaEnvelope
+		if i > 0 {
+			name += "-" + strconv.Itoa(i)
+		}
+		stored.Custom[name] = sealed[:n]
+		sealed = sealed[n:]
+	}
+	return nil
+}
+
+// wrapKey encrypts dataKey with the keychain key keyID. The key ID is
+// authenticated, so a data key cannot be presented as wrapped by another
+// key.
+func wrapKey(kek []byte, keyID string, dataKey []byte) (string, error) {
+	aead, err := newAEAD(kek)
+	if err != nil {
+		return "", err
+	}
+	n := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(n); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(n, n, dataKey, []byte(keyID))
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// unwrapKey decrypts a data key wrapped by wrapKey.
+func unwrapKey(kek []byte, keyID, wrapped string) ([]byte, error) {
+	aead, err := newAEAD(kek)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := base64.StdEncoding.DecodeString(wrapped)
+	if err != nil || len(raw) < aead.NonceSize() {
+		return nil, ErrDecrypt
+	}
+	dataKey, err := aead.Open(nil, raw[:aead.NonceSize()], raw[aead.NonceSize():], []byte(keyID))
+	if err != nil || len(dataKey) != KeySize {
+		return nil, ErrDecrypt
+	}
+	return dataKey, nil
+}
+
+// field returns the custom metadata field name, ignoring case.
+func field(custom map[string]string, name string) string {
+	if v, ok := custom[name]; ok {
+		return v
+	}
+	for k, v := range custom {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package e2ee
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// Storage encrypts objects written to an underlying backend and decrypts
+// objects read from it. Listings, existence checks, deletes and archives
+// pass through, so listings report stored sizes and archives copy
+// ciphertext.
+type Storage struct {
+	common.Storage
+	keychain *Keychain
+}
+
+// NewStorage returns underlying with end-to-end encryption by keychain.
+func NewStorage(underlying common.Storage, keychain *Keychain) *Storage {
+	return &Storage{Storage: underlying, keychain: keychain}
+}
+
+// Underlying returns the wrapped backend.
+func (s *Storage) Underlying() common.Storage {
+	return s.Storage
+}
+
+// Keychain returns the keychain objects are encrypted with.
+func (s *Storage) Keychain() *Keychain {
+	return s.keychain
+}
+
+// Put encrypts and stores an object.
+func (s *Storage) Put(key string, data io.Reader) error {
+	return s.PutWithMetadata(context.Background(), key, data, nil)
+}
+
+// PutWithContext encrypts and stores an object.
+func (s *Storage) PutWithContext(ctx context.Context, key string, data io.Reader) error {
+	return s.PutWithMetadata(ctx, key, data, nil)
+}
+
+// PutWithMetadata encrypts and stores an object, encrypting its metadata
+// into the stored metadata.
+func (s *Storage) PutWithMetadata(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) error {
+	sealed, stored, err := s.keychain.Seal(data, metadata)
+	if err != nil {
+		return err
+	}
+	return s.Storage.PutWithMetadata(ctx, key, sealed, stored)
+}
+
+// PutWithInfo encrypts and stores an object like PutWithMetadata and
+// returns what was stored, with its metadata decrypted.
+func (s *Storage) PutWithInfo(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) (*common.ObjectInfo, error) {
+	sealed, stored, err := s.keychain.Seal(data, metadata)
+	if err != nil {
+		return nil, err
+	}
+	info, err := common.PutWithInfo(ctx, s.Storage, key, sealed, stored)
+	if err != nil || info.Metadata == nil {
+		return info, err
+	}
+	written := *info.Metadata
+	written.Custom = stored.Custom
+	opened, err := s.keychain.OpenMetadata(&written)
+	if err != nil {
+		return nil, err
+	}
+	opened.Version = written.Version
+	return &common.ObjectInfo{Key: key, Metadata: opened}, nil
+}
+
+// Get retrieves and decrypts an object.
+func (s *Storage) Get(key string) (io.ReadCloser, error) {
+	return s.GetWithContext(context.Background(), key)
+}
+
+// GetWithContext retrieves and decrypts an object. Reading the returned
+// content fails with ErrDecrypt if the stored object was modified.
+func (s *Storage) GetWithContext(ctx context.Context, key string) (io.ReadCloser, error) {
+	stored, err := s.Storage.GetMetadata(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	rc, err := s.Storage.GetWithContext(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := s.keychain.Open(rc, stored)
+	if err != nil {
+		_ = rc.Close()
+		return nil, err
+	}
+	return readCloser{Reader: plain, Closer: rc}, nil
+}
+
+// GetAsOf retrieves and decrypts an earlier version of an object, returning
+// that version's decrypted metadata.
+func (s *Storage) GetAsOf(ctx context.Context, key string, at time.Time) (io.ReadCloser, *common.Metadata, error) {
+	rc, stored, err := common.GetAsOf(ctx, s.Storage, key, at)
+	if err != nil {
+		return nil, nil, err
+	}
+	metadata, err := s.keychain.OpenMetadata(stored)
+	if err != nil {
+		_ = rc.Close()
+		return nil, nil, err
+	}
+	plain, err := s.keychain.Open(rc, stored)
+	if err != nil {
+		_ = rc.Close()
+		return nil, nil, err
+	}
+	return readCloser{Reader: plain, Closer: rc}, metadata, nil
+}
+
+// GetMetadata returns the decrypted metadata of an object.
+func (s *Storage) GetMetadata(ctx context.Context, key string) (*common.Metadata, error) {
+	stored, err := s.Storage.GetMetadata(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return s.keychain.OpenMetadata(stored)
+}
+
+// UpdateMetadata replaces the encrypted metadata of an object.
+func (s *Storage) UpdateMetadata(ctx context.Context, key string, metadata *common.Metadata) error {
+	stored, err := s.Storage.GetMetadata(ctx, key)
+	if err != nil {
+		return err
+	}
+	updated, err := s.keychain.Reseal(stored, metadata)
+	if err != nil {
+		return err
+	}
+	return s.Storage.UpdateMetadata(ctx, key, updated)
+}
+
+// readCloser reads decrypted content and closes the stored content.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+var _ common.Storage = (*Storage)(nil)
@@ -0,0 +1,73 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package e2ee
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"io"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// segmentSize is the plaintext size of each encrypted segment. Every
+// segment carries its own authentication tag.
+const segmentSize = common.EncryptionSegmentSize
+
+// tagSize is the size of a GCM authentication tag.
+const tagSize = 16
+
+// domainMetadata is the first nonce byte of the metadata envelope. Content
+// segments, which share the data key, use zero (see common.NewSegmentSealer).
+const domainMetadata = 1
+
+// newAEAD returns AES-256-GCM with key.
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	return common.NewAESGCM(key)
+}
+
+// nonce returns the nonce of value counter in domain, marked final if it
+// is the last one.
+func nonce(domain byte, counter uint64, final bool) []byte {
+	n := make([]byte, 12)
+	n[0] = domain
+	if final {
+		n[1] = 1
+	}
+	binary.BigEndian.PutUint64(n[4:], counter)
+	return n
+}
+
+// CiphertextSize returns the stored size of an object of plaintextSize
+// bytes.
+func CiphertextSize(plaintextSize int64) int64 {
+	return common.SegmentedCiphertextSize(plaintextSize)
+}
+
+// PlaintextSize returns the size of the object a stored object of
+// ciphertextSize bytes decrypts to.
+func PlaintextSize(ciphertextSize int64) int64 {
+	return common.SegmentedPlaintextSize(ciphertextSize)
+}
+
+// newSealReader encrypts src in segments as it is read.
+func newSealReader(aead cipher.AEAD, src io.Reader) io.Reader {
+	return common.NewSegmentSealer(aead, src)
+}
+
+// newOpenReader decrypts and authenticates src segment by segment as it
+// is read.
+func newOpenReader(aead cipher.AEAD, src io.Reader) io.Reader {
+	return common.NewSegmentOpener(aead, src)
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package envflag sets command-line flags from environment variables, so
+// servers can be configured entirely from a container's environment, as
+// Helm charts and other deployment tools do. Each flag has a variable named
+// after it: with prefix OBJSTORE_, --rest-port is OBJSTORE_REST_PORT.
+// Flags given on the command line take precedence over the environment.
+package envflag
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ErrInvalidValue is returned for an environment variable whose value the
+// flag rejects.
+var ErrInvalidValue = errors.New("envflag: invalid value")
+
+// Name returns the environment variable of the flag called name.
+func Name(prefix, name string) string {
+	return prefix + strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(name))
+}
+
+// Apply sets every flag of fs that was not given on the command line from
+// its environment variable, if that is set. Call it after fs.Parse. A value
+// of several lines sets the flag once per non-empty line, for repeatable
+// flags such as --backend-setting.
+func Apply(fs *flag.FlagSet, prefix string) error {
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+
+	var errs []error
+	fs.VisitAll(func(f *flag.Flag) {
+		if set[f.Name] {
+			return
+		}
+		env := Name(prefix, f.Name)
+		value, ok := os.LookupEnv(env)
+		if !ok {
+			return
+		}
+		values := []string{value}
+		if strings.Contains(value, "\n") {
+			values = values[:0]
+			for _, line := range strings.Split(value, "\n") {
+				if line = strings.TrimSpace(line); line != "" {
+					values = append(values, line)
+				}
+			}
+		}
+		for _, v := range values {
+			if err := fs.Set(f.Name, v); err != nil {
+				errs = append(errs, fmt.Errorf("%w: %s: %v", ErrInvalidValue, env, err))
+				return
+			}
+		}
+	})
+	return errors.Join(errs...)
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package envflag
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestName(t *testing.T) {
+	if got := Name("OBJSTORE_", "rest-port"); got != "OBJSTORE_REST_PORT" {
+		t.Errorf("Name() = %q", got)
+	}
+}
+
+func TestApply(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	backend := fs.String("backend", "local", "")
+	port := fs.Int("rest-port", 8080, "")
+	grpc := fs.Bool("grpc", true, "")
+	interval := fs.Duration("lifecycle-interval", 0, "")
+	var settings []string
+	fs.Func("backend-setting", "", func(s string) error {
+		settings = append(settings, s)
+		return nil
+	})
+
+	t.Setenv("OBJSTORE_BACKEND", "s3")
+	t.Setenv("OBJSTORE_REST_PORT", "9000")
+	t.Setenv("OBJSTORE_GRPC", "false")
+	t.Setenv("OBJSTORE_LIFECYCLE_INTERVAL", "1h")
+	t.Setenv("OBJSTORE_BACKEND_SETTING", "bucket=data\n  region=us-east-1\n\n")
+
+	if err := fs.Parse([]string{"--rest-port", "8081"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Apply(fs, "OBJSTORE_"); err != nil {
+		t.Fatal(err)
+	}
+	if *backend != "s3" || *grpc || *interval != time.Hour {
+		t.Errorf("backend = %q, grpc = %v, interval = %v", *backend, *grpc, *interval)
+	}
+	if *port != 8081 {
+		t.Errorf("rest-port = %d, want the command line's 8081", *port)
+	}
+	if !slices.Equal(settings, []string{"bucket=data", "region=us-east-1"}) {
+		t.Errorf("backend-setting = %q", settings)
+	}
+}
+
+func TestApplyInvalid(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Int("rest-port", 8080, "")
+	fs.Bool("grpc", true, "")
+	t.Setenv("OBJSTORE_REST_PORT", "eighty")
+	t.Setenv("OBJSTORE_GRPC", "maybe")
+
+	err := Apply(fs, "OBJSTORE_")
+	if !errors.Is(err, ErrInvalidValue) {
+		t.Fatalf("Apply() error = %v, want ErrInvalidValue", err)
+	}
+	for _, env := range []string{"OBJSTORE_REST_PORT", "OBJSTORE_GRPC"} {
+		if !strings.Contains(err.Error(), env) {
+			t.Errorf("error %q does not name %s", err, env)
+		}
+	}
+}
@@ -0,0 +1,952 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package erasure stores objects erasure-coded across several backends.
+//
+// A Storage splits every object into stripes of DataShards blocks and
+// computes ParityShards Reed-Solomon parity blocks per stripe. Shard i of
+// an object, made of block i of every stripe, is written under the
+// object's key to backend i, so an object survives the loss of any
+// ParityShards backends while taking only (DataShards+ParityShards) /
+// DataShards times its size, instead of a full copy per replica. Reads use
+// the data shards when they are all available and rebuild missing ones
+// from parity otherwise, switching shards mid-stream if a backend fails
+// while an object is being read.
+//
+// A write succeeds once WriteQuorum shards are stored. Each write is tagged
+// with a random version, recorded in every shard's metadata and header, so
+// shards left over from an earlier or a failed write are never combined
+// with the current ones. Heal rewrites the shards an object is missing,
+// for example after a backend was replaced or was down during a write.
+//
+// Shards carry the object's metadata plus erasure-* custom fields
+// describing the encoding. The backends must all be dedicated to the
+// Storage and each must keep custom metadata.
+package erasure
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5" // #nosec G501 -- MD5 is the conventional ETag, not a security measure
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+const (
+	// DefaultParityShards is the number of parity shards used when none is
+	// configured.
+	DefaultParityShards = 1
+
+	// DefaultBlockSize is the largest block of a shard per stripe (256 KiB).
+	// Objects smaller than a full stripe are split into smaller blocks.
+	DefaultBlockSize = 256 * 1024
+
+	// Metadata fields recorded on every shard.
+	metaIndex           = "erasure-index"
+	metaData            = "erasure-data"
+	metaParity          = "erasure-parity"
+	metaBlock           = "erasure-block-size"
+	metaSize            = "erasure-size"
+	metaETag            = "erasure-etag"
+	metaVersion         = "erasure-version"
+	metaWritten         = "erasure-written"
+	metaContentType     = "erasure-content-type"
+	metaContentEncoding = "erasure-content-encoding"
+
+	// Every shard starts with a header of headerMagic, the header format,
+	// the shard index, two reserved bytes and the 16-byte write version.
+	headerMagic  = "OSEC"
+	headerFormat = 1
+	headerSize   = 24
+	versionSize  = 16
+
+	defaultListPageSize = 1000
+)
+
+var (
+	// ErrInvalidConfig is returned for an unusable shard layout. It wraps
+	// common.ErrInvalidArgument.
+	ErrInvalidConfig = fmt.Errorf("%w: invalid erasure configuration", common.ErrInvalidArgument)
+
+	// ErrNotEnoughShards is returned when fewer than DataShards shards of an
+	// object can be read, or fewer than WriteQuorum could be written. It
+	// wraps common.ErrUnavailable.
+	ErrNotEnoughShards = fmt.Errorf("%w: not enough erasure shards", common.ErrUnavailable)
+
+	// errStaleShard is returned when a shard belongs to another write.
+	errStaleShard = errors.New("shard belongs to another write")
+)
+
+// Config describes how objects are split. Zero fields take their defaults.
+type Config struct {
+	// DataShards is the number of shards an object's data is split into.
+	// Zero uses the number of backends minus ParityShards.
+	DataShards int
+
+	// ParityShards is the number of parity shards, which is also the
+	// number of backends that can fail without losing data. Zero uses
+	// DefaultParityShards.
+	ParityShards int
+
+	// BlockSize is the largest number of bytes of one shard per stripe.
+	// Zero uses DefaultBlockSize.
+	BlockSize int
+
+	// WriteQuorum is the number of shards that must be stored for a write
+	// to succeed, between DataShards and the number of backends. Zero uses
+	// DataShards+1, or the number of backends if that is smaller.
+	WriteQuorum int
+}
+
+// Storage erasure-codes objects across a fixed list of backends. It
+// implements common.Storage and is safe for concurrent use.
+type Storage struct {
+	common.LifecycleManager
+
+	backends []common.Storage
+
+	mu    sync.RWMutex
+	base  Config
+	cfg   Config
+	coder *coder
+}
+
+// New returns a Storage writing shard i of every object to backends[i].
+// A nil cfg uses the defaults. The number of backends must equal
+// DataShards+ParityShards.
+func New(backends []common.Storage, cfg *Config) (*Storage, error) {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	s := &Storage{
+		LifecycleManager: memory.NewLifecycleManager(),
+		backends:         backends,
+	}
+	if err := s.apply(*cfg); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Backends returns the backends shards are written to, by shard index.
+func (s *Storage) Backends() []common.Storage {
+	return s.backends
+}
+
+// Config returns the configuration in effect, with defaults filled in.
+func (s *Storage) Config() Config {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cfg
+}
+
+// Configure changes the configuration from the "dataShards",
+// "parityShards", "blockSize" and "writeQuorum" settings. Settings that
+// are not given keep their current value. Objects already stored keep the
+// layout they were written with.
+func (s *Storage) Configure(settings map[string]string) error {
+	s.mu.RLock()
+	cfg := s.base
+	s.mu.RUnlock()
+	fields := []struct {
+		name  string
+		value *int
+	}{
+		{"dataShards", &cfg.DataShards},
+		{"parityShards", &cfg.ParityShards},
+		{"blockSize", &cfg.BlockSize},
+		{"writeQuorum", &cfg.WriteQuorum},
+	}
+	for _, f := range fields {
+		v, ok := settings[f.name]
+		if !ok || v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrInvalidConfig, f.name, err)
+		}
+		*f.value = n
+	}
+	return s.apply(cfg)
+}
+
+// apply validates base, fills in its defaults and makes it current.
+func (s *Storage) apply(base Config) error {
+	n := len(s.backends)
+	if n == 0 {
+		return fmt.Errorf("%w: no backends", ErrInvalidConfig)
+	}
+	for i, b := range s.backends {
+		if b == nil {
+			return fmt.Errorf("%w: backend %d is nil", ErrInvalidConfig, i)
+		}
+	}
+	cfg := base
+	if cfg.ParityShards == 0 {
+		cfg.ParityShards = DefaultParityShards
+	}
+	if cfg.DataShards == 0 {
+		cfg.DataShards = n - cfg.ParityShards
+	}
+	if cfg.DataShards < 1 || cfg.ParityShards < 0 || cfg.DataShards+cfg.ParityShards != n {
+		return fmt.Errorf("%w: %d data and %d parity shards do not fit %d backends",
+			ErrInvalidConfig, cfg.DataShards, cfg.ParityShards, n)
+	}
+	if cfg.BlockSize == 0 {
+		cfg.BlockSize = DefaultBlockSize
+	}
+	if cfg.BlockSize < 1 {
+		return fmt.Errorf("%w: block size %d", ErrInvalidConfig, cfg.BlockSize)
+	}
+	if cfg.WriteQuorum == 0 {
+		cfg.WriteQuorum = min(cfg.DataShards+1, n)
+	}
+	if cfg.WriteQuorum < cfg.DataShards || cfg.WriteQuorum > n {
+		return fmt.Errorf("%w: write quorum %d must be between %d and %d",
+			ErrInvalidConfig, cfg.WriteQuorum, cfg.DataShards, n)
+	}
+	c, err := newCoder(cfg.DataShards, cfg.ParityShards)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.base, s.cfg, s.coder = base, cfg, c
+	return nil
+}
+
+// current returns the configuration and coder new writes use.
+func (s *Storage) current() (Config, *coder) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cfg, s.coder
+}
+
+// coderFor returns the coder for objects stored with data and parity
+// shards, which differ from the current ones after a reconfiguration.
+func (s *Storage) coderFor(data, parity int) (*coder, error) {
+	cfg, c := s.current()
+	if cfg.DataShards == data && cfg.ParityShards == parity {
+		return c, nil
+	}
+	return newCoder(data, parity)
+}
+
+// Put stores data erasure-coded under key.
+func (s *Storage) Put(key string, data io.Reader) error {
+	return s.PutWithMetadata(context.Background(), key, data, nil)
+}
+
+// PutWithContext stores data erasure-coded under key with context support.
+func (s *Storage) PutWithContext(ctx context.Context, key string, data io.Reader) error {
+	return s.PutWithMetadata(ctx, key, data, nil)
+}
+
+// PutWithMetadata stores data erasure-coded under key with metadata. The
+// object's size must be known before its blocks can be laid out, so data
+// is spooled to a temporary file first; the caller's metadata is not
+// modified. The write fails with ErrNotEnoughShards when fewer than
+// WriteQuorum shards could be stored.
+func (s *Storage) PutWithMetadata(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) error {
+	spool, err := os.CreateTemp("", "objstore-erasure-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = spool.Close()
+		_ = os.Remove(spool.Name())
+	}()
+
+	sum := md5.New() // #nosec G401 -- see import
+	size, err := io.Copy(io.MultiWriter(spool, sum), data)
+	if err == nil {
+		_, err = spool.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		return err
+	}
+
+	cfg, c := s.current()
+	version := make([]byte, versionSize)
+	if _, err := rand.Read(version); err != nil {
+		return err
+	}
+	obj := &object{
+		version: hex.EncodeToString(version),
+		written: time.Now().UnixNano(),
+		data:    cfg.DataShards,
+		parity:  cfg.ParityShards,
+		block:   blockSize(size, cfg.DataShards, cfg.BlockSize),
+		size:    size,
+		etag:    hex.EncodeToString(sum.Sum(nil)),
+		meta:    metadata,
+	}
+	targets := make([]int, len(s.backends))
+	for i := range targets {
+		targets[i] = i
+	}
+	written, err := s.writeShards(ctx, key, spool, obj, c, targets)
+	if written < cfg.WriteQuorum {
+		return fmt.Errorf("%w: stored %d of %d shards of %s, need %d: %w",
+			ErrNotEnoughShards, written, len(targets), key, cfg.WriteQuorum, err)
+	}
+	return nil
+}
+
+// Heal rewrites the shards of the object under key that are missing or
+// belong to another write, rebuilding them from the others. It returns the
+// number of shards written.
+func (s *Storage) Heal(ctx context.Context, key string) (int, error) {
+	loc, err := s.locate(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	var missing []int
+	for i := range s.backends {
+		if !loc.has(i) {
+			missing = append(missing, i)
+		}
+	}
+	if len(missing) == 0 {
+		return 0, nil
+	}
+	c, err := s.coderFor(loc.data, loc.parity)
+	if err != nil {
+		return 0, err
+	}
+	r, err := s.newReader(ctx, key, loc, c)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = r.Close() }()
+	written, err := s.writeShards(ctx, key, r, loc.object, c, missing)
+	if err != nil {
+		return written, fmt.Errorf("failed to heal %s: %w", key, err)
+	}
+	return written, nil
+}
+
+// writeShards encodes size bytes of src as obj and streams shard i to
+// backend i for every i in targets. It returns how many shards were
+// stored and the errors of the others.
+func (s *Storage) writeShards(ctx context.Context, key string, src io.Reader, obj *object, c *coder, targets []int) (int, error) {
+	total := obj.data + obj.parity
+	writers := make([]*io.PipeWriter, total)
+	putErrs := make([]error, total)
+	writeErrs := make([]error, total)
+
+	var wg sync.WaitGroup
+	for _, i := range targets {
+		pr, pw := io.Pipe()
+		writers[i] = pw
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := s.backends[i].PutWithMetadata(ctx, key, pr, obj.shardMetadata(i))
+			_ = pr.CloseWithError(err)
+			putErrs[i] = err
+		}()
+	}
+	send := func(i int, p []byte) {
+		if writers[i] == nil {
+			return
+		}
+		if _, err := writers[i].Write(p); err != nil {
+			_ = writers[i].CloseWithError(err)
+			writers[i] = nil
+			writeErrs[i] = err
+		}
+	}
+
+	for _, i := range targets {
+		send(i, obj.header(i))
+	}
+	shards := make([][]byte, total)
+	for i := range shards {
+		shards[i] = make([]byte, obj.block)
+	}
+	var srcErr error
+	left := obj.size
+	for left > 0 && srcErr == nil {
+		for d := 0; d < obj.data; d++ {
+			n := min(int64(obj.block), left)
+			if _, err := io.ReadFull(src, shards[d][:n]); err != nil {
+				srcErr = fmt.Errorf("failed to read object data: %w", err)
+				break
+			}
+			clear(shards[d][n:])
+			left -= n
+		}
+		if srcErr != nil {
+			break
+		}
+		c.encode(shards)
+		for _, i := range targets {
+			send(i, shards[i])
+		}
+	}
+
+	for _, i := range targets {
+		if writers[i] == nil {
+			continue
+		}
+		if srcErr != nil {
+			_ = writers[i].CloseWithError(srcErr)
+		} else {
+			_ = writers[i].Close()
+		}
+	}
+	wg.Wait()
+
+	written := 0
+	var failures []error
+	for _, i := range targets {
+		err := putErrs[i]
+		if err == nil {
+			// The backend returned before reading the whole shard.
+			err = writeErrs[i]
+		}
+		if err != nil {
+			failures = append(failures, fmt.Errorf("shard %d: %w", i, err))
+			continue
+		}
+		written++
+	}
+	return written, errors.Join(failures...)
+}
+
+// Get retrieves and decodes the object under key.
+func (s *Storage) Get(key string) (io.ReadCloser, error) {
+	return s.GetWithContext(context.Background(), key)
+}
+
+// GetWithContext retrieves and decodes the object under key. It fails with
+// ErrNotEnoughShards when fewer than DataShards shards can be opened.
+func (s *Storage) GetWithContext(ctx context.Context, key string) (io.ReadCloser, error) {
+	loc, err := s.locate(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	c, err := s.coderFor(loc.data, loc.parity)
+	if err != nil {
+		return nil, err
+	}
+	return s.newReader(ctx, key, loc, c)
+}
+
+// GetMetadata returns the object's metadata with its original size and
+// the MD5 digest of its content as ETag.
+func (s *Storage) GetMetadata(ctx context.Context, key string) (*common.Metadata, error) {
+	loc, err := s.locate(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return loc.metadata(), nil
+}
+
+// UpdateMetadata replaces the metadata of every shard of the object,
+// keeping the fields describing the encoding.
+func (s *Storage) UpdateMetadata(ctx context.Context, key string, metadata *common.Metadata) error {
+	loc, err := s.locate(ctx, key)
+	if err != nil {
+		return err
+	}
+	update := *loc.object
+	update.meta = metadata
+	errs := make([]error, len(s.backends))
+	s.each(func(i int, backend common.Storage) {
+		if loc.has(i) {
+			errs[i] = backend.UpdateMetadata(ctx, key, update.shardMetadata(i))
+		}
+	})
+	var failures []error
+	for i, err := range errs {
+		if err != nil {
+			failures = append(failures, fmt.Errorf("shard %d: %w", i, err))
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("failed to update metadata of %s: %w", key, errors.Join(failures...))
+	}
+	return nil
+}
+
+// Delete removes the object's shards from every backend.
+func (s *Storage) Delete(key string) error {
+	return s.DeleteWithContext(context.Background(), key)
+}
+
+// DeleteWithContext removes the object's shards from every backend. Shards
+// on unavailable backends are left behind; that is only an error when
+// there could be enough of them to still read the object.
+func (s *Storage) DeleteWithContext(ctx context.Context, key string) error {
+	errs := make([]error, len(s.backends))
+	s.each(func(i int, backend common.Storage) {
+		errs[i] = backend.DeleteWithContext(ctx, key)
+	})
+	deleted := 0
+	var failures []error
+	for i, err := range errs {
+		switch {
+		case err == nil:
+			deleted++
+		case !errors.Is(err, common.ErrNotFound):
+			failures = append(failures, fmt.Errorf("shard %d: %w", i, err))
+		}
+	}
+	cfg, _ := s.current()
+	if len(failures) >= cfg.DataShards {
+		return fmt.Errorf("failed to delete %s: %w", key, errors.Join(failures...))
+	}
+	if deleted == 0 && len(failures) == 0 {
+		return fmt.Errorf("%w: %s", common.ErrKeyNotFound, key)
+	}
+	return nil
+}
+
+// Exists reports whether enough shards of key are stored to read it.
+func (s *Storage) Exists(ctx context.Context, key string) (bool, error) {
+	_, err := s.locate(ctx, key)
+	if errors.Is(err, common.ErrKeyNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// List returns the keys under prefix.
+func (s *Storage) List(prefix string) ([]string, error) {
+	return s.ListWithContext(context.Background(), prefix)
+}
+
+// ListWithContext returns the keys under prefix that have at least
+// DataShards shards on the backends that could be listed.
+func (s *Storage) ListWithContext(ctx context.Context, prefix string) ([]string, error) {
+	entries, err := s.listAll(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// ListWithOptions returns a page of objects like ListWithContext, with
+// their logical metadata. Backends that do not return custom metadata in
+// listings cost a GetMetadata call per listed object.
+func (s *Storage) ListWithOptions(ctx context.Context, opts *common.ListOptions) (*common.ListResult, error) {
+	if opts == nil {
+		opts = &common.ListOptions{}
+	}
+	entries, err := s.listAll(ctx, opts.Prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sort objects and common prefixes together so pages split them in
+	// key order.
+	type item struct {
+		name   string
+		prefix bool
+	}
+	seen := make(map[string]bool)
+	items := make([]item, 0, len(entries))
+	for key := range entries {
+		if opts.Delimiter != "" {
+			rest := strings.TrimPrefix(key, opts.Prefix)
+			if i := strings.Index(rest, opts.Delimiter); i >= 0 {
+				p := opts.Prefix + rest[:i+len(opts.Delimiter)]
+				if !seen[p] {
+					seen[p] = true
+					items = append(items, item{name: p, prefix: true})
+				}
+				continue
+			}
+		}
+		items = append(items, item{name: key})
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].name < items[j].name })
+
+	start := 0
+	if opts.ContinueFrom != "" {
+		start = sort.Search(len(items), func(i int) bool { return items[i].name > opts.ContinueFrom })
+	}
+	limit := opts.MaxResults
+	if limit <= 0 {
+		limit = defaultListPageSize
+	}
+	end := min(start+limit, len(items))
+
+	result := &common.ListResult{
+		Objects:        []*common.ObjectInfo{},
+		CommonPrefixes: []string{},
+	}
+	for _, it := range items[start:end] {
+		if it.prefix {
+			result.CommonPrefixes = append(result.CommonPrefixes, it.name)
+			continue
+		}
+		meta := entries[it.name]
+		if meta == nil {
+			meta, err = s.GetMetadata(ctx, it.name)
+			if errors.Is(err, common.ErrNotFound) || errors.Is(err, ErrNotEnoughShards) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		result.Objects = append(result.Objects, &common.ObjectInfo{Key: it.name, Metadata: meta})
+	}
+	if end < len(items) {
+		result.Truncated = true
+		result.NextToken = items[end-1].name
+	}
+	return result, nil
+}
+
+// listAll lists every backend under prefix and returns the keys with at
+// least as many shards as their objects need, mapped to their logical
+// metadata when the listings carried it.
+func (s *Storage) listAll(ctx context.Context, prefix string) (map[string]*common.Metadata, error) {
+	listings := make([][]*common.ObjectInfo, len(s.backends))
+	errs := make([]error, len(s.backends))
+	s.each(func(i int, backend common.Storage) {
+		listings[i], errs[i] = listBackend(ctx, backend, prefix)
+	})
+
+	cfg, _ := s.current()
+	var failures []error
+	for i, err := range errs {
+		if err != nil {
+			failures = append(failures, fmt.Errorf("backend %d: %w", i, err))
+		}
+	}
+	if len(s.backends)-len(failures) < cfg.DataShards {
+		return nil, fmt.Errorf("%w: listing %q: %w", ErrNotEnoughShards, prefix, errors.Join(failures...))
+	}
+
+	type tally struct {
+		shards int
+		obj    *object
+	}
+	tallies := make(map[string]*tally)
+	for i, listing := range listings {
+		for _, info := range listing {
+			t := tallies[info.Key]
+			if t == nil {
+				t = &tally{}
+				tallies[info.Key] = t
+			}
+			t.shards++
+			if t.obj == nil {
+				t.obj, _ = parseShard(info.Metadata, i, len(s.backends))
+			}
+		}
+	}
+
+	entries := make(map[string]*common.Metadata, len(tallies))
+	for key, t := range tallies {
+		need := cfg.DataShards
+		var meta *common.Metadata
+		if t.obj != nil {
+			need, meta = t.obj.data, t.obj.metadata()
+		}
+		if t.shards >= need {
+			entries[key] = meta
+		}
+	}
+	return entries, nil
+}
+
+// listBackend returns every object under prefix on backend.
+func listBackend(ctx context.Context, backend common.Storage, prefix string) ([]*common.ObjectInfo, error) {
+	opts := &common.ListOptions{Prefix: prefix}
+	var out []*common.ObjectInfo
+	for {
+		page, err := backend.ListWithOptions(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, info := range page.Objects {
+			if info != nil {
+				out = append(out, info)
+			}
+		}
+		if !page.Truncated || page.NextToken == "" {
+			return out, nil
+		}
+		opts.ContinueFrom = page.NextToken
+	}
+}
+
+// Archive copies the decoded object to destination.
+func (s *Storage) Archive(key string, destination common.Archiver) error {
+	if destination == nil {
+		return common.ErrArchiveDestinationNil
+	}
+	rc, err := s.Get(key)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rc.Close() }()
+	return destination.Put(key, rc)
+}
+
+// each calls fn for every backend concurrently and waits for all calls.
+func (s *Storage) each(fn func(i int, backend common.Storage)) {
+	var wg sync.WaitGroup
+	for i, backend := range s.backends {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fn(i, backend)
+		}()
+	}
+	wg.Wait()
+}
+
+// location is the newest readable write of an object and the shards that
+// hold it.
+type location struct {
+	*object
+	shards []int
+}
+
+// has reports whether shard i holds the located write.
+func (l *location) has(i int) bool {
+	for _, j := range l.shards {
+		if i == j {
+			return true
+		}
+	}
+	return false
+}
+
+// locate reads the metadata of every shard of key and picks the newest
+// write that has at least DataShards shards. It fails with
+// common.ErrKeyNotFound when every backend answered without such a write
+// and with ErrNotEnoughShards when some did not answer.
+func (s *Storage) locate(ctx context.Context, key string) (*location, error) {
+	metas := make([]*common.Metadata, len(s.backends))
+	errs := make([]error, len(s.backends))
+	s.each(func(i int, backend common.Storage) {
+		metas[i], errs[i] = backend.GetMetadata(ctx, key)
+	})
+
+	writes := make(map[string]*location)
+	found := 0
+	var failures []error
+	for i := range s.backends {
+		if errs[i] != nil {
+			if !errors.Is(errs[i], common.ErrNotFound) {
+				failures = append(failures, fmt.Errorf("shard %d: %w", i, errs[i]))
+			}
+			continue
+		}
+		obj, ok := parseShard(metas[i], i, len(s.backends))
+		if !ok {
+			continue
+		}
+		found++
+		loc := writes[obj.version]
+		if loc == nil {
+			loc = &location{object: obj}
+			writes[obj.version] = loc
+		}
+		loc.shards = append(loc.shards, i)
+	}
+
+	var best *location
+	for _, loc := range writes {
+		if len(loc.shards) < loc.data {
+			continue
+		}
+		if best == nil || loc.written > best.written || (loc.written == best.written && loc.version > best.version) {
+			best = loc
+		}
+	}
+	switch {
+	case best != nil:
+		return best, nil
+	case len(failures) == 0:
+		// Every backend answered, so any shards found are leftovers of
+		// deleted objects or failed writes.
+		return nil, fmt.Errorf("%w: %s", common.ErrKeyNotFound, key)
+	default:
+		return nil, fmt.Errorf("%w: %s has %d shards available: %w", ErrNotEnoughShards, key, found, errors.Join(failures...))
+	}
+}
+
+// object describes one write of an object.
+type object struct {
+	version string
+	written int64
+	data    int
+	parity  int
+	block   int
+	size    int64
+	etag    string
+
+	// meta is the caller's metadata, without the encoding fields.
+	meta *common.Metadata
+}
+
+// blockSize returns the block size for an object of size bytes split into
+// data shards: the smallest that fits the object into one stripe, at most
+// limit.
+func blockSize(size int64, data, limit int) int {
+	per := (size + int64(data) - 1) / int64(data)
+	return int(max(1, min(per, int64(limit))))
+}
+
+// header returns the header of shard i.
+func (o *object) header(i int) []byte {
+	h := make([]byte, headerSize)
+	copy(h, headerMagic)
+	h[4] = headerFormat
+	h[5] = byte(i)
+	version, _ := hex.DecodeString(o.version)
+	copy(h[headerSize-versionSize:], version)
+	return h
+}
+
+// shardMetadata returns the metadata stored with shard i: the caller's
+// metadata plus the encoding fields. The content type and encoding are
+// kept in custom fields so backends never transform shard content.
+func (o *object) shardMetadata(i int) *common.Metadata {
+	m := &common.Metadata{}
+	if o.meta != nil {
+		m.StorageClass = o.meta.StorageClass
+		m.Custom = maps.Clone(o.meta.Custom)
+	}
+	if m.Custom == nil {
+		m.Custom = make(map[string]string, 10)
+	}
+	m.ContentType = "application/octet-stream"
+	if o.meta != nil && o.meta.ContentType != "" {
+		m.Custom[metaContentType] = o.meta.ContentType
+	}
+	if o.meta != nil && o.meta.ContentEncoding != "" {
+		m.Custom[metaContentEncoding] = o.meta.ContentEncoding
+	}
+	m.Custom[metaIndex] = strconv.Itoa(i)
+	m.Custom[metaData] = strconv.Itoa(o.data)
+	m.Custom[metaParity] = strconv.Itoa(o.parity)
+	m.Custom[metaBlock] = strconv.Itoa(o.block)
+	m.Custom[metaSize] = strconv.FormatInt(o.size, 10)
+	m.Custom[metaETag] = o.etag
+	m.Custom[metaVersion] = o.version
+	m.Custom[metaWritten] = strconv.FormatInt(o.written, 10)
+	return m
+}
+
+// metadata returns the object's metadata as callers see it.
+func (o *object) metadata() *common.Metadata {
+	m := &common.Metadata{}
+	if o.meta != nil {
+		*m = *o.meta
+		m.Custom = maps.Clone(o.meta.Custom)
+	}
+	m.Size = o.size
+	m.ETag = o.etag
+	return m
+}
+
+// parseShard decodes the encoding fields of shard i's metadata. It reports
+// false for objects that were not written by a Storage with total shards
+// and for shards stored on the wrong backend.
+func parseShard(meta *common.Metadata, i, total int) (*object, bool) {
+	if meta == nil {
+		return nil, false
+	}
+	field := func(name string) string { return common.CustomField(meta.Custom, name) }
+	index, err1 := strconv.Atoi(field(metaIndex))
+	data, err2 := strconv.Atoi(field(metaData))
+	parity, err3 := strconv.Atoi(field(metaParity))
+	block, err4 := strconv.Atoi(field(metaBlock))
+	size, err5 := strconv.ParseInt(field(metaSize), 10, 64)
+	written, err6 := strconv.ParseInt(field(metaWritten), 10, 64)
+	if err := errors.Join(err1, err2, err3, err4, err5, err6); err != nil {
+		return nil, false
+	}
+	version := field(metaVersion)
+	if raw, err := hex.DecodeString(version); err != nil || len(raw) != versionSize {
+		return nil, false
+	}
+	if index != i || data < 1 || parity < 0 || data+parity != total || block < 1 || size < 0 {
+		return nil, false
+	}
+
+	user := *meta
+	user.ContentType = field(metaContentType)
+	user.ContentEncoding = field(metaContentEncoding)
+	user.Custom = make(map[string]string, len(meta.Custom))
+	for k, v := range meta.Custom {
+		if !strings.HasPrefix(strings.ToLower(k), "erasure-") {
+			user.Custom[k] = v
+		}
+	}
+	return &object{
+		version: version,
+		written: written,
+		data:    data,
+		parity:  parity,
+		block:   block,
+		size:    size,
+		etag:    field(metaETag),
+		meta:    &user,
+	}, true
+}
+
+// openShard opens shard i of the located write and positions it offset
+// bytes into the shard's blocks.
+func (s *Storage) openShard(ctx context.Context, key string, i int, obj *object, offset int64) (io.ReadCloser, error) {
+	rc, err := s.backends[i].GetWithContext(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	header := make([]byte, headerSize)
+	if _, err = io.ReadFull(rc, header); err == nil {
+		if !bytes.Equal(header, obj.header(i)) {
+			err = errStaleShard
+		} else if offset > 0 {
+			_, err = io.CopyN(io.Discard, rc, offset)
+		}
+	}
+	if err != nil {
+		_ = rc.Close()
+		return nil, err
+	}
+	return rc, nil
+}
+
+// Ensure Storage implements Storage interface at compile time
+var _ common.Storage = (*Storage)(nil)
@@ -0,0 +1,416 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package erasure
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5" // #nosec G501 -- comparing against the ETag
+	"encoding/hex"
+	"errors"
+	"io"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/faultinject"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+	"github.com/jeremyhahn/go-objstore/pkg/storagetest"
+)
+
+// down makes every operation fail; flaky truncates every read.
+var (
+	down  = &faultinject.Config{Default: faultinject.Fault{ErrorRate: 1}}
+	flaky = &faultinject.Config{Ops: map[faultinject.Op]faultinject.Fault{
+		faultinject.OpGet: {PartialReadRate: 1},
+	}}
+)
+
+// newTestStorage returns a Storage over n memory backends, each wrapped in
+// a fault injector that is disabled until a test enables it.
+func newTestStorage(t *testing.T, n int, cfg *Config, faults *faultinject.Config) (*Storage, []*faultinject.Storage) {
+	t.Helper()
+	injectors := make([]*faultinject.Storage, n)
+	backends := make([]common.Storage, n)
+	for i := range backends {
+		injectors[i] = faultinject.New(memory.New(), faults)
+		injectors[i].SetEnabled(false)
+		backends[i] = injectors[i]
+	}
+	s, err := New(backends, cfg)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return s, injectors
+}
+
+func randomBytes(n int) []byte {
+	b := make([]byte, n)
+	rand.New(rand.NewSource(int64(n))).Read(b)
+	return b
+}
+
+func read(t *testing.T, s common.Storage, key string) []byte {
+	t.Helper()
+	rc, err := s.Get(key)
+	if err != nil {
+		t.Fatalf("Get(%s) error = %v", key, err)
+	}
+	defer rc.Close()
+	got, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatalf("reading %s: %v", key, err)
+	}
+	return got
+}
+
+func TestConformance(t *testing.T) {
+	storagetest.TestStorage(t, func(t *testing.T) common.Storage {
+		s, _ := newTestStorage(t, 3, nil, nil)
+		return s
+	})
+}
+
+func TestNewDefaults(t *testing.T) {
+	s, _ := newTestStorage(t, 6, &Config{ParityShards: 2}, nil)
+	want := Config{DataShards: 4, ParityShards: 2, BlockSize: DefaultBlockSize, WriteQuorum: 5}
+	if got := s.Config(); got != want {
+		t.Errorf("Config() = %+v, want %+v", got, want)
+	}
+
+	s, _ = newTestStorage(t, 2, nil, nil)
+	if got := s.Config(); got.DataShards != 1 || got.ParityShards != 1 || got.WriteQuorum != 2 {
+		t.Errorf("Config() = %+v, want 1 data and 1 parity shard, quorum 2", got)
+	}
+}
+
+func TestNewInvalidConfig(t *testing.T) {
+	backends := []common.Storage{memory.New(), memory.New(), memory.New()}
+	tests := []struct {
+		name     string
+		backends []common.Storage
+		cfg      Config
+	}{
+		{"no backends", nil, Config{}},
+		{"nil backend", []common.Storage{memory.New(), nil}, Config{}},
+		{"too many shards", backends, Config{DataShards: 3, ParityShards: 1}},
+		{"too few shards", backends, Config{DataShards: 1, ParityShards: 1}},
+		{"no data shards", backends, Config{ParityShards: 3}},
+		{"negative block size", backends, Config{BlockSize: -1}},
+		{"quorum below data shards", backends, Config{WriteQuorum: 1}},
+		{"quorum above backends", backends, Config{WriteQuorum: 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.backends, &tt.cfg); !errors.Is(err, ErrInvalidConfig) {
+				t.Errorf("New() error = %v, want ErrInvalidConfig", err)
+			}
+		})
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	s, _ := newTestStorage(t, 5, nil, nil)
+	if err := s.Configure(map[string]string{"parityShards": "2", "blockSize": "1024"}); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+	want := Config{DataShards: 3, ParityShards: 2, BlockSize: 1024, WriteQuorum: 4}
+	if got := s.Config(); got != want {
+		t.Errorf("Config() = %+v, want %+v", got, want)
+	}
+	if err := s.Configure(map[string]string{"writeQuorum": "5"}); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+	if got := s.Config(); got.ParityShards != 2 || got.WriteQuorum != 5 {
+		t.Errorf("Config() = %+v, want earlier settings kept", got)
+	}
+
+	for _, settings := range []map[string]string{
+		{"dataShards": "three"},
+		{"dataShards": "4"},
+	} {
+		if err := s.Configure(settings); !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("Configure(%v) error = %v, want ErrInvalidConfig", settings, err)
+		}
+	}
+	if got := s.Config(); got.WriteQuorum != 5 {
+		t.Errorf("failed Configure changed the configuration to %+v", got)
+	}
+}
+
+func TestPutGetRoundTrip(t *testing.T) {
+	s, _ := newTestStorage(t, 5, &Config{ParityShards: 2, BlockSize: 64}, nil)
+	for _, size := range []int{0, 1, 2, 100, 192, 193, 1000, 10000} {
+		data := randomBytes(size)
+		if err := s.Put("obj", bytes.NewReader(data)); err != nil {
+			t.Fatalf("Put(%d bytes) error = %v", size, err)
+		}
+		if got := read(t, s, "obj"); !bytes.Equal(got, data) {
+			t.Fatalf("%d bytes: got %d bytes back, content differs", size, len(got))
+		}
+	}
+}
+
+func TestShardsAreSmallerThanObject(t *testing.T) {
+	s, injectors := newTestStorage(t, 6, &Config{ParityShards: 2}, nil)
+	data := randomBytes(4000)
+	if err := s.Put("obj", bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	meta, err := injectors[0].GetMetadata(context.Background(), "obj")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := int64(headerSize + 1000); meta.Size != want {
+		t.Errorf("shard size = %d, want %d", meta.Size, want)
+	}
+}
+
+func TestMetadata(t *testing.T) {
+	ctx := context.Background()
+	s, injectors := newTestStorage(t, 3, nil, nil)
+	data := []byte(`{"hello":"world"}`)
+	err := s.PutWithMetadata(ctx, "doc.json", bytes.NewReader(data), &common.Metadata{
+		ContentType:     "application/json",
+		ContentEncoding: "identity",
+		Custom:          map[string]string{"owner": "alice"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sum := md5.Sum(data) // #nosec G401 -- see import
+	meta, err := s.GetMetadata(ctx, "doc.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Size != int64(len(data)) || meta.ETag != hex.EncodeToString(sum[:]) {
+		t.Errorf("Size, ETag = %d, %s; want %d, %x", meta.Size, meta.ETag, len(data), sum)
+	}
+	if meta.ContentType != "application/json" || meta.ContentEncoding != "identity" {
+		t.Errorf("ContentType, ContentEncoding = %q, %q", meta.ContentType, meta.ContentEncoding)
+	}
+	if len(meta.Custom) != 1 || meta.Custom["owner"] != "alice" {
+		t.Errorf("Custom = %v, want only owner", meta.Custom)
+	}
+
+	shard, err := injectors[1].GetMetadata(ctx, "doc.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if shard.ContentType != "application/octet-stream" || shard.ContentEncoding != "" {
+		t.Errorf("shard ContentType, ContentEncoding = %q, %q", shard.ContentType, shard.ContentEncoding)
+	}
+
+	err = s.UpdateMetadata(ctx, "doc.json", &common.Metadata{ContentType: "text/plain", Custom: map[string]string{"owner": "bob"}})
+	if err != nil {
+		t.Fatalf("UpdateMetadata() error = %v", err)
+	}
+	meta, err = s.GetMetadata(ctx, "doc.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.ContentType != "text/plain" || meta.Custom["owner"] != "bob" || meta.Size != int64(len(data)) {
+		t.Errorf("after update: %+v", meta)
+	}
+	if got := read(t, s, "doc.json"); !bytes.Equal(got, data) {
+		t.Errorf("content changed by UpdateMetadata: %q", got)
+	}
+}
+
+func TestGetReconstructsMissingShards(t *testing.T) {
+	s, injectors := newTestStorage(t, 5, &Config{ParityShards: 2, BlockSize: 100}, down)
+	data := randomBytes(2500)
+	if err := s.Put("obj", bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, lost := range [][]int{{0}, {4}, {1, 3}, {0, 1}, {3, 4}} {
+		for _, i := range lost {
+			injectors[i].SetEnabled(true)
+		}
+		if got := read(t, s, "obj"); !bytes.Equal(got, data) {
+			t.Errorf("lost %v: content differs", lost)
+		}
+		for _, i := range lost {
+			injectors[i].SetEnabled(false)
+		}
+	}
+
+	for _, i := range []int{0, 2, 4} {
+		injectors[i].SetEnabled(true)
+	}
+	if _, err := s.Get("obj"); !errors.Is(err, ErrNotEnoughShards) {
+		t.Errorf("Get() with 3 of 5 shards lost: error = %v, want ErrNotEnoughShards", err)
+	}
+	if _, err := s.Exists(context.Background(), "obj"); !errors.Is(err, ErrNotEnoughShards) {
+		t.Errorf("Exists() with 3 of 5 shards lost: error = %v, want ErrNotEnoughShards", err)
+	}
+}
+
+func TestGetSwitchesShardsMidStream(t *testing.T) {
+	s, injectors := newTestStorage(t, 4, &Config{ParityShards: 2, BlockSize: 50}, flaky)
+	data := randomBytes(1000)
+	if err := s.Put("obj", bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	injectors[0].SetEnabled(true)
+	if got := read(t, s, "obj"); !bytes.Equal(got, data) {
+		t.Error("content differs after a shard failed mid-stream")
+	}
+	injectors[3].SetEnabled(true)
+	if got := read(t, s, "obj"); !bytes.Equal(got, data) {
+		t.Error("content differs after two shards failed mid-stream")
+	}
+
+	// Depending on where the reads break off, Get or reading fails.
+	injectors[1].SetEnabled(true)
+	rc, err := s.Get("obj")
+	if err == nil {
+		_, err = io.ReadAll(rc)
+		rc.Close()
+	}
+	if !errors.Is(err, ErrNotEnoughShards) {
+		t.Errorf("reading with 3 of 4 shards failing: error = %v, want ErrNotEnoughShards", err)
+	}
+}
+
+func TestWriteQuorum(t *testing.T) {
+	s, injectors := newTestStorage(t, 5, &Config{ParityShards: 2}, down)
+	injectors[0].SetEnabled(true)
+	if err := s.Put("obj", strings.NewReader("one down")); err != nil {
+		t.Fatalf("Put() with one backend down: %v", err)
+	}
+	injectors[1].SetEnabled(true)
+	if err := s.Put("obj", strings.NewReader("two down")); !errors.Is(err, ErrNotEnoughShards) {
+		t.Fatalf("Put() with two backends down: error = %v, want ErrNotEnoughShards", err)
+	}
+}
+
+func TestStaleShardsAndHeal(t *testing.T) {
+	ctx := context.Background()
+	s, injectors := newTestStorage(t, 4, &Config{ParityShards: 2}, down)
+	if err := s.Put("obj", strings.NewReader("first version")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Backend 0 misses the second write and keeps a shard of the first.
+	injectors[0].SetEnabled(true)
+	if err := s.Put("obj", strings.NewReader("second version")); err != nil {
+		t.Fatal(err)
+	}
+	injectors[0].SetEnabled(false)
+	if got := read(t, s, "obj"); string(got) != "second version" {
+		t.Errorf("got %q, want the second version", got)
+	}
+
+	healed, err := s.Heal(ctx, "obj")
+	if err != nil || healed != 1 {
+		t.Fatalf("Heal() = %d, %v; want 1 shard", healed, err)
+	}
+	if healed, err := s.Heal(ctx, "obj"); err != nil || healed != 0 {
+		t.Errorf("second Heal() = %d, %v; want nothing to do", healed, err)
+	}
+
+	// The healed shard now stands in for two lost ones.
+	injectors[1].SetEnabled(true)
+	injectors[2].SetEnabled(true)
+	if got := read(t, s, "obj"); string(got) != "second version" {
+		t.Errorf("got %q from healed shard, want the second version", got)
+	}
+}
+
+func TestDeleteAndExists(t *testing.T) {
+	ctx := context.Background()
+	s, injectors := newTestStorage(t, 3, nil, down)
+	if err := s.Put("obj", strings.NewReader("data")); err != nil {
+		t.Fatal(err)
+	}
+
+	injectors[2].SetEnabled(true)
+	if err := s.Delete("obj"); err != nil {
+		t.Fatalf("Delete() with one backend down: %v", err)
+	}
+	injectors[2].SetEnabled(false)
+	if ok, err := s.Exists(ctx, "obj"); ok || err != nil {
+		t.Errorf("Exists() with one leftover shard = %v, %v; want false", ok, err)
+	}
+	if keys, err := s.List(""); err != nil || len(keys) != 0 {
+		t.Errorf("List() = %v, %v; leftover shard listed", keys, err)
+	}
+	if err := s.Delete("missing"); !errors.Is(err, common.ErrKeyNotFound) {
+		t.Errorf("Delete(missing) error = %v, want ErrKeyNotFound", err)
+	}
+
+	if err := s.Put("obj", strings.NewReader("data")); err != nil {
+		t.Fatal(err)
+	}
+	injectors[0].SetEnabled(true)
+	injectors[1].SetEnabled(true)
+	if err := s.Delete("obj"); err == nil {
+		t.Error("Delete() with two backends down succeeded, but the object may still be readable")
+	}
+}
+
+func TestListWithOptions(t *testing.T) {
+	ctx := context.Background()
+	s, injectors := newTestStorage(t, 3, nil, down)
+	for _, key := range []string{"a/1", "a/2", "b/1", "c"} {
+		if err := s.Put(key, strings.NewReader(key+" content")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	injectors[1].SetEnabled(true)
+
+	res, err := s.ListWithOptions(ctx, &common.ListOptions{Delimiter: "/", MaxResults: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(res.CommonPrefixes, ",") != "a/,b/" || len(res.Objects) != 0 || !res.Truncated {
+		t.Fatalf("first page = %+v", res)
+	}
+	res, err = s.ListWithOptions(ctx, &common.ListOptions{Delimiter: "/", MaxResults: 2, ContinueFrom: res.NextToken})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Objects) != 1 || res.Objects[0].Key != "c" || res.Truncated {
+		t.Fatalf("second page = %+v", res)
+	}
+	if size := res.Objects[0].Metadata.Size; size != int64(len("c content")) {
+		t.Errorf("listed size = %d, want the object size", size)
+	}
+
+	injectors[0].SetEnabled(true)
+	if _, err := s.ListWithOptions(ctx, nil); !errors.Is(err, ErrNotEnoughShards) {
+		t.Errorf("ListWithOptions() with 2 of 3 backends down: error = %v, want ErrNotEnoughShards", err)
+	}
+}
+
+func TestArchive(t *testing.T) {
+	s, _ := newTestStorage(t, 3, nil, nil)
+	if err := s.Put("obj", strings.NewReader("archived")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Archive("obj", nil); !errors.Is(err, common.ErrArchiveDestinationNil) {
+		t.Errorf("Archive(nil) error = %v", err)
+	}
+	dest := memory.New()
+	if err := s.Archive("obj", dest); err != nil {
+		t.Fatal(err)
+	}
+	if got := read(t, dest, "obj"); string(got) != "archived" {
+		t.Errorf("archived %q", got)
+	}
+}
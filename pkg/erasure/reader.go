@@ -0,0 +1,184 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package erasure
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// reader decodes an object stripe by stripe. It reads DataShards shards at
+// a time, preferring data shards, and replaces a shard that fails with one
+// that has not been tried yet, positioned at the current stripe.
+type reader struct {
+	ctx   context.Context
+	s     *Storage
+	key   string
+	loc   *location
+	coder *coder
+
+	shards  []io.ReadCloser // open shards by index
+	tried   []bool          // shards that were opened or are unavailable
+	blocks  [][]byte        // the current stripe's blocks by shard index
+	present []bool          // blocks read for the current stripe
+	stripe  []byte          // backing array of the data blocks
+	offset  int64           // offset of the current stripe within a shard
+	left    int64           // object bytes not decoded yet
+	buf     []byte          // decoded bytes not returned yet
+	lastErr error           // why the last shard was given up on
+	err     error
+}
+
+// newReader opens DataShards shards of the located write.
+func (s *Storage) newReader(ctx context.Context, key string, loc *location, c *coder) (*reader, error) {
+	total := loc.data + loc.parity
+	r := &reader{
+		ctx:     ctx,
+		s:       s,
+		key:     key,
+		loc:     loc,
+		coder:   c,
+		shards:  make([]io.ReadCloser, total),
+		tried:   make([]bool, total),
+		blocks:  make([][]byte, total),
+		present: make([]bool, total),
+		left:    loc.size,
+	}
+	for i := range r.tried {
+		r.tried[i] = !loc.has(i)
+	}
+	if loc.size > 0 {
+		r.stripe = make([]byte, loc.data*loc.block)
+		for i := range r.blocks {
+			if i < loc.data {
+				r.blocks[i] = r.stripe[i*loc.block : (i+1)*loc.block]
+			} else {
+				r.blocks[i] = make([]byte, loc.block)
+			}
+		}
+	}
+	if err := r.fill(); err != nil {
+		_ = r.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+// Read implements io.Reader.
+func (r *reader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		if r.left == 0 {
+			return 0, io.EOF
+		}
+		if r.err = r.next(); r.err != nil {
+			return 0, r.err
+		}
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// Close closes the open shards.
+func (r *reader) Close() error {
+	var first error
+	for i, rc := range r.shards {
+		if rc == nil {
+			continue
+		}
+		if err := rc.Close(); err != nil && first == nil {
+			first = err
+		}
+		r.shards[i] = nil
+	}
+	return first
+}
+
+// open reports how many shards are open.
+func (r *reader) open() int {
+	n := 0
+	for _, rc := range r.shards {
+		if rc != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// fill opens untried shards, lowest index first, until DataShards are
+// open.
+func (r *reader) fill() error {
+	for i := 0; r.open() < r.loc.data; i++ {
+		if i == len(r.shards) {
+			err := fmt.Errorf("%w: fewer than %d shards of %s are readable", ErrNotEnoughShards, r.loc.data, r.key)
+			if r.lastErr != nil {
+				err = fmt.Errorf("%w: %w", err, r.lastErr)
+			}
+			return err
+		}
+		if r.tried[i] {
+			continue
+		}
+		r.tried[i] = true
+		rc, err := r.s.openShard(r.ctx, r.key, i, r.loc.object, r.offset)
+		if err != nil {
+			r.lastErr = fmt.Errorf("shard %d: %w", i, err)
+			continue
+		}
+		r.shards[i] = rc
+	}
+	return nil
+}
+
+// next reads and decodes the next stripe into buf.
+func (r *reader) next() error {
+	if err := r.ctx.Err(); err != nil {
+		return err
+	}
+	clear(r.present)
+	for {
+		if err := r.fill(); err != nil {
+			return err
+		}
+		done := true
+		for i, rc := range r.shards {
+			if rc == nil || r.present[i] {
+				continue
+			}
+			if _, err := io.ReadFull(rc, r.blocks[i]); err != nil {
+				_ = rc.Close()
+				r.shards[i] = nil
+				r.lastErr = fmt.Errorf("shard %d: %w", i, err)
+				done = false
+				continue
+			}
+			r.present[i] = true
+		}
+		if done {
+			break
+		}
+	}
+	if err := r.coder.reconstruct(r.blocks, r.present); err != nil {
+		return err
+	}
+	n := min(int64(len(r.stripe)), r.left)
+	r.buf = r.stripe[:n]
+	r.left -= n
+	r.offset += int64(r.loc.block)
+	return nil
+}
@@ -0,0 +1,231 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package erasure
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Reed-Solomon coding over GF(2^8) with the primitive polynomial
+// x^8 + x^4 + x^3 + x^2 + 1 (0x11d). The code is systematic: the first
+// data shards of a stripe are the stripe's bytes unchanged and the parity
+// shards are linear combinations of them, so reads that find every data
+// shard never decode anything.
+
+// MaxShards is the largest total number of shards a code can have. Every
+// shard needs a distinct element of GF(2^8) in the encoding matrix.
+const MaxShards = 256
+
+var (
+	gfExp [510]byte
+	gfLog [256]byte
+	gfMul [256][256]byte
+)
+
+func init() {
+	x := 1
+	for i := 0; i < 255; i++ {
+		gfExp[i] = byte(x)
+		gfExp[i+255] = byte(x)
+		gfLog[x] = byte(i)
+		x <<= 1
+		if x&0x100 != 0 {
+			x ^= 0x11d
+		}
+	}
+	for a := 1; a < 256; a++ {
+		for b := 1; b < 256; b++ {
+			gfMul[a][b] = gfExp[int(gfLog[a])+int(gfLog[b])]
+		}
+	}
+}
+
+// gfInv returns the multiplicative inverse of a, which must not be zero.
+func gfInv(a byte) byte {
+	return gfExp[255-int(gfLog[a])]
+}
+
+// gfPow returns a raised to the power n.
+func gfPow(a byte, n int) byte {
+	if n == 0 {
+		return 1
+	}
+	if a == 0 {
+		return 0
+	}
+	return gfExp[(int(gfLog[a])*n)%255]
+}
+
+// matrix is a row-major matrix over GF(2^8).
+type matrix [][]byte
+
+func newMatrix(rows, cols int) matrix {
+	m := make(matrix, rows)
+	for r := range m {
+		m[r] = make([]byte, cols)
+	}
+	return m
+}
+
+// multiply returns m × o.
+func (m matrix) multiply(o matrix) matrix {
+	out := newMatrix(len(m), len(o[0]))
+	for r := range m {
+		for c := range o[0] {
+			var v byte
+			for i := range o {
+				v ^= gfMul[m[r][i]][o[i][c]]
+			}
+			out[r][c] = v
+		}
+	}
+	return out
+}
+
+// invert returns the inverse of the square matrix m by Gauss-Jordan
+// elimination.
+func (m matrix) invert() (matrix, error) {
+	n := len(m)
+	work := newMatrix(n, 2*n)
+	for r := range m {
+		copy(work[r], m[r])
+		work[r][n+r] = 1
+	}
+	for c := 0; c < n; c++ {
+		pivot := c
+		for pivot < n && work[pivot][c] == 0 {
+			pivot++
+		}
+		if pivot == n {
+			return nil, errors.New("singular matrix")
+		}
+		work[c], work[pivot] = work[pivot], work[c]
+		if scale := gfInv(work[c][c]); scale != 1 {
+			for i := range work[c] {
+				work[c][i] = gfMul[scale][work[c][i]]
+			}
+		}
+		for r := 0; r < n; r++ {
+			if r == c || work[r][c] == 0 {
+				continue
+			}
+			f := work[r][c]
+			for i := range work[r] {
+				work[r][i] ^= gfMul[f][work[c][i]]
+			}
+		}
+	}
+	out := make(matrix, n)
+	for r := range work {
+		out[r] = work[r][n:]
+	}
+	return out, nil
+}
+
+// coder encodes and reconstructs stripes of data+parity equally sized
+// shards.
+type coder struct {
+	data   int
+	parity int
+
+	// rows is the (data+parity) × data encoding matrix. Its top square is
+	// the identity and any data of its rows are linearly independent.
+	rows matrix
+}
+
+// newCoder builds the code for data and parity shards. The encoding
+// matrix is a Vandermonde matrix multiplied by the inverse of its top
+// square, which keeps every square submatrix invertible while making the
+// code systematic.
+func newCoder(data, parity int) (*coder, error) {
+	if data < 1 || parity < 0 || data+parity > MaxShards {
+		return nil, fmt.Errorf("%w: %d data and %d parity shards", ErrInvalidConfig, data, parity)
+	}
+	total := data + parity
+	vandermonde := newMatrix(total, data)
+	for r := range vandermonde {
+		for c := range vandermonde[r] {
+			vandermonde[r][c] = gfPow(byte(r), c)
+		}
+	}
+	top, err := vandermonde[:data].invert()
+	if err != nil {
+		return nil, err
+	}
+	return &coder{data: data, parity: parity, rows: vandermonde.multiply(top)}, nil
+}
+
+// encode computes the parity shards of a stripe from its data shards. All
+// shards must be allocated and have the same length.
+func (c *coder) encode(shards [][]byte) {
+	for p := c.data; p < c.data+c.parity; p++ {
+		c.combine(shards[p], c.rows[p], shards[:c.data])
+	}
+}
+
+// reconstruct recomputes the data shards of a stripe that are not marked
+// present from any data of the shards that are. Missing shards must still
+// be allocated; parity shards are left as they are.
+func (c *coder) reconstruct(shards [][]byte, present []bool) error {
+	var missing []int
+	for i := 0; i < c.data; i++ {
+		if !present[i] {
+			missing = append(missing, i)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	used := make([]int, 0, c.data)
+	for i := range shards {
+		if present[i] && len(used) < c.data {
+			used = append(used, i)
+		}
+	}
+	if len(used) < c.data {
+		return fmt.Errorf("%w: %d of %d needed", ErrNotEnoughShards, len(used), c.data)
+	}
+
+	sub := make(matrix, c.data)
+	inputs := make([][]byte, c.data)
+	for r, i := range used {
+		sub[r] = c.rows[i]
+		inputs[r] = shards[i]
+	}
+	decode, err := sub.invert()
+	if err != nil {
+		return err
+	}
+	for _, i := range missing {
+		c.combine(shards[i], decode[i], inputs)
+	}
+	return nil
+}
+
+// combine sets out to the linear combination of inputs with coefficients.
+func (c *coder) combine(out, coefficients []byte, inputs [][]byte) {
+	clear(out)
+	for j, in := range inputs {
+		f := coefficients[j]
+		if f == 0 {
+			continue
+		}
+		table := &gfMul[f]
+		for i, b := range in {
+			out[i] ^= table[b]
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package erasure
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+func TestCoderReconstructsAnyLostShards(t *testing.T) {
+	const data, parity, size = 4, 2, 100
+	c, err := newCoder(data, parity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rng := rand.New(rand.NewSource(1))
+	shards := make([][]byte, data+parity)
+	for i := range shards {
+		shards[i] = make([]byte, size)
+		if i < data {
+			rng.Read(shards[i])
+		}
+	}
+	c.encode(shards)
+	want := make([][]byte, len(shards))
+	for i := range shards {
+		want[i] = bytes.Clone(shards[i])
+	}
+
+	for a := 0; a < len(shards); a++ {
+		for b := a + 1; b < len(shards); b++ {
+			present := make([]bool, len(shards))
+			for i := range present {
+				present[i] = i != a && i != b
+			}
+			got := make([][]byte, len(shards))
+			for i := range got {
+				got[i] = bytes.Clone(want[i])
+				if !present[i] {
+					clear(got[i])
+				}
+			}
+			if err := c.reconstruct(got, present); err != nil {
+				t.Fatalf("lost %d,%d: %v", a, b, err)
+			}
+			for i := 0; i < data; i++ {
+				if !bytes.Equal(got[i], want[i]) {
+					t.Fatalf("lost %d,%d: data shard %d not reconstructed", a, b, i)
+				}
+			}
+		}
+	}
+}
+
+func TestCoderTooFewShards(t *testing.T) {
+	c, err := newCoder(3, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shards := [][]byte{make([]byte, 4), make([]byte, 4), make([]byte, 4), make([]byte, 4)}
+	if err := c.reconstruct(shards, []bool{false, false, true, true}); !errors.Is(err, ErrNotEnoughShards) {
+		t.Fatalf("reconstruct() error = %v, want ErrNotEnoughShards", err)
+	}
+}
+
+func TestNewCoderLimits(t *testing.T) {
+	for _, tt := range []struct{ data, parity int }{{0, 1}, {1, -1}, {200, 57}} {
+		if _, err := newCoder(tt.data, tt.parity); !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("newCoder(%d, %d) error = %v, want ErrInvalidConfig", tt.data, tt.parity, err)
+		}
+	}
+	if _, err := newCoder(200, 56); err != nil {
+		t.Errorf("newCoder(200, 56) error = %v", err)
+	}
+}
@@ -0,0 +1,260 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package events publishes object change notifications to message sinks.
+// Notifications use the Amazon S3 event notification format, so consumers
+// written for S3 events can be pointed at objstore unchanged. A Notifier
+// matches each change against rules (event name patterns, key prefix and
+// suffix) and delivers it asynchronously, with retries, to the rule's sink.
+//
+// Besides object changes, the Notifier publishes operational events
+// (lifecycle policy failures, replication lag, quota refusals, scrub
+// findings) for operators. The Slack, Microsoft Teams and SMTP sinks turn
+// notifications into chat messages and email for them.
+//
+// Sinks are created by type from string settings. The Slack, Teams and SMTP
+// sinks are always built, the SNS, SQS and EventBridge sinks with the awss3
+// tag and the Google Pub/Sub sink with the gcpstorage tag; applications can
+// register their own with RegisterSink.
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/audit"
+)
+
+// Event names published by the Storage wrapper, the scrubber and the
+// facade's background work. Rules may also use "ObjectCreated:*",
+// "ObjectRemoved:*", "ObjectIntegrity:*", "Policy:*", "Replication:*",
+// "Quota:*" or "*", with or without the "s3:" prefix.
+const (
+	// EventObjectCreatedPut is published for puts and appends.
+	EventObjectCreatedPut = "ObjectCreated:Put"
+
+	// EventObjectCreatedCopy is published for metadata updates, which S3
+	// performs by copying an object onto itself.
+	EventObjectCreatedCopy = "ObjectCreated:Copy"
+
+	// EventObjectCreatedCompleteMultipartUpload is published when an object
+	// is composed from others.
+	EventObjectCreatedCompleteMultipartUpload = "ObjectCreated:CompleteMultipartUpload"
+
+	// EventObjectRemovedDelete is published for deletes.
+	EventObjectRemovedDelete = "ObjectRemoved:Delete"
+
+	// EventObjectIntegrityCorrupted is published when scrubbing finds an
+	// object whose content does not match its checksum and cannot repair
+	// it, and EventObjectIntegrityRepaired when the object was restored
+	// from a replica. They are objstore extensions; S3 has no such events.
+	EventObjectIntegrityCorrupted = "ObjectIntegrity:Corrupted"
+	EventObjectIntegrityRepaired  = "ObjectIntegrity:Repaired"
+
+	// EventPolicyFailed is published when a lifecycle policy cannot be
+	// applied to an object, or to the backend when listing it fails.
+	EventPolicyFailed = "Policy:Failed"
+
+	// EventReplicationLagExceeded is published for a replication policy
+	// whose last successful sync is older than the alert threshold.
+	EventReplicationLagExceeded = "Replication:LagExceeded"
+
+	// EventQuotaExceeded is published when a write is refused because a
+	// quota or rate limit is exhausted.
+	EventQuotaExceeded = "Quota:Exceeded"
+)
+
+// Detail names of operational events.
+const (
+	DetailError     = "error"
+	DetailPolicy    = "policy"
+	DetailLag       = "lag"
+	DetailLastSync  = "lastSync"
+	DetailThreshold = "threshold"
+)
+
+const (
+	eventVersion    = "2.1"
+	eventSource     = "aws:s3"
+	s3SchemaVersion = "1.0"
+	eventTimeFormat = "2006-01-02T15:04:05.000Z"
+)
+
+var (
+	// ErrInvalidConfig is returned for malformed notification configuration.
+	ErrInvalidConfig = errors.New("invalid notification configuration")
+
+	// ErrUnknownSink is returned when a rule names a sink type that is not
+	// registered, such as an AWS sink in a build without the awss3 tag.
+	ErrUnknownSink = errors.New("unknown notification sink type")
+
+	// ErrQueueFull is reported through Config.OnError when a notification
+	// is dropped because deliveries are not keeping up.
+	ErrQueueFull = errors.New("notification queue full")
+
+	// ErrClosed is reported through Config.OnError for notifications raised
+	// after the Notifier was closed.
+	ErrClosed = errors.New("notifier closed")
+
+	// ErrRuleRemoved is returned when redelivering a queued notification
+	// whose rule is no longer configured.
+	ErrRuleRemoved = errors.New("notification rule no longer configured")
+)
+
+// Notification is the message body delivered to sinks: the Amazon S3 event
+// notification document.
+type Notification struct {
+	Records []Record `json:"Records"`
+}
+
+// Record describes one object change.
+type Record struct {
+	EventVersion      string            `json:"eventVersion"`
+	EventSource       string            `json:"eventSource"`
+	AWSRegion         string            `json:"awsRegion"`
+	EventTime         string            `json:"eventTime"`
+	EventName         string            `json:"eventName"`
+	UserIdentity      Identity          `json:"userIdentity"`
+	RequestParameters RequestParameters `json:"requestParameters"`
+	ResponseElements  map[string]string `json:"responseElements"`
+	S3                Entity            `json:"s3"`
+
+	// Details describes operational events, such as the error of a failed
+	// lifecycle policy. It is an objstore extension of the S3 format.
+	Details map[string]string `json:"details,omitempty"`
+}
+
+// Identity identifies who made a change.
+type Identity struct {
+	PrincipalID string `json:"principalId"`
+}
+
+// RequestParameters describes the request that made a change.
+type RequestParameters struct {
+	SourceIPAddress string `json:"sourceIPAddress"`
+}
+
+// Entity holds the bucket and object a record is about.
+type Entity struct {
+	SchemaVersion   string `json:"s3SchemaVersion"`
+	ConfigurationID string `json:"configurationId"`
+	Bucket          Bucket `json:"bucket"`
+	Object          Object `json:"object"`
+}
+
+// Bucket identifies the backend a change was made on.
+type Bucket struct {
+	Name          string   `json:"name"`
+	OwnerIdentity Identity `json:"ownerIdentity"`
+	ARN           string   `json:"arn"`
+}
+
+// Object describes the changed object. Key is URL-encoded, as in S3
+// notifications; Size and ETag are omitted for removals.
+type Object struct {
+	Key       string `json:"key"`
+	Size      int64  `json:"size,omitempty"`
+	ETag      string `json:"eTag,omitempty"`
+	Sequencer string `json:"sequencer"`
+}
+
+// DecodedKey returns the object key with its URL encoding removed.
+func (o Object) DecodedKey() string {
+	key, err := url.QueryUnescape(o.Key)
+	if err != nil {
+		return o.Key
+	}
+	return key
+}
+
+// newRecord returns a record for a change to key, attributed to the
+// principal and request in ctx.
+func newRecord(ctx context.Context, bucket, region, eventName, key string, size int64, etag, sequencer string, at time.Time) Record {
+	principal := "anonymous"
+	if p, ok := ctx.Value(adapters.PrincipalContextKey{}).(*adapters.Principal); ok && p != nil && p.ID != "" {
+		principal = p.ID
+	}
+	response := map[string]string{}
+	if id := audit.GetRequestID(ctx); id != "" {
+		response["x-amz-request-id"] = id
+	}
+
+	return Record{
+		EventVersion:     eventVersion,
+		EventSource:      eventSource,
+		AWSRegion:        region,
+		EventTime:        at.UTC().Format(eventTimeFormat),
+		EventName:        eventName,
+		UserIdentity:     Identity{PrincipalID: principal},
+		ResponseElements: response,
+		S3: Entity{
+			SchemaVersion: s3SchemaVersion,
+			Bucket: Bucket{
+				Name: bucket,
+				ARN:  "arn:aws:s3:::" + bucket,
+			},
+			Object: Object{
+				Key:       encodeKey(key),
+				Size:      size,
+				ETag:      strings.Trim(etag, `"`),
+				Sequencer: sequencer,
+			},
+		},
+	}
+}
+
+// encodeKey URL-encodes key the way S3 notifications do, leaving slashes.
+func encodeKey(key string) string {
+	return strings.ReplaceAll(url.QueryEscape(key), "%2F", "/")
+}
+
+// matchEvent reports whether eventName matches pattern, such as
+// "s3:ObjectCreated:*".
+func matchEvent(pattern, eventName string) bool {
+	pattern = strings.TrimPrefix(pattern, "s3:")
+	if pattern == "*" || pattern == eventName {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(eventName, prefix)
+	}
+	return false
+}
+
+// validEventPattern reports whether pattern names a published event or a
+// wildcard over them.
+func validEventPattern(pattern string) bool {
+	switch strings.TrimPrefix(pattern, "s3:") {
+	case "*", "ObjectCreated:*", "ObjectRemoved:*", "ObjectIntegrity:*",
+		"Policy:*", "Replication:*", "Quota:*",
+		EventObjectCreatedPut, EventObjectCreatedCopy,
+		EventObjectCreatedCompleteMultipartUpload, EventObjectRemovedDelete,
+		EventObjectIntegrityCorrupted, EventObjectIntegrityRepaired,
+		EventPolicyFailed, EventReplicationLagExceeded, EventQuotaExceeded:
+		return true
+	}
+	return false
+}
+
+// describe names a rule in errors.
+func describe(id string, index int) string {
+	if id != "" {
+		return fmt.Sprintf("rule %q", id)
+	}
+	return fmt.Sprintf("rule %d", index)
+}
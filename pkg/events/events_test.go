@@ -0,0 +1,419 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+	"github.com/jeremyhahn/go-objstore/pkg/tasks"
+)
+
+// recordingSink collects the notifications published to it, failing the
+// first fail publishes.
+type recordingSink struct {
+	mu     sync.Mutex
+	got    []*Notification
+	fail   int
+	closed bool
+}
+
+func (s *recordingSink) Publish(_ context.Context, n *Notification) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fail > 0 {
+		s.fail--
+		return errors.New("unavailable")
+	}
+	s.got = append(s.got, n)
+	return nil
+}
+
+func (s *recordingSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+func (s *recordingSink) records() []Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var records []Record
+	for _, n := range s.got {
+		records = append(records, n.Records...)
+	}
+	return records
+}
+
+// newTestNotifier returns a notifier whose rules all deliver to one
+// recording sink.
+func newTestNotifier(t *testing.T, cfg *Config) (*Notifier, *recordingSink) {
+	t.Helper()
+	sink := &recordingSink{}
+	sinkType := "recording-" + t.Name()
+	RegisterSink(sinkType, func(map[string]string) (Sink, error) { return sink, nil })
+	for i := range cfg.Rules {
+		cfg.Rules[i].Sink.Type = sinkType
+	}
+	notifier, err := NewNotifier(cfg)
+	if err != nil {
+		t.Fatalf("NewNotifier: %v", err)
+	}
+	notifier.backoff = time.Millisecond
+	return notifier, sink
+}
+
+func closeNotifier(t *testing.T, n *Notifier) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := n.Close(ctx); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+}
+
+func TestStorageNotifications(t *testing.T) {
+	notifier, sink := newTestNotifier(t, &Config{
+		Bucket: "media",
+		Region: "us-east-1",
+		Rules: []Rule{
+			{ID: "images", Events: []string{"s3:ObjectCreated:*"}, Prefix: "images/", Suffix: ".jpg"},
+			{ID: "deletes", Events: []string{"ObjectRemoved:*"}},
+		},
+	})
+	storage := NewStorage(memory.New(), notifier)
+	ctx := context.WithValue(context.Background(), adapters.PrincipalContextKey{}, &adapters.Principal{ID: "alice"})
+
+	if err := storage.PutWithContext(ctx, "images/my cat.jpg", strings.NewReader("meow")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if err := storage.PutWithContext(ctx, "docs/readme.txt", strings.NewReader("skip")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if err := storage.UpdateMetadata(ctx, "images/my cat.jpg", &common.Metadata{ContentType: "image/jpeg"}); err != nil {
+		t.Fatalf("UpdateMetadata: %v", err)
+	}
+	if err := storage.DeleteWithContext(ctx, "docs/readme.txt"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := storage.DeleteWithContext(ctx, "missing"); err == nil {
+		t.Fatal("Delete of a missing key succeeded")
+	}
+	closeNotifier(t, notifier)
+
+	records := sink.records()
+	if len(records) != 3 {
+		t.Fatalf("got %d records, want 3: %+v", len(records), records)
+	}
+	put := records[0]
+	if put.EventName != EventObjectCreatedPut || put.EventSource != "aws:s3" || put.EventVersion != "2.1" {
+		t.Errorf("put record = %s from %s v%s", put.EventName, put.EventSource, put.EventVersion)
+	}
+	if put.S3.Object.Key != "images/my+cat.jpg" || put.S3.Object.DecodedKey() != "images/my cat.jpg" {
+		t.Errorf("key = %q", put.S3.Object.Key)
+	}
+	if put.S3.Object.Size != 4 || put.S3.Object.ETag == "" {
+		t.Errorf("object = %+v, want size 4 and an ETag", put.S3.Object)
+	}
+	if put.S3.Bucket.Name != "media" || put.S3.Bucket.ARN != "arn:aws:s3:::media" || put.AWSRegion != "us-east-1" {
+		t.Errorf("bucket = %+v in %q", put.S3.Bucket, put.AWSRegion)
+	}
+	if put.S3.ConfigurationID != "images" || put.UserIdentity.PrincipalID != "alice" {
+		t.Errorf("configuration %q by %q", put.S3.ConfigurationID, put.UserIdentity.PrincipalID)
+	}
+	if records[1].EventName != EventObjectCreatedCopy {
+		t.Errorf("metadata update = %s, want %s", records[1].EventName, EventObjectCreatedCopy)
+	}
+	if del := records[2]; del.EventName != EventObjectRemovedDelete || del.S3.Object.Size != 0 || del.S3.ConfigurationID != "deletes" {
+		t.Errorf("delete record = %+v", del)
+	}
+	if records[0].S3.Object.Sequencer >= records[1].S3.Object.Sequencer {
+		t.Errorf("sequencers %s, %s do not increase", records[0].S3.Object.Sequencer, records[1].S3.Object.Sequencer)
+	}
+	if !sink.closed {
+		t.Error("sink not closed")
+	}
+
+	// The document has the field names of S3 notifications.
+	data, err := json.Marshal(Notification{Records: records[:1]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range []string{`"Records"`, `"eventTime"`, `"s3SchemaVersion":"1.0"`, `"eTag"`, `"principalId":"alice"`} {
+		if !strings.Contains(string(data), field) {
+			t.Errorf("notification %s lacks %s", data, field)
+		}
+	}
+}
+
+func TestNotifierRetriesAndReportsFailures(t *testing.T) {
+	var mu sync.Mutex
+	var failures []error
+	notifier, sink := newTestNotifier(t, &Config{
+		MaxAttempts: 2,
+		Rules:       []Rule{{ID: "all"}},
+		OnError: func(_ string, _ *Record, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			failures = append(failures, err)
+		},
+	})
+
+	sink.fail = 1
+	notifier.Notify(context.Background(), EventObjectCreatedPut, "a", 1, "e")
+	closeNotifier(t, notifier)
+	if got := len(sink.records()); got != 1 {
+		t.Errorf("delivered %d records after one failure, want 1", got)
+	}
+
+	notifier.Notify(context.Background(), EventObjectCreatedPut, "b", 1, "e")
+	mu.Lock()
+	defer mu.Unlock()
+	if len(failures) != 1 || !errors.Is(failures[0], ErrClosed) {
+		t.Errorf("failures = %v, want [ErrClosed]", failures)
+	}
+}
+
+func TestNotifierDeliveryFailure(t *testing.T) {
+	failed := make(chan error, 1)
+	notifier, sink := newTestNotifier(t, &Config{
+		MaxAttempts: 2,
+		Rules:       []Rule{{ID: "all"}},
+		OnError:     func(_ string, _ *Record, err error) { failed <- err },
+	})
+	sink.fail = 2
+	notifier.Notify(context.Background(), EventObjectRemovedDelete, "a", 0, "")
+	closeNotifier(t, notifier)
+
+	select {
+	case err := <-failed:
+		if err == nil || err.Error() != "unavailable" {
+			t.Errorf("OnError = %v, want the sink's error", err)
+		}
+	default:
+		t.Error("OnError not called after the last attempt failed")
+	}
+}
+
+func TestNotifierRedeliversThroughTasks(t *testing.T) {
+	queue, err := tasks.New(&tasks.Config{Poll: 5 * time.Millisecond, Backoff: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = queue.Close() }()
+	failed := make(chan error, 1)
+	notifier, sink := newTestNotifier(t, &Config{
+		MaxAttempts: 1,
+		Rules:       []Rule{{ID: "all"}},
+		OnError:     func(_ string, _ *Record, err error) { failed <- err },
+		Tasks:       queue,
+	})
+	defer closeNotifier(t, notifier)
+	queue.Start()
+
+	// The first delivery and the first redelivery fail.
+	sink.mu.Lock()
+	sink.fail = 2
+	sink.mu.Unlock()
+	notifier.Notify(context.Background(), EventObjectCreatedPut, "a", 1, "e")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		list, err := queue.List(context.Background(), tasks.StateSucceeded)
+		if err == nil && len(list) == 1 {
+			if list[0].Type != TaskRedeliver || list[0].Attempts != 2 {
+				t.Errorf("task = %+v", list[0])
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("notification not redelivered")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if records := sink.records(); len(records) != 1 || records[0].S3.Object.Key != "a" {
+		t.Errorf("records = %+v", records)
+	}
+	select {
+	case err := <-failed:
+		t.Errorf("OnError called for a queued notification: %v", err)
+	default:
+	}
+}
+
+func TestNotifierSetRule(t *testing.T) {
+	var mu sync.Mutex
+	sinks := make(map[string]*recordingSink)
+	sinkType := "recording-" + t.Name()
+	RegisterSink(sinkType, func(settings map[string]string) (Sink, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		sink := &recordingSink{}
+		sinks[settings["name"]] = sink
+		return sink, nil
+	})
+	notifier, err := NewNotifier(&Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rule := func(name string) Rule {
+		return Rule{ID: "logs", Prefix: "logs/", Sink: SinkConfig{Type: sinkType, Settings: map[string]string{"name": name}}}
+	}
+	ctx := context.Background()
+
+	if err := notifier.SetRule(rule("first")); err != nil {
+		t.Fatalf("SetRule() error = %v", err)
+	}
+	notifier.Notify(ctx, EventObjectCreatedPut, "logs/a", 1, "e")
+	notifier.Notify(ctx, EventObjectCreatedPut, "other", 1, "e")
+	if err := notifier.SetRule(rule("second")); err != nil {
+		t.Fatalf("SetRule() replacing error = %v", err)
+	}
+	if got := notifier.Rules(); len(got) != 1 || got[0].Sink.Settings["name"] != "second" {
+		t.Errorf("Rules() = %+v", got)
+	}
+	notifier.Notify(ctx, EventObjectCreatedPut, "logs/b", 1, "e")
+	if err := notifier.RemoveRule("logs"); err != nil {
+		t.Fatalf("RemoveRule() error = %v", err)
+	}
+	notifier.Notify(ctx, EventObjectCreatedPut, "logs/c", 1, "e")
+
+	if err := notifier.RemoveRule("logs"); !errors.Is(err, common.ErrNotFound) {
+		t.Errorf("RemoveRule() of a removed rule error = %v, want ErrNotFound", err)
+	}
+	if err := notifier.SetRule(Rule{Sink: SinkConfig{Type: sinkType}}); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("SetRule() without id error = %v, want ErrInvalidConfig", err)
+	}
+	if err := notifier.SetRule(Rule{ID: "x", Sink: SinkConfig{Type: "missing"}}); !errors.Is(err, ErrUnknownSink) {
+		t.Errorf("SetRule() unknown sink error = %v, want ErrUnknownSink", err)
+	}
+
+	closeNotifier(t, notifier)
+	for name, key := range map[string]string{"first": "logs/a", "second": "logs/b"} {
+		sink := sinks[name]
+		if records := sink.records(); len(records) != 1 || records[0].S3.Object.Key != key {
+			t.Errorf("%s sink records = %+v, want %s", name, records, key)
+		}
+		if !sink.closed {
+			t.Errorf("%s sink not closed", name)
+		}
+	}
+	if err := notifier.SetRule(rule("third")); !errors.Is(err, ErrClosed) {
+		t.Errorf("SetRule() after Close error = %v, want ErrClosed", err)
+	}
+}
+
+func TestConfigValidation(t *testing.T) {
+	for name, cfg := range map[string]*Config{
+		"no sink":       {Rules: []Rule{{ID: "a"}}},
+		"bad event":     {Rules: []Rule{{ID: "a", Events: []string{"s3:ObjectRestore:*"}, Sink: SinkConfig{Type: "sns"}}}},
+		"duplicate id":  {Rules: []Rule{{ID: "a", Sink: SinkConfig{Type: "sns"}}, {ID: "a", Sink: SinkConfig{Type: "sns"}}}},
+		"negative size": {QueueSize: -1},
+	} {
+		if err := cfg.Validate(); !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("%s: Validate() = %v, want ErrInvalidConfig", name, err)
+		}
+	}
+
+	_, err := NewNotifier(&Config{Rules: []Rule{{ID: "a", Sink: SinkConfig{Type: "carrier-pigeon"}}}})
+	if !errors.Is(err, ErrUnknownSink) {
+		t.Errorf("NewNotifier with unknown sink = %v, want ErrUnknownSink", err)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notifications.yaml")
+	data := `bucket: media
+region: eu-west-1
+rules:
+  - id: thumbnails
+    events: ["s3:ObjectCreated:*"]
+    prefix: images/
+    sink:
+      type: sqs
+      settings:
+        queueUrl: https://sqs.eu-west-1.amazonaws.com/123456789012/thumbnails
+`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+	if cfg.Bucket != "media" || len(cfg.Rules) != 1 || cfg.Rules[0].Sink.Settings["queueUrl"] == "" {
+		t.Errorf("config = %+v", cfg)
+	}
+	if !cfg.Rules[0].matches(EventObjectCreatedPut, "images/a.png") || cfg.Rules[0].matches(EventObjectRemovedDelete, "images/a.png") {
+		t.Error("rule matches the wrong events")
+	}
+
+	if _, err := LoadFile(filepath.Join(t.TempDir(), "missing.yaml")); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("LoadFile(missing) = %v, want ErrInvalidConfig", err)
+	}
+}
+
+// fullStorage refuses every put as over quota.
+type fullStorage struct {
+	common.Storage
+}
+
+func (fullStorage) PutWithContext(context.Context, string, io.Reader) error {
+	return fmt.Errorf("%w: prefix is out of space", common.ErrResourceExhausted)
+}
+
+func TestOperationalEvents(t *testing.T) {
+	notifier, sink := newTestNotifier(t, &Config{
+		Bucket: "media",
+		Rules: []Rule{
+			{ID: "ops", Events: []string{"Policy:*", "Quota:*"}},
+		},
+	})
+	storage := NewStorage(fullStorage{memory.New()}, notifier)
+
+	err := storage.PutWithContext(context.Background(), "logs/app.log", strings.NewReader("data"))
+	if !errors.Is(err, common.ErrResourceExhausted) {
+		t.Fatalf("Put error = %v, want ErrResourceExhausted", err)
+	}
+	notifier.Alert(context.Background(), EventPolicyFailed, "old/report.csv", map[string]string{DetailError: "archive failed"})
+	notifier.Alert(context.Background(), EventReplicationLagExceeded, "", map[string]string{DetailPolicy: "dr"})
+	closeNotifier(t, notifier)
+
+	records := sink.records()
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want the quota and policy events: %+v", len(records), records)
+	}
+	quota := records[0]
+	if quota.EventName != EventQuotaExceeded || quota.S3.Object.Key != "logs/app.log" ||
+		!strings.Contains(quota.Details[DetailError], "out of space") {
+		t.Errorf("quota record = %+v", quota)
+	}
+	policy := records[1]
+	if policy.EventName != EventPolicyFailed || policy.Details[DetailError] != "archive failed" || policy.S3.ConfigurationID != "ops" {
+		t.Errorf("policy record = %+v", policy)
+	}
+}
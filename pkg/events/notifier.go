@@ -0,0 +1,481 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package events
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/tasks"
+	"gopkg.in/yaml.v3"
+)
+
+// Delivery defaults.
+const (
+	DefaultQueueSize   = 1024
+	DefaultMaxAttempts = 3
+	DefaultBucket      = "objstore"
+)
+
+// publishTimeout bounds one delivery attempt.
+const publishTimeout = 30 * time.Second
+
+// TaskRedeliver is the type of the tasks that retry failed deliveries when
+// Config.Tasks is set.
+const TaskRedeliver = "notification"
+
+// Sink delivers notifications to a message service.
+type Sink interface {
+	// Publish delivers one notification. It may be retried on error.
+	Publish(ctx context.Context, n *Notification) error
+
+	// Close releases the sink's resources.
+	Close() error
+}
+
+// SinkCreator creates a sink from its settings.
+type SinkCreator func(settings map[string]string) (Sink, error)
+
+var (
+	sinkRegistryMu sync.RWMutex
+	sinkRegistry   = make(map[string]SinkCreator)
+)
+
+// RegisterSink registers a sink type.
+func RegisterSink(sinkType string, creator SinkCreator) {
+	sinkRegistryMu.Lock()
+	defer sinkRegistryMu.Unlock()
+	sinkRegistry[sinkType] = creator
+}
+
+// NewSink creates a sink of a registered type.
+func NewSink(sinkType string, settings map[string]string) (Sink, error) {
+	sinkRegistryMu.RLock()
+	creator, ok := sinkRegistry[sinkType]
+	sinkRegistryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownSink, sinkType)
+	}
+	return creator(settings)
+}
+
+// SinkConfig selects a sink type and its settings, such as "topicArn" and
+// "region" for SNS.
+type SinkConfig struct {
+	Type     string            `yaml:"type" json:"type"`
+	Settings map[string]string `yaml:"settings,omitempty" json:"settings,omitempty"`
+}
+
+// Rule sends the changes it matches to a sink.
+type Rule struct {
+	// ID is reported as the configurationId of matching records.
+	ID string `yaml:"id" json:"id"`
+
+	// Events lists event name patterns such as "s3:ObjectCreated:*". Empty
+	// matches every event.
+	Events []string `yaml:"events,omitempty" json:"events,omitempty"`
+
+	// Prefix and Suffix restrict the rule to matching keys.
+	Prefix string `yaml:"prefix,omitempty" json:"prefix,omitempty"`
+	Suffix string `yaml:"suffix,omitempty" json:"suffix,omitempty"`
+
+	Sink SinkConfig `yaml:"sink" json:"sink"`
+}
+
+// matches reports whether the rule applies to an event on key.
+func (r *Rule) matches(eventName, key string) bool {
+	if !strings.HasPrefix(key, r.Prefix) || !strings.HasSuffix(key, r.Suffix) {
+		return false
+	}
+	if len(r.Events) == 0 {
+		return true
+	}
+	for _, pattern := range r.Events {
+		if matchEvent(pattern, eventName) {
+			return true
+		}
+	}
+	return false
+}
+
+// Config configures a Notifier.
+type Config struct {
+	// Bucket is reported as the bucket name of every record
+	// (DefaultBucket if empty).
+	Bucket string `yaml:"bucket,omitempty" json:"bucket,omitempty"`
+
+	// Region is reported as the awsRegion of every record.
+	Region string `yaml:"region,omitempty" json:"region,omitempty"`
+
+	// QueueSize bounds the notifications waiting for delivery
+	// (DefaultQueueSize if zero). Notifications beyond it are dropped.
+	QueueSize int `yaml:"queue_size,omitempty" json:"queue_size,omitempty"`
+
+	// MaxAttempts bounds deliveries of one notification
+	// (DefaultMaxAttempts if zero).
+	MaxAttempts int `yaml:"max_attempts,omitempty" json:"max_attempts,omitempty"`
+
+	Rules []Rule `yaml:"rules" json:"rules"`
+
+	// OnError is called with notifications that could not be delivered.
+	// It must not block.
+	OnError func(rule string, record *Record, err error) `yaml:"-" json:"-"`
+
+	// Tasks, if set, takes over notifications whose delivery attempts all
+	// failed, retrying them with backoff until they are delivered or moved
+	// to its dead-letter state. OnError is then only called for
+	// notifications that cannot be queued.
+	Tasks *tasks.Queue `yaml:"-" json:"-"`
+}
+
+// LoadFile reads a notification configuration from a YAML or JSON file.
+func LoadFile(filename string) (*Config, error) {
+	data, err := os.ReadFile(filename) // #nosec G304 -- path supplied by the operator
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidConfig, filename, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// Validate checks that every rule has a sink type and well-formed event
+// patterns, and that rule IDs are unique.
+func (c *Config) Validate() error {
+	if c.QueueSize < 0 || c.MaxAttempts < 0 {
+		return fmt.Errorf("%w: queue_size and max_attempts must not be negative", ErrInvalidConfig)
+	}
+	seen := make(map[string]bool, len(c.Rules))
+	for i, rule := range c.Rules {
+		name := describe(rule.ID, i)
+		if rule.ID != "" {
+			if seen[rule.ID] {
+				return fmt.Errorf("%w: duplicate %s", ErrInvalidConfig, name)
+			}
+			seen[rule.ID] = true
+		}
+		if rule.Sink.Type == "" {
+			return fmt.Errorf("%w: %s has no sink type", ErrInvalidConfig, name)
+		}
+		for _, pattern := range rule.Events {
+			if !validEventPattern(pattern) {
+				return fmt.Errorf("%w: %s: unknown event %q", ErrInvalidConfig, name, pattern)
+			}
+		}
+	}
+	return nil
+}
+
+// delivery is a notification waiting for its sink.
+type delivery struct {
+	rule   string
+	index  int
+	sink   Sink
+	record Record
+}
+
+// redelivery is the payload of a TaskRedeliver task.
+type redelivery struct {
+	Rule   string `json:"rule,omitempty"`
+	Index  int    `json:"index"`
+	Record Record `json:"record"`
+}
+
+// Notifier delivers object change notifications to the sinks of matching
+// rules. Deliveries run in the background in the order changes were made.
+type Notifier struct {
+	bucket string
+	region string
+
+	// rules and sinks are replaced, not modified, when rules change, and
+	// read under mu.
+	rules []Rule
+	sinks []Sink
+
+	// retired are the sinks of replaced and removed rules, closed with the
+	// notifier once the notifications queued for them are delivered.
+	retired []Sink
+
+	maxAttempts int
+	onError     func(string, *Record, error)
+	tasks       *tasks.Queue
+
+	queue     chan delivery
+	done      chan struct{}
+	mu        sync.RWMutex
+	closed    bool
+	closeOnce sync.Once
+	sequence  atomic.Uint64
+	backoff   time.Duration
+	now       func() time.Time
+}
+
+// NewNotifier creates the sinks of cfg's rules and starts delivering.
+func NewNotifier(cfg *Config) (*Notifier, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	n := &Notifier{
+		bucket:      cfg.Bucket,
+		region:      cfg.Region,
+		rules:       cfg.Rules,
+		maxAttempts: cfg.MaxAttempts,
+		onError:     cfg.OnError,
+		tasks:       cfg.Tasks,
+		done:        make(chan struct{}),
+		backoff:     100 * time.Millisecond,
+		now:         time.Now,
+	}
+	if n.bucket == "" {
+		n.bucket = DefaultBucket
+	}
+	if n.maxAttempts == 0 {
+		n.maxAttempts = DefaultMaxAttempts
+	}
+	queueSize := cfg.QueueSize
+	if queueSize == 0 {
+		queueSize = DefaultQueueSize
+	}
+
+	for i, rule := range cfg.Rules {
+		sink, err := NewSink(rule.Sink.Type, rule.Sink.Settings)
+		if err != nil {
+			for _, created := range n.sinks {
+				_ = created.Close()
+			}
+			return nil, fmt.Errorf("%s: %w", describe(rule.ID, i), err)
+		}
+		n.sinks = append(n.sinks, sink)
+	}
+
+	// Sequencers keep increasing across restarts, as consumers ordering
+	// changes to one key by sequencer expect.
+	n.sequence.Store(uint64(n.now().UnixNano())) // #nosec G115 -- the clock is past 1970
+
+	n.queue = make(chan delivery, queueSize)
+	if n.tasks != nil {
+		n.tasks.Handle(TaskRedeliver, n.redeliver)
+	}
+	go n.run()
+	return n, nil
+}
+
+// Rules returns the rules of n.
+func (n *Notifier) Rules() []Rule {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return slices.Clone(n.rules)
+}
+
+// SetRule adds a rule, or replaces the rule with the same ID, and creates
+// its sink. Notifications already queued go to the sink they were queued
+// for. Rules set at run time last until the notifier is created again,
+// such as when a server restarts and reads its configuration file.
+func (n *Notifier) SetRule(rule Rule) error {
+	if rule.ID == "" {
+		return fmt.Errorf("%w: rules set at run time need an id", ErrInvalidConfig)
+	}
+	if err := (&Config{Rules: []Rule{rule}}).Validate(); err != nil {
+		return err
+	}
+	sink, err := NewSink(rule.Sink.Type, rule.Sink.Settings)
+	if err != nil {
+		return fmt.Errorf("%s: %w", describe(rule.ID, 0), err)
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.closed {
+		_ = sink.Close()
+		return ErrClosed
+	}
+	rules, sinks := slices.Clone(n.rules), slices.Clone(n.sinks)
+	if i := slices.IndexFunc(rules, func(r Rule) bool { return r.ID == rule.ID }); i >= 0 {
+		n.retired = append(n.retired, sinks[i])
+		rules[i], sinks[i] = rule, sink
+	} else {
+		rules, sinks = append(rules, rule), append(sinks, sink)
+	}
+	n.rules, n.sinks = rules, sinks
+	return nil
+}
+
+// RemoveRule removes the rule with id. Notifications already queued for
+// it are still delivered.
+func (n *Notifier) RemoveRule(id string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	i := slices.IndexFunc(n.rules, func(r Rule) bool { return r.ID == id })
+	if id == "" || i < 0 {
+		return fmt.Errorf("%w: notification rule %q", common.ErrNotFound, id)
+	}
+	n.retired = append(n.retired, n.sinks[i])
+	n.rules = slices.Delete(slices.Clone(n.rules), i, i+1)
+	n.sinks = slices.Delete(slices.Clone(n.sinks), i, i+1)
+	return nil
+}
+
+// Notify queues a notification of eventName on key for every matching
+// rule. Size and etag describe the object after the change; they are
+// ignored for removals.
+func (n *Notifier) Notify(ctx context.Context, eventName, key string, size int64, etag string) {
+	n.notify(ctx, eventName, key, size, etag, nil)
+}
+
+// Alert queues an operational event, such as EventPolicyFailed, for every
+// matching rule. Key is the object the event is about, or empty when it is
+// about the backend; details describe it, keyed by the Detail names.
+func (n *Notifier) Alert(ctx context.Context, eventName, key string, details map[string]string) {
+	n.notify(ctx, eventName, key, 0, "", details)
+}
+
+func (n *Notifier) notify(ctx context.Context, eventName, key string, size int64, etag string, details map[string]string) {
+	n.mu.RLock()
+	rules, sinks := n.rules, n.sinks
+	n.mu.RUnlock()
+
+	var record *Record
+	for i := range rules {
+		rule := &rules[i]
+		if !rule.matches(eventName, key) {
+			continue
+		}
+		if record == nil {
+			if strings.HasPrefix(eventName, "ObjectRemoved:") {
+				size, etag = 0, ""
+			}
+			r := newRecord(ctx, n.bucket, n.region, eventName, key, size, etag,
+				fmt.Sprintf("%016X", n.sequence.Add(1)), n.now())
+			r.Details = details
+			record = &r
+		}
+		d := delivery{rule: rule.ID, index: i, sink: sinks[i], record: *record}
+		d.record.S3.ConfigurationID = rule.ID
+		n.enqueue(d)
+	}
+}
+
+func (n *Notifier) enqueue(d delivery) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	if n.closed {
+		n.fail(d, ErrClosed)
+		return
+	}
+	select {
+	case n.queue <- d:
+	default:
+		n.fail(d, ErrQueueFull)
+	}
+}
+
+// Close delivers the queued notifications, or gives up when ctx is done,
+// and closes the sinks. Later notifications are reported as ErrClosed.
+func (n *Notifier) Close(ctx context.Context) error {
+	var err error
+	n.closeOnce.Do(func() {
+		n.mu.Lock()
+		n.closed = true
+		close(n.queue)
+		n.mu.Unlock()
+
+		select {
+		case <-n.done:
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+		for _, sink := range slices.Concat(n.sinks, n.retired) {
+			if closeErr := sink.Close(); closeErr != nil && err == nil {
+				err = closeErr
+			}
+		}
+	})
+	return err
+}
+
+func (n *Notifier) run() {
+	defer close(n.done)
+	for d := range n.queue {
+		n.deliver(d)
+	}
+}
+
+// deliver publishes d, retrying with exponential backoff.
+func (n *Notifier) deliver(d delivery) {
+	notification := &Notification{Records: []Record{d.record}}
+	var err error
+	for attempt := 0; attempt < n.maxAttempts; attempt++ {
+		if attempt > 0 {
+			time.Sleep(n.backoff << (attempt - 1))
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+		err = d.sink.Publish(ctx, notification)
+		cancel()
+		if err == nil {
+			return
+		}
+	}
+	if n.tasks != nil {
+		payload := redelivery{Rule: d.rule, Index: d.index, Record: d.record}
+		if _, queueErr := n.tasks.Enqueue(context.Background(), TaskRedeliver, payload); queueErr == nil {
+			return
+		}
+	}
+	n.fail(d, err)
+}
+
+// redeliver retries a delivery queued as a task. Notifications of rules
+// removed since are dead-lettered; those of replaced rules go to the
+// rule's current sink.
+func (n *Notifier) redeliver(ctx context.Context, task *tasks.Task) error {
+	var payload redelivery
+	if err := task.Decode(&payload); err != nil {
+		return err
+	}
+
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	i := payload.Index
+	if payload.Rule != "" {
+		i = slices.IndexFunc(n.rules, func(r Rule) bool { return r.ID == payload.Rule })
+	}
+	if i < 0 || i >= len(n.rules) || n.rules[i].ID != payload.Rule {
+		return tasks.Permanent(fmt.Errorf("%w: %s", ErrRuleRemoved, describe(payload.Rule, payload.Index)))
+	}
+	if n.closed {
+		return ErrClosed
+	}
+	ctx, cancel := context.WithTimeout(ctx, publishTimeout)
+	defer cancel()
+	return n.sinks[i].Publish(ctx, &Notification{Records: []Record{payload.Record}})
+}
+
+func (n *Notifier) fail(d delivery, err error) {
+	if n.onError != nil {
+		n.onError(d.rule, &d.record, err)
+	}
+}
@@ -0,0 +1,292 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build awss3
+
+package events
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/transport"
+
+	"github.com/aws/aws-sdk-go/aws"                                  //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+	"github.com/aws/aws-sdk-go/aws/credentials"                      //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+	"github.com/aws/aws-sdk-go/aws/session"                          //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+	"github.com/aws/aws-sdk-go/service/eventbridge"                  //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+	"github.com/aws/aws-sdk-go/service/eventbridge/eventbridgeiface" //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+	"github.com/aws/aws-sdk-go/service/sns"                          //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+	"github.com/aws/aws-sdk-go/service/sns/snsiface"                 //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+	"github.com/aws/aws-sdk-go/service/sqs"                          //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"                 //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+)
+
+// snsSubject is the subject S3 gives the SNS messages it publishes.
+const snsSubject = "Amazon S3 Notification"
+
+// defaultEventBridgeSource is the source of EventBridge events. Sources
+// starting with "aws." are reserved for AWS services, so S3's own
+// "aws.s3" cannot be used.
+const defaultEventBridgeSource = "objstore.s3"
+
+func init() {
+	RegisterSink("sns", NewSNSSink)
+	RegisterSink("sqs", NewSQSSink)
+	RegisterSink("eventbridge", NewEventBridgeSink)
+}
+
+// awsSession creates a session from the region, endpoint, accessKey and
+// secretKey settings, as the S3 backend does.
+func awsSession(name string, settings map[string]string) (*session.Session, error) {
+	cfg := &aws.Config{Region: aws.String(settings["region"])}
+	if ep := settings["endpoint"]; ep != "" {
+		cfg.Endpoint = aws.String(ep)
+	}
+	if ak := settings["accessKey"]; ak != "" {
+		cfg.Credentials = credentials.NewStaticCredentials(ak, settings["secretKey"], "")
+	}
+	httpCfg, err := transport.ParseSettings(settings)
+	if err != nil {
+		return nil, err
+	}
+	identity := settings["accessKey"] + "|" + settings["secretKey"] + "|" + settings["region"] + "|" + settings["endpoint"]
+	cfg.HTTPClient = transport.Default.Client(name, identity, httpCfg)
+	return session.NewSession(cfg)
+}
+
+// SNSSink publishes notifications to an Amazon SNS topic, as S3 does. On
+// FIFO topics messages are grouped by object key.
+type SNSSink struct {
+	svc      snsiface.SNSAPI
+	topicARN string
+}
+
+// NewSNSSink creates an SNS sink from the topicArn setting and the AWS
+// connection settings (region, endpoint, accessKey, secretKey).
+func NewSNSSink(settings map[string]string) (Sink, error) {
+	topicARN := settings["topicArn"]
+	if topicARN == "" {
+		return nil, fmt.Errorf("%w: sns sink requires topicArn", ErrInvalidConfig)
+	}
+	sess, err := awsSession("sns", settings)
+	if err != nil {
+		return nil, err
+	}
+	return &SNSSink{svc: sns.New(sess), topicARN: topicARN}, nil
+}
+
+// Publish publishes n to the topic.
+func (s *SNSSink) Publish(ctx context.Context, n *Notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	input := &sns.PublishInput{
+		TopicArn: aws.String(s.topicARN),
+		Subject:  aws.String(snsSubject),
+		Message:  aws.String(string(body)),
+	}
+	if strings.HasSuffix(s.topicARN, ".fifo") {
+		input.MessageGroupId = aws.String(groupID(n))
+		input.MessageDeduplicationId = aws.String(deduplicationID(n))
+	}
+	_, err = s.svc.PublishWithContext(ctx, input)
+	return err
+}
+
+// Close releases nothing; the HTTP client is shared.
+func (s *SNSSink) Close() error {
+	return nil
+}
+
+// SQSSink sends notifications to an Amazon SQS queue, as S3 does. On FIFO
+// queues messages are grouped by object key.
+type SQSSink struct {
+	svc      sqsiface.SQSAPI
+	queueURL string
+}
+
+// NewSQSSink creates an SQS sink from the queueUrl setting and the AWS
+// connection settings (region, endpoint, accessKey, secretKey).
+func NewSQSSink(settings map[string]string) (Sink, error) {
+	queueURL := settings["queueUrl"]
+	if queueURL == "" {
+		return nil, fmt.Errorf("%w: sqs sink requires queueUrl", ErrInvalidConfig)
+	}
+	sess, err := awsSession("sqs", settings)
+	if err != nil {
+		return nil, err
+	}
+	return &SQSSink{svc: sqs.New(sess), queueURL: queueURL}, nil
+}
+
+// Publish sends n to the queue.
+func (s *SQSSink) Publish(ctx context.Context, n *Notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	input := &sqs.SendMessageInput{
+		QueueUrl:    aws.String(s.queueURL),
+		MessageBody: aws.String(string(body)),
+	}
+	if strings.HasSuffix(s.queueURL, ".fifo") {
+		input.MessageGroupId = aws.String(groupID(n))
+		input.MessageDeduplicationId = aws.String(deduplicationID(n))
+	}
+	_, err = s.svc.SendMessageWithContext(ctx, input)
+	return err
+}
+
+// Close releases nothing; the HTTP client is shared.
+func (s *SQSSink) Close() error {
+	return nil
+}
+
+// EventBridgeSink puts notifications on an Amazon EventBridge bus in the
+// format S3 uses for EventBridge: detail types "Object Created" and
+// "Object Deleted", with the object in the event detail.
+type EventBridgeSink struct {
+	svc    eventbridgeiface.EventBridgeAPI
+	bus    string
+	source string
+}
+
+// NewEventBridgeSink creates an EventBridge sink from the eventBusName
+// (default bus if empty) and source (defaultEventBridgeSource if empty)
+// settings and the AWS connection settings.
+func NewEventBridgeSink(settings map[string]string) (Sink, error) {
+	source := settings["source"]
+	if source == "" {
+		source = defaultEventBridgeSource
+	}
+	if strings.HasPrefix(source, "aws.") {
+		return nil, fmt.Errorf("%w: eventbridge source %q is reserved for AWS services", ErrInvalidConfig, source)
+	}
+	sess, err := awsSession("eventbridge", settings)
+	if err != nil {
+		return nil, err
+	}
+	return &EventBridgeSink{svc: eventbridge.New(sess), bus: settings["eventBusName"], source: source}, nil
+}
+
+// eventBridgeDetail is the detail of S3 events on EventBridge.
+type eventBridgeDetail struct {
+	Version         string            `json:"version"`
+	Bucket          eventBridgeBucket `json:"bucket"`
+	Object          eventBridgeObject `json:"object"`
+	RequestID       string            `json:"request-id"`
+	Requester       string            `json:"requester"`
+	SourceIPAddress string            `json:"source-ip-address,omitempty"`
+	Reason          string            `json:"reason"`
+	DeletionType    string            `json:"deletion-type,omitempty"`
+}
+
+type eventBridgeBucket struct {
+	Name string `json:"name"`
+}
+
+type eventBridgeObject struct {
+	Key       string `json:"key"`
+	Size      int64  `json:"size,omitempty"`
+	ETag      string `json:"etag,omitempty"`
+	Sequencer string `json:"sequencer"`
+}
+
+// Publish puts one event per record on the bus.
+func (s *EventBridgeSink) Publish(ctx context.Context, n *Notification) error {
+	entries := make([]*eventbridge.PutEventsRequestEntry, 0, len(n.Records))
+	for _, record := range n.Records {
+		detailType, reason, deletionType := "Object Created", "PutObject", ""
+		switch record.EventName {
+		case EventObjectCreatedCopy:
+			reason = "CopyObject"
+		case EventObjectCreatedCompleteMultipartUpload:
+			reason = "CompleteMultipartUpload"
+		case EventObjectRemovedDelete:
+			detailType, reason, deletionType = "Object Deleted", "DeleteObject", "Permanently Deleted"
+		}
+		detail, err := json.Marshal(eventBridgeDetail{
+			Version:         "0",
+			Bucket:          eventBridgeBucket{Name: record.S3.Bucket.Name},
+			Object:          eventBridgeObject{Key: record.S3.Object.DecodedKey(), Size: record.S3.Object.Size, ETag: record.S3.Object.ETag, Sequencer: record.S3.Object.Sequencer},
+			RequestID:       record.ResponseElements["x-amz-request-id"],
+			Requester:       record.UserIdentity.PrincipalID,
+			SourceIPAddress: record.RequestParameters.SourceIPAddress,
+			Reason:          reason,
+			DeletionType:    deletionType,
+		})
+		if err != nil {
+			return err
+		}
+		entry := &eventbridge.PutEventsRequestEntry{
+			Source:     aws.String(s.source),
+			DetailType: aws.String(detailType),
+			Detail:     aws.String(string(detail)),
+			Resources:  []*string{aws.String(record.S3.Bucket.ARN)},
+		}
+		if at, err := time.Parse(eventTimeFormat, record.EventTime); err == nil {
+			entry.Time = aws.Time(at)
+		}
+		if s.bus != "" {
+			entry.EventBusName = aws.String(s.bus)
+		}
+		entries = append(entries, entry)
+	}
+
+	out, err := s.svc.PutEventsWithContext(ctx, &eventbridge.PutEventsInput{Entries: entries})
+	if err != nil {
+		return err
+	}
+	if failed := aws.Int64Value(out.FailedEntryCount); failed > 0 {
+		for _, entry := range out.Entries {
+			if entry != nil && entry.ErrorCode != nil {
+				return fmt.Errorf("eventbridge rejected %d event(s): %s: %s", failed, aws.StringValue(entry.ErrorCode), aws.StringValue(entry.ErrorMessage))
+			}
+		}
+		return fmt.Errorf("eventbridge rejected %d event(s)", failed)
+	}
+	return nil
+}
+
+// Close releases nothing; the HTTP client is shared.
+func (s *EventBridgeSink) Close() error {
+	return nil
+}
+
+// groupID returns the FIFO message group of n, derived from its object so
+// changes to one object are delivered in order.
+func groupID(n *Notification) string {
+	if len(n.Records) == 0 {
+		return DefaultBucket
+	}
+	sum := sha256.Sum256([]byte(n.Records[0].S3.Bucket.Name + "/" + n.Records[0].S3.Object.Key))
+	return hex.EncodeToString(sum[:])
+}
+
+// deduplicationID returns the FIFO deduplication ID of n, stable across
+// retries of the same notification.
+func deduplicationID(n *Notification) string {
+	if len(n.Records) == 0 {
+		return ""
+	}
+	r := n.Records[0]
+	sum := sha256.Sum256([]byte(r.S3.ConfigurationID + "/" + r.S3.Object.Sequencer))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build awss3
+
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"                                  //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+	"github.com/aws/aws-sdk-go/aws/request"                          //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+	"github.com/aws/aws-sdk-go/service/eventbridge"                  //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+	"github.com/aws/aws-sdk-go/service/eventbridge/eventbridgeiface" //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+	"github.com/aws/aws-sdk-go/service/sns"                          //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+	"github.com/aws/aws-sdk-go/service/sns/snsiface"                 //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+	"github.com/aws/aws-sdk-go/service/sqs"                          //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"                 //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+)
+
+type fakeSNS struct {
+	snsiface.SNSAPI
+	input *sns.PublishInput
+}
+
+func (f *fakeSNS) PublishWithContext(_ aws.Context, input *sns.PublishInput, _ ...request.Option) (*sns.PublishOutput, error) {
+	f.input = input
+	return &sns.PublishOutput{}, nil
+}
+
+type fakeSQS struct {
+	sqsiface.SQSAPI
+	input *sqs.SendMessageInput
+}
+
+func (f *fakeSQS) SendMessageWithContext(_ aws.Context, input *sqs.SendMessageInput, _ ...request.Option) (*sqs.SendMessageOutput, error) {
+	f.input = input
+	return &sqs.SendMessageOutput{}, nil
+}
+
+type fakeEventBridge struct {
+	eventbridgeiface.EventBridgeAPI
+	input  *eventbridge.PutEventsInput
+	failed bool
+}
+
+func (f *fakeEventBridge) PutEventsWithContext(_ aws.Context, input *eventbridge.PutEventsInput, _ ...request.Option) (*eventbridge.PutEventsOutput, error) {
+	f.input = input
+	if f.failed {
+		return &eventbridge.PutEventsOutput{
+			FailedEntryCount: aws.Int64(1),
+			Entries:          []*eventbridge.PutEventsResultEntry{{ErrorCode: aws.String("InternalFailure"), ErrorMessage: aws.String("try again")}},
+		}, nil
+	}
+	return &eventbridge.PutEventsOutput{FailedEntryCount: aws.Int64(0)}, nil
+}
+
+func testNotification(eventName string) *Notification {
+	record := newRecord(context.Background(), "media", "us-east-1", eventName, "images/my cat.jpg", 4, "1730800800-4", "000000000000000A",
+		time.Date(2025, 11, 5, 10, 0, 0, 0, time.UTC))
+	record.S3.ConfigurationID = "images"
+	return &Notification{Records: []Record{record}}
+}
+
+func TestSNSSink(t *testing.T) {
+	fake := &fakeSNS{}
+	sink := &SNSSink{svc: fake, topicARN: "arn:aws:sns:us-east-1:123456789012:media"}
+	if err := sink.Publish(context.Background(), testNotification(EventObjectCreatedPut)); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if aws.StringValue(fake.input.Subject) != snsSubject || fake.input.MessageGroupId != nil {
+		t.Errorf("input = %+v", fake.input)
+	}
+	var n Notification
+	if err := json.Unmarshal([]byte(aws.StringValue(fake.input.Message)), &n); err != nil || len(n.Records) != 1 {
+		t.Fatalf("message = %s (%v)", aws.StringValue(fake.input.Message), err)
+	}
+
+	sink.topicARN += ".fifo"
+	if err := sink.Publish(context.Background(), testNotification(EventObjectCreatedPut)); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if fake.input.MessageGroupId == nil || fake.input.MessageDeduplicationId == nil {
+		t.Error("FIFO publish lacks group or deduplication ID")
+	}
+
+	if _, err := NewSNSSink(map[string]string{}); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("NewSNSSink without topicArn = %v, want ErrInvalidConfig", err)
+	}
+}
+
+func TestSQSSink(t *testing.T) {
+	fake := &fakeSQS{}
+	sink := &SQSSink{svc: fake, queueURL: "https://sqs.us-east-1.amazonaws.com/123456789012/media.fifo"}
+	if err := sink.Publish(context.Background(), testNotification(EventObjectRemovedDelete)); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if !strings.Contains(aws.StringValue(fake.input.MessageBody), `"eventName":"ObjectRemoved:Delete"`) {
+		t.Errorf("body = %s", aws.StringValue(fake.input.MessageBody))
+	}
+	first := aws.StringValue(fake.input.MessageDeduplicationId)
+	if err := sink.Publish(context.Background(), testNotification(EventObjectRemovedDelete)); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if aws.StringValue(fake.input.MessageDeduplicationId) != first {
+		t.Error("a retried notification got a new deduplication ID")
+	}
+
+	if _, err := NewSQSSink(map[string]string{}); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("NewSQSSink without queueUrl = %v, want ErrInvalidConfig", err)
+	}
+}
+
+func TestEventBridgeSink(t *testing.T) {
+	fake := &fakeEventBridge{}
+	sink := &EventBridgeSink{svc: fake, bus: "objects", source: defaultEventBridgeSource}
+	if err := sink.Publish(context.Background(), testNotification(EventObjectRemovedDelete)); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	entry := fake.input.Entries[0]
+	if aws.StringValue(entry.DetailType) != "Object Deleted" || aws.StringValue(entry.EventBusName) != "objects" ||
+		aws.StringValue(entry.Resources[0]) != "arn:aws:s3:::media" {
+		t.Errorf("entry = %+v", entry)
+	}
+	var detail eventBridgeDetail
+	if err := json.Unmarshal([]byte(aws.StringValue(entry.Detail)), &detail); err != nil {
+		t.Fatal(err)
+	}
+	if detail.Object.Key != "images/my cat.jpg" || detail.Reason != "DeleteObject" || detail.Bucket.Name != "media" {
+		t.Errorf("detail = %+v", detail)
+	}
+
+	fake.failed = true
+	if err := sink.Publish(context.Background(), testNotification(EventObjectCreatedPut)); err == nil || !strings.Contains(err.Error(), "InternalFailure") {
+		t.Errorf("Publish with a rejected entry = %v", err)
+	}
+
+	if _, err := NewEventBridgeSink(map[string]string{"source": "aws.s3"}); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("NewEventBridgeSink with source aws.s3 = %v, want ErrInvalidConfig", err)
+	}
+}
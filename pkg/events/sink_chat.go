@@ -0,0 +1,235 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jeremyhahn/go-objstore/pkg/transport"
+)
+
+func init() {
+	RegisterSink("slack", NewSlackSink)
+	RegisterSink("teams", NewTeamsSink)
+}
+
+// fact is one labeled line of a message.
+type fact struct {
+	name, value string
+}
+
+// message is the human-readable form of a record, for the chat and email
+// sinks.
+type message struct {
+	title string
+	facts []fact
+}
+
+// newMessage describes r for people: a title naming the event and the
+// object or backend it is about, and the record's fields and details.
+func newMessage(r *Record) message {
+	key := r.S3.Object.DecodedKey()
+	subject := key
+	if subject == "" {
+		subject = r.S3.Bucket.Name
+	}
+	m := message{title: fmt.Sprintf("[%s] %s: %s", r.S3.Bucket.Name, r.EventName, subject)}
+	add := func(name, value string) {
+		if value != "" {
+			m.facts = append(m.facts, fact{name, value})
+		}
+	}
+	add("Event", r.EventName)
+	add("Bucket", r.S3.Bucket.Name)
+	add("Key", key)
+	if r.S3.Object.Size > 0 {
+		add("Size", strconv.FormatInt(r.S3.Object.Size, 10))
+	}
+	add("ETag", r.S3.Object.ETag)
+	add("Time", r.EventTime)
+	add("Principal", r.UserIdentity.PrincipalID)
+	add("Request", r.ResponseElements["x-amz-request-id"])
+	add("Rule", r.S3.ConfigurationID)
+	names := make([]string, 0, len(r.Details))
+	for name := range r.Details {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		add(name, r.Details[name])
+	}
+	return m
+}
+
+// text renders m as plain text, one fact per line.
+func (m message) text() string {
+	var b strings.Builder
+	b.WriteString(m.title)
+	b.WriteString("\n\n")
+	for _, f := range m.facts {
+		fmt.Fprintf(&b, "%s: %s\n", f.name, f.value)
+	}
+	return b.String()
+}
+
+// webhookSink posts one JSON payload per record to an incoming webhook.
+type webhookSink struct {
+	name    string
+	client  *http.Client
+	url     string
+	payload func(m message) any
+}
+
+func newWebhookSink(name string, settings map[string]string, payload func(m message) any) (*webhookSink, error) {
+	webhook := settings["webhookUrl"]
+	if webhook == "" {
+		return nil, fmt.Errorf("%w: %s sink requires webhookUrl", ErrInvalidConfig, name)
+	}
+	if u, err := url.ParseRequestURI(webhook); err != nil || (u.Scheme != "https" && u.Scheme != "http") {
+		return nil, fmt.Errorf("%w: %s sink: invalid webhookUrl", ErrInvalidConfig, name)
+	}
+	httpCfg, err := transport.ParseSettings(settings)
+	if err != nil {
+		return nil, err
+	}
+	return &webhookSink{
+		name:    name,
+		client:  transport.Default.Client(name, webhook, httpCfg),
+		url:     webhook,
+		payload: payload,
+	}, nil
+}
+
+// Publish posts a message for each record.
+func (s *webhookSink) Publish(ctx context.Context, n *Notification) error {
+	for i := range n.Records {
+		body, err := json.Marshal(s.payload(newMessage(&n.Records[i])))
+		if err != nil {
+			return err
+		}
+		if err := s.post(ctx, body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *webhookSink) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		// The URL is a secret; keep it out of errors.
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			err = uerr.Err
+		}
+		return fmt.Errorf("%s webhook: %w", s.name, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s webhook: %s: %s", s.name, resp.Status, bytes.TrimSpace(data))
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<20))
+	return nil
+}
+
+// Close releases nothing; the HTTP client is shared.
+func (s *webhookSink) Close() error {
+	return nil
+}
+
+// NewSlackSink creates a sink that posts a message per event to a Slack
+// incoming webhook, from its settings:
+//
+//   - webhookUrl: the incoming webhook URL (required)
+//   - channel, username, iconEmoji: overrides of the webhook's defaults,
+//     honored by legacy webhooks
+func NewSlackSink(settings map[string]string) (Sink, error) {
+	channel, username, icon := settings["channel"], settings["username"], settings["iconEmoji"]
+	sink, err := newWebhookSink("slack", settings, func(m message) any {
+		var b strings.Builder
+		fmt.Fprintf(&b, "*%s*\n", slackEscape(m.title))
+		for _, f := range m.facts {
+			fmt.Fprintf(&b, "*%s:* %s\n", slackEscape(f.name), slackEscape(f.value))
+		}
+		payload := map[string]string{"text": b.String()}
+		if channel != "" {
+			payload["channel"] = channel
+		}
+		if username != "" {
+			payload["username"] = username
+		}
+		if icon != "" {
+			payload["icon_emoji"] = icon
+		}
+		return payload
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sink, nil
+}
+
+// slackEscape escapes the characters Slack treats as markup.
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// NewTeamsSink creates a sink that posts an Adaptive Card per event to a
+// Microsoft Teams incoming webhook or Workflows webhook, from its settings:
+//
+//   - webhookUrl: the webhook URL (required)
+func NewTeamsSink(settings map[string]string) (Sink, error) {
+	sink, err := newWebhookSink("teams", settings, func(m message) any {
+		facts := make([]map[string]string, 0, len(m.facts))
+		for _, f := range m.facts {
+			facts = append(facts, map[string]string{"title": f.name, "value": f.value})
+		}
+		card := map[string]any{
+			"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+			"type":    "AdaptiveCard",
+			"version": "1.4",
+			"body": []any{
+				map[string]any{"type": "TextBlock", "text": m.title, "weight": "Bolder", "size": "Medium", "wrap": true},
+				map[string]any{"type": "FactSet", "facts": facts},
+			},
+		}
+		return map[string]any{
+			"type": "message",
+			"attachments": []any{map[string]any{
+				"contentType": "application/vnd.microsoft.card.adaptive",
+				"content":     card,
+			}},
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sink, nil
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testAlert returns a notification of a failed lifecycle policy.
+func testAlert() *Notification {
+	r := newRecord(context.Background(), "media", "", EventPolicyFailed, "old/a&b.csv", 0, "", "1", time.Time{})
+	r.S3.ConfigurationID = "ops"
+	r.Details = map[string]string{DetailError: "archive failed", DetailPolicy: "expire"}
+	return &Notification{Records: []Record{r}}
+}
+
+// webhookServer records the JSON bodies posted to it and answers status.
+func webhookServer(t *testing.T, status int) (*httptest.Server, *[]map[string]any) {
+	t.Helper()
+	var bodies []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Content-Type = %q", r.Header.Get("Content-Type"))
+		}
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+		bodies = append(bodies, body)
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, &bodies
+}
+
+func TestSlackSink(t *testing.T) {
+	server, bodies := webhookServer(t, http.StatusOK)
+	sink, err := NewSlackSink(map[string]string{"webhookUrl": server.URL, "channel": "#ops"})
+	if err != nil {
+		t.Fatalf("NewSlackSink: %v", err)
+	}
+	defer func() { _ = sink.Close() }()
+	if err := sink.Publish(context.Background(), testAlert()); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	if len(*bodies) != 1 {
+		t.Fatalf("got %d posts, want 1", len(*bodies))
+	}
+	body := (*bodies)[0]
+	text, _ := body["text"].(string)
+	for _, want := range []string{"*[media] Policy:Failed: old/a&amp;b.csv*", "*error:* archive failed", "*policy:* expire", "*Rule:* ops"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text %q does not contain %q", text, want)
+		}
+	}
+	if body["channel"] != "#ops" {
+		t.Errorf("channel = %v", body["channel"])
+	}
+}
+
+func TestTeamsSink(t *testing.T) {
+	server, bodies := webhookServer(t, http.StatusAccepted)
+	sink, err := NewTeamsSink(map[string]string{"webhookUrl": server.URL})
+	if err != nil {
+		t.Fatalf("NewTeamsSink: %v", err)
+	}
+	if err := sink.Publish(context.Background(), testAlert()); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	data, _ := json.Marshal((*bodies)[0])
+	for _, want := range []string{`"contentType":"application/vnd.microsoft.card.adaptive"`,
+		`"text":"[media] Policy:Failed: old/a\u0026b.csv"`, `{"title":"error","value":"archive failed"}`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("card %s does not contain %s", data, want)
+		}
+	}
+}
+
+func TestWebhookSinkErrors(t *testing.T) {
+	server, _ := webhookServer(t, http.StatusForbidden)
+	sink, err := NewSlackSink(map[string]string{"webhookUrl": server.URL + "/secret"})
+	if err != nil {
+		t.Fatalf("NewSlackSink: %v", err)
+	}
+	err = sink.Publish(context.Background(), testAlert())
+	if err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("Publish error = %v, want the status", err)
+	}
+
+	server.Close()
+	err = sink.Publish(context.Background(), testAlert())
+	if err == nil || strings.Contains(err.Error(), "secret") {
+		t.Errorf("Publish error = %v, want an error without the webhook URL", err)
+	}
+
+	for _, settings := range []map[string]string{{}, {"webhookUrl": "hooks.slack.com/x"}, {"webhookUrl": "ftp://hooks"}} {
+		if _, err := NewSlackSink(settings); !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("NewSlackSink(%v) = %v, want ErrInvalidConfig", settings, err)
+		}
+		if _, err := NewTeamsSink(settings); !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("NewTeamsSink(%v) = %v, want ErrInvalidConfig", settings, err)
+		}
+	}
+}
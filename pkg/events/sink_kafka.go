@@ -0,0 +1,385 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build kafka
+
+package events
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/transport"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/sasl/plain"
+	"github.com/twmb/franz-go/pkg/sasl/scram"
+)
+
+// Kafka value formats.
+const (
+	// KafkaFormatJSON writes the S3 notification document, as the other
+	// sinks do.
+	KafkaFormatJSON = "json"
+
+	// KafkaFormatJSONSchema writes a flat ObjectEvent as JSON, framed with
+	// the schema registry ID of its JSON schema.
+	KafkaFormatJSONSchema = "json-schema"
+
+	// KafkaFormatAvro writes a flat ObjectEvent in Avro binary encoding,
+	// framed with the schema registry ID of its Avro schema.
+	KafkaFormatAvro = "avro"
+)
+
+// Kafka record headers.
+const (
+	headerEventName = "objstore-event-name"
+	headerEventID   = "objstore-event-id"
+)
+
+// objectEventAvroSchema is the Avro schema of ObjectEvent.
+const objectEventAvroSchema = `{"type":"record","name":"ObjectEvent","namespace":"io.objstore.events","fields":[` +
+	`{"name":"eventName","type":"string"},` +
+	`{"name":"eventTime","type":"string"},` +
+	`{"name":"region","type":"string"},` +
+	`{"name":"bucket","type":"string"},` +
+	`{"name":"key","type":"string"},` +
+	`{"name":"size","type":"long"},` +
+	`{"name":"etag","type":"string"},` +
+	`{"name":"sequencer","type":"string"},` +
+	`{"name":"principalId","type":"string"},` +
+	`{"name":"requestId","type":"string"},` +
+	`{"name":"configurationId","type":"string"}]}`
+
+// objectEventJSONSchema is the JSON schema of ObjectEvent.
+const objectEventJSONSchema = `{"$schema":"http://json-schema.org/draft-07/schema#","title":"ObjectEvent","type":"object",` +
+	`"required":["eventName","eventTime","bucket","key","sequencer"],"properties":{` +
+	`"eventName":{"type":"string"},` +
+	`"eventTime":{"type":"string","format":"date-time"},` +
+	`"region":{"type":"string"},` +
+	`"bucket":{"type":"string"},` +
+	`"key":{"type":"string"},` +
+	`"size":{"type":"integer"},` +
+	`"etag":{"type":"string"},` +
+	`"sequencer":{"type":"string"},` +
+	`"principalId":{"type":"string"},` +
+	`"requestId":{"type":"string"},` +
+	`"configurationId":{"type":"string"}}}`
+
+func init() {
+	RegisterSink("kafka", NewKafkaSink)
+}
+
+// ObjectEvent is the flat form of a Record written by the json-schema and
+// avro formats, for tables in data lakes and CDC pipelines. Key is not
+// URL-encoded.
+type ObjectEvent struct {
+	EventName       string `json:"eventName"`
+	EventTime       string `json:"eventTime"`
+	Region          string `json:"region"`
+	Bucket          string `json:"bucket"`
+	Key             string `json:"key"`
+	Size            int64  `json:"size"`
+	ETag            string `json:"etag"`
+	Sequencer       string `json:"sequencer"`
+	PrincipalID     string `json:"principalId"`
+	RequestID       string `json:"requestId"`
+	ConfigurationID string `json:"configurationId"`
+}
+
+// NewObjectEvent flattens r.
+func NewObjectEvent(r *Record) ObjectEvent {
+	return ObjectEvent{
+		EventName:       r.EventName,
+		EventTime:       r.EventTime,
+		Region:          r.AWSRegion,
+		Bucket:          r.S3.Bucket.Name,
+		Key:             r.S3.Object.DecodedKey(),
+		Size:            r.S3.Object.Size,
+		ETag:            r.S3.Object.ETag,
+		Sequencer:       r.S3.Object.Sequencer,
+		PrincipalID:     r.UserIdentity.PrincipalID,
+		RequestID:       r.ResponseElements["x-amz-request-id"],
+		ConfigurationID: r.S3.ConfigurationID,
+	}
+}
+
+// kafkaProducer is the part of *kgo.Client the sink uses.
+type kafkaProducer interface {
+	ProduceSync(ctx context.Context, rs ...*kgo.Record) kgo.ProduceResults
+	Close()
+}
+
+// KafkaSink produces one Kafka record per event, keyed by object key so
+// every change to an object lands on the same partition in order. The
+// producer is idempotent and waits for all in-sync replicas, so retries
+// never duplicate or reorder a key's records.
+type KafkaSink struct {
+	producer kafkaProducer
+	topic    string
+	format   string
+	registry *schemaRegistry
+}
+
+// NewKafkaSink creates a Kafka sink from its settings:
+//
+//   - brokers: comma-separated seed brokers (required)
+//   - topic: topic to produce to (required)
+//   - clientId: client ID reported to the brokers
+//   - tls: "true" to connect with TLS
+//   - saslMechanism: "plain", "scram-sha-256" or "scram-sha-512", with
+//     saslUsername and saslPassword
+//   - format: "json" (default), "json-schema" or "avro"
+//   - schemaRegistry: schema registry URL, required for json-schema and
+//     avro, with optional schemaRegistryUsername and
+//     schemaRegistryPassword
+//   - subject: schema subject (default "<topic>-value")
+func NewKafkaSink(settings map[string]string) (Sink, error) {
+	var brokers []string
+	for _, broker := range strings.Split(settings["brokers"], ",") {
+		if broker = strings.TrimSpace(broker); broker != "" {
+			brokers = append(brokers, broker)
+		}
+	}
+	topic := settings["topic"]
+	if len(brokers) == 0 || topic == "" {
+		return nil, fmt.Errorf("%w: kafka sink requires brokers and topic", ErrInvalidConfig)
+	}
+
+	format := settings["format"]
+	if format == "" {
+		format = KafkaFormatJSON
+	}
+	var registry *schemaRegistry
+	switch format {
+	case KafkaFormatJSON:
+	case KafkaFormatJSONSchema, KafkaFormatAvro:
+		var err error
+		if registry, err = newSchemaRegistry(settings, topic, format); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown kafka format %q", ErrInvalidConfig, format)
+	}
+
+	opts := []kgo.Opt{
+		kgo.SeedBrokers(brokers...),
+		kgo.DefaultProduceTopic(topic),
+		kgo.RequiredAcks(kgo.AllISRAcks()),
+		kgo.RecordPartitioner(kgo.StickyKeyPartitioner(nil)),
+	}
+	if id := settings["clientId"]; id != "" {
+		opts = append(opts, kgo.ClientID(id))
+	}
+	if settings["tls"] == "true" {
+		opts = append(opts, kgo.DialTLSConfig(&tls.Config{MinVersion: tls.VersionTLS12}))
+	}
+	user, pass := settings["saslUsername"], settings["saslPassword"]
+	switch mechanism := settings["saslMechanism"]; mechanism {
+	case "":
+	case "plain":
+		opts = append(opts, kgo.SASL(plain.Auth{User: user, Pass: pass}.AsMechanism()))
+	case "scram-sha-256":
+		opts = append(opts, kgo.SASL(scram.Auth{User: user, Pass: pass}.AsSha256Mechanism()))
+	case "scram-sha-512":
+		opts = append(opts, kgo.SASL(scram.Auth{User: user, Pass: pass}.AsSha512Mechanism()))
+	default:
+		return nil, fmt.Errorf("%w: unknown kafka sasl mechanism %q", ErrInvalidConfig, mechanism)
+	}
+
+	client, err := kgo.NewClient(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &KafkaSink{producer: client, topic: topic, format: format, registry: registry}, nil
+}
+
+// Publish produces one record per event and waits for the brokers to
+// acknowledge them.
+func (s *KafkaSink) Publish(ctx context.Context, n *Notification) error {
+	records := make([]*kgo.Record, 0, len(n.Records))
+	for i := range n.Records {
+		r := &n.Records[i]
+		value, err := s.encode(ctx, r)
+		if err != nil {
+			return err
+		}
+		records = append(records, &kgo.Record{
+			Topic: s.topic,
+			Key:   []byte(r.S3.Object.DecodedKey()),
+			Value: value,
+			Headers: []kgo.RecordHeader{
+				{Key: headerEventName, Value: []byte(r.EventName)},
+				{Key: headerEventID, Value: []byte(r.S3.ConfigurationID + "/" + r.S3.Object.Sequencer)},
+			},
+		})
+	}
+	return s.producer.ProduceSync(ctx, records...).FirstErr()
+}
+
+// encode returns the record value of r in the sink's format.
+func (s *KafkaSink) encode(ctx context.Context, r *Record) ([]byte, error) {
+	if s.format == KafkaFormatJSON {
+		return json.Marshal(Notification{Records: []Record{*r}})
+	}
+
+	id, err := s.registry.id(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Confluent wire format: magic byte 0, then the big-endian schema ID.
+	value := []byte{0, 0, 0, 0, 0}
+	binary.BigEndian.PutUint32(value[1:], uint32(id)) // #nosec G115 -- registry IDs are positive int32
+	event := NewObjectEvent(r)
+	if s.format == KafkaFormatAvro {
+		return appendObjectEventAvro(value, &event), nil
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	return append(value, data...), nil
+}
+
+// Close flushes nothing, as Publish waits for acknowledgements, and closes
+// the producer.
+func (s *KafkaSink) Close() error {
+	s.producer.Close()
+	return nil
+}
+
+// appendObjectEventAvro appends the Avro binary encoding of e, following
+// objectEventAvroSchema field by field.
+func appendObjectEventAvro(b []byte, e *ObjectEvent) []byte {
+	for _, field := range []string{e.EventName, e.EventTime, e.Region, e.Bucket, e.Key} {
+		b = appendAvroString(b, field)
+	}
+	b = appendAvroLong(b, e.Size)
+	for _, field := range []string{e.ETag, e.Sequencer, e.PrincipalID, e.RequestID, e.ConfigurationID} {
+		b = appendAvroString(b, field)
+	}
+	return b
+}
+
+// appendAvroLong appends v as an Avro long: a zig-zag varint.
+func appendAvroLong(b []byte, v int64) []byte {
+	return binary.AppendUvarint(b, uint64(v<<1)^uint64(v>>63)) // #nosec G115 -- zig-zag encoding
+}
+
+// appendAvroString appends s as an Avro string: its length, then its bytes.
+func appendAvroString(b []byte, s string) []byte {
+	return append(appendAvroLong(b, int64(len(s))), s...)
+}
+
+// schemaRegistry registers the sink's schema with a Confluent-compatible
+// schema registry and caches its ID.
+type schemaRegistry struct {
+	client     *http.Client
+	url        string
+	subject    string
+	schemaType string
+	schema     string
+	username   string
+	password   string
+
+	mu       sync.Mutex
+	schemaID int
+}
+
+func newSchemaRegistry(settings map[string]string, topic, format string) (*schemaRegistry, error) {
+	base := strings.TrimSuffix(settings["schemaRegistry"], "/")
+	if base == "" {
+		return nil, fmt.Errorf("%w: kafka format %q requires schemaRegistry", ErrInvalidConfig, format)
+	}
+	if _, err := url.ParseRequestURI(base); err != nil {
+		return nil, fmt.Errorf("%w: schemaRegistry: %w", ErrInvalidConfig, err)
+	}
+	httpCfg, err := transport.ParseSettings(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &schemaRegistry{
+		client:     transport.Default.Client("schema-registry", base+"|"+settings["schemaRegistryUsername"], httpCfg),
+		url:        base,
+		subject:    settings["subject"],
+		schemaType: "JSON",
+		schema:     objectEventJSONSchema,
+		username:   settings["schemaRegistryUsername"],
+		password:   settings["schemaRegistryPassword"],
+	}
+	if r.subject == "" {
+		r.subject = topic + "-value"
+	}
+	if format == KafkaFormatAvro {
+		r.schemaType, r.schema = "AVRO", objectEventAvroSchema
+	}
+	return r, nil
+}
+
+// id returns the schema's registry ID, registering it on first use. The
+// registry returns the existing ID when the schema is already registered.
+func (r *schemaRegistry) id(ctx context.Context) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.schemaID != 0 {
+		return r.schemaID, nil
+	}
+
+	body, err := json.Marshal(map[string]string{"schema": r.schema, "schemaType": r.schemaType})
+	if err != nil {
+		return 0, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		r.url+"/subjects/"+url.PathEscape(r.subject)+"/versions", bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+	if r.username != "" {
+		req.SetBasicAuth(r.username, r.password)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("schema registry: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return 0, fmt.Errorf("schema registry: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("schema registry: registering %s: %s: %s", r.subject, resp.Status, bytes.TrimSpace(data))
+	}
+	var registered struct {
+		ID int `json:"id"`
+	}
+	if err := json.Unmarshal(data, &registered); err != nil || registered.ID <= 0 {
+		return 0, fmt.Errorf("schema registry: unexpected response %s", bytes.TrimSpace(data))
+	}
+	r.schemaID = registered.ID
+	return r.schemaID, nil
+}
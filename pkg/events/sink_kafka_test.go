@@ -0,0 +1,223 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build kafka
+
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+type fakeProducer struct {
+	records []*kgo.Record
+	err     error
+	closed  bool
+}
+
+func (p *fakeProducer) ProduceSync(_ context.Context, rs ...*kgo.Record) kgo.ProduceResults {
+	results := make(kgo.ProduceResults, 0, len(rs))
+	for _, r := range rs {
+		if p.err == nil {
+			p.records = append(p.records, r)
+		}
+		results = append(results, kgo.ProduceResult{Record: r, Err: p.err})
+	}
+	return results
+}
+
+func (p *fakeProducer) Close() { p.closed = true }
+
+func testKafkaNotification() *Notification {
+	n := &Notification{}
+	r := newRecord(context.Background(), "media", "us-east-1", EventObjectCreatedPut, "images/my cat.jpg", 4, "e", "00000001", time.Time{})
+	r.S3.ConfigurationID = "images"
+	n.Records = append(n.Records, r)
+	return n
+}
+
+func TestKafkaSinkJSON(t *testing.T) {
+	producer := &fakeProducer{}
+	sink := &KafkaSink{producer: producer, topic: "objects", format: KafkaFormatJSON}
+	if err := sink.Publish(context.Background(), testKafkaNotification()); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	record := producer.records[0]
+	if record.Topic != "objects" || string(record.Key) != "images/my cat.jpg" {
+		t.Errorf("record topic %q key %q", record.Topic, record.Key)
+	}
+	headers := map[string]string{}
+	for _, h := range record.Headers {
+		headers[h.Key] = string(h.Value)
+	}
+	if headers[headerEventName] != EventObjectCreatedPut || headers[headerEventID] != "images/00000001" {
+		t.Errorf("headers = %v", headers)
+	}
+	var n Notification
+	if err := json.Unmarshal(record.Value, &n); err != nil || n.Records[0].S3.Object.Key != "images/my+cat.jpg" {
+		t.Errorf("value = %s (%v)", record.Value, err)
+	}
+
+	producer.err = errors.New("not leader")
+	if err := sink.Publish(context.Background(), testKafkaNotification()); err == nil {
+		t.Error("Publish succeeded despite produce error")
+	}
+	if err := sink.Close(); err != nil || !producer.closed {
+		t.Errorf("Close = %v, closed = %v", err, producer.closed)
+	}
+}
+
+func newTestSchemaRegistry(t *testing.T, wantType string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.URL.Path != "/subjects/objects-value/versions" || r.Method != http.MethodPost {
+			t.Errorf("%s %s", r.Method, r.URL.Path)
+		}
+		if user, pass, _ := r.BasicAuth(); user != "registry" || pass != "secret" {
+			t.Errorf("basic auth %q/%q", user, pass)
+		}
+		var body struct {
+			Schema     string `json:"schema"`
+			SchemaType string `json:"schemaType"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.SchemaType != wantType || !json.Valid([]byte(body.Schema)) {
+			t.Errorf("register body %+v (%v)", body, err)
+		}
+		_, _ = w.Write([]byte(`{"id":42}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func newTestKafkaSink(t *testing.T, format, registryURL string) (*KafkaSink, *fakeProducer) {
+	t.Helper()
+	registry, err := newSchemaRegistry(map[string]string{
+		"schemaRegistry":         registryURL,
+		"schemaRegistryUsername": "registry",
+		"schemaRegistryPassword": "secret",
+	}, "objects", format)
+	if err != nil {
+		t.Fatalf("newSchemaRegistry: %v", err)
+	}
+	producer := &fakeProducer{}
+	return &KafkaSink{producer: producer, topic: "objects", format: format, registry: registry}, producer
+}
+
+func TestKafkaSinkAvro(t *testing.T) {
+	server, calls := newTestSchemaRegistry(t, "AVRO")
+	sink, producer := newTestKafkaSink(t, KafkaFormatAvro, server.URL)
+	for i := 0; i < 2; i++ {
+		if err := sink.Publish(context.Background(), testKafkaNotification()); err != nil {
+			t.Fatalf("Publish: %v", err)
+		}
+	}
+	if calls.Load() != 1 {
+		t.Errorf("registered schema %d times, want once", calls.Load())
+	}
+
+	value := producer.records[0].Value
+	if value[0] != 0 || binary.BigEndian.Uint32(value[1:5]) != 42 {
+		t.Fatalf("wire header = %x", value[:5])
+	}
+	r := bytes.NewReader(value[5:])
+	readLong := func() int64 {
+		v, err := binary.ReadVarint(r)
+		if err != nil {
+			t.Fatalf("read long: %v", err)
+		}
+		return v
+	}
+	readString := func() string {
+		b := make([]byte, readLong())
+		if _, err := r.Read(b); err != nil && len(b) > 0 {
+			t.Fatalf("read string: %v", err)
+		}
+		return string(b)
+	}
+	var event ObjectEvent
+	event.EventName, event.EventTime, event.Region, event.Bucket, event.Key = readString(), readString(), readString(), readString(), readString()
+	event.Size = readLong()
+	event.ETag, event.Sequencer, event.PrincipalID, event.RequestID, event.ConfigurationID = readString(), readString(), readString(), readString(), readString()
+	if want := NewObjectEvent(&testKafkaNotification().Records[0]); event != want {
+		t.Errorf("decoded %+v, want %+v", event, want)
+	}
+	if r.Len() != 0 {
+		t.Errorf("%d trailing bytes", r.Len())
+	}
+}
+
+func TestKafkaSinkJSONSchema(t *testing.T) {
+	server, _ := newTestSchemaRegistry(t, "JSON")
+	sink, producer := newTestKafkaSink(t, KafkaFormatJSONSchema, server.URL)
+	if err := sink.Publish(context.Background(), testKafkaNotification()); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	value := producer.records[0].Value
+	if value[0] != 0 || binary.BigEndian.Uint32(value[1:5]) != 42 {
+		t.Fatalf("wire header = %x", value[:5])
+	}
+	var event ObjectEvent
+	if err := json.Unmarshal(value[5:], &event); err != nil || event.Key != "images/my cat.jpg" || event.Size != 4 {
+		t.Errorf("value = %s (%v)", value[5:], err)
+	}
+}
+
+func TestKafkaSinkRegistryFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, `{"error_code":409,"message":"incompatible schema"}`, http.StatusConflict)
+	}))
+	defer server.Close()
+
+	sink, producer := newTestKafkaSink(t, KafkaFormatAvro, server.URL)
+	if err := sink.Publish(context.Background(), testKafkaNotification()); err == nil {
+		t.Error("Publish succeeded despite registry failure")
+	}
+	if len(producer.records) != 0 {
+		t.Errorf("produced %d records", len(producer.records))
+	}
+}
+
+func TestNewKafkaSinkValidation(t *testing.T) {
+	for name, settings := range map[string]map[string]string{
+		"no brokers":       {"topic": "objects"},
+		"no topic":         {"brokers": "localhost:9092"},
+		"unknown format":   {"brokers": "localhost:9092", "topic": "objects", "format": "protobuf"},
+		"avro no registry": {"brokers": "localhost:9092", "topic": "objects", "format": "avro"},
+		"unknown sasl":     {"brokers": "localhost:9092", "topic": "objects", "saslMechanism": "gssapi"},
+	} {
+		if _, err := NewKafkaSink(settings); !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("%s: NewKafkaSink = %v, want ErrInvalidConfig", name, err)
+		}
+	}
+
+	sink, err := NewKafkaSink(map[string]string{"brokers": "localhost:9092, localhost:9093", "topic": "objects", "saslMechanism": "scram-sha-512"})
+	if err != nil {
+		t.Fatalf("NewKafkaSink: %v", err)
+	}
+	_ = sink.Close()
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build gcpstorage
+
+package events
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/jeremyhahn/go-objstore/pkg/transport"
+
+	"google.golang.org/api/option"
+	"google.golang.org/api/pubsub/v1"
+	htransport "google.golang.org/api/transport/http"
+)
+
+// maxOrderingKey is the longest ordering key Pub/Sub accepts.
+const maxOrderingKey = 1024
+
+func init() {
+	RegisterSink("pubsub", NewPubSubSink)
+}
+
+// PubSubSink publishes notifications to a Google Cloud Pub/Sub topic. The
+// message data is the S3 notification document; the eventName, bucket and
+// key attributes allow subscription filters, and messages are ordered by
+// object key when the subscription enables message ordering.
+type PubSubSink struct {
+	svc   *pubsub.Service
+	topic string
+}
+
+// NewPubSubSink creates a Pub/Sub sink from the topic setting, either
+// "projects/<project>/topics/<topic>" or a topic name with the project
+// setting. The endpoint setting, or PUBSUB_EMULATOR_HOST, selects an
+// emulator, which needs no credentials; otherwise application default
+// credentials are used.
+func NewPubSubSink(settings map[string]string) (Sink, error) {
+	topic := settings["topic"]
+	if topic == "" {
+		return nil, fmt.Errorf("%w: pubsub sink requires topic", ErrInvalidConfig)
+	}
+	if !strings.HasPrefix(topic, "projects/") {
+		if settings["project"] == "" {
+			return nil, fmt.Errorf("%w: pubsub topic %q needs a project", ErrInvalidConfig, topic)
+		}
+		topic = "projects/" + settings["project"] + "/topics/" + topic
+	}
+
+	httpCfg, err := transport.ParseSettings(settings)
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	base := transport.Default.Transport("pubsub", os.Getenv("GOOGLE_APPLICATION_CREDENTIALS"), httpCfg)
+
+	endpoint := settings["endpoint"]
+	if endpoint == "" && os.Getenv("PUBSUB_EMULATOR_HOST") != "" {
+		endpoint = "http://" + os.Getenv("PUBSUB_EMULATOR_HOST") + "/"
+	}
+	opts := []option.ClientOption{}
+	if endpoint != "" {
+		opts = append(opts, option.WithEndpoint(endpoint), option.WithHTTPClient(&http.Client{Transport: base}))
+	} else {
+		rt, err := htransport.NewTransport(ctx, base, option.WithScopes(pubsub.PubsubScope))
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, option.WithHTTPClient(&http.Client{Transport: rt}))
+	}
+
+	svc, err := pubsub.NewService(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &PubSubSink{svc: svc, topic: topic}, nil
+}
+
+// Publish publishes n to the topic.
+func (s *PubSubSink) Publish(ctx context.Context, n *Notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	message := &pubsub.PubsubMessage{Data: base64.StdEncoding.EncodeToString(body)}
+	if len(n.Records) > 0 {
+		r := n.Records[0]
+		message.Attributes = map[string]string{
+			"eventName": r.EventName,
+			"bucket":    r.S3.Bucket.Name,
+			"key":       r.S3.Object.DecodedKey(),
+		}
+		if key := r.S3.Bucket.Name + "/" + r.S3.Object.DecodedKey(); len(key) <= maxOrderingKey {
+			message.OrderingKey = key
+		}
+	}
+	_, err = s.svc.Projects.Topics.Publish(s.topic, &pubsub.PublishRequest{
+		Messages: []*pubsub.PubsubMessage{message},
+	}).Context(ctx).Do()
+	return err
+}
+
+// Close releases nothing; the HTTP client is shared.
+func (s *PubSubSink) Close() error {
+	return nil
+}
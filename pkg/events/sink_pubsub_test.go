@@ -0,0 +1,77 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build gcpstorage
+
+package events
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"google.golang.org/api/pubsub/v1"
+)
+
+func TestPubSubSink(t *testing.T) {
+	var path string
+	var request pubsub.PublishRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+		_, _ = w.Write([]byte(`{"messageIds":["1"]}`))
+	}))
+	defer server.Close()
+
+	sink, err := NewPubSubSink(map[string]string{"project": "acme", "topic": "objects", "endpoint": server.URL + "/"})
+	if err != nil {
+		t.Fatalf("NewPubSubSink: %v", err)
+	}
+	defer func() { _ = sink.Close() }()
+	if err := sink.Publish(context.Background(), testPubSubNotification()); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	if path != "/v1/projects/acme/topics/objects:publish" {
+		t.Errorf("published to %s", path)
+	}
+	message := request.Messages[0]
+	if message.Attributes["eventName"] != EventObjectCreatedPut || message.Attributes["key"] != "images/my cat.jpg" {
+		t.Errorf("attributes = %v", message.Attributes)
+	}
+	data, err := base64.StdEncoding.DecodeString(message.Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var n Notification
+	if err := json.Unmarshal(data, &n); err != nil || n.Records[0].S3.Object.Key != "images/my+cat.jpg" {
+		t.Errorf("data = %s (%v)", data, err)
+	}
+
+	if _, err := NewPubSubSink(map[string]string{"topic": "objects"}); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("NewPubSubSink without project = %v, want ErrInvalidConfig", err)
+	}
+}
+
+func testPubSubNotification() *Notification {
+	n := &Notification{}
+	n.Records = append(n.Records, newRecord(context.Background(), "media", "", EventObjectCreatedPut, "images/my cat.jpg", 4, "e", "1", time.Time{}))
+	return n
+}
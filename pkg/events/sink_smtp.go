@@ -0,0 +1,207 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package events
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// SMTP connection security, the "security" setting of the SMTP sink.
+const (
+	// SMTPStartTLS upgrades the connection with STARTTLS and fails if the
+	// server does not offer it.
+	SMTPStartTLS = "starttls"
+
+	// SMTPTLS connects with TLS from the start, usually on port 465.
+	SMTPTLS = "tls"
+
+	// SMTPNone sends in plain text, for relays on a trusted network.
+	SMTPNone = "none"
+)
+
+func init() {
+	RegisterSink("smtp", NewSMTPSink)
+}
+
+// SMTPSink emails a message per event through an SMTP server.
+type SMTPSink struct {
+	addr     string
+	host     string
+	security string
+	from     string
+	to       []string
+	auth     smtp.Auth
+	now      func() time.Time
+}
+
+// NewSMTPSink creates an SMTP sink from its settings:
+//
+//   - host: the SMTP server (required)
+//   - port: the server port (default 587, or 465 with tls security)
+//   - security: starttls (default), tls or none
+//   - from: the sender address (required)
+//   - to: comma-separated recipient addresses (required)
+//   - username, password: PLAIN authentication credentials
+func NewSMTPSink(settings map[string]string) (Sink, error) {
+	host := settings["host"]
+	if host == "" || settings["from"] == "" || settings["to"] == "" {
+		return nil, fmt.Errorf("%w: smtp sink requires host, from and to", ErrInvalidConfig)
+	}
+	security := settings["security"]
+	if security == "" {
+		security = SMTPStartTLS
+	}
+	port := settings["port"]
+	switch security {
+	case SMTPStartTLS, SMTPNone:
+		if port == "" {
+			port = "587"
+		}
+	case SMTPTLS:
+		if port == "" {
+			port = "465"
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown smtp security %q", ErrInvalidConfig, security)
+	}
+
+	from, err := mail.ParseAddress(settings["from"])
+	if err != nil {
+		return nil, fmt.Errorf("%w: smtp from: %w", ErrInvalidConfig, err)
+	}
+	recipients, err := mail.ParseAddressList(settings["to"])
+	if err != nil {
+		return nil, fmt.Errorf("%w: smtp to: %w", ErrInvalidConfig, err)
+	}
+	s := &SMTPSink{
+		addr:     net.JoinHostPort(host, port),
+		host:     host,
+		security: security,
+		from:     from.Address,
+		now:      time.Now,
+	}
+	for _, r := range recipients {
+		s.to = append(s.to, r.Address)
+	}
+	if username := settings["username"]; username != "" {
+		// PlainAuth refuses to send the password over an unencrypted
+		// connection to anything but localhost.
+		s.auth = smtp.PlainAuth("", username, settings["password"], host)
+	}
+	return s, nil
+}
+
+// Publish sends an email for each record.
+func (s *SMTPSink) Publish(ctx context.Context, n *Notification) error {
+	for i := range n.Records {
+		if err := s.send(ctx, s.compose(newMessage(&n.Records[i]))); err != nil {
+			return fmt.Errorf("smtp: %w", err)
+		}
+	}
+	return nil
+}
+
+// compose renders m as a plain-text email.
+func (s *SMTPSink) compose(m message) []byte {
+	var body bytes.Buffer
+	qp := quotedprintable.NewWriter(&body)
+	_, _ = qp.Write([]byte(m.text()))
+	_ = qp.Close()
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", s.from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(s.to, ", "))
+	// Q-encoding also encodes line breaks, so keys cannot inject headers.
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", m.title))
+	fmt.Fprintf(&b, "Date: %s\r\n", s.now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+	b.Write(body.Bytes())
+	return b.Bytes()
+}
+
+// send delivers msg in one SMTP session, which ctx bounds.
+func (s *SMTPSink) send(ctx context.Context, msg []byte) error {
+	tlsConfig := &tls.Config{ServerName: s.host, MinVersion: tls.VersionTLS12}
+	var conn net.Conn
+	var err error
+	if s.security == SMTPTLS {
+		conn, err = (&tls.Dialer{Config: tlsConfig}).DialContext(ctx, "tcp", s.addr)
+	} else {
+		conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", s.addr)
+	}
+	if err != nil {
+		return err
+	}
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	c, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+	defer func() { _ = c.Close() }()
+	if s.security == SMTPStartTLS {
+		if ok, _ := c.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("%s does not support STARTTLS", s.addr)
+		}
+		if err := c.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+	if s.auth != nil {
+		if err := c.Auth(s.auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(s.from); err != nil {
+		return err
+	}
+	for _, to := range s.to {
+		if err := c.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// Close releases nothing; every delivery uses its own connection.
+func (s *SMTPSink) Close() error {
+	return nil
+}
@@ -0,0 +1,196 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// smtpServer is a minimal SMTP server that accepts every message and
+// records the session.
+type smtpServer struct {
+	listener net.Listener
+	mu       sync.Mutex
+	auth     string
+	rcpt     []string
+	data     []string
+}
+
+func newSMTPServer(t *testing.T) *smtpServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &smtpServer{listener: listener}
+	t.Cleanup(func() { _ = listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *smtpServer) port() string {
+	_, port, _ := net.SplitHostPort(s.listener.Addr().String())
+	return port
+}
+
+func (s *smtpServer) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = io.WriteString(conn, line+"\r\n") }
+	reply("220 localhost ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		s.mu.Lock()
+		switch verb {
+		case "EHLO":
+			reply("250-localhost")
+			reply("250 AUTH PLAIN")
+		case "AUTH":
+			s.auth = line
+			reply("235 authenticated")
+		case "MAIL":
+			reply("250 ok")
+		case "RCPT":
+			s.rcpt = append(s.rcpt, line)
+			reply("250 ok")
+		case "DATA":
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil || l == ".\r\n" {
+					break
+				}
+				data.WriteString(l)
+			}
+			s.data = append(s.data, data.String())
+			reply("250 queued")
+		case "QUIT":
+			reply("221 bye")
+			s.mu.Unlock()
+			return
+		default:
+			reply("502 unsupported")
+		}
+		s.mu.Unlock()
+	}
+}
+
+func TestSMTPSink(t *testing.T) {
+	server := newSMTPServer(t)
+	sink, err := NewSMTPSink(map[string]string{
+		"host":     "127.0.0.1",
+		"port":     server.port(),
+		"security": SMTPNone,
+		"from":     "objstore <objstore@example.com>",
+		"to":       "ops@example.com, oncall@example.com",
+		"username": "objstore",
+		"password": "secret",
+	})
+	if err != nil {
+		t.Fatalf("NewSMTPSink: %v", err)
+	}
+	defer func() { _ = sink.Close() }()
+	n := testAlert()
+	n.Records[0].S3.Object.Key = encodeKey("evil\r\nBcc: victim@example.com")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := sink.Publish(ctx, n); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	wantAuth := "AUTH PLAIN " + base64.StdEncoding.EncodeToString([]byte("\x00objstore\x00secret"))
+	if server.auth != wantAuth {
+		t.Errorf("auth = %q, want %q", server.auth, wantAuth)
+	}
+	if len(server.rcpt) != 2 || !strings.Contains(server.rcpt[1], "oncall@example.com") {
+		t.Errorf("recipients = %v", server.rcpt)
+	}
+	if len(server.data) != 1 {
+		t.Fatalf("got %d messages, want 1", len(server.data))
+	}
+	msg, err := mail.ReadMessage(strings.NewReader(server.data[0]))
+	if err != nil {
+		t.Fatalf("ReadMessage: %v", err)
+	}
+	if msg.Header.Get("Bcc") != "" {
+		t.Errorf("key injected a header: %v", msg.Header)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil || !strings.HasPrefix(subject, "[media] Policy:Failed: evil") {
+		t.Errorf("subject = %q (%v)", subject, err)
+	}
+	body, _ := io.ReadAll(quotedprintable.NewReader(msg.Body))
+	if !strings.Contains(string(body), "error: archive failed") {
+		t.Errorf("body = %q", body)
+	}
+}
+
+func TestSMTPSinkRequiresStartTLS(t *testing.T) {
+	server := newSMTPServer(t)
+	sink, err := NewSMTPSink(map[string]string{
+		"host": "127.0.0.1", "port": server.port(), "from": "objstore@example.com", "to": "ops@example.com",
+	})
+	if err != nil {
+		t.Fatalf("NewSMTPSink: %v", err)
+	}
+	err = sink.Publish(context.Background(), testAlert())
+	if err == nil || !strings.Contains(err.Error(), "STARTTLS") {
+		t.Errorf("Publish error = %v, want STARTTLS required", err)
+	}
+}
+
+func TestSMTPSinkConfig(t *testing.T) {
+	for name, settings := range map[string]map[string]string{
+		"no host":      {"from": "a@example.com", "to": "b@example.com"},
+		"no to":        {"host": "mail", "from": "a@example.com"},
+		"bad from":     {"host": "mail", "from": "not an address", "to": "b@example.com"},
+		"bad security": {"host": "mail", "from": "a@example.com", "to": "b@example.com", "security": "ssl"},
+	} {
+		if _, err := NewSMTPSink(settings); !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("%s: NewSMTPSink = %v, want ErrInvalidConfig", name, err)
+		}
+	}
+	sink, err := NewSMTPSink(map[string]string{"host": "mail", "from": "a@example.com", "to": "b@example.com", "security": SMTPTLS})
+	if err != nil || sink.(*SMTPSink).addr != "mail:465" {
+		t.Errorf("NewSMTPSink(tls) = %+v, %v, want port 465", sink, err)
+	}
+}
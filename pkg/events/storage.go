@@ -0,0 +1,164 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package events
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// Storage wraps a backend and notifies a Notifier of every successful
+// change made through it, and of writes refused by a quota or rate limit.
+type Storage struct {
+	common.Storage
+	notifier *Notifier
+}
+
+// NewStorage returns underlying wrapped so that its changes are published
+// by notifier.
+func NewStorage(underlying common.Storage, notifier *Notifier) *Storage {
+	return &Storage{Storage: underlying, notifier: notifier}
+}
+
+// Notifier returns the notifier s publishes changes to.
+func (s *Storage) Notifier() *Notifier {
+	return s.notifier
+}
+
+// Underlying returns the wrapped backend.
+func (s *Storage) Underlying() common.Storage {
+	return s.Storage
+}
+
+// created publishes eventName for key with the object's current size and
+// ETag, if they can be read.
+func (s *Storage) created(ctx context.Context, eventName, key string) {
+	var size int64
+	var etag string
+	if metadata, err := s.Storage.GetMetadata(ctx, key); err == nil && metadata != nil {
+		size, etag = metadata.Size, metadata.ETag
+	}
+	s.notifier.Notify(ctx, eventName, key, size, etag)
+}
+
+// refused publishes Quota:Exceeded for key when err reports an exhausted
+// quota or rate limit, and returns err.
+func (s *Storage) refused(ctx context.Context, key string, err error) error {
+	if errors.Is(err, common.ErrResourceExhausted) {
+		s.notifier.Alert(ctx, EventQuotaExceeded, key, map[string]string{DetailError: err.Error()})
+	}
+	return err
+}
+
+// Put stores an object and publishes ObjectCreated:Put.
+func (s *Storage) Put(key string, data io.Reader) error {
+	return s.PutWithContext(context.Background(), key, data)
+}
+
+// PutWithContext stores an object and publishes ObjectCreated:Put.
+func (s *Storage) PutWithContext(ctx context.Context, key string, data io.Reader) error {
+	if err := s.Storage.PutWithContext(ctx, key, data); err != nil {
+		return s.refused(ctx, key, err)
+	}
+	s.created(ctx, EventObjectCreatedPut, key)
+	return nil
+}
+
+// PutWithMetadata stores an object with metadata and publishes
+// ObjectCreated:Put.
+func (s *Storage) PutWithMetadata(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) error {
+	if err := s.Storage.PutWithMetadata(ctx, key, data, metadata); err != nil {
+		return s.refused(ctx, key, err)
+	}
+	s.created(ctx, EventObjectCreatedPut, key)
+	return nil
+}
+
+// PutWithInfo stores an object with metadata, publishes ObjectCreated:Put
+// and returns what was stored.
+func (s *Storage) PutWithInfo(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) (*common.ObjectInfo, error) {
+	info, err := common.PutWithInfo(ctx, s.Storage, key, data, metadata)
+	if err != nil {
+		return nil, s.refused(ctx, key, err)
+	}
+	s.created(ctx, EventObjectCreatedPut, key)
+	return info, nil
+}
+
+// GetRange reads a byte range of an object, falling back to discarding
+// the leading bytes of a full read when the wrapped backend cannot read
+// ranges.
+func (s *Storage) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	if rr, ok := s.Storage.(common.RangeReader); ok {
+		return rr.GetRange(ctx, key, offset, length)
+	}
+	rc, err := s.Storage.GetWithContext(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return common.SliceRange(rc, offset, length)
+}
+
+// GetAsOf reads an earlier version of an object from the wrapped backend.
+func (s *Storage) GetAsOf(ctx context.Context, key string, at time.Time) (io.ReadCloser, *common.Metadata, error) {
+	return common.GetAsOf(ctx, s.Storage, key, at)
+}
+
+// UpdateMetadata updates an object's metadata and publishes
+// ObjectCreated:Copy, as S3 does for metadata changes.
+func (s *Storage) UpdateMetadata(ctx context.Context, key string, metadata *common.Metadata) error {
+	if err := s.Storage.UpdateMetadata(ctx, key, metadata); err != nil {
+		return s.refused(ctx, key, err)
+	}
+	s.created(ctx, EventObjectCreatedCopy, key)
+	return nil
+}
+
+// Delete removes an object and publishes ObjectRemoved:Delete.
+func (s *Storage) Delete(key string) error {
+	return s.DeleteWithContext(context.Background(), key)
+}
+
+// DeleteWithContext removes an object and publishes ObjectRemoved:Delete.
+func (s *Storage) DeleteWithContext(ctx context.Context, key string) error {
+	if err := s.Storage.DeleteWithContext(ctx, key); err != nil {
+		return err
+	}
+	s.notifier.Notify(ctx, EventObjectRemovedDelete, key, 0, "")
+	return nil
+}
+
+// Append adds data to the end of an object and publishes
+// ObjectCreated:Put.
+func (s *Storage) Append(ctx context.Context, key string, data io.Reader) error {
+	if err := common.Append(ctx, s.Storage, key, data); err != nil {
+		return s.refused(ctx, key, err)
+	}
+	s.created(ctx, EventObjectCreatedPut, key)
+	return nil
+}
+
+// Compose concatenates srcKeys into destKey and publishes
+// ObjectCreated:CompleteMultipartUpload.
+func (s *Storage) Compose(ctx context.Context, destKey string, srcKeys ...string) error {
+	if err := common.Compose(ctx, s.Storage, destKey, srcKeys...); err != nil {
+		return s.refused(ctx, destKey, err)
+	}
+	s.created(ctx, EventObjectCreatedCompleteMultipartUpload, destKey)
+	return nil
+}
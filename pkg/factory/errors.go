@@ -25,6 +25,27 @@ var (
 	// ErrUnknownArchiver is returned when an unknown archiver type is specified.
 	ErrUnknownArchiver = errors.New("unknown archiver type")
 
+	// ErrInvalidBackendType is returned when registering a backend without a type name.
+	ErrInvalidBackendType = errors.New("invalid backend type")
+
+	// ErrInvalidConstructor is returned when Register is given something other than
+	// a StorageCreator or ArchiverCreator.
+	ErrInvalidConstructor = errors.New("invalid backend constructor")
+
+	// ErrBackendRegistered is returned when Register is given a type that is already registered.
+	ErrBackendRegistered = errors.New("backend type already registered")
+
+	// ErrInvalidErasureBackends is returned when the backend.<i>.* settings of an
+	// erasure backend are malformed or incomplete.
+	ErrInvalidErasureBackends = errors.New("invalid erasure backend settings")
+
+	// ErrInvalidSetting is returned when a backend setting cannot be parsed
+	// or read.
+	ErrInvalidSetting = errors.New("invalid backend setting")
+
+	// ErrPluginLoad is returned when a backend plugin cannot be opened.
+	ErrPluginLoad = errors.New("failed to load backend plugin")
+
 	// ErrTypeAssertionFailed is returned when a type assertion fails.
 	ErrTypeAssertionFailed = errors.New("type assertion failed")
 )
@@ -14,7 +14,13 @@
 package factory
 
 import (
+	"context"
+	"fmt"
+	"plugin"
+	"sync"
+
 	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/secrets"
 )
 
 // StorageCreator is a function that creates a storage backend.
@@ -24,6 +30,7 @@ type StorageCreator func(settings map[string]string) (common.Storage, error)
 type ArchiverCreator func(settings map[string]string) (common.Archiver, error)
 
 var (
+	registryMu       sync.RWMutex
 	storageRegistry  = make(map[string]StorageCreator)
 	archiverRegistry = make(map[string]ArchiverCreator)
 	archiveOnlyTypes = map[string]bool{
@@ -32,42 +39,136 @@ var (
 	}
 )
 
-// RegisterStorage registers a storage backend creator.
+// RegisterStorage registers a storage backend creator, replacing any
+// creator already registered for the type.
 func RegisterStorage(backendType string, creator StorageCreator) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
 	storageRegistry[backendType] = creator
 }
 
-// RegisterArchiver registers an archiver creator.
+// RegisterArchiver registers an archiver creator, replacing any creator
+// already registered for the type.
 func RegisterArchiver(backendType string, creator ArchiverCreator) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
 	archiverRegistry[backendType] = creator
 }
 
+// Register registers a backend type contributed by an external module,
+// typically from the module's init function so that a blank import, a
+// build-tagged file or a plugin loaded with LoadPlugin makes it available
+// to NewStorage and NewArchiver. The constructor is a StorageCreator or an
+// ArchiverCreator, or a function with either signature. A storage backend
+// is also registered as an archiver, since every Storage can receive
+// archived objects.
+//
+// Unlike RegisterStorage and RegisterArchiver, Register refuses to replace
+// a type that is already registered, so a module cannot silently take over
+// a built-in backend.
+//
+// Example usage:
+//
+//	func init() {
+//	    factory.Register("mystore", func(settings map[string]string) (common.Storage, error) {
+//	        s := mystore.New()
+//	        return s, s.Configure(settings)
+//	    })
+//	}
+func Register(backendType string, constructor any) error {
+	if backendType == "" {
+		return ErrInvalidBackendType
+	}
+
+	var storage StorageCreator
+	var archiver ArchiverCreator
+	switch c := constructor.(type) {
+	case StorageCreator:
+		storage = c
+	case func(map[string]string) (common.Storage, error):
+		storage = c
+	case ArchiverCreator:
+		archiver = c
+	case func(map[string]string) (common.Archiver, error):
+		archiver = c
+	}
+	if storage == nil && archiver == nil {
+		return fmt.Errorf("%w: %T", ErrInvalidConstructor, constructor)
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	_, storageExists := storageRegistry[backendType]
+	_, archiverExists := archiverRegistry[backendType]
+	if storageExists || archiverExists {
+		return fmt.Errorf("%w: %s", ErrBackendRegistered, backendType)
+	}
+	if storage != nil {
+		storageRegistry[backendType] = storage
+		archiver = func(settings map[string]string) (common.Archiver, error) {
+			return storage(settings)
+		}
+	} else {
+		archiveOnlyTypes[backendType] = true
+	}
+	archiverRegistry[backendType] = archiver
+	return nil
+}
+
+// LoadPlugin opens a Go plugin built with -buildmode=plugin. The plugin's
+// init functions run as it is opened and register its backends with
+// Register. Plugins must be built with the same Go version and versions of
+// this module as the program loading them.
+func LoadPlugin(path string) error {
+	if _, err := plugin.Open(path); err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrPluginLoad, path, err)
+	}
+	return nil
+}
+
 // NewStorage creates a new storage backend based on the given type.
+// Settings referencing secrets (see package secrets) are resolved first.
 func NewStorage(backendType string, settings map[string]string) (common.Storage, error) {
+	registryMu.RLock()
+	archiveOnly := archiveOnlyTypes[backendType]
+	creator, exists := storageRegistry[backendType]
+	registryMu.RUnlock()
+
 	// Check if this is an archive-only backend
-	if archiveOnlyTypes[backendType] {
+	if archiveOnly {
 		return nil, ErrArchiveOnlyBackend
 	}
-
-	creator, exists := storageRegistry[backendType]
 	if !exists {
 		return nil, ErrUnknownBackend
 	}
+	settings, err := secrets.ResolveSettings(context.Background(), settings)
+	if err != nil {
+		return nil, err
+	}
 	return creator(settings)
 }
 
-// NewArchiver creates a new archiver based on the given type.
+// NewArchiver creates a new archiver based on the given type. Settings
+// referencing secrets are resolved first.
 func NewArchiver(backendType string, settings map[string]string) (common.Archiver, error) {
+	registryMu.RLock()
 	creator, exists := archiverRegistry[backendType]
+	registryMu.RUnlock()
 	if !exists {
 		return nil, ErrUnknownArchiver
 	}
+	settings, err := secrets.ResolveSettings(context.Background(), settings)
+	if err != nil {
+		return nil, err
+	}
 	return creator(settings)
 }
 
 // ListStorageBackends returns a list of all registered storage backend types.
 // Archive-only backends (glacier, azurearchive) are excluded from this list.
 func ListStorageBackends() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
 	backends := make([]string, 0, len(storageRegistry))
 	for backendType := range storageRegistry {
 		if !archiveOnlyTypes[backendType] {
@@ -79,6 +180,8 @@ func ListStorageBackends() []string {
 
 // ListArchivers returns a list of all registered archiver types.
 func ListArchivers() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
 	archivers := make([]string, 0, len(archiverRegistry))
 	for archiverType := range archiverRegistry {
 		archivers = append(archivers, archiverType)
@@ -88,12 +191,16 @@ func ListArchivers() []string {
 
 // IsStorageBackendRegistered checks if a storage backend type is registered.
 func IsStorageBackendRegistered(backendType string) bool {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
 	_, exists := storageRegistry[backendType]
 	return exists && !archiveOnlyTypes[backendType]
 }
 
 // IsArchiverRegistered checks if an archiver type is registered.
 func IsArchiverRegistered(archiverType string) bool {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
 	_, exists := archiverRegistry[archiverType]
 	return exists
 }
@@ -0,0 +1,81 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package factory
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/erasure"
+)
+
+func init() {
+	RegisterStorage("erasure", func(settings map[string]string) (common.Storage, error) {
+		backends, err := erasureBackends(settings)
+		if err != nil {
+			return nil, err
+		}
+		storage, err := erasure.New(backends, nil)
+		if err != nil {
+			return nil, err
+		}
+		if err := storage.Configure(settings); err != nil {
+			return nil, err
+		}
+		return storage, nil
+	})
+}
+
+// erasureBackends creates the shard backends of an erasure storage. Shard
+// i goes to a backend of type "backend.<i>.type" configured with the
+// "backend.<i>.<setting>" settings, for example "backend.0.path".
+func erasureBackends(settings map[string]string) ([]common.Storage, error) {
+	shards := make(map[int]map[string]string)
+	for key, value := range settings {
+		rest, ok := strings.CutPrefix(key, "backend.")
+		if !ok {
+			continue
+		}
+		index, name, ok := strings.Cut(rest, ".")
+		i, err := strconv.Atoi(index)
+		if !ok || err != nil || i < 0 || name == "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidErasureBackends, key)
+		}
+		if shards[i] == nil {
+			shards[i] = make(map[string]string)
+		}
+		shards[i][name] = value
+	}
+
+	backends := make([]common.Storage, len(shards))
+	for i := range backends {
+		shard, ok := shards[i]
+		if !ok {
+			return nil, fmt.Errorf("%w: no settings for backend %d", ErrInvalidErasureBackends, i)
+		}
+		backendType := shard["type"]
+		if backendType == "" {
+			return nil, fmt.Errorf("%w: backend.%d.type not set", ErrInvalidErasureBackends, i)
+		}
+		delete(shard, "type")
+		backend, err := NewStorage(backendType, shard)
+		if err != nil {
+			return nil, fmt.Errorf("erasure backend %d: %w", i, err)
+		}
+		backends[i] = backend
+	}
+	return backends, nil
+}
@@ -20,6 +20,10 @@ import (
 	"io/ioutil"
 	"os"
 	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+	"github.com/jeremyhahn/go-objstore/pkg/storagetest"
 )
 
 // Test error variable
@@ -529,6 +533,7 @@ func TestFactory_NewStorage_ConfigureErrors(t *testing.T) {
 		{"gcs missing bucket", "gcs", map[string]string{}},
 		{"azure missing account", "azure", map[string]string{"containerName": "test"}},
 		{"local missing path", "local", map[string]string{}},
+		{"ipfs missing path", "ipfs", map[string]string{"endpoint": "http://127.0.0.1:5001"}},
 		{"minio missing bucket", "minio", map[string]string{"endpoint": "http://localhost:9000", "accessKey": "minioadmin", "secretKey": "minioadmin"}},
 		{"minio missing endpoint", "minio", map[string]string{"bucket": "test-bucket", "accessKey": "minioadmin", "secretKey": "minioadmin"}},
 		{"minio missing accessKey", "minio", map[string]string{"bucket": "test-bucket", "endpoint": "http://localhost:9000", "secretKey": "minioadmin"}},
@@ -545,6 +550,55 @@ func TestFactory_NewStorage_ConfigureErrors(t *testing.T) {
 	}
 }
 
+func TestFactory_NewStorage_Erasure(t *testing.T) {
+	dir := t.TempDir()
+	settings := map[string]string{
+		"parityShards":   "1",
+		"backend.0.type": "local",
+		"backend.0.path": dir,
+		"backend.1.type": "memory",
+		"backend.2.type": "memory",
+	}
+	storage, err := NewStorage("erasure", settings)
+	if err != nil {
+		t.Fatalf("NewStorage(erasure) error = %v", err)
+	}
+	if err := storage.Put("key", bytes.NewReader([]byte("erasure coded"))); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if _, err := os.Stat(dir + "/key"); err != nil {
+		t.Errorf("shard 0 not written to the local backend: %v", err)
+	}
+	rc, err := storage.Get("key")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	defer rc.Close()
+	if data, _ := io.ReadAll(rc); string(data) != "erasure coded" {
+		t.Errorf("Get() = %q", data)
+	}
+
+	tests := []struct {
+		name     string
+		settings map[string]string
+		wantErr  error
+	}{
+		{"bad index", map[string]string{"backend.x.type": "memory"}, ErrInvalidErasureBackends},
+		{"missing index", map[string]string{"backend.0.type": "memory", "backend.2.type": "memory"}, ErrInvalidErasureBackends},
+		{"missing type", map[string]string{"backend.0.type": "memory", "backend.1.path": dir}, ErrInvalidErasureBackends},
+		{"unknown type", map[string]string{"backend.0.type": "memory", "backend.1.type": "nope"}, ErrUnknownBackend},
+		{"no backends", map[string]string{}, common.ErrInvalidArgument},
+		{"bad layout", map[string]string{"backend.0.type": "memory", "backend.1.type": "memory", "dataShards": "2"}, common.ErrInvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewStorage("erasure", tt.settings); !errors.Is(err, tt.wantErr) {
+				t.Errorf("NewStorage(erasure) error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestFactory_NewArchiver_Glacier(t *testing.T) {
 	archiver, err := NewArchiver("glacier", map[string]string{
 		"vaultName": "test-vault",
@@ -617,6 +671,7 @@ func TestFactory_NewArchiver_ConfigureErrors(t *testing.T) {
 		settings map[string]string
 	}{
 		{"local missing path", "local", map[string]string{}},
+		{"ipfs missing path", "ipfs", map[string]string{"endpoint": "http://127.0.0.1:5001"}},
 		{"glacier missing vault", "glacier", map[string]string{"region": "us-east-1"}},
 		{"azurearchive missing account", "azurearchive", map[string]string{"containerName": "test"}},
 	}
@@ -630,3 +685,72 @@ func TestFactory_NewArchiver_ConfigureErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestFactory_Register(t *testing.T) {
+	t.Cleanup(func() {
+		registryMu.Lock()
+		defer registryMu.Unlock()
+		for _, backendType := range []string{"thirdparty", "thirdparty-archive"} {
+			delete(storageRegistry, backendType)
+			delete(archiverRegistry, backendType)
+			delete(archiveOnlyTypes, backendType)
+		}
+	})
+
+	err := Register("thirdparty", func(settings map[string]string) (common.Storage, error) {
+		storage := memory.New()
+		return storage, storage.Configure(settings)
+	})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if !IsStorageBackendRegistered("thirdparty") || !IsArchiverRegistered("thirdparty") {
+		t.Error("registered storage backend is not available as storage and archiver")
+	}
+	storagetest.TestStorage(t, func(t *testing.T) common.Storage {
+		storage, err := NewStorage("thirdparty", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return storage
+	})
+
+	err = Register("thirdparty-archive", ArchiverCreator(func(map[string]string) (common.Archiver, error) {
+		return memory.New(), nil
+	}))
+	if err != nil {
+		t.Fatalf("Register(archiver) error = %v", err)
+	}
+	if _, err := NewStorage("thirdparty-archive", nil); !errors.Is(err, ErrArchiveOnlyBackend) {
+		t.Errorf("NewStorage(archiver) error = %v, want ErrArchiveOnlyBackend", err)
+	}
+	if _, err := NewArchiver("thirdparty-archive", nil); err != nil {
+		t.Errorf("NewArchiver() error = %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		backendType string
+		constructor any
+		wantErr     error
+	}{
+		{"duplicate", "thirdparty", func(map[string]string) (common.Storage, error) { return nil, nil }, ErrBackendRegistered},
+		{"nil creator", "other", StorageCreator(nil), ErrInvalidConstructor},
+		{"built-in", "local", func(map[string]string) (common.Storage, error) { return nil, nil }, ErrBackendRegistered},
+		{"empty type", "", func(map[string]string) (common.Storage, error) { return nil, nil }, ErrInvalidBackendType},
+		{"wrong signature", "other", func() common.Storage { return nil }, ErrInvalidConstructor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Register(tt.backendType, tt.constructor); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Register() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFactory_LoadPlugin_Error(t *testing.T) {
+	if err := LoadPlugin("/nonexistent/backend.so"); !errors.Is(err, ErrPluginLoad) {
+		t.Errorf("LoadPlugin() error = %v, want ErrPluginLoad", err)
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package factory
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ParseSetting splits a "key=value" backend setting.
+func ParseSetting(s string) (key, value string, err error) {
+	key, value, ok := strings.Cut(s, "=")
+	if !ok || key == "" {
+		return "", "", fmt.Errorf("%w: %q is not key=value", ErrInvalidSetting, s)
+	}
+	return key, value, nil
+}
+
+// LoadSettingsDir reads backend settings from a directory holding one file
+// per setting, named after it, such as a mounted Kubernetes Secret. Hidden
+// entries, including the "..data" links Kubernetes uses to swap secret
+// contents atomically, are skipped, and a trailing newline is removed from
+// each value.
+func LoadSettingsDir(dir string) (map[string]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSetting, err)
+	}
+	settings := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidSetting, err)
+		}
+		if info.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(path) // #nosec G304 -- directory supplied by the operator
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidSetting, err)
+		}
+		settings[entry.Name()] = strings.TrimSuffix(strings.TrimSuffix(string(data), "\n"), "\r")
+	}
+	return settings, nil
+}
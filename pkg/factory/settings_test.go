@@ -0,0 +1,65 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package factory
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseSetting(t *testing.T) {
+	key, value, err := ParseSetting("endpoint=http://minio:9000/?a=b")
+	if err != nil || key != "endpoint" || value != "http://minio:9000/?a=b" {
+		t.Errorf("ParseSetting() = %q, %q, %v", key, value, err)
+	}
+	for _, s := range []string{"bucket", "=value"} {
+		if _, _, err := ParseSetting(s); !errors.Is(err, ErrInvalidSetting) {
+			t.Errorf("ParseSetting(%q) error = %v, want ErrInvalidSetting", s, err)
+		}
+	}
+}
+
+func TestLoadSettingsDir(t *testing.T) {
+	dir := t.TempDir()
+	// Laid out like a mounted Kubernetes Secret
+	data := filepath.Join(dir, "..2025_01_01")
+	if err := os.Mkdir(data, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	for name, value := range map[string]string{"accessKey": "AKIA\n", "secretKey": "s3cr3t"} {
+		if err := os.WriteFile(filepath.Join(data, name), []byte(value), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(filepath.Join("..data", name), filepath.Join(dir, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink("..2025_01_01", filepath.Join(dir, "..data")); err != nil {
+		t.Fatal(err)
+	}
+
+	settings, err := LoadSettingsDir(dir)
+	if err != nil {
+		t.Fatalf("LoadSettingsDir() error = %v", err)
+	}
+	if len(settings) != 2 || settings["accessKey"] != "AKIA" || settings["secretKey"] != "s3cr3t" {
+		t.Errorf("LoadSettingsDir() = %v", settings)
+	}
+
+	if _, err := LoadSettingsDir(filepath.Join(dir, "missing")); !errors.Is(err, ErrInvalidSetting) {
+		t.Errorf("missing directory error = %v, want ErrInvalidSetting", err)
+	}
+}
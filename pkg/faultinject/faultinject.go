@@ -0,0 +1,443 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package faultinject wraps a storage backend to inject latency, errors,
+// truncated transfers and corrupted bytes, for testing how applications and
+// objstore's own retry, failover and replication logic cope with an
+// unreliable backend.
+//
+// Faults are drawn from a random source seeded by Config.Seed, so a
+// sequence of operations made one at a time sees the same faults on every
+// run. Concurrent operations draw in the order they arrive.
+//
+//	s := faultinject.New(memory.New(), &faultinject.Config{
+//	    Seed:    42,
+//	    Default: faultinject.Fault{ErrorRate: 0.1, Latency: 20 * time.Millisecond},
+//	    Ops: map[faultinject.Op]faultinject.Fault{
+//	        faultinject.OpGet: {CorruptRate: 0.05, PartialReadRate: 0.05},
+//	    },
+//	})
+package faultinject
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// ErrInjected is the error returned by injected failures unless
+// Fault.Err is set. It wraps common.ErrUnavailable, so code that retries
+// transient errors retries it.
+var ErrInjected = fmt.Errorf("injected fault: %w", common.ErrUnavailable)
+
+// Op names a storage operation faults are configured for.
+type Op string
+
+// Operations faults can be configured for.
+const (
+	OpPut            Op = "put"
+	OpGet            Op = "get"
+	OpGetMetadata    Op = "get_metadata"
+	OpUpdateMetadata Op = "update_metadata"
+	OpExists         Op = "exists"
+	OpDelete         Op = "delete"
+	OpList           Op = "list"
+	OpArchive        Op = "archive"
+)
+
+// Fault describes the faults injected into one kind of operation. Rates
+// are probabilities from 0 to 1.
+type Fault struct {
+	// Latency delays every operation. The delay ends early when the
+	// operation's context is canceled.
+	Latency time.Duration
+
+	// Jitter adds a random delay of up to this duration to Latency.
+	Jitter time.Duration
+
+	// ErrorRate is the probability that the operation fails before it
+	// reaches the backend.
+	ErrorRate float64
+
+	// Err is the error injected failures return (default ErrInjected).
+	Err error
+
+	// PartialReadRate is the probability that the data of a get, or of a
+	// put on its way to the backend, ends early with io.ErrUnexpectedEOF.
+	PartialReadRate float64
+
+	// CorruptRate is the probability that one byte of the data of a get,
+	// or of a put on its way to the backend, is flipped.
+	CorruptRate float64
+}
+
+// Config configures the faults a Storage injects.
+type Config struct {
+	// Seed seeds the random source faults are drawn from.
+	Seed int64
+
+	// Default is the fault of operations missing from Ops.
+	Default Fault
+
+	// Ops overrides Default for individual operations.
+	Ops map[Op]Fault
+
+	// Prefix limits faults to keys with this prefix; listings are affected
+	// when their prefix starts with it. Empty affects every key.
+	Prefix string
+}
+
+// Stats counts the faults a Storage has injected.
+type Stats struct {
+	Delays     int64 `json:"delays"`
+	Errors     int64 `json:"errors"`
+	Partials   int64 `json:"partials"`
+	Corruption int64 `json:"corruption"`
+}
+
+// Storage wraps a backend and injects the faults of its Config into the
+// operations made through it.
+type Storage struct {
+	common.Storage
+
+	mu      sync.Mutex
+	cfg     Config
+	rng     *rand.Rand
+	stats   Stats
+	enabled bool
+}
+
+// New returns underlying wrapped to inject the faults of cfg.
+func New(underlying common.Storage, cfg *Config) *Storage {
+	s := &Storage{Storage: underlying, enabled: true}
+	if cfg != nil {
+		s.cfg = *cfg
+	}
+	s.rng = rand.New(rand.NewSource(s.cfg.Seed)) // #nosec G404 -- deterministic faults, not security
+	return s
+}
+
+// Underlying returns the wrapped backend.
+func (s *Storage) Underlying() common.Storage {
+	return s.Storage
+}
+
+// SetEnabled turns fault injection on or off, so a test can set up and
+// verify its data through the same Storage. Injection starts enabled.
+func (s *Storage) SetEnabled(enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.enabled = enabled
+}
+
+// Stats returns the number of faults injected so far.
+func (s *Storage) Stats() Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stats
+}
+
+// plan is the faults drawn for one operation.
+type plan struct {
+	delay   time.Duration
+	err     error
+	partial bool
+	corrupt bool
+	// cut and flip choose, within the first chunk of data, the offset a
+	// partial read ends at and the byte that is corrupted. They are drawn
+	// with the other faults so the offsets are deterministic too.
+	cut  int64
+	flip int64
+}
+
+// draw decides the faults of an operation on key.
+func (s *Storage) draw(op Op, key string) plan {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.enabled || !strings.HasPrefix(key, s.cfg.Prefix) {
+		return plan{}
+	}
+	fault, ok := s.cfg.Ops[op]
+	if !ok {
+		fault = s.cfg.Default
+	}
+
+	var p plan
+	p.delay = fault.Latency
+	if fault.Jitter > 0 {
+		p.delay += time.Duration(s.rng.Int63n(int64(fault.Jitter)))
+	}
+	if p.delay > 0 {
+		s.stats.Delays++
+	}
+	if fault.ErrorRate > 0 && s.rng.Float64() < fault.ErrorRate {
+		s.stats.Errors++
+		p.err = fault.Err
+		if p.err == nil {
+			p.err = ErrInjected
+		}
+		return p
+	}
+	if fault.PartialReadRate > 0 && s.rng.Float64() < fault.PartialReadRate {
+		s.stats.Partials++
+		p.partial = true
+		p.cut = s.rng.Int63()
+	}
+	if fault.CorruptRate > 0 && s.rng.Float64() < fault.CorruptRate {
+		s.stats.Corruption++
+		p.corrupt = true
+		p.flip = s.rng.Int63()
+	}
+	return p
+}
+
+// inject waits out the delay of p and returns its injected error.
+func (p plan) inject(ctx context.Context) error {
+	if p.delay > 0 {
+		timer := time.NewTimer(p.delay)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return p.err
+}
+
+// wrap applies the partial read and corruption faults of p to r.
+func (p plan) wrap(r io.Reader) io.Reader {
+	if !p.partial && !p.corrupt {
+		return r
+	}
+	return &faultyReader{Reader: r, plan: p}
+}
+
+// faultyReader truncates or corrupts the first chunk of data read through
+// it.
+type faultyReader struct {
+	io.Reader
+	plan plan
+	done bool
+	eof  bool
+}
+
+func (r *faultyReader) Read(p []byte) (int, error) {
+	if r.eof {
+		return 0, io.ErrUnexpectedEOF
+	}
+	n, err := r.Reader.Read(p)
+	if n == 0 || r.done {
+		return n, err
+	}
+	r.done = true
+	if r.plan.partial {
+		r.eof = true
+		n = int(r.plan.cut % int64(n))
+		err = io.ErrUnexpectedEOF
+	}
+	if r.plan.corrupt && n > 0 {
+		p[r.plan.flip%int64(n)] ^= 0xff
+	}
+	return n, err
+}
+
+// faultyReadCloser closes the reader a faultyReader wraps.
+type faultyReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
+func (s *Storage) reader(p plan, rc io.ReadCloser) io.ReadCloser {
+	if !p.partial && !p.corrupt {
+		return rc
+	}
+	return faultyReadCloser{Reader: p.wrap(rc), Closer: rc}
+}
+
+// Put stores an object unless a fault is injected.
+func (s *Storage) Put(key string, data io.Reader) error {
+	return s.PutWithContext(context.Background(), key, data)
+}
+
+// PutWithContext stores an object unless a fault is injected.
+func (s *Storage) PutWithContext(ctx context.Context, key string, data io.Reader) error {
+	p := s.draw(OpPut, key)
+	if err := p.inject(ctx); err != nil {
+		return err
+	}
+	return s.Storage.PutWithContext(ctx, key, p.wrap(data))
+}
+
+// PutWithMetadata stores an object with metadata unless a fault is
+// injected.
+func (s *Storage) PutWithMetadata(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) error {
+	p := s.draw(OpPut, key)
+	if err := p.inject(ctx); err != nil {
+		return err
+	}
+	return s.Storage.PutWithMetadata(ctx, key, p.wrap(data), metadata)
+}
+
+// PutWithInfo stores an object with metadata unless a fault is injected,
+// and returns what was stored.
+func (s *Storage) PutWithInfo(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) (*common.ObjectInfo, error) {
+	p := s.draw(OpPut, key)
+	if err := p.inject(ctx); err != nil {
+		return nil, err
+	}
+	return common.PutWithInfo(ctx, s.Storage, key, p.wrap(data), metadata)
+}
+
+// Get retrieves an object unless a fault is injected.
+func (s *Storage) Get(key string) (io.ReadCloser, error) {
+	return s.GetWithContext(context.Background(), key)
+}
+
+// GetWithContext retrieves an object unless a fault is injected.
+func (s *Storage) GetWithContext(ctx context.Context, key string) (io.ReadCloser, error) {
+	p := s.draw(OpGet, key)
+	if err := p.inject(ctx); err != nil {
+		return nil, err
+	}
+	rc, err := s.Storage.GetWithContext(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return s.reader(p, rc), nil
+}
+
+// GetRange reads a byte range unless a fault is injected, falling back to
+// discarding the leading bytes of a full read when the backend cannot read
+// ranges. Range reads use the faults of OpGet.
+func (s *Storage) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	p := s.draw(OpGet, key)
+	if err := p.inject(ctx); err != nil {
+		return nil, err
+	}
+	if rr, ok := s.Storage.(common.RangeReader); ok {
+		rc, err := rr.GetRange(ctx, key, offset, length)
+		if err != nil {
+			return nil, err
+		}
+		return s.reader(p, rc), nil
+	}
+	rc, err := s.Storage.GetWithContext(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	sliced, err := common.SliceRange(rc, offset, length)
+	if err != nil {
+		return nil, err
+	}
+	return s.reader(p, sliced), nil
+}
+
+// GetAsOf reads an earlier version of an object unless a fault is
+// injected. As-of reads use the faults of OpGet.
+func (s *Storage) GetAsOf(ctx context.Context, key string, at time.Time) (io.ReadCloser, *common.Metadata, error) {
+	p := s.draw(OpGet, key)
+	if err := p.inject(ctx); err != nil {
+		return nil, nil, err
+	}
+	rc, metadata, err := common.GetAsOf(ctx, s.Storage, key, at)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.reader(p, rc), metadata, nil
+}
+
+// GetMetadata retrieves an object's metadata unless a fault is injected.
+func (s *Storage) GetMetadata(ctx context.Context, key string) (*common.Metadata, error) {
+	if err := s.draw(OpGetMetadata, key).inject(ctx); err != nil {
+		return nil, err
+	}
+	return s.Storage.GetMetadata(ctx, key)
+}
+
+// UpdateMetadata updates an object's metadata unless a fault is injected.
+func (s *Storage) UpdateMetadata(ctx context.Context, key string, metadata *common.Metadata) error {
+	if err := s.draw(OpUpdateMetadata, key).inject(ctx); err != nil {
+		return err
+	}
+	return s.Storage.UpdateMetadata(ctx, key, metadata)
+}
+
+// Exists checks whether an object exists unless a fault is injected.
+func (s *Storage) Exists(ctx context.Context, key string) (bool, error) {
+	if err := s.draw(OpExists, key).inject(ctx); err != nil {
+		return false, err
+	}
+	return s.Storage.Exists(ctx, key)
+}
+
+// Delete removes an object unless a fault is injected.
+func (s *Storage) Delete(key string) error {
+	return s.DeleteWithContext(context.Background(), key)
+}
+
+// DeleteWithContext removes an object unless a fault is injected.
+func (s *Storage) DeleteWithContext(ctx context.Context, key string) error {
+	if err := s.draw(OpDelete, key).inject(ctx); err != nil {
+		return err
+	}
+	return s.Storage.DeleteWithContext(ctx, key)
+}
+
+// List lists keys under prefix unless a fault is injected.
+func (s *Storage) List(prefix string) ([]string, error) {
+	return s.ListWithContext(context.Background(), prefix)
+}
+
+// ListWithContext lists keys under prefix unless a fault is injected.
+func (s *Storage) ListWithContext(ctx context.Context, prefix string) ([]string, error) {
+	if err := s.draw(OpList, prefix).inject(ctx); err != nil {
+		return nil, err
+	}
+	return s.Storage.ListWithContext(ctx, prefix)
+}
+
+// ListWithOptions lists a page of objects unless a fault is injected.
+func (s *Storage) ListWithOptions(ctx context.Context, opts *common.ListOptions) (*common.ListResult, error) {
+	prefix := ""
+	if opts != nil {
+		prefix = opts.Prefix
+	}
+	if err := s.draw(OpList, prefix).inject(ctx); err != nil {
+		return nil, err
+	}
+	return s.Storage.ListWithOptions(ctx, opts)
+}
+
+// Archive copies an object to destination unless a fault is injected. The
+// data is read through Get, so get faults apply to it as well.
+func (s *Storage) Archive(key string, destination common.Archiver) error {
+	if destination == nil {
+		return common.ErrArchiveDestinationNil
+	}
+	if err := s.draw(OpArchive, key).inject(context.Background()); err != nil {
+		return err
+	}
+	rc, err := s.Get(key)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	return destination.Put(key, rc)
+}
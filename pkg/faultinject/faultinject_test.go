@@ -0,0 +1,171 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package faultinject
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+	"github.com/jeremyhahn/go-objstore/pkg/storagetest"
+)
+
+func TestConformanceWithoutFaults(t *testing.T) {
+	storagetest.TestStorage(t, func(t *testing.T) common.Storage {
+		return New(memory.New(), nil)
+	})
+}
+
+// outcomes records which of n gets fail.
+func outcomes(s *Storage, n int) []bool {
+	failed := make([]bool, n)
+	for i := range failed {
+		rc, err := s.Get("key")
+		if err == nil {
+			rc.Close()
+		}
+		failed[i] = err != nil
+	}
+	return failed
+}
+
+func TestDeterministicErrors(t *testing.T) {
+	cfg := &Config{Seed: 7, Default: Fault{ErrorRate: 0.5}}
+	backend := memory.New()
+	if err := backend.Put("key", strings.NewReader("data")); err != nil {
+		t.Fatal(err)
+	}
+
+	first := New(backend, cfg)
+	a := outcomes(first, 50)
+	b := outcomes(New(backend, cfg), 50)
+	failures := 0
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("run with the same seed differs at get %d", i)
+		}
+		if a[i] {
+			failures++
+		}
+	}
+	if failures == 0 || failures == 50 {
+		t.Errorf("%d of 50 gets failed at a 50%% error rate", failures)
+	}
+	if got := first.Stats().Errors; got != int64(failures) {
+		t.Errorf("Stats().Errors = %d, want %d", got, failures)
+	}
+
+	_, err := New(backend, &Config{Default: Fault{ErrorRate: 1}}).Get("key")
+	if !errors.Is(err, ErrInjected) || !errors.Is(err, common.ErrUnavailable) {
+		t.Errorf("Get() error = %v, want ErrInjected wrapping common.ErrUnavailable", err)
+	}
+	custom := errors.New("disk on fire")
+	if _, err := New(backend, &Config{Default: Fault{ErrorRate: 1, Err: custom}}).Get("key"); err != custom {
+		t.Errorf("Get() error = %v, want the configured error", err)
+	}
+}
+
+func TestDataFaults(t *testing.T) {
+	backend := memory.New()
+	data := bytes.Repeat([]byte("abcdefgh"), 128)
+	if err := backend.Put("key", bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+
+	s := New(backend, &Config{Seed: 1, Ops: map[Op]Fault{OpGet: {CorruptRate: 1}}})
+	rc, err := s.Get("key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil || len(got) != len(data) {
+		t.Fatalf("corrupted read = %d bytes, %v; want all %d bytes", len(got), err, len(data))
+	}
+	diff := 0
+	for i := range got {
+		if got[i] != data[i] {
+			diff++
+		}
+	}
+	if diff != 1 {
+		t.Errorf("%d bytes differ, want exactly 1", diff)
+	}
+
+	s = New(backend, &Config{Seed: 1, Ops: map[Op]Fault{OpGet: {PartialReadRate: 1}}})
+	rc, err = s.Get("key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err = io.ReadAll(rc)
+	rc.Close()
+	if !errors.Is(err, io.ErrUnexpectedEOF) || len(got) >= len(data) || !bytes.Equal(got, data[:len(got)]) {
+		t.Errorf("partial read = %d bytes, %v; want an intact prefix and io.ErrUnexpectedEOF", len(got), err)
+	}
+
+	// Writes through the wrapper fail when their data is cut short.
+	if err := s.Put("other", bytes.NewReader(data)); err != nil {
+		t.Errorf("Put() error = %v, want OpGet faults to leave puts alone", err)
+	}
+	s = New(backend, &Config{Ops: map[Op]Fault{OpPut: {PartialReadRate: 1}}})
+	if err := s.Put("cut", bytes.NewReader(data)); err == nil {
+		t.Error("Put() with a partial upload succeeded")
+	}
+}
+
+func TestLatency(t *testing.T) {
+	s := New(memory.New(), &Config{Default: Fault{Latency: 20 * time.Millisecond}})
+	start := time.Now()
+	if _, err := s.Exists(context.Background(), "key"); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("Exists() took %v, want at least the configured latency", elapsed)
+	}
+
+	s = New(memory.New(), &Config{Default: Fault{Latency: time.Hour}})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := s.Exists(ctx, "key"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Exists() error = %v, want the delay to end with the context", err)
+	}
+}
+
+func TestScope(t *testing.T) {
+	s := New(memory.New(), &Config{Prefix: "flaky/", Default: Fault{ErrorRate: 1}})
+	if err := s.Put("stable/a", strings.NewReader("x")); err != nil {
+		t.Errorf("Put() outside the prefix error = %v", err)
+	}
+	if err := s.Put("flaky/a", strings.NewReader("x")); !errors.Is(err, ErrInjected) {
+		t.Errorf("Put() inside the prefix error = %v, want ErrInjected", err)
+	}
+
+	s.SetEnabled(false)
+	if err := s.Put("flaky/a", strings.NewReader("x")); err != nil {
+		t.Errorf("Put() with injection disabled error = %v", err)
+	}
+	s.SetEnabled(true)
+	if _, err := s.List("flaky/"); !errors.Is(err, ErrInjected) {
+		t.Errorf("List() error = %v, want ErrInjected", err)
+	}
+	if got := s.Stats().Errors; got != 2 {
+		t.Errorf("Stats().Errors = %d, want 2", got)
+	}
+}
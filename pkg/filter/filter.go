@@ -0,0 +1,446 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package filter transforms object content as it is read, so a client can
+// extract a small part of an object without downloading all of it. Filters
+// decompress the object, select a range of lines of text, or run a jq query
+// over JSON, and are applied in that order. Decompression and line ranges
+// stream without buffering and stop reading the object once the last
+// requested line has been read; jq queries buffer one JSON value at a time,
+// so newline-delimited JSON is processed value by value.
+//
+// Every filter runs under Limits that bound the bytes it reads and writes
+// and the time it takes.
+package filter
+
+import (
+	"bufio"
+	"compress/bzip2"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/itchyny/gojq"
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// Default limits.
+const (
+	DefaultMaxInputBytes     = 1 << 30
+	DefaultMaxOutputBytes    = 256 << 20
+	DefaultMaxJSONValueBytes = 64 << 20
+	DefaultTimeout           = 30 * time.Second
+)
+
+// Supported decompression formats.
+const (
+	EncodingGzip    = "gzip"
+	EncodingZlib    = "zlib"
+	EncodingDeflate = "deflate"
+	EncodingBzip2   = "bzip2"
+	EncodingZstd    = "zstd"
+)
+
+var (
+	// ErrUnsupportedEncoding is returned for an unknown decompression
+	// format.
+	ErrUnsupportedEncoding = fmt.Errorf("%w: unsupported encoding", common.ErrInvalidArgument)
+
+	// ErrCorruptInput is returned when the object cannot be decompressed.
+	ErrCorruptInput = fmt.Errorf("%w: object cannot be decompressed", common.ErrInvalidArgument)
+
+	// ErrInvalidLineRange is returned for a malformed line range.
+	ErrInvalidLineRange = fmt.Errorf("%w: line ranges must be N, N- or N-M with 1 <= N <= M", common.ErrInvalidArgument)
+
+	// ErrInvalidQuery is returned for a jq expression that does not parse
+	// or compile.
+	ErrInvalidQuery = fmt.Errorf("%w: invalid jq expression", common.ErrInvalidArgument)
+
+	// ErrQueryFailed is returned when a jq query fails on the object, or
+	// the object is not JSON.
+	ErrQueryFailed = fmt.Errorf("%w: jq query failed", common.ErrInvalidArgument)
+
+	// ErrLimitExceeded is returned when a filter reads, writes or buffers
+	// more than its Limits allow. It wraps common.ErrResourceExhausted.
+	ErrLimitExceeded = fmt.Errorf("%w: filter limit exceeded", common.ErrResourceExhausted)
+)
+
+// LineRange selects lines First through Last of a text object, numbered
+// from 1. A zero Last selects through the end of the object.
+type LineRange struct {
+	First int64
+	Last  int64
+}
+
+// ParseLineRange parses "N" (line N), "N-" (line N to the end) or "N-M".
+func ParseLineRange(s string) (LineRange, error) {
+	firstStr, lastStr, hasDash := strings.Cut(strings.TrimSpace(s), "-")
+	first, err := strconv.ParseInt(firstStr, 10, 64)
+	if err != nil || first < 1 {
+		return LineRange{}, fmt.Errorf("%w: %q", ErrInvalidLineRange, s)
+	}
+	switch {
+	case !hasDash:
+		return LineRange{First: first, Last: first}, nil
+	case lastStr == "":
+		return LineRange{First: first}, nil
+	}
+	last, err := strconv.ParseInt(lastStr, 10, 64)
+	if err != nil || last < first {
+		return LineRange{}, fmt.Errorf("%w: %q", ErrInvalidLineRange, s)
+	}
+	return LineRange{First: first, Last: last}, nil
+}
+
+// Options selects the filters to apply. The zero value applies none.
+type Options struct {
+	// Decompress names the format to decompress the object from: "gzip",
+	// "zlib", "deflate", "bzip2" or "zstd".
+	Decompress string
+
+	// Lines selects a range of lines.
+	Lines *LineRange
+
+	// JQ is a jq expression run on each JSON value of the object. Results
+	// are written as compact JSON, one per line.
+	JQ string
+}
+
+// IsZero reports whether o applies no filter.
+func (o Options) IsZero() bool {
+	return o.Decompress == "" && o.Lines == nil && o.JQ == ""
+}
+
+// ContentType returns the content type of filtered output for an object of
+// content type original.
+func (o Options) ContentType(original string) string {
+	if o.JQ != "" {
+		return "application/json"
+	}
+	return original
+}
+
+// Limits bounds the resources a filter uses. Zero fields use the defaults.
+type Limits struct {
+	// MaxInputBytes bounds the bytes read from the object, after
+	// decompression.
+	MaxInputBytes int64
+
+	// MaxOutputBytes bounds the bytes written to the client.
+	MaxOutputBytes int64
+
+	// MaxJSONValueBytes bounds the size of one JSON value a jq query runs
+	// on.
+	MaxJSONValueBytes int64
+
+	// Timeout bounds the time spent filtering.
+	Timeout time.Duration
+}
+
+func (l Limits) withDefaults() Limits {
+	if l.MaxInputBytes <= 0 {
+		l.MaxInputBytes = DefaultMaxInputBytes
+	}
+	if l.MaxOutputBytes <= 0 {
+		l.MaxOutputBytes = DefaultMaxOutputBytes
+	}
+	if l.MaxJSONValueBytes <= 0 {
+		l.MaxJSONValueBytes = DefaultMaxJSONValueBytes
+	}
+	if l.Timeout <= 0 {
+		l.Timeout = DefaultTimeout
+	}
+	return l
+}
+
+// Apply returns src filtered by opts. Invalid options and a corrupt
+// compression header are reported immediately; later failures, including
+// exceeded limits, are returned by Read. Closing the returned reader stops
+// the filter but does not close src.
+func Apply(ctx context.Context, src io.Reader, opts Options, limits Limits) (io.ReadCloser, error) {
+	limits = limits.withDefaults()
+
+	var code *gojq.Code
+	if opts.JQ != "" {
+		var err error
+		if code, err = compile(opts.JQ); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, limits.Timeout)
+	closers := []func() error{func() error { cancel(); return nil }}
+	closeAll := func() error {
+		for i := len(closers) - 1; i >= 0; i-- {
+			_ = closers[i]()
+		}
+		return nil
+	}
+
+	var r io.Reader = &contextReader{ctx: ctx, r: src}
+	if opts.Decompress != "" {
+		dr, err := Decompress(opts.Decompress, r)
+		if err != nil {
+			_ = closeAll()
+			return nil, err
+		}
+		closers = append(closers, dr.Close)
+		r = dr
+	}
+	r = &limitReader{r: r, remaining: limits.MaxInputBytes}
+	if opts.Lines != nil {
+		r = newLineReader(r, *opts.Lines)
+	}
+
+	if code == nil {
+		return &filtered{Reader: &limitReader{r: r, remaining: limits.MaxOutputBytes}, close: closeAll}, nil
+	}
+
+	pr, pw := io.Pipe()
+	closers = append(closers, func() error { return pr.Close() })
+	go func() {
+		out := &limitWriter{w: pw, remaining: limits.MaxOutputBytes}
+		_ = pw.CloseWithError(runQuery(ctx, code, r, out, limits.MaxJSONValueBytes))
+	}()
+	return &filtered{Reader: pr, close: closeAll}, nil
+}
+
+// filtered is the reader returned by Apply.
+type filtered struct {
+	io.Reader
+	close func() error
+}
+
+func (f *filtered) Close() error {
+	return f.close()
+}
+
+// Decompress returns a reader of r decompressed from encoding, one of the
+// Encoding constants. A corrupt compression header is reported immediately
+// with ErrCorruptInput. Closing the returned reader does not close r.
+func Decompress(encoding string, r io.Reader) (io.ReadCloser, error) {
+	switch strings.ToLower(encoding) {
+	case EncodingGzip:
+		zr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrCorruptInput, err)
+		}
+		return zr, nil
+	case EncodingZlib:
+		zr, err := zlib.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrCorruptInput, err)
+		}
+		return zr, nil
+	case EncodingDeflate:
+		return flate.NewReader(r), nil
+	case EncodingBzip2:
+		return io.NopCloser(bzip2.NewReader(r)), nil
+	case EncodingZstd:
+		zr, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1), zstd.WithDecoderMaxWindow(64<<20))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrCorruptInput, err)
+		}
+		return zr.IOReadCloser(), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedEncoding, encoding)
+	}
+}
+
+// compile parses and compiles a jq expression. The server's environment
+// is hidden from $ENV and env.
+func compile(expr string) (*gojq.Code, error) {
+	query, err := gojq.Parse(expr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidQuery, err)
+	}
+	code, err := gojq.Compile(query, gojq.WithEnvironLoader(func() []string { return nil }))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidQuery, err)
+	}
+	return code, nil
+}
+
+// runQuery runs code on each JSON value read from r and writes each result
+// to w as compact JSON followed by a newline.
+func runQuery(ctx context.Context, code *gojq.Code, r io.Reader, w io.Writer, maxValueBytes int64) error {
+	counter := &valueLimitReader{r: r, max: maxValueBytes}
+	dec := json.NewDecoder(counter)
+	dec.UseNumber()
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+
+	for {
+		counter.reset()
+		var value any
+		if err := dec.Decode(&value); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if errors.Is(err, ErrLimitExceeded) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+				return err
+			}
+			return fmt.Errorf("%w: %w", ErrQueryFailed, err)
+		}
+
+		iter := code.RunWithContext(ctx, value)
+		for {
+			result, ok := iter.Next()
+			if !ok {
+				break
+			}
+			if err, isErr := result.(error); isErr {
+				var halt *gojq.HaltError
+				if errors.As(err, &halt) && halt.Value() == nil {
+					return nil
+				}
+				if ctxErr := ctx.Err(); ctxErr != nil {
+					return ctxErr
+				}
+				return fmt.Errorf("%w: %w", ErrQueryFailed, err)
+			}
+			if err := enc.Encode(result); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// lineReader passes through the lines of a LineRange and stops reading
+// once the last one has been read.
+type lineReader struct {
+	r       *bufio.Reader
+	lines   LineRange
+	line    int64
+	pending []byte
+	done    bool
+}
+
+func newLineReader(r io.Reader, lines LineRange) *lineReader {
+	return &lineReader{r: bufio.NewReaderSize(r, 64<<10), lines: lines, line: 1}
+}
+
+func (l *lineReader) Read(p []byte) (int, error) {
+	for len(l.pending) == 0 {
+		if l.done {
+			return 0, io.EOF
+		}
+		chunk, err := l.r.ReadSlice('\n')
+		inRange := l.line >= l.lines.First && (l.lines.Last == 0 || l.line <= l.lines.Last)
+		if len(chunk) > 0 && chunk[len(chunk)-1] == '\n' {
+			l.line++
+			if l.lines.Last != 0 && l.line > l.lines.Last {
+				l.done = true
+			}
+		}
+		if inRange {
+			// The slice stays valid until the next ReadSlice, which only
+			// happens once it has been consumed.
+			l.pending = chunk
+		}
+		switch {
+		case err == nil, errors.Is(err, bufio.ErrBufferFull):
+		case errors.Is(err, io.EOF):
+			l.done = true
+		default:
+			return 0, err
+		}
+	}
+	n := copy(p, l.pending)
+	l.pending = l.pending[n:]
+	return n, nil
+}
+
+// contextReader fails reads once ctx is done.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c *contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
+
+// limitReader fails with ErrLimitExceeded once more than remaining bytes
+// have been read.
+type limitReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (l *limitReader) Read(p []byte) (int, error) {
+	if l.remaining < 0 {
+		return 0, ErrLimitExceeded
+	}
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n + int(l.remaining), ErrLimitExceeded
+	}
+	return n, err
+}
+
+// valueLimitReader fails with ErrLimitExceeded when more than max bytes are
+// read between resets.
+type valueLimitReader struct {
+	r    io.Reader
+	max  int64
+	read int64
+}
+
+func (v *valueLimitReader) reset() {
+	v.read = 0
+}
+
+func (v *valueLimitReader) Read(p []byte) (int, error) {
+	if v.read > v.max {
+		return 0, fmt.Errorf("%w: JSON value larger than %d bytes", ErrLimitExceeded, v.max)
+	}
+	n, err := v.r.Read(p)
+	v.read += int64(n)
+	return n, err
+}
+
+// limitWriter fails with ErrLimitExceeded once more than remaining bytes
+// have been written.
+type limitWriter struct {
+	w         io.Writer
+	remaining int64
+}
+
+func (l *limitWriter) Write(p []byte) (int, error) {
+	if int64(len(p)) > l.remaining {
+		n, _ := l.w.Write(p[:l.remaining])
+		l.remaining = 0
+		return n, ErrLimitExceeded
+	}
+	n, err := l.w.Write(p)
+	l.remaining -= int64(n)
+	return n, err
+}
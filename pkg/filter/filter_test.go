@@ -0,0 +1,224 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package filter
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+func apply(t *testing.T, src io.Reader, opts Options, limits Limits) (string, error) {
+	t.Helper()
+	rc, err := Apply(context.Background(), src, opts, limits)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = rc.Close() }()
+	data, err := io.ReadAll(rc)
+	return string(data), err
+}
+
+func numberedLines(n int) string {
+	var b strings.Builder
+	for i := 1; i <= n; i++ {
+		fmt.Fprintf(&b, "line %d\n", i)
+	}
+	return b.String()
+}
+
+func gzipped(t *testing.T, s string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(s)); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// countingReader counts the bytes read from it.
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += n
+	return n, err
+}
+
+func TestParseLineRange(t *testing.T) {
+	tests := []struct {
+		in   string
+		want LineRange
+	}{
+		{"5", LineRange{5, 5}},
+		{"100-", LineRange{100, 0}},
+		{"100-200", LineRange{100, 200}},
+	}
+	for _, tt := range tests {
+		if got, err := ParseLineRange(tt.in); err != nil || got != tt.want {
+			t.Errorf("ParseLineRange(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+	for _, in := range []string{"", "0", "-5", "10-5", "a-b", "1-x"} {
+		if _, err := ParseLineRange(in); !errors.Is(err, ErrInvalidLineRange) {
+			t.Errorf("ParseLineRange(%q) error = %v, want ErrInvalidLineRange", in, err)
+		}
+	}
+}
+
+func TestApply_Lines(t *testing.T) {
+	src := &countingReader{r: strings.NewReader(numberedLines(100000))}
+	got, err := apply(t, src, Options{Lines: &LineRange{First: 3, Last: 5}}, Limits{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "line 3\nline 4\nline 5\n" {
+		t.Errorf("lines 3-5 = %q", got)
+	}
+	if src.n > 128<<10 {
+		t.Errorf("read %d bytes for the first lines of the object", src.n)
+	}
+
+	got, _ = apply(t, strings.NewReader("a\nb\nc"), Options{Lines: &LineRange{First: 2}}, Limits{})
+	if got != "b\nc" {
+		t.Errorf("lines 2- = %q", got)
+	}
+	got, _ = apply(t, strings.NewReader("a\nb\n"), Options{Lines: &LineRange{First: 5, Last: 9}}, Limits{})
+	if got != "" {
+		t.Errorf("lines past the end = %q", got)
+	}
+
+	long := strings.Repeat("x", 200<<10)
+	got, _ = apply(t, strings.NewReader("a\n"+long+"\nc\n"), Options{Lines: &LineRange{First: 2, Last: 2}}, Limits{})
+	if got != long+"\n" {
+		t.Errorf("long line: got %d bytes, want %d", len(got), len(long)+1)
+	}
+}
+
+func TestApply_Decompress(t *testing.T) {
+	got, err := apply(t, bytes.NewReader(gzipped(t, numberedLines(10))), Options{Decompress: "gzip", Lines: &LineRange{First: 10}}, Limits{})
+	if err != nil || got != "line 10\n" {
+		t.Errorf("gzip + lines = %q, %v", got, err)
+	}
+
+	var buf bytes.Buffer
+	zw, _ := zstd.NewWriter(&buf)
+	_, _ = zw.Write([]byte("zstd data"))
+	_ = zw.Close()
+	if got, err := apply(t, &buf, Options{Decompress: "zstd"}, Limits{}); err != nil || got != "zstd data" {
+		t.Errorf("zstd = %q, %v", got, err)
+	}
+
+	if _, err := Apply(context.Background(), strings.NewReader("plain text"), Options{Decompress: "gzip"}, Limits{}); !errors.Is(err, ErrCorruptInput) || !errors.Is(err, common.ErrInvalidArgument) {
+		t.Errorf("plain text as gzip: got %v", err)
+	}
+	if _, err := Apply(context.Background(), strings.NewReader(""), Options{Decompress: "rar"}, Limits{}); !errors.Is(err, ErrUnsupportedEncoding) {
+		t.Errorf("unknown encoding: got %v", err)
+	}
+
+	// A small object that decompresses beyond the input limit.
+	bomb := gzipped(t, strings.Repeat("\x00", 1<<20))
+	_, err = apply(t, bytes.NewReader(bomb), Options{Decompress: "gzip"}, Limits{MaxInputBytes: 64 << 10})
+	if !errors.Is(err, ErrLimitExceeded) || !errors.Is(err, common.ErrResourceExhausted) {
+		t.Errorf("decompression bomb: got %v", err)
+	}
+}
+
+func TestApply_JQ(t *testing.T) {
+	doc := `{"name": "build-42", "status": "ok", "artifacts": [{"path": "a.tar", "size": 12345678901234567}, {"path": "b.tar", "size": 2}]}`
+	got, err := apply(t, strings.NewReader(doc), Options{JQ: ".artifacts[] | {path, size}"}, Limits{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "{\"path\":\"a.tar\",\"size\":12345678901234567}\n{\"path\":\"b.tar\",\"size\":2}\n"
+	if got != want {
+		t.Errorf("jq = %q, want %q", got, want)
+	}
+
+	ndjson := "{\"level\":\"info\",\"msg\":\"a\"}\n{\"level\":\"error\",\"msg\":\"<b>\"}\n"
+	got, err = apply(t, strings.NewReader(ndjson), Options{JQ: `select(.level == "error") | .msg`}, Limits{})
+	if err != nil || got != "\"<b>\"\n" {
+		t.Errorf("jq over NDJSON = %q, %v", got, err)
+	}
+
+	got, err = apply(t, strings.NewReader(numberedLines(3)+`{"a":1}`+"\n"), Options{Lines: &LineRange{First: 4}, JQ: ".a"}, Limits{})
+	if err != nil || got != "1\n" {
+		t.Errorf("lines + jq = %q, %v", got, err)
+	}
+
+	if got, _ := apply(t, strings.NewReader("{}"), Options{JQ: "$ENV | length"}, Limits{}); got != "0\n" {
+		t.Errorf("$ENV exposed %q", got)
+	}
+
+	if _, err := Apply(context.Background(), strings.NewReader("{}"), Options{JQ: ".a |"}, Limits{}); !errors.Is(err, ErrInvalidQuery) {
+		t.Errorf("bad expression: got %v", err)
+	}
+	if _, err := apply(t, strings.NewReader("not json"), Options{JQ: "."}, Limits{}); !errors.Is(err, ErrQueryFailed) {
+		t.Errorf("not JSON: got %v", err)
+	}
+	if _, err := apply(t, strings.NewReader(`{"a":"x"}`), Options{JQ: ".a + 1"}, Limits{}); !errors.Is(err, ErrQueryFailed) {
+		t.Errorf("runtime error: got %v", err)
+	}
+}
+
+func TestApply_JQLimits(t *testing.T) {
+	_, err := apply(t, strings.NewReader("null"), Options{JQ: "range(100000000)"}, Limits{MaxOutputBytes: 1024})
+	if !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("output limit: got %v", err)
+	}
+
+	big := `{"data":"` + strings.Repeat("x", 1<<20) + `"}`
+	_, err = apply(t, strings.NewReader(big), Options{JQ: "."}, Limits{MaxJSONValueBytes: 64 << 10})
+	if !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("value limit: got %v", err)
+	}
+
+	start := time.Now()
+	_, err = apply(t, strings.NewReader("null"), Options{JQ: "def f: f; f"}, Limits{Timeout: 50 * time.Millisecond})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("timeout: got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("query ran for %v past its timeout", elapsed)
+	}
+}
+
+func TestOptions(t *testing.T) {
+	if !(Options{}).IsZero() || (Options{JQ: "."}).IsZero() {
+		t.Error("IsZero")
+	}
+	if ct := (Options{JQ: "."}).ContentType("text/plain"); ct != "application/json" {
+		t.Errorf("jq content type = %q", ct)
+	}
+	if ct := (Options{Decompress: "gzip"}).ContentType("text/csv"); ct != "text/csv" {
+		t.Errorf("decompress content type = %q", ct)
+	}
+}
@@ -17,10 +17,21 @@ package gcs
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
 	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/jeremyhahn/go-objstore/pkg/credfile"
+
 	"cloud.google.com/go/storage"
+	"google.golang.org/api/option"
 )
 
 // Test error variable
@@ -28,7 +39,9 @@ var errBoom = errors.New("boom")
 
 func TestGCS_Configure_Success_WithStubClient(t *testing.T) {
 	old := gcsNewClient
-	gcsNewClient = func(_ context.Context) (*storage.Client, error) { return &storage.Client{}, nil }
+	gcsNewClient = func(_ context.Context, _ http.RoundTripper, _ []option.ClientOption) (*storage.Client, error) {
+		return &storage.Client{}, nil
+	}
 	defer func() { gcsNewClient = old }()
 
 	g := &GCS{}
@@ -42,7 +55,9 @@ func TestGCS_Configure_Success_WithStubClient(t *testing.T) {
 
 func TestGCS_Configure_NewClientError(t *testing.T) {
 	old := gcsNewClient
-	gcsNewClient = func(_ context.Context) (*storage.Client, error) { return nil, errBoom }
+	gcsNewClient = func(_ context.Context, _ http.RoundTripper, _ []option.ClientOption) (*storage.Client, error) {
+		return nil, errBoom
+	}
 	defer func() { gcsNewClient = old }()
 
 	g := &GCS{}
@@ -65,7 +80,7 @@ func TestGCS_Configure_SkipClient(t *testing.T) {
 func TestGCS_Configure_ReuseExistingClient(t *testing.T) {
 	old := gcsNewClient
 	callCount := 0
-	gcsNewClient = func(_ context.Context) (*storage.Client, error) {
+	gcsNewClient = func(_ context.Context, _ http.RoundTripper, _ []option.ClientOption) (*storage.Client, error) {
 		callCount++
 		return &storage.Client{}, nil
 	}
@@ -86,3 +101,107 @@ func TestGCS_Configure_ReuseExistingClient(t *testing.T) {
 		t.Errorf("expected gcsNewClient called once, got %d", callCount)
 	}
 }
+
+func TestGCS_Configure_CredentialProviders(t *testing.T) {
+	old := gcsNewClient
+	var gotAuth []option.ClientOption
+	gcsNewClient = func(_ context.Context, _ http.RoundTripper, auth []option.ClientOption) (*storage.Client, error) {
+		gotAuth = auth
+		return &storage.Client{}, nil
+	}
+	defer func() { gcsNewClient = old }()
+
+	for provider, wantAuth := range map[string]bool{"": false, CredentialsDefault: false, CredentialsWorkloadIdentity: true} {
+		gotAuth = nil
+		if err := (&GCS{}).Configure(map[string]string{"bucket": "b", "credentials": provider}); err != nil {
+			t.Fatalf("Configure(%q) error = %v", provider, err)
+		}
+		if (len(gotAuth) > 0) != wantAuth {
+			t.Errorf("Configure(%q) auth options = %v", provider, gotAuth)
+		}
+	}
+
+	invalid := []map[string]string{
+		{"bucket": "b", "credentials": "vault"},
+		{"bucket": "b", "credentials": CredentialsFile},
+		{"bucket": "b", "credentials": CredentialsFile, "credentialsFile": writeKeyFile(t, `{"type": "unknown"}`)},
+	}
+	for _, settings := range invalid {
+		if err := (&GCS{}).Configure(settings); err == nil {
+			t.Errorf("Configure(%v) succeeded, want an error", settings)
+		}
+	}
+}
+
+func TestGCS_FileTokenSourceRotation(t *testing.T) {
+	path := writeKeyFile(t, serviceAccountKey(t, "key-1"))
+	file, err := credfile.Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	file.SetCheckInterval(0)
+	ts := &fileTokenSource{file: file}
+
+	first, err := ts.source()
+	if err != nil {
+		t.Fatalf("source() error = %v", err)
+	}
+	if again, _ := ts.source(); again != first {
+		t.Fatal("source() rebuilt the token source of an unchanged key")
+	}
+
+	if err := os.WriteFile(path, []byte(serviceAccountKey(t, "key-2-rotated")), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	rotated, err := ts.source()
+	if err != nil {
+		t.Fatalf("source() after rotation error = %v", err)
+	}
+	if rotated == first {
+		t.Fatal("source() kept the old key after the file changed")
+	}
+
+	if err := os.WriteFile(path, []byte(`{"type": "service_account", "priv`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if kept, err := ts.source(); err != nil || kept != rotated {
+		t.Fatalf("source() with a broken key = %v, %v, want the previous source", kept, err)
+	}
+}
+
+// writeKeyFile writes a credentials file and returns its path.
+func writeKeyFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "key.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// serviceAccountKey returns a service account JSON key with a fresh
+// private key.
+func serviceAccountKey(t *testing.T, keyID string) string {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(map[string]string{
+		"type":           "service_account",
+		"project_id":     "objstore-test",
+		"private_key_id": keyID,
+		"private_key":    string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"client_email":   "objstore@objstore-test.iam.gserviceaccount.com",
+		"client_id":      "1",
+		"token_uri":      "https://oauth2.googleapis.com/token",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build gcpstorage
+
+package gcs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// promptly bounds how long an aborted call may take to return.
+const promptly = 5 * time.Second
+
+// newStalledGCS returns a GCS backend whose emulator endpoint never finishes
+// a response. GET requests receive headers and a partial body first.
+func newStalledGCS(t *testing.T, extra map[string]string) *GCS {
+	t.Helper()
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.Header().Set("Content-Length", "1048576")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte("partial"))
+			w.(http.Flusher).Flush()
+		}
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	t.Cleanup(func() {
+		close(release)
+		srv.Close()
+	})
+	t.Setenv("STORAGE_EMULATOR_HOST", strings.TrimPrefix(srv.URL, "http://"))
+
+	settings := map[string]string{"bucket": "b", "httpShareClient": "false"}
+	for k, v := range extra {
+		settings[k] = v
+	}
+	g := &GCS{}
+	if err := g.Configure(settings); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+	return g
+}
+
+func TestGCS_PutWithContext_CancelAbortsUpload(t *testing.T) {
+	g := newStalledGCS(t, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	err := g.PutWithContext(ctx, "k", bytes.NewReader(make([]byte, 1<<20)))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("PutWithContext() error = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > promptly {
+		t.Fatalf("PutWithContext() returned after %v", elapsed)
+	}
+}
+
+func TestGCS_GetWithContext_CancelAbortsDownload(t *testing.T) {
+	g := newStalledGCS(t, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	rc, err := g.GetWithContext(ctx, "k")
+	if err != nil {
+		t.Fatalf("GetWithContext() error = %v", err)
+	}
+	defer func() { _ = rc.Close() }()
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	if _, err := io.ReadAll(rc); err == nil {
+		t.Fatal("reading the body of a canceled download succeeded")
+	}
+	if elapsed := time.Since(start); elapsed > promptly {
+		t.Fatalf("body read returned after %v", elapsed)
+	}
+}
+
+func TestGCS_PutTimeout_Setting(t *testing.T) {
+	g := newStalledGCS(t, map[string]string{"putTimeout": "100ms"})
+
+	start := time.Now()
+	err := g.Put("k", bytes.NewReader([]byte("data")))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Put() error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > promptly {
+		t.Fatalf("Put() returned after %v", elapsed)
+	}
+}
+
+func TestGCS_Configure_InvalidTimeout(t *testing.T) {
+	g := &GCS{}
+	err := g.Configure(map[string]string{"bucket": "b", "skip_client": "true", "timeout": "-5s"})
+	if !errors.Is(err, common.ErrInvalidArgument) {
+		t.Fatalf("Configure() error = %v, want ErrInvalidArgument", err)
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build gcpstorage
+
+package gcs
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/credfile"
+
+	"cloud.google.com/go/storage"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/option"
+)
+
+// Credential providers selected with the "credentials" setting.
+const (
+	// CredentialsDefault uses Application Default Credentials:
+	// GOOGLE_APPLICATION_CREDENTIALS, gcloud credentials, then the metadata
+	// server, which serves GKE Workload Identity.
+	CredentialsDefault = "default"
+
+	// CredentialsWorkloadIdentity always uses the metadata server, as with
+	// GKE Workload Identity or a Compute Engine service account.
+	CredentialsWorkloadIdentity = "workload-identity"
+
+	// CredentialsFile reads a service account or external account JSON key
+	// from credentialsFile and re-reads it when it changes.
+	CredentialsFile = "file"
+)
+
+// authOptions returns the client options that authenticate with the
+// credentials selected by settings.
+func authOptions(settings map[string]string) ([]option.ClientOption, error) {
+	switch provider := settings["credentials"]; provider {
+	case "", CredentialsDefault:
+		return nil, nil
+	case CredentialsWorkloadIdentity:
+		ts := google.ComputeTokenSource(settings["serviceAccount"], storage.ScopeFullControl)
+		return []option.ClientOption{option.WithTokenSource(ts)}, nil
+	case CredentialsFile:
+		file, err := credfile.Open(settings["credentialsFile"])
+		if err != nil {
+			return nil, err
+		}
+		ts := &fileTokenSource{file: file}
+		if _, err := ts.source(); err != nil {
+			return nil, err
+		}
+		return []option.ClientOption{option.WithTokenSource(ts)}, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown credentials provider %q", common.ErrInvalidArgument, provider)
+	}
+}
+
+// fileTokenSource issues tokens from a JSON key file, switching to the new
+// key whenever the file changes.
+type fileTokenSource struct {
+	file *credfile.File
+
+	mu      sync.Mutex
+	version uint64
+	ts      oauth2.TokenSource
+}
+
+// Token returns a token from the current key.
+func (s *fileTokenSource) Token() (*oauth2.Token, error) {
+	ts, err := s.source()
+	if err != nil {
+		return nil, err
+	}
+	return ts.Token()
+}
+
+// source returns the token source of the current key, rebuilding it when
+// the file changed. A key that fails to load keeps the previous source.
+func (s *fileTokenSource) source() (oauth2.TokenSource, error) {
+	_ = s.file.Refresh() // #nosec G104 -- A failed reload keeps the current key
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	version := s.file.Version()
+	if s.ts != nil && version == s.version {
+		return s.ts, nil
+	}
+	credType := google.CredentialsType(s.file.Lookup("type"))
+	creds, err := google.CredentialsFromJSONWithType(context.Background(), s.file.Data(), credType, storage.ScopeFullControl)
+	if err != nil {
+		if s.ts != nil {
+			return s.ts, nil
+		}
+		return nil, fmt.Errorf("%w: %s: %v", credfile.ErrInvalidFile, s.file.Path(), err)
+	}
+	s.ts, s.version = creds.TokenSource, version
+	return s.ts, nil
+}
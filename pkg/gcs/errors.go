@@ -0,0 +1,55 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build gcpstorage
+
+package gcs
+
+import (
+	"errors"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/googleapi"
+)
+
+// translateError maps a GCS error for key to the common error taxonomy.
+// Errors that are neither GCS sentinels nor API errors are returned unchanged.
+func translateError(err error, key string) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, storage.ErrObjectNotExist) || errors.Is(err, storage.ErrBucketNotExist) {
+		return common.ProviderError(common.ErrNotFound, key, err)
+	}
+	var gerr *googleapi.Error
+	if !errors.As(err, &gerr) {
+		return err
+	}
+	return common.ProviderError(sentinelFor(gerr), key, err)
+}
+
+// sentinelFor returns the common sentinel for a GCS API error. Quota
+// reasons take precedence because GCS reports some of them as 403.
+func sentinelFor(gerr *googleapi.Error) error {
+	for _, item := range gerr.Errors {
+		switch item.Reason {
+		case "rateLimitExceeded", "userRateLimitExceeded", "quotaExceeded":
+			return common.ErrQuotaExceeded
+		case "conditionNotMet":
+			return common.ErrPreconditionFailed
+		}
+	}
+	return common.ErrorForStatus(gerr.Code)
+}
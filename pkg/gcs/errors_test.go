@@ -0,0 +1,83 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build gcpstorage
+
+package gcs
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/googleapi"
+)
+
+func TestTranslateError(t *testing.T) {
+	apiErr := func(code int, reason string) error {
+		gerr := &googleapi.Error{Code: code, Message: reason}
+		if reason != "" {
+			gerr.Errors = []googleapi.ErrorItem{{Reason: reason, Message: reason}}
+		}
+		return gerr
+	}
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"object not exist", storage.ErrObjectNotExist, common.ErrKeyNotFound},
+		{"bucket not exist", storage.ErrBucketNotExist, common.ErrNotFound},
+		{"api not found", apiErr(http.StatusNotFound, "notFound"), common.ErrKeyNotFound},
+		{"forbidden", apiErr(http.StatusForbidden, "forbidden"), common.ErrAccessDenied},
+		{"quota reported as 403", apiErr(http.StatusForbidden, "quotaExceeded"), common.ErrQuotaExceeded},
+		{"rate limit", apiErr(http.StatusTooManyRequests, "rateLimitExceeded"), common.ErrQuotaExceeded},
+		{"condition not met", apiErr(http.StatusPreconditionFailed, "conditionNotMet"), common.ErrPreconditionFailed},
+		{"conflict", apiErr(http.StatusConflict, "conflict"), common.ErrAlreadyExists},
+		{"server error", apiErr(http.StatusServiceUnavailable, ""), common.ErrBackendUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := translateError(tt.err, "a/key")
+			if !errors.Is(got, tt.want) {
+				t.Errorf("translateError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+			if !errors.Is(got, tt.err) {
+				t.Errorf("translateError(%v) dropped the GCS error from the chain", tt.err)
+			}
+		})
+	}
+
+	plain := errors.New("not a GCS error")
+	if got := translateError(plain, "k"); got != plain {
+		t.Errorf("translateError(plain) = %v, want unchanged", got)
+	}
+	if got := translateError(nil, "k"); got != nil {
+		t.Errorf("translateError(nil) = %v, want nil", got)
+	}
+}
+
+func TestTranslatesObjectNotExist(t *testing.T) {
+	g := &GCS{client: fakeClient{b: fakeBucket{objs: map[string]*fakeObj{}}}, bucket: "bucket"}
+
+	if err := g.UpdateMetadata(context.Background(), "missing", nil); !errors.Is(err, common.ErrKeyNotFound) {
+		t.Errorf("UpdateMetadata() error = %v, want ErrKeyNotFound", err)
+	}
+	if exists, err := g.Exists(context.Background(), "missing"); exists || err != nil {
+		t.Errorf("Exists() = %v, %v, want false, nil", exists, err)
+	}
+}
@@ -20,13 +20,17 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net/http"
+	"os"
 	"sync"
 	"time"
 
 	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/transport"
 
 	"cloud.google.com/go/storage"
-	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+	htransport "google.golang.org/api/transport/http"
 )
 
 // Constants
@@ -35,9 +39,17 @@ const (
 )
 
 const (
-	lifecycleActionArchive = "archive"
+	lifecycleActionArchive    = "archive"
+	lifecycleActionTransition = common.ActionTransition
 )
 
+// storageClassArchive is the class archive policies transition objects to.
+const storageClassArchive = "ARCHIVE"
+
+// storageClasses are the GCS storage classes that can be selected per object
+// or as a lifecycle transition target.
+var storageClasses = []string{"STANDARD", "NEARLINE", "COLDLINE", storageClassArchive}
+
 // Small internal interfaces to enable unit tests without real GCS.
 type gcsObject interface {
 	NewWriter(ctx context.Context) io.WriteCloser
@@ -62,6 +74,26 @@ type gcsClient interface {
 	Bucket(name string) gcsBucket
 }
 
+// gcsRangeObject is implemented by objects that support ranged reads. It is
+// kept separate from gcsObject so test doubles need not implement it.
+type gcsRangeObject interface {
+	NewRangeReader(ctx context.Context, offset, length int64) (io.ReadCloser, error)
+}
+
+// gcsGenerationObject is implemented by objects that can address one of
+// their generations. It is kept separate from gcsObject so test doubles
+// need not implement it.
+type gcsGenerationObject interface {
+	Generation(gen int64) gcsObject
+}
+
+// gcsComposeBucket is implemented by buckets that can concatenate objects
+// server-side. It is kept separate from gcsBucket so test doubles need not
+// implement it.
+type gcsComposeBucket interface {
+	Compose(ctx context.Context, dst string, srcs []string, attrs storage.ObjectAttrs) error
+}
+
 type clientWrapper struct{ *storage.Client }
 type bucketWrapper struct{ *storage.BucketHandle }
 type objectWrapper struct{ *storage.ObjectHandle }
@@ -80,19 +112,35 @@ func (b bucketWrapper) Attrs(ctx context.Context) (*storage.BucketAttrs, error)
 func (b bucketWrapper) Update(ctx context.Context, uattrs storage.BucketAttrsToUpdate) (*storage.BucketAttrs, error) {
 	return gcsUpdateBucketFn(ctx, b.BucketHandle, uattrs)
 }
+func (b bucketWrapper) Compose(ctx context.Context, dst string, srcs []string, attrs storage.ObjectAttrs) error {
+	return gcsComposeFn(ctx, b.BucketHandle, dst, srcs, attrs)
+}
 func (i iteratorWrapper) Next() (*storage.ObjectAttrs, error) {
 	return i.ObjectIterator.Next()
 }
 
 // Function variables to enable unit testing without real network I/O.
 var (
-	gcsNewWriterFn    = func(o *storage.ObjectHandle, ctx context.Context) io.WriteCloser { w := o.NewWriter(ctx); return w }
-	gcsNewReaderFn    = func(o *storage.ObjectHandle, ctx context.Context) (io.ReadCloser, error) { return o.NewReader(ctx) }
+	gcsNewWriterFn      = func(o *storage.ObjectHandle, ctx context.Context) io.WriteCloser { w := o.NewWriter(ctx); return w }
+	gcsNewReaderFn      = func(o *storage.ObjectHandle, ctx context.Context) (io.ReadCloser, error) { return o.NewReader(ctx) }
+	gcsNewRangeReaderFn = func(o *storage.ObjectHandle, ctx context.Context, offset, length int64) (io.ReadCloser, error) {
+		return o.NewRangeReader(ctx, offset, length)
+	}
 	gcsDeleteFn       = func(o *storage.ObjectHandle, ctx context.Context) error { return o.Delete(ctx) }
 	gcsAttrsFn        = func(o *storage.ObjectHandle, ctx context.Context) (*storage.ObjectAttrs, error) { return o.Attrs(ctx) }
 	gcsUpdateObjectFn = func(o *storage.ObjectHandle, ctx context.Context, uattrs storage.ObjectAttrsToUpdate) (*storage.ObjectAttrs, error) {
 		return o.Update(ctx, uattrs)
 	}
+	gcsComposeFn = func(ctx context.Context, b *storage.BucketHandle, dst string, srcs []string, attrs storage.ObjectAttrs) error {
+		handles := make([]*storage.ObjectHandle, len(srcs))
+		for i, src := range srcs {
+			handles[i] = b.Object(src)
+		}
+		composer := b.Object(dst).ComposerFrom(handles...)
+		composer.ObjectAttrs = attrs
+		_, err := composer.Run(ctx)
+		return err
+	}
 	gcsGetBucketAttrsFn = func(ctx context.Context, b *storage.BucketHandle) (*storage.BucketAttrs, error) { return b.Attrs(ctx) }
 	gcsUpdateBucketFn   = func(ctx context.Context, b *storage.BucketHandle, uattrs storage.BucketAttrsToUpdate) (*storage.BucketAttrs, error) {
 		return b.Update(ctx, uattrs)
@@ -105,7 +153,13 @@ func (o objectWrapper) NewWriter(ctx context.Context) io.WriteCloser {
 func (o objectWrapper) NewReader(ctx context.Context) (io.ReadCloser, error) {
 	return gcsNewReaderFn(o.ObjectHandle, ctx)
 }
+func (o objectWrapper) NewRangeReader(ctx context.Context, offset, length int64) (io.ReadCloser, error) {
+	return gcsNewRangeReaderFn(o.ObjectHandle, ctx, offset, length)
+}
 func (o objectWrapper) Delete(ctx context.Context) error { return gcsDeleteFn(o.ObjectHandle, ctx) }
+func (o objectWrapper) Generation(gen int64) gcsObject {
+	return objectWrapper{o.ObjectHandle.Generation(gen)}
+}
 func (o objectWrapper) Attrs(ctx context.Context) (*storage.ObjectAttrs, error) {
 	return gcsAttrsFn(o.ObjectHandle, ctx)
 }
@@ -117,11 +171,35 @@ func (o objectWrapper) Update(ctx context.Context, uattrs storage.ObjectAttrsToU
 type GCS struct {
 	client             gcsClient
 	bucket             string
+	timeouts           *transport.Timeouts
 	policiesMutex      sync.RWMutex
 	replicationManager common.ReplicationManager
 }
 
-var gcsNewClient = func(ctx context.Context) (*storage.Client, error) { return storage.NewClient(ctx) }
+// gcsNewClient creates a storage client on top of the pooled base transport.
+var gcsNewClient = func(ctx context.Context, base http.RoundTripper, auth []option.ClientOption) (*storage.Client, error) {
+	httpClient, err := newHTTPClient(ctx, base, auth)
+	if err != nil {
+		return nil, err
+	}
+	return storage.NewClient(ctx, option.WithHTTPClient(httpClient))
+}
+
+// newHTTPClient layers Google authentication, using Application Default
+// Credentials unless auth selects others, over the pooled base transport.
+// Against an emulator no credentials are required, so the base transport is
+// used directly.
+func newHTTPClient(ctx context.Context, base http.RoundTripper, auth []option.ClientOption) (*http.Client, error) {
+	if os.Getenv("STORAGE_EMULATOR_HOST") != "" {
+		return &http.Client{Transport: base}, nil
+	}
+	opts := append([]option.ClientOption{option.WithScopes(storage.ScopeFullControl)}, auth...)
+	rt, err := htransport.NewTransport(ctx, base, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: rt}, nil
+}
 
 // New creates a new GCS storage backend.
 func New() common.Storage {
@@ -129,11 +207,19 @@ func New() common.Storage {
 }
 
 // Configure sets up the backend with the necessary settings.
+// HTTP transport tuning (http* keys) and per-operation timeouts (timeout,
+// getTimeout, ...) are described in the transport package; instances using
+// the same application default credentials share a connection pool.
 func (g *GCS) Configure(settings map[string]string) error {
 	g.bucket = settings["bucket"]
 	if g.bucket == "" {
 		return common.ErrBucketNotSet
 	}
+	timeouts, err := transport.ParseTimeouts(settings)
+	if err != nil {
+		return err
+	}
+	g.timeouts = timeouts
 	if g.client != nil {
 		return nil
 	}
@@ -141,8 +227,18 @@ func (g *GCS) Configure(settings map[string]string) error {
 	if settings["skip_client"] == "true" {
 		return nil
 	}
+	httpCfg, err := transport.ParseSettings(settings)
+	if err != nil {
+		return err
+	}
+	auth, err := authOptions(settings)
+	if err != nil {
+		return err
+	}
 	ctx := context.Background()
-	client, err := gcsNewClient(ctx)
+	identity := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS") + "|" + settings["credentials"] + "|" + settings["credentialsFile"]
+	base := transport.Default.Transport("gcs", identity, httpCfg)
+	client, err := gcsNewClient(ctx, base, auth)
 	if err != nil {
 		return err
 	}
@@ -152,60 +248,22 @@ func (g *GCS) Configure(settings map[string]string) error {
 
 // Put stores an object in the backend.
 func (g *GCS) Put(key string, data io.Reader) error {
-	if err := common.ValidateKey(key); err != nil {
-		return err
-	}
-	w := g.client.Bucket(g.bucket).Object(key).NewWriter(context.Background())
-	if _, err := io.Copy(w, data); err != nil {
-		// Close the writer to release resources; ignore the close error since
-		// the copy error is the primary failure.
-		_ = w.Close()
-		return err
-	}
-	return w.Close()
+	return g.PutWithContext(context.Background(), key, data)
 }
 
 // Get retrieves an object from the backend.
 func (g *GCS) Get(key string) (io.ReadCloser, error) {
-	if err := common.ValidateKey(key); err != nil {
-		return nil, err
-	}
-	return g.client.Bucket(g.bucket).Object(key).NewReader(context.Background())
+	return g.GetWithContext(context.Background(), key)
 }
 
 // Delete removes an object from the backend.
 func (g *GCS) Delete(key string) error {
-	if err := common.ValidateKey(key); err != nil {
-		return err
-	}
-	return g.client.Bucket(g.bucket).Object(key).Delete(context.Background())
+	return g.DeleteWithContext(context.Background(), key)
 }
 
 // List returns a list of keys that start with the given prefix.
 func (g *GCS) List(prefix string) ([]string, error) {
-	// Pre-allocate with reasonable capacity to reduce allocations
-	keys := make([]string, 0, 100)
-	ctx := context.Background()
-
-	query := &storage.Query{
-		Prefix: prefix,
-	}
-
-	it := g.client.Bucket(g.bucket).Objects(ctx, query)
-
-	for {
-		attrs, err := it.Next()
-		if err == iterator.Done { //nolint:err113 // iterator.Done is the standard sentinel error for GCS iterators
-			break
-		}
-		if err != nil {
-			return nil, err
-		}
-
-		keys = append(keys, attrs.Name)
-	}
-
-	return keys, nil
+	return g.ListWithContext(context.Background(), prefix)
 }
 
 func (g *GCS) Archive(key string, destination common.Archiver) error {
@@ -229,20 +287,32 @@ func (g *GCS) AddPolicy(policy common.LifecyclePolicy) error {
 	if policy.ID == "" {
 		return common.ErrInvalidPolicy
 	}
-	if policy.Action != actionDelete && policy.Action != lifecycleActionArchive {
+	if policy.Action != actionDelete && policy.Action != lifecycleActionArchive && policy.Action != lifecycleActionTransition {
 		return common.ErrInvalidPolicy
 	}
+	transitionClass := storageClassArchive
+	if policy.Action == lifecycleActionTransition {
+		class, err := common.NormalizeStorageClass(policy.StorageClass, storageClasses)
+		if err != nil {
+			return err
+		}
+		if class == "" {
+			return fmt.Errorf("%w: transition policy requires a storage class", common.ErrInvalidArgument)
+		}
+		transitionClass = class
+	}
 
 	g.policiesMutex.Lock()
 	defer g.policiesMutex.Unlock()
 
-	ctx := context.Background()
+	ctx, cancel := g.timeouts.Context(context.Background(), transport.OpMetadata)
+	defer cancel()
 	bucket := g.client.Bucket(g.bucket)
 
 	// Get current bucket attributes
 	attrs, err := bucket.Attrs(ctx)
 	if err != nil {
-		return err
+		return translateError(err, "")
 	}
 
 	// Copy existing lifecycle rules, removing any with the same ID
@@ -279,11 +349,11 @@ func (g *GCS) AddPolicy(policy common.LifecyclePolicy) error {
 		newRule.Action = storage.LifecycleAction{
 			Type: storage.DeleteAction,
 		}
-	case lifecycleActionArchive:
-		// Transition to Archive storage class
+	case lifecycleActionArchive, lifecycleActionTransition:
+		// Transition to the Archive storage class, or the requested one
 		newRule.Action = storage.LifecycleAction{
 			Type:         storage.SetStorageClassAction,
-			StorageClass: "ARCHIVE",
+			StorageClass: transitionClass,
 		}
 	}
 
@@ -296,7 +366,7 @@ func (g *GCS) AddPolicy(policy common.LifecyclePolicy) error {
 		},
 	})
 
-	return err
+	return translateError(err, "")
 }
 
 // RemovePolicy removes a lifecycle policy by updating GCS bucket lifecycle rules.
@@ -309,13 +379,14 @@ func (g *GCS) RemovePolicy(id string) error {
 	g.policiesMutex.Lock()
 	defer g.policiesMutex.Unlock()
 
-	ctx := context.Background()
+	ctx, cancel := g.timeouts.Context(context.Background(), transport.OpMetadata)
+	defer cancel()
 	bucket := g.client.Bucket(g.bucket)
 
 	// Get current bucket attributes
 	attrs, err := bucket.Attrs(ctx)
 	if err != nil {
-		return err
+		return translateError(err, "")
 	}
 
 	// Resolve the id to a rule using the same "rule-<index>" scheme as GetPolicies.
@@ -351,7 +422,7 @@ func (g *GCS) RemovePolicy(id string) error {
 		},
 	})
 
-	return err
+	return translateError(err, "")
 }
 
 // rulePrefix returns the first prefix of a lifecycle rule condition, or the
@@ -368,13 +439,14 @@ func (g *GCS) GetPolicies() ([]common.LifecyclePolicy, error) {
 	g.policiesMutex.RLock()
 	defer g.policiesMutex.RUnlock()
 
-	ctx := context.Background()
+	ctx, cancel := g.timeouts.Context(context.Background(), transport.OpMetadata)
+	defer cancel()
 	bucket := g.client.Bucket(g.bucket)
 
 	// Get bucket attributes including lifecycle rules
 	attrs, err := bucket.Attrs(ctx)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "")
 	}
 
 	// If no lifecycle rules exist, return empty list
@@ -407,6 +479,10 @@ func (g *GCS) GetPolicies() ([]common.LifecyclePolicy, error) {
 			policy.Action = "delete"
 		case storage.SetStorageClassAction:
 			policy.Action = lifecycleActionArchive
+			if rule.Action.StorageClass != storageClassArchive {
+				policy.Action = lifecycleActionTransition
+				policy.StorageClass = rule.Action.StorageClass
+			}
 		default:
 			// Skip rules we don't understand
 			continue
@@ -20,8 +20,12 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
+	"strconv"
+	"time"
 
 	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/transport"
 
 	"cloud.google.com/go/storage"
 	"google.golang.org/api/iterator"
@@ -34,17 +38,75 @@ func (g *GCS) PutWithContext(ctx context.Context, key string, data io.Reader) er
 
 // PutWithMetadata stores an object with associated metadata.
 func (g *GCS) PutWithMetadata(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) error {
+	_, _, err := g.put(ctx, key, data, metadata)
+	return err
+}
+
+// PutWithInfo stores an object with associated metadata and returns the
+// attributes GCS reported for the write, including its generation as the
+// version. Writers that report no attributes yield only the size written and
+// the time the write completed.
+func (g *GCS) PutWithInfo(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) (*common.ObjectInfo, error) {
+	size, attrs, err := g.put(ctx, key, data, metadata)
+	if err != nil {
+		return nil, err
+	}
+	if attrs == nil {
+		return &common.ObjectInfo{Key: key, Metadata: &common.Metadata{Size: size, LastModified: time.Now().UTC()}}, nil
+	}
+	return &common.ObjectInfo{Key: key, Metadata: &common.Metadata{
+		ContentType:     attrs.ContentType,
+		ContentEncoding: attrs.ContentEncoding,
+		Size:            attrs.Size,
+		LastModified:    attrs.Updated,
+		ETag:            attrs.Etag,
+		Version:         strconv.FormatInt(attrs.Generation, 10),
+		StorageClass:    attrs.StorageClass,
+		Custom:          maps.Clone(attrs.Metadata),
+	}}, nil
+}
+
+// attrsWriter is implemented by writers that report the attributes of the
+// object they wrote once closed, such as *storage.Writer.
+type attrsWriter interface {
+	Attrs() *storage.ObjectAttrs
+}
+
+// put uploads data to key and returns the number of bytes written and, when
+// the writer reports them, the attributes of the stored object.
+func (g *GCS) put(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) (int64, *storage.ObjectAttrs, error) {
 	if err := common.ValidateKey(key); err != nil {
-		return err
+		return 0, nil, err
 	}
+	var class string
+	if metadata != nil {
+		var err error
+		if class, err = common.NormalizeStorageClass(metadata.StorageClass, storageClasses); err != nil {
+			return 0, nil, err
+		}
+	}
+	ctx, cancel := g.timeouts.Context(ctx, transport.OpPut)
+	defer cancel()
 	w := g.client.Bucket(g.bucket).Object(key).NewWriter(ctx)
-	if _, err := io.Copy(w, data); err != nil {
+	// Object attributes can only be set on the real writer before the first
+	// write; test doubles are left untouched.
+	if sw, ok := w.(*storage.Writer); ok && class != "" {
+		sw.StorageClass = class
+	}
+	n, err := io.Copy(w, data)
+	if err != nil {
 		// Close to release the GCS write stream; ignore close error.
 		_ = w.Close()
-		return err
+		return 0, nil, translateError(err, key)
 	}
 	// Close finalizes the GCS upload; capture its error.
-	return w.Close()
+	if err := w.Close(); err != nil {
+		return 0, nil, translateError(err, key)
+	}
+	if aw, ok := w.(attrsWriter); ok {
+		return n, aw.Attrs(), nil
+	}
+	return n, nil, nil
 }
 
 // GetWithContext retrieves an object from the backend with context support.
@@ -52,8 +114,41 @@ func (g *GCS) GetWithContext(ctx context.Context, key string) (io.ReadCloser, er
 	if err := common.ValidateKey(key); err != nil {
 		return nil, err
 	}
+	ctx, cancel := g.timeouts.Context(ctx, transport.OpGet)
 	obj := g.client.Bucket(g.bucket).Object(key)
-	return obj.NewReader(ctx)
+	rc, err := obj.NewReader(ctx)
+	if err != nil {
+		cancel()
+		return nil, translateError(err, key)
+	}
+	return transport.CancelOnClose(rc, cancel), nil
+}
+
+// GetRange retrieves length bytes of an object starting at offset. A negative
+// length reads to the end of the object.
+func (g *GCS) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	if err := common.ValidateKey(key); err != nil {
+		return nil, err
+	}
+	if offset < 0 {
+		return nil, fmt.Errorf("%w: negative range offset %d", common.ErrInvalidArgument, offset)
+	}
+	ctx, cancel := g.timeouts.Context(ctx, transport.OpGet)
+	obj := g.client.Bucket(g.bucket).Object(key)
+	if ro, ok := obj.(gcsRangeObject); ok {
+		rc, err := ro.NewRangeReader(ctx, offset, length)
+		if err != nil {
+			cancel()
+			return nil, translateError(err, key)
+		}
+		return transport.CancelOnClose(rc, cancel), nil
+	}
+	rc, err := obj.NewReader(ctx)
+	if err != nil {
+		cancel()
+		return nil, translateError(err, key)
+	}
+	return common.SliceRange(transport.CancelOnClose(rc, cancel), offset, length)
 }
 
 // GetMetadata retrieves only the metadata for an object.
@@ -63,6 +158,8 @@ func (g *GCS) GetMetadata(ctx context.Context, key string) (*common.Metadata, er
 	if err := common.ValidateKey(key); err != nil {
 		return nil, err
 	}
+	ctx, cancel := g.timeouts.Context(ctx, transport.OpMetadata)
+	defer cancel()
 	meta := &common.Metadata{}
 	obj := g.client.Bucket(g.bucket).Object(key)
 	attrs, err := obj.Attrs(ctx)
@@ -72,6 +169,7 @@ func (g *GCS) GetMetadata(ctx context.Context, key string) (*common.Metadata, er
 	}
 	meta.Size = attrs.Size
 	meta.ContentType = attrs.ContentType
+	meta.StorageClass = attrs.StorageClass
 	return meta, nil
 }
 
@@ -98,11 +196,10 @@ func (g *GCS) UpdateMetadata(ctx context.Context, key string, metadata *common.M
 		ContentEncoding: metadata.ContentEncoding,
 		Metadata:        custom,
 	}
+	ctx, cancel := g.timeouts.Context(ctx, transport.OpMetadata)
+	defer cancel()
 	if _, err := g.client.Bucket(g.bucket).Object(key).Update(ctx, uattrs); err != nil {
-		if errors.Is(err, storage.ErrObjectNotExist) {
-			return fmt.Errorf("%w: %s", common.ErrKeyNotFound, key)
-		}
-		return err
+		return translateError(err, key)
 	}
 	return nil
 }
@@ -112,8 +209,10 @@ func (g *GCS) DeleteWithContext(ctx context.Context, key string) error {
 	if err := common.ValidateKey(key); err != nil {
 		return err
 	}
+	ctx, cancel := g.timeouts.Context(ctx, transport.OpDelete)
+	defer cancel()
 	obj := g.client.Bucket(g.bucket).Object(key)
-	return obj.Delete(ctx)
+	return translateError(obj.Delete(ctx), key)
 }
 
 // Exists checks if an object exists in the backend.
@@ -122,10 +221,12 @@ func (g *GCS) Exists(ctx context.Context, key string) (bool, error) {
 	if err := common.ValidateKey(key); err != nil {
 		return false, err
 	}
+	ctx, cancel := g.timeouts.Context(ctx, transport.OpMetadata)
+	defer cancel()
 	obj := g.client.Bucket(g.bucket).Object(key)
 	_, err := obj.Attrs(ctx)
 	if err != nil {
-		if errors.Is(err, storage.ErrObjectNotExist) {
+		if err = translateError(err, key); errors.Is(err, common.ErrNotFound) {
 			return false, nil
 		}
 		return false, err
@@ -135,6 +236,8 @@ func (g *GCS) Exists(ctx context.Context, key string) (bool, error) {
 
 // ListWithContext returns a list of keys with context support.
 func (g *GCS) ListWithContext(ctx context.Context, prefix string) ([]string, error) {
+	ctx, cancel := g.timeouts.Context(ctx, transport.OpList)
+	defer cancel()
 	var keys []string
 	query := &storage.Query{Prefix: prefix}
 	it := g.client.Bucket(g.bucket).Objects(ctx, query)
@@ -145,7 +248,7 @@ func (g *GCS) ListWithContext(ctx context.Context, prefix string) ([]string, err
 			break
 		}
 		if err != nil {
-			return nil, err
+			return nil, translateError(err, "")
 		}
 		keys = append(keys, attrs.Name)
 	}
@@ -159,6 +262,8 @@ func (g *GCS) ListWithOptions(ctx context.Context, opts *common.ListOptions) (*c
 	if opts == nil {
 		opts = &common.ListOptions{}
 	}
+	ctx, cancel := g.timeouts.Context(ctx, transport.OpList)
+	defer cancel()
 
 	query := &storage.Query{
 		Prefix: opts.Prefix,
@@ -203,7 +308,7 @@ func (g *GCS) ListWithOptions(ctx context.Context, opts *common.ListOptions) (*c
 			break
 		}
 		if err != nil {
-			return nil, err
+			return nil, translateError(err, "")
 		}
 
 		// Check if this is a common prefix
@@ -239,3 +344,72 @@ func contains(slice []string, item string) bool {
 	}
 	return false
 }
+
+// gcsMaxComposeSources is the GCS limit on source objects per compose request.
+const gcsMaxComposeSources = 32
+
+// Append adds data to the end of the object stored under key, creating it if
+// it does not exist. The data is uploaded as a temporary object, composed
+// onto the end of key server-side and then deleted, so the existing contents
+// are never transferred.
+func (g *GCS) Append(ctx context.Context, key string, data io.Reader) error {
+	if err := common.ValidateKey(key); err != nil {
+		return err
+	}
+	bucket := g.client.Bucket(g.bucket)
+	composer, ok := bucket.(gcsComposeBucket)
+	if !ok {
+		return common.EmulateAppend(ctx, g, key, data)
+	}
+	ctx, cancel := g.timeouts.Context(ctx, transport.OpPut)
+	defer cancel()
+
+	attrs, err := bucket.Object(key).Attrs(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return g.PutWithMetadata(ctx, key, data, nil)
+	}
+	if err != nil {
+		return translateError(err, key)
+	}
+
+	tmp := fmt.Sprintf("%s.append-%d", key, time.Now().UnixNano())
+	if err := g.PutWithMetadata(ctx, tmp, data, nil); err != nil {
+		return err
+	}
+	defer func() { _ = bucket.Object(tmp).Delete(ctx) }()
+	return translateError(composer.Compose(ctx, key, []string{key, tmp}, composeAttrs(attrs)), key)
+}
+
+// Compose writes the concatenation of srcKeys to destKey with a server-side
+// compose request. The result takes its content type, encoding, storage
+// class and custom metadata from the first source. Requests with more than
+// 32 sources, the GCS limit, are copied with common.EmulateCompose.
+func (g *GCS) Compose(ctx context.Context, destKey string, srcKeys ...string) error {
+	if err := common.ValidateComposeKeys(destKey, srcKeys); err != nil {
+		return err
+	}
+	bucket := g.client.Bucket(g.bucket)
+	composer, ok := bucket.(gcsComposeBucket)
+	if !ok || len(srcKeys) > gcsMaxComposeSources {
+		return common.EmulateCompose(ctx, g, destKey, srcKeys...)
+	}
+	ctx, cancel := g.timeouts.Context(ctx, transport.OpPut)
+	defer cancel()
+
+	attrs, err := bucket.Object(srcKeys[0]).Attrs(ctx)
+	if err != nil {
+		return translateError(err, srcKeys[0])
+	}
+	return translateError(composer.Compose(ctx, destKey, srcKeys, composeAttrs(attrs)), destKey)
+}
+
+// composeAttrs returns the attributes of attrs carried over to a composed
+// object.
+func composeAttrs(attrs *storage.ObjectAttrs) storage.ObjectAttrs {
+	return storage.ObjectAttrs{
+		ContentType:     attrs.ContentType,
+		ContentEncoding: attrs.ContentEncoding,
+		StorageClass:    attrs.StorageClass,
+		Metadata:        attrs.Metadata,
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"errors"
 	"io"
 	"testing"
+	"time"
 
 	"github.com/jeremyhahn/go-objstore/pkg/common"
 
@@ -106,6 +107,49 @@ func TestGCS_PutWithMetadata_Error(t *testing.T) {
 	}
 }
 
+func TestGCS_PutWithInfo(t *testing.T) {
+	updated := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	objs := map[string]*fakeObj{
+		"key": {written: &storage.ObjectAttrs{
+			Size:         4,
+			ContentType:  "text/plain",
+			Etag:         "CJC2",
+			Generation:   1717243200000000,
+			Updated:      updated,
+			StorageClass: "NEARLINE",
+			Metadata:     map[string]string{"author": "test"},
+		}},
+	}
+	fc := fakeClient{b: fakeBucket{objs: objs}}
+	g := &GCS{client: fc, bucket: "test-bucket"}
+
+	metadata := &common.Metadata{ContentType: "text/plain", StorageClass: "nearline"}
+	info, err := g.PutWithInfo(context.Background(), "key", bytes.NewReader([]byte("data")), metadata)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if info.Key != "key" || info.Metadata.Size != 4 || info.Metadata.ETag != "CJC2" || info.Metadata.Version != "1717243200000000" {
+		t.Fatalf("unexpected info: %+v %+v", info, info.Metadata)
+	}
+	if !info.Metadata.LastModified.Equal(updated) || info.Metadata.StorageClass != "NEARLINE" || info.Metadata.Custom["author"] != "test" {
+		t.Fatalf("unexpected metadata: %+v", info.Metadata)
+	}
+
+	// Writers that report no attributes still yield the size written.
+	info, err = g.PutWithInfo(context.Background(), "other", bytes.NewReader([]byte("abc")), nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if info.Metadata.Size != 3 || info.Metadata.Version != "" || info.Metadata.LastModified.IsZero() {
+		t.Fatalf("unexpected metadata: %+v", info.Metadata)
+	}
+
+	objs["bad"] = &fakeObj{closeErr: true}
+	if _, err := g.PutWithInfo(context.Background(), "bad", bytes.NewReader([]byte("data")), nil); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
 // TestGCS_GetWithContext tests context-aware Get
 func TestGCS_GetWithContext(t *testing.T) {
 	objs := map[string]*fakeObj{
@@ -621,3 +665,89 @@ func TestGCS_Contains(t *testing.T) {
 		t.Fatal("expected contains to return false for empty slice")
 	}
 }
+
+// composeBucket is a fakeBucket that supports server-side compose.
+type composeBucket struct {
+	fakeBucket
+	composed int
+	attrs    storage.ObjectAttrs
+}
+
+func (b *composeBucket) Compose(_ context.Context, dst string, srcs []string, attrs storage.ObjectAttrs) error {
+	var data []byte
+	for _, src := range srcs {
+		obj := b.objs[src]
+		if obj == nil || obj.data == nil {
+			return storage.ErrObjectNotExist
+		}
+		data = append(data, obj.data...)
+	}
+	b.Object(dst)
+	b.objs[dst].data = data
+	b.composed++
+	b.attrs = attrs
+	return nil
+}
+
+type composeClient struct{ b *composeBucket }
+
+func (c composeClient) Bucket(string) gcsBucket { return c.b }
+
+func TestGCS_Append(t *testing.T) {
+	ctx := context.Background()
+	bucket := &composeBucket{fakeBucket: fakeBucket{objs: map[string]*fakeObj{}}}
+	g := &GCS{client: composeClient{bucket}, bucket: "test-bucket"}
+
+	if err := g.Append(ctx, "app.log", bytes.NewReader([]byte("one\n"))); err != nil {
+		t.Fatalf("Append() to missing object error = %v", err)
+	}
+	if bucket.composed != 0 {
+		t.Errorf("Append() to missing object composed %d times, want a plain upload", bucket.composed)
+	}
+	if err := g.Append(ctx, "app.log", bytes.NewReader([]byte("two\n"))); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	if bucket.composed != 1 {
+		t.Errorf("Append() composed %d times, want 1", bucket.composed)
+	}
+	if got := string(bucket.objs["app.log"].data); got != "one\ntwo\n" {
+		t.Errorf("content = %q, want %q", got, "one\ntwo\n")
+	}
+	for key, obj := range bucket.objs {
+		if key != "app.log" && obj.data != nil {
+			t.Errorf("temporary object %s was not deleted", key)
+		}
+	}
+}
+
+func TestGCS_Compose(t *testing.T) {
+	ctx := context.Background()
+	bucket := &composeBucket{fakeBucket: fakeBucket{objs: map[string]*fakeObj{
+		"a": {data: []byte("aa")},
+		"b": {data: []byte("bb")},
+	}}}
+	g := &GCS{client: composeClient{bucket}, bucket: "test-bucket"}
+
+	if err := g.Compose(ctx, "ab", "a", "b"); err != nil {
+		t.Fatalf("Compose() error = %v", err)
+	}
+	if got := string(bucket.objs["ab"].data); got != "aabb" || bucket.composed != 1 {
+		t.Errorf("Compose() = %q after %d requests, want %q after 1", got, bucket.composed, "aabb")
+	}
+
+	if err := g.Compose(ctx, "out", "missing", "a"); !errors.Is(err, common.ErrKeyNotFound) {
+		t.Errorf("Compose() with missing first source error = %v, want ErrKeyNotFound", err)
+	}
+	if err := g.Compose(ctx, "out"); !errors.Is(err, common.ErrInvalidArgument) {
+		t.Errorf("Compose() without sources error = %v, want ErrInvalidArgument", err)
+	}
+
+	// Without compose support the sources are copied instead.
+	plain := &GCS{client: fakeClient{b: bucket.fakeBucket}, bucket: "test-bucket"}
+	if err := plain.Compose(ctx, "ba", "b", "a"); err != nil {
+		t.Fatalf("Compose() fallback error = %v", err)
+	}
+	if got := string(bucket.objs["ba"].data); got != "bbaa" || bucket.composed != 1 {
+		t.Errorf("Compose() fallback = %q, want %q without a compose request", got, "bbaa")
+	}
+}
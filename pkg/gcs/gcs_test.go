@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"io"
+	"net/http"
 	"sort"
 	"testing"
 	"time"
@@ -28,6 +29,7 @@ import (
 
 	"cloud.google.com/go/storage"
 	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
 )
 
 // Test error variables
@@ -364,12 +366,18 @@ type fakeObj struct {
 	attrsErr  bool
 	updateErr bool
 	updated   *storage.ObjectAttrsToUpdate
+	// written, when set, is reported by the writer once closed, as
+	// *storage.Writer does.
+	written *storage.ObjectAttrs
 }
 
 func (f *fakeObj) NewWriter(ctx context.Context) io.WriteCloser {
 	if f.writeErr {
 		return &errorWriter{closeErr: f.closeErr}
 	}
+	if f.written != nil {
+		return &attrsWriteCloser{nopWriteCloser{buf: &f.data, closeErr: f.closeErr}, f.written}
+	}
 	return &nopWriteCloser{buf: &f.data, closeErr: f.closeErr}
 }
 
@@ -426,6 +434,15 @@ func (n *nopWriteCloser) Close() error {
 	return nil
 }
 
+type attrsWriteCloser struct {
+	nopWriteCloser
+	attrs *storage.ObjectAttrs
+}
+
+func (a *attrsWriteCloser) Attrs() *storage.ObjectAttrs {
+	return a.attrs
+}
+
 type errorWriter struct {
 	closeErr bool
 }
@@ -570,7 +587,7 @@ func TestGCS_Configure_ClientCreationError(t *testing.T) {
 	defer func() { gcsNewClient = originalNewClient }()
 
 	// Mock gcsNewClient to return error
-	gcsNewClient = func(ctx context.Context) (*storage.Client, error) {
+	gcsNewClient = func(ctx context.Context, _ http.RoundTripper, _ []option.ClientOption) (*storage.Client, error) {
 		return nil, errClientCreationFailed
 	}
 
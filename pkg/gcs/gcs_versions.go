@@ -0,0 +1,95 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build gcpstorage
+
+package gcs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/transport"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
+)
+
+// GetAsOf reads the generation of key that was live at at from a bucket
+// with object versioning enabled: the newest generation created at or
+// before at that had not yet been replaced or deleted by then.
+func (g *GCS) GetAsOf(ctx context.Context, key string, at time.Time) (io.ReadCloser, *common.Metadata, error) {
+	if err := common.ValidateKey(key); err != nil {
+		return nil, nil, err
+	}
+
+	attrs, err := g.generationAsOf(ctx, key, at)
+	if err != nil {
+		return nil, nil, err
+	}
+	obj, ok := g.client.Bucket(g.bucket).Object(key).(gcsGenerationObject)
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: object generations are not addressable", common.ErrInternal)
+	}
+
+	ctx, cancel := g.timeouts.Context(ctx, transport.OpGet)
+	rc, err := obj.Generation(attrs.Generation).NewReader(ctx)
+	if err != nil {
+		cancel()
+		return nil, nil, translateError(err, key)
+	}
+	return transport.CancelOnClose(rc, cancel), &common.Metadata{
+		Size:            attrs.Size,
+		LastModified:    attrs.Updated,
+		ETag:            attrs.Etag,
+		ContentType:     attrs.ContentType,
+		ContentEncoding: attrs.ContentEncoding,
+		StorageClass:    attrs.StorageClass,
+		Custom:          attrs.Metadata,
+	}, nil
+}
+
+// generationAsOf returns the attributes of the generation of key live at at.
+func (g *GCS) generationAsOf(ctx context.Context, key string, at time.Time) (*storage.ObjectAttrs, error) {
+	ctx, cancel := g.timeouts.Context(ctx, transport.OpList)
+	defer cancel()
+
+	var live *storage.ObjectAttrs
+	it := g.client.Bucket(g.bucket).Objects(ctx, &storage.Query{Prefix: key, Versions: true})
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done { //nolint:err113 // iterator.Done is the standard sentinel error for GCS iterators
+			break
+		}
+		if err != nil {
+			return nil, translateError(err, key)
+		}
+		if attrs.Name != key || attrs.Created.After(at) {
+			continue
+		}
+		if !attrs.Deleted.IsZero() && !attrs.Deleted.After(at) {
+			continue
+		}
+		if live == nil || attrs.Created.After(live.Created) {
+			live = attrs
+		}
+	}
+
+	if live == nil {
+		return nil, fmt.Errorf("%w: %s as of %s", common.ErrKeyNotFound, key, at.Format(time.RFC3339))
+	}
+	return live, nil
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build gcpstorage
+
+package gcs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+
+	"cloud.google.com/go/storage"
+)
+
+// generationObj serves the data of each generation of one object.
+type generationObj struct {
+	*fakeObj
+	generations map[int64][]byte
+}
+
+func (o generationObj) Generation(gen int64) gcsObject {
+	return &fakeObj{data: o.generations[gen]}
+}
+
+type generationBucket struct {
+	fakeBucket
+	obj generationObj
+}
+
+func (b generationBucket) Object(string) gcsObject {
+	return b.obj
+}
+
+type generationClient struct {
+	b generationBucket
+}
+
+func (c generationClient) Bucket(string) gcsBucket {
+	return c.b
+}
+
+func TestGetAsOf(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2025, 6, d, 0, 0, 0, 0, time.UTC)
+	}
+	bucket := generationBucket{
+		fakeBucket: fakeBucket{iterator: &fakeIterator{objects: []*storage.ObjectAttrs{
+			{Name: "config.json", Generation: 1, Created: day(1), Deleted: day(3)},
+			{Name: "config.json", Generation: 5, Created: day(5), Metadata: map[string]string{"owner": "ops"}},
+			{Name: "config.json.bak", Generation: 2, Created: day(2)},
+		}}},
+		obj: generationObj{
+			fakeObj:     &fakeObj{},
+			generations: map[int64][]byte{1: []byte("v1"), 5: []byte("v5")},
+		},
+	}
+	backend := &GCS{client: generationClient{b: bucket}, bucket: "test-bucket"}
+	ctx := context.Background()
+
+	tests := []struct {
+		at   time.Time
+		want string
+	}{
+		{day(2), "v1"},
+		{day(5), "v5"},
+		{day(9), "v5"},
+	}
+	for _, tt := range tests {
+		bucket.iterator.(*fakeIterator).index = 0
+		reader, _, err := backend.GetAsOf(ctx, "config.json", tt.at)
+		if err != nil {
+			t.Fatalf("GetAsOf(%v) error = %v", tt.at, err)
+		}
+		data, _ := io.ReadAll(reader)
+		_ = reader.Close()
+		if string(data) != tt.want {
+			t.Errorf("GetAsOf(%v) = %q, want %q", tt.at, data, tt.want)
+		}
+	}
+
+	// Before the first generation, and after it was deleted.
+	for _, at := range []time.Time{day(0), day(3)} {
+		bucket.iterator.(*fakeIterator).index = 0
+		if _, _, err := backend.GetAsOf(ctx, "config.json", at); !errors.Is(err, common.ErrKeyNotFound) {
+			t.Errorf("GetAsOf(%v) error = %v, want ErrKeyNotFound", at, err)
+		}
+	}
+}
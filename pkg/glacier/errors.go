@@ -0,0 +1,57 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build glacier
+
+package glacier
+
+import (
+	"errors"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/smithy-go"
+)
+
+// translateError maps a Glacier API error for key to the common error
+// taxonomy. Errors that are not API errors are returned unchanged.
+func translateError(err error, key string) error {
+	var apiErr smithy.APIError
+	if err == nil || !errors.As(err, &apiErr) {
+		return err
+	}
+	status := 0
+	var respErr *awshttp.ResponseError
+	if errors.As(err, &respErr) {
+		status = respErr.HTTPStatusCode()
+	}
+	return common.ProviderError(sentinelFor(apiErr.ErrorCode(), status), key, err)
+}
+
+// sentinelFor returns the common sentinel for a Glacier error code, falling
+// back to the HTTP status.
+func sentinelFor(code string, status int) error {
+	switch code {
+	case "ResourceNotFoundException":
+		return common.ErrNotFound
+	case "AccessDeniedException", "MissingAuthenticationTokenException",
+		"UnrecognizedClientException", "InvalidSignatureException", "ExpiredTokenException":
+		return common.ErrAccessDenied
+	case "ThrottlingException", "LimitExceededException", "InsufficientCapacityException":
+		return common.ErrQuotaExceeded
+	case "ServiceUnavailableException", "RequestTimeoutException":
+		return common.ErrBackendUnavailable
+	}
+	return common.ErrorForStatus(status)
+}
@@ -126,7 +126,7 @@ func (g *Glacier) Put(key string, data io.Reader) error {
 		}
 		if pn > 0 {
 			rest := io.MultiReader(bytes.NewReader(peek[:pn]), data)
-			return g.putMultipart(ctx, key, partSize, first, rest)
+			return translateError(g.putMultipart(ctx, key, partSize, first, rest), key)
 		}
 	}
 
@@ -138,7 +138,7 @@ func (g *Glacier) Put(key string, data io.Reader) error {
 		ArchiveDescription: aws.String(key),
 		Body:               bytes.NewReader(first),
 	})
-	return err
+	return translateError(err, key)
 }
 
 // putMultipart streams the archive to Glacier with the multipart upload
@@ -27,8 +27,12 @@ import (
 	"strconv"
 	"testing"
 
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/glacier"
+	"github.com/aws/aws-sdk-go-v2/service/glacier/types"
+	"github.com/aws/smithy-go"
 )
 
 func TestGlacier_Configure_Errors(t *testing.T) {
@@ -454,3 +458,44 @@ func TestCombineTreeHashes_Empty(t *testing.T) {
 		t.Errorf("combineTreeHashes(nil) = %x, want nil", got)
 	}
 }
+
+func TestTranslateError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"vault not found", &types.ResourceNotFoundException{Message: aws.String("vault")}, common.ErrKeyNotFound},
+		{"throttled", &smithy.GenericAPIError{Code: "ThrottlingException"}, common.ErrQuotaExceeded},
+		{"limit exceeded", &types.LimitExceededException{}, common.ErrQuotaExceeded},
+		{"unavailable", &types.ServiceUnavailableException{}, common.ErrBackendUnavailable},
+		{"no credentials", &smithy.GenericAPIError{Code: "MissingAuthenticationTokenException"}, common.ErrAccessDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := translateError(tt.err, "a/key")
+			if !errors.Is(got, tt.want) {
+				t.Errorf("translateError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+			var apiErr smithy.APIError
+			if !errors.As(got, &apiErr) {
+				t.Errorf("translateError(%v) dropped the API error from the chain", tt.err)
+			}
+		})
+	}
+
+	plain := errors.New("not an API error")
+	if got := translateError(plain, "k"); got != plain {
+		t.Errorf("translateError(plain) = %v, want unchanged", got)
+	}
+}
+
+func TestGlacier_Put_TranslatesError(t *testing.T) {
+	mock := &mockGlacierAPI{uploadArchiveErr: &types.ServiceUnavailableException{Message: aws.String("down")}}
+	g := &Glacier{svc: mock, vaultName: "v", partSize: testPartSize}
+
+	err := g.Put("k", bytes.NewReader([]byte("data")))
+	if !errors.Is(err, common.ErrBackendUnavailable) {
+		t.Fatalf("Put() error = %v, want ErrBackendUnavailable", err)
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package ha
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/locks"
+)
+
+// Elector elects one leader among the instances running a job. Leadership
+// is a lease on the backend: Run acquires it when it is free or has lapsed
+// and refreshes it a few times per lease period. An instance that cannot
+// refresh its lease in time, for example because it lost its connection to
+// the backend, stops considering itself leader when the lease expires,
+// before another instance can take it over.
+type Elector struct {
+	cluster *Cluster
+	name    string
+
+	mu    sync.Mutex
+	lease *locks.Lock
+}
+
+// Name returns the job name.
+func (e *Elector) Name() string {
+	return e.name
+}
+
+// IsLeader reports whether this instance holds an unexpired lease.
+func (e *Elector) IsLeader() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.lease != nil && e.cluster.now().Before(e.lease.ExpiresAt)
+}
+
+// Leader returns the current lease, whose Owner is the leader's node ID,
+// or locks.ErrLockNotFound when there is no leader.
+func (e *Elector) Leader(ctx context.Context) (*locks.Lock, error) {
+	return e.cluster.leases.Get(ctx, e.name)
+}
+
+// Run campaigns for leadership until ctx is done, then resigns. It blocks;
+// run it in a goroutine.
+func (e *Elector) Run(ctx context.Context) {
+	interval := e.cluster.ttl / 3
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		_ = e.Step(ctx)
+		select {
+		case <-ctx.Done():
+			e.Resign(context.Background())
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Step makes one election round: the leader refreshes its lease and other
+// instances try to acquire it. It returns an error only when the backend
+// fails; losing the election is not an error.
+func (e *Elector) Step(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.lease != nil {
+		lease, err := e.cluster.leases.Refresh(ctx, e.name, e.lease.Token, e.cluster.ttl)
+		if err == nil {
+			e.lease = lease
+			return nil
+		}
+		if !errors.Is(err, locks.ErrNotHeld) && !errors.Is(err, locks.ErrLockNotFound) {
+			// Keep the lease until it expires; the next round retries.
+			return err
+		}
+		e.lease = nil
+	}
+
+	lease, err := e.cluster.leases.TryLock(ctx, e.name, e.cluster.node, e.cluster.ttl)
+	if errors.Is(err, locks.ErrLocked) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	e.lease = lease
+	return nil
+}
+
+// Resign gives up leadership, releasing the lease so another instance can
+// take over without waiting for it to expire.
+func (e *Elector) Resign(ctx context.Context) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.lease != nil {
+		_ = e.cluster.leases.Unlock(ctx, e.name, e.lease.Token)
+		e.lease = nil
+	}
+}
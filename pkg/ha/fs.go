@@ -0,0 +1,216 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package ha
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/replication"
+)
+
+// FileSystem is a replication.FileSystem that keeps files as objects under
+// the coordination prefix, so every instance works on the same policy
+// files. A file is read whole when opened and written whole when synced or
+// closed. Concurrent changes from two instances are not merged: the last
+// write wins.
+type FileSystem struct {
+	cluster *Cluster
+}
+
+var _ replication.FileSystem = (*FileSystem)(nil)
+
+// OpenFile opens name. os.O_CREATE, os.O_TRUNC and os.O_APPEND are
+// honored; a missing file without os.O_CREATE returns an error satisfying
+// os.IsNotExist.
+func (fs *FileSystem) OpenFile(name string, flag int, _ os.FileMode) (replication.ReplicationFile, error) {
+	ctx := context.Background()
+	key := fs.key(name)
+	f := &file{fs: fs, key: key, writable: flag&(os.O_WRONLY|os.O_RDWR) != 0}
+
+	if flag&os.O_TRUNC == 0 {
+		data, err := fs.read(ctx, key)
+		switch {
+		case err == nil:
+			f.data = data
+		case errors.Is(err, os.ErrNotExist) && flag&os.O_CREATE != 0:
+		default:
+			return nil, err
+		}
+	}
+	if flag&os.O_APPEND != 0 {
+		f.off = int64(len(f.data))
+	}
+	if flag&(os.O_CREATE|os.O_TRUNC) != 0 && f.writable {
+		f.dirty = true
+	}
+	return f, nil
+}
+
+// Remove deletes name.
+func (fs *FileSystem) Remove(name string) error {
+	err := fs.cluster.storage.DeleteWithContext(context.Background(), fs.key(name))
+	if errors.Is(err, common.ErrNotFound) {
+		return &os.PathError{Op: "remove", Path: name, Err: os.ErrNotExist}
+	}
+	return err
+}
+
+// Rename replaces dst with src. Readers see either the old or the new
+// contents of dst, never a partial file.
+func (fs *FileSystem) Rename(src, dst string) error {
+	ctx := context.Background()
+	data, err := fs.read(ctx, fs.key(src))
+	if err != nil {
+		return err
+	}
+	if err := fs.write(ctx, fs.key(dst), data); err != nil {
+		return err
+	}
+	return fs.cluster.storage.DeleteWithContext(ctx, fs.key(src))
+}
+
+// key maps a file name to its object key. Directories in name are kept
+// so distinct paths do not collide.
+func (fs *FileSystem) key(name string) string {
+	clean := strings.TrimPrefix(path.Clean("/"+name), "/")
+	return fs.cluster.prefix + stateDir + clean
+}
+
+func (fs *FileSystem) read(ctx context.Context, key string) ([]byte, error) {
+	rc, err := fs.cluster.storage.GetWithContext(ctx, key)
+	if errors.Is(err, common.ErrNotFound) {
+		return nil, &os.PathError{Op: "open", Path: key, Err: os.ErrNotExist}
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rc.Close() }()
+	return io.ReadAll(rc)
+}
+
+func (fs *FileSystem) write(ctx context.Context, key string, data []byte) error {
+	return fs.cluster.storage.PutWithMetadata(ctx, key, bytes.NewReader(data), &common.Metadata{
+		ContentType: "application/json",
+	})
+}
+
+// file is an open FileSystem file, buffered in memory.
+type file struct {
+	fs       *FileSystem
+	key      string
+	data     []byte
+	off      int64
+	writable bool
+	dirty    bool
+	closed   bool
+}
+
+func (f *file) Read(p []byte) (int, error) {
+	if f.closed {
+		return 0, os.ErrClosed
+	}
+	if f.off >= int64(len(f.data)) {
+		return 0, io.EOF
+	}
+	n := copy(p, f.data[f.off:])
+	f.off += int64(n)
+	return n, nil
+}
+
+func (f *file) Write(p []byte) (int, error) {
+	if f.closed {
+		return 0, os.ErrClosed
+	}
+	if !f.writable {
+		return 0, fmt.Errorf("%s: file not open for writing", f.key)
+	}
+	end := f.off + int64(len(p))
+	if end > int64(len(f.data)) {
+		grown := make([]byte, end)
+		copy(grown, f.data)
+		f.data = grown
+	}
+	copy(f.data[f.off:], p)
+	f.off = end
+	f.dirty = true
+	return len(p), nil
+}
+
+func (f *file) Seek(offset int64, whence int) (int64, error) {
+	var pos int64
+	switch whence {
+	case io.SeekStart:
+		pos = offset
+	case io.SeekCurrent:
+		pos = f.off + offset
+	case io.SeekEnd:
+		pos = int64(len(f.data)) + offset
+	default:
+		return 0, fmt.Errorf("invalid whence %d", whence)
+	}
+	if pos < 0 {
+		return 0, fmt.Errorf("negative position %d", pos)
+	}
+	f.off = pos
+	return pos, nil
+}
+
+func (f *file) Truncate(size int64) error {
+	if !f.writable {
+		return fmt.Errorf("%s: file not open for writing", f.key)
+	}
+	if size < 0 {
+		return fmt.Errorf("negative size %d", size)
+	}
+	if size <= int64(len(f.data)) {
+		f.data = f.data[:size]
+	} else {
+		f.data = append(f.data, make([]byte, size-int64(len(f.data)))...)
+	}
+	f.dirty = true
+	return nil
+}
+
+// Sync writes the file to the backend if it changed.
+func (f *file) Sync() error {
+	if f.closed {
+		return os.ErrClosed
+	}
+	if !f.dirty {
+		return nil
+	}
+	if err := f.fs.write(context.Background(), f.key, f.data); err != nil {
+		return err
+	}
+	f.dirty = false
+	return nil
+}
+
+// Close syncs and closes the file.
+func (f *file) Close() error {
+	if f.closed {
+		return os.ErrClosed
+	}
+	err := f.Sync()
+	f.closed = true
+	return err
+}
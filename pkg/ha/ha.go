@@ -0,0 +1,171 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package ha lets several objstore-server instances share one backend
+// behind a load balancer. Instead of a separate coordination service, the
+// instances coordinate through the backend itself with conditional writes:
+//
+//   - An Elector elects one leader per job (for example replication) with a
+//     lease that the leader refreshes and others take over once it lapses,
+//     so background jobs run on exactly one instance.
+//   - An IdempotencyStore keeps idempotency records on the backend, so a
+//     retried request is deduplicated whichever instance it reaches.
+//   - A FileSystem keeps policy files such as the replication policies on
+//     the backend, where every instance reads and changes the same copy.
+//
+// All of this lives under a reserved key prefix (DefaultPrefix) that
+// Storage hides from clients. The backend must support conditional writes
+// (common.ConditionalWriter).
+package ha
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/locks"
+)
+
+// DefaultPrefix is the key prefix coordination state is stored under when
+// none is configured.
+const DefaultPrefix = ".ha/"
+
+// DefaultLeaseTTL is how long a leader's lease lasts without a refresh.
+// A failed leader is replaced within about this long.
+const DefaultLeaseTTL = 15 * time.Second
+
+// Namespaces under the prefix.
+const (
+	leasesDir      = "leases/"
+	idempotencyDir = "idempotency/"
+	stateDir       = "state/"
+)
+
+var (
+	// ErrNotSupported is returned by New for backends that cannot write
+	// conditionally.
+	ErrNotSupported = errors.New("high availability requires a backend with conditional writes")
+
+	// ErrProcessLocal is returned by objstore.EnableHA for backends whose
+	// conditional writes only serialize writers within one process, such
+	// as memory and local.
+	ErrProcessLocal = errors.New("high availability requires conditional writes that are atomic across processes")
+)
+
+// Config configures a Cluster. Zero fields take their defaults.
+type Config struct {
+	// NodeID identifies this instance in leases. It defaults to the host
+	// name and must differ between instances.
+	NodeID string
+
+	// Prefix is the reserved key prefix for coordination state
+	// (default: DefaultPrefix).
+	Prefix string
+
+	// LeaseTTL is the leader lease duration (default: DefaultLeaseTTL).
+	LeaseTTL time.Duration
+}
+
+// Cluster is this instance's view of the instances sharing a backend.
+type Cluster struct {
+	storage common.Storage
+	writer  common.ConditionalWriter
+	prefix  string
+	node    string
+	ttl     time.Duration
+	leases  *locks.Manager
+	now     func() time.Time
+}
+
+// New returns a Cluster coordinating through storage, which must implement
+// common.ConditionalWriter.
+func New(storage common.Storage, cfg Config) (*Cluster, error) {
+	writer, ok := storage.(common.ConditionalWriter)
+	if !ok {
+		return nil, ErrNotSupported
+	}
+	prefix := cfg.Prefix
+	if prefix == "" {
+		prefix = DefaultPrefix
+	}
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	node := cfg.NodeID
+	if node == "" {
+		host, err := os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("failed to determine node ID: %w", err)
+		}
+		node = host
+	}
+	ttl := cfg.LeaseTTL
+	if ttl == 0 {
+		ttl = DefaultLeaseTTL
+	}
+	if ttl < locks.MinTTL || ttl > locks.MaxTTL {
+		return nil, locks.ErrInvalidTTL
+	}
+	leases, err := locks.NewManager(storage, prefix+leasesDir)
+	if err != nil {
+		return nil, err
+	}
+	return &Cluster{
+		storage: storage,
+		writer:  writer,
+		prefix:  prefix,
+		node:    node,
+		ttl:     ttl,
+		leases:  leases,
+		now:     time.Now,
+	}, nil
+}
+
+// NodeID returns this instance's ID.
+func (c *Cluster) NodeID() string {
+	return c.node
+}
+
+// Prefix returns the reserved key prefix.
+func (c *Cluster) Prefix() string {
+	return c.prefix
+}
+
+// Storage returns the backend the cluster coordinates through.
+func (c *Cluster) Storage() common.Storage {
+	return c.storage
+}
+
+// Reserved reports whether key is in the coordination namespace.
+func (c *Cluster) Reserved(key string) bool {
+	return common.IsReserved(key, c.prefix)
+}
+
+// Elector returns an elector for the job called name. Every instance
+// running the job must use the same name.
+func (c *Cluster) Elector(name string) *Elector {
+	return &Elector{cluster: c, name: name}
+}
+
+// IdempotencyStore returns the shared idempotency store.
+func (c *Cluster) IdempotencyStore() *IdempotencyStore {
+	return &IdempotencyStore{cluster: c}
+}
+
+// FileSystem returns the shared file system for policy files.
+func (c *Cluster) FileSystem() *FileSystem {
+	return &FileSystem{cluster: c}
+}
@@ -0,0 +1,342 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package ha
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+	"github.com/jeremyhahn/go-objstore/pkg/replication"
+	"github.com/jeremyhahn/go-objstore/pkg/server/middleware"
+)
+
+// plainStorage hides a backend's optional interfaces.
+type plainStorage struct {
+	common.Storage
+}
+
+func newTestCluster(t *testing.T, backend common.Storage, node string) *Cluster {
+	t.Helper()
+	cluster, err := New(backend, Config{NodeID: node, LeaseTTL: 3 * time.Second})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return cluster
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(plainStorage{memory.New()}, Config{}); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("expected ErrNotSupported, got %v", err)
+	}
+	if _, err := New(memory.New(), Config{LeaseTTL: time.Millisecond}); err == nil {
+		t.Error("expected a too short lease TTL to be rejected")
+	}
+
+	cluster, err := New(memory.New(), Config{Prefix: "coord"})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if cluster.Prefix() != "coord/" {
+		t.Errorf("expected prefix coord/, got %q", cluster.Prefix())
+	}
+	if cluster.NodeID() == "" {
+		t.Error("expected node ID to default to the host name")
+	}
+	if !cluster.Reserved("coord/leases/jobs") || !cluster.Reserved("coord") || cluster.Reserved("coordinates") {
+		t.Error("unexpected Reserved result")
+	}
+}
+
+func TestElector(t *testing.T) {
+	ctx := context.Background()
+	backend := memory.New()
+	a := newTestCluster(t, backend, "a").Elector("jobs")
+	b := newTestCluster(t, backend, "b").Elector("jobs")
+
+	if err := a.Step(ctx); err != nil {
+		t.Fatalf("Step a: %v", err)
+	}
+	if err := b.Step(ctx); err != nil {
+		t.Fatalf("Step b: %v", err)
+	}
+	if !a.IsLeader() || b.IsLeader() {
+		t.Fatalf("expected only a to lead, got a=%v b=%v", a.IsLeader(), b.IsLeader())
+	}
+
+	// Refreshing keeps the lease.
+	if err := a.Step(ctx); err != nil || !a.IsLeader() {
+		t.Fatalf("expected a to keep leading, err=%v", err)
+	}
+	leader, err := b.Leader(ctx)
+	if err != nil || leader.Owner != "a" {
+		t.Fatalf("expected leader a, got %+v, %v", leader, err)
+	}
+
+	// A different job has its own leader.
+	other := newTestCluster(t, backend, "b").Elector("scrub")
+	if err := other.Step(ctx); err != nil || !other.IsLeader() {
+		t.Fatalf("expected b to lead another job, err=%v", err)
+	}
+
+	// After a resigns, b takes over.
+	a.Resign(ctx)
+	if a.IsLeader() {
+		t.Error("expected a to stop leading after resigning")
+	}
+	if err := b.Step(ctx); err != nil || !b.IsLeader() {
+		t.Fatalf("expected b to take over, err=%v", err)
+	}
+	if err := a.Step(ctx); err != nil || a.IsLeader() {
+		t.Fatalf("expected a to follow, err=%v", err)
+	}
+}
+
+func TestElector_LeaseExpiry(t *testing.T) {
+	ctx := context.Background()
+	cluster := newTestCluster(t, memory.New(), "a")
+	now := time.Now()
+	cluster.now = func() time.Time { return now }
+
+	e := cluster.Elector("jobs")
+	if err := e.Step(ctx); err != nil || !e.IsLeader() {
+		t.Fatalf("expected to lead, err=%v", err)
+	}
+	// A leader that could not refresh stops leading when its lease ends.
+	now = now.Add(cluster.ttl + time.Second)
+	if e.IsLeader() {
+		t.Error("expected leadership to end with the lease")
+	}
+}
+
+func TestElector_Run(t *testing.T) {
+	backend := memory.New()
+	a := newTestCluster(t, backend, "a").Elector("jobs")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		a.Run(ctx)
+		close(done)
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !a.IsLeader() {
+		if time.Now().After(deadline) {
+			t.Fatal("expected Run to acquire leadership")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	if _, err := a.Leader(context.Background()); err == nil {
+		t.Error("expected Run to release the lease when stopped")
+	}
+}
+
+func TestIdempotencyStore(t *testing.T) {
+	ctx := context.Background()
+	backend := memory.New()
+	a := newTestCluster(t, backend, "a").IdempotencyStore()
+	b := newTestCluster(t, backend, "b").IdempotencyStore()
+
+	rec, err := a.Begin(ctx, "rest:alice:key1", time.Minute)
+	if err != nil || rec != nil {
+		t.Fatalf("expected reservation, got %v, %v", rec, err)
+	}
+	// Another instance sees the running request.
+	if _, err := b.Begin(ctx, "rest:alice:key1", time.Minute); !errors.Is(err, middleware.ErrIdempotencyInProgress) {
+		t.Fatalf("expected ErrIdempotencyInProgress, got %v", err)
+	}
+
+	want := &middleware.IdempotencyRecord{Fingerprint: "fp", StatusCode: 201, Body: []byte("ok")}
+	if err := a.Complete(ctx, "rest:alice:key1", want, time.Hour); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	rec, err = b.Begin(ctx, "rest:alice:key1", time.Minute)
+	if err != nil || rec == nil || rec.Fingerprint != "fp" || string(rec.Body) != "ok" {
+		t.Fatalf("expected the completed record, got %+v, %v", rec, err)
+	}
+
+	// Releasing keeps completed records but frees reservations.
+	if err := b.Release(ctx, "rest:alice:key1"); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	if rec, _ := a.Begin(ctx, "rest:alice:key1", time.Minute); rec == nil {
+		t.Error("expected Release to keep the completed record")
+	}
+	if _, err := a.Begin(ctx, "rest:alice:key2", time.Minute); err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	if err := a.Release(ctx, "rest:alice:key2"); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	if rec, err := b.Begin(ctx, "rest:alice:key2", time.Minute); err != nil || rec != nil {
+		t.Errorf("expected released key to be reservable, got %v, %v", rec, err)
+	}
+}
+
+func TestIdempotencyStore_ExpiryAndPurge(t *testing.T) {
+	ctx := context.Background()
+	backend := memory.New()
+	cluster := newTestCluster(t, backend, "a")
+	now := time.Now()
+	cluster.now = func() time.Time { return now }
+	store := cluster.IdempotencyStore()
+
+	if _, err := store.Begin(ctx, "k1", time.Minute); err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	if err := store.Complete(ctx, "k2", &middleware.IdempotencyRecord{}, time.Hour); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+
+	// An abandoned reservation can be taken over once it expires.
+	now = now.Add(2 * time.Minute)
+	if rec, err := store.Begin(ctx, "k1", time.Minute); err != nil || rec != nil {
+		t.Fatalf("expected takeover of expired reservation, got %v, %v", rec, err)
+	}
+
+	now = now.Add(2 * time.Minute)
+	removed, err := store.Purge(ctx)
+	if err != nil {
+		t.Fatalf("Purge: %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("expected 1 expired entry removed, got %d", removed)
+	}
+	if rec, err := store.Begin(ctx, "k2", time.Minute); err != nil || rec == nil {
+		t.Errorf("expected unexpired record to survive purge, got %v, %v", rec, err)
+	}
+}
+
+func TestFileSystem(t *testing.T) {
+	backend := memory.New()
+	fs := newTestCluster(t, backend, "a").FileSystem()
+
+	if _, err := fs.OpenFile("state/policies.json", os.O_RDONLY, 0); !os.IsNotExist(err) {
+		t.Fatalf("expected not-exist error, got %v", err)
+	}
+
+	f, err := fs.OpenFile("state/policies.json.tmp", os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		t.Fatalf("OpenFile: %v", err)
+	}
+	if _, err := f.Write([]byte(`{"policies":{}}`)); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := fs.Rename("state/policies.json.tmp", "state/policies.json"); err != nil {
+		t.Fatalf("Rename: %v", err)
+	}
+
+	f, err = fs.OpenFile("state/policies.json", os.O_RDONLY, 0)
+	if err != nil {
+		t.Fatalf("OpenFile: %v", err)
+	}
+	data, _ := io.ReadAll(f)
+	_ = f.Close()
+	if string(data) != `{"policies":{}}` {
+		t.Errorf("unexpected contents %q", data)
+	}
+
+	keys, _ := backend.List("")
+	if len(keys) != 1 || keys[0] != DefaultPrefix+"state/state/policies.json" {
+		t.Errorf("expected one object under the prefix, got %v", keys)
+	}
+	if err := fs.Remove("state/policies.json.tmp"); !os.IsNotExist(err) {
+		t.Errorf("expected not-exist error removing renamed file, got %v", err)
+	}
+}
+
+func TestFileSystem_SharedReplicationPolicies(t *testing.T) {
+	backend := memory.New()
+	newManager := func(node string) *replication.PersistentReplicationManager {
+		fs := newTestCluster(t, backend, node).FileSystem()
+		m, err := replication.NewPersistentReplicationManager(fs, ".replication-policies.json", time.Minute, nil, nil)
+		if err != nil {
+			t.Fatalf("NewPersistentReplicationManager: %v", err)
+		}
+		m.SetShared(true)
+		return m
+	}
+	a, b := newManager("a"), newManager("b")
+
+	policy := common.ReplicationPolicy{
+		ID:                 "p1",
+		SourceBackend:      "local",
+		DestinationBackend: "local",
+		Enabled:            true,
+	}
+	if err := a.AddPolicy(policy); err != nil {
+		t.Fatalf("AddPolicy: %v", err)
+	}
+	if _, err := b.GetPolicy("p1"); err != nil {
+		t.Fatalf("expected b to see a's policy: %v", err)
+	}
+	if err := b.RemovePolicy("p1"); err != nil {
+		t.Fatalf("RemovePolicy: %v", err)
+	}
+	policies, err := a.GetPolicies()
+	if err != nil || len(policies) != 0 {
+		t.Errorf("expected a to see the removal, got %v, %v", policies, err)
+	}
+}
+
+func TestStorage(t *testing.T) {
+	ctx := context.Background()
+	backend := memory.New()
+	cluster := newTestCluster(t, backend, "a")
+	s := NewStorage(backend, cluster)
+
+	if _, err := cluster.IdempotencyStore().Begin(ctx, "k", time.Minute); err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	if err := s.Put("data.txt", strings.NewReader("x")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	if err := s.Put(DefaultPrefix+"state/x", strings.NewReader("x")); !errors.Is(err, common.ErrReservedKey) {
+		t.Errorf("expected common.ErrReservedKey, got %v", err)
+	}
+	if !errors.Is(common.ErrReservedKey, common.ErrPermissionDenied) {
+		t.Error("expected common.ErrReservedKey to wrap ErrPermissionDenied")
+	}
+	if _, err := s.Get(DefaultPrefix + "state/x"); !errors.Is(err, common.ErrReservedKey) {
+		t.Errorf("expected common.ErrReservedKey, got %v", err)
+	}
+
+	keys, err := s.List("")
+	if err != nil || len(keys) != 1 || keys[0] != "data.txt" {
+		t.Errorf("expected coordination objects to be hidden, got %v, %v", keys, err)
+	}
+	result, err := s.ListWithOptions(ctx, &common.ListOptions{Delimiter: "/"})
+	if err != nil {
+		t.Fatalf("ListWithOptions: %v", err)
+	}
+	if len(result.CommonPrefixes) != 0 || len(result.Objects) != 1 {
+		t.Errorf("expected coordination prefix to be hidden, got %+v", result)
+	}
+	if s.Cluster() != cluster || s.Underlying() != backend {
+		t.Error("unexpected accessors")
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package ha
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/server/middleware"
+)
+
+// maxIdempotencyAttempts bounds how often Begin retries after losing a
+// race for an expired entry.
+const maxIdempotencyAttempts = 3
+
+// IdempotencyStore is a middleware.IdempotencyStore that keeps records on
+// the backend, so every instance sees requests served by the others. A key
+// is reserved by creating its entry conditionally; an expired entry is
+// taken over conditionally on its ETag.
+type IdempotencyStore struct {
+	cluster *Cluster
+}
+
+var _ middleware.IdempotencyStore = (*IdempotencyStore)(nil)
+
+// idempotencyEntry is the stored form of a reservation or completed record.
+type idempotencyEntry struct {
+	ExpiresAt time.Time                     `json:"expires_at"`
+	Record    *middleware.IdempotencyRecord `json:"record,omitempty"`
+}
+
+// Begin implements middleware.IdempotencyStore.
+func (s *IdempotencyStore) Begin(ctx context.Context, key string, ttl time.Duration) (*middleware.IdempotencyRecord, error) {
+	for attempt := 0; attempt < maxIdempotencyAttempts; attempt++ {
+		entry, etag, err := s.read(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		now := s.cluster.now()
+		if entry != nil && now.Before(entry.ExpiresAt) {
+			if entry.Record == nil {
+				return nil, middleware.ErrIdempotencyInProgress
+			}
+			return entry.Record, nil
+		}
+
+		err = s.write(ctx, key, &idempotencyEntry{ExpiresAt: now.Add(ttl)}, etag, true)
+		if err == nil {
+			return nil, nil
+		}
+		if !common.LostRace(err) {
+			return nil, err
+		}
+	}
+	return nil, middleware.ErrIdempotencyInProgress
+}
+
+// Complete implements middleware.IdempotencyStore.
+func (s *IdempotencyStore) Complete(ctx context.Context, key string, record *middleware.IdempotencyRecord, ttl time.Duration) error {
+	return s.write(ctx, key, &idempotencyEntry{
+		ExpiresAt: s.cluster.now().Add(ttl),
+		Record:    record,
+	}, "", false)
+}
+
+// Release implements middleware.IdempotencyStore. Completed records are
+// kept.
+func (s *IdempotencyStore) Release(ctx context.Context, key string) error {
+	entry, etag, err := s.read(ctx, key)
+	if err != nil || entry == nil || entry.Record != nil {
+		return err
+	}
+	// Deleting conditionally leaves a reservation taken over after expiry
+	// by another request alone.
+	err = s.cluster.writer.DeleteIfMatch(ctx, s.entryKey(key), etag)
+	if common.LostRace(err) || errors.Is(err, common.ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// Purge deletes expired entries and returns how many it removed. The
+// middleware never reads an expired entry, so this only reclaims space;
+// run it periodically on one instance.
+func (s *IdempotencyStore) Purge(ctx context.Context) (int, error) {
+	prefix := s.cluster.prefix + idempotencyDir
+	keys, err := s.cluster.storage.ListWithContext(ctx, prefix)
+	if err != nil {
+		return 0, err
+	}
+	now := s.cluster.now()
+	removed := 0
+	for _, objKey := range keys {
+		if err := ctx.Err(); err != nil {
+			return removed, err
+		}
+		entry, _, err := s.readObject(ctx, objKey)
+		if err != nil || (entry != nil && now.Before(entry.ExpiresAt)) {
+			continue
+		}
+		if err := s.cluster.storage.DeleteWithContext(ctx, objKey); err == nil {
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// entryKey hashes key, which the middleware scopes by transport and
+// principal, into a fixed-length object key.
+func (s *IdempotencyStore) entryKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return s.cluster.prefix + idempotencyDir + hex.EncodeToString(sum[:])
+}
+
+func (s *IdempotencyStore) read(ctx context.Context, key string) (*idempotencyEntry, string, error) {
+	return s.readObject(ctx, s.entryKey(key))
+}
+
+// readObject returns the entry stored at objKey and its ETag, or nil when
+// there is none. Unreadable entries are treated as expired.
+func (s *IdempotencyStore) readObject(ctx context.Context, objKey string) (*idempotencyEntry, string, error) {
+	metadata, err := s.cluster.storage.GetMetadata(ctx, objKey)
+	if errors.Is(err, common.ErrNotFound) {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	rc, err := s.cluster.storage.GetWithContext(ctx, objKey)
+	if errors.Is(err, common.ErrNotFound) {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	data, err := io.ReadAll(rc)
+	_ = rc.Close()
+	if err != nil {
+		return nil, "", err
+	}
+	var entry idempotencyEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return &idempotencyEntry{}, metadata.ETag, nil
+	}
+	return &entry, metadata.ETag, nil
+}
+
+// write stores entry under key. A conditional write requires the stored
+// entry to still have etag, or not to exist when etag is empty.
+func (s *IdempotencyStore) write(ctx context.Context, key string, entry *idempotencyEntry, etag string, conditional bool) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	metadata := &common.Metadata{ContentType: "application/json"}
+	if conditional {
+		return s.cluster.writer.PutIfMatch(ctx, s.entryKey(key), bytes.NewReader(data), metadata, etag)
+	}
+	return s.cluster.storage.PutWithMetadata(ctx, s.entryKey(key), bytes.NewReader(data), metadata)
+}
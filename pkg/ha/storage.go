@@ -0,0 +1,36 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package ha
+
+import "github.com/jeremyhahn/go-objstore/pkg/common"
+
+// Storage wraps a backend and reserves a Cluster's namespace: object
+// operations on keys under the coordination prefix fail with
+// common.ErrReservedKey and listings leave them out, so clients cannot
+// tamper with leases, idempotency records or shared policy files.
+type Storage struct {
+	*common.ReservedStorage
+	cluster *Cluster
+}
+
+// NewStorage returns underlying wrapped so that cluster's prefix is
+// reserved.
+func NewStorage(underlying common.Storage, cluster *Cluster) *Storage {
+	return &Storage{ReservedStorage: common.NewReservedStorage(underlying, cluster.Prefix()), cluster: cluster}
+}
+
+// Cluster returns the cluster whose namespace s reserves.
+func (s *Storage) Cluster() *Cluster {
+	return s.cluster
+}
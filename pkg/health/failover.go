@@ -0,0 +1,323 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package health
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/audit"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// ErrSecondaryReadOnly is returned for writes while a backend has failed
+// over to a read-only secondary.
+var ErrSecondaryReadOnly = fmt.Errorf("%w: backend failed over to a read-only secondary", common.ErrUnavailable)
+
+// FailoverConfig configures a FailoverStorage.
+type FailoverConfig struct {
+	// Secondary is the name of the secondary backend, used in logs, audit
+	// events and transitions.
+	Secondary string
+
+	// ReadOnly only serves reads from the secondary while failed over;
+	// writes fail with ErrSecondaryReadOnly. Use it when the secondary is a
+	// replica whose writes would never reach the primary.
+	ReadOnly bool
+
+	// Logger logs failovers and failbacks. If nil, a no-op logger is used.
+	Logger adapters.Logger
+
+	// AuditLog records failovers and failbacks as BACKEND_FAILOVER and
+	// BACKEND_FAILBACK events. If nil, a no-op audit logger is used.
+	AuditLog audit.AuditLogger
+
+	// OnTransition, if set, is called after every failover and failback.
+	// It is called from the monitor's probing goroutine and should not
+	// block.
+	OnTransition func(Transition)
+}
+
+// Transition describes a failover to the secondary or a failback to the
+// primary.
+type Transition struct {
+	// Backend is the name of the primary backend.
+	Backend string
+
+	// Secondary is the name of the secondary backend.
+	Secondary string
+
+	// FailedOver is true for a failover and false for a failback.
+	FailedOver bool
+
+	// Status is the primary's health that caused the transition.
+	Status Status
+
+	// At is when the transition happened.
+	At time.Time
+}
+
+// FailoverStorage routes operations to a primary backend while its monitor
+// reports it healthy, and to a secondary backend while it does not.
+// Lifecycle policies and Configure always apply to the primary.
+//
+// Objects written to the secondary while failed over are not copied back
+// to the primary on failback; pair failover with replication from the
+// secondary to reconcile them.
+type FailoverStorage struct {
+	common.Storage
+	secondary  common.Storage
+	monitor    *Monitor
+	cfg        FailoverConfig
+	failedOver atomic.Bool
+}
+
+// NewFailoverStorage returns primary wrapped so that operations move to
+// secondary while monitor reports primary unhealthy. The caller owns
+// monitor and should Start it and Close it on shutdown.
+func NewFailoverStorage(primary, secondary common.Storage, monitor *Monitor, cfg *FailoverConfig) *FailoverStorage {
+	s := &FailoverStorage{Storage: primary, secondary: secondary, monitor: monitor}
+	if cfg != nil {
+		s.cfg = *cfg
+	}
+	if s.cfg.Logger == nil {
+		s.cfg.Logger = adapters.NewNoOpLogger()
+	}
+	if s.cfg.AuditLog == nil {
+		s.cfg.AuditLog = audit.NewNoOpAuditLogger()
+	}
+
+	monitor.OnChange(s.transition)
+	s.failedOver.Store(!monitor.Healthy())
+	return s
+}
+
+// Monitor returns the monitor of the primary backend.
+func (s *FailoverStorage) Monitor() *Monitor {
+	return s.monitor
+}
+
+// FailedOver reports whether operations are currently routed to the
+// secondary backend.
+func (s *FailoverStorage) FailedOver() bool {
+	return s.failedOver.Load()
+}
+
+// Underlying returns the primary backend.
+func (s *FailoverStorage) Underlying() common.Storage {
+	return s.Storage
+}
+
+// transition moves traffic to or from the secondary when the primary's
+// health changes, and reports the move.
+func (s *FailoverStorage) transition(status Status) {
+	failedOver := !status.Healthy
+	if s.failedOver.Swap(failedOver) == failedOver {
+		return
+	}
+
+	primary := s.monitor.Backend()
+	Default.recordTransition(primary, failedOver)
+
+	ctx := context.Background()
+	fields := []adapters.Field{
+		{Key: "backend", Value: primary},
+		{Key: "secondary", Value: s.cfg.Secondary},
+	}
+	eventType, action := audit.EventBackendFailback, "failback"
+	if failedOver {
+		eventType, action = audit.EventBackendFailover, "failover"
+		fields = append(fields, adapters.Field{Key: "error", Value: status.LastError})
+		s.cfg.Logger.Warn(ctx, "Backend unhealthy, failing over to secondary", fields...)
+	} else {
+		s.cfg.Logger.Info(ctx, "Backend healthy, failing back from secondary", fields...)
+	}
+
+	_ = s.cfg.AuditLog.LogEvent(ctx, &audit.AuditEvent{ // #nosec G104 -- Audit logging errors are logged internally, should not block failover
+		Timestamp:    status.Since,
+		EventType:    eventType,
+		Resource:     primary,
+		Action:       action,
+		Result:       audit.ResultSuccess,
+		ErrorMessage: status.LastError,
+		Metadata:     map[string]any{"backend": primary, "secondary": s.cfg.Secondary},
+	})
+
+	if s.cfg.OnTransition != nil {
+		s.cfg.OnTransition(Transition{
+			Backend:    primary,
+			Secondary:  s.cfg.Secondary,
+			FailedOver: failedOver,
+			Status:     status,
+			At:         status.Since,
+		})
+	}
+}
+
+// reader returns the backend reads are routed to.
+func (s *FailoverStorage) reader() common.Storage {
+	if s.failedOver.Load() {
+		return s.secondary
+	}
+	return s.Storage
+}
+
+// writer returns the backend writes are routed to, or ErrSecondaryReadOnly.
+func (s *FailoverStorage) writer() (common.Storage, error) {
+	if !s.failedOver.Load() {
+		return s.Storage, nil
+	}
+	if s.cfg.ReadOnly {
+		return nil, fmt.Errorf("%w: %s", ErrSecondaryReadOnly, s.monitor.Backend())
+	}
+	return s.secondary, nil
+}
+
+// Put stores an object in the active backend.
+func (s *FailoverStorage) Put(key string, data io.Reader) error {
+	return s.PutWithContext(context.Background(), key, data)
+}
+
+// PutWithContext stores an object in the active backend.
+func (s *FailoverStorage) PutWithContext(ctx context.Context, key string, data io.Reader) error {
+	w, err := s.writer()
+	if err != nil {
+		return err
+	}
+	return w.PutWithContext(ctx, key, data)
+}
+
+// PutWithMetadata stores an object and its metadata in the active backend.
+func (s *FailoverStorage) PutWithMetadata(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) error {
+	w, err := s.writer()
+	if err != nil {
+		return err
+	}
+	return w.PutWithMetadata(ctx, key, data, metadata)
+}
+
+// PutWithInfo stores an object and its metadata in the active backend and
+// returns what was stored.
+func (s *FailoverStorage) PutWithInfo(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) (*common.ObjectInfo, error) {
+	w, err := s.writer()
+	if err != nil {
+		return nil, err
+	}
+	return common.PutWithInfo(ctx, w, key, data, metadata)
+}
+
+// Get retrieves an object from the active backend.
+func (s *FailoverStorage) Get(key string) (io.ReadCloser, error) {
+	return s.GetWithContext(context.Background(), key)
+}
+
+// GetWithContext retrieves an object from the active backend.
+func (s *FailoverStorage) GetWithContext(ctx context.Context, key string) (io.ReadCloser, error) {
+	return s.reader().GetWithContext(ctx, key)
+}
+
+// GetRange reads a byte range of an object from the active backend,
+// falling back to discarding the leading bytes of a full read when it
+// cannot read ranges.
+func (s *FailoverStorage) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	r := s.reader()
+	if rr, ok := r.(common.RangeReader); ok {
+		return rr.GetRange(ctx, key, offset, length)
+	}
+	rc, err := r.GetWithContext(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return common.SliceRange(rc, offset, length)
+}
+
+// GetAsOf reads an earlier version of an object from the active backend.
+func (s *FailoverStorage) GetAsOf(ctx context.Context, key string, at time.Time) (io.ReadCloser, *common.Metadata, error) {
+	return common.GetAsOf(ctx, s.reader(), key, at)
+}
+
+// GetMetadata retrieves an object's metadata from the active backend.
+func (s *FailoverStorage) GetMetadata(ctx context.Context, key string) (*common.Metadata, error) {
+	return s.reader().GetMetadata(ctx, key)
+}
+
+// UpdateMetadata updates an object's metadata in the active backend.
+func (s *FailoverStorage) UpdateMetadata(ctx context.Context, key string, metadata *common.Metadata) error {
+	w, err := s.writer()
+	if err != nil {
+		return err
+	}
+	return w.UpdateMetadata(ctx, key, metadata)
+}
+
+// Append adds data to the end of an object in the active backend.
+func (s *FailoverStorage) Append(ctx context.Context, key string, data io.Reader) error {
+	w, err := s.writer()
+	if err != nil {
+		return err
+	}
+	return common.Append(ctx, w, key, data)
+}
+
+// Compose concatenates srcKeys into destKey in the active backend.
+func (s *FailoverStorage) Compose(ctx context.Context, destKey string, srcKeys ...string) error {
+	w, err := s.writer()
+	if err != nil {
+		return err
+	}
+	return common.Compose(ctx, w, destKey, srcKeys...)
+}
+
+// Delete removes an object from the active backend.
+func (s *FailoverStorage) Delete(key string) error {
+	return s.DeleteWithContext(context.Background(), key)
+}
+
+// DeleteWithContext removes an object from the active backend.
+func (s *FailoverStorage) DeleteWithContext(ctx context.Context, key string) error {
+	w, err := s.writer()
+	if err != nil {
+		return err
+	}
+	return w.DeleteWithContext(ctx, key)
+}
+
+// Exists checks whether an object exists in the active backend.
+func (s *FailoverStorage) Exists(ctx context.Context, key string) (bool, error) {
+	return s.reader().Exists(ctx, key)
+}
+
+// List lists the keys under prefix in the active backend.
+func (s *FailoverStorage) List(prefix string) ([]string, error) {
+	return s.ListWithContext(context.Background(), prefix)
+}
+
+// ListWithContext lists the keys under prefix in the active backend.
+func (s *FailoverStorage) ListWithContext(ctx context.Context, prefix string) ([]string, error) {
+	return s.reader().ListWithContext(ctx, prefix)
+}
+
+// ListWithOptions lists objects in the active backend.
+func (s *FailoverStorage) ListWithOptions(ctx context.Context, opts *common.ListOptions) (*common.ListResult, error) {
+	return s.reader().ListWithOptions(ctx, opts)
+}
+
+// Archive copies an object from the active backend to destination.
+func (s *FailoverStorage) Archive(key string, destination common.Archiver) error {
+	return s.reader().Archive(key, destination)
+}
@@ -0,0 +1,324 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package health checks the health of storage backends in the background
+// and fails over to a secondary backend while a primary is unhealthy.
+//
+// A Monitor runs a Probe against one backend on an interval. A backend is
+// marked unhealthy after FailureThreshold consecutive failed probes and
+// healthy again after SuccessThreshold consecutive successful ones, so a
+// single slow request does not flap it. FailoverStorage routes operations
+// to a secondary backend while its monitor reports the primary unhealthy.
+//
+// Check counts, health and failover transitions are recorded in the
+// process-wide Default registry, which the metrics endpoint renders.
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+const (
+	// DefaultKey is the key the built-in probes check.
+	DefaultKey = ".health/probe"
+
+	// DefaultInterval is the time between probes.
+	DefaultInterval = 10 * time.Second
+
+	// DefaultTimeout bounds a single probe.
+	DefaultTimeout = 5 * time.Second
+
+	// DefaultFailureThreshold is the number of consecutive failed probes
+	// that mark a backend unhealthy.
+	DefaultFailureThreshold = 3
+
+	// DefaultSuccessThreshold is the number of consecutive successful
+	// probes that mark an unhealthy backend healthy again.
+	DefaultSuccessThreshold = 2
+)
+
+var (
+	// ErrInvalidConfig is returned for a malformed health check configuration.
+	ErrInvalidConfig = fmt.Errorf("%w: invalid health check config", common.ErrInvalidArgument)
+
+	// ErrProbeFailed is returned by the write probe when the object it
+	// reads back differs from the one it wrote.
+	ErrProbeFailed = fmt.Errorf("%w: health probe failed", common.ErrUnavailable)
+)
+
+// Config configures a Monitor. Zero values use the defaults above.
+type Config struct {
+	// Probe names a built-in probe: ProbeExists (the default), ProbeList or
+	// ProbeWrite. It is ignored when Custom is set.
+	Probe string
+
+	// Custom is a caller-supplied probe used instead of a built-in one.
+	Custom Probe
+
+	// Key is the object the built-in probes check, or the prefix
+	// ProbeList lists. ProbeWrite writes and deletes it, so it should not
+	// hold application data.
+	Key string
+
+	// Interval is the time between probes.
+	Interval time.Duration
+
+	// Timeout bounds a single probe.
+	Timeout time.Duration
+
+	// FailureThreshold is the number of consecutive failed probes that
+	// mark the backend unhealthy.
+	FailureThreshold int
+
+	// SuccessThreshold is the number of consecutive successful probes
+	// that mark an unhealthy backend healthy again.
+	SuccessThreshold int
+}
+
+// withDefaults returns a copy of c with zero values replaced by defaults,
+// or an error wrapping ErrInvalidConfig.
+func (c *Config) withDefaults() (Config, error) {
+	var cfg Config
+	if c != nil {
+		cfg = *c
+	}
+	if cfg.Key == "" {
+		cfg.Key = DefaultKey
+	}
+	if cfg.Interval == 0 {
+		cfg.Interval = DefaultInterval
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	if cfg.FailureThreshold == 0 {
+		cfg.FailureThreshold = DefaultFailureThreshold
+	}
+	if cfg.SuccessThreshold == 0 {
+		cfg.SuccessThreshold = DefaultSuccessThreshold
+	}
+	switch {
+	case cfg.Interval < 0:
+		return cfg, fmt.Errorf("%w: negative interval %s", ErrInvalidConfig, cfg.Interval)
+	case cfg.Timeout < 0:
+		return cfg, fmt.Errorf("%w: negative timeout %s", ErrInvalidConfig, cfg.Timeout)
+	case cfg.FailureThreshold < 0:
+		return cfg, fmt.Errorf("%w: negative failure threshold %d", ErrInvalidConfig, cfg.FailureThreshold)
+	case cfg.SuccessThreshold < 0:
+		return cfg, fmt.Errorf("%w: negative success threshold %d", ErrInvalidConfig, cfg.SuccessThreshold)
+	}
+	return cfg, nil
+}
+
+// Status is a snapshot of a backend's health.
+type Status struct {
+	// Backend is the name of the monitored backend.
+	Backend string
+
+	// Healthy reports whether the backend is considered healthy.
+	Healthy bool
+
+	// Since is when Healthy last changed, or when monitoring started.
+	Since time.Time
+
+	// CheckedAt is when the last probe finished. It is zero until the
+	// first probe.
+	CheckedAt time.Time
+
+	// LastError is the error of the last probe, empty if it succeeded.
+	LastError string
+
+	// ConsecutiveFailures counts the failed probes since the last success.
+	ConsecutiveFailures int
+
+	// ConsecutiveSuccesses counts the successful probes since the last
+	// failure.
+	ConsecutiveSuccesses int
+}
+
+// Monitor probes one backend on an interval and tracks its health. A
+// backend starts out healthy. Monitor is safe for concurrent use.
+type Monitor struct {
+	backend string
+	storage common.Storage
+	probe   Probe
+	cfg     Config
+
+	// checkMu serializes probes so listeners see transitions in order.
+	checkMu sync.Mutex
+
+	mu        sync.Mutex
+	status    Status
+	listeners []func(Status)
+
+	startOnce sync.Once
+	closeOnce sync.Once
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+// NewMonitor returns a monitor of storage, reported under the name backend.
+// Call Start to probe in the background, or Check to probe once.
+func NewMonitor(backend string, storage common.Storage, cfg *Config) (*Monitor, error) {
+	if storage == nil {
+		return nil, fmt.Errorf("%w: nil storage", ErrInvalidConfig)
+	}
+	c, err := cfg.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+	probe := c.Custom
+	if probe == nil {
+		if probe, err = NewProbe(c.Probe, c.Key); err != nil {
+			return nil, err
+		}
+	}
+
+	Default.register(backend)
+	return &Monitor{
+		backend: backend,
+		storage: storage,
+		probe:   probe,
+		cfg:     c,
+		status:  Status{Backend: backend, Healthy: true, Since: time.Now()},
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}, nil
+}
+
+// Backend returns the name of the monitored backend.
+func (m *Monitor) Backend() string {
+	return m.backend
+}
+
+// Start probes the backend every Interval in a background goroutine until
+// Close is called. Calling Start again has no effect.
+func (m *Monitor) Start() {
+	m.startOnce.Do(func() {
+		go m.run()
+	})
+}
+
+// run probes the backend until the monitor is closed.
+func (m *Monitor) run() {
+	defer close(m.done)
+
+	ticker := time.NewTicker(m.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		_ = m.Check(context.Background()) // #nosec G104 -- The result is recorded in the monitor's status
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Close stops background probing and waits for an in-flight probe to
+// finish.
+func (m *Monitor) Close() error {
+	m.closeOnce.Do(func() {
+		close(m.stop)
+		started := true
+		m.startOnce.Do(func() { started = false })
+		if started {
+			<-m.done
+		}
+	})
+	return nil
+}
+
+// Check probes the backend once, bounded by Timeout, records the result
+// and returns the probe's error.
+func (m *Monitor) Check(ctx context.Context) error {
+	m.checkMu.Lock()
+	defer m.checkMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, m.cfg.Timeout)
+	err := m.probe.Check(ctx, m.storage)
+	cancel()
+
+	m.record(err)
+	return err
+}
+
+// record updates the status with the result of a probe and notifies the
+// listeners when the backend's health changed.
+func (m *Monitor) record(err error) {
+	now := time.Now()
+
+	m.mu.Lock()
+	s := &m.status
+	s.CheckedAt = now
+	changed := false
+	if err != nil {
+		s.LastError = err.Error()
+		s.ConsecutiveFailures++
+		s.ConsecutiveSuccesses = 0
+		if s.Healthy && s.ConsecutiveFailures >= m.cfg.FailureThreshold {
+			s.Healthy, s.Since, changed = false, now, true
+		}
+	} else {
+		s.LastError = ""
+		s.ConsecutiveSuccesses++
+		s.ConsecutiveFailures = 0
+		if !s.Healthy && s.ConsecutiveSuccesses >= m.cfg.SuccessThreshold {
+			s.Healthy, s.Since, changed = true, now, true
+		}
+	}
+	status := *s
+	listeners := append([]func(Status){}, m.listeners...)
+	m.mu.Unlock()
+
+	Default.recordCheck(m.backend, err == nil, status.Healthy)
+	if changed {
+		for _, fn := range listeners {
+			fn(status)
+		}
+	}
+}
+
+// Status returns a snapshot of the backend's health.
+func (m *Monitor) Status() Status {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.status
+}
+
+// Healthy reports whether the backend is considered healthy.
+func (m *Monitor) Healthy() bool {
+	return m.Status().Healthy
+}
+
+// OnChange registers fn to be called with the new status whenever the
+// backend becomes unhealthy or healthy again. fn is called from the
+// probing goroutine and should not block.
+func (m *Monitor) OnChange(fn func(Status)) {
+	m.mu.Lock()
+	m.listeners = append(m.listeners, fn)
+	m.mu.Unlock()
+}
+
+// isNotFound reports whether err means the probed object does not exist,
+// which still proves the backend is reachable.
+func isNotFound(err error) bool {
+	return errors.Is(err, common.ErrNotFound)
+}
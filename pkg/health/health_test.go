@@ -0,0 +1,324 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package health
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/audit"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+// switchProbe fails while its error is set.
+type switchProbe struct {
+	mu  sync.Mutex
+	err error
+}
+
+func (p *switchProbe) set(err error) {
+	p.mu.Lock()
+	p.err = err
+	p.mu.Unlock()
+}
+
+func (p *switchProbe) Check(context.Context, common.Storage) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.err
+}
+
+// eventRecorder captures audit events.
+type eventRecorder struct {
+	audit.AuditLogger
+	mu     sync.Mutex
+	events []*audit.AuditEvent
+}
+
+func (r *eventRecorder) LogEvent(_ context.Context, event *audit.AuditEvent) error {
+	r.mu.Lock()
+	r.events = append(r.events, event)
+	r.mu.Unlock()
+	return nil
+}
+
+func TestMonitorThresholds(t *testing.T) {
+	probe := &switchProbe{}
+	m, err := NewMonitor("thresholds", memory.New(), &Config{Custom: probe, FailureThreshold: 2, SuccessThreshold: 2})
+	if err != nil {
+		t.Fatalf("NewMonitor() error = %v", err)
+	}
+	var changes []bool
+	m.OnChange(func(s Status) { changes = append(changes, s.Healthy) })
+
+	ctx := context.Background()
+	probe.set(errors.New("down"))
+	_ = m.Check(ctx)
+	if !m.Healthy() {
+		t.Fatal("one failure marked the backend unhealthy")
+	}
+	if err := m.Check(ctx); err == nil {
+		t.Fatal("Check() returned nil for a failing probe")
+	}
+	if s := m.Status(); s.Healthy || s.ConsecutiveFailures != 2 || s.LastError != "down" {
+		t.Fatalf("Status() = %+v after two failures", s)
+	}
+
+	probe.set(nil)
+	_ = m.Check(ctx)
+	if m.Healthy() {
+		t.Fatal("one success marked the backend healthy")
+	}
+	_ = m.Check(ctx)
+	if s := m.Status(); !s.Healthy || s.LastError != "" || s.ConsecutiveSuccesses != 2 {
+		t.Fatalf("Status() = %+v after two successes", s)
+	}
+	if len(changes) != 2 || changes[0] || !changes[1] {
+		t.Fatalf("OnChange saw %v, want [false true]", changes)
+	}
+
+	stats := statsFor(t, "thresholds")
+	if stats.ChecksFailed != 2 || stats.ChecksSucceeded != 2 || !stats.Healthy {
+		t.Fatalf("Stats = %+v", stats)
+	}
+}
+
+func TestMonitorStartClose(t *testing.T) {
+	probe := &switchProbe{err: errors.New("down")}
+	m, err := NewMonitor("background", memory.New(), &Config{Custom: probe, Interval: time.Millisecond, FailureThreshold: 1})
+	if err != nil {
+		t.Fatalf("NewMonitor() error = %v", err)
+	}
+	unhealthy := make(chan struct{})
+	var once sync.Once
+	m.OnChange(func(s Status) {
+		if !s.Healthy {
+			once.Do(func() { close(unhealthy) })
+		}
+	})
+	m.Start()
+	m.Start()
+	select {
+	case <-unhealthy:
+	case <-time.After(5 * time.Second):
+		t.Fatal("background probe never marked the backend unhealthy")
+	}
+	if err := m.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := m.Close(); err != nil {
+		t.Fatalf("second Close() error = %v", err)
+	}
+
+	idle, err := NewMonitor("idle", memory.New(), nil)
+	if err != nil {
+		t.Fatalf("NewMonitor() error = %v", err)
+	}
+	if err := idle.Close(); err != nil {
+		t.Fatalf("Close() of an unstarted monitor error = %v", err)
+	}
+}
+
+func TestNewMonitorInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *Config
+	}{
+		{"unknown probe", &Config{Probe: "ping"}},
+		{"negative interval", &Config{Interval: -time.Second}},
+		{"negative timeout", &Config{Timeout: -time.Second}},
+		{"negative failure threshold", &Config{FailureThreshold: -1}},
+		{"negative success threshold", &Config{SuccessThreshold: -1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewMonitor("invalid", memory.New(), tt.cfg); !errors.Is(err, ErrInvalidConfig) {
+				t.Fatalf("NewMonitor() error = %v, want ErrInvalidConfig", err)
+			}
+		})
+	}
+	if _, err := NewMonitor("invalid", nil, nil); !errors.Is(err, common.ErrInvalidArgument) {
+		t.Fatalf("NewMonitor(nil) error = %v, want ErrInvalidArgument", err)
+	}
+}
+
+func TestBuiltinProbes(t *testing.T) {
+	ctx := context.Background()
+	storage := memory.New()
+	for _, kind := range []string{"", ProbeExists, ProbeList, ProbeWrite} {
+		probe, err := NewProbe(kind, "")
+		if err != nil {
+			t.Fatalf("NewProbe(%q) error = %v", kind, err)
+		}
+		if err := probe.Check(ctx, storage); err != nil {
+			t.Errorf("%q probe error = %v", kind, err)
+		}
+	}
+	if exists, _ := storage.Exists(ctx, DefaultKey); exists {
+		t.Error("write probe left its object behind")
+	}
+
+	down := &failingStorage{Storage: storage, err: common.ErrBackendUnavailable}
+	for _, kind := range []string{ProbeExists, ProbeList, ProbeWrite} {
+		probe, _ := NewProbe(kind, "")
+		if err := probe.Check(ctx, down); !errors.Is(err, common.ErrUnavailable) {
+			t.Errorf("%q probe of an unavailable backend error = %v", kind, err)
+		}
+	}
+}
+
+// failingStorage fails every operation with err.
+type failingStorage struct {
+	common.Storage
+	err error
+}
+
+func (s *failingStorage) PutWithContext(context.Context, string, io.Reader) error { return s.err }
+func (s *failingStorage) PutWithMetadata(context.Context, string, io.Reader, *common.Metadata) error {
+	return s.err
+}
+func (s *failingStorage) GetWithContext(context.Context, string) (io.ReadCloser, error) {
+	return nil, s.err
+}
+func (s *failingStorage) DeleteWithContext(context.Context, string) error { return s.err }
+func (s *failingStorage) Exists(context.Context, string) (bool, error)    { return false, s.err }
+func (s *failingStorage) ListWithOptions(context.Context, *common.ListOptions) (*common.ListResult, error) {
+	return nil, s.err
+}
+
+func TestFailoverStorage(t *testing.T) {
+	ctx := context.Background()
+	primary, secondary := memory.New(), memory.New()
+	if err := secondary.Put("only-secondary.txt", strings.NewReader("s")); err != nil {
+		t.Fatal(err)
+	}
+
+	probe := &switchProbe{}
+	m, err := NewMonitor("failover-primary", primary, &Config{Custom: probe, FailureThreshold: 1, SuccessThreshold: 1})
+	if err != nil {
+		t.Fatalf("NewMonitor() error = %v", err)
+	}
+	recorder := &eventRecorder{AuditLogger: audit.NewNoOpAuditLogger()}
+	var transitions []Transition
+	s := NewFailoverStorage(primary, secondary, m, &FailoverConfig{
+		Secondary:    "failover-secondary",
+		AuditLog:     recorder,
+		OnTransition: func(tr Transition) { transitions = append(transitions, tr) },
+	})
+	if s.Underlying() != primary || s.Monitor() != m {
+		t.Fatal("Underlying() or Monitor() returned the wrong value")
+	}
+
+	if err := s.PutWithContext(ctx, "a.txt", strings.NewReader("primary")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if ok, _ := primary.Exists(ctx, "a.txt"); !ok {
+		t.Fatal("healthy write did not reach the primary")
+	}
+
+	probe.set(errors.New("down"))
+	_ = m.Check(ctx)
+	if !s.FailedOver() {
+		t.Fatal("unhealthy primary did not fail over")
+	}
+	if ok, _ := s.Exists(ctx, "only-secondary.txt"); !ok {
+		t.Fatal("read was not routed to the secondary")
+	}
+	if err := s.Append(ctx, "b.txt", strings.NewReader("secondary")); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	rc, err := s.GetRange(ctx, "b.txt", 2, 3)
+	if err != nil {
+		t.Fatalf("GetRange() error = %v", err)
+	}
+	got, _ := io.ReadAll(rc)
+	_ = rc.Close()
+	if string(got) != "con" {
+		t.Fatalf("GetRange() = %q, want %q", got, "con")
+	}
+	if ok, _ := primary.Exists(ctx, "b.txt"); ok {
+		t.Fatal("write while failed over reached the primary")
+	}
+
+	probe.set(nil)
+	_ = m.Check(ctx)
+	if s.FailedOver() {
+		t.Fatal("healthy primary did not fail back")
+	}
+	if ok, _ := s.Exists(ctx, "a.txt"); !ok {
+		t.Fatal("read after failback was not routed to the primary")
+	}
+
+	if len(transitions) != 2 || !transitions[0].FailedOver || transitions[1].FailedOver ||
+		transitions[0].Backend != "failover-primary" || transitions[0].Secondary != "failover-secondary" {
+		t.Fatalf("transitions = %+v", transitions)
+	}
+	if len(recorder.events) != 2 ||
+		recorder.events[0].EventType != audit.EventBackendFailover ||
+		recorder.events[0].ErrorMessage != "down" ||
+		recorder.events[1].EventType != audit.EventBackendFailback {
+		t.Fatalf("audit events = %+v", recorder.events)
+	}
+	stats := statsFor(t, "failover-primary")
+	if stats.Failovers != 1 || stats.Failbacks != 1 {
+		t.Fatalf("Stats = %+v", stats)
+	}
+}
+
+func TestFailoverStorageReadOnlySecondary(t *testing.T) {
+	ctx := context.Background()
+	primary, secondary := memory.New(), memory.New()
+	if err := secondary.Put("a.txt", strings.NewReader("replica")); err != nil {
+		t.Fatal(err)
+	}
+	m, err := NewMonitor("read-only", primary, &Config{Custom: &switchProbe{err: errors.New("down")}, FailureThreshold: 1})
+	if err != nil {
+		t.Fatalf("NewMonitor() error = %v", err)
+	}
+	_ = m.Check(ctx)
+	s := NewFailoverStorage(primary, secondary, m, &FailoverConfig{ReadOnly: true})
+	if !s.FailedOver() {
+		t.Fatal("failover storage over an unhealthy primary did not start failed over")
+	}
+
+	rc, err := s.GetWithContext(ctx, "a.txt")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	_ = rc.Close()
+	if err := s.Put("b.txt", strings.NewReader("b")); !errors.Is(err, ErrSecondaryReadOnly) || !errors.Is(err, common.ErrUnavailable) {
+		t.Fatalf("Put() error = %v, want ErrSecondaryReadOnly", err)
+	}
+	if err := s.Delete("a.txt"); !errors.Is(err, ErrSecondaryReadOnly) {
+		t.Fatalf("Delete() error = %v, want ErrSecondaryReadOnly", err)
+	}
+}
+
+// statsFor returns the Default registry's stats for backend.
+func statsFor(t *testing.T, backend string) Stats {
+	t.Helper()
+	for _, s := range Default.Stats() {
+		if s.Backend == backend {
+			return s
+		}
+	}
+	t.Fatalf("no stats for %s", backend)
+	return Stats{}
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package health
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// Built-in probe names.
+const (
+	// ProbeExists checks whether the probe key exists. It is the cheapest
+	// probe and needs only read access; the key does not have to exist.
+	ProbeExists = "exists"
+
+	// ProbeList lists at most one object under the probe key as a prefix.
+	ProbeList = "list"
+
+	// ProbeWrite writes the probe key, reads it back and deletes it,
+	// proving the backend accepts writes as well as reads.
+	ProbeWrite = "write"
+)
+
+// Probe checks whether a backend is able to serve requests.
+type Probe interface {
+	// Check returns nil if storage is healthy.
+	Check(ctx context.Context, storage common.Storage) error
+}
+
+// ProbeFunc adapts a function to the Probe interface.
+type ProbeFunc func(ctx context.Context, storage common.Storage) error
+
+// Check calls f.
+func (f ProbeFunc) Check(ctx context.Context, storage common.Storage) error {
+	return f(ctx, storage)
+}
+
+// NewProbe returns the built-in probe named kind, checking key. An empty
+// kind selects ProbeExists.
+func NewProbe(kind, key string) (Probe, error) {
+	if key == "" {
+		key = DefaultKey
+	}
+	switch strings.ToLower(kind) {
+	case "", ProbeExists:
+		return existsProbe(key), nil
+	case ProbeList:
+		return listProbe(key), nil
+	case ProbeWrite:
+		return writeProbe(key), nil
+	default:
+		return nil, fmt.Errorf("%w: unknown probe %q", ErrInvalidConfig, kind)
+	}
+}
+
+// existsProbe checks whether key exists.
+func existsProbe(key string) Probe {
+	return ProbeFunc(func(ctx context.Context, storage common.Storage) error {
+		if _, err := storage.Exists(ctx, key); err != nil && !isNotFound(err) {
+			return err
+		}
+		return nil
+	})
+}
+
+// listProbe lists at most one object under prefix.
+func listProbe(prefix string) Probe {
+	return ProbeFunc(func(ctx context.Context, storage common.Storage) error {
+		_, err := storage.ListWithOptions(ctx, &common.ListOptions{Prefix: prefix, MaxResults: 1})
+		return err
+	})
+}
+
+// writeProbe writes key, reads it back and deletes it.
+func writeProbe(key string) Probe {
+	return ProbeFunc(func(ctx context.Context, storage common.Storage) error {
+		payload := []byte(strconv.FormatInt(time.Now().UnixNano(), 10))
+		if err := storage.PutWithContext(ctx, key, bytes.NewReader(payload)); err != nil {
+			return err
+		}
+		rc, err := storage.GetWithContext(ctx, key)
+		if err != nil {
+			return err
+		}
+		got, err := io.ReadAll(io.LimitReader(rc, int64(len(payload))+1))
+		_ = rc.Close() // #nosec G104 -- The probe object was read in full
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(got, payload) {
+			return fmt.Errorf("%w: %s read back %d bytes that differ from those written", ErrProbeFailed, key, len(got))
+		}
+		if err := storage.DeleteWithContext(ctx, key); err != nil && !isNotFound(err) {
+			return err
+		}
+		return nil
+	})
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package health
+
+import (
+	"sort"
+	"sync"
+)
+
+// Stats reports the health checks and failovers of one backend.
+type Stats struct {
+	// Backend is the name of the monitored backend.
+	Backend string
+
+	// Healthy reports whether the backend is currently considered healthy.
+	Healthy bool
+
+	// ChecksSucceeded counts successful probes.
+	ChecksSucceeded uint64
+
+	// ChecksFailed counts failed probes.
+	ChecksFailed uint64
+
+	// Failovers counts the times traffic moved to the secondary backend.
+	Failovers uint64
+
+	// Failbacks counts the times traffic returned to the backend.
+	Failbacks uint64
+}
+
+// Registry accumulates Stats per backend. It is safe for concurrent use.
+type Registry struct {
+	mu       sync.Mutex
+	backends map[string]*Stats
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{backends: make(map[string]*Stats)}
+}
+
+// Default is the process-wide registry monitors and failover storage
+// record into.
+var Default = NewRegistry()
+
+// register adds backend to the registry as healthy, keeping its counters
+// if it is already present.
+func (r *Registry) register(backend string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.backends[backend]; !ok {
+		r.backends[backend] = &Stats{Backend: backend, Healthy: true}
+	}
+}
+
+// update applies fn to the stats of backend, creating them if needed.
+func (r *Registry) update(backend string, fn func(*Stats)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.backends[backend]
+	if !ok {
+		s = &Stats{Backend: backend, Healthy: true}
+		r.backends[backend] = s
+	}
+	fn(s)
+}
+
+// recordCheck records the result of a probe and the resulting health.
+func (r *Registry) recordCheck(backend string, succeeded, healthy bool) {
+	r.update(backend, func(s *Stats) {
+		if succeeded {
+			s.ChecksSucceeded++
+		} else {
+			s.ChecksFailed++
+		}
+		s.Healthy = healthy
+	})
+}
+
+// recordTransition records a failover to the secondary, or a failback.
+func (r *Registry) recordTransition(backend string, failedOver bool) {
+	r.update(backend, func(s *Stats) {
+		if failedOver {
+			s.Failovers++
+		} else {
+			s.Failbacks++
+		}
+	})
+}
+
+// Stats returns the statistics of every backend, sorted by backend.
+func (r *Registry) Stats() []Stats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	out := make([]Stats, 0, len(r.backends))
+	for _, s := range r.backends {
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Backend < out[j].Backend })
+	return out
+}
@@ -0,0 +1,265 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package ipfs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// client calls the RPC API of an IPFS node, such as Kubo's on port 5001.
+// Every command is a POST to /api/v0/<command>.
+type client struct {
+	http          *http.Client
+	endpoint      string
+	authorization string
+	cidVersion    int
+}
+
+// apiError is an error reported by the node.
+type apiError struct {
+	Command string
+	Status  int
+	Message string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("ipfs %s: %s", e.Command, e.Message)
+}
+
+// sentinel returns the common error the node's error corresponds to.
+func (e *apiError) sentinel() error {
+	msg := strings.ToLower(e.Message)
+	switch {
+	case strings.Contains(msg, "not found"), strings.Contains(msg, "could not find"):
+		return common.ErrNotFound
+	case e.Status == http.StatusBadRequest, strings.Contains(msg, "invalid"):
+		return common.ErrInvalidArgument
+	}
+	return common.ErrorForStatus(e.Status)
+}
+
+// translateError maps an error calling the node for key to the common
+// error taxonomy.
+func translateError(err error, key string) error {
+	var aerr *apiError
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &aerr):
+		return common.ProviderError(aerr.sentinel(), key, err)
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return err
+	}
+	var uerr *url.Error
+	if errors.As(err, &uerr) {
+		return common.ProviderError(common.ErrBackendUnavailable, key, err)
+	}
+	return err
+}
+
+// call runs command with args and returns the response of a successful
+// call. The caller closes the body.
+func (c *client) call(ctx context.Context, command string, args url.Values, body io.Reader, contentType string) (*http.Response, error) {
+	u := c.endpoint + "/api/v0/" + command
+	if len(args) > 0 {
+		u += "?" + args.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, body)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.authorization != "" {
+		req.Header.Set("Authorization", c.authorization)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusOK {
+		return resp, nil
+	}
+	defer func() { _ = resp.Body.Close() }()
+	aerr := &apiError{Command: command, Status: resp.StatusCode}
+	var payload struct{ Message string }
+	if data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10)); json.Unmarshal(data, &payload) == nil && payload.Message != "" {
+		aerr.Message = payload.Message
+	} else if text := strings.TrimSpace(string(data)); text != "" {
+		aerr.Message = text
+	} else {
+		aerr.Message = resp.Status
+	}
+	return nil, aerr
+}
+
+// callJSON runs command and decodes its JSON response into out.
+func (c *client) callJSON(ctx context.Context, command string, args url.Values, out any) error {
+	resp, err := c.call(ctx, command, args, nil, "")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if out == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("ipfs %s: invalid response: %w", command, err)
+	}
+	return nil
+}
+
+// add adds and pins data as a single file and returns its CID and size.
+func (c *client) add(ctx context.Context, data io.Reader) (string, int64, error) {
+	pr, pw := io.Pipe()
+	form := multipart.NewWriter(pw)
+	counter := &countingReader{r: data}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		part, err := form.CreateFormFile("file", "data")
+		if err == nil {
+			_, err = io.Copy(part, counter)
+		}
+		if err == nil {
+			err = form.Close()
+		}
+		_ = pw.CloseWithError(err)
+	}()
+
+	args := url.Values{
+		"pin":         {"true"},
+		"cid-version": {strconv.Itoa(c.cidVersion)},
+		"quieter":     {"true"},
+	}
+	resp, err := c.call(ctx, "add", args, pr, form.FormDataContentType())
+	_ = pr.CloseWithError(errors.New("request finished"))
+	<-done
+	if err != nil {
+		return "", 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	// The node streams one JSON object per added entry; the last one is the
+	// file itself.
+	var cid string
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var added struct{ Hash string }
+		if err := dec.Decode(&added); err == io.EOF {
+			break
+		} else if err != nil {
+			return "", 0, fmt.Errorf("ipfs add: invalid response: %w", err)
+		}
+		if added.Hash != "" {
+			cid = added.Hash
+		}
+	}
+	if cid == "" {
+		return "", 0, errors.New("ipfs add: no CID in response")
+	}
+	return cid, counter.n, nil
+}
+
+// cat streams length bytes of cid from offset; a negative length reads to
+// the end.
+func (c *client) cat(ctx context.Context, cid string, offset, length int64) (io.ReadCloser, error) {
+	args := url.Values{"arg": {cid}}
+	if offset > 0 {
+		args.Set("offset", strconv.FormatInt(offset, 10))
+	}
+	if length >= 0 {
+		args.Set("length", strconv.FormatInt(length, 10))
+	}
+	resp, err := c.call(ctx, "cat", args, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	return &streamReader{resp: resp}, nil
+}
+
+// pin pins cid recursively, fetching it from the network if the node does
+// not have it.
+func (c *client) pin(ctx context.Context, cid string) error {
+	return c.callJSON(ctx, "pin/add", url.Values{"arg": {cid}}, nil)
+}
+
+// unpin removes the recursive pin of cid. Content that is not pinned is
+// not an error.
+func (c *client) unpin(ctx context.Context, cid string) error {
+	err := c.callJSON(ctx, "pin/rm", url.Values{"arg": {cid}}, nil)
+	var aerr *apiError
+	if errors.As(err, &aerr) && strings.Contains(aerr.Message, "not pinned") {
+		return nil
+	}
+	return err
+}
+
+// stat returns the size of the file cid and whether it is a file.
+func (c *client) stat(ctx context.Context, cid string) (int64, bool, error) {
+	var st struct {
+		Size int64
+		Type string
+	}
+	if err := c.callJSON(ctx, "files/stat", url.Values{"arg": {"/ipfs/" + cid}}, &st); err != nil {
+		return 0, false, err
+	}
+	return st.Size, st.Type == "file", nil
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// streamReader is the body of a streamed response. The node reports
+// errors that happen after the response started in the X-Stream-Error
+// trailer, which would otherwise look like a clean end of the content.
+type streamReader struct {
+	resp *http.Response
+}
+
+func (r *streamReader) Read(p []byte) (int, error) {
+	n, err := r.resp.Body.Read(p)
+	if err == io.EOF {
+		if msg := r.resp.Trailer.Get("X-Stream-Error"); msg != "" {
+			return n, fmt.Errorf("%w: ipfs cat: %s", common.ErrBackendUnavailable, msg)
+		}
+	}
+	return n, err
+}
+
+func (r *streamReader) Close() error {
+	return r.resp.Body.Close()
+}
@@ -0,0 +1,613 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package ipfs provides a storage backend on an IPFS node, reached through
+// the node's RPC API (Kubo's, by default on http://127.0.0.1:5001).
+//
+// IPFS addresses content by its CID, not by name, so the backend keeps an
+// index mapping each key to the CID of its content and the object's
+// metadata, persisted as a JSON file in the directory of the "path"
+// setting. Put adds and pins the content and records its CID, which is
+// also the object's ETag and its "ipfs-cid" custom metadata field. Get
+// resolves the key to its CID and reads it from the node, and List is
+// served from the index without calling the node. Link maps a key to
+// content that is already on the network by its CID, for distributing
+// content-addressed data under readable names.
+//
+// Content is unpinned once no key refers to it any more; the node's
+// garbage collector reclaims it. Only one process may use an index at a
+// time.
+package ipfs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+	"github.com/jeremyhahn/go-objstore/pkg/transport"
+)
+
+const (
+	// DefaultEndpoint is the RPC API address used when none is configured.
+	DefaultEndpoint = "http://127.0.0.1:5001"
+
+	// MetaCID is the custom metadata field holding an object's CID.
+	MetaCID = "ipfs-cid"
+
+	// IndexFile is the name of the index file in the "path" directory.
+	IndexFile = "ipfs-index.json"
+
+	indexVersion        = 1
+	defaultListPageSize = 1000
+)
+
+// ErrNotFile is returned by Link for a CID that is not a file, such as a
+// directory.
+var ErrNotFile = fmt.Errorf("%w: CID is not a file", common.ErrInvalidArgument)
+
+// entry is the index record of one key.
+type entry struct {
+	CID      string           `json:"cid"`
+	Metadata *common.Metadata `json:"metadata"`
+}
+
+// indexState is the persisted form of the index.
+type indexState struct {
+	Version int               `json:"version"`
+	Objects map[string]*entry `json:"objects"`
+}
+
+// Storage stores objects on an IPFS node. It implements common.Storage
+// and is safe for concurrent use.
+type Storage struct {
+	common.LifecycleManager
+
+	client    *client
+	timeouts  *transport.Timeouts
+	indexPath string
+
+	// mu guards the index and serializes pinning decisions with it, so
+	// content is never unpinned while a key is being pointed at it.
+	mu      sync.RWMutex
+	objects map[string]*entry
+	refs    map[string]int
+}
+
+// New returns an unconfigured IPFS backend.
+func New() *Storage {
+	return &Storage{
+		LifecycleManager: memory.NewLifecycleManager(),
+		objects:          make(map[string]*entry),
+		refs:             make(map[string]int),
+	}
+}
+
+// Configure connects the backend to a node and loads its index.
+// Settings:
+//   - path: directory holding the key index (required)
+//   - endpoint: RPC API address (default: DefaultEndpoint)
+//   - authorization: Authorization header sent with every call, for nodes
+//     behind an authenticating proxy (optional)
+//   - cidVersion: CID version of added content, 0 or 1 (default: 1)
+//
+// The HTTP transport and timeout settings of the cloud backends apply too.
+func (s *Storage) Configure(settings map[string]string) error {
+	dir := settings["path"]
+	if dir == "" {
+		return common.ErrPathNotSet
+	}
+	endpoint := strings.TrimSuffix(settings["endpoint"], "/")
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+	cidVersion := 1
+	if v := settings["cidVersion"]; v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || (n != 0 && n != 1) {
+			return fmt.Errorf("%w: cidVersion must be 0 or 1, got %q", common.ErrInvalidArgument, v)
+		}
+		cidVersion = n
+	}
+	httpCfg, err := transport.ParseSettings(settings)
+	if err != nil {
+		return err
+	}
+	timeouts, err := transport.ParseTimeouts(settings)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return err
+	}
+	indexPath := filepath.Join(dir, IndexFile)
+	objects, err := loadIndex(indexPath)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.client = &client{
+		http:          transport.Default.Client("ipfs", endpoint+"|"+settings["authorization"], httpCfg),
+		endpoint:      endpoint,
+		authorization: settings["authorization"],
+		cidVersion:    cidVersion,
+	}
+	s.timeouts = timeouts
+	s.indexPath = indexPath
+	s.objects = objects
+	s.refs = make(map[string]int, len(objects))
+	for _, e := range objects {
+		s.refs[e.CID]++
+	}
+	return nil
+}
+
+// Put adds data under key.
+func (s *Storage) Put(key string, data io.Reader) error {
+	return s.PutWithMetadata(context.Background(), key, data, nil)
+}
+
+// PutWithContext adds data under key with context support.
+func (s *Storage) PutWithContext(ctx context.Context, key string, data io.Reader) error {
+	return s.PutWithMetadata(ctx, key, data, nil)
+}
+
+// PutWithMetadata adds data under key with metadata.
+func (s *Storage) PutWithMetadata(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) error {
+	_, err := s.add(ctx, key, data, metadata)
+	return err
+}
+
+// PutWithInfo adds and pins data like PutWithMetadata and returns what was
+// stored; its ETag is the content's CID.
+func (s *Storage) PutWithInfo(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) (*common.ObjectInfo, error) {
+	meta, err := s.add(ctx, key, data, metadata)
+	if err != nil {
+		return nil, err
+	}
+	return &common.ObjectInfo{Key: key, Metadata: meta}, nil
+}
+
+// Add adds and pins data, points key at it and returns its CID.
+func (s *Storage) Add(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) (string, error) {
+	meta, err := s.add(ctx, key, data, metadata)
+	if err != nil {
+		return "", err
+	}
+	return meta.ETag, nil
+}
+
+// add adds and pins data, points key at it and returns the metadata
+// recorded for it.
+func (s *Storage) add(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) (*common.Metadata, error) {
+	if err := common.ValidateKey(key); err != nil {
+		return nil, err
+	}
+	if s.client == nil {
+		return nil, common.ErrNotConfigured
+	}
+	ctx, cancel := s.timeouts.Context(ctx, transport.OpPut)
+	defer cancel()
+	cid, size, err := s.client.add(ctx, data)
+	if err != nil {
+		return nil, translateError(err, key)
+	}
+	meta, err := s.bind(ctx, key, cid, size, metadata)
+	if err != nil {
+		return nil, err
+	}
+	return copyMetadata(meta), nil
+}
+
+// Link points key at content already on the network, identified by its
+// CID, and pins it. The node fetches content it does not have yet, which
+// can take as long as the content takes to find and download. The CID
+// must name a file.
+func (s *Storage) Link(ctx context.Context, key, cid string, metadata *common.Metadata) error {
+	if err := common.ValidateKey(key); err != nil {
+		return err
+	}
+	if s.client == nil {
+		return common.ErrNotConfigured
+	}
+	ctx, cancel := s.timeouts.Context(ctx, transport.OpPut)
+	defer cancel()
+	if err := s.client.pin(ctx, cid); err != nil {
+		return translateError(err, key)
+	}
+	size, file, err := s.client.stat(ctx, cid)
+	if err == nil && !file {
+		err = fmt.Errorf("%w: %s", ErrNotFile, cid)
+	}
+	if err != nil {
+		s.mu.Lock()
+		if s.refs[cid] == 0 {
+			_ = s.client.unpin(ctx, cid)
+		}
+		s.mu.Unlock()
+		return translateError(err, key)
+	}
+	_, err = s.bind(ctx, key, cid, size, metadata)
+	return err
+}
+
+// CID returns the CID of the content under key.
+func (s *Storage) CID(ctx context.Context, key string) (string, error) {
+	e, err := s.lookup(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	return e.CID, nil
+}
+
+// bind records key as pointing at the pinned content cid of size bytes and
+// unpins the content key pointed at before if nothing else refers to it.
+func (s *Storage) bind(ctx context.Context, key, cid string, size int64, metadata *common.Metadata) (*common.Metadata, error) {
+	meta := &common.Metadata{}
+	if metadata != nil {
+		*meta = *metadata
+	}
+	meta.Size = size
+	meta.ETag = cid
+	meta.LastModified = time.Now().UTC()
+	meta.Custom = maps.Clone(meta.Custom)
+	if meta.Custom == nil {
+		meta.Custom = make(map[string]string, 1)
+	}
+	meta.Custom[MetaCID] = cid
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.refs[cid] == 0 {
+		// Another key may have released cid, and unpinned it, since it was
+		// added.
+		if err := s.client.pin(ctx, cid); err != nil {
+			return nil, translateError(err, key)
+		}
+	}
+	old := s.objects[key]
+	s.objects[key] = &entry{CID: cid, Metadata: meta}
+	s.refs[cid]++
+	if err := s.saveLocked(); err != nil {
+		if old != nil {
+			s.objects[key] = old
+		} else {
+			delete(s.objects, key)
+		}
+		s.releaseLocked(ctx, cid)
+		return nil, err
+	}
+	if old != nil {
+		s.releaseLocked(ctx, old.CID)
+	}
+	return meta, nil
+}
+
+// releaseLocked drops a reference to cid and unpins it when it was the
+// last one. Unpinning is best effort: content that stays pinned is only
+// wasted space.
+func (s *Storage) releaseLocked(ctx context.Context, cid string) {
+	if s.refs[cid] > 0 {
+		s.refs[cid]--
+	}
+	if s.refs[cid] == 0 {
+		delete(s.refs, cid)
+		_ = s.client.unpin(context.WithoutCancel(ctx), cid)
+	}
+}
+
+// lookup returns the index entry of key.
+func (s *Storage) lookup(ctx context.Context, key string) (*entry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	e, ok := s.objects[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", common.ErrKeyNotFound, key)
+	}
+	return e, nil
+}
+
+// Get retrieves the content under key.
+func (s *Storage) Get(key string) (io.ReadCloser, error) {
+	return s.GetWithContext(context.Background(), key)
+}
+
+// GetWithContext retrieves the content under key from the node.
+func (s *Storage) GetWithContext(ctx context.Context, key string) (io.ReadCloser, error) {
+	return s.GetRange(ctx, key, 0, -1)
+}
+
+// GetRange retrieves length bytes of the content under key from offset. A
+// negative length reads to the end.
+func (s *Storage) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	e, err := s.lookup(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if offset < 0 {
+		return nil, fmt.Errorf("%w: negative offset", common.ErrInvalidArgument)
+	}
+	ctx, cancel := s.timeouts.Context(ctx, transport.OpGet)
+	rc, err := s.client.cat(ctx, e.CID, offset, length)
+	if err != nil {
+		cancel()
+		return nil, translateError(err, key)
+	}
+	return transport.CancelOnClose(rc, cancel), nil
+}
+
+// GetMetadata returns the metadata recorded for key.
+func (s *Storage) GetMetadata(ctx context.Context, key string) (*common.Metadata, error) {
+	e, err := s.lookup(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return copyMetadata(e.Metadata), nil
+}
+
+// UpdateMetadata replaces the metadata of key, keeping its size, CID and
+// modification time.
+func (s *Storage) UpdateMetadata(ctx context.Context, key string, metadata *common.Metadata) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if metadata == nil {
+		return fmt.Errorf("%w: metadata is nil", common.ErrInvalidArgument)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, ok := s.objects[key]
+	if !ok {
+		return fmt.Errorf("%w: %s", common.ErrKeyNotFound, key)
+	}
+	meta := copyMetadata(metadata)
+	meta.Size = old.Metadata.Size
+	meta.ETag = old.CID
+	meta.LastModified = old.Metadata.LastModified
+	if meta.Custom == nil {
+		meta.Custom = make(map[string]string, 1)
+	}
+	meta.Custom[MetaCID] = old.CID
+	s.objects[key] = &entry{CID: old.CID, Metadata: meta}
+	if err := s.saveLocked(); err != nil {
+		s.objects[key] = old
+		return err
+	}
+	return nil
+}
+
+// Delete removes key.
+func (s *Storage) Delete(key string) error {
+	return s.DeleteWithContext(context.Background(), key)
+}
+
+// DeleteWithContext removes key from the index and unpins its content if
+// no other key refers to it.
+func (s *Storage) DeleteWithContext(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, ok := s.objects[key]
+	if !ok {
+		return fmt.Errorf("%w: %s", common.ErrKeyNotFound, key)
+	}
+	delete(s.objects, key)
+	if err := s.saveLocked(); err != nil {
+		s.objects[key] = old
+		return err
+	}
+	if s.client != nil {
+		ctx, cancel := s.timeouts.Context(ctx, transport.OpDelete)
+		defer cancel()
+		s.releaseLocked(ctx, old.CID)
+	}
+	return nil
+}
+
+// Exists reports whether key is in the index.
+func (s *Storage) Exists(ctx context.Context, key string) (bool, error) {
+	_, err := s.lookup(ctx, key)
+	if errors.Is(err, common.ErrKeyNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// List returns the keys under prefix.
+func (s *Storage) List(prefix string) ([]string, error) {
+	return s.ListWithContext(context.Background(), prefix)
+}
+
+// ListWithContext returns the keys under prefix from the index.
+func (s *Storage) ListWithContext(ctx context.Context, prefix string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	keys := make([]string, 0)
+	for key := range s.objects {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// ListWithOptions returns a page of objects from the index.
+func (s *Storage) ListWithOptions(ctx context.Context, opts *common.ListOptions) (*common.ListResult, error) {
+	if opts == nil {
+		opts = &common.ListOptions{}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	type item struct {
+		name  string
+		entry *entry
+	}
+	seen := make(map[string]bool)
+	var items []item
+	for key, e := range s.objects {
+		if !strings.HasPrefix(key, opts.Prefix) {
+			continue
+		}
+		if opts.Delimiter != "" {
+			rest := key[len(opts.Prefix):]
+			if i := strings.Index(rest, opts.Delimiter); i >= 0 {
+				p := opts.Prefix + rest[:i+len(opts.Delimiter)]
+				if !seen[p] {
+					seen[p] = true
+					items = append(items, item{name: p})
+				}
+				continue
+			}
+		}
+		items = append(items, item{name: key, entry: e})
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].name < items[j].name })
+
+	start := 0
+	if opts.ContinueFrom != "" {
+		start = sort.Search(len(items), func(i int) bool { return items[i].name > opts.ContinueFrom })
+	}
+	limit := opts.MaxResults
+	if limit <= 0 {
+		limit = defaultListPageSize
+	}
+	end := min(start+limit, len(items))
+
+	result := &common.ListResult{
+		Objects:        []*common.ObjectInfo{},
+		CommonPrefixes: []string{},
+	}
+	for _, it := range items[start:end] {
+		if it.entry == nil {
+			result.CommonPrefixes = append(result.CommonPrefixes, it.name)
+			continue
+		}
+		result.Objects = append(result.Objects, &common.ObjectInfo{Key: it.name, Metadata: copyMetadata(it.entry.Metadata)})
+	}
+	if end < len(items) {
+		result.Truncated = true
+		result.NextToken = items[end-1].name
+	}
+	return result, nil
+}
+
+// Archive copies the content under key to destination.
+func (s *Storage) Archive(key string, destination common.Archiver) error {
+	if destination == nil {
+		return common.ErrArchiveDestinationNil
+	}
+	rc, err := s.Get(key)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rc.Close() }()
+	return destination.Put(key, rc)
+}
+
+// copyMetadata returns a deep copy of m.
+func copyMetadata(m *common.Metadata) *common.Metadata {
+	out := *m
+	out.Custom = maps.Clone(m.Custom)
+	return &out
+}
+
+// loadIndex reads the index file at path; a missing file is an empty
+// index.
+func loadIndex(path string) (map[string]*entry, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path comes from the backend configuration
+	if errors.Is(err, os.ErrNotExist) {
+		return make(map[string]*entry), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ipfs index: %w", err)
+	}
+	var st indexState
+	if err := json.Unmarshal(data, &st); err != nil {
+		return nil, fmt.Errorf("failed to decode ipfs index %s: %w", path, err)
+	}
+	if st.Version != indexVersion {
+		return nil, fmt.Errorf("unsupported ipfs index version %d", st.Version)
+	}
+	objects := st.Objects
+	if objects == nil {
+		objects = make(map[string]*entry)
+	}
+	for key, e := range objects {
+		if e == nil || e.CID == "" || e.Metadata == nil {
+			return nil, fmt.Errorf("failed to decode ipfs index %s: invalid entry for %s", path, key)
+		}
+	}
+	return objects, nil
+}
+
+// saveLocked writes the index file atomically.
+func (s *Storage) saveLocked() error {
+	if s.indexPath == "" {
+		return common.ErrNotConfigured
+	}
+	data, err := json.MarshalIndent(indexState{Version: indexVersion, Objects: s.objects}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode ipfs index: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.indexPath), ".ipfs-index-*")
+	if err != nil {
+		return fmt.Errorf("failed to write ipfs index: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write ipfs index: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write ipfs index: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.indexPath); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write ipfs index: %w", err)
+	}
+	return nil
+}
+
+// Ensure Storage implements the Storage and RangeReader interfaces at
+// compile time
+var (
+	_ common.Storage     = (*Storage)(nil)
+	_ common.RangeReader = (*Storage)(nil)
+)
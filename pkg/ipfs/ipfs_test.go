@@ -0,0 +1,411 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package ipfs
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/storagetest"
+)
+
+// fakeNode implements the parts of the Kubo RPC API the backend uses.
+type fakeNode struct {
+	mu            sync.Mutex
+	content       map[string][]byte
+	dirs          map[string]bool
+	pins          map[string]bool
+	broken        map[string]bool // cat fails after the first byte
+	authorization string
+}
+
+func newFakeNode(t *testing.T) (*fakeNode, *httptest.Server) {
+	t.Helper()
+	n := &fakeNode{
+		content: make(map[string][]byte),
+		dirs:    make(map[string]bool),
+		pins:    make(map[string]bool),
+		broken:  make(map[string]bool),
+	}
+	srv := httptest.NewServer(n)
+	t.Cleanup(srv.Close)
+	return n, srv
+}
+
+// store adds content as another peer would and returns its CID.
+func (n *fakeNode) store(data []byte) string {
+	sum := sha256.Sum256(data)
+	cid := "bafk" + hex.EncodeToString(sum[:16])
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.content[cid] = data
+	return cid
+}
+
+func (n *fakeNode) pinned(cid string) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.pins[cid]
+}
+
+func (n *fakeNode) fail(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{"Message": msg, "Code": 0, "Type": "error"})
+}
+
+func (n *fakeNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		n.fail(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if n.authorization != "" && r.Header.Get("Authorization") != n.authorization {
+		n.fail(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	arg := r.URL.Query().Get("arg")
+	switch strings.TrimPrefix(r.URL.Path, "/api/v0/") {
+	case "add":
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			n.fail(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		data, _ := io.ReadAll(file)
+		cid := n.store(data)
+		if r.URL.Query().Get("pin") == "true" {
+			n.mu.Lock()
+			n.pins[cid] = true
+			n.mu.Unlock()
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"Name": "data", "Hash": cid, "Size": strconv.Itoa(len(data))})
+	case "cat":
+		n.mu.Lock()
+		data, ok := n.content[arg]
+		broken := n.broken[arg]
+		n.mu.Unlock()
+		if !ok {
+			n.fail(w, http.StatusInternalServerError, "block was not found locally (offline): ipld: could not find "+arg)
+			return
+		}
+		if off, _ := strconv.Atoi(r.URL.Query().Get("offset")); off > 0 {
+			data = data[min(off, len(data)):]
+		}
+		if l := r.URL.Query().Get("length"); l != "" {
+			length, _ := strconv.Atoi(l)
+			data = data[:min(length, len(data))]
+		}
+		if broken {
+			w.Header().Set("Trailer", "X-Stream-Error")
+			_, _ = w.Write(data[:1])
+			w.Header().Set("X-Stream-Error", "context deadline exceeded")
+			return
+		}
+		_, _ = w.Write(data)
+	case "pin/add":
+		n.mu.Lock()
+		_, ok := n.content[arg]
+		if ok || n.dirs[arg] {
+			n.pins[arg] = true
+		}
+		n.mu.Unlock()
+		if !ok && !n.dirs[arg] {
+			n.fail(w, http.StatusInternalServerError, "failed to fetch: ipld: could not find "+arg)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string][]string{"Pins": {arg}})
+	case "pin/rm":
+		n.mu.Lock()
+		ok := n.pins[arg]
+		delete(n.pins, arg)
+		n.mu.Unlock()
+		if !ok {
+			n.fail(w, http.StatusInternalServerError, "not pinned or pinned indirectly")
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string][]string{"Pins": {arg}})
+	case "files/stat":
+		cid := strings.TrimPrefix(arg, "/ipfs/")
+		n.mu.Lock()
+		data, ok := n.content[cid]
+		dir := n.dirs[cid]
+		n.mu.Unlock()
+		switch {
+		case dir:
+			_ = json.NewEncoder(w).Encode(map[string]any{"Hash": cid, "Size": 0, "Type": "directory"})
+		case ok:
+			_ = json.NewEncoder(w).Encode(map[string]any{"Hash": cid, "Size": len(data), "Type": "file"})
+		default:
+			n.fail(w, http.StatusInternalServerError, "ipld: could not find "+cid)
+		}
+	default:
+		n.fail(w, http.StatusNotFound, "404 page not found")
+	}
+}
+
+func newTestStorage(t *testing.T, endpoint, dir string) *Storage {
+	t.Helper()
+	s := New()
+	if err := s.Configure(map[string]string{"path": dir, "endpoint": endpoint}); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+	return s
+}
+
+// content returns what rc reads, or the error that ended it.
+func content(rc io.ReadCloser, err error) string {
+	if err != nil {
+		return "error: " + err.Error()
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return "error: " + err.Error()
+	}
+	return string(data)
+}
+
+func TestConformance(t *testing.T) {
+	_, srv := newFakeNode(t)
+	storagetest.TestStorage(t, func(t *testing.T) common.Storage {
+		return newTestStorage(t, srv.URL, t.TempDir())
+	})
+}
+
+func TestConfigure(t *testing.T) {
+	if err := New().Configure(map[string]string{}); !errors.Is(err, common.ErrPathNotSet) {
+		t.Errorf("Configure() without path: error = %v, want ErrPathNotSet", err)
+	}
+	err := New().Configure(map[string]string{"path": t.TempDir(), "cidVersion": "2"})
+	if !errors.Is(err, common.ErrInvalidArgument) {
+		t.Errorf("Configure() with cidVersion 2: error = %v, want ErrInvalidArgument", err)
+	}
+	if err := New().Put("key", strings.NewReader("x")); !errors.Is(err, common.ErrNotConfigured) {
+		t.Errorf("Put() before Configure: error = %v, want ErrNotConfigured", err)
+	}
+}
+
+func TestCIDInMetadata(t *testing.T) {
+	ctx := context.Background()
+	node, srv := newFakeNode(t)
+	s := newTestStorage(t, srv.URL, t.TempDir())
+
+	cid, err := s.Add(ctx, "docs/readme.txt", strings.NewReader("hello ipfs"), &common.Metadata{ContentType: "text/plain"})
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if !node.pinned(cid) {
+		t.Errorf("content %s not pinned", cid)
+	}
+	meta, err := s.GetMetadata(ctx, "docs/readme.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.ETag != cid || meta.Custom[MetaCID] != cid || meta.Size != 10 || meta.ContentType != "text/plain" {
+		t.Errorf("metadata = %+v, want CID %s, size 10", meta, cid)
+	}
+	if got, err := s.CID(ctx, "docs/readme.txt"); got != cid || err != nil {
+		t.Errorf("CID() = %q, %v; want %q", got, err, cid)
+	}
+
+	if err := s.UpdateMetadata(ctx, "docs/readme.txt", &common.Metadata{ContentType: "text/markdown"}); err != nil {
+		t.Fatal(err)
+	}
+	meta, _ = s.GetMetadata(ctx, "docs/readme.txt")
+	if meta.ContentType != "text/markdown" || meta.Custom[MetaCID] != cid || meta.Size != 10 {
+		t.Errorf("after update: %+v", meta)
+	}
+}
+
+func TestPutWithInfo(t *testing.T) {
+	ctx := context.Background()
+	_, srv := newFakeNode(t)
+	s := newTestStorage(t, srv.URL, t.TempDir())
+
+	info, err := s.PutWithInfo(ctx, "docs/readme.txt", strings.NewReader("hello ipfs"), &common.Metadata{ContentType: "text/plain"})
+	if err != nil {
+		t.Fatalf("PutWithInfo() error = %v", err)
+	}
+	cid, err := s.CID(ctx, "docs/readme.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Key != "docs/readme.txt" || info.Metadata.ETag != cid || info.Metadata.Size != 10 || info.Metadata.ContentType != "text/plain" {
+		t.Errorf("PutWithInfo() = %+v, want CID %s, size 10", info.Metadata, cid)
+	}
+}
+
+func TestPinsFollowReferences(t *testing.T) {
+	node, srv := newFakeNode(t)
+	s := newTestStorage(t, srv.URL, t.TempDir())
+
+	for _, key := range []string{"a", "b"} {
+		if err := s.Put(key, strings.NewReader("shared")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cid, _ := s.CID(context.Background(), "a")
+	if err := s.Delete("a"); err != nil {
+		t.Fatal(err)
+	}
+	if !node.pinned(cid) {
+		t.Error("content unpinned while another key refers to it")
+	}
+	if err := s.Put("b", strings.NewReader("changed")); err != nil {
+		t.Fatal(err)
+	}
+	if node.pinned(cid) {
+		t.Error("content still pinned after its last key was overwritten")
+	}
+
+	// Content that was released is pinned again when a key refers to it.
+	if err := s.Put("c", strings.NewReader("shared")); err != nil {
+		t.Fatal(err)
+	}
+	if !node.pinned(cid) {
+		t.Error("re-added content not pinned")
+	}
+	if err := s.Delete("missing"); !errors.Is(err, common.ErrKeyNotFound) {
+		t.Errorf("Delete(missing) error = %v, want ErrKeyNotFound", err)
+	}
+}
+
+func TestIndexPersists(t *testing.T) {
+	_, srv := newFakeNode(t)
+	dir := t.TempDir()
+	s := newTestStorage(t, srv.URL, dir)
+	if err := s.Put("dir/key", strings.NewReader("persisted")); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened := newTestStorage(t, srv.URL, dir)
+	keys, err := reopened.List("dir/")
+	if err != nil || len(keys) != 1 || keys[0] != "dir/key" {
+		t.Fatalf("List() = %v, %v", keys, err)
+	}
+	if got := content(reopened.GetWithContext(context.Background(), "dir/key")); got != "persisted" {
+		t.Errorf("Get() = %q", got)
+	}
+}
+
+func TestLink(t *testing.T) {
+	ctx := context.Background()
+	node, srv := newFakeNode(t)
+	s := newTestStorage(t, srv.URL, t.TempDir())
+
+	cid := node.store([]byte("published elsewhere"))
+	if err := s.Link(ctx, "mirror/file", cid, &common.Metadata{ContentType: "text/plain"}); err != nil {
+		t.Fatalf("Link() error = %v", err)
+	}
+	if !node.pinned(cid) {
+		t.Error("linked content not pinned")
+	}
+	if got := content(s.GetWithContext(ctx, "mirror/file")); got != "published elsewhere" {
+		t.Errorf("Get() = %q", got)
+	}
+	if meta, _ := s.GetMetadata(ctx, "mirror/file"); meta.Size != int64(len("published elsewhere")) {
+		t.Errorf("Size = %d", meta.Size)
+	}
+
+	node.dirs["bafkdir"] = true
+	if err := s.Link(ctx, "mirror/dir", "bafkdir", nil); !errors.Is(err, ErrNotFile) {
+		t.Errorf("Link(directory) error = %v, want ErrNotFile", err)
+	}
+	if node.pinned("bafkdir") {
+		t.Error("directory left pinned after a failed link")
+	}
+	if err := s.Link(ctx, "mirror/missing", "bafkmissing", nil); !errors.Is(err, common.ErrNotFound) {
+		t.Errorf("Link(missing) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestGetRange(t *testing.T) {
+	_, srv := newFakeNode(t)
+	s := newTestStorage(t, srv.URL, t.TempDir())
+	if err := s.Put("key", strings.NewReader("0123456789")); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		offset, length int64
+		want           string
+	}{
+		{0, -1, "0123456789"},
+		{3, 4, "3456"},
+		{8, -1, "89"},
+	}
+	for _, tt := range tests {
+		if got := content(s.GetRange(context.Background(), "key", tt.offset, tt.length)); got != tt.want {
+			t.Errorf("GetRange(%d, %d) = %q, want %q", tt.offset, tt.length, got, tt.want)
+		}
+	}
+}
+
+func TestStreamError(t *testing.T) {
+	node, srv := newFakeNode(t)
+	s := newTestStorage(t, srv.URL, t.TempDir())
+	cid, err := s.Add(context.Background(), "key", strings.NewReader("truncated"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	node.broken[cid] = true
+	rc, err := s.Get("key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	if _, err := io.ReadAll(rc); !errors.Is(err, common.ErrBackendUnavailable) {
+		t.Errorf("reading a stream that failed: error = %v, want ErrBackendUnavailable", err)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	node, srv := newFakeNode(t)
+	node.authorization = "Bearer secret"
+	s := New()
+	err := s.Configure(map[string]string{"path": t.TempDir(), "endpoint": srv.URL, "authorization": "Bearer secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put("key", strings.NewReader("authorized")); err != nil {
+		t.Fatalf("Put() with authorization: %v", err)
+	}
+
+	s = newTestStorage(t, srv.URL, t.TempDir())
+	if err := s.Put("key", strings.NewReader("x")); !errors.Is(err, common.ErrAccessDenied) {
+		t.Errorf("Put() without authorization: error = %v, want ErrAccessDenied", err)
+	}
+
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+	s = newTestStorage(t, down.URL, t.TempDir())
+	err = s.Put("key", bytes.NewReader([]byte("x")))
+	if !errors.Is(err, common.ErrBackendUnavailable) {
+		t.Errorf("Put() to a stopped node: error = %v, want ErrBackendUnavailable", err)
+	}
+	if err := s.Put("../escape", strings.NewReader("x")); err == nil {
+		t.Error("Put() accepted an invalid key")
+	}
+}
@@ -0,0 +1,557 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package jobs runs the server's background jobs, such as lifecycle
+// processing, scheduled replication, storage inventory and garbage
+// collection, from a single scheduler.
+//
+// Each job runs at a fixed interval and never overlaps itself: a run that
+// comes due while the previous one is still going is recorded as skipped.
+// When several servers share a backend, the scheduler only runs jobs while
+// Config.IsLeader reports this instance as leader (see pkg/ha), and
+// cancels running jobs when it stops leading. The outcome of every run is
+// kept in a bounded history, optionally persisted so it survives restarts
+// and, with a shared file system, a change of leader.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/replication"
+)
+
+const (
+	// DefaultHistorySize is the number of runs kept per job when
+	// Config.HistorySize is zero.
+	DefaultHistorySize = 20
+
+	// DefaultTick is how often the scheduler checks for due jobs when
+	// Config.Tick is zero.
+	DefaultTick = time.Second
+)
+
+var (
+	// ErrJobNotFound is returned for a job that is not registered.
+	ErrJobNotFound = fmt.Errorf("job %w", common.ErrNotFound)
+
+	// ErrJobExists is returned when registering a job name twice.
+	ErrJobExists = fmt.Errorf("%w: job already registered", common.ErrAlreadyExists)
+
+	// ErrJobRunning is returned by RunNow for a job that is already running.
+	ErrJobRunning = fmt.Errorf("%w: job is already running", common.ErrPreconditionFailed)
+
+	// ErrInvalidJob is returned when registering a job without a name, a
+	// function or a positive interval.
+	ErrInvalidJob = fmt.Errorf("%w: a job needs a name, a function and a positive interval", common.ErrInvalidArgument)
+
+	// ErrHistoryCorrupt is returned when a persisted history cannot be
+	// decoded.
+	ErrHistoryCorrupt = errors.New("job history is corrupt")
+)
+
+// Func does one run of a job. The returned message summarizes what the run
+// did, for example "3 objects deleted".
+type Func func(ctx context.Context) (string, error)
+
+// Job is a function run at a fixed interval.
+type Job struct {
+	// Name identifies the job.
+	Name string
+
+	// Interval is the time between the starts of two runs.
+	Interval time.Duration
+
+	// Timeout bounds a run. Zero means no limit.
+	Timeout time.Duration
+
+	// Run does one run.
+	Run Func
+}
+
+// Outcome is how a run ended.
+type Outcome string
+
+const (
+	// OutcomeSucceeded is a run whose function returned no error.
+	OutcomeSucceeded Outcome = "succeeded"
+
+	// OutcomeFailed is a run whose function returned an error, including
+	// runs canceled by a timeout or a loss of leadership.
+	OutcomeFailed Outcome = "failed"
+
+	// OutcomeSkipped is a run that came due while the previous run was
+	// still going.
+	OutcomeSkipped Outcome = "skipped"
+)
+
+// Run records one run of a job.
+type Run struct {
+	Job        string    `json:"job"`
+	Node       string    `json:"node,omitempty"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Outcome    Outcome   `json:"outcome"`
+	Message    string    `json:"message,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// Duration returns how long the run took.
+func (r *Run) Duration() time.Duration {
+	return r.FinishedAt.Sub(r.StartedAt)
+}
+
+// Status is the state of a job.
+type Status struct {
+	Name            string `json:"name"`
+	IntervalSeconds int64  `json:"interval_seconds"`
+
+	// Running reports whether a run is in progress on this instance.
+	Running bool `json:"running"`
+
+	// LastRun is the most recent finished run that was not skipped, or
+	// nil before the first one.
+	LastRun *Run `json:"last_run,omitempty"`
+
+	// NextRun is when the job is next due.
+	NextRun time.Time `json:"next_run"`
+
+	// History holds the recorded runs, newest first. It is only filled in
+	// by Get.
+	History []Run `json:"history,omitempty"`
+}
+
+// Config configures a Scheduler. Zero fields take their defaults.
+type Config struct {
+	// IsLeader reports whether this instance may run jobs. If nil, it
+	// always may.
+	IsLeader func() bool
+
+	// Node identifies this instance in run records.
+	Node string
+
+	// HistorySize is the number of runs kept per job.
+	HistorySize int
+
+	// HistoryPath is the file the history is persisted to. If empty, it is
+	// kept in memory and lost on restart.
+	HistoryPath string
+
+	// FileSystem stores HistoryPath. If nil, the OS file system is used.
+	FileSystem replication.FileSystem
+
+	// Shared reloads the history before it is read, for a FileSystem that
+	// other instances write too.
+	Shared bool
+
+	// Tick is how often the scheduler checks for due jobs.
+	Tick time.Duration
+}
+
+// entry is a registered job and its schedule.
+type entry struct {
+	job     Job
+	next    time.Time
+	running bool
+	cancel  context.CancelFunc
+}
+
+// Scheduler runs registered jobs at their intervals. It is safe for
+// concurrent use.
+type Scheduler struct {
+	cfg Config
+
+	mu      sync.Mutex
+	jobs    map[string]*entry
+	history map[string][]Run
+	leading bool
+
+	startOnce sync.Once
+	closeOnce sync.Once
+	stop      chan struct{}
+	done      chan struct{}
+	runs      sync.WaitGroup
+
+	// now is replaced by tests.
+	now func() time.Time
+}
+
+// New creates a Scheduler, loading the history persisted to
+// cfg.HistoryPath if it exists. Register jobs, then call Start.
+func New(cfg *Config) (*Scheduler, error) {
+	c := Config{}
+	if cfg != nil {
+		c = *cfg
+	}
+	if c.HistorySize <= 0 {
+		c.HistorySize = DefaultHistorySize
+	}
+	if c.Tick <= 0 {
+		c.Tick = DefaultTick
+	}
+	if c.FileSystem == nil {
+		c.FileSystem = &replication.OSFileSystem{}
+	}
+
+	s := &Scheduler{
+		cfg:     c,
+		jobs:    make(map[string]*entry),
+		history: make(map[string][]Run),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+		now:     time.Now,
+	}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Register adds a job. Its first run is due one interval after its last
+// recorded run, or straight away if it has none.
+func (s *Scheduler) Register(job Job) error {
+	if job.Name == "" || job.Run == nil || job.Interval <= 0 {
+		return ErrInvalidJob
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.jobs[job.Name]; exists {
+		return fmt.Errorf("%w: %s", ErrJobExists, job.Name)
+	}
+	e := &entry{job: job}
+	s.scheduleLocked(e)
+	s.jobs[job.Name] = e
+	return nil
+}
+
+// Start checks for due jobs every tick until s is closed.
+func (s *Scheduler) Start() {
+	s.startOnce.Do(func() {
+		go s.loop()
+	})
+}
+
+// Close stops scheduling, cancels running jobs and waits for them to
+// return.
+func (s *Scheduler) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.stop)
+		started := true
+		s.startOnce.Do(func() { started = false })
+		if started {
+			<-s.done
+		}
+		s.mu.Lock()
+		s.cancelRunningLocked()
+		s.mu.Unlock()
+		s.runs.Wait()
+	})
+	return nil
+}
+
+// RunNow runs a job immediately, whether or not this instance leads, and
+// returns the recorded run. It fails with ErrJobRunning if the job is
+// already running.
+func (s *Scheduler) RunNow(ctx context.Context, name string) (*Run, error) {
+	s.mu.Lock()
+	e, ok := s.jobs[name]
+	if !ok {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s", ErrJobNotFound, name)
+	}
+	if e.running {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s", ErrJobRunning, name)
+	}
+	runCtx := s.beginLocked(ctx, e)
+	s.mu.Unlock()
+
+	run := s.execute(runCtx, e)
+	return &run, nil
+}
+
+// List returns the status of every job, ordered by name, without history.
+func (s *Scheduler) List() []Status {
+	s.refresh()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := make([]Status, 0, len(s.jobs))
+	for _, e := range s.jobs {
+		statuses = append(statuses, s.statusLocked(e))
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
+
+// Get returns the status of a job with its history.
+func (s *Scheduler) Get(name string) (*Status, error) {
+	s.refresh()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.jobs[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrJobNotFound, name)
+	}
+	status := s.statusLocked(e)
+	history := s.history[name]
+	status.History = make([]Run, len(history))
+	for i, run := range history {
+		status.History[len(history)-1-i] = run
+	}
+	return &status, nil
+}
+
+func (s *Scheduler) loop() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.cfg.Tick)
+	defer ticker.Stop()
+
+	for {
+		s.tick()
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// tick starts the jobs that are due while this instance leads, and cancels
+// running jobs when it stops leading.
+func (s *Scheduler) tick() {
+	leading := s.cfg.IsLeader == nil || s.cfg.IsLeader()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !leading {
+		if s.leading {
+			s.cancelRunningLocked()
+		}
+		s.leading = false
+		return
+	}
+	if !s.leading {
+		// Pick up the runs of the previous leader so jobs are not rerun
+		// before they are due.
+		s.leading = true
+		if s.cfg.Shared {
+			s.reloadLocked()
+			for _, e := range s.jobs {
+				s.scheduleLocked(e)
+			}
+		}
+	}
+
+	now := s.now()
+	for _, e := range s.jobs {
+		if now.Before(e.next) {
+			continue
+		}
+		if e.running {
+			e.next = now.Add(e.job.Interval)
+			s.recordLocked(Run{
+				Job:        e.job.Name,
+				Node:       s.cfg.Node,
+				StartedAt:  now,
+				FinishedAt: now,
+				Outcome:    OutcomeSkipped,
+				Message:    "previous run still in progress",
+			})
+			continue
+		}
+		ctx := s.beginLocked(context.Background(), e)
+		s.runs.Add(1)
+		go func(e *entry) {
+			defer s.runs.Done()
+			s.execute(ctx, e)
+		}(e)
+	}
+}
+
+// beginLocked marks e running and returns the context of its run.
+func (s *Scheduler) beginLocked(ctx context.Context, e *entry) context.Context {
+	var cancel context.CancelFunc
+	if e.job.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, e.job.Timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	e.running = true
+	e.cancel = cancel
+	e.next = s.now().Add(e.job.Interval)
+	return ctx
+}
+
+// execute runs e and records the outcome.
+func (s *Scheduler) execute(ctx context.Context, e *entry) Run {
+	run := Run{Job: e.job.Name, Node: s.cfg.Node, StartedAt: s.now()}
+	message, err := e.job.Run(ctx)
+	run.FinishedAt = s.now()
+	run.Message = message
+	run.Outcome = OutcomeSucceeded
+	if err != nil {
+		run.Outcome = OutcomeFailed
+		run.Error = err.Error()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e.cancel()
+	e.running = false
+	e.cancel = nil
+	s.recordLocked(run)
+	return run
+}
+
+func (s *Scheduler) cancelRunningLocked() {
+	for _, e := range s.jobs {
+		if e.cancel != nil {
+			e.cancel()
+		}
+	}
+}
+
+// scheduleLocked sets when e is next due from its last recorded run.
+func (s *Scheduler) scheduleLocked(e *entry) {
+	if e.running {
+		return
+	}
+	e.next = s.now()
+	if last := s.lastRunLocked(e.job.Name); last != nil {
+		if next := last.StartedAt.Add(e.job.Interval); next.After(e.next) {
+			e.next = next
+		}
+	}
+}
+
+// lastRunLocked returns the most recent run of a job that was not skipped.
+func (s *Scheduler) lastRunLocked(name string) *Run {
+	history := s.history[name]
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].Outcome != OutcomeSkipped {
+			run := history[i]
+			return &run
+		}
+	}
+	return nil
+}
+
+func (s *Scheduler) statusLocked(e *entry) Status {
+	return Status{
+		Name:            e.job.Name,
+		IntervalSeconds: int64(e.job.Interval / time.Second),
+		Running:         e.running,
+		LastRun:         s.lastRunLocked(e.job.Name),
+		NextRun:         e.next,
+	}
+}
+
+// recordLocked adds run to the history of its job and persists it.
+func (s *Scheduler) recordLocked(run Run) {
+	if s.cfg.Shared {
+		// Keep runs recorded elsewhere since the last reload.
+		s.reloadLocked()
+	}
+	history := append(s.history[run.Job], run)
+	if len(history) > s.cfg.HistorySize {
+		history = history[len(history)-s.cfg.HistorySize:]
+	}
+	s.history[run.Job] = history
+	_ = s.saveLocked() // #nosec G104 -- The in-memory history stays authoritative; the next run retries
+}
+
+// refresh reloads a shared history and, on an instance that does not lead,
+// the schedule derived from it.
+func (s *Scheduler) refresh() {
+	if !s.cfg.Shared {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reloadLocked()
+	if !s.leading {
+		for _, e := range s.jobs {
+			s.scheduleLocked(e)
+		}
+	}
+}
+
+func (s *Scheduler) reloadLocked() {
+	_ = s.load() // #nosec G104 -- A history that cannot be read keeps the last one loaded
+}
+
+// load reads the persisted history, if any.
+func (s *Scheduler) load() error {
+	if s.cfg.HistoryPath == "" {
+		return nil
+	}
+	file, err := s.cfg.FileSystem.OpenFile(s.cfg.HistoryPath, os.O_RDONLY, 0)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read job history: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return fmt.Errorf("failed to read job history: %w", err)
+	}
+	history := make(map[string][]Run)
+	if err := json.Unmarshal(data, &history); err != nil {
+		return fmt.Errorf("%w: %v", ErrHistoryCorrupt, err)
+	}
+	s.history = history
+	return nil
+}
+
+// saveLocked persists the history by writing a temporary file and renaming
+// it over the previous one.
+func (s *Scheduler) saveLocked() error {
+	if s.cfg.HistoryPath == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(s.history, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmpName := s.cfg.HistoryPath + ".tmp"
+	tmp, err := s.cfg.FileSystem.OpenFile(tmpName, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = s.cfg.FileSystem.Remove(tmpName)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = s.cfg.FileSystem.Remove(tmpName)
+		return err
+	}
+	return s.cfg.FileSystem.Rename(tmpName, s.cfg.HistoryPath)
+}
@@ -0,0 +1,281 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package jobs
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeClock is a settable clock for schedule tests.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newTestScheduler(t *testing.T, cfg *Config) (*Scheduler, *fakeClock) {
+	t.Helper()
+	s, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	s.now = clock.Now
+	t.Cleanup(func() { _ = s.Close() })
+	return s, clock
+}
+
+// tickAndWait runs one scheduling round and waits for the runs it started.
+func tickAndWait(s *Scheduler) {
+	s.tick()
+	s.runs.Wait()
+}
+
+func TestRegister(t *testing.T) {
+	s, _ := newTestScheduler(t, nil)
+	run := func(context.Context) (string, error) { return "", nil }
+
+	for _, job := range []Job{
+		{Interval: time.Minute, Run: run},
+		{Name: "a", Interval: time.Minute},
+		{Name: "a", Run: run},
+	} {
+		if err := s.Register(job); !errors.Is(err, ErrInvalidJob) {
+			t.Errorf("Register(%+v) = %v, want ErrInvalidJob", job, err)
+		}
+	}
+	if err := s.Register(Job{Name: "a", Interval: time.Minute, Run: run}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if err := s.Register(Job{Name: "a", Interval: time.Minute, Run: run}); !errors.Is(err, ErrJobExists) {
+		t.Errorf("expected ErrJobExists, got %v", err)
+	}
+	if _, err := s.Get("missing"); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("expected ErrJobNotFound, got %v", err)
+	}
+}
+
+func TestScheduler_RunsAtInterval(t *testing.T) {
+	s, clock := newTestScheduler(t, &Config{Node: "n1"})
+	var runs atomic.Int32
+	if err := s.Register(Job{Name: "gc", Interval: time.Hour, Run: func(context.Context) (string, error) {
+		runs.Add(1)
+		return "2 removed", nil
+	}}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	failing := errors.New("backend unavailable")
+	if err := s.Register(Job{Name: "lifecycle", Interval: time.Hour, Run: func(context.Context) (string, error) {
+		return "", failing
+	}}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	tickAndWait(s)
+	if runs.Load() != 1 {
+		t.Fatalf("expected a first run straight away, got %d", runs.Load())
+	}
+	clock.Advance(30 * time.Minute)
+	tickAndWait(s)
+	if runs.Load() != 1 {
+		t.Fatalf("expected no run before the interval, got %d", runs.Load())
+	}
+	clock.Advance(30 * time.Minute)
+	tickAndWait(s)
+	if runs.Load() != 2 {
+		t.Fatalf("expected a second run after the interval, got %d", runs.Load())
+	}
+
+	statuses := s.List()
+	if len(statuses) != 2 || statuses[0].Name != "gc" || statuses[1].Name != "lifecycle" {
+		t.Fatalf("unexpected statuses %+v", statuses)
+	}
+	gc := statuses[0]
+	if gc.LastRun == nil || gc.LastRun.Outcome != OutcomeSucceeded || gc.LastRun.Message != "2 removed" || gc.LastRun.Node != "n1" {
+		t.Errorf("unexpected last run %+v", gc.LastRun)
+	}
+	if want := clock.Now().Add(time.Hour); !gc.NextRun.Equal(want) {
+		t.Errorf("next run = %v, want %v", gc.NextRun, want)
+	}
+	if gc.IntervalSeconds != 3600 || gc.History != nil {
+		t.Errorf("unexpected status %+v", gc)
+	}
+
+	lifecycle, err := s.Get("lifecycle")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if len(lifecycle.History) != 2 || lifecycle.History[0].Outcome != OutcomeFailed || lifecycle.History[0].Error != failing.Error() {
+		t.Errorf("unexpected history %+v", lifecycle.History)
+	}
+}
+
+func TestScheduler_SkipsOverlappingRuns(t *testing.T) {
+	s, clock := newTestScheduler(t, nil)
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+	if err := s.Register(Job{Name: "scrub", Interval: time.Minute, Run: func(context.Context) (string, error) {
+		started <- struct{}{}
+		<-release
+		return "", nil
+	}}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	s.tick()
+	<-started
+	if _, err := s.RunNow(context.Background(), "scrub"); !errors.Is(err, ErrJobRunning) {
+		t.Errorf("expected ErrJobRunning, got %v", err)
+	}
+	clock.Advance(time.Minute)
+	s.tick()
+	close(release)
+	s.runs.Wait()
+
+	status, _ := s.Get("scrub")
+	if len(status.History) != 2 || status.History[0].Outcome != OutcomeSucceeded || status.History[1].Outcome != OutcomeSkipped {
+		t.Errorf("expected a skipped then a succeeded run, got %+v", status.History)
+	}
+	if status.LastRun == nil || status.LastRun.Outcome != OutcomeSucceeded {
+		t.Errorf("expected skipped runs to be left out of the last run, got %+v", status.LastRun)
+	}
+}
+
+func TestScheduler_Leadership(t *testing.T) {
+	var leader atomic.Bool
+	s, _ := newTestScheduler(t, &Config{IsLeader: leader.Load})
+	canceled := make(chan struct{})
+	started := make(chan struct{}, 1)
+	if err := s.Register(Job{Name: "replication", Interval: time.Minute, Run: func(ctx context.Context) (string, error) {
+		started <- struct{}{}
+		<-ctx.Done()
+		close(canceled)
+		return "", ctx.Err()
+	}}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	tickAndWait(s)
+	if status, _ := s.Get("replication"); len(status.History) != 0 {
+		t.Fatalf("expected no run on a follower, got %+v", status.History)
+	}
+
+	leader.Store(true)
+	s.tick()
+	<-started
+
+	// Losing leadership cancels the running job.
+	leader.Store(false)
+	s.tick()
+	<-canceled
+	s.runs.Wait()
+	if status, _ := s.Get("replication"); len(status.History) != 1 || status.History[0].Outcome != OutcomeFailed {
+		t.Errorf("expected a canceled run, got %+v", status.History)
+	}
+}
+
+func TestScheduler_RunNow(t *testing.T) {
+	s, _ := newTestScheduler(t, &Config{IsLeader: func() bool { return false }})
+	if err := s.Register(Job{Name: "inventory", Interval: time.Hour, Timeout: time.Second, Run: func(ctx context.Context) (string, error) {
+		if _, ok := ctx.Deadline(); !ok {
+			return "", errors.New("expected a deadline")
+		}
+		return "ok", nil
+	}}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	run, err := s.RunNow(context.Background(), "inventory")
+	if err != nil || run.Outcome != OutcomeSucceeded || run.Message != "ok" {
+		t.Errorf("RunNow() = %+v, %v", run, err)
+	}
+	if _, err := s.RunNow(context.Background(), "missing"); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("expected ErrJobNotFound, got %v", err)
+	}
+}
+
+func TestScheduler_History(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs-history.json")
+	run := func(context.Context) (string, error) { return "", nil }
+
+	s, clock := newTestScheduler(t, &Config{HistoryPath: path, HistorySize: 2})
+	if err := s.Register(Job{Name: "gc", Interval: time.Hour, Run: run}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		tickAndWait(s)
+		clock.Advance(time.Hour)
+	}
+	status, _ := s.Get("gc")
+	if len(status.History) != 2 {
+		t.Fatalf("expected history to be capped at 2 runs, got %d", len(status.History))
+	}
+
+	// A restarted scheduler keeps the history and does not run the job
+	// before it is due.
+	restarted, err := New(&Config{HistoryPath: path})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	restarted.now = func() time.Time { return clock.Now().Add(-30 * time.Minute) }
+	if err := restarted.Register(Job{Name: "gc", Interval: time.Hour, Run: run}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	status, _ = restarted.Get("gc")
+	if len(status.History) != 2 || !status.NextRun.Equal(clock.Now()) {
+		t.Errorf("unexpected restored status %+v", status)
+	}
+}
+
+func TestScheduler_SharedHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs-history.json")
+	run := func(context.Context) (string, error) { return "", nil }
+
+	leader, clock := newTestScheduler(t, &Config{HistoryPath: path, Shared: true, Node: "a"})
+	follower, _ := newTestScheduler(t, &Config{HistoryPath: path, Shared: true, Node: "b", IsLeader: func() bool { return false }})
+	follower.now = clock.Now
+	for _, s := range []*Scheduler{leader, follower} {
+		if err := s.Register(Job{Name: "gc", Interval: time.Hour, Run: run}); err != nil {
+			t.Fatalf("Register: %v", err)
+		}
+	}
+
+	tickAndWait(leader)
+	tickAndWait(follower)
+
+	// The follower reports the leader's runs and schedule.
+	status, err := follower.Get("gc")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if status.LastRun == nil || status.LastRun.Node != "a" || !status.NextRun.Equal(clock.Now().Add(time.Hour)) {
+		t.Errorf("unexpected follower status %+v", status)
+	}
+}
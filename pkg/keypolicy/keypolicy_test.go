@@ -0,0 +1,185 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package keypolicy
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+func TestPolicy_Check(t *testing.T) {
+	policy := &Policy{Rules: []Rule{
+		{Prefix: "", ForbiddenSegments: []string{"..", ""}},
+		{
+			Prefix:            "datasets/",
+			Charset:           "a-z0-9._/-",
+			MaxLength:         32,
+			Extensions:        []string{"*.parquet", "*.csv"},
+			ForbiddenSegments: []string{"tmp", ".*"},
+		},
+	}}
+	if err := policy.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	tests := []struct {
+		key    string
+		reason string
+	}{
+		{"notes/readme.md", ""},
+		{"notes/../secret", `segment ".."`},
+		{"notes//readme.md", `segment ""`},
+		{"datasets/sales-2025.parquet", ""},
+		{"datasets/q1/orders.csv", ""},
+		{"datasets/Sales.csv", `contains 'S'`},
+		{"datasets/sales 2025.csv", `contains ' '`},
+		{"datasets/sales.json", "does not match any of *.parquet, *.csv"},
+		{"datasets/tmp/orders.csv", `segment "tmp"`},
+		{"datasets/.hidden/orders.csv", `matches forbidden pattern ".*"`},
+		{"datasets/" + strings.Repeat("a", 30) + ".csv", "more than 32"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			err := policy.Check(tt.key)
+			if tt.reason == "" {
+				if err != nil {
+					t.Errorf("Check() error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrKeyRejected) || !errors.Is(err, common.ErrInvalidArgument) {
+				t.Fatalf("Check() error = %v, want ErrKeyRejected wrapping ErrInvalidArgument", err)
+			}
+			if !strings.Contains(err.Error(), tt.reason) {
+				t.Errorf("Check() error = %q, want it to mention %q", err, tt.reason)
+			}
+		})
+	}
+}
+
+func TestPolicy_Validate(t *testing.T) {
+	invalid := []*Policy{
+		{Rules: []Rule{{Prefix: "a/"}, {Prefix: "a/"}}},
+		{Rules: []Rule{{Charset: "z-a"}}},
+		{Rules: []Rule{{MaxLength: -1}}},
+		{Rules: []Rule{{Extensions: []string{"[.csv"}}}},
+		{Rules: []Rule{{ForbiddenSegments: []string{"\\"}}}},
+	}
+	for _, policy := range invalid {
+		if err := policy.Validate(); !errors.Is(err, ErrInvalidPolicy) {
+			t.Errorf("Validate(%+v) error = %v, want ErrInvalidPolicy", policy.Rules, err)
+		}
+	}
+	if err := (&Policy{Rules: []Rule{{Charset: "-a-z_"}}}).Validate(); err != nil {
+		t.Errorf("Validate() with literal '-' error = %v", err)
+	}
+}
+
+func TestInCharset(t *testing.T) {
+	for _, tt := range []struct {
+		charset string
+		r       rune
+		want    bool
+	}{
+		{"a-z", 'm', true},
+		{"a-z", 'A', false},
+		{"a-z0-9", '5', true},
+		{"-a", '-', true},
+		{"a-", '-', true},
+		{"a-z", '-', false},
+		{"a-zé", 'é', true},
+	} {
+		if got := inCharset(tt.charset, tt.r); got != tt.want {
+			t.Errorf("inCharset(%q, %q) = %v, want %v", tt.charset, tt.r, got, tt.want)
+		}
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+	yamlFile := filepath.Join(dir, "policy.yaml")
+	if err := os.WriteFile(yamlFile, []byte(`
+rules:
+  - prefix: datasets/
+    charset: a-z0-9._/-
+    max_length: 128
+    extensions: ["*.parquet"]
+    forbidden_segments: [".."]
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	policy, err := LoadFile(yamlFile)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if len(policy.Rules) != 1 || policy.Rules[0].MaxLength != 128 || policy.Rules[0].Extensions[0] != "*.parquet" {
+		t.Errorf("LoadFile() = %+v", policy)
+	}
+
+	badFile := filepath.Join(dir, "bad.yaml")
+	if err := os.WriteFile(badFile, []byte("rules:\n  - charset: z-a\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{badFile, filepath.Join(dir, "missing.yaml")} {
+		if _, err := LoadFile(name); !errors.Is(err, ErrInvalidPolicy) {
+			t.Errorf("LoadFile(%s) error = %v, want ErrInvalidPolicy", filepath.Base(name), err)
+		}
+	}
+}
+
+func TestStorage(t *testing.T) {
+	ctx := context.Background()
+	backend := memory.New()
+	s := NewStorage(backend, &Policy{Rules: []Rule{{Prefix: "logs/", Extensions: []string{"*.log"}}}})
+	if s.Underlying() != backend {
+		t.Error("Underlying() did not return the wrapped backend")
+	}
+
+	if err := s.Put("logs/a.log", strings.NewReader("a")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if err := s.PutWithMetadata(ctx, "other/b.txt", strings.NewReader("b"), nil); err != nil {
+		t.Fatalf("PutWithMetadata() outside the rule error = %v", err)
+	}
+	if err := s.Append(ctx, "logs/a.log", strings.NewReader("a")); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+
+	// Rejected writes never reach the backend.
+	for name, write := range map[string]func() error{
+		"PutWithContext":  func() error { return s.PutWithContext(ctx, "logs/a.txt", strings.NewReader("x")) },
+		"PutWithMetadata": func() error { return s.PutWithMetadata(ctx, "logs/a.txt", strings.NewReader("x"), nil) },
+		"Append":          func() error { return s.Append(ctx, "logs/a.txt", strings.NewReader("x")) },
+		"Compose":         func() error { return s.Compose(ctx, "logs/a.txt", "logs/a.log") },
+	} {
+		if err := write(); !errors.Is(err, ErrKeyRejected) {
+			t.Errorf("%s() error = %v, want ErrKeyRejected", name, err)
+		}
+	}
+	if exists, _ := backend.Exists(ctx, "logs/a.txt"); exists {
+		t.Error("rejected object was stored")
+	}
+
+	// Sources of a compose are not checked.
+	if err := s.Compose(ctx, "logs/all.log", "logs/a.log", "other/b.txt"); err != nil {
+		t.Errorf("Compose() error = %v", err)
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package keypolicy enforces per-prefix naming rules on the keys of written
+// objects: the characters they may contain, their length, the extensions
+// they must end in and the path segments they may not contain.
+package keypolicy
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+var (
+	// ErrKeyRejected is returned when a key breaks the rule for its prefix.
+	// Errors wrapping it also wrap common.ErrInvalidArgument.
+	ErrKeyRejected = errors.New("key rejected")
+
+	// ErrInvalidPolicy is returned when a policy cannot be loaded or is
+	// malformed.
+	ErrInvalidPolicy = errors.New("invalid key policy")
+)
+
+// Rule restricts the keys that may be written under Prefix. Zero fields
+// impose no restriction.
+type Rule struct {
+	// Prefix selects the keys the rule applies to. An empty prefix matches
+	// every key.
+	Prefix string `yaml:"prefix" json:"prefix"`
+
+	// Charset lists the characters keys may contain, as in a regular
+	// expression character class without the brackets: "a-z0-9._/-"
+	// allows lowercase letters, digits, '.', '_', '/' and '-'. A '-' at
+	// either end is literal.
+	Charset string `yaml:"charset,omitempty" json:"charset,omitempty"`
+
+	// MaxLength is the maximum length of a key in bytes.
+	MaxLength int `yaml:"max_length,omitempty" json:"max_length,omitempty"`
+
+	// Extensions, when non-empty, are path.Match patterns such as
+	// "*.parquet" of which the key's last segment must match one.
+	Extensions []string `yaml:"extensions,omitempty" json:"extensions,omitempty"`
+
+	// ForbiddenSegments are path.Match patterns that no '/'-separated
+	// segment of the key may match, such as "..", ".*" or "" (an empty
+	// segment, as in "a//b").
+	ForbiddenSegments []string `yaml:"forbidden_segments,omitempty" json:"forbidden_segments,omitempty"`
+}
+
+// Policy configures key naming rules.
+type Policy struct {
+	// Rules are matched by longest prefix; only the most specific rule
+	// applies to a key.
+	Rules []Rule `yaml:"rules" json:"rules"`
+}
+
+// LoadFile reads a policy from a YAML or JSON file.
+func LoadFile(filename string) (*Policy, error) {
+	data, err := os.ReadFile(filename) // #nosec G304 -- path supplied by the operator
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPolicy, err)
+	}
+	var policy Policy
+	if err := yaml.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidPolicy, filename, err)
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// Validate checks that every rule's charset and patterns are well formed
+// and that no prefix is configured twice.
+func (p *Policy) Validate() error {
+	seen := make(map[string]bool, len(p.Rules))
+	for _, rule := range p.Rules {
+		if seen[rule.Prefix] {
+			return fmt.Errorf("%w: duplicate rule for prefix %q", ErrInvalidPolicy, rule.Prefix)
+		}
+		seen[rule.Prefix] = true
+		if rule.MaxLength < 0 {
+			return fmt.Errorf("%w: prefix %q: negative max_length %d", ErrInvalidPolicy, rule.Prefix, rule.MaxLength)
+		}
+		if err := validCharset(rule.Charset); err != nil {
+			return fmt.Errorf("%w: prefix %q: charset %q: %w", ErrInvalidPolicy, rule.Prefix, rule.Charset, err)
+		}
+		for _, pattern := range append(append([]string{}, rule.Extensions...), rule.ForbiddenSegments...) {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("%w: prefix %q: malformed pattern %q", ErrInvalidPolicy, rule.Prefix, pattern)
+			}
+		}
+	}
+	return nil
+}
+
+// rule returns the most specific rule matching key, or nil when none does.
+func (p *Policy) rule(key string) *Rule {
+	var match *Rule
+	for i := range p.Rules {
+		rule := &p.Rules[i]
+		if !strings.HasPrefix(key, rule.Prefix) {
+			continue
+		}
+		if match == nil || len(rule.Prefix) > len(match.Prefix) {
+			match = rule
+		}
+	}
+	return match
+}
+
+// Check reports whether an object may be written under key, describing the
+// first rule it breaks.
+func (p *Policy) Check(key string) error {
+	rule := p.rule(key)
+	if rule == nil {
+		return nil
+	}
+	if rule.MaxLength > 0 && len(key) > rule.MaxLength {
+		return rejected(key, rule, fmt.Sprintf("is %d bytes long, more than %d", len(key), rule.MaxLength))
+	}
+	if rule.Charset != "" {
+		for _, r := range key {
+			if !inCharset(rule.Charset, r) {
+				return rejected(key, rule, fmt.Sprintf("contains %q, outside the allowed characters [%s]", r, rule.Charset))
+			}
+		}
+	}
+	segments := strings.Split(key, "/")
+	for _, segment := range segments {
+		if pattern, ok := matchAny(rule.ForbiddenSegments, segment); ok {
+			return rejected(key, rule, fmt.Sprintf("has segment %q, which matches forbidden pattern %q", segment, pattern))
+		}
+	}
+	if len(rule.Extensions) > 0 {
+		if _, ok := matchAny(rule.Extensions, segments[len(segments)-1]); !ok {
+			return rejected(key, rule, fmt.Sprintf("does not match any of %s", strings.Join(rule.Extensions, ", ")))
+		}
+	}
+	return nil
+}
+
+func rejected(key string, rule *Rule, reason string) error {
+	return fmt.Errorf("%w: %w: %q %s (rule prefix %q)",
+		common.ErrInvalidArgument, ErrKeyRejected, key, reason, rule.Prefix)
+}
+
+// matchAny returns the first of patterns that name matches.
+func matchAny(patterns []string, name string) (string, bool) {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return pattern, true
+		}
+	}
+	return "", false
+}
+
+// validCharset checks that every range of charset is ordered.
+func validCharset(charset string) error {
+	if !utf8.ValidString(charset) {
+		return errors.New("not valid UTF-8")
+	}
+	runes := []rune(charset)
+	for i := 0; i+2 < len(runes); i++ {
+		if runes[i+1] != '-' {
+			continue
+		}
+		if runes[i] > runes[i+2] {
+			return fmt.Errorf("range %c-%c is out of order", runes[i], runes[i+2])
+		}
+		i += 2
+	}
+	return nil
+}
+
+// inCharset reports whether r is one of the characters or ranges of charset.
+func inCharset(charset string, r rune) bool {
+	runes := []rune(charset)
+	for i := 0; i < len(runes); i++ {
+		if i+2 < len(runes) && runes[i+1] == '-' {
+			if runes[i] <= r && r <= runes[i+2] {
+				return true
+			}
+			i += 2
+			continue
+		}
+		if runes[i] == r {
+			return true
+		}
+	}
+	return false
+}
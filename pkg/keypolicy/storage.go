@@ -0,0 +1,112 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package keypolicy
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// Storage wraps a backend and checks the key of every Put, Append and
+// Compose against a Policy before it reaches the backend. Reads, deletes,
+// listings and metadata updates of existing objects are passed through.
+type Storage struct {
+	common.Storage
+	policy *Policy
+}
+
+// NewStorage returns underlying wrapped so that written keys are checked
+// against policy.
+func NewStorage(underlying common.Storage, policy *Policy) *Storage {
+	return &Storage{Storage: underlying, policy: policy}
+}
+
+// Policy returns the policy enforced by s.
+func (s *Storage) Policy() *Policy {
+	return s.policy
+}
+
+// Underlying returns the wrapped backend.
+func (s *Storage) Underlying() common.Storage {
+	return s.Storage
+}
+
+// GetRange reads a byte range from the wrapped backend, falling back to
+// discarding the leading bytes of a full read when it cannot read ranges.
+func (s *Storage) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	if rr, ok := s.Storage.(common.RangeReader); ok {
+		return rr.GetRange(ctx, key, offset, length)
+	}
+	rc, err := s.Storage.GetWithContext(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return common.SliceRange(rc, offset, length)
+}
+
+// GetAsOf reads an earlier version of an object from the wrapped backend.
+func (s *Storage) GetAsOf(ctx context.Context, key string, at time.Time) (io.ReadCloser, *common.Metadata, error) {
+	return common.GetAsOf(ctx, s.Storage, key, at)
+}
+
+// Put checks the key and stores an object.
+func (s *Storage) Put(key string, data io.Reader) error {
+	return s.PutWithContext(context.Background(), key, data)
+}
+
+// PutWithContext checks the key and stores an object.
+func (s *Storage) PutWithContext(ctx context.Context, key string, data io.Reader) error {
+	if err := s.policy.Check(key); err != nil {
+		return err
+	}
+	return s.Storage.PutWithContext(ctx, key, data)
+}
+
+// PutWithMetadata checks the key and stores an object with metadata.
+func (s *Storage) PutWithMetadata(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) error {
+	if err := s.policy.Check(key); err != nil {
+		return err
+	}
+	return s.Storage.PutWithMetadata(ctx, key, data, metadata)
+}
+
+// PutWithInfo checks the key, stores an object with metadata and returns
+// what was stored.
+func (s *Storage) PutWithInfo(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) (*common.ObjectInfo, error) {
+	if err := s.policy.Check(key); err != nil {
+		return nil, err
+	}
+	return common.PutWithInfo(ctx, s.Storage, key, data, metadata)
+}
+
+// Append checks the key and adds data to the end of an object.
+func (s *Storage) Append(ctx context.Context, key string, data io.Reader) error {
+	if err := s.policy.Check(key); err != nil {
+		return err
+	}
+	return common.Append(ctx, s.Storage, key, data)
+}
+
+// Compose checks destKey and concatenates srcKeys into it. The source keys
+// are not checked, so objects written before the policy can be combined
+// into a conforming key.
+func (s *Storage) Compose(ctx context.Context, destKey string, srcKeys ...string) error {
+	if err := s.policy.Check(destKey); err != nil {
+		return err
+	}
+	return common.Compose(ctx, s.Storage, destKey, srcKeys...)
+}
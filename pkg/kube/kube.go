@@ -0,0 +1,286 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package kube is a small client for the Kubernetes API, enough for the
+// objstore operator to manage its custom resources and workloads and for
+// servers to elect leaders with Leases. It speaks the REST API directly
+// with the pod's service account, or through "kubectl proxy" during
+// development, rather than pulling in client-go.
+package kube
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNotInCluster is returned by InClusterConfig outside a pod.
+	ErrNotInCluster = errors.New("kube: not running in a Kubernetes cluster")
+
+	// ErrNotFound is returned when an object does not exist.
+	ErrNotFound = errors.New("kube: not found")
+
+	// ErrConflict is returned when an object already exists, or was changed
+	// since it was read.
+	ErrConflict = errors.New("kube: conflict")
+
+	// ErrAPI is returned for other errors reported by the API server.
+	ErrAPI = errors.New("kube: API error")
+)
+
+// ServiceAccountDir is where Kubernetes mounts a pod's service account.
+const ServiceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+// defaultTimeout bounds each API request.
+const defaultTimeout = 30 * time.Second
+
+// Config locates the API server.
+type Config struct {
+	// Host is the API server URL, such as https://10.96.0.1:443, or
+	// http://127.0.0.1:8001 for "kubectl proxy".
+	Host string
+
+	// TokenFile holds the bearer token. It is read for every request, so
+	// rotated service account tokens are picked up. Empty sends none.
+	TokenFile string
+
+	// CAFile holds the CA certificates the API server's certificate is
+	// verified against. Empty uses the system roots.
+	CAFile string
+
+	// Namespace is the namespace the pod runs in, when known.
+	Namespace string
+}
+
+// InClusterConfig returns the configuration of a pod's service account.
+func InClusterConfig() (*Config, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return nil, ErrNotInCluster
+	}
+	config := &Config{
+		Host:      "https://" + net.JoinHostPort(host, port),
+		TokenFile: filepath.Join(ServiceAccountDir, "token"),
+		CAFile:    filepath.Join(ServiceAccountDir, "ca.crt"),
+	}
+	if namespace, err := os.ReadFile(filepath.Join(ServiceAccountDir, "namespace")); err == nil {
+		config.Namespace = strings.TrimSpace(string(namespace))
+	}
+	return config, nil
+}
+
+// Client calls the Kubernetes API.
+type Client struct {
+	host       string
+	tokenFile  string
+	httpClient *http.Client
+
+	// Namespace is the configured namespace.
+	Namespace string
+}
+
+// NewClient creates a client.
+func NewClient(config *Config) (*Client, error) {
+	if config.Host == "" {
+		return nil, fmt.Errorf("%w: no API server host", ErrAPI)
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if config.CAFile != "" {
+		pem, err := os.ReadFile(config.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("kube: reading CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("kube: no certificates in %s", config.CAFile)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+	return &Client{
+		host:       strings.TrimSuffix(config.Host, "/"),
+		tokenFile:  config.TokenFile,
+		httpClient: &http.Client{Transport: transport, Timeout: defaultTimeout},
+		Namespace:  config.Namespace,
+	}, nil
+}
+
+// Path returns the API path of a resource collection, or of the named
+// object when name is set. The core group is "". An empty namespace
+// addresses every namespace, or cluster-scoped resources.
+func Path(group, version, namespace, resource, name string) string {
+	var b strings.Builder
+	if group == "" {
+		b.WriteString("/api/" + version)
+	} else {
+		b.WriteString("/apis/" + group + "/" + version)
+	}
+	if namespace != "" {
+		b.WriteString("/namespaces/" + url.PathEscape(namespace))
+	}
+	b.WriteString("/" + resource)
+	if name != "" {
+		b.WriteString("/" + url.PathEscape(name))
+	}
+	return b.String()
+}
+
+// Get reads the object or list at path into out.
+func (c *Client) Get(ctx context.Context, path string, out any) error {
+	return c.do(ctx, http.MethodGet, path, "", nil, out)
+}
+
+// Create creates obj in the collection at path, decoding the created
+// object into out when it is not nil.
+func (c *Client) Create(ctx context.Context, path string, obj, out any) error {
+	return c.do(ctx, http.MethodPost, path, "application/json", obj, out)
+}
+
+// Update replaces the object at path. The object's resourceVersion makes
+// the update fail with ErrConflict when the object changed since it was
+// read.
+func (c *Client) Update(ctx context.Context, path string, obj, out any) error {
+	return c.do(ctx, http.MethodPut, path, "application/json", obj, out)
+}
+
+// Apply creates or updates the object at path with server-side apply,
+// owning the fields set in obj as fieldManager. Fields set by other
+// managers are taken over.
+func (c *Client) Apply(ctx context.Context, path, fieldManager string, obj, out any) error {
+	path += "?fieldManager=" + url.QueryEscape(fieldManager) + "&force=true"
+	return c.do(ctx, http.MethodPatch, path, "application/apply-patch+yaml", obj, out)
+}
+
+// MergePatch applies a JSON merge patch to the object at path, such as a
+// "/status" subresource.
+func (c *Client) MergePatch(ctx context.Context, path string, patch, out any) error {
+	return c.do(ctx, http.MethodPatch, path, "application/merge-patch+json", patch, out)
+}
+
+// Delete deletes the object at path.
+func (c *Client) Delete(ctx context.Context, path string) error {
+	return c.do(ctx, http.MethodDelete, path, "", nil, nil)
+}
+
+// status is the body of an API error.
+type status struct {
+	Message string `json:"message"`
+	Reason  string `json:"reason"`
+}
+
+func (c *Client) do(ctx context.Context, method, path, contentType string, body, out any) error {
+	var reader io.Reader = http.NoBody
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.host+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.tokenFile != "" {
+		token, err := os.ReadFile(c.tokenFile)
+		if err != nil {
+			return fmt.Errorf("kube: reading token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= 300 {
+		var s status
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10)) //nolint:errcheck // the status code is reported without a body
+		if json.Unmarshal(data, &s) != nil || s.Message == "" {
+			s.Message = strings.TrimSpace(string(data))
+		}
+		switch resp.StatusCode {
+		case http.StatusNotFound:
+			return fmt.Errorf("%w: %s %s: %s", ErrNotFound, method, path, s.Message)
+		case http.StatusConflict:
+			return fmt.Errorf("%w: %s %s: %s", ErrConflict, method, path, s.Message)
+		default:
+			return fmt.Errorf("%w %d: %s %s: %s", ErrAPI, resp.StatusCode, method, path, s.Message)
+		}
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// ObjectMeta is the subset of object metadata the objstore components use.
+type ObjectMeta struct {
+	Name              string            `json:"name,omitempty"`
+	Namespace         string            `json:"namespace,omitempty"`
+	UID               string            `json:"uid,omitempty"`
+	ResourceVersion   string            `json:"resourceVersion,omitempty"`
+	Generation        int64             `json:"generation,omitempty"`
+	Labels            map[string]string `json:"labels,omitempty"`
+	Annotations       map[string]string `json:"annotations,omitempty"`
+	OwnerReferences   []OwnerReference  `json:"ownerReferences,omitempty"`
+	DeletionTimestamp *time.Time        `json:"deletionTimestamp,omitempty"`
+}
+
+// OwnerReference makes an object garbage collected with its owner.
+type OwnerReference struct {
+	APIVersion         string `json:"apiVersion"`
+	Kind               string `json:"kind"`
+	Name               string `json:"name"`
+	UID                string `json:"uid"`
+	Controller         bool   `json:"controller,omitempty"`
+	BlockOwnerDeletion bool   `json:"blockOwnerDeletion,omitempty"`
+}
+
+// Secret is a core/v1 Secret. Data values are decoded from base64 by
+// encoding/json.
+type Secret struct {
+	Metadata ObjectMeta        `json:"metadata"`
+	Data     map[string][]byte `json:"data,omitempty"`
+}
+
+// SecretData reads a Secret's data as strings.
+func (c *Client) SecretData(ctx context.Context, namespace, name string) (map[string]string, error) {
+	var secret Secret
+	if err := c.Get(ctx, Path("", "v1", namespace, "secrets", name), &secret); err != nil {
+		return nil, err
+	}
+	data := make(map[string]string, len(secret.Data))
+	for key, value := range secret.Data {
+		data[key] = string(value)
+	}
+	return data, nil
+}
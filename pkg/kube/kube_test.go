@@ -0,0 +1,123 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package kube
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPath(t *testing.T) {
+	tests := []struct {
+		group, version, namespace, resource, name string
+		want                                      string
+	}{
+		{"", "v1", "default", "secrets", "creds", "/api/v1/namespaces/default/secrets/creds"},
+		{"apps", "v1", "objstore", "deployments", "", "/apis/apps/v1/namespaces/objstore/deployments"},
+		{"objstore.automatethethings.com", "v1alpha1", "", "lifecyclepolicies", "", "/apis/objstore.automatethethings.com/v1alpha1/lifecyclepolicies"},
+	}
+	for _, tt := range tests {
+		if got := Path(tt.group, tt.version, tt.namespace, tt.resource, tt.name); got != tt.want {
+			t.Errorf("Path() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestClient(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("first\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got = append(got, r.Method+" "+r.URL.RequestURI()+" "+r.Header.Get("Content-Type")+" "+r.Header.Get("Authorization")+" "+string(body))
+		switch r.URL.Path {
+		case "/api/v1/namespaces/default/secrets/creds":
+			_, _ = w.Write([]byte(`{"metadata":{"name":"creds"},"data":{"secretKey":"czNjcjN0"}}`))
+		case "/api/v1/namespaces/default/secrets/missing":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"kind":"Status","message":"secrets \"missing\" not found","reason":"NotFound"}`))
+		case "/apis/coordination.k8s.io/v1/namespaces/default/leases/jobs":
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"message":"the object has been modified"}`))
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte("forbidden"))
+		}
+	}))
+	defer server.Close()
+
+	c, err := NewClient(&Config{Host: server.URL, TokenFile: tokenFile, Namespace: "default"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	data, err := c.SecretData(ctx, "default", "creds")
+	if err != nil || data["secretKey"] != "s3cr3t" {
+		t.Errorf("SecretData() = %v, %v", data, err)
+	}
+	if _, err := c.SecretData(ctx, "default", "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing secret error = %v, want ErrNotFound", err)
+	}
+
+	// The token is reread, so rotated tokens are used
+	if err := os.WriteFile(tokenFile, []byte("second"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	lease := Path("coordination.k8s.io", "v1", "default", "leases", "jobs")
+	if err := c.Update(ctx, lease, map[string]string{"a": "b"}, nil); !errors.Is(err, ErrConflict) {
+		t.Errorf("Update() error = %v, want ErrConflict", err)
+	}
+	err = c.Apply(ctx, "/apis/apps/v1/namespaces/default/deployments/web", "objstore-operator", map[string]string{"kind": "Deployment"}, nil)
+	if !errors.Is(err, ErrAPI) {
+		t.Errorf("Apply() error = %v, want ErrAPI", err)
+	}
+
+	want := []string{
+		"GET /api/v1/namespaces/default/secrets/creds  Bearer first ",
+		"GET /api/v1/namespaces/default/secrets/missing  Bearer first ",
+		`PUT /apis/coordination.k8s.io/v1/namespaces/default/leases/jobs application/json Bearer second {"a":"b"}`,
+		`PATCH /apis/apps/v1/namespaces/default/deployments/web?fieldManager=objstore-operator&force=true application/apply-patch+yaml Bearer second {"kind":"Deployment"}`,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("requests = %q", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("request %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestInClusterConfig(t *testing.T) {
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	if _, err := InClusterConfig(); !errors.Is(err, ErrNotInCluster) {
+		t.Errorf("InClusterConfig() error = %v, want ErrNotInCluster", err)
+	}
+	t.Setenv("KUBERNETES_SERVICE_HOST", "fd00::1")
+	t.Setenv("KUBERNETES_SERVICE_PORT", "443")
+	config, err := InClusterConfig()
+	if err != nil || config.Host != "https://[fd00::1]:443" {
+		t.Errorf("InClusterConfig() = %+v, %v", config, err)
+	}
+}
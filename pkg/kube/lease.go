@@ -0,0 +1,256 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package kube
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// DefaultLeaseDuration is how long a leader keeps a Lease without renewing
+// it when none is configured.
+const DefaultLeaseDuration = 15 * time.Second
+
+// microTime is the format of Lease times.
+const microTime = "2006-01-02T15:04:05.000000Z07:00"
+
+// Lease is a coordination.k8s.io/v1 Lease.
+type Lease struct {
+	APIVersion string     `json:"apiVersion"`
+	Kind       string     `json:"kind"`
+	Metadata   ObjectMeta `json:"metadata"`
+	Spec       LeaseSpec  `json:"spec"`
+}
+
+// LeaseSpec is the state of a Lease. Times are in microTime format.
+type LeaseSpec struct {
+	HolderIdentity       string `json:"holderIdentity,omitempty"`
+	LeaseDurationSeconds int32  `json:"leaseDurationSeconds,omitempty"`
+	AcquireTime          string `json:"acquireTime,omitempty"`
+	RenewTime            string `json:"renewTime,omitempty"`
+	LeaseTransitions     int32  `json:"leaseTransitions,omitempty"`
+}
+
+// LeaseConfig configures a LeaseElector.
+type LeaseConfig struct {
+	// Namespace and Name locate the Lease. Every instance electing the same
+	// leader must use the same Lease.
+	Namespace string
+	Name      string
+
+	// Identity is this instance's holder identity. It defaults to the host
+	// name, which is the pod name on Kubernetes.
+	Identity string
+
+	// Duration is how long a leader keeps the Lease without renewing it
+	// (default: DefaultLeaseDuration).
+	Duration time.Duration
+}
+
+// LeaseElector elects one leader among the pods sharing a Lease, the way
+// Kubernetes controllers do. Run acquires the Lease when it is free or its
+// holder stopped renewing it, and renews it a few times per lease period.
+// Expiry is judged by when this instance last saw the Lease change rather
+// than by the holder's clock, so clock skew between nodes does not matter.
+type LeaseElector struct {
+	client    *Client
+	namespace string
+	name      string
+	identity  string
+	duration  time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	renewed  time.Time // when this instance last renewed the Lease it holds
+	leading  bool
+	observed LeaseSpec // the Lease as last seen
+	seenAt   time.Time // when observed last changed
+}
+
+// NewLeaseElector returns an elector using the given client.
+func NewLeaseElector(c *Client, config LeaseConfig) (*LeaseElector, error) {
+	if config.Name == "" {
+		return nil, fmt.Errorf("%w: no lease name", ErrAPI)
+	}
+	namespace := config.Namespace
+	if namespace == "" {
+		namespace = c.Namespace
+	}
+	if namespace == "" {
+		return nil, fmt.Errorf("%w: no lease namespace", ErrAPI)
+	}
+	identity := config.Identity
+	if identity == "" {
+		host, err := os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("kube: failed to determine lease identity: %w", err)
+		}
+		identity = host
+	}
+	duration := config.Duration
+	if duration == 0 {
+		duration = DefaultLeaseDuration
+	}
+	if duration < time.Second {
+		return nil, fmt.Errorf("%w: lease duration %v is under a second", ErrAPI, duration)
+	}
+	return &LeaseElector{
+		client:    c,
+		namespace: namespace,
+		name:      config.Name,
+		identity:  identity,
+		duration:  duration,
+		now:       time.Now,
+	}, nil
+}
+
+// path returns the API path of the Lease, or of its collection when name
+// is empty.
+func (e *LeaseElector) path(name string) string {
+	return Path("coordination.k8s.io", "v1", e.namespace, "leases", name)
+}
+
+// Identity returns this instance's holder identity.
+func (e *LeaseElector) Identity() string {
+	return e.identity
+}
+
+// IsLeader reports whether this instance holds the Lease and renewed it
+// within the lease duration.
+func (e *LeaseElector) IsLeader() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.leading && e.now().Before(e.renewed.Add(e.duration))
+}
+
+// Run campaigns for leadership until ctx is done, then resigns. It blocks;
+// run it in a goroutine.
+func (e *LeaseElector) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.duration / 3)
+	defer ticker.Stop()
+	for {
+		_ = e.Step(ctx)
+		select {
+		case <-ctx.Done():
+			e.Resign(context.Background())
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Step makes one election round: the leader renews the Lease and other
+// instances take it over when it is free or expired. It returns an error
+// only when the API server fails; losing the election is not an error.
+func (e *LeaseElector) Step(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var lease Lease
+	err := e.client.Get(ctx, e.path(e.name), &lease)
+	if errors.Is(err, ErrNotFound) {
+		return e.acquireLocked(ctx, nil)
+	}
+	if err != nil {
+		// Keep leading until the lease lapses; the next round retries.
+		return err
+	}
+
+	now := e.now()
+	if lease.Spec != e.observed {
+		e.observed, e.seenAt = lease.Spec, now
+	}
+	switch {
+	case lease.Spec.HolderIdentity == e.identity:
+		return e.acquireLocked(ctx, &lease)
+	case lease.Spec.HolderIdentity == "" || now.After(e.seenAt.Add(leaseDuration(lease.Spec, e.duration))):
+		return e.acquireLocked(ctx, &lease)
+	default:
+		e.leading = false
+		return nil
+	}
+}
+
+// leaseDuration is the duration the holder of spec declared, or fallback.
+func leaseDuration(spec LeaseSpec, fallback time.Duration) time.Duration {
+	if spec.LeaseDurationSeconds > 0 {
+		return time.Duration(spec.LeaseDurationSeconds) * time.Second
+	}
+	return fallback
+}
+
+// acquireLocked creates the Lease, or renews or takes over current. A
+// conflict means another instance changed it first.
+func (e *LeaseElector) acquireLocked(ctx context.Context, current *Lease) error {
+	now := e.now()
+	stamp := now.UTC().Format(microTime)
+	lease := Lease{
+		APIVersion: "coordination.k8s.io/v1",
+		Kind:       "Lease",
+		Spec: LeaseSpec{
+			HolderIdentity:       e.identity,
+			LeaseDurationSeconds: int32((e.duration + time.Second - 1) / time.Second),
+			AcquireTime:          stamp,
+			RenewTime:            stamp,
+		},
+	}
+
+	var err error
+	if current == nil {
+		lease.Metadata = ObjectMeta{Name: e.name, Namespace: e.namespace}
+		err = e.client.Create(ctx, e.path(""), lease, &lease)
+	} else {
+		lease.Metadata = current.Metadata
+		lease.Spec.LeaseTransitions = current.Spec.LeaseTransitions
+		if current.Spec.HolderIdentity == e.identity {
+			lease.Spec.AcquireTime = current.Spec.AcquireTime
+		} else {
+			lease.Spec.LeaseTransitions++
+		}
+		err = e.client.Update(ctx, e.path(e.name), lease, &lease)
+	}
+	if errors.Is(err, ErrConflict) {
+		e.leading = false
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	e.leading, e.renewed = true, now
+	e.observed, e.seenAt = lease.Spec, now
+	return nil
+}
+
+// Resign gives up leadership, clearing the holder so another instance can
+// take over without waiting for the Lease to expire.
+func (e *LeaseElector) Resign(ctx context.Context) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if !e.leading {
+		return
+	}
+	e.leading = false
+
+	var lease Lease
+	if err := e.client.Get(ctx, e.path(e.name), &lease); err != nil || lease.Spec.HolderIdentity != e.identity {
+		return
+	}
+	lease.Spec.HolderIdentity = ""
+	lease.Spec.RenewTime = e.now().UTC().Format(microTime)
+	_ = e.client.Update(ctx, e.path(e.name), lease, nil) // #nosec G104 -- an unreleased lease expires
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package kube
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeLeases is an API server holding Leases with optimistic concurrency.
+type fakeLeases struct {
+	mu     sync.Mutex
+	leases map[string]Lease
+}
+
+func (f *fakeLeases) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	collection := Path("coordination.k8s.io", "v1", "objstore", "leases", "")
+	var lease Lease
+	switch r.Method {
+	case http.MethodGet:
+		var ok bool
+		if lease, ok = f.leases[r.URL.Path]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+	case http.MethodPost:
+		_ = json.NewDecoder(r.Body).Decode(&lease)
+		path := collection + "/" + lease.Metadata.Name
+		if _, ok := f.leases[path]; ok || r.URL.Path != collection {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		lease.Metadata.ResourceVersion = "1"
+		f.leases[path] = lease
+	case http.MethodPut:
+		_ = json.NewDecoder(r.Body).Decode(&lease)
+		current, ok := f.leases[r.URL.Path]
+		if !ok || current.Metadata.ResourceVersion != lease.Metadata.ResourceVersion {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		version, _ := strconv.Atoi(current.Metadata.ResourceVersion)
+		lease.Metadata.ResourceVersion = strconv.Itoa(version + 1)
+		f.leases[r.URL.Path] = lease
+	}
+	_ = json.NewEncoder(w).Encode(lease)
+}
+
+func (f *fakeLeases) holder() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.leases["/apis/coordination.k8s.io/v1/namespaces/objstore/leases/jobs"].Spec.HolderIdentity
+}
+
+func TestLeaseElector(t *testing.T) {
+	api := &fakeLeases{leases: make(map[string]Lease)}
+	server := httptest.NewServer(api)
+	defer server.Close()
+	client, err := NewClient(&Config{Host: server.URL, Namespace: "objstore"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	newElector := func(identity string) *LeaseElector {
+		e, err := NewLeaseElector(client, LeaseConfig{Name: "jobs", Identity: identity, Duration: 15 * time.Second})
+		if err != nil {
+			t.Fatal(err)
+		}
+		e.now = clock
+		return e
+	}
+	a, b := newElector("pod-a"), newElector("pod-b")
+	ctx := context.Background()
+
+	// The first instance creates the Lease
+	if err := a.Step(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Step(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !a.IsLeader() || b.IsLeader() || api.holder() != "pod-a" {
+		t.Fatalf("a = %v, b = %v, holder = %q", a.IsLeader(), b.IsLeader(), api.holder())
+	}
+
+	// Renewals keep the lease; b sees it change and waits
+	for range 3 {
+		now = now.Add(5 * time.Second)
+		if err := a.Step(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if err := b.Step(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !a.IsLeader() || b.IsLeader() {
+		t.Fatalf("after renewals a = %v, b = %v", a.IsLeader(), b.IsLeader())
+	}
+
+	// a stops renewing: it stops leading when its lease lapses, and b takes
+	// over once it has seen the lease unchanged for the lease duration
+	now = now.Add(10 * time.Second)
+	if err := b.Step(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if b.IsLeader() {
+		t.Fatal("b took over a lease that had not expired")
+	}
+	now = now.Add(6 * time.Second)
+	if a.IsLeader() {
+		t.Error("a still leads after its lease expired")
+	}
+	if err := b.Step(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !b.IsLeader() || api.holder() != "pod-b" {
+		t.Fatalf("b = %v, holder = %q", b.IsLeader(), api.holder())
+	}
+
+	// a comes back and loses: the lease is held
+	if err := a.Step(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if a.IsLeader() {
+		t.Error("a leads alongside b")
+	}
+
+	// b resigns and a takes over at once
+	b.Resign(ctx)
+	if b.IsLeader() || api.holder() != "" {
+		t.Fatalf("after resigning b = %v, holder = %q", b.IsLeader(), api.holder())
+	}
+	if err := a.Step(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !a.IsLeader() {
+		t.Error("a did not take over a released lease")
+	}
+}
+
+func TestNewLeaseElector(t *testing.T) {
+	client, err := NewClient(&Config{Host: "http://127.0.0.1:1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewLeaseElector(client, LeaseConfig{Name: "jobs"}); err == nil {
+		t.Error("NewLeaseElector() without a namespace succeeded")
+	}
+	if _, err := NewLeaseElector(client, LeaseConfig{Namespace: "default"}); err == nil {
+		t.Error("NewLeaseElector() without a name succeeded")
+	}
+	e, err := NewLeaseElector(client, LeaseConfig{Namespace: "default", Name: "jobs", Identity: "pod-a"})
+	if err != nil || e.Identity() != "pod-a" || e.duration != DefaultLeaseDuration {
+		t.Errorf("NewLeaseElector() = %+v, %v", e, err)
+	}
+}
@@ -0,0 +1,473 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package lifecycle simulates lifecycle policies against an inventory of
+// objects, so retention periods can be chosen before automation deletes
+// anything.
+//
+// Simulate replays an inventory snapshot, such as one written by
+// `objstore list --all --output-file`, against a set of policies and
+// reports, per month, the objects and bytes that would have been deleted,
+// archived or transitioned, and would be in the months after the
+// snapshot. Policies are proposed in a YAML or JSON file read by LoadFile,
+// or taken from a backend's current policies.
+package lifecycle
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultHorizon is how far past the snapshot Simulate projects when
+// Options.Horizon is zero.
+const DefaultHorizon = 365 * 24 * time.Hour
+
+const (
+	// ActionDelete deletes objects after the retention period.
+	ActionDelete = "delete"
+
+	// ActionArchive archives objects after the retention period.
+	ActionArchive = "archive"
+)
+
+// monthFormat formats the months of a report.
+const monthFormat = "2006-01"
+
+var (
+	// ErrInvalidPolicy is returned when a proposed policy is malformed.
+	ErrInvalidPolicy = errors.New("invalid lifecycle policy")
+
+	// ErrInvalidInventory is returned when an inventory cannot be read.
+	ErrInvalidInventory = errors.New("invalid inventory")
+)
+
+// Rule is a proposed lifecycle policy as written in a policy file.
+type Rule struct {
+	ID     string `yaml:"id" json:"id"`
+	Prefix string `yaml:"prefix" json:"prefix"`
+
+	// RetentionDays is the age in days after which Action is taken.
+	RetentionDays int `yaml:"retention_days" json:"retention_days"`
+
+	// Action is "delete", "archive" or "transition".
+	Action string `yaml:"action" json:"action"`
+
+	// StorageClass is the class objects move to when Action is
+	// "transition".
+	StorageClass string `yaml:"storage_class,omitempty" json:"storage_class,omitempty"`
+}
+
+// File is a set of proposed lifecycle policies.
+type File struct {
+	Policies []Rule `yaml:"policies" json:"policies"`
+}
+
+// LoadFile reads proposed policies from a YAML or JSON file and returns
+// them as lifecycle policies, in file order. Archive policies have no
+// destination; they are only for simulation.
+func LoadFile(filename string) ([]common.LifecyclePolicy, error) {
+	data, err := os.ReadFile(filename) // #nosec G304 -- path supplied by the operator
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPolicy, err)
+	}
+	var file File
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidPolicy, filename, err)
+	}
+
+	policies := make([]common.LifecyclePolicy, 0, len(file.Policies))
+	seen := make(map[string]bool, len(file.Policies))
+	for _, rule := range file.Policies {
+		policy, err := rule.Policy()
+		if err != nil {
+			return nil, err
+		}
+		if seen[rule.ID] {
+			return nil, fmt.Errorf("%w: duplicate policy %q", ErrInvalidPolicy, rule.ID)
+		}
+		seen[rule.ID] = true
+		policies = append(policies, policy)
+	}
+	return policies, nil
+}
+
+// Policy returns the rule as a lifecycle policy, validated.
+func (r Rule) Policy() (common.LifecyclePolicy, error) {
+	policy := common.LifecyclePolicy{
+		ID:           r.ID,
+		Prefix:       r.Prefix,
+		Retention:    time.Duration(r.RetentionDays) * 24 * time.Hour,
+		Action:       r.Action,
+		StorageClass: r.StorageClass,
+	}
+	if err := Validate(policy); err != nil {
+		return common.LifecyclePolicy{}, err
+	}
+	return policy, nil
+}
+
+// Validate checks that a policy has an ID, a known action, a storage class
+// when it transitions, and a retention period that is not negative.
+func Validate(policy common.LifecyclePolicy) error {
+	if policy.ID == "" {
+		return fmt.Errorf("%w: policy without an id", ErrInvalidPolicy)
+	}
+	if policy.Retention < 0 {
+		return fmt.Errorf("%w: policy %q: negative retention", ErrInvalidPolicy, policy.ID)
+	}
+	switch policy.Action {
+	case ActionDelete, ActionArchive:
+	case common.ActionTransition:
+		if policy.StorageClass == "" {
+			return fmt.Errorf("%w: policy %q: transition without a storage class", ErrInvalidPolicy, policy.ID)
+		}
+	default:
+		return fmt.Errorf("%w: policy %q: unknown action %q", ErrInvalidPolicy, policy.ID, policy.Action)
+	}
+	return nil
+}
+
+// Options control Simulate.
+type Options struct {
+	// SnapshotTime is when the inventory was taken. Actions due before it
+	// are reported as history and later ones as projections. If zero, the
+	// latest modification time in the inventory is used.
+	SnapshotTime time.Time
+
+	// Horizon is how far past SnapshotTime actions are projected.
+	Horizon time.Duration
+}
+
+// Tally is a number of objects and their total size.
+type Tally struct {
+	Objects int64 `json:"objects"`
+	Bytes   int64 `json:"bytes"`
+}
+
+func (t *Tally) add(size int64) {
+	t.Objects++
+	t.Bytes += size
+}
+
+// Month is what the policies do in a calendar month (UTC).
+type Month struct {
+	// Month is formatted as "2006-01".
+	Month string `json:"month"`
+
+	// Projected is set for months that end after the snapshot.
+	Projected bool `json:"projected"`
+
+	Deleted      Tally `json:"deleted"`
+	Archived     Tally `json:"archived"`
+	Transitioned Tally `json:"transitioned"`
+}
+
+// PolicyResult is what a policy does over the whole simulation.
+type PolicyResult struct {
+	ID     string `json:"id"`
+	Prefix string `json:"prefix"`
+	Action string `json:"action"`
+
+	// RetentionDays is the policy's retention period in days.
+	RetentionDays float64 `json:"retention_days"`
+
+	// Matched counts the objects under the policy's prefix.
+	Matched Tally `json:"matched"`
+
+	// Applied counts the objects the policy acts on within the horizon.
+	Applied Tally `json:"applied"`
+}
+
+// Report is the result of a simulation.
+type Report struct {
+	SnapshotTime time.Time `json:"snapshot_time"`
+	Until        time.Time `json:"until"`
+
+	// Inventory counts the objects in the inventory.
+	Inventory Tally `json:"inventory"`
+
+	// Retained counts the objects neither deleted nor archived by Until.
+	Retained Tally `json:"retained"`
+
+	// Months are the months with actions, oldest first.
+	Months []Month `json:"months"`
+
+	// Policies are the policies simulated, in order.
+	Policies []PolicyResult `json:"policies"`
+}
+
+// Object is an entry of an inventory.
+type Object struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+	StorageClass string
+}
+
+// Simulate reads an inventory from r and replays policies against it as
+// lifecycle runs would: an object is acted on once it is older than a
+// matching policy's retention, a transition leaves it in place for later
+// policies, and a delete or archive removes it. The inventory is JSON
+// Lines or CSV, as written by `objstore list --output-file`, with the
+// fields key, size, modified and, optionally, storage_class. It is read
+// once; when opts.SnapshotTime is set, objects are not kept in memory.
+func Simulate(r io.Reader, policies []common.LifecyclePolicy, opts *Options) (*Report, error) {
+	for _, policy := range policies {
+		if err := Validate(policy); err != nil {
+			return nil, err
+		}
+	}
+	if opts == nil {
+		opts = &Options{}
+	}
+	horizon := opts.Horizon
+	if horizon == 0 {
+		horizon = DefaultHorizon
+	}
+
+	report := &Report{Months: []Month{}, Policies: make([]PolicyResult, len(policies))}
+	for i, policy := range policies {
+		report.Policies[i] = PolicyResult{
+			ID:            policy.ID,
+			Prefix:        policy.Prefix,
+			Action:        policy.Action,
+			RetentionDays: policy.Retention.Hours() / 24,
+		}
+	}
+
+	// Without a snapshot time, actions are only known once the whole
+	// inventory has been read: keep them until then.
+	type pending struct {
+		due    time.Time
+		policy int
+		size   int64
+	}
+	var events []pending
+	months := make(map[string]*Month)
+	snapshot := opts.SnapshotTime
+	record := func(due time.Time, policy int, size int64) {
+		if !snapshot.IsZero() && due.After(snapshot.Add(horizon)) {
+			return
+		}
+		name := due.UTC().Format(monthFormat)
+		month, ok := months[name]
+		if !ok {
+			month = &Month{Month: name}
+			months[name] = month
+		}
+		switch policies[policy].Action {
+		case ActionDelete:
+			month.Deleted.add(size)
+		case ActionArchive:
+			month.Archived.add(size)
+		default:
+			month.Transitioned.add(size)
+		}
+		report.Policies[policy].Applied.add(size)
+	}
+
+	var latest time.Time
+	err := readInventory(r, func(obj Object) error {
+		report.Inventory.add(obj.Size)
+		if obj.LastModified.After(latest) {
+			latest = obj.LastModified
+		}
+		for _, action := range plan(policies, obj, report.Policies) {
+			if snapshot.IsZero() {
+				events = append(events, pending{due: action.due, policy: action.policy, size: obj.Size})
+				continue
+			}
+			record(action.due, action.policy, obj.Size)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if snapshot.IsZero() {
+		snapshot = latest
+		for _, e := range events {
+			record(e.due, e.policy, e.size)
+		}
+	}
+	report.SnapshotTime = snapshot.UTC()
+	report.Until = snapshot.Add(horizon).UTC()
+
+	var removed Tally
+	for _, month := range months {
+		end, _ := time.Parse(monthFormat, month.Month)
+		month.Projected = end.AddDate(0, 1, 0).After(snapshot)
+		removed.Objects += month.Deleted.Objects + month.Archived.Objects
+		removed.Bytes += month.Deleted.Bytes + month.Archived.Bytes
+		report.Months = append(report.Months, *month)
+	}
+	slices.SortFunc(report.Months, func(a, b Month) int {
+		return strings.Compare(a.Month, b.Month)
+	})
+	report.Retained = Tally{
+		Objects: report.Inventory.Objects - removed.Objects,
+		Bytes:   report.Inventory.Bytes - removed.Bytes,
+	}
+	return report, nil
+}
+
+// action is a policy acting on an object at a time.
+type action struct {
+	due    time.Time
+	policy int
+}
+
+// plan returns the actions policies take on obj, in the order they fall
+// due, counting obj as matched by each policy whose prefix it is under. It
+// ends with the first delete or archive, after which the object is gone.
+func plan(policies []common.LifecyclePolicy, obj Object, results []PolicyResult) []action {
+	var matched []action
+	for i, policy := range policies {
+		if !strings.HasPrefix(obj.Key, policy.Prefix) {
+			continue
+		}
+		results[i].Matched.add(obj.Size)
+		matched = append(matched, action{due: obj.LastModified.Add(policy.Retention), policy: i})
+	}
+	// Lifecycle runs apply the first due policy in order, so of policies
+	// due at the same time the earlier one wins.
+	slices.SortStableFunc(matched, func(a, b action) int {
+		return a.due.Compare(b.due)
+	})
+
+	var actions []action
+	class := obj.StorageClass
+	for _, a := range matched {
+		policy := policies[a.policy]
+		if policy.Action == common.ActionTransition {
+			if class == policy.StorageClass {
+				continue
+			}
+			class = policy.StorageClass
+			actions = append(actions, a)
+			continue
+		}
+		return append(actions, a)
+	}
+	return actions
+}
+
+// readInventory calls fn for each object of a JSON Lines or CSV inventory.
+// The format is detected from the first byte: "{" starts JSON Lines.
+func readInventory(r io.Reader, fn func(Object) error) error {
+	buffered := bufio.NewReader(r)
+	first, err := buffered.Peek(1)
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidInventory, err)
+	}
+	if bytes.Equal(first, []byte("{")) {
+		return readJSONL(buffered, fn)
+	}
+	return readCSV(buffered, fn)
+}
+
+func readJSONL(r io.Reader, fn func(Object) error) error {
+	decoder := json.NewDecoder(r)
+	for line := 1; ; line++ {
+		var record struct {
+			Key          string `json:"key"`
+			Size         int64  `json:"size"`
+			Modified     string `json:"modified"`
+			StorageClass string `json:"storage_class"`
+		}
+		if err := decoder.Decode(&record); errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("%w: record %d: %w", ErrInvalidInventory, line, err)
+		}
+		obj, err := newObject(record.Key, record.Modified, record.StorageClass)
+		if err != nil {
+			return fmt.Errorf("%w: record %d: %w", ErrInvalidInventory, line, err)
+		}
+		obj.Size = record.Size
+		if err := fn(obj); err != nil {
+			return err
+		}
+	}
+}
+
+func readCSV(r io.Reader, fn func(Object) error) error {
+	records := csv.NewReader(r)
+	records.ReuseRecord = true
+	header, err := records.Read()
+	if err != nil {
+		return fmt.Errorf("%w: header: %w", ErrInvalidInventory, err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for _, required := range []string{"key", "size", "modified"} {
+		if _, ok := columns[required]; !ok {
+			return fmt.Errorf("%w: missing column %q", ErrInvalidInventory, required)
+		}
+	}
+	field := func(row []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(row) {
+			return row[i]
+		}
+		return ""
+	}
+
+	for line := 2; ; line++ {
+		row, err := records.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidInventory, err)
+		}
+		obj, err := newObject(field(row, "key"), field(row, "modified"), field(row, "storage_class"))
+		if err != nil {
+			return fmt.Errorf("%w: line %d: %w", ErrInvalidInventory, line, err)
+		}
+		if obj.Size, err = strconv.ParseInt(field(row, "size"), 10, 64); err != nil {
+			return fmt.Errorf("%w: line %d: invalid size", ErrInvalidInventory, line)
+		}
+		if err := fn(obj); err != nil {
+			return err
+		}
+	}
+}
+
+func newObject(key, modified, storageClass string) (Object, error) {
+	if key == "" {
+		return Object{}, errors.New("missing key")
+	}
+	lastModified, err := time.Parse(time.RFC3339Nano, modified)
+	if err != nil {
+		return Object{}, fmt.Errorf("invalid modified time %q", modified)
+	}
+	return Object{Key: key, LastModified: lastModified, StorageClass: storageClass}, nil
+}
@@ -0,0 +1,166 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package lifecycle
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+const day = 24 * time.Hour
+
+const inventoryJSONL = `{"key":"logs/a.log","size":100,"modified":"2025-01-10T00:00:00Z"}
+{"key":"logs/b.log","size":200,"modified":"2025-03-10T00:00:00Z","storage_class":"GLACIER"}
+{"key":"images/cat.png","size":50,"modified":"2025-02-01T00:00:00Z"}
+`
+
+const inventoryCSV = `key,size,modified,storage_class,content_type
+logs/a.log,100,2025-01-10T00:00:00Z,,text/plain
+logs/b.log,200,2025-03-10T00:00:00Z,GLACIER,text/plain
+images/cat.png,50,2025-02-01T00:00:00Z,,image/png
+`
+
+func testPolicies() []common.LifecyclePolicy {
+	return []common.LifecyclePolicy{
+		{ID: "cold-logs", Prefix: "logs/", Retention: 30 * day, Action: common.ActionTransition, StorageClass: "GLACIER"},
+		{ID: "expire-logs", Prefix: "logs/", Retention: 90 * day, Action: ActionDelete},
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	for name, inventory := range map[string]string{"jsonl": inventoryJSONL, "csv": inventoryCSV} {
+		t.Run(name, func(t *testing.T) {
+			snapshot := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+			report, err := Simulate(strings.NewReader(inventory), testPolicies(), &Options{SnapshotTime: snapshot, Horizon: 60 * day})
+			if err != nil {
+				t.Fatalf("Simulate() error = %v", err)
+			}
+			if report.Inventory != (Tally{Objects: 3, Bytes: 350}) {
+				t.Errorf("Inventory = %+v", report.Inventory)
+			}
+			// a.log moves to GLACIER in February and is deleted in April;
+			// b.log is already in GLACIER and is deleted in June.
+			want := map[string]Month{
+				"2025-02": {Month: "2025-02", Transitioned: Tally{1, 100}},
+				"2025-04": {Month: "2025-04", Deleted: Tally{1, 100}},
+				"2025-06": {Month: "2025-06", Projected: true, Deleted: Tally{1, 200}},
+			}
+			if len(report.Months) != len(want) {
+				t.Fatalf("Months = %+v", report.Months)
+			}
+			for _, month := range report.Months {
+				if month != want[month.Month] {
+					t.Errorf("month %s = %+v, want %+v", month.Month, month, want[month.Month])
+				}
+			}
+			if report.Retained != (Tally{Objects: 1, Bytes: 50}) {
+				t.Errorf("Retained = %+v, want the image only", report.Retained)
+			}
+			if p := report.Policies[0]; p.Matched.Objects != 2 || p.Applied.Objects != 1 || p.RetentionDays != 30 {
+				t.Errorf("policy %+v, want 2 matched and 1 applied", p)
+			}
+			if p := report.Policies[1]; p.Applied != (Tally{2, 300}) {
+				t.Errorf("policy %+v, want both logs deleted", p)
+			}
+		})
+	}
+}
+
+func TestSimulateHorizonAndSnapshot(t *testing.T) {
+	// Without a snapshot time, the newest object (2025-03-10) is used, and
+	// b.log's deletion in June falls outside a 45 day horizon.
+	report, err := Simulate(strings.NewReader(inventoryJSONL), testPolicies(), &Options{Horizon: 45 * day})
+	if err != nil {
+		t.Fatalf("Simulate() error = %v", err)
+	}
+	if !report.SnapshotTime.Equal(time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("SnapshotTime = %v", report.SnapshotTime)
+	}
+	if report.Retained != (Tally{Objects: 2, Bytes: 250}) {
+		t.Errorf("Retained = %+v, want b.log and the image", report.Retained)
+	}
+	last := report.Months[len(report.Months)-1]
+	if last.Month != "2025-04" || !last.Projected {
+		t.Errorf("last month = %+v, want a projected April", last)
+	}
+}
+
+func TestSimulateErrors(t *testing.T) {
+	if _, err := Simulate(strings.NewReader(inventoryJSONL), []common.LifecyclePolicy{{ID: "x", Action: "shred"}}, nil); !errors.Is(err, ErrInvalidPolicy) {
+		t.Errorf("unknown action error = %v, want ErrInvalidPolicy", err)
+	}
+	for _, inventory := range []string{
+		`{"key":"a","size":1,"modified":"yesterday"}`,
+		`{"key":"a"`,
+		"key,size\na,1\n",
+		"key,size,modified\na,big,2025-01-10T00:00:00Z\n",
+	} {
+		if _, err := Simulate(strings.NewReader(inventory), nil, nil); !errors.Is(err, ErrInvalidInventory) {
+			t.Errorf("Simulate(%q) error = %v, want ErrInvalidInventory", inventory, err)
+		}
+	}
+	report, err := Simulate(strings.NewReader(""), testPolicies(), nil)
+	if err != nil || report.Inventory.Objects != 0 {
+		t.Errorf("empty inventory = %+v, %v", report, err)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "policies.yaml")
+	data := `policies:
+  - id: cold-logs
+    prefix: logs/
+    retention_days: 30
+    action: transition
+    storage_class: GLACIER
+  - id: expire-logs
+    prefix: logs/
+    retention_days: 90
+    action: delete
+`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	policies, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if len(policies) != 2 || policies[0].Retention != 30*day || policies[0].StorageClass != "GLACIER" || policies[1].Action != ActionDelete {
+		t.Errorf("LoadFile() = %+v", policies)
+	}
+
+	for _, bad := range []string{
+		"policies:\n  - id: a\n    action: transition\n",
+		"policies:\n  - id: a\n    action: delete\n  - id: a\n    action: delete\n",
+		"policies:\n  - action: delete\n",
+		"policies: [",
+	} {
+		if err := os.WriteFile(path, []byte(bad), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadFile(path); !errors.Is(err, ErrInvalidPolicy) {
+			t.Errorf("LoadFile(%q) error = %v, want ErrInvalidPolicy", bad, err)
+		}
+	}
+	if _, err := LoadFile(filepath.Join(dir, "missing.yaml")); !errors.Is(err, ErrInvalidPolicy) {
+		t.Errorf("LoadFile() of a missing file error = %v", err)
+	}
+}
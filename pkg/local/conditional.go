@@ -0,0 +1,86 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package local
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// PutIfMatch stores an object only if its current ETag is etag, or only if
+// it does not exist when etag is empty. Conditional writes are serialized
+// within this process only; processes sharing a directory must not rely on
+// them.
+func (l *Local) PutIfMatch(ctx context.Context, key string, data io.Reader, metadata *common.Metadata, etag string) error {
+	if err := l.validateKey(key); err != nil {
+		return err
+	}
+
+	l.conditionalMu.Lock()
+	defer l.conditionalMu.Unlock()
+
+	if err := l.checkETag(key, etag); err != nil {
+		return err
+	}
+	return l.PutWithMetadata(ctx, key, data, metadata)
+}
+
+// DeleteIfMatch removes an object only if its current ETag is etag.
+func (l *Local) DeleteIfMatch(ctx context.Context, key, etag string) error {
+	if err := l.validateKey(key); err != nil {
+		return err
+	}
+
+	l.conditionalMu.Lock()
+	defer l.conditionalMu.Unlock()
+
+	if _, err := os.Stat(l.objectPath(key)); os.IsNotExist(err) {
+		return fmt.Errorf("%w: %s", common.ErrKeyNotFound, key)
+	}
+	if err := l.checkETag(key, etag); err != nil {
+		return err
+	}
+	return l.DeleteWithContext(ctx, key)
+}
+
+// ProcessLocal reports true: other processes sharing the directory do not
+// see the lock that serializes conditional writes.
+func (l *Local) ProcessLocal() bool {
+	return true
+}
+
+// checkETag checks the precondition of a conditional write. An empty etag
+// requires the object not to exist.
+func (l *Local) checkETag(key, etag string) error {
+	if etag == "" {
+		_, err := os.Stat(l.objectPath(key))
+		switch {
+		case err == nil:
+			return fmt.Errorf("%w: %s already exists", common.ErrPreconditionFailed, key)
+		case !os.IsNotExist(err):
+			return translateError(err, key)
+		}
+		return nil
+	}
+
+	metadata, err := l.loadMetadata(key)
+	if err != nil || metadata.ETag != etag {
+		return fmt.Errorf("%w: %s does not match ETag %s", common.ErrPreconditionFailed, key, etag)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package local_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/local"
+)
+
+func TestConditionalWrites(t *testing.T) {
+	storage := local.New()
+	if err := storage.Configure(map[string]string{"path": t.TempDir()}); err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+	writer := storage.(common.ConditionalWriter)
+	ctx := context.Background()
+
+	if err := writer.PutIfMatch(ctx, "dir/k", strings.NewReader("v1"), nil, ""); err != nil {
+		t.Fatalf("create-if-absent: %v", err)
+	}
+	if err := writer.PutIfMatch(ctx, "dir/k", strings.NewReader("v2"), nil, ""); !errors.Is(err, common.ErrPreconditionFailed) {
+		t.Errorf("expected ErrPreconditionFailed for existing key, got %v", err)
+	}
+
+	metadata, err := storage.GetMetadata(ctx, "dir/k")
+	if err != nil {
+		t.Fatalf("GetMetadata: %v", err)
+	}
+	if err := writer.PutIfMatch(ctx, "dir/k", strings.NewReader("v2"), nil, "stale"); !errors.Is(err, common.ErrPreconditionFailed) {
+		t.Errorf("expected ErrPreconditionFailed for stale ETag, got %v", err)
+	}
+	if err := writer.PutIfMatch(ctx, "dir/k", strings.NewReader("v2"), nil, metadata.ETag); err != nil {
+		t.Fatalf("PutIfMatch: %v", err)
+	}
+
+	metadata, err = storage.GetMetadata(ctx, "dir/k")
+	if err != nil {
+		t.Fatalf("GetMetadata: %v", err)
+	}
+	if err := writer.DeleteIfMatch(ctx, "dir/k", "stale"); !errors.Is(err, common.ErrPreconditionFailed) {
+		t.Errorf("expected ErrPreconditionFailed for stale ETag, got %v", err)
+	}
+	if err := writer.DeleteIfMatch(ctx, "dir/k", metadata.ETag); err != nil {
+		t.Fatalf("DeleteIfMatch: %v", err)
+	}
+	if err := writer.DeleteIfMatch(ctx, "dir/k", metadata.ETag); !errors.Is(err, common.ErrKeyNotFound) {
+		t.Errorf("expected ErrKeyNotFound, got %v", err)
+	}
+}
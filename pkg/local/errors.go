@@ -0,0 +1,40 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package local
+
+import (
+	"errors"
+	"io/fs"
+	"syscall"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// translateError maps a filesystem error for key to the common error
+// taxonomy. Errors without a filesystem meaning are returned unchanged.
+func translateError(err error, key string) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, fs.ErrNotExist):
+		return common.ProviderError(common.ErrNotFound, key, err)
+	case errors.Is(err, fs.ErrPermission):
+		return common.ProviderError(common.ErrAccessDenied, key, err)
+	case errors.Is(err, fs.ErrExist):
+		return common.ProviderError(common.ErrAlreadyExists, key, err)
+	case errors.Is(err, syscall.ENOSPC), errors.Is(err, syscall.EDQUOT):
+		return common.ProviderError(common.ErrQuotaExceeded, key, err)
+	}
+	return err
+}
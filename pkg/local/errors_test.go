@@ -0,0 +1,60 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package local
+
+import (
+	"errors"
+	"io/fs"
+	"syscall"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+func TestTranslateError(t *testing.T) {
+	pathErr := func(errno syscall.Errno) error {
+		return &fs.PathError{Op: "open", Path: "/data/a/key", Err: errno}
+	}
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"not exist", pathErr(syscall.ENOENT), common.ErrKeyNotFound},
+		{"permission", pathErr(syscall.EACCES), common.ErrAccessDenied},
+		{"exists", pathErr(syscall.EEXIST), common.ErrAlreadyExists},
+		{"disk full", pathErr(syscall.ENOSPC), common.ErrQuotaExceeded},
+		{"disk quota", pathErr(syscall.EDQUOT), common.ErrQuotaExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := translateError(tt.err, "a/key")
+			if !errors.Is(got, tt.want) {
+				t.Errorf("translateError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+			var pe *fs.PathError
+			if !errors.As(got, &pe) {
+				t.Errorf("translateError(%v) dropped the path error from the chain", tt.err)
+			}
+		})
+	}
+
+	plain := errors.New("not a filesystem error")
+	if got := translateError(plain, "k"); got != plain {
+		t.Errorf("translateError(plain) = %v, want unchanged", got)
+	}
+	if got := translateError(nil, "k"); got != nil {
+		t.Errorf("translateError(nil) = %v, want nil", got)
+	}
+}
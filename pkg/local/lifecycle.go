@@ -25,8 +25,9 @@ import (
 )
 
 const (
-	actionDelete  = "delete"
-	actionArchive = "archive"
+	actionDelete     = "delete"
+	actionArchive    = "archive"
+	actionTransition = common.ActionTransition
 )
 
 // LifecycleManager is an in-memory lifecycle manager for the local storage backend.
@@ -92,6 +93,10 @@ func (lm *LifecycleManager) Process(storage *Local) {
 	// GetPolicies acquires RLock internally and returns a copy; no outer lock needed.
 	policies, _ := lm.GetPolicies()
 
+	// Objects past their recorded expiry are deleted whatever the policies
+	// say. Failures are retried on the next pass.
+	_, _ = common.DeleteExpired(context.Background(), storage, time.Now())
+
 	for _, policy := range policies {
 		walkFn := func(path string, info os.FileInfo, err error) error {
 			if err != nil {
@@ -115,6 +120,8 @@ func (lm *LifecycleManager) Process(storage *Local) {
 						if policy.Destination != nil {
 							_ = storage.Archive(relPath, policy.Destination)
 						}
+					case actionTransition:
+						_ = storage.setStorageClass(relPath, policy.StorageClass)
 					}
 				}
 			}
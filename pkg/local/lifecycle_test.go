@@ -15,6 +15,7 @@ package local
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"os"
 	"path/filepath"
@@ -105,3 +106,79 @@ func TestLifecycle_Process_Archive(t *testing.T) {
 		t.Fatalf("expected archived key %s, got %v", key, ma.keys)
 	}
 }
+
+func TestLifecycle_Process_Transition(t *testing.T) {
+	dir := t.TempDir()
+	s := New()
+	if err := s.Configure(map[string]string{"path": dir}); err != nil {
+		t.Fatal(err)
+	}
+	ll := s.(*Local)
+
+	memManager, ok := ll.lifecycleManager.(*LifecycleManager)
+	if !ok {
+		t.Fatal("expected in-memory lifecycle manager")
+	}
+
+	key := "reports/old.txt"
+	if err := s.Put(key, bytes.NewBufferString("data")); err != nil {
+		t.Fatal(err)
+	}
+	pth := filepath.Join(dir, key)
+	past := time.Now().Add(-72 * time.Hour)
+	if err := os.Chtimes(pth, past, past); err != nil {
+		t.Fatal(err)
+	}
+
+	policy := common.LifecyclePolicy{ID: "p3", Prefix: "reports/", Retention: time.Hour, Action: common.ActionTransition, StorageClass: "COLD"}
+	if err := s.AddPolicy(policy); err != nil {
+		t.Fatal(err)
+	}
+
+	memManager.Process(ll)
+
+	metadata, err := ll.loadMetadata(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metadata.StorageClass != "COLD" {
+		t.Fatalf("expected storage class COLD, got %q", metadata.StorageClass)
+	}
+	if _, err := os.Stat(pth); err != nil {
+		t.Fatalf("expected object to remain after transition, got err=%v", err)
+	}
+}
+
+func TestLifecycle_Process_Expiry(t *testing.T) {
+	dir := t.TempDir()
+	s := New()
+	if err := s.Configure(map[string]string{"path": dir}); err != nil {
+		t.Fatal(err)
+	}
+	ll := s.(*Local)
+	memManager, ok := ll.lifecycleManager.(*LifecycleManager)
+	if !ok {
+		t.Fatal("expected in-memory lifecycle manager")
+	}
+
+	ctx := context.Background()
+	expires := func(t time.Time) *common.Metadata {
+		return &common.Metadata{Custom: map[string]string{common.MetaExpiresAt: t.Format(time.RFC3339)}}
+	}
+	if err := s.PutWithMetadata(ctx, "tmp/expired.txt", bytes.NewBufferString("data"), expires(time.Now().Add(-time.Minute))); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.PutWithMetadata(ctx, "tmp/pending.txt", bytes.NewBufferString("data"), expires(time.Now().Add(time.Hour))); err != nil {
+		t.Fatal(err)
+	}
+
+	// No policies apply: the expiry alone decides.
+	memManager.Process(ll)
+
+	if _, err := os.Stat(filepath.Join(dir, "tmp/expired.txt")); !os.IsNotExist(err) {
+		t.Errorf("expected expired file deleted, got err=%v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "tmp/pending.txt")); err != nil {
+		t.Errorf("expected unexpired file kept, got err=%v", err)
+	}
+}
@@ -19,14 +19,17 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"maps"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/jeremyhahn/go-objstore/pkg/adapters"
 	"github.com/jeremyhahn/go-objstore/pkg/audit"
 	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/delta"
 )
 
 const metadataSuffix = ".metadata.json"
@@ -41,6 +44,8 @@ type Local struct {
 	logger                 adapters.Logger
 	auditLog               audit.AuditLogger
 	lifecycleCancel        context.CancelFunc // stops the background lifecycle goroutine
+	conditionalMu          sync.Mutex         // serializes PutIfMatch and DeleteIfMatch
+	caseInsensitive        bool               // path ignores the case of file names
 }
 
 // New creates a new Local storage backend.
@@ -66,11 +71,24 @@ func (l *Local) Configure(settings map[string]string) error {
 		return common.ErrPathNotSet
 	}
 
+	// An absolute path lets the os package lift the Windows MAX_PATH limit
+	// for deeply nested keys
+	path, err := filepath.Abs(l.path)
+	if err != nil {
+		return err
+	}
+	l.path = path
+
 	// Ensure directory exists
 	if err := os.MkdirAll(l.path, 0750); err != nil {
 		return err
 	}
 
+	l.caseInsensitive = caseInsensitive(l.path)
+	if l.caseInsensitive {
+		log.Printf("[LOCAL] ⚠ %s is on a case-insensitive file system: keys that differ only in case, such as a.txt and A.txt, are stored as the same object", l.path)
+	}
+
 	// Initialize logger and audit log with no-op defaults if not set
 	if l.logger == nil {
 		l.logger = adapters.NewNoOpLogger()
@@ -178,25 +196,50 @@ func (l *Local) PutWithContext(ctx context.Context, key string, data io.Reader)
 }
 
 // validateKey checks if a key is safe to use (no path traversal attacks)
+// and, on Windows, that it can be stored as a file under its own name.
 func (l *Local) validateKey(key string) error {
-	return common.ValidateKey(key)
+	if err := common.ValidateKey(key); err != nil {
+		return err
+	}
+	if windowsPaths {
+		return validateWindowsKey(key)
+	}
+	return nil
 }
 
 // PutWithMetadata stores an object with associated metadata.
 func (l *Local) PutWithMetadata(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) error {
+	_, err := l.put(ctx, key, data, metadata)
+	return err
+}
+
+// PutWithInfo stores an object with associated metadata and returns the
+// metadata recorded for it.
+func (l *Local) PutWithInfo(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) (*common.ObjectInfo, error) {
+	stored, err := l.put(ctx, key, data, metadata)
+	if err != nil {
+		return nil, err
+	}
+	info := *stored
+	info.Custom = maps.Clone(stored.Custom)
+	return &common.ObjectInfo{Key: key, Metadata: &info}, nil
+}
+
+// put stores an object and returns the metadata saved with it.
+func (l *Local) put(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) (*common.Metadata, error) {
 	if err := l.validateKey(key); err != nil {
-		return err
+		return nil, err
 	}
 
 	select {
 	case <-ctx.Done():
-		return ctx.Err()
+		return nil, ctx.Err()
 	default:
 	}
 
-	path := filepath.Join(l.path, key)
+	path := l.objectPath(key)
 	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil { // Restrict permissions for security
-		return err
+		return nil, translateError(err, key)
 	}
 
 	// Get at-rest encrypter if factory is set
@@ -205,7 +248,7 @@ func (l *Local) PutWithMetadata(ctx context.Context, key string, data io.Reader,
 		var err error
 		encrypter, err = l.atRestEncrypterFactory.GetEncrypter("")
 		if err != nil {
-			return fmt.Errorf("failed to get encrypter: %w", err)
+			return nil, fmt.Errorf("failed to get encrypter: %w", err)
 		}
 	}
 
@@ -214,7 +257,7 @@ func (l *Local) PutWithMetadata(ctx context.Context, key string, data io.Reader,
 	if encrypter != nil {
 		encryptedData, err := encrypter.Encrypt(ctx, data)
 		if err != nil {
-			return fmt.Errorf("encryption failed: %w", err)
+			return nil, fmt.Errorf("encryption failed: %w", err)
 		}
 		defer func() { _ = encryptedData.Close() }()
 		dataToWrite = encryptedData
@@ -227,7 +270,7 @@ func (l *Local) PutWithMetadata(ctx context.Context, key string, data io.Reader,
 		return werr
 	}); err != nil {
 		log.Printf("[LOCAL] ✗ Failed to write object '%s': %v", key, err)
-		return err
+		return nil, translateError(err, key)
 	}
 
 	// Create or update metadata
@@ -255,7 +298,7 @@ func (l *Local) PutWithMetadata(ctx context.Context, key string, data io.Reader,
 
 	if err := l.saveMetadata(key, metadata); err != nil {
 		log.Printf("[LOCAL] ✗ Failed to save metadata for '%s': %v", key, err)
-		return err
+		return nil, err
 	}
 
 	// Log successful operation with details
@@ -282,7 +325,7 @@ func (l *Local) PutWithMetadata(ctx context.Context, key string, data io.Reader,
 		})
 	}
 
-	return nil
+	return metadata, nil
 }
 
 // Get retrieves an object from the backend.
@@ -290,6 +333,141 @@ func (l *Local) Get(key string) (io.ReadCloser, error) {
 	return l.GetWithContext(context.Background(), key)
 }
 
+// GetRange retrieves length bytes of an object starting at offset. A negative
+// length reads to the end of the object. Encrypted objects are decrypted from
+// the start and the leading bytes discarded.
+func (l *Local) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	if offset < 0 {
+		return nil, fmt.Errorf("%w: negative range offset %d", common.ErrInvalidArgument, offset)
+	}
+	if l.atRestEncrypterFactory != nil {
+		rc, err := l.GetWithContext(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		return common.SliceRange(rc, offset, length)
+	}
+
+	if err := l.validateKey(key); err != nil {
+		return nil, err
+	}
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	file, err := os.Open(l.objectPath(key)) // #nosec G304 -- Path validated by validateKey() to prevent directory traversal
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", common.ErrKeyNotFound, key)
+		}
+		return nil, translateError(err, key)
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return common.SliceRange(file, 0, length)
+}
+
+// ApplyDelta rebuilds the object stored under key from its current version
+// and ops, reading only the literal ranges from src. The rebuilt object is
+// written atomically like any other Put. Objects encrypted at rest cannot be
+// read by offset and report delta.ErrNotSupported.
+func (l *Local) ApplyDelta(ctx context.Context, key, baseETag string, ops []delta.Op, src delta.Source, metadata *common.Metadata) error {
+	if l.atRestEncrypterFactory != nil {
+		return delta.ErrNotSupported
+	}
+	if err := l.validateKey(key); err != nil {
+		return err
+	}
+
+	base, err := os.Open(l.objectPath(key)) // #nosec G304 -- Path validated by validateKey() to prevent directory traversal
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("%w: %s", common.ErrKeyNotFound, key)
+		}
+		return translateError(err, key)
+	}
+	defer func() { _ = base.Close() }()
+
+	if baseETag != "" {
+		if current, err := l.loadMetadata(key); err == nil && current.ETag != baseETag {
+			return fmt.Errorf("%w: %s", delta.ErrBaseChanged, key)
+		}
+	}
+
+	// The open descriptor keeps reading the old version after the atomic
+	// rename replaces the file.
+	rc := delta.NewReader(ctx, base, ops, src)
+	defer func() { _ = rc.Close() }()
+	return l.PutWithMetadata(ctx, key, rc, metadata)
+}
+
+// Append adds data to the end of the object stored under key, creating it if
+// it does not exist. Unencrypted objects are appended to in place; objects
+// encrypted at rest are rewritten with common.EmulateAppend.
+func (l *Local) Append(ctx context.Context, key string, data io.Reader) error {
+	if l.atRestEncrypterFactory != nil {
+		return common.EmulateAppend(ctx, l, key, data)
+	}
+	if err := l.validateKey(key); err != nil {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	path := l.objectPath(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil { // Restrict permissions for security
+		return translateError(err, key)
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644) // #nosec G304 -- Path validated by validateKey() to prevent directory traversal
+	if err != nil {
+		return translateError(err, key)
+	}
+	n, err := io.Copy(file, data)
+	if err != nil {
+		_ = file.Close()
+		return translateError(err, key)
+	}
+	if err := file.Close(); err != nil {
+		return translateError(err, key)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	metadata, err := l.loadMetadata(key)
+	if err != nil {
+		metadata = &common.Metadata{}
+	}
+	metadata.Size = info.Size()
+	metadata.LastModified = time.Now()
+	metadata.ETag = fmt.Sprintf("%d-%d", info.ModTime().Unix(), info.Size())
+	if err := l.saveMetadata(key, metadata); err != nil {
+		return err
+	}
+
+	log.Printf("[LOCAL] ✓ APPEND '%s' → %s (+%s)", key, path, formatBytes(n))
+
+	if l.changeLog != nil {
+		_ = l.changeLog.RecordChange(ChangeEvent{
+			Key:       key,
+			Operation: "put",
+			Timestamp: time.Now(),
+			ETag:      metadata.ETag,
+			Size:      metadata.Size,
+		})
+	}
+	return nil
+}
+
 // GetWithContext retrieves an object from the backend with context support.
 func (l *Local) GetWithContext(ctx context.Context, key string) (io.ReadCloser, error) {
 	if err := l.validateKey(key); err != nil {
@@ -302,7 +480,7 @@ func (l *Local) GetWithContext(ctx context.Context, key string) (io.ReadCloser,
 	default:
 	}
 
-	path := filepath.Join(l.path, key)
+	path := l.objectPath(key)
 	file, err := os.Open(path) // #nosec G304 -- Path validated by validateKey() to prevent directory traversal
 	if err != nil {
 		// Don't log "not found" errors - these are expected during initialization
@@ -312,7 +490,7 @@ func (l *Local) GetWithContext(ctx context.Context, key string) (io.ReadCloser,
 		}
 		// Log actual unexpected errors
 		log.Printf("[LOCAL] ✗ GET '%s' failed: %v", key, err)
-		return nil, err
+		return nil, translateError(err, key)
 	}
 
 	// Get at-rest encrypter if factory is set
@@ -385,13 +563,13 @@ func (l *Local) UpdateMetadata(ctx context.Context, key string, metadata *common
 	}
 
 	// Verify object exists
-	path := filepath.Join(l.path, key)
+	path := l.objectPath(key)
 	info, err := os.Stat(path)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return fmt.Errorf("%w: %s", common.ErrKeyNotFound, key)
 		}
-		return err
+		return translateError(err, key)
 	}
 
 	// Update size and last modified from file
@@ -422,7 +600,7 @@ func (l *Local) DeleteWithContext(ctx context.Context, key string) error {
 	default:
 	}
 
-	path := filepath.Join(l.path, key)
+	path := l.objectPath(key)
 
 	// Get file size before deletion for logging
 	var sizeStr string
@@ -443,7 +621,7 @@ func (l *Local) DeleteWithContext(ctx context.Context, key string) error {
 		}
 		// Log actual unexpected errors
 		log.Printf("[LOCAL] ✗ DELETE '%s' failed: %v", key, err)
-		return err
+		return translateError(err, key)
 	}
 
 	if sizeStr != "" {
@@ -476,7 +654,7 @@ func (l *Local) Exists(ctx context.Context, key string) (bool, error) {
 	default:
 	}
 
-	path := filepath.Join(l.path, key)
+	path := l.objectPath(key)
 	_, err := os.Stat(path)
 	if err == nil {
 		return true, nil
@@ -496,7 +674,7 @@ func (l *Local) List(prefix string) ([]string, error) {
 func (l *Local) ListWithContext(ctx context.Context, prefix string) ([]string, error) {
 	// Validate prefix if not empty (empty prefix is valid for listing all)
 	if prefix != "" {
-		if err := l.validateKey(prefix); err != nil {
+		if err := common.ValidateKey(prefix); err != nil {
 			return nil, err
 		}
 	}
@@ -561,7 +739,7 @@ func (l *Local) ListWithOptions(ctx context.Context, opts *common.ListOptions) (
 
 	// Validate prefix if not empty (empty prefix is valid for listing all)
 	if opts.Prefix != "" {
-		if err := l.validateKey(opts.Prefix); err != nil {
+		if err := common.ValidateKey(opts.Prefix); err != nil {
 			return nil, err
 		}
 	}
@@ -725,6 +903,21 @@ func (l *Local) GetPolicies() ([]common.LifecyclePolicy, error) {
 	return l.lifecycleManager.GetPolicies()
 }
 
+// setStorageClass records class in the metadata of an existing object. The
+// local backend has a single tier, so the class is informational only; it
+// is kept so it round-trips through replication and lifecycle transitions.
+func (l *Local) setStorageClass(key, class string) error {
+	metadata, err := l.loadMetadata(key)
+	if err != nil {
+		return err
+	}
+	if metadata.StorageClass == class {
+		return nil
+	}
+	metadata.StorageClass = class
+	return l.saveMetadata(key, metadata)
+}
+
 // saveMetadata saves metadata to a sidecar file.
 func (l *Local) saveMetadata(key string, metadata *common.Metadata) error {
 	if err := l.validateKey(key); err != nil {
@@ -742,7 +935,7 @@ func (l *Local) saveMetadata(key string, metadata *common.Metadata) error {
 		}
 	}
 
-	path := filepath.Join(l.path, key)
+	path := l.objectPath(key)
 	metadataPath := path + metadataSuffix
 
 	data, err := json.Marshal(metadata)
@@ -769,7 +962,7 @@ func (l *Local) loadMetadata(key string) (*common.Metadata, error) {
 		return nil, err
 	}
 
-	path := filepath.Join(l.path, key)
+	path := l.objectPath(key)
 	metadataPath := path + metadataSuffix
 
 	data, err := os.ReadFile(metadataPath) // #nosec G304 -- Path validated by validateKey() to prevent directory traversal
@@ -893,6 +1086,13 @@ func (l *Local) SetReplicationManager(rm common.ReplicationManager) {
 	l.replicationManager = rm
 }
 
+// CaseInsensitive reports whether the storage directory is on a file system
+// that ignores the case of file names, so that keys differing only in case
+// name the same object. It is detected by Configure.
+func (l *Local) CaseInsensitive() bool {
+	return l.caseInsensitive
+}
+
 // GetPath returns the base path of the local storage.
 // This is useful for creating a replication filesystem that can be passed
 // to the replication manager.
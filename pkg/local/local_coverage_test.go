@@ -23,6 +23,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
 
 	"github.com/jeremyhahn/go-objstore/pkg/adapters"
@@ -148,9 +149,7 @@ func TestLocalFileSystem_Remove(t *testing.T) {
 // ---------------------------------------------------------------------------
 
 func TestLocalFileSystem_OpenFile_MkdirAllFails(t *testing.T) {
-	if os.Getuid() == 0 {
-		t.Skip("cannot test permission errors as root")
-	}
+	skipUnlessPermissionsEnforced(t)
 
 	dir := t.TempDir()
 	lfs := &localFileSystem{basePath: dir}
@@ -172,9 +171,7 @@ func TestLocalFileSystem_OpenFile_MkdirAllFails(t *testing.T) {
 // ---------------------------------------------------------------------------
 
 func TestWriteFileAtomic_ChmodFails(t *testing.T) {
-	if os.Getuid() == 0 {
-		t.Skip("cannot test permission errors as root")
-	}
+	skipUnlessPermissionsEnforced(t)
 
 	dir := t.TempDir()
 
@@ -220,9 +217,7 @@ func TestWriteFileAtomic_WriteCallbackFails(t *testing.T) {
 }
 
 func TestWriteFileAtomic_SyncError(t *testing.T) {
-	if os.Getuid() == 0 {
-		t.Skip("cannot test permission errors as root")
-	}
+	skipUnlessPermissionsEnforced(t)
 	// We can't easily make Sync fail on a real filesystem in a portable way,
 	// so we cover the Close-after-Rename success path instead, and validate
 	// the full happy path leaves no temp files behind.
@@ -246,9 +241,7 @@ func TestWriteFileAtomic_SyncError(t *testing.T) {
 // ---------------------------------------------------------------------------
 
 func TestLocal_SaveMetadata_MkdirFails(t *testing.T) {
-	if os.Getuid() == 0 {
-		t.Skip("cannot test permission errors as root")
-	}
+	skipUnlessPermissionsEnforced(t)
 
 	dir := t.TempDir()
 	s := newConfigured(t, dir)
@@ -277,9 +270,7 @@ func TestLocal_SaveMetadata_MkdirFails(t *testing.T) {
 // ---------------------------------------------------------------------------
 
 func TestLocal_LoadMetadata_PermissionDenied(t *testing.T) {
-	if os.Getuid() == 0 {
-		t.Skip("cannot test permission errors as root")
-	}
+	skipUnlessPermissionsEnforced(t)
 
 	dir := t.TempDir()
 	s := newConfigured(t, dir)
@@ -438,9 +429,7 @@ func TestLocal_Configure_RunLifecycle_PersistentManager(t *testing.T) {
 // ---------------------------------------------------------------------------
 
 func TestLifecycle_Process_WalkError(t *testing.T) {
-	if os.Getuid() == 0 {
-		t.Skip("cannot test permission errors as root")
-	}
+	skipUnlessPermissionsEnforced(t)
 
 	dir := t.TempDir()
 	s := newConfigured(t, dir)
@@ -480,9 +469,7 @@ func TestLifecycle_Process_WalkError(t *testing.T) {
 // ---------------------------------------------------------------------------
 
 func TestLocal_UpdateMetadata_StatNonExistError(t *testing.T) {
-	if os.Getuid() == 0 {
-		t.Skip("cannot test permission errors as root")
-	}
+	skipUnlessPermissionsEnforced(t)
 
 	dir := t.TempDir()
 	s := newConfigured(t, dir)
@@ -514,9 +501,7 @@ func TestLocal_UpdateMetadata_StatNonExistError(t *testing.T) {
 // ---------------------------------------------------------------------------
 
 func TestLocal_ListWithContext_WalkErrorReturned(t *testing.T) {
-	if os.Getuid() == 0 {
-		t.Skip("cannot test permission errors as root")
-	}
+	skipUnlessPermissionsEnforced(t)
 
 	dir := t.TempDir()
 	s := newConfigured(t, dir)
@@ -581,9 +566,7 @@ func TestLocal_Configure_MkdirAllFails(t *testing.T) {
 // ---------------------------------------------------------------------------
 
 func TestLocal_Configure_PersistentManagerError(t *testing.T) {
-	if os.Getuid() == 0 {
-		t.Skip("cannot test permission errors as root")
-	}
+	skipUnlessPermissionsEnforced(t)
 	dir := t.TempDir()
 
 	// Write an invalid JSON policy file so that load() inside
@@ -630,9 +613,7 @@ func TestLocal_PutWithMetadata_EncryptError(t *testing.T) {
 // ---------------------------------------------------------------------------
 
 func TestLocal_GetWithContext_OpenPermissionDenied(t *testing.T) {
-	if os.Getuid() == 0 {
-		t.Skip("cannot test permission errors as root")
-	}
+	skipUnlessPermissionsEnforced(t)
 
 	dir := t.TempDir()
 	s := newConfigured(t, dir)
@@ -711,9 +692,7 @@ func TestLocal_GetWithContext_StatFailsAfterDecrypt(t *testing.T) {
 // ---------------------------------------------------------------------------
 
 func TestLocal_DeleteWithContext_PermissionDenied(t *testing.T) {
-	if os.Getuid() == 0 {
-		t.Skip("cannot test permission errors as root")
-	}
+	skipUnlessPermissionsEnforced(t)
 
 	dir := t.TempDir()
 	s := newConfigured(t, dir)
@@ -766,9 +745,7 @@ func TestLocal_DeleteWithContext_PermissionDenied(t *testing.T) {
 // But then Remove also fails. So we exercise both the "Remove fails"
 // and the "stat fails → sizeStr empty → Remove fails" paths together.
 func TestLocal_DeleteWithContext_StatFailsBeforeDelete(t *testing.T) {
-	if os.Getuid() == 0 {
-		t.Skip("cannot test permission errors as root")
-	}
+	skipUnlessPermissionsEnforced(t)
 
 	dir := t.TempDir()
 	s := newConfigured(t, dir)
@@ -1123,3 +1100,15 @@ func (e *failDecryptEncrypter) Decrypt(ctx context.Context, data io.Reader) (io.
 }
 func (e *failDecryptEncrypter) Algorithm() string { return "fail" }
 func (e *failDecryptEncrypter) KeyID() string     { return "fail" }
+
+// skipUnlessPermissionsEnforced skips tests that rely on file mode bits
+// denying access, which root bypasses and Windows ignores.
+func skipUnlessPermissionsEnforced(t *testing.T) {
+	t.Helper()
+	if os.Getuid() == 0 {
+		t.Skip("cannot test permission errors as root")
+	}
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes do not deny access on Windows")
+	}
+}
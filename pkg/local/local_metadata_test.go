@@ -134,6 +134,26 @@ func TestLocal_PutWithMetadata(t *testing.T) {
 	})
 }
 
+func TestLocal_PutWithInfo(t *testing.T) {
+	storage := New().(*Local)
+	if err := storage.Configure(map[string]string{"path": t.TempDir()}); err != nil {
+		t.Fatalf("failed to configure storage: %v", err)
+	}
+	ctx := context.Background()
+
+	info, err := storage.PutWithInfo(ctx, "test/key", bytes.NewReader([]byte("test data")), nil)
+	if err != nil {
+		t.Fatalf("PutWithInfo() error = %v", err)
+	}
+	stored, err := storage.GetMetadata(ctx, "test/key")
+	if err != nil {
+		t.Fatalf("failed to get metadata: %v", err)
+	}
+	if info.Key != "test/key" || info.Metadata.ETag == "" || info.Metadata.ETag != stored.ETag || info.Metadata.Size != 9 {
+		t.Errorf("PutWithInfo() = %+v, want the stored metadata %+v", info.Metadata, stored)
+	}
+}
+
 func TestLocal_GetMetadata(t *testing.T) {
 	t.Run("get metadata for existing file with metadata", func(t *testing.T) {
 		tmpDir := t.TempDir()
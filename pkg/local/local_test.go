@@ -26,6 +26,7 @@ import (
 	"time"
 
 	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/delta"
 	"github.com/jeremyhahn/go-objstore/pkg/local"
 )
 
@@ -1620,3 +1621,144 @@ func TestLocal_ListWithOptions_InvalidContinuationToken(t *testing.T) {
 		t.Error("Expected some objects even with invalid continuation token")
 	}
 }
+
+func TestLocal_GetRange(t *testing.T) {
+	tempDir := createTempDir(t)
+	defer cleanupTempDir(t, tempDir)
+
+	storage := local.New()
+	if err := storage.Configure(map[string]string{"path": tempDir}); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+	if err := storage.Put("range.txt", strings.NewReader("0123456789")); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	rr, ok := storage.(common.RangeReader)
+	if !ok {
+		t.Fatal("local storage does not implement common.RangeReader")
+	}
+
+	tests := []struct {
+		offset, length int64
+		want           string
+	}{
+		{0, 4, "0123"},
+		{3, 3, "345"},
+		{7, -1, "789"},
+		{8, 10, "89"},
+	}
+	for _, tt := range tests {
+		rc, err := rr.GetRange(context.Background(), "range.txt", tt.offset, tt.length)
+		if err != nil {
+			t.Fatalf("GetRange(%d, %d) failed: %v", tt.offset, tt.length, err)
+		}
+		got, _ := io.ReadAll(rc)
+		_ = rc.Close()
+		if string(got) != tt.want {
+			t.Errorf("GetRange(%d, %d) = %q, want %q", tt.offset, tt.length, got, tt.want)
+		}
+	}
+
+	if _, err := rr.GetRange(context.Background(), "missing.txt", 0, 1); !errors.Is(err, common.ErrKeyNotFound) {
+		t.Errorf("Expected ErrKeyNotFound, got %v", err)
+	}
+}
+
+func TestLocal_ApplyDelta(t *testing.T) {
+	tempDir := createTempDir(t)
+	defer cleanupTempDir(t, tempDir)
+
+	storage := local.New()
+	if err := storage.Configure(map[string]string{"path": tempDir}); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+	ctx := context.Background()
+	if err := storage.PutWithMetadata(ctx, "file.txt", strings.NewReader("aaaabbbbcccc"), &common.Metadata{ContentType: "text/plain"}); err != nil {
+		t.Fatalf("PutWithMetadata failed: %v", err)
+	}
+	meta, err := storage.GetMetadata(ctx, "file.txt")
+	if err != nil {
+		t.Fatalf("GetMetadata failed: %v", err)
+	}
+
+	patcher, ok := storage.(delta.Patcher)
+	if !ok {
+		t.Fatal("local storage does not implement delta.Patcher")
+	}
+
+	// Keep "aaaa" and "cccc" from the base, replace the middle.
+	target := []byte("aaaaXYZcccc")
+	ops := []delta.Op{
+		{Kind: delta.OpCopy, Offset: 0, Length: 4},
+		{Kind: delta.OpLiteral, Offset: 4, Length: 3},
+		{Kind: delta.OpCopy, Offset: 8, Length: 4},
+	}
+	if err := patcher.ApplyDelta(ctx, "file.txt", "stale", ops, delta.BytesSource(target), nil); !errors.Is(err, delta.ErrBaseChanged) {
+		t.Fatalf("Expected ErrBaseChanged, got %v", err)
+	}
+	if err := patcher.ApplyDelta(ctx, "file.txt", meta.ETag, ops, delta.BytesSource(target), meta); err != nil {
+		t.Fatalf("ApplyDelta failed: %v", err)
+	}
+
+	rc, err := storage.Get("file.txt")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	got, _ := io.ReadAll(rc)
+	_ = rc.Close()
+	if !bytes.Equal(got, target) {
+		t.Errorf("Expected %q, got %q", target, got)
+	}
+
+	if err := patcher.ApplyDelta(ctx, "missing.txt", "", ops, delta.BytesSource(target), nil); !errors.Is(err, common.ErrKeyNotFound) {
+		t.Errorf("Expected ErrKeyNotFound, got %v", err)
+	}
+}
+
+func TestLocal_Append(t *testing.T) {
+	tempDir := createTempDir(t)
+	defer cleanupTempDir(t, tempDir)
+
+	storage := local.New()
+	if err := storage.Configure(map[string]string{"path": tempDir}); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+	appender, ok := storage.(common.Appender)
+	if !ok {
+		t.Fatal("local storage does not implement common.Appender")
+	}
+
+	ctx := context.Background()
+	if err := appender.Append(ctx, "logs/app.log", strings.NewReader("one\n")); err != nil {
+		t.Fatalf("Append to missing key failed: %v", err)
+	}
+	if err := storage.UpdateMetadata(ctx, "logs/app.log", &common.Metadata{ContentType: "text/plain"}); err != nil {
+		t.Fatalf("UpdateMetadata failed: %v", err)
+	}
+	if err := appender.Append(ctx, "logs/app.log", strings.NewReader("two\n")); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+
+	rc, err := storage.Get("logs/app.log")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	data, _ := io.ReadAll(rc)
+	_ = rc.Close()
+	if string(data) != "one\ntwo\n" {
+		t.Errorf("content = %q, want %q", data, "one\ntwo\n")
+	}
+
+	md, err := storage.GetMetadata(ctx, "logs/app.log")
+	if err != nil {
+		t.Fatalf("GetMetadata failed: %v", err)
+	}
+	if md.Size != 8 || md.ContentType != "text/plain" {
+		t.Errorf("metadata = %+v, want size 8 and content type kept", md)
+	}
+
+	if err := appender.Append(ctx, "../escape", strings.NewReader("x")); err == nil {
+		t.Error("Append with invalid key should fail")
+	}
+}
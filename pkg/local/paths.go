@@ -0,0 +1,129 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package local
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// windowsReservedNames are the device names Windows reserves in every
+// directory, with or without an extension.
+var windowsReservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true,
+	"COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true,
+	"LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+const (
+	// windowsMaxPath is the longest path Win32 APIs accept without the
+	// long-path prefix: MAX_PATH (260) less the 12 characters reserved for
+	// an 8.3 file name when a directory is created.
+	windowsMaxPath = 260 - 12
+
+	// windowsMaxSegment is the longest file name NTFS stores, in UTF-16
+	// code units, whether or not the long-path prefix is used.
+	windowsMaxSegment = 255
+
+	// windowsLongPathPrefix makes Win32 APIs pass a path to the file system
+	// unparsed, lifting the MAX_PATH limit to about 32,767 characters.
+	windowsLongPathPrefix = `\\?\`
+)
+
+// objectPath returns the file that stores key. Keys always separate
+// segments with '/', whatever the platform's separator. On Windows, paths
+// too long for MAX_PATH are given the long-path prefix.
+func (l *Local) objectPath(key string) string {
+	path := filepath.Join(l.path, filepath.FromSlash(key))
+	if windowsPaths {
+		return windowsLongPath(path)
+	}
+	return path
+}
+
+// windowsLongPath returns the absolute Windows path with the long-path
+// prefix when it is too long for MAX_PATH. Drive paths (C:\dir) become
+// \\?\C:\dir and UNC paths (\\server\share) become \\?\UNC\server\share.
+// Short, relative, device and already prefixed paths are returned
+// unchanged.
+func windowsLongPath(path string) string {
+	if len(path) < windowsMaxPath || strings.HasPrefix(path, windowsLongPathPrefix) || strings.HasPrefix(path, `\\.\`) {
+		return path
+	}
+	switch {
+	case strings.HasPrefix(path, `\\`):
+		return windowsLongPathPrefix + `UNC\` + path[2:]
+	case len(path) >= 3 && path[1] == ':' && path[2] == '\\':
+		return windowsLongPathPrefix + path
+	default:
+		return path
+	}
+}
+
+// validateWindowsKey rejects keys that Windows cannot store as a file, or
+// would store under a different name: reserved characters, including '\'
+// which Windows treats as a separator, reserved device names such as NUL or
+// COM1.txt, segments ending in '.' or ' ', which Windows strips, and
+// segments longer than NTFS allows. Long keys are otherwise fine: objectPath
+// gives their paths the long-path prefix.
+func validateWindowsKey(key string) error {
+	for _, r := range key {
+		if r < 32 || strings.ContainsRune(`<>:"|?*\`, r) {
+			return fmt.Errorf("%w: key %q contains %q, which Windows does not allow in file names",
+				common.ErrInvalidArgument, key, r)
+		}
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" {
+			continue
+		}
+		if n := len(utf16.Encode([]rune(segment))); n > windowsMaxSegment {
+			return fmt.Errorf("%w: key %q has a %d-character segment, longer than the %d Windows allows",
+				common.ErrInvalidArgument, key, n, windowsMaxSegment)
+		}
+		if strings.HasSuffix(segment, ".") || strings.HasSuffix(segment, " ") {
+			return fmt.Errorf("%w: key %q has segment %q ending in %q, which Windows strips from file names",
+				common.ErrInvalidArgument, key, segment, segment[len(segment)-1:])
+		}
+		base, _, _ := strings.Cut(segment, ".")
+		if windowsReservedNames[strings.ToUpper(strings.TrimRight(base, " "))] {
+			return fmt.Errorf("%w: key %q has segment %q, a reserved device name on Windows",
+				common.ErrInvalidArgument, key, segment)
+		}
+	}
+	return nil
+}
+
+// caseInsensitive reports whether dir is on a file system that ignores the
+// case of file names, as NTFS and APFS do by default. It creates and
+// removes a probe file; when that fails dir is assumed case-sensitive.
+func caseInsensitive(dir string) bool {
+	probe, err := os.CreateTemp(dir, ".objstore-case-probe-*")
+	if err != nil {
+		return false
+	}
+	name := probe.Name()
+	_ = probe.Close()
+	defer func() { _ = os.Remove(name) }()
+
+	_, err = os.Stat(filepath.Join(dir, strings.ToUpper(filepath.Base(name))))
+	return err == nil
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package local
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+func TestValidateWindowsKey(t *testing.T) {
+	valid := []string{"a.txt", "logs/2025/app.log", "console/out", "COM10", "logs/", "a b/c d.txt"}
+	for _, key := range valid {
+		if err := validateWindowsKey(key); err != nil {
+			t.Errorf("validateWindowsKey(%q) error = %v", key, err)
+		}
+	}
+
+	invalid := []string{
+		`a\b`, "a:b", "a<b", "a>b", `a"b`, "a|b", "a?b", "a*b", "a\x01b",
+		"CON", "nul.txt", "con.d/x", "logs/Aux/x", "lpt1.tar.gz", "com9 ",
+		"logs./a", "a.txt.", "dir /a", "logs/" + strings.Repeat("a", 256),
+	}
+	for _, key := range invalid {
+		if err := validateWindowsKey(key); !errors.Is(err, common.ErrInvalidArgument) {
+			t.Errorf("validateWindowsKey(%q) error = %v, want ErrInvalidArgument", key, err)
+		}
+	}
+}
+
+func TestValidateWindowsKey_LongKeys(t *testing.T) {
+	// Keys far beyond MAX_PATH are valid as long as every segment fits.
+	segment := strings.Repeat("a", 255)
+	key := strings.Repeat(segment+"/", 4) + "file.txt"
+	if err := validateWindowsKey(key); err != nil {
+		t.Errorf("validateWindowsKey(long key) error = %v", err)
+	}
+	// The limit counts UTF-16 code units, not bytes.
+	if err := validateWindowsKey(strings.Repeat("é", 255)); err != nil {
+		t.Errorf("validateWindowsKey(255 x é) error = %v", err)
+	}
+	if err := validateWindowsKey(strings.Repeat("😀", 128)); !errors.Is(err, common.ErrInvalidArgument) {
+		t.Errorf("validateWindowsKey(128 x 😀) error = %v, want ErrInvalidArgument", err)
+	}
+}
+
+func TestWindowsLongPath(t *testing.T) {
+	long := strings.Repeat(`\dir`, 70)
+	tests := []struct {
+		path, want string
+	}{
+		{`C:\data\a.txt`, `C:\data\a.txt`},
+		{`C:\data` + long, `\\?\C:\data` + long},
+		{`\\server\share` + long, `\\?\UNC\server\share` + long},
+		{`\\?\C:\data` + long, `\\?\C:\data` + long},
+		{`\\.\pipe` + long, `\\.\pipe` + long},
+		{`data` + long, `data` + long},
+	}
+	for _, tt := range tests {
+		if got := windowsLongPath(tt.path); got != tt.want {
+			t.Errorf("windowsLongPath(%.20q...) = %.30q..., want %.30q...", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestObjectPath(t *testing.T) {
+	l := &Local{path: t.TempDir()}
+	want := filepath.Join(l.path, "logs", "2025", "app.log")
+	if got := l.objectPath("logs/2025/app.log"); got != want {
+		t.Errorf("objectPath() = %q, want %q", got, want)
+	}
+}
+
+func TestConfigure_RelativePath(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	storage := New().(*Local)
+	if err := storage.Configure(map[string]string{"path": "data"}); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+	if !filepath.IsAbs(storage.GetPath()) {
+		t.Errorf("GetPath() = %q, want an absolute path", storage.GetPath())
+	}
+	if err := storage.Put("a/b.txt", strings.NewReader("b")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "data", "a", "b.txt")); err != nil {
+		t.Errorf("object not stored below the configured path: %v", err)
+	}
+}
+
+func TestCaseInsensitive(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "probe"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	_, err := os.Stat(filepath.Join(dir, "PROBE"))
+	want := err == nil
+	if err := os.Remove(filepath.Join(dir, "probe")); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := caseInsensitive(dir); got != want {
+		t.Errorf("caseInsensitive() = %v, want %v", got, want)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("caseInsensitive() left %d files behind", len(entries))
+	}
+	if caseInsensitive(filepath.Join(dir, "missing")) {
+		t.Error("caseInsensitive() of a missing directory = true")
+	}
+
+	storage := New().(*Local)
+	if err := storage.Configure(map[string]string{"path": dir}); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+	if storage.CaseInsensitive() != want {
+		t.Errorf("CaseInsensitive() = %v, want %v", storage.CaseInsensitive(), want)
+	}
+}
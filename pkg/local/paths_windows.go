@@ -0,0 +1,6 @@
+//go:build windows
+
+package local
+
+// windowsPaths enables the Windows file name rules in validateKey.
+const windowsPaths = true
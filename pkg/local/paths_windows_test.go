@@ -0,0 +1,53 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package local
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestLongPaths(t *testing.T) {
+	storage := New().(*Local)
+	if err := storage.Configure(map[string]string{"path": t.TempDir()}); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+
+	// Nest the key well past MAX_PATH.
+	key := strings.Repeat(strings.Repeat("d", 50)+"/", 8) + "file.txt"
+	if path := storage.objectPath(key); !strings.HasPrefix(path, windowsLongPathPrefix) {
+		t.Errorf("objectPath() = %q, want the long-path prefix", path)
+	}
+	if err := storage.Put(key, strings.NewReader("long")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	rc, err := storage.Get(key)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	data, err := io.ReadAll(rc)
+	_ = rc.Close()
+	if err != nil || string(data) != "long" {
+		t.Errorf("Get() = %q, %v", data, err)
+	}
+
+	keys, err := storage.List("")
+	if err != nil || len(keys) != 1 || keys[0] != key {
+		t.Errorf("List() = %v, %v, want [%s]", keys, err, key)
+	}
+	if err := storage.Delete(key); err != nil {
+		t.Errorf("Delete() error = %v", err)
+	}
+}
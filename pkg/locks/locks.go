@@ -0,0 +1,364 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package locks implements advisory locks on object keys with backend
+// conditional writes, so workers sharing a backend can coordinate on the
+// same keys without a separate lock service.
+//
+// A lock on key is an object under a reserved prefix (DefaultPrefix) holding
+// the lock's random token, owner and expiry. It is created with a
+// create-if-absent write and released, refreshed or taken over once expired
+// with writes conditional on its ETag, so two workers never both hold it.
+// Locks are advisory: they do not stop writes to the key itself.
+package locks
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// DefaultPrefix is the key prefix lock objects are stored under when none
+// is configured.
+const DefaultPrefix = ".locks/"
+
+// TTL bounds. Backend ETags may only change once per second, so a lock must
+// live at least that long for conditional writes to tell its versions apart.
+const (
+	MinTTL = time.Second
+	MaxTTL = 24 * time.Hour
+)
+
+// maxOwnerLength bounds the owner recorded with a lock.
+const maxOwnerLength = 256
+
+// Polling bounds for Lock.
+const (
+	minPollInterval = 50 * time.Millisecond
+	maxPollInterval = time.Second
+)
+
+// maxAttempts bounds TryLock's retries when a lock disappears or expires
+// while it is being acquired.
+const maxAttempts = 3
+
+var (
+	// ErrLocked is returned when another holder has the lock. It wraps
+	// common.ErrAlreadyExists.
+	ErrLocked = fmt.Errorf("%w: key is locked", common.ErrAlreadyExists)
+
+	// ErrNotHeld is returned when unlocking or refreshing a lock with a
+	// token that does not hold it, for example because it expired and was
+	// taken over. It wraps common.ErrPreconditionFailed.
+	ErrNotHeld = fmt.Errorf("%w: lock not held", common.ErrPreconditionFailed)
+
+	// ErrLockNotFound is returned when a key is not locked.
+	ErrLockNotFound = fmt.Errorf("lock %w", common.ErrNotFound)
+
+	// ErrInvalidTTL is returned for a TTL outside MinTTL and MaxTTL.
+	ErrInvalidTTL = fmt.Errorf("%w: lock TTL must be between %s and %s", common.ErrInvalidArgument, MinTTL, MaxTTL)
+
+	// ErrNotSupported is returned by NewManager for backends that cannot
+	// write conditionally.
+	ErrNotSupported = errors.New("backend does not support conditional writes")
+)
+
+// Lock is an advisory lock on a key. Token proves ownership and is only
+// returned to the holder.
+type Lock struct {
+	Key        string    `json:"key"`
+	Token      string    `json:"token,omitempty"`
+	Owner      string    `json:"owner,omitempty"`
+	AcquiredAt time.Time `json:"acquired_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// Manager acquires and releases locks stored in a backend.
+type Manager struct {
+	storage common.Storage
+	writer  common.ConditionalWriter
+	prefix  string
+	now     func() time.Time
+}
+
+// NewManager returns a Manager storing locks in storage under prefix, or
+// DefaultPrefix if prefix is empty. The storage must implement
+// common.ConditionalWriter.
+func NewManager(storage common.Storage, prefix string) (*Manager, error) {
+	writer, ok := storage.(common.ConditionalWriter)
+	if !ok {
+		return nil, ErrNotSupported
+	}
+	if prefix == "" {
+		prefix = DefaultPrefix
+	}
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	if err := common.ValidateKey(prefix + "lock"); err != nil {
+		return nil, fmt.Errorf("invalid lock prefix %q: %w", prefix, err)
+	}
+	return &Manager{storage: storage, writer: writer, prefix: prefix, now: time.Now}, nil
+}
+
+// Prefix returns the key prefix lock objects are stored under.
+func (m *Manager) Prefix() string {
+	return m.prefix
+}
+
+// Reserved reports whether key is in the lock namespace.
+func (m *Manager) Reserved(key string) bool {
+	return common.IsReserved(key, m.prefix)
+}
+
+// TryLock acquires the lock on key for ttl, taking over an expired lock. It
+// fails with ErrLocked if another holder has it.
+func (m *Manager) TryLock(ctx context.Context, key, owner string, ttl time.Duration) (*Lock, error) {
+	if err := m.validate(key, owner, ttl); err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		token, err := newToken()
+		if err != nil {
+			return nil, err
+		}
+		now := m.now().UTC()
+		lock := &Lock{Key: key, Token: token, Owner: owner, AcquiredAt: now, ExpiresAt: now.Add(ttl)}
+		err = m.write(ctx, lock, "")
+		if err == nil {
+			return lock, nil
+		}
+		if !common.LostRace(err) {
+			return nil, err
+		}
+
+		current, etag, err := m.read(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		if current == nil {
+			continue
+		}
+		if !m.expired(current) {
+			return nil, fmt.Errorf("%w: %s held until %s", ErrLocked, key, current.ExpiresAt.Format(time.RFC3339))
+		}
+		// Remove the expired lock, unless someone else already did, and
+		// compete for a fresh one.
+		if err := m.writer.DeleteIfMatch(ctx, m.lockKey(key), etag); err != nil &&
+			!common.LostRace(err) && !errors.Is(err, common.ErrNotFound) {
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrLocked, key)
+}
+
+// Lock acquires the lock on key for ttl, waiting until it is free or ctx is
+// done, in which case it returns ctx's error.
+func (m *Manager) Lock(ctx context.Context, key, owner string, ttl time.Duration) (*Lock, error) {
+	interval := minPollInterval
+	for {
+		lock, err := m.TryLock(ctx, key, owner, ttl)
+		if !errors.Is(err, ErrLocked) {
+			return lock, err
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		if interval *= 2; interval > maxPollInterval {
+			interval = maxPollInterval
+		}
+	}
+}
+
+// Refresh extends the lock on key held with token to expire ttl from now.
+func (m *Manager) Refresh(ctx context.Context, key, token string, ttl time.Duration) (*Lock, error) {
+	if err := m.validate(key, "", ttl); err != nil {
+		return nil, err
+	}
+	current, etag, err := m.read(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if !holds(current, token) {
+		return nil, fmt.Errorf("%w: %s", ErrNotHeld, key)
+	}
+
+	current.ExpiresAt = m.now().UTC().Add(ttl)
+	if err := m.write(ctx, current, etag); err != nil {
+		if common.LostRace(err) {
+			return nil, fmt.Errorf("%w: %s", ErrNotHeld, key)
+		}
+		return nil, err
+	}
+	return current, nil
+}
+
+// Unlock releases the lock on key held with token.
+func (m *Manager) Unlock(ctx context.Context, key, token string) error {
+	if err := common.ValidateKey(key); err != nil {
+		return err
+	}
+	current, etag, err := m.read(ctx, key)
+	if err != nil {
+		return err
+	}
+	if !holds(current, token) {
+		return fmt.Errorf("%w: %s", ErrNotHeld, key)
+	}
+
+	err = m.writer.DeleteIfMatch(ctx, m.lockKey(key), etag)
+	if common.LostRace(err) || errors.Is(err, common.ErrNotFound) {
+		return fmt.Errorf("%w: %s", ErrNotHeld, key)
+	}
+	return err
+}
+
+// Get returns the unexpired lock on key, without its token.
+func (m *Manager) Get(ctx context.Context, key string) (*Lock, error) {
+	if err := common.ValidateKey(key); err != nil {
+		return nil, err
+	}
+	current, _, err := m.read(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil || m.expired(current) {
+		return nil, fmt.Errorf("%w: %s", ErrLockNotFound, key)
+	}
+	current.Token = ""
+	return current, nil
+}
+
+// List returns the unexpired locks on keys starting with prefix, ordered by
+// key and without their tokens.
+func (m *Manager) List(ctx context.Context, prefix string) ([]Lock, error) {
+	keys, err := m.storage.ListWithContext(ctx, m.prefix+prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	locks := make([]Lock, 0, len(keys))
+	for _, lockKey := range keys {
+		current, _, err := m.read(ctx, strings.TrimPrefix(lockKey, m.prefix))
+		if err != nil {
+			return nil, err
+		}
+		if current == nil || m.expired(current) {
+			continue
+		}
+		current.Token = ""
+		locks = append(locks, *current)
+	}
+	return locks, nil
+}
+
+func (m *Manager) validate(key, owner string, ttl time.Duration) error {
+	if err := common.ValidateKey(key); err != nil {
+		return err
+	}
+	if m.Reserved(key) {
+		return fmt.Errorf("%w: %s", common.ErrReservedKey, key)
+	}
+	if ttl < MinTTL || ttl > MaxTTL {
+		return fmt.Errorf("%w: got %s", ErrInvalidTTL, ttl)
+	}
+	if len(owner) > maxOwnerLength {
+		return fmt.Errorf("%w: lock owner exceeds %d bytes", common.ErrInvalidArgument, maxOwnerLength)
+	}
+	return nil
+}
+
+func (m *Manager) lockKey(key string) string {
+	return m.prefix + key
+}
+
+func (m *Manager) expired(lock *Lock) bool {
+	return !m.now().Before(lock.ExpiresAt)
+}
+
+// read returns the lock on key and the ETag of its object, or nil if there
+// is none. The ETag is read before the contents, so a conditional write
+// based on it fails if the lock changed in between. Unreadable lock objects
+// are treated as expired.
+func (m *Manager) read(ctx context.Context, key string) (*Lock, string, error) {
+	lockKey := m.lockKey(key)
+	metadata, err := m.storage.GetMetadata(ctx, lockKey)
+	if errors.Is(err, common.ErrNotFound) {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	rc, err := m.storage.GetWithContext(ctx, lockKey)
+	if errors.Is(err, common.ErrNotFound) {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	data, err := io.ReadAll(io.LimitReader(rc, 64*1024))
+	_ = rc.Close()
+	if err != nil {
+		return nil, "", err
+	}
+
+	var lock Lock
+	if err := json.Unmarshal(data, &lock); err != nil || lock.Key != key {
+		return &Lock{Key: key}, metadata.ETag, nil
+	}
+	return &lock, metadata.ETag, nil
+}
+
+// write stores lock conditionally on etag; an empty etag requires no lock
+// object to exist.
+func (m *Manager) write(ctx context.Context, lock *Lock, etag string) error {
+	data, err := json.Marshal(lock)
+	if err != nil {
+		return err
+	}
+	return m.writer.PutIfMatch(ctx, m.lockKey(lock.Key), bytes.NewReader(data), &common.Metadata{
+		ContentType: "application/json",
+	}, etag)
+}
+
+// holds reports whether token holds lock. An expired lock is still held
+// until someone else takes it over.
+func holds(lock *Lock, token string) bool {
+	return lock != nil && lock.Token != "" &&
+		subtle.ConstantTimeCompare([]byte(lock.Token), []byte(token)) == 1
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
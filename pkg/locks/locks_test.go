@@ -0,0 +1,336 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package locks
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+// fakeClock is a settable clock for expiry tests.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newTestManager(t *testing.T) (*Manager, common.Storage, *fakeClock) {
+	t.Helper()
+	backend := memory.New()
+	manager, err := NewManager(backend, "")
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	manager.now = clock.Now
+	return manager, backend, clock
+}
+
+// plainStorage hides a backend's optional interfaces.
+type plainStorage struct {
+	common.Storage
+}
+
+func TestNewManager(t *testing.T) {
+	if _, err := NewManager(plainStorage{memory.New()}, ""); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("expected ErrNotSupported, got %v", err)
+	}
+
+	manager, err := NewManager(memory.New(), "mutex")
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	if manager.Prefix() != "mutex/" {
+		t.Errorf("expected prefix mutex/, got %q", manager.Prefix())
+	}
+
+	if _, err := NewManager(memory.New(), "../locks"); err == nil {
+		t.Error("expected invalid prefix to be rejected")
+	}
+}
+
+func TestTryLockAndUnlock(t *testing.T) {
+	manager, backend, _ := newTestManager(t)
+	ctx := context.Background()
+
+	lock, err := manager.TryLock(ctx, "jobs/report", "worker-1", time.Minute)
+	if err != nil {
+		t.Fatalf("TryLock: %v", err)
+	}
+	if lock.Token == "" || lock.Owner != "worker-1" || lock.ExpiresAt.Sub(lock.AcquiredAt) != time.Minute {
+		t.Errorf("unexpected lock: %+v", lock)
+	}
+	if exists, _ := backend.Exists(ctx, DefaultPrefix+"jobs/report"); !exists {
+		t.Error("expected lock object under the lock prefix")
+	}
+
+	_, err = manager.TryLock(ctx, "jobs/report", "worker-2", time.Minute)
+	if !errors.Is(err, ErrLocked) || !errors.Is(err, common.ErrAlreadyExists) {
+		t.Errorf("expected ErrLocked, got %v", err)
+	}
+
+	got, err := manager.Get(ctx, "jobs/report")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Token != "" || got.Owner != "worker-1" {
+		t.Errorf("expected redacted lock owned by worker-1, got %+v", got)
+	}
+
+	if err := manager.Unlock(ctx, "jobs/report", "wrong"); !errors.Is(err, ErrNotHeld) {
+		t.Errorf("expected ErrNotHeld, got %v", err)
+	}
+	if err := manager.Unlock(ctx, "jobs/report", lock.Token); err != nil {
+		t.Fatalf("Unlock: %v", err)
+	}
+	if _, err := manager.Get(ctx, "jobs/report"); !errors.Is(err, ErrLockNotFound) {
+		t.Errorf("expected ErrLockNotFound after unlock, got %v", err)
+	}
+	if err := manager.Unlock(ctx, "jobs/report", lock.Token); !errors.Is(err, ErrNotHeld) {
+		t.Errorf("expected ErrNotHeld for second unlock, got %v", err)
+	}
+
+	if _, err := manager.TryLock(ctx, "jobs/report", "worker-2", time.Minute); err != nil {
+		t.Errorf("expected lock to be free after unlock, got %v", err)
+	}
+}
+
+func TestTryLockValidation(t *testing.T) {
+	manager, _, _ := newTestManager(t)
+	ctx := context.Background()
+
+	for _, ttl := range []time.Duration{0, 500 * time.Millisecond, MaxTTL + time.Second} {
+		if _, err := manager.TryLock(ctx, "a", "", ttl); !errors.Is(err, ErrInvalidTTL) {
+			t.Errorf("ttl %s: expected ErrInvalidTTL, got %v", ttl, err)
+		}
+	}
+	if _, err := manager.TryLock(ctx, DefaultPrefix+"a", "", time.Minute); !errors.Is(err, common.ErrReservedKey) {
+		t.Errorf("expected common.ErrReservedKey, got %v", err)
+	}
+	if _, err := manager.TryLock(ctx, "a", strings.Repeat("x", maxOwnerLength+1), time.Minute); !errors.Is(err, common.ErrInvalidArgument) {
+		t.Errorf("expected long owner to be rejected, got %v", err)
+	}
+	if _, err := manager.TryLock(ctx, "", "", time.Minute); err == nil {
+		t.Error("expected empty key to be rejected")
+	}
+}
+
+func TestExpiredLockIsTakenOver(t *testing.T) {
+	manager, _, clock := newTestManager(t)
+	ctx := context.Background()
+
+	first, err := manager.TryLock(ctx, "k", "worker-1", time.Minute)
+	if err != nil {
+		t.Fatalf("TryLock: %v", err)
+	}
+	clock.Advance(time.Minute)
+
+	if _, err := manager.Get(ctx, "k"); !errors.Is(err, ErrLockNotFound) {
+		t.Errorf("expected expired lock to be hidden, got %v", err)
+	}
+	second, err := manager.TryLock(ctx, "k", "worker-2", time.Minute)
+	if err != nil {
+		t.Fatalf("expected expired lock to be taken over, got %v", err)
+	}
+	if second.Token == first.Token {
+		t.Error("expected a new token")
+	}
+
+	if err := manager.Unlock(ctx, "k", first.Token); !errors.Is(err, ErrNotHeld) {
+		t.Errorf("expected previous holder to lose the lock, got %v", err)
+	}
+	if _, err := manager.Refresh(ctx, "k", first.Token, time.Minute); !errors.Is(err, ErrNotHeld) {
+		t.Errorf("expected previous holder refresh to fail, got %v", err)
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	manager, _, clock := newTestManager(t)
+	ctx := context.Background()
+
+	lock, err := manager.TryLock(ctx, "k", "worker-1", time.Minute)
+	if err != nil {
+		t.Fatalf("TryLock: %v", err)
+	}
+	clock.Advance(50 * time.Second)
+
+	refreshed, err := manager.Refresh(ctx, "k", lock.Token, time.Minute)
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if !refreshed.ExpiresAt.Equal(clock.Now().Add(time.Minute)) || refreshed.Token != lock.Token {
+		t.Errorf("unexpected refreshed lock: %+v", refreshed)
+	}
+
+	clock.Advance(30 * time.Second)
+	if _, err := manager.TryLock(ctx, "k", "worker-2", time.Minute); !errors.Is(err, ErrLocked) {
+		t.Errorf("expected refreshed lock to still be held, got %v", err)
+	}
+
+	if _, err := manager.Refresh(ctx, "k", "wrong", time.Minute); !errors.Is(err, ErrNotHeld) {
+		t.Errorf("expected ErrNotHeld, got %v", err)
+	}
+	if _, err := manager.Refresh(ctx, "missing", lock.Token, time.Minute); !errors.Is(err, ErrNotHeld) {
+		t.Errorf("expected ErrNotHeld for missing lock, got %v", err)
+	}
+}
+
+func TestLockWaits(t *testing.T) {
+	manager, _, _ := newTestManager(t)
+	manager.now = time.Now
+	ctx := context.Background()
+
+	held, err := manager.TryLock(ctx, "k", "worker-1", time.Minute)
+	if err != nil {
+		t.Fatalf("TryLock: %v", err)
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	if _, err := manager.Lock(waitCtx, "k", "worker-2", time.Minute); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		_ = manager.Unlock(ctx, "k", held.Token)
+	}()
+	waitCtx, cancel = context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	lock, err := manager.Lock(waitCtx, "k", "worker-2", time.Minute)
+	if err != nil {
+		t.Fatalf("Lock: %v", err)
+	}
+	if lock.Owner != "worker-2" {
+		t.Errorf("expected worker-2 to hold the lock, got %+v", lock)
+	}
+}
+
+func TestTryLockConcurrent(t *testing.T) {
+	manager, _, _ := newTestManager(t)
+	ctx := context.Background()
+
+	var acquired atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := manager.TryLock(ctx, "k", "", time.Minute); err == nil {
+				acquired.Add(1)
+			} else if !errors.Is(err, ErrLocked) {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if acquired.Load() != 1 {
+		t.Errorf("expected exactly one holder, got %d", acquired.Load())
+	}
+}
+
+func TestList(t *testing.T) {
+	manager, _, clock := newTestManager(t)
+	ctx := context.Background()
+
+	for _, key := range []string{"jobs/a", "jobs/b", "other"} {
+		if _, err := manager.TryLock(ctx, key, "w", time.Minute); err != nil {
+			t.Fatalf("TryLock %s: %v", key, err)
+		}
+	}
+	if _, err := manager.TryLock(ctx, "jobs/c", "w", time.Second); err != nil {
+		t.Fatalf("TryLock: %v", err)
+	}
+	clock.Advance(time.Second)
+
+	locks, err := manager.List(ctx, "jobs/")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(locks) != 2 || locks[0].Key != "jobs/a" || locks[1].Key != "jobs/b" {
+		t.Fatalf("unexpected locks: %+v", locks)
+	}
+	for _, lock := range locks {
+		if lock.Token != "" {
+			t.Errorf("expected token to be redacted: %+v", lock)
+		}
+	}
+}
+
+func TestStorageReservesLockNamespace(t *testing.T) {
+	manager, backend, _ := newTestManager(t)
+	storage := NewStorage(backend, manager)
+	ctx := context.Background()
+
+	if _, err := manager.TryLock(ctx, "data", "w", time.Minute); err != nil {
+		t.Fatalf("TryLock: %v", err)
+	}
+	if err := storage.PutWithContext(ctx, "data", strings.NewReader("x")); err != nil {
+		t.Fatalf("expected locked key itself to stay writable, got %v", err)
+	}
+
+	reserved := DefaultPrefix + "data"
+	checks := map[string]error{
+		"put":      storage.PutWithContext(ctx, reserved, strings.NewReader("x")),
+		"metadata": storage.PutWithMetadata(ctx, reserved, strings.NewReader("x"), nil),
+		"delete":   storage.DeleteWithContext(ctx, reserved),
+		"append":   storage.Append(ctx, reserved, strings.NewReader("x")),
+		"compose":  storage.Compose(ctx, "copy", reserved),
+		"update":   storage.UpdateMetadata(ctx, reserved, &common.Metadata{}),
+	}
+	_, checks["get"] = storage.GetWithContext(ctx, reserved)
+	_, checks["range"] = storage.GetRange(ctx, reserved, 0, 1)
+	_, checks["stat"] = storage.GetMetadata(ctx, reserved)
+	_, checks["exists"] = storage.Exists(ctx, reserved)
+	for name, err := range checks {
+		if !errors.Is(err, common.ErrReservedKey) || !errors.Is(err, common.ErrPermissionDenied) {
+			t.Errorf("%s: expected common.ErrReservedKey, got %v", name, err)
+		}
+	}
+
+	keys, err := storage.ListWithContext(ctx, "")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(keys) != 1 || keys[0] != "data" {
+		t.Errorf("expected only data to be listed, got %v", keys)
+	}
+
+	result, err := storage.ListWithOptions(ctx, &common.ListOptions{Delimiter: "/"})
+	if err != nil {
+		t.Fatalf("ListWithOptions: %v", err)
+	}
+	if len(result.Objects) != 1 || len(result.CommonPrefixes) != 0 {
+		t.Errorf("expected lock prefix to be hidden, got %+v %v", result.Objects, result.CommonPrefixes)
+	}
+}
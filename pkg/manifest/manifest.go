@@ -0,0 +1,520 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package manifest groups objects into named, versioned datasets. A
+// manifest lists member keys pinned to the ETag and size they had when
+// added. Members are curated in a mutable draft and published as immutable,
+// numbered versions; publishing swaps the manifest's current version in one
+// write, so readers see either the old dataset or the new one. A version's
+// members can be streamed as a tar archive.
+//
+// Manifests are stored as JSON objects under a reserved prefix
+// (DefaultPrefix) of the backend holding their members. On backends
+// implementing common.ConditionalWriter, concurrent updates and publishes
+// are detected and fail with ErrConflict; elsewhere the last writer wins.
+package manifest
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// DefaultPrefix is the key prefix manifests are stored under when none is
+// configured.
+const DefaultPrefix = ".manifests/"
+
+// MaxMembers bounds the number of members of a manifest.
+const MaxMembers = 100000
+
+// maxAttempts bounds retries of a draft update that lost a race.
+const maxAttempts = 3
+
+// Object names under a manifest's prefix.
+const (
+	draftObject    = "draft.json"
+	currentObject  = "current.json"
+	versionsPrefix = "versions/"
+)
+
+var (
+	// ErrManifestNotFound is returned for a manifest without a draft, or
+	// without a published version when one is needed.
+	ErrManifestNotFound = fmt.Errorf("manifest %w", common.ErrNotFound)
+
+	// ErrVersionNotFound is returned for an unknown manifest version.
+	ErrVersionNotFound = fmt.Errorf("manifest version %w", common.ErrNotFound)
+
+	// ErrManifestExists is returned when creating a manifest that exists.
+	ErrManifestExists = fmt.Errorf("manifest %w", common.ErrAlreadyExists)
+
+	// ErrInvalidName is returned for names that are not 1 to 128 letters,
+	// digits, '.', '_' or '-'.
+	ErrInvalidName = fmt.Errorf("%w: manifest names must be 1 to 128 letters, digits, '.', '_' or '-'", common.ErrInvalidArgument)
+
+	// ErrTooManyMembers is returned when a manifest would exceed MaxMembers.
+	ErrTooManyMembers = fmt.Errorf("%w: manifest exceeds %d members", common.ErrInvalidArgument, MaxMembers)
+
+	// ErrEmptyManifest is returned when publishing a draft without members.
+	ErrEmptyManifest = fmt.Errorf("%w: manifest has no members", common.ErrInvalidArgument)
+
+	// ErrConflict is returned when a draft or the current version changed
+	// while it was being updated or published.
+	ErrConflict = fmt.Errorf("%w: manifest changed concurrently", common.ErrPreconditionFailed)
+
+	// ErrMemberChanged is returned when publishing or archiving a manifest
+	// whose member was changed or removed since it was added.
+	ErrMemberChanged = fmt.Errorf("%w: manifest member changed since it was added", common.ErrPreconditionFailed)
+)
+
+var validName = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// Member is an object in a manifest, pinned to the ETag and size it had
+// when added.
+type Member struct {
+	Key  string `json:"key"`
+	ETag string `json:"etag,omitempty"`
+	Size int64  `json:"size"`
+}
+
+// Manifest is a named set of objects. Drafts have version 0; published
+// versions are numbered from 1 and never change.
+type Manifest struct {
+	Name        string    `json:"name"`
+	Version     int       `json:"version,omitempty"`
+	Members     []Member  `json:"members"`
+	Size        int64     `json:"size"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	PublishedBy string    `json:"published_by,omitempty"`
+}
+
+// Manager creates, edits and publishes manifests.
+type Manager struct {
+	// members reads member objects; store holds the manifests.
+	members common.Storage
+	store   common.Storage
+	writer  common.ConditionalWriter
+	prefix  string
+	now     func() time.Time
+}
+
+// NewManager returns a Manager for manifests of objects in storage, stored
+// under prefix, or DefaultPrefix if prefix is empty. Manifests are written
+// to the first backend in storage's chain of wrapped backends that supports
+// conditional writes, or to storage itself if none does.
+func NewManager(storage common.Storage, prefix string) (*Manager, error) {
+	if prefix == "" {
+		prefix = DefaultPrefix
+	}
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	if err := common.ValidateKey(prefix + draftObject); err != nil {
+		return nil, fmt.Errorf("invalid manifest prefix %q: %w", prefix, err)
+	}
+
+	m := &Manager{members: storage, store: storage, prefix: prefix, now: time.Now}
+	for s := storage; s != nil; {
+		if writer, ok := s.(common.ConditionalWriter); ok {
+			m.store, m.writer = s, writer
+			break
+		}
+		wrapper, ok := s.(interface{ Underlying() common.Storage })
+		if !ok {
+			break
+		}
+		s = wrapper.Underlying()
+	}
+	return m, nil
+}
+
+// Prefix returns the key prefix manifests are stored under.
+func (m *Manager) Prefix() string {
+	return m.prefix
+}
+
+// Reserved reports whether key is in the manifest namespace.
+func (m *Manager) Reserved(key string) bool {
+	return common.IsReserved(key, m.prefix)
+}
+
+// Create creates the draft of a new manifest with keys as its members.
+func (m *Manager) Create(ctx context.Context, name string, keys ...string) (*Manifest, error) {
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+	if _, _, err := m.read(ctx, m.key(name, currentObject)); err == nil {
+		return nil, fmt.Errorf("%w: %s", ErrManifestExists, name)
+	} else if !errors.Is(err, common.ErrNotFound) {
+		return nil, err
+	}
+
+	draft := &Manifest{Name: name, Members: []Member{}}
+	if err := m.add(ctx, draft, keys); err != nil {
+		return nil, err
+	}
+	draft.UpdatedAt = m.now().UTC()
+	if err := m.write(ctx, m.key(name, draftObject), draft, ""); err != nil {
+		if errors.Is(err, ErrConflict) {
+			return nil, fmt.Errorf("%w: %s", ErrManifestExists, name)
+		}
+		return nil, err
+	}
+	return draft, nil
+}
+
+// Add adds keys to a manifest's draft, pinned to their current ETag and
+// size. Keys already in the draft are re-pinned.
+func (m *Manager) Add(ctx context.Context, name string, keys ...string) (*Manifest, error) {
+	return m.updateDraft(ctx, name, func(draft *Manifest) error {
+		return m.add(ctx, draft, keys)
+	})
+}
+
+// Remove removes keys from a manifest's draft. Keys that are not members
+// are ignored.
+func (m *Manager) Remove(ctx context.Context, name string, keys ...string) (*Manifest, error) {
+	remove := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		remove[key] = true
+	}
+	return m.updateDraft(ctx, name, func(draft *Manifest) error {
+		members := draft.Members[:0]
+		for _, member := range draft.Members {
+			if !remove[member.Key] {
+				members = append(members, member)
+			}
+		}
+		draft.Members = members
+		return nil
+	})
+}
+
+// Draft returns a manifest's draft.
+func (m *Manager) Draft(ctx context.Context, name string) (*Manifest, error) {
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+	draft, _, err := m.read(ctx, m.key(name, draftObject))
+	if errors.Is(err, common.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrManifestNotFound, name)
+	}
+	return draft, err
+}
+
+// Current returns the published version of a manifest.
+func (m *Manager) Current(ctx context.Context, name string) (*Manifest, error) {
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+	current, _, err := m.read(ctx, m.key(name, currentObject))
+	if errors.Is(err, common.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %s has no published version", ErrManifestNotFound, name)
+	}
+	return current, err
+}
+
+// Version returns a published version of a manifest.
+func (m *Manager) Version(ctx context.Context, name string, version int) (*Manifest, error) {
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+	manifest, _, err := m.read(ctx, m.versionKey(name, version))
+	if errors.Is(err, common.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %s version %d", ErrVersionNotFound, name, version)
+	}
+	return manifest, err
+}
+
+// Versions returns the published version numbers of a manifest in
+// ascending order.
+func (m *Manager) Versions(ctx context.Context, name string) ([]int, error) {
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+	prefix := m.key(name, versionsPrefix)
+	keys, err := m.store.ListWithContext(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]int, 0, len(keys))
+	for _, key := range keys {
+		if version, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(key, prefix), ".json")); err == nil {
+			versions = append(versions, version)
+		}
+	}
+	sort.Ints(versions)
+	return versions, nil
+}
+
+// List returns the names of all manifests in ascending order.
+func (m *Manager) List(ctx context.Context) ([]string, error) {
+	keys, err := m.store.ListWithContext(ctx, m.prefix)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	for _, key := range keys {
+		name, rest, ok := strings.Cut(strings.TrimPrefix(key, m.prefix), "/")
+		if ok && (rest == draftObject || rest == currentObject) {
+			seen[name] = true
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Publish publishes a manifest's draft as its next version and makes it
+// current. Members must be unchanged since they were added.
+func (m *Manager) Publish(ctx context.Context, name string) (*Manifest, error) {
+	draft, err := m.Draft(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(draft.Members) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrEmptyManifest, name)
+	}
+	if err := m.verify(ctx, draft); err != nil {
+		return nil, err
+	}
+
+	current, currentETag, err := m.read(ctx, m.key(name, currentObject))
+	version := 1
+	switch {
+	case err == nil:
+		version = current.Version + 1
+	case !errors.Is(err, common.ErrNotFound):
+		return nil, err
+	}
+
+	published := *draft
+	published.Version = version
+	published.UpdatedAt = m.now().UTC()
+	published.PublishedBy = adapters.Identity(ctx)
+
+	// Claiming the version number first makes concurrent publishes of the
+	// same version fail here rather than overwrite each other.
+	if err := m.write(ctx, m.versionKey(name, version), &published, ""); err != nil {
+		return nil, err
+	}
+	if err := m.write(ctx, m.key(name, currentObject), &published, currentETag); err != nil {
+		return nil, err
+	}
+	return &published, nil
+}
+
+// WriteTar writes the members of manifest to w as a tar archive, in key
+// order. Every member is checked against its pinned ETag before anything
+// is written; a member changed while the archive is written fails it.
+func (m *Manager) WriteTar(ctx context.Context, manifest *Manifest, w io.Writer) error {
+	if err := m.verify(ctx, manifest); err != nil {
+		return err
+	}
+
+	tw := tar.NewWriter(w)
+	for _, member := range manifest.Members {
+		if err := m.writeMember(ctx, tw, member); err != nil {
+			return err
+		}
+	}
+	return tw.Close()
+}
+
+func (m *Manager) writeMember(ctx context.Context, tw *tar.Writer, member Member) error {
+	rc, err := m.members.GetWithContext(ctx, member.Key)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrMemberChanged, member.Key, err)
+	}
+	defer func() { _ = rc.Close() }()
+
+	if err := tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     member.Key,
+		Size:     member.Size,
+		Mode:     0o644,
+		ModTime:  m.now().UTC(),
+		Format:   tar.FormatPAX,
+	}); err != nil {
+		return err
+	}
+	n, err := io.Copy(tw, io.LimitReader(rc, member.Size+1))
+	if err != nil {
+		if errors.Is(err, tar.ErrWriteTooLong) {
+			return fmt.Errorf("%w: %s grew", ErrMemberChanged, member.Key)
+		}
+		return err
+	}
+	if n != member.Size {
+		return fmt.Errorf("%w: %s is %d bytes, expected %d", ErrMemberChanged, member.Key, n, member.Size)
+	}
+	return nil
+}
+
+// verify checks that every member still has its pinned ETag.
+func (m *Manager) verify(ctx context.Context, manifest *Manifest) error {
+	for _, member := range manifest.Members {
+		metadata, err := m.members.GetMetadata(ctx, member.Key)
+		if errors.Is(err, common.ErrNotFound) {
+			return fmt.Errorf("%w: %s was deleted", ErrMemberChanged, member.Key)
+		}
+		if err != nil {
+			return err
+		}
+		if metadata.ETag != member.ETag || metadata.Size != member.Size {
+			return fmt.Errorf("%w: %s", ErrMemberChanged, member.Key)
+		}
+	}
+	return nil
+}
+
+// add pins keys and merges them into manifest's members.
+func (m *Manager) add(ctx context.Context, manifest *Manifest, keys []string) error {
+	members := make(map[string]Member, len(manifest.Members)+len(keys))
+	for _, member := range manifest.Members {
+		members[member.Key] = member
+	}
+	for _, key := range keys {
+		if err := common.ValidateKey(key); err != nil {
+			return err
+		}
+		if m.Reserved(key) {
+			return fmt.Errorf("%w: %s", common.ErrReservedKey, key)
+		}
+		metadata, err := m.members.GetMetadata(ctx, key)
+		if err != nil {
+			return fmt.Errorf("failed to add %s: %w", key, err)
+		}
+		members[key] = Member{Key: key, ETag: metadata.ETag, Size: metadata.Size}
+	}
+	if len(members) > MaxMembers {
+		return ErrTooManyMembers
+	}
+
+	manifest.Members = make([]Member, 0, len(members))
+	manifest.Size = 0
+	for _, member := range members {
+		manifest.Members = append(manifest.Members, member)
+		manifest.Size += member.Size
+	}
+	sort.Slice(manifest.Members, func(i, j int) bool {
+		return manifest.Members[i].Key < manifest.Members[j].Key
+	})
+	return nil
+}
+
+// updateDraft applies fn to a manifest's draft and stores it, retrying if
+// the draft changed in between.
+func (m *Manager) updateDraft(ctx context.Context, name string, fn func(*Manifest) error) (*Manifest, error) {
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+	key := m.key(name, draftObject)
+	for attempt := 0; ; attempt++ {
+		draft, etag, err := m.read(ctx, key)
+		if errors.Is(err, common.ErrNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrManifestNotFound, name)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := fn(draft); err != nil {
+			return nil, err
+		}
+		draft.Size = 0
+		for _, member := range draft.Members {
+			draft.Size += member.Size
+		}
+		draft.UpdatedAt = m.now().UTC()
+
+		err = m.write(ctx, key, draft, etag)
+		if err == nil {
+			return draft, nil
+		}
+		if !errors.Is(err, ErrConflict) || attempt+1 == maxAttempts {
+			return nil, err
+		}
+	}
+}
+
+// read returns the manifest stored under key and the ETag of its object.
+func (m *Manager) read(ctx context.Context, key string) (*Manifest, string, error) {
+	metadata, err := m.store.GetMetadata(ctx, key)
+	if err != nil {
+		return nil, "", err
+	}
+	rc, err := m.store.GetWithContext(ctx, key)
+	if err != nil {
+		return nil, "", err
+	}
+	defer func() { _ = rc.Close() }()
+
+	var manifest Manifest
+	if err := json.NewDecoder(rc).Decode(&manifest); err != nil {
+		return nil, "", fmt.Errorf("failed to decode manifest %s: %w", key, err)
+	}
+	if manifest.Members == nil {
+		manifest.Members = []Member{}
+	}
+	return &manifest, metadata.ETag, nil
+}
+
+// write stores manifest under key. On backends with conditional writes it
+// requires the object to have etag, or not to exist if etag is empty, and
+// fails with ErrConflict otherwise.
+func (m *Manager) write(ctx context.Context, key string, manifest *Manifest, etag string) error {
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	metadata := &common.Metadata{ContentType: "application/json"}
+	if m.writer == nil {
+		return m.store.PutWithMetadata(ctx, key, bytes.NewReader(data), metadata)
+	}
+
+	err = m.writer.PutIfMatch(ctx, key, bytes.NewReader(data), metadata, etag)
+	if errors.Is(err, common.ErrPreconditionFailed) || errors.Is(err, common.ErrAlreadyExists) {
+		return fmt.Errorf("%w: %s", ErrConflict, strings.TrimPrefix(key, m.prefix))
+	}
+	return err
+}
+
+func (m *Manager) key(name, object string) string {
+	return m.prefix + name + "/" + object
+}
+
+func (m *Manager) versionKey(name string, version int) string {
+	return fmt.Sprintf("%s%s/%s%010d.json", m.prefix, name, versionsPrefix, version)
+}
+
+func validateName(name string) error {
+	if !validName.MatchString(name) || name == "." || name == ".." {
+		return fmt.Errorf("%w: %q", ErrInvalidName, name)
+	}
+	return nil
+}
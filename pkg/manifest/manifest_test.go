@@ -0,0 +1,244 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package manifest
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+func newTestManager(t *testing.T, objects map[string]string) (*Manager, common.Storage) {
+	t.Helper()
+	backend := memory.New()
+	for key, data := range objects {
+		if err := backend.Put(key, strings.NewReader(data)); err != nil {
+			t.Fatalf("Put(%s): %v", key, err)
+		}
+	}
+	manager, err := NewManager(backend, "")
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	return manager, backend
+}
+
+func memberKeys(m *Manifest) []string {
+	keys := make([]string, len(m.Members))
+	for i, member := range m.Members {
+		keys[i] = member.Key
+	}
+	return keys
+}
+
+func TestDraftMembers(t *testing.T) {
+	ctx := context.Background()
+	manager, _ := newTestManager(t, map[string]string{"a": "1", "b": "22", "c": "333"})
+
+	draft, err := manager.Create(ctx, "train", "b", "a")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if got := strings.Join(memberKeys(draft), ","); got != "a,b" || draft.Size != 3 {
+		t.Fatalf("draft = %s size %d, want a,b size 3", got, draft.Size)
+	}
+	if draft.Members[1].ETag == "" {
+		t.Error("member ETag not pinned")
+	}
+	if _, err := manager.Create(ctx, "train"); !errors.Is(err, ErrManifestExists) {
+		t.Errorf("Create existing = %v, want ErrManifestExists", err)
+	}
+
+	if _, err := manager.Add(ctx, "train", "c"); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	draft, err = manager.Remove(ctx, "train", "a", "missing")
+	if err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if got := strings.Join(memberKeys(draft), ","); got != "b,c" || draft.Size != 5 {
+		t.Errorf("draft = %s size %d, want b,c size 5", got, draft.Size)
+	}
+
+	if _, err := manager.Add(ctx, "train", "missing"); !errors.Is(err, common.ErrNotFound) {
+		t.Errorf("Add missing = %v, want ErrNotFound", err)
+	}
+	if _, err := manager.Add(ctx, "train", DefaultPrefix+"x"); !errors.Is(err, common.ErrReservedKey) {
+		t.Errorf("Add reserved = %v, want common.ErrReservedKey", err)
+	}
+	if _, err := manager.Add(ctx, "other", "a"); !errors.Is(err, ErrManifestNotFound) {
+		t.Errorf("Add to unknown manifest = %v, want ErrManifestNotFound", err)
+	}
+	for _, name := range []string{"", "..", "a/b", strings.Repeat("x", 129)} {
+		if _, err := manager.Create(ctx, name); !errors.Is(err, ErrInvalidName) {
+			t.Errorf("Create(%q) = %v, want ErrInvalidName", name, err)
+		}
+	}
+}
+
+func TestPublish(t *testing.T) {
+	ctx := context.WithValue(context.Background(), adapters.PrincipalContextKey{}, &adapters.Principal{ID: "ci"})
+	manager, backend := newTestManager(t, map[string]string{"a": "1", "b": "22"})
+
+	if _, err := manager.Create(ctx, "build"); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := manager.Publish(ctx, "build"); !errors.Is(err, ErrEmptyManifest) {
+		t.Errorf("Publish empty = %v, want ErrEmptyManifest", err)
+	}
+	if _, err := manager.Current(ctx, "build"); !errors.Is(err, ErrManifestNotFound) {
+		t.Errorf("Current unpublished = %v, want ErrManifestNotFound", err)
+	}
+
+	if _, err := manager.Add(ctx, "build", "a"); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	v1, err := manager.Publish(ctx, "build")
+	if err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if v1.Version != 1 || v1.PublishedBy != "ci" {
+		t.Errorf("published version %d by %q, want 1 by ci", v1.Version, v1.PublishedBy)
+	}
+
+	// Later draft edits leave the published version alone.
+	if _, err := manager.Add(ctx, "build", "b"); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	current, err := manager.Current(ctx, "build")
+	if err != nil || len(current.Members) != 1 {
+		t.Fatalf("Current = %+v, %v; want version 1 with one member", current, err)
+	}
+	if _, err := manager.Publish(ctx, "build"); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	current, err = manager.Current(ctx, "build")
+	if err != nil || current.Version != 2 || len(current.Members) != 2 {
+		t.Fatalf("Current = %+v, %v; want version 2 with two members", current, err)
+	}
+	versions, err := manager.Versions(ctx, "build")
+	if err != nil || len(versions) != 2 || versions[0] != 1 || versions[1] != 2 {
+		t.Errorf("Versions = %v, %v; want [1 2]", versions, err)
+	}
+	if v, err := manager.Version(ctx, "build", 1); err != nil || len(v.Members) != 1 {
+		t.Errorf("Version(1) = %+v, %v", v, err)
+	}
+	if _, err := manager.Version(ctx, "build", 3); !errors.Is(err, ErrVersionNotFound) {
+		t.Errorf("Version(3) = %v, want ErrVersionNotFound", err)
+	}
+
+	// A member rewritten after it was added blocks publishing.
+	if err := backend.Put("b", strings.NewReader("changed")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if _, err := manager.Publish(ctx, "build"); !errors.Is(err, ErrMemberChanged) {
+		t.Errorf("Publish changed member = %v, want ErrMemberChanged", err)
+	}
+
+	names, err := manager.List(ctx)
+	if err != nil || len(names) != 1 || names[0] != "build" {
+		t.Errorf("List = %v, %v; want [build]", names, err)
+	}
+}
+
+func TestPublishConflict(t *testing.T) {
+	ctx := context.Background()
+	manager, _ := newTestManager(t, map[string]string{"a": "1"})
+	if _, err := manager.Create(ctx, "ds", "a"); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	// Another publisher claimed version 1 first.
+	if err := manager.write(ctx, manager.versionKey("ds", 1), &Manifest{Name: "ds", Version: 1}, ""); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := manager.Publish(ctx, "ds"); !errors.Is(err, ErrConflict) {
+		t.Errorf("Publish = %v, want ErrConflict", err)
+	}
+	if _, err := manager.Current(ctx, "ds"); !errors.Is(err, ErrManifestNotFound) {
+		t.Errorf("Current after conflict = %v, want ErrManifestNotFound", err)
+	}
+}
+
+func TestWriteTar(t *testing.T) {
+	ctx := context.Background()
+	manager, backend := newTestManager(t, map[string]string{"data/a.txt": "alpha", "data/b.txt": "beta"})
+	draft, err := manager.Create(ctx, "ds", "data/a.txt", "data/b.txt")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := manager.WriteTar(ctx, draft, &buf); err != nil {
+		t.Fatalf("WriteTar: %v", err)
+	}
+	tr := tar.NewReader(&buf)
+	var got []string
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next: %v", err)
+		}
+		data, _ := io.ReadAll(tr)
+		got = append(got, hdr.Name+"="+string(data))
+	}
+	if strings.Join(got, ",") != "data/a.txt=alpha,data/b.txt=beta" {
+		t.Errorf("archive = %v", got)
+	}
+
+	if err := backend.Delete("data/b.txt"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	buf.Reset()
+	if err := manager.WriteTar(ctx, draft, &buf); !errors.Is(err, ErrMemberChanged) {
+		t.Errorf("WriteTar with deleted member = %v, want ErrMemberChanged", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("WriteTar wrote %d bytes before failing verification", buf.Len())
+	}
+}
+
+func TestStorageReservesNamespace(t *testing.T) {
+	ctx := context.Background()
+	manager, backend := newTestManager(t, map[string]string{"a": "1"})
+	storage := NewStorage(backend, manager)
+	if _, err := manager.Create(ctx, "ds", "a"); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	key := DefaultPrefix + "ds/" + draftObject
+	if err := storage.Put(key, strings.NewReader("{}")); !errors.Is(err, common.ErrReservedKey) {
+		t.Errorf("Put reserved = %v, want common.ErrReservedKey", err)
+	}
+	if err := storage.Delete(key); !errors.Is(err, common.ErrReservedKey) {
+		t.Errorf("Delete reserved = %v, want common.ErrReservedKey", err)
+	}
+	if _, err := storage.GetMetadata(ctx, key); err != nil {
+		t.Errorf("GetMetadata reserved = %v, want manifests readable", err)
+	}
+	if err := storage.Put("b", strings.NewReader("2")); err != nil {
+		t.Errorf("Put outside namespace: %v", err)
+	}
+}
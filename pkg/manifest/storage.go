@@ -0,0 +1,144 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package manifest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// Storage wraps a backend and reserves a Manager's manifest namespace:
+// writes and deletes of keys under the manifest prefix fail with
+// common.ErrReservedKey, so manifests only change through the Manager. Manifests
+// stay readable and listable as plain JSON objects.
+type Storage struct {
+	common.Storage
+	manager *Manager
+}
+
+// NewStorage returns underlying wrapped so that manager's manifest prefix
+// is reserved.
+func NewStorage(underlying common.Storage, manager *Manager) *Storage {
+	return &Storage{Storage: underlying, manager: manager}
+}
+
+// Manager returns the manifest manager whose namespace s reserves.
+func (s *Storage) Manager() *Manager {
+	return s.manager
+}
+
+// Underlying returns the wrapped backend.
+func (s *Storage) Underlying() common.Storage {
+	return s.Storage
+}
+
+func (s *Storage) check(key string) error {
+	if s.manager.Reserved(key) {
+		return fmt.Errorf("%w: %s", common.ErrReservedKey, key)
+	}
+	return nil
+}
+
+// Put stores an object outside the manifest namespace.
+func (s *Storage) Put(key string, data io.Reader) error {
+	return s.PutWithContext(context.Background(), key, data)
+}
+
+// PutWithContext stores an object outside the manifest namespace.
+func (s *Storage) PutWithContext(ctx context.Context, key string, data io.Reader) error {
+	if err := s.check(key); err != nil {
+		return err
+	}
+	return s.Storage.PutWithContext(ctx, key, data)
+}
+
+// PutWithMetadata stores an object with metadata outside the manifest
+// namespace.
+func (s *Storage) PutWithMetadata(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) error {
+	if err := s.check(key); err != nil {
+		return err
+	}
+	return s.Storage.PutWithMetadata(ctx, key, data, metadata)
+}
+
+// PutWithInfo stores an object with metadata outside the manifest namespace
+// and returns what was stored.
+func (s *Storage) PutWithInfo(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) (*common.ObjectInfo, error) {
+	if err := s.check(key); err != nil {
+		return nil, err
+	}
+	return common.PutWithInfo(ctx, s.Storage, key, data, metadata)
+}
+
+// GetRange reads a byte range of an object, falling back to discarding
+// the leading bytes of a full read when the wrapped backend cannot read
+// ranges.
+func (s *Storage) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	if rr, ok := s.Storage.(common.RangeReader); ok {
+		return rr.GetRange(ctx, key, offset, length)
+	}
+	rc, err := s.Storage.GetWithContext(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return common.SliceRange(rc, offset, length)
+}
+
+// GetAsOf reads an earlier version of an object from the wrapped backend.
+func (s *Storage) GetAsOf(ctx context.Context, key string, at time.Time) (io.ReadCloser, *common.Metadata, error) {
+	return common.GetAsOf(ctx, s.Storage, key, at)
+}
+
+// UpdateMetadata updates the metadata of an object outside the manifest
+// namespace.
+func (s *Storage) UpdateMetadata(ctx context.Context, key string, metadata *common.Metadata) error {
+	if err := s.check(key); err != nil {
+		return err
+	}
+	return s.Storage.UpdateMetadata(ctx, key, metadata)
+}
+
+// Delete removes an object outside the manifest namespace.
+func (s *Storage) Delete(key string) error {
+	return s.DeleteWithContext(context.Background(), key)
+}
+
+// DeleteWithContext removes an object outside the manifest namespace.
+func (s *Storage) DeleteWithContext(ctx context.Context, key string) error {
+	if err := s.check(key); err != nil {
+		return err
+	}
+	return s.Storage.DeleteWithContext(ctx, key)
+}
+
+// Append adds data to the end of an object outside the manifest namespace.
+func (s *Storage) Append(ctx context.Context, key string, data io.Reader) error {
+	if err := s.check(key); err != nil {
+		return err
+	}
+	return common.Append(ctx, s.Storage, key, data)
+}
+
+// Compose concatenates srcKeys into destKey, which must be outside the
+// manifest namespace.
+func (s *Storage) Compose(ctx context.Context, destKey string, srcKeys ...string) error {
+	if err := s.check(destKey); err != nil {
+		return err
+	}
+	return common.Compose(ctx, s.Storage, destKey, srcKeys...)
+}
@@ -14,6 +14,7 @@
 package memory
 
 import (
+	"context"
 	"strings"
 	"sync"
 	"time"
@@ -22,8 +23,9 @@ import (
 )
 
 const (
-	actionDelete  = "delete"
-	actionArchive = "archive"
+	actionDelete     = "delete"
+	actionArchive    = "archive"
+	actionTransition = common.ActionTransition
 )
 
 // LifecycleManager is an in-memory lifecycle manager for the memory storage backend.
@@ -85,6 +87,10 @@ func (lm *LifecycleManager) Process(storage *Memory) {
 	}
 	lm.mutex.RUnlock()
 
+	// Objects past their recorded expiry are deleted whatever the policies
+	// say. Failures are retried on the next pass.
+	_, _ = common.DeleteExpired(context.Background(), storage, time.Now())
+
 	for _, policy := range policies {
 		// Get all keys matching the prefix
 		storage.mu.RLock()
@@ -107,6 +113,8 @@ func (lm *LifecycleManager) Process(storage *Memory) {
 				if policy.Destination != nil {
 					_ = storage.Archive(key, policy.Destination)
 				}
+			case actionTransition:
+				storage.setStorageClass(key, policy.StorageClass)
 			}
 		}
 	}
@@ -20,12 +20,14 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"maps"
 	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/delta"
 )
 
 // object represents a stored object with its data and metadata.
@@ -72,20 +74,38 @@ func (m *Memory) validateKey(key string) error {
 
 // PutWithMetadata stores an object with associated metadata.
 func (m *Memory) PutWithMetadata(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) error {
+	_, err := m.put(ctx, key, data, metadata)
+	return err
+}
+
+// PutWithInfo stores an object with associated metadata and returns the
+// metadata recorded for it.
+func (m *Memory) PutWithInfo(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) (*common.ObjectInfo, error) {
+	stored, err := m.put(ctx, key, data, metadata)
+	if err != nil {
+		return nil, err
+	}
+	info := *stored
+	info.Custom = maps.Clone(stored.Custom)
+	return &common.ObjectInfo{Key: key, Metadata: &info}, nil
+}
+
+// put stores an object and returns the metadata stored with it.
+func (m *Memory) put(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) (*common.Metadata, error) {
 	if err := m.validateKey(key); err != nil {
-		return err
+		return nil, err
 	}
 
 	select {
 	case <-ctx.Done():
-		return ctx.Err()
+		return nil, ctx.Err()
 	default:
 	}
 
 	// Read all data from the reader
 	dataBytes, err := io.ReadAll(data)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Create or update metadata
@@ -103,7 +123,7 @@ func (m *Memory) PutWithMetadata(ctx context.Context, key string, data io.Reader
 	}
 	m.mu.Unlock()
 
-	return nil
+	return metadata, nil
 }
 
 // Get retrieves an object from the backend.
@@ -138,6 +158,144 @@ func (m *Memory) GetWithContext(ctx context.Context, key string) (io.ReadCloser,
 	return io.NopCloser(bytes.NewReader(dataCopy)), nil
 }
 
+// GetRange retrieves length bytes of an object starting at offset. A negative
+// length reads to the end of the object.
+func (m *Memory) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	if err := m.validateKey(key); err != nil {
+		return nil, err
+	}
+	if offset < 0 {
+		return nil, fmt.Errorf("%w: negative range offset %d", common.ErrInvalidArgument, offset)
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	obj, exists := m.objects[key]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", common.ErrKeyNotFound, key)
+	}
+
+	size := int64(len(obj.data))
+	start := min(offset, size)
+	end := size
+	if length >= 0 && start+length < size {
+		end = start + length
+	}
+	dataCopy := make([]byte, end-start)
+	copy(dataCopy, obj.data[start:end])
+
+	return io.NopCloser(bytes.NewReader(dataCopy)), nil
+}
+
+// ApplyDelta rebuilds the object stored under key from its current version
+// and ops, reading only the literal ranges from src.
+func (m *Memory) ApplyDelta(ctx context.Context, key, baseETag string, ops []delta.Op, src delta.Source, metadata *common.Metadata) error {
+	if err := m.validateKey(key); err != nil {
+		return err
+	}
+
+	m.mu.RLock()
+	obj, exists := m.objects[key]
+	m.mu.RUnlock()
+	if !exists {
+		return fmt.Errorf("%w: %s", common.ErrKeyNotFound, key)
+	}
+	if baseETag != "" && obj.metadata != nil && obj.metadata.ETag != baseETag {
+		return fmt.Errorf("%w: %s", delta.ErrBaseChanged, key)
+	}
+
+	// Stored data is never modified in place, so the old slice stays valid
+	// while the new version is assembled.
+	rc := delta.NewReader(ctx, bytes.NewReader(obj.data), ops, src)
+	defer func() { _ = rc.Close() }()
+	return m.PutWithMetadata(ctx, key, rc, metadata)
+}
+
+// Append adds data to the end of the object stored under key, creating it if
+// it does not exist. The object's metadata is kept.
+func (m *Memory) Append(ctx context.Context, key string, data io.Reader) error {
+	if err := m.validateKey(key); err != nil {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	tail, err := io.ReadAll(data)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	metadata := &common.Metadata{}
+	var head []byte
+	if obj, exists := m.objects[key]; exists {
+		metadataCopy := *obj.metadata
+		metadata = &metadataCopy
+		head = obj.data
+	}
+	m.objects[key] = m.newObject(metadata, head, tail)
+	return nil
+}
+
+// Compose writes the concatenation of srcKeys to destKey in a single step.
+// The result takes its metadata from the first source.
+func (m *Memory) Compose(ctx context.Context, destKey string, srcKeys ...string) error {
+	if err := common.ValidateComposeKeys(destKey, srcKeys); err != nil {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	parts := make([][]byte, 0, len(srcKeys))
+	for _, key := range srcKeys {
+		obj, exists := m.objects[key]
+		if !exists {
+			return fmt.Errorf("%w: %s", common.ErrKeyNotFound, key)
+		}
+		parts = append(parts, obj.data)
+	}
+	metadata := *m.objects[srcKeys[0]].metadata
+	m.objects[destKey] = m.newObject(&metadata, parts...)
+	return nil
+}
+
+// newObject builds an object from the concatenation of parts, refreshing
+// the size, ETag and modification time in metadata. Stored data is never
+// modified in place, so parts are always copied.
+func (m *Memory) newObject(metadata *common.Metadata, parts ...[]byte) *object {
+	data := bytes.Join(parts, nil)
+	if metadata.Custom != nil {
+		custom := make(map[string]string, len(metadata.Custom))
+		for k, v := range metadata.Custom {
+			custom[k] = v
+		}
+		metadata.Custom = custom
+	}
+	metadata.Size = int64(len(data))
+	metadata.LastModified = time.Now()
+	metadata.ETag = fmt.Sprintf("%d-%d", metadata.LastModified.Unix(), metadata.Size)
+	return &object{data: data, metadata: metadata}
+}
+
 // GetMetadata retrieves only the metadata for an object.
 func (m *Memory) GetMetadata(ctx context.Context, key string) (*common.Metadata, error) {
 	if err := m.validateKey(key); err != nil {
@@ -230,6 +388,153 @@ func (m *Memory) DeleteWithContext(ctx context.Context, key string) error {
 	return nil
 }
 
+// ProcessLocal reports true: objects live in this process's memory.
+func (m *Memory) ProcessLocal() bool {
+	return true
+}
+
+// PutIfMatch stores an object only if its current ETag is etag, or only if
+// it does not exist when etag is empty.
+func (m *Memory) PutIfMatch(ctx context.Context, key string, data io.Reader, metadata *common.Metadata, etag string) error {
+	if err := m.validateKey(key); err != nil {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	dataBytes, err := io.ReadAll(data)
+	if err != nil {
+		return err
+	}
+	if metadata == nil {
+		metadata = &common.Metadata{}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.checkETagLocked(key, etag); err != nil {
+		return err
+	}
+	m.objects[key] = m.newObject(metadata, dataBytes)
+	return nil
+}
+
+// DeleteIfMatch removes an object only if its current ETag is etag.
+func (m *Memory) DeleteIfMatch(ctx context.Context, key, etag string) error {
+	if err := m.validateKey(key); err != nil {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.objects[key]; !exists {
+		return fmt.Errorf("%w: %s", common.ErrKeyNotFound, key)
+	}
+	if err := m.checkETagLocked(key, etag); err != nil {
+		return err
+	}
+	delete(m.objects, key)
+	return nil
+}
+
+// checkETagLocked checks the precondition of a conditional write. An empty
+// etag requires the object not to exist.
+func (m *Memory) checkETagLocked(key, etag string) error {
+	obj, exists := m.objects[key]
+	switch {
+	case etag == "" && exists:
+		return fmt.Errorf("%w: %s already exists", common.ErrPreconditionFailed, key)
+	case etag != "" && (!exists || obj.metadata == nil || obj.metadata.ETag != etag):
+		return fmt.Errorf("%w: %s does not match ETag %s", common.ErrPreconditionFailed, key, etag)
+	}
+	return nil
+}
+
+// ApplyBatch applies ops atomically if every precondition holds. The
+// changes are staged against a view of the touched objects and only
+// published, under the write lock, once all of them succeed.
+func (m *Memory) ApplyBatch(ctx context.Context, preconditions []common.BatchPrecondition, ops []common.BatchOp) error {
+	for _, pre := range preconditions {
+		if err := m.validateKey(pre.Key); err != nil {
+			return err
+		}
+	}
+	for _, op := range ops {
+		if err := m.validateKey(op.Key); err != nil {
+			return err
+		}
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, pre := range preconditions {
+		if err := m.checkETagLocked(pre.Key, pre.ETag); err != nil {
+			return err
+		}
+	}
+
+	// staged maps each touched key to its new object, or nil once deleted.
+	staged := make(map[string]*object, len(ops))
+	current := func(key string) *object {
+		if obj, ok := staged[key]; ok {
+			return obj
+		}
+		return m.objects[key]
+	}
+	for _, op := range ops {
+		metadata := &common.Metadata{}
+		if op.Metadata != nil {
+			copied := *op.Metadata
+			metadata = &copied
+		}
+		switch op.Type {
+		case common.BatchPut:
+			staged[op.Key] = m.newObject(metadata, op.Data)
+		case common.BatchDelete:
+			if current(op.Key) == nil {
+				return fmt.Errorf("%w: %s", common.ErrKeyNotFound, op.Key)
+			}
+			staged[op.Key] = nil
+		case common.BatchUpdateMetadata:
+			obj := current(op.Key)
+			if obj == nil {
+				return fmt.Errorf("%w: %s", common.ErrKeyNotFound, op.Key)
+			}
+			staged[op.Key] = m.newObject(metadata, obj.data)
+		default:
+			return fmt.Errorf("%w: unknown batch operation %d", common.ErrInvalidArgument, op.Type)
+		}
+	}
+
+	for key, obj := range staged {
+		if obj == nil {
+			delete(m.objects, key)
+		} else {
+			m.objects[key] = obj
+		}
+	}
+	return nil
+}
+
 // Exists checks if an object exists in the backend.
 func (m *Memory) Exists(ctx context.Context, key string) (bool, error) {
 	if err := m.validateKey(key); err != nil {
@@ -418,6 +723,16 @@ func (m *Memory) GetPolicies() ([]common.LifecyclePolicy, error) {
 	return m.lifecycleManager.GetPolicies()
 }
 
+// setStorageClass records class in the metadata of an existing object. The
+// memory backend has a single tier, so the class is informational only.
+func (m *Memory) setStorageClass(key, class string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if obj, ok := m.objects[key]; ok && obj.metadata != nil {
+		obj.metadata.StorageClass = class
+	}
+}
+
 // Clear removes all objects from the storage. This is useful for testing.
 func (m *Memory) Clear() {
 	m.mu.Lock()
@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/delta"
 )
 
 func TestNew(t *testing.T) {
@@ -132,6 +133,23 @@ func TestPutWithMetadata(t *testing.T) {
 	}
 }
 
+func TestPutWithInfo(t *testing.T) {
+	storage := New().(*Memory)
+	ctx := context.Background()
+
+	info, err := storage.PutWithInfo(ctx, "info-key", bytes.NewReader([]byte("info")), &common.Metadata{ContentType: "text/plain"})
+	if err != nil {
+		t.Fatalf("PutWithInfo() returned error: %v", err)
+	}
+	stored, err := storage.GetMetadata(ctx, "info-key")
+	if err != nil {
+		t.Fatalf("GetMetadata() returned error: %v", err)
+	}
+	if info.Key != "info-key" || info.Metadata.ETag != stored.ETag || info.Metadata.Size != 4 || !info.Metadata.LastModified.Equal(stored.LastModified) {
+		t.Fatalf("PutWithInfo() = %+v, want the stored metadata %+v", info.Metadata, stored)
+	}
+}
+
 func TestGetNotFound(t *testing.T) {
 	storage := New()
 	_ = storage.Configure(nil)
@@ -786,6 +804,47 @@ func TestLifecycleManagerProcessArchive(t *testing.T) {
 	}
 }
 
+func TestLifecycleManagerProcessTransition(t *testing.T) {
+	mem := &Memory{
+		objects:          make(map[string]*object),
+		lifecycleManager: NewLifecycleManager(),
+	}
+
+	lm := NewLifecycleManager()
+
+	err := mem.PutWithContext(context.Background(), "reports/old.txt", bytes.NewReader([]byte("old data")))
+	if err != nil {
+		t.Fatalf("PutWithContext() returned error: %v", err)
+	}
+
+	mem.mu.Lock()
+	if obj, ok := mem.objects["reports/old.txt"]; ok {
+		obj.metadata.LastModified = time.Now().Add(-48 * time.Hour)
+	}
+	mem.mu.Unlock()
+
+	policy := common.LifecyclePolicy{
+		ID:           "cool-reports",
+		Prefix:       "reports/",
+		Action:       common.ActionTransition,
+		Retention:    time.Hour,
+		StorageClass: "COOL",
+	}
+	if err := lm.AddPolicy(policy); err != nil {
+		t.Fatalf("AddPolicy() returned error: %v", err)
+	}
+
+	lm.Process(mem)
+
+	metadata, err := mem.GetMetadata(context.Background(), "reports/old.txt")
+	if err != nil {
+		t.Fatalf("GetMetadata() returned error: %v", err)
+	}
+	if metadata.StorageClass != "COOL" {
+		t.Errorf("Process() should have set storage class COOL, got %q", metadata.StorageClass)
+	}
+}
+
 func TestLifecycleManagerProcessNoPolicies(t *testing.T) {
 	mem := &Memory{
 		objects:          make(map[string]*object),
@@ -845,3 +904,222 @@ func TestLifecycleManagerProcessObjectNotOldEnough(t *testing.T) {
 		t.Error("Process() should NOT delete objects that are not old enough")
 	}
 }
+
+func TestGetRange(t *testing.T) {
+	storage := New()
+	_ = storage.Put("range-key", strings.NewReader("0123456789"))
+
+	rr, ok := storage.(common.RangeReader)
+	if !ok {
+		t.Fatal("memory storage does not implement common.RangeReader")
+	}
+
+	reader, err := rr.GetRange(context.Background(), "range-key", 2, 5)
+	if err != nil {
+		t.Fatalf("GetRange() returned error: %v", err)
+	}
+	data, _ := io.ReadAll(reader)
+	if string(data) != "23456" {
+		t.Errorf("GetRange() = %q, want %q", data, "23456")
+	}
+
+	reader, err = rr.GetRange(context.Background(), "range-key", 6, -1)
+	if err != nil {
+		t.Fatalf("GetRange() returned error: %v", err)
+	}
+	data, _ = io.ReadAll(reader)
+	if string(data) != "6789" {
+		t.Errorf("GetRange() = %q, want %q", data, "6789")
+	}
+
+	if _, err := rr.GetRange(context.Background(), "missing", 0, 1); !errors.Is(err, common.ErrKeyNotFound) {
+		t.Errorf("GetRange() error = %v, want ErrKeyNotFound", err)
+	}
+}
+
+func TestApplyDelta(t *testing.T) {
+	storage := New()
+	ctx := context.Background()
+	_ = storage.Put("delta-key", strings.NewReader("aaaabbbbcccc"))
+	meta, err := storage.GetMetadata(ctx, "delta-key")
+	if err != nil {
+		t.Fatalf("GetMetadata() returned error: %v", err)
+	}
+
+	patcher, ok := storage.(delta.Patcher)
+	if !ok {
+		t.Fatal("memory storage does not implement delta.Patcher")
+	}
+
+	target := []byte("bbbbaaaaZ")
+	ops := []delta.Op{
+		{Kind: delta.OpCopy, Offset: 4, Length: 4},
+		{Kind: delta.OpCopy, Offset: 0, Length: 4},
+		{Kind: delta.OpLiteral, Offset: 8, Length: 1},
+	}
+	if err := patcher.ApplyDelta(ctx, "delta-key", "stale", ops, delta.BytesSource(target), nil); !errors.Is(err, delta.ErrBaseChanged) {
+		t.Errorf("ApplyDelta() error = %v, want ErrBaseChanged", err)
+	}
+	if err := patcher.ApplyDelta(ctx, "delta-key", meta.ETag, ops, delta.BytesSource(target), nil); err != nil {
+		t.Fatalf("ApplyDelta() returned error: %v", err)
+	}
+
+	reader, err := storage.Get("delta-key")
+	if err != nil {
+		t.Fatalf("Get() returned error: %v", err)
+	}
+	data, _ := io.ReadAll(reader)
+	if string(data) != string(target) {
+		t.Errorf("ApplyDelta() produced %q, want %q", data, target)
+	}
+
+	if err := patcher.ApplyDelta(ctx, "missing", "", ops, delta.BytesSource(target), nil); !errors.Is(err, common.ErrKeyNotFound) {
+		t.Errorf("ApplyDelta() error = %v, want ErrKeyNotFound", err)
+	}
+}
+
+func TestAppendAndCompose(t *testing.T) {
+	ctx := context.Background()
+	storage := New()
+
+	appender, ok := storage.(common.Appender)
+	if !ok {
+		t.Fatal("memory storage does not implement common.Appender")
+	}
+	if err := appender.Append(ctx, "log", strings.NewReader("a")); err != nil {
+		t.Fatalf("Append() to missing key returned error: %v", err)
+	}
+	_ = storage.UpdateMetadata(ctx, "log", &common.Metadata{ContentType: "text/plain"})
+	if err := appender.Append(ctx, "log", strings.NewReader("bc")); err != nil {
+		t.Fatalf("Append() returned error: %v", err)
+	}
+	md, _ := storage.GetMetadata(ctx, "log")
+	if md.Size != 3 || md.ContentType != "text/plain" {
+		t.Errorf("metadata = %+v, want size 3 and content type kept", md)
+	}
+
+	composer, ok := storage.(common.Composer)
+	if !ok {
+		t.Fatal("memory storage does not implement common.Composer")
+	}
+	_ = storage.Put("other", strings.NewReader("xyz"))
+	if err := composer.Compose(ctx, "log", "log", "other", "log"); err != nil {
+		t.Fatalf("Compose() returned error: %v", err)
+	}
+	reader, _ := storage.Get("log")
+	data, _ := io.ReadAll(reader)
+	if string(data) != "abcxyzabc" {
+		t.Errorf("Compose() = %q, want %q", data, "abcxyzabc")
+	}
+
+	if err := composer.Compose(ctx, "out", "other", "missing"); !errors.Is(err, common.ErrKeyNotFound) {
+		t.Errorf("Compose() error = %v, want ErrKeyNotFound", err)
+	}
+	if err := composer.Compose(ctx, "out"); !errors.Is(err, common.ErrInvalidArgument) {
+		t.Errorf("Compose() error = %v, want ErrInvalidArgument", err)
+	}
+}
+
+func TestConditionalWrites(t *testing.T) {
+	storage := New().(*Memory)
+	ctx := context.Background()
+
+	if err := storage.PutIfMatch(ctx, "k", strings.NewReader("v1"), nil, ""); err != nil {
+		t.Fatalf("create-if-absent: %v", err)
+	}
+	if err := storage.PutIfMatch(ctx, "k", strings.NewReader("v2"), nil, ""); !errors.Is(err, common.ErrPreconditionFailed) {
+		t.Errorf("expected ErrPreconditionFailed for existing key, got %v", err)
+	}
+
+	metadata, err := storage.GetMetadata(ctx, "k")
+	if err != nil {
+		t.Fatalf("GetMetadata: %v", err)
+	}
+	if err := storage.PutIfMatch(ctx, "k", strings.NewReader("v2"), nil, "stale"); !errors.Is(err, common.ErrPreconditionFailed) {
+		t.Errorf("expected ErrPreconditionFailed for stale ETag, got %v", err)
+	}
+	if err := storage.PutIfMatch(ctx, "k", strings.NewReader("v2"), nil, metadata.ETag); err != nil {
+		t.Fatalf("PutIfMatch: %v", err)
+	}
+
+	metadata, err = storage.GetMetadata(ctx, "k")
+	if err != nil {
+		t.Fatalf("GetMetadata: %v", err)
+	}
+	if err := storage.DeleteIfMatch(ctx, "k", "stale"); !errors.Is(err, common.ErrPreconditionFailed) {
+		t.Errorf("expected ErrPreconditionFailed for stale ETag, got %v", err)
+	}
+	if err := storage.DeleteIfMatch(ctx, "k", metadata.ETag); err != nil {
+		t.Fatalf("DeleteIfMatch: %v", err)
+	}
+	if err := storage.DeleteIfMatch(ctx, "k", metadata.ETag); !errors.Is(err, common.ErrKeyNotFound) {
+		t.Errorf("expected ErrKeyNotFound, got %v", err)
+	}
+}
+
+func TestApplyBatch(t *testing.T) {
+	storage := New().(*Memory)
+	ctx := context.Background()
+
+	if err := storage.PutWithMetadata(ctx, "a", strings.NewReader("a1"), &common.Metadata{ContentType: "text/plain"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.Put("b", strings.NewReader("b1")); err != nil {
+		t.Fatal(err)
+	}
+	meta, err := storage.GetMetadata(ctx, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = storage.ApplyBatch(ctx,
+		[]common.BatchPrecondition{{Key: "a", ETag: meta.ETag}, {Key: "c"}},
+		[]common.BatchOp{
+			{Type: common.BatchPut, Key: "a", Data: []byte("a2")},
+			{Type: common.BatchPut, Key: "c", Data: []byte("c1")},
+			{Type: common.BatchUpdateMetadata, Key: "c", Metadata: &common.Metadata{ContentType: "application/json"}},
+			{Type: common.BatchDelete, Key: "b"},
+		})
+	if err != nil {
+		t.Fatalf("ApplyBatch: %v", err)
+	}
+	if got := readAll(t, storage, "a"); got != "a2" {
+		t.Errorf("a = %q, want a2", got)
+	}
+	if meta, _ := storage.GetMetadata(ctx, "c"); meta == nil || meta.ContentType != "application/json" || meta.Size != 2 {
+		t.Errorf("c metadata = %+v", meta)
+	}
+	if exists, _ := storage.Exists(ctx, "b"); exists {
+		t.Error("expected b to be deleted")
+	}
+
+	// A failed precondition or operation changes nothing.
+	err = storage.ApplyBatch(ctx, []common.BatchPrecondition{{Key: "a"}}, []common.BatchOp{{Type: common.BatchPut, Key: "d", Data: []byte("d")}})
+	if !errors.Is(err, common.ErrPreconditionFailed) {
+		t.Errorf("expected ErrPreconditionFailed, got %v", err)
+	}
+	err = storage.ApplyBatch(ctx, nil, []common.BatchOp{
+		{Type: common.BatchPut, Key: "d", Data: []byte("d")},
+		{Type: common.BatchDelete, Key: "missing"},
+	})
+	if !errors.Is(err, common.ErrKeyNotFound) {
+		t.Errorf("expected ErrKeyNotFound, got %v", err)
+	}
+	if exists, _ := storage.Exists(ctx, "d"); exists {
+		t.Error("expected failed batch to leave no changes")
+	}
+}
+
+func readAll(t *testing.T, storage common.Storage, key string) string {
+	t.Helper()
+	rc, err := storage.Get(key)
+	if err != nil {
+		t.Fatalf("Get(%s): %v", key, err)
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
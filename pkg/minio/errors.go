@@ -0,0 +1,70 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build minio
+
+package minio
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"  //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+	"github.com/aws/aws-sdk-go/aws/request" //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+	"github.com/aws/aws-sdk-go/service/s3"  //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+)
+
+// translateError maps a MinIO (S3 API) error for key to the common error taxonomy.
+// Errors that are not S3 API errors are returned unchanged.
+func translateError(err error, key string) error {
+	var aerr awserr.Error
+	if err == nil || !errors.As(err, &aerr) {
+		return err
+	}
+	if aerr.Code() == request.CanceledErrorCode && errors.Is(aerr.OrigErr(), context.DeadlineExceeded) {
+		return common.ProviderError(context.DeadlineExceeded, key, err)
+	}
+	status := 0
+	var reqErr awserr.RequestFailure
+	if errors.As(err, &reqErr) {
+		status = reqErr.StatusCode()
+	}
+	return common.ProviderError(sentinelFor(aerr.Code(), status), key, err)
+}
+
+// sentinelFor returns the common sentinel for an S3 or MinIO error code, falling back
+// to the HTTP status.
+func sentinelFor(code string, status int) error {
+	switch code {
+	case s3.ErrCodeNoSuchKey, s3.ErrCodeNoSuchBucket, s3.ErrCodeNoSuchUpload, "NotFound":
+		return common.ErrNotFound
+	case s3.ErrCodeBucketAlreadyExists, s3.ErrCodeBucketAlreadyOwnedByYou:
+		return common.ErrAlreadyExists
+	case "AccessDenied", "AllAccessDisabled", "AccountProblem", "InvalidAccessKeyId",
+		"SignatureDoesNotMatch", "ExpiredToken", "InvalidToken":
+		return common.ErrAccessDenied
+	case "PreconditionFailed":
+		return common.ErrPreconditionFailed
+	case "SlowDown", "Throttling", "ThrottlingException", "RequestLimitExceeded", "TooManyRequestsException",
+		"XMinioStorageFull", "XMinioAdminBucketQuotaExceeded":
+		return common.ErrQuotaExceeded
+	case "ServiceUnavailable", "InternalError", "RequestTimeout", "XMinioServerNotInitialized",
+		request.ErrCodeRequestError, request.ErrCodeResponseTimeout:
+		return common.ErrBackendUnavailable
+	case request.CanceledErrorCode:
+		return context.Canceled
+	}
+	return common.ErrorForStatus(status)
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build minio
+
+package minio
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+func TestTranslateError(t *testing.T) {
+	failure := func(code string, status int) error {
+		return awserr.NewRequestFailure(awserr.New(code, code, nil), status, "req-1")
+	}
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"no such key", failure("NoSuchKey", 404), common.ErrKeyNotFound},
+		{"access denied", failure("AccessDenied", 403), common.ErrAccessDenied},
+		{"precondition", failure("PreconditionFailed", 412), common.ErrPreconditionFailed},
+		{"storage full", failure("XMinioStorageFull", 507), common.ErrQuotaExceeded},
+		{"bucket quota", failure("XMinioAdminBucketQuotaExceeded", 400), common.ErrQuotaExceeded},
+		{"not initialized", failure("XMinioServerNotInitialized", 503), common.ErrBackendUnavailable},
+		{"network", awserr.New(request.ErrCodeRequestError, "send request failed", errors.New("dial tcp")), common.ErrBackendUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := translateError(tt.err, "a/key"); !errors.Is(got, tt.want) {
+				t.Errorf("translateError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+
+	plain := errors.New("not an S3 error")
+	if got := translateError(plain, "k"); got != plain {
+		t.Errorf("translateError(plain) = %v, want unchanged", got)
+	}
+}
+
+func TestGetTranslatesNotFound(t *testing.T) {
+	m := &MinIO{svc: &mockS3Client{
+		getObjectError: awserr.NewRequestFailure(awserr.New("NoSuchKey", "The specified key does not exist.", nil), 404, "req-1"),
+	}, bucket: "test-bucket"}
+
+	if _, err := m.Get("missing"); !errors.Is(err, common.ErrKeyNotFound) {
+		t.Errorf("Get() error = %v, want ErrKeyNotFound", err)
+	}
+}
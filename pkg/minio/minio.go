@@ -18,15 +18,18 @@
 package minio
 
 import (
+	"fmt"
 	"io"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/transport"
 
 	"github.com/aws/aws-sdk-go/aws"                //nolint:staticcheck // Using v1 SDK, migration to v2 planned
 	"github.com/aws/aws-sdk-go/aws/credentials"    //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+	"github.com/aws/aws-sdk-go/aws/request"        //nolint:staticcheck // Using v1 SDK, migration to v2 planned
 	"github.com/aws/aws-sdk-go/aws/session"        //nolint:staticcheck // Using v1 SDK, migration to v2 planned
 	"github.com/aws/aws-sdk-go/service/s3"         //nolint:staticcheck // Using v1 SDK, migration to v2 planned
 	"github.com/aws/aws-sdk-go/service/s3/s3iface" //nolint:staticcheck // Using v1 SDK, migration to v2 planned
@@ -34,8 +37,9 @@ import (
 
 // Constants
 const (
-	actionDelete  = "delete"
-	actionArchive = "archive"
+	actionDelete     = "delete"
+	actionArchive    = "archive"
+	actionTransition = common.ActionTransition
 )
 
 // MinIO is a storage backend that stores files in MinIO object storage.
@@ -62,6 +66,7 @@ func New() common.Storage {
 // Optional settings:
 //   - region: AWS region (defaults to "us-east-1")
 //   - useSSL: whether to use SSL (defaults to "false")
+//   - http*: HTTP transport tuning, see the transport package
 func (m *MinIO) Configure(settings map[string]string) error {
 	m.bucket = settings["bucket"]
 	if m.bucket == "" {
@@ -95,10 +100,19 @@ func (m *MinIO) Configure(settings map[string]string) error {
 		Credentials:      credentials.NewStaticCredentials(accessKey, secretKey, ""),
 	}
 
+	httpCfg, err := transport.ParseSettings(settings)
+	if err != nil {
+		return err
+	}
+	cfg.HTTPClient = transport.Default.Client("minio", accessKey+"|"+secretKey+"|"+endpoint, httpCfg)
+
 	sess, err := session.NewSession(cfg)
 	if err != nil {
 		return err
 	}
+	sess.Handlers.Send.PushFront(func(r *request.Request) {
+		r.SetContext(transport.Default.WithTrace(r.Context(), "minio"))
+	})
 
 	m.svc = s3.New(sess)
 	return nil
@@ -114,7 +128,7 @@ func (m *MinIO) Put(key string, data io.Reader) error {
 		Key:    aws.String(key),
 		Body:   aws.ReadSeekCloser(data),
 	})
-	return err
+	return translateError(err, key)
 }
 
 // Get retrieves an object from the backend.
@@ -127,7 +141,7 @@ func (m *MinIO) Get(key string) (io.ReadCloser, error) {
 		Key:    aws.String(key),
 	})
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, key)
 	}
 	return result.Body, nil
 }
@@ -141,7 +155,7 @@ func (m *MinIO) Delete(key string) error {
 		Bucket: aws.String(m.bucket),
 		Key:    aws.String(key),
 	})
-	return err
+	return translateError(err, key)
 }
 
 // List returns a list of keys that start with the given prefix.
@@ -162,7 +176,7 @@ func (m *MinIO) List(prefix string) ([]string, error) {
 
 		result, err := m.svc.ListObjectsV2(input)
 		if err != nil {
-			return nil, err
+			return nil, translateError(err, "")
 		}
 
 		for _, obj := range result.Contents {
@@ -198,9 +212,18 @@ func (m *MinIO) AddPolicy(policy common.LifecyclePolicy) error {
 	if policy.ID == "" {
 		return common.ErrInvalidPolicy
 	}
-	if policy.Action != actionDelete && policy.Action != actionArchive {
+	if policy.Action != actionDelete && policy.Action != actionArchive && policy.Action != actionTransition {
 		return common.ErrInvalidPolicy
 	}
+	// Transitions name a remote tier configured on the MinIO server, so the
+	// storage class is passed through as given.
+	transitionClass := s3.TransitionStorageClassGlacier
+	if policy.Action == actionTransition {
+		if policy.StorageClass == "" {
+			return fmt.Errorf("%w: transition policy requires a storage class", common.ErrInvalidArgument)
+		}
+		transitionClass = policy.StorageClass
+	}
 
 	m.policiesMutex.Lock()
 	defer m.policiesMutex.Unlock()
@@ -214,7 +237,7 @@ func (m *MinIO) AddPolicy(policy common.LifecyclePolicy) error {
 	if err != nil {
 		// If no lifecycle configuration exists, start with empty rules
 		if !isNoSuchLifecycleConfiguration(err) {
-			return err
+			return translateError(err, "")
 		}
 		rules = []*s3.LifecycleRule{}
 	} else {
@@ -245,12 +268,12 @@ func (m *MinIO) AddPolicy(policy common.LifecyclePolicy) error {
 		rule.Expiration = &s3.LifecycleExpiration{
 			Days: aws.Int64(days),
 		}
-	} else if policy.Action == "archive" {
+	} else {
 		// MinIO supports transitions to different storage classes
 		rule.Transitions = []*s3.Transition{
 			{
 				Days:         aws.Int64(days),
-				StorageClass: aws.String(s3.TransitionStorageClassGlacier),
+				StorageClass: aws.String(transitionClass),
 			},
 		}
 	}
@@ -265,7 +288,7 @@ func (m *MinIO) AddPolicy(policy common.LifecyclePolicy) error {
 		},
 	})
 
-	return err
+	return translateError(err, "")
 }
 
 // RemovePolicy removes a lifecycle policy by updating MinIO bucket lifecycle rules.
@@ -283,7 +306,7 @@ func (m *MinIO) RemovePolicy(id string) error {
 		if isNoSuchLifecycleConfiguration(err) {
 			return nil
 		}
-		return err
+		return translateError(err, "")
 	}
 
 	// Filter out the rule with the given ID
@@ -299,7 +322,7 @@ func (m *MinIO) RemovePolicy(id string) error {
 		_, err = m.svc.DeleteBucketLifecycle(&s3.DeleteBucketLifecycleInput{
 			Bucket: aws.String(m.bucket),
 		})
-		return err
+		return translateError(err, "")
 	}
 
 	// Otherwise, put the updated configuration
@@ -310,7 +333,7 @@ func (m *MinIO) RemovePolicy(id string) error {
 		},
 	})
 
-	return err
+	return translateError(err, "")
 }
 
 // GetPolicies returns all lifecycle policies by fetching MinIO bucket lifecycle rules.
@@ -328,7 +351,7 @@ func (m *MinIO) GetPolicies() ([]common.LifecyclePolicy, error) {
 		if isNoSuchLifecycleConfiguration(err) {
 			return []common.LifecyclePolicy{}, nil
 		}
-		return nil, err
+		return nil, translateError(err, "")
 	}
 
 	// Convert S3-compatible lifecycle rules to common.LifecyclePolicy
@@ -352,8 +375,13 @@ func (m *MinIO) GetPolicies() ([]common.LifecyclePolicy, error) {
 			policy.Action = "delete"
 			policy.Retention = time.Duration(*rule.Expiration.Days) * 24 * time.Hour
 		} else if len(rule.Transitions) > 0 && rule.Transitions[0].Days != nil {
-			// Use the first transition for archive action
+			// Use the first transition. Transitions to Glacier are how
+			// archive policies are stored; any other class is a transition.
 			policy.Action = "archive"
+			if class := aws.StringValue(rule.Transitions[0].StorageClass); class != s3.TransitionStorageClassGlacier {
+				policy.Action = actionTransition
+				policy.StorageClass = class
+			}
 			policy.Retention = time.Duration(*rule.Transitions[0].Days) * 24 * time.Hour
 		} else {
 			// Skip rules we don't understand
@@ -17,8 +17,10 @@ package minio
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
-	"strings"
+	"maps"
 	"time"
 
 	"github.com/jeremyhahn/go-objstore/pkg/common"
@@ -34,9 +36,91 @@ func (m *MinIO) PutWithContext(ctx context.Context, key string, data io.Reader)
 
 // PutWithMetadata stores an object with associated metadata.
 func (m *MinIO) PutWithMetadata(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) error {
-	if err := common.ValidateKey(key); err != nil {
+	input, err := m.putObjectInput(key, data, metadata)
+	if err != nil {
 		return err
 	}
+	_, err = m.svc.PutObjectWithContext(ctx, input)
+	return translateError(err, key)
+}
+
+// PutWithInfo stores an object like PutWithMetadata and returns the ETag
+// and version the server assigned it, without reading its metadata back.
+func (m *MinIO) PutWithInfo(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) (*common.ObjectInfo, error) {
+	body := trackPosition(data)
+	input, err := m.putObjectInput(key, body, metadata)
+	if err != nil {
+		return nil, err
+	}
+	out, err := m.svc.PutObjectWithContext(ctx, input)
+	if err != nil {
+		return nil, translateError(err, key)
+	}
+
+	info := &common.Metadata{
+		Size:         body.position(),
+		LastModified: time.Now().UTC(),
+		ETag:         aws.StringValue(out.ETag),
+		Version:      aws.StringValue(out.VersionId),
+	}
+	if metadata != nil {
+		info.ContentType = metadata.ContentType
+		info.ContentEncoding = metadata.ContentEncoding
+		info.StorageClass = metadata.StorageClass
+		info.Custom = maps.Clone(metadata.Custom)
+	}
+	return &common.ObjectInfo{Key: key, Metadata: info}, nil
+}
+
+// positionReader tracks how far its reader has been read.
+type positionReader struct {
+	r   io.Reader
+	pos int64
+}
+
+func (p *positionReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.pos += int64(n)
+	return n, err
+}
+
+func (p *positionReader) position() int64 {
+	return p.pos
+}
+
+// positionReadSeeker is a positionReader that follows the seeks the SDK
+// makes to size and retry a request body.
+type positionReadSeeker struct {
+	*positionReader
+	s io.Seeker
+}
+
+func (p *positionReadSeeker) Seek(offset int64, whence int) (int64, error) {
+	pos, err := p.s.Seek(offset, whence)
+	if err == nil {
+		p.pos = pos
+	}
+	return pos, err
+}
+
+// trackPosition wraps data to report how far it was read, keeping it
+// seekable if it is, so the size of a completed upload is its position.
+func trackPosition(data io.Reader) interface {
+	io.Reader
+	position() int64
+} {
+	p := &positionReader{r: data}
+	if s, ok := data.(io.Seeker); ok {
+		return &positionReadSeeker{positionReader: p, s: s}
+	}
+	return p
+}
+
+// putObjectInput builds the PutObject request for key.
+func (m *MinIO) putObjectInput(key string, data io.Reader, metadata *common.Metadata) (*s3.PutObjectInput, error) {
+	if err := common.ValidateKey(key); err != nil {
+		return nil, err
+	}
 	input := &s3.PutObjectInput{
 		Bucket: aws.String(m.bucket),
 		Key:    aws.String(key),
@@ -57,10 +141,13 @@ func (m *MinIO) PutWithMetadata(ctx context.Context, key string, data io.Reader,
 				input.Metadata[k] = aws.String(v)
 			}
 		}
+		class, err := storageClass(metadata.StorageClass)
+		if err != nil {
+			return nil, err
+		}
+		input.StorageClass = class
 	}
-
-	_, err := m.svc.PutObjectWithContext(ctx, input)
-	return err
+	return input, nil
 }
 
 // GetWithContext retrieves an object from the backend with context support.
@@ -73,8 +160,28 @@ func (m *MinIO) GetWithContext(ctx context.Context, key string) (io.ReadCloser,
 		Key:    aws.String(key),
 	})
 	if err != nil {
+		return nil, translateError(err, key)
+	}
+	return result.Body, nil
+}
+
+// GetRange retrieves length bytes of an object starting at offset using an
+// HTTP Range request. A negative length reads to the end of the object.
+func (m *MinIO) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	if err := common.ValidateKey(key); err != nil {
 		return nil, err
 	}
+	if offset < 0 {
+		return nil, fmt.Errorf("%w: negative range offset %d", common.ErrInvalidArgument, offset)
+	}
+	result, err := m.svc.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(m.bucket),
+		Key:    aws.String(key),
+		Range:  aws.String(common.RangeHeader(offset, length)),
+	})
+	if err != nil {
+		return nil, translateError(err, key)
+	}
 	return result.Body, nil
 }
 
@@ -88,7 +195,7 @@ func (m *MinIO) GetMetadata(ctx context.Context, key string) (*common.Metadata,
 		Key:    aws.String(key),
 	})
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, key)
 	}
 
 	metadata := &common.Metadata{
@@ -103,6 +210,11 @@ func (m *MinIO) GetMetadata(ctx context.Context, key string) (*common.Metadata,
 	if result.ContentEncoding != nil {
 		metadata.ContentEncoding = aws.StringValue(result.ContentEncoding)
 	}
+	// The storage class header is omitted for STANDARD objects.
+	metadata.StorageClass = aws.StringValue(result.StorageClass)
+	if metadata.StorageClass == "" {
+		metadata.StorageClass = s3.StorageClassStandard
+	}
 
 	// Convert MinIO metadata to custom metadata
 	if len(result.Metadata) > 0 {
@@ -139,10 +251,15 @@ func (m *MinIO) UpdateMetadata(ctx context.Context, key string, metadata *common
 				input.Metadata[k] = aws.String(v)
 			}
 		}
+		class, err := storageClass(metadata.StorageClass)
+		if err != nil {
+			return err
+		}
+		input.StorageClass = class
 	}
 
 	_, err := m.svc.CopyObjectWithContext(ctx, input)
-	return err
+	return translateError(err, key)
 }
 
 // DeleteWithContext removes an object from the backend with context support.
@@ -154,7 +271,7 @@ func (m *MinIO) DeleteWithContext(ctx context.Context, key string) error {
 		Bucket: aws.String(m.bucket),
 		Key:    aws.String(key),
 	})
-	return err
+	return translateError(err, key)
 }
 
 // Exists checks if an object exists in the backend.
@@ -167,7 +284,7 @@ func (m *MinIO) Exists(ctx context.Context, key string) (bool, error) {
 		Key:    aws.String(key),
 	})
 	if err != nil {
-		if strings.Contains(err.Error(), "NotFound") || strings.Contains(err.Error(), "404") {
+		if err = translateError(err, key); errors.Is(err, common.ErrNotFound) {
 			return false, nil
 		}
 		return false, err
@@ -192,7 +309,7 @@ func (m *MinIO) ListWithContext(ctx context.Context, prefix string) ([]string, e
 
 		result, err := m.svc.ListObjectsV2WithContext(ctx, input)
 		if err != nil {
-			return nil, err
+			return nil, translateError(err, "")
 		}
 
 		for _, obj := range result.Contents {
@@ -236,7 +353,7 @@ func (m *MinIO) ListWithOptions(ctx context.Context, opts *common.ListOptions) (
 
 	result, err := m.svc.ListObjectsV2WithContext(ctx, input)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "")
 	}
 
 	listResult := &common.ListResult{
@@ -252,8 +369,9 @@ func (m *MinIO) ListWithOptions(ctx context.Context, opts *common.ListOptions) (
 		}
 
 		metadata := &common.Metadata{
-			Size: aws.Int64Value(obj.Size),
-			ETag: aws.StringValue(obj.ETag),
+			Size:         aws.Int64Value(obj.Size),
+			ETag:         aws.StringValue(obj.ETag),
+			StorageClass: aws.StringValue(obj.StorageClass),
 		}
 		if obj.LastModified != nil {
 			metadata.LastModified = *obj.LastModified
@@ -282,3 +400,17 @@ func (m *MinIO) ListWithOptions(ctx context.Context, opts *common.ListOptions) (
 
 	return listResult, nil
 }
+
+// storageClasses are the storage classes MinIO accepts on upload. Colder
+// tiers are reached through lifecycle transitions to a configured remote tier.
+var storageClasses = []string{s3.StorageClassStandard, s3.StorageClassReducedRedundancy}
+
+// storageClass validates a MinIO storage class, returning nil for the
+// default.
+func storageClass(class string) (*string, error) {
+	class, err := common.NormalizeStorageClass(class, storageClasses)
+	if err != nil || class == "" {
+		return nil, err
+	}
+	return aws.String(class), nil
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/jeremyhahn/go-objstore/pkg/common"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/s3"
 )
@@ -35,6 +36,9 @@ func (m *mockS3Client) PutObjectWithContext(ctx aws.Context, input *s3.PutObject
 	if m.putObjectError != nil {
 		return nil, m.putObjectError
 	}
+	if input.Body != nil {
+		_, _ = io.Copy(io.Discard, input.Body)
+	}
 	return m.putObjectOutput, nil
 }
 
@@ -145,6 +149,30 @@ func TestMinIO_PutWithMetadata_NilMetadata(t *testing.T) {
 	}
 }
 
+func TestMinIO_PutWithInfo(t *testing.T) {
+	mockS3 := &mockS3Client{
+		putObjectOutput: &s3.PutObjectOutput{ETag: aws.String(`"abc123"`), VersionId: aws.String("v2")},
+	}
+	m := &MinIO{svc: mockS3, bucket: "test-bucket"}
+
+	metadata := &common.Metadata{ContentType: "text/plain", Custom: map[string]string{"author": "test"}}
+	info, err := m.PutWithInfo(context.Background(), "key", bytes.NewReader([]byte("data")), metadata)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if info.Key != "key" || info.Metadata.Size != 4 || info.Metadata.ETag != `"abc123"` || info.Metadata.Version != "v2" {
+		t.Fatalf("unexpected info: %+v %+v", info, info.Metadata)
+	}
+	if info.Metadata.ContentType != "text/plain" || info.Metadata.Custom["author"] != "test" {
+		t.Fatalf("unexpected metadata: %+v", info.Metadata)
+	}
+
+	mockS3.putObjectError = errors.New("boom")
+	if _, err := m.PutWithInfo(context.Background(), "key", bytes.NewReader([]byte("data")), nil); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
 func TestMinIO_PutWithMetadata_Error(t *testing.T) {
 	mockS3 := &mockS3Client{
 		putObjectError: errors.New("upload error"),
@@ -392,7 +420,7 @@ func TestMinIO_Exists(t *testing.T) {
 
 func TestMinIO_Exists_NotFound(t *testing.T) {
 	mockS3 := &mockS3Client{
-		headObjectError: errors.New("NotFound"),
+		headObjectError: awserr.NewRequestFailure(awserr.New("NotFound", "Not Found", nil), 404, "req-1"),
 	}
 
 	m := &MinIO{svc: mockS3, bucket: "test-bucket"}
@@ -409,7 +437,8 @@ func TestMinIO_Exists_NotFound(t *testing.T) {
 
 func TestMinIO_Exists_NotFound_404(t *testing.T) {
 	mockS3 := &mockS3Client{
-		headObjectError: errors.New("404 Not Found"),
+		// An unrecognized code still classifies by its HTTP status.
+		headObjectError: awserr.NewRequestFailure(awserr.New("UnknownError", "Not Found", nil), 404, "req-1"),
 	}
 
 	m := &MinIO{svc: mockS3, bucket: "test-bucket"}
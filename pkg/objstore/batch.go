@@ -0,0 +1,377 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package objstore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/validation"
+)
+
+// MaxBatchOps is the largest number of operations and preconditions a
+// batch may stage.
+const MaxBatchOps = 1000
+
+// BatchBuilder stages changes to several objects and applies them together
+// with Commit. Create one with Batch.
+//
+// If every key is on one backend that implements common.BatchWriter, the
+// batch is applied atomically. Otherwise the preconditions are checked, the
+// changes are applied in order and, if one fails, the objects already
+// changed are restored from a journal of their previous contents. The
+// rollback is best effort: concurrent writers may observe or interleave
+// with a partly applied batch, restored objects get new ETags, and previous
+// contents are held in memory, so batches suit small objects.
+type BatchBuilder struct {
+	preconditions []batchPrecondition
+	ops           []batchOp
+	err           error
+}
+
+type batchPrecondition struct {
+	ref  string
+	etag string
+}
+
+type batchOp struct {
+	ref string
+	op  common.BatchOp
+}
+
+// BatchError reports a batch that failed part way through and was rolled
+// back.
+type BatchError struct {
+	// Index is the position of the failed operation among the staged ones.
+	Index int
+
+	// Op is the type of the failed operation.
+	Op common.BatchOpType
+
+	// Key is the key reference of the failed operation.
+	Key string
+
+	// Err is the error of the failed operation.
+	Err error
+
+	// RollbackErrors lists the objects that could not be restored.
+	RollbackErrors []error
+}
+
+// Error describes the failed operation and any objects left changed.
+func (e *BatchError) Error() string {
+	msg := fmt.Sprintf("batch operation %d (%s %s) failed: %v", e.Index, e.Op, e.Key, e.Err)
+	if len(e.RollbackErrors) > 0 {
+		msg += fmt.Sprintf("; rollback failed for %d objects: %v", len(e.RollbackErrors), errors.Join(e.RollbackErrors...))
+	}
+	return msg
+}
+
+// Unwrap returns the error of the failed operation.
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// Batch returns an empty batch.
+//
+// Example usage:
+//
+//	meta, _ := objstore.GetMetadata(ctx, "state/index.json")
+//	err := objstore.Batch().
+//	    IfMatch("state/index.json", meta.ETag).
+//	    Put("state/index.json", index, nil).
+//	    Put("state/segments/0042.json", segment, nil).
+//	    Delete("state/segments/0017.json").
+//	    Commit(ctx)
+func Batch() *BatchBuilder {
+	return &BatchBuilder{}
+}
+
+// IfMatch requires the object to have etag when the batch is committed.
+func (b *BatchBuilder) IfMatch(keyRef, etag string) *BatchBuilder {
+	if etag == "" {
+		b.fail(fmt.Errorf("%w: empty ETag for %s", common.ErrInvalidArgument, keyRef))
+		return b
+	}
+	return b.addPrecondition(keyRef, etag)
+}
+
+// IfNotExists requires the object not to exist when the batch is committed.
+func (b *BatchBuilder) IfNotExists(keyRef string) *BatchBuilder {
+	return b.addPrecondition(keyRef, "")
+}
+
+// Put stages storing data with metadata. data must not be modified until
+// the batch is committed.
+func (b *BatchBuilder) Put(keyRef string, data []byte, metadata *common.Metadata) *BatchBuilder {
+	return b.addOp(keyRef, common.BatchOp{Type: common.BatchPut, Data: data, Metadata: metadata})
+}
+
+// Delete stages removing an object, which must exist.
+func (b *BatchBuilder) Delete(keyRef string) *BatchBuilder {
+	return b.addOp(keyRef, common.BatchOp{Type: common.BatchDelete})
+}
+
+// UpdateMetadata stages replacing the metadata of an object, which must
+// exist.
+func (b *BatchBuilder) UpdateMetadata(keyRef string, metadata *common.Metadata) *BatchBuilder {
+	return b.addOp(keyRef, common.BatchOp{Type: common.BatchUpdateMetadata, Metadata: metadata})
+}
+
+// Len returns the number of staged operations.
+func (b *BatchBuilder) Len() int {
+	return len(b.ops)
+}
+
+func (b *BatchBuilder) addPrecondition(keyRef, etag string) *BatchBuilder {
+	if b.checkRef(keyRef) {
+		b.preconditions = append(b.preconditions, batchPrecondition{ref: keyRef, etag: etag})
+	}
+	return b
+}
+
+func (b *BatchBuilder) addOp(keyRef string, op common.BatchOp) *BatchBuilder {
+	if b.checkRef(keyRef) {
+		b.ops = append(b.ops, batchOp{ref: keyRef, op: op})
+	}
+	return b
+}
+
+// checkRef validates a key reference and the batch size, recording the
+// first problem for Commit to return.
+func (b *BatchBuilder) checkRef(keyRef string) bool {
+	if b.err != nil {
+		return false
+	}
+	if err := validation.ValidateKeyReference(keyRef); err != nil {
+		b.fail(fmt.Errorf("invalid key reference: %w", err))
+		return false
+	}
+	if len(b.ops)+len(b.preconditions) >= MaxBatchOps {
+		b.fail(fmt.Errorf("%w: batch exceeds %d operations", common.ErrInvalidArgument, MaxBatchOps))
+		return false
+	}
+	return true
+}
+
+func (b *BatchBuilder) fail(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+// batchTarget is a resolved key of a staged operation or precondition.
+type batchTarget struct {
+	storage common.Storage
+	key     string
+}
+
+// Commit checks the preconditions and applies the staged operations. A
+// failed precondition returns an error wrapping common.ErrPreconditionFailed
+// and changes nothing. A failed operation returns a *BatchError unless the
+// batch was applied atomically.
+func (b *BatchBuilder) Commit(ctx context.Context) error {
+	if b.err != nil {
+		return b.err
+	}
+	if len(b.ops) == 0 && len(b.preconditions) == 0 {
+		return nil
+	}
+
+	preconditions := make([]batchTarget, len(b.preconditions))
+	for i, pre := range b.preconditions {
+		storage, key, err := getStorageForKey(pre.ref)
+		if err != nil {
+			return err
+		}
+		preconditions[i] = batchTarget{storage: storage, key: key}
+	}
+	targets := make([]batchTarget, len(b.ops))
+	for i, op := range b.ops {
+		storage, key, err := getStorageForKey(op.ref)
+		if err != nil {
+			return err
+		}
+		targets[i] = batchTarget{storage: storage, key: key}
+	}
+
+	if writer, ok := singleBatchWriter(preconditions, targets); ok {
+		pres := make([]common.BatchPrecondition, len(preconditions))
+		for i, target := range preconditions {
+			pres[i] = common.BatchPrecondition{Key: target.key, ETag: b.preconditions[i].etag}
+		}
+		ops := make([]common.BatchOp, len(targets))
+		for i, target := range targets {
+			ops[i] = b.ops[i].op
+			ops[i].Key = target.key
+		}
+		return writer.ApplyBatch(ctx, pres, ops)
+	}
+
+	for i, target := range preconditions {
+		if err := checkBatchPrecondition(ctx, target, b.preconditions[i]); err != nil {
+			return err
+		}
+	}
+	return b.applyJournaled(ctx, targets)
+}
+
+// singleBatchWriter returns the backend every target is on if it can apply
+// batches atomically. Wrappers that do not implement common.BatchWriter are
+// never bypassed, so the checks they enforce still apply.
+func singleBatchWriter(preconditions, targets []batchTarget) (common.BatchWriter, bool) {
+	all := append(append([]batchTarget(nil), preconditions...), targets...)
+	storage := all[0].storage
+	for _, target := range all[1:] {
+		if target.storage != storage {
+			return nil, false
+		}
+	}
+	// A reserved-namespace wrapper only guards its keys, so batches that
+	// stay outside the namespace can go to the backend beneath it.
+	if reserved, isReserved := storage.(*common.ReservedStorage); isReserved {
+		for _, target := range all {
+			if reserved.Reserved(target.key) {
+				return nil, false
+			}
+		}
+		storage = reserved.Underlying()
+	}
+	writer, ok := storage.(common.BatchWriter)
+	return writer, ok
+}
+
+func checkBatchPrecondition(ctx context.Context, target batchTarget, pre batchPrecondition) error {
+	metadata, err := target.storage.GetMetadata(ctx, target.key)
+	switch {
+	case errors.Is(err, common.ErrNotFound):
+		if pre.etag != "" {
+			return fmt.Errorf("%w: %s does not exist", common.ErrPreconditionFailed, pre.ref)
+		}
+		return nil
+	case err != nil:
+		return err
+	case pre.etag == "":
+		return fmt.Errorf("%w: %s already exists", common.ErrPreconditionFailed, pre.ref)
+	case metadata.ETag != pre.etag:
+		return fmt.Errorf("%w: %s does not match ETag %s", common.ErrPreconditionFailed, pre.ref, pre.etag)
+	}
+	return nil
+}
+
+// journalEntry is the state of an object before the batch first changed it.
+type journalEntry struct {
+	target   batchTarget
+	ref      string
+	exists   bool
+	data     []byte
+	metadata *common.Metadata
+}
+
+// applyJournaled applies the staged operations in order, recording each
+// object's previous state before its first change and restoring them all if
+// an operation fails.
+func (b *BatchBuilder) applyJournaled(ctx context.Context, targets []batchTarget) error {
+	var journal []*journalEntry
+	journaled := make(map[batchTarget]bool, len(targets))
+
+	for i, target := range targets {
+		if !journaled[target] {
+			entry, err := snapshot(ctx, target, b.ops[i].ref)
+			if err != nil {
+				return b.rollback(ctx, journal, i, err)
+			}
+			journal = append(journal, entry)
+			journaled[target] = true
+		}
+		if err := applyBatchOp(ctx, target, b.ops[i].op); err != nil {
+			return b.rollback(ctx, journal, i, err)
+		}
+	}
+	return nil
+}
+
+func (b *BatchBuilder) rollback(ctx context.Context, journal []*journalEntry, index int, cause error) error {
+	batchErr := &BatchError{Index: index, Op: b.ops[index].op.Type, Key: b.ops[index].ref, Err: cause}
+
+	// Restore even if the batch failed because ctx was canceled.
+	ctx = context.WithoutCancel(ctx)
+	for i := len(journal) - 1; i >= 0; i-- {
+		entry := journal[i]
+		var err error
+		if entry.exists {
+			err = entry.target.storage.PutWithMetadata(ctx, entry.target.key, bytes.NewReader(entry.data), entry.metadata)
+		} else if err = entry.target.storage.DeleteWithContext(ctx, entry.target.key); errors.Is(err, common.ErrNotFound) {
+			err = nil
+		}
+		if err != nil {
+			batchErr.RollbackErrors = append(batchErr.RollbackErrors, fmt.Errorf("%s: %w", entry.ref, err))
+		}
+	}
+	return batchErr
+}
+
+// snapshot reads the current state of an object for the journal.
+func snapshot(ctx context.Context, target batchTarget, ref string) (*journalEntry, error) {
+	entry := &journalEntry{target: target, ref: ref}
+	metadata, err := target.storage.GetMetadata(ctx, target.key)
+	if errors.Is(err, common.ErrNotFound) {
+		return entry, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rc, err := target.storage.GetWithContext(ctx, target.key)
+	if errors.Is(err, common.ErrNotFound) {
+		return entry, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rc.Close() }()
+	if entry.data, err = io.ReadAll(rc); err != nil {
+		return nil, err
+	}
+	entry.exists = true
+	entry.metadata = metadata
+	return entry, nil
+}
+
+func applyBatchOp(ctx context.Context, target batchTarget, op common.BatchOp) error {
+	var metadata *common.Metadata
+	if op.Metadata != nil {
+		copied := *op.Metadata
+		metadata = &copied
+	}
+	switch op.Type {
+	case common.BatchPut:
+		if metadata == nil {
+			return target.storage.PutWithContext(ctx, target.key, bytes.NewReader(op.Data))
+		}
+		return target.storage.PutWithMetadata(ctx, target.key, bytes.NewReader(op.Data), metadata)
+	case common.BatchDelete:
+		return target.storage.DeleteWithContext(ctx, target.key)
+	case common.BatchUpdateMetadata:
+		if metadata == nil {
+			metadata = &common.Metadata{}
+		}
+		return target.storage.UpdateMetadata(ctx, target.key, metadata)
+	}
+	return fmt.Errorf("%w: unknown batch operation %d", common.ErrInvalidArgument, op.Type)
+}
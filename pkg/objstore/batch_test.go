@@ -0,0 +1,214 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package objstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+// journaledStorage hides a backend's BatchWriter so batches on it take the
+// journaled path, and fails deletes of failKey.
+type journaledStorage struct {
+	common.Storage
+	failKey string
+}
+
+func (s *journaledStorage) DeleteWithContext(ctx context.Context, key string) error {
+	if key == s.failKey {
+		return errors.New("injected delete failure")
+	}
+	return s.Storage.DeleteWithContext(ctx, key)
+}
+
+func readObject(t *testing.T, keyRef string) (string, bool) {
+	t.Helper()
+	rc, err := GetWithContext(context.Background(), keyRef)
+	if errors.Is(err, common.ErrNotFound) {
+		return "", false
+	}
+	if err != nil {
+		t.Fatalf("GetWithContext(%s): %v", keyRef, err)
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data), true
+}
+
+// initBatchFacade registers a memory backend, applying batches atomically,
+// as the default and a "journaled" one that cannot.
+func initBatchFacade(t *testing.T) *journaledStorage {
+	t.Helper()
+	Reset()
+	t.Cleanup(Reset)
+	journaled := &journaledStorage{Storage: memory.New()}
+	err := Initialize(&FacadeConfig{
+		Backends: map[string]common.Storage{
+			"atomic":    memory.New(),
+			"journaled": journaled,
+		},
+		DefaultBackend: "atomic",
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+	return journaled
+}
+
+func TestBatchCommit(t *testing.T) {
+	initBatchFacade(t)
+	ctx := context.Background()
+
+	for _, prefix := range []string{"", "journaled:"} {
+		if err := PutWithContext(ctx, prefix+"a", strings.NewReader("a1")); err != nil {
+			t.Fatal(err)
+		}
+		if err := PutWithContext(ctx, prefix+"b", strings.NewReader("b1")); err != nil {
+			t.Fatal(err)
+		}
+		meta, err := GetMetadata(ctx, prefix+"a")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = Batch().
+			IfMatch(prefix+"a", meta.ETag).
+			IfNotExists(prefix+"c").
+			Put(prefix+"a", []byte("a2"), nil).
+			Put(prefix+"c", []byte("c1"), &common.Metadata{ContentType: "text/plain"}).
+			Delete(prefix + "b").
+			Commit(ctx)
+		if err != nil {
+			t.Fatalf("%sCommit() error = %v", prefix, err)
+		}
+		if got, _ := readObject(t, prefix+"a"); got != "a2" {
+			t.Errorf("%sa = %q, want a2", prefix, got)
+		}
+		if got, _ := readObject(t, prefix+"c"); got != "c1" {
+			t.Errorf("%sc = %q, want c1", prefix, got)
+		}
+		if _, ok := readObject(t, prefix+"b"); ok {
+			t.Errorf("%sb was not deleted", prefix)
+		}
+
+		err = Batch().IfMatch(prefix+"a", meta.ETag+"-stale").Put(prefix+"d", []byte("d"), nil).Commit(ctx)
+		if !errors.Is(err, common.ErrPreconditionFailed) {
+			t.Errorf("%sexpected ErrPreconditionFailed, got %v", prefix, err)
+		}
+		if err := Batch().IfNotExists(prefix+"a").Put(prefix+"d", []byte("d"), nil).Commit(ctx); !errors.Is(err, common.ErrPreconditionFailed) {
+			t.Errorf("%sexpected ErrPreconditionFailed for existing key, got %v", prefix, err)
+		}
+		if _, ok := readObject(t, prefix+"d"); ok {
+			t.Errorf("%sfailed precondition applied changes", prefix)
+		}
+	}
+}
+
+func TestBatchRollback(t *testing.T) {
+	journaled := initBatchFacade(t)
+	ctx := context.Background()
+
+	if err := PutWithMetadata(ctx, "journaled:a", strings.NewReader("a1"), &common.Metadata{ContentType: "text/plain"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := PutWithContext(ctx, "journaled:b", strings.NewReader("b1")); err != nil {
+		t.Fatal(err)
+	}
+	journaled.failKey = "b"
+
+	err := Batch().
+		Put("journaled:a", []byte("a2"), nil).
+		Put("journaled:new", []byte("n"), nil).
+		Put("other", []byte("o"), nil).
+		Delete("journaled:b").
+		Commit(ctx)
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("expected *BatchError, got %v", err)
+	}
+	if batchErr.Index != 3 || batchErr.Op != common.BatchDelete || batchErr.Key != "journaled:b" || len(batchErr.RollbackErrors) != 0 {
+		t.Errorf("unexpected batch error: %+v", batchErr)
+	}
+
+	if got, _ := readObject(t, "journaled:a"); got != "a1" {
+		t.Errorf("a = %q, want a1 restored", got)
+	}
+	if meta, _ := GetMetadata(ctx, "journaled:a"); meta == nil || meta.ContentType != "text/plain" {
+		t.Errorf("a metadata not restored: %+v", meta)
+	}
+	if _, ok := readObject(t, "journaled:new"); ok {
+		t.Error("expected new object to be removed")
+	}
+	if _, ok := readObject(t, "other"); ok {
+		t.Error("expected object on the other backend to be removed")
+	}
+	if got, _ := readObject(t, "journaled:b"); got != "b1" {
+		t.Errorf("b = %q, want b1", got)
+	}
+}
+
+func TestBatchAtomicFailure(t *testing.T) {
+	initBatchFacade(t)
+	ctx := context.Background()
+
+	err := Batch().Put("a", []byte("a"), nil).Delete("missing").Commit(ctx)
+	if !errors.Is(err, common.ErrKeyNotFound) {
+		t.Fatalf("expected ErrKeyNotFound, got %v", err)
+	}
+	var batchErr *BatchError
+	if errors.As(err, &batchErr) {
+		t.Error("atomic batches should not report a rollback")
+	}
+	if _, ok := readObject(t, "a"); ok {
+		t.Error("expected atomic batch to change nothing")
+	}
+}
+
+func TestBatchValidation(t *testing.T) {
+	Reset()
+	if err := Batch().Put("a", nil, nil).Commit(context.Background()); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("expected ErrNotInitialized, got %v", err)
+	}
+	initBatchFacade(t)
+
+	if err := Batch().Commit(context.Background()); err != nil {
+		t.Errorf("empty batch: %v", err)
+	}
+	if err := Batch().Put("../escape", nil, nil).Put("a", nil, nil).Commit(context.Background()); err == nil {
+		t.Error("expected invalid key to be rejected")
+	}
+	if err := Batch().IfMatch("a", "").Commit(context.Background()); !errors.Is(err, common.ErrInvalidArgument) {
+		t.Errorf("expected empty ETag to be rejected, got %v", err)
+	}
+
+	b := Batch()
+	for i := 0; i <= MaxBatchOps; i++ {
+		b.Delete("a")
+	}
+	if b.Len() != MaxBatchOps {
+		t.Errorf("Len() = %d, want %d", b.Len(), MaxBatchOps)
+	}
+	if err := b.Commit(context.Background()); !errors.Is(err, common.ErrInvalidArgument) {
+		t.Errorf("expected oversized batch to be rejected, got %v", err)
+	}
+}
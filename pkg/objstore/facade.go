@@ -19,15 +19,47 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"net"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/jeremyhahn/go-objstore/pkg/access"
 	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/alias"
 	"github.com/jeremyhahn/go-objstore/pkg/audit"
+	"github.com/jeremyhahn/go-objstore/pkg/changefeed"
+	"github.com/jeremyhahn/go-objstore/pkg/checksum"
 	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/concurrency"
+	"github.com/jeremyhahn/go-objstore/pkg/contentpolicy"
+	"github.com/jeremyhahn/go-objstore/pkg/cost"
+	"github.com/jeremyhahn/go-objstore/pkg/diff"
+	"github.com/jeremyhahn/go-objstore/pkg/events"
 	"github.com/jeremyhahn/go-objstore/pkg/factory"
+	"github.com/jeremyhahn/go-objstore/pkg/ha"
+	"github.com/jeremyhahn/go-objstore/pkg/health"
+	"github.com/jeremyhahn/go-objstore/pkg/jobs"
+	"github.com/jeremyhahn/go-objstore/pkg/keypolicy"
+	"github.com/jeremyhahn/go-objstore/pkg/locks"
+	"github.com/jeremyhahn/go-objstore/pkg/manifest"
+	"github.com/jeremyhahn/go-objstore/pkg/overlay"
+	"github.com/jeremyhahn/go-objstore/pkg/query"
+	"github.com/jeremyhahn/go-objstore/pkg/reaper"
 	"github.com/jeremyhahn/go-objstore/pkg/replication"
+	"github.com/jeremyhahn/go-objstore/pkg/residency"
+	"github.com/jeremyhahn/go-objstore/pkg/retention"
+	"github.com/jeremyhahn/go-objstore/pkg/routing"
+	"github.com/jeremyhahn/go-objstore/pkg/scrub"
+	"github.com/jeremyhahn/go-objstore/pkg/search"
+	"github.com/jeremyhahn/go-objstore/pkg/share"
+	"github.com/jeremyhahn/go-objstore/pkg/stats"
+	"github.com/jeremyhahn/go-objstore/pkg/storagefs"
+	"github.com/jeremyhahn/go-objstore/pkg/tasks"
+	"github.com/jeremyhahn/go-objstore/pkg/tombstone"
+	"github.com/jeremyhahn/go-objstore/pkg/transform"
+	"github.com/jeremyhahn/go-objstore/pkg/upload"
 	"github.com/jeremyhahn/go-objstore/pkg/validation"
 )
 
@@ -40,6 +72,101 @@ var (
 
 	// ErrBackendNotFound is returned when a backend is not found
 	ErrBackendNotFound = errors.New("backend not found")
+
+	// ErrSearchNotEnabled is returned when searching a backend without an index
+	ErrSearchNotEnabled = errors.New("search not enabled for backend")
+
+	// ErrRetentionNotEnabled is returned when managing legal holds or deletion
+	// requests on a backend without retention enabled
+	ErrRetentionNotEnabled = errors.New("retention not enabled for backend")
+
+	// ErrLocksNotEnabled is returned when using advisory locks on a backend
+	// without locks enabled
+	ErrLocksNotEnabled = errors.New("locks not enabled for backend")
+
+	// ErrSharesNotEnabled is returned when managing the share links of a
+	// backend without share links enabled
+	ErrSharesNotEnabled = errors.New("share links not enabled for backend")
+
+	// ErrUploadsNotEnabled is returned when using upload sessions on a
+	// backend without upload sessions enabled
+	ErrUploadsNotEnabled = errors.New("upload sessions not enabled for backend")
+
+	// ErrTombstonesNotEnabled is returned when managing the tombstones of a
+	// backend without deferred deletes enabled
+	ErrTombstonesNotEnabled = errors.New("tombstones not enabled for backend")
+
+	// ErrHANotEnabled is returned when looking up the cluster of a backend
+	// without high availability enabled
+	ErrHANotEnabled = errors.New("high availability not enabled for backend")
+
+	// ErrManifestsNotEnabled is returned when using manifests on a backend
+	// without manifests enabled
+	ErrManifestsNotEnabled = errors.New("manifests not enabled for backend")
+
+	// ErrAliasesNotEnabled is returned when using aliases on a backend
+	// without aliases enabled
+	ErrAliasesNotEnabled = errors.New("aliases not enabled for backend")
+
+	// ErrOverlaysNotEnabled is returned when managing the quotas of a
+	// backend without overlays enabled
+	ErrOverlaysNotEnabled = errors.New("overlays not enabled for backend")
+
+	// ErrNotificationsNotEnabled is returned when looking up the notifier of
+	// a backend without notifications enabled
+	ErrNotificationsNotEnabled = errors.New("notifications not enabled for backend")
+
+	// ErrChangeFeedNotEnabled is returned when reading the changes of a
+	// backend without a change feed enabled
+	ErrChangeFeedNotEnabled = errors.New("change feed not enabled for backend")
+
+	// ErrTransformsNotEnabled is returned when requesting a derived object
+	// from a backend without transforms enabled
+	ErrTransformsNotEnabled = errors.New("transforms not enabled for backend")
+
+	// ErrConcurrencyLimitNotEnabled is returned when looking up the
+	// concurrency limiter of a backend without one enabled
+	ErrConcurrencyLimitNotEnabled = errors.New("concurrency limit not enabled for backend")
+
+	// ErrFailoverNotEnabled is returned when looking up the health monitor
+	// of a backend without failover enabled
+	ErrFailoverNotEnabled = errors.New("failover not enabled for backend")
+
+	// ErrAccessNotEnabled is returned when requesting the read tracker of a
+	// backend without access tracking enabled
+	ErrAccessNotEnabled = errors.New("access tracking not enabled for backend")
+
+	// ErrStatsNotEnabled is returned when requesting storage statistics
+	// without snapshots enabled
+	ErrStatsNotEnabled = errors.New("storage statistics not enabled")
+
+	// ErrCostNotEnabled is returned when requesting a cost report without
+	// cost metering enabled
+	ErrCostNotEnabled = errors.New("cost metering not enabled")
+
+	// ErrJobsNotEnabled is returned when requesting job status without a
+	// job scheduler enabled
+	ErrJobsNotEnabled = errors.New("job scheduler not enabled")
+
+	// ErrTasksNotEnabled is returned when using the task queue without
+	// enabling it
+	ErrTasksNotEnabled = errors.New("task queue not enabled")
+
+	// ErrChecksumsNotEnabled is returned when scrubbing a backend without
+	// enabling checksums
+	ErrChecksumsNotEnabled = errors.New("checksums not enabled for backend")
+
+	// ErrSeekNotSupported is returned by OpenRange for backends that cannot read byte ranges
+	ErrSeekNotSupported = errors.New("backend does not support seeking")
+
+	// ErrVersionsNotSupported is returned by GetAsOf for backends that keep
+	// no earlier versions of objects
+	ErrVersionsNotSupported = common.ErrVersionsNotSupported
+
+	// ErrContextRequired is returned with FacadeConfig.RequireContext for
+	// calls made with a context that can never be cancelled, such as
+	// context.Background(), or through a function that takes no context
+	ErrContextRequired = errors.New("a cancellable context is required")
 )
 
 // Facade singleton instance
@@ -55,6 +182,13 @@ var (
 type ObjstoreFacade struct {
 	backends       map[string]common.Storage // backend name -> Storage
 	defaultBackend string                    // default backend to use
+	router         *routing.Router           // spreads default backend reads, if set
+	stats          *stats.Collector          // storage snapshots, if enabled
+	trackers       []*access.Tracker         // read trackers, if enabled
+	jobs           *jobs.Scheduler           // background job scheduler, if enabled
+	tasks          *tasks.Queue              // durable task queue, if enabled
+	timeouts       Timeouts                  // bounds of calls without a context
+	requireContext bool                      // refuse calls without a context
 	mu             sync.RWMutex
 }
 
@@ -82,6 +216,27 @@ type FacadeConfig struct {
 	// DefaultBackend is the name of the default backend to use
 	// when no backend is specified in the key reference
 	DefaultBackend string
+
+	// ReadRouting spreads reads of keys on the default backend across
+	// backends holding the same replicated data, instead of always reading
+	// from the default. The default backend is added to
+	// ReadRouting.Backends if missing. A replica that does not have an
+	// object yet, or is unavailable, falls back to the next one. With
+	// ReadRouting.HedgeAfter set, a Get that has not returned in time is
+	// also sent to the next replica and the first response is used.
+	// Writes, listings and reads naming a backend are not routed.
+	ReadRouting *routing.Config
+
+	// DefaultTimeouts bounds each class of operation called with a context
+	// that can never be cancelled, such as context.Background(), so a
+	// caller that sets no deadline cannot leave a server waiting forever
+	// on a stalled backend. Zero leaves a class unbounded.
+	DefaultTimeouts Timeouts
+
+	// RequireContext refuses such calls with ErrContextRequired instead,
+	// including the legacy functions that take no context (Put, Get,
+	// Delete and List).
+	RequireContext bool
 }
 
 // Initialize sets up the objstore facade
@@ -132,6 +287,12 @@ func Initialize(config *FacadeConfig) error {
 			backends[name] = storage
 		}
 
+		// PutAtomic stages uploads under common.StagingPrefix, which
+		// clients must neither see nor write.
+		for name, storage := range backends {
+			backends[name] = common.NewStagingStorage(storage)
+		}
+
 		if len(backends) == 0 {
 			initErr = errors.New("at least one backend must be configured")
 			return
@@ -152,15 +313,45 @@ func Initialize(config *FacadeConfig) error {
 			return
 		}
 
+		var router *routing.Router
+		if config.ReadRouting != nil {
+			router, initErr = newReadRouter(config.ReadRouting, backends, defaultBackend)
+			if initErr != nil {
+				return
+			}
+		}
+
 		facade = &ObjstoreFacade{
 			backends:       backends,
 			defaultBackend: defaultBackend,
+			router:         router,
+			timeouts:       config.DefaultTimeouts,
+			requireContext: config.RequireContext,
 		}
 	})
 
 	return initErr
 }
 
+// newReadRouter creates the read router of cfg after checking that its
+// backends are configured, adding defaultBackend if missing.
+func newReadRouter(cfg *routing.Config, backends map[string]common.Storage, defaultBackend string) (*routing.Router, error) {
+	routed := *cfg
+	routed.Backends = nil
+	hasDefault := false
+	for _, name := range cfg.Backends {
+		if _, ok := backends[name]; !ok {
+			return nil, fmt.Errorf("read routing backend %s not found in configured backends", name)
+		}
+		hasDefault = hasDefault || name == defaultBackend
+	}
+	if !hasDefault {
+		routed.Backends = append(routed.Backends, defaultBackend)
+	}
+	routed.Backends = append(routed.Backends, cfg.Backends...)
+	return routing.New(&routed)
+}
+
 // Reset clears the facade (useful for testing)
 func Reset() {
 	initMu.Lock()
@@ -169,7 +360,27 @@ func Reset() {
 	if facade != nil {
 		facade.mu.Lock()
 		facade.backends = nil
+		collector := facade.stats
+		facade.stats = nil
+		scheduler := facade.jobs
+		facade.jobs = nil
+		queue := facade.tasks
+		facade.tasks = nil
+		trackers := facade.trackers
+		facade.trackers = nil
 		facade.mu.Unlock()
+		if collector != nil {
+			_ = collector.Close()
+		}
+		if scheduler != nil {
+			_ = scheduler.Close()
+		}
+		if queue != nil {
+			_ = queue.Close()
+		}
+		for _, tracker := range trackers {
+			_ = tracker.Close()
+		}
 	}
 
 	facade = nil
@@ -283,6 +494,65 @@ func getStorageForKey(keyRef string) (common.Storage, string, error) {
 	return storage, key, nil
 }
 
+// routedRead calls read with the storage of backend, or of the default
+// backend when backend is empty. When read routing is enabled, reads of
+// the default backend go to the replicas in the router's order instead,
+// moving on to the next replica only when one is unavailable or cannot be
+// reached. A replica that does not have the key answers the read.
+func routedRead[T any](backend, key string, read func(storage common.Storage) (T, error)) (T, error) {
+	var zero T
+	if !IsInitialized() {
+		return zero, ErrNotInitialized
+	}
+
+	facade.mu.RLock()
+	router := facade.router
+	facade.mu.RUnlock()
+
+	if backend != "" || router == nil {
+		var storage common.Storage
+		var err error
+		if backend == "" {
+			storage, err = DefaultBackend()
+		} else {
+			storage, err = Backend(backend)
+		}
+		if err != nil {
+			return zero, err
+		}
+		return read(storage)
+	}
+
+	var lastErr error
+	for _, name := range router.Backends(key) {
+		storage, err := Backend(name)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		start := time.Now()
+		result, err := read(storage)
+		observeRead(router, name, time.Since(start), err)
+		if err == nil {
+			return result, nil
+		}
+		if !nextReplica(err) {
+			return zero, err
+		}
+		lastErr = err
+	}
+	return zero, lastErr
+}
+
+// nextReplica reports whether a routed read that failed with err should
+// move on to the next replica: the replica is unavailable or could not be
+// reached. Any other error, such as a missing key, is the replica's answer.
+func nextReplica(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, common.ErrUnavailable) || errors.As(err, &netErr)
+}
+
 // Simplified API - applications use these functions directly
 
 // Put stores an object in the default backend
@@ -297,7 +567,16 @@ func Put(key string, data io.Reader) error {
 		return err
 	}
 
-	return storage.Put(key, data)
+	ctx, cancel, err := boundContext(context.Background(), opWrite)
+	if err != nil {
+		return err
+	}
+	defer release(cancel)
+
+	if cancel == nil {
+		return storage.Put(key, data)
+	}
+	return storage.PutWithContext(ctx, key, data)
 }
 
 // PutWithContext stores an object with context support
@@ -312,6 +591,12 @@ func PutWithContext(ctx context.Context, keyRef string, data io.Reader) error {
 		return err
 	}
 
+	ctx, cancel, err := boundContext(ctx, opWrite)
+	if err != nil {
+		return err
+	}
+	defer release(cancel)
+
 	return storage.PutWithContext(ctx, key, data)
 }
 
@@ -334,9 +619,45 @@ func PutWithMetadata(ctx context.Context, keyRef string, data io.Reader, metadat
 		return err
 	}
 
+	ctx, cancel, err := boundContext(ctx, opWrite)
+	if err != nil {
+		return err
+	}
+	defer release(cancel)
+
 	return storage.PutWithMetadata(ctx, key, data, metadata)
 }
 
+// PutWithInfo stores an object with metadata and returns what was written:
+// its size, ETag, version and modification time where the backend reports
+// them, so writers need no follow-up metadata read.
+func PutWithInfo(ctx context.Context, keyRef string, data io.Reader, metadata *common.Metadata) (*common.ObjectInfo, error) {
+	// Validate key reference to prevent injection attacks
+	if err := validation.ValidateKeyReference(keyRef); err != nil {
+		return nil, fmt.Errorf("invalid key reference: %w", err)
+	}
+
+	// Validate metadata
+	if metadata != nil && metadata.Custom != nil {
+		if err := common.ValidateMetadata(metadata.Custom); err != nil {
+			return nil, fmt.Errorf("invalid metadata: %w", err)
+		}
+	}
+
+	storage, key, err := getStorageForKey(keyRef)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel, err := boundContext(ctx, opWrite)
+	if err != nil {
+		return nil, err
+	}
+	defer release(cancel)
+
+	return common.PutWithInfo(ctx, storage, key, data, metadata)
+}
+
 // Get retrieves an object from the default backend
 func Get(key string) (io.ReadCloser, error) {
 	// Validate key to prevent injection attacks
@@ -344,12 +665,21 @@ func Get(key string) (io.ReadCloser, error) {
 		return nil, fmt.Errorf("invalid key: %w", err)
 	}
 
-	storage, err := DefaultBackend()
+	ctx, cancel, err := boundContext(context.Background(), opRead)
 	if err != nil {
 		return nil, err
 	}
-
-	return storage.Get(key)
+	rc, err := routedGet(ctx, "", key, func(ctx context.Context, storage common.Storage) (io.ReadCloser, error) {
+		if ctx.Done() == nil {
+			return storage.Get(key)
+		}
+		return storage.GetWithContext(ctx, key)
+	})
+	if err != nil {
+		release(cancel)
+		return nil, err
+	}
+	return closeWith(rc, cancel), nil
 }
 
 // GetWithContext retrieves an object with context support
@@ -360,12 +690,216 @@ func GetWithContext(ctx context.Context, keyRef string) (io.ReadCloser, error) {
 		return nil, fmt.Errorf("invalid key reference: %w", err)
 	}
 
-	storage, key, err := getStorageForKey(keyRef)
+	ctx, cancel, err := boundContext(ctx, opRead)
+	if err != nil {
+		return nil, err
+	}
+	backend, key := parseKeyReference(keyRef)
+	rc, err := routedGet(ctx, backend, key, func(ctx context.Context, storage common.Storage) (io.ReadCloser, error) {
+		return storage.GetWithContext(ctx, key)
+	})
+	if err != nil {
+		release(cancel)
+		return nil, err
+	}
+	return closeWith(rc, cancel), nil
+}
+
+// SelectObjectContent runs an S3 Select-style SQL query over a CSV, JSON
+// or Parquet object and streams the matching records, so only the rows
+// and columns needed leave the server. The caller must close the reader.
+func SelectObjectContent(ctx context.Context, keyRef string, req *query.Request) (*query.Reader, error) {
+	rc, err := GetWithContext(ctx, keyRef)
+	if err != nil {
+		return nil, err
+	}
+	return query.Select(ctx, rc, req)
+}
+
+// Diff compares two objects where they are stored (see package diff), so
+// neither has to be downloaded. Each reference is a key reference,
+// optionally followed by "@" and an RFC 3339 time to compare the version
+// that was current then, read with GetAsOf. The objects may be on
+// different backends.
+func Diff(ctx context.Context, refA, refB string, opts diff.Options) (*diff.Result, error) {
+	a, err := openVersion(ctx, refA)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = a.Close() }()
+	b, err := openVersion(ctx, refB)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = b.Close() }()
+
+	return diff.Compare(diff.Source{Name: refA, Reader: a}, diff.Source{Name: refB, Reader: b}, opts)
+}
+
+// openVersion opens a key reference with an optional "@time" suffix.
+func openVersion(ctx context.Context, ref string) (io.ReadCloser, error) {
+	keyRef, at, ok := diff.SplitVersion(ref)
+	if !ok {
+		return GetWithContext(ctx, keyRef)
+	}
+	reader, _, err := GetAsOf(ctx, keyRef, at)
+	return reader, err
+}
+
+// GetToWriter streams an object into w and returns the number of bytes
+// written. No intermediate buffer or temporary file is used.
+func GetToWriter(ctx context.Context, keyRef string, w io.Writer) (int64, error) {
+	rc, err := GetWithContext(ctx, keyRef)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = rc.Close() }()
+
+	return io.Copy(w, rc)
+}
+
+// PutFromReaderN stores exactly size bytes read from r. The put fails with
+// io.ErrUnexpectedEOF if r ends early and with common.ErrInvalidArgument if
+// r holds more than size bytes, so a truncated or oversized stream is never
+// committed as a complete object.
+func PutFromReaderN(ctx context.Context, keyRef string, r io.Reader, size int64, metadata *common.Metadata) error {
+	if size < 0 {
+		return fmt.Errorf("%w: negative size %d", common.ErrInvalidArgument, size)
+	}
+	return PutWithMetadata(ctx, keyRef, &exactReader{r: r, remaining: size}, metadata)
+}
+
+// exactReader yields exactly remaining bytes from r or fails.
+type exactReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (e *exactReader) Read(p []byte) (int, error) {
+	if e.remaining <= 0 {
+		// Confirm the stream ends where the caller said it would.
+		var probe [1]byte
+		if n, _ := io.ReadFull(e.r, probe[:]); n > 0 {
+			return 0, fmt.Errorf("%w: reader has more data than the declared size", common.ErrInvalidArgument)
+		}
+		return 0, io.EOF
+	}
+	if int64(len(p)) > e.remaining {
+		p = p[:e.remaining]
+	}
+	n, err := e.r.Read(p)
+	e.remaining -= int64(n)
+	if err == io.EOF && e.remaining > 0 {
+		return n, io.ErrUnexpectedEOF
+	}
+	if err == io.EOF {
+		err = nil
+	}
+	return n, err
+}
+
+// OpenRange opens an object for random access. Reads are served by ranged
+// requests to the backend, so the result can back http.ServeContent without
+// downloading the object first. Backends that cannot read byte ranges return
+// ErrSeekNotSupported.
+func OpenRange(ctx context.Context, keyRef string) (io.ReadSeekCloser, error) {
+	// Validate key reference to prevent injection attacks
+	if err := validation.ValidateKeyReference(keyRef); err != nil {
+		return nil, fmt.Errorf("invalid key reference: %w", err)
+	}
+
+	ctx, cancel, err := boundContext(ctx, opRead)
 	if err != nil {
 		return nil, err
 	}
+	backend, key := parseKeyReference(keyRef)
+	reader, err := routedRead(backend, key, func(storage common.Storage) (io.ReadSeekCloser, error) {
+		// Wrappers fall back to reading the whole object for a range, so
+		// seeking is only worthwhile when the backend itself serves ranges.
+		reader, ok := storage.(common.RangeReader)
+		if _, native := baseBackend(storage).(common.RangeReader); !ok || !native {
+			return nil, fmt.Errorf("%w: %s", ErrSeekNotSupported, keyRef)
+		}
+
+		metadata, err := storage.GetMetadata(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		return common.NewRangeReadSeeker(ctx, reader, key, metadata.Size), nil
+	})
+	if err != nil || cancel == nil {
+		release(cancel)
+		return reader, err
+	}
+	return &cancelOnCloseSeeker{ReadSeekCloser: reader, cancel: cancel}, nil
+}
+
+// GetAsOf reads an object as it was at the given time on a versioned
+// backend (common.AsOfReader), returning the content and metadata of the
+// version that was current then. The caller must close the reader.
+//
+// The read goes through the backend's wrappers like Get does: reserved
+// keys are refused, deleted objects are not found, and encrypted or
+// compressed content is decoded.
+func GetAsOf(ctx context.Context, keyRef string, at time.Time) (io.ReadCloser, *common.Metadata, error) {
+	// Validate key reference to prevent injection attacks
+	if err := validation.ValidateKeyReference(keyRef); err != nil {
+		return nil, nil, fmt.Errorf("invalid key reference: %w", err)
+	}
+
+	storage, key, err := getStorageForKey(keyRef)
+	if err != nil {
+		return nil, nil, err
+	}
+	ctx, cancel, err := boundContext(ctx, opRead)
+	if err != nil {
+		return nil, nil, err
+	}
+	rc, metadata, err := common.GetAsOf(ctx, storage, key, at)
+	if err != nil {
+		release(cancel)
+		return nil, nil, err
+	}
+	return closeWith(rc, cancel), metadata, nil
+}
+
+// storageWrapper is a backend layer that wraps another backend.
+type storageWrapper interface {
+	Underlying() common.Storage
+}
+
+// findCapability walks the wrapper chain of storage, following Underlying,
+// down to the first layer that is a T.
+func findCapability[T any](storage common.Storage) (T, bool) {
+	for storage != nil {
+		if capability, ok := storage.(T); ok {
+			return capability, true
+		}
+		wrapper, ok := storage.(storageWrapper)
+		if !ok {
+			break
+		}
+		storage = wrapper.Underlying()
+	}
+	var zero T
+	return zero, false
+}
+
+// baseBackend returns the innermost backend of storage's wrapper chain.
+func baseBackend(storage common.Storage) common.Storage {
+	for {
+		wrapper, ok := storage.(storageWrapper)
+		if !ok {
+			return storage
+		}
+		storage = wrapper.Underlying()
+	}
+}
 
-	return storage.GetWithContext(ctx, key)
+// conditionalStorage is a backend layer that can write conditionally.
+type conditionalStorage interface {
+	common.Storage
+	common.ConditionalWriter
 }
 
 // GetMetadata retrieves metadata for an object
@@ -375,12 +909,16 @@ func GetMetadata(ctx context.Context, keyRef string) (*common.Metadata, error) {
 		return nil, fmt.Errorf("invalid key reference: %w", err)
 	}
 
-	storage, key, err := getStorageForKey(keyRef)
+	ctx, cancel, err := boundContext(ctx, opRead)
 	if err != nil {
 		return nil, err
 	}
+	defer release(cancel)
 
-	return storage.GetMetadata(ctx, key)
+	backend, key := parseKeyReference(keyRef)
+	return routedRead(backend, key, func(storage common.Storage) (*common.Metadata, error) {
+		return storage.GetMetadata(ctx, key)
+	})
 }
 
 // UpdateMetadata updates metadata for an object
@@ -402,9 +940,48 @@ func UpdateMetadata(ctx context.Context, keyRef string, metadata *common.Metadat
 		return err
 	}
 
+	ctx, cancel, err := boundContext(ctx, opWrite)
+	if err != nil {
+		return err
+	}
+	defer release(cancel)
+
 	return storage.UpdateMetadata(ctx, key, metadata)
 }
 
+// UpdateMetadataBatch applies patch to the metadata of every object under
+// prefix on a backend, without rewriting their data, and returns the number
+// of objects changed. See common.UpdateMetadataBatch.
+func UpdateMetadataBatch(ctx context.Context, backendName, prefix string, patch *common.MetadataPatch) (int, error) {
+	var storage common.Storage
+	var err error
+	if backendName == "" {
+		storage, err = DefaultBackend()
+	} else {
+		if err := validation.ValidateBackendName(backendName); err != nil {
+			return 0, fmt.Errorf("invalid backend name: %w", err)
+		}
+		storage, err = Backend(backendName)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if prefix != "" {
+		if err := validation.ValidatePrefix(prefix); err != nil {
+			return 0, fmt.Errorf("invalid prefix: %w", err)
+		}
+	}
+
+	ctx, cancel, err := boundContext(ctx, opAdmin)
+	if err != nil {
+		return 0, err
+	}
+	defer release(cancel)
+
+	return common.UpdateMetadataBatch(ctx, storage, prefix, patch)
+}
+
 // Delete removes an object
 func Delete(key string) error {
 	// Validate key to prevent injection attacks
@@ -417,7 +994,16 @@ func Delete(key string) error {
 		return err
 	}
 
-	return storage.Delete(key)
+	ctx, cancel, err := boundContext(context.Background(), opWrite)
+	if err != nil {
+		return err
+	}
+	defer release(cancel)
+
+	if cancel == nil {
+		return storage.Delete(key)
+	}
+	return storage.DeleteWithContext(ctx, key)
 }
 
 // DeleteWithContext removes an object with context support
@@ -432,6 +1018,12 @@ func DeleteWithContext(ctx context.Context, keyRef string) error {
 		return err
 	}
 
+	ctx, cancel, err := boundContext(ctx, opWrite)
+	if err != nil {
+		return err
+	}
+	defer release(cancel)
+
 	return storage.DeleteWithContext(ctx, key)
 }
 
@@ -442,12 +1034,16 @@ func Exists(ctx context.Context, keyRef string) (bool, error) {
 		return false, fmt.Errorf("invalid key reference: %w", err)
 	}
 
-	storage, key, err := getStorageForKey(keyRef)
+	ctx, cancel, err := boundContext(ctx, opRead)
 	if err != nil {
 		return false, err
 	}
+	defer release(cancel)
 
-	return storage.Exists(ctx, key)
+	backend, key := parseKeyReference(keyRef)
+	return routedRead(backend, key, func(storage common.Storage) (bool, error) {
+		return storage.Exists(ctx, key)
+	})
 }
 
 // List returns a list of keys with the given prefix
@@ -462,7 +1058,16 @@ func List(prefix string) ([]string, error) {
 		return nil, err
 	}
 
-	return storage.List(prefix)
+	ctx, cancel, err := boundContext(context.Background(), opList)
+	if err != nil {
+		return nil, err
+	}
+	defer release(cancel)
+
+	if cancel == nil {
+		return storage.List(prefix)
+	}
+	return storage.ListWithContext(ctx, prefix)
 }
 
 // ListWithContext returns a list of keys with context support
@@ -488,10 +1093,17 @@ func ListWithContext(ctx context.Context, keyRef string) ([]string, error) {
 		return nil, err
 	}
 
+	ctx, cancel, err := boundContext(ctx, opList)
+	if err != nil {
+		return nil, err
+	}
+	defer release(cancel)
+
 	return storage.ListWithContext(ctx, prefix)
 }
 
-// ListWithOptions returns a paginated list of objects with full metadata
+// ListWithOptions returns a paginated list of objects with full metadata,
+// each page ordered by opts.SortBy
 func ListWithOptions(ctx context.Context, backendName string, opts *common.ListOptions) (*common.ListResult, error) {
 	// Validate backend name if provided
 	var storage common.Storage
@@ -516,57 +1128,2702 @@ func ListWithOptions(ctx context.Context, backendName string, opts *common.ListO
 			return nil, fmt.Errorf("invalid prefix in options: %w", err)
 		}
 	}
-
-	return storage.ListWithOptions(ctx, opts)
-}
-
-// Archive copies an object to an archiver
-func Archive(keyRef string, destination common.Archiver) error {
-	// Validate key reference to prevent injection attacks
-	if err := validation.ValidateKeyReference(keyRef); err != nil {
-		return fmt.Errorf("invalid key reference: %w", err)
+	if opts != nil {
+		if _, err := common.ParseListSort(string(opts.SortBy)); err != nil {
+			return nil, err
+		}
 	}
 
-	storage, key, err := getStorageForKey(keyRef)
+	ctx, cancel, err := boundContext(ctx, opList)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	defer release(cancel)
 
-	return storage.Archive(key, destination)
+	result, err := storage.ListWithOptions(ctx, opts)
+	if err != nil || opts == nil || result == nil {
+		return result, err
+	}
+	if err := common.SortObjects(result.Objects, opts.SortBy, opts.SortDescending); err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
-// AddPolicy adds a lifecycle policy to a backend
-func AddPolicy(backendName string, policy common.LifecyclePolicy) error {
-	// Validate backend name if provided
+// Browse returns the folders and objects directly under prefix on a backend,
+// optionally with the number and size of the objects in each folder. See
+// common.Browse.
+func Browse(ctx context.Context, backendName, prefix string, opts *common.BrowseOptions) (*common.BrowseResult, error) {
 	var storage common.Storage
 	var err error
-
 	if backendName == "" {
 		storage, err = DefaultBackend()
 	} else {
 		if err := validation.ValidateBackendName(backendName); err != nil {
-			return fmt.Errorf("invalid backend name: %w", err)
+			return nil, fmt.Errorf("invalid backend name: %w", err)
 		}
 		storage, err = Backend(backendName)
 	}
-
+	if err != nil {
+		return nil, err
+	}
+
+	if prefix != "" {
+		if err := validation.ValidatePrefix(prefix); err != nil {
+			return nil, fmt.Errorf("invalid prefix: %w", err)
+		}
+	}
+
+	ctx, cancel, err := boundContext(ctx, opList)
+	if err != nil {
+		return nil, err
+	}
+	defer release(cancel)
+
+	return common.Browse(ctx, storage, prefix, opts)
+}
+
+// Watch reports objects created, modified or deleted under the prefix in
+// keyRef ("backend:prefix" or a bare prefix on the default backend) until ctx
+// is done. See storagefs.StorageFS.Watch for how changes are detected.
+func Watch(ctx context.Context, keyRef string, opts *storagefs.WatchOptions) (<-chan storagefs.Event, error) {
+	backend, prefix := parseKeyReference(keyRef)
+
+	if err := validation.ValidatePrefix(prefix); err != nil {
+		return nil, fmt.Errorf("invalid prefix: %w", err)
+	}
+
+	var storage common.Storage
+	var err error
+
+	if backend == "" {
+		storage, err = DefaultBackend()
+	} else {
+		storage, err = Backend(backend)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return storagefs.New(storage).Watch(ctx, prefix, opts)
+}
+
+// Archive copies an object to an archiver
+func Archive(keyRef string, destination common.Archiver) error {
+	// Validate key reference to prevent injection attacks
+	if err := validation.ValidateKeyReference(keyRef); err != nil {
+		return fmt.Errorf("invalid key reference: %w", err)
+	}
+
+	storage, key, err := getStorageForKey(keyRef)
+	if err != nil {
+		return err
+	}
+
+	return storage.Archive(key, destination)
+}
+
+// RestoreFromArchive copies sourceKey from source back into keyRef, keeping
+// its content type, encoding and custom metadata. An empty sourceKey uses
+// the key of keyRef. source must be readable, so archive-only backends such
+// as Glacier cannot be restored from directly.
+func RestoreFromArchive(ctx context.Context, keyRef string, source common.Storage, sourceKey string) error {
+	// Validate key reference to prevent injection attacks
+	if err := validation.ValidateKeyReference(keyRef); err != nil {
+		return fmt.Errorf("invalid key reference: %w", err)
+	}
+
+	storage, key, err := getStorageForKey(keyRef)
+	if err != nil {
+		return err
+	}
+	if sourceKey == "" {
+		sourceKey = key
+	}
+	if err := validation.ValidateKey(sourceKey); err != nil {
+		return fmt.Errorf("invalid source key: %w", err)
+	}
+
+	ctx, cancel, err := boundContext(ctx, opWrite)
+	if err != nil {
+		return err
+	}
+	defer release(cancel)
+
+	var metadata *common.Metadata
+	if m, err := source.GetMetadata(ctx, sourceKey); err == nil && m != nil {
+		metadata = &common.Metadata{
+			ContentType:     m.ContentType,
+			ContentEncoding: m.ContentEncoding,
+			Custom:          m.Custom,
+		}
+	}
+
+	rc, err := source.GetWithContext(ctx, sourceKey)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rc.Close() }()
+
+	if metadata == nil {
+		return storage.PutWithContext(ctx, key, rc)
+	}
+	return storage.PutWithMetadata(ctx, key, rc, metadata)
+}
+
+// RenamePrefix moves every object under oldPrefix on a backend to the same
+// key under newPrefix with parallel server-side copies and deletes. See
+// common.RenamePrefix.
+func RenamePrefix(ctx context.Context, backendName, oldPrefix, newPrefix string, opts *common.RenameOptions) (*common.RenameResult, error) {
+	var storage common.Storage
+	var err error
+	if backendName == "" {
+		storage, err = DefaultBackend()
+	} else {
+		if err := validation.ValidateBackendName(backendName); err != nil {
+			return nil, fmt.Errorf("invalid backend name: %w", err)
+		}
+		storage, err = Backend(backendName)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, prefix := range []string{oldPrefix, newPrefix} {
+		if err := validation.ValidatePrefix(prefix); err != nil {
+			return nil, fmt.Errorf("invalid prefix: %w", err)
+		}
+	}
+
+	ctx, cancel, err := boundContext(ctx, opAdmin)
+	if err != nil {
+		return nil, err
+	}
+	defer release(cancel)
+
+	return common.RenamePrefix(ctx, storage, oldPrefix, newPrefix, opts)
+}
+
+// PutAtomic stores data under keyRef so that readers never observe a
+// partially written object: the data is staged under a hidden key, verified
+// and only then promoted. See common.PutAtomic.
+func PutAtomic(ctx context.Context, keyRef string, data io.Reader, metadata *common.Metadata) error {
+	// Validate key reference to prevent injection attacks
+	if err := validation.ValidateKeyReference(keyRef); err != nil {
+		return fmt.Errorf("invalid key reference: %w", err)
+	}
+
+	storage, key, err := getStorageForKey(keyRef)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel, err := boundContext(ctx, opWrite)
+	if err != nil {
+		return err
+	}
+	defer release(cancel)
+
+	return common.PutAtomic(ctx, storage, key, data, metadata)
+}
+
+// Append adds data to the end of an object, creating it if it does not exist.
+// Backends without native append support rewrite the object.
+func Append(ctx context.Context, keyRef string, data io.Reader) error {
+	// Validate key reference to prevent injection attacks
+	if err := validation.ValidateKeyReference(keyRef); err != nil {
+		return fmt.Errorf("invalid key reference: %w", err)
+	}
+
+	storage, key, err := getStorageForKey(keyRef)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel, err := boundContext(ctx, opWrite)
+	if err != nil {
+		return err
+	}
+	defer release(cancel)
+
+	return common.Append(ctx, storage, key, data)
+}
+
+// Compose writes the concatenation of srcRefs, in order, to destRef.
+// All objects must be on the same backend.
+func Compose(ctx context.Context, destRef string, srcRefs ...string) error {
+	// Validate key references to prevent injection attacks
+	if err := validation.ValidateKeyReference(destRef); err != nil {
+		return fmt.Errorf("invalid key reference: %w", err)
+	}
+
+	storage, destKey, err := getStorageForKey(destRef)
+	if err != nil {
+		return err
+	}
+
+	srcKeys := make([]string, len(srcRefs))
+	for i, ref := range srcRefs {
+		if err := validation.ValidateKeyReference(ref); err != nil {
+			return fmt.Errorf("invalid key reference: %w", err)
+		}
+		srcStorage, key, err := getStorageForKey(ref)
+		if err != nil {
+			return err
+		}
+		if srcStorage != storage {
+			return fmt.Errorf("%w: compose source %s is not on the destination backend", common.ErrInvalidArgument, ref)
+		}
+		srcKeys[i] = key
+	}
+
+	ctx, cancel, err := boundContext(ctx, opWrite)
+	if err != nil {
+		return err
+	}
+	defer release(cancel)
+
+	return common.Compose(ctx, storage, destKey, srcKeys...)
+}
+
+// AddPolicy adds a lifecycle policy to a backend
+func AddPolicy(backendName string, policy common.LifecyclePolicy) error {
+	// Validate backend name if provided
+	var storage common.Storage
+	var err error
+
+	if backendName == "" {
+		storage, err = DefaultBackend()
+	} else {
+		if err := validation.ValidateBackendName(backendName); err != nil {
+			return fmt.Errorf("invalid backend name: %w", err)
+		}
+		storage, err = Backend(backendName)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// Validate policy prefix
+	if policy.Prefix != "" {
+		if err := validation.ValidatePrefix(policy.Prefix); err != nil {
+			return fmt.Errorf("invalid policy prefix: %w", err)
+		}
+	}
+
+	return storage.AddPolicy(policy)
+}
+
+// RemovePolicy removes a lifecycle policy from a backend
+func RemovePolicy(backendName string, policyID string) error {
+	// Validate backend name if provided
+	var storage common.Storage
+	var err error
+
+	if backendName == "" {
+		storage, err = DefaultBackend()
+	} else {
+		if err := validation.ValidateBackendName(backendName); err != nil {
+			return fmt.Errorf("invalid backend name: %w", err)
+		}
+		storage, err = Backend(backendName)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return storage.RemovePolicy(policyID)
+}
+
+// GetPolicies retrieves all lifecycle policies from a backend
+func GetPolicies(backendName string) ([]common.LifecyclePolicy, error) {
+	// Validate backend name if provided
+	var storage common.Storage
+	var err error
+
+	if backendName == "" {
+		storage, err = DefaultBackend()
+	} else {
+		if err := validation.ValidateBackendName(backendName); err != nil {
+			return nil, fmt.Errorf("invalid backend name: %w", err)
+		}
+		storage, err = Backend(backendName)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return storage.GetPolicies()
+}
+
+// ApplyPolicies applies the lifecycle policies of a backend once: every
+// object whose common.MetaExpiresAt time has passed is deleted, and every
+// other object under a policy's prefix that is older than its retention is
+// deleted, archived or moved to the policy's storage class. It returns the
+// number of objects changed. Objects that cannot be changed are skipped
+// and the first such error is returned after the pass.
+func ApplyPolicies(ctx context.Context, backendName string) (int, error) {
+	var storage common.Storage
+	var err error
+
+	if backendName == "" {
+		storage, err = DefaultBackend()
+	} else {
+		if err := validation.ValidateBackendName(backendName); err != nil {
+			return 0, fmt.Errorf("invalid backend name: %w", err)
+		}
+		storage, err = Backend(backendName)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, cancel, err := boundContext(ctx, opAdmin)
+	if err != nil {
+		return 0, err
+	}
+	defer release(cancel)
+
+	policies, err := storage.GetPolicies()
+	if err != nil {
+		return 0, err
+	}
+	return applyAll(ctx, storage, policies)
+}
+
+// DeleteExpired deletes every object of a backend whose
+// common.MetaExpiresAt time has passed and returns the number deleted.
+// ApplyPolicies does the same as part of its pass; DeleteExpired is for
+// callers that apply the policies themselves. Objects that cannot be
+// deleted are skipped, so they are retried on the next run; the first such
+// error is logged and returned after the pass.
+func DeleteExpired(ctx context.Context, backendName string) (int, error) {
+	var storage common.Storage
+	var err error
+
+	if backendName == "" {
+		storage, err = DefaultBackend()
+	} else {
+		if err := validation.ValidateBackendName(backendName); err != nil {
+			return 0, fmt.Errorf("invalid backend name: %w", err)
+		}
+		storage, err = Backend(backendName)
+	}
+	if err != nil {
+		return 0, err
+	}
+	ctx, cancel, err := boundContext(ctx, opAdmin)
+	if err != nil {
+		return 0, err
+	}
+	defer release(cancel)
+
+	deleted, err := applyAll(ctx, storage, nil)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to delete expired objects", "backend", backendName, "deleted", deleted, "error", err)
+	}
+	return deleted, err
+}
+
+// applyAll applies policies to every object of storage in one pass. With no
+// policies only expired objects are deleted. Failures are published as
+// events.EventPolicyFailed when notifications are enabled.
+func applyAll(ctx context.Context, storage common.Storage, policies []common.LifecyclePolicy) (int, error) {
+	notifier, _ := findNotifier(storage)
+	failed := func(key string, err error) {
+		if notifier != nil {
+			notifier.Alert(ctx, events.EventPolicyFailed, key, map[string]string{events.DetailError: err.Error()})
+		}
+	}
+	processed := 0
+	var firstErr error
+	opts := &common.ListOptions{}
+	for {
+		result, err := storage.ListWithOptions(ctx, opts)
+		if err != nil {
+			failed("", err)
+			return processed, err
+		}
+		for _, obj := range result.Objects {
+			if obj == nil || obj.Metadata == nil {
+				continue
+			}
+			changed, err := applyPolicies(ctx, storage, policies, obj)
+			if err != nil {
+				failed(obj.Key, err)
+			}
+			if err != nil && firstErr == nil {
+				firstErr = fmt.Errorf("failed to apply lifecycle policy to %s: %w", obj.Key, err)
+			}
+			if changed {
+				processed++
+			}
+		}
+		if result.NextToken == "" || result.NextToken == opts.ContinueFrom {
+			break
+		}
+		opts.ContinueFrom = result.NextToken
+	}
+	return processed, firstErr
+}
+
+// applyPolicies deletes obj if it has expired, and otherwise applies the
+// first policy that matches it.
+func applyPolicies(ctx context.Context, storage common.Storage, policies []common.LifecyclePolicy, obj *common.ObjectInfo) (bool, error) {
+	metadata := obj.Metadata
+	if metadata.Custom == nil {
+		// Listings of some backends omit custom metadata.
+		if stored, err := storage.GetMetadata(ctx, obj.Key); err == nil && stored != nil {
+			metadata = stored
+		}
+	}
+	if common.Expired(metadata, time.Now()) {
+		err := storage.DeleteWithContext(ctx, obj.Key)
+		return err == nil, err
+	}
+
+	for _, policy := range policies {
+		if !strings.HasPrefix(obj.Key, policy.Prefix) || time.Since(obj.Metadata.LastModified) <= policy.Retention {
+			continue
+		}
+		switch policy.Action {
+		case "delete":
+			err := storage.DeleteWithContext(ctx, obj.Key)
+			return err == nil, err
+		case "archive":
+			if policy.Destination == nil {
+				continue
+			}
+			err := storage.Archive(obj.Key, policy.Destination)
+			return err == nil, err
+		case common.ActionTransition:
+			if obj.Metadata.StorageClass == policy.StorageClass {
+				continue
+			}
+			metadata, err := storage.GetMetadata(ctx, obj.Key)
+			if err != nil {
+				return false, err
+			}
+			metadata.StorageClass = policy.StorageClass
+			err = storage.UpdateMetadata(ctx, obj.Key, metadata)
+			return err == nil, err
+		}
+	}
+	return false, nil
+}
+
+// GetReplicationManager returns the replication manager for a backend if supported
+func GetReplicationManager(backendName string) (common.ReplicationManager, error) {
+	// Validate backend name if provided
+	var storage common.Storage
+	var err error
+
+	if backendName == "" {
+		storage, err = DefaultBackend()
+	} else {
+		if err := validation.ValidateBackendName(backendName); err != nil {
+			return nil, fmt.Errorf("invalid backend name: %w", err)
+		}
+		storage, err = Backend(backendName)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return findReplicationManager(storage)
+}
+
+// findReplicationManager returns the replication manager of storage or of
+// the backend it wraps.
+func findReplicationManager(storage common.Storage) (common.ReplicationManager, error) {
+	if replicable, ok := findCapability[common.ReplicationCapable](storage); ok {
+		return replicable.GetReplicationManager()
+	}
+	return nil, common.ErrReplicationNotSupported
+}
+
+// ReplicationLag describes an enabled replication policy whose last
+// successful sync is older than an alert threshold.
+type ReplicationLag struct {
+	PolicyID string
+	// LastSync is when the policy last synced successfully; zero if it
+	// never has.
+	LastSync time.Time
+	// Lag is the time since LastSync; zero if the policy never synced.
+	Lag time.Duration
+}
+
+// CheckReplicationLag returns the enabled replication policies of a
+// backend that have not synced successfully within threshold, including
+// policies that never have. When notifications are enabled, each is also
+// published as events.EventReplicationLagExceeded. Call it after a sync so
+// policies that were just added are not reported before their first sync.
+func CheckReplicationLag(ctx context.Context, backendName string, threshold time.Duration) ([]ReplicationLag, error) {
+	if threshold <= 0 {
+		return nil, fmt.Errorf("%w: replication lag threshold must be positive", common.ErrInvalidArgument)
+	}
+	var storage common.Storage
+	var err error
+	if backendName == "" {
+		storage, err = DefaultBackend()
+	} else {
+		if err := validation.ValidateBackendName(backendName); err != nil {
+			return nil, fmt.Errorf("invalid backend name: %w", err)
+		}
+		storage, err = Backend(backendName)
+	}
+	if err != nil {
+		return nil, err
+	}
+	manager, err := findReplicationManager(storage)
+	if err != nil {
+		return nil, err
+	}
+	policies, err := manager.GetPolicies()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel, err := boundContext(ctx, opAdmin)
+	if err != nil {
+		return nil, err
+	}
+	defer release(cancel)
+
+	notifier, _ := findNotifier(storage)
+	now := time.Now()
+	var lagging []ReplicationLag
+	for _, policy := range policies {
+		if !policy.Enabled {
+			continue
+		}
+		lag := ReplicationLag{PolicyID: policy.ID, LastSync: policy.LastSyncTime}
+		details := map[string]string{
+			events.DetailPolicy:    policy.ID,
+			events.DetailThreshold: threshold.String(),
+			events.DetailLastSync:  "never",
+		}
+		if !policy.LastSyncTime.IsZero() {
+			lag.Lag = now.Sub(policy.LastSyncTime)
+			if lag.Lag <= threshold {
+				continue
+			}
+			details[events.DetailLag] = lag.Lag.Round(time.Second).String()
+			details[events.DetailLastSync] = policy.LastSyncTime.UTC().Format(time.RFC3339)
+		}
+		lagging = append(lagging, lag)
+		if notifier != nil {
+			notifier.Alert(ctx, events.EventReplicationLagExceeded, "", details)
+		}
+	}
+	return lagging, nil
+}
+
+// ReplicationConfig contains configuration for enabling replication on a backend
+type ReplicationConfig struct {
+	// PolicyFilePath is the path to the replication policy file.
+	// If empty, defaults to ".replication-policies.json" in the current directory.
+	PolicyFilePath string
+
+	// Interval is the interval between automatic sync operations.
+	// If zero, defaults to 5 minutes.
+	Interval time.Duration
+
+	// Logger is the logger to use for replication operations.
+	// If nil, a no-op logger is used.
+	Logger adapters.Logger
+
+	// AuditLog is the audit logger to use for replication operations.
+	// If nil, a no-op audit logger is used.
+	AuditLog audit.AuditLogger
+
+	// RunInBackground starts a background goroutine to run periodic syncs.
+	// If false, syncs must be triggered manually.
+	RunInBackground bool
+
+	// FileSystem stores the policy file. If nil, the OS file system is used.
+	FileSystem replication.FileSystem
+
+	// Shared reloads the policy file before every read and change, for a
+	// FileSystem that other instances write too (see ha.Cluster).
+	Shared bool
+
+	// IsLeader, if set, limits periodic syncs to when it returns true, so
+	// only one of several instances sharing policies runs them.
+	IsLeader func() bool
+}
+
+// ReplicationManagerSetter is an interface for backends that can have a replication manager set
+type ReplicationManagerSetter interface {
+	SetReplicationManager(rm common.ReplicationManager)
+}
+
+// EnableReplication creates and configures a replication manager for a backend.
+// The backend must implement both ReplicationCapable and ReplicationManagerSetter interfaces.
+// This function creates a PersistentReplicationManager using the OSFileSystem,
+// or config.FileSystem if set, and attaches it to the backend.
+//
+// Example usage:
+//
+//	objstore.EnableReplication("local", &objstore.ReplicationConfig{
+//	    PolicyFilePath: "/data/replication-policies.json",
+//	    Interval:       10 * time.Minute,
+//	    RunInBackground: true,
+//	})
+func EnableReplication(backendName string, config *ReplicationConfig) error {
+	if config == nil {
+		config = &ReplicationConfig{}
+	}
+
+	// Get the backend
+	var storage common.Storage
+	var err error
+
+	if backendName == "" {
+		storage, err = DefaultBackend()
+	} else {
+		if err := validation.ValidateBackendName(backendName); err != nil {
+			return fmt.Errorf("invalid backend name: %w", err)
+		}
+		storage, err = Backend(backendName)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// Check if backend, or one it wraps, supports setting replication manager
+	setter, ok := findCapability[ReplicationManagerSetter](storage)
+	if !ok {
+		return fmt.Errorf("backend does not support setting replication manager")
+	}
+
+	// Set defaults
+	policyFile := config.PolicyFilePath
+	if policyFile == "" {
+		policyFile = ".replication-policies.json"
+	}
+
+	interval := config.Interval
+	if interval == 0 {
+		interval = 5 * time.Minute
+	}
+
+	logger := config.Logger
+	if logger == nil {
+		logger = adapters.NewNoOpLogger()
+	}
+
+	auditLog := config.AuditLog
+	if auditLog == nil {
+		auditLog = audit.NewNoOpAuditLogger()
+	}
+
+	fs := config.FileSystem
+	if fs == nil {
+		fs = &replication.OSFileSystem{}
+	}
+
+	// Create the replication manager
+	rm, err := replication.NewPersistentReplicationManager(
+		fs,
+		policyFile,
+		interval,
+		logger,
+		auditLog,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create replication manager: %w", err)
+	}
+	rm.SetShared(config.Shared)
+	if config.IsLeader != nil {
+		rm.SetLeaderFunc(config.IsLeader)
+	}
+
+	// Set the replication manager on the backend
+	setter.SetReplicationManager(rm)
+
+	// Start background processing if requested
+	if config.RunInBackground {
+		go rm.Run(context.Background())
+	}
+
+	return nil
+}
+
+// FailoverConfig contains configuration for enabling failover on a backend
+type FailoverConfig struct {
+	// Secondary is the name of the backend operations are routed to while
+	// the primary is unhealthy. It must already be registered.
+	Secondary string
+
+	// Health configures the primary's health checks. If nil, the primary
+	// is probed with health.ProbeExists using the health package defaults.
+	Health *health.Config
+
+	// ReadOnlySecondary only serves reads from the secondary; writes fail
+	// with health.ErrSecondaryReadOnly while failed over.
+	ReadOnlySecondary bool
+
+	// Logger logs failovers and failbacks.
+	// If nil, a no-op logger is used.
+	Logger adapters.Logger
+
+	// AuditLog records failovers and failbacks.
+	// If nil, a no-op audit logger is used.
+	AuditLog audit.AuditLogger
+
+	// OnTransition, if set, is called after every failover and failback.
+	OnTransition func(health.Transition)
+}
+
+// EnableFailover monitors the health of a backend in the background and
+// routes its reads and writes to a secondary backend while it is
+// unhealthy, failing back once it recovers. The monitor returned by Health
+// should be closed on shutdown.
+//
+// Call EnableFailover after EnableReplication and before the other
+// wrappers, so locks, retention, search and the change feed see the
+// operations of whichever backend is active.
+//
+// Example usage:
+//
+//	objstore.EnableFailover("primary", &objstore.FailoverConfig{
+//	    Secondary: "secondary",
+//	    Health:    &health.Config{Probe: health.ProbeList, Interval: 5 * time.Second},
+//	})
+func EnableFailover(backendName string, config *FailoverConfig) error {
+	if config == nil {
+		config = &FailoverConfig{}
+	}
+
+	if !IsInitialized() {
+		return ErrNotInitialized
+	}
+
+	name := backendName
+	if name == "" {
+		facade.mu.RLock()
+		name = facade.defaultBackend
+		facade.mu.RUnlock()
+	} else if err := validation.ValidateBackendName(name); err != nil {
+		return fmt.Errorf("invalid backend name: %w", err)
+	}
+	if config.Secondary == "" || config.Secondary == name {
+		return fmt.Errorf("%w: failover needs a secondary backend other than %s", common.ErrInvalidArgument, name)
+	}
+	if err := validation.ValidateBackendName(config.Secondary); err != nil {
+		return fmt.Errorf("invalid secondary backend name: %w", err)
+	}
+
+	storage, err := Backend(name)
+	if err != nil {
+		return err
+	}
+	if _, err := findMonitor(storage); err == nil {
+		return nil
+	}
+	secondary, err := Backend(config.Secondary)
+	if err != nil {
+		return fmt.Errorf("secondary backend %s: %w", config.Secondary, err)
+	}
+
+	monitor, err := health.NewMonitor(name, storage, config.Health)
+	if err != nil {
+		return err
+	}
+	failover := health.NewFailoverStorage(storage, secondary, monitor, &health.FailoverConfig{
+		Secondary:    config.Secondary,
+		ReadOnly:     config.ReadOnlySecondary,
+		Logger:       config.Logger,
+		AuditLog:     config.AuditLog,
+		OnTransition: config.OnTransition,
+	})
+	monitor.Start()
+
+	facade.mu.Lock()
+	facade.backends[name] = failover
+	facade.mu.Unlock()
+
+	return nil
+}
+
+// Health returns the health monitor of a backend. Failover must first be
+// enabled with EnableFailover.
+func Health(backendName string) (*health.Monitor, error) {
+	var storage common.Storage
+	var err error
+
+	if backendName == "" {
+		storage, err = DefaultBackend()
+	} else {
+		if err := validation.ValidateBackendName(backendName); err != nil {
+			return nil, fmt.Errorf("invalid backend name: %w", err)
+		}
+		storage, err = Backend(backendName)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return findMonitor(storage)
+}
+
+// findMonitor looks for a failover wrapper in storage's chain of wrapped
+// backends.
+func findMonitor(storage common.Storage) (*health.Monitor, error) {
+	if failover, ok := findCapability[*health.FailoverStorage](storage); ok {
+		return failover.Monitor(), nil
+	}
+	return nil, ErrFailoverNotEnabled
+}
+
+// EnableConcurrencyLimit limits the operations in flight against a backend
+// (empty name selects the default backend) with an adaptive limit that
+// grows while the backend keeps up and shrinks when it throttles, fails as
+// unavailable or slows down, so retries queue instead of amplifying the
+// overload. See package concurrency. A nil cfg uses the package defaults.
+// Enabling it again has no effect.
+//
+// Call EnableConcurrencyLimit first, so the limit applies to the backend's
+// own operations rather than to those of other features.
+func EnableConcurrencyLimit(backendName string, cfg *concurrency.Config) error {
+	if !IsInitialized() {
+		return ErrNotInitialized
+	}
+
+	name := backendName
+	if name == "" {
+		facade.mu.RLock()
+		name = facade.defaultBackend
+		facade.mu.RUnlock()
+	} else if err := validation.ValidateBackendName(name); err != nil {
+		return fmt.Errorf("invalid backend name: %w", err)
+	}
+
+	storage, err := Backend(name)
+	if err != nil {
+		return err
+	}
+	if _, err := findLimiter(storage); err == nil {
+		return nil
+	}
+	limiter, err := concurrency.NewLimiter(name, cfg)
+	if err != nil {
+		return err
+	}
+
+	facade.mu.Lock()
+	facade.backends[name] = concurrency.NewStorage(storage, limiter)
+	facade.mu.Unlock()
+
+	return nil
+}
+
+// ConcurrencyLimiter returns the concurrency limiter of a backend (empty
+// name selects the default backend).
+func ConcurrencyLimiter(backendName string) (*concurrency.Limiter, error) {
+	var storage common.Storage
+	var err error
+	if backendName == "" {
+		storage, err = DefaultBackend()
+	} else {
+		if err := validation.ValidateBackendName(backendName); err != nil {
+			return nil, fmt.Errorf("invalid backend name: %w", err)
+		}
+		storage, err = Backend(backendName)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return findLimiter(storage)
+}
+
+// findLimiter walks the wrapper chain of storage to its concurrency
+// limiter.
+func findLimiter(storage common.Storage) (*concurrency.Limiter, error) {
+	if limited, ok := findCapability[*concurrency.Storage](storage); ok {
+		return limited.Limiter(), nil
+	}
+	return nil, ErrConcurrencyLimitNotEnabled
+}
+
+// EnableChecksums records the SHA-256 and MD5 digests of objects written to
+// a backend through the facade (empty name selects the default backend), so
+// replication, caches and clients can compare objects across providers
+// whose ETags differ. See package checksum.
+//
+// Call EnableChecksums before EnableContentPolicy and EnableReplication.
+func EnableChecksums(backendName string) error {
+	if !IsInitialized() {
+		return ErrNotInitialized
+	}
+
+	name := backendName
+	if name == "" {
+		facade.mu.RLock()
+		name = facade.defaultBackend
+		facade.mu.RUnlock()
+	} else if err := validation.ValidateBackendName(name); err != nil {
+		return fmt.Errorf("invalid backend name: %w", err)
+	}
+
+	storage, err := Backend(name)
+	if err != nil {
+		return err
+	}
+	if _, ok := storage.(*checksum.Storage); ok {
+		return nil
+	}
+
+	facade.mu.Lock()
+	facade.backends[name] = checksum.NewStorage(storage)
+	facade.mu.Unlock()
+
+	return nil
+}
+
+// FindDuplicates reports the objects under prefix on a backend (empty name
+// selects the default backend) that have the same content, grouped into
+// duplicate sets with the bytes removing the extra copies would free.
+// Objects are compared by the digests recorded once checksums are enabled
+// with EnableChecksums, or by MD5 ETags; objects with neither are hashed
+// when opts.Hash is set. See checksum.FindDuplicates.
+//
+// Example usage:
+//
+//	report, err := objstore.FindDuplicates(ctx, "", "datasets/", nil)
+func FindDuplicates(ctx context.Context, backendName, prefix string, opts *checksum.DuplicateOptions) (*checksum.DuplicateReport, error) {
+	var storage common.Storage
+	var err error
+	if backendName == "" {
+		storage, err = DefaultBackend()
+	} else {
+		if err := validation.ValidateBackendName(backendName); err != nil {
+			return nil, fmt.Errorf("invalid backend name: %w", err)
+		}
+		storage, err = Backend(backendName)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if prefix != "" {
+		if err := validation.ValidatePrefix(prefix); err != nil {
+			return nil, fmt.Errorf("invalid prefix: %w", err)
+		}
+	}
+
+	ctx, cancel, err := boundContext(ctx, opAdmin)
+	if err != nil {
+		return nil, err
+	}
+	defer release(cancel)
+
+	return checksum.FindDuplicates(ctx, storage, prefix, opts)
+}
+
+// ScrubConfig configures Scrub.
+type ScrubConfig struct {
+	// Prefix limits scrubbing to keys starting with it.
+	Prefix string
+
+	// BytesPerSecond is the rate objects are read at
+	// (default: scrub.DefaultRate). A negative value disables the limit.
+	BytesPerSecond int64
+
+	// Replicas names other backends that hold copies of the backend's
+	// objects under the same keys, searched in order for an intact copy
+	// of a corrupted object. The destinations of the backend's enabled
+	// replication policies are searched after them.
+	Replicas []string
+
+	// OnCorruption, if set, is called for every corrupted object after
+	// its repair was attempted.
+	OnCorruption func(scrub.Corruption)
+}
+
+// Scrub rereads every object of a backend (empty name selects the default
+// backend) and checks it against the SHA-256 digest recorded when it was
+// written, repairing corrupted objects from an intact copy on a replica.
+// Corruptions are published to the backend's notification rules as
+// ObjectIntegrity events and counted in scrub.Default. Checksums must
+// first be enabled with EnableChecksums; objects written before then
+// cannot be verified.
+//
+// Example usage:
+//
+//	result, err := objstore.Scrub(ctx, "", &objstore.ScrubConfig{Replicas: []string{"replica"}})
+func Scrub(ctx context.Context, backendName string, cfg *ScrubConfig) (*scrub.Result, error) {
+	if cfg == nil {
+		cfg = &ScrubConfig{}
+	}
+	if !IsInitialized() {
+		return nil, ErrNotInitialized
+	}
+
+	name := backendName
+	if name == "" {
+		facade.mu.RLock()
+		name = facade.defaultBackend
+		facade.mu.RUnlock()
+	} else if err := validation.ValidateBackendName(name); err != nil {
+		return nil, fmt.Errorf("invalid backend name: %w", err)
+	}
+
+	storage, err := Backend(name)
+	if err != nil {
+		return nil, err
+	}
+	checksummed, err := findChecksums(storage)
+	if err != nil {
+		return nil, err
+	}
+
+	var replicas []scrub.Replica
+	for _, replicaName := range cfg.Replicas {
+		if err := validation.ValidateBackendName(replicaName); err != nil {
+			return nil, fmt.Errorf("invalid replica backend name: %w", err)
+		}
+		replica, err := Backend(replicaName)
+		if err != nil {
+			return nil, fmt.Errorf("replica backend %s: %w", replicaName, err)
+		}
+		replicas = append(replicas, scrub.Replica{Name: replicaName, Storage: replica})
+	}
+	if manager, err := findReplicationManager(storage); err == nil {
+		if replicated, ok := manager.(interface {
+			Destinations() ([]replication.Destination, error)
+		}); ok {
+			destinations, err := replicated.Destinations()
+			if err != nil {
+				return nil, err
+			}
+			for _, d := range destinations {
+				replicas = append(replicas, scrub.Replica{Name: "replication:" + d.PolicyID, Prefix: d.Prefix, Storage: d.Storage})
+			}
+		}
+	}
+
+	ctx, cancel, err := boundContext(ctx, opAdmin)
+	if err != nil {
+		return nil, err
+	}
+	defer release(cancel)
+
+	notifier, _ := findNotifier(storage)
+	scrubber, err := scrub.New(checksummed, &scrub.Config{
+		Backend:        name,
+		Prefix:         cfg.Prefix,
+		BytesPerSecond: cfg.BytesPerSecond,
+		Replicas:       replicas,
+		OnCorruption: func(c scrub.Corruption) {
+			if notifier != nil {
+				event := events.EventObjectIntegrityCorrupted
+				if c.Repaired {
+					event = events.EventObjectIntegrityRepaired
+				}
+				notifier.Notify(ctx, event, c.Key, 0, "")
+			}
+			if cfg.OnCorruption != nil {
+				cfg.OnCorruption(c)
+			}
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return scrubber.Run(ctx)
+}
+
+// findChecksums looks for the checksum wrapper in storage's chain of
+// wrapped backends.
+func findChecksums(storage common.Storage) (*checksum.Storage, error) {
+	if checksummed, ok := findCapability[*checksum.Storage](storage); ok {
+		return checksummed, nil
+	}
+	return nil, ErrChecksumsNotEnabled
+}
+
+// EnableContentPolicy sniffs and validates the content type of objects
+// written to a backend through the facade. Objects stored without a content
+// type get one detected from the key's extension or their first bytes when
+// policy.Sniff is set, and Puts that violate a prefix's allow or deny list
+// fail with contentpolicy.ErrContentTypeRejected.
+//
+// Call EnableContentPolicy after EnableReplication and before EnableSearch,
+// so rejected objects are never indexed.
+//
+// Example usage:
+//
+//	objstore.EnableContentPolicy("", &contentpolicy.Policy{
+//	    Sniff: true,
+//	    Rules: []contentpolicy.Rule{{
+//	        Prefix: "uploads/",
+//	        Deny:   []string{contentpolicy.TypeELF, contentpolicy.TypePE},
+//	    }},
+//	})
+func EnableContentPolicy(backendName string, policy *contentpolicy.Policy) error {
+	if policy == nil {
+		return fmt.Errorf("%w: policy is nil", contentpolicy.ErrInvalidPolicy)
+	}
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+
+	if !IsInitialized() {
+		return ErrNotInitialized
+	}
+
+	name := backendName
+	if name == "" {
+		facade.mu.RLock()
+		name = facade.defaultBackend
+		facade.mu.RUnlock()
+	} else if err := validation.ValidateBackendName(name); err != nil {
+		return fmt.Errorf("invalid backend name: %w", err)
+	}
+
+	storage, err := Backend(name)
+	if err != nil {
+		return err
+	}
+	if existing, ok := storage.(*contentpolicy.Storage); ok {
+		storage = existing.Underlying()
+	}
+
+	facade.mu.Lock()
+	facade.backends[name] = contentpolicy.NewStorage(storage, policy)
+	facade.mu.Unlock()
+
+	return nil
+}
+
+// EnableKeyPolicy enforces naming rules on the keys of objects written to a
+// backend through the facade: Puts, Appends and Composes whose key breaks
+// the rule for its prefix fail with keypolicy.ErrKeyRejected. Every server
+// writes through the facade, so the rules apply to all of them.
+//
+// Call EnableKeyPolicy after EnableReplication and before EnableSearch, so
+// rejected objects are never indexed.
+//
+// Example usage:
+//
+//	objstore.EnableKeyPolicy("", &keypolicy.Policy{
+//	    Rules: []keypolicy.Rule{{
+//	        Prefix:            "datasets/",
+//	        Charset:           "a-z0-9._/-",
+//	        MaxLength:         256,
+//	        Extensions:        []string{"*.parquet", "*.csv"},
+//	        ForbiddenSegments: []string{"..", ".*", ""},
+//	    }},
+//	})
+func EnableKeyPolicy(backendName string, policy *keypolicy.Policy) error {
+	if policy == nil {
+		return fmt.Errorf("%w: policy is nil", keypolicy.ErrInvalidPolicy)
+	}
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+
+	if !IsInitialized() {
+		return ErrNotInitialized
+	}
+
+	name := backendName
+	if name == "" {
+		facade.mu.RLock()
+		name = facade.defaultBackend
+		facade.mu.RUnlock()
+	} else if err := validation.ValidateBackendName(name); err != nil {
+		return fmt.Errorf("invalid backend name: %w", err)
+	}
+
+	storage, err := Backend(name)
+	if err != nil {
+		return err
+	}
+	if existing, ok := storage.(*keypolicy.Storage); ok {
+		storage = existing.Underlying()
+	}
+
+	facade.mu.Lock()
+	facade.backends[name] = keypolicy.NewStorage(storage, policy)
+	facade.mu.Unlock()
+
+	return nil
+}
+
+// EnableOverlays applies per-prefix configuration overlays to the objects
+// written to a backend (empty name selects the default backend). When an
+// overlay names a replication policy and cfg.Replication is nil, the
+// backend's replication manager is used. Enabling overlays again replaces
+// the previous configuration.
+//
+// Example usage:
+//
+//	cfg, _ := overlay.LoadFile("overlays.yaml")
+//	objstore.EnableOverlays("", cfg)
+func EnableOverlays(backendName string, cfg *overlay.Config) error {
+	if cfg == nil {
+		return fmt.Errorf("%w: config is nil", overlay.ErrInvalidConfig)
+	}
+	if !IsInitialized() {
+		return ErrNotInitialized
+	}
+
+	name := backendName
+	if name == "" {
+		facade.mu.RLock()
+		name = facade.defaultBackend
+		facade.mu.RUnlock()
+	} else if err := validation.ValidateBackendName(name); err != nil {
+		return fmt.Errorf("invalid backend name: %w", err)
+	}
+
+	storage, err := Backend(name)
+	if err != nil {
+		return err
+	}
+	if existing, ok := storage.(*overlay.Storage); ok {
+		storage = existing.Underlying()
+	}
+	if cfg.Replication == nil && cfg.NeedsReplication() {
+		if cfg.Replication, err = findReplicationManager(storage); err != nil {
+			return fmt.Errorf("%w: %w", overlay.ErrInvalidConfig, err)
+		}
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	facade.mu.Lock()
+	facade.backends[name] = overlay.NewStorage(storage, cfg)
+	facade.mu.Unlock()
+
+	return nil
+}
+
+// Overlays returns the overlay wrapper of a backend, which manages its
+// quotas. Overlays must first be enabled with EnableOverlays.
+func Overlays(backendName string) (*overlay.Storage, error) {
+	var storage common.Storage
+	var err error
+
+	if backendName == "" {
+		storage, err = DefaultBackend()
+	} else {
+		if err := validation.ValidateBackendName(backendName); err != nil {
+			return nil, fmt.Errorf("invalid backend name: %w", err)
+		}
+		storage, err = Backend(backendName)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if overlays, ok := findCapability[*overlay.Storage](storage); ok {
+		return overlays, nil
+	}
+	return nil, ErrOverlaysNotEnabled
+}
+
+// EnableResidency pins key prefixes to the backends and regions their data
+// may be stored in, on every backend of the facade. Writes to a backend
+// outside a key's allowed locations, and replication policies copying data
+// out of them, fail with residency.ErrViolation and are audited. Existing
+// replication policies are checked too. Enabling residency again replaces
+// the previous configuration.
+//
+// Example usage:
+//
+//	cfg, _ := residency.LoadFile("residency.yaml")
+//	objstore.EnableResidency(cfg)
+func EnableResidency(cfg *residency.Config) error {
+	if cfg == nil {
+		return fmt.Errorf("%w: config is nil", residency.ErrInvalidConfig)
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	if !IsInitialized() {
+		return ErrNotInitialized
+	}
+
+	facade.mu.Lock()
+	defer facade.mu.Unlock()
+	wrapped := make(map[string]common.Storage, len(facade.backends))
+	for name, storage := range facade.backends {
+		if existing, ok := storage.(*residency.Storage); ok {
+			storage = existing.Underlying()
+		}
+		if manager, err := findReplicationManager(storage); err == nil {
+			policies, err := manager.GetPolicies()
+			if err != nil {
+				return err
+			}
+			for i := range policies {
+				if err := cfg.CheckReplication(context.Background(), &policies[i]); err != nil {
+					return err
+				}
+			}
+		}
+		wrapped[name] = residency.NewStorage(storage, name, cfg)
+	}
+	for name, storage := range wrapped {
+		facade.backends[name] = storage
+	}
+	return nil
+}
+
+// EnableCost meters the requests, stored bytes and egress of every backend
+// of the facade and prices them with cfg, for CostReport. Backends the
+// meter has no stored bytes for are listed once to seed them.
+//
+// Call EnableCost before the other wrappers, so it meters the requests
+// and bytes that reach the backends. The meter returned by Cost should be
+// closed on shutdown to persist usage recorded since the last flush.
+//
+// Example usage:
+//
+//	cfg, _ := cost.LoadFile("cost.yaml")
+//	objstore.EnableCost(cfg)
+func EnableCost(cfg *cost.Config) error {
+	if cfg == nil {
+		return fmt.Errorf("%w: config is nil", cost.ErrInvalidConfig)
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	if !IsInitialized() {
+		return ErrNotInitialized
+	}
+	if _, err := Cost(); err == nil {
+		return nil
+	}
+
+	meter, err := cost.NewMeter(cfg)
+	if err != nil {
+		return err
+	}
+
+	facade.mu.Lock()
+	defer facade.mu.Unlock()
+	wrapped := make(map[string]common.Storage, len(facade.backends))
+	for name, storage := range facade.backends {
+		if !meter.Tracks(name) {
+			if err := meter.Scan(context.Background(), name, storage); err != nil {
+				return fmt.Errorf("failed to measure backend %q: %w", name, err)
+			}
+		}
+		wrapped[name] = cost.NewStorage(storage, name, meter)
+	}
+	for name, storage := range wrapped {
+		facade.backends[name] = storage
+	}
+	return nil
+}
+
+// Cost returns the meter enabled with EnableCost.
+func Cost() (*cost.Meter, error) {
+	storage, err := DefaultBackend()
+	if err != nil {
+		return nil, err
+	}
+	if metered, ok := findCapability[*cost.Storage](storage); ok {
+		return metered.Meter(), nil
+	}
+	return nil, ErrCostNotEnabled
+}
+
+// CostReport estimates the cost of month, formatted as "2006-01", across
+// all backends. An empty month reports the current month.
+func CostReport(ctx context.Context, month string) (*cost.Report, error) {
+	meter, err := Cost()
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return meter.Report(month)
+}
+
+// EnableStats takes periodic snapshots of the object count, total size and
+// largest prefixes of every backend of the facade, for capacity planning.
+// The first snapshot is taken in the background right away, unless
+// cfg.Manual is set. Each snapshot lists every backend in full, so the
+// interval should be long for large backends. Enabling stats again keeps
+// the running collector.
+//
+// Example usage:
+//
+//	objstore.EnableStats(&stats.Config{
+//	    Interval:    time.Hour,
+//	    HistoryPath: "/data/.stats.json",
+//	})
+func EnableStats(cfg *stats.Config) error {
+	if !IsInitialized() {
+		return ErrNotInitialized
+	}
+
+	f := facade
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.stats != nil {
+		return nil
+	}
+	collector, err := stats.NewCollector(cfg, func() map[string]common.Storage {
+		f.mu.RLock()
+		defer f.mu.RUnlock()
+		backends := make(map[string]common.Storage, len(f.backends))
+		for name, storage := range f.backends {
+			backends[name] = storage
+		}
+		return backends
+	})
+	if err != nil {
+		return err
+	}
+	if !collector.Config().Manual {
+		collector.Start()
+	}
+	f.stats = collector
+	return nil
+}
+
+// StatsCollector returns the collector enabled with EnableStats.
+func StatsCollector() (*stats.Collector, error) {
+	if !IsInitialized() {
+		return nil, ErrNotInitialized
+	}
+	facade.mu.RLock()
+	defer facade.mu.RUnlock()
+	if facade.stats == nil {
+		return nil, ErrStatsNotEnabled
+	}
+	return facade.stats, nil
+}
+
+// EnableJobs creates the scheduler for background jobs and starts it.
+// Register jobs on the returned scheduler; they run from the next tick.
+// Enabling jobs again returns the running scheduler.
+//
+// Example usage:
+//
+//	scheduler, err := objstore.EnableJobs(&jobs.Config{IsLeader: elector.IsLeader})
+//	scheduler.Register(jobs.Job{
+//	    Name:     "lifecycle",
+//	    Interval: time.Hour,
+//	    Run: func(ctx context.Context) (string, error) {
+//	        n, err := objstore.ApplyPolicies(ctx, "")
+//	        return fmt.Sprintf("%d objects changed", n), err
+//	    },
+//	})
+func EnableJobs(cfg *jobs.Config) (*jobs.Scheduler, error) {
+	if !IsInitialized() {
+		return nil, ErrNotInitialized
+	}
+
+	f := facade
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.jobs != nil {
+		return f.jobs, nil
+	}
+	scheduler, err := jobs.New(cfg)
+	if err != nil {
+		return nil, err
+	}
+	scheduler.Start()
+	f.jobs = scheduler
+	return scheduler, nil
+}
+
+// Jobs returns the scheduler enabled with EnableJobs.
+func Jobs() (*jobs.Scheduler, error) {
+	if !IsInitialized() {
+		return nil, ErrNotInitialized
+	}
+	facade.mu.RLock()
+	defer facade.mu.RUnlock()
+	if facade.jobs == nil {
+		return nil, ErrJobsNotEnabled
+	}
+	return facade.jobs, nil
+}
+
+// Task types run by the queue enabled with EnableTasks.
+const (
+	// TaskArchive archives an object; its payload is an ArchiveTask.
+	TaskArchive = "archive"
+
+	// TaskRestore restores an object from an archive; its payload is a
+	// RestoreTask.
+	TaskRestore = "restore"
+
+	// TaskReplicationSync syncs replication policies; its payload is a
+	// ReplicationSyncTask.
+	TaskReplicationSync = "replication-sync"
+)
+
+// ArchiveTask is the payload of a TaskArchive task.
+type ArchiveTask struct {
+	KeyRef              string            `json:"key_ref"`
+	DestinationType     string            `json:"destination_type"`
+	DestinationSettings map[string]string `json:"destination_settings,omitempty"`
+}
+
+// RestoreTask is the payload of a TaskRestore task.
+type RestoreTask struct {
+	KeyRef         string            `json:"key_ref"`
+	SourceKey      string            `json:"source_key,omitempty"`
+	SourceType     string            `json:"source_type"`
+	SourceSettings map[string]string `json:"source_settings,omitempty"`
+}
+
+// ReplicationSyncTask is the payload of a TaskReplicationSync task. An
+// empty PolicyID syncs every enabled policy.
+type ReplicationSyncTask struct {
+	Backend     string `json:"backend,omitempty"`
+	PolicyID    string `json:"policy_id,omitempty"`
+	Parallel    bool   `json:"parallel,omitempty"`
+	WorkerCount int    `json:"worker_count,omitempty"`
+}
+
+// EnableTasks creates the durable task queue, registers the handlers of
+// the archive, restore and replication sync tasks and starts it. Other
+// packages register their own task types on the returned queue. Enabling
+// the queue again returns the running queue.
+//
+// Example usage:
+//
+//	queue, err := objstore.EnableTasks(&tasks.Config{Storage: local})
+//	task, err := queue.Enqueue(ctx, objstore.TaskArchive, objstore.ArchiveTask{
+//	    KeyRef:          "logs/2025-01.tar",
+//	    DestinationType: "glacier",
+//	})
+func EnableTasks(cfg *tasks.Config) (*tasks.Queue, error) {
+	if !IsInitialized() {
+		return nil, ErrNotInitialized
+	}
+
+	f := facade
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.tasks != nil {
+		return f.tasks, nil
+	}
+	queue, err := tasks.New(cfg)
+	if err != nil {
+		return nil, err
+	}
+	queue.Handle(TaskArchive, runArchiveTask)
+	queue.Handle(TaskRestore, runRestoreTask)
+	queue.Handle(TaskReplicationSync, runReplicationSyncTask)
+	queue.Start()
+	f.tasks = queue
+	return queue, nil
+}
+
+// Tasks returns the queue enabled with EnableTasks.
+func Tasks() (*tasks.Queue, error) {
+	if !IsInitialized() {
+		return nil, ErrNotInitialized
+	}
+	facade.mu.RLock()
+	defer facade.mu.RUnlock()
+	if facade.tasks == nil {
+		return nil, ErrTasksNotEnabled
+	}
+	return facade.tasks, nil
+}
+
+func runArchiveTask(_ context.Context, task *tasks.Task) error {
+	var payload ArchiveTask
+	if err := task.Decode(&payload); err != nil {
+		return err
+	}
+	archiver, err := factory.NewArchiver(payload.DestinationType, payload.DestinationSettings)
+	if err != nil {
+		return tasks.Permanent(err)
+	}
+	return permanentIfInvalid(Archive(payload.KeyRef, archiver))
+}
+
+func runRestoreTask(ctx context.Context, task *tasks.Task) error {
+	var payload RestoreTask
+	if err := task.Decode(&payload); err != nil {
+		return err
+	}
+	source, err := factory.NewStorage(payload.SourceType, payload.SourceSettings)
+	if err != nil {
+		return tasks.Permanent(err)
+	}
+	return permanentIfInvalid(RestoreFromArchive(ctx, payload.KeyRef, source, payload.SourceKey))
+}
+
+func runReplicationSyncTask(ctx context.Context, task *tasks.Task) error {
+	var payload ReplicationSyncTask
+	if err := task.Decode(&payload); err != nil {
+		return err
+	}
+	manager, err := GetReplicationManager(payload.Backend)
+	if err != nil {
+		return permanentIfInvalid(err)
+	}
+	var result *common.SyncResult
+	switch {
+	case payload.Parallel && payload.PolicyID == "":
+		result, err = manager.SyncAllParallel(ctx, payload.WorkerCount)
+	case payload.Parallel:
+		result, err = manager.SyncPolicyParallel(ctx, payload.PolicyID, payload.WorkerCount)
+	case payload.PolicyID == "":
+		result, err = manager.SyncAll(ctx)
+	default:
+		result, err = manager.SyncPolicy(ctx, payload.PolicyID)
+	}
+	if err != nil {
+		return permanentIfInvalid(err)
+	}
+	if result.Failed > 0 {
+		return fmt.Errorf("%d objects failed to sync", result.Failed)
+	}
+	return nil
+}
+
+// permanentIfInvalid marks errors that retrying a task cannot fix.
+func permanentIfInvalid(err error) error {
+	switch {
+	case errors.Is(err, common.ErrNotFound),
+		errors.Is(err, common.ErrInvalidArgument),
+		errors.Is(err, common.ErrPermissionDenied),
+		errors.Is(err, common.ErrReplicationNotSupported):
+		return tasks.Permanent(err)
+	}
+	return err
+}
+
+// RetentionConfig contains configuration for enabling legal holds and
+// deletion approval on a backend
+type RetentionConfig struct {
+	// ProtectedPrefixes lists key prefixes whose deletes must be approved by
+	// a second principal. Legal holds work without any protected prefix.
+	ProtectedPrefixes []string
+
+	// StatePath is the file holds and deletion requests are persisted to.
+	// If empty, they are kept in memory and lost on restart.
+	StatePath string
+
+	// FileSystem stores StatePath. If nil, the OS file system is used.
+	FileSystem replication.FileSystem
+
+	// Shared reloads the state before every read and change, for a
+	// FileSystem that other instances write too (see ha.Cluster).
+	Shared bool
+}
+
+// EnableRetention enforces legal holds and the two-person deletion rule on a
+// backend. Deletes made through the facade of keys under a protected prefix
+// create a pending deletion request, returned as a *retention.PendingError,
+// that must be approved with ApproveDeletion. Held objects cannot be deleted
+// or overwritten. Lifecycle policies and writes made directly to the
+// backend are not checked.
+//
+// Call EnableRetention after EnableReplication and before EnableSearch.
+//
+// Example usage:
+//
+//	objstore.EnableRetention("", &objstore.RetentionConfig{
+//	    ProtectedPrefixes: []string{"records/"},
+//	    StatePath:         "/data/.retention.json",
+//	})
+func EnableRetention(backendName string, config *RetentionConfig) error {
+	if config == nil {
+		config = &RetentionConfig{}
+	}
+
+	if !IsInitialized() {
+		return ErrNotInitialized
+	}
+
+	name := backendName
+	if name == "" {
+		facade.mu.RLock()
+		name = facade.defaultBackend
+		facade.mu.RUnlock()
+	} else if err := validation.ValidateBackendName(name); err != nil {
+		return fmt.Errorf("invalid backend name: %w", err)
+	}
+
+	storage, err := Backend(name)
+	if err != nil {
+		return err
+	}
+	if _, err := findRetention(storage); err == nil {
+		return nil
+	}
+
+	manager, err := retention.NewManager(storage, retention.Config{
+		ProtectedPrefixes: config.ProtectedPrefixes,
+		StatePath:         config.StatePath,
+		FileSystem:        config.FileSystem,
+		Shared:            config.Shared,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to load retention state: %w", err)
+	}
+
+	facade.mu.Lock()
+	facade.backends[name] = retention.NewStorage(storage, manager)
+	facade.mu.Unlock()
+
+	return nil
+}
+
+// Retention returns the manager of a backend's legal holds and deletion
+// requests. Retention must first be enabled with EnableRetention.
+func Retention(backendName string) (*retention.Manager, error) {
+	_, manager, err := retentionBackend(backendName)
+	return manager, err
+}
+
+// ApproveDeletion approves a pending deletion request and deletes its object.
+// The approver is the principal stored in ctx under
+// adapters.PrincipalContextKey and must differ from the requester.
+func ApproveDeletion(ctx context.Context, backendName, id string) (*retention.DeletionRequest, error) {
+	storage, manager, err := retentionBackend(backendName)
+	if err != nil {
+		return nil, err
+	}
+	return manager.Approve(ctx, id, storage)
+}
+
+// retentionBackend returns a backend, as registered with the facade, and
+// its retention manager.
+func retentionBackend(backendName string) (common.Storage, *retention.Manager, error) {
+	var storage common.Storage
+	var err error
+
+	if backendName == "" {
+		storage, err = DefaultBackend()
+	} else {
+		if err := validation.ValidateBackendName(backendName); err != nil {
+			return nil, nil, fmt.Errorf("invalid backend name: %w", err)
+		}
+		storage, err = Backend(backendName)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	manager, err := findRetention(storage)
+	if err != nil {
+		return nil, nil, err
+	}
+	return storage, manager, nil
+}
+
+// findRetention looks for a retention wrapper in storage's chain of wrapped
+// backends.
+func findRetention(storage common.Storage) (*retention.Manager, error) {
+	if protected, ok := findCapability[*retention.Storage](storage); ok {
+		return protected.Manager(), nil
+	}
+	return nil, ErrRetentionNotEnabled
+}
+
+// EnableTombstones defers a backend's deletes: deleting an object records a
+// tombstone and hides the object, which is physically removed by
+// PurgeTombstones once cfg.Grace has passed. Tombstones are stored under
+// cfg.Prefix (tombstone.DefaultPrefix if empty), in cfg.Storage if set;
+// object operations made through the facade on keys under the prefix fail
+// with common.ErrReservedKey.
+//
+// Call EnableTombstones after EnableReplication and before EnableRetention,
+// EnableNotifications and EnableSearch, so they see deletes as requested and
+// never see tombstoned objects.
+//
+// Example usage:
+//
+//	objstore.EnableTombstones("", &tombstone.Config{Grace: 24 * time.Hour})
+//	...
+//	purged, err := objstore.PurgeTombstones(ctx, "")
+func EnableTombstones(backendName string, cfg *tombstone.Config) error {
+	if !IsInitialized() {
+		return ErrNotInitialized
+	}
+
+	name := backendName
+	if name == "" {
+		facade.mu.RLock()
+		name = facade.defaultBackend
+		facade.mu.RUnlock()
+	} else if err := validation.ValidateBackendName(name); err != nil {
+		return fmt.Errorf("invalid backend name: %w", err)
+	}
+
+	storage, err := Backend(name)
+	if err != nil {
+		return err
+	}
+	if _, err := findTombstones(storage); err == nil {
+		return nil
+	}
+
+	manager, err := tombstone.NewManager(storage, cfg)
+	if err != nil {
+		return err
+	}
+
+	facade.mu.Lock()
+	facade.backends[name] = tombstone.NewStorage(storage, manager)
+	facade.mu.Unlock()
+
+	return nil
+}
+
+// Tombstones returns the tombstone manager of a backend. Tombstones must
+// first be enabled with EnableTombstones.
+func Tombstones(backendName string) (*tombstone.Manager, error) {
+	deferred, err := tombstoneBackend(backendName)
+	if err != nil {
+		return nil, err
+	}
+	return deferred.Manager(), nil
+}
+
+// PurgeTombstones physically removes a backend's deleted objects whose
+// grace period has passed and deletes expired tombstones. It returns the
+// number of objects removed.
+func PurgeTombstones(ctx context.Context, backendName string) (int, error) {
+	deferred, err := tombstoneBackend(backendName)
+	if err != nil {
+		return 0, err
+	}
+	ctx, cancel, err := boundContext(ctx, opAdmin)
+	if err != nil {
+		return 0, err
+	}
+	defer release(cancel)
+
+	return deferred.Purge(ctx)
+}
+
+// tombstoneBackend returns the tombstone wrapper of a backend.
+func tombstoneBackend(backendName string) (*tombstone.Storage, error) {
+	var storage common.Storage
+	var err error
+
+	if backendName == "" {
+		storage, err = DefaultBackend()
+	} else {
+		if err := validation.ValidateBackendName(backendName); err != nil {
+			return nil, fmt.Errorf("invalid backend name: %w", err)
+		}
+		storage, err = Backend(backendName)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return findTombstones(storage)
+}
+
+// findTombstones looks for a tombstone wrapper in storage's chain of wrapped
+// backends.
+func findTombstones(storage common.Storage) (*tombstone.Storage, error) {
+	if deferred, ok := findCapability[*tombstone.Storage](storage); ok {
+		return deferred, nil
+	}
+	return nil, ErrTombstonesNotEnabled
+}
+
+// EnableLocks provides advisory locks on a backend's keys, stored under
+// prefix (locks.DefaultPrefix if empty). The backend, or one it wraps, must
+// support conditional writes. Object operations made through the facade on
+// keys under the prefix fail with common.ErrReservedKey, and listings leave
+// them out.
+//
+// Call EnableLocks after EnableReplication and before EnableSearch, so lock
+// objects are never indexed.
+//
+// Example usage:
+//
+//	objstore.EnableLocks("", "")
+//	lock, err := objstore.TryLock(ctx, "jobs/nightly", "worker-1", time.Minute)
+//	...
+//	objstore.Unlock(ctx, "jobs/nightly", lock.Token)
+func EnableLocks(backendName, prefix string) error {
+	if !IsInitialized() {
+		return ErrNotInitialized
+	}
+
+	name := backendName
+	if name == "" {
+		facade.mu.RLock()
+		name = facade.defaultBackend
+		facade.mu.RUnlock()
+	} else if err := validation.ValidateBackendName(name); err != nil {
+		return fmt.Errorf("invalid backend name: %w", err)
+	}
+
+	storage, err := Backend(name)
+	if err != nil {
+		return err
+	}
+	if _, err := findLocks(storage); err == nil {
+		return nil
+	}
+
+	// Lock objects are written to the first backend in the chain that can
+	// write conditionally, bypassing wrappers that only see whole objects.
+	writer, ok := findCapability[conditionalStorage](storage)
+	if !ok {
+		return fmt.Errorf("backend %s: %w", name, locks.ErrNotSupported)
+	}
+
+	manager, err := locks.NewManager(writer, prefix)
+	if err != nil {
+		return err
+	}
+
+	facade.mu.Lock()
+	facade.backends[name] = locks.NewStorage(storage, manager)
+	facade.mu.Unlock()
+
+	return nil
+}
+
+// Locks returns the lock manager of a backend. Locks must first be enabled
+// with EnableLocks.
+func Locks(backendName string) (*locks.Manager, error) {
+	var storage common.Storage
+	var err error
+
+	if backendName == "" {
+		storage, err = DefaultBackend()
+	} else {
+		if err := validation.ValidateBackendName(backendName); err != nil {
+			return nil, fmt.Errorf("invalid backend name: %w", err)
+		}
+		storage, err = Backend(backendName)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return findLocks(storage)
+}
+
+// TryLock acquires the advisory lock on a key for ttl, failing with
+// locks.ErrLocked if another holder has it. The returned lock's token is
+// needed to refresh or release it.
+func TryLock(ctx context.Context, keyRef, owner string, ttl time.Duration) (*locks.Lock, error) {
+	manager, key, err := lockManagerForKey(keyRef)
+	if err != nil {
+		return nil, err
+	}
+	return manager.TryLock(ctx, key, owner, ttl)
+}
+
+// Lock acquires the advisory lock on a key for ttl, waiting until it is free
+// or ctx is done.
+func Lock(ctx context.Context, keyRef, owner string, ttl time.Duration) (*locks.Lock, error) {
+	manager, key, err := lockManagerForKey(keyRef)
+	if err != nil {
+		return nil, err
+	}
+	return manager.Lock(ctx, key, owner, ttl)
+}
+
+// RefreshLock extends a held advisory lock to expire ttl from now.
+func RefreshLock(ctx context.Context, keyRef, token string, ttl time.Duration) (*locks.Lock, error) {
+	manager, key, err := lockManagerForKey(keyRef)
+	if err != nil {
+		return nil, err
+	}
+	return manager.Refresh(ctx, key, token, ttl)
+}
+
+// Unlock releases a held advisory lock.
+func Unlock(ctx context.Context, keyRef, token string) error {
+	manager, key, err := lockManagerForKey(keyRef)
+	if err != nil {
+		return err
+	}
+	return manager.Unlock(ctx, key, token)
+}
+
+// lockManagerForKey resolves a key reference to its backend's lock manager
+// and key.
+func lockManagerForKey(keyRef string) (*locks.Manager, string, error) {
+	if err := validation.ValidateKeyReference(keyRef); err != nil {
+		return nil, "", fmt.Errorf("invalid key reference: %w", err)
+	}
+
+	storage, key, err := getStorageForKey(keyRef)
+	if err != nil {
+		return nil, "", err
+	}
+	manager, err := findLocks(storage)
+	if err != nil {
+		return nil, "", err
+	}
+	return manager, key, nil
+}
+
+// findLocks looks for a locks wrapper in storage's chain of wrapped
+// backends.
+func findLocks(storage common.Storage) (*locks.Manager, error) {
+	if reserved, ok := findCapability[*locks.Storage](storage); ok {
+		return reserved.Manager(), nil
+	}
+	return nil, ErrLocksNotEnabled
+}
+
+// EnableShares provides shareable download links to a backend's objects,
+// stored under prefix (share.DefaultPrefix if empty). The backend, or one it
+// wraps, must support conditional writes, which keep download counts exact
+// across instances. Object operations made through the facade on keys under
+// the prefix fail with common.ErrReservedKey, and listings leave them out.
+//
+// Call EnableShares after EnableReplication and before EnableSearch, so link
+// objects are never indexed.
+//
+// Example usage:
+//
+//	objstore.EnableShares("", "")
+//	link, token, err := objstore.ShareObject(ctx, "reports/q1.pdf", share.Options{
+//	    TTL:          24 * time.Hour,
+//	    MaxDownloads: 10,
+//	})
+func EnableShares(backendName, prefix string) error {
+	if !IsInitialized() {
+		return ErrNotInitialized
+	}
+
+	name := backendName
+	if name == "" {
+		facade.mu.RLock()
+		name = facade.defaultBackend
+		facade.mu.RUnlock()
+	} else if err := validation.ValidateBackendName(name); err != nil {
+		return fmt.Errorf("invalid backend name: %w", err)
+	}
+
+	storage, err := Backend(name)
+	if err != nil {
+		return err
+	}
+	if _, err := findShares(storage); err == nil {
+		return nil
+	}
+
+	// Link objects are written to the first backend in the chain that can
+	// write conditionally, bypassing wrappers that only see whole objects.
+	writer, ok := findCapability[conditionalStorage](storage)
+	if !ok {
+		return fmt.Errorf("backend %s: %w", name, share.ErrNotSupported)
+	}
+
+	manager, err := share.NewManager(writer, prefix)
+	if err != nil {
+		return err
+	}
+
+	facade.mu.Lock()
+	facade.backends[name] = share.NewStorage(storage, manager)
+	facade.mu.Unlock()
+
+	return nil
+}
+
+// Shares returns the share link manager of a backend. Share links must
+// first be enabled with EnableShares.
+func Shares(backendName string) (*share.Manager, error) {
+	var storage common.Storage
+	var err error
+
+	if backendName == "" {
+		storage, err = DefaultBackend()
+	} else {
+		if err := validation.ValidateBackendName(backendName); err != nil {
+			return nil, fmt.Errorf("invalid backend name: %w", err)
+		}
+		storage, err = Backend(backendName)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return findShares(storage)
+}
+
+// ShareObject creates a shareable download link to an existing object and
+// returns it with the token that opens it. The token is not stored and
+// cannot be retrieved later. Objects encrypted with a customer-provided key
+// cannot be shared, as downloads through a link carry no key.
+func ShareObject(ctx context.Context, keyRef string, opts share.Options) (*share.Link, string, error) {
+	if err := validation.ValidateKeyReference(keyRef); err != nil {
+		return nil, "", fmt.Errorf("invalid key reference: %w", err)
+	}
+
+	storage, key, err := getStorageForKey(keyRef)
+	if err != nil {
+		return nil, "", err
+	}
+	manager, err := findShares(storage)
+	if err != nil {
+		return nil, "", err
+	}
+
+	metadata, err := storage.GetMetadata(ctx, key)
+	if err != nil {
+		return nil, "", err
+	}
+	if common.IsCustomerKeyEncrypted(metadata) {
+		return nil, "", fmt.Errorf("%w: objects encrypted with a customer-provided key cannot be shared", common.ErrInvalidArgument)
+	}
+	return manager.Create(ctx, key, opts)
+}
+
+// findShares looks for a share link wrapper in storage's chain of wrapped
+// backends.
+func findShares(storage common.Storage) (*share.Manager, error) {
+	if reserved, ok := findCapability[*share.Storage](storage); ok {
+		return reserved.Manager(), nil
+	}
+	return nil, ErrSharesNotEnabled
+}
+
+// EnableUploads provides chunked upload sessions on a backend, stored under
+// prefix (upload.DefaultPrefix if empty). Session records and chunks are
+// written to the innermost backend, so wrappers such as checksums, content
+// policies and replication only see the object CompleteUpload assembles.
+// Object operations made through the facade on keys under the prefix fail
+// with common.ErrReservedKey, and listings leave them out.
+//
+// Call EnableUploads after EnableReplication and before EnableSearch.
+//
+// Example usage:
+//
+//	objstore.EnableUploads("", "")
+//	uploads, _ := objstore.Uploads("")
+//	session, _ := uploads.Create(ctx, "videos/raw.mp4", upload.Options{})
+//	uploads.PutChunk(ctx, session.ID, 1, chunk, chunkSHA256)
+//	objstore.CompleteUpload(ctx, "", session.ID, "")
+func EnableUploads(backendName, prefix string) error {
+	if !IsInitialized() {
+		return ErrNotInitialized
+	}
+
+	name := backendName
+	if name == "" {
+		facade.mu.RLock()
+		name = facade.defaultBackend
+		facade.mu.RUnlock()
+	} else if err := validation.ValidateBackendName(name); err != nil {
+		return fmt.Errorf("invalid backend name: %w", err)
+	}
+
+	storage, err := Backend(name)
+	if err != nil {
+		return err
+	}
+	if _, err := findUploads(storage); err == nil {
+		return nil
+	}
+
+	manager, err := upload.NewManager(baseBackend(storage), prefix)
+	if err != nil {
+		return err
+	}
+
+	facade.mu.Lock()
+	facade.backends[name] = upload.NewStorage(storage, manager)
+	facade.mu.Unlock()
+
+	return nil
+}
+
+// Uploads returns the upload session manager of a backend. Upload sessions
+// must first be enabled with EnableUploads.
+func Uploads(backendName string) (*upload.Manager, error) {
+	var storage common.Storage
+	var err error
+
+	if backendName == "" {
+		storage, err = DefaultBackend()
+	} else {
+		if err := validation.ValidateBackendName(backendName); err != nil {
+			return nil, fmt.Errorf("invalid backend name: %w", err)
+		}
+		storage, err = Backend(backendName)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return findUploads(storage)
+}
+
+// CompleteUpload assembles the chunks of an upload session into its object,
+// written through the facade like any other put, and removes the session.
+// If sha256 is not empty, it is the expected hex SHA-256 digest of the whole
+// object; a mismatch fails with upload.ErrChecksumMismatch and leaves the
+// session in place.
+func CompleteUpload(ctx context.Context, backendName, id, sha256 string) (*upload.Session, error) {
+	manager, err := Uploads(backendName)
+	if err != nil {
+		return nil, err
+	}
+
+	session, data, err := manager.Assemble(ctx, id, sha256)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = data.Close() }()
+
+	metadata := &common.Metadata{
+		ContentType:     session.ContentType,
+		ContentEncoding: session.ContentEncoding,
+		Custom:          session.Metadata,
+	}
+	keyRef := session.Key
+	if backendName != "" {
+		keyRef = backendName + ":" + session.Key
+	}
+	if err := PutWithMetadata(ctx, keyRef, data, metadata); err != nil {
+		return nil, err
+	}
+
+	// The object is stored; chunks left behind by a failed removal are
+	// purged once the session expires.
+	_ = manager.Remove(ctx, id)
+	return session, nil
+}
+
+// findUploads looks for an upload session wrapper in storage's chain of
+// wrapped backends.
+func findUploads(storage common.Storage) (*upload.Manager, error) {
+	if reserved, ok := findCapability[*upload.Storage](storage); ok {
+		return reserved.Manager(), nil
+	}
+	return nil, ErrUploadsNotEnabled
+}
+
+// ReapConfig configures ReapUploads.
+type ReapConfig struct {
+	// MaxAge is how long an upload may stay unfinished before it is
+	// aborted (default: reaper.DefaultMaxAge). It must exceed the time the
+	// longest upload takes.
+	MaxAge time.Duration
+
+	// DryRun finds stale uploads without aborting them.
+	DryRun bool
+}
+
+// ReapUploads aborts the uploads of a backend (empty name selects the
+// default backend) left unfinished for longer than cfg.MaxAge: multipart
+// uploads of backends that keep them, objects staged by PutAtomic and, when
+// EnableUploads was called, upload sessions, which are also aborted once
+// they expire. The uploads in progress and the bytes reclaimed are counted
+// in reaper.Default.
+//
+// Example usage:
+//
+//	result, err := objstore.ReapUploads(ctx, "", &objstore.ReapConfig{MaxAge: 24 * time.Hour})
+func ReapUploads(ctx context.Context, backendName string, cfg *ReapConfig) (*reaper.Result, error) {
+	if cfg == nil {
+		cfg = &ReapConfig{}
+	}
+	if !IsInitialized() {
+		return nil, ErrNotInitialized
+	}
+
+	name := backendName
+	if name == "" {
+		facade.mu.RLock()
+		name = facade.defaultBackend
+		facade.mu.RUnlock()
+	} else if err := validation.ValidateBackendName(name); err != nil {
+		return nil, fmt.Errorf("invalid backend name: %w", err)
+	}
+
+	storage, err := Backend(name)
+	if err != nil {
+		return nil, err
+	}
+	// Upload sessions are optional; without them only multipart and
+	// staged uploads are reaped.
+	sessions, _ := findUploads(storage)
+
+	r, err := reaper.New(storage, &reaper.Config{
+		Backend:  name,
+		MaxAge:   cfg.MaxAge,
+		Sessions: sessions,
+		DryRun:   cfg.DryRun,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel, err := boundContext(ctx, opAdmin)
+	if err != nil {
+		return nil, err
+	}
+	defer release(cancel)
+
+	return r.Run(ctx)
+}
+
+// EnableHA makes a backend the shared state of several objstore-server
+// instances: leader leases, idempotency records and shared policy files are
+// stored under cfg.Prefix (ha.DefaultPrefix if empty) on the backend, or the
+// first one it wraps that supports conditional writes. Backends whose
+// conditional writes are only atomic within one process, such as memory and
+// local, are refused with ha.ErrProcessLocal. Object operations
+// made through the facade on keys under the prefix fail with
+// common.ErrReservedKey, and listings leave them out.
+//
+// Call EnableHA directly after Initialize so no other wrapper sees
+// coordination objects.
+//
+// Example usage:
+//
+//	cluster, err := objstore.EnableHA("", ha.Config{NodeID: "server-1"})
+//	elector := cluster.Elector("replication")
+//	go elector.Run(ctx)
+//	objstore.EnableReplication("", &objstore.ReplicationConfig{
+//	    FileSystem:      cluster.FileSystem(),
+//	    Shared:          true,
+//	    IsLeader:        elector.IsLeader,
+//	    RunInBackground: true,
+//	})
+func EnableHA(backendName string, cfg ha.Config) (*ha.Cluster, error) {
+	if !IsInitialized() {
+		return nil, ErrNotInitialized
+	}
+
+	name := backendName
+	if name == "" {
+		facade.mu.RLock()
+		name = facade.defaultBackend
+		facade.mu.RUnlock()
+	} else if err := validation.ValidateBackendName(name); err != nil {
+		return nil, fmt.Errorf("invalid backend name: %w", err)
+	}
+
+	storage, err := Backend(name)
+	if err != nil {
+		return nil, err
+	}
+	if cluster, err := findHA(storage); err == nil {
+		return cluster, nil
+	}
+
+	writer, ok := findCapability[conditionalStorage](storage)
+	if !ok {
+		return nil, fmt.Errorf("backend %s: %w", name, ha.ErrNotSupported)
+	}
+	if local, ok := writer.(common.ProcessLocal); ok && local.ProcessLocal() {
+		return nil, fmt.Errorf("backend %s: %w", name, ha.ErrProcessLocal)
+	}
+
+	cluster, err := ha.New(writer, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	facade.mu.Lock()
+	facade.backends[name] = ha.NewStorage(storage, cluster)
+	facade.mu.Unlock()
+
+	return cluster, nil
+}
+
+// HA returns the cluster of a backend. High availability must first be
+// enabled with EnableHA.
+func HA(backendName string) (*ha.Cluster, error) {
+	var storage common.Storage
+	var err error
+
+	if backendName == "" {
+		storage, err = DefaultBackend()
+	} else {
+		if err := validation.ValidateBackendName(backendName); err != nil {
+			return nil, fmt.Errorf("invalid backend name: %w", err)
+		}
+		storage, err = Backend(backendName)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return findHA(storage)
+}
+
+// findHA looks for an HA wrapper in storage's chain of wrapped backends.
+func findHA(storage common.Storage) (*ha.Cluster, error) {
+	if reserved, ok := findCapability[*ha.Storage](storage); ok {
+		return reserved.Cluster(), nil
+	}
+	return nil, ErrHANotEnabled
+}
+
+// EnableManifests provides dataset manifests over a backend's objects,
+// stored under prefix (manifest.DefaultPrefix if empty). Writes and deletes
+// made through the facade on keys under the prefix fail with
+// common.ErrReservedKey. Manifests are only protected against concurrent
+// publishes when the backend, or one it wraps, supports conditional writes.
+//
+// Call EnableManifests after EnableRetention and before EnableSearch, so
+// manifest documents are never indexed.
+//
+// Example usage:
+//
+//	objstore.EnableManifests("", "")
+//	manifests, _ := objstore.Manifests("")
+//	manifests.Create(ctx, "train", "data/a.parquet", "data/b.parquet")
+//	manifests.Publish(ctx, "train")
+func EnableManifests(backendName, prefix string) error {
+	if !IsInitialized() {
+		return ErrNotInitialized
+	}
+
+	name := backendName
+	if name == "" {
+		facade.mu.RLock()
+		name = facade.defaultBackend
+		facade.mu.RUnlock()
+	} else if err := validation.ValidateBackendName(name); err != nil {
+		return fmt.Errorf("invalid backend name: %w", err)
+	}
+
+	storage, err := Backend(name)
+	if err != nil {
+		return err
+	}
+	if _, err := findManifests(storage); err == nil {
+		return nil
+	}
+
+	manager, err := manifest.NewManager(storage, prefix)
+	if err != nil {
+		return err
+	}
+
+	facade.mu.Lock()
+	facade.backends[name] = manifest.NewStorage(storage, manager)
+	facade.mu.Unlock()
+
+	return nil
+}
+
+// Manifests returns the manifest manager of a backend. Manifests must first
+// be enabled with EnableManifests.
+func Manifests(backendName string) (*manifest.Manager, error) {
+	var storage common.Storage
+	var err error
+
+	if backendName == "" {
+		storage, err = DefaultBackend()
+	} else {
+		if err := validation.ValidateBackendName(backendName); err != nil {
+			return nil, fmt.Errorf("invalid backend name: %w", err)
+		}
+		storage, err = Backend(backendName)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return findManifests(storage)
+}
+
+// findManifests looks for a manifest wrapper in storage's chain of wrapped
+// backends.
+func findManifests(storage common.Storage) (*manifest.Manager, error) {
+	if reserved, ok := findCapability[*manifest.Storage](storage); ok {
+		return reserved.Manager(), nil
+	}
+	return nil, ErrManifestsNotEnabled
+}
+
+// EnableAliases resolves aliases of a backend's objects, stored under
+// prefix (alias.DefaultPrefix if empty): reads made through the facade of
+// a key holding no object read the target of the alias of that name. Writes
+// and deletes made through the facade on keys under the prefix fail with
+// common.ErrReservedKey.
+//
+// Call EnableAliases before EnableSearch, so alias objects are never
+// indexed.
+//
+// Example usage:
+//
+//	objstore.EnableAliases("", "")
+//	objstore.SetAlias(ctx, "latest", "releases/v1.4.2/app.tar.gz")
+//	rc, _ := objstore.GetWithContext(ctx, "latest")
+func EnableAliases(backendName, prefix string) error {
+	if !IsInitialized() {
+		return ErrNotInitialized
+	}
+
+	name := backendName
+	if name == "" {
+		facade.mu.RLock()
+		name = facade.defaultBackend
+		facade.mu.RUnlock()
+	} else if err := validation.ValidateBackendName(name); err != nil {
+		return fmt.Errorf("invalid backend name: %w", err)
+	}
+
+	storage, err := Backend(name)
+	if err != nil {
+		return err
+	}
+	if _, err := findAliases(storage); err == nil {
+		return nil
+	}
+
+	manager, err := alias.NewManager(storage, prefix)
+	if err != nil {
+		return err
+	}
+
+	facade.mu.Lock()
+	facade.backends[name] = alias.NewStorage(storage, manager)
+	facade.mu.Unlock()
+
+	return nil
+}
+
+// Aliases returns the alias manager of a backend. Aliases must first be
+// enabled with EnableAliases.
+func Aliases(backendName string) (*alias.Manager, error) {
+	var storage common.Storage
+	var err error
+
+	if backendName == "" {
+		storage, err = DefaultBackend()
+	} else {
+		if err := validation.ValidateBackendName(backendName); err != nil {
+			return nil, fmt.Errorf("invalid backend name: %w", err)
+		}
+		storage, err = Backend(backendName)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return findAliases(storage)
+}
+
+// SetAlias points an alias at an existing object of the same backend,
+// creating the alias or moving an existing one. The name may carry a
+// backend prefix ("backend:name"); target is a key of that backend.
+func SetAlias(ctx context.Context, nameRef, target string) (*alias.Alias, error) {
+	manager, name, err := aliasManagerForKey(nameRef)
+	if err != nil {
+		return nil, err
+	}
+	return manager.Set(ctx, name, target)
+}
+
+// ResolveAlias returns an alias with the key of the object it points at.
+// The name may carry a backend prefix ("backend:name").
+func ResolveAlias(ctx context.Context, nameRef string) (*alias.Alias, error) {
+	manager, name, err := aliasManagerForKey(nameRef)
+	if err != nil {
+		return nil, err
+	}
+	return manager.Resolve(ctx, name)
+}
+
+// aliasManagerForKey returns the alias manager of the backend a key
+// reference names, and the key.
+func aliasManagerForKey(keyRef string) (*alias.Manager, string, error) {
+	if err := validation.ValidateKeyReference(keyRef); err != nil {
+		return nil, "", fmt.Errorf("invalid key reference: %w", err)
+	}
+	storage, key, err := getStorageForKey(keyRef)
+	if err != nil {
+		return nil, "", err
+	}
+	manager, err := findAliases(storage)
+	if err != nil {
+		return nil, "", err
+	}
+	return manager, key, nil
+}
+
+// findAliases looks for an alias wrapper in storage's chain of wrapped
+// backends.
+func findAliases(storage common.Storage) (*alias.Manager, error) {
+	if resolver, ok := findCapability[*alias.Storage](storage); ok {
+		return resolver.Manager(), nil
+	}
+	return nil, ErrAliasesNotEnabled
+}
+
+// EnableNotifications publishes the changes made to a backend through the
+// facade as S3 event notifications, delivered to the sinks of cfg's rules.
+// Records name the backend as their bucket unless cfg.Bucket is set. The
+// notifier returned by Notifications should be closed on shutdown to
+// deliver queued notifications.
+//
+// Call EnableNotifications after the wrappers that can refuse a change
+// (locks, content policy, retention, manifests, transforms) and before
+// EnableSearch, so only changes that were made are published.
+//
+// Example usage:
+//
+//	cfg, _ := events.LoadFile("notifications.yaml")
+//	objstore.EnableNotifications("", cfg)
+//	...
+//	notifier, _ := objstore.Notifications("")
+//	notifier.Close(ctx)
+func EnableNotifications(backendName string, cfg *events.Config) error {
+	if !IsInitialized() {
+		return ErrNotInitialized
+	}
+
+	name := backendName
+	if name == "" {
+		facade.mu.RLock()
+		name = facade.defaultBackend
+		facade.mu.RUnlock()
+	} else if err := validation.ValidateBackendName(name); err != nil {
+		return fmt.Errorf("invalid backend name: %w", err)
+	}
+	if cfg == nil {
+		return fmt.Errorf("%w: nil configuration", events.ErrInvalidConfig)
+	}
+
+	storage, err := Backend(name)
 	if err != nil {
 		return err
 	}
+	if _, err := findNotifier(storage); err == nil {
+		return nil
+	}
 
-	// Validate policy prefix
-	if policy.Prefix != "" {
-		if err := validation.ValidatePrefix(policy.Prefix); err != nil {
-			return fmt.Errorf("invalid policy prefix: %w", err)
-		}
+	config := *cfg
+	if config.Bucket == "" {
+		config.Bucket = name
+	}
+	notifier, err := events.NewNotifier(&config)
+	if err != nil {
+		return err
 	}
 
-	return storage.AddPolicy(policy)
+	facade.mu.Lock()
+	facade.backends[name] = events.NewStorage(storage, notifier)
+	facade.mu.Unlock()
+
+	return nil
 }
 
-// RemovePolicy removes a lifecycle policy from a backend
-func RemovePolicy(backendName string, policyID string) error {
-	// Validate backend name if provided
+// Notifications returns the notifier of a backend. Notifications must first
+// be enabled with EnableNotifications.
+func Notifications(backendName string) (*events.Notifier, error) {
 	var storage common.Storage
 	var err error
 
@@ -574,21 +3831,78 @@ func RemovePolicy(backendName string, policyID string) error {
 		storage, err = DefaultBackend()
 	} else {
 		if err := validation.ValidateBackendName(backendName); err != nil {
-			return fmt.Errorf("invalid backend name: %w", err)
+			return nil, fmt.Errorf("invalid backend name: %w", err)
 		}
 		storage, err = Backend(backendName)
 	}
+	if err != nil {
+		return nil, err
+	}
+	return findNotifier(storage)
+}
+
+// findNotifier looks for a notification wrapper in storage's chain of
+// wrapped backends.
+func findNotifier(storage common.Storage) (*events.Notifier, error) {
+	if notifying, ok := findCapability[*events.Storage](storage); ok {
+		return notifying.Notifier(), nil
+	}
+	return nil, ErrNotificationsNotEnabled
+}
+
+// EnableTransforms generates derived objects, such as thumbnails, from a
+// backend's objects with the presets of cfg. Derived objects are read with
+// GetDerived and cached in the backend under cfg.Prefix; writes to that
+// prefix through the facade are refused.
+//
+// Call EnableTransforms after the wrappers that can refuse a change
+// (locks, content policy, retention, manifests) and before
+// EnableNotifications, so on_put presets only run for writes that were
+// made and generating a derived object is not itself published.
+//
+// Example usage:
+//
+//	cfg, _ := transform.LoadFile("transforms.yaml")
+//	objstore.EnableTransforms("", cfg)
+//	...
+//	rc, metadata, err := objstore.GetDerived(ctx, "photos/cat.jpg", "thumb")
+func EnableTransforms(backendName string, cfg *transform.Config) error {
+	if !IsInitialized() {
+		return ErrNotInitialized
+	}
+
+	name := backendName
+	if name == "" {
+		facade.mu.RLock()
+		name = facade.defaultBackend
+		facade.mu.RUnlock()
+	} else if err := validation.ValidateBackendName(name); err != nil {
+		return fmt.Errorf("invalid backend name: %w", err)
+	}
+
+	storage, err := Backend(name)
+	if err != nil {
+		return err
+	}
+	if _, err := findTransformer(storage); err == nil {
+		return nil
+	}
 
+	transformer, err := transform.NewTransformer(storage, cfg)
 	if err != nil {
 		return err
 	}
 
-	return storage.RemovePolicy(policyID)
+	facade.mu.Lock()
+	facade.backends[name] = transform.NewStorage(storage, transformer)
+	facade.mu.Unlock()
+
+	return nil
 }
 
-// GetPolicies retrieves all lifecycle policies from a backend
-func GetPolicies(backendName string) ([]common.LifecyclePolicy, error) {
-	// Validate backend name if provided
+// Transforms returns the transformer of a backend. Transforms must first be
+// enabled with EnableTransforms.
+func Transforms(backendName string) (*transform.Transformer, error) {
 	var storage common.Storage
 	var err error
 
@@ -600,17 +3914,105 @@ func GetPolicies(backendName string) ([]common.LifecyclePolicy, error) {
 		}
 		storage, err = Backend(backendName)
 	}
-
 	if err != nil {
 		return nil, err
 	}
+	return findTransformer(storage)
+}
+
+// GetDerived returns the derived object of an object for a preset,
+// generating and caching it if needed. The caller must close the reader.
+func GetDerived(ctx context.Context, keyRef, preset string) (io.ReadCloser, *common.Metadata, error) {
+	if err := validation.ValidateKeyReference(keyRef); err != nil {
+		return nil, nil, fmt.Errorf("invalid key reference: %w", err)
+	}
 
-	return storage.GetPolicies()
+	storage, key, err := getStorageForKey(keyRef)
+	if err != nil {
+		return nil, nil, err
+	}
+	transformer, err := findTransformer(storage)
+	if err != nil {
+		return nil, nil, err
+	}
+	return transformer.Get(ctx, preset, key)
 }
 
-// GetReplicationManager returns the replication manager for a backend if supported
-func GetReplicationManager(backendName string) (common.ReplicationManager, error) {
-	// Validate backend name if provided
+// findTransformer looks for a transform wrapper in storage's chain of
+// wrapped backends.
+func findTransformer(storage common.Storage) (*transform.Transformer, error) {
+	if transforming, ok := findCapability[*transform.Storage](storage); ok {
+		return transforming.Transformer(), nil
+	}
+	return nil, ErrTransformsNotEnabled
+}
+
+// ChangeFeedConfig contains configuration for enabling the change feed on a
+// backend
+type ChangeFeedConfig struct {
+	// JournalPath is the file the change journal is persisted to.
+	// If empty, the journal is kept in memory and lost on restart.
+	JournalPath string
+
+	// MaxEntries is the number of changes retained for consumers to catch
+	// up on. Zero uses changefeed.DefaultMaxEntries.
+	MaxEntries int
+}
+
+// EnableChangeFeed records the changes made to a backend through the
+// facade in an ordered journal that consumers read with GetChanges. The
+// journal returned by ChangeFeed should be closed on shutdown.
+//
+// Call EnableChangeFeed after the wrappers that can refuse a change
+// (locks, content policy, retention, manifests, transforms) and before
+// EnableSearch, so only changes that were made are recorded.
+//
+// Example usage:
+//
+//	objstore.EnableChangeFeed("", &objstore.ChangeFeedConfig{
+//	    JournalPath: "/data/.changes.jsonl",
+//	})
+func EnableChangeFeed(backendName string, config *ChangeFeedConfig) error {
+	if config == nil {
+		config = &ChangeFeedConfig{}
+	}
+
+	if !IsInitialized() {
+		return ErrNotInitialized
+	}
+
+	name := backendName
+	if name == "" {
+		facade.mu.RLock()
+		name = facade.defaultBackend
+		facade.mu.RUnlock()
+	} else if err := validation.ValidateBackendName(name); err != nil {
+		return fmt.Errorf("invalid backend name: %w", err)
+	}
+
+	storage, err := Backend(name)
+	if err != nil {
+		return err
+	}
+	if _, err := findJournal(storage); err == nil {
+		return nil
+	}
+
+	journal, err := changefeed.NewJournal(config.JournalPath, config.MaxEntries)
+	if err != nil {
+		return fmt.Errorf("failed to open change journal: %w", err)
+	}
+
+	facade.mu.Lock()
+	facade.backends[name] = changefeed.NewStorage(storage, journal)
+	facade.mu.Unlock()
+
+	return nil
+}
+
+// ChangeFeed returns the change journal of a backend. The change feed must
+// first be enabled with EnableChangeFeed.
+func ChangeFeed(backendName string) (*changefeed.Journal, error) {
 	var storage common.Storage
 	var err error
 
@@ -622,66 +4024,107 @@ func GetReplicationManager(backendName string) (common.ReplicationManager, error
 		}
 		storage, err = Backend(backendName)
 	}
-
 	if err != nil {
 		return nil, err
 	}
+	return findJournal(storage)
+}
 
-	// Check if backend supports replication
-	replicable, ok := storage.(common.ReplicationCapable)
-	if !ok {
-		return nil, common.ErrReplicationNotSupported
+// GetChanges returns up to limit changes made to a backend after sequence
+// since, oldest first. Pass 0 to start from the oldest retained change and
+// the returned page's Next to continue. changefeed.ErrSequenceExpired means
+// the journal no longer holds the changes after since, and the consumer
+// must resynchronize from a full listing.
+func GetChanges(ctx context.Context, backendName string, since uint64, limit int) (*changefeed.Page, error) {
+	journal, err := ChangeFeed(backendName)
+	if err != nil {
+		return nil, err
 	}
-
-	return replicable.GetReplicationManager()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return journal.GetChanges(since, limit)
 }
 
-// ReplicationConfig contains configuration for enabling replication on a backend
-type ReplicationConfig struct {
-	// PolicyFilePath is the path to the replication policy file.
-	// If empty, defaults to ".replication-policies.json" in the current directory.
-	PolicyFilePath string
-
-	// Interval is the interval between automatic sync operations.
-	// If zero, defaults to 5 minutes.
-	Interval time.Duration
-
-	// Logger is the logger to use for replication operations.
-	// If nil, a no-op logger is used.
-	Logger adapters.Logger
-
-	// AuditLog is the audit logger to use for replication operations.
-	// If nil, a no-op audit logger is used.
-	AuditLog audit.AuditLogger
-
-	// RunInBackground starts a background goroutine to run periodic syncs.
-	// If false, syncs must be triggered manually.
-	RunInBackground bool
+// findJournal looks for a change feed wrapper in storage's chain of
+// wrapped backends.
+func findJournal(storage common.Storage) (*changefeed.Journal, error) {
+	if recording, ok := findCapability[*changefeed.Storage](storage); ok {
+		return recording.Journal(), nil
+	}
+	return nil, ErrChangeFeedNotEnabled
 }
 
-// ReplicationManagerSetter is an interface for backends that can have a replication manager set
-type ReplicationManagerSetter interface {
-	SetReplicationManager(rm common.ReplicationManager)
+// SearchConfig contains configuration for enabling search on a backend
+type SearchConfig struct {
+	// IndexPath is the file the search index is persisted to.
+	// If empty, the index is kept in memory and rebuilt on every start.
+	IndexPath string
+
+	// Rebuild forces a full listing of the backend to repopulate the index.
+	// An empty index is always rebuilt.
+	Rebuild bool
 }
 
-// EnableReplication creates and configures a replication manager for a backend.
-// The backend must implement both ReplicationCapable and ReplicationManagerSetter interfaces.
-// This function creates a PersistentReplicationManager using the OSFileSystem and
-// attaches it to the backend.
+// EnableSearch indexes a backend's keys and metadata so it can be queried
+// with Search. Writes made through the facade keep the index up to date;
+// writes made directly to the backend are picked up by the next rebuild.
+//
+// Call EnableSearch after EnableReplication: the backend is wrapped, so
+// capabilities detected on it afterwards are no longer visible.
 //
 // Example usage:
 //
-//	objstore.EnableReplication("local", &objstore.ReplicationConfig{
-//	    PolicyFilePath: "/data/replication-policies.json",
-//	    Interval:       10 * time.Minute,
-//	    RunInBackground: true,
+//	objstore.EnableSearch("", &objstore.SearchConfig{
+//	    IndexPath: "/data/.search-index.json",
 //	})
-func EnableReplication(backendName string, config *ReplicationConfig) error {
+func EnableSearch(backendName string, config *SearchConfig) error {
 	if config == nil {
-		config = &ReplicationConfig{}
+		config = &SearchConfig{}
 	}
 
-	// Get the backend
+	if !IsInitialized() {
+		return ErrNotInitialized
+	}
+
+	name := backendName
+	if name == "" {
+		facade.mu.RLock()
+		name = facade.defaultBackend
+		facade.mu.RUnlock()
+	} else if err := validation.ValidateBackendName(name); err != nil {
+		return fmt.Errorf("invalid backend name: %w", err)
+	}
+
+	storage, err := Backend(name)
+	if err != nil {
+		return err
+	}
+	if _, ok := storage.(*search.Storage); ok {
+		return nil
+	}
+
+	index, err := search.NewIndex(config.IndexPath)
+	if err != nil {
+		return fmt.Errorf("failed to open search index: %w", err)
+	}
+	if config.Rebuild || index.Len() == 0 {
+		if err := index.Rebuild(context.Background(), storage); err != nil {
+			return fmt.Errorf("failed to build search index: %w", err)
+		}
+	}
+
+	facade.mu.Lock()
+	facade.backends[name] = search.NewStorage(storage, index)
+	facade.mu.Unlock()
+
+	return nil
+}
+
+// Search returns the objects in a backend whose key or metadata match query.
+// Search must first be enabled on the backend with EnableSearch.
+// See search.Index.Search for the query syntax.
+func Search(ctx context.Context, backendName, query string, limit int) ([]search.Document, error) {
 	var storage common.Storage
 	var err error
 
@@ -689,61 +4132,128 @@ func EnableReplication(backendName string, config *ReplicationConfig) error {
 		storage, err = DefaultBackend()
 	} else {
 		if err := validation.ValidateBackendName(backendName); err != nil {
-			return fmt.Errorf("invalid backend name: %w", err)
+			return nil, fmt.Errorf("invalid backend name: %w", err)
 		}
 		storage, err = Backend(backendName)
 	}
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Check if backend supports setting replication manager
-	setter, ok := storage.(ReplicationManagerSetter)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	indexed, ok := storage.(*search.Storage)
 	if !ok {
-		return fmt.Errorf("backend does not support setting replication manager")
+		return nil, ErrSearchNotEnabled
 	}
 
-	// Set defaults
-	policyFile := config.PolicyFilePath
-	if policyFile == "" {
-		policyFile = ".replication-policies.json"
+	return indexed.Search(query, limit)
+}
+
+// EnableAccessTracking records when each object of a backend (empty name
+// selects the default backend) is read through the facade, so Analyze can
+// report how long objects go unread. Reads are sampled at cfg.SampleRate
+// and, when cfg.Path is set, saved there every cfg.FlushInterval.
+// Enabling tracking again keeps the running tracker.
+//
+// Call EnableAccessTracking after the wrappers that serve reads from
+// elsewhere, such as caches, so only reads clients make are recorded.
+//
+// Example usage:
+//
+//	objstore.EnableAccessTracking("", &access.Config{
+//	    SampleRate: 0.1,
+//	    Path:       "/data/.access.json",
+//	})
+func EnableAccessTracking(backendName string, cfg *access.Config) error {
+	if !IsInitialized() {
+		return ErrNotInitialized
 	}
 
-	interval := config.Interval
-	if interval == 0 {
-		interval = 5 * time.Minute
+	name := backendName
+	if name == "" {
+		facade.mu.RLock()
+		name = facade.defaultBackend
+		facade.mu.RUnlock()
+	} else if err := validation.ValidateBackendName(name); err != nil {
+		return fmt.Errorf("invalid backend name: %w", err)
 	}
 
-	logger := config.Logger
-	if logger == nil {
-		logger = adapters.NewNoOpLogger()
+	storage, err := Backend(name)
+	if err != nil {
+		return err
+	}
+	if _, err := AccessTracker(name); err == nil {
+		return nil
 	}
 
-	auditLog := config.AuditLog
-	if auditLog == nil {
-		auditLog = audit.NewNoOpAuditLogger()
+	tracker, err := access.NewTracker(cfg)
+	if err != nil {
+		return err
 	}
+	tracker.Start()
 
-	// Create the replication manager with OSFileSystem
-	rm, err := replication.NewPersistentReplicationManager(
-		&replication.OSFileSystem{},
-		policyFile,
-		interval,
-		logger,
-		auditLog,
-	)
+	facade.mu.Lock()
+	facade.backends[name] = access.NewStorage(storage, tracker)
+	facade.trackers = append(facade.trackers, tracker)
+	facade.mu.Unlock()
+	return nil
+}
+
+// AccessTracker returns the read tracker of a backend (empty name selects
+// the default backend) enabled with EnableAccessTracking.
+func AccessTracker(backendName string) (*access.Tracker, error) {
+	var storage common.Storage
+	var err error
+	if backendName == "" {
+		storage, err = DefaultBackend()
+	} else {
+		storage, err = Backend(backendName)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to create replication manager: %w", err)
+		return nil, err
+	}
+	if tracked, ok := findCapability[*access.Storage](storage); ok {
+		return tracked.Tracker(), nil
 	}
+	return nil, ErrAccessNotEnabled
+}
 
-	// Set the replication manager on the backend
-	setter.SetReplicationManager(rm)
+// Analyze reports how the objects under prefix on a backend (empty name
+// selects the default backend) are distributed by age and, when access
+// tracking is enabled, by the time since they were last read. It lists
+// every object under prefix. See access.Analyze.
+//
+// Example usage:
+//
+//	report, err := objstore.Analyze(ctx, "", "logs/", nil)
+func Analyze(ctx context.Context, backendName, prefix string, opts *access.AnalyzeOptions) (*access.Report, error) {
+	var storage common.Storage
+	var err error
+	if backendName == "" {
+		storage, err = DefaultBackend()
+	} else {
+		if err := validation.ValidateBackendName(backendName); err != nil {
+			return nil, fmt.Errorf("invalid backend name: %w", err)
+		}
+		storage, err = Backend(backendName)
+	}
+	if err != nil {
+		return nil, err
+	}
 
-	// Start background processing if requested
-	if config.RunInBackground {
-		go rm.Run(context.Background())
+	if prefix != "" {
+		if err := validation.ValidatePrefix(prefix); err != nil {
+			return nil, fmt.Errorf("invalid prefix: %w", err)
+		}
 	}
 
-	return nil
+	tracker, err := AccessTracker(backendName)
+	if err != nil && !errors.Is(err, ErrAccessNotEnabled) {
+		return nil, err
+	}
+	return access.Analyze(ctx, storage, tracker, prefix, opts)
 }
@@ -16,12 +16,56 @@ package objstore
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"image"
+	"image/png"
 	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/jeremyhahn/go-objstore/pkg/access"
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/alias"
+	"github.com/jeremyhahn/go-objstore/pkg/changefeed"
+	"github.com/jeremyhahn/go-objstore/pkg/checksum"
 	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/concurrency"
+	"github.com/jeremyhahn/go-objstore/pkg/contentpolicy"
+	"github.com/jeremyhahn/go-objstore/pkg/cost"
+	"github.com/jeremyhahn/go-objstore/pkg/diff"
+	"github.com/jeremyhahn/go-objstore/pkg/events"
+	"github.com/jeremyhahn/go-objstore/pkg/factory"
+	"github.com/jeremyhahn/go-objstore/pkg/ha"
+	"github.com/jeremyhahn/go-objstore/pkg/health"
+	"github.com/jeremyhahn/go-objstore/pkg/jobs"
+	"github.com/jeremyhahn/go-objstore/pkg/keypolicy"
+	"github.com/jeremyhahn/go-objstore/pkg/local"
+	"github.com/jeremyhahn/go-objstore/pkg/locks"
+	"github.com/jeremyhahn/go-objstore/pkg/manifest"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+	"github.com/jeremyhahn/go-objstore/pkg/overlay"
+	"github.com/jeremyhahn/go-objstore/pkg/query"
+	"github.com/jeremyhahn/go-objstore/pkg/residency"
+	"github.com/jeremyhahn/go-objstore/pkg/retention"
+	"github.com/jeremyhahn/go-objstore/pkg/routing"
+	"github.com/jeremyhahn/go-objstore/pkg/scrub"
+	"github.com/jeremyhahn/go-objstore/pkg/search"
+	"github.com/jeremyhahn/go-objstore/pkg/share"
+	"github.com/jeremyhahn/go-objstore/pkg/stats"
+	"github.com/jeremyhahn/go-objstore/pkg/storagefs"
+	"github.com/jeremyhahn/go-objstore/pkg/tasks"
+	"github.com/jeremyhahn/go-objstore/pkg/tombstone"
+	"github.com/jeremyhahn/go-objstore/pkg/transform"
+	"github.com/jeremyhahn/go-objstore/pkg/upload"
 )
 
 // Mock storage implementation for testing
@@ -723,6 +767,102 @@ func TestPutWithMetadata(t *testing.T) {
 	}
 }
 
+func TestPutWithInfo(t *testing.T) {
+	Reset()
+	defer Reset()
+	err := Initialize(&FacadeConfig{
+		Backends: map[string]common.Storage{
+			"local":  newMockStorage("local"),
+			"memory": memory.New(),
+		},
+		DefaultBackend: "local",
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+	ctx := context.Background()
+
+	// A backend that reports writes returns its metadata
+	info, err := PutWithInfo(ctx, "memory:test.txt", strings.NewReader("data"), &common.Metadata{ContentType: "text/plain"})
+	if err != nil {
+		t.Fatalf("PutWithInfo() error = %v", err)
+	}
+	if info.Key != "test.txt" || info.Metadata.Size != 4 || info.Metadata.ETag == "" || info.Metadata.ContentType != "text/plain" {
+		t.Errorf("PutWithInfo() = %+v, %+v", info, info.Metadata)
+	}
+
+	// Other backends have their metadata read after the write
+	info, err = PutWithInfo(ctx, "test.txt", strings.NewReader("data"), nil)
+	if err != nil {
+		t.Fatalf("PutWithInfo() error = %v", err)
+	}
+	if info.Key != "test.txt" || info.Metadata.Size != 4 {
+		t.Errorf("PutWithInfo() = %+v, %+v", info, info.Metadata)
+	}
+
+	if _, err := PutWithInfo(ctx, "../test.txt", strings.NewReader("data"), nil); err == nil {
+		t.Error("Expected error for invalid key")
+	}
+}
+
+// metadataCounter counts the metadata reads of a backend that reports
+// its writes.
+type metadataCounter struct {
+	*memory.Memory
+	reads int
+}
+
+func (m *metadataCounter) GetMetadata(ctx context.Context, key string) (*common.Metadata, error) {
+	m.reads++
+	return m.Memory.GetMetadata(ctx, key)
+}
+
+func TestPutWithInfoWrapped(t *testing.T) {
+	Reset()
+	defer Reset()
+	backend := &metadataCounter{Memory: memory.New().(*memory.Memory)}
+	err := Initialize(&FacadeConfig{
+		Backends:       map[string]common.Storage{"mem": backend},
+		DefaultBackend: "mem",
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+	for _, enable := range []func() error{
+		func() error { return EnableChecksums("") },
+		func() error { return EnableConcurrencyLimit("", nil) },
+		func() error { return EnableTombstones("", nil) },
+		func() error { return EnableLocks("", "") },
+		func() error { return EnableUploads("", "") },
+		func() error { return EnableAliases("", "") },
+	} {
+		if err := enable(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ctx := context.Background()
+
+	backend.reads = 0
+	info, err := PutWithInfo(ctx, "test.txt", strings.NewReader("data"), &common.Metadata{ContentType: "text/plain"})
+	if err != nil {
+		t.Fatalf("PutWithInfo() error = %v", err)
+	}
+	stored, err := backend.Memory.GetMetadata(ctx, "test.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Key != "test.txt" || info.Metadata.ETag != stored.ETag || info.Metadata.Size != 4 {
+		t.Errorf("PutWithInfo() = %+v, want the stored %+v", info.Metadata, stored)
+	}
+	if backend.reads != 0 {
+		t.Errorf("PutWithInfo() read the metadata back %d times through the wrappers", backend.reads)
+	}
+
+	if _, err := PutWithInfo(ctx, common.StagingPrefix+"x", strings.NewReader("data"), nil); !errors.Is(err, common.ErrReservedKey) {
+		t.Errorf("PutWithInfo() in a reserved namespace error = %v, want ErrReservedKey", err)
+	}
+}
+
 func TestReset(t *testing.T) {
 	// Initialize
 	err := Initialize(&FacadeConfig{
@@ -1031,6 +1171,60 @@ func TestArchive(t *testing.T) {
 	}
 }
 
+func TestRestoreFromArchive(t *testing.T) {
+	Reset()
+	err := Initialize(&FacadeConfig{
+		Backends: map[string]common.Storage{
+			"hot": memory.New(),
+		},
+		DefaultBackend: "hot",
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+
+	ctx := context.Background()
+	archive := memory.New()
+	err = archive.PutWithMetadata(ctx, "cold.txt", strings.NewReader("cold data"), &common.Metadata{
+		ContentType: "text/plain",
+		Custom:      map[string]string{"tier": "archive"},
+	})
+	if err != nil {
+		t.Fatalf("PutWithMetadata() error = %v", err)
+	}
+
+	if err := RestoreFromArchive(ctx, "hot:warm.txt", archive, "cold.txt"); err != nil {
+		t.Fatalf("RestoreFromArchive() error = %v", err)
+	}
+	md, err := GetMetadata(ctx, "warm.txt")
+	if err != nil {
+		t.Fatalf("GetMetadata() error = %v", err)
+	}
+	if md.Size != 9 || md.ContentType != "text/plain" || md.Custom["tier"] != "archive" {
+		t.Errorf("restored metadata = %+v", md)
+	}
+
+	tests := []struct {
+		name      string
+		keyRef    string
+		sourceKey string
+		wantErr   bool
+	}{
+		{"defaults to same key", "cold.txt", "", false},
+		{"missing source object", "missing.txt", "", true},
+		{"invalid key", "../test.txt", "cold.txt", true},
+		{"invalid source key", "ok.txt", "../cold.txt", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := RestoreFromArchive(ctx, tt.keyRef, archive, tt.sourceKey)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RestoreFromArchive() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestAddPolicy(t *testing.T) {
 	Reset()
 	mock := newMockStorage("local")
@@ -1357,6 +1551,38 @@ func TestListWithOptionsSpecificBackend(t *testing.T) {
 	}
 }
 
+func TestListWithOptionsSorted(t *testing.T) {
+	Reset()
+	mock := newMockStorage("local")
+	mock.objects["logs/a.log"] = []byte("aa")
+	mock.objects["logs/b.log"] = []byte("bbbb")
+	mock.objects["logs/c.log"] = []byte("c")
+
+	if err := Initialize(&FacadeConfig{
+		Backends:       map[string]common.Storage{"local": mock},
+		DefaultBackend: "local",
+	}); err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+
+	ctx := context.Background()
+	result, err := ListWithOptions(ctx, "", &common.ListOptions{Prefix: "logs/", SortBy: common.ListSortSize, SortDescending: true})
+	if err != nil {
+		t.Fatalf("ListWithOptions() error = %v", err)
+	}
+	var keys []string
+	for _, obj := range result.Objects {
+		keys = append(keys, obj.Key)
+	}
+	if strings.Join(keys, ",") != "logs/b.log,logs/a.log,logs/c.log" {
+		t.Errorf("ListWithOptions() sorted by size = %v", keys)
+	}
+
+	if _, err := ListWithOptions(ctx, "", &common.ListOptions{SortBy: "owner"}); !errors.Is(err, common.ErrInvalidArgument) {
+		t.Errorf("ListWithOptions(sort owner) = %v, want ErrInvalidArgument", err)
+	}
+}
+
 func TestFacadeNotInitialized(t *testing.T) {
 	Reset()
 
@@ -1480,3 +1706,2514 @@ func BenchmarkGetWithContext(b *testing.B) {
 		}
 	}
 }
+
+func TestEnableSearch(t *testing.T) {
+	Reset()
+	mock := newMockStorage("local")
+	mock.objects["docs/existing-report.txt"] = []byte("a")
+
+	err := Initialize(&FacadeConfig{
+		Backends: map[string]common.Storage{
+			"local": mock,
+		},
+		DefaultBackend: "local",
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+
+	ctx := context.Background()
+	if _, err := Search(ctx, "", "report", 0); !errors.Is(err, ErrSearchNotEnabled) {
+		t.Errorf("Expected ErrSearchNotEnabled, got %v", err)
+	}
+
+	if err := EnableSearch("", nil); err != nil {
+		t.Fatalf("EnableSearch() error = %v", err)
+	}
+
+	// Objects written before search was enabled are indexed by the rebuild,
+	// later writes through the facade by the wrapper.
+	if err := PutWithMetadata(ctx, "docs/new-report.txt", strings.NewReader("b"), &common.Metadata{
+		Custom: map[string]string{"owner": "ops"},
+	}); err != nil {
+		t.Fatalf("PutWithMetadata() error = %v", err)
+	}
+
+	docs, err := Search(ctx, "local", "report", 0)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(docs) != 2 {
+		t.Errorf("Expected 2 results, got %d", len(docs))
+	}
+
+	if err := DeleteWithContext(ctx, "docs/existing-report.txt"); err != nil {
+		t.Fatalf("DeleteWithContext() error = %v", err)
+	}
+	docs, err = Search(ctx, "", "report", 0)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(docs) != 1 || docs[0].Key != "docs/new-report.txt" {
+		t.Errorf("Expected only docs/new-report.txt, got %v", docs)
+	}
+
+	// Enabling twice keeps the existing index.
+	if err := EnableSearch("local", nil); err != nil {
+		t.Errorf("EnableSearch() second call error = %v", err)
+	}
+	if err := EnableSearch("missing", nil); !errors.Is(err, ErrBackendNotFound) {
+		t.Errorf("Expected ErrBackendNotFound, got %v", err)
+	}
+}
+
+func TestEnableConcurrencyLimit(t *testing.T) {
+	Reset()
+	if err := EnableConcurrencyLimit("", nil); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("Expected ErrNotInitialized, got %v", err)
+	}
+
+	backend := memory.New()
+	err := Initialize(&FacadeConfig{
+		Backends:       map[string]common.Storage{"mem": backend},
+		DefaultBackend: "mem",
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+	defer Reset()
+
+	if _, err := ConcurrencyLimiter(""); !errors.Is(err, ErrConcurrencyLimitNotEnabled) {
+		t.Errorf("Expected ErrConcurrencyLimitNotEnabled, got %v", err)
+	}
+	if err := EnableConcurrencyLimit("", &concurrency.Config{Initial: 0, Min: 8, Max: 4}); !errors.Is(err, concurrency.ErrInvalidConfig) {
+		t.Errorf("Expected ErrInvalidConfig, got %v", err)
+	}
+	if err := EnableConcurrencyLimit("", &concurrency.Config{Initial: 4}); err != nil {
+		t.Fatalf("EnableConcurrencyLimit() error = %v", err)
+	}
+	// Enabling again does not stack wrappers.
+	if err := EnableConcurrencyLimit("mem", nil); err != nil {
+		t.Fatalf("EnableConcurrencyLimit() second call error = %v", err)
+	}
+	storage, _ := Backend("mem")
+	wrapped, ok := storage.(*concurrency.Storage)
+	if !ok || wrapped.Underlying().(*common.ReservedStorage).Underlying() != backend {
+		t.Fatalf("Expected a single concurrency wrapper, got %T", storage)
+	}
+	limiter, err := ConcurrencyLimiter("mem")
+	if err != nil || limiter.Limit() != 4 {
+		t.Fatalf("ConcurrencyLimiter() = %v, %v", limiter, err)
+	}
+
+	if err := PutWithContext(context.Background(), "a.txt", strings.NewReader("hello")); err != nil {
+		t.Fatalf("PutWithContext() error = %v", err)
+	}
+}
+
+func TestEnableChecksums(t *testing.T) {
+	Reset()
+	if err := EnableChecksums(""); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("Expected ErrNotInitialized, got %v", err)
+	}
+
+	backend := memory.New()
+	err := Initialize(&FacadeConfig{
+		Backends:       map[string]common.Storage{"mem": backend},
+		DefaultBackend: "mem",
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+	defer Reset()
+
+	if err := EnableChecksums(""); err != nil {
+		t.Fatalf("EnableChecksums() error = %v", err)
+	}
+	// Enabling again does not stack wrappers.
+	if err := EnableChecksums("mem"); err != nil {
+		t.Fatalf("EnableChecksums() second call error = %v", err)
+	}
+	storage, _ := Backend("mem")
+	wrapped, ok := storage.(*checksum.Storage)
+	if !ok || wrapped.Underlying().(*common.ReservedStorage).Underlying() != backend {
+		t.Fatalf("Expected a single checksum wrapper, got %T", storage)
+	}
+
+	ctx := context.Background()
+	if err := PutWithContext(ctx, "a.txt", strings.NewReader("hello")); err != nil {
+		t.Fatalf("PutWithContext() error = %v", err)
+	}
+	metadata, err := backend.GetMetadata(ctx, "a.txt")
+	if err != nil {
+		t.Fatalf("GetMetadata() error = %v", err)
+	}
+	if common.StrongValidator(metadata) == "" {
+		t.Errorf("Expected a recorded digest, got %v", metadata.Custom)
+	}
+}
+
+func TestFindDuplicates(t *testing.T) {
+	Reset()
+	ctx := context.Background()
+	if _, err := FindDuplicates(ctx, "", "", nil); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("Expected ErrNotInitialized, got %v", err)
+	}
+
+	if err := Initialize(&FacadeConfig{
+		Backends:       map[string]common.Storage{"mem": memory.New()},
+		DefaultBackend: "mem",
+	}); err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+	defer Reset()
+	if err := EnableChecksums(""); err != nil {
+		t.Fatalf("EnableChecksums() error = %v", err)
+	}
+	for _, key := range []string{"a.txt", "b.txt", "c.txt"} {
+		if err := PutWithContext(ctx, key, strings.NewReader("hello")); err != nil {
+			t.Fatalf("PutWithContext() error = %v", err)
+		}
+	}
+
+	report, err := FindDuplicates(ctx, "mem", "", nil)
+	if err != nil {
+		t.Fatalf("FindDuplicates() error = %v", err)
+	}
+	if len(report.Sets) != 1 || len(report.Sets[0].Keys) != 3 || report.Savings != 10 {
+		t.Errorf("FindDuplicates() = %+v, want one set of 3 saving 10 bytes", report)
+	}
+	if _, err := FindDuplicates(ctx, "", "../x", nil); err == nil {
+		t.Error("Expected an error for an invalid prefix")
+	}
+}
+
+// versionedStorage answers GetAsOf with the version recorded for at.
+type versionedStorage struct {
+	common.Storage
+	versions map[time.Time]string
+}
+
+func (v *versionedStorage) GetAsOf(_ context.Context, key string, at time.Time) (io.ReadCloser, *common.Metadata, error) {
+	data, ok := v.versions[at]
+	if !ok {
+		return nil, nil, common.ErrKeyNotFound
+	}
+	return io.NopCloser(strings.NewReader(data)), &common.Metadata{Size: int64(len(data))}, nil
+}
+
+func TestGetAsOf(t *testing.T) {
+	Reset()
+	ctx := context.Background()
+	yesterday := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	if _, _, err := GetAsOf(ctx, "config.json", yesterday); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("Expected ErrNotInitialized, got %v", err)
+	}
+
+	versioned := &versionedStorage{
+		Storage:  memory.New(),
+		versions: map[time.Time]string{yesterday: `{"replicas":2}`},
+	}
+	err := Initialize(&FacadeConfig{
+		Backends: map[string]common.Storage{
+			"versioned": checksum.NewStorage(versioned),
+			"plain":     memory.New(),
+		},
+		DefaultBackend: "versioned",
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+	defer Reset()
+
+	// The checksum wrapper reads earlier versions from the backend it wraps.
+	reader, metadata, err := GetAsOf(ctx, "config.json", yesterday)
+	if err != nil {
+		t.Fatalf("GetAsOf() error = %v", err)
+	}
+	data, _ := io.ReadAll(reader)
+	_ = reader.Close()
+	if string(data) != `{"replicas":2}` || metadata.Size != int64(len(data)) {
+		t.Errorf("GetAsOf() = %q, %+v", data, metadata)
+	}
+
+	if _, _, err := GetAsOf(ctx, "config.json", yesterday.Add(-time.Hour)); !errors.Is(err, common.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound before the first version, got %v", err)
+	}
+	if _, _, err := GetAsOf(ctx, "plain:config.json", yesterday); !errors.Is(err, ErrVersionsNotSupported) {
+		t.Errorf("Expected ErrVersionsNotSupported, got %v", err)
+	}
+	if _, _, err := GetAsOf(ctx, "../etc/passwd", yesterday); err == nil {
+		t.Error("Expected an invalid key reference error")
+	}
+}
+
+// historyStorage keeps snapshots of the objects in a backend, taken with
+// snapshot, and answers GetAsOf from the last snapshot taken by at.
+type historyStorage struct {
+	*memory.Memory
+	times     []time.Time
+	snapshots []map[string]historyVersion
+}
+
+type historyVersion struct {
+	data     string
+	metadata *common.Metadata
+}
+
+func (h *historyStorage) snapshot(t *testing.T, at time.Time) {
+	t.Helper()
+	ctx := context.Background()
+	keys, err := h.Memory.ListWithContext(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	versions := make(map[string]historyVersion, len(keys))
+	for _, key := range keys {
+		rc, err := h.Memory.GetWithContext(ctx, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(rc)
+		_ = rc.Close()
+		metadata, err := h.Memory.GetMetadata(ctx, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		versions[key] = historyVersion{data: string(data), metadata: metadata}
+	}
+	h.times = append(h.times, at)
+	h.snapshots = append(h.snapshots, versions)
+}
+
+func (h *historyStorage) GetAsOf(_ context.Context, key string, at time.Time) (io.ReadCloser, *common.Metadata, error) {
+	for i := len(h.times) - 1; i >= 0; i-- {
+		if h.times[i].After(at) {
+			continue
+		}
+		if v, ok := h.snapshots[i][key]; ok {
+			return io.NopCloser(strings.NewReader(v.data)), v.metadata, nil
+		}
+		break
+	}
+	return nil, nil, common.ErrKeyNotFound
+}
+
+// testXOR is a reversible stand-in for a real cipher.
+type testXOR struct{}
+
+func (testXOR) xor(r io.Reader) (io.ReadCloser, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	for i := range data {
+		data[i] ^= 0x5a
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (x testXOR) Encrypt(_ context.Context, r io.Reader) (io.ReadCloser, error) { return x.xor(r) }
+func (x testXOR) Decrypt(_ context.Context, r io.Reader) (io.ReadCloser, error) { return x.xor(r) }
+func (testXOR) Algorithm() string                                               { return "xor" }
+func (testXOR) KeyID() string                                                   { return "default" }
+func (x testXOR) GetEncrypter(string) (common.Encrypter, error)                 { return x, nil }
+func (testXOR) DefaultKeyID() string                                            { return "default" }
+func (testXOR) Close() error                                                    { return nil }
+
+func TestGetAsOfWrapped(t *testing.T) {
+	Reset()
+	ctx := context.Background()
+	history := &historyStorage{Memory: memory.New().(*memory.Memory)}
+	err := Initialize(&FacadeConfig{
+		Backends:       map[string]common.Storage{"versioned": history},
+		DefaultBackend: "versioned",
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+	defer Reset()
+	encrypt := true
+	err = EnableOverlays("", &overlay.Config{
+		Overlays:  []overlay.Overlay{{Prefix: "secret/", Encryption: &encrypt}},
+		Encrypter: testXOR{},
+	})
+	if err != nil {
+		t.Fatalf("EnableOverlays() error = %v", err)
+	}
+	if err := EnableTombstones("", nil); err != nil {
+		t.Fatalf("EnableTombstones() error = %v", err)
+	}
+	if err := EnableLocks("", ""); err != nil {
+		t.Fatalf("EnableLocks() error = %v", err)
+	}
+
+	earlier := time.Now().Add(-2 * time.Hour)
+	later := earlier.Add(time.Hour)
+	if err := PutWithContext(ctx, "secret/plan.txt", strings.NewReader("v1")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := TryLock(ctx, "jobs/a", "w", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	history.snapshot(t, earlier)
+	if err := PutWithContext(ctx, "secret/plan.txt", strings.NewReader("v2")); err != nil {
+		t.Fatal(err)
+	}
+	history.snapshot(t, later)
+
+	// Encrypted versions are decrypted like current ones.
+	reader, _, err := GetAsOf(ctx, "secret/plan.txt", earlier)
+	if err != nil {
+		t.Fatalf("GetAsOf() error = %v", err)
+	}
+	data, _ := io.ReadAll(reader)
+	_ = reader.Close()
+	if string(data) != "v1" {
+		t.Errorf("GetAsOf() = %q, want the decrypted v1", data)
+	}
+	if stored := history.snapshots[0]["secret/plan.txt"].data; stored == "v1" {
+		t.Error("Expected the stored version to be encrypted")
+	}
+
+	// Reserved keys stay hidden, whatever the backend kept of them.
+	for _, key := range []string{".locks/jobs/a", common.StagingPrefix + "x"} {
+		if _, _, err := GetAsOf(ctx, key, later); !errors.Is(err, common.ErrReservedKey) {
+			t.Errorf("GetAsOf(%q) error = %v, want ErrReservedKey", key, err)
+		}
+	}
+	if _, ok := history.snapshots[0][".locks/jobs/a"]; !ok {
+		t.Error("Expected the backend to keep the lock")
+	}
+
+	// Versions from before a delete are readable, later ones are not.
+	if err := DeleteWithContext(ctx, "secret/plan.txt"); err != nil {
+		t.Fatal(err)
+	}
+	reader, _, err = GetAsOf(ctx, "secret/plan.txt", later)
+	if err != nil {
+		t.Fatalf("GetAsOf() before the delete error = %v", err)
+	}
+	_ = reader.Close()
+	if _, _, err := GetAsOf(ctx, "secret/plan.txt", time.Now()); !errors.Is(err, common.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after the delete, got %v", err)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	Reset()
+	ctx := context.Background()
+	yesterday := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	versioned := &versionedStorage{
+		Storage:  memory.New(),
+		versions: map[time.Time]string{yesterday: `{"replicas":2}`},
+	}
+	err := Initialize(&FacadeConfig{
+		Backends:       map[string]common.Storage{"versioned": versioned, "plain": memory.New()},
+		DefaultBackend: "versioned",
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+	defer Reset()
+
+	if err := PutWithContext(ctx, "config.json", strings.NewReader(`{"replicas":3}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := PutWithContext(ctx, "plain:config.json", strings.NewReader(`{"replicas":3}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := Diff(ctx, "config.json@"+yesterday.Format(time.RFC3339), "config.json", diff.Options{})
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	if result.Mode != diff.ModeJSON || len(result.Changes) != 1 || result.Changes[0].Path != "/replicas" {
+		t.Errorf("Diff() = %+v, want /replicas replaced", result)
+	}
+
+	// Across backends.
+	result, err = Diff(ctx, "config.json", "plain:config.json", diff.Options{})
+	if err != nil || !result.Identical {
+		t.Errorf("Diff() across backends = %+v, %v, want identical", result, err)
+	}
+
+	if _, err := Diff(ctx, "config.json", "missing.json", diff.Options{}); !errors.Is(err, common.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	if _, err := Diff(ctx, "plain:config.json@"+yesterday.Format(time.RFC3339), "config.json", diff.Options{}); !errors.Is(err, ErrVersionsNotSupported) {
+		t.Errorf("Expected ErrVersionsNotSupported, got %v", err)
+	}
+}
+
+func TestEnableContentPolicy(t *testing.T) {
+	Reset()
+	if err := EnableContentPolicy("", &contentpolicy.Policy{}); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("Expected ErrNotInitialized, got %v", err)
+	}
+
+	err := Initialize(&FacadeConfig{
+		Backends: map[string]common.Storage{
+			"local": newMockStorage("local"),
+		},
+		DefaultBackend: "local",
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+
+	if err := EnableContentPolicy("", nil); !errors.Is(err, contentpolicy.ErrInvalidPolicy) {
+		t.Errorf("Expected ErrInvalidPolicy, got %v", err)
+	}
+	policy := &contentpolicy.Policy{
+		Rules: []contentpolicy.Rule{{Prefix: "uploads/", Deny: []string{contentpolicy.TypeELF}}},
+	}
+	if err := EnableContentPolicy("local", policy); err != nil {
+		t.Fatalf("EnableContentPolicy() error = %v", err)
+	}
+
+	ctx := context.Background()
+	err = PutWithContext(ctx, "uploads/tool", strings.NewReader("\x7fELF\x02\x01\x01"))
+	if !errors.Is(err, contentpolicy.ErrContentTypeRejected) || !errors.Is(err, common.ErrInvalidArgument) {
+		t.Errorf("Expected ErrContentTypeRejected wrapping ErrInvalidArgument, got %v", err)
+	}
+	if err := PutWithContext(ctx, "bin/tool", strings.NewReader("\x7fELF\x02\x01\x01")); err != nil {
+		t.Errorf("PutWithContext() outside the rule error = %v", err)
+	}
+
+	// Enabling again replaces the policy instead of stacking wrappers.
+	if err := EnableContentPolicy("", &contentpolicy.Policy{}); err != nil {
+		t.Fatalf("EnableContentPolicy() second call error = %v", err)
+	}
+	storage, _ := Backend("local")
+	wrapped, ok := storage.(*contentpolicy.Storage)
+	if !ok || len(wrapped.Policy().Rules) != 0 {
+		t.Fatalf("Expected the replacement policy, got %T", storage)
+	}
+	if _, ok := wrapped.Underlying().(*contentpolicy.Storage); ok {
+		t.Error("Expected content policy wrappers not to stack")
+	}
+	if err := EnableContentPolicy("missing", &contentpolicy.Policy{}); !errors.Is(err, ErrBackendNotFound) {
+		t.Errorf("Expected ErrBackendNotFound, got %v", err)
+	}
+}
+
+func TestEnableKeyPolicy(t *testing.T) {
+	Reset()
+	if err := EnableKeyPolicy("", &keypolicy.Policy{}); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("Expected ErrNotInitialized, got %v", err)
+	}
+
+	err := Initialize(&FacadeConfig{
+		Backends:       map[string]common.Storage{"mem": memory.New()},
+		DefaultBackend: "mem",
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+
+	if err := EnableKeyPolicy("", nil); !errors.Is(err, keypolicy.ErrInvalidPolicy) {
+		t.Errorf("Expected ErrInvalidPolicy, got %v", err)
+	}
+	if err := EnableKeyPolicy("", &keypolicy.Policy{Rules: []keypolicy.Rule{{Charset: "z-a"}}}); !errors.Is(err, keypolicy.ErrInvalidPolicy) {
+		t.Errorf("Expected ErrInvalidPolicy, got %v", err)
+	}
+	policy := &keypolicy.Policy{
+		Rules: []keypolicy.Rule{{Prefix: "datasets/", Charset: "a-z0-9./", Extensions: []string{"*.csv"}}},
+	}
+	if err := EnableKeyPolicy("mem", policy); err != nil {
+		t.Fatalf("EnableKeyPolicy() error = %v", err)
+	}
+
+	ctx := context.Background()
+	for _, key := range []string{"datasets/Q1.csv", "datasets/q1.json"} {
+		err = PutWithContext(ctx, key, strings.NewReader("a,b"))
+		if !errors.Is(err, keypolicy.ErrKeyRejected) || !errors.Is(err, common.ErrInvalidArgument) {
+			t.Errorf("PutWithContext(%s): expected ErrKeyRejected wrapping ErrInvalidArgument, got %v", key, err)
+		}
+	}
+	if err := Append(ctx, "datasets/q1.json", strings.NewReader("a,b")); !errors.Is(err, keypolicy.ErrKeyRejected) {
+		t.Errorf("Append(): expected ErrKeyRejected, got %v", err)
+	}
+	if err := PutWithContext(ctx, "datasets/q1.csv", strings.NewReader("a,b")); err != nil {
+		t.Errorf("PutWithContext() error = %v", err)
+	}
+
+	// Enabling again replaces the policy instead of stacking wrappers.
+	if err := EnableKeyPolicy("", &keypolicy.Policy{}); err != nil {
+		t.Fatalf("EnableKeyPolicy() second call error = %v", err)
+	}
+	storage, _ := Backend("mem")
+	wrapped, ok := storage.(*keypolicy.Storage)
+	if !ok || len(wrapped.Policy().Rules) != 0 {
+		t.Fatalf("Expected the replacement policy, got %T", storage)
+	}
+	if _, ok := wrapped.Underlying().(*keypolicy.Storage); ok {
+		t.Error("Expected key policy wrappers not to stack")
+	}
+	if err := EnableKeyPolicy("missing", &keypolicy.Policy{}); !errors.Is(err, ErrBackendNotFound) {
+		t.Errorf("Expected ErrBackendNotFound, got %v", err)
+	}
+}
+
+func TestEnableOverlays(t *testing.T) {
+	Reset()
+	if err := EnableOverlays("", &overlay.Config{}); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("Expected ErrNotInitialized, got %v", err)
+	}
+
+	err := Initialize(&FacadeConfig{
+		Backends:       map[string]common.Storage{"mem": memory.New()},
+		DefaultBackend: "mem",
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+
+	if err := EnableOverlays("", nil); !errors.Is(err, overlay.ErrInvalidConfig) {
+		t.Errorf("Expected ErrInvalidConfig, got %v", err)
+	}
+	if _, err := Overlays(""); !errors.Is(err, ErrOverlaysNotEnabled) {
+		t.Errorf("Expected ErrOverlaysNotEnabled, got %v", err)
+	}
+	// Naming a replication policy needs a backend with replication.
+	err = EnableOverlays("", &overlay.Config{Overlays: []overlay.Overlay{{Prefix: "a/", Replication: "dr"}}})
+	if !errors.Is(err, overlay.ErrInvalidConfig) {
+		t.Errorf("Expected ErrInvalidConfig, got %v", err)
+	}
+
+	cfg := &overlay.Config{Overlays: []overlay.Overlay{{Prefix: "logs/", Compression: overlay.CompressionGzip}}}
+	if err := EnableOverlays("", cfg); err != nil {
+		t.Fatalf("EnableOverlays() error = %v", err)
+	}
+	ctx := context.Background()
+	if err := PutWithContext(ctx, "logs/app.log", strings.NewReader("hello")); err != nil {
+		t.Fatalf("PutWithContext() error = %v", err)
+	}
+	metadata, err := GetMetadata(ctx, "logs/app.log")
+	if err != nil || metadata.Custom[overlay.MetaCompression] != overlay.CompressionGzip {
+		t.Errorf("Expected gzip compressed object, got %+v, %v", metadata, err)
+	}
+	rc, err := GetWithContext(ctx, "logs/app.log")
+	if err != nil {
+		t.Fatalf("GetWithContext() error = %v", err)
+	}
+	data, _ := io.ReadAll(rc)
+	_ = rc.Close()
+	if string(data) != "hello" {
+		t.Errorf("Expected hello, got %q", data)
+	}
+
+	// Enabling again replaces the overlays instead of stacking wrappers.
+	if err := EnableOverlays("mem", &overlay.Config{}); err != nil {
+		t.Fatalf("EnableOverlays() second call error = %v", err)
+	}
+	storage, _ := Backend("mem")
+	wrapped, ok := storage.(*overlay.Storage)
+	if !ok || len(wrapped.Config().Overlays) != 0 {
+		t.Fatalf("Expected the replacement overlays, got %T", storage)
+	}
+	if _, ok := wrapped.Underlying().(*overlay.Storage); ok {
+		t.Error("Expected overlay wrappers not to stack")
+	}
+	if overlays, err := Overlays("mem"); err != nil || overlays != wrapped {
+		t.Errorf("Overlays() = %v, %v", overlays, err)
+	}
+}
+
+func TestEnableResidency(t *testing.T) {
+	Reset()
+	cfg := &residency.Config{
+		Rules:   []residency.Rule{{Prefix: "eu/", Regions: []string{"eu-west-1"}}},
+		Regions: map[string]string{"eu": "eu-west-1", "us": "us-east-1"},
+	}
+	if err := EnableResidency(cfg); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("Expected ErrNotInitialized, got %v", err)
+	}
+
+	err := Initialize(&FacadeConfig{
+		Backends:       map[string]common.Storage{"eu": memory.New(), "us": memory.New()},
+		DefaultBackend: "eu",
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+	if err := EnableResidency(nil); !errors.Is(err, residency.ErrInvalidConfig) {
+		t.Errorf("Expected ErrInvalidConfig, got %v", err)
+	}
+	if err := EnableResidency(cfg); err != nil {
+		t.Fatalf("EnableResidency() error = %v", err)
+	}
+
+	ctx := context.Background()
+	if err := PutWithContext(ctx, "eu/customers.csv", strings.NewReader("x")); err != nil {
+		t.Errorf("PutWithContext() to an allowed backend error = %v", err)
+	}
+	err = PutWithContext(ctx, "us:eu/customers.csv", strings.NewReader("x"))
+	if !errors.Is(err, residency.ErrViolation) || !errors.Is(err, common.ErrPermissionDenied) {
+		t.Errorf("Expected ErrViolation wrapping ErrPermissionDenied, got %v", err)
+	}
+	if err := PutWithContext(ctx, "us:public/readme", strings.NewReader("x")); err != nil {
+		t.Errorf("PutWithContext() outside the rules error = %v", err)
+	}
+
+	// Enabling again replaces the configuration instead of stacking wrappers.
+	if err := EnableResidency(cfg); err != nil {
+		t.Fatalf("EnableResidency() second call error = %v", err)
+	}
+	storage, _ := Backend("us")
+	wrapped, ok := storage.(*residency.Storage)
+	if !ok {
+		t.Fatalf("Expected *residency.Storage, got %T", storage)
+	}
+	if _, ok := wrapped.Underlying().(*residency.Storage); ok {
+		t.Error("Expected residency wrappers not to stack")
+	}
+}
+
+func TestEnableCost(t *testing.T) {
+	Reset()
+	cfg := &cost.Config{Groups: []cost.Group{{Name: "analytics", Prefixes: []string{"reports/"}}}}
+	if err := EnableCost(cfg); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("Expected ErrNotInitialized, got %v", err)
+	}
+
+	primary := memory.New()
+	if err := primary.Put("reports/q1.csv", strings.NewReader("12345")); err != nil {
+		t.Fatal(err)
+	}
+	err := Initialize(&FacadeConfig{
+		Backends:       map[string]common.Storage{"primary": primary, "archive": memory.New()},
+		DefaultBackend: "primary",
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+	ctx := context.Background()
+	if _, err := CostReport(ctx, ""); !errors.Is(err, ErrCostNotEnabled) {
+		t.Errorf("Expected ErrCostNotEnabled, got %v", err)
+	}
+	if err := EnableCost(cfg); err != nil {
+		t.Fatalf("EnableCost() error = %v", err)
+	}
+	// Enabling again keeps the existing meter.
+	if err := EnableCost(cfg); err != nil {
+		t.Fatalf("EnableCost() second call error = %v", err)
+	}
+	storage, _ := Backend("archive")
+	if wrapped, ok := storage.(*cost.Storage); !ok {
+		t.Fatalf("Expected *cost.Storage, got %T", storage)
+	} else if _, ok := wrapped.Underlying().(*cost.Storage); ok {
+		t.Error("Expected cost wrappers not to stack")
+	}
+
+	if err := PutWithContext(ctx, "archive:logs/app.log", strings.NewReader("abc")); err != nil {
+		t.Fatal(err)
+	}
+	report, err := CostReport(ctx, "")
+	if err != nil {
+		t.Fatalf("CostReport() error = %v", err)
+	}
+	stored := make(map[string]int64)
+	for _, line := range report.Lines {
+		stored[line.Backend+"/"+line.Group] = line.StoredBytes
+	}
+	if stored["primary/analytics"] != 5 || stored["archive/logs/"] != 3 {
+		t.Errorf("stored bytes = %v", stored)
+	}
+}
+
+func TestEnableStats(t *testing.T) {
+	Reset()
+	if err := EnableStats(nil); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("Expected ErrNotInitialized, got %v", err)
+	}
+
+	primary := memory.New()
+	if err := primary.Put("logs/a.log", strings.NewReader("12345")); err != nil {
+		t.Fatal(err)
+	}
+	err := Initialize(&FacadeConfig{
+		Backends:       map[string]common.Storage{"primary": primary, "archive": memory.New()},
+		DefaultBackend: "primary",
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+	defer Reset()
+	if _, err := StatsCollector(); !errors.Is(err, ErrStatsNotEnabled) {
+		t.Errorf("Expected ErrStatsNotEnabled, got %v", err)
+	}
+	if err := EnableStats(&stats.Config{Interval: time.Hour}); err != nil {
+		t.Fatalf("EnableStats() error = %v", err)
+	}
+	collector, err := StatsCollector()
+	if err != nil {
+		t.Fatalf("StatsCollector() error = %v", err)
+	}
+
+	// The first snapshot is taken in the background.
+	deadline := time.Now().Add(5 * time.Second)
+	for len(collector.Stats("", time.Time{})) < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	got := collector.Stats("primary", time.Time{})
+	if len(got) != 1 || got[0].Latest.Objects != 1 || got[0].Latest.Bytes != 5 {
+		t.Errorf("Stats(primary) = %+v", got)
+	}
+}
+
+func TestEnableRetention(t *testing.T) {
+	Reset()
+	if err := EnableRetention("", nil); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("Expected ErrNotInitialized, got %v", err)
+	}
+
+	err := Initialize(&FacadeConfig{
+		Backends: map[string]common.Storage{
+			"local": memory.New(),
+		},
+		DefaultBackend: "local",
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+	if _, err := Retention(""); !errors.Is(err, ErrRetentionNotEnabled) {
+		t.Errorf("Expected ErrRetentionNotEnabled, got %v", err)
+	}
+
+	if err := EnableRetention("local", &RetentionConfig{ProtectedPrefixes: []string{"records/"}}); err != nil {
+		t.Fatalf("EnableRetention() error = %v", err)
+	}
+	if err := EnableRetention("", &RetentionConfig{}); err != nil {
+		t.Fatalf("EnableRetention() second call error = %v", err)
+	}
+	manager, err := Retention("local")
+	if err != nil {
+		t.Fatalf("Retention() error = %v", err)
+	}
+	if got := manager.ProtectedPrefixes(); len(got) != 1 || got[0] != "records/" {
+		t.Errorf("Expected the first configuration to be kept, got %v", got)
+	}
+
+	alice := context.WithValue(context.Background(), adapters.PrincipalContextKey{}, &adapters.Principal{ID: "alice"})
+	bob := context.WithValue(context.Background(), adapters.PrincipalContextKey{}, &adapters.Principal{ID: "bob"})
+	if err := PutWithContext(alice, "records/a.csv", strings.NewReader("a")); err != nil {
+		t.Fatalf("PutWithContext() error = %v", err)
+	}
+	var pending *retention.PendingError
+	if err := DeleteWithContext(alice, "records/a.csv"); !errors.As(err, &pending) {
+		t.Fatalf("Expected *retention.PendingError, got %v", err)
+	}
+	if _, err := ApproveDeletion(alice, "", pending.Request.ID); !errors.Is(err, retention.ErrSelfApproval) {
+		t.Errorf("Expected ErrSelfApproval, got %v", err)
+	}
+	if _, err := ApproveDeletion(bob, "local", pending.Request.ID); err != nil {
+		t.Fatalf("ApproveDeletion() error = %v", err)
+	}
+	if ok, _ := Exists(bob, "records/a.csv"); ok {
+		t.Error("Expected the approved object to be deleted")
+	}
+	if err := EnableRetention("missing", nil); !errors.Is(err, ErrBackendNotFound) {
+		t.Errorf("Expected ErrBackendNotFound, got %v", err)
+	}
+}
+
+func TestScrub(t *testing.T) {
+	Reset()
+	ctx := context.Background()
+	if _, err := Scrub(ctx, "", nil); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("Expected ErrNotInitialized, got %v", err)
+	}
+
+	backend, replica := memory.New(), memory.New()
+	if err := Initialize(&FacadeConfig{
+		Backends:       map[string]common.Storage{"primary": backend, "replica": replica},
+		DefaultBackend: "primary",
+	}); err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+	defer Reset()
+	if _, err := Scrub(ctx, "", nil); !errors.Is(err, ErrChecksumsNotEnabled) {
+		t.Errorf("Expected ErrChecksumsNotEnabled, got %v", err)
+	}
+	if err := EnableChecksums(""); err != nil {
+		t.Fatalf("EnableChecksums() error = %v", err)
+	}
+
+	if err := PutWithContext(ctx, "a.txt", strings.NewReader("hello")); err != nil {
+		t.Fatalf("PutWithContext() error = %v", err)
+	}
+	if err := replica.PutWithContext(ctx, "a.txt", strings.NewReader("hello")); err != nil {
+		t.Fatalf("PutWithContext() error = %v", err)
+	}
+	metadata, err := backend.GetMetadata(ctx, "a.txt")
+	if err != nil {
+		t.Fatalf("GetMetadata() error = %v", err)
+	}
+	if err := backend.PutWithMetadata(ctx, "a.txt", strings.NewReader("hellp"), metadata); err != nil {
+		t.Fatalf("PutWithMetadata() error = %v", err)
+	}
+
+	var reported []scrub.Corruption
+	result, err := Scrub(ctx, "", &ScrubConfig{
+		Replicas:     []string{"replica"},
+		OnCorruption: func(c scrub.Corruption) { reported = append(reported, c) },
+	})
+	if err != nil {
+		t.Fatalf("Scrub() error = %v", err)
+	}
+	if result.Corrupted != 1 || result.Repaired != 1 || len(reported) != 1 || reported[0].Replica != "replica" {
+		t.Errorf("Scrub() = %+v, reported %+v", result, reported)
+	}
+	rc, err := GetWithContext(ctx, "a.txt")
+	if err != nil {
+		t.Fatalf("GetWithContext() error = %v", err)
+	}
+	defer func() { _ = rc.Close() }()
+	if data, _ := io.ReadAll(rc); string(data) != "hello" {
+		t.Errorf("Repaired content = %q", data)
+	}
+
+	if _, err := Scrub(ctx, "", &ScrubConfig{Replicas: []string{"missing"}}); !errors.Is(err, ErrBackendNotFound) {
+		t.Errorf("Expected ErrBackendNotFound for an unknown replica, got %v", err)
+	}
+}
+
+func TestEnableTombstones(t *testing.T) {
+	Reset()
+	if err := EnableTombstones("", nil); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("Expected ErrNotInitialized, got %v", err)
+	}
+
+	backend := memory.New()
+	if err := Initialize(&FacadeConfig{
+		Backends:       map[string]common.Storage{"local": backend},
+		DefaultBackend: "local",
+	}); err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+	defer Reset()
+	ctx := context.Background()
+	if _, err := PurgeTombstones(ctx, ""); !errors.Is(err, ErrTombstonesNotEnabled) {
+		t.Errorf("Expected ErrTombstonesNotEnabled, got %v", err)
+	}
+
+	// A negligible grace period makes the delete purgeable at once.
+	if err := EnableTombstones("", &tombstone.Config{Grace: time.Nanosecond}); err != nil {
+		t.Fatalf("EnableTombstones() error = %v", err)
+	}
+	if err := PutWithContext(ctx, "a.txt", strings.NewReader("a")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if err := DeleteWithContext(ctx, "a.txt"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if exists, _ := Exists(ctx, "a.txt"); exists {
+		t.Error("Expected the deleted object to be hidden")
+	}
+	if exists, _ := backend.Exists(ctx, "a.txt"); !exists {
+		t.Error("Expected the deleted object to be kept until purged")
+	}
+	if keys, _ := ListWithContext(ctx, ""); len(keys) != 0 {
+		t.Errorf("Expected deleted objects and tombstones to be hidden, got %v", keys)
+	}
+
+	time.Sleep(time.Millisecond)
+	if n, err := PurgeTombstones(ctx, "local"); n != 1 || err != nil {
+		t.Errorf("PurgeTombstones() = %d, %v", n, err)
+	}
+	if exists, _ := backend.Exists(ctx, "a.txt"); exists {
+		t.Error("Expected the object to be removed by the purge")
+	}
+	manager, err := Tombstones("")
+	if err != nil {
+		t.Fatalf("Tombstones() error = %v", err)
+	}
+	if list, _ := manager.List(ctx); len(list) != 1 || !list[0].Purged {
+		t.Errorf("Expected the tombstone to be kept after the purge, got %+v", list)
+	}
+}
+
+func TestEnableLocks(t *testing.T) {
+	Reset()
+	if err := EnableLocks("", ""); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("Expected ErrNotInitialized, got %v", err)
+	}
+
+	err := Initialize(&FacadeConfig{
+		Backends: map[string]common.Storage{
+			"local": memory.New(),
+			"mock":  newMockStorage("mock"),
+		},
+		DefaultBackend: "local",
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+	ctx := context.Background()
+	if _, err := TryLock(ctx, "jobs/a", "w", time.Minute); !errors.Is(err, ErrLocksNotEnabled) {
+		t.Errorf("Expected ErrLocksNotEnabled, got %v", err)
+	}
+	if err := EnableLocks("mock", ""); !errors.Is(err, locks.ErrNotSupported) {
+		t.Errorf("Expected ErrNotSupported, got %v", err)
+	}
+
+	if err := EnableLocks("local", ""); err != nil {
+		t.Fatalf("EnableLocks() error = %v", err)
+	}
+	if err := EnableLocks("", "other/"); err != nil {
+		t.Fatalf("EnableLocks() second call error = %v", err)
+	}
+	manager, err := Locks("local")
+	if err != nil {
+		t.Fatalf("Locks() error = %v", err)
+	}
+	if manager.Prefix() != locks.DefaultPrefix {
+		t.Errorf("Expected the first configuration to be kept, got %q", manager.Prefix())
+	}
+
+	lock, err := TryLock(ctx, "local:jobs/a", "worker-1", time.Minute)
+	if err != nil {
+		t.Fatalf("TryLock() error = %v", err)
+	}
+	if _, err := TryLock(ctx, "jobs/a", "worker-2", time.Minute); !errors.Is(err, locks.ErrLocked) {
+		t.Errorf("Expected ErrLocked, got %v", err)
+	}
+	if _, err := RefreshLock(ctx, "jobs/a", lock.Token, time.Minute); err != nil {
+		t.Errorf("RefreshLock() error = %v", err)
+	}
+	if _, err := GetWithContext(ctx, locks.DefaultPrefix+"jobs/a"); !errors.Is(err, common.ErrReservedKey) {
+		t.Errorf("Expected ErrReservedKey, got %v", err)
+	}
+	if keys, _ := ListWithContext(ctx, ""); len(keys) != 0 {
+		t.Errorf("Expected lock objects to be hidden, got %v", keys)
+	}
+	if err := Unlock(ctx, "jobs/a", lock.Token); err != nil {
+		t.Fatalf("Unlock() error = %v", err)
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	if _, err := Lock(waitCtx, "jobs/a", "worker-2", time.Minute); err != nil {
+		t.Errorf("Lock() error = %v", err)
+	}
+}
+
+func TestEnableShares(t *testing.T) {
+	Reset()
+	if err := EnableShares("", ""); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("Expected ErrNotInitialized, got %v", err)
+	}
+
+	err := Initialize(&FacadeConfig{
+		Backends: map[string]common.Storage{
+			"local": memory.New(),
+			"mock":  newMockStorage("mock"),
+		},
+		DefaultBackend: "local",
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+	ctx := context.Background()
+	if _, _, err := ShareObject(ctx, "report.pdf", share.Options{}); !errors.Is(err, ErrSharesNotEnabled) {
+		t.Errorf("Expected ErrSharesNotEnabled, got %v", err)
+	}
+	if err := EnableShares("mock", ""); !errors.Is(err, share.ErrNotSupported) {
+		t.Errorf("Expected ErrNotSupported, got %v", err)
+	}
+	if err := EnableShares("local", ""); err != nil {
+		t.Fatalf("EnableShares() error = %v", err)
+	}
+	if err := EnableShares("", "other/"); err != nil {
+		t.Fatalf("EnableShares() second call error = %v", err)
+	}
+	manager, err := Shares("")
+	if err != nil {
+		t.Fatalf("Shares() error = %v", err)
+	}
+	if manager.Prefix() != share.DefaultPrefix {
+		t.Errorf("Expected the first configuration to be kept, got %q", manager.Prefix())
+	}
+
+	if _, _, err := ShareObject(ctx, "report.pdf", share.Options{}); !errors.Is(err, common.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a missing object, got %v", err)
+	}
+	if err := PutWithContext(ctx, "report.pdf", strings.NewReader("x")); err != nil {
+		t.Fatal(err)
+	}
+	link, token, err := ShareObject(ctx, "local:report.pdf", share.Options{MaxDownloads: 1})
+	if err != nil {
+		t.Fatalf("ShareObject() error = %v", err)
+	}
+	if link.Key != "report.pdf" || token == "" {
+		t.Errorf("Unexpected link %+v", link)
+	}
+	if _, err := manager.Open(ctx, token, ""); err != nil {
+		t.Errorf("Open() error = %v", err)
+	}
+	if keys, _ := ListWithContext(ctx, ""); len(keys) != 1 {
+		t.Errorf("Expected link objects to be hidden, got %v", keys)
+	}
+	if _, err := GetWithContext(ctx, share.DefaultPrefix+link.ID); !errors.Is(err, common.ErrReservedKey) {
+		t.Errorf("Expected ErrReservedKey, got %v", err)
+	}
+
+	sealed := &common.Metadata{Custom: map[string]string{common.MetaSSECDataKey: "wrapped"}}
+	if err := PutWithMetadata(ctx, "secret.bin", strings.NewReader("x"), sealed); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ShareObject(ctx, "secret.bin", share.Options{}); !errors.Is(err, common.ErrInvalidArgument) {
+		t.Errorf("Expected ErrInvalidArgument for an SSE-C object, got %v", err)
+	}
+}
+
+func TestEnableUploads(t *testing.T) {
+	Reset()
+	if err := EnableUploads("", ""); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("Expected ErrNotInitialized, got %v", err)
+	}
+
+	err := Initialize(&FacadeConfig{
+		Backends:       map[string]common.Storage{"local": memory.New()},
+		DefaultBackend: "local",
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+	ctx := context.Background()
+	if _, err := CompleteUpload(ctx, "", "0123456789abcdef0123456789abcdef", ""); !errors.Is(err, ErrUploadsNotEnabled) {
+		t.Errorf("Expected ErrUploadsNotEnabled, got %v", err)
+	}
+	if err := EnableChecksums("local"); err != nil {
+		t.Fatal(err)
+	}
+	if err := EnableUploads("local", ""); err != nil {
+		t.Fatalf("EnableUploads() error = %v", err)
+	}
+	if err := EnableUploads("", "other/"); err != nil {
+		t.Fatalf("EnableUploads() second call error = %v", err)
+	}
+	manager, err := Uploads("")
+	if err != nil {
+		t.Fatalf("Uploads() error = %v", err)
+	}
+	if manager.Prefix() != upload.DefaultPrefix {
+		t.Errorf("Expected the first configuration to be kept, got %q", manager.Prefix())
+	}
+
+	session, err := manager.Create(ctx, "videos/raw.bin", upload.Options{
+		ContentType: "video/mp4",
+		Metadata:    map[string]string{"camera": "a"},
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	for i, part := range []string{"hello ", "world"} {
+		digest := sha256.Sum256([]byte(part))
+		if _, err := manager.PutChunk(ctx, session.ID, i+1, strings.NewReader(part), hex.EncodeToString(digest[:])); err != nil {
+			t.Fatalf("PutChunk() error = %v", err)
+		}
+	}
+	if keys, _ := ListWithContext(ctx, ""); len(keys) != 0 {
+		t.Errorf("Expected session objects to be hidden, got %v", keys)
+	}
+	if _, err := GetWithContext(ctx, upload.DefaultPrefix+session.ID+"/session.json"); !errors.Is(err, common.ErrReservedKey) {
+		t.Errorf("Expected ErrReservedKey, got %v", err)
+	}
+
+	if _, err := CompleteUpload(ctx, "local", session.ID, strings.Repeat("0", 64)); !errors.Is(err, upload.ErrChecksumMismatch) {
+		t.Errorf("Expected ErrChecksumMismatch, got %v", err)
+	}
+	whole := sha256.Sum256([]byte("hello world"))
+	completed, err := CompleteUpload(ctx, "local", session.ID, hex.EncodeToString(whole[:]))
+	if err != nil {
+		t.Fatalf("CompleteUpload() error = %v", err)
+	}
+	if completed.Key != "videos/raw.bin" {
+		t.Errorf("Unexpected session %+v", completed)
+	}
+	reader, err := GetWithContext(ctx, "videos/raw.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(reader)
+	_ = reader.Close()
+	if string(data) != "hello world" {
+		t.Errorf("Expected the assembled object, got %q", data)
+	}
+	metadata, err := GetMetadata(ctx, "videos/raw.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metadata.ContentType != "video/mp4" || common.CustomField(metadata.Custom, "camera") != "a" {
+		t.Errorf("Expected the session metadata on the object, got %+v", metadata)
+	}
+	if common.CustomField(metadata.Custom, common.MetaChecksumSHA256) != hex.EncodeToString(whole[:]) {
+		t.Errorf("Expected the checksum wrapper to see the completed object, got %+v", metadata.Custom)
+	}
+	if _, err := manager.Get(ctx, session.ID); !errors.Is(err, upload.ErrSessionNotFound) {
+		t.Errorf("Expected the session to be removed, got %v", err)
+	}
+}
+
+func TestReapUploads(t *testing.T) {
+	Reset()
+	defer Reset()
+	if _, err := ReapUploads(context.Background(), "", nil); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("Expected ErrNotInitialized, got %v", err)
+	}
+
+	backend := memory.New()
+	if err := Initialize(&FacadeConfig{
+		Backends:       map[string]common.Storage{"local": backend},
+		DefaultBackend: "local",
+	}); err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+	ctx := context.Background()
+	if err := backend.Put(common.StagingPrefix+"0123456789abcdef0123456789abcdef", strings.NewReader("staged")); err != nil {
+		t.Fatal(err)
+	}
+	if err := EnableUploads("", ""); err != nil {
+		t.Fatal(err)
+	}
+	manager, err := Uploads("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := manager.Create(ctx, "videos/raw.bin", upload.Options{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Both uploads just started.
+	result, err := ReapUploads(ctx, "", nil)
+	if err != nil {
+		t.Fatalf("ReapUploads() error = %v", err)
+	}
+	if result.InProgress != 2 || result.Aborted != 0 {
+		t.Errorf("ReapUploads() = %+v, want 2 uploads in progress and none aborted", result)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	result, err = ReapUploads(ctx, "local", &ReapConfig{MaxAge: time.Millisecond})
+	if err != nil {
+		t.Fatalf("ReapUploads() error = %v", err)
+	}
+	if result.Aborted != 2 || result.Reclaimed != int64(len("staged")) {
+		t.Errorf("ReapUploads() = %+v, want both uploads aborted", result)
+	}
+	if ok, _ := backend.Exists(ctx, common.StagingPrefix+"0123456789abcdef0123456789abcdef"); ok {
+		t.Error("the staged object was not removed")
+	}
+
+	if _, err := ReapUploads(ctx, "missing", nil); err == nil {
+		t.Error("Expected an error for an unknown backend")
+	}
+}
+
+func TestApplyPolicies(t *testing.T) {
+	Reset()
+	backend := memory.New()
+	for _, key := range []string{"logs/a.log", "logs/b.log", "data/c.bin"} {
+		if err := backend.Put(key, strings.NewReader("x")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := Initialize(&FacadeConfig{
+		Backends:       map[string]common.Storage{"default": backend},
+		DefaultBackend: "default",
+	}); err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+	defer Reset()
+
+	ctx := context.Background()
+	if n, err := ApplyPolicies(ctx, ""); err != nil || n != 0 {
+		t.Errorf("ApplyPolicies() without policies = %d, %v", n, err)
+	}
+	if err := AddPolicy("", common.LifecyclePolicy{ID: "expire-logs", Prefix: "logs/", Retention: time.Nanosecond, Action: "delete"}); err != nil {
+		t.Fatalf("AddPolicy() error = %v", err)
+	}
+	time.Sleep(time.Millisecond)
+
+	n, err := ApplyPolicies(ctx, "default")
+	if err != nil || n != 2 {
+		t.Errorf("ApplyPolicies() = %d, %v, want 2", n, err)
+	}
+	if keys, _ := ListWithContext(ctx, ""); len(keys) != 1 || keys[0] != "data/c.bin" {
+		t.Errorf("Expected only data/c.bin to remain, got %v", keys)
+	}
+	if _, err := ApplyPolicies(ctx, "missing"); !errors.Is(err, ErrBackendNotFound) {
+		t.Errorf("Expected ErrBackendNotFound, got %v", err)
+	}
+}
+
+func TestApplyPolicies_Expiry(t *testing.T) {
+	Reset()
+	backend := memory.New()
+	ctx := context.Background()
+	expires := func(at time.Time) *common.Metadata {
+		return &common.Metadata{Custom: map[string]string{common.MetaExpiresAt: at.Format(time.RFC3339)}}
+	}
+	if err := backend.PutWithMetadata(ctx, "logs/expired.log", strings.NewReader("x"), expires(time.Now().Add(-time.Minute))); err != nil {
+		t.Fatal(err)
+	}
+	if err := backend.PutWithMetadata(ctx, "logs/pending.log", strings.NewReader("x"), expires(time.Now().Add(time.Hour))); err != nil {
+		t.Fatal(err)
+	}
+	if err := backend.Put("logs/plain.log", strings.NewReader("x")); err != nil {
+		t.Fatal(err)
+	}
+	if err := Initialize(&FacadeConfig{
+		Backends:       map[string]common.Storage{"default": backend},
+		DefaultBackend: "default",
+	}); err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+	defer Reset()
+
+	// Expired objects are deleted without any policy.
+	if n, err := ApplyPolicies(ctx, ""); err != nil || n != 1 {
+		t.Errorf("ApplyPolicies() = %d, %v, want 1", n, err)
+	}
+	if exists, _ := Exists(ctx, "logs/expired.log"); exists {
+		t.Error("Expected logs/expired.log to be deleted")
+	}
+
+	// A future expiry does not protect an object from its prefix's policies.
+	if err := AddPolicy("", common.LifecyclePolicy{ID: "cool", Prefix: "logs/", Retention: time.Nanosecond, Action: "delete"}); err != nil {
+		t.Fatalf("AddPolicy() error = %v", err)
+	}
+	time.Sleep(time.Millisecond)
+	if n, err := ApplyPolicies(ctx, ""); err != nil || n != 2 {
+		t.Errorf("ApplyPolicies() with a policy = %d, %v, want 2", n, err)
+	}
+
+	if err := backend.PutWithMetadata(ctx, "tmp/expired", strings.NewReader("x"), expires(time.Now().Add(-time.Minute))); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := DeleteExpired(ctx, "default"); err != nil || n != 1 {
+		t.Errorf("DeleteExpired() = %d, %v, want 1", n, err)
+	}
+
+	err := PutWithMetadata(ctx, "tmp/bad", strings.NewReader("x"), &common.Metadata{Custom: map[string]string{common.MetaExpiresAt: "tomorrow"}})
+	if !errors.Is(err, common.ErrInvalidArgument) {
+		t.Errorf("PutWithMetadata() with an invalid expiry = %v, want ErrInvalidArgument", err)
+	}
+}
+
+func TestEnableJobs(t *testing.T) {
+	Reset()
+	if _, err := EnableJobs(nil); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("Expected ErrNotInitialized, got %v", err)
+	}
+	if err := Initialize(&FacadeConfig{
+		Backends:       map[string]common.Storage{"default": memory.New()},
+		DefaultBackend: "default",
+	}); err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+	defer Reset()
+	if _, err := Jobs(); !errors.Is(err, ErrJobsNotEnabled) {
+		t.Errorf("Expected ErrJobsNotEnabled, got %v", err)
+	}
+
+	scheduler, err := EnableJobs(&jobs.Config{Tick: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("EnableJobs() error = %v", err)
+	}
+	if again, err := EnableJobs(nil); err != nil || again != scheduler {
+		t.Errorf("Expected the running scheduler, got %v, %v", again, err)
+	}
+	ran := make(chan struct{}, 1)
+	if err := scheduler.Register(jobs.Job{Name: "gc", Interval: time.Hour, Run: func(context.Context) (string, error) {
+		ran <- struct{}{}
+		return "", nil
+	}}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	select {
+	case <-ran:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the job to run")
+	}
+	if found, err := Jobs(); err != nil || found != scheduler {
+		t.Errorf("Jobs() = %v, %v", found, err)
+	}
+}
+
+func TestEnableTasks(t *testing.T) {
+	Reset()
+	if _, err := EnableTasks(nil); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("Expected ErrNotInitialized, got %v", err)
+	}
+	if err := Initialize(&FacadeConfig{
+		Backends:       map[string]common.Storage{"default": memory.New()},
+		DefaultBackend: "default",
+	}); err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+	defer Reset()
+	if _, err := Tasks(); !errors.Is(err, ErrTasksNotEnabled) {
+		t.Errorf("Expected ErrTasksNotEnabled, got %v", err)
+	}
+
+	queue, err := EnableTasks(&tasks.Config{Poll: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("EnableTasks() error = %v", err)
+	}
+	if again, err := EnableTasks(nil); err != nil || again != queue {
+		t.Errorf("Expected the running queue, got %v, %v", again, err)
+	}
+	if found, err := Tasks(); err != nil || found != queue {
+		t.Errorf("Tasks() = %v, %v", found, err)
+	}
+
+	ctx := context.Background()
+	if err := PutWithContext(ctx, "reports/q1.csv", strings.NewReader("a,b")); err != nil {
+		t.Fatal(err)
+	}
+	archiveDir := t.TempDir()
+	archived, err := queue.Enqueue(ctx, TaskArchive, ArchiveTask{
+		KeyRef:              "reports/q1.csv",
+		DestinationType:     "local",
+		DestinationSettings: map[string]string{"path": archiveDir},
+	})
+	if err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	missing, _ := queue.Enqueue(ctx, TaskArchive, ArchiveTask{KeyRef: "missing.csv", DestinationType: "local",
+		DestinationSettings: map[string]string{"path": archiveDir}})
+
+	waitForState := func(id string, want tasks.State) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			task, err := queue.Get(ctx, id)
+			if err == nil && task.State == want {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("task %s = %+v, %v; want %s", id, task, err, want)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	waitForState(archived.ID, tasks.StateSucceeded)
+	if _, err := os.Stat(filepath.Join(archiveDir, "reports", "q1.csv")); err != nil {
+		t.Errorf("Expected the archived object: %v", err)
+	}
+	// A missing object cannot be archived by retrying.
+	waitForState(missing.ID, tasks.StateDead)
+}
+
+// sharedDisk stands in for a backend whose conditional writes are atomic
+// across processes.
+type sharedDisk struct {
+	*local.Local
+}
+
+func (sharedDisk) ProcessLocal() bool { return false }
+
+func TestEnableHA(t *testing.T) {
+	Reset()
+	if _, err := EnableHA("", ha.Config{}); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("Expected ErrNotInitialized, got %v", err)
+	}
+
+	disk, err := factory.NewStorage("local", map[string]string{"path": t.TempDir()})
+	if err != nil {
+		t.Fatalf("failed to create local storage: %v", err)
+	}
+	unshared, err := factory.NewStorage("local", map[string]string{"path": t.TempDir()})
+	if err != nil {
+		t.Fatalf("failed to create local storage: %v", err)
+	}
+	shared := sharedDisk{Local: disk.(*local.Local)}
+	err = Initialize(&FacadeConfig{
+		Backends: map[string]common.Storage{
+			"shared": shared,
+			"local":  unshared,
+			"memory": memory.New(),
+			"mock":   newMockStorage("mock"),
+		},
+		DefaultBackend: "shared",
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+	defer Reset()
+
+	if _, err := HA(""); !errors.Is(err, ErrHANotEnabled) {
+		t.Errorf("Expected ErrHANotEnabled, got %v", err)
+	}
+	if _, err := EnableHA("mock", ha.Config{}); !errors.Is(err, ha.ErrNotSupported) {
+		t.Errorf("Expected ErrNotSupported, got %v", err)
+	}
+	// Local and memory conditional writes only serialize one process.
+	for _, name := range []string{"local", "memory"} {
+		if _, err := EnableHA(name, ha.Config{}); !errors.Is(err, ha.ErrProcessLocal) {
+			t.Errorf("EnableHA(%q) error = %v, want ErrProcessLocal", name, err)
+		}
+	}
+
+	cluster, err := EnableHA("", ha.Config{NodeID: "node-1"})
+	if err != nil {
+		t.Fatalf("EnableHA() error = %v", err)
+	}
+	if again, err := EnableHA("shared", ha.Config{NodeID: "node-2"}); err != nil || again != cluster {
+		t.Errorf("Expected the first cluster to be kept, got %v, %v", again, err)
+	}
+	if found, err := HA("shared"); err != nil || found != cluster {
+		t.Errorf("HA() = %v, %v", found, err)
+	}
+
+	// Replication finds the backend beneath the HA wrapper and keeps its
+	// policies under the coordination prefix.
+	elector := cluster.Elector("replication")
+	if err := EnableReplication("", &ReplicationConfig{
+		FileSystem: cluster.FileSystem(),
+		Shared:     true,
+		IsLeader:   elector.IsLeader,
+	}); err != nil {
+		t.Fatalf("EnableReplication() error = %v", err)
+	}
+	manager, err := GetReplicationManager("")
+	if err != nil {
+		t.Fatalf("GetReplicationManager() error = %v", err)
+	}
+	if err := manager.AddPolicy(common.ReplicationPolicy{ID: "p1", SourceBackend: "shared", DestinationBackend: "shared"}); err != nil {
+		t.Fatalf("AddPolicy() error = %v", err)
+	}
+
+	ctx := context.Background()
+	if exists, _ := shared.Exists(ctx, ha.DefaultPrefix+"state/.replication-policies.json"); !exists {
+		t.Error("Expected the policy file to be stored on the backend")
+	}
+	if _, err := GetWithContext(ctx, ha.DefaultPrefix+"state/.replication-policies.json"); !errors.Is(err, common.ErrReservedKey) {
+		t.Errorf("Expected ErrReservedKey, got %v", err)
+	}
+	if keys, _ := ListWithContext(ctx, ""); len(keys) != 0 {
+		t.Errorf("Expected coordination objects to be hidden, got %v", keys)
+	}
+}
+
+func TestEnableManifests(t *testing.T) {
+	Reset()
+	if err := EnableManifests("", ""); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("Expected ErrNotInitialized, got %v", err)
+	}
+
+	err := Initialize(&FacadeConfig{
+		Backends:       map[string]common.Storage{"local": memory.New()},
+		DefaultBackend: "local",
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+	if _, err := Manifests(""); !errors.Is(err, ErrManifestsNotEnabled) {
+		t.Errorf("Expected ErrManifestsNotEnabled, got %v", err)
+	}
+	if err := EnableManifests("local", ""); err != nil {
+		t.Fatalf("EnableManifests() error = %v", err)
+	}
+	if err := EnableManifests("", "other/"); err != nil {
+		t.Fatalf("EnableManifests() second call error = %v", err)
+	}
+	manager, err := Manifests("local")
+	if err != nil {
+		t.Fatalf("Manifests() error = %v", err)
+	}
+	if manager.Prefix() != manifest.DefaultPrefix {
+		t.Errorf("Expected the first configuration to be kept, got %q", manager.Prefix())
+	}
+
+	ctx := context.Background()
+	if err := PutWithContext(ctx, "data/a", strings.NewReader("a")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if _, err := manager.Create(ctx, "ds", "data/a"); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := manager.Publish(ctx, "ds"); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if err := DeleteWithContext(ctx, manifest.DefaultPrefix+"ds/current.json"); !errors.Is(err, common.ErrReservedKey) {
+		t.Errorf("Expected ErrReservedKey, got %v", err)
+	}
+}
+
+func TestEnableAliases(t *testing.T) {
+	Reset()
+	if err := EnableAliases("", ""); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("Expected ErrNotInitialized, got %v", err)
+	}
+
+	err := Initialize(&FacadeConfig{
+		Backends:       map[string]common.Storage{"local": memory.New()},
+		DefaultBackend: "local",
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+	ctx := context.Background()
+	if _, err := SetAlias(ctx, "latest", "data/a"); !errors.Is(err, ErrAliasesNotEnabled) {
+		t.Errorf("Expected ErrAliasesNotEnabled, got %v", err)
+	}
+	if err := EnableAliases("local", ""); err != nil {
+		t.Fatalf("EnableAliases() error = %v", err)
+	}
+	if err := EnableAliases("", "other/"); err != nil {
+		t.Fatalf("EnableAliases() second call error = %v", err)
+	}
+	manager, err := Aliases("local")
+	if err != nil {
+		t.Fatalf("Aliases() error = %v", err)
+	}
+	if manager.Prefix() != alias.DefaultPrefix {
+		t.Errorf("Expected the first configuration to be kept, got %q", manager.Prefix())
+	}
+
+	if err := PutWithContext(ctx, "data/a", strings.NewReader("a")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if _, err := SetAlias(ctx, "local:latest", "data/a"); err != nil {
+		t.Fatalf("SetAlias() error = %v", err)
+	}
+	resolved, err := ResolveAlias(ctx, "latest")
+	if err != nil || resolved.Target != "data/a" {
+		t.Fatalf("ResolveAlias() = %+v, %v", resolved, err)
+	}
+	rc, err := GetWithContext(ctx, "latest")
+	if err != nil {
+		t.Fatalf("Get() through alias error = %v", err)
+	}
+	data, _ := io.ReadAll(rc)
+	_ = rc.Close()
+	if string(data) != "a" {
+		t.Errorf("Get() through alias = %q, want a", data)
+	}
+	if err := DeleteWithContext(ctx, alias.DefaultPrefix+"latest"); !errors.Is(err, common.ErrReservedKey) {
+		t.Errorf("Expected ErrReservedKey, got %v", err)
+	}
+}
+
+// recordingSink collects the notifications published to it.
+type recordingSink struct {
+	mu      sync.Mutex
+	records []events.Record
+}
+
+func (s *recordingSink) Publish(_ context.Context, n *events.Notification) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, n.Records...)
+	return nil
+}
+
+func (s *recordingSink) Close() error { return nil }
+
+func TestEnableNotifications(t *testing.T) {
+	Reset()
+	if err := EnableNotifications("", &events.Config{}); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("Expected ErrNotInitialized, got %v", err)
+	}
+
+	err := Initialize(&FacadeConfig{
+		Backends:       map[string]common.Storage{"local": memory.New()},
+		DefaultBackend: "local",
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+	if _, err := Notifications(""); !errors.Is(err, ErrNotificationsNotEnabled) {
+		t.Errorf("Expected ErrNotificationsNotEnabled, got %v", err)
+	}
+
+	sink := &recordingSink{}
+	events.RegisterSink("facade-test", func(map[string]string) (events.Sink, error) { return sink, nil })
+	cfg := &events.Config{Rules: []events.Rule{{ID: "all", Sink: events.SinkConfig{Type: "facade-test"}}}}
+	if err := EnableNotifications("local", cfg); err != nil {
+		t.Fatalf("EnableNotifications() error = %v", err)
+	}
+	if err := EnableNotifications("", cfg); err != nil {
+		t.Fatalf("EnableNotifications() second call error = %v", err)
+	}
+
+	ctx := context.Background()
+	if err := PutWithContext(ctx, "a.txt", strings.NewReader("a")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if err := DeleteWithContext(ctx, "a.txt"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	notifier, err := Notifications("local")
+	if err != nil {
+		t.Fatalf("Notifications() error = %v", err)
+	}
+	if err := notifier.Close(ctx); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if len(sink.records) != 2 {
+		t.Fatalf("Expected 2 notifications, got %d", len(sink.records))
+	}
+	if sink.records[0].S3.Bucket.Name != "local" || sink.records[1].EventName != events.EventObjectRemovedDelete {
+		t.Errorf("Unexpected notifications %+v", sink.records)
+	}
+}
+
+// undeletableStorage refuses every delete.
+type undeletableStorage struct {
+	common.Storage
+	manager common.ReplicationManager
+}
+
+func (undeletableStorage) DeleteWithContext(context.Context, string) error {
+	return errors.New("delete refused")
+}
+
+func (s undeletableStorage) GetReplicationManager() (common.ReplicationManager, error) {
+	return s.manager, nil
+}
+
+// policiesReplicationManager is a replication manager with fixed policies.
+type policiesReplicationManager struct {
+	mockReplicationManager
+	policies []common.ReplicationPolicy
+}
+
+func (m *policiesReplicationManager) GetPolicies() ([]common.ReplicationPolicy, error) {
+	return m.policies, nil
+}
+
+func TestOperationalAlerts(t *testing.T) {
+	Reset()
+	manager := &policiesReplicationManager{policies: []common.ReplicationPolicy{
+		{ID: "fresh", Enabled: true, LastSyncTime: time.Now()},
+		{ID: "stale", Enabled: true, LastSyncTime: time.Now().Add(-2 * time.Hour)},
+		{ID: "never", Enabled: true},
+		{ID: "disabled", LastSyncTime: time.Now().Add(-2 * time.Hour)},
+	}}
+	backend := undeletableStorage{Storage: memory.New(), manager: manager}
+	if err := Initialize(&FacadeConfig{
+		Backends:       map[string]common.Storage{"local": backend},
+		DefaultBackend: "local",
+	}); err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+	defer Reset()
+	ctx := context.Background()
+	if _, err := CheckReplicationLag(ctx, "", 0); !errors.Is(err, common.ErrInvalidArgument) {
+		t.Errorf("Expected ErrInvalidArgument for a zero threshold, got %v", err)
+	}
+
+	sink := &recordingSink{}
+	events.RegisterSink("facade-alerts", func(map[string]string) (events.Sink, error) { return sink, nil })
+	cfg := &events.Config{Rules: []events.Rule{{
+		ID:     "ops",
+		Events: []string{"Policy:*", "Replication:*"},
+		Sink:   events.SinkConfig{Type: "facade-alerts"},
+	}}}
+	if err := EnableNotifications("", cfg); err != nil {
+		t.Fatalf("EnableNotifications() error = %v", err)
+	}
+
+	if err := backend.Storage.Put("logs/a.log", strings.NewReader("x")); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddPolicy("", common.LifecyclePolicy{ID: "expire-logs", Prefix: "logs/", Retention: time.Nanosecond, Action: "delete"}); err != nil {
+		t.Fatalf("AddPolicy() error = %v", err)
+	}
+	time.Sleep(time.Millisecond)
+	if _, err := ApplyPolicies(ctx, ""); err == nil {
+		t.Error("Expected ApplyPolicies() to report the refused delete")
+	}
+
+	lagging, err := CheckReplicationLag(ctx, "", time.Hour)
+	if err != nil {
+		t.Fatalf("CheckReplicationLag() error = %v", err)
+	}
+	if len(lagging) != 2 || lagging[0].PolicyID != "stale" || lagging[0].Lag < 2*time.Hour ||
+		lagging[1].PolicyID != "never" || !lagging[1].LastSync.IsZero() {
+		t.Errorf("CheckReplicationLag() = %+v, want stale and never", lagging)
+	}
+
+	notifier, err := Notifications("")
+	if err != nil {
+		t.Fatalf("Notifications() error = %v", err)
+	}
+	if err := notifier.Close(ctx); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if len(sink.records) != 3 {
+		t.Fatalf("Expected 3 alerts, got %+v", sink.records)
+	}
+	failed := sink.records[0]
+	if failed.EventName != events.EventPolicyFailed || failed.S3.Object.Key != "logs/a.log" ||
+		failed.Details[events.DetailError] != "delete refused" {
+		t.Errorf("Unexpected policy alert %+v", failed)
+	}
+	stale := sink.records[1]
+	if stale.EventName != events.EventReplicationLagExceeded || stale.Details[events.DetailPolicy] != "stale" ||
+		stale.Details[events.DetailThreshold] != "1h0m0s" || stale.Details[events.DetailLag] == "" {
+		t.Errorf("Unexpected lag alert %+v", stale)
+	}
+	if never := sink.records[2]; never.Details[events.DetailLastSync] != "never" {
+		t.Errorf("Unexpected lag alert %+v", never)
+	}
+}
+
+func TestReadRouting(t *testing.T) {
+	Reset()
+	err := Initialize(&FacadeConfig{
+		Backends:       map[string]common.Storage{"local": memory.New()},
+		DefaultBackend: "local",
+		ReadRouting:    &routing.Config{Backends: []string{"missing"}},
+	})
+	if err == nil {
+		t.Fatal("Expected an error for an unknown read routing backend")
+	}
+
+	Reset()
+	primary, replica := memory.New(), memory.New()
+	err = Initialize(&FacadeConfig{
+		Backends:       map[string]common.Storage{"primary": primary, "replica": replica},
+		DefaultBackend: "primary",
+		ReadRouting:    &routing.Config{Strategy: routing.StrategyRoundRobin, Backends: []string{"replica"}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+	defer Reset()
+	ctx := context.Background()
+
+	// Both replicas hold a.txt, only the primary holds b.txt yet.
+	for _, storage := range []common.Storage{primary, replica} {
+		if err := storage.Put("a.txt", strings.NewReader("a")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := PutWithContext(ctx, "b.txt", strings.NewReader("b")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if ok, _ := replica.Exists(ctx, "b.txt"); ok {
+		t.Fatal("Put was routed to a replica")
+	}
+
+	// Round-robin alternates the first choice, so one of two reads goes to
+	// the lagging replica, whose answer is that b.txt does not exist.
+	reads := map[string]func() error{
+		"Get": func() error {
+			rc, err := GetWithContext(ctx, "b.txt")
+			if err == nil {
+				_ = rc.Close()
+			}
+			return err
+		},
+		"Exists": func() error {
+			if ok, err := Exists(ctx, "b.txt"); err != nil || ok {
+				return err
+			}
+			return common.ErrKeyNotFound
+		},
+		"GetMetadata": func() error {
+			_, err := GetMetadata(ctx, "b.txt")
+			return err
+		},
+	}
+	for name, read := range reads {
+		missing := 0
+		for i := 0; i < 2; i++ {
+			if err := read(); errors.Is(err, common.ErrNotFound) {
+				missing++
+			} else if err != nil {
+				t.Fatalf("%s() %d error = %v", name, i, err)
+			}
+		}
+		if missing != 1 {
+			t.Errorf("%s() found b.txt missing %d of 2 times, want 1", name, missing)
+		}
+	}
+
+	// An unavailable replica passes its reads on to the next one.
+	facade.mu.Lock()
+	facade.backends["replica"] = unavailableReads{Storage: replica}
+	facade.mu.Unlock()
+	for name, read := range reads {
+		for i := 0; i < 2; i++ {
+			if err := read(); err != nil {
+				t.Fatalf("%s() %d with the replica down error = %v", name, i, err)
+			}
+		}
+	}
+	facade.mu.Lock()
+	facade.backends["replica"] = replica
+	facade.mu.Unlock()
+	if !nextReplica(&net.OpError{Op: "dial", Err: errors.New("connection refused")}) {
+		t.Error("Expected a transport error to move on to the next replica")
+	}
+	if ok, err := Exists(ctx, "missing.txt"); ok || err != nil {
+		t.Fatalf("Exists() of a missing key = %v, %v", ok, err)
+	}
+	if _, err := GetWithContext(ctx, "missing.txt"); !errors.Is(err, common.ErrNotFound) {
+		t.Fatalf("Get() of a missing key error = %v, want ErrNotFound", err)
+	}
+
+	// Reads spread across both replicas.
+	spy := &readCounter{Storage: replica}
+	facade.mu.Lock()
+	facade.backends["replica"] = spy
+	facade.mu.Unlock()
+	for i := 0; i < 4; i++ {
+		rc, err := Get("a.txt")
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		_ = rc.Close()
+	}
+	if spy.reads != 2 {
+		t.Fatalf("Replica served %d of 4 reads, want 2", spy.reads)
+	}
+
+	// Naming a backend bypasses routing.
+	if _, err := GetWithContext(ctx, "replica:b.txt"); !errors.Is(err, common.ErrNotFound) {
+		t.Fatalf("Get() naming the replica error = %v, want ErrNotFound", err)
+	}
+}
+
+// unavailableReads is a backend that cannot be read.
+type unavailableReads struct {
+	common.Storage
+}
+
+func (unavailableReads) GetWithContext(context.Context, string) (io.ReadCloser, error) {
+	return nil, common.ErrUnavailable
+}
+
+func (unavailableReads) GetMetadata(context.Context, string) (*common.Metadata, error) {
+	return nil, common.ErrUnavailable
+}
+
+func (unavailableReads) Exists(context.Context, string) (bool, error) {
+	return false, common.ErrUnavailable
+}
+
+// readCounter counts the reads served by a backend.
+type readCounter struct {
+	common.Storage
+	reads int
+}
+
+func (r *readCounter) Get(key string) (io.ReadCloser, error) {
+	r.reads++
+	return r.Storage.Get(key)
+}
+
+func TestEnableFailover(t *testing.T) {
+	Reset()
+	if err := EnableFailover("", &FailoverConfig{Secondary: "dr"}); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("Expected ErrNotInitialized, got %v", err)
+	}
+
+	primary, secondary := memory.New(), memory.New()
+	err := Initialize(&FacadeConfig{
+		Backends:       map[string]common.Storage{"local": primary, "dr": secondary},
+		DefaultBackend: "local",
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+	ctx := context.Background()
+	if _, err := Health(""); !errors.Is(err, ErrFailoverNotEnabled) {
+		t.Errorf("Expected ErrFailoverNotEnabled, got %v", err)
+	}
+	if err := EnableFailover("", nil); !errors.Is(err, common.ErrInvalidArgument) {
+		t.Errorf("Expected ErrInvalidArgument without a secondary, got %v", err)
+	}
+	if err := EnableFailover("local", &FailoverConfig{Secondary: "missing"}); !errors.Is(err, ErrBackendNotFound) {
+		t.Errorf("Expected ErrBackendNotFound, got %v", err)
+	}
+
+	down := errors.New("primary down")
+	var probeMu sync.Mutex
+	probeErr := error(nil)
+	transitions := make(chan health.Transition, 2)
+	err = EnableFailover("", &FailoverConfig{
+		Secondary: "dr",
+		Health: &health.Config{
+			Custom: health.ProbeFunc(func(context.Context, common.Storage) error {
+				probeMu.Lock()
+				defer probeMu.Unlock()
+				return probeErr
+			}),
+			Interval:         time.Hour,
+			FailureThreshold: 1,
+			SuccessThreshold: 1,
+		},
+		OnTransition: func(tr health.Transition) { transitions <- tr },
+	})
+	if err != nil {
+		t.Fatalf("EnableFailover() error = %v", err)
+	}
+	if err := EnableSearch("", nil); err != nil {
+		t.Fatalf("EnableSearch() error = %v", err)
+	}
+	if err := EnableFailover("", &FailoverConfig{Secondary: "dr"}); err != nil {
+		t.Fatalf("EnableFailover() second call error = %v", err)
+	}
+	monitor, err := Health("local")
+	if err != nil {
+		t.Fatalf("Health() error = %v", err)
+	}
+	defer monitor.Close()
+
+	probeMu.Lock()
+	probeErr = down
+	probeMu.Unlock()
+	if err := monitor.Check(ctx); !errors.Is(err, down) {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if tr := <-transitions; !tr.FailedOver || tr.Backend != "local" || tr.Secondary != "dr" {
+		t.Fatalf("Unexpected transition %+v", tr)
+	}
+	if err := PutWithContext(ctx, "a.txt", strings.NewReader("a")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if ok, _ := secondary.Exists(ctx, "a.txt"); !ok {
+		t.Error("Put while failed over did not reach the secondary")
+	}
+	if ok, _ := primary.Exists(ctx, "a.txt"); ok {
+		t.Error("Put while failed over reached the primary")
+	}
+
+	probeMu.Lock()
+	probeErr = nil
+	probeMu.Unlock()
+	_ = monitor.Check(ctx)
+	if tr := <-transitions; tr.FailedOver {
+		t.Fatalf("Expected a failback, got %+v", tr)
+	}
+	if ok, _ := Exists(ctx, "a.txt"); ok {
+		t.Error("Read after failback was not routed to the primary")
+	}
+}
+
+func TestEnableChangeFeed(t *testing.T) {
+	Reset()
+	if err := EnableChangeFeed("", nil); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("Expected ErrNotInitialized, got %v", err)
+	}
+
+	err := Initialize(&FacadeConfig{
+		Backends:       map[string]common.Storage{"local": memory.New()},
+		DefaultBackend: "local",
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+	ctx := context.Background()
+	if _, err := GetChanges(ctx, "", 0, 0); !errors.Is(err, ErrChangeFeedNotEnabled) {
+		t.Errorf("Expected ErrChangeFeedNotEnabled, got %v", err)
+	}
+
+	if err := EnableChangeFeed("local", nil); err != nil {
+		t.Fatalf("EnableChangeFeed() error = %v", err)
+	}
+	if err := EnableSearch("", nil); err != nil {
+		t.Fatalf("EnableSearch() error = %v", err)
+	}
+	if err := EnableChangeFeed("", nil); err != nil {
+		t.Fatalf("EnableChangeFeed() second call error = %v", err)
+	}
+
+	if err := PutWithContext(ctx, "a.txt", strings.NewReader("a")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if err := DeleteWithContext(ctx, "a.txt"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	page, err := GetChanges(ctx, "local", 0, 0)
+	if err != nil {
+		t.Fatalf("GetChanges() error = %v", err)
+	}
+	if len(page.Changes) != 2 || page.Changes[0].Op != changefeed.OpPut || page.Changes[1].Op != changefeed.OpDelete {
+		t.Fatalf("Unexpected changes %+v", page.Changes)
+	}
+	page, err = GetChanges(ctx, "", page.Next, 0)
+	if err != nil || len(page.Changes) != 0 || page.Latest != 2 {
+		t.Errorf("GetChanges() after last = %+v, %v", page, err)
+	}
+	if _, err := GetChanges(ctx, "invalid/name", 0, 0); err == nil {
+		t.Error("Expected error for invalid backend name")
+	}
+}
+
+func TestEnableTransforms(t *testing.T) {
+	Reset()
+	cfg := &transform.Config{Presets: []transform.Preset{
+		{Name: "thumb", Processor: "image", OnPut: true, Settings: map[string]string{"width": "2"}},
+	}}
+	if err := EnableTransforms("", cfg); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("Expected ErrNotInitialized, got %v", err)
+	}
+
+	err := Initialize(&FacadeConfig{
+		Backends:       map[string]common.Storage{"local": memory.New()},
+		DefaultBackend: "local",
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+	ctx := context.Background()
+	if _, _, err := GetDerived(ctx, "a.png", "thumb"); !errors.Is(err, ErrTransformsNotEnabled) {
+		t.Errorf("Expected ErrTransformsNotEnabled, got %v", err)
+	}
+
+	if err := EnableTransforms("local", cfg); err != nil {
+		t.Fatalf("EnableTransforms() error = %v", err)
+	}
+	if err := EnableSearch("", nil); err != nil {
+		t.Fatalf("EnableSearch() error = %v", err)
+	}
+	if err := EnableTransforms("", cfg); err != nil {
+		t.Fatalf("EnableTransforms() second call error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 8, 4))); err != nil {
+		t.Fatal(err)
+	}
+	if err := PutWithContext(ctx, "a.png", &buf); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if exists, _ := Exists(ctx, ".derived/thumb/a.png"); !exists {
+		t.Error("on_put derived object was not generated")
+	}
+	if err := PutWithContext(ctx, ".derived/thumb/a.png", strings.NewReader("x")); !errors.Is(err, common.ErrReservedKey) {
+		t.Errorf("Expected ErrReservedKey, got %v", err)
+	}
+
+	rc, metadata, err := GetDerived(ctx, "local:a.png", "thumb")
+	if err != nil {
+		t.Fatalf("GetDerived() error = %v", err)
+	}
+	_ = rc.Close()
+	if metadata.ContentType != "image/jpeg" {
+		t.Errorf("Derived content type = %q", metadata.ContentType)
+	}
+	if transformer, err := Transforms(""); err != nil || len(transformer.Presets()) != 1 {
+		t.Errorf("Transforms() = %v, %v", transformer, err)
+	}
+	if _, err := Transforms("invalid/name"); err == nil {
+		t.Error("Expected error for invalid backend name")
+	}
+}
+
+func TestSelectObjectContent(t *testing.T) {
+	Reset()
+	err := Initialize(&FacadeConfig{
+		Backends:       map[string]common.Storage{"local": memory.New()},
+		DefaultBackend: "local",
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+	ctx := context.Background()
+	if err := PutWithContext(ctx, "sales.csv", strings.NewReader("region,amount\neast,10\nwest,5\neast,7\n")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	req := &query.Request{
+		Expression: "SELECT SUM(amount) FROM S3Object WHERE region = 'east'",
+		Input:      query.Input{Format: query.FormatCSV, CSV: query.CSVInput{FileHeaderInfo: query.HeaderUse}},
+	}
+	r, err := SelectObjectContent(ctx, "local:sales.csv", req)
+	if err != nil {
+		t.Fatalf("SelectObjectContent() error = %v", err)
+	}
+	out, err := io.ReadAll(r)
+	_ = r.Close()
+	if err != nil || string(out) != "17\n" {
+		t.Errorf("SelectObjectContent() = %q, %v", out, err)
+	}
+
+	if _, err := SelectObjectContent(ctx, "missing.csv", req); !errors.Is(err, common.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	if _, err := SelectObjectContent(ctx, "invalid/name:sales.csv", req); err == nil {
+		t.Error("Expected error for invalid backend name")
+	}
+	req.Expression = "SELECT"
+	if _, err := SelectObjectContent(ctx, "sales.csv", req); !errors.Is(err, query.ErrInvalidQuery) {
+		t.Errorf("Expected ErrInvalidQuery, got %v", err)
+	}
+}
+
+func TestUpdateMetadataBatch(t *testing.T) {
+	Reset()
+	archive := memory.New()
+	err := Initialize(&FacadeConfig{
+		Backends: map[string]common.Storage{
+			"local":   memory.New(),
+			"archive": archive,
+		},
+		DefaultBackend: "local",
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+
+	ctx := context.Background()
+	for _, key := range []string{"logs/a.log", "logs/b.log", "data/c.csv"} {
+		if err := archive.PutWithMetadata(ctx, key, strings.NewReader("x"), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	patch := &common.MetadataPatch{StorageClass: "COLD", Set: map[string]string{"dataset": "2024"}}
+	updated, err := UpdateMetadataBatch(ctx, "archive", "logs/", patch)
+	if err != nil || updated != 2 {
+		t.Fatalf("UpdateMetadataBatch() = %d, %v, want 2 updated", updated, err)
+	}
+	metadata, err := GetMetadata(ctx, "archive:logs/b.log")
+	if err != nil || metadata.StorageClass != "COLD" || metadata.Custom["dataset"] != "2024" {
+		t.Errorf("GetMetadata() = %+v, %v", metadata, err)
+	}
+	if metadata, _ := GetMetadata(ctx, "archive:data/c.csv"); metadata.StorageClass != "" {
+		t.Errorf("object outside the prefix was updated: %+v", metadata)
+	}
+
+	if _, err := UpdateMetadataBatch(ctx, "", "../logs", patch); err == nil {
+		t.Error("expected an invalid prefix to be rejected")
+	}
+	if _, err := UpdateMetadataBatch(ctx, "missing", "logs/", patch); err == nil {
+		t.Error("expected an unknown backend to be rejected")
+	}
+}
+
+func TestAppendAndCompose(t *testing.T) {
+	Reset()
+	err := Initialize(&FacadeConfig{
+		Backends: map[string]common.Storage{
+			"local": newMockStorage("local"),
+			"other": newMockStorage("other"),
+		},
+		DefaultBackend: "local",
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+
+	ctx := context.Background()
+	if err := Append(ctx, "app.log", strings.NewReader("one\n")); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	if err := Append(ctx, "local:app.log", strings.NewReader("two\n")); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	if err := Compose(ctx, "both.log", "app.log", "local:app.log"); err != nil {
+		t.Fatalf("Compose() error = %v", err)
+	}
+
+	rc, err := GetWithContext(ctx, "both.log")
+	if err != nil {
+		t.Fatalf("GetWithContext() error = %v", err)
+	}
+	data, _ := io.ReadAll(rc)
+	_ = rc.Close()
+	if want := "one\ntwo\none\ntwo\n"; string(data) != want {
+		t.Errorf("content = %q, want %q", data, want)
+	}
+
+	if err := Append(ctx, "../app.log", strings.NewReader("x")); err == nil {
+		t.Error("Append() with invalid key should fail")
+	}
+	err = Compose(ctx, "both.log", "app.log", "other:app.log")
+	if !errors.Is(err, common.ErrInvalidArgument) {
+		t.Errorf("Compose() across backends error = %v, want ErrInvalidArgument", err)
+	}
+}
+
+func TestPutAtomic(t *testing.T) {
+	Reset()
+	err := Initialize(&FacadeConfig{
+		Backends:       map[string]common.Storage{"mem": memory.New()},
+		DefaultBackend: "mem",
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+	ctx := context.Background()
+
+	if err := PutAtomic(ctx, "mem:app.tar.gz", strings.NewReader("release"), &common.Metadata{Size: 7}); err != nil {
+		t.Fatalf("PutAtomic() error = %v", err)
+	}
+	rc, err := GetWithContext(ctx, "app.tar.gz")
+	if err != nil {
+		t.Fatalf("GetWithContext() error = %v", err)
+	}
+	data, _ := io.ReadAll(rc)
+	_ = rc.Close()
+	if string(data) != "release" {
+		t.Errorf("content = %q, want release", data)
+	}
+
+	if err := PutAtomic(ctx, "app.tar.gz", strings.NewReader("trunc"), &common.Metadata{Size: 7}); !errors.Is(err, common.ErrPreconditionFailed) {
+		t.Errorf("PutAtomic() with short data error = %v, want ErrPreconditionFailed", err)
+	}
+	if err := PutAtomic(ctx, "../app", strings.NewReader("x"), nil); err == nil {
+		t.Error("PutAtomic() with invalid key should fail")
+	}
+}
+
+func TestStagingReserved(t *testing.T) {
+	Reset()
+	backend := memory.New()
+	err := Initialize(&FacadeConfig{
+		Backends:       map[string]common.Storage{"mem": backend},
+		DefaultBackend: "mem",
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+	defer Reset()
+	ctx := context.Background()
+
+	staged := common.StagingPrefix + "0123456789abcdef0123456789abcdef"
+	if err := backend.Put(staged, strings.NewReader("partial")); err != nil {
+		t.Fatal(err)
+	}
+	if err := PutWithContext(ctx, common.StagingPrefix+"x", strings.NewReader("x")); !errors.Is(err, common.ErrReservedKey) {
+		t.Errorf("PutWithContext() in the staging namespace error = %v, want ErrReservedKey", err)
+	}
+	if _, err := GetWithContext(ctx, staged); !errors.Is(err, common.ErrReservedKey) {
+		t.Errorf("GetWithContext() of a staged object error = %v, want ErrReservedKey", err)
+	}
+	if err := DeleteWithContext(ctx, staged); !errors.Is(err, common.ErrReservedKey) {
+		t.Errorf("DeleteWithContext() of a staged object error = %v, want ErrReservedKey", err)
+	}
+	if err := PutAtomic(ctx, "app.tar.gz", strings.NewReader("release"), nil); err != nil {
+		t.Fatalf("PutAtomic() error = %v", err)
+	}
+	keys, err := ListWithContext(ctx, "")
+	if err != nil {
+		t.Fatalf("ListWithContext() error = %v", err)
+	}
+	if len(keys) != 1 || keys[0] != "app.tar.gz" {
+		t.Errorf("ListWithContext() = %v, want only app.tar.gz", keys)
+	}
+}
+
+func TestRenamePrefix(t *testing.T) {
+	Reset()
+	err := Initialize(&FacadeConfig{
+		Backends:       map[string]common.Storage{"mem": memory.New()},
+		DefaultBackend: "mem",
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+	ctx := context.Background()
+	for _, key := range []string{"2024/a", "2024/b"} {
+		if err := PutWithContext(ctx, key, strings.NewReader(key)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := RenamePrefix(ctx, "mem", "2024/", "archive/2024/", nil)
+	if err != nil || result.Moved != 2 {
+		t.Fatalf("RenamePrefix() = %+v, %v, want 2 moved", result, err)
+	}
+	if exists, _ := Exists(ctx, "archive/2024/b"); !exists {
+		t.Error("archive/2024/b does not exist after the rename")
+	}
+	if _, err := RenamePrefix(ctx, "", "../x/", "y/", nil); err == nil {
+		t.Error("RenamePrefix() with invalid prefix should fail")
+	}
+	if _, err := RenamePrefix(ctx, "missing", "a/", "b/", nil); err == nil {
+		t.Error("RenamePrefix() on unknown backend should fail")
+	}
+}
+
+func TestStreamingHelpers(t *testing.T) {
+	Reset()
+	err := Initialize(&FacadeConfig{
+		Backends: map[string]common.Storage{
+			"mem":  memory.New(),
+			"mock": newMockStorage("mock"),
+		},
+		DefaultBackend: "mem",
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+	ctx := context.Background()
+
+	if err := PutFromReaderN(ctx, "page.html", strings.NewReader("<h1>hello</h1>"), 14, &common.Metadata{ContentType: "text/html"}); err != nil {
+		t.Fatalf("PutFromReaderN() error = %v", err)
+	}
+	if err := PutFromReaderN(ctx, "short", strings.NewReader("abc"), 4, nil); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("PutFromReaderN() with short reader error = %v, want io.ErrUnexpectedEOF", err)
+	}
+	if err := PutFromReaderN(ctx, "long", strings.NewReader("abcde"), 4, nil); !errors.Is(err, common.ErrInvalidArgument) {
+		t.Errorf("PutFromReaderN() with long reader error = %v, want ErrInvalidArgument", err)
+	}
+	if exists, _ := Exists(ctx, "short"); exists {
+		t.Error("PutFromReaderN() should not store a short stream")
+	}
+
+	var buf bytes.Buffer
+	n, err := GetToWriter(ctx, "page.html", &buf)
+	if err != nil || n != 14 || buf.String() != "<h1>hello</h1>" {
+		t.Errorf("GetToWriter() = %d, %q, %v", n, buf.String(), err)
+	}
+	if _, err := GetToWriter(ctx, "missing", &buf); err == nil {
+		t.Error("GetToWriter() on missing key should fail")
+	}
+
+	rs, err := OpenRange(ctx, "page.html")
+	if err != nil {
+		t.Fatalf("OpenRange() error = %v", err)
+	}
+	defer func() { _ = rs.Close() }()
+	req := httptest.NewRequest(http.MethodGet, "/page.html", nil)
+	req.Header.Set("Range", "bytes=4-8")
+	rec := httptest.NewRecorder()
+	http.ServeContent(rec, req, "page.html", time.Time{}, rs)
+	if rec.Code != http.StatusPartialContent || rec.Body.String() != "hello" {
+		t.Errorf("ServeContent = %d %q, want 206 %q", rec.Code, rec.Body.String(), "hello")
+	}
+
+	if _, err := OpenRange(ctx, "mock:page.html"); !errors.Is(err, ErrSeekNotSupported) {
+		t.Errorf("OpenRange() on backend without ranges error = %v, want ErrSeekNotSupported", err)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	Reset()
+	err := Initialize(&FacadeConfig{
+		Backends: map[string]common.Storage{
+			"mem":   memory.New(),
+			"other": memory.New(),
+		},
+		DefaultBackend: "mem",
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := Watch(ctx, "other:conf/", &storagefs.WatchOptions{Interval: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	if err := PutWithContext(ctx, "conf/ignored.yaml", strings.NewReader("x")); err != nil {
+		t.Fatal(err)
+	}
+	if err := PutWithContext(ctx, "other:conf/app.yaml", strings.NewReader("x")); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case event := <-events:
+		if event.Key != "conf/app.yaml" || event.Op != storagefs.EventCreate {
+			t.Errorf("Watch() event = %s %s, want create conf/app.yaml", event.Op, event.Key)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Watch() timed out waiting for event")
+	}
+
+	if _, err := Watch(ctx, "missing:conf/", nil); !errors.Is(err, ErrBackendNotFound) {
+		t.Errorf("Watch() on unknown backend error = %v, want ErrBackendNotFound", err)
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	Reset()
+	ctx := context.Background()
+	if err := EnableAccessTracking("", nil); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("Expected ErrNotInitialized, got %v", err)
+	}
+
+	if err := Initialize(&FacadeConfig{
+		Backends:       map[string]common.Storage{"mem": memory.New()},
+		DefaultBackend: "mem",
+	}); err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+	defer Reset()
+	for _, key := range []string{"logs/a.log", "logs/b.log", "readme.txt"} {
+		if err := PutWithContext(ctx, key, strings.NewReader("hello")); err != nil {
+			t.Fatalf("PutWithContext() error = %v", err)
+		}
+	}
+
+	// Without tracking only ages are reported
+	report, err := Analyze(ctx, "", "", nil)
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	if report.Total.Objects != 3 || report.Total.Access != nil || len(report.Prefixes) != 2 {
+		t.Errorf("Analyze() = %+v, want 3 objects in 2 prefixes without reads", report)
+	}
+	if _, err := AccessTracker(""); !errors.Is(err, ErrAccessNotEnabled) {
+		t.Errorf("Expected ErrAccessNotEnabled, got %v", err)
+	}
+
+	if err := EnableAccessTracking("mem", &access.Config{}); err != nil {
+		t.Fatalf("EnableAccessTracking() error = %v", err)
+	}
+	tracker, err := AccessTracker("")
+	if err != nil {
+		t.Fatalf("AccessTracker() error = %v", err)
+	}
+	if err := EnableAccessTracking("", nil); err != nil {
+		t.Fatalf("EnableAccessTracking() again error = %v", err)
+	}
+	if again, _ := AccessTracker("mem"); again != tracker {
+		t.Error("EnableAccessTracking() again replaced the tracker")
+	}
+	rc, err := GetWithContext(ctx, "logs/a.log")
+	if err != nil {
+		t.Fatalf("GetWithContext() error = %v", err)
+	}
+	_ = rc.Close()
+
+	report, err = Analyze(ctx, "mem", "logs/", nil)
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	never := report.Total.Access[len(report.Total.Access)-1]
+	if report.Total.Objects != 2 || report.Total.Access[0].Objects != 1 || never.Objects != 1 {
+		t.Errorf("Access = %+v, want one read today and one never read", report.Total.Access)
+	}
+	if _, err := Analyze(ctx, "", "../x", nil); err == nil {
+		t.Error("Expected an error for an invalid prefix")
+	}
+}
+
+func TestFindCapability(t *testing.T) {
+	backend := newMockStorage("base")
+	tombstones := tombstone.NewStorage(backend, nil)
+	outer := search.NewStorage(tombstones, nil)
+
+	if found, ok := findCapability[*tombstone.Storage](outer); !ok || found != tombstones {
+		t.Errorf("findCapability(*tombstone.Storage) = %v, %v", found, ok)
+	}
+	if found, ok := findCapability[*search.Storage](outer); !ok || found != outer {
+		t.Errorf("findCapability(*search.Storage) = %v, %v", found, ok)
+	}
+	if _, ok := findCapability[*locks.Storage](outer); ok {
+		t.Error("findCapability(*locks.Storage) found a layer that is not in the chain")
+	}
+	if _, ok := findCapability[*tombstone.Storage](nil); ok {
+		t.Error("findCapability(nil) = true")
+	}
+	if base := baseBackend(outer); base != backend {
+		t.Errorf("baseBackend() = %v, want the innermost backend", base)
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package objstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/routing"
+)
+
+// routedGet is routedRead for object content. When the router hedges
+// reads, a read of the default backend that has not returned within the
+// hedge delay is also sent to the next replica, and the first response is
+// used.
+func routedGet(ctx context.Context, backend, key string, get func(ctx context.Context, storage common.Storage) (io.ReadCloser, error)) (io.ReadCloser, error) {
+	var router *routing.Router
+	if IsInitialized() {
+		facade.mu.RLock()
+		router = facade.router
+		facade.mu.RUnlock()
+	}
+	if backend != "" || router == nil || router.HedgeAfter() <= 0 {
+		return routedRead(backend, key, func(storage common.Storage) (io.ReadCloser, error) {
+			return get(ctx, storage)
+		})
+	}
+	return hedgedGet(ctx, router, key, get)
+}
+
+// attempt is the outcome of reading one replica during a hedged read.
+type attempt struct {
+	index int
+	rc    io.ReadCloser
+	err   error
+}
+
+// hedgedGet reads key from the router's replicas in order. A new replica
+// is tried when the hedge delay passes without a response, and at once
+// when every replica tried so far is unavailable or cannot be reached. The
+// first content or other answer returned wins, including a replica's
+// answer that the key does not exist; the other reads are cancelled and
+// their content, if any, is closed.
+func hedgedGet(ctx context.Context, router *routing.Router, key string, get func(ctx context.Context, storage common.Storage) (io.ReadCloser, error)) (io.ReadCloser, error) {
+	names := router.Backends(key)
+	results := make(chan attempt, len(names))
+	var (
+		cancels []context.CancelFunc
+		hedges  []bool // whether each read was sent as a hedge
+	)
+	launch := func(hedge bool) {
+		index := len(cancels)
+		readCtx, cancel := context.WithCancel(ctx)
+		cancels = append(cancels, cancel)
+		hedges = append(hedges, hedge)
+		go func() {
+			storage, err := Backend(names[index])
+			if err != nil {
+				results <- attempt{index: index, err: err}
+				return
+			}
+			start := time.Now()
+			rc, err := get(readCtx, storage)
+			if err == nil || readCtx.Err() == nil {
+				// A read cancelled because another won says nothing
+				// about its replica.
+				observeRead(router, names[index], time.Since(start), err)
+			}
+			results <- attempt{index: index, rc: rc, err: err}
+		}()
+	}
+
+	// finish cancels the reads other than winner (-1 for none), closes
+	// the content of those still pending once they return and records
+	// the hedges sent.
+	finish := func(winner, pending int) {
+		for i, cancel := range cancels {
+			if i != winner {
+				cancel()
+			}
+			if hedges[i] {
+				router.Hedged(i == winner)
+			}
+		}
+		go func() {
+			for range pending {
+				if r := <-results; r.err == nil {
+					_ = r.rc.Close()
+				}
+			}
+		}()
+	}
+
+	timer := time.NewTimer(router.HedgeAfter())
+	defer timer.Stop()
+	launch(false)
+	pending := 1
+	var lastErr error
+	for pending > 0 {
+		select {
+		case <-timer.C:
+			if len(cancels) < len(names) {
+				launch(true)
+				pending++
+				timer.Reset(router.HedgeAfter())
+			}
+		case r := <-results:
+			pending--
+			if r.err == nil {
+				finish(r.index, pending)
+				return &cancelOnClose{ReadCloser: r.rc, cancel: cancels[r.index]}, nil
+			}
+			if !nextReplica(r.err) {
+				finish(-1, pending)
+				return nil, r.err
+			}
+			lastErr = r.err
+			if pending == 0 && len(cancels) < len(names) {
+				launch(false)
+				pending++
+				timer.Reset(router.HedgeAfter())
+			}
+		}
+	}
+	finish(-1, 0)
+	return nil, lastErr
+}
+
+// observeRead records a routed read of backend with the router. A replica
+// lagging behind is not a slow one, so a missing key counts as a success.
+func observeRead(router *routing.Router, backend string, d time.Duration, err error) {
+	if errors.Is(err, common.ErrNotFound) {
+		err = nil
+	}
+	router.Observe(backend, d, err)
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package objstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+	"github.com/jeremyhahn/go-objstore/pkg/routing"
+)
+
+// stalledReads is a backend whose reads hang until they are cancelled.
+type stalledReads struct {
+	common.Storage
+	cancelled chan struct{}
+}
+
+func (s *stalledReads) GetWithContext(ctx context.Context, key string) (io.ReadCloser, error) {
+	<-ctx.Done()
+	close(s.cancelled)
+	return nil, ctx.Err()
+}
+
+func TestHedgedGet(t *testing.T) {
+	Reset()
+	defer Reset()
+	primary := &stalledReads{Storage: memory.New(), cancelled: make(chan struct{})}
+	replica := memory.New()
+	if err := Initialize(&FacadeConfig{
+		Backends:       map[string]common.Storage{"primary": primary, "replica": replica},
+		DefaultBackend: "primary",
+		ReadRouting:    &routing.Config{Backends: []string{"replica"}, HedgeAfter: 10 * time.Millisecond},
+	}); err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+	if err := replica.Put("a.txt", strings.NewReader("a")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Round-robin reads the primary first; it stalls, so the hedge to the
+	// replica answers and the primary's read is cancelled.
+	rc, err := GetWithContext(context.Background(), "a.txt")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	data, err := io.ReadAll(rc)
+	_ = rc.Close()
+	if err != nil || string(data) != "a" {
+		t.Fatalf("Get() = %q, %v", data, err)
+	}
+	select {
+	case <-primary.cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("the losing read was not cancelled")
+	}
+	facade.mu.RLock()
+	router := facade.router
+	facade.mu.RUnlock()
+	if sent, won := router.Hedges(); sent != 1 || won != 1 {
+		t.Errorf("Hedges() = %d, %d, want 1, 1", sent, won)
+	}
+}
+
+func TestHedgedGetNotFound(t *testing.T) {
+	Reset()
+	defer Reset()
+	primary := &stalledReads{Storage: memory.New(), cancelled: make(chan struct{})}
+	missing, stale := memory.New(), memory.New()
+	if err := Initialize(&FacadeConfig{
+		Backends:       map[string]common.Storage{"primary": primary, "missing": missing, "stale": stale},
+		DefaultBackend: "primary",
+		ReadRouting:    &routing.Config{Backends: []string{"missing", "stale"}, HedgeAfter: 10 * time.Millisecond},
+	}); err != nil {
+		t.Fatalf("Failed to initialize facade: %v", err)
+	}
+	if err := stale.Put("a.txt", strings.NewReader("old")); err != nil {
+		t.Fatal(err)
+	}
+
+	// The primary stalls, so the hedge goes to the replica missing the
+	// key. Its answer wins: the read is not passed on to the stale replica.
+	if _, err := GetWithContext(context.Background(), "a.txt"); !errors.Is(err, common.ErrNotFound) {
+		t.Fatalf("Get() error = %v, want ErrNotFound", err)
+	}
+	select {
+	case <-primary.cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("the stalled read was not cancelled")
+	}
+}
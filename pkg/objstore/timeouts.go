@@ -0,0 +1,145 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package objstore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Timeouts bounds facade operations by class. A timeout applies only to
+// calls made with a context that can never be cancelled, such as
+// context.Background(), and to the legacy functions that take no context;
+// a caller's own deadline or cancellation is left alone. Zero leaves a
+// class unbounded.
+type Timeouts struct {
+	// Read bounds Get, GetWithContext, GetMetadata, Exists, OpenRange and
+	// GetAsOf, including reading the returned content until it is closed.
+	Read time.Duration
+
+	// Write bounds Put, PutWithContext, PutWithMetadata, UpdateMetadata,
+	// Delete, DeleteWithContext, RestoreFromArchive, PutAtomic, Append and
+	// Compose.
+	Write time.Duration
+
+	// List bounds List, ListWithContext, ListWithOptions and Browse.
+	List time.Duration
+
+	// Admin bounds operations over many objects: UpdateMetadataBatch,
+	// RenamePrefix, ApplyPolicies, DeleteExpired, CheckReplicationLag,
+	// FindDuplicates, Scrub and PurgeTombstones.
+	Admin time.Duration
+}
+
+// opClass is the class of a facade operation, selecting its timeout.
+type opClass int
+
+const (
+	opRead opClass = iota
+	opWrite
+	opList
+	opAdmin
+)
+
+func (c opClass) String() string {
+	switch c {
+	case opRead:
+		return "read"
+	case opWrite:
+		return "write"
+	case opList:
+		return "list"
+	default:
+		return "admin"
+	}
+}
+
+// timeout returns the timeout of class.
+func (t Timeouts) timeout(class opClass) time.Duration {
+	switch class {
+	case opRead:
+		return t.Read
+	case opWrite:
+		return t.Write
+	case opList:
+		return t.List
+	default:
+		return t.Admin
+	}
+}
+
+// boundContext applies the default timeout of class to ctx when ctx can
+// never be cancelled. With RequireContext such a ctx is refused with
+// ErrContextRequired instead. The returned cancel function is nil when ctx
+// is returned unchanged; pass it to release once the operation is done.
+func boundContext(ctx context.Context, class opClass) (context.Context, context.CancelFunc, error) {
+	if ctx.Done() != nil || !IsInitialized() {
+		return ctx, nil, nil
+	}
+
+	facade.mu.RLock()
+	timeouts, required := facade.timeouts, facade.requireContext
+	facade.mu.RUnlock()
+
+	if required {
+		return nil, nil, fmt.Errorf("%w for %s operations", ErrContextRequired, class)
+	}
+	if d := timeouts.timeout(class); d > 0 {
+		bounded, cancel := context.WithTimeout(ctx, d)
+		return bounded, cancel, nil
+	}
+	return ctx, nil, nil
+}
+
+// release calls the cancel function returned by boundContext, if any.
+func release(cancel context.CancelFunc) {
+	if cancel != nil {
+		cancel()
+	}
+}
+
+// closeWith returns rc, releasing the bounded context it is read under
+// when it is closed rather than when the read returns.
+func closeWith(rc io.ReadCloser, cancel context.CancelFunc) io.ReadCloser {
+	if cancel == nil {
+		return rc
+	}
+	return &cancelOnClose{ReadCloser: rc, cancel: cancel}
+}
+
+// cancelOnClose cancels a context when its reader is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
+
+// cancelOnCloseSeeker is cancelOnClose for random access readers.
+type cancelOnCloseSeeker struct {
+	io.ReadSeekCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnCloseSeeker) Close() error {
+	err := c.ReadSeekCloser.Close()
+	c.cancel()
+	return err
+}
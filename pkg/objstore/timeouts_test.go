@@ -0,0 +1,137 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package objstore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// stallingStorage is a backend whose metadata reads hang until their
+// context is done, and which records the contexts of reads and writes.
+type stallingStorage struct {
+	*mockStorage
+	getCtx context.Context
+	putCtx context.Context
+}
+
+func (s *stallingStorage) GetMetadata(ctx context.Context, key string) (*common.Metadata, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (s *stallingStorage) GetWithContext(ctx context.Context, key string) (io.ReadCloser, error) {
+	s.getCtx = ctx
+	return s.mockStorage.GetWithContext(ctx, key)
+}
+
+func (s *stallingStorage) PutWithContext(ctx context.Context, key string, data io.Reader) error {
+	s.putCtx = ctx
+	return s.mockStorage.PutWithContext(ctx, key, data)
+}
+
+func TestDefaultTimeouts(t *testing.T) {
+	Reset()
+	defer Reset()
+	storage := &stallingStorage{mockStorage: newMockStorage("local")}
+	if err := Initialize(&FacadeConfig{
+		Backends:        map[string]common.Storage{"local": storage},
+		DefaultTimeouts: Timeouts{Read: 20 * time.Millisecond},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A stalled read without a context gives up after the read timeout
+	start := time.Now()
+	if _, err := GetMetadata(context.Background(), "key"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("GetMetadata() error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("GetMetadata() took %v", elapsed)
+	}
+
+	// The caller's cancellation is left alone
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+	if _, err := GetMetadata(ctx, "key"); !errors.Is(err, context.Canceled) {
+		t.Errorf("GetMetadata() with a cancellable context error = %v, want context.Canceled", err)
+	}
+
+	// Writes have no timeout configured
+	if err := PutWithContext(context.Background(), "key", strings.NewReader("data")); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := storage.putCtx.Deadline(); ok {
+		t.Error("PutWithContext() applied a deadline without a write timeout")
+	}
+
+	// Content stays readable under the read timeout until it is closed
+	rc, err := Get("key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := storage.getCtx.Deadline(); !ok {
+		t.Error("Get() applied no deadline")
+	}
+	data, err := io.ReadAll(rc)
+	if err != nil || !bytes.Equal(data, []byte("data")) {
+		t.Errorf("Get() = %q, %v", data, err)
+	}
+	if storage.getCtx.Err() != nil {
+		t.Error("Get() released its context before the content was closed")
+	}
+	if err := rc.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if storage.getCtx.Err() == nil {
+		t.Error("closing the content did not release its context")
+	}
+}
+
+func TestRequireContext(t *testing.T) {
+	Reset()
+	defer Reset()
+	if err := Initialize(&FacadeConfig{
+		Backends:       map[string]common.Storage{"local": newMockStorage("local")},
+		RequireContext: true,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Put("key", strings.NewReader("data")); !errors.Is(err, ErrContextRequired) {
+		t.Errorf("Put() error = %v, want ErrContextRequired", err)
+	}
+	if _, err := ListWithContext(context.TODO(), ""); !errors.Is(err, ErrContextRequired) {
+		t.Errorf("ListWithContext(context.TODO()) error = %v, want ErrContextRequired", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if err := PutWithContext(ctx, "key", strings.NewReader("data")); err != nil {
+		t.Fatalf("PutWithContext() with a deadline error = %v", err)
+	}
+	if ok, err := Exists(ctx, "key"); !ok || err != nil {
+		t.Errorf("Exists() = %v, %v", ok, err)
+	}
+}
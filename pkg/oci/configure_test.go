@@ -0,0 +1,131 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build ocistorage
+
+package oci
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// testKey returns a PEM encoded RSA private key.
+func testKey(t *testing.T) string {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+}
+
+func TestOCI_Configure(t *testing.T) {
+	key := testKey(t)
+	keyFile := filepath.Join(t.TempDir(), "key.pem")
+	if err := os.WriteFile(keyFile, []byte(key), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	// The namespace is set so Configure does not look it up.
+	base := map[string]string{
+		"bucket":      "bucket",
+		"namespace":   "namespace",
+		"region":      "us-ashburn-1",
+		"tenancy":     "ocid1.tenancy.oc1..tenancy",
+		"user":        "ocid1.user.oc1..user",
+		"fingerprint": "aa:bb:cc",
+		"privateKey":  key,
+	}
+	with := func(changes map[string]string) map[string]string {
+		settings := map[string]string{}
+		for k, v := range base {
+			settings[k] = v
+		}
+		for k, v := range changes {
+			if v == "" {
+				delete(settings, k)
+			} else {
+				settings[k] = v
+			}
+		}
+		return settings
+	}
+	tests := []struct {
+		name     string
+		settings map[string]string
+		want     error
+	}{
+		{"valid", base, nil},
+		{"key file", with(map[string]string{"privateKey": "", "privateKeyFile": keyFile}), nil},
+		{"endpoint", with(map[string]string{"endpoint": "https://objectstorage.example.com"}), nil},
+		{"missing bucket", with(map[string]string{"bucket": ""}), common.ErrBucketNotSet},
+		{"part size too small", with(map[string]string{"partSize": "1024"}), common.ErrInvalidArgument},
+		{"part size not a number", with(map[string]string{"partSize": "big"}), common.ErrInvalidArgument},
+		{"missing user", with(map[string]string{"user": ""}), common.ErrInvalidArgument},
+		{"missing region", with(map[string]string{"region": ""}), common.ErrInvalidArgument},
+		{"missing key", with(map[string]string{"privateKey": ""}), common.ErrInvalidArgument},
+		{"invalid key", with(map[string]string{"privateKey": "not a key"}), common.ErrInvalidArgument},
+		{"config file without path", with(map[string]string{"credentials": "config-file"}), common.ErrInvalidArgument},
+		{"unknown credentials", with(map[string]string{"credentials": "magic"}), common.ErrInvalidArgument},
+		{"bad timeout", with(map[string]string{"timeout": "soon"}), common.ErrInvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &OCI{}
+			err := o.Configure(tt.settings)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("Configure() error = %v", err)
+				}
+				if o.client == nil || o.namespace != "namespace" || o.partSize != DefaultPartSize {
+					t.Errorf("Configure() left client = %v, namespace = %q, partSize = %d", o.client, o.namespace, o.partSize)
+				}
+				return
+			}
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Configure() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestOCI_ConfigureMissingKeyFile(t *testing.T) {
+	settings := map[string]string{
+		"bucket": "bucket", "namespace": "namespace", "region": "us-ashburn-1",
+		"tenancy": "t", "user": "u", "fingerprint": "f",
+		"privateKeyFile": filepath.Join(t.TempDir(), "missing.pem"),
+	}
+	if err := (&OCI{}).Configure(settings); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Configure() error = %v, want the read error", err)
+	}
+}
+
+func TestParsePartSize(t *testing.T) {
+	if size, err := parsePartSize(""); err != nil || size != DefaultPartSize {
+		t.Errorf("parsePartSize(\"\") = %d, %v", size, err)
+	}
+	if size, err := parsePartSize("10485760"); err != nil || size != MinPartSize {
+		t.Errorf("parsePartSize(min) = %d, %v", size, err)
+	}
+	if _, err := parsePartSize("53687091201"); !errors.Is(err, common.ErrInvalidArgument) {
+		t.Errorf("parsePartSize(above max) error = %v", err)
+	}
+}
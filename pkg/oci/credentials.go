@@ -0,0 +1,118 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build ocistorage
+
+package oci
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+
+	ocicommon "github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/common/auth"
+)
+
+// Credential providers selected with the "credentials" setting.
+const (
+	// CredentialsDefault uses the SDK's default chain: the DEFAULT profile
+	// of ~/.oci/config, then the OCI_* environment variables.
+	CredentialsDefault = "default"
+
+	// CredentialsConfigFile uses profile (default DEFAULT) of the CLI style
+	// configuration file configFile.
+	CredentialsConfigFile = "config-file"
+
+	// CredentialsAPIKey signs requests with an API signing key given by the
+	// tenancy, user, fingerprint and privateKey or privateKeyFile settings.
+	CredentialsAPIKey = "api-key"
+
+	// CredentialsInstancePrincipal authenticates as the compute instance,
+	// which must be in a dynamic group with access to the bucket.
+	CredentialsInstancePrincipal = "instance-principal"
+
+	// CredentialsResourcePrincipal authenticates as the resource running
+	// the process, such as an OCI Function, from the OCI_RESOURCE_PRINCIPAL_*
+	// environment variables.
+	CredentialsResourcePrincipal = "resource-principal"
+)
+
+// configurationProvider returns the configuration provider selected by
+// settings. Without a "credentials" setting, API key credentials are used
+// when tenancy is set and the default chain otherwise.
+func configurationProvider(settings map[string]string) (ocicommon.ConfigurationProvider, error) {
+	provider := settings["credentials"]
+	if provider == "" {
+		provider = CredentialsDefault
+		if settings["tenancy"] != "" {
+			provider = CredentialsAPIKey
+		}
+	}
+
+	switch provider {
+	case CredentialsDefault:
+		return ocicommon.DefaultConfigProvider(), nil
+	case CredentialsConfigFile:
+		path := settings["configFile"]
+		if path == "" {
+			return nil, fmt.Errorf("%w: config-file credentials need configFile", common.ErrInvalidArgument)
+		}
+		profile := settings["profile"]
+		if profile == "" {
+			profile = "DEFAULT"
+		}
+		return ocicommon.ConfigurationProviderFromFileWithProfile(path, profile, settings["privateKeyPassphrase"])
+	case CredentialsAPIKey:
+		return apiKeyProvider(settings)
+	case CredentialsInstancePrincipal:
+		return auth.InstancePrincipalConfigurationProvider()
+	case CredentialsResourcePrincipal:
+		return auth.ResourcePrincipalConfigurationProvider()
+	default:
+		return nil, fmt.Errorf("%w: unknown credentials provider %q", common.ErrInvalidArgument, provider)
+	}
+}
+
+// apiKeyProvider returns a provider for the API signing key in settings.
+func apiKeyProvider(settings map[string]string) (ocicommon.ConfigurationProvider, error) {
+	for _, name := range []string{"tenancy", "user", "fingerprint", "region"} {
+		if settings[name] == "" {
+			return nil, fmt.Errorf("%w: api-key credentials need %s", common.ErrInvalidArgument, name)
+		}
+	}
+	key := settings["privateKey"]
+	if key == "" {
+		if settings["privateKeyFile"] == "" {
+			return nil, fmt.Errorf("%w: api-key credentials need privateKey or privateKeyFile", common.ErrInvalidArgument)
+		}
+		data, err := os.ReadFile(settings["privateKeyFile"])
+		if err != nil {
+			return nil, fmt.Errorf("failed to read private key: %w", err)
+		}
+		key = string(data)
+	}
+	var passphrase *string
+	if p := settings["privateKeyPassphrase"]; p != "" {
+		passphrase = &p
+	}
+	provider := ocicommon.NewRawConfigurationProvider(settings["tenancy"], settings["user"], settings["region"],
+		settings["fingerprint"], key, passphrase)
+	// Parse the key now so a bad key fails Configure rather than the
+	// first request.
+	if _, err := provider.PrivateRSAKey(); err != nil {
+		return nil, fmt.Errorf("%w: invalid private key: %v", common.ErrInvalidArgument, err)
+	}
+	return provider, nil
+}
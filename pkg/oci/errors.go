@@ -0,0 +1,69 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build ocistorage
+
+package oci
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+
+	ocicommon "github.com/oracle/oci-go-sdk/v65/common"
+)
+
+// translateError maps an Object Storage error for key to the common error
+// taxonomy. Errors that are neither service errors nor network errors are
+// returned unchanged.
+func translateError(err error, key string) error {
+	if err == nil {
+		return nil
+	}
+	var serr ocicommon.ServiceError
+	if errors.As(err, &serr) {
+		return common.ProviderError(sentinelFor(serr.GetCode(), serr.GetHTTPStatusCode()), key, err)
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	if ocicommon.IsNetworkError(err) {
+		return common.ProviderError(common.ErrBackendUnavailable, key, err)
+	}
+	return err
+}
+
+// sentinelFor returns the common sentinel for an Object Storage error code,
+// falling back to the HTTP status.
+func sentinelFor(code string, status int) error {
+	switch code {
+	case "ObjectNotFound", "BucketNotFound", "NamespaceNotFound", "NoSuchUpload", "NotAuthorizedOrNotFound",
+		"RelatedResourceNotAuthorizedOrNotFound":
+		return common.ErrNotFound
+	case "BucketAlreadyExists":
+		return common.ErrAlreadyExists
+	case "NotAuthenticated", "NotAuthorized", "Forbidden", "SignUpRequired":
+		return common.ErrAccessDenied
+	case "IfMatchFailed", "IfNoneMatchFailed", "NotRestored":
+		// NotRestored: archived objects must be restored before a read.
+		return common.ErrPreconditionFailed
+	case "TooManyRequests", "QuotaExceeded", "InsufficientServiceLimit":
+		return common.ErrQuotaExceeded
+	case "InvalidParameter", "MissingParameter", "InvalidContentLength":
+		return common.ErrInvalidArgument
+	case "InternalServerError", "ServiceUnavailable":
+		return common.ErrBackendUnavailable
+	}
+	return common.ErrorForStatus(status)
+}
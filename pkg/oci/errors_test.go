@@ -0,0 +1,72 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build ocistorage
+
+package oci
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+func TestTranslateError(t *testing.T) {
+	svcErr := func(status int, code string) error {
+		return &fakeServiceError{status: status, code: code}
+	}
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"object not found", svcErr(http.StatusNotFound, "ObjectNotFound"), common.ErrKeyNotFound},
+		{"bucket not found", svcErr(http.StatusNotFound, "BucketNotFound"), common.ErrKeyNotFound},
+		{"not authorized", svcErr(http.StatusForbidden, "NotAuthorized"), common.ErrAccessDenied},
+		{"if match", svcErr(http.StatusPreconditionFailed, "IfMatchFailed"), common.ErrPreconditionFailed},
+		{"not restored", svcErr(http.StatusConflict, "NotRestored"), common.ErrPreconditionFailed},
+		{"throttled", svcErr(http.StatusTooManyRequests, "TooManyRequests"), common.ErrQuotaExceeded},
+		{"invalid", svcErr(http.StatusBadRequest, "InvalidParameter"), common.ErrInvalidArgument},
+		{"bucket exists", svcErr(http.StatusConflict, "BucketAlreadyExists"), common.ErrAlreadyExists},
+		{"internal", svcErr(http.StatusInternalServerError, "InternalServerError"), common.ErrBackendUnavailable},
+		{"status fallback", svcErr(http.StatusServiceUnavailable, "Unknown"), common.ErrBackendUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := translateError(tt.err, "a/key")
+			if !errors.Is(got, tt.want) {
+				t.Errorf("translateError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+			if !errors.Is(got, tt.err) {
+				t.Errorf("translateError(%v) dropped the service error from the chain", tt.err)
+			}
+		})
+	}
+
+	if got := translateError(svcErr(http.StatusNotFound, "NamespaceNotFound"), ""); !errors.Is(got, common.ErrNotFound) || errors.Is(got, common.ErrKeyNotFound) {
+		t.Errorf("translateError(no key) = %v, want ErrNotFound", got)
+	}
+	if got := translateError(context.Canceled, "k"); got != context.Canceled {
+		t.Errorf("translateError(canceled) = %v, want the cancellation unchanged", got)
+	}
+	plain := errors.New("not a service error")
+	if got := translateError(plain, "k"); got != plain {
+		t.Errorf("translateError(plain) = %v, want unchanged", got)
+	}
+	if got := translateError(nil, "k"); got != nil {
+		t.Errorf("translateError(nil) = %v, want nil", got)
+	}
+}
@@ -0,0 +1,373 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build ocistorage
+
+package oci
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	ocicommon "github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/objectstorage"
+)
+
+// fakeServiceError is an Object Storage service error.
+type fakeServiceError struct {
+	status int
+	code   string
+}
+
+func (e *fakeServiceError) Error() string {
+	return fmt.Sprintf("service error %s (%d)", e.code, e.status)
+}
+func (e *fakeServiceError) GetHTTPStatusCode() int  { return e.status }
+func (e *fakeServiceError) GetMessage() string      { return e.code }
+func (e *fakeServiceError) GetCode() string         { return e.code }
+func (e *fakeServiceError) GetOpcRequestID() string { return "request-id" }
+
+func notFound(code string) error {
+	return &fakeServiceError{status: http.StatusNotFound, code: code}
+}
+
+// fakeObject is an object stored by fakeAPI.
+type fakeObject struct {
+	data            []byte
+	etag            string
+	contentType     string
+	contentEncoding string
+	storageTier     objectstorage.StorageTierEnum
+	meta            map[string]string
+	modified        time.Time
+}
+
+// fakeUpload is a multipart upload in progress.
+type fakeUpload struct {
+	key     string
+	details objectstorage.CreateMultipartUploadDetails
+	parts   map[int][]byte
+}
+
+// fakeAPI is an in-memory objectStorageAPI. Like Object Storage it answers
+// missing objects with ObjectNotFound and honors If-Match.
+type fakeAPI struct {
+	mu        sync.Mutex
+	objects   map[string]*fakeObject
+	uploads   map[string]*fakeUpload
+	lifecycle []objectstorage.ObjectLifecycleRule
+	nextID    int
+
+	// failPart fails the upload of this part number when non-zero.
+	failPart int
+	// putCalls and partCalls count single and part uploads.
+	putCalls, partCalls, aborts int
+	// beforeCommit runs before a single or multipart upload is committed.
+	beforeCommit func()
+}
+
+func newFakeAPI() *fakeAPI {
+	return &fakeAPI{objects: map[string]*fakeObject{}, uploads: map[string]*fakeUpload{}}
+}
+
+// newTestOCI returns an OCI backed by a fresh fakeAPI.
+func newTestOCI() (*OCI, *fakeAPI) {
+	api := newFakeAPI()
+	return &OCI{client: api, namespace: "namespace", bucket: "bucket", partSize: DefaultPartSize}, api
+}
+
+// commit stores an object unless ifMatch does not match the stored ETag.
+// The caller holds f.mu.
+func (f *fakeAPI) commit(key string, obj *fakeObject, ifMatch *string) (string, error) {
+	if f.beforeCommit != nil {
+		hook := f.beforeCommit
+		f.beforeCommit = nil
+		f.mu.Unlock()
+		hook()
+		f.mu.Lock()
+	}
+	if ifMatch != nil {
+		if current, ok := f.objects[key]; !ok || current.etag != *ifMatch {
+			return "", &fakeServiceError{status: http.StatusPreconditionFailed, code: "IfMatchFailed"}
+		}
+	}
+	f.nextID++
+	obj.etag = fmt.Sprintf("etag-%d", f.nextID)
+	obj.modified = time.Now()
+	if obj.storageTier == "" {
+		obj.storageTier = objectstorage.StorageTierStandard
+	}
+	meta := map[string]string{}
+	for k, v := range obj.meta {
+		meta[strings.ToLower(k)] = v
+	}
+	obj.meta = meta
+	f.objects[key] = obj
+	return obj.etag, nil
+}
+
+func (f *fakeAPI) PutObject(ctx context.Context, request objectstorage.PutObjectRequest) (objectstorage.PutObjectResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return objectstorage.PutObjectResponse{}, err
+	}
+	data, err := io.ReadAll(request.PutObjectBody)
+	if err != nil {
+		return objectstorage.PutObjectResponse{}, err
+	}
+	if int64(len(data)) != *request.ContentLength {
+		return objectstorage.PutObjectResponse{}, fmt.Errorf("put %d bytes with content length %d", len(data), *request.ContentLength)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.putCalls++
+	etag, err := f.commit(*request.ObjectName, &fakeObject{
+		data:            data,
+		contentType:     deref(request.ContentType),
+		contentEncoding: deref(request.ContentEncoding),
+		storageTier:     objectstorage.StorageTierEnum(request.StorageTier),
+		meta:            request.OpcMeta,
+	}, request.IfMatch)
+	if err != nil {
+		return objectstorage.PutObjectResponse{}, err
+	}
+	return objectstorage.PutObjectResponse{ETag: ocicommon.String(etag)}, nil
+}
+
+func (f *fakeAPI) GetObject(ctx context.Context, request objectstorage.GetObjectRequest) (objectstorage.GetObjectResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return objectstorage.GetObjectResponse{}, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	obj, ok := f.objects[*request.ObjectName]
+	if !ok {
+		return objectstorage.GetObjectResponse{}, notFound("ObjectNotFound")
+	}
+	data := obj.data
+	if request.Range != nil {
+		start, end, _ := strings.Cut(strings.TrimPrefix(*request.Range, "bytes="), "-")
+		from, _ := strconv.Atoi(start)
+		to := len(data) - 1
+		if end != "" {
+			to, _ = strconv.Atoi(end)
+		}
+		data = data[min(from, len(data)):min(to+1, len(data))]
+	}
+	return objectstorage.GetObjectResponse{
+		Content:       io.NopCloser(bytes.NewReader(data)),
+		ContentLength: ocicommon.Int64(int64(len(data))),
+		ETag:          ocicommon.String(obj.etag),
+		StorageTier:   objectstorage.GetObjectStorageTierEnum(obj.storageTier),
+		OpcMeta:       obj.meta,
+	}, nil
+}
+
+func (f *fakeAPI) HeadObject(ctx context.Context, request objectstorage.HeadObjectRequest) (objectstorage.HeadObjectResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return objectstorage.HeadObjectResponse{}, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	obj, ok := f.objects[*request.ObjectName]
+	if !ok {
+		return objectstorage.HeadObjectResponse{}, notFound("ObjectNotFound")
+	}
+	return objectstorage.HeadObjectResponse{
+		ContentLength:   ocicommon.Int64(int64(len(obj.data))),
+		ContentType:     optional(obj.contentType),
+		ContentEncoding: optional(obj.contentEncoding),
+		ETag:            ocicommon.String(obj.etag),
+		LastModified:    &ocicommon.SDKTime{Time: obj.modified},
+		StorageTier:     objectstorage.HeadObjectStorageTierEnum(obj.storageTier),
+		OpcMeta:         obj.meta,
+	}, nil
+}
+
+func (f *fakeAPI) DeleteObject(ctx context.Context, request objectstorage.DeleteObjectRequest) (objectstorage.DeleteObjectResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return objectstorage.DeleteObjectResponse{}, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.objects[*request.ObjectName]; !ok {
+		return objectstorage.DeleteObjectResponse{}, notFound("ObjectNotFound")
+	}
+	delete(f.objects, *request.ObjectName)
+	return objectstorage.DeleteObjectResponse{}, nil
+}
+
+func (f *fakeAPI) ListObjects(ctx context.Context, request objectstorage.ListObjectsRequest) (objectstorage.ListObjectsResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return objectstorage.ListObjectsResponse{}, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	keys := make([]string, 0, len(f.objects))
+	for key := range f.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	prefix, delimiter, start := deref(request.Prefix), deref(request.Delimiter), deref(request.Start)
+	limit := *request.Limit
+	var page objectstorage.ListObjects
+	seen := map[string]bool{}
+	for _, key := range keys {
+		if !strings.HasPrefix(key, prefix) || key < start {
+			continue
+		}
+		common := ""
+		if delimiter != "" {
+			if i := strings.Index(key[len(prefix):], delimiter); i >= 0 {
+				common = key[:len(prefix)+i+len(delimiter)]
+				if seen[common] {
+					continue
+				}
+			}
+		}
+		if len(page.Objects)+len(page.Prefixes) == limit {
+			page.NextStartWith = ocicommon.String(key)
+			break
+		}
+		if common != "" {
+			seen[common] = true
+			page.Prefixes = append(page.Prefixes, common)
+			continue
+		}
+		obj := f.objects[key]
+		page.Objects = append(page.Objects, objectstorage.ObjectSummary{
+			Name:         ocicommon.String(key),
+			Size:         ocicommon.Int64(int64(len(obj.data))),
+			Etag:         ocicommon.String(obj.etag),
+			TimeModified: &ocicommon.SDKTime{Time: obj.modified},
+			StorageTier:  obj.storageTier,
+		})
+	}
+	return objectstorage.ListObjectsResponse{ListObjects: page}, nil
+}
+
+func (f *fakeAPI) CreateMultipartUpload(ctx context.Context, request objectstorage.CreateMultipartUploadRequest) (objectstorage.CreateMultipartUploadResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return objectstorage.CreateMultipartUploadResponse{}, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.nextID++
+	id := fmt.Sprintf("upload-%d", f.nextID)
+	details := request.CreateMultipartUploadDetails
+	f.uploads[id] = &fakeUpload{key: *details.Object, details: details, parts: map[int][]byte{}}
+	return objectstorage.CreateMultipartUploadResponse{
+		MultipartUpload: objectstorage.MultipartUpload{UploadId: ocicommon.String(id), Object: details.Object},
+	}, nil
+}
+
+func (f *fakeAPI) UploadPart(ctx context.Context, request objectstorage.UploadPartRequest) (objectstorage.UploadPartResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return objectstorage.UploadPartResponse{}, err
+	}
+	data, err := io.ReadAll(request.UploadPartBody)
+	if err != nil {
+		return objectstorage.UploadPartResponse{}, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.partCalls++
+	part := *request.UploadPartNum
+	if part == f.failPart {
+		return objectstorage.UploadPartResponse{}, &fakeServiceError{status: http.StatusInternalServerError, code: "InternalServerError"}
+	}
+	upload, ok := f.uploads[*request.UploadId]
+	if !ok || upload.key != *request.ObjectName {
+		return objectstorage.UploadPartResponse{}, notFound("NoSuchUpload")
+	}
+	if int64(len(data)) != *request.ContentLength {
+		return objectstorage.UploadPartResponse{}, fmt.Errorf("part of %d bytes with content length %d", len(data), *request.ContentLength)
+	}
+	upload.parts[part] = data
+	return objectstorage.UploadPartResponse{ETag: ocicommon.String(fmt.Sprintf("part-%d", part))}, nil
+}
+
+func (f *fakeAPI) CommitMultipartUpload(ctx context.Context, request objectstorage.CommitMultipartUploadRequest) (objectstorage.CommitMultipartUploadResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return objectstorage.CommitMultipartUploadResponse{}, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	upload, ok := f.uploads[*request.UploadId]
+	if !ok || upload.key != *request.ObjectName {
+		return objectstorage.CommitMultipartUploadResponse{}, notFound("NoSuchUpload")
+	}
+	var data []byte
+	for i, part := range request.CommitMultipartUploadDetails.PartsToCommit {
+		if *part.PartNum != i+1 || *part.Etag != fmt.Sprintf("part-%d", *part.PartNum) {
+			return objectstorage.CommitMultipartUploadResponse{}, &fakeServiceError{status: http.StatusBadRequest, code: "InvalidParameter"}
+		}
+		data = append(data, upload.parts[*part.PartNum]...)
+	}
+	details := upload.details
+	etag, err := f.commit(upload.key, &fakeObject{
+		data:            data,
+		contentType:     deref(details.ContentType),
+		contentEncoding: deref(details.ContentEncoding),
+		storageTier:     details.StorageTier,
+		meta:            details.Metadata,
+	}, request.IfMatch)
+	if err != nil {
+		return objectstorage.CommitMultipartUploadResponse{}, err
+	}
+	delete(f.uploads, *request.UploadId)
+	return objectstorage.CommitMultipartUploadResponse{ETag: ocicommon.String(etag)}, nil
+}
+
+func (f *fakeAPI) AbortMultipartUpload(ctx context.Context, request objectstorage.AbortMultipartUploadRequest) (objectstorage.AbortMultipartUploadResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.aborts++
+	if _, ok := f.uploads[*request.UploadId]; !ok {
+		return objectstorage.AbortMultipartUploadResponse{}, notFound("NoSuchUpload")
+	}
+	delete(f.uploads, *request.UploadId)
+	return objectstorage.AbortMultipartUploadResponse{}, nil
+}
+
+func (f *fakeAPI) GetObjectLifecyclePolicy(ctx context.Context, request objectstorage.GetObjectLifecyclePolicyRequest) (objectstorage.GetObjectLifecyclePolicyResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.lifecycle == nil {
+		return objectstorage.GetObjectLifecyclePolicyResponse{}, notFound("LifecyclePolicyNotFound")
+	}
+	rules := append([]objectstorage.ObjectLifecycleRule(nil), f.lifecycle...)
+	return objectstorage.GetObjectLifecyclePolicyResponse{ObjectLifecyclePolicy: objectstorage.ObjectLifecyclePolicy{Items: rules}}, nil
+}
+
+func (f *fakeAPI) PutObjectLifecyclePolicy(ctx context.Context, request objectstorage.PutObjectLifecyclePolicyRequest) (objectstorage.PutObjectLifecyclePolicyResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.lifecycle = append([]objectstorage.ObjectLifecycleRule(nil), request.PutObjectLifecyclePolicyDetails.Items...)
+	return objectstorage.PutObjectLifecyclePolicyResponse{}, nil
+}
+
+func (f *fakeAPI) DeleteObjectLifecyclePolicy(ctx context.Context, request objectstorage.DeleteObjectLifecyclePolicyRequest) (objectstorage.DeleteObjectLifecyclePolicyResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.lifecycle = nil
+	return objectstorage.DeleteObjectLifecyclePolicyResponse{}, nil
+}
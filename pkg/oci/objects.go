@@ -0,0 +1,346 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build ocistorage
+
+package oci
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"strings"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/transport"
+
+	ocicommon "github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/objectstorage"
+)
+
+const (
+	// defaultMaxKeys is the page size of listings, the Object Storage
+	// maximum.
+	defaultMaxKeys = 1000
+
+	// listFields are the object summary fields listings request.
+	listFields = "name,size,etag,timeModified,storageTier"
+)
+
+// objectAttrs are the attributes an object is stored with.
+type objectAttrs struct {
+	contentType     string
+	contentEncoding string
+	storageTier     string
+	meta            map[string]string
+}
+
+// PutWithContext stores an object in the backend with context support.
+func (o *OCI) PutWithContext(ctx context.Context, key string, data io.Reader) error {
+	return o.PutWithMetadata(ctx, key, data, nil)
+}
+
+// PutWithMetadata stores an object with associated metadata. Objects larger
+// than the part size are uploaded in parts.
+func (o *OCI) PutWithMetadata(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) error {
+	_, err := o.PutWithInfo(ctx, key, data, metadata)
+	return err
+}
+
+// PutWithInfo stores an object like PutWithMetadata and returns the ETag
+// Object Storage assigned it, without reading its metadata back.
+func (o *OCI) PutWithInfo(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) (*common.ObjectInfo, error) {
+	if err := common.ValidateKey(key); err != nil {
+		return nil, err
+	}
+	attrs, err := attrsFor(metadata)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := o.timeouts.Context(ctx, transport.OpPut)
+	defer cancel()
+	etag, size, err := o.upload(ctx, key, data, attrs, nil)
+	if err != nil {
+		return nil, translateError(err, key)
+	}
+
+	info := &common.Metadata{Size: size, LastModified: time.Now().UTC(), ETag: etag}
+	if metadata != nil {
+		info.ContentType = metadata.ContentType
+		info.ContentEncoding = metadata.ContentEncoding
+		info.StorageClass = metadata.StorageClass
+		info.Custom = maps.Clone(metadata.Custom)
+	}
+	return &common.ObjectInfo{Key: key, Metadata: info}, nil
+}
+
+// GetWithContext retrieves an object from the backend with context support.
+func (o *OCI) GetWithContext(ctx context.Context, key string) (io.ReadCloser, error) {
+	return o.get(ctx, key, nil)
+}
+
+// GetRange retrieves length bytes of an object starting at offset. A negative
+// length reads to the end of the object.
+func (o *OCI) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	if offset < 0 {
+		return nil, fmt.Errorf("%w: negative range offset %d", common.ErrInvalidArgument, offset)
+	}
+	return o.get(ctx, key, ocicommon.String(common.RangeHeader(offset, length)))
+}
+
+// get opens the object under key, or the byteRange of it when not nil.
+func (o *OCI) get(ctx context.Context, key string, byteRange *string) (io.ReadCloser, error) {
+	if err := common.ValidateKey(key); err != nil {
+		return nil, err
+	}
+	ctx, cancel := o.timeouts.Context(ctx, transport.OpGet)
+	resp, err := o.client.GetObject(ctx, objectstorage.GetObjectRequest{
+		NamespaceName: ocicommon.String(o.namespace),
+		BucketName:    ocicommon.String(o.bucket),
+		ObjectName:    ocicommon.String(key),
+		Range:         byteRange,
+	})
+	if err != nil {
+		cancel()
+		return nil, translateError(err, key)
+	}
+	return transport.CancelOnClose(resp.Content, cancel), nil
+}
+
+// GetMetadata retrieves only the metadata for an object.
+func (o *OCI) GetMetadata(ctx context.Context, key string) (*common.Metadata, error) {
+	if err := common.ValidateKey(key); err != nil {
+		return nil, err
+	}
+	ctx, cancel := o.timeouts.Context(ctx, transport.OpMetadata)
+	defer cancel()
+	resp, err := o.head(ctx, key)
+	if err != nil {
+		return nil, translateError(err, key)
+	}
+	meta := &common.Metadata{
+		ContentType:     deref(resp.ContentType),
+		ContentEncoding: deref(resp.ContentEncoding),
+		ETag:            deref(resp.ETag),
+		StorageClass:    string(resp.StorageTier),
+		Custom:          resp.OpcMeta,
+	}
+	if resp.ContentLength != nil {
+		meta.Size = *resp.ContentLength
+	}
+	if resp.LastModified != nil {
+		meta.LastModified = resp.LastModified.Time
+	}
+	return meta, nil
+}
+
+// UpdateMetadata updates the metadata for an existing object.
+// Matching the other backends, the object's metadata is replaced rather
+// than merged. Object Storage cannot change the metadata of an object in
+// place, so the object is rewritten with the new metadata. The rewrite is
+// conditional on the object's ETag and fails with an error wrapping
+// common.ErrPreconditionFailed if the object changes in the meantime. The
+// storage tier is kept unless metadata names one. A missing object yields
+// an error wrapping common.ErrKeyNotFound.
+func (o *OCI) UpdateMetadata(ctx context.Context, key string, metadata *common.Metadata) error {
+	if err := common.ValidateKey(key); err != nil {
+		return err
+	}
+	attrs, err := attrsFor(metadata)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := o.timeouts.Context(ctx, transport.OpPut)
+	defer cancel()
+	resp, err := o.client.GetObject(ctx, objectstorage.GetObjectRequest{
+		NamespaceName: ocicommon.String(o.namespace),
+		BucketName:    ocicommon.String(o.bucket),
+		ObjectName:    ocicommon.String(key),
+	})
+	if err != nil {
+		return translateError(err, key)
+	}
+	defer func() { _ = resp.Content.Close() }()
+	if attrs.storageTier == "" {
+		attrs.storageTier = string(resp.StorageTier)
+	}
+	_, _, err = o.upload(ctx, key, resp.Content, attrs, resp.ETag)
+	return translateError(err, key)
+}
+
+// DeleteWithContext removes an object from the backend with context support.
+func (o *OCI) DeleteWithContext(ctx context.Context, key string) error {
+	if err := common.ValidateKey(key); err != nil {
+		return err
+	}
+	ctx, cancel := o.timeouts.Context(ctx, transport.OpDelete)
+	defer cancel()
+	_, err := o.client.DeleteObject(ctx, objectstorage.DeleteObjectRequest{
+		NamespaceName: ocicommon.String(o.namespace),
+		BucketName:    ocicommon.String(o.bucket),
+		ObjectName:    ocicommon.String(key),
+	})
+	return translateError(err, key)
+}
+
+// Exists checks if an object exists in the backend.
+// Returns false,nil only for a not-found condition; propagates all other errors.
+func (o *OCI) Exists(ctx context.Context, key string) (bool, error) {
+	if err := common.ValidateKey(key); err != nil {
+		return false, err
+	}
+	ctx, cancel := o.timeouts.Context(ctx, transport.OpMetadata)
+	defer cancel()
+	if _, err := o.head(ctx, key); err != nil {
+		if err = translateError(err, key); errors.Is(err, common.ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (o *OCI) head(ctx context.Context, key string) (objectstorage.HeadObjectResponse, error) {
+	return o.client.HeadObject(ctx, objectstorage.HeadObjectRequest{
+		NamespaceName: ocicommon.String(o.namespace),
+		BucketName:    ocicommon.String(o.bucket),
+		ObjectName:    ocicommon.String(key),
+	})
+}
+
+// ListWithContext returns a list of keys with context support.
+func (o *OCI) ListWithContext(ctx context.Context, prefix string) ([]string, error) {
+	ctx, cancel := o.timeouts.Context(ctx, transport.OpList)
+	defer cancel()
+	var keys []string
+	var start *string
+	for {
+		resp, err := o.list(ctx, prefix, "", start, defaultMaxKeys)
+		if err != nil {
+			return nil, translateError(err, "")
+		}
+		for _, obj := range resp.Objects {
+			keys = append(keys, deref(obj.Name))
+		}
+		if resp.NextStartWith == nil {
+			return keys, nil
+		}
+		start = resp.NextStartWith
+	}
+}
+
+// ListWithOptions returns a paginated list of objects with full metadata.
+// Object Storage only supports "/" as the delimiter.
+func (o *OCI) ListWithOptions(ctx context.Context, opts *common.ListOptions) (*common.ListResult, error) {
+	if opts == nil {
+		opts = &common.ListOptions{}
+	}
+	if opts.Delimiter != "" && opts.Delimiter != "/" {
+		return nil, fmt.Errorf("%w: Object Storage only supports the \"/\" delimiter", common.ErrInvalidArgument)
+	}
+	ctx, cancel := o.timeouts.Context(ctx, transport.OpList)
+	defer cancel()
+	limit := opts.MaxResults
+	if limit <= 0 || limit > defaultMaxKeys {
+		limit = defaultMaxKeys
+	}
+	var start *string
+	if opts.ContinueFrom != "" {
+		start = ocicommon.String(opts.ContinueFrom)
+	}
+	resp, err := o.list(ctx, opts.Prefix, opts.Delimiter, start, limit)
+	if err != nil {
+		return nil, translateError(err, "")
+	}
+
+	result := &common.ListResult{
+		Objects:        make([]*common.ObjectInfo, 0, len(resp.Objects)),
+		CommonPrefixes: resp.Prefixes,
+		NextToken:      deref(resp.NextStartWith),
+		Truncated:      resp.NextStartWith != nil,
+	}
+	if result.CommonPrefixes == nil {
+		result.CommonPrefixes = []string{}
+	}
+	for _, obj := range resp.Objects {
+		meta := &common.Metadata{ETag: deref(obj.Etag), StorageClass: string(obj.StorageTier)}
+		if obj.Size != nil {
+			meta.Size = *obj.Size
+		}
+		if obj.TimeModified != nil {
+			meta.LastModified = obj.TimeModified.Time
+		}
+		result.Objects = append(result.Objects, &common.ObjectInfo{Key: deref(obj.Name), Metadata: meta})
+	}
+	return result, nil
+}
+
+// list returns one page of the bucket listing starting at start, which is
+// inclusive like the NextStartWith the previous page returned.
+func (o *OCI) list(ctx context.Context, prefix, delimiter string, start *string, limit int) (objectstorage.ListObjectsResponse, error) {
+	request := objectstorage.ListObjectsRequest{
+		NamespaceName: ocicommon.String(o.namespace),
+		BucketName:    ocicommon.String(o.bucket),
+		Prefix:        ocicommon.String(prefix),
+		Start:         start,
+		Limit:         ocicommon.Int(limit),
+		Fields:        ocicommon.String(listFields),
+	}
+	if delimiter != "" {
+		request.Delimiter = ocicommon.String(delimiter)
+	}
+	return o.client.ListObjects(ctx, request)
+}
+
+// attrsFor returns the object attributes that store metadata. Custom
+// metadata keys are lower-cased because Object Storage does not preserve
+// their case.
+func attrsFor(metadata *common.Metadata) (*objectAttrs, error) {
+	attrs := &objectAttrs{}
+	if metadata == nil {
+		return attrs, nil
+	}
+	tier, err := common.NormalizeStorageClass(metadata.StorageClass, storageClasses)
+	if err != nil {
+		return nil, err
+	}
+	attrs.contentType = metadata.ContentType
+	attrs.contentEncoding = metadata.ContentEncoding
+	attrs.storageTier = tier
+	if len(metadata.Custom) > 0 {
+		attrs.meta = make(map[string]string, len(metadata.Custom))
+		for k, v := range metadata.Custom {
+			attrs.meta[strings.ToLower(k)] = v
+		}
+	}
+	return attrs, nil
+}
+
+// deref returns the value of s, or "" when it is nil.
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// optional returns a pointer to s, or nil when it is empty.
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,420 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build ocistorage
+
+package oci
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/transport"
+
+	ocicommon "github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/objectstorage"
+)
+
+// Constants
+const (
+	actionDelete     = "delete"
+	actionArchive    = "archive"
+	actionTransition = common.ActionTransition
+)
+
+// Lifecycle rule actions.
+const (
+	ruleActionDelete           = "DELETE"
+	ruleActionArchive          = "ARCHIVE"
+	ruleActionInfrequentAccess = "INFREQUENT_ACCESS"
+)
+
+const (
+	// DefaultPartSize is the size of the parts objects are uploaded in.
+	// Objects that fit in one part are uploaded with a single request.
+	DefaultPartSize = 16 * 1024 * 1024
+
+	// MinPartSize is the smallest part Object Storage accepts, except for
+	// the last.
+	MinPartSize = 10 * 1024 * 1024
+
+	// MaxPartSize is the largest part Object Storage accepts.
+	MaxPartSize = 50 * 1024 * 1024 * 1024
+)
+
+// Storage tiers objects can be stored in.
+const (
+	storageTierStandard         = string(objectstorage.StorageTierStandard)
+	storageTierInfrequentAccess = string(objectstorage.StorageTierInfrequentAccess)
+	storageTierArchive          = string(objectstorage.StorageTierArchive)
+)
+
+// storageClasses are the storage tiers that can be selected per object or
+// as a lifecycle transition target.
+var storageClasses = []string{storageTierStandard, storageTierInfrequentAccess, storageTierArchive}
+
+// objectStorageAPI is the subset of the Object Storage client the backend
+// uses, so tests can substitute an in-memory fake.
+type objectStorageAPI interface {
+	PutObject(ctx context.Context, request objectstorage.PutObjectRequest) (objectstorage.PutObjectResponse, error)
+	GetObject(ctx context.Context, request objectstorage.GetObjectRequest) (objectstorage.GetObjectResponse, error)
+	HeadObject(ctx context.Context, request objectstorage.HeadObjectRequest) (objectstorage.HeadObjectResponse, error)
+	DeleteObject(ctx context.Context, request objectstorage.DeleteObjectRequest) (objectstorage.DeleteObjectResponse, error)
+	ListObjects(ctx context.Context, request objectstorage.ListObjectsRequest) (objectstorage.ListObjectsResponse, error)
+
+	CreateMultipartUpload(ctx context.Context, request objectstorage.CreateMultipartUploadRequest) (objectstorage.CreateMultipartUploadResponse, error)
+	UploadPart(ctx context.Context, request objectstorage.UploadPartRequest) (objectstorage.UploadPartResponse, error)
+	CommitMultipartUpload(ctx context.Context, request objectstorage.CommitMultipartUploadRequest) (objectstorage.CommitMultipartUploadResponse, error)
+	AbortMultipartUpload(ctx context.Context, request objectstorage.AbortMultipartUploadRequest) (objectstorage.AbortMultipartUploadResponse, error)
+
+	GetObjectLifecyclePolicy(ctx context.Context, request objectstorage.GetObjectLifecyclePolicyRequest) (objectstorage.GetObjectLifecyclePolicyResponse, error)
+	PutObjectLifecyclePolicy(ctx context.Context, request objectstorage.PutObjectLifecyclePolicyRequest) (objectstorage.PutObjectLifecyclePolicyResponse, error)
+	DeleteObjectLifecyclePolicy(ctx context.Context, request objectstorage.DeleteObjectLifecyclePolicyRequest) (objectstorage.DeleteObjectLifecyclePolicyResponse, error)
+}
+
+// OCI is a storage backend that stores files in OCI Object Storage.
+type OCI struct {
+	client             objectStorageAPI
+	namespace          string
+	bucket             string
+	partSize           int64
+	timeouts           *transport.Timeouts
+	policiesMutex      sync.RWMutex
+	replicationManager common.ReplicationManager
+}
+
+// New creates a new OCI Object Storage backend.
+func New() common.Storage {
+	return &OCI{}
+}
+
+// Configure sets up the backend with the necessary settings.
+// Required settings:
+//   - bucket: the bucket name
+//
+// Optional settings:
+//   - namespace: the Object Storage namespace; looked up with the
+//     credentials when not set
+//   - region: the region identifier (e.g., "us-ashburn-1"), which selects
+//     the regional endpoint; defaults to the credentials' region
+//   - endpoint: overrides the regional endpoint (e.g., a dedicated or
+//     private endpoint)
+//   - credentials: default, config-file, api-key, instance-principal or
+//     resource-principal, see CredentialsDefault and the other constants;
+//     defaults to api-key when tenancy is set and default otherwise
+//   - configFile, profile: the configuration file for config-file
+//     credentials
+//   - tenancy, user, fingerprint, privateKey or privateKeyFile: the API
+//     signing key for api-key credentials
+//   - privateKeyPassphrase: the passphrase of an encrypted private key
+//   - partSize: multipart upload part size in bytes (default 16 MiB)
+//   - http*: HTTP transport tuning, see the transport package
+//   - timeout, getTimeout, ...: per-operation timeouts, see the transport
+//     package
+func (o *OCI) Configure(settings map[string]string) error {
+	o.bucket = settings["bucket"]
+	if o.bucket == "" {
+		return common.ErrBucketNotSet
+	}
+	partSize, err := parsePartSize(settings["partSize"])
+	if err != nil {
+		return err
+	}
+	o.partSize = partSize
+	timeouts, err := transport.ParseTimeouts(settings)
+	if err != nil {
+		return err
+	}
+	o.timeouts = timeouts
+
+	httpCfg, err := transport.ParseSettings(settings)
+	if err != nil {
+		return err
+	}
+	provider, err := configurationProvider(settings)
+	if err != nil {
+		return err
+	}
+	client, err := objectstorage.NewObjectStorageClientWithConfigurationProvider(provider)
+	if err != nil {
+		return err
+	}
+	if region := settings["region"]; region != "" {
+		client.SetRegion(region)
+	}
+	if endpoint := settings["endpoint"]; endpoint != "" {
+		client.Host = endpoint
+	}
+	identity := settings["credentials"] + "|" + settings["tenancy"] + "|" + settings["user"] + "|" +
+		settings["fingerprint"] + "|" + settings["configFile"] + "|" + settings["profile"] + "|" + client.Host
+	client.HTTPClient = transport.Default.Client("oci", identity, httpCfg)
+
+	o.namespace = settings["namespace"]
+	if o.namespace == "" {
+		ctx, cancel := o.timeouts.Context(context.Background(), transport.OpMetadata)
+		defer cancel()
+		resp, err := client.GetNamespace(ctx, objectstorage.GetNamespaceRequest{})
+		if err != nil {
+			return fmt.Errorf("failed to look up the Object Storage namespace: %w", translateError(err, ""))
+		}
+		o.namespace = *resp.Value
+	}
+	o.client = &client
+	return nil
+}
+
+// parsePartSize parses the partSize setting.
+func parsePartSize(value string) (int64, error) {
+	if value == "" {
+		return DefaultPartSize, nil
+	}
+	size, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || size < MinPartSize || size > MaxPartSize {
+		return 0, fmt.Errorf("%w: partSize must be between %d and %d bytes", common.ErrInvalidArgument, MinPartSize, MaxPartSize)
+	}
+	return size, nil
+}
+
+// Put stores an object in the backend.
+func (o *OCI) Put(key string, data io.Reader) error {
+	return o.PutWithContext(context.Background(), key, data)
+}
+
+// Get retrieves an object from the backend.
+func (o *OCI) Get(key string) (io.ReadCloser, error) {
+	return o.GetWithContext(context.Background(), key)
+}
+
+// Delete removes an object from the backend.
+func (o *OCI) Delete(key string) error {
+	return o.DeleteWithContext(context.Background(), key)
+}
+
+// List returns a list of keys that start with the given prefix.
+func (o *OCI) List(prefix string) ([]string, error) {
+	return o.ListWithContext(context.Background(), prefix)
+}
+
+// Archive copies an object to another backend for archival.
+func (o *OCI) Archive(key string, destination common.Archiver) error {
+	if destination == nil {
+		return common.ErrArchiveDestinationNil
+	}
+	rc, err := o.Get(key)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rc.Close() }()
+
+	return destination.Put(key, rc)
+}
+
+// AddPolicy adds a new lifecycle policy by configuring the bucket's object
+// lifecycle policy. The policy ID becomes the rule name; a rule with the
+// same name is replaced. Transitions are supported to the InfrequentAccess
+// and Archive tiers.
+func (o *OCI) AddPolicy(policy common.LifecyclePolicy) error {
+	if policy.ID == "" {
+		return common.ErrInvalidPolicy
+	}
+	var action string
+	switch policy.Action {
+	case actionDelete:
+		action = ruleActionDelete
+	case actionArchive:
+		action = ruleActionArchive
+	case actionTransition:
+		class, err := common.NormalizeStorageClass(policy.StorageClass, storageClasses)
+		if err != nil {
+			return err
+		}
+		switch class {
+		case storageTierInfrequentAccess:
+			action = ruleActionInfrequentAccess
+		case storageTierArchive:
+			action = ruleActionArchive
+		default:
+			return fmt.Errorf("%w: transition policy requires the %s or %s storage class",
+				common.ErrInvalidArgument, storageTierInfrequentAccess, storageTierArchive)
+		}
+	default:
+		return common.ErrInvalidPolicy
+	}
+
+	o.policiesMutex.Lock()
+	defer o.policiesMutex.Unlock()
+
+	ctx, cancel := o.timeouts.Context(context.Background(), transport.OpMetadata)
+	defer cancel()
+	existing, err := o.lifecycleRules(ctx)
+	if err != nil {
+		return err
+	}
+	rules := make([]objectstorage.ObjectLifecycleRule, 0, len(existing)+1)
+	for _, rule := range existing {
+		if deref(rule.Name) != policy.ID {
+			rules = append(rules, rule)
+		}
+	}
+
+	// Convert retention duration to days (minimum 1 day)
+	days := int64(policy.Retention.Hours() / 24)
+	if days < 1 {
+		days = 1
+	}
+	rule := objectstorage.ObjectLifecycleRule{
+		Name:       ocicommon.String(policy.ID),
+		Action:     ocicommon.String(action),
+		TimeAmount: ocicommon.Int64(days),
+		TimeUnit:   objectstorage.ObjectLifecycleRuleTimeUnitDays,
+		IsEnabled:  ocicommon.Bool(true),
+		Target:     ocicommon.String("objects"),
+	}
+	if policy.Prefix != "" {
+		rule.ObjectNameFilter = &objectstorage.ObjectNameFilter{InclusionPrefixes: []string{policy.Prefix}}
+	}
+	rules = append(rules, rule)
+
+	return o.putLifecycleRules(ctx, rules)
+}
+
+// RemovePolicy removes a lifecycle policy by updating the bucket's object
+// lifecycle policy.
+func (o *OCI) RemovePolicy(id string) error {
+	o.policiesMutex.Lock()
+	defer o.policiesMutex.Unlock()
+
+	ctx, cancel := o.timeouts.Context(context.Background(), transport.OpMetadata)
+	defer cancel()
+	existing, err := o.lifecycleRules(ctx)
+	if err != nil {
+		return err
+	}
+	rules := make([]objectstorage.ObjectLifecycleRule, 0, len(existing))
+	for _, rule := range existing {
+		if deref(rule.Name) != id {
+			rules = append(rules, rule)
+		}
+	}
+	if len(rules) == len(existing) {
+		return nil
+	}
+	return o.putLifecycleRules(ctx, rules)
+}
+
+// GetPolicies returns all lifecycle policies by fetching the bucket's object
+// lifecycle policy. Disabled rules, rules for multipart uploads or previous
+// versions and rules with exclusions or patterns are skipped.
+func (o *OCI) GetPolicies() ([]common.LifecyclePolicy, error) {
+	o.policiesMutex.RLock()
+	defer o.policiesMutex.RUnlock()
+
+	ctx, cancel := o.timeouts.Context(context.Background(), transport.OpMetadata)
+	defer cancel()
+	rules, err := o.lifecycleRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	policies := make([]common.LifecyclePolicy, 0, len(rules))
+	for _, rule := range rules {
+		if rule.IsEnabled == nil || !*rule.IsEnabled || rule.TimeAmount == nil ||
+			(rule.Target != nil && *rule.Target != "objects") {
+			continue
+		}
+		policy := common.LifecyclePolicy{ID: deref(rule.Name)}
+		if filter := rule.ObjectNameFilter; filter != nil {
+			if len(filter.InclusionPrefixes) > 1 || len(filter.InclusionPatterns) > 0 || len(filter.ExclusionPatterns) > 0 {
+				continue
+			}
+			if len(filter.InclusionPrefixes) == 1 {
+				policy.Prefix = filter.InclusionPrefixes[0]
+			}
+		}
+		days := *rule.TimeAmount
+		if rule.TimeUnit == objectstorage.ObjectLifecycleRuleTimeUnitYears {
+			days *= 365
+		}
+		policy.Retention = time.Duration(days) * 24 * time.Hour
+		switch deref(rule.Action) {
+		case ruleActionDelete:
+			policy.Action = actionDelete
+		case ruleActionArchive:
+			policy.Action = actionArchive
+		case ruleActionInfrequentAccess:
+			policy.Action = actionTransition
+			policy.StorageClass = storageTierInfrequentAccess
+		default:
+			// Skip rules we don't understand
+			continue
+		}
+		policies = append(policies, policy)
+	}
+	return policies, nil
+}
+
+// lifecycleRules returns the bucket's lifecycle rules; a bucket without a
+// lifecycle policy has none.
+func (o *OCI) lifecycleRules(ctx context.Context) ([]objectstorage.ObjectLifecycleRule, error) {
+	resp, err := o.client.GetObjectLifecyclePolicy(ctx, objectstorage.GetObjectLifecyclePolicyRequest{
+		NamespaceName: ocicommon.String(o.namespace),
+		BucketName:    ocicommon.String(o.bucket),
+	})
+	if err != nil {
+		if err = translateError(err, ""); errors.Is(err, common.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return resp.Items, nil
+}
+
+// putLifecycleRules replaces the bucket's lifecycle rules, deleting the
+// policy when there are none left.
+func (o *OCI) putLifecycleRules(ctx context.Context, rules []objectstorage.ObjectLifecycleRule) error {
+	if len(rules) == 0 {
+		_, err := o.client.DeleteObjectLifecyclePolicy(ctx, objectstorage.DeleteObjectLifecyclePolicyRequest{
+			NamespaceName: ocicommon.String(o.namespace),
+			BucketName:    ocicommon.String(o.bucket),
+		})
+		return translateError(err, "")
+	}
+	_, err := o.client.PutObjectLifecyclePolicy(ctx, objectstorage.PutObjectLifecyclePolicyRequest{
+		NamespaceName:                   ocicommon.String(o.namespace),
+		BucketName:                      ocicommon.String(o.bucket),
+		PutObjectLifecyclePolicyDetails: objectstorage.PutObjectLifecyclePolicyDetails{Items: rules},
+	})
+	return translateError(err, "")
+}
+
+// GetReplicationManager returns the replication manager for this backend.
+// This method implements the common.ReplicationCapable interface.
+func (o *OCI) GetReplicationManager() (common.ReplicationManager, error) {
+	if o.replicationManager == nil {
+		return nil, common.ErrReplicationNotSupported
+	}
+	return o.replicationManager, nil
+}
+
+// SetReplicationManager allows manually setting a replication manager.
+// This is useful for testing or when you want to share a replication manager
+// across multiple backends.
+func (o *OCI) SetReplicationManager(rm common.ReplicationManager) {
+	o.replicationManager = rm
+}
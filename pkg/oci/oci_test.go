@@ -0,0 +1,358 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build ocistorage
+
+package oci
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/storagetest"
+
+	ocicommon "github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/objectstorage"
+)
+
+func TestOCI_Conformance(t *testing.T) {
+	storagetest.TestStorage(t, func(t *testing.T) common.Storage {
+		o, _ := newTestOCI()
+		return o
+	})
+}
+
+func TestOCI_ConformanceMultipart(t *testing.T) {
+	storagetest.TestStorage(t, func(t *testing.T) common.Storage {
+		o, _ := newTestOCI()
+		o.partSize = 200 * 1024
+		return o
+	})
+}
+
+// content returns the data read from rc, or the error.
+func content(rc io.ReadCloser, err error) string {
+	if err != nil {
+		return "error: " + err.Error()
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return "error: " + err.Error()
+	}
+	return string(data)
+}
+
+func TestOCI_SmallObjectUsesSinglePut(t *testing.T) {
+	o, api := newTestOCI()
+	if err := o.Put("small", strings.NewReader("hello")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if api.putCalls != 1 || api.partCalls != 0 {
+		t.Errorf("puts = %d, parts = %d, want one single put", api.putCalls, api.partCalls)
+	}
+}
+
+func TestOCI_MultipartUpload(t *testing.T) {
+	o, api := newTestOCI()
+	o.partSize = 4
+	meta := &common.Metadata{
+		ContentType:  "text/plain",
+		StorageClass: "infrequentaccess",
+		Custom:       map[string]string{"Owner": "alice"},
+	}
+	if err := o.PutWithMetadata(context.Background(), "big", strings.NewReader("0123456789"), meta); err != nil {
+		t.Fatalf("PutWithMetadata() error = %v", err)
+	}
+	if api.partCalls != 3 || api.putCalls != 0 {
+		t.Errorf("parts = %d, puts = %d, want 3 parts", api.partCalls, api.putCalls)
+	}
+	if got := content(o.GetRange(context.Background(), "big", 3, 4)); got != "3456" {
+		t.Errorf("GetRange() = %q, want 3456", got)
+	}
+	got, err := o.GetMetadata(context.Background(), "big")
+	if err != nil {
+		t.Fatalf("GetMetadata() error = %v", err)
+	}
+	if got.Size != 10 || got.ContentType != "text/plain" || got.StorageClass != "InfrequentAccess" || got.Custom["owner"] != "alice" {
+		t.Errorf("metadata = %+v", got)
+	}
+}
+
+func TestOCI_PutWithInfo(t *testing.T) {
+	ctx := context.Background()
+	o, _ := newTestOCI()
+	o.partSize = 4
+	for _, data := range []string{"abc", "0123456789"} {
+		info, err := o.PutWithInfo(ctx, "obj", strings.NewReader(data), &common.Metadata{ContentType: "text/plain"})
+		if err != nil {
+			t.Fatalf("PutWithInfo(%q) error = %v", data, err)
+		}
+		stored, err := o.GetMetadata(ctx, "obj")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Key != "obj" || info.Metadata.ETag == "" || info.Metadata.ETag != stored.ETag || info.Metadata.Size != int64(len(data)) || info.Metadata.ContentType != "text/plain" {
+			t.Errorf("PutWithInfo(%q) = %+v, stored %+v", data, info.Metadata, stored)
+		}
+	}
+}
+
+func TestOCI_MultipartExactPartSize(t *testing.T) {
+	o, api := newTestOCI()
+	o.partSize = 5
+	if err := o.Put("even", strings.NewReader("0123456789")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if api.partCalls != 2 {
+		t.Errorf("parts = %d, want 2 with no empty trailing part", api.partCalls)
+	}
+	if got := content(o.Get("even")); got != "0123456789" {
+		t.Errorf("Get() = %q", got)
+	}
+}
+
+func TestOCI_MultipartFailureAborts(t *testing.T) {
+	o, api := newTestOCI()
+	o.partSize = 4
+	api.failPart = 2
+	err := o.Put("big", strings.NewReader("0123456789"))
+	if !errors.Is(err, common.ErrBackendUnavailable) {
+		t.Fatalf("Put() error = %v, want ErrBackendUnavailable", err)
+	}
+	if api.aborts != 1 || len(api.uploads) != 0 {
+		t.Errorf("aborts = %d, uploads left = %d, want the upload aborted", api.aborts, len(api.uploads))
+	}
+	if exists, _ := o.Exists(context.Background(), "big"); exists {
+		t.Error("failed upload left an object behind")
+	}
+}
+
+func TestOCI_MultipartReadErrorAborts(t *testing.T) {
+	o, api := newTestOCI()
+	o.partSize = 4
+	errRead := errors.New("read failed")
+	data := io.MultiReader(strings.NewReader("01234567"), iotestErrReader{errRead})
+	if err := o.Put("big", data); !errors.Is(err, errRead) {
+		t.Fatalf("Put() error = %v, want the read error", err)
+	}
+	if api.aborts != 1 {
+		t.Errorf("aborts = %d, want 1", api.aborts)
+	}
+}
+
+type iotestErrReader struct{ err error }
+
+func (r iotestErrReader) Read([]byte) (int, error) { return 0, r.err }
+
+func TestOCI_PutRejectsUnknownStorageClass(t *testing.T) {
+	o, _ := newTestOCI()
+	err := o.PutWithMetadata(context.Background(), "k", strings.NewReader("x"), &common.Metadata{StorageClass: "GLACIER"})
+	if !errors.Is(err, common.ErrInvalidArgument) {
+		t.Errorf("PutWithMetadata() error = %v, want ErrInvalidArgument", err)
+	}
+}
+
+func TestOCI_ListRejectsOtherDelimiters(t *testing.T) {
+	o, _ := newTestOCI()
+	_, err := o.ListWithOptions(context.Background(), &common.ListOptions{Delimiter: "-"})
+	if !errors.Is(err, common.ErrInvalidArgument) {
+		t.Errorf("ListWithOptions() error = %v, want ErrInvalidArgument", err)
+	}
+}
+
+func TestOCI_UpdateMetadataKeepsStorageTier(t *testing.T) {
+	o, _ := newTestOCI()
+	ctx := context.Background()
+	if err := o.PutWithMetadata(ctx, "k", strings.NewReader("data"), &common.Metadata{StorageClass: "Archive"}); err != nil {
+		t.Fatalf("PutWithMetadata() error = %v", err)
+	}
+	if err := o.UpdateMetadata(ctx, "k", &common.Metadata{ContentType: "text/plain"}); err != nil {
+		t.Fatalf("UpdateMetadata() error = %v", err)
+	}
+	meta, err := o.GetMetadata(ctx, "k")
+	if err != nil {
+		t.Fatalf("GetMetadata() error = %v", err)
+	}
+	if meta.StorageClass != "Archive" || meta.ContentType != "text/plain" {
+		t.Errorf("metadata = %+v, want the storage tier kept", meta)
+	}
+	if got := content(o.Get("k")); got != "data" {
+		t.Errorf("Get() = %q, want the data unchanged", got)
+	}
+}
+
+func TestOCI_UpdateMetadataLargeObject(t *testing.T) {
+	o, api := newTestOCI()
+	o.partSize = 4
+	ctx := context.Background()
+	if err := o.Put("large", strings.NewReader("0123456789")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	err := o.UpdateMetadata(ctx, "large", &common.Metadata{Custom: map[string]string{"reviewed": "yes"}})
+	if err != nil {
+		t.Fatalf("UpdateMetadata() error = %v", err)
+	}
+	if api.partCalls != 6 {
+		t.Errorf("parts = %d, want the object rewritten in parts", api.partCalls)
+	}
+	if got := content(o.Get("large")); got != "0123456789" {
+		t.Errorf("Get() = %q, want the data unchanged", got)
+	}
+	meta, err := o.GetMetadata(ctx, "large")
+	if err != nil || meta.Custom["reviewed"] != "yes" {
+		t.Errorf("GetMetadata() = %+v, %v", meta, err)
+	}
+}
+
+func TestOCI_UpdateMetadataConcurrentWrite(t *testing.T) {
+	o, api := newTestOCI()
+	ctx := context.Background()
+	if err := o.Put("k", strings.NewReader("old")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	// Another writer replaces the object while its metadata is updated.
+	api.beforeCommit = func() {
+		if err := o.Put("k", strings.NewReader("new")); err != nil {
+			t.Errorf("concurrent Put() error = %v", err)
+		}
+	}
+	err := o.UpdateMetadata(ctx, "k", &common.Metadata{ContentType: "text/plain"})
+	if !errors.Is(err, common.ErrPreconditionFailed) {
+		t.Fatalf("UpdateMetadata() error = %v, want ErrPreconditionFailed", err)
+	}
+	if got := content(o.Get("k")); got != "new" {
+		t.Errorf("Get() = %q, want the concurrent write kept", got)
+	}
+}
+
+func TestOCI_UpdateMetadataMissing(t *testing.T) {
+	o, _ := newTestOCI()
+	if err := o.UpdateMetadata(context.Background(), "missing", nil); !errors.Is(err, common.ErrKeyNotFound) {
+		t.Errorf("UpdateMetadata() error = %v, want ErrKeyNotFound", err)
+	}
+}
+
+func TestOCI_ArchiveNilDestination(t *testing.T) {
+	o, _ := newTestOCI()
+	if err := o.Archive("k", nil); !errors.Is(err, common.ErrArchiveDestinationNil) {
+		t.Errorf("Archive(nil) error = %v", err)
+	}
+}
+
+func TestOCI_Policies(t *testing.T) {
+	o, api := newTestOCI()
+
+	policies, err := o.GetPolicies()
+	if err != nil || len(policies) != 0 {
+		t.Fatalf("GetPolicies() without lifecycle = %v, %v, want none", policies, err)
+	}
+	if err := o.AddPolicy(common.LifecyclePolicy{ID: "expire", Prefix: "tmp/", Retention: 48 * time.Hour, Action: "delete"}); err != nil {
+		t.Fatalf("AddPolicy(delete) error = %v", err)
+	}
+	if err := o.AddPolicy(common.LifecyclePolicy{ID: "cold", Prefix: "logs/", Retention: time.Hour, Action: "archive"}); err != nil {
+		t.Fatalf("AddPolicy(archive) error = %v", err)
+	}
+	err = o.AddPolicy(common.LifecyclePolicy{ID: "ia", Retention: 240 * time.Hour, Action: "transition", StorageClass: "infrequentaccess"})
+	if err != nil {
+		t.Fatalf("AddPolicy(transition) error = %v", err)
+	}
+	// Replacing a rule keeps one rule per ID.
+	if err := o.AddPolicy(common.LifecyclePolicy{ID: "expire", Prefix: "tmp/", Retention: 72 * time.Hour, Action: "delete"}); err != nil {
+		t.Fatalf("AddPolicy(replace) error = %v", err)
+	}
+
+	policies, err = o.GetPolicies()
+	if err != nil {
+		t.Fatalf("GetPolicies() error = %v", err)
+	}
+	byID := map[string]common.LifecyclePolicy{}
+	for _, p := range policies {
+		byID[p.ID] = p
+	}
+	if len(byID) != 3 {
+		t.Fatalf("policies = %+v, want 3", policies)
+	}
+	if p := byID["expire"]; p.Action != "delete" || p.Retention != 72*time.Hour || p.Prefix != "tmp/" {
+		t.Errorf("expire = %+v", p)
+	}
+	if p := byID["cold"]; p.Action != "archive" || p.Retention != 24*time.Hour {
+		t.Errorf("cold = %+v, want archive after the 1 day minimum", p)
+	}
+	if p := byID["ia"]; p.Action != "transition" || p.StorageClass != "InfrequentAccess" {
+		t.Errorf("ia = %+v", p)
+	}
+
+	for _, id := range []string{"expire", "cold", "unknown"} {
+		if err := o.RemovePolicy(id); err != nil {
+			t.Fatalf("RemovePolicy(%s) error = %v", id, err)
+		}
+	}
+	if len(api.lifecycle) != 1 {
+		t.Errorf("rules after removal = %+v, want 1", api.lifecycle)
+	}
+	if err := o.RemovePolicy("ia"); err != nil {
+		t.Fatalf("RemovePolicy(last) error = %v", err)
+	}
+	if api.lifecycle != nil {
+		t.Errorf("lifecycle = %+v, want deleted", api.lifecycle)
+	}
+}
+
+func TestOCI_AddPolicyValidation(t *testing.T) {
+	o, _ := newTestOCI()
+	for _, policy := range []common.LifecyclePolicy{
+		{Action: "delete"},
+		{ID: "x", Action: "shred"},
+	} {
+		if err := o.AddPolicy(policy); !errors.Is(err, common.ErrInvalidPolicy) {
+			t.Errorf("AddPolicy(%+v) error = %v, want ErrInvalidPolicy", policy, err)
+		}
+	}
+	for _, class := range []string{"", "Standard", "GLACIER"} {
+		err := o.AddPolicy(common.LifecyclePolicy{ID: "x", Action: "transition", StorageClass: class})
+		if !errors.Is(err, common.ErrInvalidArgument) {
+			t.Errorf("AddPolicy(transition %q) error = %v, want ErrInvalidArgument", class, err)
+		}
+	}
+}
+
+func TestOCI_GetPoliciesSkipsUnknownRules(t *testing.T) {
+	o, api := newTestOCI()
+	api.lifecycle = []objectstorage.ObjectLifecycleRule{
+		{Name: ocicommon.String("off"), Action: ocicommon.String("DELETE"), TimeAmount: ocicommon.Int64(1),
+			TimeUnit: objectstorage.ObjectLifecycleRuleTimeUnitDays, IsEnabled: ocicommon.Bool(false)},
+		{Name: ocicommon.String("uploads"), Action: ocicommon.String("ABORT"), TimeAmount: ocicommon.Int64(1),
+			TimeUnit: objectstorage.ObjectLifecycleRuleTimeUnitDays, IsEnabled: ocicommon.Bool(true),
+			Target: ocicommon.String("multipart-uploads")},
+		{Name: ocicommon.String("pattern"), Action: ocicommon.String("DELETE"), TimeAmount: ocicommon.Int64(1),
+			TimeUnit: objectstorage.ObjectLifecycleRuleTimeUnitDays, IsEnabled: ocicommon.Bool(true),
+			ObjectNameFilter: &objectstorage.ObjectNameFilter{InclusionPatterns: []string{"*.tmp"}}},
+	}
+	policies, err := o.GetPolicies()
+	if err != nil || len(policies) != 0 {
+		t.Errorf("GetPolicies() = %+v, %v, want none", policies, err)
+	}
+}
+
+func TestOCI_ReplicationManager(t *testing.T) {
+	o, _ := newTestOCI()
+	if _, err := o.GetReplicationManager(); !errors.Is(err, common.ErrReplicationNotSupported) {
+		t.Errorf("GetReplicationManager() error = %v", err)
+	}
+}
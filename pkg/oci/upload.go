@@ -0,0 +1,145 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build ocistorage
+
+package oci
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+
+	ocicommon "github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/objectstorage"
+)
+
+const (
+	// maxParts is the most parts a multipart upload may have.
+	maxParts = 10000
+
+	// abortTimeout bounds the request that aborts a failed multipart
+	// upload, which runs even when the upload's context was canceled.
+	abortTimeout = 30 * time.Second
+)
+
+// upload stores data under key, if ifMatch is not nil only while the
+// object's ETag matches it, and returns the stored object's ETag and size. Data that fits in one part is stored with a
+// single request. Larger data is uploaded in parts of o.partSize, each
+// buffered in memory because Object Storage requires the length of every
+// request body up front, and the upload is aborted if any part fails so no
+// uncommitted parts are left behind.
+func (o *OCI) upload(ctx context.Context, key string, data io.Reader, attrs *objectAttrs, ifMatch *string) (string, int64, error) {
+	buf := make([]byte, o.partSize)
+	n, err := io.ReadFull(data, buf)
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		resp, err := o.client.PutObject(ctx, objectstorage.PutObjectRequest{
+			NamespaceName:   ocicommon.String(o.namespace),
+			BucketName:      ocicommon.String(o.bucket),
+			ObjectName:      ocicommon.String(key),
+			ContentLength:   ocicommon.Int64(int64(n)),
+			PutObjectBody:   io.NopCloser(bytes.NewReader(buf[:n])),
+			ContentType:     optional(attrs.contentType),
+			ContentEncoding: optional(attrs.contentEncoding),
+			StorageTier:     objectstorage.PutObjectStorageTierEnum(attrs.storageTier),
+			OpcMeta:         attrs.meta,
+			IfMatch:         ifMatch,
+		})
+		if err != nil {
+			return "", 0, err
+		}
+		return deref(resp.ETag), int64(n), nil
+	}
+	if err != nil {
+		return "", 0, err
+	}
+
+	created, err := o.client.CreateMultipartUpload(ctx, objectstorage.CreateMultipartUploadRequest{
+		NamespaceName: ocicommon.String(o.namespace),
+		BucketName:    ocicommon.String(o.bucket),
+		CreateMultipartUploadDetails: objectstorage.CreateMultipartUploadDetails{
+			Object:          ocicommon.String(key),
+			ContentType:     optional(attrs.contentType),
+			ContentEncoding: optional(attrs.contentEncoding),
+			StorageTier:     objectstorage.StorageTierEnum(attrs.storageTier),
+			Metadata:        attrs.meta,
+		},
+	})
+	if err != nil {
+		return "", 0, err
+	}
+	uploadID := created.UploadId
+	var parts []objectstorage.CommitMultipartUploadPartDetails
+	var size int64
+	for number := 1; n > 0; number++ {
+		if number > maxParts {
+			err = fmt.Errorf("%w: object exceeds %d parts of %d bytes", common.ErrInvalidArgument, maxParts, o.partSize)
+			break
+		}
+		var resp objectstorage.UploadPartResponse
+		resp, err = o.client.UploadPart(ctx, objectstorage.UploadPartRequest{
+			NamespaceName:  ocicommon.String(o.namespace),
+			BucketName:     ocicommon.String(o.bucket),
+			ObjectName:     ocicommon.String(key),
+			UploadId:       uploadID,
+			UploadPartNum:  ocicommon.Int(number),
+			ContentLength:  ocicommon.Int64(int64(n)),
+			UploadPartBody: io.NopCloser(bytes.NewReader(buf[:n])),
+		})
+		if err != nil {
+			break
+		}
+		parts = append(parts, objectstorage.CommitMultipartUploadPartDetails{PartNum: ocicommon.Int(number), Etag: resp.ETag})
+		size += int64(n)
+		if n, err = io.ReadFull(data, buf); errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			err = nil
+		} else if err != nil {
+			break
+		}
+	}
+	var committed objectstorage.CommitMultipartUploadResponse
+	if err == nil {
+		committed, err = o.client.CommitMultipartUpload(ctx, objectstorage.CommitMultipartUploadRequest{
+			NamespaceName:                ocicommon.String(o.namespace),
+			BucketName:                   ocicommon.String(o.bucket),
+			ObjectName:                   ocicommon.String(key),
+			UploadId:                     uploadID,
+			CommitMultipartUploadDetails: objectstorage.CommitMultipartUploadDetails{PartsToCommit: parts},
+			IfMatch:                      ifMatch,
+		})
+	}
+	if err != nil {
+		o.abort(ctx, key, uploadID)
+		return "", 0, err
+	}
+	return deref(committed.ETag), size, nil
+}
+
+// abort aborts a failed multipart upload. Failures are ignored; the bucket
+// lifecycle can clean up uploads that are left behind.
+func (o *OCI) abort(ctx context.Context, key string, uploadID *string) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), abortTimeout)
+	defer cancel()
+	// #nosec G104 -- Best effort; the upload already failed
+	_, _ = o.client.AbortMultipartUpload(ctx, objectstorage.AbortMultipartUploadRequest{
+		NamespaceName: ocicommon.String(o.namespace),
+		BucketName:    ocicommon.String(o.bucket),
+		ObjectName:    ocicommon.String(key),
+		UploadId:      uploadID,
+	})
+}
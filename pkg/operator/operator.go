@@ -0,0 +1,345 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package operator reconciles the objstore custom resources on a Kubernetes
+// cluster. Each ObjectStoreBackend becomes a Deployment and Service running
+// objstore-server, and the LifecyclePolicy and ReplicationPolicy resources
+// that reference it are applied to that server through its management API
+// with the reconcile package, as "objstore apply" does. The operator owns
+// the policies of the servers it runs: policies added to them by other
+// means are removed.
+package operator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/jeremyhahn/go-objstore/pkg/cli/client"
+	"github.com/jeremyhahn/go-objstore/pkg/kube"
+	"github.com/jeremyhahn/go-objstore/pkg/lifecycle"
+	"github.com/jeremyhahn/go-objstore/pkg/reconcile"
+)
+
+// ErrInvalidSpec is returned for a resource whose spec cannot be applied.
+var ErrInvalidSpec = errors.New("operator: invalid spec")
+
+// FieldManager is the server-side apply field manager of the operator.
+const FieldManager = "objstore-operator"
+
+// DefaultPort is the REST port of the servers the operator runs.
+const DefaultPort = 8080
+
+// Options configures an Operator.
+type Options struct {
+	// Namespace limits the operator to one namespace; empty watches all.
+	Namespace string
+
+	// ServerImage is the objstore-server image of backends that do not
+	// name one.
+	ServerImage string
+
+	// Endpoint returns the URL of a backend's server. The default is its
+	// Service's cluster DNS name.
+	Endpoint func(b *ObjectStoreBackend) string
+
+	// NewTarget returns the management API of the server at endpoint. The
+	// default is a REST client.
+	NewTarget func(endpoint string) (reconcile.Target, error)
+}
+
+// Operator reconciles the custom resources.
+type Operator struct {
+	kube *kube.Client
+	opts Options
+}
+
+// New creates an operator using the given API client.
+func New(k *kube.Client, opts Options) *Operator {
+	if opts.Endpoint == nil {
+		opts.Endpoint = serviceEndpoint
+	}
+	if opts.NewTarget == nil {
+		opts.NewTarget = restTarget
+	}
+	return &Operator{kube: k, opts: opts}
+}
+
+// serviceEndpoint is the cluster DNS URL of a backend's Service.
+func serviceEndpoint(b *ObjectStoreBackend) string {
+	return fmt.Sprintf("http://%s.%s.svc:%d", b.Metadata.Name, b.Metadata.Namespace, port(b))
+}
+
+func restTarget(endpoint string) (reconcile.Target, error) {
+	c, err := client.NewRESTClient(&client.Config{ServerURL: endpoint})
+	if err != nil {
+		return reconcile.Target{}, err
+	}
+	return reconcile.Target{Policies: c, Replication: c}, nil
+}
+
+// Reconcile makes one pass over every backend and policy, bringing the
+// cluster and servers to the declared state and recording the outcome in
+// each resource's status. It is level-triggered: run it periodically, and a
+// failed pass is retried by the next one.
+func (o *Operator) Reconcile(ctx context.Context) error {
+	var backends backendList
+	if err := o.kube.Get(ctx, kube.Path(Group, Version, o.opts.Namespace, ResourceBackends, ""), &backends); err != nil {
+		return err
+	}
+	var lifecycles lifecyclePolicyList
+	if err := o.kube.Get(ctx, kube.Path(Group, Version, o.opts.Namespace, ResourceLifecyclePolicies, ""), &lifecycles); err != nil {
+		return err
+	}
+	var replications replicationPolicyList
+	if err := o.kube.Get(ctx, kube.Path(Group, Version, o.opts.Namespace, ResourceReplicationPolicies, ""), &replications); err != nil {
+		return err
+	}
+
+	// Group the policies by the backend they reference
+	lifecycleByBackend := make(map[string][]*LifecyclePolicy)
+	for i := range lifecycles.Items {
+		p := &lifecycles.Items[i]
+		key := p.Metadata.Namespace + "/" + p.Spec.BackendRef
+		lifecycleByBackend[key] = append(lifecycleByBackend[key], p)
+	}
+	replicationByBackend := make(map[string][]*ReplicationPolicy)
+	for i := range replications.Items {
+		p := &replications.Items[i]
+		key := p.Metadata.Namespace + "/" + p.Spec.BackendRef
+		replicationByBackend[key] = append(replicationByBackend[key], p)
+	}
+
+	var errs []error
+	for i := range backends.Items {
+		b := &backends.Items[i]
+		key := b.Metadata.Namespace + "/" + b.Metadata.Name
+		lcs, rps := lifecycleByBackend[key], replicationByBackend[key]
+		delete(lifecycleByBackend, key)
+		delete(replicationByBackend, key)
+		if b.Metadata.DeletionTimestamp != nil {
+			// The Deployment and Service are garbage collected with it
+			continue
+		}
+		if err := o.reconcileBackend(ctx, b, lcs, rps); err != nil {
+			errs = append(errs, fmt.Errorf("%s %s: %w", KindBackend, key, err))
+		}
+	}
+
+	// Policies whose backend does not exist
+	for _, lcs := range lifecycleByBackend {
+		for _, p := range lcs {
+			errs = append(errs, o.setLifecycleStatus(ctx, p, PhaseError, missingBackend(p.Spec.BackendRef)))
+		}
+	}
+	for _, rps := range replicationByBackend {
+		for _, p := range rps {
+			errs = append(errs, o.setReplicationStatus(ctx, p, PhaseError, missingBackend(p.Spec.BackendRef)))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func missingBackend(name string) string {
+	return fmt.Sprintf("%s %q not found", KindBackend, name)
+}
+
+// reconcileBackend runs a backend's server and applies its policies.
+func (o *Operator) reconcileBackend(ctx context.Context, b *ObjectStoreBackend, lcs []*LifecyclePolicy, rps []*ReplicationPolicy) error {
+	status := BackendStatus{
+		Phase:              PhasePending,
+		Endpoint:           o.opts.Endpoint(b),
+		ObservedGeneration: b.Metadata.Generation,
+	}
+	// setPolicies records the same outcome on every policy.
+	setPolicies := func(phase, message string) error {
+		var errs []error
+		for _, p := range lcs {
+			errs = append(errs, o.setLifecycleStatus(ctx, p, phase, message))
+		}
+		for _, p := range rps {
+			errs = append(errs, o.setReplicationStatus(ctx, p, phase, message))
+		}
+		return errors.Join(errs...)
+	}
+
+	if err := o.applyWorkload(ctx, b); err != nil {
+		status.Phase, status.Message = PhaseError, err.Error()
+		return errors.Join(err, o.setBackendStatus(ctx, b, status), setPolicies(PhasePending, "waiting for the server"))
+	}
+	ready, err := o.readyReplicas(ctx, b)
+	if err != nil {
+		return err
+	}
+	status.ReadyReplicas = ready
+	if ready == 0 {
+		status.Message = "waiting for a ready server"
+		return errors.Join(o.setBackendStatus(ctx, b, status), setPolicies(PhasePending, "waiting for the server"))
+	}
+
+	file, invalid := o.desiredState(ctx, lcs, rps)
+	err = o.applyPolicies(ctx, status.Endpoint, file, invalid)
+	if err != nil {
+		status.Phase, status.Message = PhaseError, "applying policies: "+err.Error()
+	} else {
+		status.Phase = PhaseReady
+	}
+
+	errs := []error{err, o.setBackendStatus(ctx, b, status)}
+	for _, p := range lcs {
+		phase, message := policyOutcome(err, invalid[reconcile.KindPolicy+"/"+p.Metadata.Name])
+		errs = append(errs, o.setLifecycleStatus(ctx, p, phase, message))
+	}
+	for _, p := range rps {
+		phase, message := policyOutcome(err, invalid[reconcile.KindReplication+"/"+p.Metadata.Name])
+		errs = append(errs, o.setReplicationStatus(ctx, p, phase, message))
+	}
+	return errors.Join(errs...)
+}
+
+// policyOutcome is the status of a policy after an apply that returned
+// err; invalid is why the policy was left out, if it was.
+func policyOutcome(err error, invalid string) (phase, message string) {
+	switch {
+	case invalid != "":
+		return PhaseError, invalid
+	case err != nil:
+		return PhaseError, "applying policies: " + err.Error()
+	default:
+		return PhaseApplied, ""
+	}
+}
+
+// desiredState converts the valid policies to a desired state. Invalid
+// ones are left out and returned by kind and ID with the reason.
+func (o *Operator) desiredState(ctx context.Context, lcs []*LifecyclePolicy, rps []*ReplicationPolicy) (*reconcile.File, map[string]string) {
+	file := &reconcile.File{Policies: []lifecycle.Rule{}, Replication: []reconcile.Replication{}}
+	invalid := make(map[string]string)
+
+	for _, p := range lcs {
+		rule := lifecycle.Rule{
+			ID:            p.Metadata.Name,
+			Prefix:        p.Spec.Prefix,
+			RetentionDays: p.Spec.RetentionDays,
+			Action:        p.Spec.Action,
+			StorageClass:  p.Spec.StorageClass,
+		}
+		if _, err := rule.Policy(); err != nil {
+			invalid[reconcile.KindPolicy+"/"+rule.ID] = err.Error()
+			continue
+		}
+		file.Policies = append(file.Policies, rule)
+	}
+
+	for _, p := range rps {
+		r := reconcile.Replication{
+			ID:                 p.Metadata.Name,
+			SourceBackend:      p.Spec.SourceBackend,
+			SourcePrefix:       p.Spec.SourcePrefix,
+			DestinationBackend: p.Spec.DestinationBackend,
+			CheckInterval:      p.Spec.CheckInterval,
+			Enabled:            p.Spec.Enabled,
+			Mode:               p.Spec.Mode,
+			WriteOnce:          p.Spec.WriteOnce,
+		}
+		var err error
+		r.SourceSettings, err = o.settings(ctx, p.Metadata.Namespace, p.Spec.SourceSettings, p.Spec.SourceSettingsSecret)
+		if err == nil {
+			r.DestinationSettings, err = o.settings(ctx, p.Metadata.Namespace, p.Spec.DestinationSettings, p.Spec.DestinationSettingsSecret)
+		}
+		if err == nil {
+			_, err = r.Policy()
+		}
+		if err != nil {
+			invalid[reconcile.KindReplication+"/"+r.ID] = err.Error()
+			continue
+		}
+		file.Replication = append(file.Replication, r)
+	}
+	return file, invalid
+}
+
+// settings merges the keys of the named Secret into settings.
+func (o *Operator) settings(ctx context.Context, namespace string, settings map[string]string, secret string) (map[string]string, error) {
+	if secret == "" {
+		return settings, nil
+	}
+	data, err := o.kube.SecretData(ctx, namespace, secret)
+	if err != nil {
+		return nil, fmt.Errorf("reading Secret %q: %w", secret, err)
+	}
+	merged := maps.Clone(settings)
+	if merged == nil {
+		merged = make(map[string]string, len(data))
+	}
+	maps.Copy(merged, data)
+	return merged, nil
+}
+
+// applyPolicies brings the server at endpoint to the desired state. The
+// server's copies of policies whose resources are invalid are kept, so a
+// bad edit does not remove a working policy.
+func (o *Operator) applyPolicies(ctx context.Context, endpoint string, file *reconcile.File, invalid map[string]string) error {
+	target, err := o.opts.NewTarget(endpoint)
+	if err != nil {
+		return err
+	}
+	plan, err := reconcile.Diff(ctx, target, file)
+	if err != nil {
+		return err
+	}
+	plan.Changes = slices.DeleteFunc(plan.Changes, func(c reconcile.Change) bool {
+		return c.Op == reconcile.OpDelete && invalid[c.Kind+"/"+c.ID] != ""
+	})
+	_, err = reconcile.Apply(ctx, target, plan)
+	return err
+}
+
+// statusObject is the body of a status update.
+type statusObject struct {
+	APIVersion string          `json:"apiVersion"`
+	Kind       string          `json:"kind"`
+	Metadata   kube.ObjectMeta `json:"metadata"`
+	Status     any             `json:"status"`
+}
+
+// setStatus replaces a resource's status with server-side apply, unless it
+// is unchanged.
+func setStatus[S comparable](ctx context.Context, o *Operator, resource, kind string, meta kube.ObjectMeta, current, next S) error {
+	if current == next {
+		return nil
+	}
+	path := kube.Path(Group, Version, meta.Namespace, resource, meta.Name) + "/status"
+	return o.kube.Apply(ctx, path, FieldManager, statusObject{
+		APIVersion: APIVersion,
+		Kind:       kind,
+		Metadata:   kube.ObjectMeta{Name: meta.Name, Namespace: meta.Namespace},
+		Status:     next,
+	}, nil)
+}
+
+func (o *Operator) setBackendStatus(ctx context.Context, b *ObjectStoreBackend, status BackendStatus) error {
+	return setStatus(ctx, o, ResourceBackends, KindBackend, b.Metadata, b.Status, status)
+}
+
+func (o *Operator) setLifecycleStatus(ctx context.Context, p *LifecyclePolicy, phase, message string) error {
+	status := PolicyStatus{Phase: phase, Message: message, ObservedGeneration: p.Metadata.Generation}
+	return setStatus(ctx, o, ResourceLifecyclePolicies, "LifecyclePolicy", p.Metadata, p.Status, status)
+}
+
+func (o *Operator) setReplicationStatus(ctx context.Context, p *ReplicationPolicy, phase, message string) error {
+	status := PolicyStatus{Phase: phase, Message: message, ObservedGeneration: p.Metadata.Generation}
+	return setStatus(ctx, o, ResourceReplicationPolicies, "ReplicationPolicy", p.Metadata, p.Status, status)
+}
@@ -0,0 +1,308 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/kube"
+	"github.com/jeremyhahn/go-objstore/pkg/reconcile"
+)
+
+// fakeCluster is an API server holding the custom resources.
+type fakeCluster struct {
+	mu           sync.Mutex
+	backends     []ObjectStoreBackend
+	lifecycles   []LifecyclePolicy
+	replications []ReplicationPolicy
+	secrets      map[string]map[string][]byte
+	ready        int32
+	applied      map[string]map[string]any
+	statusWrites int
+}
+
+func (f *fakeCluster) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	crs := kube.Path(Group, Version, "", "", "")
+	path := r.URL.Path
+	switch {
+	case r.Method == http.MethodGet && path == crs+ResourceBackends:
+		writeJSON(w, backendList{Items: f.backends})
+	case r.Method == http.MethodGet && path == crs+ResourceLifecyclePolicies:
+		writeJSON(w, lifecyclePolicyList{Items: f.lifecycles})
+	case r.Method == http.MethodGet && path == crs+ResourceReplicationPolicies:
+		writeJSON(w, replicationPolicyList{Items: f.replications})
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "/apis/apps/v1/") && f.applied[path] != nil:
+		writeJSON(w, map[string]any{"status": map[string]int32{"readyReplicas": f.ready}})
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "/api/v1/namespaces/objstore/secrets/"):
+		data, ok := f.secrets[strings.TrimPrefix(path, "/api/v1/namespaces/objstore/secrets/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		writeJSON(w, kube.Secret{Data: data})
+	case r.Method == http.MethodPatch && strings.HasSuffix(path, "/status"):
+		f.statusWrites++
+		var obj struct {
+			Metadata kube.ObjectMeta `json:"metadata"`
+			Status   json.RawMessage `json:"status"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&obj)
+		name := obj.Metadata.Name
+		for i := range f.backends {
+			if strings.Contains(path, "/"+ResourceBackends+"/") && f.backends[i].Metadata.Name == name {
+				f.backends[i].Status = BackendStatus{}
+				_ = json.Unmarshal(obj.Status, &f.backends[i].Status)
+			}
+		}
+		for i := range f.lifecycles {
+			if strings.Contains(path, "/"+ResourceLifecyclePolicies+"/") && f.lifecycles[i].Metadata.Name == name {
+				f.lifecycles[i].Status = PolicyStatus{}
+				_ = json.Unmarshal(obj.Status, &f.lifecycles[i].Status)
+			}
+		}
+		for i := range f.replications {
+			if strings.Contains(path, "/"+ResourceReplicationPolicies+"/") && f.replications[i].Metadata.Name == name {
+				f.replications[i].Status = PolicyStatus{}
+				_ = json.Unmarshal(obj.Status, &f.replications[i].Status)
+			}
+		}
+		writeJSON(w, map[string]any{})
+	case r.Method == http.MethodPatch:
+		var obj map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&obj)
+		f.applied[path] = obj
+		writeJSON(w, obj)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// fakeServer is an objstore-server's management API.
+type fakeServer struct {
+	policies    map[string]common.LifecyclePolicy
+	replication map[string]common.ReplicationPolicy
+}
+
+func (f *fakeServer) GetPolicies(context.Context) ([]common.LifecyclePolicy, error) {
+	policies := make([]common.LifecyclePolicy, 0, len(f.policies))
+	for _, p := range f.policies {
+		policies = append(policies, p)
+	}
+	return policies, nil
+}
+
+func (f *fakeServer) AddPolicy(_ context.Context, policy common.LifecyclePolicy) error {
+	f.policies[policy.ID] = policy
+	return nil
+}
+
+func (f *fakeServer) RemovePolicy(_ context.Context, id string) error {
+	delete(f.policies, id)
+	return nil
+}
+
+func (f *fakeServer) GetReplicationPolicies(context.Context) ([]common.ReplicationPolicy, error) {
+	policies := make([]common.ReplicationPolicy, 0, len(f.replication))
+	for _, p := range f.replication {
+		policies = append(policies, p)
+	}
+	return policies, nil
+}
+
+func (f *fakeServer) AddReplicationPolicy(_ context.Context, policy common.ReplicationPolicy) error {
+	f.replication[policy.ID] = policy
+	return nil
+}
+
+func (f *fakeServer) RemoveReplicationPolicy(_ context.Context, id string) error {
+	delete(f.replication, id)
+	return nil
+}
+
+func meta(name string) kube.ObjectMeta {
+	return kube.ObjectMeta{Name: name, Namespace: "objstore", UID: "uid-" + name, Generation: 1}
+}
+
+func TestReconcile(t *testing.T) {
+	cluster := &fakeCluster{
+		backends: []ObjectStoreBackend{{
+			Metadata: meta("archive"),
+			Spec: BackendSpec{
+				Type:           "s3",
+				Settings:       map[string]string{"region": "us-east-1", "bucket": "archive"},
+				SettingsSecret: "archive-creds",
+			},
+		}},
+		lifecycles: []LifecyclePolicy{
+			{Metadata: meta("expire-logs"), Spec: LifecyclePolicySpec{BackendRef: "archive", Prefix: "logs/", RetentionDays: 30, Action: "delete"}},
+			{Metadata: meta("broken"), Spec: LifecyclePolicySpec{BackendRef: "archive", RetentionDays: 30, Action: "shred"}},
+			{Metadata: meta("orphan"), Spec: LifecyclePolicySpec{BackendRef: "missing", RetentionDays: 1, Action: "delete"}},
+		},
+		replications: []ReplicationPolicy{{
+			Metadata: meta("offsite"),
+			Spec: ReplicationPolicySpec{
+				BackendRef:                "archive",
+				SourceBackend:             "s3",
+				SourceSettings:            map[string]string{"bucket": "archive"},
+				DestinationBackend:        "gcs",
+				DestinationSettingsSecret: "offsite-creds",
+				CheckInterval:             "5m",
+			},
+		}},
+		secrets: map[string]map[string][]byte{"offsite-creds": {"bucket": []byte("offsite"), "credentials": []byte("{}")}},
+		applied: make(map[string]map[string]any),
+	}
+	api := httptest.NewServer(cluster)
+	defer api.Close()
+
+	server := &fakeServer{
+		policies: map[string]common.LifecyclePolicy{
+			"manual": {ID: "manual", Retention: 7 * 24 * time.Hour, Action: "delete"},
+			"broken": {ID: "broken", Retention: 30 * 24 * time.Hour, Action: "delete"},
+		},
+		replication: map[string]common.ReplicationPolicy{},
+	}
+	k, err := kube.NewClient(&kube.Config{Host: api.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var endpoints []string
+	op := New(k, Options{
+		ServerImage: "objstore-server:test",
+		NewTarget: func(endpoint string) (reconcile.Target, error) {
+			endpoints = append(endpoints, endpoint)
+			return reconcile.Target{Policies: server, Replication: server}, nil
+		},
+	})
+	ctx := context.Background()
+
+	// No server is ready yet: the workload is applied and everything waits
+	if err := op.Reconcile(ctx); err != nil {
+		t.Fatal(err)
+	}
+	deployment := cluster.applied["/apis/apps/v1/namespaces/objstore/deployments/archive"]
+	if deployment == nil || cluster.applied["/api/v1/namespaces/objstore/services/archive"] == nil {
+		t.Fatalf("workload not applied: %v", cluster.applied)
+	}
+	data, _ := json.Marshal(deployment)
+	for _, want := range []string{
+		`"image":"objstore-server:test"`,
+		`"--backend=s3"`,
+		`"--backend-settings-dir=/etc/objstore/backend"`,
+		`"--backend-setting=bucket=archive","--backend-setting=region=us-east-1"`,
+		`"secretName":"archive-creds"`,
+		`"controller":true,"kind":"ObjectStoreBackend","name":"archive","uid":"uid-archive"`,
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Deployment missing %s: %s", want, data)
+		}
+	}
+	if got := cluster.backends[0].Status; got.Phase != PhasePending || got.Endpoint != "http://archive.objstore.svc:8080" {
+		t.Errorf("backend status = %+v, want Pending", got)
+	}
+	if got := cluster.lifecycles[0].Status.Phase; got != PhasePending {
+		t.Errorf("policy phase = %q, want Pending", got)
+	}
+	if got := cluster.lifecycles[2].Status; got.Phase != PhaseError || !strings.Contains(got.Message, `"missing" not found`) {
+		t.Errorf("orphan status = %+v", got)
+	}
+	if len(endpoints) != 0 {
+		t.Errorf("server called before it was ready: %v", endpoints)
+	}
+
+	// The server is ready: the policies are applied
+	cluster.ready = 1
+	if err := op.Reconcile(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(endpoints, []string{"http://archive.objstore.svc:8080"}) {
+		t.Errorf("endpoints = %v", endpoints)
+	}
+	if _, ok := server.policies["manual"]; ok {
+		t.Error("unmanaged policy was not removed")
+	}
+	if got := server.policies["expire-logs"]; got.Prefix != "logs/" || got.Retention != 30*24*time.Hour {
+		t.Errorf("expire-logs = %+v", got)
+	}
+	if got := server.policies["broken"]; got.Action != "delete" {
+		t.Errorf("policy of an invalid resource was changed: %+v", got)
+	}
+	offsite := server.replication["offsite"]
+	if offsite.DestinationSettings["bucket"] != "offsite" || offsite.DestinationSettings["credentials"] != "{}" || !offsite.Enabled {
+		t.Errorf("offsite = %+v", offsite)
+	}
+
+	if got := cluster.backends[0].Status; got.Phase != PhaseReady || got.ReadyReplicas != 1 || got.ObservedGeneration != 1 {
+		t.Errorf("backend status = %+v, want Ready", got)
+	}
+	if got := cluster.lifecycles[0].Status.Phase; got != PhaseApplied {
+		t.Errorf("expire-logs phase = %q, want Applied", got)
+	}
+	if got := cluster.lifecycles[1].Status; got.Phase != PhaseError || got.Message == "" {
+		t.Errorf("broken status = %+v, want Error", got)
+	}
+	if got := cluster.replications[0].Status.Phase; got != PhaseApplied {
+		t.Errorf("offsite phase = %q, want Applied", got)
+	}
+
+	// Nothing changed: no status is rewritten
+	writes := cluster.statusWrites
+	if err := op.Reconcile(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if cluster.statusWrites != writes {
+		t.Errorf("unchanged statuses were rewritten %d times", cluster.statusWrites-writes)
+	}
+}
+
+func TestReconcileInvalidBackend(t *testing.T) {
+	cluster := &fakeCluster{
+		backends: []ObjectStoreBackend{{Metadata: meta("untyped")}},
+		applied:  make(map[string]map[string]any),
+	}
+	api := httptest.NewServer(cluster)
+	defer api.Close()
+	k, err := kube.NewClient(&kube.Config{Host: api.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = New(k, Options{}).Reconcile(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "spec.type is required") {
+		t.Errorf("Reconcile() error = %v", err)
+	}
+	if len(cluster.applied) != 0 {
+		t.Errorf("workload applied for an invalid backend: %v", cluster.applied)
+	}
+	if got := cluster.backends[0].Status.Phase; got != PhaseError {
+		t.Errorf("phase = %q, want Error", got)
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package operator
+
+import (
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/kube"
+)
+
+// API group, version and resources of the custom resources.
+const (
+	Group      = "objstore.automatethethings.com"
+	Version    = "v1alpha1"
+	APIVersion = Group + "/" + Version
+
+	KindBackend = "ObjectStoreBackend"
+
+	ResourceBackends            = "objectstorebackends"
+	ResourceLifecyclePolicies   = "lifecyclepolicies"
+	ResourceReplicationPolicies = "replicationpolicies"
+)
+
+// Status phases.
+const (
+	PhasePending = "Pending"
+	PhaseReady   = "Ready"
+	PhaseApplied = "Applied"
+	PhaseError   = "Error"
+)
+
+// ObjectStoreBackend is an objstore-server deployment serving one storage
+// backend.
+type ObjectStoreBackend struct {
+	APIVersion string          `json:"apiVersion,omitempty"`
+	Kind       string          `json:"kind,omitempty"`
+	Metadata   kube.ObjectMeta `json:"metadata"`
+	Spec       BackendSpec     `json:"spec"`
+	Status     BackendStatus   `json:"status,omitempty"`
+}
+
+// BackendSpec is the desired server.
+type BackendSpec struct {
+	// Type is the backend type, such as local, s3 or gcs.
+	Type string `json:"type"`
+
+	// Settings are backend settings, such as bucket and region.
+	Settings map[string]string `json:"settings,omitempty"`
+
+	// SettingsSecret names a Secret in the same namespace whose keys are
+	// backend settings, for credentials. It is mounted into the server, so
+	// rotating it does not need the operator.
+	SettingsSecret string `json:"settingsSecret,omitempty"`
+
+	// Image is the objstore-server image (default: the operator's
+	// --server-image).
+	Image string `json:"image,omitempty"`
+
+	// Replicas is the number of server pods (default: 1).
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Port is the REST port the server listens on and the Service exposes
+	// (default: 8080).
+	Port int32 `json:"port,omitempty"`
+
+	// VolumeClaim names a PersistentVolumeClaim mounted at /data, the local
+	// backend's path. Without one /data is an emptyDir.
+	VolumeClaim string `json:"volumeClaim,omitempty"`
+
+	// Args are extra objstore-server flags, such as --lifecycle-interval=1h.
+	Args []string `json:"args,omitempty"`
+}
+
+// BackendStatus is the observed state of an ObjectStoreBackend.
+type BackendStatus struct {
+	Phase              string `json:"phase,omitempty"`
+	Message            string `json:"message,omitempty"`
+	Endpoint           string `json:"endpoint,omitempty"`
+	ReadyReplicas      int32  `json:"readyReplicas,omitempty"`
+	ObservedGeneration int64  `json:"observedGeneration,omitempty"`
+}
+
+// LifecyclePolicy is a lifecycle policy applied to an ObjectStoreBackend's
+// server. The policy ID is the resource name.
+type LifecyclePolicy struct {
+	APIVersion string              `json:"apiVersion,omitempty"`
+	Kind       string              `json:"kind,omitempty"`
+	Metadata   kube.ObjectMeta     `json:"metadata"`
+	Spec       LifecyclePolicySpec `json:"spec"`
+	Status     PolicyStatus        `json:"status,omitempty"`
+}
+
+// LifecyclePolicySpec is the desired policy.
+type LifecyclePolicySpec struct {
+	// BackendRef names the ObjectStoreBackend in the same namespace.
+	BackendRef    string `json:"backendRef"`
+	Prefix        string `json:"prefix,omitempty"`
+	RetentionDays int    `json:"retentionDays"`
+
+	// Action is "delete", "archive" or "transition".
+	Action       string `json:"action"`
+	StorageClass string `json:"storageClass,omitempty"`
+}
+
+// ReplicationPolicy is a replication policy applied to an
+// ObjectStoreBackend's server. The policy ID is the resource name.
+type ReplicationPolicy struct {
+	APIVersion string                `json:"apiVersion,omitempty"`
+	Kind       string                `json:"kind,omitempty"`
+	Metadata   kube.ObjectMeta       `json:"metadata"`
+	Spec       ReplicationPolicySpec `json:"spec"`
+	Status     PolicyStatus          `json:"status,omitempty"`
+}
+
+// ReplicationPolicySpec is the desired policy.
+type ReplicationPolicySpec struct {
+	// BackendRef names the ObjectStoreBackend in the same namespace.
+	BackendRef     string            `json:"backendRef"`
+	SourceBackend  string            `json:"sourceBackend"`
+	SourceSettings map[string]string `json:"sourceSettings,omitempty"`
+
+	// SourceSettingsSecret names a Secret whose keys are added to
+	// SourceSettings.
+	SourceSettingsSecret string            `json:"sourceSettingsSecret,omitempty"`
+	SourcePrefix         string            `json:"sourcePrefix,omitempty"`
+	DestinationBackend   string            `json:"destinationBackend"`
+	DestinationSettings  map[string]string `json:"destinationSettings,omitempty"`
+
+	// DestinationSettingsSecret names a Secret whose keys are added to
+	// DestinationSettings.
+	DestinationSettingsSecret string `json:"destinationSettingsSecret,omitempty"`
+
+	// CheckInterval is a duration such as "5m".
+	CheckInterval string `json:"checkInterval"`
+
+	// Enabled defaults to true.
+	Enabled   *bool                  `json:"enabled,omitempty"`
+	Mode      common.ReplicationMode `json:"mode,omitempty"`
+	WriteOnce bool                   `json:"writeOnce,omitempty"`
+}
+
+// PolicyStatus is the observed state of a policy.
+type PolicyStatus struct {
+	Phase              string `json:"phase,omitempty"`
+	Message            string `json:"message,omitempty"`
+	ObservedGeneration int64  `json:"observedGeneration,omitempty"`
+}
+
+// Lists returned by the API server.
+type (
+	backendList struct {
+		Items []ObjectStoreBackend `json:"items"`
+	}
+	lifecyclePolicyList struct {
+		Items []LifecyclePolicy `json:"items"`
+	}
+	replicationPolicyList struct {
+		Items []ReplicationPolicy `json:"items"`
+	}
+)
@@ -0,0 +1,185 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package operator
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/jeremyhahn/go-objstore/pkg/kube"
+)
+
+// Paths inside the server containers the operator runs.
+const (
+	dataPath     = "/data"
+	settingsPath = "/etc/objstore/backend"
+	serverBinary = "/app/objstore-server"
+)
+
+// object is a Kubernetes object built for server-side apply.
+type object = map[string]any
+
+func port(b *ObjectStoreBackend) int32 {
+	if b.Spec.Port > 0 {
+		return b.Spec.Port
+	}
+	return DefaultPort
+}
+
+// labels are the labels of a backend's Deployment, pods and Service; the
+// first two select its pods.
+func labels(b *ObjectStoreBackend) map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/name":       "objstore-server",
+		"app.kubernetes.io/instance":   b.Metadata.Name,
+		"app.kubernetes.io/managed-by": FieldManager,
+	}
+}
+
+func selector(b *ObjectStoreBackend) map[string]string {
+	l := labels(b)
+	delete(l, "app.kubernetes.io/managed-by")
+	return l
+}
+
+// metadata is the metadata of an object owned by b, so that it is
+// garbage collected when b is deleted.
+func metadata(b *ObjectStoreBackend) kube.ObjectMeta {
+	return kube.ObjectMeta{
+		Name:      b.Metadata.Name,
+		Namespace: b.Metadata.Namespace,
+		Labels:    labels(b),
+		OwnerReferences: []kube.OwnerReference{{
+			APIVersion:         APIVersion,
+			Kind:               KindBackend,
+			Name:               b.Metadata.Name,
+			UID:                b.Metadata.UID,
+			Controller:         true,
+			BlockOwnerDeletion: true,
+		}},
+	}
+}
+
+// serverArgs are the objstore-server flags of a backend. The server only
+// serves REST, which the operator and the Service use.
+func serverArgs(b *ObjectStoreBackend) []string {
+	args := []string{
+		"--backend=" + b.Spec.Type,
+		"--path=" + dataPath,
+		"--rest-port=" + strconv.Itoa(int(port(b))),
+		"--grpc=false",
+		"--quic=false",
+		"--mcp=false",
+	}
+	if b.Spec.SettingsSecret != "" {
+		args = append(args, "--backend-settings-dir="+settingsPath)
+	}
+	keys := make([]string, 0, len(b.Spec.Settings))
+	for key := range b.Spec.Settings {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		args = append(args, "--backend-setting="+key+"="+b.Spec.Settings[key])
+	}
+	return append(args, b.Spec.Args...)
+}
+
+// deployment is the Deployment running a backend's server.
+func (o *Operator) deployment(b *ObjectStoreBackend) object {
+	image := b.Spec.Image
+	if image == "" {
+		image = o.opts.ServerImage
+	}
+	replicas := int32(1)
+	if b.Spec.Replicas != nil {
+		replicas = *b.Spec.Replicas
+	}
+
+	data := object{"name": "data", "emptyDir": object{}}
+	if b.Spec.VolumeClaim != "" {
+		data = object{"name": "data", "persistentVolumeClaim": object{"claimName": b.Spec.VolumeClaim}}
+	}
+	volumes := []object{data}
+	mounts := []object{{"name": "data", "mountPath": dataPath}}
+	if b.Spec.SettingsSecret != "" {
+		volumes = append(volumes, object{"name": "settings", "secret": object{"secretName": b.Spec.SettingsSecret}})
+		mounts = append(mounts, object{"name": "settings", "mountPath": settingsPath, "readOnly": true})
+	}
+
+	return object{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   metadata(b),
+		"spec": object{
+			"replicas": replicas,
+			"selector": object{"matchLabels": selector(b)},
+			"template": object{
+				"metadata": object{"labels": labels(b)},
+				"spec": object{
+					"containers": []object{{
+						"name":         "objstore-server",
+						"image":        image,
+						"command":      []string{serverBinary},
+						"args":         serverArgs(b),
+						"ports":        []object{{"name": "http", "containerPort": port(b)}},
+						"volumeMounts": mounts,
+						"readinessProbe": object{
+							"httpGet": object{"path": "/health", "port": "http"},
+						},
+					}},
+					"volumes": volumes,
+				},
+			},
+		},
+	}
+}
+
+// service is the Service in front of a backend's server.
+func service(b *ObjectStoreBackend) object {
+	return object{
+		"apiVersion": "v1",
+		"kind":       "Service",
+		"metadata":   metadata(b),
+		"spec": object{
+			"selector": selector(b),
+			"ports":    []object{{"name": "http", "port": port(b), "targetPort": "http"}},
+		},
+	}
+}
+
+// applyWorkload creates or updates a backend's Deployment and Service.
+func (o *Operator) applyWorkload(ctx context.Context, b *ObjectStoreBackend) error {
+	if b.Spec.Type == "" {
+		return fmt.Errorf("%w: spec.type is required", ErrInvalidSpec)
+	}
+	ns, name := b.Metadata.Namespace, b.Metadata.Name
+	if err := o.kube.Apply(ctx, kube.Path("apps", "v1", ns, "deployments", name), FieldManager, o.deployment(b), nil); err != nil {
+		return err
+	}
+	return o.kube.Apply(ctx, kube.Path("", "v1", ns, "services", name), FieldManager, service(b), nil)
+}
+
+// readyReplicas returns how many of a backend's server pods are ready.
+func (o *Operator) readyReplicas(ctx context.Context, b *ObjectStoreBackend) (int32, error) {
+	var deployment struct {
+		Status struct {
+			ReadyReplicas int32 `json:"readyReplicas"`
+		} `json:"status"`
+	}
+	err := o.kube.Get(ctx, kube.Path("apps", "v1", b.Metadata.Namespace, "deployments", b.Metadata.Name), &deployment)
+	return deployment.Status.ReadyReplicas, err
+}
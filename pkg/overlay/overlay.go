@@ -0,0 +1,209 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package overlay applies configuration declared per key prefix to every
+// object stored under it: encryption, compression, storage class,
+// replication and quotas. Overlays are declared once, for example in the
+// server's configuration, instead of by every caller that writes the
+// objects.
+package overlay
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// Compression codecs.
+const (
+	CompressionNone = "none"
+	CompressionGzip = "gzip"
+	CompressionZstd = "zstd"
+)
+
+// Custom metadata keys recording how an object was stored, so it can be
+// read back after its overlay changes. They differ from the keys used by
+// backend-wide encryption, which may be applied underneath.
+const (
+	MetaCompression = "overlay_compression"
+	MetaKeyID       = "overlay_encryption_key_id"
+	MetaAlgorithm   = "overlay_encryption_algorithm"
+)
+
+var (
+	// ErrInvalidConfig is returned when overlays cannot be loaded or are
+	// malformed.
+	ErrInvalidConfig = errors.New("invalid overlay configuration")
+
+	// ErrQuotaExceeded is returned when a write would exceed the quota of
+	// an overlay. Errors wrapping it also wrap common.ErrResourceExhausted.
+	ErrQuotaExceeded = errors.New("overlay quota exceeded")
+)
+
+// Quota limits the data stored under an overlay's prefix. Zero fields are
+// unlimited.
+type Quota struct {
+	// MaxBytes limits the total stored size of the objects.
+	MaxBytes int64 `yaml:"maxBytes,omitempty" json:"maxBytes,omitempty"`
+
+	// MaxObjects limits the number of objects.
+	MaxObjects int64 `yaml:"maxObjects,omitempty" json:"maxObjects,omitempty"`
+}
+
+// Overlay is the configuration applied to keys under Prefix.
+type Overlay struct {
+	// Prefix selects the keys the overlay applies to. An empty prefix
+	// matches every key.
+	Prefix string `yaml:"prefix" json:"prefix"`
+
+	// Encryption encrypts objects with the Config's encrypter when true.
+	// Since only the most specific overlay applies, false on a nested
+	// prefix turns off encryption enabled on its parent.
+	Encryption *bool `yaml:"encryption,omitempty" json:"encryption,omitempty"`
+
+	// KeyID selects the encryption key; empty uses the default key.
+	KeyID string `yaml:"keyId,omitempty" json:"keyId,omitempty"`
+
+	// Compression is the codec objects are compressed with: gzip, zstd or
+	// none.
+	Compression string `yaml:"compression,omitempty" json:"compression,omitempty"`
+
+	// StorageClass is stored with objects written without one.
+	StorageClass string `yaml:"storageClass,omitempty" json:"storageClass,omitempty"`
+
+	// Replication names a replication policy of the backend that is synced
+	// after objects under the prefix change.
+	Replication string `yaml:"replication,omitempty" json:"replication,omitempty"`
+
+	// Quota limits the data stored under the prefix.
+	Quota *Quota `yaml:"quota,omitempty" json:"quota,omitempty"`
+}
+
+// encrypts reports whether objects under the overlay are encrypted.
+func (o *Overlay) encrypts() bool {
+	return o.Encryption != nil && *o.Encryption
+}
+
+// compresses reports whether objects under the overlay are compressed.
+func (o *Overlay) compresses() bool {
+	return o.Compression != "" && o.Compression != CompressionNone
+}
+
+// Config is a set of overlays. Only the most specific overlay, the one
+// with the longest matching prefix, applies to a key.
+type Config struct {
+	Overlays []Overlay `yaml:"overlays" json:"overlays"`
+
+	// Encrypter encrypts objects under overlays with encryption enabled.
+	// It is required when any overlay enables encryption.
+	Encrypter common.EncrypterFactory `yaml:"-" json:"-"`
+
+	// Replication syncs the replication policies overlays name. It is
+	// required when any overlay names one.
+	Replication common.ReplicationManager `yaml:"-" json:"-"`
+}
+
+// LoadFile reads overlays from a YAML or JSON file. The encrypter and
+// replication manager overlays need are checked when they are enabled.
+func LoadFile(filename string) (*Config, error) {
+	data, err := os.ReadFile(filename) // #nosec G304 -- path supplied by the operator
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidConfig, filename, err)
+	}
+	if err := cfg.validateOverlays(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// Validate checks that no prefix is configured twice, that codecs and
+// quotas are valid, and that an encrypter and replication manager are set
+// when overlays need them.
+func (c *Config) Validate() error {
+	if err := c.validateOverlays(); err != nil {
+		return err
+	}
+	for i := range c.Overlays {
+		o := &c.Overlays[i]
+		if o.encrypts() && c.Encrypter == nil {
+			return fmt.Errorf("%w: prefix %q enables encryption but no encrypter is configured", ErrInvalidConfig, o.Prefix)
+		}
+		if o.Replication != "" {
+			if c.Replication == nil {
+				return fmt.Errorf("%w: prefix %q: replication needs a backend with replication enabled", ErrInvalidConfig, o.Prefix)
+			}
+			if _, err := c.Replication.GetPolicy(o.Replication); err != nil {
+				return fmt.Errorf("%w: prefix %q: replication policy %q: %w", ErrInvalidConfig, o.Prefix, o.Replication, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateOverlays checks the overlays themselves.
+func (c *Config) validateOverlays() error {
+	seen := make(map[string]bool, len(c.Overlays))
+	for i := range c.Overlays {
+		o := &c.Overlays[i]
+		if seen[o.Prefix] {
+			return fmt.Errorf("%w: duplicate overlay for prefix %q", ErrInvalidConfig, o.Prefix)
+		}
+		seen[o.Prefix] = true
+
+		o.Compression = strings.ToLower(o.Compression)
+		switch o.Compression {
+		case "", CompressionNone, CompressionGzip, CompressionZstd:
+		default:
+			return fmt.Errorf("%w: prefix %q: unsupported compression %q", ErrInvalidConfig, o.Prefix, o.Compression)
+		}
+		if o.Quota != nil && (o.Quota.MaxBytes < 0 || o.Quota.MaxObjects < 0) {
+			return fmt.Errorf("%w: prefix %q: negative quota", ErrInvalidConfig, o.Prefix)
+		}
+	}
+	return nil
+}
+
+// NeedsReplication reports whether any overlay names a replication policy.
+func (c *Config) NeedsReplication() bool {
+	for i := range c.Overlays {
+		if c.Overlays[i].Replication != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Match returns the most specific overlay applying to key, or nil when
+// none does.
+func (c *Config) Match(key string) *Overlay {
+	var match *Overlay
+	for i := range c.Overlays {
+		o := &c.Overlays[i]
+		if !strings.HasPrefix(key, o.Prefix) {
+			continue
+		}
+		if match == nil || len(o.Prefix) > len(match.Prefix) {
+			match = o
+		}
+	}
+	return match
+}
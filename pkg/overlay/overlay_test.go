@@ -0,0 +1,360 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package overlay
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+// xorEncrypter is a reversible stand-in for a real cipher.
+type xorEncrypter struct{ keyID string }
+
+func (x *xorEncrypter) xor(r io.Reader) (io.ReadCloser, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	for i := range data {
+		data[i] ^= 0x5a
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (x *xorEncrypter) Encrypt(_ context.Context, r io.Reader) (io.ReadCloser, error) {
+	return x.xor(r)
+}
+
+func (x *xorEncrypter) Decrypt(_ context.Context, r io.Reader) (io.ReadCloser, error) {
+	return x.xor(r)
+}
+
+func (x *xorEncrypter) Algorithm() string { return "xor" }
+func (x *xorEncrypter) KeyID() string     { return x.keyID }
+
+type xorFactory struct{}
+
+func (xorFactory) GetEncrypter(keyID string) (common.Encrypter, error) {
+	if keyID == "" {
+		keyID = "default"
+	}
+	return &xorEncrypter{keyID: keyID}, nil
+}
+func (xorFactory) DefaultKeyID() string { return "default" }
+func (xorFactory) Close() error         { return nil }
+
+// syncCounter is a replication manager that counts syncs.
+type syncCounter struct {
+	common.ReplicationManager
+	syncs atomic.Int32
+}
+
+func (s *syncCounter) GetPolicy(id string) (*common.ReplicationPolicy, error) {
+	if id != "dr" {
+		return nil, common.ErrPolicyNotFound
+	}
+	return &common.ReplicationPolicy{ID: id}, nil
+}
+
+func (s *syncCounter) SyncPolicy(context.Context, string) (*common.SyncResult, error) {
+	s.syncs.Add(1)
+	return &common.SyncResult{}, nil
+}
+
+func enabled() *bool {
+	b := true
+	return &b
+}
+
+func read(t *testing.T, s common.Storage, key string) string {
+	t.Helper()
+	rc, err := s.GetWithContext(context.Background(), key)
+	if err != nil {
+		t.Fatalf("Get(%s) error = %v", key, err)
+	}
+	defer func() { _ = rc.Close() }()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatalf("read %s: %v", key, err)
+	}
+	return string(data)
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "overlays.yaml")
+	data := `overlays:
+  - prefix: logs/
+    compression: ZSTD
+    storageClass: STANDARD_IA
+    quota:
+      maxBytes: 1048576
+  - prefix: secrets/
+    encryption: true
+`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if got := cfg.Match("logs/app.log"); got == nil || got.Compression != CompressionZstd || got.Quota.MaxBytes != 1<<20 {
+		t.Errorf("Match(logs/app.log) = %+v", got)
+	}
+	if err := cfg.Validate(); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("Validate() without encrypter error = %v, want ErrInvalidConfig", err)
+	}
+	cfg.Encrypter = xorFactory{}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{"duplicate prefix", Config{Overlays: []Overlay{{Prefix: "a/"}, {Prefix: "a/"}}}},
+		{"bad codec", Config{Overlays: []Overlay{{Prefix: "a/", Compression: "lz4"}}}},
+		{"negative quota", Config{Overlays: []Overlay{{Prefix: "a/", Quota: &Quota{MaxBytes: -1}}}}},
+		{"replication without manager", Config{Overlays: []Overlay{{Prefix: "a/", Replication: "dr"}}}},
+		{"unknown policy", Config{Overlays: []Overlay{{Prefix: "a/", Replication: "nope"}}, Replication: &syncCounter{}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); !errors.Is(err, ErrInvalidConfig) {
+				t.Errorf("Validate() error = %v, want ErrInvalidConfig", err)
+			}
+		})
+	}
+}
+
+func TestCompressionAndStorageClass(t *testing.T) {
+	for _, codec := range []string{CompressionGzip, CompressionZstd} {
+		t.Run(codec, func(t *testing.T) {
+			ctx := context.Background()
+			backend := memory.New()
+			s := NewStorage(backend, &Config{Overlays: []Overlay{
+				{Prefix: "logs/", Compression: codec, StorageClass: "COLD"},
+			}})
+			content := strings.Repeat("line of log output\n", 1000)
+			if err := s.Put("logs/app.log", strings.NewReader(content)); err != nil {
+				t.Fatalf("Put() error = %v", err)
+			}
+
+			metadata, err := backend.GetMetadata(ctx, "logs/app.log")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if metadata.Custom[MetaCompression] != codec || metadata.StorageClass != "COLD" {
+				t.Errorf("stored metadata = %+v", metadata)
+			}
+			if metadata.Size >= int64(len(content)) {
+				t.Errorf("stored size %d not compressed from %d", metadata.Size, len(content))
+			}
+			if got := read(t, s, "logs/app.log"); got != content {
+				t.Error("round trip changed content")
+			}
+
+			rc, err := s.GetRange(ctx, "logs/app.log", 5, 3)
+			if err != nil {
+				t.Fatal(err)
+			}
+			part, _ := io.ReadAll(rc)
+			_ = rc.Close()
+			if string(part) != content[5:8] {
+				t.Errorf("GetRange() = %q, want %q", part, content[5:8])
+			}
+
+			// Keys outside the overlay are stored as is.
+			if err := s.Put("other.txt", strings.NewReader("plain")); err != nil {
+				t.Fatal(err)
+			}
+			if got := read(t, backend, "other.txt"); got != "plain" {
+				t.Errorf("backend content = %q", got)
+			}
+		})
+	}
+}
+
+func TestEncryption(t *testing.T) {
+	backend := memory.New()
+	cfg := &Config{
+		Overlays: []Overlay{
+			{Prefix: "secrets/", Encryption: enabled(), KeyID: "k1", Compression: CompressionGzip},
+			{Prefix: "secrets/public/", Encryption: new(bool)},
+		},
+		Encrypter: xorFactory{},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	s := NewStorage(backend, cfg)
+	ctx := context.Background()
+
+	if err := s.PutWithMetadata(ctx, "secrets/db", strings.NewReader("hunter2"), &common.Metadata{ContentType: "text/plain"}); err != nil {
+		t.Fatal(err)
+	}
+	metadata, _ := backend.GetMetadata(ctx, "secrets/db")
+	if metadata.Custom[MetaKeyID] != "k1" || metadata.Custom[MetaAlgorithm] != "xor" {
+		t.Errorf("stored metadata = %+v", metadata.Custom)
+	}
+	if got := read(t, s, "secrets/db"); got != "hunter2" {
+		t.Errorf("Get() = %q", got)
+	}
+
+	if err := s.Put("secrets/public/readme", strings.NewReader("hello")); err != nil {
+		t.Fatal(err)
+	}
+	if got := read(t, backend, "secrets/public/readme"); got != "hello" {
+		t.Errorf("nested overlay without encryption stored %q", got)
+	}
+}
+
+func TestQuota(t *testing.T) {
+	ctx := context.Background()
+	backend := memory.New()
+	if err := backend.Put("tenant/existing", strings.NewReader("12345")); err != nil {
+		t.Fatal(err)
+	}
+	s := NewStorage(backend, &Config{Overlays: []Overlay{
+		{Prefix: "tenant/", Quota: &Quota{MaxBytes: 10, MaxObjects: 2}},
+	}})
+
+	if err := s.Put("tenant/a", strings.NewReader("1234")); err != nil {
+		t.Fatalf("Put() within quota error = %v", err)
+	}
+	if bytes, objects, _ := s.Usage(ctx, "tenant/"); bytes != 9 || objects != 2 {
+		t.Errorf("Usage() = %d bytes, %d objects, want 9, 2", bytes, objects)
+	}
+	err := s.Put("tenant/b", strings.NewReader("x"))
+	if !errors.Is(err, ErrQuotaExceeded) || !errors.Is(err, common.ErrResourceExhausted) {
+		t.Errorf("Put() over object quota error = %v", err)
+	}
+	err = s.Put("tenant/a", strings.NewReader("123456"))
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("Put() over byte quota error = %v", err)
+	}
+	// Overwriting within the bytes freed by the old version succeeds.
+	if err := s.Put("tenant/a", strings.NewReader("12345")); err != nil {
+		t.Errorf("overwrite error = %v", err)
+	}
+
+	if err := s.Delete("tenant/existing"); err != nil {
+		t.Fatal(err)
+	}
+	if bytes, objects, _ := s.Usage(ctx, "tenant/"); bytes != 5 || objects != 1 {
+		t.Errorf("Usage() after delete = %d bytes, %d objects, want 5, 1", bytes, objects)
+	}
+	if err := s.Put("tenant/b", strings.NewReader("x")); err != nil {
+		t.Errorf("Put() after delete error = %v", err)
+	}
+}
+
+func TestSetQuota(t *testing.T) {
+	ctx := context.Background()
+	backend := memory.New()
+	s := NewStorage(backend, &Config{
+		Overlays:  []Overlay{{Prefix: "tenant/", Compression: CompressionGzip}},
+		Encrypter: xorFactory{},
+	})
+	if err := s.Put("tenant/acme/a", strings.NewReader("12345")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.SetQuota("tenant/acme/", &Quota{MaxObjects: 2}); err != nil {
+		t.Fatalf("SetQuota() error = %v", err)
+	}
+	if got := s.Quotas(); len(got) != 1 || got["tenant/acme/"].MaxObjects != 2 {
+		t.Errorf("Quotas() = %v", got)
+	}
+	// The new overlay inherits the compression of the one that applied.
+	if o := s.Config().Match("tenant/acme/b"); o == nil || o.Prefix != "tenant/acme/" || o.Compression != CompressionGzip {
+		t.Errorf("Match() = %+v", o)
+	}
+	if err := s.Put("tenant/acme/b", strings.NewReader("x")); err != nil {
+		t.Errorf("Put() within quota error = %v", err)
+	}
+	if err := s.Put("tenant/acme/c", strings.NewReader("x")); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("Put() over quota error = %v", err)
+	}
+	if got := read(t, s, "tenant/acme/b"); got != "x" {
+		t.Errorf("Get() = %q", got)
+	}
+
+	// Raising the quota recounts the objects under the prefix.
+	if err := s.SetQuota("tenant/acme/", &Quota{MaxObjects: 3}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put("tenant/acme/c", strings.NewReader("x")); err != nil {
+		t.Errorf("Put() after raising quota error = %v", err)
+	}
+	if _, objects, _ := s.Usage(ctx, "tenant/acme/"); objects != 3 {
+		t.Errorf("Usage() = %d objects, want 3", objects)
+	}
+
+	if err := s.SetQuota("tenant/acme/", nil); err != nil {
+		t.Fatalf("SetQuota(nil) error = %v", err)
+	}
+	if err := s.Put("tenant/acme/d", strings.NewReader("x")); err != nil {
+		t.Errorf("Put() without quota error = %v", err)
+	}
+	if got := s.Quotas(); len(got) != 0 {
+		t.Errorf("Quotas() after removal = %v", got)
+	}
+	if err := s.SetQuota("tenant/acme/", nil); !errors.Is(err, common.ErrNotFound) {
+		t.Errorf("SetQuota(nil) without quota error = %v, want ErrNotFound", err)
+	}
+	if err := s.SetQuota("other/", &Quota{MaxBytes: -1}); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("SetQuota() negative error = %v, want ErrInvalidConfig", err)
+	}
+}
+
+func TestReplicationTriggeredByWrites(t *testing.T) {
+	manager := &syncCounter{}
+	cfg := &Config{
+		Overlays:    []Overlay{{Prefix: "critical/", Replication: "dr"}},
+		Replication: manager,
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	s := NewStorage(memory.New(), cfg)
+
+	if err := s.Put("scratch/tmp", strings.NewReader("x")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put("critical/ledger", strings.NewReader("x")); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for manager.syncs.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if manager.syncs.Load() == 0 {
+		t.Error("write under critical/ did not sync its replication policy")
+	}
+}
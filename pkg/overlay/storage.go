@@ -0,0 +1,618 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package overlay
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/filter"
+)
+
+// Storage wraps a backend and applies a Config's overlays to the objects
+// written through it. How each object was compressed and encrypted is
+// recorded in its custom metadata, so objects stay readable when the
+// overlay of their prefix changes, as long as some overlay still
+// compresses or encrypts. Metadata reads report the stored, not the
+// original, size of compressed objects.
+type Storage struct {
+	common.Storage
+	cfg atomic.Pointer[Config]
+
+	// transforms is set when any overlay compresses or encrypts, in which
+	// case reads check each object's metadata.
+	transforms bool
+
+	// mu also serializes quota changes, which replace cfg.
+	mu    sync.Mutex
+	usage map[string]*usage
+	syncs map[string]*syncState
+}
+
+// usage is the data stored under an overlay with a quota.
+type usage struct {
+	loaded  bool
+	bytes   int64
+	objects int64
+}
+
+// syncState coalesces the replication syncs triggered by writes.
+type syncState struct {
+	running bool
+	pending bool
+}
+
+// NewStorage returns underlying wrapped so that writes under cfg's
+// prefixes are transformed, quota checked and replicated. cfg must have
+// been validated.
+func NewStorage(underlying common.Storage, cfg *Config) *Storage {
+	s := &Storage{
+		Storage: underlying,
+		usage:   make(map[string]*usage),
+		syncs:   make(map[string]*syncState),
+	}
+	s.cfg.Store(cfg)
+	for i := range cfg.Overlays {
+		if cfg.Overlays[i].compresses() || cfg.Overlays[i].encrypts() {
+			s.transforms = true
+		}
+	}
+	return s
+}
+
+// Config returns the overlays applied by s. It must not be modified;
+// quotas are changed with SetQuota.
+func (s *Storage) Config() *Config {
+	return s.cfg.Load()
+}
+
+// Underlying returns the wrapped backend.
+func (s *Storage) Underlying() common.Storage {
+	return s.Storage
+}
+
+// Usage returns the bytes and objects stored under the overlay of prefix,
+// as counted for its quota. It lists the prefix on first use.
+func (s *Storage) Usage(ctx context.Context, prefix string) (bytes, objects int64, err error) {
+	o := s.Config().Match(prefix)
+	if o == nil || o.Prefix != prefix {
+		return 0, 0, fmt.Errorf("%w: no overlay for prefix %q", common.ErrNotFound, prefix)
+	}
+	u, err := s.loadUsage(ctx, o)
+	if err != nil {
+		return 0, 0, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return u.bytes, u.objects, nil
+}
+
+// Quotas returns the quotas of the overlays that have one, by prefix.
+func (s *Storage) Quotas() map[string]Quota {
+	cfg := s.Config()
+	quotas := make(map[string]Quota)
+	for i := range cfg.Overlays {
+		if o := &cfg.Overlays[i]; o.Quota != nil {
+			quotas[o.Prefix] = *o.Quota
+		}
+	}
+	return quotas
+}
+
+// SetQuota sets the quota of the overlay of prefix, or removes it when
+// quota is nil. A prefix without an overlay of its own gets one with the
+// settings of the overlay that applied to it, so objects under it are
+// still stored the same way. Changes last until the overlays are enabled
+// again, such as when a server restarts and reads its configuration file.
+func (s *Storage) SetQuota(prefix string, quota *Quota) error {
+	if quota != nil && (quota.MaxBytes < 0 || quota.MaxObjects < 0) {
+		return fmt.Errorf("%w: prefix %q: negative quota", ErrInvalidConfig, prefix)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	current := s.Config()
+	next := *current
+	next.Overlays = slices.Clone(current.Overlays)
+	i := slices.IndexFunc(next.Overlays, func(o Overlay) bool { return o.Prefix == prefix })
+	switch {
+	case i < 0 && quota == nil:
+		return fmt.Errorf("%w: no quota for prefix %q", common.ErrNotFound, prefix)
+	case i < 0:
+		o := Overlay{Prefix: prefix}
+		if parent := current.Match(prefix); parent != nil {
+			o = *parent
+			o.Prefix = prefix
+		}
+		next.Overlays = append(next.Overlays, o)
+		i = len(next.Overlays) - 1
+		// Objects under the new overlay no longer count against the
+		// quota of the one that applied before.
+		clear(s.usage)
+	case quota == nil && next.Overlays[i].Quota == nil:
+		return fmt.Errorf("%w: no quota for prefix %q", common.ErrNotFound, prefix)
+	}
+
+	if quota == nil {
+		next.Overlays[i].Quota = nil
+	} else {
+		q := *quota
+		next.Overlays[i].Quota = &q
+	}
+	// Usage is only kept up to date while a quota is set.
+	delete(s.usage, prefix)
+	s.cfg.Store(&next)
+	return nil
+}
+
+// Put stores an object under its overlay.
+func (s *Storage) Put(key string, data io.Reader) error {
+	return s.PutWithContext(context.Background(), key, data)
+}
+
+// PutWithContext stores an object under its overlay.
+func (s *Storage) PutWithContext(ctx context.Context, key string, data io.Reader) error {
+	if s.Config().Match(key) == nil {
+		return s.Storage.PutWithContext(ctx, key, data)
+	}
+	return s.PutWithMetadata(ctx, key, data, nil)
+}
+
+// PutWithMetadata compresses, encrypts and stores an object as its
+// overlay configures, within the overlay's quota. The caller's metadata
+// is not modified.
+func (s *Storage) PutWithMetadata(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) error {
+	return s.put(ctx, key, data, metadata, func(data io.Reader, stored *common.Metadata) error {
+		return s.Storage.PutWithMetadata(ctx, key, data, stored)
+	})
+}
+
+// PutWithInfo stores an object like PutWithMetadata and returns what was
+// stored.
+func (s *Storage) PutWithInfo(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) (info *common.ObjectInfo, err error) {
+	err = s.put(ctx, key, data, metadata, func(data io.Reader, stored *common.Metadata) error {
+		info, err = common.PutWithInfo(ctx, s.Storage, key, data, stored)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// put transforms an object as its overlay configures and writes it within
+// the overlay's quota.
+func (s *Storage) put(ctx context.Context, key string, data io.Reader, metadata *common.Metadata, write func(io.Reader, *common.Metadata) error) error {
+	o := s.Config().Match(key)
+	if o == nil {
+		return write(data, metadata)
+	}
+
+	stored := common.Metadata{}
+	if metadata != nil {
+		stored = *metadata
+	}
+	stored.Custom = make(map[string]string, len(stored.Custom)+3)
+	if metadata != nil {
+		for k, v := range metadata.Custom {
+			stored.Custom[k] = v
+		}
+	}
+	delete(stored.Custom, MetaCompression)
+	delete(stored.Custom, MetaKeyID)
+	delete(stored.Custom, MetaAlgorithm)
+	if stored.StorageClass == "" {
+		stored.StorageClass = o.StorageClass
+	}
+
+	if o.compresses() {
+		compressed, err := compress(o.Compression, data)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = compressed.Close() }()
+		data = compressed
+		stored.Custom[MetaCompression] = o.Compression
+	}
+	if o.encrypts() {
+		encrypter, err := s.Config().Encrypter.GetEncrypter(o.KeyID)
+		if err != nil {
+			return err
+		}
+		encrypted, err := encrypter.Encrypt(ctx, data)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = encrypted.Close() }()
+		data = encrypted
+		stored.Custom[MetaKeyID] = encrypter.KeyID()
+		stored.Custom[MetaAlgorithm] = encrypter.Algorithm()
+	}
+	if len(stored.Custom) == 0 {
+		stored.Custom = nil
+	}
+
+	if o.Quota == nil {
+		return s.changed(o, write(data, &stored))
+	}
+	counter, err := s.reserve(ctx, o, key, data)
+	if err != nil {
+		return err
+	}
+	err = write(counter, &stored)
+	if counter.err != nil {
+		err = counter.err
+	}
+	s.commit(counter, err)
+	return s.changed(o, err)
+}
+
+// Get reads an object, undoing the compression and encryption it was
+// stored with.
+func (s *Storage) Get(key string) (io.ReadCloser, error) {
+	return s.GetWithContext(context.Background(), key)
+}
+
+// GetWithContext reads an object, undoing the compression and encryption
+// it was stored with.
+func (s *Storage) GetWithContext(ctx context.Context, key string) (io.ReadCloser, error) {
+	if !s.transforms {
+		return s.Storage.GetWithContext(ctx, key)
+	}
+	metadata, err := s.Storage.GetMetadata(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	rc, err := s.Storage.GetWithContext(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return s.decode(ctx, metadata, rc)
+}
+
+// GetAsOf reads an earlier version of an object, undoing the compression
+// and encryption that version was stored with.
+func (s *Storage) GetAsOf(ctx context.Context, key string, at time.Time) (io.ReadCloser, *common.Metadata, error) {
+	rc, metadata, err := common.GetAsOf(ctx, s.Storage, key, at)
+	if err != nil {
+		return nil, nil, err
+	}
+	decoded, err := s.decode(ctx, metadata, rc)
+	if err != nil {
+		return nil, nil, err
+	}
+	return decoded, metadata, nil
+}
+
+// decode wraps rc, the stored content of an object with metadata, to
+// decrypt and decompress it.
+func (s *Storage) decode(ctx context.Context, metadata *common.Metadata, rc io.ReadCloser) (io.ReadCloser, error) {
+	if metadata == nil || metadata.Custom == nil {
+		return rc, nil
+	}
+	decoded := &readCloser{Reader: rc, closes: []io.Closer{rc}}
+	if keyID := metadata.Custom[MetaKeyID]; keyID != "" {
+		if s.Config().Encrypter == nil {
+			_ = rc.Close()
+			return nil, fmt.Errorf("%w: object is encrypted but no encrypter is configured", common.ErrNotConfigured)
+		}
+		encrypter, err := s.Config().Encrypter.GetEncrypter(keyID)
+		if err != nil {
+			_ = rc.Close()
+			return nil, err
+		}
+		plaintext, err := encrypter.Decrypt(ctx, decoded.Reader)
+		if err != nil {
+			_ = rc.Close()
+			return nil, err
+		}
+		decoded.Reader = plaintext
+		decoded.closes = append([]io.Closer{plaintext}, decoded.closes...)
+	}
+	if codec := metadata.Custom[MetaCompression]; codec != "" {
+		decompressed, err := filter.Decompress(codec, decoded.Reader)
+		if err != nil {
+			_ = decoded.Close()
+			return nil, err
+		}
+		decoded.Reader = decompressed
+		decoded.closes = append([]io.Closer{decompressed}, decoded.closes...)
+	}
+	return decoded, nil
+}
+
+// GetRange reads a byte range of an object. Compressed and encrypted
+// objects are read in full and the leading bytes discarded.
+func (s *Storage) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	if rr, ok := s.Storage.(common.RangeReader); ok && !s.transforms {
+		return rr.GetRange(ctx, key, offset, length)
+	}
+	rc, err := s.GetWithContext(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return common.SliceRange(rc, offset, length)
+}
+
+// Delete removes an object, releasing its quota.
+func (s *Storage) Delete(key string) error {
+	return s.DeleteWithContext(context.Background(), key)
+}
+
+// DeleteWithContext removes an object, releasing its quota.
+func (s *Storage) DeleteWithContext(ctx context.Context, key string) error {
+	o := s.Config().Match(key)
+	if o == nil {
+		return s.Storage.DeleteWithContext(ctx, key)
+	}
+	if o.Quota == nil {
+		return s.changed(o, s.Storage.DeleteWithContext(ctx, key))
+	}
+	u, err := s.loadUsage(ctx, o)
+	if err != nil {
+		return err
+	}
+	size, existed, err := s.storedSize(ctx, key)
+	if err != nil {
+		return err
+	}
+	if err := s.Storage.DeleteWithContext(ctx, key); err != nil {
+		return err
+	}
+	if existed {
+		s.mu.Lock()
+		u.bytes -= size
+		u.objects--
+		s.mu.Unlock()
+	}
+	return s.changed(o, nil)
+}
+
+// Append adds data to the end of an object. Objects under overlays that
+// transform or limit their data are rewritten through s.
+func (s *Storage) Append(ctx context.Context, key string, data io.Reader) error {
+	o := s.Config().Match(key)
+	if o == nil {
+		return common.Append(ctx, s.Storage, key, data)
+	}
+	if s.transforms || o.Quota != nil {
+		return common.EmulateAppend(ctx, s, key, data)
+	}
+	return s.changed(o, common.Append(ctx, s.Storage, key, data))
+}
+
+// Compose concatenates srcKeys into destKey. When objects may be
+// transformed or destKey has a quota, the sources are read and destKey
+// written through s.
+func (s *Storage) Compose(ctx context.Context, destKey string, srcKeys ...string) error {
+	o := s.Config().Match(destKey)
+	if s.transforms || (o != nil && o.Quota != nil) {
+		return common.EmulateCompose(ctx, s, destKey, srcKeys...)
+	}
+	err := common.Compose(ctx, s.Storage, destKey, srcKeys...)
+	if o == nil {
+		return err
+	}
+	return s.changed(o, err)
+}
+
+// changed syncs the replication policy of o after a successful change.
+// Syncs run in the background, one at a time per policy; changes made
+// while a sync runs are picked up by one more sync.
+func (s *Storage) changed(o *Overlay, err error) error {
+	if err != nil || o.Replication == "" || s.Config().Replication == nil {
+		return err
+	}
+	s.mu.Lock()
+	state := s.syncs[o.Replication]
+	if state == nil {
+		state = &syncState{}
+		s.syncs[o.Replication] = state
+	}
+	if state.running {
+		state.pending = true
+		s.mu.Unlock()
+		return nil
+	}
+	state.running = true
+	s.mu.Unlock()
+
+	go func(policyID string) {
+		for {
+			// Failures are recorded in the policy's sync status.
+			_, _ = s.Config().Replication.SyncPolicy(context.Background(), policyID) // #nosec G104 -- see above
+			s.mu.Lock()
+			if !state.pending {
+				state.running = false
+				s.mu.Unlock()
+				return
+			}
+			state.pending = false
+			s.mu.Unlock()
+		}
+	}(o.Replication)
+	return nil
+}
+
+// loadUsage returns the usage of o, listing its prefix the first time.
+// Objects under a more specific overlay are not counted.
+func (s *Storage) loadUsage(ctx context.Context, o *Overlay) (*usage, error) {
+	s.mu.Lock()
+	u := s.usage[o.Prefix]
+	if u == nil {
+		u = &usage{}
+		s.usage[o.Prefix] = u
+	}
+	loaded := u.loaded
+	s.mu.Unlock()
+	if loaded {
+		return u, nil
+	}
+
+	var bytes, objects int64
+	opts := &common.ListOptions{Prefix: o.Prefix}
+	for {
+		result, err := s.Storage.ListWithOptions(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range result.Objects {
+			if match := s.Config().Match(obj.Key); match == nil || match.Prefix != o.Prefix {
+				continue
+			}
+			objects++
+			if obj.Metadata != nil {
+				bytes += obj.Metadata.Size
+			}
+		}
+		if !result.Truncated || result.NextToken == "" {
+			break
+		}
+		opts.ContinueFrom = result.NextToken
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !u.loaded {
+		u.loaded, u.bytes, u.objects = true, bytes, objects
+	}
+	return u, nil
+}
+
+// storedSize returns the stored size of key and whether it exists.
+func (s *Storage) storedSize(ctx context.Context, key string) (int64, bool, error) {
+	metadata, err := s.Storage.GetMetadata(ctx, key)
+	if errors.Is(err, common.ErrNotFound) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return metadata.Size, true, nil
+}
+
+// quotaReader counts the bytes written under a quota and fails once they
+// exceed the bytes left.
+type quotaReader struct {
+	r       io.Reader
+	usage   *usage
+	prefix  string
+	left    int64 // negative when unlimited
+	n       int64
+	oldSize int64
+	existed bool
+	err     error
+}
+
+func (q *quotaReader) Read(p []byte) (int, error) {
+	n, err := q.r.Read(p)
+	q.n += int64(n)
+	if q.left >= 0 && q.n > q.left {
+		q.err = fmt.Errorf("%w: %w: prefix %q is out of space", common.ErrResourceExhausted, ErrQuotaExceeded, q.prefix)
+		return n, q.err
+	}
+	return n, err
+}
+
+// reserve checks that writing key does not exceed the object quota of o
+// and returns data wrapped to enforce its byte quota. Concurrent writers
+// under the same prefix may together exceed the byte quota by up to one
+// object each.
+func (s *Storage) reserve(ctx context.Context, o *Overlay, key string, data io.Reader) (*quotaReader, error) {
+	u, err := s.loadUsage(ctx, o)
+	if err != nil {
+		return nil, err
+	}
+	oldSize, existed, err := s.storedSize(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !existed && o.Quota.MaxObjects > 0 && u.objects >= o.Quota.MaxObjects {
+		return nil, fmt.Errorf("%w: %w: prefix %q holds %d objects", common.ErrResourceExhausted, ErrQuotaExceeded, o.Prefix, u.objects)
+	}
+	left := int64(-1)
+	if o.Quota.MaxBytes > 0 {
+		left = max(o.Quota.MaxBytes-u.bytes+oldSize, 0)
+	}
+	return &quotaReader{r: data, usage: u, prefix: o.Prefix, left: left, oldSize: oldSize, existed: existed}, nil
+}
+
+// commit records a write through q in the usage it was reserved from.
+func (s *Storage) commit(q *quotaReader, err error) {
+	if err != nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	q.usage.bytes += q.n - q.oldSize
+	if !q.existed {
+		q.usage.objects++
+	}
+}
+
+// compress returns a reader of data compressed with codec. Closing it
+// stops the compression.
+func compress(codec string, data io.Reader) (io.ReadCloser, error) {
+	pr, pw := io.Pipe()
+	var w io.WriteCloser
+	switch codec {
+	case CompressionGzip:
+		w = gzip.NewWriter(pw)
+	case CompressionZstd:
+		zw, err := zstd.NewWriter(pw, zstd.WithEncoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		w = zw
+	default:
+		return nil, fmt.Errorf("%w: unsupported compression %q", common.ErrInvalidArgument, codec)
+	}
+	go func() {
+		_, err := io.Copy(w, data)
+		if closeErr := w.Close(); err == nil {
+			err = closeErr
+		}
+		_ = pw.CloseWithError(err) // #nosec G104 -- CloseWithError always returns nil
+	}()
+	return pr, nil
+}
+
+// readCloser reads from Reader and closes every stream in closes.
+type readCloser struct {
+	io.Reader
+	closes []io.Closer
+}
+
+func (rc *readCloser) Close() error {
+	var first error
+	for _, c := range rc.closes {
+		if err := c.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
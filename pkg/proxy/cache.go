@@ -0,0 +1,335 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package proxy
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrTooLarge is returned when an object does not fit in the cache.
+	ErrTooLarge = errors.New("object larger than cache")
+
+	// ErrSizeMismatch is returned when the bytes written to the cache do not
+	// match the size announced by the origin.
+	ErrSizeMismatch = errors.New("cached object size mismatch")
+)
+
+const (
+	dataSuffix = ".data"
+	metaSuffix = ".json"
+)
+
+// Entry describes one cached object.
+type Entry struct {
+	Key          string    `json:"key"`
+	ETag         string    `json:"etag"`
+	Size         int64     `json:"size"`
+	ContentType  string    `json:"content_type,omitempty"`
+	LastModified time.Time `json:"last_modified"`
+
+	// Validated is when the entry was last confirmed current with the
+	// origin.
+	Validated time.Time `json:"validated"`
+
+	name string
+}
+
+// DiskCache is a size-bounded LRU cache of objects on local disk. Each
+// object is stored as a data file and a JSON sidecar named by the SHA-256 of
+// its key, so the cache survives restarts. It is safe for concurrent use.
+type DiskCache struct {
+	dir      string
+	maxBytes int64
+
+	mu      sync.Mutex
+	lru     *list.List // front is most recently used
+	entries map[string]*list.Element
+	used    int64
+}
+
+// NewDiskCache opens the cache in dir, creating it if needed, and loads the
+// entries already present. Entries beyond maxBytes are evicted oldest first.
+func NewDiskCache(dir string, maxBytes int64) (*DiskCache, error) {
+	if maxBytes <= 0 {
+		return nil, fmt.Errorf("cache size must be positive, got %d", maxBytes)
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	c := &DiskCache{
+		dir:      dir,
+		maxBytes: maxBytes,
+		lru:      list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// load rebuilds the index from the sidecars in the cache directory. Partial
+// writes and entries whose data file is missing or truncated are removed.
+func (c *DiskCache) load() error {
+	files, err := os.ReadDir(c.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var loaded []*Entry
+	accessed := make(map[*Entry]time.Time)
+	for _, f := range files {
+		name := f.Name()
+		if strings.HasPrefix(name, ".tmp-") || strings.HasSuffix(name, ".tmp") {
+			_ = os.Remove(filepath.Join(c.dir, name))
+			continue
+		}
+		if !strings.HasSuffix(name, metaSuffix) {
+			continue
+		}
+		base := strings.TrimSuffix(name, metaSuffix)
+		entry, atime, err := c.readMeta(base)
+		if err != nil {
+			c.removeFiles(base)
+			continue
+		}
+		loaded = append(loaded, entry)
+		accessed[entry] = atime
+	}
+
+	// Push oldest access first, so the most recent end up at the front.
+	sort.Slice(loaded, func(i, j int) bool {
+		return accessed[loaded[i]].Before(accessed[loaded[j]])
+	})
+	for _, entry := range loaded {
+		c.entries[entry.Key] = c.lru.PushFront(entry)
+		c.used += entry.Size
+	}
+	c.evictLocked(0)
+	return nil
+}
+
+// readMeta loads the sidecar named base and checks it against its data
+// file. The data file's modification time is the entry's last access.
+func (c *DiskCache) readMeta(base string) (*Entry, time.Time, error) {
+	data, err := os.ReadFile(filepath.Join(c.dir, base+metaSuffix)) // #nosec G304 -- base is a hex digest
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	var entry Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, time.Time{}, err
+	}
+	if entryName(entry.Key) != base {
+		return nil, time.Time{}, fmt.Errorf("sidecar %s does not match key %q", base, entry.Key)
+	}
+	info, err := os.Stat(filepath.Join(c.dir, base+dataSuffix))
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	if info.Size() != entry.Size {
+		return nil, time.Time{}, ErrSizeMismatch
+	}
+	entry.name = base
+	return &entry, info.ModTime(), nil
+}
+
+// Lookup returns the entry for key and marks it most recently used.
+func (c *DiskCache) Lookup(key string) (*Entry, bool) {
+	c.mu.Lock()
+	elem, ok := c.entries[key]
+	if !ok {
+		c.mu.Unlock()
+		return nil, false
+	}
+	c.lru.MoveToFront(elem)
+	entry := *elem.Value.(*Entry)
+	c.mu.Unlock()
+
+	// Persist the access so the LRU order survives a restart.
+	now := time.Now()
+	_ = os.Chtimes(filepath.Join(c.dir, entry.name+dataSuffix), now, now)
+	return &entry, true
+}
+
+// Open opens the cached data of entry for reading.
+func (c *DiskCache) Open(entry *Entry) (*os.File, error) {
+	return os.Open(filepath.Join(c.dir, entry.name+dataSuffix)) // #nosec G304 -- name is a hex digest
+}
+
+// Touch records that entry was revalidated against the origin at t.
+func (c *DiskCache) Touch(key string, t time.Time) {
+	c.mu.Lock()
+	elem, ok := c.entries[key]
+	if !ok {
+		c.mu.Unlock()
+		return
+	}
+	entry := elem.Value.(*Entry)
+	entry.Validated = t
+	snapshot := *entry
+	c.mu.Unlock()
+	_ = c.writeMeta(&snapshot)
+}
+
+// Store copies r into the cache as the new content of entry.Key and returns
+// the stored entry. The write goes to a temporary file that replaces the
+// previous content only once it is complete, so readers never see a partial
+// object. A positive entry.Size must match the number of bytes copied.
+func (c *DiskCache) Store(entry Entry, r io.Reader) (*Entry, error) {
+	if entry.Size > c.maxBytes {
+		return nil, ErrTooLarge
+	}
+
+	tmp, err := os.CreateTemp(c.dir, ".tmp-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cache file: %w", err)
+	}
+	tmpName := tmp.Name()
+	defer func() { _ = os.Remove(tmpName) }()
+
+	limit := c.maxBytes + 1
+	n, err := io.Copy(tmp, io.LimitReader(r, limit))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if n > c.maxBytes {
+		return nil, ErrTooLarge
+	}
+	if entry.Size > 0 && n != entry.Size {
+		return nil, fmt.Errorf("%w: expected %d bytes, got %d", ErrSizeMismatch, entry.Size, n)
+	}
+	entry.Size = n
+	entry.name = entryName(entry.Key)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := os.Rename(tmpName, filepath.Join(c.dir, entry.name+dataSuffix)); err != nil {
+		return nil, fmt.Errorf("failed to commit cache file: %w", err)
+	}
+	if err := c.writeMeta(&entry); err != nil {
+		c.removeFiles(entry.name)
+		c.dropLocked(entry.Key)
+		return nil, err
+	}
+
+	c.dropLocked(entry.Key)
+	c.evictLocked(entry.Size)
+	c.entries[entry.Key] = c.lru.PushFront(&entry)
+	c.used += entry.Size
+	stored := entry
+	return &stored, nil
+}
+
+// MaxBytes returns the cache size limit in bytes.
+func (c *DiskCache) MaxBytes() int64 {
+	return c.maxBytes
+}
+
+// Remove deletes key from the cache.
+func (c *DiskCache) Remove(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.dropLocked(key) {
+		c.removeFiles(entryName(key))
+	}
+}
+
+// Len returns the number of cached objects.
+func (c *DiskCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// Used returns the total size of the cached objects in bytes.
+func (c *DiskCache) Used() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.used
+}
+
+// dropLocked removes key from the index without touching its files.
+func (c *DiskCache) dropLocked(key string) bool {
+	elem, ok := c.entries[key]
+	if !ok {
+		return false
+	}
+	c.used -= elem.Value.(*Entry).Size
+	c.lru.Remove(elem)
+	delete(c.entries, key)
+	return true
+}
+
+// evictLocked removes least recently used entries until incoming more bytes
+// fit within the cache size.
+func (c *DiskCache) evictLocked(incoming int64) {
+	for c.used+incoming > c.maxBytes {
+		elem := c.lru.Back()
+		if elem == nil {
+			return
+		}
+		entry := elem.Value.(*Entry)
+		c.dropLocked(entry.Key)
+		c.removeFiles(entry.name)
+	}
+}
+
+func (c *DiskCache) writeMeta(entry *Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(c.dir, entry.name+metaSuffix)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write cache metadata: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to write cache metadata: %w", err)
+	}
+	return nil
+}
+
+func (c *DiskCache) removeFiles(name string) {
+	_ = os.Remove(filepath.Join(c.dir, name+dataSuffix))
+	_ = os.Remove(filepath.Join(c.dir, name+metaSuffix))
+}
+
+// entryName returns the file name stem for key.
+func entryName(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,339 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package proxy implements a requester-side caching HTTP proxy for objstore.
+//
+// The proxy serves GET and HEAD requests for objects from a local disk LRU
+// cache, fetching misses from an origin (a remote objstore server or a
+// storage backend). Cached objects are revalidated against the origin's
+// ETag before they are served, so repeated downloads of the same artifact
+// cost one metadata round trip instead of a full transfer.
+package proxy
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/validation"
+)
+
+// Cache states reported in the X-Cache response header.
+const (
+	// CacheHit means the object was served from the cache without
+	// contacting the origin.
+	CacheHit = "HIT"
+
+	// CacheRevalidated means the cached object was served after the origin
+	// confirmed its ETag.
+	CacheRevalidated = "REVALIDATED"
+
+	// CacheMiss means the object was fetched from the origin.
+	CacheMiss = "MISS"
+
+	// CacheStale means the cached object was served because the origin
+	// could not be reached to revalidate it.
+	CacheStale = "STALE"
+
+	// CacheBypass means the object was too large to cache and was streamed
+	// straight from the origin.
+	CacheBypass = "BYPASS"
+)
+
+// Origin is the upstream the proxy fetches objects from. The remote clients
+// in pkg/cli/client implement it; use StorageOrigin for a backend.
+type Origin interface {
+	Get(ctx context.Context, key string) (io.ReadCloser, *common.Metadata, error)
+	GetMetadata(ctx context.Context, key string) (*common.Metadata, error)
+}
+
+// StorageOrigin adapts a storage backend to Origin.
+func StorageOrigin(storage common.Storage) Origin {
+	return storageOrigin{storage}
+}
+
+type storageOrigin struct {
+	storage common.Storage
+}
+
+func (o storageOrigin) Get(ctx context.Context, key string) (io.ReadCloser, *common.Metadata, error) {
+	metadata, err := o.storage.GetMetadata(ctx, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	rc, err := o.storage.GetWithContext(ctx, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return rc, metadata, nil
+}
+
+func (o storageOrigin) GetMetadata(ctx context.Context, key string) (*common.Metadata, error) {
+	return o.storage.GetMetadata(ctx, key)
+}
+
+// Config configures a Proxy.
+type Config struct {
+	// RevalidateAfter is how long a cached object is served without
+	// checking its ETag with the origin. Zero revalidates on every request.
+	RevalidateAfter time.Duration
+}
+
+// Proxy is an http.Handler that serves objects from a DiskCache in front of
+// an Origin. It answers GET and HEAD on /objects/{key} and, so existing
+// REST clients can point at it unchanged, /api/v1/objects/{key}. GET
+// requests support Range and If-None-Match.
+type Proxy struct {
+	origin          Origin
+	cache           *DiskCache
+	revalidateAfter time.Duration
+	now             func() time.Time
+
+	mu       sync.Mutex
+	inflight map[string]*fill
+}
+
+// fill is an in-progress fetch of one key that concurrent misses wait on.
+type fill struct {
+	done  chan struct{}
+	entry *Entry
+	err   error
+}
+
+// New returns a Proxy serving origin through cache.
+func New(origin Origin, cache *DiskCache, cfg Config) *Proxy {
+	return &Proxy{
+		origin:          origin,
+		cache:           cache,
+		revalidateAfter: cfg.RevalidateAfter,
+		now:             time.Now,
+		inflight:        make(map[string]*fill),
+	}
+}
+
+// ServeHTTP implements http.Handler.
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key, ok := objectKey(r.URL.Path)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if err := validation.ValidateKey(key); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx := r.Context()
+	state := CacheHit
+	entry, hit := p.cache.Lookup(key)
+	if hit && p.now().Sub(entry.Validated) >= p.revalidateAfter {
+		metadata, err := p.origin.GetMetadata(ctx, key)
+		switch {
+		case errors.Is(err, common.ErrNotFound):
+			p.cache.Remove(key)
+			http.NotFound(w, r)
+			return
+		case err != nil:
+			state = CacheStale
+		case sameETag(originETag(metadata), entry.ETag):
+			p.cache.Touch(key, p.now())
+			state = CacheRevalidated
+		default:
+			hit = false
+		}
+	}
+
+	if !hit {
+		if r.Method == http.MethodHead {
+			p.headFromOrigin(w, r, key)
+			return
+		}
+		var err error
+		entry, err = p.fill(ctx, key)
+		if errors.Is(err, ErrTooLarge) {
+			p.streamFromOrigin(w, r, key)
+			return
+		}
+		if err != nil {
+			writeOriginError(w, r, err)
+			return
+		}
+		state = CacheMiss
+	}
+
+	f, err := p.cache.Open(entry)
+	if err != nil {
+		// Evicted between lookup and open.
+		p.streamFromOrigin(w, r, key)
+		return
+	}
+	defer func() { _ = f.Close() }()
+
+	setHeaders(w, entry.ETag, entry.ContentType, state)
+	http.ServeContent(w, r, "", entry.LastModified, f)
+}
+
+// fill fetches key from the origin into the cache. Concurrent calls for the
+// same key share one fetch, which is not cancelled when the request that
+// started it goes away.
+func (p *Proxy) fill(ctx context.Context, key string) (*Entry, error) {
+	p.mu.Lock()
+	if f, ok := p.inflight[key]; ok {
+		p.mu.Unlock()
+		select {
+		case <-f.done:
+			return f.entry, f.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	f := &fill{done: make(chan struct{})}
+	p.inflight[key] = f
+	p.mu.Unlock()
+
+	f.entry, f.err = p.fetch(context.WithoutCancel(ctx), key)
+
+	p.mu.Lock()
+	delete(p.inflight, key)
+	p.mu.Unlock()
+	close(f.done)
+	return f.entry, f.err
+}
+
+func (p *Proxy) fetch(ctx context.Context, key string) (*Entry, error) {
+	rc, metadata, err := p.origin.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rc.Close() }()
+
+	if metadata == nil {
+		metadata = &common.Metadata{}
+	}
+	if metadata.Size > p.cache.MaxBytes() {
+		return nil, ErrTooLarge
+	}
+	lastModified := metadata.LastModified
+	if lastModified.IsZero() {
+		lastModified = p.now()
+	}
+	return p.cache.Store(Entry{
+		Key:          key,
+		ETag:         originETag(metadata),
+		Size:         metadata.Size,
+		ContentType:  metadata.ContentType,
+		LastModified: lastModified,
+		Validated:    p.now(),
+	}, rc)
+}
+
+// headFromOrigin answers a HEAD request for an uncached object from the
+// origin's metadata without downloading it.
+func (p *Proxy) headFromOrigin(w http.ResponseWriter, r *http.Request, key string) {
+	metadata, err := p.origin.GetMetadata(r.Context(), key)
+	if err != nil {
+		writeOriginError(w, r, err)
+		return
+	}
+	setHeaders(w, originETag(metadata), metadata.ContentType, CacheMiss)
+	w.Header().Set("Content-Length", strconv.FormatInt(metadata.Size, 10))
+	if !metadata.LastModified.IsZero() {
+		w.Header().Set("Last-Modified", metadata.LastModified.UTC().Format(http.TimeFormat))
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// streamFromOrigin copies an object straight from the origin to the client.
+func (p *Proxy) streamFromOrigin(w http.ResponseWriter, r *http.Request, key string) {
+	rc, metadata, err := p.origin.Get(r.Context(), key)
+	if err != nil {
+		writeOriginError(w, r, err)
+		return
+	}
+	defer func() { _ = rc.Close() }()
+
+	if metadata == nil {
+		metadata = &common.Metadata{}
+	}
+	setHeaders(w, originETag(metadata), metadata.ContentType, CacheBypass)
+	if metadata.Size > 0 {
+		w.Header().Set("Content-Length", strconv.FormatInt(metadata.Size, 10))
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.Copy(w, rc)
+}
+
+// objectKey extracts the object key from an /objects/ or /api/v1/objects/
+// request path.
+func objectKey(path string) (string, bool) {
+	for _, prefix := range []string{"/api/v1/objects/", "/objects/"} {
+		if key, ok := strings.CutPrefix(path, prefix); ok && key != "" {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+func setHeaders(w http.ResponseWriter, etag, contentType, state string) {
+	if etag != "" {
+		w.Header().Set("ETag", `"`+etag+`"`)
+	}
+	if contentType != "" {
+		w.Header().Set("Content-Type", contentType)
+	}
+	w.Header().Set("X-Cache", state)
+}
+
+func writeOriginError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, common.ErrNotFound) {
+		http.NotFound(w, r)
+		return
+	}
+	http.Error(w, "origin error: "+err.Error(), http.StatusBadGateway)
+}
+
+// originETag returns the origin's validator for an object without quotes:
+// its strong validator when it has one, so the validator survives failover
+// to a replica whose backend derives ETags differently, and otherwise its
+// ETag. Backends that report no ETag are validated by size and
+// modification time.
+func originETag(metadata *common.Metadata) string {
+	if metadata == nil {
+		return ""
+	}
+	if validator := common.StrongValidator(metadata); validator != "" {
+		return validator
+	}
+	if etag := common.NormalizeETag(metadata.ETag); etag != "" {
+		return etag
+	}
+	if metadata.LastModified.IsZero() {
+		return ""
+	}
+	return strconv.FormatInt(metadata.Size, 10) + "-" + strconv.FormatInt(metadata.LastModified.UnixNano(), 36)
+}
+
+func sameETag(a, b string) bool {
+	return a != "" && a == b
+}
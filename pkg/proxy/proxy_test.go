@@ -0,0 +1,348 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/local"
+)
+
+type object struct {
+	data []byte
+	etag string
+}
+
+// fakeOrigin is an in-memory Origin that counts calls.
+type fakeOrigin struct {
+	mu       sync.Mutex
+	objects  map[string]object
+	gets     int
+	heads    int
+	err      error
+	getDelay time.Duration
+}
+
+func newFakeOrigin() *fakeOrigin {
+	return &fakeOrigin{objects: make(map[string]object)}
+}
+
+func (o *fakeOrigin) put(key, data string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.objects[key] = object{data: []byte(data), etag: fmt.Sprintf("etag-%d", len(o.objects)+o.gets+len(data))}
+}
+
+func (o *fakeOrigin) lookup(key string) (object, error) {
+	if o.err != nil {
+		return object{}, o.err
+	}
+	obj, ok := o.objects[key]
+	if !ok {
+		return object{}, common.ProviderError(common.ErrNotFound, key, errors.New("missing"))
+	}
+	return obj, nil
+}
+
+func (o *fakeOrigin) Get(_ context.Context, key string) (io.ReadCloser, *common.Metadata, error) {
+	o.mu.Lock()
+	o.gets++
+	obj, err := o.lookup(key)
+	delay := o.getDelay
+	o.mu.Unlock()
+	if err != nil {
+		return nil, nil, err
+	}
+	time.Sleep(delay)
+	md := &common.Metadata{ETag: `"` + obj.etag + `"`, Size: int64(len(obj.data)), ContentType: "application/octet-stream"}
+	return io.NopCloser(bytes.NewReader(obj.data)), md, nil
+}
+
+func (o *fakeOrigin) GetMetadata(_ context.Context, key string) (*common.Metadata, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.heads++
+	obj, err := o.lookup(key)
+	if err != nil {
+		return nil, err
+	}
+	return &common.Metadata{ETag: obj.etag, Size: int64(len(obj.data))}, nil
+}
+
+func (o *fakeOrigin) counts() (int, int) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.gets, o.heads
+}
+
+func newTestProxy(t *testing.T, origin Origin, maxBytes int64, cfg Config) *Proxy {
+	t.Helper()
+	cache, err := NewDiskCache(t.TempDir(), maxBytes)
+	if err != nil {
+		t.Fatalf("NewDiskCache: %v", err)
+	}
+	return New(origin, cache, cfg)
+}
+
+func do(p *Proxy, method, path string, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	p.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestProxy_MissThenRevalidatedHit(t *testing.T) {
+	origin := newFakeOrigin()
+	origin.put("builds/app.tar", "artifact-v1")
+	p := newTestProxy(t, origin, 1<<20, Config{})
+
+	rec := do(p, http.MethodGet, "/objects/builds/app.tar", nil)
+	if rec.Code != http.StatusOK || rec.Body.String() != "artifact-v1" {
+		t.Fatalf("miss: got %d %q", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("X-Cache"); got != CacheMiss {
+		t.Errorf("X-Cache = %q, want %q", got, CacheMiss)
+	}
+	etag := rec.Header().Get("ETag")
+
+	rec = do(p, http.MethodGet, "/api/v1/objects/builds/app.tar", nil)
+	if rec.Code != http.StatusOK || rec.Body.String() != "artifact-v1" {
+		t.Fatalf("hit: got %d %q", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("X-Cache"); got != CacheRevalidated {
+		t.Errorf("X-Cache = %q, want %q", got, CacheRevalidated)
+	}
+	if gets, heads := origin.counts(); gets != 1 || heads != 1 {
+		t.Errorf("origin calls = %d gets, %d heads; want 1, 1", gets, heads)
+	}
+
+	rec = do(p, http.MethodGet, "/objects/builds/app.tar", map[string]string{"If-None-Match": etag})
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("If-None-Match: got %d, want 304", rec.Code)
+	}
+}
+
+func TestProxy_ChangedObjectRefetched(t *testing.T) {
+	origin := newFakeOrigin()
+	origin.put("k", "old")
+	p := newTestProxy(t, origin, 1<<20, Config{})
+
+	do(p, http.MethodGet, "/objects/k", nil)
+	origin.put("k", "newer")
+
+	rec := do(p, http.MethodGet, "/objects/k", nil)
+	if rec.Body.String() != "newer" || rec.Header().Get("X-Cache") != CacheMiss {
+		t.Fatalf("got %q (%s), want refetched content", rec.Body.String(), rec.Header().Get("X-Cache"))
+	}
+}
+
+func TestProxy_RevalidateAfter(t *testing.T) {
+	origin := newFakeOrigin()
+	origin.put("k", "data")
+	p := newTestProxy(t, origin, 1<<20, Config{RevalidateAfter: time.Minute})
+	now := time.Now()
+	p.now = func() time.Time { return now }
+
+	do(p, http.MethodGet, "/objects/k", nil)
+	rec := do(p, http.MethodGet, "/objects/k", nil)
+	if got := rec.Header().Get("X-Cache"); got != CacheHit {
+		t.Errorf("X-Cache = %q, want %q", got, CacheHit)
+	}
+	if _, heads := origin.counts(); heads != 0 {
+		t.Errorf("origin revalidated %d times within the TTL", heads)
+	}
+
+	now = now.Add(2 * time.Minute)
+	rec = do(p, http.MethodGet, "/objects/k", nil)
+	if got := rec.Header().Get("X-Cache"); got != CacheRevalidated {
+		t.Errorf("X-Cache = %q, want %q", got, CacheRevalidated)
+	}
+}
+
+func TestProxy_OriginErrors(t *testing.T) {
+	origin := newFakeOrigin()
+	origin.put("k", "data")
+	p := newTestProxy(t, origin, 1<<20, Config{})
+
+	if rec := do(p, http.MethodGet, "/objects/missing", nil); rec.Code != http.StatusNotFound {
+		t.Errorf("missing object: got %d, want 404", rec.Code)
+	}
+
+	do(p, http.MethodGet, "/objects/k", nil)
+	origin.err = errors.New("connection refused")
+	rec := do(p, http.MethodGet, "/objects/k", nil)
+	if rec.Code != http.StatusOK || rec.Header().Get("X-Cache") != CacheStale {
+		t.Errorf("unreachable origin: got %d %s, want stale copy", rec.Code, rec.Header().Get("X-Cache"))
+	}
+	if rec := do(p, http.MethodGet, "/objects/other", nil); rec.Code != http.StatusBadGateway {
+		t.Errorf("uncached with unreachable origin: got %d, want 502", rec.Code)
+	}
+
+	origin.err = nil
+	delete(origin.objects, "k")
+	if rec := do(p, http.MethodGet, "/objects/k", nil); rec.Code != http.StatusNotFound {
+		t.Errorf("deleted object: got %d, want 404", rec.Code)
+	}
+	if p.cache.Len() != 0 {
+		t.Errorf("deleted object still cached")
+	}
+}
+
+func TestProxy_RangeHeadAndMethods(t *testing.T) {
+	origin := newFakeOrigin()
+	origin.put("k", "0123456789")
+	p := newTestProxy(t, origin, 1<<20, Config{})
+
+	rec := do(p, http.MethodHead, "/objects/k", nil)
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Length") != "10" || rec.Body.Len() != 0 {
+		t.Errorf("HEAD: got %d length %q body %d", rec.Code, rec.Header().Get("Content-Length"), rec.Body.Len())
+	}
+	if gets, _ := origin.counts(); gets != 0 {
+		t.Errorf("HEAD downloaded the object")
+	}
+
+	rec = do(p, http.MethodGet, "/objects/k", map[string]string{"Range": "bytes=2-4"})
+	if rec.Code != http.StatusPartialContent || rec.Body.String() != "234" {
+		t.Errorf("Range: got %d %q", rec.Code, rec.Body.String())
+	}
+
+	if rec := do(p, http.MethodPut, "/objects/k", nil); rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("PUT: got %d, want 405", rec.Code)
+	}
+	if rec := do(p, http.MethodGet, "/health", nil); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown path: got %d, want 404", rec.Code)
+	}
+}
+
+func TestProxy_LargeObjectBypassesCache(t *testing.T) {
+	origin := newFakeOrigin()
+	origin.put("big", strings.Repeat("x", 64))
+	p := newTestProxy(t, origin, 16, Config{})
+
+	rec := do(p, http.MethodGet, "/objects/big", nil)
+	if rec.Code != http.StatusOK || rec.Body.Len() != 64 || rec.Header().Get("X-Cache") != CacheBypass {
+		t.Fatalf("got %d, %d bytes, %s", rec.Code, rec.Body.Len(), rec.Header().Get("X-Cache"))
+	}
+	if p.cache.Len() != 0 {
+		t.Errorf("large object was cached")
+	}
+}
+
+func TestProxy_ConcurrentMissesShareFetch(t *testing.T) {
+	origin := newFakeOrigin()
+	origin.put("k", "shared")
+	origin.getDelay = 50 * time.Millisecond
+	p := newTestProxy(t, origin, 1<<20, Config{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if rec := do(p, http.MethodGet, "/objects/k", nil); rec.Body.String() != "shared" {
+				t.Errorf("got %q", rec.Body.String())
+			}
+		}()
+	}
+	wg.Wait()
+	if gets, _ := origin.counts(); gets != 1 {
+		t.Errorf("origin fetched %d times, want 1", gets)
+	}
+}
+
+func TestDiskCache_EvictionAndReload(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewDiskCache(dir, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"a", "b", "c"} {
+		if _, err := cache.Store(Entry{Key: key, ETag: key, Size: 4}, strings.NewReader("data")); err != nil {
+			t.Fatalf("Store(%s): %v", key, err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if _, ok := cache.Lookup("a"); ok {
+		t.Error("least recently used entry was not evicted")
+	}
+	if cache.Used() != 8 || cache.Len() != 2 {
+		t.Errorf("used %d bytes in %d entries, want 8 in 2", cache.Used(), cache.Len())
+	}
+
+	if _, err := cache.Store(Entry{Key: "d", Size: 11}, strings.NewReader(strings.Repeat("x", 11))); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("oversized Store: got %v, want ErrTooLarge", err)
+	}
+	if _, err := cache.Store(Entry{Key: "e", Size: 5}, strings.NewReader("abc")); !errors.Is(err, ErrSizeMismatch) {
+		t.Errorf("truncated Store: got %v, want ErrSizeMismatch", err)
+	}
+
+	reopened, err := NewDiskCache(dir, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, ok := reopened.Lookup("c")
+	if !ok || entry.ETag != "c" {
+		t.Fatalf("entry not reloaded: %+v", entry)
+	}
+	f, err := reopened.Open(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	if data, _ := io.ReadAll(f); string(data) != "data" {
+		t.Errorf("reloaded data = %q", data)
+	}
+	if reopened.Len() != 2 {
+		t.Errorf("reloaded %d entries, want 2", reopened.Len())
+	}
+}
+
+func TestStorageOrigin(t *testing.T) {
+	storage := local.New()
+	if err := storage.Configure(map[string]string{"path": t.TempDir()}); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.Put("dist/lib.so", strings.NewReader("binary")); err != nil {
+		t.Fatal(err)
+	}
+	p := newTestProxy(t, StorageOrigin(storage), 1<<20, Config{})
+
+	for _, want := range []string{CacheMiss, CacheRevalidated} {
+		rec := do(p, http.MethodGet, "/objects/dist/lib.so", nil)
+		if rec.Body.String() != "binary" || rec.Header().Get("X-Cache") != want {
+			t.Errorf("got %q (%s), want %s", rec.Body.String(), rec.Header().Get("X-Cache"), want)
+		}
+	}
+	if rec := do(p, http.MethodGet, "/objects/dist/missing.so", nil); rec.Code != http.StatusNotFound {
+		t.Errorf("missing object: got %d, want 404", rec.Code)
+	}
+
+	if _, err := NewDiskCache(t.TempDir(), 0); err == nil {
+		t.Error("expected error for zero cache size")
+	}
+}
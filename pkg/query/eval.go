@@ -0,0 +1,933 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Values are nil (NULL or MISSING), bool, int64, float64, string,
+// map[string]any or []any.
+
+// record is one row of input: named values in column order.
+type record struct {
+	names  []string
+	values []any
+}
+
+// column returns the value of the named column. Unquoted names match case
+// insensitively. CSV columns can also be named by position, _1 first.
+func (r *record) column(name string, exact bool) (any, bool) {
+	for i, n := range r.names {
+		if n == name {
+			return r.values[i], true
+		}
+	}
+	if !exact {
+		for i, n := range r.names {
+			if strings.EqualFold(n, name) {
+				return r.values[i], true
+			}
+		}
+	}
+	if len(name) > 1 && name[0] == '_' {
+		if i, err := strconv.Atoi(name[1:]); err == nil && i >= 1 && i <= len(r.values) {
+			return r.values[i-1], true
+		}
+	}
+	return nil, false
+}
+
+// env is what expressions are evaluated against.
+type env struct {
+	rec *record
+
+	// aggs holds the results of the query's aggregates once all records
+	// have been read.
+	aggs []any
+}
+
+type expr interface {
+	eval(e *env) (any, error)
+}
+
+type literal struct {
+	value any
+}
+
+func (l *literal) eval(*env) (any, error) {
+	return l.value, nil
+}
+
+// pathElem is a field name, or an array index when index >= 0.
+type pathElem struct {
+	name  string
+	exact bool
+	index int
+}
+
+type columnRef struct {
+	path []pathElem
+}
+
+func (c *columnRef) eval(e *env) (any, error) {
+	if e.rec == nil {
+		return nil, nil
+	}
+	first := c.path[0]
+	if first.index >= 0 {
+		return nil, nil
+	}
+	v, ok := e.rec.column(first.name, first.exact)
+	if !ok {
+		return nil, nil
+	}
+	for _, elem := range c.path[1:] {
+		switch container := v.(type) {
+		case map[string]any:
+			if elem.index >= 0 {
+				return nil, nil
+			}
+			v, ok = lookupField(container, elem)
+			if !ok {
+				return nil, nil
+			}
+		case []any:
+			if elem.index < 0 || elem.index >= len(container) {
+				return nil, nil
+			}
+			v = container[elem.index]
+		default:
+			return nil, nil
+		}
+	}
+	return v, nil
+}
+
+func lookupField(m map[string]any, elem pathElem) (any, bool) {
+	if v, ok := m[elem.name]; ok {
+		return v, true
+	}
+	if !elem.exact {
+		for k, v := range m {
+			if strings.EqualFold(k, elem.name) {
+				return v, true
+			}
+		}
+	}
+	return nil, false
+}
+
+type logical struct {
+	op          string
+	left, right expr
+}
+
+// eval implements three-valued logic: NULL AND FALSE is FALSE, NULL OR
+// TRUE is TRUE, and other combinations with NULL are NULL.
+func (l *logical) eval(e *env) (any, error) {
+	left, err := evalBool(l.left, e)
+	if err != nil {
+		return nil, err
+	}
+	if left != nil && *left == (l.op == "OR") {
+		return *left, nil
+	}
+	right, err := evalBool(l.right, e)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case right != nil && *right == (l.op == "OR"):
+		return *right, nil
+	case left == nil || right == nil:
+		return nil, nil
+	}
+	return *right, nil
+}
+
+type not struct {
+	x expr
+}
+
+func (n *not) eval(e *env) (any, error) {
+	b, err := evalBool(n.x, e)
+	if err != nil || b == nil {
+		return nil, err
+	}
+	return !*b, nil
+}
+
+// evalBool evaluates x as a condition. NULL is returned as nil.
+func evalBool(x expr, e *env) (*bool, error) {
+	v, err := x.eval(e)
+	if err != nil {
+		return nil, err
+	}
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case bool:
+		return &v, nil
+	case string:
+		if b, err := strconv.ParseBool(v); err == nil {
+			return &b, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s is not a boolean", ErrQueryFailed, describe(v))
+}
+
+type comparison struct {
+	op          string
+	left, right expr
+}
+
+func (c *comparison) eval(e *env) (any, error) {
+	left, err := c.left.eval(e)
+	if err != nil {
+		return nil, err
+	}
+	right, err := c.right.eval(e)
+	if err != nil {
+		return nil, err
+	}
+	cmp, ok := compare(left, right)
+	if !ok {
+		return nil, nil
+	}
+	switch c.op {
+	case "=":
+		return cmp == 0, nil
+	case "!=":
+		return cmp != 0, nil
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	default:
+		return cmp >= 0, nil
+	}
+}
+
+// compare orders two values. Numbers compare numerically, and a string
+// compared with a number is read as a number, since CSV fields are text.
+// It reports false when the values are NULL or not comparable.
+func compare(a, b any) (int, bool) {
+	if a == nil || b == nil {
+		return 0, false
+	}
+	if as, ok := a.(string); ok {
+		if bs, ok := b.(string); ok {
+			return strings.Compare(as, bs), true
+		}
+	}
+	if ab, ok := a.(bool); ok {
+		bb, ok := b.(bool)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case ab == bb:
+			return 0, true
+		case !ab:
+			return -1, true
+		}
+		return 1, true
+	}
+
+	an, aok := toNumber(a)
+	bn, bok := toNumber(b)
+	if !aok || !bok {
+		return 0, false
+	}
+	ai, aInt := an.(int64)
+	bi, bInt := bn.(int64)
+	if aInt && bInt {
+		switch {
+		case ai < bi:
+			return -1, true
+		case ai > bi:
+			return 1, true
+		}
+		return 0, true
+	}
+	af, bf := toFloat(an), toFloat(bn)
+	switch {
+	case af < bf:
+		return -1, true
+	case af > bf:
+		return 1, true
+	case af == bf:
+		return 0, true
+	}
+	return 0, false
+}
+
+// toNumber returns v as an int64 or float64, parsing strings.
+func toNumber(v any) (any, bool) {
+	switch v := v.(type) {
+	case int64, float64:
+		return v, true
+	case string:
+		s := strings.TrimSpace(v)
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return i, true
+		}
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f, true
+		}
+	}
+	return nil, false
+}
+
+func toFloat(n any) float64 {
+	if i, ok := n.(int64); ok {
+		return float64(i)
+	}
+	return n.(float64)
+}
+
+type isNull struct {
+	x   expr
+	not bool
+}
+
+func (n *isNull) eval(e *env) (any, error) {
+	v, err := n.x.eval(e)
+	if err != nil {
+		return nil, err
+	}
+	return (v == nil) != n.not, nil
+}
+
+type like struct {
+	x, pattern, escape expr
+	not                bool
+
+	// re is the compiled pattern when it is a literal.
+	re *regexp.Regexp
+}
+
+func (l *like) precompile() error {
+	pattern, ok := l.pattern.(*literal)
+	if !ok {
+		return nil
+	}
+	escape := ""
+	if l.escape != nil {
+		lit, ok := l.escape.(*literal)
+		if !ok {
+			return nil
+		}
+		escape = fmt.Sprint(lit.value)
+	}
+	re, err := likePattern(fmt.Sprint(pattern.value), escape)
+	if err != nil {
+		return err
+	}
+	l.re = re
+	return nil
+}
+
+func (l *like) eval(e *env) (any, error) {
+	v, err := l.x.eval(e)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	re := l.re
+	if re == nil {
+		pattern, err := l.pattern.eval(e)
+		if err != nil || pattern == nil {
+			return nil, err
+		}
+		escape := ""
+		if l.escape != nil {
+			ev, err := l.escape.eval(e)
+			if err != nil {
+				return nil, err
+			}
+			escape = toText(ev)
+		}
+		if re, err = likePattern(toText(pattern), escape); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrQueryFailed, err)
+		}
+	}
+	return re.MatchString(toText(v)) != l.not, nil
+}
+
+type inList struct {
+	x    expr
+	list []expr
+	not  bool
+}
+
+func (in *inList) eval(e *env) (any, error) {
+	v, err := in.x.eval(e)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	sawNull := false
+	for _, item := range in.list {
+		iv, err := item.eval(e)
+		if err != nil {
+			return nil, err
+		}
+		if cmp, ok := compare(v, iv); ok && cmp == 0 {
+			return !in.not, nil
+		}
+		if iv == nil {
+			sawNull = true
+		}
+	}
+	if sawNull {
+		return nil, nil
+	}
+	return in.not, nil
+}
+
+type between struct {
+	x, lo, hi expr
+	not       bool
+}
+
+func (b *between) eval(e *env) (any, error) {
+	v, err := b.x.eval(e)
+	if err != nil {
+		return nil, err
+	}
+	lo, err := b.lo.eval(e)
+	if err != nil {
+		return nil, err
+	}
+	hi, err := b.hi.eval(e)
+	if err != nil {
+		return nil, err
+	}
+	cmpLo, okLo := compare(v, lo)
+	cmpHi, okHi := compare(v, hi)
+	if !okLo || !okHi {
+		return nil, nil
+	}
+	return (cmpLo >= 0 && cmpHi <= 0) != b.not, nil
+}
+
+// arithmetic is +, -, *, /, % or string concatenation with ||. Operands
+// that are NULL or not numbers make the result NULL.
+type arithmetic struct {
+	op          string
+	left, right expr
+}
+
+func (a *arithmetic) eval(e *env) (any, error) {
+	left, err := a.left.eval(e)
+	if err != nil {
+		return nil, err
+	}
+	right, err := a.right.eval(e)
+	if err != nil {
+		return nil, err
+	}
+	if left == nil || right == nil {
+		return nil, nil
+	}
+	if a.op == "||" {
+		return toText(left) + toText(right), nil
+	}
+
+	ln, lok := toNumber(left)
+	rn, rok := toNumber(right)
+	if !lok || !rok {
+		return nil, nil
+	}
+	li, lInt := ln.(int64)
+	ri, rInt := rn.(int64)
+	if lInt && rInt {
+		switch a.op {
+		case "+":
+			return li + ri, nil
+		case "-":
+			return li - ri, nil
+		case "*":
+			return li * ri, nil
+		case "/", "%":
+			if ri == 0 {
+				return nil, fmt.Errorf("%w: division by zero", ErrQueryFailed)
+			}
+			if a.op == "/" {
+				return li / ri, nil
+			}
+			return li % ri, nil
+		}
+	}
+	lf, rf := toFloat(ln), toFloat(rn)
+	switch a.op {
+	case "+":
+		return lf + rf, nil
+	case "-":
+		return lf - rf, nil
+	case "*":
+		return lf * rf, nil
+	}
+	if rf == 0 {
+		return nil, fmt.Errorf("%w: division by zero", ErrQueryFailed)
+	}
+	if a.op == "/" {
+		return lf / rf, nil
+	}
+	return math.Mod(lf, rf), nil
+}
+
+// castTypes maps CAST type names to the type they convert to.
+var castTypes = map[string]string{
+	"INT": "int", "INTEGER": "int", "BIGINT": "int", "SMALLINT": "int",
+	"FLOAT": "float", "DOUBLE": "float", "REAL": "float", "DECIMAL": "float", "NUMERIC": "float",
+	"STRING": "string", "VARCHAR": "string", "CHAR": "string",
+	"BOOL": "bool", "BOOLEAN": "bool",
+}
+
+type cast struct {
+	x   expr
+	typ string
+}
+
+func (c *cast) eval(e *env) (any, error) {
+	v, err := c.x.eval(e)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	// Empty CSV fields are missing values rather than malformed ones.
+	if s, ok := v.(string); ok && c.typ != "string" && strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	switch c.typ {
+	case "int":
+		if n, ok := toNumber(v); ok {
+			if f, isFloat := n.(float64); isFloat {
+				return int64(f), nil
+			}
+			return n, nil
+		}
+	case "float":
+		if n, ok := toNumber(v); ok {
+			return toFloat(n), nil
+		}
+	case "string":
+		return toText(v), nil
+	case "bool":
+		switch v := v.(type) {
+		case bool:
+			return v, nil
+		case string:
+			if b, err := strconv.ParseBool(strings.TrimSpace(v)); err == nil {
+				return b, nil
+			}
+		case int64:
+			return v != 0, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: cannot cast %s to %s", ErrQueryFailed, describe(v), strings.ToUpper(c.typ))
+}
+
+// scalarFunc is a function of a record's values.
+type scalarFunc struct {
+	minArgs, maxArgs int
+	apply            func(args []any) (any, error)
+}
+
+var scalarFuncs = map[string]scalarFunc{
+	"LOWER":            {1, 1, stringFunc(strings.ToLower)},
+	"UPPER":            {1, 1, stringFunc(strings.ToUpper)},
+	"TRIM":             {1, 1, stringFunc(strings.TrimSpace)},
+	"CHAR_LENGTH":      {1, 1, charLength},
+	"CHARACTER_LENGTH": {1, 1, charLength},
+	"SUBSTRING":        {2, 3, substring},
+	"COALESCE": {1, -1, func(args []any) (any, error) {
+		for _, arg := range args {
+			if arg != nil {
+				return arg, nil
+			}
+		}
+		return nil, nil
+	}},
+	"NULLIF": {2, 2, func(args []any) (any, error) {
+		if cmp, ok := compare(args[0], args[1]); ok && cmp == 0 {
+			return nil, nil
+		}
+		return args[0], nil
+	}},
+	"ABS": {1, 1, func(args []any) (any, error) {
+		n, ok := toNumber(args[0])
+		if !ok {
+			return nil, nil
+		}
+		if i, isInt := n.(int64); isInt {
+			if i < 0 {
+				return -i, nil
+			}
+			return i, nil
+		}
+		return math.Abs(n.(float64)), nil
+	}},
+}
+
+func stringFunc(fn func(string) string) func([]any) (any, error) {
+	return func(args []any) (any, error) {
+		if args[0] == nil {
+			return nil, nil
+		}
+		return fn(toText(args[0])), nil
+	}
+}
+
+func charLength(args []any) (any, error) {
+	if args[0] == nil {
+		return nil, nil
+	}
+	return int64(utf8.RuneCountInString(toText(args[0]))), nil
+}
+
+// substring implements SUBSTRING(s, start[, length]) with 1-based
+// character positions.
+func substring(args []any) (any, error) {
+	if args[0] == nil || args[1] == nil {
+		return nil, nil
+	}
+	runes := []rune(toText(args[0]))
+	start, ok := toNumber(args[1])
+	if !ok {
+		return nil, fmt.Errorf("%w: SUBSTRING start is not a number", ErrQueryFailed)
+	}
+	from := int64(toFloat(start)) - 1
+	to := int64(len(runes))
+	if len(args) == 3 && args[2] != nil {
+		length, ok := toNumber(args[2])
+		if !ok || toFloat(length) < 0 {
+			return nil, fmt.Errorf("%w: SUBSTRING length is not a non-negative number", ErrQueryFailed)
+		}
+		to = from + int64(toFloat(length))
+	}
+	from = max(from, 0)
+	to = min(to, int64(len(runes)))
+	if from >= to {
+		return "", nil
+	}
+	return string(runes[from:to]), nil
+}
+
+type call struct {
+	name string
+	fn   scalarFunc
+	args []expr
+}
+
+func (c *call) eval(e *env) (any, error) {
+	args := make([]any, len(c.args))
+	for i, arg := range c.args {
+		v, err := arg.eval(e)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+	return c.fn.apply(args)
+}
+
+// aggregateFuncs are the supported aggregates.
+var aggregateFuncs = map[string]struct{}{
+	"COUNT": {}, "SUM": {}, "AVG": {}, "MIN": {}, "MAX": {},
+}
+
+// aggregate is an aggregate call. arg is nil for COUNT(*).
+type aggregate struct {
+	fn    string
+	arg   expr
+	index int
+}
+
+func (a *aggregate) eval(e *env) (any, error) {
+	if e.aggs == nil {
+		return nil, fmt.Errorf("%w: aggregate evaluated per record", ErrQueryFailed)
+	}
+	return e.aggs[a.index], nil
+}
+
+// aggState accumulates an aggregate over the records.
+type aggState struct {
+	count    int64
+	sum      any
+	min, max any
+}
+
+func (s *aggState) add(a *aggregate, e *env) error {
+	if a.arg == nil {
+		s.count++
+		return nil
+	}
+	v, err := a.arg.eval(e)
+	if err != nil || v == nil {
+		return err
+	}
+	switch a.fn {
+	case "COUNT":
+		s.count++
+	case "SUM", "AVG":
+		n, ok := toNumber(v)
+		if !ok {
+			return fmt.Errorf("%w: %s cannot be summed", ErrQueryFailed, describe(v))
+		}
+		s.count++
+		switch {
+		case s.sum == nil:
+			s.sum = n
+		case isInt(s.sum) && isInt(n):
+			s.sum = s.sum.(int64) + n.(int64)
+		default:
+			s.sum = toFloat(s.sum) + toFloat(n)
+		}
+	case "MIN":
+		s.count++
+		if v = numeric(v); s.min == nil || less(v, s.min) {
+			s.min = v
+		}
+	case "MAX":
+		s.count++
+		if v = numeric(v); s.max == nil || less(s.max, v) {
+			s.max = v
+		}
+	}
+	return nil
+}
+
+func (s *aggState) result(fn string) any {
+	switch fn {
+	case "COUNT":
+		return s.count
+	case "SUM":
+		return s.sum
+	case "AVG":
+		if s.count == 0 {
+			return nil
+		}
+		return toFloat(s.sum) / float64(s.count)
+	case "MIN":
+		return s.min
+	default:
+		return s.max
+	}
+}
+
+// numeric returns v as a number if it is one or is text holding one.
+func numeric(v any) any {
+	if n, ok := toNumber(v); ok {
+		return n
+	}
+	return v
+}
+
+// less reports whether a orders before b.
+func less(a, b any) bool {
+	cmp, ok := compare(a, b)
+	return ok && cmp < 0
+}
+
+func isInt(v any) bool {
+	_, ok := v.(int64)
+	return ok
+}
+
+// containsAggregate reports whether x contains an aggregate call.
+func containsAggregate(x expr) bool {
+	found := false
+	walk(x, func(x expr) bool {
+		if _, ok := x.(*aggregate); ok {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// refersOutsideAggregate reports whether x refers to a column outside an
+// aggregate call.
+func refersOutsideAggregate(x expr) bool {
+	found := false
+	walk(x, func(x expr) bool {
+		switch x.(type) {
+		case *aggregate:
+			return false
+		case *columnRef:
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// walk calls fn on x and its subexpressions, depth first, skipping the
+// subexpressions of nodes for which fn returns false.
+func walk(x expr, fn func(expr) bool) {
+	if x == nil || !fn(x) {
+		return
+	}
+	var children []expr
+	switch x := x.(type) {
+	case *logical:
+		children = []expr{x.left, x.right}
+	case *not:
+		children = []expr{x.x}
+	case *comparison:
+		children = []expr{x.left, x.right}
+	case *isNull:
+		children = []expr{x.x}
+	case *like:
+		children = []expr{x.x, x.pattern, x.escape}
+	case *inList:
+		children = append([]expr{x.x}, x.list...)
+	case *between:
+		children = []expr{x.x, x.lo, x.hi}
+	case *arithmetic:
+		children = []expr{x.left, x.right}
+	case *cast:
+		children = []expr{x.x}
+	case *call:
+		children = x.args
+	case *aggregate:
+		children = []expr{x.arg}
+	}
+	for _, child := range children {
+		walk(child, fn)
+	}
+}
+
+// toText formats a value as text, nested values as JSON.
+func toText(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(b)
+	}
+}
+
+// describe names a value in error messages.
+func describe(v any) string {
+	switch v.(type) {
+	case map[string]any:
+		return "an object"
+	case []any:
+		return "an array"
+	}
+	return strconv.Quote(toText(v))
+}
+
+// run evaluates q over records and writes the results to w.
+func (q *Query) run(ctx context.Context, records recordReader, w recordWriter, stats *counters) error {
+	var states []aggState
+	if len(q.aggregates) > 0 {
+		states = make([]aggState, len(q.aggregates))
+	}
+	var returned int64
+
+	for q.limit < 0 || states != nil || returned < q.limit {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		rec, err := records.next()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return err
+		}
+		stats.recordsScanned.Add(1)
+
+		e := &env{rec: rec}
+		if q.where != nil {
+			ok, err := evalBool(q.where, e)
+			if err != nil {
+				return err
+			}
+			if ok == nil || !*ok {
+				continue
+			}
+		}
+
+		if states != nil {
+			for i, agg := range q.aggregates {
+				if err := states[i].add(agg, e); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if err := q.emit(w, e); err != nil {
+			return err
+		}
+		returned++
+		stats.recordsReturned.Add(1)
+	}
+
+	if states != nil && q.limit != 0 {
+		e := &env{aggs: make([]any, len(states))}
+		for i, agg := range q.aggregates {
+			e.aggs[i] = states[i].result(agg.fn)
+		}
+		if err := q.emit(w, e); err != nil {
+			return err
+		}
+		stats.recordsReturned.Add(1)
+	}
+	return w.flush()
+}
+
+// emit writes the projection of the record in e.
+func (q *Query) emit(w recordWriter, e *env) error {
+	if q.star {
+		return w.write(e.rec.names, e.rec.values)
+	}
+	names := make([]string, len(q.items))
+	values := make([]any, len(q.items))
+	for i, item := range q.items {
+		v, err := item.expr.eval(e)
+		if err != nil {
+			return err
+		}
+		names[i] = item.name
+		values[i] = v
+	}
+	return w.write(names, values)
+}
@@ -0,0 +1,784 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Query is a parsed SELECT statement.
+type Query struct {
+	star       bool
+	items      []selectItem
+	alias      string
+	where      expr
+	limit      int64
+	aggregates []*aggregate
+}
+
+// selectItem is one expression of a projection and its output name.
+type selectItem struct {
+	expr expr
+	name string
+}
+
+// Parse parses a SELECT statement.
+func Parse(expression string) (*Query, error) {
+	tokens, err := lex(expression)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	q, err := p.parseQuery()
+	if err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokQuotedIdent
+	tokString
+	tokNumber
+	tokSymbol
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// symbols are the operators and punctuation of the grammar, longest first.
+var symbols = []string{"<=", ">=", "<>", "!=", "||", "=", "<", ">", "+", "-", "*", "/", "%", "(", ")", ",", ".", "[", "]"}
+
+func lex(s string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '\'' || c == '"':
+			text, n, ok := lexQuoted(s[i:], c)
+			if !ok {
+				return nil, fmt.Errorf("%w: unterminated quote at offset %d", ErrInvalidQuery, i)
+			}
+			kind := tokString
+			if c == '"' {
+				kind = tokQuotedIdent
+			}
+			tokens = append(tokens, token{kind: kind, text: text, pos: i})
+			i += n
+		case c >= '0' && c <= '9':
+			j := i
+			for j < len(s) && s[j] >= '0' && s[j] <= '9' {
+				j++
+			}
+			if j+1 < len(s) && s[j] == '.' && s[j+1] >= '0' && s[j+1] <= '9' {
+				j++
+				for j < len(s) && s[j] >= '0' && s[j] <= '9' {
+					j++
+				}
+			}
+			if j < len(s) && (s[j] == 'e' || s[j] == 'E') {
+				k := j + 1
+				if k < len(s) && (s[k] == '+' || s[k] == '-') {
+					k++
+				}
+				if k < len(s) && s[k] >= '0' && s[k] <= '9' {
+					for k < len(s) && s[k] >= '0' && s[k] <= '9' {
+						k++
+					}
+					j = k
+				}
+			}
+			tokens = append(tokens, token{kind: tokNumber, text: s[i:j], pos: i})
+			i = j
+		case c == '_' || c < 0x80 && unicode.IsLetter(rune(c)):
+			j := i
+			for j < len(s) && (s[j] == '_' || s[j] < 0x80 && (unicode.IsLetter(rune(s[j])) || unicode.IsDigit(rune(s[j])))) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokIdent, text: s[i:j], pos: i})
+			i = j
+		default:
+			matched := false
+			for _, sym := range symbols {
+				if strings.HasPrefix(s[i:], sym) {
+					tokens = append(tokens, token{kind: tokSymbol, text: sym, pos: i})
+					i += len(sym)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("%w: unexpected character %q at offset %d", ErrInvalidQuery, c, i)
+			}
+		}
+	}
+	return append(tokens, token{kind: tokEOF, pos: len(s)}), nil
+}
+
+// lexQuoted reads a string quoted with q, in which a doubled quote stands
+// for itself, and returns its content and length.
+func lexQuoted(s string, q byte) (string, int, bool) {
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		if s[i] != q {
+			b.WriteByte(s[i])
+			continue
+		}
+		if i+1 < len(s) && s[i+1] == q {
+			b.WriteByte(q)
+			i++
+			continue
+		}
+		return b.String(), i + 1, true
+	}
+	return "", 0, false
+}
+
+// reserved are keywords that cannot be used as unquoted aliases.
+var reserved = map[string]bool{
+	"SELECT": true, "FROM": true, "WHERE": true, "LIMIT": true, "AS": true,
+	"AND": true, "OR": true, "NOT": true, "LIKE": true, "ESCAPE": true,
+	"IN": true, "BETWEEN": true, "IS": true, "NULL": true, "MISSING": true,
+	"TRUE": true, "FALSE": true, "CAST": true,
+}
+
+type parser struct {
+	tokens []token
+	i      int
+	q      *Query
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.i]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+// keyword consumes the next token if it is the keyword kw.
+func (p *parser) keyword(kw string) bool {
+	t := p.peek()
+	if t.kind == tokIdent && strings.EqualFold(t.text, kw) {
+		p.i++
+		return true
+	}
+	return false
+}
+
+// symbol consumes the next token if it is the symbol sym.
+func (p *parser) symbol(sym string) bool {
+	t := p.peek()
+	if t.kind == tokSymbol && t.text == sym {
+		p.i++
+		return true
+	}
+	return false
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	t := p.peek()
+	where := "end of query"
+	if t.kind != tokEOF {
+		where = fmt.Sprintf("%q at offset %d", t.text, t.pos)
+	}
+	return fmt.Errorf("%w: %s near %s", ErrInvalidQuery, fmt.Sprintf(format, args...), where)
+}
+
+func (p *parser) expectKeyword(kw string) error {
+	if !p.keyword(kw) {
+		return p.errorf("expected %s", kw)
+	}
+	return nil
+}
+
+func (p *parser) expectSymbol(sym string) error {
+	if !p.symbol(sym) {
+		return p.errorf("expected %q", sym)
+	}
+	return nil
+}
+
+func (p *parser) parseQuery() (*Query, error) {
+	p.q = &Query{limit: -1}
+	if err := p.expectKeyword("SELECT"); err != nil {
+		return nil, err
+	}
+
+	// The projection may refer to the source alias, which is declared
+	// after it: parse FROM first and come back.
+	start := p.i
+	depth := 0
+	for {
+		t := p.peek()
+		if t.kind == tokEOF {
+			return nil, p.errorf("expected FROM")
+		}
+		if t.kind == tokSymbol && t.text == "(" {
+			depth++
+		}
+		if t.kind == tokSymbol && t.text == ")" {
+			depth--
+		}
+		if depth == 0 && t.kind == tokIdent && strings.EqualFold(t.text, "FROM") {
+			break
+		}
+		p.next()
+	}
+	p.next()
+	if err := p.parseSource(); err != nil {
+		return nil, err
+	}
+	rest := p.i
+
+	p.i = start
+	if err := p.parseProjection(); err != nil {
+		return nil, err
+	}
+	if !p.keyword("FROM") {
+		return nil, p.errorf("expected FROM")
+	}
+	p.i = rest
+
+	if p.keyword("WHERE") {
+		where, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if containsAggregate(where) {
+			return nil, fmt.Errorf("%w: aggregates are not allowed in WHERE", ErrInvalidQuery)
+		}
+		p.q.where = where
+	}
+	if p.keyword("LIMIT") {
+		t := p.next()
+		n, err := strconv.ParseInt(t.text, 10, 64)
+		if t.kind != tokNumber || err != nil || n < 0 {
+			p.i--
+			return nil, p.errorf("expected a non-negative integer LIMIT")
+		}
+		p.q.limit = n
+	}
+	if p.peek().kind != tokEOF {
+		return nil, p.errorf("unexpected input")
+	}
+	return p.q, p.checkAggregates()
+}
+
+// parseSource parses S3Object, optionally followed by [*] and an alias.
+func (p *parser) parseSource() error {
+	t := p.next()
+	if t.kind != tokIdent || !strings.EqualFold(t.text, "S3Object") {
+		p.i--
+		return p.errorf("expected S3Object")
+	}
+	if p.symbol("[") {
+		if err := p.expectSymbol("*"); err != nil {
+			return err
+		}
+		if err := p.expectSymbol("]"); err != nil {
+			return err
+		}
+	}
+	explicit := p.keyword("AS")
+	t = p.peek()
+	switch {
+	case t.kind == tokIdent && !reserved[strings.ToUpper(t.text)], t.kind == tokQuotedIdent:
+		p.q.alias = t.text
+		p.next()
+	case explicit:
+		return p.errorf("expected an alias")
+	}
+	return nil
+}
+
+func (p *parser) parseProjection() error {
+	if p.symbol("*") {
+		p.q.star = true
+		return nil
+	}
+	for {
+		if p.isAliasStar() {
+			if len(p.q.items) > 0 || p.peekAt(3).text == "," {
+				return p.errorf("* cannot be combined with other columns")
+			}
+			p.i += 3
+			p.q.star = true
+			return nil
+		}
+		e, err := p.parseExpr()
+		if err != nil {
+			return err
+		}
+		item := selectItem{expr: e}
+		explicit := p.keyword("AS")
+		t := p.peek()
+		switch {
+		case t.kind == tokIdent && !reserved[strings.ToUpper(t.text)], t.kind == tokQuotedIdent:
+			item.name = t.text
+			p.next()
+		case explicit:
+			return p.errorf("expected a column alias")
+		}
+		if item.name == "" {
+			item.name = defaultName(e, len(p.q.items)+1)
+		}
+		p.q.items = append(p.q.items, item)
+		if !p.symbol(",") {
+			return nil
+		}
+	}
+}
+
+func (p *parser) peekAt(n int) token {
+	if p.i+n < len(p.tokens) {
+		return p.tokens[p.i+n]
+	}
+	return p.tokens[len(p.tokens)-1]
+}
+
+// isAliasStar reports whether the next tokens are alias.*.
+func (p *parser) isAliasStar() bool {
+	t := p.peek()
+	return p.q.alias != "" && (t.kind == tokIdent || t.kind == tokQuotedIdent) && aliasMatches(p.q.alias, t) &&
+		p.peekAt(1).text == "." && p.peekAt(2).kind == tokSymbol && p.peekAt(2).text == "*"
+}
+
+func aliasMatches(alias string, t token) bool {
+	if t.kind == tokQuotedIdent {
+		return t.text == alias
+	}
+	return strings.EqualFold(t.text, alias)
+}
+
+// defaultName names an unaliased projection: a column reference after its
+// last path element, anything else after its position.
+func defaultName(e expr, position int) string {
+	if ref, ok := e.(*columnRef); ok {
+		if last := ref.path[len(ref.path)-1]; last.index < 0 {
+			return last.name
+		}
+	}
+	return "_" + strconv.Itoa(position)
+}
+
+// checkAggregates rejects projections mixing aggregates with plain column
+// references, since queries have no GROUP BY.
+func (p *parser) checkAggregates() error {
+	if len(p.q.aggregates) == 0 {
+		return nil
+	}
+	if p.q.star {
+		return fmt.Errorf("%w: * cannot be combined with aggregates", ErrInvalidQuery)
+	}
+	for _, item := range p.q.items {
+		if refersOutsideAggregate(item.expr) {
+			return fmt.Errorf("%w: columns must be used inside an aggregate when the projection has aggregates", ErrInvalidQuery)
+		}
+	}
+	return nil
+}
+
+func (p *parser) parseExpr() (expr, error) {
+	return p.parseOr()
+}
+
+func (p *parser) parseOr() (expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("OR") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logical{op: "OR", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (expr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("AND") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &logical{op: "AND", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (expr, error) {
+	if p.keyword("NOT") {
+		x, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &not{x: x}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (expr, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+
+	t := p.peek()
+	if t.kind == tokSymbol {
+		switch t.text {
+		case "=", "!=", "<>", "<", "<=", ">", ">=":
+			p.next()
+			right, err := p.parseAdditive()
+			if err != nil {
+				return nil, err
+			}
+			op := t.text
+			if op == "<>" {
+				op = "!="
+			}
+			return &comparison{op: op, left: left, right: right}, nil
+		}
+		return left, nil
+	}
+
+	if p.keyword("IS") {
+		negate := p.keyword("NOT")
+		if !p.keyword("NULL") && !p.keyword("MISSING") {
+			return nil, p.errorf("expected NULL")
+		}
+		return &isNull{x: left, not: negate}, nil
+	}
+
+	negate := p.keyword("NOT")
+	switch {
+	case p.keyword("LIKE"):
+		pattern, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		l := &like{x: left, pattern: pattern, not: negate}
+		if p.keyword("ESCAPE") {
+			if l.escape, err = p.parseAdditive(); err != nil {
+				return nil, err
+			}
+		}
+		if err := l.precompile(); err != nil {
+			return nil, err
+		}
+		return l, nil
+	case p.keyword("IN"):
+		if err := p.expectSymbol("("); err != nil {
+			return nil, err
+		}
+		in := &inList{x: left, not: negate}
+		for {
+			e, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			in.list = append(in.list, e)
+			if !p.symbol(",") {
+				break
+			}
+		}
+		if err := p.expectSymbol(")"); err != nil {
+			return nil, err
+		}
+		return in, nil
+	case p.keyword("BETWEEN"):
+		lo, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectKeyword("AND"); err != nil {
+			return nil, err
+		}
+		hi, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		return &between{x: left, lo: lo, hi: hi, not: negate}, nil
+	case negate:
+		return nil, p.errorf("expected LIKE, IN or BETWEEN after NOT")
+	}
+	return left, nil
+}
+
+func (p *parser) parseAdditive() (expr, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		if t.kind != tokSymbol || (t.text != "+" && t.text != "-" && t.text != "||") {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = &arithmetic{op: t.text, left: left, right: right}
+	}
+}
+
+func (p *parser) parseMultiplicative() (expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		if t.kind != tokSymbol || (t.text != "*" && t.text != "/" && t.text != "%") {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &arithmetic{op: t.text, left: left, right: right}
+	}
+}
+
+func (p *parser) parseUnary() (expr, error) {
+	if p.symbol("-") {
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if lit, ok := x.(*literal); ok {
+			switch v := lit.value.(type) {
+			case int64:
+				return &literal{value: -v}, nil
+			case float64:
+				return &literal{value: -v}, nil
+			}
+		}
+		return &arithmetic{op: "-", left: &literal{value: int64(0)}, right: x}, nil
+	}
+	if p.symbol("+") {
+		return p.parseUnary()
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (expr, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNumber:
+		if i, err := strconv.ParseInt(t.text, 10, 64); err == nil {
+			return &literal{value: i}, nil
+		}
+		f, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			p.i--
+			return nil, p.errorf("invalid number")
+		}
+		return &literal{value: f}, nil
+	case tokString:
+		return &literal{value: t.text}, nil
+	case tokSymbol:
+		if t.text == "(" {
+			e, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			return e, p.expectSymbol(")")
+		}
+	case tokQuotedIdent:
+		return p.parsePath(t)
+	case tokIdent:
+		upper := strings.ToUpper(t.text)
+		switch upper {
+		case "NULL", "MISSING":
+			return &literal{}, nil
+		case "TRUE":
+			return &literal{value: true}, nil
+		case "FALSE":
+			return &literal{value: false}, nil
+		case "CAST":
+			return p.parseCast()
+		}
+		if p.symbol("(") {
+			return p.parseCall(upper)
+		}
+		if reserved[upper] {
+			break
+		}
+		return p.parsePath(t)
+	}
+	p.i--
+	return nil, p.errorf("expected an expression")
+}
+
+// parsePath parses a column reference starting with first, such as
+// s.address.city or s.tags[0].
+func (p *parser) parsePath(first token) (expr, error) {
+	ref := &columnRef{path: []pathElem{{name: first.text, exact: first.kind == tokQuotedIdent, index: -1}}}
+	for {
+		switch {
+		case p.peek().text == "." && p.peek().kind == tokSymbol && p.peekAt(1).text != "*":
+			p.next()
+			t := p.next()
+			if t.kind != tokIdent && t.kind != tokQuotedIdent {
+				p.i--
+				return nil, p.errorf("expected a field name")
+			}
+			ref.path = append(ref.path, pathElem{name: t.text, exact: t.kind == tokQuotedIdent, index: -1})
+		case p.symbol("["):
+			t := p.next()
+			n, err := strconv.Atoi(t.text)
+			if t.kind != tokNumber || err != nil || n < 0 {
+				p.i--
+				return nil, p.errorf("expected an array index")
+			}
+			if err := p.expectSymbol("]"); err != nil {
+				return nil, err
+			}
+			ref.path = append(ref.path, pathElem{index: n})
+		default:
+			// A leading alias is dropped: s.name is the column name.
+			if len(ref.path) > 1 && p.q.alias != "" && aliasMatches(p.q.alias, first) {
+				ref.path = ref.path[1:]
+			}
+			return ref, nil
+		}
+	}
+}
+
+func (p *parser) parseCast() (expr, error) {
+	if err := p.expectSymbol("("); err != nil {
+		return nil, err
+	}
+	x, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expectKeyword("AS"); err != nil {
+		return nil, err
+	}
+	t := p.next()
+	typ, ok := castTypes[strings.ToUpper(t.text)]
+	if t.kind != tokIdent || !ok {
+		p.i--
+		return nil, p.errorf("unsupported CAST type")
+	}
+	return &cast{x: x, typ: typ}, p.expectSymbol(")")
+}
+
+// parseCall parses the arguments of a function or aggregate call whose
+// opening parenthesis has been read.
+func (p *parser) parseCall(name string) (expr, error) {
+	if _, ok := aggregateFuncs[name]; ok {
+		agg := &aggregate{fn: name, index: len(p.q.aggregates)}
+		if name == "COUNT" && p.symbol("*") {
+			p.q.aggregates = append(p.q.aggregates, agg)
+			return agg, p.expectSymbol(")")
+		}
+		arg, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if containsAggregate(arg) {
+			return nil, fmt.Errorf("%w: aggregates cannot be nested", ErrInvalidQuery)
+		}
+		agg.arg = arg
+		p.q.aggregates = append(p.q.aggregates, agg)
+		return agg, p.expectSymbol(")")
+	}
+
+	fn, ok := scalarFuncs[name]
+	if !ok {
+		p.i -= 2
+		return nil, p.errorf("unknown function %s", name)
+	}
+	c := &call{name: name, fn: fn}
+	if !p.symbol(")") {
+		for {
+			arg, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			c.args = append(c.args, arg)
+			if !p.symbol(",") {
+				break
+			}
+		}
+		if err := p.expectSymbol(")"); err != nil {
+			return nil, err
+		}
+	}
+	if len(c.args) < fn.minArgs || (fn.maxArgs >= 0 && len(c.args) > fn.maxArgs) {
+		return nil, fmt.Errorf("%w: wrong number of arguments to %s", ErrInvalidQuery, name)
+	}
+	return c, nil
+}
+
+// likePattern converts a LIKE pattern to an anchored regular expression.
+func likePattern(pattern, escape string) (*regexp.Regexp, error) {
+	var esc rune = -1
+	if escape != "" {
+		runes := []rune(escape)
+		if len(runes) != 1 {
+			return nil, fmt.Errorf("%w: ESCAPE must be a single character", ErrInvalidQuery)
+		}
+		esc = runes[0]
+	}
+	var b strings.Builder
+	b.WriteString("(?s)^")
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+			escaped = false
+		case r == esc:
+			escaped = true
+		case r == '%':
+			b.WriteString(".*")
+		case r == '_':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
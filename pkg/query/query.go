@@ -0,0 +1,529 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package query runs S3 Select-style SQL queries over CSV, JSON and Parquet
+// objects, so analytics clients receive only the rows and columns they
+// need instead of whole objects. A query has the form
+//
+//	SELECT <projection> FROM S3Object [alias] [WHERE <condition>] [LIMIT n]
+//
+// where the projection is *, a list of expressions optionally named with
+// AS, or a list of aggregates (COUNT, SUM, AVG, MIN, MAX). Expressions
+// support column references, literals, arithmetic, string concatenation
+// (||), comparisons, AND, OR, NOT, LIKE, IN, BETWEEN, IS [NOT] NULL, CAST
+// and a few string functions.
+//
+// CSV and JSON objects are read as a stream, one record at a time, and may
+// be compressed. Parquet objects are spooled to a temporary file first,
+// because the format is read from its footer. Every query runs under
+// Limits that bound the bytes it reads and writes and the time it takes.
+package query
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/filter"
+)
+
+// Default limits.
+const (
+	DefaultMaxInputBytes  = 1 << 30
+	DefaultMaxRecordBytes = 1 << 20
+	DefaultMaxOutputBytes = 256 << 20
+	DefaultTimeout        = 5 * time.Minute
+)
+
+// Format is the serialization of query input or output.
+type Format string
+
+// Supported formats. Parquet is only supported as input.
+const (
+	FormatCSV     Format = "csv"
+	FormatJSON    Format = "json"
+	FormatParquet Format = "parquet"
+)
+
+// How the first line of a CSV object is treated.
+const (
+	// HeaderNone reads the first line as a record. Columns are named by
+	// position: _1, _2 and so on.
+	HeaderNone = "none"
+
+	// HeaderIgnore skips the first line. Columns are named by position.
+	HeaderIgnore = "ignore"
+
+	// HeaderUse names columns after the first line. Columns can still be
+	// referenced by position.
+	HeaderUse = "use"
+)
+
+var (
+	// ErrInvalidQuery is returned for a query that does not parse, or uses
+	// an unsupported feature.
+	ErrInvalidQuery = fmt.Errorf("%w: invalid query", common.ErrInvalidArgument)
+
+	// ErrInvalidSerialization is returned for unsupported input or output
+	// settings.
+	ErrInvalidSerialization = fmt.Errorf("%w: invalid serialization", common.ErrInvalidArgument)
+
+	// ErrQueryFailed is returned when an object cannot be read in its
+	// declared format, or an expression cannot be evaluated on a record.
+	ErrQueryFailed = fmt.Errorf("%w: query failed", common.ErrInvalidArgument)
+
+	// ErrLimitExceeded is returned when a query reads, writes or buffers
+	// more than its Limits allow. It wraps common.ErrResourceExhausted.
+	ErrLimitExceeded = fmt.Errorf("%w: query limit exceeded", common.ErrResourceExhausted)
+)
+
+// Request describes a query over one object.
+type Request struct {
+	// Expression is the SQL query.
+	Expression string
+
+	// Input describes the object.
+	Input Input
+
+	// Output selects how matching records are written.
+	Output Output
+
+	// Limits bounds the resources the query uses.
+	Limits Limits
+}
+
+// Input describes the serialization of the queried object.
+type Input struct {
+	// Format is the object's format. It is required.
+	Format Format
+
+	// Compression names the format CSV and JSON objects are compressed
+	// with, one of the filter.Encoding constants. Empty means the object
+	// is not compressed. Parquet objects compress their own pages.
+	Compression string
+
+	// CSV configures CSV input.
+	CSV CSVInput
+}
+
+// CSVInput configures how CSV objects are read.
+type CSVInput struct {
+	// FileHeaderInfo is HeaderNone (the default), HeaderIgnore or
+	// HeaderUse.
+	FileHeaderInfo string
+
+	// FieldDelimiter separates fields. It defaults to a comma.
+	FieldDelimiter string
+
+	// Comments, if set, skips lines starting with this character.
+	Comments string
+}
+
+// Output describes the serialization of query results.
+type Output struct {
+	// Format is FormatCSV or FormatJSON. It defaults to the input format,
+	// or JSON for Parquet input. JSON output is one object per line.
+	Format Format
+
+	// FieldDelimiter separates CSV output fields. It defaults to a comma.
+	FieldDelimiter string
+}
+
+// ContentType returns the content type of results written in o's format.
+func (o Output) ContentType() string {
+	if o.Format == FormatCSV {
+		return "text/csv"
+	}
+	return "application/json"
+}
+
+// Limits bounds the resources a query uses. Zero fields use the defaults.
+type Limits struct {
+	// MaxInputBytes bounds the bytes read from the object, after
+	// decompression.
+	MaxInputBytes int64
+
+	// MaxRecordBytes bounds the size of one record.
+	MaxRecordBytes int64
+
+	// MaxOutputBytes bounds the bytes of results.
+	MaxOutputBytes int64
+
+	// Timeout bounds the time spent on the query.
+	Timeout time.Duration
+}
+
+func (l Limits) withDefaults() Limits {
+	if l.MaxInputBytes <= 0 {
+		l.MaxInputBytes = DefaultMaxInputBytes
+	}
+	if l.MaxRecordBytes <= 0 {
+		l.MaxRecordBytes = DefaultMaxRecordBytes
+	}
+	if l.MaxOutputBytes <= 0 {
+		l.MaxOutputBytes = DefaultMaxOutputBytes
+	}
+	if l.Timeout <= 0 {
+		l.Timeout = DefaultTimeout
+	}
+	return l
+}
+
+// Stats reports the work done by a query.
+type Stats struct {
+	// BytesScanned is the number of bytes read from the object.
+	BytesScanned int64
+
+	// BytesProcessed is the number of bytes read after decompression.
+	BytesProcessed int64
+
+	// BytesReturned is the number of bytes of results.
+	BytesReturned int64
+
+	// RecordsScanned is the number of records read.
+	RecordsScanned int64
+
+	// RecordsReturned is the number of result records.
+	RecordsReturned int64
+}
+
+// normalize validates r and fills in defaults.
+func (r *Request) normalize() error {
+	r.Input.Format = Format(strings.ToLower(string(r.Input.Format)))
+	r.Output.Format = Format(strings.ToLower(string(r.Output.Format)))
+
+	switch r.Input.Format {
+	case FormatCSV:
+		csvIn := &r.Input.CSV
+		csvIn.FileHeaderInfo = strings.ToLower(csvIn.FileHeaderInfo)
+		switch csvIn.FileHeaderInfo {
+		case "":
+			csvIn.FileHeaderInfo = HeaderNone
+		case HeaderNone, HeaderIgnore, HeaderUse:
+		default:
+			return fmt.Errorf("%w: unknown CSV header info %q", ErrInvalidSerialization, csvIn.FileHeaderInfo)
+		}
+		if _, err := delimiter(csvIn.FieldDelimiter); err != nil {
+			return err
+		}
+		if csvIn.Comments != "" {
+			if _, err := delimiter(csvIn.Comments); err != nil {
+				return err
+			}
+		}
+	case FormatJSON:
+	case FormatParquet:
+		if r.Input.Compression != "" {
+			return fmt.Errorf("%w: Parquet objects cannot be compressed", ErrInvalidSerialization)
+		}
+	case "":
+		return fmt.Errorf("%w: input format is required", ErrInvalidSerialization)
+	default:
+		return fmt.Errorf("%w: unknown input format %q", ErrInvalidSerialization, r.Input.Format)
+	}
+
+	switch r.Output.Format {
+	case "":
+		r.Output.Format = FormatJSON
+		if r.Input.Format == FormatCSV {
+			r.Output.Format = FormatCSV
+		}
+	case FormatCSV, FormatJSON:
+	default:
+		return fmt.Errorf("%w: unknown output format %q", ErrInvalidSerialization, r.Output.Format)
+	}
+	if _, err := delimiter(r.Output.FieldDelimiter); err != nil {
+		return err
+	}
+	r.Limits = r.Limits.withDefaults()
+	return nil
+}
+
+// delimiter returns the single character of a CSV delimiter setting, or a
+// comma when it is empty.
+func delimiter(s string) (rune, error) {
+	runes := []rune(s)
+	switch {
+	case len(runes) == 0:
+		return ',', nil
+	case len(runes) > 1 || runes[0] == '\r' || runes[0] == '\n' || runes[0] == '"':
+		return 0, fmt.Errorf("%w: invalid CSV delimiter %q", ErrInvalidSerialization, s)
+	}
+	return runes[0], nil
+}
+
+// Reader streams the results of a query. Read returns the results in the
+// requested output format; failures after the query started, including
+// exceeded limits, are returned by Read. Stats are complete once Read has
+// returned io.EOF.
+type Reader struct {
+	pr          *io.PipeReader
+	contentType string
+	stats       *counters
+	close       func() error
+}
+
+// Read reads query results.
+func (r *Reader) Read(p []byte) (int, error) {
+	return r.pr.Read(p)
+}
+
+// Close stops the query and releases the object.
+func (r *Reader) Close() error {
+	return r.close()
+}
+
+// ContentType returns the content type of the results.
+func (r *Reader) ContentType() string {
+	return r.contentType
+}
+
+// Stats returns the work done by the query so far.
+func (r *Reader) Stats() Stats {
+	return r.stats.snapshot()
+}
+
+// counters accumulates Stats while a query runs.
+type counters struct {
+	bytesScanned    atomic.Int64
+	bytesProcessed  atomic.Int64
+	bytesReturned   atomic.Int64
+	recordsScanned  atomic.Int64
+	recordsReturned atomic.Int64
+}
+
+func (c *counters) snapshot() Stats {
+	return Stats{
+		BytesScanned:    c.bytesScanned.Load(),
+		BytesProcessed:  c.bytesProcessed.Load(),
+		BytesReturned:   c.bytesReturned.Load(),
+		RecordsScanned:  c.recordsScanned.Load(),
+		RecordsReturned: c.recordsReturned.Load(),
+	}
+}
+
+// Select runs req over the object read from src. An invalid query or
+// serialization, a corrupt compression header and a Parquet object that
+// cannot be opened are reported immediately; later failures are returned
+// by the Reader. Select takes ownership of src: it is closed with the
+// Reader, or before Select returns an error.
+func Select(ctx context.Context, src io.ReadCloser, req *Request) (*Reader, error) {
+	request := *req
+	if err := request.normalize(); err != nil {
+		_ = src.Close()
+		return nil, err
+	}
+	q, err := Parse(request.Expression)
+	if err != nil {
+		_ = src.Close()
+		return nil, err
+	}
+	limits := request.Limits
+
+	ctx, cancel := context.WithTimeout(ctx, limits.Timeout)
+	stats := &counters{}
+	closers := []func() error{src.Close, func() error { cancel(); return nil }}
+	closeAll := func() error {
+		for i := len(closers) - 1; i >= 0; i-- {
+			_ = closers[i]()
+		}
+		return nil
+	}
+
+	var scanned io.Reader = &countingReader{r: &contextReader{ctx: ctx, r: src}, n: &stats.bytesScanned}
+	var records recordReader
+	if request.Input.Format == FormatParquet {
+		spool, err := spoolToFile(scanned, limits.MaxInputBytes)
+		if err != nil {
+			_ = closeAll()
+			return nil, err
+		}
+		closers = append(closers, func() error {
+			_ = spool.Close()
+			return os.Remove(spool.Name())
+		})
+		stats.bytesProcessed.Store(stats.bytesScanned.Load())
+		if records, err = newParquetRecords(spool); err != nil {
+			_ = closeAll()
+			return nil, err
+		}
+	} else {
+		r := scanned
+		if request.Input.Compression != "" {
+			dr, err := filter.Decompress(request.Input.Compression, r)
+			if err != nil {
+				_ = closeAll()
+				return nil, err
+			}
+			closers = append(closers, dr.Close)
+			r = dr
+		}
+		r = &limitReader{r: r, remaining: limits.MaxInputBytes}
+		r = &countingReader{r: r, n: &stats.bytesProcessed}
+		records = newRecordReader(r, request.Input, limits.MaxRecordBytes)
+	}
+
+	pr, pw := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		out := &countingWriter{w: &limitWriter{w: pw, remaining: limits.MaxOutputBytes}, n: &stats.bytesReturned}
+		err := q.run(ctx, records, newRecordWriter(request.Output, out), stats)
+		if err == nil {
+			err = io.EOF
+		}
+		_ = pw.CloseWithError(err)
+	}()
+
+	var closeOnce sync.Once
+	return &Reader{
+		pr:          pr,
+		contentType: request.Output.ContentType(),
+		stats:       stats,
+		close: func() error {
+			closeOnce.Do(func() {
+				cancel()
+				_ = pr.Close()
+				<-done
+				_ = closeAll()
+			})
+			return nil
+		},
+	}, nil
+}
+
+// spoolToFile copies at most max bytes of r to a temporary file.
+func spoolToFile(r io.Reader, max int64) (*os.File, error) {
+	f, err := os.CreateTemp("", "objstore-select-*")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(f, &limitReader{r: r, remaining: max}); err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return nil, err
+	}
+	return f, nil
+}
+
+// contextReader fails reads once ctx is done.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c *contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
+
+// countingReader adds the bytes read to n.
+type countingReader struct {
+	r io.Reader
+	n *atomic.Int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n.Add(int64(n))
+	return n, err
+}
+
+// limitReader fails with ErrLimitExceeded once more than remaining bytes
+// have been read.
+type limitReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (l *limitReader) Read(p []byte) (int, error) {
+	if l.remaining < 0 {
+		return 0, ErrLimitExceeded
+	}
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n + int(l.remaining), ErrLimitExceeded
+	}
+	return n, err
+}
+
+// recordLimitReader fails with ErrLimitExceeded when more than max bytes
+// are read between resets. Readers buffer ahead, so the bound is
+// approximate by the size of their buffer.
+type recordLimitReader struct {
+	r    io.Reader
+	max  int64
+	read int64
+}
+
+func (v *recordLimitReader) reset() {
+	v.read = 0
+}
+
+func (v *recordLimitReader) Read(p []byte) (int, error) {
+	if v.read > v.max {
+		return 0, fmt.Errorf("%w: record larger than %d bytes", ErrLimitExceeded, v.max)
+	}
+	n, err := v.r.Read(p)
+	v.read += int64(n)
+	return n, err
+}
+
+// limitWriter fails with ErrLimitExceeded once more than remaining bytes
+// have been written.
+type limitWriter struct {
+	w         io.Writer
+	remaining int64
+}
+
+func (l *limitWriter) Write(p []byte) (int, error) {
+	if int64(len(p)) > l.remaining {
+		n, _ := l.w.Write(p[:l.remaining])
+		l.remaining = 0
+		return n, ErrLimitExceeded
+	}
+	n, err := l.w.Write(p)
+	l.remaining -= int64(n)
+	return n, err
+}
+
+// countingWriter adds the bytes written to n.
+type countingWriter struct {
+	w io.Writer
+	n *atomic.Int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n.Add(int64(n))
+	return n, err
+}
+
+// isAbort reports whether err stops a query for a reason other than the
+// object's content: a limit, the deadline or cancellation.
+func isAbort(err error) bool {
+	return errors.Is(err, ErrLimitExceeded) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled)
+}
@@ -0,0 +1,298 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package query
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/filter"
+)
+
+const people = `name,age,city
+Alice,34,Boston
+Bob,27,Denver
+"Carol, Jr.",41,Boston
+Dan,,Austin
+`
+
+const orders = `{"id":1,"customer":{"name":"Alice","tier":"gold"},"total":120.5,"items":["a","b"]}
+{"id":2,"customer":{"name":"Bob","tier":"silver"},"total":30,"items":[]}
+{"id":3,"customer":{"name":"Carol","tier":"gold"},"total":75.25,"items":["c"]}
+`
+
+func runSelect(t *testing.T, data []byte, req *Request) (string, Stats, error) {
+	t.Helper()
+	r, err := Select(context.Background(), io.NopCloser(bytes.NewReader(data)), req)
+	if err != nil {
+		return "", Stats{}, err
+	}
+	defer func() { _ = r.Close() }()
+	out, err := io.ReadAll(r)
+	return string(out), r.Stats(), err
+}
+
+func TestSelect(t *testing.T) {
+	csvUse := Input{Format: FormatCSV, CSV: CSVInput{FileHeaderInfo: HeaderUse}}
+	jsonIn := Input{Format: FormatJSON}
+
+	tests := []struct {
+		name  string
+		data  string
+		expr  string
+		input Input
+		out   Output
+		want  string
+	}{
+		{"csv star", people, "SELECT * FROM S3Object WHERE city = 'Denver'", csvUse, Output{}, "Bob,27,Denver\n"},
+		{"csv projection to json", people, "SELECT s.name, s.age AS years FROM S3Object s WHERE s.age > 30", csvUse, Output{Format: FormatJSON},
+			"{\"name\":\"Alice\",\"years\":\"34\"}\n{\"name\":\"Carol, Jr.\",\"years\":\"41\"}\n"},
+		{"csv positional", people, "SELECT _1 FROM S3Object LIMIT 2", Input{Format: FormatCSV, CSV: CSVInput{FileHeaderInfo: HeaderIgnore}}, Output{}, "Alice\nBob\n"},
+		{"csv no header", "a;1\nb;2\n", "SELECT _2, _1 FROM S3Object WHERE _2 = 2", Input{Format: FormatCSV, CSV: CSVInput{FieldDelimiter: ";"}}, Output{FieldDelimiter: "|"}, "2|b\n"},
+		{"csv quoted output", people, "SELECT name FROM S3Object WHERE name LIKE 'C%'", csvUse, Output{}, "\"Carol, Jr.\"\n"},
+		{"is null", people, "SELECT name FROM S3Object WHERE age IS NULL OR age = ''", csvUse, Output{}, "Dan\n"},
+		{"in and not", people, "SELECT name FROM S3Object WHERE city IN ('Boston', 'Austin') AND NOT name LIKE '%Jr%'", csvUse, Output{}, "Alice\nDan\n"},
+		{"between", people, "SELECT name FROM S3Object WHERE CAST(age AS INT) BETWEEN 27 AND 34", csvUse, Output{}, "Alice\nBob\n"},
+		{"aggregates", people, "SELECT COUNT(*), SUM(age), MAX(age), AVG(CAST(age AS FLOAT)) AS mean FROM S3Object WHERE city = 'Boston'", csvUse, Output{Format: FormatJSON},
+			"{\"_1\":2,\"_2\":75,\"_3\":41,\"mean\":37.5}\n"},
+		{"count empty", people, "SELECT COUNT(*) FROM S3Object WHERE city = 'Paris'", csvUse, Output{}, "0\n"},
+		{"json nested", orders, "SELECT o.id, o.customer.name FROM S3Object o WHERE o.customer.tier = 'gold' AND o.total >= 100", jsonIn, Output{},
+			"{\"id\":1,\"name\":\"Alice\"}\n"},
+		{"json array index", orders, "SELECT id, items[0] AS first FROM S3Object WHERE items[0] IS NOT NULL", jsonIn, Output{Format: FormatCSV}, "1,a\n3,c\n"},
+		{"json star", orders, "SELECT * FROM S3Object s WHERE s.id = 2", jsonIn, Output{},
+			"{\"customer\":{\"name\":\"Bob\",\"tier\":\"silver\"},\"id\":2,\"items\":[],\"total\":30}\n"},
+		{"json arithmetic", orders, "SELECT id, total * 2 AS double, UPPER(customer.tier) || '!' AS tier FROM S3Object LIMIT 1", jsonIn, Output{},
+			"{\"id\":1,\"double\":241,\"tier\":\"GOLD!\"}\n"},
+		{"json document array", `[{"n":1},{"n":2},{"n":3}]`, "SELECT SUM(n) AS total FROM S3Object[*]", jsonIn, Output{}, "{\"total\":6}\n"},
+		{"json scalars", "1 2 3", "SELECT _1 FROM S3Object WHERE _1 % 2 = 1", jsonIn, Output{Format: FormatCSV}, "1\n3\n"},
+		{"functions", people, "SELECT LOWER(name), CHAR_LENGTH(city), SUBSTRING(city, 2, 3), COALESCE(NULLIF(age, ''), 'n/a') FROM S3Object WHERE name = 'Dan'", csvUse, Output{},
+			"dan,6,ust,n/a\n"},
+		{"quoted identifier", "\"First Name\"\nEve\n", "SELECT \"First Name\" FROM S3Object", Input{Format: FormatCSV, CSV: CSVInput{FileHeaderInfo: HeaderUse}}, Output{}, "Eve\n"},
+		{"limit zero", people, "SELECT * FROM S3Object LIMIT 0", csvUse, Output{}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := runSelect(t, []byte(tt.data), &Request{Expression: tt.expr, Input: tt.input, Output: tt.out})
+			if err != nil {
+				t.Fatalf("Select() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Select() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelect_Compressed(t *testing.T) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, _ = zw.Write([]byte(orders))
+	_ = zw.Close()
+
+	req := &Request{
+		Expression: "SELECT COUNT(*) AS n FROM S3Object",
+		Input:      Input{Format: FormatJSON, Compression: filter.EncodingGzip},
+	}
+	got, stats, err := runSelect(t, buf.Bytes(), req)
+	if err != nil {
+		t.Fatalf("Select() error = %v", err)
+	}
+	if got != "{\"n\":3}\n" {
+		t.Errorf("Select() = %q", got)
+	}
+	if stats.BytesScanned != int64(buf.Len()) || stats.BytesProcessed != int64(len(orders)) {
+		t.Errorf("stats = %+v", stats)
+	}
+	if stats.RecordsScanned != 3 || stats.RecordsReturned != 1 || stats.BytesReturned != int64(len(got)) {
+		t.Errorf("stats = %+v", stats)
+	}
+
+	_, _, err = runSelect(t, []byte(orders), req)
+	if !errors.Is(err, filter.ErrCorruptInput) {
+		t.Errorf("uncompressed input error = %v", err)
+	}
+}
+
+type parquetRow struct {
+	Name   string    `parquet:"name"`
+	Region string    `parquet:"region"`
+	Amount float64   `parquet:"amount"`
+	Units  int32     `parquet:"units"`
+	When   time.Time `parquet:"when,timestamp(millisecond)"`
+}
+
+func TestSelect_Parquet(t *testing.T) {
+	var buf bytes.Buffer
+	w := parquet.NewGenericWriter[parquetRow](&buf)
+	when := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	if _, err := w.Write([]parquetRow{
+		{"widget", "east", 10.5, 3, when},
+		{"gadget", "west", 99, 1, when},
+		{"doohickey", "east", 4.5, 10, when},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, _, err := runSelect(t, buf.Bytes(), &Request{
+		Expression: "SELECT name, units, \"when\" FROM S3Object WHERE region = 'east' AND amount < 50",
+		Input:      Input{Format: FormatParquet},
+	})
+	if err != nil {
+		t.Fatalf("Select() error = %v", err)
+	}
+	want := "{\"name\":\"widget\",\"units\":3,\"when\":\"2025-03-01T12:00:00Z\"}\n" +
+		"{\"name\":\"doohickey\",\"units\":10,\"when\":\"2025-03-01T12:00:00Z\"}\n"
+	if got != want {
+		t.Errorf("Select() = %q, want %q", got, want)
+	}
+
+	got, _, err = runSelect(t, buf.Bytes(), &Request{
+		Expression: "SELECT SUM(units) FROM S3Object",
+		Input:      Input{Format: FormatParquet},
+		Output:     Output{Format: FormatCSV},
+	})
+	if err != nil || got != "14\n" {
+		t.Errorf("Select() = %q, %v", got, err)
+	}
+
+	_, _, err = runSelect(t, []byte("not parquet"), &Request{Expression: "SELECT * FROM S3Object", Input: Input{Format: FormatParquet}})
+	if !errors.Is(err, ErrQueryFailed) {
+		t.Errorf("invalid Parquet error = %v", err)
+	}
+}
+
+func TestSelect_InvalidRequests(t *testing.T) {
+	csvIn := Input{Format: FormatCSV}
+	tests := []struct {
+		name  string
+		expr  string
+		input Input
+		out   Output
+		want  error
+	}{
+		{"missing format", "SELECT * FROM S3Object", Input{}, Output{}, ErrInvalidSerialization},
+		{"unknown format", "SELECT * FROM S3Object", Input{Format: "xml"}, Output{}, ErrInvalidSerialization},
+		{"parquet output", "SELECT * FROM S3Object", csvIn, Output{Format: FormatParquet}, ErrInvalidSerialization},
+		{"compressed parquet", "SELECT * FROM S3Object", Input{Format: FormatParquet, Compression: "gzip"}, Output{}, ErrInvalidSerialization},
+		{"bad header info", "SELECT * FROM S3Object", Input{Format: FormatCSV, CSV: CSVInput{FileHeaderInfo: "maybe"}}, Output{}, ErrInvalidSerialization},
+		{"bad delimiter", "SELECT * FROM S3Object", Input{Format: FormatCSV, CSV: CSVInput{FieldDelimiter: "::"}}, Output{}, ErrInvalidSerialization},
+		{"unknown compression", "SELECT * FROM S3Object", Input{Format: FormatCSV, Compression: "rar"}, Output{}, filter.ErrUnsupportedEncoding},
+		{"not select", "DELETE FROM S3Object", csvIn, Output{}, ErrInvalidQuery},
+		{"wrong source", "SELECT * FROM users", csvIn, Output{}, ErrInvalidQuery},
+		{"trailing input", "SELECT * FROM S3Object ORDER BY x", csvIn, Output{}, ErrInvalidQuery},
+		{"unknown function", "SELECT EVAL(x) FROM S3Object", csvIn, Output{}, ErrInvalidQuery},
+		{"mixed aggregate", "SELECT name, COUNT(*) FROM S3Object", csvIn, Output{}, ErrInvalidQuery},
+		{"aggregate in where", "SELECT name FROM S3Object WHERE COUNT(*) > 1", csvIn, Output{}, ErrInvalidQuery},
+		{"unterminated string", "SELECT * FROM S3Object WHERE name = 'x", csvIn, Output{}, ErrInvalidQuery},
+		{"bad limit", "SELECT * FROM S3Object LIMIT -1", csvIn, Output{}, ErrInvalidQuery},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := runSelect(t, []byte(people), &Request{Expression: tt.expr, Input: tt.input, Output: tt.out})
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Select() error = %v, want %v", err, tt.want)
+			}
+			if !errors.Is(err, common.ErrInvalidArgument) {
+				t.Errorf("Select() error = %v, want an invalid argument", err)
+			}
+		})
+	}
+}
+
+func TestSelect_RuntimeErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  string
+		expr  string
+		input Input
+		want  error
+	}{
+		{"malformed json", "{\"a\":1}\n{\"a\":", "SELECT * FROM S3Object", Input{Format: FormatJSON}, ErrQueryFailed},
+		{"malformed csv", "a,\"b\n", "SELECT * FROM S3Object", Input{Format: FormatCSV}, ErrQueryFailed},
+		{"bad cast", "abc\n", "SELECT CAST(_1 AS INT) FROM S3Object", Input{Format: FormatCSV}, ErrQueryFailed},
+		{"division by zero", "1\n", "SELECT _1 / 0 FROM S3Object", Input{Format: FormatCSV}, ErrQueryFailed},
+		{"record too large", strings.Repeat("x", 64<<10) + "\n", "SELECT * FROM S3Object", Input{Format: FormatCSV}, ErrLimitExceeded},
+		{"input too large", strings.Repeat("1\n", 1<<16), "SELECT COUNT(*) FROM S3Object", Input{Format: FormatCSV}, ErrLimitExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := runSelect(t, []byte(tt.data), &Request{
+				Expression: tt.expr,
+				Input:      tt.input,
+				Limits:     Limits{MaxRecordBytes: 16 << 10, MaxInputBytes: 96 << 10},
+			})
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Select() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelect_OutputLimit(t *testing.T) {
+	data := strings.Repeat("row\n", 10000)
+	out, _, err := runSelect(t, []byte(data), &Request{
+		Expression: "SELECT * FROM S3Object",
+		Input:      Input{Format: FormatCSV},
+		Limits:     Limits{MaxOutputBytes: 1000},
+	})
+	if !errors.Is(err, ErrLimitExceeded) || !errors.Is(err, common.ErrResourceExhausted) {
+		t.Errorf("Select() error = %v", err)
+	}
+	if len(out) > 1000 {
+		t.Errorf("wrote %d bytes", len(out))
+	}
+}
+
+func TestSelect_CloseEarly(t *testing.T) {
+	src := &closeRecorder{Reader: strings.NewReader(strings.Repeat("row\n", 100000))}
+	r, err := Select(context.Background(), src, &Request{Expression: "SELECT * FROM S3Object", Input: Input{Format: FormatCSV}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Read(make([]byte, 10)); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !src.closed {
+		t.Error("source not closed")
+	}
+	if r.ContentType() != "text/csv" {
+		t.Errorf("ContentType() = %q", r.ContentType())
+	}
+}
+
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
@@ -0,0 +1,394 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package query
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/format"
+)
+
+// recordReader reads the records of an object. next returns io.EOF after
+// the last record.
+type recordReader interface {
+	next() (*record, error)
+}
+
+// newRecordReader returns a reader of the CSV or JSON records of r.
+func newRecordReader(r io.Reader, in Input, maxRecordBytes int64) recordReader {
+	limit := &recordLimitReader{r: r, max: maxRecordBytes}
+	if in.Format == FormatJSON {
+		dec := json.NewDecoder(limit)
+		dec.UseNumber()
+		return &jsonRecords{dec: dec, limit: limit}
+	}
+
+	comma, _ := delimiter(in.CSV.FieldDelimiter)
+	cr := csv.NewReader(limit)
+	cr.Comma = comma
+	if in.CSV.Comments != "" {
+		cr.Comment, _ = delimiter(in.CSV.Comments)
+	}
+	cr.FieldsPerRecord = -1
+	return &csvRecords{r: cr, limit: limit, header: in.CSV.FileHeaderInfo}
+}
+
+// csvRecords reads CSV records. Field values are strings.
+type csvRecords struct {
+	r      *csv.Reader
+	limit  *recordLimitReader
+	header string
+	names  []string
+}
+
+func (c *csvRecords) next() (*record, error) {
+	fields, err := c.read()
+	if err != nil {
+		return nil, err
+	}
+	if c.header != HeaderNone {
+		if c.header == HeaderUse {
+			c.names = fields
+		}
+		c.header = HeaderNone
+		if fields, err = c.read(); err != nil {
+			return nil, err
+		}
+	}
+
+	rec := &record{names: make([]string, len(fields)), values: make([]any, len(fields))}
+	for i, field := range fields {
+		if i < len(c.names) {
+			rec.names[i] = c.names[i]
+		} else {
+			rec.names[i] = "_" + strconv.Itoa(i+1)
+		}
+		rec.values[i] = field
+	}
+	return rec, nil
+}
+
+func (c *csvRecords) read() ([]string, error) {
+	c.limit.reset()
+	fields, err := c.r.Read()
+	switch {
+	case err == nil, errors.Is(err, io.EOF):
+		return fields, err
+	case isAbort(err):
+		return nil, err
+	}
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) && isAbort(parseErr.Err) {
+		return nil, parseErr.Err
+	}
+	return nil, fmt.Errorf("%w: malformed CSV: %w", ErrQueryFailed, err)
+}
+
+// jsonRecords reads a sequence of JSON values, such as newline-delimited
+// JSON. The elements of a top-level array are read as separate records.
+type jsonRecords struct {
+	dec     *json.Decoder
+	limit   *recordLimitReader
+	inArray bool
+}
+
+func (j *jsonRecords) next() (*record, error) {
+	for {
+		j.limit.reset()
+		if j.inArray {
+			if !j.dec.More() {
+				if _, err := j.dec.Token(); err != nil {
+					return nil, j.wrap(err)
+				}
+				j.inArray = false
+				continue
+			}
+		} else if j.dec.More() && startsArray(j.dec.Buffered()) {
+			if _, err := j.dec.Token(); err != nil {
+				return nil, j.wrap(err)
+			}
+			j.inArray = true
+			continue
+		}
+
+		var value any
+		if err := j.dec.Decode(&value); err != nil {
+			if errors.Is(err, io.EOF) && !j.inArray {
+				return nil, io.EOF
+			}
+			return nil, j.wrap(err)
+		}
+		value = normalizeJSON(value)
+		if object, ok := value.(map[string]any); ok {
+			return objectRecord(object), nil
+		}
+		return &record{names: []string{"_1"}, values: []any{value}}, nil
+	}
+}
+
+func (j *jsonRecords) wrap(err error) error {
+	if isAbort(err) {
+		return err
+	}
+	if errors.Is(err, io.EOF) {
+		err = io.ErrUnexpectedEOF
+	}
+	return fmt.Errorf("%w: malformed JSON: %w", ErrQueryFailed, err)
+}
+
+// startsArray reports whether the first non-space byte of b opens an
+// array. Decoder.More has already buffered it.
+func startsArray(b io.Reader) bool {
+	br := bufio.NewReader(b)
+	for {
+		c, err := br.ReadByte()
+		if err != nil {
+			return false
+		}
+		switch c {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		return c == '['
+	}
+}
+
+// normalizeJSON converts the json.Number values of a decoded JSON value to
+// int64 or float64.
+func normalizeJSON(v any) any {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]any:
+		for k, elem := range v {
+			v[k] = normalizeJSON(elem)
+		}
+	case []any:
+		for i, elem := range v {
+			v[i] = normalizeJSON(elem)
+		}
+	}
+	return v
+}
+
+// objectRecord returns the fields of a JSON object as a record, in key
+// order.
+func objectRecord(object map[string]any) *record {
+	rec := &record{names: make([]string, 0, len(object)), values: make([]any, 0, len(object))}
+	for k := range object {
+		rec.names = append(rec.names, k)
+	}
+	sort.Strings(rec.names)
+	for _, k := range rec.names {
+		rec.values = append(rec.values, object[k])
+	}
+	return rec
+}
+
+// parquetRecords reads the rows of a Parquet file.
+type parquetRecords struct {
+	r      *parquet.Reader
+	fields []parquet.Field
+}
+
+func newParquetRecords(f *os.File) (recordReader, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	pf, err := parquet.OpenFile(f, info.Size())
+	if err != nil {
+		return nil, fmt.Errorf("%w: not a Parquet object: %w", ErrQueryFailed, err)
+	}
+	return &parquetRecords{r: parquet.NewReader(pf), fields: pf.Schema().Fields()}, nil
+}
+
+func (p *parquetRecords) next() (rec *record, err error) {
+	// The reader panics on some malformed files.
+	defer func() {
+		if r := recover(); r != nil {
+			rec, err = nil, fmt.Errorf("%w: malformed Parquet: %v", ErrQueryFailed, r)
+		}
+	}()
+
+	row := map[string]any{}
+	if err := p.r.Read(&row); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("%w: malformed Parquet: %w", ErrQueryFailed, err)
+	}
+	rec = &record{names: make([]string, len(p.fields)), values: make([]any, len(p.fields))}
+	for i, field := range p.fields {
+		rec.names[i] = field.Name()
+		rec.values[i] = parquetValue(row[field.Name()], field)
+	}
+	return rec, nil
+}
+
+// parquetValue converts a value read from a Parquet column to a query
+// value. Dates and timestamps become RFC 3339 text.
+func parquetValue(v any, field parquet.Field) any {
+	if lt := field.Type().LogicalType(); lt != nil && field.Leaf() {
+		switch t := lt.Value.(type) {
+		case *format.TimestampType:
+			if n, ok := v.(int64); ok {
+				return time.Unix(0, 0).Add(time.Duration(n) * t.Unit.Value.Duration()).UTC().Format(time.RFC3339Nano)
+			}
+		case *format.DateType:
+			if n, ok := v.(int32); ok {
+				return time.Unix(int64(n)*86400, 0).UTC().Format(time.DateOnly)
+			}
+		}
+	}
+	return normalizeParquet(v)
+}
+
+func normalizeParquet(v any) any {
+	switch v := v.(type) {
+	case int8:
+		return int64(v)
+	case int16:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case uint8:
+		return int64(v)
+	case uint16:
+		return int64(v)
+	case uint32:
+		return int64(v)
+	case uint64:
+		if v > math.MaxInt64 {
+			return float64(v)
+		}
+		return int64(v)
+	case int:
+		return int64(v)
+	case float32:
+		return float64(v)
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	case map[string]any:
+		for k, elem := range v {
+			v[k] = normalizeParquet(elem)
+		}
+	case []any:
+		for i, elem := range v {
+			v[i] = normalizeParquet(elem)
+		}
+	}
+	return v
+}
+
+// recordWriter writes result records.
+type recordWriter interface {
+	write(names []string, values []any) error
+	flush() error
+}
+
+// newRecordWriter returns a writer of records to w in out's format.
+func newRecordWriter(out Output, w io.Writer) recordWriter {
+	if out.Format == FormatCSV {
+		cw := csv.NewWriter(w)
+		cw.Comma, _ = delimiter(out.FieldDelimiter)
+		return &csvWriter{w: cw}
+	}
+	return &jsonWriter{w: bufio.NewWriterSize(w, 32<<10)}
+}
+
+// csvWriter writes records as CSV lines. Nested values are written as
+// JSON.
+type csvWriter struct {
+	w      *csv.Writer
+	fields []string
+}
+
+func (c *csvWriter) write(_ []string, values []any) error {
+	c.fields = slices.Grow(c.fields[:0], len(values))[:len(values)]
+	for i, v := range values {
+		c.fields[i] = toText(v)
+	}
+	if err := c.w.Write(c.fields); err != nil {
+		return err
+	}
+	return c.w.Error()
+}
+
+func (c *csvWriter) flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// jsonWriter writes each record as a JSON object on its own line, with
+// fields in projection order.
+type jsonWriter struct {
+	w   *bufio.Writer
+	buf bytes.Buffer
+}
+
+func (j *jsonWriter) write(names []string, values []any) error {
+	j.buf.Reset()
+	enc := json.NewEncoder(&j.buf)
+	enc.SetEscapeHTML(false)
+	j.buf.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			j.buf.WriteByte(',')
+		}
+		if err := enc.Encode(name); err != nil {
+			return err
+		}
+		j.buf.Truncate(j.buf.Len() - 1)
+		j.buf.WriteByte(':')
+		if err := enc.Encode(jsonValue(values[i])); err != nil {
+			return fmt.Errorf("%w: %w", ErrQueryFailed, err)
+		}
+		j.buf.Truncate(j.buf.Len() - 1)
+	}
+	j.buf.WriteString("}\n")
+	_, err := j.w.Write(j.buf.Bytes())
+	return err
+}
+
+func (j *jsonWriter) flush() error {
+	return j.w.Flush()
+}
+
+// jsonValue replaces floats JSON cannot represent with null.
+func jsonValue(v any) any {
+	if f, ok := v.(float64); ok && (math.IsNaN(f) || math.IsInf(f, 0)) {
+		return nil
+	}
+	return v
+}
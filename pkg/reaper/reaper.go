@@ -0,0 +1,305 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package reaper reclaims the storage held by uploads that were started but
+// never finished: backend-native multipart uploads (common.MultipartAborter),
+// objects staged by common.PutAtomic under common.StagingPrefix, and chunked
+// upload sessions (package upload). A writer that crashes or loses its
+// connection leaves these behind, invisible to listings but still stored
+// and billed.
+//
+// A Reaper lists the uploads in progress and aborts those older than
+// Config.MaxAge. The uploads found and the bytes reclaimed are counted in a
+// Registry, which the server exports as metrics.
+//
+//	reaper, err := reaper.New(storage, &reaper.Config{MaxAge: 24 * time.Hour})
+//	result, err := reaper.Run(ctx)
+package reaper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/upload"
+)
+
+// DefaultMaxAge is the age after which an unfinished upload is aborted
+// when Config.MaxAge is zero.
+const DefaultMaxAge = 24 * time.Hour
+
+// ErrInvalidConfig is returned by New for a malformed configuration.
+var ErrInvalidConfig = fmt.Errorf("%w: invalid reaper configuration", common.ErrInvalidArgument)
+
+// Kind is the kind of an unfinished upload.
+type Kind string
+
+// Kinds of unfinished uploads.
+const (
+	// KindMultipart is a backend-native multipart upload.
+	KindMultipart Kind = "multipart"
+
+	// KindStaged is an object staged by common.PutAtomic.
+	KindStaged Kind = "staged"
+
+	// KindSession is a chunked upload session.
+	KindSession Kind = "session"
+)
+
+// Upload is an upload in progress.
+type Upload struct {
+	Kind Kind   `json:"kind"`
+	Key  string `json:"key"`
+
+	// ID is the multipart upload ID or the upload session ID; staged
+	// objects have none.
+	ID string `json:"id,omitempty"`
+
+	// Started is when the upload began, and Size the bytes stored so far.
+	Started time.Time `json:"started"`
+	Size    int64     `json:"size"`
+
+	// Expires is when an upload session can no longer be continued.
+	Expires time.Time `json:"expires,omitzero"`
+}
+
+// Config configures a Reaper. Zero fields take their defaults.
+type Config struct {
+	// Backend names the backend in metrics.
+	Backend string
+
+	// MaxAge is how long an upload may stay unfinished before it is
+	// aborted (default: DefaultMaxAge). It must exceed the time the
+	// longest upload takes, or uploads still running are aborted.
+	MaxAge time.Duration
+
+	// Sessions, if set, is the manager of the backend's chunked upload
+	// sessions. Sessions past their expiry are aborted regardless of
+	// MaxAge.
+	Sessions *upload.Manager
+
+	// DryRun finds stale uploads without aborting them.
+	DryRun bool
+
+	// Registry counts the uploads found and aborted. If nil, Default is
+	// used.
+	Registry *Registry
+}
+
+// Result summarizes a reaper run.
+type Result struct {
+	// InProgress counts the uploads found, and Bytes their size.
+	InProgress int   `json:"in_progress"`
+	Bytes      int64 `json:"bytes"`
+
+	// Aborted counts the stale uploads aborted, or that would have been
+	// in a dry run, and Reclaimed their size.
+	Aborted   int   `json:"aborted"`
+	Reclaimed int64 `json:"reclaimed"`
+
+	// Failed counts the stale uploads that could not be aborted.
+	Failed int `json:"failed"`
+
+	// Stale describes every upload aborted.
+	Stale []Upload `json:"stale,omitempty"`
+}
+
+// Reaper finds and aborts unfinished uploads of one backend.
+type Reaper struct {
+	storage   common.Storage
+	multipart common.MultipartAborter
+	staging   bool
+	cfg       Config
+	now       func() time.Time
+}
+
+// New returns a Reaper of storage. Multipart uploads are reaped if storage,
+// or a backend it wraps, implements common.MultipartAborter, and staged
+// objects if the chain reserves common.StagingPrefix (see
+// common.NewStagingStorage), so that clients cannot have written there.
+func New(storage common.Storage, cfg *Config) (*Reaper, error) {
+	var c Config
+	if cfg != nil {
+		c = *cfg
+	}
+	if c.MaxAge < 0 {
+		return nil, fmt.Errorf("%w: max age must not be negative", ErrInvalidConfig)
+	}
+	if c.MaxAge == 0 {
+		c.MaxAge = DefaultMaxAge
+	}
+	if c.Registry == nil {
+		c.Registry = Default
+	}
+	return &Reaper{storage: storage, multipart: findMultipart(storage), staging: reservesStaging(storage), cfg: c, now: time.Now}, nil
+}
+
+// findMultipart returns the first backend in storage's chain of wrappers
+// that keeps multipart uploads, or nil.
+func findMultipart(storage common.Storage) common.MultipartAborter {
+	for storage != nil {
+		if aborter, ok := storage.(common.MultipartAborter); ok {
+			return aborter
+		}
+		wrapper, ok := storage.(interface{ Underlying() common.Storage })
+		if !ok {
+			break
+		}
+		storage = wrapper.Underlying()
+	}
+	return nil
+}
+
+// reservesStaging reports whether a wrapper in storage's chain reserves
+// common.StagingPrefix.
+func reservesStaging(storage common.Storage) bool {
+	for storage != nil {
+		if reserved, ok := storage.(interface{ Prefix() string }); ok && reserved.Prefix() == common.StagingPrefix {
+			return true
+		}
+		wrapper, ok := storage.(interface{ Underlying() common.Storage })
+		if !ok {
+			break
+		}
+		storage = wrapper.Underlying()
+	}
+	return false
+}
+
+// Uploads returns the uploads in progress, oldest first, and records them
+// in the registry.
+func (r *Reaper) Uploads(ctx context.Context) ([]Upload, error) {
+	var uploads []Upload
+	if r.multipart != nil {
+		pending, err := r.multipart.ListMultipartUploads(ctx, "")
+		if err != nil {
+			return nil, fmt.Errorf("list multipart uploads: %w", err)
+		}
+		for _, p := range pending {
+			uploads = append(uploads, Upload{Kind: KindMultipart, Key: p.Key, ID: p.UploadID, Started: p.Initiated, Size: p.Size})
+		}
+	}
+
+	staged, err := r.staged(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list staged uploads: %w", err)
+	}
+	uploads = append(uploads, staged...)
+
+	if r.cfg.Sessions != nil {
+		sessions, err := r.cfg.Sessions.Sessions(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("list upload sessions: %w", err)
+		}
+		for _, s := range sessions {
+			uploads = append(uploads, Upload{Kind: KindSession, Key: s.Key, ID: s.ID, Started: s.CreatedAt, Size: s.Received(), Expires: s.ExpiresAt})
+		}
+	}
+
+	sort.SliceStable(uploads, func(i, j int) bool { return uploads[i].Started.Before(uploads[j].Started) })
+	r.cfg.Registry.recordInProgress(r.cfg.Backend, uploads)
+	return uploads, nil
+}
+
+// staged lists the objects staged by common.PutAtomic. Other objects under
+// the prefix, and every object when the namespace is not reserved, may be
+// user data and are left alone.
+func (r *Reaper) staged(ctx context.Context) ([]Upload, error) {
+	if !r.staging {
+		return nil, nil
+	}
+	ctx = common.WithStagingAccess(ctx)
+	var uploads []Upload
+	opts := &common.ListOptions{Prefix: common.StagingPrefix}
+	for {
+		page, err := r.storage.ListWithOptions(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range page.Objects {
+			if obj == nil || !common.IsStagedKey(obj.Key) {
+				continue
+			}
+			u := Upload{Kind: KindStaged, Key: obj.Key}
+			if obj.Metadata != nil {
+				u.Started = obj.Metadata.LastModified
+				u.Size = obj.Metadata.Size
+			}
+			uploads = append(uploads, u)
+		}
+		if page.NextToken == "" {
+			return uploads, nil
+		}
+		opts.ContinueFrom = page.NextToken
+	}
+}
+
+// Run aborts the uploads that have been in progress longer than MaxAge,
+// and upload sessions past their expiry. An upload that cannot be aborted
+// is counted as failed and the others are still tried; the returned error
+// is the first such failure.
+func (r *Reaper) Run(ctx context.Context) (*Result, error) {
+	uploads, err := r.Uploads(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{InProgress: len(uploads)}
+	now := r.now()
+	cutoff := now.Add(-r.cfg.MaxAge)
+	var firstErr error
+	for _, u := range uploads {
+		result.Bytes += u.Size
+		expired := !u.Expires.IsZero() && !now.Before(u.Expires)
+		if !u.Started.Before(cutoff) && !expired {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		if !r.cfg.DryRun {
+			err := r.abort(ctx, u)
+			if errors.Is(err, common.ErrNotFound) {
+				// Finished or aborted since it was listed.
+				continue
+			}
+			if err != nil {
+				result.Failed++
+				if firstErr == nil {
+					firstErr = fmt.Errorf("abort %s upload of %s: %w", u.Kind, u.Key, err)
+				}
+				continue
+			}
+			r.cfg.Registry.recordReaped(r.cfg.Backend, u)
+		}
+		result.Stale = append(result.Stale, u)
+		result.Aborted++
+		result.Reclaimed += u.Size
+	}
+	return result, firstErr
+}
+
+// abort aborts an upload.
+func (r *Reaper) abort(ctx context.Context, u Upload) error {
+	switch u.Kind {
+	case KindMultipart:
+		return r.multipart.AbortMultipartUpload(ctx, u.Key, u.ID)
+	case KindSession:
+		return r.cfg.Sessions.Remove(ctx, u.ID)
+	default:
+		return r.storage.DeleteWithContext(common.WithStagingAccess(ctx), u.Key)
+	}
+}
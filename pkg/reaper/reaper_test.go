@@ -0,0 +1,195 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package reaper
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+	"github.com/jeremyhahn/go-objstore/pkg/upload"
+)
+
+// multipartStorage is a backend with unfinished multipart uploads.
+type multipartStorage struct {
+	common.Storage
+	uploads []common.MultipartUpload
+}
+
+func (s *multipartStorage) ListMultipartUploads(ctx context.Context, prefix string) ([]common.MultipartUpload, error) {
+	return s.uploads, nil
+}
+
+func (s *multipartStorage) AbortMultipartUpload(ctx context.Context, key, uploadID string) error {
+	for i, u := range s.uploads {
+		if u.Key == key && u.UploadID == uploadID {
+			s.uploads = append(s.uploads[:i], s.uploads[i+1:]...)
+			return nil
+		}
+	}
+	return common.ErrNotFound
+}
+
+// stagedKey names an object staged by common.PutAtomic.
+const stagedKey = common.StagingPrefix + "0123456789abcdef0123456789abcdef"
+
+// wrapper stands for the facade's wrappers around a backend.
+type wrapper struct {
+	common.Storage
+}
+
+func (w *wrapper) Underlying() common.Storage {
+	return w.Storage
+}
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	backend := &multipartStorage{
+		Storage: memory.New(),
+		uploads: []common.MultipartUpload{
+			{Key: "old.bin", UploadID: "u1", Initiated: now.Add(-48 * time.Hour), Size: 100},
+			{Key: "new.bin", UploadID: "u2", Initiated: now.Add(-time.Minute), Size: 10},
+		},
+	}
+	if err := backend.Put(stagedKey, strings.NewReader("staged")); err != nil {
+		t.Fatal(err)
+	}
+	if err := backend.Put("kept.txt", strings.NewReader("kept")); err != nil {
+		t.Fatal(err)
+	}
+	sessions, err := upload.NewManager(backend, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err := sessions.Create(ctx, "video.mp4", upload.Options{TTL: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	registry := NewRegistry()
+	r, err := New(&wrapper{Storage: common.NewStagingStorage(backend)}, &Config{Backend: "s3", MaxAge: 12 * time.Hour, Sessions: sessions, Registry: registry})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	uploads, err := r.Uploads(ctx)
+	if err != nil {
+		t.Fatalf("Uploads() error = %v", err)
+	}
+	if len(uploads) != 4 || uploads[0].Key != "old.bin" {
+		t.Fatalf("Uploads() = %+v, want 4 uploads, oldest first", uploads)
+	}
+
+	// Nothing but the old multipart upload is stale yet.
+	result, err := r.Run(ctx)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.InProgress != 4 || result.Aborted != 1 || result.Reclaimed != 100 || result.Stale[0].ID != "u1" {
+		t.Fatalf("Run() = %+v, want the old multipart upload aborted", result)
+	}
+
+	// A day later the staged object and the expired session go too.
+	r.now = func() time.Time { return now.Add(24 * time.Hour) }
+	result, err = r.Run(ctx)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.Aborted != 3 || result.Reclaimed != 10+int64(len("staged")) {
+		t.Fatalf("Run() a day later = %+v, want 3 uploads aborted", result)
+	}
+	if _, err := sessions.Lookup(ctx, session.ID); !errors.Is(err, upload.ErrSessionNotFound) {
+		t.Errorf("session still exists: %v", err)
+	}
+	if ok, _ := backend.Exists(ctx, "kept.txt"); !ok {
+		t.Error("an object outside the staging namespace was removed")
+	}
+
+	var multipart Stats
+	for _, s := range registry.Stats() {
+		if s.Kind == KindMultipart {
+			multipart = s
+		}
+	}
+	if multipart.Aborted != 2 || multipart.ReclaimedBytes != 110 || multipart.InProgress != 0 {
+		t.Errorf("multipart Stats = %+v", multipart)
+	}
+}
+
+func TestRunDryRun(t *testing.T) {
+	backend := memory.New()
+	if err := backend.Put(stagedKey, strings.NewReader("staged")); err != nil {
+		t.Fatal(err)
+	}
+	r, err := New(common.NewStagingStorage(backend), &Config{DryRun: true, Registry: NewRegistry()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.now = func() time.Time { return time.Now().Add(DefaultMaxAge + time.Hour) }
+
+	result, err := r.Run(context.Background())
+	if err != nil || result.Aborted != 1 {
+		t.Fatalf("Run() = %+v, %v, want one stale upload", result, err)
+	}
+	if ok, _ := backend.Exists(context.Background(), stagedKey); !ok {
+		t.Error("a dry run removed the staged object")
+	}
+}
+
+func TestRunKeepsUserData(t *testing.T) {
+	ctx := context.Background()
+	backend := memory.New()
+	for _, key := range []string{stagedKey, common.StagingPrefix + "report.csv"} {
+		if err := backend.Put(key, strings.NewReader("data")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	later := func() time.Time { return time.Now().Add(DefaultMaxAge + time.Hour) }
+
+	// Without the reservation anything under the prefix may be user data.
+	r, err := New(backend, &Config{Registry: NewRegistry()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.now = later
+	result, err := r.Run(ctx)
+	if err != nil || result.InProgress != 0 {
+		t.Fatalf("Run() on an unreserved backend = %+v, %v, want nothing reaped", result, err)
+	}
+
+	// With it, only the objects PutAtomic names are reaped.
+	r, err = New(common.NewStagingStorage(backend), &Config{Registry: NewRegistry()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.now = later
+	result, err = r.Run(ctx)
+	if err != nil || result.Aborted != 1 || result.Stale[0].Key != stagedKey {
+		t.Fatalf("Run() = %+v, %v, want only the staged object reaped", result, err)
+	}
+	if ok, _ := backend.Exists(ctx, common.StagingPrefix+"report.csv"); !ok {
+		t.Error("user data under the staging prefix was removed")
+	}
+}
+
+func TestNewInvalid(t *testing.T) {
+	if _, err := New(memory.New(), &Config{MaxAge: -time.Hour}); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("New() error = %v, want ErrInvalidConfig", err)
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package reaper
+
+import (
+	"sort"
+	"sync"
+)
+
+// Stats counts the unfinished uploads of one kind on one backend.
+type Stats struct {
+	// Backend is the name of the backend and Kind the kind of upload.
+	Backend string
+	Kind    Kind
+
+	// InProgress counts the uploads found by the last listing, and
+	// InProgressBytes their size.
+	InProgress      uint64
+	InProgressBytes uint64
+
+	// Aborted counts the stale uploads aborted, and ReclaimedBytes their
+	// size.
+	Aborted        uint64
+	ReclaimedBytes uint64
+}
+
+// Registry accumulates Stats per backend and kind of upload. It is safe
+// for concurrent use.
+type Registry struct {
+	mu    sync.Mutex
+	stats map[statsKey]*Stats
+}
+
+type statsKey struct {
+	backend string
+	kind    Kind
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{stats: make(map[statsKey]*Stats)}
+}
+
+// Default is the process-wide registry reapers record into.
+var Default = NewRegistry()
+
+// statsLocked returns the stats of backend and kind, creating them if
+// needed.
+func (r *Registry) statsLocked(backend string, kind Kind) *Stats {
+	key := statsKey{backend: backend, kind: kind}
+	s, ok := r.stats[key]
+	if !ok {
+		s = &Stats{Backend: backend, Kind: kind}
+		r.stats[key] = s
+	}
+	return s
+}
+
+// recordInProgress replaces the uploads in progress on backend with
+// uploads. Every kind is reset, so kinds with none left read zero.
+func (r *Registry) recordInProgress(backend string, uploads []Upload) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, kind := range []Kind{KindMultipart, KindStaged, KindSession} {
+		s := r.statsLocked(backend, kind)
+		s.InProgress, s.InProgressBytes = 0, 0
+	}
+	for _, u := range uploads {
+		s := r.statsLocked(backend, u.Kind)
+		s.InProgress++
+		s.InProgressBytes += uint64(max(u.Size, 0)) // #nosec G115 -- clamped to non-negative
+	}
+}
+
+// recordReaped records a stale upload aborted.
+func (r *Registry) recordReaped(backend string, u Upload) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.statsLocked(backend, u.Kind)
+	s.Aborted++
+	s.ReclaimedBytes += uint64(max(u.Size, 0)) // #nosec G115 -- clamped to non-negative
+	if s.InProgress > 0 {
+		s.InProgress--
+		s.InProgressBytes -= min(s.InProgressBytes, uint64(max(u.Size, 0))) // #nosec G115 -- clamped to non-negative
+	}
+}
+
+// Stats returns the statistics of every backend and kind, sorted by
+// backend and kind.
+func (r *Registry) Stats() []Stats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stats := make([]Stats, 0, len(r.stats))
+	for _, s := range r.stats {
+		stats = append(stats, *s)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Backend != stats[j].Backend {
+			return stats[i].Backend < stats[j].Backend
+		}
+		return stats[i].Kind < stats[j].Kind
+	})
+	return stats
+}
@@ -0,0 +1,669 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package reconcile brings a server's configuration to a declared state,
+// so that lifecycle policies, replication policies, quotas, webhooks and
+// aliases can be kept in version control and applied from CI.
+//
+// LoadFile reads the desired state, Diff compares it with what a Target
+// reports and returns a Plan of creates, updates and deletes, and Apply
+// carries the plan out. Only the sections present in the file are
+// managed: a file without "replication" leaves replication policies alone,
+// while "replication: []" deletes every one of them.
+package reconcile
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/alias"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/events"
+	"github.com/jeremyhahn/go-objstore/pkg/lifecycle"
+	"github.com/jeremyhahn/go-objstore/pkg/overlay"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// ErrInvalidConfig is returned when a desired state file is malformed.
+	ErrInvalidConfig = errors.New("invalid desired state")
+
+	// ErrUnsupported is returned when a file declares settings the target
+	// cannot manage.
+	ErrUnsupported = errors.New("not supported by the target")
+)
+
+// startupSections are settings servers only read at startup, with the
+// flag that configures them.
+var startupSections = map[string]string{
+	"buckets": "the server's backend flags",
+}
+
+// Kinds of resources a plan changes.
+const (
+	KindPolicy      = "policy"
+	KindReplication = "replication"
+	KindQuota       = "quota"
+	KindWebhook     = "webhook"
+	KindAlias       = "alias"
+)
+
+// Operations of a change.
+const (
+	OpCreate = "create"
+	OpUpdate = "update"
+	OpDelete = "delete"
+)
+
+// File is the desired state of a server. A nil section is not managed.
+type File struct {
+	// Policies are the lifecycle policies.
+	Policies []lifecycle.Rule `json:"policies"`
+
+	// Replication are the replication policies.
+	Replication []Replication `json:"replication"`
+
+	// Quotas map key prefixes to the quotas of the data stored under them.
+	Quotas map[string]Quota `json:"quotas"`
+
+	// Webhooks are the event notification rules.
+	Webhooks []events.Rule `json:"webhooks"`
+
+	// Aliases map alias names to the keys they point at.
+	Aliases map[string]string `json:"aliases"`
+}
+
+// Replication is a replication policy as written in a desired state file.
+type Replication struct {
+	ID                  string            `json:"id"`
+	SourceBackend       string            `json:"source_backend"`
+	SourceSettings      map[string]string `json:"source_settings,omitempty"`
+	SourcePrefix        string            `json:"source_prefix,omitempty"`
+	DestinationBackend  string            `json:"destination_backend"`
+	DestinationSettings map[string]string `json:"destination_settings,omitempty"`
+
+	// CheckInterval is a duration such as "5m".
+	CheckInterval string `json:"check_interval"`
+
+	// Enabled defaults to true.
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Mode is "transparent" (the default) or "opaque".
+	Mode       common.ReplicationMode   `json:"mode,omitempty"`
+	Encryption *common.EncryptionPolicy `json:"encryption,omitempty"`
+	WriteOnce  bool                     `json:"write_once,omitempty"`
+}
+
+// Quota limits the data stored under a prefix. Zero fields are unlimited.
+type Quota struct {
+	MaxBytes   int64 `json:"max_bytes,omitempty"`
+	MaxObjects int64 `json:"max_objects,omitempty"`
+}
+
+// LoadFile reads a desired state from a YAML or JSON file. Unknown fields
+// are rejected, so that typos are not silently ignored.
+func LoadFile(filename string) (*File, error) {
+	data, err := os.ReadFile(filename) // #nosec G304 -- path supplied by the operator
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+	return Parse(data)
+}
+
+// Parse reads a desired state from YAML or JSON. Field names are those of
+// the JSON encoding in both formats.
+func Parse(data []byte) (*File, error) {
+	var raw any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+	if sections, ok := raw.(map[string]any); ok {
+		for _, name := range slices.Sorted(maps.Keys(sections)) {
+			if flag, ok := startupSections[name]; ok {
+				return nil, fmt.Errorf("%w: %s are configured when the server starts, with %s", ErrUnsupported, name, flag)
+			}
+		}
+	}
+	encoded, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+
+	file := &File{}
+	decoder := json.NewDecoder(strings.NewReader(string(encoded)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(file); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+	return file, nil
+}
+
+// lifecyclePolicies returns the declared lifecycle policies, validated.
+func (f *File) lifecyclePolicies() (map[string]common.LifecyclePolicy, error) {
+	policies := make(map[string]common.LifecyclePolicy, len(f.Policies))
+	for _, rule := range f.Policies {
+		policy, err := rule.Policy()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+		}
+		if _, ok := policies[rule.ID]; ok {
+			return nil, fmt.Errorf("%w: duplicate policy %q", ErrInvalidConfig, rule.ID)
+		}
+		policies[rule.ID] = policy
+	}
+	return policies, nil
+}
+
+// replicationPolicies returns the declared replication policies, validated.
+func (f *File) replicationPolicies() (map[string]common.ReplicationPolicy, error) {
+	policies := make(map[string]common.ReplicationPolicy, len(f.Replication))
+	for _, r := range f.Replication {
+		policy, err := r.Policy()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := policies[r.ID]; ok {
+			return nil, fmt.Errorf("%w: duplicate replication policy %q", ErrInvalidConfig, r.ID)
+		}
+		policies[r.ID] = policy
+	}
+	return policies, nil
+}
+
+// quotas returns the declared quotas, validated.
+func (f *File) quotas() (map[string]overlay.Quota, error) {
+	quotas := make(map[string]overlay.Quota, len(f.Quotas))
+	for prefix, q := range f.Quotas {
+		if q.MaxBytes < 0 || q.MaxObjects < 0 {
+			return nil, fmt.Errorf("%w: quota of prefix %q is negative", ErrInvalidConfig, prefix)
+		}
+		quotas[prefix] = overlay.Quota{MaxBytes: q.MaxBytes, MaxObjects: q.MaxObjects}
+	}
+	return quotas, nil
+}
+
+// webhooks returns the declared webhooks by ID, validated.
+func (f *File) webhooks() (map[string]events.Rule, error) {
+	if err := (&events.Config{Rules: f.Webhooks}).Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+	webhooks := make(map[string]events.Rule, len(f.Webhooks))
+	for _, rule := range f.Webhooks {
+		if rule.ID == "" {
+			return nil, fmt.Errorf("%w: webhooks need an id", ErrInvalidConfig)
+		}
+		webhooks[rule.ID] = rule
+	}
+	return webhooks, nil
+}
+
+// Policy returns the declared replication policy, validated.
+func (r Replication) Policy() (common.ReplicationPolicy, error) {
+	if r.ID == "" || r.SourceBackend == "" || r.DestinationBackend == "" {
+		return common.ReplicationPolicy{}, fmt.Errorf("%w: replication policies need an id, source_backend and destination_backend", ErrInvalidConfig)
+	}
+	interval, err := time.ParseDuration(r.CheckInterval)
+	if err != nil || interval <= 0 {
+		return common.ReplicationPolicy{}, fmt.Errorf("%w: replication policy %q: invalid check_interval %q", ErrInvalidConfig, r.ID, r.CheckInterval)
+	}
+	mode := r.Mode
+	if mode == "" {
+		mode = common.ReplicationModeTransparent
+	}
+	if mode != common.ReplicationModeTransparent && mode != common.ReplicationModeOpaque {
+		return common.ReplicationPolicy{}, fmt.Errorf("%w: replication policy %q: unknown mode %q", ErrInvalidConfig, r.ID, mode)
+	}
+	return common.ReplicationPolicy{
+		ID:                  r.ID,
+		SourceBackend:       r.SourceBackend,
+		SourceSettings:      r.SourceSettings,
+		SourcePrefix:        r.SourcePrefix,
+		DestinationBackend:  r.DestinationBackend,
+		DestinationSettings: r.DestinationSettings,
+		CheckInterval:       interval,
+		Enabled:             r.Enabled == nil || *r.Enabled,
+		ReplicationMode:     mode,
+		Encryption:          r.Encryption,
+		WriteOnce:           r.WriteOnce,
+	}, nil
+}
+
+// PolicyStore manages lifecycle policies.
+type PolicyStore interface {
+	GetPolicies(ctx context.Context) ([]common.LifecyclePolicy, error)
+	AddPolicy(ctx context.Context, policy common.LifecyclePolicy) error
+	RemovePolicy(ctx context.Context, id string) error
+}
+
+// ReplicationStore manages replication policies.
+type ReplicationStore interface {
+	GetReplicationPolicies(ctx context.Context) ([]common.ReplicationPolicy, error)
+	AddReplicationPolicy(ctx context.Context, policy common.ReplicationPolicy) error
+	RemoveReplicationPolicy(ctx context.Context, id string) error
+}
+
+// QuotaStore manages the quotas of key prefixes.
+type QuotaStore interface {
+	ListQuotas(ctx context.Context) (map[string]overlay.Quota, error)
+	SetQuota(ctx context.Context, prefix string, quota overlay.Quota) error
+	RemoveQuota(ctx context.Context, prefix string) error
+}
+
+// WebhookStore manages event notification rules.
+type WebhookStore interface {
+	ListWebhooks(ctx context.Context) ([]events.Rule, error)
+	SetWebhook(ctx context.Context, rule events.Rule) error
+	DeleteWebhook(ctx context.Context, id string) error
+}
+
+// AliasStore manages aliases.
+type AliasStore interface {
+	ListAliases(ctx context.Context, prefix string) ([]alias.Alias, error)
+	SetAlias(ctx context.Context, name, target string) (*alias.Alias, error)
+	DeleteAlias(ctx context.Context, name string) error
+}
+
+// Target is the server a desired state is applied to. A nil store cannot
+// be managed; declaring its section returns ErrUnsupported.
+type Target struct {
+	Policies    PolicyStore
+	Replication ReplicationStore
+	Quotas      QuotaStore
+	Webhooks    WebhookStore
+	Aliases     AliasStore
+}
+
+// FieldChange is a field an update changes.
+type FieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// Change creates, updates or deletes a resource.
+type Change struct {
+	Kind string `json:"kind"`
+	ID   string `json:"id"`
+	Op   string `json:"op"`
+
+	// Fields are the fields an update changes.
+	Fields []FieldChange `json:"fields,omitempty"`
+
+	policy      common.LifecyclePolicy
+	replication common.ReplicationPolicy
+	quota       overlay.Quota
+	webhook     events.Rule
+	target      string
+}
+
+// Plan is the changes that bring a target to a desired state, in the
+// order they are applied: lifecycle policies, replication policies,
+// quotas, webhooks, then aliases, each by ID.
+type Plan struct {
+	Changes []Change `json:"changes"`
+}
+
+// Empty reports whether the target is already in the desired state.
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// Diff compares the desired state with the target and returns the changes
+// Apply makes. Nothing is changed.
+func Diff(ctx context.Context, target Target, file *File) (*Plan, error) {
+	plan := &Plan{Changes: []Change{}}
+	if file.Policies != nil {
+		if err := diffPolicies(ctx, target.Policies, file, plan); err != nil {
+			return nil, err
+		}
+	}
+	if file.Replication != nil {
+		if err := diffReplication(ctx, target.Replication, file, plan); err != nil {
+			return nil, err
+		}
+	}
+	if file.Quotas != nil {
+		if err := diffQuotas(ctx, target.Quotas, file, plan); err != nil {
+			return nil, err
+		}
+	}
+	if file.Webhooks != nil {
+		if err := diffWebhooks(ctx, target.Webhooks, file, plan); err != nil {
+			return nil, err
+		}
+	}
+	if file.Aliases != nil {
+		if err := diffAliases(ctx, target.Aliases, file, plan); err != nil {
+			return nil, err
+		}
+	}
+	return plan, nil
+}
+
+func diffPolicies(ctx context.Context, store PolicyStore, file *File, plan *Plan) error {
+	desired, err := file.lifecyclePolicies()
+	if err != nil {
+		return err
+	}
+	if store == nil {
+		return fmt.Errorf("%w: lifecycle policies", ErrUnsupported)
+	}
+	current, err := store.GetPolicies(ctx)
+	if err != nil {
+		return err
+	}
+	actual := make(map[string]common.LifecyclePolicy, len(current))
+	for _, p := range current {
+		actual[p.ID] = p
+	}
+	for _, id := range sortedIDs(desired, actual) {
+		want, declared := desired[id]
+		have, exists := actual[id]
+		change := Change{Kind: KindPolicy, ID: id, policy: want}
+		switch {
+		case !declared:
+			change.Op = OpDelete
+		case !exists:
+			change.Op = OpCreate
+		default:
+			change.Fields = compare(
+				"prefix", have.Prefix, want.Prefix,
+				"retention", have.Retention.String(), want.Retention.String(),
+				"action", have.Action, want.Action,
+				"storage_class", have.StorageClass, want.StorageClass,
+			)
+			if len(change.Fields) == 0 {
+				continue
+			}
+			change.Op = OpUpdate
+		}
+		plan.Changes = append(plan.Changes, change)
+	}
+	return nil
+}
+
+func diffReplication(ctx context.Context, store ReplicationStore, file *File, plan *Plan) error {
+	desired, err := file.replicationPolicies()
+	if err != nil {
+		return err
+	}
+	if store == nil {
+		return fmt.Errorf("%w: replication policies", ErrUnsupported)
+	}
+	current, err := store.GetReplicationPolicies(ctx)
+	if err != nil {
+		return err
+	}
+	actual := make(map[string]common.ReplicationPolicy, len(current))
+	for _, p := range current {
+		if p.ReplicationMode == "" {
+			p.ReplicationMode = common.ReplicationModeTransparent
+		}
+		actual[p.ID] = p
+	}
+	for _, id := range sortedIDs(desired, actual) {
+		want, declared := desired[id]
+		have, exists := actual[id]
+		change := Change{Kind: KindReplication, ID: id, replication: want}
+		switch {
+		case !declared:
+			change.Op = OpDelete
+		case !exists:
+			change.Op = OpCreate
+		default:
+			change.Fields = compare(
+				"source_backend", have.SourceBackend, want.SourceBackend,
+				"source_settings", formatSettings(have.SourceSettings), formatSettings(want.SourceSettings),
+				"source_prefix", have.SourcePrefix, want.SourcePrefix,
+				"destination_backend", have.DestinationBackend, want.DestinationBackend,
+				"destination_settings", formatSettings(have.DestinationSettings), formatSettings(want.DestinationSettings),
+				"check_interval", have.CheckInterval.String(), want.CheckInterval.String(),
+				"enabled", fmt.Sprint(have.Enabled), fmt.Sprint(want.Enabled),
+				"mode", string(have.ReplicationMode), string(want.ReplicationMode),
+				"write_once", fmt.Sprint(have.WriteOnce), fmt.Sprint(want.WriteOnce),
+			)
+			if !reflect.DeepEqual(have.Encryption, want.Encryption) {
+				change.Fields = append(change.Fields, FieldChange{Field: "encryption", From: formatJSON(have.Encryption), To: formatJSON(want.Encryption)})
+			}
+			if len(change.Fields) == 0 {
+				continue
+			}
+			change.Op = OpUpdate
+		}
+		plan.Changes = append(plan.Changes, change)
+	}
+	return nil
+}
+
+func diffQuotas(ctx context.Context, store QuotaStore, file *File, plan *Plan) error {
+	desired, err := file.quotas()
+	if err != nil {
+		return err
+	}
+	if store == nil {
+		return fmt.Errorf("%w: quotas", ErrUnsupported)
+	}
+	actual, err := store.ListQuotas(ctx)
+	if err != nil {
+		return err
+	}
+	for _, prefix := range sortedIDs(desired, actual) {
+		want, declared := desired[prefix]
+		have, exists := actual[prefix]
+		change := Change{Kind: KindQuota, ID: prefix, quota: want}
+		switch {
+		case !declared:
+			change.Op = OpDelete
+		case !exists:
+			change.Op = OpCreate
+		default:
+			change.Fields = compare(
+				"max_bytes", fmt.Sprint(have.MaxBytes), fmt.Sprint(want.MaxBytes),
+				"max_objects", fmt.Sprint(have.MaxObjects), fmt.Sprint(want.MaxObjects),
+			)
+			if len(change.Fields) == 0 {
+				continue
+			}
+			change.Op = OpUpdate
+		}
+		plan.Changes = append(plan.Changes, change)
+	}
+	return nil
+}
+
+func diffWebhooks(ctx context.Context, store WebhookStore, file *File, plan *Plan) error {
+	desired, err := file.webhooks()
+	if err != nil {
+		return err
+	}
+	if store == nil {
+		return fmt.Errorf("%w: webhooks", ErrUnsupported)
+	}
+	current, err := store.ListWebhooks(ctx)
+	if err != nil {
+		return err
+	}
+	actual := make(map[string]events.Rule, len(current))
+	for _, rule := range current {
+		actual[rule.ID] = rule
+	}
+	for _, id := range sortedIDs(desired, actual) {
+		want, declared := desired[id]
+		have, exists := actual[id]
+		change := Change{Kind: KindWebhook, ID: id, webhook: want}
+		switch {
+		case !declared:
+			change.Op = OpDelete
+		case !exists:
+			change.Op = OpCreate
+		default:
+			change.Fields = compare(
+				"events", strings.Join(have.Events, ","), strings.Join(want.Events, ","),
+				"prefix", have.Prefix, want.Prefix,
+				"suffix", have.Suffix, want.Suffix,
+				"sink_type", have.Sink.Type, want.Sink.Type,
+			)
+			// Sink settings often carry credentials, so only the names of
+			// the settings are shown.
+			if !maps.Equal(have.Sink.Settings, want.Sink.Settings) {
+				change.Fields = append(change.Fields, FieldChange{
+					Field: "sink_settings",
+					From:  strings.Join(slices.Sorted(maps.Keys(have.Sink.Settings)), ","),
+					To:    strings.Join(slices.Sorted(maps.Keys(want.Sink.Settings)), ","),
+				})
+			}
+			if len(change.Fields) == 0 {
+				continue
+			}
+			change.Op = OpUpdate
+		}
+		plan.Changes = append(plan.Changes, change)
+	}
+	return nil
+}
+
+func diffAliases(ctx context.Context, store AliasStore, file *File, plan *Plan) error {
+	if store == nil {
+		return fmt.Errorf("%w: aliases", ErrUnsupported)
+	}
+	for name, target := range file.Aliases {
+		if name == "" || target == "" {
+			return fmt.Errorf("%w: aliases need a name and a target", ErrInvalidConfig)
+		}
+	}
+	current, err := store.ListAliases(ctx, "")
+	if err != nil {
+		return err
+	}
+	actual := make(map[string]string, len(current))
+	for _, a := range current {
+		actual[a.Name] = a.Target
+	}
+	for _, name := range sortedIDs(file.Aliases, actual) {
+		want, declared := file.Aliases[name]
+		have, exists := actual[name]
+		change := Change{Kind: KindAlias, ID: name, target: want}
+		switch {
+		case !declared:
+			change.Op = OpDelete
+		case !exists:
+			change.Op = OpCreate
+		case have != want:
+			change.Op = OpUpdate
+			change.Fields = []FieldChange{{Field: "target", From: have, To: want}}
+		default:
+			continue
+		}
+		plan.Changes = append(plan.Changes, change)
+	}
+	return nil
+}
+
+// Apply makes the changes of plan, in order, and returns how many were
+// made before the first error. Lifecycle and replication policies are
+// updated by removing and adding them again; quotas, webhooks and aliases
+// are replaced.
+func Apply(ctx context.Context, target Target, plan *Plan) (int, error) {
+	for i, change := range plan.Changes {
+		if err := apply(ctx, target, change); err != nil {
+			return i, fmt.Errorf("%s %s %q: %w", change.Op, change.Kind, change.ID, err)
+		}
+	}
+	return len(plan.Changes), nil
+}
+
+func apply(ctx context.Context, target Target, change Change) error {
+	switch change.Kind {
+	case KindPolicy:
+		if change.Op != OpCreate {
+			if err := target.Policies.RemovePolicy(ctx, change.ID); err != nil {
+				return err
+			}
+		}
+		if change.Op != OpDelete {
+			return target.Policies.AddPolicy(ctx, change.policy)
+		}
+	case KindReplication:
+		if change.Op != OpCreate {
+			if err := target.Replication.RemoveReplicationPolicy(ctx, change.ID); err != nil {
+				return err
+			}
+		}
+		if change.Op != OpDelete {
+			return target.Replication.AddReplicationPolicy(ctx, change.replication)
+		}
+	case KindQuota:
+		if change.Op == OpDelete {
+			return target.Quotas.RemoveQuota(ctx, change.ID)
+		}
+		return target.Quotas.SetQuota(ctx, change.ID, change.quota)
+	case KindWebhook:
+		if change.Op == OpDelete {
+			return target.Webhooks.DeleteWebhook(ctx, change.ID)
+		}
+		return target.Webhooks.SetWebhook(ctx, change.webhook)
+	case KindAlias:
+		if change.Op == OpDelete {
+			return target.Aliases.DeleteAlias(ctx, change.ID)
+		}
+		_, err := target.Aliases.SetAlias(ctx, change.ID, change.target)
+		return err
+	}
+	return nil
+}
+
+// sortedIDs returns the keys of desired and actual, sorted.
+func sortedIDs[V, W any](desired map[string]V, actual map[string]W) []string {
+	ids := slices.Collect(maps.Keys(desired))
+	for id := range actual {
+		if _, ok := desired[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// compare takes name, from, to triples and returns those that differ.
+func compare(fields ...string) []FieldChange {
+	var changes []FieldChange
+	for i := 0; i+2 < len(fields); i += 3 {
+		if fields[i+1] != fields[i+2] {
+			changes = append(changes, FieldChange{Field: fields[i], From: fields[i+1], To: fields[i+2]})
+		}
+	}
+	return changes
+}
+
+// formatSettings formats settings as sorted "key=value" pairs, so that
+// an empty map and no map compare equal.
+func formatSettings(settings map[string]string) string {
+	pairs := make([]string, 0, len(settings))
+	for _, key := range slices.Sorted(maps.Keys(settings)) {
+		pairs = append(pairs, key+"="+settings[key])
+	}
+	return strings.Join(pairs, ",")
+}
+
+func formatJSON(v any) string {
+	data, _ := json.Marshal(v) //nolint:errcheck // encryption policies always marshal
+	return string(data)
+}
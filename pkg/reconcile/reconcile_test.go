@@ -0,0 +1,335 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/alias"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/events"
+	"github.com/jeremyhahn/go-objstore/pkg/overlay"
+)
+
+// fakeServer keeps its configuration in memory and records calls.
+type fakeServer struct {
+	policies    map[string]common.LifecyclePolicy
+	replication map[string]common.ReplicationPolicy
+	quotas      map[string]overlay.Quota
+	webhooks    map[string]events.Rule
+	aliases     map[string]string
+	calls       []string
+	fail        string
+}
+
+func newFakeServer() *fakeServer {
+	return &fakeServer{
+		policies: map[string]common.LifecyclePolicy{
+			"old-temp":  {ID: "old-temp", Prefix: "tmp/", Retention: 24 * time.Hour, Action: "delete"},
+			"cold-logs": {ID: "cold-logs", Prefix: "logs/", Retention: 7 * 24 * time.Hour, Action: "delete"},
+			"reports":   {ID: "reports", Prefix: "reports/", Retention: 365 * 24 * time.Hour, Action: "delete"},
+		},
+		replication: map[string]common.ReplicationPolicy{
+			"backup": {ID: "backup", SourceBackend: "local", DestinationBackend: "s3", CheckInterval: time.Hour, Enabled: true},
+		},
+		quotas: map[string]overlay.Quota{
+			"tenants/acme/": {MaxBytes: 100},
+			"tmp/":          {MaxObjects: 5},
+		},
+		webhooks: map[string]events.Rule{
+			"uploads": {ID: "uploads", Prefix: "uploads/", Sink: events.SinkConfig{Type: "slack", Settings: map[string]string{"webhookUrl": "https://hooks.example.com/a"}}},
+			"old":     {ID: "old", Sink: events.SinkConfig{Type: "sns"}},
+		},
+		aliases: map[string]string{"latest": "releases/v1.tar.gz", "stale": "releases/v0.tar.gz"},
+	}
+}
+
+func (f *fakeServer) call(name string) error {
+	f.calls = append(f.calls, name)
+	if name == f.fail {
+		return errors.New("server error")
+	}
+	return nil
+}
+
+func (f *fakeServer) GetPolicies(context.Context) ([]common.LifecyclePolicy, error) {
+	policies := make([]common.LifecyclePolicy, 0, len(f.policies))
+	for _, p := range f.policies {
+		policies = append(policies, p)
+	}
+	return policies, nil
+}
+
+func (f *fakeServer) AddPolicy(_ context.Context, policy common.LifecyclePolicy) error {
+	f.policies[policy.ID] = policy
+	return f.call("add policy " + policy.ID)
+}
+
+func (f *fakeServer) RemovePolicy(_ context.Context, id string) error {
+	delete(f.policies, id)
+	return f.call("remove policy " + id)
+}
+
+func (f *fakeServer) GetReplicationPolicies(context.Context) ([]common.ReplicationPolicy, error) {
+	policies := make([]common.ReplicationPolicy, 0, len(f.replication))
+	for _, p := range f.replication {
+		policies = append(policies, p)
+	}
+	return policies, nil
+}
+
+func (f *fakeServer) AddReplicationPolicy(_ context.Context, policy common.ReplicationPolicy) error {
+	f.replication[policy.ID] = policy
+	return f.call("add replication " + policy.ID)
+}
+
+func (f *fakeServer) RemoveReplicationPolicy(_ context.Context, id string) error {
+	delete(f.replication, id)
+	return f.call("remove replication " + id)
+}
+
+func (f *fakeServer) ListQuotas(context.Context) (map[string]overlay.Quota, error) {
+	return maps.Clone(f.quotas), nil
+}
+
+func (f *fakeServer) SetQuota(_ context.Context, prefix string, quota overlay.Quota) error {
+	f.quotas[prefix] = quota
+	return f.call("set quota " + prefix)
+}
+
+func (f *fakeServer) RemoveQuota(_ context.Context, prefix string) error {
+	delete(f.quotas, prefix)
+	return f.call("remove quota " + prefix)
+}
+
+func (f *fakeServer) ListWebhooks(context.Context) ([]events.Rule, error) {
+	return slices.Collect(maps.Values(f.webhooks)), nil
+}
+
+func (f *fakeServer) SetWebhook(_ context.Context, rule events.Rule) error {
+	f.webhooks[rule.ID] = rule
+	return f.call("set webhook " + rule.ID)
+}
+
+func (f *fakeServer) DeleteWebhook(_ context.Context, id string) error {
+	delete(f.webhooks, id)
+	return f.call("delete webhook " + id)
+}
+
+func (f *fakeServer) ListAliases(context.Context, string) ([]alias.Alias, error) {
+	aliases := make([]alias.Alias, 0, len(f.aliases))
+	for name, target := range f.aliases {
+		aliases = append(aliases, alias.Alias{Name: name, Target: target})
+	}
+	return aliases, nil
+}
+
+func (f *fakeServer) SetAlias(_ context.Context, name, target string) (*alias.Alias, error) {
+	f.aliases[name] = target
+	return &alias.Alias{Name: name, Target: target}, f.call("set alias " + name)
+}
+
+func (f *fakeServer) DeleteAlias(_ context.Context, name string) error {
+	delete(f.aliases, name)
+	return f.call("delete alias " + name)
+}
+
+func (f *fakeServer) target() Target {
+	return Target{Policies: f, Replication: f, Quotas: f, Webhooks: f, Aliases: f}
+}
+
+const desiredState = `
+policies:
+  - id: cold-logs
+    prefix: logs/
+    retention_days: 30
+    action: transition
+    storage_class: GLACIER
+  - id: reports
+    prefix: reports/
+    retention_days: 365
+    action: delete
+  - id: scratch
+    prefix: scratch/
+    retention_days: 1
+    action: delete
+replication:
+  - id: backup
+    source_backend: local
+    destination_backend: s3
+    check_interval: 1h
+quotas:
+  tenants/acme/:
+    max_bytes: 1024
+  logs/:
+    max_objects: 1000
+webhooks:
+  - id: uploads
+    prefix: uploads/
+    sink:
+      type: slack
+      settings:
+        webhookUrl: https://hooks.example.com/b
+aliases:
+  latest: releases/v2.tar.gz
+`
+
+func TestDiffAndApply(t *testing.T) {
+	file, err := Parse([]byte(desiredState))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	server := newFakeServer()
+	ctx := context.Background()
+
+	plan, err := Diff(ctx, server.target(), file)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	var summary []string
+	for _, c := range plan.Changes {
+		summary = append(summary, fmt.Sprintf("%s %s %s", c.Op, c.Kind, c.ID))
+	}
+	want := []string{
+		"update policy cold-logs",
+		"delete policy old-temp",
+		"create policy scratch",
+		"create quota logs/",
+		"update quota tenants/acme/",
+		"delete quota tmp/",
+		"delete webhook old",
+		"update webhook uploads",
+		"update alias latest",
+		"delete alias stale",
+	}
+	if !slices.Equal(summary, want) {
+		t.Fatalf("plan = %v, want %v", summary, want)
+	}
+	if fields := plan.Changes[0].Fields; len(fields) != 3 || fields[0] != (FieldChange{Field: "retention", From: "168h0m0s", To: "720h0m0s"}) {
+		t.Errorf("cold-logs fields = %+v, want retention, action and storage_class", fields)
+	}
+	// Webhook settings may be credentials and are not shown.
+	if fields := plan.Changes[7].Fields; len(fields) != 1 || fields[0] != (FieldChange{Field: "sink_settings", From: "webhookUrl", To: "webhookUrl"}) {
+		t.Errorf("uploads fields = %+v, want sink_settings names", fields)
+	}
+	if len(server.calls) != 0 {
+		t.Errorf("Diff() changed the server: %v", server.calls)
+	}
+
+	applied, err := Apply(ctx, server.target(), plan)
+	if err != nil || applied != len(plan.Changes) {
+		t.Fatalf("Apply() = %d, %v", applied, err)
+	}
+	if p := server.policies["cold-logs"]; p.Action != common.ActionTransition || p.StorageClass != "GLACIER" {
+		t.Errorf("cold-logs = %+v, want a transition to GLACIER", p)
+	}
+	if _, ok := server.policies["old-temp"]; ok || server.aliases["latest"] != "releases/v2.tar.gz" || len(server.aliases) != 1 {
+		t.Errorf("server = %+v, %+v after Apply()", server.policies, server.aliases)
+	}
+	if len(server.quotas) != 2 || server.quotas["tenants/acme/"].MaxBytes != 1024 || server.quotas["logs/"].MaxObjects != 1000 {
+		t.Errorf("quotas = %+v after Apply()", server.quotas)
+	}
+	if len(server.webhooks) != 1 || server.webhooks["uploads"].Sink.Settings["webhookUrl"] != "https://hooks.example.com/b" {
+		t.Errorf("webhooks = %+v after Apply()", server.webhooks)
+	}
+
+	// Applied again, there is nothing left to do
+	plan, err = Diff(ctx, server.target(), file)
+	if err != nil || !plan.Empty() {
+		t.Errorf("second Diff() = %+v, %v, want an empty plan", plan, err)
+	}
+}
+
+func TestDiffUnmanagedSections(t *testing.T) {
+	file, err := Parse([]byte("replication: []\n"))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	server := newFakeServer()
+	// Lifecycle policies and aliases are not declared and stay as they are
+	plan, err := Diff(context.Background(), Target{Replication: server}, file)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	if len(plan.Changes) != 1 || plan.Changes[0].Op != OpDelete || plan.Changes[0].ID != "backup" {
+		t.Errorf("plan = %+v, want the backup replication policy deleted", plan.Changes)
+	}
+
+	file, err = Parse([]byte("aliases:\n  latest: a.txt\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Diff(context.Background(), Target{Policies: server}, file); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Diff() of aliases without an alias store error = %v, want ErrUnsupported", err)
+	}
+}
+
+func TestApplyStopsAtFirstError(t *testing.T) {
+	file, err := Parse([]byte(desiredState))
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := newFakeServer()
+	plan, err := Diff(context.Background(), server.target(), file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.fail = "remove policy old-temp"
+	applied, err := Apply(context.Background(), server.target(), plan)
+	if err == nil || applied != 1 {
+		t.Errorf("Apply() = %d, %v, want an error after one change", applied, err)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, data := range []string{
+		"policies:\n  - id: a\n    action: shred\n",
+		"policies:\n  - id: a\n    action: delete\n  - id: a\n    action: delete\n",
+		"replication:\n  - id: r\n    source_backend: local\n    destination_backend: s3\n    check_interval: soon\n",
+		"replication:\n  - id: r\n    source_backend: local\n    destination_backend: s3\n    check_interval: 1h\n    mode: mirrored\n",
+		"quotas:\n  logs/:\n    max_bytes: -1\n",
+		"quotas:\n  logs/: 10GiB\n",
+		"webhooks:\n  - prefix: a/\n    sink:\n      type: sns\n",
+		"webhooks:\n  - id: a\n    events: [s3:Nothing]\n    sink:\n      type: sns\n",
+		"polices: []\n",
+		"policies: {",
+	} {
+		file, err := Parse([]byte(data))
+		if err == nil {
+			_, err = Diff(context.Background(), newFakeServer().target(), file)
+		}
+		if !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("%q: error = %v, want ErrInvalidConfig", data, err)
+		}
+	}
+	if _, err := Parse([]byte("buckets:\n  logs:\n    versioning: true\n")); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("buckets error = %v, want ErrUnsupported", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "objstore.json")
+	if err := os.WriteFile(path, []byte(`{"aliases": {"latest": "a.txt"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if file, err := LoadFile(path); err != nil || file.Aliases["latest"] != "a.txt" || file.Policies != nil {
+		t.Errorf("LoadFile() = %+v, %v", file, err)
+	}
+}
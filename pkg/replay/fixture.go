@@ -0,0 +1,219 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package replay records the interactions of an application with a real
+// storage backend to a fixture file, and serves them back from a backend
+// that needs no credentials or network. Record once against S3, GCS or
+// Azure, commit the fixture, and run the same tests in CI against the
+// recorded semantics:
+//
+//	// Record (run locally with credentials)
+//	rec := replay.NewRecorder(s3Storage, "testdata/upload.json")
+//	defer rec.Close()
+//	runScenario(t, rec)
+//
+//	// Replay (runs anywhere)
+//	r, err := replay.Open("testdata/upload.json")
+//	runScenario(t, r)
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// FixtureVersion is the version of the fixture format written by Recorder.
+const FixtureVersion = 1
+
+var (
+	// ErrNoInteraction is returned by a Replayer for an operation that was
+	// not recorded.
+	ErrNoInteraction = errors.New("no recorded interaction")
+
+	// ErrFixtureCorrupt is returned when a fixture file cannot be decoded.
+	ErrFixtureCorrupt = errors.New("replay fixture is corrupt")
+)
+
+// Operations recorded in fixtures.
+const (
+	OpPut             = "put"
+	OpGet             = "get"
+	OpGetMetadata     = "get_metadata"
+	OpUpdateMetadata  = "update_metadata"
+	OpExists          = "exists"
+	OpDelete          = "delete"
+	OpList            = "list"
+	OpListWithOptions = "list_with_options"
+	OpArchive         = "archive"
+)
+
+// Interaction is one recorded operation and its outcome.
+type Interaction struct {
+	// Op is the operation, such as "get".
+	Op string `json:"op"`
+
+	// Key is the object key, or the prefix of a list.
+	Key string `json:"key,omitempty"`
+
+	// Options are the options of a paginated listing.
+	Options *common.ListOptions `json:"options,omitempty"`
+
+	// Data is the content a get returned or an archive copied. The size of
+	// a put's content is recorded in Size instead.
+	Data []byte `json:"data,omitempty"`
+
+	// Size is the number of bytes a put stored.
+	Size int64 `json:"size,omitempty"`
+
+	// Metadata is the metadata a put or metadata update sent, or the
+	// metadata a metadata read returned.
+	Metadata *common.Metadata `json:"metadata,omitempty"`
+
+	// Exists is the result of an existence check.
+	Exists bool `json:"exists,omitempty"`
+
+	// Keys are the keys a list returned.
+	Keys []string `json:"keys,omitempty"`
+
+	// Result is the page a paginated listing returned.
+	Result *common.ListResult `json:"result,omitempty"`
+
+	// Error is the message of the error the operation returned.
+	Error string `json:"error,omitempty"`
+
+	// Code classifies Error so replayed errors match the same sentinels,
+	// such as common.ErrNotFound, as the recorded ones.
+	Code common.ErrorCode `json:"code,omitempty"`
+}
+
+// Fixture is the content of a fixture file.
+type Fixture struct {
+	Version      int           `json:"version"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// LoadFixture reads a fixture file.
+func LoadFixture(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- fixture path is chosen by the test author
+	if err != nil {
+		return nil, err
+	}
+	var fixture Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrFixtureCorrupt, path, err)
+	}
+	if fixture.Version != FixtureVersion {
+		return nil, fmt.Errorf("%w: %s: unsupported version %d", ErrFixtureCorrupt, path, fixture.Version)
+	}
+	return &fixture, nil
+}
+
+// Save writes the fixture to path atomically, as indented JSON so changes
+// to recorded fixtures review well.
+func (f *Fixture) Save(path string) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			return err
+		}
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".replay-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// signature identifies the request of an interaction, so that a replayed
+// request is answered by the recording of the same request.
+func (i *Interaction) signature() string {
+	sig := i.Op + "\x00" + i.Key
+	if i.Options != nil {
+		options, _ := json.Marshal(i.Options)
+		sig += "\x00" + string(options)
+	}
+	return sig
+}
+
+// setError records err in the interaction.
+func (i *Interaction) setError(err error) {
+	if err == nil {
+		return
+	}
+	i.Error = err.Error()
+	i.Code = common.Classify(err)
+}
+
+// err returns the recorded error, or nil.
+func (i *Interaction) err() error {
+	if i.Error == "" {
+		return nil
+	}
+	return &replayedError{message: i.Error, sentinel: sentinelForCode(i.Code)}
+}
+
+// replayedError is a recorded error: its message is the original one and it
+// unwraps to the canonical sentinel the original classified as.
+type replayedError struct {
+	message  string
+	sentinel error
+}
+
+func (e *replayedError) Error() string { return e.message }
+
+func (e *replayedError) Unwrap() error { return e.sentinel }
+
+// sentinelForCode returns the canonical error of a classification.
+func sentinelForCode(code common.ErrorCode) error {
+	switch code {
+	case common.CodeNotFound:
+		return common.ErrNotFound
+	case common.CodeAlreadyExists:
+		return common.ErrAlreadyExists
+	case common.CodeInvalidArgument:
+		return common.ErrInvalidArgument
+	case common.CodePermissionDenied:
+		return common.ErrPermissionDenied
+	case common.CodeUnauthenticated:
+		return common.ErrUnauthenticated
+	case common.CodeResourceExhausted:
+		return common.ErrResourceExhausted
+	case common.CodeUnavailable:
+		return common.ErrUnavailable
+	case common.CodePreconditionFailed:
+		return common.ErrPreconditionFailed
+	case common.CodeCanceled:
+		return context.Canceled
+	case common.CodeDeadlineExceeded:
+		return context.DeadlineExceeded
+	default:
+		return common.ErrInternal
+	}
+}
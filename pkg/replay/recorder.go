@@ -0,0 +1,217 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package replay
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// Recorder wraps a backend and records every operation made through it.
+// Objects read through it are buffered in memory so their content can be
+// recorded, so record scenarios with small objects. Lifecycle policies are
+// passed through and not recorded.
+type Recorder struct {
+	common.Storage
+
+	path         string
+	mu           sync.Mutex
+	interactions []Interaction
+}
+
+// NewRecorder returns underlying wrapped to record its interactions to the
+// fixture file at path when Close is called.
+func NewRecorder(underlying common.Storage, path string) *Recorder {
+	return &Recorder{Storage: underlying, path: path}
+}
+
+// Underlying returns the wrapped backend.
+func (r *Recorder) Underlying() common.Storage {
+	return r.Storage
+}
+
+// Interactions returns the interactions recorded so far.
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Interaction(nil), r.interactions...)
+}
+
+// Close writes the recorded interactions to the fixture file.
+func (r *Recorder) Close() error {
+	fixture := &Fixture{Version: FixtureVersion, Interactions: r.Interactions()}
+	return fixture.Save(r.path)
+}
+
+func (r *Recorder) record(i Interaction, err error) {
+	i.setError(err)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.interactions = append(r.interactions, i)
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.Reader.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// Put stores an object and records the put.
+func (r *Recorder) Put(key string, data io.Reader) error {
+	return r.PutWithContext(context.Background(), key, data)
+}
+
+// PutWithContext stores an object and records the put.
+func (r *Recorder) PutWithContext(ctx context.Context, key string, data io.Reader) error {
+	counter := &countingReader{Reader: data}
+	err := r.Storage.PutWithContext(ctx, key, counter)
+	r.record(Interaction{Op: OpPut, Key: key, Size: counter.n}, err)
+	return err
+}
+
+// PutWithMetadata stores an object with metadata and records the put.
+func (r *Recorder) PutWithMetadata(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) error {
+	counter := &countingReader{Reader: data}
+	err := r.Storage.PutWithMetadata(ctx, key, counter, metadata)
+	r.record(Interaction{Op: OpPut, Key: key, Size: counter.n, Metadata: metadata}, err)
+	return err
+}
+
+// PutWithInfo stores an object with metadata, records the put and returns
+// what was stored.
+func (r *Recorder) PutWithInfo(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) (*common.ObjectInfo, error) {
+	counter := &countingReader{Reader: data}
+	info, err := common.PutWithInfo(ctx, r.Storage, key, counter, metadata)
+	r.record(Interaction{Op: OpPut, Key: key, Size: counter.n, Metadata: metadata}, err)
+	return info, err
+}
+
+// Get retrieves an object and records its content.
+func (r *Recorder) Get(key string) (io.ReadCloser, error) {
+	return r.GetWithContext(context.Background(), key)
+}
+
+// GetWithContext retrieves an object and records its content.
+func (r *Recorder) GetWithContext(ctx context.Context, key string) (io.ReadCloser, error) {
+	rc, err := r.Storage.GetWithContext(ctx, key)
+	if err != nil {
+		r.record(Interaction{Op: OpGet, Key: key}, err)
+		return nil, err
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	r.record(Interaction{Op: OpGet, Key: key, Data: data}, err)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// GetMetadata retrieves an object's metadata and records it.
+func (r *Recorder) GetMetadata(ctx context.Context, key string) (*common.Metadata, error) {
+	metadata, err := r.Storage.GetMetadata(ctx, key)
+	r.record(Interaction{Op: OpGetMetadata, Key: key, Metadata: metadata}, err)
+	return metadata, err
+}
+
+// UpdateMetadata updates an object's metadata and records the update.
+func (r *Recorder) UpdateMetadata(ctx context.Context, key string, metadata *common.Metadata) error {
+	err := r.Storage.UpdateMetadata(ctx, key, metadata)
+	r.record(Interaction{Op: OpUpdateMetadata, Key: key, Metadata: metadata}, err)
+	return err
+}
+
+// Exists checks whether an object exists and records the result.
+func (r *Recorder) Exists(ctx context.Context, key string) (bool, error) {
+	exists, err := r.Storage.Exists(ctx, key)
+	r.record(Interaction{Op: OpExists, Key: key, Exists: exists}, err)
+	return exists, err
+}
+
+// Delete removes an object and records the delete.
+func (r *Recorder) Delete(key string) error {
+	return r.DeleteWithContext(context.Background(), key)
+}
+
+// DeleteWithContext removes an object and records the delete.
+func (r *Recorder) DeleteWithContext(ctx context.Context, key string) error {
+	err := r.Storage.DeleteWithContext(ctx, key)
+	r.record(Interaction{Op: OpDelete, Key: key}, err)
+	return err
+}
+
+// List lists keys under prefix and records them.
+func (r *Recorder) List(prefix string) ([]string, error) {
+	return r.ListWithContext(context.Background(), prefix)
+}
+
+// ListWithContext lists keys under prefix and records them.
+func (r *Recorder) ListWithContext(ctx context.Context, prefix string) ([]string, error) {
+	keys, err := r.Storage.ListWithContext(ctx, prefix)
+	r.record(Interaction{Op: OpList, Key: prefix, Keys: keys}, err)
+	return keys, err
+}
+
+// ListWithOptions lists a page of objects and records it.
+func (r *Recorder) ListWithOptions(ctx context.Context, opts *common.ListOptions) (*common.ListResult, error) {
+	result, err := r.Storage.ListWithOptions(ctx, opts)
+	r.record(Interaction{Op: OpListWithOptions, Options: normalizeOptions(opts), Result: result}, err)
+	return result, err
+}
+
+// Archive copies an object to destination and records the content copied.
+func (r *Recorder) Archive(key string, destination common.Archiver) error {
+	if destination == nil {
+		return r.Storage.Archive(key, destination)
+	}
+	capture := &capturingArchiver{Archiver: destination}
+	err := r.Storage.Archive(key, capture)
+	r.record(Interaction{Op: OpArchive, Key: key, Data: capture.data}, err)
+	return err
+}
+
+// capturingArchiver records the content archived to it.
+type capturingArchiver struct {
+	common.Archiver
+	data []byte
+}
+
+func (a *capturingArchiver) Put(key string, data io.Reader) error {
+	content, err := io.ReadAll(data)
+	if err != nil {
+		return err
+	}
+	a.data = content
+	return a.Archiver.Put(key, bytes.NewReader(content))
+}
+
+// normalizeOptions returns options that identify the same listing as opts,
+// so nil and empty options replay alike.
+func normalizeOptions(opts *common.ListOptions) *common.ListOptions {
+	if opts == nil {
+		return &common.ListOptions{}
+	}
+	normalized := *opts
+	return &normalized
+}
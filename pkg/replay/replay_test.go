@@ -0,0 +1,138 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+// archive collects what is archived to it.
+type archive map[string]string
+
+func (a archive) Put(key string, data io.Reader) error {
+	b, err := io.ReadAll(data)
+	a[key] = string(b)
+	return err
+}
+
+// scenario exercises s and returns a transcript of what it observed.
+func scenario(t *testing.T, s common.Storage) string {
+	t.Helper()
+	ctx := context.Background()
+	var out strings.Builder
+	note := func(format string, args ...any) { fmt.Fprintf(&out, format+"\n", args...) }
+
+	err := s.PutWithMetadata(ctx, "docs/a.txt", strings.NewReader("alpha"), &common.Metadata{ContentType: "text/plain"})
+	note("put a: %v", err)
+	note("put b: %v", s.Put("docs/b.txt", strings.NewReader("beta")))
+	if rc, err := s.Get("docs/a.txt"); err == nil {
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		note("get a: %s", data)
+	} else {
+		note("get a: %v", err)
+	}
+	_, err = s.Get("missing")
+	note("get missing: not found=%v", errors.Is(err, common.ErrNotFound))
+	meta, err := s.GetMetadata(ctx, "docs/a.txt")
+	note("metadata a: %s %d %v", meta.ContentType, meta.Size, err)
+	exists, err := s.Exists(ctx, "docs/b.txt")
+	note("exists b: %v %v", exists, err)
+	keys, err := s.List("docs/")
+	note("list: %v %v", keys, err)
+	page, err := s.ListWithOptions(ctx, &common.ListOptions{Prefix: "docs/", MaxResults: 1})
+	note("page: %d %v %v", len(page.Objects), page.Truncated, err)
+	dest := archive{}
+	note("archive: %v %q", s.Archive("docs/b.txt", dest), dest["docs/b.txt"])
+	note("delete a: %v", s.Delete("docs/a.txt"))
+	exists, err = s.Exists(ctx, "docs/a.txt")
+	note("exists a: %v %v", exists, err)
+	return out.String()
+}
+
+func TestRecordReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixtures", "scenario.json")
+	recorder := NewRecorder(memory.New(), path)
+	recorded := scenario(t, recorder)
+	if err := recorder.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	replayer, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if replayed := scenario(t, replayer); replayed != recorded {
+		t.Errorf("replayed transcript:\n%s\nwant recorded transcript:\n%s", replayed, recorded)
+	}
+	if unused := replayer.Unused(); len(unused) != 0 {
+		t.Errorf("Unused() = %+v, want every interaction replayed", unused)
+	}
+
+	// Exhausted requests repeat their last recording; unknown ones fail.
+	if exists, _ := replayer.Exists(context.Background(), "docs/a.txt"); exists {
+		t.Error("repeated Exists() did not return the last recording")
+	}
+	if _, err := replayer.Get("never/recorded"); !errors.Is(err, ErrNoInteraction) {
+		t.Errorf("Get(unrecorded) error = %v, want ErrNoInteraction", err)
+	}
+}
+
+func TestReplayerConfigure(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "fixture.json")
+	fixture := &Fixture{Version: FixtureVersion, Interactions: []Interaction{
+		{Op: OpGet, Key: "k", Data: []byte("v")},
+		{Op: OpDelete, Key: "k", Error: "access denied", Code: common.CodePermissionDenied},
+	}}
+	if err := fixture.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	r := New()
+	if err := r.Configure(map[string]string{}); !errors.Is(err, common.ErrPathNotSet) {
+		t.Errorf("Configure() error = %v, want ErrPathNotSet", err)
+	}
+	if err := r.Configure(map[string]string{"path": path}); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+	rc, err := r.Get("k")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := io.ReadAll(rc); string(data) != "v" {
+		t.Errorf("Get() = %q, want %q", data, "v")
+	}
+	err = r.Delete("k")
+	if !errors.Is(err, common.ErrPermissionDenied) || err.Error() != "access denied" {
+		t.Errorf("Delete() error = %v, want the recorded permission error", err)
+	}
+
+	if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(path); !errors.Is(err, ErrFixtureCorrupt) {
+		t.Errorf("Open(corrupt) error = %v, want ErrFixtureCorrupt", err)
+	}
+}
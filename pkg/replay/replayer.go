@@ -0,0 +1,265 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package replay
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+// Replayer is a storage backend that answers operations with the outcomes
+// recorded in a fixture. Each request is answered by the recordings of the
+// same request (operation, key and list options) in the order they were
+// recorded; once they are used up the last one is repeated. Requests that
+// were never recorded fail with ErrNoInteraction. The content sent by puts
+// is read and discarded, so a replayed get returns what was recorded, not
+// what the test just wrote.
+type Replayer struct {
+	common.LifecycleManager
+
+	mu      sync.Mutex
+	pending map[string][]Interaction
+	last    map[string]Interaction
+}
+
+// New returns a Replayer without interactions, to be loaded with
+// Configure.
+func New() *Replayer {
+	r := &Replayer{LifecycleManager: memory.NewLifecycleManager()}
+	r.load(&Fixture{Version: FixtureVersion})
+	return r
+}
+
+// NewReplayer returns a Replayer that answers with the interactions of
+// fixture.
+func NewReplayer(fixture *Fixture) *Replayer {
+	r := New()
+	r.load(fixture)
+	return r
+}
+
+// Open returns a Replayer that answers with the interactions of the fixture
+// file at path.
+func Open(path string) (*Replayer, error) {
+	fixture, err := LoadFixture(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewReplayer(fixture), nil
+}
+
+// Configure loads the fixture file named by the "path" setting.
+func (r *Replayer) Configure(settings map[string]string) error {
+	path := settings["path"]
+	if path == "" {
+		return common.ErrPathNotSet
+	}
+	fixture, err := LoadFixture(path)
+	if err != nil {
+		return err
+	}
+	r.load(fixture)
+	return nil
+}
+
+func (r *Replayer) load(fixture *Fixture) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pending = make(map[string][]Interaction)
+	r.last = make(map[string]Interaction)
+	for _, i := range fixture.Interactions {
+		sig := i.signature()
+		r.pending[sig] = append(r.pending[sig], i)
+	}
+}
+
+// Unused returns the recorded interactions that have not been replayed,
+// so a test can assert that it made every request it was recorded making.
+func (r *Replayer) Unused() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var unused []Interaction
+	for _, queue := range r.pending {
+		unused = append(unused, queue...)
+	}
+	return unused
+}
+
+// next returns the recording that answers request.
+func (r *Replayer) next(request Interaction) (Interaction, error) {
+	sig := request.signature()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if queue := r.pending[sig]; len(queue) > 0 {
+		r.pending[sig] = queue[1:]
+		if len(queue) == 1 {
+			delete(r.pending, sig)
+		}
+		r.last[sig] = queue[0]
+		return queue[0], nil
+	}
+	if i, ok := r.last[sig]; ok {
+		return i, nil
+	}
+	return Interaction{}, fmt.Errorf("%w: %s %q", ErrNoInteraction, request.Op, request.Key)
+}
+
+// Put discards data and returns the recorded outcome of the put.
+func (r *Replayer) Put(key string, data io.Reader) error {
+	return r.PutWithContext(context.Background(), key, data)
+}
+
+// PutWithContext discards data and returns the recorded outcome of the put.
+func (r *Replayer) PutWithContext(ctx context.Context, key string, data io.Reader) error {
+	return r.PutWithMetadata(ctx, key, data, nil)
+}
+
+// PutWithMetadata discards data and returns the recorded outcome of the
+// put.
+func (r *Replayer) PutWithMetadata(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	i, err := r.next(Interaction{Op: OpPut, Key: key})
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(io.Discard, data); err != nil {
+		return err
+	}
+	return i.err()
+}
+
+// Get returns the recorded content of the object.
+func (r *Replayer) Get(key string) (io.ReadCloser, error) {
+	return r.GetWithContext(context.Background(), key)
+}
+
+// GetWithContext returns the recorded content of the object.
+func (r *Replayer) GetWithContext(ctx context.Context, key string) (io.ReadCloser, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	i, err := r.next(Interaction{Op: OpGet, Key: key})
+	if err != nil {
+		return nil, err
+	}
+	if err := i.err(); err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(i.Data)), nil
+}
+
+// GetMetadata returns the recorded metadata of the object.
+func (r *Replayer) GetMetadata(ctx context.Context, key string) (*common.Metadata, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	i, err := r.next(Interaction{Op: OpGetMetadata, Key: key})
+	if err != nil {
+		return nil, err
+	}
+	return i.Metadata, i.err()
+}
+
+// UpdateMetadata returns the recorded outcome of the metadata update.
+func (r *Replayer) UpdateMetadata(ctx context.Context, key string, metadata *common.Metadata) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	i, err := r.next(Interaction{Op: OpUpdateMetadata, Key: key})
+	if err != nil {
+		return err
+	}
+	return i.err()
+}
+
+// Exists returns the recorded result of the existence check.
+func (r *Replayer) Exists(ctx context.Context, key string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	i, err := r.next(Interaction{Op: OpExists, Key: key})
+	if err != nil {
+		return false, err
+	}
+	return i.Exists, i.err()
+}
+
+// Delete returns the recorded outcome of the delete.
+func (r *Replayer) Delete(key string) error {
+	return r.DeleteWithContext(context.Background(), key)
+}
+
+// DeleteWithContext returns the recorded outcome of the delete.
+func (r *Replayer) DeleteWithContext(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	i, err := r.next(Interaction{Op: OpDelete, Key: key})
+	if err != nil {
+		return err
+	}
+	return i.err()
+}
+
+// List returns the recorded keys under prefix.
+func (r *Replayer) List(prefix string) ([]string, error) {
+	return r.ListWithContext(context.Background(), prefix)
+}
+
+// ListWithContext returns the recorded keys under prefix.
+func (r *Replayer) ListWithContext(ctx context.Context, prefix string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	i, err := r.next(Interaction{Op: OpList, Key: prefix})
+	if err != nil {
+		return nil, err
+	}
+	return i.Keys, i.err()
+}
+
+// ListWithOptions returns the recorded page of the listing.
+func (r *Replayer) ListWithOptions(ctx context.Context, opts *common.ListOptions) (*common.ListResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	i, err := r.next(Interaction{Op: OpListWithOptions, Options: normalizeOptions(opts)})
+	if err != nil {
+		return nil, err
+	}
+	return i.Result, i.err()
+}
+
+// Archive puts the recorded content of the archived object to destination.
+func (r *Replayer) Archive(key string, destination common.Archiver) error {
+	if destination == nil {
+		return common.ErrArchiveDestinationNil
+	}
+	i, err := r.next(Interaction{Op: OpArchive, Key: key})
+	if err != nil {
+		return err
+	}
+	if err := i.err(); err != nil {
+		return err
+	}
+	return destination.Put(key, bytes.NewReader(i.Data))
+}
@@ -23,6 +23,10 @@ import (
 type ChangeDetector struct {
 	source common.Storage
 	dest   common.Storage
+
+	// writeOnce compares against the versioned keys of a write-once
+	// destination (see WriteOnceKey).
+	writeOnce bool
 }
 
 // NewChangeDetector creates a new ChangeDetector.
@@ -33,8 +37,20 @@ func NewChangeDetector(source, dest common.Storage) *ChangeDetector {
 	}
 }
 
+// NewWriteOnceChangeDetector creates a ChangeDetector for a write-once
+// destination, which reports source objects whose current version has not
+// been copied yet.
+func NewWriteOnceChangeDetector(source, dest common.Storage) *ChangeDetector {
+	return &ChangeDetector{
+		source:    source,
+		dest:      dest,
+		writeOnce: true,
+	}
+}
+
 // DetectChanges compares source and destination to find objects that need syncing.
-// It uses ETag and LastModified metadata for comparison.
+// It compares strong validators when both sides have one, and otherwise
+// ETag, size and LastModified metadata.
 // Returns a list of keys that have changed or are new.
 func (cd *ChangeDetector) DetectChanges(ctx context.Context, prefix string) ([]string, error) {
 	var changedKeys []string
@@ -51,6 +67,13 @@ func (cd *ChangeDetector) DetectChanges(ctx context.Context, prefix string) ([]s
 		}
 
 		for _, obj := range result.Objects {
+			if cd.writeOnce {
+				exists, err := cd.dest.Exists(ctx, WriteOnceKey(obj.Key, obj.Metadata))
+				if err != nil || !exists {
+					changedKeys = append(changedKeys, obj.Key)
+				}
+				continue
+			}
 			destMeta, err := cd.dest.GetMetadata(ctx, obj.Key)
 			// If error occurs getting dest metadata, assume object doesn't exist or needs sync
 			if err != nil || hasChanged(obj.Metadata, destMeta) {
@@ -73,8 +96,13 @@ func hasChanged(src, dest *common.Metadata) bool {
 		return true // Object doesn't exist at destination
 	}
 
-	// Compare ETags (most reliable)
-	if src.ETag != "" && dest.ETag != "" && src.ETag != dest.ETag {
+	// Compare content digests, which hold across providers (most reliable)
+	if same, known := common.SameContent(src, dest); known {
+		return !same
+	}
+
+	// Compare ETags
+	if src.ETag != "" && dest.ETag != "" && common.NormalizeETag(src.ETag) != common.NormalizeETag(dest.ETag) {
 		return true
 	}
 
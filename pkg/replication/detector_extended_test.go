@@ -595,3 +595,34 @@ func TestHasChanged_OneETagEmpty(t *testing.T) {
 		t.Error("Expected no change when one ETag is empty and size/time match")
 	}
 }
+
+// TestHasChanged_StrongValidators tests that recorded content digests decide
+// across backends whose ETags differ.
+func TestHasChanged_StrongValidators(t *testing.T) {
+	now := time.Now()
+	const md5 = "5d41402abc4b2a76b9719d911017c592"
+
+	// A single-part S3 source and a local copy that recorded its digest.
+	src := &common.Metadata{
+		Size:         5,
+		ETag:         `"` + md5 + `"`,
+		LastModified: now,
+	}
+	dest := &common.Metadata{
+		Size:         5,
+		ETag:         "1700000000-5",
+		LastModified: now.Add(-time.Hour),
+		Custom: map[string]string{
+			common.MetaChecksumMD5:  md5,
+			common.MetaChecksumSize: "5",
+		},
+	}
+	if hasChanged(src, dest) {
+		t.Error("Expected no change when the digests match despite different ETags and times")
+	}
+
+	src.ETag = `"7d793037a0760186574b0282f2f435e7"`
+	if !hasChanged(src, dest) {
+		t.Error("Expected a change when the digests differ")
+	}
+}
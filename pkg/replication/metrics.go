@@ -26,6 +26,7 @@ type ReplicationMetrics struct {
 	totalObjectsDeleted atomic.Int64
 	totalBytesSynced    atomic.Int64
 	totalErrors         atomic.Int64
+	deltaBytesSaved     atomic.Int64
 
 	// Timing
 	lastSyncTime      atomic.Int64 // Unix timestamp in nanoseconds
@@ -58,6 +59,12 @@ func (m *ReplicationMetrics) IncrementErrors(count int64) {
 	m.totalErrors.Add(count)
 }
 
+// IncrementDeltaBytesSaved adds bytes that delta sync reused from the
+// destination instead of transferring.
+func (m *ReplicationMetrics) IncrementDeltaBytesSaved(bytes int64) {
+	m.deltaBytesSaved.Add(bytes)
+}
+
 // RecordSync records the completion of a sync operation.
 func (m *ReplicationMetrics) RecordSync(duration time.Duration) {
 	m.lastSyncTime.Store(time.Now().UnixNano())
@@ -85,6 +92,12 @@ func (m *ReplicationMetrics) GetTotalErrors() int64 {
 	return m.totalErrors.Load()
 }
 
+// GetDeltaBytesSaved returns the total number of bytes delta sync did not
+// have to transfer.
+func (m *ReplicationMetrics) GetDeltaBytesSaved() int64 {
+	return m.deltaBytesSaved.Load()
+}
+
 // GetLastSyncTime returns the timestamp of the last sync.
 func (m *ReplicationMetrics) GetLastSyncTime() time.Time {
 	nanos := m.lastSyncTime.Load()
@@ -111,6 +124,7 @@ func (m *ReplicationMetrics) GetMetricsSnapshot() MetricsSnapshot {
 		TotalObjectsDeleted: m.GetTotalObjectsDeleted(),
 		TotalBytesSynced:    m.GetTotalBytesSynced(),
 		TotalErrors:         m.GetTotalErrors(),
+		DeltaBytesSaved:     m.GetDeltaBytesSaved(),
 		LastSyncTime:        m.GetLastSyncTime(),
 		AverageSyncDuration: m.GetAverageSyncDuration(),
 		SyncCount:           m.syncCount.Load(),
@@ -124,6 +138,7 @@ func (m *ReplicationMetrics) Reset() {
 	m.totalObjectsDeleted.Store(0)
 	m.totalBytesSynced.Store(0)
 	m.totalErrors.Store(0)
+	m.deltaBytesSaved.Store(0)
 	m.lastSyncTime.Store(0)
 	m.totalSyncDuration.Store(0)
 	m.syncCount.Store(0)
@@ -135,6 +150,7 @@ type MetricsSnapshot struct {
 	TotalObjectsDeleted int64         `json:"total_objects_deleted"`
 	TotalBytesSynced    int64         `json:"total_bytes_synced"`
 	TotalErrors         int64         `json:"total_errors"`
+	DeltaBytesSaved     int64         `json:"delta_bytes_saved"`
 	LastSyncTime        time.Time     `json:"last_sync_time"`
 	AverageSyncDuration time.Duration `json:"average_sync_duration"`
 	SyncCount           int64         `json:"sync_count"`
@@ -16,8 +16,10 @@ package replication
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"os"
+	"sort"
 	"sync"
 	"time"
 
@@ -89,6 +91,12 @@ type PersistentReplicationManager struct {
 	// Background processing control
 	stopChan chan struct{}
 	stopOnce sync.Once
+
+	// shared reloads the policy file before every read and change, for
+	// policy files that other instances also write.
+	shared bool
+	// isLeader, if set, gates the scheduled syncs of Run.
+	isLeader func() bool
 }
 
 // persistedPolicies is the structure used for JSON serialization.
@@ -148,11 +156,53 @@ func NewPersistentReplicationManager(
 		}
 		logger.Info(context.Background(), "No existing policy file found, starting fresh",
 			adapters.Field{Key: "policy_file", Value: policyFile})
+	} else {
+		logger.Info(context.Background(), "Loaded replication policies",
+			adapters.Field{Key: "count", Value: len(prm.policies)})
 	}
 
 	return prm, nil
 }
 
+// SetShared marks the policy file as shared with other instances, for
+// example several servers behind a load balancer keeping it on their common
+// backend. The manager then reloads the file before every read and change
+// so it sees policies added or removed elsewhere.
+func (prm *PersistentReplicationManager) SetShared(shared bool) {
+	prm.mutex.Lock()
+	defer prm.mutex.Unlock()
+	prm.shared = shared
+}
+
+// SetLeaderFunc makes Run skip scheduled syncs unless isLeader returns
+// true, so only one of several instances sharing policies replicates.
+// Manually triggered syncs are not affected.
+func (prm *PersistentReplicationManager) SetLeaderFunc(isLeader func() bool) {
+	prm.mutex.Lock()
+	defer prm.mutex.Unlock()
+	prm.isLeader = isLeader
+}
+
+// refresh reloads a shared policy file.
+func (prm *PersistentReplicationManager) refresh() {
+	prm.mutex.Lock()
+	defer prm.mutex.Unlock()
+	prm.reloadLocked()
+}
+
+// reloadLocked reloads a shared policy file. Must be called with mutex
+// locked.
+func (prm *PersistentReplicationManager) reloadLocked() {
+	if !prm.shared {
+		return
+	}
+	if err := prm.load(); err != nil && !os.IsNotExist(err) {
+		prm.logger.Warn(context.Background(), "Failed to reload shared replication policies",
+			adapters.Field{Key: "policy_file", Value: prm.policyFile},
+			adapters.Field{Key: fieldError, Value: err.Error()})
+	}
+}
+
 // AddPolicy adds a new replication policy and persists it to storage.
 func (prm *PersistentReplicationManager) AddPolicy(policy common.ReplicationPolicy) error {
 	if policy.ID == "" {
@@ -161,6 +211,7 @@ func (prm *PersistentReplicationManager) AddPolicy(policy common.ReplicationPoli
 
 	prm.mutex.Lock()
 	defer prm.mutex.Unlock()
+	prm.reloadLocked()
 
 	prm.policies[policy.ID] = policy
 	// Initialize metrics for the new policy
@@ -182,6 +233,7 @@ func (prm *PersistentReplicationManager) AddPolicy(policy common.ReplicationPoli
 func (prm *PersistentReplicationManager) RemovePolicy(id string) error {
 	prm.mutex.Lock()
 	defer prm.mutex.Unlock()
+	prm.reloadLocked()
 
 	if _, exists := prm.policies[id]; !exists {
 		return common.ErrPolicyNotFound
@@ -205,6 +257,7 @@ func (prm *PersistentReplicationManager) RemovePolicy(id string) error {
 
 // GetPolicy retrieves a replication policy by ID.
 func (prm *PersistentReplicationManager) GetPolicy(id string) (*common.ReplicationPolicy, error) {
+	prm.refresh()
 	prm.mutex.RLock()
 	defer prm.mutex.RUnlock()
 
@@ -218,6 +271,7 @@ func (prm *PersistentReplicationManager) GetPolicy(id string) (*common.Replicati
 
 // GetPolicies retrieves all replication policies.
 func (prm *PersistentReplicationManager) GetPolicies() ([]common.ReplicationPolicy, error) {
+	prm.refresh()
 	prm.mutex.RLock()
 	defer prm.mutex.RUnlock()
 
@@ -351,6 +405,49 @@ func (prm *PersistentReplicationManager) getOrCreateMetrics(policyID string) *Re
 	return metrics
 }
 
+// Destination is the backend an enabled policy keeps copies of objects
+// in, under the same keys.
+type Destination struct {
+	PolicyID string
+
+	// Prefix is the policy's source prefix; only keys starting with it
+	// are copied.
+	Prefix string
+
+	// Storage is the destination, opened the way syncs open it, so reads
+	// return the content the source held.
+	Storage common.Storage
+}
+
+// Destinations returns the destinations of the enabled policies, for
+// example to restore a damaged object from its copy. Write-once policies,
+// which keep versions under their own keys, are left out.
+func (prm *PersistentReplicationManager) Destinations() ([]Destination, error) {
+	policies, err := prm.GetPolicies()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(policies, func(i, j int) bool { return policies[i].ID < policies[j].ID })
+
+	var destinations []Destination
+	for _, policy := range policies {
+		if !policy.Enabled || policy.WriteOnce {
+			continue
+		}
+		backendFactory, sourceFactory, destFactory := prm.getFactories(policy.ID)
+		syncer, err := NewSyncer(policy, backendFactory, sourceFactory, destFactory, prm.logger, prm.auditLog)
+		if err != nil {
+			return nil, fmt.Errorf("policy %s: %w", policy.ID, err)
+		}
+		destinations = append(destinations, Destination{
+			PolicyID: policy.ID,
+			Prefix:   policy.SourcePrefix,
+			Storage:  syncer.dest,
+		})
+	}
+	return destinations, nil
+}
+
 // SyncAll synchronizes all enabled policies.
 func (prm *PersistentReplicationManager) SyncAll(ctx context.Context) (*common.SyncResult, error) {
 	policies, err := prm.GetPolicies()
@@ -426,10 +523,12 @@ func (prm *PersistentReplicationManager) SyncPolicy(ctx context.Context, policyI
 	// Update last sync time on success
 	if err == nil {
 		prm.mutex.Lock()
-		p := prm.policies[policyID]
-		p.LastSyncTime = time.Now()
-		prm.policies[policyID] = p
-		_ = prm.save() // Best effort - don't fail the sync if save fails
+		prm.reloadLocked()
+		if p, exists := prm.policies[policyID]; exists {
+			p.LastSyncTime = time.Now()
+			prm.policies[policyID] = p
+			_ = prm.save() // Best effort - don't fail the sync if save fails
+		}
 		prm.mutex.Unlock()
 	}
 
@@ -510,10 +609,12 @@ func (prm *PersistentReplicationManager) SyncPolicyParallel(ctx context.Context,
 	// Update last sync time on success
 	if err == nil {
 		prm.mutex.Lock()
-		p := prm.policies[policyID]
-		p.LastSyncTime = time.Now()
-		prm.policies[policyID] = p
-		_ = prm.save() // Best effort - don't fail the sync if save fails
+		prm.reloadLocked()
+		if p, exists := prm.policies[policyID]; exists {
+			p.LastSyncTime = time.Now()
+			prm.policies[policyID] = p
+			_ = prm.save() // Best effort - don't fail the sync if save fails
+		}
 		prm.mutex.Unlock()
 	}
 
@@ -532,6 +633,13 @@ func (prm *PersistentReplicationManager) Run(ctx context.Context) {
 	for {
 		select {
 		case <-ticker.C:
+			prm.mutex.RLock()
+			isLeader := prm.isLeader
+			prm.mutex.RUnlock()
+			if isLeader != nil && !isLeader() {
+				prm.logger.Debug(ctx, "Skipping scheduled sync on follower")
+				continue
+			}
 			prm.logger.Debug(ctx, "Running scheduled sync")
 			result, err := prm.SyncAll(ctx)
 			if err != nil {
@@ -634,8 +742,5 @@ func (prm *PersistentReplicationManager) load() error {
 		}
 	}
 
-	prm.logger.Info(context.Background(), "Loaded replication policies",
-		adapters.Field{Key: "count", Value: len(prm.policies)})
-
 	return nil
 }
@@ -19,6 +19,8 @@ import (
 	"errors"
 	"io"
 	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -473,6 +475,44 @@ func TestGetPolicies(t *testing.T) {
 	}
 }
 
+// TestDestinations tests opening the destinations of enabled policies.
+func TestDestinations(t *testing.T) {
+	mgr, err := NewPersistentReplicationManager(newMockFileSystem(), "test-policies.json", 5*time.Minute, nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	dest := t.TempDir()
+	for _, policy := range []common.ReplicationPolicy{
+		{ID: "copy", SourcePrefix: "logs/", Enabled: true},
+		{ID: "disabled", Enabled: false},
+		{ID: "vault", Enabled: true, WriteOnce: true},
+	} {
+		policy.SourceBackend = "local"
+		policy.SourceSettings = map[string]string{"path": t.TempDir()}
+		policy.DestinationBackend = "local"
+		policy.DestinationSettings = map[string]string{"path": dest}
+		policy.ReplicationMode = common.ReplicationModeTransparent
+		if err := mgr.AddPolicy(policy); err != nil {
+			t.Fatalf("Failed to add policy: %v", err)
+		}
+	}
+
+	destinations, err := mgr.Destinations()
+	if err != nil {
+		t.Fatalf("Destinations: %v", err)
+	}
+	if len(destinations) != 1 || destinations[0].PolicyID != "copy" || destinations[0].Prefix != "logs/" {
+		t.Fatalf("destinations = %+v", destinations)
+	}
+	if err := destinations[0].Storage.Put("logs/a", strings.NewReader("a")); err != nil {
+		t.Fatalf("Put to destination: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "logs", "a")); err != nil {
+		t.Errorf("destination storage does not write to the destination: %v", err)
+	}
+}
+
 // TestGetPoliciesEmpty tests retrieving policies when none exist.
 func TestGetPoliciesEmpty(t *testing.T) {
 	fs := newMockFileSystem()
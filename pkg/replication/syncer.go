@@ -17,12 +17,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"sync"
 	"time"
 
 	"github.com/jeremyhahn/go-objstore/pkg/adapters"
 	"github.com/jeremyhahn/go-objstore/pkg/audit"
 	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/delta"
+	"github.com/jeremyhahn/go-objstore/pkg/download"
 	"github.com/jeremyhahn/go-objstore/pkg/factory"
 	"github.com/jeremyhahn/go-objstore/pkg/local"
 )
@@ -54,6 +57,14 @@ type Syncer struct {
 	logger   adapters.Logger
 	auditLog audit.AuditLogger
 	metrics  *ReplicationMetrics
+
+	// downloader fetches large source objects as parallel ranged parts.
+	// A nil downloader reads each object with a single Get.
+	downloader *download.Manager
+
+	// deltaOpts controls rsync-style delta transfer of large objects that
+	// already exist at the destination. Nil disables it.
+	deltaOpts *delta.Options
 }
 
 // NewSyncer creates a new Syncer with proper encryption wrapping based on the policy.
@@ -81,6 +92,16 @@ func NewSyncer(
 		return nil, fmt.Errorf("failed to create destination backend: %w", err)
 	}
 
+	downloadOpts, err := download.ParseSettings(policy.SourceSettings)
+	if err != nil {
+		return nil, fmt.Errorf("invalid download settings: %w", err)
+	}
+
+	deltaOpts, err := delta.ParseSettings(policy.DestinationSettings)
+	if err != nil {
+		return nil, fmt.Errorf("invalid delta settings: %w", err)
+	}
+
 	// Set backend at-rest encryption if applicable (Layer 1)
 	if policy.Encryption != nil && policy.Encryption.Backend != nil && policy.Encryption.Backend.Enabled {
 		if policy.SourceBackend == backendLocal {
@@ -121,6 +142,9 @@ func NewSyncer(
 		logger:   logger,
 		auditLog: auditLog,
 		metrics:  NewReplicationMetrics(),
+
+		downloader: download.NewManager(downloadOpts),
+		deltaOpts:  deltaOpts,
 	}, nil
 }
 
@@ -132,11 +156,13 @@ func (s *Syncer) SyncAll(ctx context.Context) (*common.SyncResult, error) {
 	}
 
 	// Detect changes
-	detector := NewChangeDetector(s.source, s.dest)
-	changedKeys, err := detector.DetectChanges(ctx, s.policy.SourcePrefix)
+	changedKeys, err := s.detector().DetectChanges(ctx, s.policy.SourcePrefix)
 	if err != nil {
 		return nil, fmt.Errorf("change detection failed: %w", err)
 	}
+	if changedKeys, err = s.applyTombstones(ctx, changedKeys, result); err != nil {
+		return nil, err
+	}
 
 	// Sync each changed object
 	for _, key := range changedKeys {
@@ -164,6 +190,7 @@ func (s *Syncer) SyncAll(ctx context.Context) (*common.SyncResult, error) {
 	s.logger.Info(ctx, "Sync completed",
 		adapters.Field{Key: fieldPolicyID, Value: s.policy.ID},
 		adapters.Field{Key: "synced", Value: result.Synced},
+		adapters.Field{Key: "deleted", Value: result.Deleted},
 		adapters.Field{Key: fieldFailed, Value: result.Failed},
 		adapters.Field{Key: "duration", Value: result.Duration.String()})
 
@@ -183,11 +210,13 @@ func (s *Syncer) SyncAllParallel(ctx context.Context, workerCount int) (*common.
 	}
 
 	// Detect changes
-	detector := NewChangeDetector(s.source, s.dest)
-	changedKeys, err := detector.DetectChanges(ctx, s.policy.SourcePrefix)
+	changedKeys, err := s.detector().DetectChanges(ctx, s.policy.SourcePrefix)
 	if err != nil {
 		return nil, fmt.Errorf("change detection failed: %w", err)
 	}
+	if changedKeys, err = s.applyTombstones(ctx, changedKeys, result); err != nil {
+		return nil, err
+	}
 
 	if len(changedKeys) == 0 {
 		result.Duration = time.Since(startTime)
@@ -265,6 +294,7 @@ func (s *Syncer) SyncAllParallel(ctx context.Context, workerCount int) (*common.
 	s.logger.Info(ctx, "Parallel sync completed",
 		adapters.Field{Key: fieldPolicyID, Value: s.policy.ID},
 		adapters.Field{Key: "synced", Value: result.Synced},
+		adapters.Field{Key: "deleted", Value: result.Deleted},
 		adapters.Field{Key: fieldFailed, Value: result.Failed},
 		adapters.Field{Key: "bytes", Value: result.BytesTotal},
 		adapters.Field{Key: "duration", Value: result.Duration.String()})
@@ -272,11 +302,36 @@ func (s *Syncer) SyncAllParallel(ctx context.Context, workerCount int) (*common.
 	return result, nil
 }
 
+// detector returns the change detector for the policy's destination.
+func (s *Syncer) detector() *ChangeDetector {
+	if s.policy.WriteOnce {
+		return NewWriteOnceChangeDetector(s.source, s.dest)
+	}
+	return NewChangeDetector(s.source, s.dest)
+}
+
 // SyncObject synchronizes a single object from source to destination.
 // Returns the size of the object synced.
 func (s *Syncer) SyncObject(ctx context.Context, key string) (int64, error) {
+	if s.policy.WriteOnce {
+		return s.syncWriteOnce(ctx, key)
+	}
+
+	// Send only the changed blocks when the destination has an older version
+	size, ok, err := s.syncDelta(ctx, key)
+	if err != nil {
+		_ = s.auditLog.LogObjectMutation(ctx, "replication_failed",
+			"", "", "", key, "", "", 0, "failure", err)
+		return 0, fmt.Errorf("failed to write destination: %w", err)
+	}
+	if ok {
+		_ = s.auditLog.LogObjectMutation(ctx, "replication_success",
+			"", "", "", key, "", "", size, "success", nil)
+		return size, nil
+	}
+
 	// Get from source (automatically decrypted if encrypted)
-	reader, err := s.source.GetWithContext(ctx, key)
+	reader, err := s.openSource(ctx, key)
 	if err != nil {
 		return 0, fmt.Errorf("failed to read source: %w", err)
 	}
@@ -292,8 +347,9 @@ func (s *Syncer) SyncObject(ctx context.Context, key string) (int64, error) {
 		srcMetadata = &common.Metadata{}
 	}
 
-	// Put to destination (automatically encrypted if enabled)
-	err = s.dest.PutWithMetadata(ctx, key, reader, srcMetadata)
+	// Put to destination (automatically encrypted if enabled), recording
+	// the source's validators so later syncs can compare the copies
+	err = s.dest.PutWithMetadata(ctx, key, reader, common.WithValidators(srcMetadata))
 	if err != nil {
 		_ = s.auditLog.LogObjectMutation(ctx, "replication_failed",
 			"", "", "", key, "", "", 0, "failure", err)
@@ -311,6 +367,88 @@ func (s *Syncer) SyncObject(ctx context.Context, key string) (int64, error) {
 	return srcMetadata.Size, nil
 }
 
+// syncDelta transfers key as a delta against the destination's current
+// version. It reports false without error whenever a delta transfer does not
+// apply: delta sync is disabled, the object is below the threshold, either
+// backend lacks the required capability, the destination has no copy yet, or
+// the destination changed while the delta was being computed. The caller
+// then falls back to a full transfer.
+func (s *Syncer) syncDelta(ctx context.Context, key string) (int64, bool, error) {
+	patcher, ok := s.dest.(delta.Patcher)
+	if !ok {
+		return 0, false, nil
+	}
+	rr, ok := s.source.(common.RangeReader)
+	if !ok {
+		return 0, false, nil
+	}
+
+	srcMetadata, err := s.source.GetMetadata(ctx, key)
+	if err != nil || srcMetadata == nil || !s.deltaOpts.Applies(srcMetadata.Size) {
+		return 0, false, nil
+	}
+	destMetadata, err := s.dest.GetMetadata(ctx, key)
+	if err != nil || destMetadata == nil || destMetadata.ETag == "" {
+		return 0, false, nil
+	}
+
+	base, err := s.dest.GetWithContext(ctx, key)
+	if err != nil {
+		return 0, false, nil
+	}
+	sig, err := delta.NewSignature(base, s.deltaOpts.BlockSize)
+	_ = base.Close()
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to compute signature: %w", err)
+	}
+
+	reader, err := s.openSource(ctx, key)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to read source: %w", err)
+	}
+	ops, err := delta.Diff(sig, reader)
+	_ = reader.Close()
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to compute delta: %w", err)
+	}
+
+	err = patcher.ApplyDelta(ctx, key, destMetadata.ETag, ops, delta.RangeSource(rr, key), common.WithValidators(srcMetadata))
+	if errors.Is(err, delta.ErrNotSupported) || errors.Is(err, delta.ErrBaseChanged) {
+		s.logger.Debug(ctx, "Delta sync not applied, sending full object",
+			adapters.Field{Key: fieldKey, Value: key},
+			adapters.Field{Key: "reason", Value: err.Error()})
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	stats := delta.Summarize(ops)
+	s.metrics.IncrementDeltaBytesSaved(stats.CopiedBytes)
+	s.logger.Debug(ctx, "Object synced with delta",
+		adapters.Field{Key: fieldKey, Value: key},
+		adapters.Field{Key: "size", Value: srcMetadata.Size},
+		adapters.Field{Key: "transferred", Value: stats.LiteralBytes})
+
+	return srcMetadata.Size, true, nil
+}
+
+// openSource opens the source object for reading. With a downloader, large
+// objects on backends that support ranged reads are fetched as parallel
+// parts; the key is checked up front so a missing object is reported here
+// rather than as a destination write failure.
+func (s *Syncer) openSource(ctx context.Context, key string) (io.ReadCloser, error) {
+	if s.downloader == nil {
+		return s.source.GetWithContext(ctx, key)
+	}
+	if exists, err := s.source.Exists(ctx, key); err != nil {
+		return nil, err
+	} else if !exists {
+		return nil, fmt.Errorf("%w: %s", common.ErrKeyNotFound, key)
+	}
+	return s.downloader.Reader(ctx, s.source, key), nil
+}
+
 // GetMetrics returns the current replication metrics.
 func (s *Syncer) GetMetrics() *ReplicationMetrics {
 	return s.metrics
@@ -369,6 +507,15 @@ func (s *Syncer) SyncIncremental(ctx context.Context, changeLog ChangeLog) (*com
 			}
 
 		case operationDelete:
+			if s.policy.WriteOnce {
+				s.skipDelete(ctx, change.Key)
+				if markErr := changeLog.MarkProcessed(change.Key, s.policy.ID); markErr != nil {
+					s.logger.Warn(ctx, "Failed to mark change as processed",
+						adapters.Field{Key: fieldKey, Value: change.Key},
+						adapters.Field{Key: fieldError, Value: markErr.Error()})
+				}
+				continue
+			}
 			// Delete from destination
 			err = s.dest.DeleteWithContext(ctx, change.Key)
 			if err != nil {
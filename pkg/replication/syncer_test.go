@@ -23,6 +23,9 @@ import (
 	"time"
 
 	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/delta"
+	"github.com/jeremyhahn/go-objstore/pkg/download"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
 )
 
 func TestSyncObject_Success(t *testing.T) {
@@ -369,3 +372,118 @@ func TestSyncAll_TimingMetrics(t *testing.T) {
 		t.Error("expected non-zero duration")
 	}
 }
+
+func TestSyncObject_ParallelDownload(t *testing.T) {
+	source := memory.New()
+	dest := memory.New()
+
+	testData := bytes.Repeat([]byte("0123456789abcdef"), 1024)
+	if err := source.Put("big.bin", bytes.NewReader(testData)); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	syncer := &Syncer{
+		policy:     common.ReplicationPolicy{ID: "test-policy"},
+		source:     source,
+		dest:       dest,
+		logger:     &mockLogger{},
+		metrics:    NewReplicationMetrics(),
+		auditLog:   &mockAuditLogger{},
+		downloader: download.NewManager(&download.Options{PartSize: 1000, Concurrency: 3}),
+	}
+
+	size, err := syncer.SyncObject(context.Background(), "big.bin")
+	if err != nil {
+		t.Fatalf("SyncObject failed: %v", err)
+	}
+	if size != int64(len(testData)) {
+		t.Errorf("expected size %d, got %d", len(testData), size)
+	}
+
+	reader, err := dest.Get("big.bin")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	defer reader.Close()
+	synced, _ := io.ReadAll(reader)
+	if !bytes.Equal(synced, testData) {
+		t.Error("synced data does not match source data")
+	}
+
+	if _, err := syncer.SyncObject(context.Background(), "missing.bin"); !errors.Is(err, common.ErrKeyNotFound) {
+		t.Errorf("expected ErrKeyNotFound, got %v", err)
+	}
+}
+
+// rangeCountingStorage records the bytes served by GetRange.
+type rangeCountingStorage struct {
+	common.Storage
+	rangeBytes int64
+}
+
+func (r *rangeCountingStorage) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	r.rangeBytes += length
+	return r.Storage.(common.RangeReader).GetRange(ctx, key, offset, length)
+}
+
+func TestSyncObject_DeltaSync(t *testing.T) {
+	source := &rangeCountingStorage{Storage: memory.New()}
+	dest := memory.New()
+
+	base := bytes.Repeat([]byte("0123456789abcdef"), 4096)
+	changed := append([]byte{}, base...)
+	copy(changed[20000:], "changed block")
+
+	if err := dest.Put("big.bin", bytes.NewReader(base)); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if err := source.Put("big.bin", bytes.NewReader(changed)); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	syncer := &Syncer{
+		policy:    common.ReplicationPolicy{ID: "test-policy"},
+		source:    source,
+		dest:      dest,
+		logger:    &mockLogger{},
+		metrics:   NewReplicationMetrics(),
+		auditLog:  &mockAuditLogger{},
+		deltaOpts: &delta.Options{BlockSize: 1024, Threshold: 1},
+	}
+
+	size, err := syncer.SyncObject(context.Background(), "big.bin")
+	if err != nil {
+		t.Fatalf("SyncObject failed: %v", err)
+	}
+	if size != int64(len(changed)) {
+		t.Errorf("expected size %d, got %d", len(changed), size)
+	}
+
+	reader, err := dest.Get("big.bin")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	defer reader.Close()
+	synced, _ := io.ReadAll(reader)
+	if !bytes.Equal(synced, changed) {
+		t.Error("synced data does not match source data")
+	}
+
+	if source.rangeBytes == 0 || source.rangeBytes > 2048 {
+		t.Errorf("expected only the changed block to be transferred, got %d bytes", source.rangeBytes)
+	}
+	if saved := syncer.metrics.GetDeltaBytesSaved(); saved != int64(len(changed))-source.rangeBytes {
+		t.Errorf("unexpected delta bytes saved %d", saved)
+	}
+
+	// Objects missing at the destination fall back to a full transfer.
+	if err := source.Put("new.bin", bytes.NewReader(changed)); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if _, err := syncer.SyncObject(context.Background(), "new.bin"); err != nil {
+		t.Fatalf("SyncObject failed: %v", err)
+	}
+	if exists, _ := dest.Exists(context.Background(), "new.bin"); !exists {
+		t.Error("expected new.bin to be synced in full")
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package replication
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/tombstone"
+)
+
+// applyTombstones honors deletes deferred by tombstones on either backend.
+// It drops from keys the source's tombstones and tombstoned objects, and
+// objects the destination deleted after they were last modified, so a sync
+// never resurrects a deleted object. Objects tombstoned at the source are
+// deleted at the destination, except on write-once destinations. Deletes
+// are counted in result.
+func (s *Syncer) applyTombstones(ctx context.Context, keys []string, result *common.SyncResult) ([]string, error) {
+	sourcePrefix := tombstone.PrefixFromSettings(s.policy.SourceSettings)
+	sourceTombstones, err := tombstone.Load(ctx, s.source, sourcePrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read source tombstones: %w", err)
+	}
+	destTombstones, err := tombstone.Load(ctx, s.dest, tombstone.PrefixFromSettings(s.policy.DestinationSettings))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read destination tombstones: %w", err)
+	}
+
+	live := keys[:0]
+	for _, key := range keys {
+		if strings.HasPrefix(key, sourcePrefix) || sourceTombstones[key] != nil {
+			continue
+		}
+		if t := destTombstones[key]; t != nil {
+			metadata, err := s.source.GetMetadata(ctx, key)
+			if err == nil && metadata != nil && !metadata.LastModified.After(t.DeletedAt) {
+				s.logger.Debug(ctx, "Object deleted at destination not copied again",
+					adapters.Field{Key: fieldKey, Value: key})
+				continue
+			}
+		}
+		live = append(live, key)
+	}
+
+	if s.policy.WriteOnce {
+		return live, nil
+	}
+	for key := range sourceTombstones {
+		if !strings.HasPrefix(key, s.policy.SourcePrefix) || destTombstones[key] != nil {
+			continue
+		}
+		if exists, err := s.dest.Exists(ctx, key); err != nil || !exists {
+			continue
+		}
+		if err := s.dest.DeleteWithContext(ctx, key); err != nil {
+			result.Failed++
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", key, err))
+			s.logger.Error(ctx, "Object delete failed",
+				adapters.Field{Key: fieldKey, Value: key},
+				adapters.Field{Key: "operation", Value: operationDelete},
+				adapters.Field{Key: fieldError, Value: err.Error()})
+			continue
+		}
+		result.Deleted++
+		_ = s.auditLog.LogObjectMutation(ctx, "replication_delete",
+			"", "", "", key, "", "", 0, "success", nil)
+	}
+	return live, nil
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package replication
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/audit"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+	"github.com/jeremyhahn/go-objstore/pkg/tombstone"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTombstoneSyncer(source, dest common.Storage) *Syncer {
+	return &Syncer{
+		policy:   common.ReplicationPolicy{ID: "tombstones"},
+		source:   source,
+		dest:     dest,
+		logger:   adapters.NewNoOpLogger(),
+		auditLog: audit.NewNoOpAuditLogger(),
+		metrics:  NewReplicationMetrics(),
+	}
+}
+
+func TestSync_PropagatesTombstones(t *testing.T) {
+	ctx := context.Background()
+	source := memory.New()
+	dest := memory.New()
+	manager, err := tombstone.NewManager(source, nil)
+	require.NoError(t, err)
+	deferred := tombstone.NewStorage(source, manager)
+
+	require.NoError(t, deferred.PutWithContext(ctx, "a.txt", bytes.NewReader([]byte("a"))))
+	require.NoError(t, deferred.PutWithContext(ctx, "b.txt", bytes.NewReader([]byte("b"))))
+	result, err := newTombstoneSyncer(source, dest).SyncAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Synced)
+
+	// The deleted object is still in the source during its grace period,
+	// but is deleted at the destination and its tombstone is not copied.
+	require.NoError(t, deferred.DeleteWithContext(ctx, "a.txt"))
+	result, err = newTombstoneSyncer(source, dest).SyncAllParallel(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Synced)
+	assert.Equal(t, 1, result.Deleted)
+	assert.Equal(t, []string{"b.txt"}, destKeys(t, dest))
+}
+
+func TestSync_DoesNotResurrectDeletedObjects(t *testing.T) {
+	ctx := context.Background()
+	lagging := memory.New()
+	dest := memory.New()
+	manager, err := tombstone.NewManager(dest, nil)
+	require.NoError(t, err)
+	deferred := tombstone.NewStorage(dest, manager)
+
+	// The destination deleted and purged an object the lagging replica
+	// still has.
+	require.NoError(t, lagging.PutWithContext(ctx, "a.txt", bytes.NewReader([]byte("a"))))
+	require.NoError(t, deferred.PutWithContext(ctx, "a.txt", bytes.NewReader([]byte("a"))))
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, deferred.DeleteWithContext(ctx, "a.txt"))
+	require.NoError(t, dest.DeleteWithContext(ctx, "a.txt"))
+
+	result, err := newTombstoneSyncer(lagging, dest).SyncAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Synced)
+	exists, err := dest.Exists(ctx, "a.txt")
+	require.NoError(t, err)
+	assert.False(t, exists, "deleted object copied back from a lagging replica")
+
+	// A newer write at the source is copied.
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, lagging.PutWithContext(ctx, "a.txt", bytes.NewReader([]byte("new"))))
+	result, err = newTombstoneSyncer(lagging, dest).SyncAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Synced)
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package replication
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// WriteOnceSeparator separates the source key from the version suffix of a
+// key on a write-once destination.
+const WriteOnceSeparator = "@"
+
+// writeOnceTimeFormat is the UTC timestamp layout of a write-once version
+// suffix. It sorts lexically in time order.
+const writeOnceTimeFormat = "20060102T150405Z"
+
+// maxWriteOnceTagLength bounds the ETag portion of a version suffix.
+const maxWriteOnceTagLength = 16
+
+// WriteOnceKey returns the key under which a write-once destination stores
+// the version of key described by metadata:
+//
+//	<key>@<last-modified>-<etag>
+//
+// The last-modified time is UTC at second precision and the ETag is reduced
+// to at most 16 alphanumeric characters, so versions of a key list in time
+// order and an unchanged source object always maps to the same key.
+func WriteOnceKey(key string, metadata *common.Metadata) string {
+	var b strings.Builder
+	b.WriteString(key)
+	b.WriteString(WriteOnceSeparator)
+	if metadata == nil {
+		return b.String()
+	}
+	b.WriteString(metadata.LastModified.UTC().Format(writeOnceTimeFormat))
+	tag := make([]byte, 0, maxWriteOnceTagLength)
+	for i := 0; i < len(metadata.ETag) && len(tag) < maxWriteOnceTagLength; i++ {
+		c := metadata.ETag[i]
+		if c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' {
+			tag = append(tag, c)
+		}
+	}
+	if len(tag) > 0 {
+		b.WriteByte('-')
+		b.Write(tag)
+	}
+	return b.String()
+}
+
+// syncWriteOnce copies the current version of key to its versioned key on a
+// write-once destination. A version that is already present is left alone;
+// on backends that support conditional writes the copy is created only if
+// the key does not exist, so concurrent syncs never overwrite each other.
+func (s *Syncer) syncWriteOnce(ctx context.Context, key string) (int64, error) {
+	srcMetadata, err := s.source.GetMetadata(ctx, key)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get metadata: %w", err)
+	}
+	// Guard: backends may return nil on best-effort stub implementations.
+	if srcMetadata == nil {
+		srcMetadata = &common.Metadata{}
+	}
+	destKey := WriteOnceKey(key, srcMetadata)
+
+	if exists, err := s.dest.Exists(ctx, destKey); err == nil && exists {
+		return 0, nil
+	}
+
+	reader, err := s.openSource(ctx, key)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read source: %w", err)
+	}
+	defer func() { _ = reader.Close() }()
+
+	if cw, ok := s.dest.(common.ConditionalWriter); ok {
+		err = cw.PutIfMatch(ctx, destKey, reader, srcMetadata, "")
+		if errors.Is(err, common.ErrPreconditionFailed) {
+			return 0, nil
+		}
+	} else {
+		err = s.dest.PutWithMetadata(ctx, destKey, reader, srcMetadata)
+	}
+	if err != nil {
+		_ = s.auditLog.LogObjectMutation(ctx, "replication_failed",
+			"", "", "", key, "", "", 0, "failure", err)
+		return 0, fmt.Errorf("failed to write destination: %w", err)
+	}
+
+	_ = s.auditLog.LogObjectMutation(ctx, "replication_success",
+		"", "", "", key, "", "", srcMetadata.Size, "success", nil)
+
+	s.logger.Debug(ctx, "Object version synced",
+		adapters.Field{Key: fieldKey, Value: key},
+		adapters.Field{Key: "version_key", Value: destKey},
+		adapters.Field{Key: "size", Value: srcMetadata.Size})
+
+	return srcMetadata.Size, nil
+}
+
+// skipDelete records a source delete that a write-once destination does not
+// propagate.
+func (s *Syncer) skipDelete(ctx context.Context, key string) {
+	_ = s.auditLog.LogObjectMutation(ctx, "replication_delete_skipped",
+		"", "", "", key, "", "", 0, "success", nil)
+	s.logger.Debug(ctx, "Delete not propagated to write-once destination",
+		adapters.Field{Key: fieldKey, Value: key})
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package replication
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/audit"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteOnceKey(t *testing.T) {
+	modified := time.Date(2025, 3, 4, 5, 6, 7, 890, time.FixedZone("X", 3600))
+
+	assert.Equal(t, "a/b.txt@20250304T040607Z-abc123",
+		WriteOnceKey("a/b.txt", &common.Metadata{LastModified: modified, ETag: `"abc123"`}))
+	assert.Equal(t, "k@20250304T040607Z-0123456789abcdef",
+		WriteOnceKey("k", &common.Metadata{LastModified: modified, ETag: "0123456789abcdef0123-4"}))
+	assert.Equal(t, "k@20250304T040607Z",
+		WriteOnceKey("k", &common.Metadata{LastModified: modified}))
+	assert.Equal(t, "k@", WriteOnceKey("k", nil))
+}
+
+func newWriteOnceSyncer(source, dest common.Storage) *Syncer {
+	return &Syncer{
+		policy: common.ReplicationPolicy{
+			ID:        "worm",
+			WriteOnce: true,
+		},
+		source:   source,
+		dest:     dest,
+		logger:   adapters.NewNoOpLogger(),
+		auditLog: audit.NewNoOpAuditLogger(),
+		metrics:  NewReplicationMetrics(),
+	}
+}
+
+func destKeys(t *testing.T, dest common.Storage) []string {
+	t.Helper()
+	keys, err := dest.List("")
+	require.NoError(t, err)
+	return keys
+}
+
+func TestSyncWriteOnce_KeepsEveryVersion(t *testing.T) {
+	ctx := context.Background()
+	source := memory.New()
+	dest := memory.New()
+	syncer := newWriteOnceSyncer(source, dest)
+
+	require.NoError(t, source.PutWithContext(ctx, "doc.txt", bytes.NewReader([]byte("v1"))))
+	result, err := syncer.SyncAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Synced)
+
+	// An unchanged source is not copied again.
+	result, err = syncer.SyncAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Synced)
+
+	require.NoError(t, source.PutWithContext(ctx, "doc.txt", bytes.NewReader([]byte("encrypted by ransomware"))))
+	result, err = syncer.SyncAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Synced)
+
+	keys := destKeys(t, dest)
+	require.Len(t, keys, 2)
+	for _, key := range keys {
+		assert.True(t, strings.HasPrefix(key, "doc.txt"+WriteOnceSeparator), key)
+	}
+	exists, err := dest.Exists(ctx, "doc.txt")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	var contents []string
+	for _, key := range keys {
+		r, err := dest.GetWithContext(ctx, key)
+		require.NoError(t, err)
+		var buf bytes.Buffer
+		_, err = buf.ReadFrom(r)
+		require.NoError(t, err)
+		_ = r.Close()
+		contents = append(contents, buf.String())
+	}
+	assert.ElementsMatch(t, []string{"v1", "encrypted by ransomware"}, contents)
+}
+
+func TestSyncWriteOnce_IgnoresDeletes(t *testing.T) {
+	ctx := context.Background()
+	source := memory.New()
+	dest := memory.New()
+	syncer := newWriteOnceSyncer(source, dest)
+
+	require.NoError(t, source.PutWithContext(ctx, "doc.txt", bytes.NewReader([]byte("v1"))))
+	changeLog := newMockChangeLog()
+	require.NoError(t, changeLog.RecordChange(ChangeEvent{Key: "doc.txt", Operation: operationPut, Timestamp: time.Now()}))
+	result, err := syncer.SyncIncremental(ctx, changeLog)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Synced)
+
+	require.NoError(t, source.DeleteWithContext(ctx, "doc.txt"))
+	changeLog = newMockChangeLog()
+	require.NoError(t, changeLog.RecordChange(ChangeEvent{Key: "doc.txt", Operation: operationDelete, Timestamp: time.Now()}))
+	result, err = syncer.SyncIncremental(ctx, changeLog)
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Deleted)
+	assert.Equal(t, 0, result.Failed)
+	assert.Len(t, destKeys(t, dest), 1)
+
+	unprocessed, err := changeLog.GetUnprocessed("worm")
+	require.NoError(t, err)
+	assert.Empty(t, unprocessed)
+}
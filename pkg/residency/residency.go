@@ -0,0 +1,213 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package residency pins key prefixes to the backends and regions their
+// data may be stored in, as required for datasets bound by data
+// protection rules such as GDPR. Writes to other backends and replication
+// policies that would copy the data elsewhere are rejected, and each
+// violation is recorded in the audit log.
+package residency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/audit"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+var (
+	// ErrViolation is returned when an operation would store data outside
+	// the locations allowed for it. Errors wrapping it also wrap
+	// common.ErrPermissionDenied.
+	ErrViolation = errors.New("data residency violation")
+
+	// ErrInvalidConfig is returned when a residency configuration cannot be
+	// loaded or is malformed.
+	ErrInvalidConfig = errors.New("invalid residency configuration")
+)
+
+// Rule restricts where objects under Prefix may be stored. A location is
+// allowed when it is in both lists; an empty list allows any.
+type Rule struct {
+	// Prefix selects the keys the rule applies to. An empty prefix matches
+	// every key.
+	Prefix string `yaml:"prefix" json:"prefix"`
+
+	// Regions lists the regions the data may be stored in, for example
+	// "eu-west-1" or "europe-west3". Backends with an unknown region are
+	// not allowed when Regions is set.
+	Regions []string `yaml:"regions,omitempty" json:"regions,omitempty"`
+
+	// Backends lists the facade backend names writes may go to, and the
+	// backend types replication policies may copy to.
+	Backends []string `yaml:"backends,omitempty" json:"backends,omitempty"`
+}
+
+// Config configures residency enforcement.
+type Config struct {
+	// Rules are matched by longest prefix; only the most specific rule
+	// applies to a key.
+	Rules []Rule `yaml:"rules" json:"rules"`
+
+	// Regions maps facade backend names to the region they store data in.
+	Regions map[string]string `yaml:"regions" json:"regions"`
+
+	// AuditLog records violations. Nil uses the audit logger of the
+	// request context.
+	AuditLog audit.AuditLogger `yaml:"-" json:"-"`
+}
+
+// LoadFile reads a residency configuration from a YAML or JSON file.
+func LoadFile(filename string) (*Config, error) {
+	data, err := os.ReadFile(filename) // #nosec G304 -- path supplied by the operator
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidConfig, filename, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// Validate checks that no prefix is configured twice and that every rule
+// restricts something.
+func (c *Config) Validate() error {
+	seen := make(map[string]bool, len(c.Rules))
+	for _, rule := range c.Rules {
+		if seen[rule.Prefix] {
+			return fmt.Errorf("%w: duplicate rule for prefix %q", ErrInvalidConfig, rule.Prefix)
+		}
+		seen[rule.Prefix] = true
+		if len(rule.Regions) == 0 && len(rule.Backends) == 0 {
+			return fmt.Errorf("%w: rule for prefix %q allows no regions or backends", ErrInvalidConfig, rule.Prefix)
+		}
+	}
+	return nil
+}
+
+// rule returns the most specific rule matching key, or nil when none does.
+func (c *Config) rule(key string) *Rule {
+	var match *Rule
+	for i := range c.Rules {
+		rule := &c.Rules[i]
+		if !strings.HasPrefix(key, rule.Prefix) {
+			continue
+		}
+		if match == nil || len(rule.Prefix) > len(match.Prefix) {
+			match = rule
+		}
+	}
+	return match
+}
+
+// allows reports whether rule permits storing data in backend and region.
+func (r *Rule) allows(backend, region string) bool {
+	if len(r.Backends) > 0 && !slices.Contains(r.Backends, backend) {
+		return false
+	}
+	if len(r.Regions) > 0 && !slices.ContainsFunc(r.Regions, func(allowed string) bool {
+		return strings.EqualFold(allowed, region)
+	}) {
+		return false
+	}
+	return true
+}
+
+// CheckWrite reports whether key may be written to the facade backend
+// named backend.
+func (c *Config) CheckWrite(ctx context.Context, backend, key string) error {
+	rule := c.rule(key)
+	if rule == nil || rule.allows(backend, c.Regions[backend]) {
+		return nil
+	}
+	err := c.violation(rule, "backend %q (region %q)", backend, c.Regions[backend])
+	c.audit(ctx, "write", backend, key, rule, err)
+	return err
+}
+
+// CheckReplication reports whether policy may copy data from its source
+// prefix to its destination. Every rule covering keys under the source
+// prefix must allow the destination backend type and its region, read
+// from the "region" or "location" destination setting.
+func (c *Config) CheckReplication(ctx context.Context, policy *common.ReplicationPolicy) error {
+	region := policy.DestinationSettings["region"]
+	if region == "" {
+		region = policy.DestinationSettings["location"]
+	}
+	for i := range c.Rules {
+		rule := &c.Rules[i]
+		// A rule covers the policy when either prefix contains the other.
+		if !strings.HasPrefix(rule.Prefix, policy.SourcePrefix) && !strings.HasPrefix(policy.SourcePrefix, rule.Prefix) {
+			continue
+		}
+		if strings.HasPrefix(policy.SourcePrefix, rule.Prefix) && c.rule(policy.SourcePrefix) != rule {
+			// A more specific rule governs the whole source prefix.
+			continue
+		}
+		if rule.allows(policy.DestinationBackend, region) {
+			continue
+		}
+		err := c.violation(rule, "replication policy %q destination %q (region %q)", policy.ID, policy.DestinationBackend, region)
+		c.audit(ctx, "replicate", policy.DestinationBackend, policy.SourcePrefix, rule, err)
+		return err
+	}
+	return nil
+}
+
+func (c *Config) violation(rule *Rule, format string, args ...any) error {
+	return fmt.Errorf("%w: %w: data under %q is pinned to regions %v and backends %v, not "+format,
+		append([]any{common.ErrPermissionDenied, ErrViolation, rule.Prefix, rule.Regions, rule.Backends}, args...)...)
+}
+
+// audit records a violation.
+func (c *Config) audit(ctx context.Context, action, backend, key string, rule *Rule, err error) {
+	logger := c.AuditLog
+	if logger == nil {
+		logger = audit.GetAuditLogger(ctx)
+	}
+	userID, name, onBehalfOf := "", "", ""
+	if p, ok := ctx.Value(adapters.PrincipalContextKey{}).(*adapters.Principal); ok && p != nil {
+		userID, name, onBehalfOf = p.ID, p.Name, p.OnBehalfOf
+	}
+	_ = logger.LogEvent(ctx, &audit.AuditEvent{ // #nosec G104 -- Audit logging errors are logged internally, should not block operations
+		Timestamp:    time.Now(),
+		EventType:    audit.EventResidencyViolation,
+		UserID:       userID,
+		Principal:    name,
+		OnBehalfOf:   onBehalfOf,
+		Resource:     key,
+		Key:          key,
+		Action:       action,
+		Result:       audit.ResultFailure,
+		ErrorMessage: err.Error(),
+		RequestID:    audit.GetRequestID(ctx),
+		Metadata: map[string]any{
+			"backend":         backend,
+			"rule_prefix":     rule.Prefix,
+			"allowed_regions": rule.Regions,
+		},
+	})
+}
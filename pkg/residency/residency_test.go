@@ -0,0 +1,197 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package residency
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/audit"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+// recordingLogger records the events logged to it.
+type recordingLogger struct {
+	audit.AuditLogger
+	events []*audit.AuditEvent
+}
+
+func (r *recordingLogger) LogEvent(_ context.Context, event *audit.AuditEvent) error {
+	r.events = append(r.events, event)
+	return nil
+}
+
+// policyStore is a replication manager keeping policies in memory.
+type policyStore struct {
+	common.ReplicationManager
+	policies []common.ReplicationPolicy
+}
+
+func (p *policyStore) AddPolicy(policy common.ReplicationPolicy) error {
+	p.policies = append(p.policies, policy)
+	return nil
+}
+
+// replicable is a memory backend with a replication manager.
+type replicable struct {
+	common.Storage
+	manager *policyStore
+}
+
+func (r *replicable) GetReplicationManager() (common.ReplicationManager, error) {
+	return r.manager, nil
+}
+
+func testConfig(log *recordingLogger) *Config {
+	return &Config{
+		Rules: []Rule{
+			{Prefix: "eu/", Regions: []string{"eu-west-1", "eu-central-1"}},
+			{Prefix: "eu/archive/", Backends: []string{"archive"}},
+		},
+		Regions: map[string]string{
+			"primary": "eu-west-1",
+			"us":      "us-east-1",
+			"archive": "eu-central-1",
+		},
+		AuditLog: log,
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "residency.yaml")
+	data := "rules:\n  - prefix: eu/\n    regions: [eu-west-1]\nregions:\n  default: eu-west-1\n"
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if len(cfg.Rules) != 1 || cfg.Regions["default"] != "eu-west-1" {
+		t.Errorf("LoadFile() = %+v", cfg)
+	}
+
+	for _, bad := range []*Config{
+		{Rules: []Rule{{Prefix: "a/", Regions: []string{"x"}}, {Prefix: "a/", Regions: []string{"y"}}}},
+		{Rules: []Rule{{Prefix: "a/"}}},
+	} {
+		if err := bad.Validate(); !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("Validate(%+v) error = %v, want ErrInvalidConfig", bad.Rules, err)
+		}
+	}
+}
+
+func TestCheckWrite(t *testing.T) {
+	log := &recordingLogger{}
+	cfg := testConfig(log)
+	tests := []struct {
+		backend, key string
+		wantErr      bool
+	}{
+		{"us", "public/readme", false},
+		{"primary", "eu/customers.csv", false},
+		{"us", "eu/customers.csv", true},
+		{"unknown", "eu/customers.csv", true},
+		{"archive", "eu/archive/2024.tar", false},
+		{"primary", "eu/archive/2024.tar", true},
+	}
+	for _, tt := range tests {
+		err := cfg.CheckWrite(context.Background(), tt.backend, tt.key)
+		if tt.wantErr != (err != nil) {
+			t.Errorf("CheckWrite(%s, %s) error = %v, wantErr %v", tt.backend, tt.key, err, tt.wantErr)
+		}
+		if err != nil && (!errors.Is(err, ErrViolation) || !errors.Is(err, common.ErrPermissionDenied)) {
+			t.Errorf("CheckWrite(%s, %s) error = %v, want ErrViolation", tt.backend, tt.key, err)
+		}
+	}
+	if len(log.events) != 3 {
+		t.Fatalf("audited %d violations, want 3", len(log.events))
+	}
+	if e := log.events[0]; e.EventType != audit.EventResidencyViolation || e.Result != audit.ResultFailure || e.Key != "eu/customers.csv" {
+		t.Errorf("audit event = %+v", e)
+	}
+}
+
+func TestCheckReplication(t *testing.T) {
+	cfg := testConfig(&recordingLogger{})
+	tests := []struct {
+		name   string
+		policy common.ReplicationPolicy
+		ok     bool
+	}{
+		{"outside rules", common.ReplicationPolicy{SourcePrefix: "public/", DestinationBackend: "s3", DestinationSettings: map[string]string{"region": "us-east-1"}}, true},
+		{"allowed region", common.ReplicationPolicy{SourcePrefix: "eu/data/", DestinationBackend: "s3", DestinationSettings: map[string]string{"region": "eu-central-1"}}, true},
+		{"gcs location", common.ReplicationPolicy{SourcePrefix: "eu/data/", DestinationBackend: "gcs", DestinationSettings: map[string]string{"location": "EU-WEST-1"}}, true},
+		{"disallowed region", common.ReplicationPolicy{SourcePrefix: "eu/", DestinationBackend: "s3", DestinationSettings: map[string]string{"region": "us-east-1"}}, false},
+		{"unknown region", common.ReplicationPolicy{SourcePrefix: "eu/", DestinationBackend: "local"}, false},
+		// The whole bucket includes eu/ keys.
+		{"covers pinned prefix", common.ReplicationPolicy{DestinationBackend: "s3", DestinationSettings: map[string]string{"region": "us-east-1"}}, false},
+		// eu/archive/ may only go to the archive backend.
+		{"nested rule", common.ReplicationPolicy{SourcePrefix: "eu/", DestinationBackend: "s3", DestinationSettings: map[string]string{"region": "eu-west-1"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := cfg.CheckReplication(context.Background(), &tt.policy)
+			if tt.ok != (err == nil) {
+				t.Errorf("CheckReplication() error = %v, want ok %v", err, tt.ok)
+			}
+		})
+	}
+}
+
+func TestStorage(t *testing.T) {
+	log := &recordingLogger{}
+	cfg := testConfig(log)
+	manager := &policyStore{}
+	s := NewStorage(&replicable{Storage: memory.New(), manager: manager}, "us", cfg)
+
+	ctx := context.WithValue(context.Background(), adapters.PrincipalContextKey{}, &adapters.Principal{ID: "u1", Name: "alice"})
+	if err := s.PutWithContext(ctx, "eu/customers.csv", strings.NewReader("x")); !errors.Is(err, ErrViolation) {
+		t.Errorf("PutWithContext() error = %v, want ErrViolation", err)
+	}
+	if err := s.Append(ctx, "eu/log", strings.NewReader("x")); !errors.Is(err, ErrViolation) {
+		t.Errorf("Append() error = %v, want ErrViolation", err)
+	}
+	if err := s.Put("public/readme", strings.NewReader("x")); err != nil {
+		t.Errorf("Put() outside rules error = %v", err)
+	}
+	if len(log.events) != 2 || log.events[0].Principal != "alice" {
+		t.Errorf("audit events = %+v", log.events)
+	}
+
+	rm, err := s.GetReplicationManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = rm.AddPolicy(common.ReplicationPolicy{ID: "dr", SourcePrefix: "eu/", DestinationBackend: "s3", DestinationSettings: map[string]string{"region": "us-west-2"}})
+	if !errors.Is(err, ErrViolation) {
+		t.Errorf("AddPolicy() error = %v, want ErrViolation", err)
+	}
+	if err := rm.AddPolicy(common.ReplicationPolicy{ID: "ok", SourcePrefix: "public/", DestinationBackend: "s3"}); err != nil {
+		t.Errorf("AddPolicy() error = %v", err)
+	}
+	if len(manager.policies) != 1 || manager.policies[0].ID != "ok" {
+		t.Errorf("policies = %+v", manager.policies)
+	}
+
+	if _, err := NewStorage(memory.New(), "us", cfg).GetReplicationManager(); !errors.Is(err, common.ErrReplicationNotSupported) {
+		t.Errorf("GetReplicationManager() error = %v, want ErrReplicationNotSupported", err)
+	}
+}
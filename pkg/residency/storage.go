@@ -0,0 +1,164 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package residency
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/replication"
+)
+
+// Storage wraps the facade backend named name and rejects writes of keys
+// pinned elsewhere, as well as replication policies added through its
+// replication manager that would copy data out of its allowed locations.
+// Reads, listings and deletes are passed through.
+type Storage struct {
+	common.Storage
+	name string
+	cfg  *Config
+}
+
+// NewStorage returns underlying, the facade backend named name, wrapped to
+// enforce cfg.
+func NewStorage(underlying common.Storage, name string, cfg *Config) *Storage {
+	return &Storage{Storage: underlying, name: name, cfg: cfg}
+}
+
+// Config returns the configuration enforced by s.
+func (s *Storage) Config() *Config {
+	return s.cfg
+}
+
+// Underlying returns the wrapped backend.
+func (s *Storage) Underlying() common.Storage {
+	return s.Storage
+}
+
+// GetRange reads a byte range from the wrapped backend, falling back to
+// discarding the leading bytes of a full read when it cannot read ranges.
+func (s *Storage) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	if rr, ok := s.Storage.(common.RangeReader); ok {
+		return rr.GetRange(ctx, key, offset, length)
+	}
+	rc, err := s.Storage.GetWithContext(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return common.SliceRange(rc, offset, length)
+}
+
+// GetAsOf reads an earlier version of an object from the wrapped backend.
+func (s *Storage) GetAsOf(ctx context.Context, key string, at time.Time) (io.ReadCloser, *common.Metadata, error) {
+	return common.GetAsOf(ctx, s.Storage, key, at)
+}
+
+// Put stores an object if its key may be stored in this backend.
+func (s *Storage) Put(key string, data io.Reader) error {
+	return s.PutWithContext(context.Background(), key, data)
+}
+
+// PutWithContext stores an object if its key may be stored in this
+// backend.
+func (s *Storage) PutWithContext(ctx context.Context, key string, data io.Reader) error {
+	if err := s.cfg.CheckWrite(ctx, s.name, key); err != nil {
+		return err
+	}
+	return s.Storage.PutWithContext(ctx, key, data)
+}
+
+// PutWithMetadata stores an object with metadata if its key may be stored
+// in this backend.
+func (s *Storage) PutWithMetadata(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) error {
+	if err := s.cfg.CheckWrite(ctx, s.name, key); err != nil {
+		return err
+	}
+	return s.Storage.PutWithMetadata(ctx, key, data, metadata)
+}
+
+// PutWithInfo stores an object with metadata if its key may be stored in
+// this backend, and returns what was stored.
+func (s *Storage) PutWithInfo(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) (*common.ObjectInfo, error) {
+	if err := s.cfg.CheckWrite(ctx, s.name, key); err != nil {
+		return nil, err
+	}
+	return common.PutWithInfo(ctx, s.Storage, key, data, metadata)
+}
+
+// Append adds data to the end of an object if its key may be stored in
+// this backend.
+func (s *Storage) Append(ctx context.Context, key string, data io.Reader) error {
+	if err := s.cfg.CheckWrite(ctx, s.name, key); err != nil {
+		return err
+	}
+	return common.Append(ctx, s.Storage, key, data)
+}
+
+// Compose concatenates srcKeys into destKey if destKey may be stored in
+// this backend.
+func (s *Storage) Compose(ctx context.Context, destKey string, srcKeys ...string) error {
+	if err := s.cfg.CheckWrite(ctx, s.name, destKey); err != nil {
+		return err
+	}
+	return common.Compose(ctx, s.Storage, destKey, srcKeys...)
+}
+
+// GetReplicationManager returns the replication manager of the wrapped
+// backend, checking the policies added through it.
+func (s *Storage) GetReplicationManager() (common.ReplicationManager, error) {
+	storage := s.Storage
+	for {
+		if replicable, ok := storage.(common.ReplicationCapable); ok {
+			manager, err := replicable.GetReplicationManager()
+			if err != nil {
+				return nil, err
+			}
+			return &replicationManager{ReplicationManager: manager, cfg: s.cfg}, nil
+		}
+		wrapper, ok := storage.(interface{ Underlying() common.Storage })
+		if !ok {
+			return nil, common.ErrReplicationNotSupported
+		}
+		storage = wrapper.Underlying()
+	}
+}
+
+// replicationManager rejects replication policies violating residency.
+type replicationManager struct {
+	common.ReplicationManager
+	cfg *Config
+}
+
+// AddPolicy adds policy if its destination is allowed for the data it
+// copies.
+func (m *replicationManager) AddPolicy(policy common.ReplicationPolicy) error {
+	if err := m.cfg.CheckReplication(context.Background(), &policy); err != nil {
+		return err
+	}
+	return m.ReplicationManager.AddPolicy(policy)
+}
+
+// GetReplicationStatus returns the status of a policy, if the wrapped
+// manager tracks it.
+func (m *replicationManager) GetReplicationStatus(id string) (*replication.ReplicationStatus, error) {
+	provider, ok := m.ReplicationManager.(interface {
+		GetReplicationStatus(id string) (*replication.ReplicationStatus, error)
+	})
+	if !ok {
+		return nil, common.ErrReplicationNotSupported
+	}
+	return provider.GetReplicationStatus(id)
+}
@@ -0,0 +1,594 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package retention protects regulated objects from deletion. A legal hold
+// blocks every delete and overwrite of an object until it is released.
+// Deletes under protected prefixes follow a two-person rule: they create a
+// pending DeletionRequest that a different principal must approve before
+// the object is removed.
+//
+// Holds and requests can be persisted to a JSON file so they survive
+// restarts, on the local disk or on a shared FileSystem that several
+// instances use. Every request, decision and hold change is audit logged.
+package retention
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/audit"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/replication"
+)
+
+// maxReasonLength bounds the free-text reason recorded with holds and
+// rejections.
+const maxReasonLength = 1024
+
+var (
+	// ErrDeletionPending is returned by Delete when the deletion was queued
+	// for approval instead of executed. The returned error is a
+	// *PendingError, which also wraps common.ErrPreconditionFailed.
+	ErrDeletionPending = errors.New("deletion pending approval")
+
+	// ErrLegalHold is returned when deleting or overwriting an object under
+	// legal hold. Errors wrapping it also wrap common.ErrPermissionDenied.
+	ErrLegalHold = errors.New("object is under legal hold")
+
+	// ErrRequestNotFound is returned for an unknown deletion request ID.
+	ErrRequestNotFound = fmt.Errorf("deletion request %w", common.ErrNotFound)
+
+	// ErrRequestDecided is returned when approving or rejecting a request
+	// that is no longer pending.
+	ErrRequestDecided = fmt.Errorf("%w: deletion request already decided", common.ErrPreconditionFailed)
+
+	// ErrSelfApproval is returned when a principal approves its own deletion
+	// request, or when the approver cannot be identified.
+	ErrSelfApproval = fmt.Errorf("%w: deletion must be approved by a different principal", common.ErrPermissionDenied)
+
+	// ErrHoldNotFound is returned when releasing a hold that does not exist.
+	ErrHoldNotFound = fmt.Errorf("legal hold %w", common.ErrNotFound)
+
+	// ErrStateCorrupt is returned when a persisted state file cannot be
+	// decoded.
+	ErrStateCorrupt = errors.New("retention state is corrupt")
+)
+
+// Status is the state of a DeletionRequest.
+type Status string
+
+const (
+	// StatusPending requests await approval.
+	StatusPending Status = "pending"
+
+	// StatusApproved requests were approved and the object deleted.
+	StatusApproved Status = "approved"
+
+	// StatusRejected requests were rejected; the object was kept.
+	StatusRejected Status = "rejected"
+)
+
+// DeletionRequest records a delete of a protected object awaiting, or
+// having received, a decision.
+type DeletionRequest struct {
+	ID          string     `json:"id"`
+	Key         string     `json:"key"`
+	Status      Status     `json:"status"`
+	RequestedBy string     `json:"requested_by,omitempty"`
+	RequestedAt time.Time  `json:"requested_at"`
+	DecidedBy   string     `json:"decided_by,omitempty"`
+	DecidedAt   *time.Time `json:"decided_at,omitempty"`
+	Reason      string     `json:"reason,omitempty"`
+}
+
+// Hold is a legal hold on one object.
+type Hold struct {
+	Key      string    `json:"key"`
+	PlacedBy string    `json:"placed_by,omitempty"`
+	PlacedAt time.Time `json:"placed_at"`
+	Reason   string    `json:"reason,omitempty"`
+}
+
+// PendingError is returned by Delete when a deletion was queued for approval.
+type PendingError struct {
+	Request DeletionRequest
+}
+
+// Error implements error.
+func (e *PendingError) Error() string {
+	return fmt.Sprintf("%s: %s (request %s)", ErrDeletionPending, e.Request.Key, e.Request.ID)
+}
+
+// Unwrap lets errors.Is match ErrDeletionPending and, for transports that
+// classify errors, common.ErrPreconditionFailed.
+func (e *PendingError) Unwrap() []error {
+	return []error{ErrDeletionPending, common.ErrPreconditionFailed}
+}
+
+// Config configures a Manager.
+type Config struct {
+	// ProtectedPrefixes lists the key prefixes whose deletes need approval.
+	// An empty string protects every key.
+	ProtectedPrefixes []string
+
+	// StatePath is the file holds and requests are persisted to. If empty,
+	// they are kept in memory only.
+	StatePath string
+
+	// FileSystem stores StatePath. If nil, the OS file system is used.
+	FileSystem replication.FileSystem
+
+	// Shared reloads the state before every read and change, for a
+	// FileSystem that other instances write too (see ha.Cluster).
+	Shared bool
+}
+
+// state is the persisted form of a Manager.
+type state struct {
+	Requests []*DeletionRequest `json:"requests"`
+	Holds    []*Hold            `json:"holds"`
+}
+
+// Manager tracks legal holds and deletion requests for one backend. It is
+// safe for concurrent use.
+type Manager struct {
+	mu       sync.Mutex
+	storage  common.Storage
+	path     string
+	fs       replication.FileSystem
+	shared   bool
+	prefixes []string
+	requests map[string]*DeletionRequest
+	holds    map[string]*Hold
+}
+
+// NewManager creates a Manager for storage, the backend whose objects it
+// protects. State is loaded from config.StatePath when the file exists.
+func NewManager(storage common.Storage, config Config) (*Manager, error) {
+	m := &Manager{
+		storage:  storage,
+		path:     config.StatePath,
+		fs:       config.FileSystem,
+		shared:   config.Shared,
+		prefixes: append([]string(nil), config.ProtectedPrefixes...),
+		requests: make(map[string]*DeletionRequest),
+		holds:    make(map[string]*Hold),
+	}
+	if m.fs == nil {
+		m.fs = &replication.OSFileSystem{}
+	}
+	if err := m.loadLocked(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// loadLocked replaces the state with the persisted one, if any.
+func (m *Manager) loadLocked() error {
+	if m.path == "" {
+		return nil
+	}
+	file, err := m.fs.OpenFile(m.path, os.O_RDONLY, 0)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read retention state: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return fmt.Errorf("failed to read retention state: %w", err)
+	}
+
+	var st state
+	if err := json.Unmarshal(data, &st); err != nil {
+		return fmt.Errorf("%w: %v", ErrStateCorrupt, err)
+	}
+	m.requests = make(map[string]*DeletionRequest, len(st.Requests))
+	for _, req := range st.Requests {
+		m.requests[req.ID] = req
+	}
+	m.holds = make(map[string]*Hold, len(st.Holds))
+	for _, hold := range st.Holds {
+		m.holds[hold.Key] = hold
+	}
+	return nil
+}
+
+// refreshLocked reloads shared state. Reads keep the state last loaded when
+// it cannot be reloaded; changes fail rather than overwrite what they did
+// not see.
+func (m *Manager) refreshLocked() error {
+	if !m.shared {
+		return nil
+	}
+	return m.loadLocked()
+}
+
+// ProtectedPrefixes returns the prefixes whose deletes need approval.
+func (m *Manager) ProtectedPrefixes() []string {
+	return append([]string(nil), m.prefixes...)
+}
+
+// Protected reports whether deleting key needs approval.
+func (m *Manager) Protected(key string) bool {
+	for _, prefix := range m.prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Held reports whether key is under legal hold.
+func (m *Manager) Held(key string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_ = m.refreshLocked() // #nosec G104 -- reads use the state last loaded
+	_, ok := m.holds[key]
+	return ok
+}
+
+// RequestDeletion queues a deletion of key for approval. A key has at most
+// one pending request; asking again returns the existing one.
+func (m *Manager) RequestDeletion(ctx context.Context, key string) (*DeletionRequest, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.refreshLocked(); err != nil {
+		return nil, err
+	}
+
+	if _, held := m.holds[key]; held {
+		err := holdError(key)
+		m.audit(ctx, audit.EventDeletionRequested, "request", key, "", err)
+		return nil, err
+	}
+	for _, req := range m.requests {
+		if req.Key == key && req.Status == StatusPending {
+			copied := *req
+			return &copied, nil
+		}
+	}
+
+	req := &DeletionRequest{
+		ID:          uuid.NewString(),
+		Key:         key,
+		Status:      StatusPending,
+		RequestedBy: adapters.Identity(ctx),
+		RequestedAt: time.Now().UTC(),
+	}
+	m.requests[req.ID] = req
+	if err := m.saveLocked(); err != nil {
+		delete(m.requests, req.ID)
+		m.audit(ctx, audit.EventDeletionRequested, "request", key, req.ID, err)
+		return nil, err
+	}
+	m.audit(ctx, audit.EventDeletionRequested, "request", key, req.ID, nil)
+	copied := *req
+	return &copied, nil
+}
+
+// Requests returns the deletion requests with status, or all requests when
+// status is empty, oldest first.
+func (m *Manager) Requests(status Status) []DeletionRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_ = m.refreshLocked() // #nosec G104 -- reads use the state last loaded
+
+	out := make([]DeletionRequest, 0, len(m.requests))
+	for _, req := range m.requests {
+		if status == "" || req.Status == status {
+			out = append(out, *req)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].RequestedAt.Equal(out[j].RequestedAt) {
+			return out[i].RequestedAt.Before(out[j].RequestedAt)
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
+
+// Request returns the deletion request with id.
+func (m *Manager) Request(id string) (*DeletionRequest, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_ = m.refreshLocked() // #nosec G104 -- reads use the state last loaded
+
+	req, ok := m.requests[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrRequestNotFound, id)
+	}
+	copied := *req
+	return &copied, nil
+}
+
+// Approve approves a pending request and deletes its object through
+// storage, which is normally the backend as registered with the facade so
+// wrappers such as the search index observe the delete. The approver, taken
+// from the principal in ctx, must differ from the requester. A request
+// whose object is under legal hold, or whose delete fails, stays pending.
+func (m *Manager) Approve(ctx context.Context, id string, storage common.Storage) (*DeletionRequest, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.refreshLocked(); err != nil {
+		return nil, err
+	}
+
+	req, ok := m.requests[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrRequestNotFound, id)
+	}
+	approver := adapters.Identity(ctx)
+	var err error
+	switch {
+	case req.Status != StatusPending:
+		err = fmt.Errorf("%w: %s is %s", ErrRequestDecided, id, req.Status)
+	case approver == "" || approver == req.RequestedBy:
+		err = ErrSelfApproval
+	case m.holds[req.Key] != nil:
+		err = holdError(req.Key)
+	default:
+		err = storage.DeleteWithContext(withApproval(ctx, req.Key), req.Key)
+	}
+	if err != nil {
+		m.audit(ctx, audit.EventDeletionApproved, "approve", req.Key, id, err)
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	req.Status = StatusApproved
+	req.DecidedBy = approver
+	req.DecidedAt = &now
+	err = m.saveLocked()
+	m.audit(ctx, audit.EventDeletionApproved, "approve", req.Key, id, err)
+	if err != nil {
+		return nil, fmt.Errorf("object deleted but approval not recorded: %w", err)
+	}
+	copied := *req
+	return &copied, nil
+}
+
+// Reject rejects a pending request, keeping the object. Any principal
+// permitted to manage retention may reject, including the requester.
+func (m *Manager) Reject(ctx context.Context, id, reason string) (*DeletionRequest, error) {
+	if err := validateReason(reason); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.refreshLocked(); err != nil {
+		return nil, err
+	}
+
+	req, ok := m.requests[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrRequestNotFound, id)
+	}
+	if req.Status != StatusPending {
+		err := fmt.Errorf("%w: %s is %s", ErrRequestDecided, id, req.Status)
+		m.audit(ctx, audit.EventDeletionRejected, "reject", req.Key, id, err)
+		return nil, err
+	}
+
+	previous := *req
+	now := time.Now().UTC()
+	req.Status = StatusRejected
+	req.DecidedBy = adapters.Identity(ctx)
+	req.DecidedAt = &now
+	req.Reason = reason
+	if err := m.saveLocked(); err != nil {
+		*req = previous
+		m.audit(ctx, audit.EventDeletionRejected, "reject", req.Key, id, err)
+		return nil, err
+	}
+	m.audit(ctx, audit.EventDeletionRejected, "reject", req.Key, id, nil)
+	copied := *req
+	return &copied, nil
+}
+
+// PlaceHold places a legal hold on key, which must exist. Placing a hold on
+// a held object returns the existing hold.
+func (m *Manager) PlaceHold(ctx context.Context, key, reason string) (*Hold, error) {
+	if err := common.ValidateKey(key); err != nil {
+		return nil, err
+	}
+	if err := validateReason(reason); err != nil {
+		return nil, err
+	}
+	exists, err := m.storage.Exists(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", common.ErrKeyNotFound, key)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.refreshLocked(); err != nil {
+		return nil, err
+	}
+
+	if hold, ok := m.holds[key]; ok {
+		copied := *hold
+		return &copied, nil
+	}
+	hold := &Hold{
+		Key:      key,
+		PlacedBy: adapters.Identity(ctx),
+		PlacedAt: time.Now().UTC(),
+		Reason:   reason,
+	}
+	m.holds[key] = hold
+	if err := m.saveLocked(); err != nil {
+		delete(m.holds, key)
+		m.audit(ctx, audit.EventLegalHoldPlaced, "hold", key, "", err)
+		return nil, err
+	}
+	m.audit(ctx, audit.EventLegalHoldPlaced, "hold", key, "", nil)
+	copied := *hold
+	return &copied, nil
+}
+
+// ReleaseHold releases the legal hold on key.
+func (m *Manager) ReleaseHold(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.refreshLocked(); err != nil {
+		return err
+	}
+
+	hold, ok := m.holds[key]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrHoldNotFound, key)
+	}
+	delete(m.holds, key)
+	if err := m.saveLocked(); err != nil {
+		m.holds[key] = hold
+		m.audit(ctx, audit.EventLegalHoldReleased, "release", key, "", err)
+		return err
+	}
+	m.audit(ctx, audit.EventLegalHoldReleased, "release", key, "", nil)
+	return nil
+}
+
+// Holds returns all legal holds ordered by key.
+func (m *Manager) Holds() []Hold {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_ = m.refreshLocked() // #nosec G104 -- reads use the state last loaded
+
+	out := make([]Hold, 0, len(m.holds))
+	for _, hold := range m.holds {
+		out = append(out, *hold)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out
+}
+
+// audit records a retention event with the principal in ctx.
+func (m *Manager) audit(ctx context.Context, eventType audit.EventType, action, key, requestID string, err error) {
+	userID, name, onBehalfOf := "", "", ""
+	if p, ok := ctx.Value(adapters.PrincipalContextKey{}).(*adapters.Principal); ok && p != nil {
+		userID, name, onBehalfOf = p.ID, p.Name, p.OnBehalfOf
+	}
+	event := &audit.AuditEvent{
+		Timestamp:  time.Now(),
+		EventType:  eventType,
+		UserID:     userID,
+		Principal:  name,
+		OnBehalfOf: onBehalfOf,
+		Resource:   key,
+		Key:        key,
+		Action:     action,
+		Result:     audit.ResultSuccess,
+		RequestID:  audit.GetRequestID(ctx),
+	}
+	if err != nil {
+		event.Result = audit.ResultFailure
+		event.ErrorMessage = err.Error()
+	}
+	if requestID != "" {
+		event.Metadata = map[string]any{"deletion_request_id": requestID}
+	}
+	_ = audit.GetAuditLogger(ctx).LogEvent(ctx, event) // #nosec G104 -- Audit logging errors are logged internally, should not block operations
+}
+
+// saveLocked writes the state to its file, if any, replacing the previous
+// copy atomically.
+func (m *Manager) saveLocked() error {
+	if m.path == "" {
+		return nil
+	}
+
+	st := state{
+		Requests: make([]*DeletionRequest, 0, len(m.requests)),
+		Holds:    make([]*Hold, 0, len(m.holds)),
+	}
+	for _, req := range m.requests {
+		st.Requests = append(st.Requests, req)
+	}
+	for _, hold := range m.holds {
+		st.Holds = append(st.Holds, hold)
+	}
+	sort.Slice(st.Requests, func(i, j int) bool { return st.Requests[i].ID < st.Requests[j].ID })
+	sort.Slice(st.Holds, func(i, j int) bool { return st.Holds[i].Key < st.Holds[j].Key })
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode retention state: %w", err)
+	}
+
+	tmp := m.path + ".tmp-" + uuid.NewString()
+	file, err := m.fs.OpenFile(tmp, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write retention state: %w", err)
+	}
+	if _, err := file.Write(data); err != nil {
+		_ = file.Close()
+		_ = m.fs.Remove(tmp)
+		return fmt.Errorf("failed to write retention state: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		_ = m.fs.Remove(tmp)
+		return fmt.Errorf("failed to write retention state: %w", err)
+	}
+	if err := m.fs.Rename(tmp, m.path); err != nil {
+		_ = m.fs.Remove(tmp)
+		return fmt.Errorf("failed to write retention state: %w", err)
+	}
+	return nil
+}
+
+func holdError(key string) error {
+	return fmt.Errorf("%w: %w: %s", common.ErrPermissionDenied, ErrLegalHold, key)
+}
+
+func validateReason(reason string) error {
+	if len(reason) > maxReasonLength {
+		return fmt.Errorf("%w: reason exceeds %d bytes", common.ErrInvalidArgument, maxReasonLength)
+	}
+	return nil
+}
+
+// approvalKey is the context key under which Approve marks the key whose
+// deletion it is executing.
+type approvalKey struct{}
+
+func withApproval(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, approvalKey{}, key)
+}
+
+func approved(ctx context.Context, key string) bool {
+	k, ok := ctx.Value(approvalKey{}).(string)
+	return ok && k == key
+}
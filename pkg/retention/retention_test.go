@@ -0,0 +1,387 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package retention
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/audit"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+// eventRecorder captures audit events.
+type eventRecorder struct {
+	audit.AuditLogger
+	mu     sync.Mutex
+	events []*audit.AuditEvent
+}
+
+func (r *eventRecorder) LogEvent(_ context.Context, event *audit.AuditEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *eventRecorder) types() []audit.EventType {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	types := make([]audit.EventType, 0, len(r.events))
+	for _, event := range r.events {
+		types = append(types, event.EventType)
+	}
+	return types
+}
+
+// as returns ctx carrying principal id and the audit recorder.
+func as(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, adapters.PrincipalContextKey{}, &adapters.Principal{ID: id, Name: id})
+}
+
+func newProtected(t *testing.T, statePath string) (*Storage, common.Storage, *eventRecorder, context.Context) {
+	t.Helper()
+	backend := memory.New()
+	manager, err := NewManager(backend, Config{ProtectedPrefixes: []string{"records/"}, StatePath: statePath})
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	recorder := &eventRecorder{AuditLogger: audit.NewNoOpAuditLogger()}
+	ctx := context.WithValue(context.Background(), audit.AuditLoggerKey, recorder)
+	for _, key := range []string{"records/ledger.csv", "scratch/tmp.txt"} {
+		if err := backend.PutWithContext(ctx, key, strings.NewReader("data")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return NewStorage(backend, manager), backend, recorder, ctx
+}
+
+func exists(t *testing.T, backend common.Storage, key string) bool {
+	t.Helper()
+	ok, err := backend.Exists(context.Background(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ok
+}
+
+func TestDeleteProtected(t *testing.T) {
+	s, backend, recorder, ctx := newProtected(t, "")
+	alice := as(ctx, "alice")
+
+	// Unprotected keys are deleted immediately.
+	if err := s.DeleteWithContext(alice, "scratch/tmp.txt"); err != nil {
+		t.Fatalf("DeleteWithContext() error = %v", err)
+	}
+	if exists(t, backend, "scratch/tmp.txt") {
+		t.Error("unprotected object was not deleted")
+	}
+
+	// Protected keys are queued, once.
+	err := s.DeleteWithContext(alice, "records/ledger.csv")
+	var pending *PendingError
+	if !errors.As(err, &pending) || !errors.Is(err, ErrDeletionPending) || !errors.Is(err, common.ErrPreconditionFailed) {
+		t.Fatalf("DeleteWithContext() error = %v, want *PendingError", err)
+	}
+	if pending.Request.Key != "records/ledger.csv" || pending.Request.RequestedBy != "alice" || pending.Request.Status != StatusPending {
+		t.Errorf("pending request = %+v", pending.Request)
+	}
+	if !exists(t, backend, "records/ledger.csv") {
+		t.Fatal("protected object was deleted before approval")
+	}
+	var again *PendingError
+	if err := s.Delete("records/ledger.csv"); !errors.As(err, &again) || again.Request.ID != pending.Request.ID {
+		t.Errorf("second Delete() error = %v, want the same request", err)
+	}
+	if got := s.Manager().Requests(StatusPending); len(got) != 1 {
+		t.Errorf("Requests(pending) = %d, want 1", len(got))
+	}
+
+	// Missing protected keys are not queued.
+	if err := s.DeleteWithContext(alice, "records/missing.csv"); errors.Is(err, ErrDeletionPending) {
+		t.Errorf("DeleteWithContext() of a missing key queued a request")
+	}
+
+	// The requester cannot approve; anonymous callers cannot approve.
+	id := pending.Request.ID
+	for _, approver := range []context.Context{alice, ctx} {
+		if _, err := s.Manager().Approve(approver, id, s); !errors.Is(err, ErrSelfApproval) || !errors.Is(err, common.ErrPermissionDenied) {
+			t.Errorf("Approve() error = %v, want ErrSelfApproval", err)
+		}
+	}
+
+	req, err := s.Manager().Approve(as(ctx, "bob"), id, s)
+	if err != nil {
+		t.Fatalf("Approve() error = %v", err)
+	}
+	if req.Status != StatusApproved || req.DecidedBy != "bob" || req.DecidedAt == nil {
+		t.Errorf("approved request = %+v", req)
+	}
+	if exists(t, backend, "records/ledger.csv") {
+		t.Error("approved deletion did not delete the object")
+	}
+	if _, err := s.Manager().Approve(as(ctx, "bob"), id, s); !errors.Is(err, ErrRequestDecided) {
+		t.Errorf("second Approve() error = %v, want ErrRequestDecided", err)
+	}
+	if _, err := s.Manager().Approve(as(ctx, "bob"), "missing", s); !errors.Is(err, ErrRequestNotFound) || !errors.Is(err, common.ErrNotFound) {
+		t.Errorf("Approve(missing) error = %v, want ErrRequestNotFound", err)
+	}
+
+	want := []audit.EventType{
+		audit.EventDeletionRequested,
+		audit.EventDeletionApproved, audit.EventDeletionApproved, // self and anonymous approvals refused
+		audit.EventDeletionApproved,
+		audit.EventDeletionApproved, // decided request refused
+	}
+	got := recorder.types()
+	if len(got) != len(want) {
+		t.Fatalf("audit events = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("audit event %d = %s, want %s", i, got[i], want[i])
+		}
+	}
+	if recorder.events[3].Result != audit.ResultSuccess || recorder.events[3].UserID != "bob" ||
+		recorder.events[3].Metadata["deletion_request_id"] != id {
+		t.Errorf("approval audit event = %+v", recorder.events[3])
+	}
+	if recorder.events[1].Result != audit.ResultFailure {
+		t.Errorf("refused approval audited as %s", recorder.events[1].Result)
+	}
+}
+
+func TestReject(t *testing.T) {
+	s, backend, _, ctx := newProtected(t, "")
+
+	var pending *PendingError
+	if err := s.DeleteWithContext(as(ctx, "alice"), "records/ledger.csv"); !errors.As(err, &pending) {
+		t.Fatalf("DeleteWithContext() error = %v, want *PendingError", err)
+	}
+	if _, err := s.Manager().Reject(ctx, pending.Request.ID, strings.Repeat("x", maxReasonLength+1)); !errors.Is(err, common.ErrInvalidArgument) {
+		t.Errorf("Reject() with a long reason error = %v", err)
+	}
+
+	// The requester may withdraw its own request.
+	req, err := s.Manager().Reject(as(ctx, "alice"), pending.Request.ID, "filed in error")
+	if err != nil {
+		t.Fatalf("Reject() error = %v", err)
+	}
+	if req.Status != StatusRejected || req.Reason != "filed in error" || req.DecidedBy != "alice" {
+		t.Errorf("rejected request = %+v", req)
+	}
+	if !exists(t, backend, "records/ledger.csv") {
+		t.Error("rejected deletion removed the object")
+	}
+	if _, err := s.Manager().Approve(as(ctx, "bob"), req.ID, s); !errors.Is(err, ErrRequestDecided) {
+		t.Errorf("Approve() of a rejected request error = %v", err)
+	}
+	if got := s.Manager().Requests(StatusPending); len(got) != 0 {
+		t.Errorf("Requests(pending) = %v, want none", got)
+	}
+	if got := s.Manager().Requests(""); len(got) != 1 {
+		t.Errorf("Requests() = %d, want 1", len(got))
+	}
+}
+
+func TestLegalHold(t *testing.T) {
+	s, backend, recorder, ctx := newProtected(t, "")
+	carol := as(ctx, "carol")
+	m := s.Manager()
+
+	var pending *PendingError
+	if err := s.DeleteWithContext(as(ctx, "alice"), "records/ledger.csv"); !errors.As(err, &pending) {
+		t.Fatalf("DeleteWithContext() error = %v, want *PendingError", err)
+	}
+
+	if _, err := m.PlaceHold(carol, "records/missing.csv", ""); !errors.Is(err, common.ErrNotFound) {
+		t.Errorf("PlaceHold(missing) error = %v, want ErrNotFound", err)
+	}
+	hold, err := m.PlaceHold(carol, "records/ledger.csv", "litigation")
+	if err != nil {
+		t.Fatalf("PlaceHold() error = %v", err)
+	}
+	if hold.PlacedBy != "carol" || hold.Reason != "litigation" {
+		t.Errorf("hold = %+v", hold)
+	}
+	if again, err := m.PlaceHold(as(ctx, "dave"), "records/ledger.csv", "other"); err != nil || again.PlacedBy != "carol" {
+		t.Errorf("PlaceHold() again = %+v, %v; want the existing hold", again, err)
+	}
+	if holds := m.Holds(); len(holds) != 1 || holds[0].Key != "records/ledger.csv" {
+		t.Errorf("Holds() = %v", holds)
+	}
+
+	// Held objects cannot be deleted, overwritten, or approved for deletion.
+	isHold := func(err error) bool {
+		return errors.Is(err, ErrLegalHold) && errors.Is(err, common.ErrPermissionDenied)
+	}
+	if err := s.DeleteWithContext(carol, "records/ledger.csv"); !isHold(err) {
+		t.Errorf("DeleteWithContext() error = %v, want ErrLegalHold", err)
+	}
+	if err := s.PutWithContext(carol, "records/ledger.csv", strings.NewReader("new")); !isHold(err) {
+		t.Errorf("PutWithContext() error = %v, want ErrLegalHold", err)
+	}
+	if err := s.PutWithMetadata(carol, "records/ledger.csv", strings.NewReader("new"), nil); !isHold(err) {
+		t.Errorf("PutWithMetadata() error = %v, want ErrLegalHold", err)
+	}
+	if err := s.Append(carol, "records/ledger.csv", strings.NewReader("more")); !isHold(err) {
+		t.Errorf("Append() error = %v, want ErrLegalHold", err)
+	}
+	if err := s.Compose(carol, "records/ledger.csv", "scratch/tmp.txt"); !isHold(err) {
+		t.Errorf("Compose() error = %v, want ErrLegalHold", err)
+	}
+	if _, err := m.Approve(as(ctx, "bob"), pending.Request.ID, s); !isHold(err) {
+		t.Errorf("Approve() error = %v, want ErrLegalHold", err)
+	}
+	if req, _ := m.Request(pending.Request.ID); req.Status != StatusPending {
+		t.Errorf("request status = %s after a refused approval, want pending", req.Status)
+	}
+
+	if err := m.ReleaseHold(carol, "records/ledger.csv"); err != nil {
+		t.Fatalf("ReleaseHold() error = %v", err)
+	}
+	if err := m.ReleaseHold(carol, "records/ledger.csv"); !errors.Is(err, ErrHoldNotFound) {
+		t.Errorf("ReleaseHold() again error = %v, want ErrHoldNotFound", err)
+	}
+	if _, err := m.Approve(as(ctx, "bob"), pending.Request.ID, s); err != nil {
+		t.Fatalf("Approve() after release error = %v", err)
+	}
+	if exists(t, backend, "records/ledger.csv") {
+		t.Error("approved deletion did not delete the object")
+	}
+
+	var placed, released int
+	for _, eventType := range recorder.types() {
+		switch eventType {
+		case audit.EventLegalHoldPlaced:
+			placed++
+		case audit.EventLegalHoldReleased:
+			released++
+		}
+	}
+	if placed != 1 || released != 1 {
+		t.Errorf("audited %d placed and %d released holds, want 1 and 1", placed, released)
+	}
+}
+
+func TestOnBehalfOfIdentity(t *testing.T) {
+	s, _, _, ctx := newProtected(t, "")
+	service := func(user string) context.Context {
+		return context.WithValue(ctx, adapters.PrincipalContextKey{}, &adapters.Principal{ID: "frontend", OnBehalfOf: user})
+	}
+
+	var pending *PendingError
+	if err := s.DeleteWithContext(service("alice"), "records/ledger.csv"); !errors.As(err, &pending) {
+		t.Fatalf("DeleteWithContext() error = %v, want *PendingError", err)
+	}
+	if pending.Request.RequestedBy != "alice" {
+		t.Errorf("RequestedBy = %q, want the end user", pending.Request.RequestedBy)
+	}
+	if _, err := s.Manager().Approve(service("alice"), pending.Request.ID, s); !errors.Is(err, ErrSelfApproval) {
+		t.Errorf("Approve() for the requester error = %v, want ErrSelfApproval", err)
+	}
+	if _, err := s.Manager().Approve(service("bob"), pending.Request.ID, s); err != nil {
+		t.Errorf("Approve() for another user error = %v", err)
+	}
+}
+
+func TestStatePersisted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "retention.json")
+	s, backend, _, ctx := newProtected(t, path)
+
+	var pending *PendingError
+	if err := s.DeleteWithContext(as(ctx, "alice"), "records/ledger.csv"); !errors.As(err, &pending) {
+		t.Fatalf("DeleteWithContext() error = %v, want *PendingError", err)
+	}
+	if _, err := s.Manager().PlaceHold(ctx, "scratch/tmp.txt", "audit"); err != nil {
+		t.Fatalf("PlaceHold() error = %v", err)
+	}
+
+	reloaded, err := NewManager(backend, Config{StatePath: path})
+	if err != nil {
+		t.Fatalf("NewManager() reload error = %v", err)
+	}
+	if req, err := reloaded.Request(pending.Request.ID); err != nil || req.Status != StatusPending || req.RequestedBy != "alice" {
+		t.Errorf("reloaded request = %+v, %v", req, err)
+	}
+	if !reloaded.Held("scratch/tmp.txt") {
+		t.Error("reloaded manager lost the legal hold")
+	}
+
+	if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewManager(backend, Config{StatePath: path}); !errors.Is(err, ErrStateCorrupt) {
+		t.Errorf("NewManager() with a corrupt file error = %v, want ErrStateCorrupt", err)
+	}
+
+	// A failed write leaves the in-memory state unchanged.
+	broken, err := NewManager(backend, Config{ProtectedPrefixes: []string{"records/"}, StatePath: filepath.Join(t.TempDir(), "missing", "state.json")})
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	if _, err := broken.PlaceHold(ctx, "records/ledger.csv", ""); err == nil {
+		t.Error("PlaceHold() with an unwritable state file succeeded")
+	}
+	if broken.Held("records/ledger.csv") {
+		t.Error("hold kept after the state could not be saved")
+	}
+}
+
+func TestStateShared(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "retention.json")
+	backend := memory.New()
+	ctx := context.Background()
+	if err := backend.PutWithContext(ctx, "records/ledger.csv", strings.NewReader("ledger")); err != nil {
+		t.Fatal(err)
+	}
+	config := Config{ProtectedPrefixes: []string{"records/"}, StatePath: path, Shared: true}
+	a, err := NewManager(backend, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewManager(backend, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Each instance sees the other's changes
+	if _, err := a.PlaceHold(ctx, "records/ledger.csv", "litigation"); err != nil {
+		t.Fatalf("PlaceHold() error = %v", err)
+	}
+	if !b.Held("records/ledger.csv") {
+		t.Error("hold placed on one instance not seen by the other")
+	}
+	if err := b.ReleaseHold(ctx, "records/ledger.csv"); err != nil {
+		t.Fatalf("ReleaseHold() on the other instance error = %v", err)
+	}
+	if a.Held("records/ledger.csv") || len(a.Holds()) != 0 {
+		t.Error("released hold still seen")
+	}
+
+	// A change is refused when the shared state cannot be read
+	if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.PlaceHold(ctx, "records/ledger.csv", ""); !errors.Is(err, ErrStateCorrupt) {
+		t.Errorf("PlaceHold() with corrupt shared state error = %v, want ErrStateCorrupt", err)
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package retention
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// Storage wraps a backend and enforces a Manager's holds and protected
+// prefixes. Deletes of held objects fail with ErrLegalHold; deletes under a
+// protected prefix return a *PendingError and leave the object in place.
+// Puts, appends and composes that would replace a held object also fail.
+type Storage struct {
+	common.Storage
+	manager *Manager
+}
+
+// NewStorage returns underlying wrapped so that deletes and overwrites are
+// checked against manager.
+func NewStorage(underlying common.Storage, manager *Manager) *Storage {
+	return &Storage{Storage: underlying, manager: manager}
+}
+
+// Manager returns the manager enforced by s.
+func (s *Storage) Manager() *Manager {
+	return s.manager
+}
+
+// Underlying returns the wrapped backend.
+func (s *Storage) Underlying() common.Storage {
+	return s.Storage
+}
+
+// GetRange reads a byte range from the wrapped backend, falling back to
+// discarding the leading bytes of a full read when it cannot read ranges.
+func (s *Storage) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	if rr, ok := s.Storage.(common.RangeReader); ok {
+		return rr.GetRange(ctx, key, offset, length)
+	}
+	rc, err := s.Storage.GetWithContext(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return common.SliceRange(rc, offset, length)
+}
+
+// GetAsOf reads an earlier version of an object from the wrapped backend.
+func (s *Storage) GetAsOf(ctx context.Context, key string, at time.Time) (io.ReadCloser, *common.Metadata, error) {
+	return common.GetAsOf(ctx, s.Storage, key, at)
+}
+
+// Put stores an object unless it would replace a held object.
+func (s *Storage) Put(key string, data io.Reader) error {
+	return s.PutWithContext(context.Background(), key, data)
+}
+
+// PutWithContext stores an object unless it would replace a held object.
+func (s *Storage) PutWithContext(ctx context.Context, key string, data io.Reader) error {
+	if s.manager.Held(key) {
+		return holdError(key)
+	}
+	return s.Storage.PutWithContext(ctx, key, data)
+}
+
+// PutWithMetadata stores an object with metadata unless it would replace a
+// held object.
+func (s *Storage) PutWithMetadata(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) error {
+	if s.manager.Held(key) {
+		return holdError(key)
+	}
+	return s.Storage.PutWithMetadata(ctx, key, data, metadata)
+}
+
+// PutWithInfo stores an object with metadata unless it would replace a held
+// object, and returns what was stored.
+func (s *Storage) PutWithInfo(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) (*common.ObjectInfo, error) {
+	if s.manager.Held(key) {
+		return nil, holdError(key)
+	}
+	return common.PutWithInfo(ctx, s.Storage, key, data, metadata)
+}
+
+// Append adds data to the end of an object unless it is held.
+func (s *Storage) Append(ctx context.Context, key string, data io.Reader) error {
+	if s.manager.Held(key) {
+		return holdError(key)
+	}
+	return common.Append(ctx, s.Storage, key, data)
+}
+
+// Compose concatenates srcKeys into destKey unless destKey is held.
+func (s *Storage) Compose(ctx context.Context, destKey string, srcKeys ...string) error {
+	if s.manager.Held(destKey) {
+		return holdError(destKey)
+	}
+	return common.Compose(ctx, s.Storage, destKey, srcKeys...)
+}
+
+// Delete removes an object, subject to holds and approval.
+func (s *Storage) Delete(key string) error {
+	return s.DeleteWithContext(context.Background(), key)
+}
+
+// DeleteWithContext removes an object. Held objects cannot be deleted, and
+// existing objects under a protected prefix are queued for approval instead.
+func (s *Storage) DeleteWithContext(ctx context.Context, key string) error {
+	if approved(ctx, key) {
+		return s.Storage.DeleteWithContext(ctx, key)
+	}
+	if s.manager.Held(key) {
+		return holdError(key)
+	}
+	if !s.manager.Protected(key) {
+		return s.Storage.DeleteWithContext(ctx, key)
+	}
+
+	// Deleting a missing object is left to the backend rather than queued.
+	exists, err := s.Storage.Exists(ctx, key)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return s.Storage.DeleteWithContext(ctx, key)
+	}
+	req, err := s.manager.RequestDeletion(ctx, key)
+	if err != nil {
+		return err
+	}
+	return &PendingError{Request: *req}
+}
@@ -0,0 +1,352 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package routing spreads reads across backends that hold the same
+// replicated data. A Router orders the candidate backends for a key by
+// one of several strategies; the caller reads from the first and falls
+// back to the next only when a replica is unavailable or cannot be
+// reached. Reads can also be hedged: a read that is slow to return is
+// sent to the next backend as well and the first response wins.
+package routing
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// Strategies.
+const (
+	// StrategyRoundRobin rotates the first choice through the backends on
+	// every read.
+	StrategyRoundRobin = "round-robin"
+
+	// StrategyLatency prefers the backend with the lowest observed read
+	// latency. Backends that have not been read from yet are tried first
+	// so they get measured.
+	StrategyLatency = "latency"
+
+	// StrategyZone prefers backends in the local zone, rotating among
+	// them, and falls back to the other zones.
+	StrategyZone = "zone"
+
+	// StrategyHash maps each key to a backend on a consistent hash ring,
+	// so repeated reads of a key hit the same replica's caches and adding
+	// or removing a backend only moves the keys it owns.
+	StrategyHash = "hash"
+)
+
+const (
+	// DefaultVirtualNodes is the number of points each backend occupies
+	// on the hash ring.
+	DefaultVirtualNodes = 128
+
+	// DefaultErrorPenalty is the latency recorded for a failed read.
+	DefaultErrorPenalty = time.Second
+
+	// latencyWeight is the weight of a new observation in the moving
+	// average of a backend's latency.
+	latencyWeight = 0.2
+)
+
+// ErrInvalidConfig is returned for a malformed routing configuration.
+var ErrInvalidConfig = fmt.Errorf("%w: invalid read routing config", common.ErrInvalidArgument)
+
+// Config configures a Router.
+type Config struct {
+	// Strategy is StrategyRoundRobin (the default), StrategyLatency,
+	// StrategyZone or StrategyHash.
+	Strategy string
+
+	// Backends names the backends holding the same data.
+	Backends []string
+
+	// Zones maps backend names to the zone they run in, for StrategyZone.
+	Zones map[string]string
+
+	// Zone is the zone of this process, for StrategyZone.
+	Zone string
+
+	// VirtualNodes is the number of points each backend occupies on the
+	// hash ring, for StrategyHash. Zero uses DefaultVirtualNodes.
+	VirtualNodes int
+
+	// ErrorPenalty is the latency recorded for a failed read, for
+	// StrategyLatency. Zero uses DefaultErrorPenalty.
+	ErrorPenalty time.Duration
+
+	// HedgeAfter hedges reads that have not returned within it by sending
+	// the same read to the next backend and using the first response. It
+	// lowers tail latency at the cost of extra requests, so set it near
+	// the usual p95 or p99 read latency. Zero disables hedging.
+	HedgeAfter time.Duration
+}
+
+// point is a backend's position on the hash ring.
+type point struct {
+	hash    uint64
+	backend int
+}
+
+// Router orders the backends to read a key from. It is safe for
+// concurrent use.
+type Router struct {
+	strategy   string
+	backends   []string
+	penalty    time.Duration
+	hedgeAfter time.Duration
+
+	next atomic.Uint64
+
+	// hedges counts hedged reads sent and hedgeWins those that answered
+	// first.
+	hedges, hedgeWins atomic.Uint64
+
+	// local and remote hold the indexes of the backends in and outside
+	// the local zone, for StrategyZone.
+	local, remote []int
+
+	// ring is sorted by hash, for StrategyHash.
+	ring []point
+
+	// latency holds the moving average read latency of each backend in
+	// nanoseconds, zero until the first observation, for StrategyLatency.
+	mu      sync.Mutex
+	latency []float64
+}
+
+// New returns a router over cfg.Backends, or an error wrapping
+// ErrInvalidConfig.
+func New(cfg *Config) (*Router, error) {
+	if cfg == nil || len(cfg.Backends) == 0 {
+		return nil, fmt.Errorf("%w: no backends", ErrInvalidConfig)
+	}
+	seen := make(map[string]bool, len(cfg.Backends))
+	for _, name := range cfg.Backends {
+		if name == "" || seen[name] {
+			return nil, fmt.Errorf("%w: empty or duplicate backend %q", ErrInvalidConfig, name)
+		}
+		seen[name] = true
+	}
+
+	if cfg.HedgeAfter < 0 {
+		return nil, fmt.Errorf("%w: negative hedge delay %s", ErrInvalidConfig, cfg.HedgeAfter)
+	}
+
+	r := &Router{
+		strategy:   cfg.Strategy,
+		backends:   append([]string(nil), cfg.Backends...),
+		penalty:    cfg.ErrorPenalty,
+		hedgeAfter: cfg.HedgeAfter,
+	}
+	if r.strategy == "" {
+		r.strategy = StrategyRoundRobin
+	}
+	if r.penalty == 0 {
+		r.penalty = DefaultErrorPenalty
+	}
+
+	switch r.strategy {
+	case StrategyRoundRobin:
+	case StrategyLatency:
+		r.latency = make([]float64, len(r.backends))
+	case StrategyZone:
+		if cfg.Zone == "" {
+			return nil, fmt.Errorf("%w: zone strategy needs the local zone", ErrInvalidConfig)
+		}
+		for i, name := range r.backends {
+			if cfg.Zones[name] == cfg.Zone {
+				r.local = append(r.local, i)
+			} else {
+				r.remote = append(r.remote, i)
+			}
+		}
+	case StrategyHash:
+		vnodes := cfg.VirtualNodes
+		if vnodes == 0 {
+			vnodes = DefaultVirtualNodes
+		}
+		if vnodes < 0 {
+			return nil, fmt.Errorf("%w: negative virtual nodes %d", ErrInvalidConfig, vnodes)
+		}
+		r.ring = make([]point, 0, vnodes*len(r.backends))
+		for i, name := range r.backends {
+			for v := 0; v < vnodes; v++ {
+				r.ring = append(r.ring, point{hash: hashString(name + "#" + strconv.Itoa(v)), backend: i})
+			}
+		}
+		sort.Slice(r.ring, func(i, j int) bool { return r.ring[i].hash < r.ring[j].hash })
+	default:
+		return nil, fmt.Errorf("%w: unknown strategy %q", ErrInvalidConfig, cfg.Strategy)
+	}
+	return r, nil
+}
+
+// Strategy returns the router's strategy.
+func (r *Router) Strategy() string {
+	return r.strategy
+}
+
+// HedgeAfter returns how long a read waits before it is hedged, zero if
+// reads are not hedged.
+func (r *Router) HedgeAfter() time.Duration {
+	return r.hedgeAfter
+}
+
+// Hedged records a hedged read, and whether the hedge answered before the
+// read it hedged.
+func (r *Router) Hedged(won bool) {
+	r.hedges.Add(1)
+	if won {
+		r.hedgeWins.Add(1)
+	}
+}
+
+// Hedges returns the number of hedged reads sent and how many of them
+// answered first.
+func (r *Router) Hedges() (sent, won uint64) {
+	return r.hedges.Load(), r.hedgeWins.Load()
+}
+
+// Backends returns every backend in the order key should be read from:
+// the preferred backend first, then the fallbacks.
+func (r *Router) Backends(key string) []string {
+	var order []int
+	switch r.strategy {
+	case StrategyLatency:
+		order = r.byLatency()
+	case StrategyZone:
+		n := r.next.Add(1) - 1
+		order = append(rotate(r.local, n), rotate(r.remote, n)...)
+	case StrategyHash:
+		order = r.onRing(key)
+	default:
+		order = rotate(indexes(len(r.backends)), r.next.Add(1)-1)
+	}
+
+	names := make([]string, len(order))
+	for i, idx := range order {
+		names[i] = r.backends[idx]
+	}
+	return names
+}
+
+// Observe records how long a read from backend took and whether it
+// failed. Only StrategyLatency uses the observations.
+func (r *Router) Observe(backend string, d time.Duration, err error) {
+	if r.latency == nil {
+		return
+	}
+	if err != nil {
+		d = r.penalty
+	}
+	for i, name := range r.backends {
+		if name != backend {
+			continue
+		}
+		r.mu.Lock()
+		if r.latency[i] == 0 {
+			r.latency[i] = float64(d)
+		} else {
+			r.latency[i] += latencyWeight * (float64(d) - r.latency[i])
+		}
+		r.mu.Unlock()
+		return
+	}
+}
+
+// Latency returns the moving average read latency of backend, zero if it
+// has not been observed.
+func (r *Router) Latency(backend string) time.Duration {
+	if r.latency == nil {
+		return 0
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, name := range r.backends {
+		if name == backend {
+			return time.Duration(r.latency[i])
+		}
+	}
+	return 0
+}
+
+// byLatency orders the backends by ascending average latency, rotating
+// among ties so unmeasured backends share the first reads.
+func (r *Router) byLatency() []int {
+	order := rotate(indexes(len(r.backends)), r.next.Add(1)-1)
+	r.mu.Lock()
+	latency := append([]float64(nil), r.latency...)
+	r.mu.Unlock()
+	sort.SliceStable(order, func(i, j int) bool { return latency[order[i]] < latency[order[j]] })
+	return order
+}
+
+// onRing orders the backends by their first point at or after the key's
+// hash, walking the ring clockwise.
+func (r *Router) onRing(key string) []int {
+	h := hashString(key)
+	start := sort.Search(len(r.ring), func(i int) bool { return r.ring[i].hash >= h })
+
+	order := make([]int, 0, len(r.backends))
+	seen := make([]bool, len(r.backends))
+	for i := 0; i < len(r.ring) && len(order) < len(r.backends); i++ {
+		p := r.ring[(start+i)%len(r.ring)]
+		if !seen[p.backend] {
+			seen[p.backend] = true
+			order = append(order, p.backend)
+		}
+	}
+	return order
+}
+
+// indexes returns 0..n-1.
+func indexes(n int) []int {
+	out := make([]int, n)
+	for i := range out {
+		out[i] = i
+	}
+	return out
+}
+
+// rotate returns a copy of s rotated left by n.
+func rotate(s []int, n uint64) []int {
+	out := make([]int, 0, len(s))
+	if len(s) == 0 {
+		return out
+	}
+	start := int(n % uint64(len(s)))
+	out = append(out, s[start:]...)
+	return append(out, s[:start]...)
+}
+
+// hashString returns the 64-bit FNV-1a hash of s, mixed with the
+// splitmix64 finalizer so that similar strings such as the virtual node
+// names of a backend spread evenly over the ring.
+func hashString(s string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(s)) // #nosec G104 -- hash.Hash never returns an error
+	x := h.Sum64()
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
@@ -0,0 +1,162 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package routing
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRoundRobin(t *testing.T) {
+	r, err := New(&Config{Backends: []string{"a", "b", "c"}})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if r.Strategy() != StrategyRoundRobin {
+		t.Fatalf("Strategy() = %q, want round-robin", r.Strategy())
+	}
+	want := [][]string{{"a", "b", "c"}, {"b", "c", "a"}, {"c", "a", "b"}, {"a", "b", "c"}}
+	for i, w := range want {
+		if got := r.Backends("key"); !reflect.DeepEqual(got, w) {
+			t.Fatalf("read %d: Backends() = %v, want %v", i, got, w)
+		}
+	}
+}
+
+func TestLatency(t *testing.T) {
+	r, err := New(&Config{Strategy: StrategyLatency, Backends: []string{"slow", "fast", "new"}})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	r.Observe("slow", 80*time.Millisecond, nil)
+	r.Observe("fast", 5*time.Millisecond, nil)
+	if got := r.Backends("key"); !reflect.DeepEqual(got, []string{"new", "fast", "slow"}) {
+		t.Fatalf("Backends() = %v, want the unmeasured backend first", got)
+	}
+
+	r.Observe("new", 0, errors.New("unavailable"))
+	if got := r.Latency("new"); got != DefaultErrorPenalty {
+		t.Fatalf("Latency() after a failure = %s, want %s", got, DefaultErrorPenalty)
+	}
+	if got := r.Backends("key"); !reflect.DeepEqual(got, []string{"fast", "slow", "new"}) {
+		t.Fatalf("Backends() = %v, want fastest first", got)
+	}
+
+	r.Observe("fast", 105*time.Millisecond, nil)
+	if got := r.Latency("fast"); got != 25*time.Millisecond {
+		t.Fatalf("Latency() = %s, want the moving average 25ms", got)
+	}
+	if got := r.Latency("unknown"); got != 0 {
+		t.Fatalf("Latency() of an unknown backend = %s", got)
+	}
+}
+
+func TestZone(t *testing.T) {
+	r, err := New(&Config{
+		Strategy: StrategyZone,
+		Backends: []string{"east1", "west1", "east2"},
+		Zones:    map[string]string{"east1": "us-east", "east2": "us-east", "west1": "us-west"},
+		Zone:     "us-east",
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if got := r.Backends("key"); !reflect.DeepEqual(got, []string{"east1", "east2", "west1"}) {
+		t.Fatalf("Backends() = %v", got)
+	}
+	if got := r.Backends("key"); !reflect.DeepEqual(got, []string{"east2", "east1", "west1"}) {
+		t.Fatalf("Backends() = %v, want the local zone rotated", got)
+	}
+}
+
+func TestHash(t *testing.T) {
+	backends := []string{"a", "b", "c"}
+	r, err := New(&Config{Strategy: StrategyHash, Backends: backends})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	counts := map[string]int{}
+	owners := map[string]string{}
+	for i := 0; i < 3000; i++ {
+		key := fmt.Sprintf("objects/%d.bin", i)
+		order := r.Backends(key)
+		if len(order) != len(backends) {
+			t.Fatalf("Backends(%q) = %v, want every backend", key, order)
+		}
+		if again := r.Backends(key); !reflect.DeepEqual(again, order) {
+			t.Fatalf("Backends(%q) is not stable: %v then %v", key, order, again)
+		}
+		counts[order[0]]++
+		owners[key] = order[0]
+	}
+	for _, name := range backends {
+		if counts[name] < 500 {
+			t.Errorf("backend %s owns %d of 3000 keys, want a fair share", name, counts[name])
+		}
+	}
+
+	// Adding a backend only moves keys to it.
+	grown, err := New(&Config{Strategy: StrategyHash, Backends: append(backends, "d")})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	for key, owner := range owners {
+		if got := grown.Backends(key)[0]; got != owner && got != "d" {
+			t.Fatalf("key %s moved from %s to %s", key, owner, got)
+		}
+	}
+}
+
+func TestNewInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *Config
+	}{
+		{"nil", nil},
+		{"no backends", &Config{}},
+		{"duplicate backend", &Config{Backends: []string{"a", "a"}}},
+		{"empty backend", &Config{Backends: []string{""}}},
+		{"unknown strategy", &Config{Strategy: "random", Backends: []string{"a"}}},
+		{"zone without local zone", &Config{Strategy: StrategyZone, Backends: []string{"a"}}},
+		{"negative virtual nodes", &Config{Strategy: StrategyHash, Backends: []string{"a"}, VirtualNodes: -1}},
+		{"negative hedge delay", &Config{Backends: []string{"a"}, HedgeAfter: -time.Second}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.cfg); !errors.Is(err, ErrInvalidConfig) {
+				t.Fatalf("New() error = %v, want ErrInvalidConfig", err)
+			}
+		})
+	}
+}
+
+func TestHedges(t *testing.T) {
+	r, err := New(&Config{Backends: []string{"a", "b"}, HedgeAfter: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if got := r.HedgeAfter(); got != 50*time.Millisecond {
+		t.Fatalf("HedgeAfter() = %s", got)
+	}
+	r.Hedged(true)
+	r.Hedged(false)
+	r.Hedged(true)
+	if sent, won := r.Hedges(); sent != 3 || won != 2 {
+		t.Fatalf("Hedges() = %d, %d, want 3, 2", sent, won)
+	}
+}
@@ -15,7 +15,18 @@
 
 package s3
 
-import "testing"
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/credfile"
+
+	"github.com/aws/aws-sdk-go/aws/credentials" //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+)
 
 func TestS3_Configure_Errors(t *testing.T) {
 	s := &S3{}
@@ -41,3 +52,120 @@ func TestS3_Configure_WithEndpointAndCreds(t *testing.T) {
 		t.Fatalf("expected svc initialized")
 	}
 }
+
+func TestS3_Configure_CredentialProviders(t *testing.T) {
+	base := map[string]string{"bucket": "b", "region": "us-east-1"}
+	with := func(extra map[string]string) map[string]string {
+		settings := map[string]string{}
+		for k, v := range base {
+			settings[k] = v
+		}
+		for k, v := range extra {
+			settings[k] = v
+		}
+		return settings
+	}
+
+	valid := []map[string]string{
+		{"credentials": CredentialsDefault},
+		{"credentials": CredentialsInstance},
+		{"credentials": CredentialsAssumeRole, "roleArn": "arn:aws:iam::123456789012:role/objstore", "externalId": "x"},
+		{"credentials": CredentialsWebIdentity, "roleArn": "arn:aws:iam::123456789012:role/objstore", "webIdentityTokenFile": "/var/run/token"},
+		{"credentials": CredentialsStatic, "accessKey": "ak", "secretKey": "sk", "sessionToken": "st"},
+	}
+	for _, extra := range valid {
+		if err := (&S3{}).Configure(with(extra)); err != nil {
+			t.Errorf("Configure(%v) error = %v", extra, err)
+		}
+	}
+
+	t.Setenv("AWS_ROLE_ARN", "")
+	t.Setenv("AWS_WEB_IDENTITY_TOKEN_FILE", "")
+	invalid := []map[string]string{
+		{"credentials": "vault"},
+		{"credentials": CredentialsStatic, "accessKey": "ak"},
+		{"credentials": CredentialsStatic, "secretKey": "sk"},
+		{"credentials": CredentialsAssumeRole},
+		{"credentials": CredentialsWebIdentity, "roleArn": "arn:aws:iam::123456789012:role/objstore"},
+		{"credentials": CredentialsFile},
+		{"credentials": CredentialsFile, "credentialsFile": filepath.Join(t.TempDir(), "missing")},
+	}
+	for _, extra := range invalid {
+		if err := (&S3{}).Configure(with(extra)); err == nil {
+			t.Errorf("Configure(%v) succeeded, want an error", extra)
+		}
+	}
+}
+
+func TestS3_FileCredentialsRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials")
+	if err := os.WriteFile(path, []byte("[default]\naws_access_key_id = AK1\naws_secret_access_key = SK1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	file, err := credfile.Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	file.SetCheckInterval(0)
+	creds := credentials.NewCredentials(&fileProvider{file: file})
+
+	value, err := creds.Get()
+	if err != nil || value.AccessKeyID != "AK1" || value.SecretAccessKey != "SK1" {
+		t.Fatalf("Get() = %+v, %v", value, err)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"accessKey": "AK2", "secretKey": "SK2", "sessionToken": "ST2"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if !creds.IsExpired() {
+		t.Fatal("credentials did not expire after the file changed")
+	}
+	value, err = creds.Get()
+	if err != nil || value.AccessKeyID != "AK2" || value.SessionToken != "ST2" {
+		t.Fatalf("Get() after rotation = %+v, %v", value, err)
+	}
+
+	if err := os.WriteFile(path, []byte("accessKey=AK3\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	creds.Expire()
+	if _, err := creds.Get(); !errors.Is(err, credfile.ErrInvalidFile) {
+		t.Fatalf("Get() of a file without a secret key error = %v", err)
+	}
+}
+
+func TestS3_Configure_ObjectLock(t *testing.T) {
+	base := func(extra map[string]string) map[string]string {
+		settings := map[string]string{"bucket": "b", "region": "us-east-1", "accessKey": "ak", "secretKey": "sk"}
+		for k, v := range extra {
+			settings[k] = v
+		}
+		return settings
+	}
+
+	s := &S3{}
+	if err := s.Configure(base(map[string]string{"objectLockMode": "compliance", "objectLockDays": "30"})); err != nil {
+		t.Fatalf("unexpected configure error: %v", err)
+	}
+	input, err := s.putObjectInput("k", nil, nil)
+	if err != nil {
+		t.Fatalf("putObjectInput: %v", err)
+	}
+	if input.ObjectLockMode == nil || *input.ObjectLockMode != "COMPLIANCE" {
+		t.Fatalf("unexpected lock mode: %v", input.ObjectLockMode)
+	}
+	if input.ObjectLockRetainUntilDate == nil || input.ObjectLockRetainUntilDate.Before(time.Now().AddDate(0, 0, 29)) {
+		t.Fatalf("unexpected retain-until date: %v", input.ObjectLockRetainUntilDate)
+	}
+
+	for _, extra := range []map[string]string{
+		{"objectLockMode": "LEGAL"},
+		{"objectLockMode": "GOVERNANCE"},
+		{"objectLockMode": "GOVERNANCE", "objectLockDays": "0"},
+		{"objectLockDays": "7"},
+	} {
+		if err := (&S3{}).Configure(base(extra)); !errors.Is(err, common.ErrInvalidArgument) {
+			t.Errorf("%v: expected invalid argument, got %v", extra, err)
+		}
+	}
+}
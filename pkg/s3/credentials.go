@@ -0,0 +1,166 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build awss3
+
+package s3
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/credfile"
+
+	"github.com/aws/aws-sdk-go/aws"                          //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+	"github.com/aws/aws-sdk-go/aws/credentials"              //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+	"github.com/aws/aws-sdk-go/aws/credentials/ec2rolecreds" //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"     //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+	"github.com/aws/aws-sdk-go/aws/session"                  //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+)
+
+// Credential providers selected with the "credentials" setting.
+const (
+	// CredentialsStatic uses the accessKey, secretKey and optional
+	// sessionToken settings.
+	CredentialsStatic = "static"
+
+	// CredentialsDefault uses the SDK's default chain: environment, shared
+	// config, web identity, then the ECS or EC2 role.
+	CredentialsDefault = "default"
+
+	// CredentialsWebIdentity exchanges a web identity token file for role
+	// credentials, as with IAM Roles for Service Accounts (IRSA) on EKS.
+	CredentialsWebIdentity = "web-identity"
+
+	// CredentialsAssumeRole assumes roleArn with the default chain's
+	// credentials.
+	CredentialsAssumeRole = "assume-role"
+
+	// CredentialsInstance uses the EC2 instance profile role.
+	CredentialsInstance = "instance"
+
+	// CredentialsFile reads accessKey, secretKey and sessionToken from
+	// credentialsFile and re-reads it when it changes.
+	CredentialsFile = "file"
+)
+
+// newCredentials returns the credentials selected by settings, or nil to
+// use the SDK's default chain. Without a "credentials" setting, static
+// credentials are used when accessKey is set. Role based providers call
+// STS in the backend's region.
+func newCredentials(settings map[string]string, cfg *aws.Config) (*credentials.Credentials, error) {
+	provider := settings["credentials"]
+	if provider == "" && settings["accessKey"] != "" {
+		provider = CredentialsStatic
+	}
+
+	switch provider {
+	case "", CredentialsDefault:
+		return nil, nil
+	case CredentialsStatic:
+		if settings["accessKey"] == "" {
+			return nil, common.ErrAccessKeyNotSet
+		}
+		if settings["secretKey"] == "" {
+			return nil, common.ErrSecretKeyNotSet
+		}
+		return credentials.NewStaticCredentials(settings["accessKey"], settings["secretKey"], settings["sessionToken"]), nil
+	case CredentialsFile:
+		file, err := credfile.Open(settings["credentialsFile"])
+		if err != nil {
+			return nil, err
+		}
+		return credentials.NewCredentials(&fileProvider{file: file}), nil
+	}
+
+	// The remaining providers call STS or the instance metadata service,
+	// never the S3 endpoint override.
+	sess, err := session.NewSession(&aws.Config{Region: cfg.Region, HTTPClient: cfg.HTTPClient})
+	if err != nil {
+		return nil, err
+	}
+	switch provider {
+	case CredentialsWebIdentity:
+		roleARN := settingOrEnv(settings, "roleArn", "AWS_ROLE_ARN")
+		tokenFile := settingOrEnv(settings, "webIdentityTokenFile", "AWS_WEB_IDENTITY_TOKEN_FILE")
+		if roleARN == "" || tokenFile == "" {
+			return nil, fmt.Errorf("%w: web-identity credentials need roleArn and webIdentityTokenFile", common.ErrInvalidArgument)
+		}
+		return stscreds.NewWebIdentityCredentials(sess, roleARN, sessionName(settings), tokenFile), nil
+	case CredentialsAssumeRole:
+		roleARN := settings["roleArn"]
+		if roleARN == "" {
+			return nil, fmt.Errorf("%w: assume-role credentials need roleArn", common.ErrInvalidArgument)
+		}
+		return stscreds.NewCredentials(sess, roleARN, func(p *stscreds.AssumeRoleProvider) {
+			p.RoleSessionName = sessionName(settings)
+			if id := settings["externalId"]; id != "" {
+				p.ExternalID = aws.String(id)
+			}
+		}), nil
+	case CredentialsInstance:
+		return ec2rolecreds.NewCredentials(sess), nil
+	default:
+		return nil, fmt.Errorf("%w: unknown credentials provider %q", common.ErrInvalidArgument, provider)
+	}
+}
+
+// settingOrEnv returns settings[key], falling back to the environment
+// variable env.
+func settingOrEnv(settings map[string]string, key, env string) string {
+	if v := settings[key]; v != "" {
+		return v
+	}
+	return os.Getenv(env)
+}
+
+// sessionName returns the role session name to use.
+func sessionName(settings map[string]string) string {
+	if name := settingOrEnv(settings, "roleSessionName", "AWS_ROLE_SESSION_NAME"); name != "" {
+		return name
+	}
+	return "objstore"
+}
+
+// fileProvider serves credentials from a credentials file. They expire,
+// and are read again, whenever the file changes.
+type fileProvider struct {
+	file    *credfile.File
+	version uint64
+}
+
+// Retrieve returns the credentials in the file.
+func (p *fileProvider) Retrieve() (credentials.Value, error) {
+	if err := p.file.Refresh(); err != nil && p.version == 0 {
+		return credentials.Value{}, err
+	}
+	value := credentials.Value{
+		AccessKeyID:     p.file.Lookup("accessKey", "aws_access_key_id"),
+		SecretAccessKey: p.file.Lookup("secretKey", "aws_secret_access_key"),
+		SessionToken:    p.file.Lookup("sessionToken", "aws_session_token"),
+		ProviderName:    "ObjstoreFileProvider",
+	}
+	if value.AccessKeyID == "" || value.SecretAccessKey == "" {
+		return credentials.Value{}, fmt.Errorf("%w: %s has no access key and secret key", credfile.ErrInvalidFile, p.file.Path())
+	}
+	p.version = p.file.Version()
+	return value, nil
+}
+
+// IsExpired reports whether the file changed since the credentials were
+// retrieved.
+func (p *fileProvider) IsExpired() bool {
+	_ = p.file.Refresh() // #nosec G104 -- A failed reload keeps the current credentials
+	return p.file.Version() != p.version
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build awss3
+
+package s3
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+func TestTranslateError(t *testing.T) {
+	failure := func(code string, status int) error {
+		return awserr.NewRequestFailure(awserr.New(code, code, nil), status, "req-1")
+	}
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"no such key", failure("NoSuchKey", 404), common.ErrKeyNotFound},
+		{"head not found", failure("NotFound", 404), common.ErrKeyNotFound},
+		{"no such bucket", failure("NoSuchBucket", 404), common.ErrNotFound},
+		{"access denied", failure("AccessDenied", 403), common.ErrAccessDenied},
+		{"bad signature", failure("SignatureDoesNotMatch", 403), common.ErrAccessDenied},
+		{"precondition", failure("PreconditionFailed", 412), common.ErrPreconditionFailed},
+		{"slow down", failure("SlowDown", 503), common.ErrQuotaExceeded},
+		{"service unavailable", failure("ServiceUnavailable", 503), common.ErrBackendUnavailable},
+		{"network", awserr.New(request.ErrCodeRequestError, "send request failed", errors.New("dial tcp")), common.ErrBackendUnavailable},
+		{"canceled", awserr.New(request.CanceledErrorCode, "request context canceled", context.Canceled), context.Canceled},
+		{"unknown code, status fallback", failure("SomethingNew", 409), common.ErrAlreadyExists},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := translateError(tt.err, "a/key")
+			if !errors.Is(got, tt.want) {
+				t.Errorf("translateError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+			var aerr awserr.Error
+			if !errors.As(got, &aerr) {
+				t.Errorf("translateError(%v) dropped the S3 error from the chain", tt.err)
+			}
+		})
+	}
+
+	plain := errors.New("not an S3 error")
+	if got := translateError(plain, "k"); got != plain {
+		t.Errorf("translateError(plain) = %v, want unchanged", got)
+	}
+	if got := translateError(failure("Weird", 400), "k"); common.Classify(got) != common.CodeInternal {
+		t.Errorf("unmapped 400 classified as %v, want internal", common.Classify(got))
+	}
+}
+
+func TestGetTranslatesNotFound(t *testing.T) {
+	s := &S3{svc: &mockS3Client{
+		getObjectError: awserr.NewRequestFailure(awserr.New("NoSuchKey", "The specified key does not exist.", nil), 404, "req-1"),
+	}, bucket: "test-bucket"}
+
+	if _, err := s.Get("missing"); !errors.Is(err, common.ErrKeyNotFound) {
+		t.Errorf("Get() error = %v, want ErrKeyNotFound", err)
+	}
+	if _, err := s.GetWithContext(context.Background(), "missing"); !errors.Is(err, common.ErrKeyNotFound) {
+		t.Errorf("GetWithContext() error = %v, want ErrKeyNotFound", err)
+	}
+}
@@ -18,15 +18,18 @@
 package s3
 
 import (
+	"context"
+	"fmt"
 	"io"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/transport"
 
 	"github.com/aws/aws-sdk-go/aws"                  //nolint:staticcheck // Using v1 SDK, migration to v2 planned
-	"github.com/aws/aws-sdk-go/aws/credentials"      //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+	"github.com/aws/aws-sdk-go/aws/request"          //nolint:staticcheck // Using v1 SDK, migration to v2 planned
 	"github.com/aws/aws-sdk-go/aws/session"          //nolint:staticcheck // Using v1 SDK, migration to v2 planned
 	"github.com/aws/aws-sdk-go/service/s3"           //nolint:staticcheck // Using v1 SDK, migration to v2 planned
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"   //nolint:staticcheck // Using v1 SDK, migration to v2 planned
@@ -35,8 +38,9 @@ import (
 
 // Constants
 const (
-	actionDelete  = "delete"
-	actionArchive = "archive"
+	actionDelete     = "delete"
+	actionArchive    = "archive"
+	actionTransition = common.ActionTransition
 )
 
 type s3Uploader interface {
@@ -51,6 +55,8 @@ var s3managerNewUploaderWithClient = func(c s3iface.S3API, options ...func(*s3ma
 type S3 struct {
 	svc                s3iface.S3API
 	bucket             string
+	timeouts           *transport.Timeouts
+	objectLock         *objectLock
 	policiesMutex      sync.RWMutex
 	replicationManager common.ReplicationManager
 }
@@ -61,11 +67,26 @@ func New() common.Storage {
 }
 
 // Configure sets up the backend with the necessary settings.
+// HTTP transport tuning (http* keys) and per-operation timeouts (timeout,
+// getTimeout, ...) are described in the transport package; instances with
+// the same credentials, region and endpoint share a client. With
+// objectLockMode and objectLockDays every written object is retained under
+// S3 Object Lock for that many days.
 func (s *S3) Configure(settings map[string]string) error {
 	s.bucket = settings["bucket"]
 	if s.bucket == "" {
 		return common.ErrBucketNotSet
 	}
+	timeouts, err := transport.ParseTimeouts(settings)
+	if err != nil {
+		return err
+	}
+	s.timeouts = timeouts
+	lock, err := parseObjectLock(settings)
+	if err != nil {
+		return err
+	}
+	s.objectLock = lock
 
 	cfg := &aws.Config{
 		Region: aws.String(settings["region"]),
@@ -74,15 +95,28 @@ func (s *S3) Configure(settings map[string]string) error {
 		cfg.Endpoint = aws.String(ep)
 		cfg.S3ForcePathStyle = aws.Bool(settings["forcePathStyle"] == "true")
 	}
-	if ak := settings["accessKey"]; ak != "" {
-		sk := settings["secretKey"]
-		cfg.Credentials = credentials.NewStaticCredentials(ak, sk, "")
+
+	httpCfg, err := transport.ParseSettings(settings)
+	if err != nil {
+		return err
+	}
+	identity := settings["accessKey"] + "|" + settings["secretKey"] + "|" + settings["region"] + "|" + settings["endpoint"] +
+		"|" + settings["credentials"] + "|" + settings["credentialsFile"] + "|" + settings["roleArn"]
+	cfg.HTTPClient = transport.Default.Client("s3", identity, httpCfg)
+
+	creds, err := newCredentials(settings, cfg)
+	if err != nil {
+		return err
 	}
+	cfg.Credentials = creds
 
 	sess, err := session.NewSession(cfg)
 	if err != nil {
 		return err
 	}
+	sess.Handlers.Send.PushFront(func(r *request.Request) {
+		r.SetContext(transport.Default.WithTrace(r.Context(), "s3"))
+	})
 
 	s.svc = s3.New(sess)
 	return nil
@@ -90,79 +124,22 @@ func (s *S3) Configure(settings map[string]string) error {
 
 // Put stores an object in the backend.
 func (s *S3) Put(key string, data io.Reader) error {
-	if err := common.ValidateKey(key); err != nil {
-		return err
-	}
-	_, err := s.svc.PutObject(&s3.PutObjectInput{
-		Bucket: aws.String(s.bucket),
-		Key:    aws.String(key),
-		Body:   aws.ReadSeekCloser(data),
-	})
-	return err
+	return s.PutWithContext(context.Background(), key, data)
 }
 
 // Get retrieves an object from the backend.
 func (s *S3) Get(key string) (io.ReadCloser, error) {
-	if err := common.ValidateKey(key); err != nil {
-		return nil, err
-	}
-	result, err := s.svc.GetObject(&s3.GetObjectInput{
-		Bucket: aws.String(s.bucket),
-		Key:    aws.String(key),
-	})
-	if err != nil {
-		return nil, err
-	}
-	return result.Body, nil
+	return s.GetWithContext(context.Background(), key)
 }
 
 // Delete removes an object from the backend.
 func (s *S3) Delete(key string) error {
-	if err := common.ValidateKey(key); err != nil {
-		return err
-	}
-	_, err := s.svc.DeleteObject(&s3.DeleteObjectInput{
-		Bucket: aws.String(s.bucket),
-		Key:    aws.String(key),
-	})
-	return err
+	return s.DeleteWithContext(context.Background(), key)
 }
 
 // List returns a list of keys that start with the given prefix.
 func (s *S3) List(prefix string) ([]string, error) {
-	// Pre-allocate with reasonable capacity to reduce allocations
-	keys := make([]string, 0, 100)
-	var continuationToken *string
-
-	for {
-		input := &s3.ListObjectsV2Input{
-			Bucket: aws.String(s.bucket),
-			Prefix: aws.String(prefix),
-		}
-
-		if continuationToken != nil {
-			input.ContinuationToken = continuationToken
-		}
-
-		result, err := s.svc.ListObjectsV2(input)
-		if err != nil {
-			return nil, err
-		}
-
-		for _, obj := range result.Contents {
-			if obj.Key != nil {
-				keys = append(keys, *obj.Key)
-			}
-		}
-
-		if !aws.BoolValue(result.IsTruncated) {
-			break
-		}
-
-		continuationToken = result.NextContinuationToken
-	}
-
-	return keys, nil
+	return s.ListWithContext(context.Background(), prefix)
 }
 
 // Archive copies an object to another backend for archival.
@@ -181,15 +158,29 @@ func (s *S3) AddPolicy(policy common.LifecyclePolicy) error {
 	if policy.ID == "" {
 		return common.ErrInvalidPolicy
 	}
-	if policy.Action != actionDelete && policy.Action != actionArchive {
+	if policy.Action != actionDelete && policy.Action != actionArchive && policy.Action != actionTransition {
 		return common.ErrInvalidPolicy
 	}
+	transitionClass := s3.TransitionStorageClassGlacier
+	if policy.Action == actionTransition {
+		class, err := common.NormalizeStorageClass(policy.StorageClass, s3.TransitionStorageClass_Values())
+		if err != nil {
+			return err
+		}
+		if class == "" {
+			return fmt.Errorf("%w: transition policy requires a storage class", common.ErrInvalidArgument)
+		}
+		transitionClass = class
+	}
 
 	s.policiesMutex.Lock()
 	defer s.policiesMutex.Unlock()
 
+	ctx, cancel := s.timeouts.Context(context.Background(), transport.OpMetadata)
+	defer cancel()
+
 	// Get existing lifecycle configuration
-	existingConfig, err := s.svc.GetBucketLifecycleConfiguration(&s3.GetBucketLifecycleConfigurationInput{
+	existingConfig, err := s.svc.GetBucketLifecycleConfigurationWithContext(ctx, &s3.GetBucketLifecycleConfigurationInput{
 		Bucket: aws.String(s.bucket),
 	})
 
@@ -197,7 +188,7 @@ func (s *S3) AddPolicy(policy common.LifecyclePolicy) error {
 	if err != nil {
 		// If no lifecycle configuration exists, start with empty rules
 		if !isNoSuchLifecycleConfiguration(err) {
-			return err
+			return translateError(err, "")
 		}
 		rules = []*s3.LifecycleRule{}
 	} else {
@@ -228,11 +219,11 @@ func (s *S3) AddPolicy(policy common.LifecyclePolicy) error {
 		rule.Expiration = &s3.LifecycleExpiration{
 			Days: aws.Int64(days),
 		}
-	} else if policy.Action == "archive" {
+	} else {
 		rule.Transitions = []*s3.Transition{
 			{
 				Days:         aws.Int64(days),
-				StorageClass: aws.String(s3.TransitionStorageClassGlacier),
+				StorageClass: aws.String(transitionClass),
 			},
 		}
 	}
@@ -240,14 +231,14 @@ func (s *S3) AddPolicy(policy common.LifecyclePolicy) error {
 	rules = append(rules, rule)
 
 	// Put the updated lifecycle configuration
-	_, err = s.svc.PutBucketLifecycleConfiguration(&s3.PutBucketLifecycleConfigurationInput{
+	_, err = s.svc.PutBucketLifecycleConfigurationWithContext(ctx, &s3.PutBucketLifecycleConfigurationInput{
 		Bucket: aws.String(s.bucket),
 		LifecycleConfiguration: &s3.BucketLifecycleConfiguration{
 			Rules: rules,
 		},
 	})
 
-	return err
+	return translateError(err, "")
 }
 
 // RemovePolicy removes a lifecycle policy by updating S3 bucket lifecycle rules.
@@ -255,8 +246,11 @@ func (s *S3) RemovePolicy(id string) error {
 	s.policiesMutex.Lock()
 	defer s.policiesMutex.Unlock()
 
+	ctx, cancel := s.timeouts.Context(context.Background(), transport.OpMetadata)
+	defer cancel()
+
 	// Get existing lifecycle configuration
-	existingConfig, err := s.svc.GetBucketLifecycleConfiguration(&s3.GetBucketLifecycleConfigurationInput{
+	existingConfig, err := s.svc.GetBucketLifecycleConfigurationWithContext(ctx, &s3.GetBucketLifecycleConfigurationInput{
 		Bucket: aws.String(s.bucket),
 	})
 
@@ -265,7 +259,7 @@ func (s *S3) RemovePolicy(id string) error {
 		if isNoSuchLifecycleConfiguration(err) {
 			return nil
 		}
-		return err
+		return translateError(err, "")
 	}
 
 	// Filter out the rule with the given ID
@@ -278,21 +272,21 @@ func (s *S3) RemovePolicy(id string) error {
 
 	// If no rules left, delete the lifecycle configuration entirely
 	if len(rules) == 0 {
-		_, err = s.svc.DeleteBucketLifecycle(&s3.DeleteBucketLifecycleInput{
+		_, err = s.svc.DeleteBucketLifecycleWithContext(ctx, &s3.DeleteBucketLifecycleInput{
 			Bucket: aws.String(s.bucket),
 		})
-		return err
+		return translateError(err, "")
 	}
 
 	// Otherwise, put the updated configuration
-	_, err = s.svc.PutBucketLifecycleConfiguration(&s3.PutBucketLifecycleConfigurationInput{
+	_, err = s.svc.PutBucketLifecycleConfigurationWithContext(ctx, &s3.PutBucketLifecycleConfigurationInput{
 		Bucket: aws.String(s.bucket),
 		LifecycleConfiguration: &s3.BucketLifecycleConfiguration{
 			Rules: rules,
 		},
 	})
 
-	return err
+	return translateError(err, "")
 }
 
 // GetPolicies returns all lifecycle policies by fetching S3 bucket lifecycle rules.
@@ -300,8 +294,11 @@ func (s *S3) GetPolicies() ([]common.LifecyclePolicy, error) {
 	s.policiesMutex.RLock()
 	defer s.policiesMutex.RUnlock()
 
+	ctx, cancel := s.timeouts.Context(context.Background(), transport.OpMetadata)
+	defer cancel()
+
 	// Get lifecycle configuration from S3
-	lifecycleConfig, err := s.svc.GetBucketLifecycleConfiguration(&s3.GetBucketLifecycleConfigurationInput{
+	lifecycleConfig, err := s.svc.GetBucketLifecycleConfigurationWithContext(ctx, &s3.GetBucketLifecycleConfigurationInput{
 		Bucket: aws.String(s.bucket),
 	})
 
@@ -310,7 +307,7 @@ func (s *S3) GetPolicies() ([]common.LifecyclePolicy, error) {
 		if isNoSuchLifecycleConfiguration(err) {
 			return []common.LifecyclePolicy{}, nil
 		}
-		return nil, err
+		return nil, translateError(err, "")
 	}
 
 	// Convert S3 lifecycle rules to common.LifecyclePolicy
@@ -334,8 +331,13 @@ func (s *S3) GetPolicies() ([]common.LifecyclePolicy, error) {
 			policy.Action = "delete"
 			policy.Retention = time.Duration(*rule.Expiration.Days) * 24 * time.Hour
 		} else if len(rule.Transitions) > 0 && rule.Transitions[0].Days != nil {
-			// Use the first transition for archive action
+			// Use the first transition. Transitions to Glacier are how
+			// archive policies are stored; any other class is a transition.
 			policy.Action = "archive"
+			if class := aws.StringValue(rule.Transitions[0].StorageClass); class != s3.TransitionStorageClassGlacier {
+				policy.Action = actionTransition
+				policy.StorageClass = class
+			}
 			policy.Retention = time.Duration(*rule.Transitions[0].Days) * 24 * time.Hour
 		} else {
 			// Skip rules we don't understand
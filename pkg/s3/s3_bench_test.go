@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
 	"github.com/jeremyhahn/go-objstore/pkg/common"
@@ -73,6 +74,22 @@ func (m *benchS3Client) ListObjectsV2(input *s3.ListObjectsV2Input) (*s3.ListObj
 	}, nil
 }
 
+func (m *benchS3Client) PutObjectWithContext(_ aws.Context, input *s3.PutObjectInput, _ ...request.Option) (*s3.PutObjectOutput, error) {
+	return m.PutObject(input)
+}
+
+func (m *benchS3Client) GetObjectWithContext(_ aws.Context, input *s3.GetObjectInput, _ ...request.Option) (*s3.GetObjectOutput, error) {
+	return m.GetObject(input)
+}
+
+func (m *benchS3Client) DeleteObjectWithContext(_ aws.Context, input *s3.DeleteObjectInput, _ ...request.Option) (*s3.DeleteObjectOutput, error) {
+	return m.DeleteObject(input)
+}
+
+func (m *benchS3Client) ListObjectsV2WithContext(_ aws.Context, input *s3.ListObjectsV2Input, _ ...request.Option) (*s3.ListObjectsV2Output, error) {
+	return m.ListObjectsV2(input)
+}
+
 func newMockS3() *S3 {
 	return &S3{
 		svc:    &benchS3Client{objects: make(map[string][]byte)},
@@ -0,0 +1,69 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build awss3
+
+package s3
+
+import (
+	"context"
+	"io"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/transport"
+
+	"github.com/aws/aws-sdk-go/aws"         //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+	"github.com/aws/aws-sdk-go/aws/request" //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+	"github.com/aws/aws-sdk-go/service/s3"  //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+)
+
+// PutIfMatch stores an object only if its current ETag is etag, or only if
+// it does not exist when etag is empty, using S3 conditional writes
+// (If-Match and If-None-Match). The v1 SDK has no fields for them, so the
+// headers are set on the request.
+func (s *S3) PutIfMatch(ctx context.Context, key string, data io.Reader, metadata *common.Metadata, etag string) error {
+	input, err := s.putObjectInput(key, data, metadata)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := s.timeouts.Context(ctx, transport.OpPut)
+	defer cancel()
+	_, err = s.svc.PutObjectWithContext(ctx, input, conditionHeader(etag, true))
+	return translateError(err, key)
+}
+
+// DeleteIfMatch deletes an object only if its current ETag is etag. S3
+// compatible services that ignore If-Match on DeleteObject delete
+// unconditionally.
+func (s *S3) DeleteIfMatch(ctx context.Context, key, etag string) error {
+	if err := common.ValidateKey(key); err != nil {
+		return err
+	}
+	ctx, cancel := s.timeouts.Context(ctx, transport.OpDelete)
+	defer cancel()
+	_, err := s.svc.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	}, conditionHeader(etag, false))
+	return translateError(err, key)
+}
+
+// conditionHeader returns the request option for an ETag precondition. An
+// empty etag requires the object not to exist when absentOK is set.
+func conditionHeader(etag string, absentOK bool) request.Option {
+	if etag == "" && absentOK {
+		return request.WithSetRequestHeaders(map[string]string{"If-None-Match": "*"})
+	}
+	return request.WithSetRequestHeaders(map[string]string{"If-Match": etag})
+}
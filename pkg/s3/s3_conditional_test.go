@@ -0,0 +1,87 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build awss3
+
+package s3
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+
+	"github.com/aws/aws-sdk-go/aws"                //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+	"github.com/aws/aws-sdk-go/aws/awserr"         //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+	"github.com/aws/aws-sdk-go/aws/request"        //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+	"github.com/aws/aws-sdk-go/service/s3"         //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+	"github.com/aws/aws-sdk-go/service/s3/s3iface" //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+)
+
+// conditionalS3Client records the headers request options set.
+type conditionalS3Client struct {
+	s3iface.S3API
+	header http.Header
+	err    error
+}
+
+func (m *conditionalS3Client) apply(opts []request.Option) {
+	req := &request.Request{HTTPRequest: &http.Request{Header: http.Header{}}}
+	req.ApplyOptions(opts...)
+	m.header = req.HTTPRequest.Header
+}
+
+func (m *conditionalS3Client) PutObjectWithContext(_ aws.Context, _ *s3.PutObjectInput, opts ...request.Option) (*s3.PutObjectOutput, error) {
+	m.apply(opts)
+	return &s3.PutObjectOutput{}, m.err
+}
+
+func (m *conditionalS3Client) DeleteObjectWithContext(_ aws.Context, _ *s3.DeleteObjectInput, opts ...request.Option) (*s3.DeleteObjectOutput, error) {
+	m.apply(opts)
+	return &s3.DeleteObjectOutput{}, m.err
+}
+
+func TestConditionalWrites(t *testing.T) {
+	ctx := context.Background()
+	client := &conditionalS3Client{}
+	storage := &S3{svc: client, bucket: "test-bucket"}
+
+	if err := storage.PutIfMatch(ctx, "locks/a", strings.NewReader("x"), nil, ""); err != nil {
+		t.Fatalf("PutIfMatch() error = %v", err)
+	}
+	if got := client.header.Get("If-None-Match"); got != "*" {
+		t.Errorf("If-None-Match = %q, want *", got)
+	}
+
+	if err := storage.PutIfMatch(ctx, "locks/a", strings.NewReader("x"), nil, `"abc"`); err != nil {
+		t.Fatalf("PutIfMatch() error = %v", err)
+	}
+	if got := client.header.Get("If-Match"); got != `"abc"` {
+		t.Errorf("If-Match = %q, want \"abc\"", got)
+	}
+
+	if err := storage.DeleteIfMatch(ctx, "locks/a", `"abc"`); err != nil {
+		t.Fatalf("DeleteIfMatch() error = %v", err)
+	}
+	if got := client.header.Get("If-Match"); got != `"abc"` {
+		t.Errorf("If-Match = %q, want \"abc\"", got)
+	}
+
+	client.err = awserr.NewRequestFailure(awserr.New("PreconditionFailed", "At least one of the pre-conditions you specified did not hold", nil), http.StatusPreconditionFailed, "req")
+	if err := storage.PutIfMatch(ctx, "locks/a", strings.NewReader("x"), nil, ""); !errors.Is(err, common.ErrPreconditionFailed) {
+		t.Errorf("PutIfMatch() error = %v, want ErrPreconditionFailed", err)
+	}
+}
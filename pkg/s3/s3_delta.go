@@ -0,0 +1,263 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build awss3
+
+package s3
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/delta"
+	"github.com/jeremyhahn/go-objstore/pkg/transport"
+
+	"github.com/aws/aws-sdk-go/aws"        //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+	"github.com/aws/aws-sdk-go/service/s3" //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+)
+
+const (
+	// deltaMinPartSize is the S3 minimum size of every part but the last.
+	deltaMinPartSize int64 = 5 * 1024 * 1024
+
+	// deltaMaxCopyPartSize is the S3 maximum size of one UploadPartCopy range.
+	deltaMaxCopyPartSize int64 = 5 * 1024 * 1024 * 1024
+
+	// deltaPartSize is the size at which buffered bytes are uploaded as a part.
+	deltaPartSize int64 = 16 * 1024 * 1024
+)
+
+// ApplyDelta rebuilds the object stored under key as a multipart upload.
+// Unchanged ranges of at least 5 MiB are copied server-side with
+// UploadPartCopy, so only literal ranges (and short unchanged ranges that
+// cannot form a part on their own) are transferred. Every read of the
+// current version is conditioned on baseETag.
+func (s *S3) ApplyDelta(ctx context.Context, key, baseETag string, ops []delta.Op, src delta.Source, metadata *common.Metadata) error {
+	if err := common.ValidateKey(key); err != nil {
+		return err
+	}
+	if len(ops) == 0 {
+		return s.PutWithMetadata(ctx, key, bytes.NewReader(nil), metadata)
+	}
+	ctx, cancel := s.timeouts.Context(ctx, transport.OpPut)
+	defer cancel()
+
+	input := &s3.CreateMultipartUploadInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	}
+	if s.objectLock != nil {
+		input.ObjectLockMode = aws.String(s.objectLock.mode)
+		input.ObjectLockRetainUntilDate = s.objectLock.retainUntil()
+	}
+	if metadata != nil {
+		if metadata.ContentType != "" {
+			input.ContentType = aws.String(metadata.ContentType)
+		}
+		if metadata.ContentEncoding != "" {
+			input.ContentEncoding = aws.String(metadata.ContentEncoding)
+		}
+		if len(metadata.Custom) > 0 {
+			input.Metadata = make(map[string]*string)
+			for k, v := range metadata.Custom {
+				input.Metadata[k] = aws.String(v)
+			}
+		}
+		class, err := storageClass(metadata.StorageClass)
+		if err != nil {
+			return err
+		}
+		input.StorageClass = class
+	}
+	created, err := s.svc.CreateMultipartUploadWithContext(ctx, input)
+	if err != nil {
+		return translateError(err, key)
+	}
+
+	u := &deltaUpload{s: s, ctx: ctx, key: key, baseETag: baseETag, uploadID: created.UploadId, src: src}
+	if err := u.run(ops); err != nil {
+		_, _ = s.svc.AbortMultipartUploadWithContext(ctx, &s3.AbortMultipartUploadInput{
+			Bucket:   aws.String(s.bucket),
+			Key:      aws.String(key),
+			UploadId: created.UploadId,
+		})
+		return err
+	}
+
+	_, err = s.svc.CompleteMultipartUploadWithContext(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(s.bucket),
+		Key:             aws.String(key),
+		UploadId:        created.UploadId,
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: u.parts},
+	})
+	return translateError(err, key)
+}
+
+// deltaUpload assembles the parts of one ApplyDelta call.
+type deltaUpload struct {
+	s        *S3
+	ctx      context.Context
+	key      string
+	baseETag string
+	uploadID *string
+	src      delta.Source
+
+	parts   []*s3.CompletedPart
+	pending bytes.Buffer
+}
+
+// run converts ops into copied and uploaded parts.
+func (u *deltaUpload) run(ops []delta.Op) error {
+	for _, op := range ops {
+		if op.Kind == delta.OpLiteral {
+			if err := u.appendRange(op.Offset, op.Length, u.readLiteral); err != nil {
+				return err
+			}
+			continue
+		}
+
+		off, n := op.Offset, op.Length
+		if n >= deltaMinPartSize && u.pending.Len() > 0 {
+			// Top up the buffered bytes so they form a valid part, then
+			// copy the rest of the range server-side.
+			if need := deltaMinPartSize - int64(u.pending.Len()); need > 0 {
+				if err := u.appendRange(off, need, u.readBase); err != nil {
+					return err
+				}
+				off, n = off+need, n-need
+			}
+			if err := u.flush(); err != nil {
+				return err
+			}
+		}
+		for n >= deltaMinPartSize {
+			size := min(n, deltaMaxCopyPartSize)
+			if err := u.copyPart(off, size); err != nil {
+				return err
+			}
+			off, n = off+size, n-size
+		}
+		if n > 0 {
+			if err := u.appendRange(off, n, u.readBase); err != nil {
+				return err
+			}
+		}
+	}
+	if u.pending.Len() > 0 {
+		return u.flush()
+	}
+	return nil
+}
+
+// appendRange buffers length bytes opened by open, uploading a part each
+// time the buffer reaches deltaPartSize.
+func (u *deltaUpload) appendRange(offset, length int64, open func(offset, length int64) (io.ReadCloser, error)) error {
+	rc, err := open(offset, length)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rc.Close() }()
+
+	for length > 0 {
+		take := min(length, deltaPartSize-int64(u.pending.Len()))
+		if _, err := io.CopyN(&u.pending, rc, take); err != nil {
+			return fmt.Errorf("failed to read delta range at %d: %w", offset, err)
+		}
+		length -= take
+		if int64(u.pending.Len()) >= deltaPartSize {
+			if err := u.flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// readLiteral opens a range of the new version.
+func (u *deltaUpload) readLiteral(offset, length int64) (io.ReadCloser, error) {
+	return u.src(u.ctx, offset, length)
+}
+
+// readBase opens a range of the current version, failing with
+// delta.ErrBaseChanged if it no longer matches baseETag.
+func (u *deltaUpload) readBase(offset, length int64) (io.ReadCloser, error) {
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(u.s.bucket),
+		Key:    aws.String(u.key),
+		Range:  aws.String(common.RangeHeader(offset, length)),
+	}
+	if u.baseETag != "" {
+		input.IfMatch = aws.String(u.baseETag)
+	}
+	result, err := u.s.svc.GetObjectWithContext(u.ctx, input)
+	if err != nil {
+		return nil, deltaError(u.key, err)
+	}
+	return result.Body, nil
+}
+
+// copyPart copies a range of the current version into the next part.
+func (u *deltaUpload) copyPart(offset, length int64) error {
+	input := &s3.UploadPartCopyInput{
+		Bucket:          aws.String(u.s.bucket),
+		Key:             aws.String(u.key),
+		UploadId:        u.uploadID,
+		PartNumber:      aws.Int64(int64(len(u.parts) + 1)),
+		CopySource:      aws.String(u.s.bucket + "/" + u.key),
+		CopySourceRange: aws.String(common.RangeHeader(offset, length)),
+	}
+	if u.baseETag != "" {
+		input.CopySourceIfMatch = aws.String(u.baseETag)
+	}
+	result, err := u.s.svc.UploadPartCopyWithContext(u.ctx, input)
+	if err != nil {
+		return deltaError(u.key, err)
+	}
+	u.parts = append(u.parts, &s3.CompletedPart{
+		ETag:       result.CopyPartResult.ETag,
+		PartNumber: input.PartNumber,
+	})
+	return nil
+}
+
+// flush uploads the buffered bytes as the next part.
+func (u *deltaUpload) flush() error {
+	partNumber := aws.Int64(int64(len(u.parts) + 1))
+	result, err := u.s.svc.UploadPartWithContext(u.ctx, &s3.UploadPartInput{
+		Bucket:     aws.String(u.s.bucket),
+		Key:        aws.String(u.key),
+		UploadId:   u.uploadID,
+		PartNumber: partNumber,
+		Body:       bytes.NewReader(u.pending.Bytes()),
+	})
+	if err != nil {
+		return translateError(err, u.key)
+	}
+	u.pending.Reset()
+	u.parts = append(u.parts, &s3.CompletedPart{ETag: result.ETag, PartNumber: partNumber})
+	return nil
+}
+
+// deltaError maps a failed precondition on the base version to
+// delta.ErrBaseChanged and other errors to the common taxonomy.
+func deltaError(key string, err error) error {
+	translated := translateError(err, key)
+	if errors.Is(translated, common.ErrPreconditionFailed) {
+		return fmt.Errorf("%w: %s: %v", delta.ErrBaseChanged, key, err)
+	}
+	return translated
+}
@@ -0,0 +1,168 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build awss3
+
+package s3
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/delta"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+// multipartS3Client holds one object and assembles multipart uploads of it.
+type multipartS3Client struct {
+	s3iface.S3API
+	object []byte
+	etag   string
+
+	parts     map[int64][]byte
+	copied    int64
+	uploaded  int64
+	aborted   bool
+	completed bool
+}
+
+func parseRange(header string) (int64, int64) {
+	var start, end int64
+	_, _ = fmt.Sscanf(header, "bytes=%d-%d", &start, &end)
+	return start, end
+}
+
+func (m *multipartS3Client) precondition(etag *string) error {
+	if etag != nil && *etag != m.etag {
+		return awserr.New("PreconditionFailed", "At least one of the pre-conditions you specified did not hold", nil)
+	}
+	return nil
+}
+
+func (m *multipartS3Client) CreateMultipartUploadWithContext(aws.Context, *s3.CreateMultipartUploadInput, ...request.Option) (*s3.CreateMultipartUploadOutput, error) {
+	m.parts = make(map[int64][]byte)
+	return &s3.CreateMultipartUploadOutput{UploadId: aws.String("upload-1")}, nil
+}
+
+func (m *multipartS3Client) GetObjectWithContext(_ aws.Context, input *s3.GetObjectInput, _ ...request.Option) (*s3.GetObjectOutput, error) {
+	if err := m.precondition(input.IfMatch); err != nil {
+		return nil, err
+	}
+	start, end := parseRange(aws.StringValue(input.Range))
+	return &s3.GetObjectOutput{Body: io.NopCloser(bytes.NewReader(m.object[start : end+1]))}, nil
+}
+
+func (m *multipartS3Client) UploadPartCopyWithContext(_ aws.Context, input *s3.UploadPartCopyInput, _ ...request.Option) (*s3.UploadPartCopyOutput, error) {
+	if err := m.precondition(input.CopySourceIfMatch); err != nil {
+		return nil, err
+	}
+	start, end := parseRange(aws.StringValue(input.CopySourceRange))
+	m.parts[*input.PartNumber] = m.object[start : end+1]
+	m.copied += end - start + 1
+	return &s3.UploadPartCopyOutput{CopyPartResult: &s3.CopyPartResult{ETag: aws.String("copy")}}, nil
+}
+
+func (m *multipartS3Client) UploadPartWithContext(_ aws.Context, input *s3.UploadPartInput, _ ...request.Option) (*s3.UploadPartOutput, error) {
+	data, err := io.ReadAll(input.Body)
+	if err != nil {
+		return nil, err
+	}
+	m.parts[*input.PartNumber] = data
+	m.uploaded += int64(len(data))
+	return &s3.UploadPartOutput{ETag: aws.String("upload")}, nil
+}
+
+func (m *multipartS3Client) CompleteMultipartUploadWithContext(_ aws.Context, input *s3.CompleteMultipartUploadInput, _ ...request.Option) (*s3.CompleteMultipartUploadOutput, error) {
+	var numbers []int64
+	for _, p := range input.MultipartUpload.Parts {
+		numbers = append(numbers, *p.PartNumber)
+	}
+	sort.Slice(numbers, func(i, j int) bool { return numbers[i] < numbers[j] })
+
+	var assembled []byte
+	for i, n := range numbers {
+		if i < len(numbers)-1 && int64(len(m.parts[n])) < deltaMinPartSize {
+			return nil, awserr.New("EntityTooSmall", "part too small", nil)
+		}
+		assembled = append(assembled, m.parts[n]...)
+	}
+	m.object = assembled
+	m.completed = true
+	return &s3.CompleteMultipartUploadOutput{}, nil
+}
+
+func (m *multipartS3Client) AbortMultipartUploadWithContext(aws.Context, *s3.AbortMultipartUploadInput, ...request.Option) (*s3.AbortMultipartUploadOutput, error) {
+	m.aborted = true
+	return &s3.AbortMultipartUploadOutput{}, nil
+}
+
+func TestS3_ApplyDelta(t *testing.T) {
+	const mib = 1024 * 1024
+	base := make([]byte, 12*mib)
+	rand.New(rand.NewSource(1)).Read(base)
+	changed := append([]byte{}, base[:6*mib]...)
+	changed = append(changed, []byte("inserted")...)
+	changed = append(changed, base[6*mib:]...)
+
+	client := &multipartS3Client{object: append([]byte{}, base...), etag: `"v1"`}
+	s := &S3{svc: client, bucket: "test-bucket"}
+
+	sig, err := delta.NewSignature(bytes.NewReader(base), mib)
+	if err != nil {
+		t.Fatalf("NewSignature: %v", err)
+	}
+	ops, err := delta.Diff(sig, bytes.NewReader(changed))
+	if err != nil {
+		t.Fatalf("Diff: %v", err)
+	}
+
+	err = s.ApplyDelta(context.Background(), "big.bin", `"v1"`, ops, delta.BytesSource(changed), nil)
+	if err != nil {
+		t.Fatalf("ApplyDelta: %v", err)
+	}
+	if !client.completed || !bytes.Equal(client.object, changed) {
+		t.Fatalf("rebuilt object does not match the new version")
+	}
+	if client.copied < 5*mib {
+		t.Errorf("expected unchanged ranges to be copied server-side, copied %d bytes", client.copied)
+	}
+	if client.copied+client.uploaded != int64(len(changed)) {
+		t.Errorf("copied %d + uploaded %d != %d", client.copied, client.uploaded, len(changed))
+	}
+}
+
+func TestS3_ApplyDelta_BaseChanged(t *testing.T) {
+	data := bytes.Repeat([]byte("x"), 6*1024*1024)
+	client := &multipartS3Client{object: data, etag: `"v2"`}
+	s := &S3{svc: client, bucket: "test-bucket"}
+
+	ops := []delta.Op{{Kind: delta.OpCopy, Offset: 0, Length: int64(len(data))}}
+	err := s.ApplyDelta(context.Background(), "big.bin", `"v1"`, ops, delta.BytesSource(nil), nil)
+	if !errors.Is(err, delta.ErrBaseChanged) {
+		t.Fatalf("expected ErrBaseChanged, got %v", err)
+	}
+	if !client.aborted || client.completed {
+		t.Error("expected the multipart upload to be aborted")
+	}
+}
@@ -17,11 +17,14 @@ package s3
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
-	"strings"
+	"maps"
 	"time"
 
 	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/transport"
 
 	"github.com/aws/aws-sdk-go/aws"        //nolint:staticcheck // Using v1 SDK, migration to v2 planned
 	"github.com/aws/aws-sdk-go/service/s3" //nolint:staticcheck // Using v1 SDK, migration to v2 planned
@@ -34,14 +37,108 @@ func (s *S3) PutWithContext(ctx context.Context, key string, data io.Reader) err
 
 // PutWithMetadata stores an object with associated metadata.
 func (s *S3) PutWithMetadata(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) error {
-	if err := common.ValidateKey(key); err != nil {
+	input, err := s.putObjectInput(key, data, metadata)
+	if err != nil {
 		return err
 	}
+
+	ctx, cancel := s.timeouts.Context(ctx, transport.OpPut)
+	defer cancel()
+	_, err = s.svc.PutObjectWithContext(ctx, input)
+	return translateError(err, key)
+}
+
+// PutWithInfo stores an object with associated metadata and returns its
+// ETag and version ID as reported by S3, with the size written. S3 does
+// not report the modification time of a write; the time the write
+// completed is returned instead.
+func (s *S3) PutWithInfo(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) (*common.ObjectInfo, error) {
+	body := trackPosition(data)
+	input, err := s.putObjectInput(key, body, metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := s.timeouts.Context(ctx, transport.OpPut)
+	defer cancel()
+	out, err := s.svc.PutObjectWithContext(ctx, input)
+	if err != nil {
+		return nil, translateError(err, key)
+	}
+
+	info := &common.Metadata{
+		Size:         body.position(),
+		LastModified: time.Now().UTC(),
+		ETag:         aws.StringValue(out.ETag),
+		Version:      aws.StringValue(out.VersionId),
+	}
+	if metadata != nil {
+		info.ContentType = metadata.ContentType
+		info.ContentEncoding = metadata.ContentEncoding
+		info.StorageClass = metadata.StorageClass
+		info.Custom = maps.Clone(metadata.Custom)
+	}
+	return &common.ObjectInfo{Key: key, Metadata: info}, nil
+}
+
+// positionReader tracks how far its reader has been read.
+type positionReader struct {
+	r   io.Reader
+	pos int64
+}
+
+func (p *positionReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.pos += int64(n)
+	return n, err
+}
+
+func (p *positionReader) position() int64 {
+	return p.pos
+}
+
+// positionReadSeeker is a positionReader that follows the seeks the SDK
+// makes to size and retry a request body.
+type positionReadSeeker struct {
+	*positionReader
+	s io.Seeker
+}
+
+func (p *positionReadSeeker) Seek(offset int64, whence int) (int64, error) {
+	pos, err := p.s.Seek(offset, whence)
+	if err == nil {
+		p.pos = pos
+	}
+	return pos, err
+}
+
+// trackPosition wraps data to report how far it was read, keeping it
+// seekable if it is, so the size of a completed upload is its position.
+func trackPosition(data io.Reader) interface {
+	io.Reader
+	position() int64
+} {
+	p := &positionReader{r: data}
+	if s, ok := data.(io.Seeker); ok {
+		return &positionReadSeeker{positionReader: p, s: s}
+	}
+	return p
+}
+
+// putObjectInput builds the PutObject request for key.
+func (s *S3) putObjectInput(key string, data io.Reader, metadata *common.Metadata) (*s3.PutObjectInput, error) {
+	if err := common.ValidateKey(key); err != nil {
+		return nil, err
+	}
 	input := &s3.PutObjectInput{
 		Bucket: aws.String(s.bucket),
 		Key:    aws.String(key),
 		Body:   aws.ReadSeekCloser(data),
 	}
+	if s.objectLock != nil {
+		input.ObjectLockMode = aws.String(s.objectLock.mode)
+		input.ObjectLockRetainUntilDate = s.objectLock.retainUntil()
+	}
 
 	// Add metadata if provided
 	if metadata != nil {
@@ -57,10 +154,13 @@ func (s *S3) PutWithMetadata(ctx context.Context, key string, data io.Reader, me
 				input.Metadata[k] = aws.String(v)
 			}
 		}
+		class, err := storageClass(metadata.StorageClass)
+		if err != nil {
+			return nil, err
+		}
+		input.StorageClass = class
 	}
-
-	_, err := s.svc.PutObjectWithContext(ctx, input)
-	return err
+	return input, nil
 }
 
 // GetWithContext retrieves an object from the backend with context support.
@@ -68,14 +168,38 @@ func (s *S3) GetWithContext(ctx context.Context, key string) (io.ReadCloser, err
 	if err := common.ValidateKey(key); err != nil {
 		return nil, err
 	}
+	ctx, cancel := s.timeouts.Context(ctx, transport.OpGet)
 	result, err := s.svc.GetObjectWithContext(ctx, &s3.GetObjectInput{
 		Bucket: aws.String(s.bucket),
 		Key:    aws.String(key),
 	})
 	if err != nil {
+		cancel()
+		return nil, translateError(err, key)
+	}
+	return transport.CancelOnClose(result.Body, cancel), nil
+}
+
+// GetRange retrieves length bytes of an object starting at offset using an
+// HTTP Range request. A negative length reads to the end of the object.
+func (s *S3) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	if err := common.ValidateKey(key); err != nil {
 		return nil, err
 	}
-	return result.Body, nil
+	if offset < 0 {
+		return nil, fmt.Errorf("%w: negative range offset %d", common.ErrInvalidArgument, offset)
+	}
+	ctx, cancel := s.timeouts.Context(ctx, transport.OpGet)
+	result, err := s.svc.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+		Range:  aws.String(common.RangeHeader(offset, length)),
+	})
+	if err != nil {
+		cancel()
+		return nil, translateError(err, key)
+	}
+	return transport.CancelOnClose(result.Body, cancel), nil
 }
 
 // GetMetadata retrieves only the metadata for an object.
@@ -83,12 +207,14 @@ func (s *S3) GetMetadata(ctx context.Context, key string) (*common.Metadata, err
 	if err := common.ValidateKey(key); err != nil {
 		return nil, err
 	}
+	ctx, cancel := s.timeouts.Context(ctx, transport.OpMetadata)
+	defer cancel()
 	result, err := s.svc.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
 		Bucket: aws.String(s.bucket),
 		Key:    aws.String(key),
 	})
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, key)
 	}
 
 	metadata := &common.Metadata{
@@ -103,6 +229,11 @@ func (s *S3) GetMetadata(ctx context.Context, key string) (*common.Metadata, err
 	if result.ContentEncoding != nil {
 		metadata.ContentEncoding = aws.StringValue(result.ContentEncoding)
 	}
+	// S3 omits the storage class header for STANDARD objects.
+	metadata.StorageClass = aws.StringValue(result.StorageClass)
+	if metadata.StorageClass == "" {
+		metadata.StorageClass = s3.StorageClassStandard
+	}
 
 	// Convert S3 metadata to custom metadata
 	if len(result.Metadata) > 0 {
@@ -139,10 +270,17 @@ func (s *S3) UpdateMetadata(ctx context.Context, key string, metadata *common.Me
 				input.Metadata[k] = aws.String(v)
 			}
 		}
+		class, err := storageClass(metadata.StorageClass)
+		if err != nil {
+			return err
+		}
+		input.StorageClass = class
 	}
 
+	ctx, cancel := s.timeouts.Context(ctx, transport.OpMetadata)
+	defer cancel()
 	_, err := s.svc.CopyObjectWithContext(ctx, input)
-	return err
+	return translateError(err, key)
 }
 
 // DeleteWithContext removes an object from the backend with context support.
@@ -150,11 +288,13 @@ func (s *S3) DeleteWithContext(ctx context.Context, key string) error {
 	if err := common.ValidateKey(key); err != nil {
 		return err
 	}
+	ctx, cancel := s.timeouts.Context(ctx, transport.OpDelete)
+	defer cancel()
 	_, err := s.svc.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
 		Bucket: aws.String(s.bucket),
 		Key:    aws.String(key),
 	})
-	return err
+	return translateError(err, key)
 }
 
 // Exists checks if an object exists in the backend.
@@ -162,12 +302,14 @@ func (s *S3) Exists(ctx context.Context, key string) (bool, error) {
 	if err := common.ValidateKey(key); err != nil {
 		return false, err
 	}
+	ctx, cancel := s.timeouts.Context(ctx, transport.OpMetadata)
+	defer cancel()
 	_, err := s.svc.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
 		Bucket: aws.String(s.bucket),
 		Key:    aws.String(key),
 	})
 	if err != nil {
-		if strings.Contains(err.Error(), "NotFound") {
+		if err = translateError(err, key); errors.Is(err, common.ErrNotFound) {
 			return false, nil
 		}
 		return false, err
@@ -177,6 +319,9 @@ func (s *S3) Exists(ctx context.Context, key string) (bool, error) {
 
 // ListWithContext returns a list of keys with context support.
 func (s *S3) ListWithContext(ctx context.Context, prefix string) ([]string, error) {
+	ctx, cancel := s.timeouts.Context(ctx, transport.OpList)
+	defer cancel()
+
 	var keys []string
 	var continuationToken *string
 
@@ -192,7 +337,7 @@ func (s *S3) ListWithContext(ctx context.Context, prefix string) ([]string, erro
 
 		result, err := s.svc.ListObjectsV2WithContext(ctx, input)
 		if err != nil {
-			return nil, err
+			return nil, translateError(err, "")
 		}
 
 		for _, obj := range result.Contents {
@@ -234,9 +379,11 @@ func (s *S3) ListWithOptions(ctx context.Context, opts *common.ListOptions) (*co
 		input.ContinuationToken = aws.String(opts.ContinueFrom)
 	}
 
+	ctx, cancel := s.timeouts.Context(ctx, transport.OpList)
+	defer cancel()
 	result, err := s.svc.ListObjectsV2WithContext(ctx, input)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "")
 	}
 
 	listResult := &common.ListResult{
@@ -252,8 +399,9 @@ func (s *S3) ListWithOptions(ctx context.Context, opts *common.ListOptions) (*co
 		}
 
 		metadata := &common.Metadata{
-			Size: aws.Int64Value(obj.Size),
-			ETag: aws.StringValue(obj.ETag),
+			Size:         aws.Int64Value(obj.Size),
+			ETag:         aws.StringValue(obj.ETag),
+			StorageClass: aws.StringValue(obj.StorageClass),
 		}
 		if obj.LastModified != nil {
 			metadata.LastModified = *obj.LastModified
@@ -282,3 +430,13 @@ func (s *S3) ListWithOptions(ctx context.Context, opts *common.ListOptions) (*co
 
 	return listResult, nil
 }
+
+// storageClass validates an S3 storage class, returning nil for the bucket
+// default.
+func storageClass(class string) (*string, error) {
+	class, err := common.NormalizeStorageClass(class, s3.StorageClass_Values())
+	if err != nil || class == "" {
+		return nil, err
+	}
+	return aws.String(class), nil
+}
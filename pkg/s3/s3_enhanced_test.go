@@ -26,15 +26,21 @@ import (
 	"github.com/jeremyhahn/go-objstore/pkg/common"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/s3"
 )
 
 // Extended mock to support context methods
 func (m *mockS3Client) PutObjectWithContext(ctx aws.Context, input *s3.PutObjectInput, opts ...request.Option) (*s3.PutObjectOutput, error) {
+	m.lastPutObjectInput = input
 	if m.putObjectError != nil {
 		return nil, m.putObjectError
 	}
+	// Consume the body as the SDK does when it sends the request
+	if input.Body != nil {
+		_, _ = io.Copy(io.Discard, input.Body)
+	}
 	return m.putObjectOutput, nil
 }
 
@@ -78,6 +84,18 @@ func (m *mockS3Client) ListObjectsV2WithContext(ctx aws.Context, input *s3.ListO
 	return m.listObjectsV2Output, nil
 }
 
+func (m *mockS3Client) GetBucketLifecycleConfigurationWithContext(ctx aws.Context, input *s3.GetBucketLifecycleConfigurationInput, opts ...request.Option) (*s3.GetBucketLifecycleConfigurationOutput, error) {
+	return m.GetBucketLifecycleConfiguration(input)
+}
+
+func (m *mockS3Client) PutBucketLifecycleConfigurationWithContext(ctx aws.Context, input *s3.PutBucketLifecycleConfigurationInput, opts ...request.Option) (*s3.PutBucketLifecycleConfigurationOutput, error) {
+	return m.PutBucketLifecycleConfiguration(input)
+}
+
+func (m *mockS3Client) DeleteBucketLifecycleWithContext(ctx aws.Context, input *s3.DeleteBucketLifecycleInput, opts ...request.Option) (*s3.DeleteBucketLifecycleOutput, error) {
+	return m.DeleteBucketLifecycle(input)
+}
+
 // TestS3_PutWithContext tests the context-aware Put method
 func TestS3_PutWithContext(t *testing.T) {
 	mockS3 := &mockS3Client{
@@ -131,6 +149,49 @@ func TestS3_PutWithMetadata(t *testing.T) {
 	}
 }
 
+func TestS3_PutWithInfo(t *testing.T) {
+	mockS3 := &mockS3Client{
+		putObjectOutput: &s3.PutObjectOutput{ETag: aws.String(`"abc123"`), VersionId: aws.String("v2")},
+	}
+	s := &S3{svc: mockS3, bucket: "test-bucket"}
+
+	metadata := &common.Metadata{ContentType: "text/plain", Custom: map[string]string{"author": "test"}}
+	info, err := s.PutWithInfo(context.Background(), "key", bytes.NewReader([]byte("data")), metadata)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if info.Key != "key" || info.Metadata.Size != 4 || info.Metadata.ETag != `"abc123"` || info.Metadata.Version != "v2" {
+		t.Fatalf("unexpected info: %+v %+v", info, info.Metadata)
+	}
+	if info.Metadata.ContentType != "text/plain" || info.Metadata.Custom["author"] != "test" || info.Metadata.LastModified.IsZero() {
+		t.Fatalf("unexpected metadata: %+v", info.Metadata)
+	}
+
+	mockS3.putObjectError = errors.New("boom")
+	if _, err := s.PutWithInfo(context.Background(), "key", bytes.NewReader([]byte("data")), nil); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestS3_PutWithMetadata_StorageClass(t *testing.T) {
+	mockS3 := &mockS3Client{putObjectOutput: &s3.PutObjectOutput{}}
+	s := &S3{svc: mockS3, bucket: "test-bucket"}
+
+	metadata := &common.Metadata{StorageClass: "glacier_ir"}
+	if err := s.PutWithMetadata(context.Background(), "key", bytes.NewReader([]byte("data")), metadata); err != nil {
+		t.Fatal(err)
+	}
+	if got := aws.StringValue(mockS3.lastPutObjectInput.StorageClass); got != s3.StorageClassGlacierIr {
+		t.Fatalf("expected storage class GLACIER_IR, got %q", got)
+	}
+
+	metadata = &common.Metadata{StorageClass: "COOL"}
+	err := s.PutWithMetadata(context.Background(), "key", bytes.NewReader([]byte("data")), metadata)
+	if !errors.Is(err, common.ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument, got %v", err)
+	}
+}
+
 func TestS3_PutWithMetadata_NilMetadata(t *testing.T) {
 	mockS3 := &mockS3Client{
 		putObjectOutput: &s3.PutObjectOutput{},
@@ -392,7 +453,7 @@ func TestS3_Exists(t *testing.T) {
 
 func TestS3_Exists_NotFound(t *testing.T) {
 	mockS3 := &mockS3Client{
-		headObjectError: errors.New("NotFound"),
+		headObjectError: awserr.NewRequestFailure(awserr.New("NotFound", "Not Found", nil), 404, "req-1"),
 	}
 
 	s := &S3{svc: mockS3, bucket: "test-bucket"}
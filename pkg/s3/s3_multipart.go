@@ -0,0 +1,105 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build awss3
+
+package s3
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/transport"
+
+	"github.com/aws/aws-sdk-go/aws"        //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+	"github.com/aws/aws-sdk-go/service/s3" //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+)
+
+// ListMultipartUploads returns the unfinished multipart uploads of keys
+// starting with prefix, with the size of the parts each has stored.
+func (s *S3) ListMultipartUploads(ctx context.Context, prefix string) ([]common.MultipartUpload, error) {
+	ctx, cancel := s.timeouts.Context(ctx, transport.OpList)
+	defer cancel()
+
+	var uploads []common.MultipartUpload
+	input := &s3.ListMultipartUploadsInput{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(prefix),
+	}
+	for {
+		result, err := s.svc.ListMultipartUploadsWithContext(ctx, input)
+		if err != nil {
+			return nil, translateError(err, "")
+		}
+		for _, u := range result.Uploads {
+			upload := common.MultipartUpload{
+				Key:       aws.StringValue(u.Key),
+				UploadID:  aws.StringValue(u.UploadId),
+				Initiated: aws.TimeValue(u.Initiated),
+			}
+			size, err := s.partsSize(ctx, upload.Key, upload.UploadID)
+			if errors.Is(err, common.ErrNotFound) {
+				// Completed or aborted since it was listed.
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			upload.Size = size
+			uploads = append(uploads, upload)
+		}
+		if !aws.BoolValue(result.IsTruncated) {
+			break
+		}
+		input.KeyMarker = result.NextKeyMarker
+		input.UploadIdMarker = result.NextUploadIdMarker
+	}
+	return uploads, nil
+}
+
+// partsSize returns the total size of the parts of an upload.
+func (s *S3) partsSize(ctx context.Context, key, uploadID string) (int64, error) {
+	var size int64
+	input := &s3.ListPartsInput{
+		Bucket:   aws.String(s.bucket),
+		Key:      aws.String(key),
+		UploadId: aws.String(uploadID),
+	}
+	for {
+		result, err := s.svc.ListPartsWithContext(ctx, input)
+		if err != nil {
+			return 0, translateError(err, key)
+		}
+		for _, part := range result.Parts {
+			size += aws.Int64Value(part.Size)
+		}
+		if !aws.BoolValue(result.IsTruncated) {
+			return size, nil
+		}
+		input.PartNumberMarker = result.NextPartNumberMarker
+	}
+}
+
+// AbortMultipartUpload aborts an upload and deletes its parts.
+func (s *S3) AbortMultipartUpload(ctx context.Context, key, uploadID string) error {
+	ctx, cancel := s.timeouts.Context(ctx, transport.OpDelete)
+	defer cancel()
+
+	_, err := s.svc.AbortMultipartUploadWithContext(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(s.bucket),
+		Key:      aws.String(key),
+		UploadId: aws.String(uploadID),
+	})
+	return translateError(err, key)
+}
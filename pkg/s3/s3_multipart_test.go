@@ -0,0 +1,120 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build awss3
+
+package s3
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+// pendingUploadsS3Client holds unfinished multipart uploads, listed one
+// per page.
+type pendingUploadsS3Client struct {
+	s3iface.S3API
+	uploads []*s3.MultipartUpload
+	parts   map[string][]int64
+	aborted []string
+}
+
+func (m *pendingUploadsS3Client) ListMultipartUploadsWithContext(_ aws.Context, input *s3.ListMultipartUploadsInput, _ ...request.Option) (*s3.ListMultipartUploadsOutput, error) {
+	i := 0
+	if input.UploadIdMarker != nil {
+		for i < len(m.uploads) && aws.StringValue(m.uploads[i].UploadId) != *input.UploadIdMarker {
+			i++
+		}
+		i++
+	}
+	out := &s3.ListMultipartUploadsOutput{IsTruncated: aws.Bool(i+1 < len(m.uploads))}
+	if i < len(m.uploads) {
+		out.Uploads = m.uploads[i : i+1]
+		out.NextKeyMarker = m.uploads[i].Key
+		out.NextUploadIdMarker = m.uploads[i].UploadId
+	}
+	return out, nil
+}
+
+func (m *pendingUploadsS3Client) ListPartsWithContext(_ aws.Context, input *s3.ListPartsInput, _ ...request.Option) (*s3.ListPartsOutput, error) {
+	sizes, ok := m.parts[aws.StringValue(input.UploadId)]
+	if !ok {
+		return nil, awserr.New(s3.ErrCodeNoSuchUpload, "The specified upload does not exist", nil)
+	}
+	out := &s3.ListPartsOutput{IsTruncated: aws.Bool(false)}
+	for i, size := range sizes {
+		out.Parts = append(out.Parts, &s3.Part{PartNumber: aws.Int64(int64(i + 1)), Size: aws.Int64(size)})
+	}
+	return out, nil
+}
+
+func (m *pendingUploadsS3Client) AbortMultipartUploadWithContext(_ aws.Context, input *s3.AbortMultipartUploadInput, _ ...request.Option) (*s3.AbortMultipartUploadOutput, error) {
+	if _, ok := m.parts[aws.StringValue(input.UploadId)]; !ok {
+		return nil, awserr.New(s3.ErrCodeNoSuchUpload, "The specified upload does not exist", nil)
+	}
+	delete(m.parts, aws.StringValue(input.UploadId))
+	m.aborted = append(m.aborted, aws.StringValue(input.Key))
+	return &s3.AbortMultipartUploadOutput{}, nil
+}
+
+func TestMultipartUploads(t *testing.T) {
+	initiated := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	client := &pendingUploadsS3Client{
+		uploads: []*s3.MultipartUpload{
+			{Key: aws.String("big.bin"), UploadId: aws.String("u1"), Initiated: aws.Time(initiated)},
+			{Key: aws.String("done.bin"), UploadId: aws.String("u2"), Initiated: aws.Time(initiated)},
+			{Key: aws.String("huge.bin"), UploadId: aws.String("u3"), Initiated: aws.Time(initiated)},
+		},
+		// u2 completes between the listing of uploads and of its parts.
+		parts: map[string][]int64{"u1": {5 << 20, 1024}, "u3": {5 << 20}},
+	}
+	s := &S3{svc: client, bucket: "bucket"}
+	var _ common.MultipartAborter = s
+
+	uploads, err := s.ListMultipartUploads(context.Background(), "")
+	if err != nil {
+		t.Fatalf("ListMultipartUploads() error = %v", err)
+	}
+	want := []common.MultipartUpload{
+		{Key: "big.bin", UploadID: "u1", Initiated: initiated, Size: 5<<20 + 1024},
+		{Key: "huge.bin", UploadID: "u3", Initiated: initiated, Size: 5 << 20},
+	}
+	if len(uploads) != len(want) {
+		t.Fatalf("ListMultipartUploads() = %+v, want %+v", uploads, want)
+	}
+	for i := range want {
+		if uploads[i] != want[i] {
+			t.Errorf("upload %d = %+v, want %+v", i, uploads[i], want[i])
+		}
+	}
+
+	if err := s.AbortMultipartUpload(context.Background(), "big.bin", "u1"); err != nil {
+		t.Fatalf("AbortMultipartUpload() error = %v", err)
+	}
+	if err := s.AbortMultipartUpload(context.Background(), "big.bin", "u1"); !errors.Is(err, common.ErrNotFound) {
+		t.Errorf("AbortMultipartUpload() of an aborted upload error = %v, want ErrNotFound", err)
+	}
+	if len(client.aborted) != 1 || client.aborted[0] != "big.bin" {
+		t.Errorf("aborted = %v", client.aborted)
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build awss3
+
+package s3
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+
+	"github.com/aws/aws-sdk-go/aws"        //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+	"github.com/aws/aws-sdk-go/service/s3" //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+)
+
+// objectLock is the S3 Object Lock retention applied to every object the
+// backend writes, configured with the objectLockMode (GOVERNANCE or
+// COMPLIANCE) and objectLockDays settings. The bucket must have been created
+// with Object Lock enabled.
+type objectLock struct {
+	mode string
+	days int
+}
+
+// parseObjectLock reads the object lock settings. Both are optional, but
+// each requires the other.
+func parseObjectLock(settings map[string]string) (*objectLock, error) {
+	mode := strings.ToUpper(settings["objectLockMode"])
+	days := settings["objectLockDays"]
+	if mode == "" && days == "" {
+		return nil, nil
+	}
+	if mode != s3.ObjectLockModeGovernance && mode != s3.ObjectLockModeCompliance {
+		return nil, fmt.Errorf("%w: objectLockMode must be GOVERNANCE or COMPLIANCE", common.ErrInvalidArgument)
+	}
+	n, err := strconv.Atoi(days)
+	if err != nil || n <= 0 {
+		return nil, fmt.Errorf("%w: objectLockDays must be a positive number of days", common.ErrInvalidArgument)
+	}
+	return &objectLock{mode: mode, days: n}, nil
+}
+
+// retainUntil returns the retention date for an object written now.
+func (l *objectLock) retainUntil() *time.Time {
+	return aws.Time(time.Now().UTC().AddDate(0, 0, l.days))
+}
@@ -54,6 +54,7 @@ type mockS3Client struct {
 	listObjectsV2Calls                    int
 	listObjectsV2Outputs                  []*s3.ListObjectsV2Output
 	lifecycleConfig                       *s3.BucketLifecycleConfiguration
+	lastPutObjectInput                    *s3.PutObjectInput
 }
 
 func (m *mockS3Client) PutObject(input *s3.PutObjectInput) (*s3.PutObjectOutput, error) {
@@ -415,6 +416,51 @@ func TestS3_AddPolicy_Archive(t *testing.T) {
 	}
 }
 
+func TestS3_AddPolicy_Transition(t *testing.T) {
+	mockS3 := &mockS3Client{
+		putBucketLifecycleConfigurationOutput: &s3.PutBucketLifecycleConfigurationOutput{},
+		getBucketLifecycleConfigurationError:  errors.New("NoSuchLifecycleConfiguration"),
+	}
+
+	s := &S3{svc: mockS3, bucket: "test-bucket"}
+	policy := common.LifecyclePolicy{
+		ID:           "ia-policy",
+		Prefix:       "reports/",
+		Retention:    30 * 24 * time.Hour,
+		Action:       common.ActionTransition,
+		StorageClass: "standard_ia",
+	}
+	if err := s.AddPolicy(policy); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	transition := mockS3.lifecycleConfig.Rules[0].Transitions[0]
+	if aws.StringValue(transition.StorageClass) != s3.TransitionStorageClassStandardIa {
+		t.Fatalf("expected storage class STANDARD_IA, got %v", transition.StorageClass)
+	}
+
+	policies, err := s.GetPolicies()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(policies) != 1 {
+		t.Fatalf("expected 1 policy, got %d", len(policies))
+	}
+	if policies[0].Action != common.ActionTransition || policies[0].StorageClass != s3.TransitionStorageClassStandardIa {
+		t.Fatalf("expected transition to STANDARD_IA, got %s %s", policies[0].Action, policies[0].StorageClass)
+	}
+}
+
+func TestS3_AddPolicy_TransitionInvalidClass(t *testing.T) {
+	s := &S3{svc: &mockS3Client{}, bucket: "test-bucket"}
+	for _, class := range []string{"", "NEARLINE"} {
+		policy := common.LifecyclePolicy{ID: "p", Action: common.ActionTransition, StorageClass: class}
+		if err := s.AddPolicy(policy); !errors.Is(err, common.ErrInvalidArgument) {
+			t.Fatalf("storage class %q: expected ErrInvalidArgument, got %v", class, err)
+		}
+	}
+}
+
 func TestS3_AddPolicy_InvalidID(t *testing.T) {
 	mockS3 := &mockS3Client{}
 	s := &S3{svc: mockS3, bucket: "test-bucket"}
@@ -0,0 +1,131 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build awss3
+
+package s3
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/transport"
+
+	"github.com/aws/aws-sdk-go/aws"        //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+	"github.com/aws/aws-sdk-go/service/s3" //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+)
+
+// GetAsOf reads the version of key that was current at at from a bucket
+// with versioning enabled. Versions and delete markers of the key are
+// listed and the newest one written at or before at is chosen; if that is
+// a delete marker, or there is none, the key did not exist then.
+func (s *S3) GetAsOf(ctx context.Context, key string, at time.Time) (io.ReadCloser, *common.Metadata, error) {
+	if err := common.ValidateKey(key); err != nil {
+		return nil, nil, err
+	}
+
+	versionID, err := s.versionAsOf(ctx, key, at)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := s.timeouts.Context(ctx, transport.OpGet)
+	result, err := s.svc.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket:    aws.String(s.bucket),
+		Key:       aws.String(key),
+		VersionId: aws.String(versionID),
+	})
+	if err != nil {
+		cancel()
+		return nil, nil, translateError(err, key)
+	}
+	return transport.CancelOnClose(result.Body, cancel), versionMetadata(result), nil
+}
+
+// versionAsOf returns the ID of the version of key current at at.
+func (s *S3) versionAsOf(ctx context.Context, key string, at time.Time) (string, error) {
+	ctx, cancel := s.timeouts.Context(ctx, transport.OpList)
+	defer cancel()
+
+	var (
+		found     bool
+		versionID string
+		deleted   bool
+		newest    time.Time
+	)
+	// S3 lists the versions of a key newest first, so on equal times the
+	// first one seen wins.
+	consider := func(k, id *string, modified *time.Time, marker bool) {
+		t := aws.TimeValue(modified)
+		if aws.StringValue(k) != key || t.After(at) {
+			return
+		}
+		if !found || t.After(newest) {
+			found, versionID, deleted, newest = true, aws.StringValue(id), marker, t
+		}
+	}
+
+	input := &s3.ListObjectVersionsInput{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(key),
+	}
+	for {
+		result, err := s.svc.ListObjectVersionsWithContext(ctx, input)
+		if err != nil {
+			return "", translateError(err, key)
+		}
+		for _, v := range result.Versions {
+			consider(v.Key, v.VersionId, v.LastModified, false)
+		}
+		for _, m := range result.DeleteMarkers {
+			consider(m.Key, m.VersionId, m.LastModified, true)
+		}
+		if !aws.BoolValue(result.IsTruncated) {
+			break
+		}
+		input.KeyMarker = result.NextKeyMarker
+		input.VersionIdMarker = result.NextVersionIdMarker
+	}
+
+	if !found || deleted {
+		return "", fmt.Errorf("%w: %s as of %s", common.ErrKeyNotFound, key, at.Format(time.RFC3339))
+	}
+	return versionID, nil
+}
+
+// versionMetadata converts the response to a versioned GetObject.
+func versionMetadata(result *s3.GetObjectOutput) *common.Metadata {
+	metadata := &common.Metadata{
+		Size:            aws.Int64Value(result.ContentLength),
+		LastModified:    aws.TimeValue(result.LastModified),
+		ETag:            aws.StringValue(result.ETag),
+		ContentType:     aws.StringValue(result.ContentType),
+		ContentEncoding: aws.StringValue(result.ContentEncoding),
+		StorageClass:    aws.StringValue(result.StorageClass),
+	}
+	if metadata.StorageClass == "" {
+		metadata.StorageClass = s3.StorageClassStandard
+	}
+	if len(result.Metadata) > 0 {
+		metadata.Custom = make(map[string]string, len(result.Metadata))
+		for k, v := range result.Metadata {
+			if v != nil {
+				metadata.Custom[k] = *v
+			}
+		}
+	}
+	return metadata
+}
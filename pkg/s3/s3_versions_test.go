@@ -0,0 +1,98 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build awss3
+
+package s3
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+
+	"github.com/aws/aws-sdk-go/aws"                //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+	"github.com/aws/aws-sdk-go/aws/request"        //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+	"github.com/aws/aws-sdk-go/service/s3"         //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+	"github.com/aws/aws-sdk-go/service/s3/s3iface" //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+)
+
+// versionedS3Client serves a fixed version history of one key.
+type versionedS3Client struct {
+	s3iface.S3API
+	output *s3.ListObjectVersionsOutput
+	got    string
+}
+
+func (m *versionedS3Client) ListObjectVersionsWithContext(_ aws.Context, _ *s3.ListObjectVersionsInput, _ ...request.Option) (*s3.ListObjectVersionsOutput, error) {
+	return m.output, nil
+}
+
+func (m *versionedS3Client) GetObjectWithContext(_ aws.Context, input *s3.GetObjectInput, _ ...request.Option) (*s3.GetObjectOutput, error) {
+	m.got = aws.StringValue(input.VersionId)
+	return &s3.GetObjectOutput{
+		Body:          io.NopCloser(strings.NewReader(m.got)),
+		ContentLength: aws.Int64(int64(len(m.got))),
+		Metadata:      map[string]*string{"owner": aws.String("ops")},
+	}, nil
+}
+
+func TestGetAsOf(t *testing.T) {
+	day := func(d int) *time.Time {
+		ts := time.Date(2025, 6, d, 0, 0, 0, 0, time.UTC)
+		return &ts
+	}
+	client := &versionedS3Client{output: &s3.ListObjectVersionsOutput{
+		Versions: []*s3.ObjectVersion{
+			{Key: aws.String("config.json"), VersionId: aws.String("v3"), LastModified: day(5)},
+			{Key: aws.String("config.json"), VersionId: aws.String("v1"), LastModified: day(1)},
+			{Key: aws.String("config.json.bak"), VersionId: aws.String("b1"), LastModified: day(2)},
+		},
+		DeleteMarkers: []*s3.DeleteMarkerEntry{
+			{Key: aws.String("config.json"), VersionId: aws.String("d2"), LastModified: day(3)},
+		},
+	}}
+	storage := &S3{svc: client, bucket: "test-bucket"}
+	ctx := context.Background()
+
+	tests := []struct {
+		at   *time.Time
+		want string
+	}{
+		{day(2), "v1"},
+		{day(5), "v3"},
+		{day(9), "v3"},
+	}
+	for _, tt := range tests {
+		reader, metadata, err := storage.GetAsOf(ctx, "config.json", *tt.at)
+		if err != nil {
+			t.Fatalf("GetAsOf(%v) error = %v", tt.at, err)
+		}
+		data, _ := io.ReadAll(reader)
+		_ = reader.Close()
+		if string(data) != tt.want || metadata.Custom["owner"] != "ops" {
+			t.Errorf("GetAsOf(%v) = %q, %+v, want %q", tt.at, data, metadata, tt.want)
+		}
+	}
+
+	// Before the first version, and while the key was deleted.
+	for _, at := range []*time.Time{day(0), day(4)} {
+		if _, _, err := storage.GetAsOf(ctx, "config.json", *at); !errors.Is(err, common.ErrKeyNotFound) {
+			t.Errorf("GetAsOf(%v) error = %v, want ErrKeyNotFound", at, err)
+		}
+	}
+}
@@ -0,0 +1,409 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package scrub finds objects whose content no longer matches the SHA-256
+// digest recorded when they were written (see package checksum), such as
+// objects damaged by bit rot, truncated writes or changes made behind the
+// backend's back, before a reader hits them.
+//
+// A Scrubber lists a backend and re-reads every object at a throttled rate
+// so scrubbing does not compete with clients. When a corrupted object has
+// an intact copy on a replica, such as a replication destination, the
+// object is rewritten from it. Every corruption is reported through
+// Config.OnCorruption and counted in a Registry, which the server exports
+// as metrics.
+//
+//	scrubber, err := scrub.New(checksummed, &scrub.Config{Replicas: replicas})
+//	result, err := scrubber.Run(ctx)
+package scrub
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// DefaultRate is the number of bytes read per second when
+// Config.BytesPerSecond is zero.
+const DefaultRate = 8 << 20
+
+// chunkSize is the size of the reads the rate limit is applied to.
+const chunkSize = 64 << 10
+
+// ErrInvalidConfig is returned by New for a malformed configuration.
+var ErrInvalidConfig = fmt.Errorf("%w: invalid scrub configuration", common.ErrInvalidArgument)
+
+// Replica is another backend that keeps copies of the scrubbed objects,
+// under the same keys.
+type Replica struct {
+	// Name identifies the replica in corruption reports.
+	Name string
+
+	// Prefix limits the replica to keys starting with it, for copies
+	// such as replication destinations that only hold part of the
+	// backend.
+	Prefix string
+
+	// Storage is the replica's backend.
+	Storage common.Storage
+}
+
+// Config configures a Scrubber. Zero fields take their defaults.
+type Config struct {
+	// Backend names the scrubbed backend in corruption reports and
+	// metrics.
+	Backend string
+
+	// Prefix limits scrubbing to keys starting with it.
+	Prefix string
+
+	// BytesPerSecond is the rate objects are read at
+	// (default: DefaultRate). A negative value disables the limit.
+	BytesPerSecond int64
+
+	// Replicas are searched, in order, for an intact copy of a corrupted
+	// object. Without replicas, corruption is only reported.
+	Replicas []Replica
+
+	// OnCorruption, if set, is called for every corrupted object after
+	// its repair was attempted.
+	OnCorruption func(Corruption)
+
+	// Registry counts scrubbed objects and corruptions. If nil, Default
+	// is used.
+	Registry *Registry
+}
+
+// Corruption describes an object whose content did not match its digest.
+type Corruption struct {
+	Backend string `json:"backend,omitempty"`
+	Key     string `json:"key"`
+
+	// Expected is the recorded SHA-256 digest and Actual the digest of
+	// the content read, both hex encoded.
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+
+	// Repaired reports whether the object was rewritten from Replica.
+	Repaired bool   `json:"repaired"`
+	Replica  string `json:"replica,omitempty"`
+
+	// Error describes why the object could not be repaired.
+	Error string `json:"error,omitempty"`
+}
+
+// Result summarizes a scrub run.
+type Result struct {
+	// Scanned counts the objects listed.
+	Scanned int `json:"scanned"`
+
+	// Verified counts the objects whose content matched their digest.
+	Verified int `json:"verified"`
+
+	// Unverifiable counts the objects without a recorded digest, and
+	// objects changed while they were being read.
+	Unverifiable int `json:"unverifiable"`
+
+	// Corrupted counts the objects whose content did not match their
+	// digest, and Repaired those of them rewritten from a replica.
+	Corrupted int `json:"corrupted"`
+	Repaired  int `json:"repaired"`
+
+	// Failed counts the objects that could not be read.
+	Failed int `json:"failed"`
+
+	// Bytes is the amount of content read.
+	Bytes int64 `json:"bytes"`
+
+	// Corruptions describes every corrupted object.
+	Corruptions []Corruption `json:"corruptions,omitempty"`
+
+	// Errors describes the objects that could not be read.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// Scrubber verifies the objects of a backend against their digests.
+type Scrubber struct {
+	storage  common.Storage
+	cfg      Config
+	limiter  *rate.Limiter
+	registry *Registry
+}
+
+// New returns a Scrubber for storage, whose objects carry the digests
+// recorded by checksum.Storage. Repairs are written to storage, so pass
+// the checksum wrapper rather than the backend it wraps if the repaired
+// objects should get fresh digests too.
+func New(storage common.Storage, cfg *Config) (*Scrubber, error) {
+	c := Config{}
+	if cfg != nil {
+		c = *cfg
+	}
+	if storage == nil {
+		return nil, fmt.Errorf("%w: no storage", ErrInvalidConfig)
+	}
+	for _, replica := range c.Replicas {
+		if replica.Storage == nil {
+			return nil, fmt.Errorf("%w: replica %q has no storage", ErrInvalidConfig, replica.Name)
+		}
+	}
+	if c.BytesPerSecond == 0 {
+		c.BytesPerSecond = DefaultRate
+	}
+	s := &Scrubber{storage: storage, cfg: c, registry: c.Registry}
+	if s.registry == nil {
+		s.registry = Default
+	}
+	if c.BytesPerSecond > 0 {
+		s.limiter = rate.NewLimiter(rate.Limit(c.BytesPerSecond), chunkSize)
+	}
+	return s, nil
+}
+
+// Run scrubs every object once. Objects that cannot be read are recorded
+// in the result and skipped; Run only fails if the backend cannot be
+// listed or ctx is done.
+func (s *Scrubber) Run(ctx context.Context) (*Result, error) {
+	result := &Result{}
+	opts := &common.ListOptions{Prefix: s.cfg.Prefix, MaxResults: 1000}
+	for {
+		page, err := s.storage.ListWithOptions(ctx, opts)
+		if err != nil {
+			return result, err
+		}
+		for _, obj := range page.Objects {
+			s.scrubObject(ctx, obj.Key, result)
+			if err := ctx.Err(); err != nil {
+				return result, err
+			}
+		}
+		if !page.Truncated || page.NextToken == "" {
+			return result, nil
+		}
+		opts.ContinueFrom = page.NextToken
+	}
+}
+
+// scrubObject verifies one object, repairing it if it is corrupted, and
+// records the outcome.
+func (s *Scrubber) scrubObject(ctx context.Context, key string, result *Result) {
+	result.Scanned++
+	metadata, err := s.storage.GetMetadata(ctx, key)
+	if err != nil {
+		s.failed(ctx, key, err, result)
+		return
+	}
+	want, ok := recorded(metadata)
+	if !ok {
+		result.Unverifiable++
+		s.registry.recordScan(s.cfg.Backend, 0)
+		return
+	}
+
+	got, n, err := s.digest(ctx, s.storage, key, nil)
+	result.Bytes += n
+	s.registry.recordScan(s.cfg.Backend, n)
+	if err != nil {
+		s.failed(ctx, key, err, result)
+		return
+	}
+	if got == want {
+		result.Verified++
+		return
+	}
+	// An object overwritten while it was read does not match the digest
+	// read before; it is checked again on the next run.
+	if !s.unchanged(ctx, key, want) {
+		result.Unverifiable++
+		return
+	}
+
+	corruption := Corruption{Backend: s.cfg.Backend, Key: key, Expected: want, Actual: got}
+	result.Corrupted++
+	if replica, err := s.repair(ctx, key, want, metadata); err != nil {
+		corruption.Error = err.Error()
+	} else {
+		corruption.Repaired = true
+		corruption.Replica = replica
+		result.Repaired++
+	}
+	result.Corruptions = append(result.Corruptions, corruption)
+	s.registry.recordCorruption(s.cfg.Backend, corruption.Repaired)
+	if s.cfg.OnCorruption != nil {
+		s.cfg.OnCorruption(corruption)
+	}
+}
+
+// failed records an object that could not be read. Objects deleted since
+// they were listed are not failures.
+func (s *Scrubber) failed(ctx context.Context, key string, err error, result *Result) {
+	if errors.Is(err, common.ErrKeyNotFound) || ctx.Err() != nil {
+		result.Unverifiable++
+		return
+	}
+	result.Failed++
+	result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", key, err))
+}
+
+// unchanged reports whether the object's recorded digest is still want.
+func (s *Scrubber) unchanged(ctx context.Context, key, want string) bool {
+	metadata, err := s.storage.GetMetadata(ctx, key)
+	if err != nil {
+		return false
+	}
+	got, _ := recorded(metadata)
+	return got == want
+}
+
+// recorded returns the SHA-256 digest recorded for an object. Unlike
+// common.Validators, it keeps digests whose recorded size no longer
+// matches the object, since a truncated object is corruption too.
+func recorded(metadata *common.Metadata) (string, bool) {
+	if metadata == nil {
+		return "", false
+	}
+	sum := strings.ToLower(common.CustomField(metadata.Custom, common.MetaChecksumSHA256))
+	if b, err := hex.DecodeString(sum); err != nil || len(b) != sha256.Size {
+		return "", false
+	}
+	return sum, true
+}
+
+// repair looks for a copy of key whose content matches want on the
+// replicas and rewrites the object from the first one found, returning the
+// replica's name.
+func (s *Scrubber) repair(ctx context.Context, key, want string, metadata *common.Metadata) (string, error) {
+	if len(s.cfg.Replicas) == 0 {
+		return "", errors.New("no replicas configured")
+	}
+	var reasons []string
+	for _, replica := range s.cfg.Replicas {
+		if !strings.HasPrefix(key, replica.Prefix) {
+			continue
+		}
+		err := s.repairFrom(ctx, replica, key, want, metadata)
+		if err == nil {
+			return replica.Name, nil
+		}
+		reasons = append(reasons, fmt.Sprintf("%s: %v", replica.Name, err))
+	}
+	if len(reasons) == 0 {
+		return "", errors.New("no replica holds the key")
+	}
+	return "", fmt.Errorf("no intact copy found (%s)", strings.Join(reasons, "; "))
+}
+
+// repairFrom copies key from replica to a temporary file, checks it
+// against want and, if it matches, rewrites the object with it.
+func (s *Scrubber) repairFrom(ctx context.Context, replica Replica, key, want string, metadata *common.Metadata) error {
+	spool, err := os.CreateTemp("", "objstore-scrub-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = spool.Close()
+		_ = os.Remove(spool.Name())
+	}()
+
+	got, _, err := s.digest(ctx, replica.Storage, key, spool)
+	if err != nil {
+		return err
+	}
+	if got != want {
+		return errors.New("copy is corrupted too")
+	}
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	// Leave objects rewritten by clients since they were verified alone.
+	if !s.unchanged(ctx, key, want) {
+		return errors.New("object changed during repair")
+	}
+	return s.storage.PutWithMetadata(ctx, key, spool, metadata)
+}
+
+// digest reads key from storage at the configured rate, copying it to w if
+// w is not nil, and returns its hex encoded SHA-256 digest and size.
+func (s *Scrubber) digest(ctx context.Context, storage common.Storage, key string, w io.Writer) (string, int64, error) {
+	rc, err := storage.GetWithContext(ctx, key)
+	if err != nil {
+		return "", 0, err
+	}
+	defer func() { _ = rc.Close() }()
+
+	h := sha256.New()
+	var sink io.Writer = h
+	if w != nil {
+		sink = io.MultiWriter(h, w)
+	}
+	n, err := s.copy(ctx, sink, rc)
+	if err != nil {
+		return "", n, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), n, nil
+}
+
+// copy copies r to w in chunks, waiting for the rate limit before each.
+func (s *Scrubber) copy(ctx context.Context, w io.Writer, r io.Reader) (int64, error) {
+	if s.limiter == nil {
+		return io.Copy(w, r)
+	}
+	buf := make([]byte, chunkSize)
+	var total int64
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			if werr := s.wait(ctx, n); werr != nil {
+				return total, werr
+			}
+			if _, werr := w.Write(buf[:n]); werr != nil {
+				return total, werr
+			}
+			total += int64(n)
+		}
+		if err == io.EOF {
+			return total, nil
+		}
+		if err != nil {
+			return total, err
+		}
+	}
+}
+
+// wait blocks until n more bytes may be read. Unlike rate.Limiter.WaitN,
+// it waits for a deadline instead of failing early, so a run cut short by
+// its timeout ends with the context's error.
+func (s *Scrubber) wait(ctx context.Context, n int) error {
+	delay := s.limiter.ReserveN(time.Now(), n).Delay()
+	if delay == 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
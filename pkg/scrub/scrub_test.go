@@ -0,0 +1,184 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package scrub
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/checksum"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+// corrupt overwrites the content of key in backend, keeping its metadata
+// and so its recorded digest.
+func corrupt(t *testing.T, backend common.Storage, key, content string) {
+	t.Helper()
+	ctx := context.Background()
+	metadata, err := backend.GetMetadata(ctx, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := backend.PutWithMetadata(ctx, key, strings.NewReader(content), metadata); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func read(t *testing.T, storage common.Storage, key string) string {
+	t.Helper()
+	rc, err := storage.GetWithContext(context.Background(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = rc.Close() }()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+	backend := memory.New()
+	primary := checksum.NewStorage(backend)
+	replica := memory.New()
+	for key, content := range map[string]string{"a.txt": "alpha", "b.txt": "bravo", "c.txt": "charlie"} {
+		if err := primary.PutWithContext(ctx, key, strings.NewReader(content)); err != nil {
+			t.Fatal(err)
+		}
+		if err := replica.PutWithContext(ctx, key, strings.NewReader(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := backend.PutWithContext(ctx, "plain.txt", strings.NewReader("no digest")); err != nil {
+		t.Fatal(err)
+	}
+	corrupt(t, backend, "a.txt", "alphx")
+	corrupt(t, backend, "b.txt", "brav")
+	corrupt(t, replica, "b.txt", "bravx")
+
+	registry := NewRegistry()
+	var reported []Corruption
+	scrubber, err := New(primary, &Config{
+		Backend:      "primary",
+		Replicas:     []Replica{{Name: "replica", Storage: replica}},
+		OnCorruption: func(c Corruption) { reported = append(reported, c) },
+		Registry:     registry,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := scrubber.Run(ctx)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if result.Scanned != 4 || result.Verified != 1 || result.Unverifiable != 1 || result.Corrupted != 2 || result.Repaired != 1 || result.Failed != 0 {
+		t.Errorf("result = %+v", result)
+	}
+	if len(reported) != 2 {
+		t.Fatalf("reported = %+v", reported)
+	}
+	for _, c := range reported {
+		switch c.Key {
+		case "a.txt":
+			if !c.Repaired || c.Replica != "replica" || c.Backend != "primary" {
+				t.Errorf("a.txt corruption = %+v", c)
+			}
+		case "b.txt":
+			if c.Repaired || !strings.Contains(c.Error, "corrupted too") {
+				t.Errorf("b.txt corruption = %+v", c)
+			}
+		}
+	}
+	if got := read(t, primary, "a.txt"); got != "alpha" {
+		t.Errorf("repaired a.txt = %q", got)
+	}
+	if err := primary.Verify(ctx, "a.txt"); err != nil {
+		t.Errorf("Verify(a.txt) after repair: %v", err)
+	}
+	if got := read(t, primary, "b.txt"); got != "brav" {
+		t.Errorf("b.txt = %q, want it left alone", got)
+	}
+
+	stats := registry.Stats()
+	if len(stats) != 1 || stats[0].Objects != 4 || stats[0].Corruptions != 2 || stats[0].Repairs != 1 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
+
+func TestRun_NoReplicas(t *testing.T) {
+	ctx := context.Background()
+	backend := memory.New()
+	primary := checksum.NewStorage(backend)
+	if err := primary.PutWithContext(ctx, "logs/a", strings.NewReader("alpha")); err != nil {
+		t.Fatal(err)
+	}
+	if err := primary.PutWithContext(ctx, "other/b", strings.NewReader("bravo")); err != nil {
+		t.Fatal(err)
+	}
+	corrupt(t, backend, "logs/a", "alphx")
+	corrupt(t, backend, "other/b", "bravx")
+
+	scrubber, err := New(primary, &Config{Prefix: "logs/", BytesPerSecond: -1, Registry: NewRegistry()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := scrubber.Run(ctx)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if result.Scanned != 1 || result.Corrupted != 1 || result.Repaired != 0 || len(result.Corruptions) != 1 {
+		t.Fatalf("result = %+v", result)
+	}
+	if c := result.Corruptions[0]; c.Key != "logs/a" || c.Error == "" || c.Actual == c.Expected {
+		t.Errorf("corruption = %+v", c)
+	}
+}
+
+func TestRun_Throttled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	primary := checksum.NewStorage(memory.New())
+	if err := primary.PutWithContext(ctx, "big", strings.NewReader(strings.Repeat("x", 4*chunkSize))); err != nil {
+		t.Fatal(err)
+	}
+	// At one chunk per second, reading the object outlasts the deadline.
+	scrubber, err := New(primary, &Config{BytesPerSecond: chunkSize, Registry: NewRegistry()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	result, err := scrubber.Run(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Run() error = %v, want context.DeadlineExceeded", err)
+	}
+	if result.Failed != 0 || result.Corrupted != 0 || time.Since(start) > 2*time.Second {
+		t.Errorf("result = %+v after %v", result, time.Since(start))
+	}
+}
+
+func TestNew_InvalidConfig(t *testing.T) {
+	if _, err := New(nil, nil); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("New(nil) error = %v", err)
+	}
+	if _, err := New(memory.New(), &Config{Replicas: []Replica{{Name: "r"}}}); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("New() with a replica without storage error = %v", err)
+	}
+}
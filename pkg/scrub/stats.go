@@ -0,0 +1,91 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package scrub
+
+import (
+	"sort"
+	"sync"
+)
+
+// Stats counts the scrubbing of one backend.
+type Stats struct {
+	// Backend is the name of the scrubbed backend.
+	Backend string
+
+	// Objects counts the objects checked and Bytes the content read.
+	Objects uint64
+	Bytes   uint64
+
+	// Corruptions counts the corrupted objects found, and Repairs those
+	// of them rewritten from a replica.
+	Corruptions uint64
+	Repairs     uint64
+}
+
+// Registry accumulates Stats per backend. It is safe for concurrent use.
+type Registry struct {
+	mu       sync.Mutex
+	backends map[string]*Stats
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{backends: make(map[string]*Stats)}
+}
+
+// Default is the process-wide registry scrubbers record into.
+var Default = NewRegistry()
+
+// update applies fn to the stats of backend, creating them if needed.
+func (r *Registry) update(backend string, fn func(*Stats)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.backends[backend]
+	if !ok {
+		s = &Stats{Backend: backend}
+		r.backends[backend] = s
+	}
+	fn(s)
+}
+
+// recordScan records an object checked by reading n bytes.
+func (r *Registry) recordScan(backend string, n int64) {
+	r.update(backend, func(s *Stats) {
+		s.Objects++
+		s.Bytes += uint64(n) // #nosec G115 -- n is a byte count, never negative
+	})
+}
+
+// recordCorruption records a corrupted object and whether it was repaired.
+func (r *Registry) recordCorruption(backend string, repaired bool) {
+	r.update(backend, func(s *Stats) {
+		s.Corruptions++
+		if repaired {
+			s.Repairs++
+		}
+	})
+}
+
+// Stats returns the statistics of every backend, sorted by backend.
+func (r *Registry) Stats() []Stats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	out := make([]Stats, 0, len(r.backends))
+	for _, s := range r.backends {
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Backend < out[j].Backend })
+	return out
+}
@@ -0,0 +1,515 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package search maintains an inverted index over object keys and metadata
+// so objects can be found by name, content type or custom metadata without
+// listing the whole backend.
+//
+// An Index holds one Document per object. Keys, content types and custom
+// metadata values are split into lower-case terms; each term maps to the set
+// of keys that contain it. Storage wraps a backend and keeps its Index up to
+// date on every Put, Delete and UpdateMetadata, and Rebuild populates an
+// Index from a full listing when it is first enabled.
+//
+// The index can be persisted to a JSON file so it survives restarts and
+// separate CLI invocations. Changes are appended to a journal next to that
+// file, so a write costs one journal record rather than a rewrite of the
+// whole index; the journal is folded into the file once it outgrows it.
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// DefaultLimit is the number of results returned when no limit is given.
+const DefaultLimit = 100
+
+// fieldContentType is the query field matching an object's content type.
+const fieldContentType = "content-type"
+
+const (
+	// journalSuffix is appended to the index path to name its journal.
+	journalSuffix = ".journal"
+
+	// minCompactEntries is the journal length below which it is never
+	// folded into the index file.
+	minCompactEntries = 1024
+
+	opUpdate = "update"
+	opRemove = "remove"
+)
+
+var (
+	// ErrEmptyQuery is returned when a query has no terms.
+	ErrEmptyQuery = errors.New("search query is empty")
+
+	// ErrIndexCorrupt is returned when a persisted index cannot be decoded.
+	ErrIndexCorrupt = errors.New("search index is corrupt")
+)
+
+// Document is the indexed view of one object.
+type Document struct {
+	Key          string            `json:"key"`
+	Size         int64             `json:"size"`
+	ETag         string            `json:"etag,omitempty"`
+	ContentType  string            `json:"content_type,omitempty"`
+	LastModified time.Time         `json:"last_modified"`
+	Custom       map[string]string `json:"custom,omitempty"`
+}
+
+// journalEntry is one change recorded in the journal.
+type journalEntry struct {
+	Op  string    `json:"op"`
+	Key string    `json:"key,omitempty"`
+	Doc *Document `json:"doc,omitempty"`
+}
+
+// Index is an in-memory inverted index of Documents. It is safe for
+// concurrent use.
+type Index struct {
+	mu   sync.RWMutex
+	path string
+	docs map[string]*Document
+
+	// journaled is the number of journal entries not yet folded into the
+	// index file.
+	journaled int
+
+	// postings maps a term to the keys containing it. Bare terms come from
+	// keys and metadata values; field terms are stored as "field:term" and
+	// "field:whole value".
+	postings map[string]map[string]struct{}
+}
+
+// NewIndex creates an Index. When path is non-empty the index is loaded from
+// that file and its journal if they exist, and every change is journaled.
+func NewIndex(path string) (*Index, error) {
+	idx := &Index{
+		path:     path,
+		docs:     make(map[string]*Document),
+		postings: make(map[string]map[string]struct{}),
+	}
+	if path == "" {
+		return idx, nil
+	}
+
+	data, err := os.ReadFile(path) // #nosec G304 -- index path is operator configuration
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("failed to read search index: %w", err)
+	default:
+		var docs []*Document
+		if err := json.Unmarshal(data, &docs); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrIndexCorrupt, err)
+		}
+		for _, doc := range docs {
+			idx.addLocked(doc)
+		}
+	}
+	if err := idx.replayJournal(); err != nil {
+		return nil, err
+	}
+	return idx, nil
+}
+
+// replayJournal applies the journal, if any, on top of the loaded index
+// file. A torn last record, left by a crash during a write, is ignored.
+func (idx *Index) replayJournal() error {
+	data, err := os.ReadFile(idx.path + journalSuffix) // #nosec G304 -- index path is operator configuration
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read search index journal: %w", err)
+	}
+
+	lines := strings.Split(string(data), "\n")
+	for i, line := range lines {
+		if line == "" {
+			continue
+		}
+		var entry journalEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			if i == len(lines)-1 {
+				break
+			}
+			return fmt.Errorf("%w: journal: %v", ErrIndexCorrupt, err)
+		}
+		switch {
+		case entry.Op == opUpdate && entry.Doc != nil:
+			idx.removeLocked(entry.Doc.Key)
+			idx.addLocked(entry.Doc)
+		case entry.Op == opRemove:
+			idx.removeLocked(entry.Key)
+		default:
+			return fmt.Errorf("%w: journal: unknown entry %q", ErrIndexCorrupt, entry.Op)
+		}
+		idx.journaled++
+	}
+	return nil
+}
+
+// Len returns the number of indexed objects.
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.docs)
+}
+
+// Update indexes or re-indexes the object stored under key.
+func (idx *Index) Update(key string, metadata *common.Metadata) error {
+	doc := newDocument(key, metadata)
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.removeLocked(key)
+	idx.addLocked(doc)
+	return idx.journalLocked(journalEntry{Op: opUpdate, Doc: doc})
+}
+
+// Remove drops key from the index. Removing an unknown key is not an error.
+func (idx *Index) Remove(key string) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if _, ok := idx.docs[key]; !ok {
+		return nil
+	}
+	idx.removeLocked(key)
+	return idx.journalLocked(journalEntry{Op: opRemove, Key: key})
+}
+
+// Rebuild replaces the contents of the index with a full listing of storage.
+func (idx *Index) Rebuild(ctx context.Context, storage common.Storage) error {
+	docs := make(map[string]*common.Metadata)
+	opts := &common.ListOptions{MaxResults: 1000}
+	for {
+		result, err := storage.ListWithOptions(ctx, opts)
+		if err != nil {
+			return fmt.Errorf("failed to list objects: %w", err)
+		}
+		for _, obj := range result.Objects {
+			docs[obj.Key] = obj.Metadata
+		}
+		if !result.Truncated || result.NextToken == "" {
+			break
+		}
+		opts.ContinueFrom = result.NextToken
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.docs = make(map[string]*Document, len(docs))
+	idx.postings = make(map[string]map[string]struct{})
+	for key, metadata := range docs {
+		idx.addLocked(newDocument(key, metadata))
+	}
+	return idx.saveLocked()
+}
+
+// newDocument returns the Document of the object stored under key. The
+// custom metadata is copied so later changes by the caller do not leak into
+// the index.
+func newDocument(key string, metadata *common.Metadata) *Document {
+	doc := &Document{Key: key}
+	if metadata == nil {
+		return doc
+	}
+	doc.Size = metadata.Size
+	doc.ETag = metadata.ETag
+	doc.ContentType = metadata.ContentType
+	doc.LastModified = metadata.LastModified
+	if len(metadata.Custom) > 0 {
+		doc.Custom = make(map[string]string, len(metadata.Custom))
+		for k, v := range metadata.Custom {
+			doc.Custom[k] = v
+		}
+	}
+	return doc
+}
+
+// Search returns the documents matching query, sorted by key. At most limit
+// documents are returned; a non-positive limit uses DefaultLimit.
+//
+// A query is a list of space-separated terms that must all match:
+//
+//	report            key, content type or a metadata value contains "report"
+//	rep*              ... contains a term starting with "rep"
+//	author:alice      custom metadata "author" contains "alice"
+//	content-type:image/png
+//	prefix:logs/2025/ key starts with "logs/2025/"
+func (idx *Index) Search(query string, limit int) ([]Document, error) {
+	terms := strings.Fields(query)
+	if len(terms) == 0 {
+		return nil, ErrEmptyQuery
+	}
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	var matches map[string]struct{}
+	for _, term := range terms {
+		keys := idx.matchLocked(term)
+		if matches == nil {
+			matches = keys
+		} else {
+			matches = intersect(matches, keys)
+		}
+		if len(matches) == 0 {
+			return []Document{}, nil
+		}
+	}
+
+	keys := make([]string, 0, len(matches))
+	for key := range matches {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	if len(keys) > limit {
+		keys = keys[:limit]
+	}
+
+	results := make([]Document, 0, len(keys))
+	for _, key := range keys {
+		doc := *idx.docs[key]
+		results = append(results, doc)
+	}
+	return results, nil
+}
+
+// matchLocked returns the keys matching one query term.
+func (idx *Index) matchLocked(term string) map[string]struct{} {
+	field, value, hasField := strings.Cut(term, ":")
+	if !hasField || field == "" {
+		return idx.lookupLocked("", term)
+	}
+
+	field = strings.ToLower(field)
+	if field == "prefix" {
+		keys := make(map[string]struct{})
+		for key := range idx.docs {
+			if strings.HasPrefix(key, value) {
+				keys[key] = struct{}{}
+			}
+		}
+		return keys
+	}
+
+	// A whole value match covers values that tokenize into several terms,
+	// such as "image/png".
+	if keys, ok := idx.postings[field+":"+strings.ToLower(value)]; ok {
+		return copySet(keys)
+	}
+	return idx.lookupLocked(field+":", value)
+}
+
+// lookupLocked intersects the postings of every term in value, each
+// qualified with prefix. A trailing "*" matches terms by prefix.
+func (idx *Index) lookupLocked(prefix, value string) map[string]struct{} {
+	wildcard := strings.HasSuffix(value, "*")
+	words := tokenize(strings.TrimSuffix(value, "*"))
+	if len(words) == 0 {
+		return map[string]struct{}{}
+	}
+
+	var result map[string]struct{}
+	for i, word := range words {
+		var keys map[string]struct{}
+		if wildcard && i == len(words)-1 {
+			keys = make(map[string]struct{})
+			for term, postings := range idx.postings {
+				if strings.HasPrefix(term, prefix+word) {
+					for key := range postings {
+						keys[key] = struct{}{}
+					}
+				}
+			}
+		} else {
+			keys = copySet(idx.postings[prefix+word])
+		}
+		if result == nil {
+			result = keys
+		} else {
+			result = intersect(result, keys)
+		}
+	}
+	return result
+}
+
+// terms returns every posting term of doc.
+func (doc *Document) terms() []string {
+	var out []string
+	add := func(field, value string) {
+		for _, word := range tokenize(value) {
+			out = append(out, word)
+			if field != "" {
+				out = append(out, field+":"+word)
+			}
+		}
+		if field != "" && value != "" {
+			out = append(out, field+":"+strings.ToLower(value))
+		}
+	}
+
+	add("key", doc.Key)
+	add(fieldContentType, doc.ContentType)
+	for k, v := range doc.Custom {
+		add(strings.ToLower(k), v)
+	}
+	return out
+}
+
+func (idx *Index) addLocked(doc *Document) {
+	idx.docs[doc.Key] = doc
+	for _, term := range doc.terms() {
+		keys, ok := idx.postings[term]
+		if !ok {
+			keys = make(map[string]struct{})
+			idx.postings[term] = keys
+		}
+		keys[doc.Key] = struct{}{}
+	}
+}
+
+func (idx *Index) removeLocked(key string) {
+	doc, ok := idx.docs[key]
+	if !ok {
+		return
+	}
+	for _, term := range doc.terms() {
+		if keys, ok := idx.postings[term]; ok {
+			delete(keys, key)
+			if len(keys) == 0 {
+				delete(idx.postings, term)
+			}
+		}
+	}
+	delete(idx.docs, key)
+}
+
+// journalLocked appends entry to the journal, if any. Once the journal holds
+// more entries than the index has documents it is folded into the index
+// file, which keeps replay time and disk use proportional to the index.
+func (idx *Index) journalLocked(entry journalEntry) error {
+	if idx.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode search index journal: %w", err)
+	}
+	f, err := os.OpenFile(idx.path+journalSuffix, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600) // #nosec G304 -- index path is operator configuration
+	if err != nil {
+		return fmt.Errorf("failed to write search index journal: %w", err)
+	}
+	_, err = f.Write(append(data, '\n'))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write search index journal: %w", err)
+	}
+
+	idx.journaled++
+	if idx.journaled >= minCompactEntries && idx.journaled > len(idx.docs) {
+		return idx.saveLocked()
+	}
+	return nil
+}
+
+// saveLocked writes the index to its file, if any, replacing the previous
+// copy atomically, and empties the journal. Replaying a journal that
+// survived a crash after the rename is harmless: its entries are already
+// part of the file.
+func (idx *Index) saveLocked() error {
+	if idx.path == "" {
+		return nil
+	}
+
+	docs := make([]*Document, 0, len(idx.docs))
+	for _, doc := range idx.docs {
+		docs = append(docs, doc)
+	}
+	sort.Slice(docs, func(i, j int) bool { return docs[i].Key < docs[j].Key })
+	data, err := json.Marshal(docs)
+	if err != nil {
+		return fmt.Errorf("failed to encode search index: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(idx.path), ".search-index-*")
+	if err != nil {
+		return fmt.Errorf("failed to write search index: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write search index: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write search index: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), idx.path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write search index: %w", err)
+	}
+	if err := os.Remove(idx.path + journalSuffix); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to reset search index journal: %w", err)
+	}
+	idx.journaled = 0
+	return nil
+}
+
+// tokenize splits s into lower-case runs of letters and digits.
+func tokenize(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func copySet(in map[string]struct{}) map[string]struct{} {
+	out := make(map[string]struct{}, len(in))
+	for k := range in {
+		out[k] = struct{}{}
+	}
+	return out
+}
+
+func intersect(a, b map[string]struct{}) map[string]struct{} {
+	if len(b) < len(a) {
+		a, b = b, a
+	}
+	out := make(map[string]struct{})
+	for k := range a {
+		if _, ok := b[k]; ok {
+			out[k] = struct{}{}
+		}
+	}
+	return out
+}
@@ -0,0 +1,283 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package search
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+func keysOf(docs []Document) []string {
+	keys := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		keys = append(keys, doc.Key)
+	}
+	return keys
+}
+
+func seed(t *testing.T, s common.Storage) {
+	t.Helper()
+	ctx := context.Background()
+	objects := []struct {
+		key  string
+		meta *common.Metadata
+	}{
+		{"reports/2025/q1-report.pdf", &common.Metadata{ContentType: "application/pdf", Custom: map[string]string{"author": "Alice Smith", "dept": "finance"}}},
+		{"reports/2025/q2-report.pdf", &common.Metadata{ContentType: "application/pdf", Custom: map[string]string{"author": "Bob", "dept": "finance"}}},
+		{"images/logo.png", &common.Metadata{ContentType: "image/png", Custom: map[string]string{"author": "alice"}}},
+		{"logs/app.log", nil},
+	}
+	for _, obj := range objects {
+		if err := s.PutWithMetadata(ctx, obj.key, strings.NewReader("data"), obj.meta); err != nil {
+			t.Fatalf("PutWithMetadata(%s): %v", obj.key, err)
+		}
+	}
+}
+
+func TestIndex_Search(t *testing.T) {
+	index, _ := NewIndex("")
+	s := NewStorage(memory.New(), index)
+	seed(t, s)
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"report", []string{"reports/2025/q1-report.pdf", "reports/2025/q2-report.pdf"}},
+		{"REPORT q1", []string{"reports/2025/q1-report.pdf"}},
+		{"author:alice", []string{"images/logo.png", "reports/2025/q1-report.pdf"}},
+		{"author:alice dept:finance", []string{"reports/2025/q1-report.pdf"}},
+		{"content-type:image/png", []string{"images/logo.png"}},
+		{"content-type:pdf", []string{"reports/2025/q1-report.pdf", "reports/2025/q2-report.pdf"}},
+		{"prefix:logs/", []string{"logs/app.log"}},
+		{"fin*", []string{"reports/2025/q1-report.pdf", "reports/2025/q2-report.pdf"}},
+		{"missing", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			docs, err := s.Search(tt.query, 0)
+			if err != nil {
+				t.Fatalf("Search: %v", err)
+			}
+			if got := keysOf(docs); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+
+	docs, err := s.Search("pdf", 1)
+	if err != nil || len(docs) != 1 {
+		t.Errorf("expected limit to apply, got %v %v", keysOf(docs), err)
+	}
+	if _, err := s.Search("   ", 0); !errors.Is(err, ErrEmptyQuery) {
+		t.Errorf("expected ErrEmptyQuery, got %v", err)
+	}
+}
+
+func TestStorage_KeepsIndexCurrent(t *testing.T) {
+	index, _ := NewIndex("")
+	s := NewStorage(memory.New(), index)
+	seed(t, s)
+	ctx := context.Background()
+
+	if err := s.Delete("images/logo.png"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := s.UpdateMetadata(ctx, "logs/app.log", &common.Metadata{Custom: map[string]string{"author": "alice"}}); err != nil {
+		t.Fatalf("UpdateMetadata: %v", err)
+	}
+	if err := s.Put("notes/alice.txt", strings.NewReader("hi")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	docs, err := s.Search("alice", 0)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	want := []string{"logs/app.log", "notes/alice.txt", "reports/2025/q1-report.pdf"}
+	if got := keysOf(docs); !reflect.DeepEqual(got, want) {
+		t.Errorf("Search = %v, want %v", got, want)
+	}
+	if docs[1].Size != 2 {
+		t.Errorf("expected size reported by the backend, got %d", docs[1].Size)
+	}
+
+	if err := s.Append(ctx, "notes/alice.txt", strings.NewReader("!")); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	if err := s.Compose(ctx, "notes/combined.txt", "notes/alice.txt", "notes/alice.txt"); err != nil {
+		t.Fatalf("Compose: %v", err)
+	}
+	docs, _ = s.Search("prefix:notes/", 0)
+	if len(docs) != 2 || docs[0].Size != 3 || docs[1].Size != 6 {
+		t.Errorf("Search after Append and Compose = %+v, want sizes 3 and 6", docs)
+	}
+}
+
+func TestIndex_PersistAndRebuild(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.json")
+	underlying := memory.New()
+
+	index, err := NewIndex(path)
+	if err != nil {
+		t.Fatalf("NewIndex: %v", err)
+	}
+	seed(t, NewStorage(underlying, index))
+
+	reloaded, err := NewIndex(path)
+	if err != nil {
+		t.Fatalf("NewIndex: %v", err)
+	}
+	if reloaded.Len() != 4 {
+		t.Fatalf("expected 4 persisted documents, got %d", reloaded.Len())
+	}
+
+	// Writes made around the index are picked up by a rebuild.
+	if err := underlying.Delete("logs/app.log"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := reloaded.Rebuild(context.Background(), underlying); err != nil {
+		t.Fatalf("Rebuild: %v", err)
+	}
+	docs, err := reloaded.Search("prefix:logs/", 0)
+	if err != nil || len(docs) != 0 {
+		t.Errorf("expected rebuilt index to drop deleted object, got %v %v", keysOf(docs), err)
+	}
+
+	if err := os.WriteFile(path, []byte("not json"), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if _, err := NewIndex(path); !errors.Is(err, ErrIndexCorrupt) {
+		t.Errorf("expected ErrIndexCorrupt, got %v", err)
+	}
+}
+
+func TestIndex_Journal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.json")
+	index, err := NewIndex(path)
+	if err != nil {
+		t.Fatalf("NewIndex: %v", err)
+	}
+	for _, key := range []string{"a.txt", "b.txt", "c.txt"} {
+		if err := index.Update(key, &common.Metadata{Custom: map[string]string{"team": "ops"}}); err != nil {
+			t.Fatalf("Update(%s): %v", key, err)
+		}
+	}
+	if err := index.Remove("b.txt"); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+
+	// Writes go to the journal; the index file is not rewritten.
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected no index file before compaction, got %v", err)
+	}
+
+	// A torn last record, as left by a crash mid-write, is ignored.
+	f, err := os.OpenFile(path+journalSuffix, os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		t.Fatalf("OpenFile: %v", err)
+	}
+	_, _ = f.WriteString(`{"op":"update","doc":{"key":"d.t`)
+	_ = f.Close()
+
+	reloaded, err := NewIndex(path)
+	if err != nil {
+		t.Fatalf("NewIndex: %v", err)
+	}
+	docs, err := reloaded.Search("team:ops", 0)
+	if err != nil || !reflect.DeepEqual(keysOf(docs), []string{"a.txt", "c.txt"}) {
+		t.Errorf("replayed index = %v, %v", keysOf(docs), err)
+	}
+
+	// Rebuilding folds the journal into the index file.
+	underlying := memory.New()
+	if err := underlying.Put("e.txt", strings.NewReader("e")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if err := reloaded.Rebuild(context.Background(), underlying); err != nil {
+		t.Fatalf("Rebuild: %v", err)
+	}
+	if _, err := os.Stat(path + journalSuffix); !os.IsNotExist(err) {
+		t.Errorf("expected journal to be removed by rebuild, got %v", err)
+	}
+	if rebuilt, err := NewIndex(path); err != nil || rebuilt.Len() != 1 {
+		t.Errorf("rebuilt index: %v", err)
+	}
+}
+
+func TestIndex_JournalCompacts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.json")
+	index, err := NewIndex(path)
+	if err != nil {
+		t.Fatalf("NewIndex: %v", err)
+	}
+	// Rewriting the same key grows the journal past the index size.
+	for range minCompactEntries {
+		if err := index.Update("key", nil); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+	}
+	if _, err := os.Stat(path + journalSuffix); !os.IsNotExist(err) {
+		t.Errorf("expected journal to be compacted, got %v", err)
+	}
+	reloaded, err := NewIndex(path)
+	if err != nil || reloaded.Len() != 1 {
+		t.Fatalf("NewIndex after compaction: %v", err)
+	}
+}
+
+func TestIndex_RebuildCopiesCustomMetadata(t *testing.T) {
+	underlying := memory.New()
+	custom := map[string]string{"author": "alice"}
+	if err := underlying.PutWithMetadata(context.Background(), "doc.txt", strings.NewReader("x"), &common.Metadata{Custom: custom}); err != nil {
+		t.Fatalf("PutWithMetadata: %v", err)
+	}
+	index, _ := NewIndex("")
+	if err := index.Rebuild(context.Background(), &customSharingStorage{Storage: underlying, custom: custom}); err != nil {
+		t.Fatalf("Rebuild: %v", err)
+	}
+	custom["author"] = "mallory"
+
+	docs, err := index.Search("author:alice", 0)
+	if err != nil || len(docs) != 1 || docs[0].Custom["author"] != "alice" {
+		t.Errorf("indexed document changed with the listed metadata: %+v, %v", docs, err)
+	}
+}
+
+// customSharingStorage lists objects with a Custom map shared with the test.
+type customSharingStorage struct {
+	common.Storage
+	custom map[string]string
+}
+
+func (s *customSharingStorage) ListWithOptions(ctx context.Context, opts *common.ListOptions) (*common.ListResult, error) {
+	result, err := s.Storage.ListWithOptions(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range result.Objects {
+		if obj.Metadata != nil {
+			obj.Metadata.Custom = s.custom
+		}
+	}
+	return result, nil
+}
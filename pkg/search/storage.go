@@ -0,0 +1,146 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package search
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// Storage wraps a backend and updates an Index after every successful write.
+// Reads, listings and lifecycle operations pass through unchanged.
+type Storage struct {
+	common.Storage
+	index *Index
+}
+
+// NewStorage returns underlying wrapped so that writes update index.
+func NewStorage(underlying common.Storage, index *Index) *Storage {
+	return &Storage{Storage: underlying, index: index}
+}
+
+// Index returns the index kept up to date by s.
+func (s *Storage) Index() *Index {
+	return s.index
+}
+
+// Underlying returns the wrapped backend.
+func (s *Storage) Underlying() common.Storage {
+	return s.Storage
+}
+
+// Search runs query against the index. See Index.Search for the syntax.
+func (s *Storage) Search(query string, limit int) ([]Document, error) {
+	return s.index.Search(query, limit)
+}
+
+// GetRange reads a byte range from the wrapped backend, falling back to
+// discarding the leading bytes of a full read when it cannot read ranges.
+func (s *Storage) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	if rr, ok := s.Storage.(common.RangeReader); ok {
+		return rr.GetRange(ctx, key, offset, length)
+	}
+	rc, err := s.Storage.GetWithContext(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return common.SliceRange(rc, offset, length)
+}
+
+// GetAsOf reads an earlier version of an object from the wrapped backend.
+func (s *Storage) GetAsOf(ctx context.Context, key string, at time.Time) (io.ReadCloser, *common.Metadata, error) {
+	return common.GetAsOf(ctx, s.Storage, key, at)
+}
+
+// Put stores an object and indexes it.
+func (s *Storage) Put(key string, data io.Reader) error {
+	return s.PutWithContext(context.Background(), key, data)
+}
+
+// PutWithContext stores an object and indexes it.
+func (s *Storage) PutWithContext(ctx context.Context, key string, data io.Reader) error {
+	if err := s.Storage.PutWithContext(ctx, key, data); err != nil {
+		return err
+	}
+	return s.reindex(ctx, key, nil)
+}
+
+// PutWithMetadata stores an object with metadata and indexes it.
+func (s *Storage) PutWithMetadata(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) error {
+	if err := s.Storage.PutWithMetadata(ctx, key, data, metadata); err != nil {
+		return err
+	}
+	return s.reindex(ctx, key, metadata)
+}
+
+// PutWithInfo stores an object with metadata, indexes it and returns what
+// was stored.
+func (s *Storage) PutWithInfo(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) (*common.ObjectInfo, error) {
+	info, err := common.PutWithInfo(ctx, s.Storage, key, data, metadata)
+	if err != nil {
+		return nil, err
+	}
+	return info, s.reindex(ctx, key, metadata)
+}
+
+// UpdateMetadata updates an object's metadata and re-indexes it.
+func (s *Storage) UpdateMetadata(ctx context.Context, key string, metadata *common.Metadata) error {
+	if err := s.Storage.UpdateMetadata(ctx, key, metadata); err != nil {
+		return err
+	}
+	return s.reindex(ctx, key, metadata)
+}
+
+// Append adds data to the end of an object and re-indexes it.
+func (s *Storage) Append(ctx context.Context, key string, data io.Reader) error {
+	if err := common.Append(ctx, s.Storage, key, data); err != nil {
+		return err
+	}
+	return s.reindex(ctx, key, nil)
+}
+
+// Compose concatenates srcKeys into destKey and indexes the result.
+func (s *Storage) Compose(ctx context.Context, destKey string, srcKeys ...string) error {
+	if err := common.Compose(ctx, s.Storage, destKey, srcKeys...); err != nil {
+		return err
+	}
+	return s.reindex(ctx, destKey, nil)
+}
+
+// Delete removes an object and drops it from the index.
+func (s *Storage) Delete(key string) error {
+	return s.DeleteWithContext(context.Background(), key)
+}
+
+// DeleteWithContext removes an object and drops it from the index.
+func (s *Storage) DeleteWithContext(ctx context.Context, key string) error {
+	if err := s.Storage.DeleteWithContext(ctx, key); err != nil {
+		return err
+	}
+	return s.index.Remove(key)
+}
+
+// reindex indexes key using the metadata the backend reports after a write,
+// which carries the final size, ETag and modification time. When the
+// backend cannot report metadata, fallback is indexed instead.
+func (s *Storage) reindex(ctx context.Context, key string, fallback *common.Metadata) error {
+	metadata, err := s.Storage.GetMetadata(ctx, key)
+	if err != nil || metadata == nil {
+		metadata = fallback
+	}
+	return s.index.Update(key, metadata)
+}
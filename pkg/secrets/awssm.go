@@ -0,0 +1,96 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build awss3
+
+package secrets
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+
+	"github.com/aws/aws-sdk-go/aws"                                        //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+	"github.com/aws/aws-sdk-go/aws/arn"                                    //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+	"github.com/aws/aws-sdk-go/aws/awserr"                                 //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+	"github.com/aws/aws-sdk-go/aws/session"                                //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+	"github.com/aws/aws-sdk-go/service/secretsmanager"                     //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface" //nolint:staticcheck // Using v1 SDK, migration to v2 planned
+)
+
+func init() {
+	RegisterSource("awssm", NewAWSSecretsManagerSource(nil))
+}
+
+// AWSSecretsManagerSource reads secrets from AWS Secrets Manager. A
+// reference is a secret name or ARN, optionally followed by #field to
+// select a field of a JSON secret. Names are looked up in the region of
+// the AWS SDK's shared configuration; ARNs in their own region.
+type AWSSecretsManagerSource struct {
+	client func(region string) (secretsmanageriface.SecretsManagerAPI, error)
+}
+
+// NewAWSSecretsManagerSource creates an AWS Secrets Manager source. A nil
+// client creates clients with the AWS SDK's default credential chain.
+func NewAWSSecretsManagerSource(client secretsmanageriface.SecretsManagerAPI) *AWSSecretsManagerSource {
+	if client != nil {
+		return &AWSSecretsManagerSource{client: func(string) (secretsmanageriface.SecretsManagerAPI, error) {
+			return client, nil
+		}}
+	}
+	return &AWSSecretsManagerSource{client: func(region string) (secretsmanageriface.SecretsManagerAPI, error) {
+		cfg := &aws.Config{}
+		if region != "" {
+			cfg.Region = aws.String(region)
+		}
+		sess, err := session.NewSessionWithOptions(session.Options{
+			Config:            *cfg,
+			SharedConfigState: session.SharedConfigEnable,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return secretsmanager.New(sess), nil
+	}}
+}
+
+// Resolve reads the current version of the secret at ref.
+func (s *AWSSecretsManagerSource) Resolve(ctx context.Context, ref string) (string, error) {
+	id, field := splitField(ref)
+	region := ""
+	if parsed, err := arn.Parse(id); err == nil {
+		region = parsed.Region
+	}
+	client, err := s.client(region)
+	if err != nil {
+		return "", err
+	}
+	out, err := client.GetSecretValueWithContext(ctx, &secretsmanager.GetSecretValueInput{SecretId: aws.String(id)})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == secretsmanager.ErrCodeResourceNotFoundException {
+			return "", ErrSecretNotFound
+		}
+		return "", err
+	}
+	var secret string
+	switch {
+	case out.SecretString != nil:
+		secret = *out.SecretString
+	case out.SecretBinary != nil:
+		secret = string(out.SecretBinary)
+	default:
+		return "", fmt.Errorf("%w: secret %s has no value", common.ErrNotFound, id)
+	}
+	return selectField(secret, field)
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build gcpstorage
+
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+
+	"golang.org/x/oauth2/google"
+)
+
+// defaultSecretManagerEndpoint is the GCP Secret Manager API endpoint.
+const defaultSecretManagerEndpoint = "https://secretmanager.googleapis.com"
+
+// cloudPlatformScope is the OAuth scope used to access secrets.
+const cloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+
+func init() {
+	RegisterSource("gcpsm", NewGCPSecretManagerSource("", nil))
+}
+
+// GCPSecretManagerSource reads secrets from GCP Secret Manager. A reference
+// is a secret resource name, "projects/P/secrets/S" or
+// "projects/P/secrets/S/versions/V", or the short form "P/S"; the latest
+// version is read unless one is named. #field selects a field of a JSON
+// secret.
+type GCPSecretManagerSource struct {
+	endpoint string
+	client   *http.Client
+}
+
+// NewGCPSecretManagerSource creates a GCP Secret Manager source. An empty
+// endpoint uses the public API, and a nil client authenticates with
+// Application Default Credentials.
+func NewGCPSecretManagerSource(endpoint string, client *http.Client) *GCPSecretManagerSource {
+	if endpoint == "" {
+		endpoint = defaultSecretManagerEndpoint
+	}
+	return &GCPSecretManagerSource{endpoint: strings.TrimRight(endpoint, "/"), client: client}
+}
+
+// Resolve reads the secret version at ref.
+func (s *GCPSecretManagerSource) Resolve(ctx context.Context, ref string) (string, error) {
+	name, field := splitField(ref)
+	name, err := secretVersionName(name)
+	if err != nil {
+		return "", err
+	}
+	client := s.client
+	if client == nil {
+		if client, err = google.DefaultClient(ctx, cloudPlatformScope); err != nil {
+			return "", fmt.Errorf("%w: %v", common.ErrUnauthenticated, err)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.endpoint+"/v1/"+name+":access", nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", common.ErrUnavailable, err)
+	}
+	defer func() { _ = resp.Body.Close() }() // #nosec G104 -- Body is fully read
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return "", ErrSecretNotFound
+	case resp.StatusCode == http.StatusForbidden:
+		return "", fmt.Errorf("%w: secret manager denied access to %s", common.ErrPermissionDenied, name)
+	case resp.StatusCode != http.StatusOK:
+		return "", fmt.Errorf("%w: secret manager returned %s", common.ErrUnavailable, resp.Status)
+	}
+
+	var body struct {
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body); err != nil {
+		return "", err
+	}
+	secret, err := base64.StdEncoding.DecodeString(body.Payload.Data)
+	if err != nil {
+		return "", err
+	}
+	return selectField(string(secret), field)
+}
+
+// secretVersionName returns the secret version resource name of name.
+func secretVersionName(name string) (string, error) {
+	parts := strings.Split(strings.Trim(name, "/"), "/")
+	switch {
+	case len(parts) == 2 && parts[0] != "" && parts[1] != "":
+		return "projects/" + parts[0] + "/secrets/" + parts[1] + "/versions/latest", nil
+	case len(parts) == 4 && parts[0] == "projects" && parts[2] == "secrets":
+		return strings.Join(parts, "/") + "/versions/latest", nil
+	case len(parts) == 6 && parts[0] == "projects" && parts[2] == "secrets" && parts[4] == "versions":
+		return strings.Join(parts, "/"), nil
+	}
+	return "", fmt.Errorf("%w: %q is not a secret name", ErrInvalidReference, name)
+}
@@ -0,0 +1,225 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package secrets resolves references to secrets in configuration values,
+// so bucket credentials and encryption key material do not have to be
+// written into configuration files.
+//
+// A reference is a value of the form "scheme:reference", where scheme
+// names a registered source:
+//
+//	env:AWS_SECRET_ACCESS_KEY                      environment variable
+//	file:/run/secrets/s3-secret-key                file content
+//	vault:secret/data/objstore#secretKey           HashiCorp Vault KV secret
+//	awssm:prod/objstore#secretKey                  AWS Secrets Manager
+//	gcpsm:projects/p/secrets/objstore-key          GCP Secret Manager
+//
+// The optional #field selects a field of a secret holding a JSON object.
+// The awssm source is built with the awss3 build tag and gcpsm with
+// gcpstorage. Values whose scheme is not a registered source, such as
+// endpoint URLs, are left unchanged.
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+var (
+	// ErrInvalidReference is returned for a malformed secret reference.
+	ErrInvalidReference = fmt.Errorf("%w: invalid secret reference", common.ErrInvalidArgument)
+
+	// ErrSecretNotFound is returned when a referenced secret or field does
+	// not exist.
+	ErrSecretNotFound = fmt.Errorf("secret %w", common.ErrNotFound)
+
+	// ErrReferenceNotAllowed is returned by RejectReferences.
+	ErrReferenceNotAllowed = fmt.Errorf("%w: secret references are not allowed", common.ErrInvalidArgument)
+)
+
+// Source resolves references of one scheme. ref is the value after the
+// "scheme:" prefix.
+type Source interface {
+	Resolve(ctx context.Context, ref string) (string, error)
+}
+
+// SourceFunc adapts a function to a Source.
+type SourceFunc func(ctx context.Context, ref string) (string, error)
+
+// Resolve calls f.
+func (f SourceFunc) Resolve(ctx context.Context, ref string) (string, error) {
+	return f(ctx, ref)
+}
+
+var (
+	sourcesMu sync.RWMutex
+	sources   = make(map[string]Source)
+)
+
+func init() {
+	RegisterSource("env", SourceFunc(resolveEnv))
+	RegisterSource("file", SourceFunc(resolveFile))
+	RegisterSource("vault", NewVaultSource(nil))
+}
+
+// RegisterSource registers the source of a scheme, replacing any source
+// registered for it before.
+func RegisterSource(scheme string, source Source) {
+	sourcesMu.Lock()
+	defer sourcesMu.Unlock()
+	sources[scheme] = source
+}
+
+// lookup returns the source and reference of value, if value references
+// a secret.
+func lookup(value string) (Source, string, bool) {
+	scheme, ref, ok := strings.Cut(value, ":")
+	if !ok {
+		return nil, "", false
+	}
+	sourcesMu.RLock()
+	defer sourcesMu.RUnlock()
+	source, ok := sources[scheme]
+	return source, ref, ok
+}
+
+// IsReference reports whether value references a secret of a registered
+// source.
+func IsReference(value string) bool {
+	_, _, ok := lookup(value)
+	return ok
+}
+
+// Resolve returns the secret value references, or value unchanged if it
+// is not a reference.
+func Resolve(ctx context.Context, value string) (string, error) {
+	source, ref, ok := lookup(value)
+	if !ok {
+		return value, nil
+	}
+	if ref == "" {
+		return "", fmt.Errorf("%w: %q", ErrInvalidReference, value)
+	}
+	secret, err := source.Resolve(ctx, ref)
+	if err != nil {
+		scheme, _, _ := strings.Cut(value, ":")
+		return "", fmt.Errorf("resolve %s secret %q: %w", scheme, ref, err)
+	}
+	return secret, nil
+}
+
+// ResolveSettings returns a copy of settings with every secret reference
+// resolved. Settings without references are returned as is. Resolved
+// values are not resolved again, so a secret that happens to look like a
+// reference is used verbatim.
+func ResolveSettings(ctx context.Context, settings map[string]string) (map[string]string, error) {
+	if !HasReferences(settings) {
+		return settings, nil
+	}
+	resolved := make(map[string]string, len(settings))
+	for k, v := range settings {
+		secret, err := Resolve(ctx, v)
+		if err != nil {
+			return nil, fmt.Errorf("setting %s: %w", k, err)
+		}
+		resolved[k] = secret
+	}
+	return resolved, nil
+}
+
+// HasReferences reports whether any value of settings references a
+// secret.
+func HasReferences(settings map[string]string) bool {
+	for _, v := range settings {
+		if IsReference(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// RejectReferences returns ErrReferenceNotAllowed if any value of settings
+// references a secret. Servers call it on settings supplied by clients,
+// which must not be able to read the server's environment, files or
+// secret stores.
+func RejectReferences(settings map[string]string) error {
+	for k, v := range settings {
+		if IsReference(v) {
+			return fmt.Errorf("%w: setting %s", ErrReferenceNotAllowed, k)
+		}
+	}
+	return nil
+}
+
+// resolveEnv resolves env:NAME to the value of the environment variable.
+func resolveEnv(_ context.Context, name string) (string, error) {
+	v, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("%w: environment variable %s not set", ErrSecretNotFound, name)
+	}
+	return v, nil
+}
+
+// resolveFile resolves file:PATH to the content of the file, without a
+// trailing newline.
+func resolveFile(_ context.Context, path string) (string, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- Path is chosen by the operator
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// splitField splits a reference into the secret name and the #field.
+func splitField(ref string) (name, field string) {
+	if i := strings.LastIndex(ref, "#"); i >= 0 {
+		return ref[:i], ref[i+1:]
+	}
+	return ref, ""
+}
+
+// selectField returns field of a secret holding a JSON object, or the
+// whole secret if field is empty.
+func selectField(secret, field string) (string, error) {
+	if field == "" {
+		return secret, nil
+	}
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(secret), &fields); err != nil {
+		return "", fmt.Errorf("%w: field %s of a secret that is not a JSON object", ErrInvalidReference, field)
+	}
+	return fieldValue(fields, field)
+}
+
+// fieldValue returns field of fields as a string. Non-string values are
+// returned in their JSON encoding.
+func fieldValue(fields map[string]any, field string) (string, error) {
+	v, ok := fields[field]
+	if !ok {
+		return "", fmt.Errorf("%w: no field %s", ErrSecretNotFound, field)
+	}
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package secrets
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+func TestResolveSettings(t *testing.T) {
+	t.Setenv("OBJSTORE_TEST_SECRET", "from-env")
+	path := filepath.Join(t.TempDir(), "key")
+	if err := os.WriteFile(path, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	settings := map[string]string{
+		"accessKey": "env:OBJSTORE_TEST_SECRET",
+		"secretKey": "file:" + path,
+		"endpoint":  "https://s3.example.com",
+		"bucket":    "plain",
+	}
+	got, err := ResolveSettings(context.Background(), settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"accessKey": "from-env",
+		"secretKey": "from-file",
+		"endpoint":  "https://s3.example.com",
+		"bucket":    "plain",
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s = %q, want %q", k, got[k], v)
+		}
+	}
+	if settings["accessKey"] != "env:OBJSTORE_TEST_SECRET" {
+		t.Error("ResolveSettings modified its argument")
+	}
+}
+
+func TestResolveErrors(t *testing.T) {
+	ctx := context.Background()
+	if _, err := Resolve(ctx, "env:OBJSTORE_TEST_UNSET"); !errors.Is(err, common.ErrNotFound) {
+		t.Errorf("unset env: err = %v, want ErrNotFound", err)
+	}
+	if _, err := Resolve(ctx, "env:"); !errors.Is(err, ErrInvalidReference) {
+		t.Errorf("empty ref: err = %v, want ErrInvalidReference", err)
+	}
+	if _, err := ResolveSettings(ctx, map[string]string{"k": "file:/nonexistent/secret"}); err == nil {
+		t.Error("missing file: want error")
+	}
+}
+
+func TestSelectField(t *testing.T) {
+	got, err := selectField(`{"user":"u","port":5432}`, "port")
+	if err != nil || got != "5432" {
+		t.Errorf("selectField = %q, %v", got, err)
+	}
+	if _, err := selectField("not json", "user"); !errors.Is(err, ErrInvalidReference) {
+		t.Errorf("err = %v, want ErrInvalidReference", err)
+	}
+	if _, err := selectField(`{"user":"u"}`, "missing"); !errors.Is(err, ErrSecretNotFound) {
+		t.Errorf("err = %v, want ErrSecretNotFound", err)
+	}
+}
+
+func TestRejectReferences(t *testing.T) {
+	if err := RejectReferences(map[string]string{"endpoint": "https://example.com"}); err != nil {
+		t.Errorf("plain settings: %v", err)
+	}
+	err := RejectReferences(map[string]string{"secretKey": "file:/etc/passwd"})
+	if !errors.Is(err, ErrReferenceNotAllowed) || !errors.Is(err, common.ErrInvalidArgument) {
+		t.Errorf("err = %v, want ErrReferenceNotAllowed", err)
+	}
+}
+
+func TestVaultSource(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "tok" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/objstore":
+			_, _ = w.Write([]byte(`{"data":{"data":{"secretKey":"v2"},"metadata":{"version":3}}}`))
+		case "/v1/kv/objstore":
+			_, _ = w.Write([]byte(`{"data":{"secretKey":"v1"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	s := NewVaultSource(&VaultConfig{Address: srv.URL, Token: "tok"})
+	if got, err := s.Resolve(ctx, "secret/data/objstore#secretKey"); err != nil || got != "v2" {
+		t.Errorf("kv2 = %q, %v", got, err)
+	}
+	if got, err := s.Resolve(ctx, "kv/objstore"); err != nil || got != "v1" {
+		t.Errorf("kv1 = %q, %v", got, err)
+	}
+	if _, err := s.Resolve(ctx, "kv/missing"); !errors.Is(err, ErrSecretNotFound) {
+		t.Errorf("missing: err = %v, want ErrSecretNotFound", err)
+	}
+	denied := NewVaultSource(&VaultConfig{Address: srv.URL, Token: "bad"})
+	if _, err := denied.Resolve(ctx, "kv/objstore"); !errors.Is(err, common.ErrPermissionDenied) {
+		t.Errorf("denied: err = %v, want ErrPermissionDenied", err)
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// VaultConfig configures the vault source. Empty fields fall back to the
+// VAULT_ADDR, VAULT_TOKEN and VAULT_NAMESPACE environment variables when
+// a secret is resolved.
+type VaultConfig struct {
+	Address    string
+	Token      string
+	Namespace  string
+	HTTPClient *http.Client
+}
+
+// VaultSource reads secrets from HashiCorp Vault KV engines, version 1 or
+// 2. A reference is the API path of the secret, for example
+// "secret/data/objstore#secretKey" for a KV version 2 engine mounted at
+// secret. Without a #field the secret must hold exactly one field.
+type VaultSource struct {
+	cfg VaultConfig
+}
+
+// NewVaultSource creates a vault source. A nil cfg uses the environment.
+func NewVaultSource(cfg *VaultConfig) *VaultSource {
+	s := &VaultSource{}
+	if cfg != nil {
+		s.cfg = *cfg
+	}
+	if s.cfg.HTTPClient == nil {
+		s.cfg.HTTPClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	return s
+}
+
+// Resolve reads the secret at ref.
+func (s *VaultSource) Resolve(ctx context.Context, ref string) (string, error) {
+	path, field := splitField(ref)
+	addr := configOrEnv(s.cfg.Address, "VAULT_ADDR")
+	if addr == "" {
+		return "", fmt.Errorf("%w: VAULT_ADDR not set", common.ErrInvalidArgument)
+	}
+	url := strings.TrimRight(addr, "/") + "/v1/" + strings.TrimLeft(path, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	if token := configOrEnv(s.cfg.Token, "VAULT_TOKEN"); token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	if ns := configOrEnv(s.cfg.Namespace, "VAULT_NAMESPACE"); ns != "" {
+		req.Header.Set("X-Vault-Namespace", ns)
+	}
+
+	resp, err := s.cfg.HTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", common.ErrUnavailable, err)
+	}
+	defer func() { _ = resp.Body.Close() }() // #nosec G104 -- Body is fully read
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return "", ErrSecretNotFound
+	case resp.StatusCode == http.StatusForbidden:
+		return "", fmt.Errorf("%w: vault denied access to %s", common.ErrPermissionDenied, path)
+	case resp.StatusCode != http.StatusOK:
+		return "", fmt.Errorf("%w: vault returned %s", common.ErrUnavailable, resp.Status)
+	}
+
+	var body struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body); err != nil {
+		return "", err
+	}
+	fields := body.Data
+	// KV version 2 nests the secret under data.data, next to its metadata.
+	if nested, ok := fields["data"].(map[string]any); ok {
+		if _, ok := fields["metadata"]; ok {
+			fields = nested
+		}
+	}
+
+	if field == "" {
+		if len(fields) != 1 {
+			return "", fmt.Errorf("%w: vault secret %s has %d fields, select one with #field", ErrInvalidReference, path, len(fields))
+		}
+		for name := range fields {
+			field = name
+		}
+	}
+	return fieldValue(fields, field)
+}
+
+// configOrEnv returns v, falling back to the environment variable env.
+func configOrEnv(v, env string) string {
+	if v != "" {
+		return v
+	}
+	return os.Getenv(env)
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// ErrorBody is the JSON body of every error response of the HTTP transports
+// (REST and QUIC). Code is the machine-readable class of the failure (the
+// name of a common.ErrorCode, such as "NOT_FOUND"), so clients can branch on
+// it instead of parsing Message.
+type ErrorBody struct {
+	// Error is the HTTP status text, kept for clients that predate Code.
+	Error string `json:"error" example:"Not Found"`
+	// Code is the machine-readable error code.
+	Code string `json:"code" example:"NOT_FOUND"`
+	// Status is the HTTP status code of the response.
+	Status int `json:"status" example:"404"`
+	// Message is a human-readable description safe to show to clients.
+	Message string `json:"message" example:"object not found"`
+	// RequestID is the X-Request-ID of the request, when it has one.
+	RequestID string `json:"request_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	// Retryable reports whether retrying the request unchanged may succeed.
+	Retryable bool `json:"retryable" example:"false"`
+	// Details holds additional error-specific fields.
+	Details map[string]any `json:"details,omitempty"`
+}
+
+// NewErrorBody returns the error body for a response with the HTTP status
+// and message. Its code is the one the status stands for (see CodeForStatus).
+func NewErrorBody(status int, message string) *ErrorBody {
+	code := CodeForStatus(status)
+	return &ErrorBody{
+		Error:     http.StatusText(status),
+		Code:      code.String(),
+		Status:    status,
+		Message:   message,
+		Retryable: code.Retryable(),
+	}
+}
+
+// ErrorBodyFor returns the error body for err, with the status and
+// sanitized message HTTPStatus maps it to.
+func ErrorBodyFor(err error) *ErrorBody {
+	return NewErrorBody(HTTPStatus(err))
+}
+
+// CodeForStatus returns the error code an HTTP error status stands for. It
+// inverts HTTPStatus for the statuses it returns and classifies the other
+// statuses handlers respond with: other client errors are invalid
+// arguments, other server errors internal ones.
+func CodeForStatus(status int) common.ErrorCode {
+	switch status {
+	case http.StatusNotFound, http.StatusGone:
+		return common.CodeNotFound
+	case http.StatusConflict:
+		return common.CodeAlreadyExists
+	case http.StatusUnauthorized:
+		return common.CodeUnauthenticated
+	case http.StatusForbidden:
+		return common.CodePermissionDenied
+	case http.StatusTooManyRequests:
+		return common.CodeResourceExhausted
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		return common.CodeUnavailable
+	case http.StatusPreconditionFailed, http.StatusPreconditionRequired:
+		return common.CodePreconditionFailed
+	case 499:
+		return common.CodeCanceled
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return common.CodeDeadlineExceeded
+	}
+	if status >= 400 && status < 500 {
+		return common.CodeInvalidArgument
+	}
+	return common.CodeInternal
+}
+
+// WriteHTTP writes body as the JSON error response of a net/http handler.
+// Like http.Error, it drops a Content-Length set for the intended response.
+func WriteHTTP(w http.ResponseWriter, body *ErrorBody) {
+	w.Header().Del("Content-Length")
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(body.Status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -47,6 +47,8 @@ func HTTPStatus(err error) (int, string) {
 		return http.StatusTooManyRequests, "rate limit exceeded"
 	case common.CodeUnavailable:
 		return http.StatusServiceUnavailable, "service unavailable"
+	case common.CodePreconditionFailed:
+		return http.StatusPreconditionFailed, "precondition failed"
 	case common.CodeCanceled:
 		// 499 Client Closed Request (nginx convention).
 		return 499, "request canceled"
@@ -78,6 +80,8 @@ func GRPCStatus(err error) error {
 		return status.Error(codes.ResourceExhausted, "rate limit exceeded")
 	case common.CodeUnavailable:
 		return status.Error(codes.Unavailable, "service unavailable")
+	case common.CodePreconditionFailed:
+		return status.Error(codes.FailedPrecondition, "precondition failed")
 	case common.CodeCanceled:
 		return status.Error(codes.Canceled, "request canceled")
 	case common.CodeDeadlineExceeded:
@@ -108,6 +112,8 @@ func JSONRPCError(err error) (int, string) {
 		return jsonrpc.CodeRateLimited, "rate limit exceeded"
 	case common.CodeUnavailable:
 		return jsonrpc.CodeUnavailable, "service unavailable"
+	case common.CodePreconditionFailed:
+		return jsonrpc.CodePreconditionFailed, "precondition failed"
 	case common.CodeCanceled:
 		return jsonrpc.CodeInternal, "request canceled"
 	case common.CodeDeadlineExceeded:
@@ -15,9 +15,13 @@ package errors
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io/fs"
 	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/jeremyhahn/go-objstore/pkg/common"
@@ -46,6 +50,16 @@ func TestTaxonomyMatrix(t *testing.T) {
 		{"unauthenticated", common.ErrUnauthenticated, http.StatusUnauthorized, codes.Unauthenticated, jsonrpc.CodeUnauthenticated},
 		{"resource exhausted", common.ErrResourceExhausted, http.StatusTooManyRequests, codes.ResourceExhausted, jsonrpc.CodeRateLimited},
 		{"unavailable", common.ErrUnavailable, http.StatusServiceUnavailable, codes.Unavailable, jsonrpc.CodeUnavailable},
+		{"not found", common.ErrNotFound, http.StatusNotFound, codes.NotFound, jsonrpc.CodeNotFound},
+		{"precondition failed", common.ErrPreconditionFailed, http.StatusPreconditionFailed, codes.FailedPrecondition, jsonrpc.CodePreconditionFailed},
+		{"access denied", common.ErrAccessDenied, http.StatusForbidden, codes.PermissionDenied, jsonrpc.CodeForbidden},
+		{"quota exceeded", common.ErrQuotaExceeded, http.StatusTooManyRequests, codes.ResourceExhausted, jsonrpc.CodeRateLimited},
+		{"backend unavailable", common.ErrBackendUnavailable, http.StatusServiceUnavailable, codes.Unavailable, jsonrpc.CodeUnavailable},
+		{
+			"provider error",
+			common.ProviderError(common.ErrPreconditionFailed, "k", fmt.Errorf("PreconditionFailed: etag mismatch")),
+			http.StatusPreconditionFailed, codes.FailedPrecondition, jsonrpc.CodePreconditionFailed,
+		},
 		{"canceled", context.Canceled, 499, codes.Canceled, jsonrpc.CodeInternal},
 		{"deadline", context.DeadlineExceeded, http.StatusGatewayTimeout, codes.DeadlineExceeded, jsonrpc.CodeInternal},
 		{"unclassified", fmt.Errorf("disk on fire"), http.StatusInternalServerError, codes.Internal, jsonrpc.CodeInternal},
@@ -89,3 +103,81 @@ func TestNilError(t *testing.T) {
 		t.Errorf("GRPCStatus(nil) = %v, want nil", err)
 	}
 }
+
+// TestErrorBodyFor verifies that error bodies carry the code the error
+// classifies as, whichever status it maps to.
+func TestErrorBodyFor(t *testing.T) {
+	for _, err := range []error{
+		common.ErrKeyNotFound,
+		common.ErrAlreadyExists,
+		common.ErrInvalidArgument,
+		common.ErrPermissionDenied,
+		common.ErrUnauthenticated,
+		common.ErrResourceExhausted,
+		common.ErrUnavailable,
+		common.ErrPreconditionFailed,
+		context.Canceled,
+		context.DeadlineExceeded,
+		fmt.Errorf("disk on fire"),
+	} {
+		body := ErrorBodyFor(err)
+		code := common.Classify(err)
+		if body.Code != code.String() || body.Retryable != code.Retryable() {
+			t.Errorf("ErrorBodyFor(%v) code = %s (retryable %v), want %s (retryable %v)", err, body.Code, body.Retryable, code, code.Retryable())
+		}
+		if status, message := HTTPStatus(err); body.Status != status || body.Message != message {
+			t.Errorf("ErrorBodyFor(%v) = %d %q, want %d %q", err, body.Status, body.Message, status, message)
+		}
+	}
+}
+
+func TestCodeForStatus(t *testing.T) {
+	tests := []struct {
+		status int
+		want   common.ErrorCode
+	}{
+		{http.StatusBadRequest, common.CodeInvalidArgument},
+		{http.StatusMethodNotAllowed, common.CodeInvalidArgument},
+		{http.StatusRequestEntityTooLarge, common.CodeInvalidArgument},
+		{http.StatusUnprocessableEntity, common.CodeInvalidArgument},
+		{http.StatusGone, common.CodeNotFound},
+		{http.StatusRequestTimeout, common.CodeDeadlineExceeded},
+		{http.StatusBadGateway, common.CodeUnavailable},
+		{http.StatusNotImplemented, common.CodeInternal},
+		{http.StatusInternalServerError, common.CodeInternal},
+	}
+	for _, tt := range tests {
+		if got := CodeForStatus(tt.status); got != tt.want {
+			t.Errorf("CodeForStatus(%d) = %s, want %s", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestWriteHTTP(t *testing.T) {
+	w := httptest.NewRecorder()
+	body := NewErrorBody(http.StatusServiceUnavailable, "service unavailable")
+	body.RequestID = "req-1"
+	WriteHTTP(w, body)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("body %q is not JSON: %v", w.Body.String(), err)
+	}
+	want := map[string]any{
+		"error":      "Service Unavailable",
+		"code":       "UNAVAILABLE",
+		"status":     float64(503),
+		"message":    "service unavailable",
+		"request_id": "req-1",
+		"retryable":  true,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("body = %v, want %v", got, want)
+	}
+}
@@ -17,10 +17,12 @@ import (
 	"context"
 	"testing"
 
+	objstorepb "github.com/jeremyhahn/go-objstore/api/proto"
 	"github.com/jeremyhahn/go-objstore/pkg/adapters"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 func ctxWithPrincipal(p adapters.Principal) context.Context {
@@ -131,3 +133,55 @@ func TestAuthorizationStreamInterceptor_RBACEnforced(t *testing.T) {
 		}
 	})
 }
+
+// recvServerStream delivers a single request message to RecvMsg.
+type recvServerStream struct {
+	mockServerStream
+	msg *objstorepb.GetRequest
+}
+
+func (s *recvServerStream) RecvMsg(m any) error {
+	proto.Merge(m.(*objstorepb.GetRequest), s.msg)
+	return nil
+}
+
+// TestAuthorization_ScopedToken verifies that a principal carrying a token
+// scope is limited to keys under its prefix, on unary calls by the request
+// key and on streams by the key of each received message.
+func TestAuthorization_ScopedToken(t *testing.T) {
+	authz := adapters.NewScopedAuthorizer(adapters.NewNoOpAuthorizer())
+	ctx := ctxWithPrincipal(adapters.Principal{ID: "worker", Scope: &adapters.TokenScope{
+		Prefix:  "jobs/42/",
+		Actions: []string{adapters.ActionRead, adapters.ActionWrite},
+	}})
+
+	unary := AuthorizationUnaryInterceptor(authz, adapters.NewNoOpLogger())
+	ok := func(context.Context, any) (any, error) { return nil, nil }
+	for _, tt := range []struct {
+		method string
+		req    any
+		want   codes.Code
+	}{
+		{"/objstore.ObjectStore/Put", &objstorepb.PutRequest{Key: "jobs/42/out.bin"}, codes.OK},
+		{"/objstore.ObjectStore/Put", &objstorepb.PutRequest{Key: "jobs/43/out.bin"}, codes.PermissionDenied},
+		{"/objstore.ObjectStore/Delete", &objstorepb.DeleteRequest{Key: "jobs/42/out.bin"}, codes.PermissionDenied},
+		{"/objstore.ObjectStore/List", &objstorepb.ListRequest{Prefix: "jobs/42/"}, codes.PermissionDenied},
+		{"/objstore.ObjectStore/AddPolicy", &objstorepb.AddPolicyRequest{}, codes.PermissionDenied},
+	} {
+		if _, err := unary(ctx, tt.req, &grpc.UnaryServerInfo{FullMethod: tt.method}, ok); status.Code(err) != tt.want {
+			t.Errorf("%s %v = %v, want %v", tt.method, tt.req, err, tt.want)
+		}
+	}
+
+	stream := AuthorizationStreamInterceptor(authz, adapters.NewNoOpLogger())
+	recv := func(_ any, ss grpc.ServerStream) error { return ss.RecvMsg(&objstorepb.GetRequest{}) }
+	for key, want := range map[string]codes.Code{
+		"jobs/42/in.bin": codes.OK,
+		"jobs/7/in.bin":  codes.PermissionDenied,
+	} {
+		ss := &recvServerStream{mockServerStream: mockServerStream{ctx: ctx}, msg: &objstorepb.GetRequest{Key: key}}
+		if err := stream(nil, ss, &grpc.StreamServerInfo{FullMethod: "/objstore.ObjectStore/Get"}, recv); status.Code(err) != want {
+			t.Errorf("Get stream for %q = %v, want %v", key, err, want)
+		}
+	}
+}
@@ -1090,3 +1090,28 @@ func TestApplyPolicies_PrefixFiltering(t *testing.T) {
 		t.Logf("Expected 2 objects processed (logs/ prefix), got %d", resp.ObjectsProcessed)
 	}
 }
+
+func TestWithIdempotency(t *testing.T) {
+	opts := DefaultServerOptions()
+	if opts.EnableIdempotency {
+		t.Error("Idempotency should be disabled by default")
+	}
+
+	config := &middleware.IdempotencyConfig{Window: time.Hour}
+	WithIdempotency(true, config)(opts)
+
+	if !opts.EnableIdempotency {
+		t.Error("Idempotency should be enabled")
+	}
+	if opts.IdempotencyConfig != config {
+		t.Error("Idempotency config not set correctly")
+	}
+
+	// Test with nil config
+	opts2 := DefaultServerOptions()
+	WithIdempotency(true, nil)(opts2)
+
+	if opts2.IdempotencyConfig == nil {
+		t.Error("Idempotency config should keep its default")
+	}
+}
@@ -15,17 +15,23 @@ package grpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"math"
+	"strings"
 	"time"
 
 	objstorepb "github.com/jeremyhahn/go-objstore/api/proto"
 	"github.com/jeremyhahn/go-objstore/pkg/adapters"
 	"github.com/jeremyhahn/go-objstore/pkg/audit"
+	"github.com/jeremyhahn/go-objstore/pkg/changefeed"
 	"github.com/jeremyhahn/go-objstore/pkg/common"
 	"github.com/jeremyhahn/go-objstore/pkg/factory"
 	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+	"github.com/jeremyhahn/go-objstore/pkg/secrets"
 	servererrors "github.com/jeremyhahn/go-objstore/pkg/server/errors"
+	"github.com/jeremyhahn/go-objstore/pkg/version"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
@@ -39,6 +45,11 @@ const (
 	principalUnknown = "unknown"
 )
 
+// APIVersion is the revision of the objstore.v1 API implemented by this
+// server, reported in HealthResponse.api_version. It is incremented whenever
+// RPCs or fields are added to the package.
+const APIVersion int32 = 4
+
 // Error variables
 var (
 	ErrPoliciesCountExceedsRange = fmt.Errorf("policies count exceeds int32 range")
@@ -75,12 +86,7 @@ func (s *Server) Put(ctx context.Context, req *objstorepb.PutRequest) (*objstore
 	reader := &bytesReader{data: req.Data}
 
 	// Store the object using facade
-	var err error
-	if metadata != nil {
-		err = objstore.PutWithMetadata(ctx, s.keyRef(req.Key), reader, metadata)
-	} else {
-		err = objstore.PutWithContext(ctx, s.keyRef(req.Key), reader)
-	}
+	info, err := objstore.PutWithInfo(ctx, s.keyRef(req.Key), reader, metadata)
 
 	// Audit logging
 	auditLogger := audit.GetAuditLogger(ctx)
@@ -100,17 +106,19 @@ func (s *Server) Put(ctx context.Context, req *objstorepb.PutRequest) (*objstore
 		userID, principal, s.backend, req.Key, ipAddress, requestID, bytesTransferred,
 		audit.ResultSuccess, nil)
 
-	// Get the ETag from metadata if available
-	etag := ""
-	if metadata != nil {
-		etag = metadata.ETag
-	}
-
-	return &objstorepb.PutResponse{
+	resp := &objstorepb.PutResponse{
 		Success: true,
 		Message: "Object stored successfully",
-		Etag:    etag,
-	}, nil
+	}
+	if written := info.Metadata; written != nil {
+		resp.Etag = written.ETag
+		resp.Version = written.Version
+		resp.Size = written.Size
+		if !written.LastModified.IsZero() {
+			resp.LastModified = timestamppb.New(written.LastModified)
+		}
+	}
+	return resp, nil
 }
 
 // Get retrieves an object from the backend with streaming support.
@@ -319,8 +327,10 @@ func (s *Server) UpdateMetadata(ctx context.Context, req *objstorepb.UpdateMetad
 // Health performs a health check.
 func (s *Server) Health(ctx context.Context, req *objstorepb.HealthRequest) (*objstorepb.HealthResponse, error) {
 	return &objstorepb.HealthResponse{
-		Status:  objstorepb.HealthResponse_SERVING,
-		Message: "Service is healthy",
+		Status:        objstorepb.HealthResponse_SERVING,
+		Message:       "Service is healthy",
+		ApiVersion:    APIVersion,
+		ServerVersion: version.Get(),
 	}, nil
 }
 
@@ -366,6 +376,129 @@ func (s *Server) Archive(ctx context.Context, req *objstorepb.ArchiveRequest) (*
 	}, nil
 }
 
+// RestoreFromArchive copies an object back from a readable archival backend.
+func (s *Server) RestoreFromArchive(ctx context.Context, req *objstorepb.RestoreFromArchiveRequest) (*objstorepb.RestoreFromArchiveResponse, error) {
+	if req.Key == "" {
+		return nil, status.Error(codes.InvalidArgument, "key is required")
+	}
+
+	if req.SourceType == "" {
+		return nil, status.Error(codes.InvalidArgument, "source_type is required")
+	}
+
+	if err := secrets.RejectReferences(req.SourceSettings); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	source, err := factory.NewStorage(req.SourceType, req.SourceSettings)
+	if errors.Is(err, factory.ErrArchiveOnlyBackend) {
+		return nil, status.Errorf(codes.FailedPrecondition,
+			"%s is archive-only and cannot be read back; retrieve the object with the provider first", req.SourceType)
+	}
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	err = objstore.RestoreFromArchive(ctx, s.keyRef(req.Key), source, req.SourceKey)
+
+	// Audit logging
+	auditLogger := audit.GetAuditLogger(ctx)
+	principal, userID := extractGRPCPrincipal(ctx)
+	requestID := audit.GetRequestID(ctx)
+	ipAddress := extractGRPCClientIP(ctx)
+
+	if err != nil {
+		_ = auditLogger.LogObjectMutation(ctx, audit.EventObjectRestored,
+			userID, principal, s.backend, req.Key, ipAddress, requestID, 0,
+			audit.ResultFailure, err)
+		return nil, mapError(err)
+	}
+
+	var size int64
+	if md, err := objstore.GetMetadata(ctx, s.keyRef(req.Key)); err == nil && md != nil {
+		size = md.Size
+	}
+
+	_ = auditLogger.LogObjectMutation(ctx, audit.EventObjectRestored,
+		userID, principal, s.backend, req.Key, ipAddress, requestID, size,
+		audit.ResultSuccess, nil)
+
+	return &objstorepb.RestoreFromArchiveResponse{
+		Success: true,
+		Message: "object restored successfully",
+		Size:    size,
+	}, nil
+}
+
+// Search finds objects whose key or metadata match a query.
+func (s *Server) Search(ctx context.Context, req *objstorepb.SearchRequest) (*objstorepb.SearchResponse, error) {
+	if strings.TrimSpace(req.Query) == "" {
+		return nil, status.Error(codes.InvalidArgument, "query is required")
+	}
+	if req.Limit < 0 {
+		return nil, status.Error(codes.InvalidArgument, "limit must not be negative")
+	}
+
+	docs, err := objstore.Search(ctx, s.backend, req.Query, int(req.Limit))
+	if errors.Is(err, objstore.ErrSearchNotEnabled) {
+		return nil, status.Error(codes.Unimplemented, "search is not enabled on this server")
+	}
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	objects := make([]*objstorepb.ObjectInfo, len(docs))
+	for i, doc := range docs {
+		objects[i] = &objstorepb.ObjectInfo{
+			Key: doc.Key,
+			Metadata: metadataToProto(&common.Metadata{
+				ContentType:  doc.ContentType,
+				Size:         doc.Size,
+				LastModified: doc.LastModified,
+				ETag:         doc.ETag,
+				Custom:       doc.Custom,
+			}),
+		}
+	}
+	return &objstorepb.SearchResponse{Objects: objects}, nil
+}
+
+// GetChanges returns the changes made after a sequence number, oldest first.
+func (s *Server) GetChanges(ctx context.Context, req *objstorepb.GetChangesRequest) (*objstorepb.GetChangesResponse, error) {
+	if req.Limit < 0 {
+		return nil, status.Error(codes.InvalidArgument, "limit must not be negative")
+	}
+
+	page, err := objstore.GetChanges(ctx, s.backend, req.Since, int(req.Limit))
+	switch {
+	case errors.Is(err, objstore.ErrChangeFeedNotEnabled):
+		return nil, status.Error(codes.Unimplemented, "change feed is not enabled on this server")
+	case errors.Is(err, changefeed.ErrSequenceExpired):
+		// Tell the consumer why it must resynchronize from a full listing.
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case err != nil:
+		return nil, mapError(err)
+	}
+
+	changes := make([]*objstorepb.Change, len(page.Changes))
+	for i, change := range page.Changes {
+		changes[i] = &objstorepb.Change{
+			Sequence:  change.Sequence,
+			Op:        change.Op,
+			Key:       change.Key,
+			Etag:      change.ETag,
+			Size:      change.Size,
+			Timestamp: timestamppb.New(change.Timestamp),
+		}
+	}
+	return &objstorepb.GetChangesResponse{
+		Changes: changes,
+		Next:    page.Next,
+		Latest:  page.Latest,
+		More:    page.More,
+	}, nil
+}
+
 // AddPolicy adds a new lifecycle policy.
 func (s *Server) AddPolicy(ctx context.Context, req *objstorepb.AddPolicyRequest) (*objstorepb.AddPolicyResponse, error) {
 	if req.Policy == nil {
@@ -447,17 +580,23 @@ func (s *Server) ApplyPolicies(ctx context.Context, req *objstorepb.ApplyPolicie
 		return nil, mapError(err)
 	}
 
+	deleted, err := objstore.DeleteExpired(ctx, s.backend)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	expired := int32(min(deleted, math.MaxInt32)) // #nosec G115 -- clamped to int32
+
 	if len(policies) == 0 {
 		return &objstorepb.ApplyPoliciesResponse{
 			Success:          true,
 			PoliciesCount:    0,
-			ObjectsProcessed: 0,
+			ObjectsProcessed: expired,
 			Message:          "no lifecycle policies to apply",
 		}, nil
 	}
 
 	// Apply policies by listing objects and checking retention
-	objectsProcessed := int32(0)
+	objectsProcessed := expired
 	opts := &common.ListOptions{
 		Prefix: "",
 	}
@@ -662,5 +801,8 @@ func lifecyclePolicyToProto(p *common.LifecyclePolicy) *objstorepb.LifecyclePoli
 
 // createArchiver creates an archiver from factory based on destination type.
 func createArchiver(destinationType string, settings map[string]string) (common.Archiver, error) {
+	if err := secrets.RejectReferences(settings); err != nil {
+		return nil, err
+	}
 	return factory.NewArchiver(destinationType, settings)
 }
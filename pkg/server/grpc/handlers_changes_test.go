@@ -0,0 +1,71 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package grpc
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	objstorepb "github.com/jeremyhahn/go-objstore/api/proto"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetChanges(t *testing.T) {
+	server, err := newTestServer(t, memory.New())
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	ctx := context.Background()
+
+	_, err = server.GetChanges(ctx, &objstorepb.GetChangesRequest{})
+	if status.Code(err) != codes.Unimplemented {
+		t.Errorf("GetChanges() before EnableChangeFeed error = %v, want Unimplemented", err)
+	}
+
+	if err := objstore.EnableChangeFeed("", &objstore.ChangeFeedConfig{MaxEntries: 2}); err != nil {
+		t.Fatalf("EnableChangeFeed() error = %v", err)
+	}
+	for _, key := range []string{"a.txt", "b.txt", "c.txt"} {
+		if err := objstore.PutWithContext(ctx, key, strings.NewReader("data")); err != nil {
+			t.Fatalf("Put() error = %v", err)
+		}
+	}
+
+	resp, err := server.GetChanges(ctx, &objstorepb.GetChangesRequest{Since: 1, Limit: 1})
+	if err != nil {
+		t.Fatalf("GetChanges() error = %v", err)
+	}
+	if len(resp.Changes) != 1 || resp.Next != 2 || resp.Latest != 3 || !resp.More {
+		t.Fatalf("GetChanges() = %+v", resp)
+	}
+	change := resp.Changes[0]
+	if change.Sequence != 2 || change.Op != "put" || change.Key != "b.txt" || change.Size != 4 || change.Etag == "" || change.Timestamp == nil {
+		t.Errorf("change = %+v", change)
+	}
+
+	// The first change has been trimmed.
+	_, err = server.GetChanges(ctx, &objstorepb.GetChangesRequest{})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("GetChanges(expired) error = %v, want FailedPrecondition", err)
+	}
+	_, err = server.GetChanges(ctx, &objstorepb.GetChangesRequest{Limit: -1})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("GetChanges(negative limit) error = %v, want InvalidArgument", err)
+	}
+}
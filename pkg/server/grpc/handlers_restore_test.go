@@ -0,0 +1,178 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package grpc
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	objstorepb "github.com/jeremyhahn/go-objstore/api/proto"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/factory"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+	"github.com/jeremyhahn/go-objstore/pkg/version"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRestoreFromArchive(t *testing.T) {
+	archiveDir := t.TempDir()
+	archive, err := factory.NewStorage("local", map[string]string{"path": archiveDir})
+	if err != nil {
+		t.Fatalf("NewStorage() error = %v", err)
+	}
+	err = archive.PutWithMetadata(context.Background(), "cold/report.txt", strings.NewReader("archived data"), &common.Metadata{
+		ContentType: "text/plain",
+		Custom:      map[string]string{"author": "alice"},
+	})
+	if err != nil {
+		t.Fatalf("PutWithMetadata() error = %v", err)
+	}
+
+	server, err := newTestServer(t, memory.New())
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	ctx := context.Background()
+
+	resp, err := server.RestoreFromArchive(ctx, &objstorepb.RestoreFromArchiveRequest{
+		Key:            "restored/report.txt",
+		SourceKey:      "cold/report.txt",
+		SourceType:     "local",
+		SourceSettings: map[string]string{"path": archiveDir},
+	})
+	if err != nil {
+		t.Fatalf("RestoreFromArchive() error = %v", err)
+	}
+	if !resp.Success || resp.Size != int64(len("archived data")) {
+		t.Errorf("RestoreFromArchive() = %+v", resp)
+	}
+
+	rc, err := objstore.GetWithContext(ctx, "restored/report.txt")
+	if err != nil {
+		t.Fatalf("GetWithContext() error = %v", err)
+	}
+	data, _ := io.ReadAll(rc)
+	_ = rc.Close()
+	if string(data) != "archived data" {
+		t.Errorf("restored data = %q", data)
+	}
+	md, err := objstore.GetMetadata(ctx, "restored/report.txt")
+	if err != nil {
+		t.Fatalf("GetMetadata() error = %v", err)
+	}
+	if md.ContentType != "text/plain" || md.Custom["author"] != "alice" {
+		t.Errorf("restored metadata = %+v", md)
+	}
+}
+
+func TestRestoreFromArchive_Errors(t *testing.T) {
+	server, err := newTestServer(t, memory.New())
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		req  *objstorepb.RestoreFromArchiveRequest
+		want codes.Code
+	}{
+		{"missing key", &objstorepb.RestoreFromArchiveRequest{SourceType: "local"}, codes.InvalidArgument},
+		{"missing source type", &objstorepb.RestoreFromArchiveRequest{Key: "a"}, codes.InvalidArgument},
+		{"unknown source type", &objstorepb.RestoreFromArchiveRequest{Key: "a", SourceType: "tape"}, codes.InvalidArgument},
+		{"archive-only source", &objstorepb.RestoreFromArchiveRequest{Key: "a", SourceType: "glacier"}, codes.FailedPrecondition},
+		{"missing object", &objstorepb.RestoreFromArchiveRequest{
+			Key:            "a",
+			SourceType:     "local",
+			SourceSettings: map[string]string{"path": t.TempDir()},
+		}, codes.NotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := server.RestoreFromArchive(context.Background(), tt.req)
+			if status.Code(err) != tt.want {
+				t.Errorf("RestoreFromArchive() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearch(t *testing.T) {
+	server, err := newTestServer(t, memory.New())
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	ctx := context.Background()
+
+	_, err = server.Search(ctx, &objstorepb.SearchRequest{Query: "report"})
+	if status.Code(err) != codes.Unimplemented {
+		t.Errorf("Search() before EnableSearch error = %v, want Unimplemented", err)
+	}
+
+	if err := objstore.EnableSearch("", nil); err != nil {
+		t.Fatalf("EnableSearch() error = %v", err)
+	}
+	err = objstore.PutWithMetadata(ctx, "docs/report.txt", strings.NewReader("data"), &common.Metadata{
+		ContentType: "text/plain",
+		Custom:      map[string]string{"author": "alice"},
+	})
+	if err != nil {
+		t.Fatalf("PutWithMetadata() error = %v", err)
+	}
+
+	resp, err := server.Search(ctx, &objstorepb.SearchRequest{Query: "author:alice"})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(resp.Objects) != 1 || resp.Objects[0].Key != "docs/report.txt" {
+		t.Fatalf("Search() = %v", resp.Objects)
+	}
+	if md := resp.Objects[0].Metadata; md.ContentType != "text/plain" || md.Custom["author"] != "alice" || md.Size != 4 {
+		t.Errorf("Search() metadata = %+v", md)
+	}
+
+	resp, err = server.Search(ctx, &objstorepb.SearchRequest{Query: "author:bob"})
+	if err != nil || len(resp.Objects) != 0 {
+		t.Errorf("Search(no match) = %v, %v", resp, err)
+	}
+
+	for _, req := range []*objstorepb.SearchRequest{{Query: " "}, {Query: "report", Limit: -1}} {
+		if _, err := server.Search(ctx, req); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Search(%+v) error = %v, want InvalidArgument", req, err)
+		}
+	}
+}
+
+func TestHealth_ReportsVersions(t *testing.T) {
+	server, err := newTestServer(t, memory.New())
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+
+	resp, err := server.Health(context.Background(), &objstorepb.HealthRequest{})
+	if err != nil {
+		t.Fatalf("Health() error = %v", err)
+	}
+	if resp.ApiVersion != APIVersion {
+		t.Errorf("ApiVersion = %d, want %d", resp.ApiVersion, APIVersion)
+	}
+	if resp.ServerVersion != version.Get() {
+		t.Errorf("ServerVersion = %q, want %q", resp.ServerVersion, version.Get())
+	}
+}
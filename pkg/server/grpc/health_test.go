@@ -0,0 +1,162 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	objstorepb "github.com/jeremyhahn/go-objstore/api/proto"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+)
+
+// dialTestServer opens a plain client connection to a server started by
+// setupTestServer.
+func dialTestServer(t *testing.T, server *Server) *grpc.ClientConn {
+	t.Helper()
+	conn, err := grpc.NewClient(server.GetAddress(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to dial server: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func TestHealthService(t *testing.T) {
+	server, _, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	health := grpc_health_v1.NewHealthClient(dialTestServer(t, server))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for _, service := range []string{"", objstorepb.ObjectStore_ServiceDesc.ServiceName} {
+		resp, err := health.Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: service})
+		if err != nil {
+			t.Fatalf("Check(%q) failed: %v", service, err)
+		}
+		if resp.Status != grpc_health_v1.HealthCheckResponse_SERVING {
+			t.Errorf("Check(%q) = %v, want SERVING", service, resp.Status)
+		}
+	}
+
+	_, err := health.Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: "unknown.Service"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Check(unknown) error = %v, want NotFound", err)
+	}
+}
+
+func TestHealthService_NotServingOnStop(t *testing.T) {
+	server, _, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	health := grpc_health_v1.NewHealthClient(dialTestServer(t, server))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := health.Watch(ctx, &grpc_health_v1.HealthCheckRequest{})
+	if err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv failed: %v", err)
+	}
+	if resp.Status != grpc_health_v1.HealthCheckResponse_SERVING {
+		t.Fatalf("initial status = %v, want SERVING", resp.Status)
+	}
+
+	go server.Stop()
+
+	resp, err = stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv after Stop failed: %v", err)
+	}
+	if resp.Status != grpc_health_v1.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("status after Stop = %v, want NOT_SERVING", resp.Status)
+	}
+	cancel()
+}
+
+func TestHealthService_Disabled(t *testing.T) {
+	server, _, cleanup := setupTestServer(t, WithHealthCheck(false))
+	defer cleanup()
+
+	health := grpc_health_v1.NewHealthClient(dialTestServer(t, server))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := health.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+	if status.Code(err) != codes.Unimplemented {
+		t.Errorf("Check error = %v, want Unimplemented", err)
+	}
+}
+
+func TestReflectionService(t *testing.T) {
+	server, _, cleanup := setupTestServer(t, WithReflection(true))
+	defer cleanup()
+
+	client := reflectionpb.NewServerReflectionClient(dialTestServer(t, server))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.ServerReflectionInfo(ctx)
+	if err != nil {
+		t.Fatalf("ServerReflectionInfo failed: %v", err)
+	}
+	err = stream.Send(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+	})
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv failed: %v", err)
+	}
+
+	services := make(map[string]bool)
+	for _, svc := range resp.GetListServicesResponse().GetService() {
+		services[svc.Name] = true
+	}
+	for _, want := range []string{objstorepb.ObjectStore_ServiceDesc.ServiceName, grpc_health_v1.Health_ServiceDesc.ServiceName} {
+		if !services[want] {
+			t.Errorf("reflection did not list %s (got %v)", want, services)
+		}
+	}
+}
+
+func TestReflectionService_DisabledByDefault(t *testing.T) {
+	server, _, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	client := reflectionpb.NewServerReflectionClient(dialTestServer(t, server))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.ServerReflectionInfo(ctx)
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.Unimplemented {
+		t.Errorf("reflection error = %v, want Unimplemented", err)
+	}
+}
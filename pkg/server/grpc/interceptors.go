@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/audit"
 	"github.com/jeremyhahn/go-objstore/pkg/server/metrics"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -332,6 +333,7 @@ func AuthenticationUnaryInterceptor(authenticator adapters.Authenticator, logger
 		// and the shared adapters key (used by the audit interceptor).
 		ctx = context.WithValue(ctx, principalContextKey, *principal)
 		ctx = context.WithValue(ctx, adapters.PrincipalContextKey{}, principal)
+		audit.SetPrincipal(ctx, principal)
 
 		return handler(ctx, req)
 	}
@@ -383,6 +385,7 @@ func AuthenticationStreamInterceptor(authenticator adapters.Authenticator, logge
 		// and the shared adapters key (used by the audit interceptor).
 		ctx = context.WithValue(ctx, principalContextKey, *principal)
 		ctx = context.WithValue(ctx, adapters.PrincipalContextKey{}, principal)
+		audit.SetPrincipal(ctx, principal)
 
 		// Create a wrapped server stream with the updated context
 		wrappedStream := &wrappedServerStream{
@@ -412,6 +415,7 @@ var methodActions = map[string]struct {
 	"UpdateMetadata":          {adapters.ActionWrite, resourceObject},
 	"Delete":                  {adapters.ActionDelete, resourceObject},
 	"List":                    {adapters.ActionList, resourceObject},
+	"GetChanges":              {adapters.ActionList, resourceObject},
 	"Archive":                 {adapters.ActionAdmin, resourceObject},
 	"AddPolicy":               {adapters.ActionAdmin, adapters.ResourcePolicy},
 	"RemovePolicy":            {adapters.ActionAdmin, adapters.ResourcePolicy},
@@ -445,6 +449,19 @@ func actionForMethod(fullMethod string) (action, resource string) {
 	return adapters.ActionAdmin, resourceObject
 }
 
+// requestResource returns the object key, or list prefix, carried by an
+// object-plane request so authorizers see the same resources as on REST. It
+// returns fallback for requests that name neither.
+func requestResource(req any, fallback string) string {
+	switch r := req.(type) {
+	case interface{ GetKey() string }:
+		return r.GetKey()
+	case interface{ GetPrefix() string }:
+		return r.GetPrefix()
+	}
+	return fallback
+}
+
 // principalFromContext extracts the authenticated principal stored by the
 // authentication interceptor.
 func principalFromContext(ctx context.Context) *adapters.Principal {
@@ -475,6 +492,9 @@ func AuthorizationUnaryInterceptor(authorizer adapters.Authorizer, logger adapte
 		}
 
 		action, resource := actionForMethod(info.FullMethod)
+		if resource == resourceObject {
+			resource = requestResource(req, resource)
+		}
 		if err := authorizer.Authorize(ctx, principal, action, resource); err != nil {
 			logger.Warn(ctx, "Authorization denied",
 				adapters.Field{Key: fieldMethod, Value: info.FullMethod},
@@ -508,18 +528,45 @@ func AuthorizationStreamInterceptor(authorizer adapters.Authorizer, logger adapt
 		}
 
 		action, resource := actionForMethod(info.FullMethod)
-		if err := authorizer.Authorize(ctx, principal, action, resource); err != nil {
-			logger.Warn(ctx, "Authorization denied",
-				adapters.Field{Key: fieldMethod, Value: info.FullMethod},
-				adapters.Field{Key: fieldError, Value: err.Error()},
-			)
-			return status.Error(codes.PermissionDenied, "authorization denied")
+		authorize := func(resource string) error {
+			if err := authorizer.Authorize(ctx, principal, action, resource); err != nil {
+				logger.Warn(ctx, "Authorization denied",
+					adapters.Field{Key: fieldMethod, Value: info.FullMethod},
+					adapters.Field{Key: fieldError, Value: err.Error()},
+				)
+				return status.Error(codes.PermissionDenied, "authorization denied")
+			}
+			return nil
 		}
 
+		// A scoped token is limited to a key prefix, and the keys of a stream
+		// are only known once its messages arrive, so authorize each one.
+		if principal.Scope != nil && resource == resourceObject {
+			return handler(srv, &authorizedServerStream{ServerStream: ss, authorize: authorize})
+		}
+
+		if err := authorize(resource); err != nil {
+			return err
+		}
 		return handler(srv, ss)
 	}
 }
 
+// authorizedServerStream authorizes every message received on a stream
+// against the object key it carries.
+type authorizedServerStream struct {
+	grpc.ServerStream
+	authorize func(resource string) error
+}
+
+// RecvMsg receives the next message and authorizes its key.
+func (s *authorizedServerStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return s.authorize(requestResource(m, resourceObject))
+}
+
 // wrappedServerStream wraps a grpc.ServerStream to override the context.
 type wrappedServerStream struct {
 	grpc.ServerStream
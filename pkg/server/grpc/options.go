@@ -49,6 +49,26 @@ type ServerOptions struct {
 	// KeepAliveTimeout is the duration the server waits for keepalive ping ack
 	KeepAliveTimeout time.Duration
 
+	// MaxConnectionIdle closes connections idle for longer than this duration
+	// (0 = never)
+	MaxConnectionIdle time.Duration
+
+	// MaxConnectionAge closes connections older than this duration so clients
+	// rebalance across servers behind a load balancer (0 = never)
+	MaxConnectionAge time.Duration
+
+	// MaxConnectionAgeGrace is the time allowed for in-flight RPCs to finish
+	// after MaxConnectionAge is reached (0 = forever)
+	MaxConnectionAgeGrace time.Duration
+
+	// KeepAliveMinTime is the minimum interval clients may send keepalive
+	// pings; clients pinging more often are disconnected
+	KeepAliveMinTime time.Duration
+
+	// KeepAlivePermitWithoutStream allows client keepalive pings when there
+	// are no active streams
+	KeepAlivePermitWithoutStream bool
+
 	// EnableReflection enables gRPC server reflection for debugging
 	EnableReflection bool
 
@@ -67,13 +87,34 @@ type ServerOptions struct {
 	// RateLimitConfig is the rate limiting configuration
 	RateLimitConfig *middleware.RateLimitConfig
 
+	// EnableIdempotency deduplicates retried Put, Delete and Archive calls
+	// that carry idempotency-key metadata
+	EnableIdempotency bool
+
+	// IdempotencyConfig is the idempotency key configuration
+	IdempotencyConfig *middleware.IdempotencyConfig
+
 	// EnableRequestID enables request ID tracking via interceptors
 	EnableRequestID bool
 
+	// PreAuthUnaryInterceptors are additional unary interceptors applied
+	// before authentication, after the request ID, rate limit and audit
+	// interceptors
+	PreAuthUnaryInterceptors []grpc.UnaryServerInterceptor
+
+	// PreAuthStreamInterceptors are additional stream interceptors applied
+	// before authentication, after the request ID, rate limit and audit
+	// interceptors
+	PreAuthStreamInterceptors []grpc.StreamServerInterceptor
+
 	// UnaryInterceptors is a list of additional unary interceptors to apply
+	// after the built-in ones, when the authenticated principal is in the
+	// context under adapters.PrincipalContextKey
 	UnaryInterceptors []grpc.UnaryServerInterceptor
 
 	// StreamInterceptors is a list of additional stream interceptors to apply
+	// after the built-in ones, when the authenticated principal is in the
+	// context under adapters.PrincipalContextKey
 	StreamInterceptors []grpc.StreamServerInterceptor
 
 	// ChunkSize is the size of data chunks for streaming operations (default: 64KB)
@@ -113,12 +154,15 @@ func DefaultServerOptions() *ServerOptions {
 		ConnectionTimeout:     30 * time.Second,
 		KeepAliveTime:         2 * time.Hour,
 		KeepAliveTimeout:      20 * time.Second,
+		KeepAliveMinTime:      5 * time.Minute,
 		EnableReflection:      false,
 		EnableHealthCheck:     true,
 		EnableMetrics:         true,
 		EnableLogging:         true,
 		EnableRateLimit:       false, // Disabled by default
 		RateLimitConfig:       middleware.DefaultRateLimitConfig(),
+		EnableIdempotency:     false, // Disabled by default
+		IdempotencyConfig:     middleware.DefaultIdempotencyConfig(),
 		EnableRequestID:       true,
 		ChunkSize:             64 * 1024, // 64KB
 		UnaryInterceptors:     []grpc.UnaryServerInterceptor{},
@@ -179,6 +223,25 @@ func WithKeepAlive(time, timeout time.Duration) ServerOption {
 	}
 }
 
+// WithConnectionAge sets how long connections may stay idle or open before
+// the server closes them, and the grace period for in-flight RPCs.
+func WithConnectionAge(idle, age, grace time.Duration) ServerOption {
+	return func(o *ServerOptions) {
+		o.MaxConnectionIdle = idle
+		o.MaxConnectionAge = age
+		o.MaxConnectionAgeGrace = grace
+	}
+}
+
+// WithKeepAliveEnforcement sets the keepalive enforcement policy applied to
+// client pings.
+func WithKeepAliveEnforcement(minTime time.Duration, permitWithoutStream bool) ServerOption {
+	return func(o *ServerOptions) {
+		o.KeepAliveMinTime = minTime
+		o.KeepAlivePermitWithoutStream = permitWithoutStream
+	}
+}
+
 // WithReflection enables or disables server reflection.
 func WithReflection(enable bool) ServerOption {
 	return func(o *ServerOptions) {
@@ -221,6 +284,22 @@ func WithStreamInterceptor(interceptor grpc.StreamServerInterceptor) ServerOptio
 	}
 }
 
+// WithPreAuthUnaryInterceptor adds a unary interceptor that runs before
+// authentication.
+func WithPreAuthUnaryInterceptor(interceptor grpc.UnaryServerInterceptor) ServerOption {
+	return func(o *ServerOptions) {
+		o.PreAuthUnaryInterceptors = append(o.PreAuthUnaryInterceptors, interceptor)
+	}
+}
+
+// WithPreAuthStreamInterceptor adds a stream interceptor that runs before
+// authentication.
+func WithPreAuthStreamInterceptor(interceptor grpc.StreamServerInterceptor) ServerOption {
+	return func(o *ServerOptions) {
+		o.PreAuthStreamInterceptors = append(o.PreAuthStreamInterceptors, interceptor)
+	}
+}
+
 // WithChunkSize sets the chunk size for streaming operations.
 func WithChunkSize(size int) ServerOption {
 	return func(o *ServerOptions) {
@@ -266,6 +345,16 @@ func WithRateLimit(enable bool, config *middleware.RateLimitConfig) ServerOption
 	}
 }
 
+// WithIdempotency enables or disables idempotency key handling.
+func WithIdempotency(enable bool, config *middleware.IdempotencyConfig) ServerOption {
+	return func(o *ServerOptions) {
+		o.EnableIdempotency = enable
+		if config != nil {
+			o.IdempotencyConfig = config
+		}
+	}
+}
+
 // WithRequestID enables or disables request ID tracking.
 func WithRequestID(enable bool) ServerOption {
 	return func(o *ServerOptions) {
@@ -53,6 +53,37 @@ func TestWithKeepAlive(t *testing.T) {
 	}
 }
 
+func TestWithConnectionAge(t *testing.T) {
+	opts := DefaultServerOptions()
+	WithConnectionAge(15*time.Minute, time.Hour, 30*time.Second)(opts)
+
+	if opts.MaxConnectionIdle != 15*time.Minute {
+		t.Errorf("Expected MaxConnectionIdle 15m, got %v", opts.MaxConnectionIdle)
+	}
+	if opts.MaxConnectionAge != time.Hour {
+		t.Errorf("Expected MaxConnectionAge 1h, got %v", opts.MaxConnectionAge)
+	}
+	if opts.MaxConnectionAgeGrace != 30*time.Second {
+		t.Errorf("Expected MaxConnectionAgeGrace 30s, got %v", opts.MaxConnectionAgeGrace)
+	}
+}
+
+func TestWithKeepAliveEnforcement(t *testing.T) {
+	opts := DefaultServerOptions()
+	if opts.KeepAliveMinTime != 5*time.Minute || opts.KeepAlivePermitWithoutStream {
+		t.Errorf("Unexpected default enforcement policy: %v, %v", opts.KeepAliveMinTime, opts.KeepAlivePermitWithoutStream)
+	}
+
+	WithKeepAliveEnforcement(10*time.Second, true)(opts)
+
+	if opts.KeepAliveMinTime != 10*time.Second {
+		t.Errorf("Expected KeepAliveMinTime 10s, got %v", opts.KeepAliveMinTime)
+	}
+	if !opts.KeepAlivePermitWithoutStream {
+		t.Error("Expected KeepAlivePermitWithoutStream to be enabled")
+	}
+}
+
 func TestWithHealthCheck(t *testing.T) {
 	opts := DefaultServerOptions()
 	WithHealthCheck(false)(opts)
@@ -92,6 +123,21 @@ func TestWithStreamInterceptor(t *testing.T) {
 	}
 }
 
+func TestWithPreAuthInterceptors(t *testing.T) {
+	opts := DefaultServerOptions()
+
+	WithPreAuthUnaryInterceptor(LoggingUnaryInterceptor(opts.Logger))(opts)
+	WithPreAuthStreamInterceptor(LoggingStreamInterceptor(opts.Logger))(opts)
+
+	if len(opts.PreAuthUnaryInterceptors) != 1 || len(opts.PreAuthStreamInterceptors) != 1 {
+		t.Errorf("Expected 1 pre-auth interceptor each, got %d unary and %d stream",
+			len(opts.PreAuthUnaryInterceptors), len(opts.PreAuthStreamInterceptors))
+	}
+	if len(opts.UnaryInterceptors) != 0 || len(opts.StreamInterceptors) != 0 {
+		t.Error("Pre-auth interceptors must not be added to the post-auth chain")
+	}
+}
+
 func TestMultipleOptions(t *testing.T) {
 	opts := DefaultServerOptions()
 
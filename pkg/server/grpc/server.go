@@ -17,6 +17,7 @@ import (
 	"context"
 	"crypto/tls"
 	"net"
+	"strings"
 	"sync"
 
 	objstorepb "github.com/jeremyhahn/go-objstore/api/proto"
@@ -46,10 +47,17 @@ type Server struct {
 	backend     string // Backend name (empty = default)
 	opts        *ServerOptions
 	grpcServer  *grpc.Server
+	health      *health.Server
 	listener    net.Listener
 	metrics     *MetricsCollector
 	rateLimiter *middleware.RateLimiter
 	mu          sync.RWMutex
+
+	// The interceptor chains are built once so the transports they are
+	// installed on share one rate limiter.
+	chainOnce          sync.Once
+	unaryInterceptors  []grpc.UnaryServerInterceptor
+	streamInterceptors []grpc.StreamServerInterceptor
 }
 
 // NewServer creates a new gRPC server instance using the ObjstoreFacade.
@@ -82,28 +90,38 @@ func (s *Server) Start() error {
 	if err != nil {
 		return err
 	}
+	return s.Serve(listener)
+}
 
+// Serve serves the gRPC server on a listener created by the caller, such as
+// a Unix socket or a listener shared through cmux. It blocks until the
+// server stops.
+func (s *Server) Serve(listener net.Listener) error {
 	// Build server options
 	serverOpts := s.buildServerOptions()
 
 	// Create gRPC server
 	grpcServer := grpc.NewServer(serverOpts...)
 
+	// Register the ObjectStore service
+	objstorepb.RegisterObjectStoreServer(grpcServer, s)
+
+	// Register health check service
+	var healthServer *health.Server
+	if s.opts.EnableHealthCheck {
+		healthServer = health.NewServer()
+		grpc_health_v1.RegisterHealthServer(grpcServer, healthServer)
+		healthServer.SetServingStatus("", grpc_health_v1.HealthCheckResponse_SERVING)
+		healthServer.SetServingStatus(objstorepb.ObjectStore_ServiceDesc.ServiceName, grpc_health_v1.HealthCheckResponse_SERVING)
+	}
+
 	// Store references with mutex protection
 	s.mu.Lock()
 	s.listener = listener
 	s.grpcServer = grpcServer
+	s.health = healthServer
 	s.mu.Unlock()
 
-	// Register the ObjectStore service
-	objstorepb.RegisterObjectStoreServer(grpcServer, s)
-
-	// Register health check service
-	healthServer := health.NewServer()
-	grpc_health_v1.RegisterHealthServer(grpcServer, healthServer)
-	healthServer.SetServingStatus("", grpc_health_v1.HealthCheckResponse_SERVING)
-	healthServer.SetServingStatus("objstore.ObjectStore", grpc_health_v1.HealthCheckResponse_SERVING)
-
 	// Enable reflection if configured
 	if s.opts.EnableReflection {
 		reflection.Register(grpcServer)
@@ -111,7 +129,7 @@ func (s *Server) Start() error {
 	}
 
 	s.opts.Logger.Info(context.Background(), "Starting gRPC server",
-		adapters.Field{Key: "address", Value: s.opts.Address},
+		adapters.Field{Key: "address", Value: listener.Addr().String()},
 	)
 
 	// Start serving (this blocks)
@@ -126,9 +144,15 @@ func (s *Server) Start() error {
 func (s *Server) Stop() {
 	s.mu.RLock()
 	grpcServer := s.grpcServer
+	healthServer := s.health
 	limiter := s.rateLimiter
 	s.mu.RUnlock()
 
+	// Report NOT_SERVING first so health-checking load balancers drain
+	// traffic while in-flight RPCs complete.
+	if healthServer != nil {
+		healthServer.Shutdown()
+	}
 	if limiter != nil {
 		limiter.Stop()
 	}
@@ -143,9 +167,15 @@ func (s *Server) Stop() {
 func (s *Server) ForceStop() {
 	s.mu.RLock()
 	grpcServer := s.grpcServer
+	healthServer := s.health
 	limiter := s.rateLimiter
 	s.mu.RUnlock()
 
+	// Report NOT_SERVING first so health-checking load balancers drain
+	// traffic while in-flight RPCs complete.
+	if healthServer != nil {
+		healthServer.Shutdown()
+	}
 	if limiter != nil {
 		limiter.Stop()
 	}
@@ -156,6 +186,60 @@ func (s *Server) ForceStop() {
 	}
 }
 
+// Register registers the ObjectStore service on a gRPC server owned by the
+// application, alongside its own services. The application then serves and
+// stops that server itself; Stop only releases this server's resources.
+//
+// Interceptors belong to the grpc.Server, so the authentication,
+// authorization, rate limiting and other interceptors configured through
+// ServerOptions only run when UnaryInterceptor and StreamInterceptor are
+// installed on it:
+//
+//	srv, _ := grpcserver.NewServer(grpcserver.WithAuthenticator(auth))
+//	gs := grpc.NewServer(
+//	    grpc.ChainUnaryInterceptor(srv.UnaryInterceptor(), appUnary),
+//	    grpc.ChainStreamInterceptor(srv.StreamInterceptor(), appStream),
+//	)
+//	srv.Register(gs)
+//	apppb.RegisterAppServer(gs, app)
+func (s *Server) Register(registrar grpc.ServiceRegistrar) {
+	objstorepb.RegisterObjectStoreServer(registrar, s)
+}
+
+// UnaryInterceptor returns the server's unary interceptor chain for
+// installation on a gRPC server passed to Register. Calls to other services
+// pass through it untouched.
+func (s *Server) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	unary, _ := s.interceptors()
+	chain := ChainUnaryInterceptors(unary...)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !isObjectStoreMethod(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		return chain(ctx, req, info, handler)
+	}
+}
+
+// StreamInterceptor returns the server's stream interceptor chain for
+// installation on a gRPC server passed to Register. Calls to other services
+// pass through it untouched.
+func (s *Server) StreamInterceptor() grpc.StreamServerInterceptor {
+	_, stream := s.interceptors()
+	chain := ChainStreamInterceptors(stream...)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !isObjectStoreMethod(info.FullMethod) {
+			return handler(srv, ss)
+		}
+		return chain(srv, ss, info, handler)
+	}
+}
+
+// isObjectStoreMethod reports whether a full method name such as
+// "/objstore.v1.ObjectStore/Put" belongs to the ObjectStore service.
+func isObjectStoreMethod(fullMethod string) bool {
+	return strings.HasPrefix(fullMethod, "/"+objstorepb.ObjectStore_ServiceDesc.ServiceName+"/")
+}
+
 // GetMetrics returns the current server metrics.
 func (s *Server) GetMetrics() map[string]any {
 	return s.metrics.GetMetrics()
@@ -206,14 +290,48 @@ func (s *Server) buildServerOptions() []grpc.ServerOption {
 		grpc.MaxSendMsgSize(s.opts.MaxSendMessageSize),
 	)
 
-	// Set keepalive parameters
-	opts = append(opts, grpc.KeepaliveParams(keepalive.ServerParameters{
-		Time:    s.opts.KeepAliveTime,
-		Timeout: s.opts.KeepAliveTimeout,
-	}))
+	// Set keepalive parameters and the policy enforced on client pings
+	opts = append(opts,
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			MaxConnectionIdle:     s.opts.MaxConnectionIdle,
+			MaxConnectionAge:      s.opts.MaxConnectionAge,
+			MaxConnectionAgeGrace: s.opts.MaxConnectionAgeGrace,
+			Time:                  s.opts.KeepAliveTime,
+			Timeout:               s.opts.KeepAliveTimeout,
+		}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             s.opts.KeepAliveMinTime,
+			PermitWithoutStream: s.opts.KeepAlivePermitWithoutStream,
+		}),
+	)
+
+	unaryInterceptors, streamInterceptors := s.interceptors()
+
+	// Chain all interceptors
+	if len(unaryInterceptors) > 0 {
+		opts = append(opts, grpc.ChainUnaryInterceptor(unaryInterceptors...))
+	}
 
-	// Build interceptor chains
-	// Order: recovery → request ID → rate limit → auth → logging → metrics → custom
+	if len(streamInterceptors) > 0 {
+		opts = append(opts, grpc.ChainStreamInterceptor(streamInterceptors...))
+	}
+
+	return opts
+}
+
+// interceptors returns the server's interceptor chains, building them on
+// first use.
+func (s *Server) interceptors() ([]grpc.UnaryServerInterceptor, []grpc.StreamServerInterceptor) {
+	s.chainOnce.Do(func() {
+		s.unaryInterceptors, s.streamInterceptors = s.buildInterceptors()
+	})
+	return s.unaryInterceptors, s.streamInterceptors
+}
+
+// buildInterceptors constructs the interceptor chains based on configuration.
+func (s *Server) buildInterceptors() ([]grpc.UnaryServerInterceptor, []grpc.StreamServerInterceptor) {
+	// Order: recovery → request ID → rate limit → audit → pre-auth custom → auth → idempotency →
+	// logging → metrics → custom
 	unaryInterceptors := []grpc.UnaryServerInterceptor{
 		RecoveryUnaryInterceptor(), // Always add recovery first
 	}
@@ -246,6 +364,10 @@ func (s *Server) buildServerOptions() []grpc.ServerOption {
 		streamInterceptors = append(streamInterceptors, audit.AuditStreamInterceptor(s.opts.AuditLogger))
 	}
 
+	// Add custom interceptors that run before authentication
+	unaryInterceptors = append(unaryInterceptors, s.opts.PreAuthUnaryInterceptors...)
+	streamInterceptors = append(streamInterceptors, s.opts.PreAuthStreamInterceptors...)
+
 	// Add authentication interceptors (always enabled, uses NoOpAuthenticator by default)
 	unaryInterceptors = append(unaryInterceptors, AuthenticationUnaryInterceptor(s.opts.Authenticator, s.opts.Logger))
 	streamInterceptors = append(streamInterceptors, AuthenticationStreamInterceptor(s.opts.Authenticator, s.opts.Logger))
@@ -256,9 +378,16 @@ func (s *Server) buildServerOptions() []grpc.ServerOption {
 	if authorizer == nil {
 		authorizer = adapters.NewNoOpAuthorizer()
 	}
+	authorizer = adapters.NewScopedAuthorizer(authorizer)
 	unaryInterceptors = append(unaryInterceptors, AuthorizationUnaryInterceptor(authorizer, s.opts.Logger))
 	streamInterceptors = append(streamInterceptors, AuthorizationStreamInterceptor(authorizer, s.opts.Logger))
 
+	// Add idempotency interceptor if enabled. Runs after authentication so
+	// keys are scoped to the principal. Put, Delete and Archive are unary.
+	if s.opts.EnableIdempotency {
+		unaryInterceptors = append(unaryInterceptors, middleware.NewIdempotency(s.opts.IdempotencyConfig, s.opts.Logger).UnaryInterceptor())
+	}
+
 	// Add logging interceptors
 	if s.opts.EnableLogging {
 		unaryInterceptors = append(unaryInterceptors, LoggingUnaryInterceptor(s.opts.Logger))
@@ -275,16 +404,7 @@ func (s *Server) buildServerOptions() []grpc.ServerOption {
 	unaryInterceptors = append(unaryInterceptors, s.opts.UnaryInterceptors...)
 	streamInterceptors = append(streamInterceptors, s.opts.StreamInterceptors...)
 
-	// Chain all interceptors
-	if len(unaryInterceptors) > 0 {
-		opts = append(opts, grpc.ChainUnaryInterceptor(unaryInterceptors...))
-	}
-
-	if len(streamInterceptors) > 0 {
-		opts = append(opts, grpc.ChainStreamInterceptor(streamInterceptors...))
-	}
-
-	return opts
+	return unaryInterceptors, streamInterceptors
 }
 
 // WithTLSFromFiles is a helper to create a TLS config from certificate files.
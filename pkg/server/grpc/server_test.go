@@ -19,6 +19,7 @@ import (
 	"crypto/tls"
 	"errors"
 	"io"
+	"net"
 	"strings"
 	"testing"
 	"time"
@@ -28,7 +29,11 @@ import (
 	"github.com/jeremyhahn/go-objstore/pkg/objstore"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
 )
 
 // mockStorage implements the common.Storage interface for testing.
@@ -191,6 +196,47 @@ func TestNewServer(t *testing.T) {
 	// Server now uses facade, no direct storage reference to check
 }
 
+func TestServer_RegisterOnExistingServer(t *testing.T) {
+	initTestFacade(t, newMockStorage())
+	server, err := NewServer(WithAuthenticator(&mockAuthenticator{shouldFail: true}))
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	defer server.Stop()
+
+	// The application owns the gRPC server and serves its own services on it.
+	gs := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(server.UnaryInterceptor()),
+		grpc.ChainStreamInterceptor(server.StreamInterceptor()),
+	)
+	server.Register(gs)
+	appHealth := health.NewServer()
+	grpc_health_v1.RegisterHealthServer(gs, appHealth)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go gs.Serve(listener)
+	defer gs.Stop()
+
+	conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// The objstore interceptors guard the ObjectStore service...
+	_, err = objstorepb.NewObjectStoreClient(conn).Health(context.Background(), &objstorepb.HealthRequest{})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Health() error = %v, want Unauthenticated", err)
+	}
+	// ...and leave the application's services alone.
+	if _, err := grpc_health_v1.NewHealthClient(conn).Check(context.Background(), &grpc_health_v1.HealthCheckRequest{}); err != nil {
+		t.Errorf("application health check error = %v", err)
+	}
+}
+
 func TestNewServer_FacadeNotInitialized(t *testing.T) {
 	// Reset facade to ensure it's not initialized
 	objstore.Reset()
@@ -367,6 +413,9 @@ func TestPutAndGet(t *testing.T) {
 	if !putResp.Success {
 		t.Errorf("Put not successful: %s", putResp.Message)
 	}
+	if putResp.Etag != "mock-etag" || putResp.Size != 9 || putResp.LastModified == nil {
+		t.Errorf("Put did not report the object written: %+v", putResp)
+	}
 
 	// Test Get
 	getReq := &objstorepb.GetRequest{
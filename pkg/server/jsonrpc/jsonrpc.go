@@ -65,6 +65,8 @@ const (
 	CodeNotFound = -32004
 	// CodeAlreadyExists reports a conflict with an existing resource.
 	CodeAlreadyExists = -32005
+	// CodePreconditionFailed reports a failed conditional request.
+	CodePreconditionFailed = -32006
 	// CodeRateLimited reports a rate-limit rejection.
 	CodeRateLimited = -32029
 )
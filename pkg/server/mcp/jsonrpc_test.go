@@ -101,8 +101,8 @@ func TestRPCHandler_HandleToolsList(t *testing.T) {
 		t.Fatal("expected tools to be a slice")
 	}
 
-	if len(tools) != 19 {
-		t.Errorf("expected 19 tools, got %d", len(tools))
+	if len(tools) != 20 {
+		t.Errorf("expected 20 tools, got %d", len(tools))
 	}
 }
 
@@ -333,19 +333,26 @@ func (s *Server) authenticationMiddleware(next http.Handler) http.Handler {
 		// Do NOT assign back to s.config.Logger — that would mutate shared
 		// server state and cause a data race under concurrent requests.
 		ctx := context.WithValue(r.Context(), principalContextKey, principal)
+		ctx = context.WithValue(ctx, adapters.PrincipalContextKey{}, principal)
 		r = r.WithContext(ctx)
 
 		reqLogger := s.config.Logger.WithFields(
 			adapters.Field{Key: "principal_id", Value: principal.ID},
 			adapters.Field{Key: "principal_name", Value: principal.Name},
 		)
+		if principal.OnBehalfOf != "" {
+			reqLogger = reqLogger.WithFields(adapters.Field{Key: "on_behalf_of", Value: principal.OnBehalfOf})
+		}
 
 		// Authorize the request. Peek at the JSON-RPC body to derive the
 		// MCP method (and tool name for tools/call), then restore the body
 		// for the downstream handler.
 		action, resource, body := s.deriveMCPActionResource(r)
 		r.Body = io.NopCloser(bytes.NewReader(body))
-		if err := s.config.Authorizer.Authorize(ctx, principal, action, resource); err != nil {
+		// Tool calls carry no object key here, so scoped tokens are held to
+		// their actions and, when prefixed, denied.
+		authorizer := adapters.NewScopedAuthorizer(s.config.Authorizer)
+		if err := authorizer.Authorize(ctx, principal, action, resource); err != nil {
 			reqLogger.Warn(ctx, "MCP HTTP authorization denied",
 				adapters.Field{Key: "error", Value: err.Error()},
 				adapters.Field{Key: "path", Value: r.URL.Path},
@@ -366,6 +373,7 @@ var mcpToolActions = map[string]string{
 	"objstore_exists":          adapters.ActionRead,
 	"objstore_get_metadata":    adapters.ActionRead,
 	"objstore_list":            adapters.ActionList,
+	"objstore_browse":          adapters.ActionList,
 	"objstore_put":             adapters.ActionWrite,
 	"objstore_update_metadata": adapters.ActionWrite,
 	"objstore_delete":          adapters.ActionDelete,
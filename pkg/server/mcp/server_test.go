@@ -113,8 +113,8 @@ func TestServer_ListTools(t *testing.T) {
 	server := createTestServer(t, storage, ModeStdio)
 
 	tools := server.ListTools()
-	if len(tools) != 19 {
-		t.Errorf("expected 19 tools, got %d", len(tools))
+	if len(tools) != 20 {
+		t.Errorf("expected 20 tools, got %d", len(tools))
 	}
 
 	toolNames := make(map[string]bool)
@@ -25,6 +25,7 @@ import (
 	"github.com/jeremyhahn/go-objstore/pkg/common"
 	"github.com/jeremyhahn/go-objstore/pkg/factory"
 	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+	"github.com/jeremyhahn/go-objstore/pkg/secrets"
 	"github.com/jeremyhahn/go-objstore/pkg/version"
 )
 
@@ -144,6 +145,24 @@ func (r *ToolRegistry) RegisterDefaultTools() {
 		},
 	}
 
+	r.tools["objstore_browse"] = Tool{
+		Name:        "objstore_browse",
+		Description: "Browse the object store like a directory tree. Returns the folders and objects directly under a prefix, optionally with the number and total size of the objects in each folder.",
+		InputSchema: map[string]any{
+			schemaType: schemaObject,
+			schemaProperties: map[string]any{
+				fieldPrefix: map[string]any{
+					schemaType:        schemaString,
+					schemaDescription: "Folder to browse, ending in \"/\" (empty string browses the root)",
+				},
+				"totals": map[string]any{
+					schemaType:        "boolean",
+					schemaDescription: "Count the objects and bytes in each folder (lists every object under the prefix)",
+				},
+			},
+		},
+	}
+
 	r.tools["objstore_exists"] = Tool{
 		Name:        "objstore_exists",
 		Description: "Check if an object exists in the object store. Returns true if the object exists.",
@@ -363,6 +382,8 @@ func (e *ToolExecutor) Execute(ctx context.Context, toolName string, args map[st
 		return e.executeDelete(ctx, args)
 	case "objstore_list":
 		return e.executeList(ctx, args)
+	case "objstore_browse":
+		return e.executeBrowse(ctx, args)
 	case "objstore_exists":
 		return e.executeExists(ctx, args)
 	case "objstore_get_metadata":
@@ -443,12 +464,7 @@ func (e *ToolExecutor) executePut(ctx context.Context, args map[string]any) (str
 	}
 
 	// Store the object using facade
-	if metadata != nil {
-		err = objstore.PutWithMetadata(ctx, e.keyRef(key), reader, metadata)
-	} else {
-		err = objstore.PutWithContext(ctx, e.keyRef(key), reader)
-	}
-
+	info, err := objstore.PutWithInfo(ctx, e.keyRef(key), reader, metadata)
 	if err != nil {
 		return "", err
 	}
@@ -457,6 +473,10 @@ func (e *ToolExecutor) executePut(ctx context.Context, args map[string]any) (str
 		fieldSuccess: true,
 		fieldKey:     key,
 		"size":       len(decoded),
+		"etag":       info.Metadata.ETag,
+	}
+	if info.Metadata.Version != "" {
+		result["version"] = info.Metadata.Version
 	}
 
 	jsonResult, _ := json.MarshalIndent(result, "", "  ")
@@ -557,6 +577,38 @@ func (e *ToolExecutor) executeList(ctx context.Context, args map[string]any) (st
 	return string(jsonResult), nil
 }
 
+// executeBrowse executes the objstore_browse tool
+func (e *ToolExecutor) executeBrowse(ctx context.Context, args map[string]any) (string, error) {
+	prefix, _ := args[fieldPrefix].(string)
+	totals, _ := args["totals"].(bool)
+
+	browseResult, err := objstore.Browse(ctx, e.backend, prefix, &common.BrowseOptions{Totals: totals})
+	if err != nil {
+		return "", err
+	}
+
+	objects := make([]map[string]any, len(browseResult.Objects))
+	for i, obj := range browseResult.Objects {
+		objects[i] = map[string]any{fieldKey: obj.Key}
+		if obj.Metadata != nil {
+			objects[i]["size"] = obj.Metadata.Size
+			objects[i]["last_modified"] = obj.Metadata.LastModified
+		}
+	}
+
+	result := map[string]any{
+		fieldSuccess:    true,
+		fieldPrefix:     prefix,
+		"folders":       browseResult.Folders,
+		"objects":       objects,
+		"total_objects": browseResult.TotalObjects,
+		"total_bytes":   browseResult.TotalBytes,
+	}
+
+	jsonResult, _ := json.MarshalIndent(result, "", "  ")
+	return string(jsonResult), nil
+}
+
 // executeExists executes the objstore_exists tool
 func (e *ToolExecutor) executeExists(ctx context.Context, args map[string]any) (string, error) {
 	key, ok := args[fieldKey].(string)
@@ -876,19 +928,24 @@ func (e *ToolExecutor) executeApplyPolicies(ctx context.Context, args map[string
 		return "", err
 	}
 
+	expired, err := objstore.DeleteExpired(ctx, e.backend)
+	if err != nil {
+		return "", err
+	}
+
 	if len(policies) == 0 {
 		result := map[string]any{
 			fieldSuccess:        true,
 			fieldMessage:        "no lifecycle policies to apply",
 			"policies_count":    0,
-			"objects_processed": 0,
+			"objects_processed": expired,
 		}
 		jsonResult, _ := json.MarshalIndent(result, "", "  ")
 		return string(jsonResult), nil
 	}
 
 	// Apply policies by listing objects and checking retention
-	objectsProcessed := 0
+	objectsProcessed := expired
 	opts := &common.ListOptions{
 		Prefix: "",
 	}
@@ -949,5 +1006,8 @@ func (e *ToolExecutor) executeApplyPolicies(ctx context.Context, args map[string
 
 // createArchiver creates an archiver from factory based on destination type
 func createArchiver(destinationType string, settings map[string]string) (common.Archiver, error) {
+	if err := secrets.RejectReferences(settings); err != nil {
+		return nil, err
+	}
 	return factory.NewArchiver(destinationType, settings)
 }
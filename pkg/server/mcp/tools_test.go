@@ -330,8 +330,8 @@ func TestToolRegistry(t *testing.T) {
 	registry.RegisterDefaultTools()
 
 	tools := registry.ListTools()
-	if len(tools) != 19 {
-		t.Errorf("expected 19 tools, got %d", len(tools))
+	if len(tools) != 20 {
+		t.Errorf("expected 20 tools, got %d", len(tools))
 	}
 
 	expectedTools := []string{
@@ -339,6 +339,7 @@ func TestToolRegistry(t *testing.T) {
 		"objstore_get",
 		"objstore_delete",
 		"objstore_list",
+		"objstore_browse",
 		"objstore_exists",
 		"objstore_get_metadata",
 		"objstore_update_metadata",
@@ -707,6 +708,40 @@ func TestToolExecutor_ExecuteList(t *testing.T) {
 	}
 }
 
+func TestToolExecutor_ExecuteBrowse(t *testing.T) {
+	storage := NewMockStorage()
+	executor := createTestToolExecutor(t, storage)
+
+	for key, data := range map[string]string{
+		"logs/a.log":         "aaaa",
+		"logs/2025/b.log":    "bb",
+		"logs/2025/01/c.log": "c",
+		"readme.txt":         "readme",
+	} {
+		storage.PutWithContext(context.Background(), key, strings.NewReader(data))
+	}
+
+	result, err := executor.Execute(context.Background(), "objstore_browse", map[string]any{"prefix": "logs/", "totals": true})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	var parsed struct {
+		Folders []common.Folder  `json:"folders"`
+		Objects []map[string]any `json:"objects"`
+		Total   int64            `json:"total_objects"`
+	}
+	if err := json.Unmarshal([]byte(result), &parsed); err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed.Folders) != 1 || parsed.Folders[0] != (common.Folder{Prefix: "logs/2025/", Objects: 2, Bytes: 3}) {
+		t.Errorf("folders = %+v", parsed.Folders)
+	}
+	if len(parsed.Objects) != 1 || parsed.Objects[0]["key"] != "logs/a.log" || parsed.Total != 3 {
+		t.Errorf("objects = %+v, total %d", parsed.Objects, parsed.Total)
+	}
+}
+
 func TestToolExecutor_ExecuteExists(t *testing.T) {
 	storage := NewMockStorage()
 	executor := createTestToolExecutor(t, storage)
@@ -27,6 +27,11 @@ import (
 	"sync"
 	"time"
 
+	"github.com/jeremyhahn/go-objstore/pkg/concurrency"
+	"github.com/jeremyhahn/go-objstore/pkg/health"
+	"github.com/jeremyhahn/go-objstore/pkg/reaper"
+	"github.com/jeremyhahn/go-objstore/pkg/scrub"
+	"github.com/jeremyhahn/go-objstore/pkg/transport"
 	"github.com/jeremyhahn/go-objstore/pkg/version"
 )
 
@@ -162,6 +167,114 @@ func (r *Registry) WritePrometheus(w io.Writer) {
 		fmt.Fprintf(w, "objstore_request_duration_seconds_sum{transport=%q,code=%q} %g\n",
 			s.key.transport, s.key.code, float64(s.stat.latencyNanos)/1e9)
 	}
+
+	writeTransportStats(w, transport.Default.Stats())
+	writeHealthStats(w, health.Default.Stats())
+	writeScrubStats(w, scrub.Default.Stats())
+	writeConcurrencyStats(w, concurrency.Default.Stats())
+	writeReaperStats(w, reaper.Default.Stats())
+}
+
+// writeTransportStats renders the outbound backend connection pool statistics
+// so operators can verify that connections are being reused under load.
+func writeTransportStats(w io.Writer, stats []transport.Stats) {
+	fmt.Fprintf(w, "# HELP objstore_backend_http_clients Pooled HTTP clients by backend type.\n")
+	fmt.Fprintf(w, "# TYPE objstore_backend_http_clients gauge\n")
+	for _, s := range stats {
+		fmt.Fprintf(w, "objstore_backend_http_clients{backend=%q} %d\n", s.Backend, s.Clients)
+	}
+
+	fmt.Fprintf(w, "# HELP objstore_backend_http_connections_total Outbound backend requests by connection reuse.\n")
+	fmt.Fprintf(w, "# TYPE objstore_backend_http_connections_total counter\n")
+	for _, s := range stats {
+		fmt.Fprintf(w, "objstore_backend_http_connections_total{backend=%q,reused=\"false\"} %d\n", s.Backend, s.NewConns)
+		fmt.Fprintf(w, "objstore_backend_http_connections_total{backend=%q,reused=\"true\"} %d\n", s.Backend, s.ReusedConns)
+	}
+}
+
+// writeHealthStats renders the backend health checks and failovers so
+// operators can alert on an unhealthy backend or a failover.
+func writeHealthStats(w io.Writer, stats []health.Stats) {
+	fmt.Fprintf(w, "# HELP objstore_backend_healthy Whether a monitored backend is healthy (1) or not (0).\n")
+	fmt.Fprintf(w, "# TYPE objstore_backend_healthy gauge\n")
+	for _, s := range stats {
+		healthy := 0
+		if s.Healthy {
+			healthy = 1
+		}
+		fmt.Fprintf(w, "objstore_backend_healthy{backend=%q} %d\n", s.Backend, healthy)
+	}
+
+	fmt.Fprintf(w, "# HELP objstore_backend_health_checks_total Backend health probes by result.\n")
+	fmt.Fprintf(w, "# TYPE objstore_backend_health_checks_total counter\n")
+	for _, s := range stats {
+		fmt.Fprintf(w, "objstore_backend_health_checks_total{backend=%q,result=\"success\"} %d\n", s.Backend, s.ChecksSucceeded)
+		fmt.Fprintf(w, "objstore_backend_health_checks_total{backend=%q,result=\"failure\"} %d\n", s.Backend, s.ChecksFailed)
+	}
+
+	fmt.Fprintf(w, "# HELP objstore_backend_failovers_total Times traffic moved from a backend to its secondary.\n")
+	fmt.Fprintf(w, "# TYPE objstore_backend_failovers_total counter\n")
+	for _, s := range stats {
+		fmt.Fprintf(w, "objstore_backend_failovers_total{backend=%q} %d\n", s.Backend, s.Failovers)
+	}
+
+	fmt.Fprintf(w, "# HELP objstore_backend_failbacks_total Times traffic returned to a backend from its secondary.\n")
+	fmt.Fprintf(w, "# TYPE objstore_backend_failbacks_total counter\n")
+	for _, s := range stats {
+		fmt.Fprintf(w, "objstore_backend_failbacks_total{backend=%q} %d\n", s.Backend, s.Failbacks)
+	}
+}
+
+// writeScrubStats renders the scrubbing of backends so operators can alert
+// on corrupted objects, especially those that could not be repaired.
+func writeScrubStats(w io.Writer, stats []scrub.Stats) {
+	fmt.Fprintf(w, "# HELP objstore_scrub_objects_total Objects checked against their checksums by scrubbing.\n")
+	fmt.Fprintf(w, "# TYPE objstore_scrub_objects_total counter\n")
+	for _, s := range stats {
+		fmt.Fprintf(w, "objstore_scrub_objects_total{backend=%q} %d\n", s.Backend, s.Objects)
+	}
+
+	fmt.Fprintf(w, "# HELP objstore_scrub_bytes_total Bytes read by scrubbing.\n")
+	fmt.Fprintf(w, "# TYPE objstore_scrub_bytes_total counter\n")
+	for _, s := range stats {
+		fmt.Fprintf(w, "objstore_scrub_bytes_total{backend=%q} %d\n", s.Backend, s.Bytes)
+	}
+
+	fmt.Fprintf(w, "# HELP objstore_scrub_corruptions_total Corrupted objects found by scrubbing, by whether they were repaired.\n")
+	fmt.Fprintf(w, "# TYPE objstore_scrub_corruptions_total counter\n")
+	for _, s := range stats {
+		fmt.Fprintf(w, "objstore_scrub_corruptions_total{backend=%q,repaired=\"true\"} %d\n", s.Backend, s.Repairs)
+		fmt.Fprintf(w, "objstore_scrub_corruptions_total{backend=%q,repaired=\"false\"} %d\n", s.Backend, s.Corruptions-s.Repairs)
+	}
+}
+
+// writeConcurrencyStats renders the adaptive concurrency limits of backends
+// so operators can see a provider throttling and operations queueing.
+func writeConcurrencyStats(w io.Writer, stats []concurrency.Stats) {
+	fmt.Fprintf(w, "# HELP objstore_backend_concurrency_limit Operations currently allowed in flight against a backend.\n")
+	fmt.Fprintf(w, "# TYPE objstore_backend_concurrency_limit gauge\n")
+	for _, s := range stats {
+		fmt.Fprintf(w, "objstore_backend_concurrency_limit{backend=%q} %d\n", s.Backend, s.Limit)
+	}
+
+	fmt.Fprintf(w, "# HELP objstore_backend_operations_in_flight Backend operations running or waiting for a slot.\n")
+	fmt.Fprintf(w, "# TYPE objstore_backend_operations_in_flight gauge\n")
+	for _, s := range stats {
+		fmt.Fprintf(w, "objstore_backend_operations_in_flight{backend=%q,state=\"running\"} %d\n", s.Backend, s.InFlight)
+		fmt.Fprintf(w, "objstore_backend_operations_in_flight{backend=%q,state=\"waiting\"} %d\n", s.Backend, s.Waiting)
+	}
+
+	fmt.Fprintf(w, "# HELP objstore_backend_concurrency_decreases_total Times a backend's concurrency limit was cut on overload.\n")
+	fmt.Fprintf(w, "# TYPE objstore_backend_concurrency_decreases_total counter\n")
+	for _, s := range stats {
+		fmt.Fprintf(w, "objstore_backend_concurrency_decreases_total{backend=%q} %d\n", s.Backend, s.Decreases)
+	}
+
+	fmt.Fprintf(w, "# HELP objstore_backend_concurrency_rejections_total Operations that gave up waiting for a slot.\n")
+	fmt.Fprintf(w, "# TYPE objstore_backend_concurrency_rejections_total counter\n")
+	for _, s := range stats {
+		fmt.Fprintf(w, "objstore_backend_concurrency_rejections_total{backend=%q} %d\n", s.Backend, s.Rejections)
+	}
 }
 
 // Handler returns an http.Handler that renders the Default registry in
@@ -172,3 +285,32 @@ func Handler() http.Handler {
 		Default.WritePrometheus(w)
 	})
 }
+
+// writeReaperStats renders the unfinished uploads of backends and the
+// storage reclaimed by aborting stale ones, so operators can see abandoned
+// transfers piling up.
+func writeReaperStats(w io.Writer, stats []reaper.Stats) {
+	fmt.Fprintf(w, "# HELP objstore_uploads_in_progress Unfinished uploads found by the last reaper run.\n")
+	fmt.Fprintf(w, "# TYPE objstore_uploads_in_progress gauge\n")
+	for _, s := range stats {
+		fmt.Fprintf(w, "objstore_uploads_in_progress{backend=%q,kind=%q} %d\n", s.Backend, s.Kind, s.InProgress)
+	}
+
+	fmt.Fprintf(w, "# HELP objstore_uploads_in_progress_bytes Bytes stored by unfinished uploads.\n")
+	fmt.Fprintf(w, "# TYPE objstore_uploads_in_progress_bytes gauge\n")
+	for _, s := range stats {
+		fmt.Fprintf(w, "objstore_uploads_in_progress_bytes{backend=%q,kind=%q} %d\n", s.Backend, s.Kind, s.InProgressBytes)
+	}
+
+	fmt.Fprintf(w, "# HELP objstore_stale_uploads_aborted_total Unfinished uploads aborted for being too old.\n")
+	fmt.Fprintf(w, "# TYPE objstore_stale_uploads_aborted_total counter\n")
+	for _, s := range stats {
+		fmt.Fprintf(w, "objstore_stale_uploads_aborted_total{backend=%q,kind=%q} %d\n", s.Backend, s.Kind, s.Aborted)
+	}
+
+	fmt.Fprintf(w, "# HELP objstore_stale_upload_bytes_reclaimed_total Bytes reclaimed by aborting stale uploads.\n")
+	fmt.Fprintf(w, "# TYPE objstore_stale_upload_bytes_reclaimed_total counter\n")
+	for _, s := range stats {
+		fmt.Fprintf(w, "objstore_stale_upload_bytes_reclaimed_total{backend=%q,kind=%q} %d\n", s.Backend, s.Kind, s.ReclaimedBytes)
+	}
+}
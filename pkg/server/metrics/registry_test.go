@@ -20,6 +20,11 @@ import (
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/concurrency"
+	"github.com/jeremyhahn/go-objstore/pkg/health"
+	"github.com/jeremyhahn/go-objstore/pkg/reaper"
+	"github.com/jeremyhahn/go-objstore/pkg/scrub"
 )
 
 func TestRecordAndRender(t *testing.T) {
@@ -87,3 +92,79 @@ func TestRecordRequestConcurrent(t *testing.T) {
 		t.Errorf("expected 5000 quic requests, got:\n%s", sb.String())
 	}
 }
+
+func TestWriteHealthStats(t *testing.T) {
+	var sb strings.Builder
+	writeHealthStats(&sb, []health.Stats{
+		{Backend: "primary", Healthy: false, ChecksSucceeded: 4, ChecksFailed: 3, Failovers: 1},
+		{Backend: "secondary", Healthy: true, ChecksSucceeded: 7, Failbacks: 2},
+	})
+	out := sb.String()
+	for _, want := range []string{
+		`objstore_backend_healthy{backend="primary"} 0`,
+		`objstore_backend_healthy{backend="secondary"} 1`,
+		`objstore_backend_health_checks_total{backend="primary",result="success"} 4`,
+		`objstore_backend_health_checks_total{backend="primary",result="failure"} 3`,
+		`objstore_backend_failovers_total{backend="primary"} 1`,
+		`objstore_backend_failbacks_total{backend="secondary"} 2`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteScrubStats(t *testing.T) {
+	var sb strings.Builder
+	writeScrubStats(&sb, []scrub.Stats{
+		{Backend: "primary", Objects: 10, Bytes: 4096, Corruptions: 3, Repairs: 2},
+	})
+	out := sb.String()
+	for _, want := range []string{
+		`objstore_scrub_objects_total{backend="primary"} 10`,
+		`objstore_scrub_bytes_total{backend="primary"} 4096`,
+		`objstore_scrub_corruptions_total{backend="primary",repaired="true"} 2`,
+		`objstore_scrub_corruptions_total{backend="primary",repaired="false"} 1`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteConcurrencyStats(t *testing.T) {
+	var sb strings.Builder
+	writeConcurrencyStats(&sb, []concurrency.Stats{
+		{Backend: "s3", Limit: 12, InFlight: 12, Waiting: 3, Decreases: 4, Rejections: 1},
+	})
+	out := sb.String()
+	for _, want := range []string{
+		`objstore_backend_concurrency_limit{backend="s3"} 12`,
+		`objstore_backend_operations_in_flight{backend="s3",state="running"} 12`,
+		`objstore_backend_operations_in_flight{backend="s3",state="waiting"} 3`,
+		`objstore_backend_concurrency_decreases_total{backend="s3"} 4`,
+		`objstore_backend_concurrency_rejections_total{backend="s3"} 1`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteReaperStats(t *testing.T) {
+	var sb strings.Builder
+	writeReaperStats(&sb, []reaper.Stats{
+		{Backend: "s3", Kind: reaper.KindMultipart, InProgress: 2, InProgressBytes: 2048, Aborted: 5, ReclaimedBytes: 1 << 30},
+	})
+	out := sb.String()
+	for _, want := range []string{
+		`objstore_uploads_in_progress{backend="s3",kind="multipart"} 2`,
+		`objstore_uploads_in_progress_bytes{backend="s3",kind="multipart"} 2048`,
+		`objstore_stale_uploads_aborted_total{backend="s3",kind="multipart"} 5`,
+		`objstore_stale_upload_bytes_reclaimed_total{backend="s3",kind="multipart"} 1073741824`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	servererrors "github.com/jeremyhahn/go-objstore/pkg/server/errors"
+)
+
+// retryAfterDetails are the error details of responses carrying a
+// one-second Retry-After header.
+func retryAfterDetails() map[string]any {
+	return map[string]any{"retry_after_seconds": 1}
+}
+
+// abortWithError aborts a gin request with the shared JSON error body,
+// tagged with the request ID.
+func abortWithError(c *gin.Context, body *servererrors.ErrorBody) {
+	body.RequestID = GetRequestIDFromGinContext(c)
+	c.AbortWithStatusJSON(body.Status, body)
+}
+
+// writeError writes the shared JSON error body of a net/http request,
+// tagged with the request ID.
+func writeError(w http.ResponseWriter, r *http.Request, body *servererrors.ErrorBody) {
+	body.RequestID = GetRequestIDFromContext(r.Context())
+	servererrors.WriteHTTP(w, body)
+}
@@ -20,6 +20,7 @@ import (
 	"net/http"
 
 	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	servererrors "github.com/jeremyhahn/go-objstore/pkg/server/errors"
 )
 
 // RequestIDHTTPMiddleware is the net/http counterpart of RequestIDMiddleware,
@@ -70,7 +71,9 @@ func (l *RateLimiter) HTTPMiddleware(next http.Handler) http.Handler {
 			w.Header().Set("X-RateLimit-Limit", fmt.Sprintf("%.0f", l.config.RequestsPerSecond))
 			w.Header().Set("X-RateLimit-Burst", fmt.Sprintf("%d", l.config.Burst))
 			w.Header().Set("Retry-After", "1")
-			http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+			body := servererrors.NewErrorBody(http.StatusTooManyRequests, "rate limit exceeded")
+			body.Details = retryAfterDetails()
+			writeError(w, r, body)
 			return
 		}
 
@@ -0,0 +1,570 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	servererrors "github.com/jeremyhahn/go-objstore/pkg/server/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header carrying a client-chosen
+	// idempotency key.
+	IdempotencyKeyHeader = "Idempotency-Key"
+
+	// IdempotentReplayedHeader is set to "true" on responses replayed from a
+	// stored idempotency record.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	// GRPCIdempotencyKeyKey is the metadata key for gRPC idempotency keys.
+	GRPCIdempotencyKeyKey = "idempotency-key"
+
+	// GRPCIdempotentReplayedKey is the response header metadata key set on
+	// replayed gRPC responses.
+	GRPCIdempotentReplayedKey = "idempotent-replayed"
+
+	// idempotencyKeyMaxLen is the maximum accepted length of an idempotency key.
+	idempotencyKeyMaxLen = 255
+
+	// idempotencyMaxFingerprintBody is the largest request body hashed into
+	// the REST request fingerprint; larger bodies contribute only their length.
+	idempotencyMaxFingerprintBody = 1 << 20
+)
+
+// ErrIdempotencyInProgress is returned by an IdempotencyStore when another
+// request holding the same key has not completed yet.
+var ErrIdempotencyInProgress = errors.New("idempotent request in progress")
+
+// IdempotencyRecord is the stored outcome of a completed request.
+type IdempotencyRecord struct {
+	// Fingerprint identifies the request the key was first used with.
+	Fingerprint string
+
+	// StatusCode is the HTTP status of a REST response.
+	StatusCode int
+
+	// Header holds the REST response headers to replay.
+	Header http.Header
+
+	// Body is the REST response body, or the marshaled gRPC response.
+	Body []byte
+
+	// CreatedAt is when the record was stored.
+	CreatedAt time.Time
+}
+
+// IdempotencyStore persists idempotency records. Implementations must be safe
+// for concurrent use and make Begin atomic, so that exactly one request wins a
+// key; a shared store (e.g. Redis) lets several server replicas deduplicate
+// requests together.
+type IdempotencyStore interface {
+	// Begin reserves key for a new request for up to ttl. It returns the
+	// completed record when one exists, ErrIdempotencyInProgress when the key
+	// is reserved by a running request, or (nil, nil) when the caller now
+	// holds the reservation.
+	Begin(ctx context.Context, key string, ttl time.Duration) (*IdempotencyRecord, error)
+
+	// Complete stores record under key for ttl, replacing the reservation.
+	Complete(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) error
+
+	// Release drops the reservation for key so the request can be retried.
+	Release(ctx context.Context, key string) error
+}
+
+// IdempotencyConfig holds idempotency key configuration
+type IdempotencyConfig struct {
+	// Window is how long a completed request is remembered (default: 24h)
+	Window time.Duration
+
+	// LockTimeout bounds how long a running request holds its key, so a
+	// request lost to a crash does not block retries for the whole window
+	// (default: 5m)
+	LockTimeout time.Duration
+
+	// MaxResponseSize is the largest REST response body recorded; larger
+	// responses are not deduplicated (default: 1MB)
+	MaxResponseSize int
+
+	// GRPCMethods lists the unary gRPC method names (without service prefix)
+	// that honor idempotency keys (default: Put, Delete, Archive)
+	GRPCMethods []string
+
+	// Store persists records (default: in-memory store)
+	Store IdempotencyStore
+}
+
+// DefaultIdempotencyConfig returns an idempotency config with sensible defaults
+func DefaultIdempotencyConfig() *IdempotencyConfig {
+	return &IdempotencyConfig{
+		Window:          24 * time.Hour,
+		LockTimeout:     5 * time.Minute,
+		MaxResponseSize: 1 << 20,
+		GRPCMethods:     []string{"Put", "Delete", "Archive"},
+	}
+}
+
+// Idempotency deduplicates retried mutating requests that carry an
+// idempotency key. The first request with a key runs normally and its
+// successful response is recorded; repeats within the window receive the
+// recorded response without running the handler again. Failed requests
+// release their key so the client can retry them.
+//
+// Keys are scoped to the authenticated principal, so attach the middleware
+// after authentication.
+type Idempotency struct {
+	config  IdempotencyConfig
+	methods map[string]struct{}
+	logger  adapters.Logger
+}
+
+// NewIdempotency creates idempotency middleware. Zero config fields take
+// their defaults.
+func NewIdempotency(config *IdempotencyConfig, logger adapters.Logger) *Idempotency {
+	cfg := *DefaultIdempotencyConfig()
+	if config != nil {
+		if config.Window > 0 {
+			cfg.Window = config.Window
+		}
+		if config.LockTimeout > 0 {
+			cfg.LockTimeout = config.LockTimeout
+		}
+		if config.MaxResponseSize > 0 {
+			cfg.MaxResponseSize = config.MaxResponseSize
+		}
+		if len(config.GRPCMethods) > 0 {
+			cfg.GRPCMethods = config.GRPCMethods
+		}
+		cfg.Store = config.Store
+	}
+	if cfg.Store == nil {
+		cfg.Store = NewMemoryIdempotencyStore()
+	}
+	if logger == nil {
+		logger = adapters.NewDefaultLogger()
+	}
+
+	methods := make(map[string]struct{}, len(cfg.GRPCMethods))
+	for _, m := range cfg.GRPCMethods {
+		methods[m] = struct{}{}
+	}
+
+	return &Idempotency{config: cfg, methods: methods, logger: logger}
+}
+
+// validIdempotencyKey reports whether key is usable: non-empty, at most
+// idempotencyKeyMaxLen bytes, and printable ASCII.
+func validIdempotencyKey(key string) bool {
+	if key == "" || len(key) > idempotencyKeyMaxLen {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x21 || key[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// scopedKey namespaces a client key by transport and principal so different
+// callers cannot observe each other's responses.
+func scopedKey(ctx context.Context, transport, key string) string {
+	principal := ""
+	if p, ok := ctx.Value(adapters.PrincipalContextKey{}).(*adapters.Principal); ok && p != nil {
+		principal = p.ID
+	}
+	sum := sha256.Sum256([]byte(principal + "\x00" + key))
+	return transport + ":" + hex.EncodeToString(sum[:])
+}
+
+// isMutatingMethod reports whether an HTTP method changes server state.
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// httpFingerprint identifies a REST request by method, URL and body. Bodies
+// larger than idempotencyMaxFingerprintBody are identified by length only
+// so large uploads are not buffered.
+func httpFingerprint(r *http.Request) (string, error) {
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%s %s\n%s\n", r.Method, r.URL.RequestURI(), r.Header.Get("Content-Type"))
+
+	if r.Body != nil && r.ContentLength >= 0 && r.ContentLength <= idempotencyMaxFingerprintBody {
+		body, err := io.ReadAll(io.LimitReader(r.Body, idempotencyMaxFingerprintBody+1))
+		if err != nil {
+			return "", err
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		_, _ = h.Write(body)
+	} else {
+		_, _ = fmt.Fprintf(h, "length:%d", r.ContentLength)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// recordingWriter tees a response body into a buffer, up to limit bytes.
+type recordingWriter struct {
+	gin.ResponseWriter
+	body     bytes.Buffer
+	limit    int
+	overflow bool
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	w.capture(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *recordingWriter) capture(b []byte) {
+	if w.overflow {
+		return
+	}
+	if w.body.Len()+len(b) > w.limit {
+		w.overflow = true
+		w.body.Reset()
+		return
+	}
+	w.body.Write(b)
+}
+
+// GinMiddleware returns a Gin middleware deduplicating POST, PUT, PATCH and
+// DELETE requests that carry an Idempotency-Key header. A repeat of a
+// completed request is answered with the recorded response and an
+// Idempotent-Replayed header. Reusing a key for a different request returns
+// 422; a repeat that arrives while the original is still running returns 409.
+func (i *Idempotency) GinMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" || !isMutatingMethod(c.Request.Method) {
+			c.Next()
+			return
+		}
+		if !validIdempotencyKey(key) {
+			abortWithError(c, servererrors.NewErrorBody(http.StatusBadRequest,
+				fmt.Sprintf("%s must be 1-%d printable ASCII characters", IdempotencyKeyHeader, idempotencyKeyMaxLen)))
+			return
+		}
+
+		ctx := c.Request.Context()
+		fingerprint, err := httpFingerprint(c.Request)
+		if err != nil {
+			abortWithError(c, servererrors.NewErrorBody(http.StatusBadRequest, "failed to read request body: "+err.Error()))
+			return
+		}
+
+		storeKey := scopedKey(ctx, "rest", key)
+		record, err := i.config.Store.Begin(ctx, storeKey, i.config.LockTimeout)
+		switch {
+		case errors.Is(err, ErrIdempotencyInProgress):
+			c.Header("Retry-After", "1")
+			// The original may still succeed or fail: a retry gets its outcome.
+			body := servererrors.NewErrorBody(http.StatusConflict, "A request with this idempotency key is still being processed")
+			body.Retryable = true
+			body.Details = retryAfterDetails()
+			abortWithError(c, body)
+			return
+		case err != nil:
+			i.logger.Error(ctx, "Idempotency store unavailable",
+				adapters.Field{Key: "error", Value: err.Error()},
+			)
+			abortWithError(c, servererrors.NewErrorBody(http.StatusServiceUnavailable, "Idempotency store unavailable"))
+			return
+		case record != nil:
+			if record.Fingerprint != fingerprint {
+				abortWithError(c, servererrors.NewErrorBody(http.StatusUnprocessableEntity,
+					"The idempotency key was already used for a different request"))
+				return
+			}
+			for name, values := range record.Header {
+				c.Writer.Header()[name] = values
+			}
+			c.Header(IdempotentReplayedHeader, "true")
+			c.Status(record.StatusCode)
+			_, _ = c.Writer.Write(record.Body)
+			c.Abort()
+			return
+		}
+
+		writer := &recordingWriter{ResponseWriter: c.Writer, limit: i.config.MaxResponseSize}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		statusCode := writer.Status()
+		if statusCode < 200 || statusCode >= 300 || writer.overflow {
+			if err := i.config.Store.Release(ctx, storeKey); err != nil {
+				i.logger.Warn(ctx, "Failed to release idempotency key",
+					adapters.Field{Key: "error", Value: err.Error()},
+				)
+			}
+			return
+		}
+
+		header := writer.Header().Clone()
+		header.Del(RequestIDHeader)
+		err = i.config.Store.Complete(ctx, storeKey, &IdempotencyRecord{
+			Fingerprint: fingerprint,
+			StatusCode:  statusCode,
+			Header:      header,
+			Body:        bytes.Clone(writer.body.Bytes()),
+			CreatedAt:   time.Now(),
+		}, i.config.Window)
+		if err != nil {
+			i.logger.Warn(ctx, "Failed to record idempotent response",
+				adapters.Field{Key: "error", Value: err.Error()},
+			)
+		}
+	}
+}
+
+// grpcIdempotencyKey returns the idempotency key from incoming gRPC metadata.
+func grpcIdempotencyKey(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if values := md.Get(GRPCIdempotencyKeyKey); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// grpcFingerprint identifies a unary gRPC request by method and message.
+func grpcFingerprint(fullMethod string, req any) (string, error) {
+	h := sha256.New()
+	_, _ = io.WriteString(h, fullMethod+"\n")
+	if msg, ok := req.(proto.Message); ok {
+		b, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+		if err != nil {
+			return "", err
+		}
+		_, _ = h.Write(b)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// UnaryInterceptor returns a gRPC unary interceptor deduplicating calls to
+// the configured methods that carry idempotency-key metadata. Replayed
+// responses carry idempotent-replayed header metadata. Reusing a key for a
+// different request fails with InvalidArgument; a repeat that arrives while
+// the original is still running fails with Aborted.
+func (i *Idempotency) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		if _, ok := i.methods[path.Base(info.FullMethod)]; !ok {
+			return handler(ctx, req)
+		}
+		key := grpcIdempotencyKey(ctx)
+		if key == "" {
+			return handler(ctx, req)
+		}
+		if !validIdempotencyKey(key) {
+			return nil, status.Errorf(codes.InvalidArgument,
+				"%s must be 1-%d printable ASCII characters", GRPCIdempotencyKeyKey, idempotencyKeyMaxLen)
+		}
+
+		fingerprint, err := grpcFingerprint(info.FullMethod, req)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to fingerprint request: %v", err)
+		}
+
+		storeKey := scopedKey(ctx, "grpc", key)
+		record, err := i.config.Store.Begin(ctx, storeKey, i.config.LockTimeout)
+		switch {
+		case errors.Is(err, ErrIdempotencyInProgress):
+			return nil, status.Error(codes.Aborted, "a request with this idempotency key is still being processed")
+		case err != nil:
+			i.logger.Error(ctx, "Idempotency store unavailable",
+				adapters.Field{Key: "method", Value: info.FullMethod},
+				adapters.Field{Key: "error", Value: err.Error()},
+			)
+			return nil, status.Error(codes.Unavailable, "idempotency store unavailable")
+		case record != nil:
+			if record.Fingerprint != fingerprint {
+				return nil, status.Error(codes.InvalidArgument,
+					"the idempotency key was already used for a different request")
+			}
+			var wrapped anypb.Any
+			if err := proto.Unmarshal(record.Body, &wrapped); err != nil {
+				return nil, status.Errorf(codes.Internal, "failed to decode recorded response: %v", err)
+			}
+			resp, err := wrapped.UnmarshalNew()
+			if err != nil {
+				return nil, status.Errorf(codes.Internal, "failed to decode recorded response: %v", err)
+			}
+			_ = grpc.SetHeader(ctx, metadata.Pairs(GRPCIdempotentReplayedKey, "true")) // Ignore error, continue processing
+			return resp, nil
+		}
+
+		resp, err := handler(ctx, req)
+		if err != nil {
+			if releaseErr := i.config.Store.Release(ctx, storeKey); releaseErr != nil {
+				i.logger.Warn(ctx, "Failed to release idempotency key",
+					adapters.Field{Key: "method", Value: info.FullMethod},
+					adapters.Field{Key: "error", Value: releaseErr.Error()},
+				)
+			}
+			return resp, err
+		}
+
+		if err := i.complete(ctx, storeKey, fingerprint, resp); err != nil {
+			i.logger.Warn(ctx, "Failed to record idempotent response",
+				adapters.Field{Key: "method", Value: info.FullMethod},
+				adapters.Field{Key: "error", Value: err.Error()},
+			)
+		}
+		return resp, nil
+	}
+}
+
+// complete records a successful gRPC response, releasing the key when the
+// response cannot be recorded.
+func (i *Idempotency) complete(ctx context.Context, storeKey, fingerprint string, resp any) error {
+	body, err := marshalResponse(resp)
+	if err != nil {
+		_ = i.config.Store.Release(ctx, storeKey)
+		return err
+	}
+	return i.config.Store.Complete(ctx, storeKey, &IdempotencyRecord{
+		Fingerprint: fingerprint,
+		Body:        body,
+		CreatedAt:   time.Now(),
+	}, i.config.Window)
+}
+
+// marshalResponse encodes a gRPC response as a serialized anypb.Any so it
+// can be decoded later without knowing its type.
+func marshalResponse(resp any) ([]byte, error) {
+	msg, ok := resp.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("response %T is not a protobuf message", resp)
+	}
+	wrapped, err := anypb.New(msg)
+	if err != nil {
+		return nil, err
+	}
+	return proto.Marshal(wrapped)
+}
+
+// memoryIdempotencyEntry is a reservation (record == nil) or a completed record.
+type memoryIdempotencyEntry struct {
+	record  *IdempotencyRecord
+	expires time.Time
+}
+
+// idempotencySweepInterval controls how often expired entries are purged.
+const idempotencySweepInterval = time.Minute
+
+// MemoryIdempotencyStore is an in-process IdempotencyStore. Records are lost
+// on restart and not shared between replicas; use a shared store when running
+// more than one server.
+type MemoryIdempotencyStore struct {
+	mu        sync.Mutex
+	entries   map[string]*memoryIdempotencyEntry
+	nextSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryIdempotencyStore creates an empty in-memory idempotency store.
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{
+		entries: make(map[string]*memoryIdempotencyEntry),
+		now:     time.Now,
+	}
+}
+
+// Begin implements IdempotencyStore.
+func (s *MemoryIdempotencyStore) Begin(_ context.Context, key string, ttl time.Duration) (*IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweepLocked(now)
+
+	if entry, ok := s.entries[key]; ok && now.Before(entry.expires) {
+		if entry.record == nil {
+			return nil, ErrIdempotencyInProgress
+		}
+		return entry.record, nil
+	}
+
+	s.entries[key] = &memoryIdempotencyEntry{expires: now.Add(ttl)}
+	return nil, nil
+}
+
+// Complete implements IdempotencyStore.
+func (s *MemoryIdempotencyStore) Complete(_ context.Context, key string, record *IdempotencyRecord, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[key] = &memoryIdempotencyEntry{record: record, expires: s.now().Add(ttl)}
+	return nil
+}
+
+// Release implements IdempotencyStore.
+func (s *MemoryIdempotencyStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entry, ok := s.entries[key]; ok && entry.record == nil {
+		delete(s.entries, key)
+	}
+	return nil
+}
+
+// sweepLocked purges expired entries at most once per
+// idempotencySweepInterval. Callers must hold s.mu.
+func (s *MemoryIdempotencyStore) sweepLocked(now time.Time) {
+	if now.Before(s.nextSweep) {
+		return
+	}
+	s.nextSweep = now.Add(idempotencySweepInterval)
+	for key, entry := range s.entries {
+		if !now.Before(entry.expires) {
+			delete(s.entries, key)
+		}
+	}
+}
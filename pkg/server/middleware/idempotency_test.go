@@ -0,0 +1,283 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	servererrors "github.com/jeremyhahn/go-objstore/pkg/server/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func newIdempotencyRouter(t *testing.T, idem *Idempotency, calls *int, statusCode int) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(idem.GinMiddleware())
+	handler := func(c *gin.Context) {
+		*calls++
+		c.Header("ETag", "abc")
+		c.JSON(statusCode, gin.H{"call": *calls})
+	}
+	router.PUT("/objects/*key", handler)
+	router.GET("/objects/*key", handler)
+	return router
+}
+
+func idempotentRequest(method, target, body, key string) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	return req
+}
+
+func TestIdempotencyGinMiddleware(t *testing.T) {
+	t.Run("replays completed request", func(t *testing.T) {
+		calls := 0
+		router := newIdempotencyRouter(t, NewIdempotency(nil, adapters.NewNoOpLogger()), &calls, http.StatusCreated)
+
+		first := httptest.NewRecorder()
+		router.ServeHTTP(first, idempotentRequest(http.MethodPut, "/objects/a", "data", "key-1"))
+		second := httptest.NewRecorder()
+		router.ServeHTTP(second, idempotentRequest(http.MethodPut, "/objects/a", "data", "key-1"))
+
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, http.StatusCreated, second.Code)
+		assert.Equal(t, first.Body.String(), second.Body.String())
+		assert.Equal(t, "abc", second.Header().Get("ETag"))
+		assert.Equal(t, "true", second.Header().Get(IdempotentReplayedHeader))
+		assert.Empty(t, first.Header().Get(IdempotentReplayedHeader))
+	})
+
+	t.Run("requests without key are not deduplicated", func(t *testing.T) {
+		calls := 0
+		router := newIdempotencyRouter(t, NewIdempotency(nil, adapters.NewNoOpLogger()), &calls, http.StatusOK)
+
+		for i := 0; i < 2; i++ {
+			router.ServeHTTP(httptest.NewRecorder(), idempotentRequest(http.MethodPut, "/objects/a", "data", ""))
+		}
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("safe methods ignore key", func(t *testing.T) {
+		calls := 0
+		router := newIdempotencyRouter(t, NewIdempotency(nil, adapters.NewNoOpLogger()), &calls, http.StatusOK)
+
+		for i := 0; i < 2; i++ {
+			router.ServeHTTP(httptest.NewRecorder(), idempotentRequest(http.MethodGet, "/objects/a", "", "key-1"))
+		}
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("key reused for different request", func(t *testing.T) {
+		calls := 0
+		router := newIdempotencyRouter(t, NewIdempotency(nil, adapters.NewNoOpLogger()), &calls, http.StatusOK)
+
+		router.ServeHTTP(httptest.NewRecorder(), idempotentRequest(http.MethodPut, "/objects/a", "data", "key-1"))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, idempotentRequest(http.MethodPut, "/objects/a", "other", "key-1"))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("failed request releases key", func(t *testing.T) {
+		calls := 0
+		router := newIdempotencyRouter(t, NewIdempotency(nil, adapters.NewNoOpLogger()), &calls, http.StatusInternalServerError)
+
+		for i := 0; i < 2; i++ {
+			router.ServeHTTP(httptest.NewRecorder(), idempotentRequest(http.MethodPut, "/objects/a", "data", "key-1"))
+		}
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("in-flight request conflicts", func(t *testing.T) {
+		store := NewMemoryIdempotencyStore()
+		calls := 0
+		router := newIdempotencyRouter(t, NewIdempotency(&IdempotencyConfig{Store: store}, adapters.NewNoOpLogger()), &calls, http.StatusOK)
+
+		_, err := store.Begin(context.Background(), scopedKey(context.Background(), "rest", "key-1"), time.Minute)
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, idempotentRequest(http.MethodPut, "/objects/a", "data", "key-1"))
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, 0, calls)
+
+		var body servererrors.ErrorBody
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, "ALREADY_EXISTS", body.Code)
+		assert.True(t, body.Retryable, "a request in progress is retryable")
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		calls := 0
+		router := newIdempotencyRouter(t, NewIdempotency(nil, adapters.NewNoOpLogger()), &calls, http.StatusOK)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, idempotentRequest(http.MethodPut, "/objects/a", "data", strings.Repeat("k", idempotencyKeyMaxLen+1)))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, 0, calls)
+	})
+
+	t.Run("oversized response is not recorded", func(t *testing.T) {
+		calls := 0
+		idem := NewIdempotency(&IdempotencyConfig{MaxResponseSize: 4}, adapters.NewNoOpLogger())
+		router := newIdempotencyRouter(t, idem, &calls, http.StatusOK)
+
+		for i := 0; i < 2; i++ {
+			router.ServeHTTP(httptest.NewRecorder(), idempotentRequest(http.MethodPut, "/objects/a", "data", "key-1"))
+		}
+		assert.Equal(t, 2, calls)
+	})
+}
+
+func TestIdempotencyKeysScopedToPrincipal(t *testing.T) {
+	alice := context.WithValue(context.Background(), adapters.PrincipalContextKey{}, &adapters.Principal{ID: "alice"})
+	bob := context.WithValue(context.Background(), adapters.PrincipalContextKey{}, &adapters.Principal{ID: "bob"})
+
+	assert.NotEqual(t, scopedKey(alice, "rest", "k"), scopedKey(bob, "rest", "k"))
+	assert.NotEqual(t, scopedKey(alice, "rest", "k"), scopedKey(alice, "grpc", "k"))
+	assert.Equal(t, scopedKey(alice, "rest", "k"), scopedKey(alice, "rest", "k"))
+}
+
+func TestIdempotencyUnaryInterceptor(t *testing.T) {
+	putInfo := &grpc.UnaryServerInfo{FullMethod: "/objstore.v1.ObjectStore/Put"}
+	withKey := func(key string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs(GRPCIdempotencyKeyKey, key))
+	}
+
+	t.Run("replays completed call", func(t *testing.T) {
+		interceptor := NewIdempotency(nil, adapters.NewNoOpLogger()).UnaryInterceptor()
+		calls := 0
+		handler := func(ctx context.Context, req any) (any, error) {
+			calls++
+			return wrapperspb.Int64(int64(calls)), nil
+		}
+
+		first, err := interceptor(withKey("k"), wrapperspb.String("a"), putInfo, handler)
+		require.NoError(t, err)
+		second, err := interceptor(withKey("k"), wrapperspb.String("a"), putInfo, handler)
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, calls)
+		assert.True(t, proto.Equal(first.(proto.Message), second.(proto.Message)))
+	})
+
+	t.Run("other methods pass through", func(t *testing.T) {
+		interceptor := NewIdempotency(nil, adapters.NewNoOpLogger()).UnaryInterceptor()
+		calls := 0
+		handler := func(ctx context.Context, req any) (any, error) {
+			calls++
+			return wrapperspb.Bool(true), nil
+		}
+		info := &grpc.UnaryServerInfo{FullMethod: "/objstore.v1.ObjectStore/UpdateMetadata"}
+
+		for i := 0; i < 2; i++ {
+			_, err := interceptor(withKey("k"), wrapperspb.String("a"), info, handler)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("key reused for different request", func(t *testing.T) {
+		interceptor := NewIdempotency(nil, adapters.NewNoOpLogger()).UnaryInterceptor()
+		handler := func(ctx context.Context, req any) (any, error) {
+			return wrapperspb.Bool(true), nil
+		}
+
+		_, err := interceptor(withKey("k"), wrapperspb.String("a"), putInfo, handler)
+		require.NoError(t, err)
+		_, err = interceptor(withKey("k"), wrapperspb.String("b"), putInfo, handler)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("failed call releases key", func(t *testing.T) {
+		interceptor := NewIdempotency(nil, adapters.NewNoOpLogger()).UnaryInterceptor()
+		calls := 0
+		handler := func(ctx context.Context, req any) (any, error) {
+			calls++
+			return nil, status.Error(codes.Unavailable, "backend down")
+		}
+
+		for i := 0; i < 2; i++ {
+			_, err := interceptor(withKey("k"), wrapperspb.String("a"), putInfo, handler)
+			assert.Equal(t, codes.Unavailable, status.Code(err))
+		}
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("in-flight call aborts", func(t *testing.T) {
+		store := NewMemoryIdempotencyStore()
+		interceptor := NewIdempotency(&IdempotencyConfig{Store: store}, adapters.NewNoOpLogger()).UnaryInterceptor()
+		_, err := store.Begin(context.Background(), scopedKey(context.Background(), "grpc", "k"), time.Minute)
+		require.NoError(t, err)
+
+		_, err = interceptor(withKey("k"), wrapperspb.String("a"), putInfo, func(ctx context.Context, req any) (any, error) {
+			t.Fatal("handler must not run")
+			return nil, nil
+		})
+		assert.Equal(t, codes.Aborted, status.Code(err))
+	})
+}
+
+func TestMemoryIdempotencyStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	store := NewMemoryIdempotencyStore()
+	store.now = func() time.Time { return now }
+
+	record, err := store.Begin(ctx, "k", time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, record)
+
+	_, err = store.Begin(ctx, "k", time.Minute)
+	assert.ErrorIs(t, err, ErrIdempotencyInProgress)
+
+	require.NoError(t, store.Complete(ctx, "k", &IdempotencyRecord{Fingerprint: "f"}, time.Hour))
+	record, err = store.Begin(ctx, "k", time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, record)
+	assert.Equal(t, "f", record.Fingerprint)
+
+	// Release never drops a completed record.
+	require.NoError(t, store.Release(ctx, "k"))
+	record, err = store.Begin(ctx, "k", time.Minute)
+	require.NoError(t, err)
+	assert.NotNil(t, record)
+
+	// Records expire after their ttl.
+	now = now.Add(2 * time.Hour)
+	record, err = store.Begin(ctx, "k", time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, record)
+	assert.Len(t, store.entries, 1)
+}
@@ -24,6 +24,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	servererrors "github.com/jeremyhahn/go-objstore/pkg/server/errors"
 	"golang.org/x/time/rate"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -196,11 +197,9 @@ func (l *RateLimiter) GinMiddleware() gin.HandlerFunc {
 			c.Header("X-RateLimit-Burst", fmt.Sprintf("%d", l.config.Burst))
 			c.Header("Retry-After", "1")
 
-			c.JSON(http.StatusTooManyRequests, gin.H{
-				"error":   "Rate limit exceeded",
-				"message": "Too many requests, please try again later",
-			})
-			c.Abort()
+			body := servererrors.NewErrorBody(http.StatusTooManyRequests, "Too many requests, please try again later")
+			body.Details = retryAfterDetails()
+			abortWithError(c, body)
 			return
 		}
 
@@ -15,6 +15,7 @@ package middleware
 
 import (
 	"context"
+	"encoding/json"
 	"net"
 	"net/http"
 	"net/http/httptest"
@@ -24,7 +25,9 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	servererrors "github.com/jeremyhahn/go-objstore/pkg/server/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/peer"
@@ -85,6 +88,12 @@ func TestGinMiddleware(t *testing.T) {
 		router.ServeHTTP(w, req)
 		assert.Equal(t, http.StatusTooManyRequests, w.Code)
 		assert.Contains(t, w.Header().Get("Retry-After"), "1")
+
+		var body servererrors.ErrorBody
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, "RESOURCE_EXHAUSTED", body.Code)
+		assert.True(t, body.Retryable)
+		assert.Equal(t, float64(1), body.Details["retry_after_seconds"])
 	})
 
 	t.Run("per-IP rate limiting", func(t *testing.T) {
@@ -30,8 +30,10 @@ import (
 	"github.com/jeremyhahn/go-objstore/pkg/common"
 	"github.com/jeremyhahn/go-objstore/pkg/factory"
 	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+	"github.com/jeremyhahn/go-objstore/pkg/secrets"
 	servererrors "github.com/jeremyhahn/go-objstore/pkg/server/errors"
 	"github.com/jeremyhahn/go-objstore/pkg/server/metrics"
+	"github.com/jeremyhahn/go-objstore/pkg/server/middleware"
 )
 
 // Constants
@@ -91,7 +93,7 @@ func NewHandler(backend string, maxRequestBodySize int64, readTimeout, writeTime
 		writeTimeout:       writeTimeout,
 		logger:             logger,
 		authenticator:      authenticator,
-		authorizer:         authorizer,
+		authorizer:         adapters.NewScopedAuthorizer(authorizer),
 		allowedOrigins:     allowedOrigins,
 	}, nil
 }
@@ -161,7 +163,7 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 				slog.String(fieldMethod, r.Method),
 			)
 			if !rw.wroteHeader {
-				http.Error(w, "internal server error", http.StatusInternalServerError)
+				writeError(r.Context(), w, http.StatusInternalServerError, "internal server error")
 				metrics.Default.RecordRequest(metrics.TransportQUIC, strconv.Itoa(http.StatusInternalServerError), time.Since(start))
 				return
 			}
@@ -199,7 +201,7 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			adapters.Field{Key: fieldPath, Value: r.URL.Path},
 			adapters.Field{Key: fieldMethod, Value: r.Method},
 		)
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		writeError(r.Context(), w, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
@@ -207,12 +209,16 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// Do NOT assign back to h.logger — that would mutate shared handler state
 	// and cause a data race under concurrent requests.
 	ctx := context.WithValue(r.Context(), principalContextKey, principal)
+	ctx = context.WithValue(ctx, adapters.PrincipalContextKey{}, principal)
 	r = r.WithContext(ctx)
 
 	reqLogger := h.logger.WithFields(
 		adapters.Field{Key: "principal_id", Value: principal.ID},
 		adapters.Field{Key: "principal_name", Value: principal.Name},
 	)
+	if principal.OnBehalfOf != "" {
+		reqLogger = reqLogger.WithFields(adapters.Field{Key: "on_behalf_of", Value: principal.OnBehalfOf})
+	}
 
 	// Authorize the request after successful authentication.
 	action, resource := deriveActionResource(r)
@@ -222,7 +228,7 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			adapters.Field{Key: fieldPath, Value: r.URL.Path},
 			adapters.Field{Key: fieldMethod, Value: r.Method},
 		)
-		http.Error(w, "forbidden", http.StatusForbidden)
+		writeError(r.Context(), w, http.StatusForbidden, "forbidden")
 		return
 	}
 
@@ -237,8 +243,12 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		h.handleExistsHead(rw, r)
 	case strings.HasPrefix(r.URL.Path, "/objects/"):
 		h.handleObject(rw, r)
+	case r.URL.Path == "/objects" && r.Method == http.MethodPatch:
+		h.handleUpdateMetadataBatch(rw, r)
 	case r.URL.Path == "/objects":
 		h.handleList(rw, r)
+	case r.URL.Path == "/browse":
+		h.handleBrowse(rw, r)
 	case r.URL.Path == "/archive":
 		h.handleArchive(rw, r)
 	case r.URL.Path == "/policies/apply":
@@ -256,7 +266,7 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	case strings.HasPrefix(r.URL.Path, "/replication/status/"):
 		h.handleGetReplicationStatus(rw, r)
 	default:
-		http.Error(rw, "not found", http.StatusNotFound)
+		writeError(r.Context(), rw, http.StatusNotFound, "not found")
 	}
 
 	// Log the request using the request-local enriched logger.
@@ -308,8 +318,19 @@ func writeBackendError(ctx context.Context, w http.ResponseWriter, err error) {
 	if ctxErr := ctx.Err(); ctxErr != nil {
 		err = ctxErr
 	}
-	code, message := servererrors.HTTPStatus(err)
-	http.Error(w, message, code)
+	writeErrorBody(ctx, w, servererrors.ErrorBodyFor(err))
+}
+
+// writeError writes the shared JSON error body with the error code status
+// stands for, so QUIC errors have the same shape as REST ones.
+func writeError(ctx context.Context, w http.ResponseWriter, status int, message string) {
+	writeErrorBody(ctx, w, servererrors.NewErrorBody(status, message))
+}
+
+// writeErrorBody writes body tagged with the request ID.
+func writeErrorBody(ctx context.Context, w http.ResponseWriter, body *servererrors.ErrorBody) {
+	body.RequestID = middleware.GetRequestIDFromContext(ctx)
+	servererrors.WriteHTTP(w, body)
 }
 
 // handleHealth handles health check requests.
@@ -329,7 +350,7 @@ func (h *Handler) handleObject(w http.ResponseWriter, r *http.Request) {
 	// Extract key from path
 	key := strings.TrimPrefix(r.URL.Path, "/objects/")
 	if key == "" {
-		http.Error(w, "invalid key", http.StatusBadRequest)
+		writeError(r.Context(), w, http.StatusBadRequest, "invalid key")
 		return
 	}
 
@@ -358,7 +379,7 @@ func (h *Handler) handleObject(w http.ResponseWriter, r *http.Request) {
 	case http.MethodHead:
 		h.handleHead(w, r, key)
 	default:
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		writeError(r.Context(), w, http.StatusMethodNotAllowed, "method not allowed")
 	}
 }
 
@@ -369,7 +390,7 @@ func (h *Handler) handlePut(w http.ResponseWriter, r *http.Request, key string)
 
 	// Check content length
 	if r.ContentLength > h.maxRequestBodySize {
-		http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+		writeError(r.Context(), w, http.StatusRequestEntityTooLarge, "request body too large")
 		return
 	}
 
@@ -391,18 +412,50 @@ func (h *Handler) handlePut(w http.ResponseWriter, r *http.Request, key string)
 		}
 	}
 
+	// With SSE-C headers the object is encrypted with the client's key
+	// before it reaches the backend. The key itself is never stored.
+	var body io.Reader = limitedReader
+	customerKey, err := common.CustomerKeyFromHeaders(r.Header)
+	if err != nil {
+		writeBackendError(ctx, w, err)
+		return
+	}
+	if customerKey != nil {
+		if body, err = customerKey.Seal(limitedReader, metadata); err != nil {
+			writeBackendError(ctx, w, err)
+			return
+		}
+		customerKey.SetHeaders(w.Header(), false)
+	}
+
 	// Store the object using facade
-	err := objstore.PutWithMetadata(ctx, h.keyRef(key), limitedReader, metadata)
+	info, err := objstore.PutWithInfo(ctx, h.keyRef(key), body, metadata)
 	if err != nil {
 		writeBackendError(ctx, w, err)
 		return
 	}
 
-	w.WriteHeader(http.StatusCreated)
-	if err := json.NewEncoder(w).Encode(map[string]string{
+	// Report what was written so clients need no follow-up HEAD
+	written := info.Metadata
+	response := map[string]any{
 		fieldKey:     key,
 		fieldMessage: "object stored successfully",
-	}); err != nil {
+		"etag":       written.ETag,
+		"size":       written.Size,
+	}
+	if written.ETag != "" {
+		w.Header().Set("ETag", written.ETag)
+	}
+	if written.Version != "" {
+		response["version"] = written.Version
+	}
+	if !written.LastModified.IsZero() {
+		w.Header().Set("Last-Modified", written.LastModified.Format(http.TimeFormat))
+		response["last_modified"] = written.LastModified.UTC().Format(time.RFC3339Nano)
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
 		// Log error but response already started
 		h.logger.Error(r.Context(), "failed to encode response", adapters.Field{Key: fieldError, Value: err.Error()})
 	}
@@ -420,7 +473,15 @@ func (h *Handler) handleGet(w http.ResponseWriter, r *http.Request, key string)
 		return
 	}
 	if info == nil {
-		http.Error(w, "object not found", http.StatusNotFound)
+		writeError(r.Context(), w, http.StatusNotFound, "object not found")
+		return
+	}
+	customerKey, err := common.CustomerKeyFromHeaders(r.Header)
+	if err == nil {
+		err = common.CheckCustomerKey(info, customerKey)
+	}
+	if err != nil {
+		writeBackendError(ctx, w, err)
 		return
 	}
 
@@ -432,6 +493,16 @@ func (h *Handler) handleGet(w http.ResponseWriter, r *http.Request, key string)
 	}
 	defer func() { _ = reader.Close() }()
 
+	var body io.Reader = reader
+	if customerKey != nil {
+		if body, err = customerKey.Open(reader, info); err != nil {
+			writeBackendError(ctx, w, err)
+			return
+		}
+		info = common.CustomerKeyMetadata(info)
+		customerKey.SetHeaders(w.Header(), false)
+	}
+
 	// Set response headers
 	if info.ContentType != "" {
 		w.Header().Set("Content-Type", info.ContentType)
@@ -454,7 +525,7 @@ func (h *Handler) handleGet(w http.ResponseWriter, r *http.Request, key string)
 
 	// Copy object data to response
 	w.WriteHeader(http.StatusOK)
-	if _, err := io.Copy(w, reader); err != nil {
+	if _, err := io.Copy(w, body); err != nil {
 		// Cannot send error headers after data has been written
 		// Log error or use middleware to handle this
 		return
@@ -491,6 +562,19 @@ func (h *Handler) handleHead(w http.ResponseWriter, r *http.Request, key string)
 		w.WriteHeader(http.StatusNotFound)
 		return
 	}
+	customerKey, err := common.CustomerKeyFromHeaders(r.Header)
+	if err == nil {
+		err = common.CheckCustomerKey(info, customerKey)
+	}
+	if err != nil {
+		code, _ := servererrors.HTTPStatus(err)
+		w.WriteHeader(code)
+		return
+	}
+	if customerKey != nil {
+		info = common.CustomerKeyMetadata(info)
+		customerKey.SetHeaders(w.Header(), false)
+	}
 
 	// Set response headers
 	if info.ContentType != "" {
@@ -532,13 +616,13 @@ type metadataResponse struct {
 // GET /objects/<key>?exists= query variant is preserved in handleObject.
 func (h *Handler) handleExistsHead(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodHead {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		writeError(r.Context(), w, http.StatusMethodNotAllowed, "method not allowed")
 		return
 	}
 
 	key := strings.TrimPrefix(r.URL.Path, "/exists/")
 	if key == "" {
-		http.Error(w, "key is required", http.StatusBadRequest)
+		writeError(r.Context(), w, http.StatusBadRequest, "key is required")
 		return
 	}
 
@@ -560,14 +644,14 @@ func (h *Handler) handleExistsHead(w http.ResponseWriter, r *http.Request) {
 // keeping the existing HEAD /objects/<key> behavior unchanged.
 func (h *Handler) handleGetMetadata(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		writeError(r.Context(), w, http.StatusMethodNotAllowed, "method not allowed")
 		return
 	}
 
 	key := strings.TrimPrefix(r.URL.Path, "/metadata/")
 	key = path.Clean(key)
 	if key == "" || key == "." {
-		http.Error(w, "key is required", http.StatusBadRequest)
+		writeError(r.Context(), w, http.StatusBadRequest, "key is required")
 		return
 	}
 
@@ -580,9 +664,18 @@ func (h *Handler) handleGetMetadata(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if info == nil {
-		http.Error(w, "object not found", http.StatusNotFound)
+		writeError(r.Context(), w, http.StatusNotFound, "object not found")
 		return
 	}
+	customerKey, err := common.CustomerKeyFromHeaders(r.Header)
+	if err == nil {
+		err = common.CheckCustomerKey(info, customerKey)
+	}
+	if err != nil {
+		writeBackendError(ctx, w, err)
+		return
+	}
+	info = common.CustomerKeyMetadata(info)
 
 	resp := metadataResponse{
 		Key:         key,
@@ -607,7 +700,7 @@ func (h *Handler) handleGetMetadata(w http.ResponseWriter, r *http.Request) {
 // handleList handles GET requests to list objects.
 func (h *Handler) handleList(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		writeError(r.Context(), w, http.StatusMethodNotAllowed, "method not allowed")
 		return
 	}
 
@@ -616,10 +709,22 @@ func (h *Handler) handleList(w http.ResponseWriter, r *http.Request) {
 
 	// Parse query parameters
 	query := r.URL.Query()
+	sortBy, err := common.ParseListSort(query.Get("sort"))
+	if err != nil {
+		writeError(r.Context(), w, http.StatusBadRequest, "invalid sort parameter")
+		return
+	}
+	order := query.Get("order")
+	if order != "" && order != "asc" && order != "desc" {
+		writeError(r.Context(), w, http.StatusBadRequest, "invalid order parameter")
+		return
+	}
 	options := &common.ListOptions{
-		Prefix:       query.Get("prefix"),
-		Delimiter:    query.Get("delimiter"),
-		ContinueFrom: query.Get("continue"),
+		Prefix:         query.Get("prefix"),
+		Delimiter:      query.Get("delimiter"),
+		ContinueFrom:   query.Get("continue"),
+		SortBy:         sortBy,
+		SortDescending: order == "desc",
 	}
 
 	// Parse max results
@@ -680,6 +785,68 @@ func (h *Handler) handleExists(w http.ResponseWriter, r *http.Request, key strin
 	}
 }
 
+// handleBrowse handles GET requests for the folders and objects directly
+// under a prefix.
+func (h *Handler) handleBrowse(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(r.Context(), w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.readTimeout)
+	defer cancel()
+
+	query := r.URL.Query()
+	opts := &common.BrowseOptions{Delimiter: query.Get("delimiter")}
+	if totals := query.Get("totals"); totals != "" {
+		var err error
+		if opts.Totals, err = strconv.ParseBool(totals); err != nil {
+			writeError(r.Context(), w, http.StatusBadRequest, "invalid totals parameter")
+			return
+		}
+	}
+
+	result, err := objstore.Browse(ctx, h.backend, query.Get("prefix"), opts)
+	if err != nil {
+		writeBackendError(ctx, w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		h.logger.Error(r.Context(), "failed to encode response", adapters.Field{Key: fieldError, Value: err.Error()})
+	}
+}
+
+// handleUpdateMetadataBatch handles PATCH requests to apply a metadata patch
+// to every object under a prefix.
+func (h *Handler) handleUpdateMetadataBatch(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.writeTimeout)
+	defer cancel()
+
+	var patch common.MetadataPatch
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+		writeError(r.Context(), w, http.StatusBadRequest, common.SanitizeErrorMessage(err))
+		return
+	}
+
+	updated, err := objstore.UpdateMetadataBatch(ctx, h.backend, r.URL.Query().Get("prefix"), &patch)
+	if err != nil {
+		writeBackendError(ctx, w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(map[string]any{
+		fieldMessage: "metadata updated successfully",
+		"updated":    updated,
+	}); err != nil {
+		h.logger.Error(r.Context(), "failed to encode response", adapters.Field{Key: fieldError, Value: err.Error()})
+	}
+}
+
 // handleUpdateMetadata handles PATCH requests to update object metadata.
 func (h *Handler) handleUpdateMetadata(w http.ResponseWriter, r *http.Request, key string) {
 	ctx, cancel := context.WithTimeout(r.Context(), h.writeTimeout)
@@ -693,7 +860,7 @@ func (h *Handler) handleUpdateMetadata(w http.ResponseWriter, r *http.Request, k
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, common.SanitizeErrorMessage(err), http.StatusBadRequest)
+		writeError(r.Context(), w, http.StatusBadRequest, common.SanitizeErrorMessage(err))
 		return
 	}
 
@@ -704,6 +871,12 @@ func (h *Handler) handleUpdateMetadata(w http.ResponseWriter, r *http.Request, k
 		Custom:          req.Custom,
 	}
 
+	// Objects encrypted with a customer-provided key keep their wrapped
+	// data key, or they could not be read again.
+	if stored, err := objstore.GetMetadata(ctx, h.keyRef(key)); err == nil {
+		common.KeepCustomerKeyFields(stored, metadata)
+	}
+
 	// Update metadata using facade
 	err := objstore.UpdateMetadata(ctx, h.keyRef(key), metadata)
 	if err != nil {
@@ -724,7 +897,7 @@ func (h *Handler) handleUpdateMetadata(w http.ResponseWriter, r *http.Request, k
 // handleArchive handles POST requests to archive objects.
 func (h *Handler) handleArchive(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		writeError(r.Context(), w, http.StatusMethodNotAllowed, "method not allowed")
 		return
 	}
 
@@ -739,24 +912,24 @@ func (h *Handler) handleArchive(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, common.SanitizeErrorMessage(err), http.StatusBadRequest)
+		writeError(r.Context(), w, http.StatusBadRequest, common.SanitizeErrorMessage(err))
 		return
 	}
 
 	if req.Key == "" {
-		http.Error(w, "key is required", http.StatusBadRequest)
+		writeError(r.Context(), w, http.StatusBadRequest, "key is required")
 		return
 	}
 
 	if req.DestinationType == "" {
-		http.Error(w, "destination_type is required", http.StatusBadRequest)
+		writeError(r.Context(), w, http.StatusBadRequest, "destination_type is required")
 		return
 	}
 
 	// Create archiver from factory
 	archiver, err := createArchiver(req.DestinationType, req.DestinationSettings)
 	if err != nil {
-		http.Error(w, common.SanitizeErrorMessage(err), http.StatusBadRequest)
+		writeError(r.Context(), w, http.StatusBadRequest, common.SanitizeErrorMessage(err))
 		return
 	}
 
@@ -786,7 +959,7 @@ func (h *Handler) handlePolicies(w http.ResponseWriter, r *http.Request) {
 	case http.MethodPost:
 		h.handleAddPolicy(w, r)
 	default:
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		writeError(r.Context(), w, http.StatusMethodNotAllowed, "method not allowed")
 	}
 }
 
@@ -851,27 +1024,27 @@ func (h *Handler) handleAddPolicy(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, common.SanitizeErrorMessage(err), http.StatusBadRequest)
+		writeError(r.Context(), w, http.StatusBadRequest, common.SanitizeErrorMessage(err))
 		return
 	}
 
 	if req.ID == "" {
-		http.Error(w, "policy ID is required", http.StatusBadRequest)
+		writeError(r.Context(), w, http.StatusBadRequest, "policy ID is required")
 		return
 	}
 
 	if req.Action == "" {
-		http.Error(w, "action is required", http.StatusBadRequest)
+		writeError(r.Context(), w, http.StatusBadRequest, "action is required")
 		return
 	}
 
 	if req.Action != actionDelete && req.Action != actionArchive {
-		http.Error(w, "action must be 'delete' or 'archive'", http.StatusBadRequest)
+		writeError(r.Context(), w, http.StatusBadRequest, "action must be 'delete' or 'archive'")
 		return
 	}
 
 	if req.RetentionSeconds < 0 {
-		http.Error(w, "retention_seconds must not be negative", http.StatusBadRequest)
+		writeError(r.Context(), w, http.StatusBadRequest, "retention_seconds must not be negative")
 		return
 	}
 
@@ -886,13 +1059,13 @@ func (h *Handler) handleAddPolicy(w http.ResponseWriter, r *http.Request) {
 	// Create archiver if action is "archive"
 	if req.Action == "archive" {
 		if req.DestinationType == "" {
-			http.Error(w, "destination_type required for archive action", http.StatusBadRequest)
+			writeError(r.Context(), w, http.StatusBadRequest, "destination_type required for archive action")
 			return
 		}
 
 		archiver, err := createArchiver(req.DestinationType, req.DestinationSettings)
 		if err != nil {
-			http.Error(w, common.SanitizeErrorMessage(err), http.StatusBadRequest)
+			writeError(r.Context(), w, http.StatusBadRequest, common.SanitizeErrorMessage(err))
 			return
 		}
 		policy.Destination = archiver
@@ -919,7 +1092,7 @@ func (h *Handler) handleAddPolicy(w http.ResponseWriter, r *http.Request) {
 // handlePolicyByID handles DELETE requests for individual policies.
 func (h *Handler) handlePolicyByID(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodDelete {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		writeError(r.Context(), w, http.StatusMethodNotAllowed, "method not allowed")
 		return
 	}
 
@@ -929,7 +1102,7 @@ func (h *Handler) handlePolicyByID(w http.ResponseWriter, r *http.Request) {
 	// Extract policy ID from path
 	id := strings.TrimPrefix(r.URL.Path, "/policies/")
 	if id == "" {
-		http.Error(w, "policy ID is required", http.StatusBadRequest)
+		writeError(r.Context(), w, http.StatusBadRequest, "policy ID is required")
 		return
 	}
 
@@ -937,7 +1110,7 @@ func (h *Handler) handlePolicyByID(w http.ResponseWriter, r *http.Request) {
 	err := objstore.RemovePolicy(h.backend, id)
 	if err != nil {
 		if errors.Is(err, common.ErrPolicyNotFound) {
-			http.Error(w, "policy not found", http.StatusNotFound)
+			writeError(r.Context(), w, http.StatusNotFound, "policy not found")
 			return
 		}
 		writeBackendError(ctx, w, err)
@@ -957,7 +1130,7 @@ func (h *Handler) handlePolicyByID(w http.ResponseWriter, r *http.Request) {
 // handleApplyPolicies handles POST requests to apply all lifecycle policies.
 func (h *Handler) handleApplyPolicies(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		writeError(r.Context(), w, http.StatusMethodNotAllowed, "method not allowed")
 		return
 	}
 
@@ -971,13 +1144,19 @@ func (h *Handler) handleApplyPolicies(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	expired, err := objstore.DeleteExpired(ctx, h.backend)
+	if err != nil {
+		writeBackendError(ctx, w, err)
+		return
+	}
+
 	if len(policies) == 0 {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
 		if err := json.NewEncoder(w).Encode(map[string]any{
 			fieldMessage:        "no lifecycle policies to apply",
 			"policies_count":    0,
-			"objects_processed": 0,
+			"objects_processed": expired,
 		}); err != nil {
 			h.logger.Error(r.Context(), "failed to encode response", adapters.Field{Key: fieldError, Value: err.Error()})
 		}
@@ -985,7 +1164,7 @@ func (h *Handler) handleApplyPolicies(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Apply policies by listing objects and checking retention
-	objectsProcessed := 0
+	objectsProcessed := expired
 	opts := &common.ListOptions{
 		Prefix: "",
 	}
@@ -1052,8 +1231,9 @@ func (h *Handler) handleApplyPolicies(w http.ResponseWriter, r *http.Request) {
 }
 
 // deriveActionResource maps an HTTP/3 request to a (action, resource) pair using
-// the route taxonomy. Object operations use the object key as the resource;
-// management operations use the resource category constants.
+// the route taxonomy. Object operations use the object key as the resource and
+// listing uses the requested prefix; management operations use the resource
+// category constants.
 func deriveActionResource(r *http.Request) (action, resource string) {
 	urlPath := r.URL.Path
 	method := r.Method
@@ -1068,8 +1248,13 @@ func deriveActionResource(r *http.Request) (action, resource string) {
 		return adapters.ActionAdmin, adapters.ResourcePolicy
 	case urlPath == "/archive":
 		return adapters.ActionAdmin, adapters.ResourcePolicy
+	case urlPath == "/objects" && method == http.MethodPatch:
+		// A batch metadata update writes every object under the prefix.
+		return adapters.ActionWrite, r.URL.Query().Get("prefix")
 	case urlPath == "/objects":
-		return adapters.ActionList, ""
+		return adapters.ActionList, r.URL.Query().Get("prefix")
+	case urlPath == "/browse":
+		return adapters.ActionList, r.URL.Query().Get("prefix")
 	case strings.HasPrefix(urlPath, "/objects/"):
 		key := path.Clean(strings.TrimPrefix(urlPath, "/objects/"))
 		// exists check is a GET with the exists query parameter.
@@ -1093,5 +1278,8 @@ func deriveActionResource(r *http.Request) (action, resource string) {
 
 // createArchiver creates an archiver from factory based on destination type
 func createArchiver(destinationType string, settings map[string]string) (common.Archiver, error) {
+	if err := secrets.RejectReferences(settings); err != nil {
+		return nil, err
+	}
 	return factory.NewArchiver(destinationType, settings)
 }
@@ -36,7 +36,7 @@ func (h *Handler) handleReplicationPolicies(w http.ResponseWriter, r *http.Reque
 	case http.MethodPost:
 		h.handleAddReplicationPolicy(w, r)
 	default:
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		writeError(r.Context(), w, http.StatusMethodNotAllowed, "method not allowed")
 	}
 }
 
@@ -49,7 +49,7 @@ func (h *Handler) handleGetReplicationPolicies(w http.ResponseWriter, r *http.Re
 	repMgr, err := objstore.GetReplicationManager(h.backend)
 	if err != nil {
 		if errors.Is(err, common.ErrReplicationNotSupported) {
-			http.Error(w, "replication not supported by this storage backend", http.StatusInternalServerError)
+			writeError(r.Context(), w, http.StatusInternalServerError, "replication not supported by this storage backend")
 			return
 		}
 		writeBackendError(ctx, w, err)
@@ -78,6 +78,7 @@ func (h *Handler) handleGetReplicationPolicies(w http.ResponseWriter, r *http.Re
 			"enabled":              policy.Enabled,
 			"replication_mode":     policy.ReplicationMode,
 			"encryption":           policy.Encryption,
+			"write_once":           policy.WriteOnce,
 		}
 	}
 
@@ -111,31 +112,32 @@ func (h *Handler) handleAddReplicationPolicy(w http.ResponseWriter, r *http.Requ
 		Enabled             bool                     `json:"enabled"`
 		ReplicationMode     common.ReplicationMode   `json:"replication_mode,omitempty"`
 		Encryption          *common.EncryptionPolicy `json:"encryption,omitempty"`
+		WriteOnce           bool                     `json:"write_once,omitempty"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(r.Context(), w, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	// Validate required fields
 	if req.ID == "" {
-		http.Error(w, "policy ID is required", http.StatusBadRequest)
+		writeError(r.Context(), w, http.StatusBadRequest, "policy ID is required")
 		return
 	}
 
 	if req.SourceBackend == "" {
-		http.Error(w, "source_backend is required", http.StatusBadRequest)
+		writeError(r.Context(), w, http.StatusBadRequest, "source_backend is required")
 		return
 	}
 
 	if req.DestinationBackend == "" {
-		http.Error(w, "destination_backend is required", http.StatusBadRequest)
+		writeError(r.Context(), w, http.StatusBadRequest, "destination_backend is required")
 		return
 	}
 
 	if req.CheckInterval <= 0 {
-		http.Error(w, "check_interval must be positive", http.StatusBadRequest)
+		writeError(r.Context(), w, http.StatusBadRequest, "check_interval must be positive")
 		return
 	}
 
@@ -143,7 +145,7 @@ func (h *Handler) handleAddReplicationPolicy(w http.ResponseWriter, r *http.Requ
 	if req.ReplicationMode != "" {
 		if req.ReplicationMode != common.ReplicationModeTransparent &&
 			req.ReplicationMode != common.ReplicationModeOpaque {
-			http.Error(w, "invalid replication_mode: must be 'transparent' or 'opaque'", http.StatusBadRequest)
+			writeError(r.Context(), w, http.StatusBadRequest, "invalid replication_mode: must be 'transparent' or 'opaque'")
 			return
 		}
 	}
@@ -160,6 +162,7 @@ func (h *Handler) handleAddReplicationPolicy(w http.ResponseWriter, r *http.Requ
 		Enabled:             req.Enabled,
 		ReplicationMode:     req.ReplicationMode,
 		Encryption:          req.Encryption,
+		WriteOnce:           req.WriteOnce,
 	}
 
 	// Set default replication mode if not specified
@@ -171,7 +174,7 @@ func (h *Handler) handleAddReplicationPolicy(w http.ResponseWriter, r *http.Requ
 	repMgr, err := objstore.GetReplicationManager(h.backend)
 	if err != nil {
 		if errors.Is(err, common.ErrReplicationNotSupported) {
-			http.Error(w, "replication not supported by this storage backend", http.StatusInternalServerError)
+			writeError(r.Context(), w, http.StatusInternalServerError, "replication not supported by this storage backend")
 			return
 		}
 		writeBackendError(ctx, w, err)
@@ -205,7 +208,7 @@ func (h *Handler) handleReplicationPolicyByID(w http.ResponseWriter, r *http.Req
 	case http.MethodDelete:
 		h.handleDeleteReplicationPolicy(w, r)
 	default:
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		writeError(r.Context(), w, http.StatusMethodNotAllowed, "method not allowed")
 	}
 }
 
@@ -217,7 +220,7 @@ func (h *Handler) handleGetReplicationPolicy(w http.ResponseWriter, r *http.Requ
 	// Extract policy ID from path
 	id := strings.TrimPrefix(r.URL.Path, "/replication/policies/")
 	if id == "" {
-		http.Error(w, "policy ID is required", http.StatusBadRequest)
+		writeError(r.Context(), w, http.StatusBadRequest, "policy ID is required")
 		return
 	}
 
@@ -225,7 +228,7 @@ func (h *Handler) handleGetReplicationPolicy(w http.ResponseWriter, r *http.Requ
 	repMgr, err := objstore.GetReplicationManager(h.backend)
 	if err != nil {
 		if errors.Is(err, common.ErrReplicationNotSupported) {
-			http.Error(w, "replication not supported by this storage backend", http.StatusInternalServerError)
+			writeError(r.Context(), w, http.StatusInternalServerError, "replication not supported by this storage backend")
 			return
 		}
 		writeBackendError(ctx, w, err)
@@ -236,7 +239,7 @@ func (h *Handler) handleGetReplicationPolicy(w http.ResponseWriter, r *http.Requ
 	policy, err := repMgr.GetPolicy(id)
 	if err != nil {
 		if errors.Is(err, common.ErrPolicyNotFound) {
-			http.Error(w, "policy not found", http.StatusNotFound)
+			writeError(r.Context(), w, http.StatusNotFound, "policy not found")
 			return
 		}
 		writeBackendError(ctx, w, err)
@@ -256,6 +259,7 @@ func (h *Handler) handleGetReplicationPolicy(w http.ResponseWriter, r *http.Requ
 		"enabled":              policy.Enabled,
 		"replication_mode":     policy.ReplicationMode,
 		"encryption":           policy.Encryption,
+		"write_once":           policy.WriteOnce,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -273,7 +277,7 @@ func (h *Handler) handleDeleteReplicationPolicy(w http.ResponseWriter, r *http.R
 	// Extract policy ID from path
 	id := strings.TrimPrefix(r.URL.Path, "/replication/policies/")
 	if id == "" {
-		http.Error(w, "policy ID is required", http.StatusBadRequest)
+		writeError(r.Context(), w, http.StatusBadRequest, "policy ID is required")
 		return
 	}
 
@@ -281,7 +285,7 @@ func (h *Handler) handleDeleteReplicationPolicy(w http.ResponseWriter, r *http.R
 	repMgr, err := objstore.GetReplicationManager(h.backend)
 	if err != nil {
 		if errors.Is(err, common.ErrReplicationNotSupported) {
-			http.Error(w, "replication not supported by this storage backend", http.StatusInternalServerError)
+			writeError(r.Context(), w, http.StatusInternalServerError, "replication not supported by this storage backend")
 			return
 		}
 		writeBackendError(ctx, w, err)
@@ -292,7 +296,7 @@ func (h *Handler) handleDeleteReplicationPolicy(w http.ResponseWriter, r *http.R
 	err = repMgr.RemovePolicy(id)
 	if err != nil {
 		if errors.Is(err, common.ErrPolicyNotFound) {
-			http.Error(w, "policy not found", http.StatusNotFound)
+			writeError(r.Context(), w, http.StatusNotFound, "policy not found")
 			return
 		}
 		writeBackendError(ctx, w, err)
@@ -313,7 +317,7 @@ func (h *Handler) handleDeleteReplicationPolicy(w http.ResponseWriter, r *http.R
 // handleTriggerReplication handles POST requests to manually trigger replication.
 func (h *Handler) handleTriggerReplication(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		writeError(r.Context(), w, http.StatusMethodNotAllowed, "method not allowed")
 		return
 	}
 
@@ -329,7 +333,7 @@ func (h *Handler) handleTriggerReplication(w http.ResponseWriter, r *http.Reques
 	repMgr, err := objstore.GetReplicationManager(h.backend)
 	if err != nil {
 		if errors.Is(err, common.ErrReplicationNotSupported) {
-			http.Error(w, "replication not supported by this storage backend", http.StatusInternalServerError)
+			writeError(r.Context(), w, http.StatusInternalServerError, "replication not supported by this storage backend")
 			return
 		}
 		writeBackendError(ctx, w, err)
@@ -356,7 +360,7 @@ func (h *Handler) handleTriggerReplication(w http.ResponseWriter, r *http.Reques
 
 	if err != nil {
 		if errors.Is(err, common.ErrPolicyNotFound) {
-			http.Error(w, "policy not found", http.StatusNotFound)
+			writeError(r.Context(), w, http.StatusNotFound, "policy not found")
 			return
 		}
 		writeBackendError(ctx, w, err)
@@ -392,7 +396,7 @@ func (h *Handler) handleGetReplicationStatus(w http.ResponseWriter, r *http.Requ
 	// Extract policy ID from path
 	id := strings.TrimPrefix(r.URL.Path, "/replication/status/")
 	if id == "" {
-		http.Error(w, "policy ID is required", http.StatusBadRequest)
+		writeError(r.Context(), w, http.StatusBadRequest, "policy ID is required")
 		return
 	}
 
@@ -400,7 +404,7 @@ func (h *Handler) handleGetReplicationStatus(w http.ResponseWriter, r *http.Requ
 	repMgr, err := objstore.GetReplicationManager(h.backend)
 	if err != nil {
 		if errors.Is(err, common.ErrReplicationNotSupported) {
-			http.Error(w, "replication not supported by this storage backend", http.StatusInternalServerError)
+			writeError(r.Context(), w, http.StatusInternalServerError, "replication not supported by this storage backend")
 			return
 		}
 		writeBackendError(ctx, w, err)
@@ -412,13 +416,13 @@ func (h *Handler) handleGetReplicationStatus(w http.ResponseWriter, r *http.Requ
 		GetReplicationStatus(id string) (*replication.ReplicationStatus, error)
 	})
 	if !ok {
-		http.Error(w, "replication status not supported by this backend", http.StatusInternalServerError)
+		writeError(r.Context(), w, http.StatusInternalServerError, "replication status not supported by this backend")
 		return
 	}
 	replicationStatus, err := statusProvider.GetReplicationStatus(id)
 	if err != nil {
 		if errors.Is(err, common.ErrPolicyNotFound) {
-			http.Error(w, "policy not found", http.StatusNotFound)
+			writeError(r.Context(), w, http.StatusNotFound, "policy not found")
 			return
 		}
 		writeBackendError(ctx, w, err)
@@ -29,6 +29,8 @@ import (
 	"github.com/jeremyhahn/go-objstore/pkg/adapters"
 	"github.com/jeremyhahn/go-objstore/pkg/common"
 	"github.com/jeremyhahn/go-objstore/pkg/local"
+	servererrors "github.com/jeremyhahn/go-objstore/pkg/server/errors"
+	"github.com/jeremyhahn/go-objstore/pkg/server/middleware"
 )
 
 func setupTestHandler(t *testing.T) (*Handler, common.Storage) {
@@ -63,13 +65,13 @@ func TestHandlerPutObject(t *testing.T) {
 		t.Errorf("Expected status 201, got %d", w.Code)
 	}
 
-	var response map[string]string
+	var response map[string]any
 	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
 		t.Fatalf("Failed to decode response: %v", err)
 	}
 
 	if response["key"] != "test-key" {
-		t.Errorf("Expected key 'test-key', got %s", response["key"])
+		t.Errorf("Expected key 'test-key', got %v", response["key"])
 	}
 }
 
@@ -138,6 +140,23 @@ func TestHandlerGetObjectNotFound(t *testing.T) {
 	}
 }
 
+func TestHandlerErrorBody(t *testing.T) {
+	handler, _ := setupTestHandler(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/objects/nonexistent", nil)
+	req = req.WithContext(context.WithValue(req.Context(), middleware.RequestIDContextKey, "req-7"))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	var body servererrors.ErrorBody
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("error body %q is not JSON: %v", w.Body.String(), err)
+	}
+	if body.Code != "NOT_FOUND" || body.Status != http.StatusNotFound || body.RequestID != "req-7" || body.Retryable {
+		t.Errorf("error body = %+v, want a non-retryable NOT_FOUND for req-7", body)
+	}
+}
+
 func TestHandlerDeleteObject(t *testing.T) {
 	handler, storage := setupTestHandler(t)
 
@@ -391,6 +410,63 @@ func TestHandlerCustomMetadata(t *testing.T) {
 	}
 }
 
+func TestHandlerCustomerProvidedKey(t *testing.T) {
+	handler, storage := setupTestHandler(t)
+
+	key, _ := common.NewCustomerKey(bytes.Repeat([]byte{1}, 32))
+	other, _ := common.NewCustomerKey(bytes.Repeat([]byte{2}, 32))
+	do := func(method, path string, body io.Reader, k *common.CustomerKey) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, body)
+		if k != nil {
+			k.SetHeaders(req.Header, true)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := do(http.MethodPut, "/objects/secret.txt", strings.NewReader("top secret"), key); w.Code != http.StatusCreated {
+		t.Fatalf("PUT status = %d: %s", w.Code, w.Body.String())
+	}
+	rc, err := storage.Get("secret.txt")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	stored, _ := io.ReadAll(rc)
+	rc.Close()
+	if bytes.Contains(stored, []byte("top secret")) {
+		t.Fatal("backend stores plaintext")
+	}
+
+	w := do(http.MethodGet, "/objects/secret.txt", nil, key)
+	if w.Code != http.StatusOK || w.Body.String() != "top secret" {
+		t.Fatalf("GET = %d %q", w.Code, w.Body.String())
+	}
+	if w.Header().Get(common.HeaderSSECustomerKeyMD5) != key.KeyMD5() {
+		t.Errorf("GET key MD5 header = %q", w.Header().Get(common.HeaderSSECustomerKeyMD5))
+	}
+	if w := do(http.MethodHead, "/objects/secret.txt", nil, key); w.Header().Get("Content-Length") != "10" {
+		t.Errorf("HEAD Content-Length = %s, want 10", w.Header().Get("Content-Length"))
+	}
+	if w := do(http.MethodGet, "/objects/secret.txt", nil, nil); w.Code != http.StatusBadRequest {
+		t.Errorf("GET without key status = %d, want 400", w.Code)
+	}
+	if w := do(http.MethodGet, "/objects/secret.txt", nil, other); w.Code != http.StatusForbidden {
+		t.Errorf("GET with other key status = %d, want 403", w.Code)
+	}
+	if w := do(http.MethodGet, "/metadata/secret.txt", nil, other); w.Code != http.StatusForbidden {
+		t.Errorf("GET metadata with other key status = %d, want 403", w.Code)
+	}
+
+	w = do(http.MethodPatch, "/objects/secret.txt", strings.NewReader(`{"custom":{"owner":"bob"}}`), nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("PATCH status = %d", w.Code)
+	}
+	if w := do(http.MethodGet, "/objects/secret.txt", nil, key); w.Body.String() != "top secret" {
+		t.Errorf("GET after metadata update = %d %q", w.Code, w.Body.String())
+	}
+}
+
 func TestHandlerContentEncoding(t *testing.T) {
 	handler, _ := setupTestHandler(t)
 
@@ -508,7 +584,7 @@ func TestHandlerArchive(t *testing.T) {
 		t.Error("Expected message in response")
 	}
 	if response["key"] != "test-key" {
-		t.Errorf("Expected key 'test-key', got %s", response["key"])
+		t.Errorf("Expected key 'test-key', got %v", response["key"])
 	}
 }
 
@@ -0,0 +1,157 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+)
+
+// accessKeysPath is the prefix of the access key API. Access key routes
+// are authorized as admin actions on adapters.ResourceAccessKey.
+const accessKeysPath = "/api/v1/access-keys"
+
+// errAccessKeysNotEnabled is reported when the server has no access key
+// store.
+var errAccessKeysNotEnabled = errors.New("access keys are not enabled on this server")
+
+// CreateAccessKeyRequest issues a SigV4 access key for a principal
+type CreateAccessKeyRequest struct {
+	PrincipalID string   `json:"principal_id" binding:"required" example:"ci-deployer"`
+	Roles       []string `json:"roles,omitempty" example:"writer"`
+	Description string   `json:"description,omitempty" example:"CI uploads"`
+} // @name CreateAccessKeyRequest
+
+// AccessKeyResponse is an issued access key. The secret is only returned
+// when the key is created.
+type AccessKeyResponse struct {
+	AccessKeyID     string   `json:"access_key_id" example:"OSKJ7Q2MZ4XK3TPLWA5D"`
+	SecretAccessKey string   `json:"secret_access_key,omitempty"`
+	PrincipalID     string   `json:"principal_id" example:"ci-deployer"`
+	Roles           []string `json:"roles,omitempty" example:"writer"`
+	Description     string   `json:"description,omitempty" example:"CI uploads"`
+	CreatedAt       string   `json:"created_at" example:"2025-11-05T10:00:00Z"`
+} // @name AccessKey
+
+// AccessKeysResponse lists access keys
+type AccessKeysResponse struct {
+	AccessKeys []AccessKeyResponse `json:"access_keys"`
+	Count      int                 `json:"count" example:"1"`
+} // @name AccessKeyList
+
+// ListAccessKeys lists the issued access keys, without their secrets
+func (h *Handler) ListAccessKeys(c *gin.Context) {
+	if h.accessKeys == nil {
+		respondWithAccessKeyError(c, errAccessKeysNotEnabled)
+		return
+	}
+
+	keys := h.accessKeys.List()
+	response := AccessKeysResponse{
+		AccessKeys: make([]AccessKeyResponse, 0, len(keys)),
+		Count:      len(keys),
+	}
+	for i := range keys {
+		response.AccessKeys = append(response.AccessKeys, accessKeyResponse(&keys[i]))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// CreateAccessKey issues an access key and returns its secret, which is
+// not shown again
+func (h *Handler) CreateAccessKey(c *gin.Context) {
+	var body CreateAccessKeyRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	if h.accessKeys == nil {
+		respondWithAccessKeyError(c, errAccessKeysNotEnabled)
+		return
+	}
+
+	key, err := h.accessKeys.Create(adapters.AccessKey{
+		PrincipalID: body.PrincipalID,
+		Roles:       body.Roles,
+		Description: body.Description,
+	})
+	if err != nil {
+		respondWithAccessKeyError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, accessKeyResponse(key))
+}
+
+// GetAccessKey returns an issued access key without its secret
+func (h *Handler) GetAccessKey(c *gin.Context) {
+	if h.accessKeys == nil {
+		respondWithAccessKeyError(c, errAccessKeysNotEnabled)
+		return
+	}
+
+	key, err := h.accessKeys.Get(c.Param("id"))
+	if err != nil {
+		respondWithAccessKeyError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, accessKeyResponse(key))
+}
+
+// DeleteAccessKey revokes an access key
+func (h *Handler) DeleteAccessKey(c *gin.Context) {
+	if h.accessKeys == nil {
+		respondWithAccessKeyError(c, errAccessKeysNotEnabled)
+		return
+	}
+
+	if err := h.accessKeys.Delete(c.Param("id")); err != nil {
+		respondWithAccessKeyError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// isAccessKeysPath reports whether path belongs to the access key API.
+func isAccessKeysPath(path string) bool {
+	return path == accessKeysPath || strings.HasPrefix(path, accessKeysPath+"/")
+}
+
+func respondWithAccessKeyError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, errAccessKeysNotEnabled):
+		RespondWithError(c, http.StatusNotImplemented, err.Error())
+	case errors.Is(err, adapters.ErrInvalidAccessKey):
+		RespondWithError(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, adapters.ErrAccessKeyNotFound):
+		RespondWithError(c, http.StatusNotFound, err.Error())
+	default:
+		RespondWithBackendError(c, err)
+	}
+}
+
+func accessKeyResponse(key *adapters.AccessKey) AccessKeyResponse {
+	return AccessKeyResponse{
+		AccessKeyID:     key.AccessKeyID,
+		SecretAccessKey: key.SecretAccessKey,
+		PrincipalID:     key.PrincipalID,
+		Roles:           key.Roles,
+		Description:     key.Description,
+		CreatedAt:       key.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+)
+
+func TestAccessKeyEndpoints(t *testing.T) {
+	keys, err := adapters.NewAccessKeyStore("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bearer := adapters.NewBearerTokenAuthenticator(func(_ context.Context, token string) (*adapters.Principal, error) {
+		return &adapters.Principal{ID: token, Name: token}, nil
+	})
+	router := newManagementTestServer(t, nil, func(c *ServerConfig) {
+		c.AccessKeys = keys
+		c.Authenticator = adapters.NewCompositeAuthenticator(adapters.NewSigV4Authenticator(keys.LookupSecret), bearer)
+	})
+
+	w := doBearerRequest(router, http.MethodPost, "/api/v1/access-keys", "admin", `{"principal_id":"ci","description":"uploads"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create = %d %s", w.Code, w.Body.String())
+	}
+	var created AccessKeyResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil || created.SecretAccessKey == "" || created.PrincipalID != "ci" {
+		t.Fatalf("created = %+v, %v", created, err)
+	}
+
+	// The issued key signs requests as its principal
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/objects/ci/build.tar", strings.NewReader("data"))
+	adapters.SignHTTPRequestV4(req, adapters.SigV4Credentials{AccessKeyID: created.AccessKeyID, SecretAccessKey: created.SecretAccessKey},
+		"us-east-1", "objstore", adapters.SigV4UnsignedPayload, time.Now())
+	signed := httptest.NewRecorder()
+	router.ServeHTTP(signed, req)
+	if signed.Code != http.StatusCreated {
+		t.Errorf("signed put = %d %s", signed.Code, signed.Body.String())
+	}
+
+	w = doBearerRequest(router, http.MethodGet, "/api/v1/access-keys", "admin", "")
+	var list AccessKeysResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil || list.Count != 1 || list.AccessKeys[0].SecretAccessKey != "" {
+		t.Errorf("list = %d %s", w.Code, w.Body.String())
+	}
+	w = doBearerRequest(router, http.MethodGet, "/api/v1/access-keys/"+created.AccessKeyID, "admin", "")
+	if w.Code != http.StatusOK || strings.Contains(w.Body.String(), created.SecretAccessKey) {
+		t.Errorf("get = %d %s", w.Code, w.Body.String())
+	}
+	if w := doBearerRequest(router, http.MethodPost, "/api/v1/access-keys", "alice", `{"principal_id":"alice"}`); w.Code != http.StatusForbidden {
+		t.Errorf("create as non-admin = %d, want %d", w.Code, http.StatusForbidden)
+	}
+	if w := doBearerRequest(router, http.MethodPost, "/api/v1/access-keys", "admin", `{}`); w.Code != http.StatusBadRequest {
+		t.Errorf("create without principal = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	if w := doBearerRequest(router, http.MethodDelete, "/api/v1/access-keys/"+created.AccessKeyID, "admin", ""); w.Code != http.StatusNoContent {
+		t.Errorf("delete = %d %s", w.Code, w.Body.String())
+	}
+	for _, method := range []string{http.MethodGet, http.MethodDelete} {
+		if w := doBearerRequest(router, method, "/api/v1/access-keys/"+created.AccessKeyID, "admin", ""); w.Code != http.StatusNotFound {
+			t.Errorf("%s revoked key = %d, want %d", method, w.Code, http.StatusNotFound)
+		}
+	}
+}
+
+func TestAccessKeyEndpointsNotEnabled(t *testing.T) {
+	router := newManagementTestServer(t, nil)
+	w := doBearerRequest(router, http.MethodGet, "/api/v1/access-keys", "admin", "")
+	if w.Code != http.StatusNotImplemented {
+		t.Errorf("list = %d %s, want %d", w.Code, w.Body.String(), http.StatusNotImplemented)
+	}
+}
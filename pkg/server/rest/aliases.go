@@ -0,0 +1,198 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/alias"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+	servererrors "github.com/jeremyhahn/go-objstore/pkg/server/errors"
+)
+
+// aliasesPath is the prefix of the object alias API. Alias routes are
+// authorized against the alias name, like object routes.
+const aliasesPath = "/api/v1/aliases"
+
+// SetAliasRequest points an alias at an object
+type SetAliasRequest struct {
+	Target string `json:"target" binding:"required" example:"releases/v1.4.2/app.tar.gz"`
+} // @name SetAliasRequest
+
+// AliasResponse is a named reference to an object
+type AliasResponse struct {
+	Name      string `json:"name" example:"releases/latest"`
+	Target    string `json:"target" example:"releases/v1.4.2/app.tar.gz"`
+	UpdatedAt string `json:"updated_at" example:"2025-11-05T10:00:00Z"`
+	UpdatedBy string `json:"updated_by,omitempty" example:"ci"`
+} // @name Alias
+
+// AliasesResponse lists aliases
+type AliasesResponse struct {
+	Aliases []AliasResponse `json:"aliases"`
+	Count   int             `json:"count" example:"1"`
+} // @name AliasList
+
+// ListAliases lists aliases, optionally filtered by name prefix
+func (h *Handler) ListAliases(c *gin.Context) {
+	manager, err := objstore.Aliases(h.backend)
+	if err != nil {
+		respondWithAliasError(c, err)
+		return
+	}
+
+	aliases, err := manager.List(c.Request.Context(), c.Query("prefix"))
+	if err != nil {
+		respondWithAliasError(c, err)
+		return
+	}
+	response := AliasesResponse{
+		Aliases: make([]AliasResponse, 0, len(aliases)),
+		Count:   len(aliases),
+	}
+	for i := range aliases {
+		response.Aliases = append(response.Aliases, aliasResponse(&aliases[i]))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetAlias resolves an alias to the key of its target
+func (h *Handler) GetAlias(c *gin.Context) {
+	name := strings.TrimLeft(c.Param(keyField), "/")
+	if name == "" {
+		RespondWithError(c, http.StatusBadRequest, "alias name is required")
+		return
+	}
+
+	manager, err := objstore.Aliases(h.backend)
+	if err != nil {
+		respondWithAliasError(c, err)
+		return
+	}
+
+	a, err := manager.Resolve(c.Request.Context(), name)
+	if err != nil {
+		respondWithAliasError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, aliasResponse(a))
+}
+
+// SetAlias creates an alias or moves it to another target. Reading an
+// alias reads its target, so the caller must be allowed to read the
+// target as well as write the alias.
+func (h *Handler) SetAlias(c *gin.Context) {
+	name := strings.TrimLeft(c.Param(keyField), "/")
+	if name == "" {
+		RespondWithError(c, http.StatusBadRequest, "alias name is required")
+		return
+	}
+
+	var body SetAliasRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	if !h.authorizeAliasTarget(c, body.Target) {
+		return
+	}
+
+	manager, err := objstore.Aliases(h.backend)
+	if err != nil {
+		respondWithAliasError(c, err)
+		return
+	}
+
+	a, err := manager.Set(c.Request.Context(), name, body.Target)
+	if err != nil {
+		respondWithAliasError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, aliasResponse(a))
+}
+
+// DeleteAlias removes an alias, leaving its target as is
+func (h *Handler) DeleteAlias(c *gin.Context) {
+	name := strings.TrimLeft(c.Param(keyField), "/")
+	if name == "" {
+		RespondWithError(c, http.StatusBadRequest, "alias name is required")
+		return
+	}
+
+	manager, err := objstore.Aliases(h.backend)
+	if err != nil {
+		respondWithAliasError(c, err)
+		return
+	}
+
+	if err := manager.Delete(c.Request.Context(), name); err != nil {
+		respondWithAliasError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// authorizeAliasTarget checks that the caller may read target, responding
+// with 403 and returning false if not.
+func (h *Handler) authorizeAliasTarget(c *gin.Context, target string) bool {
+	if h.authorizer == nil {
+		return true
+	}
+	value, _ := c.Get(principalContextKey)
+	principal, _ := value.(*adapters.Principal)
+	if principal == nil {
+		RespondWithError(c, http.StatusForbidden, "Forbidden")
+		return false
+	}
+	if err := h.authorizer.Authorize(c.Request.Context(), principal, adapters.ActionRead, target); err != nil {
+		RespondWithError(c, http.StatusForbidden, "Forbidden")
+		return false
+	}
+	return true
+}
+
+// isAliasesPath reports whether path belongs to the object alias API.
+func isAliasesPath(path string) bool {
+	return path == aliasesPath || strings.HasPrefix(path, aliasesPath+"/")
+}
+
+// respondWithAliasError maps alias errors to responses that name the alias
+// or target at fault rather than the generic object messages.
+func respondWithAliasError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, objstore.ErrAliasesNotEnabled):
+		RespondWithError(c, http.StatusNotImplemented, "aliases are not enabled on this server")
+	case errors.Is(err, alias.ErrAliasNotFound), errors.Is(err, alias.ErrNameInUse),
+		errors.Is(err, alias.ErrInvalidTarget), errors.Is(err, common.ErrReservedKey):
+		code, _ := servererrors.HTTPStatus(err)
+		RespondWithError(c, code, err.Error())
+	default:
+		RespondWithBackendError(c, err)
+	}
+}
+
+func aliasResponse(a *alias.Alias) AliasResponse {
+	return AliasResponse{
+		Name:      a.Name,
+		Target:    a.Target,
+		UpdatedAt: a.UpdatedAt.Format(time.RFC3339),
+		UpdatedBy: a.UpdatedBy,
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/alias"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+)
+
+// secretAuthorizer lets everyone do anything except read keys under
+// "secret/", which only admin may.
+type secretAuthorizer struct{}
+
+func (secretAuthorizer) Authorize(_ context.Context, p *adapters.Principal, action, resource string) error {
+	if action == adapters.ActionRead && strings.HasPrefix(resource, "secret/") && p.ID != "admin" {
+		return errors.New("denied")
+	}
+	return nil
+}
+
+// newAliasesTestServer builds a server over a memory backend holding
+// "releases/v1/app" and "secret/key"; every bearer token authenticates as
+// the user it names.
+func newAliasesTestServer(t *testing.T, enable bool) *gin.Engine {
+	t.Helper()
+	storage := memory.New()
+	for _, key := range []string{"releases/v1/app", "secret/key"} {
+		if err := storage.Put(key, strings.NewReader("data of "+key)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	initTestFacade(t, storage)
+	if enable {
+		if err := objstore.EnableAliases("", ""); err != nil {
+			t.Fatalf("EnableAliases: %v", err)
+		}
+	}
+
+	config := DefaultServerConfig()
+	config.Mode = gin.TestMode
+	config.Authenticator = adapters.NewBearerTokenAuthenticator(func(_ context.Context, token string) (*adapters.Principal, error) {
+		return &adapters.Principal{ID: token, Name: token}, nil
+	})
+	config.Authorizer = secretAuthorizer{}
+	server, err := NewServer(storage, config)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	return server.Router()
+}
+
+func doBearerRequest(router *gin.Engine, method, path, bearer, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+bearer)
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAliasEndpoints(t *testing.T) {
+	router := newAliasesTestServer(t, true)
+
+	w := doBearerRequest(router, http.MethodPut, "/api/v1/aliases/releases/latest", "alice", `{"target":"releases/v1/app"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("set = %d %s", w.Code, w.Body.String())
+	}
+	var a AliasResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &a); err != nil {
+		t.Fatal(err)
+	}
+	if a.Name != "releases/latest" || a.Target != "releases/v1/app" || a.UpdatedBy != "alice" {
+		t.Errorf("alias = %+v", a)
+	}
+
+	w = doBearerRequest(router, http.MethodGet, "/api/v1/objects/releases/latest", "bob", "")
+	if w.Code != http.StatusOK || w.Body.String() != "data of releases/v1/app" {
+		t.Errorf("GET object through alias = %d %q", w.Code, w.Body.String())
+	}
+	w = doBearerRequest(router, http.MethodGet, "/api/v1/aliases/releases/latest", "bob", "")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"target":"releases/v1/app"`) {
+		t.Errorf("GET alias = %d %s", w.Code, w.Body.String())
+	}
+	w = doBearerRequest(router, http.MethodGet, "/api/v1/aliases?prefix=releases/", "bob", "")
+	var list AliasesResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil || list.Count != 1 {
+		t.Errorf("GET /aliases = %d %s", w.Code, w.Body.String())
+	}
+
+	if w := doBearerRequest(router, http.MethodPut, "/api/v1/aliases/x", "alice", `{"target":"missing"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("set to missing target = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	if w := doBearerRequest(router, http.MethodPut, "/api/v1/aliases/releases/v1/app", "alice", `{"target":"secret/key"}`); w.Code != http.StatusForbidden {
+		t.Errorf("set to unreadable target = %d, want %d", w.Code, http.StatusForbidden)
+	}
+	if w := doBearerRequest(router, http.MethodPut, "/api/v1/aliases/releases/v1/app", "admin", `{"target":"secret/key"}`); w.Code != http.StatusConflict {
+		t.Errorf("set over object = %d, want %d", w.Code, http.StatusConflict)
+	}
+	if w := doBearerRequest(router, http.MethodPut, "/api/v1/aliases/x", "alice", `{}`); w.Code != http.StatusBadRequest {
+		t.Errorf("set without target = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	if w := doBearerRequest(router, http.MethodPut, "/api/v1/objects/"+alias.DefaultPrefix+"releases/latest", "alice", "{}"); w.Code != http.StatusForbidden {
+		t.Errorf("PUT reserved object = %d, want %d", w.Code, http.StatusForbidden)
+	}
+
+	if w := doBearerRequest(router, http.MethodDelete, "/api/v1/aliases/releases/latest", "alice", ""); w.Code != http.StatusNoContent {
+		t.Fatalf("delete = %d %s", w.Code, w.Body.String())
+	}
+	if w := doBearerRequest(router, http.MethodGet, "/api/v1/aliases/releases/latest", "bob", ""); w.Code != http.StatusNotFound {
+		t.Errorf("GET deleted alias = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	if w := doBearerRequest(router, http.MethodGet, "/api/v1/objects/releases/latest", "bob", ""); w.Code != http.StatusNotFound {
+		t.Errorf("GET object through deleted alias = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestAliasEndpointsNotEnabled(t *testing.T) {
+	router := newAliasesTestServer(t, false)
+
+	if w := doBearerRequest(router, http.MethodGet, "/api/v1/aliases", "alice", ""); w.Code != http.StatusNotImplemented {
+		t.Errorf("GET /aliases = %d, want %d", w.Code, http.StatusNotImplemented)
+	}
+	if w := doBearerRequest(router, http.MethodPut, "/api/v1/aliases/x", "alice", `{"target":"releases/v1/app"}`); w.Code != http.StatusNotImplemented {
+		t.Errorf("PUT /aliases/x = %d, want %d", w.Code, http.StatusNotImplemented)
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/access"
+	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+)
+
+// AnalyzePrefix returns how the objects under the prefix query parameter
+// are distributed by age and, when access tracking is enabled, by the time
+// since they were last read. depth groups the objects by key segments
+// below the prefix (negative reports totals only) and buckets lists the
+// bucket bounds, such as "7d,30d,90d".
+func (h *Handler) AnalyzePrefix(c *gin.Context) {
+	opts := &access.AnalyzeOptions{}
+	if depthStr := c.Query("depth"); depthStr != "" {
+		depth, err := strconv.Atoi(depthStr)
+		if err != nil {
+			RespondWithError(c, http.StatusBadRequest, "invalid depth parameter")
+			return
+		}
+		opts.Depth = depth
+	}
+	if buckets := c.Query("buckets"); buckets != "" {
+		bounds, err := access.ParseBuckets(buckets)
+		if err != nil {
+			RespondWithError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		opts.Buckets = bounds
+	}
+
+	report, err := objstore.Analyze(c.Request.Context(), h.backend, c.Query("prefix"), opts)
+	if err != nil {
+		RespondWithBackendError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/access"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+)
+
+func TestAnalyzePrefix(t *testing.T) {
+	backend := memory.New()
+	for _, key := range []string{"logs/2025/a.log", "logs/2026/b.log", "readme.txt"} {
+		if err := backend.Put(key, strings.NewReader("12345")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	handler := newTestHandler(t, backend)
+	if err := objstore.EnableAccessTracking("", nil); err != nil {
+		t.Fatalf("EnableAccessTracking() error = %v", err)
+	}
+	defer objstore.Reset()
+
+	router := gin.New()
+	router.GET("/analyze", handler.AnalyzePrefix)
+
+	tests := []struct {
+		name           string
+		url            string
+		wantStatusCode int
+	}{
+		{"whole backend", "/analyze", http.StatusOK},
+		{"prefix with buckets", "/analyze?prefix=logs/&depth=1&buckets=7d,30d", http.StatusOK},
+		{"invalid depth", "/analyze?depth=deep", http.StatusBadRequest},
+		{"invalid buckets", "/analyze?buckets=30d,7d", http.StatusBadRequest},
+		{"invalid prefix", "/analyze?prefix=../x", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.url, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("AnalyzePrefix() status = %v, want %v, body: %s", w.Code, tt.wantStatusCode, w.Body.String())
+			}
+		})
+	}
+
+	req := httptest.NewRequest("GET", "/analyze?prefix=logs/&buckets=7d,30d", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	var report access.Report
+	if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+		t.Fatalf("Failed to decode report: %v", err)
+	}
+	if report.Total.Objects != 2 || len(report.Total.Age) != 3 || len(report.Prefixes) != 2 || report.TrackingSince == nil {
+		t.Errorf("report = %+v, want 2 objects in 3 age buckets and 2 prefixes", report)
+	}
+}
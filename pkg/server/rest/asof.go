@@ -0,0 +1,62 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+)
+
+// getObjectAsOf serves the version of key that was current at the RFC 3339
+// time asOf, as requested with GET /objects/{key}?as_of=time.
+func (h *Handler) getObjectAsOf(c *gin.Context, key, asOf string, customerKey *common.CustomerKey) {
+	at, err := time.Parse(time.RFC3339, asOf)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "as_of must be an RFC 3339 time")
+		return
+	}
+
+	reader, metadata, err := objstore.GetAsOf(c.Request.Context(), h.keyRef(key), at)
+	switch {
+	case errors.Is(err, objstore.ErrVersionsNotSupported):
+		RespondWithError(c, http.StatusNotImplemented, "the backend does not keep earlier versions of objects")
+		return
+	case err != nil:
+		RespondWithBackendError(c, err)
+		return
+	}
+	defer func() { _ = reader.Close() }()
+
+	if err := common.CheckCustomerKey(metadata, customerKey); err != nil {
+		RespondWithBackendError(c, err)
+		return
+	}
+	var body io.Reader = reader
+	if customerKey != nil {
+		if body, err = customerKey.Open(reader, metadata); err != nil {
+			RespondWithBackendError(c, err)
+			return
+		}
+		metadata = common.CustomerKeyMetadata(metadata)
+		customerKey.SetHeaders(c.Writer.Header(), false)
+	}
+
+	writeObject(c, metadata, body)
+}
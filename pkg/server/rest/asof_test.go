@@ -0,0 +1,89 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+// asOfStorage serves the same earlier version of every key.
+type asOfStorage struct {
+	common.Storage
+	at time.Time
+}
+
+func (s *asOfStorage) GetAsOf(_ context.Context, key string, at time.Time) (io.ReadCloser, *common.Metadata, error) {
+	if at.Before(s.at) {
+		return nil, nil, common.ErrKeyNotFound
+	}
+	return io.NopCloser(strings.NewReader("old")), &common.Metadata{
+		Size:        3,
+		ContentType: "text/plain",
+		ETag:        `"v1"`,
+	}, nil
+}
+
+func TestGetObject_AsOf(t *testing.T) {
+	written := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	handler := newTestHandler(t, &asOfStorage{Storage: memory.New(), at: written})
+
+	router := gin.New()
+	router.GET("/objects/*key", handler.GetObject)
+
+	get := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/objects/config.json?"+query, nil))
+		return w
+	}
+
+	w := get("as_of=2025-06-02T00:00:00Z")
+	if w.Code != http.StatusOK || w.Body.String() != "old" {
+		t.Fatalf("Expected the earlier version, got %d: %s", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("ETag"); got != `"v1"` {
+		t.Errorf("ETag = %q, want the version's ETag", got)
+	}
+
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"as_of=2025-05-01T00:00:00Z", http.StatusNotFound},
+		{"as_of=yesterday", http.StatusBadRequest},
+		{"as_of=2025-06-02T00:00:00Z&jq=.name", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		if w := get(tt.query); w.Code != tt.want {
+			t.Errorf("GET ?%s = %d, want %d", tt.query, w.Code, tt.want)
+		}
+	}
+
+	// A backend without versions.
+	handler = newTestHandler(t, memory.New())
+	router = gin.New()
+	router.GET("/objects/*key", handler.GetObject)
+	if w := get("as_of=2025-06-02T00:00:00Z"); w.Code != http.StatusNotImplemented {
+		t.Errorf("Expected 501 without versions, got %d", w.Code)
+	}
+}
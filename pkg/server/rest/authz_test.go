@@ -234,6 +234,7 @@ func TestIsPublicPath(t *testing.T) {
 		want          bool
 	}{
 		{"/health", false, true},
+		{"/upload", false, true},
 		{"/swagger/index.html", false, false},
 		{"/metrics", false, false},
 		{"/metrics", true, true},
@@ -257,6 +258,11 @@ func TestIsAuthzExemptPath(t *testing.T) {
 	}{
 		{"/health", false, true},
 		{"/swagger/index.html", false, true},
+		{"/ui/", false, true},
+		{"/ui/app.js", false, true},
+		{"/uixyz", false, false},
+		{"/api/v1/uploads/0123456789abcdef0123456789abcdef/chunks/1", false, true},
+		{"/api/v1/uploads/objects/key", false, false},
 		{"/metrics", false, false},
 		{"/metrics", true, true},
 		{"/api/v1/objects/key", false, false},
@@ -0,0 +1,99 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/changefeed"
+	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+)
+
+// ChangeResponse is one entry of the change feed
+type ChangeResponse struct {
+	Sequence  uint64 `json:"sequence" example:"42"`
+	Op        string `json:"op" example:"put"`
+	Key       string `json:"key" example:"logs/2025/11/05.log"`
+	ETag      string `json:"etag,omitempty" example:"1730800800-1048576"`
+	Size      int64  `json:"size,omitempty" example:"1048576"`
+	Timestamp string `json:"timestamp" example:"2025-11-05T10:00:00Z"`
+} // @name Change
+
+// ChangesResponse is a page of the change feed
+type ChangesResponse struct {
+	Changes []ChangeResponse `json:"changes"`
+	Next    uint64           `json:"next" example:"42"`
+	Latest  uint64           `json:"latest" example:"57"`
+	More    bool             `json:"more" example:"true"`
+} // @name ChangeList
+
+// GetChanges returns the changes made after the since sequence, oldest first
+func (h *Handler) GetChanges(c *gin.Context) {
+	var since uint64
+	if sinceStr := c.Query("since"); sinceStr != "" {
+		n, err := strconv.ParseUint(sinceStr, 10, 64)
+		if err != nil {
+			RespondWithError(c, http.StatusBadRequest, "invalid since parameter")
+			return
+		}
+		since = n
+	}
+
+	var limit int
+	if limitStr := c.Query("limit"); limitStr != "" {
+		n, err := strconv.Atoi(limitStr)
+		if err != nil || n < 0 {
+			RespondWithError(c, http.StatusBadRequest, "invalid limit parameter")
+			return
+		}
+		limit = n
+	}
+
+	page, err := objstore.GetChanges(c.Request.Context(), h.backend, since, limit)
+	switch {
+	case errors.Is(err, objstore.ErrChangeFeedNotEnabled):
+		RespondWithError(c, http.StatusNotImplemented, "change feed is not enabled on this server")
+		return
+	case errors.Is(err, changefeed.ErrSequenceExpired):
+		// The consumer has fallen behind the journal and must
+		// resynchronize from a full listing.
+		RespondWithError(c, http.StatusGone, err.Error())
+		return
+	case err != nil:
+		RespondWithBackendError(c, err)
+		return
+	}
+
+	changes := make([]ChangeResponse, len(page.Changes))
+	for i, change := range page.Changes {
+		changes[i] = ChangeResponse{
+			Sequence:  change.Sequence,
+			Op:        change.Op,
+			Key:       change.Key,
+			ETag:      change.ETag,
+			Size:      change.Size,
+			Timestamp: change.Timestamp.Format(time.RFC3339Nano),
+		}
+	}
+	c.JSON(http.StatusOK, ChangesResponse{
+		Changes: changes,
+		Next:    page.Next,
+		Latest:  page.Latest,
+		More:    page.More,
+	})
+}
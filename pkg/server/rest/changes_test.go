@@ -0,0 +1,102 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+)
+
+func TestGetChanges(t *testing.T) {
+	handler := newTestHandler(t, memory.New())
+
+	router := gin.New()
+	router.GET("/changes", handler.GetChanges)
+
+	// The change feed is not enabled yet
+	req := httptest.NewRequest("GET", "/changes", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotImplemented {
+		t.Errorf("GetChanges() status = %v, want %v", w.Code, http.StatusNotImplemented)
+	}
+
+	if err := objstore.EnableChangeFeed("", &objstore.ChangeFeedConfig{MaxEntries: 2}); err != nil {
+		t.Fatalf("EnableChangeFeed() error = %v", err)
+	}
+	ctx := context.Background()
+	for _, key := range []string{"a.txt", "b.txt"} {
+		if err := objstore.PutWithContext(ctx, key, strings.NewReader("data")); err != nil {
+			t.Fatalf("Put() error = %v", err)
+		}
+	}
+	if err := objstore.DeleteWithContext(ctx, "a.txt"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		url            string
+		wantStatusCode int
+		wantCount      int
+	}{
+		{"page", "/changes?since=1&limit=1", http.StatusOK, 1},
+		{"rest", "/changes?since=2", http.StatusOK, 1},
+		{"caught up", "/changes?since=3", http.StatusOK, 0},
+		{"expired", "/changes", http.StatusGone, 0},
+		{"invalid since", "/changes?since=-1", http.StatusBadRequest, 0},
+		{"invalid limit", "/changes?limit=x", http.StatusBadRequest, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.url, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("GetChanges() status = %v, want %v, body: %s", w.Code, tt.wantStatusCode, w.Body.String())
+			}
+			if w.Code != http.StatusOK {
+				return
+			}
+			var resp ChangesResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if len(resp.Changes) != tt.wantCount || resp.Latest != 3 {
+				t.Errorf("GetChanges() = %+v", resp)
+			}
+		})
+	}
+
+	req = httptest.NewRequest("GET", "/changes?since=2", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	var resp ChangesResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if change := resp.Changes[0]; change.Sequence != 3 || change.Op != "delete" || change.Key != "a.txt" || resp.Next != 3 || resp.More {
+		t.Errorf("GetChanges() = %+v", resp)
+	}
+}
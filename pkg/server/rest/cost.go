@@ -0,0 +1,42 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/cost"
+	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+)
+
+// GetCostReport returns the estimated cost of a month across all backends,
+// by backend and group. The month query parameter (YYYY-MM) defaults to the
+// current month.
+func (h *Handler) GetCostReport(c *gin.Context) {
+	report, err := objstore.CostReport(c.Request.Context(), c.Query("month"))
+	switch {
+	case errors.Is(err, objstore.ErrCostNotEnabled):
+		RespondWithError(c, http.StatusNotImplemented, "cost metering is not enabled on this server")
+		return
+	case errors.Is(err, cost.ErrInvalidMonth):
+		RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	case err != nil:
+		RespondWithBackendError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/cost"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+)
+
+func TestGetCostReport(t *testing.T) {
+	handler := newTestHandler(t, memory.New())
+
+	router := gin.New()
+	router.GET("/cost", handler.GetCostReport)
+
+	// Cost metering is not enabled yet
+	req := httptest.NewRequest("GET", "/cost", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotImplemented {
+		t.Errorf("GetCostReport() status = %v, want %v", w.Code, http.StatusNotImplemented)
+	}
+
+	cfg := &cost.Config{
+		Currency:       "EUR",
+		DefaultPricing: cost.Pricing{WritePer1000: 5},
+		Groups:         []cost.Group{{Name: "analytics", Prefixes: []string{"reports/"}}},
+	}
+	if err := objstore.EnableCost(cfg); err != nil {
+		t.Fatalf("EnableCost() error = %v", err)
+	}
+	if err := objstore.PutWithContext(context.Background(), "reports/q1.csv", strings.NewReader("data")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		url            string
+		wantStatusCode int
+	}{
+		{"current month", "/cost", http.StatusOK},
+		{"malformed month", "/cost?month=11-2025", http.StatusBadRequest},
+		{"future month", "/cost?month=9999-01", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.url, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("GetCostReport() status = %v, want %v, body: %s", w.Code, tt.wantStatusCode, w.Body.String())
+			}
+		})
+	}
+
+	req = httptest.NewRequest("GET", "/cost", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	var report cost.Report
+	if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if report.Currency != "EUR" || len(report.Groups) != 1 || report.Groups[0].Group != "analytics" || report.Total.Requests != 0.005 {
+		t.Errorf("GetCostReport() = %+v", report)
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+	"github.com/jeremyhahn/go-objstore/pkg/transform"
+)
+
+// getDerivedObject serves the derived object of key for a transform
+// preset, as requested with GET /objects/{key}?preset=name.
+func (h *Handler) getDerivedObject(c *gin.Context, key, preset string) {
+	reader, metadata, err := objstore.GetDerived(c.Request.Context(), h.keyRef(key), preset)
+	switch {
+	case errors.Is(err, objstore.ErrTransformsNotEnabled):
+		RespondWithError(c, http.StatusNotImplemented, "transforms are not enabled on this server")
+		return
+	case errors.Is(err, transform.ErrPresetNotFound):
+		RespondWithError(c, http.StatusNotFound, "unknown preset: "+preset)
+		return
+	case err != nil:
+		RespondWithBackendError(c, err)
+		return
+	}
+	defer func() { _ = reader.Close() }()
+
+	contentType := metadata.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	c.Header("Content-Type", contentType)
+	if metadata.ETag != "" {
+		c.Header("ETag", metadata.ETag)
+	}
+	if !metadata.LastModified.IsZero() {
+		c.Header("Last-Modified", metadata.LastModified.Format(http.TimeFormat))
+	}
+	if metadata.Size > 0 {
+		c.Header("Content-Length", strconv.FormatInt(metadata.Size, 10))
+	}
+	if source := metadata.Custom[transform.MetadataSourceETag]; source != "" {
+		c.Header("X-Source-ETag", source)
+	}
+
+	c.Status(http.StatusOK)
+	if _, err := io.Copy(c.Writer, reader); err != nil {
+		_ = c.Error(err)
+	}
+}
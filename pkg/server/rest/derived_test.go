@@ -0,0 +1,107 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+	"github.com/jeremyhahn/go-objstore/pkg/transform"
+)
+
+func TestGetObject_Preset(t *testing.T) {
+	handler := newTestHandler(t, memory.New())
+
+	router := gin.New()
+	router.GET("/objects/*key", handler.GetObject)
+
+	ctx := context.Background()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 40, 20))); err != nil {
+		t.Fatal(err)
+	}
+	if err := objstore.PutWithMetadata(ctx, "photos/cat.png", &buf, &common.Metadata{ContentType: "image/png"}); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if err := objstore.PutWithContext(ctx, "notes.txt", strings.NewReader("text")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	// Transforms are not enabled yet
+	req := httptest.NewRequest("GET", "/objects/photos/cat.png?preset=thumb", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotImplemented {
+		t.Errorf("GetObject() status = %v, want %v", w.Code, http.StatusNotImplemented)
+	}
+
+	err := objstore.EnableTransforms("", &transform.Config{Presets: []transform.Preset{{
+		Name:         "thumb",
+		Processor:    "image",
+		ContentTypes: []string{"image/*"},
+		Settings:     map[string]string{"width": "10"},
+	}}})
+	if err != nil {
+		t.Fatalf("EnableTransforms() error = %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		url            string
+		wantStatusCode int
+	}{
+		{"thumbnail", "/objects/photos/cat.png?preset=thumb", http.StatusOK},
+		{"original", "/objects/photos/cat.png", http.StatusOK},
+		{"unknown preset", "/objects/photos/cat.png?preset=huge", http.StatusNotFound},
+		{"missing source", "/objects/photos/dog.png?preset=thumb", http.StatusNotFound},
+		{"not an image", "/objects/notes.txt?preset=thumb", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.url, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("GetObject() status = %v, want %v, body: %s", w.Code, tt.wantStatusCode, w.Body.String())
+			}
+		})
+	}
+
+	req = httptest.NewRequest("GET", "/objects/photos/cat.png?preset=thumb", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if ct := w.Header().Get("Content-Type"); ct != "image/jpeg" {
+		t.Errorf("Content-Type = %q, want image/jpeg", ct)
+	}
+	img, err := jpeg.Decode(w.Body)
+	if err != nil {
+		t.Fatalf("thumbnail is not a JPEG: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 10 || b.Dy() != 5 {
+		t.Errorf("thumbnail bounds = %v, want 10x5", b)
+	}
+}
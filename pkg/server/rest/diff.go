@@ -0,0 +1,79 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/diff"
+	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+)
+
+// diffPath is the object diff API.
+const diffPath = "/api/v1/diff"
+
+// isDiffPath reports whether path is the object diff API.
+func isDiffPath(path string) bool {
+	return path == diffPath
+}
+
+// diffKey returns the object key a diff reference reads, without its
+// version suffix, for authorization.
+func diffKey(ref string) string {
+	key, _, _ := diff.SplitVersion(ref)
+	return key
+}
+
+// DiffObjects compares the objects named by the a and b query parameters,
+// each a key optionally followed by @ and an RFC 3339 time to compare the
+// version current then. The mode parameter selects text, json or binary
+// comparison (default: detected) and context the lines of context in a
+// text diff.
+func (h *Handler) DiffObjects(c *gin.Context) {
+	a, b := c.Query("a"), c.Query("b")
+	if a == "" || b == "" {
+		RespondWithError(c, http.StatusBadRequest, "a and b query parameters are required")
+		return
+	}
+	mode, err := diff.ParseMode(c.Query("mode"))
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	opts := diff.Options{Mode: mode}
+	if s := c.Query("context"); s != "" {
+		if opts.Context, err = strconv.Atoi(s); err != nil || opts.Context < 1 {
+			RespondWithError(c, http.StatusBadRequest, "context must be a positive integer")
+			return
+		}
+	}
+
+	result, err := objstore.Diff(c.Request.Context(), h.keyRef(a), h.keyRef(b), opts)
+	switch {
+	case errors.Is(err, objstore.ErrVersionsNotSupported):
+		RespondWithError(c, http.StatusNotImplemented, "the backend does not keep earlier versions of objects")
+		return
+	case errors.Is(err, diff.ErrTooLarge):
+		RespondWithError(c, http.StatusUnprocessableEntity, common.SanitizeErrorMessage(err))
+		return
+	case err != nil:
+		RespondWithBackendError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
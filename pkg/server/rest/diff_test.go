@@ -0,0 +1,117 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/audit"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/diff"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+)
+
+func TestDiffObjects(t *testing.T) {
+	handler := newTestHandler(t, memory.New())
+	router := gin.New()
+	router.GET("/api/v1/diff", handler.DiffObjects)
+
+	ctx := context.Background()
+	for key, data := range map[string]string{
+		"a.txt": "one\ntwo\n",
+		"b.txt": "one\nthree\n",
+	} {
+		if err := objstore.PutWithContext(ctx, key, strings.NewReader(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	get := func(query url.Values) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/diff?"+query.Encode(), nil))
+		return w
+	}
+
+	w := get(url.Values{"a": {"a.txt"}, "b": {"b.txt"}})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var result diff.Result
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Mode != diff.ModeText || !strings.Contains(result.Unified, "-two\n+three\n") {
+		t.Errorf("Unexpected diff: %+v", result)
+	}
+
+	tests := []struct {
+		query url.Values
+		want  int
+	}{
+		{url.Values{"a": {"a.txt"}}, http.StatusBadRequest},
+		{url.Values{"a": {"a.txt"}, "b": {"b.txt"}, "mode": {"xml"}}, http.StatusBadRequest},
+		{url.Values{"a": {"a.txt"}, "b": {"b.txt"}, "context": {"0"}}, http.StatusBadRequest},
+		{url.Values{"a": {"a.txt"}, "b": {"b.txt"}, "mode": {"json"}}, http.StatusBadRequest},
+		{url.Values{"a": {"a.txt"}, "b": {"missing.txt"}}, http.StatusNotFound},
+		{url.Values{"a": {"a.txt@2025-06-01T00:00:00Z"}, "b": {"b.txt"}}, http.StatusNotImplemented},
+	}
+	for _, tt := range tests {
+		if w := get(tt.query); w.Code != tt.want {
+			t.Errorf("GET ?%s = %d, want %d: %s", tt.query.Encode(), w.Code, tt.want, w.Body.String())
+		}
+	}
+}
+
+// keyAuthorizer denies every action on one resource.
+type keyAuthorizer struct {
+	denied string
+}
+
+func (a *keyAuthorizer) Authorize(_ context.Context, _ *adapters.Principal, _, resource string) error {
+	if resource == a.denied {
+		return common.ErrPermissionDenied
+	}
+	return nil
+}
+
+func TestDiffObjects_AuthorizesBothKeys(t *testing.T) {
+	handler := newTestHandler(t, memory.New())
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set(principalContextKey, &adapters.Principal{ID: "user"})
+		c.Next()
+	})
+	router.Use(AuthorizationMiddleware(&keyAuthorizer{denied: "secret.json"}, adapters.NewNoOpLogger(), audit.NewNoOpAuditLogger(), false))
+	router.GET("/api/v1/diff", handler.DiffObjects)
+
+	for _, query := range []string{
+		"a=secret.json&b=public.json",
+		"a=public.json&b=secret.json",
+		"a=public.json&b=secret.json@2025-06-01T00:00:00Z",
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/diff?"+query, nil))
+		if w.Code != http.StatusForbidden {
+			t.Errorf("GET ?%s = %d, want 403", query, w.Code)
+		}
+	}
+}
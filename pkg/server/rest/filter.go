@@ -0,0 +1,120 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/filter"
+	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+)
+
+// headerFilterError is the trailer reporting a filter that failed after
+// the response started.
+const headerFilterError = "X-Filter-Error"
+
+// filterPeekSize is how much filtered output is produced before the
+// response status is sent, so that filters failing on the start of an
+// object are reported with an error status.
+const filterPeekSize = 32 << 10
+
+// filterOptions parses the decompress, range-lines and jq query parameters
+// of a GET request.
+func filterOptions(c *gin.Context) (filter.Options, error) {
+	opts := filter.Options{
+		Decompress: c.Query("decompress"),
+		JQ:         c.Query("jq"),
+	}
+	if lines := c.Query("range-lines"); lines != "" {
+		r, err := filter.ParseLineRange(lines)
+		if err != nil {
+			return filter.Options{}, err
+		}
+		opts.Lines = &r
+	}
+	return opts, nil
+}
+
+// getFilteredObject streams an object through the filters of opts.
+// Because the filtered size is not known in advance, the response has no
+// Content-Length or ETag. A filter that fails after the response started
+// truncates it and is reported in the X-Filter-Error trailer.
+func (h *Handler) getFilteredObject(c *gin.Context, key string, opts filter.Options) {
+	ctx := c.Request.Context()
+	metadata, err := objstore.GetMetadata(ctx, h.keyRef(key))
+	if err != nil {
+		RespondWithBackendError(c, err)
+		return
+	}
+	reader, err := objstore.GetWithContext(ctx, h.keyRef(key))
+	if err != nil {
+		RespondWithBackendError(c, err)
+		return
+	}
+	defer func() { _ = reader.Close() }()
+
+	filtered, err := filter.Apply(ctx, reader, opts, h.filterLimits)
+	if err != nil {
+		respondWithFilterError(c, err)
+		return
+	}
+	defer func() { _ = filtered.Close() }()
+
+	peek := make([]byte, filterPeekSize)
+	n, err := io.ReadFull(filtered, peek)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		respondWithFilterError(c, err)
+		return
+	}
+	complete := err != nil
+
+	contentType := opts.ContentType(metadata.ContentType)
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	c.Header("Content-Type", contentType)
+	if metadata.ContentEncoding != "" && opts.Decompress == "" {
+		c.Header("Content-Encoding", metadata.ContentEncoding)
+	}
+	if !complete {
+		c.Header("Trailer", headerFilterError)
+	}
+	c.Status(http.StatusOK)
+	if _, err := c.Writer.Write(peek[:n]); err != nil || complete {
+		return
+	}
+
+	if _, err := io.Copy(c.Writer, filtered); err != nil {
+		c.Writer.Header().Set(headerFilterError, common.SanitizeErrorMessage(err))
+		_ = c.Error(err)
+	}
+}
+
+// respondWithFilterError maps a filter failure to a response before any of
+// the body was written.
+func respondWithFilterError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, filter.ErrLimitExceeded):
+		RespondWithError(c, http.StatusUnprocessableEntity, common.SanitizeErrorMessage(err))
+	case errors.Is(err, context.DeadlineExceeded):
+		RespondWithError(c, http.StatusUnprocessableEntity, "filter timed out")
+	default:
+		RespondWithBackendError(c, err)
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/filter"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+)
+
+func TestGetObject_Filters(t *testing.T) {
+	handler := newTestHandler(t, memory.New())
+	handler.filterLimits = filter.Limits{MaxOutputBytes: 64 << 10}
+
+	router := gin.New()
+	router.GET("/objects/*key", handler.GetObject)
+
+	ctx := context.Background()
+	var lines strings.Builder
+	for i := 1; i <= 1000; i++ {
+		fmt.Fprintf(&lines, "%d\n", i)
+	}
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	_, _ = zw.Write([]byte(lines.String()))
+	_ = zw.Close()
+
+	objects := map[string]struct {
+		data        io.Reader
+		contentType string
+	}{
+		"logs/app.log.gz": {&gz, "text/plain"},
+		"build.json":      {strings.NewReader(`{"status":"ok","artifacts":[{"path":"a.tar"},{"path":"b.tar"}]}`), "application/json"},
+		"events.ndjson":   {strings.NewReader(strings.Repeat(`{"n":1}`+"\n", 20000)), "application/x-ndjson"},
+	}
+	for key, obj := range objects {
+		if err := objstore.PutWithMetadata(ctx, key, obj.data, &common.Metadata{ContentType: obj.contentType}); err != nil {
+			t.Fatalf("Put(%s) error = %v", key, err)
+		}
+	}
+
+	tests := []struct {
+		name           string
+		url            string
+		wantStatusCode int
+		wantBody       string
+		wantType       string
+	}{
+		{"decompress and lines", "/objects/logs/app.log.gz?decompress=gzip&range-lines=998-", http.StatusOK, "998\n999\n1000\n", "text/plain"},
+		{"jq", "/objects/build.json?jq=" + url.QueryEscape("[.artifacts[].path]"), http.StatusOK, "[\"a.tar\",\"b.tar\"]\n", "application/json"},
+		{"invalid line range", "/objects/build.json?range-lines=5-1", http.StatusBadRequest, "", ""},
+		{"invalid jq", "/objects/build.json?jq=.a%20%7C", http.StatusBadRequest, "", ""},
+		{"not gzip", "/objects/build.json?decompress=gzip", http.StatusBadRequest, "", ""},
+		{"unknown encoding", "/objects/build.json?decompress=rar", http.StatusBadRequest, "", ""},
+		{"not json", "/objects/logs/app.log.gz?jq=.", http.StatusBadRequest, "", ""},
+		{"missing object", "/objects/missing.json?jq=.", http.StatusNotFound, "", ""},
+		{"with preset", "/objects/build.json?jq=.&preset=thumb", http.StatusBadRequest, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.url, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("GetObject() status = %v, want %v, body: %s", w.Code, tt.wantStatusCode, w.Body.String())
+			}
+			if tt.wantStatusCode != http.StatusOK {
+				return
+			}
+			if w.Body.String() != tt.wantBody {
+				t.Errorf("body = %q, want %q", w.Body.String(), tt.wantBody)
+			}
+			if ct := w.Header().Get("Content-Type"); ct != tt.wantType {
+				t.Errorf("Content-Type = %q, want %q", ct, tt.wantType)
+			}
+		})
+	}
+
+	// The output limit is hit after the response started: the body is
+	// truncated and the failure reported in the trailer.
+	req := httptest.NewRequest("GET", "/objects/events.ndjson?jq=.", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || w.Body.Len() > 64<<10 {
+		t.Fatalf("status = %v, %d bytes", w.Code, w.Body.Len())
+	}
+	if w.Header().Get("Trailer") != headerFilterError {
+		t.Errorf("Trailer = %q", w.Header().Get("Trailer"))
+	}
+	if trailer := w.Result().Trailer.Get(headerFilterError); trailer == "" {
+		t.Error("expected the filter error trailer")
+	}
+}
@@ -23,11 +23,17 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/api/openapi"
 	"github.com/jeremyhahn/go-objstore/pkg/adapters"
 	"github.com/jeremyhahn/go-objstore/pkg/audit"
 	"github.com/jeremyhahn/go-objstore/pkg/common"
 	"github.com/jeremyhahn/go-objstore/pkg/factory"
+	"github.com/jeremyhahn/go-objstore/pkg/filter"
 	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+	"github.com/jeremyhahn/go-objstore/pkg/query"
+	"github.com/jeremyhahn/go-objstore/pkg/retention"
+	"github.com/jeremyhahn/go-objstore/pkg/secrets"
+	servererrors "github.com/jeremyhahn/go-objstore/pkg/server/errors"
 	"github.com/jeremyhahn/go-objstore/pkg/validation"
 	"github.com/jeremyhahn/go-objstore/pkg/version"
 )
@@ -43,9 +49,21 @@ const (
 // keyField is the request/response field name for an object key.
 const keyField = "key"
 
+// headerStorageClass carries an object's storage class on uploads and is
+// echoed on downloads.
+const headerStorageClass = "X-Storage-Class"
+
+// headerExpiresAt sets an object's expiry time on uploads, stored as the
+// common.MetaExpiresAt custom metadata field.
+const headerExpiresAt = "X-Objstore-Expires-At"
+
 // Handler handles REST API requests using the ObjstoreFacade
 type Handler struct {
-	backend string // Backend name (empty = default)
+	backend      string                   // Backend name (empty = default)
+	filterLimits filter.Limits            // Resource limits of GET filters
+	selectLimits query.Limits             // Resource limits of select queries
+	authorizer   adapters.Authorizer      // Authorizes upload session routes (nil = allow)
+	accessKeys   *adapters.AccessKeyStore // Issues SigV4 access keys (nil = disabled)
 }
 
 // NewHandler creates a new Handler instance.
@@ -81,6 +99,17 @@ func (h *Handler) PutObject(c *gin.Context) {
 		key = key[1:]
 	}
 
+	// With atomic=true the upload is staged and verified before it replaces
+	// the object, so readers never see a partial write.
+	var atomic bool
+	if value := c.Query("atomic"); value != "" {
+		var err error
+		if atomic, err = strconv.ParseBool(value); err != nil {
+			RespondWithError(c, http.StatusBadRequest, "invalid atomic parameter")
+			return
+		}
+	}
+
 	var reader io.Reader
 	var metadata *common.Metadata
 
@@ -119,6 +148,7 @@ func (h *Handler) PutObject(c *gin.Context) {
 		metadata = &common.Metadata{
 			ContentType:     c.GetHeader("Content-Type"),
 			ContentEncoding: c.GetHeader("Content-Encoding"),
+			StorageClass:    c.GetHeader(headerStorageClass),
 		}
 
 		// Custom metadata is carried as a JSON object (string->string map) in
@@ -140,8 +170,44 @@ func (h *Handler) PutObject(c *gin.Context) {
 		}
 	}
 
+	if expiresAt := c.GetHeader(headerExpiresAt); expiresAt != "" {
+		if _, err := common.ParseExpiresAt(expiresAt); err != nil {
+			RespondWithError(c, http.StatusBadRequest, "invalid "+headerExpiresAt+": must be an RFC 3339 time")
+			return
+		}
+		if metadata.Custom == nil {
+			metadata.Custom = make(map[string]string)
+		}
+		metadata.Custom[common.MetaExpiresAt] = expiresAt
+	}
+
+	// With SSE-C headers the object is encrypted with the client's key
+	// before it reaches the backend. The key itself is never stored.
+	customerKey, err := common.CustomerKeyFromHeaders(c.Request.Header)
+	if err != nil {
+		RespondWithBackendError(c, err)
+		return
+	}
+	if customerKey != nil {
+		if reader, err = customerKey.Seal(reader, metadata); err != nil {
+			RespondWithBackendError(c, err)
+			return
+		}
+		customerKey.SetHeaders(c.Writer.Header(), false)
+	}
+
 	// Store the object using facade
-	err := objstore.PutWithMetadata(c.Request.Context(), h.keyRef(key), reader, metadata)
+	var info *common.ObjectInfo
+	if atomic {
+		if err = objstore.PutAtomic(c.Request.Context(), h.keyRef(key), reader, metadata); err == nil {
+			info = &common.ObjectInfo{Key: key, Metadata: &common.Metadata{Size: metadata.Size}}
+			if stored, metaErr := objstore.GetMetadata(c.Request.Context(), h.keyRef(key)); metaErr == nil && stored != nil {
+				info.Metadata = stored
+			}
+		}
+	} else {
+		info, err = objstore.PutWithInfo(c.Request.Context(), h.keyRef(key), reader, metadata)
+	}
 
 	// Audit logging
 	auditLogger := audit.GetAuditLogger(c.Request.Context())
@@ -156,20 +222,25 @@ func (h *Handler) PutObject(c *gin.Context) {
 		return
 	}
 
-	bytesTransferred := metadata.Size
+	written := info.Metadata
 	_ = auditLogger.LogObjectMutation(c.Request.Context(), audit.EventObjectCreated,
-		userID, principal, h.backend, key, c.ClientIP(), requestID, bytesTransferred,
+		userID, principal, h.backend, key, c.ClientIP(), requestID, written.Size,
 		audit.ResultSuccess, nil)
 
-	// Get the stored metadata to retrieve the ETag
-	var etag string
-	storedMetadata, metaErr := objstore.GetMetadata(c.Request.Context(), h.keyRef(key))
-	if metaErr == nil && storedMetadata != nil && storedMetadata.ETag != "" {
-		etag = storedMetadata.ETag
-		c.Header("ETag", etag)
+	// Report what was written so clients need no follow-up HEAD
+	response := gin.H{keyField: key, "etag": written.ETag, "size": written.Size}
+	if written.ETag != "" {
+		c.Header("ETag", written.ETag)
+	}
+	if written.Version != "" {
+		response["version"] = written.Version
+	}
+	if !written.LastModified.IsZero() {
+		c.Header("Last-Modified", written.LastModified.Format(http.TimeFormat))
+		response["last_modified"] = written.LastModified.UTC().Format(time.RFC3339Nano)
 	}
 
-	RespondWithSuccess(c, http.StatusCreated, "object uploaded successfully", gin.H{keyField: key, "etag": etag})
+	RespondWithSuccess(c, http.StatusCreated, "object uploaded successfully", response)
 }
 
 // GetObject handles object download
@@ -185,6 +256,41 @@ func (h *Handler) GetObject(c *gin.Context) {
 		key = key[1:]
 	}
 
+	opts, err := filterOptions(c)
+	if err != nil {
+		RespondWithBackendError(c, err)
+		return
+	}
+	customerKey, err := common.CustomerKeyFromHeaders(c.Request.Header)
+	if err != nil {
+		RespondWithBackendError(c, err)
+		return
+	}
+	if customerKey != nil && (c.Query("preset") != "" || !opts.IsZero()) {
+		RespondWithError(c, http.StatusBadRequest, "preset, decompress, range-lines and jq cannot be used with a customer-provided key")
+		return
+	}
+	if asOf := c.Query("as_of"); asOf != "" {
+		if c.Query("preset") != "" || !opts.IsZero() {
+			RespondWithError(c, http.StatusBadRequest, "as_of cannot be combined with preset, decompress, range-lines or jq")
+			return
+		}
+		h.getObjectAsOf(c, key, asOf, customerKey)
+		return
+	}
+	if preset := c.Query("preset"); preset != "" {
+		if !opts.IsZero() {
+			RespondWithError(c, http.StatusBadRequest, "preset cannot be combined with decompress, range-lines or jq")
+			return
+		}
+		h.getDerivedObject(c, key, preset)
+		return
+	}
+	if !opts.IsZero() {
+		h.getFilteredObject(c, key, opts)
+		return
+	}
+
 	// Get metadata first to set headers
 	metadata, err := objstore.GetMetadata(c.Request.Context(), h.keyRef(key))
 	if err != nil {
@@ -192,6 +298,11 @@ func (h *Handler) GetObject(c *gin.Context) {
 		return
 	}
 
+	if err := common.CheckCustomerKey(metadata, customerKey); err != nil {
+		RespondWithBackendError(c, err)
+		return
+	}
+
 	// Get the object using facade
 	reader, err := objstore.GetWithContext(c.Request.Context(), h.keyRef(key))
 	if err != nil {
@@ -200,6 +311,22 @@ func (h *Handler) GetObject(c *gin.Context) {
 	}
 	defer func() { _ = reader.Close() }()
 
+	var body io.Reader = reader
+	if customerKey != nil {
+		if body, err = customerKey.Open(reader, metadata); err != nil {
+			RespondWithBackendError(c, err)
+			return
+		}
+		metadata = common.CustomerKeyMetadata(metadata)
+		customerKey.SetHeaders(c.Writer.Header(), false)
+	}
+
+	writeObject(c, metadata, body)
+}
+
+// writeObject sets the object headers from metadata and streams body as
+// the response.
+func writeObject(c *gin.Context, metadata *common.Metadata, body io.Reader) {
 	// Set response headers
 	if metadata.ContentType != "" {
 		c.Header("Content-Type", metadata.ContentType)
@@ -215,6 +342,10 @@ func (h *Handler) GetObject(c *gin.Context) {
 		c.Header("ETag", metadata.ETag)
 	}
 
+	if metadata.StorageClass != "" {
+		c.Header(headerStorageClass, metadata.StorageClass)
+	}
+
 	if !metadata.LastModified.IsZero() {
 		c.Header("Last-Modified", metadata.LastModified.Format(http.TimeFormat))
 	}
@@ -232,8 +363,7 @@ func (h *Handler) GetObject(c *gin.Context) {
 
 	// Stream the response
 	c.Status(http.StatusOK)
-	_, err = io.Copy(c.Writer, reader)
-	if err != nil {
+	if _, err := io.Copy(c.Writer, body); err != nil {
 		_ = c.Error(err)
 	}
 }
@@ -271,6 +401,14 @@ func (h *Handler) DeleteObject(c *gin.Context) {
 	principal, userID := extractPrincipal(c)
 	requestID := audit.GetRequestID(c.Request.Context())
 
+	// Deletes under a protected prefix are queued for approval; the retention
+	// manager audits the request.
+	var pending *retention.PendingError
+	if errors.As(err, &pending) {
+		c.JSON(http.StatusAccepted, deletionRequestResponse(&pending.Request))
+		return
+	}
+
 	if err != nil {
 		_ = auditLogger.LogObjectMutation(c.Request.Context(), audit.EventObjectDeleted,
 			userID, principal, h.backend, key, c.ClientIP(), requestID, 0,
@@ -315,12 +453,28 @@ func (h *Handler) HeadObject(c *gin.Context) {
 	// Get metadata to set headers
 	metadata, err := objstore.GetMetadata(c.Request.Context(), h.keyRef(key))
 	if err == nil {
+		customerKey, keyErr := common.CustomerKeyFromHeaders(c.Request.Header)
+		if keyErr == nil {
+			keyErr = common.CheckCustomerKey(metadata, customerKey)
+		}
+		if keyErr != nil {
+			code, _ := servererrors.HTTPStatus(keyErr)
+			c.Status(code)
+			return
+		}
+		if customerKey != nil {
+			metadata = common.CustomerKeyMetadata(metadata)
+			customerKey.SetHeaders(c.Writer.Header(), false)
+		}
 		if metadata.ContentType != "" {
 			c.Header("Content-Type", metadata.ContentType)
 		}
 		if metadata.ETag != "" {
 			c.Header("ETag", metadata.ETag)
 		}
+		if metadata.StorageClass != "" {
+			c.Header(headerStorageClass, metadata.StorageClass)
+		}
 		if !metadata.LastModified.IsZero() {
 			c.Header("Last-Modified", metadata.LastModified.Format(http.TimeFormat))
 		}
@@ -360,11 +514,24 @@ func (h *Handler) ListObjects(c *gin.Context) {
 		limit = MaxListLimit
 	}
 
+	sortBy, err := common.ParseListSort(c.Query("sort"))
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "invalid sort parameter")
+		return
+	}
+	order := c.Query("order")
+	if order != "" && order != "asc" && order != "desc" {
+		RespondWithError(c, http.StatusBadRequest, "invalid order parameter")
+		return
+	}
+
 	opts := &common.ListOptions{
-		Prefix:       prefix,
-		MaxResults:   limit,
-		ContinueFrom: token,
-		Delimiter:    delimiter,
+		Prefix:         prefix,
+		MaxResults:     limit,
+		ContinueFrom:   token,
+		Delimiter:      delimiter,
+		SortBy:         sortBy,
+		SortDescending: order == "desc",
 	}
 
 	// List using facade
@@ -377,6 +544,59 @@ func (h *Handler) ListObjects(c *gin.Context) {
 	RespondWithListObjects(c, result)
 }
 
+// Browse returns the folders and objects directly under a prefix. With
+// totals=true each folder carries the number and size of the objects under
+// it.
+func (h *Handler) Browse(c *gin.Context) {
+	opts := &common.BrowseOptions{Delimiter: c.Query("delimiter")}
+	if totals := c.Query("totals"); totals != "" {
+		var err error
+		if opts.Totals, err = strconv.ParseBool(totals); err != nil {
+			RespondWithError(c, http.StatusBadRequest, "invalid totals parameter")
+			return
+		}
+	}
+
+	result, err := objstore.Browse(c.Request.Context(), h.backend, c.Query("prefix"), opts)
+	if err != nil {
+		RespondWithBackendError(c, err)
+		return
+	}
+
+	RespondWithBrowse(c, result)
+}
+
+// SearchObjects finds objects whose key or metadata match a query
+func (h *Handler) SearchObjects(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		RespondWithError(c, http.StatusBadRequest, "q parameter is required")
+		return
+	}
+
+	limit := DefaultListLimit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		n, err := strconv.Atoi(limitStr)
+		if err != nil || n < 0 {
+			RespondWithError(c, http.StatusBadRequest, "invalid limit parameter")
+			return
+		}
+		limit = min(n, MaxListLimit)
+	}
+
+	docs, err := objstore.Search(c.Request.Context(), h.backend, query, limit)
+	if errors.Is(err, objstore.ErrSearchNotEnabled) {
+		RespondWithError(c, http.StatusNotImplemented, "search is not enabled on this server")
+		return
+	}
+	if err != nil {
+		RespondWithBackendError(c, err)
+		return
+	}
+
+	RespondWithSearchResults(c, query, docs)
+}
+
 // GetObjectMetadata retrieves object metadata
 func (h *Handler) GetObjectMetadata(c *gin.Context) {
 	key := c.Param(keyField)
@@ -401,7 +621,16 @@ func (h *Handler) GetObjectMetadata(c *gin.Context) {
 		return
 	}
 
-	RespondWithObject(c, key, metadata)
+	customerKey, err := common.CustomerKeyFromHeaders(c.Request.Header)
+	if err == nil {
+		err = common.CheckCustomerKey(metadata, customerKey)
+	}
+	if err != nil {
+		RespondWithBackendError(c, err)
+		return
+	}
+
+	RespondWithObject(c, key, common.CustomerKeyMetadata(metadata))
 }
 
 // UpdateObjectMetadata updates object metadata
@@ -436,6 +665,12 @@ func (h *Handler) UpdateObjectMetadata(c *gin.Context) {
 		return
 	}
 
+	// Objects encrypted with a customer-provided key keep their wrapped
+	// data key, or they could not be read again.
+	if stored, err := objstore.GetMetadata(c.Request.Context(), h.keyRef(key)); err == nil {
+		common.KeepCustomerKeyFields(stored, &metadata)
+	}
+
 	// Update metadata using facade
 	err = objstore.UpdateMetadata(c.Request.Context(), h.keyRef(key), &metadata)
 	if err != nil {
@@ -446,6 +681,24 @@ func (h *Handler) UpdateObjectMetadata(c *gin.Context) {
 	RespondWithSuccess(c, http.StatusOK, "metadata updated successfully", gin.H{keyField: key})
 }
 
+// UpdateMetadataBatch applies a metadata patch to every object under the
+// prefix query parameter
+func (h *Handler) UpdateMetadataBatch(c *gin.Context) {
+	var patch common.MetadataPatch
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "invalid metadata patch JSON: "+err.Error())
+		return
+	}
+
+	updated, err := objstore.UpdateMetadataBatch(c.Request.Context(), h.backend, c.Query("prefix"), &patch)
+	if err != nil {
+		RespondWithBackendError(c, err)
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, "metadata updated successfully", gin.H{"updated": updated})
+}
+
 // HealthCheck handles health check requests
 func (h *Handler) HealthCheck(c *gin.Context) {
 	c.JSON(http.StatusOK, HealthResponse{
@@ -454,6 +707,21 @@ func (h *Handler) HealthCheck(c *gin.Context) {
 	})
 }
 
+// OpenAPIJSON serves the OpenAPI specification of this API as JSON
+func (h *Handler) OpenAPIJSON(c *gin.Context) {
+	data, err := openapi.JSON()
+	if err != nil {
+		RespondWithError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.Data(http.StatusOK, "application/json", data)
+}
+
+// OpenAPIYAML serves the OpenAPI specification of this API as YAML
+func (h *Handler) OpenAPIYAML(c *gin.Context) {
+	c.Data(http.StatusOK, "application/yaml", openapi.YAML())
+}
+
 // Archive handles archiving an object to another backend
 func (h *Handler) Archive(c *gin.Context) {
 	var req ArchiveRequest
@@ -485,6 +753,15 @@ func (h *Handler) Archive(c *gin.Context) {
 		return
 	}
 
+	if req.Async {
+		enqueueTask(c, objstore.TaskArchive, objstore.ArchiveTask{
+			KeyRef:              h.keyRef(req.Key),
+			DestinationType:     req.DestinationType,
+			DestinationSettings: req.DestinationSettings,
+		})
+		return
+	}
+
 	// Perform archive operation using facade
 	err = objstore.Archive(h.keyRef(req.Key), archiver)
 
@@ -536,10 +813,16 @@ func (h *Handler) AddPolicy(c *gin.Context) {
 
 	// Build lifecycle policy
 	policy := common.LifecyclePolicy{
-		ID:        req.ID,
-		Prefix:    req.Prefix,
-		Retention: time.Duration(req.RetentionSeconds) * time.Second,
-		Action:    req.Action,
+		ID:           req.ID,
+		Prefix:       req.Prefix,
+		Retention:    time.Duration(req.RetentionSeconds) * time.Second,
+		Action:       req.Action,
+		StorageClass: req.StorageClass,
+	}
+
+	if req.Action == common.ActionTransition && req.StorageClass == "" {
+		RespondWithError(c, http.StatusBadRequest, "storage_class required for transition action")
+		return
 	}
 
 	// Create archiver if action is "archive"
@@ -660,17 +943,23 @@ func (h *Handler) ApplyPolicies(c *gin.Context) {
 		return
 	}
 
+	expired, err := objstore.DeleteExpired(ctx, h.backend)
+	if err != nil {
+		RespondWithBackendError(c, err)
+		return
+	}
+
 	if len(policies) == 0 {
 		c.JSON(http.StatusOK, gin.H{
 			"message":           "No lifecycle policies to apply",
 			"policies_count":    0,
-			"objects_processed": 0,
+			"objects_processed": expired,
 		})
 		return
 	}
 
 	// Apply policies by listing objects and checking retention
-	objectsProcessed := 0
+	objectsProcessed := expired
 	opts := &common.ListOptions{
 		Prefix: "",
 	}
@@ -712,6 +1001,16 @@ func (h *Handler) ApplyPolicies(c *gin.Context) {
 					}
 					objectsProcessed++
 				}
+			case common.ActionTransition:
+				metadata, err := objstore.GetMetadata(ctx, h.keyRef(obj.Key))
+				if err != nil || metadata.StorageClass == policy.StorageClass {
+					continue
+				}
+				metadata.StorageClass = policy.StorageClass
+				if err := objstore.UpdateMetadata(ctx, h.keyRef(obj.Key), metadata); err != nil {
+					continue
+				}
+				objectsProcessed++
 			}
 		}
 	}
@@ -744,6 +1043,9 @@ func extractPrincipal(c *gin.Context) (principal string, userID string) {
 
 // createArchiver creates an archiver from factory based on destination type
 func createArchiver(destinationType string, settings map[string]string) (common.Archiver, error) {
+	if err := secrets.RejectReferences(settings); err != nil {
+		return nil, err
+	}
 	return factory.NewArchiver(destinationType, settings)
 }
 
@@ -18,6 +18,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
@@ -27,6 +28,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/jeremyhahn/go-objstore/pkg/adapters"
 	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
 )
 
 // Test error variables
@@ -142,6 +144,79 @@ func TestPutObjectInvalidCustomMetadata(t *testing.T) {
 	}
 }
 
+// TestPutObjectExpiresAtHeader verifies that X-Objstore-Expires-At is
+// stored as the expiry metadata field and that malformed times are
+// rejected with 400.
+func TestPutObjectExpiresAtHeader(t *testing.T) {
+	storage := NewMockStorage()
+	handler := newTestHandler(t, storage)
+
+	router := gin.New()
+	router.PUT("/objects/*key", handler.PutObject)
+
+	req := httptest.NewRequest("PUT", "/objects/tmp.txt", strings.NewReader("content"))
+	req.Header.Set(headerExpiresAt, "2030-01-02T03:04:05Z")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	metadata, err := storage.GetMetadata(context.Background(), "tmp.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := metadata.Custom[common.MetaExpiresAt]; got != "2030-01-02T03:04:05Z" {
+		t.Errorf("Expected the expiry to be stored, got %q", got)
+	}
+
+	req = httptest.NewRequest("PUT", "/objects/tmp.txt", strings.NewReader("content"))
+	req.Header.Set(headerExpiresAt, "next week")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a malformed expiry, got %d", w.Code)
+	}
+}
+
+func TestPutObjectAtomic(t *testing.T) {
+	storage := memory.New()
+	handler := newTestHandler(t, storage)
+
+	router := gin.New()
+	router.PUT("/objects/*key", handler.PutObject)
+
+	req := httptest.NewRequest("PUT", "/objects/app.tar.gz?atomic=true", strings.NewReader("release"))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest("PUT", "/objects/app.tar.gz?atomic=true", strings.NewReader("corrupt"))
+	req.Header.Set("X-Object-Metadata", `{"checksum-sha256":"`+strings.Repeat("0", 64)+`"}`)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusPreconditionFailed {
+		t.Errorf("Expected status 412 for a checksum mismatch, got %d: %s", w.Code, w.Body.String())
+	}
+	rc, err := storage.Get("app.tar.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(rc)
+	_ = rc.Close()
+	if string(data) != "release" {
+		t.Errorf("Expected the failed atomic upload to leave the object, got %q", data)
+	}
+
+	req = httptest.NewRequest("PUT", "/objects/app.tar.gz?atomic=maybe", strings.NewReader("x"))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a malformed atomic parameter, got %d", w.Code)
+	}
+}
+
 // TestPutObjectMetadataTooManyEntries verifies that a custom metadata map
 // exceeding the maximum number of entries is rejected with 400 by the
 // handler's up-front validation rather than surfacing as a 500. The
@@ -75,6 +75,7 @@ func (h *Handler) AddReplicationPolicy(c *gin.Context) {
 		Enabled:             req.Enabled,
 		ReplicationMode:     req.ReplicationMode,
 		Encryption:          req.Encryption,
+		WriteOnce:           req.WriteOnce,
 	}
 
 	// Set default replication mode if not specified
@@ -240,6 +241,7 @@ type TriggerReplicationRequest struct {
 	PolicyID    string `json:"policy_id,omitempty"`
 	Parallel    bool   `json:"parallel,omitempty"`
 	WorkerCount int    `json:"worker_count,omitempty"`
+	Async       bool   `json:"async,omitempty"`
 }
 
 // TriggerReplication handles manually triggering replication
@@ -254,6 +256,17 @@ func (h *Handler) TriggerReplication(c *gin.Context) {
 		policyID = c.Query("policy_id")
 	}
 
+	// Long syncs, such as migrations, can run as a task instead.
+	if req.Async {
+		enqueueTask(c, objstore.TaskReplicationSync, objstore.ReplicationSyncTask{
+			Backend:     h.backend,
+			PolicyID:    policyID,
+			Parallel:    req.Parallel,
+			WorkerCount: req.WorkerCount,
+		})
+		return
+	}
+
 	// Get replication manager from facade
 	repMgr, err := objstore.GetReplicationManager(h.backend)
 	if err != nil {
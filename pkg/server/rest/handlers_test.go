@@ -26,7 +26,10 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
 	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+	"github.com/jeremyhahn/go-objstore/pkg/objstore"
 )
 
 // MockStorage implements common.Storage for testing
@@ -228,6 +231,20 @@ func TestPutObject(t *testing.T) {
 			if w.Code != tt.wantStatusCode {
 				t.Errorf("PutObject() status = %v, want %v, body: %s", w.Code, tt.wantStatusCode, w.Body.String())
 			}
+
+			// The response describes the object written
+			var resp struct {
+				Data map[string]any `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp.Data["etag"] != "mock-etag" || resp.Data["size"] != float64(len(tt.body)) || resp.Data["last_modified"] == nil {
+				t.Errorf("PutObject() data = %v", resp.Data)
+			}
+			if w.Header().Get("ETag") != "mock-etag" {
+				t.Errorf("PutObject() ETag header = %q", w.Header().Get("ETag"))
+			}
 		})
 	}
 }
@@ -387,6 +404,91 @@ func TestGetObject(t *testing.T) {
 	}
 }
 
+func TestCustomerProvidedKey(t *testing.T) {
+	storage := memory.New()
+	handler := newTestHandler(t, storage)
+	router := gin.New()
+	router.PUT("/objects/*key", handler.PutObject)
+	router.GET("/objects/*key", handler.GetObject)
+	router.HEAD("/objects/*key", handler.HeadObject)
+	router.GET("/metadata/*key", handler.GetObjectMetadata)
+	router.PUT("/metadata/*key", handler.UpdateObjectMetadata)
+
+	key, _ := common.NewCustomerKey(bytes.Repeat([]byte{1}, 32))
+	other, _ := common.NewCustomerKey(bytes.Repeat([]byte{2}, 32))
+	do := func(method, path string, body io.Reader, k *common.CustomerKey) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, body)
+		if k != nil {
+			k.SetHeaders(req.Header, true)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	req := httptest.NewRequest(http.MethodPut, "/objects/secret.txt", strings.NewReader("top secret"))
+	req.Header.Set("X-Object-Metadata", `{"owner":"alice"}`)
+	key.SetHeaders(req.Header, true)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("PutObject() status = %d: %s", w.Code, w.Body.String())
+	}
+	if w.Header().Get(common.HeaderSSECustomerKeyMD5) != key.KeyMD5() {
+		t.Errorf("PutObject() key MD5 header = %q", w.Header().Get(common.HeaderSSECustomerKeyMD5))
+	}
+
+	rc, err := storage.Get("secret.txt")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	stored, _ := io.ReadAll(rc)
+	rc.Close()
+	if bytes.Contains(stored, []byte("top secret")) {
+		t.Fatal("backend stores plaintext")
+	}
+
+	w = do(http.MethodGet, "/objects/secret.txt", nil, key)
+	if w.Code != http.StatusOK || w.Body.String() != "top secret" {
+		t.Fatalf("GetObject() = %d %q", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("Content-Length"); got != "10" {
+		t.Errorf("GetObject() Content-Length = %s, want 10", got)
+	}
+	if got := w.Header().Get("X-Object-Metadata"); got != `{"owner":"alice"}` {
+		t.Errorf("GetObject() X-Object-Metadata = %s", got)
+	}
+
+	for name, tt := range map[string]struct {
+		key  *common.CustomerKey
+		path string
+		want int
+	}{
+		"get without key":    {nil, "/objects/secret.txt", http.StatusBadRequest},
+		"get with other key": {other, "/objects/secret.txt", http.StatusForbidden},
+		"get plain with key": {key, "/objects/plain.txt", http.StatusBadRequest},
+		"metadata no key":    {nil, "/metadata/secret.txt", http.StatusBadRequest},
+		"metadata with key":  {key, "/metadata/secret.txt", http.StatusOK},
+	} {
+		_ = storage.Put("plain.txt", strings.NewReader("plain"))
+		if w := do(http.MethodGet, tt.path, nil, tt.key); w.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d", name, w.Code, tt.want)
+		}
+	}
+
+	if w := do(http.MethodHead, "/objects/secret.txt", nil, other); w.Code != http.StatusForbidden {
+		t.Errorf("HeadObject() with other key status = %d, want 403", w.Code)
+	}
+
+	w = do(http.MethodPut, "/metadata/secret.txt", strings.NewReader(`{"custom":{"owner":"bob"}}`), nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("UpdateObjectMetadata() status = %d", w.Code)
+	}
+	if w := do(http.MethodGet, "/objects/secret.txt", nil, key); w.Body.String() != "top secret" {
+		t.Errorf("GetObject() after metadata update = %d %q", w.Code, w.Body.String())
+	}
+}
+
 func TestDeleteObject(t *testing.T) {
 	storage := NewMockStorage()
 	handler := newTestHandler(t, storage)
@@ -551,6 +653,90 @@ func TestListObjectsInvalidLimit(t *testing.T) {
 	}
 }
 
+func TestListObjectsSorted(t *testing.T) {
+	storage := NewMockStorage()
+	handler := newTestHandler(t, storage)
+
+	for key, data := range map[string]string{"a.txt": "aa", "b.txt": "bbbb", "c.txt": "c"} {
+		storage.PutWithMetadata(context.Background(), key, strings.NewReader(data), &common.Metadata{
+			ContentType: "text/plain",
+			Size:        int64(len(data)),
+		})
+	}
+
+	router := gin.New()
+	router.GET("/objects", handler.ListObjects)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/objects?sort=size&order=desc", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("ListObjects() status = %v, body %s", w.Code, w.Body.String())
+	}
+	var response ListObjectsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for _, obj := range response.Objects {
+		keys = append(keys, obj.Key)
+		if obj.ContentType != "text/plain" {
+			t.Errorf("%s: content type = %q, want text/plain", obj.Key, obj.ContentType)
+		}
+	}
+	if strings.Join(keys, ",") != "b.txt,a.txt,c.txt" {
+		t.Errorf("ListObjects() sorted by size = %v", keys)
+	}
+
+	for _, query := range []string{"?sort=owner", "?sort=size&order=down"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/objects"+query, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("ListObjects(%s) status = %v, want %v", query, w.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestBrowse(t *testing.T) {
+	storage := NewMockStorage()
+	handler := newTestHandler(t, storage)
+
+	for key, data := range map[string]string{"docs/a.txt": "aa", "docs/2025/b.txt": "bbbb", "docs/2025/c.txt": "c"} {
+		storage.PutWithMetadata(context.Background(), key, strings.NewReader(data), &common.Metadata{
+			ContentType: "text/plain",
+			Size:        int64(len(data)),
+		})
+	}
+
+	router := gin.New()
+	router.GET("/browse", handler.Browse)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/browse?prefix=docs/&totals=true", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Browse() status = %v, body %s", w.Code, w.Body.String())
+	}
+	var response BrowseResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	want := common.Folder{Prefix: "docs/2025/", Objects: 2, Bytes: 5}
+	if len(response.Folders) != 1 || response.Folders[0] != want {
+		t.Errorf("Browse() folders = %+v, want %+v", response.Folders, want)
+	}
+	if len(response.Objects) != 1 || response.Objects[0].Key != "docs/a.txt" || response.Objects[0].Size != 2 {
+		t.Errorf("Browse() objects = %+v, want docs/a.txt", response.Objects)
+	}
+	if response.TotalObjects != 3 || response.TotalBytes != 7 {
+		t.Errorf("Browse() totals = %d objects, %d bytes, want 3 and 7", response.TotalObjects, response.TotalBytes)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/browse?totals=maybe", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Browse(totals=maybe) status = %v, want %v", w.Code, http.StatusBadRequest)
+	}
+}
+
 func TestGetObjectMetadata(t *testing.T) {
 	storage := NewMockStorage()
 	handler := newTestHandler(t, storage)
@@ -598,6 +784,69 @@ func TestGetObjectMetadata(t *testing.T) {
 	}
 }
 
+func TestUpdateMetadataBatch(t *testing.T) {
+	storage := memory.New()
+	initTestFacade(t, storage)
+	ctx := context.Background()
+	for _, key := range []string{"logs/a.log", "logs/b.log", "data/c.csv"} {
+		if err := storage.PutWithMetadata(ctx, key, strings.NewReader("x"), &common.Metadata{Custom: map[string]string{"draft": "true"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config := DefaultServerConfig()
+	config.Mode = gin.TestMode
+	config.Authenticator = adapters.NewBearerTokenAuthenticator(func(_ context.Context, token string) (*adapters.Principal, error) {
+		return &adapters.Principal{ID: token, Name: token, Roles: []string{token}}, nil
+	})
+	config.Authorizer = adapters.NewRBACAuthorizer(map[string][]string{
+		"writer": {adapters.ActionRead, adapters.ActionList, adapters.ActionWrite},
+		"reader": {adapters.ActionRead, adapters.ActionList},
+	})
+	server, err := NewServer(storage, config)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	patch := func(bearer, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPatch, "/api/v1/objects?prefix=logs/", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+bearer)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	if w := patch("reader", `{"set":{"tier":"cold"}}`); w.Code != http.StatusForbidden {
+		t.Errorf("reader status = %d, want %d", w.Code, http.StatusForbidden)
+	}
+	if w := patch("writer", `{}`); w.Code != http.StatusBadRequest {
+		t.Errorf("empty patch status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	w := patch("writer", `{"storage_class":"COLD","set":{"tier":"cold"},"remove":["draft"]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Data struct {
+			Updated int `json:"updated"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || resp.Data.Updated != 2 {
+		t.Errorf("response = %s, want 2 updated", w.Body.String())
+	}
+	for key, want := range map[string]string{"logs/a.log": "cold", "logs/b.log": "cold", "data/c.csv": ""} {
+		metadata, err := storage.GetMetadata(ctx, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if metadata.Custom["tier"] != want || (want != "") == (metadata.Custom["draft"] != "") {
+			t.Errorf("%s: custom = %v", key, metadata.Custom)
+		}
+	}
+}
+
 func TestUpdateObjectMetadata(t *testing.T) {
 	storage := NewMockStorage()
 	handler := newTestHandler(t, storage)
@@ -801,3 +1050,104 @@ func TestObjectMetadataRoundTrip(t *testing.T) {
 		t.Errorf("HEAD X-Object-Metadata = %v, want author=alice env=prod", headCustom)
 	}
 }
+
+func TestObjectStorageClassRoundTrip(t *testing.T) {
+	storage := NewMockStorage()
+	handler := newTestHandler(t, storage)
+
+	router := gin.New()
+	router.PUT("/objects/*key", handler.PutObject)
+	router.GET("/objects/*key", handler.GetObject)
+	router.HEAD("/objects/*key", handler.HeadObject)
+
+	putReq := httptest.NewRequest("PUT", "/objects/cold.txt", strings.NewReader("rarely read"))
+	putReq.Header.Set("X-Storage-Class", "STANDARD_IA")
+	putW := httptest.NewRecorder()
+	router.ServeHTTP(putW, putReq)
+	if putW.Code != http.StatusCreated {
+		t.Fatalf("PUT status = %d, want %d, body: %s", putW.Code, http.StatusCreated, putW.Body.String())
+	}
+
+	stored, err := storage.GetMetadata(context.Background(), "cold.txt")
+	if err != nil {
+		t.Fatalf("GetMetadata after PUT: %v", err)
+	}
+	if stored.StorageClass != "STANDARD_IA" {
+		t.Errorf("stored StorageClass = %q, want STANDARD_IA", stored.StorageClass)
+	}
+
+	for _, method := range []string{"GET", "HEAD"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, "/objects/cold.txt", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s status = %d, want 200", method, w.Code)
+		}
+		if got := w.Header().Get("X-Storage-Class"); got != "STANDARD_IA" {
+			t.Errorf("%s X-Storage-Class = %q, want STANDARD_IA", method, got)
+		}
+	}
+}
+
+func TestSearchObjects(t *testing.T) {
+	storage := NewMockStorage()
+	handler := newTestHandler(t, storage)
+
+	router := gin.New()
+	router.GET("/search", handler.SearchObjects)
+
+	// Search is not enabled yet
+	req := httptest.NewRequest("GET", "/search?q=report", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotImplemented {
+		t.Errorf("SearchObjects() status = %v, want %v", w.Code, http.StatusNotImplemented)
+	}
+
+	if err := objstore.EnableSearch("", nil); err != nil {
+		t.Fatalf("EnableSearch() error = %v", err)
+	}
+	err := objstore.PutWithMetadata(context.Background(), "docs/report.txt", strings.NewReader("data"), &common.Metadata{
+		ContentType: "text/plain",
+		Custom:      map[string]string{"author": "alice"},
+	})
+	if err != nil {
+		t.Fatalf("PutWithMetadata() error = %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		url            string
+		wantStatusCode int
+		wantCount      int
+	}{
+		{"match", "/search?q=author:alice", http.StatusOK, 1},
+		{"no match", "/search?q=author:bob", http.StatusOK, 0},
+		{"missing query", "/search", http.StatusBadRequest, 0},
+		{"invalid limit", "/search?q=report&limit=x", http.StatusBadRequest, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.url, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("SearchObjects() status = %v, want %v, body: %s", w.Code, tt.wantStatusCode, w.Body.String())
+			}
+			if w.Code != http.StatusOK {
+				return
+			}
+			var response SearchResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if response.Count != tt.wantCount || len(response.Objects) != tt.wantCount {
+				t.Errorf("SearchObjects() count = %d, want %d", response.Count, tt.wantCount)
+			}
+			if tt.wantCount == 1 && response.Objects[0].Metadata["author"] != "alice" {
+				t.Errorf("SearchObjects() metadata = %v", response.Objects[0].Metadata)
+			}
+		})
+	}
+}
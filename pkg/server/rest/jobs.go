@@ -0,0 +1,75 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/jobs"
+	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+)
+
+const jobsPath = "/api/v1/jobs"
+
+// JobsResponse is the status of the server's background jobs
+type JobsResponse struct {
+	Jobs []jobs.Status `json:"jobs"`
+} // @name JobList
+
+// ListJobs returns the status of every background job: whether it is
+// running, its last run and when it is next due.
+func (h *Handler) ListJobs(c *gin.Context) {
+	scheduler, ok := jobScheduler(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, JobsResponse{Jobs: scheduler.List()})
+}
+
+// GetJob returns the status of a background job with its run history,
+// newest first.
+func (h *Handler) GetJob(c *gin.Context) {
+	scheduler, ok := jobScheduler(c)
+	if !ok {
+		return
+	}
+	status, err := scheduler.Get(c.Param("name"))
+	if err != nil {
+		RespondWithBackendError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// jobScheduler returns the scheduler, or responds with an error when jobs
+// are not enabled.
+func jobScheduler(c *gin.Context) (*jobs.Scheduler, bool) {
+	scheduler, err := objstore.Jobs()
+	switch {
+	case errors.Is(err, objstore.ErrJobsNotEnabled):
+		RespondWithError(c, http.StatusNotImplemented, "background jobs are not enabled on this server")
+		return nil, false
+	case err != nil:
+		RespondWithBackendError(c, err)
+		return nil, false
+	}
+	return scheduler, true
+}
+
+func isJobsPath(path string) bool {
+	return path == jobsPath || strings.HasPrefix(path, jobsPath+"/")
+}
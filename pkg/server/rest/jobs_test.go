@@ -0,0 +1,100 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/jobs"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+)
+
+func TestJobs(t *testing.T) {
+	handler := newTestHandler(t, memory.New())
+
+	router := gin.New()
+	router.GET("/jobs", handler.ListJobs)
+	router.GET("/jobs/:name", handler.GetJob)
+
+	// Jobs are not enabled yet
+	req := httptest.NewRequest("GET", "/jobs", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotImplemented {
+		t.Errorf("ListJobs() status = %v, want %v", w.Code, http.StatusNotImplemented)
+	}
+
+	// Use a long tick so only RunNow runs the job.
+	scheduler, err := objstore.EnableJobs(&jobs.Config{Tick: time.Hour, IsLeader: func() bool { return false }})
+	if err != nil {
+		t.Fatalf("EnableJobs() error = %v", err)
+	}
+	defer objstore.Reset()
+	if err := scheduler.Register(jobs.Job{Name: "replication", Interval: time.Hour, Run: func(context.Context) (string, error) {
+		return "1 policy synced", nil
+	}}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if _, err := scheduler.RunNow(context.Background(), "replication"); err != nil {
+		t.Fatalf("RunNow() error = %v", err)
+	}
+
+	req = httptest.NewRequest("GET", "/jobs", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	var list JobsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if w.Code != http.StatusOK || len(list.Jobs) != 1 || list.Jobs[0].LastRun == nil || list.Jobs[0].IntervalSeconds != 3600 {
+		t.Errorf("ListJobs() = %d %+v", w.Code, list)
+	}
+
+	req = httptest.NewRequest("GET", "/jobs/replication", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	var status jobs.Status
+	if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if w.Code != http.StatusOK || len(status.History) != 1 || status.History[0].Message != "1 policy synced" {
+		t.Errorf("GetJob() = %d %+v", w.Code, status)
+	}
+
+	req = httptest.NewRequest("GET", "/jobs/missing", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("GetJob(missing) status = %v, want %v", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestDeriveActionResourceJobs(t *testing.T) {
+	for _, path := range []string{"/api/v1/jobs", "/api/v1/jobs/replication"} {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, path, nil)
+		action, resource := deriveActionResource(c)
+		if action != adapters.ActionRead || resource != adapters.ResourceJobs {
+			t.Errorf("deriveActionResource(%s) = %s, %s", path, action, resource)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/locks"
+	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+)
+
+// locksPath is the prefix of the advisory lock API. Lock routes are
+// authorized against the locked key, like object routes.
+const locksPath = "/api/v1/locks"
+
+// headerLockToken carries the token of a held lock when refreshing or
+// releasing it.
+const headerLockToken = "X-Lock-Token"
+
+// Lock request bounds.
+const (
+	defaultLockTTL = 30 * time.Second
+	maxLockWait    = time.Minute
+)
+
+// AcquireLockRequest is the optional body when acquiring or refreshing a lock
+type AcquireLockRequest struct {
+	TTLSeconds  int    `json:"ttl_seconds,omitempty" example:"30"`
+	WaitSeconds int    `json:"wait_seconds,omitempty" example:"10"`
+	Owner       string `json:"owner,omitempty" example:"worker-1"`
+} // @name AcquireLockRequest
+
+// LockResponse is an advisory lock on one key. The token is only returned
+// to the holder.
+type LockResponse struct {
+	Key        string `json:"key" example:"jobs/nightly"`
+	Token      string `json:"token,omitempty" example:"9f86d081884c7d659a2feaa0c55ad015"`
+	Owner      string `json:"owner,omitempty" example:"worker-1"`
+	AcquiredAt string `json:"acquired_at" example:"2025-11-05T10:00:00Z"`
+	ExpiresAt  string `json:"expires_at" example:"2025-11-05T10:00:30Z"`
+} // @name Lock
+
+// LocksResponse lists advisory locks
+type LocksResponse struct {
+	Locks []LockResponse `json:"locks"`
+	Count int            `json:"count" example:"1"`
+} // @name LockList
+
+// ListLocks lists the held locks, optionally filtered by key prefix
+func (h *Handler) ListLocks(c *gin.Context) {
+	manager, err := objstore.Locks(h.backend)
+	if err != nil {
+		respondWithLockError(c, err)
+		return
+	}
+
+	held, err := manager.List(c.Request.Context(), c.Query("prefix"))
+	if err != nil {
+		respondWithLockError(c, err)
+		return
+	}
+	response := LocksResponse{
+		Locks: make([]LockResponse, 0, len(held)),
+		Count: len(held),
+	}
+	for i := range held {
+		response.Locks = append(response.Locks, lockResponse(&held[i]))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetLock returns the lock on a key, without its token
+func (h *Handler) GetLock(c *gin.Context) {
+	key := strings.TrimLeft(c.Param(keyField), "/")
+	if key == "" {
+		RespondWithError(c, http.StatusBadRequest, "key parameter is required")
+		return
+	}
+
+	manager, err := objstore.Locks(h.backend)
+	if err != nil {
+		respondWithLockError(c, err)
+		return
+	}
+
+	lock, err := manager.Get(c.Request.Context(), key)
+	if err != nil {
+		respondWithLockError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, lockResponse(lock))
+}
+
+// AcquireLock acquires the lock on a key, waiting up to wait_seconds for it
+// to be released. With an X-Lock-Token header it refreshes the held lock
+// instead.
+func (h *Handler) AcquireLock(c *gin.Context) {
+	key := strings.TrimLeft(c.Param(keyField), "/")
+	if key == "" {
+		RespondWithError(c, http.StatusBadRequest, "key parameter is required")
+		return
+	}
+
+	var body AcquireLockRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			RespondWithError(c, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+	}
+	ttl := defaultLockTTL
+	if body.TTLSeconds != 0 {
+		ttl = time.Duration(body.TTLSeconds) * time.Second
+	}
+	wait := time.Duration(body.WaitSeconds) * time.Second
+	if wait < 0 || wait > maxLockWait {
+		RespondWithError(c, http.StatusBadRequest, fmt.Sprintf("wait_seconds must be between 0 and %d", int(maxLockWait.Seconds())))
+		return
+	}
+
+	manager, err := objstore.Locks(h.backend)
+	if err != nil {
+		respondWithLockError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	var lock *locks.Lock
+	switch token := c.GetHeader(headerLockToken); {
+	case token != "":
+		lock, err = manager.Refresh(ctx, key, token, ttl)
+	case wait > 0:
+		waitCtx, cancel := context.WithTimeout(ctx, wait)
+		lock, err = manager.Lock(waitCtx, key, lockOwner(ctx, body.Owner), ttl)
+		cancel()
+		if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+			err = fmt.Errorf("%w: %s", locks.ErrLocked, key)
+		}
+	default:
+		lock, err = manager.TryLock(ctx, key, lockOwner(ctx, body.Owner), ttl)
+	}
+	if err != nil {
+		respondWithLockError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, lockResponse(lock))
+}
+
+// ReleaseLock releases the lock on a key held with the X-Lock-Token token
+func (h *Handler) ReleaseLock(c *gin.Context) {
+	key := strings.TrimLeft(c.Param(keyField), "/")
+	if key == "" {
+		RespondWithError(c, http.StatusBadRequest, "key parameter is required")
+		return
+	}
+	token := c.GetHeader(headerLockToken)
+	if token == "" {
+		RespondWithError(c, http.StatusBadRequest, headerLockToken+" header is required")
+		return
+	}
+
+	manager, err := objstore.Locks(h.backend)
+	if err != nil {
+		respondWithLockError(c, err)
+		return
+	}
+
+	if err := manager.Unlock(c.Request.Context(), key, token); err != nil {
+		respondWithLockError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// isLocksPath reports whether path belongs to the advisory lock API.
+func isLocksPath(path string) bool {
+	return path == locksPath || strings.HasPrefix(path, locksPath+"/")
+}
+
+// lockOwner returns the owner recorded with a new lock: the authenticated
+// identity, so holders cannot be impersonated, or else the requested owner.
+func lockOwner(ctx context.Context, requested string) string {
+	if id := adapters.Identity(ctx); id != "" {
+		return id
+	}
+	return requested
+}
+
+// respondWithLockError maps lock errors to responses that name the lock
+// rather than the generic object messages.
+func respondWithLockError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, objstore.ErrLocksNotEnabled):
+		RespondWithError(c, http.StatusNotImplemented, "locks are not enabled on this server")
+	case errors.Is(err, locks.ErrLockNotFound):
+		RespondWithError(c, http.StatusNotFound, "lock not found")
+	case errors.Is(err, locks.ErrLocked):
+		RespondWithError(c, http.StatusConflict, "key is locked")
+	case errors.Is(err, locks.ErrNotHeld):
+		RespondWithError(c, http.StatusPreconditionFailed, "lock is not held with this token")
+	case errors.Is(err, common.ErrReservedKey):
+		RespondWithError(c, http.StatusForbidden, "key is in the reserved lock namespace")
+	case errors.Is(err, locks.ErrInvalidTTL):
+		RespondWithError(c, http.StatusBadRequest, fmt.Sprintf("ttl_seconds must be between %d and %d",
+			int(locks.MinTTL.Seconds()), int(locks.MaxTTL.Seconds())))
+	default:
+		RespondWithBackendError(c, err)
+	}
+}
+
+func lockResponse(lock *locks.Lock) LockResponse {
+	return LockResponse{
+		Key:        lock.Key,
+		Token:      lock.Token,
+		Owner:      lock.Owner,
+		AcquiredAt: lock.AcquiredAt.Format(time.RFC3339),
+		ExpiresAt:  lock.ExpiresAt.Format(time.RFC3339),
+	}
+}
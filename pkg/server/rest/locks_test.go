@@ -0,0 +1,147 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/locks"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+)
+
+// newLocksTestServer builds a server over a memory backend; every bearer
+// token authenticates as the user it names.
+func newLocksTestServer(t *testing.T, enable bool) *gin.Engine {
+	t.Helper()
+	storage := memory.New()
+	initTestFacade(t, storage)
+	if enable {
+		if err := objstore.EnableLocks("", ""); err != nil {
+			t.Fatalf("EnableLocks: %v", err)
+		}
+	}
+
+	config := DefaultServerConfig()
+	config.Mode = gin.TestMode
+	config.Authenticator = adapters.NewBearerTokenAuthenticator(func(_ context.Context, token string) (*adapters.Principal, error) {
+		return &adapters.Principal{ID: token, Name: token, Roles: []string{"admin"}}, nil
+	})
+	server, err := NewServer(storage, config)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	return server.Router()
+}
+
+func doLockRequest(router *gin.Engine, method, path, bearer, lockToken, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+bearer)
+	if lockToken != "" {
+		req.Header.Set(headerLockToken, lockToken)
+	}
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestLockEndpoints(t *testing.T) {
+	router := newLocksTestServer(t, true)
+
+	w := doLockRequest(router, http.MethodPut, "/api/v1/locks/jobs/nightly", "alice", "", `{"ttl_seconds":60,"owner":"spoofed"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("acquire = %d %s", w.Code, w.Body.String())
+	}
+	var lock LockResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &lock); err != nil {
+		t.Fatal(err)
+	}
+	if lock.Token == "" || lock.Owner != "alice" || lock.Key != "jobs/nightly" {
+		t.Errorf("acquired lock = %+v", lock)
+	}
+
+	if w := doLockRequest(router, http.MethodPut, "/api/v1/locks/jobs/nightly", "bob", "", ""); w.Code != http.StatusConflict {
+		t.Errorf("acquire held lock = %d, want %d", w.Code, http.StatusConflict)
+	}
+	if w := doLockRequest(router, http.MethodPut, "/api/v1/locks/jobs/nightly", "bob", "", `{"wait_seconds":1}`); w.Code != http.StatusConflict {
+		t.Errorf("wait for held lock = %d, want %d", w.Code, http.StatusConflict)
+	}
+	if w := doLockRequest(router, http.MethodPut, "/api/v1/locks/jobs/other", "bob", "", `{"ttl_seconds":-1}`); w.Code != http.StatusBadRequest {
+		t.Errorf("invalid ttl = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	if w := doLockRequest(router, http.MethodPut, "/api/v1/locks/jobs/other", "bob", "", `{"wait_seconds":3600}`); w.Code != http.StatusBadRequest {
+		t.Errorf("invalid wait = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	w = doLockRequest(router, http.MethodGet, "/api/v1/locks/jobs/nightly", "bob", "", "")
+	if w.Code != http.StatusOK || strings.Contains(w.Body.String(), lock.Token) {
+		t.Errorf("GET lock = %d %s", w.Code, w.Body.String())
+	}
+	w = doLockRequest(router, http.MethodGet, "/api/v1/locks?prefix=jobs/", "bob", "", "")
+	var list LocksResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil || list.Count != 1 || list.Locks[0].Token != "" {
+		t.Errorf("GET /locks = %d %s", w.Code, w.Body.String())
+	}
+
+	if w := doLockRequest(router, http.MethodPut, "/api/v1/locks/jobs/nightly", "alice", lock.Token, `{"ttl_seconds":120}`); w.Code != http.StatusOK {
+		t.Errorf("refresh = %d %s", w.Code, w.Body.String())
+	}
+	if w := doLockRequest(router, http.MethodPut, "/api/v1/locks/jobs/nightly", "bob", "wrong", ""); w.Code != http.StatusPreconditionFailed {
+		t.Errorf("refresh with wrong token = %d, want %d", w.Code, http.StatusPreconditionFailed)
+	}
+
+	if w := doLockRequest(router, http.MethodPut, "/api/v1/objects/"+locks.DefaultPrefix+"jobs/nightly", "alice", "", "data"); w.Code != http.StatusForbidden {
+		t.Errorf("PUT reserved object = %d, want %d", w.Code, http.StatusForbidden)
+	}
+	if w := doLockRequest(router, http.MethodDelete, "/api/v1/objects/"+locks.DefaultPrefix+"jobs/nightly", "alice", "", ""); w.Code != http.StatusForbidden {
+		t.Errorf("DELETE reserved object = %d, want %d", w.Code, http.StatusForbidden)
+	}
+
+	if w := doLockRequest(router, http.MethodDelete, "/api/v1/locks/jobs/nightly", "bob", "", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("release without token = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	if w := doLockRequest(router, http.MethodDelete, "/api/v1/locks/jobs/nightly", "bob", "wrong", ""); w.Code != http.StatusPreconditionFailed {
+		t.Errorf("release with wrong token = %d, want %d", w.Code, http.StatusPreconditionFailed)
+	}
+	if w := doLockRequest(router, http.MethodDelete, "/api/v1/locks/jobs/nightly", "alice", lock.Token, ""); w.Code != http.StatusNoContent {
+		t.Fatalf("release = %d %s", w.Code, w.Body.String())
+	}
+	if w := doLockRequest(router, http.MethodGet, "/api/v1/locks/jobs/nightly", "bob", "", ""); w.Code != http.StatusNotFound {
+		t.Errorf("GET released lock = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	if w := doLockRequest(router, http.MethodPut, "/api/v1/locks/jobs/nightly", "bob", "", ""); w.Code != http.StatusOK {
+		t.Errorf("acquire released lock = %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestLockEndpointsNotEnabled(t *testing.T) {
+	router := newLocksTestServer(t, false)
+
+	if w := doLockRequest(router, http.MethodGet, "/api/v1/locks", "alice", "", ""); w.Code != http.StatusNotImplemented {
+		t.Errorf("GET /locks = %d, want %d", w.Code, http.StatusNotImplemented)
+	}
+	if w := doLockRequest(router, http.MethodPut, "/api/v1/locks/k", "alice", "", ""); w.Code != http.StatusNotImplemented {
+		t.Errorf("PUT /locks/k = %d, want %d", w.Code, http.StatusNotImplemented)
+	}
+}
@@ -0,0 +1,347 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/manifest"
+	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+	servererrors "github.com/jeremyhahn/go-objstore/pkg/server/errors"
+)
+
+// manifestsPath is the prefix of the dataset manifest API, authorized
+// against adapters.ResourceManifest.
+const manifestsPath = "/api/v1/manifests"
+
+// CreateManifestRequest creates a manifest draft
+type CreateManifestRequest struct {
+	Name string   `json:"name" binding:"required" example:"imagenet-train"`
+	Keys []string `json:"keys,omitempty" example:"datasets/imagenet/train-00000.tar"`
+} // @name CreateManifestRequest
+
+// UpdateManifestMembersRequest adds and removes members of a manifest draft
+type UpdateManifestMembersRequest struct {
+	Add    []string `json:"add,omitempty" example:"datasets/imagenet/train-00001.tar"`
+	Remove []string `json:"remove,omitempty" example:"datasets/imagenet/train-00000.tar"`
+} // @name UpdateManifestMembersRequest
+
+// ManifestMemberResponse is an object in a manifest, pinned to the ETag it
+// had when added
+type ManifestMemberResponse struct {
+	Key  string `json:"key" example:"datasets/imagenet/train-00000.tar"`
+	ETag string `json:"etag,omitempty" example:"1730800800-1048576"`
+	Size int64  `json:"size" example:"1048576"`
+} // @name ManifestMember
+
+// ManifestResponse is a manifest draft (version 0) or published version
+type ManifestResponse struct {
+	Name        string                   `json:"name" example:"imagenet-train"`
+	Version     int                      `json:"version" example:"3"`
+	Members     []ManifestMemberResponse `json:"members"`
+	Size        int64                    `json:"size" example:"1048576"`
+	UpdatedAt   string                   `json:"updated_at" example:"2025-11-05T10:00:00Z"`
+	PublishedBy string                   `json:"published_by,omitempty" example:"ci"`
+} // @name Manifest
+
+// ManifestsResponse lists manifest names
+type ManifestsResponse struct {
+	Manifests []string `json:"manifests"`
+	Count     int      `json:"count" example:"1"`
+} // @name ManifestList
+
+// ManifestVersionsResponse lists the published versions of a manifest
+type ManifestVersionsResponse struct {
+	Name     string `json:"name" example:"imagenet-train"`
+	Versions []int  `json:"versions"`
+	Count    int    `json:"count" example:"3"`
+} // @name ManifestVersionList
+
+// ListManifests lists the names of all manifests
+func (h *Handler) ListManifests(c *gin.Context) {
+	manager, err := objstore.Manifests(h.backend)
+	if err != nil {
+		respondWithManifestError(c, err)
+		return
+	}
+
+	names, err := manager.List(c.Request.Context())
+	if err != nil {
+		respondWithManifestError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, ManifestsResponse{Manifests: names, Count: len(names)})
+}
+
+// CreateManifest creates a manifest draft with the given member keys
+func (h *Handler) CreateManifest(c *gin.Context) {
+	var body CreateManifestRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+
+	manager, err := objstore.Manifests(h.backend)
+	if err != nil {
+		respondWithManifestError(c, err)
+		return
+	}
+
+	draft, err := manager.Create(c.Request.Context(), body.Name, body.Keys...)
+	if err != nil {
+		respondWithManifestError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, manifestResponse(draft))
+}
+
+// GetManifest returns the published version of a manifest
+func (h *Handler) GetManifest(c *gin.Context) {
+	manager, err := objstore.Manifests(h.backend)
+	if err != nil {
+		respondWithManifestError(c, err)
+		return
+	}
+
+	current, err := manager.Current(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		respondWithManifestError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, manifestResponse(current))
+}
+
+// GetManifestDraft returns the draft of a manifest
+func (h *Handler) GetManifestDraft(c *gin.Context) {
+	manager, err := objstore.Manifests(h.backend)
+	if err != nil {
+		respondWithManifestError(c, err)
+		return
+	}
+
+	draft, err := manager.Draft(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		respondWithManifestError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, manifestResponse(draft))
+}
+
+// UpdateManifestMembers adds and then removes members of a manifest draft
+func (h *Handler) UpdateManifestMembers(c *gin.Context) {
+	var body UpdateManifestMembersRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	if len(body.Add) == 0 && len(body.Remove) == 0 {
+		RespondWithError(c, http.StatusBadRequest, "add or remove is required")
+		return
+	}
+
+	manager, err := objstore.Manifests(h.backend)
+	if err != nil {
+		respondWithManifestError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	name := c.Param("name")
+	var draft *manifest.Manifest
+	if len(body.Add) > 0 {
+		if draft, err = manager.Add(ctx, name, body.Add...); err != nil {
+			respondWithManifestError(c, err)
+			return
+		}
+	}
+	if len(body.Remove) > 0 {
+		if draft, err = manager.Remove(ctx, name, body.Remove...); err != nil {
+			respondWithManifestError(c, err)
+			return
+		}
+	}
+	c.JSON(http.StatusOK, manifestResponse(draft))
+}
+
+// PublishManifest publishes a manifest's draft as its next version
+func (h *Handler) PublishManifest(c *gin.Context) {
+	manager, err := objstore.Manifests(h.backend)
+	if err != nil {
+		respondWithManifestError(c, err)
+		return
+	}
+
+	published, err := manager.Publish(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		respondWithManifestError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, manifestResponse(published))
+}
+
+// ListManifestVersions lists the published versions of a manifest
+func (h *Handler) ListManifestVersions(c *gin.Context) {
+	manager, err := objstore.Manifests(h.backend)
+	if err != nil {
+		respondWithManifestError(c, err)
+		return
+	}
+
+	name := c.Param("name")
+	versions, err := manager.Versions(c.Request.Context(), name)
+	if err != nil {
+		respondWithManifestError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, ManifestVersionsResponse{Name: name, Versions: versions, Count: len(versions)})
+}
+
+// GetManifestVersion returns a published version of a manifest
+func (h *Handler) GetManifestVersion(c *gin.Context) {
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 1 {
+		RespondWithError(c, http.StatusBadRequest, "version must be a positive integer")
+		return
+	}
+
+	manager, err := objstore.Manifests(h.backend)
+	if err != nil {
+		respondWithManifestError(c, err)
+		return
+	}
+
+	published, err := manager.Version(c.Request.Context(), c.Param("name"), version)
+	if err != nil {
+		respondWithManifestError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, manifestResponse(published))
+}
+
+// GetManifestTar streams the members of a manifest as a tar archive. The
+// version query parameter selects a published version or "draft"; the
+// current version is the default.
+func (h *Handler) GetManifestTar(c *gin.Context) {
+	manager, err := objstore.Manifests(h.backend)
+	if err != nil {
+		respondWithManifestError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	name := c.Param("name")
+	var m *manifest.Manifest
+	switch version := c.Query("version"); version {
+	case "":
+		m, err = manager.Current(ctx, name)
+	case "draft":
+		m, err = manager.Draft(ctx, name)
+	default:
+		n, convErr := strconv.Atoi(version)
+		if convErr != nil || n < 1 {
+			RespondWithError(c, http.StatusBadRequest, `version must be a positive integer or "draft"`)
+			return
+		}
+		m, err = manager.Version(ctx, name, n)
+	}
+	if err != nil {
+		respondWithManifestError(c, err)
+		return
+	}
+
+	filename := fmt.Sprintf("%s-v%d.tar", m.Name, m.Version)
+	if m.Version == 0 {
+		filename = m.Name + "-draft.tar"
+	}
+	w := &tarResponseWriter{c: c, filename: filename}
+	if err := manager.WriteTar(ctx, m, w); err != nil {
+		// Once the archive has started the status is sent; the truncated
+		// body is all the client will see.
+		if !c.Writer.Written() {
+			respondWithManifestError(c, err)
+		}
+		return
+	}
+	if !c.Writer.Written() {
+		w.start()
+	}
+}
+
+// tarResponseWriter sends the archive headers with the first write, so
+// failures found before any data is written can still be reported as JSON.
+type tarResponseWriter struct {
+	c        *gin.Context
+	filename string
+}
+
+func (w *tarResponseWriter) start() {
+	w.c.Header("Content-Type", "application/x-tar")
+	w.c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", w.filename))
+	w.c.Status(http.StatusOK)
+	w.c.Writer.WriteHeaderNow()
+}
+
+func (w *tarResponseWriter) Write(p []byte) (int, error) {
+	if !w.c.Writer.Written() {
+		w.start()
+	}
+	return w.c.Writer.Write(p)
+}
+
+// isManifestsPath reports whether path belongs to the manifest API.
+func isManifestsPath(path string) bool {
+	return path == manifestsPath || strings.HasPrefix(path, manifestsPath+"/")
+}
+
+// respondWithManifestError maps manifest errors to responses that name the
+// manifest rather than the generic object messages.
+func respondWithManifestError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, objstore.ErrManifestsNotEnabled):
+		RespondWithError(c, http.StatusNotImplemented, "manifests are not enabled on this server")
+	case errors.Is(err, manifest.ErrManifestNotFound), errors.Is(err, manifest.ErrVersionNotFound),
+		errors.Is(err, manifest.ErrManifestExists), errors.Is(err, manifest.ErrConflict),
+		errors.Is(err, manifest.ErrMemberChanged), errors.Is(err, manifest.ErrInvalidName),
+		errors.Is(err, manifest.ErrEmptyManifest), errors.Is(err, manifest.ErrTooManyMembers),
+		errors.Is(err, common.ErrReservedKey):
+		// Manifest errors name the manifest or member at fault, which the
+		// generic messages would drop.
+		code, _ := servererrors.HTTPStatus(err)
+		RespondWithError(c, code, err.Error())
+	default:
+		RespondWithBackendError(c, err)
+	}
+}
+
+func manifestResponse(m *manifest.Manifest) ManifestResponse {
+	members := make([]ManifestMemberResponse, len(m.Members))
+	for i, member := range m.Members {
+		members[i] = ManifestMemberResponse{Key: member.Key, ETag: member.ETag, Size: member.Size}
+	}
+	return ManifestResponse{
+		Name:        m.Name,
+		Version:     m.Version,
+		Members:     members,
+		Size:        m.Size,
+		UpdatedAt:   m.UpdatedAt.Format(time.RFC3339),
+		PublishedBy: m.PublishedBy,
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+)
+
+// newManifestsTestServer builds a server over a memory backend holding two
+// objects; every bearer token authenticates as the admin it names.
+func newManifestsTestServer(t *testing.T, enable bool) *gin.Engine {
+	t.Helper()
+	storage := memory.New()
+	for key, data := range map[string]string{"data/a.txt": "alpha", "data/b.txt": "beta"} {
+		if err := storage.Put(key, strings.NewReader(data)); err != nil {
+			t.Fatalf("Put(%s): %v", key, err)
+		}
+	}
+	initTestFacade(t, storage)
+	if enable {
+		if err := objstore.EnableManifests("", ""); err != nil {
+			t.Fatalf("EnableManifests: %v", err)
+		}
+	}
+
+	config := DefaultServerConfig()
+	config.Mode = gin.TestMode
+	config.Authenticator = adapters.NewBearerTokenAuthenticator(func(_ context.Context, token string) (*adapters.Principal, error) {
+		return &adapters.Principal{ID: token, Name: token, Roles: []string{"admin"}}, nil
+	})
+	server, err := NewServer(storage, config)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	return server.Router()
+}
+
+func TestManifestEndpoints(t *testing.T) {
+	router := newManifestsTestServer(t, true)
+
+	w := doTokenRequest(router, http.MethodPost, "/api/v1/manifests", "ci", `{"name":"train","keys":["data/a.txt"]}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create = %d %s", w.Code, w.Body.String())
+	}
+	if w := doTokenRequest(router, http.MethodPost, "/api/v1/manifests", "ci", `{"name":"train"}`); w.Code != http.StatusConflict {
+		t.Errorf("create existing = %d, want 409", w.Code)
+	}
+	if w := doTokenRequest(router, http.MethodGet, "/api/v1/manifests/train", "ci", ""); w.Code != http.StatusNotFound {
+		t.Errorf("get unpublished = %d, want 404", w.Code)
+	}
+
+	w = doTokenRequest(router, http.MethodPost, "/api/v1/manifests/train/members", "ci", `{"add":["data/b.txt"]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("update members = %d %s", w.Code, w.Body.String())
+	}
+	w = doTokenRequest(router, http.MethodPost, "/api/v1/manifests/train/publish", "ci", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("publish = %d %s", w.Code, w.Body.String())
+	}
+	var published ManifestResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &published); err != nil {
+		t.Fatal(err)
+	}
+	if published.Version != 1 || len(published.Members) != 2 || published.PublishedBy != "ci" {
+		t.Errorf("published = %+v", published)
+	}
+
+	w = doTokenRequest(router, http.MethodGet, "/api/v1/manifests/train/versions", "ci", "")
+	var versions ManifestVersionsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &versions); err != nil || versions.Count != 1 {
+		t.Errorf("versions = %s", w.Body.String())
+	}
+	if w := doTokenRequest(router, http.MethodGet, "/api/v1/manifests/train/versions/1", "ci", ""); w.Code != http.StatusOK {
+		t.Errorf("get version = %d", w.Code)
+	}
+	if w := doTokenRequest(router, http.MethodGet, "/api/v1/manifests/train/versions/x", "ci", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("get bad version = %d, want 400", w.Code)
+	}
+
+	w = doTokenRequest(router, http.MethodGet, "/api/v1/manifests/train/tar", "ci", "")
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "application/x-tar" {
+		t.Fatalf("tar = %d %s", w.Code, w.Header().Get("Content-Type"))
+	}
+	tr := tar.NewReader(bytes.NewReader(w.Body.Bytes()))
+	var names []string
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("tar: %v", err)
+		}
+		names = append(names, hdr.Name)
+	}
+	if strings.Join(names, ",") != "data/a.txt,data/b.txt" {
+		t.Errorf("tar members = %v", names)
+	}
+
+	// A changed member is reported before any of the archive is sent.
+	if w := doTokenRequest(router, http.MethodPut, "/api/v1/objects/data/b.txt", "ci", "rewritten"); w.Code != http.StatusCreated {
+		t.Fatalf("put = %d %s", w.Code, w.Body.String())
+	}
+	w = doTokenRequest(router, http.MethodGet, "/api/v1/manifests/train/tar", "ci", "")
+	if w.Code != http.StatusPreconditionFailed || !strings.Contains(w.Body.String(), "data/b.txt") {
+		t.Errorf("tar with changed member = %d %s", w.Code, w.Body.String())
+	}
+
+	// Manifest documents cannot be changed through the object API.
+	if w := doTokenRequest(router, http.MethodDelete, "/api/v1/objects/.manifests/train/current.json", "ci", ""); w.Code != http.StatusForbidden {
+		t.Errorf("delete manifest object = %d, want 403", w.Code)
+	}
+
+	w = doTokenRequest(router, http.MethodGet, "/api/v1/manifests", "ci", "")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"train"`) {
+		t.Errorf("list = %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestManifestEndpointsNotEnabled(t *testing.T) {
+	router := newManifestsTestServer(t, false)
+	if w := doTokenRequest(router, http.MethodGet, "/api/v1/manifests", "ci", ""); w.Code != http.StatusNotImplemented {
+		t.Errorf("list = %d, want 501", w.Code)
+	}
+}
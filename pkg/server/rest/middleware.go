@@ -14,6 +14,7 @@
 package rest
 
 import (
+	"context"
 	"log/slog"
 	"net/http"
 	"strconv"
@@ -131,6 +132,14 @@ func LoggingMiddleware(logger adapters.Logger) gin.HandlerFunc {
 			{Key: "latency", Value: latency.String()},
 			{Key: "client_ip", Value: clientIP},
 		}
+		if value, ok := c.Get(principalContextKey); ok {
+			if principal, _ := value.(*adapters.Principal); principal != nil {
+				fields = append(fields, adapters.Field{Key: "principal_id", Value: principal.ID})
+				if principal.OnBehalfOf != "" {
+					fields = append(fields, adapters.Field{Key: "on_behalf_of", Value: principal.OnBehalfOf})
+				}
+			}
+		}
 
 		switch {
 		case statusCode >= 500:
@@ -183,8 +192,8 @@ func RequestSizeLimitMiddleware(maxSize int64) gin.HandlerFunc {
 // authenticator. Public paths (/health, and /metrics when metricsPublic is
 // set) bypass authentication entirely so they remain reachable behind
 // restrictive authenticators (e.g. Prometheus scrapers and load-balancer
-// health checks carry no credentials). Swagger documentation is not public
-// and requires authentication.
+// health checks carry no credentials). Swagger and OpenAPI documentation is
+// not public and requires authentication.
 func AuthenticationMiddleware(authenticator adapters.Authenticator, logger adapters.Logger, auditLogger audit.AuditLogger, metricsPublic bool) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if isPublicPath(c.Request.URL.Path, metricsPublic) {
@@ -213,8 +222,10 @@ func AuthenticationMiddleware(authenticator adapters.Authenticator, logger adapt
 			return
 		}
 
-		// Store principal in context for use by handlers
+		// Store principal in context for use by handlers, and in the request
+		// context for transport-agnostic middleware
 		c.Set(principalContextKey, principal)
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), adapters.PrincipalContextKey{}, principal))
 
 		// Audit log successful authentication
 		if auditLogger != nil {
@@ -233,8 +244,9 @@ func AuthenticationMiddleware(authenticator adapters.Authenticator, logger adapt
 // everything, preserving prior behavior.
 func AuthorizationMiddleware(authorizer adapters.Authorizer, logger adapters.Logger, auditLogger audit.AuditLogger, metricsPublic bool) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Public paths and swagger are exempt from authorization; swagger still
-		// requires authentication, enforced by AuthenticationMiddleware.
+		// Public paths and API documentation are exempt from authorization;
+		// documentation still requires authentication, enforced by
+		// AuthenticationMiddleware.
 		if isAuthzExemptPath(c.Request.URL.Path, metricsPublic) {
 			c.Next()
 			return
@@ -250,8 +262,17 @@ func AuthorizationMiddleware(authorizer adapters.Authorizer, logger adapters.Log
 		}
 
 		action, resource := deriveActionResource(c)
+		err := authorizer.Authorize(c.Request.Context(), principal, action, resource)
+		if err == nil && isDiffPath(c.Request.URL.Path) {
+			resource = diffKey(c.Query("b"))
+			err = authorizer.Authorize(c.Request.Context(), principal, action, resource)
+		}
+		if err == nil && isRenamePath(c.Request.URL.Path) {
+			action, resource = adapters.ActionWrite, c.Query("new_prefix")
+			err = authorizer.Authorize(c.Request.Context(), principal, action, resource)
+		}
 
-		if err := authorizer.Authorize(c.Request.Context(), principal, action, resource); err != nil {
+		if err != nil {
 			logger.Warn(c.Request.Context(), "Authorization denied",
 				adapters.Field{Key: "error", Value: err.Error()},
 				adapters.Field{Key: "path", Value: c.Request.URL.Path},
@@ -277,31 +298,133 @@ func AuthorizationMiddleware(authorizer adapters.Authorizer, logger adapters.Log
 }
 
 // isPublicPath reports whether the path bypasses authentication entirely.
-// Only /health is always public; /metrics is public when the server is
-// configured with MetricsPublic. Swagger documentation requires
-// authentication and is therefore never public.
+// Only /health, share link downloads, whose token is their credential, and
+// browser form uploads, whose signed POST policy is theirs, are always
+// public; /metrics is public when the server is configured with
+// MetricsPublic. Swagger documentation requires authentication and is
+// therefore never public.
 func isPublicPath(path string, metricsPublic bool) bool {
 	if path == "/metrics" {
 		return metricsPublic
 	}
-	return path == "/health"
+	return path == "/health" || path == uploadPath || strings.HasPrefix(path, sharePath)
 }
 
 // isAuthzExemptPath reports whether the path is exempt from authorization.
-// All public (unauthenticated) paths are exempt, as is /swagger, which
-// requires authentication but no specific permission.
+// All public (unauthenticated) paths are exempt, as are /swagger, the
+// OpenAPI specification and the static admin UI assets, which require
+// authentication but no specific permission. The token endpoint authorizes
+// each requested action itself, and the upload session routes authorize a
+// write of the session's key once they have looked the session up.
 func isAuthzExemptPath(path string, metricsPublic bool) bool {
-	return isPublicPath(path, metricsPublic) || strings.HasPrefix(path, "/swagger")
+	return isPublicPath(path, metricsPublic) || strings.HasPrefix(path, "/swagger") ||
+		path == "/openapi.json" || path == "/openapi.yaml" || path == tokensPath ||
+		isUploadSessionPath(path) || path == uiPath || strings.HasPrefix(path, uiPath+"/")
+}
+
+// isRetentionPath reports whether path belongs to the legal hold and
+// deletion approval API. Matching is by prefix so object keys containing
+// "/holds" are not mistaken for it.
+func isRetentionPath(path string) bool {
+	for _, prefix := range retentionPrefixes {
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+	return false
 }
 
 // deriveActionResource maps an HTTP request to a (action, resource) pair using
 // the route taxonomy. Object and metadata operations use the object key as the
-// resource; management operations use the resource category constants.
+// resource and listing uses the requested prefix; management operations use
+// the resource category constants.
 func deriveActionResource(c *gin.Context) (action, resource string) {
 	path := c.Request.URL.Path
 	method := c.Request.Method
 
 	switch {
+	case isRetentionPath(path):
+		return adapters.ActionAdmin, adapters.ResourceRetention
+	case isQuotasPath(path):
+		// Checked before the substring matches below: prefixes such as
+		// "policies/" appear in the path.
+		return adapters.ActionAdmin, adapters.ResourceQuota
+	case isWebhooksPath(path):
+		return adapters.ActionAdmin, adapters.ResourceWebhook
+	case isAccessKeysPath(path):
+		return adapters.ActionAdmin, adapters.ResourceAccessKey
+	case isLocksPath(path):
+		// Locks are authorized against the locked key: inspecting them is a
+		// read (a list without a key) and taking or releasing them a write.
+		key := strings.TrimPrefix(c.Param("key"), "/")
+		switch {
+		case method != http.MethodGet:
+			return adapters.ActionWrite, key
+		case key == "":
+			return adapters.ActionList, c.Query("prefix")
+		default:
+			return adapters.ActionRead, key
+		}
+	case isAliasesPath(path):
+		// Aliases are authorized against their name: resolving them is a
+		// read (a list without a name) and setting or removing them a
+		// write. SetAlias also checks read access to the target.
+		name := strings.TrimPrefix(c.Param("key"), "/")
+		switch {
+		case method != http.MethodGet:
+			return adapters.ActionWrite, name
+		case name == "":
+			return adapters.ActionList, c.Query("prefix")
+		default:
+			return adapters.ActionRead, name
+		}
+	case isSharesPath(path):
+		// Creating a link lets anyone holding it read the key, so it needs
+		// read access to the key; managing links is authorized on the
+		// shares category.
+		switch method {
+		case http.MethodPost:
+			return adapters.ActionRead, strings.TrimPrefix(c.Param("key"), "/")
+		case http.MethodDelete:
+			return adapters.ActionDelete, adapters.ResourceShares
+		default:
+			return adapters.ActionRead, adapters.ResourceShares
+		}
+	case isUploadsPath(path):
+		// Starting an upload session is a write of its key. Routes of an
+		// existing session are exempt and authorized by their handlers.
+		return adapters.ActionWrite, strings.TrimPrefix(c.Param("key"), "/")
+	case isSelectPath(path):
+		// A select query reads the object it runs over.
+		return adapters.ActionRead, strings.TrimPrefix(c.Param("key"), "/")
+	case isDiffPath(path):
+		// A diff reads two objects; AuthorizationMiddleware checks the
+		// second (b) as well.
+		return adapters.ActionRead, diffKey(c.Query("a"))
+	case isRenamePath(path):
+		// A rename deletes every object under prefix; AuthorizationMiddleware
+		// checks the write of new_prefix as well.
+		return adapters.ActionDelete, c.Query("prefix")
+	case isManifestsPath(path):
+		switch {
+		case method != http.MethodGet:
+			return adapters.ActionWrite, adapters.ResourceManifest
+		case path == manifestsPath:
+			return adapters.ActionList, adapters.ResourceManifest
+		default:
+			return adapters.ActionRead, adapters.ResourceManifest
+		}
+	case isJobsPath(path):
+		// Checked before the substring matches below: job names such as
+		// "replication" appear in the path.
+		return adapters.ActionRead, adapters.ResourceJobs
+	case isTasksPath(path):
+		// Tasks carry object keys and destination settings: reading them
+		// needs read access and retrying them admin access.
+		if method == http.MethodGet {
+			return adapters.ActionRead, adapters.ResourceTasks
+		}
+		return adapters.ActionAdmin, adapters.ResourceTasks
 	case strings.Contains(path, "/replication"):
 		return adapters.ActionAdmin, adapters.ResourceReplication
 	case strings.Contains(path, "/policies"):
@@ -310,10 +433,32 @@ func deriveActionResource(c *gin.Context) (action, resource string) {
 		// Archive acts on an object key; key is supplied in the request body so
 		// the route param is unavailable here. Use the policy resource category.
 		return adapters.ActionAdmin, adapters.ResourcePolicy
+	case method == http.MethodPatch && c.Param("key") == "" && strings.HasSuffix(path, "/objects"):
+		// A batch metadata update writes every object under the prefix.
+		return adapters.ActionWrite, c.Query("prefix")
 	case method == http.MethodGet && c.Param("key") == "" && strings.HasSuffix(path, "/objects"):
 		// GET on the bare objects collection (/objects, /api/v1/objects) is a
-		// list operation with no specific resource.
+		// list operation; its resource is the requested prefix.
+		return adapters.ActionList, c.Query("prefix")
+	// The collection routes below are matched exactly: an object key may end
+	// in the same segment (GET /api/v1/objects/a/search reads a/search).
+	case method == http.MethodGet && path == "/api/v1/browse":
+		// Browsing lists the children of the requested prefix.
+		return adapters.ActionList, c.Query("prefix")
+	case method == http.MethodGet && (path == "/api/v1/search" || path == "/search"):
+		// Search returns keys and metadata across the backend, like a list.
+		return adapters.ActionList, ""
+	case method == http.MethodGet && path == "/api/v1/changes":
+		// The change feed reveals keys across the backend, like a list.
+		return adapters.ActionList, ""
+	case method == http.MethodGet && path == "/api/v1/stats":
+		// Statistics reveal prefixes and sizes across the backend, like a list.
 		return adapters.ActionList, ""
+	case method == http.MethodGet && path == "/api/v1/analyze":
+		// An analysis counts the objects under the prefix, like a list.
+		return adapters.ActionList, c.Query("prefix")
+	case method == http.MethodGet && path == "/api/v1/cost":
+		return adapters.ActionRead, adapters.ResourceCost
 	}
 
 	// Object key is carried in the "key" route param for /objects, /exists,
@@ -14,13 +14,18 @@
 package rest
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
 )
 
 func TestCORSMiddleware(t *testing.T) {
@@ -213,3 +218,70 @@ func TestRequestSizeLimitMiddlewarePOST(t *testing.T) {
 		t.Errorf("RequestSizeLimitMiddleware() POST status = %v, want %v", w.Code, http.StatusRequestEntityTooLarge)
 	}
 }
+
+func TestDeriveActionResourceCollectionRoutes(t *testing.T) {
+	derive := func(method, path, key string) (string, string) {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(method, path, nil)
+		if key != "" {
+			c.Params = gin.Params{{Key: "key", Value: key}}
+		}
+		return deriveActionResource(c)
+	}
+
+	for _, tc := range []struct {
+		path, action, resource string
+	}{
+		{"/api/v1/search", adapters.ActionList, ""},
+		{"/search", adapters.ActionList, ""},
+		{"/api/v1/browse?prefix=logs/", adapters.ActionList, "logs/"},
+		{"/api/v1/changes", adapters.ActionList, ""},
+		{"/api/v1/stats", adapters.ActionList, ""},
+		{"/api/v1/analyze?prefix=logs/", adapters.ActionList, "logs/"},
+		{"/api/v1/cost", adapters.ActionRead, adapters.ResourceCost},
+	} {
+		if action, resource := derive(http.MethodGet, tc.path, ""); action != tc.action || resource != tc.resource {
+			t.Errorf("deriveActionResource(%s) = %s, %q, want %s, %q", tc.path, action, resource, tc.action, tc.resource)
+		}
+	}
+
+	// An object key ending in a collection route's segment is still a read
+	// of that key, not a list.
+	for _, segment := range []string{"search", "browse", "changes", "stats", "analyze", "cost"} {
+		key := "/secret/" + segment
+		for _, path := range []string{"/api/v1/objects" + key, "/objects" + key} {
+			if action, resource := derive(http.MethodGet, path, key); action != adapters.ActionRead || resource != "secret/"+segment {
+				t.Errorf("deriveActionResource(%s) = %s, %q, want %s, %q", path, action, resource, adapters.ActionRead, "secret/"+segment)
+			}
+		}
+	}
+}
+
+func TestSigV4PayloadMismatchStoresNothing(t *testing.T) {
+	storage := memory.New()
+	initTestFacade(t, storage)
+	creds := adapters.SigV4Credentials{AccessKeyID: "AKIDWRITER", SecretAccessKey: "writer-secret"}
+
+	config := DefaultServerConfig()
+	config.Mode = gin.TestMode
+	config.Authenticator = adapters.NewStaticSigV4Authenticator(map[string]adapters.SigV4Key{
+		creds.AccessKeyID: {SecretAccessKey: creds.SecretAccessKey, Principal: adapters.Principal{ID: "writer"}},
+	})
+	server, err := NewServer(storage, config)
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+
+	sum := sha256.Sum256([]byte("original"))
+	req := httptest.NewRequest(http.MethodPut, "http://objstore.local/api/v1/objects/forged.txt", strings.NewReader("forged"))
+	adapters.SignHTTPRequestV4(req, creds, "us-east-1", "s3", hex.EncodeToString(sum[:]), time.Now())
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("PUT with forged body status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+	if exists, err := storage.Exists(context.Background(), "forged.txt"); err != nil || exists {
+		t.Errorf("forged body was stored: exists = %v, %v", exists, err)
+	}
+}
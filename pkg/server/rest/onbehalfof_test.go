@@ -0,0 +1,83 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/audit"
+)
+
+// eventRecorder captures audit events.
+type eventRecorder struct {
+	audit.AuditLogger
+	mu     sync.Mutex
+	events []*audit.AuditEvent
+}
+
+func (r *eventRecorder) LogEvent(_ context.Context, event *audit.AuditEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+	return nil
+}
+
+// TestOnBehalfOfAudited verifies that a service permitted to impersonate has
+// the end user recorded in the audit log, and that other callers are refused.
+func TestOnBehalfOfAudited(t *testing.T) {
+	authz := adapters.NewRBACAuthorizer(map[string][]string{
+		"frontend": {adapters.ActionRead, adapters.ActionList, adapters.ActionImpersonate},
+		"reader":   {adapters.ActionRead, adapters.ActionList},
+	})
+	callers := adapters.NewBearerTokenAuthenticator(func(_ context.Context, token string) (*adapters.Principal, error) {
+		return &adapters.Principal{ID: token, Name: token, Roles: []string{token}}, nil
+	})
+	recorder := &eventRecorder{AuditLogger: audit.NewNoOpAuditLogger()}
+
+	config := DefaultServerConfig()
+	config.Mode = gin.TestMode
+	config.Authenticator = adapters.NewOnBehalfOfAuthenticator(callers, authz)
+	config.Authorizer = authz
+	config.AuditLogger = recorder
+	router := newRESTServer(t, config).Router()
+
+	request := func(caller string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/objects", nil)
+		req.Header.Set("Authorization", "Bearer "+caller)
+		req.Header.Set(adapters.OnBehalfOfHeader, "alice@example.com")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := request("frontend"); code != http.StatusOK {
+		t.Fatalf("frontend on behalf of alice = %d, want %d", code, http.StatusOK)
+	}
+	recorder.mu.Lock()
+	last := recorder.events[len(recorder.events)-1]
+	recorder.mu.Unlock()
+	if last.UserID != "frontend" || last.OnBehalfOf != "alice@example.com" {
+		t.Errorf("audit event = %s on behalf of %q, want frontend on behalf of alice@example.com", last.UserID, last.OnBehalfOf)
+	}
+
+	if code := request("reader"); code != http.StatusUnauthorized {
+		t.Errorf("reader on behalf of alice = %d, want %d", code, http.StatusUnauthorized)
+	}
+}
@@ -0,0 +1,246 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/audit"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+)
+
+// uploadPath is the endpoint of browser form uploads signed with a POST
+// policy. It is served at the server root without authentication: the
+// signed policy in the form is the credential.
+const uploadPath = "/upload"
+
+// maxPostPolicyFieldBytes bounds the form fields preceding the file, which
+// are read into memory before the policy is verified.
+const maxPostPolicyFieldBytes = 64 << 10
+
+// Form fields of a POST policy upload beyond the signed policy.
+const (
+	postKeyField             = "key"
+	postFileField            = "file"
+	postContentTypeField     = "content-type"
+	postContentEncodingField = "content-encoding"
+	postMetaPrefix           = "x-amz-meta-"
+	postSuccessStatusField   = "success_action_status"
+	postSuccessRedirectField = "success_action_redirect"
+	postFilenameVariable     = "${filename}"
+)
+
+var (
+	errUploadTooLarge = errors.New("file is larger than the POST policy allows")
+	errUploadTooSmall = errors.New("file is smaller than the POST policy requires")
+)
+
+// setupPostPolicyRoutes mounts the browser form upload endpoint. The route is
+// public because the form carries its own credential, a POST policy signed
+// with a SigV4 key; the policy's signer must be authorized to write the key.
+func setupPostPolicyRoutes(router *gin.Engine, handler *Handler, verifier *adapters.SigV4Authenticator, authorizer adapters.Authorizer, logger adapters.Logger) {
+	router.POST(uploadPath, func(c *gin.Context) {
+		reader, err := c.Request.MultipartReader()
+		if err != nil {
+			RespondWithError(c, http.StatusBadRequest, "request must be multipart/form-data")
+			return
+		}
+
+		// The fields precede the file, which S3 requires to be the last part;
+		// anything after it is ignored.
+		fields := make(map[string]string)
+		var file io.Reader
+		var fileType string
+		budget := int64(maxPostPolicyFieldBytes)
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				RespondWithError(c, http.StatusBadRequest, "invalid multipart form: "+err.Error())
+				return
+			}
+			name := strings.ToLower(part.FormName())
+			if name == postFileField {
+				file = part
+				fileType = part.Header.Get("Content-Type")
+				if key, ok := fields[postKeyField]; ok {
+					fields[postKeyField] = strings.ReplaceAll(key, postFilenameVariable, part.FileName())
+				}
+				break
+			}
+			value, err := io.ReadAll(io.LimitReader(part, budget+1))
+			budget -= int64(len(value))
+			if err != nil || budget < 0 {
+				RespondWithError(c, http.StatusBadRequest, "form fields are too large or unreadable")
+				return
+			}
+			fields[name] = string(value)
+		}
+
+		ctx := c.Request.Context()
+		principal, policy, err := verifier.VerifyPostPolicy(ctx, fields)
+		if err != nil {
+			logger.Warn(ctx, "POST policy verification failed",
+				adapters.Field{Key: "error", Value: err.Error()},
+				adapters.Field{Key: "path", Value: c.Request.URL.Path},
+			)
+			_ = audit.GetAuditLogger(ctx).LogAuthFailure(ctx, "", "", c.ClientIP(), audit.GetRequestID(ctx), err.Error()) // #nosec G104 -- Audit logging errors are logged internally, should not block operations
+			RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		if err := policy.Check(fields); err != nil {
+			RespondWithError(c, http.StatusForbidden, err.Error())
+			return
+		}
+
+		key := strings.TrimLeft(fields[postKeyField], "/")
+		if key == "" || file == nil {
+			RespondWithError(c, http.StatusBadRequest, "key and file fields are required")
+			return
+		}
+		if err := authorizer.Authorize(ctx, principal, adapters.ActionWrite, key); err != nil {
+			logger.Warn(ctx, "POST policy upload denied",
+				adapters.Field{Key: "error", Value: err.Error()},
+				adapters.Field{Key: "resource", Value: key},
+				adapters.Field{Key: "principal_id", Value: principal.ID},
+			)
+			RespondWithError(c, http.StatusForbidden, "Forbidden")
+			return
+		}
+		c.Set(principalContextKey, principal)
+		c.Request = c.Request.WithContext(context.WithValue(ctx, adapters.PrincipalContextKey{}, principal))
+		ctx = c.Request.Context()
+
+		metadata, err := postPolicyMetadata(fields, fileType)
+		if err != nil {
+			RespondWithError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		body := &sizeRangeReader{r: file, max: -1}
+		if minSize, maxSize, ok := policy.SizeRange(); ok {
+			body.min, body.max = minSize, maxSize
+		}
+
+		err = objstore.PutWithMetadata(ctx, handler.keyRef(key), body, metadata)
+
+		auditLogger := audit.GetAuditLogger(ctx)
+		principalName, userID := extractPrincipal(c)
+		requestID := audit.GetRequestID(ctx)
+		if err != nil {
+			_ = auditLogger.LogObjectMutation(ctx, audit.EventObjectCreated,
+				userID, principalName, handler.backend, key, c.ClientIP(), requestID, 0,
+				audit.ResultFailure, err)
+			if body.err != nil {
+				RespondWithError(c, http.StatusBadRequest, body.err.Error())
+				return
+			}
+			RespondWithBackendError(c, err)
+			return
+		}
+		_ = auditLogger.LogObjectMutation(ctx, audit.EventObjectCreated,
+			userID, principalName, handler.backend, key, c.ClientIP(), requestID, body.n,
+			audit.ResultSuccess, nil)
+
+		var etag string
+		if stored, metaErr := objstore.GetMetadata(ctx, handler.keyRef(key)); metaErr == nil && stored != nil && stored.ETag != "" {
+			etag = stored.ETag
+			c.Header("ETag", etag)
+		}
+		respondPostPolicyUpload(c, fields, key, etag)
+	})
+}
+
+// postPolicyMetadata builds the object metadata from the form fields,
+// falling back to the file part's own content type.
+func postPolicyMetadata(fields map[string]string, fileType string) (*common.Metadata, error) {
+	metadata := &common.Metadata{
+		ContentType:     fields[postContentTypeField],
+		ContentEncoding: fields[postContentEncodingField],
+	}
+	if metadata.ContentType == "" {
+		metadata.ContentType = fileType
+	}
+	for name, value := range fields {
+		if !strings.HasPrefix(name, postMetaPrefix) {
+			continue
+		}
+		if metadata.Custom == nil {
+			metadata.Custom = make(map[string]string)
+		}
+		metadata.Custom[strings.TrimPrefix(name, postMetaPrefix)] = value
+	}
+	if err := common.ValidateMetadata(metadata.Custom); err != nil {
+		return nil, fmt.Errorf("invalid %s fields: %w", postMetaPrefix+"*", err)
+	}
+	return metadata, nil
+}
+
+// respondPostPolicyUpload answers a successful upload the way S3 does: a
+// redirect to success_action_redirect with the key and ETag appended, or
+// else the status in success_action_status (204 unless 200 or 201). A 201
+// carries the same body as PUT /objects.
+func respondPostPolicyUpload(c *gin.Context, fields map[string]string, key, etag string) {
+	if redirect, err := url.Parse(fields[postSuccessRedirectField]); err == nil && redirect.IsAbs() {
+		query := redirect.Query()
+		query.Set(postKeyField, key)
+		query.Set("etag", etag)
+		redirect.RawQuery = query.Encode()
+		c.Redirect(http.StatusSeeOther, redirect.String())
+		return
+	}
+	switch fields[postSuccessStatusField] {
+	case "200":
+		c.Status(http.StatusOK)
+	case "201":
+		RespondWithSuccess(c, http.StatusCreated, "object uploaded successfully", gin.H{keyField: key, "etag": etag})
+	default:
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// sizeRangeReader fails a read once more than max bytes (unless max is
+// negative) have been read, or at EOF with fewer than min, keeping the
+// error so it can be told apart from storage errors.
+type sizeRangeReader struct {
+	r        io.Reader
+	min, max int64
+	n        int64
+	err      error
+}
+
+func (s *sizeRangeReader) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	s.n += int64(n)
+	switch {
+	case s.max >= 0 && s.n > s.max:
+		s.err = errUploadTooLarge
+	case err == io.EOF && s.n < s.min:
+		s.err = errUploadTooSmall
+	}
+	if s.err != nil {
+		return n, s.err
+	}
+	return n, err
+}
@@ -0,0 +1,270 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+)
+
+var (
+	testUploaderCreds = adapters.SigV4Credentials{AccessKeyID: "AKIDUPLOADER", SecretAccessKey: "uploader-secret"}
+	testReaderCreds   = adapters.SigV4Credentials{AccessKeyID: "AKIDREADER", SecretAccessKey: "reader-secret"}
+)
+
+// newPostPolicyTestServer builds a server accepting form uploads signed by
+// an uploader key, which may write, and a reader key, which may not.
+func newPostPolicyTestServer(t *testing.T) *gin.Engine {
+	t.Helper()
+	storage := memory.New()
+	initTestFacade(t, storage)
+
+	config := DefaultServerConfig()
+	config.Mode = gin.TestMode
+	config.Authenticator = readerOnlyAuthenticator{}
+	config.Authorizer = adapters.NewRBACAuthorizer(map[string][]string{
+		"uploader": {adapters.ActionWrite},
+		"reader":   {adapters.ActionRead},
+	})
+	config.PostPolicyAuthenticator = adapters.NewStaticSigV4Authenticator(map[string]adapters.SigV4Key{
+		testUploaderCreds.AccessKeyID: {SecretAccessKey: testUploaderCreds.SecretAccessKey, Principal: adapters.Principal{ID: "uploader", Roles: []string{"uploader"}}},
+		testReaderCreds.AccessKeyID:   {SecretAccessKey: testReaderCreds.SecretAccessKey, Principal: adapters.Principal{ID: "reader", Roles: []string{"reader"}}},
+	})
+	server, err := NewServer(storage, config)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	return server.Router()
+}
+
+// signTestUpload signs a policy allowing uploads under uploads/ of up to
+// 16 bytes, with any x-amz-meta-tag.
+func signTestUpload(t *testing.T, creds adapters.SigV4Credentials, extra ...adapters.PostPolicyCondition) map[string]string {
+	t.Helper()
+	policy := adapters.PostPolicy{
+		Expiration: time.Now().Add(time.Hour),
+		Conditions: append([]adapters.PostPolicyCondition{
+			adapters.PostPolicyPrefix("key", "uploads/"),
+			adapters.PostPolicyPrefix("x-amz-meta-tag", ""),
+			adapters.PostPolicySize(1, 16),
+		}, extra...),
+	}
+	fields, err := adapters.SignPostPolicy(policy, creds, "us-east-1", "s3", time.Now())
+	if err != nil {
+		t.Fatalf("SignPostPolicy: %v", err)
+	}
+	return fields
+}
+
+// doFormUpload posts fields followed by a file part named filename.
+func doFormUpload(router *gin.Engine, fields map[string]string, filename, content string) *httptest.ResponseRecorder {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	for name, value := range fields {
+		_ = form.WriteField(name, value)
+	}
+	file, _ := form.CreateFormFile("file", filename)
+	_, _ = io.WriteString(file, content)
+	_ = form.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/upload", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestPostPolicyUploadDisabledByDefault(t *testing.T) {
+	config := DefaultServerConfig()
+	config.Mode = gin.TestMode
+	router := newRESTServer(t, config).Router()
+
+	w := doFormUpload(router, map[string]string{"key": "a.txt"}, "a.txt", "data")
+	if w.Code != http.StatusNotFound {
+		t.Errorf("POST /upload without PostPolicyAuthenticator = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestPostPolicyUpload(t *testing.T) {
+	router := newPostPolicyTestServer(t)
+
+	fields := signTestUpload(t, testUploaderCreds)
+	fields["key"] = "uploads/${filename}"
+	fields["x-amz-meta-tag"] = "avatar"
+	w := doFormUpload(router, fields, "cat.txt", "meow")
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("upload = %d (%s), want %d", w.Code, w.Body.String(), http.StatusNoContent)
+	}
+	if w.Header().Get("ETag") == "" {
+		t.Error("upload response has no ETag")
+	}
+
+	ctx := context.Background()
+	reader, err := objstore.GetWithContext(ctx, "uploads/cat.txt")
+	if err != nil {
+		t.Fatalf("Get uploaded object: %v", err)
+	}
+	data, _ := io.ReadAll(reader)
+	_ = reader.Close()
+	if string(data) != "meow" {
+		t.Errorf("uploaded content = %q, want meow", data)
+	}
+	metadata, err := objstore.GetMetadata(ctx, "uploads/cat.txt")
+	if err != nil {
+		t.Fatalf("GetMetadata: %v", err)
+	}
+	if metadata.Custom["tag"] != "avatar" {
+		t.Errorf("custom metadata = %v, want tag=avatar", metadata.Custom)
+	}
+	if metadata.ContentType != "application/octet-stream" {
+		t.Errorf("content type = %q, want the file part's", metadata.ContentType)
+	}
+}
+
+func TestPostPolicyUploadAWSSDK(t *testing.T) {
+	router := newPostPolicyTestServer(t)
+
+	client := s3.New(s3.Options{
+		Region: "us-east-1",
+		Credentials: aws.NewCredentialsCache(aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+			return aws.Credentials{AccessKeyID: testUploaderCreds.AccessKeyID, SecretAccessKey: testUploaderCreds.SecretAccessKey}, nil
+		})),
+	})
+	presigned, err := s3.NewPresignClient(client).PresignPostObject(context.Background(), &s3.PutObjectInput{
+		Bucket: aws.String("objstore"),
+		Key:    aws.String("uploads/report.csv"),
+	}, func(o *s3.PresignPostOptions) {
+		o.Conditions = []any{map[string]string{"success_action_status": "201"}}
+	})
+	if err != nil {
+		t.Fatalf("PresignPostObject: %v", err)
+	}
+
+	fields := map[string]string{"success_action_status": "201"}
+	for name, value := range presigned.Values {
+		fields[name] = value
+	}
+	w := doFormUpload(router, fields, "report.csv", "a,b\n1,2\n")
+	if w.Code != http.StatusCreated {
+		t.Fatalf("upload = %d (%s), want %d", w.Code, w.Body.String(), http.StatusCreated)
+	}
+	var resp SuccessResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if data, _ := resp.Data.(map[string]any); data[keyField] != "uploads/report.csv" {
+		t.Errorf("response data = %v, want key uploads/report.csv", resp.Data)
+	}
+}
+
+func TestPostPolicyUploadRedirect(t *testing.T) {
+	router := newPostPolicyTestServer(t)
+
+	fields := signTestUpload(t, testUploaderCreds, adapters.PostPolicyPrefix("success_action_redirect", "https://app.example.com/"))
+	fields["key"] = "uploads/a.txt"
+	fields["success_action_redirect"] = "https://app.example.com/done?step=2"
+	w := doFormUpload(router, fields, "a.txt", "data")
+	if w.Code != http.StatusSeeOther {
+		t.Fatalf("upload = %d (%s), want %d", w.Code, w.Body.String(), http.StatusSeeOther)
+	}
+	location, err := url.Parse(w.Header().Get("Location"))
+	if err != nil || location.Host != "app.example.com" {
+		t.Fatalf("Location = %q", w.Header().Get("Location"))
+	}
+	if query := location.Query(); query.Get("key") != "uploads/a.txt" || query.Get("step") != "2" || query.Get("etag") == "" {
+		t.Errorf("redirect query = %v", query)
+	}
+}
+
+func TestPostPolicyUploadRejects(t *testing.T) {
+	router := newPostPolicyTestServer(t)
+
+	tests := []struct {
+		name    string
+		fields  func() map[string]string
+		content string
+		want    int
+	}{
+		{"unsigned", func() map[string]string {
+			return map[string]string{"key": "uploads/a.txt"}
+		}, "data", http.StatusUnauthorized},
+		{"bad signature", func() map[string]string {
+			f := signTestUpload(t, testUploaderCreds)
+			f["key"] = "uploads/a.txt"
+			f["x-amz-signature"] = strings.Repeat("0", 64)
+			return f
+		}, "data", http.StatusUnauthorized},
+		{"key outside prefix", func() map[string]string {
+			f := signTestUpload(t, testUploaderCreds)
+			f["key"] = "private/a.txt"
+			return f
+		}, "data", http.StatusForbidden},
+		{"unsigned field", func() map[string]string {
+			f := signTestUpload(t, testUploaderCreds)
+			f["key"] = "uploads/a.txt"
+			f["x-amz-meta-owner"] = "mallory"
+			return f
+		}, "data", http.StatusForbidden},
+		{"signer cannot write", func() map[string]string {
+			f := signTestUpload(t, testReaderCreds)
+			f["key"] = "uploads/a.txt"
+			return f
+		}, "data", http.StatusForbidden},
+		{"too large", func() map[string]string {
+			f := signTestUpload(t, testUploaderCreds)
+			f["key"] = "uploads/a.txt"
+			return f
+		}, strings.Repeat("x", 17), http.StatusBadRequest},
+		{"too small", func() map[string]string {
+			f := signTestUpload(t, testUploaderCreds)
+			f["key"] = "uploads/a.txt"
+			return f
+		}, "", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doFormUpload(router, tt.fields(), "a.txt", tt.content)
+			if w.Code != tt.want {
+				t.Errorf("upload = %d (%s), want %d", w.Code, w.Body.String(), tt.want)
+			}
+		})
+	}
+	if _, err := objstore.GetMetadata(context.Background(), "uploads/a.txt"); err == nil {
+		t.Error("rejected upload was stored")
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader(`{"key":"uploads/a.txt"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("JSON upload = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
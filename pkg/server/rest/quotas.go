@@ -0,0 +1,151 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"errors"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+	"github.com/jeremyhahn/go-objstore/pkg/overlay"
+)
+
+// quotasPath is the prefix of the quota API. Quota routes are authorized
+// as admin actions on adapters.ResourceQuota.
+const quotasPath = "/api/v1/quotas"
+
+// prefixField is the route parameter holding a key prefix.
+const prefixField = "prefix"
+
+// SetQuotaRequest limits the data stored under a prefix. Zero fields are
+// unlimited.
+type SetQuotaRequest struct {
+	MaxBytes   int64 `json:"max_bytes,omitempty" example:"1073741824"`
+	MaxObjects int64 `json:"max_objects,omitempty" example:"10000"`
+} // @name SetQuotaRequest
+
+// QuotaResponse is the quota of a prefix
+type QuotaResponse struct {
+	Prefix     string `json:"prefix" example:"tenants/acme/"`
+	MaxBytes   int64  `json:"max_bytes,omitempty" example:"1073741824"`
+	MaxObjects int64  `json:"max_objects,omitempty" example:"10000"`
+} // @name Quota
+
+// QuotasResponse lists quotas
+type QuotasResponse struct {
+	Quotas []QuotaResponse `json:"quotas"`
+	Count  int             `json:"count" example:"1"`
+} // @name QuotaList
+
+// ListQuotas lists the quotas of the default backend, by prefix
+func (h *Handler) ListQuotas(c *gin.Context) {
+	overlays, err := objstore.Overlays(h.backend)
+	if err != nil {
+		respondWithQuotaError(c, err)
+		return
+	}
+
+	quotas := overlays.Quotas()
+	response := QuotasResponse{
+		Quotas: make([]QuotaResponse, 0, len(quotas)),
+		Count:  len(quotas),
+	}
+	for _, prefix := range slices.Sorted(maps.Keys(quotas)) {
+		response.Quotas = append(response.Quotas, quotaResponse(prefix, quotas[prefix]))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetQuota returns the quota of a prefix
+func (h *Handler) GetQuota(c *gin.Context) {
+	prefix := strings.TrimPrefix(c.Param(prefixField), "/")
+	overlays, err := objstore.Overlays(h.backend)
+	if err != nil {
+		respondWithQuotaError(c, err)
+		return
+	}
+
+	quota, ok := overlays.Quotas()[prefix]
+	if !ok {
+		RespondWithError(c, http.StatusNotFound, "no quota for prefix "+prefix)
+		return
+	}
+	c.JSON(http.StatusOK, quotaResponse(prefix, quota))
+}
+
+// SetQuota sets the quota of a prefix until the server restarts
+func (h *Handler) SetQuota(c *gin.Context) {
+	prefix := strings.TrimPrefix(c.Param(prefixField), "/")
+	var body SetQuotaRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+
+	overlays, err := objstore.Overlays(h.backend)
+	if err != nil {
+		respondWithQuotaError(c, err)
+		return
+	}
+
+	quota := overlay.Quota{MaxBytes: body.MaxBytes, MaxObjects: body.MaxObjects}
+	if err := overlays.SetQuota(prefix, &quota); err != nil {
+		respondWithQuotaError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, quotaResponse(prefix, quota))
+}
+
+// RemoveQuota removes the quota of a prefix
+func (h *Handler) RemoveQuota(c *gin.Context) {
+	prefix := strings.TrimPrefix(c.Param(prefixField), "/")
+	overlays, err := objstore.Overlays(h.backend)
+	if err != nil {
+		respondWithQuotaError(c, err)
+		return
+	}
+
+	if err := overlays.SetQuota(prefix, nil); err != nil {
+		respondWithQuotaError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// isQuotasPath reports whether path belongs to the quota API.
+func isQuotasPath(path string) bool {
+	return path == quotasPath || strings.HasPrefix(path, quotasPath+"/")
+}
+
+func respondWithQuotaError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, objstore.ErrOverlaysNotEnabled):
+		RespondWithError(c, http.StatusNotImplemented, "quotas need overlays enabled on this server")
+	case errors.Is(err, overlay.ErrInvalidConfig):
+		RespondWithError(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, common.ErrNotFound):
+		RespondWithError(c, http.StatusNotFound, err.Error())
+	default:
+		RespondWithBackendError(c, err)
+	}
+}
+
+func quotaResponse(prefix string, quota overlay.Quota) QuotaResponse {
+	return QuotaResponse{Prefix: prefix, MaxBytes: quota.MaxBytes, MaxObjects: quota.MaxObjects}
+}
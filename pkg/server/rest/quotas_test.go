@@ -0,0 +1,123 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+	"github.com/jeremyhahn/go-objstore/pkg/overlay"
+)
+
+// adminAuthorizer allows admin actions only to the "admin" principal.
+type adminAuthorizer struct{}
+
+func (adminAuthorizer) Authorize(_ context.Context, p *adapters.Principal, action, _ string) error {
+	if action == adapters.ActionAdmin && p.ID != "admin" {
+		return errors.New("denied")
+	}
+	return nil
+}
+
+// newManagementTestServer builds a server over a memory backend after
+// enable has configured the facade; every bearer token authenticates as
+// the user it names, and only "admin" may manage the server. opts adjust
+// the configuration.
+func newManagementTestServer(t *testing.T, enable func() error, opts ...ServerOption) *gin.Engine {
+	t.Helper()
+	storage := memory.New()
+	initTestFacade(t, storage)
+	if enable != nil {
+		if err := enable(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config := DefaultServerConfig()
+	config.Mode = gin.TestMode
+	config.Authenticator = adapters.NewBearerTokenAuthenticator(func(_ context.Context, token string) (*adapters.Principal, error) {
+		return &adapters.Principal{ID: token, Name: token}, nil
+	})
+	config.Authorizer = adminAuthorizer{}
+	server, err := NewServer(storage, config, opts...)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	return server.Router()
+}
+
+func TestQuotaEndpoints(t *testing.T) {
+	router := newManagementTestServer(t, func() error {
+		return objstore.EnableOverlays("", &overlay.Config{})
+	})
+
+	w := doBearerRequest(router, http.MethodPut, "/api/v1/quotas/tenants/acme/", "admin", `{"max_objects":1}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("set = %d %s", w.Code, w.Body.String())
+	}
+	var quota QuotaResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &quota); err != nil || quota.Prefix != "tenants/acme/" || quota.MaxObjects != 1 {
+		t.Errorf("quota = %+v, %v", quota, err)
+	}
+
+	if w := doBearerRequest(router, http.MethodPut, "/api/v1/objects/tenants/acme/a", "alice", "a"); w.Code != http.StatusCreated {
+		t.Fatalf("put within quota = %d %s", w.Code, w.Body.String())
+	}
+	if w := doBearerRequest(router, http.MethodPut, "/api/v1/objects/tenants/acme/b", "alice", "b"); w.Code != http.StatusTooManyRequests {
+		t.Errorf("put over quota = %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+
+	w = doBearerRequest(router, http.MethodGet, "/api/v1/quotas", "admin", "")
+	var list QuotasResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil || list.Count != 1 || list.Quotas[0].Prefix != "tenants/acme/" {
+		t.Errorf("list = %d %s", w.Code, w.Body.String())
+	}
+	if w := doBearerRequest(router, http.MethodGet, "/api/v1/quotas/tenants/acme/", "admin", ""); w.Code != http.StatusOK {
+		t.Errorf("get = %d %s", w.Code, w.Body.String())
+	}
+	if w := doBearerRequest(router, http.MethodGet, "/api/v1/quotas", "alice", ""); w.Code != http.StatusForbidden {
+		t.Errorf("list as non-admin = %d, want %d", w.Code, http.StatusForbidden)
+	}
+	if w := doBearerRequest(router, http.MethodPut, "/api/v1/quotas/x/", "admin", `{"max_bytes":-1}`); w.Code != http.StatusBadRequest {
+		t.Errorf("negative quota = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	if w := doBearerRequest(router, http.MethodDelete, "/api/v1/quotas/tenants/acme/", "admin", ""); w.Code != http.StatusNoContent {
+		t.Errorf("delete = %d %s", w.Code, w.Body.String())
+	}
+	for _, method := range []string{http.MethodGet, http.MethodDelete} {
+		if w := doBearerRequest(router, method, "/api/v1/quotas/tenants/acme/", "admin", ""); w.Code != http.StatusNotFound {
+			t.Errorf("%s removed quota = %d, want %d", method, w.Code, http.StatusNotFound)
+		}
+	}
+	if w := doBearerRequest(router, http.MethodPut, "/api/v1/objects/tenants/acme/b", "alice", "b"); w.Code != http.StatusCreated {
+		t.Errorf("put after removing quota = %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestQuotaEndpointsNotEnabled(t *testing.T) {
+	router := newManagementTestServer(t, nil)
+	w := doBearerRequest(router, http.MethodGet, "/api/v1/quotas", "admin", "")
+	if w.Code != http.StatusNotImplemented || !strings.Contains(w.Body.String(), "overlays") {
+		t.Errorf("list = %d %s, want %d", w.Code, w.Body.String(), http.StatusNotImplemented)
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+)
+
+// renamePath is the prefix rename API. It is authorized as a delete of the
+// prefix query parameter and, by AuthorizationMiddleware, a write of
+// new_prefix.
+const renamePath = "/api/v1/rename"
+
+// maxRenameConcurrency bounds the parallel copies a request may ask for.
+const maxRenameConcurrency = 64
+
+// isRenamePath reports whether path is the prefix rename API.
+func isRenamePath(path string) bool {
+	return path == renamePath
+}
+
+// RenamePrefix moves every object under the prefix query parameter to the
+// same key under new_prefix. With dry_run=true it only reports what would move;
+// overwrite=true replaces objects already at a new key and concurrency sets
+// the number of objects moved in parallel.
+func (h *Handler) RenamePrefix(c *gin.Context) {
+	from, to := c.Query("prefix"), c.Query("new_prefix")
+	if from == "" || to == "" {
+		RespondWithError(c, http.StatusBadRequest, "prefix and new_prefix query parameters are required")
+		return
+	}
+	opts := &common.RenameOptions{}
+	for name, field := range map[string]*bool{"dry_run": &opts.DryRun, "overwrite": &opts.Overwrite} {
+		if value := c.Query(name); value != "" {
+			var err error
+			if *field, err = strconv.ParseBool(value); err != nil {
+				RespondWithError(c, http.StatusBadRequest, "invalid "+name+" parameter")
+				return
+			}
+		}
+	}
+	if value := c.Query("concurrency"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > maxRenameConcurrency {
+			RespondWithError(c, http.StatusBadRequest, "concurrency must be between 1 and "+strconv.Itoa(maxRenameConcurrency))
+			return
+		}
+		opts.Concurrency = n
+	}
+
+	result, err := objstore.RenamePrefix(c.Request.Context(), h.backend, from, to, opts)
+	if err != nil {
+		RespondWithBackendError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
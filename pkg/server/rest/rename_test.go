@@ -0,0 +1,95 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+// lockedAuthorizer lets everyone do anything except write keys under
+// "locked/".
+type lockedAuthorizer struct{}
+
+func (lockedAuthorizer) Authorize(_ context.Context, _ *adapters.Principal, action, resource string) error {
+	if action == adapters.ActionWrite && strings.HasPrefix(resource, "locked/") {
+		return errors.New("denied")
+	}
+	return nil
+}
+
+func TestRenamePrefixEndpoint(t *testing.T) {
+	storage := memory.New()
+	for _, key := range []string{"logs/a", "logs/b"} {
+		if err := storage.Put(key, strings.NewReader(key)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	initTestFacade(t, storage)
+	config := DefaultServerConfig()
+	config.Mode = gin.TestMode
+	config.Authenticator = adapters.NewBearerTokenAuthenticator(func(_ context.Context, token string) (*adapters.Principal, error) {
+		return &adapters.Principal{ID: token}, nil
+	})
+	config.Authorizer = lockedAuthorizer{}
+	server, err := NewServer(storage, config)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	router := server.Router()
+
+	w := doBearerRequest(router, http.MethodPost, "/api/v1/rename?prefix=logs/&new_prefix=archive/logs/&dry_run=true", "alice", "")
+	var result common.RenameResult
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil || w.Code != http.StatusOK || result.Moved != 2 || !result.DryRun {
+		t.Fatalf("dry run = %d %s", w.Code, w.Body.String())
+	}
+	if exists, _ := storage.Exists(context.Background(), "archive/logs/a"); exists {
+		t.Error("dry run moved an object")
+	}
+
+	w = doBearerRequest(router, http.MethodPost, "/api/v1/rename?prefix=logs/&new_prefix=archive/logs/&concurrency=2", "alice", "")
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil || w.Code != http.StatusOK || result.Moved != 2 {
+		t.Fatalf("rename = %d %s", w.Code, w.Body.String())
+	}
+	if exists, _ := storage.Exists(context.Background(), "archive/logs/b"); !exists {
+		t.Error("archive/logs/b does not exist after the rename")
+	}
+
+	tests := []struct {
+		name, query string
+		want        int
+	}{
+		{"unwritable destination", "prefix=archive/&new_prefix=locked/", http.StatusForbidden},
+		{"missing destination", "prefix=archive/", http.StatusBadRequest},
+		{"overlapping prefixes", "prefix=archive/&new_prefix=archive/old/", http.StatusBadRequest},
+		{"invalid concurrency", "prefix=archive/&new_prefix=b/&concurrency=1000", http.StatusBadRequest},
+		{"invalid dry_run", "prefix=archive/&new_prefix=b/&dry_run=maybe", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := doBearerRequest(router, http.MethodPost, "/api/v1/rename?"+tt.query, "alice", ""); w.Code != tt.want {
+				t.Errorf("POST /rename?%s = %d, want %d: %s", tt.query, w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
@@ -19,15 +19,14 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/jeremyhahn/go-objstore/pkg/common"
 	"github.com/jeremyhahn/go-objstore/pkg/replication"
+	"github.com/jeremyhahn/go-objstore/pkg/search"
 	servererrors "github.com/jeremyhahn/go-objstore/pkg/server/errors"
+	"github.com/jeremyhahn/go-objstore/pkg/server/middleware"
 )
 
-// ErrorResponse represents a standard error response
-type ErrorResponse struct {
-	Error   string `json:"error" example:"error message"`
-	Code    int    `json:"code" example:"400"`
-	Message string `json:"message,omitempty" example:"detailed error description"`
-} // @name ErrorResponse
+// ErrorResponse represents a standard error response: the JSON error body
+// shared with the QUIC transport, with a machine-readable code.
+type ErrorResponse = servererrors.ErrorBody
 
 // SuccessResponse represents a standard success response
 type SuccessResponse struct {
@@ -37,12 +36,13 @@ type SuccessResponse struct {
 
 // ObjectResponse represents an object metadata response
 type ObjectResponse struct {
-	Key         string            `json:"key" example:"path/to/object.txt"`
-	Size        int64             `json:"size" example:"1024"`
-	Modified    string            `json:"modified,omitempty" example:"2025-11-05T10:00:00Z"`
-	ETag        string            `json:"etag,omitempty" example:"d41d8cd98f00b204e9800998ecf8427e"`
-	ContentType string            `json:"content_type,omitempty" example:"text/plain"`
-	Metadata    map[string]string `json:"metadata,omitempty"`
+	Key          string            `json:"key" example:"path/to/object.txt"`
+	Size         int64             `json:"size" example:"1024"`
+	Modified     string            `json:"modified,omitempty" example:"2025-11-05T10:00:00Z"`
+	ETag         string            `json:"etag,omitempty" example:"d41d8cd98f00b204e9800998ecf8427e"`
+	ContentType  string            `json:"content_type,omitempty" example:"text/plain"`
+	StorageClass string            `json:"storage_class,omitempty" example:"STANDARD_IA"`
+	Metadata     map[string]string `json:"metadata,omitempty"`
 } // @name ObjectResponse
 
 // ListObjectsResponse represents a paginated list of objects
@@ -53,6 +53,22 @@ type ListObjectsResponse struct {
 	Truncated      bool             `json:"truncated" example:"false"`
 } // @name ListObjectsResponse
 
+// BrowseResponse represents the folders and objects directly under a prefix
+type BrowseResponse struct {
+	Prefix       string           `json:"prefix" example:"logs/"`
+	Folders      []common.Folder  `json:"folders"`
+	Objects      []ObjectResponse `json:"objects"`
+	TotalObjects int64            `json:"total_objects" example:"42"`
+	TotalBytes   int64            `json:"total_bytes" example:"1048576"`
+} // @name BrowseResponse
+
+// SearchResponse represents the objects matching a search query
+type SearchResponse struct {
+	Query   string           `json:"query" example:"author:alice"`
+	Objects []ObjectResponse `json:"objects"`
+	Count   int              `json:"count" example:"1"`
+} // @name SearchResponse
+
 // HealthResponse represents the health check response
 type HealthResponse struct {
 	Status  string `json:"status" example:"healthy"`
@@ -64,6 +80,7 @@ type ArchiveRequest struct {
 	Key                 string            `json:"key" binding:"required" example:"path/to/object.txt"`
 	DestinationType     string            `json:"destination_type" binding:"required" example:"s3"`
 	DestinationSettings map[string]string `json:"destination_settings,omitempty"`
+	Async               bool              `json:"async,omitempty"`
 } // @name ArchiveRequest
 
 // AddPolicyRequest represents a request to add a lifecycle policy
@@ -74,6 +91,7 @@ type AddPolicyRequest struct {
 	Action              string            `json:"action" binding:"required" example:"delete"`
 	DestinationType     string            `json:"destination_type,omitempty" example:"s3"`
 	DestinationSettings map[string]string `json:"destination_settings,omitempty"`
+	StorageClass        string            `json:"storage_class,omitempty" example:"STANDARD_IA"`
 } // @name AddPolicyRequest
 
 // PolicyResponse represents a lifecycle policy response
@@ -83,6 +101,7 @@ type PolicyResponse struct {
 	RetentionSeconds int64  `json:"retention_seconds" example:"2592000"`
 	Action           string `json:"action" example:"delete"`
 	DestinationType  string `json:"destination_type,omitempty" example:"s3"`
+	StorageClass     string `json:"storage_class,omitempty" example:"STANDARD_IA"`
 } // @name PolicyResponse
 
 // GetPoliciesResponse represents a list of lifecycle policies
@@ -103,6 +122,7 @@ type AddReplicationPolicyRequest struct {
 	Enabled              bool                     `json:"enabled" example:"true"`
 	ReplicationMode      common.ReplicationMode   `json:"replication_mode,omitempty" example:"transparent"`
 	Encryption           *common.EncryptionPolicy `json:"encryption,omitempty"`
+	WriteOnce            bool                     `json:"write_once,omitempty" example:"false"`
 } // @name AddReplicationPolicyRequest
 
 // ReplicationPolicyResponse represents a replication policy response
@@ -118,6 +138,7 @@ type ReplicationPolicyResponse struct {
 	Enabled              bool                     `json:"enabled" example:"true"`
 	ReplicationMode      common.ReplicationMode   `json:"replication_mode" example:"transparent"`
 	Encryption           *common.EncryptionPolicy `json:"encryption,omitempty"`
+	WriteOnce            bool                     `json:"write_once,omitempty" example:"false"`
 } // @name ReplicationPolicyResponse
 
 // GetReplicationPoliciesResponse represents a list of replication policies
@@ -163,17 +184,19 @@ type ReplicationStatusResponse struct {
 // taxonomy (common.Classify) and sends the matching HTTP status, so REST
 // reports the same class of failure as gRPC and the JSON-RPC transports.
 func RespondWithBackendError(c *gin.Context, err error) {
-	code, message := servererrors.HTTPStatus(err)
-	RespondWithError(c, code, message)
+	respondWithErrorBody(c, servererrors.ErrorBodyFor(err))
 }
 
-// RespondWithError sends a standard error response
+// RespondWithError sends a standard error response, with the error code the
+// status stands for
 func RespondWithError(c *gin.Context, code int, message string) {
-	c.JSON(code, ErrorResponse{
-		Error:   http.StatusText(code),
-		Code:    code,
-		Message: message,
-	})
+	respondWithErrorBody(c, servererrors.NewErrorBody(code, message))
+}
+
+// respondWithErrorBody sends body, tagged with the request ID
+func respondWithErrorBody(c *gin.Context, body *ErrorResponse) {
+	body.RequestID = middleware.GetRequestIDFromGinContext(c)
+	c.JSON(body.Status, body)
 }
 
 // RespondWithSuccess sends a standard success response
@@ -195,10 +218,11 @@ func RespondWithObject(c *gin.Context, key string, metadata *common.Metadata) {
 	}
 
 	response := ObjectResponse{
-		Key:         key,
-		Size:        metadata.Size,
-		ETag:        metadata.ETag,
-		ContentType: metadata.ContentType,
+		Key:          key,
+		Size:         metadata.Size,
+		ETag:         metadata.ETag,
+		ContentType:  metadata.ContentType,
+		StorageClass: metadata.StorageClass,
 	}
 
 	if !metadata.LastModified.IsZero() {
@@ -222,18 +246,71 @@ func RespondWithListObjects(c *gin.Context, result *common.ListResult) {
 	}
 
 	for _, obj := range result.Objects {
+		response.Objects = append(response.Objects, objectResponse(obj))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RespondWithBrowse sends the content of a virtual directory
+func RespondWithBrowse(c *gin.Context, result *common.BrowseResult) {
+	response := BrowseResponse{
+		Prefix:       result.Prefix,
+		Folders:      result.Folders,
+		Objects:      make([]ObjectResponse, 0, len(result.Objects)),
+		TotalObjects: result.TotalObjects,
+		TotalBytes:   result.TotalBytes,
+	}
+	for _, obj := range result.Objects {
+		response.Objects = append(response.Objects, objectResponse(obj))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// objectResponse flattens a listed object and its metadata
+func objectResponse(obj *common.ObjectInfo) ObjectResponse {
+	objResp := ObjectResponse{Key: obj.Key}
+	if obj.Metadata == nil {
+		return objResp
+	}
+	objResp.Size = obj.Metadata.Size
+	objResp.ETag = obj.Metadata.ETag
+	objResp.ContentType = obj.Metadata.ContentType
+	objResp.StorageClass = obj.Metadata.StorageClass
+
+	if !obj.Metadata.LastModified.IsZero() {
+		objResp.Modified = obj.Metadata.LastModified.Format("2006-01-02T15:04:05Z07:00")
+	}
+
+	if len(obj.Metadata.Custom) > 0 {
+		objResp.Metadata = obj.Metadata.Custom
+	}
+	return objResp
+}
+
+// RespondWithSearchResults sends a search results response
+func RespondWithSearchResults(c *gin.Context, query string, docs []search.Document) {
+	response := SearchResponse{
+		Query:   query,
+		Objects: make([]ObjectResponse, 0, len(docs)),
+		Count:   len(docs),
+	}
+
+	for _, doc := range docs {
 		objResp := ObjectResponse{
-			Key:  obj.Key,
-			Size: obj.Metadata.Size,
-			ETag: obj.Metadata.ETag,
+			Key:         doc.Key,
+			Size:        doc.Size,
+			ETag:        doc.ETag,
+			ContentType: doc.ContentType,
 		}
 
-		if !obj.Metadata.LastModified.IsZero() {
-			objResp.Modified = obj.Metadata.LastModified.Format("2006-01-02T15:04:05Z07:00")
+		if !doc.LastModified.IsZero() {
+			objResp.Modified = doc.LastModified.Format("2006-01-02T15:04:05Z07:00")
 		}
 
-		if len(obj.Metadata.Custom) > 0 {
-			objResp.Metadata = obj.Metadata.Custom
+		if len(doc.Custom) > 0 {
+			objResp.Metadata = doc.Custom
 		}
 
 		response.Objects = append(response.Objects, objResp)
@@ -255,6 +332,7 @@ func RespondWithPolicies(c *gin.Context, policies []common.LifecyclePolicy) {
 			Prefix:           policy.Prefix,
 			RetentionSeconds: int64(policy.Retention.Seconds()),
 			Action:           policy.Action,
+			StorageClass:     policy.StorageClass,
 		}
 
 		response.Policies = append(response.Policies, policyResp)
@@ -282,6 +360,7 @@ func RespondWithReplicationPolicies(c *gin.Context, policies []common.Replicatio
 			Enabled:              policy.Enabled,
 			ReplicationMode:      policy.ReplicationMode,
 			Encryption:           policy.Encryption,
+			WriteOnce:            policy.WriteOnce,
 		}
 
 		if !policy.LastSyncTime.IsZero() {
@@ -312,6 +391,7 @@ func RespondWithReplicationPolicy(c *gin.Context, policy *common.ReplicationPoli
 		Enabled:              policy.Enabled,
 		ReplicationMode:      policy.ReplicationMode,
 		Encryption:           policy.Encryption,
+		WriteOnce:            policy.WriteOnce,
 	}
 
 	if !policy.LastSyncTime.IsZero() {
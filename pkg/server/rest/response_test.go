@@ -14,13 +14,16 @@
 package rest
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/server/middleware"
 )
 
 func init() {
@@ -80,6 +83,40 @@ func TestRespondWithError(t *testing.T) {
 	}
 }
 
+func TestRespondWithErrorBody(t *testing.T) {
+	router := gin.New()
+	router.Use(middleware.RequestIDMiddleware())
+	router.GET("/missing", func(c *gin.Context) {
+		RespondWithBackendError(c, common.ErrKeyNotFound)
+	})
+	router.GET("/busy", func(c *gin.Context) {
+		RespondWithError(c, http.StatusServiceUnavailable, "try again later")
+	})
+
+	tests := []struct {
+		path string
+		want ErrorResponse
+	}{
+		{"/missing", ErrorResponse{Error: "Not Found", Code: "NOT_FOUND", Status: http.StatusNotFound, Message: "object not found"}},
+		{"/busy", ErrorResponse{Error: "Service Unavailable", Code: "UNAVAILABLE", Status: http.StatusServiceUnavailable, Message: "try again later", Retryable: true}},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, http.NoBody)
+		req.Header.Set(middleware.RequestIDHeader, "req-42")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var got ErrorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+			t.Fatalf("GET %s body %q: %v", tt.path, w.Body.String(), err)
+		}
+		tt.want.RequestID = "req-42"
+		if w.Code != tt.want.Status || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("GET %s = %d %+v, want %+v", tt.path, w.Code, got, tt.want)
+		}
+	}
+}
+
 func TestRespondWithSuccess(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -0,0 +1,264 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+	"github.com/jeremyhahn/go-objstore/pkg/retention"
+)
+
+// retentionPrefixes are the paths of the legal hold and deletion approval
+// API, authorized as admin actions on adapters.ResourceRetention.
+var retentionPrefixes = []string{"/api/v1/deletions", "/api/v1/holds"}
+
+// DeletionRequestResponse is a pending or decided deletion of a protected object
+type DeletionRequestResponse struct {
+	ID          string `json:"id" example:"4c1d2f0e-8a8b-4d7e-9d55-2f5b0c6f1a3e"`
+	Key         string `json:"key" example:"records/2025/ledger.csv"`
+	Status      string `json:"status" example:"pending"`
+	RequestedBy string `json:"requested_by,omitempty" example:"alice"`
+	RequestedAt string `json:"requested_at" example:"2025-11-05T10:00:00Z"`
+	DecidedBy   string `json:"decided_by,omitempty" example:"bob"`
+	DecidedAt   string `json:"decided_at,omitempty" example:"2025-11-05T11:00:00Z"`
+	Reason      string `json:"reason,omitempty" example:"retention period not over"`
+} // @name DeletionRequest
+
+// DeletionRequestsResponse lists deletion requests
+type DeletionRequestsResponse struct {
+	Requests []DeletionRequestResponse `json:"requests"`
+	Count    int                       `json:"count" example:"1"`
+} // @name DeletionRequestList
+
+// RejectDeletionRequest is the optional body of a rejection
+type RejectDeletionRequest struct {
+	Reason string `json:"reason,omitempty" example:"retention period not over"`
+} // @name RejectDeletionRequest
+
+// LegalHoldRequest is the optional body when placing a legal hold
+type LegalHoldRequest struct {
+	Reason string `json:"reason,omitempty" example:"litigation 2025-17"`
+} // @name LegalHoldRequest
+
+// LegalHoldResponse is a legal hold on one object
+type LegalHoldResponse struct {
+	Key      string `json:"key" example:"records/2025/ledger.csv"`
+	PlacedBy string `json:"placed_by,omitempty" example:"carol"`
+	PlacedAt string `json:"placed_at" example:"2025-11-05T10:00:00Z"`
+	Reason   string `json:"reason,omitempty" example:"litigation 2025-17"`
+} // @name LegalHold
+
+// LegalHoldsResponse lists legal holds
+type LegalHoldsResponse struct {
+	Holds []LegalHoldResponse `json:"holds"`
+	Count int                 `json:"count" example:"1"`
+} // @name LegalHoldList
+
+// ListDeletionRequests lists deletion requests, optionally filtered by status
+func (h *Handler) ListDeletionRequests(c *gin.Context) {
+	status := retention.Status(c.Query("status"))
+	switch status {
+	case "", retention.StatusPending, retention.StatusApproved, retention.StatusRejected:
+	default:
+		RespondWithError(c, http.StatusBadRequest, "status must be pending, approved or rejected")
+		return
+	}
+
+	manager, err := objstore.Retention(h.backend)
+	if err != nil {
+		respondWithRetentionError(c, err)
+		return
+	}
+
+	requests := manager.Requests(status)
+	response := DeletionRequestsResponse{
+		Requests: make([]DeletionRequestResponse, 0, len(requests)),
+		Count:    len(requests),
+	}
+	for i := range requests {
+		response.Requests = append(response.Requests, deletionRequestResponse(&requests[i]))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetDeletionRequest returns one deletion request
+func (h *Handler) GetDeletionRequest(c *gin.Context) {
+	manager, err := objstore.Retention(h.backend)
+	if err != nil {
+		respondWithRetentionError(c, err)
+		return
+	}
+
+	req, err := manager.Request(c.Param("id"))
+	if err != nil {
+		respondWithRetentionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, deletionRequestResponse(req))
+}
+
+// ApproveDeletionRequest approves a pending deletion and deletes the object
+func (h *Handler) ApproveDeletionRequest(c *gin.Context) {
+	req, err := objstore.ApproveDeletion(c.Request.Context(), h.backend, c.Param("id"))
+	if err != nil {
+		respondWithRetentionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, deletionRequestResponse(req))
+}
+
+// RejectDeletionRequest rejects a pending deletion, keeping the object
+func (h *Handler) RejectDeletionRequest(c *gin.Context) {
+	var body RejectDeletionRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			RespondWithError(c, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+	}
+
+	manager, err := objstore.Retention(h.backend)
+	if err != nil {
+		respondWithRetentionError(c, err)
+		return
+	}
+
+	req, err := manager.Reject(c.Request.Context(), c.Param("id"), body.Reason)
+	if err != nil {
+		respondWithRetentionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, deletionRequestResponse(req))
+}
+
+// ListLegalHolds lists the objects under legal hold
+func (h *Handler) ListLegalHolds(c *gin.Context) {
+	manager, err := objstore.Retention(h.backend)
+	if err != nil {
+		respondWithRetentionError(c, err)
+		return
+	}
+
+	holds := manager.Holds()
+	response := LegalHoldsResponse{
+		Holds: make([]LegalHoldResponse, 0, len(holds)),
+		Count: len(holds),
+	}
+	for i := range holds {
+		response.Holds = append(response.Holds, legalHoldResponse(&holds[i]))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// PlaceLegalHold places a legal hold on an object
+func (h *Handler) PlaceLegalHold(c *gin.Context) {
+	key := strings.TrimLeft(c.Param(keyField), "/")
+	if key == "" {
+		RespondWithError(c, http.StatusBadRequest, "key parameter is required")
+		return
+	}
+
+	var body LegalHoldRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			RespondWithError(c, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+	}
+
+	manager, err := objstore.Retention(h.backend)
+	if err != nil {
+		respondWithRetentionError(c, err)
+		return
+	}
+
+	hold, err := manager.PlaceHold(c.Request.Context(), key, body.Reason)
+	if err != nil {
+		respondWithRetentionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, legalHoldResponse(hold))
+}
+
+// ReleaseLegalHold releases the legal hold on an object
+func (h *Handler) ReleaseLegalHold(c *gin.Context) {
+	key := strings.TrimLeft(c.Param(keyField), "/")
+	if key == "" {
+		RespondWithError(c, http.StatusBadRequest, "key parameter is required")
+		return
+	}
+
+	manager, err := objstore.Retention(h.backend)
+	if err != nil {
+		respondWithRetentionError(c, err)
+		return
+	}
+
+	if err := manager.ReleaseHold(c.Request.Context(), key); err != nil {
+		respondWithRetentionError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// respondWithRetentionError maps retention errors to responses that name
+// the retention resource rather than the generic object messages.
+func respondWithRetentionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, objstore.ErrRetentionNotEnabled):
+		RespondWithError(c, http.StatusNotImplemented, "retention is not enabled on this server")
+	case errors.Is(err, retention.ErrRequestNotFound):
+		RespondWithError(c, http.StatusNotFound, "deletion request not found")
+	case errors.Is(err, retention.ErrHoldNotFound):
+		RespondWithError(c, http.StatusNotFound, "legal hold not found")
+	case errors.Is(err, retention.ErrRequestDecided):
+		RespondWithError(c, http.StatusConflict, "deletion request already decided")
+	case errors.Is(err, retention.ErrSelfApproval):
+		RespondWithError(c, http.StatusForbidden, "deletion must be approved by a different principal")
+	case errors.Is(err, retention.ErrLegalHold):
+		RespondWithError(c, http.StatusForbidden, "object is under legal hold")
+	default:
+		RespondWithBackendError(c, err)
+	}
+}
+
+func deletionRequestResponse(req *retention.DeletionRequest) DeletionRequestResponse {
+	resp := DeletionRequestResponse{
+		ID:          req.ID,
+		Key:         req.Key,
+		Status:      string(req.Status),
+		RequestedBy: req.RequestedBy,
+		RequestedAt: req.RequestedAt.Format(time.RFC3339),
+		DecidedBy:   req.DecidedBy,
+		Reason:      req.Reason,
+	}
+	if req.DecidedAt != nil {
+		resp.DecidedAt = req.DecidedAt.Format(time.RFC3339)
+	}
+	return resp
+}
+
+func legalHoldResponse(hold *retention.Hold) LegalHoldResponse {
+	return LegalHoldResponse{
+		Key:      hold.Key,
+		PlacedBy: hold.PlacedBy,
+		PlacedAt: hold.PlacedAt.Format(time.RFC3339),
+		Reason:   hold.Reason,
+	}
+}
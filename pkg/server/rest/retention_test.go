@@ -0,0 +1,160 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+)
+
+// newRetentionTestServer builds a server over a memory backend with
+// "records/" protected; every bearer token authenticates as the user it
+// names.
+func newRetentionTestServer(t *testing.T, enable bool) *gin.Engine {
+	t.Helper()
+	storage := memory.New()
+	initTestFacade(t, storage)
+	if enable {
+		if err := objstore.EnableRetention("", &objstore.RetentionConfig{ProtectedPrefixes: []string{"records/"}}); err != nil {
+			t.Fatalf("EnableRetention: %v", err)
+		}
+	}
+
+	config := DefaultServerConfig()
+	config.Mode = gin.TestMode
+	config.Authenticator = adapters.NewBearerTokenAuthenticator(func(_ context.Context, token string) (*adapters.Principal, error) {
+		return &adapters.Principal{ID: token, Name: token, Roles: []string{"admin"}}, nil
+	})
+	server, err := NewServer(storage, config)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	return server.Router()
+}
+
+func TestDeleteProtectedObjectRequiresApproval(t *testing.T) {
+	router := newRetentionTestServer(t, true)
+
+	if w := doTokenRequest(router, http.MethodPut, "/api/v1/objects/records/ledger.csv", "alice", "data"); w.Code != http.StatusCreated {
+		t.Fatalf("PUT = %d, want %d (body=%s)", w.Code, http.StatusCreated, w.Body.String())
+	}
+
+	w := doTokenRequest(router, http.MethodDelete, "/api/v1/objects/records/ledger.csv", "alice", "")
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("DELETE protected = %d, want %d (body=%s)", w.Code, http.StatusAccepted, w.Body.String())
+	}
+	var pending DeletionRequestResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &pending); err != nil {
+		t.Fatal(err)
+	}
+	if pending.Status != "pending" || pending.RequestedBy != "alice" || pending.Key != "records/ledger.csv" {
+		t.Errorf("pending request = %+v", pending)
+	}
+	if w := doTokenRequest(router, http.MethodHead, "/api/v1/objects/records/ledger.csv", "alice", ""); w.Code != http.StatusOK {
+		t.Errorf("HEAD after queued delete = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	w = doTokenRequest(router, http.MethodGet, "/api/v1/deletions", "bob", "")
+	var list DeletionRequestsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil || list.Count != 1 || list.Requests[0].ID != pending.ID {
+		t.Errorf("GET /deletions = %d %s", w.Code, w.Body.String())
+	}
+	if w := doTokenRequest(router, http.MethodGet, "/api/v1/deletions?status=bogus", "bob", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("GET /deletions?status=bogus = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	if w := doTokenRequest(router, http.MethodGet, "/api/v1/deletions/missing", "bob", ""); w.Code != http.StatusNotFound {
+		t.Errorf("GET /deletions/missing = %d, want %d", w.Code, http.StatusNotFound)
+	}
+
+	if w := doTokenRequest(router, http.MethodPost, "/api/v1/deletions/"+pending.ID+"/approve", "alice", ""); w.Code != http.StatusForbidden {
+		t.Errorf("self approval = %d, want %d", w.Code, http.StatusForbidden)
+	}
+	if w := doTokenRequest(router, http.MethodPost, "/api/v1/deletions/"+pending.ID+"/approve", "bob", ""); w.Code != http.StatusOK {
+		t.Fatalf("approval = %d, want %d (body=%s)", w.Code, http.StatusOK, w.Body.String())
+	}
+	if w := doTokenRequest(router, http.MethodHead, "/api/v1/objects/records/ledger.csv", "bob", ""); w.Code != http.StatusNotFound {
+		t.Errorf("HEAD after approval = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	if w := doTokenRequest(router, http.MethodPost, "/api/v1/deletions/"+pending.ID+"/reject", "bob", ""); w.Code != http.StatusConflict {
+		t.Errorf("reject decided request = %d, want %d", w.Code, http.StatusConflict)
+	}
+}
+
+func TestLegalHoldEndpoints(t *testing.T) {
+	router := newRetentionTestServer(t, true)
+
+	if w := doTokenRequest(router, http.MethodPut, "/api/v1/objects/scratch/a.txt", "alice", "data"); w.Code != http.StatusCreated {
+		t.Fatalf("PUT = %d, want %d", w.Code, http.StatusCreated)
+	}
+	if w := doTokenRequest(router, http.MethodPut, "/api/v1/holds/scratch/missing.txt", "carol", ""); w.Code != http.StatusNotFound {
+		t.Errorf("hold missing object = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	w := doTokenRequest(router, http.MethodPut, "/api/v1/holds/scratch/a.txt", "carol", `{"reason":"litigation"}`)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"placed_by":"carol"`) {
+		t.Fatalf("place hold = %d %s", w.Code, w.Body.String())
+	}
+
+	if w := doTokenRequest(router, http.MethodDelete, "/api/v1/objects/scratch/a.txt", "alice", ""); w.Code != http.StatusForbidden {
+		t.Errorf("DELETE held object = %d, want %d", w.Code, http.StatusForbidden)
+	}
+	if w := doTokenRequest(router, http.MethodPut, "/api/v1/objects/scratch/a.txt", "alice", "new"); w.Code != http.StatusForbidden {
+		t.Errorf("PUT held object = %d, want %d", w.Code, http.StatusForbidden)
+	}
+
+	w = doTokenRequest(router, http.MethodGet, "/api/v1/holds", "carol", "")
+	var holds LegalHoldsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &holds); err != nil || holds.Count != 1 || holds.Holds[0].Reason != "litigation" {
+		t.Errorf("GET /holds = %d %s", w.Code, w.Body.String())
+	}
+
+	if w := doTokenRequest(router, http.MethodDelete, "/api/v1/holds/scratch/a.txt", "carol", ""); w.Code != http.StatusNoContent {
+		t.Errorf("release hold = %d, want %d", w.Code, http.StatusNoContent)
+	}
+	if w := doTokenRequest(router, http.MethodDelete, "/api/v1/holds/scratch/a.txt", "carol", ""); w.Code != http.StatusNotFound {
+		t.Errorf("release missing hold = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	if w := doTokenRequest(router, http.MethodDelete, "/api/v1/objects/scratch/a.txt", "alice", ""); w.Code != http.StatusNoContent {
+		t.Errorf("DELETE after release = %d, want %d", w.Code, http.StatusNoContent)
+	}
+}
+
+func TestRetentionEndpointsNotEnabled(t *testing.T) {
+	router := newRetentionTestServer(t, false)
+
+	for _, path := range []string{"/api/v1/deletions", "/api/v1/holds"} {
+		if w := doTokenRequest(router, http.MethodGet, path, "alice", ""); w.Code != http.StatusNotImplemented {
+			t.Errorf("GET %s without retention = %d, want %d", path, w.Code, http.StatusNotImplemented)
+		}
+	}
+}
+
+// TestRetentionRequiresAdmin verifies the retention API is authorized as an
+// admin action.
+func TestRetentionRequiresAdmin(t *testing.T) {
+	router := newRESTServerWithRolePermissions(t, map[string][]string{
+		"reader": {adapters.ActionRead, adapters.ActionList, adapters.ActionWrite, adapters.ActionDelete},
+	}).Router()
+
+	if w := doTokenRequest(router, http.MethodGet, "/api/v1/deletions", "", ""); w.Code != http.StatusForbidden {
+		t.Errorf("GET /deletions as non-admin = %d, want %d", w.Code, http.StatusForbidden)
+	}
+}
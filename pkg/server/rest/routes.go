@@ -26,8 +26,14 @@ func SetupRoutes(router *gin.Engine, handler *Handler) {
 	// Health check endpoint (no auth required)
 	router.GET("/health", handler.HealthCheck)
 
-	// Swagger documentation
-	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	// Public share link downloads (no auth required; the token is the
+	// credential)
+	router.GET(sharePath+":token", handler.DownloadShare)
+
+	// OpenAPI specification and the Swagger UI that renders it
+	router.GET("/openapi.json", handler.OpenAPIJSON)
+	router.GET("/openapi.yaml", handler.OpenAPIYAML)
+	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, ginSwagger.URL("/openapi.json")))
 
 	// Prometheus metrics endpoint (requires authorization unless the server is
 	// configured with MetricsPublic)
@@ -49,6 +55,9 @@ func SetupRoutes(router *gin.Engine, handler *Handler) {
 			// List objects
 			objects.GET("", handler.ListObjects)
 
+			// Update the metadata of every object under a prefix
+			objects.PATCH("", handler.UpdateMetadataBatch)
+
 			// Object CRUD operations
 			objects.PUT("/*key", handler.PutObject)
 			objects.GET("/*key", handler.GetObject)
@@ -56,6 +65,42 @@ func SetupRoutes(router *gin.Engine, handler *Handler) {
 			objects.HEAD("/*key", handler.HeadObject)
 		}
 
+		// Search objects by key and metadata
+		v1.GET("/search", handler.SearchObjects)
+
+		// Virtual directories
+		v1.GET("/browse", handler.Browse)
+
+		// Change feed
+		v1.GET("/changes", handler.GetChanges)
+
+		// Storage statistics
+		v1.GET("/stats", handler.GetStats)
+
+		// Age and access distributions
+		v1.GET("/analyze", handler.AnalyzePrefix)
+
+		// Cost estimates
+		v1.GET("/cost", handler.GetCostReport)
+
+		// Background jobs
+		v1.GET("/jobs", handler.ListJobs)
+		v1.GET("/jobs/:name", handler.GetJob)
+
+		// Durable task queue
+		v1.GET("/tasks", handler.ListTasks)
+		v1.GET("/tasks/:id", handler.GetTask)
+		v1.POST("/tasks/:id/retry", handler.RetryTask)
+
+		// SQL select queries over CSV, JSON and Parquet objects
+		v1.POST("/select/*key", handler.SelectObjectContent)
+
+		// Object diffs
+		v1.GET("/diff", handler.DiffObjects)
+
+		// Move every object under a prefix to another
+		v1.POST("/rename", handler.RenamePrefix)
+
 		// Archive operations
 		v1.POST("/archive", handler.Archive)
 
@@ -78,6 +123,99 @@ func SetupRoutes(router *gin.Engine, handler *Handler) {
 			replication.POST("/trigger", handler.TriggerReplication)
 			replication.GET("/status/*id", handler.GetReplicationStatus)
 		}
+
+		// Quota operations
+		quotas := v1.Group("/quotas")
+		{
+			quotas.GET("", handler.ListQuotas)
+			quotas.GET("/*prefix", handler.GetQuota)
+			quotas.PUT("/*prefix", handler.SetQuota)
+			quotas.DELETE("/*prefix", handler.RemoveQuota)
+		}
+
+		// Event notification webhook operations
+		webhooks := v1.Group("/webhooks")
+		{
+			webhooks.GET("", handler.ListWebhooks)
+			webhooks.GET("/:id", handler.GetWebhook)
+			webhooks.PUT("/:id", handler.SetWebhook)
+			webhooks.DELETE("/:id", handler.DeleteWebhook)
+		}
+
+		// SigV4 access key operations
+		accessKeys := v1.Group("/access-keys")
+		{
+			accessKeys.GET("", handler.ListAccessKeys)
+			accessKeys.POST("", handler.CreateAccessKey)
+			accessKeys.GET("/:id", handler.GetAccessKey)
+			accessKeys.DELETE("/:id", handler.DeleteAccessKey)
+		}
+
+		// Deletion approval and legal hold operations
+		deletions := v1.Group("/deletions")
+		{
+			deletions.GET("", handler.ListDeletionRequests)
+			deletions.GET("/:id", handler.GetDeletionRequest)
+			deletions.POST("/:id/approve", handler.ApproveDeletionRequest)
+			deletions.POST("/:id/reject", handler.RejectDeletionRequest)
+		}
+		holds := v1.Group("/holds")
+		{
+			holds.GET("", handler.ListLegalHolds)
+			holds.PUT("/*key", handler.PlaceLegalHold)
+			holds.DELETE("/*key", handler.ReleaseLegalHold)
+		}
+
+		// Advisory lock operations
+		lockRoutes := v1.Group("/locks")
+		{
+			lockRoutes.GET("", handler.ListLocks)
+			lockRoutes.GET("/*key", handler.GetLock)
+			lockRoutes.PUT("/*key", handler.AcquireLock)
+			lockRoutes.DELETE("/*key", handler.ReleaseLock)
+		}
+
+		// Object alias operations
+		aliases := v1.Group("/aliases")
+		{
+			aliases.GET("", handler.ListAliases)
+			aliases.GET("/*key", handler.GetAlias)
+			aliases.PUT("/*key", handler.SetAlias)
+			aliases.DELETE("/*key", handler.DeleteAlias)
+		}
+
+		// Share link operations
+		shares := v1.Group("/shares")
+		{
+			shares.GET("", handler.ListShares)
+			shares.GET("/:id", handler.GetShare)
+			shares.DELETE("/:id", handler.RevokeShare)
+			shares.POST("/objects/*key", handler.CreateShare)
+		}
+
+		// Upload session operations
+		uploads := v1.Group("/uploads")
+		{
+			uploads.POST("/objects/*key", handler.CreateUpload)
+			uploads.GET("/:id", handler.GetUpload)
+			uploads.DELETE("/:id", handler.AbortUpload)
+			uploads.PUT("/:id/chunks/:number", handler.PutUploadChunk)
+			uploads.POST("/:id/complete", handler.CompleteUpload)
+		}
+
+		// Dataset manifest operations
+		manifests := v1.Group("/manifests")
+		{
+			manifests.GET("", handler.ListManifests)
+			manifests.POST("", handler.CreateManifest)
+			manifests.GET("/:name", handler.GetManifest)
+			manifests.GET("/:name/draft", handler.GetManifestDraft)
+			manifests.POST("/:name/members", handler.UpdateManifestMembers)
+			manifests.POST("/:name/publish", handler.PublishManifest)
+			manifests.GET("/:name/versions", handler.ListManifestVersions)
+			manifests.GET("/:name/versions/:version", handler.GetManifestVersion)
+			manifests.GET("/:name/tar", handler.GetManifestTar)
+		}
 	}
 
 	// Backwards compatibility: support routes without /api/v1 prefix
@@ -85,6 +223,7 @@ func SetupRoutes(router *gin.Engine, handler *Handler) {
 	router.PUT("/metadata/*key", handler.UpdateObjectMetadata)
 	router.HEAD("/exists/*key", handler.ExistsObject)
 	router.GET("/objects", handler.ListObjects)
+	router.GET("/search", handler.SearchObjects)
 	router.PUT("/objects/*key", handler.PutObject)
 	router.GET("/objects/*key", handler.GetObject)
 	router.DELETE("/objects/*key", handler.DeleteObject)
@@ -14,11 +14,17 @@
 package rest
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/jeremyhahn/go-objstore/api/openapi"
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
 )
 
 func TestSetupRoutes(t *testing.T) {
@@ -188,6 +194,95 @@ func TestRoutesSwagger(t *testing.T) {
 	}
 }
 
+func TestRoutesOpenAPI(t *testing.T) {
+	storage := NewMockStorage()
+	handler := newTestHandler(t, storage)
+
+	router := gin.New()
+	SetupRoutes(router, handler)
+
+	req := httptest.NewRequest("GET", "/openapi.json", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("/openapi.json status = %v", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("/openapi.json Content-Type = %q", ct)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("/openapi.json is not valid JSON: %v", err)
+	}
+	if doc["openapi"] != "3.0.3" {
+		t.Errorf("openapi = %v", doc["openapi"])
+	}
+
+	req = httptest.NewRequest("GET", "/openapi.yaml", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Body.String(), "openapi: 3.0.3") {
+		t.Errorf("/openapi.yaml status = %v, body prefix %q", w.Code, w.Body.String()[:min(20, w.Body.Len())])
+	}
+}
+
+// TestRoutesDocumentedInOpenAPI keeps api/openapi/objstore.yaml in step with
+// the router: every API route must be documented, and every documented
+// operation must be served.
+func TestRoutesDocumentedInOpenAPI(t *testing.T) {
+	storage := NewMockStorage()
+	handler := newTestHandler(t, storage)
+
+	router := gin.New()
+	SetupRoutes(router, handler)
+
+	// Optional routes mounted by NewServer when configured
+	tokens, err := adapters.NewTokenService(&adapters.TokenServiceConfig{Key: make([]byte, 32)})
+	if err != nil {
+		t.Fatalf("NewTokenService() error = %v", err)
+	}
+	setupTokenRoutes(router, tokens, adapters.NewNoOpAuthorizer(), adapters.NewNoOpLogger())
+	setupPostPolicyRoutes(router, handler, adapters.NewSigV4Authenticator(nil), adapters.NewNoOpAuthorizer(), adapters.NewNoOpLogger())
+
+	ops, err := openapi.Operations()
+	if err != nil {
+		t.Fatalf("Operations() error = %v", err)
+	}
+	documented := make(map[string]bool, len(ops))
+	for _, op := range ops {
+		documented[op.Method+" "+op.Path] = true
+	}
+
+	wildcard := regexp.MustCompile(`/\*(\w+)$`)
+	param := regexp.MustCompile(`/:(\w+)`)
+	served := make(map[string]bool)
+	for _, route := range router.Routes() {
+		var path string
+		switch {
+		case strings.HasPrefix(route.Path, "/api/v1/"):
+			path = strings.TrimPrefix(route.Path, "/api/v1")
+		case route.Path == "/health" || route.Path == "/metrics" || route.Path == uploadPath || strings.HasPrefix(route.Path, sharePath):
+			path = route.Path
+		default:
+			// Legacy unversioned aliases and documentation routes
+			continue
+		}
+		path = wildcard.ReplaceAllString(path, "/{$1}")
+		path = param.ReplaceAllString(path, "/{$1}")
+		served[route.Method+" "+path] = true
+		if !documented[route.Method+" "+path] {
+			t.Errorf("route %s %s is not documented in api/openapi/objstore.yaml", route.Method, route.Path)
+		}
+	}
+	for _, op := range ops {
+		if !served[op.Method+" "+op.Path] {
+			t.Errorf("operation %s (%s %s) is documented but not served", op.ID, op.Method, op.Path)
+		}
+	}
+}
+
 func TestRoutesWithTrailingSlash(t *testing.T) {
 	storage := NewMockStorage()
 	handler := newTestHandler(t, storage)
@@ -0,0 +1,152 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+	"github.com/jeremyhahn/go-objstore/pkg/query"
+)
+
+// selectPath is the prefix of the select query API.
+const selectPath = "/api/v1/select"
+
+// Trailers of a select response.
+const (
+	headerSelectError          = "X-Select-Error"
+	headerSelectBytesScanned   = "X-Select-Bytes-Scanned"
+	headerSelectBytesProcessed = "X-Select-Bytes-Processed"
+	headerSelectBytesReturned  = "X-Select-Bytes-Returned"
+)
+
+// SelectCSVInput configures how a CSV object is read
+type SelectCSVInput struct {
+	FileHeaderInfo string `json:"file_header_info,omitempty" example:"use"`
+	FieldDelimiter string `json:"field_delimiter,omitempty" example:","`
+	Comments       string `json:"comments,omitempty" example:"#"`
+} // @name SelectCSVInput
+
+// SelectInput describes the serialization of the queried object
+type SelectInput struct {
+	Format      string          `json:"format" binding:"required" example:"csv"`
+	Compression string          `json:"compression,omitempty" example:"gzip"`
+	CSV         *SelectCSVInput `json:"csv,omitempty"`
+} // @name SelectInput
+
+// SelectOutput describes the serialization of query results
+type SelectOutput struct {
+	Format         string `json:"format,omitempty" example:"json"`
+	FieldDelimiter string `json:"field_delimiter,omitempty" example:","`
+} // @name SelectOutput
+
+// SelectObjectContentRequest runs a SQL query over an object
+type SelectObjectContentRequest struct {
+	Expression string        `json:"expression" binding:"required" example:"SELECT s.name FROM S3Object s WHERE s.age > 30"`
+	Input      SelectInput   `json:"input" binding:"required"`
+	Output     *SelectOutput `json:"output,omitempty"`
+} // @name SelectObjectContentRequest
+
+// isSelectPath reports whether path belongs to the select query API.
+func isSelectPath(path string) bool {
+	return path == selectPath || strings.HasPrefix(path, selectPath+"/")
+}
+
+// SelectObjectContent runs an S3 Select-style SQL query over a CSV, JSON
+// or Parquet object and streams the matching records. Failures after the
+// response started truncate it and are reported in the X-Select-Error
+// trailer; the query's statistics are sent as trailers too.
+func (h *Handler) SelectObjectContent(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	if key == "" {
+		RespondWithError(c, http.StatusBadRequest, "object key is required")
+		return
+	}
+	var body SelectObjectContentRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+
+	req := &query.Request{
+		Expression: body.Expression,
+		Input: query.Input{
+			Format:      query.Format(body.Input.Format),
+			Compression: body.Input.Compression,
+		},
+		Limits: h.selectLimits,
+	}
+	if csvIn := body.Input.CSV; csvIn != nil {
+		req.Input.CSV = query.CSVInput{
+			FileHeaderInfo: csvIn.FileHeaderInfo,
+			FieldDelimiter: csvIn.FieldDelimiter,
+			Comments:       csvIn.Comments,
+		}
+	}
+	if out := body.Output; out != nil {
+		req.Output = query.Output{Format: query.Format(out.Format), FieldDelimiter: out.FieldDelimiter}
+	}
+
+	results, err := objstore.SelectObjectContent(c.Request.Context(), h.keyRef(key), req)
+	if err != nil {
+		respondWithSelectError(c, err)
+		return
+	}
+	defer func() { _ = results.Close() }()
+
+	peek := make([]byte, filterPeekSize)
+	n, err := io.ReadFull(results, peek)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		respondWithSelectError(c, err)
+		return
+	}
+
+	c.Header("Content-Type", results.ContentType())
+	c.Header("Trailer", strings.Join([]string{
+		headerSelectError, headerSelectBytesScanned, headerSelectBytesProcessed, headerSelectBytesReturned,
+	}, ", "))
+	c.Status(http.StatusOK)
+	if _, err := c.Writer.Write(peek[:n]); err != nil {
+		return
+	}
+	if _, err := io.Copy(c.Writer, results); err != nil {
+		c.Writer.Header().Set(headerSelectError, common.SanitizeErrorMessage(err))
+		_ = c.Error(err)
+	}
+
+	stats := results.Stats()
+	c.Writer.Header().Set(headerSelectBytesScanned, strconv.FormatInt(stats.BytesScanned, 10))
+	c.Writer.Header().Set(headerSelectBytesProcessed, strconv.FormatInt(stats.BytesProcessed, 10))
+	c.Writer.Header().Set(headerSelectBytesReturned, strconv.FormatInt(stats.BytesReturned, 10))
+}
+
+// respondWithSelectError maps a query failure to a response before any of
+// the body was written.
+func respondWithSelectError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, common.ErrResourceExhausted):
+		RespondWithError(c, http.StatusUnprocessableEntity, common.SanitizeErrorMessage(err))
+	case errors.Is(err, context.DeadlineExceeded):
+		RespondWithError(c, http.StatusUnprocessableEntity, "query timed out")
+	default:
+		RespondWithBackendError(c, err)
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+	"github.com/jeremyhahn/go-objstore/pkg/query"
+)
+
+func TestSelectObjectContent(t *testing.T) {
+	handler := newTestHandler(t, memory.New())
+	handler.selectLimits = query.Limits{MaxOutputBytes: 64 << 10}
+
+	router := gin.New()
+	router.POST("/select/*key", handler.SelectObjectContent)
+
+	ctx := context.Background()
+	csvData := "name,age\nAlice,34\nBob,27\n"
+	if err := objstore.PutWithContext(ctx, "people.csv", strings.NewReader(csvData)); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if err := objstore.PutWithContext(ctx, "big.ndjson", strings.NewReader(strings.Repeat(`{"n":1}`+"\n", 20000))); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	csvInput := `"input":{"format":"csv","csv":{"file_header_info":"use"}}`
+	tests := []struct {
+		name           string
+		key            string
+		body           string
+		wantStatusCode int
+		wantBody       string
+		wantType       string
+	}{
+		{"csv to json", "people.csv", `{"expression":"SELECT name FROM S3Object WHERE age > 30",` + csvInput + `,"output":{"format":"json"}}`,
+			http.StatusOK, "{\"name\":\"Alice\"}\n", "application/json"},
+		{"csv", "people.csv", `{"expression":"SELECT COUNT(*) FROM S3Object",` + csvInput + `}`,
+			http.StatusOK, "2\n", "text/csv"},
+		{"invalid query", "people.csv", `{"expression":"SELECT FROM",` + csvInput + `}`, http.StatusBadRequest, "", ""},
+		{"invalid format", "people.csv", `{"expression":"SELECT * FROM S3Object","input":{"format":"xml"}}`, http.StatusBadRequest, "", ""},
+		{"missing input", "people.csv", `{"expression":"SELECT * FROM S3Object"}`, http.StatusBadRequest, "", ""},
+		{"invalid json", "people.csv", `{`, http.StatusBadRequest, "", ""},
+		{"bad record", "people.csv", `{"expression":"SELECT * FROM S3Object","input":{"format":"json"}}`, http.StatusBadRequest, "", ""},
+		{"missing object", "missing.csv", `{"expression":"SELECT * FROM S3Object",` + csvInput + `}`, http.StatusNotFound, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/select/"+tt.key, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("SelectObjectContent() status = %v, want %v, body: %s", w.Code, tt.wantStatusCode, w.Body.String())
+			}
+			if tt.wantStatusCode != http.StatusOK {
+				return
+			}
+			if w.Body.String() != tt.wantBody {
+				t.Errorf("body = %q, want %q", w.Body.String(), tt.wantBody)
+			}
+			if ct := w.Header().Get("Content-Type"); ct != tt.wantType {
+				t.Errorf("Content-Type = %q, want %q", ct, tt.wantType)
+			}
+			trailer := w.Result().Trailer
+			if trailer.Get(headerSelectBytesScanned) != strconv.Itoa(len(csvData)) || trailer.Get(headerSelectBytesReturned) == "" {
+				t.Errorf("trailers = %v", trailer)
+			}
+		})
+	}
+
+	// The output limit is hit after the response started
+	req := httptest.NewRequest("POST", "/select/big.ndjson", strings.NewReader(`{"expression":"SELECT * FROM S3Object","input":{"format":"json"}}`))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || w.Body.Len() > 64<<10 {
+		t.Fatalf("status = %v, %d bytes", w.Code, w.Body.Len())
+	}
+	if w.Result().Trailer.Get(headerSelectError) == "" {
+		t.Error("expected the select error trailer")
+	}
+}
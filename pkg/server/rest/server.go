@@ -17,6 +17,7 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"net"
 	"net/http"
 	"time"
 
@@ -24,6 +25,8 @@ import (
 	"github.com/jeremyhahn/go-objstore/pkg/adapters"
 	"github.com/jeremyhahn/go-objstore/pkg/audit"
 	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/filter"
+	"github.com/jeremyhahn/go-objstore/pkg/query"
 	"github.com/jeremyhahn/go-objstore/pkg/server/middleware"
 )
 
@@ -69,6 +72,13 @@ type ServerConfig struct {
 	// RateLimitConfig is the rate limiting configuration
 	RateLimitConfig *middleware.RateLimitConfig
 
+	// EnableIdempotency deduplicates retried mutating requests that carry an
+	// Idempotency-Key header
+	EnableIdempotency bool
+
+	// IdempotencyConfig is the idempotency key configuration
+	IdempotencyConfig *middleware.IdempotencyConfig
+
 	// EnableSecurityHeaders enables security headers middleware
 	EnableSecurityHeaders bool
 
@@ -81,6 +91,15 @@ type ServerConfig struct {
 	// MaxRequestSize is the maximum request body size in bytes (default: 100MB)
 	MaxRequestSize int64
 
+	// FilterLimits bounds the resources used by GET filters such as
+	// ?decompress=, ?range-lines= and ?jq= (zero fields use the filter
+	// package defaults)
+	FilterLimits filter.Limits
+
+	// SelectLimits bounds the resources used by select queries (zero
+	// fields use the query package defaults)
+	SelectLimits query.Limits
+
 	// ReadTimeout is the maximum duration for reading the entire request
 	ReadTimeout time.Duration
 
@@ -102,6 +121,23 @@ type ServerConfig struct {
 	// Authorizer is the pluggable authorization adapter (default: NoOpAuthorizer = allow-all)
 	Authorizer adapters.Authorizer
 
+	// TokenService, when set, serves POST /api/v1/tokens, where an
+	// authenticated principal mints short-lived scoped tokens. Include the
+	// same service in Authenticator (e.g. via NewCompositeAuthenticator) on
+	// every server that should accept the tokens (default: nil = disabled).
+	TokenService *adapters.TokenService
+
+	// PostPolicyAuthenticator, when set, serves POST /upload, where browsers
+	// upload directly with form fields signed as an S3-style POST policy
+	// by one of its SigV4 keys (default: nil = disabled).
+	PostPolicyAuthenticator *adapters.SigV4Authenticator
+
+	// AccessKeys, when set, serves /api/v1/access-keys, where admins issue
+	// and revoke SigV4 access keys. Include a SigV4Authenticator over its
+	// LookupSecret in Authenticator so the keys authenticate (default: nil
+	// = the endpoints answer 501).
+	AccessKeys *adapters.AccessKeyStore
+
 	// TLSConfig is the TLS/mTLS configuration (default: nil = no TLS)
 	TLSConfig *adapters.TLSConfig
 
@@ -115,6 +151,45 @@ type ServerConfig struct {
 	// The default (false) requires Prometheus scrapers to present credentials
 	// accepted by the configured authorizer.
 	MetricsPublic bool
+
+	// EnableUI serves the embedded admin UI at /ui. The UI requires
+	// authentication and calls the API with the caller's credentials, so every
+	// action it takes is authorized as usual (default: false).
+	EnableUI bool
+
+	// UIOIDC enables OpenID Connect sign-in for the admin UI and serves its
+	// static assets without authentication (default: nil = token entry).
+	UIOIDC *UIOIDCConfig
+
+	// PreAuthMiddleware runs before authentication, after the request ID,
+	// rate limit, security header, CORS and audit middleware. Use it to
+	// reject requests early or to prepare what the Authenticator reads,
+	// such as translating a tenant header.
+	PreAuthMiddleware []gin.HandlerFunc
+
+	// Middleware runs after the built-in middleware, just before the route
+	// handlers, so the authenticated principal is in the request context
+	// under adapters.PrincipalContextKey. Use it for tenant extraction,
+	// additional authorization or request shaping.
+	Middleware []gin.HandlerFunc
+}
+
+// ServerOption modifies the ServerConfig passed to NewServer.
+type ServerOption func(*ServerConfig)
+
+// WithPreAuthMiddleware adds middleware that runs before authentication.
+func WithPreAuthMiddleware(handlers ...gin.HandlerFunc) ServerOption {
+	return func(c *ServerConfig) {
+		c.PreAuthMiddleware = append(c.PreAuthMiddleware, handlers...)
+	}
+}
+
+// WithMiddleware adds middleware that runs after authentication and
+// authorization, just before the route handlers.
+func WithMiddleware(handlers ...gin.HandlerFunc) ServerOption {
+	return func(c *ServerConfig) {
+		c.Middleware = append(c.Middleware, handlers...)
+	}
 }
 
 // DefaultServerConfig returns a ServerConfig with sensible defaults
@@ -126,6 +201,8 @@ func DefaultServerConfig() *ServerConfig {
 		EnableLogging:         true,
 		EnableRateLimit:       false, // Disabled by default
 		RateLimitConfig:       middleware.DefaultRateLimitConfig(),
+		EnableIdempotency:     false, // Disabled by default
+		IdempotencyConfig:     middleware.DefaultIdempotencyConfig(),
 		EnableSecurityHeaders: true,
 		SecurityHeadersConfig: middleware.DefaultSecurityHeadersConfig(),
 		EnableRequestID:       true,
@@ -144,11 +221,15 @@ func DefaultServerConfig() *ServerConfig {
 	}
 }
 
-// NewServer creates a new REST API server
-func NewServer(storage common.Storage, config *ServerConfig) (*Server, error) {
+// NewServer creates a new REST API server. Options are applied to config
+// after it is defaulted.
+func NewServer(storage common.Storage, config *ServerConfig, opts ...ServerOption) (*Server, error) {
 	if config == nil {
 		config = DefaultServerConfig()
 	}
+	for _, opt := range opts {
+		opt(config)
+	}
 
 	// Set defaults for nil fields
 	if config.Logger == nil {
@@ -191,7 +272,8 @@ func NewServer(storage common.Storage, config *ServerConfig) (*Server, error) {
 	// requests are counted too). Exposed at GET /metrics.
 	router.Use(MetricsMiddleware())
 
-	// Middleware order: request ID → rate limit → security headers → CORS → auth → logging → size limit
+	// Middleware order: request ID → rate limit → security headers → CORS → audit → pre-auth custom →
+	// auth → logging → size limit → idempotency → custom
 
 	// Add request ID middleware if enabled (should be first to track all requests)
 	if config.EnableRequestID {
@@ -208,7 +290,11 @@ func NewServer(storage common.Storage, config *ServerConfig) (*Server, error) {
 
 	// Add security headers middleware if enabled
 	if config.EnableSecurityHeaders {
-		router.Use(middleware.SecurityHeadersMiddleware(config.SecurityHeadersConfig))
+		securityHeaders := config.SecurityHeadersConfig
+		if config.EnableUI && config.UIOIDC != nil {
+			securityHeaders = config.UIOIDC.securityHeaders(securityHeaders)
+		}
+		router.Use(middleware.SecurityHeadersMiddleware(securityHeaders))
 	}
 
 	// Add CORS middleware if enabled
@@ -221,8 +307,17 @@ func NewServer(storage common.Storage, config *ServerConfig) (*Server, error) {
 		router.Use(audit.AuditMiddleware(config.AuditLogger))
 	}
 
+	// Add custom middleware that runs before authentication
+	router.Use(config.PreAuthMiddleware...)
+
+	// Let browsers load the admin UI before signing in
+	authenticator := config.Authenticator
+	if config.EnableUI && config.UIOIDC != nil {
+		authenticator = newPublicUIAuthenticator(authenticator)
+	}
+
 	// Add authentication middleware (always enabled, uses NoOpAuthenticator by default)
-	router.Use(AuthenticationMiddleware(config.Authenticator, config.Logger, config.AuditLogger, config.MetricsPublic))
+	router.Use(AuthenticationMiddleware(authenticator, config.Logger, config.AuditLogger, config.MetricsPublic))
 
 	// Add authorization middleware (always enabled, uses NoOpAuthorizer by default).
 	// Runs after authentication so the principal is available. Health and swagger
@@ -230,7 +325,8 @@ func NewServer(storage common.Storage, config *ServerConfig) (*Server, error) {
 	// since this is a global middleware, the health route still passes through the
 	// allow-all default. AuthorizationMiddleware only denies when a restrictive
 	// authorizer is configured.
-	router.Use(AuthorizationMiddleware(config.Authorizer, config.Logger, config.AuditLogger, config.MetricsPublic))
+	authorizer := adapters.NewScopedAuthorizer(config.Authorizer)
+	router.Use(AuthorizationMiddleware(authorizer, config.Logger, config.AuditLogger, config.MetricsPublic))
 
 	// Add logging middleware if enabled
 	if config.EnableLogging {
@@ -242,14 +338,37 @@ func NewServer(storage common.Storage, config *ServerConfig) (*Server, error) {
 		router.Use(RequestSizeLimitMiddleware(config.MaxRequestSize))
 	}
 
+	// Add idempotency middleware if enabled. Runs after authentication so keys
+	// are scoped to the principal, and after the size limit so fingerprinting
+	// reads a bounded body.
+	if config.EnableIdempotency {
+		router.Use(middleware.NewIdempotency(config.IdempotencyConfig, config.Logger).GinMiddleware())
+	}
+
+	// Add custom middleware
+	router.Use(config.Middleware...)
+
 	// Create handler (uses facade with default backend)
 	handler, err := NewHandler("")
 	if err != nil {
 		return nil, fmt.Errorf("failed to create handler: %w", err)
 	}
+	handler.filterLimits = config.FilterLimits
+	handler.selectLimits = config.SelectLimits
+	handler.authorizer = authorizer
+	handler.accessKeys = config.AccessKeys
 
 	// Setup routes
 	SetupRoutes(router, handler)
+	if config.EnableUI {
+		setupUIRoutes(router, config.UIOIDC)
+	}
+	if config.TokenService != nil {
+		setupTokenRoutes(router, config.TokenService, authorizer, config.Logger)
+	}
+	if config.PostPolicyAuthenticator != nil {
+		setupPostPolicyRoutes(router, handler, config.PostPolicyAuthenticator, authorizer, config.Logger)
+	}
 
 	// Create HTTP server
 	addr := fmt.Sprintf("%s:%d", config.Host, config.Port)
@@ -274,8 +393,39 @@ func NewServer(storage common.Storage, config *ServerConfig) (*Server, error) {
 	return server, nil
 }
 
+// HTTPHandler returns the server's routes and middleware as an http.Handler,
+// for mounting on an application's own mux instead of calling Start. The
+// routes keep their paths (/api/v1/..., /health), so strip any mount prefix
+// first:
+//
+//	mux.Handle("/storage/", http.StripPrefix("/storage", server.HTTPHandler()))
+//
+// The application's http.Server then owns the listener, timeouts and TLS;
+// Shutdown still releases the server's own resources such as the rate
+// limiter.
+func (s *Server) HTTPHandler() http.Handler {
+	return s.router
+}
+
 // Start starts the REST API server
 func (s *Server) Start() error {
+	return s.serve(nil)
+}
+
+// Serve serves the REST API server on a listener created by the caller,
+// such as a Unix socket or a listener shared through cmux. The configured
+// host and port are ignored.
+func (s *Server) Serve(listener net.Listener) error {
+	return s.serve(listener)
+}
+
+// serve listens on the configured address when listener is nil.
+func (s *Server) serve(listener net.Listener) error {
+	addr := s.httpServer.Addr
+	if listener != nil {
+		addr = listener.Addr().String()
+	}
+
 	// Build TLS config if provided. Build returns a nil *tls.Config when the
 	// adapter config is disabled (the zero value); serve plaintext in that case.
 	if s.config.TLSConfig != nil {
@@ -292,18 +442,24 @@ func (s *Server) Start() error {
 			s.httpServer.TLSConfig = tlsConfig
 
 			s.config.Logger.Info(context.Background(), "Starting REST API server with TLS",
-				adapters.Field{Key: "address", Value: s.httpServer.Addr},
+				adapters.Field{Key: "address", Value: addr},
 				adapters.Field{Key: "tls_mode", Value: s.config.TLSConfig.Mode},
 			)
 
-			// ListenAndServeTLS requires empty cert/key params when using TLSConfig
+			// The TLS serve methods require empty cert/key params when using TLSConfig
+			if listener != nil {
+				return s.httpServer.ServeTLS(listener, "", "")
+			}
 			return s.httpServer.ListenAndServeTLS("", "")
 		}
 	}
 
 	s.config.Logger.Info(context.Background(), "Starting REST API server",
-		adapters.Field{Key: "address", Value: s.httpServer.Addr},
+		adapters.Field{Key: "address", Value: addr},
 	)
+	if listener != nil {
+		return s.httpServer.Serve(listener)
+	}
 	return s.httpServer.ListenAndServe()
 }
 
@@ -15,14 +15,18 @@ package rest
 
 import (
 	"context"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
 	"github.com/jeremyhahn/go-objstore/pkg/common"
 	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+	"github.com/jeremyhahn/go-objstore/pkg/server/middleware"
 )
 
 // initSrvTestFacade initializes the objstore facade with a mock storage for testing.
@@ -204,6 +208,91 @@ func TestServerWithAllMiddleware(t *testing.T) {
 	}
 }
 
+func TestServerCustomMiddleware(t *testing.T) {
+	storage := NewMockStorage()
+	initSrvTestFacade(t, storage)
+
+	var preAuthPrincipal, principal any
+	server, err := NewServer(storage, &ServerConfig{Mode: gin.TestMode},
+		WithPreAuthMiddleware(func(c *gin.Context) {
+			if c.GetHeader("X-Tenant") == "" {
+				c.AbortWithStatus(http.StatusBadRequest)
+				return
+			}
+			preAuthPrincipal = c.Request.Context().Value(adapters.PrincipalContextKey{})
+		}),
+		WithMiddleware(func(c *gin.Context) {
+			principal = c.Request.Context().Value(adapters.PrincipalContextKey{})
+			c.Header("X-Tenant", c.GetHeader("X-Tenant"))
+		}),
+	)
+	if err != nil {
+		t.Fatalf("NewServer() failed: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/objects", nil)
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("request without tenant status = %v, want %v", w.Code, http.StatusBadRequest)
+	}
+
+	req = httptest.NewRequest("GET", "/objects", nil)
+	req.Header.Set("X-Tenant", "acme")
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	if w.Code != http.StatusOK || w.Header().Get("X-Tenant") != "acme" {
+		t.Errorf("request with tenant = %v %v", w.Code, w.Header())
+	}
+	if preAuthPrincipal != nil {
+		t.Errorf("pre-auth middleware saw principal %v", preAuthPrincipal)
+	}
+	if principal == nil {
+		t.Error("middleware did not see the authenticated principal")
+	}
+}
+
+func TestServerEmbedded(t *testing.T) {
+	storage := NewMockStorage()
+	initSrvTestFacade(t, storage)
+	server, err := NewServer(storage, &ServerConfig{Mode: gin.TestMode})
+	if err != nil {
+		t.Fatalf("NewServer() failed: %v", err)
+	}
+
+	// Mount on an application mux under a prefix
+	mux := http.NewServeMux()
+	mux.Handle("/storage/", http.StripPrefix("/storage", server.HTTPHandler()))
+	req := httptest.NewRequest("GET", "/storage/health", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("mounted handler status = %v, want %v", w.Code, http.StatusOK)
+	}
+
+	// Serve on a listener owned by the caller
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	errCh := make(chan error, 1)
+	go func() { errCh <- server.Serve(listener) }()
+	resp, err := http.Get("http://" + listener.Addr().String() + "/health")
+	if err != nil {
+		t.Fatalf("GET /health error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Serve() status = %v, want %v", resp.StatusCode, http.StatusOK)
+	}
+	if err := server.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-errCh; err != http.ErrServerClosed {
+		t.Errorf("Serve() error = %v, want %v", err, http.ErrServerClosed)
+	}
+}
+
 func TestServerWithoutMiddleware(t *testing.T) {
 	storage := NewMockStorage()
 	config := &ServerConfig{
@@ -320,3 +409,44 @@ func TestServerCustomTimeouts(t *testing.T) {
 		t.Errorf("IdleTimeout = %v, want %v", server.httpServer.IdleTimeout, 60*time.Second)
 	}
 }
+
+func TestServerIdempotency(t *testing.T) {
+	storage := NewMockStorage()
+	config := DefaultServerConfig()
+	config.Mode = gin.TestMode
+	config.EnableAudit = false
+	config.EnableLogging = false
+	config.EnableIdempotency = true
+
+	initSrvTestFacade(t, storage)
+	server, err := NewServer(storage, config)
+	if err != nil {
+		t.Fatalf("NewServer() failed: %v", err)
+	}
+
+	do := func(method string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/v1/objects/idem.txt", strings.NewReader("data"))
+		req.Header.Set(middleware.IdempotencyKeyHeader, "delete-1")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	if err := storage.Put("idem.txt", strings.NewReader("data")); err != nil {
+		t.Fatalf("Put() failed: %v", err)
+	}
+
+	first := do(http.MethodDelete)
+	if first.Code != http.StatusNoContent {
+		t.Fatalf("first DELETE status = %d, want %d", first.Code, http.StatusNoContent)
+	}
+
+	// The retried delete replays the original success instead of a 404.
+	second := do(http.MethodDelete)
+	if second.Code != http.StatusNoContent {
+		t.Errorf("retried DELETE status = %d, want %d", second.Code, http.StatusNoContent)
+	}
+	if second.Header().Get(middleware.IdempotentReplayedHeader) != "true" {
+		t.Error("retried DELETE was not replayed")
+	}
+}
@@ -0,0 +1,309 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+	"github.com/jeremyhahn/go-objstore/pkg/share"
+)
+
+// sharesPath is the prefix of the share link management API. Creating a
+// link is authorized as a read of the shared key; listing and revoking
+// links as actions on adapters.ResourceShares.
+const sharesPath = "/api/v1/shares"
+
+// sharePath is the prefix of public share link downloads, followed by the
+// link token. It is served at the server root without authentication.
+const sharePath = "/share/"
+
+// headerSharePassword carries the password of a password-protected link.
+// Browsers may send it as the password of HTTP basic authentication
+// instead.
+const headerSharePassword = "X-Share-Password"
+
+// CreateShareRequest is the optional body when creating a share link
+type CreateShareRequest struct {
+	TTLSeconds     int64  `json:"ttl_seconds,omitempty" example:"86400"`
+	MaxDownloads   int    `json:"max_downloads,omitempty" example:"10"`
+	Password       string `json:"password,omitempty" example:"correct horse"`
+	BytesPerSecond int64  `json:"bytes_per_second,omitempty" example:"1048576"`
+} // @name CreateShareRequest
+
+// ShareResponse is a share link with its download accounting. The token
+// and URL are only returned when the link is created.
+type ShareResponse struct {
+	ID                string `json:"id" example:"3f2a9c1e5b7d4e6f8a0b1c2d3e4f5a6b"`
+	Key               string `json:"key" example:"reports/q1.pdf"`
+	Token             string `json:"token,omitempty" example:"q3JxW9zT0b8Vf6yH2mK5pL7nR1sD4gA8cE0uI6oY3tQ"`
+	URL               string `json:"url,omitempty" example:"https://objstore.example.com/share/q3JxW9zT0b8Vf6yH2mK5pL7nR1sD4gA8cE0uI6oY3tQ"`
+	Status            string `json:"status" example:"active"`
+	CreatedBy         string `json:"created_by,omitempty" example:"alice"`
+	CreatedAt         string `json:"created_at" example:"2025-11-05T10:00:00Z"`
+	ExpiresAt         string `json:"expires_at" example:"2025-11-06T10:00:00Z"`
+	MaxDownloads      int    `json:"max_downloads,omitempty" example:"10"`
+	BytesPerSecond    int64  `json:"bytes_per_second,omitempty" example:"1048576"`
+	PasswordProtected bool   `json:"password_protected"`
+	Downloads         int    `json:"downloads" example:"3"`
+	BytesServed       int64  `json:"bytes_served" example:"3145728"`
+	LastDownload      string `json:"last_download,omitempty" example:"2025-11-05T12:30:00Z"`
+} // @name Share
+
+// SharesResponse lists share links
+type SharesResponse struct {
+	Shares []ShareResponse `json:"shares"`
+	Count  int             `json:"count" example:"1"`
+} // @name ShareList
+
+// CreateShare creates a share link to an object
+func (h *Handler) CreateShare(c *gin.Context) {
+	key := strings.TrimLeft(c.Param(keyField), "/")
+	if key == "" {
+		RespondWithError(c, http.StatusBadRequest, "key parameter is required")
+		return
+	}
+
+	var body CreateShareRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			RespondWithError(c, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+	}
+
+	link, token, err := objstore.ShareObject(c.Request.Context(), h.keyRef(key), share.Options{
+		TTL:            time.Duration(body.TTLSeconds) * time.Second,
+		MaxDownloads:   body.MaxDownloads,
+		Password:       body.Password,
+		BytesPerSecond: body.BytesPerSecond,
+	})
+	if err != nil {
+		respondWithShareError(c, err)
+		return
+	}
+	response := shareResponse(link)
+	response.Token = token
+	response.URL = shareURL(c, token)
+	c.JSON(http.StatusCreated, response)
+}
+
+// ListShares lists every share link, including expired and exhausted ones
+func (h *Handler) ListShares(c *gin.Context) {
+	manager, err := objstore.Shares(h.backend)
+	if err != nil {
+		respondWithShareError(c, err)
+		return
+	}
+
+	links, err := manager.List(c.Request.Context())
+	if err != nil {
+		respondWithShareError(c, err)
+		return
+	}
+	response := SharesResponse{
+		Shares: make([]ShareResponse, 0, len(links)),
+		Count:  len(links),
+	}
+	for i := range links {
+		response.Shares = append(response.Shares, shareResponse(&links[i]))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetShare returns one share link, without its token
+func (h *Handler) GetShare(c *gin.Context) {
+	manager, err := objstore.Shares(h.backend)
+	if err != nil {
+		respondWithShareError(c, err)
+		return
+	}
+
+	link, err := manager.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondWithShareError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, shareResponse(link))
+}
+
+// RevokeShare deletes a share link
+func (h *Handler) RevokeShare(c *gin.Context) {
+	manager, err := objstore.Shares(h.backend)
+	if err != nil {
+		respondWithShareError(c, err)
+		return
+	}
+
+	if err := manager.Revoke(c.Request.Context(), c.Param("id")); err != nil {
+		respondWithShareError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// DownloadShare serves the object of a share link to anyone holding its
+// token. Each download counts against the link's limit when it starts,
+// and its bytes are added to the link once it ends.
+func (h *Handler) DownloadShare(c *gin.Context) {
+	// The request log and audit events read the path after the handler
+	// returns; name the link by its ID there so the token is never recorded.
+	token := c.Param("token")
+	c.Request.URL.Path = sharePath + share.LinkID(token)
+	c.Request.URL.RawPath = ""
+
+	// Servers without share links answer as for an unknown token, so the
+	// public endpoint does not reveal how the server is configured.
+	manager, err := objstore.Shares(h.backend)
+	if err != nil {
+		respondWithShareError(c, share.ErrLinkNotFound)
+		return
+	}
+
+	password := c.GetHeader(headerSharePassword)
+	if password == "" {
+		if _, basic, ok := c.Request.BasicAuth(); ok {
+			password = basic
+		}
+	}
+	ctx := c.Request.Context()
+	link, err := manager.Open(ctx, token, password)
+	if err != nil {
+		respondWithShareError(c, err)
+		return
+	}
+
+	metadata, err := objstore.GetMetadata(ctx, h.keyRef(link.Key))
+	if err == nil && common.IsCustomerKeyEncrypted(metadata) {
+		err = common.ErrCustomerKeyRequired
+	}
+	if err != nil {
+		RespondWithBackendError(c, err)
+		return
+	}
+	reader, err := objstore.GetWithContext(ctx, h.keyRef(link.Key))
+	if err != nil {
+		RespondWithBackendError(c, err)
+		return
+	}
+	defer func() { _ = reader.Close() }()
+	body := manager.Throttle(ctx, link, reader)
+	defer func() { _ = body.Close() }()
+
+	// A capped download may outlast the server's write timeout.
+	if link.BytesPerSecond > 0 {
+		_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+	}
+
+	contentType := metadata.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	c.Header("Content-Type", contentType)
+	if metadata.ContentEncoding != "" {
+		c.Header("Content-Encoding", metadata.ContentEncoding)
+	}
+	if metadata.Size > 0 {
+		c.Header("Content-Length", strconv.FormatInt(metadata.Size, 10))
+	}
+	if metadata.ETag != "" {
+		c.Header("ETag", metadata.ETag)
+	}
+	if !metadata.LastModified.IsZero() {
+		c.Header("Last-Modified", metadata.LastModified.Format(http.TimeFormat))
+	}
+	if disposition := mime.FormatMediaType("attachment", map[string]string{"filename": path.Base(link.Key)}); disposition != "" {
+		c.Header("Content-Disposition", disposition)
+	}
+	c.Header("Cache-Control", "private, no-store")
+
+	c.Status(http.StatusOK)
+	n, err := io.Copy(c.Writer, body)
+	if err != nil {
+		_ = c.Error(err)
+	}
+	if err := manager.AddBytes(context.WithoutCancel(ctx), link.ID, n); err != nil {
+		_ = c.Error(err)
+	}
+}
+
+// isSharesPath reports whether path belongs to the share link management
+// API.
+func isSharesPath(path string) bool {
+	return path == sharesPath || strings.HasPrefix(path, sharesPath+"/")
+}
+
+// shareURL returns the download URL of token as reached through the
+// request's host.
+func shareURL(c *gin.Context, token string) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host + sharePath + token
+}
+
+// respondWithShareError maps share link errors to responses that name the
+// link rather than the generic object messages.
+func respondWithShareError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, objstore.ErrSharesNotEnabled):
+		RespondWithError(c, http.StatusNotImplemented, "share links are not enabled on this server")
+	case errors.Is(err, share.ErrLinkNotFound):
+		RespondWithError(c, http.StatusNotFound, "share link not found")
+	case errors.Is(err, share.ErrLinkExpired):
+		RespondWithError(c, http.StatusGone, "share link has expired")
+	case errors.Is(err, share.ErrLinkExhausted):
+		RespondWithError(c, http.StatusGone, "share link download limit reached")
+	case errors.Is(err, share.ErrPasswordRequired):
+		c.Header("WWW-Authenticate", `Basic realm="objstore share link", charset="UTF-8"`)
+		RespondWithError(c, http.StatusUnauthorized, "share link requires a password")
+	case errors.Is(err, common.ErrReservedKey):
+		RespondWithError(c, http.StatusForbidden, "key is in the reserved share link namespace")
+	case errors.Is(err, share.ErrInvalidOptions):
+		RespondWithError(c, http.StatusBadRequest, err.Error())
+	default:
+		RespondWithBackendError(c, err)
+	}
+}
+
+func shareResponse(link *share.Link) ShareResponse {
+	response := ShareResponse{
+		ID:                link.ID,
+		Key:               link.Key,
+		Status:            string(link.Status(time.Now())),
+		CreatedBy:         link.CreatedBy,
+		CreatedAt:         link.CreatedAt.Format(time.RFC3339),
+		ExpiresAt:         link.ExpiresAt.Format(time.RFC3339),
+		MaxDownloads:      link.MaxDownloads,
+		BytesPerSecond:    link.BytesPerSecond,
+		PasswordProtected: link.Protected,
+		Downloads:         link.Downloads,
+		BytesServed:       link.BytesServed,
+	}
+	if link.LastDownload != nil {
+		response.LastDownload = link.LastDownload.Format(time.RFC3339)
+	}
+	return response
+}
@@ -0,0 +1,195 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+)
+
+// newSharesTestServer builds a server over a memory backend holding
+// reports/q1.pdf; every bearer token authenticates as the user it names.
+func newSharesTestServer(t *testing.T, enable bool) *gin.Engine {
+	t.Helper()
+	storage := memory.New()
+	if err := storage.Put("reports/q1.pdf", strings.NewReader("quarterly")); err != nil {
+		t.Fatal(err)
+	}
+	initTestFacade(t, storage)
+	if enable {
+		if err := objstore.EnableShares("", ""); err != nil {
+			t.Fatalf("EnableShares: %v", err)
+		}
+	}
+
+	config := DefaultServerConfig()
+	config.Mode = gin.TestMode
+	config.Authenticator = adapters.NewBearerTokenAuthenticator(func(_ context.Context, token string) (*adapters.Principal, error) {
+		return &adapters.Principal{ID: token, Name: token, Roles: []string{"admin"}}, nil
+	})
+	server, err := NewServer(storage, config)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	return server.Router()
+}
+
+func doShareRequest(router *gin.Engine, method, path, bearer, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func createTestShare(t *testing.T, router *gin.Engine, body string) ShareResponse {
+	t.Helper()
+	w := doShareRequest(router, http.MethodPost, "/api/v1/shares/objects/reports/q1.pdf", "alice", body)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create = %d %s", w.Code, w.Body.String())
+	}
+	var created ShareResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatal(err)
+	}
+	return created
+}
+
+func TestShareEndpoints(t *testing.T) {
+	router := newSharesTestServer(t, true)
+
+	created := createTestShare(t, router, `{"ttl_seconds":3600,"max_downloads":2}`)
+	if created.Token == "" || created.URL != "http://example.com/share/"+created.Token ||
+		created.Key != "reports/q1.pdf" || created.CreatedBy != "alice" || created.Status != "active" {
+		t.Fatalf("unexpected link: %+v", created)
+	}
+
+	// Downloads need no credentials.
+	w := doShareRequest(router, http.MethodGet, "/share/"+created.Token, "", "")
+	if w.Code != http.StatusOK || w.Body.String() != "quarterly" {
+		t.Fatalf("download = %d %q", w.Code, w.Body.String())
+	}
+	if cd := w.Header().Get("Content-Disposition"); cd != `attachment; filename=q1.pdf` {
+		t.Errorf("Content-Disposition = %q", cd)
+	}
+	if w.Header().Get("X-Object-Metadata") != "" {
+		t.Error("expected custom metadata not to be exposed")
+	}
+	if w := doShareRequest(router, http.MethodGet, "/share/"+created.Token, "", ""); w.Code != http.StatusOK {
+		t.Fatalf("second download = %d", w.Code)
+	}
+	if w := doShareRequest(router, http.MethodGet, "/share/"+created.Token, "", ""); w.Code != http.StatusGone {
+		t.Errorf("download past the limit = %d, want %d", w.Code, http.StatusGone)
+	}
+	if w := doShareRequest(router, http.MethodGet, "/share/unknown", "", ""); w.Code != http.StatusNotFound {
+		t.Errorf("unknown token = %d, want %d", w.Code, http.StatusNotFound)
+	}
+
+	// Request logs and audit events see the link ID, never the token.
+	req := httptest.NewRequest(http.MethodGet, "/share/"+created.Token, nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	if req.URL.Path != "/share/"+created.ID {
+		t.Errorf("path after download = %q, want the link ID", req.URL.Path)
+	}
+
+	w = doShareRequest(router, http.MethodGet, "/api/v1/shares", "bob", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("list = %d %s", w.Code, w.Body.String())
+	}
+	var list SharesResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatal(err)
+	}
+	if list.Count != 1 || list.Shares[0].Token != "" || list.Shares[0].Downloads != 2 ||
+		list.Shares[0].BytesServed != int64(2*len("quarterly")) || list.Shares[0].Status != "exhausted" {
+		t.Fatalf("unexpected list: %+v", list)
+	}
+
+	if w := doShareRequest(router, http.MethodGet, "/api/v1/shares/"+created.ID, "bob", ""); w.Code != http.StatusOK {
+		t.Errorf("get = %d", w.Code)
+	}
+	if w := doShareRequest(router, http.MethodDelete, "/api/v1/shares/"+created.ID, "bob", ""); w.Code != http.StatusNoContent {
+		t.Fatalf("revoke = %d %s", w.Code, w.Body.String())
+	}
+	if w := doShareRequest(router, http.MethodGet, "/api/v1/shares/"+created.ID, "bob", ""); w.Code != http.StatusNotFound {
+		t.Errorf("get after revoke = %d, want %d", w.Code, http.StatusNotFound)
+	}
+
+	if w := doShareRequest(router, http.MethodPost, "/api/v1/shares/objects/missing", "alice", ""); w.Code != http.StatusNotFound {
+		t.Errorf("share of a missing object = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	if w := doShareRequest(router, http.MethodPost, "/api/v1/shares/objects/reports/q1.pdf", "alice", `{"max_downloads":-1}`); w.Code != http.StatusBadRequest {
+		t.Errorf("invalid options = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	if w := doShareRequest(router, http.MethodGet, "/api/v1/shares", "", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("list without credentials = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestShareDownloadPassword(t *testing.T) {
+	router := newSharesTestServer(t, true)
+	created := createTestShare(t, router, `{"password":"s3cret"}`)
+	if !created.PasswordProtected {
+		t.Fatalf("expected a protected link: %+v", created)
+	}
+
+	w := doShareRequest(router, http.MethodGet, "/share/"+created.Token, "", "")
+	if w.Code != http.StatusUnauthorized || !strings.HasPrefix(w.Header().Get("WWW-Authenticate"), "Basic ") {
+		t.Fatalf("download without password = %d, WWW-Authenticate %q", w.Code, w.Header().Get("WWW-Authenticate"))
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/share/"+created.Token, nil)
+	req.Header.Set(headerSharePassword, "s3cret")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("download with header password = %d", w.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/share/"+created.Token, nil)
+	req.SetBasicAuth("", "s3cret")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("download with basic auth password = %d", w.Code)
+	}
+}
+
+func TestShareEndpointsNotEnabled(t *testing.T) {
+	router := newSharesTestServer(t, false)
+
+	if w := doShareRequest(router, http.MethodGet, "/api/v1/shares", "alice", ""); w.Code != http.StatusNotImplemented {
+		t.Errorf("GET /shares = %d, want %d", w.Code, http.StatusNotImplemented)
+	}
+	if w := doShareRequest(router, http.MethodPost, "/api/v1/shares/objects/reports/q1.pdf", "alice", ""); w.Code != http.StatusNotImplemented {
+		t.Errorf("POST /shares/objects/reports/q1.pdf = %d, want %d", w.Code, http.StatusNotImplemented)
+	}
+	if w := doShareRequest(router, http.MethodGet, "/share/token", "", ""); w.Code != http.StatusNotFound {
+		t.Errorf("GET /share/token = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
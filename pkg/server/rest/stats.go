@@ -0,0 +1,71 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+	"github.com/jeremyhahn/go-objstore/pkg/stats"
+)
+
+// StatsResponse is the snapshot history of the backends
+type StatsResponse struct {
+	Backends []stats.BackendStats `json:"backends"`
+} // @name StorageStats
+
+// GetStats returns the object count, size, growth rate and largest
+// prefixes of each backend over time. The since parameter is an RFC 3339
+// time or a duration back from now such as 168h; backend selects one
+// backend; format=prometheus renders the latest snapshots as gauges.
+func (h *Handler) GetStats(c *gin.Context) {
+	collector, err := objstore.StatsCollector()
+	switch {
+	case errors.Is(err, objstore.ErrStatsNotEnabled):
+		RespondWithError(c, http.StatusNotImplemented, "storage statistics are not enabled on this server")
+		return
+	case err != nil:
+		RespondWithBackendError(c, err)
+		return
+	}
+
+	switch c.DefaultQuery("format", "json") {
+	case "json":
+	case "prometheus":
+		c.Header("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		c.Status(http.StatusOK)
+		collector.WritePrometheus(c.Writer)
+		return
+	default:
+		RespondWithError(c, http.StatusBadRequest, "invalid format parameter (want json or prometheus)")
+		return
+	}
+
+	var since time.Time
+	if sinceStr := c.Query("since"); sinceStr != "" {
+		if t, err := time.Parse(time.RFC3339, sinceStr); err == nil {
+			since = t
+		} else if d, err := time.ParseDuration(sinceStr); err == nil && d > 0 {
+			since = time.Now().Add(-d)
+		} else {
+			RespondWithError(c, http.StatusBadRequest, "invalid since parameter")
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, StatsResponse{Backends: collector.Stats(c.Query("backend"), since)})
+}
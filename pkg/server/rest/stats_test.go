@@ -0,0 +1,97 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+	"github.com/jeremyhahn/go-objstore/pkg/stats"
+)
+
+func TestGetStats(t *testing.T) {
+	backend := memory.New()
+	if err := backend.Put("logs/a.log", strings.NewReader("12345")); err != nil {
+		t.Fatal(err)
+	}
+	handler := newTestHandler(t, backend)
+
+	router := gin.New()
+	router.GET("/stats", handler.GetStats)
+
+	// Statistics are not enabled yet
+	req := httptest.NewRequest("GET", "/stats", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotImplemented {
+		t.Errorf("GetStats() status = %v, want %v", w.Code, http.StatusNotImplemented)
+	}
+
+	// Take a snapshot now rather than waiting for the background one.
+	if err := objstore.EnableStats(&stats.Config{}); err != nil {
+		t.Fatalf("EnableStats() error = %v", err)
+	}
+	defer objstore.Reset()
+	collector, _ := objstore.StatsCollector()
+	if err := collector.Collect(context.Background()); err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		url            string
+		wantStatusCode int
+	}{
+		{"json", "/stats", http.StatusOK},
+		{"since duration", "/stats?since=24h", http.StatusOK},
+		{"since time", "/stats?since=2025-01-01T00:00:00Z", http.StatusOK},
+		{"invalid since", "/stats?since=yesterday", http.StatusBadRequest},
+		{"invalid format", "/stats?format=xml", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.url, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("GetStats() status = %v, want %v, body: %s", w.Code, tt.wantStatusCode, w.Body.String())
+			}
+		})
+	}
+
+	req = httptest.NewRequest("GET", "/stats", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	var resp StatsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(resp.Backends) != 1 || resp.Backends[0].Latest == nil || resp.Backends[0].Latest.Bytes != 5 {
+		t.Errorf("GetStats() = %+v", resp)
+	}
+
+	req = httptest.NewRequest("GET", "/stats?format=prometheus", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "objstore_storage_bytes{") {
+		t.Errorf("GetStats(prometheus) = %d %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+	"github.com/jeremyhahn/go-objstore/pkg/tasks"
+)
+
+const tasksPath = "/api/v1/tasks"
+
+// TasksResponse lists queued tasks
+type TasksResponse struct {
+	Tasks []tasks.Task `json:"tasks"`
+} // @name TaskList
+
+// ListTasks returns the queued tasks, oldest first, optionally only those
+// in one state (?state=pending|running|succeeded|dead).
+func (h *Handler) ListTasks(c *gin.Context) {
+	queue, ok := taskQueue(c)
+	if !ok {
+		return
+	}
+	state := tasks.State(c.Query("state"))
+	switch state {
+	case "", tasks.StatePending, tasks.StateRunning, tasks.StateSucceeded, tasks.StateDead:
+	default:
+		RespondWithError(c, http.StatusBadRequest, "state must be pending, running, succeeded or dead")
+		return
+	}
+	list, err := queue.List(c.Request.Context(), state)
+	if err != nil {
+		RespondWithBackendError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, TasksResponse{Tasks: list})
+}
+
+// GetTask returns a queued task.
+func (h *Handler) GetTask(c *gin.Context) {
+	queue, ok := taskQueue(c)
+	if !ok {
+		return
+	}
+	task, err := queue.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		RespondWithBackendError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, task)
+}
+
+// RetryTask moves a dead task back to pending so it is attempted again.
+func (h *Handler) RetryTask(c *gin.Context) {
+	queue, ok := taskQueue(c)
+	if !ok {
+		return
+	}
+	task, err := queue.Retry(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		RespondWithBackendError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, task)
+}
+
+// enqueueTask queues a task and responds with it, 202 Accepted.
+func enqueueTask(c *gin.Context, taskType string, payload any) {
+	queue, ok := taskQueue(c)
+	if !ok {
+		return
+	}
+	task, err := queue.Enqueue(c.Request.Context(), taskType, payload)
+	if err != nil {
+		RespondWithBackendError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, task)
+}
+
+// taskQueue returns the task queue, or responds with an error when it is
+// not enabled.
+func taskQueue(c *gin.Context) (*tasks.Queue, bool) {
+	queue, err := objstore.Tasks()
+	switch {
+	case errors.Is(err, objstore.ErrTasksNotEnabled):
+		RespondWithError(c, http.StatusNotImplemented, "the task queue is not enabled on this server")
+		return nil, false
+	case err != nil:
+		RespondWithBackendError(c, err)
+		return nil, false
+	}
+	return queue, true
+}
+
+func isTasksPath(path string) bool {
+	return path == tasksPath || strings.HasPrefix(path, tasksPath+"/")
+}
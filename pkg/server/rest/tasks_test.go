@@ -0,0 +1,118 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+	"github.com/jeremyhahn/go-objstore/pkg/tasks"
+)
+
+func TestTasks(t *testing.T) {
+	handler := newTestHandler(t, memory.New())
+
+	router := gin.New()
+	router.POST("/archive", handler.Archive)
+	router.POST("/replication/trigger", handler.TriggerReplication)
+	router.GET("/tasks", handler.ListTasks)
+	router.GET("/tasks/:id", handler.GetTask)
+	router.POST("/tasks/:id/retry", handler.RetryTask)
+
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	archive := `{"key":"reports/q1.csv","destination_type":"local","destination_settings":{"path":"` + t.TempDir() + `"},"async":true}`
+
+	// The queue is not enabled yet
+	if w := serve("GET", "/tasks", ""); w.Code != http.StatusNotImplemented {
+		t.Errorf("ListTasks() status = %v, want %v", w.Code, http.StatusNotImplemented)
+	}
+	if w := serve("POST", "/archive", archive); w.Code != http.StatusNotImplemented {
+		t.Errorf("async Archive() status = %v, want %v", w.Code, http.StatusNotImplemented)
+	}
+
+	// Never lead, so queued tasks stay pending.
+	if _, err := objstore.EnableTasks(&tasks.Config{IsLeader: func() bool { return false }}); err != nil {
+		t.Fatalf("EnableTasks() error = %v", err)
+	}
+	defer objstore.Reset()
+
+	w := serve("POST", "/archive", archive)
+	var archived tasks.Task
+	if err := json.Unmarshal(w.Body.Bytes(), &archived); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if w.Code != http.StatusAccepted || archived.Type != objstore.TaskArchive || archived.State != tasks.StatePending {
+		t.Errorf("async Archive() = %d %+v", w.Code, archived)
+	}
+	if w := serve("POST", "/replication/trigger", `{"policy_id":"migrate","async":true}`); w.Code != http.StatusAccepted {
+		t.Errorf("async TriggerReplication() status = %v, want %v", w.Code, http.StatusAccepted)
+	}
+
+	w = serve("GET", "/tasks?state=pending", "")
+	var list TasksResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if w.Code != http.StatusOK || len(list.Tasks) != 2 || list.Tasks[1].Type != objstore.TaskReplicationSync {
+		t.Errorf("ListTasks() = %d %+v", w.Code, list)
+	}
+	var payload objstore.ReplicationSyncTask
+	if err := list.Tasks[1].Decode(&payload); err != nil || payload.PolicyID != "migrate" {
+		t.Errorf("replication task payload = %+v, %v", payload, err)
+	}
+	if w := serve("GET", "/tasks?state=bogus", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("ListTasks(bogus) status = %v, want %v", w.Code, http.StatusBadRequest)
+	}
+
+	if w := serve("GET", "/tasks/"+archived.ID, ""); w.Code != http.StatusOK {
+		t.Errorf("GetTask() status = %v, want %v", w.Code, http.StatusOK)
+	}
+	if w := serve("GET", "/tasks/missing", ""); w.Code != http.StatusNotFound {
+		t.Errorf("GetTask(missing) status = %v, want %v", w.Code, http.StatusNotFound)
+	}
+	// Only dead tasks can be retried.
+	if w := serve("POST", "/tasks/"+archived.ID+"/retry", ""); w.Code != http.StatusPreconditionFailed {
+		t.Errorf("RetryTask(pending) status = %v, want %v", w.Code, http.StatusPreconditionFailed)
+	}
+}
+
+func TestDeriveActionResourceTasks(t *testing.T) {
+	for _, tc := range []struct {
+		method, path, action string
+	}{
+		{http.MethodGet, "/api/v1/tasks", adapters.ActionRead},
+		{http.MethodGet, "/api/v1/tasks/abc", adapters.ActionRead},
+		{http.MethodPost, "/api/v1/tasks/abc/retry", adapters.ActionAdmin},
+	} {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(tc.method, tc.path, nil)
+		action, resource := deriveActionResource(c)
+		if action != tc.action || resource != adapters.ResourceTasks {
+			t.Errorf("deriveActionResource(%s %s) = %s, %s", tc.method, tc.path, action, resource)
+		}
+	}
+}
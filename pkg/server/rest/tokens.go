@@ -0,0 +1,101 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+)
+
+// tokensPath is the endpoint where principals mint scoped tokens.
+const tokensPath = "/api/v1/tokens"
+
+// TokenRequest is the request body for minting a scoped token
+type TokenRequest struct {
+	Prefix     string   `json:"prefix,omitempty" example:"uploads/job-42/"`
+	Actions    []string `json:"actions" binding:"required" example:"read,write"`
+	TTLSeconds int64    `json:"ttl_seconds,omitempty" example:"900"`
+} // @name TokenRequest
+
+// TokenResponse is a minted scoped token
+type TokenResponse struct {
+	Token     string   `json:"token"`
+	ExpiresAt string   `json:"expires_at" example:"2025-11-05T10:15:00Z"`
+	Prefix    string   `json:"prefix,omitempty" example:"uploads/job-42/"`
+	Actions   []string `json:"actions" example:"read,write"`
+} // @name TokenResponse
+
+// setupTokenRoutes mounts the scoped token endpoint. The route is exempt from
+// AuthorizationMiddleware because its permission depends on the body: the
+// caller must hold every requested action on the requested prefix.
+func setupTokenRoutes(router *gin.Engine, tokens *adapters.TokenService, authorizer adapters.Authorizer, logger adapters.Logger) {
+	router.POST(tokensPath, func(c *gin.Context) {
+		value, _ := c.Get(principalContextKey)
+		principal, _ := value.(*adapters.Principal)
+		if principal == nil {
+			RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+
+		var req TokenRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			RespondWithError(c, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+
+		for _, action := range req.Actions {
+			if err := authorizer.Authorize(c.Request.Context(), principal, action, req.Prefix); err != nil {
+				logger.Warn(c.Request.Context(), "Token request denied",
+					adapters.Field{Key: "error", Value: err.Error()},
+					adapters.Field{Key: "action", Value: action},
+					adapters.Field{Key: "prefix", Value: req.Prefix},
+					adapters.Field{Key: "principal_id", Value: principal.ID},
+				)
+				RespondWithError(c, http.StatusForbidden, "Forbidden")
+				return
+			}
+		}
+
+		scope := adapters.TokenScope{Prefix: req.Prefix, Actions: req.Actions}
+		token, expires, err := tokens.Issue(principal, scope, time.Duration(req.TTLSeconds)*time.Second)
+		switch {
+		case errors.Is(err, adapters.ErrInvalidTokenScope):
+			RespondWithError(c, http.StatusBadRequest, err.Error())
+			return
+		case errors.Is(err, adapters.ErrInsufficientPermissions):
+			RespondWithError(c, http.StatusForbidden, err.Error())
+			return
+		case err != nil:
+			RespondWithError(c, http.StatusInternalServerError, "failed to issue token")
+			return
+		}
+
+		logger.Info(c.Request.Context(), "Issued scoped token",
+			adapters.Field{Key: "principal_id", Value: principal.ID},
+			adapters.Field{Key: "prefix", Value: req.Prefix},
+			adapters.Field{Key: "actions", Value: req.Actions},
+			adapters.Field{Key: "expires_at", Value: expires},
+		)
+		c.JSON(http.StatusCreated, TokenResponse{
+			Token:     token,
+			ExpiresAt: expires.UTC().Format(time.RFC3339),
+			Prefix:    req.Prefix,
+			Actions:   req.Actions,
+		})
+	})
+}
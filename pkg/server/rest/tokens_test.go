@@ -0,0 +1,141 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+)
+
+// newTokenTestServer builds a server where "alice-secret" authenticates an
+// editor and tokens minted at /api/v1/tokens are accepted.
+func newTokenTestServer(t *testing.T) *gin.Engine {
+	t.Helper()
+	tokens, err := adapters.NewTokenService(&adapters.TokenServiceConfig{
+		Key: []byte("0123456789abcdef0123456789abcdef"),
+	})
+	if err != nil {
+		t.Fatalf("NewTokenService: %v", err)
+	}
+	static := adapters.NewBearerTokenAuthenticator(func(_ context.Context, token string) (*adapters.Principal, error) {
+		if token != "alice-secret" {
+			return nil, adapters.ErrInvalidCredentials
+		}
+		return &adapters.Principal{ID: "alice", Name: "Alice", Type: "user", Roles: []string{"editor"}}, nil
+	})
+
+	config := DefaultServerConfig()
+	config.Mode = gin.TestMode
+	config.TokenService = tokens
+	config.Authenticator = adapters.NewCompositeAuthenticator(tokens, static)
+	config.Authorizer = adapters.NewRBACAuthorizer(map[string][]string{
+		"editor": {adapters.ActionRead, adapters.ActionWrite, adapters.ActionList},
+	})
+	return newRESTServer(t, config).Router()
+}
+
+func doTokenRequest(router *gin.Engine, method, path, bearer, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestTokenEndpointDisabledByDefault(t *testing.T) {
+	config := DefaultServerConfig()
+	config.Mode = gin.TestMode
+	router := newRESTServer(t, config).Router()
+
+	w := doTokenRequest(router, http.MethodPost, "/api/v1/tokens", "", `{"actions":["read"]}`)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("POST /api/v1/tokens without TokenService = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestScopedTokenLifecycle(t *testing.T) {
+	router := newTokenTestServer(t)
+
+	w := doTokenRequest(router, http.MethodPost, "/api/v1/tokens", "alice-secret",
+		`{"prefix":"uploads/","actions":["read","write"],"ttl_seconds":60}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("mint token = %d (%s), want %d", w.Code, w.Body.String(), http.StatusCreated)
+	}
+	var resp TokenResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Token == "" || resp.ExpiresAt == "" || resp.Prefix != "uploads/" {
+		t.Fatalf("response = %+v, want token, expiry and prefix", resp)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		want   int
+	}{
+		{"write in prefix", http.MethodPut, "/api/v1/objects/uploads/a.txt", "data", http.StatusCreated},
+		{"read in prefix", http.MethodGet, "/api/v1/objects/uploads/a.txt", "", http.StatusOK},
+		{"write outside prefix", http.MethodPut, "/api/v1/objects/private/a.txt", "data", http.StatusForbidden},
+		{"list not in scope", http.MethodGet, "/api/v1/objects?prefix=uploads/", "", http.StatusForbidden},
+		{"policies not in scope", http.MethodGet, "/api/v1/policies", "", http.StatusForbidden},
+		{"token cannot mint", http.MethodPost, "/api/v1/tokens", `{"actions":["read"]}`, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doTokenRequest(router, tt.method, tt.path, resp.Token, tt.body)
+			if w.Code != tt.want {
+				t.Errorf("%s %s = %d (%s), want %d", tt.method, tt.path, w.Code, w.Body.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenEndpointRejects(t *testing.T) {
+	router := newTokenTestServer(t)
+
+	tests := []struct {
+		name   string
+		bearer string
+		body   string
+		want   int
+	}{
+		{"unauthenticated", "", `{"actions":["read"]}`, http.StatusUnauthorized},
+		{"missing actions", "alice-secret", `{"prefix":"a/"}`, http.StatusBadRequest},
+		{"action the caller lacks", "alice-secret", `{"actions":["delete"]}`, http.StatusForbidden},
+		{"lifetime over maximum", "alice-secret", `{"actions":["read"],"ttl_seconds":86400}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doTokenRequest(router, http.MethodPost, "/api/v1/tokens", tt.bearer, tt.body)
+			if w.Code != tt.want {
+				t.Errorf("POST /api/v1/tokens = %d (%s), want %d", w.Code, w.Body.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"context"
+	"embed"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/server/middleware"
+)
+
+// uiPath is the mount point of the embedded admin UI.
+const uiPath = "/ui"
+
+// uiOIDCConfigPath is the file the UI fetches to discover single sign-on.
+const uiOIDCConfigPath = uiPath + "/oidc.json"
+
+//go:embed ui
+var uiAssets embed.FS
+
+// UIOIDCConfig enables OpenID Connect sign-in for the admin UI. The UI runs
+// the authorization code flow with PKCE against Issuer as a public client and
+// sends the resulting token as its bearer token, so the server's
+// Authenticator (typically an adapters.OIDCAuthenticator for the same issuer)
+// must accept it. When set, the UI's static assets are served without
+// authentication so that a browser can load the sign-in page; the API remains
+// protected as usual.
+type UIOIDCConfig struct {
+	// Issuer is the OpenID provider URL; the UI discovers its endpoints from
+	// Issuer + "/.well-known/openid-configuration".
+	Issuer string
+
+	// ClientID is the public client registered with the provider. Its
+	// redirect URI must be the UI URL, e.g. https://objstore.example.com/ui/.
+	ClientID string
+
+	// Scopes requested at sign-in (default: "openid profile email").
+	Scopes string
+
+	// UseAccessToken sends the access token instead of the ID token. Use it
+	// when the provider issues JWT access tokens whose audience is the API
+	// rather than the UI client.
+	UseAccessToken bool
+}
+
+// SetupUIRoutes mounts the embedded single-page admin UI at /ui. The page is
+// static; it drives the server through the same authenticated /api/v1 routes
+// as any other client, so it grants no access of its own. Serving the page
+// requires authentication like the Swagger UI.
+func SetupUIRoutes(router *gin.Engine) {
+	setupUIRoutes(router, nil)
+}
+
+// setupUIRoutes mounts the UI and, when oidc is set, the sign-in
+// configuration it reads from /ui/oidc.json.
+func setupUIRoutes(router *gin.Engine, oidc *UIOIDCConfig) {
+	assets, err := fs.Sub(uiAssets, "ui")
+	if err != nil {
+		// The directory is embedded at build time; a failure here is a
+		// packaging bug, not a runtime condition.
+		panic(err)
+	}
+	fileServer := http.StripPrefix(uiPath, http.FileServer(http.FS(assets)))
+
+	serve := func(c *gin.Context) {
+		if c.Request.URL.Path == uiOIDCConfigPath {
+			if oidc == nil {
+				c.Status(http.StatusNotFound)
+				return
+			}
+			c.JSON(http.StatusOK, oidc.document())
+			return
+		}
+		fileServer.ServeHTTP(c.Writer, c.Request)
+	}
+	router.GET(uiPath+"/*filepath", serve)
+	router.HEAD(uiPath+"/*filepath", serve)
+}
+
+// document returns the sign-in configuration published to the UI.
+func (o *UIOIDCConfig) document() gin.H {
+	scopes := o.Scopes
+	if scopes == "" {
+		scopes = "openid profile email"
+	}
+	token := "id_token"
+	if o.UseAccessToken {
+		token = "access_token"
+	}
+	return gin.H{
+		"issuer":    o.Issuer,
+		"client_id": o.ClientID,
+		"scopes":    scopes,
+		"token":     token,
+	}
+}
+
+// securityHeaders returns config with the issuer's origin added to the
+// Content-Security-Policy connect-src, so the UI can reach the provider's
+// discovery and token endpoints. A policy that already sets connect-src is
+// left unchanged.
+func (o *UIOIDCConfig) securityHeaders(config *middleware.SecurityHeadersConfig) *middleware.SecurityHeadersConfig {
+	if config == nil {
+		config = middleware.DefaultSecurityHeadersConfig()
+	}
+	issuer, err := url.Parse(o.Issuer)
+	if err != nil || issuer.Scheme == "" || issuer.Host == "" ||
+		config.ContentSecurityPolicy == "" || strings.Contains(config.ContentSecurityPolicy, "connect-src") {
+		return config
+	}
+	withIssuer := *config
+	withIssuer.ContentSecurityPolicy += "; connect-src 'self' " + issuer.Scheme + "://" + issuer.Host
+	return &withIssuer
+}
+
+// publicUIAuthenticator lets unauthenticated browsers load the admin UI so
+// they can sign in. Reads of the UI's static assets authenticate as the
+// anonymous principal; every other request is passed to the wrapped
+// authenticator.
+type publicUIAuthenticator struct {
+	adapters.Authenticator
+	anonymous adapters.Authenticator
+}
+
+func newPublicUIAuthenticator(next adapters.Authenticator) *publicUIAuthenticator {
+	return &publicUIAuthenticator{Authenticator: next, anonymous: adapters.NewNoOpAuthenticator()}
+}
+
+// AuthenticateHTTP implements adapters.Authenticator.
+func (a *publicUIAuthenticator) AuthenticateHTTP(ctx context.Context, r *http.Request) (*adapters.Principal, error) {
+	if (r.Method == http.MethodGet || r.Method == http.MethodHead) &&
+		(r.URL.Path == uiPath || strings.HasPrefix(r.URL.Path, uiPath+"/")) {
+		return a.anonymous.AuthenticateHTTP(ctx, r)
+	}
+	return a.Authenticator.AuthenticateHTTP(ctx, r)
+}
@@ -0,0 +1,507 @@
+// Admin UI for the go-objstore REST server. Plain browser JavaScript with no
+// build step; every call goes through the same authenticated /api/v1 routes
+// as any other client.
+'use strict';
+
+const API = '/api/v1';
+const TOKEN_KEY = 'objstore.token';
+const OIDC_KEY = 'objstore.oidc';
+
+const $ = (id) => document.getElementById(id);
+
+const state = {
+  prefix: '',
+};
+
+// ---- HTTP -------------------------------------------------------------------
+
+function authHeaders(extra) {
+  const headers = Object.assign({}, extra);
+  const token = sessionStorage.getItem(TOKEN_KEY);
+  if (token) headers['Authorization'] = 'Bearer ' + token;
+  return headers;
+}
+
+async function request(method, path, { body, headers, query } = {}) {
+  let url = path;
+  if (query) {
+    const params = new URLSearchParams();
+    for (const [k, v] of Object.entries(query)) {
+      if (v !== undefined && v !== null && v !== '') params.set(k, String(v));
+    }
+    const qs = params.toString();
+    if (qs) url += '?' + qs;
+  }
+  const resp = await fetch(url, { method, headers: authHeaders(headers), body });
+  if (!resp.ok) {
+    let message = resp.status + ' ' + resp.statusText;
+    try {
+      const err = await resp.json();
+      if (err.message) message += ': ' + err.message;
+    } catch (e) {
+      // not JSON
+    }
+    throw new Error(message);
+  }
+  return resp;
+}
+
+async function json(method, path, opts = {}) {
+  if (opts.json !== undefined) {
+    opts.body = JSON.stringify(opts.json);
+    opts.headers = Object.assign({ 'Content-Type': 'application/json' }, opts.headers);
+  }
+  const resp = await request(method, path, opts);
+  return resp.status === 204 ? null : resp.json();
+}
+
+function encodeKey(key) {
+  return key.split('/').map(encodeURIComponent).join('/');
+}
+
+// ---- helpers ----------------------------------------------------------------
+
+function showStatus(message, isError) {
+  const el = $('status');
+  el.textContent = message;
+  el.className = isError ? 'error' : '';
+  el.hidden = false;
+  clearTimeout(showStatus.timer);
+  showStatus.timer = setTimeout(() => { el.hidden = true; }, isError ? 8000 : 3000);
+}
+
+// guard runs fn and reports any error in the status bar.
+function guard(fn) {
+  return async (...args) => {
+    try {
+      await fn(...args);
+    } catch (err) {
+      showStatus(err.message, true);
+    }
+  };
+}
+
+function el(tag, attrs = {}, ...children) {
+  const node = document.createElement(tag);
+  for (const [k, v] of Object.entries(attrs)) {
+    if (k === 'onclick') node.addEventListener('click', guard(v));
+    else if (k === 'className') node.className = v;
+    else node.setAttribute(k, v);
+  }
+  for (const child of children) {
+    node.append(child instanceof Node ? child : document.createTextNode(child ?? ''));
+  }
+  return node;
+}
+
+function formatSize(n) {
+  if (n === undefined || n === null) return '';
+  const units = ['B', 'KiB', 'MiB', 'GiB', 'TiB'];
+  let i = 0;
+  while (n >= 1024 && i < units.length - 1) {
+    n /= 1024;
+    i++;
+  }
+  return (i === 0 ? n : n.toFixed(1)) + ' ' + units[i];
+}
+
+function formatTime(value) {
+  if (!value) return '';
+  const d = new Date(value);
+  return isNaN(d) ? value : d.toLocaleString();
+}
+
+// parseSettings turns "k=v" lines into an object.
+function parseSettings(text) {
+  const out = {};
+  for (const line of text.split('\n')) {
+    const i = line.indexOf('=');
+    if (i > 0) out[line.slice(0, i).trim()] = line.slice(i + 1).trim();
+  }
+  return out;
+}
+
+// ---- objects ----------------------------------------------------------------
+
+function renderBreadcrumbs() {
+  const nav = $('breadcrumbs');
+  nav.replaceChildren(el('button', { className: 'link', onclick: () => openPrefix('') }, 'root'));
+  let acc = '';
+  for (const part of state.prefix.split('/').filter(Boolean)) {
+    acc += part + '/';
+    const target = acc;
+    nav.append(el('button', { className: 'link', onclick: () => openPrefix(target) }, part));
+  }
+}
+
+async function openPrefix(prefix) {
+  state.prefix = prefix;
+  $('objects').replaceChildren();
+  $('metadata').hidden = true;
+  renderBreadcrumbs();
+  await loadObjects();
+}
+
+async function loadObjects() {
+  const dir = await json('GET', API + '/browse', {
+    query: { prefix: state.prefix, totals: true },
+  });
+  const rows = $('objects');
+  for (const f of dir.folders || []) {
+    rows.append(el('tr', {},
+      el('td', {}, el('button', { className: 'link', onclick: () => openPrefix(f.prefix) }, f.prefix.slice(state.prefix.length))),
+      el('td', { className: 'num' }, formatSize(f.bytes)),
+      el('td', {}, f.objects + (f.objects === 1 ? ' object' : ' objects')),
+      el('td')));
+  }
+  for (const obj of dir.objects || []) {
+    rows.append(el('tr', {},
+      el('td', {}, obj.key.slice(state.prefix.length)),
+      el('td', { className: 'num' }, formatSize(obj.size)),
+      el('td', {}, formatTime(obj.modified)),
+      el('td', { className: 'actions' },
+        el('button', { className: 'link', onclick: () => showMetadata(obj.key) }, 'metadata'), ' ',
+        el('button', { className: 'link', onclick: () => download(obj.key) }, 'download'), ' ',
+        el('button', { className: 'link danger', onclick: () => removeObject(obj.key) }, 'delete'))));
+  }
+  if (rows.children.length === 0) {
+    rows.append(el('tr', {}, el('td', { colspan: '4' }, 'No objects.')));
+  } else {
+    rows.append(el('tr', { className: 'total' },
+      el('td', {}, 'Total'),
+      el('td', { className: 'num' }, formatSize(dir.total_bytes)),
+      el('td', {}, dir.total_objects + (dir.total_objects === 1 ? ' object' : ' objects')),
+      el('td')));
+  }
+}
+
+async function showMetadata(key) {
+  const meta = await json('GET', API + '/metadata/' + encodeKey(key));
+  $('metadata-key').textContent = key;
+  $('metadata-body').textContent = JSON.stringify(meta, null, 2);
+  $('metadata').hidden = false;
+}
+
+async function download(key) {
+  const resp = await request('GET', API + '/objects/' + encodeKey(key));
+  const url = URL.createObjectURL(await resp.blob());
+  const a = el('a', { href: url, download: key.split('/').pop() });
+  document.body.append(a);
+  a.click();
+  a.remove();
+  URL.revokeObjectURL(url);
+}
+
+async function removeObject(key) {
+  if (!confirm('Delete ' + key + '?')) return;
+  await request('DELETE', API + '/objects/' + encodeKey(key));
+  showStatus('Deleted ' + key);
+  await openPrefix(state.prefix);
+}
+
+async function upload(event) {
+  event.preventDefault();
+  const file = $('upload-file').files[0];
+  if (!file) return;
+  const key = $('upload-key').value.trim() || state.prefix + file.name;
+  await request('PUT', API + '/objects/' + encodeKey(key), {
+    body: file,
+    headers: { 'Content-Type': file.type || 'application/octet-stream' },
+  });
+  event.target.reset();
+  showStatus('Uploaded ' + key);
+  await openPrefix(state.prefix);
+}
+
+// ---- lifecycle --------------------------------------------------------------
+
+async function loadPolicies() {
+  const resp = await json('GET', API + '/policies');
+  const rows = $('policies');
+  rows.replaceChildren();
+  for (const p of resp.policies || []) {
+    rows.append(el('tr', {},
+      el('td', {}, p.id),
+      el('td', {}, p.prefix || ''),
+      el('td', { className: 'num' }, String(p.retention_seconds)),
+      el('td', {}, p.action + (p.storage_class ? ' → ' + p.storage_class : '')),
+      el('td', {}, p.destination_type || ''),
+      el('td', { className: 'actions' },
+        el('button', { className: 'link danger', onclick: () => removePolicy(p.id) }, 'delete'))));
+  }
+  if (rows.children.length === 0) {
+    rows.append(el('tr', {}, el('td', { colspan: '6' }, 'No lifecycle policies.')));
+  }
+}
+
+async function addPolicy(event) {
+  event.preventDefault();
+  const f = new FormData(event.target);
+  await json('POST', API + '/policies', {
+    json: {
+      id: f.get('id'),
+      prefix: f.get('prefix'),
+      retention_seconds: Number(f.get('retention_seconds')),
+      action: f.get('action'),
+      destination_type: f.get('destination_type'),
+      storage_class: f.get('storage_class'),
+    },
+  });
+  event.target.reset();
+  showStatus('Lifecycle policy added');
+  await loadPolicies();
+}
+
+async function removePolicy(id) {
+  if (!confirm('Remove lifecycle policy ' + id + '?')) return;
+  await request('DELETE', API + '/policies/' + encodeURIComponent(id));
+  showStatus('Lifecycle policy removed');
+  await loadPolicies();
+}
+
+async function applyPolicies() {
+  const resp = await json('POST', API + '/policies/apply');
+  showStatus(resp.message + ' (' + resp.objects_processed + ' objects processed)');
+}
+
+// ---- replication ------------------------------------------------------------
+
+async function loadReplication() {
+  const resp = await json('GET', API + '/replication/policies');
+  const rows = $('replication');
+  rows.replaceChildren();
+  for (const p of resp.policies || []) {
+    rows.append(el('tr', {},
+      el('td', {}, p.id),
+      el('td', {}, p.source_backend + (p.source_prefix ? ':' + p.source_prefix : '')),
+      el('td', {}, p.destination_backend),
+      el('td', { className: 'num' }, String(p.check_interval_seconds)),
+      el('td', {}, p.replication_mode || ''),
+      el('td', {}, p.enabled ? 'yes' : 'no'),
+      el('td', {}, formatTime(p.last_sync_time)),
+      el('td', { className: 'actions' },
+        el('button', { className: 'link', onclick: () => replicationStatus(p.id) }, 'status'), ' ',
+        el('button', { className: 'link', onclick: () => sync(p.id) }, 'sync'), ' ',
+        el('button', { className: 'link danger', onclick: () => removeReplication(p.id) }, 'delete'))));
+  }
+  if (rows.children.length === 0) {
+    rows.append(el('tr', {}, el('td', { colspan: '8' }, 'No replication policies.')));
+  }
+}
+
+async function addReplication(event) {
+  event.preventDefault();
+  const f = new FormData(event.target);
+  await json('POST', API + '/replication/policies', {
+    json: {
+      id: f.get('id'),
+      source_backend: f.get('source_backend'),
+      source_prefix: f.get('source_prefix'),
+      destination_backend: f.get('destination_backend'),
+      destination_settings: parseSettings(f.get('destination_settings')),
+      check_interval_seconds: Number(f.get('check_interval_seconds')),
+      replication_mode: f.get('replication_mode'),
+      enabled: f.get('enabled') === 'on',
+    },
+  });
+  event.target.reset();
+  showStatus('Replication policy added');
+  await loadReplication();
+}
+
+async function removeReplication(id) {
+  if (!confirm('Remove replication policy ' + id + '?')) return;
+  await request('DELETE', API + '/replication/policies/' + encodeURIComponent(id));
+  showStatus('Replication policy removed');
+  await loadReplication();
+}
+
+async function sync(id) {
+  const resp = await json('POST', API + '/replication/trigger', { json: id ? { policy_id: id } : {} });
+  const r = resp.result || {};
+  showStatus('Sync complete: ' + r.synced + ' synced, ' + r.deleted + ' deleted, ' + r.failed + ' failed in ' + r.duration);
+  await loadReplication();
+}
+
+async function replicationStatus(id) {
+  const resp = await json('GET', API + '/replication/status/' + encodeURIComponent(id));
+  $('replication-status-id').textContent = id;
+  $('replication-status-body').textContent = JSON.stringify(resp, null, 2);
+  $('replication-status').hidden = false;
+}
+
+// ---- health -----------------------------------------------------------------
+
+let metricsText = '';
+
+function renderMetrics() {
+  const filter = $('metrics-filter').value.trim();
+  const lines = metricsText.split('\n').filter((l) => !l.startsWith('#') && l.includes(filter));
+  $('metrics').textContent = lines.join('\n');
+}
+
+async function loadHealth() {
+  const health = await json('GET', '/health');
+  $('health').textContent = JSON.stringify(health, null, 2);
+  try {
+    metricsText = await (await request('GET', '/metrics')).text();
+  } catch (err) {
+    metricsText = '# ' + err.message;
+    $('metrics').textContent = err.message;
+    return;
+  }
+  renderMetrics();
+}
+
+// ---- single sign-on ---------------------------------------------------------
+//
+// When the server publishes oidc.json, the UI signs in with the OpenID Connect
+// authorization code flow with PKCE and uses the resulting token as its bearer
+// token. Otherwise the token form is used.
+
+function base64url(bytes) {
+  return btoa(String.fromCharCode(...bytes)).replace(/\+/g, '-').replace(/\//g, '_').replace(/=+$/, '');
+}
+
+function randomString() {
+  return base64url(crypto.getRandomValues(new Uint8Array(32)));
+}
+
+function redirectURI() {
+  return location.origin + location.pathname;
+}
+
+async function loadOIDC() {
+  const resp = await fetch('oidc.json');
+  if (!resp.ok) return null;
+  const config = await resp.json();
+  const discovery = await fetch(config.issuer.replace(/\/$/, '') + '/.well-known/openid-configuration');
+  if (!discovery.ok) throw new Error('Sign-in unavailable: provider discovery returned ' + discovery.status);
+  return Object.assign(config, await discovery.json(), { issuer: config.issuer });
+}
+
+async function signIn(oidc) {
+  const verifier = randomString();
+  const stateValue = randomString();
+  sessionStorage.setItem(OIDC_KEY, JSON.stringify({ verifier, state: stateValue, hash: location.hash }));
+  const challenge = base64url(new Uint8Array(
+    await crypto.subtle.digest('SHA-256', new TextEncoder().encode(verifier))));
+  const params = new URLSearchParams({
+    response_type: 'code',
+    client_id: oidc.client_id,
+    redirect_uri: redirectURI(),
+    scope: oidc.scopes,
+    state: stateValue,
+    code_challenge: challenge,
+    code_challenge_method: 'S256',
+  });
+  location.assign(oidc.authorization_endpoint + '?' + params);
+}
+
+// completeSignIn exchanges the authorization code in the URL, if any, for a
+// token.
+async function completeSignIn(oidc) {
+  const params = new URLSearchParams(location.search);
+  if (!params.has('code') && !params.has('error')) return;
+  const saved = JSON.parse(sessionStorage.getItem(OIDC_KEY) || '{}');
+  sessionStorage.removeItem(OIDC_KEY);
+  history.replaceState(null, '', location.pathname + (saved.hash || ''));
+
+  if (params.has('error')) {
+    throw new Error('Sign-in failed: ' + (params.get('error_description') || params.get('error')));
+  }
+  if (!saved.state || params.get('state') !== saved.state) {
+    throw new Error('Sign-in failed: state mismatch');
+  }
+  const resp = await fetch(oidc.token_endpoint, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/x-www-form-urlencoded' },
+    body: new URLSearchParams({
+      grant_type: 'authorization_code',
+      code: params.get('code'),
+      redirect_uri: redirectURI(),
+      client_id: oidc.client_id,
+      code_verifier: saved.verifier,
+    }),
+  });
+  if (!resp.ok) throw new Error('Sign-in failed: token endpoint returned ' + resp.status);
+  const tokens = await resp.json();
+  if (!tokens[oidc.token]) throw new Error('Sign-in failed: no ' + oidc.token + ' in token response');
+  sessionStorage.setItem(TOKEN_KEY, tokens[oidc.token]);
+}
+
+// tokenSubject returns the display name from a JWT without verifying it;
+// the server verifies the token on every request.
+function tokenSubject(token) {
+  try {
+    const claims = JSON.parse(atob(token.split('.')[1].replace(/-/g, '+').replace(/_/g, '/')));
+    return claims.preferred_username || claims.email || claims.sub || '';
+  } catch (err) {
+    return '';
+  }
+}
+
+async function setupSSO() {
+  const oidc = await loadOIDC();
+  if (!oidc) return;
+  $('token-form').hidden = true;
+  $('sso').hidden = false;
+  $('sign-in').addEventListener('click', guard(() => signIn(oidc)));
+  $('sign-out').addEventListener('click', () => {
+    sessionStorage.removeItem(TOKEN_KEY);
+    location.reload();
+  });
+  await completeSignIn(oidc);
+
+  const token = sessionStorage.getItem(TOKEN_KEY);
+  $('sign-in').hidden = !!token;
+  $('sign-out').hidden = !token;
+  $('sso-user').textContent = token ? tokenSubject(token) : '';
+}
+
+// ---- wiring -----------------------------------------------------------------
+
+const loaders = {
+  objects: () => openPrefix(state.prefix),
+  lifecycle: loadPolicies,
+  replication: loadReplication,
+  health: loadHealth,
+};
+
+function showView(name) {
+  for (const button of document.querySelectorAll('header nav button')) {
+    button.classList.toggle('active', button.dataset.view === name);
+  }
+  for (const view of Object.keys(loaders)) {
+    $('view-' + view).hidden = view !== name;
+  }
+  location.hash = name;
+  return loaders[name]();
+}
+
+document.addEventListener('DOMContentLoaded', async () => {
+  await guard(setupSSO)();
+  $('token').value = sessionStorage.getItem(TOKEN_KEY) || '';
+  $('token-form').addEventListener('submit', guard(async (event) => {
+    event.preventDefault();
+    const token = $('token').value.trim();
+    if (token) sessionStorage.setItem(TOKEN_KEY, token);
+    else sessionStorage.removeItem(TOKEN_KEY);
+    showStatus('Token saved for this browser session');
+    await showView(location.hash.slice(1) || 'objects');
+  }));
+
+  for (const button of document.querySelectorAll('header nav button')) {
+    button.addEventListener('click', guard(() => showView(button.dataset.view)));
+  }
+  $('upload-form').addEventListener('submit', guard(upload));
+  $('policy-form').addEventListener('submit', guard(addPolicy));
+  $('apply-policies').addEventListener('click', guard(applyPolicies));
+  $('replication-form').addEventListener('submit', guard(addReplication));
+  $('sync-all').addEventListener('click', guard(() => sync('')));
+  $('refresh-health').addEventListener('click', guard(loadHealth));
+  $('metrics-filter').addEventListener('input', renderMetrics);
+
+  const initial = location.hash.slice(1);
+  guard(showView)(loaders[initial] ? initial : 'objects');
+});
@@ -0,0 +1,178 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+)
+
+func TestUIDisabledByDefault(t *testing.T) {
+	config := DefaultServerConfig()
+	config.Mode = gin.TestMode
+	router := newRESTServer(t, config).Router()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ui/", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("GET /ui/ without EnableUI = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestUIServesAssets(t *testing.T) {
+	config := DefaultServerConfig()
+	config.Mode = gin.TestMode
+	config.EnableUI = true
+	router := newRESTServer(t, config).Router()
+
+	tests := []struct {
+		path        string
+		contentType string
+		contains    string
+	}{
+		{"/ui/", "text/html", `<script src="app.js">`},
+		{"/ui/app.js", "javascript", "/api/v1"},
+		{"/ui/style.css", "text/css", "--accent"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if w.Code != http.StatusOK {
+			t.Errorf("GET %s = %d, want %d", tt.path, w.Code, http.StatusOK)
+			continue
+		}
+		if ct := w.Header().Get("Content-Type"); !strings.Contains(ct, tt.contentType) {
+			t.Errorf("GET %s Content-Type = %q, want %q", tt.path, ct, tt.contentType)
+		}
+		if !strings.Contains(w.Body.String(), tt.contains) {
+			t.Errorf("GET %s body missing %q", tt.path, tt.contains)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ui", nil))
+	if w.Code != http.StatusMovedPermanently || w.Header().Get("Location") != "/ui/" {
+		t.Errorf("GET /ui = %d (Location %q), want redirect to /ui/", w.Code, w.Header().Get("Location"))
+	}
+}
+
+func TestUIRequiresAuthentication(t *testing.T) {
+	config := DefaultServerConfig()
+	config.Mode = gin.TestMode
+	config.EnableUI = true
+	config.Authenticator = denyAllAuthenticator{}
+	router := newRESTServer(t, config).Router()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ui/", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("GET /ui/ behind deny-all authenticator = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
+
+// TestUIGrantsNoAccess verifies that serving the UI does not widen access: a
+// principal with no permissions can load the page but not the data it shows.
+func TestUIGrantsNoAccess(t *testing.T) {
+	config := DefaultServerConfig()
+	config.Mode = gin.TestMode
+	config.EnableUI = true
+	config.Authenticator = readerOnlyAuthenticator{}
+	config.Authorizer = adapters.NewRBACAuthorizer(map[string][]string{})
+	router := newRESTServer(t, config).Router()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ui/", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("GET /ui/ for authenticated principal = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/objects", nil))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("GET /api/v1/objects for principal without permissions = %d, want %d", w.Code, http.StatusForbidden)
+	}
+}
+
+func TestUIOIDCConfigNotFoundWithoutSSO(t *testing.T) {
+	config := DefaultServerConfig()
+	config.Mode = gin.TestMode
+	config.EnableUI = true
+	router := newRESTServer(t, config).Router()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ui/oidc.json", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("GET /ui/oidc.json without UIOIDC = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+// TestUIOIDCSignIn verifies that with single sign-on the UI loads without
+// credentials and publishes its sign-in configuration, while the API still
+// requires authentication.
+func TestUIOIDCSignIn(t *testing.T) {
+	config := DefaultServerConfig()
+	config.Mode = gin.TestMode
+	config.EnableUI = true
+	config.Authenticator = denyAllAuthenticator{}
+	config.UIOIDC = &UIOIDCConfig{Issuer: "https://idp.example.com/realms/objstore", ClientID: "objstore-ui"}
+	router := newRESTServer(t, config).Router()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ui/", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("GET /ui/ with UIOIDC = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	if csp := w.Header().Get("Content-Security-Policy"); !strings.Contains(csp, "connect-src 'self' https://idp.example.com") {
+		t.Errorf("Content-Security-Policy = %q, want connect-src allowing the issuer", csp)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ui/oidc.json", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /ui/oidc.json = %d, want %d", w.Code, http.StatusOK)
+	}
+	var doc map[string]string
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("decode oidc.json: %v", err)
+	}
+	want := map[string]string{
+		"issuer":    "https://idp.example.com/realms/objstore",
+		"client_id": "objstore-ui",
+		"scopes":    "openid profile email",
+		"token":     "id_token",
+	}
+	for k, v := range want {
+		if doc[k] != v {
+			t.Errorf("oidc.json %s = %q, want %q", k, doc[k], v)
+		}
+	}
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/api/v1/objects", nil),
+		httptest.NewRequest(http.MethodPut, "/ui/app.js", strings.NewReader("x")),
+	} {
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("%s %s with UIOIDC = %d, want %d", req.Method, req.URL.Path, w.Code, http.StatusUnauthorized)
+		}
+	}
+}
@@ -0,0 +1,289 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/audit"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+	"github.com/jeremyhahn/go-objstore/pkg/upload"
+)
+
+// uploadsPath is the prefix of the upload session API. Creating a session
+// is authorized as a write of its key; the routes of an existing session
+// carry only its ID, so the handlers authorize the write themselves.
+const uploadsPath = "/api/v1/uploads"
+
+// headerChunkSHA256 carries the hex SHA-256 digest of an uploaded chunk.
+const headerChunkSHA256 = "X-Chunk-SHA256"
+
+// CreateUploadRequest is the optional body when creating an upload session
+type CreateUploadRequest struct {
+	TTLSeconds      int64             `json:"ttl_seconds,omitempty" example:"86400"`
+	Size            int64             `json:"size,omitempty" example:"1073741824"`
+	ContentType     string            `json:"content_type,omitempty" example:"video/mp4"`
+	ContentEncoding string            `json:"content_encoding,omitempty" example:"gzip"`
+	Metadata        map[string]string `json:"metadata,omitempty"`
+} // @name CreateUploadRequest
+
+// CompleteUploadRequest is the optional body when completing an upload
+// session
+type CompleteUploadRequest struct {
+	SHA256 string `json:"sha256,omitempty" example:"b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"`
+} // @name CompleteUploadRequest
+
+// UploadChunkResponse is a stored chunk of an upload session
+type UploadChunkResponse struct {
+	Number int    `json:"number" example:"1"`
+	Size   int64  `json:"size" example:"8388608"`
+	SHA256 string `json:"sha256" example:"b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"`
+} // @name UploadChunk
+
+// UploadSessionResponse is an upload session with the chunks received so
+// far
+type UploadSessionResponse struct {
+	ID              string                `json:"id" example:"3f2a9c1e5b7d4e6f8a0b1c2d3e4f5a6b"`
+	Key             string                `json:"key" example:"videos/raw.mp4"`
+	CreatedBy       string                `json:"created_by,omitempty" example:"alice"`
+	CreatedAt       string                `json:"created_at" example:"2025-11-05T10:00:00Z"`
+	ExpiresAt       string                `json:"expires_at" example:"2025-11-06T10:00:00Z"`
+	Size            int64                 `json:"size,omitempty" example:"1073741824"`
+	ContentType     string                `json:"content_type,omitempty" example:"video/mp4"`
+	ContentEncoding string                `json:"content_encoding,omitempty" example:"gzip"`
+	Metadata        map[string]string     `json:"metadata,omitempty"`
+	Chunks          []UploadChunkResponse `json:"chunks"`
+	Received        int64                 `json:"received" example:"16777216"`
+} // @name UploadSession
+
+// CreateUpload starts an upload session for an object
+func (h *Handler) CreateUpload(c *gin.Context) {
+	key := strings.TrimLeft(c.Param(keyField), "/")
+	if key == "" {
+		RespondWithError(c, http.StatusBadRequest, "key parameter is required")
+		return
+	}
+
+	var body CreateUploadRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			RespondWithError(c, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+	}
+
+	manager, err := objstore.Uploads(h.backend)
+	if err != nil {
+		respondWithUploadError(c, err)
+		return
+	}
+	session, err := manager.Create(c.Request.Context(), key, upload.Options{
+		TTL:             time.Duration(body.TTLSeconds) * time.Second,
+		Size:            body.Size,
+		ContentType:     body.ContentType,
+		ContentEncoding: body.ContentEncoding,
+		Metadata:        body.Metadata,
+	})
+	if err != nil {
+		respondWithUploadError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, uploadSessionResponse(session))
+}
+
+// GetUpload returns an upload session with the chunks received so far
+func (h *Handler) GetUpload(c *gin.Context) {
+	manager, _, ok := h.uploadSession(c)
+	if !ok {
+		return
+	}
+
+	session, err := manager.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondWithUploadError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, uploadSessionResponse(session))
+}
+
+// PutUploadChunk stores a numbered chunk of an upload session, replacing
+// any earlier upload of it. The chunk is kept only if it matches the
+// digest in the X-Chunk-SHA256 header.
+func (h *Handler) PutUploadChunk(c *gin.Context) {
+	number, err := strconv.Atoi(c.Param("number"))
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "chunk number must be an integer")
+		return
+	}
+	sum := c.GetHeader(headerChunkSHA256)
+	if sum == "" {
+		RespondWithError(c, http.StatusBadRequest, headerChunkSHA256+" header is required")
+		return
+	}
+
+	manager, _, ok := h.uploadSession(c)
+	if !ok {
+		return
+	}
+	chunk, err := manager.PutChunk(c.Request.Context(), c.Param("id"), number, c.Request.Body, sum)
+	if err != nil {
+		respondWithUploadError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, UploadChunkResponse{Number: chunk.Number, Size: chunk.Size, SHA256: chunk.SHA256})
+}
+
+// CompleteUpload assembles the chunks of an upload session into its object
+// and ends the session
+func (h *Handler) CompleteUpload(c *gin.Context) {
+	_, session, ok := h.uploadSession(c)
+	if !ok {
+		return
+	}
+
+	var body CompleteUploadRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			RespondWithError(c, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+	completed, err := objstore.CompleteUpload(ctx, h.backend, session.ID, body.SHA256)
+
+	auditLogger := audit.GetAuditLogger(ctx)
+	principal, userID := extractPrincipal(c)
+	requestID := audit.GetRequestID(ctx)
+	if err != nil {
+		_ = auditLogger.LogObjectMutation(ctx, audit.EventObjectCreated,
+			userID, principal, h.backend, session.Key, c.ClientIP(), requestID, 0,
+			audit.ResultFailure, err)
+		respondWithUploadError(c, err)
+		return
+	}
+	_ = auditLogger.LogObjectMutation(ctx, audit.EventObjectCreated,
+		userID, principal, h.backend, session.Key, c.ClientIP(), requestID, completed.Received(),
+		audit.ResultSuccess, nil)
+
+	var etag string
+	if stored, metaErr := objstore.GetMetadata(ctx, h.keyRef(session.Key)); metaErr == nil && stored != nil && stored.ETag != "" {
+		etag = stored.ETag
+		c.Header("ETag", etag)
+	}
+	RespondWithSuccess(c, http.StatusCreated, "object uploaded successfully", gin.H{keyField: session.Key, "etag": etag})
+}
+
+// AbortUpload ends an upload session and deletes its chunks
+func (h *Handler) AbortUpload(c *gin.Context) {
+	manager, _, ok := h.uploadSession(c)
+	if !ok {
+		return
+	}
+
+	if err := manager.Remove(c.Request.Context(), c.Param("id")); err != nil {
+		respondWithUploadError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// uploadSession returns the upload manager and the session named by the
+// id route parameter after checking that the caller may write the
+// session's key. It responds itself and returns false otherwise.
+func (h *Handler) uploadSession(c *gin.Context) (*upload.Manager, *upload.Session, bool) {
+	manager, err := objstore.Uploads(h.backend)
+	if err != nil {
+		respondWithUploadError(c, err)
+		return nil, nil, false
+	}
+	session, err := manager.Lookup(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondWithUploadError(c, err)
+		return nil, nil, false
+	}
+	if h.authorizer == nil {
+		return manager, session, true
+	}
+
+	value, _ := c.Get(principalContextKey)
+	principal, _ := value.(*adapters.Principal)
+	if principal == nil {
+		RespondWithError(c, http.StatusForbidden, "Forbidden")
+		return nil, nil, false
+	}
+	if err := h.authorizer.Authorize(c.Request.Context(), principal, adapters.ActionWrite, session.Key); err != nil {
+		RespondWithError(c, http.StatusForbidden, "Forbidden")
+		return nil, nil, false
+	}
+	return manager, session, true
+}
+
+// isUploadsPath reports whether path belongs to the upload session API.
+func isUploadsPath(path string) bool {
+	return path == uploadsPath || strings.HasPrefix(path, uploadsPath+"/")
+}
+
+// isUploadSessionPath reports whether path names an existing upload
+// session rather than the object of a new one.
+func isUploadSessionPath(path string) bool {
+	return strings.HasPrefix(path, uploadsPath+"/") && !strings.HasPrefix(path, uploadsPath+"/objects/")
+}
+
+// respondWithUploadError maps upload session errors to responses that name
+// the session rather than the generic object messages.
+func respondWithUploadError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, objstore.ErrUploadsNotEnabled):
+		RespondWithError(c, http.StatusNotImplemented, "upload sessions are not enabled on this server")
+	case errors.Is(err, upload.ErrSessionExpired):
+		RespondWithError(c, http.StatusGone, "upload session has expired")
+	case errors.Is(err, upload.ErrSessionNotFound):
+		RespondWithError(c, http.StatusNotFound, "upload session not found")
+	case errors.Is(err, common.ErrReservedKey):
+		RespondWithError(c, http.StatusForbidden, "key is in the reserved upload session namespace")
+	case errors.Is(err, upload.ErrInvalidOptions), errors.Is(err, upload.ErrInvalidChunk),
+		errors.Is(err, upload.ErrChecksumMismatch), errors.Is(err, upload.ErrIncomplete):
+		RespondWithError(c, http.StatusBadRequest, err.Error())
+	default:
+		RespondWithBackendError(c, err)
+	}
+}
+
+func uploadSessionResponse(session *upload.Session) UploadSessionResponse {
+	response := UploadSessionResponse{
+		ID:              session.ID,
+		Key:             session.Key,
+		CreatedBy:       session.CreatedBy,
+		CreatedAt:       session.CreatedAt.Format(time.RFC3339),
+		ExpiresAt:       session.ExpiresAt.Format(time.RFC3339),
+		Size:            session.Size,
+		ContentType:     session.ContentType,
+		ContentEncoding: session.ContentEncoding,
+		Metadata:        session.Metadata,
+		Chunks:          make([]UploadChunkResponse, 0, len(session.Chunks)),
+		Received:        session.Received(),
+	}
+	for _, chunk := range session.Chunks {
+		response.Chunks = append(response.Chunks, UploadChunkResponse{Number: chunk.Number, Size: chunk.Size, SHA256: chunk.SHA256})
+	}
+	return response
+}
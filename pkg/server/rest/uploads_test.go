@@ -0,0 +1,237 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+)
+
+// newUploadsTestServer builds a server over a memory backend; every bearer
+// token authenticates as the user it names, with a role of the same name.
+// Writers may write and readers only read.
+func newUploadsTestServer(t *testing.T, enable bool) *gin.Engine {
+	t.Helper()
+	storage := memory.New()
+	initTestFacade(t, storage)
+	if enable {
+		if err := objstore.EnableUploads("", ""); err != nil {
+			t.Fatalf("EnableUploads: %v", err)
+		}
+	}
+
+	config := DefaultServerConfig()
+	config.Mode = gin.TestMode
+	config.Authenticator = adapters.NewBearerTokenAuthenticator(func(_ context.Context, token string) (*adapters.Principal, error) {
+		return &adapters.Principal{ID: token, Name: token, Roles: []string{token}}, nil
+	})
+	config.Authorizer = adapters.NewRBACAuthorizer(map[string][]string{
+		"writer": {adapters.ActionRead, adapters.ActionWrite},
+		"reader": {adapters.ActionRead},
+	})
+	server, err := NewServer(storage, config)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	return server.Router()
+}
+
+func doUploadRequest(router *gin.Engine, method, path, bearer, body string, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+bearer)
+	for name, value := range header {
+		req.Header.Set(name, value)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func chunkHeader(data string) map[string]string {
+	sum := sha256.Sum256([]byte(data))
+	return map[string]string{headerChunkSHA256: hex.EncodeToString(sum[:])}
+}
+
+func createTestUpload(t *testing.T, router *gin.Engine, body string) UploadSessionResponse {
+	t.Helper()
+	w := doUploadRequest(router, http.MethodPost, "/api/v1/uploads/objects/videos/raw.bin", "writer", body,
+		map[string]string{"Content-Type": "application/json"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create = %d %s", w.Code, w.Body.String())
+	}
+	var created UploadSessionResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatal(err)
+	}
+	return created
+}
+
+func TestUploadEndpoints(t *testing.T) {
+	router := newUploadsTestServer(t, true)
+
+	created := createTestUpload(t, router, `{"size":11,"content_type":"video/mp4","metadata":{"camera":"a"}}`)
+	if created.ID == "" || created.Key != "videos/raw.bin" || created.CreatedBy != "writer" ||
+		created.Size != 11 || created.Chunks == nil || created.Received != 0 {
+		t.Fatalf("unexpected session: %+v", created)
+	}
+	base := uploadsPath + "/" + created.ID
+
+	// Chunks may arrive in any order; a chunk not matching its digest is
+	// refused and not kept.
+	if w := doUploadRequest(router, http.MethodPut, base+"/chunks/2", "writer", "world", chunkHeader("world")); w.Code != http.StatusOK {
+		t.Fatalf("chunk 2 = %d %s", w.Code, w.Body.String())
+	}
+	if w := doUploadRequest(router, http.MethodPut, base+"/chunks/1", "writer", "HELLO ", chunkHeader("hello ")); w.Code != http.StatusBadRequest {
+		t.Errorf("mismatched chunk = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	if w := doUploadRequest(router, http.MethodPost, base+"/complete", "writer", "", nil); w.Code != http.StatusBadRequest {
+		t.Errorf("complete with a missing chunk = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	w := doUploadRequest(router, http.MethodPut, base+"/chunks/1", "writer", "hello ", chunkHeader("hello "))
+	var chunk UploadChunkResponse
+	if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &chunk) != nil || chunk.Number != 1 || chunk.Size != 6 {
+		t.Fatalf("chunk 1 = %d %s", w.Code, w.Body.String())
+	}
+
+	w = doUploadRequest(router, http.MethodGet, base, "writer", "", nil)
+	var status UploadSessionResponse
+	if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &status) != nil {
+		t.Fatalf("get = %d %s", w.Code, w.Body.String())
+	}
+	if len(status.Chunks) != 2 || status.Chunks[0].Number != 1 || status.Received != 11 {
+		t.Errorf("unexpected status: %+v", status)
+	}
+
+	whole := sha256.Sum256([]byte("hello world"))
+	w = doUploadRequest(router, http.MethodPost, base+"/complete", "writer", `{"sha256":"`+hex.EncodeToString(whole[:])+`"}`,
+		map[string]string{"Content-Type": "application/json"})
+	if w.Code != http.StatusCreated || w.Header().Get("ETag") == "" {
+		t.Fatalf("complete = %d %s", w.Code, w.Body.String())
+	}
+
+	reader, err := objstore.GetWithContext(context.Background(), "videos/raw.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(reader)
+	_ = reader.Close()
+	if string(data) != "hello world" {
+		t.Errorf("object = %q", data)
+	}
+	metadata, err := objstore.GetMetadata(context.Background(), "videos/raw.bin")
+	if err != nil || metadata.ContentType != "video/mp4" || metadata.Custom["camera"] != "a" {
+		t.Errorf("metadata = %+v, %v", metadata, err)
+	}
+	if w := doUploadRequest(router, http.MethodGet, base, "writer", "", nil); w.Code != http.StatusNotFound {
+		t.Errorf("get after complete = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestUploadEndpointsAbort(t *testing.T) {
+	router := newUploadsTestServer(t, true)
+	created := createTestUpload(t, router, "")
+	base := uploadsPath + "/" + created.ID
+
+	if w := doUploadRequest(router, http.MethodPut, base+"/chunks/1", "writer", "x", chunkHeader("x")); w.Code != http.StatusOK {
+		t.Fatalf("chunk = %d %s", w.Code, w.Body.String())
+	}
+	if w := doUploadRequest(router, http.MethodDelete, base, "writer", "", nil); w.Code != http.StatusNoContent {
+		t.Fatalf("abort = %d %s", w.Code, w.Body.String())
+	}
+	if w := doUploadRequest(router, http.MethodDelete, base, "writer", "", nil); w.Code != http.StatusNotFound {
+		t.Errorf("second abort = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	if _, err := objstore.GetMetadata(context.Background(), "videos/raw.bin"); err == nil {
+		t.Error("expected no object after an abort")
+	}
+}
+
+func TestUploadEndpointsValidation(t *testing.T) {
+	router := newUploadsTestServer(t, true)
+	created := createTestUpload(t, router, "")
+	base := uploadsPath + "/" + created.ID
+
+	cases := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		header map[string]string
+		want   int
+	}{
+		{"missing digest", http.MethodPut, base + "/chunks/1", "x", nil, http.StatusBadRequest},
+		{"chunk number", http.MethodPut, base + "/chunks/one", "x", chunkHeader("x"), http.StatusBadRequest},
+		{"chunk zero", http.MethodPut, base + "/chunks/0", "x", chunkHeader("x"), http.StatusBadRequest},
+		{"unknown session", http.MethodGet, uploadsPath + "/0123456789abcdef0123456789abcdef", "", nil, http.StatusNotFound},
+		{"malformed session", http.MethodGet, uploadsPath + "/nope", "", nil, http.StatusNotFound},
+		{"ttl", http.MethodPost, uploadsPath + "/objects/a.bin", `{"ttl_seconds":-1}`, nil, http.StatusBadRequest},
+		{"body", http.MethodPost, uploadsPath + "/objects/a.bin", `{`, nil, http.StatusBadRequest},
+		{"reserved key", http.MethodPost, uploadsPath + "/objects/.uploads/x", "", nil, http.StatusForbidden},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if w := doUploadRequest(router, tc.method, tc.path, "writer", tc.body, tc.header); w.Code != tc.want {
+				t.Errorf("%s %s = %d, want %d: %s", tc.method, tc.path, w.Code, tc.want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestUploadEndpointsAuthorization(t *testing.T) {
+	router := newUploadsTestServer(t, true)
+
+	if w := doUploadRequest(router, http.MethodPost, uploadsPath+"/objects/videos/raw.bin", "reader", "", nil); w.Code != http.StatusForbidden {
+		t.Errorf("create as reader = %d, want %d", w.Code, http.StatusForbidden)
+	}
+
+	// Session routes carry no key; their handlers check the session's.
+	created := createTestUpload(t, router, "")
+	base := uploadsPath + "/" + created.ID
+	for _, tc := range []struct{ method, path string }{
+		{http.MethodGet, base},
+		{http.MethodPut, base + "/chunks/1"},
+		{http.MethodPost, base + "/complete"},
+		{http.MethodDelete, base},
+	} {
+		if w := doUploadRequest(router, tc.method, tc.path, "reader", "x", chunkHeader("x")); w.Code != http.StatusForbidden {
+			t.Errorf("%s %s as reader = %d, want %d", tc.method, tc.path, w.Code, http.StatusForbidden)
+		}
+	}
+	if w := doUploadRequest(router, http.MethodGet, base, "writer", "", nil); w.Code != http.StatusOK {
+		t.Errorf("get as writer = %d, want %d", w.Code, http.StatusOK)
+	}
+}
+
+func TestUploadEndpointsNotEnabled(t *testing.T) {
+	router := newUploadsTestServer(t, false)
+
+	if w := doUploadRequest(router, http.MethodPost, uploadsPath+"/objects/a.bin", "writer", "", nil); w.Code != http.StatusNotImplemented {
+		t.Errorf("create = %d, want %d", w.Code, http.StatusNotImplemented)
+	}
+	if w := doUploadRequest(router, http.MethodGet, uploadsPath+"/0123456789abcdef0123456789abcdef", "writer", "", nil); w.Code != http.StatusNotImplemented {
+		t.Errorf("get = %d, want %d", w.Code, http.StatusNotImplemented)
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/events"
+	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+)
+
+// webhooksPath is the prefix of the webhook API, which manages the event
+// notification rules of the default backend. Webhook routes are
+// authorized as admin actions on adapters.ResourceWebhook, since sink
+// settings carry credentials.
+const webhooksPath = "/api/v1/webhooks"
+
+// WebhookSink is the sink a webhook delivers to
+type WebhookSink struct {
+	Type     string            `json:"type" binding:"required" example:"slack"`
+	Settings map[string]string `json:"settings,omitempty"`
+} // @name WebhookSink
+
+// SetWebhookRequest sends the events it matches to a sink
+type SetWebhookRequest struct {
+	Events []string    `json:"events,omitempty" example:"s3:ObjectCreated:*"`
+	Prefix string      `json:"prefix,omitempty" example:"uploads/"`
+	Suffix string      `json:"suffix,omitempty" example:".jpg"`
+	Sink   WebhookSink `json:"sink" binding:"required"`
+} // @name SetWebhookRequest
+
+// WebhookResponse is an event notification rule
+type WebhookResponse struct {
+	ID     string      `json:"id" example:"uploads"`
+	Events []string    `json:"events,omitempty" example:"s3:ObjectCreated:*"`
+	Prefix string      `json:"prefix,omitempty" example:"uploads/"`
+	Suffix string      `json:"suffix,omitempty" example:".jpg"`
+	Sink   WebhookSink `json:"sink"`
+} // @name Webhook
+
+// WebhooksResponse lists webhooks
+type WebhooksResponse struct {
+	Webhooks []WebhookResponse `json:"webhooks"`
+	Count    int               `json:"count" example:"1"`
+} // @name WebhookList
+
+// ListWebhooks lists the event notification rules of the default backend
+func (h *Handler) ListWebhooks(c *gin.Context) {
+	notifier, err := objstore.Notifications(h.backend)
+	if err != nil {
+		respondWithWebhookError(c, err)
+		return
+	}
+
+	rules := notifier.Rules()
+	response := WebhooksResponse{
+		Webhooks: make([]WebhookResponse, 0, len(rules)),
+		Count:    len(rules),
+	}
+	for i := range rules {
+		response.Webhooks = append(response.Webhooks, webhookResponse(&rules[i]))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetWebhook returns an event notification rule
+func (h *Handler) GetWebhook(c *gin.Context) {
+	notifier, err := objstore.Notifications(h.backend)
+	if err != nil {
+		respondWithWebhookError(c, err)
+		return
+	}
+
+	id := c.Param("id")
+	rules := notifier.Rules()
+	i := slices.IndexFunc(rules, func(r events.Rule) bool { return r.ID == id })
+	if i < 0 {
+		RespondWithError(c, http.StatusNotFound, "webhook not found: "+id)
+		return
+	}
+	c.JSON(http.StatusOK, webhookResponse(&rules[i]))
+}
+
+// SetWebhook creates or replaces an event notification rule until the
+// server restarts
+func (h *Handler) SetWebhook(c *gin.Context) {
+	var body SetWebhookRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+
+	notifier, err := objstore.Notifications(h.backend)
+	if err != nil {
+		respondWithWebhookError(c, err)
+		return
+	}
+
+	rule := events.Rule{
+		ID:     c.Param("id"),
+		Events: body.Events,
+		Prefix: body.Prefix,
+		Suffix: body.Suffix,
+		Sink:   events.SinkConfig{Type: body.Sink.Type, Settings: body.Sink.Settings},
+	}
+	if err := notifier.SetRule(rule); err != nil {
+		respondWithWebhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, webhookResponse(&rule))
+}
+
+// DeleteWebhook removes an event notification rule
+func (h *Handler) DeleteWebhook(c *gin.Context) {
+	notifier, err := objstore.Notifications(h.backend)
+	if err != nil {
+		respondWithWebhookError(c, err)
+		return
+	}
+
+	if err := notifier.RemoveRule(c.Param("id")); err != nil {
+		respondWithWebhookError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// isWebhooksPath reports whether path belongs to the webhook API.
+func isWebhooksPath(path string) bool {
+	return path == webhooksPath || strings.HasPrefix(path, webhooksPath+"/")
+}
+
+func respondWithWebhookError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, objstore.ErrNotificationsNotEnabled):
+		RespondWithError(c, http.StatusNotImplemented, "notifications are not enabled on this server")
+	case errors.Is(err, events.ErrInvalidConfig), errors.Is(err, events.ErrUnknownSink):
+		RespondWithError(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, common.ErrNotFound):
+		RespondWithError(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, events.ErrClosed):
+		RespondWithError(c, http.StatusServiceUnavailable, err.Error())
+	default:
+		RespondWithBackendError(c, err)
+	}
+}
+
+func webhookResponse(rule *events.Rule) WebhookResponse {
+	return WebhookResponse{
+		ID:     rule.ID,
+		Events: rule.Events,
+		Prefix: rule.Prefix,
+		Suffix: rule.Suffix,
+		Sink:   WebhookSink{Type: rule.Sink.Type, Settings: rule.Sink.Settings},
+	}
+}
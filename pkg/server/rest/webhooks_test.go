@@ -0,0 +1,96 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/events"
+	"github.com/jeremyhahn/go-objstore/pkg/objstore"
+)
+
+// discardSink accepts and drops notifications.
+type discardSink struct{}
+
+func (discardSink) Publish(context.Context, *events.Notification) error { return nil }
+func (discardSink) Close() error                                        { return nil }
+
+func TestWebhookEndpoints(t *testing.T) {
+	events.RegisterSink("discard", func(map[string]string) (events.Sink, error) { return discardSink{}, nil })
+	router := newManagementTestServer(t, func() error {
+		return objstore.EnableNotifications("", &events.Config{})
+	})
+	t.Cleanup(func() {
+		if notifier, err := objstore.Notifications(""); err == nil {
+			_ = notifier.Close(context.Background())
+		}
+	})
+
+	body := `{"events":["s3:ObjectCreated:*"],"prefix":"uploads/","sink":{"type":"discard","settings":{"name":"a"}}}`
+	w := doBearerRequest(router, http.MethodPut, "/api/v1/webhooks/uploads", "admin", body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("set = %d %s", w.Code, w.Body.String())
+	}
+	var webhook WebhookResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &webhook); err != nil || webhook.ID != "uploads" || webhook.Sink.Settings["name"] != "a" {
+		t.Errorf("webhook = %+v, %v", webhook, err)
+	}
+	if w := doBearerRequest(router, http.MethodPut, "/api/v1/webhooks/uploads", "alice", body); w.Code != http.StatusForbidden {
+		t.Errorf("set as non-admin = %d, want %d", w.Code, http.StatusForbidden)
+	}
+
+	// Setting it again replaces it.
+	body = strings.Replace(body, `"name":"a"`, `"name":"b"`, 1)
+	if w := doBearerRequest(router, http.MethodPut, "/api/v1/webhooks/uploads", "admin", body); w.Code != http.StatusOK {
+		t.Fatalf("replace = %d %s", w.Code, w.Body.String())
+	}
+	w = doBearerRequest(router, http.MethodGet, "/api/v1/webhooks", "admin", "")
+	var list WebhooksResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil || list.Count != 1 || list.Webhooks[0].Sink.Settings["name"] != "b" {
+		t.Errorf("list = %d %s", w.Code, w.Body.String())
+	}
+	if w := doBearerRequest(router, http.MethodGet, "/api/v1/webhooks/uploads", "admin", ""); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"prefix":"uploads/"`) {
+		t.Errorf("get = %d %s", w.Code, w.Body.String())
+	}
+
+	for name, invalid := range map[string]string{
+		"unknown event": `{"events":["s3:Nothing"],"sink":{"type":"discard"}}`,
+		"unknown sink":  `{"sink":{"type":"carrier-pigeon"}}`,
+		"no sink":       `{"prefix":"a/"}`,
+	} {
+		if w := doBearerRequest(router, http.MethodPut, "/api/v1/webhooks/bad", "admin", invalid); w.Code != http.StatusBadRequest {
+			t.Errorf("%s = %d %s, want %d", name, w.Code, w.Body.String(), http.StatusBadRequest)
+		}
+	}
+
+	if w := doBearerRequest(router, http.MethodDelete, "/api/v1/webhooks/uploads", "admin", ""); w.Code != http.StatusNoContent {
+		t.Errorf("delete = %d %s", w.Code, w.Body.String())
+	}
+	for _, method := range []string{http.MethodGet, http.MethodDelete} {
+		if w := doBearerRequest(router, method, "/api/v1/webhooks/uploads", "admin", ""); w.Code != http.StatusNotFound {
+			t.Errorf("%s deleted webhook = %d, want %d", method, w.Code, http.StatusNotFound)
+		}
+	}
+}
+
+func TestWebhookEndpointsNotEnabled(t *testing.T) {
+	router := newManagementTestServer(t, nil)
+	if w := doBearerRequest(router, http.MethodGet, "/api/v1/webhooks", "admin", ""); w.Code != http.StatusNotImplemented {
+		t.Errorf("list = %d %s, want %d", w.Code, w.Body.String(), http.StatusNotImplemented)
+	}
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/jeremyhahn/go-objstore/pkg/factory"
 	"github.com/jeremyhahn/go-objstore/pkg/objstore"
 	"github.com/jeremyhahn/go-objstore/pkg/replication"
+	"github.com/jeremyhahn/go-objstore/pkg/secrets"
 	servererrors "github.com/jeremyhahn/go-objstore/pkg/server/errors"
 	"github.com/jeremyhahn/go-objstore/pkg/server/jsonrpc"
 	"github.com/jeremyhahn/go-objstore/pkg/version"
@@ -45,7 +46,12 @@ const ErrCodeForbidden = jsonrpc.CodeForbidden
 
 // Handler handles JSON-RPC requests
 type Handler struct {
-	backend       string
+	backend string
+
+	// prefix confines the handler to keys under it; see
+	// ServerConfig.KeyPrefix.
+	prefix string
+
 	logger        adapters.Logger
 	authenticator adapters.Authenticator
 	authorizer    adapters.Authorizer
@@ -140,12 +146,45 @@ func (h *Handler) authorize(ctx context.Context, req *Request) *Response {
 
 // keyRef builds a key reference with optional backend prefix.
 func (h *Handler) keyRef(key string) string {
+	key = h.prefix + key
 	if h.backend == "" {
 		return key
 	}
 	return h.backend + ":" + key
 }
 
+// scopeParams are the parameters checked on a tenant-scoped socket.
+type scopeParams struct {
+	Key    string `json:"key"`
+	Prefix string `json:"prefix"`
+}
+
+// checkScope refuses requests that would leave a tenant-scoped socket's
+// prefix: management methods, which act on the whole server, and keys or
+// list prefixes that climb out of it with "..".
+func (h *Handler) checkScope(req *Request) *Response {
+	if h.prefix == "" {
+		return nil
+	}
+	if mapping, ok := methodAuthz[req.Method]; ok && mapping.action == adapters.ActionAdmin {
+		return h.errorResponse(req.ID, ErrCodeForbidden, "method not available on a tenant-scoped socket")
+	}
+	var params scopeParams
+	if len(req.Params) > 0 && json.Unmarshal(req.Params, &params) == nil {
+		if params.Key != "" {
+			if err := common.ValidateKey(params.Key); err != nil {
+				return h.errorResponse(req.ID, ErrCodeInvalidParams, err.Error())
+			}
+		}
+		for _, segment := range strings.Split(params.Prefix, "/") {
+			if segment == ".." {
+				return h.errorResponse(req.ID, ErrCodeInvalidParams, "prefix cannot contain path traversal sequences")
+			}
+		}
+	}
+	return nil
+}
+
 // Handle processes a JSON-RPC request and returns a response
 func (h *Handler) Handle(ctx context.Context, req *Request) *Response {
 	// Enforce authentication + authorization before dispatch. Health/ping are
@@ -153,6 +192,9 @@ func (h *Handler) Handle(ctx context.Context, req *Request) *Response {
 	if denied := h.authorize(ctx, req); denied != nil {
 		return denied
 	}
+	if denied := h.checkScope(req); denied != nil {
+		return denied
+	}
 
 	switch req.Method {
 	case MethodPut:
@@ -216,23 +258,28 @@ func (h *Handler) handlePut(ctx context.Context, req *Request) *Response {
 	}
 
 	// Put object using facade
+	var metadata *common.Metadata
 	if params.Metadata != nil {
 		// Convert metadata
-		metadata := &common.Metadata{
+		metadata = &common.Metadata{
 			ContentType:     params.Metadata.ContentType,
 			ContentEncoding: params.Metadata.ContentEncoding,
 			Custom:          params.Metadata.Custom,
 		}
-		if err := objstore.PutWithMetadata(ctx, h.keyRef(params.Key), bytes.NewReader(data), metadata); err != nil {
-			return h.backendErrorResponse(req.ID, err)
-		}
-	} else {
-		if err := objstore.PutWithContext(ctx, h.keyRef(params.Key), bytes.NewReader(data)); err != nil {
-			return h.backendErrorResponse(req.ID, err)
-		}
+	}
+	info, err := objstore.PutWithInfo(ctx, h.keyRef(params.Key), bytes.NewReader(data), metadata)
+	if err != nil {
+		return h.backendErrorResponse(req.ID, err)
 	}
 
-	return h.successResponse(req.ID, map[string]string{fieldStatus: "ok"})
+	result := map[string]any{fieldStatus: "ok", "etag": info.Metadata.ETag, "size": info.Metadata.Size}
+	if info.Metadata.Version != "" {
+		result["version"] = info.Metadata.Version
+	}
+	if !info.Metadata.LastModified.IsZero() {
+		result["last_modified"] = info.Metadata.LastModified.UTC().Format(time.RFC3339Nano)
+	}
+	return h.successResponse(req.ID, result)
 }
 
 // handleGet handles the get method
@@ -323,7 +370,7 @@ func (h *Handler) handleList(ctx context.Context, req *Request) *Response {
 	}
 
 	opts := &common.ListOptions{
-		Prefix:       params.Prefix,
+		Prefix:       h.prefix + params.Prefix,
 		Delimiter:    params.Delimiter,
 		MaxResults:   params.MaxResults,
 		ContinueFrom: params.ContinueFrom,
@@ -337,7 +384,7 @@ func (h *Handler) handleList(ctx context.Context, req *Request) *Response {
 	objects := make([]ObjectInfo, 0, len(result.Objects))
 	for _, obj := range result.Objects {
 		info := ObjectInfo{
-			Key: obj.Key,
+			Key: strings.TrimPrefix(obj.Key, h.prefix),
 		}
 		if obj.Metadata != nil {
 			info.Size = obj.Metadata.Size
@@ -419,6 +466,10 @@ func (h *Handler) handleArchive(ctx context.Context, req *Request) *Response {
 		return h.errorResponse(req.ID, ErrCodeInvalidParams, "destination_type is required")
 	}
 
+	if err := secrets.RejectReferences(params.DestinationSettings); err != nil {
+		return h.errorResponse(req.ID, ErrCodeInvalidParams, err.Error())
+	}
+
 	// Create archiver from factory
 	archiver, err := factory.NewArchiver(params.DestinationType, params.DestinationSettings)
 	if err != nil {
@@ -509,15 +560,20 @@ func (h *Handler) handleApplyPolicies(ctx context.Context, req *Request) *Respon
 		return h.backendErrorResponse(req.ID, err)
 	}
 
+	expired, err := objstore.DeleteExpired(ctx, h.backend)
+	if err != nil {
+		return h.backendErrorResponse(req.ID, err)
+	}
+
 	if len(policies) == 0 {
 		return h.successResponse(req.ID, &ApplyPoliciesResult{
 			PoliciesCount:    0,
-			ObjectsProcessed: 0,
+			ObjectsProcessed: expired,
 		})
 	}
 
 	// Apply policies by listing objects and checking retention
-	objectsProcessed := 0
+	objectsProcessed := expired
 	opts := &common.ListOptions{
 		Prefix: "",
 	}
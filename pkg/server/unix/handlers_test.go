@@ -359,6 +359,46 @@ func TestHandleList(t *testing.T) {
 	}
 }
 
+func TestHandlerKeyPrefix(t *testing.T) {
+	storage := NewMockStorage()
+	storage.objects["tenants/acme/a.txt"] = []byte("acme")
+	storage.objects["tenants/other/b.txt"] = []byte("other")
+
+	handler := createTestHandler(t, storage)
+	handler.prefix = "tenants/acme/"
+	call := func(method string, params any) *Response {
+		paramsJSON, _ := json.Marshal(params)
+		return handler.Handle(context.Background(), &Request{JSONRPC: jsonRPCVersion, Method: method, Params: paramsJSON, ID: 1})
+	}
+
+	resp := call(MethodPut, PutParams{Key: "new.txt", Data: base64.StdEncoding.EncodeToString([]byte("x"))})
+	if resp.Error != nil {
+		t.Fatalf("put: %s", resp.Error.Message)
+	}
+	if _, ok := storage.objects["tenants/acme/new.txt"]; !ok {
+		t.Errorf("put stored %v, want tenants/acme/new.txt", storage.objects)
+	}
+
+	resp = call(MethodList, ListParams{})
+	result, ok := resp.Result.(*ListResult)
+	if !ok || len(result.Objects) != 2 || result.Objects[0].Key != "a.txt" && result.Objects[1].Key != "a.txt" {
+		t.Errorf("list = %+v, %v, want a.txt and new.txt without the prefix", resp.Result, resp.Error)
+	}
+
+	for _, c := range []struct {
+		method string
+		params any
+	}{
+		{MethodGet, GetParams{Key: "../other/b.txt"}},
+		{MethodList, ListParams{Prefix: "../other/"}},
+		{MethodGetPolicies, struct{}{}},
+	} {
+		if resp := call(c.method, c.params); resp.Error == nil {
+			t.Errorf("%s %+v succeeded on a tenant-scoped socket", c.method, c.params)
+		}
+	}
+}
+
 func TestHandleHealth(t *testing.T) {
 	storage := NewMockStorage()
 	handler := createTestHandler(t, storage)
@@ -45,7 +45,8 @@ type principalCtxKey struct{}
 
 // withPrincipal returns a copy of ctx carrying the given principal.
 func withPrincipal(ctx context.Context, p *adapters.Principal) context.Context {
-	return context.WithValue(ctx, principalCtxKey{}, p)
+	ctx = context.WithValue(ctx, principalCtxKey{}, p)
+	return context.WithValue(ctx, adapters.PrincipalContextKey{}, p)
 }
 
 // principalFromContext returns the peer-credential principal stored in ctx, if any.
@@ -83,6 +84,13 @@ type ServerConfig struct {
 	// Backend is the name of the backend to use (empty = default backend)
 	Backend string
 
+	// KeyPrefix confines the socket to keys under this prefix, such as
+	// "tenants/acme/", for a sidecar serving one tenant's pods. Keys sent by
+	// clients are resolved relative to it and listed keys are returned
+	// without it; management methods (policies, replication, archive) are
+	// refused. Empty serves every key.
+	KeyPrefix string
+
 	// Logger is the pluggable logger adapter
 	Logger adapters.Logger
 
@@ -208,6 +216,7 @@ func NewServer(config *ServerConfig) (*Server, error) {
 	}
 
 	handler := NewHandler(config.Backend, config.Logger, config.Authenticator, config.Authorizer)
+	handler.prefix = config.KeyPrefix
 
 	// Peer-credential authentication defaults to enabled when left unset.
 	usePeerCred := true
@@ -0,0 +1,491 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package share implements shareable download links: URLs carrying a
+// secret token that let anyone holding them download one object without
+// credentials, until the link expires, is revoked or reaches its download
+// limit. A link may require a password and cap the bandwidth of its
+// downloads, and records how often and how much it was downloaded.
+//
+// A link is an object under a reserved prefix (DefaultPrefix) named by the
+// link's ID, a digest of its token, so the token is never stored and
+// cannot be recovered from the backend or the list of links. Downloads are
+// counted with writes conditional on the link object's ETag, so instances
+// sharing a backend never exceed a link's download limit between them.
+package share
+
+import (
+	"bytes"
+	"context"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// DefaultPrefix is the key prefix link objects are stored under when none
+// is configured.
+const DefaultPrefix = ".shares/"
+
+// Link lifetime bounds.
+const (
+	DefaultTTL = 24 * time.Hour
+	MaxTTL     = 365 * 24 * time.Hour
+)
+
+// maxPasswordLength bounds link passwords.
+const maxPasswordLength = 1024
+
+// maxAttempts bounds the retries of a download count or byte count that
+// lost a race with another download of the same link.
+const maxAttempts = 8
+
+// Password hashing parameters.
+const (
+	passwordScheme     = "pbkdf2-sha256"
+	passwordIterations = 100000
+	passwordSaltSize   = 16
+)
+
+var (
+	// ErrLinkNotFound is returned for an unknown link ID or token.
+	ErrLinkNotFound = fmt.Errorf("share link %w", common.ErrNotFound)
+
+	// ErrLinkExpired is returned when downloading through a link past its
+	// expiry. It wraps common.ErrNotFound.
+	ErrLinkExpired = fmt.Errorf("share link has expired: %w", common.ErrNotFound)
+
+	// ErrLinkExhausted is returned when downloading through a link that
+	// reached its download limit. It wraps common.ErrNotFound.
+	ErrLinkExhausted = fmt.Errorf("share link download limit reached: %w", common.ErrNotFound)
+
+	// ErrPasswordRequired is returned when downloading through a
+	// password-protected link without its password. It wraps
+	// common.ErrPermissionDenied.
+	ErrPasswordRequired = fmt.Errorf("%w: share link requires a password", common.ErrPermissionDenied)
+
+	// ErrInvalidOptions is returned by Create for out-of-range options.
+	ErrInvalidOptions = fmt.Errorf("%w: invalid share link options", common.ErrInvalidArgument)
+
+	// ErrNotSupported is returned by NewManager for backends that cannot
+	// write conditionally.
+	ErrNotSupported = errors.New("backend does not support conditional writes")
+)
+
+// Status is the state of a Link.
+type Status string
+
+const (
+	// StatusActive links can be downloaded through.
+	StatusActive Status = "active"
+
+	// StatusExpired links are past their expiry.
+	StatusExpired Status = "expired"
+
+	// StatusExhausted links reached their download limit.
+	StatusExhausted Status = "exhausted"
+)
+
+// Options configures a new link. Zero fields take their defaults.
+type Options struct {
+	// TTL is how long the link works (default: DefaultTTL, at most MaxTTL).
+	TTL time.Duration
+
+	// MaxDownloads is how many downloads the link allows (0: unlimited).
+	MaxDownloads int
+
+	// Password, if set, must accompany every download.
+	Password string
+
+	// BytesPerSecond caps the combined bandwidth of the link's downloads
+	// on each instance (0: unlimited).
+	BytesPerSecond int64
+}
+
+// Link is a shareable download link to one object, with its download
+// accounting. The token that opens it is only returned by Create.
+type Link struct {
+	ID             string     `json:"id"`
+	Key            string     `json:"key"`
+	CreatedBy      string     `json:"created_by,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	ExpiresAt      time.Time  `json:"expires_at"`
+	MaxDownloads   int        `json:"max_downloads,omitempty"`
+	BytesPerSecond int64      `json:"bytes_per_second,omitempty"`
+	Protected      bool       `json:"password_protected,omitempty"`
+	Downloads      int        `json:"downloads"`
+	BytesServed    int64      `json:"bytes_served"`
+	LastDownload   *time.Time `json:"last_download,omitempty"`
+}
+
+// Status returns the state of the link at now.
+func (l *Link) Status(now time.Time) Status {
+	switch {
+	case !now.Before(l.ExpiresAt):
+		return StatusExpired
+	case l.MaxDownloads > 0 && l.Downloads >= l.MaxDownloads:
+		return StatusExhausted
+	default:
+		return StatusActive
+	}
+}
+
+// record is the stored form of a link.
+type record struct {
+	Link
+	PasswordHash string `json:"password_hash,omitempty"`
+}
+
+// Manager creates, opens and revokes links stored in a backend. It is safe
+// for concurrent use.
+type Manager struct {
+	storage common.Storage
+	writer  common.ConditionalWriter
+	prefix  string
+	now     func() time.Time
+
+	mu       sync.Mutex
+	limiters map[string]*limiter
+}
+
+// NewManager returns a Manager storing links in storage under prefix, or
+// DefaultPrefix if prefix is empty. The storage must implement
+// common.ConditionalWriter.
+func NewManager(storage common.Storage, prefix string) (*Manager, error) {
+	writer, ok := storage.(common.ConditionalWriter)
+	if !ok {
+		return nil, ErrNotSupported
+	}
+	if prefix == "" {
+		prefix = DefaultPrefix
+	}
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	if err := common.ValidateKey(prefix + "link"); err != nil {
+		return nil, fmt.Errorf("invalid share link prefix %q: %w", prefix, err)
+	}
+	return &Manager{
+		storage:  storage,
+		writer:   writer,
+		prefix:   prefix,
+		now:      time.Now,
+		limiters: make(map[string]*limiter),
+	}, nil
+}
+
+// Prefix returns the key prefix link objects are stored under.
+func (m *Manager) Prefix() string {
+	return m.prefix
+}
+
+// Reserved reports whether key is in the link namespace.
+func (m *Manager) Reserved(key string) bool {
+	return common.IsReserved(key, m.prefix)
+}
+
+// Create creates a link to key and returns it with the token that opens
+// it. The creator is taken from the principal in ctx. Create does not
+// check that the object exists; callers sharing objects on behalf of
+// clients should.
+func (m *Manager) Create(ctx context.Context, key string, opts Options) (*Link, string, error) {
+	if err := common.ValidateKey(key); err != nil {
+		return nil, "", err
+	}
+	if m.Reserved(key) {
+		return nil, "", fmt.Errorf("%w: %s", common.ErrReservedKey, key)
+	}
+	if err := validate(&opts); err != nil {
+		return nil, "", err
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return nil, "", err
+	}
+	now := m.now().UTC()
+	rec := &record{Link: Link{
+		ID:             LinkID(token),
+		Key:            key,
+		CreatedBy:      adapters.Identity(ctx),
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(opts.TTL),
+		MaxDownloads:   opts.MaxDownloads,
+		BytesPerSecond: opts.BytesPerSecond,
+		Protected:      opts.Password != "",
+	}}
+	if opts.Password != "" {
+		if rec.PasswordHash, err = hashPassword(opts.Password); err != nil {
+			return nil, "", err
+		}
+	}
+	if err := m.write(ctx, rec, ""); err != nil {
+		return nil, "", err
+	}
+	link := rec.Link
+	return &link, token, nil
+}
+
+// Open counts a download through the link token opens and returns the
+// link. It fails with ErrLinkNotFound, ErrLinkExpired, ErrPasswordRequired
+// or ErrLinkExhausted if the download is not allowed. A download counts
+// when it is opened, whether or not it completes.
+func (m *Manager) Open(ctx context.Context, token, password string) (*Link, error) {
+	if token == "" {
+		return nil, ErrLinkNotFound
+	}
+	id := LinkID(token)
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		rec, etag, err := m.read(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if rec == nil {
+			return nil, ErrLinkNotFound
+		}
+		now := m.now().UTC()
+		if rec.Status(now) == StatusExpired {
+			return nil, ErrLinkExpired
+		}
+		if rec.PasswordHash != "" && !checkPassword(rec.PasswordHash, password) {
+			return nil, ErrPasswordRequired
+		}
+		if rec.Status(now) == StatusExhausted {
+			return nil, ErrLinkExhausted
+		}
+
+		rec.Downloads++
+		rec.LastDownload = &now
+		err = m.write(ctx, rec, etag)
+		if err == nil {
+			link := rec.Link
+			return &link, nil
+		}
+		if !common.LostRace(err) {
+			return nil, err
+		}
+	}
+	return nil, errors.New("failed to count share link download: too many concurrent downloads")
+}
+
+// AddBytes adds n bytes to the bytes served through the link with id. It
+// is a no-op for links revoked since.
+func (m *Manager) AddBytes(ctx context.Context, id string, n int64) error {
+	if n <= 0 {
+		return nil
+	}
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		rec, etag, err := m.read(ctx, id)
+		if err != nil || rec == nil {
+			return err
+		}
+		rec.BytesServed += n
+		err = m.write(ctx, rec, etag)
+		if !common.LostRace(err) {
+			return err
+		}
+	}
+	return errors.New("failed to record share link bytes: too many concurrent downloads")
+}
+
+// Get returns the link with id.
+func (m *Manager) Get(ctx context.Context, id string) (*Link, error) {
+	rec, _, err := m.read(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if rec == nil {
+		return nil, fmt.Errorf("%w: %s", ErrLinkNotFound, id)
+	}
+	return &rec.Link, nil
+}
+
+// List returns every link, including expired and exhausted ones, oldest
+// first.
+func (m *Manager) List(ctx context.Context) ([]Link, error) {
+	keys, err := m.storage.ListWithContext(ctx, m.prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	links := make([]Link, 0, len(keys))
+	for _, key := range keys {
+		rec, _, err := m.read(ctx, strings.TrimPrefix(key, m.prefix))
+		if err != nil {
+			return nil, err
+		}
+		if rec != nil {
+			links = append(links, rec.Link)
+		}
+	}
+	sort.Slice(links, func(i, j int) bool {
+		if !links[i].CreatedAt.Equal(links[j].CreatedAt) {
+			return links[i].CreatedAt.Before(links[j].CreatedAt)
+		}
+		return links[i].ID < links[j].ID
+	})
+	return links, nil
+}
+
+// Revoke deletes the link with id. Downloads already under way finish.
+func (m *Manager) Revoke(ctx context.Context, id string) error {
+	if !validID(id) {
+		return fmt.Errorf("%w: %s", ErrLinkNotFound, id)
+	}
+	err := m.storage.DeleteWithContext(ctx, m.prefix+id)
+	if errors.Is(err, common.ErrNotFound) {
+		return fmt.Errorf("%w: %s", ErrLinkNotFound, id)
+	}
+	return err
+}
+
+// read returns the link with id and the ETag of its object, or nil if
+// there is none. The ETag is read before the contents, so a conditional
+// write based on it fails if the link changed in between.
+func (m *Manager) read(ctx context.Context, id string) (*record, string, error) {
+	if !validID(id) {
+		return nil, "", nil
+	}
+	key := m.prefix + id
+	metadata, err := m.storage.GetMetadata(ctx, key)
+	if errors.Is(err, common.ErrNotFound) {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	rc, err := m.storage.GetWithContext(ctx, key)
+	if errors.Is(err, common.ErrNotFound) {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	data, err := io.ReadAll(io.LimitReader(rc, 64*1024))
+	_ = rc.Close()
+	if err != nil {
+		return nil, "", err
+	}
+
+	var rec record
+	if err := json.Unmarshal(data, &rec); err != nil || rec.ID != id {
+		return nil, "", fmt.Errorf("share link %s is corrupt", id)
+	}
+	return &rec, metadata.ETag, nil
+}
+
+// write stores rec conditionally on etag; an empty etag requires no link
+// object to exist.
+func (m *Manager) write(ctx context.Context, rec *record, etag string) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	return m.writer.PutIfMatch(ctx, m.prefix+rec.ID, bytes.NewReader(data), &common.Metadata{
+		ContentType: "application/json",
+	}, etag)
+}
+
+func validate(opts *Options) error {
+	if opts.TTL == 0 {
+		opts.TTL = DefaultTTL
+	}
+	switch {
+	case opts.TTL < 0 || opts.TTL > MaxTTL:
+		return fmt.Errorf("%w: TTL must be between 0 and %s", ErrInvalidOptions, MaxTTL)
+	case opts.MaxDownloads < 0:
+		return fmt.Errorf("%w: max downloads must not be negative", ErrInvalidOptions)
+	case opts.BytesPerSecond < 0:
+		return fmt.Errorf("%w: bytes per second must not be negative", ErrInvalidOptions)
+	case len(opts.Password) > maxPasswordLength:
+		return fmt.Errorf("%w: password exceeds %d bytes", ErrInvalidOptions, maxPasswordLength)
+	}
+	return nil
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// LinkID returns the ID of the link token opens: the hex encoding of the
+// first 16 bytes of the token's SHA-256 digest.
+func LinkID(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:16])
+}
+
+func validID(id string) bool {
+	if len(id) != 32 {
+		return false
+	}
+	_, err := hex.DecodeString(id)
+	return err == nil
+}
+
+// hashPassword returns password hashed as scheme$iterations$salt$hash.
+func hashPassword(password string) (string, error) {
+	salt := make([]byte, passwordSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	sum, err := pbkdf2.Key(sha256.New, password, salt, passwordIterations, sha256.Size)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join([]string{
+		passwordScheme,
+		strconv.Itoa(passwordIterations),
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(sum),
+	}, "$"), nil
+}
+
+func checkPassword(hash, password string) bool {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 4 || parts[0] != passwordScheme {
+		return false
+	}
+	iterations, err := strconv.Atoi(parts[1])
+	if err != nil || iterations <= 0 {
+		return false
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return false
+	}
+	want, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return false
+	}
+	got, err := pbkdf2.Key(sha256.New, password, salt, iterations, len(want))
+	return err == nil && subtle.ConstantTimeCompare(got, want) == 1
+}
@@ -0,0 +1,360 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package share
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+// fakeClock is a settable clock for expiry tests.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newTestManager(t *testing.T) (*Manager, common.Storage, *fakeClock) {
+	t.Helper()
+	backend := memory.New()
+	manager, err := NewManager(backend, "")
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	manager.now = clock.Now
+	return manager, backend, clock
+}
+
+// plainStorage hides a backend's optional interfaces.
+type plainStorage struct {
+	common.Storage
+}
+
+func TestNewManager(t *testing.T) {
+	if _, err := NewManager(plainStorage{memory.New()}, ""); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("expected ErrNotSupported, got %v", err)
+	}
+
+	manager, err := NewManager(memory.New(), "links")
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	if manager.Prefix() != "links/" {
+		t.Errorf("expected prefix links/, got %q", manager.Prefix())
+	}
+
+	if _, err := NewManager(memory.New(), "../links"); err == nil {
+		t.Error("expected invalid prefix to be rejected")
+	}
+}
+
+func TestCreateAndOpen(t *testing.T) {
+	manager, backend, clock := newTestManager(t)
+	ctx := context.WithValue(context.Background(), adapters.PrincipalContextKey{}, &adapters.Principal{ID: "alice"})
+
+	link, token, err := manager.Create(ctx, "reports/q1.pdf", Options{})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if token == "" || link.ID != LinkID(token) || link.CreatedBy != "alice" ||
+		link.ExpiresAt.Sub(link.CreatedAt) != DefaultTTL || link.Protected {
+		t.Errorf("unexpected link: %+v", link)
+	}
+	data, err := backend.Get(DefaultPrefix + link.ID)
+	if err != nil {
+		t.Fatalf("expected link object under the link prefix: %v", err)
+	}
+	stored, _ := io.ReadAll(data)
+	if bytes.Contains(stored, []byte(token)) {
+		t.Error("expected the token not to be stored")
+	}
+
+	for i := 1; i <= 3; i++ {
+		opened, err := manager.Open(ctx, token, "")
+		if err != nil {
+			t.Fatalf("Open: %v", err)
+		}
+		if opened.Downloads != i || opened.LastDownload == nil || opened.Key != "reports/q1.pdf" {
+			t.Errorf("unexpected link after download %d: %+v", i, opened)
+		}
+	}
+	if _, err := manager.Open(ctx, token+"x", ""); !errors.Is(err, ErrLinkNotFound) {
+		t.Errorf("expected ErrLinkNotFound for an unknown token, got %v", err)
+	}
+	if _, err := manager.Open(ctx, "", ""); !errors.Is(err, ErrLinkNotFound) {
+		t.Errorf("expected ErrLinkNotFound for an empty token, got %v", err)
+	}
+
+	clock.Advance(DefaultTTL)
+	if _, err := manager.Open(ctx, token, ""); !errors.Is(err, ErrLinkExpired) {
+		t.Errorf("expected ErrLinkExpired, got %v", err)
+	}
+	got, err := manager.Get(ctx, link.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Downloads != 3 || got.Status(clock.Now()) != StatusExpired {
+		t.Errorf("expected 3 downloads on an expired link, got %+v", got)
+	}
+}
+
+func TestOpenLimitsAndPassword(t *testing.T) {
+	manager, _, clock := newTestManager(t)
+	ctx := context.Background()
+
+	link, token, err := manager.Create(ctx, "k", Options{MaxDownloads: 2, Password: "s3cret", TTL: time.Hour})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if !link.Protected || link.MaxDownloads != 2 {
+		t.Errorf("unexpected link: %+v", link)
+	}
+
+	for _, password := range []string{"", "wrong"} {
+		if _, err := manager.Open(ctx, token, password); !errors.Is(err, ErrPasswordRequired) ||
+			!errors.Is(err, common.ErrPermissionDenied) {
+			t.Errorf("password %q: expected ErrPasswordRequired, got %v", password, err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := manager.Open(ctx, token, "s3cret"); err != nil {
+			t.Fatalf("Open: %v", err)
+		}
+	}
+	if _, err := manager.Open(ctx, token, "s3cret"); !errors.Is(err, ErrLinkExhausted) {
+		t.Errorf("expected ErrLinkExhausted, got %v", err)
+	}
+	got, _ := manager.Get(ctx, link.ID)
+	if got.Downloads != 2 || got.Status(clock.Now()) != StatusExhausted {
+		t.Errorf("expected an exhausted link, got %+v", got)
+	}
+}
+
+func TestOpenConcurrent(t *testing.T) {
+	manager, _, _ := newTestManager(t)
+	ctx := context.Background()
+
+	link, token, err := manager.Create(ctx, "k", Options{MaxDownloads: 3})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	var opened atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := manager.Open(ctx, token, ""); err == nil {
+				opened.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if opened.Load() > 3 {
+		t.Errorf("expected at most 3 downloads, got %d", opened.Load())
+	}
+	got, _ := manager.Get(ctx, link.ID)
+	if int32(got.Downloads) != opened.Load() {
+		t.Errorf("expected %d downloads to be counted, got %d", opened.Load(), got.Downloads)
+	}
+}
+
+func TestCreateValidation(t *testing.T) {
+	manager, _, _ := newTestManager(t)
+	ctx := context.Background()
+
+	tests := map[string]struct {
+		key  string
+		opts Options
+		want error
+	}{
+		"reserved key":     {DefaultPrefix + "x", Options{}, common.ErrReservedKey},
+		"invalid key":      {"../x", Options{}, common.ErrInvalidArgument},
+		"negative TTL":     {"k", Options{TTL: -time.Second}, ErrInvalidOptions},
+		"TTL too long":     {"k", Options{TTL: MaxTTL + time.Second}, ErrInvalidOptions},
+		"negative limit":   {"k", Options{MaxDownloads: -1}, ErrInvalidOptions},
+		"negative rate":    {"k", Options{BytesPerSecond: -1}, ErrInvalidOptions},
+		"password too big": {"k", Options{Password: strings.Repeat("p", maxPasswordLength+1)}, ErrInvalidOptions},
+	}
+	for name, tt := range tests {
+		if _, _, err := manager.Create(ctx, tt.key, tt.opts); !errors.Is(err, tt.want) {
+			t.Errorf("%s: expected %v, got %v", name, tt.want, err)
+		}
+	}
+}
+
+func TestListAddBytesAndRevoke(t *testing.T) {
+	manager, _, clock := newTestManager(t)
+	ctx := context.Background()
+
+	first, _, err := manager.Create(ctx, "a", Options{})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	clock.Advance(time.Second)
+	second, _, err := manager.Create(ctx, "b", Options{})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	if err := manager.AddBytes(ctx, first.ID, 100); err != nil {
+		t.Fatalf("AddBytes: %v", err)
+	}
+	if err := manager.AddBytes(ctx, first.ID, 50); err != nil {
+		t.Fatalf("AddBytes: %v", err)
+	}
+
+	links, err := manager.List(ctx)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(links) != 2 || links[0].ID != first.ID || links[1].ID != second.ID || links[0].BytesServed != 150 {
+		t.Fatalf("unexpected links: %+v", links)
+	}
+
+	if err := manager.Revoke(ctx, first.ID); err != nil {
+		t.Fatalf("Revoke: %v", err)
+	}
+	if err := manager.Revoke(ctx, first.ID); !errors.Is(err, ErrLinkNotFound) {
+		t.Errorf("expected ErrLinkNotFound for a second revoke, got %v", err)
+	}
+	if _, err := manager.Get(ctx, first.ID); !errors.Is(err, ErrLinkNotFound) {
+		t.Errorf("expected ErrLinkNotFound after revoke, got %v", err)
+	}
+	if err := manager.AddBytes(ctx, first.ID, 10); err != nil {
+		t.Errorf("expected AddBytes on a revoked link to be a no-op, got %v", err)
+	}
+	if err := manager.Revoke(ctx, "../etc"); !errors.Is(err, ErrLinkNotFound) {
+		t.Errorf("expected ErrLinkNotFound for a malformed ID, got %v", err)
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	manager, _, _ := newTestManager(t)
+	ctx := context.Background()
+
+	unlimited := &Link{ID: "a"}
+	r := manager.Throttle(ctx, unlimited, strings.NewReader("data"))
+	if data, _ := io.ReadAll(r); string(data) != "data" {
+		t.Errorf("expected data, got %q", data)
+	}
+
+	link := &Link{ID: "b", BytesPerSecond: 4096}
+	first := manager.Throttle(ctx, link, bytes.NewReader(make([]byte, 8192)))
+	second := manager.Throttle(ctx, link, bytes.NewReader(make([]byte, 2048)))
+	if len(manager.limiters) != 1 {
+		t.Fatalf("expected downloads of one link to share a limiter, got %d", len(manager.limiters))
+	}
+	start := time.Now()
+	if n, err := io.Copy(io.Discard, first); n != 8192 || err != nil {
+		t.Fatalf("Copy: %d, %v", n, err)
+	}
+	if n, err := io.Copy(io.Discard, second); n != 2048 || err != nil {
+		t.Fatalf("Copy: %d, %v", n, err)
+	}
+	// The burst allows the first 4 KiB at once; the other 6 KiB take 1.5s.
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("expected throttled downloads to take over a second, took %s", elapsed)
+	}
+	_ = first.Close()
+	_ = first.Close()
+	if len(manager.limiters) != 1 {
+		t.Error("expected the limiter to stay while a download uses it")
+	}
+	_ = second.Close()
+	if len(manager.limiters) != 0 {
+		t.Error("expected the limiter to be dropped after the last download")
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	slow := manager.Throttle(cancelled, &Link{ID: "c", BytesPerSecond: 1}, strings.NewReader("xy"))
+	defer slow.Close()
+	if _, err := io.ReadAll(slow); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestStorageReservesLinkNamespace(t *testing.T) {
+	manager, backend, _ := newTestManager(t)
+	storage := NewStorage(backend, manager)
+	ctx := context.Background()
+
+	if err := storage.PutWithContext(ctx, "data", strings.NewReader("x")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	link, _, err := manager.Create(ctx, "data", Options{})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	reserved := DefaultPrefix + link.ID
+	checks := map[string]error{
+		"put":      storage.PutWithContext(ctx, reserved, strings.NewReader("x")),
+		"metadata": storage.PutWithMetadata(ctx, reserved, strings.NewReader("x"), nil),
+		"delete":   storage.DeleteWithContext(ctx, reserved),
+		"append":   storage.Append(ctx, reserved, strings.NewReader("x")),
+		"compose":  storage.Compose(ctx, "copy", reserved),
+		"update":   storage.UpdateMetadata(ctx, reserved, &common.Metadata{}),
+	}
+	_, checks["get"] = storage.GetWithContext(ctx, reserved)
+	_, checks["range"] = storage.GetRange(ctx, reserved, 0, 1)
+	_, checks["stat"] = storage.GetMetadata(ctx, reserved)
+	_, checks["exists"] = storage.Exists(ctx, reserved)
+	for name, err := range checks {
+		if !errors.Is(err, common.ErrReservedKey) || !errors.Is(err, common.ErrPermissionDenied) {
+			t.Errorf("%s: expected common.ErrReservedKey, got %v", name, err)
+		}
+	}
+
+	keys, err := storage.ListWithContext(ctx, "")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(keys) != 1 || keys[0] != "data" {
+		t.Errorf("expected only data to be listed, got %v", keys)
+	}
+
+	result, err := storage.ListWithOptions(ctx, &common.ListOptions{Delimiter: "/"})
+	if err != nil {
+		t.Fatalf("ListWithOptions: %v", err)
+	}
+	if len(result.Objects) != 1 || len(result.CommonPrefixes) != 0 {
+		t.Errorf("expected link prefix to be hidden, got %+v %v", result.Objects, result.CommonPrefixes)
+	}
+}
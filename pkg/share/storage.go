@@ -0,0 +1,36 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package share
+
+import "github.com/jeremyhahn/go-objstore/pkg/common"
+
+// Storage wraps a backend and reserves a Manager's link namespace: object
+// operations on keys under the link prefix fail with common.ErrReservedKey
+// and listings leave them out, so clients can only change links through the
+// Manager.
+type Storage struct {
+	*common.ReservedStorage
+	manager *Manager
+}
+
+// NewStorage returns underlying wrapped so that manager's link prefix is
+// reserved.
+func NewStorage(underlying common.Storage, manager *Manager) *Storage {
+	return &Storage{ReservedStorage: common.NewReservedStorage(underlying, manager.Prefix()), manager: manager}
+}
+
+// Manager returns the link manager whose namespace s reserves.
+func (s *Storage) Manager() *Manager {
+	return s.manager
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package share
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// maxChunk bounds the bytes a throttled reader reads at once, and so the
+// burst of a link's bandwidth cap.
+const maxChunk = 32 * 1024
+
+// limiter is the bandwidth limiter shared by a link's downloads, counted
+// so it is dropped when the last one ends.
+type limiter struct {
+	*rate.Limiter
+	users int
+}
+
+// Throttle returns r limited to link's bandwidth cap, which all of the
+// link's downloads through m share. The returned reader must be closed
+// when the download ends; closing it does not close r. Reads fail with
+// ctx's error once it is done.
+func (m *Manager) Throttle(ctx context.Context, link *Link, r io.Reader) io.ReadCloser {
+	if link.BytesPerSecond <= 0 {
+		return io.NopCloser(r)
+	}
+
+	m.mu.Lock()
+	l, ok := m.limiters[link.ID]
+	if !ok {
+		burst := int(min(link.BytesPerSecond, maxChunk))
+		l = &limiter{Limiter: rate.NewLimiter(rate.Limit(link.BytesPerSecond), burst)}
+		m.limiters[link.ID] = l
+	}
+	l.users++
+	m.mu.Unlock()
+
+	return &throttledReader{ctx: ctx, r: r, manager: m, id: link.ID, limiter: l}
+}
+
+// release drops a download's use of the limiter of the link with id.
+func (m *Manager) release(id string, l *limiter) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if l.users--; l.users == 0 && m.limiters[id] == l {
+		delete(m.limiters, id)
+	}
+}
+
+// throttledReader waits for its link's limiter after every read.
+type throttledReader struct {
+	ctx     context.Context
+	r       io.Reader
+	manager *Manager
+	id      string
+	limiter *limiter
+	once    sync.Once
+}
+
+func (t *throttledReader) Read(p []byte) (int, error) {
+	if burst := t.limiter.Burst(); len(p) > burst {
+		p = p[:burst]
+	}
+	n, err := t.r.Read(p)
+	if n > 0 {
+		if werr := t.limiter.WaitN(t.ctx, n); werr != nil {
+			return n, werr
+		}
+	}
+	return n, err
+}
+
+func (t *throttledReader) Close() error {
+	t.once.Do(func() { t.manager.release(t.id, t.limiter) })
+	return nil
+}
@@ -0,0 +1,396 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package signing signs objects when they are written and verifies them
+// when they are read, so consumers can tell that an object is the one its
+// producer wrote even if the backend storing it is compromised.
+//
+// A Signer hashes the content of every object it writes and signs a
+// Manifest binding the object key to the content's SHA-256 digest and
+// size. The signature and the manifest fields are stored as custom
+// metadata. A Verifier checks the signature against a set of trusted
+// ed25519 public keys before an object is read, and checks the digest as
+// the content streams; a modified, truncated, unsigned or moved object
+// fails to read.
+//
+//	signer := signing.NewSigner(privateKey)
+//	verifier := signing.NewVerifier(publicKey)
+//	storage := signing.NewStorage(backend, signer, verifier)
+//
+// Keys are stored as PEM-encoded PKCS #8 (private) and PKIX (public) ed25519
+// keys, the format written by "openssl genpkey -algorithm ed25519".
+package signing
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// manifestVersion is the version of the signed manifest format.
+const manifestVersion = 1
+
+// Custom metadata stored with signed objects. Servers may change the case
+// of the names, so they are looked up case-insensitively.
+const (
+	MetaSignature = "signature"
+	MetaKeyID     = "signature-key-id"
+	MetaSHA256    = "signature-sha256"
+	MetaSize      = "signature-size"
+	MetaSignedAt  = "signature-signed-at"
+)
+
+var (
+	// ErrUnsigned is returned when reading an object that has no signature.
+	ErrUnsigned = fmt.Errorf("%w: object is not signed", common.ErrPreconditionFailed)
+
+	// ErrUntrustedKey is returned when an object is signed by a key the
+	// Verifier does not trust.
+	ErrUntrustedKey = fmt.Errorf("%w: object is signed by an untrusted key", common.ErrPermissionDenied)
+
+	// ErrInvalidSignature is returned when the signature of an object does
+	// not match its manifest, or the manifest was stored under another key.
+	ErrInvalidSignature = fmt.Errorf("%w: invalid object signature", common.ErrPermissionDenied)
+
+	// ErrTampered is returned by reads whose content does not match the
+	// digest or size of its signed manifest.
+	ErrTampered = fmt.Errorf("%w: object content does not match its signed manifest", common.ErrPermissionDenied)
+
+	// ErrNoVerifier is returned when verifying without trusted keys.
+	ErrNoVerifier = fmt.Errorf("%w: no signature verification keys configured", common.ErrPreconditionFailed)
+
+	// ErrInvalidKey is returned for a malformed key file.
+	ErrInvalidKey = fmt.Errorf("%w: invalid signing key", common.ErrInvalidArgument)
+)
+
+// Manifest is the signed statement about an object. It binds the object
+// key, so a signed object copied or moved to another key fails to verify
+// until it is signed again.
+type Manifest struct {
+	Version  int       `json:"version"`
+	Key      string    `json:"key"`
+	Size     int64     `json:"size"`
+	SHA256   string    `json:"sha256"`
+	KeyID    string    `json:"key_id"`
+	SignedAt time.Time `json:"signed_at"`
+}
+
+// bytes returns the encoding of the manifest that is signed.
+func (m *Manifest) bytes() []byte {
+	data, _ := json.Marshal(m)
+	return data
+}
+
+// GenerateKey returns a new random ed25519 signing key.
+func GenerateKey() (ed25519.PrivateKey, error) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	return private, err
+}
+
+// KeyID returns the ID of a public key: the hex encoding of the first
+// eight bytes of its SHA-256 digest.
+func KeyID(public ed25519.PublicKey) string {
+	sum := sha256.Sum256(public)
+	return hex.EncodeToString(sum[:8])
+}
+
+// SavePrivateKey writes key to path as PEM-encoded PKCS #8, readable only
+// by its owner.
+func SavePrivateKey(path string, key ed25519.PrivateKey) error {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return err
+	}
+	return writeKeyFile(path, &pem.Block{Type: "PRIVATE KEY", Bytes: der}, 0o600)
+}
+
+// SavePublicKey writes key to path as PEM-encoded PKIX.
+func SavePublicKey(path string, key ed25519.PublicKey) error {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return err
+	}
+	return writeKeyFile(path, &pem.Block{Type: "PUBLIC KEY", Bytes: der}, 0o644)
+}
+
+// LoadPrivateKey reads an ed25519 private key saved with SavePrivateKey.
+func LoadPrivateKey(path string) (ed25519.PrivateKey, error) {
+	der, err := readKeyFile(path, "PRIVATE KEY")
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidKey, path, err)
+	}
+	private, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s: not an ed25519 key", ErrInvalidKey, path)
+	}
+	return private, nil
+}
+
+// LoadPublicKey reads an ed25519 public key saved with SavePublicKey.
+func LoadPublicKey(path string) (ed25519.PublicKey, error) {
+	der, err := readKeyFile(path, "PUBLIC KEY")
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidKey, path, err)
+	}
+	public, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s: not an ed25519 key", ErrInvalidKey, path)
+	}
+	return public, nil
+}
+
+func writeKeyFile(path string, block *pem.Block, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	// #nosec G304 -- path supplied by the user
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	if err := pem.Encode(f, block); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func readKeyFile(path, blockType string) ([]byte, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path supplied by the user
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != blockType {
+		return nil, fmt.Errorf("%w: %s: no %s PEM block", ErrInvalidKey, path, blockType)
+	}
+	return block.Bytes, nil
+}
+
+// Signer signs the objects it writes.
+type Signer struct {
+	key ed25519.PrivateKey
+	id  string
+	now func() time.Time
+}
+
+// NewSigner returns a Signer signing with key.
+func NewSigner(key ed25519.PrivateKey) *Signer {
+	return &Signer{key: key, id: KeyID(key.Public().(ed25519.PublicKey)), now: time.Now}
+}
+
+// KeyID returns the ID of the signing key.
+func (s *Signer) KeyID() string {
+	return s.id
+}
+
+// Sign prepares an object for storage under key. Signing needs the digest
+// of the whole content before it is stored, so data is spooled to a
+// temporary file; Sign returns the spooled content, which must be closed
+// to remove the file, and metadata with the signature fields added.
+// metadata may be nil.
+func (s *Signer) Sign(key string, data io.Reader, metadata *common.Metadata) (io.ReadCloser, *common.Metadata, error) {
+	spool, err := os.CreateTemp("", "objstore-sign-*")
+	if err != nil {
+		return nil, nil, err
+	}
+	content := &spooledFile{File: spool}
+	h := sha256.New()
+	size, err := io.Copy(io.MultiWriter(spool, h), data)
+	if err == nil {
+		_, err = spool.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		_ = content.Close()
+		return nil, nil, err
+	}
+
+	m := &Manifest{
+		Version:  manifestVersion,
+		Key:      key,
+		Size:     size,
+		SHA256:   hex.EncodeToString(h.Sum(nil)),
+		KeyID:    s.id,
+		SignedAt: s.now().UTC().Truncate(time.Second),
+	}
+	signed := &common.Metadata{Size: size}
+	if metadata != nil {
+		*signed = *metadata
+		signed.Size = size
+	}
+	signed.Custom = make(map[string]string, len(custom(metadata))+5)
+	for k, v := range custom(metadata) {
+		signed.Custom[k] = v
+	}
+	signed.Custom[MetaSignature] = base64.StdEncoding.EncodeToString(ed25519.Sign(s.key, m.bytes()))
+	signed.Custom[MetaKeyID] = m.KeyID
+	signed.Custom[MetaSHA256] = m.SHA256
+	signed.Custom[MetaSize] = strconv.FormatInt(m.Size, 10)
+	signed.Custom[MetaSignedAt] = m.SignedAt.Format(time.RFC3339)
+	return content, signed, nil
+}
+
+// spooledFile is a temporary file that is removed when closed.
+type spooledFile struct {
+	*os.File
+}
+
+func (f *spooledFile) Close() error {
+	err := f.File.Close()
+	_ = os.Remove(f.Name())
+	return err
+}
+
+// Verifier verifies objects against a set of trusted public keys.
+type Verifier struct {
+	keys map[string]ed25519.PublicKey
+}
+
+// NewVerifier returns a Verifier trusting keys.
+func NewVerifier(keys ...ed25519.PublicKey) *Verifier {
+	v := &Verifier{keys: make(map[string]ed25519.PublicKey, len(keys))}
+	for _, key := range keys {
+		v.keys[KeyID(key)] = key
+	}
+	return v
+}
+
+// Verify checks the signature of the object stored under key with
+// metadata and returns its manifest. The content is not read; use Open to
+// check it as well.
+func (v *Verifier) Verify(key string, metadata *common.Metadata) (*Manifest, error) {
+	fields := custom(metadata)
+	signature := field(fields, MetaSignature)
+	if !IsSigned(metadata) {
+		return nil, fmt.Errorf("%w: %s", ErrUnsigned, key)
+	}
+	m := &Manifest{
+		Version: manifestVersion,
+		Key:     key,
+		SHA256:  field(fields, MetaSHA256),
+		KeyID:   field(fields, MetaKeyID),
+	}
+	public, ok := v.keys[m.KeyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s: key %q", ErrUntrustedKey, key, m.KeyID)
+	}
+	var err error
+	if m.Size, err = strconv.ParseInt(field(fields, MetaSize), 10, 64); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSignature, key)
+	}
+	if m.SignedAt, err = time.Parse(time.RFC3339, field(fields, MetaSignedAt)); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSignature, key)
+	}
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil || !ed25519.Verify(public, m.bytes(), sig) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSignature, key)
+	}
+	return m, nil
+}
+
+// Open verifies the signature of the object stored under key with
+// metadata and returns its content, which is checked against the manifest
+// as it is read: the read that reaches the end of a modified or truncated
+// object fails with ErrTampered instead of io.EOF.
+func (v *Verifier) Open(key string, data io.Reader, metadata *common.Metadata) (io.Reader, error) {
+	m, err := v.Verify(key, metadata)
+	if err != nil {
+		return nil, err
+	}
+	return &verifyingReader{r: data, manifest: m, hash: sha256.New()}, nil
+}
+
+// verifyingReader checks content against its manifest as it is read.
+type verifyingReader struct {
+	r        io.Reader
+	manifest *Manifest
+	hash     hash.Hash
+	n        int64
+}
+
+func (r *verifyingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.hash.Write(p[:n])
+	r.n += int64(n)
+	if r.n > r.manifest.Size {
+		return n, fmt.Errorf("%w: %s", ErrTampered, r.manifest.Key)
+	}
+	if err == io.EOF && (r.n != r.manifest.Size || hex.EncodeToString(r.hash.Sum(nil)) != r.manifest.SHA256) {
+		return n, fmt.Errorf("%w: %s", ErrTampered, r.manifest.Key)
+	}
+	return n, err
+}
+
+// IsSigned reports whether metadata is that of a signed object.
+func IsSigned(metadata *common.Metadata) bool {
+	return field(custom(metadata), MetaSignature) != ""
+}
+
+// KeepSignature copies the signature fields of stored, the metadata of a
+// signed object, into update, so a metadata update keeps the object
+// verifiable. update may be nil.
+func KeepSignature(stored, update *common.Metadata) {
+	if update == nil || !IsSigned(stored) {
+		return
+	}
+	if update.Custom == nil {
+		update.Custom = make(map[string]string)
+	}
+	for _, name := range []string{MetaSignature, MetaKeyID, MetaSHA256, MetaSize, MetaSignedAt} {
+		update.Custom[name] = field(stored.Custom, name)
+	}
+}
+
+// field returns the custom metadata field name, ignoring case.
+func field(custom map[string]string, name string) string {
+	if v, ok := custom[name]; ok {
+		return v
+	}
+	for k, v := range custom {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return ""
+}
+
+// custom returns the custom metadata of metadata, which may be nil.
+func custom(metadata *common.Metadata) map[string]string {
+	if metadata == nil {
+		return nil
+	}
+	return metadata.Custom
+}
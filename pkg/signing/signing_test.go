@@ -0,0 +1,150 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package signing
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+func newKey(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func readAll(t *testing.T, s common.Storage, key string) ([]byte, error) {
+	t.Helper()
+	rc, err := s.GetWithContext(context.Background(), key)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+func TestSignAndVerify(t *testing.T) {
+	ctx := context.Background()
+	key := newKey(t)
+	backend := memory.New()
+	s := NewStorage(backend, NewSigner(key), NewVerifier(key.Public().(ed25519.PublicKey)))
+
+	data := bytes.Repeat([]byte("release artifact "), 1000)
+	metadata := &common.Metadata{ContentType: "application/gzip", Custom: map[string]string{"build": "42"}}
+	if err := s.PutWithMetadata(ctx, "releases/app.tgz", bytes.NewReader(data), metadata); err != nil {
+		t.Fatal(err)
+	}
+	if len(metadata.Custom) != 1 {
+		t.Errorf("Put() modified the caller's metadata: %+v", metadata.Custom)
+	}
+	got, err := readAll(t, s, "releases/app.tgz")
+	if err != nil || !bytes.Equal(got, data) {
+		t.Fatalf("Get() = %d bytes, %v", len(got), err)
+	}
+	m, err := s.Verify(ctx, "releases/app.tgz")
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if m.Key != "releases/app.tgz" || m.Size != int64(len(data)) || m.KeyID != KeyID(key.Public().(ed25519.PublicKey)) {
+		t.Errorf("Verify() = %+v", m)
+	}
+
+	// Metadata updates keep the signature.
+	if err := s.UpdateMetadata(ctx, "releases/app.tgz", &common.Metadata{Custom: map[string]string{"build": "43"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Verify(ctx, "releases/app.tgz"); err != nil {
+		t.Errorf("Verify() after UpdateMetadata error = %v", err)
+	}
+
+	stored, err := backend.GetMetadata(ctx, "releases/app.tgz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		key     string
+		content []byte
+		want    error
+	}{
+		{"modified", "releases/app.tgz", bytes.ToUpper(data), ErrTampered},
+		{"truncated", "releases/app.tgz", data[:len(data)-1], ErrTampered},
+		{"extended", "releases/app.tgz", append(bytes.Clone(data), '!'), ErrTampered},
+		{"moved", "releases/old.tgz", data, ErrInvalidSignature},
+	}
+	for _, tt := range tests {
+		if err := backend.PutWithMetadata(ctx, tt.key, bytes.NewReader(tt.content), stored); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := readAll(t, s, tt.key); !errors.Is(err, tt.want) {
+			t.Errorf("%s: Get() error = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	if err := backend.Put("unsigned", strings.NewReader("x")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readAll(t, s, "unsigned"); !errors.Is(err, ErrUnsigned) {
+		t.Errorf("unsigned: Get() error = %v, want ErrUnsigned", err)
+	}
+	other := NewStorage(backend, NewSigner(newKey(t)), nil)
+	if err := other.Put("other", strings.NewReader("x")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readAll(t, s, "other"); !errors.Is(err, ErrUntrustedKey) {
+		t.Errorf("untrusted: Get() error = %v, want ErrUntrustedKey", err)
+	}
+	if _, err := other.Verify(ctx, "other"); !errors.Is(err, ErrNoVerifier) {
+		t.Errorf("Verify() without keys error = %v, want ErrNoVerifier", err)
+	}
+}
+
+func TestKeyFiles(t *testing.T) {
+	dir := t.TempDir()
+	key := newKey(t)
+	public := key.Public().(ed25519.PublicKey)
+	if err := SavePrivateKey(filepath.Join(dir, "signing.pem"), key); err != nil {
+		t.Fatal(err)
+	}
+	if err := SavePublicKey(filepath.Join(dir, "signing.pub"), public); err != nil {
+		t.Fatal(err)
+	}
+	if err := SavePrivateKey(filepath.Join(dir, "signing.pem"), newKey(t)); err == nil {
+		t.Error("SavePrivateKey() overwrote an existing key")
+	}
+
+	loaded, err := LoadPrivateKey(filepath.Join(dir, "signing.pem"))
+	if err != nil || !loaded.Equal(key) {
+		t.Errorf("LoadPrivateKey() = %v", err)
+	}
+	loadedPublic, err := LoadPublicKey(filepath.Join(dir, "signing.pub"))
+	if err != nil || !loadedPublic.Equal(public) {
+		t.Errorf("LoadPublicKey() = %v", err)
+	}
+	if _, err := LoadPublicKey(filepath.Join(dir, "signing.pem")); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("LoadPublicKey(private key) error = %v, want ErrInvalidKey", err)
+	}
+}
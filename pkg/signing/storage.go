@@ -0,0 +1,186 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package signing
+
+import (
+	"context"
+	"io"
+	"maps"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// Storage signs objects written to an underlying backend and verifies
+// objects read from it. Either the signer or the verifier may be nil to
+// only sign or only verify. Listings, deletes and other operations pass
+// through; objects copied or moved by the backend must be signed again
+// under their new key.
+type Storage struct {
+	common.Storage
+	signer   *Signer
+	verifier *Verifier
+}
+
+// NewStorage returns underlying with objects signed by signer and verified
+// by verifier.
+func NewStorage(underlying common.Storage, signer *Signer, verifier *Verifier) *Storage {
+	return &Storage{Storage: underlying, signer: signer, verifier: verifier}
+}
+
+// Underlying returns the wrapped backend.
+func (s *Storage) Underlying() common.Storage {
+	return s.Storage
+}
+
+// Put signs and stores an object.
+func (s *Storage) Put(key string, data io.Reader) error {
+	return s.PutWithMetadata(context.Background(), key, data, nil)
+}
+
+// PutWithContext signs and stores an object.
+func (s *Storage) PutWithContext(ctx context.Context, key string, data io.Reader) error {
+	return s.PutWithMetadata(ctx, key, data, nil)
+}
+
+// PutWithMetadata signs and stores an object, adding the signature to its
+// metadata.
+func (s *Storage) PutWithMetadata(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) error {
+	if s.signer == nil {
+		return s.Storage.PutWithMetadata(ctx, key, data, metadata)
+	}
+	content, signed, err := s.signer.Sign(key, data, metadata)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = content.Close() }()
+	return s.Storage.PutWithMetadata(ctx, key, content, signed)
+}
+
+// PutWithInfo signs and stores an object like PutWithMetadata and returns
+// what was stored.
+func (s *Storage) PutWithInfo(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) (*common.ObjectInfo, error) {
+	if s.signer == nil {
+		return common.PutWithInfo(ctx, s.Storage, key, data, metadata)
+	}
+	content, signed, err := s.signer.Sign(key, data, metadata)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = content.Close() }()
+	return common.PutWithInfo(ctx, s.Storage, key, content, signed)
+}
+
+// Get retrieves and verifies an object.
+func (s *Storage) Get(key string) (io.ReadCloser, error) {
+	return s.GetWithContext(context.Background(), key)
+}
+
+// GetWithContext retrieves and verifies an object. Objects that are not
+// signed by a trusted key fail to open; reading the returned content
+// fails with ErrTampered if it does not match the signed manifest.
+func (s *Storage) GetWithContext(ctx context.Context, key string) (io.ReadCloser, error) {
+	if s.verifier == nil {
+		return s.Storage.GetWithContext(ctx, key)
+	}
+	metadata, err := s.Storage.GetMetadata(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.verifier.Verify(key, metadata); err != nil {
+		return nil, err
+	}
+	rc, err := s.Storage.GetWithContext(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	verified, err := s.verifier.Open(key, rc, metadata)
+	if err != nil {
+		_ = rc.Close()
+		return nil, err
+	}
+	return readCloser{Reader: verified, Closer: rc}, nil
+}
+
+// GetAsOf retrieves and verifies an earlier version of an object against
+// the signature stored with that version.
+func (s *Storage) GetAsOf(ctx context.Context, key string, at time.Time) (io.ReadCloser, *common.Metadata, error) {
+	rc, metadata, err := common.GetAsOf(ctx, s.Storage, key, at)
+	if err != nil || s.verifier == nil {
+		return rc, metadata, err
+	}
+	if _, err := s.verifier.Verify(key, metadata); err != nil {
+		_ = rc.Close()
+		return nil, nil, err
+	}
+	verified, err := s.verifier.Open(key, rc, metadata)
+	if err != nil {
+		_ = rc.Close()
+		return nil, nil, err
+	}
+	return readCloser{Reader: verified, Closer: rc}, metadata, nil
+}
+
+// Verify checks the signature and content of the object stored under key
+// and returns its manifest.
+func (s *Storage) Verify(ctx context.Context, key string) (*Manifest, error) {
+	if s.verifier == nil {
+		return nil, ErrNoVerifier
+	}
+	metadata, err := s.Storage.GetMetadata(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	m, err := s.verifier.Verify(key, metadata)
+	if err != nil {
+		return nil, err
+	}
+	rc, err := s.Storage.GetWithContext(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rc.Close() }()
+	verified, err := s.verifier.Open(key, rc, metadata)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(io.Discard, verified); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// UpdateMetadata replaces the metadata of an object, keeping its
+// signature.
+func (s *Storage) UpdateMetadata(ctx context.Context, key string, metadata *common.Metadata) error {
+	stored, err := s.Storage.GetMetadata(ctx, key)
+	if err != nil {
+		return err
+	}
+	if metadata != nil {
+		update := *metadata
+		update.Custom = maps.Clone(metadata.Custom)
+		KeepSignature(stored, &update)
+		metadata = &update
+	}
+	return s.Storage.UpdateMetadata(ctx, key, metadata)
+}
+
+// readCloser reads verified content and closes the stored content.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+var _ common.Storage = (*Storage)(nil)
@@ -0,0 +1,475 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package stats records periodic snapshots of the object count, total size
+// and largest prefixes of each backend, so capacity can be planned from
+// their history and growth rate.
+//
+// A Collector lists every backend at a fixed interval and keeps the
+// snapshots for a retention period, optionally persisted to a JSON file so
+// the history survives restarts. Reports are served as JSON, and the latest
+// snapshot as Prometheus gauges, for dashboards such as Grafana.
+package stats
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+const (
+	// DefaultInterval is the time between snapshots when Config.Interval
+	// is zero.
+	DefaultInterval = time.Hour
+
+	// DefaultRetention is how long snapshots are kept when
+	// Config.Retention is zero.
+	DefaultRetention = 90 * 24 * time.Hour
+
+	// DefaultTopPrefixes is the number of prefixes recorded per snapshot
+	// when Config.TopPrefixes is zero.
+	DefaultTopPrefixes = 10
+
+	// DefaultPrefixDepth is the number of key segments prefixes are
+	// grouped by when Config.PrefixDepth is zero.
+	DefaultPrefixDepth = 1
+)
+
+var (
+	// ErrInvalidConfig is returned when a stats configuration is malformed.
+	ErrInvalidConfig = errors.New("invalid stats configuration")
+
+	// ErrHistoryCorrupt is returned when a persisted history cannot be
+	// decoded.
+	ErrHistoryCorrupt = errors.New("stats history is corrupt")
+)
+
+// Config configures snapshot collection.
+type Config struct {
+	// Interval is the time between snapshots.
+	Interval time.Duration
+
+	// Retention is how long snapshots are kept.
+	Retention time.Duration
+
+	// TopPrefixes is the number of largest prefixes, by size, recorded in
+	// each snapshot. A negative value records none.
+	TopPrefixes int
+
+	// PrefixDepth is the number of "/"-separated key segments prefixes
+	// are grouped by: 1 groups "logs/2025/a.log" under "logs/", 2 under
+	// "logs/2025/".
+	PrefixDepth int
+
+	// HistoryPath is the file snapshots are persisted to. If empty, they
+	// are kept in memory and lost on restart.
+	HistoryPath string
+
+	// Manual disables background snapshots: the owner calls Collect
+	// instead, for example from a job scheduler (see pkg/jobs).
+	Manual bool
+}
+
+// Validate checks that no setting is negative, other than TopPrefixes.
+func (c *Config) Validate() error {
+	if c.Interval < 0 || c.Retention < 0 || c.PrefixDepth < 0 {
+		return fmt.Errorf("%w: interval, retention and prefix depth must not be negative", ErrInvalidConfig)
+	}
+	return nil
+}
+
+// PrefixUsage is the number and size of the objects under a prefix.
+type PrefixUsage struct {
+	Prefix  string `json:"prefix"`
+	Objects int64  `json:"objects"`
+	Bytes   int64  `json:"bytes"`
+}
+
+// Snapshot is the usage of a backend at a point in time.
+type Snapshot struct {
+	Time        time.Time     `json:"time"`
+	Objects     int64         `json:"objects"`
+	Bytes       int64         `json:"bytes"`
+	TopPrefixes []PrefixUsage `json:"top_prefixes,omitempty"`
+}
+
+// BackendStats is the snapshot history of a backend and its growth over
+// that history.
+type BackendStats struct {
+	Backend string `json:"backend"`
+
+	// Latest is the most recent snapshot, or nil before the first one.
+	Latest *Snapshot `json:"latest,omitempty"`
+
+	// GrowthBytesPerDay and GrowthObjectsPerDay are the average change per
+	// day between the first and last snapshot of Series.
+	GrowthBytesPerDay   float64 `json:"growth_bytes_per_day"`
+	GrowthObjectsPerDay float64 `json:"growth_objects_per_day"`
+
+	// Series holds the snapshots in the requested window, oldest first.
+	Series []Snapshot `json:"series"`
+}
+
+// Collector takes and keeps snapshots of a set of backends. It is safe for
+// concurrent use.
+type Collector struct {
+	cfg      Config
+	backends func() map[string]common.Storage
+
+	mu     sync.RWMutex
+	series map[string][]Snapshot
+
+	// collectMu serializes collections.
+	collectMu sync.Mutex
+
+	startOnce sync.Once
+	closeOnce sync.Once
+	stop      chan struct{}
+	done      chan struct{}
+
+	// now is replaced by tests.
+	now func() time.Time
+}
+
+// NewCollector creates a Collector taking snapshots of the backends
+// returned by backends, keyed by name, loading the history persisted to
+// cfg.HistoryPath if it exists. Call Start to take snapshots in the
+// background.
+func NewCollector(cfg *Config, backends func() map[string]common.Storage) (*Collector, error) {
+	c := Config{}
+	if cfg != nil {
+		c = *cfg
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	if c.Interval == 0 {
+		c.Interval = DefaultInterval
+	}
+	if c.Retention == 0 {
+		c.Retention = DefaultRetention
+	}
+	if c.TopPrefixes == 0 {
+		c.TopPrefixes = DefaultTopPrefixes
+	}
+	if c.PrefixDepth == 0 {
+		c.PrefixDepth = DefaultPrefixDepth
+	}
+
+	collector := &Collector{
+		cfg:      c,
+		backends: backends,
+		series:   make(map[string][]Snapshot),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+		now:      time.Now,
+	}
+	if c.HistoryPath == "" {
+		return collector, nil
+	}
+	data, err := os.ReadFile(c.HistoryPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return collector, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stats history: %w", err)
+	}
+	if err := json.Unmarshal(data, &collector.series); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrHistoryCorrupt, err)
+	}
+	if collector.series == nil {
+		collector.series = make(map[string][]Snapshot)
+	}
+	return collector, nil
+}
+
+// Config returns the configuration of c, with defaults applied.
+func (c *Collector) Config() Config {
+	return c.cfg
+}
+
+// Start takes a snapshot now and then every interval until c is closed.
+func (c *Collector) Start() {
+	c.startOnce.Do(func() {
+		go c.run()
+	})
+}
+
+func (c *Collector) run() {
+	defer close(c.done)
+
+	ticker := time.NewTicker(c.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		_ = c.Collect(context.Background()) // #nosec G104 -- A failed snapshot is retried at the next interval
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Close stops background collection and waits for an in-flight snapshot
+// to finish.
+func (c *Collector) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.stop)
+		started := true
+		c.startOnce.Do(func() { started = false })
+		if started {
+			<-c.done
+		}
+	})
+	return nil
+}
+
+// Collect takes a snapshot of every backend, drops snapshots older than
+// the retention period and persists the history. A backend that cannot be
+// listed is skipped and the first such error is returned.
+func (c *Collector) Collect(ctx context.Context) error {
+	c.collectMu.Lock()
+	defer c.collectMu.Unlock()
+
+	var firstErr error
+	taken := make(map[string]Snapshot)
+	for name, storage := range c.backends() {
+		snapshot, err := c.snapshot(ctx, storage)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to snapshot backend %q: %w", name, err)
+			}
+			continue
+		}
+		taken[name] = *snapshot
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cutoff := c.now().Add(-c.cfg.Retention)
+	for name, snapshot := range taken {
+		c.series[name] = append(c.series[name], snapshot)
+	}
+	for name, series := range c.series {
+		i := sort.Search(len(series), func(i int) bool { return !series[i].Time.Before(cutoff) })
+		if i == len(series) {
+			delete(c.series, name)
+			continue
+		}
+		c.series[name] = series[i:]
+	}
+	if err := c.saveLocked(); err != nil && firstErr == nil {
+		firstErr = err
+	}
+	return firstErr
+}
+
+// snapshot lists storage and totals its objects by prefix.
+func (c *Collector) snapshot(ctx context.Context, storage common.Storage) (*Snapshot, error) {
+	snapshot := &Snapshot{}
+	prefixes := make(map[string]*PrefixUsage)
+	opts := &common.ListOptions{MaxResults: 1000}
+	for {
+		result, err := storage.ListWithOptions(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range result.Objects {
+			var size int64
+			if obj.Metadata != nil {
+				size = obj.Metadata.Size
+			}
+			snapshot.Objects++
+			snapshot.Bytes += size
+
+			prefix := c.prefix(obj.Key)
+			usage, ok := prefixes[prefix]
+			if !ok {
+				usage = &PrefixUsage{Prefix: prefix}
+				prefixes[prefix] = usage
+			}
+			usage.Objects++
+			usage.Bytes += size
+		}
+		if !result.Truncated || result.NextToken == "" {
+			break
+		}
+		opts.ContinueFrom = result.NextToken
+	}
+	snapshot.Time = c.now().UTC()
+
+	if c.cfg.TopPrefixes > 0 {
+		for _, usage := range prefixes {
+			snapshot.TopPrefixes = append(snapshot.TopPrefixes, *usage)
+		}
+		sort.Slice(snapshot.TopPrefixes, func(i, j int) bool {
+			a, b := snapshot.TopPrefixes[i], snapshot.TopPrefixes[j]
+			if a.Bytes != b.Bytes {
+				return a.Bytes > b.Bytes
+			}
+			return a.Prefix < b.Prefix
+		})
+		if len(snapshot.TopPrefixes) > c.cfg.TopPrefixes {
+			snapshot.TopPrefixes = snapshot.TopPrefixes[:c.cfg.TopPrefixes]
+		}
+	}
+	return snapshot, nil
+}
+
+// prefix returns the first PrefixDepth segments of key, including the
+// trailing "/", or "" for keys with fewer segments.
+func (c *Collector) prefix(key string) string {
+	end := 0
+	for range c.cfg.PrefixDepth {
+		i := strings.IndexByte(key[end:], '/')
+		if i < 0 {
+			break
+		}
+		end += i + 1
+	}
+	return key[:end]
+}
+
+// Stats returns the history of each backend since the given time, sorted
+// by backend name. A zero since returns the whole history. When backend
+// is non-empty only that backend is returned.
+func (c *Collector) Stats(backend string, since time.Time) []BackendStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	names := make([]string, 0, len(c.series))
+	for name := range c.series {
+		if backend == "" || name == backend {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	out := make([]BackendStats, 0, len(names))
+	for _, name := range names {
+		all := c.series[name]
+		i := sort.Search(len(all), func(i int) bool { return !all[i].Time.Before(since) })
+		window := append([]Snapshot(nil), all[i:]...)
+
+		stats := BackendStats{Backend: name, Series: window}
+		if len(all) > 0 {
+			latest := all[len(all)-1]
+			stats.Latest = &latest
+		}
+		stats.GrowthBytesPerDay, stats.GrowthObjectsPerDay = Growth(window)
+		out = append(out, stats)
+	}
+	return out
+}
+
+// Growth returns the average change in bytes and objects per day between
+// the first and last of series, or zero when it spans no time.
+func Growth(series []Snapshot) (bytesPerDay, objectsPerDay float64) {
+	if len(series) < 2 {
+		return 0, 0
+	}
+	first, last := series[0], series[len(series)-1]
+	days := last.Time.Sub(first.Time).Hours() / 24
+	if days <= 0 {
+		return 0, 0
+	}
+	return float64(last.Bytes-first.Bytes) / days, float64(last.Objects-first.Objects) / days
+}
+
+// WritePrometheus renders the latest snapshot and growth of each backend
+// as Prometheus gauges.
+func (c *Collector) WritePrometheus(w io.Writer) {
+	stats := c.Stats("", time.Time{})
+
+	fmt.Fprintf(w, "# HELP objstore_storage_objects Objects stored in the backend at the last snapshot.\n")
+	fmt.Fprintf(w, "# TYPE objstore_storage_objects gauge\n")
+	for _, s := range stats {
+		fmt.Fprintf(w, "objstore_storage_objects{backend=%q} %d\n", s.Backend, s.Latest.Objects)
+	}
+
+	fmt.Fprintf(w, "# HELP objstore_storage_bytes Bytes stored in the backend at the last snapshot.\n")
+	fmt.Fprintf(w, "# TYPE objstore_storage_bytes gauge\n")
+	for _, s := range stats {
+		fmt.Fprintf(w, "objstore_storage_bytes{backend=%q} %d\n", s.Backend, s.Latest.Bytes)
+	}
+
+	fmt.Fprintf(w, "# HELP objstore_storage_growth_bytes_per_day Average daily change in stored bytes over the retained snapshots.\n")
+	fmt.Fprintf(w, "# TYPE objstore_storage_growth_bytes_per_day gauge\n")
+	for _, s := range stats {
+		fmt.Fprintf(w, "objstore_storage_growth_bytes_per_day{backend=%q} %g\n", s.Backend, s.GrowthBytesPerDay)
+	}
+
+	fmt.Fprintf(w, "# HELP objstore_storage_snapshot_timestamp_seconds Time of the last snapshot.\n")
+	fmt.Fprintf(w, "# TYPE objstore_storage_snapshot_timestamp_seconds gauge\n")
+	for _, s := range stats {
+		fmt.Fprintf(w, "objstore_storage_snapshot_timestamp_seconds{backend=%q} %d\n", s.Backend, s.Latest.Time.Unix())
+	}
+
+	fmt.Fprintf(w, "# HELP objstore_storage_prefix_bytes Bytes stored under the largest prefixes at the last snapshot.\n")
+	fmt.Fprintf(w, "# TYPE objstore_storage_prefix_bytes gauge\n")
+	for _, s := range stats {
+		for _, p := range s.Latest.TopPrefixes {
+			fmt.Fprintf(w, "objstore_storage_prefix_bytes{backend=%q,prefix=%q} %d\n", s.Backend, p.Prefix, p.Bytes)
+		}
+	}
+
+	fmt.Fprintf(w, "# HELP objstore_storage_prefix_objects Objects stored under the largest prefixes at the last snapshot.\n")
+	fmt.Fprintf(w, "# TYPE objstore_storage_prefix_objects gauge\n")
+	for _, s := range stats {
+		for _, p := range s.Latest.TopPrefixes {
+			fmt.Fprintf(w, "objstore_storage_prefix_objects{backend=%q,prefix=%q} %d\n", s.Backend, p.Prefix, p.Objects)
+		}
+	}
+}
+
+// saveLocked writes the history to its file, if any, replacing the
+// previous copy atomically.
+func (c *Collector) saveLocked() error {
+	if c.cfg.HistoryPath == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(c.series)
+	if err != nil {
+		return fmt.Errorf("failed to encode stats history: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(c.cfg.HistoryPath), ".stats-history-*")
+	if err != nil {
+		return fmt.Errorf("failed to write stats history: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write stats history: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write stats history: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.cfg.HistoryPath); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write stats history: %w", err)
+	}
+	return nil
+}
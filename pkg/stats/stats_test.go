@@ -0,0 +1,164 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package stats
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+func put(t *testing.T, s common.Storage, key string, size int) {
+	t.Helper()
+	if err := s.Put(key, strings.NewReader(strings.Repeat("x", size))); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCollect(t *testing.T) {
+	backend := memory.New()
+	put(t, backend, "logs/2025/a.log", 100)
+	put(t, backend, "logs/2025/b.log", 50)
+	put(t, backend, "images/cat.png", 10)
+	put(t, backend, "readme.txt", 1)
+
+	now := time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC)
+	c, err := NewCollector(&Config{TopPrefixes: 2, Retention: 48 * time.Hour}, func() map[string]common.Storage {
+		return map[string]common.Storage{"default": backend}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.now = func() time.Time { return now }
+	if err := c.Collect(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// A day later logs/ grew by 240 bytes in two objects.
+	now = now.Add(24 * time.Hour)
+	put(t, backend, "logs/2025/c.log", 200)
+	put(t, backend, "logs/2025/d.log", 40)
+	if err := c.Collect(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := c.Stats("", time.Time{})
+	if len(stats) != 1 || len(stats[0].Series) != 2 {
+		t.Fatalf("Stats() = %+v", stats)
+	}
+	s := stats[0]
+	if s.Latest.Objects != 6 || s.Latest.Bytes != 401 {
+		t.Errorf("Latest = %+v", s.Latest)
+	}
+	if s.GrowthBytesPerDay != 240 || s.GrowthObjectsPerDay != 2 {
+		t.Errorf("growth = %v bytes/day, %v objects/day", s.GrowthBytesPerDay, s.GrowthObjectsPerDay)
+	}
+	want := []PrefixUsage{{Prefix: "logs/", Objects: 4, Bytes: 390}, {Prefix: "images/", Objects: 1, Bytes: 10}}
+	if len(s.Latest.TopPrefixes) != 2 || s.Latest.TopPrefixes[0] != want[0] || s.Latest.TopPrefixes[1] != want[1] {
+		t.Errorf("TopPrefixes = %+v, want %+v", s.Latest.TopPrefixes, want)
+	}
+
+	if window := c.Stats("default", now); len(window) != 1 || len(window[0].Series) != 1 || window[0].GrowthBytesPerDay != 0 {
+		t.Errorf("Stats(since now) = %+v", window)
+	}
+	if other := c.Stats("archive", time.Time{}); len(other) != 0 {
+		t.Errorf("Stats(unknown backend) = %+v", other)
+	}
+
+	// Snapshots older than the retention period are dropped.
+	now = now.Add(48 * time.Hour)
+	if err := c.Collect(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if series := c.Stats("default", time.Time{})[0].Series; len(series) != 2 {
+		t.Errorf("retained %d snapshots, want 2", len(series))
+	}
+
+	var buf bytes.Buffer
+	c.WritePrometheus(&buf)
+	for _, line := range []string{
+		`objstore_storage_bytes{backend="default"} 401`,
+		`objstore_storage_objects{backend="default"} 6`,
+		`objstore_storage_prefix_bytes{backend="default",prefix="logs/"} 390`,
+	} {
+		if !strings.Contains(buf.String(), line) {
+			t.Errorf("metrics missing %q:\n%s", line, buf.String())
+		}
+	}
+}
+
+func TestPrefixDepth(t *testing.T) {
+	c, err := NewCollector(&Config{PrefixDepth: 2}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]string{
+		"logs/2025/a.log": "logs/2025/",
+		"logs/a.log":      "logs/",
+		"a.log":           "",
+	}
+	for key, want := range tests {
+		if got := c.prefix(key); got != want {
+			t.Errorf("prefix(%q) = %q, want %q", key, got, want)
+		}
+	}
+
+	if _, err := NewCollector(&Config{Interval: -time.Second}, nil); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("NewCollector() error = %v, want ErrInvalidConfig", err)
+	}
+}
+
+func TestHistoryPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats.json")
+	backend := memory.New()
+	put(t, backend, "a/b", 5)
+	backends := func() map[string]common.Storage { return map[string]common.Storage{"default": backend} }
+
+	c, err := NewCollector(&Config{HistoryPath: path}, backends)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Start()
+	deadline := time.Now().Add(5 * time.Second)
+	for len(c.Stats("", time.Time{})) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := NewCollector(&Config{HistoryPath: path}, backends)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := reopened.Stats("", time.Time{})
+	if len(stats) != 1 || stats[0].Latest.Bytes != 5 {
+		t.Errorf("reopened Stats() = %+v", stats)
+	}
+
+	if err := os.WriteFile(path, []byte("["), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewCollector(&Config{HistoryPath: path}, backends); !errors.Is(err, ErrHistoryCorrupt) {
+		t.Errorf("NewCollector() error = %v, want ErrHistoryCorrupt", err)
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package storagefs
+
+import (
+	"os"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+
+	"github.com/spf13/afero"
+)
+
+// AferoFs adapts a StorageFS to afero.Fs, so any afero-based tool can read
+// and write a storage backend. StorageFile already implements afero.File;
+// only the methods returning files need adapting.
+type AferoFs struct {
+	*StorageFS
+}
+
+// Compile-time check that AferoFs implements afero.Fs
+var _ afero.Fs = (*AferoFs)(nil)
+
+// NewAferoFs returns an afero.Fs backed by storage.
+func NewAferoFs(storage common.Storage) *AferoFs {
+	return New(storage).Afero()
+}
+
+// Afero returns an afero.Fs view of the file system.
+func (sfs *StorageFS) Afero() *AferoFs {
+	return &AferoFs{StorageFS: sfs}
+}
+
+// Create creates a file in the filesystem, returning the file and an error, if any.
+func (a *AferoFs) Create(name string) (afero.File, error) {
+	return a.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0666)
+}
+
+// Open opens a file, returning the file and an error, if any.
+func (a *AferoFs) Open(name string) (afero.File, error) {
+	return a.OpenFile(name, os.O_RDONLY, 0)
+}
+
+// OpenFile opens a file with the specified flag and perm.
+func (a *AferoFs) OpenFile(name string, flag int, perm os.FileMode) (afero.File, error) {
+	f, err := a.StorageFS.OpenFile(name, flag, perm)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
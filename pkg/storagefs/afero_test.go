@@ -0,0 +1,69 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package storagefs
+
+import (
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+
+	"github.com/spf13/afero"
+)
+
+func TestAferoFs(t *testing.T) {
+	var afs afero.Fs = NewAferoFs(memory.New())
+
+	if err := afs.MkdirAll("site/css", 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := afero.WriteFile(afs, "site/index.html", []byte("<h1>hi</h1>"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if err := afero.WriteFile(afs, "site/css/app.css", []byte("body {}"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	data, err := afero.ReadFile(afs, "site/index.html")
+	if err != nil || string(data) != "<h1>hi</h1>" {
+		t.Errorf("ReadFile = %q, %v", data, err)
+	}
+	if ok, _ := afero.Exists(afs, "site/css/app.css"); !ok {
+		t.Error("Exists(site/css/app.css) = false, want true")
+	}
+	if ok, _ := afero.IsDir(afs, "site/css"); !ok {
+		t.Error("IsDir(site/css) = false, want true")
+	}
+
+	infos, err := afero.ReadDir(afs, "site")
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	var names []string
+	for _, info := range infos {
+		names = append(names, info.Name())
+	}
+	if len(names) != 2 || names[0] != "css" || names[1] != "index.html" {
+		t.Errorf("ReadDir(site) = %v, want [css index.html]", names)
+	}
+
+	if err := afs.Rename("site/index.html", "site/home.html"); err != nil {
+		t.Fatalf("Rename: %v", err)
+	}
+	if ok, _ := afero.Exists(afs, "site/index.html"); ok {
+		t.Error("renamed file still exists")
+	}
+	if _, err := afs.Open("missing.txt"); err == nil {
+		t.Error("Open(missing) should fail")
+	}
+}
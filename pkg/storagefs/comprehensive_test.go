@@ -18,6 +18,7 @@ import (
 	"errors"
 	"io"
 	"os"
+	"runtime"
 	"testing"
 	"time"
 )
@@ -827,6 +828,16 @@ func TestNormalizePath_MoreEdgeCases(t *testing.T) {
 		{"//double//slash", "double/slash"},
 		{"./current/./dir", "current/dir"},
 		{"parent/../child", "child"},
+		{`dir\sub\file.txt`, "dir/sub/file.txt"},
+	}
+	if runtime.GOOS == "windows" {
+		tests = append(tests, []struct {
+			input    string
+			expected string
+		}{
+			{`C:\data\file.txt`, "data/file.txt"},
+			{`\\host\share\data\file.txt`, "data/file.txt"},
+		}...)
 	}
 
 	for _, tt := range tests {
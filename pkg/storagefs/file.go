@@ -15,6 +15,7 @@ package storagefs
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io"
 	"io/fs"
@@ -23,6 +24,8 @@ import (
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
 )
 
 // Error variables
@@ -33,10 +36,16 @@ var (
 
 // StorageFile implements fs.File interface for object storage.
 // It provides file-like operations with buffered writes and seek support.
+//
+// Existing objects on backends that support ranged reads are paged: reads
+// fetch only the pages they touch and writes keep only the modified pages,
+// so random access through ReadAt, WriteAt and Seek does not download the
+// whole object. Other files are buffered in memory in full.
 type StorageFile struct {
 	fs       *StorageFS
 	name     string
 	buf      *bytes.Buffer
+	paged    *pagedFile
 	offset   int64
 	flag     int
 	perm     os.FileMode
@@ -84,6 +93,18 @@ func newStorageFile(fs *StorageFS, name string, flag int, perm os.FileMode) (*St
 	append := flag&os.O_APPEND != 0
 	trunc := flag&os.O_TRUNC != 0
 
+	if !(trunc && writeMode) {
+		if paged := fs.openPaged(name); paged != nil {
+			f.paged = paged
+			info, err := fs.getMetadata(name)
+			if err != nil {
+				info = NewFileInfo(path.Base(name), paged.size, perm, time.Now(), false)
+			}
+			f.fileInfo = info
+			return f, nil
+		}
+	}
+
 	// For read mode, try to get existing file
 	if readMode {
 		data, err := fs.storage.Get(name)
@@ -145,9 +166,68 @@ func isNotFoundError(err error) bool {
 		return false
 	}
 	return errors.Is(err, os.ErrNotExist) ||
+		errors.Is(err, common.ErrKeyNotFound) ||
 		err.Error() == "key not found"
 }
 
+// openPaged returns a pagedFile for name when the backend supports ranged
+// reads and the object exists, or nil to fall back to full buffering.
+func (sfs *StorageFS) openPaged(name string) *pagedFile {
+	reader, ok := sfs.storage.(common.RangeReader)
+	if !ok {
+		return nil
+	}
+	ctx := context.Background()
+	if exists, err := sfs.storage.Exists(ctx, name); err != nil || !exists {
+		return nil
+	}
+	metadata, err := sfs.storage.GetMetadata(ctx, name)
+	if err != nil || metadata == nil {
+		return nil
+	}
+	return newPagedFile(ctx, sfs.storage, reader, name, metadata, defaultPageSize)
+}
+
+// size returns the current length of a regular file.
+func (f *StorageFile) size() int64 {
+	if f.paged != nil {
+		return f.paged.size
+	}
+	if f.buf != nil {
+		return int64(f.buf.Len())
+	}
+	return 0
+}
+
+// flushLocked writes the file to storage if it was opened for writing.
+// O_CREATE alone (without O_WRONLY or O_RDWR) does not imply a write intent.
+func (f *StorageFile) flushLocked() error {
+	if f.flag&(os.O_WRONLY|os.O_RDWR) == 0 || f.isDir {
+		return nil
+	}
+
+	switch {
+	case f.paged != nil:
+		if !f.paged.modified {
+			return nil
+		}
+		if err := f.paged.flush(); err != nil {
+			return err
+		}
+	case f.buf != nil:
+		if err := f.fs.putFile(f.name, f.buf.Bytes()); err != nil {
+			return err
+		}
+	default:
+		return nil
+	}
+
+	// Update metadata with new size and mod time
+	f.fileInfo.size = f.size()
+	f.fileInfo.modTime = time.Now()
+	return f.fs.putMetadata(f.name, f.fileInfo)
+}
+
 // Close closes the file, flushing writes if necessary.
 func (f *StorageFile) Close() error {
 	if f.closed.Load() {
@@ -159,24 +239,7 @@ func (f *StorageFile) Close() error {
 
 	f.closed.Store(true)
 
-	// If file was opened for writing, flush to storage.
-	// O_CREATE alone (without O_WRONLY or O_RDWR) does not imply a write intent,
-	// so only flush when an explicit write flag is set.
-	if f.flag&(os.O_WRONLY|os.O_RDWR) != 0 && f.buf != nil && !f.isDir {
-		data := f.buf.Bytes()
-		if err := f.fs.storage.Put(f.name, bytes.NewReader(data)); err != nil {
-			return err
-		}
-
-		// Update metadata with new size and mod time
-		f.fileInfo.size = int64(len(data))
-		f.fileInfo.modTime = time.Now()
-		if err := f.fs.putMetadata(f.name, f.fileInfo); err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return f.flushLocked()
 }
 
 // Read reads data from the file.
@@ -198,6 +261,15 @@ func (f *StorageFile) Read(p []byte) (n int, err error) {
 		return 0, os.ErrPermission
 	}
 
+	if f.paged != nil {
+		n, err = f.paged.ReadAt(p, f.offset)
+		f.offset += int64(n)
+		if n > 0 && err == io.EOF {
+			err = nil
+		}
+		return n, err
+	}
+
 	if f.buf == nil {
 		return 0, io.EOF
 	}
@@ -233,6 +305,10 @@ func (f *StorageFile) ReadAt(p []byte, off int64) (n int, err error) {
 		return 0, os.ErrPermission
 	}
 
+	if f.paged != nil {
+		return f.paged.ReadAt(p, off)
+	}
+
 	if f.buf == nil {
 		return 0, io.EOF
 	}
@@ -264,10 +340,7 @@ func (f *StorageFile) Seek(offset int64, whence int) (int64, error) {
 	}
 
 	var newOffset int64
-	bufLen := int64(0)
-	if f.buf != nil {
-		bufLen = int64(f.buf.Len())
-	}
+	bufLen := f.size()
 
 	switch whence {
 	case io.SeekStart:
@@ -305,13 +378,19 @@ func (f *StorageFile) Write(p []byte) (n int, err error) {
 		return 0, os.ErrPermission
 	}
 
-	if f.buf == nil {
-		f.buf = new(bytes.Buffer)
-	}
-
 	// Handle append mode
 	if f.flag&os.O_APPEND != 0 {
-		f.offset = int64(f.buf.Len())
+		f.offset = f.size()
+	}
+
+	if f.paged != nil {
+		n, err = f.paged.WriteAt(p, f.offset)
+		f.offset += int64(n)
+		return n, err
+	}
+
+	if f.buf == nil {
+		f.buf = new(bytes.Buffer)
 	}
 
 	// If writing at offset beyond current size, pad with zeros
@@ -358,6 +437,10 @@ func (f *StorageFile) WriteAt(p []byte, off int64) (n int, err error) {
 		return 0, os.ErrPermission
 	}
 
+	if f.paged != nil {
+		return f.paged.WriteAt(p, off)
+	}
+
 	if f.buf == nil {
 		f.buf = new(bytes.Buffer)
 	}
@@ -510,9 +593,9 @@ func (f *StorageFile) Stat() (os.FileInfo, error) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
-	// Update size if buffer exists
-	if f.buf != nil && !f.isDir {
-		f.fileInfo.size = int64(f.buf.Len())
+	// Update size if contents are loaded
+	if (f.buf != nil || f.paged != nil) && !f.isDir {
+		f.fileInfo.size = f.size()
 	}
 
 	return f.fileInfo, nil
@@ -527,23 +610,7 @@ func (f *StorageFile) Sync() error {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
-	// If file was opened for writing, flush to storage.
-	// O_CREATE alone (without O_WRONLY or O_RDWR) does not imply a write intent.
-	if f.flag&(os.O_WRONLY|os.O_RDWR) != 0 && f.buf != nil && !f.isDir {
-		data := f.buf.Bytes()
-		if err := f.fs.storage.Put(f.name, bytes.NewReader(data)); err != nil {
-			return err
-		}
-
-		// Update metadata
-		f.fileInfo.size = int64(len(data))
-		f.fileInfo.modTime = time.Now()
-		if err := f.fs.putMetadata(f.name, f.fileInfo); err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return f.flushLocked()
 }
 
 // Truncate changes the size of the file.
@@ -563,6 +630,15 @@ func (f *StorageFile) Truncate(size int64) error {
 		return os.ErrPermission
 	}
 
+	if f.paged != nil {
+		if size < 0 {
+			return ErrNegativeOffset
+		}
+		f.paged.Truncate(size)
+		f.fileInfo.size = size
+		return nil
+	}
+
 	if f.buf == nil {
 		f.buf = new(bytes.Buffer)
 	}
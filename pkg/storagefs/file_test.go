@@ -15,6 +15,7 @@ package storagefs
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io"
 	"os"
@@ -22,6 +23,8 @@ import (
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
 )
 
 // mockStorageForFile extends the base mockStorage for file tests with metadata tracking
@@ -761,3 +764,37 @@ func TestStorageFile_DoubleClose(t *testing.T) {
 		t.Errorf("expected os.ErrClosed or nil, got %v", err)
 	}
 }
+
+func TestStorageFile_ContentTypeDetection(t *testing.T) {
+	write := func(fs *StorageFS, name string, data []byte) string {
+		t.Helper()
+		f, err := fs.Create(name)
+		if err != nil {
+			t.Fatalf("Create(%s): %v", name, err)
+		}
+		if _, err := f.Write(data); err != nil {
+			t.Fatalf("Write(%s): %v", name, err)
+		}
+		if err := f.Close(); err != nil {
+			t.Fatalf("Close(%s): %v", name, err)
+		}
+		metadata, err := fs.storage.GetMetadata(context.Background(), name)
+		if err != nil {
+			t.Fatalf("GetMetadata(%s): %v", name, err)
+		}
+		return metadata.ContentType
+	}
+
+	fs := New(memory.New())
+	if got := write(fs, "site/index.html", []byte("plain text")); got != "text/html; charset=utf-8" {
+		t.Errorf("content type from extension = %q", got)
+	}
+	if got := write(fs, "images/logo", []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")); got != "image/png" {
+		t.Errorf("sniffed content type = %q", got)
+	}
+
+	fs = New(memory.New(), WithContentTypeDetection(false))
+	if got := write(fs, "site/index.html", []byte("<h1>hi</h1>")); got != "" {
+		t.Errorf("content type with detection disabled = %q, want none", got)
+	}
+}
@@ -0,0 +1,327 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package storagefs
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// IOFS exposes a StorageFS as a read-only io/fs file system, so a storage
+// backend can be used directly with html/template, http.FS, fs.WalkDir and
+// anything else that accepts an fs.FS.
+//
+// Directories are either explicit (created with Mkdir) or implied by the
+// keys stored beneath them. The .meta/ tree and directory markers used by
+// StorageFS are hidden.
+type IOFS struct {
+	sfs  *StorageFS
+	root string
+}
+
+// Compile-time checks that IOFS implements the io/fs interfaces
+var (
+	_ fs.FS         = (*IOFS)(nil)
+	_ fs.ReadDirFS  = (*IOFS)(nil)
+	_ fs.ReadFileFS = (*IOFS)(nil)
+	_ fs.StatFS     = (*IOFS)(nil)
+	_ fs.SubFS      = (*IOFS)(nil)
+)
+
+// NewIOFS returns an io/fs view of storage.
+func NewIOFS(storage common.Storage) *IOFS {
+	return New(storage).IOFS()
+}
+
+// IOFS returns an io/fs view of the file system.
+func (sfs *StorageFS) IOFS() *IOFS {
+	return &IOFS{sfs: sfs, root: "."}
+}
+
+// Open opens the named file or directory for reading.
+func (f *IOFS) Open(name string) (fs.File, error) {
+	info, err := f.stat("open", name)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return &ioDir{fsys: f, name: name, info: info}, nil
+	}
+
+	file, err := f.sfs.Open(f.key(name))
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	return &ioFile{File: file, info: info}, nil
+}
+
+// Stat returns a FileInfo describing the named file or directory.
+func (f *IOFS) Stat(name string) (fs.FileInfo, error) {
+	return f.stat("stat", name)
+}
+
+// ReadFile reads the named file and returns its contents.
+func (f *IOFS) ReadFile(name string) ([]byte, error) {
+	info, err := f.stat("readfile", name)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: ErrIsDirectory}
+	}
+
+	rc, err := f.sfs.storage.Get(f.key(name))
+	if err != nil {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: err}
+	}
+	defer func() { _ = rc.Close() }()
+	return io.ReadAll(rc)
+}
+
+// ReadDir reads the named directory and returns its entries sorted by name.
+func (f *IOFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	info, err := f.stat("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: ErrNotDirectory}
+	}
+	entries, err := f.readDir(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: err}
+	}
+	return entries, nil
+}
+
+// Sub returns an IOFS rooted at dir.
+func (f *IOFS) Sub(dir string) (fs.FS, error) {
+	if !fs.ValidPath(dir) {
+		return nil, &fs.PathError{Op: "sub", Path: dir, Err: fs.ErrInvalid}
+	}
+	if dir == "." {
+		return f, nil
+	}
+	return &IOFS{sfs: f.sfs, root: f.key(dir)}, nil
+}
+
+// key maps a name relative to f to a storage key.
+func (f *IOFS) key(name string) string {
+	return path.Join(f.root, name)
+}
+
+// stat resolves name to a directory or file. Directories take precedence,
+// since some backends report directories as existing keys.
+func (f *IOFS) stat(op, name string) (fs.FileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	key := f.key(name)
+	if hiddenKey(key) {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+
+	isDir, err := f.isDir(key)
+	if err != nil {
+		return nil, &fs.PathError{Op: op, Path: name, Err: err}
+	}
+	if isDir {
+		info := NewFileInfo(path.Base(name), 0, os.ModeDir|0755, time.Time{}, true)
+		if meta, err := f.sfs.getMetadataInternal(key); err == nil && meta.IsDir {
+			info.mode = os.ModeDir | meta.Mode.Perm()
+			info.modTime = meta.ModTime
+		}
+		return info, nil
+	}
+
+	ctx := context.Background()
+	exists, err := f.sfs.storage.Exists(ctx, key)
+	if err != nil {
+		return nil, &fs.PathError{Op: op, Path: name, Err: err}
+	}
+	if !exists {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+
+	info := NewFileInfo(path.Base(name), 0, 0644, time.Time{}, false)
+	if metadata, err := f.sfs.storage.GetMetadata(ctx, key); err == nil && metadata != nil {
+		info.size = metadata.Size
+		info.modTime = metadata.LastModified
+	}
+	if meta, err := f.sfs.getMetadataInternal(key); err == nil && !meta.IsDir {
+		info.mode = meta.Mode.Perm()
+		info.modTime = meta.ModTime
+	}
+	return info, nil
+}
+
+// isDir reports whether key is the root, an explicit directory or the
+// prefix of at least one stored key.
+func (f *IOFS) isDir(key string) (bool, error) {
+	if key == "." {
+		return true, nil
+	}
+	// Backends may fail to stat a marker below a file; that is not a
+	// directory either.
+	if exists, _ := f.sfs.dirExists(key); exists {
+		return true, nil
+	}
+	result, err := f.sfs.storage.ListWithOptions(context.Background(), &common.ListOptions{
+		Prefix:     key + "/",
+		Delimiter:  "/",
+		MaxResults: 1,
+	})
+	if err != nil {
+		return false, err
+	}
+	return len(result.Objects) > 0 || len(result.CommonPrefixes) > 0, nil
+}
+
+// readDir lists the direct children of the directory name, sorted by name.
+func (f *IOFS) readDir(name string) ([]fs.DirEntry, error) {
+	prefix := ""
+	if key := f.key(name); key != "." {
+		prefix = key + "/"
+	}
+
+	seen := make(map[string]bool)
+	var entries []fs.DirEntry
+	add := func(child string, isDir bool) {
+		if child == "" || seen[child] || hiddenKey(prefix+child) {
+			return
+		}
+		seen[child] = true
+		entries = append(entries, &ioDirEntry{fsys: f, name: path.Join(name, child), isDir: isDir})
+	}
+
+	opts := &common.ListOptions{Prefix: prefix, Delimiter: "/"}
+	for {
+		result, err := f.sfs.storage.ListWithOptions(context.Background(), opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range result.CommonPrefixes {
+			add(strings.TrimSuffix(strings.TrimPrefix(p, prefix), "/"), true)
+		}
+		for _, obj := range result.Objects {
+			add(strings.TrimPrefix(obj.Key, prefix), false)
+		}
+		if !result.Truncated || result.NextToken == "" {
+			break
+		}
+		opts.ContinueFrom = result.NextToken
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries, nil
+}
+
+// hiddenKey reports whether key belongs to StorageFS bookkeeping.
+func hiddenKey(key string) bool {
+	return key == strings.TrimSuffix(metadataPrefix, "/") ||
+		strings.HasPrefix(key, metadataPrefix) ||
+		path.Base(key) == dirMarker
+}
+
+// ioFile is an open file of an IOFS. It reports the same FileInfo as
+// IOFS.Stat.
+type ioFile struct {
+	File
+	info fs.FileInfo
+}
+
+func (f *ioFile) Stat() (fs.FileInfo, error) {
+	return f.info, nil
+}
+
+// ioDir is an open directory of an IOFS.
+type ioDir struct {
+	fsys    *IOFS
+	name    string
+	info    fs.FileInfo
+	entries []fs.DirEntry
+	loaded  bool
+	offset  int
+}
+
+func (d *ioDir) Stat() (fs.FileInfo, error) {
+	return d.info, nil
+}
+
+func (d *ioDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.name, Err: ErrIsDirectory}
+}
+
+func (d *ioDir) Close() error {
+	return nil
+}
+
+// ReadDir returns the next n entries of the directory, or all remaining
+// entries when n <= 0, following the fs.ReadDirFile contract.
+func (d *ioDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if !d.loaded {
+		entries, err := d.fsys.readDir(d.name)
+		if err != nil {
+			return nil, &fs.PathError{Op: "readdir", Path: d.name, Err: err}
+		}
+		d.entries, d.loaded = entries, true
+	}
+
+	remaining := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return remaining, nil
+	}
+	if len(remaining) == 0 {
+		return nil, io.EOF
+	}
+	n = min(n, len(remaining))
+	d.offset += n
+	return remaining[:n], nil
+}
+
+// ioDirEntry is a directory entry of an IOFS. Its FileInfo is resolved on
+// demand with IOFS.Stat.
+type ioDirEntry struct {
+	fsys  *IOFS
+	name  string
+	isDir bool
+}
+
+func (e *ioDirEntry) Name() string {
+	return path.Base(e.name)
+}
+
+func (e *ioDirEntry) IsDir() bool {
+	return e.isDir
+}
+
+func (e *ioDirEntry) Type() fs.FileMode {
+	if e.isDir {
+		return fs.ModeDir
+	}
+	return 0
+}
+
+func (e *ioDirEntry) Info() (fs.FileInfo, error) {
+	return e.fsys.Stat(e.name)
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package storagefs
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/local"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+// seedIOFS stores a tree mixing keys written directly to the backend with
+// files and directories created through StorageFS.
+func seedIOFS(t *testing.T, storage common.Storage) {
+	t.Helper()
+	for key, data := range map[string]string{
+		"hello.txt":          "hello, world\n",
+		"docs/guide.md":      "# Guide\n",
+		"docs/api/index.md":  "# API\n",
+		"assets/css/app.css": "body {}\n",
+	} {
+		if err := storage.Put(key, strings.NewReader(data)); err != nil {
+			t.Fatalf("Put(%s): %v", key, err)
+		}
+	}
+
+	sfs := New(storage)
+	if err := sfs.Mkdir("empty", 0750); err != nil {
+		t.Fatalf("Mkdir: %v", err)
+	}
+	f, err := sfs.Create("notes.txt")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := f.WriteString("remember\n"); err != nil {
+		t.Fatalf("WriteString: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+}
+
+func TestIOFS_FSTest(t *testing.T) {
+	backends := map[string]func(t *testing.T) common.Storage{
+		"memory": func(t *testing.T) common.Storage { return memory.New() },
+		"local": func(t *testing.T) common.Storage {
+			storage := local.New()
+			if err := storage.Configure(map[string]string{"path": t.TempDir()}); err != nil {
+				t.Fatalf("Configure: %v", err)
+			}
+			return storage
+		},
+	}
+
+	for name, newStorage := range backends {
+		t.Run(name, func(t *testing.T) {
+			storage := newStorage(t)
+			seedIOFS(t, storage)
+			fsys := NewIOFS(storage)
+
+			err := fstest.TestFS(fsys,
+				"hello.txt", "notes.txt", "empty",
+				"docs/guide.md", "docs/api/index.md", "assets/css/app.css")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sub, err := fs.Sub(fsys, "docs")
+			if err != nil {
+				t.Fatalf("Sub: %v", err)
+			}
+			if err := fstest.TestFS(sub, "guide.md", "api/index.md"); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestIOFS_Errors(t *testing.T) {
+	storage := memory.New()
+	seedIOFS(t, storage)
+	fsys := NewIOFS(storage)
+
+	if _, err := fsys.Open("missing.txt"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Open(missing) error = %v, want fs.ErrNotExist", err)
+	}
+	if _, err := fsys.Open("/hello.txt"); !errors.Is(err, fs.ErrInvalid) {
+		t.Errorf("Open(invalid path) error = %v, want fs.ErrInvalid", err)
+	}
+	if _, err := fsys.Stat(".meta/notes.txt"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Stat(.meta) error = %v, want fs.ErrNotExist", err)
+	}
+	if _, err := fsys.ReadDir("hello.txt"); !errors.Is(err, ErrNotDirectory) {
+		t.Errorf("ReadDir(file) error = %v, want ErrNotDirectory", err)
+	}
+	if _, err := fsys.ReadFile("docs"); !errors.Is(err, ErrIsDirectory) {
+		t.Errorf("ReadFile(dir) error = %v, want ErrIsDirectory", err)
+	}
+
+	info, err := fsys.Stat("notes.txt")
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if info.Size() != 9 || info.Mode() != 0666 {
+		t.Errorf("Stat(notes.txt) = size %d mode %v, want 9 and the mode it was created with", info.Size(), info.Mode())
+	}
+
+	f, _ := fsys.Open("hello.txt")
+	defer func() { _ = f.Close() }()
+	if w, ok := f.(interface{ Write([]byte) (int, error) }); ok {
+		if _, err := w.Write([]byte("x")); !errors.Is(err, os.ErrPermission) {
+			t.Errorf("Write through io/fs view error = %v, want os.ErrPermission", err)
+		}
+	}
+}
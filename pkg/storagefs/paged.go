@@ -0,0 +1,294 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package storagefs
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/delta"
+)
+
+const (
+	// defaultPageSize is the unit in which paged files read and modify
+	// objects.
+	defaultPageSize int64 = 1024 * 1024
+
+	// maxCleanPages bounds the number of unmodified pages kept in memory.
+	maxCleanPages = 16
+)
+
+// pagedFile holds the contents of an existing object opened through a
+// backend that supports ranged reads. Pages are fetched on first access and
+// only modified pages are kept until the next flush, so random access to a
+// large object never downloads the whole of it.
+//
+// On flush the new version is written with delta.Patcher when the backend
+// supports it, copying unmodified ranges server-side (S3 uses UploadPartCopy),
+// and otherwise streamed to Put page by page.
+type pagedFile struct {
+	ctx      context.Context
+	storage  common.Storage
+	reader   common.RangeReader
+	key      string
+	etag     string
+	pageSize int64
+
+	// size is the current length of the file. baseSize is the length of the
+	// stored version still visible through it; Truncate can shrink it.
+	size     int64
+	baseSize int64
+	modified bool
+
+	clean map[int64][]byte // unmodified pages, at most maxCleanPages
+	lru   []int64          // clean page indexes, least recently used first
+	dirty map[int64][]byte // modified pages, each pageSize long
+}
+
+// newPagedFile opens the stored version of key, whose size and ETag are
+// given by metadata.
+func newPagedFile(ctx context.Context, storage common.Storage, reader common.RangeReader, key string, metadata *common.Metadata, pageSize int64) *pagedFile {
+	return &pagedFile{
+		ctx:      ctx,
+		storage:  storage,
+		reader:   reader,
+		key:      key,
+		etag:     metadata.ETag,
+		pageSize: pageSize,
+		size:     metadata.Size,
+		baseSize: metadata.Size,
+		clean:    make(map[int64][]byte),
+		dirty:    make(map[int64][]byte),
+	}
+}
+
+// ReadAt implements io.ReaderAt over the current contents.
+func (p *pagedFile) ReadAt(b []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, ErrNegativeOffset
+	}
+	n := 0
+	for n < len(b) && off < p.size {
+		i, within := off/p.pageSize, off%p.pageSize
+		data, err := p.page(i)
+		if err != nil {
+			return n, err
+		}
+		// Bytes past the end of data but within the file are zeros.
+		end := min(p.pageSize, p.size-i*p.pageSize)
+		chunk := b[n:min(len(b), n+int(end-within))]
+		copied := 0
+		if within < int64(len(data)) {
+			copied = copy(chunk, data[within:])
+		}
+		clear(chunk[copied:])
+		n += len(chunk)
+		off += int64(len(chunk))
+	}
+	if n < len(b) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// WriteAt implements io.WriterAt, extending the file when needed.
+func (p *pagedFile) WriteAt(b []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, ErrNegativeOffset
+	}
+	n := 0
+	for n < len(b) {
+		i, within := off/p.pageSize, off%p.pageSize
+		take := min(int64(len(b)-n), p.pageSize-within)
+		page, err := p.dirtyPage(i, within == 0 && take == p.pageSize)
+		if err != nil {
+			return n, err
+		}
+		copy(page[within:], b[n:n+int(take)])
+		n += int(take)
+		off += take
+	}
+	p.size = max(p.size, off)
+	p.modified = true
+	return n, nil
+}
+
+// Truncate changes the size of the file. Growing it adds zeros.
+func (p *pagedFile) Truncate(size int64) {
+	if size < p.size {
+		for i, page := range p.dirty {
+			start := i * p.pageSize
+			switch {
+			case start >= size:
+				delete(p.dirty, i)
+			case start+p.pageSize > size:
+				clear(page[size-start:])
+			}
+		}
+		for i, page := range p.clean {
+			if start := i * p.pageSize; start+int64(len(page)) > size {
+				p.dropClean(i)
+			}
+		}
+		p.baseSize = min(p.baseSize, size)
+	}
+	p.size = size
+	p.modified = true
+}
+
+// flush writes the current contents to the backend if they were modified.
+func (p *pagedFile) flush() error {
+	if !p.modified {
+		return nil
+	}
+
+	written := false
+	if patcher, ok := p.storage.(delta.Patcher); ok && p.baseSize > 0 {
+		err := patcher.ApplyDelta(p.ctx, p.key, p.etag, p.ops(), p.source, nil)
+		if err != nil && !errors.Is(err, delta.ErrNotSupported) {
+			return err
+		}
+		written = err == nil
+	}
+	if !written {
+		if err := p.storage.PutWithContext(p.ctx, p.key, io.NewSectionReader(p, 0, p.size)); err != nil {
+			return err
+		}
+	}
+
+	// The written version becomes the new base.
+	p.etag = ""
+	if metadata, err := p.storage.GetMetadata(p.ctx, p.key); err == nil && metadata != nil {
+		p.etag = metadata.ETag
+	}
+	p.baseSize = p.size
+	p.modified = false
+	p.dirty = make(map[int64][]byte)
+	p.clean = make(map[int64][]byte)
+	p.lru = nil
+	return nil
+}
+
+// ops describes the current contents as ranges copied from the stored
+// version and literal ranges read through source.
+func (p *pagedFile) ops() []delta.Op {
+	var ops []delta.Op
+	add := func(kind delta.OpKind, off, length int64) {
+		if last := len(ops) - 1; last >= 0 && ops[last].Kind == kind && ops[last].Offset+ops[last].Length == off {
+			ops[last].Length += length
+			return
+		}
+		ops = append(ops, delta.Op{Kind: kind, Offset: off, Length: length})
+	}
+	for start := int64(0); start < p.size; start += p.pageSize {
+		end := min(start+p.pageSize, p.size)
+		if _, dirty := p.dirty[start/p.pageSize]; dirty || start >= p.baseSize {
+			add(delta.OpLiteral, start, end-start)
+			continue
+		}
+		copyEnd := min(end, p.baseSize)
+		add(delta.OpCopy, start, copyEnd-start)
+		if copyEnd < end {
+			add(delta.OpLiteral, copyEnd, end-copyEnd)
+		}
+	}
+	return ops
+}
+
+// source serves the literal ranges of ops.
+func (p *pagedFile) source(_ context.Context, offset, length int64) (io.ReadCloser, error) {
+	return io.NopCloser(io.NewSectionReader(p, offset, length)), nil
+}
+
+// page returns the contents of page i, which may be shorter than the page
+// when the rest of it is zeros.
+func (p *pagedFile) page(i int64) ([]byte, error) {
+	if page, ok := p.dirty[i]; ok {
+		return page, nil
+	}
+	if page, ok := p.clean[i]; ok {
+		p.touchClean(i)
+		return page, nil
+	}
+
+	start := i * p.pageSize
+	if start >= p.baseSize {
+		return nil, nil
+	}
+	length := min(p.pageSize, p.baseSize-start)
+	rc, err := p.reader.GetRange(p.ctx, p.key, start, length)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rc.Close() }()
+	page := make([]byte, length)
+	if _, err := io.ReadFull(rc, page); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+
+	if len(p.lru) >= maxCleanPages {
+		p.dropClean(p.lru[0])
+	}
+	p.clean[i] = page
+	p.lru = append(p.lru, i)
+	return page, nil
+}
+
+// dirtyPage returns page i for modification. Its current contents are
+// loaded first unless the caller is about to overwrite all of it.
+func (p *pagedFile) dirtyPage(i int64, overwrite bool) ([]byte, error) {
+	if page, ok := p.dirty[i]; ok {
+		return page, nil
+	}
+	page := make([]byte, p.pageSize)
+	if !overwrite && i*p.pageSize < p.size {
+		data, err := p.page(i)
+		if err != nil {
+			return nil, err
+		}
+		copy(page, data)
+	}
+	p.dropClean(i)
+	p.dirty[i] = page
+	return page, nil
+}
+
+// touchClean marks clean page i as most recently used.
+func (p *pagedFile) touchClean(i int64) {
+	for j, idx := range p.lru {
+		if idx == i {
+			p.lru = append(append(p.lru[:j:j], p.lru[j+1:]...), i)
+			return
+		}
+	}
+}
+
+// dropClean evicts clean page i.
+func (p *pagedFile) dropClean(i int64) {
+	if _, ok := p.clean[i]; !ok {
+		return
+	}
+	delete(p.clean, i)
+	for j, idx := range p.lru {
+		if idx == i {
+			p.lru = append(p.lru[:j], p.lru[j+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,259 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package storagefs
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/delta"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+// rangeStorage counts the bytes requested through GetRange and hides any
+// delta.Patcher of the wrapped backend.
+type rangeStorage struct {
+	common.Storage
+	ranged int64
+	puts   int
+}
+
+func (r *rangeStorage) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	r.ranged += length
+	return r.Storage.(common.RangeReader).GetRange(ctx, key, offset, length)
+}
+
+func (r *rangeStorage) PutWithContext(ctx context.Context, key string, data io.Reader) error {
+	r.puts++
+	return r.Storage.PutWithContext(ctx, key, data)
+}
+
+// patchStorage is a rangeStorage that also applies deltas.
+type patchStorage struct {
+	*rangeStorage
+	patched []delta.Op
+}
+
+func (p *patchStorage) ApplyDelta(ctx context.Context, key, baseETag string, ops []delta.Op, src delta.Source, metadata *common.Metadata) error {
+	p.patched = ops
+	return p.Storage.(delta.Patcher).ApplyDelta(ctx, key, baseETag, ops, src, metadata)
+}
+
+// pagedObject returns 3.5 pages of patterned data.
+func pagedObject() []byte {
+	data := make([]byte, 3*defaultPageSize+defaultPageSize/2)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	return data
+}
+
+func TestPagedFile_ReadAtFetchesOnlyTouchedPages(t *testing.T) {
+	storage := &rangeStorage{Storage: memory.New()}
+	data := pagedObject()
+	if err := storage.Put("big.bin", bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := New(storage).Open("big.bin")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	buf := make([]byte, 16)
+	off := 2*defaultPageSize + 100
+	if _, err := f.ReadAt(buf, off); err != nil {
+		t.Fatalf("ReadAt() error = %v", err)
+	}
+	if !bytes.Equal(buf, data[off:off+16]) {
+		t.Error("ReadAt() returned wrong bytes")
+	}
+	if storage.ranged != defaultPageSize {
+		t.Errorf("ReadAt() fetched %d bytes, want one page", storage.ranged)
+	}
+
+	// Reading across the end returns the tail and io.EOF.
+	tail := make([]byte, 32)
+	n, err := f.ReadAt(tail, int64(len(data))-10)
+	if n != 10 || err != io.EOF || !bytes.Equal(tail[:n], data[len(data)-10:]) {
+		t.Errorf("ReadAt() at end = %d, %v", n, err)
+	}
+
+	if pos, err := f.Seek(-4, io.SeekEnd); err != nil || pos != int64(len(data))-4 {
+		t.Fatalf("Seek() = %d, %v", pos, err)
+	}
+	rest, err := io.ReadAll(f)
+	if err != nil || !bytes.Equal(rest, data[len(data)-4:]) {
+		t.Errorf("Read() after Seek() = %v, %v", rest, err)
+	}
+}
+
+func TestPagedFile_ZipReader(t *testing.T) {
+	var archive bytes.Buffer
+	zw := zip.NewWriter(&archive)
+	w, _ := zw.CreateHeader(&zip.FileHeader{Name: "padding.bin", Method: zip.Store})
+	_, _ = w.Write(pagedObject())
+	w, _ = zw.Create("hello.txt")
+	_, _ = w.Write([]byte("hello from the end of the archive"))
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	storage := &rangeStorage{Storage: memory.New()}
+	if err := storage.Put("archive.zip", bytes.NewReader(archive.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+	f, err := New(storage).Open("archive.zip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+
+	zr, err := zip.NewReader(f.(io.ReaderAt), int64(archive.Len()))
+	if err != nil {
+		t.Fatalf("zip.NewReader() error = %v", err)
+	}
+	rc, err := zr.Open("hello.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := io.ReadAll(rc)
+	if string(got) != "hello from the end of the archive" {
+		t.Errorf("zip entry = %q", got)
+	}
+	if storage.ranged >= int64(archive.Len()) {
+		t.Errorf("zip reader fetched %d of %d bytes", storage.ranged, archive.Len())
+	}
+}
+
+func TestPagedFile_WriteAtPatchesModifiedPages(t *testing.T) {
+	storage := &patchStorage{rangeStorage: &rangeStorage{Storage: memory.New()}}
+	data := pagedObject()
+	if err := storage.Put("db.bin", bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := New(storage).OpenFile("db.bin", os.O_RDWR, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	patch := []byte("PATCHED")
+	off := defaultPageSize + 10
+	if _, err := f.WriteAt(patch, off); err != nil {
+		t.Fatalf("WriteAt() error = %v", err)
+	}
+	if _, err := f.Seek(0, io.SeekEnd); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte("!")); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	want := append(append([]byte{}, data...), '!')
+	copy(want[off:], patch)
+	rc, err := storage.Get("db.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := io.ReadAll(rc)
+	_ = rc.Close()
+	if !bytes.Equal(got, want) {
+		t.Error("stored object does not match writes")
+	}
+
+	wantOps := []delta.Op{
+		{Kind: delta.OpCopy, Offset: 0, Length: defaultPageSize},
+		{Kind: delta.OpLiteral, Offset: defaultPageSize, Length: defaultPageSize},
+		{Kind: delta.OpCopy, Offset: 2 * defaultPageSize, Length: defaultPageSize},
+		{Kind: delta.OpLiteral, Offset: 3 * defaultPageSize, Length: int64(len(want)) - 3*defaultPageSize},
+	}
+	if len(storage.patched) != len(wantOps) {
+		t.Fatalf("ApplyDelta() ops = %+v, want %+v", storage.patched, wantOps)
+	}
+	for i := range wantOps {
+		if storage.patched[i] != wantOps[i] {
+			t.Errorf("op %d = %+v, want %+v", i, storage.patched[i], wantOps[i])
+		}
+	}
+	if storage.puts != 0 {
+		t.Errorf("Close() used Put %d times, want ApplyDelta only", storage.puts)
+	}
+}
+
+func TestPagedFile_TruncateWithoutPatcher(t *testing.T) {
+	storage := &rangeStorage{Storage: memory.New()}
+	data := pagedObject()
+	if err := storage.Put("log.bin", bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := New(storage).OpenFile("log.bin", os.O_RDWR, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Truncate(10); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Truncate(20); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteAt([]byte("x"), 19); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	want := append(append([]byte{}, data[:10]...), make([]byte, 10)...)
+	want[19] = 'x'
+	rc, err := storage.Get("log.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := io.ReadAll(rc)
+	_ = rc.Close()
+	if !bytes.Equal(got, want) {
+		t.Errorf("stored object = %v, want %v", got, want)
+	}
+	if storage.puts != 1 {
+		t.Errorf("Close() used Put %d times, want 1", storage.puts)
+	}
+}
+
+func TestPagedFile_UnmodifiedCloseDoesNotWrite(t *testing.T) {
+	storage := &rangeStorage{Storage: memory.New()}
+	if err := storage.Put("config.yaml", bytes.NewReader([]byte("a: 1\n"))); err != nil {
+		t.Fatal(err)
+	}
+	storage.puts = 0
+	f, err := New(storage).OpenFile("config.yaml", os.O_RDWR, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if storage.puts != 0 {
+		t.Errorf("Close() of an unmodified file wrote it %d times", storage.puts)
+	}
+}
@@ -22,10 +22,12 @@ import (
 	"io/fs"
 	"os"
 	"path"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/contentpolicy"
 )
 
 const (
@@ -55,14 +57,32 @@ type fileMetadata struct {
 // StorageFS wraps a common.Storage interface to provide filesystem semantics.
 // It implements the Fs interface for file operations over object storage.
 type StorageFS struct {
-	storage common.Storage
+	storage     common.Storage
+	detectTypes bool
+}
+
+// Option configures a StorageFS.
+type Option func(*StorageFS)
+
+// WithContentTypeDetection enables or disables setting the content type of
+// written files from their extension, or else by sniffing their first 512
+// bytes. It is enabled by default.
+func WithContentTypeDetection(enable bool) Option {
+	return func(fs *StorageFS) {
+		fs.detectTypes = enable
+	}
 }
 
 // New creates a new StorageFS instance wrapping the given storage backend.
-func New(storage common.Storage) *StorageFS {
-	return &StorageFS{
-		storage: storage,
+func New(storage common.Storage, opts ...Option) *StorageFS {
+	fs := &StorageFS{
+		storage:     storage,
+		detectTypes: true,
+	}
+	for _, opt := range opts {
+		opt(fs)
 	}
+	return fs
 }
 
 // Name returns the name of the filesystem.
@@ -362,6 +382,11 @@ func normalizePath(p string) string {
 		return "."
 	}
 
+	// Drop the volume of Windows paths such as C:\data\x or
+	// \\host\share\x (always empty elsewhere), so names built with
+	// filepath map to keys
+	p = strings.TrimPrefix(p, filepath.VolumeName(p))
+
 	// Replace backslashes with forward slashes
 	p = strings.ReplaceAll(p, "\\", "/")
 
@@ -403,6 +428,21 @@ func splitPath(p string) []string {
 	return result
 }
 
+// putFile stores data as the content of file name, with its content type
+// detected unless detection is disabled.
+func (fs *StorageFS) putFile(name string, data []byte) error {
+	if !fs.detectTypes {
+		return fs.storage.Put(name, bytes.NewReader(data))
+	}
+	contentType := contentpolicy.TypeByExtension(name)
+	if contentType == "" {
+		contentType = contentpolicy.Detect(data)
+	}
+	return fs.storage.PutWithMetadata(context.Background(), name, bytes.NewReader(data), &common.Metadata{
+		ContentType: contentType,
+	})
+}
+
 // putMetadata stores file metadata (using FileInfo type)
 func (fs *StorageFS) putMetadata(name string, info *FileInfo) error {
 	meta := fileMetadata{
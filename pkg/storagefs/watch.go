@@ -0,0 +1,195 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package storagefs
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// Watch event operations.
+const (
+	EventCreate = "create"
+	EventModify = "modify"
+	EventDelete = "delete"
+)
+
+const (
+	// DefaultWatchInterval is how often a polling watch lists the backend.
+	DefaultWatchInterval = 2 * time.Second
+
+	// defaultWatchBuffer is the capacity of the event channel.
+	defaultWatchBuffer = 100
+)
+
+// Event describes one change to an object under a watched prefix.
+type Event struct {
+	Key  string    `json:"key"`
+	Op   string    `json:"op"` // "create", "modify" or "delete"
+	ETag string    `json:"etag,omitempty"`
+	Size int64     `json:"size,omitempty"`
+	Time time.Time `json:"time"`
+}
+
+// ChangeNotifier is implemented by backends that can push change events, for
+// example from a server-side event bus. Watch subscribes through it instead
+// of polling. The returned channel must be closed when ctx is done.
+type ChangeNotifier interface {
+	WatchChanges(ctx context.Context, prefix string) (<-chan Event, error)
+}
+
+// WatchOptions configures Watch.
+type WatchOptions struct {
+	// Interval is the polling period. Defaults to DefaultWatchInterval.
+	Interval time.Duration
+
+	// Buffer is the capacity of the event channel. Defaults to 100.
+	Buffer int
+}
+
+// Watch reports objects created, modified or deleted under prefix until ctx
+// is done, then closes the returned channel. prefix is matched against keys
+// as-is, so "conf" also matches "config.yaml"; use "conf/" to watch a
+// directory. StorageFS bookkeeping keys are never reported.
+//
+// Backends implementing ChangeNotifier push their own events. All others are
+// polled: the prefix is listed every Interval and each object's ETag (or its
+// size and modification time, when the backend has no ETag) is compared with
+// the previous listing. Objects present when Watch is called produce no
+// events, and changes that are undone within one interval are not seen.
+func (sfs *StorageFS) Watch(ctx context.Context, prefix string, opts *WatchOptions) (<-chan Event, error) {
+	prefix = strings.TrimPrefix(prefix, "/")
+	if notifier, ok := sfs.storage.(ChangeNotifier); ok {
+		return notifier.WatchChanges(ctx, prefix)
+	}
+
+	if opts == nil {
+		opts = &WatchOptions{}
+	}
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	buffer := opts.Buffer
+	if buffer <= 0 {
+		buffer = defaultWatchBuffer
+	}
+
+	snapshot, err := sfs.snapshot(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan Event, buffer)
+	go sfs.poll(ctx, prefix, interval, snapshot, events)
+	return events, nil
+}
+
+// objectVersion is what a polling watch remembers about one object.
+type objectVersion struct {
+	etag     string
+	size     int64
+	modified time.Time
+}
+
+// changed reports whether v and other describe different contents.
+func (v objectVersion) changed(other objectVersion) bool {
+	if v.etag != "" || other.etag != "" {
+		return v.etag != other.etag
+	}
+	return v.size != other.size || !v.modified.Equal(other.modified)
+}
+
+// poll lists prefix every interval and sends the differences to events.
+func (sfs *StorageFS) poll(ctx context.Context, prefix string, interval time.Duration, previous map[string]objectVersion, events chan<- Event) {
+	defer close(events)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		current, err := sfs.snapshot(ctx, prefix)
+		if err != nil {
+			// Transient listing failures are retried on the next tick.
+			continue
+		}
+		for _, event := range diffSnapshots(previous, current) {
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+		previous = current
+	}
+}
+
+// snapshot lists every visible object under prefix.
+func (sfs *StorageFS) snapshot(ctx context.Context, prefix string) (map[string]objectVersion, error) {
+	versions := make(map[string]objectVersion)
+	opts := &common.ListOptions{Prefix: prefix, MaxResults: 1000}
+	for {
+		result, err := sfs.storage.ListWithOptions(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range result.Objects {
+			if hiddenKey(obj.Key) {
+				continue
+			}
+			var v objectVersion
+			if obj.Metadata != nil {
+				v = objectVersion{etag: obj.Metadata.ETag, size: obj.Metadata.Size, modified: obj.Metadata.LastModified}
+			}
+			versions[obj.Key] = v
+		}
+		if !result.Truncated || result.NextToken == "" {
+			return versions, nil
+		}
+		opts.ContinueFrom = result.NextToken
+	}
+}
+
+// diffSnapshots returns the events turning previous into current, sorted by
+// key.
+func diffSnapshots(previous, current map[string]objectVersion) []Event {
+	now := time.Now()
+	var events []Event
+	for key, v := range current {
+		old, existed := previous[key]
+		switch {
+		case !existed:
+			events = append(events, Event{Key: key, Op: EventCreate, ETag: v.etag, Size: v.size, Time: now})
+		case old.changed(v):
+			events = append(events, Event{Key: key, Op: EventModify, ETag: v.etag, Size: v.size, Time: now})
+		}
+	}
+	for key := range previous {
+		if _, exists := current[key]; !exists {
+			events = append(events, Event{Key: key, Op: EventDelete, Time: now})
+		}
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].Key < events[j].Key })
+	return events
+}
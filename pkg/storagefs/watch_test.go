@@ -0,0 +1,128 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package storagefs
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+// nextEvent waits for one event or fails the test.
+func nextEvent(t *testing.T, events <-chan Event) Event {
+	t.Helper()
+	select {
+	case event, ok := <-events:
+		if !ok {
+			t.Fatal("event channel closed")
+		}
+		return event
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for event")
+	}
+	return Event{}
+}
+
+func TestWatch_Polling(t *testing.T) {
+	storage := memory.New()
+	sfs := New(storage)
+	if err := storage.Put("conf/existing.yaml", strings.NewReader("a")); err != nil {
+		t.Fatal(err)
+	}
+	if err := sfs.Mkdir("conf/sub", 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := sfs.Watch(ctx, "/conf/", &WatchOptions{Interval: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+
+	steps := []struct {
+		name   string
+		change func() error
+		want   Event
+	}{
+		{"create", func() error { return storage.Put("conf/app.yaml", strings.NewReader("v1")) }, Event{Key: "conf/app.yaml", Op: EventCreate}},
+		{"modify", func() error { return storage.Put("conf/app.yaml", strings.NewReader("v2 longer")) }, Event{Key: "conf/app.yaml", Op: EventModify}},
+		{"delete", func() error { return storage.Delete("conf/existing.yaml") }, Event{Key: "conf/existing.yaml", Op: EventDelete}},
+	}
+	for _, step := range steps {
+		// Writes outside the prefix must not be reported.
+		if err := storage.Put("other/"+step.name, strings.NewReader("x")); err != nil {
+			t.Fatal(err)
+		}
+		if err := step.change(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		got := nextEvent(t, events)
+		if got.Key != step.want.Key || got.Op != step.want.Op {
+			t.Errorf("%s: event = %s %s, want %s %s", step.name, got.Op, got.Key, step.want.Op, step.want.Key)
+		}
+		if step.want.Op != EventDelete && got.ETag == "" {
+			t.Errorf("%s: event has no ETag", step.name)
+		}
+	}
+
+	cancel()
+	for range events {
+	}
+}
+
+// pushStorage is a backend that pushes its own change events.
+type pushStorage struct {
+	common.Storage
+	prefix string
+}
+
+func (p *pushStorage) WatchChanges(ctx context.Context, prefix string) (<-chan Event, error) {
+	p.prefix = prefix
+	events := make(chan Event, 1)
+	events <- Event{Key: prefix + "pushed", Op: EventCreate}
+	close(events)
+	return events, nil
+}
+
+func TestWatch_ChangeNotifier(t *testing.T) {
+	storage := &pushStorage{Storage: memory.New()}
+	events, err := New(storage).Watch(context.Background(), "logs/", nil)
+	if err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	if got := nextEvent(t, events); got.Key != "logs/pushed" || storage.prefix != "logs/" {
+		t.Errorf("Watch() did not use ChangeNotifier: event %+v, prefix %q", got, storage.prefix)
+	}
+}
+
+func TestDiffSnapshots_WithoutETag(t *testing.T) {
+	then := time.Unix(100, 0)
+	previous := map[string]objectVersion{
+		"same":    {size: 1, modified: then},
+		"touched": {size: 1, modified: then},
+	}
+	current := map[string]objectVersion{
+		"same":    {size: 1, modified: then},
+		"touched": {size: 1, modified: then.Add(time.Second)},
+	}
+	events := diffSnapshots(previous, current)
+	if len(events) != 1 || events[0].Key != "touched" || events[0].Op != EventModify {
+		t.Errorf("diffSnapshots() = %+v, want one modify of touched", events)
+	}
+}
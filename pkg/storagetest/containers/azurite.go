@@ -0,0 +1,74 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build azureblob
+
+package containers
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/Azure/azure-storage-blob-go/azblob"
+	"github.com/jeremyhahn/go-objstore/pkg/azure"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// AzuriteImage is the image Azurite starts.
+var AzuriteImage = "mcr.microsoft.com/azure-storage/azurite:3.33.0"
+
+// Azurite's well-known development account.
+const (
+	azuriteAccount = "devstoreaccount1"
+	azuriteKey     = "Eby8vdM02xNOcqFlqUwJPLlmEtlCDXJ1OUzFT50uSRZ6IFsuFq2UVErCz4I6tq/K1SZFPTOtr/KBHBeksoGMGw=="
+)
+
+// Azurite starts the Azurite blob service for the "azure" backend. The
+// bucket passed to Settings and CreateBucket is the container name.
+func Azurite(t *testing.T) *Emulator {
+	t.Helper()
+	endpoint := start(t, AzuriteImage, "10000/tcp",
+		testcontainers.WithCmd("azurite-blob", "--blobHost", "0.0.0.0", "--blobPort", "10000",
+			"--inMemoryPersistence", "--skipApiVersionCheck"),
+		testcontainers.WithWaitStrategy(wait.ForLog("Azurite Blob service is successfully listening")),
+	)
+	accountURL := endpoint + "/" + azuriteAccount
+
+	return &Emulator{
+		Endpoint:   accountURL,
+		newStorage: azure.New,
+		settings: func(bucket string) map[string]string {
+			return map[string]string{
+				"accountName":   azuriteAccount,
+				"accountKey":    azuriteKey,
+				"containerName": bucket,
+				"endpoint":      accountURL,
+			}
+		},
+		createBucket: func(ctx context.Context, bucket string) error {
+			cred, err := azblob.NewSharedKeyCredential(azuriteAccount, azuriteKey)
+			if err != nil {
+				return err
+			}
+			u, err := url.Parse(accountURL + "/" + bucket)
+			if err != nil {
+				return err
+			}
+			container := azblob.NewContainerURL(*u, azblob.NewPipeline(cred, azblob.PipelineOptions{}))
+			_, err = container.Create(ctx, azblob.Metadata{}, azblob.PublicAccessNone)
+			return err
+		},
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package containers starts storage emulators in Docker containers, via
+// testcontainers, so backends can be tested against a real service without
+// a shared environment. Each emulator serves one built-in backend: MinIO
+// for "minio", Azurite for "azure" and fake-gcs-server for "gcs". The
+// emulator for a backend is compiled with that backend's build tag.
+//
+// An emulator lives as long as the test that started it, and NewStorage
+// returns a backend on a new, empty bucket, so an emulator plugs straight
+// into the conformance suite:
+//
+//	func TestMinIO(t *testing.T) {
+//	    storagetest.TestStorage(t, containers.MinIO(t).NewStorage)
+//	}
+//
+// Tests are skipped, not failed, when Docker is not available.
+package containers
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// Emulator is a running storage emulator.
+type Emulator struct {
+	// Endpoint is the base URL of the emulator on the host.
+	Endpoint string
+
+	newStorage   func() common.Storage
+	settings     func(bucket string) map[string]string
+	createBucket func(ctx context.Context, bucket string) error
+	buckets      atomic.Int64
+}
+
+// Settings returns the backend settings for bucket, the container name on
+// Azurite.
+func (e *Emulator) Settings(bucket string) map[string]string {
+	return e.settings(bucket)
+}
+
+// CreateBucket creates bucket on the emulator.
+func (e *Emulator) CreateBucket(ctx context.Context, bucket string) error {
+	return e.createBucket(ctx, bucket)
+}
+
+// NewStorage creates a new bucket and returns a backend configured for it.
+// It has the signature of storagetest.Factory.
+func (e *Emulator) NewStorage(t *testing.T) common.Storage {
+	t.Helper()
+	bucket := fmt.Sprintf("objstore-test-%d", e.buckets.Add(1))
+	if err := e.CreateBucket(context.Background(), bucket); err != nil {
+		t.Fatalf("create bucket %s: %v", bucket, err)
+	}
+	s := e.newStorage()
+	if err := s.Configure(e.Settings(bucket)); err != nil {
+		t.Fatalf("configure storage: %v", err)
+	}
+	return s
+}
+
+// start runs image with port exposed until the test ends and returns the
+// HTTP endpoint of the port on the host. The test is skipped when Docker is
+// not available.
+func start(t *testing.T, image, port string, opts ...testcontainers.ContainerCustomizer) string {
+	t.Helper()
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+
+	ctx := context.Background()
+	opts = append([]testcontainers.ContainerCustomizer{testcontainers.WithExposedPorts(port)}, opts...)
+	ctr, err := testcontainers.Run(ctx, image, opts...)
+	testcontainers.CleanupContainer(t, ctr)
+	if err != nil {
+		t.Fatalf("start %s: %v", image, err)
+	}
+	endpoint, err := ctr.PortEndpoint(ctx, port, "http")
+	if err != nil {
+		t.Fatalf("endpoint of %s: %v", image, err)
+	}
+	return endpoint
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build gcpstorage
+
+package containers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"cloud.google.com/go/storage"
+	"github.com/jeremyhahn/go-objstore/pkg/gcs"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// FakeGCSImage is the image FakeGCS starts.
+var FakeGCSImage = "fsouza/fake-gcs-server:1.49.1"
+
+const fakeGCSProject = "objstore-test"
+
+// FakeGCS starts fake-gcs-server for the "gcs" backend. The GCS client
+// finds emulators through the STORAGE_EMULATOR_HOST environment variable,
+// which FakeGCS sets for the rest of the test with t.Setenv, so the test
+// must not be parallel.
+func FakeGCS(t *testing.T) *Emulator {
+	t.Helper()
+	endpoint := start(t, FakeGCSImage, "4443/tcp",
+		testcontainers.WithCmd("-scheme", "http", "-port", "4443", "-backend", "memory"),
+		testcontainers.WithWaitStrategy(wait.ForHTTP("/storage/v1/b").WithPort("4443/tcp")),
+	)
+	// Resumable uploads continue at the server's external URL, which must
+	// be the port mapped on the host rather than the one in the container.
+	if err := setExternalURL(endpoint); err != nil {
+		t.Fatalf("configure fake-gcs-server: %v", err)
+	}
+	t.Setenv("STORAGE_EMULATOR_HOST", endpoint)
+
+	client, err := storage.NewClient(context.Background())
+	if err != nil {
+		t.Fatalf("gcs client: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+
+	return &Emulator{
+		Endpoint:   endpoint,
+		newStorage: gcs.New,
+		settings: func(bucket string) map[string]string {
+			return map[string]string{"bucket": bucket}
+		},
+		createBucket: func(ctx context.Context, bucket string) error {
+			return client.Bucket(bucket).Create(ctx, fakeGCSProject, nil)
+		},
+	}
+}
+
+// setExternalURL sets the URL fake-gcs-server hands out to clients.
+func setExternalURL(endpoint string) error {
+	body, err := json.Marshal(map[string]string{"externalUrl": endpoint})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPut, endpoint+"/_internal/config", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("PUT /_internal/config: %s", resp.Status)
+	}
+	return nil
+}
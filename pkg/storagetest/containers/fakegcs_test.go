@@ -0,0 +1,26 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build gcpstorage
+
+package containers
+
+import (
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/storagetest"
+)
+
+func TestFakeGCS(t *testing.T) {
+	storagetest.TestStorage(t, FakeGCS(t).NewStorage)
+}
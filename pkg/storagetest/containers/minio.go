@@ -0,0 +1,74 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+//go:build minio
+
+package containers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	awss3 "github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/jeremyhahn/go-objstore/pkg/minio"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// MinIOImage is the image MinIO starts.
+var MinIOImage = "quay.io/minio/minio:RELEASE.2024-09-22T00-33-43Z"
+
+const (
+	minioUser     = "minioadmin"
+	minioPassword = "minioadmin"
+	minioRegion   = "us-east-1"
+)
+
+// MinIO starts a MinIO server for the "minio" backend.
+func MinIO(t *testing.T) *Emulator {
+	t.Helper()
+	endpoint := start(t, MinIOImage, "9000/tcp",
+		testcontainers.WithCmd("server", "/data"),
+		testcontainers.WithEnv(map[string]string{
+			"MINIO_ROOT_USER":     minioUser,
+			"MINIO_ROOT_PASSWORD": minioPassword,
+		}),
+		testcontainers.WithWaitStrategy(wait.ForHTTP("/minio/health/live").WithPort("9000/tcp")),
+	)
+
+	client := awss3.New(awss3.Options{
+		Region:       minioRegion,
+		BaseEndpoint: aws.String(endpoint),
+		UsePathStyle: true,
+		Credentials:  credentials.NewStaticCredentialsProvider(minioUser, minioPassword, ""),
+	})
+	return &Emulator{
+		Endpoint:   endpoint,
+		newStorage: minio.New,
+		settings: func(bucket string) map[string]string {
+			return map[string]string{
+				"bucket":    bucket,
+				"endpoint":  endpoint,
+				"region":    minioRegion,
+				"accessKey": minioUser,
+				"secretKey": minioPassword,
+			}
+		},
+		createBucket: func(ctx context.Context, bucket string) error {
+			_, err := client.CreateBucket(ctx, &awss3.CreateBucketInput{Bucket: aws.String(bucket)})
+			return err
+		},
+	}
+}
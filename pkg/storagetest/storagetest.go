@@ -0,0 +1,470 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package storagetest is a conformance test suite for common.Storage
+// implementations. Backend authors run it from their own tests to verify
+// that a backend behaves like the built-in ones:
+//
+//	func TestConformance(t *testing.T) {
+//	    storagetest.TestStorage(t, func(t *testing.T) common.Storage {
+//	        s := mystore.New()
+//	        if err := s.Configure(map[string]string{"path": t.TempDir()}); err != nil {
+//	            t.Fatal(err)
+//	        }
+//	        return s
+//	    })
+//	}
+package storagetest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// Factory returns a new, empty storage backend. It is called once per
+// subtest, so each starts from an empty backend; register cleanup with
+// t.Cleanup.
+type Factory func(t *testing.T) common.Storage
+
+// TestStorage runs the conformance suite against backends returned by
+// newStorage: reads and writes, listing, archiving, metadata, paginated
+// and delimited listing, context cancellation, key validation and
+// concurrent use. Each check is a subtest, so a single one can be run with
+// -run 'TestConformance/Pagination'.
+func TestStorage(t *testing.T, newStorage Factory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s common.Storage)
+	}{
+		{"PutGet", testPutGet},
+		{"Overwrite", testOverwrite},
+		{"EmptyObject", testEmptyObject},
+		{"GetMissing", testGetMissing},
+		{"Exists", testExists},
+		{"Delete", testDelete},
+		{"List", testList},
+		{"Archive", testArchive},
+		{"Metadata", testMetadata},
+		{"UpdateMetadata", testUpdateMetadata},
+		{"ListWithOptions", testListWithOptions},
+		{"Pagination", testPagination},
+		{"Delimiter", testDelimiter},
+		{"ContextCancellation", testContextCancellation},
+		{"KeyValidation", testKeyValidation},
+		{"Concurrency", testConcurrency},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newStorage(t))
+		})
+	}
+}
+
+// put stores data under key and fails the test on error.
+func put(t *testing.T, s common.Storage, key, data string) {
+	t.Helper()
+	if err := s.Put(key, strings.NewReader(data)); err != nil {
+		t.Fatalf("Put(%q) error = %v", key, err)
+	}
+}
+
+// get reads the object stored under key and fails the test on error.
+func get(t *testing.T, s common.Storage, key string) string {
+	t.Helper()
+	r, err := s.Get(key)
+	if err != nil {
+		t.Fatalf("Get(%q) error = %v", key, err)
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("reading %q: %v", key, err)
+	}
+	return string(data)
+}
+
+// list returns the sorted keys under prefix and fails the test on error.
+func list(t *testing.T, s common.Storage, prefix string) []string {
+	t.Helper()
+	keys, err := s.List(prefix)
+	if err != nil {
+		t.Fatalf("List(%q) error = %v", prefix, err)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func testPutGet(t *testing.T, s common.Storage) {
+	put(t, s, "a.txt", "hello")
+	put(t, s, "dir/nested/b.bin", "\x00\x01\xff")
+	if got := get(t, s, "a.txt"); got != "hello" {
+		t.Errorf("Get(a.txt) = %q, want %q", got, "hello")
+	}
+	if got := get(t, s, "dir/nested/b.bin"); got != "\x00\x01\xff" {
+		t.Errorf("Get(dir/nested/b.bin) = %q, want binary data intact", got)
+	}
+
+	large := bytes.Repeat([]byte("0123456789abcdef"), 64*1024) // 1 MiB
+	if err := s.Put("large", bytes.NewReader(large)); err != nil {
+		t.Fatalf("Put(large) error = %v", err)
+	}
+	if got := get(t, s, "large"); got != string(large) {
+		t.Errorf("Get(large) returned %d bytes, want %d intact", len(got), len(large))
+	}
+}
+
+func testOverwrite(t *testing.T, s common.Storage) {
+	put(t, s, "key", "first version")
+	put(t, s, "key", "second")
+	if got := get(t, s, "key"); got != "second" {
+		t.Errorf("Get() after overwrite = %q, want %q", got, "second")
+	}
+	if keys := list(t, s, ""); len(keys) != 1 {
+		t.Errorf("List() after overwrite = %v, want one key", keys)
+	}
+}
+
+func testEmptyObject(t *testing.T, s common.Storage) {
+	put(t, s, "empty", "")
+	if got := get(t, s, "empty"); got != "" {
+		t.Errorf("Get(empty) = %q, want empty", got)
+	}
+	exists, err := s.Exists(context.Background(), "empty")
+	if err != nil || !exists {
+		t.Errorf("Exists(empty) = %v, %v, want true", exists, err)
+	}
+}
+
+func testGetMissing(t *testing.T, s common.Storage) {
+	r, err := s.Get("missing")
+	if err == nil {
+		r.Close()
+		t.Fatal("Get(missing) succeeded, want an error")
+	}
+	if !errors.Is(err, common.ErrNotFound) {
+		t.Errorf("Get(missing) error = %v, want common.ErrNotFound", err)
+	}
+}
+
+func testExists(t *testing.T, s common.Storage) {
+	ctx := context.Background()
+	put(t, s, "present", "x")
+	if exists, err := s.Exists(ctx, "present"); err != nil || !exists {
+		t.Errorf("Exists(present) = %v, %v, want true", exists, err)
+	}
+	if exists, err := s.Exists(ctx, "absent"); err != nil || exists {
+		t.Errorf("Exists(absent) = %v, %v, want false", exists, err)
+	}
+}
+
+func testDelete(t *testing.T, s common.Storage) {
+	ctx := context.Background()
+	put(t, s, "doomed", "x")
+	put(t, s, "kept", "y")
+	if err := s.Delete("doomed"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if exists, err := s.Exists(ctx, "doomed"); err != nil || exists {
+		t.Errorf("Exists() after Delete = %v, %v, want false", exists, err)
+	}
+	if r, err := s.Get("doomed"); err == nil {
+		r.Close()
+		t.Error("Get() after Delete succeeded, want an error")
+	}
+	if got := get(t, s, "kept"); got != "y" {
+		t.Errorf("Delete removed the wrong object: Get(kept) = %q", got)
+	}
+}
+
+func testList(t *testing.T, s common.Storage) {
+	for _, key := range []string{"logs/a", "logs/b", "logs/sub/c", "logsx", "other/d"} {
+		put(t, s, key, key)
+	}
+	if got, want := list(t, s, "logs/"), []string{"logs/a", "logs/b", "logs/sub/c"}; !slices.Equal(got, want) {
+		t.Errorf("List(logs/) = %v, want %v", got, want)
+	}
+	if got := list(t, s, ""); len(got) != 5 {
+		t.Errorf("List(\"\") = %v, want all 5 keys", got)
+	}
+	if got := list(t, s, "nothing/"); len(got) != 0 {
+		t.Errorf("List(nothing/) = %v, want none", got)
+	}
+}
+
+// archiveRecorder is an Archiver that keeps what it is given.
+type archiveRecorder map[string]string
+
+func (a archiveRecorder) Put(key string, data io.Reader) error {
+	b, err := io.ReadAll(data)
+	if err != nil {
+		return err
+	}
+	a[key] = string(b)
+	return nil
+}
+
+func testArchive(t *testing.T, s common.Storage) {
+	put(t, s, "cold/data", "archived")
+	dest := archiveRecorder{}
+	if err := s.Archive("cold/data", dest); err != nil {
+		t.Fatalf("Archive() error = %v", err)
+	}
+	if dest["cold/data"] != "archived" {
+		t.Errorf("archived %v, want cold/data = %q", dest, "archived")
+	}
+	if got := get(t, s, "cold/data"); got != "archived" {
+		t.Errorf("Get() after Archive = %q, want the object kept", got)
+	}
+}
+
+func testMetadata(t *testing.T, s common.Storage) {
+	ctx := context.Background()
+	before := time.Now().Add(-time.Minute)
+	err := s.PutWithMetadata(ctx, "doc.json", strings.NewReader(`{"a":1}`), &common.Metadata{
+		ContentType: "application/json",
+		Custom:      map[string]string{"owner": "alice"},
+	})
+	if err != nil {
+		t.Fatalf("PutWithMetadata() error = %v", err)
+	}
+
+	meta, err := s.GetMetadata(ctx, "doc.json")
+	if err != nil {
+		t.Fatalf("GetMetadata() error = %v", err)
+	}
+	if meta.Size != 7 {
+		t.Errorf("Size = %d, want 7", meta.Size)
+	}
+	if meta.ContentType != "application/json" {
+		t.Errorf("ContentType = %q, want application/json", meta.ContentType)
+	}
+	if meta.Custom["owner"] != "alice" {
+		t.Errorf("Custom = %v, want owner=alice", meta.Custom)
+	}
+	if meta.LastModified.Before(before) {
+		t.Errorf("LastModified = %v, want the time of the put", meta.LastModified)
+	}
+	if got := get(t, s, "doc.json"); got != `{"a":1}` {
+		t.Errorf("Get() = %q, want the data stored with metadata", got)
+	}
+
+	if _, err := s.GetMetadata(ctx, "missing"); !errors.Is(err, common.ErrNotFound) {
+		t.Errorf("GetMetadata(missing) error = %v, want common.ErrNotFound", err)
+	}
+}
+
+func testUpdateMetadata(t *testing.T, s common.Storage) {
+	ctx := context.Background()
+	put(t, s, "obj", "data")
+	err := s.UpdateMetadata(ctx, "obj", &common.Metadata{
+		ContentType: "text/plain",
+		Custom:      map[string]string{"reviewed": "true"},
+	})
+	if err != nil {
+		t.Fatalf("UpdateMetadata() error = %v", err)
+	}
+	meta, err := s.GetMetadata(ctx, "obj")
+	if err != nil {
+		t.Fatalf("GetMetadata() error = %v", err)
+	}
+	if meta.ContentType != "text/plain" || meta.Custom["reviewed"] != "true" {
+		t.Errorf("metadata after update = %+v", meta)
+	}
+	if got := get(t, s, "obj"); got != "data" {
+		t.Errorf("Get() after UpdateMetadata = %q, want the data unchanged", got)
+	}
+
+	if err := s.UpdateMetadata(ctx, "missing", &common.Metadata{}); err == nil {
+		t.Error("UpdateMetadata(missing) succeeded, want an error")
+	}
+}
+
+func testListWithOptions(t *testing.T, s common.Storage) {
+	put(t, s, "img/a.png", "12345")
+	put(t, s, "img/b.png", "1")
+	put(t, s, "txt/c.txt", "1")
+	result, err := s.ListWithOptions(context.Background(), &common.ListOptions{Prefix: "img/"})
+	if err != nil {
+		t.Fatalf("ListWithOptions() error = %v", err)
+	}
+	if result.Truncated || result.NextToken != "" {
+		t.Errorf("single page result is truncated: %+v", result)
+	}
+	sizes := map[string]int64{}
+	for _, obj := range result.Objects {
+		if obj.Metadata == nil {
+			t.Fatalf("object %q has no metadata", obj.Key)
+		}
+		sizes[obj.Key] = obj.Metadata.Size
+	}
+	if len(sizes) != 2 || sizes["img/a.png"] != 5 || sizes["img/b.png"] != 1 {
+		t.Errorf("ListWithOptions(img/) sizes = %v", sizes)
+	}
+}
+
+func testPagination(t *testing.T, s common.Storage) {
+	ctx := context.Background()
+	var want []string
+	for i := range 25 {
+		key := fmt.Sprintf("page/%02d", i)
+		put(t, s, key, "x")
+		want = append(want, key)
+	}
+	put(t, s, "elsewhere", "x")
+
+	var got []string
+	opts := &common.ListOptions{Prefix: "page/", MaxResults: 10}
+	for pages := 1; ; pages++ {
+		if pages > 10 {
+			t.Fatal("pagination did not terminate")
+		}
+		result, err := s.ListWithOptions(ctx, opts)
+		if err != nil {
+			t.Fatalf("ListWithOptions() page %d error = %v", pages, err)
+		}
+		if len(result.Objects) > 10 {
+			t.Errorf("page %d has %d objects, want at most 10", pages, len(result.Objects))
+		}
+		for _, obj := range result.Objects {
+			got = append(got, obj.Key)
+		}
+		if result.NextToken == "" {
+			if result.Truncated {
+				t.Error("last page is truncated without a next token")
+			}
+			break
+		}
+		opts.ContinueFrom = result.NextToken
+	}
+	slices.Sort(got)
+	if !slices.Equal(got, want) {
+		t.Errorf("paginated keys = %v, want each of %v exactly once", got, want)
+	}
+}
+
+func testDelimiter(t *testing.T, s common.Storage) {
+	for _, key := range []string{"root/a", "root/sub1/b", "root/sub1/c", "root/sub2/d"} {
+		put(t, s, key, "x")
+	}
+	result, err := s.ListWithOptions(context.Background(), &common.ListOptions{Prefix: "root/", Delimiter: "/"})
+	if err != nil {
+		t.Fatalf("ListWithOptions() error = %v", err)
+	}
+	var keys []string
+	for _, obj := range result.Objects {
+		keys = append(keys, obj.Key)
+	}
+	prefixes := slices.Clone(result.CommonPrefixes)
+	slices.Sort(prefixes)
+	if !slices.Equal(keys, []string{"root/a"}) {
+		t.Errorf("delimited objects = %v, want [root/a]", keys)
+	}
+	if !slices.Equal(prefixes, []string{"root/sub1/", "root/sub2/"}) {
+		t.Errorf("common prefixes = %v, want [root/sub1/ root/sub2/]", prefixes)
+	}
+}
+
+func testContextCancellation(t *testing.T, s common.Storage) {
+	put(t, s, "existing", "x")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := s.PutWithContext(ctx, "new", strings.NewReader("x")); err == nil {
+		t.Error("PutWithContext() with a canceled context succeeded")
+	}
+	if r, err := s.GetWithContext(ctx, "existing"); err == nil {
+		r.Close()
+		t.Error("GetWithContext() with a canceled context succeeded")
+	}
+	if err := s.DeleteWithContext(ctx, "existing"); err == nil {
+		t.Error("DeleteWithContext() with a canceled context succeeded")
+	}
+	if _, err := s.ListWithContext(ctx, ""); err == nil {
+		t.Error("ListWithContext() with a canceled context succeeded")
+	}
+
+	// Nothing changed.
+	if exists, err := s.Exists(context.Background(), "new"); err != nil || exists {
+		t.Errorf("Exists(new) = %v, %v, want the canceled put not stored", exists, err)
+	}
+	if got := get(t, s, "existing"); got != "x" {
+		t.Errorf("Get(existing) = %q, want the canceled delete not applied", got)
+	}
+}
+
+func testKeyValidation(t *testing.T, s common.Storage) {
+	for _, key := range []string{"", "../escape", "a/../../escape", "/absolute", "nul\x00byte"} {
+		err := s.Put(key, strings.NewReader("x"))
+		if !errors.Is(err, common.ErrInvalidArgument) {
+			t.Errorf("Put(%q) error = %v, want common.ErrInvalidArgument", key, err)
+		}
+	}
+	if keys := list(t, s, ""); len(keys) != 0 {
+		t.Errorf("invalid keys were stored: %v", keys)
+	}
+}
+
+func testConcurrency(t *testing.T, s common.Storage) {
+	const workers = 16
+	var wg sync.WaitGroup
+	errs := make(chan error, workers*3)
+	for i := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			key := fmt.Sprintf("concurrent/%02d", i)
+			want := strings.Repeat(string(rune('a'+i)), 1024)
+			if err := s.Put(key, strings.NewReader(want)); err != nil {
+				errs <- fmt.Errorf("Put(%q): %w", key, err)
+				return
+			}
+			// Writers racing on one key must leave one complete value.
+			if err := s.Put("concurrent/shared", strings.NewReader(want)); err != nil {
+				errs <- fmt.Errorf("Put(shared): %w", err)
+			}
+			r, err := s.Get(key)
+			if err != nil {
+				errs <- fmt.Errorf("Get(%q): %w", key, err)
+				return
+			}
+			data, err := io.ReadAll(r)
+			r.Close()
+			if err != nil || string(data) != want {
+				errs <- fmt.Errorf("Get(%q) returned %d bytes, err %v", key, len(data), err)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	if keys := list(t, s, "concurrent/"); len(keys) != workers+1 {
+		t.Errorf("List() = %d keys, want %d", len(keys), workers+1)
+	}
+	shared := get(t, s, "concurrent/shared")
+	if len(shared) != 1024 || strings.Count(shared, shared[:1]) != 1024 {
+		t.Errorf("Get(shared) = a mix of concurrent writes (%d bytes)", len(shared))
+	}
+}
@@ -0,0 +1,789 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package tasks is a durable queue for asynchronous operations, such as
+// archiving objects, long replication syncs and event notification
+// deliveries.
+//
+// Tasks are stored as objects on a storage backend, one per task under a
+// prefix for each state, so they survive restarts. A failed attempt is
+// retried with exponential backoff; a task that fails Config.MaxAttempts
+// times, or with a Permanent error, is moved to the dead-letter state until
+// it is retried by hand. Tasks are run at least once: a task whose attempt
+// was interrupted by a restart or a change of leader is run again.
+package tasks
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+const (
+	// DefaultPrefix is the key prefix tasks are stored under when
+	// Config.Prefix is empty.
+	DefaultPrefix = ".tasks/"
+
+	// DefaultMaxAttempts bounds the attempts of a task when
+	// Config.MaxAttempts is zero.
+	DefaultMaxAttempts = 8
+
+	// DefaultBackoff is the delay before the first retry when
+	// Config.Backoff is zero. It doubles with every failed attempt.
+	DefaultBackoff = time.Second
+
+	// DefaultMaxBackoff caps the delay between retries when
+	// Config.MaxBackoff is zero.
+	DefaultMaxBackoff = time.Hour
+
+	// DefaultWorkers bounds the tasks run at once when Config.Workers is
+	// zero.
+	DefaultWorkers = 4
+
+	// DefaultTimeout bounds an attempt when Config.Timeout is zero.
+	DefaultTimeout = 10 * time.Minute
+
+	// DefaultRetention is how long succeeded tasks are kept when
+	// Config.Retention is zero.
+	DefaultRetention = 24 * time.Hour
+
+	// DefaultPoll is how often the queue checks for due tasks when
+	// Config.Poll is zero.
+	DefaultPoll = time.Second
+
+	// sweepInterval is how often succeeded tasks past their retention are
+	// deleted.
+	sweepInterval = time.Hour
+
+	// maxTaskSize bounds the stored form of a task that is read back.
+	maxTaskSize = 1 << 20
+)
+
+var (
+	// ErrTaskNotFound is returned for a task ID that is not queued.
+	ErrTaskNotFound = fmt.Errorf("task %w", common.ErrNotFound)
+
+	// ErrInvalidTask is returned when enqueuing a task without a type or
+	// with a payload that cannot be encoded.
+	ErrInvalidTask = fmt.Errorf("%w: invalid task", common.ErrInvalidArgument)
+
+	// ErrNotRetryable is returned by Retry for a task that is not in the
+	// dead-letter state.
+	ErrNotRetryable = fmt.Errorf("%w: only dead tasks can be retried", common.ErrPreconditionFailed)
+
+	// ErrInvalidConfig is returned by New for a shared queue without
+	// storage.
+	ErrInvalidConfig = fmt.Errorf("%w: a shared task queue needs storage", common.ErrInvalidArgument)
+
+	// ErrTaskCorrupt is returned for a stored task that cannot be decoded.
+	ErrTaskCorrupt = errors.New("task is corrupt")
+)
+
+// State is the stage of a task.
+type State string
+
+const (
+	// StatePending is a task waiting for its next attempt.
+	StatePending State = "pending"
+
+	// StateRunning is a task being attempted. It is only reported by the
+	// instance running it and is stored as pending.
+	StateRunning State = "running"
+
+	// StateSucceeded is a task whose last attempt succeeded.
+	StateSucceeded State = "succeeded"
+
+	// StateDead is a task that failed every attempt, or failed
+	// permanently, and waits to be retried by hand.
+	StateDead State = "dead"
+)
+
+// storedStates are the states a task is stored under.
+var storedStates = []State{StatePending, StateSucceeded, StateDead}
+
+// Task is an operation queued for asynchronous execution.
+type Task struct {
+	ID      string          `json:"id"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+	State   State           `json:"state"`
+
+	// Attempts counts the attempts made so far.
+	Attempts    int `json:"attempts"`
+	MaxAttempts int `json:"max_attempts"`
+
+	// NextAttempt is when a pending task is next due.
+	NextAttempt time.Time `json:"next_attempt"`
+
+	// LastError is the error of the last failed attempt.
+	LastError string `json:"last_error,omitempty"`
+
+	// Node identifies the instance that made the last attempt.
+	Node string `json:"node,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Decode unmarshals the task's payload into v.
+func (t *Task) Decode(v any) error {
+	if err := json.Unmarshal(t.Payload, v); err != nil {
+		return Permanent(fmt.Errorf("invalid %s task payload: %w", t.Type, err))
+	}
+	return nil
+}
+
+// Handler attempts a task. A task whose handler returns an error is
+// retried unless the error is Permanent.
+type Handler func(ctx context.Context, task *Task) error
+
+// permanentError marks an error that retrying cannot fix.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so that the task failing with it is moved to the
+// dead-letter state without further attempts.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether err was wrapped with Permanent.
+func IsPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
+
+// Config configures a Queue. Zero fields take their defaults.
+type Config struct {
+	// Storage keeps the tasks. If nil, they are kept in memory and lost on
+	// restart.
+	Storage common.Storage
+
+	// Prefix is the key prefix tasks are stored under (default:
+	// DefaultPrefix).
+	Prefix string
+
+	// Shared reads tasks back from Storage, for a backend that other
+	// instances enqueue to and retry on too.
+	Shared bool
+
+	// IsLeader reports whether this instance may run tasks. If nil, it
+	// always may. Every instance may enqueue and retry tasks.
+	IsLeader func() bool
+
+	// Node identifies this instance in tasks it attempts.
+	Node string
+
+	// MaxAttempts bounds the attempts of a task.
+	MaxAttempts int
+
+	// Backoff is the delay before the first retry, doubled for every
+	// further attempt up to MaxBackoff.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+
+	// Workers bounds the tasks run at once.
+	Workers int
+
+	// Timeout bounds an attempt.
+	Timeout time.Duration
+
+	// Retention is how long succeeded tasks are kept. Dead tasks are kept
+	// until they are retried.
+	Retention time.Duration
+
+	// Poll is how often the queue checks for due tasks.
+	Poll time.Duration
+}
+
+// Queue runs queued tasks with the handler registered for their type. It is
+// safe for concurrent use.
+type Queue struct {
+	cfg Config
+
+	mu        sync.Mutex
+	handlers  map[string]Handler
+	tasks     map[string]*Task
+	running   map[string]context.CancelFunc
+	leading   bool
+	lastSweep time.Time
+
+	startOnce sync.Once
+	closeOnce sync.Once
+	stop      chan struct{}
+	done      chan struct{}
+	runs      sync.WaitGroup
+
+	// now is replaced by tests.
+	now func() time.Time
+}
+
+// New creates a Queue and loads the tasks in storage. Register handlers,
+// then call Start.
+func New(cfg *Config) (*Queue, error) {
+	c := Config{}
+	if cfg != nil {
+		c = *cfg
+	}
+	if c.Shared && c.Storage == nil {
+		return nil, ErrInvalidConfig
+	}
+	if c.Prefix == "" {
+		c.Prefix = DefaultPrefix
+	}
+	if !strings.HasSuffix(c.Prefix, "/") {
+		c.Prefix += "/"
+	}
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = DefaultMaxAttempts
+	}
+	if c.Backoff <= 0 {
+		c.Backoff = DefaultBackoff
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = DefaultMaxBackoff
+	}
+	if c.Workers <= 0 {
+		c.Workers = DefaultWorkers
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = DefaultTimeout
+	}
+	if c.Retention <= 0 {
+		c.Retention = DefaultRetention
+	}
+	if c.Poll <= 0 {
+		c.Poll = DefaultPoll
+	}
+
+	q := &Queue{
+		cfg:      c,
+		handlers: make(map[string]Handler),
+		tasks:    make(map[string]*Task),
+		running:  make(map[string]context.CancelFunc),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+		now:      time.Now,
+	}
+	// A shared queue reads pending tasks when it starts leading.
+	if c.Storage != nil && !c.Shared {
+		loaded, err := q.readAll(context.Background(), "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to load tasks: %w", err)
+		}
+		for _, t := range loaded {
+			q.tasks[t.ID] = t
+		}
+	}
+	return q, nil
+}
+
+// Handle registers the handler of a task type. Tasks of a type without a
+// handler stay pending.
+func (q *Queue) Handle(taskType string, handler Handler) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.handlers[taskType] = handler
+}
+
+// Enqueue queues a task of taskType whose payload is v encoded as JSON.
+func (q *Queue) Enqueue(ctx context.Context, taskType string, v any) (*Task, error) {
+	if taskType == "" {
+		return nil, fmt.Errorf("%w: a task needs a type", ErrInvalidTask)
+	}
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTask, err)
+	}
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	now := q.now()
+	t := &Task{
+		ID:          id,
+		Type:        taskType,
+		Payload:     payload,
+		State:       StatePending,
+		MaxAttempts: q.cfg.MaxAttempts,
+		NextAttempt: now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := q.write(ctx, t); err != nil {
+		return nil, err
+	}
+
+	task := *t
+	if !q.cfg.Shared {
+		q.mu.Lock()
+		q.tasks[id] = t
+		q.mu.Unlock()
+	}
+	return &task, nil
+}
+
+// Start checks for due tasks every poll until q is closed.
+func (q *Queue) Start() {
+	q.startOnce.Do(func() {
+		go q.loop()
+	})
+}
+
+// Close stops the queue, cancels running attempts and waits for them to
+// return. Cancelled attempts are not counted and run again later.
+func (q *Queue) Close() error {
+	q.closeOnce.Do(func() {
+		close(q.stop)
+		started := true
+		q.startOnce.Do(func() { started = false })
+		if started {
+			<-q.done
+		}
+		q.mu.Lock()
+		q.cancelRunningLocked()
+		q.mu.Unlock()
+		q.runs.Wait()
+	})
+	return nil
+}
+
+// List returns the tasks in state, or every task when state is empty,
+// oldest first.
+func (q *Queue) List(ctx context.Context, state State) ([]Task, error) {
+	var all []*Task
+	if q.cfg.Shared {
+		stored := state
+		if state == StateRunning {
+			stored = StatePending
+		}
+		var err error
+		if all, err = q.readAll(ctx, stored); err != nil {
+			return nil, err
+		}
+	} else {
+		q.mu.Lock()
+		for _, t := range q.tasks {
+			task := *t
+			all = append(all, &task)
+		}
+		q.mu.Unlock()
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	list := make([]Task, 0, len(all))
+	for _, t := range all {
+		q.overlayLocked(t)
+		if state == "" || t.State == state {
+			list = append(list, *t)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].CreatedAt.Equal(list[j].CreatedAt) {
+			return list[i].CreatedAt.Before(list[j].CreatedAt)
+		}
+		return list[i].ID < list[j].ID
+	})
+	return list, nil
+}
+
+// Get returns a task.
+func (q *Queue) Get(ctx context.Context, id string) (*Task, error) {
+	t, err := q.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.overlayLocked(t)
+	return t, nil
+}
+
+// Retry moves a dead task back to pending with its attempts reset, so it
+// is attempted again straight away.
+func (q *Queue) Retry(ctx context.Context, id string) (*Task, error) {
+	t, err := q.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if t.State != StateDead {
+		return nil, fmt.Errorf("%w: task %s is %s", ErrNotRetryable, id, t.State)
+	}
+
+	now := q.now()
+	t.State = StatePending
+	t.Attempts = 0
+	t.MaxAttempts = q.cfg.MaxAttempts
+	t.NextAttempt = now
+	t.UpdatedAt = now
+	if err := q.move(ctx, t, StateDead); err != nil {
+		return nil, err
+	}
+
+	if !q.cfg.Shared {
+		q.mu.Lock()
+		stored := *t
+		q.tasks[id] = &stored
+		q.mu.Unlock()
+	}
+	return t, nil
+}
+
+// find returns a copy of a task, read from storage for a shared queue.
+func (q *Queue) find(ctx context.Context, id string) (*Task, error) {
+	if q.cfg.Shared {
+		for _, state := range storedStates {
+			t, err := q.read(ctx, q.key(state, id))
+			if errors.Is(err, common.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			return t, nil
+		}
+		return nil, fmt.Errorf("%w: %s", ErrTaskNotFound, id)
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	t, ok := q.tasks[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrTaskNotFound, id)
+	}
+	task := *t
+	return &task, nil
+}
+
+// overlayLocked reports a task this instance is attempting as running.
+func (q *Queue) overlayLocked(t *Task) {
+	if _, ok := q.running[t.ID]; ok && t.State == StatePending {
+		t.State = StateRunning
+	}
+}
+
+func (q *Queue) loop() {
+	defer close(q.done)
+
+	ticker := time.NewTicker(q.cfg.Poll)
+	defer ticker.Stop()
+
+	for {
+		q.tick()
+		select {
+		case <-q.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// tick starts the tasks that are due while this instance leads, and
+// cancels running attempts when it stops leading.
+func (q *Queue) tick() {
+	leading := q.cfg.IsLeader == nil || q.cfg.IsLeader()
+	if !leading {
+		q.mu.Lock()
+		if q.leading {
+			q.cancelRunningLocked()
+		}
+		q.leading = false
+		q.mu.Unlock()
+		return
+	}
+
+	// Pick up tasks enqueued or retried by other instances.
+	if q.cfg.Shared {
+		q.refresh(context.Background())
+	}
+	q.sweep(context.Background())
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.leading = true
+
+	now := q.now()
+	var due []*Task
+	for _, t := range q.tasks {
+		if t.State != StatePending || now.Before(t.NextAttempt) || q.handlers[t.Type] == nil {
+			continue
+		}
+		if _, ok := q.running[t.ID]; ok {
+			continue
+		}
+		due = append(due, t)
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].CreatedAt.Before(due[j].CreatedAt) })
+
+	for _, t := range due {
+		if len(q.running) >= q.cfg.Workers {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), q.cfg.Timeout)
+		q.running[t.ID] = cancel
+		task := *t
+		handler := q.handlers[t.Type]
+		q.runs.Add(1)
+		go func() {
+			defer q.runs.Done()
+			q.execute(ctx, handler, &task)
+		}()
+	}
+}
+
+// execute attempts task and records the outcome.
+func (q *Queue) execute(ctx context.Context, handler Handler, task *Task) {
+	err := handler(ctx, task)
+	interrupted := errors.Is(ctx.Err(), context.Canceled)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if cancel, ok := q.running[task.ID]; ok {
+		cancel()
+		delete(q.running, task.ID)
+	}
+	t, ok := q.tasks[task.ID]
+	if !ok || t.State != StatePending || interrupted {
+		// Retried or removed meanwhile, or cancelled at shutdown or on a
+		// change of leader: the attempt does not count.
+		return
+	}
+
+	now := q.now()
+	t.Attempts++
+	t.Node = q.cfg.Node
+	t.UpdatedAt = now
+	switch {
+	case err == nil:
+		t.State = StateSucceeded
+		t.LastError = ""
+	case IsPermanent(err) || t.Attempts >= t.MaxAttempts:
+		t.State = StateDead
+		t.LastError = err.Error()
+	default:
+		t.LastError = err.Error()
+		t.NextAttempt = now.Add(q.backoff(t.Attempts))
+	}
+	// The in-memory task stays authoritative; a failed write is retried
+	// with the next change.
+	_ = q.move(context.Background(), t, StatePending) // #nosec G104 -- see above
+	if q.cfg.Shared && t.State != StatePending {
+		// A shared queue only caches the tasks it has to run.
+		delete(q.tasks, t.ID)
+	}
+}
+
+// backoff returns the delay after the given number of failed attempts.
+func (q *Queue) backoff(attempts int) time.Duration {
+	delay := q.cfg.Backoff
+	for i := 1; i < attempts && delay < q.cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, q.cfg.MaxBackoff)
+}
+
+func (q *Queue) cancelRunningLocked() {
+	for _, cancel := range q.running {
+		cancel()
+	}
+}
+
+// refresh caches the pending tasks stored by other instances and drops
+// the ones they finished.
+func (q *Queue) refresh(ctx context.Context) {
+	keys, err := q.cfg.Storage.ListWithContext(ctx, q.cfg.Prefix+string(StatePending)+"/")
+	if err != nil {
+		return
+	}
+	stored := make(map[string]bool, len(keys))
+	var unknown []string
+	q.mu.Lock()
+	for _, key := range keys {
+		id := q.id(key)
+		stored[id] = true
+		if t, ok := q.tasks[id]; !ok || t.State != StatePending {
+			unknown = append(unknown, key)
+		}
+	}
+	for id, t := range q.tasks {
+		if _, running := q.running[id]; t.State == StatePending && !stored[id] && !running {
+			delete(q.tasks, id)
+		}
+	}
+	q.mu.Unlock()
+
+	for _, key := range unknown {
+		t, err := q.read(ctx, key)
+		if err != nil {
+			continue
+		}
+		q.mu.Lock()
+		q.tasks[t.ID] = t
+		q.mu.Unlock()
+	}
+}
+
+// sweep deletes succeeded tasks older than the retention period, at most
+// once per sweepInterval.
+func (q *Queue) sweep(ctx context.Context) {
+	q.mu.Lock()
+	now := q.now()
+	if now.Sub(q.lastSweep) < sweepInterval {
+		q.mu.Unlock()
+		return
+	}
+	q.lastSweep = now
+	cutoff := now.Add(-q.cfg.Retention)
+	var expired []*Task
+	for id, t := range q.tasks {
+		if t.State == StateSucceeded && t.UpdatedAt.Before(cutoff) {
+			expired = append(expired, t)
+			delete(q.tasks, id)
+		}
+	}
+	q.mu.Unlock()
+
+	if q.cfg.Shared {
+		// A shared queue does not cache succeeded tasks.
+		succeeded, err := q.readAll(ctx, StateSucceeded)
+		if err != nil {
+			return
+		}
+		for _, t := range succeeded {
+			if t.UpdatedAt.Before(cutoff) {
+				expired = append(expired, t)
+			}
+		}
+	}
+	for _, t := range expired {
+		_ = q.remove(ctx, q.key(StateSucceeded, t.ID)) // #nosec G104 -- The next sweep retries
+	}
+}
+
+// key returns the storage key of a task in state.
+func (q *Queue) key(state State, id string) string {
+	return q.cfg.Prefix + string(state) + "/" + id
+}
+
+// id returns the task ID of a storage key.
+func (q *Queue) id(key string) string {
+	return key[strings.LastIndex(key, "/")+1:]
+}
+
+// write stores t under its state.
+func (q *Queue) write(ctx context.Context, t *Task) error {
+	if q.cfg.Storage == nil {
+		return nil
+	}
+	data, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	return q.cfg.Storage.PutWithMetadata(ctx, q.key(t.State, t.ID), bytes.NewReader(data), &common.Metadata{
+		ContentType: "application/json",
+	})
+}
+
+// move stores t under its state and removes it from the state it was in.
+func (q *Queue) move(ctx context.Context, t *Task, from State) error {
+	if err := q.write(ctx, t); err != nil {
+		return err
+	}
+	if t.State == from {
+		return nil
+	}
+	return q.remove(ctx, q.key(from, t.ID))
+}
+
+func (q *Queue) remove(ctx context.Context, key string) error {
+	if q.cfg.Storage == nil {
+		return nil
+	}
+	err := q.cfg.Storage.DeleteWithContext(ctx, key)
+	if errors.Is(err, common.ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// read returns the task stored at key.
+func (q *Queue) read(ctx context.Context, key string) (*Task, error) {
+	rc, err := q.cfg.Storage.GetWithContext(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rc.Close() }()
+	data, err := io.ReadAll(io.LimitReader(rc, maxTaskSize))
+	if err != nil {
+		return nil, err
+	}
+	var t Task
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrTaskCorrupt, key, err)
+	}
+	return &t, nil
+}
+
+// readAll returns the stored tasks in state, or in every state when state
+// is empty. Corrupt tasks are skipped.
+func (q *Queue) readAll(ctx context.Context, state State) ([]*Task, error) {
+	states := storedStates
+	if state != "" {
+		states = []State{state}
+	}
+	var all []*Task
+	for _, s := range states {
+		keys, err := q.cfg.Storage.ListWithContext(ctx, q.cfg.Prefix+string(s)+"/")
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range keys {
+			t, err := q.read(ctx, key)
+			if errors.Is(err, common.ErrNotFound) || errors.Is(err, ErrTaskCorrupt) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			all = append(all, t)
+		}
+	}
+	return all, nil
+}
+
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,350 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package tasks
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+// fakeClock is a settable clock for retry tests.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newTestQueue(t *testing.T, cfg *Config, clock *fakeClock) *Queue {
+	t.Helper()
+	q, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	q.now = clock.Now
+	t.Cleanup(func() { _ = q.Close() })
+	return q
+}
+
+func newClock() *fakeClock {
+	return &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+// tickAndWait runs one polling round and waits for the attempts it started.
+func tickAndWait(q *Queue) {
+	q.tick()
+	q.runs.Wait()
+}
+
+func mustGet(t *testing.T, q *Queue, id string) *Task {
+	t.Helper()
+	task, err := q.Get(context.Background(), id)
+	if err != nil {
+		t.Fatalf("Get(%s): %v", id, err)
+	}
+	return task
+}
+
+func TestEnqueue(t *testing.T) {
+	q := newTestQueue(t, nil, newClock())
+	ctx := context.Background()
+
+	if _, err := q.Enqueue(ctx, "", nil); !errors.Is(err, ErrInvalidTask) {
+		t.Errorf("Enqueue() without a type = %v, want ErrInvalidTask", err)
+	}
+	if _, err := q.Enqueue(ctx, "a", make(chan int)); !errors.Is(err, ErrInvalidTask) {
+		t.Errorf("Enqueue() with an unencodable payload = %v, want ErrInvalidTask", err)
+	}
+	task, err := q.Enqueue(ctx, "archive", map[string]string{"key": "a.txt"})
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	if task.ID == "" || task.State != StatePending || task.MaxAttempts != DefaultMaxAttempts {
+		t.Errorf("task = %+v", task)
+	}
+	var payload map[string]string
+	if err := task.Decode(&payload); err != nil || payload["key"] != "a.txt" {
+		t.Errorf("Decode() = %v, %v", payload, err)
+	}
+	if _, err := q.Get(ctx, "missing"); !errors.Is(err, ErrTaskNotFound) || !errors.Is(err, common.ErrNotFound) {
+		t.Errorf("Get() for a missing task = %v, want ErrTaskNotFound", err)
+	}
+	if _, err := New(&Config{Shared: true}); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("New() shared without storage = %v, want ErrInvalidConfig", err)
+	}
+}
+
+func TestQueue_RunsTasks(t *testing.T) {
+	q := newTestQueue(t, &Config{Node: "node-a"}, newClock())
+	ctx := context.Background()
+
+	orphan, _ := q.Enqueue(ctx, "unhandled", nil)
+	task, _ := q.Enqueue(ctx, "echo", "hello")
+	var got atomic.Value
+	q.Handle("echo", func(_ context.Context, task *Task) error {
+		var s string
+		if err := task.Decode(&s); err != nil {
+			return err
+		}
+		got.Store(s)
+		return nil
+	})
+
+	tickAndWait(q)
+	if got.Load() != "hello" {
+		t.Errorf("handler got %v", got.Load())
+	}
+	done := mustGet(t, q, task.ID)
+	if done.State != StateSucceeded || done.Attempts != 1 || done.Node != "node-a" {
+		t.Errorf("task = %+v", done)
+	}
+	if waiting := mustGet(t, q, orphan.ID); waiting.State != StatePending || waiting.Attempts != 0 {
+		t.Errorf("task without a handler = %+v, want pending", waiting)
+	}
+}
+
+func TestQueue_RetriesAndDeadLetter(t *testing.T) {
+	clock := newClock()
+	q := newTestQueue(t, &Config{MaxAttempts: 3, Backoff: time.Second}, clock)
+	ctx := context.Background()
+
+	var attempts atomic.Int32
+	q.Handle("flaky", func(context.Context, *Task) error {
+		attempts.Add(1)
+		return errors.New("backend unavailable")
+	})
+	task, _ := q.Enqueue(ctx, "flaky", nil)
+
+	tickAndWait(q)
+	failed := mustGet(t, q, task.ID)
+	if failed.State != StatePending || failed.Attempts != 1 || failed.LastError != "backend unavailable" {
+		t.Fatalf("after one failure task = %+v", failed)
+	}
+	if want := clock.Now().Add(time.Second); !failed.NextAttempt.Equal(want) {
+		t.Errorf("NextAttempt = %s, want %s", failed.NextAttempt, want)
+	}
+
+	// Not due yet.
+	tickAndWait(q)
+	if attempts.Load() != 1 {
+		t.Errorf("retried before the backoff: %d attempts", attempts.Load())
+	}
+
+	clock.Advance(time.Second)
+	tickAndWait(q)
+	if second := mustGet(t, q, task.ID); !second.NextAttempt.Equal(clock.Now().Add(2 * time.Second)) {
+		t.Errorf("second backoff NextAttempt = %s, want doubled", second.NextAttempt)
+	}
+	clock.Advance(2 * time.Second)
+	tickAndWait(q)
+	dead := mustGet(t, q, task.ID)
+	if dead.State != StateDead || dead.Attempts != 3 {
+		t.Fatalf("after max attempts task = %+v, want dead", dead)
+	}
+	if list, _ := q.List(ctx, StateDead); len(list) != 1 || list[0].ID != task.ID {
+		t.Errorf("List(dead) = %+v", list)
+	}
+
+	if _, err := q.Retry(ctx, "missing"); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("Retry() of a missing task = %v", err)
+	}
+	q.Handle("flaky", func(context.Context, *Task) error { return nil })
+	retried, err := q.Retry(ctx, task.ID)
+	if err != nil || retried.State != StatePending || retried.Attempts != 0 {
+		t.Fatalf("Retry() = %+v, %v", retried, err)
+	}
+	if _, err := q.Retry(ctx, task.ID); !errors.Is(err, ErrNotRetryable) {
+		t.Errorf("Retry() of a pending task = %v, want ErrNotRetryable", err)
+	}
+	tickAndWait(q)
+	if done := mustGet(t, q, task.ID); done.State != StateSucceeded {
+		t.Errorf("retried task = %+v, want succeeded", done)
+	}
+}
+
+func TestQueue_Permanent(t *testing.T) {
+	q := newTestQueue(t, nil, newClock())
+	q.Handle("bad", func(_ context.Context, task *Task) error {
+		var v int
+		return task.Decode(&v)
+	})
+	task, _ := q.Enqueue(context.Background(), "bad", "not a number")
+
+	tickAndWait(q)
+	if dead := mustGet(t, q, task.ID); dead.State != StateDead || dead.Attempts != 1 {
+		t.Errorf("task = %+v, want dead after one attempt", dead)
+	}
+	if IsPermanent(errors.New("x")) || !IsPermanent(Permanent(errors.New("x"))) || Permanent(nil) != nil {
+		t.Error("IsPermanent() misreports")
+	}
+}
+
+func TestQueue_Backoff(t *testing.T) {
+	q := newTestQueue(t, &Config{Backoff: time.Second, MaxBackoff: 10 * time.Second}, newClock())
+	for attempts, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 4: 8 * time.Second, 5: 10 * time.Second, 60: 10 * time.Second} {
+		if got := q.backoff(attempts); got != want {
+			t.Errorf("backoff(%d) = %s, want %s", attempts, got, want)
+		}
+	}
+}
+
+func TestQueue_Persistence(t *testing.T) {
+	storage := memory.New()
+	clock := newClock()
+	ctx := context.Background()
+
+	first := newTestQueue(t, &Config{Storage: storage}, clock)
+	first.Handle("fail", func(context.Context, *Task) error { return Permanent(errors.New("no")) })
+	pending, _ := first.Enqueue(ctx, "later", nil)
+	dead, _ := first.Enqueue(ctx, "fail", nil)
+	tickAndWait(first)
+	_ = first.Close()
+
+	second := newTestQueue(t, &Config{Storage: storage}, clock)
+	list, err := second.List(ctx, "")
+	if err != nil || len(list) != 2 {
+		t.Fatalf("List() after restart = %+v, %v", list, err)
+	}
+	if got := mustGet(t, second, pending.ID); got.State != StatePending {
+		t.Errorf("pending task after restart = %+v", got)
+	}
+	if got := mustGet(t, second, dead.ID); got.State != StateDead || got.LastError != "no" {
+		t.Errorf("dead task after restart = %+v", got)
+	}
+	keys, _ := storage.ListWithContext(ctx, DefaultPrefix)
+	if len(keys) != 2 {
+		t.Errorf("stored keys = %v, want one per task", keys)
+	}
+}
+
+func TestQueue_Sweep(t *testing.T) {
+	storage := memory.New()
+	clock := newClock()
+	ctx := context.Background()
+	q := newTestQueue(t, &Config{Storage: storage, Retention: time.Hour}, clock)
+	q.Handle("ok", func(context.Context, *Task) error { return nil })
+	task, _ := q.Enqueue(ctx, "ok", nil)
+	tickAndWait(q)
+
+	clock.Advance(2 * time.Hour)
+	tickAndWait(q)
+	if _, err := q.Get(ctx, task.ID); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("Get() after retention = %v, want ErrTaskNotFound", err)
+	}
+	if keys, _ := storage.ListWithContext(ctx, DefaultPrefix); len(keys) != 0 {
+		t.Errorf("stored keys after retention = %v", keys)
+	}
+}
+
+func TestQueue_Shared(t *testing.T) {
+	storage := memory.New()
+	clock := newClock()
+	ctx := context.Background()
+
+	var leaderIsA atomic.Bool
+	leaderIsA.Store(true)
+	a := newTestQueue(t, &Config{Storage: storage, Shared: true, Node: "a", IsLeader: leaderIsA.Load}, clock)
+	b := newTestQueue(t, &Config{Storage: storage, Shared: true, Node: "b", IsLeader: func() bool { return !leaderIsA.Load() }}, clock)
+
+	var ran atomic.Int32
+	fail := atomic.Bool{}
+	fail.Store(true)
+	handler := func(context.Context, *Task) error {
+		ran.Add(1)
+		if fail.Load() {
+			return Permanent(errors.New("rejected"))
+		}
+		return nil
+	}
+	a.Handle("deliver", handler)
+	b.Handle("deliver", handler)
+
+	// Enqueued on the follower, run by the leader.
+	task, err := b.Enqueue(ctx, "deliver", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tickAndWait(b)
+	if ran.Load() != 0 {
+		t.Fatal("follower ran a task")
+	}
+	tickAndWait(a)
+	if got := mustGet(t, b, task.ID); got.State != StateDead || got.Node != "a" {
+		t.Fatalf("task seen by follower = %+v, want dead on a", got)
+	}
+
+	// Retried on the follower, picked up by the leader.
+	fail.Store(false)
+	if _, err := b.Retry(ctx, task.ID); err != nil {
+		t.Fatalf("Retry: %v", err)
+	}
+	tickAndWait(a)
+	if got := mustGet(t, a, task.ID); got.State != StateSucceeded {
+		t.Fatalf("task after retry = %+v, want succeeded", got)
+	}
+
+	// A new leader picks up pending tasks.
+	leaderIsA.Store(false)
+	next, _ := a.Enqueue(ctx, "deliver", nil)
+	tickAndWait(a)
+	tickAndWait(b)
+	if got := mustGet(t, a, next.ID); got.State != StateSucceeded || got.Node != "b" {
+		t.Errorf("task after change of leader = %+v, want succeeded on b", got)
+	}
+	if list, _ := a.List(ctx, StateSucceeded); len(list) != 2 {
+		t.Errorf("List(succeeded) = %+v", list)
+	}
+}
+
+func TestQueue_LeadershipLossInterruptsAttempt(t *testing.T) {
+	var leading atomic.Bool
+	leading.Store(true)
+	q := newTestQueue(t, &Config{IsLeader: leading.Load}, newClock())
+
+	started := make(chan struct{})
+	q.Handle("slow", func(ctx context.Context, _ *Task) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	task, _ := q.Enqueue(context.Background(), "slow", nil)
+
+	q.tick()
+	<-started
+	if got := mustGet(t, q, task.ID); got.State != StateRunning {
+		t.Errorf("task during its attempt = %+v, want running", got)
+	}
+	leading.Store(false)
+	tickAndWait(q)
+	if got := mustGet(t, q, task.ID); got.State != StatePending || got.Attempts != 0 {
+		t.Errorf("interrupted task = %+v, want pending without a counted attempt", got)
+	}
+}
@@ -0,0 +1,331 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package tombstone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// Storage wraps a backend and defers its deletes to a Manager. Deleting an
+// existing object records a tombstone and leaves the object in place until
+// Purge removes it; tombstoned objects read as missing and are left out of
+// listings. Writing a tombstoned key replaces the object and clears the
+// tombstone, unless the write's modification time predates the delete.
+// Object operations on keys under the tombstone prefix fail with
+// common.ErrReservedKey.
+type Storage struct {
+	common.Storage
+	manager *Manager
+}
+
+// NewStorage returns underlying wrapped so that deletes are deferred by
+// manager.
+func NewStorage(underlying common.Storage, manager *Manager) *Storage {
+	return &Storage{Storage: underlying, manager: manager}
+}
+
+// Manager returns the manager recording s's tombstones.
+func (s *Storage) Manager() *Manager {
+	return s.manager
+}
+
+// Underlying returns the wrapped backend.
+func (s *Storage) Underlying() common.Storage {
+	return s.Storage
+}
+
+// Purge physically removes the objects whose grace period has passed and
+// deletes expired tombstones. It returns the number of objects removed.
+func (s *Storage) Purge(ctx context.Context) (int, error) {
+	return s.manager.Purge(ctx, s.Storage)
+}
+
+// check fails for reserved keys and reports key's tombstone, if any.
+func (s *Storage) check(ctx context.Context, key string) (*Tombstone, error) {
+	if s.manager.Reserved(key) {
+		return nil, fmt.Errorf("%w: %s", common.ErrReservedKey, key)
+	}
+	return s.manager.Lookup(ctx, key)
+}
+
+// visible fails for reserved and tombstoned keys.
+func (s *Storage) visible(ctx context.Context, key string) error {
+	t, err := s.check(ctx, key)
+	if err != nil {
+		return err
+	}
+	if t != nil {
+		return fmt.Errorf("%w: %s", common.ErrKeyNotFound, key)
+	}
+	return nil
+}
+
+// replace writes key with write and clears its tombstone, refusing writes
+// whose modification time predates the delete.
+func (s *Storage) replace(ctx context.Context, key string, metadata *common.Metadata, write func() error) error {
+	t, err := s.check(ctx, key)
+	if err != nil {
+		return err
+	}
+	if t == nil {
+		return write()
+	}
+	if metadata != nil && !metadata.LastModified.IsZero() && metadata.LastModified.Before(t.DeletedAt) {
+		return fmt.Errorf("%w: %s was deleted at %s", ErrStaleWrite, key, t.DeletedAt.Format(time.RFC3339))
+	}
+
+	done := s.manager.beginWrite(key)
+	defer done()
+	if err := write(); err != nil {
+		return err
+	}
+	return s.manager.Clear(ctx, key)
+}
+
+// Put stores an object, replacing a deleted one.
+func (s *Storage) Put(key string, data io.Reader) error {
+	return s.PutWithContext(context.Background(), key, data)
+}
+
+// PutWithContext stores an object, replacing a deleted one.
+func (s *Storage) PutWithContext(ctx context.Context, key string, data io.Reader) error {
+	return s.replace(ctx, key, nil, func() error {
+		return s.Storage.PutWithContext(ctx, key, data)
+	})
+}
+
+// PutWithMetadata stores an object with metadata, replacing a deleted one
+// unless metadata.LastModified predates the delete.
+func (s *Storage) PutWithMetadata(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) error {
+	return s.replace(ctx, key, metadata, func() error {
+		return s.Storage.PutWithMetadata(ctx, key, data, metadata)
+	})
+}
+
+// PutWithInfo stores an object with metadata like PutWithMetadata and
+// returns what was stored.
+func (s *Storage) PutWithInfo(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) (info *common.ObjectInfo, err error) {
+	err = s.replace(ctx, key, metadata, func() error {
+		info, err = common.PutWithInfo(ctx, s.Storage, key, data, metadata)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// Get retrieves an object that is not deleted.
+func (s *Storage) Get(key string) (io.ReadCloser, error) {
+	return s.GetWithContext(context.Background(), key)
+}
+
+// GetWithContext retrieves an object that is not deleted.
+func (s *Storage) GetWithContext(ctx context.Context, key string) (io.ReadCloser, error) {
+	if err := s.visible(ctx, key); err != nil {
+		return nil, err
+	}
+	return s.Storage.GetWithContext(ctx, key)
+}
+
+// GetRange reads a byte range of an object that is not deleted, falling
+// back to discarding the leading bytes of a full read when the wrapped
+// backend cannot read ranges.
+func (s *Storage) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	if err := s.visible(ctx, key); err != nil {
+		return nil, err
+	}
+	if rr, ok := s.Storage.(common.RangeReader); ok {
+		return rr.GetRange(ctx, key, offset, length)
+	}
+	rc, err := s.Storage.GetWithContext(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return common.SliceRange(rc, offset, length)
+}
+
+// GetAsOf reads the version of an object that was current at the given
+// time, unless the object had been deleted by then.
+func (s *Storage) GetAsOf(ctx context.Context, key string, at time.Time) (io.ReadCloser, *common.Metadata, error) {
+	t, err := s.check(ctx, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	if t != nil && !at.Before(t.DeletedAt) {
+		return nil, nil, fmt.Errorf("%w: %s", common.ErrKeyNotFound, key)
+	}
+	return common.GetAsOf(ctx, s.Storage, key, at)
+}
+
+// GetMetadata retrieves the metadata of an object that is not deleted.
+func (s *Storage) GetMetadata(ctx context.Context, key string) (*common.Metadata, error) {
+	if err := s.visible(ctx, key); err != nil {
+		return nil, err
+	}
+	return s.Storage.GetMetadata(ctx, key)
+}
+
+// UpdateMetadata updates the metadata of an object that is not deleted.
+func (s *Storage) UpdateMetadata(ctx context.Context, key string, metadata *common.Metadata) error {
+	if err := s.visible(ctx, key); err != nil {
+		return err
+	}
+	return s.Storage.UpdateMetadata(ctx, key, metadata)
+}
+
+// Delete records the deletion of an object.
+func (s *Storage) Delete(key string) error {
+	return s.DeleteWithContext(context.Background(), key)
+}
+
+// DeleteWithContext records the deletion of an existing object, leaving it
+// in place until its grace period has passed. Deleting a missing or
+// already deleted object fails as the backend would.
+func (s *Storage) DeleteWithContext(ctx context.Context, key string) error {
+	if err := s.visible(ctx, key); err != nil {
+		return err
+	}
+	exists, err := s.Storage.Exists(ctx, key)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return s.Storage.DeleteWithContext(ctx, key)
+	}
+	_, err = s.manager.Mark(ctx, key)
+	return err
+}
+
+// Exists reports whether an object exists and is not deleted.
+func (s *Storage) Exists(ctx context.Context, key string) (bool, error) {
+	t, err := s.check(ctx, key)
+	if err != nil || t != nil {
+		return false, err
+	}
+	return s.Storage.Exists(ctx, key)
+}
+
+// Archive copies an object that is not deleted to destination.
+func (s *Storage) Archive(key string, destination common.Archiver) error {
+	if err := s.visible(context.Background(), key); err != nil {
+		return err
+	}
+	return s.Storage.Archive(key, destination)
+}
+
+// Append adds data to the end of an object. Appending to a deleted object
+// starts a new one.
+func (s *Storage) Append(ctx context.Context, key string, data io.Reader) error {
+	t, err := s.check(ctx, key)
+	if err != nil {
+		return err
+	}
+	if t == nil {
+		return common.Append(ctx, s.Storage, key, data)
+	}
+
+	done := s.manager.beginWrite(key)
+	defer done()
+	if err := s.Storage.DeleteWithContext(ctx, key); err != nil && !errors.Is(err, common.ErrNotFound) {
+		return err
+	}
+	if err := common.Append(ctx, s.Storage, key, data); err != nil {
+		return err
+	}
+	return s.manager.Clear(ctx, key)
+}
+
+// Compose concatenates srcKeys into destKey, replacing a deleted destKey.
+// Deleted source objects are missing.
+func (s *Storage) Compose(ctx context.Context, destKey string, srcKeys ...string) error {
+	for _, key := range srcKeys {
+		if err := s.visible(ctx, key); err != nil {
+			return err
+		}
+	}
+	return s.replace(ctx, destKey, nil, func() error {
+		return common.Compose(ctx, s.Storage, destKey, srcKeys...)
+	})
+}
+
+// List returns the keys starting with prefix, leaving out deleted objects
+// and tombstones.
+func (s *Storage) List(prefix string) ([]string, error) {
+	return s.ListWithContext(context.Background(), prefix)
+}
+
+// ListWithContext returns the keys starting with prefix, leaving out
+// deleted objects and tombstones.
+func (s *Storage) ListWithContext(ctx context.Context, prefix string) ([]string, error) {
+	keys, err := s.Storage.ListWithContext(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	deleted, err := s.manager.deleted(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	filtered := keys[:0]
+	for _, key := range keys {
+		if _, ok := deleted[key]; !ok && !s.manager.Reserved(key) {
+			filtered = append(filtered, key)
+		}
+	}
+	return filtered, nil
+}
+
+// ListWithOptions lists objects, leaving out deleted objects, tombstones
+// and the tombstone prefix itself. Pages that contained them come back
+// short.
+func (s *Storage) ListWithOptions(ctx context.Context, opts *common.ListOptions) (*common.ListResult, error) {
+	result, err := s.Storage.ListWithOptions(ctx, opts)
+	if err != nil || result == nil {
+		return result, err
+	}
+	prefix := ""
+	if opts != nil {
+		prefix = opts.Prefix
+	}
+	deleted, err := s.manager.deleted(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := result.Objects[:0]
+	for _, obj := range result.Objects {
+		if obj != nil {
+			if _, ok := deleted[obj.Key]; ok || s.manager.Reserved(obj.Key) {
+				continue
+			}
+		}
+		objects = append(objects, obj)
+	}
+	result.Objects = objects
+
+	prefixes := result.CommonPrefixes[:0]
+	for _, p := range result.CommonPrefixes {
+		if !s.manager.Reserved(p) {
+			prefixes = append(prefixes, p)
+		}
+	}
+	result.CommonPrefixes = prefixes
+	return result, nil
+}
@@ -0,0 +1,444 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package tombstone defers deletes on backends that replicas and caches
+// read from, so deleted objects are not resurrected by a lagging copy.
+//
+// Deleting an object through Storage writes a tombstone under a reserved
+// prefix (DefaultPrefix) instead of removing it. Reads and listings of the
+// key are suppressed at once, and the object is physically removed by Purge
+// once a grace period has passed, giving replication and caches time to see
+// the delete. The tombstone itself is kept for a retention period after
+// that: writes carrying a modification time from before the delete are
+// refused, and the replication syncer neither copies tombstoned objects nor
+// restores them to a destination that deleted them.
+package tombstone
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// DefaultPrefix is the key prefix tombstones are stored under when none is
+// configured.
+const DefaultPrefix = ".tombstones/"
+
+// Defaults for Config.
+const (
+	DefaultGrace     = 24 * time.Hour
+	DefaultRetention = 7 * 24 * time.Hour
+)
+
+// SettingPrefix is the replication policy setting naming the prefix a
+// backend's tombstones are stored under, when it is not DefaultPrefix.
+const SettingPrefix = "tombstonePrefix"
+
+// maxTombstoneSize bounds the tombstone objects read back.
+const maxTombstoneSize = 64 * 1024
+
+var (
+
+	// ErrStaleWrite is returned when writing an object whose modification
+	// time predates its deletion, such as a copy from a lagging replica. It
+	// wraps common.ErrPreconditionFailed.
+	ErrStaleWrite = fmt.Errorf("%w: write predates the deletion of the object", common.ErrPreconditionFailed)
+)
+
+// Tombstone records the deletion of an object.
+type Tombstone struct {
+	Key       string    `json:"key"`
+	DeletedAt time.Time `json:"deleted_at"`
+
+	// PurgeAfter is when the object is physically removed.
+	PurgeAfter time.Time `json:"purge_after"`
+
+	// Purged reports whether the object has been physically removed.
+	Purged bool `json:"purged,omitempty"`
+
+	// ExpiresAt is when the tombstone itself is removed.
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Config configures a Manager. Zero fields take their defaults.
+type Config struct {
+	// Storage keeps the tombstones. If nil, they are kept in the backend
+	// passed to NewManager.
+	Storage common.Storage
+
+	// Prefix is the key prefix tombstones are stored under (default:
+	// DefaultPrefix).
+	Prefix string
+
+	// Grace is how long a deleted object is kept before it is physically
+	// removed. It should exceed the replication interval and the time
+	// caches serve an object without revalidating it.
+	Grace time.Duration
+
+	// Retention is how long a tombstone is kept after its object is
+	// physically removed, refusing stale writes of the object.
+	Retention time.Duration
+
+	// Shared reads tombstones from storage on every check, for a backend
+	// that other instances delete through too.
+	Shared bool
+}
+
+// Manager records tombstones in a backend. It is safe for concurrent use.
+type Manager struct {
+	storage   common.Storage
+	prefix    string
+	grace     time.Duration
+	retention time.Duration
+	shared    bool
+
+	mu    sync.RWMutex
+	index map[string]*Tombstone // unshared tombstones by key
+
+	// writesMu orders physical removal against writes that replace a
+	// tombstoned object, so Purge never removes the new object.
+	writesMu sync.Mutex
+	writes   map[string]int
+
+	// now is replaced by tests.
+	now func() time.Time
+}
+
+// NewManager returns a Manager storing tombstones in storage, or in
+// cfg.Storage if set, and loads the existing ones unless cfg.Shared.
+func NewManager(storage common.Storage, cfg *Config) (*Manager, error) {
+	c := Config{}
+	if cfg != nil {
+		c = *cfg
+	}
+	if c.Storage != nil {
+		storage = c.Storage
+	}
+	if c.Prefix == "" {
+		c.Prefix = DefaultPrefix
+	}
+	if !strings.HasSuffix(c.Prefix, "/") {
+		c.Prefix += "/"
+	}
+	if err := common.ValidateKey(c.Prefix + "key"); err != nil {
+		return nil, fmt.Errorf("invalid tombstone prefix %q: %w", c.Prefix, err)
+	}
+	if c.Grace <= 0 {
+		c.Grace = DefaultGrace
+	}
+	if c.Retention <= 0 {
+		c.Retention = DefaultRetention
+	}
+
+	m := &Manager{
+		storage:   storage,
+		prefix:    c.Prefix,
+		grace:     c.Grace,
+		retention: c.Retention,
+		shared:    c.Shared,
+		index:     make(map[string]*Tombstone),
+		writes:    make(map[string]int),
+		now:       time.Now,
+	}
+	if !m.shared {
+		loaded, err := Load(context.Background(), storage, m.prefix)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load tombstones: %w", err)
+		}
+		for key, t := range loaded {
+			m.index[key] = t
+		}
+	}
+	return m, nil
+}
+
+// Prefix returns the reserved key prefix.
+func (m *Manager) Prefix() string {
+	return m.prefix
+}
+
+// Reserved reports whether key is in the tombstone namespace.
+func (m *Manager) Reserved(key string) bool {
+	return common.IsReserved(key, m.prefix)
+}
+
+// Mark records the deletion of key.
+func (m *Manager) Mark(ctx context.Context, key string) (*Tombstone, error) {
+	now := m.now()
+	t := &Tombstone{
+		Key:        key,
+		DeletedAt:  now,
+		PurgeAfter: now.Add(m.grace),
+		ExpiresAt:  now.Add(m.grace + m.retention),
+	}
+	if err := m.write(ctx, t); err != nil {
+		return nil, err
+	}
+	result := *t
+	return &result, nil
+}
+
+// Lookup returns the tombstone of key, or nil if it has none.
+func (m *Manager) Lookup(ctx context.Context, key string) (*Tombstone, error) {
+	if m.shared {
+		return read(ctx, m.storage, m.prefix, key)
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	t, ok := m.index[key]
+	if !ok {
+		return nil, nil
+	}
+	result := *t
+	return &result, nil
+}
+
+// Clear removes the tombstone of key, if any.
+func (m *Manager) Clear(ctx context.Context, key string) error {
+	err := m.storage.DeleteWithContext(ctx, m.prefix+key)
+	if err != nil && !errors.Is(err, common.ErrNotFound) {
+		return err
+	}
+	if !m.shared {
+		m.mu.Lock()
+		delete(m.index, key)
+		m.mu.Unlock()
+	}
+	return nil
+}
+
+// List returns every tombstone, oldest first.
+func (m *Manager) List(ctx context.Context) ([]Tombstone, error) {
+	all, err := m.all(ctx)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]Tombstone, 0, len(all))
+	for _, t := range all {
+		list = append(list, *t)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].DeletedAt.Equal(list[j].DeletedAt) {
+			return list[i].DeletedAt.Before(list[j].DeletedAt)
+		}
+		return list[i].Key < list[j].Key
+	})
+	return list, nil
+}
+
+// Purge physically removes from storage the objects whose grace period has
+// passed, and deletes the tombstones whose retention has. It returns the
+// number of objects removed.
+func (m *Manager) Purge(ctx context.Context, storage common.Storage) (int, error) {
+	list, err := m.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var errs []error
+	purged := 0
+	for i := range list {
+		t := &list[i]
+		now := m.now()
+		switch {
+		case !now.Before(t.ExpiresAt):
+			if err := m.expire(ctx, t); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", t.Key, err))
+			}
+		case !t.Purged && !now.Before(t.PurgeAfter):
+			ok, err := m.remove(ctx, storage, t)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", t.Key, err))
+			} else if ok {
+				purged++
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return purged, err
+		}
+	}
+	return purged, errors.Join(errs...)
+}
+
+// remove physically removes t's object unless it is being replaced or its
+// tombstone has changed, and records that it is gone.
+func (m *Manager) remove(ctx context.Context, storage common.Storage, t *Tombstone) (bool, error) {
+	m.writesMu.Lock()
+	defer m.writesMu.Unlock()
+	if m.writes[t.Key] > 0 {
+		return false, nil
+	}
+	current, err := m.Lookup(ctx, t.Key)
+	if err != nil || current == nil || !current.DeletedAt.Equal(t.DeletedAt) {
+		return false, err
+	}
+
+	err = storage.DeleteWithContext(ctx, t.Key)
+	if err != nil && !errors.Is(err, common.ErrNotFound) {
+		return false, err
+	}
+	current.Purged = true
+	return true, m.write(ctx, current)
+}
+
+// expire deletes t unless the object was deleted again since.
+func (m *Manager) expire(ctx context.Context, t *Tombstone) error {
+	current, err := m.Lookup(ctx, t.Key)
+	if err != nil || current == nil || !current.DeletedAt.Equal(t.DeletedAt) {
+		return err
+	}
+	return m.Clear(ctx, t.Key)
+}
+
+// deleted returns the set of tombstoned keys starting with prefix. Shared
+// tombstones are listed rather than read.
+func (m *Manager) deleted(ctx context.Context, prefix string) (map[string]struct{}, error) {
+	set := make(map[string]struct{})
+	if !m.shared {
+		m.mu.RLock()
+		defer m.mu.RUnlock()
+		for key := range m.index {
+			if strings.HasPrefix(key, prefix) {
+				set[key] = struct{}{}
+			}
+		}
+		return set, nil
+	}
+
+	keys, err := m.storage.ListWithContext(ctx, m.prefix+prefix)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		set[strings.TrimPrefix(key, m.prefix)] = struct{}{}
+	}
+	return set, nil
+}
+
+// beginWrite marks key as being replaced until the returned function is
+// called, so Purge leaves it alone.
+func (m *Manager) beginWrite(key string) func() {
+	m.writesMu.Lock()
+	m.writes[key]++
+	m.writesMu.Unlock()
+	return func() {
+		m.writesMu.Lock()
+		if m.writes[key]--; m.writes[key] <= 0 {
+			delete(m.writes, key)
+		}
+		m.writesMu.Unlock()
+	}
+}
+
+// all returns the tombstones by key.
+func (m *Manager) all(ctx context.Context) (map[string]*Tombstone, error) {
+	if m.shared {
+		return Load(ctx, m.storage, m.prefix)
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	all := make(map[string]*Tombstone, len(m.index))
+	for key, t := range m.index {
+		copied := *t
+		all[key] = &copied
+	}
+	return all, nil
+}
+
+func (m *Manager) write(ctx context.Context, t *Tombstone) error {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	err = m.storage.PutWithMetadata(ctx, m.prefix+t.Key, bytes.NewReader(data), &common.Metadata{
+		ContentType: "application/json",
+		Size:        int64(len(data)),
+	})
+	if err != nil {
+		return err
+	}
+	if !m.shared {
+		stored := *t
+		m.mu.Lock()
+		m.index[t.Key] = &stored
+		m.mu.Unlock()
+	}
+	return nil
+}
+
+// Load reads the tombstones stored in storage under prefix, by key. Objects
+// under the prefix that are not tombstones are skipped.
+func Load(ctx context.Context, storage common.Storage, prefix string) (map[string]*Tombstone, error) {
+	keys, err := storage.ListWithContext(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	all := make(map[string]*Tombstone, len(keys))
+	for _, objectKey := range keys {
+		key := strings.TrimPrefix(objectKey, prefix)
+		t, err := read(ctx, storage, prefix, key)
+		if err != nil {
+			return nil, err
+		}
+		if t != nil {
+			all[key] = t
+		}
+	}
+	return all, nil
+}
+
+// PrefixFromSettings returns the tombstone prefix named by settings, such
+// as a replication policy's source or destination settings, or
+// DefaultPrefix.
+func PrefixFromSettings(settings map[string]string) string {
+	prefix := settings[SettingPrefix]
+	if prefix == "" {
+		return DefaultPrefix
+	}
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return prefix
+}
+
+// read returns the tombstone of key, or nil if it has none or the object
+// stored for it is not a tombstone of key.
+func read(ctx context.Context, storage common.Storage, prefix, key string) (*Tombstone, error) {
+	rc, err := storage.GetWithContext(ctx, prefix+key)
+	if errors.Is(err, common.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(io.LimitReader(rc, maxTombstoneSize))
+	_ = rc.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	var t Tombstone
+	if err := json.Unmarshal(data, &t); err != nil || t.Key != key {
+		return nil, nil
+	}
+	return &t, nil
+}
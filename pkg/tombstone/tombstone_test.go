@@ -0,0 +1,284 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package tombstone
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+// fakeClock is a settable clock for grace period tests.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newTestStorage(t *testing.T, backend common.Storage, cfg *Config) (*Storage, *fakeClock) {
+	t.Helper()
+	manager, err := NewManager(backend, cfg)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	manager.now = clock.Now
+	return NewStorage(backend, manager), clock
+}
+
+func put(t *testing.T, s common.Storage, key, data string) {
+	t.Helper()
+	if err := s.PutWithContext(context.Background(), key, strings.NewReader(data)); err != nil {
+		t.Fatalf("Put(%s): %v", key, err)
+	}
+}
+
+func readObject(t *testing.T, s common.Storage, key string) string {
+	t.Helper()
+	rc, err := s.GetWithContext(context.Background(), key)
+	if err != nil {
+		t.Fatalf("Get(%s): %v", key, err)
+	}
+	defer func() { _ = rc.Close() }()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatalf("read %s: %v", key, err)
+	}
+	return string(data)
+}
+
+func TestNewManager(t *testing.T) {
+	if _, err := NewManager(memory.New(), &Config{Prefix: "../x"}); err == nil {
+		t.Error("expected an error for an invalid prefix")
+	}
+
+	manager, err := NewManager(memory.New(), &Config{Prefix: "deleted"})
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	if manager.Prefix() != "deleted/" || manager.grace != DefaultGrace || manager.retention != DefaultRetention {
+		t.Errorf("defaults not applied: %q %s %s", manager.Prefix(), manager.grace, manager.retention)
+	}
+	if !manager.Reserved("deleted/a") || !manager.Reserved("deleted") || manager.Reserved("deletedx") {
+		t.Error("Reserved() does not match the prefix")
+	}
+}
+
+func TestDeleteIsDeferred(t *testing.T) {
+	backend := memory.New()
+	s, clock := newTestStorage(t, backend, &Config{Grace: time.Hour, Retention: 24 * time.Hour})
+	ctx := context.Background()
+	put(t, s, "docs/a.txt", "a")
+	put(t, s, "docs/b.txt", "b")
+
+	if err := s.DeleteWithContext(ctx, "docs/a.txt"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	// Reads are suppressed, but the object is still in the backend.
+	if _, err := s.GetWithContext(ctx, "docs/a.txt"); !errors.Is(err, common.ErrNotFound) {
+		t.Errorf("Get() of a deleted object: %v", err)
+	}
+	if _, err := s.GetMetadata(ctx, "docs/a.txt"); !errors.Is(err, common.ErrNotFound) {
+		t.Errorf("GetMetadata() of a deleted object: %v", err)
+	}
+	if exists, err := s.Exists(ctx, "docs/a.txt"); exists || err != nil {
+		t.Errorf("Exists() = %v, %v", exists, err)
+	}
+	if err := s.DeleteWithContext(ctx, "docs/a.txt"); !errors.Is(err, common.ErrNotFound) {
+		t.Errorf("second Delete(): %v", err)
+	}
+	if exists, _ := backend.Exists(ctx, "docs/a.txt"); !exists {
+		t.Error("object removed before its grace period")
+	}
+	keys, err := s.ListWithContext(ctx, "")
+	if err != nil || len(keys) != 1 || keys[0] != "docs/b.txt" {
+		t.Errorf("List() = %v, %v", keys, err)
+	}
+	result, err := s.ListWithOptions(ctx, &common.ListOptions{})
+	if err != nil || len(result.Objects) != 1 || result.Objects[0].Key != "docs/b.txt" {
+		t.Errorf("ListWithOptions() = %+v, %v", result, err)
+	}
+
+	// Deleting a missing object fails as the backend would.
+	if err := s.DeleteWithContext(ctx, "docs/missing.txt"); !errors.Is(err, common.ErrNotFound) {
+		t.Errorf("Delete() of a missing object: %v", err)
+	}
+
+	// Nothing is purged during the grace period.
+	if n, err := s.Purge(ctx); n != 0 || err != nil {
+		t.Errorf("Purge() during grace = %d, %v", n, err)
+	}
+	clock.Advance(time.Hour)
+	if n, err := s.Purge(ctx); n != 1 || err != nil {
+		t.Errorf("Purge() = %d, %v", n, err)
+	}
+	if exists, _ := backend.Exists(ctx, "docs/a.txt"); exists {
+		t.Error("object not removed after its grace period")
+	}
+	list, _ := s.Manager().List(ctx)
+	if len(list) != 1 || !list[0].Purged {
+		t.Errorf("tombstone after purge = %+v", list)
+	}
+
+	// The tombstone outlives the object for its retention.
+	clock.Advance(24 * time.Hour)
+	if n, err := s.Purge(ctx); n != 0 || err != nil {
+		t.Errorf("Purge() after retention = %d, %v", n, err)
+	}
+	if list, _ := s.Manager().List(ctx); len(list) != 0 {
+		t.Errorf("tombstones after retention = %+v", list)
+	}
+}
+
+func TestWriteReplacesDeletedObject(t *testing.T) {
+	backend := memory.New()
+	s, clock := newTestStorage(t, backend, nil)
+	ctx := context.Background()
+	put(t, s, "a.txt", "old")
+	if err := s.DeleteWithContext(ctx, "a.txt"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	deletedAt := clock.Now()
+	clock.Advance(time.Minute)
+
+	// A copy modified before the delete, as from a lagging replica, is
+	// refused.
+	stale := &common.Metadata{LastModified: deletedAt.Add(-time.Minute)}
+	if err := s.PutWithMetadata(ctx, "a.txt", strings.NewReader("old"), stale); !errors.Is(err, ErrStaleWrite) {
+		t.Errorf("stale PutWithMetadata(): %v", err)
+	}
+	if _, err := s.GetWithContext(ctx, "a.txt"); !errors.Is(err, common.ErrNotFound) {
+		t.Errorf("Get() after a stale write: %v", err)
+	}
+
+	put(t, s, "a.txt", "new")
+	if got := readObject(t, s, "a.txt"); got != "new" {
+		t.Errorf("Get() = %q, want new", got)
+	}
+	if t2, _ := s.Manager().Lookup(ctx, "a.txt"); t2 != nil {
+		t.Errorf("tombstone not cleared: %+v", t2)
+	}
+
+	// Appending to a deleted object starts a new one.
+	if err := s.DeleteWithContext(ctx, "a.txt"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := s.Append(ctx, "a.txt", strings.NewReader("fresh")); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	if got := readObject(t, s, "a.txt"); got != "fresh" {
+		t.Errorf("Get() after Append = %q, want fresh", got)
+	}
+}
+
+func TestReservedNamespace(t *testing.T) {
+	s, _ := newTestStorage(t, memory.New(), nil)
+	ctx := context.Background()
+	put(t, s, "a.txt", "a")
+	if err := s.DeleteWithContext(ctx, "a.txt"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	if _, err := s.GetWithContext(ctx, DefaultPrefix+"a.txt"); !errors.Is(err, common.ErrReservedKey) {
+		t.Errorf("Get() of a tombstone: %v", err)
+	}
+	if err := s.PutWithContext(ctx, DefaultPrefix+"b.txt", strings.NewReader("x")); !errors.Is(err, common.ErrReservedKey) {
+		t.Errorf("Put() of a tombstone: %v", err)
+	}
+	if err := s.DeleteWithContext(ctx, DefaultPrefix+"a.txt"); !errors.Is(err, common.ErrReservedKey) {
+		t.Errorf("Delete() of a tombstone: %v", err)
+	}
+	keys, err := s.ListWithContext(ctx, "")
+	if err != nil || len(keys) != 0 {
+		t.Errorf("List() = %v, %v", keys, err)
+	}
+}
+
+func TestSharedAndReloaded(t *testing.T) {
+	backend := memory.New()
+	ctx := context.Background()
+	a, _ := newTestStorage(t, backend, &Config{Shared: true})
+	b, _ := newTestStorage(t, backend, &Config{Shared: true})
+	put(t, a, "a.txt", "a")
+
+	// A delete through one instance is seen by the other.
+	if err := a.DeleteWithContext(ctx, "a.txt"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if exists, _ := b.Exists(ctx, "a.txt"); exists {
+		t.Error("shared tombstone not seen by another instance")
+	}
+	if keys, _ := b.ListWithContext(ctx, ""); len(keys) != 0 {
+		t.Errorf("List() on another instance = %v", keys)
+	}
+
+	// An unshared manager loads the tombstones when it is created.
+	c, _ := newTestStorage(t, backend, nil)
+	if exists, _ := c.Exists(ctx, "a.txt"); exists {
+		t.Error("tombstone not loaded")
+	}
+	loaded, err := Load(ctx, backend, DefaultPrefix)
+	if err != nil || loaded["a.txt"] == nil {
+		t.Errorf("Load() = %v, %v", loaded, err)
+	}
+}
+
+func TestPurgeSkipsReplacedObject(t *testing.T) {
+	backend := memory.New()
+	s, clock := newTestStorage(t, backend, &Config{Grace: time.Hour})
+	ctx := context.Background()
+	put(t, s, "a.txt", "old")
+	if err := s.DeleteWithContext(ctx, "a.txt"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	clock.Advance(time.Hour)
+
+	// A write in progress keeps Purge away from the key.
+	done := s.Manager().beginWrite("a.txt")
+	if n, err := s.Purge(ctx); n != 0 || err != nil {
+		t.Errorf("Purge() during a write = %d, %v", n, err)
+	}
+	done()
+	if exists, _ := backend.Exists(ctx, "a.txt"); !exists {
+		t.Error("object removed while being replaced")
+	}
+}
+
+func TestPrefixFromSettings(t *testing.T) {
+	if got := PrefixFromSettings(nil); got != DefaultPrefix {
+		t.Errorf("PrefixFromSettings(nil) = %q", got)
+	}
+	if got := PrefixFromSettings(map[string]string{SettingPrefix: ".ha/tombstones"}); got != ".ha/tombstones/" {
+		t.Errorf("PrefixFromSettings() = %q", got)
+	}
+}
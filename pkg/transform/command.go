@@ -0,0 +1,162 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package transform
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+func init() {
+	RegisterProcessor("command", newCommandProcessor)
+}
+
+// Command processor defaults.
+const (
+	defaultCommandTimeout = time.Minute
+	maxCommandStderr      = 4096
+)
+
+// commandProcessor runs an external program, such as pdftoppm or ffmpeg,
+// on a temporary copy of the source. The program writes the derived object
+// to standard output, or to the file named by {output} if the command
+// contains it.
+//
+// Settings:
+//   - command: the program and its arguments, separated by spaces; {input}
+//     is replaced by the path of the source copy and {output} by the path
+//     the program should write to (required)
+//   - content_type: the content type of the derived object (required)
+//   - timeout: how long the program may run (default 1m)
+type commandProcessor struct {
+	args        []string
+	contentType string
+	timeout     time.Duration
+}
+
+func newCommandProcessor(settings map[string]string) (Processor, error) {
+	args := strings.Fields(settings["command"])
+	if len(args) == 0 {
+		return nil, fmt.Errorf("%w: command processor requires a command", ErrInvalidConfig)
+	}
+	if !strings.Contains(settings["command"], "{input}") {
+		return nil, fmt.Errorf("%w: command must reference {input}", ErrInvalidConfig)
+	}
+	p := &commandProcessor{
+		args:        args,
+		contentType: settings["content_type"],
+		timeout:     defaultCommandTimeout,
+	}
+	if p.contentType == "" {
+		return nil, fmt.Errorf("%w: command processor requires a content_type", ErrInvalidConfig)
+	}
+	if timeout := settings["timeout"]; timeout != "" {
+		d, err := time.ParseDuration(timeout)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("%w: invalid command timeout %q", ErrInvalidConfig, timeout)
+		}
+		p.timeout = d
+	}
+	return p, nil
+}
+
+// Process implements Processor.
+func (p *commandProcessor) Process(ctx context.Context, src io.Reader, dst io.Writer) (string, error) {
+	dir, err := os.MkdirTemp("", "objstore-transform-")
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	input := filepath.Join(dir, "input")
+	output := filepath.Join(dir, "output")
+	if err := writeFile(input, src); err != nil {
+		return "", err
+	}
+
+	usesOutput := false
+	args := make([]string, len(p.args))
+	for i, arg := range p.args {
+		if strings.Contains(arg, "{output}") {
+			usesOutput = true
+		}
+		args[i] = strings.NewReplacer("{input}", input, "{output}", output).Replace(arg)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	// #nosec G204 -- the command is configured by the operator
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = dir
+	stderr := &limitedBuffer{max: maxCommandStderr}
+	cmd.Stderr = stderr
+	if !usesOutput {
+		cmd.Stdout = dst
+	}
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("%s: %w", args[0], ctx.Err())
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s: %w: %s", args[0], err, msg)
+		}
+		return "", fmt.Errorf("%s: %w", args[0], err)
+	}
+
+	if usesOutput {
+		f, err := os.Open(output) // #nosec G304 -- path in our temporary directory
+		if err != nil {
+			return "", fmt.Errorf("%s did not write {output}: %w", args[0], err)
+		}
+		defer func() { _ = f.Close() }()
+		if _, err := io.Copy(dst, f); err != nil {
+			return "", err
+		}
+	}
+	return p.contentType, nil
+}
+
+func writeFile(name string, src io.Reader) error {
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0o600) // #nosec G304 -- path in our temporary directory
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, src); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// limitedBuffer keeps the first max bytes written to it and discards the
+// rest.
+type limitedBuffer struct {
+	bytes.Buffer
+	max int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.max - b.Len(); room > 0 {
+		b.Buffer.Write(p[:min(room, len(p))])
+	}
+	return len(p), nil
+}
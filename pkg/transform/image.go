@@ -0,0 +1,193 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package transform
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif" // registers the GIF decoder with image.Decode
+	"image/jpeg"
+	"image/png"
+	"io"
+	"strconv"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+func init() {
+	RegisterProcessor("image", newImageProcessor)
+}
+
+// Image processor defaults.
+const (
+	defaultImageQuality = 80
+	maxImageDimension   = 8192
+
+	// maxImagePixels bounds the decoded size of a source image, so a small
+	// file claiming huge dimensions cannot exhaust memory.
+	maxImagePixels = 100_000_000
+)
+
+// imageProcessor scales JPEG, PNG and GIF images to fit within a bounding
+// box, preserving their aspect ratio. Images are never enlarged.
+//
+// Settings:
+//   - width, height: the bounding box in pixels; at least one is required
+//   - format: "jpeg" (default) or "png"
+//   - quality: JPEG quality from 1 to 100 (default 80)
+type imageProcessor struct {
+	width   int
+	height  int
+	format  string
+	quality int
+}
+
+func newImageProcessor(settings map[string]string) (Processor, error) {
+	p := &imageProcessor{format: "jpeg", quality: defaultImageQuality}
+	var err error
+	if p.width, err = dimensionSetting(settings, "width"); err != nil {
+		return nil, err
+	}
+	if p.height, err = dimensionSetting(settings, "height"); err != nil {
+		return nil, err
+	}
+	if p.width == 0 && p.height == 0 {
+		return nil, fmt.Errorf("%w: image processor requires width or height", ErrInvalidConfig)
+	}
+	if format := settings["format"]; format != "" {
+		if format == "jpg" {
+			format = "jpeg"
+		}
+		if format != "jpeg" && format != "png" {
+			return nil, fmt.Errorf("%w: image format must be jpeg or png, got %q", ErrInvalidConfig, format)
+		}
+		p.format = format
+	}
+	if quality := settings["quality"]; quality != "" {
+		q, err := strconv.Atoi(quality)
+		if err != nil || q < 1 || q > 100 {
+			return nil, fmt.Errorf("%w: image quality must be 1 to 100, got %q", ErrInvalidConfig, quality)
+		}
+		p.quality = q
+	}
+	return p, nil
+}
+
+func dimensionSetting(settings map[string]string, name string) (int, error) {
+	value := settings[name]
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 || n > maxImageDimension {
+		return 0, fmt.Errorf("%w: image %s must be 1 to %d, got %q", ErrInvalidConfig, name, maxImageDimension, value)
+	}
+	return n, nil
+}
+
+// Process implements Processor.
+func (p *imageProcessor) Process(ctx context.Context, src io.Reader, dst io.Writer) (string, error) {
+	data, err := io.ReadAll(src)
+	if err != nil {
+		return "", err
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", common.ErrInvalidArgument, err)
+	}
+	if int64(config.Width)*int64(config.Height) > maxImagePixels {
+		return "", fmt.Errorf("%w: image is %dx%d pixels", ErrSourceTooLarge, config.Width, config.Height)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", common.ErrInvalidArgument, err)
+	}
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	scaled := scale(img, p.width, p.height)
+	if p.format == "png" {
+		return "image/png", png.Encode(dst, scaled)
+	}
+	return "image/jpeg", jpeg.Encode(dst, flatten(scaled), &jpeg.Options{Quality: p.quality})
+}
+
+// fitWithin returns the size of a w×h image scaled down to fit within a
+// maxW×maxH box, where zero leaves that dimension unbounded.
+func fitWithin(w, h, maxW, maxH int) (int, int) {
+	ratio := 1.0
+	if maxW > 0 && w > maxW {
+		ratio = float64(maxW) / float64(w)
+	}
+	if maxH > 0 && h > maxH {
+		ratio = min(ratio, float64(maxH)/float64(h))
+	}
+	return max(1, int(float64(w)*ratio+0.5)), max(1, int(float64(h)*ratio+0.5))
+}
+
+// scale shrinks img to fit within maxW×maxH by averaging the source pixels
+// covered by each destination pixel.
+func scale(img image.Image, maxW, maxH int) *image.NRGBA {
+	bounds := img.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	dstW, dstH := fitWithin(srcW, srcH, maxW, maxH)
+
+	src := image.NewNRGBA(image.Rect(0, 0, srcW, srcH))
+	draw.Draw(src, src.Bounds(), img, bounds.Min, draw.Src)
+	if dstW == srcW && dstH == srcH {
+		return src
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, dstW, dstH))
+	for y := 0; y < dstH; y++ {
+		y0, y1 := y*srcH/dstH, max((y+1)*srcH/dstH, y*srcH/dstH+1)
+		for x := 0; x < dstW; x++ {
+			x0, x1 := x*srcW/dstW, max((x+1)*srcW/dstW, x*srcW/dstW+1)
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					px := row[sx*4 : sx*4+4]
+					alpha := uint64(px[3])
+					r += uint64(px[0]) * alpha
+					g += uint64(px[1]) * alpha
+					b += uint64(px[2]) * alpha
+					a += alpha
+					n++
+				}
+			}
+			i := dst.PixOffset(x, y)
+			if a > 0 {
+				dst.Pix[i] = uint8(r / a)
+				dst.Pix[i+1] = uint8(g / a)
+				dst.Pix[i+2] = uint8(b / a)
+			}
+			dst.Pix[i+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
+
+// flatten composites img onto white, since JPEG has no alpha channel.
+func flatten(img *image.NRGBA) image.Image {
+	out := image.NewRGBA(img.Bounds())
+	draw.Draw(out, out.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(out, out.Bounds(), img, img.Bounds().Min, draw.Over)
+	return out
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package transform
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// Storage wraps a backend to maintain a Transformer's derived objects:
+// writing a source generates its on_put presets, and deleting it removes
+// its derived objects. Writes and deletes of keys under the derived object
+// prefix fail with common.ErrReservedKey, so derived objects only change through
+// the Transformer. They stay readable and listable as plain objects.
+type Storage struct {
+	common.Storage
+	transformer *Transformer
+}
+
+// NewStorage returns underlying wrapped so that transformer's derived
+// objects follow their sources.
+func NewStorage(underlying common.Storage, transformer *Transformer) *Storage {
+	return &Storage{Storage: underlying, transformer: transformer}
+}
+
+// Transformer returns the transformer whose derived objects s maintains.
+func (s *Storage) Transformer() *Transformer {
+	return s.transformer
+}
+
+// Underlying returns the wrapped backend.
+func (s *Storage) Underlying() common.Storage {
+	return s.Storage
+}
+
+func (s *Storage) check(key string) error {
+	if s.transformer.Reserved(key) {
+		return fmt.Errorf("%w: %s", common.ErrReservedKey, key)
+	}
+	return nil
+}
+
+// written generates the on_put derived objects of key once a write
+// succeeded.
+func (s *Storage) written(ctx context.Context, key string, err error) error {
+	if err == nil {
+		s.transformer.generateOnPut(ctx, key)
+	}
+	return err
+}
+
+// Put stores an object outside the derived object namespace.
+func (s *Storage) Put(key string, data io.Reader) error {
+	return s.PutWithContext(context.Background(), key, data)
+}
+
+// PutWithContext stores an object outside the derived object namespace.
+func (s *Storage) PutWithContext(ctx context.Context, key string, data io.Reader) error {
+	if err := s.check(key); err != nil {
+		return err
+	}
+	return s.written(ctx, key, s.Storage.PutWithContext(ctx, key, data))
+}
+
+// PutWithMetadata stores an object with metadata outside the derived
+// object namespace.
+func (s *Storage) PutWithMetadata(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) error {
+	if err := s.check(key); err != nil {
+		return err
+	}
+	return s.written(ctx, key, s.Storage.PutWithMetadata(ctx, key, data, metadata))
+}
+
+// PutWithInfo stores an object with metadata outside the derived object
+// namespace and returns what was stored.
+func (s *Storage) PutWithInfo(ctx context.Context, key string, data io.Reader, metadata *common.Metadata) (*common.ObjectInfo, error) {
+	if err := s.check(key); err != nil {
+		return nil, err
+	}
+	info, err := common.PutWithInfo(ctx, s.Storage, key, data, metadata)
+	if err := s.written(ctx, key, err); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// GetRange reads a byte range of an object, falling back to discarding
+// the leading bytes of a full read when the wrapped backend cannot read
+// ranges.
+func (s *Storage) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	if rr, ok := s.Storage.(common.RangeReader); ok {
+		return rr.GetRange(ctx, key, offset, length)
+	}
+	rc, err := s.Storage.GetWithContext(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return common.SliceRange(rc, offset, length)
+}
+
+// GetAsOf reads an earlier version of an object from the wrapped backend.
+func (s *Storage) GetAsOf(ctx context.Context, key string, at time.Time) (io.ReadCloser, *common.Metadata, error) {
+	return common.GetAsOf(ctx, s.Storage, key, at)
+}
+
+// UpdateMetadata updates the metadata of an object outside the derived
+// object namespace.
+func (s *Storage) UpdateMetadata(ctx context.Context, key string, metadata *common.Metadata) error {
+	if err := s.check(key); err != nil {
+		return err
+	}
+	return s.Storage.UpdateMetadata(ctx, key, metadata)
+}
+
+// Delete removes an object outside the derived object namespace and its
+// derived objects.
+func (s *Storage) Delete(key string) error {
+	return s.DeleteWithContext(context.Background(), key)
+}
+
+// DeleteWithContext removes an object outside the derived object namespace
+// and its derived objects.
+func (s *Storage) DeleteWithContext(ctx context.Context, key string) error {
+	if err := s.check(key); err != nil {
+		return err
+	}
+	if err := s.Storage.DeleteWithContext(ctx, key); err != nil {
+		return err
+	}
+	s.transformer.removeDerived(ctx, key)
+	return nil
+}
+
+// Append adds data to the end of an object outside the derived object
+// namespace.
+func (s *Storage) Append(ctx context.Context, key string, data io.Reader) error {
+	if err := s.check(key); err != nil {
+		return err
+	}
+	return s.written(ctx, key, common.Append(ctx, s.Storage, key, data))
+}
+
+// Compose concatenates srcKeys into destKey, which must be outside the
+// derived object namespace.
+func (s *Storage) Compose(ctx context.Context, destKey string, srcKeys ...string) error {
+	if err := s.check(destKey); err != nil {
+		return err
+	}
+	return s.written(ctx, destKey, common.Compose(ctx, s.Storage, destKey, srcKeys...))
+}
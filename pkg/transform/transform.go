@@ -0,0 +1,466 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package transform generates derived objects, such as thumbnails, from
+// stored objects. A preset names a processor and its settings; derived
+// objects are generated on demand, or eagerly when their source is written,
+// and cached in the backend under a reserved prefix (DefaultPrefix). A
+// cached derived object records the ETag of the source it was generated
+// from and is regenerated once the source changes.
+//
+// Two processors are built in: "image" resizes JPEG, PNG and GIF images,
+// and "command" runs an external program such as pdftoppm or ffmpeg.
+// Others can be added with RegisterProcessor.
+package transform
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// DefaultPrefix is the key prefix derived objects are stored under when
+// none is configured.
+const DefaultPrefix = ".derived/"
+
+// DefaultMaxSourceSize bounds the size of objects a preset is applied to
+// when none is configured.
+const DefaultMaxSourceSize = 100 << 20
+
+// Custom metadata recorded on derived objects.
+const (
+	MetadataSourceKey  = "source-key"
+	MetadataSourceETag = "source-etag"
+	MetadataPreset     = "preset"
+)
+
+var (
+	// ErrInvalidConfig is returned for a malformed transform configuration.
+	ErrInvalidConfig = fmt.Errorf("%w: invalid transform configuration", common.ErrInvalidArgument)
+
+	// ErrUnknownProcessor is returned for a preset naming a processor that
+	// is not registered.
+	ErrUnknownProcessor = fmt.Errorf("%w: unknown processor", common.ErrInvalidArgument)
+
+	// ErrPresetNotFound is returned for a preset that is not configured.
+	ErrPresetNotFound = fmt.Errorf("transform preset %w", common.ErrNotFound)
+
+	// ErrNotApplicable is returned when a preset's content types or prefix
+	// do not match the source object.
+	ErrNotApplicable = fmt.Errorf("%w: preset does not apply to this object", common.ErrInvalidArgument)
+
+	// ErrSourceTooLarge is returned for a source larger than the configured
+	// maximum.
+	ErrSourceTooLarge = fmt.Errorf("%w: source object too large to transform", common.ErrInvalidArgument)
+)
+
+var validPresetName = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// Processor generates a derived object from a source object.
+type Processor interface {
+	// Process reads the source from src, writes the derived object to dst
+	// and returns its content type.
+	Process(ctx context.Context, src io.Reader, dst io.Writer) (contentType string, err error)
+}
+
+// ProcessorCreator creates a processor from its settings.
+type ProcessorCreator func(settings map[string]string) (Processor, error)
+
+var (
+	processorRegistryMu sync.RWMutex
+	processorRegistry   = make(map[string]ProcessorCreator)
+)
+
+// RegisterProcessor registers a processor type.
+func RegisterProcessor(processorType string, creator ProcessorCreator) {
+	processorRegistryMu.Lock()
+	defer processorRegistryMu.Unlock()
+	processorRegistry[processorType] = creator
+}
+
+// NewProcessor creates a processor of a registered type.
+func NewProcessor(processorType string, settings map[string]string) (Processor, error) {
+	processorRegistryMu.RLock()
+	creator, ok := processorRegistry[processorType]
+	processorRegistryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownProcessor, processorType)
+	}
+	return creator(settings)
+}
+
+// Preset is a named transformation.
+type Preset struct {
+	// Name addresses the preset, as in key?preset=thumb.
+	Name string `yaml:"name" json:"name"`
+
+	// Processor is the registered processor type, such as "image".
+	Processor string `yaml:"processor" json:"processor"`
+
+	// Settings configure the processor, such as "width" for "image".
+	Settings map[string]string `yaml:"settings,omitempty" json:"settings,omitempty"`
+
+	// ContentTypes restricts the preset to sources whose content type
+	// matches one of these patterns, such as "image/*". Empty matches any
+	// content type.
+	ContentTypes []string `yaml:"content_types,omitempty" json:"content_types,omitempty"`
+
+	// Prefix restricts the preset to keys with this prefix.
+	Prefix string `yaml:"prefix,omitempty" json:"prefix,omitempty"`
+
+	// OnPut generates the derived object when its source is written instead
+	// of on first request.
+	OnPut bool `yaml:"on_put,omitempty" json:"on_put,omitempty"`
+}
+
+// applies reports whether the preset applies to a source object.
+func (p *Preset) applies(key, contentType string) bool {
+	if !strings.HasPrefix(key, p.Prefix) {
+		return false
+	}
+	if len(p.ContentTypes) == 0 {
+		return true
+	}
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	for _, pattern := range p.ContentTypes {
+		if ok, _ := path.Match(strings.ToLower(pattern), mediaType); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Config configures a Transformer.
+type Config struct {
+	// Prefix is the key prefix derived objects are stored under
+	// (DefaultPrefix if empty).
+	Prefix string `yaml:"prefix,omitempty" json:"prefix,omitempty"`
+
+	// MaxSourceSize bounds the size in bytes of sources presets are applied
+	// to (DefaultMaxSourceSize if zero).
+	MaxSourceSize int64 `yaml:"max_source_size,omitempty" json:"max_source_size,omitempty"`
+
+	Presets []Preset `yaml:"presets" json:"presets"`
+
+	// OnError is called when generating a derived object on put fails.
+	// The put itself succeeds.
+	OnError func(preset, key string, err error) `yaml:"-" json:"-"`
+}
+
+// LoadFile reads a transform configuration from a YAML or JSON file.
+func LoadFile(filename string) (*Config, error) {
+	data, err := os.ReadFile(filename) // #nosec G304 -- path supplied by the operator
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidConfig, filename, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// Validate checks that presets have unique, well-formed names and a
+// processor, and that content type patterns are well formed.
+func (c *Config) Validate() error {
+	if c.MaxSourceSize < 0 {
+		return fmt.Errorf("%w: max_source_size must not be negative", ErrInvalidConfig)
+	}
+	if c.Prefix != "" && (strings.HasPrefix(c.Prefix, "/") || !strings.HasSuffix(c.Prefix, "/")) {
+		return fmt.Errorf("%w: prefix %q must be relative and end with '/'", ErrInvalidConfig, c.Prefix)
+	}
+	seen := make(map[string]bool, len(c.Presets))
+	for _, preset := range c.Presets {
+		if !validPresetName.MatchString(preset.Name) {
+			return fmt.Errorf("%w: preset name %q must be 1 to 64 letters, digits, '.', '_' or '-'", ErrInvalidConfig, preset.Name)
+		}
+		if seen[preset.Name] {
+			return fmt.Errorf("%w: duplicate preset %q", ErrInvalidConfig, preset.Name)
+		}
+		seen[preset.Name] = true
+		if preset.Processor == "" {
+			return fmt.Errorf("%w: preset %q has no processor", ErrInvalidConfig, preset.Name)
+		}
+		for _, pattern := range preset.ContentTypes {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("%w: preset %q: bad content type pattern %q", ErrInvalidConfig, preset.Name, pattern)
+			}
+		}
+	}
+	return nil
+}
+
+type preset struct {
+	Preset
+	processor Processor
+}
+
+// Transformer generates and caches derived objects of a backend.
+type Transformer struct {
+	storage       common.Storage
+	prefix        string
+	maxSourceSize int64
+	presets       map[string]*preset
+	onError       func(preset, key string, err error)
+
+	mu       sync.Mutex
+	inflight map[string]*generation
+}
+
+// generation is an in-progress generation of one derived object that
+// concurrent requests wait on.
+type generation struct {
+	done chan struct{}
+	err  error
+}
+
+// NewTransformer returns a Transformer that reads sources from and stores
+// derived objects in storage.
+func NewTransformer(storage common.Storage, cfg *Config) (*Transformer, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("%w: nil configuration", ErrInvalidConfig)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	t := &Transformer{
+		storage:       storage,
+		prefix:        cfg.Prefix,
+		maxSourceSize: cfg.MaxSourceSize,
+		presets:       make(map[string]*preset, len(cfg.Presets)),
+		onError:       cfg.OnError,
+		inflight:      make(map[string]*generation),
+	}
+	if t.prefix == "" {
+		t.prefix = DefaultPrefix
+	}
+	if t.maxSourceSize == 0 {
+		t.maxSourceSize = DefaultMaxSourceSize
+	}
+	for _, p := range cfg.Presets {
+		processor, err := NewProcessor(p.Processor, p.Settings)
+		if err != nil {
+			return nil, fmt.Errorf("preset %q: %w", p.Name, err)
+		}
+		t.presets[p.Name] = &preset{Preset: p, processor: processor}
+	}
+	return t, nil
+}
+
+// Presets returns the configured presets sorted by name.
+func (t *Transformer) Presets() []Preset {
+	presets := make([]Preset, 0, len(t.presets))
+	for _, p := range t.presets {
+		presets = append(presets, p.Preset)
+	}
+	sort.Slice(presets, func(i, j int) bool { return presets[i].Name < presets[j].Name })
+	return presets
+}
+
+// Reserved reports whether key is in the derived object namespace.
+func (t *Transformer) Reserved(key string) bool {
+	return strings.HasPrefix(key, t.prefix)
+}
+
+// DerivedKey returns the key the derived object of key for preset is
+// cached under.
+func (t *Transformer) DerivedKey(presetName, key string) string {
+	return t.prefix + presetName + "/" + key
+}
+
+// Get returns the derived object of key for a preset, generating it if it
+// is not cached or was generated from an earlier version of the source.
+func (t *Transformer) Get(ctx context.Context, presetName, key string) (io.ReadCloser, *common.Metadata, error) {
+	p, ok := t.presets[presetName]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %s", ErrPresetNotFound, presetName)
+	}
+	if t.Reserved(key) {
+		return nil, nil, fmt.Errorf("%w: %s", ErrNotApplicable, key)
+	}
+	source, err := t.storage.GetMetadata(ctx, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !p.applies(key, source.ContentType) {
+		return nil, nil, fmt.Errorf("%w: %s", ErrNotApplicable, key)
+	}
+
+	derivedKey := t.DerivedKey(presetName, key)
+	if rc, metadata, ok := t.cached(ctx, derivedKey, source); ok {
+		return rc, metadata, nil
+	}
+	if err := t.generate(ctx, p, key, source); err != nil {
+		return nil, nil, err
+	}
+	metadata, err := t.storage.GetMetadata(ctx, derivedKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	rc, err := t.storage.GetWithContext(ctx, derivedKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return rc, metadata, nil
+}
+
+// cached opens the derived object at derivedKey if it was generated from
+// the current version of source.
+func (t *Transformer) cached(ctx context.Context, derivedKey string, source *common.Metadata) (io.ReadCloser, *common.Metadata, bool) {
+	metadata, err := t.storage.GetMetadata(ctx, derivedKey)
+	if err != nil || metadata.Custom[MetadataSourceETag] != sourceVersion(source) {
+		return nil, nil, false
+	}
+	rc, err := t.storage.GetWithContext(ctx, derivedKey)
+	if err != nil {
+		return nil, nil, false
+	}
+	return rc, metadata, true
+}
+
+// Generate (re)generates the derived object of key for a preset.
+func (t *Transformer) Generate(ctx context.Context, presetName, key string) error {
+	p, ok := t.presets[presetName]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrPresetNotFound, presetName)
+	}
+	source, err := t.storage.GetMetadata(ctx, key)
+	if err != nil {
+		return err
+	}
+	if !p.applies(key, source.ContentType) {
+		return fmt.Errorf("%w: %s", ErrNotApplicable, key)
+	}
+	return t.generate(ctx, p, key, source)
+}
+
+// generate runs p on key and stores the result. Concurrent generations of
+// the same derived object share one run.
+func (t *Transformer) generate(ctx context.Context, p *preset, key string, source *common.Metadata) error {
+	derivedKey := t.DerivedKey(p.Name, key)
+
+	t.mu.Lock()
+	if g, ok := t.inflight[derivedKey]; ok {
+		t.mu.Unlock()
+		select {
+		case <-g.done:
+			return g.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	g := &generation{done: make(chan struct{})}
+	t.inflight[derivedKey] = g
+	t.mu.Unlock()
+
+	g.err = t.run(ctx, p, key, derivedKey, source)
+
+	t.mu.Lock()
+	delete(t.inflight, derivedKey)
+	t.mu.Unlock()
+	close(g.done)
+	return g.err
+}
+
+func (t *Transformer) run(ctx context.Context, p *preset, key, derivedKey string, source *common.Metadata) error {
+	if source.Size > t.maxSourceSize {
+		return fmt.Errorf("%w: %s is %d bytes", ErrSourceTooLarge, key, source.Size)
+	}
+	rc, err := t.storage.GetWithContext(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rc.Close() }()
+
+	var out bytes.Buffer
+	contentType, err := p.processor.Process(ctx, io.LimitReader(rc, t.maxSourceSize), &out)
+	if err != nil {
+		return fmt.Errorf("preset %q failed on %s: %w", p.Name, key, err)
+	}
+	return t.storage.PutWithMetadata(ctx, derivedKey, &out, &common.Metadata{
+		ContentType: contentType,
+		Size:        int64(out.Len()),
+		Custom: map[string]string{
+			MetadataSourceKey:  key,
+			MetadataSourceETag: sourceVersion(source),
+			MetadataPreset:     p.Name,
+		},
+	})
+}
+
+// generateOnPut generates the derived objects of the presets that apply to
+// key and are marked on_put. Failures are reported to OnError.
+func (t *Transformer) generateOnPut(ctx context.Context, key string) {
+	var source *common.Metadata
+	for _, p := range t.presets {
+		if !p.OnPut || !strings.HasPrefix(key, p.Prefix) {
+			continue
+		}
+		if source == nil {
+			var err error
+			if source, err = t.storage.GetMetadata(ctx, key); err != nil {
+				t.reportError(p.Name, key, err)
+				return
+			}
+		}
+		if !p.applies(key, source.ContentType) {
+			continue
+		}
+		if err := t.generate(ctx, p, key, source); err != nil {
+			t.reportError(p.Name, key, err)
+		}
+	}
+}
+
+// removeDerived deletes the derived objects of key.
+func (t *Transformer) removeDerived(ctx context.Context, key string) {
+	for name := range t.presets {
+		err := t.storage.DeleteWithContext(ctx, t.DerivedKey(name, key))
+		if err != nil && !errors.Is(err, common.ErrNotFound) {
+			t.reportError(name, key, err)
+		}
+	}
+}
+
+func (t *Transformer) reportError(presetName, key string, err error) {
+	if t.onError != nil {
+		t.onError(presetName, key, err)
+	}
+}
+
+// sourceVersion identifies the version of a source object a derived object
+// was generated from: its ETag, or its size and modification time on
+// backends that report no ETag.
+func sourceVersion(metadata *common.Metadata) string {
+	if metadata.ETag != "" {
+		return strings.Trim(metadata.ETag, `"`)
+	}
+	return strconv.FormatInt(metadata.Size, 10) + "-" + strconv.FormatInt(metadata.LastModified.UnixNano(), 36)
+}
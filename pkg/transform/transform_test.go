@@ -0,0 +1,332 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package transform
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+func pngImage(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 200, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// countingProcessor upper-cases its input and counts its runs.
+type countingProcessor struct {
+	runs atomic.Int32
+}
+
+func (p *countingProcessor) Process(_ context.Context, src io.Reader, dst io.Writer) (string, error) {
+	p.runs.Add(1)
+	data, err := io.ReadAll(src)
+	if err != nil {
+		return "", err
+	}
+	_, err = dst.Write(bytes.ToUpper(data))
+	return "text/plain", err
+}
+
+func newCountingStorage(t *testing.T, presets ...Preset) (*Storage, *countingProcessor) {
+	t.Helper()
+	counter := &countingProcessor{}
+	RegisterProcessor("counting-"+t.Name(), func(map[string]string) (Processor, error) {
+		return counter, nil
+	})
+	for i := range presets {
+		presets[i].Processor = "counting-" + t.Name()
+	}
+	transformer, err := NewTransformer(memory.New(), &Config{Presets: presets})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewStorage(transformer.storage, transformer), counter
+}
+
+func readAll(t *testing.T, rc io.ReadCloser) string {
+	t.Helper()
+	defer func() { _ = rc.Close() }()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestTransformer_GetCachesUntilSourceChanges(t *testing.T) {
+	ctx := context.Background()
+	s, counter := newCountingStorage(t, Preset{Name: "upper"})
+	transformer := s.Transformer()
+
+	if err := s.PutWithContext(ctx, "notes.txt", strings.NewReader("hello")); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		rc, metadata, err := transformer.Get(ctx, "upper", "notes.txt")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := readAll(t, rc); got != "HELLO" {
+			t.Errorf("derived = %q", got)
+		}
+		if metadata.ContentType != "text/plain" || metadata.Custom[MetadataSourceKey] != "notes.txt" {
+			t.Errorf("metadata = %+v", metadata)
+		}
+	}
+	if runs := counter.runs.Load(); runs != 1 {
+		t.Errorf("processor ran %d times, want 1", runs)
+	}
+
+	if err := s.PutWithContext(ctx, "notes.txt", strings.NewReader("hello, world")); err != nil {
+		t.Fatal(err)
+	}
+	rc, _, err := transformer.Get(ctx, "upper", "notes.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := readAll(t, rc); got != "HELLO, WORLD" {
+		t.Errorf("derived after change = %q", got)
+	}
+
+	if _, _, err := transformer.Get(ctx, "missing", "notes.txt"); !errors.Is(err, ErrPresetNotFound) || !errors.Is(err, common.ErrNotFound) {
+		t.Errorf("unknown preset: got %v", err)
+	}
+	if _, _, err := transformer.Get(ctx, "upper", "absent.txt"); !errors.Is(err, common.ErrNotFound) {
+		t.Errorf("missing source: got %v", err)
+	}
+}
+
+func TestStorage_OnPutDeleteAndReservedKeys(t *testing.T) {
+	ctx := context.Background()
+	s, counter := newCountingStorage(t,
+		Preset{Name: "eager", OnPut: true, Prefix: "docs/"},
+		Preset{Name: "lazy"},
+	)
+	transformer := s.Transformer()
+
+	if err := s.PutWithContext(ctx, "docs/a.txt", strings.NewReader("a")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.PutWithContext(ctx, "other/b.txt", strings.NewReader("b")); err != nil {
+		t.Fatal(err)
+	}
+	if runs := counter.runs.Load(); runs != 1 {
+		t.Errorf("on_put ran %d times, want 1", runs)
+	}
+	derivedKey := transformer.DerivedKey("eager", "docs/a.txt")
+	if derivedKey != ".derived/eager/docs/a.txt" {
+		t.Errorf("DerivedKey = %q", derivedKey)
+	}
+	if exists, _ := s.Exists(ctx, derivedKey); !exists {
+		t.Fatal("on_put derived object was not stored")
+	}
+
+	if _, _, err := transformer.Get(ctx, "eager", "other/b.txt"); !errors.Is(err, ErrNotApplicable) {
+		t.Errorf("preset outside its prefix: got %v", err)
+	}
+
+	for _, err := range []error{
+		s.PutWithContext(ctx, derivedKey, strings.NewReader("forged")),
+		s.DeleteWithContext(ctx, derivedKey),
+		s.UpdateMetadata(ctx, derivedKey, &common.Metadata{}),
+	} {
+		if !errors.Is(err, common.ErrReservedKey) || !errors.Is(err, common.ErrPermissionDenied) {
+			t.Errorf("write to reserved key: got %v", err)
+		}
+	}
+
+	if err := s.DeleteWithContext(ctx, "docs/a.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if exists, _ := s.Exists(ctx, derivedKey); exists {
+		t.Error("derived object survived its source")
+	}
+}
+
+func TestImageProcessor(t *testing.T) {
+	ctx := context.Background()
+	transformer, err := NewTransformer(memory.New(), &Config{Presets: []Preset{
+		{Name: "thumb", Processor: "image", ContentTypes: []string{"image/*"}, Settings: map[string]string{"width": "8"}},
+		{Name: "icon", Processor: "image", Settings: map[string]string{"width": "4", "height": "4", "format": "png"}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	storage := transformer.storage
+	if err := storage.PutWithMetadata(ctx, "photo.png", bytes.NewReader(pngImage(t, 32, 16)), &common.Metadata{ContentType: "image/png"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.PutWithMetadata(ctx, "readme.txt", strings.NewReader("text"), &common.Metadata{ContentType: "text/plain"}); err != nil {
+		t.Fatal(err)
+	}
+
+	rc, metadata, err := transformer.Get(ctx, "thumb", "photo.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := jpeg.Decode(strings.NewReader(readAll(t, rc)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metadata.ContentType != "image/jpeg" || img.Bounds().Dx() != 8 || img.Bounds().Dy() != 4 {
+		t.Errorf("thumb: %s %v", metadata.ContentType, img.Bounds())
+	}
+
+	rc, _, err = transformer.Get(ctx, "icon", "photo.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err = png.Decode(strings.NewReader(readAll(t, rc)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds().Dx() != 4 || img.Bounds().Dy() != 2 {
+		t.Errorf("icon bounds = %v, want 4x2", img.Bounds())
+	}
+
+	if _, _, err := transformer.Get(ctx, "thumb", "readme.txt"); !errors.Is(err, ErrNotApplicable) {
+		t.Errorf("text source: got %v, want ErrNotApplicable", err)
+	}
+	if _, _, err := transformer.Get(ctx, "icon", "readme.txt"); !errors.Is(err, common.ErrInvalidArgument) {
+		t.Errorf("undecodable source: got %v, want ErrInvalidArgument", err)
+	}
+}
+
+func TestFitWithin(t *testing.T) {
+	tests := []struct {
+		w, h, maxW, maxH, wantW, wantH int
+	}{
+		{1000, 500, 200, 0, 200, 100},
+		{1000, 500, 0, 100, 200, 100},
+		{1000, 500, 200, 200, 200, 100},
+		{100, 50, 200, 200, 100, 50},
+		{3000, 1, 100, 0, 100, 1},
+	}
+	for _, tt := range tests {
+		if w, h := fitWithin(tt.w, tt.h, tt.maxW, tt.maxH); w != tt.wantW || h != tt.wantH {
+			t.Errorf("fitWithin(%d, %d, %d, %d) = %d, %d; want %d, %d", tt.w, tt.h, tt.maxW, tt.maxH, w, h, tt.wantW, tt.wantH)
+		}
+	}
+}
+
+func TestCommandProcessor(t *testing.T) {
+	if _, err := exec.LookPath("cp"); err != nil {
+		t.Skip("cp not available")
+	}
+	ctx := context.Background()
+
+	stdout, err := NewProcessor("command", map[string]string{"command": "cat {input}", "content_type": "text/plain"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	contentType, err := stdout.Process(ctx, strings.NewReader("via stdout"), &out)
+	if err != nil || contentType != "text/plain" || out.String() != "via stdout" {
+		t.Errorf("stdout command: %q %q %v", out.String(), contentType, err)
+	}
+
+	file, err := NewProcessor("command", map[string]string{"command": "cp {input} {output}", "content_type": "application/pdf"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	if _, err := file.Process(ctx, strings.NewReader("via file"), &out); err != nil || out.String() != "via file" {
+		t.Errorf("output file command: %q %v", out.String(), err)
+	}
+
+	failing, err := NewProcessor("command", map[string]string{"command": "cat {input}.missing", "content_type": "text/plain"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := failing.Process(ctx, strings.NewReader("x"), io.Discard); err == nil || !strings.Contains(err.Error(), "No such file") {
+		t.Errorf("failing command: got %v", err)
+	}
+
+	for _, settings := range []map[string]string{
+		{"content_type": "text/plain"},
+		{"command": "cat", "content_type": "text/plain"},
+		{"command": "cat {input}"},
+		{"command": "cat {input}", "content_type": "text/plain", "timeout": "soon"},
+	} {
+		if _, err := NewProcessor("command", settings); !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("NewProcessor(%v): got %v, want ErrInvalidConfig", settings, err)
+		}
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "transforms.yaml")
+	if err := os.WriteFile(valid, []byte(`
+presets:
+  - name: thumb
+    processor: image
+    content_types: ["image/*"]
+    on_put: true
+    settings:
+      width: "256"
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadFile(valid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Presets) != 1 || !cfg.Presets[0].OnPut || cfg.Presets[0].Settings["width"] != "256" {
+		t.Errorf("loaded %+v", cfg)
+	}
+
+	for _, bad := range []*Config{
+		{Presets: []Preset{{Name: "bad name", Processor: "image"}}},
+		{Presets: []Preset{{Name: "a", Processor: "image"}, {Name: "a", Processor: "image"}}},
+		{Presets: []Preset{{Name: "a"}}},
+		{Prefix: "/abs/", Presets: []Preset{{Name: "a", Processor: "image"}}},
+		{MaxSourceSize: -1},
+	} {
+		if err := bad.Validate(); !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("Validate(%+v): got %v, want ErrInvalidConfig", bad, err)
+		}
+	}
+	if _, err := NewTransformer(memory.New(), &Config{Presets: []Preset{{Name: "a", Processor: "nope"}}}); !errors.Is(err, ErrUnknownProcessor) {
+		t.Errorf("unknown processor: got %v", err)
+	}
+	if _, err := NewTransformer(memory.New(), &Config{Presets: []Preset{{Name: "a", Processor: "image"}}}); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("image without dimensions: got %v", err)
+	}
+}
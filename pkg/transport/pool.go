@@ -0,0 +1,199 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package transport
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptrace"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// Stats reports connection usage for one backend type.
+type Stats struct {
+	// Backend is the backend type the clients were created for (e.g. "s3").
+	Backend string
+
+	// Clients is the number of distinct pooled clients for the backend.
+	Clients int
+
+	// NewConns counts requests that had to dial a new connection.
+	NewConns uint64
+
+	// ReusedConns counts requests served over an existing connection.
+	ReusedConns uint64
+}
+
+// counters holds the per-backend connection counters shared by every client
+// created for that backend.
+type counters struct {
+	newConns    atomic.Uint64
+	reusedConns atomic.Uint64
+}
+
+// Pool caches HTTP clients keyed by backend type, credential identity and
+// transport tuning. It is safe for concurrent use.
+type Pool struct {
+	mu       sync.Mutex
+	clients  map[string]*http.Client
+	owners   map[string]string
+	counters map[string]*counters
+}
+
+// NewPool creates an empty Pool.
+func NewPool() *Pool {
+	return &Pool{
+		clients:  make(map[string]*http.Client),
+		owners:   make(map[string]string),
+		counters: make(map[string]*counters),
+	}
+}
+
+// Default is the process-wide pool used by the cloud backends.
+var Default = NewPool()
+
+// Client returns an HTTP client for the given backend type and credential
+// identity. When cfg.Share is set, instances presenting the same identity and
+// tuning receive the same client and therefore the same connection pool. The
+// identity is hashed before being used as a cache key so secrets are never
+// retained in plain text. A nil cfg uses DefaultConfig.
+//
+// The client's Transport is a plain *http.Transport because some SDKs (the
+// AWS SDK when AWS_CA_BUNDLE is set) require that concrete type. Requests
+// only contribute to Stats when their context carries WithTrace.
+func (p *Pool) Client(backend, identity string, cfg *Config) *http.Client {
+	if cfg == nil {
+		cfg = DefaultConfig()
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.countersLocked(backend)
+	if !cfg.Share {
+		return &http.Client{Transport: cfg.NewTransport()}
+	}
+
+	sum := sha256.Sum256([]byte(identity))
+	key := backend + "|" + hex.EncodeToString(sum[:]) + "|" + cfg.fingerprint()
+	if c, ok := p.clients[key]; ok {
+		return c
+	}
+	c := &http.Client{Transport: cfg.NewTransport()}
+	p.clients[key] = c
+	p.owners[key] = backend
+	return c
+}
+
+// Transport returns the pooled transport for the given backend and identity,
+// wrapped so every request contributes to Stats. Backends whose SDK layers
+// its own authentication over a base http.RoundTripper use this instead of
+// Client.
+func (p *Pool) Transport(backend, identity string, cfg *Config) http.RoundTripper {
+	return &countingTransport{
+		base:  p.Client(backend, identity, cfg).Transport,
+		trace: p.trace(backend),
+	}
+}
+
+// WithTrace returns a copy of ctx that records the connection used by a
+// request into the Stats of backend.
+func (p *Pool) WithTrace(ctx context.Context, backend string) context.Context {
+	return httptrace.WithClientTrace(ctx, p.trace(backend))
+}
+
+// trace builds the client trace that feeds the counters of backend.
+func (p *Pool) trace(backend string) *httptrace.ClientTrace {
+	p.mu.Lock()
+	ctr := p.countersLocked(backend)
+	p.mu.Unlock()
+
+	return &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			if info.Reused {
+				ctr.reusedConns.Add(1)
+			} else {
+				ctr.newConns.Add(1)
+			}
+		},
+	}
+}
+
+// countersLocked returns the counters for backend, creating them if needed.
+// The caller must hold p.mu.
+func (p *Pool) countersLocked(backend string) *counters {
+	ctr, ok := p.counters[backend]
+	if !ok {
+		ctr = &counters{}
+		p.counters[backend] = ctr
+	}
+	return ctr
+}
+
+// Stats returns connection statistics per backend type, sorted by backend.
+func (p *Pool) Stats() []Stats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	clients := make(map[string]int, len(p.counters))
+	for _, backend := range p.owners {
+		clients[backend]++
+	}
+
+	out := make([]Stats, 0, len(p.counters))
+	for backend, ctr := range p.counters {
+		out = append(out, Stats{
+			Backend:     backend,
+			Clients:     clients[backend],
+			NewConns:    ctr.newConns.Load(),
+			ReusedConns: ctr.reusedConns.Load(),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Backend < out[j].Backend })
+	return out
+}
+
+// CloseIdleConnections closes idle connections on every pooled client.
+func (p *Pool) CloseIdleConnections() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, c := range p.clients {
+		c.CloseIdleConnections()
+	}
+}
+
+// countingTransport wraps a transport and attaches the backend's client
+// trace to every request.
+type countingTransport struct {
+	base  http.RoundTripper
+	trace *httptrace.ClientTrace
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := httptrace.WithClientTrace(req.Context(), t.trace)
+	return t.base.RoundTrip(req.WithContext(ctx))
+}
+
+// CloseIdleConnections lets http.Client.CloseIdleConnections reach the
+// wrapped transport.
+func (t *countingTransport) CloseIdleConnections() {
+	if c, ok := t.base.(interface{ CloseIdleConnections() }); ok {
+		c.CloseIdleConnections()
+	}
+}
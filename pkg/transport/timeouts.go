@@ -0,0 +1,156 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package transport
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// Factory setting keys understood by ParseTimeouts. Values are Go durations
+// (e.g. "30s"); unset or zero values impose no deadline of their own.
+const (
+	// SettingTimeout is the default deadline for every operation without a
+	// more specific setting.
+	SettingTimeout = "timeout"
+
+	// SettingGetTimeout bounds downloads, including reading the response
+	// body until it is closed.
+	SettingGetTimeout = "getTimeout"
+
+	// SettingPutTimeout bounds uploads, appends and composes.
+	SettingPutTimeout = "putTimeout"
+
+	// SettingDeleteTimeout bounds deletes.
+	SettingDeleteTimeout = "deleteTimeout"
+
+	// SettingListTimeout bounds a complete listing, across all pages.
+	SettingListTimeout = "listTimeout"
+
+	// SettingMetadataTimeout bounds metadata reads and updates, existence
+	// checks and lifecycle configuration calls.
+	SettingMetadataTimeout = "metadataTimeout"
+)
+
+// Operation identifies the class of backend call a timeout applies to.
+type Operation int
+
+// Operation classes with their own timeout setting.
+const (
+	OpGet Operation = iota
+	OpPut
+	OpDelete
+	OpList
+	OpMetadata
+)
+
+// Timeouts holds the per-operation default deadlines for one backend
+// instance. A zero duration falls back to Default; a zero Default leaves the
+// caller's context untouched. The nil *Timeouts imposes no deadlines.
+type Timeouts struct {
+	Default  time.Duration
+	Get      time.Duration
+	Put      time.Duration
+	Delete   time.Duration
+	List     time.Duration
+	Metadata time.Duration
+}
+
+// ParseTimeouts builds Timeouts from backend factory settings. Malformed or
+// negative values are rejected with an error wrapping
+// common.ErrInvalidArgument.
+func ParseTimeouts(settings map[string]string) (*Timeouts, error) {
+	t := &Timeouts{}
+	durations := []struct {
+		key string
+		dst *time.Duration
+	}{
+		{SettingTimeout, &t.Default},
+		{SettingGetTimeout, &t.Get},
+		{SettingPutTimeout, &t.Put},
+		{SettingDeleteTimeout, &t.Delete},
+		{SettingListTimeout, &t.List},
+		{SettingMetadataTimeout, &t.Metadata},
+	}
+	for _, s := range durations {
+		v, ok := settings[s.key]
+		if !ok || v == "" {
+			continue
+		}
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("%w: %s must be a non-negative duration, got %q", common.ErrInvalidArgument, s.key, v)
+		}
+		*s.dst = d
+	}
+	return t, nil
+}
+
+// For returns the deadline for op, falling back to Default.
+func (t *Timeouts) For(op Operation) time.Duration {
+	if t == nil {
+		return 0
+	}
+	var d time.Duration
+	switch op {
+	case OpGet:
+		d = t.Get
+	case OpPut:
+		d = t.Put
+	case OpDelete:
+		d = t.Delete
+	case OpList:
+		d = t.List
+	case OpMetadata:
+		d = t.Metadata
+	}
+	if d == 0 {
+		d = t.Default
+	}
+	return d
+}
+
+// Context derives the context for one op from the caller's ctx. When op has
+// a timeout the returned context carries it; a caller deadline that expires
+// sooner still wins. The cancel function must always be called.
+func (t *Timeouts) Context(ctx context.Context, op Operation) (context.Context, context.CancelFunc) {
+	if d := t.For(op); d > 0 {
+		return context.WithTimeout(ctx, d)
+	}
+	return ctx, func() {}
+}
+
+// CancelOnClose returns rc with cancel deferred until the reader is closed,
+// so a streamed response body keeps its operation context alive while it is
+// read.
+func CancelOnClose(rc io.ReadCloser, cancel context.CancelFunc) io.ReadCloser {
+	return &cancelReadCloser{ReadCloser: rc, cancel: cancel}
+}
+
+type cancelReadCloser struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+	once   sync.Once
+}
+
+func (c *cancelReadCloser) Close() error {
+	err := c.ReadCloser.Close()
+	c.once.Do(c.cancel)
+	return err
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package transport
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+func TestParseTimeouts(t *testing.T) {
+	timeouts, err := ParseTimeouts(map[string]string{
+		SettingTimeout:    "30s",
+		SettingGetTimeout: "5m",
+		SettingPutTimeout: "",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tests := []struct {
+		op   Operation
+		want time.Duration
+	}{
+		{OpGet, 5 * time.Minute},
+		{OpPut, 30 * time.Second},
+		{OpDelete, 30 * time.Second},
+		{OpList, 30 * time.Second},
+		{OpMetadata, 30 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := timeouts.For(tt.op); got != tt.want {
+			t.Errorf("For(%d) = %v, want %v", tt.op, got, tt.want)
+		}
+	}
+
+	for _, v := range []string{"soon", "-1s"} {
+		if _, err := ParseTimeouts(map[string]string{SettingListTimeout: v}); !errors.Is(err, common.ErrInvalidArgument) {
+			t.Errorf("ParseTimeouts(%q) error = %v, want ErrInvalidArgument", v, err)
+		}
+	}
+}
+
+func TestTimeouts_Context(t *testing.T) {
+	var none *Timeouts
+	ctx, cancel := none.Context(context.Background(), OpGet)
+	cancel()
+	if _, ok := ctx.Deadline(); ok {
+		t.Error("nil Timeouts set a deadline")
+	}
+
+	timeouts := &Timeouts{Put: time.Hour}
+	ctx, cancel = timeouts.Context(context.Background(), OpPut)
+	defer cancel()
+	if deadline, ok := ctx.Deadline(); !ok || time.Until(deadline) > time.Hour {
+		t.Errorf("Put deadline = %v, %v; want within an hour", deadline, ok)
+	}
+
+	// A caller deadline that expires sooner wins.
+	parent, parentCancel := context.WithTimeout(context.Background(), time.Minute)
+	defer parentCancel()
+	ctx, cancel = timeouts.Context(parent, OpPut)
+	defer cancel()
+	if deadline, _ := ctx.Deadline(); time.Until(deadline) > time.Minute {
+		t.Errorf("deadline %v outlives the caller's", deadline)
+	}
+}
+
+func TestCancelOnClose(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	rc := CancelOnClose(io.NopCloser(strings.NewReader("data")), cancel)
+
+	if b, err := io.ReadAll(rc); err != nil || string(b) != "data" {
+		t.Fatalf("ReadAll() = %q, %v", b, err)
+	}
+	if ctx.Err() != nil {
+		t.Fatal("context canceled before Close")
+	}
+	if err := rc.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if ctx.Err() == nil {
+		t.Fatal("context not canceled by Close")
+	}
+	_ = rc.Close()
+}
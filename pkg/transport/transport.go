@@ -0,0 +1,211 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package transport builds tuned HTTP clients for the cloud storage backends
+// and shares them across backend instances that use the same credentials.
+//
+// The SDK defaults keep very few idle connections per host, so highly
+// concurrent workloads spend most of their time in TCP and TLS handshakes.
+// Backends call ParseSettings with their factory settings and obtain a
+// client from the process-wide Default pool, which reuses one connection
+// pool per (backend, identity, tuning) tuple and records connection reuse
+// statistics for the /metrics endpoint. ParseTimeouts reads the
+// per-operation default deadlines the backends apply to each SDK call.
+package transport
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// Factory setting keys understood by ParseSettings. All keys are optional;
+// unset keys keep the DefaultConfig value.
+const (
+	// SettingMaxIdleConns caps idle connections across all hosts.
+	SettingMaxIdleConns = "httpMaxIdleConns"
+
+	// SettingMaxIdleConnsPerHost caps idle connections kept per host.
+	SettingMaxIdleConnsPerHost = "httpMaxIdleConnsPerHost"
+
+	// SettingMaxConnsPerHost caps total connections per host (0 = unlimited).
+	SettingMaxConnsPerHost = "httpMaxConnsPerHost"
+
+	// SettingIdleConnTimeout is how long an idle connection is kept (e.g. "90s").
+	SettingIdleConnTimeout = "httpIdleConnTimeout"
+
+	// SettingTLSHandshakeTimeout bounds the TLS handshake (e.g. "10s").
+	SettingTLSHandshakeTimeout = "httpTLSHandshakeTimeout"
+
+	// SettingResponseHeaderTimeout bounds the wait for response headers (0 = none).
+	SettingResponseHeaderTimeout = "httpResponseHeaderTimeout"
+
+	// SettingDialTimeout bounds establishing the TCP connection.
+	SettingDialTimeout = "httpDialTimeout"
+
+	// SettingKeepAlive is the TCP keep-alive period.
+	SettingKeepAlive = "httpKeepAlive"
+
+	// SettingDisableHTTP2 disables HTTP/2 negotiation when "true".
+	SettingDisableHTTP2 = "httpDisableHTTP2"
+
+	// SettingShareClient disables client sharing across instances when "false".
+	SettingShareClient = "httpShareClient"
+)
+
+// Config holds the HTTP transport tuning for one backend.
+type Config struct {
+	MaxIdleConns          int
+	MaxIdleConnsPerHost   int
+	MaxConnsPerHost       int
+	IdleConnTimeout       time.Duration
+	TLSHandshakeTimeout   time.Duration
+	ResponseHeaderTimeout time.Duration
+	ExpectContinueTimeout time.Duration
+	DialTimeout           time.Duration
+	KeepAlive             time.Duration
+	DisableHTTP2          bool
+
+	// Share reuses a single client for all instances with the same identity
+	// and tuning. Disable it to give an instance a private connection pool.
+	Share bool
+}
+
+// DefaultConfig returns tuning suited to highly concurrent object storage
+// traffic. Unlike http.DefaultTransport it keeps up to 100 idle connections
+// per host instead of 2.
+func DefaultConfig() *Config {
+	return &Config{
+		MaxIdleConns:          512,
+		MaxIdleConnsPerHost:   100,
+		MaxConnsPerHost:       0,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 0,
+		ExpectContinueTimeout: time.Second,
+		DialTimeout:           30 * time.Second,
+		KeepAlive:             30 * time.Second,
+		DisableHTTP2:          false,
+		Share:                 true,
+	}
+}
+
+// ParseSettings builds a Config from backend factory settings, starting from
+// DefaultConfig. Malformed values are rejected with an error wrapping
+// common.ErrInvalidArgument.
+func ParseSettings(settings map[string]string) (*Config, error) {
+	cfg := DefaultConfig()
+
+	ints := []struct {
+		key string
+		dst *int
+	}{
+		{SettingMaxIdleConns, &cfg.MaxIdleConns},
+		{SettingMaxIdleConnsPerHost, &cfg.MaxIdleConnsPerHost},
+		{SettingMaxConnsPerHost, &cfg.MaxConnsPerHost},
+	}
+	for _, s := range ints {
+		v, ok := settings[s.key]
+		if !ok || v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("%w: %s must be a non-negative integer, got %q", common.ErrInvalidArgument, s.key, v)
+		}
+		*s.dst = n
+	}
+
+	durations := []struct {
+		key string
+		dst *time.Duration
+	}{
+		{SettingIdleConnTimeout, &cfg.IdleConnTimeout},
+		{SettingTLSHandshakeTimeout, &cfg.TLSHandshakeTimeout},
+		{SettingResponseHeaderTimeout, &cfg.ResponseHeaderTimeout},
+		{SettingDialTimeout, &cfg.DialTimeout},
+		{SettingKeepAlive, &cfg.KeepAlive},
+	}
+	for _, s := range durations {
+		v, ok := settings[s.key]
+		if !ok || v == "" {
+			continue
+		}
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("%w: %s must be a non-negative duration, got %q", common.ErrInvalidArgument, s.key, v)
+		}
+		*s.dst = d
+	}
+
+	bools := []struct {
+		key string
+		dst *bool
+	}{
+		{SettingDisableHTTP2, &cfg.DisableHTTP2},
+		{SettingShareClient, &cfg.Share},
+	}
+	for _, s := range bools {
+		v, ok := settings[s.key]
+		if !ok || v == "" {
+			continue
+		}
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s must be a boolean, got %q", common.ErrInvalidArgument, s.key, v)
+		}
+		*s.dst = b
+	}
+
+	return cfg, nil
+}
+
+// NewTransport builds an *http.Transport from the configuration.
+func (c *Config) NewTransport() *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   c.DialTimeout,
+		KeepAlive: c.KeepAlive,
+	}
+	t := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		MaxIdleConns:          c.MaxIdleConns,
+		MaxIdleConnsPerHost:   c.MaxIdleConnsPerHost,
+		MaxConnsPerHost:       c.MaxConnsPerHost,
+		IdleConnTimeout:       c.IdleConnTimeout,
+		TLSHandshakeTimeout:   c.TLSHandshakeTimeout,
+		ResponseHeaderTimeout: c.ResponseHeaderTimeout,
+		ExpectContinueTimeout: c.ExpectContinueTimeout,
+		ForceAttemptHTTP2:     !c.DisableHTTP2,
+	}
+	if c.DisableHTTP2 {
+		// A non-nil, empty TLSNextProto map is the documented way to turn off
+		// HTTP/2 on a custom transport.
+		t.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+	return t
+}
+
+// fingerprint identifies the tuning so differently tuned instances never
+// share a connection pool.
+func (c *Config) fingerprint() string {
+	return fmt.Sprintf("%d/%d/%d/%s/%s/%s/%s/%s/%s/%t",
+		c.MaxIdleConns, c.MaxIdleConnsPerHost, c.MaxConnsPerHost,
+		c.IdleConnTimeout, c.TLSHandshakeTimeout, c.ResponseHeaderTimeout,
+		c.ExpectContinueTimeout, c.DialTimeout, c.KeepAlive, c.DisableHTTP2)
+}
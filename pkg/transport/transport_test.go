@@ -0,0 +1,151 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package transport
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+func TestParseSettings_Defaults(t *testing.T) {
+	cfg, err := ParseSettings(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *cfg != *DefaultConfig() {
+		t.Errorf("expected defaults, got %+v", cfg)
+	}
+}
+
+func TestParseSettings_Overrides(t *testing.T) {
+	cfg, err := ParseSettings(map[string]string{
+		SettingMaxIdleConns:          "64",
+		SettingMaxIdleConnsPerHost:   "32",
+		SettingMaxConnsPerHost:       "16",
+		SettingIdleConnTimeout:       "45s",
+		SettingTLSHandshakeTimeout:   "5s",
+		SettingResponseHeaderTimeout: "2m",
+		SettingDialTimeout:           "3s",
+		SettingKeepAlive:             "15s",
+		SettingDisableHTTP2:          "true",
+		SettingShareClient:           "false",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.MaxIdleConns != 64 || cfg.MaxIdleConnsPerHost != 32 || cfg.MaxConnsPerHost != 16 {
+		t.Errorf("connection limits not applied: %+v", cfg)
+	}
+	if cfg.IdleConnTimeout != 45*time.Second || cfg.ResponseHeaderTimeout != 2*time.Minute {
+		t.Errorf("timeouts not applied: %+v", cfg)
+	}
+	if !cfg.DisableHTTP2 || cfg.Share {
+		t.Errorf("flags not applied: %+v", cfg)
+	}
+
+	tr := cfg.NewTransport()
+	if tr.MaxIdleConnsPerHost != 32 || tr.ForceAttemptHTTP2 || tr.TLSNextProto == nil {
+		t.Errorf("transport does not reflect config: %+v", tr)
+	}
+}
+
+func TestParseSettings_Invalid(t *testing.T) {
+	for _, settings := range []map[string]string{
+		{SettingMaxIdleConns: "many"},
+		{SettingMaxConnsPerHost: "-1"},
+		{SettingIdleConnTimeout: "forever"},
+		{SettingDisableHTTP2: "maybe"},
+	} {
+		if _, err := ParseSettings(settings); !errors.Is(err, common.ErrInvalidArgument) {
+			t.Errorf("ParseSettings(%v) = %v, want ErrInvalidArgument", settings, err)
+		}
+	}
+}
+
+func TestPool_SharesClientsByIdentity(t *testing.T) {
+	p := NewPool()
+	cfg := DefaultConfig()
+
+	a := p.Client("s3", "key|secret", cfg)
+	b := p.Client("s3", "key|secret", cfg)
+	if a != b {
+		t.Error("expected same client for the same identity")
+	}
+	if c := p.Client("s3", "other|secret", cfg); c == a {
+		t.Error("expected different client for a different identity")
+	}
+
+	tuned := DefaultConfig()
+	tuned.MaxIdleConnsPerHost = 7
+	if c := p.Client("s3", "key|secret", tuned); c == a {
+		t.Error("expected different client for different tuning")
+	}
+
+	private := DefaultConfig()
+	private.Share = false
+	if c := p.Client("s3", "key|secret", private); c == a {
+		t.Error("expected private client when sharing is disabled")
+	}
+
+	stats := p.Stats()
+	if len(stats) != 1 || stats[0].Backend != "s3" || stats[0].Clients != 3 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestPool_CountsConnectionReuse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer srv.Close()
+
+	p := NewPool()
+	client := &http.Client{Transport: p.Transport("gcs", "id", nil)}
+	for i := 0; i < 3; i++ {
+		resp, err := client.Get(srv.URL)
+		if err != nil {
+			t.Fatalf("GET: %v", err)
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}
+
+	// Requests issued through the plain client only count with WithTrace.
+	req, err := http.NewRequestWithContext(p.WithTrace(context.Background(), "gcs"), http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	resp, err := p.Client("gcs", "id", nil).Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	p.CloseIdleConnections()
+
+	stats := p.Stats()
+	if len(stats) != 1 {
+		t.Fatalf("expected one backend, got %+v", stats)
+	}
+	if stats[0].NewConns != 1 || stats[0].ReusedConns != 3 {
+		t.Errorf("expected 1 new and 3 reused connections, got %+v", stats[0])
+	}
+}
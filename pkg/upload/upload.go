@@ -0,0 +1,585 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+// Package upload implements chunked upload sessions: a client creates a
+// session for a key, uploads the object as numbered chunks, each with its
+// SHA-256, in any order and retrying any that fail, and completes the
+// session to assemble the object. Sessions need no multipart support from
+// the backend, so they work the same on every backend.
+//
+// A session and its chunks are objects under a reserved prefix
+// (DefaultPrefix), so sessions survive server restarts and can be
+// continued through any instance sharing the backend. A chunk's digest is
+// checked when it is uploaded and again when the session is completed, and
+// the assembled object can be checked against a digest of the whole.
+package upload
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+)
+
+// DefaultPrefix is the key prefix sessions are stored under when none is
+// configured.
+const DefaultPrefix = ".uploads/"
+
+// Session lifetime bounds.
+const (
+	DefaultTTL = 24 * time.Hour
+	MaxTTL     = 7 * 24 * time.Hour
+)
+
+// Chunk bounds.
+const (
+	// MaxChunks is the highest chunk number.
+	MaxChunks = 10000
+
+	// MaxChunkSize is the largest chunk in bytes (256 MiB).
+	MaxChunkSize int64 = 256 << 20
+)
+
+// Object names under a session's prefix.
+const (
+	sessionObject = "session.json"
+	chunksPrefix  = "chunks/"
+)
+
+var (
+	// ErrSessionNotFound is returned for an unknown, completed or aborted
+	// session.
+	ErrSessionNotFound = fmt.Errorf("upload session %w", common.ErrNotFound)
+
+	// ErrSessionExpired is returned for a session past its expiry. It wraps
+	// common.ErrNotFound.
+	ErrSessionExpired = fmt.Errorf("upload session has expired: %w", common.ErrNotFound)
+
+	// ErrInvalidOptions is returned by Create for out-of-range options.
+	ErrInvalidOptions = fmt.Errorf("%w: invalid upload session options", common.ErrInvalidArgument)
+
+	// ErrInvalidChunk is returned for a chunk number outside 1 to
+	// MaxChunks, a malformed digest or a chunk larger than MaxChunkSize.
+	ErrInvalidChunk = fmt.Errorf("%w: invalid chunk", common.ErrInvalidArgument)
+
+	// ErrChecksumMismatch is returned when a chunk or the assembled object
+	// does not match its SHA-256.
+	ErrChecksumMismatch = fmt.Errorf("%w: SHA-256 mismatch", common.ErrInvalidArgument)
+
+	// ErrIncomplete is returned when completing a session with missing
+	// chunks or a size other than the one announced.
+	ErrIncomplete = fmt.Errorf("%w: upload session is incomplete", common.ErrInvalidArgument)
+)
+
+// Options configures a new session. Zero fields take their defaults.
+type Options struct {
+	// TTL is how long the session can be continued (default: DefaultTTL, at
+	// most MaxTTL).
+	TTL time.Duration
+
+	// Size, if set, is the size the assembled object must have.
+	Size int64
+
+	// ContentType and ContentEncoding are stored with the object.
+	ContentType     string
+	ContentEncoding string
+
+	// Metadata is the custom metadata stored with the object.
+	Metadata map[string]string
+}
+
+// Session is an upload in progress. Chunks are only filled in by Get and
+// Assemble.
+type Session struct {
+	ID              string            `json:"id"`
+	Key             string            `json:"key"`
+	CreatedBy       string            `json:"created_by,omitempty"`
+	CreatedAt       time.Time         `json:"created_at"`
+	ExpiresAt       time.Time         `json:"expires_at"`
+	Size            int64             `json:"size,omitempty"`
+	ContentType     string            `json:"content_type,omitempty"`
+	ContentEncoding string            `json:"content_encoding,omitempty"`
+	Metadata        map[string]string `json:"metadata,omitempty"`
+	Chunks          []Chunk           `json:"chunks,omitempty"`
+}
+
+// Received returns the total size of the session's chunks.
+func (s *Session) Received() int64 {
+	var n int64
+	for _, chunk := range s.Chunks {
+		n += chunk.Size
+	}
+	return n
+}
+
+// Chunk is an uploaded chunk of a session.
+type Chunk struct {
+	Number int    `json:"number"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Manager creates sessions and stores their chunks in a backend. It is
+// safe for concurrent use; chunks of one session may be uploaded in
+// parallel.
+type Manager struct {
+	storage common.Storage
+	prefix  string
+	now     func() time.Time
+}
+
+// NewManager returns a Manager storing sessions in storage under prefix, or
+// DefaultPrefix if prefix is empty.
+func NewManager(storage common.Storage, prefix string) (*Manager, error) {
+	if prefix == "" {
+		prefix = DefaultPrefix
+	}
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	if err := common.ValidateKey(prefix + sessionObject); err != nil {
+		return nil, fmt.Errorf("invalid upload session prefix %q: %w", prefix, err)
+	}
+	return &Manager{storage: storage, prefix: prefix, now: time.Now}, nil
+}
+
+// Prefix returns the key prefix sessions are stored under.
+func (m *Manager) Prefix() string {
+	return m.prefix
+}
+
+// Reserved reports whether key is in the session namespace.
+func (m *Manager) Reserved(key string) bool {
+	return common.IsReserved(key, m.prefix)
+}
+
+// Create starts a session for key. The creator is taken from the principal
+// in ctx.
+func (m *Manager) Create(ctx context.Context, key string, opts Options) (*Session, error) {
+	if err := common.ValidateKey(key); err != nil {
+		return nil, err
+	}
+	if m.Reserved(key) {
+		return nil, fmt.Errorf("%w: %s", common.ErrReservedKey, key)
+	}
+	if err := validate(&opts); err != nil {
+		return nil, err
+	}
+
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	now := m.now().UTC()
+	session := &Session{
+		ID:              id,
+		Key:             key,
+		CreatedBy:       adapters.Identity(ctx),
+		CreatedAt:       now,
+		ExpiresAt:       now.Add(opts.TTL),
+		Size:            opts.Size,
+		ContentType:     opts.ContentType,
+		ContentEncoding: opts.ContentEncoding,
+		Metadata:        opts.Metadata,
+	}
+	data, err := json.Marshal(session)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.storage.PutWithMetadata(ctx, m.prefix+id+"/"+sessionObject, bytes.NewReader(data), &common.Metadata{
+		ContentType: "application/json",
+	}); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// PutChunk stores chunk number of session id, replacing any earlier upload
+// of it. sum is the hex SHA-256 of data; a chunk that does not match it is
+// not kept.
+func (m *Manager) PutChunk(ctx context.Context, id string, number int, data io.Reader, sum string) (*Chunk, error) {
+	if number < 1 || number > MaxChunks {
+		return nil, fmt.Errorf("%w: number must be between 1 and %d", ErrInvalidChunk, MaxChunks)
+	}
+	sum = strings.ToLower(sum)
+	if !isDigest(sum) {
+		return nil, fmt.Errorf("%w: SHA-256 must be 64 hex digits", ErrInvalidChunk)
+	}
+	if _, err := m.session(ctx, id); err != nil {
+		return nil, err
+	}
+
+	key := m.chunkKey(id, number)
+	body := &verifier{r: data, hash: sha256.New(), want: sum, limit: MaxChunkSize}
+	err := m.storage.PutWithMetadata(ctx, key, body, &common.Metadata{
+		ContentType: "application/octet-stream",
+		Custom:      map[string]string{common.MetaChecksumSHA256: sum},
+	})
+	if err == nil && body.err == nil && !body.eof {
+		err = fmt.Errorf("%w: chunk was not read to the end", ErrInvalidChunk)
+	}
+	if body.err != nil || err != nil {
+		// Never keep a chunk that was cut short or does not match.
+		_ = m.storage.DeleteWithContext(context.WithoutCancel(ctx), key)
+		if body.err != nil {
+			return nil, body.err
+		}
+		return nil, err
+	}
+	return &Chunk{Number: number, Size: body.n, SHA256: sum}, nil
+}
+
+// Lookup returns session id without its chunks, which is cheaper than Get
+// when only the session itself is needed.
+func (m *Manager) Lookup(ctx context.Context, id string) (*Session, error) {
+	return m.session(ctx, id)
+}
+
+// Get returns session id with its chunks in order.
+func (m *Manager) Get(ctx context.Context, id string) (*Session, error) {
+	session, err := m.session(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.chunks(ctx, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// Sessions returns every session with its chunks, expired ones included,
+// so that sessions left unfinished can be found and removed.
+func (m *Manager) Sessions(ctx context.Context) ([]*Session, error) {
+	keys, err := m.storage.ListWithContext(ctx, m.prefix)
+	if err != nil {
+		return nil, err
+	}
+	var sessions []*Session
+	for _, key := range keys {
+		id, ok := strings.CutSuffix(strings.TrimPrefix(key, m.prefix), "/"+sessionObject)
+		if !ok || !validID(id) {
+			continue
+		}
+		session, err := m.load(ctx, id)
+		if errors.Is(err, ErrSessionNotFound) {
+			// Completed or aborted since it was listed.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := m.chunks(ctx, session); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+// chunks fills in the chunks of session in order.
+func (m *Manager) chunks(ctx context.Context, session *Session) error {
+	id := session.ID
+	chunkPrefix := m.prefix + id + "/" + chunksPrefix
+	keys, err := m.storage.ListWithContext(ctx, chunkPrefix)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		number, err := strconv.Atoi(strings.TrimPrefix(key, chunkPrefix))
+		if err != nil || number < 1 || number > MaxChunks {
+			continue
+		}
+		metadata, err := m.storage.GetMetadata(ctx, key)
+		if errors.Is(err, common.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		sum := strings.ToLower(common.CustomField(metadata.Custom, common.MetaChecksumSHA256))
+		if !isDigest(sum) {
+			continue
+		}
+		session.Chunks = append(session.Chunks, Chunk{Number: number, Size: metadata.Size, SHA256: sum})
+	}
+	sort.Slice(session.Chunks, func(i, j int) bool { return session.Chunks[i].Number < session.Chunks[j].Number })
+	return nil
+}
+
+// Assemble returns session id and a reader of its object: its chunks in
+// order, numbered from 1 without gaps. Each chunk is checked against its
+// digest as it is read, and the whole against sum if it is not empty; a
+// mismatch fails the read with ErrChecksumMismatch. Callers store the
+// object and then Remove the session.
+func (m *Manager) Assemble(ctx context.Context, id, sum string) (*Session, io.ReadCloser, error) {
+	sum = strings.ToLower(sum)
+	if sum != "" && !isDigest(sum) {
+		return nil, nil, fmt.Errorf("%w: SHA-256 must be 64 hex digits", ErrInvalidChunk)
+	}
+	session, err := m.Get(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i, chunk := range session.Chunks {
+		if chunk.Number != i+1 {
+			return nil, nil, fmt.Errorf("%w: chunk %d is missing", ErrIncomplete, i+1)
+		}
+	}
+	if session.Size > 0 && session.Received() != session.Size {
+		return nil, nil, fmt.Errorf("%w: received %d of %d bytes", ErrIncomplete, session.Received(), session.Size)
+	}
+	return session, &assembler{ctx: ctx, m: m, session: session, whole: sha256.New(), want: sum}, nil
+}
+
+// Remove deletes session id and its chunks, completing or aborting it.
+func (m *Manager) Remove(ctx context.Context, id string) error {
+	if !validID(id) {
+		return fmt.Errorf("%w: %s", ErrSessionNotFound, id)
+	}
+	keys, err := m.storage.ListWithContext(ctx, m.prefix+id+"/")
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("%w: %s", ErrSessionNotFound, id)
+	}
+	// The session object goes last, so an interrupted removal can be
+	// retried.
+	sort.Slice(keys, func(i, j int) bool {
+		return !strings.HasSuffix(keys[i], sessionObject) && strings.HasSuffix(keys[j], sessionObject)
+	})
+	for _, key := range keys {
+		if err := m.storage.DeleteWithContext(ctx, key); err != nil && !errors.Is(err, common.ErrNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Purge removes the sessions past their expiry and returns how many it
+// removed.
+func (m *Manager) Purge(ctx context.Context) (int, error) {
+	keys, err := m.storage.ListWithContext(ctx, m.prefix)
+	if err != nil {
+		return 0, err
+	}
+	purged := 0
+	for _, key := range keys {
+		id, ok := strings.CutSuffix(strings.TrimPrefix(key, m.prefix), "/"+sessionObject)
+		if !ok || !validID(id) {
+			continue
+		}
+		if _, err := m.session(ctx, id); !errors.Is(err, ErrSessionExpired) {
+			continue
+		}
+		if err := m.Remove(ctx, id); err != nil && !errors.Is(err, common.ErrNotFound) {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// session reads session id without its chunks, failing for expired ones.
+func (m *Manager) session(ctx context.Context, id string) (*Session, error) {
+	session, err := m.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !m.now().Before(session.ExpiresAt) {
+		return nil, fmt.Errorf("%w: %s", ErrSessionExpired, id)
+	}
+	return session, nil
+}
+
+// load reads session id without its chunks.
+func (m *Manager) load(ctx context.Context, id string) (*Session, error) {
+	if !validID(id) {
+		return nil, fmt.Errorf("%w: %s", ErrSessionNotFound, id)
+	}
+	rc, err := m.storage.GetWithContext(ctx, m.prefix+id+"/"+sessionObject)
+	if errors.Is(err, common.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrSessionNotFound, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(io.LimitReader(rc, 64*1024))
+	_ = rc.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	var session Session
+	if err := json.Unmarshal(data, &session); err != nil || session.ID != id {
+		return nil, fmt.Errorf("upload session %s is corrupt", id)
+	}
+	return &session, nil
+}
+
+func (m *Manager) chunkKey(id string, number int) string {
+	return fmt.Sprintf("%s%s/%s%05d", m.prefix, id, chunksPrefix, number)
+}
+
+// verifier counts and hashes a chunk as it is stored, failing the read
+// that passes limit or ends with the wrong digest. The error is kept so
+// it can be told apart from storage errors.
+type verifier struct {
+	r     io.Reader
+	hash  hash.Hash
+	want  string
+	limit int64
+	n     int64
+	eof   bool
+	err   error
+}
+
+func (v *verifier) Read(p []byte) (int, error) {
+	if v.err != nil {
+		return 0, v.err
+	}
+	n, err := v.r.Read(p)
+	v.n += int64(n)
+	_, _ = v.hash.Write(p[:n])
+	v.eof = err == io.EOF
+	switch {
+	case v.limit > 0 && v.n > v.limit:
+		v.err = fmt.Errorf("%w: chunk exceeds %d bytes", ErrInvalidChunk, v.limit)
+	case err == io.EOF && v.want != "" && hex.EncodeToString(v.hash.Sum(nil)) != v.want:
+		v.err = ErrChecksumMismatch
+	case err != nil && err != io.EOF:
+		v.err = err
+	}
+	if v.err != nil {
+		return n, v.err
+	}
+	return n, err
+}
+
+// assembler reads a session's chunks in order, checking each one's digest
+// and the digest of the whole.
+type assembler struct {
+	ctx     context.Context
+	m       *Manager
+	session *Session
+	whole   hash.Hash
+	want    string
+
+	next  int
+	chunk io.ReadCloser
+	err   error
+}
+
+func (a *assembler) Read(p []byte) (int, error) {
+	for a.err == nil {
+		if a.chunk == nil {
+			if a.next == len(a.session.Chunks) {
+				if a.want != "" && hex.EncodeToString(a.whole.Sum(nil)) != a.want {
+					a.err = ErrChecksumMismatch
+				} else {
+					a.err = io.EOF
+				}
+				break
+			}
+			chunk := a.session.Chunks[a.next]
+			rc, err := a.m.storage.GetWithContext(a.ctx, a.m.chunkKey(a.session.ID, chunk.Number))
+			if err != nil {
+				a.err = err
+				break
+			}
+			a.chunk = struct {
+				io.Reader
+				io.Closer
+			}{&verifier{r: rc, hash: sha256.New(), want: chunk.SHA256}, rc}
+		}
+
+		n, err := a.chunk.Read(p)
+		_, _ = a.whole.Write(p[:n])
+		if err == io.EOF {
+			_ = a.chunk.Close()
+			a.chunk, err = nil, nil
+			a.next++
+		}
+		if err != nil {
+			if errors.Is(err, ErrChecksumMismatch) {
+				err = fmt.Errorf("%w: chunk %d", ErrChecksumMismatch, a.session.Chunks[a.next].Number)
+			}
+			a.err = err
+		}
+		if n > 0 {
+			return n, nil
+		}
+	}
+	return 0, a.err
+}
+
+func (a *assembler) Close() error {
+	if a.chunk != nil {
+		return a.chunk.Close()
+	}
+	return nil
+}
+
+func validate(opts *Options) error {
+	if opts.TTL == 0 {
+		opts.TTL = DefaultTTL
+	}
+	switch {
+	case opts.TTL < 0 || opts.TTL > MaxTTL:
+		return fmt.Errorf("%w: TTL must be between 0 and %s", ErrInvalidOptions, MaxTTL)
+	case opts.Size < 0 || opts.Size > MaxChunks*MaxChunkSize:
+		return fmt.Errorf("%w: size must be between 0 and %d bytes", ErrInvalidOptions, MaxChunks*MaxChunkSize)
+	}
+	if err := common.ValidateMetadata(opts.Metadata); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidOptions, err)
+	}
+	return nil
+}
+
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func validID(id string) bool {
+	return len(id) == 32 && isHex(id)
+}
+
+func isDigest(s string) bool {
+	return len(s) == 64 && isHex(s)
+}
+
+func isHex(s string) bool {
+	for _, r := range s {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,362 @@
+// Copyright (c) 2025 Jeremy Hahn
+// Copyright (c) 2025 Automate The Things, LLC
+//
+// This file is part of go-objstore.
+//
+// go-objstore is dual-licensed:
+//
+// 1. GNU Affero General Public License v3.0 (AGPL-3.0)
+//    See LICENSE file or visit https://www.gnu.org/licenses/agpl-3.0.html
+//
+// 2. Commercial License
+//    Contact licensing@automatethethings.com for commercial licensing options.
+
+package upload
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-objstore/pkg/adapters"
+	"github.com/jeremyhahn/go-objstore/pkg/common"
+	"github.com/jeremyhahn/go-objstore/pkg/memory"
+)
+
+func newTestManager(t *testing.T) (*Manager, common.Storage) {
+	t.Helper()
+	backend := memory.New()
+	manager, err := NewManager(backend, "")
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	return manager, backend
+}
+
+func digest(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func readAll(t *testing.T, rc io.ReadCloser) (string, error) {
+	t.Helper()
+	defer func() { _ = rc.Close() }()
+	data, err := io.ReadAll(rc)
+	return string(data), err
+}
+
+func TestNewManager(t *testing.T) {
+	manager, err := NewManager(memory.New(), "sessions")
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	if manager.Prefix() != "sessions/" {
+		t.Errorf("expected prefix sessions/, got %q", manager.Prefix())
+	}
+	if _, err := NewManager(memory.New(), "../sessions"); err == nil {
+		t.Error("expected an invalid prefix to be rejected")
+	}
+}
+
+func TestUploadLifecycle(t *testing.T) {
+	manager, _ := newTestManager(t)
+	ctx := context.WithValue(context.Background(), adapters.PrincipalContextKey{}, &adapters.Principal{ID: "alice"})
+
+	session, err := manager.Create(ctx, "videos/cat.mp4", Options{
+		Size:        11,
+		ContentType: "video/mp4",
+		Metadata:    map[string]string{"camera": "phone"},
+	})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if session.CreatedBy != "alice" || !session.ExpiresAt.Equal(session.CreatedAt.Add(DefaultTTL)) {
+		t.Errorf("unexpected session %+v", session)
+	}
+
+	// Chunks may arrive in any order and in parallel.
+	parts := []string{"hello", " ", "world"}
+	var wg sync.WaitGroup
+	for i := len(parts) - 1; i >= 0; i-- {
+		wg.Add(1)
+		go func(number int, data string) {
+			defer wg.Done()
+			if _, err := manager.PutChunk(ctx, session.ID, number, strings.NewReader(data), digest(data)); err != nil {
+				t.Errorf("PutChunk %d: %v", number, err)
+			}
+		}(i+1, parts[i])
+	}
+	wg.Wait()
+
+	got, err := manager.Get(ctx, session.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if len(got.Chunks) != 3 || got.Received() != 11 || got.Chunks[2] != (Chunk{Number: 3, Size: 5, SHA256: digest("world")}) {
+		t.Errorf("unexpected chunks %+v", got.Chunks)
+	}
+
+	if looked, err := manager.Lookup(ctx, session.ID); err != nil || looked.Key != session.Key || looked.Chunks != nil {
+		t.Errorf("Lookup = %+v, %v", looked, err)
+	}
+
+	assembled, rc, err := manager.Assemble(ctx, session.ID, digest("hello world"))
+	if err != nil {
+		t.Fatalf("Assemble: %v", err)
+	}
+	if assembled.Key != "videos/cat.mp4" || assembled.Metadata["camera"] != "phone" {
+		t.Errorf("unexpected session %+v", assembled)
+	}
+	if data, err := readAll(t, rc); err != nil || data != "hello world" {
+		t.Errorf("assembled %q, %v", data, err)
+	}
+
+	if err := manager.Remove(ctx, session.ID); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if _, err := manager.Get(ctx, session.ID); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("expected ErrSessionNotFound after Remove, got %v", err)
+	}
+	if err := manager.Remove(ctx, session.ID); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("expected ErrSessionNotFound for a second Remove, got %v", err)
+	}
+}
+
+func TestSessionSurvivesRestart(t *testing.T) {
+	manager, backend := newTestManager(t)
+	ctx := context.Background()
+
+	session, err := manager.Create(ctx, "data.bin", Options{})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := manager.PutChunk(ctx, session.ID, 1, strings.NewReader("abc"), digest("abc")); err != nil {
+		t.Fatalf("PutChunk: %v", err)
+	}
+
+	restarted, err := NewManager(backend, "")
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	if _, err := restarted.PutChunk(ctx, session.ID, 2, strings.NewReader("def"), digest("def")); err != nil {
+		t.Fatalf("PutChunk after restart: %v", err)
+	}
+	_, rc, err := restarted.Assemble(ctx, session.ID, "")
+	if err != nil {
+		t.Fatalf("Assemble: %v", err)
+	}
+	if data, err := readAll(t, rc); err != nil || data != "abcdef" {
+		t.Errorf("assembled %q, %v", data, err)
+	}
+}
+
+func TestPutChunkRejects(t *testing.T) {
+	manager, backend := newTestManager(t)
+	ctx := context.Background()
+	session, err := manager.Create(ctx, "data.bin", Options{})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		id     string
+		number int
+		data   string
+		sum    string
+		want   error
+	}{
+		{"number zero", session.ID, 0, "a", digest("a"), ErrInvalidChunk},
+		{"number too high", session.ID, MaxChunks + 1, "a", digest("a"), ErrInvalidChunk},
+		{"malformed digest", session.ID, 1, "a", "abc", ErrInvalidChunk},
+		{"digest mismatch", session.ID, 1, "a", digest("b"), ErrChecksumMismatch},
+		{"unknown session", strings.Repeat("0", 32), 1, "a", digest("a"), ErrSessionNotFound},
+		{"malformed session", "../x", 1, "a", digest("a"), ErrSessionNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := manager.PutChunk(ctx, tt.id, tt.number, strings.NewReader(tt.data), tt.sum)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("PutChunk() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	// A mismatching upload does not keep the chunk.
+	if exists, _ := backend.Exists(ctx, manager.chunkKey(session.ID, 1)); exists {
+		t.Error("mismatching chunk was kept")
+	}
+}
+
+func TestAssembleRejects(t *testing.T) {
+	manager, backend := newTestManager(t)
+	ctx := context.Background()
+
+	newSession := func(opts Options, chunks map[int]string) *Session {
+		t.Helper()
+		session, err := manager.Create(ctx, "data.bin", opts)
+		if err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		for number, data := range chunks {
+			if _, err := manager.PutChunk(ctx, session.ID, number, strings.NewReader(data), digest(data)); err != nil {
+				t.Fatalf("PutChunk: %v", err)
+			}
+		}
+		return session
+	}
+
+	gap := newSession(Options{}, map[int]string{1: "a", 3: "c"})
+	if _, _, err := manager.Assemble(ctx, gap.ID, ""); !errors.Is(err, ErrIncomplete) {
+		t.Errorf("expected ErrIncomplete for a missing chunk, got %v", err)
+	}
+
+	short := newSession(Options{Size: 10}, map[int]string{1: "abc"})
+	if _, _, err := manager.Assemble(ctx, short.ID, ""); !errors.Is(err, ErrIncomplete) {
+		t.Errorf("expected ErrIncomplete for a short upload, got %v", err)
+	}
+
+	whole := newSession(Options{}, map[int]string{1: "abc"})
+	_, rc, err := manager.Assemble(ctx, whole.ID, digest("xyz"))
+	if err != nil {
+		t.Fatalf("Assemble: %v", err)
+	}
+	if _, err := readAll(t, rc); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("expected ErrChecksumMismatch for the whole, got %v", err)
+	}
+
+	// A chunk corrupted at rest is caught while assembling.
+	corrupt := newSession(Options{}, map[int]string{1: "abc", 2: "def"})
+	if err := backend.PutWithMetadata(ctx, manager.chunkKey(corrupt.ID, 2), strings.NewReader("dxf"), &common.Metadata{
+		Custom: map[string]string{common.MetaChecksumSHA256: digest("def")},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	_, rc, err = manager.Assemble(ctx, corrupt.ID, "")
+	if err != nil {
+		t.Fatalf("Assemble: %v", err)
+	}
+	if _, err := readAll(t, rc); !errors.Is(err, ErrChecksumMismatch) || !strings.Contains(err.Error(), "chunk 2") {
+		t.Errorf("expected ErrChecksumMismatch for chunk 2, got %v", err)
+	}
+}
+
+func TestCreateValidation(t *testing.T) {
+	manager, _ := newTestManager(t)
+	ctx := context.Background()
+
+	for name, opts := range map[string]Options{
+		"negative TTL": {TTL: -time.Second},
+		"long TTL":     {TTL: MaxTTL + time.Second},
+		"size":         {Size: -1},
+		"metadata":     {Metadata: map[string]string{"bad\nkey": "x"}},
+	} {
+		if _, err := manager.Create(ctx, "data.bin", opts); !errors.Is(err, ErrInvalidOptions) {
+			t.Errorf("%s: expected ErrInvalidOptions, got %v", name, err)
+		}
+	}
+	if _, err := manager.Create(ctx, DefaultPrefix+"x", Options{}); !errors.Is(err, common.ErrReservedKey) {
+		t.Errorf("expected common.ErrReservedKey, got %v", err)
+	}
+	if _, err := manager.Create(ctx, "../escape", Options{}); err == nil {
+		t.Error("expected an invalid key to be rejected")
+	}
+}
+
+func TestExpiryAndPurge(t *testing.T) {
+	manager, _ := newTestManager(t)
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	manager.now = func() time.Time { return now }
+
+	expiring, err := manager.Create(ctx, "a.bin", Options{TTL: time.Hour})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := manager.PutChunk(ctx, expiring.ID, 1, strings.NewReader("a"), digest("a")); err != nil {
+		t.Fatalf("PutChunk: %v", err)
+	}
+	lasting, err := manager.Create(ctx, "b.bin", Options{TTL: 48 * time.Hour})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	now = now.Add(2 * time.Hour)
+	if _, err := manager.PutChunk(ctx, expiring.ID, 2, strings.NewReader("b"), digest("b")); !errors.Is(err, ErrSessionExpired) {
+		t.Errorf("expected ErrSessionExpired, got %v", err)
+	}
+	sessions, err := manager.Sessions(ctx)
+	if err != nil || len(sessions) != 2 {
+		t.Fatalf("Sessions = %d sessions, %v, want the expired one too", len(sessions), err)
+	}
+	for _, session := range sessions {
+		if session.ID == expiring.ID && session.Received() != 1 {
+			t.Errorf("expected the expired session's chunk to be listed, got %d bytes", session.Received())
+		}
+	}
+	purged, err := manager.Purge(ctx)
+	if err != nil || purged != 1 {
+		t.Fatalf("Purge = %d, %v, want 1", purged, err)
+	}
+	if _, err := manager.Get(ctx, expiring.ID); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("expected purged session to be gone, got %v", err)
+	}
+	if _, err := manager.Get(ctx, lasting.ID); err != nil {
+		t.Errorf("expected unexpired session to remain, got %v", err)
+	}
+}
+
+func TestStorageReservesSessionNamespace(t *testing.T) {
+	manager, backend := newTestManager(t)
+	storage := NewStorage(backend, manager)
+	ctx := context.Background()
+
+	if err := storage.PutWithContext(ctx, "data", strings.NewReader("x")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	session, err := manager.Create(ctx, "data", Options{})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	reserved := DefaultPrefix + session.ID + "/" + sessionObject
+	checks := map[string]error{
+		"put":      storage.PutWithContext(ctx, reserved, strings.NewReader("x")),
+		"metadata": storage.PutWithMetadata(ctx, reserved, strings.NewReader("x"), nil),
+		"delete":   storage.DeleteWithContext(ctx, reserved),
+		"append":   storage.Append(ctx, reserved, strings.NewReader("x")),
+		"compose":  storage.Compose(ctx, "copy", reserved),
+		"update":   storage.UpdateMetadata(ctx, reserved, &common.Metadata{}),
+	}
+	_, checks["get"] = storage.GetWithContext(ctx, reserved)
+	_, checks["range"] = storage.GetRange(ctx, reserved, 0, 1)
+	_, checks["stat"] = storage.GetMetadata(ctx, reserved)
+	_, checks["exists"] = storage.Exists(ctx, reserved)
+	for name, err := range checks {
+		if !errors.Is(err, common.ErrReservedKey) || !errors.Is(err, common.ErrPermissionDenied) {
+			t.Errorf("%s: expected common.ErrReservedKey, got %v", name, err)
+		}
+	}
+
+	keys, err := storage.ListWithContext(ctx, "")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(keys) != 1 || keys[0] != "data" {
+		t.Errorf("expected only data to be listed, got %v", keys)
+	}
+
+	result, err := storage.ListWithOptions(ctx, &common.ListOptions{Delimiter: "/"})
+	if err != nil {
+		t.Fatalf("ListWithOptions: %v", err)
+	}
+	if len(result.Objects) != 1 || len(result.CommonPrefixes) != 0 {
+		t.Errorf("expected session prefix to be hidden, got %+v %v", result.Objects, result.CommonPrefixes)
+	}
+}
@@ -59,8 +59,9 @@ func ValidateKey(key string) error {
 		return fmt.Errorf("%w: key too long (max 1024 characters)", common.ErrInvalidArgument)
 	}
 
-	// Check for absolute paths
-	if filepath.IsAbs(key) {
+	// Check for absolute paths; filepath.IsAbs does not consider "/etc"
+	// absolute on Windows
+	if strings.HasPrefix(key, "/") || filepath.IsAbs(key) {
 		return fmt.Errorf("%w: key cannot be an absolute path", common.ErrInvalidArgument)
 	}
 